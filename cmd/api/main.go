@@ -8,57 +8,89 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 	_ "time/tzdata"
 
 	"portal_final_backend/internal/adapters"
 	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/analytics"
+	analyticsservice "portal_final_backend/internal/analytics/service"
 	"portal_final_backend/internal/appointments"
+	"portal_final_backend/internal/auditexport"
+	auditexportservice "portal_final_backend/internal/auditexport/service"
 	"portal_final_backend/internal/auth"
+	"portal_final_backend/internal/billing"
+	billingservice "portal_final_backend/internal/billing/service"
 	"portal_final_backend/internal/catalog"
+	"portal_final_backend/internal/customfields"
 	"portal_final_backend/internal/email"
 	"portal_final_backend/internal/energylabel"
+	"portal_final_backend/internal/eventoutbox"
 	"portal_final_backend/internal/events"
 	"portal_final_backend/internal/exports"
+	"portal_final_backend/internal/files"
+	"portal_final_backend/internal/graphapi"
+	"portal_final_backend/internal/grpcapi"
 	apphttp "portal_final_backend/internal/http"
 	"portal_final_backend/internal/http/agents"
 	"portal_final_backend/internal/http/router"
 	"portal_final_backend/internal/identity"
 	"portal_final_backend/internal/imap"
 	"portal_final_backend/internal/isde"
+	"portal_final_backend/internal/jobs"
+	"portal_final_backend/internal/kvk"
 	"portal_final_backend/internal/leadenrichment"
 	"portal_final_backend/internal/leads"
 	leadagent "portal_final_backend/internal/leads/agent"
 	"portal_final_backend/platform/adk/confirmation"
 	leadsmgmt "portal_final_backend/internal/leads/management"
 	leadsports "portal_final_backend/internal/leads/ports"
+	leadsrepo "portal_final_backend/internal/leads/repository"
 	"portal_final_backend/internal/maps"
 	"portal_final_backend/internal/notification"
 	"portal_final_backend/internal/orchestration"
 	"portal_final_backend/internal/notification/outbox"
 	"portal_final_backend/internal/partners"
+	"portal_final_backend/internal/payouts"
 	partnersrepo "portal_final_backend/internal/partners/repository"
 	partnersvc "portal_final_backend/internal/partners/service"
 	"portal_final_backend/internal/pdf"
 	"portal_final_backend/internal/productflows"
 	"portal_final_backend/internal/quotes"
+	"portal_final_backend/internal/residency"
+	residencyservice "portal_final_backend/internal/residency/service"
+	"portal_final_backend/internal/retention"
+	retentionservice "portal_final_backend/internal/retention/service"
 	"portal_final_backend/internal/scheduler"
 	"portal_final_backend/internal/search"
 	"portal_final_backend/internal/services"
+	"portal_final_backend/internal/storagequota"
+	storagequotaservice "portal_final_backend/internal/storagequota/service"
 	"portal_final_backend/internal/tasks"
+	"portal_final_backend/internal/uploads"
+	uploadsservice "portal_final_backend/internal/uploads/service"
+	uploadstransport "portal_final_backend/internal/uploads/transport"
+	"portal_final_backend/internal/telephony"
+	"portal_final_backend/internal/triage"
 	"portal_final_backend/internal/webhook"
 	"portal_final_backend/internal/whatsapp"
 	"portal_final_backend/internal/whatsappagent"
 	whatsappagentdb "portal_final_backend/internal/whatsappagent/db"
+	"portal_final_backend/internal/workorders"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/transcription"
+	"portal_final_backend/platform/circuitbreaker"
 	"portal_final_backend/platform/config"
 	"portal_final_backend/platform/db"
 	"portal_final_backend/platform/httpkit"
 	"portal_final_backend/platform/logger"
 	"portal_final_backend/platform/rediskit"
+	"portal_final_backend/platform/secrets"
 	"portal_final_backend/platform/validator"
 
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -114,6 +146,8 @@ func main() {
 	orchestration.MustInitSkillLoader()
 
 	eventBus := events.NewInMemoryBus(log)
+	eventOutbox := eventoutbox.NewRepository(pool)
+	eventBus.SetHandlerGuard(eventoutbox.NewHandlerGuard(eventOutbox))
 	sessionRedis, closeSessionRedis := initSessionRedis(cfg, log)
 	defer closeSessionRedis()
 
@@ -123,6 +157,11 @@ func main() {
 	sender := initEmailSenderOrPanic(cfg, log)
 	val := validator.New()
 	storageSvc := initStorageOrPanic(ctx, cfg, log)
+	circuitbreaker.Configure(circuitbreaker.Settings{
+		FailureThreshold: cfg.GetCircuitBreakerFailureThreshold(),
+		OpenTimeout:      cfg.GetCircuitBreakerOpenTimeout(),
+		HalfOpenProbes:   cfg.GetCircuitBreakerHalfOpenProbes(),
+	})
 	initGotenbergIfEnabled(cfg, log)
 
 	app := buildHTTPApp(appBuildDeps{
@@ -137,9 +176,34 @@ func main() {
 		reminderScheduler:   reminderScheduler,
 		sessionRedis:        sessionRedis,
 	})
+	startEventOutboxDispatcher(ctx, eventOutbox, eventBus, log)
 	serveUntilShutdown(ctx, cfg, log, eventBus, app)
 }
 
+// startEventOutboxDispatcher publishes events stored in the outbox. It starts
+// after all modules subscribed, so no stored event is delivered to an
+// incomplete set of handlers. Only events whose producer writes them in the
+// transaction of their state change go through the outbox: the public quote
+// decisions. Other events are still published on the bus directly.
+func startEventOutboxDispatcher(ctx context.Context, repo *eventoutbox.Repository, eventBus *events.InMemoryBus, log *logger.Logger) {
+	registry := eventoutbox.NewRegistry()
+	registry.Register(
+		events.LeadCreated{},
+		events.QuoteAccepted{},
+		events.QuoteRejected{},
+		events.AppointmentCreated{},
+		events.AppointmentUpdated{},
+		events.AppointmentStatusChanged{},
+		events.AppointmentDeleted{},
+		events.PartnerOfferCreated{},
+		events.PartnerOfferAccepted{},
+		events.PartnerOfferRejected{},
+		events.PartnerOfferDeleted{},
+		events.PartnerOfferExpired{},
+	)
+	go eventoutbox.NewDispatcher(repo, eventBus, registry, log).Run(ctx)
+}
+
 func noOpCloser() {
 	// Intentionally empty: used as a safe default closer when no resource was initialized.
 }
@@ -226,6 +290,8 @@ func initStorageOrPanic(ctx context.Context, cfg *config.Config, log *logger.Log
 	ensureBucket(ctx, log, storageSvc, "organization-logos", cfg.GetMinioBucketOrganizationLogos())
 	ensureBucket(ctx, log, storageSvc, "quote-pdfs", cfg.GetMinioBucketQuotePDFs())
 	ensureBucket(ctx, log, storageSvc, "quote-attachments", cfg.GetMinioBucketQuoteAttachments())
+	ensureBucket(ctx, log, storageSvc, "files", cfg.GetMinioBucketFiles())
+	ensureBucket(ctx, log, storageSvc, "audit-exports", cfg.GetMinioBucketAuditExports())
 	log.Info(
 		"storage service initialized",
 		"leadAttachmentsBucket", cfg.GetMinioBucketLeadServiceAttachments(),
@@ -234,6 +300,8 @@ func initStorageOrPanic(ctx context.Context, cfg *config.Config, log *logger.Log
 		"organizationLogosBucket", cfg.GetMinioBucketOrganizationLogos(),
 		"quotePDFsBucket", cfg.GetMinioBucketQuotePDFs(),
 		"quoteAttachmentsBucket", cfg.GetMinioBucketQuoteAttachments(),
+		"filesBucket", cfg.GetMinioBucketFiles(),
+		"auditExportsBucket", cfg.GetMinioBucketAuditExports(),
 	)
 
 	return storageSvc
@@ -302,8 +370,20 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 	pool := deps.pool
 	eventBus := deps.eventBus
 	sender := deps.sender
-	storageSvc := deps.storageSvc
 	val := deps.val
+	storageQuotaModule := storagequota.NewModule(pool, deps.storageSvc, storagequotaservice.Config{
+		SoftLimitBytes: cfg.GetStorageQuotaSoftLimitBytes(),
+		HardLimitBytes: cfg.GetStorageQuotaHardLimitBytes(),
+		Buckets:        cfg.GetMinioBuckets(),
+	}, val, log)
+	storageSvc := storageQuotaModule.Storage()
+	billingModule := billing.NewModule(pool, billingservice.Config{
+		DefaultPlan: cfg.GetBillingDefaultPlan(),
+		GracePeriod: cfg.GetBillingGracePeriod(),
+		AppBaseURL:  cfg.GetAppBaseURL(),
+	}, val, log)
+	billingModule.ConfigureProviders(cfg, cfg.GetPublicAPIBaseURL())
+	billingModule.RegisterHandlers(eventBus)
 	reminderScheduler := deps.reminderScheduler
 	sessionRedis := deps.sessionRedis
 
@@ -318,12 +398,30 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 	identityModule := identity.NewModule(pool, eventBus, storageSvc, cfg.GetMinioBucketOrganizationLogos(), val, whatsappClient)
 	identityModule.RegisterHandlers(eventBus)
 	notificationModule.SetOrganizationSettingsReader(identityModule.Service())
+	notificationModule.SetMessagingPolicyReader(identityModule.Service())
+	notificationModule.SetBrandingReader(identityModule.Service())
+	notificationModule.SetOrganizationLanguageReader(identityModule.Service())
 	notificationModule.SetUserTenancyReader(identityModule.Service())
 	notificationModule.SetWorkflowResolver(identityModule.Service())
+	notificationModule.SetPortalDomainResolver(identityModule.Service())
+	identityModule.Service().SetWorkflowSimulator(notificationModule)
+	identityModule.Service().SetWorkflowVariableCatalog(notificationModule)
+	identityModule.Service().SetEmailTemplateRenderer(notificationModule)
+	notificationModule.SetEmailTemplateReader(identityModule.Service())
 	notificationModule.SetWhatsAppInboxWriter(identityModule.Service())
 
-	wireSMTPEncryptionKey(cfg, log, identityModule.Service(), notificationModule)
+	aiUsageLedger := adapters.NewAIUsageLedgerAdapter(identityModule.Service())
+	llmRouter := llmrouter.New(cfg.ResolveProviderConfig, aiUsageLedger.Settings, aiUsageLedger, log)
+	llmRouter.SetRunMeter(billingModule.Service())
+	llmrouter.SetDefault(llmRouter)
+
+	secretsSvc := initSecretsOrPanic(cfg, log)
+	smtpKeyring := wireSMTPEncryptionKey(ctx, secretsSvc, log, identityModule.Service(), notificationModule)
+	wireOutboxEncryptionKey(ctx, secretsSvc, log)
+	leadContact := wireLeadContactEncryption(ctx, secretsSvc, log)
+	identityModule.SetLeadContactCipher(leadContact)
 	imapModule := imap.NewModule(pool, val, eventBus, log)
+	imapModule.SetLeadContactCipher(leadContact)
 	if reminderScheduler != nil {
 		imapModule.Service().SetScheduler(reminderScheduler)
 		go runIMAPPeriodicSweep(ctx, reminderScheduler, log)
@@ -331,6 +429,7 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 
 	authModule := auth.NewModule(pool, identityModule.Service(), cfg, eventBus, log, val)
 	authModule.Service().SetAccessTokenBlocklistRedis(sessionRedis)
+	wireSSOConfig(ctx, cfg, secretsSvc, log, identityModule.Service(), authModule.Service())
 
 	leadsModule, err := leads.NewModule(ctx, pool, eventBus, storageSvc, val, leads.ModuleDeps{
 		Config:                cfg,
@@ -342,7 +441,9 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 		log.Error("failed to initialize leads module", "error", err)
 		panic("failed to initialize leads module: " + err.Error())
 	}
+	leadsModule.SetContactCipher(leadContact)
 	leadsModule.ManagementService().SetWorkflowOverrideWriter(identityModule.Service())
+	leadsModule.LeadGeocoder().StartRetryLoop(ctx)
 	leadsModule.ManagementService().SetLeadDetailWorkflowContextReader(adapters.NewLeadDetailWorkflowContextReader(identityModule.Service()))
 	leadsModule.ManagementService().SetInAppNotificationService(notificationModule.InAppService())
 	identityModule.Service().SetSSE(leadsModule.SSE())
@@ -391,6 +492,9 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 		}, nil
 	})
 	notificationModule.SetLeadWhatsAppReader(leadsModule.Repository())
+	notificationModule.SetLeadConsentReader(leadsModule.Repository())
+	notificationModule.SetLeadMarketingConfirmationStore(leadsModule.Repository())
+	notificationModule.SetLeadLanguageReader(leadsModule.Repository())
 	notificationModule.SetOrganizationMemberReader(leadsModule.Repository())
 	notificationModule.SetLeadAssigneeReader(adapters.NewLeadAssigneeReader(leadsModule.Repository()))
 
@@ -406,6 +510,8 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 		Storage:           storageSvc,
 		AttachmentBucket:  cfg.GetMinioBucketLeadServiceAttachments(),
 		TimelineRecorder:  leadsModule.Repository(),
+
+		CalendarFeedBaseURL: cfg.GetPublicAPIBaseURL(),
 	})
 	appointmentsModule.SetSSE(leadsModule.SSE())
 	appointmentBooker := adapters.NewAppointmentsAdapter(appointmentsModule.Service)
@@ -423,7 +529,7 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 		leadsModule.SetEnergyLabelEnricher(energyLabelEnricher)
 	}
 
-	leadEnrichmentModule := leadenrichment.NewModule(log)
+	leadEnrichmentModule := leadenrichment.NewModule(pool, log)
 	leadsModule.SetLeadEnricher(adapters.NewLeadEnrichmentAdapter(leadEnrichmentModule.Service()))
 
 	mapsModule := maps.NewModule(log)
@@ -433,10 +539,13 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 	productflowsModule := productflows.NewModule(pool, val, log)
 	catalogModule := catalog.NewModule(pool, storageSvc, cfg.GetMinioBucketCatalogAssets(), val, cfg, log)
 	catalogModule.RegisterHandlers(eventBus)
+	catalogModule.SetEventBus(eventBus)
+	wireCatalogPriceFeedKeyring(ctx, secretsSvc, log, catalogModule)
 	partnersModule := partners.NewModule(pool, eventBus, storageSvc, cfg.GetMinioBucketPartnerLogos(), val)
 	partnersModule.Service().SetAttachmentsBucket(cfg.GetMinioBucketLeadServiceAttachments())
 	partnersModule.Service().SetPDFBucket(cfg.GetMinioBucketQuotePDFs())
 	partnersOfferPDFProcessor := adapters.NewPartnerOfferPDFProcessor(partnersrepo.New(pool), identityModule.Service(), storageSvc, cfg, sender)
+	partnersOfferPDFProcessor.SetSMTPKeyring(smtpKeyring)
 	partnersModule.SetOfferPDFRegenerator(partnersOfferPDFProcessor)
 	partnersModule.Service().SetOrganizationSettingsReader(func(ctx context.Context, organizationID uuid.UUID) (partnersvc.OrganizationOfferSettings, error) {
 		settings, err := identityModule.Service().GetOrganizationSettings(ctx, organizationID)
@@ -452,14 +561,57 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 		}
 		return partner.ContactPhone, nil
 	}))
+	kvkModule := kvk.NewModule(pool, eventBus, val, cfg, log)
+	filesModule := files.NewModule(pool, storageSvc, cfg.GetMinioBucketFiles(), val, log)
+	uploadsModule := uploads.NewModule(pool, storageSvc, uploadsservice.Config{
+		PartSize:   cfg.GetUploadPartSizeBytes(),
+		SessionTTL: cfg.GetUploadSessionTTL(),
+		Buckets: map[string]string{
+			uploadstransport.TargetLeadService: cfg.GetMinioBucketLeadServiceAttachments(),
+			uploadstransport.TargetQuote:       cfg.GetMinioBucketQuoteAttachments(),
+		},
+	}, val, log)
 	quotesModule := quotes.NewModule(pool, eventBus, val)
+	quotesModule.RegisterHandlers(eventBus)
 	quotesModule.Service().SetLeadTransferCreator(leadsModule.ManagementService())
 	quotesModule.Service().SetLeadTransferRepository(leadsModule.Repository())
 	leadsModule.ManagementService().SetAcceptedQuoteUpdater(quotesModule.Service())
 	leadsModule.ManagementService().SetLeadDetailQuotesReader(adapters.NewLeadDetailQuoteReader(quotesModule.Service()))
 	leadsModule.ManagementService().SetLeadDetailAppointmentsReader(adapters.NewLeadDetailAppointmentReader(appointmentsModule.Service))
 	tasksModule := tasks.NewModule(pool, val, reminderScheduler, leadsModule.Repository(), log)
+	tasksModule.Service().SetSSE(leadsModule.SSE())
+	tasksModule.RegisterHandlers(eventBus)
+	workOrdersModule := workorders.NewModule(pool, eventBus, leadsModule.Repository(), val, log)
+	workOrdersModule.RegisterHandlers(eventBus)
+	payoutsModule := payouts.NewModule(pool, val, log)
+	payoutsModule.RegisterHandlers(eventBus)
+	triageModule := triage.NewModule(pool, val, log)
+	triageModule.RegisterHandlers(eventBus)
+	notificationModule.SetLeadTriageRecorder(triageModule.Service())
+	telephonyModule := telephony.NewModule(pool, val, leadsModule.Repository(), leadsModule.Repository(), log)
+	telephonyModule.Service().SetSSE(leadsModule.SSE())
 	searchModule := search.NewModule(pool, val)
+	graphapiModule := graphapi.NewModule(pool, log)
+	analyticsModule := analytics.NewModule(pool, analyticsservice.Config{}, val, log)
+	retentionModule := retention.NewModule(pool, retentionservice.Config{}, val, log)
+	customFieldsModule := customfields.NewModule(pool, val, log)
+	auditExportModule := auditexport.NewModule(pool, deps.storageSvc, auditexportservice.Config{Bucket: cfg.GetMinioBucketAuditExports()}, val, log)
+	residencyModule := residency.NewModule(pool, residencyservice.Config{}, log)
+	if err := residencyModule.Service().SyncSchemas(ctx); err != nil {
+		log.Error("failed to sync organization data schemas", "error", err)
+	}
+	httpkit.SetTenantContextBinder(residencyModule.Service().BindTenant)
+	db.SetSchemaLister(residencyModule.Service().ListSchemas)
+	go residencyModule.Service().WatchPlacements(ctx)
+	leadsModule.SetPublicTokenLocator(residencyModule.Service().TokenLocator("rac_leads", "public_token"))
+	leadsModule.SetPortalLinkLocator(residencyModule.Service().TokenLocator("rac_lead_portal_links", "id"))
+	quotesModule.SetPublicTokenLocator(residencyModule.Service().TokenLocator("rac_quotes", "public_token", "preview_token"))
+	quotesModule.SetPaymentLocator(residencyModule.Service().TokenLocator("rac_quote_payments", "external_id"))
+	partnersModule.SetPublicTokenLocator(residencyModule.Service().TokenLocator("rac_partner_offers", "public_token"))
+	workOrdersModule.SetPublicTokenLocator(residencyModule.Service().TokenLocator("rac_work_orders", "confirmation_token"))
+	httpkit.SetBranchAccessResolver(identityModule.Service().IsBranchOf)
+	httpkit.SetPortalHostResolver(identityModule.Service().ResolvePortalHost)
+	analyticsModule.Service().SetBranchLister(identityModule.Service())
 	quotesModule.SetGenerateQuoteJobQueue(reminderScheduler)
 	if cfg.IsEmbeddingEnabled() && cfg.IsQdrantEnabled() {
 		quotesModule.SetHumanFeedbackMemoryQueue(reminderScheduler)
@@ -467,7 +619,15 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 	leadsModule.GetSubsidyAnalyzerService().SetSchedulerClient(*reminderScheduler)
 	leadsModule.GetSubsidyAnalyzerService().SetQuoteRepo(*quotesModule.Repository())
 	quotesModule.SetSubsidyAnalyzerService(leadsModule.GetSubsidyAnalyzerService())
-	wireMoneybirdConfig(cfg, log, quotesModule.Service())
+	wireMoneybirdConfig(ctx, cfg, secretsSvc, log, quotesModule.Service())
+	wireMollieConfig(ctx, cfg, secretsSvc, log, quotesModule.Service())
+	wireTelephonyConfig(ctx, cfg, secretsSvc, log, telephonyModule.Service())
+	startGRPCServerIfEnabled(ctx, cfg, log, grpcapi.Dependencies{
+		Leads:      leadsModule.ManagementService(),
+		Quotes:     quotesModule.Service(),
+		Partners:   partnersModule.Service(),
+		BindTenant: residencyModule.Service().BindTenant,
+	})
 
 	quoteViewer := adapters.NewQuotePublicAdapter(quotesModule.Service(), leadsModule.Repository(), quotesModule.Repository())
 	appointmentViewer := adapters.NewAppointmentPublicAdapter(appointmentsModule.Service)
@@ -497,11 +657,18 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 	quotesContacts := adapters.NewQuotesContactReader(leadsModule.Repository(), identityModule.Service(), authModule.Repository())
 	quotesModule.Service().SetQuoteContactReader(quotesContacts)
 	quotesModule.SetLogoPresigner(adapters.NewQuotesLogoPresigner(storageSvc, cfg.GetMinioBucketOrganizationLogos()))
+	quotesModule.SetBrandingReader(adapters.NewQuotesBrandingReader(identityModule.Service()))
 
 	quoteTermsResolver := adapters.NewQuoteTermsResolverAdapter(identityModule.Service(), identityModule.Service(), leadsModule.Repository())
 	quotesModule.Service().SetQuoteTermsResolver(quoteTermsResolver)
 	quotePDFProcessor := adapters.NewQuoteAcceptanceProcessor(quotesModule.Repository(), identityModule.Service(), quotesContacts, storageSvc, cfg, quoteTermsResolver)
+	quotePDFProcessor.SetTermsDocumentReader(quotesModule.Service())
 	quotesModule.SetPDFGenerator(quotePDFProcessor)
+	quotesModule.SetAttachmentPreviewer(adapters.NewQuoteAttachmentPreviewer(quotesModule.Repository(), storageSvc, cfg))
+	quotesModule.SetCreditNotePDFGenerator(adapters.NewCreditNotePDFProcessor(quotesModule.Repository(), identityModule.Service(), quotesContacts, storageSvc, cfg))
+	payoutsModule.SetStorageForPDF(storageSvc, cfg.GetMinioBucketQuotePDFs())
+	payoutsModule.SetPDFGenerator(adapters.NewPayoutStatementPDFProcessor(payoutsModule.Repository(), identityModule.Service(), storageSvc, cfg))
+	payoutsModule.Service().SetAccountingExporter(adapters.NewPayoutAccountingExporter(quotesModule.Service()))
 	notificationModule.SetQuotePDFGenerator(quotePDFProcessor)
 
 	notificationModule.SetQuoteActivityWriter(adapters.NewQuoteActivityWriter(quotesModule.Repository()))
@@ -516,9 +683,12 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 	defer closeTranscriber()
 
 	webhookModule := webhook.NewModule(pool, leadsModule.ManagementService(), storageSvc, cfg.GetMinioBucketLeadServiceAttachments(), eventBus, val, log)
+	webhookModule.SetLeadContactCipher(leadContact)
 	webhookModule.SetWhatsAppClient(whatsappClient)
 	webhookModule.SetWhatsAppWebhookSecret(cfg.GetWhatsAppWebhookSecret())
 	webhookModule.SetWhatsAppInboxIngester(identityModule.Service())
+	webhookModule.SetCustomFieldWriter(customFieldsModule.Service())
+	wireLeadConnectorKeyring(ctx, secretsSvc, log, webhookModule)
 
 	waProvCfg, waModelOvr := cfg.ResolveAgentModel(config.LLMModelAgentWhatsAppAgent)
 	whatsappagentModule, err := whatsappagent.NewModule(pool, whatsappagent.ModuleConfig{
@@ -563,10 +733,9 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 	}
 
 	exportsModule := exports.NewModule(pool, val)
-	wireExportsEncryptionKey(cfg, log, exportsModule)
+	wireExportsEncryptionKey(ctx, secretsSvc, log, exportsModule)
 
 	wireIMAPEncryptionKey(cfg, log, imapModule.Service())
-	wireSMTPEncryptionKeyForIMAP(cfg, log, imapModule.Service())
 
 	agentsModule := agents.NewModule(pool)
 
@@ -583,9 +752,24 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 		catalogModule,
 		appointmentsModule,
 		partnersModule,
+		kvkModule,
+		filesModule,
+		uploadsModule,
+		storageQuotaModule,
+		billingModule,
 		quotesModule,
 		tasksModule,
+		workOrdersModule,
+		payoutsModule,
+		triageModule,
+		telephonyModule,
 		searchModule,
+		graphapiModule,
+		analyticsModule,
+		retentionModule,
+		customFieldsModule,
+		auditExportModule,
+		residencyModule,
 		webhookModule,
 		exportsModule,
 		agentsModule,
@@ -594,13 +778,19 @@ func buildHTTPApp(deps appBuildDeps) *apphttp.App {
 	if whatsappagentModule != nil {
 		modules = append(modules, whatsappagentModule)
 	}
+	if jobInspector, err := scheduler.NewInspector(cfg); err != nil {
+		log.Warn("job dashboard disabled", "error", err)
+	} else {
+		modules = append(modules, jobs.NewModule(jobInspector, pool, deps.reminderScheduler, val))
+	}
 
 	return &apphttp.App{
-		Config:   cfg,
-		Logger:   log,
-		Health:   db.NewPoolAdapter(pool),
-		EventBus: eventBus,
-		Modules:  modules,
+		Config:                  cfg,
+		Logger:                  log,
+		Health:                  db.NewPoolAdapter(pool),
+		EventBus:                eventBus,
+		Modules:                 modules,
+		AuthenticatedMiddleware: []gin.HandlerFunc{billingModule.Middleware()},
 	}
 }
 
@@ -633,81 +823,186 @@ func serveUntilShutdown(ctx context.Context, cfg *config.Config, log *logger.Log
 	}
 }
 
-// wireSMTPEncryptionKey parses and injects the SMTP encryption key into identity and notification modules.
-func wireSMTPEncryptionKey(cfg *config.Config, log *logger.Logger, identitySvc interface{ SetSMTPEncryptionKey([]byte) }, notificationMod interface{ SetSMTPEncryptionKey([]byte) }) {
-	smtpKeyHex := cfg.GetSMTPEncryptionKey()
-	if smtpKeyHex == "" {
-		return
+func initSecretsOrPanic(cfg *config.Config, log *logger.Logger) *secrets.Service {
+	secretsSvc, err := secrets.NewFromConfig(cfg)
+	if err != nil {
+		log.Error("failed to initialize secrets backend", "error", err)
+		panic("failed to initialize secrets backend: " + err.Error())
 	}
+	log.Info("secrets backend initialized", "backend", secretsSvc.BackendName())
+	return secretsSvc
+}
 
-	smtpKey, err := hex.DecodeString(smtpKeyHex)
+// loadKeyringOrPanic resolves an encryption keyring by name. It returns nil when the secret is not configured.
+func loadKeyringOrPanic(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, name string) *secrets.Keyring {
+	keyring, err := secretsSvc.Keyring(ctx, name)
 	if err != nil {
-		log.Error("invalid SMTP_ENCRYPTION_KEY (must be hex-encoded)", "error", err)
-		panic("invalid SMTP_ENCRYPTION_KEY: " + err.Error())
+		log.Error("invalid encryption keyring", "name", name, "error", err)
+		panic("invalid " + name + ": " + err.Error())
+	}
+	return keyring
+}
+
+// wireSMTPEncryptionKey resolves the SMTP keyring and injects it into identity and notification modules.
+func wireSMTPEncryptionKey(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, identitySvc interface{ SetSMTPKeyring(*secrets.Keyring) }, notificationMod interface{ SetSMTPKeyring(*secrets.Keyring) }) *secrets.Keyring {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "SMTP_ENCRYPTION_KEY")
+	if keyring == nil {
+		return nil
+	}
+
+	identitySvc.SetSMTPKeyring(keyring)
+	notificationMod.SetSMTPKeyring(keyring)
+	log.Info("smtp encryption key configured", "primaryKeyId", keyring.PrimaryID())
+	return keyring
+}
+
+// wireOutboxEncryptionKey enables encryption of notification outbox payloads when OUTBOX_ENCRYPTION_KEY is configured.
+func wireOutboxEncryptionKey(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger) {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "OUTBOX_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
+	}
+
+	outbox.SetKeyring(keyring)
+	log.Info("outbox encryption key configured", "primaryKeyId", keyring.PrimaryID())
+}
+
+// wireLeadContactEncryption returns the cipher for lead phone numbers and email addresses when both
+// LEADS_CONTACT_ENCRYPTION_KEY and LEADS_CONTACT_INDEX_KEY are configured, and nil otherwise.
+func wireLeadContactEncryption(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger) *leadsrepo.ContactCipher {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "LEADS_CONTACT_ENCRYPTION_KEY")
+	indexKeyring := loadKeyringOrPanic(ctx, secretsSvc, log, "LEADS_CONTACT_INDEX_KEY")
+	if keyring == nil || indexKeyring == nil {
+		if keyring != nil || indexKeyring != nil {
+			log.Warn("lead contact encryption disabled: LEADS_CONTACT_ENCRYPTION_KEY and LEADS_CONTACT_INDEX_KEY must both be set")
+		}
+		return nil
+	}
+
+	log.Info("lead contact encryption configured", "primaryKeyId", keyring.PrimaryID())
+	return leadsrepo.NewContactCipher(keyring, indexKeyring.PrimaryKey())
+}
+
+// wireCatalogPriceFeedKeyring enables storing supplier API tokens on catalog price
+// feeds when CATALOG_PRICE_FEED_ENCRYPTION_KEY is configured.
+func wireCatalogPriceFeedKeyring(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, catalogMod interface{ SetPriceFeedKeyring(*secrets.Keyring) }) {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "CATALOG_PRICE_FEED_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
+	}
+
+	catalogMod.SetPriceFeedKeyring(keyring)
+	log.Info("catalog price feed encryption key configured", "primaryKeyId", keyring.PrimaryID())
+}
+
+// wireLeadConnectorKeyring enables storing marketplace API tokens on polling lead
+// source connectors when LEAD_CONNECTOR_ENCRYPTION_KEY is configured.
+func wireLeadConnectorKeyring(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, webhookMod interface{ SetConnectorKeyring(*secrets.Keyring) }) {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "LEAD_CONNECTOR_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
 	}
-	if len(smtpKey) != 32 {
-		log.Error("SMTP_ENCRYPTION_KEY must be 32 bytes (64 hex chars)", "length", len(smtpKey))
-		panic("SMTP_ENCRYPTION_KEY must be 32 bytes")
+
+	webhookMod.SetConnectorKeyring(keyring)
+	log.Info("lead connector encryption key configured", "primaryKeyId", keyring.PrimaryID())
+}
+
+// wireSSOConfig enables organization single sign-on when SSO_ENCRYPTION_KEY is configured.
+func wireSSOConfig(ctx context.Context, cfg *config.Config, secretsSvc *secrets.Service, log *logger.Logger, identitySvc interface{ SetSSOKeyring(*secrets.Keyring) }, authSvc interface{ SetSSORedirectURL(string) }) {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "SSO_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
 	}
 
-	identitySvc.SetSMTPEncryptionKey(smtpKey)
-	notificationMod.SetSMTPEncryptionKey(smtpKey)
-	log.Info("smtp encryption key configured")
+	identitySvc.SetSSOKeyring(keyring)
+	authSvc.SetSSORedirectURL(strings.TrimRight(cfg.GetAppBaseURL(), "/") + "/auth/sso/callback")
+	log.Info("sso encryption key configured", "primaryKeyId", keyring.PrimaryID())
 }
 
-func wireMoneybirdConfig(cfg *config.Config, log *logger.Logger, quotesSvc interface {
+func wireMoneybirdConfig(ctx context.Context, cfg *config.Config, secretsSvc *secrets.Service, log *logger.Logger, quotesSvc interface {
 	SetMoneybirdConfig(string, string, string, string)
-	SetMoneybirdEncryptionKey([]byte)
+	SetMoneybirdKeyring(*secrets.Keyring)
 }) {
 	clientID := cfg.GetMoneybirdClientID()
 	clientSecret := cfg.GetMoneybirdClientSecret()
 	redirectURI := cfg.GetMoneybirdRedirectURI()
 	frontendURL := cfg.GetMoneybirdFrontendURL()
-	encryptionKeyHex := cfg.GetMoneybirdEncryptionKey()
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "MONEYBIRD_ENCRYPTION_KEY")
 
-	if clientID == "" && clientSecret == "" && redirectURI == "" && encryptionKeyHex == "" {
+	if clientID == "" && clientSecret == "" && redirectURI == "" && keyring == nil {
 		return
 	}
 
-	if clientID == "" || clientSecret == "" || redirectURI == "" || encryptionKeyHex == "" {
+	if clientID == "" || clientSecret == "" || redirectURI == "" || keyring == nil {
 		log.Warn("moneybird config is partially configured; oauth flow will be disabled")
 		return
 	}
 
 	quotesSvc.SetMoneybirdConfig(clientID, clientSecret, redirectURI, frontendURL)
+	quotesSvc.SetMoneybirdKeyring(keyring)
+	log.Info("moneybird oauth configuration enabled", "primaryKeyId", keyring.PrimaryID())
+}
 
-	encryptionKey, err := hex.DecodeString(encryptionKeyHex)
-	if err != nil {
-		log.Error("invalid MONEYBIRD_ENCRYPTION_KEY (must be hex-encoded)", "error", err)
-		panic("invalid MONEYBIRD_ENCRYPTION_KEY: " + err.Error())
-	}
-	if len(encryptionKey) != 32 {
-		log.Error("MONEYBIRD_ENCRYPTION_KEY must be 32 bytes (64 hex chars)", "length", len(encryptionKey))
-		panic("MONEYBIRD_ENCRYPTION_KEY must be 32 bytes")
+func wireMollieConfig(ctx context.Context, cfg *config.Config, secretsSvc *secrets.Service, log *logger.Logger, quotesSvc interface {
+	SetMollieConfig(string, string)
+	SetMollieKeyring(*secrets.Keyring)
+}) {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "MOLLIE_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
 	}
 
-	quotesSvc.SetMoneybirdEncryptionKey(encryptionKey)
-	log.Info("moneybird oauth configuration enabled")
+	quotesSvc.SetMollieConfig(cfg.GetPublicBaseURL(), cfg.GetPublicAPIBaseURL())
+	quotesSvc.SetMollieKeyring(keyring)
+	log.Info("mollie payments enabled", "primaryKeyId", keyring.PrimaryID())
 }
 
-func wireExportsEncryptionKey(cfg *config.Config, log *logger.Logger, exportsMod interface{ SetEncryptionKey([]byte) }) {
-	keyHex := cfg.GetExportsEncryptionKey()
-	if keyHex == "" {
+func wireTelephonyConfig(ctx context.Context, cfg *config.Config, secretsSvc *secrets.Service, log *logger.Logger, telephonySvc interface {
+	SetPublicAPIBaseURL(string)
+	SetKeyring(*secrets.Keyring)
+}) {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "TELEPHONY_ENCRYPTION_KEY")
+	if keyring == nil {
 		return
 	}
 
-	key, err := hex.DecodeString(keyHex)
+	telephonySvc.SetPublicAPIBaseURL(cfg.GetPublicAPIBaseURL())
+	telephonySvc.SetKeyring(keyring)
+	log.Info("telephony integration enabled", "primaryKeyId", keyring.PrimaryID())
+}
+
+// startGRPCServerIfEnabled runs the internal gRPC server next to the HTTP server when
+// GRPC_ADDR is configured. It stops together with the HTTP server when ctx is cancelled.
+func startGRPCServerIfEnabled(ctx context.Context, cfg *config.Config, log *logger.Logger, deps grpcapi.Dependencies) {
+	if cfg.GetGRPCAddr() == "" {
+		return
+	}
+	grpcServer, err := grpcapi.New(grpcapi.Config{
+		Addr:           cfg.GetGRPCAddr(),
+		CertFile:       cfg.GetGRPCTLSCertFile(),
+		KeyFile:        cfg.GetGRPCTLSKeyFile(),
+		ClientCAFile:   cfg.GetGRPCTLSClientCAFile(),
+		AllowedClients: cfg.GetGRPCAllowedClients(),
+	}, deps, log)
 	if err != nil {
-		log.Error("invalid EXPORTS_ENCRYPTION_KEY (must be hex-encoded)", "error", err)
-		panic("invalid EXPORTS_ENCRYPTION_KEY: " + err.Error())
+		log.Error("failed to initialize grpc server", "error", err)
+		panic("failed to initialize grpc server: " + err.Error())
 	}
-	if len(key) != 32 {
-		log.Error("EXPORTS_ENCRYPTION_KEY must be 32 bytes (64 hex chars)", "length", len(key))
-		panic("EXPORTS_ENCRYPTION_KEY must be 32 bytes")
+	go func() {
+		if err := grpcServer.Serve(ctx); err != nil {
+			log.Error("grpc server error", "error", err)
+		}
+	}()
+}
+
+func wireExportsEncryptionKey(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, exportsMod interface{ SetKeyring(*secrets.Keyring) }) {
+	keyring := loadKeyringOrPanic(ctx, secretsSvc, log, "EXPORTS_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
 	}
 
-	exportsMod.SetEncryptionKey(key)
-	log.Info("exports encryption key configured")
+	exportsMod.SetKeyring(keyring)
+	log.Info("exports encryption key configured", "primaryKeyId", keyring.PrimaryID())
 }
 
 func wireIMAPEncryptionKey(cfg *config.Config, log *logger.Logger, imapSvc interface{ SetEncryptionKey([]byte) }) {
@@ -728,24 +1023,6 @@ func wireIMAPEncryptionKey(cfg *config.Config, log *logger.Logger, imapSvc inter
 	log.Info("imap encryption key configured")
 }
 
-func wireSMTPEncryptionKeyForIMAP(cfg *config.Config, log *logger.Logger, imapSvc interface{ SetSMTPEncryptionKey([]byte) }) {
-	smtpKeyHex := cfg.GetSMTPEncryptionKey()
-	if smtpKeyHex == "" {
-		return
-	}
-	smtpKey, err := hex.DecodeString(smtpKeyHex)
-	if err != nil {
-		log.Error("invalid SMTP_ENCRYPTION_KEY for imap (must be hex-encoded)", "error", err)
-		panic("invalid SMTP_ENCRYPTION_KEY for imap: " + err.Error())
-	}
-	if len(smtpKey) != 32 {
-		log.Error("SMTP_ENCRYPTION_KEY for imap must be 32 bytes (64 hex chars)", "length", len(smtpKey))
-		panic("SMTP_ENCRYPTION_KEY for imap must be 32 bytes")
-	}
-	imapSvc.SetSMTPEncryptionKey(smtpKey)
-	log.Info("imap smtp encryption key configured")
-}
-
 func initReminderScheduler(cfg config.SchedulerConfig, log *logger.Logger) (*scheduler.Client, func()) {
 	if cfg.GetRedisURL() == "" {
 		log.Error("REDIS_URL not configured; async scheduler is required")
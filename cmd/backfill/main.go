@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"portal_final_backend/internal/adapters"
+	"portal_final_backend/internal/backfill"
+	"portal_final_backend/internal/backfill/leadbackfill"
+	"portal_final_backend/internal/energylabel"
+	"portal_final_backend/internal/leadenrichment"
+	"portal_final_backend/internal/maps"
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// command is a backfill subcommand. run builds and runs the job; it returns
+// without error when the job is not configured.
+type command struct {
+	description string
+	defaults    backfill.Options
+	run         func(ctx context.Context, env environment, opts backfill.Options) error
+}
+
+// environment is what the subcommands need to build their jobs.
+type environment struct {
+	cfg      *config.Config
+	pool     *pgxpool.Pool
+	progress backfill.ProgressStore
+	log      *logger.Logger
+}
+
+var commands = map[string]command{
+	leadbackfill.NameGeocode: {
+		description: "store coordinates for leads without latitude/longitude",
+		defaults:    leadbackfill.GeocodeOptions,
+		run:         runLeadGeocode,
+	},
+	leadbackfill.NameEnergyLabel: {
+		description: "fetch EP-Online energy labels for leads that never had one fetched",
+		defaults:    leadbackfill.EnergyLabelOptions,
+		run:         runLeadEnergyLabel,
+	},
+	leadbackfill.NameEnrichment: {
+		description: "fetch PDOK/CBS postcode enrichment and re-score leads",
+		defaults:    leadbackfill.EnrichmentOptions,
+		run:         runLeadEnrichment,
+	},
+}
+
+// Runs one of the backfills, e.g. `backfill lead-enrichment -dry-run`. Progress
+// is saved after every batch, so an interrupted run resumes where it stopped.
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	cmd, ok := commands[name]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	opts := cmd.defaults
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.IntVar(&opts.BatchSize, "batch-size", opts.BatchSize, "records processed per batch")
+	flags.DurationVar(&opts.Interval, "interval", opts.Interval, "pause after each record that calls an external API")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "process records without writing them or the progress")
+	flags.BoolVar(&opts.Restart, "restart", false, "ignore saved progress and start from the first record")
+	_ = flags.Parse(os.Args[2:])
+
+	cfg, err := config.Load()
+	if err != nil {
+		panic("failed to load config: " + err.Error())
+	}
+	log := logger.New(cfg.Env)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := db.NewPool(ctx, cfg)
+	if err != nil {
+		log.Error("failed to connect to database", "error", err)
+		panic("failed to connect to database: " + err.Error())
+	}
+	defer pool.Close()
+
+	env := environment{cfg: cfg, pool: pool, progress: backfill.NewRepository(pool), log: log}
+	if err := cmd.run(ctx, env, opts); err != nil {
+		log.Error("backfill failed", "backfill", name, "error", err)
+		pool.Close()
+		stop()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("usage: backfill <command> [-batch-size n] [-interval d] [-dry-run] [-restart]\n\ncommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-18s %s\n", name, commands[name].description)
+	}
+	fmt.Fprint(os.Stderr, b.String())
+}
+
+func runLeadGeocode(ctx context.Context, env environment, opts backfill.Options) error {
+	mapsService := maps.NewService(env.log)
+	mapsService.SetGeocoding(maps.NewGeocodeProviders(env.cfg), maps.NewRepository(env.pool))
+
+	_, err := leadbackfill.NewGeocodeJob(env.pool, mapsService, env.log).Run(ctx, env.progress, opts)
+	return err
+}
+
+func runLeadEnergyLabel(ctx context.Context, env environment, opts backfill.Options) error {
+	if !env.cfg.IsEnergyLabelEnabled() {
+		env.log.Warn("energy label module disabled, skipping backfill")
+		return nil
+	}
+	energyModule := energylabel.NewModule(env.cfg, env.log)
+	if !energyModule.IsEnabled() {
+		env.log.Warn("energy label service not available, skipping backfill")
+		return nil
+	}
+	enricher := adapters.NewEnergyLabelAdapter(energyModule.Service())
+	if enricher == nil {
+		env.log.Warn("energy label enricher unavailable, skipping backfill")
+		return nil
+	}
+
+	_, err := leadbackfill.NewEnergyLabelJob(env.pool, enricher, env.log).Run(ctx, env.progress, opts)
+	return err
+}
+
+func runLeadEnrichment(ctx context.Context, env environment, opts backfill.Options) error {
+	enrichmentModule := leadenrichment.NewModule(env.pool, env.log)
+	enricher := adapters.NewLeadEnrichmentAdapter(enrichmentModule.Service())
+	if enricher == nil {
+		env.log.Warn("lead enrichment adapter unavailable, skipping backfill")
+		return nil
+	}
+
+	_, err := leadbackfill.NewEnrichmentJob(env.pool, enricher, env.log).Run(ctx, env.progress, opts)
+	return err
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/secrets"
+)
+
+// Encrypts and blind-indexes the consumer phone numbers and email addresses of
+// existing leads and restores plaintext columns cleared by earlier runs. Run it
+// after enabling lead contact encryption and after every rotation of
+// LEADS_CONTACT_ENCRYPTION_KEY.
+func main() {
+	var batchSize int
+	flag.IntVar(&batchSize, "batch-size", 500, "leads encrypted per transaction")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		panic("failed to load config: " + err.Error())
+	}
+
+	log := logger.New(cfg.Env)
+	log.Info("starting lead contact encryption backfill")
+
+	ctx := context.Background()
+	secretsSvc, err := secrets.NewFromConfig(cfg)
+	if err != nil {
+		log.Error("failed to initialize secrets backend", "error", err)
+		panic("failed to initialize secrets backend: " + err.Error())
+	}
+	keyring, err := secretsSvc.Keyring(ctx, "LEADS_CONTACT_ENCRYPTION_KEY")
+	if err != nil {
+		log.Error("invalid LEADS_CONTACT_ENCRYPTION_KEY", "error", err)
+		panic("invalid LEADS_CONTACT_ENCRYPTION_KEY: " + err.Error())
+	}
+	indexKeyring, err := secretsSvc.Keyring(ctx, "LEADS_CONTACT_INDEX_KEY")
+	if err != nil {
+		log.Error("invalid LEADS_CONTACT_INDEX_KEY", "error", err)
+		panic("invalid LEADS_CONTACT_INDEX_KEY: " + err.Error())
+	}
+	if keyring == nil || indexKeyring == nil {
+		log.Warn("LEADS_CONTACT_ENCRYPTION_KEY and LEADS_CONTACT_INDEX_KEY not configured, nothing to encrypt")
+		return
+	}
+
+	pool, err := db.NewPool(ctx, cfg)
+	if err != nil {
+		log.Error("failed to connect to database", "error", err)
+		panic("failed to connect to database: " + err.Error())
+	}
+	defer pool.Close()
+
+	repo := repository.New(pool)
+	repo.SetContactCipher(repository.NewContactCipher(keyring, indexKeyring.PrimaryKey()))
+	total := 0
+	for {
+		n, err := repo.EncryptContactBatch(ctx, batchSize)
+		if err != nil {
+			log.Error("failed to encrypt lead contacts", "encrypted", total, "error", err)
+			return
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+		log.Info("encrypted lead contacts", "batch", n, "total", total)
+	}
+
+	log.Info("lead contact encryption backfill completed", "encrypted", total, "primaryKeyId", keyring.PrimaryID())
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"portal_final_backend/internal/notification/outbox"
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/secrets"
+)
+
+// Encrypts notification outbox payloads stored as plaintext and re-encrypts
+// payloads sealed with a key other than the primary OUTBOX_ENCRYPTION_KEY key.
+// Run it after enabling encryption and after every key rotation.
+func main() {
+	var batchSize int
+	flag.IntVar(&batchSize, "batch-size", 500, "rows encrypted per transaction")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		panic("failed to load config: " + err.Error())
+	}
+
+	log := logger.New(cfg.Env)
+	log.Info("starting notification outbox payload encryption")
+
+	ctx := context.Background()
+	secretsSvc, err := secrets.NewFromConfig(cfg)
+	if err != nil {
+		log.Error("failed to initialize secrets backend", "error", err)
+		panic("failed to initialize secrets backend: " + err.Error())
+	}
+	keyring, err := secretsSvc.Keyring(ctx, "OUTBOX_ENCRYPTION_KEY")
+	if err != nil {
+		log.Error("invalid OUTBOX_ENCRYPTION_KEY", "error", err)
+		panic("invalid OUTBOX_ENCRYPTION_KEY: " + err.Error())
+	}
+	if keyring == nil {
+		log.Warn("OUTBOX_ENCRYPTION_KEY not configured, nothing to encrypt")
+		return
+	}
+	outbox.SetKeyring(keyring)
+
+	pool, err := db.NewPool(ctx, cfg)
+	if err != nil {
+		log.Error("failed to connect to database", "error", err)
+		panic("failed to connect to database: " + err.Error())
+	}
+	defer pool.Close()
+
+	repo := outbox.New(pool)
+	total := 0
+	for {
+		n, err := repo.EncryptBatch(ctx, batchSize)
+		if err != nil {
+			log.Error("failed to encrypt outbox payloads", "encrypted", total, "error", err)
+			return
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+		log.Info("encrypted outbox payloads", "batch", n, "total", total)
+	}
+
+	log.Info("notification outbox payload encryption completed", "encrypted", total, "primaryKeyId", keyring.PrimaryID())
+}
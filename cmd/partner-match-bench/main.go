@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// legacyMatchQuery is the earth_distance partner search that ran before
+// migration 229. It computes the distance to every partner of the
+// organization and is kept here only as the baseline.
+const legacyMatchQuery = `
+SELECT p.id
+FROM RAC_partners p
+JOIN RAC_partner_service_types pst ON pst.partner_id = p.id
+JOIN RAC_service_types st ON st.id = pst.service_type_id AND st.organization_id = p.organization_id
+WHERE p.organization_id = $3
+	AND st.is_active = true
+	AND (st.name = $4 OR st.slug = $4)
+	AND p.latitude IS NOT NULL AND p.longitude IS NOT NULL
+	AND earth_distance(ll_to_earth($2, $1), ll_to_earth(p.latitude, p.longitude)) <= ($5 * 1000.0)
+ORDER BY earth_distance(ll_to_earth($2, $1), ll_to_earth(p.latitude, p.longitude)) ASC
+LIMIT 5
+`
+
+// Compares the latency of the PostGIS partner search with the earth_distance
+// query it replaced, for one lead of an organization.
+func main() {
+	var (
+		orgID       string
+		leadID      string
+		serviceType string
+		radiusKm    int
+		runs        int
+	)
+	flag.StringVar(&orgID, "org", "", "organization ID")
+	flag.StringVar(&leadID, "lead", "", "lead ID whose coordinates anchor the search")
+	flag.StringVar(&serviceType, "service-type", "", "service type name or slug")
+	flag.IntVar(&radiusKm, "radius", 50, "search radius in km")
+	flag.IntVar(&runs, "runs", 50, "timed runs per query")
+	flag.Parse()
+
+	organizationID, err := uuid.Parse(orgID)
+	if err != nil {
+		panic("invalid -org: " + err.Error())
+	}
+	anchorLeadID, err := uuid.Parse(leadID)
+	if err != nil {
+		panic("invalid -lead: " + err.Error())
+	}
+	if serviceType == "" || runs <= 0 {
+		panic("-service-type and a positive -runs are required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		panic("failed to load config: " + err.Error())
+	}
+	log := logger.New(cfg.Env)
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, cfg)
+	if err != nil {
+		log.Error("failed to connect to database", "error", err)
+		panic("failed to connect to database: " + err.Error())
+	}
+	defer pool.Close()
+
+	var lat, lon float64
+	if err := pool.QueryRow(ctx, `
+		SELECT latitude, longitude FROM RAC_leads
+		WHERE id = $1 AND organization_id = $2 AND latitude IS NOT NULL AND longitude IS NOT NULL`,
+		anchorLeadID, organizationID,
+	).Scan(&lat, &lon); err != nil {
+		panic("lead has no coordinates: " + err.Error())
+	}
+
+	var partners int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM RAC_partners WHERE organization_id = $1`, organizationID).Scan(&partners); err != nil {
+		panic("failed to count partners: " + err.Error())
+	}
+
+	repo := repository.New(pool)
+	legacy := measure(runs, func() error {
+		return runLegacyMatch(ctx, pool, lat, lon, organizationID, serviceType, radiusKm)
+	})
+	postgis := measure(runs, func() error {
+		_, err := repo.FindMatchingPartners(ctx, organizationID, anchorLeadID, serviceType, "", radiusKm, nil)
+		return err
+	})
+
+	fmt.Printf("partners in organization: %d, radius: %d km, runs: %d\n", partners, radiusKm, runs)
+	legacy.print("earth_distance")
+	postgis.print("postgis")
+}
+
+func runLegacyMatch(ctx context.Context, pool *pgxpool.Pool, lat, lon float64, organizationID uuid.UUID, serviceType string, radiusKm int) error {
+	rows, err := pool.Query(ctx, legacyMatchQuery, lon, lat, organizationID, serviceType, radiusKm)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
+
+type latencies []time.Duration
+
+// measure runs fn once to warm up and then times it runs times.
+func measure(runs int, fn func() error) latencies {
+	if err := fn(); err != nil {
+		panic("query failed: " + err.Error())
+	}
+	samples := make(latencies, 0, runs)
+	for i := 0; i < runs; i++ {
+		start := time.Now()
+		if err := fn(); err != nil {
+			panic("query failed: " + err.Error())
+		}
+		samples = append(samples, time.Since(start))
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return samples
+}
+
+func (l latencies) percentile(p float64) time.Duration {
+	return l[int(p*float64(len(l)-1))]
+}
+
+func (l latencies) print(name string) {
+	fmt.Printf("%-15s p50=%v p95=%v max=%v\n", name, l.percentile(0.5), l.percentile(0.95), l[len(l)-1])
+}
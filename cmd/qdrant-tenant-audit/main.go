@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/qdrant"
+)
+
+// main scans the tenant-owned catalog collection for points without an organization
+// payload. It exits with status 1 when such points are found so it can gate deploys.
+func main() {
+	var collection string
+	var organizationID string
+	var sampleSize int
+	flag.StringVar(&collection, "collection", "", "collection to audit (defaults to CATALOG_EMBEDDING_COLLECTION)")
+	flag.StringVar(&organizationID, "organization", "", "audit the per-tenant collection of this organization")
+	flag.IntVar(&sampleSize, "sample", 20, "number of offending point IDs to report")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		panic("failed to load config: " + err.Error())
+	}
+
+	log := logger.New(cfg.Env)
+	if cfg.GetQdrantURL() == "" {
+		log.Warn("qdrant not configured, skipping tenant audit")
+		return
+	}
+	if collection == "" {
+		collection = cfg.GetCatalogEmbeddingCollection()
+	}
+
+	client := qdrant.NewClient(qdrant.Config{
+		BaseURL:     cfg.GetQdrantURL(),
+		APIKey:      cfg.GetQdrantAPIKey(),
+		Collection:  collection,
+		TenancyMode: cfg.GetQdrantTenancyMode(),
+		Timeout:     2 * time.Minute,
+	})
+	if organizationID != "" {
+		collection = client.TenantCollection(organizationID)
+	}
+
+	log.Info("starting qdrant tenant audit", "collection", collection, "tenancyMode", client.TenancyMode())
+	audit, err := client.AuditTenantPayloads(context.Background(), collection, sampleSize)
+	if err != nil {
+		log.Error("tenant audit failed", "collection", collection, "error", err)
+		os.Exit(2)
+	}
+
+	if audit.Missing == 0 {
+		log.Info("tenant audit passed", "collection", audit.Collection, "points", audit.Total)
+		return
+	}
+
+	sample := make([]string, 0, len(audit.SampleIDs))
+	for _, id := range audit.SampleIDs {
+		sample = append(sample, fmt.Sprint(id))
+	}
+	log.Error("points without organization payload found",
+		"collection", audit.Collection,
+		"points", audit.Total,
+		"missing", audit.Missing,
+		"sampleIds", sample,
+	)
+	os.Exit(1)
+}
@@ -14,33 +14,65 @@ import (
 
 	"portal_final_backend/internal/adapters"
 	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/analytics"
+	analyticsservice "portal_final_backend/internal/analytics/service"
 	"portal_final_backend/internal/appointments"
+	"portal_final_backend/internal/auditexport"
+	auditexportservice "portal_final_backend/internal/auditexport/service"
+	authrepo "portal_final_backend/internal/auth/repository"
+	"portal_final_backend/internal/backfill"
+	"portal_final_backend/internal/backfill/leadbackfill"
 	"portal_final_backend/internal/catalog"
+	catalogservice "portal_final_backend/internal/catalog/service"
 	"portal_final_backend/internal/email"
+	"portal_final_backend/internal/energylabel"
 	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/files"
+	filesservice "portal_final_backend/internal/files/service"
 	identityrepo "portal_final_backend/internal/identity/repository"
 	identityservice "portal_final_backend/internal/identity/service"
 	"portal_final_backend/internal/imap"
+	jobsrepo "portal_final_backend/internal/jobs/repository"
+	jobsservice "portal_final_backend/internal/jobs/service"
+	"portal_final_backend/internal/kvk"
+	kvkservice "portal_final_backend/internal/kvk/service"
+	"portal_final_backend/internal/leadenrichment"
 	"portal_final_backend/internal/leads"
 	leadagent "portal_final_backend/internal/leads/agent"
 	"portal_final_backend/internal/leads/maintenance"
 	leadrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/maps"
 	"portal_final_backend/internal/notification"
 	"portal_final_backend/internal/notification/outbox"
 	"portal_final_backend/internal/partners"
 	partnersrepo "portal_final_backend/internal/partners/repository"
 	"portal_final_backend/internal/pdf"
 	"portal_final_backend/internal/quotes"
+	"portal_final_backend/internal/residency"
+	residencyservice "portal_final_backend/internal/residency/service"
+	"portal_final_backend/internal/retention"
+	retentionservice "portal_final_backend/internal/retention/service"
 	"portal_final_backend/internal/scheduler"
+	"portal_final_backend/internal/storagequota"
+	storagequotaservice "portal_final_backend/internal/storagequota/service"
 	"portal_final_backend/internal/tasks"
+	"portal_final_backend/internal/triage"
+	"portal_final_backend/internal/uploads"
+	uploadsservice "portal_final_backend/internal/uploads/service"
+	"portal_final_backend/internal/webhook"
 	"portal_final_backend/internal/whatsapp"
 	"portal_final_backend/internal/whatsappagent"
 	whatsappagentdb "portal_final_backend/internal/whatsappagent/db"
+	"portal_final_backend/internal/workorders"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/transcription"
+	"portal_final_backend/platform/circuitbreaker"
 	"portal_final_backend/platform/config"
 	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/httpkit"
 	"portal_final_backend/platform/logger"
 	"portal_final_backend/platform/rediskit"
+	"portal_final_backend/platform/secrets"
 	"portal_final_backend/platform/validator"
 
 	"github.com/google/uuid"
@@ -77,6 +109,8 @@ func initStorageOrPanic(ctx context.Context, cfg *config.Config, log *logger.Log
 	ensureBucket(ctx, log, storageSvc, "organization-logos", cfg.GetMinioBucketOrganizationLogos())
 	ensureBucket(ctx, log, storageSvc, "quote-pdfs", cfg.GetMinioBucketQuotePDFs())
 	ensureBucket(ctx, log, storageSvc, "quote-attachments", cfg.GetMinioBucketQuoteAttachments())
+	ensureBucket(ctx, log, storageSvc, "files", cfg.GetMinioBucketFiles())
+	ensureBucket(ctx, log, storageSvc, "audit-exports", cfg.GetMinioBucketAuditExports())
 	log.Info(
 		"storage service initialized",
 		"leadAttachmentsBucket", cfg.GetMinioBucketLeadServiceAttachments(),
@@ -85,6 +119,8 @@ func initStorageOrPanic(ctx context.Context, cfg *config.Config, log *logger.Log
 		"organizationLogosBucket", cfg.GetMinioBucketOrganizationLogos(),
 		"quotePDFsBucket", cfg.GetMinioBucketQuotePDFs(),
 		"quoteAttachmentsBucket", cfg.GetMinioBucketQuoteAttachments(),
+		"filesBucket", cfg.GetMinioBucketFiles(),
+		"auditExportsBucket", cfg.GetMinioBucketAuditExports(),
 	)
 
 	return storageSvc
@@ -142,6 +178,11 @@ func main() {
 
 	log := logger.New(cfg.Env)
 	log.Info("starting scheduler", "env", cfg.Env)
+	circuitbreaker.Configure(circuitbreaker.Settings{
+		FailureThreshold: cfg.GetCircuitBreakerFailureThreshold(),
+		OpenTimeout:      cfg.GetCircuitBreakerOpenTimeout(),
+		HalfOpenProbes:   cfg.GetCircuitBreakerHalfOpenProbes(),
+	})
 	initGotenbergIfEnabled(cfg, log)
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -179,11 +220,26 @@ func main() {
 	notificationModule.RegisterHandlers(eventBus)
 	whatsAppClient := whatsapp.NewClient(cfg, log)
 	leadReader := leadrepo.New(pool)
-	storageSvc := initStorageOrPanic(ctx, cfg, log)
+	val := validator.New()
+	rawStorage := initStorageOrPanic(ctx, cfg, log)
+	storageQuotaModule := storagequota.NewModule(pool, rawStorage, storagequotaservice.Config{
+		SoftLimitBytes: cfg.GetStorageQuotaSoftLimitBytes(),
+		HardLimitBytes: cfg.GetStorageQuotaHardLimitBytes(),
+		Buckets:        cfg.GetMinioBuckets(),
+	}, val, log)
+	storageSvc := storageQuotaModule.Storage()
 	notificationModule.SetWhatsAppSender(whatsAppClient)
 	notificationModule.SetLeadWhatsAppReader(leadReader)
+	notificationModule.SetLeadConsentReader(leadReader)
+	notificationModule.SetLeadMarketingConfirmationStore(leadReader)
+	notificationModule.SetLeadLanguageReader(leadReader)
 	notificationModule.SetOrganizationMemberReader(leadReader)
 	notificationModule.SetNotificationOutbox(outbox.New(pool))
+	// Triage inbox: stale leads, failed energy label refreshes and messages
+	// the outbox gave up on queue their lead for a human.
+	triageModule := triage.NewModule(pool, val, log)
+	triageModule.RegisterHandlers(eventBus)
+	notificationModule.SetLeadTriageRecorder(triageModule.Service())
 	identityReader := identityrepo.New(pool)
 	identitySvc := identityservice.New(
 		identityReader,
@@ -194,11 +250,20 @@ func main() {
 		whatsAppClient,
 	)
 	notificationModule.SetOrganizationSettingsReader(identityReader)
+	notificationModule.SetBrandingReader(identityReader)
+	notificationModule.SetOrganizationLanguageReader(identityReader)
 	notificationModule.SetUserTenancyReader(identitySvc)
 	notificationModule.SetWorkflowResolver(identitySvc)
-	wireSchedulerSMTPEncryptionKey(cfg, log, identitySvc, notificationModule)
+	notificationModule.SetPortalDomainResolver(identitySvc)
+	secretsSvc := initSchedulerSecretsOrPanic(cfg, log)
+	smtpKeyring := wireSchedulerSMTPEncryptionKey(ctx, secretsSvc, log, identitySvc, notificationModule)
+	wireSchedulerOutboxEncryptionKey(ctx, secretsSvc, log)
+	leadContact := wireSchedulerLeadContactEncryption(ctx, secretsSvc, log)
+	leadReader.SetContactCipher(leadContact)
+	identityReader.SetLeadContactCipher(leadContact)
 
-	val := validator.New()
+	aiUsageLedger := adapters.NewAIUsageLedgerAdapter(identitySvc)
+	llmrouter.SetDefault(llmrouter.New(cfg.ResolveProviderConfig, aiUsageLedger.Settings, aiUsageLedger, log))
 
 	// Worker-side quote generation wiring (no HTTP handlers required).
 	catalogModule := catalog.NewModule(pool, storageSvc, cfg.GetMinioBucketCatalogAssets(), val, cfg, log)
@@ -212,15 +277,22 @@ func main() {
 		log.Error("failed to initialize leads module", "error", err)
 		panic("failed to initialize leads module: " + err.Error())
 	}
+	leadsModule.SetContactCipher(leadContact)
 	partnersModule := partners.NewModule(pool, eventBus, storageSvc, cfg.GetMinioBucketPartnerLogos(), val)
 	quotesModule := quotes.NewModule(pool, eventBus, val)
 	quotesModule.Service().SetLeadTransferCreator(leadsModule.ManagementService())
 	quotesModule.Service().SetLeadTransferRepository(leadsModule.Repository())
+	quotesModule.SetGenerateQuoteJobQueue(reminderScheduler)
 	leadsModule.GetSubsidyAnalyzerService().SetSchedulerClient(*reminderScheduler)
 	leadsModule.GetSubsidyAnalyzerService().SetQuoteRepo(*quotesModule.Repository())
 	quotesModule.SetSubsidyAnalyzerService(leadsModule.GetSubsidyAnalyzerService())
 	tasksModule := tasks.NewModule(pool, val, reminderScheduler, leadsModule.Repository(), log)
+	tasksModule.RegisterHandlers(eventBus)
 	leadsModule.ManagementService().SetAcceptedQuoteUpdater(quotesModule.Service())
+	// Quotes sent by the auto-send sweep need the timeline and the contact
+	// details for the customer email, as when an agent sends them.
+	quotesModule.Service().SetTimelineWriter(adapters.NewQuotesTimelineWriter(leadsModule.Repository()))
+	quotesModule.Service().SetQuoteContactReader(adapters.NewQuotesContactReader(leadsModule.Repository(), identitySvc, authrepo.New(pool)))
 
 	catalogReader := adapters.NewCatalogProductReader(catalogModule.Repository())
 	leadsModule.SetCatalogReader(catalogReader)
@@ -260,17 +332,122 @@ func main() {
 	go dispatcher.Run(ctx)
 
 	cleanupInterval := getDurationEnv("AI_QUOTE_JOB_CLEANUP_INTERVAL", time.Hour)
+	recoveryInterval := getDurationEnv("AI_QUOTE_JOB_RECOVERY_INTERVAL", time.Minute)
 	completedRetention := time.Duration(getPositiveIntEnv("AI_QUOTE_JOB_COMPLETED_RETENTION_DAYS", 14)) * 24 * time.Hour
 	failedRetention := time.Duration(getPositiveIntEnv("AI_QUOTE_JOB_FAILED_RETENTION_DAYS", 30)) * 24 * time.Hour
-	aiQuoteJobCleanup := scheduler.NewAIQuoteJobCleanup(pool, log, cleanupInterval, completedRetention, failedRetention)
-	go aiQuoteJobCleanup.Run(ctx)
+	aiQuoteJobCleanup := scheduler.NewAIQuoteJobCleanup(pool, log, completedRetention, failedRetention)
 
 	// Periodic catalog gap analyzer ("Librarian"): turns frequent 0-result searches
 	// and ad-hoc quote items into draft catalog products for human review.
 	gapInterval := getDurationEnv("CATALOG_GAP_ANALYZER_INTERVAL", 6*time.Hour)
 	maxDrafts := getPositiveIntEnv("CATALOG_GAP_MAX_DRAFTS_PER_RUN", 10)
 	gapAnalyzer := maintenance.NewCatalogGapAnalyzer(leadrepo.New(pool), catalogModule.Repository(), log)
-	go runCatalogGapAnalyzerLoop(ctx, pool, gapAnalyzer, gapInterval, maxDrafts, log)
+
+	// Periodic KvK revalidation: re-checks linked partners and leads and flags
+	// companies that have been dissolved since their last check.
+	kvkModule := kvk.NewModule(pool, eventBus, val, cfg, log)
+	if kvkModule.IsEnabled() {
+		kvkInterval := getDurationEnv("KVK_REVALIDATION_INTERVAL", 24*time.Hour)
+		kvkMaxAge := getDurationEnv("KVK_REVALIDATION_MAX_AGE", kvkservice.DefaultRevalidationAge)
+		kvkBatchSize := getPositiveIntEnv("KVK_REVALIDATION_BATCH_SIZE", 200)
+		go runKvKRevalidationLoop(ctx, kvkModule.Service(), kvkInterval, kvkMaxAge, kvkBatchSize, log)
+	}
+
+	// Periodic file orphan cleanup: removes abandoned uploads and objects in the
+	// files bucket that have no metadata.
+	filesModule := files.NewModule(pool, storageSvc, cfg.GetMinioBucketFiles(), val, log)
+	fileCleanupInterval := getDurationEnv("FILES_ORPHAN_CLEANUP_INTERVAL", 6*time.Hour)
+	fileCleanupGrace := getDurationEnv("FILES_ORPHAN_GRACE_PERIOD", filesservice.DefaultOrphanGracePeriod)
+
+	// Resumable upload expiry sweep: aborts uploads that received no part within
+	// the session TTL, so their stored parts do not linger in the buckets.
+	uploadsModule := uploads.NewModule(pool, storageSvc, uploadsservice.Config{
+		PartSize:   cfg.GetUploadPartSizeBytes(),
+		SessionTTL: cfg.GetUploadSessionTTL(),
+	}, val, log)
+	uploadExpirySweepInterval := getDurationEnv("UPLOAD_EXPIRY_SWEEP_INTERVAL", 15*time.Minute)
+
+	// Attachment rendition sweep: renders web-optimized and thumbnail copies of
+	// newly uploaded photos. It runs at default priority as users wait for it.
+	renditionGenerator := maintenance.NewAttachmentRenditionGenerator(leadrepo.New(pool), storageSvc, cfg.GetMinioBucketLeadServiceAttachments(), log)
+	renditionSweepInterval := getDurationEnv("ATTACHMENT_RENDITION_SWEEP_INTERVAL", time.Minute)
+
+	// Periodic energy label refresh: re-fetches labels older than the refresh age.
+	// A changed label is recorded on the lead timeline and re-scores the lead.
+	energyLabelModule := energylabel.NewModule(cfg, log)
+	if energyLabelModule.IsEnabled() {
+		leadsModule.SetEnergyLabelEnricher(adapters.NewEnergyLabelAdapter(energyLabelModule.Service()))
+	}
+	energyLabelRefreshInterval := getDurationEnv("ENERGY_LABEL_REFRESH_INTERVAL", time.Hour)
+	energyLabelRefreshMaxAgeMonths := getPositiveIntEnv("ENERGY_LABEL_REFRESH_MAX_AGE_MONTHS", 6)
+	energyLabelRefreshBatchSize := getPositiveIntEnv("ENERGY_LABEL_REFRESH_BATCH_SIZE", 50)
+
+	// Periodic catalog re-index: re-embeds products whose vectors are missing,
+	// outdated or produced by a previous embedding model or version.
+	reindexInterval := getDurationEnv("CATALOG_REINDEX_INTERVAL", time.Hour)
+	reindexBatchSize := getPositiveIntEnv("CATALOG_REINDEX_BATCH_SIZE", 100)
+	reindexDelay := getDurationEnv("CATALOG_REINDEX_DELAY", 250*time.Millisecond)
+	go runCatalogReindexLoop(ctx, catalogModule.Service(), reindexInterval, reindexBatchSize, reindexDelay, log)
+
+	// Nightly storage usage reconciliation: recomputes per-organization usage
+	// from the bucket listings to correct drift in the running counters.
+	reconcileHour := getPositiveIntEnv("STORAGE_USAGE_RECONCILE_HOUR", 3)
+	go runStorageUsageReconcileLoop(ctx, storageQuotaModule.Service(), reconcileHour, log)
+
+	// Quote installment sweep: announces payment schedule installments of accepted
+	// quotes that became due, which the notification module turns into reminders.
+	installmentSweepInterval := getDurationEnv("QUOTE_INSTALLMENT_SWEEP_INTERVAL", time.Hour)
+
+	// Warranty expiry sweep: announces warranties of completed work orders that
+	// expire soon, which the notification module turns into maintenance offers.
+	workOrdersModule := workorders.NewModule(pool, eventBus, leadsModule.Repository(), val, log)
+	warrantyExpirySweepInterval := getDurationEnv("WARRANTY_EXPIRY_SWEEP_INTERVAL", 6*time.Hour)
+
+	// Portal domain maintenance: expires unverified domain claims and re-checks
+	// the TXT record of verified portal domains.
+	portalDomainInterval := getDurationEnv("PORTAL_DOMAIN_CHECK_INTERVAL", time.Hour)
+
+	// Quote auto-send sweep: sends AI-drafted quotes whose review window ended
+	// without an agent intervening, as configured by the auto-send policy.
+	autoSendSweepInterval := getDurationEnv("QUOTE_AUTO_SEND_SWEEP_INTERVAL", time.Minute)
+
+	// Agent approval expiry sweep: closes reviews of AI actions whose SLA ran
+	// out after the waiting agent run ended, so they leave the review queue.
+	approvalExpirySweepInterval := getDurationEnv("AGENT_APPROVAL_EXPIRY_SWEEP_INTERVAL", time.Minute)
+
+	// Lead connector poll sweep: pulls new leads from the marketplaces of polling
+	// connectors whose poll interval has passed.
+	webhookModule := webhook.NewModule(pool, leadsModule.ManagementService(), storageSvc, cfg.GetMinioBucketLeadServiceAttachments(), eventBus, val, log)
+	wireSchedulerLeadConnectorKeyring(ctx, secretsSvc, log, webhookModule)
+	webhookModule.SetLeadContactCipher(leadContact)
+	connectorPollSweepInterval := getDurationEnv("LEAD_CONNECTOR_POLL_SWEEP_INTERVAL", time.Minute)
+
+	// Catalog price feed sweep: fetches the supplier price feeds whose fetch
+	// interval has passed, updates costs and sell prices and alerts on price jumps.
+	catalogModule.SetEventBus(eventBus)
+	wireSchedulerCatalogPriceFeedKeyring(ctx, secretsSvc, log, catalogModule)
+	priceFeedSweepInterval := getDurationEnv("CATALOG_PRICE_FEED_SWEEP_INTERVAL", time.Hour)
+
+	// Funnel analytics refresh: rebuilds the recent daily aggregates behind the
+	// dashboard charts from the lead service events and quotes.
+	analyticsModule := analytics.NewModule(pool, analyticsservice.Config{
+		RefreshLookbackDays: getPositiveIntEnv("ANALYTICS_REFRESH_LOOKBACK_DAYS", analyticsservice.DefaultRefreshLookbackDays),
+	}, val, log)
+	analyticsRefreshInterval := getDurationEnv("ANALYTICS_REFRESH_INTERVAL", time.Hour)
+
+	// Data retention: applies the per-organization policies that anonymize or
+	// purge rejected leads, trim the timeline and remove orphaned draft quotes.
+	retentionModule := retention.NewModule(pool, retentionservice.Config{
+		BatchSize: getPositiveIntEnv("RETENTION_BATCH_SIZE", retentionservice.DefaultBatchSize),
+	}, val, log)
+	retentionInterval := getDurationEnv("RETENTION_APPLY_INTERVAL", 24*time.Hour)
+
+	// Compliance audit exports: writes the audit log, communications, auth
+	// events and API usage of each opted-in organization to the audit export
+	// bucket once its cadence period completes, and removes expired snapshots.
+	// Exports bypass the storage quota.
+	auditExportModule := auditexport.NewModule(pool, rawStorage, auditexportservice.Config{Bucket: cfg.GetMinioBucketAuditExports()}, val, log)
+	auditExportInterval := getDurationEnv("AUDIT_EXPORT_INTERVAL", time.Hour)
 
 	// Morning daily digest: sends a summary email to admin users each morning.
 	digestHour := getPositiveIntEnv("DAILY_DIGEST_HOUR", 7)
@@ -278,9 +455,11 @@ func main() {
 	digestService := maintenance.NewDailyDigestService(pool, staleDetector, log)
 	go runDailyDigestLoop(ctx, pool, digestService, sender, digestHour, cfg, log)
 
-	// Stale lead in-app notification sweep: enqueues per-lead notifications for
-	// all organisations so agents are nudged about leads that have gone quiet.
+	// Stale lead sweep: enqueues per-lead nudges for all organisations so agents
+	// get a notification and a follow-up task for leads that have gone quiet.
 	staleNotifier := maintenance.NewStaleLeadNotifier(pool, notificationModule.InAppService(), log)
+	staleNotifier.SetTaskCreator(adapters.NewStaleLeadTaskCreatorAdapter(tasksModule.Service()))
+	staleNotifier.SetEventBus(eventBus)
 	staleLeadSweepInterval := getDurationEnv("STALE_LEAD_SWEEP_INTERVAL", 4*time.Hour)
 
 	worker, err := scheduler.NewWorker(cfg, pool, eventBus, log)
@@ -288,6 +467,13 @@ func main() {
 		log.Error("failed to initialize scheduler worker", "error", err)
 		panic("failed to initialize scheduler worker: " + err.Error())
 	}
+	// Tasks of organizations with a dedicated data schema run against that
+	// schema; sweeps across organizations visit every schema.
+	residencyModule := residency.NewModule(pool, residencyservice.Config{}, log)
+	worker.SetTenantContextBinder(residencyModule.Service().BindTenant)
+	httpkit.SetTenantContextBinder(residencyModule.Service().BindTenant)
+	db.SetSchemaLister(residencyModule.Service().ListSchemas)
+	go residencyModule.Service().WatchPlacements(ctx)
 	worker.SetQuoteJobProcessor(quotesModule.Service())
 	worker.SetCallLogProcessor(leadsModule)
 	worker.SetLeadAutomationProcessor(leadsModule)
@@ -297,16 +483,20 @@ func main() {
 	worker.SetOfferSummaryProcessor(partnersModule.Service())
 	worker.SetTaskReminderProcessor(tasksModule.Service())
 	imapModule := imap.NewModule(pool, val, eventBus, log)
+	imapModule.SetLeadContactCipher(leadContact)
 	worker.SetIMAPSyncProcessor(imapModule.Service())
 	wireSchedulerIMAPEncryptionKey(cfg, log, imapModule.Service())
 
 	notificationModule.SetQuotePDFStorage(storageSvc, cfg.GetMinioBucketQuotePDFs())
 	quoteTermsResolver := adapters.NewQuoteTermsResolverAdapter(identitySvc, identitySvc, leadsModule.Repository())
+	quotesModule.Service().SetQuoteTermsResolver(quoteTermsResolver)
 	quotePDFProcessor := adapters.NewQuoteAcceptanceProcessor(quotesModule.Repository(), identitySvc, nil, storageSvc, cfg, quoteTermsResolver)
+	quotePDFProcessor.SetTermsDocumentReader(quotesModule.Service())
 	notificationModule.SetQuotePDFGenerator(quotePDFProcessor)
 	worker.SetAcceptedQuotePDFProcessor(quotePDFProcessor)
 
 	offerPDFProcessor := adapters.NewPartnerOfferPDFProcessor(partnersrepo.New(pool), identitySvc, storageSvc, cfg, sender)
+	offerPDFProcessor.SetSMTPKeyring(smtpKeyring)
 	worker.SetOfferPDFProcessor(offerPDFProcessor)
 	audioTranscriber, closeTranscriber := initAudioTranscriber(log)
 	defer closeTranscriber()
@@ -352,6 +542,157 @@ func main() {
 	}
 	worker.SetWAAgentVoiceTranscriptionProcessor(whatsappagentModule.Service())
 
+	// Periodic maintenance runs as low priority tasks, so it shares retries,
+	// organization fairness and the job dashboard with all other work. The gap
+	// sweep fans out one analysis task per organization.
+	worker.SetCatalogGapProcessor(catalogGapProcessor{analyzer: gapAnalyzer, log: log})
+	worker.HandleTenantMaintenance(scheduler.TaskAIQuoteJobCleanup, aiQuoteJobCleanup.Cleanup)
+	worker.HandleTenantMaintenance(scheduler.TaskAIQuoteJobRecovery, func(ctx context.Context) error {
+		result, err := quotesModule.Service().RecoverInterruptedGenerateQuoteJobs(ctx, time.Now())
+		if result.Requeued+result.Failed > 0 {
+			log.Info("ai quote job recovery completed", "requeued", result.Requeued, "failed", result.Failed)
+		}
+		return err
+	})
+	worker.HandleMaintenance(scheduler.TaskCatalogGapSweep, func(ctx context.Context) error {
+		return runCatalogGapSweep(ctx, pool, reminderScheduler, maxDrafts, log)
+	})
+	worker.HandleMaintenance(scheduler.TaskFileOrphanCleanup, func(ctx context.Context) error {
+		return runFileOrphanCleanupOnce(ctx, filesModule.Service(), fileCleanupGrace, log)
+	})
+	worker.HandleMaintenance(scheduler.TaskUploadExpirySweep, func(ctx context.Context) error {
+		expired, err := uploadsModule.Service().ExpireAbandoned(ctx, time.Now())
+		if expired > 0 {
+			log.Info("upload expiry sweep completed", "expired", expired)
+		}
+		return err
+	})
+	worker.HandleTenantMaintenance(scheduler.TaskAttachmentRenditionSweep, func(ctx context.Context) error {
+		result, err := renditionGenerator.Run(ctx)
+		if result.Processed+result.Skipped+result.Failed > 0 {
+			log.Info("attachment rendition sweep completed", "processed", result.Processed, "skipped", result.Skipped, "failed", result.Failed)
+		}
+		return err
+	})
+	worker.HandleTenantMaintenance(scheduler.TaskEnergyLabelRefresh, func(ctx context.Context) error {
+		fetchedBefore := time.Now().AddDate(0, -energyLabelRefreshMaxAgeMonths, 0)
+		result, err := leadsModule.ManagementService().RefreshStaleEnergyLabels(ctx, fetchedBefore, energyLabelRefreshBatchSize)
+		if result.Checked > 0 {
+			log.Info("energy label refresh completed", "checked", result.Checked, "changed", result.Changed, "failed", result.Failed)
+		}
+		return err
+	})
+	worker.HandleTenantMaintenance(scheduler.TaskWarrantyExpirySweep, func(ctx context.Context) error {
+		published, err := workOrdersModule.Service().PublishExpiringWarranties(ctx, time.Now())
+		if published > 0 {
+			log.Info("warranty expiry sweep completed", "published", published)
+		}
+		return err
+	})
+	worker.HandleMaintenance(scheduler.TaskPortalDomainMaintenance, func(ctx context.Context) error {
+		result, err := identitySvc.MaintainPortalDomains(ctx, time.Now())
+		if result.ExpiredClaims > 0 || result.Checked > 0 {
+			log.Info("portal domain maintenance completed", "expiredClaims", result.ExpiredClaims, "checked", result.Checked, "revoked", result.Revoked, "failed", result.Failed)
+		}
+		return err
+	})
+	worker.HandleTenantMaintenance(scheduler.TaskQuoteInstallmentDueSweep, func(ctx context.Context) error {
+		published, err := quotesModule.Service().PublishDueInstallments(ctx, time.Now())
+		if published > 0 {
+			log.Info("quote installment due sweep completed", "published", published)
+		}
+		return err
+	})
+	worker.HandleTenantMaintenance(scheduler.TaskQuoteAutoSendSweep, func(ctx context.Context) error {
+		sent, err := quotesModule.Service().SendDueAutoSends(ctx, time.Now())
+		if sent > 0 {
+			log.Info("quote auto-send sweep completed", "sent", sent)
+		}
+		return err
+	})
+	worker.HandleMaintenance(scheduler.TaskAgentApprovalExpirySweep, func(ctx context.Context) error {
+		expired, err := leadsModule.Repository().ExpireOverdueAgentApprovals(ctx, time.Now())
+		if expired > 0 {
+			log.Info("agent approval expiry sweep completed", "expired", expired)
+		}
+		return err
+	})
+	worker.HandleMaintenance(scheduler.TaskLeadConnectorPollSweep, func(ctx context.Context) error {
+		result, err := webhookModule.Service().PollDueConnectors(ctx, time.Now())
+		if result.Received > 0 {
+			log.Info("lead connector poll sweep completed", "received", result.Received, "created", result.Created,
+				"duplicates", result.Duplicates, "failed", result.Failed)
+		}
+		return err
+	})
+	worker.HandleMaintenance(scheduler.TaskCatalogPriceFeedSweep, func(ctx context.Context) error {
+		result, err := catalogModule.Service().RunDuePriceFeeds(ctx, time.Now())
+		if result.Feeds > 0 {
+			log.Info("catalog price feed sweep completed", "feeds", result.Feeds, "failed", result.Failed,
+				"updated", result.Updated, "alerts", result.Alerts)
+		}
+		return err
+	})
+	worker.HandleTenantMaintenance(scheduler.TaskAnalyticsRefresh, func(ctx context.Context) error {
+		return analyticsModule.Service().Refresh(ctx, time.Now())
+	})
+	worker.HandleMaintenance(scheduler.TaskRetentionApply, func(ctx context.Context) error {
+		result, err := retentionModule.Service().ApplyAll(ctx, time.Now())
+		if result.RejectedLeads+result.TimelineEvents+result.DraftQuotes > 0 {
+			log.Info("retention run completed", "organizations", result.Organizations, "rejectedLeads", result.RejectedLeads,
+				"timelineEvents", result.TimelineEvents, "draftQuotes", result.DraftQuotes, "failed", result.Failed)
+		}
+		return err
+	})
+
+	worker.HandleMaintenance(scheduler.TaskAuditExportRun, func(ctx context.Context) error {
+		result, err := auditExportModule.Service().RunDue(ctx, time.Now())
+		if result.Snapshots+result.Purged+result.Failed > 0 {
+			log.Info("audit export run completed", "organizations", result.Organizations, "snapshots", result.Snapshots,
+				"records", result.Records, "purged", result.Purged, "failed", result.Failed)
+		}
+		return err
+	})
+
+	// Maintenance jobs started on demand from the job dashboard.
+	maintenanceRuns := jobsservice.NewRunner(jobsrepo.New(pool), log)
+	registerMaintenanceRunJobs(maintenanceRuns, cfg, pool, catalogModule.Service(), reindexDelay, log)
+	worker.SetMaintenanceRunProcessor(maintenanceRuns)
+
+	periodic, err := scheduler.NewPeriodicScheduler(cfg, log)
+	if err != nil {
+		log.Error("failed to initialize periodic scheduler", "error", err)
+		panic("failed to initialize periodic scheduler: " + err.Error())
+	}
+	for _, entry := range []struct {
+		taskType string
+		interval time.Duration
+	}{
+		{scheduler.TaskAIQuoteJobCleanup, cleanupInterval},
+		{scheduler.TaskAIQuoteJobRecovery, recoveryInterval},
+		{scheduler.TaskCatalogGapSweep, gapInterval},
+		{scheduler.TaskFileOrphanCleanup, fileCleanupInterval},
+		{scheduler.TaskUploadExpirySweep, uploadExpirySweepInterval},
+		{scheduler.TaskAttachmentRenditionSweep, renditionSweepInterval},
+		{scheduler.TaskEnergyLabelRefresh, energyLabelRefreshInterval},
+		{scheduler.TaskQuoteInstallmentDueSweep, installmentSweepInterval},
+		{scheduler.TaskWarrantyExpirySweep, warrantyExpirySweepInterval},
+		{scheduler.TaskPortalDomainMaintenance, portalDomainInterval},
+		{scheduler.TaskQuoteAutoSendSweep, autoSendSweepInterval},
+		{scheduler.TaskAgentApprovalExpirySweep, approvalExpirySweepInterval},
+		{scheduler.TaskLeadConnectorPollSweep, connectorPollSweepInterval},
+		{scheduler.TaskCatalogPriceFeedSweep, priceFeedSweepInterval},
+		{scheduler.TaskAnalyticsRefresh, analyticsRefreshInterval},
+		{scheduler.TaskRetentionApply, retentionInterval},
+		{scheduler.TaskAuditExportRun, auditExportInterval},
+	} {
+		if err := periodic.Register(entry.taskType, entry.interval); err != nil {
+			log.Error("failed to register periodic task", "type", entry.taskType, "error", err)
+			panic("failed to register periodic task: " + err.Error())
+		}
+	}
+	go periodic.Run(ctx)
+
 	go runStaleLeadSweepLoop(ctx, pool, staleDetector, reminderScheduler, reminderScheduler, staleLeadSweepInterval, log)
 
 	worker.Run(ctx)
@@ -402,65 +743,247 @@ func initReminderSchedulerWithCloser(cfg *config.Config, log *logger.Logger) (*s
 	}
 }
 
-type gapOrgSettings struct {
-	OrganizationID uuid.UUID
-	Threshold      int
-	LookbackDays   int
+func runKvKRevalidationLoop(ctx context.Context, svc *kvkservice.Service, interval, maxAge time.Duration, batchSize int, log *logger.Logger) {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(30 * time.Second):
+	}
+
+	runKvKRevalidationOnce(ctx, svc, maxAge, batchSize, log)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runKvKRevalidationOnce(ctx, svc, maxAge, batchSize, log)
+		}
+	}
 }
 
-func runCatalogGapAnalyzerLoop(ctx context.Context, pool *pgxpool.Pool, analyzer *maintenance.CatalogGapAnalyzer, interval time.Duration, maxDrafts int, log *logger.Logger) {
-	if interval <= 0 {
-		interval = 6 * time.Hour
+func runKvKRevalidationOnce(ctx context.Context, svc *kvkservice.Service, maxAge time.Duration, batchSize int, log *logger.Logger) {
+	res, err := svc.RevalidateStale(ctx, maxAge, batchSize)
+	if err != nil {
+		log.Warn("kvk revalidation: run failed", "error", err)
+		return
 	}
-	if maxDrafts <= 0 {
-		maxDrafts = 10
+	if res.Checked > 0 || res.Failed > 0 {
+		log.Info("kvk revalidation: run completed", "checked", res.Checked, "dissolved", res.Dissolved, "notFound", res.NotFound, "failed", res.Failed)
+	}
+}
+
+func runFileOrphanCleanupOnce(ctx context.Context, svc *filesservice.Service, grace time.Duration, log *logger.Logger) error {
+	res, err := svc.CleanupOrphans(ctx, grace)
+	if err != nil {
+		log.Warn("file orphan cleanup: run failed", "error", err)
+		return err
+	}
+	if res.StalePending > 0 || res.OrphanObjects > 0 || res.Failed > 0 {
+		log.Info("file orphan cleanup: run completed", "stalePending", res.StalePending, "orphanObjects", res.OrphanObjects, "failed", res.Failed)
+	}
+	return nil
+}
+
+func runStorageUsageReconcileLoop(ctx context.Context, svc *storagequotaservice.Service, targetHour int, log *logger.Logger) {
+	loc, _ := time.LoadLocation("Europe/Amsterdam")
+
+	ticker := time.NewTicker(15 * time.Minute)
+	defer ticker.Stop()
+
+	var lastRunDate string
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().In(loc)
+			todayStr := now.Format("2006-01-02")
+			if now.Hour() == targetHour && lastRunDate != todayStr {
+				lastRunDate = todayStr
+				runStorageUsageReconcileOnce(ctx, svc, log)
+			}
+		}
+	}
+}
+
+func runStorageUsageReconcileOnce(ctx context.Context, svc *storagequotaservice.Service, log *logger.Logger) {
+	res, err := svc.Reconcile(ctx)
+	if err != nil {
+		log.Warn("storage usage reconcile: run failed", "error", err)
+		return
+	}
+	log.Info("storage usage reconcile: run completed", "buckets", res.Buckets, "objects", res.Objects, "unattributed", res.Unattributed, "failed", res.Failed)
+}
+
+func runCatalogReindexLoop(ctx context.Context, svc *catalogservice.Service, interval time.Duration, batchSize int, delay time.Duration, log *logger.Logger) {
+	if interval <= 0 {
+		interval = time.Hour
 	}
 
-	// Run once shortly after startup, then on interval.
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	// Small startup delay to avoid competing with initial DB connection churn.
 	select {
 	case <-ctx.Done():
 		return
-	case <-time.After(15 * time.Second):
+	case <-time.After(45 * time.Second):
 	}
 
-	runCatalogGapAnalyzerOnce(ctx, pool, analyzer, maxDrafts, log)
+	runCatalogReindexOnce(ctx, svc, batchSize, delay, log)
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			runCatalogGapAnalyzerOnce(ctx, pool, analyzer, maxDrafts, log)
+			runCatalogReindexOnce(ctx, svc, batchSize, delay, log)
 		}
 	}
 }
 
-func runCatalogGapAnalyzerOnce(ctx context.Context, pool *pgxpool.Pool, analyzer *maintenance.CatalogGapAnalyzer, maxDrafts int, log *logger.Logger) {
-	orgs, err := listGapEnabledOrganizations(ctx, pool)
+func runCatalogReindexOnce(ctx context.Context, svc *catalogservice.Service, batchSize int, delay time.Duration, log *logger.Logger) {
+	res, err := svc.ReindexStaleEmbeddings(ctx, batchSize, delay)
 	if err != nil {
-		log.Warn("catalog gap: failed to list org settings", "error", err)
+		log.Warn("catalog reindex: run failed", "error", err)
 		return
 	}
-	if len(orgs) == 0 {
-		return
+	if res.Checked > 0 {
+		log.Info("catalog reindex: run completed", "checked", res.Checked, "reindexed", res.Reindexed, "failed", res.Failed)
+	}
+}
+
+type gapOrgSettings struct {
+	OrganizationID uuid.UUID
+	Threshold      int
+	LookbackDays   int
+}
+
+// runCatalogGapSweep enqueues one gap analysis per organization that enabled it.
+func runCatalogGapSweep(ctx context.Context, pool *pgxpool.Pool, enqueuer scheduler.CatalogGapScheduler, maxDrafts int, log *logger.Logger) error {
+	orgs, err := listGapEnabledOrganizations(ctx, pool)
+	if err != nil {
+		log.Warn("catalog gap: failed to list org settings", "error", err)
+		return err
 	}
 
 	for _, o := range orgs {
 		if ctx.Err() != nil {
-			return
+			return ctx.Err()
 		}
-		res, err := analyzer.RunForOrganization(ctx, o.OrganizationID, o.Threshold, o.LookbackDays, maxDrafts)
-		if err != nil {
-			log.Warn("catalog gap: run failed", "orgId", o.OrganizationID, "error", err)
-			continue
+		if err := enqueuer.EnqueueCatalogGapAnalysis(ctx, scheduler.CatalogGapAnalyzePayload{
+			OrganizationID: o.OrganizationID.String(),
+			Threshold:      o.Threshold,
+			LookbackDays:   o.LookbackDays,
+			MaxDrafts:      maxDrafts,
+		}); err != nil {
+			log.Warn("catalog gap: failed to enqueue analysis", "orgId", o.OrganizationID, "error", err)
+		}
+	}
+	return nil
+}
+
+// registerMaintenanceRunJobs sets how the maintenance jobs of the job dashboard
+// run. The lead backfills share their saved progress with cmd/backfill.
+func registerMaintenanceRunJobs(runner *jobsservice.Runner, cfg *config.Config, pool *pgxpool.Pool, catalogSvc *catalogservice.Service, reindexDelay time.Duration, log *logger.Logger) {
+	progress := backfill.NewRepository(pool)
+
+	runner.Register(jobsservice.JobLeadGeocode, func(ctx context.Context, params jobsservice.RunParams, report func(jobsrepo.RunCounts)) error {
+		mapsService := maps.NewService(log)
+		mapsService.SetGeocoding(maps.NewGeocodeProviders(cfg), maps.NewRepository(pool))
+		job := leadbackfill.NewGeocodeJob(pool, mapsService, log)
+		_, err := job.Run(ctx, progress, backfillOptions(leadbackfill.GeocodeOptions, params, report))
+		return err
+	})
+	runner.Register(jobsservice.JobLeadEnrichment, func(ctx context.Context, params jobsservice.RunParams, report func(jobsrepo.RunCounts)) error {
+		enricher := adapters.NewLeadEnrichmentAdapter(leadenrichment.NewModule(pool, log).Service())
+		job := leadbackfill.NewEnrichmentJob(pool, enricher, log)
+		_, err := job.Run(ctx, progress, backfillOptions(leadbackfill.EnrichmentOptions, params, report))
+		return err
+	})
+	runner.Register(jobsservice.JobCatalogReembed, func(ctx context.Context, params jobsservice.RunParams, report func(jobsrepo.RunCounts)) error {
+		batchSize := params.BatchSize
+		if batchSize <= 0 {
+			batchSize = 100
 		}
-		if res.CreatedDrafts > 0 || res.Candidates > 0 {
-			log.Info("catalog gap: run completed", "orgId", o.OrganizationID, "candidates", res.Candidates, "createdDrafts", res.CreatedDrafts, "skippedExists", res.SkippedExists)
+		var counts jobsrepo.RunCounts
+		for {
+			res, err := catalogSvc.ReindexStaleEmbeddings(ctx, batchSize, reindexDelay)
+			counts.Processed += res.Checked
+			counts.Updated += res.Reindexed
+			counts.Failed += res.Failed
+			report(counts)
+			// Products that keep failing stay stale, so stop once a batch
+			// makes no progress.
+			if err != nil || res.Checked < batchSize || res.Reindexed == 0 {
+				return err
+			}
+		}
+	})
+	outboxRepo := outbox.New(pool)
+	runner.Register(jobsservice.JobOutboxRequeue, func(ctx context.Context, params jobsservice.RunParams, report func(jobsrepo.RunCounts)) error {
+		batchSize := params.BatchSize
+		if batchSize <= 0 {
+			batchSize = 100
+		}
+		var counts jobsrepo.RunCounts
+		for {
+			requeued, err := outboxRepo.RequeueFailed(ctx, *params.FailedSince, params.Kind, batchSize)
+			if err != nil {
+				return err
+			}
+			counts.Processed += requeued
+			counts.Updated += requeued
+			report(counts)
+			if requeued < batchSize {
+				return nil
+			}
 		}
+	})
+}
+
+// backfillOptions applies the parameters of a maintenance run to the default
+// options of a backfill and reports its progress on the run.
+func backfillOptions(defaults backfill.Options, params jobsservice.RunParams, report func(jobsrepo.RunCounts)) backfill.Options {
+	opts := defaults
+	if params.BatchSize > 0 {
+		opts.BatchSize = params.BatchSize
 	}
+	opts.DryRun = params.DryRun
+	opts.Restart = params.Restart
+	opts.Progress = func(stats backfill.Stats) {
+		report(jobsrepo.RunCounts{Processed: stats.Processed, Updated: stats.Updated, Skipped: stats.Skipped, Failed: stats.Failed})
+	}
+	return opts
+}
+
+// catalogGapProcessor runs the per-organization gap analysis tasks.
+type catalogGapProcessor struct {
+	analyzer *maintenance.CatalogGapAnalyzer
+	log      *logger.Logger
+}
+
+func (p catalogGapProcessor) ProcessCatalogGapAnalysis(ctx context.Context, payload scheduler.CatalogGapAnalyzePayload) error {
+	orgID, err := uuid.Parse(payload.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.analyzer.RunForOrganization(ctx, orgID, payload.Threshold, payload.LookbackDays, payload.MaxDrafts)
+	if err != nil {
+		p.log.Warn("catalog gap: run failed", "orgId", orgID, "error", err)
+		return err
+	}
+	if res.CreatedDrafts > 0 || res.Candidates > 0 {
+		p.log.Info("catalog gap: run completed", "orgId", orgID, "candidates", res.Candidates, "createdDrafts", res.CreatedDrafts, "skippedExists", res.SkippedExists)
+	}
+	return nil
 }
 
 func listGapEnabledOrganizations(ctx context.Context, pool *pgxpool.Pool) ([]gapOrgSettings, error) {
@@ -564,25 +1087,77 @@ func wireSchedulerIMAPEncryptionKey(cfg *config.Config, log *logger.Logger, imap
 	log.Info("scheduler imap encryption key configured")
 }
 
-func wireSchedulerSMTPEncryptionKey(cfg *config.Config, log *logger.Logger, identitySvc interface{ SetSMTPEncryptionKey([]byte) }, notificationMod interface{ SetSMTPEncryptionKey([]byte) }) {
-	keyHex := cfg.GetSMTPEncryptionKey()
-	if strings.TrimSpace(keyHex) == "" {
-		return
+func initSchedulerSecretsOrPanic(cfg *config.Config, log *logger.Logger) *secrets.Service {
+	secretsSvc, err := secrets.NewFromConfig(cfg)
+	if err != nil {
+		log.Error("failed to initialize secrets backend", "error", err)
+		panic("failed to initialize secrets backend: " + err.Error())
 	}
+	log.Info("scheduler secrets backend initialized", "backend", secretsSvc.BackendName())
+	return secretsSvc
+}
 
-	key, err := hex.DecodeString(keyHex)
+// loadSchedulerKeyringOrPanic resolves an encryption keyring by name. It returns nil when the secret is not configured.
+func loadSchedulerKeyringOrPanic(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, name string) *secrets.Keyring {
+	keyring, err := secretsSvc.Keyring(ctx, name)
 	if err != nil {
-		log.Error("invalid SMTP_ENCRYPTION_KEY (must be hex-encoded)", "error", err)
-		panic("invalid SMTP_ENCRYPTION_KEY: " + err.Error())
+		log.Error("invalid encryption keyring", "name", name, "error", err)
+		panic("invalid " + name + ": " + err.Error())
 	}
-	if len(key) != 32 {
-		log.Error("SMTP_ENCRYPTION_KEY must be 32 bytes (64 hex chars)", "length", len(key))
-		panic("SMTP_ENCRYPTION_KEY must be 32 bytes")
+	return keyring
+}
+
+func wireSchedulerSMTPEncryptionKey(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, identitySvc interface{ SetSMTPKeyring(*secrets.Keyring) }, notificationMod interface{ SetSMTPKeyring(*secrets.Keyring) }) *secrets.Keyring {
+	keyring := loadSchedulerKeyringOrPanic(ctx, secretsSvc, log, "SMTP_ENCRYPTION_KEY")
+	if keyring == nil {
+		return nil
+	}
+
+	identitySvc.SetSMTPKeyring(keyring)
+	notificationMod.SetSMTPKeyring(keyring)
+	log.Info("scheduler smtp encryption key configured", "backend", secretsSvc.BackendName(), "primaryKeyId", keyring.PrimaryID())
+	return keyring
+}
+
+func wireSchedulerOutboxEncryptionKey(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger) {
+	keyring := loadSchedulerKeyringOrPanic(ctx, secretsSvc, log, "OUTBOX_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
+	}
+
+	outbox.SetKeyring(keyring)
+	log.Info("scheduler outbox encryption key configured", "backend", secretsSvc.BackendName(), "primaryKeyId", keyring.PrimaryID())
+}
+
+func wireSchedulerLeadContactEncryption(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger) *leadrepo.ContactCipher {
+	keyring := loadSchedulerKeyringOrPanic(ctx, secretsSvc, log, "LEADS_CONTACT_ENCRYPTION_KEY")
+	indexKeyring := loadSchedulerKeyringOrPanic(ctx, secretsSvc, log, "LEADS_CONTACT_INDEX_KEY")
+	if keyring == nil || indexKeyring == nil {
+		return nil
+	}
+
+	log.Info("scheduler lead contact encryption configured", "primaryKeyId", keyring.PrimaryID())
+	return leadrepo.NewContactCipher(keyring, indexKeyring.PrimaryKey())
+}
+
+func wireSchedulerCatalogPriceFeedKeyring(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, catalogMod interface{ SetPriceFeedKeyring(*secrets.Keyring) }) {
+	keyring := loadSchedulerKeyringOrPanic(ctx, secretsSvc, log, "CATALOG_PRICE_FEED_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
 	}
 
-	identitySvc.SetSMTPEncryptionKey(key)
-	notificationMod.SetSMTPEncryptionKey(key)
-	log.Info("scheduler smtp encryption key configured")
+	catalogMod.SetPriceFeedKeyring(keyring)
+	log.Info("scheduler catalog price feed encryption key configured", "primaryKeyId", keyring.PrimaryID())
+}
+
+func wireSchedulerLeadConnectorKeyring(ctx context.Context, secretsSvc *secrets.Service, log *logger.Logger, webhookMod interface{ SetConnectorKeyring(*secrets.Keyring) }) {
+	keyring := loadSchedulerKeyringOrPanic(ctx, secretsSvc, log, "LEAD_CONNECTOR_ENCRYPTION_KEY")
+	if keyring == nil {
+		return
+	}
+
+	webhookMod.SetConnectorKeyring(keyring)
+	log.Info("scheduler lead connector encryption key configured", "primaryKeyId", keyring.PrimaryID())
 }
 
 type digestOrg struct {
@@ -712,7 +1287,12 @@ func runStaleLeadSweepOnce(
 		if ctx.Err() != nil {
 			return
 		}
-		items, err := detector.ListStaleLeadServices(ctx, orgID, 100)
+		orgCtx, err := httpkit.BindTenant(ctx, orgID)
+		if err != nil {
+			log.Warn("stale lead sweep: failed to bind organization", "orgId", orgID, "error", err)
+			continue
+		}
+		items, err := detector.ListStaleLeadServices(orgCtx, orgID, 100)
 		if err != nil {
 			log.Warn("stale lead sweep: detector failed", "orgId", orgID, "error", err)
 			continue
@@ -787,6 +1367,11 @@ func sendDigestForOrg(
 	if !org.DigestEnabled {
 		return
 	}
+	ctx, err := httpkit.BindTenant(ctx, org.OrganizationID)
+	if err != nil {
+		log.Warn("daily digest: failed to bind organization", "orgId", org.OrganizationID, "error", err)
+		return
+	}
 
 	digest, err := digestService.GenerateDigest(ctx, org.OrganizationID, org.Name, dashboardURL)
 	if err != nil {
@@ -0,0 +1,59 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	identityrepo "portal_final_backend/internal/identity/repository"
+	identitysvc "portal_final_backend/internal/identity/service"
+	"portal_final_backend/platform/ai/llmrouter"
+
+	"github.com/google/uuid"
+)
+
+// AIUsageLedgerAdapter backs the LLM router with the identity AI settings and usage ledger.
+type AIUsageLedgerAdapter struct {
+	svc *identitysvc.Service
+}
+
+func NewAIUsageLedgerAdapter(svc *identitysvc.Service) *AIUsageLedgerAdapter {
+	return &AIUsageLedgerAdapter{svc: svc}
+}
+
+// Settings satisfies llmrouter.SettingsReader.
+func (a *AIUsageLedgerAdapter) Settings(ctx context.Context, organizationID uuid.UUID) (llmrouter.Settings, error) {
+	settings, err := a.svc.GetOrganizationAISettings(ctx, organizationID)
+	if err != nil {
+		return llmrouter.Settings{}, err
+	}
+	result := llmrouter.Settings{
+		Temperature:        settings.Temperature,
+		MonthlyTokenBudget: settings.MonthlyTokenBudget,
+	}
+	if settings.Provider != nil {
+		result.Provider = *settings.Provider
+	}
+	if settings.Model != nil {
+		result.Model = *settings.Model
+	}
+	return result, nil
+}
+
+func (a *AIUsageLedgerAdapter) TokenUsageSince(ctx context.Context, organizationID uuid.UUID, since time.Time) (int64, error) {
+	return a.svc.AITokensSince(ctx, organizationID, since)
+}
+
+func (a *AIUsageLedgerAdapter) RecordUsage(ctx context.Context, usage llmrouter.Usage) error {
+	return a.svc.RecordAIUsage(ctx, identityrepo.AIUsageRecord{
+		OrganizationID:   usage.OrganizationID,
+		Kind:             usage.Kind,
+		Source:           usage.Source,
+		Provider:         usage.Provider,
+		Model:            usage.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		EstimatedCostUSD: usage.EstimatedCostUSD,
+	})
+}
+
+var _ llmrouter.UsageStore = (*AIUsageLedgerAdapter)(nil)
@@ -0,0 +1,133 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/pdf"
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/service"
+
+	"github.com/google/uuid"
+)
+
+// CreditNoteDataReader is the narrow repo interface used to render credit notes.
+type CreditNoteDataReader interface {
+	GetByID(ctx context.Context, id uuid.UUID, orgID uuid.UUID) (*repository.Quote, error)
+	GetCreditNote(ctx context.Context, id, quoteID, orgID uuid.UUID) (*repository.CreditNote, error)
+	GetCreditNoteItems(ctx context.Context, creditNoteID, orgID uuid.UUID) ([]repository.CreditNoteItem, error)
+	SetCreditNotePDFFileKey(ctx context.Context, id, orgID uuid.UUID, fileKey string) error
+}
+
+// CreditNotePDFProcessor generates credit note PDFs, uploads them to MinIO,
+// and persists the file key on the credit note record.
+type CreditNotePDFProcessor struct {
+	repo          CreditNoteDataReader
+	orgReader     QuoteOrgReader
+	contactReader service.QuoteContactReader
+	storage       storage.StorageService
+	cfg           QuotePDFBucketConfig
+}
+
+// NewCreditNotePDFProcessor creates a new processor adapter.
+func NewCreditNotePDFProcessor(repo CreditNoteDataReader, orgReader QuoteOrgReader, contactReader service.QuoteContactReader, storageSvc storage.StorageService, cfg QuotePDFBucketConfig) *CreditNotePDFProcessor {
+	return &CreditNotePDFProcessor{
+		repo:          repo,
+		orgReader:     orgReader,
+		contactReader: contactReader,
+		storage:       storageSvc,
+		cfg:           cfg,
+	}
+}
+
+// GenerateCreditNotePDF builds the credit note PDF, uploads it to storage,
+// and persists the file key on the credit note.
+func (p *CreditNotePDFProcessor) GenerateCreditNotePDF(ctx context.Context, quoteID, creditNoteID, organizationID uuid.UUID) (string, []byte, error) {
+	quote, err := p.repo.GetByID(ctx, quoteID, organizationID)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch quote for credit note PDF: %w", err)
+	}
+	note, err := p.repo.GetCreditNote(ctx, creditNoteID, quoteID, organizationID)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch credit note for PDF: %w", err)
+	}
+	items, err := p.repo.GetCreditNoteItems(ctx, creditNoteID, organizationID)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch credit note items for PDF: %w", err)
+	}
+
+	data := pdf.CreditNotePDFData{
+		CreditNoteNumber: note.CreditNoteNumber,
+		QuoteNumber:      quote.QuoteNumber,
+		Reason:           note.Reason,
+		CreatedAt:        note.CreatedAt,
+		CustomerName:     strings.TrimSpace(derefStr(quote.CustomerFirstName) + " " + derefStr(quote.CustomerLastName)),
+		SubtotalCents:    note.SubtotalCents,
+		TaxTotalCents:    note.TaxTotalCents,
+		TotalCents:       note.TotalCents,
+		Items:            make([]pdf.CreditNoteLinePDF, len(items)),
+	}
+	for i, it := range items {
+		data.Items[i] = pdf.CreditNoteLinePDF{
+			Description:    it.Description,
+			Quantity:       it.Quantity,
+			UnitPriceCents: it.UnitPriceCents,
+			TaxRateBps:     it.TaxRateBps,
+			LineTotalCents: it.LineTotalCents,
+		}
+	}
+
+	p.applyCustomer(ctx, &data, quote, organizationID)
+
+	org, orgErr := p.orgReader.GetOrganization(ctx, organizationID)
+	if orgErr == nil {
+		data.OrganizationName = org.Name
+		data.OrgEmail = derefStr(org.Email)
+		data.OrgPhone = derefStr(org.Phone)
+		data.OrgVatNumber = derefStr(org.VatNumber)
+		data.OrgKvkNumber = derefStr(org.KvkNumber)
+		data.OrgAddressLine1 = derefStr(org.AddressLine1)
+		data.OrgPostalCode = derefStr(org.PostalCode)
+		data.OrgCity = derefStr(org.City)
+	}
+	data.OrgLogo = downloadOrganizationLogo(ctx, p.storage, p.cfg.GetMinioBucketOrganizationLogos(), org, orgErr, organizationID)
+
+	pdfBytes, err := pdf.GenerateCreditNotePDF(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("generate credit note PDF: %w", err)
+	}
+
+	fileKey, err := p.storage.UploadFile(ctx, p.cfg.GetMinioBucketQuotePDFs(), organizationID.String(),
+		fmt.Sprintf("%s.pdf", note.CreditNoteNumber), "application/pdf", bytes.NewReader(pdfBytes), int64(len(pdfBytes)))
+	if err != nil {
+		return "", nil, fmt.Errorf("upload credit note PDF to storage: %w", err)
+	}
+	if err := p.repo.SetCreditNotePDFFileKey(ctx, creditNoteID, organizationID, fileKey); err != nil {
+		return "", nil, fmt.Errorf("persist credit note PDF file key: %w", err)
+	}
+
+	return fileKey, pdfBytes, nil
+}
+
+// applyCustomer fills the customer block the same way the quote PDF does, so both
+// documents address the customer identically.
+func (p *CreditNotePDFProcessor) applyCustomer(ctx context.Context, data *pdf.CreditNotePDFData, quote *repository.Quote, organizationID uuid.UUID) {
+	var contactData *service.QuoteContactData
+	if p.contactReader != nil {
+		if resolved, err := p.contactReader.GetQuoteContactData(ctx, quote.LeadID, organizationID); err == nil {
+			contactData = &resolved
+			if resolved.ConsumerName != "" {
+				data.CustomerName = resolved.ConsumerName
+			}
+		}
+	}
+
+	var customer pdf.QuotePDFData
+	applyContactData(&customer, contactData, quote)
+	data.CustomerAddressLine1 = customer.CustomerAddressLine1
+	data.CustomerPostalCode = customer.CustomerPostalCode
+	data.CustomerCity = customer.CustomerCity
+}
@@ -51,6 +51,7 @@ func (a *LeadEnrichmentAdapter) EnrichLead(ctx context.Context, postcode string)
 		HuishoudensMetKinderenPct: data.HuishoudensMetKinderenPct,
 		Stedelijkheid:             data.Stedelijkheid,
 		Confidence:                data.Confidence,
+		FetchedAt:                 data.FetchedAt,
 	}, nil
 }
 
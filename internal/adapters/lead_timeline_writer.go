@@ -30,6 +30,7 @@ func (a *LeadTimelineWriter) CreateTimelineEvent(ctx context.Context, params not
 		Summary:        params.Summary,
 		Metadata:       params.Metadata,
 		Visibility:     params.Visibility,
+		DedupKey:       params.DedupKey,
 	})
 	return err
 }
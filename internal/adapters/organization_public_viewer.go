@@ -10,7 +10,8 @@ import (
 	"github.com/google/uuid"
 )
 
-// OrganizationPublicAdapter exposes organization contact info for the public lead portal.
+// OrganizationPublicAdapter exposes organization contact info and branding for the
+// public lead portal.
 type OrganizationPublicAdapter struct {
 	svc *identitysvc.Service
 }
@@ -30,6 +31,14 @@ func (a *OrganizationPublicAdapter) GetPublicPhone(ctx context.Context, organiza
 	return normalizeWhatsAppNL(*org.Phone), nil
 }
 
+func (a *OrganizationPublicAdapter) GetPublicBranding(ctx context.Context, organizationID uuid.UUID) (ports.PublicBranding, error) {
+	branding, err := a.svc.GetOrganizationBranding(ctx, organizationID)
+	if err != nil {
+		return ports.PublicBranding{}, err
+	}
+	return ports.PublicBranding{PrimaryColor: branding.PrimaryColor, AccentColor: branding.AccentColor}, nil
+}
+
 func normalizeWhatsAppNL(phone string) string {
 	trimmed := strings.TrimSpace(phone)
 	if trimmed == "" {
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"log/slog"
@@ -13,9 +12,9 @@ import (
 	"portal_final_backend/internal/adapters/storage"
 	"portal_final_backend/internal/email"
 	identityrepo "portal_final_backend/internal/identity/repository"
-	"portal_final_backend/internal/identity/smtpcrypto"
 	partnersrepo "portal_final_backend/internal/partners/repository"
 	"portal_final_backend/internal/pdf"
+	"portal_final_backend/platform/secrets"
 
 	"github.com/google/uuid"
 )
@@ -34,7 +33,6 @@ type PartnerOfferPDFBucketConfig interface {
 	GetMinioBucketQuotePDFs() string
 	GetMinioBucketOrganizationLogos() string
 	GetMinioBucketLeadServiceAttachments() string
-	GetSMTPEncryptionKey() string
 }
 
 // OfferPDFOrgReader is the narrow interface for fetching organization data.
@@ -51,6 +49,7 @@ type PartnerOfferPDFProcessor struct {
 	storage   storage.StorageService
 	cfg       PartnerOfferPDFBucketConfig
 	sender    email.Sender
+	smtpKeys  *secrets.Keyring
 }
 
 // NewPartnerOfferPDFProcessor creates a new processor.
@@ -70,6 +69,11 @@ func NewPartnerOfferPDFProcessor(
 	}
 }
 
+// SetSMTPKeyring sets the keyring used to decrypt organization SMTP passwords.
+func (p *PartnerOfferPDFProcessor) SetSMTPKeyring(keyring *secrets.Keyring) {
+	p.smtpKeys = keyring
+}
+
 // GenerateAndStoreOfferPDF fetches the accepted offer, generates a PDF, uploads it
 // to MinIO, and persists the file key on the offer record.
 func (p *PartnerOfferPDFProcessor) GenerateAndStoreOfferPDF(ctx context.Context, offerID, tenantID uuid.UUID) (string, error) {
@@ -251,17 +255,11 @@ func (p *PartnerOfferPDFProcessor) resolveSender(ctx context.Context, organizati
 
 	password := ""
 	if settings.SMTPPassword != nil && strings.TrimSpace(*settings.SMTPPassword) != "" {
-		keyHex := strings.TrimSpace(p.cfg.GetSMTPEncryptionKey())
-		if keyHex == "" {
+		if p.smtpKeys == nil {
 			slog.Warn("smtp password configured but SMTP_ENCRYPTION_KEY is missing for partner offer pdf email", "organizationId", organizationID)
 			return p.sender
 		}
-		key, err := hex.DecodeString(keyHex)
-		if err != nil {
-			slog.Warn("invalid SMTP_ENCRYPTION_KEY for partner offer pdf email", "organizationId", organizationID, "error", err)
-			return p.sender
-		}
-		decrypted, err := smtpcrypto.Decrypt(*settings.SMTPPassword, key)
+		decrypted, err := p.smtpKeys.Decrypt(*settings.SMTPPassword)
 		if err != nil {
 			slog.Warn("failed to decrypt smtp password for partner offer pdf email", "organizationId", organizationID, "error", err)
 			return p.sender
@@ -30,6 +30,7 @@ func (a *PartnerOffersTimelineWriter) WriteOfferEvent(ctx context.Context, p not
 		Summary:        p.Summary,
 		Metadata:       p.Metadata,
 		Visibility:     p.Visibility,
+		DedupKey:       p.DedupKey,
 	})
 	return err
 }
@@ -0,0 +1,46 @@
+package adapters
+
+import (
+	"context"
+
+	payoutsrepo "portal_final_backend/internal/payouts/repository"
+	payoutsvc "portal_final_backend/internal/payouts/service"
+	quotesvc "portal_final_backend/internal/quotes/service"
+
+	"github.com/google/uuid"
+)
+
+// PayoutInvoiceExporter is the narrow quotes service interface that books
+// payouts in the accounting integration.
+type PayoutInvoiceExporter interface {
+	ExportPayoutInvoice(ctx context.Context, tenantID uuid.UUID, provider string, invoice quotesvc.PayoutInvoice) (string, string, error)
+}
+
+// PayoutAccountingExporter books payout statements through the accounting
+// integration of the quotes module.
+type PayoutAccountingExporter struct {
+	exporter PayoutInvoiceExporter
+}
+
+// NewPayoutAccountingExporter creates a new exporter adapter.
+func NewPayoutAccountingExporter(exporter PayoutInvoiceExporter) *PayoutAccountingExporter {
+	return &PayoutAccountingExporter{exporter: exporter}
+}
+
+// ExportPayoutStatement books a statement as a purchase invoice of the partner.
+func (a *PayoutAccountingExporter) ExportPayoutStatement(ctx context.Context, organizationID uuid.UUID, provider string, statement payoutsrepo.Statement, lines []payoutsvc.ExportLine) (string, string, error) {
+	invoice := quotesvc.PayoutInvoice{
+		Reference:    statement.StatementNumber,
+		Date:         statement.PeriodEnd,
+		PartnerID:    statement.PartnerID,
+		PartnerName:  statement.PartnerName,
+		PartnerEmail: statement.PartnerEmail,
+		Lines:        make([]quotesvc.PayoutInvoiceLine, len(lines)),
+	}
+	for i, line := range lines {
+		invoice.Lines[i] = quotesvc.PayoutInvoiceLine{Description: line.Description, AmountCents: line.AmountCents}
+	}
+	return a.exporter.ExportPayoutInvoice(ctx, organizationID, provider, invoice)
+}
+
+var _ payoutsvc.AccountingExporter = (*PayoutAccountingExporter)(nil)
@@ -0,0 +1,125 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"portal_final_backend/internal/adapters/storage"
+	payoutsrepo "portal_final_backend/internal/payouts/repository"
+	"portal_final_backend/internal/pdf"
+
+	"github.com/google/uuid"
+)
+
+// PayoutStatementDataReader is the narrow repo interface used to render payout statements.
+type PayoutStatementDataReader interface {
+	GetStatement(ctx context.Context, id, organizationID uuid.UUID) (payoutsrepo.Statement, error)
+	ListCommissions(ctx context.Context, p payoutsrepo.CommissionListParams) ([]payoutsrepo.Commission, int, error)
+	ListAdjustments(ctx context.Context, organizationID uuid.UUID, partnerID, statementID *uuid.UUID, openOnly bool) ([]payoutsrepo.Adjustment, error)
+	SetStatementPDFFileKey(ctx context.Context, id, organizationID uuid.UUID, fileKey string) error
+}
+
+// PayoutStatementPDFProcessor generates payout statement PDFs, uploads them
+// to MinIO, and persists the file key on the statement.
+type PayoutStatementPDFProcessor struct {
+	repo      PayoutStatementDataReader
+	orgReader QuoteOrgReader
+	storage   storage.StorageService
+	cfg       QuotePDFBucketConfig
+}
+
+// NewPayoutStatementPDFProcessor creates a new processor adapter.
+func NewPayoutStatementPDFProcessor(repo PayoutStatementDataReader, orgReader QuoteOrgReader, storageSvc storage.StorageService, cfg QuotePDFBucketConfig) *PayoutStatementPDFProcessor {
+	return &PayoutStatementPDFProcessor{
+		repo:      repo,
+		orgReader: orgReader,
+		storage:   storageSvc,
+		cfg:       cfg,
+	}
+}
+
+// GeneratePayoutStatementPDF builds the statement PDF, uploads it to storage,
+// and persists the file key on the statement.
+func (p *PayoutStatementPDFProcessor) GeneratePayoutStatementPDF(ctx context.Context, statementID, organizationID uuid.UUID) (string, []byte, error) {
+	statement, err := p.repo.GetStatement(ctx, statementID, organizationID)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch payout statement for PDF: %w", err)
+	}
+	commissions, _, err := p.repo.ListCommissions(ctx, payoutsrepo.CommissionListParams{OrganizationID: organizationID, StatementID: &statementID})
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch commissions for payout statement PDF: %w", err)
+	}
+	adjustments, err := p.repo.ListAdjustments(ctx, organizationID, nil, &statementID, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch adjustments for payout statement PDF: %w", err)
+	}
+
+	data := pdf.PayoutStatementPDFData{
+		StatementNumber:     statement.StatementNumber,
+		PeriodStart:         statement.PeriodStart,
+		PeriodEnd:           statement.PeriodEnd,
+		CreatedAt:           statement.CreatedAt,
+		PartnerName:         statement.PartnerName,
+		PartnerKvkNumber:    statement.PartnerKVK,
+		PartnerVatNumber:    statement.PartnerVAT,
+		PartnerAddressLine1: statement.PartnerAddress,
+		PartnerPostalCode:   statement.PartnerZipCode,
+		PartnerCity:         statement.PartnerCity,
+		Jobs:                make([]pdf.PayoutJobLinePDF, len(commissions)),
+		Adjustments:         make([]pdf.PayoutAdjustmentLinePDF, len(adjustments)),
+		EarningsCents:       statement.EarningsCents,
+		LeadFeesCents:       statement.LeadFeesCents,
+		AdjustmentsCents:    statement.AdjustmentsCents,
+		PayoutCents:         statement.PayoutCents,
+	}
+	for i, c := range commissions {
+		description := "Opdracht"
+		if c.ServiceType != nil && *c.ServiceType != "" {
+			description = *c.ServiceType
+		}
+		data.Jobs[i] = pdf.PayoutJobLinePDF{
+			Description:      description,
+			CompletedAt:      c.EarnedAt,
+			VakmanPriceCents: c.VakmanPriceCents,
+			LeadFeeCents:     c.LeadFeeCents,
+			PayoutCents:      c.PayoutCents,
+		}
+	}
+	for i, a := range adjustments {
+		data.Adjustments[i] = pdf.PayoutAdjustmentLinePDF{
+			Description:   a.Description,
+			EffectiveDate: a.EffectiveDate,
+			AmountCents:   a.AmountCents,
+		}
+	}
+
+	org, orgErr := p.orgReader.GetOrganization(ctx, organizationID)
+	if orgErr == nil {
+		data.OrganizationName = org.Name
+		data.OrgEmail = derefStr(org.Email)
+		data.OrgPhone = derefStr(org.Phone)
+		data.OrgVatNumber = derefStr(org.VatNumber)
+		data.OrgKvkNumber = derefStr(org.KvkNumber)
+		data.OrgAddressLine1 = derefStr(org.AddressLine1)
+		data.OrgPostalCode = derefStr(org.PostalCode)
+		data.OrgCity = derefStr(org.City)
+	}
+	data.OrgLogo = downloadOrganizationLogo(ctx, p.storage, p.cfg.GetMinioBucketOrganizationLogos(), org, orgErr, organizationID)
+
+	pdfBytes, err := pdf.GeneratePayoutStatementPDF(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("generate payout statement PDF: %w", err)
+	}
+
+	fileKey, err := p.storage.UploadFile(ctx, p.cfg.GetMinioBucketQuotePDFs(), organizationID.String(),
+		fmt.Sprintf("%s.pdf", statement.StatementNumber), "application/pdf", bytes.NewReader(pdfBytes), int64(len(pdfBytes)))
+	if err != nil {
+		return "", nil, fmt.Errorf("upload payout statement PDF to storage: %w", err)
+	}
+	if err := p.repo.SetStatementPDFFileKey(ctx, statementID, organizationID, fileKey); err != nil {
+		return "", nil, fmt.Errorf("persist payout statement PDF file key: %w", err)
+	}
+
+	return fileKey, pdfBytes, nil
+}
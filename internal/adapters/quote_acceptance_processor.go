@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"portal_final_backend/internal/adapters/storage"
 	identityrepo "portal_final_backend/internal/identity/repository"
@@ -27,6 +28,7 @@ type QuoteDataReader interface {
 	GetItemsByQuoteID(ctx context.Context, quoteID uuid.UUID, orgID uuid.UUID) ([]repository.QuoteItem, error)
 	GetAttachmentsByQuoteID(ctx context.Context, quoteID uuid.UUID, orgID uuid.UUID) ([]repository.QuoteAttachment, error)
 	GetURLsByQuoteID(ctx context.Context, quoteID uuid.UUID, orgID uuid.UUID) ([]repository.QuoteURL, error)
+	ListPaymentInstallments(ctx context.Context, quoteID, orgID uuid.UUID) ([]repository.PaymentInstallment, error)
 	SetPDFFileKey(ctx context.Context, quoteID uuid.UUID, fileKey string) error
 }
 
@@ -41,6 +43,12 @@ type QuotePDFBucketConfig interface {
 // QuoteOrgReader is the narrow interface for fetching organization profile data.
 type QuoteOrgReader interface {
 	GetOrganization(ctx context.Context, organizationID uuid.UUID) (identityrepo.Organization, error)
+	GetOrganizationBranding(ctx context.Context, organizationID uuid.UUID) (identityrepo.OrganizationBranding, error)
+}
+
+// QuoteTermsDocumentReader resolves the terms & conditions version printed on a quote PDF.
+type QuoteTermsDocumentReader interface {
+	ResolveQuoteTermsVersion(ctx context.Context, quote *repository.Quote) (*repository.QuoteTerms, error)
 }
 
 // QuoteAcceptanceProcessor implements notification.QuoteAcceptanceProcessor.
@@ -52,6 +60,7 @@ type QuoteAcceptanceProcessor struct {
 	storage       storage.StorageService
 	cfg           QuotePDFBucketConfig
 	termsResolver service.QuoteTermsResolver
+	termsDocs     QuoteTermsDocumentReader
 }
 
 // NewQuoteAcceptanceProcessor creates a new processor adapter.
@@ -66,6 +75,11 @@ func NewQuoteAcceptanceProcessor(repo QuoteDataReader, orgReader QuoteOrgReader,
 	}
 }
 
+// SetTermsDocumentReader enables embedding the terms & conditions version in the PDF.
+func (p *QuoteAcceptanceProcessor) SetTermsDocumentReader(reader QuoteTermsDocumentReader) {
+	p.termsDocs = reader
+}
+
 // GenerateAndStorePDF builds the quote PDF, uploads it to storage,
 // and persists the file key on the quote record.
 func (p *QuoteAcceptanceProcessor) GenerateAndStorePDF(
@@ -202,11 +216,14 @@ func (p *QuoteAcceptanceProcessor) buildPDFData(
 
 	applyContactData(&data, bc.contactData, quote)
 	p.applyQuoteTerms(ctx, &data, bc.organizationID, quote.LeadID, quote.LeadServiceID)
+	p.applyTermsDocument(ctx, &data, quote)
 	applyOrgFields(&data, bc.org, bc.orgErr)
+	p.applyBranding(ctx, &data, bc.organizationID)
 
 	// Load document attachments and download enabled PDFs from MinIO
 	data.AttachmentPDFs = p.downloadEnabledAttachments(ctx, quote.ID, quote.OrganizationID)
 	data.URLs = p.loadURLEntries(ctx, quote.ID, quote.OrganizationID)
+	data.PaymentSchedule = p.loadPaymentSchedule(ctx, quote, calc.TotalCents)
 
 	return data
 }
@@ -260,6 +277,28 @@ func (p *QuoteAcceptanceProcessor) applyQuoteTerms(ctx context.Context, data *pd
 	}
 }
 
+// applyTermsDocument embeds the terms version the quote was sent with, or the
+// one in effect now for a quote that was not sent yet.
+func (p *QuoteAcceptanceProcessor) applyTermsDocument(ctx context.Context, data *pdf.QuotePDFData, quote *repository.Quote) {
+	if p.termsDocs == nil {
+		return
+	}
+	terms, err := p.termsDocs.ResolveQuoteTermsVersion(ctx, quote)
+	if err != nil {
+		slog.Warn("failed to resolve terms document for quote pdf", "quoteID", quote.ID, "error", err)
+		return
+	}
+	if terms == nil {
+		return
+	}
+	data.Terms = &pdf.QuoteTermsEntry{
+		Title:         terms.DocumentName,
+		Version:       terms.Version.VersionNumber,
+		EffectiveFrom: terms.Version.EffectiveFrom,
+		Content:       terms.Version.Content,
+	}
+}
+
 // applyOrgFields populates organization fields on the PDF data when available.
 func applyOrgFields(data *pdf.QuotePDFData, org identityrepo.Organization, orgErr error) {
 	if orgErr != nil {
@@ -276,12 +315,36 @@ func applyOrgFields(data *pdf.QuotePDFData, org identityrepo.Organization, orgEr
 	data.OrgCountry = derefStr(org.Country)
 }
 
+// applyBranding sets the organization brand colors, keeping the default
+// colors when none are configured or they cannot be read.
+func (p *QuoteAcceptanceProcessor) applyBranding(ctx context.Context, data *pdf.QuotePDFData, organizationID uuid.UUID) {
+	branding, err := p.orgReader.GetOrganizationBranding(ctx, organizationID)
+	if err != nil {
+		slog.Warn("failed to load organization branding for quote pdf", "orgID", organizationID, "error", err)
+		return
+	}
+	data.PrimaryColor = derefStr(branding.PrimaryColor)
+	data.AccentColor = derefStr(branding.AccentColor)
+}
+
 // downloadOrgLogo fetches the organization logo from storage, returning nil on any failure.
 func (p *QuoteAcceptanceProcessor) downloadOrgLogo(
 	ctx context.Context,
 	org identityrepo.Organization,
 	orgErr error,
 	organizationID uuid.UUID,
+) []byte {
+	return downloadOrganizationLogo(ctx, p.storage, p.cfg.GetMinioBucketOrganizationLogos(), org, orgErr, organizationID)
+}
+
+// downloadOrganizationLogo is shared by the document processors that render the org logo.
+func downloadOrganizationLogo(
+	ctx context.Context,
+	storageSvc storage.StorageService,
+	logoBucket string,
+	org identityrepo.Organization,
+	orgErr error,
+	organizationID uuid.UUID,
 ) []byte {
 	if orgErr != nil {
 		slog.Warn("could not fetch organization for logo", "error", orgErr)
@@ -292,10 +355,9 @@ func (p *QuoteAcceptanceProcessor) downloadOrgLogo(
 		return nil
 	}
 
-	logoBucket := p.cfg.GetMinioBucketOrganizationLogos()
 	slog.Info("downloading org logo", "bucket", logoBucket, "key", *org.LogoFileKey)
 
-	logoReader, dlErr := p.storage.DownloadFile(ctx, logoBucket, *org.LogoFileKey)
+	logoReader, dlErr := storageSvc.DownloadFile(ctx, logoBucket, *org.LogoFileKey)
 	if dlErr != nil {
 		slog.Warn("logo download failed", "bucket", logoBucket, "key", *org.LogoFileKey, "error", dlErr)
 		return nil
@@ -385,6 +447,16 @@ func (p *QuoteAcceptanceProcessor) loadURLEntries(ctx context.Context, quoteID,
 	return result
 }
 
+// loadPaymentSchedule returns the installments printed below the totals, if any.
+func (p *QuoteAcceptanceProcessor) loadPaymentSchedule(ctx context.Context, quote *repository.Quote, totalCents int64) []transport.PaymentInstallmentResponse {
+	installments, err := p.repo.ListPaymentInstallments(ctx, quote.ID, quote.OrganizationID)
+	if err != nil {
+		slog.Warn("failed to load payment schedule", "quoteID", quote.ID, "error", err)
+		return nil
+	}
+	return service.BuildPaymentSchedule(installments, totalCents, quote.AcceptedAt, time.Now())
+}
+
 // uploadAndPersist uploads the PDF to MinIO and persists the file key on the quote record.
 func (p *QuoteAcceptanceProcessor) uploadAndPersist(
 	ctx context.Context,
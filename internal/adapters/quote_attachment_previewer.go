@@ -0,0 +1,356 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register decoder
+	"image/jpeg"
+	_ "image/png" // register decoder
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/pdf"
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Preview variants as requested by the public quote viewer. Images are scaled
+// down to JPEG. PDFs and office documents are served one page at a time as a
+// single-page PDF, which browsers render inline; a document thumbnail is its
+// first page.
+const (
+	attachmentPreviewThumbnail = "thumbnail"
+	attachmentPreviewFull      = "preview"
+)
+
+// Variants stored next to the requested ones: document pages, and the PDF
+// conversion of an office document so later pages skip LibreOffice.
+const (
+	attachmentPreviewPage     = "page"
+	attachmentPreviewDocument = "document"
+)
+
+const (
+	contentTypePDF  = "application/pdf"
+	contentTypeJPEG = "image/jpeg"
+
+	thumbnailMaxSide   = 320
+	previewMaxSide     = 1600
+	previewJPEGQuality = 82
+
+	// maxPreviewSourceBytes bounds the attachment read into memory for rendering.
+	maxPreviewSourceBytes = 50 << 20
+	// maxPreviewImagePixels guards against decompression bombs.
+	maxPreviewImagePixels = 50_000_000
+)
+
+type attachmentKind int
+
+const (
+	attachmentKindUnsupported attachmentKind = iota
+	attachmentKindImage
+	attachmentKindPDF
+	attachmentKindOffice
+)
+
+// QuoteAttachmentPreviewStore is the narrow repo interface for stored previews.
+type QuoteAttachmentPreviewStore interface {
+	GetAttachmentPreview(ctx context.Context, quoteID, orgID uuid.UUID, source, fileKey, variant string, page int) (*repository.QuoteAttachmentPreview, error)
+	CreateAttachmentPreview(ctx context.Context, preview repository.QuoteAttachmentPreview) (bool, error)
+	ListStaleAttachmentPreviews(ctx context.Context, quoteID, orgID uuid.UUID) ([]repository.QuoteAttachmentPreview, error)
+	DeleteAttachmentPreview(ctx context.Context, id, orgID uuid.UUID) error
+}
+
+// QuoteAttachmentPreviewer renders web-friendly previews of quote attachments
+// on first request and keeps them in the quote attachments bucket.
+type QuoteAttachmentPreviewer struct {
+	repo    QuoteAttachmentPreviewStore
+	storage storage.StorageService
+	cfg     QuotePDFBucketConfig
+}
+
+// NewQuoteAttachmentPreviewer creates a new previewer adapter.
+func NewQuoteAttachmentPreviewer(repo QuoteAttachmentPreviewStore, storageSvc storage.StorageService, cfg QuotePDFBucketConfig) *QuoteAttachmentPreviewer {
+	return &QuoteAttachmentPreviewer{repo: repo, storage: storageSvc, cfg: cfg}
+}
+
+// RenderAttachmentPreview returns the preview of an attachment, rendering and
+// storing it when it was not requested before. Page is 1-based and only used
+// for documents.
+func (p *QuoteAttachmentPreviewer) RenderAttachmentPreview(ctx context.Context, att repository.QuoteAttachment, variant string, page int) ([]byte, string, error) {
+	kind := detectAttachmentKind(att.Filename, att.FileKey)
+	switch kind {
+	case attachmentKindImage:
+		maxSide := previewMaxSide
+		if variant == attachmentPreviewThumbnail {
+			maxSide = thumbnailMaxSide
+		} else {
+			variant = attachmentPreviewFull
+		}
+		return p.cachedPreview(ctx, att, variant, 0, func() ([]byte, string, error) {
+			source, err := p.downloadSource(ctx, att)
+			if err != nil {
+				return nil, "", err
+			}
+			data, err := renderImagePreview(source, maxSide)
+			return data, contentTypeJPEG, err
+		})
+	case attachmentKindPDF, attachmentKindOffice:
+		if variant == attachmentPreviewThumbnail || page < 1 {
+			page = 1
+		}
+		return p.cachedPreview(ctx, att, attachmentPreviewPage, page, func() ([]byte, string, error) {
+			document, err := p.documentPDF(ctx, att, kind)
+			if err != nil {
+				return nil, "", err
+			}
+			data, err := pdf.ExtractPDFPage(ctx, document, page)
+			if errors.Is(err, pdf.ErrPageNotFound) {
+				return nil, "", apperr.NotFound("attachment page not found")
+			}
+			return data, contentTypePDF, err
+		})
+	default:
+		return nil, "", apperr.Validation("no preview is available for this attachment type")
+	}
+}
+
+// PruneAttachmentPreviews deletes the previews of files that are no longer
+// attached to the quote. Objects that cannot be deleted keep their record so
+// the next prune retries them.
+func (p *QuoteAttachmentPreviewer) PruneAttachmentPreviews(ctx context.Context, quoteID, orgID uuid.UUID) error {
+	stale, err := p.repo.ListStaleAttachmentPreviews(ctx, quoteID, orgID)
+	if err != nil {
+		return err
+	}
+
+	bucket := p.cfg.GetMinioBucketQuoteAttachments()
+	for _, preview := range stale {
+		if err := p.storage.DeleteObject(ctx, bucket, preview.PreviewKey); err != nil {
+			slog.Warn("failed to delete stale attachment preview", "quoteID", quoteID, "key", preview.PreviewKey, "error", err)
+			continue
+		}
+		if err := p.repo.DeleteAttachmentPreview(ctx, preview.ID, orgID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cachedPreview serves a stored preview, or renders and stores it. Storing is
+// best effort: a rendered preview is returned even when it cannot be kept.
+func (p *QuoteAttachmentPreviewer) cachedPreview(ctx context.Context, att repository.QuoteAttachment, variant string, page int, render func() ([]byte, string, error)) ([]byte, string, error) {
+	bucket := p.cfg.GetMinioBucketQuoteAttachments()
+
+	cached, err := p.repo.GetAttachmentPreview(ctx, att.QuoteID, att.OrganizationID, att.Source, att.FileKey, variant, page)
+	switch {
+	case err == nil:
+		data, readErr := readObject(ctx, p.storage, bucket, cached.PreviewKey)
+		if readErr == nil {
+			return data, cached.ContentType, nil
+		}
+		slog.Warn("stored attachment preview unreadable, rendering again", "quoteID", att.QuoteID, "key", cached.PreviewKey, "error", readErr)
+		if err := p.repo.DeleteAttachmentPreview(ctx, cached.ID, att.OrganizationID); err != nil {
+			return nil, "", err
+		}
+	case !apperr.Is(err, apperr.KindNotFound):
+		return nil, "", err
+	}
+
+	data, contentType, err := render()
+	if err != nil {
+		return nil, "", err
+	}
+	p.storePreview(ctx, bucket, att, variant, page, data, contentType)
+	return data, contentType, nil
+}
+
+func (p *QuoteAttachmentPreviewer) storePreview(ctx context.Context, bucket string, att repository.QuoteAttachment, variant string, page int, data []byte, contentType string) {
+	folder := fmt.Sprintf("%s/quotes/%s/previews", att.OrganizationID, att.QuoteID)
+	fileName := fmt.Sprintf("%s-%d%s", variant, page, previewExtension(contentType))
+	key, err := p.storage.UploadFile(ctx, bucket, folder, fileName, contentType, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		slog.Warn("failed to store attachment preview", "quoteID", att.QuoteID, "variant", variant, "error", err)
+		return
+	}
+
+	created, err := p.repo.CreateAttachmentPreview(ctx, repository.QuoteAttachmentPreview{
+		OrganizationID: att.OrganizationID,
+		QuoteID:        att.QuoteID,
+		Source:         att.Source,
+		FileKey:        att.FileKey,
+		Variant:        variant,
+		Page:           page,
+		PreviewKey:     key,
+		ContentType:    contentType,
+		SizeBytes:      int64(len(data)),
+	})
+	if err != nil {
+		slog.Warn("failed to record attachment preview", "quoteID", att.QuoteID, "variant", variant, "error", err)
+	}
+	if err != nil || !created {
+		// A concurrent request stored the same preview first.
+		if delErr := p.storage.DeleteObject(ctx, bucket, key); delErr != nil {
+			slog.Warn("failed to delete duplicate attachment preview", "key", key, "error", delErr)
+		}
+	}
+}
+
+// documentPDF returns the attachment as PDF, converting office documents with
+// Gotenberg once and keeping the conversion.
+func (p *QuoteAttachmentPreviewer) documentPDF(ctx context.Context, att repository.QuoteAttachment, kind attachmentKind) ([]byte, error) {
+	if kind == attachmentKindPDF {
+		return p.downloadSource(ctx, att)
+	}
+
+	data, _, err := p.cachedPreview(ctx, att, attachmentPreviewDocument, 0, func() ([]byte, string, error) {
+		source, err := p.downloadSource(ctx, att)
+		if err != nil {
+			return nil, "", err
+		}
+		converted, err := pdf.ConvertOfficeToPDF(ctx, officeFilename(att), source)
+		return converted, contentTypePDF, err
+	})
+	return data, err
+}
+
+func (p *QuoteAttachmentPreviewer) downloadSource(ctx context.Context, att repository.QuoteAttachment) ([]byte, error) {
+	buckets := resolveAttachmentBuckets(att.Source, p.cfg.GetMinioBucketQuoteAttachments(), p.cfg.GetMinioBucketCatalogAssets())
+	var lastErr error
+	for _, bucket := range buckets {
+		data, err := readObject(ctx, p.storage, bucket, att.FileKey)
+		if err == nil {
+			return data, nil
+		}
+		if apperr.Is(err, apperr.KindValidation) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("download attachment %s: %w", att.FileKey, lastErr)
+}
+
+func readObject(ctx context.Context, storageSvc storage.StorageService, bucket, key string) ([]byte, error) {
+	reader, err := storageSvc.DownloadFile(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxPreviewSourceBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxPreviewSourceBytes {
+		return nil, apperr.Validation("attachment is too large to preview")
+	}
+	return data, nil
+}
+
+// detectAttachmentKind classifies an attachment by the extension of its
+// filename, falling back to the storage key.
+func detectAttachmentKind(filename, fileKey string) attachmentKind {
+	ext := strings.ToLower(path.Ext(strings.TrimSpace(filename)))
+	if ext == "" {
+		ext = strings.ToLower(path.Ext(fileKey))
+	}
+	switch ext {
+	case ".jpg", ".jpeg", ".png", ".gif":
+		return attachmentKindImage
+	case ".pdf":
+		return attachmentKindPDF
+	case ".doc", ".docx", ".odt", ".rtf", ".xls", ".xlsx", ".ods", ".ppt", ".pptx", ".odp":
+		return attachmentKindOffice
+	default:
+		return attachmentKindUnsupported
+	}
+}
+
+// officeFilename returns a filename whose extension LibreOffice recognizes.
+func officeFilename(att repository.QuoteAttachment) string {
+	if path.Ext(strings.TrimSpace(att.Filename)) != "" {
+		return path.Base(strings.TrimSpace(att.Filename))
+	}
+	return path.Base(att.FileKey)
+}
+
+func previewExtension(contentType string) string {
+	if contentType == contentTypeJPEG {
+		return ".jpg"
+	}
+	return ".pdf"
+}
+
+// renderImagePreview scales an image to fit within maxSide pixels and encodes
+// it as JPEG. Smaller images are re-encoded without upscaling; transparency is
+// flattened onto white.
+func renderImagePreview(data []byte, maxSide int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperr.Validation("attachment image cannot be decoded")
+	}
+	if cfg.Width*cfg.Height > maxPreviewImagePixels {
+		return nil, apperr.Validation("attachment image is too large to preview")
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperr.Validation("attachment image cannot be decoded")
+	}
+	scaled := scaleImage(src, maxSide)
+
+	canvas := image.NewRGBA(scaled.Bounds())
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), scaled, scaled.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: previewJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("encode preview: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleImage shrinks src to fit within maxSide by averaging the source pixels
+// that fall into each target pixel.
+func scaleImage(src image.Image, maxSide int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return src
+	}
+
+	scale := float64(maxSide) / float64(max(w, h))
+	dw := max(1, int(float64(w)*scale+0.5))
+	dh := max(1, int(float64(h)*scale+0.5))
+	dst := image.NewRGBA64(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		sy0 := b.Min.Y + y*h/dh
+		sy1 := max(sy0+1, b.Min.Y+(y+1)*h/dh)
+		for x := 0; x < dw; x++ {
+			sx0 := b.Min.X + x*w/dw
+			sx1 := max(sx0+1, b.Min.X+(x+1)*w/dw)
+
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,83 @@
+package adapters
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestDetectAttachmentKind(t *testing.T) {
+	cases := []struct {
+		filename string
+		fileKey  string
+		want     attachmentKind
+	}{
+		{"Brochure.PDF", "org/quotes/q/brochure_1a2b3c4d.pdf", attachmentKindPDF},
+		{"foto.jpeg", "", attachmentKindImage},
+		{"Offerte voorwaarden.docx", "", attachmentKindOffice},
+		{"prijslijst", "org/catalog/prijslijst_1a2b3c4d.xlsx", attachmentKindOffice},
+		{"video.mp4", "", attachmentKindUnsupported},
+		{"logo.svg", "", attachmentKindUnsupported},
+	}
+	for _, tc := range cases {
+		if got := detectAttachmentKind(tc.filename, tc.fileKey); got != tc.want {
+			t.Errorf("detectAttachmentKind(%q, %q) = %d, want %d", tc.filename, tc.fileKey, got, tc.want)
+		}
+	}
+}
+
+func TestRenderImagePreviewScalesDown(t *testing.T) {
+	data := encodeTestPNG(t, 1000, 500)
+
+	preview, err := renderImagePreview(data, thumbnailMaxSide)
+	if err != nil {
+		t.Fatalf("render preview: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(preview))
+	if err != nil {
+		t.Fatalf("decode preview as JPEG: %v", err)
+	}
+	if got := img.Bounds().Size(); got.X != 320 || got.Y != 160 {
+		t.Fatalf("expected 320x160 preview, got %dx%d", got.X, got.Y)
+	}
+}
+
+func TestRenderImagePreviewDoesNotUpscale(t *testing.T) {
+	data := encodeTestPNG(t, 120, 80)
+
+	preview, err := renderImagePreview(data, previewMaxSide)
+	if err != nil {
+		t.Fatalf("render preview: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(preview))
+	if err != nil {
+		t.Fatalf("decode preview as JPEG: %v", err)
+	}
+	if got := img.Bounds().Size(); got.X != 120 || got.Y != 80 {
+		t.Fatalf("expected 120x80 preview, got %dx%d", got.X, got.Y)
+	}
+}
+
+func TestRenderImagePreviewRejectsNonImage(t *testing.T) {
+	if _, err := renderImagePreview([]byte("%PDF-1.7"), thumbnailMaxSide); err == nil {
+		t.Fatal("expected an error for data that is not an image")
+	}
+}
+
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
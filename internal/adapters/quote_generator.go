@@ -23,6 +23,12 @@ func NewQuoteGeneratorAdapter(generator agent.QuoteGenerator) *QuoteGeneratorAda
 
 // GenerateFromPrompt calls the leads module's QuoteGenerator agent and maps the result.
 func (a *QuoteGeneratorAdapter) GenerateFromPrompt(ctx context.Context, leadID, serviceID, tenantID uuid.UUID, prompt string, existingQuoteID *uuid.UUID, force bool) (*quotesvc.GenerateQuoteResult, error) {
+	if report := quotesvc.GenerateQuoteProgressReporter(ctx); report != nil {
+		ctx = agent.WithGenerationProgress(ctx, func(p agent.GenerationProgress) {
+			report(quotesvc.GenerateQuoteProgress{Stage: p.Stage, Count: p.Count})
+		})
+	}
+
 	result, err := a.quoteGenerator.Generate(ctx, leadID, serviceID, tenantID, prompt, existingQuoteID, force)
 	if err != nil {
 		return nil, fmt.Errorf("quote generation failed: %w", err)
@@ -70,6 +70,53 @@ func (a *QuoteTermsResolverAdapter) ResolveQuoteTerms(
 		return paymentDays, validDays, nil
 	}
 
+	workflow := a.resolveWorkflow(ctx, organizationID, leadID, serviceContext)
+	if workflow == nil {
+		return paymentDays, validDays, nil
+	}
+
+	if workflow.QuotePaymentDaysOverride != nil {
+		paymentDays = *workflow.QuotePaymentDaysOverride
+	}
+	if workflow.QuoteValidDaysOverride != nil {
+		validDays = *workflow.QuoteValidDaysOverride
+	}
+
+	return paymentDays, validDays, nil
+}
+
+// ResolveQuoteTermsScope returns the service type and effective workflow that
+// select the terms document of a quote.
+func (a *QuoteTermsResolverAdapter) ResolveQuoteTermsScope(
+	ctx context.Context,
+	organizationID uuid.UUID,
+	leadID uuid.UUID,
+	leadServiceID *uuid.UUID,
+) (quotesvc.QuoteTermsScope, error) {
+	if a.leadServices == nil {
+		return quotesvc.QuoteTermsScope{}, nil
+	}
+	serviceContext, ok := a.resolveLeadServiceContext(ctx, leadID, organizationID, leadServiceID)
+	if !ok {
+		return quotesvc.QuoteTermsScope{}, nil
+	}
+
+	scope := quotesvc.QuoteTermsScope{ServiceType: serviceContext.ServiceType}
+	if a.workflowResolver == nil {
+		return scope, nil
+	}
+	if workflow := a.resolveWorkflow(ctx, organizationID, leadID, serviceContext); workflow != nil {
+		scope.WorkflowID = &workflow.ID
+	}
+	return scope, nil
+}
+
+func (a *QuoteTermsResolverAdapter) resolveWorkflow(
+	ctx context.Context,
+	organizationID uuid.UUID,
+	leadID uuid.UUID,
+	serviceContext leadsrepo.LeadService,
+) *identityrepo.Workflow {
 	resolved, err := a.workflowResolver.ResolveLeadWorkflow(ctx, identitysvc.ResolveLeadWorkflowInput{
 		OrganizationID:  organizationID,
 		LeadID:          leadID,
@@ -77,18 +124,10 @@ func (a *QuoteTermsResolverAdapter) ResolveQuoteTerms(
 		LeadServiceType: &serviceContext.ServiceType,
 		PipelineStage:   &serviceContext.PipelineStage,
 	})
-	if err != nil || resolved.Workflow == nil {
-		return paymentDays, validDays, nil
-	}
-
-	if resolved.Workflow.QuotePaymentDaysOverride != nil {
-		paymentDays = *resolved.Workflow.QuotePaymentDaysOverride
-	}
-	if resolved.Workflow.QuoteValidDaysOverride != nil {
-		validDays = *resolved.Workflow.QuoteValidDaysOverride
+	if err != nil {
+		return nil
 	}
-
-	return paymentDays, validDays, nil
+	return resolved.Workflow
 }
 
 func (a *QuoteTermsResolverAdapter) resolveLeadServiceContext(
@@ -0,0 +1,33 @@
+package adapters
+
+import (
+	"context"
+
+	identitysvc "portal_final_backend/internal/identity/service"
+	quotesvc "portal_final_backend/internal/quotes/service"
+	"portal_final_backend/internal/quotes/transport"
+
+	"github.com/google/uuid"
+)
+
+// QuotesBrandingReader exposes organization brand colors for public quote pages.
+type QuotesBrandingReader struct {
+	svc *identitysvc.Service
+}
+
+// NewQuotesBrandingReader creates a new branding reader adapter.
+func NewQuotesBrandingReader(svc *identitysvc.Service) *QuotesBrandingReader {
+	return &QuotesBrandingReader{svc: svc}
+}
+
+// GetPublicBranding returns the brand colors of the organization.
+func (r *QuotesBrandingReader) GetPublicBranding(ctx context.Context, organizationID uuid.UUID) (transport.PublicBrandingResponse, error) {
+	branding, err := r.svc.GetOrganizationBranding(ctx, organizationID)
+	if err != nil {
+		return transport.PublicBrandingResponse{}, err
+	}
+	return transport.PublicBrandingResponse{PrimaryColor: branding.PrimaryColor, AccentColor: branding.AccentColor}, nil
+}
+
+// Compile-time check that QuotesBrandingReader implements quotes/service.BrandingReader.
+var _ quotesvc.BrandingReader = (*QuotesBrandingReader)(nil)
@@ -31,6 +31,7 @@ func (a *QuotesTimelineWriter) CreateTimelineEvent(ctx context.Context, params q
 		Summary:        params.Summary,
 		Metadata:       params.Metadata,
 		Visibility:     params.Visibility,
+		DedupKey:       params.DedupKey,
 	})
 	return err
 }
@@ -0,0 +1,72 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"portal_final_backend/internal/leads/maintenance"
+	"portal_final_backend/internal/tasks"
+)
+
+// StaleLeadTaskCreatorAdapter opens the follow-up tasks of the stale lead
+// sweep through the tasks service.
+type StaleLeadTaskCreatorAdapter struct {
+	tasks *tasks.Service
+}
+
+func NewStaleLeadTaskCreatorAdapter(tasksSvc *tasks.Service) *StaleLeadTaskCreatorAdapter {
+	return &StaleLeadTaskCreatorAdapter{tasks: tasksSvc}
+}
+
+// CreateStaleLeadTask opens a high priority task for the owner of a stale lead
+// service, unless the service still has an open follow-up task. The owner is
+// also recorded as its creator, as the task has no human author.
+func (a *StaleLeadTaskCreatorAdapter) CreateStaleLeadTask(ctx context.Context, task maintenance.StaleLeadTask) (bool, error) {
+	if a.tasks == nil {
+		return false, fmt.Errorf("task service not configured")
+	}
+	open, err := a.tasks.List(ctx, task.OrganizationID, tasks.ListTasksRequest{
+		ScopeType:     tasks.ScopeLeadService,
+		Status:        tasks.StatusOpen,
+		LeadServiceID: task.LeadServiceID.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, existing := range open {
+		if strings.HasPrefix(existing.Title, maintenance.StaleLeadTaskTitlePrefix) {
+			return false, nil
+		}
+	}
+
+	leadID := task.LeadID.String()
+	serviceID := task.LeadServiceID.String()
+	description := task.Description
+	dueAt := task.DueAt
+	_, err = a.tasks.Create(ctx, task.OrganizationID, task.AssigneeID, tasks.CreateTaskRequest{
+		ScopeType:      tasks.ScopeLeadService,
+		LeadID:         &leadID,
+		LeadServiceID:  &serviceID,
+		AssignedUserID: task.AssigneeID.String(),
+		Title:          truncateTaskTitle(task.Title),
+		Description:    &description,
+		Priority:       tasks.PriorityHigh,
+		DueAt:          &dueAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// truncateTaskTitle keeps a title within the 200 characters tasks allow.
+func truncateTaskTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) <= 200 {
+		return title
+	}
+	return string(runes[:200])
+}
+
+var _ maintenance.StaleLeadTaskCreator = (*StaleLeadTaskCreatorAdapter)(nil)
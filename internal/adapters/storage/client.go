@@ -74,10 +74,7 @@ func (s *MinIOService) GenerateUploadURL(ctx context.Context, bucket, folder, fi
 	}
 
 	// Generate unique file key with UUID to prevent overwrites
-	ext := path.Ext(fileName)
-	baseName := strings.TrimSuffix(fileName, ext)
-	uniqueFileName := fmt.Sprintf("%s_%s%s", baseName, uuid.New().String()[:8], ext)
-	fileKey := filepath.ToSlash(filepath.Join(folder, uniqueFileName))
+	fileKey := uniqueFileKey(folder, fileName)
 
 	// Generate presigned PUT URL
 	expiresAt := time.Now().Add(PresignedURLTTL)
@@ -141,10 +138,7 @@ func (s *MinIOService) DeleteObject(ctx context.Context, bucket, fileKey string)
 
 // UploadFile uploads a file directly to storage from an io.Reader and returns the file key.
 func (s *MinIOService) UploadFile(ctx context.Context, bucket, folder, fileName, contentType string, reader io.Reader, size int64) (string, error) {
-	ext := path.Ext(fileName)
-	baseName := strings.TrimSuffix(fileName, ext)
-	uniqueFileName := fmt.Sprintf("%s_%s%s", baseName, uuid.New().String()[:8], ext)
-	fileKey := filepath.ToSlash(filepath.Join(folder, uniqueFileName))
+	fileKey := uniqueFileKey(folder, fileName)
 
 	_, err := s.client.PutObject(ctx, bucket, fileKey, reader, size, minio.PutObjectOptions{
 		ContentType: contentType,
@@ -155,6 +149,14 @@ func (s *MinIOService) UploadFile(ctx context.Context, bucket, folder, fileName,
 	return fileKey, nil
 }
 
+// uniqueFileKey places fileName in folder with a random suffix to prevent overwrites.
+func uniqueFileKey(folder, fileName string) string {
+	ext := path.Ext(fileName)
+	baseName := strings.TrimSuffix(fileName, ext)
+	uniqueFileName := fmt.Sprintf("%s_%s%s", baseName, uuid.New().String()[:8], ext)
+	return filepath.ToSlash(filepath.Join(folder, uniqueFileName))
+}
+
 // GetMaxFileSize returns the configured maximum file size in bytes.
 func (s *MinIOService) GetMaxFileSize() int64 {
 	return s.maxFileSize
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// ObjectInventory is implemented by storage backends that can inspect and enumerate
+// objects. It is kept separate from StorageService so existing fakes stay valid.
+type ObjectInventory interface {
+	// StatObject returns the metadata of an object without downloading it.
+	StatObject(ctx context.Context, bucket, fileKey string) (ObjectInfo, error)
+
+	// ListObjects calls fn for every object under prefix. Returning an error from fn
+	// stops the iteration and is returned as-is.
+	ListObjects(ctx context.Context, bucket, prefix string, fn func(ObjectInfo) error) error
+}
+
+var _ ObjectInventory = (*MinIOService)(nil)
+
+// StatObject returns the metadata of an object without downloading it.
+func (s *MinIOService) StatObject(ctx context.Context, bucket, fileKey string) (ObjectInfo, error) {
+	stat, err := s.client.StatObject(ctx, bucket, fileKey, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat object %s: %w", fileKey, err)
+	}
+	return ObjectInfo{Key: stat.Key, Size: stat.Size, ContentType: stat.ContentType, LastModified: stat.LastModified}, nil
+}
+
+// ListObjects calls fn for every object under prefix.
+func (s *MinIOService) ListObjects(ctx context.Context, bucket, prefix string, fn func(ObjectInfo) error) error {
+	for obj := range s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return fmt.Errorf("failed to list objects in %s: %w", bucket, obj.Err)
+		}
+		if err := fn(ObjectInfo{Key: obj.Key, Size: obj.Size, ContentType: obj.ContentType, LastModified: obj.LastModified}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
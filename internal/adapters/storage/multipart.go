@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"portal_final_backend/platform/apperr"
+)
+
+// Multipart form fields of a streamed upload. The size and checksum fields must
+// precede the file part, because the file is stored while it is being read.
+const (
+	MultipartFileField     = "file"
+	MultipartSizeField     = "sizeBytes"
+	MultipartChecksumField = "checksumSha256"
+)
+
+// maxMultipartFieldBytes bounds the non-file form fields read into memory.
+const maxMultipartFieldBytes = 4 << 10
+
+// MultipartUpload is a file streamed from a multipart request into storage.
+type MultipartUpload struct {
+	FileKey        string
+	FileName       string
+	ContentType    string
+	SizeBytes      int64
+	ChecksumSHA256 string
+	// Fields holds the form fields sent before the file part.
+	Fields map[string]string
+}
+
+// StreamMultipartUpload pipes the file part of a multipart/form-data request
+// straight into storage, so the file is never held in memory or on disk. The
+// sizeBytes field is required; checksumSha256, when sent, is compared with the
+// SHA-256 of the received bytes. Uploads that do not match their declared size
+// or checksum are deleted again. accept, when not nil, can further restrict the
+// content types allowed by the storage service; its error is returned as is.
+func StreamMultipartUpload(ctx context.Context, svc StorageService, req *http.Request, bucket, folder string, accept func(contentType string) error) (MultipartUpload, error) {
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return MultipartUpload{}, apperr.BadRequest("expected a multipart/form-data body")
+	}
+
+	upload := MultipartUpload{Fields: make(map[string]string)}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return MultipartUpload{}, apperr.Validation("file is required")
+		}
+		if err != nil {
+			return MultipartUpload{}, multipartReadError(err)
+		}
+		if part.FormName() == MultipartFileField {
+			return streamFilePart(ctx, svc, part, bucket, folder, upload, accept)
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxMultipartFieldBytes+1))
+		if err != nil {
+			return MultipartUpload{}, multipartReadError(err)
+		}
+		if len(value) > maxMultipartFieldBytes {
+			return MultipartUpload{}, apperr.Validation("form field " + part.FormName() + " is too long")
+		}
+		upload.Fields[part.FormName()] = strings.TrimSpace(string(value))
+	}
+}
+
+func streamFilePart(ctx context.Context, svc StorageService, part *multipart.Part, bucket, folder string, upload MultipartUpload, accept func(string) error) (MultipartUpload, error) {
+	fileName := path.Base(strings.ReplaceAll(strings.TrimSpace(part.FileName()), "\\", "/"))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return MultipartUpload{}, apperr.Validation("file name is required")
+	}
+	contentType := strings.TrimSpace(part.Header.Get("Content-Type"))
+	if err := svc.ValidateContentType(contentType); err != nil {
+		return MultipartUpload{}, apperr.Validation("file type not allowed")
+	}
+	if accept != nil {
+		if err := accept(contentType); err != nil {
+			return MultipartUpload{}, err
+		}
+	}
+
+	size, expectedChecksum, err := parseUploadFields(upload.Fields)
+	if err != nil {
+		return MultipartUpload{}, err
+	}
+	if err := svc.ValidateFileSize(size); err != nil {
+		return MultipartUpload{}, apperr.Validation(err.Error())
+	}
+
+	counted := &countingHashReader{reader: part, hash: sha256.New()}
+	fileKey, err := svc.UploadFile(ctx, bucket, folder, fileName, contentType, counted, size)
+	if err != nil {
+		return MultipartUpload{}, multipartReadError(err)
+	}
+
+	// Anything left in the part means the file is larger than declared.
+	extra, err := io.Copy(io.Discard, io.LimitReader(part, 1))
+	if err != nil || extra > 0 || counted.n != size {
+		_ = svc.DeleteObject(ctx, bucket, fileKey)
+		if err != nil {
+			return MultipartUpload{}, multipartReadError(err)
+		}
+		return MultipartUpload{}, apperr.Validation("file size does not match sizeBytes")
+	}
+
+	checksum := hex.EncodeToString(counted.hash.Sum(nil))
+	if expectedChecksum != "" && checksum != expectedChecksum {
+		_ = svc.DeleteObject(ctx, bucket, fileKey)
+		return MultipartUpload{}, apperr.Validation("file checksum does not match checksumSha256")
+	}
+
+	upload.FileKey = fileKey
+	upload.FileName = fileName
+	upload.ContentType = contentType
+	upload.SizeBytes = size
+	upload.ChecksumSHA256 = checksum
+	return upload, nil
+}
+
+// parseUploadFields reads the declared size and the optional hex SHA-256 checksum.
+func parseUploadFields(fields map[string]string) (int64, string, error) {
+	size, err := strconv.ParseInt(fields[MultipartSizeField], 10, 64)
+	if err != nil || size <= 0 {
+		return 0, "", apperr.Validation(MultipartSizeField + " must be sent before the file as a positive number")
+	}
+
+	checksum := strings.ToLower(fields[MultipartChecksumField])
+	if checksum == "" {
+		return size, "", nil
+	}
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return 0, "", apperr.Validation(MultipartChecksumField + " must be a hex encoded SHA-256")
+	}
+	return size, checksum, nil
+}
+
+func multipartReadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperr.PayloadTooLarge("request body too large")
+	}
+	var domainErr *apperr.Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return err
+}
+
+// countingHashReader hashes and counts the bytes read through it.
+type countingHashReader struct {
+	reader io.Reader
+	hash   hash.Hash
+	n      int64
+}
+
+func (r *countingHashReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	_, _ = r.hash.Write(p[:n])
+	return n, err
+}
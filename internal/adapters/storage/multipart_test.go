@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"testing"
+
+	"portal_final_backend/platform/apperr"
+)
+
+type memoryStorage struct {
+	StorageService
+	objects map[string][]byte
+}
+
+func (m *memoryStorage) ValidateContentType(contentType string) error {
+	if contentType != "application/pdf" {
+		return errors.New("not allowed")
+	}
+	return nil
+}
+
+func (m *memoryStorage) ValidateFileSize(sizeBytes int64) error { return nil }
+
+func (m *memoryStorage) UploadFile(_ context.Context, _, folder, fileName, _ string, reader io.Reader, size int64) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, size))
+	if err != nil {
+		return "", err
+	}
+	key := folder + "/" + fileName
+	m.objects[key] = data
+	return key, nil
+}
+
+func (m *memoryStorage) DeleteObject(_ context.Context, _, fileKey string) error {
+	delete(m.objects, fileKey)
+	return nil
+}
+
+func multipartRequest(t *testing.T, fields map[string]string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, name := range []string{MultipartSizeField, MultipartChecksumField} {
+		if value, ok := fields[name]; ok {
+			_ = writer.WriteField(name, value)
+		}
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="quote.pdf"`)
+	header.Set("Content-Type", "application/pdf")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = part.Write(content)
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestStreamMultipartUploadVerifiesChecksum(t *testing.T) {
+	content := []byte("%PDF-1.7 test")
+	sum := sha256.Sum256(content)
+	store := &memoryStorage{objects: map[string][]byte{}}
+
+	req := multipartRequest(t, map[string]string{
+		MultipartSizeField:     strconv.Itoa(len(content)),
+		MultipartChecksumField: hex.EncodeToString(sum[:]),
+	}, content)
+	upload, err := StreamMultipartUpload(context.Background(), store, req, "bucket", "org/quotes", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if upload.FileKey != "org/quotes/quote.pdf" || upload.SizeBytes != int64(len(content)) || !bytes.Equal(store.objects[upload.FileKey], content) {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+
+	req = multipartRequest(t, map[string]string{
+		MultipartSizeField:     strconv.Itoa(len(content)),
+		MultipartChecksumField: hex.EncodeToString(make([]byte, sha256.Size)),
+	}, content)
+	delete(store.objects, upload.FileKey)
+	_, err = StreamMultipartUpload(context.Background(), store, req, "bucket", "org/quotes", nil)
+	if !apperr.Is(err, apperr.KindValidation) || len(store.objects) != 0 {
+		t.Fatalf("expected a checksum mismatch to be rejected and deleted, got %v (%d objects)", err, len(store.objects))
+	}
+}
+
+func TestStreamMultipartUploadRejectsSizeMismatch(t *testing.T) {
+	content := []byte("%PDF-1.7 test")
+	store := &memoryStorage{objects: map[string][]byte{}}
+
+	req := multipartRequest(t, map[string]string{MultipartSizeField: "4"}, content)
+	_, err := StreamMultipartUpload(context.Background(), store, req, "bucket", "org", nil)
+	if !apperr.Is(err, apperr.KindValidation) || len(store.objects) != 0 {
+		t.Fatalf("expected a larger file than declared to be rejected, got %v", err)
+	}
+
+	req = multipartRequest(t, map[string]string{}, content)
+	if _, err := StreamMultipartUpload(context.Background(), store, req, "bucket", "org", nil); !apperr.Is(err, apperr.KindValidation) {
+		t.Fatalf("expected a missing size to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// MinMultipartPartSize is the smallest size S3 accepts for every part but the last.
+	MinMultipartPartSize = 5 << 20
+	// MaxMultipartParts is the largest number of parts S3 accepts for one object.
+	MaxMultipartParts = 10000
+)
+
+// UploadedPart is a stored part of a multipart upload.
+type UploadedPart struct {
+	Number int
+	ETag   string
+	Size   int64
+}
+
+// MultipartUploader is implemented by storage backends that can assemble an object
+// from parts uploaded one by one (S3 multipart uploads). It is kept separate from
+// StorageService so existing fakes stay valid.
+type MultipartUploader interface {
+	// StartMultipartUpload reserves a file key in folder and starts an upload for it.
+	// size is the expected size of the complete object.
+	StartMultipartUpload(ctx context.Context, bucket, folder, fileName, contentType string, size int64) (fileKey, uploadID string, err error)
+
+	// UploadPart stores one part. An upload of a part number that was already
+	// stored replaces it. sha256Hex, when set, is verified by the backend.
+	UploadPart(ctx context.Context, bucket, fileKey, uploadID string, number int, reader io.Reader, size int64, sha256Hex string) (UploadedPart, error)
+
+	// CompleteMultipartUpload assembles the object from parts, ordered by number.
+	CompleteMultipartUpload(ctx context.Context, bucket, fileKey, uploadID string, parts []UploadedPart) error
+
+	// AbortMultipartUpload discards an upload and its stored parts.
+	AbortMultipartUpload(ctx context.Context, bucket, fileKey, uploadID string) error
+}
+
+var _ MultipartUploader = (*MinIOService)(nil)
+
+// StartMultipartUpload starts an S3 multipart upload under a unique file key.
+func (s *MinIOService) StartMultipartUpload(ctx context.Context, bucket, folder, fileName, contentType string, size int64) (string, string, error) {
+	if err := s.ValidateContentType(contentType); err != nil {
+		return "", "", err
+	}
+	if err := s.ValidateFileSize(size); err != nil {
+		return "", "", err
+	}
+
+	fileKey := uniqueFileKey(folder, fileName)
+	uploadID, err := s.core().NewMultipartUpload(ctx, bucket, fileKey, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to start multipart upload %s: %w", fileKey, err)
+	}
+	return fileKey, uploadID, nil
+}
+
+// UploadPart stores one part of a multipart upload.
+func (s *MinIOService) UploadPart(ctx context.Context, bucket, fileKey, uploadID string, number int, reader io.Reader, size int64, sha256Hex string) (UploadedPart, error) {
+	part, err := s.core().PutObjectPart(ctx, bucket, fileKey, uploadID, number, reader, size, minio.PutObjectPartOptions{Sha256Hex: sha256Hex})
+	if err != nil {
+		return UploadedPart{}, fmt.Errorf("failed to upload part %d of %s: %w", number, fileKey, err)
+	}
+	return UploadedPart{Number: part.PartNumber, ETag: part.ETag, Size: part.Size}, nil
+}
+
+// CompleteMultipartUpload assembles the object from its parts.
+func (s *MinIOService) CompleteMultipartUpload(ctx context.Context, bucket, fileKey, uploadID string, parts []UploadedPart) error {
+	complete := make([]minio.CompletePart, 0, len(parts))
+	for _, part := range parts {
+		complete = append(complete, minio.CompletePart{PartNumber: part.Number, ETag: part.ETag})
+	}
+	if _, err := s.core().CompleteMultipartUpload(ctx, bucket, fileKey, uploadID, complete, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to complete multipart upload %s: %w", fileKey, err)
+	}
+	return nil
+}
+
+// AbortMultipartUpload discards a multipart upload.
+func (s *MinIOService) AbortMultipartUpload(ctx context.Context, bucket, fileKey, uploadID string) error {
+	if err := s.core().AbortMultipartUpload(ctx, bucket, fileKey, uploadID); err != nil {
+		return fmt.Errorf("failed to abort multipart upload %s: %w", fileKey, err)
+	}
+	return nil
+}
+
+func (s *MinIOService) core() minio.Core {
+	return minio.Core{Client: s.client}
+}
@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"portal_final_backend/internal/analytics/service"
+	"portal_final_backend/internal/analytics/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxCostReportBytes = 10 << 20
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+func (h *Handler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/analytics/funnel", h.GetFunnel)
+	rg.GET("/analytics/breakdown", h.GetBreakdown)
+	rg.GET("/analytics/daily", h.GetDaily)
+	rg.GET("/analytics/source-roi", h.GetSourceROI)
+	rg.GET("/analytics/source-roi.csv", h.ExportSourceROICSV)
+	rg.GET("/analytics/agent-workload", h.GetAgentWorkload)
+	rg.GET("/analytics/capacity-plan", h.GetCapacityPlan)
+	rg.GET("/analytics/workflows", h.GetWorkflowAnalytics)
+	rg.GET("/analytics/workflows/variants", h.GetWorkflowVariantAnalytics)
+	rg.GET("/analytics/group/funnel", h.GetGroupFunnel)
+	rg.GET("/analytics/group/breakdown", h.GetGroupBreakdown)
+}
+
+// RegisterAdminRoutes registers the marketing cost import.
+func (h *Handler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.POST("/analytics/marketing-costs/import", h.ImportMarketingCosts)
+}
+
+func (h *Handler) GetFunnel(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.ReportQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query)
+	resp, err := h.svc.GetFunnel(c.Request.Context(), tenantID, from, to)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetBreakdown(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.BreakdownQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query.ReportQuery)
+	resp, err := h.svc.GetBreakdown(c.Request.Context(), tenantID, query.GroupBy, from, to)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// GetGroupFunnel consolidates the funnel of a franchise head office and its branches.
+func (h *Handler) GetGroupFunnel(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.ReportQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query)
+	resp, err := h.svc.GetGroupFunnel(c.Request.Context(), tenantID, from, to)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// GetGroupBreakdown consolidates the breakdown of a franchise head office and its branches.
+func (h *Handler) GetGroupBreakdown(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.BreakdownQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query.ReportQuery)
+	resp, err := h.svc.GetGroupBreakdown(c.Request.Context(), tenantID, query.GroupBy, from, to)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetDaily(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.ReportQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query)
+	resp, err := h.svc.GetDaily(c.Request.Context(), tenantID, from, to)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetSourceROI(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.ReportQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query)
+	resp, err := h.svc.GetSourceROI(c.Request.Context(), tenantID, from, to)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetWorkflowAnalytics(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.WorkflowAnalyticsQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query.ReportQuery)
+	resp, err := h.svc.GetWorkflowAnalytics(c.Request.Context(), tenantID, from, to, query.ReplyWindowDays, query.ConversionWindowDays)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetWorkflowVariantAnalytics(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.WorkflowAnalyticsQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query.ReportQuery)
+	resp, err := h.svc.GetWorkflowVariantAnalytics(c.Request.Context(), tenantID, from, to, query.ReplyWindowDays, query.ConversionWindowDays)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) ExportSourceROICSV(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.ReportQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	from, to := parseRange(query)
+	resp, err := h.svc.GetSourceROI(c.Request.Context(), tenantID, from, to)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=source-roi-%s-%s.csv", resp.From, resp.To))
+
+	writer := csv.NewWriter(c.Writer)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{"Source", "Leads", "Customers", "Conversion Rate", "Quotes Accepted", "Revenue", "Cost", "Clicks", "ROI", "Cost Per Lead", "Cost Per Customer"})
+	for _, item := range append(resp.Sources, resp.Totals) {
+		_ = writer.Write([]string{
+			item.Source,
+			strconv.FormatInt(item.Leads, 10),
+			strconv.FormatInt(item.Customers, 10),
+			strconv.FormatFloat(item.ConversionRate, 'f', 4, 64),
+			strconv.FormatInt(item.QuotesAccepted, 10),
+			formatCents(item.RevenueCents),
+			formatCents(item.CostCents),
+			strconv.FormatInt(item.Clicks, 10),
+			formatOptionalRatio(item.ROI),
+			formatOptionalCents(item.CostPerLeadCents),
+			formatOptionalCents(item.CostPerCustomerCents),
+		})
+	}
+}
+
+// GetAgentWorkload reports the open work per agent for workload balancing.
+func (h *Handler) GetAgentWorkload(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.WorkloadQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetAgentWorkload(c.Request.Context(), tenantID, query.StaleAfterDays, query.UpcomingDays, time.Now().UTC())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// GetCapacityPlan flags the weeks where accepted-quote demand exceeds partner capacity.
+func (h *Handler) GetCapacityPlan(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.CapacityPlanQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetCapacityPlan(c.Request.Context(), tenantID, query.HistoryWeeks, query.HorizonWeeks, time.Now().UTC())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// ImportMarketingCosts imports a Google Ads cost report sent as the CSV request body.
+func (h *Handler) ImportMarketingCosts(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	query, ok := httpkit.BindQuery[transport.ImportMarketingCostsQuery](c, h.val)
+	if !ok {
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxCostReportBytes)
+	resp, err := h.svc.ImportMarketingCosts(c.Request.Context(), tenantID, query.Source, body)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// parseRange converts the validated query dates; the inclusive "to" day becomes
+// an exclusive bound.
+func parseRange(query transport.ReportQuery) (time.Time, time.Time) {
+	var from, to time.Time
+	if query.From != "" {
+		from, _ = time.Parse(time.DateOnly, query.From)
+	}
+	if query.To != "" {
+		to, _ = time.Parse(time.DateOnly, query.To)
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to
+}
+
+func formatCents(cents int64) string {
+	return strconv.FormatFloat(float64(cents)/100, 'f', 2, 64)
+}
+
+func formatOptionalCents(cents *int64) string {
+	if cents == nil {
+		return ""
+	}
+	return formatCents(*cents)
+}
+
+func formatOptionalRatio(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 4, 64)
+}
@@ -0,0 +1,44 @@
+// Package analytics provides lead pipeline funnel reporting per organization,
+// served from daily aggregates that the scheduler refreshes, source ROI
+// reporting against imported marketing spend, and the open workload per agent.
+package analytics
+
+import (
+	"portal_final_backend/internal/analytics/handler"
+	"portal_final_backend/internal/analytics/repository"
+	"portal_final_backend/internal/analytics/service"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+}
+
+func NewModule(pool *pgxpool.Pool, cfg service.Config, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), cfg, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+	}
+}
+
+// Service returns the analytics service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "analytics"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterProtectedRoutes(ctx.Protected)
+	m.handler.RegisterAdminRoutes(ctx.Admin)
+}
+
+var _ apphttp.Module = (*Module)(nil)
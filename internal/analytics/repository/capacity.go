@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CapacityCell is one week of one service type in one region. Regions are the
+// first two digits of the postal code.
+type CapacityCell struct {
+	WeekStart   time.Time // Monday, as a UTC date
+	ServiceType string
+	Region      string
+}
+
+// CapacityDemand counts the quotes accepted in a capacity cell.
+type CapacityDemand struct {
+	CapacityCell
+	AcceptedQuotes int64
+	ValueCents     int64
+}
+
+// CapacitySupply counts the partner days available for jobs in a capacity
+// cell. A partner that offers several service types counts for each of them.
+type CapacitySupply struct {
+	CapacityCell
+	PartnerDays int64
+	Partners    int64
+}
+
+// ListAcceptedQuoteDemand counts accepted quotes per week, service type and
+// region of the lead, for Europe/Amsterdam days in [from, to).
+func (r *Repository) ListAcceptedQuoteDemand(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]CapacityDemand, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT date_trunc('week', (q.accepted_at AT TIME ZONE 'Europe/Amsterdam')::date)::date AS week_start,
+			COALESCE(st.name, 'unknown') AS service_type,
+			COALESCE(left(upper(regexp_replace(l.address_zip_code, '\s', '', 'g')), 2), '') AS region,
+			COUNT(*)::bigint,
+			COALESCE(SUM(q.total_cents), 0)::bigint
+		FROM RAC_quotes q
+		JOIN RAC_leads l ON l.id = q.lead_id AND l.organization_id = $1 AND l.deleted_at IS NULL
+		LEFT JOIN RAC_lead_services ls ON ls.id = q.lead_service_id
+		LEFT JOIN RAC_service_types st ON st.id = ls.service_type_id
+		WHERE q.organization_id = $1 AND q.status = 'Accepted' AND q.accepted_at IS NOT NULL
+			AND (q.accepted_at AT TIME ZONE 'Europe/Amsterdam')::date >= $2::date
+			AND (q.accepted_at AT TIME ZONE 'Europe/Amsterdam')::date < $3::date
+		GROUP BY 1, 2, 3`,
+		organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list accepted quote demand: %w", err)
+	}
+	defer rows.Close()
+
+	var demand []CapacityDemand
+	for rows.Next() {
+		var d CapacityDemand
+		if err := rows.Scan(&d.WeekStart, &d.ServiceType, &d.Region, &d.AcceptedQuotes, &d.ValueCents); err != nil {
+			return nil, fmt.Errorf("scan accepted quote demand: %w", err)
+		}
+		demand = append(demand, d)
+	}
+	return demand, rows.Err()
+}
+
+// ListPartnerJobCapacity counts, per week, service type and region of the
+// partner, the distinct days partners marked as available for jobs in their
+// open and accepted offers, for Europe/Amsterdam days in [from, to).
+func (r *Repository) ListPartnerJobCapacity(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]CapacitySupply, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH partner_days AS (
+			SELECT DISTINCT o.partner_id,
+				((slot->>'start')::timestamptz AT TIME ZONE 'Europe/Amsterdam')::date AS day
+			FROM RAC_partner_offers o
+			CROSS JOIN LATERAL jsonb_array_elements(o.job_availability) AS slot
+			WHERE o.organization_id = $1
+				AND o.status IN ('pending', 'sent', 'accepted')
+				AND jsonb_typeof(o.job_availability) = 'array'
+		)
+		SELECT date_trunc('week', pd.day)::date AS week_start,
+			st.name AS service_type,
+			left(upper(regexp_replace(p.postal_code, '\s', '', 'g')), 2) AS region,
+			COUNT(*)::bigint,
+			COUNT(DISTINCT pd.partner_id)::bigint
+		FROM partner_days pd
+		JOIN RAC_partners p ON p.id = pd.partner_id AND p.organization_id = $1
+		JOIN RAC_partner_service_types pst ON pst.partner_id = p.id
+		JOIN RAC_service_types st ON st.id = pst.service_type_id
+		WHERE pd.day >= $2::date AND pd.day < $3::date
+		GROUP BY 1, 2, 3`,
+		organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list partner job capacity: %w", err)
+	}
+	defer rows.Close()
+
+	var supply []CapacitySupply
+	for rows.Next() {
+		var s CapacitySupply
+		if err := rows.Scan(&s.WeekStart, &s.ServiceType, &s.Region, &s.PartnerDays, &s.Partners); err != nil {
+			return nil, fmt.Errorf("scan partner job capacity: %w", err)
+		}
+		supply = append(supply, s)
+	}
+	return supply, rows.Err()
+}
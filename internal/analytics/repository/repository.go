@@ -0,0 +1,361 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Dimensions a funnel breakdown can be grouped by.
+const (
+	DimensionServiceType = "service_type"
+	DimensionSource      = "source"
+	DimensionAgent       = "assigned_agent_id"
+)
+
+// Repository maintains and reads the daily funnel aggregates.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// FunnelCounts are the summed funnel counters of a group of aggregate rows.
+type FunnelCounts struct {
+	ServicesCreated         int64
+	ServicesWon             int64
+	ServicesLost            int64
+	QuotesIssued            int64
+	QuotesAccepted          int64
+	QuoteValueIssuedCents   int64
+	QuoteValueAcceptedCents int64
+}
+
+// FunnelGroup holds the counters of one breakdown key. Key is empty for rows
+// without a value, e.g. services without an assigned agent.
+type FunnelGroup struct {
+	Key string
+	FunnelCounts
+}
+
+// FunnelDay holds the counters of one calendar day.
+type FunnelDay struct {
+	Day time.Time
+	FunnelCounts
+}
+
+// StageTotals holds the entries and exits of a pipeline stage.
+type StageTotals struct {
+	Stage                 string
+	Entered               int64
+	Exited                int64
+	ExitedDurationSeconds int64
+}
+
+// RefreshState reports how far the aggregates have been refreshed.
+type RefreshState struct {
+	RefreshedThrough time.Time
+	RefreshedAt      time.Time
+}
+
+// The service dimensions shared by the funnel facts. Services of deleted leads
+// are left out of all analytics.
+const serviceDimensionsCTE = `
+	svc AS (
+		SELECT ls.id, ls.organization_id,
+			COALESCE(st.name, 'unknown') AS service_type,
+			COALESCE(NULLIF(ls.source, ''), NULLIF(l.source, ''), 'unknown') AS source,
+			l.assigned_agent_id,
+			ls.pipeline_stage::text AS pipeline_stage,
+			ls.created_at,
+			ls.updated_at
+		FROM RAC_lead_services ls
+		JOIN RAC_leads l ON l.id = ls.lead_id AND l.deleted_at IS NULL
+		LEFT JOIN RAC_service_types st ON st.id = ls.service_type_id
+	)`
+
+// Services are won or lost on the day they last entered their terminal stage,
+// so reopened services drop out of the outcome counts. Quotes are issued on
+// creation unless still a draft and accepted on their acceptance day.
+const refreshFunnelQuery = `
+	WITH` + serviceDimensionsCTE + `,
+	outcomes AS (
+		SELECT s.*, COALESCE((
+			SELECT MAX(e.occurred_at)
+			FROM RAC_lead_service_events e
+			WHERE e.lead_service_id = s.id
+				AND e.event_type = 'pipeline_stage_changed'
+				AND e.pipeline_stage = s.pipeline_stage
+		), s.updated_at) AS closed_at
+		FROM svc s
+		WHERE s.pipeline_stage IN ('Completed', 'Lost')
+	),
+	quotes AS (
+		SELECT q.organization_id,
+			COALESCE(s.service_type, 'unknown') AS service_type,
+			COALESCE(s.source, NULLIF(l.source, ''), 'unknown') AS source,
+			COALESCE(s.assigned_agent_id, l.assigned_agent_id) AS assigned_agent_id,
+			q.status::text AS status, q.total_cents, q.created_at, q.accepted_at
+		FROM RAC_quotes q
+		JOIN RAC_leads l ON l.id = q.lead_id AND l.deleted_at IS NULL
+		LEFT JOIN svc s ON s.id = q.lead_service_id
+	),
+	facts AS (
+		SELECT organization_id, (created_at AT TIME ZONE 'Europe/Amsterdam')::date AS day,
+			service_type, source, assigned_agent_id,
+			1 AS created, 0 AS won, 0 AS lost, 0 AS issued, 0 AS accepted, 0::bigint AS issued_cents, 0::bigint AS accepted_cents
+		FROM svc
+		UNION ALL
+		SELECT organization_id, (closed_at AT TIME ZONE 'Europe/Amsterdam')::date,
+			service_type, source, assigned_agent_id,
+			0, (pipeline_stage = 'Completed')::int, (pipeline_stage = 'Lost')::int, 0, 0, 0, 0
+		FROM outcomes
+		UNION ALL
+		SELECT organization_id, (created_at AT TIME ZONE 'Europe/Amsterdam')::date,
+			service_type, source, assigned_agent_id,
+			0, 0, 0, 1, 0, total_cents, 0
+		FROM quotes
+		WHERE status <> 'Draft'
+		UNION ALL
+		SELECT organization_id, (accepted_at AT TIME ZONE 'Europe/Amsterdam')::date,
+			service_type, source, assigned_agent_id,
+			0, 0, 0, 0, 1, 0, total_cents
+		FROM quotes
+		WHERE status = 'Accepted' AND accepted_at IS NOT NULL
+	)
+	INSERT INTO RAC_analytics_funnel_daily (
+		organization_id, day, service_type, source, assigned_agent_id,
+		services_created, services_won, services_lost, quotes_issued, quotes_accepted,
+		quote_value_issued_cents, quote_value_accepted_cents
+	)
+	SELECT organization_id, day, service_type, source, assigned_agent_id,
+		SUM(created), SUM(won), SUM(lost), SUM(issued), SUM(accepted),
+		SUM(issued_cents), SUM(accepted_cents)
+	FROM facts
+	WHERE $1::date IS NULL OR day >= $1::date
+	GROUP BY organization_id, day, service_type, source, assigned_agent_id`
+
+// A stay starts when a service enters a stage different from its previous one
+// and ends when the next stay starts. Services still in a stage have no exit.
+const refreshStagesQuery = `
+	WITH entries AS (
+		SELECT e.id, e.organization_id, e.lead_service_id, e.pipeline_stage, e.occurred_at,
+			LAG(e.pipeline_stage) OVER (PARTITION BY e.lead_service_id ORDER BY e.occurred_at, e.id) AS previous_stage
+		FROM RAC_lead_service_events e
+		JOIN RAC_leads l ON l.id = e.lead_id AND l.deleted_at IS NULL
+		WHERE e.event_type IN ('service_created', 'pipeline_stage_changed')
+			AND e.pipeline_stage IS NOT NULL
+	),
+	stays AS (
+		SELECT organization_id, pipeline_stage, occurred_at AS entered_at,
+			LEAD(occurred_at) OVER (PARTITION BY lead_service_id ORDER BY occurred_at, id) AS exited_at
+		FROM entries
+		WHERE previous_stage IS DISTINCT FROM pipeline_stage
+	),
+	facts AS (
+		SELECT organization_id, (entered_at AT TIME ZONE 'Europe/Amsterdam')::date AS day, pipeline_stage,
+			1 AS entered, 0 AS exited, 0::bigint AS duration_seconds
+		FROM stays
+		UNION ALL
+		SELECT organization_id, (exited_at AT TIME ZONE 'Europe/Amsterdam')::date, pipeline_stage,
+			0, 1, EXTRACT(EPOCH FROM exited_at - entered_at)::bigint
+		FROM stays
+		WHERE exited_at IS NOT NULL
+	)
+	INSERT INTO RAC_analytics_stage_daily (organization_id, day, pipeline_stage, entered_count, exited_count, exited_duration_seconds)
+	SELECT organization_id, day, pipeline_stage, SUM(entered), SUM(exited), SUM(duration_seconds)
+	FROM facts
+	WHERE $1::date IS NULL OR day >= $1::date
+	GROUP BY organization_id, day, pipeline_stage`
+
+// Refresh rebuilds the aggregates from lookbackDays before the last refreshed day
+// up to today, or the full history when the aggregates were never refreshed.
+// The rebuild runs in one transaction, so readers never see a partial refresh
+// and concurrent refreshes are serialized. Returns the first rebuilt day, nil for
+// a full rebuild.
+func (r *Repository) Refresh(ctx context.Context, lookbackDays int, today time.Time) (*time.Time, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin analytics refresh: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE RAC_analytics_refresh_state IN EXCLUSIVE MODE`); err != nil {
+		return nil, fmt.Errorf("lock analytics refresh state: %w", err)
+	}
+
+	var since *time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT refreshed_through - $1::int
+		FROM RAC_analytics_refresh_state`, lookbackDays,
+	).Scan(&since)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get analytics refresh state: %w", err)
+	}
+
+	for _, table := range []string{"RAC_analytics_funnel_daily", "RAC_analytics_stage_daily"} {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE $1::date IS NULL OR day >= $1::date`, since); err != nil {
+			return nil, fmt.Errorf("clear %s: %w", table, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, refreshFunnelQuery, since); err != nil {
+		return nil, fmt.Errorf("refresh funnel aggregates: %w", err)
+	}
+	if _, err := tx.Exec(ctx, refreshStagesQuery, since); err != nil {
+		return nil, fmt.Errorf("refresh stage aggregates: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_analytics_refresh_state (id, refreshed_through, refreshed_at)
+		VALUES (true, $1, now())
+		ON CONFLICT (id) DO UPDATE SET
+			refreshed_through = EXCLUDED.refreshed_through,
+			refreshed_at = EXCLUDED.refreshed_at`, today,
+	); err != nil {
+		return nil, fmt.Errorf("store analytics refresh state: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit analytics refresh: %w", err)
+	}
+	return since, nil
+}
+
+// GetRefreshState returns the last refresh, or nil when the aggregates were
+// never refreshed.
+func (r *Repository) GetRefreshState(ctx context.Context) (*RefreshState, error) {
+	var state RefreshState
+	err := r.pool.QueryRow(ctx, `
+		SELECT refreshed_through, refreshed_at
+		FROM RAC_analytics_refresh_state`,
+	).Scan(&state.RefreshedThrough, &state.RefreshedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get analytics refresh state: %w", err)
+	}
+	return &state, nil
+}
+
+const funnelCountColumns = `
+	COALESCE(SUM(services_created), 0)::bigint,
+	COALESCE(SUM(services_won), 0)::bigint,
+	COALESCE(SUM(services_lost), 0)::bigint,
+	COALESCE(SUM(quotes_issued), 0)::bigint,
+	COALESCE(SUM(quotes_accepted), 0)::bigint,
+	COALESCE(SUM(quote_value_issued_cents), 0)::bigint,
+	COALESCE(SUM(quote_value_accepted_cents), 0)::bigint`
+
+func (c *FunnelCounts) scanTargets() []any {
+	return []any{
+		&c.ServicesCreated, &c.ServicesWon, &c.ServicesLost, &c.QuotesIssued, &c.QuotesAccepted,
+		&c.QuoteValueIssuedCents, &c.QuoteValueAcceptedCents,
+	}
+}
+
+// GetFunnelTotals sums the funnel counters of an organization over [from, to).
+func (r *Repository) GetFunnelTotals(ctx context.Context, organizationID uuid.UUID, from, to time.Time) (FunnelCounts, error) {
+	var counts FunnelCounts
+	err := r.pool.QueryRow(ctx, `
+		SELECT`+funnelCountColumns+`
+		FROM RAC_analytics_funnel_daily
+		WHERE organization_id = $1 AND day >= $2 AND day < $3`, organizationID, from, to,
+	).Scan(counts.scanTargets()...)
+	if err != nil {
+		return FunnelCounts{}, fmt.Errorf("get funnel totals: %w", err)
+	}
+	return counts, nil
+}
+
+// ListFunnelGroups sums the funnel counters of an organization over [from, to)
+// per value of dimension, which must be one of the Dimension constants.
+func (r *Repository) ListFunnelGroups(ctx context.Context, organizationID uuid.UUID, dimension string, from, to time.Time) ([]FunnelGroup, error) {
+	switch dimension {
+	case DimensionServiceType, DimensionSource, DimensionAgent:
+	default:
+		return nil, fmt.Errorf("list funnel groups: unknown dimension %q", dimension)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT COALESCE(`+dimension+`::text, ''),`+funnelCountColumns+`
+		FROM RAC_analytics_funnel_daily
+		WHERE organization_id = $1 AND day >= $2 AND day < $3
+		GROUP BY 1
+		ORDER BY 1`, organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list funnel groups: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]FunnelGroup, 0)
+	for rows.Next() {
+		var g FunnelGroup
+		if err := rows.Scan(append([]any{&g.Key}, g.scanTargets()...)...); err != nil {
+			return nil, fmt.Errorf("scan funnel group: %w", err)
+		}
+		items = append(items, g)
+	}
+	return items, rows.Err()
+}
+
+// ListFunnelDays returns the funnel counters of an organization per day over
+// [from, to). Days without activity are omitted.
+func (r *Repository) ListFunnelDays(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]FunnelDay, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT day,`+funnelCountColumns+`
+		FROM RAC_analytics_funnel_daily
+		WHERE organization_id = $1 AND day >= $2 AND day < $3
+		GROUP BY day
+		ORDER BY day`, organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list funnel days: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]FunnelDay, 0)
+	for rows.Next() {
+		var d FunnelDay
+		if err := rows.Scan(append([]any{&d.Day}, d.scanTargets()...)...); err != nil {
+			return nil, fmt.Errorf("scan funnel day: %w", err)
+		}
+		items = append(items, d)
+	}
+	return items, rows.Err()
+}
+
+// ListStageTotals sums the stage entries and exits of an organization over [from, to).
+func (r *Repository) ListStageTotals(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]StageTotals, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT pipeline_stage,
+			COALESCE(SUM(entered_count), 0)::bigint,
+			COALESCE(SUM(exited_count), 0)::bigint,
+			COALESCE(SUM(exited_duration_seconds), 0)::bigint
+		FROM RAC_analytics_stage_daily
+		WHERE organization_id = $1 AND day >= $2 AND day < $3
+		GROUP BY pipeline_stage`, organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list stage totals: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]StageTotals, 0)
+	for rows.Next() {
+		var s StageTotals
+		if err := rows.Scan(&s.Stage, &s.Entered, &s.Exited, &s.ExitedDurationSeconds); err != nil {
+			return nil, fmt.Errorf("scan stage totals: %w", err)
+		}
+		items = append(items, s)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MarketingCost is the advertising spend of one source and campaign on a day.
+type MarketingCost struct {
+	Day         time.Time
+	Source      string
+	Campaign    string
+	CostCents   int64
+	Clicks      int64
+	Impressions int64
+}
+
+// SourceROI holds the cohort results and spend of one lead source.
+type SourceROI struct {
+	Source         string
+	Leads          int64
+	Customers      int64
+	QuotesAccepted int64
+	RevenueCents   int64
+	CostCents      int64
+	Clicks         int64
+}
+
+// UpsertMarketingCosts stores imported spend; rows of an earlier import for the
+// same day, source and campaign are replaced.
+func (r *Repository) UpsertMarketingCosts(ctx context.Context, organizationID uuid.UUID, costs []MarketingCost) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin import marketing costs: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, cost := range costs {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_marketing_costs (organization_id, day, source, campaign, cost_cents, clicks, impressions)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (organization_id, day, source, campaign) DO UPDATE SET
+				cost_cents = EXCLUDED.cost_cents,
+				clicks = EXCLUDED.clicks,
+				impressions = EXCLUDED.impressions,
+				imported_at = now()`,
+			organizationID, cost.Day, cost.Source, cost.Campaign, cost.CostCents, cost.Clicks, cost.Impressions,
+		); err != nil {
+			return fmt.Errorf("upsert marketing cost: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit import marketing costs: %w", err)
+	}
+	return nil
+}
+
+// ListSourceROI reports per source the leads created in [from, to), the accepted
+// quotes of those leads whenever they were accepted, and the spend in the same
+// days. Days are Europe/Amsterdam calendar days. Leads without UTM source but
+// with a Google click ID are attributed to google, which is also the source
+// Google Ads costs are imported under by default.
+func (r *Repository) ListSourceROI(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]SourceROI, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH cohort AS (
+			SELECT l.id,
+				CASE
+					WHEN NULLIF(TRIM(l.utm_source), '') IS NOT NULL THEN LOWER(TRIM(l.utm_source))
+					WHEN NULLIF(l.gclid, '') IS NOT NULL THEN 'google'
+					ELSE COALESCE(NULLIF(LOWER(TRIM(l.source)), ''), 'unknown')
+				END AS source
+			FROM RAC_leads l
+			WHERE l.organization_id = $1
+				AND l.deleted_at IS NULL
+				AND l.created_at >= ($2::date::timestamp AT TIME ZONE 'Europe/Amsterdam')
+				AND l.created_at < ($3::date::timestamp AT TIME ZONE 'Europe/Amsterdam')
+		),
+		accepted AS (
+			SELECT q.lead_id, COUNT(*) AS quotes, SUM(q.total_cents) AS revenue_cents
+			FROM RAC_quotes q
+			JOIN cohort c ON c.id = q.lead_id
+			WHERE q.organization_id = $1 AND q.status = 'Accepted'
+			GROUP BY q.lead_id
+		),
+		results AS (
+			SELECT c.source,
+				COUNT(*) AS leads,
+				COUNT(a.lead_id) AS customers,
+				COALESCE(SUM(a.quotes), 0) AS quotes_accepted,
+				COALESCE(SUM(a.revenue_cents), 0) AS revenue_cents
+			FROM cohort c
+			LEFT JOIN accepted a ON a.lead_id = c.id
+			GROUP BY c.source
+		),
+		costs AS (
+			SELECT source, SUM(cost_cents) AS cost_cents, SUM(clicks) AS clicks
+			FROM RAC_marketing_costs
+			WHERE organization_id = $1 AND day >= $2 AND day < $3
+			GROUP BY source
+		)
+		SELECT COALESCE(r.source, k.source),
+			COALESCE(r.leads, 0)::bigint,
+			COALESCE(r.customers, 0)::bigint,
+			COALESCE(r.quotes_accepted, 0)::bigint,
+			COALESCE(r.revenue_cents, 0)::bigint,
+			COALESCE(k.cost_cents, 0)::bigint,
+			COALESCE(k.clicks, 0)::bigint
+		FROM results r
+		FULL OUTER JOIN costs k ON k.source = r.source
+		ORDER BY 5 DESC, 1`, organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list source roi: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]SourceROI, 0)
+	for rows.Next() {
+		var s SourceROI
+		if err := rows.Scan(&s.Source, &s.Leads, &s.Customers, &s.QuotesAccepted, &s.RevenueCents, &s.CostCents, &s.Clicks); err != nil {
+			return nil, fmt.Errorf("scan source roi: %w", err)
+		}
+		items = append(items, s)
+	}
+	return items, rows.Err()
+}
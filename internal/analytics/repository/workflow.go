@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkflowStepStats counts the messages one workflow step sent and what the
+// recipients did afterwards. Opens and clicks are only known for tracked
+// emails; replies and quote conversions are attributed to a message when
+// they follow it within the given windows.
+type WorkflowStepStats struct {
+	WorkflowID     uuid.UUID
+	WorkflowName   string
+	StepID         uuid.UUID
+	Trigger        string
+	Channel        string
+	Sent           int64
+	Delivered      int64
+	Opened         int64
+	Clicked        int64
+	Replied        int64
+	QuotesViewed   int64
+	QuotesAccepted int64
+}
+
+// workflowMessageOutcomesSQL attributes delivery, engagement, replies and
+// quote conversions to the workflow messages of organization $1 enqueued in
+// [$2, $3), with reply window $4 and conversion window $5 in days. It defines
+// the CTE "attributed" with one row per message.
+const workflowMessageOutcomesSQL = `WITH messages AS (
+			SELECT wm.outbox_id, wm.workflow_id, wm.step_id, wm.lead_id, wm.channel, wm.trigger, wm.variant_key,
+				o.status, o.run_at
+			FROM RAC_workflow_messages wm
+			JOIN RAC_notification_outbox o ON o.id = wm.outbox_id
+			WHERE wm.organization_id = $1
+				AND wm.created_at >= ($2::date::timestamp AT TIME ZONE 'Europe/Amsterdam')
+				AND wm.created_at < ($3::date::timestamp AT TIME ZONE 'Europe/Amsterdam')
+		),
+		attributed AS (
+			SELECT m.*,
+				m.status = 'succeeded' AS delivered,
+				t.first_opened_at IS NOT NULL AS opened,
+				COALESCE(t.click_count, 0) > 0 AS clicked,
+				m.status = 'succeeded' AND m.lead_id IS NOT NULL AND (
+					EXISTS (
+						SELECT 1 FROM RAC_whatsapp_messages wa
+						WHERE wa.organization_id = $1 AND wa.lead_id = m.lead_id AND wa.direction = 'inbound'
+							AND wa.created_at > m.run_at AND wa.created_at <= m.run_at + make_interval(days => $4)
+					) OR EXISTS (
+						SELECT 1 FROM RAC_user_imap_message_leads ml
+						JOIN RAC_user_imap_messages im ON im.account_id = ml.account_id AND im.uid = ml.message_uid
+						WHERE ml.organization_id = $1 AND ml.lead_id = m.lead_id
+							AND im.received_at > m.run_at AND im.received_at <= m.run_at + make_interval(days => $4)
+					)
+				) AS replied,
+				m.status = 'succeeded' AND m.lead_id IS NOT NULL AND EXISTS (
+					SELECT 1 FROM RAC_quotes q
+					WHERE q.organization_id = $1 AND q.lead_id = m.lead_id
+						AND q.viewed_at > m.run_at AND q.viewed_at <= m.run_at + make_interval(days => $5)
+				) AS quote_viewed,
+				m.status = 'succeeded' AND m.lead_id IS NOT NULL AND EXISTS (
+					SELECT 1 FROM RAC_quotes q
+					WHERE q.organization_id = $1 AND q.lead_id = m.lead_id
+						AND q.accepted_at > m.run_at AND q.accepted_at <= m.run_at + make_interval(days => $5)
+				) AS quote_accepted
+			FROM messages m
+			LEFT JOIN RAC_notification_email_tracking t ON t.outbox_id = m.outbox_id
+		)`
+
+// workflowMessageCountsSQL aggregates "attributed" rows into the counters of
+// WorkflowStepStats, from Sent to QuotesAccepted.
+const workflowMessageCountsSQL = `COUNT(*) FILTER (WHERE a.status <> 'cancelled'),
+			COUNT(*) FILTER (WHERE a.delivered),
+			COUNT(*) FILTER (WHERE a.opened),
+			COUNT(*) FILTER (WHERE a.clicked),
+			COUNT(*) FILTER (WHERE a.replied),
+			COUNT(*) FILTER (WHERE a.quote_viewed),
+			COUNT(*) FILTER (WHERE a.quote_accepted)`
+
+// ListWorkflowStepStats reports per workflow step the messages enqueued in
+// [from, to) Europe/Amsterdam calendar days. A reply is an inbound WhatsApp
+// message or an inbox email linked to the lead within replyDays of the
+// message; a conversion is a quote of the lead viewed or accepted within
+// conversionDays.
+func (r *Repository) ListWorkflowStepStats(ctx context.Context, organizationID uuid.UUID, from, to time.Time, replyDays, conversionDays int) ([]WorkflowStepStats, error) {
+	rows, err := r.pool.Query(ctx, `
+		`+workflowMessageOutcomesSQL+`
+		SELECT a.workflow_id, COALESCE(w.name, ''), a.step_id, a.trigger, a.channel,
+			`+workflowMessageCountsSQL+`
+		FROM attributed a
+		LEFT JOIN RAC_workflows w ON w.id = a.workflow_id
+		GROUP BY a.workflow_id, w.name, a.step_id, a.trigger, a.channel
+		ORDER BY 2, 1, 4, 5`,
+		organizationID, from, to, replyDays, conversionDays,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow step stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]WorkflowStepStats, 0)
+	for rows.Next() {
+		var s WorkflowStepStats
+		if err := rows.Scan(&s.WorkflowID, &s.WorkflowName, &s.StepID, &s.Trigger, &s.Channel,
+			&s.Sent, &s.Delivered, &s.Opened, &s.Clicked, &s.Replied, &s.QuotesViewed, &s.QuotesAccepted); err != nil {
+			return nil, fmt.Errorf("scan workflow step stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// WorkflowVariantStats are the WorkflowStepStats of the messages one A/B
+// template variant of a step sent.
+type WorkflowVariantStats struct {
+	WorkflowStepStats
+	VariantKey string
+}
+
+// ListWorkflowVariantStats reports like ListWorkflowStepStats, per template
+// variant of the steps that ran an A/B test. Rows are ordered by workflow,
+// step and variant key.
+func (r *Repository) ListWorkflowVariantStats(ctx context.Context, organizationID uuid.UUID, from, to time.Time, replyDays, conversionDays int) ([]WorkflowVariantStats, error) {
+	rows, err := r.pool.Query(ctx, `
+		`+workflowMessageOutcomesSQL+`
+		SELECT a.workflow_id, COALESCE(w.name, ''), a.step_id, a.trigger, a.channel, a.variant_key,
+			`+workflowMessageCountsSQL+`
+		FROM attributed a
+		LEFT JOIN RAC_workflows w ON w.id = a.workflow_id
+		WHERE a.variant_key IS NOT NULL
+		GROUP BY a.workflow_id, w.name, a.step_id, a.trigger, a.channel, a.variant_key
+		ORDER BY 2, 1, 3, 6`,
+		organizationID, from, to, replyDays, conversionDays,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow variant stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]WorkflowVariantStats, 0)
+	for rows.Next() {
+		var s WorkflowVariantStats
+		if err := rows.Scan(&s.WorkflowID, &s.WorkflowName, &s.StepID, &s.Trigger, &s.Channel, &s.VariantKey,
+			&s.Sent, &s.Delivered, &s.Opened, &s.Clicked, &s.Replied, &s.QuotesViewed, &s.QuotesAccepted); err != nil {
+			return nil, fmt.Errorf("scan workflow variant stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StalenessBucketDays are the lower bounds, in days since the last touch, of
+// the staleness buckets open leads are counted in. The last bucket is open
+// ended.
+var StalenessBucketDays = []int{0, 3, 7, 14, 30}
+
+// WorkloadQuery selects the moments the workload report is measured against.
+type WorkloadQuery struct {
+	Now           time.Time
+	StaleBefore   time.Time // leads last touched before this are stale
+	UpcomingUntil time.Time // appointments starting in [Now, UpcomingUntil) are upcoming
+}
+
+// AgentWorkload is the open work of one agent. AgentID is nil for leads and
+// quotes without an assigned agent.
+type AgentWorkload struct {
+	AgentID                *uuid.UUID
+	Email                  *string
+	FirstName              *string
+	LastName               *string
+	OpenLeads              int64
+	StaleLeads             int64
+	OldestTouchAt          *time.Time
+	AverageTouchAgeSeconds float64
+	Staleness              []int64 // open leads per StalenessBucketDays bucket
+	UpcomingAppointments   int64
+	NextAppointmentAt      *time.Time
+	PendingQuotes          int64
+	SentQuotes             int64
+	PendingQuoteValueCents int64
+}
+
+// ListAgentWorkload reports per agent the open leads, upcoming appointments
+// and pending quotes of an organization. A lead is open while it has a service
+// that is not completed or lost; it was last touched at its latest timeline
+// event, or its creation when it has none. Every member of the organization
+// is listed, also without any open work.
+func (r *Repository) ListAgentWorkload(ctx context.Context, organizationID uuid.UUID, q WorkloadQuery) ([]AgentWorkload, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH open_leads AS (
+			SELECT l.id, l.assigned_agent_id AS agent_id,
+				COALESCE(t.last_touch_at, l.created_at) AS last_touch_at
+			FROM RAC_leads l
+			LEFT JOIN LATERAL (
+				SELECT te.created_at AS last_touch_at
+				FROM lead_timeline_events te
+				WHERE te.lead_id = l.id
+				ORDER BY te.created_at DESC
+				LIMIT 1
+			) t ON true
+			WHERE l.organization_id = $1
+				AND l.deleted_at IS NULL
+				AND EXISTS (
+					SELECT 1 FROM RAC_lead_services s
+					WHERE s.organization_id = $1 AND s.lead_id = l.id
+						AND s.pipeline_stage NOT IN ('Completed', 'Lost')
+				)
+		),
+		lead_stats AS (
+			SELECT agent_id,
+				COUNT(*) AS open_leads,
+				COUNT(*) FILTER (WHERE last_touch_at < $3) AS stale_leads,
+				MIN(last_touch_at) AS oldest_touch_at,
+				AVG(EXTRACT(EPOCH FROM ($2 - last_touch_at))) AS avg_touch_age_seconds,
+				COUNT(*) FILTER (WHERE last_touch_at > $2 - interval '3 days') AS bucket_0,
+				COUNT(*) FILTER (WHERE last_touch_at <= $2 - interval '3 days' AND last_touch_at > $2 - interval '7 days') AS bucket_3,
+				COUNT(*) FILTER (WHERE last_touch_at <= $2 - interval '7 days' AND last_touch_at > $2 - interval '14 days') AS bucket_7,
+				COUNT(*) FILTER (WHERE last_touch_at <= $2 - interval '14 days' AND last_touch_at > $2 - interval '30 days') AS bucket_14,
+				COUNT(*) FILTER (WHERE last_touch_at <= $2 - interval '30 days') AS bucket_30
+			FROM open_leads
+			GROUP BY agent_id
+		),
+		appointment_stats AS (
+			SELECT a.user_id AS agent_id, COUNT(*) AS upcoming, MIN(a.start_time) AS next_at
+			FROM RAC_appointments a
+			WHERE a.organization_id = $1
+				AND a.status = 'scheduled'
+				AND a.start_time >= $2 AND a.start_time < $4
+			GROUP BY a.user_id
+		),
+		quote_stats AS (
+			SELECT l.assigned_agent_id AS agent_id,
+				COUNT(*) AS pending,
+				COUNT(*) FILTER (WHERE q.status = 'Sent') AS sent,
+				COALESCE(SUM(q.total_cents), 0) AS value_cents
+			FROM RAC_quotes q
+			JOIN RAC_leads l ON l.id = q.lead_id AND l.organization_id = $1 AND l.deleted_at IS NULL
+			WHERE q.organization_id = $1 AND q.status IN ('Draft', 'Sent')
+			GROUP BY l.assigned_agent_id
+		),
+		agents AS (
+			SELECT om.user_id AS agent_id FROM RAC_organization_members om WHERE om.organization_id = $1
+			UNION SELECT agent_id FROM lead_stats
+			UNION SELECT agent_id FROM appointment_stats
+			UNION SELECT agent_id FROM quote_stats
+		)
+		SELECT ag.agent_id, u.email, u.first_name, u.last_name,
+			COALESCE(ls.open_leads, 0)::bigint,
+			COALESCE(ls.stale_leads, 0)::bigint,
+			ls.oldest_touch_at,
+			COALESCE(ls.avg_touch_age_seconds, 0)::float8,
+			COALESCE(ls.bucket_0, 0)::bigint,
+			COALESCE(ls.bucket_3, 0)::bigint,
+			COALESCE(ls.bucket_7, 0)::bigint,
+			COALESCE(ls.bucket_14, 0)::bigint,
+			COALESCE(ls.bucket_30, 0)::bigint,
+			COALESCE(aps.upcoming, 0)::bigint,
+			aps.next_at,
+			COALESCE(qs.pending, 0)::bigint,
+			COALESCE(qs.sent, 0)::bigint,
+			COALESCE(qs.value_cents, 0)::bigint
+		FROM agents ag
+		LEFT JOIN RAC_users u ON u.id = ag.agent_id
+		LEFT JOIN lead_stats ls ON ls.agent_id IS NOT DISTINCT FROM ag.agent_id
+		LEFT JOIN appointment_stats aps ON aps.agent_id IS NOT DISTINCT FROM ag.agent_id
+		LEFT JOIN quote_stats qs ON qs.agent_id IS NOT DISTINCT FROM ag.agent_id
+		ORDER BY ag.agent_id IS NULL, 5 DESC, u.email`,
+		organizationID, q.Now, q.StaleBefore, q.UpcomingUntil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list agent workload: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]AgentWorkload, 0)
+	for rows.Next() {
+		w := AgentWorkload{Staleness: make([]int64, len(StalenessBucketDays))}
+		if err := rows.Scan(
+			&w.AgentID, &w.Email, &w.FirstName, &w.LastName,
+			&w.OpenLeads, &w.StaleLeads, &w.OldestTouchAt, &w.AverageTouchAgeSeconds,
+			&w.Staleness[0], &w.Staleness[1], &w.Staleness[2], &w.Staleness[3], &w.Staleness[4],
+			&w.UpcomingAppointments, &w.NextAppointmentAt,
+			&w.PendingQuotes, &w.SentQuotes, &w.PendingQuoteValueCents,
+		); err != nil {
+			return nil, fmt.Errorf("scan agent workload: %w", err)
+		}
+		items = append(items, w)
+	}
+	return items, rows.Err()
+}
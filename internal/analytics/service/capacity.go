@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+	"portal_final_backend/internal/analytics/transport"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultCapacityHistoryWeeks = 4
+	defaultCapacityHorizonWeeks = 8
+)
+
+type capacityKey struct {
+	serviceType string
+	region      string
+}
+
+// GetCapacityPlan compares the accepted-quote volume per service type and
+// region with the partner days available for jobs, week by week. Past weeks
+// use the quotes accepted in them; the current and coming weeks expect at
+// least the average weekly volume of the past weeks. A cell is a bottleneck
+// when the expected jobs exceed the available partner days.
+func (s *Service) GetCapacityPlan(ctx context.Context, organizationID uuid.UUID, historyWeeks, horizonWeeks int, now time.Time) (transport.CapacityPlanResponse, error) {
+	if historyWeeks <= 0 {
+		historyWeeks = defaultCapacityHistoryWeeks
+	}
+	if horizonWeeks <= 0 {
+		horizonWeeks = defaultCapacityHorizonWeeks
+	}
+
+	currentWeek := weekStart(s.calendarDay(now))
+	from := currentWeek.AddDate(0, 0, -7*historyWeeks)
+	to := currentWeek.AddDate(0, 0, 7*horizonWeeks)
+
+	demand, err := s.repo.ListAcceptedQuoteDemand(ctx, organizationID, from, to)
+	if err != nil {
+		return transport.CapacityPlanResponse{}, err
+	}
+	supply, err := s.repo.ListPartnerJobCapacity(ctx, organizationID, from, to)
+	if err != nil {
+		return transport.CapacityPlanResponse{}, err
+	}
+
+	resp := buildCapacityPlan(demand, supply, from, currentWeek, historyWeeks, horizonWeeks)
+	resp.GeneratedAt = now
+	return resp, nil
+}
+
+func buildCapacityPlan(demand []repository.CapacityDemand, supply []repository.CapacitySupply, from, currentWeek time.Time, historyWeeks, horizonWeeks int) transport.CapacityPlanResponse {
+	type cellKey struct {
+		week string
+		key  capacityKey
+	}
+	demandByCell := make(map[cellKey]repository.CapacityDemand, len(demand))
+	supplyByCell := make(map[cellKey]repository.CapacitySupply, len(supply))
+	historyTotals := make(map[capacityKey]int64)
+	keys := make(map[capacityKey]struct{})
+
+	for _, d := range demand {
+		key := capacityKey{serviceType: d.ServiceType, region: d.Region}
+		demandByCell[cellKey{week: d.WeekStart.Format(time.DateOnly), key: key}] = d
+		keys[key] = struct{}{}
+		if d.WeekStart.Before(currentWeek) {
+			historyTotals[key] += d.AcceptedQuotes
+		}
+	}
+	for _, sup := range supply {
+		key := capacityKey{serviceType: sup.ServiceType, region: sup.Region}
+		supplyByCell[cellKey{week: sup.WeekStart.Format(time.DateOnly), key: key}] = sup
+		keys[key] = struct{}{}
+	}
+
+	sortedKeys := make([]capacityKey, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		if sortedKeys[i].serviceType != sortedKeys[j].serviceType {
+			return sortedKeys[i].serviceType < sortedKeys[j].serviceType
+		}
+		return sortedKeys[i].region < sortedKeys[j].region
+	})
+
+	resp := transport.CapacityPlanResponse{
+		HistoryWeeks: historyWeeks,
+		HorizonWeeks: horizonWeeks,
+		Weeks:        make([]transport.CapacityWeek, 0, historyWeeks+horizonWeeks),
+	}
+	for week := from; len(resp.Weeks) < historyWeeks+horizonWeeks; week = week.AddDate(0, 0, 7) {
+		weekKey := week.Format(time.DateOnly)
+		projected := !week.Before(currentWeek)
+		item := transport.CapacityWeek{
+			WeekStart: weekKey,
+			Projected: projected,
+			Cells:     make([]transport.CapacityCell, 0, len(sortedKeys)),
+		}
+		for _, key := range sortedKeys {
+			d := demandByCell[cellKey{week: weekKey, key: key}]
+			sup := supplyByCell[cellKey{week: weekKey, key: key}]
+			cell := transport.CapacityCell{
+				ServiceType:        key.serviceType,
+				Region:             key.region,
+				AcceptedQuotes:     d.AcceptedQuotes,
+				AcceptedValueCents: d.ValueCents,
+				ExpectedJobs:       float64(d.AcceptedQuotes),
+				PartnerDays:        sup.PartnerDays,
+				AvailablePartners:  sup.Partners,
+			}
+			if projected {
+				average := roundTenth(float64(historyTotals[key]) / float64(historyWeeks))
+				cell.ExpectedJobs = math.Max(cell.ExpectedJobs, average)
+			}
+			if cell.AcceptedQuotes == 0 && cell.ExpectedJobs == 0 && cell.PartnerDays == 0 {
+				continue
+			}
+			if shortfall := cell.ExpectedJobs - float64(cell.PartnerDays); shortfall > 0 {
+				cell.Bottleneck = true
+				cell.Shortfall = roundTenth(shortfall)
+				item.Bottlenecks++
+			}
+			item.ExpectedJobs += cell.ExpectedJobs
+			item.PartnerDays += cell.PartnerDays
+			item.Cells = append(item.Cells, cell)
+		}
+		item.ExpectedJobs = roundTenth(item.ExpectedJobs)
+		resp.Bottlenecks += item.Bottlenecks
+		resp.Weeks = append(resp.Weeks, item)
+	}
+	return resp
+}
+
+// weekStart returns the Monday of the week of a UTC date.
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func roundTenth(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+)
+
+func TestWeekStartReturnsMonday(t *testing.T) {
+	sunday := time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)
+	if got := weekStart(sunday); !got.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected monday 2026-03-16, got %s", got)
+	}
+}
+
+func TestBuildCapacityPlanFlagsProjectedShortfall(t *testing.T) {
+	currentWeek := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+	from := currentWeek.AddDate(0, 0, -14)
+	cell := func(week time.Time) repository.CapacityCell {
+		return repository.CapacityCell{WeekStart: week, ServiceType: "Dakkapel", Region: "35"}
+	}
+	demand := []repository.CapacityDemand{
+		{CapacityCell: cell(from), AcceptedQuotes: 4},
+		{CapacityCell: cell(from.AddDate(0, 0, 7)), AcceptedQuotes: 2},
+	}
+	supply := []repository.CapacitySupply{
+		{CapacityCell: cell(from), PartnerDays: 5, Partners: 2},
+		{CapacityCell: cell(currentWeek), PartnerDays: 1, Partners: 1},
+	}
+
+	plan := buildCapacityPlan(demand, supply, from, currentWeek, 2, 2)
+	if len(plan.Weeks) != 4 {
+		t.Fatalf("expected 4 weeks, got %d", len(plan.Weeks))
+	}
+	if plan.Weeks[0].Bottlenecks != 0 || plan.Weeks[1].Bottlenecks != 1 {
+		t.Fatalf("unexpected history bottlenecks %+v", plan.Weeks[:2])
+	}
+	current := plan.Weeks[2]
+	if !current.Projected || len(current.Cells) != 1 {
+		t.Fatalf("unexpected current week %+v", current)
+	}
+	if got := current.Cells[0]; got.ExpectedJobs != 3 || !got.Bottleneck || got.Shortfall != 2 {
+		t.Fatalf("expected average demand of 3 against 1 partner day, got %+v", got)
+	}
+	if plan.Bottlenecks != 3 {
+		t.Fatalf("expected 3 bottlenecks, got %d", plan.Bottlenecks)
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+	"portal_final_backend/internal/analytics/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// DefaultCostSource is the lead source Google Ads spend is attributed to.
+const DefaultCostSource = "google"
+
+const maxCostImportRows = 20000
+
+// costColumns maps the English and Dutch Google Ads report headers to fields.
+var costColumns = map[string]string{
+	"day":         "day",
+	"date":        "day",
+	"dag":         "day",
+	"datum":       "day",
+	"campaign":    "campaign",
+	"campagne":    "campaign",
+	"cost":        "cost",
+	"kosten":      "cost",
+	"clicks":      "clicks",
+	"klikken":     "clicks",
+	"impr.":       "impressions",
+	"impressions": "impressions",
+	"vertoningen": "impressions",
+}
+
+var costDayLayouts = []string{time.DateOnly, "02-01-2006", "Jan 2, 2006", "2 Jan 2006"}
+
+// ImportMarketingCosts parses a Google Ads cost report and stores the spend of
+// every row under source. Rows for the same day and campaign are summed.
+func (s *Service) ImportMarketingCosts(ctx context.Context, organizationID uuid.UUID, source string, r io.Reader) (transport.ImportMarketingCostsResponse, error) {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if source == "" {
+		source = DefaultCostSource
+	}
+
+	costs, err := parseCostReport(r)
+	if err != nil {
+		return transport.ImportMarketingCostsResponse{}, err
+	}
+	if len(costs) == 0 {
+		return transport.ImportMarketingCostsResponse{}, apperr.Validation("cost report contains no rows")
+	}
+
+	resp := transport.ImportMarketingCostsResponse{Source: source, Rows: len(costs)}
+	for i := range costs {
+		costs[i].Source = source
+		resp.TotalCostCents += costs[i].CostCents
+		day := costs[i].Day.Format(time.DateOnly)
+		if resp.From == "" || day < resp.From {
+			resp.From = day
+		}
+		if day > resp.To {
+			resp.To = day
+		}
+	}
+
+	if err := s.repo.UpsertMarketingCosts(ctx, organizationID, costs); err != nil {
+		return transport.ImportMarketingCostsResponse{}, err
+	}
+	return resp, nil
+}
+
+// parseCostReport reads a Google Ads report CSV. Title lines before the header
+// row and total rows are skipped.
+func parseCostReport(r io.Reader) ([]repository.MarketingCost, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var columns map[string]int
+	merged := make(map[string]int)
+	costs := make([]repository.MarketingCost, 0)
+	line := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, apperr.Validation(fmt.Sprintf("invalid cost report on line %d", line))
+		}
+
+		if columns == nil {
+			columns = costHeaderColumns(record)
+			continue
+		}
+
+		value := func(field string) string {
+			idx, ok := columns[field]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		rawDay := value("day")
+		if rawDay == "" || strings.HasPrefix(strings.ToLower(rawDay), "total") || strings.HasPrefix(strings.ToLower(rawDay), "totaal") {
+			continue
+		}
+		day, ok := parseCostDay(rawDay)
+		if !ok {
+			return nil, apperr.Validation(fmt.Sprintf("invalid day %q on line %d", rawDay, line))
+		}
+		cost, ok := parseAmountCents(value("cost"))
+		if !ok {
+			return nil, apperr.Validation(fmt.Sprintf("invalid cost %q on line %d", value("cost"), line))
+		}
+
+		campaign := value("campaign")
+		key := day.Format(time.DateOnly) + "\x00" + campaign
+		if idx, ok := merged[key]; ok {
+			costs[idx].CostCents += cost
+			costs[idx].Clicks += parseCount(value("clicks"))
+			costs[idx].Impressions += parseCount(value("impressions"))
+			continue
+		}
+		if len(costs) >= maxCostImportRows {
+			return nil, apperr.Validation("cost report has too many rows").WithDetails(map[string]int{"maxRows": maxCostImportRows})
+		}
+		merged[key] = len(costs)
+		costs = append(costs, repository.MarketingCost{
+			Day:         day,
+			Campaign:    campaign,
+			CostCents:   cost,
+			Clicks:      parseCount(value("clicks")),
+			Impressions: parseCount(value("impressions")),
+		})
+	}
+
+	if columns == nil {
+		return nil, apperr.Validation("cost report needs day and cost columns")
+	}
+	return costs, nil
+}
+
+// costHeaderColumns returns the column positions when record is the header row,
+// or nil when it is a title line.
+func costHeaderColumns(record []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := costColumns[name]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["day"]; !ok {
+		return nil
+	}
+	if _, ok := columns["cost"]; !ok {
+		return nil
+	}
+	return columns
+}
+
+func parseCostDay(value string) (time.Time, bool) {
+	for _, layout := range costDayLayouts {
+		if day, err := time.Parse(layout, value); err == nil {
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseAmountCents parses an amount in either "1,234.56" or "1.234,56" notation;
+// the last separator followed by one or two digits is the decimal separator.
+func parseAmountCents(value string) (int64, bool) {
+	value = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' || r == '-' {
+			return r
+		}
+		return -1
+	}, value)
+	if strings.Trim(value, "-") == "" {
+		// Google Ads reports "--" for days without spend.
+		return 0, true
+	}
+
+	integer, fraction := value, ""
+	if idx := strings.LastIndexAny(value, ".,"); idx >= 0 && len(value)-idx-1 <= 2 {
+		integer, fraction = value[:idx], value[idx+1:]
+	}
+	integer = strings.NewReplacer(".", "", ",", "").Replace(integer)
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	cents, err := strconv.ParseInt(integer+fraction, 10, 64)
+	if err != nil || cents < 0 {
+		return 0, false
+	}
+	return cents, true
+}
+
+func parseCount(value string) int64 {
+	value = strings.NewReplacer(".", "", ",", "", " ", "").Replace(value)
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAmountCents(t *testing.T) {
+	cases := map[string]int64{
+		"12.34":      1234,
+		"12,34":      1234,
+		"1,234.56":   123456,
+		"1.234,56":   123456,
+		"€ 1.234,5":  123450,
+		"1,234":      123400,
+		"1234":       123400,
+		"--":         0,
+		"":           0,
+		"EUR 0.99":   99,
+		"12.345.678": 1234567800,
+	}
+	for input, want := range cases {
+		got, ok := parseAmountCents(input)
+		if !ok {
+			t.Fatalf("parseAmountCents(%q) failed", input)
+		}
+		if got != want {
+			t.Fatalf("parseAmountCents(%q) = %d, want %d", input, got, want)
+		}
+	}
+
+	if _, ok := parseAmountCents("-5.00"); ok {
+		t.Fatal("parseAmountCents(-5.00): expected negative amount to be rejected")
+	}
+}
+
+func TestParseCostReportSkipsTitleAndTotalRows(t *testing.T) {
+	report := strings.Join([]string{
+		"Campaign report",
+		`"1 March 2026 - 2 March 2026"`,
+		"Day,Campaign,Clicks,Impr.,Cost",
+		"2026-03-01,Warmtepompen,10,200,12.50",
+		"2026-03-01,Warmtepompen,5,100,2.50",
+		`2026-03-02,Isolatie,3,50,"1,234.00"`,
+		"Total: Account,,18,350,1249.00",
+	}, "\n")
+
+	costs, err := parseCostReport(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("parseCostReport() error = %v", err)
+	}
+	if len(costs) != 2 {
+		t.Fatalf("parseCostReport() returned %d rows, want 2", len(costs))
+	}
+	if costs[0].Campaign != "Warmtepompen" || costs[0].CostCents != 1500 || costs[0].Clicks != 15 || costs[0].Impressions != 300 {
+		t.Fatalf("merged row = %+v", costs[0])
+	}
+	if costs[1].CostCents != 123400 || costs[1].Day.Format("2006-01-02") != "2026-03-02" {
+		t.Fatalf("second row = %+v", costs[1])
+	}
+}
+
+func TestParseCostReportRequiresHeader(t *testing.T) {
+	if _, err := parseCostReport(strings.NewReader("Campaign,Clicks\nWarmtepompen,10\n")); err == nil {
+		t.Fatal("parseCostReport() without day and cost columns: expected error")
+	}
+}
+
+func TestParseCostReportRejectsInvalidDay(t *testing.T) {
+	if _, err := parseCostReport(strings.NewReader("Dag,Kosten\nmorgen,1,00\n")); err == nil {
+		t.Fatal("parseCostReport() with invalid day: expected error")
+	}
+}
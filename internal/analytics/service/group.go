@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+	"portal_final_backend/internal/analytics/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// BranchLister returns the branches of a franchise head office.
+type BranchLister interface {
+	ListBranchIDs(ctx context.Context, parentID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// SetBranchLister enables the consolidated reports of head offices.
+func (s *Service) SetBranchLister(branches BranchLister) {
+	s.branches = branches
+}
+
+// GetGroupFunnel reports the funnel totals of a head office and its branches,
+// per organization and consolidated. Organizations without branches get their
+// own totals.
+func (s *Service) GetGroupFunnel(ctx context.Context, organizationID uuid.UUID, from, to time.Time) (transport.GroupFunnelResponse, error) {
+	from, to, err := s.resolveRange(from, to)
+	if err != nil {
+		return transport.GroupFunnelResponse{}, err
+	}
+	organizationIDs, err := s.groupOrganizations(ctx, organizationID)
+	if err != nil {
+		return transport.GroupFunnelResponse{}, err
+	}
+
+	var totals repository.FunnelCounts
+	items := make([]transport.GroupFunnelItem, 0, len(organizationIDs))
+	for _, id := range organizationIDs {
+		counts, err := s.repo.GetFunnelTotals(ctx, id, from, to)
+		if err != nil {
+			return transport.GroupFunnelResponse{}, err
+		}
+		totals = addFunnelCounts(totals, counts)
+		items = append(items, transport.GroupFunnelItem{
+			OrganizationID: id.String(),
+			HeadOffice:     id == organizationID,
+			FunnelCounts:   toFunnelCounts(counts),
+		})
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return transport.GroupFunnelResponse{}, err
+	}
+
+	return transport.GroupFunnelResponse{
+		From:          from.Format(time.DateOnly),
+		To:            to.AddDate(0, 0, -1).Format(time.DateOnly),
+		RefreshedAt:   refreshedAt,
+		Totals:        toFunnelCounts(totals),
+		Organizations: items,
+	}, nil
+}
+
+// GetGroupBreakdown reports the funnel counters of a head office and its
+// branches together per service type, source or agent.
+func (s *Service) GetGroupBreakdown(ctx context.Context, organizationID uuid.UUID, groupBy string, from, to time.Time) (transport.BreakdownResponse, error) {
+	dimension, ok := groupByDimensions[groupBy]
+	if !ok {
+		return transport.BreakdownResponse{}, apperr.Validation("unsupported groupBy")
+	}
+	from, to, err := s.resolveRange(from, to)
+	if err != nil {
+		return transport.BreakdownResponse{}, err
+	}
+	organizationIDs, err := s.groupOrganizations(ctx, organizationID)
+	if err != nil {
+		return transport.BreakdownResponse{}, err
+	}
+
+	var groups []repository.FunnelGroup
+	for _, id := range organizationIDs {
+		orgGroups, err := s.repo.ListFunnelGroups(ctx, id, dimension, from, to)
+		if err != nil {
+			return transport.BreakdownResponse{}, err
+		}
+		groups = append(groups, orgGroups...)
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return transport.BreakdownResponse{}, err
+	}
+
+	resp := transport.BreakdownResponse{
+		From:        from.Format(time.DateOnly),
+		To:          to.AddDate(0, 0, -1).Format(time.DateOnly),
+		GroupBy:     groupBy,
+		RefreshedAt: refreshedAt,
+	}
+	merged := mergeFunnelGroups(groups)
+	resp.Items = make([]transport.BreakdownItem, 0, len(merged))
+	for _, group := range merged {
+		resp.Items = append(resp.Items, transport.BreakdownItem{Key: group.Key, FunnelCounts: toFunnelCounts(group.FunnelCounts)})
+	}
+	return resp, nil
+}
+
+// groupOrganizations returns the organization followed by its branches.
+func (s *Service) groupOrganizations(ctx context.Context, organizationID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{organizationID}
+	if s.branches == nil {
+		return ids, nil
+	}
+	branchIDs, err := s.branches.ListBranchIDs(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return append(ids, branchIDs...), nil
+}
+
+// mergeFunnelGroups adds up the groups with the same key, ordered by key like
+// the breakdown of a single organization.
+func mergeFunnelGroups(groups []repository.FunnelGroup) []repository.FunnelGroup {
+	byKey := make(map[string]int, len(groups))
+	merged := make([]repository.FunnelGroup, 0, len(groups))
+	for _, group := range groups {
+		if i, ok := byKey[group.Key]; ok {
+			merged[i].FunnelCounts = addFunnelCounts(merged[i].FunnelCounts, group.FunnelCounts)
+			continue
+		}
+		byKey[group.Key] = len(merged)
+		merged = append(merged, group)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Key < merged[j].Key })
+	return merged
+}
+
+func addFunnelCounts(a, b repository.FunnelCounts) repository.FunnelCounts {
+	return repository.FunnelCounts{
+		ServicesCreated:         a.ServicesCreated + b.ServicesCreated,
+		ServicesWon:             a.ServicesWon + b.ServicesWon,
+		ServicesLost:            a.ServicesLost + b.ServicesLost,
+		QuotesIssued:            a.QuotesIssued + b.QuotesIssued,
+		QuotesAccepted:          a.QuotesAccepted + b.QuotesAccepted,
+		QuoteValueIssuedCents:   a.QuoteValueIssuedCents + b.QuoteValueIssuedCents,
+		QuoteValueAcceptedCents: a.QuoteValueAcceptedCents + b.QuoteValueAcceptedCents,
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/analytics/repository"
+)
+
+func TestMergeFunnelGroupsAddsUpBranches(t *testing.T) {
+	groups := []repository.FunnelGroup{
+		{Key: "website", FunnelCounts: repository.FunnelCounts{ServicesCreated: 4, ServicesWon: 1}},
+		{Key: "", FunnelCounts: repository.FunnelCounts{ServicesCreated: 2}},
+		{Key: "website", FunnelCounts: repository.FunnelCounts{ServicesCreated: 3, ServicesLost: 2}},
+	}
+
+	merged := mergeFunnelGroups(groups)
+	if len(merged) != 2 {
+		t.Fatalf("mergeFunnelGroups() returned %d groups, want 2", len(merged))
+	}
+	if merged[0].Key != "" || merged[1].Key != "website" {
+		t.Fatalf("mergeFunnelGroups() keys = %q, %q, want sorted by key", merged[0].Key, merged[1].Key)
+	}
+	website := merged[1].FunnelCounts
+	if website.ServicesCreated != 7 || website.ServicesWon != 1 || website.ServicesLost != 2 {
+		t.Fatalf("mergeFunnelGroups() website = %+v", website)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"portal_final_backend/internal/analytics/transport"
+
+	"github.com/google/uuid"
+)
+
+// GetSourceROI reports per lead source the revenue of the leads created in
+// [from, to) against the spend in the same days.
+func (s *Service) GetSourceROI(ctx context.Context, organizationID uuid.UUID, from, to time.Time) (transport.SourceROIResponse, error) {
+	from, to, err := s.resolveRange(from, to)
+	if err != nil {
+		return transport.SourceROIResponse{}, err
+	}
+
+	sources, err := s.repo.ListSourceROI(ctx, organizationID, from, to)
+	if err != nil {
+		return transport.SourceROIResponse{}, err
+	}
+
+	resp := transport.SourceROIResponse{
+		From:    from.Format(time.DateOnly),
+		To:      to.AddDate(0, 0, -1).Format(time.DateOnly),
+		Sources: make([]transport.SourceROIItem, 0, len(sources)),
+	}
+	for _, source := range sources {
+		item := transport.SourceROIItem{
+			Source:         source.Source,
+			Leads:          source.Leads,
+			Customers:      source.Customers,
+			ConversionRate: rate(source.Customers, source.Leads),
+			QuotesAccepted: source.QuotesAccepted,
+			RevenueCents:   source.RevenueCents,
+			CostCents:      source.CostCents,
+			Clicks:         source.Clicks,
+		}
+		if source.CostCents > 0 {
+			roi := float64(source.RevenueCents-source.CostCents) / float64(source.CostCents)
+			item.ROI = &roi
+			if source.Leads > 0 {
+				cpl := source.CostCents / source.Leads
+				item.CostPerLeadCents = &cpl
+			}
+			if source.Customers > 0 {
+				cpa := source.CostCents / source.Customers
+				item.CostPerCustomerCents = &cpa
+			}
+		}
+
+		resp.Totals.Leads += item.Leads
+		resp.Totals.Customers += item.Customers
+		resp.Totals.QuotesAccepted += item.QuotesAccepted
+		resp.Totals.RevenueCents += item.RevenueCents
+		resp.Totals.CostCents += item.CostCents
+		resp.Totals.Clicks += item.Clicks
+		resp.Sources = append(resp.Sources, item)
+	}
+	resp.Totals.Source = "total"
+	resp.Totals.ConversionRate = rate(resp.Totals.Customers, resp.Totals.Leads)
+	if resp.Totals.CostCents > 0 {
+		roi := float64(resp.Totals.RevenueCents-resp.Totals.CostCents) / float64(resp.Totals.CostCents)
+		resp.Totals.ROI = &roi
+	}
+	return resp, nil
+}
@@ -0,0 +1,278 @@
+// Package service implements the lead pipeline funnel reports on top of the
+// daily aggregates refreshed by the scheduler, and the source ROI reports.
+package service
+
+import (
+	"context"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+	"portal_final_backend/internal/analytics/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/timekit"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultRefreshLookbackDays covers quotes that are sent or accepted and
+	// services that close some weeks after the day they are counted on.
+	DefaultRefreshLookbackDays = 35
+	defaultReportDays          = 30
+	maxReportDays              = 366
+)
+
+// stageOrder lists the pipeline stages in funnel order; unknown stages are
+// appended after them.
+var stageOrder = []string{
+	"Triage",
+	"Nurturing",
+	"Estimation",
+	"Proposal",
+	"Fulfillment",
+	"Completed",
+	"Lost",
+	"Manual_Intervention",
+}
+
+// groupByDimensions maps the breakdown query values to aggregate dimensions.
+var groupByDimensions = map[string]string{
+	"serviceType": repository.DimensionServiceType,
+	"source":      repository.DimensionSource,
+	"agent":       repository.DimensionAgent,
+}
+
+type Repository interface {
+	Refresh(ctx context.Context, lookbackDays int, today time.Time) (*time.Time, error)
+	GetRefreshState(ctx context.Context) (*repository.RefreshState, error)
+	GetFunnelTotals(ctx context.Context, organizationID uuid.UUID, from, to time.Time) (repository.FunnelCounts, error)
+	ListFunnelGroups(ctx context.Context, organizationID uuid.UUID, dimension string, from, to time.Time) ([]repository.FunnelGroup, error)
+	ListFunnelDays(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]repository.FunnelDay, error)
+	ListStageTotals(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]repository.StageTotals, error)
+	UpsertMarketingCosts(ctx context.Context, organizationID uuid.UUID, costs []repository.MarketingCost) error
+	ListSourceROI(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]repository.SourceROI, error)
+	ListAgentWorkload(ctx context.Context, organizationID uuid.UUID, q repository.WorkloadQuery) ([]repository.AgentWorkload, error)
+	ListAcceptedQuoteDemand(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]repository.CapacityDemand, error)
+	ListPartnerJobCapacity(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]repository.CapacitySupply, error)
+	ListWorkflowStepStats(ctx context.Context, organizationID uuid.UUID, from, to time.Time, replyDays, conversionDays int) ([]repository.WorkflowStepStats, error)
+	ListWorkflowVariantStats(ctx context.Context, organizationID uuid.UUID, from, to time.Time, replyDays, conversionDays int) ([]repository.WorkflowVariantStats, error)
+}
+
+// Config controls the aggregate refresh. The lookback is the number of days
+// before the last refreshed day that every refresh rebuilds.
+type Config struct {
+	RefreshLookbackDays int
+}
+
+type Service struct {
+	repo     Repository
+	cfg      Config
+	loc      *time.Location
+	log      *logger.Logger
+	branches BranchLister
+}
+
+func New(repo Repository, cfg Config, log *logger.Logger) *Service {
+	if cfg.RefreshLookbackDays <= 0 {
+		cfg.RefreshLookbackDays = DefaultRefreshLookbackDays
+	}
+	return &Service{repo: repo, cfg: cfg, loc: timekit.ResolveLocation("Europe/Amsterdam"), log: log}
+}
+
+// Refresh rebuilds the recent daily aggregates, or all of them on the first run.
+func (s *Service) Refresh(ctx context.Context, now time.Time) error {
+	since, err := s.repo.Refresh(ctx, s.cfg.RefreshLookbackDays, s.calendarDay(now))
+	if err != nil {
+		return err
+	}
+	if since == nil {
+		s.log.Info("analytics refresh: rebuilt full history")
+	}
+	return nil
+}
+
+// GetFunnel reports the funnel totals and stage traffic of an organization.
+// Days are Europe/Amsterdam calendar days in [from, to); zero values select the
+// default range.
+func (s *Service) GetFunnel(ctx context.Context, organizationID uuid.UUID, from, to time.Time) (transport.FunnelResponse, error) {
+	from, to, err := s.resolveRange(from, to)
+	if err != nil {
+		return transport.FunnelResponse{}, err
+	}
+
+	totals, err := s.repo.GetFunnelTotals(ctx, organizationID, from, to)
+	if err != nil {
+		return transport.FunnelResponse{}, err
+	}
+	stages, err := s.repo.ListStageTotals(ctx, organizationID, from, to)
+	if err != nil {
+		return transport.FunnelResponse{}, err
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return transport.FunnelResponse{}, err
+	}
+
+	resp := transport.FunnelResponse{
+		From:        from.Format(time.DateOnly),
+		To:          to.AddDate(0, 0, -1).Format(time.DateOnly),
+		RefreshedAt: refreshedAt,
+		Totals:      toFunnelCounts(totals),
+		Stages:      make([]transport.StageResponse, 0, len(stages)),
+	}
+	for _, stage := range sortStages(stages) {
+		item := transport.StageResponse{
+			Stage:          stage.Stage,
+			Entered:        stage.Entered,
+			Exited:         stage.Exited,
+			ConversionRate: rate(stage.Entered, totals.ServicesCreated),
+		}
+		if stage.Exited > 0 {
+			item.AverageTimeInStageSeconds = stage.ExitedDurationSeconds / stage.Exited
+		}
+		resp.Stages = append(resp.Stages, item)
+	}
+	return resp, nil
+}
+
+// GetBreakdown reports the funnel counters and win rates of an organization per
+// service type, source or agent.
+func (s *Service) GetBreakdown(ctx context.Context, organizationID uuid.UUID, groupBy string, from, to time.Time) (transport.BreakdownResponse, error) {
+	dimension, ok := groupByDimensions[groupBy]
+	if !ok {
+		return transport.BreakdownResponse{}, apperr.Validation("unsupported groupBy")
+	}
+	from, to, err := s.resolveRange(from, to)
+	if err != nil {
+		return transport.BreakdownResponse{}, err
+	}
+
+	groups, err := s.repo.ListFunnelGroups(ctx, organizationID, dimension, from, to)
+	if err != nil {
+		return transport.BreakdownResponse{}, err
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return transport.BreakdownResponse{}, err
+	}
+
+	resp := transport.BreakdownResponse{
+		From:        from.Format(time.DateOnly),
+		To:          to.AddDate(0, 0, -1).Format(time.DateOnly),
+		GroupBy:     groupBy,
+		RefreshedAt: refreshedAt,
+		Items:       make([]transport.BreakdownItem, 0, len(groups)),
+	}
+	for _, group := range groups {
+		resp.Items = append(resp.Items, transport.BreakdownItem{Key: group.Key, FunnelCounts: toFunnelCounts(group.FunnelCounts)})
+	}
+	return resp, nil
+}
+
+// GetDaily returns the per-day funnel counters of an organization for charts.
+func (s *Service) GetDaily(ctx context.Context, organizationID uuid.UUID, from, to time.Time) (transport.DailyResponse, error) {
+	from, to, err := s.resolveRange(from, to)
+	if err != nil {
+		return transport.DailyResponse{}, err
+	}
+
+	days, err := s.repo.ListFunnelDays(ctx, organizationID, from, to)
+	if err != nil {
+		return transport.DailyResponse{}, err
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return transport.DailyResponse{}, err
+	}
+
+	resp := transport.DailyResponse{
+		From:        from.Format(time.DateOnly),
+		To:          to.AddDate(0, 0, -1).Format(time.DateOnly),
+		RefreshedAt: refreshedAt,
+		Days:        make([]transport.DailyItem, 0, len(days)),
+	}
+	for _, day := range days {
+		resp.Days = append(resp.Days, transport.DailyItem{Date: day.Day.Format(time.DateOnly), FunnelCounts: toFunnelCounts(day.FunnelCounts)})
+	}
+	return resp, nil
+}
+
+func (s *Service) refreshedAt(ctx context.Context) (*time.Time, error) {
+	state, err := s.repo.GetRefreshState(ctx)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	return &state.RefreshedAt, nil
+}
+
+// resolveRange applies the default range and validates its length. Bounds are
+// dates at UTC midnight, matching how the aggregate days are read back.
+func (s *Service) resolveRange(from, to time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = s.calendarDay(time.Now()).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -defaultReportDays)
+	}
+	from, to = dateOnly(from), dateOnly(to)
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, apperr.Validation("from must be before to")
+	}
+	if to.Sub(from) > maxReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, apperr.Validation("report range is too long").WithDetails(map[string]int{"maxDays": maxReportDays})
+	}
+	return from, to, nil
+}
+
+// calendarDay returns the Europe/Amsterdam calendar day of t as a UTC date.
+func (s *Service) calendarDay(t time.Time) time.Time {
+	local := t.In(s.loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func sortStages(stages []repository.StageTotals) []repository.StageTotals {
+	byStage := make(map[string]repository.StageTotals, len(stages))
+	for _, stage := range stages {
+		byStage[stage.Stage] = stage
+	}
+
+	sorted := make([]repository.StageTotals, 0, len(stages))
+	for _, name := range stageOrder {
+		if stage, ok := byStage[name]; ok {
+			sorted = append(sorted, stage)
+			delete(byStage, name)
+		}
+	}
+	for _, stage := range stages {
+		if _, ok := byStage[stage.Stage]; ok {
+			sorted = append(sorted, stage)
+		}
+	}
+	return sorted
+}
+
+func toFunnelCounts(c repository.FunnelCounts) transport.FunnelCounts {
+	return transport.FunnelCounts{
+		ServicesCreated:         c.ServicesCreated,
+		ServicesWon:             c.ServicesWon,
+		ServicesLost:            c.ServicesLost,
+		WinRate:                 rate(c.ServicesWon, c.ServicesWon+c.ServicesLost),
+		QuotesIssued:            c.QuotesIssued,
+		QuotesAccepted:          c.QuotesAccepted,
+		QuoteAcceptanceRate:     rate(c.QuotesAccepted, c.QuotesIssued),
+		QuoteValueIssuedCents:   c.QuoteValueIssuedCents,
+		QuoteValueAcceptedCents: c.QuoteValueAcceptedCents,
+	}
+}
+
+func rate(part, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+)
+
+func TestResolveRangeDefaultsToLastThirtyDays(t *testing.T) {
+	svc := New(nil, Config{}, nil)
+
+	from, to, err := svc.resolveRange(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("resolveRange() error = %v", err)
+	}
+	if got := to.Sub(from); got != defaultReportDays*24*time.Hour {
+		t.Fatalf("resolveRange() span = %s, want %d days", got, defaultReportDays)
+	}
+	if to.Location() != time.UTC || to.Hour() != 0 {
+		t.Fatalf("resolveRange() to = %s, want a UTC date", to)
+	}
+}
+
+func TestResolveRangeRejectsInvalidRanges(t *testing.T) {
+	svc := New(nil, Config{}, nil)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, _, err := svc.resolveRange(day, day); err == nil {
+		t.Fatal("resolveRange() with empty range: expected error")
+	}
+	if _, _, err := svc.resolveRange(day, day.AddDate(0, 0, maxReportDays+1)); err == nil {
+		t.Fatal("resolveRange() with too long range: expected error")
+	}
+}
+
+func TestCalendarDayUsesAmsterdamTime(t *testing.T) {
+	svc := New(nil, Config{}, nil)
+
+	// 23:30 UTC on 31 March is already 1 April in Amsterdam (CEST).
+	got := svc.calendarDay(time.Date(2026, 3, 31, 23, 30, 0, 0, time.UTC))
+	want := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("calendarDay() = %s, want %s", got, want)
+	}
+}
+
+func TestSortStagesKeepsFunnelOrder(t *testing.T) {
+	stages := []repository.StageTotals{
+		{Stage: "Lost"},
+		{Stage: "Custom"},
+		{Stage: "Triage"},
+		{Stage: "Proposal"},
+	}
+
+	sorted := sortStages(stages)
+	want := []string{"Triage", "Proposal", "Lost", "Custom"}
+	if len(sorted) != len(want) {
+		t.Fatalf("sortStages() returned %d stages, want %d", len(sorted), len(want))
+	}
+	for i, stage := range sorted {
+		if stage.Stage != want[i] {
+			t.Fatalf("sortStages()[%d] = %s, want %s", i, stage.Stage, want[i])
+		}
+	}
+}
+
+func TestToFunnelCountsRates(t *testing.T) {
+	counts := toFunnelCounts(repository.FunnelCounts{ServicesWon: 3, ServicesLost: 1, QuotesIssued: 4, QuotesAccepted: 1})
+	if counts.WinRate != 0.75 {
+		t.Fatalf("WinRate = %v, want 0.75", counts.WinRate)
+	}
+	if counts.QuoteAcceptanceRate != 0.25 {
+		t.Fatalf("QuoteAcceptanceRate = %v, want 0.25", counts.QuoteAcceptanceRate)
+	}
+
+	empty := toFunnelCounts(repository.FunnelCounts{})
+	if empty.WinRate != 0 || empty.QuoteAcceptanceRate != 0 {
+		t.Fatalf("rates without outcomes = %v/%v, want 0", empty.WinRate, empty.QuoteAcceptanceRate)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+	"portal_final_backend/internal/analytics/transport"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultReplyWindowDays      = 7
+	defaultConversionWindowDays = 14
+)
+
+// GetWorkflowAnalytics reports per workflow and step the messages sent in
+// [from, to): their delivery, email opens and clicks, and the replies and
+// quote conversions that followed them within the attribution windows.
+func (s *Service) GetWorkflowAnalytics(ctx context.Context, organizationID uuid.UUID, from, to time.Time, replyDays, conversionDays int) (transport.WorkflowAnalyticsResponse, error) {
+	from, to, err := s.resolveRange(from, to)
+	if err != nil {
+		return transport.WorkflowAnalyticsResponse{}, err
+	}
+	replyDays, conversionDays = workflowAttributionWindows(replyDays, conversionDays)
+
+	stats, err := s.repo.ListWorkflowStepStats(ctx, organizationID, from, to, replyDays, conversionDays)
+	if err != nil {
+		return transport.WorkflowAnalyticsResponse{}, err
+	}
+
+	resp := buildWorkflowAnalytics(stats)
+	resp.From = from.Format(time.DateOnly)
+	resp.To = to.AddDate(0, 0, -1).Format(time.DateOnly)
+	resp.ReplyWindowDays = replyDays
+	resp.ConversionWindowDays = conversionDays
+	return resp, nil
+}
+
+// workflowAttributionWindows applies the default reply and conversion windows
+// to the ones not given.
+func workflowAttributionWindows(replyDays, conversionDays int) (int, int) {
+	if replyDays <= 0 {
+		replyDays = defaultReplyWindowDays
+	}
+	if conversionDays <= 0 {
+		conversionDays = defaultConversionWindowDays
+	}
+	return replyDays, conversionDays
+}
+
+// buildWorkflowAnalytics groups the step stats, which arrive ordered by
+// workflow, into workflows and totals.
+func buildWorkflowAnalytics(stats []repository.WorkflowStepStats) transport.WorkflowAnalyticsResponse {
+	resp := transport.WorkflowAnalyticsResponse{Workflows: make([]transport.WorkflowAnalyticsItem, 0)}
+	var totals repository.WorkflowStepStats
+	var workflow *transport.WorkflowAnalyticsItem
+	var workflowTotals repository.WorkflowStepStats
+	flush := func() {
+		if workflow != nil {
+			workflow.WorkflowMessageCounts = toWorkflowMessageCounts(workflowTotals)
+			resp.Workflows = append(resp.Workflows, *workflow)
+		}
+	}
+
+	for _, step := range stats {
+		if workflow == nil || workflow.WorkflowID != step.WorkflowID.String() {
+			flush()
+			workflow = &transport.WorkflowAnalyticsItem{
+				WorkflowID: step.WorkflowID.String(),
+				Name:       step.WorkflowName,
+				Steps:      make([]transport.WorkflowStepAnalytics, 0),
+			}
+			workflowTotals = repository.WorkflowStepStats{}
+		}
+		workflow.Steps = append(workflow.Steps, transport.WorkflowStepAnalytics{
+			StepID:                step.StepID.String(),
+			Trigger:               step.Trigger,
+			Channel:               step.Channel,
+			WorkflowMessageCounts: toWorkflowMessageCounts(step),
+		})
+		addWorkflowStats(&workflowTotals, step)
+		addWorkflowStats(&totals, step)
+	}
+	flush()
+	resp.Totals = toWorkflowMessageCounts(totals)
+	return resp
+}
+
+func addWorkflowStats(total *repository.WorkflowStepStats, step repository.WorkflowStepStats) {
+	total.Sent += step.Sent
+	total.Delivered += step.Delivered
+	total.Opened += step.Opened
+	total.Clicked += step.Clicked
+	total.Replied += step.Replied
+	total.QuotesViewed += step.QuotesViewed
+	total.QuotesAccepted += step.QuotesAccepted
+}
+
+func toWorkflowMessageCounts(s repository.WorkflowStepStats) transport.WorkflowMessageCounts {
+	return transport.WorkflowMessageCounts{
+		Sent:           s.Sent,
+		Delivered:      s.Delivered,
+		Opened:         s.Opened,
+		Clicked:        s.Clicked,
+		Replied:        s.Replied,
+		QuotesViewed:   s.QuotesViewed,
+		QuotesAccepted: s.QuotesAccepted,
+		DeliveryRate:   rate(s.Delivered, s.Sent),
+		OpenRate:       rate(s.Opened, s.Delivered),
+		ClickRate:      rate(s.Clicked, s.Delivered),
+		ReplyRate:      rate(s.Replied, s.Delivered),
+		ConversionRate: rate(s.QuotesAccepted, s.Delivered),
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/analytics/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildWorkflowAnalyticsGroupsStepsPerWorkflow(t *testing.T) {
+	welcome, reminder := uuid.New(), uuid.New()
+	stats := []repository.WorkflowStepStats{
+		{WorkflowID: welcome, WorkflowName: "Welkom", StepID: uuid.New(), Channel: "email", Sent: 10, Delivered: 8, Opened: 4, Clicked: 2},
+		{WorkflowID: welcome, WorkflowName: "Welkom", StepID: uuid.New(), Channel: "whatsapp", Sent: 6, Delivered: 6, Replied: 3},
+		{WorkflowID: reminder, WorkflowName: "Herinnering", StepID: uuid.New(), Channel: "email", Sent: 4, Delivered: 4, QuotesAccepted: 1},
+	}
+
+	resp := buildWorkflowAnalytics(stats)
+	if len(resp.Workflows) != 2 {
+		t.Fatalf("buildWorkflowAnalytics() returned %d workflows, want 2", len(resp.Workflows))
+	}
+	first := resp.Workflows[0]
+	if len(first.Steps) != 2 || first.Sent != 16 || first.Delivered != 14 || first.Replied != 3 {
+		t.Fatalf("buildWorkflowAnalytics() first workflow = %+v", first)
+	}
+	if first.Steps[0].OpenRate != 0.5 {
+		t.Fatalf("email step open rate = %v, want 0.5", first.Steps[0].OpenRate)
+	}
+	if resp.Totals.Sent != 20 || resp.Totals.QuotesAccepted != 1 || resp.Totals.ConversionRate != 1.0/18 {
+		t.Fatalf("buildWorkflowAnalytics() totals = %+v", resp.Totals)
+	}
+}
+
+func TestBuildWorkflowVariantAnalyticsComparesWithBaseline(t *testing.T) {
+	workflowID, stepID := uuid.New(), uuid.New()
+	step := repository.WorkflowStepStats{WorkflowID: workflowID, WorkflowName: "Offerte opvolging", StepID: stepID, Channel: "email"}
+	a, b := step, step
+	a.Sent, a.Delivered, a.Opened, a.Replied, a.QuotesAccepted = 200, 200, 60, 20, 10
+	b.Sent, b.Delivered, b.Opened, b.Replied, b.QuotesAccepted = 200, 200, 100, 22, 10
+	stats := []repository.WorkflowVariantStats{
+		{WorkflowStepStats: a, VariantKey: "A"},
+		{WorkflowStepStats: b, VariantKey: "B"},
+	}
+
+	resp := buildWorkflowVariantAnalytics(stats)
+	if len(resp.Tests) != 1 || len(resp.Tests[0].Variants) != 2 {
+		t.Fatalf("buildWorkflowVariantAnalytics() = %+v, want one test with two variants", resp)
+	}
+	if got := resp.Tests[0].Variants[0].Comparisons; len(got) != 0 {
+		t.Fatalf("baseline comparisons = %+v, want none", got)
+	}
+	comparisons := resp.Tests[0].Variants[1].Comparisons
+	hints := make(map[string]string, len(comparisons))
+	for _, c := range comparisons {
+		hints[c.Metric] = c.Hint
+	}
+	if hints["openRate"] != variantHintSignificant || hints["replyRate"] != variantHintNotSignificant || hints["conversionRate"] != variantHintNotSignificant {
+		t.Fatalf("variant B hints = %v", hints)
+	}
+}
+
+func TestCompareProportionsNeedsMinimumSample(t *testing.T) {
+	got := compareProportions("replyRate", "A", 1, 10, 8, 10)
+	if got.Hint != variantHintInsufficientData || got.PValue != 1 || got.Lift != 7 {
+		t.Fatalf("compareProportions() = %+v, want insufficient data with lift 7", got)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+	"portal_final_backend/internal/analytics/transport"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// minVariantSampleSize is the number of delivered messages each variant
+	// needs before a comparison is tested for significance.
+	minVariantSampleSize = 30
+	// variantSignificanceLevel is the p-value below which a difference
+	// between variants is reported as significant.
+	variantSignificanceLevel = 0.05
+
+	variantHintSignificant      = "significant"
+	variantHintNotSignificant   = "not_significant"
+	variantHintInsufficientData = "insufficient_data"
+)
+
+// GetWorkflowVariantAnalytics reports per workflow step that ran an A/B test
+// the outcomes of each template variant in [from, to), and compares every
+// variant with the step's baseline variant.
+func (s *Service) GetWorkflowVariantAnalytics(ctx context.Context, organizationID uuid.UUID, from, to time.Time, replyDays, conversionDays int) (transport.WorkflowVariantAnalyticsResponse, error) {
+	from, to, err := s.resolveRange(from, to)
+	if err != nil {
+		return transport.WorkflowVariantAnalyticsResponse{}, err
+	}
+	replyDays, conversionDays = workflowAttributionWindows(replyDays, conversionDays)
+
+	stats, err := s.repo.ListWorkflowVariantStats(ctx, organizationID, from, to, replyDays, conversionDays)
+	if err != nil {
+		return transport.WorkflowVariantAnalyticsResponse{}, err
+	}
+
+	resp := buildWorkflowVariantAnalytics(stats)
+	resp.From = from.Format(time.DateOnly)
+	resp.To = to.AddDate(0, 0, -1).Format(time.DateOnly)
+	resp.ReplyWindowDays = replyDays
+	resp.ConversionWindowDays = conversionDays
+	return resp, nil
+}
+
+// buildWorkflowVariantAnalytics groups the variant stats, which arrive
+// ordered by step and variant key, into tests. The first variant of each
+// step is its baseline.
+func buildWorkflowVariantAnalytics(stats []repository.WorkflowVariantStats) transport.WorkflowVariantAnalyticsResponse {
+	resp := transport.WorkflowVariantAnalyticsResponse{Tests: make([]transport.WorkflowVariantTest, 0)}
+	var baseline repository.WorkflowVariantStats
+	for _, variant := range stats {
+		n := len(resp.Tests)
+		item := transport.WorkflowVariantAnalytics{
+			Key:                   variant.VariantKey,
+			WorkflowMessageCounts: toWorkflowMessageCounts(variant.WorkflowStepStats),
+			Comparisons:           make([]transport.WorkflowVariantComparison, 0),
+		}
+		if n == 0 || resp.Tests[n-1].WorkflowID != variant.WorkflowID.String() || resp.Tests[n-1].StepID != variant.StepID.String() {
+			resp.Tests = append(resp.Tests, transport.WorkflowVariantTest{
+				WorkflowID:   variant.WorkflowID.String(),
+				WorkflowName: variant.WorkflowName,
+				StepID:       variant.StepID.String(),
+				Trigger:      variant.Trigger,
+				Channel:      variant.Channel,
+				Variants:     make([]transport.WorkflowVariantAnalytics, 0),
+			})
+			n++
+			baseline = variant
+		} else {
+			item.Comparisons = compareWorkflowVariant(baseline, variant)
+		}
+		resp.Tests[n-1].Variants = append(resp.Tests[n-1].Variants, item)
+	}
+	return resp
+}
+
+// compareWorkflowVariant compares the open rate (emails only), reply rate
+// and conversion rate of variant with those of baseline.
+func compareWorkflowVariant(baseline, variant repository.WorkflowVariantStats) []transport.WorkflowVariantComparison {
+	comparisons := make([]transport.WorkflowVariantComparison, 0, 3)
+	if variant.Channel == "email" {
+		comparisons = append(comparisons, compareProportions("openRate", baseline.VariantKey,
+			baseline.Opened, baseline.Delivered, variant.Opened, variant.Delivered))
+	}
+	comparisons = append(comparisons,
+		compareProportions("replyRate", baseline.VariantKey,
+			baseline.Replied, baseline.Delivered, variant.Replied, variant.Delivered),
+		compareProportions("conversionRate", baseline.VariantKey,
+			baseline.QuotesAccepted, baseline.Delivered, variant.QuotesAccepted, variant.Delivered),
+	)
+	return comparisons
+}
+
+// compareProportions runs a two-sided two-proportion z-test of hits/total
+// against baseHits/baseTotal.
+func compareProportions(metric, baselineKey string, baseHits, baseTotal, hits, total int64) transport.WorkflowVariantComparison {
+	comparison := transport.WorkflowVariantComparison{
+		Metric:   metric,
+		Baseline: baselineKey,
+		PValue:   1,
+		Hint:     variantHintInsufficientData,
+	}
+	baseRate, variantRate := rate(baseHits, baseTotal), rate(hits, total)
+	if baseRate > 0 {
+		comparison.Lift = roundThousandth((variantRate - baseRate) / baseRate)
+	}
+	if baseTotal < minVariantSampleSize || total < minVariantSampleSize {
+		return comparison
+	}
+
+	comparison.Hint = variantHintNotSignificant
+	pooled := float64(baseHits+hits) / float64(baseTotal+total)
+	stdErr := math.Sqrt(pooled * (1 - pooled) * (1/float64(baseTotal) + 1/float64(total)))
+	if stdErr == 0 {
+		return comparison
+	}
+	z := (variantRate - baseRate) / stdErr
+	p := math.Erfc(math.Abs(z) / math.Sqrt2)
+	comparison.ZScore = roundThousandth(z)
+	comparison.PValue = roundThousandth(p)
+	if p < variantSignificanceLevel {
+		comparison.Hint = variantHintSignificant
+	}
+	return comparison
+}
+
+func roundThousandth(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+	"portal_final_backend/internal/analytics/transport"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultStaleAfterDays = 7
+	defaultUpcomingDays   = 7
+)
+
+// GetAgentWorkload reports the open leads, upcoming appointments and pending
+// quotes per agent, with how long ago their leads were last touched, so
+// managers can rebalance assignments.
+func (s *Service) GetAgentWorkload(ctx context.Context, organizationID uuid.UUID, staleAfterDays, upcomingDays int, now time.Time) (transport.AgentWorkloadResponse, error) {
+	if staleAfterDays <= 0 {
+		staleAfterDays = defaultStaleAfterDays
+	}
+	if upcomingDays <= 0 {
+		upcomingDays = defaultUpcomingDays
+	}
+
+	agents, err := s.repo.ListAgentWorkload(ctx, organizationID, repository.WorkloadQuery{
+		Now:           now,
+		StaleBefore:   now.AddDate(0, 0, -staleAfterDays),
+		UpcomingUntil: now.AddDate(0, 0, upcomingDays),
+	})
+	if err != nil {
+		return transport.AgentWorkloadResponse{}, err
+	}
+
+	resp := transport.AgentWorkloadResponse{
+		GeneratedAt:    now,
+		StaleAfterDays: staleAfterDays,
+		UpcomingDays:   upcomingDays,
+		Buckets:        stalenessBuckets(),
+		Totals:         transport.AgentWorkloadItem{Staleness: make([]int64, len(repository.StalenessBucketDays))},
+		Agents:         make([]transport.AgentWorkloadItem, 0, len(agents)),
+	}
+	var touchAgeSeconds float64
+	for _, agent := range agents {
+		item := toAgentWorkloadItem(agent, now)
+		resp.Agents = append(resp.Agents, item)
+
+		resp.Totals.OpenLeads += item.OpenLeads
+		resp.Totals.StaleLeads += item.StaleLeads
+		resp.Totals.UpcomingAppointments += item.UpcomingAppointments
+		resp.Totals.PendingQuotes += item.PendingQuotes
+		resp.Totals.SentQuotes += item.SentQuotes
+		resp.Totals.PendingQuoteValueCents += item.PendingQuoteValueCents
+		for i, count := range item.Staleness {
+			resp.Totals.Staleness[i] += count
+		}
+		touchAgeSeconds += agent.AverageTouchAgeSeconds * float64(agent.OpenLeads)
+		if item.DaysSinceOldestTouch != nil && (resp.Totals.DaysSinceOldestTouch == nil || *item.DaysSinceOldestTouch > *resp.Totals.DaysSinceOldestTouch) {
+			resp.Totals.DaysSinceOldestTouch = item.DaysSinceOldestTouch
+		}
+		if item.NextAppointmentAt != nil && (resp.Totals.NextAppointmentAt == nil || item.NextAppointmentAt.Before(*resp.Totals.NextAppointmentAt)) {
+			resp.Totals.NextAppointmentAt = item.NextAppointmentAt
+		}
+	}
+	if resp.Totals.OpenLeads > 0 {
+		resp.Totals.AverageDaysSinceLastTouch = roundDays(touchAgeSeconds / float64(resp.Totals.OpenLeads))
+	}
+	return resp, nil
+}
+
+func toAgentWorkloadItem(agent repository.AgentWorkload, now time.Time) transport.AgentWorkloadItem {
+	item := transport.AgentWorkloadItem{
+		OpenLeads:                 agent.OpenLeads,
+		StaleLeads:                agent.StaleLeads,
+		AverageDaysSinceLastTouch: roundDays(agent.AverageTouchAgeSeconds),
+		Staleness:                 agent.Staleness,
+		UpcomingAppointments:      agent.UpcomingAppointments,
+		NextAppointmentAt:         agent.NextAppointmentAt,
+		PendingQuotes:             agent.PendingQuotes,
+		SentQuotes:                agent.SentQuotes,
+		PendingQuoteValueCents:    agent.PendingQuoteValueCents,
+	}
+	if agent.AgentID != nil {
+		item.AgentID = agent.AgentID.String()
+	}
+	if agent.Email != nil {
+		item.Email = *agent.Email
+	}
+	item.Name = strings.TrimSpace(derefString(agent.FirstName) + " " + derefString(agent.LastName))
+	if agent.OldestTouchAt != nil {
+		days := int(now.Sub(*agent.OldestTouchAt) / (24 * time.Hour))
+		item.DaysSinceOldestTouch = &days
+	}
+	return item
+}
+
+// stalenessBuckets describes the heatmap columns in repository bucket order.
+func stalenessBuckets() []transport.StalenessBucket {
+	bounds := repository.StalenessBucketDays
+	buckets := make([]transport.StalenessBucket, 0, len(bounds))
+	for i, minDays := range bounds {
+		bucket := transport.StalenessBucket{MinDays: minDays, Label: fmt.Sprintf("%d+", minDays)}
+		if i+1 < len(bounds) {
+			maxDays := bounds[i+1]
+			bucket.MaxDays = &maxDays
+			bucket.Label = fmt.Sprintf("%d-%d", minDays, maxDays-1)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// roundDays converts seconds to days with one decimal.
+func roundDays(seconds float64) float64 {
+	return math.Round(seconds/86400*10) / 10
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
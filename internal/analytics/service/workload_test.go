@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/analytics/repository"
+
+	"github.com/google/uuid"
+)
+
+type workloadRepo struct {
+	Repository
+	query  repository.WorkloadQuery
+	agents []repository.AgentWorkload
+}
+
+func (r *workloadRepo) ListAgentWorkload(_ context.Context, _ uuid.UUID, q repository.WorkloadQuery) ([]repository.AgentWorkload, error) {
+	r.query = q
+	return r.agents, nil
+}
+
+func TestGetAgentWorkloadSumsAgents(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	agentID := uuid.New()
+	email, first, last := "sanne@example.com", "Sanne", "de Vries"
+	oldest := now.Add(-10*24*time.Hour - time.Hour)
+	next := now.Add(26 * time.Hour)
+	repo := &workloadRepo{agents: []repository.AgentWorkload{
+		{
+			AgentID: &agentID, Email: &email, FirstName: &first, LastName: &last,
+			OpenLeads: 3, StaleLeads: 1, OldestTouchAt: &oldest, AverageTouchAgeSeconds: 4 * 86400,
+			Staleness: []int64{1, 1, 1, 0, 0}, UpcomingAppointments: 2, NextAppointmentAt: &next,
+			PendingQuotes: 2, SentQuotes: 1, PendingQuoteValueCents: 150000,
+		},
+		{
+			OpenLeads: 1, StaleLeads: 1, AverageTouchAgeSeconds: 40 * 86400,
+			Staleness: []int64{0, 0, 0, 0, 1},
+		},
+	}}
+	svc := New(repo, Config{}, nil)
+
+	resp, err := svc.GetAgentWorkload(context.Background(), uuid.New(), 0, 14, now)
+	if err != nil {
+		t.Fatalf("GetAgentWorkload() error = %v", err)
+	}
+
+	if !repo.query.StaleBefore.Equal(now.AddDate(0, 0, -defaultStaleAfterDays)) || !repo.query.UpcomingUntil.Equal(now.AddDate(0, 0, 14)) {
+		t.Fatalf("unexpected workload query %+v", repo.query)
+	}
+	agent := resp.Agents[0]
+	if agent.AgentID != agentID.String() || agent.Name != "Sanne de Vries" {
+		t.Fatalf("agent = %+v", agent)
+	}
+	if agent.DaysSinceOldestTouch == nil || *agent.DaysSinceOldestTouch != 10 {
+		t.Fatalf("daysSinceOldestTouch = %v, want 10", agent.DaysSinceOldestTouch)
+	}
+	if resp.Agents[1].AgentID != "" {
+		t.Fatalf("unassigned agentId = %q, want empty", resp.Agents[1].AgentID)
+	}
+	if resp.Totals.OpenLeads != 4 || resp.Totals.StaleLeads != 2 || resp.Totals.PendingQuoteValueCents != 150000 {
+		t.Fatalf("totals = %+v", resp.Totals)
+	}
+	if resp.Totals.AverageDaysSinceLastTouch != 13 {
+		t.Fatalf("average days = %v, want 13", resp.Totals.AverageDaysSinceLastTouch)
+	}
+	if resp.Totals.Staleness[4] != 1 || resp.Totals.Staleness[0] != 1 {
+		t.Fatalf("staleness totals = %v", resp.Totals.Staleness)
+	}
+}
+
+func TestStalenessBucketsLabels(t *testing.T) {
+	buckets := stalenessBuckets()
+	want := []string{"0-2", "3-6", "7-13", "14-29", "30+"}
+	if len(buckets) != len(want) {
+		t.Fatalf("got %d buckets, want %d", len(buckets), len(want))
+	}
+	for i, label := range want {
+		if buckets[i].Label != label {
+			t.Fatalf("bucket %d label = %q, want %q", i, buckets[i].Label, label)
+		}
+	}
+	if buckets[len(buckets)-1].MaxDays != nil {
+		t.Fatal("last bucket should be open ended")
+	}
+}
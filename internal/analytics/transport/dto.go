@@ -0,0 +1,305 @@
+// Package transport provides DTOs for the funnel analytics endpoints.
+package transport
+
+import "time"
+
+// ReportQuery selects the Europe/Amsterdam calendar days of a report; both dates
+// are inclusive. Omitted dates default to the last 30 days.
+type ReportQuery struct {
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// BreakdownQuery selects the dimension and days of a win rate breakdown.
+type BreakdownQuery struct {
+	ReportQuery
+	GroupBy string `form:"groupBy" validate:"required,oneof=serviceType source agent"`
+}
+
+// FunnelCounts are the funnel counters of a period. Rates are fractions between
+// 0 and 1; the win rate only covers services that were won or lost.
+type FunnelCounts struct {
+	ServicesCreated         int64   `json:"servicesCreated"`
+	ServicesWon             int64   `json:"servicesWon"`
+	ServicesLost            int64   `json:"servicesLost"`
+	WinRate                 float64 `json:"winRate"`
+	QuotesIssued            int64   `json:"quotesIssued"`
+	QuotesAccepted          int64   `json:"quotesAccepted"`
+	QuoteAcceptanceRate     float64 `json:"quoteAcceptanceRate"`
+	QuoteValueIssuedCents   int64   `json:"quoteValueIssuedCents"`
+	QuoteValueAcceptedCents int64   `json:"quoteValueAcceptedCents"`
+}
+
+// StageResponse reports the traffic through one pipeline stage. ConversionRate is
+// the number of stage entries relative to the services created in the period.
+type StageResponse struct {
+	Stage                     string  `json:"stage"`
+	Entered                   int64   `json:"entered"`
+	Exited                    int64   `json:"exited"`
+	ConversionRate            float64 `json:"conversionRate"`
+	AverageTimeInStageSeconds int64   `json:"averageTimeInStageSeconds"`
+}
+
+type FunnelResponse struct {
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	RefreshedAt *time.Time      `json:"refreshedAt,omitempty"`
+	Totals      FunnelCounts    `json:"totals"`
+	Stages      []StageResponse `json:"stages"`
+}
+
+// GroupFunnelItem holds the funnel totals of one organization of a franchise.
+type GroupFunnelItem struct {
+	OrganizationID string `json:"organizationId"`
+	HeadOffice     bool   `json:"headOffice"`
+	FunnelCounts
+}
+
+// GroupFunnelResponse consolidates the funnel of a head office and its branches.
+type GroupFunnelResponse struct {
+	From          string            `json:"from"`
+	To            string            `json:"to"`
+	RefreshedAt   *time.Time        `json:"refreshedAt,omitempty"`
+	Totals        FunnelCounts      `json:"totals"`
+	Organizations []GroupFunnelItem `json:"organizations"`
+}
+
+// BreakdownItem holds the counters of one service type, source or agent. Key is
+// empty for services without a value, e.g. leads without an assigned agent.
+type BreakdownItem struct {
+	Key string `json:"key"`
+	FunnelCounts
+}
+
+type BreakdownResponse struct {
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	GroupBy     string          `json:"groupBy"`
+	RefreshedAt *time.Time      `json:"refreshedAt,omitempty"`
+	Items       []BreakdownItem `json:"items"`
+}
+
+type DailyItem struct {
+	Date string `json:"date"`
+	FunnelCounts
+}
+
+// DailyResponse is the time series for the dashboard charts. Days without
+// activity are omitted.
+type DailyResponse struct {
+	From        string      `json:"from"`
+	To          string      `json:"to"`
+	RefreshedAt *time.Time  `json:"refreshedAt,omitempty"`
+	Days        []DailyItem `json:"days"`
+}
+
+// SourceROIItem reports the cohort of leads created from one source. ROI and the
+// cost ratios are omitted when no spend was imported for the source.
+type SourceROIItem struct {
+	Source               string   `json:"source"`
+	Leads                int64    `json:"leads"`
+	Customers            int64    `json:"customers"`
+	ConversionRate       float64  `json:"conversionRate"`
+	QuotesAccepted       int64    `json:"quotesAccepted"`
+	RevenueCents         int64    `json:"revenueCents"`
+	CostCents            int64    `json:"costCents"`
+	Clicks               int64    `json:"clicks"`
+	ROI                  *float64 `json:"roi,omitempty"`
+	CostPerLeadCents     *int64   `json:"costPerLeadCents,omitempty"`
+	CostPerCustomerCents *int64   `json:"costPerCustomerCents,omitempty"`
+}
+
+type SourceROIResponse struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Totals  SourceROIItem   `json:"totals"`
+	Sources []SourceROIItem `json:"sources"`
+}
+
+// ImportMarketingCostsQuery selects the lead source the imported spend belongs to.
+type ImportMarketingCostsQuery struct {
+	Source string `form:"source" validate:"omitempty,max=100"`
+}
+
+type ImportMarketingCostsResponse struct {
+	Source         string `json:"source"`
+	Rows           int    `json:"rows"`
+	From           string `json:"from"`
+	To             string `json:"to"`
+	TotalCostCents int64  `json:"totalCostCents"`
+}
+
+// WorkloadQuery tunes the agent workload report. Leads without activity for
+// StaleAfterDays are stale; appointments in the next UpcomingDays are upcoming.
+type WorkloadQuery struct {
+	StaleAfterDays int `form:"staleAfterDays" validate:"omitempty,min=1,max=90"`
+	UpcomingDays   int `form:"upcomingDays" validate:"omitempty,min=1,max=60"`
+}
+
+// StalenessBucket is a column of the staleness heatmap: open leads last
+// touched at least MinDays and, when set, less than MaxDays ago.
+type StalenessBucket struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"minDays"`
+	MaxDays *int   `json:"maxDays,omitempty"`
+}
+
+// AgentWorkloadItem is the open work of one agent. AgentID is empty for leads
+// and quotes without an assigned agent. Staleness holds the open leads per
+// bucket of the response, in the same order.
+type AgentWorkloadItem struct {
+	AgentID                   string     `json:"agentId"`
+	Email                     string     `json:"email,omitempty"`
+	Name                      string     `json:"name,omitempty"`
+	OpenLeads                 int64      `json:"openLeads"`
+	StaleLeads                int64      `json:"staleLeads"`
+	DaysSinceOldestTouch      *int       `json:"daysSinceOldestTouch,omitempty"`
+	AverageDaysSinceLastTouch float64    `json:"averageDaysSinceLastTouch"`
+	Staleness                 []int64    `json:"staleness"`
+	UpcomingAppointments      int64      `json:"upcomingAppointments"`
+	NextAppointmentAt         *time.Time `json:"nextAppointmentAt,omitempty"`
+	PendingQuotes             int64      `json:"pendingQuotes"`
+	SentQuotes                int64      `json:"sentQuotes"`
+	PendingQuoteValueCents    int64      `json:"pendingQuoteValueCents"`
+}
+
+type AgentWorkloadResponse struct {
+	GeneratedAt    time.Time           `json:"generatedAt"`
+	StaleAfterDays int                 `json:"staleAfterDays"`
+	UpcomingDays   int                 `json:"upcomingDays"`
+	Buckets        []StalenessBucket   `json:"buckets"`
+	Totals         AgentWorkloadItem   `json:"totals"`
+	Agents         []AgentWorkloadItem `json:"agents"`
+}
+
+// CapacityPlanQuery selects how many past weeks set the expected weekly volume
+// and how many weeks, including the current one, are planned ahead.
+type CapacityPlanQuery struct {
+	HistoryWeeks int `form:"historyWeeks" validate:"omitempty,min=1,max=12"`
+	HorizonWeeks int `form:"horizonWeeks" validate:"omitempty,min=1,max=26"`
+}
+
+// CapacityCell compares the jobs expected for a service type in a region,
+// the first two digits of the postal code, with the partner days available.
+type CapacityCell struct {
+	ServiceType        string  `json:"serviceType"`
+	Region             string  `json:"region"`
+	AcceptedQuotes     int64   `json:"acceptedQuotes"`
+	AcceptedValueCents int64   `json:"acceptedValueCents"`
+	ExpectedJobs       float64 `json:"expectedJobs"`
+	PartnerDays        int64   `json:"partnerDays"`
+	AvailablePartners  int64   `json:"availablePartners"`
+	Shortfall          float64 `json:"shortfall,omitempty"`
+	Bottleneck         bool    `json:"bottleneck"`
+}
+
+// CapacityWeek is one week of the capacity plan. Projected weeks expect at
+// least the average weekly volume of the history weeks.
+type CapacityWeek struct {
+	WeekStart    string         `json:"weekStart"`
+	Projected    bool           `json:"projected"`
+	ExpectedJobs float64        `json:"expectedJobs"`
+	PartnerDays  int64          `json:"partnerDays"`
+	Bottlenecks  int            `json:"bottlenecks"`
+	Cells        []CapacityCell `json:"cells"`
+}
+
+type CapacityPlanResponse struct {
+	GeneratedAt  time.Time      `json:"generatedAt"`
+	HistoryWeeks int            `json:"historyWeeks"`
+	HorizonWeeks int            `json:"horizonWeeks"`
+	Bottlenecks  int            `json:"bottlenecks"`
+	Weeks        []CapacityWeek `json:"weeks"`
+}
+
+// WorkflowAnalyticsQuery selects the days of the workflow report and how many
+// days after a message replies and quote conversions are attributed to it.
+type WorkflowAnalyticsQuery struct {
+	ReportQuery
+	ReplyWindowDays      int `form:"replyWindowDays" validate:"omitempty,min=1,max=30"`
+	ConversionWindowDays int `form:"conversionWindowDays" validate:"omitempty,min=1,max=90"`
+}
+
+// WorkflowMessageCounts are the outcomes of workflow messages. The delivery
+// rate is relative to the messages sent, the other rates to the messages
+// delivered. Opens and clicks are only tracked for emails of organizations
+// that enabled email tracking.
+type WorkflowMessageCounts struct {
+	Sent           int64   `json:"sent"`
+	Delivered      int64   `json:"delivered"`
+	Opened         int64   `json:"opened"`
+	Clicked        int64   `json:"clicked"`
+	Replied        int64   `json:"replied"`
+	QuotesViewed   int64   `json:"quotesViewed"`
+	QuotesAccepted int64   `json:"quotesAccepted"`
+	DeliveryRate   float64 `json:"deliveryRate"`
+	OpenRate       float64 `json:"openRate"`
+	ClickRate      float64 `json:"clickRate"`
+	ReplyRate      float64 `json:"replyRate"`
+	ConversionRate float64 `json:"conversionRate"`
+}
+
+type WorkflowStepAnalytics struct {
+	StepID  string `json:"stepId"`
+	Trigger string `json:"trigger"`
+	Channel string `json:"channel"`
+	WorkflowMessageCounts
+}
+
+// WorkflowAnalyticsItem is one workflow with its steps. Name is empty for
+// workflows that were deleted since.
+type WorkflowAnalyticsItem struct {
+	WorkflowID string `json:"workflowId"`
+	Name       string `json:"name"`
+	WorkflowMessageCounts
+	Steps []WorkflowStepAnalytics `json:"steps"`
+}
+
+type WorkflowAnalyticsResponse struct {
+	From                 string                  `json:"from"`
+	To                   string                  `json:"to"`
+	ReplyWindowDays      int                     `json:"replyWindowDays"`
+	ConversionWindowDays int                     `json:"conversionWindowDays"`
+	Totals               WorkflowMessageCounts   `json:"totals"`
+	Workflows            []WorkflowAnalyticsItem `json:"workflows"`
+}
+
+// WorkflowVariantComparison compares a metric of a variant with the baseline
+// variant of its step using a two-proportion z-test. Hint is "significant"
+// when p < 0.05, "not_significant" otherwise, and "insufficient_data" while
+// either variant has delivered fewer messages than the test needs.
+type WorkflowVariantComparison struct {
+	Metric   string  `json:"metric"`
+	Baseline string  `json:"baseline"`
+	Lift     float64 `json:"lift"`
+	ZScore   float64 `json:"zScore"`
+	PValue   float64 `json:"pValue"`
+	Hint     string  `json:"hint"`
+}
+
+// WorkflowVariantAnalytics is one template variant of a step. Comparisons
+// are empty for the baseline variant.
+type WorkflowVariantAnalytics struct {
+	Key string `json:"key"`
+	WorkflowMessageCounts
+	Comparisons []WorkflowVariantComparison `json:"comparisons"`
+}
+
+// WorkflowVariantTest is a workflow step that ran an A/B test of its
+// templates. Its first variant by key is the baseline.
+type WorkflowVariantTest struct {
+	WorkflowID   string                     `json:"workflowId"`
+	WorkflowName string                     `json:"workflowName"`
+	StepID       string                     `json:"stepId"`
+	Trigger      string                     `json:"trigger"`
+	Channel      string                     `json:"channel"`
+	Variants     []WorkflowVariantAnalytics `json:"variants"`
+}
+
+type WorkflowVariantAnalyticsResponse struct {
+	From                 string                `json:"from"`
+	To                   string                `json:"to"`
+	ReplyWindowDays      int                   `json:"replyWindowDays"`
+	ConversionWindowDays int                   `json:"conversionWindowDays"`
+	Tests                []WorkflowVariantTest `json:"tests"`
+}
@@ -8,6 +8,7 @@ import (
 	"portal_final_backend/internal/appointments/transport"
 	"portal_final_backend/platform/apperr"
 	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/ical"
 	"portal_final_backend/platform/validator"
 
 	"github.com/gin-gonic/gin"
@@ -37,10 +38,16 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.PATCH("/:id/status", h.UpdateStatus)
 	rg.GET("/:id/visit-report", h.GetVisitReport)
 	rg.PUT("/:id/visit-report", h.UpsertVisitReport)
+	rg.GET("/:id/visit", h.GetVisitExecution)
+	rg.POST("/:id/check-in", h.CheckIn)
+	rg.POST("/:id/check-out", h.CheckOut)
 	rg.POST("/:id/attachments/presign", h.PresignAttachmentUpload)
 	rg.GET("/:id/attachments", h.ListAttachments)
 	rg.POST("/:id/attachments", h.CreateAttachment)
 	rg.GET("/:id/attachments/:attachmentId/download", h.GetAttachmentDownloadURL)
+	rg.GET("/calendar-feed", h.GetCalendarFeed)
+	rg.POST("/calendar-feed", h.CreateCalendarFeed)
+	rg.DELETE("/calendar-feed", h.DeleteCalendarFeed)
 
 	avail := rg.Group("/availability")
 	{
@@ -58,6 +65,12 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	}
 }
 
+// RegisterPublicRoutes registers the token-authenticated iCalendar feed. Calendar apps
+// cannot send bearer tokens, so the secret token in the URL is the credential.
+func (h *Handler) RegisterPublicRoutes(rg *gin.RouterGroup) {
+	rg.GET("/feeds/:token", h.GetCalendarFeedICS)
+}
+
 // --- Appointments ---
 
 func (h *Handler) List(c *gin.Context) {
@@ -205,6 +218,69 @@ func (h *Handler) UpsertVisitReport(c *gin.Context) {
 	h.respond(c, result, err, http.StatusOK)
 }
 
+// --- Visit Execution ---
+
+func (h *Handler) GetVisitExecution(c *gin.Context) {
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	ctx, auth, ok := h.reqCtx(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetVisitExecution(ctx, id, auth.UserID, auth.IsAdmin, auth.TenantID)
+	if err != nil {
+		if domainErr, ok := err.(*apperr.Error); ok && domainErr.Kind == apperr.KindNotFound {
+			httpkit.OK(c, nil)
+			return
+		}
+	}
+	h.respond(c, result, err, http.StatusOK)
+}
+
+func (h *Handler) CheckIn(c *gin.Context) {
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req transport.CheckInVisitRequest
+	if !h.bind(c, &req, false) {
+		return
+	}
+
+	ctx, auth, ok := h.reqCtx(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.CheckIn(ctx, id, auth.UserID, auth.IsAdmin, auth.TenantID, req)
+	h.respond(c, result, err, http.StatusOK)
+}
+
+func (h *Handler) CheckOut(c *gin.Context) {
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req transport.CheckOutVisitRequest
+	if !h.bind(c, &req, false) {
+		return
+	}
+
+	ctx, auth, ok := h.reqCtx(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.CheckOut(ctx, id, auth.UserID, auth.IsAdmin, auth.TenantID, req)
+	h.respond(c, result, err, http.StatusOK)
+}
+
 // --- Attachments ---
 
 func (h *Handler) PresignAttachmentUpload(c *gin.Context) {
@@ -441,6 +517,47 @@ func (h *Handler) GetAvailableSlots(c *gin.Context) {
 	h.respond(c, result, err, http.StatusOK)
 }
 
+// --- Calendar Feeds ---
+
+func (h *Handler) GetCalendarFeed(c *gin.Context) {
+	ctx, auth, ok := h.reqCtx(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetCalendarFeed(ctx, auth.UserID, auth.TenantID)
+	h.respond(c, result, err, http.StatusOK)
+}
+
+func (h *Handler) CreateCalendarFeed(c *gin.Context) {
+	ctx, auth, ok := h.reqCtx(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.CreateCalendarFeed(ctx, auth.UserID, auth.TenantID)
+	h.respond(c, result, err, http.StatusCreated)
+}
+
+func (h *Handler) DeleteCalendarFeed(c *gin.Context) {
+	ctx, auth, ok := h.reqCtx(c)
+	if !ok {
+		return
+	}
+
+	err := h.svc.DeleteCalendarFeed(ctx, auth.UserID, auth.TenantID)
+	h.respond(c, gin.H{"message": "calendar feed deleted"}, err, http.StatusOK)
+}
+
+func (h *Handler) GetCalendarFeedICS(c *gin.Context) {
+	body, err := h.svc.RenderCalendarFeed(c.Request.Context(), c.Param("token"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, ical.MIMEType, body)
+}
+
 // --- Helpers ---
 
 type authParams struct {
@@ -33,6 +33,8 @@ type Dependencies struct {
 	Storage           storage.StorageService
 	TimelineRecorder  leadsrepo.TimelineEventStore
 	AttachmentBucket  string
+	// CalendarFeedBaseURL is the public API origin for per-agent iCalendar feed URLs.
+	CalendarFeedBaseURL string
 }
 
 // NewModule creates a new appointments module with all dependencies wired.
@@ -47,6 +49,8 @@ func NewModule(deps Dependencies) *Module {
 		Storage:           deps.Storage,
 		AttachmentBucket:  deps.AttachmentBucket,
 		TimelineRecorder:  deps.TimelineRecorder,
+
+		CalendarFeedBaseURL: deps.CalendarFeedBaseURL,
 	})
 
 	return &Module{
@@ -69,6 +73,7 @@ func (m *Module) Name() string {
 // RegisterRoutes registers the module's routes under /api/appointments.
 func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	m.handler.RegisterRoutes(ctx.Protected.Group("/appointments"))
+	m.handler.RegisterPublicRoutes(ctx.V1.Group("/calendar"))
 }
 
 // Compile-time check that Module implements apphttp.Module.
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const calendarFeedNotFoundMsg = "calendar feed not found"
+
+// CalendarFeed is the iCalendar subscription feed of an agent.
+type CalendarFeed struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	CreatedAt      time.Time
+	LastAccessedAt *time.Time
+}
+
+// ReplaceCalendarFeedToken stores a new feed token hash for the user, invalidating the
+// previous feed URL.
+func (r *Repository) ReplaceCalendarFeedToken(ctx context.Context, orgID, userID uuid.UUID, tokenHash string) (*CalendarFeed, error) {
+	var feed CalendarFeed
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_appointment_calendar_feeds (organization_id, user_id, token_hash)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, user_id)
+		DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now(), last_accessed_at = NULL
+		RETURNING organization_id, user_id, created_at, last_accessed_at
+	`, orgID, userID, tokenHash).Scan(&feed.OrganizationID, &feed.UserID, &feed.CreatedAt, &feed.LastAccessedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store calendar feed token: %w", err)
+	}
+	return &feed, nil
+}
+
+// GetCalendarFeed returns the feed of the user, or nil when none is enabled.
+func (r *Repository) GetCalendarFeed(ctx context.Context, orgID, userID uuid.UUID) (*CalendarFeed, error) {
+	var feed CalendarFeed
+	err := r.pool.QueryRow(ctx, `
+		SELECT organization_id, user_id, created_at, last_accessed_at
+		FROM RAC_appointment_calendar_feeds
+		WHERE organization_id = $1 AND user_id = $2
+	`, orgID, userID).Scan(&feed.OrganizationID, &feed.UserID, &feed.CreatedAt, &feed.LastAccessedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return &feed, nil
+}
+
+// TouchCalendarFeedByTokenHash resolves the owner of a feed token and records the access.
+func (r *Repository) TouchCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (*CalendarFeed, error) {
+	var feed CalendarFeed
+	err := r.pool.QueryRow(ctx, `
+		UPDATE RAC_appointment_calendar_feeds SET last_accessed_at = now()
+		WHERE token_hash = $1
+		RETURNING organization_id, user_id, created_at, last_accessed_at
+	`, tokenHash).Scan(&feed.OrganizationID, &feed.UserID, &feed.CreatedAt, &feed.LastAccessedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound(calendarFeedNotFoundMsg)
+		}
+		return nil, fmt.Errorf("failed to resolve calendar feed: %w", err)
+	}
+	return &feed, nil
+}
+
+// DeleteCalendarFeed disables the feed of the user.
+func (r *Repository) DeleteCalendarFeed(ctx context.Context, orgID, userID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_appointment_calendar_feeds
+		WHERE organization_id = $1 AND user_id = $2
+	`, orgID, userID); err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	return nil
+}
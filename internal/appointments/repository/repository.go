@@ -7,6 +7,8 @@ import (
 
 	appointmentsdb "portal_final_backend/internal/appointments/db"
 	"portal_final_backend/internal/appointments/transport"
+	"portal_final_backend/internal/eventoutbox"
+	"portal_final_backend/internal/events"
 	"portal_final_backend/platform/apperr"
 
 	"github.com/google/uuid"
@@ -130,25 +132,29 @@ func optionalString(v pgtype.Text) *string {
 
 // --- Operations ---
 
-func (r *Repository) Create(ctx context.Context, a *Appointment) error {
-	return r.queries.CreateAppointment(ctx, appointmentsdb.CreateAppointmentParams{
-		ID:             toPgUUID(a.ID),
-		OrganizationID: toPgUUID(a.OrganizationID),
-		UserID:         toPgUUID(a.UserID),
-		LeadID:         toPgUUIDPtr(a.LeadID),
-		LeadServiceID:  toPgUUIDPtr(a.LeadServiceID),
-		Type:           a.Type,
-		Title:          a.Title,
-		Description:    toPgText(a.Description),
-		Location:       toPgText(a.Location),
-		MeetingLink:    toPgText(a.MeetingLink),
-		StartTime:      toPgTimestamp(a.StartTime),
-		EndTime:        toPgTimestamp(a.EndTime),
-		Status:         a.Status,
-		AllDay:         a.AllDay,
-		CreatedAt:      toPgTimestamp(a.CreatedAt),
-		UpdatedAt:      toPgTimestamp(a.UpdatedAt),
-	})
+// Create inserts the appointment. The write methods below store the pending
+// events in the domain event outbox in the same transaction.
+func (r *Repository) Create(ctx context.Context, a *Appointment, pendingEvents ...events.Event) error {
+	return eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		return r.queries.WithTx(tx).CreateAppointment(ctx, appointmentsdb.CreateAppointmentParams{
+			ID:             toPgUUID(a.ID),
+			OrganizationID: toPgUUID(a.OrganizationID),
+			UserID:         toPgUUID(a.UserID),
+			LeadID:         toPgUUIDPtr(a.LeadID),
+			LeadServiceID:  toPgUUIDPtr(a.LeadServiceID),
+			Type:           a.Type,
+			Title:          a.Title,
+			Description:    toPgText(a.Description),
+			Location:       toPgText(a.Location),
+			MeetingLink:    toPgText(a.MeetingLink),
+			StartTime:      toPgTimestamp(a.StartTime),
+			EndTime:        toPgTimestamp(a.EndTime),
+			Status:         a.Status,
+			AllDay:         a.AllDay,
+			CreatedAt:      toPgTimestamp(a.CreatedAt),
+			UpdatedAt:      toPgTimestamp(a.UpdatedAt),
+		})
+	}, pendingEvents...)
 }
 
 func (r *Repository) GetByID(ctx context.Context, id, organizationID uuid.UUID) (*Appointment, error) {
@@ -334,28 +340,34 @@ func (r *Repository) ListLeadVisitsByStatus(ctx context.Context, leadID, organiz
 	return items, nil
 }
 
-func (r *Repository) Update(ctx context.Context, a *Appointment) error {
-	res, err := r.queries.UpdateAppointment(ctx, appointmentsdb.UpdateAppointmentParams{
-		ID: toPgUUID(a.ID), Title: a.Title, Description: toPgText(a.Description),
-		Location: toPgText(a.Location), MeetingLink: toPgText(a.MeetingLink),
-		StartTime: toPgTimestamp(a.StartTime), EndTime: toPgTimestamp(a.EndTime),
-		AllDay: a.AllDay, UpdatedAt: toPgTimestamp(a.UpdatedAt), OrganizationID: toPgUUID(a.OrganizationID),
-	})
-	return r.affected(res, err)
+func (r *Repository) Update(ctx context.Context, a *Appointment, pendingEvents ...events.Event) error {
+	return eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		res, err := r.queries.WithTx(tx).UpdateAppointment(ctx, appointmentsdb.UpdateAppointmentParams{
+			ID: toPgUUID(a.ID), Title: a.Title, Description: toPgText(a.Description),
+			Location: toPgText(a.Location), MeetingLink: toPgText(a.MeetingLink),
+			StartTime: toPgTimestamp(a.StartTime), EndTime: toPgTimestamp(a.EndTime),
+			AllDay: a.AllDay, UpdatedAt: toPgTimestamp(a.UpdatedAt), OrganizationID: toPgUUID(a.OrganizationID),
+		})
+		return r.affected(res, err)
+	}, pendingEvents...)
 }
 
-func (r *Repository) UpdateStatus(ctx context.Context, id, organizationID uuid.UUID, status string) error {
-	res, err := r.queries.UpdateAppointmentStatus(ctx, appointmentsdb.UpdateAppointmentStatusParams{
-		ID: toPgUUID(id), OrganizationID: toPgUUID(organizationID), Status: status, UpdatedAt: toPgTimestamp(time.Now()),
-	})
-	return r.affected(res, err)
+func (r *Repository) UpdateStatus(ctx context.Context, id, organizationID uuid.UUID, status string, pendingEvents ...events.Event) error {
+	return eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		res, err := r.queries.WithTx(tx).UpdateAppointmentStatus(ctx, appointmentsdb.UpdateAppointmentStatusParams{
+			ID: toPgUUID(id), OrganizationID: toPgUUID(organizationID), Status: status, UpdatedAt: toPgTimestamp(time.Now()),
+		})
+		return r.affected(res, err)
+	}, pendingEvents...)
 }
 
-func (r *Repository) Delete(ctx context.Context, id, organizationID uuid.UUID) error {
-	res, err := r.queries.DeleteAppointment(ctx, appointmentsdb.DeleteAppointmentParams{
-		ID: toPgUUID(id), OrganizationID: toPgUUID(organizationID),
-	})
-	return r.affected(res, err)
+func (r *Repository) Delete(ctx context.Context, id, organizationID uuid.UUID, pendingEvents ...events.Event) error {
+	return eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		res, err := r.queries.WithTx(tx).DeleteAppointment(ctx, appointmentsdb.DeleteAppointmentParams{
+			ID: toPgUUID(id), OrganizationID: toPgUUID(organizationID),
+		})
+		return r.affected(res, err)
+	}, pendingEvents...)
 }
 
 func (r *Repository) affected(res int64, err error) error {
@@ -443,6 +455,55 @@ func (r *Repository) List(ctx context.Context, p ListParams) (*ListResult, error
 	}, nil
 }
 
+// ListByLeadIDs returns the appointments of several leads in one query, ordered by start
+// time. Batched readers use it instead of listing appointments per lead.
+func (r *Repository) ListByLeadIDs(ctx context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID) ([]Appointment, error) {
+	if len(leadIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, organization_id, user_id, lead_id, lead_service_id, type, title, description,
+			location, meeting_link, start_time, end_time, status, all_day, created_at, updated_at
+		FROM RAC_appointments
+		WHERE lead_id = ANY($1) AND organization_id = $2
+		ORDER BY lead_id, start_time ASC`,
+		toPgUUIDSlice(leadIDs), toPgUUID(organizationID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Appointment
+	for rows.Next() {
+		var row appointmentsdb.ListAppointmentsRow
+		if err := rows.Scan(
+			&row.ID, &row.OrganizationID, &row.UserID, &row.LeadID, &row.LeadServiceID, &row.Type, &row.Title, &row.Description,
+			&row.Location, &row.MeetingLink, &row.StartTime, &row.EndTime, &row.Status, &row.AllDay, &row.CreatedAt, &row.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, Appointment{
+			ID:             uuid.UUID(row.ID.Bytes),
+			OrganizationID: uuid.UUID(row.OrganizationID.Bytes),
+			UserID:         uuid.UUID(row.UserID.Bytes),
+			LeadID:         optionalUUID(row.LeadID),
+			LeadServiceID:  optionalUUID(row.LeadServiceID),
+			Type:           row.Type,
+			Title:          row.Title,
+			Description:    optionalString(row.Description),
+			Location:       optionalString(row.Location),
+			MeetingLink:    optionalString(row.MeetingLink),
+			StartTime:      row.StartTime.Time,
+			EndTime:        row.EndTime.Time,
+			Status:         row.Status,
+			AllDay:         row.AllDay,
+			CreatedAt:      row.CreatedAt.Time,
+			UpdatedAt:      row.UpdatedAt.Time,
+		})
+	}
+	return items, rows.Err()
+}
+
 func optionalSearchParam(v string) pgtype.Text {
 	if v == "" {
 		return pgtype.Text{}
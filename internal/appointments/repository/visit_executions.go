@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GeoFix is a GPS position reported by the device of the field agent.
+type GeoFix struct {
+	Latitude  *float64
+	Longitude *float64
+	AccuracyM *float64
+}
+
+// VisitExecution records when an appointment was executed on site.
+type VisitExecution struct {
+	AppointmentID  uuid.UUID
+	OrganizationID uuid.UUID
+	CheckedInAt    time.Time
+	CheckedInBy    *uuid.UUID
+	CheckIn        GeoFix
+	CheckedOutAt   *time.Time
+	CheckedOutBy   *uuid.UUID
+	CheckOut       GeoFix
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+const visitExecutionColumns = `appointment_id, organization_id, checked_in_at, checked_in_by,
+	check_in_latitude, check_in_longitude, check_in_accuracy_m,
+	checked_out_at, checked_out_by, check_out_latitude, check_out_longitude, check_out_accuracy_m,
+	created_at, updated_at`
+
+func scanVisitExecution(row pgx.Row) (*VisitExecution, error) {
+	var e VisitExecution
+	err := row.Scan(&e.AppointmentID, &e.OrganizationID, &e.CheckedInAt, &e.CheckedInBy,
+		&e.CheckIn.Latitude, &e.CheckIn.Longitude, &e.CheckIn.AccuracyM,
+		&e.CheckedOutAt, &e.CheckedOutBy, &e.CheckOut.Latitude, &e.CheckOut.Longitude, &e.CheckOut.AccuracyM,
+		&e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetVisitExecution returns the execution of the appointment, or nil when nobody
+// has checked in yet.
+func (r *Repository) GetVisitExecution(ctx context.Context, apptID, orgID uuid.UUID) (*VisitExecution, error) {
+	e, err := scanVisitExecution(r.pool.QueryRow(ctx, `
+		SELECT `+visitExecutionColumns+`
+		FROM RAC_appointment_visit_executions
+		WHERE appointment_id = $1 AND organization_id = $2
+	`, apptID, orgID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get visit execution: %w", err)
+	}
+	return e, nil
+}
+
+// CheckInVisit records the arrival on site. It returns nil when the appointment
+// was already checked in.
+func (r *Repository) CheckInVisit(ctx context.Context, apptID, orgID, userID uuid.UUID, at time.Time, fix GeoFix) (*VisitExecution, error) {
+	e, err := scanVisitExecution(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_appointment_visit_executions
+			(appointment_id, organization_id, checked_in_at, checked_in_by, check_in_latitude, check_in_longitude, check_in_accuracy_m)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (appointment_id) DO NOTHING
+		RETURNING `+visitExecutionColumns,
+		apptID, orgID, at, userID, fix.Latitude, fix.Longitude, fix.AccuracyM))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check in visit: %w", err)
+	}
+	return e, nil
+}
+
+// CheckOutVisit records the departure from site. It returns nil when the
+// appointment is not checked in or was already checked out.
+func (r *Repository) CheckOutVisit(ctx context.Context, apptID, orgID, userID uuid.UUID, at time.Time, fix GeoFix) (*VisitExecution, error) {
+	e, err := scanVisitExecution(r.pool.QueryRow(ctx, `
+		UPDATE RAC_appointment_visit_executions
+		SET checked_out_at = $3, checked_out_by = $4,
+			check_out_latitude = $5, check_out_longitude = $6, check_out_accuracy_m = $7,
+			updated_at = now()
+		WHERE appointment_id = $1 AND organization_id = $2 AND checked_out_at IS NULL
+		RETURNING `+visitExecutionColumns,
+		apptID, orgID, at, userID, fix.Latitude, fix.Longitude, fix.AccuracyM))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check out visit: %w", err)
+	}
+	return e, nil
+}
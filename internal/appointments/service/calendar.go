@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/appointments/repository"
+	"portal_final_backend/internal/appointments/transport"
+	"portal_final_backend/internal/auth/token"
+	"portal_final_backend/internal/email"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/ical"
+
+	"github.com/google/uuid"
+)
+
+const (
+	calendarFeedPath         = "/api/v1/calendar/feeds/"
+	calendarFeedSuffix       = ".ics"
+	calendarFeedName         = "Afspraken"
+	calendarFeedTokenBytes   = 32
+	calendarFeedLookback     = 90 * 24 * time.Hour
+	calendarFeedLookahead    = 365 * 24 * time.Hour
+	calendarInviteAttachment = "afspraak.ics"
+)
+
+// appointmentCalendarEvent converts an appointment into a VEVENT with a stable UID, so
+// updated invites and feed refreshes replace the existing calendar entry.
+func appointmentCalendarEvent(appt *repository.Appointment, leadInfo *transport.AppointmentLeadInfo) ical.Event {
+	location := getOptionalString(appt.Location)
+	if location == "" && leadInfo != nil {
+		location = leadInfo.Address
+	}
+	status := ical.StatusConfirmed
+	if appt.Status == string(transport.AppointmentStatusCancelled) {
+		status = ical.StatusCancelled
+	}
+	return ical.Event{
+		UID:          ical.UID(appt.ID.String()),
+		Sequence:     ical.SequenceSince(appt.CreatedAt, appt.UpdatedAt),
+		Start:        appt.StartTime,
+		End:          appt.EndTime,
+		AllDay:       appt.AllDay,
+		Summary:      appt.Title,
+		Description:  getOptionalString(appt.Description),
+		Location:     location,
+		URL:          getOptionalString(appt.MeetingLink),
+		Status:       status,
+		LastModified: appt.UpdatedAt,
+	}
+}
+
+// appointmentInviteAttachment renders the .ics invite attached to confirmation emails.
+func appointmentInviteAttachment(appt *repository.Appointment, leadInfo *transport.AppointmentLeadInfo) email.Attachment {
+	return email.Attachment{
+		Content:  ical.Calendar{Method: ical.MethodRequest, Events: []ical.Event{appointmentCalendarEvent(appt, leadInfo)}}.Encode(),
+		FileName: calendarInviteAttachment,
+		MIMEType: ical.MIMEType,
+	}
+}
+
+// GetCalendarFeed returns whether the user has an iCalendar feed enabled.
+func (s *Service) GetCalendarFeed(ctx context.Context, userID uuid.UUID, tenantID uuid.UUID) (*transport.CalendarFeedResponse, error) {
+	feed, err := s.repo.GetCalendarFeed(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return &transport.CalendarFeedResponse{Enabled: false}, nil
+	}
+	return toCalendarFeedResponse(feed, ""), nil
+}
+
+// CreateCalendarFeed issues a new secret feed URL for the user. Any previously issued URL
+// stops working, which doubles as the way to revoke a leaked link.
+func (s *Service) CreateCalendarFeed(ctx context.Context, userID uuid.UUID, tenantID uuid.UUID) (*transport.CalendarFeedResponse, error) {
+	if s.calendarFeedBaseURL == "" {
+		return nil, apperr.BadRequest("calendar feeds are not configured")
+	}
+	rawToken, err := token.GenerateRandomToken(calendarFeedTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := s.repo.ReplaceCalendarFeedToken(ctx, tenantID, userID, token.HashSHA256(rawToken))
+	if err != nil {
+		return nil, err
+	}
+	feedURL := strings.TrimRight(s.calendarFeedBaseURL, "/") + calendarFeedPath + rawToken + calendarFeedSuffix
+	return toCalendarFeedResponse(feed, feedURL), nil
+}
+
+// DeleteCalendarFeed disables the iCalendar feed of the user.
+func (s *Service) DeleteCalendarFeed(ctx context.Context, userID uuid.UUID, tenantID uuid.UUID) error {
+	return s.repo.DeleteCalendarFeed(ctx, tenantID, userID)
+}
+
+// RenderCalendarFeed renders the scheduled appointments of the feed owner. The token is
+// the only credential, so unknown tokens are reported as not found.
+func (s *Service) RenderCalendarFeed(ctx context.Context, rawToken string) ([]byte, error) {
+	rawToken = strings.TrimSuffix(strings.TrimSpace(rawToken), calendarFeedSuffix)
+	if rawToken == "" {
+		return nil, apperr.NotFound("calendar feed not found")
+	}
+	feed, err := s.repo.TouchCalendarFeedByTokenHash(ctx, token.HashSHA256(rawToken))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	appts, err := s.repo.ListForDateRange(ctx, feed.OrganizationID, feed.UserID, now.Add(-calendarFeedLookback), now.Add(calendarFeedLookahead))
+	if err != nil {
+		return nil, err
+	}
+
+	cal := ical.Calendar{Name: calendarFeedName, Method: ical.MethodPublish, Events: make([]ical.Event, 0, len(appts))}
+	for i := range appts {
+		cal.Events = append(cal.Events, appointmentCalendarEvent(&appts[i], nil))
+	}
+	return cal.Encode(), nil
+}
+
+func toCalendarFeedResponse(feed *repository.CalendarFeed, feedURL string) *transport.CalendarFeedResponse {
+	createdAt := feed.CreatedAt
+	return &transport.CalendarFeedResponse{
+		Enabled:        true,
+		URL:            feedURL,
+		CreatedAt:      &createdAt,
+		LastAccessedAt: feed.LastAccessedAt,
+	}
+}
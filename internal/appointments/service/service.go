@@ -1,7 +1,9 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -16,6 +18,7 @@ import (
 	"portal_final_backend/internal/notification/sse"
 	"portal_final_backend/internal/scheduler"
 	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/ical"
 	"portal_final_backend/platform/sanitize"
 	"portal_final_backend/platform/timekit"
 
@@ -46,6 +49,8 @@ type Service struct {
 	storage           storage.StorageService
 	attachmentBucket  string
 	timelineRecorder  leadsrepo.TimelineEventStore
+	// calendarFeedBaseURL is the public API origin used to build iCalendar feed URLs.
+	calendarFeedBaseURL string
 }
 
 type Dependencies struct {
@@ -57,6 +62,8 @@ type Dependencies struct {
 	Storage           storage.StorageService
 	AttachmentBucket  string
 	TimelineRecorder  leadsrepo.TimelineEventStore
+	// CalendarFeedBaseURL enables per-agent iCalendar feeds when set.
+	CalendarFeedBaseURL string
 }
 
 // New creates a new appointments service
@@ -70,6 +77,8 @@ func New(deps Dependencies) *Service {
 		storage:           deps.Storage,
 		attachmentBucket:  deps.AttachmentBucket,
 		timelineRecorder:  deps.TimelineRecorder,
+
+		calendarFeedBaseURL: deps.CalendarFeedBaseURL,
 	}
 }
 
@@ -133,23 +142,56 @@ func (s *Service) Create(ctx context.Context, userID uuid.UUID, isAdmin bool, te
 	}
 
 	appt := s.buildAppointment(userID, tenantID, req)
-	if err := s.repo.Create(ctx, appt); err != nil {
+	leadInfo := s.getLeadInfoIfPresent(ctx, appt.LeadID, tenantID)
+	scheduled := appt.Status == string(transport.AppointmentStatusScheduled)
+
+	var pendingEvents []events.Event
+	if scheduled {
+		pendingEvents = append(pendingEvents, s.appointmentCreatedEvent(ctx, tenantID, appt, leadInfo))
+	}
+	if err := s.repo.Create(ctx, appt, pendingEvents...); err != nil {
 		return nil, err
 	}
 
-	leadInfo := s.getLeadInfoIfPresent(ctx, appt.LeadID, tenantID)
 	s.sendConfirmationEmailIfNeeded(ctx, req.SendConfirmationEmail, appt, leadInfo, tenantID)
 
 	resp := appt.ToResponse(leadInfo)
 
-	if appt.Status == string(transport.AppointmentStatusScheduled) {
+	if scheduled {
 		s.publishScheduledAppointment(ctx, tenantID, appt, leadInfo)
 	}
 
 	return &resp, nil
 }
 
-// publishScheduledAppointment broadcasts SSE events, publishes to the event bus, and schedules reminders for a newly scheduled appointment.
+// appointmentCreatedEvent builds the AppointmentCreated event that Create stores
+// with the appointment.
+func (s *Service) appointmentCreatedEvent(ctx context.Context, tenantID uuid.UUID, appt *repository.Appointment, leadInfo *transport.AppointmentLeadInfo) events.AppointmentCreated {
+	evt := events.AppointmentCreated{
+		BaseEvent:      events.NewBaseEvent(),
+		AppointmentID:  appt.ID,
+		OrganizationID: appt.OrganizationID,
+		LeadID:         appt.LeadID,
+		LeadServiceID:  appt.LeadServiceID,
+		UserID:         appt.UserID,
+		Type:           appt.Type,
+		Title:          appt.Title,
+		StartTime:      appt.StartTime,
+		EndTime:        appt.EndTime,
+		Location:       getOptionalString(appt.Location),
+		AllDay:         appt.AllDay,
+	}
+	if leadInfo != nil {
+		evt.ConsumerName = formatConsumerName(leadInfo.FirstName, leadInfo.LastName)
+		evt.ConsumerPhone = leadInfo.Phone
+	}
+	if appt.LeadID != nil {
+		evt.ConsumerEmail = s.getLeadEmail(ctx, *appt.LeadID, tenantID)
+	}
+	return evt
+}
+
+// publishScheduledAppointment broadcasts SSE events and schedules reminders for a newly scheduled appointment.
 func (s *Service) publishScheduledAppointment(ctx context.Context, tenantID uuid.UUID, appt *repository.Appointment, leadInfo *transport.AppointmentLeadInfo) {
 	s.publishSSE(tenantID, sse.Event{
 		Type:    sse.EventAppointmentCreated,
@@ -178,30 +220,6 @@ func (s *Service) publishScheduledAppointment(ctx context.Context, tenantID uuid
 		},
 	})
 
-	if s.eventBus != nil {
-		evt := events.AppointmentCreated{
-			BaseEvent:      events.NewBaseEvent(),
-			AppointmentID:  appt.ID,
-			OrganizationID: appt.OrganizationID,
-			LeadID:         appt.LeadID,
-			LeadServiceID:  appt.LeadServiceID,
-			UserID:         appt.UserID,
-			Type:           appt.Type,
-			Title:          appt.Title,
-			StartTime:      appt.StartTime,
-			EndTime:        appt.EndTime,
-			Location:       getOptionalString(appt.Location),
-		}
-		if leadInfo != nil {
-			evt.ConsumerName = formatConsumerName(leadInfo.FirstName, leadInfo.LastName)
-			evt.ConsumerPhone = leadInfo.Phone
-		}
-		if appt.LeadID != nil {
-			evt.ConsumerEmail = s.getLeadEmail(ctx, *appt.LeadID, tenantID)
-		}
-		s.eventBus.Publish(ctx, evt)
-	}
-
 	if s.reminderScheduler != nil && appt.Type == string(transport.AppointmentTypeLeadVisit) && leadInfo != nil && leadInfo.Phone != "" {
 		reminderAt := appt.StartTime.Add(-24 * time.Hour)
 		if reminderAt.After(time.Now()) {
@@ -300,7 +318,7 @@ func (s *Service) sendConfirmationEmailIfNeeded(ctx context.Context, sendEmail *
 	if consumerEmail := s.getLeadEmail(ctx, *appt.LeadID, tenantID); consumerEmail != "" {
 		nlLoc := timekit.ResolveLocation(defaultTimezone)
 		scheduledDate := appt.StartTime.In(nlLoc).Format("Monday, January 2, 2006 at 15:04")
-		_ = s.emailSender.SendVisitInviteEmail(ctx, consumerEmail, leadInfo.FirstName, scheduledDate, leadInfo.Address)
+		_ = s.emailSender.SendVisitInviteEmail(ctx, consumerEmail, leadInfo.FirstName, scheduledDate, leadInfo.Address, appointmentInviteAttachment(appt, leadInfo))
 	}
 }
 
@@ -345,6 +363,7 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, is
 		return nil, apperr.Forbidden("not authorized to update this appointment")
 	}
 
+	previous := *appt
 	applyAppointmentUpdates(appt, req)
 
 	if !appt.EndTime.After(appt.StartTime) {
@@ -358,11 +377,15 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, is
 	}
 
 	appt.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, appt); err != nil {
+	leadInfo := s.getLeadInfoIfPresent(ctx, appt.LeadID, tenantID)
+	var pendingEvents []events.Event
+	if appt.Status == string(transport.AppointmentStatusScheduled) && calendarDetailsChanged(&previous, appt) {
+		pendingEvents = append(pendingEvents, s.appointmentUpdatedEvent(ctx, tenantID, appt, leadInfo))
+	}
+	if err := s.repo.Update(ctx, appt, pendingEvents...); err != nil {
 		return nil, err
 	}
 
-	leadInfo := s.getLeadInfoIfPresent(ctx, appt.LeadID, tenantID)
 	resp := appt.ToResponse(leadInfo)
 
 	// Broadcast appointment update via SSE
@@ -397,6 +420,47 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, is
 	return &resp, nil
 }
 
+// appointmentUpdatedEvent builds the AppointmentUpdated event for a scheduled
+// appointment that moved or changed, so consumers can resend an updated calendar
+// invite. Update stores it with the change.
+func (s *Service) appointmentUpdatedEvent(ctx context.Context, tenantID uuid.UUID, appt *repository.Appointment, leadInfo *transport.AppointmentLeadInfo) events.AppointmentUpdated {
+	evt := events.AppointmentUpdated{
+		BaseEvent:      events.NewBaseEvent(),
+		AppointmentID:  appt.ID,
+		OrganizationID: appt.OrganizationID,
+		LeadID:         appt.LeadID,
+		LeadServiceID:  appt.LeadServiceID,
+		UserID:         appt.UserID,
+		Type:           appt.Type,
+		Title:          appt.Title,
+		StartTime:      appt.StartTime,
+		EndTime:        appt.EndTime,
+		AllDay:         appt.AllDay,
+		Sequence:       ical.SequenceSince(appt.CreatedAt, appt.UpdatedAt),
+		Location:       getOptionalString(appt.Location),
+	}
+	if leadInfo != nil {
+		evt.ConsumerName = formatConsumerName(leadInfo.FirstName, leadInfo.LastName)
+		evt.ConsumerPhone = leadInfo.Phone
+		if evt.Location == "" {
+			evt.Location = leadInfo.Address
+		}
+	}
+	if appt.LeadID != nil {
+		evt.ConsumerEmail = s.getLeadEmail(ctx, *appt.LeadID, tenantID)
+	}
+	return evt
+}
+
+// calendarDetailsChanged reports whether an update affects what a calendar invite shows.
+func calendarDetailsChanged(before, after *repository.Appointment) bool {
+	return !before.StartTime.Equal(after.StartTime) ||
+		!before.EndTime.Equal(after.EndTime) ||
+		before.AllDay != after.AllDay ||
+		before.Title != after.Title ||
+		getOptionalString(before.Location) != getOptionalString(after.Location)
+}
+
 // applyAppointmentUpdates applies partial updates from the request to the appointment.
 func applyAppointmentUpdates(appt *repository.Appointment, req transport.UpdateAppointmentRequest) {
 	if req.Title != nil {
@@ -441,7 +505,16 @@ func (s *Service) UpdateStatus(ctx context.Context, id uuid.UUID, userID uuid.UU
 		return &resp, nil
 	}
 
-	if err := s.repo.UpdateStatus(ctx, id, tenantID, string(req.Status)); err != nil {
+	if err := s.repo.UpdateStatus(ctx, id, tenantID, string(req.Status), events.AppointmentStatusChanged{
+		BaseEvent:      events.NewBaseEvent(),
+		AppointmentID:  appt.ID,
+		OrganizationID: appt.OrganizationID,
+		LeadID:         appt.LeadID,
+		LeadServiceID:  appt.LeadServiceID,
+		UserID:         appt.UserID,
+		OldStatus:      oldStatus,
+		NewStatus:      string(req.Status),
+	}); err != nil {
 		return nil, err
 	}
 
@@ -481,19 +554,6 @@ func (s *Service) UpdateStatus(ctx context.Context, id uuid.UUID, userID uuid.UU
 		},
 	})
 
-	if s.eventBus != nil {
-		s.eventBus.Publish(ctx, events.AppointmentStatusChanged{
-			BaseEvent:      events.NewBaseEvent(),
-			AppointmentID:  appt.ID,
-			OrganizationID: appt.OrganizationID,
-			LeadID:         appt.LeadID,
-			LeadServiceID:  appt.LeadServiceID,
-			UserID:         appt.UserID,
-			OldStatus:      oldStatus,
-			NewStatus:      string(req.Status),
-		})
-	}
-
 	return &resp, nil
 }
 
@@ -509,22 +569,14 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID, is
 		return apperr.Forbidden("not authorized to delete this appointment")
 	}
 
-	if err := s.repo.Delete(ctx, id, tenantID); err != nil {
-		return err
-	}
-
-	if s.eventBus != nil {
-		s.eventBus.Publish(ctx, events.AppointmentDeleted{
-			BaseEvent:      events.NewBaseEvent(),
-			AppointmentID:  id,
-			OrganizationID: tenantID,
-			LeadID:         appt.LeadID,
-			LeadServiceID:  appt.LeadServiceID,
-			UserID:         userID,
-		})
-	}
-
-	return nil
+	return s.repo.Delete(ctx, id, tenantID, events.AppointmentDeleted{
+		BaseEvent:      events.NewBaseEvent(),
+		AppointmentID:  id,
+		OrganizationID: tenantID,
+		LeadID:         appt.LeadID,
+		LeadServiceID:  appt.LeadServiceID,
+		UserID:         userID,
+	})
 }
 
 // List retrieves appointments with filtering
@@ -697,15 +749,7 @@ func (s *Service) GetVisitReport(ctx context.Context, appointmentID uuid.UUID, u
 		return nil, apperr.NotFound("visit report not found")
 	}
 
-	return &transport.AppointmentVisitReportResponse{
-		AppointmentID:       report.AppointmentID,
-		Measurements:        report.Measurements,
-		MeasurementProducts: report.MeasurementProducts,
-		AccessDifficulty:    toAccessDifficulty(report.AccessDifficulty),
-		Notes:               report.Notes,
-		CreatedAt:           report.CreatedAt,
-		UpdatedAt:           report.UpdatedAt,
-	}, nil
+	return toVisitReportResponse(report), nil
 }
 
 func (s *Service) UpsertVisitReport(ctx context.Context, appointmentID uuid.UUID, userID uuid.UUID, isAdmin bool, tenantID uuid.UUID, req transport.UpsertVisitReportRequest) (*transport.AppointmentVisitReportResponse, error) {
@@ -753,18 +797,12 @@ func (s *Service) UpsertVisitReport(ctx context.Context, appointmentID uuid.UUID
 			LeadID:        *leadID,
 			LeadServiceID: *leadServiceID,
 			TenantID:      tenantID,
+			// New measurements change what can be quoted, so the estimator re-runs on them.
+			MeasurementsChanged: measurementsChanged(existing, saved),
 		})
 	}
 
-	return &transport.AppointmentVisitReportResponse{
-		AppointmentID:       saved.AppointmentID,
-		Measurements:        saved.Measurements,
-		MeasurementProducts: saved.MeasurementProducts,
-		AccessDifficulty:    toAccessDifficulty(saved.AccessDifficulty),
-		Notes:               saved.Notes,
-		CreatedAt:           saved.CreatedAt,
-		UpdatedAt:           saved.UpdatedAt,
-	}, nil
+	return toVisitReportResponse(saved), nil
 }
 
 // Attachments
@@ -1385,6 +1423,56 @@ func (s *Service) ensureAccess(ctx context.Context, appointmentID uuid.UUID, use
 	return appt, nil
 }
 
+func toVisitReportResponse(report *repository.VisitReport) *transport.AppointmentVisitReportResponse {
+	return &transport.AppointmentVisitReportResponse{
+		AppointmentID:       report.AppointmentID,
+		Measurements:        report.Measurements,
+		MeasurementProducts: report.MeasurementProducts,
+		AccessDifficulty:    toAccessDifficulty(report.AccessDifficulty),
+		Notes:               report.Notes,
+		CreatedAt:           report.CreatedAt,
+		UpdatedAt:           report.UpdatedAt,
+	}
+}
+
+// measurementsChanged reports whether the saved report carries measurements
+// that differ from the previous version of the report.
+func measurementsChanged(previous *repository.VisitReport, saved *repository.VisitReport) bool {
+	var prevMeasurements *string
+	var prevProducts json.RawMessage
+	if previous != nil {
+		prevMeasurements = previous.Measurements
+		prevProducts = previous.MeasurementProducts
+	}
+	if strings.TrimSpace(derefString(prevMeasurements)) != strings.TrimSpace(derefString(saved.Measurements)) {
+		return true
+	}
+	return !jsonEqual(prevProducts, saved.MeasurementProducts)
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	return bytes.Equal(compactJSON(a), compactJSON(b))
+}
+
+// compactJSON strips insignificant whitespace and maps null to empty.
+func compactJSON(raw json.RawMessage) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return bytes.TrimSpace(raw)
+	}
+	if bytes.Equal(buf.Bytes(), []byte("null")) {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
 func toAccessDifficulty(value *string) *transport.AccessDifficulty {
 	if value == nil {
 		return nil
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"portal_final_backend/internal/appointments/repository"
+	"portal_final_backend/internal/appointments/transport"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/notification/sse"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// GetVisitExecution returns the on-site execution of an appointment together
+// with its field report and attachments.
+func (s *Service) GetVisitExecution(ctx context.Context, appointmentID uuid.UUID, userID uuid.UUID, isAdmin bool, tenantID uuid.UUID) (*transport.VisitExecutionResponse, error) {
+	if _, err := s.ensureAccess(ctx, appointmentID, userID, isAdmin, tenantID); err != nil {
+		return nil, err
+	}
+	execution, err := s.repo.GetVisitExecution(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if execution == nil {
+		return nil, apperr.NotFound("appointment is not checked in")
+	}
+	return s.buildVisitExecutionResponse(ctx, execution, nil)
+}
+
+// CheckIn records the arrival of the agent at the visit address.
+func (s *Service) CheckIn(ctx context.Context, appointmentID uuid.UUID, userID uuid.UUID, isAdmin bool, tenantID uuid.UUID, req transport.CheckInVisitRequest) (*transport.VisitExecutionResponse, error) {
+	appt, err := s.ensureAccess(ctx, appointmentID, userID, isAdmin, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if appt.Status != string(transport.AppointmentStatusScheduled) {
+		return nil, apperr.Conflict("only scheduled appointments can be checked in")
+	}
+
+	fix := toGeoFix(req.Location)
+	execution, err := s.repo.CheckInVisit(ctx, appointmentID, tenantID, userID, time.Now(), fix)
+	if err != nil {
+		return nil, err
+	}
+	if execution == nil {
+		return nil, apperr.Conflict("appointment is already checked in")
+	}
+
+	s.recordVisitTimeline(ctx, appt, leadsrepo.EventTypeVisitStarted, "Bezoek gestart",
+		fmt.Sprintf("Ingecheckt voor afspraak %s", appt.Title),
+		map[string]any{
+			"checkedInAt": execution.CheckedInAt,
+			"location":    geoFixMetadata(execution.CheckIn),
+		})
+	s.publishVisitExecutionSSE(appt, "visit_checked_in", fmt.Sprintf("Ingecheckt bij afspraak: %s", appt.Title))
+
+	return s.buildVisitExecutionResponse(ctx, execution, nil)
+}
+
+// CheckOut closes a checked-in visit. The field report and photos are stored
+// first so a failed check-out can simply be retried, after which the
+// appointment is marked completed.
+func (s *Service) CheckOut(ctx context.Context, appointmentID uuid.UUID, userID uuid.UUID, isAdmin bool, tenantID uuid.UUID, req transport.CheckOutVisitRequest) (*transport.VisitExecutionResponse, error) {
+	appt, err := s.ensureAccess(ctx, appointmentID, userID, isAdmin, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	execution, err := s.repo.GetVisitExecution(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if execution == nil {
+		return nil, apperr.Conflict("appointment is not checked in")
+	}
+	if execution.CheckedOutAt != nil {
+		return nil, apperr.Conflict("appointment is already checked out")
+	}
+
+	report, err := s.UpsertVisitReport(ctx, appointmentID, userID, isAdmin, tenantID, req.Report)
+	if err != nil {
+		return nil, err
+	}
+	for _, photo := range req.Photos {
+		if _, err := s.CreateAttachment(ctx, appointmentID, userID, isAdmin, tenantID, photo); err != nil {
+			return nil, err
+		}
+	}
+
+	execution, err = s.repo.CheckOutVisit(ctx, appointmentID, tenantID, userID, time.Now(), toGeoFix(req.Location))
+	if err != nil {
+		return nil, err
+	}
+	if execution == nil {
+		return nil, apperr.Conflict("appointment is already checked out")
+	}
+
+	if appt.Status == string(transport.AppointmentStatusScheduled) {
+		if _, err := s.UpdateStatus(ctx, appointmentID, userID, isAdmin, tenantID, transport.UpdateAppointmentStatusRequest{Status: transport.AppointmentStatusCompleted}); err != nil {
+			return nil, err
+		}
+	}
+
+	duration := execution.CheckedOutAt.Sub(execution.CheckedInAt)
+	s.recordVisitTimeline(ctx, appt, leadsrepo.EventTypeVisitCompleted, "Bezoek afgerond",
+		fmt.Sprintf("Uitgecheckt na %d minuten met %d foto's", int(duration.Minutes()), len(req.Photos)),
+		map[string]any{
+			"checkedInAt":     execution.CheckedInAt,
+			"checkedOutAt":    execution.CheckedOutAt,
+			"durationMinutes": int(duration.Minutes()),
+			"photoCount":      len(req.Photos),
+			"hasMeasurements": report.Measurements != nil || len(report.MeasurementProducts) > 0,
+			"location":        geoFixMetadata(execution.CheckOut),
+		})
+	s.publishVisitExecutionSSE(appt, "visit_checked_out", fmt.Sprintf("Bezoek afgerond: %s", appt.Title))
+
+	return s.buildVisitExecutionResponse(ctx, execution, report)
+}
+
+func (s *Service) buildVisitExecutionResponse(ctx context.Context, execution *repository.VisitExecution, report *transport.AppointmentVisitReportResponse) (*transport.VisitExecutionResponse, error) {
+	resp := &transport.VisitExecutionResponse{
+		AppointmentID:   execution.AppointmentID,
+		CheckedInAt:     execution.CheckedInAt,
+		CheckedInBy:     execution.CheckedInBy,
+		CheckedOutAt:    execution.CheckedOutAt,
+		CheckedOutBy:    execution.CheckedOutBy,
+		CheckInLocation: toGeoFixResponse(execution.CheckIn),
+		Report:          report,
+	}
+	if execution.CheckedOutAt == nil {
+		return resp, nil
+	}
+	checkOut := toGeoFixResponse(execution.CheckOut)
+	resp.CheckOutLocation = &checkOut
+
+	if resp.Report == nil {
+		if saved, err := s.repo.GetVisitReport(ctx, execution.AppointmentID, execution.OrganizationID); err == nil {
+			resp.Report = toVisitReportResponse(saved)
+		}
+	}
+	attachments, err := s.repo.ListAttachments(ctx, execution.AppointmentID, execution.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	for _, att := range attachments {
+		resp.Photos = append(resp.Photos, transport.AppointmentAttachmentResponse{
+			ID:            att.ID,
+			AppointmentID: att.AppointmentID,
+			FileKey:       att.FileKey,
+			FileName:      att.FileName,
+			ContentType:   att.ContentType,
+			SizeBytes:     att.SizeBytes,
+			CreatedAt:     att.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) recordVisitTimeline(ctx context.Context, appt *repository.Appointment, eventType, title, summary string, metadata map[string]any) {
+	if s.timelineRecorder == nil || appt.LeadID == nil {
+		return
+	}
+	metadata["appointmentId"] = appt.ID.String()
+	metadata["appointmentTitle"] = appt.Title
+	metadata["timelineKind"] = "appointment_visit"
+	_, _ = s.timelineRecorder.CreateTimelineEvent(ctx, leadsrepo.CreateTimelineEventParams{
+		LeadID:         *appt.LeadID,
+		ServiceID:      appt.LeadServiceID,
+		OrganizationID: appt.OrganizationID,
+		ActorType:      leadsrepo.ActorTypeUser,
+		ActorName:      "Afspraken",
+		EventType:      eventType,
+		Title:          title,
+		Summary:        leadsrepo.TruncateSummary(summary, leadsrepo.TimelineSummaryMaxLen),
+		Metadata:       metadata,
+	})
+}
+
+func (s *Service) publishVisitExecutionSSE(appt *repository.Appointment, action, message string) {
+	eventData := map[string]interface{}{
+		"appointmentId": appt.ID,
+		"leadId":        appt.LeadID,
+		"leadServiceId": appt.LeadServiceID,
+		"action":        action,
+	}
+	s.publishSSE(appt.OrganizationID, sse.Event{
+		Type:    sse.EventAppointmentUpdated,
+		Message: message,
+		Data:    eventData,
+	})
+	s.publishLeadSSE(appt.LeadID, sse.Event{
+		Type: sse.EventAppointmentUpdated,
+		Data: eventData,
+	})
+}
+
+func toGeoFix(req *transport.GeoFixRequest) repository.GeoFix {
+	if req == nil {
+		return repository.GeoFix{}
+	}
+	return repository.GeoFix{Latitude: req.Latitude, Longitude: req.Longitude, AccuracyM: req.AccuracyM}
+}
+
+func toGeoFixResponse(fix repository.GeoFix) transport.GeoFixResponse {
+	return transport.GeoFixResponse{Latitude: fix.Latitude, Longitude: fix.Longitude, AccuracyM: fix.AccuracyM}
+}
+
+func geoFixMetadata(fix repository.GeoFix) map[string]any {
+	if fix.Latitude == nil || fix.Longitude == nil {
+		return nil
+	}
+	metadata := map[string]any{"latitude": *fix.Latitude, "longitude": *fix.Longitude}
+	if fix.AccuracyM != nil {
+		metadata["accuracyM"] = *fix.AccuracyM
+	}
+	return metadata
+}
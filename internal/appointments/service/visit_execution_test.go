@@ -0,0 +1,33 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"portal_final_backend/internal/appointments/repository"
+)
+
+func TestMeasurementsChanged(t *testing.T) {
+	text := func(v string) *string { return &v }
+	report := func(measurements *string, products string) *repository.VisitReport {
+		return &repository.VisitReport{Measurements: measurements, MeasurementProducts: json.RawMessage(products)}
+	}
+
+	cases := []struct {
+		name     string
+		previous *repository.VisitReport
+		saved    *repository.VisitReport
+		want     bool
+	}{
+		{"first report without measurements", nil, report(nil, ""), false},
+		{"first report with measurements", nil, report(text("dak 42m2"), ""), true},
+		{"only notes changed", report(text("dak 42m2"), `[{"id":1}]`), report(text(" dak 42m2 "), `[ {"id": 1} ]`), false},
+		{"measurement text changed", report(text("dak 42m2"), ""), report(text("dak 48m2"), ""), true},
+		{"products added", report(nil, "null"), report(nil, `[{"id":1}]`), true},
+	}
+	for _, tc := range cases {
+		if got := measurementsChanged(tc.previous, tc.saved); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
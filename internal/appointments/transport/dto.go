@@ -180,6 +180,45 @@ type AppointmentAttachmentResponse struct {
 	ContentType   *string   `json:"contentType,omitempty"`
 }
 
+// --- Visit Execution ---
+
+// GeoFixRequest is the GPS position reported by the device of the field agent.
+type GeoFixRequest struct {
+	Latitude  *float64 `json:"latitude,omitempty" validate:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude,omitempty" validate:"required_with=Latitude,omitempty,min=-180,max=180"`
+	AccuracyM *float64 `json:"accuracyM,omitempty" validate:"omitempty,min=0"`
+}
+
+type CheckInVisitRequest struct {
+	Location *GeoFixRequest `json:"location,omitempty"`
+}
+
+// CheckOutVisitRequest closes the visit with the field report captured on site.
+// Photos must have been uploaded through the presign endpoint first.
+type CheckOutVisitRequest struct {
+	Location *GeoFixRequest                       `json:"location,omitempty"`
+	Report   UpsertVisitReportRequest             `json:"report"`
+	Photos   []CreateAppointmentAttachmentRequest `json:"photos,omitempty" validate:"omitempty,max=50,dive"`
+}
+
+type GeoFixResponse struct {
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	AccuracyM *float64 `json:"accuracyM,omitempty"`
+}
+
+type VisitExecutionResponse struct {
+	CheckedInAt      time.Time                       `json:"checkedInAt"`
+	CheckedOutAt     *time.Time                      `json:"checkedOutAt,omitempty"`
+	CheckedInBy      *uuid.UUID                      `json:"checkedInBy,omitempty"`
+	CheckedOutBy     *uuid.UUID                      `json:"checkedOutBy,omitempty"`
+	AppointmentID    uuid.UUID                       `json:"appointmentId"`
+	CheckInLocation  GeoFixResponse                  `json:"checkInLocation"`
+	CheckOutLocation *GeoFixResponse                 `json:"checkOutLocation,omitempty"`
+	Report           *AppointmentVisitReportResponse `json:"report,omitempty"`
+	Photos           []AppointmentAttachmentResponse `json:"photos,omitempty"`
+}
+
 // --- Availability ---
 
 type CreateAvailabilityRuleRequest struct {
@@ -257,3 +296,12 @@ type DaySlots struct {
 type AvailableSlotsResponse struct {
 	Days []DaySlots `json:"days"`
 }
+
+// CalendarFeedResponse describes the iCalendar subscription feed of the current user.
+// URL is only returned when the feed is created, because only the token hash is stored.
+type CalendarFeedResponse struct {
+	Enabled        bool       `json:"enabled"`
+	URL            string     `json:"url,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt,omitempty"`
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/auditexport/service"
+	"portal_final_backend/internal/auditexport/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+// RegisterAdminRoutes registers the audit export settings and snapshots.
+func (h *Handler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/audit-exports/settings", h.GetSettings)
+	rg.PUT("/audit-exports/settings", h.UpdateSettings)
+	rg.GET("/audit-exports", h.ListSnapshots)
+	rg.GET("/audit-exports/:id/download", h.DownloadSnapshot)
+}
+
+func (h *Handler) GetSettings(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetSettings(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) UpdateSettings(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.UpdateAuditExportSettingsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.UpdateSettings(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// ListSnapshots lists the export snapshots that have not expired yet.
+func (h *Handler) ListSnapshots(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListAuditExportsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListSnapshots(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) DownloadSnapshot(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	snapshotID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "invalid audit export id", nil)
+		return
+	}
+
+	resp, err := h.svc.DownloadSnapshot(c.Request.Context(), tenantID, snapshotID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
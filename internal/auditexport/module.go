@@ -0,0 +1,42 @@
+// Package auditexport provides periodic per-organization compliance exports of
+// audit logs, communications, auth events and API usage to object storage.
+package auditexport
+
+import (
+	"portal_final_backend/internal/auditexport/handler"
+	"portal_final_backend/internal/auditexport/repository"
+	"portal_final_backend/internal/auditexport/service"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+}
+
+func NewModule(pool *pgxpool.Pool, store service.ObjectStore, cfg service.Config, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), store, cfg, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+	}
+}
+
+// Service returns the audit export service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "auditexport"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterAdminRoutes(ctx.Admin)
+}
+
+var _ apphttp.Module = (*Module)(nil)
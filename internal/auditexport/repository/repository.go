@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository persists audit export settings and snapshots and reads the
+// records that go into an export.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Settings is the audit export configuration of an organization.
+type Settings struct {
+	OrganizationID    uuid.UUID
+	Enabled           bool
+	Cadence           string
+	RetentionDays     int
+	LastExportedUntil *time.Time
+	UpdatedAt         time.Time
+}
+
+// Snapshot is one exported object covering [PeriodStart, PeriodEnd).
+type Snapshot struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	ObjectKey      string
+	SizeBytes      int64
+	RecordCounts   map[string]int64
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+// Record is one exported row: the source it came from, when it happened and
+// its JSON representation.
+type Record struct {
+	Category   string
+	Type       string
+	OccurredAt time.Time
+	Data       json.RawMessage
+}
+
+const settingsColumns = `organization_id, enabled, cadence, retention_days, last_exported_until, updated_at`
+
+const snapshotColumns = `id, organization_id, period_start, period_end, object_key, size_bytes, record_counts, created_at, expires_at`
+
+func scanSettings(row pgx.Row) (Settings, error) {
+	var s Settings
+	err := row.Scan(&s.OrganizationID, &s.Enabled, &s.Cadence, &s.RetentionDays, &s.LastExportedUntil, &s.UpdatedAt)
+	return s, err
+}
+
+func scanSnapshot(row pgx.Row) (Snapshot, error) {
+	var s Snapshot
+	err := row.Scan(&s.ID, &s.OrganizationID, &s.PeriodStart, &s.PeriodEnd, &s.ObjectKey, &s.SizeBytes, &s.RecordCounts, &s.CreatedAt, &s.ExpiresAt)
+	return s, err
+}
+
+// GetSettings returns the audit export settings of an organization, or nil
+// when the organization never configured them.
+func (r *Repository) GetSettings(ctx context.Context, organizationID uuid.UUID) (*Settings, error) {
+	s, err := scanSettings(r.pool.QueryRow(ctx, `
+		SELECT `+settingsColumns+`
+		FROM RAC_organization_audit_export_settings
+		WHERE organization_id = $1`, organizationID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get audit export settings: %w", err)
+	}
+	return &s, nil
+}
+
+// UpsertSettings stores the audit export settings of an organization. The
+// export progress is kept.
+func (r *Repository) UpsertSettings(ctx context.Context, s Settings) (Settings, error) {
+	stored, err := scanSettings(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_organization_audit_export_settings (organization_id, enabled, cadence, retention_days)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			cadence = EXCLUDED.cadence,
+			retention_days = EXCLUDED.retention_days,
+			updated_at = now()
+		RETURNING `+settingsColumns, s.OrganizationID, s.Enabled, s.Cadence, s.RetentionDays,
+	))
+	if err != nil {
+		return Settings{}, fmt.Errorf("upsert audit export settings: %w", err)
+	}
+	return stored, nil
+}
+
+// ListEnabledSettings returns the settings of every organization with audit
+// exports enabled.
+func (r *Repository) ListEnabledSettings(ctx context.Context) ([]Settings, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+settingsColumns+`
+		FROM RAC_organization_audit_export_settings
+		WHERE enabled
+		ORDER BY organization_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list audit export settings: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Settings, 0)
+	for rows.Next() {
+		s, err := scanSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit export settings: %w", err)
+		}
+		items = append(items, s)
+	}
+	return items, rows.Err()
+}
+
+// CreateSnapshot records an uploaded export and advances the export progress
+// of its organization to the end of the exported period.
+func (r *Repository) CreateSnapshot(ctx context.Context, s Snapshot) (Snapshot, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	created, err := scanSnapshot(tx.QueryRow(ctx, `
+		INSERT INTO RAC_audit_export_snapshots (organization_id, period_start, period_end, object_key, size_bytes, record_counts, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+snapshotColumns,
+		s.OrganizationID, s.PeriodStart, s.PeriodEnd, s.ObjectKey, s.SizeBytes, s.RecordCounts, s.ExpiresAt,
+	))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("create audit export snapshot: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_organization_audit_export_settings
+		SET last_exported_until = $2
+		WHERE organization_id = $1`, s.OrganizationID, s.PeriodEnd,
+	); err != nil {
+		return Snapshot{}, fmt.Errorf("advance audit export progress: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Snapshot{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return created, nil
+}
+
+// ListSnapshots returns the snapshots of an organization, newest period first.
+func (r *Repository) ListSnapshots(ctx context.Context, organizationID uuid.UUID, limit int) ([]Snapshot, error) {
+	return r.listSnapshots(ctx, `
+		SELECT `+snapshotColumns+`
+		FROM RAC_audit_export_snapshots
+		WHERE organization_id = $1
+		ORDER BY period_end DESC
+		LIMIT $2`, organizationID, limit)
+}
+
+// GetSnapshot returns a snapshot of an organization, or nil when it does not
+// exist.
+func (r *Repository) GetSnapshot(ctx context.Context, organizationID, id uuid.UUID) (*Snapshot, error) {
+	s, err := scanSnapshot(r.pool.QueryRow(ctx, `
+		SELECT `+snapshotColumns+`
+		FROM RAC_audit_export_snapshots
+		WHERE organization_id = $1 AND id = $2`, organizationID, id,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get audit export snapshot: %w", err)
+	}
+	return &s, nil
+}
+
+// ListExpiredSnapshots returns at most limit snapshots that expired before now.
+func (r *Repository) ListExpiredSnapshots(ctx context.Context, now time.Time, limit int) ([]Snapshot, error) {
+	return r.listSnapshots(ctx, `
+		SELECT `+snapshotColumns+`
+		FROM RAC_audit_export_snapshots
+		WHERE expires_at < $1
+		ORDER BY expires_at
+		LIMIT $2`, now, limit)
+}
+
+// DeleteSnapshot removes the row of a snapshot whose object was deleted.
+func (r *Repository) DeleteSnapshot(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM RAC_audit_export_snapshots WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete audit export snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) listSnapshots(ctx context.Context, query string, args ...any) ([]Snapshot, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list audit export snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Snapshot, 0)
+	for rows.Next() {
+		s, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit export snapshot: %w", err)
+		}
+		items = append(items, s)
+	}
+	return items, rows.Err()
+}
+
+// EachRecord streams every record of an organization that happened in
+// [from, to), source by source and oldest first within a source.
+func (r *Repository) EachRecord(ctx context.Context, organizationID uuid.UUID, from, to time.Time, fn func(Record) error) error {
+	for _, src := range recordSources {
+		if err := r.eachSourceRecord(ctx, src, organizationID, from, to, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) eachSourceRecord(ctx context.Context, src recordSource, organizationID uuid.UUID, from, to time.Time, fn func(Record) error) error {
+	rows, err := r.pool.Query(ctx, src.query, organizationID, from, to)
+	if err != nil {
+		return fmt.Errorf("export %s records: %w", src.recordType, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rec := Record{Category: src.category, Type: src.recordType}
+		var data string
+		if err := rows.Scan(&rec.OccurredAt, &data); err != nil {
+			return fmt.Errorf("scan %s record: %w", src.recordType, err)
+		}
+		rec.Data = json.RawMessage(data)
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export %s records: %w", src.recordType, err)
+	}
+	return nil
+}
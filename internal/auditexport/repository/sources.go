@@ -0,0 +1,93 @@
+package repository
+
+// Record categories of an audit export.
+const (
+	CategoryAudit          = "audit"
+	CategoryCommunications = "communications"
+	CategoryAuth           = "auth"
+	CategoryAPIUsage       = "api_usage"
+)
+
+// recordSource is a table exported into audit snapshots. Every query takes
+// the organization and the half-open period [$2, $3) and selects the moment
+// the record happened and its JSON representation. Message bodies and
+// notification payloads are left out: the export is evidence of what was
+// sent to whom and when, not a copy of the correspondence.
+type recordSource struct {
+	category   string
+	recordType string
+	query      string
+}
+
+// memberUsers selects the users of the organization; auth events belong to
+// users, not organizations.
+const memberUsers = `SELECT m.user_id FROM RAC_organization_members m WHERE m.organization_id = $1`
+
+var recordSources = []recordSource{
+	{CategoryAudit, "timeline_event", `
+		SELECT e.created_at, json_build_object(
+			'id', e.id, 'leadId', e.lead_id, 'serviceId', e.service_id,
+			'actorType', e.actor_type, 'actorName', e.actor_name, 'eventType', e.event_type,
+			'title', e.title, 'summary', e.summary, 'metadata', e.metadata,
+			'createdAt', e.created_at)::text
+		FROM lead_timeline_events e
+		WHERE e.organization_id = $1 AND e.created_at >= $2 AND e.created_at < $3
+		ORDER BY e.created_at, e.id`},
+	{CategoryAudit, "quote_activity", `
+		SELECT a.created_at, json_build_object(
+			'id', a.id, 'quoteId', a.quote_id, 'eventType', a.event_type,
+			'message', a.message, 'metadata', a.metadata, 'createdAt', a.created_at)::text
+		FROM RAC_quote_activity a
+		WHERE a.organization_id = $1 AND a.created_at >= $2 AND a.created_at < $3
+		ORDER BY a.created_at, a.id`},
+	{CategoryCommunications, "notification", `
+		SELECT o.created_at, json_build_object(
+			'id', o.id, 'kind', o.kind, 'template', o.template, 'status', o.status,
+			'attempts', o.attempts, 'runAt', o.run_at, 'createdAt', o.created_at,
+			'updatedAt', o.updated_at)::text
+		FROM RAC_notification_outbox o
+		WHERE o.tenant_id = $1 AND o.created_at >= $2 AND o.created_at < $3
+		ORDER BY o.created_at, o.id`},
+	{CategoryCommunications, "whatsapp_message", `
+		SELECT w.created_at, json_build_object(
+			'id', w.id, 'conversationId', w.conversation_id, 'leadId', w.lead_id,
+			'direction', w.direction, 'status', w.status, 'phoneNumber', w.phone_number,
+			'sentAt', w.sent_at, 'readAt', w.read_at, 'failedAt', w.failed_at,
+			'createdAt', w.created_at)::text
+		FROM RAC_whatsapp_messages w
+		WHERE w.organization_id = $1 AND w.created_at >= $2 AND w.created_at < $3
+		ORDER BY w.created_at, w.id`},
+	{CategoryCommunications, "call", `
+		SELECT c.started_at, json_build_object(
+			'id', c.id, 'provider', c.provider, 'direction', c.direction, 'status', c.status,
+			'fromNumber', c.from_number, 'toNumber', c.to_number, 'leadId', c.lead_id,
+			'userId', c.user_id, 'startedAt', c.started_at, 'answeredAt', c.answered_at,
+			'endedAt', c.ended_at, 'durationSeconds', c.duration_seconds)::text
+		FROM RAC_telephony_calls c
+		WHERE c.organization_id = $1 AND c.started_at >= $2 AND c.started_at < $3
+		ORDER BY c.started_at, c.id`},
+	{CategoryAuth, "login_attempt", `
+		SELECT a.created_at, json_build_object(
+			'id', a.id, 'userId', a.user_id, 'email', a.email, 'ipAddress', a.ip_address,
+			'userAgent', a.user_agent, 'success', a.success, 'failureReason', a.failure_reason,
+			'createdAt', a.created_at)::text
+		FROM RAC_auth_login_attempts a
+		WHERE a.user_id IN (` + memberUsers + `) AND a.created_at >= $2 AND a.created_at < $3
+		ORDER BY a.created_at, a.id`},
+	{CategoryAuth, "session", `
+		SELECT s.created_at, json_build_object(
+			'id', s.id, 'userId', s.user_id, 'ipAddress', s.ip_address, 'userAgent', s.user_agent,
+			'createdAt', s.created_at, 'expiresAt', s.expires_at, 'revokedAt', s.revoked_at,
+			'revokedReason', s.revoked_reason)::text
+		FROM RAC_auth_sessions s
+		WHERE s.user_id IN (` + memberUsers + `) AND s.created_at >= $2 AND s.created_at < $3
+		ORDER BY s.created_at, s.id`},
+	{CategoryAPIUsage, "ai_usage", `
+		SELECT u.created_at, json_build_object(
+			'id', u.id, 'provider', u.provider, 'model', u.model,
+			'promptTokens', u.prompt_tokens, 'completionTokens', u.completion_tokens,
+			'createdAt', u.created_at)::text
+		FROM RAC_ai_usage_events u
+		WHERE u.organization_id = $1 AND u.created_at >= $2 AND u.created_at < $3
+		ORDER BY u.created_at, u.id`},
+}
@@ -0,0 +1,353 @@
+// Package service implements per-organization compliance exports: audit logs,
+// communications, auth events and API usage written periodically to a
+// dedicated bucket as gzip-compressed NDJSON snapshots.
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/auditexport/repository"
+	"portal_final_backend/internal/auditexport/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+// Export cadences.
+const (
+	CadenceDaily   = "daily"
+	CadenceWeekly  = "weekly"
+	CadenceMonthly = "monthly"
+)
+
+const (
+	defaultCadence       = CadenceWeekly
+	defaultRetentionDays = 365
+	defaultListLimit     = 50
+	// purgeBatchSize bounds the expired snapshots removed per run; whatever
+	// remains is removed by the next run.
+	purgeBatchSize = 100
+	contentType    = "application/gzip"
+)
+
+type Repository interface {
+	GetSettings(ctx context.Context, organizationID uuid.UUID) (*repository.Settings, error)
+	UpsertSettings(ctx context.Context, s repository.Settings) (repository.Settings, error)
+	ListEnabledSettings(ctx context.Context) ([]repository.Settings, error)
+	CreateSnapshot(ctx context.Context, s repository.Snapshot) (repository.Snapshot, error)
+	ListSnapshots(ctx context.Context, organizationID uuid.UUID, limit int) ([]repository.Snapshot, error)
+	GetSnapshot(ctx context.Context, organizationID, id uuid.UUID) (*repository.Snapshot, error)
+	ListExpiredSnapshots(ctx context.Context, now time.Time, limit int) ([]repository.Snapshot, error)
+	DeleteSnapshot(ctx context.Context, id uuid.UUID) error
+	EachRecord(ctx context.Context, organizationID uuid.UUID, from, to time.Time, fn func(repository.Record) error) error
+}
+
+// ObjectStore is the part of the object storage the exports use.
+type ObjectStore interface {
+	UploadFile(ctx context.Context, bucket, folder, fileName, contentType string, reader io.Reader, size int64) (string, error)
+	GenerateDownloadURL(ctx context.Context, bucket, fileKey string) (*storage.PresignedURL, error)
+	DeleteObject(ctx context.Context, bucket, fileKey string) error
+}
+
+// Config names the bucket the snapshots are written to.
+type Config struct {
+	Bucket string
+}
+
+// RunResult summarizes an export run over all organizations.
+type RunResult struct {
+	Organizations int
+	Snapshots     int
+	Records       int64
+	Purged        int
+	Failed        int
+}
+
+type Service struct {
+	repo  Repository
+	store ObjectStore
+	cfg   Config
+	log   *logger.Logger
+}
+
+func New(repo Repository, store ObjectStore, cfg Config, log *logger.Logger) *Service {
+	return &Service{repo: repo, store: store, cfg: cfg, log: log}
+}
+
+// GetSettings returns the audit export settings of an organization;
+// organizations without stored settings export nothing.
+func (s *Service) GetSettings(ctx context.Context, organizationID uuid.UUID) (transport.AuditExportSettingsResponse, error) {
+	settings, err := s.repo.GetSettings(ctx, organizationID)
+	if err != nil {
+		return transport.AuditExportSettingsResponse{}, err
+	}
+	if settings == nil {
+		return toSettingsResponse(repository.Settings{Cadence: defaultCadence, RetentionDays: defaultRetentionDays}), nil
+	}
+	return toSettingsResponse(*settings), nil
+}
+
+// UpdateSettings replaces the audit export settings of an organization. They
+// take effect on the next scheduled run.
+func (s *Service) UpdateSettings(ctx context.Context, organizationID uuid.UUID, req transport.UpdateAuditExportSettingsRequest) (transport.AuditExportSettingsResponse, error) {
+	stored, err := s.repo.UpsertSettings(ctx, repository.Settings{
+		OrganizationID: organizationID,
+		Enabled:        req.Enabled,
+		Cadence:        req.Cadence,
+		RetentionDays:  req.RetentionDays,
+	})
+	if err != nil {
+		return transport.AuditExportSettingsResponse{}, err
+	}
+	return toSettingsResponse(stored), nil
+}
+
+// ListSnapshots returns the available snapshots of an organization.
+func (s *Service) ListSnapshots(ctx context.Context, organizationID uuid.UUID, req transport.ListAuditExportsRequest) (transport.AuditExportListResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	snapshots, err := s.repo.ListSnapshots(ctx, organizationID, limit)
+	if err != nil {
+		return transport.AuditExportListResponse{}, err
+	}
+	items := make([]transport.AuditExportSnapshotResponse, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		items = append(items, toSnapshotResponse(snapshot))
+	}
+	return transport.AuditExportListResponse{Items: items}, nil
+}
+
+// DownloadSnapshot returns a short-lived download link to a snapshot.
+func (s *Service) DownloadSnapshot(ctx context.Context, organizationID, snapshotID uuid.UUID) (transport.AuditExportDownloadResponse, error) {
+	snapshot, err := s.repo.GetSnapshot(ctx, organizationID, snapshotID)
+	if err != nil {
+		return transport.AuditExportDownloadResponse{}, err
+	}
+	if snapshot == nil {
+		return transport.AuditExportDownloadResponse{}, apperr.NotFound("audit export not found")
+	}
+	presigned, err := s.store.GenerateDownloadURL(ctx, s.cfg.Bucket, snapshot.ObjectKey)
+	if err != nil {
+		return transport.AuditExportDownloadResponse{}, err
+	}
+	return transport.AuditExportDownloadResponse{URL: presigned.URL, ExpiresAt: presigned.ExpiresAt}, nil
+}
+
+// RunDue exports, for every organization with exports enabled, the records
+// since its previous export up to the last completed cadence period, then
+// removes expired snapshots. A failing organization is logged and skipped so
+// it does not hold back the others; its period is exported by the next run.
+func (s *Service) RunDue(ctx context.Context, now time.Time) (RunResult, error) {
+	settings, err := s.repo.ListEnabledSettings(ctx)
+	if err != nil {
+		return RunResult{}, err
+	}
+
+	var result RunResult
+	var errs []error
+	for _, setting := range settings {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		start, end, due := exportPeriod(setting, now)
+		if !due {
+			continue
+		}
+		result.Organizations++
+		orgCtx, err := httpkit.BindTenant(ctx, setting.OrganizationID)
+		if err != nil {
+			result.Failed++
+			errs = append(errs, err)
+			s.log.Error("audit export: failed to bind organization", "organizationId", setting.OrganizationID, "error", err)
+			continue
+		}
+		snapshot, err := s.export(orgCtx, setting, start, end, now)
+		if err != nil {
+			result.Failed++
+			errs = append(errs, err)
+			s.log.Error("audit export: failed to export organization", "organizationId", setting.OrganizationID, "error", err)
+			continue
+		}
+		result.Snapshots++
+		for _, n := range snapshot.RecordCounts {
+			result.Records += n
+		}
+	}
+
+	purged, err := s.purgeExpired(ctx, now)
+	result.Purged = purged
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return result, errors.Join(errs...)
+}
+
+func (s *Service) export(ctx context.Context, setting repository.Settings, start, end, now time.Time) (repository.Snapshot, error) {
+	body, counts, err := s.encode(ctx, setting.OrganizationID, start, end)
+	if err != nil {
+		return repository.Snapshot{}, err
+	}
+
+	folder := fmt.Sprintf("%s/%s", setting.OrganizationID, start.Format("2006"))
+	fileName := fmt.Sprintf("audit_%s_%s.ndjson.gz", start.Format("20060102"), end.Format("20060102"))
+	key, err := s.store.UploadFile(ctx, s.cfg.Bucket, folder, fileName, contentType, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return repository.Snapshot{}, err
+	}
+
+	snapshot, err := s.repo.CreateSnapshot(ctx, repository.Snapshot{
+		OrganizationID: setting.OrganizationID,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		ObjectKey:      key,
+		SizeBytes:      int64(len(body)),
+		RecordCounts:   counts,
+		ExpiresAt:      now.AddDate(0, 0, setting.RetentionDays),
+	})
+	if err != nil {
+		// Without its row the object would never expire.
+		if delErr := s.store.DeleteObject(ctx, s.cfg.Bucket, key); delErr != nil {
+			s.log.Warn("audit export: failed to remove unrecorded snapshot", "key", key, "error", delErr)
+		}
+		return repository.Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// exportLine is one line of a snapshot.
+type exportLine struct {
+	Category       string          `json:"category"`
+	Type           string          `json:"type"`
+	OrganizationID uuid.UUID       `json:"organizationId"`
+	OccurredAt     time.Time       `json:"occurredAt"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// encode writes the records of [start, end) as gzip-compressed NDJSON and
+// counts them per record type.
+func (s *Service) encode(ctx context.Context, organizationID uuid.UUID, start, end time.Time) ([]byte, map[string]int64, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	counts := make(map[string]int64)
+
+	err := s.repo.EachRecord(ctx, organizationID, start, end, func(rec repository.Record) error {
+		counts[rec.Type]++
+		return enc.Encode(exportLine{
+			Category:       rec.Category,
+			Type:           rec.Type,
+			OrganizationID: organizationID,
+			OccurredAt:     rec.OccurredAt.UTC(),
+			Data:           rec.Data,
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, nil, fmt.Errorf("compress audit export: %w", err)
+	}
+	return buf.Bytes(), counts, nil
+}
+
+// purgeExpired removes expired snapshots, object first so a failed delete is
+// retried by the next run.
+func (s *Service) purgeExpired(ctx context.Context, now time.Time) (int, error) {
+	expired, err := s.repo.ListExpiredSnapshots(ctx, now, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, snapshot := range expired {
+		if err := s.store.DeleteObject(ctx, s.cfg.Bucket, snapshot.ObjectKey); err != nil {
+			s.log.Warn("audit export: failed to delete expired snapshot", "snapshotId", snapshot.ID, "error", err)
+			continue
+		}
+		if err := s.repo.DeleteSnapshot(ctx, snapshot.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// exportPeriod returns the period the next export of an organization covers:
+// from the end of its previous export, or the start of the last completed
+// cadence period for a first export, up to the end of the last completed
+// cadence period. Periods are aligned to UTC days, ISO weeks and months.
+func exportPeriod(setting repository.Settings, now time.Time) (time.Time, time.Time, bool) {
+	end := periodBoundary(setting.Cadence, now)
+	start := previousBoundary(setting.Cadence, end)
+	if setting.LastExportedUntil != nil {
+		start = setting.LastExportedUntil.UTC()
+	}
+	return start, end, start.Before(end)
+}
+
+// periodBoundary returns the start of the cadence period now falls in.
+func periodBoundary(cadence string, now time.Time) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch cadence {
+	case CadenceDaily:
+		return day
+	case CadenceMonthly:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		sinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -sinceMonday)
+	}
+}
+
+func previousBoundary(cadence string, boundary time.Time) time.Time {
+	switch cadence {
+	case CadenceDaily:
+		return boundary.AddDate(0, 0, -1)
+	case CadenceMonthly:
+		return boundary.AddDate(0, -1, 0)
+	default:
+		return boundary.AddDate(0, 0, -7)
+	}
+}
+
+func toSettingsResponse(s repository.Settings) transport.AuditExportSettingsResponse {
+	resp := transport.AuditExportSettingsResponse{
+		Enabled:           s.Enabled,
+		Cadence:           s.Cadence,
+		RetentionDays:     s.RetentionDays,
+		LastExportedUntil: s.LastExportedUntil,
+	}
+	if !s.UpdatedAt.IsZero() {
+		updatedAt := s.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+func toSnapshotResponse(s repository.Snapshot) transport.AuditExportSnapshotResponse {
+	counts := s.RecordCounts
+	if counts == nil {
+		counts = map[string]int64{}
+	}
+	return transport.AuditExportSnapshotResponse{
+		ID:           s.ID.String(),
+		PeriodStart:  s.PeriodStart,
+		PeriodEnd:    s.PeriodEnd,
+		SizeBytes:    s.SizeBytes,
+		RecordCounts: counts,
+		CreatedAt:    s.CreatedAt,
+		ExpiresAt:    s.ExpiresAt,
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/auditexport/repository"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	Repository
+	settings  []repository.Settings
+	records   []repository.Record
+	snapshots []repository.Snapshot
+	from, to  time.Time
+}
+
+func (f *fakeRepository) ListEnabledSettings(context.Context) ([]repository.Settings, error) {
+	return f.settings, nil
+}
+
+func (f *fakeRepository) EachRecord(_ context.Context, _ uuid.UUID, from, to time.Time, fn func(repository.Record) error) error {
+	f.from, f.to = from, to
+	for _, rec := range f.records {
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) CreateSnapshot(_ context.Context, s repository.Snapshot) (repository.Snapshot, error) {
+	f.snapshots = append(f.snapshots, s)
+	return s, nil
+}
+
+func (f *fakeRepository) ListExpiredSnapshots(context.Context, time.Time, int) ([]repository.Snapshot, error) {
+	return nil, nil
+}
+
+type fakeStore struct {
+	ObjectStore
+	bucket string
+	body   []byte
+}
+
+func (f *fakeStore) UploadFile(_ context.Context, bucket, folder, fileName, _ string, reader io.Reader, _ int64) (string, error) {
+	f.bucket = bucket
+	body, err := io.ReadAll(reader)
+	f.body = body
+	return folder + "/" + fileName, err
+}
+
+func TestExportPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 11, 14, 30, 0, 0, time.UTC) // a Wednesday
+
+	cases := []struct {
+		cadence    string
+		start, end time.Time
+	}{
+		{CadenceDaily, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{CadenceWeekly, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{CadenceMonthly, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		start, end, due := exportPeriod(repository.Settings{Cadence: tc.cadence}, now)
+		if !due || !start.Equal(tc.start) || !end.Equal(tc.end) {
+			t.Errorf("%s: period = [%v, %v) due=%v, want [%v, %v)", tc.cadence, start, end, due, tc.start, tc.end)
+		}
+	}
+
+	exported := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	if _, _, due := exportPeriod(repository.Settings{Cadence: CadenceWeekly, LastExportedUntil: &exported}, now); due {
+		t.Fatal("a period that was already exported should not be due again")
+	}
+}
+
+func TestRunDueWritesCompressedNDJSON(t *testing.T) {
+	orgID := uuid.New()
+	lastExport := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{
+		settings: []repository.Settings{{OrganizationID: orgID, Enabled: true, Cadence: CadenceDaily, RetentionDays: 90, LastExportedUntil: &lastExport}},
+		records: []repository.Record{
+			{Category: repository.CategoryAudit, Type: "timeline_event", OccurredAt: lastExport.Add(time.Hour), Data: json.RawMessage(`{"title":"Notitie toegevoegd"}`)},
+			{Category: repository.CategoryAuth, Type: "login_attempt", OccurredAt: lastExport.Add(2 * time.Hour), Data: json.RawMessage(`{"success":true}`)},
+		},
+	}
+	store := &fakeStore{}
+	svc := New(repo, store, Config{Bucket: "audit-exports"}, logger.New("development"))
+	now := time.Date(2026, 3, 11, 2, 0, 0, 0, time.UTC)
+
+	result, err := svc.RunDue(context.Background(), now)
+	if err != nil {
+		t.Fatalf("RunDue() error = %v", err)
+	}
+	if result.Snapshots != 1 || result.Records != 2 {
+		t.Fatalf("result = %+v, want one snapshot with two records", result)
+	}
+	if !repo.from.Equal(lastExport) || !repo.to.Equal(time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("exported [%v, %v), want from the previous export up to today", repo.from, repo.to)
+	}
+	snapshot := repo.snapshots[0]
+	if snapshot.RecordCounts["timeline_event"] != 1 || !snapshot.ExpiresAt.Equal(now.AddDate(0, 0, 90)) || store.bucket != "audit-exports" {
+		t.Fatalf("unexpected snapshot %+v in bucket %q", snapshot, store.bucket)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(store.body))
+	if err != nil {
+		t.Fatalf("snapshot is not gzip: %v", err)
+	}
+	var lines []exportLine
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var line exportLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 || lines[1].Category != repository.CategoryAuth || lines[1].OrganizationID != orgID {
+		t.Fatalf("unexpected lines %+v", lines)
+	}
+}
@@ -0,0 +1,50 @@
+// Package transport provides DTOs for organization audit exports.
+package transport
+
+import "time"
+
+// AuditExportSettingsResponse is the audit export configuration of an
+// organization.
+type AuditExportSettingsResponse struct {
+	Enabled           bool       `json:"enabled"`
+	Cadence           string     `json:"cadence"`
+	RetentionDays     int        `json:"retentionDays"`
+	LastExportedUntil *time.Time `json:"lastExportedUntil,omitempty"`
+	UpdatedAt         *time.Time `json:"updatedAt,omitempty"`
+}
+
+// UpdateAuditExportSettingsRequest replaces the audit export configuration of
+// an organization. Snapshots are removed retentionDays after they were made.
+type UpdateAuditExportSettingsRequest struct {
+	Enabled       bool   `json:"enabled"`
+	Cadence       string `json:"cadence" validate:"required,oneof=daily weekly monthly"`
+	RetentionDays int    `json:"retentionDays" validate:"required,gte=30,lte=3650"`
+}
+
+// ListAuditExportsRequest pages through the snapshots of an organization.
+type ListAuditExportsRequest struct {
+	Limit int `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// AuditExportSnapshotResponse is one export: a gzip-compressed NDJSON object
+// with the records of [periodStart, periodEnd).
+type AuditExportSnapshotResponse struct {
+	ID           string           `json:"id"`
+	PeriodStart  time.Time        `json:"periodStart"`
+	PeriodEnd    time.Time        `json:"periodEnd"`
+	SizeBytes    int64            `json:"sizeBytes"`
+	RecordCounts map[string]int64 `json:"recordCounts"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	ExpiresAt    time.Time        `json:"expiresAt"`
+}
+
+// AuditExportListResponse lists the available snapshots, newest first.
+type AuditExportListResponse struct {
+	Items []AuditExportSnapshotResponse `json:"items"`
+}
+
+// AuditExportDownloadResponse is a short-lived link to a snapshot.
+type AuditExportDownloadResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -39,19 +39,32 @@ type AuthService interface {
 
 	// Authentication & Identity
 	SignUp(ctx context.Context, email, plainPassword string, organizationName *string, inviteToken *string) error
-	SignIn(ctx context.Context, email, plainPassword string) (string, string, error)
-	Refresh(ctx context.Context, refreshToken string) (string, string, error)
+	SignIn(ctx context.Context, email, plainPassword string, client service.ClientInfo) (string, string, error)
+	Refresh(ctx context.Context, refreshToken string, client service.ClientInfo) (string, string, error)
 	SignOut(ctx context.Context, refreshToken string, accessToken string) error
 	ForgotPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, rawToken, newPassword string) error
 	VerifyEmail(ctx context.Context, rawToken string) error
 	ResolveInvite(ctx context.Context, rawToken string) (transport.ResolveInviteResponse, error)
 
+	// Sign-in Lockouts
+	ListLockouts(ctx context.Context) ([]transport.LockoutResponse, error)
+	ClearLockout(ctx context.Context, actorID, lockoutID uuid.UUID) error
+
+	// Single Sign-On
+	StartSSO(ctx context.Context, email string, organizationID *uuid.UUID) (string, error)
+	FinishSSO(ctx context.Context, code, state string, client service.ClientInfo) (string, string, error)
+
+	// Sessions
+	ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID *uuid.UUID) ([]transport.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeSessions(ctx context.Context, userID uuid.UUID, keepSessionID *uuid.UUID) (int, error)
+
 	// WebAuthn Passkeys (implemented in webauthn.go)
 	BeginPasskeyRegistration(ctx context.Context, userID uuid.UUID) (interface{}, error)
 	FinishPasskeyRegistration(ctx context.Context, userID uuid.UUID, nickname string, body []byte) error
 	BeginPasskeyLogin(ctx context.Context) (interface{}, error)
-	FinishPasskeyLogin(ctx context.Context, challenge string, body []byte) (string, string, error)
+	FinishPasskeyLogin(ctx context.Context, challenge string, body []byte, client service.ClientInfo) (string, string, error)
 	ListPasskeys(ctx context.Context, userID uuid.UUID) ([]service.PasskeyInfo, error)
 	RenamePasskey(ctx context.Context, userID uuid.UUID, credID []byte, nickname string) error
 	DeletePasskey(ctx context.Context, userID uuid.UUID, credID []byte) error
@@ -234,7 +247,7 @@ func (h *Handler) SignIn(c *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := h.svc.SignIn(c.Request.Context(), req.Email, req.Password)
+	accessToken, refreshToken, err := h.svc.SignIn(c.Request.Context(), req.Email, req.Password, clientInfo(c))
 	if httpkit.HandleError(c, err) {
 		return
 	}
@@ -251,7 +264,7 @@ func (h *Handler) Refresh(c *gin.Context) {
 		return
 	}
 
-	accessToken, newRefreshToken, err := h.svc.Refresh(c.Request.Context(), refreshToken)
+	accessToken, newRefreshToken, err := h.svc.Refresh(c.Request.Context(), refreshToken, clientInfo(c))
 	if httpkit.HandleError(c, err) {
 		if usedCookie {
 			h.clearRefreshCookie(c)
@@ -403,6 +416,11 @@ func (h *Handler) extractRefreshToken(c *gin.Context) (token string, fromCookie
 	return "", false
 }
 
+// clientInfo describes the client a request came from.
+func clientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{IPAddress: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
 func bearerTokenFromHeader(authHeader string) (string, bool) {
 	if !strings.HasPrefix(authHeader, bearerPrefix) {
 		return "", false
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListLockouts lists the account and IP lockouts currently blocking sign-in.
+func (h *Handler) ListLockouts(c *gin.Context) {
+	lockouts, err := h.svc.ListLockouts(c.Request.Context())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, lockouts)
+}
+
+// ClearLockout lifts a sign-in lockout.
+func (h *Handler) ClearLockout(c *gin.Context) {
+	id := httpkit.MustGetIdentity(c)
+	if id == nil {
+		return
+	}
+
+	lockoutID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.ClearLockout(c.Request.Context(), id.UserID(), lockoutID)) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"message": "lockout cleared"})
+}
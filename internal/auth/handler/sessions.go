@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"portal_final_backend/internal/auth/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSessions lists the devices the current user is signed in on.
+func (h *Handler) ListSessions(c *gin.Context) {
+	id := httpkit.MustGetIdentity(c)
+	if id == nil {
+		return
+	}
+
+	sessions, err := h.svc.ListSessions(c.Request.Context(), id.UserID(), httpkit.GetSessionID(c))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, sessions)
+}
+
+// RevokeSession signs the current user out of one session.
+func (h *Handler) RevokeSession(c *gin.Context) {
+	id := httpkit.MustGetIdentity(c)
+	if id == nil {
+		return
+	}
+
+	sessionID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.RevokeSession(c.Request.Context(), id.UserID(), sessionID)) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"message": "session revoked"})
+}
+
+// RevokeSessions signs the current user out of all other sessions, or of all
+// sessions including the current one with ?includeCurrent=true.
+func (h *Handler) RevokeSessions(c *gin.Context) {
+	id := httpkit.MustGetIdentity(c)
+	if id == nil {
+		return
+	}
+
+	keep := httpkit.GetSessionID(c)
+	if c.Query("includeCurrent") == "true" {
+		keep = nil
+	}
+
+	revoked, err := h.svc.RevokeSessions(c.Request.Context(), id.UserID(), keep)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, transport.RevokeSessionsResponse{Revoked: revoked})
+}
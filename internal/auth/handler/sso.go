@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"portal_final_backend/internal/auth/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StartSSO returns the identity provider URL to send the user to for single
+// sign-on.
+func (h *Handler) StartSSO(c *gin.Context) {
+	req, ok := httpkit.BindJSON[transport.StartSSORequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	var organizationID *uuid.UUID
+	if req.OrganizationID != "" {
+		id := uuid.MustParse(req.OrganizationID)
+		organizationID = &id
+	}
+
+	authorizationURL, err := h.svc.StartSSO(c.Request.Context(), req.Email, organizationID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, transport.StartSSOResponse{AuthorizationURL: authorizationURL})
+}
+
+// FinishSSO signs the user in with the code the identity provider returned.
+func (h *Handler) FinishSSO(c *gin.Context) {
+	req, ok := httpkit.BindJSON[transport.FinishSSORequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	accessToken, refreshToken, err := h.svc.FinishSSO(c.Request.Context(), req.Code, req.State, clientInfo(c))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	h.setRefreshCookie(c, refreshToken)
+	httpkit.OK(c, transport.AuthResponse{AccessToken: accessToken, RefreshToken: refreshToken})
+}
@@ -104,7 +104,7 @@ func (h *Handler) FinishPasskeyLogin(c *gin.Context) {
 		return
 	}
 
-	access, refresh, err := h.svc.FinishPasskeyLogin(c.Request.Context(), req.Challenge, req.Credential)
+	access, refresh, err := h.svc.FinishPasskeyLogin(c.Request.Context(), req.Challenge, req.Credential, clientInfo(c))
 	if httpkit.HandleError(c, err) {
 		return
 	}
@@ -98,6 +98,8 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 
 	authGroup.POST("/passkey/login/begin", m.handler.BeginPasskeyLogin)
 	authGroup.POST("/passkey/login/finish", m.handler.FinishPasskeyLogin)
+	authGroup.POST("/sso/start", m.handler.StartSSO)
+	authGroup.POST("/sso/callback", m.handler.FinishSSO)
 
 	// ---------------------------------------------------------
 	// Protected Routes (Requires Valid Session/Token)
@@ -120,6 +122,12 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	passkeysGroup.PATCH("/:credentialId", m.handler.RenamePasskey)
 	passkeysGroup.DELETE("/:credentialId", m.handler.DeletePasskey)
 
+	// Session Management
+	sessionsGroup := usersGroup.Group("/me/sessions")
+	sessionsGroup.GET("", m.handler.ListSessions)
+	sessionsGroup.DELETE("", m.handler.RevokeSessions)
+	sessionsGroup.DELETE("/:id", m.handler.RevokeSession)
+
 	// Legacy / Broad scopes (See auth.go notes on pagination)
 	usersGroup.GET("", m.handler.ListUsers)
 
@@ -127,6 +135,14 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	// Admin Routes
 	// ---------------------------------------------------------
 	ctx.Admin.PUT("/users/:id/roles", m.handler.SetUserRoles)
+
+	// ---------------------------------------------------------
+	// Superadmin Routes
+	// ---------------------------------------------------------
+	if ctx.SuperAdmin != nil {
+		ctx.SuperAdmin.GET("/auth/lockouts", m.handler.ListLockouts)
+		ctx.SuperAdmin.DELETE("/auth/lockouts/:id", m.handler.ClearLockout)
+	}
 }
 
 // Compile-time check to ensure Module implements the interface.
@@ -0,0 +1,292 @@
+// Package oidc implements the parts of OpenID Connect needed for enterprise
+// single sign-on: provider discovery, the authorization code flow with PKCE
+// and ID token verification against the provider's signing keys.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	maxResponseBytes = 1 << 20
+	jwksCacheTTL     = time.Hour
+)
+
+// ErrInvalidIDToken is returned for ID tokens that fail verification.
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// Provider holds the endpoints from a provider's discovery document.
+type Provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// IDToken is a verified ID token.
+type IDToken struct {
+	Subject       string
+	Email         string
+	EmailVerified *bool
+	Claims        jwt.MapClaims
+}
+
+// Client talks to OpenID Connect providers. It caches their signing keys.
+type Client struct {
+	http *http.Client
+
+	mu   sync.Mutex
+	keys map[string]cachedKeys
+}
+
+type cachedKeys struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewClient creates a client. A nil httpClient uses a client with a 10 second
+// timeout.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Client{http: httpClient, keys: map[string]cachedKeys{}}
+}
+
+// Discover loads the discovery document of issuer.
+func (c *Client) Discover(ctx context.Context, issuer string) (Provider, error) {
+	endpoint := strings.TrimRight(issuer, "/") + "/.well-known/openid-configuration"
+	var provider Provider
+	if err := c.getJSON(ctx, endpoint, &provider); err != nil {
+		return Provider{}, fmt.Errorf("discover oidc provider: %w", err)
+	}
+	if strings.TrimRight(provider.Issuer, "/") != strings.TrimRight(issuer, "/") {
+		return Provider{}, fmt.Errorf("discover oidc provider: issuer mismatch %q", provider.Issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return Provider{}, errors.New("discover oidc provider: incomplete discovery document")
+	}
+	return provider, nil
+}
+
+// AuthCodeURL returns the URL to send the user to for signing in.
+func AuthCodeURL(provider Provider, clientID, redirectURI, state, nonce, codeVerifier string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", clientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", "openid email profile")
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return provider.AuthorizationEndpoint + separator + query.Encode()
+}
+
+// CodeChallenge returns the S256 PKCE challenge of a code verifier.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Exchange redeems an authorization code and returns the raw ID token.
+func (c *Client) Exchange(ctx context.Context, provider Provider, clientID, clientSecret, redirectURI, code, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", clientID)
+	form.Set("client_secret", clientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("build oidc token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oidc token request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("oidc token request failed with status %d", resp.StatusCode)
+	}
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return "", fmt.Errorf("decode oidc token response: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return "", errors.New("oidc token response has no id token")
+	}
+	return tokens.IDToken, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of an
+// ID token.
+func (c *Client) VerifyIDToken(ctx context.Context, provider Provider, rawIDToken, clientID, nonce string) (IDToken, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.signingKey(ctx, provider.JWKSURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(provider.Issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return IDToken{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return IDToken{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	token := IDToken{Claims: claims}
+	token.Subject, _ = claims["sub"].(string)
+	if token.Subject == "" {
+		return IDToken{}, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	token.Email, _ = claims["email"].(string)
+	if token.Email == "" {
+		// Azure AD puts the address in preferred_username when the email
+		// claim is not configured.
+		if username, _ := claims["preferred_username"].(string); strings.Contains(username, "@") {
+			token.Email = username
+		}
+	}
+	if verified, ok := claims["email_verified"].(bool); ok {
+		token.EmailVerified = &verified
+	}
+	return token, nil
+}
+
+// StringValues returns a claim as a list of strings. Single strings and
+// arrays of strings are supported; anything else yields nil.
+func (t IDToken) StringValues(claim string) []string {
+	switch value := t.Claims[claim].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// signingKey returns the key with the given ID, refetching the key set once
+// when the ID is unknown to handle key rotation.
+func (c *Client) signingKey(ctx context.Context, jwksURI, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	cached, ok := c.keys[jwksURI]
+	c.mu.Unlock()
+
+	if !ok || time.Since(cached.fetchedAt) > jwksCacheTTL || cached.keys[kid] == nil {
+		keys, err := c.fetchKeys(ctx, jwksURI)
+		if err != nil {
+			return nil, err
+		}
+		cached = cachedKeys{keys: keys, fetchedAt: time.Now()}
+		c.mu.Lock()
+		c.keys[jwksURI] = cached
+		c.mu.Unlock()
+	}
+
+	if key := cached.keys[kid]; key != nil {
+		return key, nil
+	}
+	if kid == "" && len(cached.keys) == 1 {
+		for _, key := range cached.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (c *Client) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := c.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("fetch oidc signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, endpoint string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(target)
+}
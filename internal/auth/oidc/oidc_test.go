@@ -0,0 +1,138 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type testProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	p := &testProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Provider{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func (p *testProvider) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            p.server.URL,
+		"aud":            "client-1",
+		"sub":            "user-123",
+		"email":          "jan@example.com",
+		"email_verified": true,
+		"nonce":          "nonce-1",
+		"groups":         []string{"admins", "staff"},
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	p := newTestProvider(t)
+	client := NewClient(p.server.Client())
+	ctx := context.Background()
+
+	provider, err := client.Discover(ctx, p.server.URL)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	token, err := client.VerifyIDToken(ctx, provider, p.sign(t, p.claims()), "client-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if token.Subject != "user-123" || token.Email != "jan@example.com" {
+		t.Fatalf("unexpected token %+v", token)
+	}
+	if token.EmailVerified == nil || !*token.EmailVerified {
+		t.Fatal("expected email to be verified")
+	}
+	if got := token.StringValues("groups"); len(got) != 2 || got[0] != "admins" {
+		t.Fatalf("unexpected groups %v", got)
+	}
+}
+
+func TestVerifyIDTokenRejectsInvalidTokens(t *testing.T) {
+	p := newTestProvider(t)
+	client := NewClient(p.server.Client())
+	ctx := context.Background()
+	provider, err := client.Discover(ctx, p.server.URL)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims())
+	forged.Header["kid"] = "key-1"
+	forgedToken, _ := forged.SignedString(otherKey)
+
+	mutate := func(key string, value any) string {
+		claims := p.claims()
+		claims[key] = value
+		return p.sign(t, claims)
+	}
+	cases := map[string]string{
+		"wrong signature": forgedToken,
+		"wrong audience":  mutate("aud", "client-2"),
+		"wrong issuer":    mutate("iss", "https://evil.example.com"),
+		"wrong nonce":     mutate("nonce", "nonce-2"),
+		"expired":         mutate("exp", time.Now().Add(-time.Hour).Unix()),
+	}
+	for name, raw := range cases {
+		if _, err := client.VerifyIDToken(ctx, provider, raw, "client-1", "nonce-1"); !errors.Is(err, ErrInvalidIDToken) {
+			t.Fatalf("%s: expected ErrInvalidIDToken, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Lockout scopes
+const (
+	LockoutScopeAccount = "account"
+	LockoutScopeIP      = "ip"
+)
+
+// LoginAttempt is one password sign-in attempt.
+type LoginAttempt struct {
+	UserID        *uuid.UUID
+	Email         string
+	IPAddress     string
+	UserAgent     string
+	Success       bool
+	FailureReason *string
+}
+
+// Lockout blocks sign-in for an account or client IP until LockedUntil.
+type Lockout struct {
+	ID             uuid.UUID
+	Scope          string
+	Subject        string
+	UserID         *uuid.UUID
+	FailedAttempts int
+	LockedUntil    time.Time
+	ClearedAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// RecordLoginAttempt stores a sign-in attempt.
+func (r *Repository) RecordLoginAttempt(ctx context.Context, attempt LoginAttempt) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_auth_login_attempts (user_id, email, ip_address, user_agent, success, failure_reason)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)`,
+		attempt.UserID, attempt.Email, attempt.IPAddress, attempt.UserAgent, attempt.Success, attempt.FailureReason,
+	)
+	if err != nil {
+		return fmt.Errorf("record login attempt: %w", err)
+	}
+	return nil
+}
+
+// CountAccountFailures counts the failed attempts for an email since its last
+// successful sign-in, its last cleared lockout or since, whichever is latest.
+func (r *Repository) CountAccountFailures(ctx context.Context, email, reason string, since time.Time) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM RAC_auth_login_attempts a
+		WHERE a.email = $1
+			AND NOT a.success
+			AND a.failure_reason = $2
+			AND a.created_at > GREATEST(
+				$3::timestamptz,
+				COALESCE((SELECT MAX(s.created_at) FROM RAC_auth_login_attempts s WHERE s.email = $1 AND s.success), '-infinity'),
+				COALESCE((SELECT l.cleared_at FROM RAC_auth_lockouts l WHERE l.scope = $4 AND l.subject = $1), '-infinity')
+			)`,
+		email, reason, since, LockoutScopeAccount,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count account login failures: %w", err)
+	}
+	return count, nil
+}
+
+// CountIPFailures counts the failed attempts from an IP since the given time
+// or its last cleared lockout, whichever is latest.
+func (r *Repository) CountIPFailures(ctx context.Context, ipAddress, reason string, since time.Time) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM RAC_auth_login_attempts a
+		WHERE a.ip_address = $1
+			AND NOT a.success
+			AND a.failure_reason = $2
+			AND a.created_at > GREATEST(
+				$3::timestamptz,
+				COALESCE((SELECT l.cleared_at FROM RAC_auth_lockouts l WHERE l.scope = $4 AND l.subject = $1), '-infinity')
+			)`,
+		ipAddress, reason, since, LockoutScopeIP,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count ip login failures: %w", err)
+	}
+	return count, nil
+}
+
+// GetActiveLockout returns the active lockout of a subject, or ErrNotFound.
+func (r *Repository) GetActiveLockout(ctx context.Context, scope, subject string) (Lockout, error) {
+	row := r.pool.QueryRow(ctx, `
+		SELECT `+lockoutColumns+`
+		FROM RAC_auth_lockouts
+		WHERE scope = $1 AND subject = $2
+			AND cleared_at IS NULL
+			AND locked_until > now()`,
+		scope, subject,
+	)
+	lockout, err := scanLockout(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Lockout{}, ErrNotFound
+	}
+	if err != nil {
+		return Lockout{}, fmt.Errorf("get active lockout: %w", err)
+	}
+	return lockout, nil
+}
+
+// UpsertLockout locks a subject until lockedUntil. It reports whether the
+// subject was not locked before. The previous state is read in the same
+// statement, under a row lock, so concurrent failures report a new lockout
+// once: when the row is inserted concurrently, the losing upsert finds no
+// previous row but did not insert either.
+func (r *Repository) UpsertLockout(ctx context.Context, scope, subject string, userID *uuid.UUID, failedAttempts int, lockedUntil time.Time) (Lockout, bool, error) {
+	var (
+		l       Lockout
+		created bool
+	)
+	err := r.pool.QueryRow(ctx, `
+		WITH previous AS (
+			SELECT cleared_at IS NULL AND locked_until > now() AS active
+			FROM RAC_auth_lockouts
+			WHERE scope = $1 AND subject = $2
+			FOR UPDATE
+		), upserted AS (
+			INSERT INTO RAC_auth_lockouts (scope, subject, user_id, failed_attempts, locked_until)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (scope, subject) DO UPDATE SET
+				user_id = COALESCE(EXCLUDED.user_id, RAC_auth_lockouts.user_id),
+				failed_attempts = EXCLUDED.failed_attempts,
+				locked_until = EXCLUDED.locked_until,
+				cleared_at = NULL,
+				cleared_by = NULL,
+				updated_at = now()
+			RETURNING `+lockoutColumns+`, xmax = 0 AS inserted
+		)
+		SELECT `+lockoutColumns+`, inserted OR EXISTS (SELECT 1 FROM previous WHERE NOT active)
+		FROM upserted`,
+		scope, subject, userID, failedAttempts, lockedUntil,
+	).Scan(&l.ID, &l.Scope, &l.Subject, &l.UserID, &l.FailedAttempts, &l.LockedUntil, &l.ClearedAt, &l.CreatedAt, &l.UpdatedAt, &created)
+	if err != nil {
+		return Lockout{}, false, fmt.Errorf("upsert lockout: %w", err)
+	}
+	return l, created, nil
+}
+
+// ListActiveLockouts returns the lockouts that currently block sign-in.
+func (r *Repository) ListActiveLockouts(ctx context.Context) ([]Lockout, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+lockoutColumns+`
+		FROM RAC_auth_lockouts
+		WHERE cleared_at IS NULL AND locked_until > now()
+		ORDER BY updated_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list active lockouts: %w", err)
+	}
+	defer rows.Close()
+
+	lockouts := make([]Lockout, 0)
+	for rows.Next() {
+		lockout, err := scanLockout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan lockout: %w", err)
+		}
+		lockouts = append(lockouts, lockout)
+	}
+	return lockouts, rows.Err()
+}
+
+// ClearLockout lifts a lockout and resets its failure count.
+func (r *Repository) ClearLockout(ctx context.Context, id uuid.UUID, clearedBy uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_auth_lockouts
+		SET cleared_at = now(), cleared_by = $2, updated_at = now()
+		WHERE id = $1 AND cleared_at IS NULL`,
+		id, clearedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("clear lockout: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// KnownDeviceStatus reports whether the user signed in before, and whether from
+// the given device and network.
+func (r *Repository) KnownDeviceStatus(ctx context.Context, userID uuid.UUID, deviceHash, network string) (seenBefore, knownDevice, knownNetwork bool, err error) {
+	err = r.pool.QueryRow(ctx, `
+		SELECT
+			COUNT(*) > 0,
+			COALESCE(bool_or(device_hash = $2), false),
+			COALESCE(bool_or(network = $3), false)
+		FROM RAC_auth_known_devices
+		WHERE user_id = $1`,
+		userID, deviceHash, network,
+	).Scan(&seenBefore, &knownDevice, &knownNetwork)
+	if err != nil {
+		return false, false, false, fmt.Errorf("check known devices: %w", err)
+	}
+	return seenBefore, knownDevice, knownNetwork, nil
+}
+
+// TouchKnownDevice records a sign-in from a device and network.
+func (r *Repository) TouchKnownDevice(ctx context.Context, userID uuid.UUID, deviceHash, network, userAgent string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_auth_known_devices (user_id, device_hash, network, user_agent)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		ON CONFLICT (user_id, device_hash, network) DO UPDATE SET
+			last_seen_at = now(),
+			user_agent = EXCLUDED.user_agent`,
+		userID, deviceHash, network, userAgent,
+	)
+	if err != nil {
+		return fmt.Errorf("touch known device: %w", err)
+	}
+	return nil
+}
+
+const lockoutColumns = `id, scope, subject, user_id, failed_attempts, locked_until, cleared_at, created_at, updated_at`
+
+func scanLockout(row pgx.Row) (Lockout, error) {
+	var l Lockout
+	err := row.Scan(&l.ID, &l.Scope, &l.Subject, &l.UserID, &l.FailedAttempts, &l.LockedUntil, &l.ClearedAt, &l.CreatedAt, &l.UpdatedAt)
+	return l, err
+}
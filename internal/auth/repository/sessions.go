@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Session is a signed-in device: the chain of refresh tokens rotated from one
+// sign-in.
+type Session struct {
+	ID                   uuid.UUID
+	UserID               uuid.UUID
+	UserAgent            *string
+	IPAddress            *string
+	AccessTokenJTI       *string
+	AccessTokenExpiresAt *time.Time
+	CreatedAt            time.Time
+	LastUsedAt           time.Time
+	ExpiresAt            time.Time
+}
+
+// CreateSession starts a session for a user.
+func (r *Repository) CreateSession(ctx context.Context, userID uuid.UUID, userAgent, ipAddress string, expiresAt time.Time) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_auth_sessions (user_id, user_agent, ip_address, expires_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4)
+		RETURNING id`,
+		userID, userAgent, ipAddress, expiresAt,
+	).Scan(&id)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("create session: %w", err)
+	}
+	return id, nil
+}
+
+// GetRefreshTokenSessionID returns the session a refresh token belongs to, or
+// nil for tokens issued before sessions were tracked.
+func (r *Repository) GetRefreshTokenSessionID(ctx context.Context, tokenHash string) (*uuid.UUID, error) {
+	var sessionID *uuid.UUID
+	err := r.pool.QueryRow(ctx, `SELECT session_id FROM RAC_refresh_tokens WHERE token_hash = $1`, tokenHash).Scan(&sessionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get refresh token session: %w", err)
+	}
+	return sessionID, nil
+}
+
+// CreateSessionRefreshToken stores a refresh token issued for a session.
+func (r *Repository) CreateSessionRefreshToken(ctx context.Context, userID, sessionID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_refresh_tokens (user_id, session_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)`,
+		userID, sessionID, tokenHash, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create session refresh token: %w", err)
+	}
+	return nil
+}
+
+// UpdateSessionTokens records the tokens just issued for a session and marks
+// it as used.
+func (r *Repository) UpdateSessionTokens(ctx context.Context, sessionID uuid.UUID, ipAddress, accessTokenJTI string, accessTokenExpiresAt, expiresAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE RAC_auth_sessions
+		SET ip_address = COALESCE(NULLIF($2, ''), ip_address),
+			access_token_jti = $3,
+			access_token_expires_at = $4,
+			expires_at = $5,
+			last_used_at = now()
+		WHERE id = $1`,
+		sessionID, ipAddress, accessTokenJTI, accessTokenExpiresAt, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update session tokens: %w", err)
+	}
+	return nil
+}
+
+// ListActiveSessions returns the unrevoked, unexpired sessions of a user,
+// most recently used first.
+func (r *Repository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+sessionColumns+`
+		FROM RAC_auth_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY last_used_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list active sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]Session, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// RevokeSession revokes one session of a user and its refresh tokens. It
+// returns ErrNotFound when the session does not exist or is already revoked.
+func (r *Repository) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, reason string) (Session, error) {
+	sessions, err := r.revokeSessions(ctx, `
+		UPDATE RAC_auth_sessions
+		SET revoked_at = now(), revoked_reason = $3
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		RETURNING `+sessionColumns,
+		sessionID, userID, reason,
+	)
+	if err != nil {
+		return Session{}, err
+	}
+	if len(sessions) == 0 {
+		return Session{}, ErrNotFound
+	}
+	return sessions[0], nil
+}
+
+// RevokeSessions revokes all sessions of a user except keepSessionID, if set,
+// along with their refresh tokens and any refresh tokens issued before
+// sessions were tracked.
+func (r *Repository) RevokeSessions(ctx context.Context, userID uuid.UUID, keepSessionID *uuid.UUID, reason string) ([]Session, error) {
+	sessions, err := r.revokeSessions(ctx, `
+		UPDATE RAC_auth_sessions
+		SET revoked_at = now(), revoked_reason = $3
+		WHERE user_id = $1 AND revoked_at IS NULL AND ($2::uuid IS NULL OR id <> $2)
+		RETURNING `+sessionColumns,
+		userID, keepSessionID, reason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_refresh_tokens
+		SET revoked_at = now()
+		WHERE user_id = $1 AND session_id IS NULL AND revoked_at IS NULL`,
+		userID,
+	); err != nil {
+		return nil, fmt.Errorf("revoke legacy refresh tokens: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSessionByRefreshToken revokes the session a refresh token belongs to.
+// It returns ErrNotFound when the token has no active session.
+func (r *Repository) RevokeSessionByRefreshToken(ctx context.Context, tokenHash, reason string) (Session, error) {
+	sessions, err := r.revokeSessions(ctx, `
+		UPDATE RAC_auth_sessions
+		SET revoked_at = now(), revoked_reason = $2
+		WHERE id = (SELECT session_id FROM RAC_refresh_tokens WHERE token_hash = $1) AND revoked_at IS NULL
+		RETURNING `+sessionColumns,
+		tokenHash, reason,
+	)
+	if err != nil {
+		return Session{}, err
+	}
+	if len(sessions) == 0 {
+		return Session{}, ErrNotFound
+	}
+	return sessions[0], nil
+}
+
+// revokeSessions runs an UPDATE ... RETURNING statement that revokes sessions
+// and revokes the refresh tokens of the returned sessions in the same
+// transaction.
+func (r *Repository) revokeSessions(ctx context.Context, query string, args ...any) ([]Session, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin revoke sessions: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("revoke sessions: %w", err)
+	}
+	sessions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (Session, error) {
+		return scanSession(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("revoke sessions: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_refresh_tokens
+		SET revoked_at = now()
+		WHERE revoked_at IS NULL AND session_id = ANY($1)`,
+		ids,
+	); err != nil {
+		return nil, fmt.Errorf("revoke session refresh tokens: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit revoke sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+const sessionColumns = `id, user_id, user_agent, ip_address, access_token_jti, access_token_expires_at, created_at, last_used_at, expires_at`
+
+func scanSession(row pgx.Row) (Session, error) {
+	var s Session
+	err := row.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IPAddress, &s.AccessTokenJTI, &s.AccessTokenExpiresAt, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt)
+	return s, err
+}
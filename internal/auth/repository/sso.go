@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetUserIDBySSOIdentity returns the user linked to an identity provider
+// account, or ErrNotFound.
+func (r *Repository) GetUserIDBySSOIdentity(ctx context.Context, issuer, subject string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT user_id FROM RAC_user_sso_identities
+		WHERE issuer = $1 AND subject = $2`,
+		issuer, subject,
+	).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.UUID{}, ErrNotFound
+	}
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("get sso identity: %w", err)
+	}
+	return userID, nil
+}
+
+// LinkSSOIdentity links an identity provider account to a user and records
+// the sign-in.
+func (r *Repository) LinkSSOIdentity(ctx context.Context, userID, organizationID uuid.UUID, issuer, subject string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_user_sso_identities (issuer, subject, user_id, organization_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (issuer, subject) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			organization_id = EXCLUDED.organization_id,
+			last_login_at = now()`,
+		issuer, subject, userID, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("link sso identity: %w", err)
+	}
+	return nil
+}
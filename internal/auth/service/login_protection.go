@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/netip"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/auth/repository"
+	"portal_final_backend/internal/auth/token"
+	"portal_final_backend/internal/auth/transport"
+	"portal_final_backend/internal/events"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Consecutive failed sign-ins of an account, counted over at most
+	// accountFailureWindow, before it is locked.
+	accountLockoutThreshold = 5
+	accountFailureWindow    = 24 * time.Hour
+	// Failed sign-ins from one IP within ipFailureWindow before it is locked.
+	ipLockoutThreshold = 20
+	ipFailureWindow    = 15 * time.Minute
+
+	// Lockouts start at baseLockoutDuration and double with every further
+	// failure, up to maxLockoutDuration.
+	baseLockoutDuration = time.Minute
+	maxLockoutDuration  = 24 * time.Hour
+
+	failureInvalidCredentials = "invalid_credentials"
+	failureEmailNotVerified   = "email_not_verified"
+
+	tooManyAttemptsMessage = "too many failed sign-in attempts, try again later"
+)
+
+// ClientInfo describes the client a sign-in request came from.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// lockoutDuration returns how long to lock a subject after failures failed
+// attempts, or zero below the threshold.
+func lockoutDuration(failures, threshold int) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+	d := baseLockoutDuration
+	for i := threshold; i < failures && d < maxLockoutDuration; i++ {
+		d *= 2
+	}
+	return min(d, maxLockoutDuration)
+}
+
+// signInNetwork groups IP addresses into the network they are likely to be
+// used from: a /24 for IPv4 and a /48 for IPv6.
+func signInNetwork(ip string) string {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return strings.TrimSpace(ip)
+	}
+	bits := 48
+	if addr.Unmap().Is4() {
+		addr = addr.Unmap()
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return addr.String()
+	}
+	return prefix.String()
+}
+
+func signInDeviceHash(userAgent string) string {
+	return token.HashSHA256(strings.ToLower(strings.TrimSpace(userAgent)))
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+type lockoutSubject struct {
+	scope   string
+	subject string
+}
+
+// checkLockout rejects sign-in while the account or client IP is locked.
+func (s *Service) checkLockout(ctx context.Context, email string, client ClientInfo) error {
+	subjects := []lockoutSubject{{repository.LockoutScopeAccount, email}}
+	if client.IPAddress != "" {
+		subjects = append(subjects, lockoutSubject{repository.LockoutScopeIP, client.IPAddress})
+	}
+	for _, sub := range subjects {
+		lockout, err := s.repo.GetActiveLockout(ctx, sub.scope, sub.subject)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			s.log.Warn("failed to check sign-in lockout", "scope", sub.scope, "error", err)
+			continue
+		}
+		retryAfter := int(math.Ceil(time.Until(lockout.LockedUntil).Seconds()))
+		return apperr.TooManyRequests(tooManyAttemptsMessage).WithDetails(map[string]any{"retryAfterSeconds": retryAfter})
+	}
+	return nil
+}
+
+// recordFailedSignIn stores a failed attempt and locks the account or client
+// IP once they cross their threshold.
+func (s *Service) recordFailedSignIn(ctx context.Context, userID *uuid.UUID, email string, client ClientInfo, reason string) {
+	if err := s.repo.RecordLoginAttempt(ctx, repository.LoginAttempt{
+		UserID:        userID,
+		Email:         email,
+		IPAddress:     client.IPAddress,
+		UserAgent:     client.UserAgent,
+		FailureReason: &reason,
+	}); err != nil {
+		s.log.Warn("failed to record sign-in attempt", "error", err)
+		return
+	}
+	s.log.AuthEvent("signin", email, false, reason)
+	if reason != failureInvalidCredentials {
+		return
+	}
+
+	now := time.Now()
+	failures, err := s.repo.CountAccountFailures(ctx, email, failureInvalidCredentials, now.Add(-accountFailureWindow))
+	if err != nil {
+		s.log.Warn("failed to count account sign-in failures", "error", err)
+	} else if d := lockoutDuration(failures, accountLockoutThreshold); d > 0 {
+		lockout, created, err := s.repo.UpsertLockout(ctx, repository.LockoutScopeAccount, email, userID, failures, now.Add(d))
+		if err != nil {
+			s.log.Warn("failed to lock account", "error", err)
+		} else if created && userID != nil {
+			s.eventBus.Publish(ctx, events.AccountLockedOut{
+				BaseEvent:   events.NewBaseEvent(),
+				UserID:      *userID,
+				Email:       email,
+				IPAddress:   client.IPAddress,
+				LockedUntil: lockout.LockedUntil,
+			})
+		}
+	}
+
+	if client.IPAddress == "" {
+		return
+	}
+	failures, err = s.repo.CountIPFailures(ctx, client.IPAddress, failureInvalidCredentials, now.Add(-ipFailureWindow))
+	if err != nil {
+		s.log.Warn("failed to count ip sign-in failures", "error", err)
+		return
+	}
+	if d := lockoutDuration(failures, ipLockoutThreshold); d > 0 {
+		if _, _, err := s.repo.UpsertLockout(ctx, repository.LockoutScopeIP, client.IPAddress, nil, failures, now.Add(d)); err != nil {
+			s.log.Warn("failed to lock ip", "error", err)
+		}
+	}
+}
+
+// recordSuccessfulSignIn stores the attempt and tells the user when they
+// signed in from a device or network not seen before.
+func (s *Service) recordSuccessfulSignIn(ctx context.Context, user repository.User, email string, client ClientInfo) {
+	if err := s.repo.RecordLoginAttempt(ctx, repository.LoginAttempt{
+		UserID:    &user.ID,
+		Email:     email,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Success:   true,
+	}); err != nil {
+		s.log.Warn("failed to record sign-in attempt", "error", err)
+	}
+	if client.IPAddress == "" {
+		return
+	}
+
+	deviceHash := signInDeviceHash(client.UserAgent)
+	network := signInNetwork(client.IPAddress)
+	seenBefore, knownDevice, knownNetwork, err := s.repo.KnownDeviceStatus(ctx, user.ID, deviceHash, network)
+	if err != nil {
+		s.log.Warn("failed to check known devices", "error", err)
+		return
+	}
+	if seenBefore && (!knownDevice || !knownNetwork) {
+		s.eventBus.Publish(ctx, events.NewSignInDetected{
+			BaseEvent:   events.NewBaseEvent(),
+			UserID:      user.ID,
+			Email:       user.Email,
+			IPAddress:   client.IPAddress,
+			UserAgent:   client.UserAgent,
+			NewDevice:   !knownDevice,
+			NewLocation: !knownNetwork,
+			SignedInAt:  time.Now().UTC(),
+		})
+	}
+	if err := s.repo.TouchKnownDevice(ctx, user.ID, deviceHash, network, client.UserAgent); err != nil {
+		s.log.Warn("failed to record known device", "error", err)
+	}
+}
+
+// ListLockouts returns the lockouts currently blocking sign-in.
+func (s *Service) ListLockouts(ctx context.Context) ([]transport.LockoutResponse, error) {
+	lockouts, err := s.repo.ListActiveLockouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]transport.LockoutResponse, 0, len(lockouts))
+	for _, l := range lockouts {
+		item := transport.LockoutResponse{
+			ID:             l.ID.String(),
+			Scope:          l.Scope,
+			Subject:        l.Subject,
+			FailedAttempts: l.FailedAttempts,
+			LockedUntil:    l.LockedUntil,
+			CreatedAt:      l.CreatedAt,
+			UpdatedAt:      l.UpdatedAt,
+		}
+		if l.UserID != nil {
+			userID := l.UserID.String()
+			item.UserID = &userID
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// ClearLockout lifts a lockout so sign-in is allowed again.
+func (s *Service) ClearLockout(ctx context.Context, actorID, lockoutID uuid.UUID) error {
+	if err := s.repo.ClearLockout(ctx, lockoutID, actorID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.NotFound("lockout not found")
+		}
+		return err
+	}
+	s.log.Info("sign-in lockout cleared", "lockoutId", lockoutID, "actorId", actorID)
+	return nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	cases := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 4, want: 0},
+		{failures: 5, want: time.Minute},
+		{failures: 6, want: 2 * time.Minute},
+		{failures: 8, want: 8 * time.Minute},
+		{failures: 100, want: maxLockoutDuration},
+	}
+	for _, tc := range cases {
+		if got := lockoutDuration(tc.failures, accountLockoutThreshold); got != tc.want {
+			t.Fatalf("lockoutDuration(%d) = %s, want %s", tc.failures, got, tc.want)
+		}
+	}
+}
+
+func TestSignInNetwork(t *testing.T) {
+	cases := map[string]string{
+		"203.0.113.42":          "203.0.113.0/24",
+		"::ffff:203.0.113.42":   "203.0.113.0/24",
+		"2001:db8:1234:5678::1": "2001:db8:1234::/48",
+		"not-an-ip":             "not-an-ip",
+	}
+	for ip, want := range cases {
+		if got := signInNetwork(ip); got != want {
+			t.Fatalf("signInNetwork(%q) = %q, want %q", ip, got, want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"portal_final_backend/internal/auth/oidc"
 	"portal_final_backend/internal/auth/password"
 	"portal_final_backend/internal/auth/repository"
 	"portal_final_backend/internal/auth/token"
@@ -44,6 +45,9 @@ type Service struct {
 	log      *logger.Logger
 	redis    *redis.Client
 	webauthn *webauthn.WebAuthn
+	oidc     *oidc.Client
+	// ssoRedirectURL is where identity providers return users after SSO.
+	ssoRedirectURL string
 }
 
 type Profile struct {
@@ -62,7 +66,7 @@ type Profile struct {
 }
 
 func New(repo *repository.Repository, identity *identityservice.Service, cfg config.AuthServiceConfig, eventBus events.Bus, log *logger.Logger) *Service {
-	return &Service{repo: repo, identity: identity, cfg: cfg, eventBus: eventBus, log: log}
+	return &Service{repo: repo, identity: identity, cfg: cfg, eventBus: eventBus, log: log, oidc: oidc.NewClient(nil)}
 }
 
 func (s *Service) SetAccessTokenBlocklistRedis(client *redis.Client) {
@@ -119,33 +123,50 @@ func (s *Service) SignUp(ctx context.Context, email, plainPassword string, organ
 	return s.enqueueEmailVerification(ctx, user.ID, user.Email)
 }
 
-func (s *Service) SignIn(ctx context.Context, email, plainPassword string) (string, string, error) {
+func (s *Service) SignIn(ctx context.Context, email, plainPassword string, client ClientInfo) (string, string, error) {
+	loginEmail := normalizeLoginEmail(email)
+	if err := s.checkLockout(ctx, loginEmail, client); err != nil {
+		return "", "", err
+	}
+
 	user, err := s.repo.GetUserByEmail(ctx, email)
 	if err != nil {
+		s.recordFailedSignIn(ctx, nil, loginEmail, client, failureInvalidCredentials)
 		return "", "", apperr.Unauthorized(invalidCredentialsMessage)
 	}
 
 	if err := password.Compare(user.PasswordHash, plainPassword); err != nil {
+		s.recordFailedSignIn(ctx, &user.ID, loginEmail, client, failureInvalidCredentials)
 		return "", "", apperr.Unauthorized(invalidCredentialsMessage)
 	}
 
 	if !user.EmailVerified {
+		s.recordFailedSignIn(ctx, &user.ID, loginEmail, client, failureEmailNotVerified)
 		return "", "", apperr.Forbidden("email not verified")
 	}
 
+	if err := s.checkSSOEnforced(ctx, user.ID); err != nil {
+		return "", "", err
+	}
+
 	if err := s.ensureBootstrapSuperAdmin(ctx, user.ID, user.Email); err != nil {
 		return "", "", err
 	}
+	s.recordSuccessfulSignIn(ctx, user, loginEmail, client)
 
-	return s.issueTokens(ctx, user.ID, user.Email)
+	return s.issueTokens(ctx, user.ID, user.Email, nil, client)
 }
 
-func (s *Service) Refresh(ctx context.Context, refreshToken string) (string, string, error) {
+func (s *Service) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (string, string, error) {
 	hash := token.HashSHA256(refreshToken)
 	userID, expiresAt, err := s.repo.GetRefreshToken(ctx, hash)
 	if err != nil {
 		return "", "", apperr.Unauthorized(tokenInvalidMessage)
 	}
+	sessionID, err := s.repo.GetRefreshTokenSessionID(ctx, hash)
+	if err != nil {
+		return "", "", err
+	}
 
 	// Always revoke the used token (consumed or expired) to prevent reuse.
 	_ = s.repo.RevokeRefreshToken(ctx, hash)
@@ -162,11 +183,14 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string) (string, str
 		return "", "", err
 	}
 
-	return s.issueTokens(ctx, userID, user.Email)
+	return s.issueTokens(ctx, userID, user.Email, sessionID, client)
 }
 
 func (s *Service) SignOut(ctx context.Context, refreshToken string, accessToken string) error {
 	hash := token.HashSHA256(refreshToken)
+	if _, err := s.repo.RevokeSessionByRefreshToken(ctx, hash, sessionRevokedSignOut); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
 	if err := s.repo.RevokeRefreshToken(ctx, hash); err != nil {
 		return err
 	}
@@ -230,7 +254,7 @@ func (s *Service) ResetPassword(ctx context.Context, rawToken, newPassword strin
 	}
 
 	_ = s.repo.UseUserToken(ctx, hash, repository.TokenTypePasswordReset)
-	_ = s.repo.RevokeAllRefreshTokens(ctx, userID)
+	s.revokeAllSessions(ctx, userID, sessionRevokedPasswordReset)
 
 	return nil
 }
@@ -254,7 +278,7 @@ func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, currentP
 		return err
 	}
 
-	_ = s.repo.RevokeAllRefreshTokens(ctx, userID)
+	s.revokeAllSessions(ctx, userID, sessionRevokedPasswordChanged)
 	return nil
 }
 
@@ -469,16 +493,21 @@ func (s *Service) blocklistAccessToken(ctx context.Context, accessToken string)
 	}
 
 	jti, _ := claims["jti"].(string)
-	if jti = strings.TrimSpace(jti); jti == "" {
+	expFloat, ok := claims["exp"].(float64)
+	if !ok {
 		return nil
 	}
 
-	expFloat, ok := claims["exp"].(float64)
-	if !ok {
+	return s.blocklistJTI(ctx, jti, time.Unix(int64(expFloat), 0))
+}
+
+// blocklistJTI rejects the access token with the given ID until it expires.
+func (s *Service) blocklistJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	if jti = strings.TrimSpace(jti); s.redis == nil || jti == "" {
 		return nil
 	}
 
-	ttl := time.Until(time.Unix(int64(expFloat), 0))
+	ttl := time.Until(expiresAt)
 	if ttl <= 0 {
 		return nil
 	}
@@ -486,7 +515,9 @@ func (s *Service) blocklistAccessToken(ctx context.Context, accessToken string)
 	return s.redis.Set(ctx, "auth:blocklist:jti:"+jti, "1", ttl).Err()
 }
 
-func (s *Service) issueTokens(ctx context.Context, userID uuid.UUID, email string) (string, string, error) {
+// issueTokens issues an access and refresh token for a session, starting a new
+// session when sessionID is nil.
+func (s *Service) issueTokens(ctx context.Context, userID uuid.UUID, email string, sessionID *uuid.UUID, client ClientInfo) (string, string, error) {
 	roles, err := s.repo.GetUserRoles(ctx, userID)
 	if err != nil {
 		return "", "", err
@@ -499,7 +530,19 @@ func (s *Service) issueTokens(ctx context.Context, userID uuid.UUID, email strin
 		return "", "", err
 	}
 
-	accessToken, err := s.signJWT(userID, email, tenantID, roles, s.cfg.GetAccessTokenTTL(), accessTokenType, s.cfg.GetJWTAccessSecret())
+	now := time.Now()
+	refreshExpiresAt := now.Add(s.cfg.GetRefreshTokenTTL())
+	if sessionID == nil {
+		id, err := s.repo.CreateSession(ctx, userID, client.UserAgent, client.IPAddress, refreshExpiresAt)
+		if err != nil {
+			return "", "", err
+		}
+		sessionID = &id
+	}
+
+	jti := uuid.NewString()
+	accessExpiresAt := now.Add(s.cfg.GetAccessTokenTTL())
+	accessToken, err := s.signJWT(userID, email, tenantID, roles, *sessionID, jti, accessExpiresAt, accessTokenType, s.cfg.GetJWTAccessSecret())
 	if err != nil {
 		return "", "", err
 	}
@@ -510,21 +553,25 @@ func (s *Service) issueTokens(ctx context.Context, userID uuid.UUID, email strin
 	}
 
 	hash := token.HashSHA256(refreshToken)
-	if err := s.repo.CreateRefreshToken(ctx, userID, hash, time.Now().Add(s.cfg.GetRefreshTokenTTL())); err != nil {
+	if err := s.repo.CreateSessionRefreshToken(ctx, userID, *sessionID, hash, refreshExpiresAt); err != nil {
+		return "", "", err
+	}
+	if err := s.repo.UpdateSessionTokens(ctx, *sessionID, client.IPAddress, jti, accessExpiresAt, refreshExpiresAt); err != nil {
 		return "", "", err
 	}
 
 	return accessToken, refreshToken, nil
 }
 
-func (s *Service) signJWT(userID uuid.UUID, email string, tenantID *uuid.UUID, roles []string, ttl time.Duration, tokenType, secret string) (string, error) {
+func (s *Service) signJWT(userID uuid.UUID, email string, tenantID *uuid.UUID, roles []string, sessionID uuid.UUID, jti string, expiresAt time.Time, tokenType, secret string) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":   userID.String(),
 		"email": email,
 		"type":  tokenType,
 		"roles": roles,
-		"jti":   uuid.NewString(),
-		"exp":   time.Now().Add(ttl).Unix(),
+		"sid":   sessionID.String(),
+		"jti":   jti,
+		"exp":   expiresAt.Unix(),
 		"iat":   time.Now().Unix(),
 	}
 	if tenantID != nil {
@@ -15,9 +15,10 @@ func TestSignJWTIncludesEmailSubjectAndExpiry(t *testing.T) {
 	userID := uuid.New()
 	tenantID := uuid.New()
 	roles := []string{"admin", "user"}
+	sessionID := uuid.New()
 	secret := "test-secret"
 
-	tokenString, err := svc.signJWT(userID, "dev_user@company.com", &tenantID, roles, 15*time.Minute, accessTokenType, secret)
+	tokenString, err := svc.signJWT(userID, "dev_user@company.com", &tenantID, roles, sessionID, uuid.NewString(), time.Now().Add(15*time.Minute), accessTokenType, secret)
 	if err != nil {
 		t.Fatalf("signJWT returned error: %v", err)
 	}
@@ -48,6 +49,9 @@ func TestSignJWTIncludesEmailSubjectAndExpiry(t *testing.T) {
 	if got := claims["tenant_id"]; got != tenantID.String() {
 		t.Fatalf("expected tenant_id=%q, got %v", tenantID.String(), got)
 	}
+	if got := claims["sid"]; got != sessionID.String() {
+		t.Fatalf("expected sid=%q, got %v", sessionID.String(), got)
+	}
 	if _, ok := claims["exp"].(float64); !ok {
 		t.Fatalf("expected exp claim to be numeric, got %T", claims["exp"])
 	}
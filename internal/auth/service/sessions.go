@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"portal_final_backend/internal/auth/repository"
+	"portal_final_backend/internal/auth/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Reasons stored when a session is revoked.
+const (
+	sessionRevokedSignOut         = "signed out"
+	sessionRevokedByUser          = "revoked by user"
+	sessionRevokedPasswordChanged = "password changed"
+	sessionRevokedPasswordReset   = "password reset"
+)
+
+// ListSessions returns the active sessions of a user. currentSessionID marks
+// the session the request was made with.
+func (s *Service) ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID *uuid.UUID) ([]transport.SessionResponse, error) {
+	sessions, err := s.repo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]transport.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, transport.SessionResponse{
+			ID:         session.ID.String(),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    currentSessionID != nil && *currentSessionID == session.ID,
+		})
+	}
+	return result, nil
+}
+
+// RevokeSession signs a user out of one session.
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.repo.RevokeSession(ctx, userID, sessionID, sessionRevokedByUser)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.NotFound("session not found")
+	}
+	if err != nil {
+		return err
+	}
+	s.blocklistSessions(ctx, []repository.Session{session})
+	return nil
+}
+
+// RevokeSessions signs a user out of all sessions except keepSessionID, if
+// set, and returns how many were revoked.
+func (s *Service) RevokeSessions(ctx context.Context, userID uuid.UUID, keepSessionID *uuid.UUID) (int, error) {
+	sessions, err := s.repo.RevokeSessions(ctx, userID, keepSessionID, sessionRevokedByUser)
+	if err != nil {
+		return 0, err
+	}
+	s.blocklistSessions(ctx, sessions)
+	return len(sessions), nil
+}
+
+// revokeAllSessions signs a user out everywhere after a credential change.
+func (s *Service) revokeAllSessions(ctx context.Context, userID uuid.UUID, reason string) {
+	sessions, err := s.repo.RevokeSessions(ctx, userID, nil, reason)
+	if err != nil {
+		s.log.Warn("failed to revoke sessions", "userId", userID, "reason", reason, "error", err)
+		return
+	}
+	s.blocklistSessions(ctx, sessions)
+}
+
+// blocklistSessions rejects the latest access token of revoked sessions, so
+// they end right away instead of when the access token expires.
+func (s *Service) blocklistSessions(ctx context.Context, sessions []repository.Session) {
+	for _, session := range sessions {
+		if session.AccessTokenJTI == nil || session.AccessTokenExpiresAt == nil {
+			continue
+		}
+		if err := s.blocklistJTI(ctx, *session.AccessTokenJTI, *session.AccessTokenExpiresAt); err != nil {
+			s.log.Warn("failed to blocklist session access token", "sessionId", session.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/auth/oidc"
+	"portal_final_backend/internal/auth/password"
+	"portal_final_backend/internal/auth/repository"
+	"portal_final_backend/internal/auth/token"
+	identityrepo "portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ssoStateTTL       = 10 * time.Minute
+	ssoStateKeyPrefix = "auth:sso:state:"
+
+	ssoFailedMessage      = "single sign-on failed"
+	ssoRequiredMessage    = "this organization requires single sign-on"
+	ssoUnavailableMessage = "single sign-on not available"
+)
+
+// ssoState is kept in Redis between starting and finishing an SSO sign-in.
+type ssoState struct {
+	OrganizationID uuid.UUID `json:"organizationId"`
+	Nonce          string    `json:"nonce"`
+	CodeVerifier   string    `json:"codeVerifier"`
+}
+
+// SetSSORedirectURL sets the frontend URL identity providers send users back
+// to after signing in.
+func (s *Service) SetSSORedirectURL(url string) {
+	s.ssoRedirectURL = strings.TrimSpace(url)
+}
+
+// StartSSO begins an SSO sign-in for the organization with the given ID, or
+// else for the organization that accepts the domain of email, and returns the
+// URL of the identity provider to send the user to.
+func (s *Service) StartSSO(ctx context.Context, email string, organizationID *uuid.UUID) (string, error) {
+	if s.redis == nil || s.ssoRedirectURL == "" {
+		return "", apperr.Internal(ssoUnavailableMessage)
+	}
+
+	var (
+		config identityrepo.OrganizationSSOConfig
+		err    error
+	)
+	if organizationID != nil {
+		config, err = s.identity.ResolveSSOProvider(ctx, *organizationID)
+	} else {
+		config, err = s.identity.ResolveSSOProviderForEmail(ctx, email)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	provider, err := s.oidc.Discover(ctx, config.IssuerURL)
+	if err != nil {
+		s.log.Warn("sso discovery failed", "organizationId", config.OrganizationID, "error", err)
+		return "", apperr.BadRequest("identity provider unreachable")
+	}
+
+	state, err := token.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := token.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := token.GenerateRandomToken(48)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(ssoState{OrganizationID: config.OrganizationID, Nonce: nonce, CodeVerifier: verifier})
+	if err != nil {
+		return "", err
+	}
+	if err := s.redis.Set(ctx, ssoStateKeyPrefix+token.HashSHA256(state), data, ssoStateTTL).Err(); err != nil {
+		return "", err
+	}
+
+	return oidc.AuthCodeURL(provider, config.ClientID, s.ssoRedirectURL, state, nonce, verifier), nil
+}
+
+// FinishSSO completes an SSO sign-in with the code and state the identity
+// provider returned, provisioning the user when the organization allows it.
+func (s *Service) FinishSSO(ctx context.Context, code, state string, client ClientInfo) (string, string, error) {
+	if s.redis == nil || s.ssoRedirectURL == "" {
+		return "", "", apperr.Internal(ssoUnavailableMessage)
+	}
+
+	// GetDel makes the state single-use.
+	data, err := s.redis.GetDel(ctx, ssoStateKeyPrefix+token.HashSHA256(state)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return "", "", apperr.Unauthorized("sign-in session expired or invalid")
+	}
+	if err != nil {
+		return "", "", err
+	}
+	var st ssoState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return "", "", err
+	}
+
+	config, err := s.identity.ResolveSSOProvider(ctx, st.OrganizationID)
+	if err != nil {
+		return "", "", err
+	}
+	provider, err := s.oidc.Discover(ctx, config.IssuerURL)
+	if err != nil {
+		s.log.Warn("sso discovery failed", "organizationId", config.OrganizationID, "error", err)
+		return "", "", apperr.Unauthorized(ssoFailedMessage)
+	}
+	rawIDToken, err := s.oidc.Exchange(ctx, provider, config.ClientID, config.ClientSecret, s.ssoRedirectURL, code, st.CodeVerifier)
+	if err != nil {
+		s.log.Warn("sso code exchange failed", "organizationId", config.OrganizationID, "error", err)
+		return "", "", apperr.Unauthorized(ssoFailedMessage)
+	}
+	idToken, err := s.oidc.VerifyIDToken(ctx, provider, rawIDToken, config.ClientID, st.Nonce)
+	if err != nil {
+		s.log.Warn("sso id token rejected", "organizationId", config.OrganizationID, "error", err)
+		return "", "", apperr.Unauthorized(ssoFailedMessage)
+	}
+
+	email := normalizeLoginEmail(idToken.Email)
+	if idToken.EmailVerified != nil && !*idToken.EmailVerified {
+		return "", "", apperr.Forbidden("identity provider has not verified this email address")
+	}
+	if _, domain, ok := strings.Cut(email, "@"); !ok || !config.AllowsDomain(domain) {
+		return "", "", apperr.Forbidden("email domain not allowed for this organization")
+	}
+
+	user, created, err := s.resolveSSOUser(ctx, config, provider.Issuer, idToken.Subject, email)
+	if err != nil {
+		return "", "", err
+	}
+	roles, err := s.repo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return "", "", err
+	}
+	if containsString(roles, superAdminRole) {
+		return "", "", apperr.Forbidden("platform administrators cannot sign in through single sign-on")
+	}
+	if err := s.repo.LinkSSOIdentity(ctx, user.ID, config.OrganizationID, provider.Issuer, idToken.Subject); err != nil {
+		return "", "", err
+	}
+	if created || config.RoleClaim != nil {
+		if err := s.repo.SetUserRoles(ctx, user.ID, ssoRoles(config, idToken)); err != nil {
+			return "", "", err
+		}
+	}
+	if !user.EmailVerified {
+		if err := s.repo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return "", "", err
+		}
+	}
+
+	s.log.AuthEvent("sso_signin", email, true, "")
+	s.recordSuccessfulSignIn(ctx, user, email, client)
+	return s.issueTokens(ctx, user.ID, user.Email, nil, client)
+}
+
+// resolveSSOUser finds the user of an identity provider account: by an
+// existing link, else by email, else by provisioning a new member. Existing
+// users must already be members of the SSO organization; an email match alone
+// never moves an account into it.
+func (s *Service) resolveSSOUser(ctx context.Context, config identityrepo.OrganizationSSOConfig, issuer, subject, email string) (repository.User, bool, error) {
+	if userID, err := s.repo.GetUserIDBySSOIdentity(ctx, issuer, subject); err == nil {
+		user, err := s.repo.GetUserByID(ctx, userID)
+		if err != nil {
+			return repository.User{}, false, err
+		}
+		return user, false, s.requireSSOMembership(ctx, user.ID, config.OrganizationID)
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return repository.User{}, false, err
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if err == nil {
+		return user, false, s.requireSSOMembership(ctx, user.ID, config.OrganizationID)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return repository.User{}, false, err
+	}
+	if !config.JITProvisioning {
+		return repository.User{}, false, apperr.Forbidden("no account exists for this email; ask your administrator for an invite")
+	}
+
+	user, err = s.provisionSSOUser(ctx, config.OrganizationID, email)
+	if err != nil {
+		return repository.User{}, false, err
+	}
+	return user, true, nil
+}
+
+// requireSSOMembership rejects users that are not a member of the SSO
+// organization, including users without any organization.
+func (s *Service) requireSSOMembership(ctx context.Context, userID, organizationID uuid.UUID) error {
+	orgID, err := s.identity.GetUserOrganizationID(ctx, userID)
+	if errors.Is(err, identityrepo.ErrNotFound) || (err == nil && orgID != organizationID) {
+		return apperr.Forbidden("account is not a member of this organization; ask your administrator for an invite")
+	}
+	return err
+}
+
+// provisionSSOUser creates a user for a first SSO sign-in. The user gets a
+// random password, so they can only sign in through SSO or after a reset.
+func (s *Service) provisionSSOUser(ctx context.Context, organizationID uuid.UUID, email string) (repository.User, error) {
+	randomPassword, err := token.GenerateRandomToken(32)
+	if err != nil {
+		return repository.User{}, err
+	}
+	hash, err := password.Hash(randomPassword)
+	if err != nil {
+		return repository.User{}, err
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return repository.User{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	user, err := s.repo.CreateUserTx(ctx, tx, email, hash)
+	if err != nil {
+		return repository.User{}, err
+	}
+	if err := s.repo.SetUserRolesTx(ctx, tx, user.ID, []string{defaultUserRole}); err != nil {
+		return repository.User{}, err
+	}
+	if err := s.identity.AddMember(ctx, tx, organizationID, user.ID); err != nil {
+		return repository.User{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return repository.User{}, err
+	}
+
+	s.log.Info("sso user provisioned", "userId", user.ID, "organizationId", organizationID)
+	return user, nil
+}
+
+// ssoRoles maps the role claim of an ID token to application roles, falling
+// back to the default role when no value is mapped.
+func ssoRoles(config identityrepo.OrganizationSSOConfig, idToken oidc.IDToken) []string {
+	roles := make([]string, 0)
+	if config.RoleClaim != nil {
+		for _, value := range idToken.StringValues(*config.RoleClaim) {
+			if role, ok := config.RoleMappings[value]; ok && !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		roles = append(roles, config.DefaultRole)
+	}
+	slices.Sort(roles)
+	return roles
+}
+
+// checkSSOEnforced rejects password sign-in for members of organizations that
+// require SSO.
+func (s *Service) checkSSOEnforced(ctx context.Context, userID uuid.UUID) error {
+	orgID, err := s.identity.GetUserOrganizationID(ctx, userID)
+	if errors.Is(err, identityrepo.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	enforced, err := s.identity.IsSSOEnforced(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if enforced {
+		return apperr.Forbidden(ssoRequiredMessage)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"portal_final_backend/internal/auth/oidc"
+	identityrepo "portal_final_backend/internal/identity/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestSSORoles(t *testing.T) {
+	claim := "groups"
+	config := identityrepo.OrganizationSSOConfig{
+		RoleClaim:    &claim,
+		RoleMappings: map[string]string{"portal-admins": "admin", "portal-users": "user", "sales": "user"},
+		DefaultRole:  "agent",
+	}
+	token := func(groups any) oidc.IDToken {
+		return oidc.IDToken{Claims: jwt.MapClaims{"groups": groups}}
+	}
+
+	cases := []struct {
+		name  string
+		token oidc.IDToken
+		want  []string
+	}{
+		{name: "mapped", token: token([]any{"portal-admins", "sales", "portal-users"}), want: []string{"admin", "user"}},
+		{name: "single string", token: token("portal-admins"), want: []string{"admin"}},
+		{name: "unmapped", token: token([]any{"other"}), want: []string{"agent"}},
+		{name: "missing claim", token: oidc.IDToken{Claims: jwt.MapClaims{}}, want: []string{"agent"}},
+	}
+	for _, tc := range cases {
+		if got := ssoRoles(config, tc.token); !slices.Equal(got, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -175,7 +175,7 @@ func (s *Service) BeginPasskeyLogin(ctx context.Context) (interface{}, error) {
 }
 
 // FinishPasskeyLogin Cognitive Complexity greatly reduced by extracting validation logic.
-func (s *Service) FinishPasskeyLogin(ctx context.Context, challenge string, body []byte) (string, string, error) {
+func (s *Service) FinishPasskeyLogin(ctx context.Context, challenge string, body []byte, client ClientInfo) (string, string, error) {
 	if err := s.ensureWebAuthnReady(); err != nil {
 		return "", "", err
 	}
@@ -201,7 +201,7 @@ func (s *Service) FinishPasskeyLogin(ctx context.Context, challenge string, body
 		return "", "", apperr.Forbidden("email not verified")
 	}
 
-	return s.issueTokens(ctx, user.ID, user.Email)
+	return s.issueTokens(ctx, user.ID, user.Email, nil, client)
 }
 
 // validateAndFetchLoginUser encapsulates the WebAuthn user handler closure, flattening complexity.
@@ -51,6 +51,18 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"newPassword" validate:"required,strongpassword,max=1024"`
 }
 
+// StartSSORequest starts single sign-on for an organization, found by ID or
+// by the domain of the email address.
+type StartSSORequest struct {
+	Email          string `json:"email" validate:"required_without=OrganizationID,omitempty,email,max=255"`
+	OrganizationID string `json:"organizationId" validate:"omitempty,uuid"`
+}
+
+type FinishSSORequest struct {
+	Code  string `json:"code" validate:"required,max=2048"`
+	State string `json:"state" validate:"required,max=512"`
+}
+
 // =============================================================================
 // User & Profile Models
 // Optimization: Struct fields are packed (ordered by size) to minimize memory
@@ -114,6 +126,35 @@ type UserSummary struct {
 	Roles     []string `json:"roles"`
 }
 
+type StartSSOResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  *string   `json:"userAgent,omitempty"`
+	IPAddress  *string   `json:"ipAddress,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+type LockoutResponse struct {
+	ID             string    `json:"id"`
+	Scope          string    `json:"scope"`
+	Subject        string    `json:"subject"`
+	UserID         *string   `json:"userId,omitempty"`
+	FailedAttempts int       `json:"failedAttempts"`
+	LockedUntil    time.Time `json:"lockedUntil"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
 // =============================================================================
 // RBAC & Organization Data
 // =============================================================================
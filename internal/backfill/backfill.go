@@ -0,0 +1,221 @@
+// Package backfill runs resumable batch jobs over existing records, such as
+// enriching leads that were created before an enrichment existed.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+// Cursor is the position of a backfill in the (created_at, id) order of records.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// ItemResult is the outcome of backfilling a single record.
+type ItemResult struct {
+	// Updated reports that the record was updated, or would be in a dry run.
+	Updated bool
+	// External reports that an external API was called, so the item counts
+	// against the rate limit. Items answered from a cache do not.
+	External bool
+}
+
+// Job is a backfill over records of type T.
+type Job[T any] interface {
+	// List returns up to limit records after the cursor, in cursor order.
+	List(ctx context.Context, after Cursor, limit int) ([]T, error)
+	// Cursor returns the position of a record.
+	Cursor(item T) Cursor
+	// Process backfills a record and must not write anything in a dry run.
+	// An error fails the record; an error wrapped with Stop ends the run.
+	Process(ctx context.Context, item T, dryRun bool) (ItemResult, error)
+}
+
+// Options control batching, pacing and resumption of a run.
+type Options struct {
+	BatchSize int
+	// Interval is the pause after each item that called an external API.
+	Interval time.Duration
+	// FailureBackoff is the pause after a failed item.
+	FailureBackoff time.Duration
+	// DryRun processes records without writing them or the progress.
+	DryRun bool
+	// Restart ignores the saved progress and starts from the first record.
+	Restart bool
+	// Progress, when set, receives the stats of the run after each batch.
+	Progress func(Stats)
+}
+
+// Stats counts the records handled by a run.
+type Stats struct {
+	Processed int
+	Updated   int
+	Skipped   int
+	Failed    int
+}
+
+func (s Stats) add(other Stats) Stats {
+	return Stats{
+		Processed: s.Processed + other.Processed,
+		Updated:   s.Updated + other.Updated,
+		Skipped:   s.Skipped + other.Skipped,
+		Failed:    s.Failed + other.Failed,
+	}
+}
+
+// Progress is the saved state of a backfill. Totals accumulate over resumed runs.
+type Progress struct {
+	Cursor      Cursor
+	Totals      Stats
+	CompletedAt *time.Time
+}
+
+// ProgressStore persists the progress of backfills by name.
+type ProgressStore interface {
+	// LoadProgress returns the saved progress, or nil when the backfill never ran.
+	LoadProgress(ctx context.Context, name string) (*Progress, error)
+	SaveProgress(ctx context.Context, name string, progress Progress) error
+}
+
+type stopError struct{ err error }
+
+func (e stopError) Error() string { return e.err.Error() }
+func (e stopError) Unwrap() error { return e.err }
+
+// Stop marks an item error as fatal for the run, e.g. when a required provider
+// is unavailable and every following item would fail the same way.
+func Stop(err error) error {
+	return stopError{err: err}
+}
+
+// Run processes the records of a job in batches, saving the cursor after each
+// batch. A run resumes from the saved cursor unless the previous run completed
+// or Restart is set. It returns the stats of this run.
+func Run[T any](ctx context.Context, name string, job Job[T], store ProgressStore, opts Options, log *logger.Logger) (Stats, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+
+	progress, err := startProgress(ctx, name, store, opts)
+	if err != nil {
+		return Stats{}, err
+	}
+	previous := progress.Totals
+
+	var stats Stats
+	started := time.Now()
+	log.Info("backfill started", "backfill", name, "dryRun", opts.DryRun, "batchSize", opts.BatchSize,
+		"resumeAfter", progress.Cursor.ID, "previouslyProcessed", previous.Processed)
+
+	for {
+		items, err := job.List(ctx, progress.Cursor, opts.BatchSize)
+		if err != nil {
+			return stats, err
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		for _, item := range items {
+			result, err := job.Process(ctx, item, opts.DryRun)
+			progress.Cursor = job.Cursor(item)
+			stats.Processed++
+
+			var stop stopError
+			switch {
+			case errors.As(err, &stop):
+				stats.Failed++
+				progress.Totals = previous.add(stats)
+				saveProgress(ctx, name, store, progress, opts, log)
+				log.Error("backfill stopped", "backfill", name, "error", stop.err)
+				return stats, stop.err
+			case err != nil:
+				stats.Failed++
+				log.Error("backfill item failed", "backfill", name, "id", progress.Cursor.ID, "error", err)
+				if err := wait(ctx, opts.FailureBackoff); err != nil {
+					return stats, err
+				}
+				continue
+			case result.Updated:
+				stats.Updated++
+			default:
+				stats.Skipped++
+			}
+
+			if result.External {
+				if err := wait(ctx, opts.Interval); err != nil {
+					return stats, err
+				}
+			}
+		}
+
+		progress.Totals = previous.add(stats)
+		saveProgress(ctx, name, store, progress, opts, log)
+		if opts.Progress != nil {
+			opts.Progress(stats)
+		}
+		log.Info("backfill batch completed", "backfill", name, "processed", stats.Processed, "updated", stats.Updated,
+			"skipped", stats.Skipped, "failed", stats.Failed, "perSecond", rate(stats.Processed, time.Since(started)))
+	}
+
+	completedAt := time.Now().UTC()
+	progress.CompletedAt = &completedAt
+	progress.Totals = previous.add(stats)
+	saveProgress(ctx, name, store, progress, opts, log)
+	log.Info("backfill completed", "backfill", name, "processed", stats.Processed, "updated", stats.Updated,
+		"skipped", stats.Skipped, "failed", stats.Failed, "duration", time.Since(started).Round(time.Second))
+	return stats, nil
+}
+
+// startProgress returns the progress to resume from. A completed backfill starts
+// over, as records created since then may need it too.
+func startProgress(ctx context.Context, name string, store ProgressStore, opts Options) (Progress, error) {
+	if opts.Restart {
+		return Progress{}, nil
+	}
+	saved, err := store.LoadProgress(ctx, name)
+	if err != nil {
+		return Progress{}, err
+	}
+	if saved == nil || saved.CompletedAt != nil {
+		return Progress{}, nil
+	}
+	return *saved, nil
+}
+
+func saveProgress(ctx context.Context, name string, store ProgressStore, progress Progress, opts Options, log *logger.Logger) {
+	if opts.DryRun {
+		return
+	}
+	if err := store.SaveProgress(ctx, name, progress); err != nil {
+		log.Warn("failed to save backfill progress", "backfill", name, "error", err)
+	}
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func rate(count int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(int(float64(count)/elapsed.Seconds()*10)) / 10
+}
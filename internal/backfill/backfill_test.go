@@ -0,0 +1,126 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+type fakeRecord struct {
+	cursor Cursor
+	fail   error
+}
+
+type fakeJob struct {
+	records   []fakeRecord
+	processed int
+	writes    int
+}
+
+func (j *fakeJob) List(_ context.Context, after Cursor, limit int) ([]fakeRecord, error) {
+	var page []fakeRecord
+	for _, r := range j.records {
+		if r.cursor.CreatedAt.After(after.CreatedAt) && len(page) < limit {
+			page = append(page, r)
+		}
+	}
+	return page, nil
+}
+
+func (j *fakeJob) Cursor(r fakeRecord) Cursor { return r.cursor }
+
+func (j *fakeJob) Process(_ context.Context, r fakeRecord, dryRun bool) (ItemResult, error) {
+	j.processed++
+	if r.fail != nil {
+		return ItemResult{}, r.fail
+	}
+	if !dryRun {
+		j.writes++
+	}
+	return ItemResult{Updated: true, External: true}, nil
+}
+
+type memoryStore map[string]Progress
+
+func (s memoryStore) LoadProgress(_ context.Context, name string) (*Progress, error) {
+	p, ok := s[name]
+	if !ok {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (s memoryStore) SaveProgress(_ context.Context, name string, p Progress) error {
+	s[name] = p
+	return nil
+}
+
+func records(n int) []fakeRecord {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]fakeRecord, n)
+	for i := range out {
+		out[i] = fakeRecord{cursor: Cursor{CreatedAt: base.Add(time.Duration(i+1) * time.Minute), ID: uuid.New()}}
+	}
+	return out
+}
+
+func TestRunResumesFromSavedCursor(t *testing.T) {
+	job := &fakeJob{records: records(5)}
+	job.records[3].fail = errors.New("lookup failed")
+	store := memoryStore{"leads": {Cursor: job.records[1].cursor, Totals: Stats{Processed: 2, Updated: 2}}}
+
+	stats, err := Run[fakeRecord](context.Background(), "leads", job, store, Options{BatchSize: 2}, logger.New("development"))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if stats != (Stats{Processed: 3, Updated: 2, Failed: 1}) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	saved := store["leads"]
+	if saved.CompletedAt == nil || saved.Cursor != job.records[4].cursor || saved.Totals != (Stats{Processed: 5, Updated: 4, Failed: 1}) {
+		t.Fatalf("unexpected progress %+v", saved)
+	}
+
+	// A completed backfill starts over on the next run.
+	job.processed = 0
+	if _, err := Run[fakeRecord](context.Background(), "leads", job, store, Options{BatchSize: 2}, logger.New("development")); err != nil {
+		t.Fatalf("rerun: %v", err)
+	}
+	if job.processed != 5 {
+		t.Fatalf("expected rerun to process all records, processed %d", job.processed)
+	}
+}
+
+func TestRunDryRunWritesNothing(t *testing.T) {
+	job := &fakeJob{records: records(3)}
+	store := memoryStore{}
+
+	stats, err := Run[fakeRecord](context.Background(), "leads", job, store, Options{DryRun: true}, logger.New("development"))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if stats.Updated != 3 || job.writes != 0 || len(store) != 0 {
+		t.Fatalf("dry run wrote: stats %+v, writes %d, progress %v", stats, job.writes, store)
+	}
+}
+
+func TestRunStopsAndKeepsCursor(t *testing.T) {
+	errNoProvider := errors.New("no provider")
+	job := &fakeJob{records: records(4)}
+	job.records[2].fail = Stop(errNoProvider)
+	store := memoryStore{}
+
+	_, err := Run[fakeRecord](context.Background(), "leads", job, store, Options{BatchSize: 10}, logger.New("development"))
+	if !errors.Is(err, errNoProvider) {
+		t.Fatalf("expected stop error, got %v", err)
+	}
+	saved := store["leads"]
+	if job.processed != 3 || saved.CompletedAt != nil || saved.Cursor != job.records[2].cursor {
+		t.Fatalf("unexpected progress %+v after %d records", saved, job.processed)
+	}
+}
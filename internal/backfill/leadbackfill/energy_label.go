@@ -0,0 +1,134 @@
+package leadbackfill
+
+import (
+	"context"
+	"time"
+
+	"portal_final_backend/internal/backfill"
+	"portal_final_backend/internal/leads/ports"
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type leadEnergyAddress struct {
+	id       uuid.UUID
+	tenantID uuid.UUID
+	cursor   backfill.Cursor
+	street   string
+	house    string
+	zip      string
+	city     string
+}
+
+// EnergyLabelJob fetches energy labels for leads that never had one fetched.
+type EnergyLabelJob struct {
+	pool     *pgxpool.Pool
+	repo     *repository.Repository
+	enricher ports.EnergyLabelEnricher
+	log      *logger.Logger
+}
+
+func NewEnergyLabelJob(pool *pgxpool.Pool, enricher ports.EnergyLabelEnricher, log *logger.Logger) *EnergyLabelJob {
+	return &EnergyLabelJob{pool: pool, repo: repository.New(pool), enricher: enricher, log: log}
+}
+
+// Run runs the backfill, resuming from the progress saved in the store.
+func (j *EnergyLabelJob) Run(ctx context.Context, store backfill.ProgressStore, opts backfill.Options) (backfill.Stats, error) {
+	return backfill.Run[leadEnergyAddress](ctx, NameEnergyLabel, j, store, opts, j.log)
+}
+
+func (j *EnergyLabelJob) List(ctx context.Context, after backfill.Cursor, limit int) ([]leadEnergyAddress, error) {
+	rows, err := j.pool.Query(ctx, `
+		SELECT id, organization_id, created_at, address_street, address_house_number, address_zip_code, address_city
+		FROM RAC_leads
+		WHERE deleted_at IS NULL
+		  AND energy_label_fetched_at IS NULL
+		  AND address_zip_code <> ''
+		  AND address_house_number <> ''
+		  AND (created_at, id) > ($1, $2)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
+	`, after.CreatedAt, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leads := make([]leadEnergyAddress, 0)
+	for rows.Next() {
+		var lead leadEnergyAddress
+		if err := rows.Scan(&lead.id, &lead.tenantID, &lead.cursor.CreatedAt, &lead.street, &lead.house, &lead.zip, &lead.city); err != nil {
+			return nil, err
+		}
+		lead.cursor.ID = lead.id
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+func (j *EnergyLabelJob) Cursor(lead leadEnergyAddress) backfill.Cursor {
+	return lead.cursor
+}
+
+func (j *EnergyLabelJob) Process(parentCtx context.Context, lead leadEnergyAddress, dryRun bool) (backfill.ItemResult, error) {
+	if lead.zip == "0000XX" || lead.street == "Unknown" || lead.city == "Unknown" {
+		return backfill.ItemResult{}, nil
+	}
+
+	// Use a timeout per lead to avoid hanging on slow API calls
+	ctx, cancel := context.WithTimeout(parentCtx, 15*time.Second)
+	defer cancel()
+
+	data, err := j.enricher.EnrichLead(ctx, ports.EnrichLeadParams{
+		Postcode:   lead.zip,
+		Huisnummer: lead.house,
+	})
+	if err != nil {
+		return backfill.ItemResult{External: true}, err
+	}
+	if dryRun {
+		return backfill.ItemResult{Updated: true, External: true}, nil
+	}
+
+	// An empty result is stored too, so the lead is not looked up again.
+	if err := j.repo.UpdateEnergyLabel(ctx, lead.id, lead.tenantID, energyLabelParams(data, time.Now().UTC())); err != nil {
+		return backfill.ItemResult{External: true}, err
+	}
+
+	if data == nil {
+		j.log.Info("no energy label found", "leadId", lead.id, "tenantId", lead.tenantID)
+	} else {
+		j.log.Info("energy label updated", "leadId", lead.id, "tenantId", lead.tenantID, "class", data.Energieklasse)
+	}
+	return backfill.ItemResult{Updated: true, External: true}, nil
+}
+
+func energyLabelParams(data *ports.LeadEnergyData, fetchedAt time.Time) repository.UpdateEnergyLabelParams {
+	params := repository.UpdateEnergyLabelParams{FetchedAt: fetchedAt}
+	if data == nil {
+		return params
+	}
+
+	params.Class = toPtr(data.Energieklasse)
+	params.Index = data.EnergieIndex
+	if data.Bouwjaar != 0 {
+		bouwjaar := data.Bouwjaar
+		params.Bouwjaar = &bouwjaar
+	}
+	params.Gebouwtype = toPtr(data.Gebouwtype)
+	params.ValidUntil = data.GeldigTot
+	params.RegisteredAt = data.Registratiedatum
+	params.PrimairFossiel = data.PrimaireFossieleEnergie
+	params.BAGObjectID = toPtr(data.BAGVerblijfsobjectID)
+	return params
+}
+
+func toPtr(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
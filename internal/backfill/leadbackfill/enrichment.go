@@ -0,0 +1,145 @@
+package leadbackfill
+
+import (
+	"context"
+	"time"
+
+	"portal_final_backend/internal/backfill"
+	"portal_final_backend/internal/leads/ports"
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/scoring"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type leadPostcode struct {
+	id       uuid.UUID
+	tenantID uuid.UUID
+	cursor   backfill.Cursor
+	zip      string
+	house    string
+}
+
+// EnrichmentJob fetches postcode enrichment for leads and re-scores them.
+type EnrichmentJob struct {
+	pool     *pgxpool.Pool
+	repo     *repository.Repository
+	scorer   *scoring.Service
+	enricher ports.LeadEnricher
+	log      *logger.Logger
+}
+
+func NewEnrichmentJob(pool *pgxpool.Pool, enricher ports.LeadEnricher, log *logger.Logger) *EnrichmentJob {
+	repo := repository.New(pool)
+	return &EnrichmentJob{pool: pool, repo: repo, scorer: scoring.New(repo, log), enricher: enricher, log: log}
+}
+
+// Run runs the backfill, resuming from the progress saved in the store.
+func (j *EnrichmentJob) Run(ctx context.Context, store backfill.ProgressStore, opts backfill.Options) (backfill.Stats, error) {
+	return backfill.Run[leadPostcode](ctx, NameEnrichment, j, store, opts, j.log)
+}
+
+func (j *EnrichmentJob) List(ctx context.Context, after backfill.Cursor, limit int) ([]leadPostcode, error) {
+	rows, err := j.pool.Query(ctx, `
+		SELECT id, organization_id, created_at, address_zip_code, address_house_number
+		FROM RAC_leads
+		WHERE deleted_at IS NULL
+		  AND address_zip_code <> ''
+		  AND address_house_number <> ''
+		  AND (created_at, id) > ($1, $2)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
+	`, after.CreatedAt, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leads := make([]leadPostcode, 0)
+	for rows.Next() {
+		var lead leadPostcode
+		if err := rows.Scan(&lead.id, &lead.tenantID, &lead.cursor.CreatedAt, &lead.zip, &lead.house); err != nil {
+			return nil, err
+		}
+		lead.cursor.ID = lead.id
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+func (j *EnrichmentJob) Cursor(lead leadPostcode) backfill.Cursor {
+	return lead.cursor
+}
+
+// Process enriches and re-scores a lead. Only enrichment fetched from the
+// external APIs rather than the postcode cache counts against the rate limit.
+func (j *EnrichmentJob) Process(parentCtx context.Context, lead leadPostcode, dryRun bool) (backfill.ItemResult, error) {
+	if lead.zip == "0000XX" {
+		return backfill.ItemResult{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, 20*time.Second)
+	defer cancel()
+
+	started := time.Now().UTC()
+	data, err := j.enricher.EnrichLead(ctx, lead.zip)
+	if err != nil {
+		return backfill.ItemResult{External: true}, err
+	}
+	result := backfill.ItemResult{Updated: true, External: data != nil && !data.FetchedAt.Before(started)}
+
+	scoreResult, err := j.scorer.Recalculate(ctx, lead.id, nil, lead.tenantID, true)
+	if err != nil {
+		return result, err
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	if data == nil {
+		if err := j.repo.UpdateLeadScore(ctx, lead.id, lead.tenantID, repository.UpdateLeadScoreParams{
+			Score:          &scoreResult.Score,
+			ScorePreAI:     &scoreResult.ScorePreAI,
+			ScoreFactors:   scoreResult.FactorsJSON,
+			ScoreVersion:   toPtr(scoreResult.Version),
+			ScoreUpdatedAt: scoreResult.UpdatedAt,
+		}); err != nil {
+			return result, err
+		}
+		j.log.Info("lead score updated without enrichment", "leadId", lead.id, "tenantId", lead.tenantID, "score", scoreResult.Score)
+		return result, nil
+	}
+
+	if err := j.repo.UpdateLeadEnrichment(ctx, lead.id, lead.tenantID, repository.UpdateLeadEnrichmentParams{
+		Source:                    toPtr(data.Source),
+		Postcode6:                 toPtr(data.Postcode6),
+		Postcode4:                 toPtr(data.Postcode4),
+		Buurtcode:                 toPtr(data.Buurtcode),
+		DataYear:                  data.DataYear,
+		GemAardgasverbruik:        data.GemAardgasverbruik,
+		GemElektriciteitsverbruik: data.GemElektriciteitsverbruik,
+		HuishoudenGrootte:         data.HuishoudenGrootte,
+		KoopwoningenPct:           data.KoopwoningenPct,
+		BouwjaarVanaf2000Pct:      data.BouwjaarVanaf2000Pct,
+		WOZWaarde:                 data.WOZWaarde,
+		MediaanVermogenX1000:      data.MediaanVermogenX1000,
+		GemInkomen:                data.GemInkomenHuishouden,
+		PctHoogInkomen:            data.PctHoogInkomen,
+		PctLaagInkomen:            data.PctLaagInkomen,
+		HuishoudensMetKinderenPct: data.HuishoudensMetKinderenPct,
+		Stedelijkheid:             data.Stedelijkheid,
+		Confidence:                data.Confidence,
+		FetchedAt:                 time.Now().UTC(),
+		Score:                     &scoreResult.Score,
+		ScorePreAI:                &scoreResult.ScorePreAI,
+		ScoreFactors:              scoreResult.FactorsJSON,
+		ScoreVersion:              toPtr(scoreResult.Version),
+		ScoreUpdatedAt:            &scoreResult.UpdatedAt,
+	}); err != nil {
+		return result, err
+	}
+	j.log.Info("lead enrichment updated", "leadId", lead.id, "tenantId", lead.tenantID, "score", scoreResult.Score, "cached", !result.External)
+	return result, nil
+}
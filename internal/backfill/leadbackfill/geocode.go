@@ -0,0 +1,102 @@
+package leadbackfill
+
+import (
+	"context"
+	"errors"
+
+	"portal_final_backend/internal/backfill"
+	"portal_final_backend/internal/maps"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type geocodeLead struct {
+	id          uuid.UUID
+	cursor      backfill.Cursor
+	street      string
+	houseNumber string
+	zipCode     string
+	city        string
+}
+
+// GeocodeJob stores coordinates for leads without latitude/longitude.
+type GeocodeJob struct {
+	pool *pgxpool.Pool
+	maps *maps.Service
+	log  *logger.Logger
+}
+
+func NewGeocodeJob(pool *pgxpool.Pool, mapsService *maps.Service, log *logger.Logger) *GeocodeJob {
+	return &GeocodeJob{pool: pool, maps: mapsService, log: log}
+}
+
+// Run runs the backfill, resuming from the progress saved in the store.
+func (j *GeocodeJob) Run(ctx context.Context, store backfill.ProgressStore, opts backfill.Options) (backfill.Stats, error) {
+	return backfill.Run[geocodeLead](ctx, NameGeocode, j, store, opts, j.log)
+}
+
+func (j *GeocodeJob) List(ctx context.Context, after backfill.Cursor, limit int) ([]geocodeLead, error) {
+	rows, err := j.pool.Query(ctx, `
+		SELECT id, created_at, address_street, address_house_number, address_zip_code, address_city
+		FROM RAC_leads
+		WHERE deleted_at IS NULL
+		  AND (latitude IS NULL OR longitude IS NULL)
+		  AND (created_at, id) > ($1, $2)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
+	`, after.CreatedAt, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leads := make([]geocodeLead, 0)
+	for rows.Next() {
+		var lead geocodeLead
+		if err := rows.Scan(&lead.id, &lead.cursor.CreatedAt, &lead.street, &lead.houseNumber, &lead.zipCode, &lead.city); err != nil {
+			return nil, err
+		}
+		lead.cursor.ID = lead.id
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+func (j *GeocodeJob) Cursor(lead geocodeLead) backfill.Cursor {
+	return lead.cursor
+}
+
+// Process geocodes a lead. Only a missing geocoding provider stops the run;
+// addresses that cannot be geocoded are skipped.
+func (j *GeocodeJob) Process(ctx context.Context, lead geocodeLead, dryRun bool) (backfill.ItemResult, error) {
+	if lead.street == "Unknown" || lead.city == "Unknown" || lead.zipCode == "0000XX" {
+		return backfill.ItemResult{}, nil
+	}
+
+	query := maps.GeocodeQuery{Street: lead.street, HouseNumber: lead.houseNumber, ZipCode: lead.zipCode, City: lead.city}
+	result, err := j.maps.Geocode(ctx, query)
+	if err != nil {
+		if errors.Is(err, maps.ErrNoGeocoderAvailable) {
+			return backfill.ItemResult{}, backfill.Stop(err)
+		}
+		return backfill.ItemResult{External: true}, err
+	}
+	if result == nil {
+		j.log.Info("no geocode result", "leadId", lead.id, "address", query.String())
+		return backfill.ItemResult{External: true}, nil
+	}
+	if dryRun {
+		return backfill.ItemResult{Updated: true, External: true}, nil
+	}
+
+	if _, err := j.pool.Exec(ctx, `
+		UPDATE RAC_leads
+		SET latitude = $2, longitude = $3, updated_at = now()
+		WHERE id = $1
+	`, lead.id, result.Lat, result.Lon); err != nil {
+		return backfill.ItemResult{External: true}, err
+	}
+	return backfill.ItemResult{Updated: true, External: true}, nil
+}
@@ -0,0 +1,25 @@
+// Package leadbackfill contains the backfills over existing leads, run from
+// cmd/backfill and on demand through the scheduler.
+package leadbackfill
+
+import (
+	"time"
+
+	"portal_final_backend/internal/backfill"
+)
+
+// Names under which the backfills save their progress.
+const (
+	NameGeocode     = "lead-geocode"
+	NameEnergyLabel = "lead-energy-label"
+	NameEnrichment  = "lead-enrichment"
+)
+
+// Default options of the backfills, paced for the external APIs they call.
+var (
+	// Providers pace and meter themselves, and the geocode cache answers
+	// repeated addresses, so the geocode backfill does not need to throttle.
+	GeocodeOptions     = backfill.Options{BatchSize: 25}
+	EnergyLabelOptions = backfill.Options{BatchSize: 25, Interval: 500 * time.Millisecond, FailureBackoff: time.Second}
+	EnrichmentOptions  = backfill.Options{BatchSize: 50, Interval: 300 * time.Millisecond, FailureBackoff: time.Second}
+)
@@ -0,0 +1,61 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository stores backfill progress in the database.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+func (r *Repository) LoadProgress(ctx context.Context, name string) (*Progress, error) {
+	var (
+		p         Progress
+		createdAt time.Time
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT cursor_created_at, cursor_id, processed, updated, skipped, failed, completed_at
+		FROM RAC_backfill_progress
+		WHERE name = $1
+	`, name).Scan(&createdAt, &p.Cursor.ID, &p.Totals.Processed, &p.Totals.Updated, &p.Totals.Skipped, &p.Totals.Failed, &p.CompletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("load backfill progress: %w", err)
+	}
+	p.Cursor.CreatedAt = createdAt
+	return &p, nil
+}
+
+func (r *Repository) SaveProgress(ctx context.Context, name string, p Progress) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_backfill_progress (name, cursor_created_at, cursor_id, processed, updated, skipped, failed, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (name) DO UPDATE
+		SET cursor_created_at = EXCLUDED.cursor_created_at,
+			cursor_id = EXCLUDED.cursor_id,
+			processed = EXCLUDED.processed,
+			updated = EXCLUDED.updated,
+			skipped = EXCLUDED.skipped,
+			failed = EXCLUDED.failed,
+			completed_at = EXCLUDED.completed_at,
+			started_at = CASE WHEN RAC_backfill_progress.completed_at IS NOT NULL THEN now() ELSE RAC_backfill_progress.started_at END,
+			updated_at = now()
+	`, name, p.Cursor.CreatedAt, p.Cursor.ID, p.Totals.Processed, p.Totals.Updated, p.Totals.Skipped, p.Totals.Failed, p.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("save backfill progress: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"portal_final_backend/internal/billing/repository"
+	"portal_final_backend/internal/billing/service"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// meteredRoutes maps the routes that add a unit of a plan limit to that limit,
+// by their path below the API version so every version is metered. AI runs
+// are admitted by the LLM router instead, as they are not started by one
+// route.
+var meteredRoutes = map[string]string{
+	"POST /leads":                       repository.MetricLeads,
+	"POST /admin/organizations/invites": service.MetricUsers,
+}
+
+// meteredMetric returns the limit a route adds a unit of, if any.
+func meteredMetric(method, fullPath string) (string, bool) {
+	rest, ok := strings.CutPrefix(fullPath, "/api/v")
+	if !ok {
+		return "", false
+	}
+	slash := strings.IndexByte(rest, '/')
+	if slash <= 0 || strings.Trim(rest[:slash], "0123456789") != "" {
+		return "", false
+	}
+	metric, ok := meteredRoutes[method+" "+rest[slash:]]
+	return metric, ok
+}
+
+// Enforce returns the middleware that applies the subscription of the
+// organization to authenticated requests: while a payment is overdue past its
+// grace period every change is rejected except on the billing routes, and
+// metered routes are rejected once their plan limit is used. Billing failures
+// are logged and let the request through.
+func (h *Handler) Enforce(log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isReadOnly(c.Request.Method) || strings.Contains(c.FullPath(), "/billing/") {
+			c.Next()
+			return
+		}
+		tenantID := httpkit.GetIdentity(c).TenantID()
+		if tenantID == nil {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		err := h.svc.CheckAccess(ctx, *tenantID)
+		if metric, ok := meteredMetric(c.Request.Method, c.FullPath()); ok && err == nil {
+			err = h.svc.CheckLimit(ctx, *tenantID, metric)
+		}
+		if err != nil && !apperr.Is(err, apperr.KindPaymentRequired) {
+			log.Warn("billing: enforcement check failed", "organizationId", *tenantID, "error", err)
+			err = nil
+		}
+		if httpkit.HandleError(c, err) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
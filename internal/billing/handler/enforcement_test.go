@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/billing/repository"
+	"portal_final_backend/internal/billing/service"
+)
+
+func TestMeteredMetricCoversEveryAPIVersion(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{"POST", "/api/v1/leads", repository.MetricLeads},
+		{"POST", "/api/v2/leads", repository.MetricLeads},
+		{"POST", "/api/v2/admin/organizations/invites", service.MetricUsers},
+		{"PUT", "/api/v2/leads", ""},
+		{"POST", "/api/v2/leads/:id", ""},
+		{"POST", "/api/vx/leads", ""},
+		{"POST", "/leads", ""},
+	}
+	for _, tt := range tests {
+		got, ok := meteredMetric(tt.method, tt.path)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("meteredMetric(%s %s) = %q, %v, want %q", tt.method, tt.path, got, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/billing/service"
+	"portal_final_backend/internal/billing/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+// RegisterProtectedRoutes registers the plans and the subscription overview.
+func (h *Handler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/plans", h.ListPlans)
+	rg.GET("/subscription", h.GetSubscription)
+}
+
+// RegisterAdminRoutes registers the routes that start and stop paid subscriptions.
+func (h *Handler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.POST("/checkout", h.StartCheckout)
+	rg.POST("/subscription/cancel", h.CancelSubscription)
+}
+
+// RegisterSuperAdminRoutes registers the subscription overview and manual plan
+// assignment of any organization.
+func (h *Handler) RegisterSuperAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/organizations/:organizationID/subscription", h.GetOrganizationSubscription)
+	rg.PUT("/organizations/:organizationID/subscription", h.AssignPlan)
+}
+
+// RegisterWebhookRoutes registers the unauthenticated payment provider webhooks.
+func (h *Handler) RegisterWebhookRoutes(rg *gin.RouterGroup) {
+	rg.POST("/webhooks/:provider", h.HandleWebhook)
+}
+
+func (h *Handler) ListPlans(c *gin.Context) {
+	resp, err := h.svc.ListPlans(c.Request.Context())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetSubscription(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetSubscription(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) StartCheckout(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.StartCheckoutRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.StartCheckout(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
+
+func (h *Handler) CancelSubscription(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.CancelSubscription(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetOrganizationSubscription(c *gin.Context) {
+	organizationID, ok := httpkit.ParseUUIDParam(c, "organizationID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetSubscription(c.Request.Context(), organizationID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) AssignPlan(c *gin.Context) {
+	organizationID, ok := httpkit.ParseUUIDParam(c, "organizationID")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.AssignPlanRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.AssignPlan(c.Request.Context(), organizationID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// HandleWebhook handles POST /api/v1/billing/webhooks/:provider. A non-2xx
+// response makes the provider retry.
+func (h *Handler) HandleWebhook(c *gin.Context) {
+	if err := h.svc.HandleWebhook(c.Request.Context(), c.Param("provider"), c.Request); httpkit.HandleError(c, err) {
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,96 @@
+// Package billing manages the platform subscriptions of organizations: plans
+// with user, lead and AI run limits, usage metering, payment through Stripe or
+// Mollie with a grace period for overdue payments, and the middleware that
+// enforces all of it on the API.
+package billing
+
+import (
+	"context"
+	"strings"
+
+	"portal_final_backend/internal/billing/handler"
+	"portal_final_backend/internal/billing/repository"
+	"portal_final_backend/internal/billing/service"
+	"portal_final_backend/internal/events"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	routePath          = "/billing"
+	mollieWebhookRoute = "/api/v1/billing/webhooks/mollie"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+	log     *logger.Logger
+}
+
+func NewModule(pool *pgxpool.Pool, cfg service.Config, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), cfg, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+		log:     log,
+	}
+}
+
+// Service returns the billing service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+// ConfigureProviders enables the payment providers that have credentials.
+func (m *Module) ConfigureProviders(cfg config.BillingConfig, publicAPIBaseURL string) {
+	if key := cfg.GetStripeSecretKey(); key != "" {
+		m.service.RegisterProvider(service.NewStripe(key, cfg.GetStripeWebhookSecret()))
+	}
+	if key := cfg.GetBillingMollieAPIKey(); key != "" {
+		m.service.RegisterProvider(service.NewMollie(key, strings.TrimRight(publicAPIBaseURL, "/")+mollieWebhookRoute))
+	}
+}
+
+// Middleware returns the subscription enforcement for authenticated routes.
+func (m *Module) Middleware() gin.HandlerFunc {
+	return m.handler.Enforce(m.log)
+}
+
+func (m *Module) Name() string {
+	return "billing"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterProtectedRoutes(ctx.Protected.Group(routePath))
+	m.handler.RegisterAdminRoutes(ctx.Admin.Group(routePath))
+	m.handler.RegisterWebhookRoutes(ctx.V1.Group(routePath))
+	if ctx.SuperAdmin != nil {
+		m.handler.RegisterSuperAdminRoutes(ctx.SuperAdmin)
+	}
+}
+
+// RegisterHandlers subscribes the module to the events it meters.
+func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
+	bus.Subscribe(events.LeadCreated{}.EventName(), m)
+}
+
+// Handle processes subscribed domain events.
+func (m *Module) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.LeadCreated:
+		if err := m.service.RecordLead(ctx, e.TenantID); err != nil {
+			m.log.Error("failed to meter created lead", "leadId", e.LeadID, "error", err)
+			return err
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+var _ apphttp.Module = (*Module)(nil)
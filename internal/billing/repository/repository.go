@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrPlanNotFound = errors.New("billing plan not found")
+
+// Subscription statuses.
+const (
+	StatusPending  = "pending"
+	StatusActive   = "active"
+	StatusPastDue  = "past_due"
+	StatusCanceled = "canceled"
+)
+
+// Metered usage counters.
+const (
+	MetricLeads  = "leads"
+	MetricAIRuns = "ai_runs"
+)
+
+// Repository persists billing plans, organization subscriptions and usage.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Plan is a subscription plan. A nil limit is unlimited.
+type Plan struct {
+	Code              string
+	Name              string
+	PriceCents        int64
+	Currency          string
+	MaxUsers          *int64
+	MaxLeadsPerMonth  *int64
+	MaxAIRunsPerMonth *int64
+	StripePriceID     *string
+	IsActive          bool
+	SortOrder         int
+}
+
+// Subscription is the platform subscription of an organization.
+type Subscription struct {
+	OrganizationID         uuid.UUID
+	PlanCode               string
+	Status                 string
+	Provider               *string
+	ProviderCustomerID     *string
+	ProviderSubscriptionID *string
+	CurrentPeriodEnd       *time.Time
+	GraceUntil             *time.Time
+	CancelAtPeriodEnd      bool
+	ProviderEventAt        *time.Time
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+// OrganizationContact is what payment providers need to create a customer.
+type OrganizationContact struct {
+	Name  string
+	Email string
+}
+
+const planColumns = `code, name, price_cents, currency, max_users, max_leads_per_month,
+	max_ai_runs_per_month, stripe_price_id, is_active, sort_order`
+
+func scanPlan(row pgx.Row) (Plan, error) {
+	var p Plan
+	err := row.Scan(&p.Code, &p.Name, &p.PriceCents, &p.Currency, &p.MaxUsers, &p.MaxLeadsPerMonth,
+		&p.MaxAIRunsPerMonth, &p.StripePriceID, &p.IsActive, &p.SortOrder)
+	return p, err
+}
+
+// ListPlans returns the plans in display order.
+func (r *Repository) ListPlans(ctx context.Context, activeOnly bool) ([]Plan, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+planColumns+`
+		FROM RAC_billing_plans
+		WHERE NOT $1::boolean OR is_active
+		ORDER BY sort_order, code`, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := make([]Plan, 0)
+	for rows.Next() {
+		p, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, p)
+	}
+	return plans, rows.Err()
+}
+
+// GetPlan returns a plan by its code.
+func (r *Repository) GetPlan(ctx context.Context, code string) (Plan, error) {
+	p, err := scanPlan(r.pool.QueryRow(ctx, `SELECT `+planColumns+` FROM RAC_billing_plans WHERE code = $1`, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Plan{}, ErrPlanNotFound
+	}
+	return p, err
+}
+
+// GetSubscription returns the subscription of an organization, or nil if it
+// never had one.
+func (r *Repository) GetSubscription(ctx context.Context, organizationID uuid.UUID) (*Subscription, error) {
+	var s Subscription
+	err := r.pool.QueryRow(ctx, `
+		SELECT organization_id, plan_code, status, provider, provider_customer_id, provider_subscription_id,
+			current_period_end, grace_until, cancel_at_period_end, provider_event_at, created_at, updated_at
+		FROM RAC_organization_subscriptions
+		WHERE organization_id = $1`, organizationID,
+	).Scan(&s.OrganizationID, &s.PlanCode, &s.Status, &s.Provider, &s.ProviderCustomerID, &s.ProviderSubscriptionID,
+		&s.CurrentPeriodEnd, &s.GraceUntil, &s.CancelAtPeriodEnd, &s.ProviderEventAt, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SaveSubscription creates or replaces the subscription of an organization.
+// A state with a provider event time older than the one stored is not applied.
+func (r *Repository) SaveSubscription(ctx context.Context, s Subscription) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_organization_subscriptions (
+			organization_id, plan_code, status, provider, provider_customer_id, provider_subscription_id,
+			current_period_end, grace_until, cancel_at_period_end, provider_event_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			plan_code = EXCLUDED.plan_code,
+			status = EXCLUDED.status,
+			provider = EXCLUDED.provider,
+			provider_customer_id = EXCLUDED.provider_customer_id,
+			provider_subscription_id = EXCLUDED.provider_subscription_id,
+			current_period_end = EXCLUDED.current_period_end,
+			grace_until = EXCLUDED.grace_until,
+			cancel_at_period_end = EXCLUDED.cancel_at_period_end,
+			provider_event_at = COALESCE(EXCLUDED.provider_event_at, RAC_organization_subscriptions.provider_event_at),
+			updated_at = now()
+		WHERE EXCLUDED.provider_event_at IS NULL
+			OR RAC_organization_subscriptions.provider_event_at IS NULL
+			OR EXCLUDED.provider_event_at >= RAC_organization_subscriptions.provider_event_at`,
+		s.OrganizationID, s.PlanCode, s.Status, s.Provider, s.ProviderCustomerID, s.ProviderSubscriptionID,
+		s.CurrentPeriodEnd, s.GraceUntil, s.CancelAtPeriodEnd, s.ProviderEventAt)
+	return err
+}
+
+// GetOrganizationContact returns the name and email of an organization.
+func (r *Repository) GetOrganizationContact(ctx context.Context, organizationID uuid.UUID) (OrganizationContact, error) {
+	var c OrganizationContact
+	err := r.pool.QueryRow(ctx, `
+		SELECT name, COALESCE(email, '')
+		FROM RAC_organizations
+		WHERE id = $1`, organizationID,
+	).Scan(&c.Name, &c.Email)
+	return c, err
+}
+
+// AdmitUsage counts one unit of a metric unless the organization already used
+// its limit for the period. A nil limit always admits.
+func (r *Repository) AdmitUsage(ctx context.Context, organizationID uuid.UUID, metric string, periodStart time.Time, limit *int64) (bool, error) {
+	if limit != nil && *limit <= 0 {
+		return false, nil
+	}
+	var quantity int64
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_billing_usage (organization_id, metric, period_start, quantity)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (organization_id, metric, period_start) DO UPDATE SET
+			quantity = RAC_billing_usage.quantity + 1,
+			updated_at = now()
+		WHERE $4::bigint IS NULL OR RAC_billing_usage.quantity < $4
+		RETURNING quantity`,
+		organizationID, metric, periodStart, limit,
+	).Scan(&quantity)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// AddUsage counts usage of a metric regardless of the limit.
+func (r *Repository) AddUsage(ctx context.Context, organizationID uuid.UUID, metric string, periodStart time.Time, quantity int64) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_billing_usage (organization_id, metric, period_start, quantity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, metric, period_start) DO UPDATE SET
+			quantity = RAC_billing_usage.quantity + EXCLUDED.quantity,
+			updated_at = now()`,
+		organizationID, metric, periodStart, quantity)
+	return err
+}
+
+// GetUsage returns the usage of one metric in a period.
+func (r *Repository) GetUsage(ctx context.Context, organizationID uuid.UUID, metric string, periodStart time.Time) (int64, error) {
+	var quantity int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM RAC_billing_usage
+		WHERE organization_id = $1 AND metric = $2 AND period_start = $3`,
+		organizationID, metric, periodStart,
+	).Scan(&quantity)
+	return quantity, err
+}
+
+// CountSeats returns the members of an organization plus its open invites.
+func (r *Repository) CountSeats(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	var seats int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM RAC_organization_members WHERE organization_id = $1) +
+			(SELECT COUNT(*) FROM RAC_organization_invites
+			 WHERE organization_id = $1 AND used_at IS NULL AND expires_at > now())`,
+		organizationID,
+	).Scan(&seats)
+	return seats, err
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/billing/repository"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/mollie"
+
+	"github.com/google/uuid"
+)
+
+const (
+	mollieInterval      = "1 month"
+	mollieMetadataOrg   = "organization_id"
+	mollieMetadataPlan  = "plan_code"
+	mollieSequenceFirst = "first"
+)
+
+// Mollie collects subscriptions with a first payment that creates a mandate,
+// after which a Mollie subscription charges the customer every month.
+type Mollie struct {
+	apiKey     string
+	webhookURL string
+	client     *mollie.Client
+}
+
+// NewMollie creates the Mollie provider with the platform API key and the
+// public URL of the billing webhook.
+func NewMollie(apiKey, webhookURL string) *Mollie {
+	return &Mollie{
+		apiKey:     apiKey,
+		webhookURL: webhookURL,
+		client:     mollie.NewClient(""),
+	}
+}
+
+func (p *Mollie) Name() string {
+	return ProviderMollie
+}
+
+type mollieAmount struct {
+	Currency string `json:"currency"`
+	Value    string `json:"value"`
+}
+
+type molliePayment struct {
+	ID             string            `json:"id"`
+	Status         string            `json:"status"`
+	SequenceType   string            `json:"sequenceType"`
+	CustomerID     string            `json:"customerId"`
+	SubscriptionID string            `json:"subscriptionId"`
+	Amount         mollieAmount      `json:"amount"`
+	Description    string            `json:"description"`
+	PaidAt         *time.Time        `json:"paidAt"`
+	Metadata       map[string]string `json:"metadata"`
+	Links          struct {
+		Checkout *struct {
+			Href string `json:"href"`
+		} `json:"checkout"`
+	} `json:"_links"`
+}
+
+type mollieSubscription struct {
+	ID       string            `json:"id"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (p *Mollie) CreateCheckout(ctx context.Context, req CheckoutRequest) (CheckoutSession, error) {
+	metadata := map[string]string{
+		mollieMetadataOrg:  req.OrganizationID.String(),
+		mollieMetadataPlan: req.Plan.Code,
+	}
+	customerID := req.CustomerID
+	if customerID == "" {
+		var customer struct {
+			ID string `json:"id"`
+		}
+		body := map[string]any{"name": req.Contact.Name, "metadata": metadata}
+		if req.Contact.Email != "" {
+			body["email"] = req.Contact.Email
+		}
+		if err := p.client.Do(ctx, p.apiKey, http.MethodPost, "/customers", body, &customer); err != nil {
+			return CheckoutSession{}, err
+		}
+		customerID = customer.ID
+	}
+
+	var payment molliePayment
+	if err := p.client.Do(ctx, p.apiKey, http.MethodPost, "/payments", map[string]any{
+		"amount":       mollieAmountOf(req.Plan),
+		"description":  req.Description,
+		"sequenceType": mollieSequenceFirst,
+		"customerId":   customerID,
+		"redirectUrl":  req.SuccessURL,
+		"cancelUrl":    req.CancelURL,
+		"webhookUrl":   p.webhookURL,
+		"metadata":     metadata,
+	}, &payment); err != nil {
+		return CheckoutSession{}, err
+	}
+	if payment.Links.Checkout == nil || payment.Links.Checkout.Href == "" {
+		return CheckoutSession{}, apperr.BadRequest("mollie did not return a checkout url")
+	}
+	return CheckoutSession{URL: payment.Links.Checkout.Href, CustomerID: customerID}, nil
+}
+
+func (p *Mollie) CancelSubscription(ctx context.Context, customerID, subscriptionID string) error {
+	path := fmt.Sprintf("/customers/%s/subscriptions/%s", url.PathEscape(customerID), url.PathEscape(subscriptionID))
+	return p.client.Do(ctx, p.apiKey, http.MethodDelete, path, nil, nil)
+}
+
+// ParseWebhook fetches the payment Mollie reports. Mollie webhooks are not
+// signed; reading the payment back from the API is what authenticates them.
+// A paid first payment starts the subscription, which Mollie renews monthly
+// with recurring payments that report on the same webhook.
+func (p *Mollie) ParseWebhook(ctx context.Context, r *http.Request) (*SubscriptionUpdate, error) {
+	paymentID := strings.TrimSpace(r.PostFormValue("id"))
+	if !strings.HasPrefix(paymentID, "tr_") {
+		return nil, apperr.BadRequest("invalid mollie payment id")
+	}
+	var payment molliePayment
+	if err := p.client.Do(ctx, p.apiKey, http.MethodGet, "/payments/"+url.PathEscape(paymentID), nil, &payment); err != nil {
+		return nil, err
+	}
+
+	if payment.SequenceType == mollieSequenceFirst {
+		if payment.Status != "paid" {
+			return nil, nil
+		}
+		return p.startSubscription(ctx, payment)
+	}
+	if payment.SubscriptionID == "" {
+		return nil, nil
+	}
+
+	var sub mollieSubscription
+	path := fmt.Sprintf("/customers/%s/subscriptions/%s", url.PathEscape(payment.CustomerID), url.PathEscape(payment.SubscriptionID))
+	if err := p.client.Do(ctx, p.apiKey, http.MethodGet, path, nil, &sub); err != nil {
+		return nil, err
+	}
+	update := mollieUpdate(sub.Metadata, payment.CustomerID, payment.SubscriptionID)
+	if update == nil {
+		return nil, nil
+	}
+	switch payment.Status {
+	case "paid":
+		update.Status = repository.StatusActive
+		end := mollieNextPeriodEnd(payment)
+		update.CurrentPeriodEnd = &end
+	case "failed", "expired", "canceled":
+		update.Status = repository.StatusPastDue
+	default:
+		return nil, nil
+	}
+	return update, nil
+}
+
+func (p *Mollie) startSubscription(ctx context.Context, payment molliePayment) (*SubscriptionUpdate, error) {
+	update := mollieUpdate(payment.Metadata, payment.CustomerID, "")
+	if update == nil {
+		return nil, nil
+	}
+	end := mollieNextPeriodEnd(payment)
+
+	var sub mollieSubscription
+	path := fmt.Sprintf("/customers/%s/subscriptions", url.PathEscape(payment.CustomerID))
+	if err := p.client.Do(ctx, p.apiKey, http.MethodPost, path, map[string]any{
+		"amount":      payment.Amount,
+		"interval":    mollieInterval,
+		"startDate":   end.Format("2006-01-02"),
+		"description": payment.Description,
+		"webhookUrl":  p.webhookURL,
+		"metadata":    payment.Metadata,
+	}, &sub); err != nil {
+		return nil, err
+	}
+
+	update.SubscriptionID = sub.ID
+	update.Status = repository.StatusActive
+	update.CurrentPeriodEnd = &end
+	return update, nil
+}
+
+func mollieUpdate(metadata map[string]string, customerID, subscriptionID string) *SubscriptionUpdate {
+	organizationID, err := uuid.Parse(metadata[mollieMetadataOrg])
+	if err != nil {
+		return nil
+	}
+	return &SubscriptionUpdate{
+		OrganizationID: organizationID,
+		PlanCode:       metadata[mollieMetadataPlan],
+		CustomerID:     customerID,
+		SubscriptionID: subscriptionID,
+	}
+}
+
+// mollieNextPeriodEnd returns when the month paid for by a payment ends.
+func mollieNextPeriodEnd(payment molliePayment) time.Time {
+	paidAt := time.Now().UTC()
+	if payment.PaidAt != nil {
+		paidAt = payment.PaidAt.UTC()
+	}
+	return paidAt.AddDate(0, 1, 0)
+}
+
+func mollieAmountOf(plan repository.Plan) mollieAmount {
+	return mollieAmount{
+		Currency: strings.ToUpper(plan.Currency),
+		Value:    fmt.Sprintf("%d.%02d", plan.PriceCents/100, plan.PriceCents%100),
+	}
+}
+
+var _ PaymentProvider = (*Mollie)(nil)
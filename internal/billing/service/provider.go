@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"portal_final_backend/internal/billing/repository"
+
+	"github.com/google/uuid"
+)
+
+// Payment providers.
+const (
+	ProviderStripe = "stripe"
+	ProviderMollie = "mollie"
+)
+
+// CheckoutRequest starts the paid subscription of an organization.
+type CheckoutRequest struct {
+	OrganizationID uuid.UUID
+	Contact        repository.OrganizationContact
+	Plan           repository.Plan
+	// CustomerID is the provider customer of an earlier subscription, if any.
+	CustomerID  string
+	SuccessURL  string
+	CancelURL   string
+	Description string
+}
+
+// CheckoutSession is where the organization completes the payment.
+type CheckoutSession struct {
+	URL        string
+	CustomerID string
+}
+
+// SubscriptionUpdate is the state of a subscription reported by a provider.
+type SubscriptionUpdate struct {
+	OrganizationID    uuid.UUID
+	PlanCode          string
+	Status            string
+	CustomerID        string
+	SubscriptionID    string
+	CurrentPeriodEnd  *time.Time
+	CancelAtPeriodEnd bool
+	// EventAt is when the provider emitted the state. Updates emitted before
+	// the last applied one are ignored. It is nil for states fetched from the
+	// provider while handling the webhook, which are always current.
+	EventAt *time.Time
+}
+
+// PaymentProvider collects subscription payments of organizations.
+type PaymentProvider interface {
+	Name() string
+	CreateCheckout(ctx context.Context, req CheckoutRequest) (CheckoutSession, error)
+	// CancelSubscription stops the renewal; the paid period is kept.
+	CancelSubscription(ctx context.Context, customerID, subscriptionID string) error
+	// ParseWebhook verifies a webhook call and returns the subscription state it
+	// reports, or nil for events that do not change a subscription.
+	ParseWebhook(ctx context.Context, r *http.Request) (*SubscriptionUpdate, error)
+}
@@ -0,0 +1,573 @@
+// Package service implements platform subscriptions: plans and their limits,
+// usage metering, payment through Stripe or Mollie, and grace periods for
+// overdue payments.
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"portal_final_backend/internal/billing/repository"
+	"portal_final_backend/internal/billing/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+// MetricUsers is the seat limit of a plan. Unlike leads and AI runs it is not
+// counted per period but from the current members and open invites.
+const MetricUsers = "users"
+
+// statusNone reports an organization without a subscription.
+const statusNone = "none"
+
+// entitlementCacheTTL bounds how long another instance may still enforce a
+// subscription or plan that changed. Changes made by this instance apply at
+// once.
+const entitlementCacheTTL = 30 * time.Second
+
+const (
+	msgPaymentOverdue  = "subscription payment is overdue"
+	msgLimitReached    = "plan limit reached"
+	msgPlanNotFound    = "plan not found"
+	msgProviderMissing = "payment provider is not configured"
+)
+
+type Repository interface {
+	ListPlans(ctx context.Context, activeOnly bool) ([]repository.Plan, error)
+	GetPlan(ctx context.Context, code string) (repository.Plan, error)
+	GetSubscription(ctx context.Context, organizationID uuid.UUID) (*repository.Subscription, error)
+	SaveSubscription(ctx context.Context, s repository.Subscription) error
+	GetOrganizationContact(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationContact, error)
+	AdmitUsage(ctx context.Context, organizationID uuid.UUID, metric string, periodStart time.Time, limit *int64) (bool, error)
+	AddUsage(ctx context.Context, organizationID uuid.UUID, metric string, periodStart time.Time, quantity int64) error
+	GetUsage(ctx context.Context, organizationID uuid.UUID, metric string, periodStart time.Time) (int64, error)
+	CountSeats(ctx context.Context, organizationID uuid.UUID) (int64, error)
+}
+
+// Config holds the plan of organizations without a subscription and the grace
+// period of overdue payments. An empty default plan leaves them unlimited.
+type Config struct {
+	DefaultPlan string
+	GracePeriod time.Duration
+	// AppBaseURL is the frontend checkouts return to.
+	AppBaseURL string
+}
+
+type cachedSubscription struct {
+	sub       *repository.Subscription
+	expiresAt time.Time
+}
+
+type cachedPlan struct {
+	plan      repository.Plan
+	err       error
+	expiresAt time.Time
+}
+
+type Service struct {
+	repo      Repository
+	providers map[string]PaymentProvider
+	cfg       Config
+	log       *logger.Logger
+	now       func() time.Time
+
+	mu            sync.Mutex
+	subscriptions map[uuid.UUID]cachedSubscription
+	plans         map[string]cachedPlan
+}
+
+func New(repo Repository, cfg Config, log *logger.Logger) *Service {
+	return &Service{
+		repo:          repo,
+		providers:     make(map[string]PaymentProvider),
+		cfg:           cfg,
+		log:           log,
+		now:           time.Now,
+		subscriptions: make(map[uuid.UUID]cachedSubscription),
+		plans:         make(map[string]cachedPlan),
+	}
+}
+
+// RegisterProvider makes a payment provider available for checkouts and webhooks.
+func (s *Service) RegisterProvider(p PaymentProvider) {
+	s.providers[p.Name()] = p
+}
+
+// access is what a subscription grants at a point in time.
+type access struct {
+	// PlanCode is the plan that applies; empty means the default plan.
+	PlanCode string
+	// Overdue blocks the organization once a payment is overdue past its grace period.
+	Overdue    bool
+	GraceUntil *time.Time
+}
+
+// evaluate resolves the access of a subscription. Paid periods that were not
+// renewed and failed payments keep the plan for the grace period; canceled
+// subscriptions keep it until the paid period ends and then fall back to the
+// default plan.
+func evaluate(sub *repository.Subscription, now time.Time, grace time.Duration) access {
+	if sub == nil {
+		return access{}
+	}
+	switch sub.Status {
+	case repository.StatusActive:
+		if sub.CurrentPeriodEnd == nil || now.Before(*sub.CurrentPeriodEnd) {
+			return access{PlanCode: sub.PlanCode}
+		}
+		graceUntil := sub.CurrentPeriodEnd.Add(grace)
+		return access{PlanCode: sub.PlanCode, Overdue: !now.Before(graceUntil), GraceUntil: &graceUntil}
+	case repository.StatusPastDue:
+		graceUntil := sub.GraceUntil
+		if graceUntil == nil && sub.CurrentPeriodEnd != nil {
+			end := sub.CurrentPeriodEnd.Add(grace)
+			graceUntil = &end
+		}
+		if graceUntil == nil {
+			return access{PlanCode: sub.PlanCode}
+		}
+		return access{PlanCode: sub.PlanCode, Overdue: !now.Before(*graceUntil), GraceUntil: graceUntil}
+	case repository.StatusCanceled:
+		if sub.CurrentPeriodEnd != nil && now.Before(*sub.CurrentPeriodEnd) {
+			return access{PlanCode: sub.PlanCode}
+		}
+	}
+	return access{}
+}
+
+// entitlement resolves the subscription, its access and the plan that applies.
+// A nil plan is unlimited. Subscriptions and plans are cached, as every
+// authenticated change is checked against them.
+func (s *Service) entitlement(ctx context.Context, organizationID uuid.UUID) (*repository.Subscription, access, *repository.Plan, error) {
+	sub, err := s.cachedSubscription(ctx, organizationID)
+	if err != nil {
+		return nil, access{}, nil, err
+	}
+	acc := evaluate(sub, s.now(), s.cfg.GracePeriod)
+	code := acc.PlanCode
+	if code == "" {
+		code = s.cfg.DefaultPlan
+	}
+	if code == "" {
+		return sub, acc, nil, nil
+	}
+	plan, err := s.cachedPlan(ctx, code)
+	if errors.Is(err, repository.ErrPlanNotFound) {
+		s.log.Warn("billing: plan not found, limits not enforced", "organizationId", organizationID, "plan", code)
+		return sub, acc, nil, nil
+	}
+	if err != nil {
+		return nil, access{}, nil, err
+	}
+	return sub, acc, &plan, nil
+}
+
+func (s *Service) cachedSubscription(ctx context.Context, organizationID uuid.UUID) (*repository.Subscription, error) {
+	now := s.now()
+	s.mu.Lock()
+	cached, ok := s.subscriptions[organizationID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return copySubscription(cached.sub), nil
+	}
+
+	sub, err := s.repo.GetSubscription(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.subscriptions[organizationID] = cachedSubscription{sub: copySubscription(sub), expiresAt: now.Add(entitlementCacheTTL)}
+	s.mu.Unlock()
+	return sub, nil
+}
+
+func copySubscription(sub *repository.Subscription) *repository.Subscription {
+	if sub == nil {
+		return nil
+	}
+	c := *sub
+	return &c
+}
+
+// cachedPlan returns a plan by its code. Unknown plans are cached too.
+func (s *Service) cachedPlan(ctx context.Context, code string) (repository.Plan, error) {
+	now := s.now()
+	s.mu.Lock()
+	cached, ok := s.plans[code]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.plan, cached.err
+	}
+
+	plan, err := s.repo.GetPlan(ctx, code)
+	if err != nil && !errors.Is(err, repository.ErrPlanNotFound) {
+		return repository.Plan{}, err
+	}
+	s.mu.Lock()
+	s.plans[code] = cachedPlan{plan: plan, err: err, expiresAt: now.Add(entitlementCacheTTL)}
+	s.mu.Unlock()
+	return plan, err
+}
+
+// saveSubscription stores a subscription and drops its cached copy.
+func (s *Service) saveSubscription(ctx context.Context, sub repository.Subscription) error {
+	err := s.repo.SaveSubscription(ctx, sub)
+	s.mu.Lock()
+	delete(s.subscriptions, sub.OrganizationID)
+	s.mu.Unlock()
+	return err
+}
+
+func limitOf(plan *repository.Plan, metric string) *int64 {
+	if plan == nil {
+		return nil
+	}
+	switch metric {
+	case MetricUsers:
+		return plan.MaxUsers
+	case repository.MetricLeads:
+		return plan.MaxLeadsPerMonth
+	case repository.MetricAIRuns:
+		return plan.MaxAIRunsPerMonth
+	}
+	return nil
+}
+
+func periodStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *Service) used(ctx context.Context, organizationID uuid.UUID, metric string) (int64, error) {
+	if metric == MetricUsers {
+		return s.repo.CountSeats(ctx, organizationID)
+	}
+	return s.repo.GetUsage(ctx, organizationID, metric, periodStart(s.now()))
+}
+
+func limitReached(plan *repository.Plan, metric string, used, limit int64) error {
+	return apperr.PaymentRequired(msgLimitReached).WithDetails(map[string]any{
+		"plan":   plan.Code,
+		"metric": metric,
+		"limit":  limit,
+		"used":   used,
+	})
+}
+
+// CheckAccess rejects organizations whose payment is overdue past the grace period.
+func (s *Service) CheckAccess(ctx context.Context, organizationID uuid.UUID) error {
+	sub, acc, _, err := s.entitlement(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if !acc.Overdue {
+		return nil
+	}
+	return apperr.PaymentRequired(msgPaymentOverdue).WithDetails(map[string]any{
+		"status":     sub.Status,
+		"graceUntil": acc.GraceUntil,
+	})
+}
+
+// CheckLimit rejects adding one more unit of a metric once the plan limit is used.
+func (s *Service) CheckLimit(ctx context.Context, organizationID uuid.UUID, metric string) error {
+	_, _, plan, err := s.entitlement(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	limit := limitOf(plan, metric)
+	if limit == nil {
+		return nil
+	}
+	used, err := s.used(ctx, organizationID, metric)
+	if err != nil {
+		return err
+	}
+	if used >= *limit {
+		return limitReached(plan, metric, used, *limit)
+	}
+	return nil
+}
+
+// AdmitAIRun counts an AI run of an organization, or rejects it once the plan
+// limit is used. Billing failures are logged and let the run through so they
+// cannot take the agents down.
+func (s *Service) AdmitAIRun(ctx context.Context, organizationID uuid.UUID) error {
+	_, _, plan, err := s.entitlement(ctx, organizationID)
+	if err != nil {
+		s.log.Warn("billing: failed to resolve plan for ai run", "organizationId", organizationID, "error", err)
+		return nil
+	}
+	limit := limitOf(plan, repository.MetricAIRuns)
+	admitted, err := s.repo.AdmitUsage(ctx, organizationID, repository.MetricAIRuns, periodStart(s.now()), limit)
+	if err != nil {
+		s.log.Warn("billing: failed to meter ai run", "organizationId", organizationID, "error", err)
+		return nil
+	}
+	if !admitted {
+		return limitReached(plan, repository.MetricAIRuns, *limit, *limit)
+	}
+	return nil
+}
+
+// RecordLead counts a created lead. Leads from forms and webhooks are never
+// rejected, so customer requests are not lost, but they do count.
+func (s *Service) RecordLead(ctx context.Context, organizationID uuid.UUID) error {
+	return s.repo.AddUsage(ctx, organizationID, repository.MetricLeads, periodStart(s.now()), 1)
+}
+
+// ListPlans returns the plans organizations can subscribe to.
+func (s *Service) ListPlans(ctx context.Context) ([]transport.PlanResponse, error) {
+	plans, err := s.repo.ListPlans(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]transport.PlanResponse, len(plans))
+	for i := range plans {
+		resp[i] = toPlanResponse(plans[i])
+	}
+	return resp, nil
+}
+
+// GetSubscription returns the subscription of an organization with its usage.
+func (s *Service) GetSubscription(ctx context.Context, organizationID uuid.UUID) (transport.SubscriptionResponse, error) {
+	sub, acc, plan, err := s.entitlement(ctx, organizationID)
+	if err != nil {
+		return transport.SubscriptionResponse{}, err
+	}
+
+	resp := transport.SubscriptionResponse{
+		OrganizationID: organizationID,
+		Status:         statusNone,
+		PaymentOverdue: acc.Overdue,
+		GraceUntil:     acc.GraceUntil,
+		Usage:          make([]transport.UsageResponse, 0, 3),
+	}
+	if plan != nil {
+		p := toPlanResponse(*plan)
+		resp.Plan = &p
+	}
+	if sub != nil {
+		resp.Status = sub.Status
+		resp.Provider = sub.Provider
+		resp.CurrentPeriodEnd = sub.CurrentPeriodEnd
+		resp.CancelAtPeriodEnd = sub.CancelAtPeriodEnd
+	}
+	for _, metric := range []string{MetricUsers, repository.MetricLeads, repository.MetricAIRuns} {
+		used, err := s.used(ctx, organizationID, metric)
+		if err != nil {
+			return transport.SubscriptionResponse{}, err
+		}
+		resp.Usage = append(resp.Usage, transport.UsageResponse{Metric: metric, Used: used, Limit: limitOf(plan, metric)})
+	}
+	return resp, nil
+}
+
+// StartCheckout starts a paid subscription and returns where to pay for it.
+// A running subscription has to be canceled first.
+func (s *Service) StartCheckout(ctx context.Context, organizationID uuid.UUID, req transport.StartCheckoutRequest) (transport.CheckoutResponse, error) {
+	provider, ok := s.providers[req.Provider]
+	if !ok {
+		return transport.CheckoutResponse{}, apperr.BadRequest(msgProviderMissing)
+	}
+	plan, err := s.repo.GetPlan(ctx, req.PlanCode)
+	if errors.Is(err, repository.ErrPlanNotFound) || (err == nil && !plan.IsActive) {
+		return transport.CheckoutResponse{}, apperr.NotFound(msgPlanNotFound)
+	}
+	if err != nil {
+		return transport.CheckoutResponse{}, err
+	}
+
+	current, err := s.repo.GetSubscription(ctx, organizationID)
+	if err != nil {
+		return transport.CheckoutResponse{}, err
+	}
+	if current != nil && (current.Status == repository.StatusActive || current.Status == repository.StatusPastDue) {
+		return transport.CheckoutResponse{}, apperr.Conflict("organization already has a subscription; cancel it first")
+	}
+	contact, err := s.repo.GetOrganizationContact(ctx, organizationID)
+	if err != nil {
+		return transport.CheckoutResponse{}, err
+	}
+
+	checkout := CheckoutRequest{
+		OrganizationID: organizationID,
+		Contact:        contact,
+		Plan:           plan,
+		SuccessURL:     s.returnURL("success"),
+		CancelURL:      s.returnURL("canceled"),
+		Description:    "Abonnement " + plan.Name,
+	}
+	if current != nil && current.Provider != nil && *current.Provider == req.Provider && current.ProviderCustomerID != nil {
+		checkout.CustomerID = *current.ProviderCustomerID
+	}
+	session, err := provider.CreateCheckout(ctx, checkout)
+	if err != nil {
+		return transport.CheckoutResponse{}, err
+	}
+
+	// A canceled subscription keeps its paid period until the new one is paid.
+	if current == nil || evaluate(current, s.now(), s.cfg.GracePeriod).PlanCode == "" {
+		pending := repository.Subscription{
+			OrganizationID: organizationID,
+			PlanCode:       plan.Code,
+			Status:         repository.StatusPending,
+			Provider:       &req.Provider,
+		}
+		if session.CustomerID != "" {
+			pending.ProviderCustomerID = &session.CustomerID
+		}
+		if err := s.saveSubscription(ctx, pending); err != nil {
+			return transport.CheckoutResponse{}, err
+		}
+	}
+	return transport.CheckoutResponse{CheckoutURL: session.URL}, nil
+}
+
+func (s *Service) returnURL(result string) string {
+	return strings.TrimRight(s.cfg.AppBaseURL, "/") + "/settings/billing?checkout=" + result
+}
+
+// CancelSubscription stops the renewal of the subscription of an organization.
+// The plan applies until the paid period ends.
+func (s *Service) CancelSubscription(ctx context.Context, organizationID uuid.UUID) (transport.SubscriptionResponse, error) {
+	sub, err := s.repo.GetSubscription(ctx, organizationID)
+	if err != nil {
+		return transport.SubscriptionResponse{}, err
+	}
+	if sub == nil || (sub.Status != repository.StatusActive && sub.Status != repository.StatusPastDue) {
+		return transport.SubscriptionResponse{}, apperr.Conflict("organization has no running subscription")
+	}
+	if sub.Provider != nil && sub.ProviderSubscriptionID != nil {
+		provider, ok := s.providers[*sub.Provider]
+		if !ok {
+			return transport.SubscriptionResponse{}, apperr.BadRequest(msgProviderMissing)
+		}
+		var customerID string
+		if sub.ProviderCustomerID != nil {
+			customerID = *sub.ProviderCustomerID
+		}
+		if err := provider.CancelSubscription(ctx, customerID, *sub.ProviderSubscriptionID); err != nil {
+			return transport.SubscriptionResponse{}, err
+		}
+	}
+
+	sub.Status = repository.StatusCanceled
+	sub.CancelAtPeriodEnd = true
+	sub.GraceUntil = nil
+	if err := s.saveSubscription(ctx, *sub); err != nil {
+		return transport.SubscriptionResponse{}, err
+	}
+	return s.GetSubscription(ctx, organizationID)
+}
+
+// AssignPlan puts an organization on a plan without online payment.
+func (s *Service) AssignPlan(ctx context.Context, organizationID uuid.UUID, req transport.AssignPlanRequest) (transport.SubscriptionResponse, error) {
+	if _, err := s.repo.GetPlan(ctx, req.PlanCode); err != nil {
+		if errors.Is(err, repository.ErrPlanNotFound) {
+			return transport.SubscriptionResponse{}, apperr.NotFound(msgPlanNotFound)
+		}
+		return transport.SubscriptionResponse{}, err
+	}
+	if err := s.saveSubscription(ctx, repository.Subscription{
+		OrganizationID:   organizationID,
+		PlanCode:         req.PlanCode,
+		Status:           repository.StatusActive,
+		CurrentPeriodEnd: req.CurrentPeriodEnd,
+	}); err != nil {
+		return transport.SubscriptionResponse{}, err
+	}
+	return s.GetSubscription(ctx, organizationID)
+}
+
+// HandleWebhook applies the subscription state a provider reports.
+func (s *Service) HandleWebhook(ctx context.Context, providerName string, r *http.Request) error {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return apperr.NotFound(msgProviderMissing)
+	}
+	update, err := provider.ParseWebhook(ctx, r)
+	if err != nil || update == nil {
+		return err
+	}
+
+	current, err := s.repo.GetSubscription(ctx, update.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if staleUpdate(current, *update) {
+		s.log.Info("billing: stale webhook ignored", "organizationId", update.OrganizationID, "provider", providerName, "eventAt", update.EventAt)
+		return nil
+	}
+	next := applyUpdate(current, providerName, *update, s.now(), s.cfg.GracePeriod)
+	if next.PlanCode == "" {
+		s.log.Warn("billing: webhook without plan ignored", "organizationId", update.OrganizationID, "provider", providerName)
+		return nil
+	}
+	if err := s.saveSubscription(ctx, next); err != nil {
+		return err
+	}
+	s.log.Info("billing: subscription updated", "organizationId", update.OrganizationID, "provider", providerName, "status", next.Status, "plan", next.PlanCode)
+	return nil
+}
+
+// staleUpdate reports whether the provider emitted an update before the one
+// the subscription was last updated from.
+func staleUpdate(current *repository.Subscription, update SubscriptionUpdate) bool {
+	return current != nil && current.ProviderEventAt != nil && update.EventAt != nil &&
+		update.EventAt.Before(*current.ProviderEventAt)
+}
+
+// applyUpdate merges a provider update into the stored subscription. A failed
+// payment starts the grace period once; a paid one clears it.
+func applyUpdate(current *repository.Subscription, providerName string, update SubscriptionUpdate, now time.Time, grace time.Duration) repository.Subscription {
+	next := repository.Subscription{OrganizationID: update.OrganizationID}
+	if current != nil {
+		next = *current
+	}
+	next.Provider = &providerName
+	next.Status = update.Status
+	next.CancelAtPeriodEnd = update.CancelAtPeriodEnd
+	if update.PlanCode != "" {
+		next.PlanCode = update.PlanCode
+	}
+	if update.CustomerID != "" {
+		next.ProviderCustomerID = &update.CustomerID
+	}
+	if update.SubscriptionID != "" {
+		next.ProviderSubscriptionID = &update.SubscriptionID
+	}
+	if update.CurrentPeriodEnd != nil {
+		next.CurrentPeriodEnd = update.CurrentPeriodEnd
+	}
+	if update.EventAt != nil {
+		next.ProviderEventAt = update.EventAt
+	}
+
+	switch {
+	case next.Status != repository.StatusPastDue:
+		next.GraceUntil = nil
+	case current == nil || current.Status != repository.StatusPastDue || current.GraceUntil == nil:
+		graceUntil := now.Add(grace)
+		next.GraceUntil = &graceUntil
+	}
+	return next
+}
+
+func toPlanResponse(p repository.Plan) transport.PlanResponse {
+	return transport.PlanResponse{
+		Code:              p.Code,
+		Name:              p.Name,
+		PriceCents:        p.PriceCents,
+		Currency:          p.Currency,
+		MaxUsers:          p.MaxUsers,
+		MaxLeadsPerMonth:  p.MaxLeadsPerMonth,
+		MaxAIRunsPerMonth: p.MaxAIRunsPerMonth,
+		StripeAvailable:   p.StripePriceID != nil && *p.StripePriceID != "",
+	}
+}
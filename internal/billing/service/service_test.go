@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/billing/repository"
+	"portal_final_backend/internal/billing/transport"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	grace := 7 * 24 * time.Hour
+	future := now.Add(24 * time.Hour)
+	recent := now.Add(-24 * time.Hour)
+	longAgo := now.Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		sub      *repository.Subscription
+		wantPlan string
+		overdue  bool
+	}{
+		{"no subscription", nil, "", false},
+		{"pending", &repository.Subscription{PlanCode: "growth", Status: repository.StatusPending}, "", false},
+		{"active", &repository.Subscription{PlanCode: "growth", Status: repository.StatusActive, CurrentPeriodEnd: &future}, "growth", false},
+		{"not renewed within grace", &repository.Subscription{PlanCode: "growth", Status: repository.StatusActive, CurrentPeriodEnd: &recent}, "growth", false},
+		{"not renewed past grace", &repository.Subscription{PlanCode: "growth", Status: repository.StatusActive, CurrentPeriodEnd: &longAgo}, "growth", true},
+		{"past due within grace", &repository.Subscription{PlanCode: "growth", Status: repository.StatusPastDue, GraceUntil: &future}, "growth", false},
+		{"past due past grace", &repository.Subscription{PlanCode: "growth", Status: repository.StatusPastDue, GraceUntil: &recent}, "growth", true},
+		{"canceled in paid period", &repository.Subscription{PlanCode: "growth", Status: repository.StatusCanceled, CurrentPeriodEnd: &future}, "growth", false},
+		{"canceled after paid period", &repository.Subscription{PlanCode: "growth", Status: repository.StatusCanceled, CurrentPeriodEnd: &recent}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluate(tt.sub, now, grace)
+			if got.PlanCode != tt.wantPlan || got.Overdue != tt.overdue {
+				t.Errorf("evaluate() = plan %q overdue %v, want plan %q overdue %v", got.PlanCode, got.Overdue, tt.wantPlan, tt.overdue)
+			}
+		})
+	}
+}
+
+func TestApplyUpdateStartsGraceOnce(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	grace := 7 * 24 * time.Hour
+	update := SubscriptionUpdate{OrganizationID: uuid.New(), PlanCode: "starter", Status: repository.StatusPastDue}
+
+	first := applyUpdate(&repository.Subscription{PlanCode: "starter", Status: repository.StatusActive}, ProviderMollie, update, now, grace)
+	if first.GraceUntil == nil || !first.GraceUntil.Equal(now.Add(grace)) {
+		t.Fatalf("grace until = %v, want %v", first.GraceUntil, now.Add(grace))
+	}
+
+	retried := applyUpdate(&first, ProviderMollie, update, now.Add(48*time.Hour), grace)
+	if !retried.GraceUntil.Equal(*first.GraceUntil) {
+		t.Errorf("a repeated failure moved the grace period to %v", retried.GraceUntil)
+	}
+
+	update.Status = repository.StatusActive
+	if paid := applyUpdate(&retried, ProviderMollie, update, now, grace); paid.GraceUntil != nil {
+		t.Error("expected a paid subscription to clear the grace period")
+	}
+}
+
+func TestVerifyStripeSignature(t *testing.T) {
+	now := time.Unix(1_792_000_000, 0)
+	payload := []byte(`{"type":"customer.subscription.updated"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte(timestamp + "." + string(payload)))
+	header := "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !verifyStripeSignature(payload, header, "whsec_test", now, 5*time.Minute) {
+		t.Error("expected a valid signature to verify")
+	}
+	if verifyStripeSignature(payload, header, "whsec_other", now, 5*time.Minute) {
+		t.Error("expected a signature under another secret to fail")
+	}
+	if verifyStripeSignature(payload, header, "whsec_test", now.Add(time.Hour), 5*time.Minute) {
+		t.Error("expected an old signature to fail")
+	}
+}
+
+func TestStaleUpdateIgnoresEarlierEvents(t *testing.T) {
+	applied := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	earlier, later := applied.Add(-time.Minute), applied.Add(time.Minute)
+	current := &repository.Subscription{Status: repository.StatusCanceled, ProviderEventAt: &applied}
+
+	if !staleUpdate(current, SubscriptionUpdate{Status: repository.StatusActive, EventAt: &earlier}) {
+		t.Error("expected an event emitted before the applied one to be stale")
+	}
+	if staleUpdate(current, SubscriptionUpdate{EventAt: &later}) || staleUpdate(current, SubscriptionUpdate{EventAt: &applied}) {
+		t.Error("expected events emitted at or after the applied one to apply")
+	}
+	if staleUpdate(current, SubscriptionUpdate{}) || staleUpdate(nil, SubscriptionUpdate{EventAt: &earlier}) {
+		t.Error("expected updates without event time or subscription to apply")
+	}
+
+	next := applyUpdate(current, ProviderStripe, SubscriptionUpdate{Status: repository.StatusActive, EventAt: &later}, later, time.Hour)
+	if next.ProviderEventAt == nil || !next.ProviderEventAt.Equal(later) {
+		t.Errorf("provider event at = %v, want %v", next.ProviderEventAt, later)
+	}
+}
+
+// countingRepository serves one subscription and plan and counts the reads.
+type countingRepository struct {
+	Repository
+	sub                 *repository.Subscription
+	subReads, planReads int
+}
+
+func (r *countingRepository) GetSubscription(context.Context, uuid.UUID) (*repository.Subscription, error) {
+	r.subReads++
+	return r.sub, nil
+}
+
+func (r *countingRepository) GetPlan(_ context.Context, code string) (repository.Plan, error) {
+	r.planReads++
+	return repository.Plan{Code: code}, nil
+}
+
+func (r *countingRepository) GetUsage(context.Context, uuid.UUID, string, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *countingRepository) CountSeats(context.Context, uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (r *countingRepository) SaveSubscription(_ context.Context, sub repository.Subscription) error {
+	r.sub = &sub
+	return nil
+}
+
+func TestEntitlementIsCachedUntilSaved(t *testing.T) {
+	orgID := uuid.New()
+	repo := &countingRepository{sub: &repository.Subscription{OrganizationID: orgID, PlanCode: "starter", Status: repository.StatusActive}}
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	svc := New(repo, Config{}, logger.New("test"))
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 3 {
+		if err := svc.CheckAccess(ctx, orgID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if repo.subReads != 1 || repo.planReads != 1 {
+		t.Fatalf("reads = %d subscriptions, %d plans, want 1 each", repo.subReads, repo.planReads)
+	}
+
+	if _, err := svc.AssignPlan(ctx, orgID, transport.AssignPlanRequest{PlanCode: "growth"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, plan, err := svc.entitlement(ctx, orgID); err != nil || plan == nil || plan.Code != "growth" {
+		t.Fatalf("plan after assignment = %v, %v, want growth", plan, err)
+	}
+
+	subReads := repo.subReads
+	now = now.Add(entitlementCacheTTL)
+	if _, _, _, err := svc.entitlement(ctx, orgID); err != nil {
+		t.Fatal(err)
+	}
+	if repo.subReads != subReads+1 {
+		t.Error("expected the cached subscription to expire")
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/billing/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	stripeAPIBaseURL          = "https://api.stripe.com/v1"
+	stripeSignatureHeader     = "Stripe-Signature"
+	stripeSignatureTolerance  = 5 * time.Minute
+	stripeMetadataOrg         = "organization_id"
+	stripeMetadataPlan        = "plan_code"
+	stripeMaxWebhookBodyBytes = 1 << 20
+)
+
+// Stripe collects subscriptions through Stripe Checkout and Billing.
+type Stripe struct {
+	secretKey     string
+	webhookSecret string
+	baseURL       string
+	client        *http.Client
+	now           func() time.Time
+}
+
+// NewStripe creates the Stripe provider.
+func NewStripe(secretKey, webhookSecret string) *Stripe {
+	return &Stripe{
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		baseURL:       stripeAPIBaseURL,
+		client:        &http.Client{Timeout: 20 * time.Second},
+		now:           time.Now,
+	}
+}
+
+func (p *Stripe) Name() string {
+	return ProviderStripe
+}
+
+func (p *Stripe) CreateCheckout(ctx context.Context, req CheckoutRequest) (CheckoutSession, error) {
+	if req.Plan.StripePriceID == nil || *req.Plan.StripePriceID == "" {
+		return CheckoutSession{}, apperr.BadRequest("plan is not available through stripe")
+	}
+	org := req.OrganizationID.String()
+	form := url.Values{
+		"mode":                                 {"subscription"},
+		"line_items[0][price]":                 {*req.Plan.StripePriceID},
+		"line_items[0][quantity]":              {"1"},
+		"client_reference_id":                  {org},
+		"success_url":                          {req.SuccessURL},
+		"cancel_url":                           {req.CancelURL},
+		"metadata[" + stripeMetadataOrg + "]":  {org},
+		"metadata[" + stripeMetadataPlan + "]": {req.Plan.Code},
+		"subscription_data[metadata][" + stripeMetadataOrg + "]":  {org},
+		"subscription_data[metadata][" + stripeMetadataPlan + "]": {req.Plan.Code},
+	}
+	if req.CustomerID != "" {
+		form.Set("customer", req.CustomerID)
+	} else if req.Contact.Email != "" {
+		form.Set("customer_email", req.Contact.Email)
+	}
+
+	var session struct {
+		URL      string `json:"url"`
+		Customer string `json:"customer"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/checkout/sessions", form, &session); err != nil {
+		return CheckoutSession{}, err
+	}
+	return CheckoutSession{URL: session.URL, CustomerID: session.Customer}, nil
+}
+
+func (p *Stripe) CancelSubscription(ctx context.Context, _, subscriptionID string) error {
+	form := url.Values{"cancel_at_period_end": {"true"}}
+	return p.do(ctx, http.MethodPost, "/subscriptions/"+url.PathEscape(subscriptionID), form, nil)
+}
+
+type stripeSubscription struct {
+	ID                string            `json:"id"`
+	Customer          string            `json:"customer"`
+	Status            string            `json:"status"`
+	CurrentPeriodEnd  int64             `json:"current_period_end"`
+	CancelAtPeriodEnd bool              `json:"cancel_at_period_end"`
+	Metadata          map[string]string `json:"metadata"`
+	Items             struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+func (p *Stripe) ParseWebhook(_ context.Context, r *http.Request) (*SubscriptionUpdate, error) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, stripeMaxWebhookBodyBytes))
+	if err != nil {
+		return nil, apperr.BadRequest("invalid webhook body")
+	}
+	if !verifyStripeSignature(payload, r.Header.Get(stripeSignatureHeader), p.webhookSecret, p.now(), stripeSignatureTolerance) {
+		return nil, apperr.Unauthorized("invalid stripe signature")
+	}
+
+	var event struct {
+		Type    string `json:"type"`
+		Created int64  `json:"created"`
+		Data    struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, apperr.BadRequest("invalid stripe event")
+	}
+	switch event.Type {
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+	default:
+		return nil, nil
+	}
+
+	var sub stripeSubscription
+	if err := json.Unmarshal(event.Data.Object, &sub); err != nil {
+		return nil, apperr.BadRequest("invalid stripe subscription")
+	}
+	update := stripeSubscriptionUpdate(sub)
+	if update != nil && event.Created > 0 {
+		created := time.Unix(event.Created, 0).UTC()
+		update.EventAt = &created
+	}
+	return update, nil
+}
+
+// stripeSubscriptionUpdate maps a Stripe subscription onto our statuses. The
+// organization and plan come from the metadata set at checkout.
+func stripeSubscriptionUpdate(sub stripeSubscription) *SubscriptionUpdate {
+	organizationID, err := uuid.Parse(sub.Metadata[stripeMetadataOrg])
+	if err != nil {
+		// Not one of our platform subscriptions.
+		return nil
+	}
+	update := &SubscriptionUpdate{
+		OrganizationID:    organizationID,
+		PlanCode:          sub.Metadata[stripeMetadataPlan],
+		CustomerID:        sub.Customer,
+		SubscriptionID:    sub.ID,
+		CancelAtPeriodEnd: sub.CancelAtPeriodEnd,
+	}
+	switch sub.Status {
+	case "active", "trialing":
+		update.Status = repository.StatusActive
+	case "past_due", "unpaid":
+		update.Status = repository.StatusPastDue
+	case "canceled", "incomplete_expired":
+		update.Status = repository.StatusCanceled
+	default:
+		update.Status = repository.StatusPending
+	}
+
+	periodEnd := sub.CurrentPeriodEnd
+	if periodEnd == 0 && len(sub.Items.Data) > 0 {
+		// Newer API versions report the period per subscription item.
+		periodEnd = sub.Items.Data[0].CurrentPeriodEnd
+	}
+	if periodEnd > 0 {
+		end := time.Unix(periodEnd, 0).UTC()
+		update.CurrentPeriodEnd = &end
+	}
+	return update
+}
+
+// verifyStripeSignature checks the Stripe-Signature header: an HMAC-SHA256 of
+// "{timestamp}.{payload}" under the endpoint secret, signed recently.
+func verifyStripeSignature(payload []byte, header, secret string, now time.Time, tolerance time.Duration) bool {
+	if secret == "" || header == "" {
+		return false
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(signedAt, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Stripe) do(ctx context.Context, method, path string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("build stripe request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return apperr.BadRequest(fmt.Sprintf("stripe request failed with status %d", resp.StatusCode))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode stripe response: %w", err)
+	}
+	return nil
+}
+
+var _ PaymentProvider = (*Stripe)(nil)
@@ -0,0 +1,57 @@
+// Package transport provides DTOs for platform billing.
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlanResponse is a subscription plan. A nil limit is unlimited.
+type PlanResponse struct {
+	Code              string `json:"code"`
+	Name              string `json:"name"`
+	PriceCents        int64  `json:"priceCents"`
+	Currency          string `json:"currency"`
+	MaxUsers          *int64 `json:"maxUsers"`
+	MaxLeadsPerMonth  *int64 `json:"maxLeadsPerMonth"`
+	MaxAIRunsPerMonth *int64 `json:"maxAiRunsPerMonth"`
+	StripeAvailable   bool   `json:"stripeAvailable"`
+}
+
+// UsageResponse is the usage of a limit in the current period.
+type UsageResponse struct {
+	Metric string `json:"metric"`
+	Used   int64  `json:"used"`
+	Limit  *int64 `json:"limit"`
+}
+
+// SubscriptionResponse reports the subscription of an organization and the
+// plan that currently applies to it.
+type SubscriptionResponse struct {
+	OrganizationID    uuid.UUID       `json:"organizationId"`
+	Plan              *PlanResponse   `json:"plan"`
+	Status            string          `json:"status"`
+	Provider          *string         `json:"provider,omitempty"`
+	CurrentPeriodEnd  *time.Time      `json:"currentPeriodEnd,omitempty"`
+	GraceUntil        *time.Time      `json:"graceUntil,omitempty"`
+	CancelAtPeriodEnd bool            `json:"cancelAtPeriodEnd"`
+	PaymentOverdue    bool            `json:"paymentOverdue"`
+	Usage             []UsageResponse `json:"usage"`
+}
+
+type StartCheckoutRequest struct {
+	PlanCode string `json:"planCode" validate:"required"`
+	Provider string `json:"provider" validate:"required,oneof=stripe mollie"`
+}
+
+type CheckoutResponse struct {
+	CheckoutURL string `json:"checkoutUrl"`
+}
+
+// AssignPlanRequest puts an organization on a plan without online payment,
+// e.g. for customers that are invoiced. A nil period end never expires.
+type AssignPlanRequest struct {
+	PlanCode         string     `json:"planCode" validate:"required"`
+	CurrentPeriodEnd *time.Time `json:"currentPeriodEnd"`
+}
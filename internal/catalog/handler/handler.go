@@ -391,6 +391,25 @@ func (h *Handler) CreateCatalogAsset(c *gin.Context) {
 	httpkit.JSON(c, http.StatusCreated, result)
 }
 
+// UploadCatalogAsset streams a multipart file upload to MinIO and creates the asset.
+// POST /api/v1/admin/catalog/products/:id/assets/upload?assetType=image|document
+func (h *Handler) UploadCatalogAsset(c *gin.Context) {
+	productID, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.UploadCatalogAsset(c.Request.Context(), tenantID, productID, c.Query("assetType"), c.Request)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, result)
+}
+
 // CreateCatalogURLAsset creates a URL-based catalog asset (terms URL).
 // POST /api/v1/admin/catalog/products/:id/assets/url
 func (h *Handler) CreateCatalogURLAsset(c *gin.Context) {
@@ -501,6 +520,21 @@ func (h *Handler) SearchProductsForAutocomplete(c *gin.Context) {
 	httpkit.OK(c, result)
 }
 
+// GetEmbeddingCoverage reports index coverage and embedding model drift of the catalog.
+// GET /api/v1/admin/catalog/embeddings/coverage
+func (h *Handler) GetEmbeddingCoverage(c *gin.Context) {
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetEmbeddingCoverage(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
 // =====================================================================
 // Internal DRY Helpers
 // =====================================================================
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"portal_final_backend/internal/catalog/transport"
+	"portal_final_backend/platform/httpkit"
+)
+
+// maxPriceFeedUploadBytes caps uploaded supplier price files.
+const maxPriceFeedUploadBytes = 20 << 20
+
+// ListPriceFeeds retrieves the supplier price feeds.
+// GET /api/v1/admin/catalog/price-feeds
+func (h *Handler) ListPriceFeeds(c *gin.Context) {
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListPriceFeeds(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// CreatePriceFeed configures a supplier price feed.
+// POST /api/v1/admin/catalog/price-feeds
+func (h *Handler) CreatePriceFeed(c *gin.Context) {
+	req, ok := httpkit.BindJSON[transport.PriceFeedRequest](c, h.val)
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.CreatePriceFeed(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, result)
+}
+
+// UpdatePriceFeed replaces the settings of a supplier price feed.
+// PUT /api/v1/admin/catalog/price-feeds/:id
+func (h *Handler) UpdatePriceFeed(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.PriceFeedRequest](c, h.val)
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.UpdatePriceFeed(c.Request.Context(), tenantID, id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// DeletePriceFeed removes a supplier price feed.
+// DELETE /api/v1/admin/catalog/price-feeds/:id
+func (h *Handler) DeletePriceFeed(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.DeletePriceFeed(c.Request.Context(), tenantID, id)) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RunPriceFeed fetches and imports a supplier price feed without waiting for its interval.
+// POST /api/v1/admin/catalog/price-feeds/:id/run
+func (h *Handler) RunPriceFeed(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.RunPriceFeed(c.Request.Context(), tenantID, id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// ImportPriceFeed imports a supplier price file uploaded as the multipart field "file".
+// POST /api/v1/admin/catalog/price-feeds/:id/import
+func (h *Handler) ImportPriceFeed(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, "file is required")
+		return
+	}
+	if header.Size > maxPriceFeedUploadBytes {
+		httpkit.Error(c, http.StatusRequestEntityTooLarge, msgInvalidRequest, "file is too large")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, "file cannot be read")
+		return
+	}
+	defer file.Close()
+	body, err := io.ReadAll(io.LimitReader(file, maxPriceFeedUploadBytes))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, "file cannot be read")
+		return
+	}
+
+	result, err := h.svc.ImportPriceFeed(c.Request.Context(), tenantID, id, body)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// ListPriceChanges retrieves the price change history, optionally of one feed.
+// GET /api/v1/admin/catalog/price-changes
+func (h *Handler) ListPriceChanges(c *gin.Context) {
+	req, ok := httpkit.BindQuery[transport.ListPriceChangesRequest](c, h.val)
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListPriceChanges(c.Request.Context(), tenantID, nil, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// ListProductPriceHistory retrieves the price change history of a product.
+// GET /api/v1/catalog/products/:id/price-history
+func (h *Handler) ListProductPriceHistory(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListPriceChangesRequest](c, h.val)
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListPriceChanges(c.Request.Context(), tenantID, &id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// ListPriceMarginRules retrieves the margin rules.
+// GET /api/v1/admin/catalog/margin-rules
+func (h *Handler) ListPriceMarginRules(c *gin.Context) {
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListPriceMarginRules(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// SavePriceMarginRule sets the margin of a feed and product type scope.
+// PUT /api/v1/admin/catalog/margin-rules
+func (h *Handler) SavePriceMarginRule(c *gin.Context) {
+	req, ok := httpkit.BindJSON[transport.PriceMarginRuleRequest](c, h.val)
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.SavePriceMarginRule(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// DeletePriceMarginRule removes a margin rule.
+// DELETE /api/v1/admin/catalog/margin-rules/:id
+func (h *Handler) DeletePriceMarginRule(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.DeletePriceMarginRule(c.Request.Context(), tenantID, id)) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"portal_final_backend/internal/catalog/transport"
+	"portal_final_backend/platform/httpkit"
+)
+
+// GetProductStock retrieves the stock level and recent movements of a product.
+// GET /api/v1/catalog/products/:id/stock
+func (h *Handler) GetProductStock(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetProductStock(c.Request.Context(), tenantID, id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// SetProductStock starts tracking the stock of a product or corrects its counted quantity.
+// PUT /api/v1/admin/catalog/products/:id/stock
+func (h *Handler) SetProductStock(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.SetProductStockRequest](c, h.val)
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.SetProductStock(c.Request.Context(), tenantID, identity.UserID(), id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// AdjustProductStock books a delivery or correction on a tracked product.
+// POST /api/v1/admin/catalog/products/:id/stock/adjustments
+func (h *Handler) AdjustProductStock(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.AdjustProductStockRequest](c, h.val)
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.AdjustProductStock(c.Request.Context(), tenantID, identity.UserID(), id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// StopTrackingProductStock stops tracking the stock of a product.
+// DELETE /api/v1/admin/catalog/products/:id/stock
+func (h *Handler) StopTrackingProductStock(c *gin.Context) {
+	id, ok := h.parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.StopTrackingProductStock(c.Request.Context(), tenantID, id)) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListLowStockProducts retrieves the tracked products at or below their low-stock threshold.
+// GET /api/v1/admin/catalog/stock/low
+func (h *Handler) ListLowStockProducts(c *gin.Context) {
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListLowStockProducts(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// GetStockSettings retrieves which event consumes stock.
+// GET /api/v1/admin/catalog/stock/settings
+func (h *Handler) GetStockSettings(c *gin.Context) {
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetStockSettings(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+// UpdateStockSettings selects which event consumes stock.
+// PUT /api/v1/admin/catalog/stock/settings
+func (h *Handler) UpdateStockSettings(c *gin.Context) {
+	req, ok := httpkit.BindJSON[transport.StockSettingsRequest](c, h.val)
+	if !ok {
+		return
+	}
+	tenantID, ok := h.getTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.UpdateStockSettings(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
@@ -15,6 +15,7 @@ import (
 	"portal_final_backend/platform/config"
 	"portal_final_backend/platform/logger"
 	"portal_final_backend/platform/qdrant"
+	"portal_final_backend/platform/secrets"
 	"portal_final_backend/platform/validator"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -24,8 +25,13 @@ import (
 const (
 	pathVatRates        = "/catalog/vat-rates"
 	pathProducts        = "/catalog/products"
+	pathEmbeddings      = "/catalog/embeddings"
+	pathPriceFeeds      = "/catalog/price-feeds"
+	pathMarginRules     = "/catalog/margin-rules"
+	pathStock           = "/catalog/stock"
 	pathProductID       = "/:id"
 	pathMaterials       = pathProductID + "/materials"
+	pathProductStock    = pathProductID + "/stock"
 	pathAssets          = pathProductID + "/assets"
 	pathAssetIDDownload = pathAssets + "/:assetId/download"
 	pathAssetID         = pathAssets + "/:assetId"
@@ -56,6 +62,7 @@ func NewModule(
 			BaseURL:    cfg.GetCatalogEmbeddingAPIURL(),
 			APIKey:     cfg.GetCatalogEmbeddingAPIKey(),
 			Collection: cfg.GetCatalogEmbeddingCollection(),
+			Model:      cfg.GetCatalogEmbeddingModel(),
 		})
 	}
 
@@ -67,15 +74,17 @@ func NewModule(
 		})
 	}
 
-	// Factory helper to avoid repetitive Qdrant client boilerplate.
-	newQdrant := func(collection string) *qdrant.Client {
+	// Factory helper to avoid repetitive Qdrant client boilerplate. Only the catalog
+	// collection is tenant-owned; the reference collections are shared by design.
+	newQdrant := func(collection string, tenancyMode string) *qdrant.Client {
 		if cfg.GetQdrantURL() == "" || collection == "" {
 			return nil
 		}
 		return qdrant.NewClient(qdrant.Config{
-			BaseURL:    cfg.GetQdrantURL(),
-			APIKey:     cfg.GetQdrantAPIKey(),
-			Collection: collection,
+			BaseURL:     cfg.GetQdrantURL(),
+			APIKey:      cfg.GetQdrantAPIKey(),
+			Collection:  collection,
+			TenancyMode: tenancyMode,
 		})
 	}
 
@@ -86,10 +95,12 @@ func NewModule(
 		Logger:              log,
 		EmbeddingClient:     embedClient,
 		EmbeddingCollection: cfg.GetCatalogEmbeddingCollection(),
+		EmbeddingVersion:    cfg.GetCatalogEmbeddingVersion(),
+		VectorTenancyMode:   cfg.GetQdrantTenancyMode(),
 		SearchEmbedding:     searchEmbed,
-		CatalogQdrant:       newQdrant(cfg.GetCatalogEmbeddingCollection()),
-		QdrantClient:        newQdrant(cfg.GetQdrantCollection()),
-		BouwmaatQdrant:      newQdrant(cfg.GetBouwmaatEmbeddingCollection()),
+		CatalogQdrant:       newQdrant(cfg.GetCatalogEmbeddingCollection(), cfg.GetQdrantTenancyMode()),
+		QdrantClient:        newQdrant(cfg.GetQdrantCollection(), qdrant.TenancyShared),
+		BouwmaatQdrant:      newQdrant(cfg.GetBouwmaatEmbeddingCollection(), qdrant.TenancyShared),
 	})
 
 	return &Module{
@@ -114,6 +125,16 @@ func (m *Module) Repository() repository.Repository {
 	return m.repo
 }
 
+// SetEventBus enables price jump alerts from supplier price feeds.
+func (m *Module) SetEventBus(bus events.Bus) {
+	m.service.SetEventBus(bus)
+}
+
+// SetPriceFeedKeyring enables storing supplier API tokens on price feeds.
+func (m *Module) SetPriceFeedKeyring(keyring *secrets.Keyring) {
+	m.service.SetPriceFeedKeyring(keyring)
+}
+
 // RegisterRoutes mounts catalog routes using centralized path constants.
 func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	// ---------------------------------------------------------
@@ -141,6 +162,8 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 		prodProtected.GET("/search", m.handler.SearchProductsForAutocomplete)
 		prodProtected.GET(pathProductID, m.handler.GetProductByID)
 		prodProtected.GET(pathMaterials, m.handler.ListProductMaterials)
+		prodProtected.GET(pathProductID+"/price-history", m.handler.ListProductPriceHistory)
+		prodProtected.GET(pathProductStock, m.handler.GetProductStock)
 		prodProtected.GET(pathAssets, m.handler.ListCatalogAssets)
 		prodProtected.GET(pathAssetIDDownload, m.handler.GetCatalogAssetDownloadURL)
 	}
@@ -156,17 +179,61 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 		prodAdmin.POST(pathMaterials, m.handler.AddProductMaterials)
 		prodAdmin.DELETE(pathMaterials, m.handler.RemoveProductMaterials)
 
+		// Stock
+		prodAdmin.PUT(pathProductStock, m.handler.SetProductStock)
+		prodAdmin.DELETE(pathProductStock, m.handler.StopTrackingProductStock)
+		prodAdmin.POST(pathProductStock+"/adjustments", m.handler.AdjustProductStock)
+
 		// Assets
 		prodAdmin.POST(pathProductID+"/assets/presign", m.handler.GetCatalogAssetPresign)
 		prodAdmin.POST(pathAssets, m.handler.CreateCatalogAsset)
+		prodAdmin.POST(pathProductID+"/assets/upload", m.handler.UploadCatalogAsset)
 		prodAdmin.POST(pathProductID+"/assets/url", m.handler.CreateCatalogURLAsset)
 		prodAdmin.DELETE(pathAssetID, m.handler.DeleteCatalogAsset)
 	}
+
+	// ---------------------------------------------------------
+	// Supplier Price Feeds
+	// ---------------------------------------------------------
+	feedAdmin := ctx.Admin.Group(pathPriceFeeds)
+	{
+		feedAdmin.GET("", m.handler.ListPriceFeeds)
+		feedAdmin.POST("", m.handler.CreatePriceFeed)
+		feedAdmin.PUT(pathProductID, m.handler.UpdatePriceFeed)
+		feedAdmin.DELETE(pathProductID, m.handler.DeletePriceFeed)
+		feedAdmin.POST(pathProductID+"/run", m.handler.RunPriceFeed)
+		feedAdmin.POST(pathProductID+"/import", m.handler.ImportPriceFeed)
+	}
+	ctx.Admin.GET("/catalog/price-changes", m.handler.ListPriceChanges)
+
+	marginAdmin := ctx.Admin.Group(pathMarginRules)
+	{
+		marginAdmin.GET("", m.handler.ListPriceMarginRules)
+		marginAdmin.PUT("", m.handler.SavePriceMarginRule)
+		marginAdmin.DELETE(pathProductID, m.handler.DeletePriceMarginRule)
+	}
+
+	// ---------------------------------------------------------
+	// Stock
+	// ---------------------------------------------------------
+	stockAdmin := ctx.Admin.Group(pathStock)
+	{
+		stockAdmin.GET("/low", m.handler.ListLowStockProducts)
+		stockAdmin.GET("/settings", m.handler.GetStockSettings)
+		stockAdmin.PUT("/settings", m.handler.UpdateStockSettings)
+	}
+
+	// ---------------------------------------------------------
+	// Embedding Index
+	// ---------------------------------------------------------
+	ctx.Admin.GET(pathEmbeddings+"/coverage", m.handler.GetEmbeddingCoverage)
 }
 
 // RegisterHandlers subscribes the module to system-wide events.
 func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
 	bus.Subscribe(events.OrganizationCreated{}.EventName(), m)
+	bus.Subscribe(events.QuoteAccepted{}.EventName(), m)
+	bus.Subscribe(events.PartnerOfferAccepted{}.EventName(), m)
 }
 
 // Handle processes subscribed domain events.
@@ -174,6 +241,10 @@ func (m *Module) Handle(ctx context.Context, event events.Event) error {
 	switch e := event.(type) {
 	case events.OrganizationCreated:
 		return m.service.SeedDefaultVatRates(ctx, e.OrganizationID)
+	case events.QuoteAccepted:
+		return m.service.ConsumeStockForAcceptedQuote(ctx, e.OrganizationID, e.QuoteID)
+	case events.PartnerOfferAccepted:
+		return m.service.ConsumeStockForAcceptedPartnerOffer(ctx, e.OrganizationID, e.LeadServiceID)
 	default:
 		return nil
 	}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RecordProductEmbedding stores the model and version that produced a product vector.
+func (r *Repo) RecordProductEmbedding(ctx context.Context, params RecordProductEmbeddingParams) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_catalog_product_embeddings (product_id, organization_id, collection, embedding_model, embedding_version, indexed_at)
+		SELECT $1, $2, $3, $4, $5, now()
+		WHERE EXISTS (SELECT 1 FROM RAC_catalog_products WHERE id = $1 AND organization_id = $2)
+		ON CONFLICT (product_id) DO UPDATE SET
+			collection = EXCLUDED.collection,
+			embedding_model = EXCLUDED.embedding_model,
+			embedding_version = EXCLUDED.embedding_version,
+			indexed_at = now()`,
+		params.ProductID, params.OrganizationID, params.Collection, params.Model, params.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("record product embedding: %w", err)
+	}
+	return nil
+}
+
+// ListStaleProductEmbeddings returns products whose vector is missing, was produced by
+// another model or version, or predates the last product update. Never-indexed products
+// come first.
+func (r *Repo) ListStaleProductEmbeddings(ctx context.Context, model, version string, limit int) ([]StaleProductEmbedding, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT p.organization_id, p.id
+		FROM RAC_catalog_products p
+		LEFT JOIN RAC_catalog_product_embeddings e ON e.product_id = p.id
+		WHERE e.product_id IS NULL
+			OR e.embedding_model <> $1
+			OR e.embedding_version <> $2
+			OR e.indexed_at < p.updated_at
+		ORDER BY e.indexed_at ASC NULLS FIRST, p.id
+		LIMIT $3`, model, version, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list stale product embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]StaleProductEmbedding, 0)
+	for rows.Next() {
+		var item StaleProductEmbedding
+		if err := rows.Scan(&item.OrganizationID, &item.ProductID); err != nil {
+			return nil, fmt.Errorf("scan stale product embedding: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetEmbeddingCoverage reports how many products of an organization have a vector
+// produced by the given model and version.
+func (r *Repo) GetEmbeddingCoverage(ctx context.Context, organizationID uuid.UUID, model, version string) (EmbeddingCoverage, error) {
+	coverage := EmbeddingCoverage{Versions: make([]EmbeddingVersionCount, 0)}
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)::int,
+			COUNT(*) FILTER (WHERE e.product_id IS NULL)::int,
+			COUNT(*) FILTER (WHERE e.embedding_model = $2 AND e.embedding_version = $3 AND e.indexed_at >= p.updated_at)::int,
+			COUNT(*) FILTER (WHERE e.embedding_model = $2 AND e.embedding_version = $3 AND e.indexed_at < p.updated_at)::int
+		FROM RAC_catalog_products p
+		LEFT JOIN RAC_catalog_product_embeddings e ON e.product_id = p.id
+		WHERE p.organization_id = $1`, organizationID, model, version,
+	).Scan(&coverage.TotalProducts, &coverage.Missing, &coverage.Current, &coverage.Outdated)
+	if err != nil {
+		return EmbeddingCoverage{}, fmt.Errorf("get embedding coverage: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT embedding_model, embedding_version, COUNT(*)::int, MAX(indexed_at)
+		FROM RAC_catalog_product_embeddings
+		WHERE organization_id = $1
+		GROUP BY embedding_model, embedding_version
+		ORDER BY COUNT(*) DESC`, organizationID,
+	)
+	if err != nil {
+		return EmbeddingCoverage{}, fmt.Errorf("list embedding versions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item EmbeddingVersionCount
+		if err := rows.Scan(&item.Model, &item.Version, &item.Products, &item.LastIndexedAt); err != nil {
+			return EmbeddingCoverage{}, fmt.Errorf("scan embedding version: %w", err)
+		}
+		coverage.Versions = append(coverage.Versions, item)
+	}
+	if err := rows.Err(); err != nil {
+		return EmbeddingCoverage{}, fmt.Errorf("list embedding versions: %w", err)
+	}
+
+	coverage.Drifted = coverage.TotalProducts - coverage.Missing - coverage.Current - coverage.Outdated
+	return coverage, nil
+}
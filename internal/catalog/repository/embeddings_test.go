@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// embeddingsSchema holds the columns the embedding queries read, so the test
+// runs against any Postgres instead of a fully migrated database.
+const embeddingsSchema = `
+CREATE TABLE RAC_catalog_products (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+CREATE TABLE RAC_catalog_product_embeddings (
+	product_id uuid PRIMARY KEY REFERENCES RAC_catalog_products(id) ON DELETE CASCADE,
+	organization_id uuid NOT NULL,
+	collection text NOT NULL,
+	embedding_model text NOT NULL,
+	embedding_version text NOT NULL,
+	indexed_at timestamptz NOT NULL DEFAULT now()
+);
+`
+
+// openEmbeddingsDB connects to TEST_DATABASE_URL with a scratch schema first
+// on the search path and drops the schema when the test ends.
+func openEmbeddingsDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+	schema := "catalog_embeddings_" + uuid.NewString()[:8]
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ", public"
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if _, err := pool.Exec(ctx, embeddingsSchema); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestListStaleProductEmbeddings(t *testing.T) {
+	pool := openEmbeddingsDB(t)
+	ctx := context.Background()
+	orgID := uuid.New()
+	now := time.Now()
+
+	products := []struct {
+		name      string
+		updatedAt time.Time
+		model     string
+		version   string
+		indexedAt time.Time
+	}{
+		{name: "current", updatedAt: now.Add(-2 * time.Hour), model: "bge-m3", version: "2", indexedAt: now.Add(-time.Hour)},
+		{name: "missing", updatedAt: now},
+		{name: "other model", updatedAt: now.Add(-2 * time.Hour), model: "e5", version: "2", indexedAt: now.Add(-90 * time.Minute)},
+		{name: "other version", updatedAt: now.Add(-2 * time.Hour), model: "bge-m3", version: "1", indexedAt: now.Add(-3 * time.Hour)},
+		{name: "updated", updatedAt: now, model: "bge-m3", version: "2", indexedAt: now.Add(-time.Hour)},
+	}
+	ids := make(map[uuid.UUID]string, len(products))
+	for _, p := range products {
+		id := uuid.New()
+		ids[id] = p.name
+		if _, err := pool.Exec(ctx, "INSERT INTO RAC_catalog_products (id, organization_id, updated_at) VALUES ($1, $2, $3)", id, orgID, p.updatedAt); err != nil {
+			t.Fatal(err)
+		}
+		if p.model == "" {
+			continue
+		}
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO RAC_catalog_product_embeddings (product_id, organization_id, collection, embedding_model, embedding_version, indexed_at)
+			VALUES ($1, $2, 'catalog', $3, $4, $5)`, id, orgID, p.model, p.version, p.indexedAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := New(pool)
+	stale, err := repo.ListStaleProductEmbeddings(ctx, "bge-m3", "2", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(stale))
+	for _, item := range stale {
+		got = append(got, ids[item.ProductID])
+	}
+	// Never-indexed products first, then by the age of their vector.
+	want := []string{"missing", "other version", "other model", "updated"}
+	if len(got) != len(want) {
+		t.Fatalf("stale products = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("stale products = %v, want %v", got, want)
+		}
+	}
+
+	limited, err := repo.ListStaleProductEmbeddings(ctx, "bge-m3", "2", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 1 || ids[limited[0].ProductID] != "missing" {
+		t.Errorf("limited to one, got %v", limited)
+	}
+
+	coverage, err := repo.GetEmbeddingCoverage(ctx, orgID, "bge-m3", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coverage.TotalProducts != 5 || coverage.Current != 1 || coverage.Outdated != 1 || coverage.Missing != 1 || coverage.Drifted != 2 {
+		t.Errorf("unexpected coverage %+v", coverage)
+	}
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MarkInheritedProductOverridden stops head-office catalog syncs from
+// overwriting a product a branch inherited. Products the organization created
+// itself are not affected.
+func (r *Repo) MarkInheritedProductOverridden(ctx context.Context, organizationID, productID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_catalog_inherited_products SET overridden_at = now()
+		WHERE organization_id = $1 AND product_id = $2 AND overridden_at IS NULL`,
+		organizationID, productID,
+	); err != nil {
+		return fmt.Errorf("mark inherited product overridden: %w", err)
+	}
+	return nil
+}
@@ -160,6 +160,177 @@ type ListProductsParams struct {
 	IsDraft        *bool
 }
 
+// RecordProductEmbeddingParams records the embedding model and version of an indexed product.
+type RecordProductEmbeddingParams struct {
+	OrganizationID uuid.UUID
+	ProductID      uuid.UUID
+	Collection     string
+	Model          string
+	Version        string
+}
+
+// StaleProductEmbedding identifies a product that needs to be re-embedded.
+type StaleProductEmbedding struct {
+	OrganizationID uuid.UUID
+	ProductID      uuid.UUID
+}
+
+// EmbeddingVersionCount counts the products indexed with one model version.
+type EmbeddingVersionCount struct {
+	LastIndexedAt time.Time
+	Model         string
+	Version       string
+	Products      int
+}
+
+// EmbeddingCoverage reports how much of a catalog is indexed with the current model.
+// Drifted products were indexed by another model or version; outdated products were
+// indexed by the current one but changed afterwards.
+type EmbeddingCoverage struct {
+	Versions      []EmbeddingVersionCount
+	TotalProducts int
+	Current       int
+	Outdated      int
+	Drifted       int
+	Missing       int
+}
+
+// PriceFeed is a supplier price feed. Feeds without a source URL are only
+// imported by upload.
+type PriceFeed struct {
+	LastRunAt          *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	ID                 uuid.UUID
+	OrganizationID     uuid.UUID
+	SupplierName       string
+	Format             string
+	SKUField           string
+	PriceField         string
+	CSVDelimiter       string
+	SourceURL          *string
+	APITokenEncrypted  *string
+	LastError          *string
+	FetchIntervalHours int
+	AlertThresholdBps  int
+	IsActive           bool
+}
+
+// PriceFeedProduct is a product matched by a feed row, with its last known cost.
+type PriceFeedProduct struct {
+	ID             uuid.UUID
+	Title          string
+	Reference      string
+	Type           string
+	CostCents      *int64
+	PriceCents     int64
+	UnitPriceCents int64
+}
+
+// PriceMarginRule is the margin applied on top of the cost price. An empty feed
+// or product type matches every feed or type.
+type PriceMarginRule struct {
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	FeedID         *uuid.UUID
+	ProductType    *string
+	MarginBps      int
+}
+
+// ApplyPriceChangeParams records a new supplier cost and, when it changed, the
+// derived sell price of a product.
+type ApplyPriceChangeParams struct {
+	OrganizationID   uuid.UUID
+	ProductID        uuid.UUID
+	FeedID           uuid.UUID
+	SupplierSKU      string
+	OldCostCents     *int64
+	ChangeBps        *int
+	NewCostCents     int64
+	OldPriceCents    int64
+	NewPriceCents    int64
+	UpdateUnitPrice  bool
+	ExceedsThreshold bool
+}
+
+// PriceChange is one entry of the price change history.
+type PriceChange struct {
+	CreatedAt        time.Time
+	ID               uuid.UUID
+	ProductID        uuid.UUID
+	FeedID           *uuid.UUID
+	ProductTitle     string
+	SupplierSKU      string
+	OldCostCents     *int64
+	ChangeBps        *int
+	NewCostCents     int64
+	OldPriceCents    int64
+	NewPriceCents    int64
+	ExceedsThreshold bool
+}
+
+// ListPriceChangesParams filters the price change history.
+type ListPriceChangesParams struct {
+	OrganizationID uuid.UUID
+	ProductID      *uuid.UUID
+	FeedID         *uuid.UUID
+	Limit          int
+}
+
+// ProductStock is the stock level of a tracked product.
+type ProductStock struct {
+	UpdatedAt         time.Time
+	ProductID         uuid.UUID
+	OrganizationID    uuid.UUID
+	Title             string
+	Reference         string
+	Quantity          float64
+	LowStockThreshold float64
+}
+
+// StockMovement is one change of a product's stock level.
+type StockMovement struct {
+	CreatedAt     time.Time
+	ID            uuid.UUID
+	ProductID     uuid.UUID
+	QuoteID       *uuid.UUID
+	CreatedBy     *uuid.UUID
+	Note          *string
+	Reason        string
+	QuantityDelta float64
+	QuantityAfter float64
+}
+
+// SetProductStockParams starts tracking a product or corrects its stock level.
+type SetProductStockParams struct {
+	OrganizationID    uuid.UUID
+	ProductID         uuid.UUID
+	CreatedBy         *uuid.UUID
+	Quantity          float64
+	LowStockThreshold float64
+}
+
+// AdjustProductStockParams adds a relative stock change, e.g. a delivery.
+type AdjustProductStockParams struct {
+	OrganizationID uuid.UUID
+	ProductID      uuid.UUID
+	CreatedBy      *uuid.UUID
+	Note           *string
+	Delta          float64
+}
+
+// StockConsumption is the stock change of one product caused by a quote.
+type StockConsumption struct {
+	ProductID         uuid.UUID
+	Title             string
+	Reference         string
+	QuantityBefore    float64
+	QuantityAfter     float64
+	LowStockThreshold float64
+}
+
 // Repository defines catalog storage operations.
 type Repository interface {
 	CreateVatRate(ctx context.Context, params CreateVatRateParams) (VatRate, error)
@@ -191,4 +362,37 @@ type Repository interface {
 	RemoveProductMaterials(ctx context.Context, organizationID, productID uuid.UUID, materialIDs []uuid.UUID) error
 	ListProductMaterials(ctx context.Context, organizationID, productID uuid.UUID) ([]Product, error)
 	HasProductMaterials(ctx context.Context, organizationID, productID uuid.UUID) (bool, error)
+
+	RecordProductEmbedding(ctx context.Context, params RecordProductEmbeddingParams) error
+	ListStaleProductEmbeddings(ctx context.Context, model, version string, limit int) ([]StaleProductEmbedding, error)
+	GetEmbeddingCoverage(ctx context.Context, organizationID uuid.UUID, model, version string) (EmbeddingCoverage, error)
+
+	CreatePriceFeed(ctx context.Context, feed PriceFeed) (PriceFeed, error)
+	UpdatePriceFeed(ctx context.Context, feed PriceFeed) (PriceFeed, error)
+	GetPriceFeed(ctx context.Context, organizationID, id uuid.UUID) (PriceFeed, error)
+	ListPriceFeeds(ctx context.Context, organizationID uuid.UUID) ([]PriceFeed, error)
+	ListDuePriceFeeds(ctx context.Context, now time.Time) ([]PriceFeed, error)
+	DeletePriceFeed(ctx context.Context, organizationID, id uuid.UUID) error
+	RecordPriceFeedRun(ctx context.Context, id uuid.UUID, ranAt time.Time, lastError *string) error
+	ListPriceFeedProducts(ctx context.Context, organizationID uuid.UUID, references []string) ([]PriceFeedProduct, error)
+	ApplyPriceChange(ctx context.Context, params ApplyPriceChangeParams) error
+	ListPriceChanges(ctx context.Context, params ListPriceChangesParams) ([]PriceChange, error)
+
+	ListPriceMarginRules(ctx context.Context, organizationID uuid.UUID) ([]PriceMarginRule, error)
+	UpsertPriceMarginRule(ctx context.Context, rule PriceMarginRule) (PriceMarginRule, error)
+	DeletePriceMarginRule(ctx context.Context, organizationID, id uuid.UUID) error
+
+	GetProductStock(ctx context.Context, organizationID, productID uuid.UUID) (ProductStock, error)
+	ListProductStock(ctx context.Context, organizationID uuid.UUID, productIDs []uuid.UUID) ([]ProductStock, error)
+	ListLowStockProducts(ctx context.Context, organizationID uuid.UUID) ([]ProductStock, error)
+	SetProductStock(ctx context.Context, params SetProductStockParams) (ProductStock, error)
+	AdjustProductStock(ctx context.Context, params AdjustProductStockParams) (ProductStock, error)
+	DeleteProductStock(ctx context.Context, organizationID, productID uuid.UUID) error
+	ListStockMovements(ctx context.Context, organizationID, productID uuid.UUID, limit int) ([]StockMovement, error)
+	GetStockConsumeOn(ctx context.Context, organizationID uuid.UUID) (string, error)
+	SetStockConsumeOn(ctx context.Context, organizationID uuid.UUID, consumeOn string) error
+	FindAcceptedQuoteForService(ctx context.Context, organizationID, leadServiceID uuid.UUID) (*uuid.UUID, error)
+	ConsumeQuoteStock(ctx context.Context, organizationID, quoteID uuid.UUID, reason string) ([]StockConsumption, error)
+
+	MarkInheritedProductOverridden(ctx context.Context, organizationID, productID uuid.UUID) error
 }
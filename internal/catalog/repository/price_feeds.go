@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"portal_final_backend/platform/apperr"
+)
+
+const (
+	errMsgPriceFeedNotFound   = "price feed not found"
+	errMsgMarginRuleNotFound  = "margin rule not found"
+	errMsgMarginRuleFeedScope = "margin rule feed not found"
+)
+
+const priceFeedColumns = `id, organization_id, supplier_name, format, source_url, api_token_encrypted,
+	sku_field, price_field, csv_delimiter, fetch_interval_hours, alert_threshold_bps, is_active,
+	last_run_at, last_error, created_at, updated_at`
+
+func scanPriceFeed(row pgx.Row) (PriceFeed, error) {
+	var feed PriceFeed
+	err := row.Scan(
+		&feed.ID, &feed.OrganizationID, &feed.SupplierName, &feed.Format, &feed.SourceURL, &feed.APITokenEncrypted,
+		&feed.SKUField, &feed.PriceField, &feed.CSVDelimiter, &feed.FetchIntervalHours, &feed.AlertThresholdBps, &feed.IsActive,
+		&feed.LastRunAt, &feed.LastError, &feed.CreatedAt, &feed.UpdatedAt,
+	)
+	return feed, err
+}
+
+func scanPriceFeeds(rows pgx.Rows) ([]PriceFeed, error) {
+	defer rows.Close()
+	feeds := make([]PriceFeed, 0)
+	for rows.Next() {
+		feed, err := scanPriceFeed(rows)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds, rows.Err()
+}
+
+// CreatePriceFeed stores a new supplier price feed.
+func (r *Repo) CreatePriceFeed(ctx context.Context, feed PriceFeed) (PriceFeed, error) {
+	created, err := scanPriceFeed(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_catalog_price_feeds (
+			organization_id, supplier_name, format, source_url, api_token_encrypted,
+			sku_field, price_field, csv_delimiter, fetch_interval_hours, alert_threshold_bps, is_active
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING `+priceFeedColumns,
+		feed.OrganizationID, feed.SupplierName, feed.Format, feed.SourceURL, feed.APITokenEncrypted,
+		feed.SKUField, feed.PriceField, feed.CSVDelimiter, feed.FetchIntervalHours, feed.AlertThresholdBps, feed.IsActive,
+	))
+	if err != nil {
+		return PriceFeed{}, fmt.Errorf("create price feed: %w", err)
+	}
+	return created, nil
+}
+
+// UpdatePriceFeed replaces the settings of a price feed.
+func (r *Repo) UpdatePriceFeed(ctx context.Context, feed PriceFeed) (PriceFeed, error) {
+	updated, err := scanPriceFeed(r.pool.QueryRow(ctx, `
+		UPDATE RAC_catalog_price_feeds
+		SET supplier_name = $3, format = $4, source_url = $5, api_token_encrypted = $6,
+			sku_field = $7, price_field = $8, csv_delimiter = $9, fetch_interval_hours = $10,
+			alert_threshold_bps = $11, is_active = $12, updated_at = now()
+		WHERE id = $1 AND organization_id = $2
+		RETURNING `+priceFeedColumns,
+		feed.ID, feed.OrganizationID, feed.SupplierName, feed.Format, feed.SourceURL, feed.APITokenEncrypted,
+		feed.SKUField, feed.PriceField, feed.CSVDelimiter, feed.FetchIntervalHours, feed.AlertThresholdBps, feed.IsActive,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PriceFeed{}, apperr.NotFound(errMsgPriceFeedNotFound)
+	}
+	if err != nil {
+		return PriceFeed{}, fmt.Errorf("update price feed: %w", err)
+	}
+	return updated, nil
+}
+
+// GetPriceFeed returns a price feed of an organization.
+func (r *Repo) GetPriceFeed(ctx context.Context, organizationID, id uuid.UUID) (PriceFeed, error) {
+	feed, err := scanPriceFeed(r.pool.QueryRow(ctx, `SELECT `+priceFeedColumns+`
+		FROM RAC_catalog_price_feeds
+		WHERE id = $1 AND organization_id = $2`, id, organizationID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PriceFeed{}, apperr.NotFound(errMsgPriceFeedNotFound)
+	}
+	if err != nil {
+		return PriceFeed{}, fmt.Errorf("get price feed: %w", err)
+	}
+	return feed, nil
+}
+
+// ListPriceFeeds returns the price feeds of an organization by supplier.
+func (r *Repo) ListPriceFeeds(ctx context.Context, organizationID uuid.UUID) ([]PriceFeed, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+priceFeedColumns+`
+		FROM RAC_catalog_price_feeds
+		WHERE organization_id = $1
+		ORDER BY supplier_name, created_at`, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list price feeds: %w", err)
+	}
+	return scanPriceFeeds(rows)
+}
+
+// ListDuePriceFeeds returns the active feeds with a source URL whose fetch
+// interval has passed, least recently run first.
+func (r *Repo) ListDuePriceFeeds(ctx context.Context, now time.Time) ([]PriceFeed, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+priceFeedColumns+`
+		FROM RAC_catalog_price_feeds
+		WHERE is_active AND source_url IS NOT NULL
+			AND (last_run_at IS NULL OR last_run_at + make_interval(hours => fetch_interval_hours) <= $1)
+		ORDER BY last_run_at NULLS FIRST`, now)
+	if err != nil {
+		return nil, fmt.Errorf("list due price feeds: %w", err)
+	}
+	return scanPriceFeeds(rows)
+}
+
+// DeletePriceFeed removes a price feed. Imported costs and history are kept.
+func (r *Repo) DeletePriceFeed(ctx context.Context, organizationID, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM RAC_catalog_price_feeds WHERE id = $1 AND organization_id = $2`, id, organizationID)
+	if err != nil {
+		return fmt.Errorf("delete price feed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound(errMsgPriceFeedNotFound)
+	}
+	return nil
+}
+
+// RecordPriceFeedRun stores when a feed ran and the error that stopped it, if any.
+func (r *Repo) RecordPriceFeedRun(ctx context.Context, id uuid.UUID, ranAt time.Time, lastError *string) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_catalog_price_feeds SET last_run_at = $2, last_error = $3 WHERE id = $1`,
+		id, ranAt, lastError,
+	); err != nil {
+		return fmt.Errorf("record price feed run: %w", err)
+	}
+	return nil
+}
+
+// ListPriceFeedProducts returns the products whose reference is one of references,
+// with their last imported cost.
+func (r *Repo) ListPriceFeedProducts(ctx context.Context, organizationID uuid.UUID, references []string) ([]PriceFeedProduct, error) {
+	if len(references) == 0 {
+		return []PriceFeedProduct{}, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT p.id, p.title, p.reference, p.type, c.cost_cents, p.price_cents, p.unit_price_cents
+		FROM RAC_catalog_products p
+		LEFT JOIN RAC_catalog_product_costs c ON c.product_id = p.id
+		WHERE p.organization_id = $1 AND p.reference = ANY($2::text[])`,
+		organizationID, references,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list price feed products: %w", err)
+	}
+	defer rows.Close()
+
+	products := make([]PriceFeedProduct, 0, len(references))
+	for rows.Next() {
+		var product PriceFeedProduct
+		if err := rows.Scan(&product.ID, &product.Title, &product.Reference, &product.Type, &product.CostCents,
+			&product.PriceCents, &product.UnitPriceCents); err != nil {
+			return nil, fmt.Errorf("scan price feed product: %w", err)
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+// ApplyPriceChange stores the new cost of a product, updates its sell price and
+// appends the change to the price history in one transaction.
+func (r *Repo) ApplyPriceChange(ctx context.Context, params ApplyPriceChangeParams) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin apply price change tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_catalog_product_costs (product_id, organization_id, feed_id, supplier_sku, cost_cents, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+		ON CONFLICT (product_id) DO UPDATE SET
+			feed_id = EXCLUDED.feed_id,
+			supplier_sku = EXCLUDED.supplier_sku,
+			cost_cents = EXCLUDED.cost_cents,
+			updated_at = now()`,
+		params.ProductID, params.OrganizationID, params.FeedID, params.SupplierSKU, params.NewCostCents,
+	); err != nil {
+		return fmt.Errorf("store product cost: %w", err)
+	}
+
+	if params.NewPriceCents != params.OldPriceCents {
+		column := "price_cents"
+		if params.UpdateUnitPrice {
+			column = "unit_price_cents"
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE RAC_catalog_products SET `+column+` = $3, updated_at = now()
+			WHERE id = $1 AND organization_id = $2`,
+			params.ProductID, params.OrganizationID, params.NewPriceCents,
+		); err != nil {
+			return fmt.Errorf("update product price: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_catalog_price_changes (
+			organization_id, product_id, feed_id, supplier_sku, old_cost_cents, new_cost_cents,
+			old_price_cents, new_price_cents, change_bps, exceeds_threshold
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		params.OrganizationID, params.ProductID, params.FeedID, params.SupplierSKU, params.OldCostCents, params.NewCostCents,
+		params.OldPriceCents, params.NewPriceCents, params.ChangeBps, params.ExceedsThreshold,
+	); err != nil {
+		return fmt.Errorf("record price change: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit apply price change tx: %w", err)
+	}
+	return nil
+}
+
+// ListPriceChanges returns the most recent price changes, optionally of one product or feed.
+func (r *Repo) ListPriceChanges(ctx context.Context, params ListPriceChangesParams) ([]PriceChange, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT ch.id, ch.product_id, ch.feed_id, p.title, ch.supplier_sku, ch.old_cost_cents, ch.change_bps,
+			ch.new_cost_cents, ch.old_price_cents, ch.new_price_cents, ch.exceeds_threshold, ch.created_at
+		FROM RAC_catalog_price_changes ch
+		JOIN RAC_catalog_products p ON p.id = ch.product_id
+		WHERE ch.organization_id = $1
+			AND ($2::uuid IS NULL OR ch.product_id = $2)
+			AND ($3::uuid IS NULL OR ch.feed_id = $3)
+		ORDER BY ch.created_at DESC
+		LIMIT $4`,
+		params.OrganizationID, params.ProductID, params.FeedID, params.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list price changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]PriceChange, 0)
+	for rows.Next() {
+		var change PriceChange
+		if err := rows.Scan(&change.ID, &change.ProductID, &change.FeedID, &change.ProductTitle, &change.SupplierSKU,
+			&change.OldCostCents, &change.ChangeBps, &change.NewCostCents, &change.OldPriceCents, &change.NewPriceCents,
+			&change.ExceedsThreshold, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan price change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// ListPriceMarginRules returns the margin rules of an organization.
+func (r *Repo) ListPriceMarginRules(ctx context.Context, organizationID uuid.UUID) ([]PriceMarginRule, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, organization_id, feed_id, product_type, margin_bps, created_at, updated_at
+		FROM RAC_catalog_price_margin_rules
+		WHERE organization_id = $1
+		ORDER BY feed_id NULLS FIRST, product_type NULLS FIRST`, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list margin rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]PriceMarginRule, 0)
+	for rows.Next() {
+		var rule PriceMarginRule
+		if err := rows.Scan(&rule.ID, &rule.OrganizationID, &rule.FeedID, &rule.ProductType, &rule.MarginBps,
+			&rule.CreatedAt, &rule.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan margin rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// UpsertPriceMarginRule creates the rule of a feed and product type scope or
+// replaces its margin.
+func (r *Repo) UpsertPriceMarginRule(ctx context.Context, rule PriceMarginRule) (PriceMarginRule, error) {
+	var saved PriceMarginRule
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_catalog_price_margin_rules (organization_id, feed_id, product_type, margin_bps)
+		SELECT $1, $2, $3, $4
+		WHERE $2::uuid IS NULL
+			OR EXISTS (SELECT 1 FROM RAC_catalog_price_feeds WHERE id = $2 AND organization_id = $1)
+		ON CONFLICT (organization_id, COALESCE(feed_id, '00000000-0000-0000-0000-000000000000'::uuid), COALESCE(product_type, ''))
+		DO UPDATE SET margin_bps = EXCLUDED.margin_bps, updated_at = now()
+		RETURNING id, organization_id, feed_id, product_type, margin_bps, created_at, updated_at`,
+		rule.OrganizationID, rule.FeedID, rule.ProductType, rule.MarginBps,
+	).Scan(&saved.ID, &saved.OrganizationID, &saved.FeedID, &saved.ProductType, &saved.MarginBps,
+		&saved.CreatedAt, &saved.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PriceMarginRule{}, apperr.NotFound(errMsgMarginRuleFeedScope)
+	}
+	if err != nil {
+		return PriceMarginRule{}, fmt.Errorf("upsert margin rule: %w", err)
+	}
+	return saved, nil
+}
+
+// DeletePriceMarginRule removes a margin rule.
+func (r *Repo) DeletePriceMarginRule(ctx context.Context, organizationID, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM RAC_catalog_price_margin_rules WHERE id = $1 AND organization_id = $2`, id, organizationID)
+	if err != nil {
+		return fmt.Errorf("delete margin rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound(errMsgMarginRuleNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"portal_final_backend/platform/apperr"
+)
+
+// Stock movement reasons. The consumption reasons double as the values of the
+// organization's consume-on setting.
+const (
+	StockReasonAdjustment           = "adjustment"
+	StockReasonQuoteAccepted        = "quote_accepted"
+	StockReasonPartnerOfferAccepted = "partner_offer_accepted"
+)
+
+const errMsgProductStockNotFound = "product stock is not tracked"
+
+const productStockColumns = `s.product_id, s.organization_id, p.title, p.reference, s.quantity, s.low_stock_threshold, s.updated_at`
+
+func scanProductStock(row pgx.Row) (ProductStock, error) {
+	var stock ProductStock
+	err := row.Scan(&stock.ProductID, &stock.OrganizationID, &stock.Title, &stock.Reference,
+		&stock.Quantity, &stock.LowStockThreshold, &stock.UpdatedAt)
+	return stock, err
+}
+
+func scanProductStocks(rows pgx.Rows) ([]ProductStock, error) {
+	defer rows.Close()
+	stocks := make([]ProductStock, 0)
+	for rows.Next() {
+		stock, err := scanProductStock(rows)
+		if err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, stock)
+	}
+	return stocks, rows.Err()
+}
+
+// GetProductStock returns the stock level of a tracked product.
+func (r *Repo) GetProductStock(ctx context.Context, organizationID, productID uuid.UUID) (ProductStock, error) {
+	stock, err := scanProductStock(r.pool.QueryRow(ctx, `
+		SELECT `+productStockColumns+`
+		FROM RAC_catalog_product_stock s
+		JOIN RAC_catalog_products p ON p.id = s.product_id
+		WHERE s.organization_id = $1 AND s.product_id = $2`,
+		organizationID, productID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ProductStock{}, apperr.NotFound(errMsgProductStockNotFound)
+	}
+	if err != nil {
+		return ProductStock{}, fmt.Errorf("get product stock: %w", err)
+	}
+	return stock, nil
+}
+
+// ListProductStock returns the stock levels of the tracked products among productIDs.
+func (r *Repo) ListProductStock(ctx context.Context, organizationID uuid.UUID, productIDs []uuid.UUID) ([]ProductStock, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+productStockColumns+`
+		FROM RAC_catalog_product_stock s
+		JOIN RAC_catalog_products p ON p.id = s.product_id
+		WHERE s.organization_id = $1 AND s.product_id = ANY($2::uuid[])`,
+		organizationID, productIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list product stock: %w", err)
+	}
+	stocks, err := scanProductStocks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("scan product stock: %w", err)
+	}
+	return stocks, nil
+}
+
+// ListLowStockProducts returns the tracked products at or below their low-stock threshold.
+func (r *Repo) ListLowStockProducts(ctx context.Context, organizationID uuid.UUID) ([]ProductStock, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+productStockColumns+`
+		FROM RAC_catalog_product_stock s
+		JOIN RAC_catalog_products p ON p.id = s.product_id
+		WHERE s.organization_id = $1 AND s.quantity <= s.low_stock_threshold
+		ORDER BY s.quantity - s.low_stock_threshold, p.title`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list low stock products: %w", err)
+	}
+	stocks, err := scanProductStocks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("scan low stock products: %w", err)
+	}
+	return stocks, nil
+}
+
+// SetProductStock starts tracking a product or overwrites its stock level. A
+// changed quantity is recorded as an adjustment.
+func (r *Repo) SetProductStock(ctx context.Context, params SetProductStockParams) (ProductStock, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return ProductStock{}, fmt.Errorf("begin set product stock tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM RAC_catalog_products WHERE id = $1 AND organization_id = $2)`,
+		params.ProductID, params.OrganizationID,
+	).Scan(&exists); err != nil {
+		return ProductStock{}, fmt.Errorf("check product: %w", err)
+	}
+	if !exists {
+		return ProductStock{}, apperr.NotFound(errMsgProductNotFound)
+	}
+
+	var previous float64
+	err = tx.QueryRow(ctx, `
+		SELECT quantity FROM RAC_catalog_product_stock WHERE product_id = $1 FOR UPDATE`,
+		params.ProductID,
+	).Scan(&previous)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return ProductStock{}, fmt.Errorf("lock product stock: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_catalog_product_stock (product_id, organization_id, quantity, low_stock_threshold, updated_at)
+		VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT (product_id) DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			low_stock_threshold = EXCLUDED.low_stock_threshold,
+			updated_at = now()`,
+		params.ProductID, params.OrganizationID, params.Quantity, params.LowStockThreshold,
+	); err != nil {
+		return ProductStock{}, fmt.Errorf("store product stock: %w", err)
+	}
+
+	if delta := params.Quantity - previous; delta != 0 {
+		if err := insertStockMovement(ctx, tx, params.OrganizationID, params.ProductID, delta, params.Quantity,
+			StockReasonAdjustment, nil, nil, params.CreatedBy); err != nil {
+			return ProductStock{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ProductStock{}, fmt.Errorf("commit set product stock tx: %w", err)
+	}
+	return r.GetProductStock(ctx, params.OrganizationID, params.ProductID)
+}
+
+// AdjustProductStock adds delta to the stock of a tracked product.
+func (r *Repo) AdjustProductStock(ctx context.Context, params AdjustProductStockParams) (ProductStock, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return ProductStock{}, fmt.Errorf("begin adjust product stock tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var quantity float64
+	err = tx.QueryRow(ctx, `
+		UPDATE RAC_catalog_product_stock
+		SET quantity = quantity + $3, updated_at = now()
+		WHERE product_id = $1 AND organization_id = $2
+		RETURNING quantity`,
+		params.ProductID, params.OrganizationID, params.Delta,
+	).Scan(&quantity)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ProductStock{}, apperr.NotFound(errMsgProductStockNotFound)
+	}
+	if err != nil {
+		return ProductStock{}, fmt.Errorf("adjust product stock: %w", err)
+	}
+
+	if err := insertStockMovement(ctx, tx, params.OrganizationID, params.ProductID, params.Delta, quantity,
+		StockReasonAdjustment, nil, params.Note, params.CreatedBy); err != nil {
+		return ProductStock{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ProductStock{}, fmt.Errorf("commit adjust product stock tx: %w", err)
+	}
+	return r.GetProductStock(ctx, params.OrganizationID, params.ProductID)
+}
+
+// DeleteProductStock stops tracking the stock of a product. Its movements are kept.
+func (r *Repo) DeleteProductStock(ctx context.Context, organizationID, productID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_catalog_product_stock WHERE product_id = $1 AND organization_id = $2`,
+		productID, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete product stock: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound(errMsgProductStockNotFound)
+	}
+	return nil
+}
+
+// ListStockMovements returns the most recent stock movements of a product.
+func (r *Repo) ListStockMovements(ctx context.Context, organizationID, productID uuid.UUID, limit int) ([]StockMovement, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, product_id, quote_id, created_by, note, reason, quantity_delta, quantity_after, created_at
+		FROM RAC_catalog_stock_movements
+		WHERE organization_id = $1 AND product_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3`,
+		organizationID, productID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	movements := make([]StockMovement, 0)
+	for rows.Next() {
+		var m StockMovement
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.QuoteID, &m.CreatedBy, &m.Note, &m.Reason,
+			&m.QuantityDelta, &m.QuantityAfter, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan stock movement: %w", err)
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// GetStockConsumeOn returns which event consumes stock for an organization.
+func (r *Repo) GetStockConsumeOn(ctx context.Context, organizationID uuid.UUID) (string, error) {
+	var consumeOn string
+	err := r.pool.QueryRow(ctx, `
+		SELECT consume_on FROM RAC_catalog_stock_settings WHERE organization_id = $1`,
+		organizationID,
+	).Scan(&consumeOn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return StockReasonQuoteAccepted, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get stock settings: %w", err)
+	}
+	return consumeOn, nil
+}
+
+// SetStockConsumeOn stores which event consumes stock for an organization.
+func (r *Repo) SetStockConsumeOn(ctx context.Context, organizationID uuid.UUID, consumeOn string) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_catalog_stock_settings (organization_id, consume_on, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (organization_id) DO UPDATE SET consume_on = EXCLUDED.consume_on, updated_at = now()`,
+		organizationID, consumeOn,
+	); err != nil {
+		return fmt.Errorf("store stock settings: %w", err)
+	}
+	return nil
+}
+
+// FindAcceptedQuoteForService returns the most recently accepted quote of a lead service.
+func (r *Repo) FindAcceptedQuoteForService(ctx context.Context, organizationID, leadServiceID uuid.UUID) (*uuid.UUID, error) {
+	var quoteID uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT id FROM RAC_quotes
+		WHERE organization_id = $1 AND lead_service_id = $2 AND status = 'Accepted'
+		ORDER BY accepted_at DESC NULLS LAST, created_at DESC
+		LIMIT 1`,
+		organizationID, leadServiceID,
+	).Scan(&quoteID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find accepted quote: %w", err)
+	}
+	return &quoteID, nil
+}
+
+// ConsumeQuoteStock subtracts the quantities of a quote's catalog lines from the
+// tracked products. Optional lines count only when selected. Products already
+// consumed by the same quote are skipped, so replaying an event is harmless.
+func (r *Repo) ConsumeQuoteStock(ctx context.Context, organizationID, quoteID uuid.UUID, reason string) ([]StockConsumption, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin consume quote stock tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		WITH lines AS (
+			SELECT qi.catalog_product_id AS product_id, SUM(qi.quantity_numeric) AS quantity
+			FROM RAC_quote_items qi
+			JOIN RAC_quotes q ON q.id = qi.quote_id
+			WHERE q.id = $2 AND q.organization_id = $1
+				AND qi.catalog_product_id IS NOT NULL
+				AND (qi.is_optional = false OR qi.is_selected = true)
+			GROUP BY qi.catalog_product_id
+		)
+		SELECT s.product_id, p.title, p.reference, s.quantity, s.low_stock_threshold, lines.quantity
+		FROM lines
+		JOIN RAC_catalog_product_stock s ON s.product_id = lines.product_id AND s.organization_id = $1
+		JOIN RAC_catalog_products p ON p.id = s.product_id
+		ORDER BY s.product_id
+		FOR UPDATE OF s`,
+		organizationID, quoteID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list quote stock lines: %w", err)
+	}
+	type stockLine struct {
+		consumption StockConsumption
+		quantity    float64
+	}
+	lines := make([]stockLine, 0)
+	for rows.Next() {
+		var line stockLine
+		c := &line.consumption
+		if err := rows.Scan(&c.ProductID, &c.Title, &c.Reference, &c.QuantityBefore, &c.LowStockThreshold, &line.quantity); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan quote stock line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list quote stock lines: %w", err)
+	}
+
+	consumed := make([]StockConsumption, 0, len(lines))
+	for _, line := range lines {
+		c := line.consumption
+		c.QuantityAfter = c.QuantityBefore - line.quantity
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO RAC_catalog_stock_movements (
+				organization_id, product_id, quantity_delta, quantity_after, reason, quote_id
+			)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (product_id, quote_id) WHERE quote_id IS NOT NULL DO NOTHING`,
+			organizationID, c.ProductID, -line.quantity, c.QuantityAfter, reason, quoteID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("record stock consumption: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE RAC_catalog_product_stock SET quantity = $2, updated_at = now() WHERE product_id = $1`,
+			c.ProductID, c.QuantityAfter,
+		); err != nil {
+			return nil, fmt.Errorf("consume product stock: %w", err)
+		}
+		consumed = append(consumed, c)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit consume quote stock tx: %w", err)
+	}
+	return consumed, nil
+}
+
+func insertStockMovement(ctx context.Context, tx pgx.Tx, organizationID, productID uuid.UUID, delta, after float64,
+	reason string, quoteID *uuid.UUID, note *string, createdBy *uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_catalog_stock_movements (
+			organization_id, product_id, quantity_delta, quantity_after, reason, quote_id, note, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		organizationID, productID, delta, after, reason, quoteID, note, createdBy,
+	); err != nil {
+		return fmt.Errorf("record stock movement: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"portal_final_backend/internal/catalog/repository"
+	"portal_final_backend/internal/catalog/transport"
+	"portal_final_backend/platform/ai/embeddingapi"
+	"portal_final_backend/platform/ai/llmrouter"
+	"portal_final_backend/platform/qdrant"
+
+	"github.com/google/uuid"
+)
+
+const defaultReindexBatchSize = 100
+
+// ReindexResult summarizes a single re-index run.
+type ReindexResult struct {
+	Checked   int
+	Reindexed int
+	Failed    int
+}
+
+// indexProduct embeds a product into its tenant collection and records the model version
+// that produced the vector.
+func (s *Service) indexProduct(ctx context.Context, tenantID uuid.UUID, product repository.Product, reason string) error {
+	collection := qdrant.TenantCollectionName(s.embeddingCollection, tenantID.String(), s.vectorTenancyMode)
+	request := embeddingapi.AddDocumentsRequest{
+		Documents:  []map[string]any{s.buildCatalogDocument(tenantID, product)},
+		TextFields: []string{"name", "description", "reference", "type", "labor_time_text", "unit_label"},
+		IDField:    "id",
+		Collection: collection,
+	}
+
+	ctx = llmrouter.WithSource(llmrouter.WithOrganizationID(ctx, tenantID), "catalog-indexing")
+	resp, err := s.embeddingClient.AddDocuments(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.RecordProductEmbedding(ctx, repository.RecordProductEmbeddingParams{
+		OrganizationID: tenantID,
+		ProductID:      product.ID,
+		Collection:     collection,
+		Model:          s.embeddingClient.Model(),
+		Version:        s.embeddingVersion,
+	}); err != nil {
+		return fmt.Errorf("product indexed but version not recorded: %w", err)
+	}
+
+	s.log.Info("catalog indexed", "productId", product.ID, "documentsAdded", resp.DocumentsAdded, "reason", reason)
+	return nil
+}
+
+// ReindexStaleEmbeddings re-embeds up to batchSize products whose vector is missing,
+// outdated or produced by another embedding model or version. The delay between
+// products rate-limits calls to the embedding API.
+func (s *Service) ReindexStaleEmbeddings(ctx context.Context, batchSize int, delay time.Duration) (ReindexResult, error) {
+	if s.embeddingClient == nil {
+		return ReindexResult{}, nil
+	}
+	if batchSize <= 0 {
+		batchSize = defaultReindexBatchSize
+	}
+
+	stale, err := s.repo.ListStaleProductEmbeddings(ctx, s.embeddingClient.Model(), s.embeddingVersion, batchSize)
+	if err != nil {
+		return ReindexResult{}, err
+	}
+
+	var result ReindexResult
+	for i, item := range stale {
+		if i > 0 && delay > 0 {
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		result.Checked++
+		product, err := s.repo.GetProductByID(ctx, item.OrganizationID, item.ProductID)
+		if err != nil {
+			result.Failed++
+			s.log.Warn("catalog reindex: product lookup failed", "productId", item.ProductID, "error", err)
+			continue
+		}
+
+		indexCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err = s.indexProduct(indexCtx, item.OrganizationID, product, "reindex")
+		cancel()
+		if err != nil {
+			result.Failed++
+			s.log.Warn("catalog reindex: indexing failed", "productId", item.ProductID, "error", err)
+			continue
+		}
+		result.Reindexed++
+	}
+	return result, nil
+}
+
+// GetEmbeddingCoverage reports how much of the tenant catalog is indexed with the
+// configured embedding model and version.
+func (s *Service) GetEmbeddingCoverage(ctx context.Context, tenantID uuid.UUID) (transport.EmbeddingCoverageResponse, error) {
+	model := ""
+	if s.embeddingClient != nil {
+		model = s.embeddingClient.Model()
+	}
+
+	coverage, err := s.repo.GetEmbeddingCoverage(ctx, tenantID, model, s.embeddingVersion)
+	if err != nil {
+		return transport.EmbeddingCoverageResponse{}, err
+	}
+
+	resp := transport.EmbeddingCoverageResponse{
+		Enabled:       s.embeddingClient != nil,
+		Model:         model,
+		Version:       s.embeddingVersion,
+		Collection:    qdrant.TenantCollectionName(s.embeddingCollection, tenantID.String(), s.vectorTenancyMode),
+		TotalProducts: coverage.TotalProducts,
+		Current:       coverage.Current,
+		Outdated:      coverage.Outdated,
+		Drifted:       coverage.Drifted,
+		Missing:       coverage.Missing,
+		Versions:      make([]transport.EmbeddingVersionResponse, 0, len(coverage.Versions)),
+	}
+	if coverage.TotalProducts > 0 {
+		resp.CoveragePercent = float64(coverage.Current) * 100 / float64(coverage.TotalProducts)
+	}
+	for _, v := range coverage.Versions {
+		resp.Versions = append(resp.Versions, transport.EmbeddingVersionResponse{
+			Model:         v.Model,
+			Version:       v.Version,
+			Products:      v.Products,
+			LastIndexedAt: v.LastIndexedAt.Format(time.RFC3339),
+			IsCurrent:     v.Model == model && v.Version == s.embeddingVersion,
+		})
+	}
+	return resp, nil
+}
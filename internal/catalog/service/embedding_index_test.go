@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"portal_final_backend/internal/catalog/repository"
+	"portal_final_backend/platform/ai/embeddingapi"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+// embeddingRepo serves the stale products of a re-index run and records the
+// versions written back.
+type embeddingRepo struct {
+	repository.Repository
+	stale    []repository.StaleProductEmbedding
+	products map[uuid.UUID]repository.Product
+	recorded []repository.RecordProductEmbeddingParams
+	model    string
+	version  string
+}
+
+func (r *embeddingRepo) ListStaleProductEmbeddings(_ context.Context, model, version string, limit int) ([]repository.StaleProductEmbedding, error) {
+	r.model, r.version = model, version
+	return r.stale[:min(limit, len(r.stale))], nil
+}
+
+func (r *embeddingRepo) GetProductByID(_ context.Context, organizationID, id uuid.UUID) (repository.Product, error) {
+	product, ok := r.products[id]
+	if !ok || product.OrganizationID != organizationID {
+		return repository.Product{}, apperr.NotFound("product not found")
+	}
+	return product, nil
+}
+
+func (r *embeddingRepo) RecordProductEmbedding(_ context.Context, params repository.RecordProductEmbeddingParams) error {
+	r.recorded = append(r.recorded, params)
+	return nil
+}
+
+func newEmbeddingServer(t *testing.T, documents *[]map[string]any) *embeddingapi.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req embeddingapi.AddDocumentsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*documents = append(*documents, req.Documents...)
+		_ = json.NewEncoder(w).Encode(embeddingapi.AddDocumentsResponse{DocumentsAdded: len(req.Documents)})
+	}))
+	t.Cleanup(server.Close)
+	return embeddingapi.NewClient(embeddingapi.Config{BaseURL: server.URL, Model: "bge-m3"})
+}
+
+func TestReindexStaleEmbeddingsRecordsModelAndVersion(t *testing.T) {
+	orgID := uuid.New()
+	indexed, deleted := uuid.New(), uuid.New()
+	repo := &embeddingRepo{
+		stale: []repository.StaleProductEmbedding{
+			{OrganizationID: orgID, ProductID: indexed},
+			{OrganizationID: orgID, ProductID: deleted},
+		},
+		products: map[uuid.UUID]repository.Product{
+			indexed: {ID: indexed, OrganizationID: orgID, Title: "Dakpan"},
+		},
+	}
+	var documents []map[string]any
+	svc := New(Config{
+		Repository:          repo,
+		Logger:              logger.New("development"),
+		EmbeddingClient:     newEmbeddingServer(t, &documents),
+		EmbeddingCollection: "catalog",
+		EmbeddingVersion:    "2",
+	})
+
+	result, err := svc.ReindexStaleEmbeddings(context.Background(), 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (ReindexResult{Checked: 2, Reindexed: 1, Failed: 1}) {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if repo.model != "bge-m3" || repo.version != "2" {
+		t.Errorf("stale products selected for %s/%s, want bge-m3/2", repo.model, repo.version)
+	}
+	if len(documents) != 1 || documents[0]["id"] != indexed.String() || documents[0]["embedding_version"] != "2" {
+		t.Fatalf("unexpected documents sent: %+v", documents)
+	}
+	if len(repo.recorded) != 1 {
+		t.Fatalf("expected one recorded embedding, got %+v", repo.recorded)
+	}
+	if got := repo.recorded[0]; got.ProductID != indexed || got.Model != "bge-m3" || got.Version != "2" {
+		t.Errorf("unexpected recorded embedding %+v", got)
+	}
+}
+
+func TestReindexStaleEmbeddingsWithoutEmbeddingClient(t *testing.T) {
+	repo := &embeddingRepo{stale: []repository.StaleProductEmbedding{{OrganizationID: uuid.New(), ProductID: uuid.New()}}}
+	svc := New(Config{Repository: repo, Logger: logger.New("development")})
+
+	result, err := svc.ReindexStaleEmbeddings(context.Background(), 10, 0)
+	if err != nil || result != (ReindexResult{}) {
+		t.Fatalf("expected a no-op without embedding client, got %+v (%v)", result, err)
+	}
+}
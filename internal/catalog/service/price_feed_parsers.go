@@ -0,0 +1,350 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"portal_final_backend/internal/catalog/repository"
+)
+
+const (
+	priceFeedFormatCSV = "csv"
+	priceFeedFormatEDI = "edi"
+	priceFeedFormatAPI = "api"
+)
+
+// priceFeedItem is one supplier row: the SKU matched against product references
+// and the cost price.
+type priceFeedItem struct {
+	SKU       string
+	CostCents int64
+}
+
+// parsePriceFeed decodes a feed body into items. Rows without a SKU or with an
+// unreadable price are skipped and counted as invalid; a later row for the same
+// SKU replaces an earlier one.
+func parsePriceFeed(feed repository.PriceFeed, body []byte) ([]priceFeedItem, int, error) {
+	var (
+		items   []priceFeedItem
+		invalid int
+		err     error
+	)
+	switch feed.Format {
+	case priceFeedFormatCSV:
+		items, invalid, err = parseCSVPriceFeed(body, feed.CSVDelimiter, feed.SKUField, feed.PriceField)
+	case priceFeedFormatEDI:
+		items, invalid, err = parseEDIPriceFeed(body)
+	case priceFeedFormatAPI:
+		items, invalid, err = parseAPIPriceFeed(body, feed.SKUField, feed.PriceField)
+	default:
+		return nil, 0, fmt.Errorf("unsupported price feed format %q", feed.Format)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return dedupePriceFeedItems(items), invalid, nil
+}
+
+func parseCSVPriceFeed(body []byte, delimiter, skuField, priceField string) ([]priceFeedItem, int, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))))
+	if comma, _ := utf8.DecodeRuneInString(delimiter); comma != utf8.RuneError {
+		reader.Comma = comma
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("read csv header: %w", err)
+	}
+	skuCol, priceCol := -1, -1
+	for i, name := range header {
+		switch {
+		case strings.EqualFold(strings.TrimSpace(name), skuField):
+			skuCol = i
+		case strings.EqualFold(strings.TrimSpace(name), priceField):
+			priceCol = i
+		}
+	}
+	if skuCol < 0 || priceCol < 0 {
+		return nil, 0, fmt.Errorf("csv header must contain the columns %q and %q", skuField, priceField)
+	}
+
+	items := make([]priceFeedItem, 0)
+	invalid := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("read csv row: %w", err)
+		}
+		if skuCol >= len(record) || priceCol >= len(record) {
+			invalid++
+			continue
+		}
+		item, ok := newPriceFeedItem(record[skuCol], record[priceCol])
+		if !ok {
+			invalid++
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, invalid, nil
+}
+
+// parseEDIPriceFeed reads an EDIFACT PRICAT message. Each LIN segment starts an
+// item; the supplier article number (PIA ...:SA) is preferred over the LIN item
+// number, and the net price (PRI+AAA) over other price qualifiers.
+func parseEDIPriceFeed(body []byte) ([]priceFeedItem, int, error) {
+	text := strings.TrimSpace(string(body))
+	component, element, release, terminator := ':', '+', '?', '\''
+	if strings.HasPrefix(text, "UNA") && len(text) >= 9 {
+		component, element, release, terminator = rune(text[3]), rune(text[4]), rune(text[6]), rune(text[8])
+		text = text[9:]
+	}
+
+	type ediItem struct {
+		lineSKU, supplierSKU, price, netPrice string
+	}
+	var (
+		current *ediItem
+		parsed  []ediItem
+	)
+	for _, segment := range splitEDI(text, terminator, release) {
+		elements := splitEDI(strings.TrimSpace(segment), element, release)
+		if len(elements) == 0 {
+			continue
+		}
+		switch elements[0] {
+		case "LIN":
+			if current != nil {
+				parsed = append(parsed, *current)
+			}
+			current = &ediItem{}
+			if len(elements) > 3 {
+				current.lineSKU = splitEDI(elements[3], component, release)[0]
+			}
+		case "PIA":
+			if current == nil {
+				continue
+			}
+			for _, code := range elements[2:] {
+				parts := splitEDI(code, component, release)
+				if len(parts) > 1 && parts[1] == "SA" {
+					current.supplierSKU = parts[0]
+				}
+			}
+		case "PRI":
+			if current == nil || len(elements) < 2 {
+				continue
+			}
+			parts := splitEDI(elements[1], component, release)
+			if len(parts) < 2 {
+				continue
+			}
+			if parts[0] == "AAA" {
+				current.netPrice = parts[1]
+			} else if current.price == "" {
+				current.price = parts[1]
+			}
+		}
+	}
+	if current != nil {
+		parsed = append(parsed, *current)
+	}
+	if len(parsed) == 0 {
+		return nil, 0, errors.New("edi message contains no LIN segments")
+	}
+
+	items := make([]priceFeedItem, 0, len(parsed))
+	invalid := 0
+	for _, line := range parsed {
+		sku := line.supplierSKU
+		if sku == "" {
+			sku = line.lineSKU
+		}
+		price := line.netPrice
+		if price == "" {
+			price = line.price
+		}
+		item, ok := newPriceFeedItem(sku, price)
+		if !ok {
+			invalid++
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, invalid, nil
+}
+
+// splitEDI splits s on sep, honouring the release character that escapes the next rune.
+func splitEDI(s string, sep, release rune) []string {
+	parts := make([]string, 0, 4)
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == release:
+			escaped = true
+		case r == sep:
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return append(parts, b.String())
+}
+
+// parseAPIPriceFeed reads a JSON array of price objects, or an object holding
+// that array under items, products, prices or data.
+func parseAPIPriceFeed(body []byte, skuField, priceField string) ([]priceFeedItem, int, error) {
+	var rows []map[string]any
+	if err := json.Unmarshal(body, &rows); err != nil {
+		var envelope map[string]json.RawMessage
+		if envErr := json.Unmarshal(body, &envelope); envErr != nil {
+			return nil, 0, fmt.Errorf("decode price feed json: %w", err)
+		}
+		for _, key := range []string{"items", "products", "prices", "data"} {
+			if raw, ok := envelope[key]; ok {
+				if err := json.Unmarshal(raw, &rows); err != nil {
+					return nil, 0, fmt.Errorf("decode price feed %s: %w", key, err)
+				}
+				break
+			}
+		}
+	}
+
+	items := make([]priceFeedItem, 0, len(rows))
+	invalid := 0
+	for _, row := range rows {
+		item, ok := newPriceFeedItem(jsonScalar(row[skuField]), jsonScalar(row[priceField]))
+		if !ok {
+			invalid++
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, invalid, nil
+}
+
+func jsonScalar(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+func newPriceFeedItem(sku, price string) (priceFeedItem, bool) {
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return priceFeedItem{}, false
+	}
+	cents, err := parseFeedPriceCents(price)
+	if err != nil {
+		return priceFeedItem{}, false
+	}
+	return priceFeedItem{SKU: sku, CostCents: cents}, true
+}
+
+// parseFeedPriceCents parses a supplier price in euros. Both "1.234,56" and
+// "1,234.56" are accepted: the last separator is the decimal mark.
+func parseFeedPriceCents(raw string) (int64, error) {
+	value := strings.TrimSpace(raw)
+	value = strings.TrimPrefix(value, "€")
+	value = strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(value), "EUR"))
+	value = strings.ReplaceAll(value, " ", "")
+	if value == "" {
+		return 0, errors.New("empty price")
+	}
+
+	lastComma, lastDot := strings.LastIndex(value, ","), strings.LastIndex(value, ".")
+	if lastComma > lastDot {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.Replace(value, ",", ".", 1)
+	} else {
+		value = strings.ReplaceAll(value, ",", "")
+	}
+
+	euros, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price %q", raw)
+	}
+	if euros < 0 || math.IsInf(euros, 0) || math.IsNaN(euros) {
+		return 0, fmt.Errorf("invalid price %q", raw)
+	}
+	return int64(math.Round(euros * 100)), nil
+}
+
+func dedupePriceFeedItems(items []priceFeedItem) []priceFeedItem {
+	index := make(map[string]int, len(items))
+	deduped := make([]priceFeedItem, 0, len(items))
+	for _, item := range items {
+		if i, ok := index[item.SKU]; ok {
+			deduped[i] = item
+			continue
+		}
+		index[item.SKU] = len(deduped)
+		deduped = append(deduped, item)
+	}
+	return deduped
+}
+
+// resolveMarginBps returns the margin of the most specific rule for a product of
+// productType imported by feedID. A feed match outranks a product type match.
+func resolveMarginBps(rules []repository.PriceMarginRule, feedID uuid.UUID, productType string) (int, bool) {
+	margin, bestScore := 0, -1
+	for _, rule := range rules {
+		score := 0
+		if rule.FeedID != nil {
+			if *rule.FeedID != feedID {
+				continue
+			}
+			score += 2
+		}
+		if rule.ProductType != nil {
+			if *rule.ProductType != productType {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			margin, bestScore = rule.MarginBps, score
+		}
+	}
+	return margin, bestScore >= 0
+}
+
+// applyMargin derives the sell price from a cost price, rounded to whole cents.
+func applyMargin(costCents int64, marginBps int) int64 {
+	return (costCents*int64(10000+marginBps) + 5000) / 10000
+}
+
+// costChangeBps returns the relative cost change in basis points. There is no
+// change to report when the previous cost is unknown or zero.
+func costChangeBps(oldCents *int64, newCents int64) (int, bool) {
+	if oldCents == nil || *oldCents <= 0 {
+		return 0, false
+	}
+	return int((newCents - *oldCents) * 10000 / *oldCents), true
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"portal_final_backend/internal/catalog/repository"
+)
+
+func TestParseCSVPriceFeedReadsDecimalComma(t *testing.T) {
+	feed := repository.PriceFeed{Format: priceFeedFormatCSV, CSVDelimiter: ";", SKUField: "artikel", PriceField: "prijs"}
+	body := []byte("\xef\xbb\xbfArtikel;Omschrijving;Prijs\nA-1;Tegel;1.234,56\nA-2;Lijm;\nA-1;Tegel;12,50\n")
+
+	items, invalid, err := parsePriceFeed(feed, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if invalid != 1 {
+		t.Fatalf("expected 1 invalid row, got %d", invalid)
+	}
+	if len(items) != 1 || items[0].SKU != "A-1" || items[0].CostCents != 1250 {
+		t.Fatalf("expected the last A-1 row to win, got %+v", items)
+	}
+}
+
+func TestParseEDIPriceFeedPrefersSupplierCodeAndNetPrice(t *testing.T) {
+	feed := repository.PriceFeed{Format: priceFeedFormatEDI}
+	body := []byte("UNA:+.? 'UNH+1+PRICAT:D:96A:UN'LIN+1++8712345000017:EN'PIA+1+SUP-17:SA'PRI+AAB:19.95'PRI+AAA:15.50'LIN+2++8712345000024:EN'PRI+AAB:4.20'UNT+8+1'")
+
+	items, invalid, err := parsePriceFeed(feed, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if invalid != 0 || len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v (invalid %d)", items, invalid)
+	}
+	if items[0].SKU != "SUP-17" || items[0].CostCents != 1550 {
+		t.Fatalf("expected supplier code with net price, got %+v", items[0])
+	}
+	if items[1].SKU != "8712345000024" || items[1].CostCents != 420 {
+		t.Fatalf("expected item number with gross price fallback, got %+v", items[1])
+	}
+}
+
+func TestParseAPIPriceFeedReadsEnvelope(t *testing.T) {
+	feed := repository.PriceFeed{Format: priceFeedFormatAPI, SKUField: "sku", PriceField: "price"}
+	body := []byte(`{"items":[{"sku":"X1","price":9.99},{"sku":"X2","price":"1,234.50"},{"price":3}]}`)
+
+	items, invalid, err := parsePriceFeed(feed, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if invalid != 1 || len(items) != 2 || items[0].CostCents != 999 || items[1].CostCents != 123450 {
+		t.Fatalf("unexpected items %+v (invalid %d)", items, invalid)
+	}
+}
+
+func TestResolveMarginBpsPrefersMostSpecificRule(t *testing.T) {
+	feedID, otherFeedID := uuid.New(), uuid.New()
+	material := "material"
+	rules := []repository.PriceMarginRule{
+		{MarginBps: 2000},
+		{ProductType: &material, MarginBps: 2500},
+		{FeedID: &feedID, MarginBps: 3000},
+		{FeedID: &otherFeedID, ProductType: &material, MarginBps: 9000},
+	}
+
+	if margin, ok := resolveMarginBps(rules, feedID, material); !ok || margin != 3000 {
+		t.Fatalf("expected feed rule to win, got %d %v", margin, ok)
+	}
+	if margin, ok := resolveMarginBps(rules, uuid.New(), material); !ok || margin != 2500 {
+		t.Fatalf("expected product type rule, got %d %v", margin, ok)
+	}
+	if margin, ok := resolveMarginBps(rules, uuid.New(), "service"); !ok || margin != 2000 {
+		t.Fatalf("expected default rule, got %d %v", margin, ok)
+	}
+	if _, ok := resolveMarginBps(nil, feedID, material); ok {
+		t.Fatal("expected no margin without rules")
+	}
+}
+
+func TestBuildPriceChangeFlagsJumpsAboveThreshold(t *testing.T) {
+	feed := repository.PriceFeed{ID: uuid.New(), AlertThresholdBps: 1000}
+	oldCost := int64(1000)
+	product := repository.PriceFeedProduct{ID: uuid.New(), Type: "material", CostCents: &oldCost, PriceCents: 1500}
+	rules := []repository.PriceMarginRule{{MarginBps: 5000}}
+
+	params := buildPriceChange(feed, product, priceFeedItem{SKU: "A-1", CostCents: 1250}, rules)
+	if params.ChangeBps == nil || *params.ChangeBps != 2500 || !params.ExceedsThreshold {
+		t.Fatalf("expected a 25%% jump above threshold, got %+v", params)
+	}
+	if params.NewPriceCents != 1875 || params.UpdateUnitPrice {
+		t.Fatalf("expected price 1875 from margin, got %+v", params)
+	}
+
+	params = buildPriceChange(feed, product, priceFeedItem{SKU: "A-1", CostCents: 950}, nil)
+	if params.ExceedsThreshold || params.NewPriceCents != 1500 {
+		t.Fatalf("expected small change without margin to keep price, got %+v", params)
+	}
+}
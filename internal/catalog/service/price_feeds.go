@@ -0,0 +1,449 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"portal_final_backend/internal/catalog/repository"
+	"portal_final_backend/internal/catalog/transport"
+	"portal_final_backend/internal/events"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/secrets"
+)
+
+const (
+	defaultFeedSKUField           = "sku"
+	defaultFeedPriceField         = "price"
+	defaultFeedCSVDelimiter       = ";"
+	defaultFeedFetchIntervalHours = 168
+	defaultFeedAlertThresholdBps  = 1000
+	defaultPriceChangesLimit      = 100
+	maxPriceFeedBytes             = 20 << 20
+	maxAlertedPriceJumps          = 50
+)
+
+// PriceFeedSweepResult summarizes a scheduled run over all due price feeds.
+type PriceFeedSweepResult struct {
+	Feeds   int
+	Failed  int
+	Updated int
+	Alerts  int
+}
+
+// SetEventBus enables price jump alerts.
+func (s *Service) SetEventBus(bus events.Bus) {
+	s.eventBus = bus
+}
+
+// SetPriceFeedKeyring sets the keyring that encrypts supplier API tokens.
+func (s *Service) SetPriceFeedKeyring(keyring *secrets.Keyring) {
+	s.priceFeedKeyring = keyring
+}
+
+// ListPriceFeeds returns the supplier price feeds of the tenant.
+func (s *Service) ListPriceFeeds(ctx context.Context, tenantID uuid.UUID) ([]transport.PriceFeedResponse, error) {
+	feeds, err := s.repo.ListPriceFeeds(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mapSlice(feeds, toPriceFeedResponse), nil
+}
+
+// CreatePriceFeed configures a new supplier price feed.
+func (s *Service) CreatePriceFeed(ctx context.Context, tenantID uuid.UUID, req transport.PriceFeedRequest) (transport.PriceFeedResponse, error) {
+	feed := repository.PriceFeed{
+		OrganizationID:     tenantID,
+		FetchIntervalHours: defaultFeedFetchIntervalHours,
+		AlertThresholdBps:  defaultFeedAlertThresholdBps,
+		IsActive:           true,
+	}
+	if err := s.applyPriceFeedRequest(&feed, req); err != nil {
+		return transport.PriceFeedResponse{}, err
+	}
+	created, err := s.repo.CreatePriceFeed(ctx, feed)
+	if err != nil {
+		return transport.PriceFeedResponse{}, err
+	}
+	return toPriceFeedResponse(created), nil
+}
+
+// UpdatePriceFeed replaces the settings of a supplier price feed.
+func (s *Service) UpdatePriceFeed(ctx context.Context, tenantID, id uuid.UUID, req transport.PriceFeedRequest) (transport.PriceFeedResponse, error) {
+	feed, err := s.repo.GetPriceFeed(ctx, tenantID, id)
+	if err != nil {
+		return transport.PriceFeedResponse{}, err
+	}
+	if err := s.applyPriceFeedRequest(&feed, req); err != nil {
+		return transport.PriceFeedResponse{}, err
+	}
+	updated, err := s.repo.UpdatePriceFeed(ctx, feed)
+	if err != nil {
+		return transport.PriceFeedResponse{}, err
+	}
+	return toPriceFeedResponse(updated), nil
+}
+
+// DeletePriceFeed removes a supplier price feed.
+func (s *Service) DeletePriceFeed(ctx context.Context, tenantID, id uuid.UUID) error {
+	return s.repo.DeletePriceFeed(ctx, tenantID, id)
+}
+
+// RunPriceFeed fetches a feed from its source URL and imports it right away.
+func (s *Service) RunPriceFeed(ctx context.Context, tenantID, id uuid.UUID) (transport.PriceFeedRunResponse, error) {
+	feed, err := s.repo.GetPriceFeed(ctx, tenantID, id)
+	if err != nil {
+		return transport.PriceFeedRunResponse{}, err
+	}
+	if feed.SourceURL == nil {
+		return transport.PriceFeedRunResponse{}, apperr.Validation("price feed has no source URL; upload the file instead")
+	}
+	return s.runPriceFeed(ctx, feed, time.Now())
+}
+
+// ImportPriceFeed imports an uploaded feed file.
+func (s *Service) ImportPriceFeed(ctx context.Context, tenantID, id uuid.UUID, body []byte) (transport.PriceFeedRunResponse, error) {
+	feed, err := s.repo.GetPriceFeed(ctx, tenantID, id)
+	if err != nil {
+		return transport.PriceFeedRunResponse{}, err
+	}
+	result, err := s.importPriceFeed(ctx, feed, body)
+	s.recordPriceFeedRun(ctx, feed, time.Now(), err)
+	return result, err
+}
+
+// RunDuePriceFeeds fetches and imports every active feed whose fetch interval
+// has passed. A failing feed is recorded on the feed and does not stop the others.
+func (s *Service) RunDuePriceFeeds(ctx context.Context, now time.Time) (PriceFeedSweepResult, error) {
+	feeds, err := s.repo.ListDuePriceFeeds(ctx, now)
+	if err != nil {
+		return PriceFeedSweepResult{}, err
+	}
+	result := PriceFeedSweepResult{Feeds: len(feeds)}
+	for _, feed := range feeds {
+		run, err := s.runPriceFeed(ctx, feed, now)
+		if err != nil {
+			result.Failed++
+			s.log.Error("catalog price feed run failed", "feedId", feed.ID, "supplier", feed.SupplierName, "error", err)
+			continue
+		}
+		result.Updated += run.Updated
+		result.Alerts += run.Alerts
+	}
+	return result, nil
+}
+
+func (s *Service) runPriceFeed(ctx context.Context, feed repository.PriceFeed, now time.Time) (transport.PriceFeedRunResponse, error) {
+	body, err := s.fetchPriceFeed(ctx, feed)
+	var result transport.PriceFeedRunResponse
+	if err == nil {
+		result, err = s.importPriceFeed(ctx, feed, body)
+	}
+	s.recordPriceFeedRun(ctx, feed, now, err)
+	return result, err
+}
+
+func (s *Service) recordPriceFeedRun(ctx context.Context, feed repository.PriceFeed, ranAt time.Time, runErr error) {
+	var lastError *string
+	if runErr != nil {
+		lastError = strPtr(runErr.Error())
+	}
+	if err := s.repo.RecordPriceFeedRun(ctx, feed.ID, ranAt, lastError); err != nil {
+		s.log.Error("failed to record catalog price feed run", "feedId", feed.ID, "error", err)
+	}
+}
+
+func (s *Service) fetchPriceFeed(ctx context.Context, feed repository.PriceFeed) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *feed.SourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build price feed request: %w", err)
+	}
+	if feed.APITokenEncrypted != nil {
+		if s.priceFeedKeyring == nil {
+			return nil, fmt.Errorf("price feed API token cannot be decrypted: no keyring configured")
+		}
+		token, err := s.priceFeedKeyring.Decrypt(*feed.APITokenEncrypted)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt price feed API token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch price feed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch price feed: unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPriceFeedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read price feed: %w", err)
+	}
+	return body, nil
+}
+
+// importPriceFeed matches the feed rows to products on reference, stores the new
+// costs, reprices products covered by a margin rule and alerts on cost jumps
+// beyond the feed's threshold. Unchanged costs leave no history.
+func (s *Service) importPriceFeed(ctx context.Context, feed repository.PriceFeed, body []byte) (transport.PriceFeedRunResponse, error) {
+	items, invalid, err := parsePriceFeed(feed, body)
+	if err != nil {
+		return transport.PriceFeedRunResponse{}, apperr.Validation(err.Error())
+	}
+	result := transport.PriceFeedRunResponse{Rows: len(items) + invalid, Invalid: invalid}
+
+	references := make([]string, 0, len(items))
+	for _, item := range items {
+		references = append(references, item.SKU)
+	}
+	products, err := s.repo.ListPriceFeedProducts(ctx, feed.OrganizationID, references)
+	if err != nil {
+		return result, err
+	}
+	productByRef := make(map[string]repository.PriceFeedProduct, len(products))
+	for _, product := range products {
+		productByRef[product.Reference] = product
+	}
+	rules, err := s.repo.ListPriceMarginRules(ctx, feed.OrganizationID)
+	if err != nil {
+		return result, err
+	}
+
+	jumps := make([]events.CatalogPriceJump, 0)
+	for _, item := range items {
+		product, ok := productByRef[item.SKU]
+		if !ok {
+			result.Unmatched++
+			continue
+		}
+		result.Matched++
+		if product.CostCents != nil && *product.CostCents == item.CostCents {
+			result.Unchanged++
+			continue
+		}
+
+		params := buildPriceChange(feed, product, item, rules)
+		if err := s.repo.ApplyPriceChange(ctx, params); err != nil {
+			return result, err
+		}
+		result.Updated++
+		if params.ExceedsThreshold {
+			result.Alerts++
+			jumps = append(jumps, events.CatalogPriceJump{
+				ProductID:    product.ID,
+				Title:        product.Title,
+				Reference:    product.Reference,
+				OldCostCents: *params.OldCostCents,
+				NewCostCents: params.NewCostCents,
+				ChangeBps:    *params.ChangeBps,
+			})
+		}
+	}
+
+	s.publishPriceJumps(ctx, feed, jumps)
+	return result, nil
+}
+
+func buildPriceChange(feed repository.PriceFeed, product repository.PriceFeedProduct, item priceFeedItem, rules []repository.PriceMarginRule) repository.ApplyPriceChangeParams {
+	updateUnitPrice := product.UnitPriceCents > 0
+	oldPrice := product.PriceCents
+	if updateUnitPrice {
+		oldPrice = product.UnitPriceCents
+	}
+	newPrice := oldPrice
+	if margin, ok := resolveMarginBps(rules, feed.ID, product.Type); ok {
+		newPrice = applyMargin(item.CostCents, margin)
+	}
+
+	params := repository.ApplyPriceChangeParams{
+		OrganizationID:  feed.OrganizationID,
+		ProductID:       product.ID,
+		FeedID:          feed.ID,
+		SupplierSKU:     item.SKU,
+		OldCostCents:    product.CostCents,
+		NewCostCents:    item.CostCents,
+		OldPriceCents:   oldPrice,
+		NewPriceCents:   newPrice,
+		UpdateUnitPrice: updateUnitPrice,
+	}
+	if change, ok := costChangeBps(product.CostCents, item.CostCents); ok {
+		params.ChangeBps = &change
+		params.ExceedsThreshold = feed.AlertThresholdBps > 0 && (change > feed.AlertThresholdBps || -change > feed.AlertThresholdBps)
+	}
+	return params
+}
+
+func (s *Service) publishPriceJumps(ctx context.Context, feed repository.PriceFeed, jumps []events.CatalogPriceJump) {
+	if s.eventBus == nil || len(jumps) == 0 {
+		return
+	}
+	if len(jumps) > maxAlertedPriceJumps {
+		jumps = jumps[:maxAlertedPriceJumps]
+	}
+	s.eventBus.Publish(ctx, events.CatalogPriceJumpsDetected{
+		BaseEvent:      events.NewBaseEvent(),
+		OrganizationID: feed.OrganizationID,
+		FeedID:         feed.ID,
+		SupplierName:   feed.SupplierName,
+		ThresholdBps:   feed.AlertThresholdBps,
+		Jumps:          jumps,
+	})
+}
+
+// ListPriceChanges returns the price change history of the tenant, or of one product.
+func (s *Service) ListPriceChanges(ctx context.Context, tenantID uuid.UUID, productID *uuid.UUID, req transport.ListPriceChangesRequest) ([]transport.PriceChangeResponse, error) {
+	params := repository.ListPriceChangesParams{
+		OrganizationID: tenantID,
+		ProductID:      productID,
+		Limit:          req.Limit,
+	}
+	if params.Limit <= 0 {
+		params.Limit = defaultPriceChangesLimit
+	}
+	if req.FeedID != "" {
+		feedID, err := uuid.Parse(req.FeedID)
+		if err != nil {
+			return nil, apperr.BadRequest("invalid feedId")
+		}
+		params.FeedID = &feedID
+	}
+	changes, err := s.repo.ListPriceChanges(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return mapSlice(changes, toPriceChangeResponse), nil
+}
+
+// ListPriceMarginRules returns the margin rules of the tenant.
+func (s *Service) ListPriceMarginRules(ctx context.Context, tenantID uuid.UUID) ([]transport.PriceMarginRuleResponse, error) {
+	rules, err := s.repo.ListPriceMarginRules(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mapSlice(rules, toPriceMarginRuleResponse), nil
+}
+
+// SavePriceMarginRule sets the margin of a feed and product type scope. It applies
+// from the next import of the feed.
+func (s *Service) SavePriceMarginRule(ctx context.Context, tenantID uuid.UUID, req transport.PriceMarginRuleRequest) (transport.PriceMarginRuleResponse, error) {
+	var productType *string
+	if req.ProductType != nil {
+		productType = optionalString(*req.ProductType)
+	}
+	rule, err := s.repo.UpsertPriceMarginRule(ctx, repository.PriceMarginRule{
+		OrganizationID: tenantID,
+		FeedID:         req.FeedID,
+		ProductType:    productType,
+		MarginBps:      *req.MarginBps,
+	})
+	if err != nil {
+		return transport.PriceMarginRuleResponse{}, err
+	}
+	return toPriceMarginRuleResponse(rule), nil
+}
+
+// DeletePriceMarginRule removes a margin rule.
+func (s *Service) DeletePriceMarginRule(ctx context.Context, tenantID, id uuid.UUID) error {
+	return s.repo.DeletePriceMarginRule(ctx, tenantID, id)
+}
+
+func (s *Service) applyPriceFeedRequest(feed *repository.PriceFeed, req transport.PriceFeedRequest) error {
+	feed.SupplierName = strings.TrimSpace(req.SupplierName)
+	feed.Format = req.Format
+	feed.SourceURL = optionalString(req.SourceURL)
+	feed.SKUField = defaultString(strings.TrimSpace(req.SKUField), defaultFeedSKUField)
+	feed.PriceField = defaultString(strings.TrimSpace(req.PriceField), defaultFeedPriceField)
+	feed.CSVDelimiter = defaultString(req.CSVDelimiter, defaultFeedCSVDelimiter)
+	if req.FetchIntervalHours > 0 {
+		feed.FetchIntervalHours = req.FetchIntervalHours
+	}
+	if req.AlertThresholdBps != nil {
+		feed.AlertThresholdBps = *req.AlertThresholdBps
+	}
+	if req.IsActive != nil {
+		feed.IsActive = *req.IsActive
+	}
+
+	if req.APIToken == nil {
+		return nil
+	}
+	token := strings.TrimSpace(*req.APIToken)
+	if token == "" {
+		feed.APITokenEncrypted = nil
+		return nil
+	}
+	if s.priceFeedKeyring == nil {
+		return apperr.Validation("storing supplier API tokens is not configured")
+	}
+	encrypted, err := s.priceFeedKeyring.Encrypt(token)
+	if err != nil {
+		return fmt.Errorf("encrypt price feed API token: %w", err)
+	}
+	feed.APITokenEncrypted = &encrypted
+	return nil
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func toPriceFeedResponse(feed repository.PriceFeed) transport.PriceFeedResponse {
+	resp := transport.PriceFeedResponse{
+		ID:                 feed.ID,
+		SupplierName:       feed.SupplierName,
+		Format:             feed.Format,
+		SourceURL:          feed.SourceURL,
+		SKUField:           feed.SKUField,
+		PriceField:         feed.PriceField,
+		CSVDelimiter:       feed.CSVDelimiter,
+		LastError:          feed.LastError,
+		CreatedAt:          feed.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:          feed.UpdatedAt.Format(time.RFC3339),
+		FetchIntervalHours: feed.FetchIntervalHours,
+		AlertThresholdBps:  feed.AlertThresholdBps,
+		HasAPIToken:        feed.APITokenEncrypted != nil,
+		IsActive:           feed.IsActive,
+	}
+	if feed.LastRunAt != nil {
+		resp.LastRunAt = strPtr(feed.LastRunAt.Format(time.RFC3339))
+	}
+	return resp
+}
+
+func toPriceMarginRuleResponse(rule repository.PriceMarginRule) transport.PriceMarginRuleResponse {
+	return transport.PriceMarginRuleResponse{
+		ID:          rule.ID,
+		FeedID:      rule.FeedID,
+		ProductType: rule.ProductType,
+		CreatedAt:   rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   rule.UpdatedAt.Format(time.RFC3339),
+		MarginBps:   rule.MarginBps,
+	}
+}
+
+func toPriceChangeResponse(change repository.PriceChange) transport.PriceChangeResponse {
+	return transport.PriceChangeResponse{
+		ID:               change.ID,
+		ProductID:        change.ProductID,
+		FeedID:           change.FeedID,
+		ProductTitle:     change.ProductTitle,
+		SupplierSKU:      change.SupplierSKU,
+		OldCostCents:     change.OldCostCents,
+		ChangeBps:        change.ChangeBps,
+		CreatedAt:        change.CreatedAt.Format(time.RFC3339),
+		NewCostCents:     change.NewCostCents,
+		OldPriceCents:    change.OldPriceCents,
+		NewPriceCents:    change.NewPriceCents,
+		ExceedsThreshold: change.ExceedsThreshold,
+	}
+}
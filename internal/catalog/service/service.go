@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,11 +15,14 @@ import (
 	"portal_final_backend/internal/adapters/storage"
 	"portal_final_backend/internal/catalog/repository"
 	"portal_final_backend/internal/catalog/transport"
+	"portal_final_backend/internal/events"
 	"portal_final_backend/platform/ai/embeddingapi"
 	"portal_final_backend/platform/ai/embeddings"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/apperr"
 	"portal_final_backend/platform/logger"
 	"portal_final_backend/platform/qdrant"
+	"portal_final_backend/platform/secrets"
 )
 
 const errPriceAndUnitPriceNonNegative = "priceCents and unitPriceCents must be 0 or greater"
@@ -38,6 +42,7 @@ const (
 type autocompleteCollectionSearch struct {
 	kind       string
 	collection string
+	target     string // physical collection; differs from collection for per-tenant catalogs
 	filter     *qdrant.Filter
 }
 
@@ -61,10 +66,15 @@ type Service struct {
 	log                 *logger.Logger
 	embeddingClient     *embeddingapi.Client
 	embeddingCollection string
+	embeddingVersion    string
+	vectorTenancyMode   string
 	searchEmbedding     *embeddings.Client
 	catalogQdrant       *qdrant.Client
 	qdrantClient        *qdrant.Client
 	bouwmaatQdrant      *qdrant.Client
+	eventBus            events.Bus
+	priceFeedKeyring    *secrets.Keyring
+	httpClient          *http.Client
 }
 
 // Config contains dependencies for constructing Service.
@@ -75,6 +85,8 @@ type Config struct {
 	Logger              *logger.Logger
 	EmbeddingClient     *embeddingapi.Client
 	EmbeddingCollection string
+	EmbeddingVersion    string
+	VectorTenancyMode   string
 	SearchEmbedding     *embeddings.Client
 	CatalogQdrant       *qdrant.Client
 	QdrantClient        *qdrant.Client
@@ -90,10 +102,13 @@ func New(cfg Config) *Service {
 		log:                 cfg.Logger,
 		embeddingClient:     cfg.EmbeddingClient,
 		embeddingCollection: strings.TrimSpace(cfg.EmbeddingCollection),
+		embeddingVersion:    strings.TrimSpace(cfg.EmbeddingVersion),
+		vectorTenancyMode:   qdrant.NormalizeTenancyMode(cfg.VectorTenancyMode),
 		searchEmbedding:     cfg.SearchEmbedding,
 		catalogQdrant:       cfg.CatalogQdrant,
 		qdrantClient:        cfg.QdrantClient,
 		bouwmaatQdrant:      cfg.BouwmaatQdrant,
+		httpClient:          &http.Client{Timeout: 60 * time.Second},
 	}
 }
 
@@ -370,6 +385,12 @@ func (s *Service) UpdateProduct(ctx context.Context, tenantID uuid.UUID, id uuid
 		PeriodUnit:     req.PeriodUnit,
 	}
 
+	// A branch editing a product inherited from its head office keeps its
+	// version from then on.
+	if err := s.repo.MarkInheritedProductOverridden(ctx, tenantID, id); err != nil {
+		return transport.ProductResponse{}, err
+	}
+
 	product, err := s.repo.UpdateProduct(ctx, params)
 	if err != nil {
 		return transport.ProductResponse{}, err
@@ -394,24 +415,14 @@ func (s *Service) indexProductAsync(tenantID uuid.UUID, product repository.Produ
 		return
 	}
 
-	request := embeddingapi.AddDocumentsRequest{
-		Documents:  []map[string]any{s.buildCatalogDocument(tenantID, product)},
-		TextFields: []string{"name", "description", "reference", "type", "labor_time_text", "unit_label"},
-		IDField:    "id",
-		Collection: s.embeddingCollection,
-	}
-
 	go func() {
 		// Use a fresh timeout context for the background worker to prevent context leakage
 		bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		resp, err := s.embeddingClient.AddDocuments(bgCtx, request)
-		if err != nil {
+		if err := s.indexProduct(bgCtx, tenantID, product, reason); err != nil {
 			s.log.Error("catalog indexing failed", "error", err, "productId", product.ID, "reason", reason)
-			return
 		}
-		s.log.Info("catalog indexed", "productId", product.ID, "documentsAdded", resp.DocumentsAdded, "reason", reason)
 	}()
 }
 
@@ -428,6 +439,10 @@ func (s *Service) buildCatalogDocument(tenantID uuid.UUID, product repository.Pr
 		"unit_price_cents": product.UnitPriceCents,
 		"vat_rate_id":      product.VatRateID.String(),
 	}
+	if s.embeddingClient != nil {
+		document["embedding_model"] = s.embeddingClient.Model()
+		document["embedding_version"] = s.embeddingVersion
+	}
 	if product.UnitLabel != nil && strings.TrimSpace(*product.UnitLabel) != "" {
 		document["unit_label"] = strings.TrimSpace(*product.UnitLabel)
 	}
@@ -571,6 +586,38 @@ func (s *Service) CreateCatalogAsset(ctx context.Context, tenantID uuid.UUID, pr
 	return toCatalogAssetResponse(asset), nil
 }
 
+// UploadCatalogAsset streams a multipart file upload to MinIO and creates the
+// catalog asset for it.
+func (s *Service) UploadCatalogAsset(ctx context.Context, tenantID uuid.UUID, productID uuid.UUID, assetType string, r *http.Request) (transport.CatalogAssetResponse, error) {
+	if _, err := s.repo.GetProductByID(ctx, tenantID, productID); err != nil {
+		return transport.CatalogAssetResponse{}, err
+	}
+	if assetType != "image" && assetType != "document" {
+		return transport.CatalogAssetResponse{}, apperr.Validation("invalid assetType")
+	}
+
+	folder := fmt.Sprintf("%s/%s/%s", tenantID.String(), productID.String(), assetType)
+	upload, err := storage.StreamMultipartUpload(ctx, s.storage, r, s.bucket, folder, func(contentType string) error {
+		return validateAssetType(assetType, contentType)
+	})
+	if err != nil {
+		return transport.CatalogAssetResponse{}, err
+	}
+
+	asset, err := s.CreateCatalogAsset(ctx, tenantID, productID, transport.CreateCatalogAssetRequest{
+		AssetType:   assetType,
+		FileKey:     upload.FileKey,
+		FileName:    upload.FileName,
+		ContentType: upload.ContentType,
+		SizeBytes:   upload.SizeBytes,
+	})
+	if err != nil {
+		_ = s.storage.DeleteObject(ctx, s.bucket, upload.FileKey)
+		return transport.CatalogAssetResponse{}, err
+	}
+	return asset, nil
+}
+
 func (s *Service) CreateCatalogURLAsset(ctx context.Context, tenantID uuid.UUID, productID uuid.UUID, req transport.CreateCatalogURLAssetRequest) (transport.CatalogAssetResponse, error) {
 	if _, err := s.repo.GetProductByID(ctx, tenantID, productID); err != nil {
 		return transport.CatalogAssetResponse{}, err
@@ -771,7 +818,8 @@ func (s *Service) qdrantSearch(ctx context.Context, tenantID uuid.UUID, query st
 		return autocompleteQdrantSources{}, nil
 	}
 
-	vector, err := s.searchEmbedding.Embed(ctx, query)
+	embedCtx := llmrouter.WithSource(llmrouter.WithOrganizationID(ctx, tenantID), "catalog-search")
+	vector, err := s.searchEmbedding.Embed(embedCtx, query)
 	if err != nil {
 		return autocompleteQdrantSources{}, fmt.Errorf("embed query: %w", err)
 	}
@@ -779,7 +827,7 @@ func (s *Service) qdrantSearch(ctx context.Context, tenantID uuid.UUID, query st
 	requests := make([]qdrant.SearchRequest, 0, len(searches))
 	for _, search := range searches {
 		requests = append(requests, qdrant.SearchRequest{
-			CollectionName: search.collection,
+			CollectionName: search.target,
 			Vector:         vector,
 			Limit:          limit,
 			WithPayload:    true,
@@ -817,7 +865,7 @@ func (s *Service) qdrantSearch(ctx context.Context, tenantID uuid.UUID, query st
 func (s *Service) buildAutocompleteCollectionSearches(tenantID uuid.UUID) ([]autocompleteCollectionSearch, *qdrant.Client) {
 	searches := make([]autocompleteCollectionSearch, 0, 3)
 	var batchClient *qdrant.Client
-	appendSearch := func(client *qdrant.Client, kind string, filter *qdrant.Filter) {
+	appendSearch := func(client *qdrant.Client, kind string, filter *qdrant.Filter, target string) {
 		if client == nil {
 			return
 		}
@@ -828,11 +876,17 @@ func (s *Service) buildAutocompleteCollectionSearches(tenantID uuid.UUID) ([]aut
 		if batchClient == nil {
 			batchClient = client
 		}
-		searches = append(searches, autocompleteCollectionSearch{kind: kind, collection: collection, filter: filter})
+		if target == "" {
+			target = collection
+		}
+		searches = append(searches, autocompleteCollectionSearch{kind: kind, collection: collection, target: target, filter: filter})
+	}
+	// The catalog is tenant-owned: without a tenant it is skipped rather than searched unfiltered.
+	if s.catalogQdrant != nil && tenantID != uuid.Nil {
+		appendSearch(s.catalogQdrant, autocompleteSourceCatalog, qdrant.NewOrganizationFilter(tenantID.String()), s.catalogQdrant.TenantCollection(tenantID.String()))
 	}
-	appendSearch(s.catalogQdrant, autocompleteSourceCatalog, qdrant.NewOrganizationFilter(tenantID.String()))
-	appendSearch(s.qdrantClient, autocompleteSourceRef, nil)
-	appendSearch(s.bouwmaatQdrant, autocompleteSourceRef, nil)
+	appendSearch(s.qdrantClient, autocompleteSourceRef, nil, "")
+	appendSearch(s.bouwmaatQdrant, autocompleteSourceRef, nil, "")
 	return searches, batchClient
 }
 
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"portal_final_backend/internal/catalog/repository"
+	"portal_final_backend/internal/catalog/transport"
+	"portal_final_backend/internal/events"
+	"portal_final_backend/platform/apperr"
+)
+
+const defaultStockMovementsLimit = 50
+
+// GetProductStock returns the stock level of a tracked product with its recent movements.
+func (s *Service) GetProductStock(ctx context.Context, tenantID, productID uuid.UUID) (transport.ProductStockDetailResponse, error) {
+	stock, err := s.repo.GetProductStock(ctx, tenantID, productID)
+	if err != nil {
+		return transport.ProductStockDetailResponse{}, err
+	}
+	movements, err := s.repo.ListStockMovements(ctx, tenantID, productID, defaultStockMovementsLimit)
+	if err != nil {
+		return transport.ProductStockDetailResponse{}, err
+	}
+	return transport.ProductStockDetailResponse{
+		ProductStockResponse: toProductStockResponse(stock),
+		Movements:            mapSlice(movements, toStockMovementResponse),
+	}, nil
+}
+
+// SetProductStock starts tracking the stock of a product or corrects its counted quantity.
+func (s *Service) SetProductStock(ctx context.Context, tenantID, userID, productID uuid.UUID, req transport.SetProductStockRequest) (transport.ProductStockResponse, error) {
+	stock, err := s.repo.SetProductStock(ctx, repository.SetProductStockParams{
+		OrganizationID:    tenantID,
+		ProductID:         productID,
+		CreatedBy:         &userID,
+		Quantity:          *req.Quantity,
+		LowStockThreshold: req.LowStockThreshold,
+	})
+	if err != nil {
+		return transport.ProductStockResponse{}, err
+	}
+	return toProductStockResponse(stock), nil
+}
+
+// AdjustProductStock books a relative stock change on a tracked product.
+func (s *Service) AdjustProductStock(ctx context.Context, tenantID, userID, productID uuid.UUID, req transport.AdjustProductStockRequest) (transport.ProductStockResponse, error) {
+	if req.Delta == 0 {
+		return transport.ProductStockResponse{}, apperr.Validation("delta must not be zero")
+	}
+	stock, err := s.repo.AdjustProductStock(ctx, repository.AdjustProductStockParams{
+		OrganizationID: tenantID,
+		ProductID:      productID,
+		CreatedBy:      &userID,
+		Note:           optionalString(req.Note),
+		Delta:          req.Delta,
+	})
+	if err != nil {
+		return transport.ProductStockResponse{}, err
+	}
+	return toProductStockResponse(stock), nil
+}
+
+// StopTrackingProductStock removes the stock level of a product.
+func (s *Service) StopTrackingProductStock(ctx context.Context, tenantID, productID uuid.UUID) error {
+	return s.repo.DeleteProductStock(ctx, tenantID, productID)
+}
+
+// ListLowStockProducts returns the tracked products at or below their low-stock threshold.
+func (s *Service) ListLowStockProducts(ctx context.Context, tenantID uuid.UUID) ([]transport.ProductStockResponse, error) {
+	stocks, err := s.repo.ListLowStockProducts(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mapSlice(stocks, toProductStockResponse), nil
+}
+
+// GetStockSettings returns which event consumes stock for the tenant.
+func (s *Service) GetStockSettings(ctx context.Context, tenantID uuid.UUID) (transport.StockSettingsResponse, error) {
+	consumeOn, err := s.repo.GetStockConsumeOn(ctx, tenantID)
+	if err != nil {
+		return transport.StockSettingsResponse{}, err
+	}
+	return transport.StockSettingsResponse{ConsumeOn: consumeOn}, nil
+}
+
+// UpdateStockSettings selects which event consumes stock for the tenant.
+func (s *Service) UpdateStockSettings(ctx context.Context, tenantID uuid.UUID, req transport.StockSettingsRequest) (transport.StockSettingsResponse, error) {
+	if err := s.repo.SetStockConsumeOn(ctx, tenantID, req.ConsumeOn); err != nil {
+		return transport.StockSettingsResponse{}, err
+	}
+	return transport.StockSettingsResponse{ConsumeOn: req.ConsumeOn}, nil
+}
+
+// ConsumeStockForAcceptedQuote consumes stock when the customer accepts a quote,
+// unless the organization consumes stock on partner offer acceptance instead.
+func (s *Service) ConsumeStockForAcceptedQuote(ctx context.Context, tenantID, quoteID uuid.UUID) error {
+	consumeOn, err := s.repo.GetStockConsumeOn(ctx, tenantID)
+	if err != nil || consumeOn != repository.StockReasonQuoteAccepted {
+		return err
+	}
+	return s.consumeStock(ctx, tenantID, quoteID, repository.StockReasonQuoteAccepted)
+}
+
+// ConsumeStockForAcceptedPartnerOffer consumes the stock of the accepted quote of
+// a lead service when a partner accepts its job offer, if the organization
+// consumes stock at that point.
+func (s *Service) ConsumeStockForAcceptedPartnerOffer(ctx context.Context, tenantID, leadServiceID uuid.UUID) error {
+	consumeOn, err := s.repo.GetStockConsumeOn(ctx, tenantID)
+	if err != nil || consumeOn != repository.StockReasonPartnerOfferAccepted {
+		return err
+	}
+	quoteID, err := s.repo.FindAcceptedQuoteForService(ctx, tenantID, leadServiceID)
+	if err != nil || quoteID == nil {
+		return err
+	}
+	return s.consumeStock(ctx, tenantID, *quoteID, repository.StockReasonPartnerOfferAccepted)
+}
+
+func (s *Service) consumeStock(ctx context.Context, tenantID, quoteID uuid.UUID, reason string) error {
+	consumed, err := s.repo.ConsumeQuoteStock(ctx, tenantID, quoteID, reason)
+	if err != nil {
+		return err
+	}
+
+	lowStock := lowStockItems(consumed)
+	if s.eventBus != nil && len(lowStock) > 0 {
+		s.eventBus.Publish(ctx, events.CatalogLowStockDetected{
+			BaseEvent:      events.NewBaseEvent(),
+			OrganizationID: tenantID,
+			QuoteID:        quoteID,
+			Items:          lowStock,
+		})
+	}
+	if len(consumed) > 0 {
+		s.log.Info("catalog stock consumed", "quoteId", quoteID, "reason", reason, "products", len(consumed), "lowStock", len(lowStock))
+	}
+	return nil
+}
+
+// lowStockItems returns the products that crossed their low-stock threshold. A
+// product that was already low before the quote is not reported again.
+func lowStockItems(consumed []repository.StockConsumption) []events.CatalogLowStockItem {
+	items := make([]events.CatalogLowStockItem, 0)
+	for _, c := range consumed {
+		if c.QuantityBefore <= c.LowStockThreshold || c.QuantityAfter > c.LowStockThreshold {
+			continue
+		}
+		items = append(items, events.CatalogLowStockItem{
+			ProductID:         c.ProductID,
+			Title:             c.Title,
+			Reference:         c.Reference,
+			Quantity:          c.QuantityAfter,
+			LowStockThreshold: c.LowStockThreshold,
+		})
+	}
+	return items
+}
+
+func toProductStockResponse(stock repository.ProductStock) transport.ProductStockResponse {
+	return transport.ProductStockResponse{
+		ProductID:         stock.ProductID,
+		Title:             stock.Title,
+		Reference:         stock.Reference,
+		UpdatedAt:         stock.UpdatedAt.Format(time.RFC3339),
+		Quantity:          stock.Quantity,
+		LowStockThreshold: stock.LowStockThreshold,
+		InStock:           stock.Quantity > 0,
+		LowStock:          stock.Quantity <= stock.LowStockThreshold,
+	}
+}
+
+func toStockMovementResponse(movement repository.StockMovement) transport.StockMovementResponse {
+	return transport.StockMovementResponse{
+		ID:            movement.ID,
+		QuoteID:       movement.QuoteID,
+		CreatedBy:     movement.CreatedBy,
+		Note:          movement.Note,
+		Reason:        movement.Reason,
+		CreatedAt:     movement.CreatedAt.Format(time.RFC3339),
+		QuantityDelta: movement.QuantityDelta,
+		QuantityAfter: movement.QuantityAfter,
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"portal_final_backend/internal/catalog/repository"
+)
+
+func TestLowStockItemsReportsOnlyThresholdCrossings(t *testing.T) {
+	crossed, alreadyLow, stillFine := uuid.New(), uuid.New(), uuid.New()
+	consumed := []repository.StockConsumption{
+		{ProductID: crossed, Title: "Tegellijm", QuantityBefore: 12, QuantityAfter: 4, LowStockThreshold: 5},
+		{ProductID: alreadyLow, Title: "Voegmiddel", QuantityBefore: 3, QuantityAfter: 1, LowStockThreshold: 5},
+		{ProductID: stillFine, Title: "Primer", QuantityBefore: 20, QuantityAfter: 15, LowStockThreshold: 5},
+	}
+
+	items := lowStockItems(consumed)
+	if len(items) != 1 || items[0].ProductID != crossed {
+		t.Fatalf("expected only the product that crossed its threshold, got %+v", items)
+	}
+	if items[0].Quantity != 4 || items[0].LowStockThreshold != 5 {
+		t.Fatalf("expected quantity after consumption, got %+v", items[0])
+	}
+}
+
+func TestLowStockItemsReportsExactThreshold(t *testing.T) {
+	consumed := []repository.StockConsumption{
+		{ProductID: uuid.New(), QuantityBefore: 6, QuantityAfter: 5, LowStockThreshold: 5},
+	}
+
+	if items := lowStockItems(consumed); len(items) != 1 {
+		t.Fatalf("expected reaching the threshold to count as low stock, got %+v", items)
+	}
+}
+
+func TestToProductStockResponseFlagsStock(t *testing.T) {
+	resp := toProductStockResponse(repository.ProductStock{Quantity: 0, LowStockThreshold: 2})
+	if resp.InStock || !resp.LowStock {
+		t.Fatalf("expected empty stock to be out of stock and low, got %+v", resp)
+	}
+
+	resp = toProductStockResponse(repository.ProductStock{Quantity: 2.5, LowStockThreshold: 2})
+	if !resp.InStock || resp.LowStock {
+		t.Fatalf("expected stock above threshold to be in stock and not low, got %+v", resp)
+	}
+}
@@ -230,3 +230,174 @@ type ProductMaterialLinkInput struct {
 	MaterialID  uuid.UUID `json:"materialId" validate:"required"`
 	PricingMode string    `json:"pricingMode" validate:"required,oneof=included additional optional"`
 }
+
+// ─── Embedding Index ────────────────────────────────────────────────────────
+
+// EmbeddingVersionResponse counts the products indexed with one model version.
+type EmbeddingVersionResponse struct {
+	Model         string `json:"model"`
+	Version       string `json:"version"`
+	LastIndexedAt string `json:"lastIndexedAt"`
+	Products      int    `json:"products"`
+	IsCurrent     bool   `json:"isCurrent"`
+}
+
+// EmbeddingCoverageResponse reports index coverage and model drift of the catalog.
+type EmbeddingCoverageResponse struct {
+	Model           string                     `json:"model"`
+	Version         string                     `json:"version"`
+	Collection      string                     `json:"collection"`
+	Versions        []EmbeddingVersionResponse `json:"versions"`
+	CoveragePercent float64                    `json:"coveragePercent"`
+	TotalProducts   int                        `json:"totalProducts"`
+	Current         int                        `json:"current"`
+	Outdated        int                        `json:"outdated"`
+	Drifted         int                        `json:"drifted"`
+	Missing         int                        `json:"missing"`
+	Enabled         bool                       `json:"enabled"`
+}
+
+// ─── Price Feeds ────────────────────────────────────────────────────────────
+
+// PriceFeedRequest defines the payload for creating or replacing a supplier price feed.
+// Rows are matched to products on reference; skuField and priceField name the
+// CSV columns or JSON keys. An omitted apiToken keeps the stored one.
+type PriceFeedRequest struct {
+	SupplierName       string  `json:"supplierName" validate:"required,min=1,max=200"`
+	Format             string  `json:"format" validate:"required,oneof=csv edi api"`
+	SourceURL          string  `json:"sourceUrl" validate:"omitempty,url,max=1000"`
+	APIToken           *string `json:"apiToken,omitempty" validate:"omitempty,max=1000"`
+	SKUField           string  `json:"skuField" validate:"omitempty,max=100"`
+	PriceField         string  `json:"priceField" validate:"omitempty,max=100"`
+	CSVDelimiter       string  `json:"csvDelimiter" validate:"omitempty,len=1"`
+	FetchIntervalHours int     `json:"fetchIntervalHours" validate:"omitempty,min=1,max=720"`
+	AlertThresholdBps  *int    `json:"alertThresholdBps,omitempty" validate:"omitempty,min=0,max=100000"`
+	IsActive           *bool   `json:"isActive,omitempty"`
+}
+
+// PriceFeedResponse represents a supplier price feed in the API.
+type PriceFeedResponse struct {
+	ID                 uuid.UUID `json:"id"`
+	SupplierName       string    `json:"supplierName"`
+	Format             string    `json:"format"`
+	SourceURL          *string   `json:"sourceUrl,omitempty"`
+	SKUField           string    `json:"skuField"`
+	PriceField         string    `json:"priceField"`
+	CSVDelimiter       string    `json:"csvDelimiter"`
+	LastRunAt          *string   `json:"lastRunAt,omitempty"`
+	LastError          *string   `json:"lastError,omitempty"`
+	CreatedAt          string    `json:"createdAt"`
+	UpdatedAt          string    `json:"updatedAt"`
+	FetchIntervalHours int       `json:"fetchIntervalHours"`
+	AlertThresholdBps  int       `json:"alertThresholdBps"`
+	HasAPIToken        bool      `json:"hasApiToken"`
+	IsActive           bool      `json:"isActive"`
+}
+
+// PriceFeedRunResponse summarizes one import of a price feed.
+type PriceFeedRunResponse struct {
+	Rows      int `json:"rows"`
+	Matched   int `json:"matched"`
+	Unmatched int `json:"unmatched"`
+	Invalid   int `json:"invalid"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Alerts    int `json:"alerts"`
+}
+
+// PriceMarginRuleRequest defines the payload for setting the margin of a scope.
+// Leave feedId and productType empty for the organization default.
+type PriceMarginRuleRequest struct {
+	FeedID      *uuid.UUID `json:"feedId,omitempty"`
+	ProductType *string    `json:"productType,omitempty" validate:"omitempty,oneof=digital_service service product material"`
+	MarginBps   *int       `json:"marginBps" validate:"required,min=0,max=100000"`
+}
+
+// PriceMarginRuleResponse represents a margin rule in the API.
+type PriceMarginRuleResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	FeedID      *uuid.UUID `json:"feedId,omitempty"`
+	ProductType *string    `json:"productType,omitempty"`
+	CreatedAt   string     `json:"createdAt"`
+	UpdatedAt   string     `json:"updatedAt"`
+	MarginBps   int        `json:"marginBps"`
+}
+
+// ListPriceChangesRequest handles query parameters for the price change history.
+type ListPriceChangesRequest struct {
+	FeedID string `form:"feedId" validate:"omitempty,uuid"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// PriceChangeResponse represents one entry of the price change history.
+type PriceChangeResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	ProductID        uuid.UUID  `json:"productId"`
+	FeedID           *uuid.UUID `json:"feedId,omitempty"`
+	ProductTitle     string     `json:"productTitle"`
+	SupplierSKU      string     `json:"supplierSku"`
+	OldCostCents     *int64     `json:"oldCostCents,omitempty"`
+	ChangeBps        *int       `json:"changeBps,omitempty"`
+	CreatedAt        string     `json:"createdAt"`
+	NewCostCents     int64      `json:"newCostCents"`
+	OldPriceCents    int64      `json:"oldPriceCents"`
+	NewPriceCents    int64      `json:"newPriceCents"`
+	ExceedsThreshold bool       `json:"exceedsThreshold"`
+}
+
+// ─── Stock ─────────────────────────────────────────────────────────────────
+
+// SetProductStockRequest starts tracking the stock of a product or corrects the
+// counted quantity.
+type SetProductStockRequest struct {
+	Quantity          *float64 `json:"quantity" validate:"required"`
+	LowStockThreshold float64  `json:"lowStockThreshold" validate:"min=0"`
+}
+
+// AdjustProductStockRequest adds a relative stock change, e.g. a delivery (positive)
+// or breakage (negative).
+type AdjustProductStockRequest struct {
+	Delta float64 `json:"delta" validate:"required"`
+	Note  string  `json:"note" validate:"max=500"`
+}
+
+// ProductStockResponse represents the stock level of a tracked product.
+type ProductStockResponse struct {
+	ProductID         uuid.UUID `json:"productId"`
+	Title             string    `json:"title"`
+	Reference         string    `json:"reference"`
+	UpdatedAt         string    `json:"updatedAt"`
+	Quantity          float64   `json:"quantity"`
+	LowStockThreshold float64   `json:"lowStockThreshold"`
+	InStock           bool      `json:"inStock"`
+	LowStock          bool      `json:"lowStock"`
+}
+
+// StockMovementResponse represents one change of a product's stock level.
+type StockMovementResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	QuoteID       *uuid.UUID `json:"quoteId,omitempty"`
+	CreatedBy     *uuid.UUID `json:"createdBy,omitempty"`
+	Note          *string    `json:"note,omitempty"`
+	Reason        string     `json:"reason"`
+	CreatedAt     string     `json:"createdAt"`
+	QuantityDelta float64    `json:"quantityDelta"`
+	QuantityAfter float64    `json:"quantityAfter"`
+}
+
+// ProductStockDetailResponse is the stock level of a product with its recent movements.
+type ProductStockDetailResponse struct {
+	ProductStockResponse
+	Movements []StockMovementResponse `json:"movements"`
+}
+
+// StockSettingsRequest selects the event that consumes stock: the customer
+// accepting the quote, or the partner accepting the job offer.
+type StockSettingsRequest struct {
+	ConsumeOn string `json:"consumeOn" validate:"required,oneof=quote_accepted partner_offer_accepted"`
+}
+
+// StockSettingsResponse represents the stock settings of an organization.
+type StockSettingsResponse struct {
+	ConsumeOn string `json:"consumeOn"`
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/customfields/repository"
+	"portal_final_backend/internal/customfields/service"
+	"portal_final_backend/internal/customfields/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const msgInvalidRequest = "invalid request"
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+// RegisterRoutes registers reading the definitions and reading and editing
+// the values on leads and quotes.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/definitions", h.ListDefinitions)
+	rg.GET("/leads/:id", h.entityValues(repository.EntityLead, h.GetValues))
+	rg.PATCH("/leads/:id", h.entityValues(repository.EntityLead, h.UpdateValues))
+	rg.GET("/quotes/:id", h.entityValues(repository.EntityQuote, h.GetValues))
+	rg.PATCH("/quotes/:id", h.entityValues(repository.EntityQuote, h.UpdateValues))
+}
+
+// RegisterAdminRoutes registers managing the custom field definitions.
+func (h *Handler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.POST("/definitions", h.CreateDefinition)
+	rg.PUT("/definitions/:id", h.UpdateDefinition)
+	rg.DELETE("/definitions/:id", h.DeleteDefinition)
+}
+
+func (h *Handler) ListDefinitions(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListDefinitionsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListDefinitions(c.Request.Context(), tenantID, req.Entity)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) CreateDefinition(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.CreateDefinitionRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.CreateDefinition(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *Handler) UpdateDefinition(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.UpdateDefinitionRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.UpdateDefinition(c.Request.Context(), tenantID, id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) DeleteDefinition(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteDefinition(c.Request.Context(), tenantID, id); httpkit.HandleError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) GetValues(c *gin.Context, tenantID uuid.UUID, entity string, id uuid.UUID) {
+	resp, err := h.svc.GetValues(c.Request.Context(), tenantID, entity, id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) UpdateValues(c *gin.Context, tenantID uuid.UUID, entity string, id uuid.UUID) {
+	req, ok := httpkit.BindJSON[transport.UpdateValuesRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.UpdateValues(c.Request.Context(), tenantID, entity, id, req.Values)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// entityValues resolves the tenant and record of a values route for entity.
+func (h *Handler) entityValues(entity string, next func(c *gin.Context, tenantID uuid.UUID, entity string, id uuid.UUID)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := httpkit.RequireTenant(c)
+		if !ok {
+			return
+		}
+		id, ok := parseID(c)
+		if !ok {
+			return
+		}
+		next(c, tenantID, entity, id)
+	}
+}
+
+func parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, "invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
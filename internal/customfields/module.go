@@ -0,0 +1,44 @@
+// Package customfields lets organizations define their own fields on leads
+// and quotes. Values are validated against the definitions and stored on the
+// records, where search, list filters, webhooks and templates can use them.
+package customfields
+
+import (
+	"portal_final_backend/internal/customfields/handler"
+	"portal_final_backend/internal/customfields/repository"
+	"portal_final_backend/internal/customfields/service"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+}
+
+func NewModule(pool *pgxpool.Pool, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+	}
+}
+
+// Service returns the custom fields service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "customfields"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterRoutes(ctx.Protected.Group("/custom-fields"))
+	m.handler.RegisterAdminRoutes(ctx.Admin.Group("/custom-fields"))
+}
+
+var _ apphttp.Module = (*Module)(nil)
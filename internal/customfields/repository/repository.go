@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Entities custom fields can be defined on.
+const (
+	EntityLead  = "lead"
+	EntityQuote = "quote"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrDuplicateKey = errors.New("duplicate key")
+)
+
+// entityTables maps an entity to the table holding its custom_fields column.
+var entityTables = map[string]string{
+	EntityLead:  "RAC_leads",
+	EntityQuote: "RAC_quotes",
+}
+
+// Repository persists custom field definitions and the values stored on
+// leads and quotes.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Definition describes a custom field of an organization.
+type Definition struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Entity         string
+	Key            string
+	Label          string
+	Type           string
+	Options        []string
+	Required       bool
+	MaxLength      *int
+	MinValue       *float64
+	MaxValue       *float64
+	Position       int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+const definitionColumns = `id, organization_id, entity, field_key, label, field_type, options, required, max_length, min_value, max_value, position, created_at, updated_at`
+
+func scanDefinition(row pgx.Row) (Definition, error) {
+	var d Definition
+	err := row.Scan(&d.ID, &d.OrganizationID, &d.Entity, &d.Key, &d.Label, &d.Type, &d.Options, &d.Required,
+		&d.MaxLength, &d.MinValue, &d.MaxValue, &d.Position, &d.CreatedAt, &d.UpdatedAt)
+	return d, err
+}
+
+// ListDefinitions returns the custom fields of an organization in display
+// order, limited to entity when it is set.
+func (r *Repository) ListDefinitions(ctx context.Context, organizationID uuid.UUID, entity string) ([]Definition, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+definitionColumns+`
+		FROM RAC_custom_field_definitions
+		WHERE organization_id = $1 AND ($2 = '' OR entity = $2)
+		ORDER BY entity, position, created_at`, organizationID, entity,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list custom field definitions: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Definition, 0)
+	for rows.Next() {
+		d, err := scanDefinition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan custom field definition: %w", err)
+		}
+		items = append(items, d)
+	}
+	return items, rows.Err()
+}
+
+// GetDefinition returns one custom field of an organization.
+func (r *Repository) GetDefinition(ctx context.Context, id, organizationID uuid.UUID) (Definition, error) {
+	d, err := scanDefinition(r.pool.QueryRow(ctx, `
+		SELECT `+definitionColumns+`
+		FROM RAC_custom_field_definitions
+		WHERE id = $1 AND organization_id = $2`, id, organizationID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Definition{}, ErrNotFound
+	}
+	if err != nil {
+		return Definition{}, fmt.Errorf("get custom field definition: %w", err)
+	}
+	return d, nil
+}
+
+// CreateDefinition stores a new custom field. It returns ErrDuplicateKey when
+// the entity already has a field with the same key.
+func (r *Repository) CreateDefinition(ctx context.Context, d Definition) (Definition, error) {
+	stored, err := scanDefinition(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_custom_field_definitions (organization_id, entity, field_key, label, field_type, options, required, max_length, min_value, max_value, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING `+definitionColumns,
+		d.OrganizationID, d.Entity, d.Key, d.Label, d.Type, d.Options, d.Required, d.MaxLength, d.MinValue, d.MaxValue, d.Position,
+	))
+	if isUniqueViolation(err) {
+		return Definition{}, ErrDuplicateKey
+	}
+	if err != nil {
+		return Definition{}, fmt.Errorf("create custom field definition: %w", err)
+	}
+	return stored, nil
+}
+
+// UpdateDefinition replaces the label, validation and position of a custom
+// field. Its entity, key and type are fixed once values may exist.
+func (r *Repository) UpdateDefinition(ctx context.Context, d Definition) (Definition, error) {
+	stored, err := scanDefinition(r.pool.QueryRow(ctx, `
+		UPDATE RAC_custom_field_definitions
+		SET label = $3, options = $4, required = $5, max_length = $6, min_value = $7, max_value = $8, position = $9, updated_at = now()
+		WHERE id = $1 AND organization_id = $2
+		RETURNING `+definitionColumns,
+		d.ID, d.OrganizationID, d.Label, d.Options, d.Required, d.MaxLength, d.MinValue, d.MaxValue, d.Position,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Definition{}, ErrNotFound
+	}
+	if err != nil {
+		return Definition{}, fmt.Errorf("update custom field definition: %w", err)
+	}
+	return stored, nil
+}
+
+// DeleteDefinition removes a custom field. Stored values are kept on the
+// leads and quotes until they are next edited.
+func (r *Repository) DeleteDefinition(ctx context.Context, id, organizationID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_custom_field_definitions
+		WHERE id = $1 AND organization_id = $2`, id, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete custom field definition: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetValues returns the custom field values of a lead or quote.
+func (r *Repository) GetValues(ctx context.Context, entity string, id, organizationID uuid.UUID) (map[string]any, error) {
+	table, ok := entityTables[entity]
+	if !ok {
+		return nil, fmt.Errorf("unknown custom field entity %q", entity)
+	}
+	var raw []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT custom_fields FROM `+table+`
+		WHERE id = $1 AND organization_id = $2`, id, organizationID,
+	).Scan(&raw)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get custom field values: %w", err)
+	}
+	values := map[string]any{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("decode custom field values: %w", err)
+	}
+	return values, nil
+}
+
+// SetValues replaces the custom field values of a lead or quote.
+func (r *Repository) SetValues(ctx context.Context, entity string, id, organizationID uuid.UUID, values map[string]any) error {
+	table, ok := entityTables[entity]
+	if !ok {
+		return fmt.Errorf("unknown custom field entity %q", entity)
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("encode custom field values: %w", err)
+	}
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE `+table+`
+		SET custom_fields = $3, updated_at = now()
+		WHERE id = $1 AND organization_id = $2`, id, organizationID, raw,
+	)
+	if err != nil {
+		return fmt.Errorf("set custom field values: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,260 @@
+// Package service manages organization-defined custom fields on leads and
+// quotes: their definitions and the validated values stored on each record.
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"portal_final_backend/internal/customfields/repository"
+	"portal_final_backend/internal/customfields/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+// Field types.
+const (
+	TypeText    = "text"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeDate    = "date"
+	TypeSelect  = "select"
+)
+
+const (
+	msgDefinitionNotFound = "custom field not found"
+	msgInvalidValues      = "invalid custom field values"
+)
+
+var fieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+type Repository interface {
+	ListDefinitions(ctx context.Context, organizationID uuid.UUID, entity string) ([]repository.Definition, error)
+	GetDefinition(ctx context.Context, id, organizationID uuid.UUID) (repository.Definition, error)
+	CreateDefinition(ctx context.Context, d repository.Definition) (repository.Definition, error)
+	UpdateDefinition(ctx context.Context, d repository.Definition) (repository.Definition, error)
+	DeleteDefinition(ctx context.Context, id, organizationID uuid.UUID) error
+	GetValues(ctx context.Context, entity string, id, organizationID uuid.UUID) (map[string]any, error)
+	SetValues(ctx context.Context, entity string, id, organizationID uuid.UUID, values map[string]any) error
+}
+
+type Service struct {
+	repo Repository
+	log  *logger.Logger
+}
+
+func New(repo Repository, log *logger.Logger) *Service {
+	return &Service{repo: repo, log: log}
+}
+
+// ListDefinitions returns the custom fields of an organization, limited to
+// entity when it is set.
+func (s *Service) ListDefinitions(ctx context.Context, organizationID uuid.UUID, entity string) (transport.DefinitionListResponse, error) {
+	defs, err := s.repo.ListDefinitions(ctx, organizationID, entity)
+	if err != nil {
+		return transport.DefinitionListResponse{}, err
+	}
+	resp := transport.DefinitionListResponse{Definitions: make([]transport.DefinitionResponse, 0, len(defs))}
+	for _, d := range defs {
+		resp.Definitions = append(resp.Definitions, toDefinitionResponse(d))
+	}
+	return resp, nil
+}
+
+func (s *Service) CreateDefinition(ctx context.Context, organizationID uuid.UUID, req transport.CreateDefinitionRequest) (transport.DefinitionResponse, error) {
+	d := repository.Definition{
+		OrganizationID: organizationID,
+		Entity:         req.Entity,
+		Key:            strings.TrimSpace(req.Key),
+		Label:          strings.TrimSpace(req.Label),
+		Type:           req.Type,
+		Options:        normalizeOptions(req.Options),
+		Required:       req.Required,
+		MaxLength:      req.MaxLength,
+		MinValue:       req.MinValue,
+		MaxValue:       req.MaxValue,
+		Position:       req.Position,
+	}
+	if !fieldKeyPattern.MatchString(d.Key) {
+		return transport.DefinitionResponse{}, apperr.Validation("key must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	}
+	if err := validateDefinition(d); err != nil {
+		return transport.DefinitionResponse{}, err
+	}
+
+	stored, err := s.repo.CreateDefinition(ctx, d)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return transport.DefinitionResponse{}, apperr.Conflict("a custom field with this key already exists")
+	}
+	if err != nil {
+		return transport.DefinitionResponse{}, err
+	}
+	return toDefinitionResponse(stored), nil
+}
+
+func (s *Service) UpdateDefinition(ctx context.Context, organizationID, id uuid.UUID, req transport.UpdateDefinitionRequest) (transport.DefinitionResponse, error) {
+	d, err := s.repo.GetDefinition(ctx, id, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.DefinitionResponse{}, apperr.NotFound(msgDefinitionNotFound)
+	}
+	if err != nil {
+		return transport.DefinitionResponse{}, err
+	}
+
+	d.Label = strings.TrimSpace(req.Label)
+	d.Options = normalizeOptions(req.Options)
+	d.Required = req.Required
+	d.MaxLength = req.MaxLength
+	d.MinValue = req.MinValue
+	d.MaxValue = req.MaxValue
+	d.Position = req.Position
+	if err := validateDefinition(d); err != nil {
+		return transport.DefinitionResponse{}, err
+	}
+
+	stored, err := s.repo.UpdateDefinition(ctx, d)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.DefinitionResponse{}, apperr.NotFound(msgDefinitionNotFound)
+	}
+	if err != nil {
+		return transport.DefinitionResponse{}, err
+	}
+	return toDefinitionResponse(stored), nil
+}
+
+func (s *Service) DeleteDefinition(ctx context.Context, organizationID, id uuid.UUID) error {
+	err := s.repo.DeleteDefinition(ctx, id, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.NotFound(msgDefinitionNotFound)
+	}
+	return err
+}
+
+// GetValues returns the custom field values of a lead or quote.
+func (s *Service) GetValues(ctx context.Context, organizationID uuid.UUID, entity string, id uuid.UUID) (transport.ValuesResponse, error) {
+	values, err := s.repo.GetValues(ctx, entity, id, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.ValuesResponse{}, apperr.NotFound(entity + " not found")
+	}
+	if err != nil {
+		return transport.ValuesResponse{}, err
+	}
+	return transport.ValuesResponse{Values: values}, nil
+}
+
+// UpdateValues validates changed custom field values against the
+// organization's definitions and stores them on a lead or quote. Values of
+// fields that no longer have a definition are dropped.
+func (s *Service) UpdateValues(ctx context.Context, organizationID uuid.UUID, entity string, id uuid.UUID, changes map[string]any) (transport.ValuesResponse, error) {
+	defs, err := s.repo.ListDefinitions(ctx, organizationID, entity)
+	if err != nil {
+		return transport.ValuesResponse{}, err
+	}
+	current, err := s.repo.GetValues(ctx, entity, id, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.ValuesResponse{}, apperr.NotFound(entity + " not found")
+	}
+	if err != nil {
+		return transport.ValuesResponse{}, err
+	}
+
+	values, err := MergeValues(defs, current, changes)
+	if err != nil {
+		return transport.ValuesResponse{}, err
+	}
+	if err := s.repo.SetValues(ctx, entity, id, organizationID, values); err != nil {
+		return transport.ValuesResponse{}, err
+	}
+	return transport.ValuesResponse{Values: values}, nil
+}
+
+// ApplyLeadInput stores custom field values received as text, such as mapped
+// webhook form fields, on a lead. Values that cannot be converted to their
+// field's type are skipped so that an inbound lead is never rejected for them.
+func (s *Service) ApplyLeadInput(ctx context.Context, organizationID, leadID uuid.UUID, input map[string]string) error {
+	if len(input) == 0 {
+		return nil
+	}
+	defs, err := s.repo.ListDefinitions(ctx, organizationID, repository.EntityLead)
+	if err != nil {
+		return err
+	}
+	current, err := s.repo.GetValues(ctx, repository.EntityLead, leadID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	values, skipped := CoerceValues(defs, input)
+	if len(skipped) > 0 {
+		s.log.Warn("custom fields: skipped lead input values", "leadId", leadID, "orgId", organizationID, "fields", skipped)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	for key, value := range values {
+		current[key] = value
+	}
+	return s.repo.SetValues(ctx, repository.EntityLead, leadID, organizationID, current)
+}
+
+func validateDefinition(d repository.Definition) error {
+	if d.Type == TypeSelect && len(d.Options) == 0 {
+		return apperr.Validation("select fields need at least one option")
+	}
+	if d.Type != TypeSelect && len(d.Options) > 0 {
+		return apperr.Validation("options are only allowed on select fields")
+	}
+	if d.MaxLength != nil && d.Type != TypeText {
+		return apperr.Validation("maxLength is only allowed on text fields")
+	}
+	if (d.MinValue != nil || d.MaxValue != nil) && d.Type != TypeNumber {
+		return apperr.Validation("minValue and maxValue are only allowed on number fields")
+	}
+	if d.MinValue != nil && d.MaxValue != nil && *d.MinValue > *d.MaxValue {
+		return apperr.Validation("minValue must be <= maxValue")
+	}
+	return nil
+}
+
+func normalizeOptions(options []string) []string {
+	out := make([]string, 0, len(options))
+	seen := make(map[string]struct{}, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		if _, ok := seen[strings.ToLower(option)]; ok {
+			continue
+		}
+		seen[strings.ToLower(option)] = struct{}{}
+		out = append(out, option)
+	}
+	return out
+}
+
+func toDefinitionResponse(d repository.Definition) transport.DefinitionResponse {
+	options := d.Options
+	if options == nil {
+		options = []string{}
+	}
+	return transport.DefinitionResponse{
+		ID:        d.ID.String(),
+		Entity:    d.Entity,
+		Key:       d.Key,
+		Label:     d.Label,
+		Type:      d.Type,
+		Options:   options,
+		Required:  d.Required,
+		MaxLength: d.MaxLength,
+		MinValue:  d.MinValue,
+		MaxValue:  d.MaxValue,
+		Position:  d.Position,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"portal_final_backend/internal/customfields/repository"
+	"portal_final_backend/platform/apperr"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// defaultMaxTextLength bounds text fields without their own maxLength.
+	defaultMaxTextLength = 1000
+)
+
+// inputDateLayouts are the date notations accepted from text input.
+var inputDateLayouts = []string{dateLayout, "02-01-2006", "02/01/2006", "2/1/2006"}
+
+// MergeValues applies changes to the current values of a record and validates
+// the result against defs. A nil change clears the field. It returns an
+// apperr validation error that lists every invalid field.
+func MergeValues(defs []repository.Definition, current, changes map[string]any) (map[string]any, error) {
+	byKey := make(map[string]repository.Definition, len(defs))
+	for _, d := range defs {
+		byKey[d.Key] = d
+	}
+
+	problems := map[string]string{}
+	values := make(map[string]any, len(defs))
+	for key, value := range current {
+		if _, ok := byKey[key]; ok && value != nil {
+			values[key] = value
+		}
+	}
+	for key, value := range changes {
+		d, ok := byKey[key]
+		if !ok {
+			problems[key] = "unknown field"
+			continue
+		}
+		normalized, err := normalizeValue(d, value)
+		if err != nil {
+			problems[key] = err.Error()
+			continue
+		}
+		if normalized == nil {
+			delete(values, key)
+			continue
+		}
+		values[key] = normalized
+	}
+	for _, d := range defs {
+		if _, ok := values[d.Key]; d.Required && !ok {
+			if _, reported := problems[d.Key]; !reported {
+				problems[d.Key] = "is required"
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, apperr.Validation(msgInvalidValues).WithDetails(problems)
+	}
+	return values, nil
+}
+
+// CoerceValues converts text input to the types of the matching definitions.
+// It returns the converted values and the sorted keys of the input that has
+// no definition or could not be converted.
+func CoerceValues(defs []repository.Definition, input map[string]string) (map[string]any, []string) {
+	byKey := make(map[string]repository.Definition, len(defs))
+	for _, d := range defs {
+		byKey[d.Key] = d
+	}
+
+	values := make(map[string]any, len(input))
+	var skipped []string
+	for key, raw := range input {
+		d, ok := byKey[key]
+		if !ok {
+			skipped = append(skipped, key)
+			continue
+		}
+		value, err := coerceText(d, raw)
+		if err == nil {
+			value, err = normalizeValue(d, value)
+		}
+		if err != nil {
+			skipped = append(skipped, key)
+			continue
+		}
+		if value != nil {
+			values[key] = value
+		}
+	}
+	sort.Strings(skipped)
+	return values, skipped
+}
+
+// normalizeValue checks a JSON value against a definition and returns it in
+// its stored form. Empty values normalize to nil.
+func normalizeValue(d repository.Definition, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch d.Type {
+	case TypeText:
+		text, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be text")
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, nil
+		}
+		maxLength := defaultMaxTextLength
+		if d.MaxLength != nil {
+			maxLength = *d.MaxLength
+		}
+		if utf8.RuneCountInString(text) > maxLength {
+			return nil, fmt.Errorf("must be at most %d characters", maxLength)
+		}
+		return text, nil
+	case TypeNumber:
+		number, ok := value.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, errors.New("must be a number")
+		}
+		if d.MinValue != nil && number < *d.MinValue {
+			return nil, fmt.Errorf("must be at least %v", *d.MinValue)
+		}
+		if d.MaxValue != nil && number > *d.MaxValue {
+			return nil, fmt.Errorf("must be at most %v", *d.MaxValue)
+		}
+		return number, nil
+	case TypeBoolean:
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("must be true or false")
+		}
+		return flag, nil
+	case TypeDate:
+		text, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a date (YYYY-MM-DD)")
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, nil
+		}
+		date, err := time.Parse(dateLayout, text)
+		if err != nil {
+			return nil, errors.New("must be a date (YYYY-MM-DD)")
+		}
+		return date.Format(dateLayout), nil
+	case TypeSelect:
+		text, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be one of the options")
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, nil
+		}
+		for _, option := range d.Options {
+			if strings.EqualFold(option, text) {
+				return option, nil
+			}
+		}
+		return nil, errors.New("must be one of the options")
+	default:
+		return nil, fmt.Errorf("has unsupported type %q", d.Type)
+	}
+}
+
+// coerceText converts text input to the JSON type of a definition.
+func coerceText(d repository.Definition, raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	switch d.Type {
+	case TypeNumber:
+		number, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", "."), 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return number, nil
+	case TypeBoolean:
+		switch strings.ToLower(raw) {
+		case "true", "1", "yes", "ja", "on", "y", "j":
+			return true, nil
+		case "false", "0", "no", "nee", "off", "n":
+			return false, nil
+		}
+		return nil, errors.New("must be true or false")
+	case TypeDate:
+		for _, layout := range inputDateLayouts {
+			if date, err := time.Parse(layout, raw); err == nil {
+				return date.Format(dateLayout), nil
+			}
+		}
+		return nil, errors.New("must be a date")
+	default:
+		return raw, nil
+	}
+}
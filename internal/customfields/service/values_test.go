@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"portal_final_backend/internal/customfields/repository"
+	"portal_final_backend/platform/apperr"
+)
+
+func testDefinitions() []repository.Definition {
+	maxRooms := 10.0
+	return []repository.Definition{
+		{Key: "roof_type", Type: TypeSelect, Options: []string{"Flat", "Pitched"}, Required: true},
+		{Key: "rooms", Type: TypeNumber, MaxValue: &maxRooms},
+		{Key: "monument", Type: TypeBoolean},
+		{Key: "built_on", Type: TypeDate},
+	}
+}
+
+func TestMergeValuesNormalizesAndClears(t *testing.T) {
+	current := map[string]any{"roof_type": "Flat", "rooms": 3.0, "removed_field": "x"}
+	changes := map[string]any{"roof_type": "pitched", "rooms": nil, "monument": true}
+
+	got, err := MergeValues(testDefinitions(), current, changes)
+	if err != nil {
+		t.Fatalf("MergeValues() error = %v", err)
+	}
+	want := map[string]any{"roof_type": "Pitched", "monument": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MergeValues() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeValuesReportsEveryInvalidField(t *testing.T) {
+	changes := map[string]any{"roof_type": nil, "rooms": 11.0, "built_on": "01-02-2024", "colour": "red"}
+
+	_, err := MergeValues(testDefinitions(), nil, changes)
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) {
+		t.Fatalf("MergeValues() error = %v, want validation error", err)
+	}
+	problems, _ := appErr.Details.(map[string]string)
+	for _, key := range []string{"roof_type", "rooms", "built_on", "colour"} {
+		if _, ok := problems[key]; !ok {
+			t.Errorf("missing problem for %q in %v", key, problems)
+		}
+	}
+}
+
+func TestCoerceValuesConvertsTextInput(t *testing.T) {
+	input := map[string]string{
+		"roof_type": "flat",
+		"rooms":     "2,5",
+		"monument":  "ja",
+		"built_on":  "15-03-1930",
+		"colour":    "red",
+		"unknown":   "x",
+	}
+
+	got, skipped := CoerceValues(testDefinitions(), input)
+	want := map[string]any{"roof_type": "Flat", "rooms": 2.5, "monument": true, "built_on": "1930-03-15"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CoerceValues() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(skipped, []string{"colour", "unknown"}) {
+		t.Fatalf("skipped = %v", skipped)
+	}
+}
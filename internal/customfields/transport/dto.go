@@ -0,0 +1,65 @@
+// Package transport provides DTOs for organization-defined custom fields.
+package transport
+
+import "time"
+
+// CreateDefinitionRequest defines a new custom field. Options list the
+// allowed values of select fields.
+type CreateDefinitionRequest struct {
+	Entity    string   `json:"entity" validate:"required,oneof=lead quote"`
+	Key       string   `json:"key" validate:"required,min=1,max=50"`
+	Label     string   `json:"label" validate:"required,min=1,max=100"`
+	Type      string   `json:"type" validate:"required,oneof=text number boolean date select"`
+	Options   []string `json:"options" validate:"max=100,dive,required,max=100"`
+	Required  bool     `json:"required"`
+	MaxLength *int     `json:"maxLength" validate:"omitempty,gte=1,lte=5000"`
+	MinValue  *float64 `json:"minValue"`
+	MaxValue  *float64 `json:"maxValue"`
+	Position  int      `json:"position" validate:"gte=0"`
+}
+
+// UpdateDefinitionRequest replaces the label and validation of a custom
+// field. The entity, key and type cannot change.
+type UpdateDefinitionRequest struct {
+	Label     string   `json:"label" validate:"required,min=1,max=100"`
+	Options   []string `json:"options" validate:"max=100,dive,required,max=100"`
+	Required  bool     `json:"required"`
+	MaxLength *int     `json:"maxLength" validate:"omitempty,gte=1,lte=5000"`
+	MinValue  *float64 `json:"minValue"`
+	MaxValue  *float64 `json:"maxValue"`
+	Position  int      `json:"position" validate:"gte=0"`
+}
+
+type ListDefinitionsRequest struct {
+	Entity string `form:"entity" validate:"omitempty,oneof=lead quote"`
+}
+
+type DefinitionResponse struct {
+	ID        string    `json:"id"`
+	Entity    string    `json:"entity"`
+	Key       string    `json:"key"`
+	Label     string    `json:"label"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options"`
+	Required  bool      `json:"required"`
+	MaxLength *int      `json:"maxLength,omitempty"`
+	MinValue  *float64  `json:"minValue,omitempty"`
+	MaxValue  *float64  `json:"maxValue,omitempty"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type DefinitionListResponse struct {
+	Definitions []DefinitionResponse `json:"definitions"`
+}
+
+// UpdateValuesRequest changes the custom field values of a lead or quote. A
+// null value clears the field; fields that are left out keep their value.
+type UpdateValuesRequest struct {
+	Values map[string]any `json:"values" validate:"required,max=100"`
+}
+
+type ValuesResponse struct {
+	Values map[string]any `json:"values"`
+}
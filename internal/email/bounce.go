@@ -0,0 +1,18 @@
+package email
+
+import (
+	"errors"
+
+	gomail "github.com/wneessen/go-mail"
+)
+
+// IsHardBounce reports whether a send failed because the mail server
+// permanently rejected the recipient, so retrying or mailing the address
+// again is pointless.
+func IsHardBounce(err error) bool {
+	var sendErr *gomail.SendError
+	if !errors.As(err, &sendErr) {
+		return false
+	}
+	return sendErr.Reason == gomail.ErrSMTPRcptTo && !sendErr.IsTemp()
+}
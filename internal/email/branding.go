@@ -0,0 +1,103 @@
+package email
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Branding customizes outgoing emails for an organization. Empty fields keep
+// the platform defaults. Colors must be validated #RRGGBB values and the
+// header and footer must be sanitized HTML; they are inserted verbatim.
+type Branding struct {
+	PrimaryColor string
+	AccentColor  string
+	HeaderHTML   string
+	FooterHTML   string
+	SenderName   string
+}
+
+func (b Branding) isZero() bool {
+	return b == Branding{}
+}
+
+// WithBranding returns a copy of sender that applies branding to every email
+// it sends. Senders that do not support branding are returned unchanged.
+func WithBranding(sender Sender, branding Branding) Sender {
+	if branding.isZero() {
+		return sender
+	}
+	switch s := sender.(type) {
+	case *brevoSender:
+		branded := *s
+		branded.branding = branding
+		return &branded
+	case *SMTPSender:
+		branded := *s
+		branded.branding = branding
+		return &branded
+	default:
+		return sender
+	}
+}
+
+// fromName returns the sender display name, preferring the branded one.
+func (b Branding) fromName(fallback string) string {
+	if b.SenderName != "" {
+		return b.SenderName
+	}
+	return fallback
+}
+
+// apply inserts the branding into a rendered email. Full documents get the
+// color overrides in their head and the header and footer inside the body;
+// HTML fragments, such as custom emails, are wrapped in the header and footer.
+func (b Branding) apply(html string) string {
+	if b.isZero() {
+		return html
+	}
+
+	if style := b.styleOverrides(); style != "" {
+		if idx := strings.Index(strings.ToLower(html), "</head>"); idx >= 0 {
+			html = html[:idx] + style + html[idx:]
+		}
+	}
+
+	if b.HeaderHTML != "" {
+		header := `<div class="brand-header">` + b.HeaderHTML + `</div>`
+		if start := strings.Index(strings.ToLower(html), "<body"); start >= 0 {
+			if end := strings.Index(html[start:], ">"); end >= 0 {
+				pos := start + end + 1
+				html = html[:pos] + header + html[pos:]
+			}
+		} else {
+			html = header + html
+		}
+	}
+
+	if b.FooterHTML != "" {
+		footer := `<div class="brand-footer">` + b.FooterHTML + `</div>`
+		if idx := strings.LastIndex(strings.ToLower(html), "</body>"); idx >= 0 {
+			html = html[:idx] + footer + html[idx:]
+		} else {
+			html += footer
+		}
+	}
+
+	return html
+}
+
+// styleOverrides recolors the header section and call-to-action button of the
+// base template.
+func (b Branding) styleOverrides() string {
+	var rules strings.Builder
+	if b.PrimaryColor != "" {
+		fmt.Fprintf(&rules, ".header-section { background-color: %s !important; }", b.PrimaryColor)
+	}
+	if b.AccentColor != "" {
+		fmt.Fprintf(&rules, ".button-brutal { background-color: %s !important; }", b.AccentColor)
+	}
+	if rules.Len() == 0 {
+		return ""
+	}
+	return "<style>" + rules.String() + "</style>"
+}
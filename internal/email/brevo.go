@@ -23,7 +23,7 @@ type Attachment struct {
 type Sender interface {
 	SendVerificationEmail(ctx context.Context, toEmail, verifyURL string) error
 	SendPasswordResetEmail(ctx context.Context, toEmail, resetURL string) error
-	SendVisitInviteEmail(ctx context.Context, toEmail, consumerName, scheduledDate, address string) error
+	SendVisitInviteEmail(ctx context.Context, toEmail, consumerName, scheduledDate, address string, attachments ...Attachment) error
 	SendOrganizationInviteEmail(ctx context.Context, toEmail, organizationName, inviteURL string) error
 	SendPartnerInviteEmail(ctx context.Context, toEmail, organizationName, partnerName, inviteURL string) error
 	SendQuoteProposalEmail(ctx context.Context, toEmail, consumerName, organizationName, quoteNumber, proposalURL string) error
@@ -76,6 +76,7 @@ type brevoSender struct {
 	apiKey    string
 	fromName  string
 	fromEmail string
+	branding  Branding
 }
 
 type brevoAttachment struct {
@@ -137,11 +138,11 @@ func (b *brevoSender) SendPasswordResetEmail(ctx context.Context, to, url string
 	})
 }
 
-func (b *brevoSender) SendVisitInviteEmail(ctx context.Context, to, name, date, addr string) error {
+func (b *brevoSender) SendVisitInviteEmail(ctx context.Context, to, name, date, addr string, atts ...Attachment) error {
 	return b.renderAndSend(ctx, to, subjectVisitInvite, "visit_invite.html", visitInviteEmailData{
 		baseEmailData: baseEmailData{Title: "Bezoek ingepland", Heading: "Bezoek ingepland"},
 		ConsumerName:  name, ScheduledDate: date, Address: addr,
-	})
+	}, atts...)
 }
 
 func (b *brevoSender) SendOrganizationInviteEmail(ctx context.Context, to, org, url string) error {
@@ -217,9 +218,9 @@ func (b *brevoSender) SendDailyDigestEmail(ctx context.Context, to string, data
 func (b *brevoSender) send(ctx context.Context, to, subject, html string, atts ...Attachment) error {
 	payload := brevoEmailRequest{
 		Subject:     subject,
-		HTMLContent: html,
+		HTMLContent: b.branding.apply(html),
 	}
-	payload.Sender.Name, payload.Sender.Email = b.fromName, b.fromEmail
+	payload.Sender.Name, payload.Sender.Email = b.branding.fromName(b.fromName), b.fromEmail
 	payload.To = []struct {
 		Email string `json:"email"`
 	}{{Email: to}}
@@ -267,7 +268,7 @@ type NoopSender struct{}
 
 func (NoopSender) SendVerificationEmail(context.Context, string, string) error  { return nil }
 func (NoopSender) SendPasswordResetEmail(context.Context, string, string) error { return nil }
-func (NoopSender) SendVisitInviteEmail(context.Context, string, string, string, string) error {
+func (NoopSender) SendVisitInviteEmail(context.Context, string, string, string, string, ...Attachment) error {
 	return nil
 }
 func (NoopSender) SendOrganizationInviteEmail(context.Context, string, string, string) error {
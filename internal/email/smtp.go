@@ -12,12 +12,13 @@ import (
 
 // SMTPSender reordered for memory alignment.
 type SMTPSender struct {
-	host      string // 16 bytes
-	username  string // 16 bytes
-	password  string // 16 bytes
-	fromName  string // 16 bytes
-	fromEmail string // 16 bytes
-	port      int    // 8 bytes
+	host      string   // 16 bytes
+	username  string   // 16 bytes
+	password  string   // 16 bytes
+	fromName  string   // 16 bytes
+	fromEmail string   // 16 bytes
+	branding  Branding // 80 bytes
+	port      int      // 8 bytes
 }
 
 // NewSMTPSender creates a new SMTPSender with the given SMTP credentials.
@@ -59,11 +60,11 @@ func (s *SMTPSender) SendPasswordResetEmail(ctx context.Context, to, url string)
 	})
 }
 
-func (s *SMTPSender) SendVisitInviteEmail(ctx context.Context, to, name, date, addr string) error {
+func (s *SMTPSender) SendVisitInviteEmail(ctx context.Context, to, name, date, addr string, atts ...Attachment) error {
 	return s.renderAndSend(ctx, to, subjectVisitInvite, "visit_invite.html", visitInviteEmailData{
 		baseEmailData: baseEmailData{Title: "Bezoek ingepland", Heading: "Bezoek ingepland"},
 		ConsumerName:  name, ScheduledDate: date, Address: addr,
-	})
+	}, atts...)
 }
 
 func (s *SMTPSender) SendOrganizationInviteEmail(ctx context.Context, to, org, url string) error {
@@ -138,14 +139,14 @@ func (s *SMTPSender) SendDailyDigestEmail(ctx context.Context, to string, data D
 // Complexity: O(A) where A is the number of attachments.
 func (s *SMTPSender) send(ctx context.Context, to, subject, html string, atts ...Attachment) error {
 	msg := gomail.NewMsg()
-	if err := msg.FromFormat(s.fromName, s.fromEmail); err != nil {
+	if err := msg.FromFormat(s.branding.fromName(s.fromName), s.fromEmail); err != nil {
 		return fmt.Errorf("smtp from: %w", err)
 	}
 	if err := msg.To(to); err != nil {
 		return fmt.Errorf("smtp to: %w", err)
 	}
 	msg.Subject(subject)
-	msg.SetBodyString(gomail.TypeTextHTML, html)
+	msg.SetBodyString(gomail.TypeTextHTML, s.branding.apply(html))
 
 	for _, att := range atts {
 		// AttachReader is O(1) space as it streams the bytes.NewReader into the msg buffer.
@@ -0,0 +1,147 @@
+package eventoutbox
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultInterval  = time.Second
+	defaultBatchSize = 50
+	claimLease       = 5 * time.Minute
+	maxAttempts      = 20
+	maxRetryDelay    = time.Hour
+	pruneInterval    = time.Hour
+	retention        = 30 * 24 * time.Hour
+)
+
+// Publisher delivers a decoded event to its handlers and reports whether all
+// of them succeeded. events.Bus satisfies it; a broker adapter can too.
+type Publisher interface {
+	PublishSync(ctx context.Context, event events.Event) error
+}
+
+// Dispatcher publishes committed outbox events. Delivery is at least once:
+// handlers run through the guard of NewHandlerGuard to skip redeliveries.
+type Dispatcher struct {
+	repo      *Repository
+	publisher Publisher
+	registry  *Registry
+	log       *logger.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+func NewDispatcher(repo *Repository, publisher Publisher, registry *Registry, log *logger.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:      repo,
+		publisher: publisher,
+		registry:  registry,
+		log:       log,
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+	}
+}
+
+// Run dispatches pending events until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		for {
+			n, err := d.DispatchPending(ctx)
+			if err != nil {
+				d.log.Warn("failed to dispatch domain events", "error", err)
+			}
+			if err != nil || n < d.batchSize {
+				break
+			}
+		}
+		if time.Since(lastPrune) >= pruneInterval {
+			if err := d.repo.Prune(ctx, time.Now().Add(-retention)); err != nil {
+				d.log.Warn("failed to prune domain events", "error", err)
+			}
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending publishes one batch of due events and returns its size.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	records, err := d.repo.Claim(ctx, d.batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+	for _, rec := range records {
+		d.dispatch(ctx, rec)
+	}
+	return len(records), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, rec Record) {
+	event, err := d.registry.Decode(rec.EventName, rec.Payload)
+	if err == nil {
+		err = d.publisher.PublishSync(events.WithEventID(db.WithSchema(ctx, rec.Schema), rec.ID), event)
+	}
+	if err == nil {
+		if markErr := d.repo.MarkPublished(ctx, rec.ID); markErr != nil {
+			d.log.Warn("failed to mark domain event published", "eventId", rec.ID, "error", markErr)
+		}
+		return
+	}
+
+	dead := rec.Attempts >= maxAttempts
+	if dead {
+		d.log.Error("giving up domain event", "eventId", rec.ID, "event", rec.EventName, "attempts", rec.Attempts, "error", err)
+	} else {
+		d.log.Warn("domain event delivery failed", "eventId", rec.ID, "event", rec.EventName, "attempts", rec.Attempts, "error", err)
+	}
+	if markErr := d.repo.MarkFailed(ctx, rec.ID, err.Error(), time.Now().Add(retryDelay(rec.Attempts)), dead); markErr != nil {
+		d.log.Warn("failed to mark domain event failed", "eventId", rec.ID, "error", markErr)
+	}
+}
+
+// retryDelay backs off exponentially from one second, capped at an hour.
+func retryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	seconds := math.Pow(2, float64(attempts-1))
+	if seconds >= maxRetryDelay.Seconds() {
+		return maxRetryDelay
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// NewHandlerGuard returns the bus guard that records which handlers completed
+// a stored event and skips them when the event is delivered again.
+func NewHandlerGuard(repo *Repository) events.HandlerGuard {
+	return func(ctx context.Context, eventID uuid.UUID, key string, run func(context.Context) error) error {
+		done, err := repo.Processed(ctx, eventID, key)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if err := run(ctx); err != nil {
+			return err
+		}
+		return repo.MarkProcessed(ctx, eventID, key)
+	}
+}
@@ -0,0 +1,112 @@
+package eventoutbox
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+// memoryReceipts is the receipt bookkeeping of NewHandlerGuard without a
+// database.
+type memoryReceipts struct {
+	mu   sync.Mutex
+	done map[string]bool
+}
+
+func (m *memoryReceipts) guard(ctx context.Context, eventID uuid.UUID, key string, run func(context.Context) error) error {
+	m.mu.Lock()
+	done := m.done[eventID.String()+" "+key]
+	m.mu.Unlock()
+	if done {
+		return nil
+	}
+	if err := run(ctx); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.done[eventID.String()+" "+key] = true
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memoryReceipts) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.done))
+	for key := range m.done {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestRedeliveryOfQuoteAcceptedRunsFannedOutHandlersOnce(t *testing.T) {
+	receipts := &memoryReceipts{done: map[string]bool{}}
+	bus := events.NewInMemoryBus(logger.New("development"))
+	bus.SetHandlerGuard(receipts.guard)
+
+	var pipelineRuns, stageRuns, notifyRuns atomic.Int32
+	// Like the orchestrator, the first handler moves the lead to the next
+	// pipeline stage and publishes the change.
+	bus.Subscribe(events.QuoteAccepted{}.EventName(), events.HandlerFunc(func(ctx context.Context, event events.Event) error {
+		pipelineRuns.Add(1)
+		if _, stored := events.EventIDFromContext(ctx); stored {
+			t.Error("handler context carries the stored event ID")
+		}
+		accepted := event.(events.QuoteAccepted)
+		bus.Publish(ctx, events.PipelineStageChanged{BaseEvent: events.NewBaseEvent(), LeadID: accepted.LeadID, TenantID: accepted.OrganizationID})
+		return nil
+	}))
+	// The second handler fails on the first delivery.
+	bus.Subscribe(events.QuoteAccepted{}.EventName(), events.HandlerFunc(func(context.Context, events.Event) error {
+		if notifyRuns.Add(1) == 1 {
+			return errors.New("mail server unavailable")
+		}
+		return nil
+	}))
+	bus.Subscribe(events.PipelineStageChanged{}.EventName(), events.HandlerFunc(func(context.Context, events.Event) error {
+		stageRuns.Add(1)
+		return nil
+	}))
+
+	eventID := uuid.New()
+	accepted := events.QuoteAccepted{BaseEvent: events.NewBaseEvent(), QuoteID: uuid.New(), LeadID: uuid.New(), OrganizationID: uuid.New()}
+	ctx := events.WithEventID(context.Background(), eventID)
+
+	if err := bus.PublishSync(ctx, accepted); err == nil {
+		t.Fatal("first delivery succeeded, want the failing handler's error")
+	}
+	if err := bus.PublishSync(ctx, accepted); err != nil {
+		t.Fatalf("redelivery failed: %v", err)
+	}
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := pipelineRuns.Load(); got != 1 {
+		t.Errorf("pipeline handler ran %d times, want 1", got)
+	}
+	if got := notifyRuns.Load(); got != 2 {
+		t.Errorf("failing handler ran %d times, want 2", got)
+	}
+	if got := stageRuns.Load(); got != 1 {
+		t.Errorf("stage change handler ran %d times, want 1", got)
+	}
+
+	keys := receipts.keys()
+	if len(keys) != 2 {
+		t.Fatalf("receipts = %v, want one per QuoteAccepted handler", keys)
+	}
+	for _, key := range keys {
+		if !strings.Contains(key, " "+accepted.EventName()+"/") {
+			t.Errorf("receipt %q is not keyed by the event name", key)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package eventoutbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"portal_final_backend/internal/events"
+)
+
+// Registry maps event names to their Go types, to decode stored events into
+// the values subscribers type-switch on.
+type Registry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]reflect.Type)}
+}
+
+// Register adds the types of the given event values, e.g.
+// Register(events.QuoteAccepted{}).
+func (r *Registry) Register(prototypes ...events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, prototype := range prototypes {
+		r.types[prototype.EventName()] = reflect.TypeOf(prototype)
+	}
+}
+
+// Decode returns the stored event as a value of its registered type.
+func (r *Registry) Decode(name string, payload []byte) (events.Event, error) {
+	r.mu.RLock()
+	typ, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("event %q is not registered", name)
+	}
+
+	ptr := reflect.New(typ)
+	if err := json.Unmarshal(payload, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s event: %w", name, err)
+	}
+	event, ok := ptr.Elem().Interface().(events.Event)
+	if !ok {
+		return nil, fmt.Errorf("event %q does not implement events.Event", name)
+	}
+	return event, nil
+}
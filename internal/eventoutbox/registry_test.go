@@ -0,0 +1,83 @@
+package eventoutbox
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/events"
+
+	"github.com/google/uuid"
+)
+
+func TestRegistryDecodesStoredEvent(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(events.QuoteAccepted{})
+
+	want := events.QuoteAccepted{BaseEvent: events.NewBaseEvent(), QuoteID: uuid.New(), TotalCents: 12500, QuoteNumber: "OFF-2026-0001"}
+	payload, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := registry.Decode(want.EventName(), payload)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	accepted, ok := got.(events.QuoteAccepted)
+	if !ok {
+		t.Fatalf("Decode() = %T, want events.QuoteAccepted", got)
+	}
+	if accepted.QuoteID != want.QuoteID || accepted.TotalCents != want.TotalCents || !accepted.OccurredAt().Equal(want.OccurredAt()) {
+		t.Fatalf("Decode() = %+v, want %+v", accepted, want)
+	}
+
+	if _, err := registry.Decode("unknown.event", payload); err == nil {
+		t.Fatal("Decode() of an unregistered event succeeded")
+	}
+}
+
+func TestRegistryRoundTripsOutboxEvents(t *testing.T) {
+	leadID, serviceID := uuid.New(), uuid.New()
+	stored := []events.Event{
+		events.LeadCreated{BaseEvent: events.NewBaseEvent(), LeadID: leadID, LeadServiceID: serviceID, ConsumerName: "Jan de Vries"},
+		events.AppointmentCreated{BaseEvent: events.NewBaseEvent(), AppointmentID: uuid.New(), LeadID: &leadID, StartTime: time.Now().UTC()},
+		events.AppointmentStatusChanged{BaseEvent: events.NewBaseEvent(), AppointmentID: uuid.New(), OldStatus: "scheduled", NewStatus: "completed"},
+		events.PartnerOfferExpired{BaseEvent: events.NewBaseEvent(), OfferID: uuid.New(), LeadID: leadID, PartnerName: "Dakwerken BV"},
+	}
+	registry := NewRegistry()
+	registry.Register(stored...)
+
+	for _, event := range stored {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := registry.Decode(event.EventName(), payload)
+		if err != nil {
+			t.Fatalf("Decode(%s) error = %v", event.EventName(), err)
+		}
+		again, err := json.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(payload) {
+			t.Errorf("Decode(%s) = %s, want %s", event.EventName(), again, payload)
+		}
+	}
+}
+
+func TestRetryDelayBacksOffUpToAnHour(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		5:  16 * time.Second,
+		13: time.Hour,
+		20: time.Hour,
+	}
+	for attempts, want := range cases {
+		if got := retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
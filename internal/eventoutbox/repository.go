@@ -0,0 +1,188 @@
+// Package eventoutbox stores domain events in the transaction of the state
+// change they describe and publishes them after commit, so a crash between
+// commit and publish no longer loses them.
+package eventoutbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/platform/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX is the transaction (or pool) an event is written with.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Enqueue writes event to the outbox with tx. Written with the transaction of
+// a state change, the event is published if and only if that transaction
+// commits. The data schema of ctx is stored with it, so the event is
+// published for the same schema.
+func Enqueue(ctx context.Context, tx DBTX, event events.Event) (uuid.UUID, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("marshal %s event: %w", event.EventName(), err)
+	}
+	occurredAt := event.OccurredAt()
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	id := uuid.New()
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_domain_event_outbox (id, event_name, payload, occurred_at, schema_name)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))`,
+		id, event.EventName(), payload, occurredAt, db.SchemaFromContext(ctx),
+	); err != nil {
+		return uuid.Nil, fmt.Errorf("enqueue %s event: %w", event.EventName(), err)
+	}
+	return id, nil
+}
+
+// InTx runs write in a transaction of pool and writes pendingEvents to the
+// outbox before committing, so the events are published exactly when the
+// write lands.
+func InTx(ctx context.Context, pool *pgxpool.Pool, write func(tx pgx.Tx) error, pendingEvents ...events.Event) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := write(tx); err != nil {
+		return err
+	}
+	for _, event := range pendingEvents {
+		if _, err := Enqueue(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Record is a stored event claimed for publishing.
+type Record struct {
+	ID        uuid.UUID
+	EventName string
+	Payload   json.RawMessage
+	Attempts  int
+	// Schema is the data schema the event was stored for, "" for public.
+	Schema string
+}
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Claim locks up to limit due events for lease and counts the attempt.
+// Events claimed by a dispatcher that crashed become due again when the
+// lease ends.
+func (r *Repository) Claim(ctx context.Context, limit int, lease time.Duration) ([]Record, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE RAC_domain_event_outbox o SET
+			attempts = o.attempts + 1,
+			locked_until = now() + $2 * interval '1 millisecond'
+		WHERE o.id IN (
+			SELECT id FROM RAC_domain_event_outbox
+			WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= now()
+				AND (locked_until IS NULL OR locked_until < now())
+			ORDER BY next_attempt_at, created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING o.id, o.event_name, o.payload, o.attempts, COALESCE(o.schema_name, '')`,
+		limit, lease.Milliseconds(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim domain events: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]Record, 0, limit)
+	for rows.Next() {
+		var rec Record
+		if err := rows.Scan(&rec.ID, &rec.EventName, &rec.Payload, &rec.Attempts, &rec.Schema); err != nil {
+			return nil, fmt.Errorf("scan domain event: %w", err)
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// MarkPublished records that every handler processed the event and drops its
+// payload, which may hold personal data.
+func (r *Repository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_domain_event_outbox SET
+			published_at = now(), locked_until = NULL, last_error = NULL, payload = '{}'::jsonb
+		WHERE id = $1`, id,
+	); err != nil {
+		return fmt.Errorf("mark domain event published: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed schedules the next attempt at retryAt, or gives the event up
+// when dead is set.
+func (r *Repository) MarkFailed(ctx context.Context, id uuid.UUID, cause string, retryAt time.Time, dead bool) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_domain_event_outbox SET
+			locked_until = NULL, last_error = $2, next_attempt_at = $3,
+			dead_at = CASE WHEN $4 THEN now() END
+		WHERE id = $1`, id, cause, retryAt, dead,
+	); err != nil {
+		return fmt.Errorf("mark domain event failed: %w", err)
+	}
+	return nil
+}
+
+// Processed reports whether handler already completed the event.
+func (r *Repository) Processed(ctx context.Context, eventID uuid.UUID, handler string) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT true FROM RAC_domain_event_receipts WHERE event_id = $1 AND handler = $2`,
+		eventID, handler,
+	).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get domain event receipt: %w", err)
+	}
+	return exists, nil
+}
+
+// MarkProcessed records that handler completed the event.
+func (r *Repository) MarkProcessed(ctx context.Context, eventID uuid.UUID, handler string) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_domain_event_receipts (event_id, handler) VALUES ($1, $2)
+		ON CONFLICT (event_id, handler) DO NOTHING`, eventID, handler,
+	); err != nil {
+		return fmt.Errorf("record domain event receipt: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes published events and receipts older than before.
+func (r *Repository) Prune(ctx context.Context, before time.Time) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM RAC_domain_event_outbox WHERE published_at < $1`, before); err != nil {
+		return fmt.Errorf("prune domain events: %w", err)
+	}
+	if _, err := r.pool.Exec(ctx, `DELETE FROM RAC_domain_event_receipts WHERE processed_at < $1`, before); err != nil {
+		return fmt.Errorf("prune domain event receipts: %w", err)
+	}
+	return nil
+}
@@ -12,14 +12,19 @@ import (
 
 // --- Platform Re-exports ---
 type (
-	Event       = events.Event
-	Bus         = events.Bus
-	Handler     = events.Handler
-	HandlerFunc = events.HandlerFunc
-	BaseEvent   = events.BaseEvent
+	Event        = events.Event
+	Bus          = events.Bus
+	Handler      = events.Handler
+	HandlerFunc  = events.HandlerFunc
+	BaseEvent    = events.BaseEvent
+	HandlerGuard = events.HandlerGuard
 )
 
-var NewBaseEvent = events.NewBaseEvent
+var (
+	NewBaseEvent       = events.NewBaseEvent
+	WithEventID        = events.WithEventID
+	EventIDFromContext = events.EventIDFromContext
+)
 
 // ─── Auth Domain Events ──────────────────────────────────────────────────────
 
@@ -50,6 +55,29 @@ type PasswordResetRequested struct {
 
 func (e PasswordResetRequested) EventName() string { return "auth.password.reset_requested" }
 
+type AccountLockedOut struct {
+	BaseEvent
+	UserID      uuid.UUID `json:"userId"`
+	Email       string    `json:"email"`
+	IPAddress   string    `json:"ipAddress"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+func (e AccountLockedOut) EventName() string { return "auth.account.locked_out" }
+
+type NewSignInDetected struct {
+	BaseEvent
+	UserID      uuid.UUID `json:"userId"`
+	Email       string    `json:"email"`
+	IPAddress   string    `json:"ipAddress"`
+	UserAgent   string    `json:"userAgent"`
+	NewDevice   bool      `json:"newDevice"`
+	NewLocation bool      `json:"newLocation"`
+	SignedInAt  time.Time `json:"signedInAt"`
+}
+
+func (e NewSignInDetected) EventName() string { return "auth.sign_in.new_device" }
+
 // ─── Leads Domain Events ─────────────────────────────────────────────────────
 
 type LeadCreated struct {
@@ -80,6 +108,21 @@ type LeadAssigned struct {
 
 func (e LeadAssigned) EventName() string { return "leads.lead.assigned" }
 
+// LeadsHandedOver is published when leads are transferred to another agent
+// with a handover note, either one at a time or in bulk when an agent leaves.
+type LeadsHandedOver struct {
+	BaseEvent
+	TenantID        uuid.UUID   `json:"tenantId"`
+	LeadIDs         []uuid.UUID `json:"leadIds"`
+	FromAgentID     *uuid.UUID  `json:"fromAgentId,omitempty"`
+	ToAgentID       uuid.UUID   `json:"toAgentId"`
+	TransferredByID uuid.UUID   `json:"transferredById"`
+	Note            string      `json:"note"`
+	BulkTransferID  *uuid.UUID  `json:"bulkTransferId,omitempty"`
+}
+
+func (e LeadsHandedOver) EventName() string { return "leads.lead.handed_over" }
+
 type LeadServiceAdded struct {
 	BaseEvent
 	LeadID        uuid.UUID `json:"leadId"`
@@ -110,6 +153,73 @@ type LeadAutoDisqualified struct {
 
 func (e LeadAutoDisqualified) EventName() string { return "leads.lead.auto_disqualified" }
 
+// LeadStale is published by the stale lead sweep when a lead service has made
+// no progress for longer than its organization allows. ConsumerNudge is set
+// when the organization re-engages the consumer through the lead_stale
+// workflow.
+type LeadStale struct {
+	BaseEvent
+	LeadID          uuid.UUID  `json:"leadId"`
+	LeadServiceID   uuid.UUID  `json:"leadServiceId"`
+	TenantID        uuid.UUID  `json:"tenantId"`
+	StaleReason     string     `json:"staleReason"`
+	AssignedAgentID *uuid.UUID `json:"assignedAgentId,omitempty"`
+	ConsumerNudge   bool       `json:"consumerNudge"`
+}
+
+func (e LeadStale) EventName() string { return "leads.service.stale" }
+
+// LeadActionRecommended is published when an AI analysis of a lead service is
+// saved, carrying the next action it recommends (e.g. CallImmediately).
+type LeadActionRecommended struct {
+	BaseEvent
+	LeadID            uuid.UUID  `json:"leadId"`
+	LeadServiceID     uuid.UUID  `json:"leadServiceId"`
+	TenantID          uuid.UUID  `json:"tenantId"`
+	AssignedAgentID   *uuid.UUID `json:"assignedAgentId,omitempty"`
+	RecommendedAction string     `json:"recommendedAction"`
+	UrgencyLevel      string     `json:"urgencyLevel"`
+	Summary           string     `json:"summary"`
+}
+
+func (e LeadActionRecommended) EventName() string { return "leads.analysis.action_recommended" }
+
+type LeadPortalLinkRequested struct {
+	BaseEvent
+	LeadID    uuid.UUID `json:"leadId"`
+	TenantID  uuid.UUID `json:"tenantId"`
+	Channel   string    `json:"channel"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (e LeadPortalLinkRequested) EventName() string { return "leads.portal_link.requested" }
+
+// LeadChannelConsentChanged is published when a lead grants or withdraws
+// consent for being contacted over a channel ("email" or "whatsapp").
+type LeadChannelConsentChanged struct {
+	BaseEvent
+	LeadID   uuid.UUID `json:"leadId"`
+	TenantID uuid.UUID `json:"tenantId"`
+	Channel  string    `json:"channel"`
+	Granted  bool      `json:"granted"`
+}
+
+func (e LeadChannelConsentChanged) EventName() string { return "leads.channel_consent.changed" }
+
+// LeadEnrichmentFailed is published when looking up external data for a lead
+// failed, as opposed to finding nothing. Enrichment is "lead_enrichment" for
+// the postcode statistics or "energy_label".
+type LeadEnrichmentFailed struct {
+	BaseEvent
+	LeadID     uuid.UUID `json:"leadId"`
+	TenantID   uuid.UUID `json:"tenantId"`
+	Enrichment string    `json:"enrichment"`
+	Error      string    `json:"error"`
+}
+
+func (e LeadEnrichmentFailed) EventName() string { return "leads.enrichment.failed" }
+
 type LeadServiceStatusChanged struct {
 	BaseEvent
 	LeadID        uuid.UUID `json:"leadId"`
@@ -224,6 +334,17 @@ type PartnerInviteCreated struct {
 
 func (e PartnerInviteCreated) EventName() string { return "partners.invite.created" }
 
+type PartnerApplicationSubmitted struct {
+	BaseEvent
+	ApplicationID  uuid.UUID `json:"applicationId"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	BusinessName   string    `json:"businessName"`
+	KVKNumber      string    `json:"kvkNumber"`
+	ContactEmail   string    `json:"contactEmail"`
+}
+
+func (e PartnerApplicationSubmitted) EventName() string { return "partners.application.submitted" }
+
 type PartnerOfferCreated struct {
 	BaseEvent
 	OfferID          uuid.UUID `json:"offerId"`
@@ -292,6 +413,124 @@ type PartnerOfferDeleted struct {
 
 func (e PartnerOfferDeleted) EventName() string { return "partners.offer.deleted" }
 
+// ─── Work Order Domain Events ────────────────────────────────────────────────
+
+// WorkOrderCompleted is published when the work of an accepted partner offer
+// is finished and the customer is asked to confirm it.
+type WorkOrderCompleted struct {
+	BaseEvent
+	WorkOrderID       uuid.UUID  `json:"workOrderId"`
+	OrganizationID    uuid.UUID  `json:"organizationId"`
+	LeadID            uuid.UUID  `json:"leadId"`
+	LeadServiceID     uuid.UUID  `json:"leadServiceId"`
+	PartnerID         *uuid.UUID `json:"partnerId,omitempty"`
+	ConfirmationToken string     `json:"confirmationToken"`
+}
+
+func (e WorkOrderCompleted) EventName() string { return "workorders.work_order.completed" }
+
+// WorkOrderConfirmed is published when the customer confirms the completion of
+// a work order. It closes the lead service and starts invoicing.
+type WorkOrderConfirmed struct {
+	BaseEvent
+	WorkOrderID    uuid.UUID  `json:"workOrderId"`
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	LeadID         uuid.UUID  `json:"leadId"`
+	LeadServiceID  uuid.UUID  `json:"leadServiceId"`
+	QuoteID        *uuid.UUID `json:"quoteId,omitempty"`
+	Feedback       string     `json:"feedback,omitempty"`
+}
+
+func (e WorkOrderConfirmed) EventName() string { return "workorders.work_order.confirmed" }
+
+// WarrantyClaimSubmitted is published when a customer submits a claim against
+// the warranty of a completed work order.
+type WarrantyClaimSubmitted struct {
+	BaseEvent
+	ClaimID        uuid.UUID `json:"claimId"`
+	WarrantyID     uuid.UUID `json:"warrantyId"`
+	WorkOrderID    uuid.UUID `json:"workOrderId"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	LeadID         uuid.UUID `json:"leadId"`
+	LeadServiceID  uuid.UUID `json:"leadServiceId"`
+}
+
+func (e WarrantyClaimSubmitted) EventName() string { return "workorders.warranty_claim.submitted" }
+
+// WarrantyExpiring is published once per warranty when its expiry date comes
+// near, so the customer can be offered maintenance.
+type WarrantyExpiring struct {
+	BaseEvent
+	WarrantyID     uuid.UUID `json:"warrantyId"`
+	WorkOrderID    uuid.UUID `json:"workOrderId"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	LeadID         uuid.UUID `json:"leadId"`
+	LeadServiceID  uuid.UUID `json:"leadServiceId"`
+	ExpiresOn      time.Time `json:"expiresOn"`
+	CoveredItems   []string  `json:"coveredItems,omitempty"`
+}
+
+func (e WarrantyExpiring) EventName() string { return "workorders.warranty.expiring" }
+
+// ─── KvK Domain Events ───────────────────────────────────────────────────────
+
+type KvKCompanyDissolved struct {
+	BaseEvent
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	EntityType     string     `json:"entityType"`
+	EntityID       uuid.UUID  `json:"entityId"`
+	KVKNumber      string     `json:"kvkNumber"`
+	LegalName      string     `json:"legalName"`
+	DissolvedAt    *time.Time `json:"dissolvedAt,omitempty"`
+}
+
+func (e KvKCompanyDissolved) EventName() string { return "kvk.company.dissolved" }
+
+// ─── Catalog Domain Events ───────────────────────────────────────────────────
+
+// CatalogPriceJump is a supplier cost change beyond the feed's alert threshold.
+type CatalogPriceJump struct {
+	ProductID    uuid.UUID `json:"productId"`
+	Title        string    `json:"title"`
+	Reference    string    `json:"reference"`
+	OldCostCents int64     `json:"oldCostCents"`
+	NewCostCents int64     `json:"newCostCents"`
+	ChangeBps    int       `json:"changeBps"`
+}
+
+// CatalogPriceJumpsDetected is published once per price feed run in which one or
+// more supplier costs moved more than the feed's alert threshold.
+type CatalogPriceJumpsDetected struct {
+	BaseEvent
+	OrganizationID uuid.UUID          `json:"organizationId"`
+	FeedID         uuid.UUID          `json:"feedId"`
+	SupplierName   string             `json:"supplierName"`
+	ThresholdBps   int                `json:"thresholdBps"`
+	Jumps          []CatalogPriceJump `json:"jumps"`
+}
+
+func (e CatalogPriceJumpsDetected) EventName() string { return "catalog.price_feed.jumps_detected" }
+
+// CatalogLowStockItem is a tracked product whose stock fell to or below its threshold.
+type CatalogLowStockItem struct {
+	ProductID         uuid.UUID `json:"productId"`
+	Title             string    `json:"title"`
+	Reference         string    `json:"reference"`
+	Quantity          float64   `json:"quantity"`
+	LowStockThreshold float64   `json:"lowStockThreshold"`
+}
+
+// CatalogLowStockDetected is published when consuming stock for a quote drops one
+// or more products to or below their low-stock threshold.
+type CatalogLowStockDetected struct {
+	BaseEvent
+	OrganizationID uuid.UUID             `json:"organizationId"`
+	QuoteID        uuid.UUID             `json:"quoteId"`
+	Items          []CatalogLowStockItem `json:"items"`
+}
+
+func (e CatalogLowStockDetected) EventName() string { return "catalog.stock.low_detected" }
+
 // ─── Quotes Domain Events ────────────────────────────────────────────────────
 
 type QuoteCreated struct {
@@ -324,6 +563,35 @@ type QuoteSent struct {
 
 func (e QuoteSent) EventName() string { return "quotes.quote.sent" }
 
+type QuoteApprovalRequested struct {
+	BaseEvent
+	ApprovalID      uuid.UUID `json:"approvalId"`
+	QuoteID         uuid.UUID `json:"quoteId"`
+	OrganizationID  uuid.UUID `json:"organizationId"`
+	LeadID          uuid.UUID `json:"leadId"`
+	QuoteNumber     string    `json:"quoteNumber"`
+	QuoteTotalCents int64     `json:"quoteTotalCents"`
+	RequestedByID   uuid.UUID `json:"requestedById"`
+	Reasons         []string  `json:"reasons"`
+}
+
+func (e QuoteApprovalRequested) EventName() string { return "quotes.quote.approval_requested" }
+
+type QuoteApprovalDecided struct {
+	BaseEvent
+	ApprovalID     uuid.UUID  `json:"approvalId"`
+	QuoteID        uuid.UUID  `json:"quoteId"`
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	LeadID         uuid.UUID  `json:"leadId"`
+	QuoteNumber    string     `json:"quoteNumber"`
+	Approved       bool       `json:"approved"`
+	DecidedByID    uuid.UUID  `json:"decidedById"`
+	RequestedByID  *uuid.UUID `json:"requestedById,omitempty"`
+	Note           string     `json:"note,omitempty"`
+}
+
+func (e QuoteApprovalDecided) EventName() string { return "quotes.quote.approval_decided" }
+
 type QuoteViewed struct {
 	BaseEvent
 	QuoteID        uuid.UUID `json:"quoteId"`
@@ -352,7 +620,7 @@ type QuoteAnnotated struct {
 	QuoteID          uuid.UUID  `json:"quoteId"`
 	OrganizationID   uuid.UUID  `json:"organizationId"`
 	LeadID           uuid.UUID  `json:"leadId"`
-	ItemID           uuid.UUID  `json:"itemId"`
+	ItemID           uuid.UUID  `json:"itemId"` // uuid.Nil for a comment on the quote as a whole
 	QuoteNumber      string     `json:"quoteNumber"`
 	PublicToken      string     `json:"publicToken"`
 	ItemDescription  string     `json:"itemDescription"`
@@ -435,6 +703,44 @@ type QuoteDeleted struct {
 
 func (e QuoteDeleted) EventName() string { return "quotes.quote.deleted" }
 
+// QuoteInstallmentDue is published once when an installment of an accepted quote's
+// payment schedule becomes due.
+type QuoteInstallmentDue struct {
+	BaseEvent
+	QuoteID        uuid.UUID  `json:"quoteId"`
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	LeadID         uuid.UUID  `json:"leadId"`
+	LeadServiceID  *uuid.UUID `json:"leadServiceId,omitempty"`
+	InstallmentID  uuid.UUID  `json:"installmentId"`
+	QuoteNumber    string     `json:"quoteNumber"`
+	Sequence       int        `json:"sequence"`
+	Label          string     `json:"label"`
+	AmountCents    int64      `json:"amountCents"`
+	DueAt          time.Time  `json:"dueAt"`
+	PublicToken    string     `json:"publicToken,omitempty"`
+}
+
+func (e QuoteInstallmentDue) EventName() string { return "quotes.quote.installment_due" }
+
+// QuotePaymentUpdated is published when the payment provider reports a new status for an
+// online payment of a quote.
+type QuotePaymentUpdated struct {
+	BaseEvent
+	QuoteID        uuid.UUID  `json:"quoteId"`
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	LeadID         uuid.UUID  `json:"leadId"`
+	LeadServiceID  *uuid.UUID `json:"leadServiceId,omitempty"`
+	PaymentID      uuid.UUID  `json:"paymentId"`
+	InstallmentID  *uuid.UUID `json:"installmentId,omitempty"`
+	QuoteNumber    string     `json:"quoteNumber"`
+	Provider       string     `json:"provider"`
+	Status         string     `json:"status"`
+	AmountCents    int64      `json:"amountCents"`
+	PaidAt         *time.Time `json:"paidAt,omitempty"`
+}
+
+func (e QuotePaymentUpdated) EventName() string { return "quotes.quote.payment_updated" }
+
 // ─── Appointments Domain Events ──────────────────────────────────────────────
 
 type AppointmentCreated struct {
@@ -450,12 +756,37 @@ type AppointmentCreated struct {
 	ConsumerPhone  string     `json:"consumerPhone,omitempty"`
 	ConsumerEmail  string     `json:"consumerEmail,omitempty"`
 	Location       string     `json:"location,omitempty"`
+	AllDay         bool       `json:"allDay,omitempty"`
 	LeadID         *uuid.UUID `json:"leadId,omitempty"`
 	LeadServiceID  *uuid.UUID `json:"leadServiceId,omitempty"`
 }
 
 func (e AppointmentCreated) EventName() string { return "appointments.appointment.created" }
 
+// AppointmentUpdated is published when the time, place or title of a scheduled
+// appointment changes. Sequence increases with every change so calendar invites
+// generated from the event replace the previous version.
+type AppointmentUpdated struct {
+	BaseEvent
+	AppointmentID  uuid.UUID  `json:"appointmentId"`
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	UserID         uuid.UUID  `json:"userId"`
+	Type           string     `json:"type"`
+	Title          string     `json:"title"`
+	StartTime      time.Time  `json:"startTime"`
+	EndTime        time.Time  `json:"endTime"`
+	AllDay         bool       `json:"allDay,omitempty"`
+	Sequence       int        `json:"sequence"`
+	ConsumerName   string     `json:"consumerName,omitempty"`
+	ConsumerPhone  string     `json:"consumerPhone,omitempty"`
+	ConsumerEmail  string     `json:"consumerEmail,omitempty"`
+	Location       string     `json:"location,omitempty"`
+	LeadID         *uuid.UUID `json:"leadId,omitempty"`
+	LeadServiceID  *uuid.UUID `json:"leadServiceId,omitempty"`
+}
+
+func (e AppointmentUpdated) EventName() string { return "appointments.appointment.updated" }
+
 type AppointmentStatusChanged struct {
 	BaseEvent
 	AppointmentID  uuid.UUID  `json:"appointmentId"`
@@ -488,6 +819,8 @@ type VisitReportSubmitted struct {
 	LeadID        uuid.UUID `json:"leadId"`
 	LeadServiceID uuid.UUID `json:"leadServiceId"`
 	TenantID      uuid.UUID `json:"tenantId"`
+	// MeasurementsChanged is set when the report brought new or different measurements.
+	MeasurementsChanged bool `json:"measurementsChanged"`
 }
 
 func (e VisitReportSubmitted) EventName() string { return "appointments.visit_report.submitted" }
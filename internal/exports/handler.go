@@ -10,8 +10,8 @@ import (
 	"time"
 
 	"portal_final_backend/internal/auth/password"
-	"portal_final_backend/internal/identity/smtpcrypto"
 	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/secrets"
 	"portal_final_backend/platform/validator"
 
 	"github.com/gin-gonic/gin"
@@ -58,16 +58,18 @@ type ExportCredentialResponse struct {
 // ─── HANDLER DEFINITION ──────────────────────────────────────────────────────
 
 type Handler struct {
-	val           *validator.Validator
-	repo          *Repository
-	encryptionKey []byte
+	val     *validator.Validator
+	repo    *Repository
+	keyring *secrets.Keyring
 }
 
 func NewHandler(repo *Repository, val *validator.Validator) *Handler {
 	return &Handler{repo: repo, val: val}
 }
 
-func (h *Handler) SetEncryptionKey(key []byte) { h.encryptionKey = key }
+func (h *Handler) SetEncryptionKey(key []byte) { h.keyring = secrets.SingleKeyring(key) }
+
+func (h *Handler) SetKeyring(keyring *secrets.Keyring) { h.keyring = keyring }
 
 func (h *Handler) Wait() {
 	// No background tasks to wait for.
@@ -91,8 +93,8 @@ func (h *Handler) HandleUpsertCredential(c *gin.Context) {
 
 	hash, _ := password.Hash(plain)
 	var enc *string
-	if len(h.encryptionKey) == 32 {
-		if cipher, err := smtpcrypto.Encrypt(plain, h.encryptionKey); err == nil {
+	if h.keyring != nil {
+		if cipher, err := h.keyring.Encrypt(plain); err == nil {
 			enc = &cipher
 		}
 	}
@@ -151,7 +153,7 @@ func (h *Handler) HandleRevealPassword(c *gin.Context) {
 		return
 	}
 
-	if len(h.encryptionKey) != 32 {
+	if h.keyring == nil {
 		httpkit.Error(c, http.StatusConflict, "reveal not configured", nil)
 		return
 	}
@@ -161,7 +163,7 @@ func (h *Handler) HandleRevealPassword(c *gin.Context) {
 		return
 	}
 
-	plain, err := smtpcrypto.Decrypt(*cred.PasswordEncrypted, h.encryptionKey)
+	plain, err := h.keyring.Decrypt(*cred.PasswordEncrypted)
 	if err != nil {
 		httpkit.Error(c, http.StatusInternalServerError, "decryption failed", nil)
 		return
@@ -2,6 +2,7 @@ package exports
 
 import (
 	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/platform/secrets"
 	"portal_final_backend/platform/validator"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -20,8 +21,9 @@ func NewModule(pool *pgxpool.Pool, val *validator.Validator) *Module {
 	}
 }
 
-func (m *Module) SetEncryptionKey(key []byte) { m.handler.SetEncryptionKey(key) }
-func (m *Module) Name() string                { return "exports" }
+func (m *Module) SetEncryptionKey(key []byte)         { m.handler.SetEncryptionKey(key) }
+func (m *Module) SetKeyring(keyring *secrets.Keyring) { m.handler.SetKeyring(keyring) }
+func (m *Module) Name() string                        { return "exports" }
 
 func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	public := ctx.V1.Group("/exports")
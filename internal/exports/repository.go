@@ -42,6 +42,12 @@ type ConversionEvent struct {
 	LeadID              uuid.UUID
 	LeadServiceID       uuid.UUID
 	GCLID               string
+	FBCLID              *string
+	UTMSource           *string
+	UTMMedium           *string
+	UTMCampaign         *string
+	UTMContent          *string
+	UTMTerm             *string
 	ConsumerEmail       *string
 	ConsumerPhone       string
 	ConsumerFirstName   string
@@ -142,9 +148,61 @@ func (r *Repository) ListConversionEvents(ctx context.Context, tid uuid.UUID, fr
 			Status:              ptr(row.Status.String, row.Status.Valid),
 		})
 	}
+	if err := r.attachAttribution(ctx, tid, res); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
+// attachAttribution adds the fbclid and UTM parameters of the leads to the
+// conversion events, so uploads can attribute leads that have no gclid.
+func (r *Repository) attachAttribution(ctx context.Context, tid uuid.UUID, events []ConversionEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	leadIDs := make([]uuid.UUID, 0, len(events))
+	seen := make(map[uuid.UUID]struct{}, len(events))
+	for _, e := range events {
+		if _, ok := seen[e.LeadID]; !ok {
+			seen[e.LeadID] = struct{}{}
+			leadIDs = append(leadIDs, e.LeadID)
+		}
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, fbclid, utm_source, utm_medium, utm_campaign, utm_content, utm_term
+		FROM RAC_leads
+		WHERE organization_id = $1 AND id = ANY($2)`, tid, leadIDs)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byLead := make(map[uuid.UUID]ConversionEvent, len(leadIDs))
+	for rows.Next() {
+		var id uuid.UUID
+		var a ConversionEvent
+		if err := rows.Scan(&id, &a.FBCLID, &a.UTMSource, &a.UTMMedium, &a.UTMCampaign, &a.UTMContent, &a.UTMTerm); err != nil {
+			return err
+		}
+		byLead[id] = a
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range events {
+		a := byLead[events[i].LeadID]
+		events[i].FBCLID = a.FBCLID
+		events[i].UTMSource = a.UTMSource
+		events[i].UTMMedium = a.UTMMedium
+		events[i].UTMCampaign = a.UTMCampaign
+		events[i].UTMContent = a.UTMContent
+		events[i].UTMTerm = a.UTMTerm
+	}
+	return nil
+}
+
 func (r *Repository) DeleteCredential(ctx context.Context, tid uuid.UUID) error {
 	ra, err := r.queries.DeleteGoogleAdsExportCredential(ctx, pgtype.UUID{Bytes: tid, Valid: true})
 	if err == nil && ra == 0 {
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/files/service"
+	"portal_final_backend/internal/files/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	msgInvalidRequest   = "invalid request"
+	msgValidationFailed = "validation failed"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.ListFiles)
+	rg.GET("/usage", h.GetStorageUsage)
+	rg.POST("/uploads", h.RequestUpload)
+	rg.POST("/:fileId/complete", h.CompleteUpload)
+	rg.GET("/:fileId", h.GetFile)
+	rg.GET("/:fileId/download", h.GetDownloadURL)
+	rg.DELETE("/:fileId", h.DeleteFile)
+}
+
+func (h *Handler) RequestUpload(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.RequestUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.svc.RequestUpload(c.Request.Context(), tenantID, httpkit.GetIdentity(c).UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
+
+func (h *Handler) CompleteUpload(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	fileID, ok := httpkit.ParseUUIDParam(c, "fileId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.CompleteUpload(c.Request.Context(), tenantID, fileID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) ListFiles(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.ListFilesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.svc.ListFiles(c.Request.Context(), tenantID, req.OwnerType, uuid.MustParse(req.OwnerID))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetFile(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	fileID, ok := httpkit.ParseUUIDParam(c, "fileId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetFile(c.Request.Context(), tenantID, fileID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetDownloadURL(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	fileID, ok := httpkit.ParseUUIDParam(c, "fileId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetDownloadURL(c.Request.Context(), tenantID, fileID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) DeleteFile(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	fileID, ok := httpkit.ParseUUIDParam(c, "fileId")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteFile(c.Request.Context(), tenantID, fileID); httpkit.HandleError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) GetStorageUsage(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetStorageUsage(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
@@ -0,0 +1,43 @@
+// Package files provides the central file management module: metadata for every
+// attachment, checksum deduplication, presigned transfers and orphan cleanup.
+package files
+
+import (
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/files/handler"
+	"portal_final_backend/internal/files/repository"
+	"portal_final_backend/internal/files/service"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+}
+
+func NewModule(pool *pgxpool.Pool, storageSvc storage.StorageService, bucket string, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), storageSvc, bucket, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+	}
+}
+
+// Service returns the files service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "files"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterRoutes(ctx.Protected.Group("/files"))
+}
+
+var _ apphttp.Module = (*Module)(nil)
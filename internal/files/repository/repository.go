@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const fileNotFoundMsg = "file not found"
+
+// File statuses.
+const (
+	StatusPending = "pending"
+	StatusReady   = "ready"
+)
+
+// ownerTables maps an owner type onto the tenant-scoped table that holds the owner.
+var ownerTables = map[string]string{
+	"lead":            "RAC_leads",
+	"lead_service":    "RAC_lead_services",
+	"quote":           "RAC_quotes",
+	"catalog_product": "RAC_catalog_products",
+	"partner":         "RAC_partners",
+}
+
+// Repository persists file metadata.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// File is the metadata of an uploaded file.
+type File struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	OwnerType      string
+	OwnerID        uuid.UUID
+	Bucket         string
+	FileKey        string
+	FileName       string
+	ContentType    string
+	SizeBytes      int64
+	ChecksumSHA256 string
+	Status         string
+	CreatedBy      *uuid.UUID
+	CreatedAt      time.Time
+	UploadedAt     *time.Time
+}
+
+// Usage is the storage used by the files of an organization. Deduplicated files
+// count once towards StoredBytes but every reference counts towards LogicalBytes.
+type Usage struct {
+	Files         int64
+	StoredObjects int64
+	StoredBytes   int64
+	LogicalBytes  int64
+}
+
+const fileColumns = `id, organization_id, owner_type, owner_id, bucket, file_key, file_name, content_type, size_bytes, checksum_sha256, status, created_by, created_at, uploaded_at`
+
+func scanFile(row pgx.Row) (File, error) {
+	var f File
+	err := row.Scan(&f.ID, &f.OrganizationID, &f.OwnerType, &f.OwnerID, &f.Bucket, &f.FileKey, &f.FileName, &f.ContentType, &f.SizeBytes, &f.ChecksumSHA256, &f.Status, &f.CreatedBy, &f.CreatedAt, &f.UploadedAt)
+	return f, err
+}
+
+func collectFiles(rows pgx.Rows) ([]File, error) {
+	defer rows.Close()
+	items := make([]File, 0)
+	for rows.Next() {
+		f, err := scanFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan file: %w", err)
+		}
+		items = append(items, f)
+	}
+	return items, rows.Err()
+}
+
+// OwnerExists reports whether the owner of a file belongs to the organization.
+func (r *Repository) OwnerExists(ctx context.Context, organizationID uuid.UUID, ownerType string, ownerID uuid.UUID) (bool, error) {
+	if ownerType == "organization" {
+		return ownerID == organizationID, nil
+	}
+	table, ok := ownerTables[ownerType]
+	if !ok {
+		return false, nil
+	}
+
+	var exists bool
+	// The table name comes from the ownerTables whitelist.
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND organization_id = $2)`, table)
+	if err := r.pool.QueryRow(ctx, query, ownerID, organizationID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check file owner: %w", err)
+	}
+	return exists, nil
+}
+
+// CreateFile inserts file metadata.
+func (r *Repository) CreateFile(ctx context.Context, f File) (File, error) {
+	created, err := scanFile(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_files (organization_id, owner_type, owner_id, bucket, file_key, file_name, content_type, size_bytes, checksum_sha256, status, created_by, uploaded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING `+fileColumns,
+		f.OrganizationID, f.OwnerType, f.OwnerID, f.Bucket, f.FileKey, f.FileName, f.ContentType, f.SizeBytes, f.ChecksumSHA256, f.Status, f.CreatedBy, f.UploadedAt,
+	))
+	if err != nil {
+		return File{}, fmt.Errorf("create file: %w", err)
+	}
+	return created, nil
+}
+
+// GetFile returns the metadata of a file.
+func (r *Repository) GetFile(ctx context.Context, organizationID, id uuid.UUID) (File, error) {
+	f, err := scanFile(r.pool.QueryRow(ctx, `SELECT `+fileColumns+` FROM RAC_files WHERE id = $1 AND organization_id = $2`, id, organizationID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return File{}, apperr.NotFound(fileNotFoundMsg)
+	}
+	if err != nil {
+		return File{}, fmt.Errorf("get file: %w", err)
+	}
+	return f, nil
+}
+
+// FindReadyByChecksum returns an uploaded file with the same content, or nil.
+func (r *Repository) FindReadyByChecksum(ctx context.Context, organizationID uuid.UUID, checksum string) (*File, error) {
+	f, err := scanFile(r.pool.QueryRow(ctx, `
+		SELECT `+fileColumns+`
+		FROM RAC_files
+		WHERE organization_id = $1 AND checksum_sha256 = $2 AND status = 'ready'
+		ORDER BY created_at
+		LIMIT 1`, organizationID, checksum,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find file by checksum: %w", err)
+	}
+	return &f, nil
+}
+
+// MarkReady marks a pending file as uploaded.
+func (r *Repository) MarkReady(ctx context.Context, organizationID, id uuid.UUID) (File, error) {
+	f, err := scanFile(r.pool.QueryRow(ctx, `
+		UPDATE RAC_files SET status = 'ready', uploaded_at = now()
+		WHERE id = $1 AND organization_id = $2 AND status = 'pending'
+		RETURNING `+fileColumns, id, organizationID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return File{}, apperr.NotFound(fileNotFoundMsg)
+	}
+	if err != nil {
+		return File{}, fmt.Errorf("mark file ready: %w", err)
+	}
+	return f, nil
+}
+
+// ListByOwner returns the files of an owner, newest first.
+func (r *Repository) ListByOwner(ctx context.Context, organizationID uuid.UUID, ownerType string, ownerID uuid.UUID) ([]File, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+fileColumns+`
+		FROM RAC_files
+		WHERE organization_id = $1 AND owner_type = $2 AND owner_id = $3
+		ORDER BY created_at DESC`, organizationID, ownerType, ownerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	return collectFiles(rows)
+}
+
+// DeleteFile removes file metadata and reports whether other files still reference
+// the stored object.
+func (r *Repository) DeleteFile(ctx context.Context, organizationID, id uuid.UUID) (File, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return File{}, false, fmt.Errorf("begin delete file: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	f, err := scanFile(tx.QueryRow(ctx, `DELETE FROM RAC_files WHERE id = $1 AND organization_id = $2 RETURNING `+fileColumns, id, organizationID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return File{}, false, apperr.NotFound(fileNotFoundMsg)
+	}
+	if err != nil {
+		return File{}, false, fmt.Errorf("delete file: %w", err)
+	}
+
+	var referenced bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM RAC_files WHERE bucket = $1 AND file_key = $2)`, f.Bucket, f.FileKey).Scan(&referenced); err != nil {
+		return File{}, false, fmt.Errorf("check file references: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return File{}, false, fmt.Errorf("commit delete file: %w", err)
+	}
+	return f, referenced, nil
+}
+
+// DeleteStalePending removes pending uploads created before the given time and
+// returns the removed rows whose object is not referenced by another file.
+func (r *Repository) DeleteStalePending(ctx context.Context, before time.Time, limit int) ([]File, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH stale AS (
+			DELETE FROM RAC_files
+			WHERE id IN (
+				SELECT id FROM RAC_files
+				WHERE status = 'pending' AND created_at < $1
+				ORDER BY created_at
+				LIMIT $2
+			)
+			RETURNING `+fileColumns+`
+		)
+		SELECT `+fileColumns+` FROM stale s
+		WHERE NOT EXISTS (
+			-- The statement snapshot still contains the deleted rows.
+			SELECT 1 FROM RAC_files f
+			WHERE f.bucket = s.bucket AND f.file_key = s.file_key AND f.id NOT IN (SELECT id FROM stale)
+		)`, before, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("delete stale pending files: %w", err)
+	}
+	return collectFiles(rows)
+}
+
+// ReferencedKeys returns the subset of keys in a bucket that are referenced by file metadata.
+func (r *Repository) ReferencedKeys(ctx context.Context, bucket string, keys []string) (map[string]bool, error) {
+	rows, err := r.pool.Query(ctx, `SELECT DISTINCT file_key FROM RAC_files WHERE bucket = $1 AND file_key = ANY($2)`, bucket, keys)
+	if err != nil {
+		return nil, fmt.Errorf("list referenced file keys: %w", err)
+	}
+	defer rows.Close()
+
+	referenced := make(map[string]bool, len(keys))
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scan referenced file key: %w", err)
+		}
+		referenced[key] = true
+	}
+	return referenced, rows.Err()
+}
+
+// GetUsage returns the storage used by the uploaded files of an organization.
+func (r *Repository) GetUsage(ctx context.Context, organizationID uuid.UUID) (Usage, error) {
+	var u Usage
+	err := r.pool.QueryRow(ctx, `
+		WITH ready AS (
+			SELECT bucket, file_key, size_bytes FROM RAC_files
+			WHERE organization_id = $1 AND status = 'ready'
+		), objects AS (
+			SELECT bucket, file_key, MAX(size_bytes) AS size_bytes FROM ready GROUP BY bucket, file_key
+		)
+		SELECT
+			(SELECT COUNT(*) FROM ready)::bigint,
+			(SELECT COUNT(*) FROM objects)::bigint,
+			(SELECT COALESCE(SUM(size_bytes), 0) FROM objects)::bigint,
+			(SELECT COALESCE(SUM(size_bytes), 0) FROM ready)::bigint`, organizationID,
+	).Scan(&u.Files, &u.StoredObjects, &u.StoredBytes, &u.LogicalBytes)
+	if err != nil {
+		return Usage{}, fmt.Errorf("get file usage: %w", err)
+	}
+	return u, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// filesSchema holds RAC_files without the references to organizations and
+// users, so the test runs against any Postgres instead of a fully migrated
+// database.
+const filesSchema = `
+CREATE TABLE RAC_files (
+	id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	organization_id uuid NOT NULL,
+	owner_type text NOT NULL,
+	owner_id uuid NOT NULL,
+	bucket text NOT NULL,
+	file_key text NOT NULL,
+	file_name text NOT NULL,
+	content_type text NOT NULL,
+	size_bytes bigint NOT NULL,
+	checksum_sha256 text NOT NULL,
+	status text NOT NULL DEFAULT 'pending',
+	created_by uuid,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	uploaded_at timestamptz
+);
+`
+
+// openFilesDB connects to TEST_DATABASE_URL with a scratch schema first on
+// the search path and drops the schema when the test ends.
+func openFilesDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+	schema := "files_" + uuid.NewString()[:8]
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ", public"
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if _, err := pool.Exec(ctx, filesSchema); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func insertFile(t *testing.T, pool *pgxpool.Pool, organizationID uuid.UUID, key, checksum, status string, createdAt time.Time) uuid.UUID {
+	t.Helper()
+	var id uuid.UUID
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO RAC_files (organization_id, owner_type, owner_id, bucket, file_key, file_name, content_type, size_bytes, checksum_sha256, status, created_at)
+		VALUES ($1, 'lead', $2, 'files', $3, $3, 'application/pdf', 10, $4, $5, $6)
+		RETURNING id`, organizationID, uuid.New(), key, checksum, status, createdAt,
+	).Scan(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestFindReadyByChecksum(t *testing.T) {
+	pool := openFilesDB(t)
+	ctx := context.Background()
+	orgID, otherOrgID := uuid.New(), uuid.New()
+	now := time.Now()
+	shared, pendingOnly := strings.Repeat("a", 64), strings.Repeat("b", 64)
+
+	insertFile(t, pool, otherOrgID, "other/org.pdf", shared, StatusReady, now.Add(-3*time.Hour))
+	insertFile(t, pool, orgID, "org/first.pdf", shared, StatusReady, now.Add(-2*time.Hour))
+	insertFile(t, pool, orgID, "org/second.pdf", shared, StatusReady, now.Add(-time.Hour))
+	insertFile(t, pool, orgID, "org/pending.pdf", pendingOnly, StatusPending, now)
+
+	repo := New(pool)
+	found, err := repo.FindReadyByChecksum(ctx, orgID, shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.FileKey != "org/first.pdf" {
+		t.Fatalf("expected the oldest ready file of the organization, got %+v", found)
+	}
+
+	for _, checksum := range []string{pendingOnly, strings.Repeat("c", 64)} {
+		found, err := repo.FindReadyByChecksum(ctx, orgID, checksum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != nil {
+			t.Errorf("checksum %s: expected no match, got %+v", checksum[:1], found)
+		}
+	}
+}
+
+func TestDeleteStalePendingReturnsOnlyUnreferencedObjects(t *testing.T) {
+	pool := openFilesDB(t)
+	ctx := context.Background()
+	orgID := uuid.New()
+	now := time.Now()
+	cutoff := now.Add(-24 * time.Hour)
+	checksum := strings.Repeat("a", 64)
+
+	stale := insertFile(t, pool, orgID, "org/stale.pdf", checksum, StatusPending, now.Add(-48*time.Hour))
+	insertFile(t, pool, orgID, "org/recent.pdf", checksum, StatusPending, now)
+	// A stale upload that shares the object of a ready file.
+	sharing := insertFile(t, pool, orgID, "org/ready.pdf", checksum, StatusPending, now.Add(-48*time.Hour))
+	insertFile(t, pool, orgID, "org/ready.pdf", checksum, StatusReady, now.Add(-48*time.Hour))
+	// Two stale uploads of the same object are both removed, so it is orphaned.
+	insertFile(t, pool, orgID, "org/twice.pdf", checksum, StatusPending, now.Add(-48*time.Hour))
+	insertFile(t, pool, orgID, "org/twice.pdf", checksum, StatusPending, now.Add(-47*time.Hour))
+
+	repo := New(pool)
+	removed, err := repo.DeleteStalePending(ctx, cutoff, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(removed))
+	for _, f := range removed {
+		keys = append(keys, f.FileKey)
+	}
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != "org/stale.pdf,org/twice.pdf,org/twice.pdf" {
+		t.Fatalf("removed objects = %v, want stale and both twice rows", keys)
+	}
+
+	for _, id := range []uuid.UUID{stale, sharing} {
+		if _, err := repo.GetFile(ctx, orgID, id); err == nil {
+			t.Errorf("expected stale file %s to be deleted", id)
+		}
+	}
+	referenced, err := repo.ReferencedKeys(ctx, "files", []string{"org/stale.pdf", "org/recent.pdf", "org/ready.pdf", "org/twice.pdf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(referenced) != 2 || !referenced["org/recent.pdf"] || !referenced["org/ready.pdf"] {
+		t.Errorf("referenced keys = %v, want recent and ready", referenced)
+	}
+}
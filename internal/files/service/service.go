@@ -0,0 +1,340 @@
+// Package service implements the files domain: metadata, deduplication, presigned
+// transfers and orphan cleanup for uploaded attachments.
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/files/repository"
+	"portal_final_backend/internal/files/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultOrphanGracePeriod protects uploads that are still in flight from cleanup.
+	DefaultOrphanGracePeriod = 24 * time.Hour
+
+	orphanCleanupBatchSize = 500
+)
+
+type Repository interface {
+	OwnerExists(ctx context.Context, organizationID uuid.UUID, ownerType string, ownerID uuid.UUID) (bool, error)
+	CreateFile(ctx context.Context, f repository.File) (repository.File, error)
+	GetFile(ctx context.Context, organizationID, id uuid.UUID) (repository.File, error)
+	FindReadyByChecksum(ctx context.Context, organizationID uuid.UUID, checksum string) (*repository.File, error)
+	MarkReady(ctx context.Context, organizationID, id uuid.UUID) (repository.File, error)
+	ListByOwner(ctx context.Context, organizationID uuid.UUID, ownerType string, ownerID uuid.UUID) ([]repository.File, error)
+	DeleteFile(ctx context.Context, organizationID, id uuid.UUID) (repository.File, bool, error)
+	DeleteStalePending(ctx context.Context, before time.Time, limit int) ([]repository.File, error)
+	ReferencedKeys(ctx context.Context, bucket string, keys []string) (map[string]bool, error)
+	GetUsage(ctx context.Context, organizationID uuid.UUID) (repository.Usage, error)
+}
+
+// OrphanCleanupResult summarizes a cleanup run.
+type OrphanCleanupResult struct {
+	StalePending  int
+	OrphanObjects int
+	Failed        int
+}
+
+type Service struct {
+	repo    Repository
+	storage storage.StorageService
+	bucket  string
+	log     *logger.Logger
+}
+
+func New(repo Repository, storageSvc storage.StorageService, bucket string, log *logger.Logger) *Service {
+	return &Service{repo: repo, storage: storageSvc, bucket: bucket, log: log}
+}
+
+// RequestUpload registers a file for an owner. When the organization already stored the
+// same content, the new file shares that object and no upload is needed.
+func (s *Service) RequestUpload(ctx context.Context, organizationID, userID uuid.UUID, req transport.RequestUploadRequest) (transport.UploadResponse, error) {
+	if err := s.storage.ValidateContentType(req.ContentType); err != nil {
+		return transport.UploadResponse{}, apperr.Validation(err.Error())
+	}
+	if err := s.storage.ValidateFileSize(req.SizeBytes); err != nil {
+		return transport.UploadResponse{}, apperr.Validation(err.Error())
+	}
+	if err := s.ensureOwner(ctx, organizationID, req.OwnerType, req.OwnerID); err != nil {
+		return transport.UploadResponse{}, err
+	}
+
+	checksum := strings.ToLower(req.ChecksumSHA256)
+	file := repository.File{
+		OrganizationID: organizationID,
+		OwnerType:      req.OwnerType,
+		OwnerID:        req.OwnerID,
+		Bucket:         s.bucket,
+		FileName:       path.Base(strings.TrimSpace(req.FileName)),
+		ContentType:    req.ContentType,
+		SizeBytes:      req.SizeBytes,
+		ChecksumSHA256: checksum,
+		Status:         repository.StatusPending,
+		CreatedBy:      &userID,
+	}
+
+	existing, err := s.repo.FindReadyByChecksum(ctx, organizationID, checksum)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	if existing != nil && existing.SizeBytes == req.SizeBytes {
+		now := time.Now()
+		file.Bucket = existing.Bucket
+		file.FileKey = existing.FileKey
+		file.Status = repository.StatusReady
+		file.UploadedAt = &now
+		created, err := s.repo.CreateFile(ctx, file)
+		if err != nil {
+			return transport.UploadResponse{}, err
+		}
+		return transport.UploadResponse{File: toFileResponse(created), Deduplicated: true}, nil
+	}
+
+	folder := path.Join(organizationID.String(), req.OwnerType, req.OwnerID.String())
+	presigned, err := s.storage.GenerateUploadURL(ctx, s.bucket, folder, file.FileName, req.ContentType, req.SizeBytes)
+	if err != nil {
+		return transport.UploadResponse{}, apperr.Validation(err.Error())
+	}
+	file.FileKey = presigned.FileKey
+
+	created, err := s.repo.CreateFile(ctx, file)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	return transport.UploadResponse{
+		File:      toFileResponse(created),
+		UploadURL: presigned.URL,
+		ExpiresAt: &presigned.ExpiresAt,
+	}, nil
+}
+
+// CompleteUpload verifies the uploaded object against the registered size and
+// checksum and marks the file ready. A mismatching upload is discarded.
+func (s *Service) CompleteUpload(ctx context.Context, organizationID, id uuid.UUID) (transport.FileResponse, error) {
+	file, err := s.repo.GetFile(ctx, organizationID, id)
+	if err != nil {
+		return transport.FileResponse{}, err
+	}
+	if file.Status == repository.StatusReady {
+		return toFileResponse(file), nil
+	}
+
+	checksum, size, err := s.hashObject(ctx, file.Bucket, file.FileKey)
+	if err != nil {
+		return transport.FileResponse{}, apperr.Validation("uploaded file not found").WithDetails(err.Error())
+	}
+	if size != file.SizeBytes || checksum != file.ChecksumSHA256 {
+		s.discard(ctx, organizationID, file)
+		return transport.FileResponse{}, apperr.Validation("uploaded file does not match its size or checksum").WithDetails(map[string]any{
+			"expectedSizeBytes": file.SizeBytes,
+			"actualSizeBytes":   size,
+		})
+	}
+
+	ready, err := s.repo.MarkReady(ctx, organizationID, id)
+	if err != nil {
+		return transport.FileResponse{}, err
+	}
+	return toFileResponse(ready), nil
+}
+
+func (s *Service) GetFile(ctx context.Context, organizationID, id uuid.UUID) (transport.FileResponse, error) {
+	file, err := s.repo.GetFile(ctx, organizationID, id)
+	if err != nil {
+		return transport.FileResponse{}, err
+	}
+	return toFileResponse(file), nil
+}
+
+func (s *Service) ListFiles(ctx context.Context, organizationID uuid.UUID, ownerType string, ownerID uuid.UUID) (transport.FileListResponse, error) {
+	files, err := s.repo.ListByOwner(ctx, organizationID, ownerType, ownerID)
+	if err != nil {
+		return transport.FileListResponse{}, err
+	}
+	items := make([]transport.FileResponse, 0, len(files))
+	for _, f := range files {
+		items = append(items, toFileResponse(f))
+	}
+	return transport.FileListResponse{Items: items}, nil
+}
+
+// GetDownloadURL returns a presigned download URL for an uploaded file.
+func (s *Service) GetDownloadURL(ctx context.Context, organizationID, id uuid.UUID) (transport.DownloadURLResponse, error) {
+	file, err := s.repo.GetFile(ctx, organizationID, id)
+	if err != nil {
+		return transport.DownloadURLResponse{}, err
+	}
+	if file.Status != repository.StatusReady {
+		return transport.DownloadURLResponse{}, apperr.Conflict("file upload is not completed")
+	}
+
+	presigned, err := s.storage.GenerateDownloadURL(ctx, file.Bucket, file.FileKey)
+	if err != nil {
+		return transport.DownloadURLResponse{}, err
+	}
+	return transport.DownloadURLResponse{URL: presigned.URL, ExpiresAt: presigned.ExpiresAt}, nil
+}
+
+// DeleteFile removes a file. The stored object is removed once no other file shares it.
+func (s *Service) DeleteFile(ctx context.Context, organizationID, id uuid.UUID) error {
+	file, referenced, err := s.repo.DeleteFile(ctx, organizationID, id)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return nil
+	}
+	if err := s.storage.DeleteObject(ctx, file.Bucket, file.FileKey); err != nil {
+		// The orphan cleanup retries objects that are left behind.
+		s.log.Warn("files: failed to delete object", "fileId", id, "key", file.FileKey, "error", err)
+	}
+	return nil
+}
+
+// GetStorageUsage reports the storage used by the files of an organization.
+func (s *Service) GetStorageUsage(ctx context.Context, organizationID uuid.UUID) (transport.StorageUsageResponse, error) {
+	usage, err := s.repo.GetUsage(ctx, organizationID)
+	if err != nil {
+		return transport.StorageUsageResponse{}, err
+	}
+	return transport.StorageUsageResponse{
+		Files:         usage.Files,
+		StoredObjects: usage.StoredObjects,
+		StoredBytes:   usage.StoredBytes,
+		LogicalBytes:  usage.LogicalBytes,
+		SavedBytes:    usage.LogicalBytes - usage.StoredBytes,
+	}, nil
+}
+
+// CleanupOrphans removes uploads that were never completed and objects in the files
+// bucket that have no metadata. Objects younger than the grace period are kept.
+func (s *Service) CleanupOrphans(ctx context.Context, grace time.Duration) (OrphanCleanupResult, error) {
+	if grace <= 0 {
+		grace = DefaultOrphanGracePeriod
+	}
+	cutoff := time.Now().Add(-grace)
+	var result OrphanCleanupResult
+
+	for {
+		stale, err := s.repo.DeleteStalePending(ctx, cutoff, orphanCleanupBatchSize)
+		if err != nil {
+			return result, err
+		}
+		for _, f := range stale {
+			// The object may never have been uploaded; a failed delete is not an error.
+			_ = s.storage.DeleteObject(ctx, f.Bucket, f.FileKey)
+			result.StalePending++
+		}
+		if len(stale) < orphanCleanupBatchSize {
+			break
+		}
+	}
+
+	inventory, ok := s.storage.(storage.ObjectInventory)
+	if !ok {
+		return result, nil
+	}
+
+	batch := make([]string, 0, orphanCleanupBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		referenced, err := s.repo.ReferencedKeys(ctx, s.bucket, batch)
+		if err != nil {
+			return err
+		}
+		for _, key := range batch {
+			if referenced[key] {
+				continue
+			}
+			if err := s.storage.DeleteObject(ctx, s.bucket, key); err != nil {
+				result.Failed++
+				s.log.Warn("files: failed to delete orphan object", "key", key, "error", err)
+				continue
+			}
+			result.OrphanObjects++
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := inventory.ListObjects(ctx, s.bucket, "", func(obj storage.ObjectInfo) error {
+		if obj.LastModified.After(cutoff) {
+			return nil
+		}
+		batch = append(batch, obj.Key)
+		if len(batch) < orphanCleanupBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return result, err
+	}
+	return result, flush()
+}
+
+func (s *Service) ensureOwner(ctx context.Context, organizationID uuid.UUID, ownerType string, ownerID uuid.UUID) error {
+	exists, err := s.repo.OwnerExists(ctx, organizationID, ownerType, ownerID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperr.NotFound("file owner not found").WithDetails(map[string]string{"ownerType": ownerType, "ownerId": ownerID.String()})
+	}
+	return nil
+}
+
+// hashObject streams an object and returns its SHA-256 checksum and size.
+func (s *Service) hashObject(ctx context.Context, bucket, key string) (string, int64, error) {
+	reader, err := s.storage.DownloadFile(ctx, bucket, key)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, reader)
+	if err != nil {
+		return "", 0, fmt.Errorf("read object %s: %w", key, err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+func (s *Service) discard(ctx context.Context, organizationID uuid.UUID, file repository.File) {
+	if _, referenced, err := s.repo.DeleteFile(ctx, organizationID, file.ID); err != nil || referenced {
+		return
+	}
+	if err := s.storage.DeleteObject(ctx, file.Bucket, file.FileKey); err != nil {
+		s.log.Warn("files: failed to delete rejected upload", "fileId", file.ID, "error", err)
+	}
+}
+
+func toFileResponse(f repository.File) transport.FileResponse {
+	return transport.FileResponse{
+		ID:             f.ID,
+		OwnerType:      f.OwnerType,
+		OwnerID:        f.OwnerID,
+		FileName:       f.FileName,
+		ContentType:    f.ContentType,
+		SizeBytes:      f.SizeBytes,
+		ChecksumSHA256: f.ChecksumSHA256,
+		Status:         f.Status,
+		CreatedAt:      f.CreatedAt,
+		UploadedAt:     f.UploadedAt,
+	}
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/files/repository"
+	"portal_final_backend/internal/files/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const testBucket = "files"
+
+type memoryRepo struct {
+	files map[uuid.UUID]repository.File
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{files: map[uuid.UUID]repository.File{}}
+}
+
+func (r *memoryRepo) add(f repository.File) repository.File {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = time.Now()
+	}
+	r.files[f.ID] = f
+	return f
+}
+
+func (r *memoryRepo) OwnerExists(context.Context, uuid.UUID, string, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (r *memoryRepo) CreateFile(_ context.Context, f repository.File) (repository.File, error) {
+	return r.add(f), nil
+}
+
+func (r *memoryRepo) GetFile(_ context.Context, organizationID, id uuid.UUID) (repository.File, error) {
+	f, ok := r.files[id]
+	if !ok || f.OrganizationID != organizationID {
+		return repository.File{}, apperr.NotFound("file not found")
+	}
+	return f, nil
+}
+
+func (r *memoryRepo) FindReadyByChecksum(_ context.Context, organizationID uuid.UUID, checksum string) (*repository.File, error) {
+	for _, f := range r.files {
+		if f.OrganizationID == organizationID && f.ChecksumSHA256 == checksum && f.Status == repository.StatusReady {
+			return &f, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRepo) MarkReady(_ context.Context, organizationID, id uuid.UUID) (repository.File, error) {
+	f, err := r.GetFile(context.Background(), organizationID, id)
+	if err != nil {
+		return repository.File{}, err
+	}
+	now := time.Now()
+	f.Status = repository.StatusReady
+	f.UploadedAt = &now
+	r.files[id] = f
+	return f, nil
+}
+
+func (r *memoryRepo) ListByOwner(context.Context, uuid.UUID, string, uuid.UUID) ([]repository.File, error) {
+	return nil, nil
+}
+
+func (r *memoryRepo) DeleteFile(_ context.Context, organizationID, id uuid.UUID) (repository.File, bool, error) {
+	f, err := r.GetFile(context.Background(), organizationID, id)
+	if err != nil {
+		return repository.File{}, false, err
+	}
+	delete(r.files, id)
+	return f, r.referenced(f.Bucket, f.FileKey), nil
+}
+
+func (r *memoryRepo) DeleteStalePending(_ context.Context, before time.Time, limit int) ([]repository.File, error) {
+	var removed []repository.File
+	for id, f := range r.files {
+		if len(removed) == limit {
+			break
+		}
+		if f.Status == repository.StatusPending && f.CreatedAt.Before(before) {
+			delete(r.files, id)
+			removed = append(removed, f)
+		}
+	}
+	unreferenced := removed[:0]
+	for _, f := range removed {
+		if !r.referenced(f.Bucket, f.FileKey) {
+			unreferenced = append(unreferenced, f)
+		}
+	}
+	return unreferenced, nil
+}
+
+func (r *memoryRepo) ReferencedKeys(_ context.Context, bucket string, keys []string) (map[string]bool, error) {
+	referenced := map[string]bool{}
+	for _, key := range keys {
+		if r.referenced(bucket, key) {
+			referenced[key] = true
+		}
+	}
+	return referenced, nil
+}
+
+func (r *memoryRepo) GetUsage(context.Context, uuid.UUID) (repository.Usage, error) {
+	return repository.Usage{}, nil
+}
+
+func (r *memoryRepo) referenced(bucket, key string) bool {
+	for _, f := range r.files {
+		if f.Bucket == bucket && f.FileKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryStorage keeps objects in memory and lists them for the orphan cleanup.
+type memoryStorage struct {
+	storage.StorageService
+	objects  map[string][]byte
+	modified map[string]time.Time
+	deleted  []string
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{objects: map[string][]byte{}, modified: map[string]time.Time{}}
+}
+
+func (m *memoryStorage) put(key string, data []byte, modified time.Time) {
+	m.objects[key] = data
+	m.modified[key] = modified
+}
+
+func (m *memoryStorage) ValidateContentType(string) error { return nil }
+func (m *memoryStorage) ValidateFileSize(int64) error     { return nil }
+
+func (m *memoryStorage) GenerateUploadURL(_ context.Context, _, folder, fileName, _ string, _ int64) (*storage.PresignedURL, error) {
+	key := folder + "/" + uuid.NewString() + "-" + fileName
+	return &storage.PresignedURL{URL: "https://storage.example/" + key, FileKey: key, ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func (m *memoryStorage) DownloadFile(_ context.Context, _, fileKey string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.objects[fileKey])), nil
+}
+
+func (m *memoryStorage) DeleteObject(_ context.Context, _, fileKey string) error {
+	delete(m.objects, fileKey)
+	m.deleted = append(m.deleted, fileKey)
+	return nil
+}
+
+func (m *memoryStorage) StatObject(_ context.Context, _, fileKey string) (storage.ObjectInfo, error) {
+	return storage.ObjectInfo{Key: fileKey, Size: int64(len(m.objects[fileKey])), LastModified: m.modified[fileKey]}, nil
+}
+
+func (m *memoryStorage) ListObjects(_ context.Context, _, prefix string, fn func(storage.ObjectInfo) error) error {
+	for key, data := range m.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if err := fn(storage.ObjectInfo{Key: key, Size: int64(len(data)), LastModified: m.modified[key]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checksumOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRequestUploadReusesObjectWithSameContent(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newMemoryRepo(), newMemoryStorage()
+	svc := New(repo, store, testBucket, logger.New("development"))
+	organizationID := uuid.New()
+	content := []byte("offerte.pdf")
+
+	existing := repo.add(repository.File{
+		OrganizationID: organizationID,
+		OwnerType:      "lead",
+		OwnerID:        uuid.New(),
+		Bucket:         testBucket,
+		FileKey:        "org/lead/offerte.pdf",
+		SizeBytes:      int64(len(content)),
+		ChecksumSHA256: checksumOf(content),
+		Status:         repository.StatusReady,
+	})
+
+	resp, err := svc.RequestUpload(ctx, organizationID, uuid.New(), transport.RequestUploadRequest{
+		OwnerType:      "quote",
+		OwnerID:        uuid.New(),
+		FileName:       "offerte.pdf",
+		ContentType:    "application/pdf",
+		SizeBytes:      int64(len(content)),
+		ChecksumSHA256: strings.ToUpper(checksumOf(content)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Deduplicated || resp.UploadURL != "" || resp.File.Status != repository.StatusReady {
+		t.Fatalf("expected a ready file without upload URL, got %+v", resp)
+	}
+	if got := repo.files[resp.File.ID].FileKey; got != existing.FileKey {
+		t.Fatalf("expected the new file to share object %q, got %q", existing.FileKey, got)
+	}
+}
+
+func TestRequestUploadWithoutMatchingContentNeedsUpload(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("foto.jpg")
+
+	tests := []struct {
+		name     string
+		existing repository.File
+	}{
+		{name: "other checksum", existing: repository.File{ChecksumSHA256: checksumOf([]byte("other")), SizeBytes: int64(len(content)), Status: repository.StatusReady}},
+		{name: "other size", existing: repository.File{ChecksumSHA256: checksumOf(content), SizeBytes: int64(len(content)) + 1, Status: repository.StatusReady}},
+		{name: "pending upload", existing: repository.File{ChecksumSHA256: checksumOf(content), SizeBytes: int64(len(content)), Status: repository.StatusPending}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, store := newMemoryRepo(), newMemoryStorage()
+			svc := New(repo, store, testBucket, logger.New("development"))
+			organizationID := uuid.New()
+			tt.existing.OrganizationID = organizationID
+			tt.existing.Bucket = testBucket
+			tt.existing.FileKey = "org/lead/existing.jpg"
+			repo.add(tt.existing)
+
+			resp, err := svc.RequestUpload(ctx, organizationID, uuid.New(), transport.RequestUploadRequest{
+				OwnerType:      "lead",
+				OwnerID:        uuid.New(),
+				FileName:       "foto.jpg",
+				ContentType:    "image/jpeg",
+				SizeBytes:      int64(len(content)),
+				ChecksumSHA256: checksumOf(content),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Deduplicated || resp.UploadURL == "" || resp.File.Status != repository.StatusPending {
+				t.Fatalf("expected a pending file with an upload URL, got %+v", resp)
+			}
+			if got := repo.files[resp.File.ID].FileKey; got == tt.existing.FileKey {
+				t.Fatalf("expected a new object, got the existing key %q", got)
+			}
+		})
+	}
+}
+
+func TestCleanupOrphansSelectsStaleUploadsAndUnreferencedObjects(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newMemoryRepo(), newMemoryStorage()
+	svc := New(repo, store, testBucket, logger.New("development"))
+	organizationID := uuid.New()
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+
+	file := func(key, status string, createdAt time.Time) {
+		repo.add(repository.File{OrganizationID: organizationID, Bucket: testBucket, FileKey: key, Status: status, CreatedAt: createdAt})
+		store.put(key, []byte(key), createdAt)
+	}
+	file("stale-pending", repository.StatusPending, old)
+	file("recent-pending", repository.StatusPending, recent)
+	file("ready", repository.StatusReady, old)
+	// A stale upload that reused the object of a ready file keeps the object.
+	repo.add(repository.File{OrganizationID: organizationID, Bucket: testBucket, FileKey: "ready", Status: repository.StatusPending, CreatedAt: old})
+	store.put("orphan", []byte("orphan"), old)
+	store.put("recent-orphan", []byte("recent"), recent)
+
+	result, err := svc.CleanupOrphans(ctx, DefaultOrphanGracePeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StalePending != 1 || result.OrphanObjects != 1 || result.Failed != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	for _, key := range []string{"stale-pending", "orphan"} {
+		if _, ok := store.objects[key]; ok {
+			t.Errorf("expected object %q to be deleted", key)
+		}
+	}
+	for _, key := range []string{"recent-pending", "ready", "recent-orphan"} {
+		if _, ok := store.objects[key]; !ok {
+			t.Errorf("expected object %q to be kept", key)
+		}
+	}
+	if len(repo.files) != 2 {
+		t.Errorf("expected the recent pending and the ready file to remain, got %d files", len(repo.files))
+	}
+}
@@ -0,0 +1,75 @@
+// Package transport provides DTOs for the files domain.
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Owner types a file can be attached to.
+const (
+	OwnerLead           = "lead"
+	OwnerLeadService    = "lead_service"
+	OwnerQuote          = "quote"
+	OwnerCatalogProduct = "catalog_product"
+	OwnerPartner        = "partner"
+	OwnerOrganization   = "organization"
+)
+
+// RequestUploadRequest registers a file and asks for a presigned upload URL.
+// The SHA-256 checksum of the content is required for deduplication and verification.
+type RequestUploadRequest struct {
+	OwnerType      string    `json:"ownerType" validate:"required,oneof=lead lead_service quote catalog_product partner organization"`
+	OwnerID        uuid.UUID `json:"ownerId" validate:"required"`
+	FileName       string    `json:"fileName" validate:"required,min=1,max=255"`
+	ContentType    string    `json:"contentType" validate:"required,max=255"`
+	SizeBytes      int64     `json:"sizeBytes" validate:"required,gt=0"`
+	ChecksumSHA256 string    `json:"checksumSha256" validate:"required,len=64,hexadecimal"`
+}
+
+// ListFilesRequest selects the files of one owner.
+type ListFilesRequest struct {
+	OwnerType string `form:"ownerType" validate:"required,oneof=lead lead_service quote catalog_product partner organization"`
+	OwnerID   string `form:"ownerId" validate:"required,uuid"`
+}
+
+type FileResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	OwnerType      string     `json:"ownerType"`
+	OwnerID        uuid.UUID  `json:"ownerId"`
+	FileName       string     `json:"fileName"`
+	ContentType    string     `json:"contentType"`
+	SizeBytes      int64      `json:"sizeBytes"`
+	ChecksumSHA256 string     `json:"checksumSha256"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UploadedAt     *time.Time `json:"uploadedAt,omitempty"`
+}
+
+// UploadResponse is returned when an upload is requested. Deduplicated files are
+// ready immediately and carry no upload URL.
+type UploadResponse struct {
+	File         FileResponse `json:"file"`
+	UploadURL    string       `json:"uploadUrl,omitempty"`
+	ExpiresAt    *time.Time   `json:"expiresAt,omitempty"`
+	Deduplicated bool         `json:"deduplicated"`
+}
+
+type FileListResponse struct {
+	Items []FileResponse `json:"items"`
+}
+
+type DownloadURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// StorageUsageResponse reports the storage used by the files of an organization.
+type StorageUsageResponse struct {
+	Files         int64 `json:"files"`
+	StoredObjects int64 `json:"storedObjects"`
+	StoredBytes   int64 `json:"storedBytes"`
+	LogicalBytes  int64 `json:"logicalBytes"`
+	SavedBytes    int64 `json:"savedBytes"`
+}
@@ -0,0 +1,52 @@
+package graphapi
+
+import (
+	"net/http"
+
+	"portal_final_backend/platform/graphql"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	msgInvalidRequest = "invalid request"
+	maxQueryLength    = 16 * 1024
+)
+
+// Handler serves the read-only GraphQL endpoint.
+type Handler struct {
+	readers Readers
+	schema  *graphql.Schema
+}
+
+func NewHandler(readers Readers, schema *graphql.Schema) *Handler {
+	return &Handler{readers: readers, schema: schema}
+}
+
+// Query executes a GraphQL query for the caller's organization. Field errors are
+// reported in the response body next to partial data, as GraphQL clients expect.
+func (h *Handler) Query(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, err.Error())
+		return
+	}
+	if req.Query == "" {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, "query is required")
+		return
+	}
+	if len(req.Query) > maxQueryLength {
+		httpkit.Error(c, http.StatusRequestEntityTooLarge, msgInvalidRequest, "query is too large")
+		return
+	}
+
+	ctx := c.Request.Context()
+	ctx = withRequestScope(ctx, newRequestScope(ctx, h.readers, tenantID))
+	httpkit.OK(c, h.schema.Execute(ctx, req))
+}
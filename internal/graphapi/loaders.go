@@ -0,0 +1,137 @@
+package graphapi
+
+import (
+	"context"
+
+	appointmentsrepo "portal_final_backend/internal/appointments/repository"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	partnersrepo "portal_final_backend/internal/partners/repository"
+	quotesrepo "portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/platform/graphql"
+
+	"github.com/google/uuid"
+)
+
+// LeadReader is the subset of the leads repository used by the gateway.
+type LeadReader interface {
+	GetByID(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (leadsrepo.Lead, error)
+	List(ctx context.Context, params leadsrepo.ListParams) ([]leadsrepo.Lead, int, error)
+	ListLeadServicesByLeadIDs(ctx context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID) ([]leadsrepo.LeadService, error)
+	ListTimelineEventsByLeadIDs(ctx context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID, limitPerLead int) ([]leadsrepo.TimelineEvent, error)
+}
+
+// QuoteReader is the subset of the quotes repository used by the gateway.
+type QuoteReader interface {
+	ListByLeadIDs(ctx context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID) ([]quotesrepo.Quote, error)
+}
+
+// AppointmentReader is the subset of the appointments repository used by the gateway.
+type AppointmentReader interface {
+	ListByLeadIDs(ctx context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID) ([]appointmentsrepo.Appointment, error)
+}
+
+// OfferReader is the subset of the partners repository used by the gateway.
+type OfferReader interface {
+	ListOffersForServices(ctx context.Context, leadServiceIDs []uuid.UUID, organizationID uuid.UUID) ([]partnersrepo.PartnerOfferWithContext, error)
+}
+
+// Readers are the repositories the gateway resolves against.
+type Readers struct {
+	Leads        LeadReader
+	Quotes       QuoteReader
+	Appointments AppointmentReader
+	Offers       OfferReader
+}
+
+type timelineKey struct {
+	leadID uuid.UUID
+	limit  int
+}
+
+// requestScope carries the tenant and the per-request loaders. Every loader query is
+// scoped to the tenant of the authenticated user.
+type requestScope struct {
+	tenantID     uuid.UUID
+	services     *graphql.Loader[uuid.UUID, []leadsrepo.LeadService]
+	timeline     *graphql.Loader[timelineKey, []leadsrepo.TimelineEvent]
+	quotes       *graphql.Loader[uuid.UUID, []quotesrepo.Quote]
+	appointments *graphql.Loader[uuid.UUID, []appointmentsrepo.Appointment]
+	offers       *graphql.Loader[uuid.UUID, []partnersrepo.PartnerOfferWithContext]
+}
+
+type scopeKey struct{}
+
+func newRequestScope(ctx context.Context, readers Readers, tenantID uuid.UUID) *requestScope {
+	return &requestScope{
+		tenantID: tenantID,
+		services: graphql.NewLoader(ctx, func(ctx context.Context, leadIDs []uuid.UUID) (map[uuid.UUID][]leadsrepo.LeadService, error) {
+			items, err := readers.Leads.ListLeadServicesByLeadIDs(ctx, leadIDs, tenantID)
+			return groupBy(items, func(s leadsrepo.LeadService) uuid.UUID { return s.LeadID }), err
+		}),
+		timeline: graphql.NewLoader(ctx, func(ctx context.Context, keys []timelineKey) (map[timelineKey][]leadsrepo.TimelineEvent, error) {
+			return loadTimeline(ctx, readers.Leads, tenantID, keys)
+		}),
+		quotes: graphql.NewLoader(ctx, func(ctx context.Context, leadIDs []uuid.UUID) (map[uuid.UUID][]quotesrepo.Quote, error) {
+			items, err := readers.Quotes.ListByLeadIDs(ctx, leadIDs, tenantID)
+			return groupBy(items, func(q quotesrepo.Quote) uuid.UUID { return q.LeadID }), err
+		}),
+		appointments: graphql.NewLoader(ctx, func(ctx context.Context, leadIDs []uuid.UUID) (map[uuid.UUID][]appointmentsrepo.Appointment, error) {
+			items, err := readers.Appointments.ListByLeadIDs(ctx, leadIDs, tenantID)
+			return groupBy(items, func(a appointmentsrepo.Appointment) uuid.UUID {
+				if a.LeadID == nil {
+					return uuid.Nil
+				}
+				return *a.LeadID
+			}), err
+		}),
+		offers: graphql.NewLoader(ctx, func(ctx context.Context, serviceIDs []uuid.UUID) (map[uuid.UUID][]partnersrepo.PartnerOfferWithContext, error) {
+			items, err := readers.Offers.ListOffersForServices(ctx, serviceIDs, tenantID)
+			return groupBy(items, func(o partnersrepo.PartnerOfferWithContext) uuid.UUID { return o.LeadServiceID }), err
+		}),
+	}
+}
+
+func withRequestScope(ctx context.Context, scope *requestScope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+func scopeFrom(ctx context.Context) *requestScope {
+	scope, _ := ctx.Value(scopeKey{}).(*requestScope)
+	return scope
+}
+
+// loadTimeline fetches every requested lead with the largest requested limit and trims
+// each key to its own limit, so differing limit arguments still cost one query.
+func loadTimeline(ctx context.Context, leads LeadReader, tenantID uuid.UUID, keys []timelineKey) (map[timelineKey][]leadsrepo.TimelineEvent, error) {
+	leadIDs := make([]uuid.UUID, 0, len(keys))
+	seen := make(map[uuid.UUID]struct{}, len(keys))
+	maxLimit := 0
+	for _, key := range keys {
+		if _, ok := seen[key.leadID]; !ok {
+			seen[key.leadID] = struct{}{}
+			leadIDs = append(leadIDs, key.leadID)
+		}
+		maxLimit = max(maxLimit, key.limit)
+	}
+
+	events, err := leads.ListTimelineEventsByLeadIDs(ctx, leadIDs, tenantID, maxLimit)
+	if err != nil {
+		return nil, err
+	}
+	byLead := groupBy(events, func(e leadsrepo.TimelineEvent) uuid.UUID { return e.LeadID })
+	out := make(map[timelineKey][]leadsrepo.TimelineEvent, len(keys))
+	for _, key := range keys {
+		items := byLead[key.leadID]
+		out[key] = items[:min(len(items), key.limit)]
+	}
+	return out, nil
+}
+
+func groupBy[T any](items []T, key func(T) uuid.UUID) map[uuid.UUID][]T {
+	out := make(map[uuid.UUID][]T)
+	for _, item := range items {
+		k := key(item)
+		out[k] = append(out[k], item)
+	}
+	return out
+}
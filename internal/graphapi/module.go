@@ -0,0 +1,37 @@
+package graphapi
+
+import (
+	appointmentsrepo "portal_final_backend/internal/appointments/repository"
+	apphttp "portal_final_backend/internal/http"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	partnersrepo "portal_final_backend/internal/partners/repository"
+	quotesrepo "portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/platform/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Module exposes the read-only GraphQL gateway used by the agent dashboard.
+type Module struct {
+	handler *Handler
+}
+
+func NewModule(pool *pgxpool.Pool, log *logger.Logger) *Module {
+	readers := Readers{
+		Leads:        leadsrepo.New(pool),
+		Quotes:       quotesrepo.New(pool),
+		Appointments: appointmentsrepo.New(pool),
+		Offers:       partnersrepo.New(pool),
+	}
+	return &Module{handler: NewHandler(readers, newSchema(readers, log))}
+}
+
+func (m *Module) Name() string {
+	return "graphql"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	ctx.ProtectedVersions().POST("/graphql", m.handler.Query, nil)
+}
+
+var _ apphttp.Module = (*Module)(nil)
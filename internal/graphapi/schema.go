@@ -0,0 +1,254 @@
+package graphapi
+
+import (
+	"errors"
+
+	appointmentsrepo "portal_final_backend/internal/appointments/repository"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	partnersrepo "portal_final_backend/internal/partners/repository"
+	quotesrepo "portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/graphql"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize      = 20
+	maxPageSize          = 100
+	defaultTimelineLimit = 50
+	maxTimelineLimit     = 200
+	maxQueryDepth        = 6
+)
+
+type leadConnection struct {
+	items    []leadsrepo.Lead
+	total    int
+	page     int
+	pageSize int
+}
+
+// prop builds a field that reads a value from the source object.
+func prop[T any](t graphql.Type, get func(T) any) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(T)), nil
+	}}
+}
+
+// newSchema builds the read-only dashboard schema. Nested lists resolve through the
+// request scope's loaders, so each nested field costs one query per depth level.
+func newSchema(readers Readers, log *logger.Logger) *graphql.Schema {
+	partnerOffer := &graphql.Object{Name: "PartnerOffer", Fields: graphql.Fields{
+		"id":                 prop(graphql.NonNullOf(graphql.ID), func(o partnersrepo.PartnerOfferWithContext) any { return o.ID }),
+		"partnerId":          prop(graphql.NonNullOf(graphql.ID), func(o partnersrepo.PartnerOfferWithContext) any { return o.PartnerID }),
+		"partnerName":        prop(graphql.NonNullOf(graphql.String), func(o partnersrepo.PartnerOfferWithContext) any { return o.PartnerName }),
+		"serviceId":          prop(graphql.NonNullOf(graphql.ID), func(o partnersrepo.PartnerOfferWithContext) any { return o.LeadServiceID }),
+		"status":             prop(graphql.NonNullOf(graphql.String), func(o partnersrepo.PartnerOfferWithContext) any { return o.Status }),
+		"customerPriceCents": prop(graphql.NonNullOf(graphql.Int), func(o partnersrepo.PartnerOfferWithContext) any { return o.CustomerPriceCents }),
+		"vakmanPriceCents":   prop(graphql.NonNullOf(graphql.Int), func(o partnersrepo.PartnerOfferWithContext) any { return o.VakmanPriceCents }),
+		"expiresAt":          prop(graphql.NonNullOf(graphql.DateTime), func(o partnersrepo.PartnerOfferWithContext) any { return o.ExpiresAt }),
+		"acceptedAt":         prop(graphql.DateTime, func(o partnersrepo.PartnerOfferWithContext) any { return o.AcceptedAt }),
+		"rejectedAt":         prop(graphql.DateTime, func(o partnersrepo.PartnerOfferWithContext) any { return o.RejectedAt }),
+		"createdAt":          prop(graphql.NonNullOf(graphql.DateTime), func(o partnersrepo.PartnerOfferWithContext) any { return o.CreatedAt }),
+	}}
+
+	leadService := &graphql.Object{Name: "LeadService", Fields: graphql.Fields{
+		"id":            prop(graphql.NonNullOf(graphql.ID), func(s leadsrepo.LeadService) any { return s.ID }),
+		"serviceType":   prop(graphql.NonNullOf(graphql.String), func(s leadsrepo.LeadService) any { return s.ServiceType }),
+		"status":        prop(graphql.NonNullOf(graphql.String), func(s leadsrepo.LeadService) any { return s.Status }),
+		"pipelineStage": prop(graphql.NonNullOf(graphql.String), func(s leadsrepo.LeadService) any { return s.PipelineStage }),
+		"consumerNote":  prop(graphql.String, func(s leadsrepo.LeadService) any { return s.ConsumerNote }),
+		"source":        prop(graphql.String, func(s leadsrepo.LeadService) any { return s.Source }),
+		"createdAt":     prop(graphql.NonNullOf(graphql.DateTime), func(s leadsrepo.LeadService) any { return s.CreatedAt }),
+		"updatedAt":     prop(graphql.NonNullOf(graphql.DateTime), func(s leadsrepo.LeadService) any { return s.UpdatedAt }),
+		"partnerOffers": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(partnerOffer))),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return scopeFrom(p.Context).offers.Load(p.Source.(leadsrepo.LeadService).ID), nil
+			},
+		},
+	}}
+
+	timelineEvent := &graphql.Object{Name: "TimelineEvent", Fields: graphql.Fields{
+		"id":         prop(graphql.NonNullOf(graphql.ID), func(e leadsrepo.TimelineEvent) any { return e.ID }),
+		"serviceId":  prop(graphql.ID, func(e leadsrepo.TimelineEvent) any { return e.ServiceID }),
+		"actorType":  prop(graphql.NonNullOf(graphql.String), func(e leadsrepo.TimelineEvent) any { return e.ActorType }),
+		"actorName":  prop(graphql.NonNullOf(graphql.String), func(e leadsrepo.TimelineEvent) any { return e.ActorName }),
+		"eventType":  prop(graphql.NonNullOf(graphql.String), func(e leadsrepo.TimelineEvent) any { return e.EventType }),
+		"title":      prop(graphql.NonNullOf(graphql.String), func(e leadsrepo.TimelineEvent) any { return e.Title }),
+		"summary":    prop(graphql.String, func(e leadsrepo.TimelineEvent) any { return e.Summary }),
+		"visibility": prop(graphql.NonNullOf(graphql.String), func(e leadsrepo.TimelineEvent) any { return e.Visibility }),
+		"createdAt":  prop(graphql.NonNullOf(graphql.DateTime), func(e leadsrepo.TimelineEvent) any { return e.CreatedAt }),
+	}}
+
+	quote := &graphql.Object{Name: "Quote", Fields: graphql.Fields{
+		"id":                  prop(graphql.NonNullOf(graphql.ID), func(q quotesrepo.Quote) any { return q.ID }),
+		"quoteNumber":         prop(graphql.NonNullOf(graphql.String), func(q quotesrepo.Quote) any { return q.QuoteNumber }),
+		"serviceId":           prop(graphql.ID, func(q quotesrepo.Quote) any { return q.LeadServiceID }),
+		"status":              prop(graphql.NonNullOf(graphql.String), func(q quotesrepo.Quote) any { return q.Status }),
+		"subtotalCents":       prop(graphql.NonNullOf(graphql.Int), func(q quotesrepo.Quote) any { return q.SubtotalCents }),
+		"discountAmountCents": prop(graphql.NonNullOf(graphql.Int), func(q quotesrepo.Quote) any { return q.DiscountAmountCents }),
+		"taxTotalCents":       prop(graphql.NonNullOf(graphql.Int), func(q quotesrepo.Quote) any { return q.TaxTotalCents }),
+		"totalCents":          prop(graphql.NonNullOf(graphql.Int), func(q quotesrepo.Quote) any { return q.TotalCents }),
+		"validUntil":          prop(graphql.DateTime, func(q quotesrepo.Quote) any { return q.ValidUntil }),
+		"viewedAt":            prop(graphql.DateTime, func(q quotesrepo.Quote) any { return q.ViewedAt }),
+		"acceptedAt":          prop(graphql.DateTime, func(q quotesrepo.Quote) any { return q.AcceptedAt }),
+		"rejectedAt":          prop(graphql.DateTime, func(q quotesrepo.Quote) any { return q.RejectedAt }),
+		"createdAt":           prop(graphql.NonNullOf(graphql.DateTime), func(q quotesrepo.Quote) any { return q.CreatedAt }),
+		"updatedAt":           prop(graphql.NonNullOf(graphql.DateTime), func(q quotesrepo.Quote) any { return q.UpdatedAt }),
+	}}
+
+	appointment := &graphql.Object{Name: "Appointment", Fields: graphql.Fields{
+		"id":        prop(graphql.NonNullOf(graphql.ID), func(a appointmentsrepo.Appointment) any { return a.ID }),
+		"serviceId": prop(graphql.ID, func(a appointmentsrepo.Appointment) any { return a.LeadServiceID }),
+		"userId":    prop(graphql.NonNullOf(graphql.ID), func(a appointmentsrepo.Appointment) any { return a.UserID }),
+		"type":      prop(graphql.NonNullOf(graphql.String), func(a appointmentsrepo.Appointment) any { return a.Type }),
+		"title":     prop(graphql.NonNullOf(graphql.String), func(a appointmentsrepo.Appointment) any { return a.Title }),
+		"status":    prop(graphql.NonNullOf(graphql.String), func(a appointmentsrepo.Appointment) any { return a.Status }),
+		"location":  prop(graphql.String, func(a appointmentsrepo.Appointment) any { return a.Location }),
+		"startTime": prop(graphql.NonNullOf(graphql.DateTime), func(a appointmentsrepo.Appointment) any { return a.StartTime }),
+		"endTime":   prop(graphql.NonNullOf(graphql.DateTime), func(a appointmentsrepo.Appointment) any { return a.EndTime }),
+		"allDay":    prop(graphql.NonNullOf(graphql.Bool), func(a appointmentsrepo.Appointment) any { return a.AllDay }),
+	}}
+
+	lead := &graphql.Object{Name: "Lead", Fields: graphql.Fields{
+		"id":              prop(graphql.NonNullOf(graphql.ID), func(l leadsrepo.Lead) any { return l.ID }),
+		"firstName":       prop(graphql.NonNullOf(graphql.String), func(l leadsrepo.Lead) any { return l.ConsumerFirstName }),
+		"lastName":        prop(graphql.NonNullOf(graphql.String), func(l leadsrepo.Lead) any { return l.ConsumerLastName }),
+		"email":           prop(graphql.String, func(l leadsrepo.Lead) any { return l.ConsumerEmail }),
+		"phone":           prop(graphql.NonNullOf(graphql.String), func(l leadsrepo.Lead) any { return l.ConsumerPhone }),
+		"role":            prop(graphql.NonNullOf(graphql.String), func(l leadsrepo.Lead) any { return l.ConsumerRole }),
+		"street":          prop(graphql.NonNullOf(graphql.String), func(l leadsrepo.Lead) any { return l.AddressStreet }),
+		"houseNumber":     prop(graphql.NonNullOf(graphql.String), func(l leadsrepo.Lead) any { return l.AddressHouseNumber }),
+		"zipCode":         prop(graphql.NonNullOf(graphql.String), func(l leadsrepo.Lead) any { return l.AddressZipCode }),
+		"city":            prop(graphql.NonNullOf(graphql.String), func(l leadsrepo.Lead) any { return l.AddressCity }),
+		"latitude":        prop(graphql.Float, func(l leadsrepo.Lead) any { return l.Latitude }),
+		"longitude":       prop(graphql.Float, func(l leadsrepo.Lead) any { return l.Longitude }),
+		"source":          prop(graphql.String, func(l leadsrepo.Lead) any { return l.Source }),
+		"assignedAgentId": prop(graphql.ID, func(l leadsrepo.Lead) any { return l.AssignedAgentID }),
+		"whatsAppOptedIn": prop(graphql.NonNullOf(graphql.Bool), func(l leadsrepo.Lead) any { return l.WhatsAppOptedIn }),
+		"createdAt":       prop(graphql.NonNullOf(graphql.DateTime), func(l leadsrepo.Lead) any { return l.CreatedAt }),
+		"updatedAt":       prop(graphql.NonNullOf(graphql.DateTime), func(l leadsrepo.Lead) any { return l.UpdatedAt }),
+		"services": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(leadService))),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return scopeFrom(p.Context).services.Load(p.Source.(leadsrepo.Lead).ID), nil
+			},
+		},
+		"timeline": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(timelineEvent))),
+			Args: []graphql.Arg{{Name: "limit", Type: graphql.Int, Default: defaultTimelineLimit}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				limit, _ := p.Args["limit"].(int)
+				if limit < 1 || limit > maxTimelineLimit {
+					return nil, apperr.Validation("timeline limit must be between 1 and 200")
+				}
+				return scopeFrom(p.Context).timeline.Load(timelineKey{leadID: p.Source.(leadsrepo.Lead).ID, limit: limit}), nil
+			},
+		},
+		"quotes": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(quote))),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return scopeFrom(p.Context).quotes.Load(p.Source.(leadsrepo.Lead).ID), nil
+			},
+		},
+		"appointments": {
+			Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(appointment))),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return scopeFrom(p.Context).appointments.Load(p.Source.(leadsrepo.Lead).ID), nil
+			},
+		},
+	}}
+
+	leadConnectionType := &graphql.Object{Name: "LeadConnection", Fields: graphql.Fields{
+		"items":      prop(graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(lead))), func(c leadConnection) any { return c.items }),
+		"total":      prop(graphql.NonNullOf(graphql.Int), func(c leadConnection) any { return c.total }),
+		"page":       prop(graphql.NonNullOf(graphql.Int), func(c leadConnection) any { return c.page }),
+		"pageSize":   prop(graphql.NonNullOf(graphql.Int), func(c leadConnection) any { return c.pageSize }),
+		"totalPages": prop(graphql.NonNullOf(graphql.Int), func(c leadConnection) any { return (c.total + c.pageSize - 1) / c.pageSize }),
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: graphql.Fields{
+		"lead": {
+			Type: lead,
+			Args: []graphql.Arg{{Name: "id", Type: graphql.NonNullOf(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				id, err := uuid.Parse(p.Args["id"].(string))
+				if err != nil {
+					return nil, apperr.Validation("id must be a valid UUID")
+				}
+				item, err := readers.Leads.GetByID(p.Context, id, scopeFrom(p.Context).tenantID)
+				if errors.Is(err, leadsrepo.ErrNotFound) {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				return item, nil
+			},
+		},
+		"leads": {
+			Type: graphql.NonNullOf(leadConnectionType),
+			Args: []graphql.Arg{
+				{Name: "page", Type: graphql.Int, Default: 1},
+				{Name: "pageSize", Type: graphql.Int, Default: defaultPageSize},
+				{Name: "search", Type: graphql.String},
+				{Name: "status", Type: graphql.String},
+				{Name: "assignedAgentId", Type: graphql.ID},
+			},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return listLeads(p, readers.Leads)
+			},
+		},
+	}}
+
+	return &graphql.Schema{Query: query, MaxDepth: maxQueryDepth, FormatError: errorFormatter(log)}
+}
+
+func listLeads(p graphql.ResolveParams, leads LeadReader) (any, error) {
+	page, _ := p.Args["page"].(int)
+	pageSize, _ := p.Args["pageSize"].(int)
+	page = max(page, 1)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	params := leadsrepo.ListParams{
+		OrganizationID: scopeFrom(p.Context).tenantID,
+		Offset:         (page - 1) * pageSize,
+		Limit:          pageSize,
+	}
+	if search, ok := p.Args["search"].(string); ok {
+		params.Search = search
+	}
+	if status, ok := p.Args["status"].(string); ok {
+		params.Status = &status
+	}
+	if raw, ok := p.Args["assignedAgentId"].(string); ok {
+		agentID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, apperr.Validation("assignedAgentId must be a valid UUID")
+		}
+		params.AssignedAgentID = &agentID
+	}
+
+	items, total, err := leads.List(p.Context, params)
+	if err != nil {
+		return nil, err
+	}
+	return leadConnection{items: items, total: total, page: page, pageSize: pageSize}, nil
+}
+
+// errorFormatter exposes domain error messages and hides everything else.
+func errorFormatter(log *logger.Logger) func(error) string {
+	return func(err error) string {
+		var domainErr *apperr.Error
+		if errors.As(err, &domainErr) && domainErr.Kind != apperr.KindInternal {
+			return domainErr.Message
+		}
+		log.Error("graphql field failed", "error", err)
+		return "internal error"
+	}
+}
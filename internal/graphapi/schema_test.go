@@ -0,0 +1,215 @@
+package graphapi
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	appointmentsrepo "portal_final_backend/internal/appointments/repository"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	partnersrepo "portal_final_backend/internal/partners/repository"
+	quotesrepo "portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/platform/graphql"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+type fakeReaders struct {
+	tenantID uuid.UUID
+	leads    []leadsrepo.Lead
+	services []leadsrepo.LeadService
+	events   []leadsrepo.TimelineEvent
+	calls    map[string]int
+	tenants  []uuid.UUID
+}
+
+func (f *fakeReaders) record(name string, tenantID uuid.UUID) {
+	f.calls[name]++
+	f.tenants = append(f.tenants, tenantID)
+}
+
+func (f *fakeReaders) GetByID(_ context.Context, id uuid.UUID, organizationID uuid.UUID) (leadsrepo.Lead, error) {
+	f.record("GetByID", organizationID)
+	for _, lead := range f.leads {
+		if lead.ID == id && lead.OrganizationID == organizationID {
+			return lead, nil
+		}
+	}
+	return leadsrepo.Lead{}, leadsrepo.ErrNotFound
+}
+
+func (f *fakeReaders) List(_ context.Context, params leadsrepo.ListParams) ([]leadsrepo.Lead, int, error) {
+	f.record("List", params.OrganizationID)
+	return f.leads, len(f.leads), nil
+}
+
+func (f *fakeReaders) ListLeadServicesByLeadIDs(_ context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID) ([]leadsrepo.LeadService, error) {
+	f.record("ListLeadServicesByLeadIDs", organizationID)
+	var out []leadsrepo.LeadService
+	for _, svc := range f.services {
+		if slices.Contains(leadIDs, svc.LeadID) {
+			out = append(out, svc)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeReaders) ListTimelineEventsByLeadIDs(_ context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID, limitPerLead int) ([]leadsrepo.TimelineEvent, error) {
+	f.record("ListTimelineEventsByLeadIDs", organizationID)
+	perLead := make(map[uuid.UUID]int)
+	var out []leadsrepo.TimelineEvent
+	for _, event := range f.events {
+		if slices.Contains(leadIDs, event.LeadID) && perLead[event.LeadID] < limitPerLead {
+			perLead[event.LeadID]++
+			out = append(out, event)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeReaders) ListByLeadIDs(_ context.Context, _ []uuid.UUID, organizationID uuid.UUID) ([]quotesrepo.Quote, error) {
+	f.record("Quotes.ListByLeadIDs", organizationID)
+	return nil, nil
+}
+
+type fakeAppointments struct{ *fakeReaders }
+
+func (f fakeAppointments) ListByLeadIDs(_ context.Context, _ []uuid.UUID, organizationID uuid.UUID) ([]appointmentsrepo.Appointment, error) {
+	f.record("Appointments.ListByLeadIDs", organizationID)
+	return nil, nil
+}
+
+func (f *fakeReaders) ListOffersForServices(_ context.Context, serviceIDs []uuid.UUID, organizationID uuid.UUID) ([]partnersrepo.PartnerOfferWithContext, error) {
+	f.record("ListOffersForServices", organizationID)
+	out := make([]partnersrepo.PartnerOfferWithContext, 0, len(serviceIDs))
+	for _, id := range serviceIDs {
+		out = append(out, partnersrepo.PartnerOfferWithContext{
+			PartnerOffer: partnersrepo.PartnerOffer{ID: uuid.New(), LeadServiceID: id, Status: "pending"},
+			PartnerName:  "Dakwerken BV",
+		})
+	}
+	return out, nil
+}
+
+func newFakeReaders() *fakeReaders {
+	tenantID := uuid.New()
+	f := &fakeReaders{tenantID: tenantID, calls: make(map[string]int)}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		lead := leadsrepo.Lead{ID: uuid.New(), OrganizationID: tenantID, ConsumerFirstName: "Lead", CreatedAt: now, UpdatedAt: now}
+		f.leads = append(f.leads, lead)
+		for range 2 {
+			f.services = append(f.services, leadsrepo.LeadService{ID: uuid.New(), LeadID: lead.ID, ServiceType: "Dakisolatie", Status: "New", CreatedAt: now, UpdatedAt: now})
+		}
+		for j := range i + 3 {
+			f.events = append(f.events, leadsrepo.TimelineEvent{ID: uuid.New(), LeadID: lead.ID, Title: strings.Repeat("e", j+1), CreatedAt: now})
+		}
+	}
+	return f
+}
+
+func executeTestQuery(t *testing.T, f *fakeReaders, query string) map[string]any {
+	t.Helper()
+	readers := Readers{Leads: f, Quotes: f, Appointments: fakeAppointments{f}, Offers: f}
+	schema := newSchema(readers, logger.New("development"))
+
+	ctx := context.Background()
+	ctx = withRequestScope(ctx, newRequestScope(ctx, readers, f.tenantID))
+	result := schema.Execute(ctx, graphql.Request{Query: query})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", result.Errors[0])
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded.Data
+}
+
+func TestLeadsQueryBatchesNestedFieldsPerLevel(t *testing.T) {
+	t.Parallel()
+
+	f := newFakeReaders()
+	data := executeTestQuery(t, f, `{
+		leads(pageSize: 10) {
+			total
+			items {
+				id
+				services { id partnerOffers { partnerName } }
+				recent: timeline(limit: 2) { title }
+				timeline(limit: 4) { title }
+				quotes { id }
+				appointments { id }
+			}
+		}
+	}`)
+
+	want := map[string]int{
+		"List":                        1,
+		"ListLeadServicesByLeadIDs":   1,
+		"ListTimelineEventsByLeadIDs": 1,
+		"Quotes.ListByLeadIDs":        1,
+		"Appointments.ListByLeadIDs":  1,
+		"ListOffersForServices":       1,
+	}
+	for name, count := range want {
+		if f.calls[name] != count {
+			t.Errorf("expected %d call(s) to %s, got %d", count, name, f.calls[name])
+		}
+	}
+	for _, tenantID := range f.tenants {
+		if tenantID != f.tenantID {
+			t.Fatalf("expected every read to be scoped to %s, got %s", f.tenantID, tenantID)
+		}
+	}
+
+	items := data["leads"].(map[string]any)["items"].([]any)
+	if len(items) != 3 {
+		t.Fatalf("expected 3 leads, got %d", len(items))
+	}
+	for i, raw := range items {
+		item := raw.(map[string]any)
+		services := item["services"].([]any)
+		if len(services) != 2 {
+			t.Fatalf("lead %d: expected 2 services, got %d", i, len(services))
+		}
+		offers := services[0].(map[string]any)["partnerOffers"].([]any)
+		if len(offers) != 1 {
+			t.Fatalf("lead %d: expected 1 offer per service, got %d", i, len(offers))
+		}
+		if got := len(item["recent"].([]any)); got != 2 {
+			t.Errorf("lead %d: expected recent timeline trimmed to 2, got %d", i, got)
+		}
+		if got := len(item["timeline"].([]any)); got != min(i+3, 4) {
+			t.Errorf("lead %d: expected %d timeline events, got %d", i, min(i+3, 4), got)
+		}
+	}
+}
+
+func TestLeadQueryReturnsNullOutsideTenant(t *testing.T) {
+	t.Parallel()
+
+	f := newFakeReaders()
+	other := newFakeReaders()
+	data := executeTestQuery(t, f, `{ lead(id: "`+other.leads[0].ID.String()+`") { id } }`)
+	if data["lead"] != nil {
+		t.Fatalf("expected lead of another tenant to resolve to null, got %v", data["lead"])
+	}
+
+	data = executeTestQuery(t, f, `{ lead(id: "`+f.leads[1].ID.String()+`") { id firstName } }`)
+	lead := data["lead"].(map[string]any)
+	if lead["id"] != f.leads[1].ID.String() || lead["firstName"] != "Lead" {
+		t.Fatalf("unexpected lead payload: %v", lead)
+	}
+}
@@ -0,0 +1,237 @@
+// Package grpcapi exposes read-only gRPC APIs for internal service-to-service calls.
+// It runs on its own port next to the HTTP server, authenticates callers with mTLS and
+// calls the same service layer as the HTTP handlers.
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	internalapiv1 "portal_final_backend/internal/grpcapi/proto/internalapi/v1"
+	leadstransport "portal_final_backend/internal/leads/transport"
+	partnerstransport "portal_final_backend/internal/partners/transport"
+	quotestransport "portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// LeadReader is the subset of the leads management service exposed over gRPC.
+type LeadReader interface {
+	GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (leadstransport.LeadResponse, error)
+	List(ctx context.Context, req leadstransport.ListLeadsRequest, tenantID uuid.UUID) (leadstransport.LeadListResponse, error)
+}
+
+// QuoteReader is the subset of the quotes service exposed over gRPC.
+type QuoteReader interface {
+	GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*quotestransport.QuoteResponse, error)
+	List(ctx context.Context, tenantID uuid.UUID, req quotestransport.ListQuotesRequest) (*quotestransport.QuoteListResponse, error)
+}
+
+// PartnerReader is the subset of the partners service exposed over gRPC.
+type PartnerReader interface {
+	GetByID(ctx context.Context, tenantID uuid.UUID, id uuid.UUID) (partnerstransport.PartnerResponse, error)
+	List(ctx context.Context, tenantID uuid.UUID, req partnerstransport.ListPartnersRequest) (partnerstransport.ListPartnersResponse, error)
+}
+
+// Config holds the listener and mTLS settings. Client certificates are mandatory; when
+// AllowedClients is non-empty, the certificate common name or a DNS SAN must match.
+type Config struct {
+	Addr           string
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	AllowedClients []string
+}
+
+// TenantContextBinder routes the queries of a call to the data schema of the
+// organization it is scoped to.
+type TenantContextBinder func(ctx context.Context, organizationID uuid.UUID) (context.Context, error)
+
+// Dependencies are the services backing the gRPC APIs. BindTenant is optional.
+type Dependencies struct {
+	Leads      LeadReader
+	Quotes     QuoteReader
+	Partners   PartnerReader
+	BindTenant TenantContextBinder
+}
+
+// Server is the internal gRPC server.
+type Server struct {
+	cfg  Config
+	grpc *grpc.Server
+	log  *logger.Logger
+}
+
+// New builds the gRPC server and registers all services.
+func New(cfg Config, deps Dependencies, log *logger.Logger) (*Server, error) {
+	if deps.Leads == nil || deps.Quotes == nil || deps.Partners == nil {
+		return nil, errors.New("grpcapi: leads, quotes and partners services are required")
+	}
+	tlsConfig, err := loadServerTLS(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{cfg: cfg, log: log}
+	s.grpc = grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.ChainUnaryInterceptor(s.recoverInterceptor, s.authorizeInterceptor, s.errorInterceptor),
+	)
+	internalapiv1.RegisterLeadServiceServer(s.grpc, &leadServer{leads: deps.Leads, bind: deps.BindTenant})
+	internalapiv1.RegisterQuoteServiceServer(s.grpc, &quoteServer{quotes: deps.Quotes, bind: deps.BindTenant})
+	internalapiv1.RegisterPartnerServiceServer(s.grpc, &partnerServer{partners: deps.Partners, bind: deps.BindTenant})
+	return s, nil
+}
+
+// Serve listens on the configured address until ctx is cancelled, then stops gracefully.
+func (s *Server) Serve(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("grpcapi: listen on %s: %w", s.cfg.Addr, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		stopped := make(chan struct{})
+		go func() {
+			s.grpc.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			s.grpc.Stop()
+		}
+	}()
+
+	s.log.Info("grpc server listening", "addr", s.cfg.Addr)
+	if err := s.grpc.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+func loadServerTLS(cfg Config) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" || cfg.ClientCAFile == "" {
+		return nil, errors.New("grpcapi: GRPC_TLS_CERT_FILE, GRPC_TLS_KEY_FILE and GRPC_TLS_CLIENT_CA_FILE are required")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("grpcapi: load server certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("grpcapi: read client CA: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("grpcapi: client CA file contains no certificates")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func (s *Server) recoverInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("grpc handler panic", "method", info.FullMethod, "panic", r)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// authorizeInterceptor restricts calls to the configured client identities. The TLS
+// layer already verified the certificate chain.
+func (s *Server) authorizeInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	client, ok := clientIdentities(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "client certificate required")
+	}
+	if len(s.cfg.AllowedClients) > 0 && !slices.ContainsFunc(client, func(name string) bool {
+		return slices.Contains(s.cfg.AllowedClients, name)
+	}) {
+		s.log.Warn("grpc client not allowed", "method", info.FullMethod, "client", strings.Join(client, ","))
+		return nil, status.Error(codes.PermissionDenied, "client not allowed")
+	}
+	return handler(ctx, req)
+}
+
+// errorInterceptor maps domain errors to gRPC status codes and logs unexpected failures.
+func (s *Server) errorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, status.FromContextError(err).Err()
+	}
+	code := statusCode(apperr.GetKind(err))
+	if code == codes.Internal {
+		s.log.Error("grpc request failed", "method", info.FullMethod, "error", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	var domainErr *apperr.Error
+	if errors.As(err, &domainErr) {
+		return nil, status.Error(code, domainErr.Message)
+	}
+	return nil, status.Error(code, err.Error())
+}
+
+func statusCode(kind apperr.Kind) codes.Code {
+	switch kind {
+	case apperr.KindNotFound, apperr.KindGone:
+		return codes.NotFound
+	case apperr.KindValidation, apperr.KindBadRequest:
+		return codes.InvalidArgument
+	case apperr.KindConflict:
+		return codes.AlreadyExists
+	case apperr.KindForbidden:
+		return codes.PermissionDenied
+	case apperr.KindUnauthorized:
+		return codes.Unauthenticated
+	case apperr.KindTooManyRequests, apperr.KindPaymentRequired:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// clientIdentities returns the common name and DNS SANs of the verified client certificate.
+func clientIdentities(ctx context.Context) ([]string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	leaf := tlsInfo.State.VerifiedChains[0][0]
+	names := append([]string{leaf.Subject.CommonName}, leaf.DNSNames...)
+	return names, true
+}
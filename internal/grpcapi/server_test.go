@@ -0,0 +1,138 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+
+	internalapiv1 "portal_final_backend/internal/grpcapi/proto/internalapi/v1"
+	leadstransport "portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type fakeLeadReader struct {
+	lead     leadstransport.LeadResponse
+	err      error
+	tenantID uuid.UUID
+	schema   string
+	listReq  leadstransport.ListLeadsRequest
+}
+
+func (f *fakeLeadReader) GetByID(ctx context.Context, _ uuid.UUID, tenantID uuid.UUID) (leadstransport.LeadResponse, error) {
+	f.tenantID = tenantID
+	f.schema = db.SchemaFromContext(ctx)
+	return f.lead, f.err
+}
+
+func (f *fakeLeadReader) List(_ context.Context, req leadstransport.ListLeadsRequest, tenantID uuid.UUID) (leadstransport.LeadListResponse, error) {
+	f.tenantID = tenantID
+	f.listReq = req
+	return leadstransport.LeadListResponse{Items: []leadstransport.LeadResponse{f.lead}, Total: 1, Page: req.Page, PageSize: req.PageSize, TotalPages: 1}, nil
+}
+
+func TestLeadServerScopesCallsToOrganization(t *testing.T) {
+	orgID := uuid.New()
+	leadStatus := leadstransport.LeadStatus("New")
+	reader := &fakeLeadReader{lead: leadstransport.LeadResponse{
+		ID:              uuid.New(),
+		Consumer:        leadstransport.ConsumerResponse{FirstName: "Jan", LastName: "Jansen"},
+		AggregateStatus: &leadStatus,
+	}}
+	srv := &leadServer{leads: reader}
+
+	lead, err := srv.GetLead(context.Background(), &internalapiv1.GetLeadRequest{OrganizationId: orgID.String(), Id: reader.lead.ID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.tenantID != orgID || lead.GetFirstName() != "Jan" || lead.GetStatus() != "New" {
+		t.Fatalf("unexpected lead %+v for tenant %s", lead, reader.tenantID)
+	}
+
+	list, err := srv.ListLeads(context.Background(), &internalapiv1.ListLeadsRequest{OrganizationId: orgID.String(), PageSize: 500})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.listReq.Page != 1 || reader.listReq.PageSize != maxPageSize || len(list.GetLeads()) != 1 {
+		t.Fatalf("expected clamped pagination, got page %d size %d", reader.listReq.Page, reader.listReq.PageSize)
+	}
+
+	if _, err := srv.GetLead(context.Background(), &internalapiv1.GetLeadRequest{OrganizationId: "nope", Id: reader.lead.ID.String()}); !apperr.Is(err, apperr.KindValidation) {
+		t.Fatalf("expected validation error for invalid organization id, got %v", err)
+	}
+}
+
+func TestLeadServerBindsOrganizationSchema(t *testing.T) {
+	orgID := uuid.New()
+	reader := &fakeLeadReader{lead: leadstransport.LeadResponse{ID: uuid.New()}}
+	srv := &leadServer{leads: reader, bind: func(ctx context.Context, organizationID uuid.UUID) (context.Context, error) {
+		if organizationID != orgID {
+			t.Fatalf("bound organization %s, want %s", organizationID, orgID)
+		}
+		return db.WithSchema(ctx, "tenant_abc"), nil
+	}}
+
+	if _, err := srv.GetLead(context.Background(), &internalapiv1.GetLeadRequest{OrganizationId: orgID.String(), Id: reader.lead.ID.String()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.schema != "tenant_abc" {
+		t.Fatalf("lead read in schema %q, want tenant_abc", reader.schema)
+	}
+
+	srv.bind = func(ctx context.Context, _ uuid.UUID) (context.Context, error) {
+		return ctx, errors.New("placement lookup failed")
+	}
+	_, err := srv.GetLead(context.Background(), &internalapiv1.GetLeadRequest{OrganizationId: orgID.String(), Id: reader.lead.ID.String()})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable when the organization cannot be bound, got %v", err)
+	}
+}
+
+func TestErrorInterceptorMapsDomainErrors(t *testing.T) {
+	s := &Server{log: logger.New("development")}
+	info := &grpc.UnaryServerInfo{FullMethod: internalapiv1.LeadService_GetLead_FullMethodName}
+
+	_, err := s.errorInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, apperr.NotFound("lead not found")
+	})
+	if st, _ := status.FromError(err); st.Code() != codes.NotFound || st.Message() != "lead not found" {
+		t.Fatalf("expected NotFound status, got %v", err)
+	}
+
+	_, err = s.errorInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, context.DeadlineExceeded
+	})
+	if st, _ := status.FromError(err); st.Code() != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded status, got %v", err)
+	}
+}
+
+func TestAuthorizeInterceptorChecksClientCertificateNames(t *testing.T) {
+	s := &Server{cfg: Config{AllowedClients: []string{"reporting"}}, log: logger.New("development")}
+	info := &grpc.UnaryServerInfo{FullMethod: internalapiv1.QuoteService_ListQuotes_FullMethodName}
+	ok := func(context.Context, any) (any, error) { return "ok", nil }
+
+	if _, err := s.authorizeInterceptor(context.Background(), nil, info, ok); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without a peer certificate, got %v", err)
+	}
+
+	for name, want := range map[string]codes.Code{"reporting": codes.OK, "billing": codes.PermissionDenied} {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: name}}}},
+		}}})
+		if _, err := s.authorizeInterceptor(ctx, nil, info, ok); status.Code(err) != want {
+			t.Fatalf("client %q: expected %v, got %v", name, want, err)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	internalapiv1 "portal_final_backend/internal/grpcapi/proto/internalapi/v1"
+	leadstransport "portal_final_backend/internal/leads/transport"
+	partnerstransport "portal_final_backend/internal/partners/transport"
+	quotestransport "portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type leadServer struct {
+	internalapiv1.UnimplementedLeadServiceServer
+	leads LeadReader
+	bind  TenantContextBinder
+}
+
+func (s *leadServer) GetLead(ctx context.Context, req *internalapiv1.GetLeadRequest) (*internalapiv1.Lead, error) {
+	orgID, id, err := parseScopedID(req.GetOrganizationId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = bindTenant(ctx, s.bind, orgID); err != nil {
+		return nil, err
+	}
+	lead, err := s.leads.GetByID(ctx, id, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return toProtoLead(lead), nil
+}
+
+func (s *leadServer) ListLeads(ctx context.Context, req *internalapiv1.ListLeadsRequest) (*internalapiv1.ListLeadsResponse, error) {
+	orgID, err := parseUUID(req.GetOrganizationId(), "organization_id")
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = bindTenant(ctx, s.bind, orgID); err != nil {
+		return nil, err
+	}
+	page, pageSize := pagination(req.GetPage(), req.GetPageSize())
+	listReq := leadstransport.ListLeadsRequest{Search: req.GetSearch(), Page: page, PageSize: pageSize}
+	if req.GetStatus() != "" {
+		leadStatus := leadstransport.LeadStatus(req.GetStatus())
+		listReq.Status = &leadStatus
+	}
+	if req.GetAssignedAgentId() != "" {
+		agentID, err := parseUUID(req.GetAssignedAgentId(), "assigned_agent_id")
+		if err != nil {
+			return nil, err
+		}
+		listReq.AssignedAgentID = &agentID
+	}
+
+	result, err := s.leads.List(ctx, listReq, orgID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &internalapiv1.ListLeadsResponse{
+		Leads:      make([]*internalapiv1.Lead, 0, len(result.Items)),
+		Total:      int32(result.Total),
+		Page:       int32(result.Page),
+		PageSize:   int32(result.PageSize),
+		TotalPages: int32(result.TotalPages),
+	}
+	for _, lead := range result.Items {
+		resp.Leads = append(resp.Leads, toProtoLead(lead))
+	}
+	return resp, nil
+}
+
+type quoteServer struct {
+	internalapiv1.UnimplementedQuoteServiceServer
+	quotes QuoteReader
+	bind   TenantContextBinder
+}
+
+func (s *quoteServer) GetQuote(ctx context.Context, req *internalapiv1.GetQuoteRequest) (*internalapiv1.Quote, error) {
+	orgID, id, err := parseScopedID(req.GetOrganizationId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = bindTenant(ctx, s.bind, orgID); err != nil {
+		return nil, err
+	}
+	quote, err := s.quotes.GetByID(ctx, id, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return toProtoQuote(*quote), nil
+}
+
+func (s *quoteServer) ListQuotes(ctx context.Context, req *internalapiv1.ListQuotesRequest) (*internalapiv1.ListQuotesResponse, error) {
+	orgID, err := parseUUID(req.GetOrganizationId(), "organization_id")
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = bindTenant(ctx, s.bind, orgID); err != nil {
+		return nil, err
+	}
+	if req.GetLeadId() != "" {
+		if _, err := parseUUID(req.GetLeadId(), "lead_id"); err != nil {
+			return nil, err
+		}
+	}
+	page, pageSize := pagination(req.GetPage(), req.GetPageSize())
+	result, err := s.quotes.List(ctx, orgID, quotestransport.ListQuotesRequest{
+		LeadID:   req.GetLeadId(),
+		Status:   req.GetStatus(),
+		Search:   req.GetSearch(),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &internalapiv1.ListQuotesResponse{
+		Quotes:     make([]*internalapiv1.Quote, 0, len(result.Items)),
+		Total:      int32(result.Total),
+		Page:       int32(result.Page),
+		PageSize:   int32(result.PageSize),
+		TotalPages: int32(result.TotalPages),
+	}
+	for _, quote := range result.Items {
+		resp.Quotes = append(resp.Quotes, toProtoQuote(quote))
+	}
+	return resp, nil
+}
+
+type partnerServer struct {
+	internalapiv1.UnimplementedPartnerServiceServer
+	partners PartnerReader
+	bind     TenantContextBinder
+}
+
+func (s *partnerServer) GetPartner(ctx context.Context, req *internalapiv1.GetPartnerRequest) (*internalapiv1.Partner, error) {
+	orgID, id, err := parseScopedID(req.GetOrganizationId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = bindTenant(ctx, s.bind, orgID); err != nil {
+		return nil, err
+	}
+	partner, err := s.partners.GetByID(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toProtoPartner(partner), nil
+}
+
+func (s *partnerServer) ListPartners(ctx context.Context, req *internalapiv1.ListPartnersRequest) (*internalapiv1.ListPartnersResponse, error) {
+	orgID, err := parseUUID(req.GetOrganizationId(), "organization_id")
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = bindTenant(ctx, s.bind, orgID); err != nil {
+		return nil, err
+	}
+	page, pageSize := pagination(req.GetPage(), req.GetPageSize())
+	result, err := s.partners.List(ctx, orgID, partnerstransport.ListPartnersRequest{
+		Search:   req.GetSearch(),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &internalapiv1.ListPartnersResponse{
+		Partners:   make([]*internalapiv1.Partner, 0, len(result.Items)),
+		Total:      int32(result.Total),
+		Page:       int32(result.Page),
+		PageSize:   int32(result.PageSize),
+		TotalPages: int32(result.TotalPages),
+	}
+	for _, partner := range result.Items {
+		resp.Partners = append(resp.Partners, toProtoPartner(partner))
+	}
+	return resp, nil
+}
+
+func toProtoLead(lead leadstransport.LeadResponse) *internalapiv1.Lead {
+	out := &internalapiv1.Lead{
+		Id:        lead.ID.String(),
+		FirstName: lead.Consumer.FirstName,
+		LastName:  lead.Consumer.LastName,
+		Email:     stringValue(lead.Consumer.Email),
+		Phone:     lead.Consumer.Phone,
+		Address: &internalapiv1.Address{
+			Street:      lead.Address.Street,
+			HouseNumber: lead.Address.HouseNumber,
+			ZipCode:     lead.Address.ZipCode,
+			City:        lead.Address.City,
+		},
+		AssignedAgentId: uuidValue(lead.AssignedAgentID),
+		Source:          stringValue(lead.Source),
+		CreatedAt:       timestamppb.New(lead.CreatedAt),
+		UpdatedAt:       timestamppb.New(lead.UpdatedAt),
+	}
+	if lead.AggregateStatus != nil {
+		out.Status = string(*lead.AggregateStatus)
+	}
+	if lead.CurrentService != nil {
+		out.ServiceType = string(lead.CurrentService.ServiceType)
+	}
+	return out
+}
+
+func toProtoQuote(quote quotestransport.QuoteResponse) *internalapiv1.Quote {
+	return &internalapiv1.Quote{
+		Id:                  quote.ID.String(),
+		QuoteNumber:         quote.QuoteNumber,
+		LeadId:              quote.LeadID.String(),
+		LeadServiceId:       uuidValue(quote.LeadServiceID),
+		Status:              string(quote.Status),
+		SubtotalCents:       quote.SubtotalCents,
+		DiscountAmountCents: quote.DiscountAmountCents,
+		TaxTotalCents:       quote.TaxTotalCents,
+		TotalCents:          quote.TotalCents,
+		ValidUntil:          timestampValue(quote.ValidUntil),
+		AcceptedAt:          timestampValue(quote.AcceptedAt),
+		RejectedAt:          timestampValue(quote.RejectedAt),
+		CreatedAt:           timestamppb.New(quote.CreatedAt),
+		UpdatedAt:           timestamppb.New(quote.UpdatedAt),
+	}
+}
+
+func toProtoPartner(partner partnerstransport.PartnerResponse) *internalapiv1.Partner {
+	serviceTypeIDs := make([]string, 0, len(partner.ServiceTypeIDs))
+	for _, id := range partner.ServiceTypeIDs {
+		serviceTypeIDs = append(serviceTypeIDs, id.String())
+	}
+	return &internalapiv1.Partner{
+		Id:             partner.ID.String(),
+		BusinessName:   partner.BusinessName,
+		KvkNumber:      stringValue(partner.KVKNumber),
+		VatNumber:      stringValue(partner.VATNumber),
+		AddressLine1:   partner.AddressLine1,
+		HouseNumber:    stringValue(partner.HouseNumber),
+		PostalCode:     partner.PostalCode,
+		City:           partner.City,
+		Country:        partner.Country,
+		ContactName:    partner.ContactName,
+		ContactEmail:   partner.ContactEmail,
+		ContactPhone:   partner.ContactPhone,
+		ServiceTypeIds: serviceTypeIDs,
+		CreatedAt:      timestamppb.New(partner.CreatedAt),
+		UpdatedAt:      timestamppb.New(partner.UpdatedAt),
+	}
+}
+
+// bindTenant routes the queries of a call to the data schema of its
+// organization. Calls fail rather than read the shared schema when the
+// placement of the organization cannot be resolved.
+func bindTenant(ctx context.Context, bind TenantContextBinder, orgID uuid.UUID) (context.Context, error) {
+	if bind == nil {
+		return ctx, nil
+	}
+	bound, err := bind(ctx, orgID)
+	if err != nil {
+		return ctx, status.Error(codes.Unavailable, "organization data unavailable")
+	}
+	return bound, nil
+}
+
+func parseScopedID(orgIDText, idText string) (uuid.UUID, uuid.UUID, error) {
+	orgID, err := parseUUID(orgIDText, "organization_id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	id, err := parseUUID(idText, "id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return orgID, id, nil
+}
+
+func parseUUID(value, field string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, apperr.Validation(field + " must be a valid UUID")
+	}
+	return id, nil
+}
+
+func pagination(page, pageSize int32) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return int(page), int(pageSize)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func uuidValue(value *uuid.UUID) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+func timestampValue(value *time.Time) *timestamppb.Timestamp {
+	if value == nil {
+		return nil
+	}
+	return timestamppb.New(*value)
+}
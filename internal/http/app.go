@@ -6,6 +6,8 @@ import (
 	"portal_final_backend/internal/events"
 	"portal_final_backend/platform/config"
 	"portal_final_backend/platform/logger"
+
+	"github.com/gin-gonic/gin"
 )
 
 // RouterConfig combines the config interfaces needed by the HTTP router.
@@ -32,4 +34,7 @@ type App struct {
 	EventBus events.Bus
 	// Modules contains all HTTP-facing domain modules.
 	Modules []Module
+	// AuthenticatedMiddleware runs after authentication on every protected and
+	// admin route, e.g. to enforce subscription limits.
+	AuthenticatedMiddleware []gin.HandlerFunc
 }
@@ -27,12 +27,19 @@ type RouterContext struct {
 	Engine *gin.Engine
 	// V1 is the /api/v1 route group.
 	V1 *gin.RouterGroup
+	// Public is the unauthenticated /api/v1/public route group of the customer
+	// and partner portals. It resolves the organization of custom portal domains.
+	Public *gin.RouterGroup
 	// Protected is the authenticated route group under /api/v1.
 	Protected *gin.RouterGroup
 	// Admin is the admin-only route group under /api/v1/admin.
 	Admin *gin.RouterGroup
 	// SuperAdmin is the superadmin-only route group under /api/v1/superadmin.
 	SuperAdmin *gin.RouterGroup
+	// V2 is the /api/v2 route group.
+	V2 *gin.RouterGroup
+	// ProtectedV2 is the authenticated route group under /api/v2.
+	ProtectedV2 *gin.RouterGroup
 	// Config is the JWT configuration for auth middleware (scoped access).
 	Config config.JWTConfig
 	// AuthMiddleware provides the authentication middleware.
@@ -40,3 +47,13 @@ type RouterContext struct {
 	// AuthRateLimiter is the stricter rate limiter for auth routes.
 	AuthRateLimiter *httpkit.AuthRateLimiter
 }
+
+// PublicVersions returns the public route groups of all API versions.
+func (r *RouterContext) PublicVersions() VersionedGroup {
+	return VersionedGroup{V1: r.V1, V2: r.V2}
+}
+
+// ProtectedVersions returns the authenticated route groups of all API versions.
+func (r *RouterContext) ProtectedVersions() VersionedGroup {
+	return VersionedGroup{V1: r.Protected, V2: r.ProtectedV2}
+}
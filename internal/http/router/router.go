@@ -7,6 +7,7 @@ import (
 	"time"
 
 	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/platform/circuitbreaker"
 	"portal_final_backend/platform/config"
 	"portal_final_backend/platform/httpkit"
 
@@ -39,24 +40,45 @@ func New(app *apphttp.App) *gin.Engine {
 	globalLimiter := httpkit.NewIPRateLimiter(rate.Limit(100), 200, log)
 	engine.Use(globalLimiter.RateLimit())
 
+	// Request body limits; file upload routes get the larger upload limit
+	engine.Use(httpkit.BodyLimit(cfg.GetHTTPMaxBodyBytes(), bodyLimitRoutes(cfg)))
+
 	registerHealthRoute(engine, app)
 
-	// Set up route groups
-	v1 := engine.Group("/api/v1")
+	// Set up route groups. Every version gets its own tree so modules can register v1 and
+	// v2 handlers side by side; deprecation headers are driven by configuration.
+	v1 := engine.Group(apiVersionPrefix(apphttp.APIVersion1))
+	v1.Use(apiVersionMiddleware(engine, apphttp.APIVersion1, deprecationPolicy{
+		DeprecatedAt: cfg.GetAPIV1DeprecatedAt(),
+		SunsetAt:     cfg.GetAPIV1SunsetAt(),
+	}))
+	public := v1.Group("/public")
+	public.Use(httpkit.PortalHost(cfg.GetTrustedProxies()))
 	protected := v1.Group("")
 	protected.Use(httpkit.AuthRequired(cfg))
+	protected.Use(app.AuthenticatedMiddleware...)
 	admin := v1.Group("/admin")
 	admin.Use(httpkit.AuthRequired(cfg), httpkit.RequireRole("admin"))
+	admin.Use(app.AuthenticatedMiddleware...)
 	superAdmin := v1.Group("/superadmin")
 	superAdmin.Use(httpkit.AuthRequired(cfg), httpkit.RequireRole("superadmin"))
+	registerCircuitBreakerRoute(superAdmin)
+	v2 := engine.Group(apiVersionPrefix(apphttp.APIVersion2))
+	v2.Use(apiVersionMiddleware(engine, apphttp.APIVersion2, deprecationPolicy{}))
+	protectedV2 := v2.Group("")
+	protectedV2.Use(httpkit.AuthRequired(cfg))
+	protectedV2.Use(app.AuthenticatedMiddleware...)
 
 	// Router context provides shared dependencies to modules
 	routerCtx := &apphttp.RouterContext{
 		Engine:          engine,
 		V1:              v1,
+		Public:          public,
 		Protected:       protected,
 		Admin:           admin,
 		SuperAdmin:      superAdmin,
+		V2:              v2,
+		ProtectedV2:     protectedV2,
 		Config:          cfg,
 		AuthMiddleware:  httpkit.AuthRequired(cfg),
 		AuthRateLimiter: httpkit.NewAuthRateLimiter(log),
@@ -71,6 +93,32 @@ func New(app *apphttp.App) *gin.Engine {
 	return engine
 }
 
+// registerCircuitBreakerRoute exposes the state and counters of the circuit
+// breakers of this API process.
+func registerCircuitBreakerRoute(superAdmin *gin.RouterGroup) {
+	superAdmin.GET("/system/circuit-breakers", func(c *gin.Context) {
+		httpkit.OK(c, circuitbreaker.Snapshot())
+	})
+}
+
+// bodyLimitRoutes returns the per-route body limits: routes that accept files
+// get the upload limit, and HTTP_BODY_LIMITS overrides are applied on top.
+func bodyLimitRoutes(cfg config.HTTPConfig) map[string]int64 {
+	upload := cfg.GetHTTPMaxUploadBodyBytes()
+	routes := map[string]int64{
+		"*/upload":                            upload,
+		"/api/v1/webhook/*":                   upload,
+		"/api/v1/admin/catalog/price-feeds/*": upload,
+		"/api/v1/uploads/*":                   upload,
+		// Inbox attachments are sent base64 encoded inside the JSON body.
+		"/api/v1/whatsapp/conversations/*": upload,
+	}
+	for pattern, limit := range cfg.GetHTTPBodyLimits() {
+		routes[pattern] = limit
+	}
+	return routes
+}
+
 func webhookCorsBypass() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if strings.HasPrefix(c.Request.URL.Path, "/api/v1/webhook/") {
@@ -95,8 +143,8 @@ func webhookCorsBypass() gin.HandlerFunc {
 func buildCorsConfig(cfg config.HTTPConfig) cors.Config {
 	corsConfig := cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Webhook-API-Key", "X-Idempotency-Key"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Webhook-API-Key", "X-Idempotency-Key", "If-Match", "X-Branch-ID"},
+		ExposeHeaders:    append([]string{"Content-Length", "Content-Disposition", "ETag"}, versionHeaders...),
 		AllowCredentials: cfg.GetCORSAllowCreds(),
 		MaxAge:           12 * time.Hour,
 	}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apphttp "portal_final_backend/internal/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	headerAPIVersion  = "API-Version"
+	headerDeprecation = "Deprecation"
+	headerSunset      = "Sunset"
+	headerLink        = "Link"
+)
+
+// versionHeaders are exposed to browsers so the frontend can detect deprecated calls.
+var versionHeaders = []string{headerAPIVersion, headerDeprecation, headerSunset, headerLink}
+
+// deprecationPolicy describes the lifecycle of an API version. Zero times mean the
+// version is not deprecated or has no sunset date yet.
+type deprecationPolicy struct {
+	DeprecatedAt time.Time
+	SunsetAt     time.Time
+}
+
+func (p deprecationPolicy) active() bool {
+	return !p.DeprecatedAt.IsZero() || !p.SunsetAt.IsZero()
+}
+
+// apiVersionPrefix returns the path prefix of a version, e.g. "/api/v2".
+func apiVersionPrefix(version apphttp.APIVersion) string {
+	return "/api/" + version.String()
+}
+
+// apiVersionMiddleware tags requests with their API version and, for deprecated versions,
+// sets the Deprecation (RFC 9745) and Sunset (RFC 8594) headers. When the endpoint also
+// exists on the latest version, a successor-version link points clients to it.
+func apiVersionMiddleware(engine *gin.Engine, version apphttp.APIVersion, policy deprecationPolicy) gin.HandlerFunc {
+	successors := newSuccessorIndex(engine)
+	return func(c *gin.Context) {
+		apphttp.SetAPIVersion(c, version)
+		c.Header(headerAPIVersion, version.String())
+
+		if policy.active() {
+			if !policy.DeprecatedAt.IsZero() {
+				c.Header(headerDeprecation, "@"+strconv.FormatInt(policy.DeprecatedAt.Unix(), 10))
+			}
+			if !policy.SunsetAt.IsZero() {
+				c.Header(headerSunset, policy.SunsetAt.UTC().Format(http.TimeFormat))
+			}
+			if successor, ok := successors.lookup(c, version); ok {
+				c.Header(headerLink, "<"+successor+`>; rel="successor-version"`)
+			}
+		}
+		c.Next()
+	}
+}
+
+// successorIndex knows which routes exist on the latest API version. It is built on the
+// first request, after all modules registered their routes.
+type successorIndex struct {
+	engine *gin.Engine
+	once   sync.Once
+	routes map[string]struct{}
+}
+
+func newSuccessorIndex(engine *gin.Engine) *successorIndex {
+	return &successorIndex{engine: engine}
+}
+
+func (s *successorIndex) lookup(c *gin.Context, version apphttp.APIVersion) (string, bool) {
+	if version >= apphttp.LatestAPIVersion {
+		return "", false
+	}
+	s.once.Do(func() {
+		s.routes = make(map[string]struct{})
+		for _, route := range s.engine.Routes() {
+			s.routes[route.Method+" "+route.Path] = struct{}{}
+		}
+	})
+
+	fromPrefix := apiVersionPrefix(version)
+	toPrefix := apiVersionPrefix(apphttp.LatestAPIVersion)
+	fullPath := c.FullPath()
+	if !strings.HasPrefix(fullPath, fromPrefix+"/") {
+		return "", false
+	}
+	if _, ok := s.routes[c.Request.Method+" "+toPrefix+strings.TrimPrefix(fullPath, fromPrefix)]; !ok {
+		return "", false
+	}
+	return toPrefix + strings.TrimPrefix(c.Request.URL.Path, fromPrefix), true
+}
@@ -0,0 +1,81 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apphttp "portal_final_backend/internal/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newVersionedTestEngine(policy deprecationPolicy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	v1 := engine.Group(apiVersionPrefix(apphttp.APIVersion1))
+	v1.Use(apiVersionMiddleware(engine, apphttp.APIVersion1, policy))
+	v2 := engine.Group(apiVersionPrefix(apphttp.APIVersion2))
+	v2.Use(apiVersionMiddleware(engine, apphttp.APIVersion2, deprecationPolicy{}))
+
+	versions := apphttp.VersionedGroup{V1: v1, V2: v2}.Group("/items")
+	shape := func(c *gin.Context) {
+		c.String(http.StatusOK, apphttp.Negotiate(c, map[apphttp.APIVersion]string{
+			apphttp.APIVersion1: "legacy",
+			apphttp.APIVersion2: "current",
+		}))
+	}
+	versions.GET("/:id", shape, nil)
+	v1.GET("/legacy-only", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return engine
+}
+
+func TestVersionedRoutesServeBothVersionsWithNegotiatedShape(t *testing.T) {
+	t.Parallel()
+
+	engine := newVersionedTestEngine(deprecationPolicy{})
+	for path, want := range map[string]string{"/api/v1/items/1": "legacy", "/api/v2/items/1": "current"} {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK || recorder.Body.String() != want {
+			t.Fatalf("%s: expected 200 %q, got %d %q", path, want, recorder.Code, recorder.Body.String())
+		}
+		if recorder.Header().Get(headerDeprecation) != "" {
+			t.Fatalf("%s: expected no deprecation header without a policy", path)
+		}
+	}
+}
+
+func TestDeprecatedVersionSetsDeprecationSunsetAndSuccessorHeaders(t *testing.T) {
+	t.Parallel()
+
+	deprecatedAt := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+	engine := newVersionedTestEngine(deprecationPolicy{DeprecatedAt: deprecatedAt, SunsetAt: sunsetAt})
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/items/42", nil))
+
+	if got := recorder.Header().Get(headerDeprecation); got != "@1780272000" {
+		t.Fatalf("unexpected Deprecation header %q", got)
+	}
+	if got := recorder.Header().Get(headerSunset); got != "Fri, 01 Jan 2027 00:00:00 GMT" {
+		t.Fatalf("unexpected Sunset header %q", got)
+	}
+	if got := recorder.Header().Get(headerLink); got != `</api/v2/items/42>; rel="successor-version"` {
+		t.Fatalf("unexpected Link header %q", got)
+	}
+	if got := recorder.Header().Get(headerAPIVersion); got != "v1" {
+		t.Fatalf("unexpected API-Version header %q", got)
+	}
+
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/legacy-only", nil))
+	if recorder.Header().Get(headerLink) != "" {
+		t.Fatal("expected no successor link for an endpoint without a v2 route")
+	}
+	if recorder.Header().Get(headerDeprecation) == "" {
+		t.Fatal("expected deprecation header on every v1 endpoint")
+	}
+}
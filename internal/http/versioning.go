@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersion identifies a major version of the public API (the /api/vN path prefix).
+type APIVersion int
+
+const (
+	// APIVersion1 is the original /api/v1 surface.
+	APIVersion1 APIVersion = 1
+	// APIVersion2 is the /api/v2 surface.
+	APIVersion2 APIVersion = 2
+	// LatestAPIVersion is the newest version served by the router.
+	LatestAPIVersion = APIVersion2
+)
+
+const apiVersionContextKey = "apiVersion"
+
+// String returns the path segment of the version, e.g. "v2".
+func (v APIVersion) String() string {
+	return "v" + strconv.Itoa(int(v))
+}
+
+// SetAPIVersion records the API version a request was routed through.
+// It is called by the router's version middleware.
+func SetAPIVersion(c *gin.Context, version APIVersion) {
+	c.Set(apiVersionContextKey, version)
+}
+
+// APIVersionFrom returns the API version of the request. Requests that were not routed
+// through a versioned group are treated as v1.
+func APIVersionFrom(c *gin.Context) APIVersion {
+	if value, ok := c.Get(apiVersionContextKey); ok {
+		if version, ok := value.(APIVersion); ok {
+			return version
+		}
+	}
+	return APIVersion1
+}
+
+// Negotiate picks the variant for the request's API version, falling back to the newest
+// variant that is not newer than the requested version. Handlers shared between versions
+// use it to change a response shape without registering a second handler:
+//
+//	httpkit.OK(c, apphttp.Negotiate(c, map[apphttp.APIVersion]any{
+//		apphttp.APIVersion1: legacy,
+//		apphttp.APIVersion2: current,
+//	}))
+func Negotiate[T any](c *gin.Context, variants map[APIVersion]T) T {
+	var zero T
+	for version := APIVersionFrom(c); version >= APIVersion1; version-- {
+		if variant, ok := variants[version]; ok {
+			return variant
+		}
+	}
+	return zero
+}
+
+// VersionedGroup pairs the same route group across API versions so modules can register
+// v1 and v2 handlers side by side.
+type VersionedGroup struct {
+	V1 *gin.RouterGroup
+	V2 *gin.RouterGroup
+}
+
+// Group creates a sub-group with the same relative path on every version.
+func (g VersionedGroup) Group(relativePath string, handlers ...gin.HandlerFunc) VersionedGroup {
+	return VersionedGroup{
+		V1: g.V1.Group(relativePath, handlers...),
+		V2: g.V2.Group(relativePath, handlers...),
+	}
+}
+
+// Handle registers v1 on the v1 group and v2 on the v2 group. When v2 is nil the v1
+// handler is served unchanged on v2, so endpoints whose shape did not change need no
+// duplicate handler. Passing a nil v1 registers a v2-only endpoint.
+func (g VersionedGroup) Handle(method, relativePath string, v1, v2 gin.HandlerFunc) {
+	if v1 != nil {
+		g.V1.Handle(method, relativePath, v1)
+	}
+	if v2 == nil {
+		v2 = v1
+	}
+	if v2 != nil {
+		g.V2.Handle(method, relativePath, v2)
+	}
+}
+
+// GET registers a versioned GET endpoint. See Handle.
+func (g VersionedGroup) GET(relativePath string, v1, v2 gin.HandlerFunc) {
+	g.Handle(http.MethodGet, relativePath, v1, v2)
+}
+
+// POST registers a versioned POST endpoint. See Handle.
+func (g VersionedGroup) POST(relativePath string, v1, v2 gin.HandlerFunc) {
+	g.Handle(http.MethodPost, relativePath, v1, v2)
+}
+
+// PUT registers a versioned PUT endpoint. See Handle.
+func (g VersionedGroup) PUT(relativePath string, v1, v2 gin.HandlerFunc) {
+	g.Handle(http.MethodPut, relativePath, v1, v2)
+}
+
+// PATCH registers a versioned PATCH endpoint. See Handle.
+func (g VersionedGroup) PATCH(relativePath string, v1, v2 gin.HandlerFunc) {
+	g.Handle(http.MethodPatch, relativePath, v1, v2)
+}
+
+// DELETE registers a versioned DELETE endpoint. See Handle.
+func (g VersionedGroup) DELETE(relativePath string, v1, v2 gin.HandlerFunc) {
+	g.Handle(http.MethodDelete, relativePath, v1, v2)
+}
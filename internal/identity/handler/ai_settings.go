@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RegisterSuperAdminRoutes registers the cross-tenant AI usage report used for
+// billing, the cloning of organizations into demo or sandbox environments and
+// the linking of franchise branches to their head office.
+func (h *Handler) RegisterSuperAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/organizations/:organizationID/ai-usage", h.GetOrganizationAIUsageReport)
+	rg.POST("/organizations/:organizationID/clone", h.CloneOrganization)
+	rg.PUT("/organizations/:organizationID/parent", h.SetParentOrganization)
+	rg.DELETE("/organizations/:organizationID/parent", h.ClearParentOrganization)
+}
+
+func (h *Handler) GetOrganizationAISettings(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.svc.GetOrganizationAISettings(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toOrganizationAISettingsResponse(settings))
+}
+
+func (h *Handler) UpdateOrganizationAISettings(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateOrganizationAISettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	settings, err := h.svc.UpdateOrganizationAISettings(c.Request.Context(), tenantID, repository.OrganizationAISettings{
+		Provider:           req.Provider,
+		Model:              req.Model,
+		Temperature:        req.Temperature,
+		MonthlyTokenBudget: req.MonthlyTokenBudget,
+	})
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toOrganizationAISettingsResponse(settings))
+}
+
+func (h *Handler) GetOrganizationAIUsage(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.svc.GetAIUsageSummary(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	resp := transport.OrganizationAIUsageResponse{
+		PeriodStart:        summary.PeriodStart,
+		ChatTokens:         summary.ChatTokens,
+		EmbeddingTokens:    summary.EmbeddingTokens,
+		EstimatedCostUSD:   summary.EstimatedCostUSD,
+		MonthlyTokenBudget: summary.MonthlyTokenBudget,
+		BudgetExceeded:     summary.BudgetExceeded(),
+		ByModel:            make([]transport.AIUsageModelItem, 0, len(summary.ByModel)),
+	}
+	if summary.MonthlyTokenBudget != nil {
+		remaining := max(*summary.MonthlyTokenBudget-summary.ChatTokens, 0)
+		resp.RemainingTokens = &remaining
+	}
+	for _, item := range summary.ByModel {
+		resp.ByModel = append(resp.ByModel, transport.AIUsageModelItem{
+			Kind:             item.Kind,
+			Provider:         item.Provider,
+			Model:            item.Model,
+			Calls:            item.Calls,
+			PromptTokens:     item.PromptTokens,
+			CompletionTokens: item.CompletionTokens,
+			EstimatedCostUSD: item.EstimatedCostUSD,
+		})
+	}
+
+	httpkit.OK(c, resp)
+}
+
+// GetOwnAIUsageReport returns the daily AI usage of the caller's organization.
+func (h *Handler) GetOwnAIUsageReport(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	h.writeAIUsageReport(c, tenantID)
+}
+
+// GetOrganizationAIUsageReport returns the daily AI usage of any organization, for billing.
+func (h *Handler) GetOrganizationAIUsageReport(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("organizationID"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	h.writeAIUsageReport(c, organizationID)
+}
+
+func (h *Handler) writeAIUsageReport(c *gin.Context, organizationID uuid.UUID) {
+	var query transport.AIUsageReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(query); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	// Dates were validated above; the inclusive "to" day becomes an exclusive bound.
+	var from, to time.Time
+	if query.From != "" {
+		from, _ = time.Parse(time.DateOnly, query.From)
+	}
+	if query.To != "" {
+		to, _ = time.Parse(time.DateOnly, query.To)
+		to = to.AddDate(0, 0, 1)
+	}
+
+	report, err := h.svc.GetAIUsageReport(c.Request.Context(), organizationID, from, to)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	resp := transport.AIUsageReportResponse{
+		OrganizationID:   organizationID.String(),
+		From:             report.From.Format(time.DateOnly),
+		To:               report.To.AddDate(0, 0, -1).Format(time.DateOnly),
+		Calls:            report.Calls,
+		PromptTokens:     report.PromptTokens,
+		CompletionTokens: report.CompletionTokens,
+		EstimatedCostUSD: report.EstimatedCostUSD,
+		Days:             make([]transport.AIUsageDayItem, 0, len(report.Days)),
+	}
+	for _, day := range report.Days {
+		resp.Days = append(resp.Days, transport.AIUsageDayItem{
+			Date:             day.Day.Format(time.DateOnly),
+			Kind:             day.Kind,
+			Calls:            day.Calls,
+			PromptTokens:     day.PromptTokens,
+			CompletionTokens: day.CompletionTokens,
+			EstimatedCostUSD: day.EstimatedCostUSD,
+		})
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func toOrganizationAISettingsResponse(settings repository.OrganizationAISettings) transport.OrganizationAISettingsResponse {
+	providers := config.LLMProviderIDs()
+	options := make([]transport.AIProviderOption, 0, len(providers))
+	for _, id := range providers {
+		options = append(options, transport.AIProviderOption{ID: id, Models: config.LLMProviderModels(id)})
+	}
+
+	return transport.OrganizationAISettingsResponse{
+		Provider:           settings.Provider,
+		Model:              settings.Model,
+		Temperature:        settings.Temperature,
+		MonthlyTokenBudget: settings.MonthlyTokenBudget,
+		UpdatedAt:          settings.UpdatedAt,
+		AvailableProviders: options,
+	}
+}
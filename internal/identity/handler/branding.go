@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetOrganizationBranding(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	branding, err := h.svc.GetOrganizationBranding(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toBrandingResponse(branding))
+}
+
+func (h *Handler) UpdateOrganizationBranding(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateBrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	branding, err := h.svc.UpdateOrganizationBranding(c.Request.Context(), tenantID, repository.OrganizationBranding{
+		PrimaryColor:      req.PrimaryColor,
+		AccentColor:       req.AccentColor,
+		EmailHeaderHTML:   req.EmailHeaderHTML,
+		EmailFooterHTML:   req.EmailFooterHTML,
+		SenderDisplayName: req.SenderDisplayName,
+	})
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toBrandingResponse(branding))
+}
+
+func (h *Handler) DeleteOrganizationBranding(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.ResetOrganizationBranding(c.Request.Context(), tenantID); httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"message": "branding reset"})
+}
+
+func toBrandingResponse(branding repository.OrganizationBranding) transport.BrandingResponse {
+	return transport.BrandingResponse{
+		PrimaryColor:      branding.PrimaryColor,
+		AccentColor:       branding.AccentColor,
+		EmailHeaderHTML:   branding.EmailHeaderHTML,
+		EmailFooterHTML:   branding.EmailFooterHTML,
+		SenderDisplayName: branding.SenderDisplayName,
+		UpdatedAt:         branding.UpdatedAt,
+	}
+}
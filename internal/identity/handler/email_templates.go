@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) ListEmailTemplates(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	summaries, err := h.svc.ListEmailTemplates(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	templates := make([]transport.EmailTemplateSummaryResponse, 0, len(summaries))
+	for _, summary := range summaries {
+		templates = append(templates, transport.EmailTemplateSummaryResponse{
+			Key:           summary.Definition.Key,
+			Description:   summary.Definition.Description,
+			Variables:     toEmailTemplateVariables(summary.Definition.Variables),
+			Overridden:    summary.ActiveVersion != nil,
+			ActiveVersion: summary.ActiveVersion,
+			UpdatedAt:     summary.UpdatedAt,
+		})
+	}
+	httpkit.OK(c, transport.EmailTemplateListResponse{Templates: templates})
+}
+
+func (h *Handler) GetEmailTemplate(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.svc.GetEmailTemplate(c.Request.Context(), tenantID, c.Param("templateKey"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toEmailTemplateDetailResponse(detail))
+}
+
+func (h *Handler) SaveEmailTemplate(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID := identity.TenantID()
+	if tenantID == nil {
+		httpkit.Error(c, http.StatusBadRequest, msgTenantNotSet, nil)
+		return
+	}
+
+	req, ok := h.bindEmailTemplateDraft(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	templateKey := c.Param("templateKey")
+	if _, err := h.svc.SaveEmailTemplate(ctx, *tenantID, identity.UserID(), templateKey, req.Subject, req.BodyHTML); httpkit.HandleError(c, err) {
+		return
+	}
+
+	detail, err := h.svc.GetEmailTemplate(ctx, *tenantID, templateKey)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, toEmailTemplateDetailResponse(detail))
+}
+
+func (h *Handler) ActivateEmailTemplateVersion(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, "invalid version")
+		return
+	}
+
+	ctx := c.Request.Context()
+	templateKey := c.Param("templateKey")
+	if err := h.svc.ActivateEmailTemplateVersion(ctx, tenantID, templateKey, version); httpkit.HandleError(c, err) {
+		return
+	}
+
+	detail, err := h.svc.GetEmailTemplate(ctx, tenantID, templateKey)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, toEmailTemplateDetailResponse(detail))
+}
+
+func (h *Handler) ResetEmailTemplate(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.ResetEmailTemplate(c.Request.Context(), tenantID, c.Param("templateKey")); httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"message": "email template reset"})
+}
+
+func (h *Handler) PreviewEmailTemplate(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	req, ok := h.bindEmailTemplateDraft(c)
+	if !ok {
+		return
+	}
+
+	rendered, err := h.svc.PreviewEmailTemplate(c.Request.Context(), tenantID, c.Param("templateKey"), req.Subject, req.BodyHTML)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, transport.EmailTemplatePreviewResponse{
+		Valid:               rendered.Valid(),
+		Subject:             rendered.Subject,
+		BodyHTML:            rendered.BodyHTML,
+		TemplateErrors:      nonNilStrings(rendered.TemplateErrors),
+		UnresolvedVariables: nonNilStrings(rendered.UnresolvedVariables),
+	})
+}
+
+// TestSendEmailTemplate sends a draft override, rendered with example
+// values, to the requesting admin.
+func (h *Handler) TestSendEmailTemplate(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID := identity.TenantID()
+	if tenantID == nil {
+		httpkit.Error(c, http.StatusBadRequest, msgTenantNotSet, nil)
+		return
+	}
+
+	req, ok := h.bindEmailTemplateDraft(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.SendEmailTemplateTest(c.Request.Context(), *tenantID, identity.UserID(), c.Param("templateKey"), req.Subject, req.BodyHTML); httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"status": "sent"})
+}
+
+func (h *Handler) bindEmailTemplateDraft(c *gin.Context) (transport.EmailTemplateDraftRequest, bool) {
+	var req transport.EmailTemplateDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return req, false
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+func toEmailTemplateDetailResponse(detail service.EmailTemplateDetail) transport.EmailTemplateDetailResponse {
+	resp := transport.EmailTemplateDetailResponse{
+		Key:         detail.Definition.Key,
+		Description: detail.Definition.Description,
+		Variables:   toEmailTemplateVariables(detail.Definition.Variables),
+		Versions:    make([]transport.EmailTemplateVersionResponse, 0, len(detail.Versions)),
+	}
+	if detail.Active != nil {
+		active := toEmailTemplateVersionResponse(*detail.Active)
+		resp.Active = &active
+	}
+	for _, version := range detail.Versions {
+		resp.Versions = append(resp.Versions, toEmailTemplateVersionResponse(version))
+	}
+	return resp
+}
+
+func toEmailTemplateVersionResponse(version repository.EmailTemplateVersion) transport.EmailTemplateVersionResponse {
+	resp := transport.EmailTemplateVersionResponse{
+		Version:   version.Version,
+		Subject:   version.Subject,
+		BodyHTML:  version.BodyHTML,
+		CreatedAt: version.CreatedAt,
+	}
+	if version.CreatedBy != nil {
+		createdBy := version.CreatedBy.String()
+		resp.CreatedBy = &createdBy
+	}
+	return resp
+}
+
+func toEmailTemplateVariables(variables []service.WorkflowVariable) []transport.WorkflowVariableResponse {
+	resp := make([]transport.WorkflowVariableResponse, 0, len(variables))
+	for _, variable := range variables {
+		resp = append(resp, transport.WorkflowVariableResponse{
+			Path:        variable.Path,
+			Placeholder: "{{" + variable.Path + "}}",
+			Type:        variable.Type,
+			Example:     variable.Example,
+			Source:      variable.Source,
+		})
+	}
+	return resp
+}
@@ -27,6 +27,7 @@ const (
 	pathWorkflow             = "/organizations/me/workflow-engine/workflows/:workflowID"
 	pathLeadWorkflowOverride = "/organizations/me/workflow-engine/leads/:leadID/override"
 	pathLeadWorkflowResolve  = "/organizations/me/workflow-engine/leads/:leadID/resolve"
+	pathEmailTemplate        = "/organizations/me/email-templates/:templateKey"
 )
 
 func New(svc *service.Service, val *validator.Validator) *Handler {
@@ -38,6 +39,36 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.PATCH("/organizations/me", h.UpdateOrganization)
 	rg.GET("/organizations/me/settings", h.GetOrganizationSettings)
 	rg.PATCH("/organizations/me/settings", h.UpdateOrganizationSettings)
+	rg.POST("/organizations/me/sandboxes", h.CreateOrganizationSandbox)
+	rg.POST("/organizations/me/branches/catalog-sync", h.SyncBranchCatalogs)
+	rg.GET("/organizations/me/ai-settings", h.GetOrganizationAISettings)
+	rg.PUT("/organizations/me/ai-settings", h.UpdateOrganizationAISettings)
+	rg.GET("/organizations/me/messaging-policy", h.GetOrganizationMessagingPolicy)
+	rg.PUT("/organizations/me/messaging-policy", h.UpdateOrganizationMessagingPolicy)
+	rg.GET("/organizations/me/branding", h.GetOrganizationBranding)
+	rg.PUT("/organizations/me/branding", h.UpdateOrganizationBranding)
+	rg.DELETE("/organizations/me/branding", h.DeleteOrganizationBranding)
+	rg.GET("/organizations/me/email-templates", h.ListEmailTemplates)
+	rg.GET(pathEmailTemplate, h.GetEmailTemplate)
+	rg.PUT(pathEmailTemplate, h.SaveEmailTemplate)
+	rg.DELETE(pathEmailTemplate, h.ResetEmailTemplate)
+	rg.POST(pathEmailTemplate+"/versions/:version/activate", h.ActivateEmailTemplateVersion)
+	rg.POST(pathEmailTemplate+"/preview", h.PreviewEmailTemplate)
+	rg.POST(pathEmailTemplate+"/test-send", h.TestSendEmailTemplate)
+	rg.GET("/organizations/me/localization", h.GetOrganizationLocalization)
+	rg.PUT("/organizations/me/localization", h.UpdateOrganizationLocalization)
+	rg.GET("/organizations/me/stale-lead-settings", h.GetOrganizationStaleLeadSettings)
+	rg.PUT("/organizations/me/stale-lead-settings", h.UpdateOrganizationStaleLeadSettings)
+	rg.GET("/organizations/me/portal-domain", h.GetPortalDomain)
+	rg.PUT("/organizations/me/portal-domain", h.UpdatePortalDomain)
+	rg.DELETE("/organizations/me/portal-domain", h.DeletePortalDomain)
+	rg.POST("/organizations/me/portal-domain/verify", h.VerifyPortalDomain)
+	rg.GET("/organizations/me/sso", h.GetOrganizationSSOConfig)
+	rg.PUT("/organizations/me/sso", h.UpdateOrganizationSSOConfig)
+	rg.DELETE("/organizations/me/sso", h.DeleteOrganizationSSOConfig)
+	rg.POST("/organizations/me/sso/verify-domains", h.VerifyOrganizationSSODomains)
+	rg.GET("/organizations/me/ai-usage", h.GetOrganizationAIUsage)
+	rg.GET("/organizations/me/ai-usage/daily", h.GetOwnAIUsageReport)
 	rg.GET("/organizations/me/whatsapp/reply-scenario-analytics", h.ListWhatsAppReplyScenarioAnalytics)
 	rg.GET(pathWorkflows, h.ListWorkflows)
 	rg.POST(pathWorkflows, h.CreateWorkflow)
@@ -54,6 +85,9 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.PUT(pathLeadWorkflowOverride, h.UpsertLeadWorkflowOverride)
 	rg.DELETE(pathLeadWorkflowOverride, h.DeleteLeadWorkflowOverride)
 	rg.GET(pathLeadWorkflowResolve, h.ResolveLeadWorkflow)
+	rg.POST("/organizations/me/workflow-engine/simulate", h.SimulateWorkflow)
+	rg.POST("/organizations/me/workflow-engine/conditions/test", h.TestWorkflowCondition)
+	rg.GET("/organizations/me/workflow-engine/variables", h.ListWorkflowVariables)
 	rg.POST("/organizations/me/whatsapp/register", h.RegisterWhatsApp)
 	rg.GET("/organizations/me/whatsapp/qr", h.GetWhatsAppQR)
 	rg.GET("/organizations/me/whatsapp/status", h.GetWhatsAppStatus)
@@ -65,6 +99,8 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.DELETE("/organizations/me/smtp", h.ClearSMTP)
 	rg.POST("/organizations/me/smtp/test", h.TestSMTP)
 	rg.POST("/organizations/me/smtp/detect", h.DetectSMTP)
+	rg.GET("/organizations/me/smtp/sender-domain", h.GetSenderDomain)
+	rg.POST("/organizations/me/smtp/sender-domain/verify", h.VerifySenderDomain)
 	rg.POST("/organizations/me/logo/presign", h.PresignLogo)
 	rg.POST("/organizations/me/logo", h.SetLogo)
 	rg.GET("/organizations/me/logo/download", h.GetLogoDownload)
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetOrganizationLocalization(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	localization, err := h.svc.GetOrganizationLocalization(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toLocalizationResponse(localization))
+}
+
+func (h *Handler) UpdateOrganizationLocalization(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateLocalizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	localization, err := h.svc.UpdateOrganizationDefaultLanguage(c.Request.Context(), tenantID, req.DefaultLanguage)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toLocalizationResponse(localization))
+}
+
+func toLocalizationResponse(localization service.OrganizationLocalization) transport.LocalizationResponse {
+	return transport.LocalizationResponse{
+		DefaultLanguage:    localization.DefaultLanguage,
+		SupportedLanguages: localization.SupportedLanguages,
+	}
+}
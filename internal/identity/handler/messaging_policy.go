@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+const quietHoursLayout = "15:04"
+
+func (h *Handler) GetOrganizationMessagingPolicy(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.svc.GetOrganizationMessagingPolicy(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toMessagingPolicyResponse(policy))
+}
+
+func (h *Handler) UpdateOrganizationMessagingPolicy(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateMessagingPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	start, err := parseMinuteOfDay(req.QuietHoursStart, repository.DefaultQuietHoursStart)
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+	end, err := parseMinuteOfDay(req.QuietHoursEnd, repository.DefaultQuietHoursEnd)
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	policy, err := h.svc.UpdateOrganizationMessagingPolicy(c.Request.Context(), tenantID, repository.OrganizationMessagingPolicy{
+		QuietHoursEnabled:         req.QuietHoursEnabled,
+		QuietHoursStart:           start,
+		QuietHoursEnd:             end,
+		Timezone:                  req.Timezone,
+		MaxMessagesPerLeadPerHour: req.MaxMessagesPerLeadPerHour,
+		MaxMessagesPerLeadPerDay:  req.MaxMessagesPerLeadPerDay,
+		IncludeEmail:              req.IncludeEmail,
+		EmailTracking:             req.EmailTracking,
+	})
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toMessagingPolicyResponse(policy))
+}
+
+func parseMinuteOfDay(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.Parse(quietHoursLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func formatMinuteOfDay(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+func toMessagingPolicyResponse(policy repository.OrganizationMessagingPolicy) transport.MessagingPolicyResponse {
+	return transport.MessagingPolicyResponse{
+		QuietHoursEnabled:         policy.QuietHoursEnabled,
+		QuietHoursStart:           formatMinuteOfDay(policy.QuietHoursStart),
+		QuietHoursEnd:             formatMinuteOfDay(policy.QuietHoursEnd),
+		Timezone:                  policy.Timezone,
+		MaxMessagesPerLeadPerHour: policy.MaxMessagesPerLeadPerHour,
+		MaxMessagesPerLeadPerDay:  policy.MaxMessagesPerLeadPerDay,
+		IncludeEmail:              policy.IncludeEmail,
+		EmailTracking:             policy.EmailTracking,
+		UpdatedAt:                 policy.UpdatedAt,
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateOrganizationSandbox copies the caller's organization into a sandbox for
+// trying out workflow and catalog changes.
+func (h *Handler) CreateOrganizationSandbox(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.CreateOrganizationSandboxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	h.cloneOrganization(c, service.CloneOrganizationInput{
+		SourceOrganizationID: tenantID,
+		CreatedBy:            identity.UserID(),
+		Name:                 req.Name,
+		Purpose:              repository.ClonePurposeSandbox,
+		InviteEmail:          req.InviteEmail,
+		DemoLeads:            req.DemoLeads,
+	})
+}
+
+// CloneOrganization copies any organization into a demo or sandbox organization.
+func (h *Handler) CloneOrganization(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	organizationID, err := uuid.Parse(c.Param("organizationID"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.CloneOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	h.cloneOrganization(c, service.CloneOrganizationInput{
+		SourceOrganizationID: organizationID,
+		CreatedBy:            identity.UserID(),
+		Name:                 req.Name,
+		Purpose:              req.Purpose,
+		InviteEmail:          req.InviteEmail,
+		DemoLeads:            req.DemoLeads,
+	})
+}
+
+func (h *Handler) cloneOrganization(c *gin.Context, input service.CloneOrganizationInput) {
+	result, err := h.svc.CloneOrganization(c.Request.Context(), input)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, transport.CloneOrganizationResponse{
+		OrganizationID:  result.OrganizationID.String(),
+		Name:            result.Name,
+		Purpose:         result.Purpose,
+		CopiedRows:      result.CopiedRows,
+		DemoLeads:       result.DemoLeads,
+		InviteExpiresAt: result.InviteExpiresAt,
+	})
+}
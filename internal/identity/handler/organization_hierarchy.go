@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetOrganizationHierarchy returns the head office of a branch or the branches
+// of a head office. Head-office users pass a branch ID in the X-Branch-ID
+// header to read that branch.
+func (h *Handler) GetOrganizationHierarchy(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	hierarchy, err := h.svc.GetOrganizationHierarchy(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	resp := transport.OrganizationHierarchyResponse{
+		Branches: make([]transport.OrganizationBranchResponse, len(hierarchy.Branches)),
+	}
+	if hierarchy.ParentOrganizationID != nil {
+		parentID := hierarchy.ParentOrganizationID.String()
+		resp.ParentOrganizationID = &parentID
+	}
+	for i, branch := range hierarchy.Branches {
+		resp.Branches[i] = transport.OrganizationBranchResponse{
+			ID:        branch.ID.String(),
+			Name:      branch.Name,
+			CreatedAt: branch.CreatedAt,
+		}
+	}
+	httpkit.OK(c, resp)
+}
+
+// SyncBranchCatalogs pushes the head-office catalog to every branch.
+func (h *Handler) SyncBranchCatalogs(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.svc.SyncBranchCatalogs(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	resp := transport.BranchCatalogSyncListResponse{Items: make([]transport.BranchCatalogSyncResponse, len(results))}
+	for i, result := range results {
+		resp.Items[i] = toBranchCatalogSyncResponse(result)
+	}
+	httpkit.OK(c, resp)
+}
+
+// SetParentOrganization makes an organization a branch of a head office.
+func (h *Handler) SetParentOrganization(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("organizationID"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.SetParentOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	result, err := h.svc.LinkBranch(c.Request.Context(), organizationID, uuid.MustParse(req.ParentOrganizationID))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, toBranchCatalogSyncResponse(result))
+}
+
+// ClearParentOrganization detaches a branch from its head office.
+func (h *Handler) ClearParentOrganization(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("organizationID"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.UnlinkBranch(c.Request.Context(), organizationID)) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"message": "branch detached from head office"})
+}
+
+func toBranchCatalogSyncResponse(result service.BranchCatalogSyncOutput) transport.BranchCatalogSyncResponse {
+	return transport.BranchCatalogSyncResponse{
+		OrganizationID:  result.OrganizationID.String(),
+		CreatedProducts: result.Created,
+		UpdatedProducts: result.Updated,
+	}
+}
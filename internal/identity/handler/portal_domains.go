@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPublicRoutes registers the white-label portal lookup by request host.
+func (h *Handler) RegisterPublicRoutes(rg *gin.RouterGroup) {
+	rg.GET("/portal", h.GetPublicPortal)
+}
+
+func (h *Handler) GetPortalDomain(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	domain, err := h.svc.GetPortalDomain(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toPortalDomainResponse(domain))
+}
+
+func (h *Handler) UpdatePortalDomain(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.PortalDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	domain, err := h.svc.RegisterPortalDomain(c.Request.Context(), tenantID, req.Domain)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toPortalDomainResponse(domain))
+}
+
+func (h *Handler) VerifyPortalDomain(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	domain, err := h.svc.VerifyPortalDomain(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toPortalDomainResponse(domain))
+}
+
+func (h *Handler) DeletePortalDomain(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.DeletePortalDomain(c.Request.Context(), tenantID)) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"message": "portal domain removed"})
+}
+
+// GetPublicPortal returns the organization whose verified custom domain the
+// request was made to.
+func (h *Handler) GetPublicPortal(c *gin.Context) {
+	organizationID, ok := httpkit.PortalOrganizationID(c)
+	if !ok {
+		httpkit.Error(c, http.StatusNotFound, "portal domain not found", nil)
+		return
+	}
+
+	org, domain, err := h.svc.GetPortalOrganization(c.Request.Context(), organizationID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, transport.PublicPortalResponse{
+		OrganizationID: org.ID.String(),
+		Name:           org.Name,
+		Domain:         domain.Domain,
+	})
+}
+
+func toPortalDomainResponse(domain repository.OrganizationPortalDomain) transport.PortalDomainResponse {
+	record := service.PortalDomainVerificationRecord(domain)
+	return transport.PortalDomainResponse{
+		Domain:        domain.Domain,
+		Verified:      domain.VerifiedAt != nil,
+		VerifiedAt:    domain.VerifiedAt,
+		LastCheckedAt: domain.LastCheckedAt,
+		LastError:     domain.LastError,
+		VerificationRecord: transport.PortalDomainRecordResponse{
+			Type:  "TXT",
+			Name:  record.Name,
+			Value: record.Value,
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetSenderDomain(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	domain, err := h.svc.GetSenderDomain(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, domain)
+}
+
+func (h *Handler) VerifySenderDomain(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.VerifySenderDomainRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+			return
+		}
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	domain, err := h.svc.VerifySenderDomain(c.Request.Context(), tenantID, req.Domain, req.DKIMSelector)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, domain)
+}
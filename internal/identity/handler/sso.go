@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetOrganizationSSOConfig(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	config, err := h.svc.GetOrganizationSSOConfig(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toSSOConfigResponse(config))
+}
+
+func (h *Handler) UpdateOrganizationSSOConfig(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateSSOConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	config, err := h.svc.UpdateOrganizationSSOConfig(c.Request.Context(), tenantID, repository.OrganizationSSOConfig{
+		Enabled:         req.Enabled,
+		IssuerURL:       req.IssuerURL,
+		ClientID:        req.ClientID,
+		ClientSecret:    req.ClientSecret,
+		AllowedDomains:  req.AllowedDomains,
+		RoleClaim:       req.RoleClaim,
+		RoleMappings:    req.RoleMappings,
+		DefaultRole:     req.DefaultRole,
+		JITProvisioning: req.JITProvisioning,
+		EnforceSSO:      req.EnforceSSO,
+	})
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toSSOConfigResponse(config))
+}
+
+func (h *Handler) VerifyOrganizationSSODomains(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	config, err := h.svc.VerifySSODomains(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toSSOConfigResponse(config))
+}
+
+func (h *Handler) DeleteOrganizationSSOConfig(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.DeleteOrganizationSSOConfig(c.Request.Context(), tenantID)) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"message": "single sign-on removed"})
+}
+
+func toSSOConfigResponse(config repository.OrganizationSSOConfig) transport.SSOConfigResponse {
+	return transport.SSOConfigResponse{
+		Enabled:         config.Enabled,
+		IssuerURL:       config.IssuerURL,
+		ClientID:        config.ClientID,
+		HasClientSecret: config.ClientSecret != "",
+		AllowedDomains:  config.AllowedDomains,
+		RoleClaim:       config.RoleClaim,
+		RoleMappings:    config.RoleMappings,
+		DefaultRole:     config.DefaultRole,
+		JITProvisioning: config.JITProvisioning,
+		EnforceSSO:      config.EnforceSSO,
+		UpdatedAt:       config.UpdatedAt,
+		Domains:         toSSODomainResponses(config.Domains),
+	}
+}
+
+func toSSODomainResponses(domains []repository.OrganizationSSODomain) []transport.SSODomainResponse {
+	items := make([]transport.SSODomainResponse, 0, len(domains))
+	for _, domain := range domains {
+		record := service.SSODomainVerificationRecord(domain)
+		items = append(items, transport.SSODomainResponse{
+			Domain:        domain.Domain,
+			Verified:      domain.VerifiedAt != nil,
+			VerifiedAt:    domain.VerifiedAt,
+			LastCheckedAt: domain.LastCheckedAt,
+			LastError:     domain.LastError,
+			VerificationRecord: transport.PortalDomainRecordResponse{
+				Type:  "TXT",
+				Name:  record.Name,
+				Value: record.Value,
+			},
+		})
+	}
+	return items
+}
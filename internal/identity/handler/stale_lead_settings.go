@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetOrganizationStaleLeadSettings(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.svc.GetOrganizationStaleLeadSettings(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toStaleLeadSettingsResponse(settings))
+}
+
+func (h *Handler) UpdateOrganizationStaleLeadSettings(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateStaleLeadSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	settings, err := h.svc.UpdateOrganizationStaleLeadSettings(c.Request.Context(), tenantID, repository.OrganizationStaleLeadSettings{
+		NoActivityDays:       req.NoActivityDays,
+		NurturingDays:        req.NurturingDays,
+		NoQuoteDays:          req.NoQuoteDays,
+		DraftQuoteDays:       req.DraftQuoteDays,
+		RescheduleDays:       req.RescheduleDays,
+		CreateOwnerTasks:     req.CreateOwnerTasks,
+		ConsumerNudgeEnabled: req.ConsumerNudgeEnabled,
+	})
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, toStaleLeadSettingsResponse(settings))
+}
+
+func toStaleLeadSettingsResponse(settings repository.OrganizationStaleLeadSettings) transport.StaleLeadSettingsResponse {
+	return transport.StaleLeadSettingsResponse{
+		NoActivityDays:       settings.NoActivityDays,
+		NurturingDays:        settings.NurturingDays,
+		NoQuoteDays:          settings.NoQuoteDays,
+		DraftQuoteDays:       settings.DraftQuoteDays,
+		RescheduleDays:       settings.RescheduleDays,
+		CreateOwnerTasks:     settings.CreateOwnerTasks,
+		ConsumerNudgeEnabled: settings.ConsumerNudgeEnabled,
+		UpdatedAt:            settings.UpdatedAt,
+	}
+}
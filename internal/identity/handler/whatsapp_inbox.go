@@ -18,6 +18,7 @@ import (
 )
 
 func (h *Handler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/organizations/me/hierarchy", h.GetOrganizationHierarchy)
 	rg.GET("/whatsapp/conversations", h.ListWhatsAppConversations)
 	rg.GET("/whatsapp/conversations/unread-count", h.GetWhatsAppUnreadConversationCount)
 	rg.GET("/chat/:chatJID/messages", h.ListWhatsAppMessagesByChatJID)
@@ -585,36 +585,102 @@ func (h *Handler) DeleteWorkflowStep(c *gin.Context) {
 
 func mapCreateWorkflowStepRequest(req transport.CreateWorkflowStepRequest) repository.WorkflowStepUpsert {
 	return repository.WorkflowStepUpsert{
-		Trigger:         req.Trigger,
-		Channel:         req.Channel,
-		Audience:        req.Audience,
-		Action:          req.Action,
-		StepOrder:       req.StepOrder,
-		DelayMinutes:    req.DelayMinutes,
-		Enabled:         req.Enabled,
-		RecipientConfig: mapRecipientConfig(req.RecipientConfig),
-		TemplateSubject: req.TemplateSubject,
-		TemplateBody:    req.TemplateBody,
-		StopOnReply:     req.StopOnReply,
+		Trigger:              req.Trigger,
+		Channel:              req.Channel,
+		Audience:             req.Audience,
+		Action:               req.Action,
+		StepOrder:            req.StepOrder,
+		DelayMinutes:         req.DelayMinutes,
+		Enabled:              req.Enabled,
+		RecipientConfig:      mapRecipientConfig(req.RecipientConfig),
+		TemplateSubject:      req.TemplateSubject,
+		TemplateBody:         req.TemplateBody,
+		StopOnReply:          req.StopOnReply,
+		SendCondition:        req.SendCondition,
+		TemplateTranslations: mapWorkflowStepTranslationsRequest(req.TemplateTranslations),
+		TemplateVariants:     mapWorkflowStepVariantsRequest(req.TemplateVariants),
 	}
 }
 
 func mapUpdateWorkflowStepRequest(req transport.UpdateWorkflowStepRequest) repository.WorkflowStepUpsert {
 	return repository.WorkflowStepUpsert{
-		Trigger:         req.Trigger,
-		Channel:         req.Channel,
-		Audience:        req.Audience,
-		Action:          req.Action,
-		StepOrder:       req.StepOrder,
-		DelayMinutes:    req.DelayMinutes,
-		Enabled:         req.Enabled,
-		RecipientConfig: mapRecipientConfig(req.RecipientConfig),
-		TemplateSubject: req.TemplateSubject,
-		TemplateBody:    req.TemplateBody,
-		StopOnReply:     req.StopOnReply,
+		Trigger:              req.Trigger,
+		Channel:              req.Channel,
+		Audience:             req.Audience,
+		Action:               req.Action,
+		StepOrder:            req.StepOrder,
+		DelayMinutes:         req.DelayMinutes,
+		Enabled:              req.Enabled,
+		RecipientConfig:      mapRecipientConfig(req.RecipientConfig),
+		TemplateSubject:      req.TemplateSubject,
+		TemplateBody:         req.TemplateBody,
+		StopOnReply:          req.StopOnReply,
+		SendCondition:        req.SendCondition,
+		TemplateTranslations: mapWorkflowStepTranslationsRequest(req.TemplateTranslations),
+		TemplateVariants:     mapWorkflowStepVariantsRequest(req.TemplateVariants),
 	}
 }
 
+func mapWorkflowStepTranslationsRequest(req map[string]transport.WorkflowStepTranslation) map[string]repository.WorkflowStepTranslation {
+	if len(req) == 0 {
+		return nil
+	}
+	translations := make(map[string]repository.WorkflowStepTranslation, len(req))
+	for lang, translation := range req {
+		translations[lang] = repository.WorkflowStepTranslation{
+			TemplateSubject: translation.TemplateSubject,
+			TemplateBody:    translation.TemplateBody,
+		}
+	}
+	return translations
+}
+
+func mapWorkflowStepTranslationsResponse(translations map[string]repository.WorkflowStepTranslation) map[string]transport.WorkflowStepTranslation {
+	if len(translations) == 0 {
+		return nil
+	}
+	resp := make(map[string]transport.WorkflowStepTranslation, len(translations))
+	for lang, translation := range translations {
+		resp[lang] = transport.WorkflowStepTranslation{
+			TemplateSubject: translation.TemplateSubject,
+			TemplateBody:    translation.TemplateBody,
+		}
+	}
+	return resp
+}
+
+func mapWorkflowStepVariantsRequest(req []transport.WorkflowStepVariant) []repository.WorkflowStepVariant {
+	if len(req) == 0 {
+		return nil
+	}
+	variants := make([]repository.WorkflowStepVariant, 0, len(req))
+	for _, variant := range req {
+		variants = append(variants, repository.WorkflowStepVariant{
+			Key:             variant.Key,
+			Weight:          variant.Weight,
+			TemplateSubject: variant.TemplateSubject,
+			TemplateBody:    variant.TemplateBody,
+		})
+	}
+	return variants
+}
+
+func mapWorkflowStepVariantsResponse(variants []repository.WorkflowStepVariant) []transport.WorkflowStepVariant {
+	if len(variants) == 0 {
+		return nil
+	}
+	resp := make([]transport.WorkflowStepVariant, 0, len(variants))
+	for _, variant := range variants {
+		resp = append(resp, transport.WorkflowStepVariant{
+			Key:             variant.Key,
+			Weight:          variant.Weight,
+			TemplateSubject: variant.TemplateSubject,
+			TemplateBody:    variant.TemplateBody,
+		})
+	}
+	return resp
+}
+
 func mapRecipientConfig(req transport.WorkflowStepRecipientConfig) map[string]any {
 	cfg := map[string]any{}
 	if req.Audience != "" {
@@ -633,6 +699,115 @@ func mapRecipientConfig(req transport.WorkflowStepRecipientConfig) map[string]an
 	return cfg
 }
 
+// SimulateWorkflow renders the steps of a workflow for a lead without sending
+// them, so template errors show up in the editor instead of in production.
+func (h *Handler) SimulateWorkflow(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID := identity.TenantID()
+	if tenantID == nil {
+		httpkit.Error(c, http.StatusBadRequest, msgTenantNotSet, nil)
+		return
+	}
+	if !h.canManageWorkflowEngine(c, *tenantID, identity.UserID(), identity.HasRole("admin")) {
+		return
+	}
+
+	var req transport.SimulateWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	input, err := mapSimulateWorkflowRequest(req)
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, err.Error())
+		return
+	}
+
+	result, err := h.svc.SimulateWorkflow(c.Request.Context(), *tenantID, input)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, mapSimulateWorkflowResponse(result))
+}
+
+// TestWorkflowCondition evaluates a step send condition for a lead so the
+// editor can show whether the step would be sent.
+func (h *Handler) TestWorkflowCondition(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID := identity.TenantID()
+	if tenantID == nil {
+		httpkit.Error(c, http.StatusBadRequest, msgTenantNotSet, nil)
+		return
+	}
+	if !h.canManageWorkflowEngine(c, *tenantID, identity.UserID(), identity.HasRole("admin")) {
+		return
+	}
+
+	var req transport.TestWorkflowConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	input := service.TestWorkflowConditionInput{
+		Condition:  req.Condition,
+		Trigger:    req.Trigger,
+		SampleLead: mapWorkflowSampleLead(req.SampleLead),
+		Variables:  req.Variables,
+	}
+	if req.LeadID != nil {
+		leadID, err := uuid.Parse(*req.LeadID)
+		if err != nil {
+			httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, msgInvalidLeadID)
+			return
+		}
+		input.LeadID = &leadID
+	}
+
+	result, err := h.svc.TestWorkflowCondition(c.Request.Context(), *tenantID, input)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, transport.TestWorkflowConditionResponse{
+		Valid:     result.Valid,
+		Error:     result.Error,
+		Matched:   result.Matched,
+		Variables: result.Variables,
+	})
+}
+
+// ListWorkflowVariables returns the template variables available per trigger,
+// optionally limited to the trigger query parameter.
+func (h *Handler) ListWorkflowVariables(c *gin.Context) {
+	if _, ok := h.requireTenantID(c); !ok {
+		return
+	}
+
+	catalog, err := h.svc.ListWorkflowVariables(c.Query("trigger"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, mapWorkflowVariableCatalogResponse(catalog))
+}
+
 func (h *Handler) requireTenantID(c *gin.Context) (uuid.UUID, bool) {
 	identity := httpkit.MustGetIdentity(c)
 	if identity == nil {
@@ -699,18 +874,21 @@ func mapWorkflowStepResponse(step repository.WorkflowStep) transport.WorkflowSte
 	cfg.CustomPhones = stringSliceFromAny(step.RecipientConfig["customPhones"])
 
 	return transport.WorkflowStepResponse{
-		ID:              step.ID.String(),
-		Trigger:         step.Trigger,
-		Channel:         step.Channel,
-		Audience:        step.Audience,
-		Action:          step.Action,
-		StepOrder:       step.StepOrder,
-		DelayMinutes:    step.DelayMinutes,
-		Enabled:         step.Enabled,
-		RecipientConfig: cfg,
-		TemplateSubject: step.TemplateSubject,
-		TemplateBody:    step.TemplateBody,
-		StopOnReply:     step.StopOnReply,
+		ID:                   step.ID.String(),
+		Trigger:              step.Trigger,
+		Channel:              step.Channel,
+		Audience:             step.Audience,
+		Action:               step.Action,
+		StepOrder:            step.StepOrder,
+		DelayMinutes:         step.DelayMinutes,
+		Enabled:              step.Enabled,
+		RecipientConfig:      cfg,
+		TemplateSubject:      step.TemplateSubject,
+		TemplateBody:         step.TemplateBody,
+		StopOnReply:          step.StopOnReply,
+		SendCondition:        step.SendCondition,
+		TemplateTranslations: mapWorkflowStepTranslationsResponse(step.TemplateTranslations),
+		TemplateVariants:     mapWorkflowStepVariantsResponse(step.TemplateVariants),
 	}
 }
 
@@ -750,17 +928,20 @@ func mapWorkflowUpsertRequest(req transport.UpsertWorkflowRequest) (repository.W
 
 func mapWorkflowStepUpsertRequest(req transport.UpsertWorkflowStepRequest) (repository.WorkflowStepUpsert, error) {
 	step := repository.WorkflowStepUpsert{
-		Trigger:         req.Trigger,
-		Channel:         req.Channel,
-		Audience:        req.Audience,
-		Action:          req.Action,
-		StepOrder:       req.StepOrder,
-		DelayMinutes:    req.DelayMinutes,
-		Enabled:         req.Enabled,
-		TemplateSubject: req.TemplateSubject,
-		TemplateBody:    req.TemplateBody,
-		StopOnReply:     req.StopOnReply,
-		RecipientConfig: map[string]any{},
+		Trigger:              req.Trigger,
+		Channel:              req.Channel,
+		Audience:             req.Audience,
+		Action:               req.Action,
+		StepOrder:            req.StepOrder,
+		DelayMinutes:         req.DelayMinutes,
+		Enabled:              req.Enabled,
+		TemplateSubject:      req.TemplateSubject,
+		TemplateBody:         req.TemplateBody,
+		StopOnReply:          req.StopOnReply,
+		SendCondition:        req.SendCondition,
+		TemplateTranslations: mapWorkflowStepTranslationsRequest(req.TemplateTranslations),
+		TemplateVariants:     mapWorkflowStepVariantsRequest(req.TemplateVariants),
+		RecipientConfig:      map[string]any{},
 	}
 
 	if req.ID != nil {
@@ -788,6 +969,95 @@ func mapWorkflowStepUpsertRequest(req transport.UpsertWorkflowStepRequest) (repo
 	return step, nil
 }
 
+func mapSimulateWorkflowRequest(req transport.SimulateWorkflowRequest) (service.SimulateWorkflowInput, error) {
+	input := service.SimulateWorkflowInput{
+		Trigger:   req.Trigger,
+		Variables: req.Variables,
+		StartAt:   req.StartAt,
+		Language:  req.Language,
+	}
+	if req.WorkflowID != nil {
+		workflowID, err := uuid.Parse(*req.WorkflowID)
+		if err != nil {
+			return service.SimulateWorkflowInput{}, fmt.Errorf("%s", msgInvalidWorkflowID)
+		}
+		input.WorkflowID = &workflowID
+	}
+	if req.Workflow != nil {
+		workflow, err := mapWorkflowUpsertRequest(*req.Workflow)
+		if err != nil {
+			return service.SimulateWorkflowInput{}, err
+		}
+		input.Workflow = &workflow
+	}
+	if req.LeadID != nil {
+		leadID, err := uuid.Parse(*req.LeadID)
+		if err != nil {
+			return service.SimulateWorkflowInput{}, fmt.Errorf("%s", msgInvalidLeadID)
+		}
+		input.LeadID = &leadID
+	}
+	input.SampleLead = mapWorkflowSampleLead(req.SampleLead)
+	return input, nil
+}
+
+func mapWorkflowSampleLead(req *transport.WorkflowSampleLeadRequest) *service.WorkflowSampleLead {
+	if req == nil {
+		return nil
+	}
+	return &service.WorkflowSampleLead{
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Street:      req.Street,
+		HouseNumber: req.HouseNumber,
+		ZipCode:     req.ZipCode,
+		City:        req.City,
+		ServiceType: req.ServiceType,
+	}
+}
+
+func mapSimulateWorkflowResponse(result service.SimulateWorkflowResult) transport.SimulateWorkflowResponse {
+	steps := make([]transport.SimulatedWorkflowStepResponse, 0, len(result.Steps))
+	for _, step := range result.Steps {
+		var stepID *string
+		if step.StepID != nil {
+			id := step.StepID.String()
+			stepID = &id
+		}
+		steps = append(steps, transport.SimulatedWorkflowStepResponse{
+			StepID:              stepID,
+			StepOrder:           step.StepOrder,
+			Trigger:             step.Trigger,
+			Channel:             step.Channel,
+			Audience:            step.Audience,
+			Language:            step.Language,
+			Enabled:             step.Enabled,
+			DelayMinutes:        step.DelayMinutes,
+			SendAt:              step.SendAt,
+			Subject:             step.Subject,
+			Body:                step.Body,
+			Recipients:          nonNilStrings(step.Recipients),
+			TemplateErrors:      nonNilStrings(step.TemplateErrors),
+			UnresolvedVariables: nonNilStrings(step.UnresolvedVariables),
+			EmptyVariables:      nonNilStrings(step.EmptyVariables),
+			SendCondition:       step.SendCondition,
+			ConditionMet:        step.ConditionMet,
+			Skipped:             step.Skipped,
+			SkipReason:          step.SkipReason,
+		})
+	}
+	return transport.SimulateWorkflowResponse{Valid: result.Valid, Steps: steps}
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
 func parseAssignmentRuleUpsert(rule transport.UpsertWorkflowAssignmentRuleRequest) (repository.WorkflowAssignmentRuleUpsert, error) {
 	workflowID, err := uuid.Parse(rule.WorkflowID)
 	if err != nil {
@@ -882,3 +1152,21 @@ func stringSliceFromAny(value any) []string {
 	}
 	return result
 }
+
+func mapWorkflowVariableCatalogResponse(catalog []service.WorkflowTriggerVariables) transport.WorkflowVariableCatalogResponse {
+	triggers := make([]transport.WorkflowTriggerVariablesResponse, 0, len(catalog))
+	for _, entry := range catalog {
+		variables := make([]transport.WorkflowVariableResponse, 0, len(entry.Variables))
+		for _, variable := range entry.Variables {
+			variables = append(variables, transport.WorkflowVariableResponse{
+				Path:        variable.Path,
+				Placeholder: "{{" + variable.Path + "}}",
+				Type:        variable.Type,
+				Example:     variable.Example,
+				Source:      variable.Source,
+			})
+		}
+		triggers = append(triggers, transport.WorkflowTriggerVariablesResponse{Trigger: entry.Trigger, Variables: variables})
+	}
+	return transport.WorkflowVariableCatalogResponse{Triggers: triggers}
+}
@@ -18,8 +18,10 @@ import (
 )
 
 type Module struct {
-	handler *handler.Handler
-	service *service.Service
+	handler  *handler.Handler
+	service  *service.Service
+	repo     *repository.Repository
+	leadRepo *leadsrepo.Repository
 }
 
 func NewModule(pool *pgxpool.Pool, eventBus events.Bus, storageSvc storage.StorageService, logoBucket string, val *validator.Validator, whatsappClient *whatsapp.Client) *Module {
@@ -28,7 +30,7 @@ func NewModule(pool *pgxpool.Pool, eventBus events.Bus, storageSvc storage.Stora
 	svc := service.New(repo, leadRepo, eventBus, storageSvc, logoBucket, whatsappClient)
 	h := handler.New(svc, val)
 
-	return &Module{handler: h, service: svc}
+	return &Module{handler: h, service: svc, repo: repo, leadRepo: leadRepo}
 }
 
 func (m *Module) Name() string {
@@ -39,9 +41,20 @@ func (m *Module) Service() *service.Service {
 	return m.service
 }
 
+// SetLeadContactCipher lets the module read and match leads whose contact
+// details are encrypted.
+func (m *Module) SetLeadContactCipher(cipher *leadsrepo.ContactCipher) {
+	m.repo.SetLeadContactCipher(cipher)
+	m.leadRepo.SetContactCipher(cipher)
+}
+
 func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	m.handler.RegisterRoutes(ctx.Admin)
 	m.handler.RegisterProtectedRoutes(ctx.Protected)
+	m.handler.RegisterPublicRoutes(ctx.Public)
+	if ctx.SuperAdmin != nil {
+		m.handler.RegisterSuperAdminRoutes(ctx.SuperAdmin)
+	}
 }
 
 func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// OrganizationAISettings is the AI provider configuration of an organization.
+// Nil fields fall back to the platform defaults.
+type OrganizationAISettings struct {
+	OrganizationID     uuid.UUID
+	Provider           *string
+	Model              *string
+	Temperature        *float64
+	MonthlyTokenBudget *int64
+	UpdatedAt          *time.Time
+}
+
+// AIUsageRecord is the token usage of a single AI call.
+type AIUsageRecord struct {
+	OrganizationID   uuid.UUID
+	Kind             string
+	Source           string
+	Provider         string
+	Model            string
+	PromptTokens     int64
+	CompletionTokens int64
+	EstimatedCostUSD float64
+}
+
+// AIUsageByModel aggregates token usage per provider model.
+type AIUsageByModel struct {
+	Kind             string
+	Provider         string
+	Model            string
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+	EstimatedCostUSD float64
+}
+
+// AIUsageDay aggregates token usage per UTC day and call kind.
+type AIUsageDay struct {
+	Day              time.Time
+	Kind             string
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+	EstimatedCostUSD float64
+}
+
+// GetOrganizationAISettings returns the AI settings of an organization, or empty settings
+// when none were configured.
+func (r *Repository) GetOrganizationAISettings(ctx context.Context, organizationID uuid.UUID) (OrganizationAISettings, error) {
+	settings := OrganizationAISettings{OrganizationID: organizationID}
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT provider, model, temperature, monthly_token_budget, updated_at
+		FROM RAC_organization_ai_settings
+		WHERE organization_id = $1`, organizationID,
+	).Scan(&settings.Provider, &settings.Model, &settings.Temperature, &settings.MonthlyTokenBudget, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return settings, nil
+	}
+	if err != nil {
+		return OrganizationAISettings{}, fmt.Errorf("get organization ai settings: %w", err)
+	}
+	settings.UpdatedAt = &updatedAt
+	return settings, nil
+}
+
+// UpsertOrganizationAISettings replaces the AI settings of an organization.
+func (r *Repository) UpsertOrganizationAISettings(ctx context.Context, settings OrganizationAISettings) (OrganizationAISettings, error) {
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_organization_ai_settings (organization_id, provider, model, temperature, monthly_token_budget, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+		ON CONFLICT (organization_id) DO UPDATE SET
+			provider = EXCLUDED.provider,
+			model = EXCLUDED.model,
+			temperature = EXCLUDED.temperature,
+			monthly_token_budget = EXCLUDED.monthly_token_budget,
+			updated_at = now()
+		RETURNING updated_at`,
+		settings.OrganizationID, settings.Provider, settings.Model, settings.Temperature, settings.MonthlyTokenBudget,
+	).Scan(&updatedAt)
+	if err != nil {
+		return OrganizationAISettings{}, fmt.Errorf("upsert organization ai settings: %w", err)
+	}
+	settings.UpdatedAt = &updatedAt
+	return settings, nil
+}
+
+// InsertAIUsage appends a usage record to the AI usage ledger.
+func (r *Repository) InsertAIUsage(ctx context.Context, usage AIUsageRecord) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_ai_usage_events (organization_id, kind, source, provider, model, prompt_tokens, completion_tokens, estimated_cost_usd)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		usage.OrganizationID, usage.Kind, usage.Source, usage.Provider, usage.Model,
+		usage.PromptTokens, usage.CompletionTokens, usage.EstimatedCostUSD,
+	)
+	if err != nil {
+		return fmt.Errorf("insert ai usage: %w", err)
+	}
+	return nil
+}
+
+// SumAITokensSince returns the chat prompt and completion tokens used since the given time.
+// Embeddings are billed but do not count towards the token budget.
+func (r *Repository) SumAITokensSince(ctx context.Context, organizationID uuid.UUID, since time.Time) (int64, error) {
+	var total int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(prompt_tokens + completion_tokens), 0)::bigint
+		FROM RAC_ai_usage_events
+		WHERE organization_id = $1 AND kind = 'chat' AND created_at >= $2`, organizationID, since,
+	).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("sum ai tokens: %w", err)
+	}
+	return total, nil
+}
+
+// ListAIUsageByModel aggregates usage since the given time per provider model.
+func (r *Repository) ListAIUsageByModel(ctx context.Context, organizationID uuid.UUID, since time.Time) ([]AIUsageByModel, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT kind, provider, model, COUNT(*)::bigint,
+			COALESCE(SUM(prompt_tokens), 0)::bigint, COALESCE(SUM(completion_tokens), 0)::bigint,
+			COALESCE(SUM(estimated_cost_usd), 0)::float8
+		FROM RAC_ai_usage_events
+		WHERE organization_id = $1 AND created_at >= $2
+		GROUP BY kind, provider, model
+		ORDER BY SUM(prompt_tokens + completion_tokens) DESC`, organizationID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list ai usage by model: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]AIUsageByModel, 0)
+	for rows.Next() {
+		var item AIUsageByModel
+		if err := rows.Scan(&item.Kind, &item.Provider, &item.Model, &item.Calls, &item.PromptTokens, &item.CompletionTokens, &item.EstimatedCostUSD); err != nil {
+			return nil, fmt.Errorf("scan ai usage by model: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ListAIUsageDaily aggregates usage per UTC day and kind for created_at in [from, to).
+func (r *Repository) ListAIUsageDaily(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]AIUsageDay, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT date_trunc('day', created_at AT TIME ZONE 'UTC')::date AS day, kind, COUNT(*)::bigint,
+			COALESCE(SUM(prompt_tokens), 0)::bigint, COALESCE(SUM(completion_tokens), 0)::bigint,
+			COALESCE(SUM(estimated_cost_usd), 0)::float8
+		FROM RAC_ai_usage_events
+		WHERE organization_id = $1 AND created_at >= $2 AND created_at < $3
+		GROUP BY day, kind
+		ORDER BY day, kind`, organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list daily ai usage: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]AIUsageDay, 0)
+	for rows.Next() {
+		var item AIUsageDay
+		if err := rows.Scan(&item.Day, &item.Kind, &item.Calls, &item.PromptTokens, &item.CompletionTokens, &item.EstimatedCostUSD); err != nil {
+			return nil, fmt.Errorf("scan daily ai usage: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
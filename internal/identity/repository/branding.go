@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// OrganizationBranding customizes the emails, quote PDFs and public portals
+// that customers of an organization see. Nil fields use the platform default.
+type OrganizationBranding struct {
+	OrganizationID    uuid.UUID
+	PrimaryColor      *string
+	AccentColor       *string
+	EmailHeaderHTML   *string
+	EmailFooterHTML   *string
+	SenderDisplayName *string
+	UpdatedAt         *time.Time
+}
+
+// GetOrganizationBranding returns the branding of an organization, or empty
+// branding when none was configured.
+func (r *Repository) GetOrganizationBranding(ctx context.Context, organizationID uuid.UUID) (OrganizationBranding, error) {
+	branding := OrganizationBranding{OrganizationID: organizationID}
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT primary_color, accent_color, email_header_html, email_footer_html, sender_display_name, updated_at
+		FROM RAC_organization_branding
+		WHERE organization_id = $1`, organizationID,
+	).Scan(&branding.PrimaryColor, &branding.AccentColor, &branding.EmailHeaderHTML, &branding.EmailFooterHTML,
+		&branding.SenderDisplayName, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return branding, nil
+	}
+	if err != nil {
+		return OrganizationBranding{}, fmt.Errorf("get organization branding: %w", err)
+	}
+	branding.UpdatedAt = &updatedAt
+	return branding, nil
+}
+
+// UpsertOrganizationBranding replaces the branding of an organization.
+func (r *Repository) UpsertOrganizationBranding(ctx context.Context, branding OrganizationBranding) (OrganizationBranding, error) {
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_organization_branding (
+			organization_id, primary_color, accent_color, email_header_html, email_footer_html, sender_display_name, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, now())
+		ON CONFLICT (organization_id) DO UPDATE SET
+			primary_color = EXCLUDED.primary_color,
+			accent_color = EXCLUDED.accent_color,
+			email_header_html = EXCLUDED.email_header_html,
+			email_footer_html = EXCLUDED.email_footer_html,
+			sender_display_name = EXCLUDED.sender_display_name,
+			updated_at = now()
+		RETURNING updated_at`,
+		branding.OrganizationID, branding.PrimaryColor, branding.AccentColor, branding.EmailHeaderHTML,
+		branding.EmailFooterHTML, branding.SenderDisplayName,
+	).Scan(&updatedAt)
+	if err != nil {
+		return OrganizationBranding{}, fmt.Errorf("upsert organization branding: %w", err)
+	}
+	branding.UpdatedAt = &updatedAt
+	return branding, nil
+}
+
+// DeleteOrganizationBranding resets an organization to the default branding.
+func (r *Repository) DeleteOrganizationBranding(ctx context.Context, organizationID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM RAC_organization_branding WHERE organization_id = $1`, organizationID); err != nil {
+		return fmt.Errorf("delete organization branding: %w", err)
+	}
+	return nil
+}
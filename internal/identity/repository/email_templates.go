@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// EmailTemplateVersion is one saved revision of an organization's override
+// of a system email.
+type EmailTemplateVersion struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	TemplateKey    string
+	Version        int
+	Subject        string
+	BodyHTML       string
+	CreatedBy      *uuid.UUID
+	CreatedAt      time.Time
+}
+
+// EmailTemplateOverride records which version of a system email an
+// organization sends instead of the built-in template.
+type EmailTemplateOverride struct {
+	TemplateKey   string
+	ActiveVersion int
+	UpdatedAt     time.Time
+}
+
+const emailTemplateVersionColumns = `v.id, v.organization_id, v.template_key, v.version, v.subject, v.body_html, v.created_by, v.created_at`
+
+func scanEmailTemplateVersion(row pgx.Row) (EmailTemplateVersion, error) {
+	var v EmailTemplateVersion
+	err := row.Scan(&v.ID, &v.OrganizationID, &v.TemplateKey, &v.Version, &v.Subject, &v.BodyHTML, &v.CreatedBy, &v.CreatedAt)
+	return v, err
+}
+
+// ListEmailTemplateOverrides returns the system emails an organization overrides.
+func (r *Repository) ListEmailTemplateOverrides(ctx context.Context, organizationID uuid.UUID) ([]EmailTemplateOverride, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT template_key, active_version, updated_at
+		FROM RAC_email_template_overrides
+		WHERE organization_id = $1
+		ORDER BY template_key`, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list email template overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := make([]EmailTemplateOverride, 0)
+	for rows.Next() {
+		var o EmailTemplateOverride
+		if err := rows.Scan(&o.TemplateKey, &o.ActiveVersion, &o.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan email template override: %w", err)
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, rows.Err()
+}
+
+// GetActiveEmailTemplate returns the version of a system email an
+// organization sends, or ErrNotFound when it uses the built-in template.
+func (r *Repository) GetActiveEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey string) (EmailTemplateVersion, error) {
+	version, err := scanEmailTemplateVersion(r.pool.QueryRow(ctx, `
+		SELECT `+emailTemplateVersionColumns+`
+		FROM RAC_email_template_overrides o
+		JOIN RAC_email_template_versions v
+			ON v.organization_id = o.organization_id AND v.template_key = o.template_key AND v.version = o.active_version
+		WHERE o.organization_id = $1 AND o.template_key = $2`, organizationID, templateKey))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return EmailTemplateVersion{}, ErrNotFound
+	}
+	if err != nil {
+		return EmailTemplateVersion{}, fmt.Errorf("get active email template: %w", err)
+	}
+	return version, nil
+}
+
+// ListEmailTemplateVersions returns every saved version of a system email
+// override, newest first.
+func (r *Repository) ListEmailTemplateVersions(ctx context.Context, organizationID uuid.UUID, templateKey string) ([]EmailTemplateVersion, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+emailTemplateVersionColumns+`
+		FROM RAC_email_template_versions v
+		WHERE v.organization_id = $1 AND v.template_key = $2
+		ORDER BY v.version DESC`, organizationID, templateKey)
+	if err != nil {
+		return nil, fmt.Errorf("list email template versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]EmailTemplateVersion, 0)
+	for rows.Next() {
+		version, err := scanEmailTemplateVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan email template version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// CreateEmailTemplateVersion saves a new version of a system email override
+// and makes it the active one.
+func (r *Repository) CreateEmailTemplateVersion(ctx context.Context, organizationID uuid.UUID, templateKey, subject, bodyHTML string, createdBy uuid.UUID) (EmailTemplateVersion, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return EmailTemplateVersion{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Locking the override row serializes concurrent saves of one template;
+	// concurrent first saves are caught by the unique version constraint.
+	if _, err := tx.Exec(ctx, `
+		SELECT 1 FROM RAC_email_template_overrides
+		WHERE organization_id = $1 AND template_key = $2
+		FOR UPDATE`, organizationID, templateKey); err != nil {
+		return EmailTemplateVersion{}, fmt.Errorf("lock email template override: %w", err)
+	}
+
+	version, err := scanEmailTemplateVersion(tx.QueryRow(ctx, `
+		INSERT INTO RAC_email_template_versions AS v (organization_id, template_key, version, subject, body_html, created_by)
+		SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5
+		FROM RAC_email_template_versions
+		WHERE organization_id = $1 AND template_key = $2
+		RETURNING `+emailTemplateVersionColumns,
+		organizationID, templateKey, subject, bodyHTML, createdBy))
+	if err != nil {
+		return EmailTemplateVersion{}, fmt.Errorf("create email template version: %w", err)
+	}
+
+	if err := activateEmailTemplateVersion(ctx, tx, organizationID, templateKey, version.Version); err != nil {
+		return EmailTemplateVersion{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return EmailTemplateVersion{}, err
+	}
+	return version, nil
+}
+
+// ActivateEmailTemplateVersion makes an earlier saved version the one that is
+// sent. It returns ErrNotFound when the version does not exist.
+func (r *Repository) ActivateEmailTemplateVersion(ctx context.Context, organizationID uuid.UUID, templateKey string, version int) error {
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM RAC_email_template_versions
+			WHERE organization_id = $1 AND template_key = $2 AND version = $3
+		)`, organizationID, templateKey, version).Scan(&exists); err != nil {
+		return fmt.Errorf("check email template version: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return activateEmailTemplateVersion(ctx, r.pool, organizationID, templateKey, version)
+}
+
+func activateEmailTemplateVersion(ctx context.Context, q DBTX, organizationID uuid.UUID, templateKey string, version int) error {
+	if _, err := q.Exec(ctx, `
+		INSERT INTO RAC_email_template_overrides (organization_id, template_key, active_version, updated_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (organization_id, template_key) DO UPDATE SET
+			active_version = EXCLUDED.active_version,
+			updated_at = now()`,
+		organizationID, templateKey, version); err != nil {
+		return fmt.Errorf("activate email template version: %w", err)
+	}
+	return nil
+}
+
+// DeleteEmailTemplateOverride makes an organization send the built-in
+// template again. Saved versions are kept so they can be activated later.
+func (r *Repository) DeleteEmailTemplateOverride(ctx context.Context, organizationID uuid.UUID, templateKey string) error {
+	if _, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_email_template_overrides
+		WHERE organization_id = $1 AND template_key = $2`, organizationID, templateKey); err != nil {
+		return fmt.Errorf("delete email template override: %w", err)
+	}
+	return nil
+}
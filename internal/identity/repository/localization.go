@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetOrganizationDefaultLanguage returns the language customer-facing
+// notifications of an organization fall back to.
+func (r *Repository) GetOrganizationDefaultLanguage(ctx context.Context, organizationID uuid.UUID) (string, error) {
+	var language string
+	err := r.pool.QueryRow(ctx, `
+		SELECT default_language FROM RAC_organizations WHERE id = $1`, organizationID,
+	).Scan(&language)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("get organization default language: %w", err)
+	}
+	return language, nil
+}
+
+// SetOrganizationDefaultLanguage sets the fallback notification language of
+// an organization.
+func (r *Repository) SetOrganizationDefaultLanguage(ctx context.Context, organizationID uuid.UUID, language string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_organizations SET default_language = $2, updated_at = now()
+		WHERE id = $1`, organizationID, language,
+	)
+	if err != nil {
+		return fmt.Errorf("set organization default language: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	DefaultQuietHoursStart   = 21 * 60
+	DefaultQuietHoursEnd     = 8 * 60
+	DefaultMessagingTimezone = "Europe/Amsterdam"
+)
+
+// OrganizationMessagingPolicy limits when and how often outbound messages
+// reach a lead. Quiet hours are minutes since midnight in Timezone; nil caps
+// are unlimited. EmailTracking opts in to open and click tracking of emails.
+type OrganizationMessagingPolicy struct {
+	OrganizationID            uuid.UUID
+	QuietHoursEnabled         bool
+	QuietHoursStart           int
+	QuietHoursEnd             int
+	Timezone                  string
+	MaxMessagesPerLeadPerHour *int
+	MaxMessagesPerLeadPerDay  *int
+	IncludeEmail              bool
+	EmailTracking             bool
+	UpdatedAt                 *time.Time
+}
+
+// GetOrganizationMessagingPolicy returns the messaging policy of an
+// organization, or the disabled default when none was configured.
+func (r *Repository) GetOrganizationMessagingPolicy(ctx context.Context, organizationID uuid.UUID) (OrganizationMessagingPolicy, error) {
+	policy := OrganizationMessagingPolicy{
+		OrganizationID:  organizationID,
+		QuietHoursStart: DefaultQuietHoursStart,
+		QuietHoursEnd:   DefaultQuietHoursEnd,
+		Timezone:        DefaultMessagingTimezone,
+	}
+	var start, end int16
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT quiet_hours_enabled, quiet_hours_start, quiet_hours_end, timezone,
+			max_messages_per_lead_per_hour, max_messages_per_lead_per_day, include_email, email_tracking_enabled, updated_at
+		FROM RAC_organization_messaging_policies
+		WHERE organization_id = $1`, organizationID,
+	).Scan(&policy.QuietHoursEnabled, &start, &end, &policy.Timezone,
+		&policy.MaxMessagesPerLeadPerHour, &policy.MaxMessagesPerLeadPerDay, &policy.IncludeEmail, &policy.EmailTracking, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return policy, nil
+	}
+	if err != nil {
+		return OrganizationMessagingPolicy{}, fmt.Errorf("get organization messaging policy: %w", err)
+	}
+	policy.QuietHoursStart = int(start)
+	policy.QuietHoursEnd = int(end)
+	policy.UpdatedAt = &updatedAt
+	return policy, nil
+}
+
+// UpsertOrganizationMessagingPolicy replaces the messaging policy of an
+// organization.
+func (r *Repository) UpsertOrganizationMessagingPolicy(ctx context.Context, policy OrganizationMessagingPolicy) (OrganizationMessagingPolicy, error) {
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_organization_messaging_policies (
+			organization_id, quiet_hours_enabled, quiet_hours_start, quiet_hours_end, timezone,
+			max_messages_per_lead_per_hour, max_messages_per_lead_per_day, include_email, email_tracking_enabled, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now())
+		ON CONFLICT (organization_id) DO UPDATE SET
+			quiet_hours_enabled = EXCLUDED.quiet_hours_enabled,
+			quiet_hours_start = EXCLUDED.quiet_hours_start,
+			quiet_hours_end = EXCLUDED.quiet_hours_end,
+			timezone = EXCLUDED.timezone,
+			max_messages_per_lead_per_hour = EXCLUDED.max_messages_per_lead_per_hour,
+			max_messages_per_lead_per_day = EXCLUDED.max_messages_per_lead_per_day,
+			include_email = EXCLUDED.include_email,
+			email_tracking_enabled = EXCLUDED.email_tracking_enabled,
+			updated_at = now()
+		RETURNING updated_at`,
+		policy.OrganizationID, policy.QuietHoursEnabled, int16(policy.QuietHoursStart), int16(policy.QuietHoursEnd), policy.Timezone,
+		policy.MaxMessagesPerLeadPerHour, policy.MaxMessagesPerLeadPerDay, policy.IncludeEmail, policy.EmailTracking,
+	).Scan(&updatedAt)
+	if err != nil {
+		return OrganizationMessagingPolicy{}, fmt.Errorf("upsert organization messaging policy: %w", err)
+	}
+	policy.UpdatedAt = &updatedAt
+	return policy, nil
+}
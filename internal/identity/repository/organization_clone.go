@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Clone purposes.
+const (
+	ClonePurposeDemo    = "demo"
+	ClonePurposeSandbox = "sandbox"
+)
+
+// cloneTable describes how the rows of one organization-scoped table are copied.
+// Rows are copied column by column through jsonb, so columns added later are
+// cloned without changes here.
+type cloneTable struct {
+	name string
+	// keyed tables get a fresh id per row; the old-to-new mapping is used to
+	// rewrite the remapped columns of tables cloned after them.
+	keyed bool
+	remap []string
+	// clear lists credential and contact columns that must not leak into the copy.
+	clear []string
+}
+
+// organizationCloneTables lists the configuration that is copied, in dependency
+// order. Customer data (leads, quotes, appointments, conversations, partners),
+// credentials (SSO, telephony, API keys, price feed tokens) and stored files are
+// deliberately left out.
+var organizationCloneTables = []cloneTable{
+	{name: "RAC_organization_settings", clear: []string{
+		"smtp_host", "smtp_port", "smtp_username", "smtp_password", "smtp_from_email", "smtp_from_name",
+		"whatsapp_device_id", "whatsapp_account_jid", "notification_email",
+	}},
+	{name: "RAC_organization_branding"},
+	{name: "RAC_organization_ai_settings"},
+	{name: "RAC_organization_messaging_policies"},
+	{name: "RAC_organization_stale_lead_settings"},
+	{name: "RAC_organization_retention_policies"},
+	{name: "RAC_agent_review_policies"},
+	{name: "RAC_quote_approval_policies"},
+	{name: "RAC_quote_auto_send_policies"},
+	{name: "RAC_webhook_spam_policies"},
+	{name: "RAC_email_template_versions", keyed: true},
+	{name: "RAC_email_template_overrides"},
+	{name: "RAC_custom_field_definitions", keyed: true},
+	{name: "RAC_service_types", keyed: true},
+	{name: "RAC_workflows", keyed: true},
+	{name: "RAC_workflow_steps", keyed: true, remap: []string{"workflow_id"}},
+	{name: "RAC_workflow_assignment_rules", keyed: true, remap: []string{"workflow_id"}},
+	{name: "rac_product_flows", keyed: true},
+	{name: "RAC_partner_offer_terms", keyed: true},
+	{name: "RAC_catalog_vat_rates", keyed: true},
+	{name: "RAC_catalog_products", keyed: true, remap: []string{"vat_rate_id"}},
+	{name: "RAC_catalog_product_materials", remap: []string{"product_id", "material_id"}},
+	{name: "RAC_catalog_product_counters"},
+	{name: "RAC_catalog_stock_settings"},
+	{name: "RAC_quote_templates", keyed: true},
+	{name: "RAC_quote_template_items", keyed: true, remap: []string{"template_id", "catalog_product_id"}},
+}
+
+// CloneOrganizationParams describes a new organization copied from a source.
+type CloneOrganizationParams struct {
+	SourceOrganizationID uuid.UUID
+	Name                 string
+	Purpose              string
+	CreatedBy            uuid.UUID
+}
+
+// CloneOrganizationResult reports the new organization and the number of rows
+// copied per table.
+type CloneOrganizationResult struct {
+	OrganizationID uuid.UUID
+	CopiedRows     map[string]int64
+}
+
+// CloneOrganization creates a new organization with the profile and
+// configuration of the source organization in one transaction. The logo is not
+// copied because stored files belong to a single organization.
+func (r *Repository) CloneOrganization(ctx context.Context, params CloneOrganizationParams) (CloneOrganizationResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return CloneOrganizationResult{}, fmt.Errorf("begin clone organization tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var orgID uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO RAC_organizations
+		SELECT (jsonb_populate_record(NULL::RAC_organizations, to_jsonb(src) || jsonb_build_object(
+			'id', gen_random_uuid(), 'name', $2::text, 'created_by', $3::uuid,
+			'created_at', now(), 'updated_at', now(),
+			'logo_file_key', NULL, 'logo_file_name', NULL, 'logo_content_type', NULL, 'logo_size_bytes', NULL,
+			'parent_organization_id', NULL
+		))).*
+		FROM RAC_organizations src
+		WHERE src.id = $1
+		RETURNING id`,
+		params.SourceOrganizationID, params.Name, params.CreatedBy,
+	).Scan(&orgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return CloneOrganizationResult{}, ErrNotFound
+	}
+	if err != nil {
+		return CloneOrganizationResult{}, fmt.Errorf("copy organization: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE organization_clone_ids (old_id UUID PRIMARY KEY, new_id UUID NOT NULL) ON COMMIT DROP`,
+	); err != nil {
+		return CloneOrganizationResult{}, fmt.Errorf("create clone id map: %w", err)
+	}
+
+	copied := make(map[string]int64, len(organizationCloneTables))
+	for _, table := range organizationCloneTables {
+		if table.keyed {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO organization_clone_ids (old_id, new_id)
+				SELECT id, gen_random_uuid() FROM `+table.name+` WHERE organization_id = $1`,
+				params.SourceOrganizationID,
+			); err != nil {
+				return CloneOrganizationResult{}, fmt.Errorf("map %s ids: %w", table.name, err)
+			}
+		}
+		tag, err := tx.Exec(ctx, cloneTableSQL(table), params.SourceOrganizationID, orgID)
+		if err != nil {
+			return CloneOrganizationResult{}, fmt.Errorf("copy %s: %w", table.name, err)
+		}
+		copied[table.name] = tag.RowsAffected()
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_organization_clones (organization_id, source_organization_id, purpose, created_by)
+		VALUES ($1, $2, $3, $4)`,
+		orgID, params.SourceOrganizationID, params.Purpose, params.CreatedBy,
+	); err != nil {
+		return CloneOrganizationResult{}, fmt.Errorf("record organization clone: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return CloneOrganizationResult{}, fmt.Errorf("commit clone organization tx: %w", err)
+	}
+	return CloneOrganizationResult{OrganizationID: orgID, CopiedRows: copied}, nil
+}
+
+// RecordCloneDemoLeads stores how many demo leads were generated for a cloned organization.
+func (r *Repository) RecordCloneDemoLeads(ctx context.Context, organizationID uuid.UUID, demoLeads int) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_organization_clones SET demo_leads = $2 WHERE organization_id = $1`,
+		organizationID, demoLeads,
+	); err != nil {
+		return fmt.Errorf("record clone demo leads: %w", err)
+	}
+	return nil
+}
+
+// ListActiveServiceTypeNames returns the names of the active service types of an organization.
+func (r *Repository) ListActiveServiceTypeNames(ctx context.Context, organizationID uuid.UUID) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT name FROM RAC_service_types
+		WHERE organization_id = $1 AND is_active = true
+		ORDER BY display_order, name`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list service types: %w", err)
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scan service types: %w", err)
+	}
+	return names, nil
+}
+
+// cloneTableSQL builds the statement that copies the rows of one table from the
+// source organization ($1) to the new organization ($2).
+func cloneTableSQL(table cloneTable) string {
+	overrides := []string{"'organization_id', $2::uuid"}
+	joins := make([]string, 0, len(table.remap)+1)
+	if table.keyed {
+		overrides = append(overrides, "'id', ids.new_id")
+		joins = append(joins, "JOIN organization_clone_ids ids ON ids.old_id = src.id")
+	}
+	for i, column := range table.remap {
+		alias := fmt.Sprintf("m%d", i)
+		overrides = append(overrides, fmt.Sprintf("'%s', %s.new_id", column, alias))
+		joins = append(joins, fmt.Sprintf("LEFT JOIN organization_clone_ids %s ON %s.old_id = src.%s", alias, alias, column))
+	}
+	for _, column := range table.clear {
+		overrides = append(overrides, fmt.Sprintf("'%s', NULL", column))
+	}
+
+	return fmt.Sprintf(`INSERT INTO %[1]s
+		SELECT (jsonb_populate_record(NULL::%[1]s, to_jsonb(src) || jsonb_build_object(%[2]s))).*
+		FROM %[1]s src %[3]s
+		WHERE src.organization_id = $1`,
+		table.name, strings.Join(overrides, ", "), strings.Join(joins, " "))
+}
@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// OrganizationBranch is an organization that belongs to a head office.
+type OrganizationBranch struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+}
+
+// BranchCatalogSync reports how many inherited products a catalog sync created
+// and refreshed in a branch.
+type BranchCatalogSync struct {
+	Created int64
+	Updated int64
+}
+
+// inheritedProductColumns are refreshed on inherited products the branch has
+// not edited. New copies get every column of the head-office product.
+const inheritedProductColumns = `title, reference, description, price_cents, unit_price_cents, unit_label,
+	labor_time_text, type, period_count, period_unit, is_draft`
+
+// SetParentOrganization makes an organization a branch of a head office. The
+// hierarchy is one level deep: a head office cannot be a branch itself and a
+// branch cannot have branches.
+func (r *Repository) SetParentOrganization(ctx context.Context, branchID, parentID uuid.UUID) error {
+	if branchID == parentID {
+		return apperr.Validation("an organization cannot be its own head office")
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin set parent organization tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, parent_organization_id,
+			EXISTS (SELECT 1 FROM RAC_organizations b WHERE b.parent_organization_id = o.id)
+		FROM RAC_organizations o
+		WHERE o.id = ANY($1::uuid[])
+		FOR UPDATE`, []uuid.UUID{branchID, parentID},
+	)
+	if err != nil {
+		return fmt.Errorf("lock organizations: %w", err)
+	}
+	type node struct {
+		parentID    *uuid.UUID
+		hasBranches bool
+	}
+	nodes := make(map[uuid.UUID]node, 2)
+	for rows.Next() {
+		var id uuid.UUID
+		var n node
+		if err := rows.Scan(&id, &n.parentID, &n.hasBranches); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan organization: %w", err)
+		}
+		nodes[id] = n
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("lock organizations: %w", err)
+	}
+
+	branch, branchFound := nodes[branchID]
+	parent, parentFound := nodes[parentID]
+	if !branchFound || !parentFound {
+		return ErrNotFound
+	}
+	if parent.parentID != nil {
+		return apperr.Validation("a branch cannot be a head office")
+	}
+	if branch.hasBranches {
+		return apperr.Validation("a head office cannot become a branch")
+	}
+	if branch.parentID != nil && *branch.parentID != parentID {
+		return apperr.Conflict("organization already belongs to another head office")
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_organizations SET parent_organization_id = $2, updated_at = now()
+		WHERE id = $1`, branchID, parentID,
+	); err != nil {
+		return fmt.Errorf("set parent organization: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit set parent organization tx: %w", err)
+	}
+	return nil
+}
+
+// ClearParentOrganization detaches a branch from its head office. Inherited
+// products stay with the branch as its own products.
+func (r *Repository) ClearParentOrganization(ctx context.Context, branchID uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin clear parent organization tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE RAC_organizations SET parent_organization_id = NULL, updated_at = now()
+		WHERE id = $1 AND parent_organization_id IS NOT NULL`, branchID,
+	)
+	if err != nil {
+		return fmt.Errorf("clear parent organization: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM RAC_catalog_inherited_products WHERE organization_id = $1`, branchID,
+	); err != nil {
+		return fmt.Errorf("detach inherited products: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit clear parent organization tx: %w", err)
+	}
+	return nil
+}
+
+// GetParentOrganizationID returns the head office of an organization, or nil
+// when the organization is not a branch.
+func (r *Repository) GetParentOrganizationID(ctx context.Context, organizationID uuid.UUID) (*uuid.UUID, error) {
+	var parentID *uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT parent_organization_id FROM RAC_organizations WHERE id = $1`, organizationID,
+	).Scan(&parentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get parent organization: %w", err)
+	}
+	return parentID, nil
+}
+
+// ListBranches returns the branches of a head office by name.
+func (r *Repository) ListBranches(ctx context.Context, parentID uuid.UUID) ([]OrganizationBranch, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, name, created_at FROM RAC_organizations
+		WHERE parent_organization_id = $1
+		ORDER BY name, id`, parentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	defer rows.Close()
+
+	branches := make([]OrganizationBranch, 0)
+	for rows.Next() {
+		var b OrganizationBranch
+		if err := rows.Scan(&b.ID, &b.Name, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan branch: %w", err)
+		}
+		branches = append(branches, b)
+	}
+	return branches, rows.Err()
+}
+
+// IsBranchOf reports whether an organization is a branch of the head office.
+func (r *Repository) IsBranchOf(ctx context.Context, parentID, branchID uuid.UUID) (bool, error) {
+	var ok bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM RAC_organizations WHERE id = $2 AND parent_organization_id = $1)`,
+		parentID, branchID,
+	).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("check branch: %w", err)
+	}
+	return ok, nil
+}
+
+// SyncBranchCatalog copies the published head-office products the branch does
+// not have yet, together with the VAT rates and material links they need, and
+// refreshes the inherited products the branch has not edited. Products the
+// branch created itself are left alone.
+func (r *Repository) SyncBranchCatalog(ctx context.Context, branchID uuid.UUID) (BranchCatalogSync, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("begin branch catalog sync tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var parentID *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT parent_organization_id FROM RAC_organizations WHERE id = $1 FOR UPDATE`, branchID,
+	).Scan(&parentID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && parentID == nil) {
+		return BranchCatalogSync{}, ErrNotFound
+	}
+	if err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("lock branch: %w", err)
+	}
+
+	// VAT rates are matched by name, which is unique per organization.
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_catalog_vat_rates
+		SELECT (jsonb_populate_record(NULL::RAC_catalog_vat_rates, to_jsonb(src) || jsonb_build_object(
+			'id', gen_random_uuid(), 'organization_id', $2::uuid, 'created_at', now(), 'updated_at', now()
+		))).*
+		FROM RAC_catalog_vat_rates src
+		WHERE src.organization_id = $1
+			AND NOT EXISTS (SELECT 1 FROM RAC_catalog_vat_rates b WHERE b.organization_id = $2 AND b.name = src.name)`,
+		*parentID, branchID,
+	); err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("copy vat rates: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE branch_catalog_ids (old_id UUID PRIMARY KEY, new_id UUID NOT NULL, created BOOLEAN NOT NULL) ON COMMIT DROP`,
+	); err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("create branch catalog id map: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO branch_catalog_ids (old_id, new_id, created)
+		SELECT source_product_id, product_id, false FROM RAC_catalog_inherited_products WHERE organization_id = $2
+		UNION ALL
+		SELECT p.id, gen_random_uuid(), true FROM RAC_catalog_products p
+		WHERE p.organization_id = $1 AND NOT p.is_draft
+			AND NOT EXISTS (
+				SELECT 1 FROM RAC_catalog_inherited_products i
+				WHERE i.organization_id = $2 AND i.source_product_id = p.id
+			)`,
+		*parentID, branchID,
+	); err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("map branch catalog ids: %w", err)
+	}
+
+	created, err := tx.Exec(ctx, `
+		INSERT INTO RAC_catalog_products
+		SELECT (jsonb_populate_record(NULL::RAC_catalog_products, to_jsonb(src) || jsonb_build_object(
+			'id', ids.new_id, 'organization_id', $1::uuid, 'vat_rate_id', bv.id,
+			'created_at', now(), 'updated_at', now()
+		))).*
+		FROM RAC_catalog_products src
+		JOIN branch_catalog_ids ids ON ids.old_id = src.id AND ids.created
+		JOIN RAC_catalog_vat_rates sv ON sv.id = src.vat_rate_id
+		JOIN RAC_catalog_vat_rates bv ON bv.organization_id = $1 AND bv.name = sv.name`,
+		branchID,
+	)
+	if err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("copy inherited products: %w", err)
+	}
+
+	updated, err := tx.Exec(ctx, `
+		UPDATE RAC_catalog_products dst
+		SET (`+inheritedProductColumns+`, vat_rate_id, updated_at) = (
+			SELECT `+inheritedProductColumns+`, bv.id, now()
+			FROM RAC_catalog_products src
+			JOIN RAC_catalog_vat_rates sv ON sv.id = src.vat_rate_id
+			JOIN RAC_catalog_vat_rates bv ON bv.organization_id = $1 AND bv.name = sv.name
+			WHERE src.id = i.source_product_id
+		)
+		FROM RAC_catalog_inherited_products i
+		WHERE i.product_id = dst.id AND i.organization_id = $1 AND i.overridden_at IS NULL`,
+		branchID,
+	)
+	if err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("refresh inherited products: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_catalog_inherited_products (product_id, organization_id, source_product_id)
+		SELECT ids.new_id, $1, ids.old_id
+		FROM branch_catalog_ids ids
+		JOIN RAC_catalog_products p ON p.id = ids.new_id
+		WHERE ids.created
+		ON CONFLICT (product_id) DO NOTHING`,
+		branchID,
+	); err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("link inherited products: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_catalog_inherited_products SET synced_at = now()
+		WHERE organization_id = $1 AND overridden_at IS NULL`,
+		branchID,
+	); err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("mark inherited products synced: %w", err)
+	}
+
+	// Material links are only copied for new products; afterwards the branch
+	// manages them itself.
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_catalog_product_materials (organization_id, product_id, material_id)
+		SELECT $2, pm.new_id, mm.new_id
+		FROM RAC_catalog_product_materials m
+		JOIN branch_catalog_ids pm ON pm.old_id = m.product_id AND pm.created
+		JOIN branch_catalog_ids mm ON mm.old_id = m.material_id
+		WHERE m.organization_id = $1
+		ON CONFLICT DO NOTHING`,
+		*parentID, branchID,
+	); err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("copy inherited product materials: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return BranchCatalogSync{}, fmt.Errorf("commit branch catalog sync tx: %w", err)
+	}
+	return BranchCatalogSync{Created: created.RowsAffected(), Updated: updated.RowsAffected()}, nil
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const portalDomainUniqueIndex = "idx_organization_portal_domains_domain"
+
+// OrganizationPortalDomain is the custom domain an organization serves its
+// public quote and lead portal from.
+type OrganizationPortalDomain struct {
+	OrganizationID    uuid.UUID
+	Domain            string
+	VerificationToken string
+	VerifiedAt        *time.Time
+	LastVerifiedAt    *time.Time
+	LastCheckedAt     *time.Time
+	LastError         *string
+	ClaimedAt         time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// PortalDomainCheck is the outcome of a DNS verification of a portal domain.
+// Revoke withdraws an earlier verification when the record is gone.
+type PortalDomainCheck struct {
+	Verified bool
+	Revoke   bool
+	Error    *string
+}
+
+const portalDomainColumns = `organization_id, domain, verification_token, verified_at, last_verified_at, last_checked_at, last_error, claimed_at, created_at, updated_at`
+
+func scanPortalDomain(row pgx.Row) (OrganizationPortalDomain, error) {
+	var d OrganizationPortalDomain
+	err := row.Scan(&d.OrganizationID, &d.Domain, &d.VerificationToken, &d.VerifiedAt, &d.LastVerifiedAt, &d.LastCheckedAt, &d.LastError, &d.ClaimedAt, &d.CreatedAt, &d.UpdatedAt)
+	return d, err
+}
+
+func collectPortalDomains(rows pgx.Rows) ([]OrganizationPortalDomain, error) {
+	defer rows.Close()
+	items := make([]OrganizationPortalDomain, 0)
+	for rows.Next() {
+		d, err := scanPortalDomain(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan portal domain: %w", err)
+		}
+		items = append(items, d)
+	}
+	return items, rows.Err()
+}
+
+// GetOrganizationPortalDomain returns the portal domain of an organization, or
+// ErrNotFound when none was registered.
+func (r *Repository) GetOrganizationPortalDomain(ctx context.Context, organizationID uuid.UUID) (OrganizationPortalDomain, error) {
+	domain, err := scanPortalDomain(r.pool.QueryRow(ctx, `
+		SELECT `+portalDomainColumns+` FROM RAC_organization_portal_domains WHERE organization_id = $1`,
+		organizationID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationPortalDomain{}, ErrNotFound
+	}
+	if err != nil {
+		return OrganizationPortalDomain{}, fmt.Errorf("get organization portal domain: %w", err)
+	}
+	return domain, nil
+}
+
+// GetVerifiedPortalDomainByHost returns the verified portal domain matching a
+// request host, or ErrNotFound.
+func (r *Repository) GetVerifiedPortalDomainByHost(ctx context.Context, host string) (OrganizationPortalDomain, error) {
+	domain, err := scanPortalDomain(r.pool.QueryRow(ctx, `
+		SELECT `+portalDomainColumns+` FROM RAC_organization_portal_domains
+		WHERE lower(domain) = lower($1) AND verified_at IS NOT NULL`,
+		host,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationPortalDomain{}, ErrNotFound
+	}
+	if err != nil {
+		return OrganizationPortalDomain{}, fmt.Errorf("get portal domain by host: %w", err)
+	}
+	return domain, nil
+}
+
+// UpsertOrganizationPortalDomain registers the portal domain of an
+// organization. Registering another domain replaces the previous one and
+// starts a new claim with the given token; registering the same domain again
+// keeps its verification. Only verified domains are unique, so a claim does
+// not lock other organizations out of a domain.
+func (r *Repository) UpsertOrganizationPortalDomain(ctx context.Context, organizationID uuid.UUID, domain, verificationToken string) (OrganizationPortalDomain, error) {
+	result, err := scanPortalDomain(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_organization_portal_domains (organization_id, domain, verification_token)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			domain = EXCLUDED.domain,
+			verification_token = CASE WHEN RAC_organization_portal_domains.domain = EXCLUDED.domain
+				THEN RAC_organization_portal_domains.verification_token ELSE EXCLUDED.verification_token END,
+			verified_at = CASE WHEN RAC_organization_portal_domains.domain = EXCLUDED.domain
+				THEN RAC_organization_portal_domains.verified_at END,
+			last_verified_at = CASE WHEN RAC_organization_portal_domains.domain = EXCLUDED.domain
+				THEN RAC_organization_portal_domains.last_verified_at END,
+			last_checked_at = CASE WHEN RAC_organization_portal_domains.domain = EXCLUDED.domain
+				THEN RAC_organization_portal_domains.last_checked_at END,
+			last_error = CASE WHEN RAC_organization_portal_domains.domain = EXCLUDED.domain
+				THEN RAC_organization_portal_domains.last_error END,
+			claimed_at = CASE WHEN RAC_organization_portal_domains.domain = EXCLUDED.domain
+				THEN RAC_organization_portal_domains.claimed_at ELSE now() END,
+			updated_at = now()
+		RETURNING `+portalDomainColumns,
+		organizationID, domain, verificationToken,
+	))
+	if isDuplicatePortalDomain(err) {
+		return OrganizationPortalDomain{}, apperr.Conflict("domain is already used by another organization")
+	}
+	if err != nil {
+		return OrganizationPortalDomain{}, fmt.Errorf("upsert organization portal domain: %w", err)
+	}
+	return result, nil
+}
+
+// RecordPortalDomainCheck stores the outcome of a DNS verification. A failed
+// check keeps an earlier verification unless it is revoked, so a DNS hiccup
+// does not break links.
+func (r *Repository) RecordPortalDomainCheck(ctx context.Context, organizationID uuid.UUID, check PortalDomainCheck) (OrganizationPortalDomain, error) {
+	domain, err := scanPortalDomain(r.pool.QueryRow(ctx, `
+		UPDATE RAC_organization_portal_domains SET
+			verified_at = CASE WHEN $2 THEN COALESCE(verified_at, now()) WHEN $3 THEN NULL ELSE verified_at END,
+			last_verified_at = CASE WHEN $2 THEN now() ELSE last_verified_at END,
+			last_checked_at = now(),
+			last_error = $4,
+			updated_at = now()
+		WHERE organization_id = $1
+		RETURNING `+portalDomainColumns,
+		organizationID, check.Verified, check.Revoke, check.Error,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationPortalDomain{}, ErrNotFound
+	}
+	if isDuplicatePortalDomain(err) {
+		return OrganizationPortalDomain{}, apperr.Conflict("domain is already verified by another organization")
+	}
+	if err != nil {
+		return OrganizationPortalDomain{}, fmt.Errorf("record portal domain check: %w", err)
+	}
+	return domain, nil
+}
+
+// ListPortalDomainsDueForCheck returns verified portal domains that were last
+// checked before the given time, the longest unchecked first.
+func (r *Repository) ListPortalDomainsDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]OrganizationPortalDomain, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+portalDomainColumns+` FROM RAC_organization_portal_domains
+		WHERE verified_at IS NOT NULL AND (last_checked_at IS NULL OR last_checked_at < $1)
+		ORDER BY last_checked_at NULLS FIRST
+		LIMIT $2`,
+		checkedBefore, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list portal domains due for check: %w", err)
+	}
+	return collectPortalDomains(rows)
+}
+
+// DeleteUnverifiedPortalDomainClaims removes claims that were not verified
+// since before the given time and returns how many were removed.
+func (r *Repository) DeleteUnverifiedPortalDomainClaims(ctx context.Context, claimedBefore time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_organization_portal_domains
+		WHERE verified_at IS NULL AND claimed_at < $1`,
+		claimedBefore,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete unverified portal domain claims: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteOrganizationPortalDomain removes the portal domain of an organization.
+func (r *Repository) DeleteOrganizationPortalDomain(ctx context.Context, organizationID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM RAC_organization_portal_domains WHERE organization_id = $1`, organizationID)
+	if err != nil {
+		return fmt.Errorf("delete organization portal domain: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func isDuplicatePortalDomain(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "23505" && pgErr.ConstraintName == portalDomainUniqueIndex
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	identitydb "portal_final_backend/internal/identity/db"
+	leadsrepo "portal_final_backend/internal/leads/repository"
 	"portal_final_backend/platform/apperr"
 )
 
@@ -23,14 +24,21 @@ const whatsappAccountJIDUniqueIndex = "idx_rac_organization_settings_whatsapp_ac
 type DBTX = identitydb.DBTX
 
 type Repository struct {
-	pool    *pgxpool.Pool
-	queries *identitydb.Queries
+	pool        *pgxpool.Pool
+	queries     *identitydb.Queries
+	leadContact *leadsrepo.ContactCipher
 }
 
 func New(pool *pgxpool.Pool) *Repository {
 	return &Repository{pool: pool, queries: identitydb.New(pool)}
 }
 
+// SetLeadContactCipher lets WhatsApp conversations find leads whose phone
+// numbers are encrypted.
+func (r *Repository) SetLeadContactCipher(cipher *leadsrepo.ContactCipher) {
+	r.leadContact = cipher
+}
+
 func (r *Repository) queriesFor(q DBTX) *identitydb.Queries {
 	if q != nil {
 		return identitydb.New(q)
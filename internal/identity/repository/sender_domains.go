@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// OrganizationSenderDomain is the outcome of the last DNS check of the domain
+// an organization's SMTP configuration sends from.
+type OrganizationSenderDomain struct {
+	OrganizationID uuid.UUID
+	Domain         string
+	DKIMSelector   *string
+	SPFStatus      string
+	DKIMStatus     string
+	DMARCStatus    string
+	Warnings       []string
+	// VerifiedAt is set while all checks pass.
+	VerifiedAt *time.Time
+	// OverrideAt is set when an admin enabled SMTP although the checks failed.
+	OverrideAt    *time.Time
+	LastCheckedAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// SenderDomainCheck is the result of checking a sender domain.
+type SenderDomainCheck struct {
+	Domain       string
+	DKIMSelector *string
+	SPFStatus    string
+	DKIMStatus   string
+	DMARCStatus  string
+	Warnings     []string
+	Passed       bool
+}
+
+const senderDomainColumns = `organization_id, domain, dkim_selector, spf_status, dkim_status, dmarc_status, warnings,
+	verified_at, override_at, last_checked_at, created_at, updated_at`
+
+func scanSenderDomain(row pgx.Row) (OrganizationSenderDomain, error) {
+	var d OrganizationSenderDomain
+	err := row.Scan(&d.OrganizationID, &d.Domain, &d.DKIMSelector, &d.SPFStatus, &d.DKIMStatus, &d.DMARCStatus, &d.Warnings,
+		&d.VerifiedAt, &d.OverrideAt, &d.LastCheckedAt, &d.CreatedAt, &d.UpdatedAt)
+	return d, err
+}
+
+// GetOrganizationSenderDomain returns the last sender domain check of an
+// organization, or ErrNotFound when its sender domain was never checked.
+func (r *Repository) GetOrganizationSenderDomain(ctx context.Context, organizationID uuid.UUID) (OrganizationSenderDomain, error) {
+	domain, err := scanSenderDomain(r.pool.QueryRow(ctx, `
+		SELECT `+senderDomainColumns+` FROM RAC_organization_sender_domains WHERE organization_id = $1`,
+		organizationID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationSenderDomain{}, ErrNotFound
+	}
+	if err != nil {
+		return OrganizationSenderDomain{}, fmt.Errorf("get organization sender domain: %w", err)
+	}
+	return domain, nil
+}
+
+// RecordSenderDomainCheck stores a sender domain check. Unlike portal domains a
+// failed check clears the verification, so admins see a broken record as soon
+// as it is detected. An override only survives checks of the same domain.
+func (r *Repository) RecordSenderDomainCheck(ctx context.Context, organizationID uuid.UUID, check SenderDomainCheck) (OrganizationSenderDomain, error) {
+	warnings := check.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+	domain, err := scanSenderDomain(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_organization_sender_domains
+			(organization_id, domain, dkim_selector, spf_status, dkim_status, dmarc_status, warnings, verified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, CASE WHEN $8 THEN now() END)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			domain = EXCLUDED.domain,
+			dkim_selector = EXCLUDED.dkim_selector,
+			spf_status = EXCLUDED.spf_status,
+			dkim_status = EXCLUDED.dkim_status,
+			dmarc_status = EXCLUDED.dmarc_status,
+			warnings = EXCLUDED.warnings,
+			verified_at = CASE WHEN $8 AND RAC_organization_sender_domains.domain = EXCLUDED.domain
+				THEN COALESCE(RAC_organization_sender_domains.verified_at, now()) ELSE EXCLUDED.verified_at END,
+			override_at = CASE WHEN RAC_organization_sender_domains.domain = EXCLUDED.domain
+				THEN RAC_organization_sender_domains.override_at END,
+			last_checked_at = now(),
+			updated_at = now()
+		RETURNING `+senderDomainColumns,
+		organizationID, check.Domain, check.DKIMSelector, check.SPFStatus, check.DKIMStatus, check.DMARCStatus, warnings, check.Passed,
+	))
+	if err != nil {
+		return OrganizationSenderDomain{}, fmt.Errorf("record sender domain check: %w", err)
+	}
+	return domain, nil
+}
+
+// OverrideSenderDomain records that an admin enabled SMTP for the checked
+// sender domain although its checks failed.
+func (r *Repository) OverrideSenderDomain(ctx context.Context, organizationID uuid.UUID) (OrganizationSenderDomain, error) {
+	domain, err := scanSenderDomain(r.pool.QueryRow(ctx, `
+		UPDATE RAC_organization_sender_domains SET override_at = now(), updated_at = now()
+		WHERE organization_id = $1
+		RETURNING `+senderDomainColumns,
+		organizationID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationSenderDomain{}, ErrNotFound
+	}
+	if err != nil {
+		return OrganizationSenderDomain{}, fmt.Errorf("override sender domain: %w", err)
+	}
+	return domain, nil
+}
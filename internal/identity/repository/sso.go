@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const ssoDomainVerifiedIndex = "idx_organization_sso_domains_verified"
+
+// OrganizationSSOConfig is the OpenID Connect single sign-on setup of an
+// organization. ClientSecret is stored encrypted. Domains holds the
+// verification state of AllowedDomains.
+type OrganizationSSOConfig struct {
+	OrganizationID  uuid.UUID
+	Enabled         bool
+	IssuerURL       string
+	ClientID        string
+	ClientSecret    string
+	AllowedDomains  []string
+	RoleClaim       *string
+	RoleMappings    map[string]string
+	DefaultRole     string
+	JITProvisioning bool
+	EnforceSSO      bool
+	UpdatedAt       time.Time
+	Domains         []OrganizationSSODomain
+}
+
+// OrganizationSSODomain is an email domain of SSO sign-in and its DNS
+// verification.
+type OrganizationSSODomain struct {
+	Domain            string
+	VerificationToken string
+	VerifiedAt        *time.Time
+	LastCheckedAt     *time.Time
+	LastError         *string
+}
+
+// AllowsDomain reports whether SSO sign-in applies to email addresses of the
+// domain: it must be allowed and verified.
+func (c OrganizationSSOConfig) AllowsDomain(domain string) bool {
+	for _, d := range c.Domains {
+		if d.Domain == domain && d.VerifiedAt != nil {
+			return true
+		}
+	}
+	return false
+}
+
+const ssoConfigColumns = `organization_id, enabled, issuer_url, client_id, client_secret_encrypted, allowed_domains,
+	role_claim, role_mappings, default_role, jit_provisioning, enforce_sso, updated_at`
+
+// GetOrganizationSSOConfig returns the SSO config of an organization, or
+// ErrNotFound when none was configured.
+func (r *Repository) GetOrganizationSSOConfig(ctx context.Context, organizationID uuid.UUID) (OrganizationSSOConfig, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+ssoConfigColumns+` FROM RAC_organization_sso_configs WHERE organization_id = $1`, organizationID)
+	config, err := scanSSOConfig(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationSSOConfig{}, ErrNotFound
+	}
+	if err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("get organization sso config: %w", err)
+	}
+	return r.withSSODomains(ctx, config)
+}
+
+// GetOrganizationSSOConfigByDomain returns the enabled SSO config of the
+// organization that verified the given email domain, or ErrNotFound.
+func (r *Repository) GetOrganizationSSOConfigByDomain(ctx context.Context, domain string) (OrganizationSSOConfig, error) {
+	row := r.pool.QueryRow(ctx, `
+		SELECT `+ssoConfigColumns+`
+		FROM RAC_organization_sso_configs
+		WHERE enabled AND organization_id = (
+			SELECT organization_id FROM RAC_organization_sso_domains
+			WHERE lower(domain) = lower($1) AND verified_at IS NOT NULL
+		)`, domain)
+	config, err := scanSSOConfig(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationSSOConfig{}, ErrNotFound
+	}
+	if err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("get organization sso config by domain: %w", err)
+	}
+	return r.withSSODomains(ctx, config)
+}
+
+// UpsertOrganizationSSOConfig replaces the SSO config of an organization.
+// Newly allowed domains start unverified with the token of the same index in
+// verificationTokens; domains that stay keep their verification.
+func (r *Repository) UpsertOrganizationSSOConfig(ctx context.Context, config OrganizationSSOConfig, verificationTokens []string) (OrganizationSSOConfig, error) {
+	mappings, err := json.Marshal(config.RoleMappings)
+	if err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("marshal sso role mappings: %w", err)
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("begin sso config update: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	row := tx.QueryRow(ctx, `
+		INSERT INTO RAC_organization_sso_configs (
+			organization_id, enabled, issuer_url, client_id, client_secret_encrypted, allowed_domains,
+			role_claim, role_mappings, default_role, jit_provisioning, enforce_sso, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now())
+		ON CONFLICT (organization_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			issuer_url = EXCLUDED.issuer_url,
+			client_id = EXCLUDED.client_id,
+			client_secret_encrypted = EXCLUDED.client_secret_encrypted,
+			allowed_domains = EXCLUDED.allowed_domains,
+			role_claim = EXCLUDED.role_claim,
+			role_mappings = EXCLUDED.role_mappings,
+			default_role = EXCLUDED.default_role,
+			jit_provisioning = EXCLUDED.jit_provisioning,
+			enforce_sso = EXCLUDED.enforce_sso,
+			updated_at = now()
+		RETURNING `+ssoConfigColumns,
+		config.OrganizationID, config.Enabled, config.IssuerURL, config.ClientID, config.ClientSecret, config.AllowedDomains,
+		config.RoleClaim, mappings, config.DefaultRole, config.JITProvisioning, config.EnforceSSO,
+	)
+	saved, err := scanSSOConfig(row)
+	if err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("upsert organization sso config: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM RAC_organization_sso_domains
+		WHERE organization_id = $1 AND NOT (domain = ANY($2::text[]))`,
+		config.OrganizationID, config.AllowedDomains,
+	); err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("remove sso domains: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_organization_sso_domains (organization_id, domain, verification_token)
+		SELECT $1, d.domain, d.token
+		FROM unnest($2::text[], $3::text[]) AS d(domain, token)
+		ON CONFLICT (organization_id, domain) DO NOTHING`,
+		config.OrganizationID, config.AllowedDomains, verificationTokens,
+	); err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("add sso domains: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("commit sso config update: %w", err)
+	}
+	return r.withSSODomains(ctx, saved)
+}
+
+// RecordSSODomainCheck stores the outcome of the DNS verification of an SSO
+// domain. A failed check keeps an earlier verification so a DNS hiccup does
+// not lock members out. Verifying a domain another organization verified
+// first is a conflict.
+func (r *Repository) RecordSSODomainCheck(ctx context.Context, organizationID uuid.UUID, domain string, verified bool, checkErr *string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_organization_sso_domains SET
+			verified_at = CASE WHEN $3 THEN COALESCE(verified_at, now()) ELSE verified_at END,
+			last_checked_at = now(),
+			last_error = $4,
+			updated_at = now()
+		WHERE organization_id = $1 AND domain = $2`,
+		organizationID, domain, verified, checkErr,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == ssoDomainVerifiedIndex {
+		return apperr.Conflict("domain is already verified by another organization")
+	}
+	if err != nil {
+		return fmt.Errorf("record sso domain check: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) withSSODomains(ctx context.Context, config OrganizationSSOConfig) (OrganizationSSOConfig, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT domain, verification_token, verified_at, last_checked_at, last_error
+		FROM RAC_organization_sso_domains
+		WHERE organization_id = $1
+		ORDER BY domain`, config.OrganizationID)
+	if err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("list sso domains: %w", err)
+	}
+	domains, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (OrganizationSSODomain, error) {
+		var d OrganizationSSODomain
+		err := row.Scan(&d.Domain, &d.VerificationToken, &d.VerifiedAt, &d.LastCheckedAt, &d.LastError)
+		return d, err
+	})
+	if err != nil {
+		return OrganizationSSOConfig{}, fmt.Errorf("list sso domains: %w", err)
+	}
+	config.Domains = domains
+	return config, nil
+}
+
+// DeleteOrganizationSSOConfig removes the SSO config of an organization.
+func (r *Repository) DeleteOrganizationSSOConfig(ctx context.Context, organizationID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM RAC_organization_sso_configs WHERE organization_id = $1`, organizationID)
+	if err != nil {
+		return fmt.Errorf("delete organization sso config: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanSSOConfig(row pgx.Row) (OrganizationSSOConfig, error) {
+	var c OrganizationSSOConfig
+	var mappings []byte
+	if err := row.Scan(&c.OrganizationID, &c.Enabled, &c.IssuerURL, &c.ClientID, &c.ClientSecret, &c.AllowedDomains,
+		&c.RoleClaim, &mappings, &c.DefaultRole, &c.JITProvisioning, &c.EnforceSSO, &c.UpdatedAt); err != nil {
+		return OrganizationSSOConfig{}, err
+	}
+	c.RoleMappings = map[string]string{}
+	if len(mappings) > 0 {
+		if err := json.Unmarshal(mappings, &c.RoleMappings); err != nil {
+			return OrganizationSSOConfig{}, fmt.Errorf("decode sso role mappings: %w", err)
+		}
+	}
+	return c, nil
+}
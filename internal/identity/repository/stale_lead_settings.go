@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Default stale lead thresholds, in days.
+const (
+	DefaultStaleNoActivityDays = 7
+	DefaultStaleNurturingDays  = 7
+	DefaultStaleNoQuoteDays    = 14
+	DefaultStaleDraftQuoteDays = 30
+	DefaultStaleRescheduleDays = 2
+)
+
+// OrganizationStaleLeadSettings sets after how many days a lead service is
+// stale for each stale reason, and what the stale lead sweep does about it.
+type OrganizationStaleLeadSettings struct {
+	OrganizationID       uuid.UUID
+	NoActivityDays       int
+	NurturingDays        int
+	NoQuoteDays          int
+	DraftQuoteDays       int
+	RescheduleDays       int
+	CreateOwnerTasks     bool
+	ConsumerNudgeEnabled bool
+	UpdatedAt            *time.Time
+}
+
+// GetOrganizationStaleLeadSettings returns the stale lead settings of an
+// organization, or the defaults when none were configured.
+func (r *Repository) GetOrganizationStaleLeadSettings(ctx context.Context, organizationID uuid.UUID) (OrganizationStaleLeadSettings, error) {
+	settings := OrganizationStaleLeadSettings{
+		OrganizationID:   organizationID,
+		NoActivityDays:   DefaultStaleNoActivityDays,
+		NurturingDays:    DefaultStaleNurturingDays,
+		NoQuoteDays:      DefaultStaleNoQuoteDays,
+		DraftQuoteDays:   DefaultStaleDraftQuoteDays,
+		RescheduleDays:   DefaultStaleRescheduleDays,
+		CreateOwnerTasks: true,
+	}
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT no_activity_days, nurturing_days, no_quote_days, draft_quote_days, reschedule_days,
+			create_owner_tasks, consumer_nudge_enabled, updated_at
+		FROM RAC_organization_stale_lead_settings
+		WHERE organization_id = $1`, organizationID,
+	).Scan(&settings.NoActivityDays, &settings.NurturingDays, &settings.NoQuoteDays, &settings.DraftQuoteDays,
+		&settings.RescheduleDays, &settings.CreateOwnerTasks, &settings.ConsumerNudgeEnabled, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return settings, nil
+	}
+	if err != nil {
+		return OrganizationStaleLeadSettings{}, fmt.Errorf("get organization stale lead settings: %w", err)
+	}
+	settings.UpdatedAt = &updatedAt
+	return settings, nil
+}
+
+// UpsertOrganizationStaleLeadSettings replaces the stale lead settings of an
+// organization.
+func (r *Repository) UpsertOrganizationStaleLeadSettings(ctx context.Context, settings OrganizationStaleLeadSettings) (OrganizationStaleLeadSettings, error) {
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_organization_stale_lead_settings (
+			organization_id, no_activity_days, nurturing_days, no_quote_days, draft_quote_days, reschedule_days,
+			create_owner_tasks, consumer_nudge_enabled, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now())
+		ON CONFLICT (organization_id) DO UPDATE SET
+			no_activity_days = EXCLUDED.no_activity_days,
+			nurturing_days = EXCLUDED.nurturing_days,
+			no_quote_days = EXCLUDED.no_quote_days,
+			draft_quote_days = EXCLUDED.draft_quote_days,
+			reschedule_days = EXCLUDED.reschedule_days,
+			create_owner_tasks = EXCLUDED.create_owner_tasks,
+			consumer_nudge_enabled = EXCLUDED.consumer_nudge_enabled,
+			updated_at = now()
+		RETURNING updated_at`,
+		settings.OrganizationID, settings.NoActivityDays, settings.NurturingDays, settings.NoQuoteDays,
+		settings.DraftQuoteDays, settings.RescheduleDays, settings.CreateOwnerTasks, settings.ConsumerNudgeEnabled,
+	).Scan(&updatedAt)
+	if err != nil {
+		return OrganizationStaleLeadSettings{}, fmt.Errorf("upsert organization stale lead settings: %w", err)
+	}
+	settings.UpdatedAt = &updatedAt
+	return settings, nil
+}
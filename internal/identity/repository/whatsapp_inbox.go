@@ -1116,13 +1116,15 @@ func (r *Repository) resolveConversationLead(ctx context.Context, tx pgx.Tx, org
 		return &lead.ID, strings.TrimSpace(lead.FirstName + " " + lead.LastName), nil
 	}
 
+	// Leads with encrypted contact details are matched on their blind index.
 	const query = `
 		SELECT id, consumer_first_name, consumer_last_name
 		FROM RAC_leads
-		WHERE organization_id = $1 AND consumer_phone = $2
+		WHERE organization_id = $1
+			AND (($3 <> '' AND consumer_phone_bidx = $3) OR (($3 = '' OR contact_key_id IS NULL) AND consumer_phone = $2))
 		ORDER BY updated_at DESC, created_at DESC
 		LIMIT 1`
-	row := tx.QueryRow(ctx, query, organizationID, phoneNumber)
+	row := tx.QueryRow(ctx, query, organizationID, phoneNumber, r.leadContact.PhoneBlindIndex(phoneNumber))
 	lead, err := scanLeadLookupRow(row)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, "", nil
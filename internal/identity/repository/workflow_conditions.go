@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	identitydb "portal_final_backend/internal/identity/db"
+)
+
+// setWorkflowStepCondition stores the send condition of a step. A blank
+// condition is stored as NULL so the step always sends.
+func setWorkflowStepCondition(ctx context.Context, db identitydb.DBTX, organizationID, stepID uuid.UUID, condition *string) error {
+	if _, err := db.Exec(ctx, `
+		UPDATE RAC_workflow_steps SET send_condition = $3
+		WHERE id = $1 AND organization_id = $2`, stepID, organizationID, normalizedSendCondition(condition),
+	); err != nil {
+		return fmt.Errorf("set workflow step condition: %w", err)
+	}
+	return nil
+}
+
+// attachWorkflowStepConditions loads the send conditions of the organization's
+// steps into steps.
+func (r *Repository) attachWorkflowStepConditions(ctx context.Context, organizationID uuid.UUID, steps []WorkflowStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, send_condition
+		FROM RAC_workflow_steps
+		WHERE organization_id = $1 AND send_condition IS NOT NULL`, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("list workflow step conditions: %w", err)
+	}
+	defer rows.Close()
+
+	conditions := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var stepID uuid.UUID
+		var condition string
+		if err := rows.Scan(&stepID, &condition); err != nil {
+			return fmt.Errorf("scan workflow step condition: %w", err)
+		}
+		conditions[stepID] = condition
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("list workflow step conditions: %w", err)
+	}
+
+	for i := range steps {
+		if condition, ok := conditions[steps[i].ID]; ok {
+			steps[i].SendCondition = &condition
+		}
+	}
+	return nil
+}
+
+func (r *Repository) workflowStepCondition(ctx context.Context, organizationID, stepID uuid.UUID) (*string, error) {
+	var condition *string
+	err := r.pool.QueryRow(ctx, `
+		SELECT send_condition FROM RAC_workflow_steps
+		WHERE id = $1 AND organization_id = $2`, stepID, organizationID,
+	).Scan(&condition)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get workflow step condition: %w", err)
+	}
+	return condition, nil
+}
+
+func normalizedSendCondition(condition *string) *string {
+	if condition == nil || strings.TrimSpace(*condition) == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*condition)
+	return &trimmed
+}
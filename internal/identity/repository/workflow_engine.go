@@ -42,8 +42,15 @@ type WorkflowStep struct {
 	TemplateSubject *string
 	TemplateBody    *string
 	StopOnReply     bool
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	SendCondition   *string
+	// TemplateTranslations holds language variants of the templates, keyed by
+	// ISO 639-1 code.
+	TemplateTranslations map[string]WorkflowStepTranslation
+	// TemplateVariants holds the step's A/B test variants; empty when the
+	// step is not being tested.
+	TemplateVariants []WorkflowStepVariant
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 type WorkflowUpsert struct {
@@ -70,6 +77,11 @@ type WorkflowStepUpsert struct {
 	TemplateSubject *string
 	TemplateBody    *string
 	StopOnReply     bool
+	SendCondition   *string
+	// TemplateTranslations replaces the step's language variants.
+	TemplateTranslations map[string]WorkflowStepTranslation
+	// TemplateVariants replaces the step's A/B test variants.
+	TemplateVariants []WorkflowStepVariant
 }
 
 type WorkflowAssignmentRule struct {
@@ -147,6 +159,17 @@ func (r *Repository) ListWorkflows(ctx context.Context, organizationID uuid.UUID
 			workflows[idx].Steps = append(workflows[idx].Steps, step)
 		}
 	}
+	for i := range workflows {
+		if err := r.attachWorkflowStepConditions(ctx, organizationID, workflows[i].Steps); err != nil {
+			return nil, err
+		}
+		if err := r.attachWorkflowStepTranslations(ctx, organizationID, workflows[i].Steps); err != nil {
+			return nil, err
+		}
+		if err := r.attachWorkflowStepVariants(ctx, organizationID, workflows[i].Steps); err != nil {
+			return nil, err
+		}
+	}
 
 	return workflows, nil
 }
@@ -178,6 +201,15 @@ func (r *Repository) GetWorkflow(ctx context.Context, workflowID, organizationID
 			workflow.Steps = append(workflow.Steps, step)
 		}
 	}
+	if err := r.attachWorkflowStepConditions(ctx, organizationID, workflow.Steps); err != nil {
+		return Workflow{}, err
+	}
+	if err := r.attachWorkflowStepTranslations(ctx, organizationID, workflow.Steps); err != nil {
+		return Workflow{}, err
+	}
+	if err := r.attachWorkflowStepVariants(ctx, organizationID, workflow.Steps); err != nil {
+		return Workflow{}, err
+	}
 
 	return workflow, nil
 }
@@ -195,7 +227,7 @@ func (r *Repository) CreateWorkflow(ctx context.Context, organizationID uuid.UUI
 		return Workflow{}, err
 	}
 
-	steps, err := upsertWorkflowStepsTx(ctx, queries, organizationID, wf.ID, workflow.Steps)
+	steps, err := upsertWorkflowStepsTx(ctx, tx, queries, organizationID, wf.ID, workflow.Steps)
 	if err != nil {
 		return Workflow{}, err
 	}
@@ -255,7 +287,7 @@ func (r *Repository) ReplaceWorkflows(ctx context.Context, organizationID uuid.U
 			OrganizationID: toPgUUID(organizationID),
 		})
 
-		steps, err := upsertWorkflowStepsTx(ctx, queries, organizationID, wf.ID, workflow.Steps)
+		steps, err := upsertWorkflowStepsTx(ctx, tx, queries, organizationID, wf.ID, workflow.Steps)
 		if err != nil {
 			return nil, err
 		}
@@ -302,7 +334,7 @@ func (r *Repository) EnsureDefaultWorkflowSeed(ctx context.Context, organization
 	if err != nil {
 		return err
 	}
-	if _, err := upsertWorkflowStepsTx(ctx, queries, organizationID, wf.ID, workflow.Steps); err != nil {
+	if _, err := upsertWorkflowStepsTx(ctx, tx, queries, organizationID, wf.ID, workflow.Steps); err != nil {
 		return err
 	}
 
@@ -358,7 +390,7 @@ func upsertWorkflowTx(ctx context.Context, queries *identitydb.Queries, organiza
 	}, nil
 }
 
-func upsertWorkflowStepsTx(ctx context.Context, queries *identitydb.Queries, organizationID uuid.UUID, workflowID uuid.UUID, steps []WorkflowStepUpsert) ([]WorkflowStep, error) {
+func upsertWorkflowStepsTx(ctx context.Context, tx pgx.Tx, queries *identitydb.Queries, organizationID uuid.UUID, workflowID uuid.UUID, steps []WorkflowStepUpsert) ([]WorkflowStep, error) {
 	keptStepIDs := make([]uuid.UUID, 0, len(steps))
 	result := make([]WorkflowStep, 0, len(steps))
 	now := time.Now()
@@ -392,24 +424,36 @@ func upsertWorkflowStepsTx(ctx context.Context, queries *identitydb.Queries, org
 		if err != nil {
 			return nil, err
 		}
+		if err := setWorkflowStepCondition(ctx, tx, organizationID, stepID, step.SendCondition); err != nil {
+			return nil, err
+		}
+		if err := setWorkflowStepTranslations(ctx, tx, organizationID, stepID, step.TemplateTranslations); err != nil {
+			return nil, err
+		}
+		if err := setWorkflowStepVariants(ctx, tx, organizationID, stepID, step.TemplateVariants); err != nil {
+			return nil, err
+		}
 		keptStepIDs = append(keptStepIDs, stepID)
 		result = append(result, WorkflowStep{
-			ID:              stepID,
-			OrganizationID:  organizationID,
-			WorkflowID:      workflowID,
-			Trigger:         step.Trigger,
-			Channel:         step.Channel,
-			Audience:        step.Audience,
-			Action:          step.Action,
-			StepOrder:       step.StepOrder,
-			DelayMinutes:    step.DelayMinutes,
-			Enabled:         step.Enabled,
-			RecipientConfig: step.RecipientConfig,
-			TemplateSubject: step.TemplateSubject,
-			TemplateBody:    step.TemplateBody,
-			StopOnReply:     step.StopOnReply,
-			CreatedAt:       now,
-			UpdatedAt:       now,
+			ID:                   stepID,
+			OrganizationID:       organizationID,
+			WorkflowID:           workflowID,
+			Trigger:              step.Trigger,
+			Channel:              step.Channel,
+			Audience:             step.Audience,
+			Action:               step.Action,
+			StepOrder:            step.StepOrder,
+			DelayMinutes:         step.DelayMinutes,
+			Enabled:              step.Enabled,
+			RecipientConfig:      step.RecipientConfig,
+			TemplateSubject:      step.TemplateSubject,
+			TemplateBody:         step.TemplateBody,
+			StopOnReply:          step.StopOnReply,
+			SendCondition:        normalizedSendCondition(step.SendCondition),
+			TemplateTranslations: step.TemplateTranslations,
+			TemplateVariants:     step.TemplateVariants,
+			CreatedAt:            now,
+			UpdatedAt:            now,
 		})
 	}
 
@@ -641,24 +685,36 @@ func (r *Repository) CreateWorkflowStep(ctx context.Context, organizationID, wor
 	if err != nil {
 		return WorkflowStep{}, err
 	}
+	if err := setWorkflowStepCondition(ctx, r.pool, organizationID, stepID, step.SendCondition); err != nil {
+		return WorkflowStep{}, err
+	}
+	if err := setWorkflowStepTranslations(ctx, r.pool, organizationID, stepID, step.TemplateTranslations); err != nil {
+		return WorkflowStep{}, err
+	}
+	if err := setWorkflowStepVariants(ctx, r.pool, organizationID, stepID, step.TemplateVariants); err != nil {
+		return WorkflowStep{}, err
+	}
 	now := time.Now()
 	return WorkflowStep{
-		ID:              stepID,
-		OrganizationID:  organizationID,
-		WorkflowID:      workflowID,
-		Trigger:         step.Trigger,
-		Channel:         step.Channel,
-		Audience:        step.Audience,
-		Action:          step.Action,
-		StepOrder:       step.StepOrder,
-		DelayMinutes:    step.DelayMinutes,
-		Enabled:         step.Enabled,
-		RecipientConfig: step.RecipientConfig,
-		TemplateSubject: step.TemplateSubject,
-		TemplateBody:    step.TemplateBody,
-		StopOnReply:     step.StopOnReply,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:                   stepID,
+		OrganizationID:       organizationID,
+		WorkflowID:           workflowID,
+		Trigger:              step.Trigger,
+		Channel:              step.Channel,
+		Audience:             step.Audience,
+		Action:               step.Action,
+		StepOrder:            step.StepOrder,
+		DelayMinutes:         step.DelayMinutes,
+		Enabled:              step.Enabled,
+		RecipientConfig:      step.RecipientConfig,
+		TemplateSubject:      step.TemplateSubject,
+		TemplateBody:         step.TemplateBody,
+		StopOnReply:          step.StopOnReply,
+		SendCondition:        normalizedSendCondition(step.SendCondition),
+		TemplateTranslations: step.TemplateTranslations,
+		TemplateVariants:     step.TemplateVariants,
+		CreatedAt:            now,
+		UpdatedAt:            now,
 	}, nil
 }
 
@@ -686,15 +742,16 @@ func (r *Repository) UpdateWorkflowStep(ctx context.Context, organizationID, wor
 	if err != nil {
 		return WorkflowStep{}, err
 	}
-	row, err := r.queries.GetWorkflowStep(ctx, identitydb.GetWorkflowStepParams{
-		ID:             toPgUUID(stepID),
-		OrganizationID: toPgUUID(organizationID),
-		WorkflowID:     toPgUUID(workflowID),
-	})
-	if err != nil {
+	if err := setWorkflowStepCondition(ctx, r.pool, organizationID, stepID, step.SendCondition); err != nil {
 		return WorkflowStep{}, err
 	}
-	return workflowStepFromModel(row)
+	if err := setWorkflowStepTranslations(ctx, r.pool, organizationID, stepID, step.TemplateTranslations); err != nil {
+		return WorkflowStep{}, err
+	}
+	if err := setWorkflowStepVariants(ctx, r.pool, organizationID, stepID, step.TemplateVariants); err != nil {
+		return WorkflowStep{}, err
+	}
+	return r.GetWorkflowStep(ctx, organizationID, workflowID, stepID)
 }
 
 func (r *Repository) DeleteWorkflowStep(ctx context.Context, organizationID, workflowID, stepID uuid.UUID) error {
@@ -714,7 +771,20 @@ func (r *Repository) GetWorkflowStep(ctx context.Context, organizationID, workfl
 	if err != nil {
 		return WorkflowStep{}, err
 	}
-	return workflowStepFromModel(row)
+	step, err := workflowStepFromModel(row)
+	if err != nil {
+		return WorkflowStep{}, err
+	}
+	if step.SendCondition, err = r.workflowStepCondition(ctx, organizationID, stepID); err != nil {
+		return WorkflowStep{}, err
+	}
+	if step.TemplateTranslations, err = r.workflowStepTranslations(ctx, organizationID, stepID); err != nil {
+		return WorkflowStep{}, err
+	}
+	if step.TemplateVariants, err = r.workflowStepVariants(ctx, organizationID, stepID); err != nil {
+		return WorkflowStep{}, err
+	}
+	return step, nil
 }
 
 func toPgUUIDSlice(values []uuid.UUID) []pgtype.UUID {
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	identitydb "portal_final_backend/internal/identity/db"
+)
+
+// WorkflowStepTranslation is a language variant of a step's templates. Nil
+// fields fall back to the step's base template.
+type WorkflowStepTranslation struct {
+	TemplateSubject *string `json:"templateSubject,omitempty"`
+	TemplateBody    *string `json:"templateBody,omitempty"`
+}
+
+// setWorkflowStepTranslations replaces the template translations of a step,
+// keyed by language code.
+func setWorkflowStepTranslations(ctx context.Context, db identitydb.DBTX, organizationID, stepID uuid.UUID, translations map[string]WorkflowStepTranslation) error {
+	if translations == nil {
+		translations = map[string]WorkflowStepTranslation{}
+	}
+	payload, err := json.Marshal(translations)
+	if err != nil {
+		return fmt.Errorf("marshal workflow step translations: %w", err)
+	}
+	if _, err := db.Exec(ctx, `
+		UPDATE RAC_workflow_steps SET template_translations = $3
+		WHERE id = $1 AND organization_id = $2`, stepID, organizationID, payload,
+	); err != nil {
+		return fmt.Errorf("set workflow step translations: %w", err)
+	}
+	return nil
+}
+
+// attachWorkflowStepTranslations loads the template translations of the
+// organization's steps into steps.
+func (r *Repository) attachWorkflowStepTranslations(ctx context.Context, organizationID uuid.UUID, steps []WorkflowStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, template_translations
+		FROM RAC_workflow_steps
+		WHERE organization_id = $1 AND template_translations <> '{}'::jsonb`, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("list workflow step translations: %w", err)
+	}
+	defer rows.Close()
+
+	translations := make(map[uuid.UUID]map[string]WorkflowStepTranslation)
+	for rows.Next() {
+		var stepID uuid.UUID
+		var payload []byte
+		if err := rows.Scan(&stepID, &payload); err != nil {
+			return fmt.Errorf("scan workflow step translations: %w", err)
+		}
+		decoded, err := decodeWorkflowStepTranslations(payload)
+		if err != nil {
+			return err
+		}
+		translations[stepID] = decoded
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("list workflow step translations: %w", err)
+	}
+
+	for i := range steps {
+		if decoded, ok := translations[steps[i].ID]; ok {
+			steps[i].TemplateTranslations = decoded
+		}
+	}
+	return nil
+}
+
+func (r *Repository) workflowStepTranslations(ctx context.Context, organizationID, stepID uuid.UUID) (map[string]WorkflowStepTranslation, error) {
+	var payload []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT template_translations FROM RAC_workflow_steps
+		WHERE id = $1 AND organization_id = $2`, stepID, organizationID,
+	).Scan(&payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get workflow step translations: %w", err)
+	}
+	return decodeWorkflowStepTranslations(payload)
+}
+
+func decodeWorkflowStepTranslations(payload []byte) (map[string]WorkflowStepTranslation, error) {
+	if len(payload) == 0 {
+		return nil, nil
+	}
+	var translations map[string]WorkflowStepTranslation
+	if err := json.Unmarshal(payload, &translations); err != nil {
+		return nil, fmt.Errorf("decode workflow step translations: %w", err)
+	}
+	if len(translations) == 0 {
+		return nil, nil
+	}
+	return translations, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	identitydb "portal_final_backend/internal/identity/db"
+)
+
+// WorkflowStepVariant is an A/B test variant of a step's templates. Leads are
+// split over the variants in proportion to Weight. Nil fields fall back to
+// the step's base template.
+type WorkflowStepVariant struct {
+	Key             string  `json:"key"`
+	Weight          int     `json:"weight"`
+	TemplateSubject *string `json:"templateSubject,omitempty"`
+	TemplateBody    *string `json:"templateBody,omitempty"`
+}
+
+// setWorkflowStepVariants replaces the template variants of a step.
+func setWorkflowStepVariants(ctx context.Context, db identitydb.DBTX, organizationID, stepID uuid.UUID, variants []WorkflowStepVariant) error {
+	if variants == nil {
+		variants = []WorkflowStepVariant{}
+	}
+	payload, err := json.Marshal(variants)
+	if err != nil {
+		return fmt.Errorf("marshal workflow step variants: %w", err)
+	}
+	if _, err := db.Exec(ctx, `
+		UPDATE RAC_workflow_steps SET template_variants = $3
+		WHERE id = $1 AND organization_id = $2`, stepID, organizationID, payload,
+	); err != nil {
+		return fmt.Errorf("set workflow step variants: %w", err)
+	}
+	return nil
+}
+
+// attachWorkflowStepVariants loads the template variants of the
+// organization's steps into steps.
+func (r *Repository) attachWorkflowStepVariants(ctx context.Context, organizationID uuid.UUID, steps []WorkflowStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, template_variants
+		FROM RAC_workflow_steps
+		WHERE organization_id = $1 AND template_variants <> '[]'::jsonb`, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("list workflow step variants: %w", err)
+	}
+	defer rows.Close()
+
+	variants := make(map[uuid.UUID][]WorkflowStepVariant)
+	for rows.Next() {
+		var stepID uuid.UUID
+		var payload []byte
+		if err := rows.Scan(&stepID, &payload); err != nil {
+			return fmt.Errorf("scan workflow step variants: %w", err)
+		}
+		decoded, err := decodeWorkflowStepVariants(payload)
+		if err != nil {
+			return err
+		}
+		variants[stepID] = decoded
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("list workflow step variants: %w", err)
+	}
+
+	for i := range steps {
+		if decoded, ok := variants[steps[i].ID]; ok {
+			steps[i].TemplateVariants = decoded
+		}
+	}
+	return nil
+}
+
+func (r *Repository) workflowStepVariants(ctx context.Context, organizationID, stepID uuid.UUID) ([]WorkflowStepVariant, error) {
+	var payload []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT template_variants FROM RAC_workflow_steps
+		WHERE id = $1 AND organization_id = $2`, stepID, organizationID,
+	).Scan(&payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get workflow step variants: %w", err)
+	}
+	return decodeWorkflowStepVariants(payload)
+}
+
+func decodeWorkflowStepVariants(payload []byte) ([]WorkflowStepVariant, error) {
+	if len(payload) == 0 {
+		return nil, nil
+	}
+	var variants []WorkflowStepVariant
+	if err := json.Unmarshal(payload, &variants); err != nil {
+		return nil, fmt.Errorf("decode workflow step variants: %w", err)
+	}
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	return variants, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/ai/llmrouter"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/config"
+
+	"github.com/google/uuid"
+)
+
+// maxAIUsageReportDays bounds the range of a daily usage report.
+const maxAIUsageReportDays = 366
+
+// AIUsageSummary is the month-to-date AI token usage of an organization.
+type AIUsageSummary struct {
+	PeriodStart        time.Time
+	ChatTokens         int64
+	EmbeddingTokens    int64
+	EstimatedCostUSD   float64
+	MonthlyTokenBudget *int64
+	ByModel            []repository.AIUsageByModel
+}
+
+// BudgetExceeded reports whether agent runs are paused for the rest of the period.
+// Only chat tokens count towards the budget.
+func (u AIUsageSummary) BudgetExceeded() bool {
+	return u.MonthlyTokenBudget != nil && u.ChatTokens >= *u.MonthlyTokenBudget
+}
+
+// AIUsageReport is the daily AI usage of an organization for the UTC days in [From, To).
+type AIUsageReport struct {
+	From             time.Time
+	To               time.Time
+	Days             []repository.AIUsageDay
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+	EstimatedCostUSD float64
+}
+
+func (s *Service) GetOrganizationAISettings(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationAISettings, error) {
+	return s.repo.GetOrganizationAISettings(ctx, organizationID)
+}
+
+// UpdateOrganizationAISettings replaces the AI settings of an organization. The provider must
+// be a configured preset and the model, when set, must be served by that provider.
+func (s *Service) UpdateOrganizationAISettings(ctx context.Context, organizationID uuid.UUID, settings repository.OrganizationAISettings) (repository.OrganizationAISettings, error) {
+	settings.OrganizationID = organizationID
+	settings.Provider = normalizeOptionalLower(settings.Provider)
+	settings.Model = normalizeOptionalLower(settings.Model)
+
+	if settings.Provider != nil && !config.IsLLMProvider(*settings.Provider) {
+		return repository.OrganizationAISettings{}, apperr.Validation("unknown ai provider").WithDetails(*settings.Provider)
+	}
+	if settings.Model != nil {
+		if settings.Provider == nil {
+			return repository.OrganizationAISettings{}, apperr.Validation("a model requires a provider")
+		}
+		if !slices.Contains(config.LLMProviderModels(*settings.Provider), *settings.Model) {
+			return repository.OrganizationAISettings{}, apperr.Validation("model is not served by the provider").WithDetails(*settings.Model)
+		}
+	}
+
+	return s.repo.UpsertOrganizationAISettings(ctx, settings)
+}
+
+// RecordAIUsage appends the token usage of an LLM call to the ledger.
+func (s *Service) RecordAIUsage(ctx context.Context, usage repository.AIUsageRecord) error {
+	return s.repo.InsertAIUsage(ctx, usage)
+}
+
+// AITokensSince returns the tokens an organization used since the given time.
+func (s *Service) AITokensSince(ctx context.Context, organizationID uuid.UUID, since time.Time) (int64, error) {
+	return s.repo.SumAITokensSince(ctx, organizationID, since)
+}
+
+// GetAIUsageSummary reports the usage of the current calendar month (UTC) against the budget.
+func (s *Service) GetAIUsageSummary(ctx context.Context, organizationID uuid.UUID) (AIUsageSummary, error) {
+	now := time.Now().UTC()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	settings, err := s.repo.GetOrganizationAISettings(ctx, organizationID)
+	if err != nil {
+		return AIUsageSummary{}, err
+	}
+	byModel, err := s.repo.ListAIUsageByModel(ctx, organizationID, periodStart)
+	if err != nil {
+		return AIUsageSummary{}, err
+	}
+
+	summary := AIUsageSummary{
+		PeriodStart:        periodStart,
+		MonthlyTokenBudget: settings.MonthlyTokenBudget,
+		ByModel:            byModel,
+	}
+	for _, item := range byModel {
+		tokens := item.PromptTokens + item.CompletionTokens
+		if item.Kind == llmrouter.KindEmbedding {
+			summary.EmbeddingTokens += tokens
+		} else {
+			summary.ChatTokens += tokens
+		}
+		summary.EstimatedCostUSD += item.EstimatedCostUSD
+	}
+	return summary, nil
+}
+
+// GetAIUsageReport aggregates the AI usage ledger per UTC day for billing. Zero bounds
+// default to the current month up to and including today.
+func (s *Service) GetAIUsageReport(ctx context.Context, organizationID uuid.UUID, from, to time.Time) (AIUsageReport, error) {
+	today := truncateToUTCDay(time.Now())
+	if from.IsZero() {
+		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = today.AddDate(0, 0, 1)
+	}
+	from, to = truncateToUTCDay(from), truncateToUTCDay(to)
+	if !from.Before(to) {
+		return AIUsageReport{}, apperr.Validation("from must be before to")
+	}
+	if to.Sub(from) > maxAIUsageReportDays*24*time.Hour {
+		return AIUsageReport{}, apperr.Validation("usage report range is too long").WithDetails(map[string]int{"maxDays": maxAIUsageReportDays})
+	}
+
+	days, err := s.repo.ListAIUsageDaily(ctx, organizationID, from, to)
+	if err != nil {
+		return AIUsageReport{}, err
+	}
+
+	report := AIUsageReport{From: from, To: to, Days: days}
+	for _, day := range days {
+		report.Calls += day.Calls
+		report.PromptTokens += day.PromptTokens
+		report.CompletionTokens += day.CompletionTokens
+		report.EstimatedCostUSD += day.EstimatedCostUSD
+	}
+	return report, nil
+}
+
+func truncateToUTCDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func normalizeOptionalLower(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.ToLower(strings.TrimSpace(*value))
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
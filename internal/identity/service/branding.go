@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/imap/sanitize"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+var brandingColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+func (s *Service) GetOrganizationBranding(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationBranding, error) {
+	return s.repo.GetOrganizationBranding(ctx, organizationID)
+}
+
+// UpdateOrganizationBranding replaces the branding of an organization. Colors
+// must be #RRGGBB hex values; the email header and footer are sanitized to
+// the same HTML subset as inbound email bodies.
+func (s *Service) UpdateOrganizationBranding(ctx context.Context, organizationID uuid.UUID, branding repository.OrganizationBranding) (repository.OrganizationBranding, error) {
+	normalized, err := normalizeOrganizationBranding(branding)
+	if err != nil {
+		return repository.OrganizationBranding{}, err
+	}
+	normalized.OrganizationID = organizationID
+	return s.repo.UpsertOrganizationBranding(ctx, normalized)
+}
+
+// ResetOrganizationBranding restores the platform default branding.
+func (s *Service) ResetOrganizationBranding(ctx context.Context, organizationID uuid.UUID) error {
+	return s.repo.DeleteOrganizationBranding(ctx, organizationID)
+}
+
+func normalizeOrganizationBranding(branding repository.OrganizationBranding) (repository.OrganizationBranding, error) {
+	var err error
+	if branding.PrimaryColor, err = normalizeBrandingColor(branding.PrimaryColor); err != nil {
+		return repository.OrganizationBranding{}, err
+	}
+	if branding.AccentColor, err = normalizeBrandingColor(branding.AccentColor); err != nil {
+		return repository.OrganizationBranding{}, err
+	}
+	branding.EmailHeaderHTML = sanitizeBrandingHTML(branding.EmailHeaderHTML)
+	branding.EmailFooterHTML = sanitizeBrandingHTML(branding.EmailFooterHTML)
+	branding.SenderDisplayName = normalizeOptional(branding.SenderDisplayName)
+	if branding.SenderDisplayName != nil && strings.ContainsAny(*branding.SenderDisplayName, "\r\n<>\"") {
+		return repository.OrganizationBranding{}, apperr.Validation("sender display name contains invalid characters")
+	}
+	return branding, nil
+}
+
+func normalizeBrandingColor(value *string) (*string, error) {
+	color := normalizeOptional(value)
+	if color == nil {
+		return nil, nil
+	}
+	normalized := strings.ToLower(*color)
+	if !brandingColorPattern.MatchString(normalized) {
+		return nil, apperr.Validation("colors must be hex values like #1c1917").WithDetails(*color)
+	}
+	return &normalized, nil
+}
+
+func sanitizeBrandingHTML(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	clean := sanitize.SanitizeHTML(*value)
+	return normalizeOptional(&clean)
+}
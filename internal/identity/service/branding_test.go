@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"portal_final_backend/internal/identity/repository"
+)
+
+func brandingString(value string) *string {
+	return &value
+}
+
+func TestNormalizeOrganizationBrandingCleansInput(t *testing.T) {
+	branding, err := normalizeOrganizationBranding(repository.OrganizationBranding{
+		PrimaryColor:      brandingString(" #0A3D62 "),
+		AccentColor:       brandingString(""),
+		EmailHeaderHTML:   brandingString(`<p onclick="steal()">Welkom</p><script>alert(1)</script>`),
+		EmailFooterHTML:   brandingString("   "),
+		SenderDisplayName: brandingString("  Dakwerken Jansen "),
+	})
+	if err != nil {
+		t.Fatalf("normalizeOrganizationBranding() error = %v", err)
+	}
+	if branding.PrimaryColor == nil || *branding.PrimaryColor != "#0a3d62" {
+		t.Fatalf("primary color = %v, want #0a3d62", branding.PrimaryColor)
+	}
+	if branding.AccentColor != nil {
+		t.Fatalf("empty accent color should be cleared, got %q", *branding.AccentColor)
+	}
+	if branding.EmailHeaderHTML == nil || *branding.EmailHeaderHTML != "<p>Welkom</p>" {
+		t.Fatalf("header html = %v, want sanitized paragraph", branding.EmailHeaderHTML)
+	}
+	if branding.EmailFooterHTML != nil {
+		t.Fatalf("blank footer should be cleared, got %q", *branding.EmailFooterHTML)
+	}
+	if branding.SenderDisplayName == nil || *branding.SenderDisplayName != "Dakwerken Jansen" {
+		t.Fatalf("sender display name = %v, want trimmed name", branding.SenderDisplayName)
+	}
+}
+
+func TestNormalizeOrganizationBrandingRejectsInvalidValues(t *testing.T) {
+	tests := map[string]repository.OrganizationBranding{
+		"named color":      {PrimaryColor: brandingString("red")},
+		"short hex":        {AccentColor: brandingString("#fff")},
+		"css injection":    {AccentColor: brandingString("#fff;}body{")},
+		"header injection": {SenderDisplayName: brandingString("Jansen\r\nBcc: x@example.com")},
+	}
+	for name, branding := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := normalizeOrganizationBranding(branding); err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+}
+
+func TestSanitizeBrandingHTMLKeepsLinks(t *testing.T) {
+	html := sanitizeBrandingHTML(brandingString(`<a href="https://example.com" style="color:red">Site</a>`))
+	if html == nil || !strings.Contains(*html, `href="https://example.com"`) || strings.Contains(*html, "style") {
+		t.Fatalf("sanitized html = %v", html)
+	}
+}
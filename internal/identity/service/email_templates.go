@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const emailTemplateNotFound = "email template not found"
+
+// EmailTemplateRenderer renders system email overrides with the variables the
+// notification engine sends them with, and delivers test sends.
+type EmailTemplateRenderer interface {
+	EmailTemplateCatalog() []EmailTemplateDefinition
+	RenderEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey, subject, bodyHTML string) (RenderedEmailTemplate, error)
+	SendEmailTemplateTest(ctx context.Context, organizationID, userID uuid.UUID, rendered RenderedEmailTemplate) error
+}
+
+// EmailTemplateDefinition describes a system email organizations can override.
+type EmailTemplateDefinition struct {
+	Key         string
+	Description string
+	Variables   []WorkflowVariable
+}
+
+// RenderedEmailTemplate is an override rendered with example variables.
+type RenderedEmailTemplate struct {
+	Subject             string
+	BodyHTML            string
+	TemplateErrors      []string
+	UnresolvedVariables []string
+}
+
+// Valid reports whether the template can be saved and sent.
+func (r RenderedEmailTemplate) Valid() bool {
+	return len(r.TemplateErrors) == 0 && len(r.UnresolvedVariables) == 0 &&
+		strings.TrimSpace(r.Subject) != "" && strings.TrimSpace(r.BodyHTML) != ""
+}
+
+// EmailTemplateSummary is a system email with the override an organization
+// sends, if any.
+type EmailTemplateSummary struct {
+	Definition    EmailTemplateDefinition
+	ActiveVersion *int
+	UpdatedAt     *time.Time
+}
+
+// EmailTemplateDetail is a system email with its active override and every
+// saved version.
+type EmailTemplateDetail struct {
+	Definition EmailTemplateDefinition
+	Active     *repository.EmailTemplateVersion
+	Versions   []repository.EmailTemplateVersion
+}
+
+func (s *Service) SetEmailTemplateRenderer(renderer EmailTemplateRenderer) {
+	s.emailTemplateRenderer = renderer
+}
+
+// ListEmailTemplates returns the overridable system emails and which of them
+// the organization overrides.
+func (s *Service) ListEmailTemplates(ctx context.Context, organizationID uuid.UUID) ([]EmailTemplateSummary, error) {
+	if s.emailTemplateRenderer == nil {
+		return nil, apperr.Internal("email templates are not configured")
+	}
+	overrides, err := s.repo.ListEmailTemplateOverrides(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]repository.EmailTemplateOverride, len(overrides))
+	for _, override := range overrides {
+		byKey[override.TemplateKey] = override
+	}
+
+	catalog := s.emailTemplateRenderer.EmailTemplateCatalog()
+	summaries := make([]EmailTemplateSummary, 0, len(catalog))
+	for _, definition := range catalog {
+		summary := EmailTemplateSummary{Definition: definition}
+		if override, ok := byKey[definition.Key]; ok {
+			version, updatedAt := override.ActiveVersion, override.UpdatedAt
+			summary.ActiveVersion = &version
+			summary.UpdatedAt = &updatedAt
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// GetEmailTemplate returns a system email with the organization's active
+// override and version history.
+func (s *Service) GetEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey string) (EmailTemplateDetail, error) {
+	definition, err := s.emailTemplateDefinition(templateKey)
+	if err != nil {
+		return EmailTemplateDetail{}, err
+	}
+	detail := EmailTemplateDetail{Definition: definition}
+
+	active, err := s.repo.GetActiveEmailTemplate(ctx, organizationID, definition.Key)
+	switch {
+	case err == nil:
+		detail.Active = &active
+	case !errors.Is(err, repository.ErrNotFound):
+		return EmailTemplateDetail{}, err
+	}
+
+	if detail.Versions, err = s.repo.ListEmailTemplateVersions(ctx, organizationID, definition.Key); err != nil {
+		return EmailTemplateDetail{}, err
+	}
+	return detail, nil
+}
+
+// GetActiveEmailTemplate returns the override an organization sends for a
+// system email, or repository.ErrNotFound when it sends the built-in one.
+func (s *Service) GetActiveEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey string) (repository.EmailTemplateVersion, error) {
+	return s.repo.GetActiveEmailTemplate(ctx, organizationID, templateKey)
+}
+
+// SaveEmailTemplate validates an override against the template's variables
+// and stores it as a new active version.
+func (s *Service) SaveEmailTemplate(ctx context.Context, organizationID, userID uuid.UUID, templateKey, subject, bodyHTML string) (repository.EmailTemplateVersion, error) {
+	definition, err := s.emailTemplateDefinition(templateKey)
+	if err != nil {
+		return repository.EmailTemplateVersion{}, err
+	}
+	subject, bodyHTML = strings.TrimSpace(subject), strings.TrimSpace(bodyHTML)
+	if _, err := s.renderValidEmailTemplate(ctx, organizationID, definition.Key, subject, bodyHTML); err != nil {
+		return repository.EmailTemplateVersion{}, err
+	}
+	return s.repo.CreateEmailTemplateVersion(ctx, organizationID, definition.Key, subject, bodyHTML, userID)
+}
+
+// ActivateEmailTemplateVersion rolls an override back or forward to a saved version.
+func (s *Service) ActivateEmailTemplateVersion(ctx context.Context, organizationID uuid.UUID, templateKey string, version int) error {
+	definition, err := s.emailTemplateDefinition(templateKey)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ActivateEmailTemplateVersion(ctx, organizationID, definition.Key, version); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.NotFound("email template version not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// ResetEmailTemplate makes the organization send the built-in template again.
+func (s *Service) ResetEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey string) error {
+	definition, err := s.emailTemplateDefinition(templateKey)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteEmailTemplateOverride(ctx, organizationID, definition.Key)
+}
+
+// PreviewEmailTemplate renders a draft override with example variables and
+// reports template errors and unknown variables.
+func (s *Service) PreviewEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey, subject, bodyHTML string) (RenderedEmailTemplate, error) {
+	definition, err := s.emailTemplateDefinition(templateKey)
+	if err != nil {
+		return RenderedEmailTemplate{}, err
+	}
+	return s.emailTemplateRenderer.RenderEmailTemplate(ctx, organizationID, definition.Key, subject, bodyHTML)
+}
+
+// SendEmailTemplateTest renders a draft override with example variables and
+// sends it to the requesting user through the organization's sender.
+func (s *Service) SendEmailTemplateTest(ctx context.Context, organizationID, userID uuid.UUID, templateKey, subject, bodyHTML string) error {
+	definition, err := s.emailTemplateDefinition(templateKey)
+	if err != nil {
+		return err
+	}
+	rendered, err := s.renderValidEmailTemplate(ctx, organizationID, definition.Key, subject, bodyHTML)
+	if err != nil {
+		return err
+	}
+	return s.emailTemplateRenderer.SendEmailTemplateTest(ctx, organizationID, userID, rendered)
+}
+
+func (s *Service) renderValidEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey, subject, bodyHTML string) (RenderedEmailTemplate, error) {
+	rendered, err := s.emailTemplateRenderer.RenderEmailTemplate(ctx, organizationID, templateKey, subject, bodyHTML)
+	if err != nil {
+		return RenderedEmailTemplate{}, err
+	}
+	if !rendered.Valid() {
+		return RenderedEmailTemplate{}, apperr.Validation("email template is invalid").WithDetails(map[string]any{
+			"templateErrors":      rendered.TemplateErrors,
+			"unresolvedVariables": rendered.UnresolvedVariables,
+		})
+	}
+	return rendered, nil
+}
+
+func (s *Service) emailTemplateDefinition(templateKey string) (EmailTemplateDefinition, error) {
+	if s.emailTemplateRenderer == nil {
+		return EmailTemplateDefinition{}, apperr.Internal("email templates are not configured")
+	}
+	templateKey = strings.TrimSpace(templateKey)
+	for _, definition := range s.emailTemplateRenderer.EmailTemplateCatalog() {
+		if strings.EqualFold(definition.Key, templateKey) {
+			return definition, nil
+		}
+	}
+	return EmailTemplateDefinition{}, apperr.NotFound(emailTemplateNotFound).WithDetails(templateKey)
+}
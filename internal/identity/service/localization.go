@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/i18n"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationLocalization is the notification language setup of an
+// organization.
+type OrganizationLocalization struct {
+	DefaultLanguage    string
+	SupportedLanguages []string
+}
+
+// GetOrganizationLocalization returns the default notification language of
+// an organization and the languages templates can be translated to.
+func (s *Service) GetOrganizationLocalization(ctx context.Context, organizationID uuid.UUID) (OrganizationLocalization, error) {
+	language, err := s.GetOrganizationDefaultLanguage(ctx, organizationID)
+	if err != nil {
+		return OrganizationLocalization{}, err
+	}
+	return OrganizationLocalization{DefaultLanguage: language, SupportedLanguages: i18n.SupportedLanguages()}, nil
+}
+
+// GetOrganizationDefaultLanguage returns the language notifications fall
+// back to when a lead has none.
+func (s *Service) GetOrganizationDefaultLanguage(ctx context.Context, organizationID uuid.UUID) (string, error) {
+	language, err := s.repo.GetOrganizationDefaultLanguage(ctx, organizationID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return "", apperr.NotFound(organizationNotFound)
+		}
+		return "", err
+	}
+	return i18n.Resolve(language), nil
+}
+
+// UpdateOrganizationDefaultLanguage sets the fallback notification language
+// of an organization.
+func (s *Service) UpdateOrganizationDefaultLanguage(ctx context.Context, organizationID uuid.UUID, language string) (OrganizationLocalization, error) {
+	normalized, ok := i18n.Normalize(language)
+	if !ok {
+		return OrganizationLocalization{}, apperr.Validation("unsupported language").WithDetails(language)
+	}
+	if err := s.repo.SetOrganizationDefaultLanguage(ctx, organizationID, normalized); err != nil {
+		if err == repository.ErrNotFound {
+			return OrganizationLocalization{}, apperr.NotFound(organizationNotFound)
+		}
+		return OrganizationLocalization{}, err
+	}
+	return OrganizationLocalization{DefaultLanguage: normalized, SupportedLanguages: i18n.SupportedLanguages()}, nil
+}
+
+func validateWorkflowStepsTranslations(steps []repository.WorkflowStepUpsert) error {
+	for _, step := range steps {
+		if err := validateWorkflowStepTranslations(step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateWorkflowStepTranslations(step repository.WorkflowStepUpsert) error {
+	for language := range step.TemplateTranslations {
+		if normalized, ok := i18n.Normalize(language); !ok || normalized != language {
+			return apperr.Validation(fmt.Sprintf("unsupported template language %q for step %d", language, step.StepOrder))
+		}
+	}
+	return nil
+}
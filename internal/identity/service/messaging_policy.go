@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+func (s *Service) GetOrganizationMessagingPolicy(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationMessagingPolicy, error) {
+	return s.repo.GetOrganizationMessagingPolicy(ctx, organizationID)
+}
+
+// UpdateOrganizationMessagingPolicy replaces the quiet hours and frequency
+// caps of an organization. The time zone must be a known IANA zone.
+func (s *Service) UpdateOrganizationMessagingPolicy(ctx context.Context, organizationID uuid.UUID, policy repository.OrganizationMessagingPolicy) (repository.OrganizationMessagingPolicy, error) {
+	policy.OrganizationID = organizationID
+	policy.Timezone = strings.TrimSpace(policy.Timezone)
+	if policy.Timezone == "" {
+		policy.Timezone = repository.DefaultMessagingTimezone
+	}
+	if _, err := time.LoadLocation(policy.Timezone); err != nil {
+		return repository.OrganizationMessagingPolicy{}, apperr.Validation("unknown timezone").WithDetails(policy.Timezone)
+	}
+	if !validMinuteOfDay(policy.QuietHoursStart) || !validMinuteOfDay(policy.QuietHoursEnd) {
+		return repository.OrganizationMessagingPolicy{}, apperr.Validation("quiet hours must be between 00:00 and 23:59")
+	}
+	if policy.QuietHoursEnabled && policy.QuietHoursStart == policy.QuietHoursEnd {
+		return repository.OrganizationMessagingPolicy{}, apperr.Validation("quiet hours start and end must differ")
+	}
+	if policy.MaxMessagesPerLeadPerHour != nil && policy.MaxMessagesPerLeadPerDay != nil &&
+		*policy.MaxMessagesPerLeadPerHour > *policy.MaxMessagesPerLeadPerDay {
+		return repository.OrganizationMessagingPolicy{}, apperr.Validation("hourly cap cannot exceed the daily cap")
+	}
+	return s.repo.UpsertOrganizationMessagingPolicy(ctx, policy)
+}
+
+func validMinuteOfDay(minute int) bool {
+	return minute >= 0 && minute < 24*60
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const demoLeadSource = "demo"
+
+// CloneOrganizationInput describes a demo or sandbox organization copied from a source.
+type CloneOrganizationInput struct {
+	SourceOrganizationID uuid.UUID
+	CreatedBy            uuid.UUID
+	Name                 string
+	Purpose              string
+	InviteEmail          string
+	DemoLeads            int
+}
+
+type CloneOrganizationOutput struct {
+	OrganizationID  uuid.UUID
+	Name            string
+	Purpose         string
+	CopiedRows      map[string]int64
+	DemoLeads       int
+	InviteExpiresAt *time.Time
+}
+
+// CloneOrganization copies the configuration of an organization into a new
+// organization without customer data, optionally fills it with synthetic demo
+// leads and invites a first user. The copy does not publish OrganizationCreated,
+// so no default workflows or catalog are seeded on top of the copied ones.
+func (s *Service) CloneOrganization(ctx context.Context, input CloneOrganizationInput) (CloneOrganizationOutput, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return CloneOrganizationOutput{}, apperr.Validation("organization name is required")
+	}
+
+	cloned, err := s.repo.CloneOrganization(ctx, repository.CloneOrganizationParams{
+		SourceOrganizationID: input.SourceOrganizationID,
+		Name:                 name,
+		Purpose:              input.Purpose,
+		CreatedBy:            input.CreatedBy,
+	})
+	if err == repository.ErrNotFound {
+		return CloneOrganizationOutput{}, apperr.NotFound(organizationNotFound)
+	}
+	if err != nil {
+		return CloneOrganizationOutput{}, err
+	}
+
+	output := CloneOrganizationOutput{
+		OrganizationID: cloned.OrganizationID,
+		Name:           name,
+		Purpose:        input.Purpose,
+		CopiedRows:     cloned.CopiedRows,
+	}
+
+	if input.DemoLeads > 0 {
+		created, err := s.createDemoLeads(ctx, cloned.OrganizationID, input.DemoLeads)
+		if err != nil {
+			return output, fmt.Errorf("organization %s created, demo leads failed: %w", cloned.OrganizationID, err)
+		}
+		output.DemoLeads = created
+	}
+
+	if input.InviteEmail != "" {
+		_, expiresAt, err := s.CreateInvite(ctx, cloned.OrganizationID, input.InviteEmail, input.CreatedBy)
+		if err != nil {
+			return output, fmt.Errorf("organization %s created, invite failed: %w", cloned.OrganizationID, err)
+		}
+		output.InviteExpiresAt = &expiresAt
+	}
+
+	return output, nil
+}
+
+// createDemoLeads inserts synthetic leads straight into the repository. No
+// LeadCreated events are published, so demo leads do not trigger AI agents or
+// outbound messages.
+func (s *Service) createDemoLeads(ctx context.Context, organizationID uuid.UUID, count int) (int, error) {
+	if s.leadsRepo == nil {
+		return 0, nil
+	}
+	serviceTypes, err := s.repo.ListActiveServiceTypeNames(ctx, organizationID)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, spec := range demoLeadSpecs(count, serviceTypes) {
+		spec.lead.OrganizationID = organizationID
+		lead, err := s.leadsRepo.Create(ctx, spec.lead)
+		if err != nil {
+			return created, err
+		}
+		created++
+		if spec.serviceType == "" {
+			continue
+		}
+		if _, err := s.leadsRepo.CreateLeadService(ctx, leadsrepo.CreateLeadServiceParams{
+			LeadID:         lead.ID,
+			OrganizationID: organizationID,
+			ServiceType:    spec.serviceType,
+			ConsumerNote:   &spec.note,
+			Source:         spec.lead.Source,
+		}); err != nil {
+			return created, err
+		}
+	}
+
+	if err := s.repo.RecordCloneDemoLeads(ctx, organizationID, created); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+type demoLeadSpec struct {
+	lead        leadsrepo.CreateLeadParams
+	serviceType string
+	note        string
+}
+
+var (
+	demoFirstNames = []string{"Sanne", "Daan", "Emma", "Lucas", "Julia", "Thijs", "Fleur", "Bram", "Lotte", "Ruben"}
+	demoLastNames  = []string{"de Vries", "Jansen", "Bakker", "Visser", "Smit", "Meijer", "de Boer", "Mulder", "Bos", "Vos"}
+	demoCities     = []struct{ zip, city string }{
+		{"1011 AB", "Amsterdam"}, {"3511 CD", "Utrecht"}, {"3011 EF", "Rotterdam"},
+		{"2511 GH", "Den Haag"}, {"5611 JK", "Eindhoven"}, {"9711 LM", "Groningen"},
+	}
+	demoNotes = []string{
+		"Graag een offerte, liefst binnen twee weken.",
+		"Kunt u eerst langskomen om te kijken?",
+		"Ik ben overdag bereikbaar via e-mail.",
+		"Het gaat om een tussenwoning uit 1985.",
+	}
+)
+
+// demoLeadSpecs builds deterministic, clearly fictional leads. Emails use the
+// reserved example.com domain and phone numbers come from the range Ofcom
+// reserves for drama, so demo data never reaches a real person.
+func demoLeadSpecs(count int, serviceTypes []string) []demoLeadSpec {
+	source := demoLeadSource
+	specs := make([]demoLeadSpec, 0, count)
+	for i := 0; i < count; i++ {
+		firstName := demoFirstNames[i%len(demoFirstNames)]
+		lastName := demoLastNames[(i*3)%len(demoLastNames)]
+		location := demoCities[i%len(demoCities)]
+		email := fmt.Sprintf("demo.lead%02d@example.com", i+1)
+
+		spec := demoLeadSpec{
+			lead: leadsrepo.CreateLeadParams{
+				ConsumerFirstName:  firstName,
+				ConsumerLastName:   lastName,
+				ConsumerPhone:      fmt.Sprintf("+447700900%03d", i),
+				ConsumerEmail:      &email,
+				ConsumerRole:       "Owner",
+				AddressStreet:      "Voorbeeldstraat",
+				AddressHouseNumber: fmt.Sprintf("%d", i+1),
+				AddressZipCode:     location.zip,
+				AddressCity:        location.city,
+				Source:             &source,
+			},
+			note: demoNotes[i%len(demoNotes)],
+		}
+		if len(serviceTypes) > 0 {
+			spec.serviceType = serviceTypes[i%len(serviceTypes)]
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
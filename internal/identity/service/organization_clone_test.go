@@ -0,0 +1,42 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDemoLeadSpecsAreFictional(t *testing.T) {
+	specs := demoLeadSpecs(12, []string{"Dakkapel", "Zonnepanelen"})
+	if len(specs) != 12 {
+		t.Fatalf("expected 12 demo leads, got %d", len(specs))
+	}
+
+	phones := make(map[string]bool, len(specs))
+	for i, spec := range specs {
+		if !strings.HasPrefix(spec.lead.ConsumerPhone, "+447700900") {
+			t.Fatalf("lead %d: expected a reserved drama phone number, got %q", i, spec.lead.ConsumerPhone)
+		}
+		if spec.lead.ConsumerEmail == nil || !strings.HasSuffix(*spec.lead.ConsumerEmail, "@example.com") {
+			t.Fatalf("lead %d: expected an example.com email, got %v", i, spec.lead.ConsumerEmail)
+		}
+		if spec.lead.Source == nil || *spec.lead.Source != demoLeadSource {
+			t.Fatalf("lead %d: expected source %q, got %v", i, demoLeadSource, spec.lead.Source)
+		}
+		if phones[spec.lead.ConsumerPhone] {
+			t.Fatalf("lead %d: duplicate phone %q", i, spec.lead.ConsumerPhone)
+		}
+		phones[spec.lead.ConsumerPhone] = true
+	}
+
+	if specs[0].serviceType != "Dakkapel" || specs[1].serviceType != "Zonnepanelen" {
+		t.Fatalf("expected service types to rotate, got %q and %q", specs[0].serviceType, specs[1].serviceType)
+	}
+}
+
+func TestDemoLeadSpecsWithoutServiceTypes(t *testing.T) {
+	for _, spec := range demoLeadSpecs(3, nil) {
+		if spec.serviceType != "" {
+			t.Fatalf("expected no service type without active service types, got %q", spec.serviceType)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const organizationNotBranch = "organization is not a branch"
+
+// BranchCatalogSyncOutput reports the catalog sync of one branch.
+type BranchCatalogSyncOutput struct {
+	OrganizationID uuid.UUID
+	repository.BranchCatalogSync
+}
+
+// OrganizationHierarchy is the place of an organization in a franchise: the
+// head office it belongs to, or the branches it has.
+type OrganizationHierarchy struct {
+	ParentOrganizationID *uuid.UUID
+	Branches             []repository.OrganizationBranch
+}
+
+// LinkBranch makes an organization a branch of a head office and copies the
+// head-office catalog into it.
+func (s *Service) LinkBranch(ctx context.Context, branchID, parentID uuid.UUID) (BranchCatalogSyncOutput, error) {
+	if err := s.repo.SetParentOrganization(ctx, branchID, parentID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return BranchCatalogSyncOutput{}, apperr.NotFound(organizationNotFound)
+		}
+		return BranchCatalogSyncOutput{}, err
+	}
+	return s.syncBranchCatalog(ctx, branchID)
+}
+
+// UnlinkBranch detaches a branch from its head office. The branch keeps the
+// products it inherited.
+func (s *Service) UnlinkBranch(ctx context.Context, branchID uuid.UUID) error {
+	err := s.repo.ClearParentOrganization(ctx, branchID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.NotFound(organizationNotBranch)
+	}
+	return err
+}
+
+// GetOrganizationHierarchy returns the head office or the branches of an organization.
+func (s *Service) GetOrganizationHierarchy(ctx context.Context, organizationID uuid.UUID) (OrganizationHierarchy, error) {
+	parentID, err := s.repo.GetParentOrganizationID(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return OrganizationHierarchy{}, apperr.NotFound(organizationNotFound)
+	}
+	if err != nil {
+		return OrganizationHierarchy{}, err
+	}
+	hierarchy := OrganizationHierarchy{ParentOrganizationID: parentID, Branches: []repository.OrganizationBranch{}}
+	if parentID != nil {
+		return hierarchy, nil
+	}
+	hierarchy.Branches, err = s.repo.ListBranches(ctx, organizationID)
+	return hierarchy, err
+}
+
+// ListBranchIDs returns the branches of a head office, for reports that
+// consolidate them.
+func (s *Service) ListBranchIDs(ctx context.Context, parentID uuid.UUID) ([]uuid.UUID, error) {
+	branches, err := s.repo.ListBranches(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(branches))
+	for i, branch := range branches {
+		ids[i] = branch.ID
+	}
+	return ids, nil
+}
+
+// IsBranchOf reports whether an organization is a branch of the head office.
+// It backs the read access of head-office users to their branches.
+func (s *Service) IsBranchOf(ctx context.Context, parentID, branchID uuid.UUID) (bool, error) {
+	return s.repo.IsBranchOf(ctx, parentID, branchID)
+}
+
+// SyncBranchCatalogs pushes the head-office catalog to every branch.
+func (s *Service) SyncBranchCatalogs(ctx context.Context, parentID uuid.UUID) ([]BranchCatalogSyncOutput, error) {
+	branches, err := s.repo.ListBranches(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]BranchCatalogSyncOutput, 0, len(branches))
+	for _, branch := range branches {
+		result, err := s.syncBranchCatalog(ctx, branch.ID)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *Service) syncBranchCatalog(ctx context.Context, branchID uuid.UUID) (BranchCatalogSyncOutput, error) {
+	synced, err := s.repo.SyncBranchCatalog(ctx, branchID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return BranchCatalogSyncOutput{}, apperr.NotFound(organizationNotBranch)
+	}
+	if err != nil {
+		return BranchCatalogSyncOutput{}, err
+	}
+	return BranchCatalogSyncOutput{OrganizationID: branchID, BranchCatalogSync: synced}, nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/auth/token"
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// PortalDomainClaimTTL is how long an organization has to verify a domain
+	// it registered before the claim is removed.
+	PortalDomainClaimTTL = 7 * 24 * time.Hour
+	// PortalDomainRecheckInterval is how often verified domains are checked
+	// for their TXT record.
+	PortalDomainRecheckInterval = 24 * time.Hour
+	// portalDomainRevokeAfter is how long the TXT record of a verified domain
+	// may be missing before the verification is revoked.
+	portalDomainRevokeAfter  = 72 * time.Hour
+	portalDomainRecheckBatch = 100
+
+	portalDomainNotConfigured     = "portal domain not configured"
+	portalDomainTokenBytes        = 24
+	portalDomainRecordPrefix      = "_portal-verification."
+	portalDomainRecordValuePrefix = "portal-verification="
+)
+
+var portalDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// DomainVerificationRecord is the DNS TXT record that proves ownership of a
+// portal or SSO domain.
+type DomainVerificationRecord struct {
+	Name  string
+	Value string
+}
+
+// PortalDomainVerificationRecord returns the TXT record an organization has to
+// publish for its portal domain.
+func PortalDomainVerificationRecord(domain repository.OrganizationPortalDomain) DomainVerificationRecord {
+	return DomainVerificationRecord{
+		Name:  portalDomainRecordPrefix + domain.Domain,
+		Value: portalDomainRecordValuePrefix + domain.VerificationToken,
+	}
+}
+
+// GetPortalDomain returns the custom portal domain of an organization.
+func (s *Service) GetPortalDomain(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationPortalDomain, error) {
+	domain, err := s.repo.GetOrganizationPortalDomain(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.OrganizationPortalDomain{}, apperr.NotFound(portalDomainNotConfigured)
+	}
+	return domain, err
+}
+
+// PortalDomainMaintenanceResult summarizes a portal domain maintenance run.
+type PortalDomainMaintenanceResult struct {
+	ExpiredClaims int64
+	Checked       int
+	Revoked       int
+	Failed        int
+}
+
+// RegisterPortalDomain sets the custom domain of the public portal. The domain
+// is used for portal links once VerifyPortalDomain found its TXT record.
+func (s *Service) RegisterPortalDomain(ctx context.Context, organizationID uuid.UUID, rawDomain string) (repository.OrganizationPortalDomain, error) {
+	domain, err := normalizePortalDomain(rawDomain)
+	if err != nil {
+		return repository.OrganizationPortalDomain{}, err
+	}
+	owner, err := s.repo.GetVerifiedPortalDomainByHost(ctx, domain)
+	if err == nil && owner.OrganizationID != organizationID {
+		return repository.OrganizationPortalDomain{}, apperr.Conflict("domain is already used by another organization")
+	}
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return repository.OrganizationPortalDomain{}, err
+	}
+	verificationToken, err := token.GenerateRandomToken(portalDomainTokenBytes)
+	if err != nil {
+		return repository.OrganizationPortalDomain{}, err
+	}
+	return s.repo.UpsertOrganizationPortalDomain(ctx, organizationID, domain, verificationToken)
+}
+
+// VerifyPortalDomain looks up the TXT record of the portal domain and records
+// whether it holds the verification token.
+func (s *Service) VerifyPortalDomain(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationPortalDomain, error) {
+	domain, err := s.GetPortalDomain(ctx, organizationID)
+	if err != nil {
+		return repository.OrganizationPortalDomain{}, err
+	}
+
+	domain, err = s.repo.RecordPortalDomainCheck(ctx, organizationID, s.checkPortalDomain(ctx, domain, time.Now()))
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.OrganizationPortalDomain{}, apperr.NotFound(portalDomainNotConfigured)
+	}
+	return domain, err
+}
+
+// MaintainPortalDomains removes claims that were not verified within
+// PortalDomainClaimTTL and re-checks verified domains that were not checked
+// within PortalDomainRecheckInterval. A verified domain whose TXT record has
+// been missing for portalDomainRevokeAfter loses its verification, so a domain
+// that changed hands no longer serves the portal of its former owner.
+func (s *Service) MaintainPortalDomains(ctx context.Context, now time.Time) (PortalDomainMaintenanceResult, error) {
+	var result PortalDomainMaintenanceResult
+	expired, err := s.repo.DeleteUnverifiedPortalDomainClaims(ctx, now.Add(-PortalDomainClaimTTL))
+	if err != nil {
+		return result, err
+	}
+	result.ExpiredClaims = expired
+
+	due, err := s.repo.ListPortalDomainsDueForCheck(ctx, now.Add(-PortalDomainRecheckInterval), portalDomainRecheckBatch)
+	if err != nil {
+		return result, err
+	}
+	for _, domain := range due {
+		check := s.checkPortalDomain(ctx, domain, now)
+		if _, err := s.repo.RecordPortalDomainCheck(ctx, domain.OrganizationID, check); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			return result, err
+		}
+		result.Checked++
+		if check.Revoke {
+			result.Revoked++
+		} else if !check.Verified {
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// checkPortalDomain looks up the TXT record of a portal domain. A lookup that
+// fails keeps the verification; a record that has been missing since
+// portalDomainRevokeAfter revokes it.
+func (s *Service) checkPortalDomain(ctx context.Context, domain repository.OrganizationPortalDomain, now time.Time) repository.PortalDomainCheck {
+	record := PortalDomainVerificationRecord(domain)
+	values, err := s.lookupTXT(ctx, record.Name)
+	if err == nil && slices.Contains(values, record.Value) {
+		return repository.PortalDomainCheck{Verified: true}
+	}
+
+	msg := "verification record not found"
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && !dnsErr.IsNotFound {
+		msg = "DNS lookup failed: " + dnsErr.Err
+	}
+	if err != nil && (dnsErr == nil || !dnsErr.IsNotFound) {
+		return repository.PortalDomainCheck{Error: &msg}
+	}
+	lastVerified := domain.LastVerifiedAt
+	if lastVerified == nil {
+		lastVerified = domain.VerifiedAt
+	}
+	revoke := lastVerified != nil && lastVerified.Before(now.Add(-portalDomainRevokeAfter))
+	return repository.PortalDomainCheck{Revoke: revoke, Error: &msg}
+}
+
+// DeletePortalDomain removes the custom portal domain; links fall back to the
+// shared portal URL.
+func (s *Service) DeletePortalDomain(ctx context.Context, organizationID uuid.UUID) error {
+	err := s.repo.DeleteOrganizationPortalDomain(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.NotFound(portalDomainNotConfigured)
+	}
+	return err
+}
+
+// ResolvePublicBaseURL returns the base URL of the public portal of an
+// organization on its verified custom domain, or an empty string when the
+// shared portal URL applies.
+func (s *Service) ResolvePublicBaseURL(ctx context.Context, organizationID uuid.UUID) (string, error) {
+	domain, err := s.repo.GetOrganizationPortalDomain(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if domain.VerifiedAt == nil {
+		return "", nil
+	}
+	return "https://" + domain.Domain, nil
+}
+
+// ResolvePortalHost returns the organization whose verified portal domain is
+// the given request host.
+func (s *Service) ResolvePortalHost(ctx context.Context, host string) (uuid.UUID, bool, error) {
+	host = strings.ToLower(strings.TrimSuffix(stripPort(strings.TrimSpace(host)), "."))
+	if host == "" {
+		return uuid.UUID{}, false, nil
+	}
+	domain, err := s.repo.GetVerifiedPortalDomainByHost(ctx, host)
+	if errors.Is(err, repository.ErrNotFound) {
+		return uuid.UUID{}, false, nil
+	}
+	if err != nil {
+		return uuid.UUID{}, false, err
+	}
+	return domain.OrganizationID, true, nil
+}
+
+// GetPortalOrganization returns the organization behind a portal domain, for
+// white-label portals to load their branding.
+func (s *Service) GetPortalOrganization(ctx context.Context, organizationID uuid.UUID) (repository.Organization, repository.OrganizationPortalDomain, error) {
+	org, err := s.repo.GetOrganization(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.Organization{}, repository.OrganizationPortalDomain{}, apperr.NotFound(organizationNotFound)
+	}
+	if err != nil {
+		return repository.Organization{}, repository.OrganizationPortalDomain{}, err
+	}
+	domain, err := s.GetPortalDomain(ctx, organizationID)
+	return org, domain, err
+}
+
+// normalizePortalDomain accepts a bare host name or a URL and returns the
+// lower-case host name.
+func normalizePortalDomain(raw string) (string, error) {
+	domain := strings.ToLower(strings.TrimSpace(raw))
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	domain = strings.TrimSuffix(domain, ".")
+	if len(domain) > 253 || !portalDomainPattern.MatchString(domain) {
+		return "", apperr.Validation("domain must be a host name such as offerte.example.nl")
+	}
+	return domain, nil
+}
+
+func stripPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+)
+
+func TestNormalizePortalDomain(t *testing.T) {
+	valid := map[string]string{
+		"offerte.example.nl":                    "offerte.example.nl",
+		"  Offerte.Example.NL. ":                "offerte.example.nl",
+		"https://portal.example.com/track?x=1":  "portal.example.com",
+		"http://my-brand.example.co.uk/quotes/": "my-brand.example.co.uk",
+	}
+	for raw, want := range valid {
+		got, err := normalizePortalDomain(raw)
+		if err != nil {
+			t.Fatalf("normalizePortalDomain(%q) error = %v", raw, err)
+		}
+		if got != want {
+			t.Fatalf("normalizePortalDomain(%q) = %q, want %q", raw, got, want)
+		}
+	}
+
+	for _, raw := range []string{"", "localhost", "10.0.0.1", "example.com:8080", "-bad.example.com", "under_score.example.com"} {
+		if _, err := normalizePortalDomain(raw); err == nil {
+			t.Fatalf("normalizePortalDomain(%q): expected error", raw)
+		}
+	}
+}
+
+func TestStripPort(t *testing.T) {
+	if got := stripPort("portal.example.com:443"); got != "portal.example.com" {
+		t.Fatalf("stripPort() = %q", got)
+	}
+	if got := stripPort("portal.example.com"); got != "portal.example.com" {
+		t.Fatalf("stripPort() = %q", got)
+	}
+}
+
+func TestCheckPortalDomainRevokesOnlyLongMissingRecords(t *testing.T) {
+	now := time.Now()
+	recent, old := now.Add(-time.Hour), now.Add(-4*24*time.Hour)
+	domain := repository.OrganizationPortalDomain{Domain: "offerte.example.nl", VerificationToken: "token"}
+	record := PortalDomainVerificationRecord(domain)
+	notFound := &net.DNSError{Err: "no such host", IsNotFound: true}
+	timeout := &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+
+	tests := []struct {
+		name         string
+		lastVerified *time.Time
+		values       []string
+		err          error
+		want         repository.PortalDomainCheck
+	}{
+		{name: "record present", lastVerified: &old, values: []string{"other", record.Value}, want: repository.PortalDomainCheck{Verified: true}},
+		{name: "record missing briefly", lastVerified: &recent, values: []string{"other"}},
+		{name: "record missing for days", lastVerified: &old, err: notFound, want: repository.PortalDomainCheck{Revoke: true}},
+		{name: "lookup failing for days", lastVerified: &old, err: timeout},
+		{name: "lookup cancelled", lastVerified: &old, err: context.Canceled},
+		{name: "never verified", err: notFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Service{lookupTXT: func(_ context.Context, name string) ([]string, error) {
+				if name != record.Name {
+					return nil, errors.New("unexpected record " + name)
+				}
+				return tt.values, tt.err
+			}}
+			d := domain
+			d.LastVerifiedAt = tt.lastVerified
+			got := svc.checkPortalDomain(context.Background(), d, now)
+			if got.Verified != tt.want.Verified || got.Revoke != tt.want.Revoke {
+				t.Fatalf("checkPortalDomain() = %+v, want verified=%v revoke=%v", got, tt.want.Verified, tt.want.Revoke)
+			}
+			if !got.Verified && got.Error == nil {
+				t.Fatal("expected a failed check to carry an error")
+			}
+		})
+	}
+}
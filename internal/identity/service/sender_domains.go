@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	senderCheckPass    = "pass"
+	senderCheckMissing = "missing"
+	senderCheckInvalid = "invalid"
+	senderCheckError   = "error"
+	// senderCheckSkipped marks checks of mailbox providers such as Gmail,
+	// which publish and sign for their own domains.
+	senderCheckSkipped = "skipped"
+
+	senderDomainNotChecked = "sender domain not checked"
+	senderDomainTimeout    = 10 * time.Second
+)
+
+// commonDKIMSelectors are tried when no DKIM selector is known for a domain.
+var commonDKIMSelectors = []string{
+	"default", "google", "selector1", "selector2", "k1", "k2", "s1", "s2", "mail", "dkim", "smtp",
+}
+
+// GetSenderDomain returns the last DNS check of the organization's sender domain.
+func (s *Service) GetSenderDomain(ctx context.Context, organizationID uuid.UUID) (transport.SenderDomainResponse, error) {
+	domain, err := s.repo.GetOrganizationSenderDomain(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.SenderDomainResponse{}, apperr.NotFound(senderDomainNotChecked)
+	}
+	if err != nil {
+		return transport.SenderDomainResponse{}, err
+	}
+	return toSenderDomainResponse(domain), nil
+}
+
+// VerifySenderDomain checks the SPF, DKIM and DMARC records of a sender
+// domain and stores the outcome. An empty domain checks the domain of the
+// configured SMTP from address; an empty selector reuses the selector found
+// by the previous check or tries common ones.
+func (s *Service) VerifySenderDomain(ctx context.Context, organizationID uuid.UUID, domain, dkimSelector string) (transport.SenderDomainResponse, error) {
+	checked, err := s.verifySenderDomain(ctx, organizationID, domain, dkimSelector)
+	if err != nil {
+		return transport.SenderDomainResponse{}, err
+	}
+	return toSenderDomainResponse(checked), nil
+}
+
+func (s *Service) verifySenderDomain(ctx context.Context, organizationID uuid.UUID, domain, dkimSelector string) (repository.OrganizationSenderDomain, error) {
+	domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+	if domain == "" {
+		settings, err := s.repo.GetOrganizationSettings(ctx, organizationID)
+		if err != nil {
+			return repository.OrganizationSenderDomain{}, err
+		}
+		if settings.SMTPFromEmail != nil {
+			domain, _, _ = parseSMTPEmail(*settings.SMTPFromEmail)
+		}
+		if domain == "" {
+			return repository.OrganizationSenderDomain{}, apperr.Validation("no SMTP from address configured; provide the domain to check")
+		}
+	}
+	if dkimSelector == "" {
+		previous, err := s.repo.GetOrganizationSenderDomain(ctx, organizationID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return repository.OrganizationSenderDomain{}, err
+		}
+		if err == nil && previous.Domain == domain && previous.DKIMSelector != nil {
+			dkimSelector = *previous.DKIMSelector
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, senderDomainTimeout)
+	defer cancel()
+	check := checkSenderDomain(ctx, s.lookupTXT, domain, strings.TrimSpace(dkimSelector))
+	return s.repo.RecordSenderDomainCheck(ctx, organizationID, check)
+}
+
+// requireSenderDomain refuses tenant SMTP for a from address whose domain
+// fails the DNS checks, unless allowUnverified overrides the refusal.
+func (s *Service) requireSenderDomain(ctx context.Context, organizationID uuid.UUID, fromEmail, dkimSelector string, allowUnverified bool) error {
+	domain, _, ok := parseSMTPEmail(fromEmail)
+	if !ok {
+		return apperr.Validation("invalid from address")
+	}
+	checked, err := s.verifySenderDomain(ctx, organizationID, domain, dkimSelector)
+	if err != nil {
+		return err
+	}
+	if checked.VerifiedAt != nil {
+		return nil
+	}
+	if !allowUnverified {
+		return apperr.Validation(fmt.Sprintf("sender domain %s failed DNS checks (%s); fix the records or set allowUnverifiedDomain",
+			checked.Domain, strings.Join(checked.Warnings, "; ")))
+	}
+	_, err = s.repo.OverrideSenderDomain(ctx, organizationID)
+	return err
+}
+
+// checkSenderDomain looks up the SPF, DKIM and DMARC records of domain.
+func checkSenderDomain(ctx context.Context, lookupTXT func(context.Context, string) ([]string, error), domain, dkimSelector string) repository.SenderDomainCheck {
+	check := repository.SenderDomainCheck{Domain: domain}
+	if _, managed := knownProviders[domain]; managed {
+		check.SPFStatus, check.DKIMStatus, check.DMARCStatus = senderCheckSkipped, senderCheckSkipped, senderCheckSkipped
+		check.Passed = true
+		return check
+	}
+
+	var warnings []string
+	check.SPFStatus, warnings = checkSPF(ctx, lookupTXT, domain)
+	check.Warnings = append(check.Warnings, warnings...)
+
+	var selector string
+	check.DKIMStatus, selector, warnings = checkDKIM(ctx, lookupTXT, domain, dkimSelector)
+	check.Warnings = append(check.Warnings, warnings...)
+	if selector != "" {
+		check.DKIMSelector = &selector
+	}
+
+	check.DMARCStatus, warnings = checkDMARC(ctx, lookupTXT, domain)
+	check.Warnings = append(check.Warnings, warnings...)
+
+	check.Passed = check.SPFStatus == senderCheckPass && check.DKIMStatus == senderCheckPass && check.DMARCStatus == senderCheckPass
+	return check
+}
+
+func checkSPF(ctx context.Context, lookupTXT func(context.Context, string) ([]string, error), domain string) (string, []string) {
+	records, status := lookupTaggedTXT(ctx, lookupTXT, domain, "v=spf1")
+	switch {
+	case status != senderCheckPass:
+		return status, []string{senderLookupWarning("SPF", domain, status)}
+	case len(records) > 1:
+		return senderCheckInvalid, []string{fmt.Sprintf("%s publishes %d SPF records; receivers reject mail when there is more than one", domain, len(records))}
+	case strings.HasSuffix(strings.ToLower(records[0]), "+all"):
+		return senderCheckPass, []string{fmt.Sprintf("the SPF record of %s ends in +all and authorizes every server", domain)}
+	}
+	return senderCheckPass, nil
+}
+
+func checkDKIM(ctx context.Context, lookupTXT func(context.Context, string) ([]string, error), domain, selector string) (string, string, []string) {
+	selectors := commonDKIMSelectors
+	if selector != "" {
+		selectors = []string{selector}
+	}
+	status := senderCheckMissing
+	for _, candidate := range selectors {
+		name := candidate + "._domainkey." + domain
+		records, err := lookupTXT(ctx, name)
+		if err != nil {
+			if lookupFailed(err) {
+				status = senderCheckError
+			}
+			continue
+		}
+		for _, record := range records {
+			if dkimKey(record) != "" {
+				return senderCheckPass, candidate, nil
+			}
+		}
+		status = senderCheckInvalid
+	}
+	if selector != "" {
+		return status, selector, []string{fmt.Sprintf("no DKIM key found at %s._domainkey.%s", selector, domain)}
+	}
+	return status, "", []string{fmt.Sprintf("no DKIM key found for %s under common selectors; provide the DKIM selector of your mail provider", domain)}
+}
+
+func checkDMARC(ctx context.Context, lookupTXT func(context.Context, string) ([]string, error), domain string) (string, []string) {
+	records, status := lookupTaggedTXT(ctx, lookupTXT, "_dmarc."+domain, "v=DMARC1")
+	switch {
+	case status != senderCheckPass:
+		return status, []string{senderLookupWarning("DMARC", "_dmarc."+domain, status)}
+	case len(records) > 1:
+		return senderCheckInvalid, []string{fmt.Sprintf("%s publishes %d DMARC records; receivers ignore DMARC when there is more than one", domain, len(records))}
+	case dmarcPolicy(records[0]) == "none":
+		return senderCheckPass, []string{fmt.Sprintf("the DMARC policy of %s is p=none; spoofed mail is not rejected", domain)}
+	}
+	return senderCheckPass, nil
+}
+
+// lookupTaggedTXT returns the TXT records of name that start with tag.
+func lookupTaggedTXT(ctx context.Context, lookupTXT func(context.Context, string) ([]string, error), name, tag string) ([]string, string) {
+	values, err := lookupTXT(ctx, name)
+	if err != nil {
+		if lookupFailed(err) {
+			return nil, senderCheckError
+		}
+		return nil, senderCheckMissing
+	}
+	var records []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) >= len(tag) && strings.EqualFold(value[:len(tag)], tag) {
+			records = append(records, value)
+		}
+	}
+	if len(records) == 0 {
+		return nil, senderCheckMissing
+	}
+	return records, senderCheckPass
+}
+
+// lookupFailed reports whether err is a DNS failure other than the record not
+// existing.
+func lookupFailed(err error) bool {
+	var dnsErr *net.DNSError
+	return !errors.As(err, &dnsErr) || !dnsErr.IsNotFound
+}
+
+func senderLookupWarning(kind, name, status string) string {
+	if status == senderCheckError {
+		return fmt.Sprintf("DNS lookup of the %s record at %s failed", kind, name)
+	}
+	return fmt.Sprintf("no %s record found at %s", kind, name)
+}
+
+// dkimKey returns the public key (p= tag) of a DKIM record.
+func dkimKey(record string) string {
+	return strings.TrimSpace(recordTag(record, "p"))
+}
+
+// dmarcPolicy returns the policy (p= tag) of a DMARC record.
+func dmarcPolicy(record string) string {
+	return strings.ToLower(strings.TrimSpace(recordTag(record, "p")))
+}
+
+// recordTag returns the value of a tag in a "tag=value; tag=value" record.
+func recordTag(record, tag string) string {
+	for _, part := range strings.Split(record, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), tag) {
+			return value
+		}
+	}
+	return ""
+}
+
+func toSenderDomainResponse(domain repository.OrganizationSenderDomain) transport.SenderDomainResponse {
+	warnings := domain.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return transport.SenderDomainResponse{
+		Domain:        domain.Domain,
+		Verified:      domain.VerifiedAt != nil,
+		VerifiedAt:    domain.VerifiedAt,
+		Overridden:    domain.VerifiedAt == nil && domain.OverrideAt != nil,
+		OverrideAt:    domain.OverrideAt,
+		DKIMSelector:  domain.DKIMSelector,
+		SPF:           domain.SPFStatus,
+		DKIM:          domain.DKIMStatus,
+		DMARC:         domain.DMARCStatus,
+		Warnings:      warnings,
+		LastCheckedAt: domain.LastCheckedAt,
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+func fakeTXT(records map[string][]string) func(context.Context, string) ([]string, error) {
+	return func(_ context.Context, name string) ([]string, error) {
+		if values, ok := records[name]; ok {
+			return values, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+}
+
+func TestCheckSenderDomainPasses(t *testing.T) {
+	lookup := fakeTXT(map[string][]string{
+		"example.nl":                     {"google-site-verification=abc", "v=spf1 include:_spf.google.com ~all"},
+		"google._domainkey.example.nl":   {"v=DKIM1; k=rsa; p=MIIBIjANBg"},
+		"_dmarc.example.nl":              {"v=DMARC1; p=none; rua=mailto:dmarc@example.nl"},
+		"selector1._domainkey.other.com": {"v=DKIM1; p="},
+	})
+
+	check := checkSenderDomain(context.Background(), lookup, "example.nl", "")
+	if !check.Passed || check.DKIMSelector == nil || *check.DKIMSelector != "google" {
+		t.Fatalf("checkSenderDomain() = %+v, want passed with selector google", check)
+	}
+	if len(check.Warnings) != 1 || !strings.Contains(check.Warnings[0], "p=none") {
+		t.Fatalf("checkSenderDomain() warnings = %v, want the p=none warning", check.Warnings)
+	}
+}
+
+func TestCheckSenderDomainReportsMissingRecords(t *testing.T) {
+	lookup := fakeTXT(map[string][]string{
+		"example.nl":                      {"v=spf1 a mx ~all", "v=spf1 include:mail.example.nl ~all"},
+		"selector1._domainkey.example.nl": {"v=DKIM1; p="},
+	})
+
+	check := checkSenderDomain(context.Background(), lookup, "example.nl", "selector1")
+	if check.Passed {
+		t.Fatal("checkSenderDomain() passed without DKIM key and DMARC record")
+	}
+	if check.SPFStatus != senderCheckInvalid || check.DKIMStatus != senderCheckInvalid || check.DMARCStatus != senderCheckMissing {
+		t.Fatalf("checkSenderDomain() statuses = %s/%s/%s", check.SPFStatus, check.DKIMStatus, check.DMARCStatus)
+	}
+	if len(check.Warnings) != 3 {
+		t.Fatalf("checkSenderDomain() warnings = %v, want one per record", check.Warnings)
+	}
+}
+
+func TestCheckSenderDomainSkipsMailboxProviders(t *testing.T) {
+	check := checkSenderDomain(context.Background(), fakeTXT(nil), "gmail.com", "")
+	if !check.Passed || check.SPFStatus != senderCheckSkipped {
+		t.Fatalf("checkSenderDomain() = %+v, want skipped checks", check)
+	}
+}
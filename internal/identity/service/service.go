@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"portal_final_backend/internal/notification/sse"
 	"portal_final_backend/internal/whatsapp"
 	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/secrets"
 
 	"github.com/google/uuid"
 )
@@ -113,13 +115,21 @@ type Service struct {
 	attachmentsBucket string
 	whatsapp          *whatsapp.Client
 	sse               *sse.Service
-	smtpEncryptionKey []byte
+	smtpKeyring       *secrets.Keyring
+	ssoKeyring        *secrets.Keyring
 	whatsappReplyer   WhatsAppReplySuggester
 	leadActions       WhatsAppLeadActions
+	workflowSimulator WorkflowSimulator
+	// workflowVariableCatalog lists the template variables per trigger.
+	workflowVariableCatalog WorkflowVariableCatalog
+	emailTemplateRenderer   EmailTemplateRenderer
+	// lookupTXT resolves DNS TXT records for portal domain verification and
+	// sender domain checks.
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
 }
 
 func New(repo *repository.Repository, leadsRepo *leadsrepo.Repository, eventBus events.Bus, storageSvc storage.StorageService, logoBucket string, whatsappClient *whatsapp.Client) *Service {
-	return &Service{repo: repo, leadsRepo: leadsRepo, eventBus: eventBus, storage: storageSvc, logoBucket: logoBucket, whatsapp: whatsappClient}
+	return &Service{repo: repo, leadsRepo: leadsRepo, eventBus: eventBus, storage: storageSvc, logoBucket: logoBucket, whatsapp: whatsappClient, lookupTXT: net.DefaultResolver.LookupTXT}
 }
 
 func (s *Service) SetWhatsAppReplySuggester(replyer WhatsAppReplySuggester) {
@@ -245,6 +255,15 @@ func (s *Service) CreateWorkflow(ctx context.Context, organizationID uuid.UUID,
 	if len(workflow.Steps) == 0 {
 		return repository.Workflow{}, apperr.Validation("workflow steps cannot be empty")
 	}
+	if err := validateWorkflowStepConditions(workflow.Steps); err != nil {
+		return repository.Workflow{}, err
+	}
+	if err := validateWorkflowStepsTranslations(workflow.Steps); err != nil {
+		return repository.Workflow{}, err
+	}
+	if err := validateWorkflowStepsVariants(workflow.Steps); err != nil {
+		return repository.Workflow{}, err
+	}
 	return s.repo.CreateWorkflow(ctx, organizationID, workflow)
 }
 
@@ -261,6 +280,15 @@ func (s *Service) ReplaceWorkflows(ctx context.Context, organizationID uuid.UUID
 		if len(wf.Steps) == 0 {
 			return nil, apperr.Validation("workflow steps cannot be empty")
 		}
+		if err := validateWorkflowStepConditions(wf.Steps); err != nil {
+			return nil, err
+		}
+		if err := validateWorkflowStepsTranslations(wf.Steps); err != nil {
+			return nil, err
+		}
+		if err := validateWorkflowStepsVariants(wf.Steps); err != nil {
+			return nil, err
+		}
 	}
 	normalized := normalizeWorkflowUpserts(workflows)
 	return s.repo.ReplaceWorkflows(ctx, organizationID, normalized)
@@ -273,10 +301,28 @@ func normalizeWorkflowUpserts(workflows []repository.WorkflowUpsert) []repositor
 }
 
 func (s *Service) CreateWorkflowStep(ctx context.Context, organizationID, workflowID uuid.UUID, step repository.WorkflowStepUpsert) (repository.WorkflowStep, error) {
+	if err := validateWorkflowStepCondition(step); err != nil {
+		return repository.WorkflowStep{}, err
+	}
+	if err := validateWorkflowStepTranslations(step); err != nil {
+		return repository.WorkflowStep{}, err
+	}
+	if err := validateWorkflowStepVariants(step); err != nil {
+		return repository.WorkflowStep{}, err
+	}
 	return s.repo.CreateWorkflowStep(ctx, organizationID, workflowID, step)
 }
 
 func (s *Service) UpdateWorkflowStep(ctx context.Context, organizationID, workflowID, stepID uuid.UUID, step repository.WorkflowStepUpsert) (repository.WorkflowStep, error) {
+	if err := validateWorkflowStepCondition(step); err != nil {
+		return repository.WorkflowStep{}, err
+	}
+	if err := validateWorkflowStepTranslations(step); err != nil {
+		return repository.WorkflowStep{}, err
+	}
+	if err := validateWorkflowStepVariants(step); err != nil {
+		return repository.WorkflowStep{}, err
+	}
 	return s.repo.UpdateWorkflowStep(ctx, organizationID, workflowID, stepID, step)
 }
 
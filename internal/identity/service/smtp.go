@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -14,22 +15,27 @@ import (
 	"time"
 
 	"portal_final_backend/internal/identity/repository"
-	"portal_final_backend/internal/identity/smtpcrypto"
 	"portal_final_backend/internal/identity/transport"
 	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/secrets"
 
 	"github.com/google/uuid"
 	gomail "github.com/wneessen/go-mail"
 )
 
-// SetSMTPEncryptionKey sets the AES-256 key used for encrypting SMTP passwords.
+// SetSMTPEncryptionKey sets a single legacy AES-256 key used for encrypting SMTP passwords.
 func (s *Service) SetSMTPEncryptionKey(key []byte) {
-	s.smtpEncryptionKey = key
+	s.smtpKeyring = secrets.SingleKeyring(key)
+}
+
+// SetSMTPKeyring sets the keyring used for encrypting and decrypting SMTP passwords.
+func (s *Service) SetSMTPKeyring(keyring *secrets.Keyring) {
+	s.smtpKeyring = keyring
 }
 
 // SetOrganizationSMTP stores encrypted SMTP configuration for the organization.
 func (s *Service) SetOrganizationSMTP(ctx context.Context, organizationID uuid.UUID, req transport.SetSMTPRequest) error {
-	if len(s.smtpEncryptionKey) == 0 {
+	if s.smtpKeyring == nil {
 		return apperr.Internal("SMTP encryption not configured")
 	}
 
@@ -37,7 +43,7 @@ func (s *Service) SetOrganizationSMTP(ctx context.Context, organizationID uuid.U
 
 	if req.Password != "" {
 		// New password provided — encrypt it.
-		enc, err := smtpcrypto.Encrypt(req.Password, s.smtpEncryptionKey)
+		enc, err := s.smtpKeyring.Encrypt(req.Password)
 		if err != nil {
 			return apperr.Internal("failed to encrypt SMTP password")
 		}
@@ -54,6 +60,10 @@ func (s *Service) SetOrganizationSMTP(ctx context.Context, organizationID uuid.U
 		encrypted = *settings.SMTPPassword
 	}
 
+	if err := s.requireSenderDomain(ctx, organizationID, req.FromEmail, req.DKIMSelector, req.AllowUnverifiedDomain); err != nil {
+		return err
+	}
+
 	_, err := s.repo.UpsertOrganizationSMTP(ctx, organizationID, repository.OrganizationSMTPUpdate{
 		SMTPHost:      req.Host,
 		SMTPPort:      req.Port,
@@ -76,14 +86,23 @@ func (s *Service) GetOrganizationSMTPStatus(ctx context.Context, organizationID
 		return transport.SMTPStatusResponse{Configured: false}, nil
 	}
 
-	return transport.SMTPStatusResponse{
+	status := transport.SMTPStatusResponse{
 		Configured: true,
 		Host:       settings.SMTPHost,
 		Port:       settings.SMTPPort,
 		Username:   settings.SMTPUsername,
 		FromEmail:  settings.SMTPFromEmail,
 		FromName:   settings.SMTPFromName,
-	}, nil
+	}
+	senderDomain, err := s.repo.GetOrganizationSenderDomain(ctx, organizationID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return transport.SMTPStatusResponse{}, err
+	}
+	if err == nil {
+		resp := toSenderDomainResponse(senderDomain)
+		status.SenderDomain = &resp
+	}
+	return status, nil
 }
 
 // ClearOrganizationSMTP removes the SMTP configuration for the organization.
@@ -607,7 +626,7 @@ func (s *Service) detectSMTPWithNetwork(ctx context.Context, domain, username st
 
 // TestOrganizationSMTP sends a test email using the stored SMTP configuration.
 func (s *Service) TestOrganizationSMTP(ctx context.Context, organizationID uuid.UUID, toEmail string) error {
-	if len(s.smtpEncryptionKey) == 0 {
+	if s.smtpKeyring == nil {
 		return apperr.Internal("SMTP encryption not configured")
 	}
 
@@ -622,7 +641,7 @@ func (s *Service) TestOrganizationSMTP(ctx context.Context, organizationID uuid.
 		return apperr.Validation("SMTP password missing")
 	}
 
-	password, err := smtpcrypto.Decrypt(*settings.SMTPPassword, s.smtpEncryptionKey)
+	password, err := s.smtpKeyring.Decrypt(*settings.SMTPPassword)
 	if err != nil {
 		return apperr.Internal("failed to decrypt SMTP password")
 	}
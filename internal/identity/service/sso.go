@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"portal_final_backend/internal/auth/token"
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/secrets"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ssoNotConfiguredMsg        = "single sign-on not configured"
+	ssoDomainRecordPrefix      = "_sso-verification."
+	ssoDomainRecordValuePrefix = "sso-verification="
+)
+
+// ssoAssignableRoles are the roles SSO may grant. superadmin is never granted
+// through an identity provider.
+var ssoAssignableRoles = []string{"admin", "user", "agent", "scout", "partner"}
+
+// SetSSOKeyring sets the keyring used for encrypting SSO client secrets.
+func (s *Service) SetSSOKeyring(keyring *secrets.Keyring) {
+	s.ssoKeyring = keyring
+}
+
+// GetOrganizationSSOConfig returns the SSO config of an organization, with
+// the client secret left encrypted.
+func (s *Service) GetOrganizationSSOConfig(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationSSOConfig, error) {
+	config, err := s.repo.GetOrganizationSSOConfig(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.OrganizationSSOConfig{}, apperr.NotFound(ssoNotConfiguredMsg)
+	}
+	return config, err
+}
+
+// SSODomainVerificationRecord returns the TXT record an organization has to
+// publish to use SSO for the email addresses of a domain.
+func SSODomainVerificationRecord(domain repository.OrganizationSSODomain) DomainVerificationRecord {
+	return DomainVerificationRecord{
+		Name:  ssoDomainRecordPrefix + domain.Domain,
+		Value: ssoDomainRecordValuePrefix + domain.VerificationToken,
+	}
+}
+
+// UpdateOrganizationSSOConfig validates and stores the SSO config of an
+// organization. config.ClientSecret is the plain secret; when empty the
+// stored secret is kept. Newly allowed domains need VerifySSODomains before
+// SSO applies to them.
+func (s *Service) UpdateOrganizationSSOConfig(ctx context.Context, organizationID uuid.UUID, config repository.OrganizationSSOConfig) (repository.OrganizationSSOConfig, error) {
+	if s.ssoKeyring == nil {
+		return repository.OrganizationSSOConfig{}, apperr.Internal("SSO encryption not configured")
+	}
+	config.OrganizationID = organizationID
+	if err := normalizeSSOConfig(&config); err != nil {
+		return repository.OrganizationSSOConfig{}, err
+	}
+
+	if config.ClientSecret != "" {
+		encrypted, err := s.ssoKeyring.Encrypt(config.ClientSecret)
+		if err != nil {
+			return repository.OrganizationSSOConfig{}, apperr.Internal("failed to encrypt SSO client secret")
+		}
+		config.ClientSecret = encrypted
+	} else {
+		existing, err := s.repo.GetOrganizationSSOConfig(ctx, organizationID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return repository.OrganizationSSOConfig{}, apperr.Validation("client secret is required for initial SSO configuration")
+		}
+		if err != nil {
+			return repository.OrganizationSSOConfig{}, err
+		}
+		config.ClientSecret = existing.ClientSecret
+	}
+
+	tokens := make([]string, len(config.AllowedDomains))
+	for i := range tokens {
+		verificationToken, err := token.GenerateRandomToken(portalDomainTokenBytes)
+		if err != nil {
+			return repository.OrganizationSSOConfig{}, err
+		}
+		tokens[i] = verificationToken
+	}
+	return s.repo.UpsertOrganizationSSOConfig(ctx, config, tokens)
+}
+
+// VerifySSODomains looks up the TXT record of every allowed SSO domain and
+// records whether it holds the verification token.
+func (s *Service) VerifySSODomains(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationSSOConfig, error) {
+	config, err := s.GetOrganizationSSOConfig(ctx, organizationID)
+	if err != nil {
+		return repository.OrganizationSSOConfig{}, err
+	}
+
+	for _, domain := range config.Domains {
+		record := SSODomainVerificationRecord(domain)
+		var checkErr *string
+		values, err := s.lookupTXT(ctx, record.Name)
+		verified := err == nil && slices.Contains(values, record.Value)
+		if !verified {
+			msg := "verification record not found"
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) && !dnsErr.IsNotFound {
+				msg = "DNS lookup failed: " + dnsErr.Err
+			}
+			checkErr = &msg
+		}
+		err = s.repo.RecordSSODomainCheck(ctx, organizationID, domain.Domain, verified, checkErr)
+		if apperr.Is(err, apperr.KindConflict) {
+			msg := "domain is already verified by another organization"
+			err = s.repo.RecordSSODomainCheck(ctx, organizationID, domain.Domain, false, &msg)
+		}
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return repository.OrganizationSSOConfig{}, err
+		}
+	}
+	return s.GetOrganizationSSOConfig(ctx, organizationID)
+}
+
+// DeleteOrganizationSSOConfig removes the SSO config of an organization,
+// which re-enables password sign-in.
+func (s *Service) DeleteOrganizationSSOConfig(ctx context.Context, organizationID uuid.UUID) error {
+	err := s.repo.DeleteOrganizationSSOConfig(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.NotFound(ssoNotConfiguredMsg)
+	}
+	return err
+}
+
+// ResolveSSOProvider returns the enabled SSO config of an organization with
+// its client secret decrypted.
+func (s *Service) ResolveSSOProvider(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationSSOConfig, error) {
+	config, err := s.repo.GetOrganizationSSOConfig(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !config.Enabled) {
+		return repository.OrganizationSSOConfig{}, apperr.NotFound(ssoNotConfiguredMsg)
+	}
+	if err != nil {
+		return repository.OrganizationSSOConfig{}, err
+	}
+	return s.decryptSSOConfig(config)
+}
+
+// ResolveSSOProviderForEmail returns the enabled SSO config of the
+// organization that verified the domain of email, with its client secret
+// decrypted.
+func (s *Service) ResolveSSOProviderForEmail(ctx context.Context, email string) (repository.OrganizationSSOConfig, error) {
+	_, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok || domain == "" {
+		return repository.OrganizationSSOConfig{}, apperr.Validation("invalid email address")
+	}
+	config, err := s.repo.GetOrganizationSSOConfigByDomain(ctx, domain)
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.OrganizationSSOConfig{}, apperr.NotFound(ssoNotConfiguredMsg)
+	}
+	if err != nil {
+		return repository.OrganizationSSOConfig{}, err
+	}
+	return s.decryptSSOConfig(config)
+}
+
+// IsSSOEnforced reports whether members of an organization must sign in
+// through SSO.
+func (s *Service) IsSSOEnforced(ctx context.Context, organizationID uuid.UUID) (bool, error) {
+	config, err := s.repo.GetOrganizationSSOConfig(ctx, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return config.Enabled && config.EnforceSSO, nil
+}
+
+func (s *Service) decryptSSOConfig(config repository.OrganizationSSOConfig) (repository.OrganizationSSOConfig, error) {
+	if s.ssoKeyring == nil {
+		return repository.OrganizationSSOConfig{}, apperr.Internal("SSO encryption not configured")
+	}
+	secret, err := s.ssoKeyring.Decrypt(config.ClientSecret)
+	if err != nil {
+		return repository.OrganizationSSOConfig{}, apperr.Internal("failed to decrypt SSO client secret")
+	}
+	config.ClientSecret = secret
+	return config, nil
+}
+
+func normalizeSSOConfig(config *repository.OrganizationSSOConfig) error {
+	config.IssuerURL = strings.TrimSpace(config.IssuerURL)
+	issuer, err := url.Parse(config.IssuerURL)
+	if err != nil || issuer.Scheme != "https" || issuer.Host == "" {
+		return apperr.Validation("issuer must be an https URL")
+	}
+	config.ClientID = strings.TrimSpace(config.ClientID)
+	if config.ClientID == "" {
+		return apperr.Validation("client id is required")
+	}
+	config.ClientSecret = strings.TrimSpace(config.ClientSecret)
+
+	domains := make([]string, 0, len(config.AllowedDomains))
+	for _, domain := range config.AllowedDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || strings.ContainsAny(domain, "@/ ") || !strings.Contains(domain, ".") {
+			return apperr.Validation("invalid email domain").WithDetails(domain)
+		}
+		if !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+	if len(domains) == 0 {
+		return apperr.Validation("at least one email domain is required")
+	}
+	config.AllowedDomains = domains
+
+	if config.RoleClaim != nil {
+		claim := strings.TrimSpace(*config.RoleClaim)
+		config.RoleClaim = &claim
+		if claim == "" {
+			config.RoleClaim = nil
+		}
+	}
+	config.DefaultRole = strings.TrimSpace(config.DefaultRole)
+	if config.DefaultRole == "" {
+		config.DefaultRole = "user"
+	}
+	if !slices.Contains(ssoAssignableRoles, config.DefaultRole) {
+		return apperr.Validation("default role cannot be assigned through SSO").WithDetails(config.DefaultRole)
+	}
+	mappings := make(map[string]string, len(config.RoleMappings))
+	for value, role := range config.RoleMappings {
+		value, role = strings.TrimSpace(value), strings.TrimSpace(role)
+		if value == "" {
+			continue
+		}
+		if !slices.Contains(ssoAssignableRoles, role) {
+			return apperr.Validation("role cannot be assigned through SSO").WithDetails(role)
+		}
+		mappings[value] = role
+	}
+	config.RoleMappings = mappings
+
+	if config.EnforceSSO && !config.Enabled {
+		return apperr.Validation("SSO must be enabled to enforce it")
+	}
+	return nil
+}
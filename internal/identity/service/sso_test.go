@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+)
+
+func TestSSOConfigOnlyAllowsVerifiedDomains(t *testing.T) {
+	verifiedAt := time.Now()
+	config := repository.OrganizationSSOConfig{
+		AllowedDomains: []string{"example.nl", "claimed.nl"},
+		Domains: []repository.OrganizationSSODomain{
+			{Domain: "example.nl", VerificationToken: "abc", VerifiedAt: &verifiedAt},
+			{Domain: "claimed.nl", VerificationToken: "def"},
+		},
+	}
+
+	if !config.AllowsDomain("example.nl") {
+		t.Error("verified domain not allowed")
+	}
+	if config.AllowsDomain("claimed.nl") {
+		t.Error("unverified domain allowed")
+	}
+	if config.AllowsDomain("gmail.com") {
+		t.Error("unlisted domain allowed")
+	}
+
+	record := SSODomainVerificationRecord(config.Domains[1])
+	if record.Name != "_sso-verification.claimed.nl" || record.Value != "sso-verification=def" {
+		t.Errorf("verification record = %+v", record)
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"portal_final_backend/internal/identity/repository"
+
+	"github.com/google/uuid"
+)
+
+func (s *Service) GetOrganizationStaleLeadSettings(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationStaleLeadSettings, error) {
+	return s.repo.GetOrganizationStaleLeadSettings(ctx, organizationID)
+}
+
+// UpdateOrganizationStaleLeadSettings replaces the stale lead thresholds of
+// an organization. Thresholds left at zero fall back to the defaults.
+func (s *Service) UpdateOrganizationStaleLeadSettings(ctx context.Context, organizationID uuid.UUID, settings repository.OrganizationStaleLeadSettings) (repository.OrganizationStaleLeadSettings, error) {
+	settings.OrganizationID = organizationID
+	settings.NoActivityDays = daysOrDefault(settings.NoActivityDays, repository.DefaultStaleNoActivityDays)
+	settings.NurturingDays = daysOrDefault(settings.NurturingDays, repository.DefaultStaleNurturingDays)
+	settings.NoQuoteDays = daysOrDefault(settings.NoQuoteDays, repository.DefaultStaleNoQuoteDays)
+	settings.DraftQuoteDays = daysOrDefault(settings.DraftQuoteDays, repository.DefaultStaleDraftQuoteDays)
+	settings.RescheduleDays = daysOrDefault(settings.RescheduleDays, repository.DefaultStaleRescheduleDays)
+	return s.repo.UpsertOrganizationStaleLeadSettings(ctx, settings)
+}
+
+func daysOrDefault(days, fallback int) int {
+	if days <= 0 {
+		return fallback
+	}
+	return days
+}
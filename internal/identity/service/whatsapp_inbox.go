@@ -741,6 +741,7 @@ func (s *Service) ReceiveIncomingWhatsAppMessage(ctx context.Context, input webh
 		return false, nil
 	}
 
+	s.confirmMarketingFromReply(ctx, conversation, input.Body)
 	s.publishWhatsAppMessageReceived(ctx, input.OrganizationID, conversation, message)
 	s.publishWhatsAppConversationUpdated(input.OrganizationID, conversation)
 	return true, nil
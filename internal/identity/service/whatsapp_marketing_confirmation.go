@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"portal_final_backend/internal/identity/repository"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+)
+
+// marketingConfirmationReplies are the WhatsApp replies that confirm a
+// pending marketing consent of the lead.
+var marketingConfirmationReplies = map[string]bool{
+	"ja":  true,
+	"yes": true,
+}
+
+func isMarketingConfirmationReply(body string) bool {
+	reply := strings.ToLower(strings.Trim(strings.TrimSpace(body), ".!"))
+	return marketingConfirmationReplies[reply]
+}
+
+// confirmMarketingFromReply confirms the marketing consent of the lead of the
+// conversation when it replies "JA" to the double opt-in request.
+func (s *Service) confirmMarketingFromReply(ctx context.Context, conversation repository.WhatsAppConversation, body string) {
+	if s.leadsRepo == nil || conversation.LeadID == nil || !isMarketingConfirmationReply(body) {
+		return
+	}
+	confirmed, err := s.leadsRepo.ConfirmPendingMarketing(ctx, *conversation.LeadID, conversation.OrganizationID, leadsrepo.ConsentChangeOrigin{})
+	if err != nil || !confirmed {
+		return
+	}
+	summary := "Klant heeft aanmelding voor aanbiedingen en nieuws bevestigd via WhatsApp"
+	_, _ = s.leadsRepo.CreateTimelineEvent(ctx, leadsrepo.CreateTimelineEventParams{
+		LeadID:         *conversation.LeadID,
+		OrganizationID: conversation.OrganizationID,
+		ActorType:      leadsrepo.ActorTypeLead,
+		ActorName:      leadsrepo.ActorNameKlant,
+		EventType:      leadsrepo.EventTypeMarketingConfirmed,
+		Title:          leadsrepo.EventTitleMarketingConfirmed,
+		Summary:        &summary,
+	})
+}
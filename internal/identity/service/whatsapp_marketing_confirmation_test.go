@@ -0,0 +1,16 @@
+package service
+
+import "testing"
+
+func TestIsMarketingConfirmationReply(t *testing.T) {
+	for _, body := range []string{"JA", " ja ", "Ja!", "yes."} {
+		if !isMarketingConfirmationReply(body) {
+			t.Errorf("expected %q to confirm", body)
+		}
+	}
+	for _, body := range []string{"", "nee", "ja graag meer info", "STOP"} {
+		if isMarketingConfirmationReply(body) {
+			t.Errorf("expected %q not to confirm", body)
+		}
+	}
+}
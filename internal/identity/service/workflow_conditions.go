@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/internal/identity/workflowcondition"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// WorkflowConditionVariablesInput selects the lead a send condition is tested
+// against.
+type WorkflowConditionVariablesInput struct {
+	OrganizationID uuid.UUID
+	Trigger        string
+	LeadID         *uuid.UUID
+	SampleLead     *WorkflowSampleLead
+	Variables      map[string]any
+}
+
+type TestWorkflowConditionInput struct {
+	Condition  string
+	Trigger    string
+	LeadID     *uuid.UUID
+	SampleLead *WorkflowSampleLead
+	Variables  map[string]any
+}
+
+type TestWorkflowConditionResult struct {
+	Valid     bool
+	Error     string
+	Matched   bool
+	Variables map[string]any
+}
+
+// TestWorkflowCondition evaluates a send condition against a lead, or against
+// the given variables when no lead is selected.
+func (s *Service) TestWorkflowCondition(ctx context.Context, organizationID uuid.UUID, input TestWorkflowConditionInput) (TestWorkflowConditionResult, error) {
+	condition, err := workflowcondition.Parse(input.Condition)
+	if err != nil {
+		return TestWorkflowConditionResult{Valid: false, Error: err.Error()}, nil
+	}
+
+	vars := input.Variables
+	if s.workflowSimulator != nil {
+		vars, err = s.workflowSimulator.WorkflowConditionVariables(ctx, WorkflowConditionVariablesInput{
+			OrganizationID: organizationID,
+			Trigger:        input.Trigger,
+			LeadID:         input.LeadID,
+			SampleLead:     input.SampleLead,
+			Variables:      input.Variables,
+		})
+		if err != nil {
+			return TestWorkflowConditionResult{}, err
+		}
+	}
+
+	return TestWorkflowConditionResult{Valid: true, Matched: condition.Matches(vars), Variables: vars}, nil
+}
+
+func validateWorkflowStepConditions(steps []repository.WorkflowStepUpsert) error {
+	for _, step := range steps {
+		if err := validateWorkflowStepCondition(step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateWorkflowStepCondition(step repository.WorkflowStepUpsert) error {
+	if step.SendCondition == nil {
+		return nil
+	}
+	if err := workflowcondition.Validate(*step.SendCondition); err != nil {
+		return apperr.Validation(fmt.Sprintf("invalid send condition for step %d: %s", step.StepOrder, err.Error()))
+	}
+	return nil
+}
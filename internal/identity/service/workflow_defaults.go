@@ -90,6 +90,31 @@ func buildDefaultWorkflowSteps() []repository.WorkflowStepUpsert {
 		newDefaultWorkflowStep(21, "job_completed", "email", "lead", leadRecipients,
 			stringPtr("Het werk is afgerond – laat een review achter"),
 			"Hallo {{lead.name}},\n\nHet werk is afgerond! We hopen dat je tevreden bent met het resultaat.\n\nWe zouden het erg waarderen als je een review achterlaat via: {{org.reviewUrl}}\n\nMet vriendelijke groet,\n{{org.name}}"),
+		newDefaultWorkflowStep(22, "quote_installment_due", "whatsapp", "lead", leadRecipients, nil,
+			"Hallo {{lead.name}}, de termijn \"{{installment.label}}\" van {{installment.amount}} voor offerte {{quote.number}} is verschuldigd per {{installment.dueDate}}."),
+		newDefaultWorkflowStep(23, "quote_installment_due", "email", "lead", leadRecipients,
+			stringPtr("Betalingstermijn offerte {{quote.number}}"),
+			"Hallo {{lead.name}},\n\nDe termijn \"{{installment.label}}\" van {{installment.amount}} voor offerte {{quote.number}} is verschuldigd per {{installment.dueDate}}.\n\nMet vriendelijke groet,\n{{org.name}}"),
+		newDefaultWorkflowStep(24, "appointment_updated", "whatsapp", "lead", leadRecipients, nil,
+			"Hallo {{lead.name}}, je afspraak is gewijzigd. De nieuwe afspraak is op {{appointment.date}} om {{appointment.time}}."),
+		newDefaultWorkflowStep(25, "appointment_updated", "email", "lead", leadRecipients,
+			stringPtr("Afspraak gewijzigd naar {{appointment.date}}"),
+			"Hallo {{lead.name}},\n\nJe afspraak is gewijzigd. De nieuwe afspraak is op {{appointment.date}} om {{appointment.time}}. De bijgevoegde agenda-uitnodiging werkt je agenda bij.\n\nMet vriendelijke groet,\n{{org.name}}"),
+		newDefaultWorkflowStep(26, "lead_stale", "whatsapp", "lead", leadRecipients, nil,
+			"Hallo {{lead.name}}, we hebben al even niets van je gehoord over je aanvraag voor {{lead.serviceType}}. Heb je nog vragen of zullen we een afspraak inplannen? Reageer gerust op dit bericht."),
+		newDefaultWorkflowStep(27, "lead_stale", "email", "lead", leadRecipients,
+			stringPtr("Nog interesse in {{lead.serviceType}}?"),
+			"Hallo {{lead.name}},\n\nWe hebben al even niets van je gehoord over je aanvraag voor {{lead.serviceType}}. Heb je nog vragen, of zullen we een afspraak inplannen? Reageer gerust op deze e-mail.\n\nMet vriendelijke groet,\n{{org.name}}"),
+		newDefaultWorkflowStep(28, "work_order_completed", "whatsapp", "lead", leadRecipients, nil,
+			"Hallo {{lead.name}}, de werkzaamheden voor {{lead.serviceType}} zijn afgerond. Wil je bevestigen dat alles naar wens is? {{links.confirm}}"),
+		newDefaultWorkflowStep(29, "work_order_completed", "email", "lead", leadRecipients,
+			stringPtr("Bevestig de afgeronde werkzaamheden"),
+			"Hallo {{lead.name}},\n\nDe werkzaamheden voor {{lead.serviceType}} zijn afgerond. Wil je via onderstaande link bevestigen dat alles naar wens is uitgevoerd?\n\n{{links.confirm}}\n\nMet vriendelijke groet,\n{{org.name}}"),
+		newDefaultWorkflowStep(30, "warranty_expiring", "whatsapp", "lead", leadRecipients, nil,
+			"Hallo {{lead.name}}, de garantie op {{lead.serviceType}} loopt af op {{warranty.expiresOn}}. Zullen we een onderhoudsbeurt inplannen? Reageer gerust op dit bericht."),
+		newDefaultWorkflowStep(31, "warranty_expiring", "email", "lead", leadRecipients,
+			stringPtr("Je garantie loopt binnenkort af"),
+			"Hallo {{lead.name}},\n\nDe garantie op {{lead.serviceType}} loopt af op {{warranty.expiresOn}}. Dit is een goed moment voor een onderhoudsbeurt of controle, zodat alles in goede staat blijft. Reageer op deze e-mail om een afspraak in te plannen.\n\nMet vriendelijke groet,\n{{org.name}}"),
 	}
 }
 
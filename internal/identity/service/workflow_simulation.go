@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// WorkflowSimulator renders workflow steps the way the notification engine
+// would send them, without sending anything.
+type WorkflowSimulator interface {
+	SimulateWorkflowSteps(ctx context.Context, input SimulateWorkflowStepsInput) ([]SimulatedWorkflowStep, error)
+	WorkflowConditionVariables(ctx context.Context, input WorkflowConditionVariablesInput) (map[string]any, error)
+}
+
+// WorkflowSampleLead is the lead a simulated workflow is rendered for.
+type WorkflowSampleLead struct {
+	FirstName   string
+	LastName    string
+	Phone       string
+	Email       string
+	Street      string
+	HouseNumber string
+	ZipCode     string
+	City        string
+	ServiceType string
+}
+
+type SimulateWorkflowStepsInput struct {
+	OrganizationID uuid.UUID
+	Steps          []repository.WorkflowStep
+	// LeadID renders the steps for an existing lead; SampleLead is used otherwise.
+	LeadID     *uuid.UUID
+	SampleLead *WorkflowSampleLead
+	// Variables override template variables, such as a quote number.
+	Variables map[string]any
+	StartAt   time.Time
+	// Language overrides the language resolved for the lead when set.
+	Language *string
+}
+
+type SimulatedWorkflowStep struct {
+	StepID              *uuid.UUID
+	StepOrder           int
+	Trigger             string
+	Channel             string
+	Audience            string
+	Language            string
+	Enabled             bool
+	DelayMinutes        int
+	SendAt              time.Time
+	Subject             string
+	Body                string
+	Recipients          []string
+	TemplateErrors      []string
+	UnresolvedVariables []string
+	EmptyVariables      []string
+	SendCondition       *string
+	ConditionMet        bool
+	Skipped             bool
+	SkipReason          string
+}
+
+// SimulateWorkflowInput selects the workflow to simulate: a stored workflow
+// or an unsaved definition from the editor.
+type SimulateWorkflowInput struct {
+	WorkflowID *uuid.UUID
+	Workflow   *repository.WorkflowUpsert
+	Trigger    string
+	LeadID     *uuid.UUID
+	SampleLead *WorkflowSampleLead
+	Variables  map[string]any
+	StartAt    *time.Time
+	Language   *string
+}
+
+type SimulateWorkflowResult struct {
+	Valid bool
+	Steps []SimulatedWorkflowStep
+}
+
+func (s *Service) SetWorkflowSimulator(simulator WorkflowSimulator) {
+	s.workflowSimulator = simulator
+}
+
+// SimulateWorkflow renders every step of a workflow for a lead and reports
+// template errors, unresolved variables and when each step would be sent.
+func (s *Service) SimulateWorkflow(ctx context.Context, organizationID uuid.UUID, input SimulateWorkflowInput) (SimulateWorkflowResult, error) {
+	if s.workflowSimulator == nil {
+		return SimulateWorkflowResult{}, apperr.Internal("workflow simulation is not configured")
+	}
+	if (input.WorkflowID == nil) == (input.Workflow == nil) {
+		return SimulateWorkflowResult{}, apperr.Validation("provide either workflowId or workflow")
+	}
+
+	var steps []repository.WorkflowStep
+	if input.WorkflowID != nil {
+		workflow, err := s.repo.GetWorkflow(ctx, *input.WorkflowID, organizationID)
+		if err != nil {
+			return SimulateWorkflowResult{}, err
+		}
+		steps = workflow.Steps
+	} else {
+		steps = workflowStepsFromUpsert(organizationID, input.Workflow.Steps)
+	}
+	steps = filterWorkflowStepsByTrigger(steps, input.Trigger)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].StepOrder < steps[j].StepOrder })
+
+	startAt := time.Now().UTC()
+	if input.StartAt != nil {
+		startAt = input.StartAt.UTC()
+	}
+
+	simulated, err := s.workflowSimulator.SimulateWorkflowSteps(ctx, SimulateWorkflowStepsInput{
+		OrganizationID: organizationID,
+		Steps:          steps,
+		LeadID:         input.LeadID,
+		SampleLead:     input.SampleLead,
+		Variables:      input.Variables,
+		StartAt:        startAt,
+		Language:       input.Language,
+	})
+	if err != nil {
+		return SimulateWorkflowResult{}, err
+	}
+
+	valid := true
+	for _, step := range simulated {
+		if len(step.TemplateErrors) > 0 || len(step.UnresolvedVariables) > 0 {
+			valid = false
+		}
+	}
+	return SimulateWorkflowResult{Valid: valid, Steps: simulated}, nil
+}
+
+func workflowStepsFromUpsert(organizationID uuid.UUID, upserts []repository.WorkflowStepUpsert) []repository.WorkflowStep {
+	steps := make([]repository.WorkflowStep, 0, len(upserts))
+	for _, upsert := range upserts {
+		step := repository.WorkflowStep{
+			OrganizationID:       organizationID,
+			Trigger:              upsert.Trigger,
+			Channel:              upsert.Channel,
+			Audience:             upsert.Audience,
+			Action:               upsert.Action,
+			StepOrder:            upsert.StepOrder,
+			DelayMinutes:         upsert.DelayMinutes,
+			Enabled:              upsert.Enabled,
+			RecipientConfig:      upsert.RecipientConfig,
+			TemplateSubject:      upsert.TemplateSubject,
+			TemplateBody:         upsert.TemplateBody,
+			StopOnReply:          upsert.StopOnReply,
+			SendCondition:        upsert.SendCondition,
+			TemplateTranslations: upsert.TemplateTranslations,
+			TemplateVariants:     upsert.TemplateVariants,
+		}
+		if upsert.ID != nil {
+			step.ID = *upsert.ID
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func filterWorkflowStepsByTrigger(steps []repository.WorkflowStep, trigger string) []repository.WorkflowStep {
+	trigger = strings.TrimSpace(trigger)
+	if trigger == "" {
+		return append([]repository.WorkflowStep(nil), steps...)
+	}
+	filtered := make([]repository.WorkflowStep, 0, len(steps))
+	for _, step := range steps {
+		if strings.EqualFold(step.Trigger, trigger) {
+			filtered = append(filtered, step)
+		}
+	}
+	return filtered
+}
+
+// WorkflowVariableCatalog lists the template variables the notification
+// engine provides to the steps of each trigger.
+type WorkflowVariableCatalog interface {
+	WorkflowVariableCatalog() []WorkflowTriggerVariables
+}
+
+type WorkflowVariable struct {
+	Path    string
+	Type    string
+	Example string
+	// Source is "base" for variables every trigger has and "event" for
+	// variables the trigger's event adds.
+	Source string
+}
+
+type WorkflowTriggerVariables struct {
+	Trigger   string
+	Variables []WorkflowVariable
+}
+
+func (s *Service) SetWorkflowVariableCatalog(catalog WorkflowVariableCatalog) {
+	s.workflowVariableCatalog = catalog
+}
+
+// ListWorkflowVariables returns the variable catalog, limited to trigger when
+// it is set.
+func (s *Service) ListWorkflowVariables(trigger string) ([]WorkflowTriggerVariables, error) {
+	if s.workflowVariableCatalog == nil {
+		return nil, apperr.Internal("workflow variable catalog is not configured")
+	}
+	catalog := s.workflowVariableCatalog.WorkflowVariableCatalog()
+	trigger = strings.TrimSpace(trigger)
+	if trigger == "" {
+		return catalog, nil
+	}
+	for _, entry := range catalog {
+		if strings.EqualFold(entry.Trigger, trigger) {
+			return []WorkflowTriggerVariables{entry}, nil
+		}
+	}
+	return nil, apperr.Validation("unknown workflow trigger")
+}
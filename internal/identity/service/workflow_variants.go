@@ -0,0 +1,46 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/apperr"
+)
+
+const minWorkflowStepVariants = 2
+
+func validateWorkflowStepsVariants(steps []repository.WorkflowStepUpsert) error {
+	for _, step := range steps {
+		if err := validateWorkflowStepVariants(step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateWorkflowStepVariants requires an A/B test to have at least two
+// variants with distinct keys and positive weights.
+func validateWorkflowStepVariants(step repository.WorkflowStepUpsert) error {
+	if len(step.TemplateVariants) == 0 {
+		return nil
+	}
+	if len(step.TemplateVariants) < minWorkflowStepVariants {
+		return apperr.Validation(fmt.Sprintf("step %d needs at least %d template variants", step.StepOrder, minWorkflowStepVariants))
+	}
+	seen := make(map[string]bool, len(step.TemplateVariants))
+	for _, variant := range step.TemplateVariants {
+		key := strings.TrimSpace(variant.Key)
+		if key == "" || key != variant.Key {
+			return apperr.Validation(fmt.Sprintf("invalid template variant key %q for step %d", variant.Key, step.StepOrder))
+		}
+		if seen[key] {
+			return apperr.Validation(fmt.Sprintf("duplicate template variant key %q for step %d", key, step.StepOrder))
+		}
+		seen[key] = true
+		if variant.Weight <= 0 {
+			return apperr.Validation(fmt.Sprintf("template variant %q of step %d needs a positive weight", key, step.StepOrder))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package transport
+
+import "time"
+
+// UpdateOrganizationAISettingsRequest replaces the AI settings; omitted fields reset to the platform defaults.
+type UpdateOrganizationAISettingsRequest struct {
+	Provider           *string  `json:"provider" validate:"omitempty,max=50"`
+	Model              *string  `json:"model" validate:"omitempty,max=100"`
+	Temperature        *float64 `json:"temperature" validate:"omitempty,gte=0,lte=2"`
+	MonthlyTokenBudget *int64   `json:"monthlyTokenBudget" validate:"omitempty,gt=0"`
+}
+
+type AIProviderOption struct {
+	ID     string   `json:"id"`
+	Models []string `json:"models"`
+}
+
+type OrganizationAISettingsResponse struct {
+	Provider           *string            `json:"provider"`
+	Model              *string            `json:"model"`
+	Temperature        *float64           `json:"temperature"`
+	MonthlyTokenBudget *int64             `json:"monthlyTokenBudget"`
+	UpdatedAt          *time.Time         `json:"updatedAt,omitempty"`
+	AvailableProviders []AIProviderOption `json:"availableProviders"`
+}
+
+type AIUsageModelItem struct {
+	Kind             string  `json:"kind"`
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Calls            int64   `json:"calls"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	EstimatedCostUSD float64 `json:"estimatedCostUsd"`
+}
+
+type OrganizationAIUsageResponse struct {
+	PeriodStart        time.Time          `json:"periodStart"`
+	ChatTokens         int64              `json:"chatTokens"`
+	EmbeddingTokens    int64              `json:"embeddingTokens"`
+	EstimatedCostUSD   float64            `json:"estimatedCostUsd"`
+	MonthlyTokenBudget *int64             `json:"monthlyTokenBudget"`
+	RemainingTokens    *int64             `json:"remainingTokens"`
+	BudgetExceeded     bool               `json:"budgetExceeded"`
+	ByModel            []AIUsageModelItem `json:"byModel"`
+}
+
+// AIUsageReportQuery selects the UTC days of a usage report; both dates are inclusive.
+type AIUsageReportQuery struct {
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type AIUsageDayItem struct {
+	Date             string  `json:"date"`
+	Kind             string  `json:"kind"`
+	Calls            int64   `json:"calls"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	EstimatedCostUSD float64 `json:"estimatedCostUsd"`
+}
+
+type AIUsageReportResponse struct {
+	OrganizationID   string           `json:"organizationId"`
+	From             string           `json:"from"`
+	To               string           `json:"to"`
+	Calls            int64            `json:"calls"`
+	PromptTokens     int64            `json:"promptTokens"`
+	CompletionTokens int64            `json:"completionTokens"`
+	EstimatedCostUSD float64          `json:"estimatedCostUsd"`
+	Days             []AIUsageDayItem `json:"days"`
+}
@@ -0,0 +1,22 @@
+package transport
+
+import "time"
+
+// UpdateBrandingRequest replaces the branding of an organization. Omitted or
+// empty fields fall back to the platform defaults.
+type UpdateBrandingRequest struct {
+	PrimaryColor      *string `json:"primaryColor" validate:"omitempty,max=7"`
+	AccentColor       *string `json:"accentColor" validate:"omitempty,max=7"`
+	EmailHeaderHTML   *string `json:"emailHeaderHtml" validate:"omitempty,max=10000"`
+	EmailFooterHTML   *string `json:"emailFooterHtml" validate:"omitempty,max=10000"`
+	SenderDisplayName *string `json:"senderDisplayName" validate:"omitempty,max=100"`
+}
+
+type BrandingResponse struct {
+	PrimaryColor      *string    `json:"primaryColor"`
+	AccentColor       *string    `json:"accentColor"`
+	EmailHeaderHTML   *string    `json:"emailHeaderHtml"`
+	EmailFooterHTML   *string    `json:"emailFooterHtml"`
+	SenderDisplayName *string    `json:"senderDisplayName"`
+	UpdatedAt         *time.Time `json:"updatedAt,omitempty"`
+}
@@ -186,6 +186,12 @@ type SetSMTPRequest struct {
 	Password  string `json:"password" validate:"omitempty,max=500"`
 	FromEmail string `json:"fromEmail" validate:"required,email,max=255"`
 	FromName  string `json:"fromName" validate:"required,max=120"`
+	// DKIMSelector names the DKIM key of the from domain when it is not one
+	// of the common selectors.
+	DKIMSelector string `json:"dkimSelector,omitempty" validate:"omitempty,max=63"`
+	// AllowUnverifiedDomain enables SMTP although the from domain fails the
+	// SPF, DKIM or DMARC checks.
+	AllowUnverifiedDomain bool `json:"allowUnverifiedDomain"`
 }
 
 // SMTPStatusResponse returns the current SMTP configuration status (password is never exposed).
@@ -196,6 +202,9 @@ type SMTPStatusResponse struct {
 	Username   *string `json:"username,omitempty"`
 	FromEmail  *string `json:"fromEmail,omitempty"`
 	FromName   *string `json:"fromName,omitempty"`
+	// SenderDomain is the last DNS check of the from domain, with warnings
+	// for records that put delivery at risk.
+	SenderDomain *SenderDomainResponse `json:"senderDomain,omitempty"`
 }
 
 // TestSMTPRequest is the request to send a test email via the configured SMTP.
@@ -229,6 +238,23 @@ type WorkflowStepRecipientConfig struct {
 	CustomPhones         []string `json:"customPhones,omitempty" validate:"omitempty,dive,min=6,max=50"`
 }
 
+// WorkflowStepTranslation is a language variant of a step's templates. Omitted
+// fields fall back to the step's base template.
+type WorkflowStepTranslation struct {
+	TemplateSubject *string `json:"templateSubject,omitempty" validate:"omitempty,max=500"`
+	TemplateBody    *string `json:"templateBody,omitempty" validate:"omitempty,max=12000"`
+}
+
+// WorkflowStepVariant is an A/B test variant of a step's templates. Leads are
+// split over the variants in proportion to weight; omitted fields fall back
+// to the step's base template.
+type WorkflowStepVariant struct {
+	Key             string  `json:"key" validate:"required,max=20"`
+	Weight          int     `json:"weight" validate:"min=1,max=100"`
+	TemplateSubject *string `json:"templateSubject,omitempty" validate:"omitempty,max=500"`
+	TemplateBody    *string `json:"templateBody,omitempty" validate:"omitempty,max=12000"`
+}
+
 type WorkflowStepResponse struct {
 	ID              string                      `json:"id"`
 	Trigger         string                      `json:"trigger"`
@@ -242,6 +268,9 @@ type WorkflowStepResponse struct {
 	TemplateSubject *string                     `json:"templateSubject,omitempty"`
 	TemplateBody    *string                     `json:"templateBody,omitempty"`
 	StopOnReply     bool                        `json:"stopOnReply"`
+	SendCondition   *string                     `json:"sendCondition,omitempty" validate:"omitempty,max=1000"`
+	TemplateTranslations map[string]WorkflowStepTranslation `json:"templateTranslations,omitempty" validate:"omitempty,max=10,dive,keys,oneof=nl en de fr,endkeys"`
+	TemplateVariants []WorkflowStepVariant `json:"templateVariants,omitempty"`
 }
 
 type WorkflowResponse struct {
@@ -274,6 +303,9 @@ type UpsertWorkflowStepRequest struct {
 	TemplateSubject *string                     `json:"templateSubject,omitempty" validate:"omitempty,max=500"`
 	TemplateBody    *string                     `json:"templateBody,omitempty" validate:"omitempty,max=12000"`
 	StopOnReply     bool                        `json:"stopOnReply"`
+	SendCondition   *string                     `json:"sendCondition,omitempty" validate:"omitempty,max=1000"`
+	TemplateTranslations map[string]WorkflowStepTranslation `json:"templateTranslations,omitempty" validate:"omitempty,max=10,dive,keys,oneof=nl en de fr,endkeys"`
+	TemplateVariants []WorkflowStepVariant `json:"templateVariants,omitempty" validate:"omitempty,min=2,max=5,dive"`
 }
 
 type UpsertWorkflowRequest struct {
@@ -378,6 +410,9 @@ type CreateWorkflowStepRequest struct {
 	TemplateSubject *string                     `json:"templateSubject,omitempty" validate:"omitempty,max=500"`
 	TemplateBody    *string                     `json:"templateBody,omitempty" validate:"omitempty,max=12000"`
 	StopOnReply     bool                        `json:"stopOnReply"`
+	SendCondition   *string                     `json:"sendCondition,omitempty" validate:"omitempty,max=1000"`
+	TemplateTranslations map[string]WorkflowStepTranslation `json:"templateTranslations,omitempty" validate:"omitempty,max=10,dive,keys,oneof=nl en de fr,endkeys"`
+	TemplateVariants []WorkflowStepVariant `json:"templateVariants,omitempty" validate:"omitempty,min=2,max=5,dive"`
 }
 
 type UpdateWorkflowStepRequest struct {
@@ -392,4 +427,95 @@ type UpdateWorkflowStepRequest struct {
 	TemplateSubject *string                     `json:"templateSubject,omitempty" validate:"omitempty,max=500"`
 	TemplateBody    *string                     `json:"templateBody,omitempty" validate:"omitempty,max=12000"`
 	StopOnReply     bool                        `json:"stopOnReply"`
+	SendCondition   *string                     `json:"sendCondition,omitempty" validate:"omitempty,max=1000"`
+	TemplateTranslations map[string]WorkflowStepTranslation `json:"templateTranslations,omitempty" validate:"omitempty,max=10,dive,keys,oneof=nl en de fr,endkeys"`
+	TemplateVariants []WorkflowStepVariant `json:"templateVariants,omitempty" validate:"omitempty,min=2,max=5,dive"`
+}
+
+// WorkflowSampleLeadRequest is the lead a workflow simulation renders for.
+type WorkflowSampleLeadRequest struct {
+	FirstName   string `json:"firstName" validate:"max=100"`
+	LastName    string `json:"lastName" validate:"max=100"`
+	Phone       string `json:"phone" validate:"max=50"`
+	Email       string `json:"email" validate:"omitempty,email"`
+	Street      string `json:"street" validate:"max=200"`
+	HouseNumber string `json:"houseNumber" validate:"max=20"`
+	ZipCode     string `json:"zipCode" validate:"max=20"`
+	City        string `json:"city" validate:"max=100"`
+	ServiceType string `json:"serviceType" validate:"max=120"`
+}
+
+// SimulateWorkflowRequest simulates a stored workflow (workflowId) or an
+// unsaved definition (workflow) for an existing lead or a sample lead.
+type SimulateWorkflowRequest struct {
+	WorkflowID *string                    `json:"workflowId,omitempty" validate:"omitempty,uuid4"`
+	Workflow   *UpsertWorkflowRequest     `json:"workflow,omitempty"`
+	Trigger    string                     `json:"trigger,omitempty" validate:"max=100"`
+	LeadID     *string                    `json:"leadId,omitempty" validate:"omitempty,uuid4"`
+	SampleLead *WorkflowSampleLeadRequest `json:"sampleLead,omitempty"`
+	Variables  map[string]any             `json:"variables,omitempty"`
+	StartAt    *time.Time                 `json:"startAt,omitempty"`
+	// Language overrides the language resolved for the lead.
+	Language *string `json:"language,omitempty" validate:"omitempty,oneof=nl en de fr"`
+}
+
+type SimulatedWorkflowStepResponse struct {
+	StepID              *string   `json:"stepId,omitempty"`
+	StepOrder           int       `json:"stepOrder"`
+	Trigger             string    `json:"trigger"`
+	Channel             string    `json:"channel"`
+	Audience            string    `json:"audience"`
+	Language            string    `json:"language"`
+	Enabled             bool      `json:"enabled"`
+	DelayMinutes        int       `json:"delayMinutes"`
+	SendAt              time.Time `json:"sendAt"`
+	Subject             string    `json:"subject,omitempty"`
+	Body                string    `json:"body"`
+	Recipients          []string  `json:"recipients"`
+	TemplateErrors      []string  `json:"templateErrors"`
+	UnresolvedVariables []string  `json:"unresolvedVariables"`
+	EmptyVariables      []string  `json:"emptyVariables"`
+	SendCondition       *string   `json:"sendCondition,omitempty"`
+	ConditionMet        bool      `json:"conditionMet"`
+	Skipped             bool      `json:"skipped"`
+	SkipReason          string    `json:"skipReason,omitempty"`
+}
+
+type SimulateWorkflowResponse struct {
+	Valid bool                            `json:"valid"`
+	Steps []SimulatedWorkflowStepResponse `json:"steps"`
+}
+
+type WorkflowVariableResponse struct {
+	Path        string `json:"path"`
+	Placeholder string `json:"placeholder"`
+	Type        string `json:"type"`
+	Example     string `json:"example"`
+	Source      string `json:"source"`
+}
+
+type WorkflowTriggerVariablesResponse struct {
+	Trigger   string                     `json:"trigger"`
+	Variables []WorkflowVariableResponse `json:"variables"`
+}
+
+type WorkflowVariableCatalogResponse struct {
+	Triggers []WorkflowTriggerVariablesResponse `json:"triggers"`
+}
+
+// TestWorkflowConditionRequest evaluates a send condition for an existing
+// lead or a sample lead; variables override the lead's values.
+type TestWorkflowConditionRequest struct {
+	Condition  string                     `json:"condition" validate:"required,max=1000"`
+	Trigger    string                     `json:"trigger,omitempty" validate:"max=100"`
+	LeadID     *string                    `json:"leadId,omitempty" validate:"omitempty,uuid4"`
+	SampleLead *WorkflowSampleLeadRequest `json:"sampleLead,omitempty"`
+	Variables  map[string]any             `json:"variables,omitempty"`
+}
+
+type TestWorkflowConditionResponse struct {
+	Valid     bool           `json:"valid"`
+	Error     string         `json:"error,omitempty"`
+	Matched   bool           `json:"matched"`
+	Variables map[string]any `json:"variables,omitempty"`
 }
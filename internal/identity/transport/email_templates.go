@@ -0,0 +1,47 @@
+package transport
+
+import "time"
+
+// EmailTemplateDraftRequest is an override of a system email as edited in the
+// template editor. Placeholders use the {{path}} syntax of workflow templates.
+type EmailTemplateDraftRequest struct {
+	Subject  string `json:"subject" validate:"required,max=300"`
+	BodyHTML string `json:"bodyHtml" validate:"required,max=100000"`
+}
+
+type EmailTemplateSummaryResponse struct {
+	Key           string                     `json:"key"`
+	Description   string                     `json:"description"`
+	Variables     []WorkflowVariableResponse `json:"variables"`
+	Overridden    bool                       `json:"overridden"`
+	ActiveVersion *int                       `json:"activeVersion,omitempty"`
+	UpdatedAt     *time.Time                 `json:"updatedAt,omitempty"`
+}
+
+type EmailTemplateListResponse struct {
+	Templates []EmailTemplateSummaryResponse `json:"templates"`
+}
+
+type EmailTemplateVersionResponse struct {
+	Version   int       `json:"version"`
+	Subject   string    `json:"subject"`
+	BodyHTML  string    `json:"bodyHtml"`
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type EmailTemplateDetailResponse struct {
+	Key         string                         `json:"key"`
+	Description string                         `json:"description"`
+	Variables   []WorkflowVariableResponse     `json:"variables"`
+	Active      *EmailTemplateVersionResponse  `json:"active"`
+	Versions    []EmailTemplateVersionResponse `json:"versions"`
+}
+
+type EmailTemplatePreviewResponse struct {
+	Valid               bool     `json:"valid"`
+	Subject             string   `json:"subject"`
+	BodyHTML            string   `json:"bodyHtml"`
+	TemplateErrors      []string `json:"templateErrors"`
+	UnresolvedVariables []string `json:"unresolvedVariables"`
+}
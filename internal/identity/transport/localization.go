@@ -0,0 +1,12 @@
+package transport
+
+// UpdateLocalizationRequest sets the language customer-facing notifications
+// fall back to when a lead has no preferred language.
+type UpdateLocalizationRequest struct {
+	DefaultLanguage string `json:"defaultLanguage" validate:"required,oneof=nl en de fr"`
+}
+
+type LocalizationResponse struct {
+	DefaultLanguage    string   `json:"defaultLanguage"`
+	SupportedLanguages []string `json:"supportedLanguages"`
+}
@@ -0,0 +1,29 @@
+package transport
+
+import "time"
+
+// UpdateMessagingPolicyRequest configures quiet hours ("HH:MM" in timezone)
+// and per-lead frequency caps for outbound messages. EmailTracking opts in to
+// open and click tracking of workflow emails.
+type UpdateMessagingPolicyRequest struct {
+	QuietHoursEnabled         bool   `json:"quietHoursEnabled"`
+	QuietHoursStart           string `json:"quietHoursStart" validate:"required_if=QuietHoursEnabled true,omitempty,len=5"`
+	QuietHoursEnd             string `json:"quietHoursEnd" validate:"required_if=QuietHoursEnabled true,omitempty,len=5"`
+	Timezone                  string `json:"timezone" validate:"omitempty,max=64"`
+	MaxMessagesPerLeadPerHour *int   `json:"maxMessagesPerLeadPerHour" validate:"omitempty,min=1,max=100"`
+	MaxMessagesPerLeadPerDay  *int   `json:"maxMessagesPerLeadPerDay" validate:"omitempty,min=1,max=1000"`
+	IncludeEmail              bool   `json:"includeEmail"`
+	EmailTracking             bool   `json:"emailTracking"`
+}
+
+type MessagingPolicyResponse struct {
+	QuietHoursEnabled         bool       `json:"quietHoursEnabled"`
+	QuietHoursStart           string     `json:"quietHoursStart"`
+	QuietHoursEnd             string     `json:"quietHoursEnd"`
+	Timezone                  string     `json:"timezone"`
+	MaxMessagesPerLeadPerHour *int       `json:"maxMessagesPerLeadPerHour"`
+	MaxMessagesPerLeadPerDay  *int       `json:"maxMessagesPerLeadPerDay"`
+	IncludeEmail              bool       `json:"includeEmail"`
+	EmailTracking             bool       `json:"emailTracking"`
+	UpdatedAt                 *time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,27 @@
+package transport
+
+import "time"
+
+// CreateOrganizationSandboxRequest copies the caller's organization into a sandbox.
+type CreateOrganizationSandboxRequest struct {
+	Name        string `json:"name" validate:"required,max=200"`
+	InviteEmail string `json:"inviteEmail" validate:"omitempty,email"`
+	DemoLeads   int    `json:"demoLeads" validate:"gte=0,lte=50"`
+}
+
+// CloneOrganizationRequest copies any organization into a demo or sandbox organization.
+type CloneOrganizationRequest struct {
+	Name        string `json:"name" validate:"required,max=200"`
+	Purpose     string `json:"purpose" validate:"required,oneof=demo sandbox"`
+	InviteEmail string `json:"inviteEmail" validate:"omitempty,email"`
+	DemoLeads   int    `json:"demoLeads" validate:"gte=0,lte=50"`
+}
+
+type CloneOrganizationResponse struct {
+	OrganizationID  string           `json:"organizationId"`
+	Name            string           `json:"name"`
+	Purpose         string           `json:"purpose"`
+	CopiedRows      map[string]int64 `json:"copiedRows"`
+	DemoLeads       int              `json:"demoLeads"`
+	InviteExpiresAt *time.Time       `json:"inviteExpiresAt,omitempty"`
+}
@@ -0,0 +1,33 @@
+package transport
+
+import "time"
+
+// SetParentOrganizationRequest makes an organization a branch of a head office.
+type SetParentOrganizationRequest struct {
+	ParentOrganizationID string `json:"parentOrganizationId" validate:"required,uuid"`
+}
+
+type OrganizationBranchResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// OrganizationHierarchyResponse places an organization in its franchise. A
+// branch has a parent organization; a head office lists its branches.
+type OrganizationHierarchyResponse struct {
+	ParentOrganizationID *string                      `json:"parentOrganizationId,omitempty"`
+	Branches             []OrganizationBranchResponse `json:"branches"`
+}
+
+// BranchCatalogSyncResponse counts the inherited products a catalog sync
+// created and refreshed in a branch.
+type BranchCatalogSyncResponse struct {
+	OrganizationID  string `json:"organizationId"`
+	CreatedProducts int64  `json:"createdProducts"`
+	UpdatedProducts int64  `json:"updatedProducts"`
+}
+
+type BranchCatalogSyncListResponse struct {
+	Items []BranchCatalogSyncResponse `json:"items"`
+}
@@ -0,0 +1,31 @@
+package transport
+
+import "time"
+
+// PortalDomainRequest registers the custom domain of the public portal.
+type PortalDomainRequest struct {
+	Domain string `json:"domain" validate:"required,max=253"`
+}
+
+// PortalDomainRecordResponse is the DNS record that proves domain ownership.
+type PortalDomainRecordResponse struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PortalDomainResponse struct {
+	Domain             string                     `json:"domain"`
+	Verified           bool                       `json:"verified"`
+	VerifiedAt         *time.Time                 `json:"verifiedAt,omitempty"`
+	LastCheckedAt      *time.Time                 `json:"lastCheckedAt,omitempty"`
+	LastError          *string                    `json:"lastError,omitempty"`
+	VerificationRecord PortalDomainRecordResponse `json:"verificationRecord"`
+}
+
+// PublicPortalResponse identifies the organization behind a white-label portal domain.
+type PublicPortalResponse struct {
+	OrganizationID string `json:"organizationId"`
+	Name           string `json:"name"`
+	Domain         string `json:"domain"`
+}
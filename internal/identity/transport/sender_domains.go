@@ -0,0 +1,27 @@
+package transport
+
+import "time"
+
+// VerifySenderDomainRequest checks the DNS records of a sender domain. An
+// empty domain checks the domain of the configured SMTP from address.
+type VerifySenderDomainRequest struct {
+	Domain       string `json:"domain" validate:"omitempty,fqdn,max=253"`
+	DKIMSelector string `json:"dkimSelector" validate:"omitempty,max=63"`
+}
+
+// SenderDomainResponse is the last DNS check of the domain tenant SMTP sends
+// from. SPF, DKIM and DMARC are "pass", "missing", "invalid", "error" or
+// "skipped" for mailbox providers that sign their own mail.
+type SenderDomainResponse struct {
+	Domain        string     `json:"domain"`
+	Verified      bool       `json:"verified"`
+	VerifiedAt    *time.Time `json:"verifiedAt,omitempty"`
+	Overridden    bool       `json:"overridden"`
+	OverrideAt    *time.Time `json:"overrideAt,omitempty"`
+	DKIMSelector  *string    `json:"dkimSelector,omitempty"`
+	SPF           string     `json:"spf"`
+	DKIM          string     `json:"dkim"`
+	DMARC         string     `json:"dmarc"`
+	Warnings      []string   `json:"warnings"`
+	LastCheckedAt time.Time  `json:"lastCheckedAt"`
+}
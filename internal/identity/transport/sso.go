@@ -0,0 +1,45 @@
+package transport
+
+import "time"
+
+// UpdateSSOConfigRequest configures OpenID Connect single sign-on. An empty
+// clientSecret keeps the stored secret. roleMappings maps values of roleClaim
+// in the ID token to application roles.
+type UpdateSSOConfigRequest struct {
+	Enabled         bool              `json:"enabled"`
+	IssuerURL       string            `json:"issuerUrl" validate:"required,url,max=500"`
+	ClientID        string            `json:"clientId" validate:"required,max=255"`
+	ClientSecret    string            `json:"clientSecret" validate:"omitempty,max=1024"`
+	AllowedDomains  []string          `json:"allowedDomains" validate:"required,min=1,max=20,dive,required,max=253"`
+	RoleClaim       *string           `json:"roleClaim" validate:"omitempty,max=100"`
+	RoleMappings    map[string]string `json:"roleMappings" validate:"omitempty,max=100,dive,keys,max=255,endkeys,required,max=50"`
+	DefaultRole     string            `json:"defaultRole" validate:"omitempty,max=50"`
+	JITProvisioning bool              `json:"jitProvisioning"`
+	EnforceSSO      bool              `json:"enforceSso"`
+}
+
+type SSOConfigResponse struct {
+	Enabled         bool              `json:"enabled"`
+	IssuerURL       string            `json:"issuerUrl"`
+	ClientID        string            `json:"clientId"`
+	HasClientSecret bool              `json:"hasClientSecret"`
+	AllowedDomains  []string          `json:"allowedDomains"`
+	RoleClaim       *string           `json:"roleClaim"`
+	RoleMappings    map[string]string `json:"roleMappings"`
+	DefaultRole     string            `json:"defaultRole"`
+	JITProvisioning bool              `json:"jitProvisioning"`
+	EnforceSSO      bool              `json:"enforceSso"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+	// Domains lists the DNS verification of allowedDomains; SSO only applies
+	// to verified domains.
+	Domains []SSODomainResponse `json:"domains"`
+}
+
+type SSODomainResponse struct {
+	Domain             string                     `json:"domain"`
+	Verified           bool                       `json:"verified"`
+	VerifiedAt         *time.Time                 `json:"verifiedAt,omitempty"`
+	LastCheckedAt      *time.Time                 `json:"lastCheckedAt,omitempty"`
+	LastError          *string                    `json:"lastError,omitempty"`
+	VerificationRecord PortalDomainRecordResponse `json:"verificationRecord"`
+}
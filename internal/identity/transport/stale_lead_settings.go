@@ -0,0 +1,27 @@
+package transport
+
+import "time"
+
+// UpdateStaleLeadSettingsRequest sets after how many days without progress a
+// lead service is stale, per stale reason, and what happens when it is.
+// Omitted thresholds use the defaults.
+type UpdateStaleLeadSettingsRequest struct {
+	NoActivityDays       int  `json:"noActivityDays" validate:"omitempty,min=1,max=365"`
+	NurturingDays        int  `json:"nurturingDays" validate:"omitempty,min=1,max=365"`
+	NoQuoteDays          int  `json:"noQuoteDays" validate:"omitempty,min=1,max=365"`
+	DraftQuoteDays       int  `json:"draftQuoteDays" validate:"omitempty,min=1,max=365"`
+	RescheduleDays       int  `json:"rescheduleDays" validate:"omitempty,min=1,max=365"`
+	CreateOwnerTasks     bool `json:"createOwnerTasks"`
+	ConsumerNudgeEnabled bool `json:"consumerNudgeEnabled"`
+}
+
+type StaleLeadSettingsResponse struct {
+	NoActivityDays       int        `json:"noActivityDays"`
+	NurturingDays        int        `json:"nurturingDays"`
+	NoQuoteDays          int        `json:"noQuoteDays"`
+	DraftQuoteDays       int        `json:"draftQuoteDays"`
+	RescheduleDays       int        `json:"rescheduleDays"`
+	CreateOwnerTasks     bool       `json:"createOwnerTasks"`
+	ConsumerNudgeEnabled bool       `json:"consumerNudgeEnabled"`
+	UpdatedAt            *time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,386 @@
+// Package workflowcondition parses and evaluates the send conditions of
+// workflow steps.
+//
+// A condition is a small boolean expression over the step's template
+// variables, for example:
+//
+//	lead.score >= 60 && lead.source in ["website", "werkspot"] && has(lead.email)
+//	quote.totalCents > 500000 || lead.serviceType == "Warmtepomp"
+//
+// Supported are number, string, true, false and null literals, dotted
+// variable paths, the comparison operators == != < <= > >=, the list
+// operator in, the logical operators && || ! (or and, or, not), parentheses
+// and has(path), which is true when the variable exists and is not empty.
+// String comparisons ignore case and surrounding whitespace. Expressions
+// cannot call anything else, so they are safe to store and evaluate.
+package workflowcondition
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxLength is the longest condition that is accepted.
+	MaxLength = 1000
+	maxDepth  = 32
+)
+
+// Condition is a parsed send condition.
+type Condition struct {
+	source string
+	root   node
+}
+
+// Parse parses a condition expression. An empty expression is not a
+// condition; callers treat it as "always send".
+func Parse(expr string) (*Condition, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, errors.New("condition is empty")
+	}
+	if len(expr) > MaxLength {
+		return nil, fmt.Errorf("condition is longer than %d characters", MaxLength)
+	}
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return &Condition{source: expr, root: root}, nil
+}
+
+// Validate reports whether expr is a valid condition. Empty is valid.
+func Validate(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return nil
+	}
+	_, err := Parse(expr)
+	return err
+}
+
+// Evaluate parses expr and evaluates it against vars. An empty expression
+// always matches.
+func Evaluate(expr string, vars map[string]any) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+	condition, err := Parse(expr)
+	if err != nil {
+		return false, err
+	}
+	return condition.Matches(vars), nil
+}
+
+// String returns the condition as it was written.
+func (c *Condition) String() string {
+	return c.source
+}
+
+// Matches evaluates the condition against the template variables of a step.
+// Missing variables are null; comparing values of different types is false.
+func (c *Condition) Matches(vars map[string]any) bool {
+	return truthy(c.root.eval(vars))
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		ch := expr[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case ch == '[':
+			tokens = append(tokens, token{kind: tokenLBracket, text: "[", pos: i})
+			i++
+		case ch == ']':
+			tokens = append(tokens, token{kind: tokenRBracket, text: "]", pos: i})
+			i++
+		case ch == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case ch == '"' || ch == '\'':
+			end := i + 1
+			var b strings.Builder
+			for end < len(expr) && expr[end] != ch {
+				if expr[end] == '\\' && end+1 < len(expr) {
+					end++
+				}
+				b.WriteByte(expr[end])
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String(), pos: i})
+			i = end + 1
+		case ch >= '0' && ch <= '9' || ch == '-' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9':
+			end := i + 1
+			for end < len(expr) && (expr[end] >= '0' && expr[end] <= '9' || expr[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: expr[i:end], pos: i})
+			i = end
+		case isIdentStart(ch):
+			end := i + 1
+			for end < len(expr) && (isIdentStart(expr[end]) || expr[end] >= '0' && expr[end] <= '9' || expr[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: expr[i:end], pos: i})
+			i = end
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"} {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", ch, i)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, text: "end of condition", pos: len(expr)}), nil
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(words ...string) bool {
+	tok := p.peek()
+	if tok.kind == tokenOp {
+		for _, word := range words {
+			if tok.text == word {
+				return true
+			}
+		}
+	}
+	if tok.kind == tokenIdent {
+		for _, word := range words {
+			if strings.EqualFold(tok.text, word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr(depth int) (node, error) {
+	if depth > maxDepth {
+		return nil, errors.New("condition is nested too deeply")
+	}
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("||", "or") {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (node, error) {
+	left, err := p.parseNot(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("&&", "and") {
+		p.next()
+		right, err := p.parseNot(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot(depth int) (node, error) {
+	if p.isKeyword("!", "not") {
+		if depth > maxDepth {
+			return nil, errors.New("condition is nested too deeply")
+		}
+		p.next()
+		operand, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseComparison(depth)
+}
+
+func (p *parser) parseComparison(depth int) (node, error) {
+	left, err := p.parseOperand(depth)
+	if err != nil {
+		return nil, err
+	}
+	if p.isKeyword("in") {
+		p.next()
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inNode{value: left, list: list}, nil
+	}
+	tok := p.peek()
+	if tok.kind == tokenOp {
+		switch tok.text {
+		case "==", "!=", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parseOperand(depth)
+			if err != nil {
+				return nil, err
+			}
+			return compareNode{op: tok.text, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseOperand(depth int) (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return literalNode{value: value}, nil
+	case tokenString:
+		return literalNode{value: tok.text}, nil
+	case tokenLParen:
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) at position %d", closing.pos)
+		}
+		return inner, nil
+	case tokenIdent:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null", "nil":
+			return literalNode{value: nil}, nil
+		case "has":
+			return p.parseHas(tok)
+		}
+		return pathNode{path: tok.text}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
+
+func (p *parser) parseHas(fn token) (node, error) {
+	if open := p.next(); open.kind != tokenLParen {
+		return nil, fmt.Errorf("expected ( after has at position %d", open.pos)
+	}
+	arg := p.next()
+	if arg.kind != tokenIdent {
+		return nil, fmt.Errorf("has expects a variable at position %d", arg.pos)
+	}
+	if closing := p.next(); closing.kind != tokenRParen {
+		return nil, fmt.Errorf("expected ) at position %d", closing.pos)
+	}
+	return hasNode{path: arg.text}, nil
+}
+
+func (p *parser) parseList() ([]any, error) {
+	if open := p.next(); open.kind != tokenLBracket {
+		return nil, fmt.Errorf("expected [ after in at position %d", open.pos)
+	}
+	var values []any
+	for {
+		tok := p.next()
+		switch tok.kind {
+		case tokenString:
+			values = append(values, tok.text)
+		case tokenNumber:
+			value, err := strconv.ParseFloat(tok.text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+			}
+			values = append(values, value)
+		case tokenRBracket:
+			if len(values) == 0 {
+				return values, nil
+			}
+			return nil, fmt.Errorf("unexpected ] at position %d", tok.pos)
+		default:
+			return nil, fmt.Errorf("lists may only contain strings and numbers, got %q at position %d", tok.text, tok.pos)
+		}
+		sep := p.next()
+		if sep.kind == tokenRBracket {
+			return values, nil
+		}
+		if sep.kind != tokenComma {
+			return nil, fmt.Errorf("expected , or ] at position %d", sep.pos)
+		}
+	}
+}
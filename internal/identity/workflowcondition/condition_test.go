@@ -0,0 +1,57 @@
+package workflowcondition
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]any{
+		"lead": map[string]any{
+			"score":       72,
+			"source":      "Website",
+			"serviceType": "Warmtepomp",
+			"email":       "jan@example.com",
+			"phone":       "",
+		},
+		"quote": map[string]any{"totalCents": int64(650000)},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`lead.score >= 60 && lead.score < 80`, true},
+		{`lead.score > 80`, false},
+		{`lead.source in ["website", "werkspot"]`, true},
+		{`lead.serviceType == "warmtepomp"`, true},
+		{`has(lead.email) and not has(lead.phone)`, true},
+		{`quote.totalCents > 500000 || lead.score < 10`, true},
+		{`lead.nickname == null`, true},
+		{`lead.missing > 3`, false},
+		{`!(lead.source == "werkspot")`, true},
+		{``, true},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr, vars)
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%q: expected %v, got %v", tt.expr, tt.want, got)
+		}
+	}
+}
+
+func TestParseRejectsInvalidConditions(t *testing.T) {
+	for _, expr := range []string{
+		`lead.score >=`,
+		`lead.source in "website"`,
+		`(lead.score > 1`,
+		`lead.score > 1 lead.score`,
+		`"unterminated`,
+		`exec("rm")`,
+		`lead.score ; 1`,
+	} {
+		if err := Validate(expr); err == nil {
+			t.Fatalf("expected %q to be rejected", expr)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package workflowcondition
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type node interface {
+	eval(vars map[string]any) any
+}
+
+type literalNode struct{ value any }
+
+type pathNode struct{ path string }
+
+type hasNode struct{ path string }
+
+type notNode struct{ operand node }
+
+type andNode struct{ left, right node }
+
+type orNode struct{ left, right node }
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+type inNode struct {
+	value node
+	list  []any
+}
+
+func (n literalNode) eval(map[string]any) any { return n.value }
+
+func (n pathNode) eval(vars map[string]any) any {
+	value, _ := lookup(vars, n.path)
+	return value
+}
+
+func (n hasNode) eval(vars map[string]any) any {
+	value, ok := lookup(vars, n.path)
+	return ok && truthy(value)
+}
+
+func (n notNode) eval(vars map[string]any) any { return !truthy(n.operand.eval(vars)) }
+
+func (n andNode) eval(vars map[string]any) any {
+	return truthy(n.left.eval(vars)) && truthy(n.right.eval(vars))
+}
+
+func (n orNode) eval(vars map[string]any) any {
+	return truthy(n.left.eval(vars)) || truthy(n.right.eval(vars))
+}
+
+func (n compareNode) eval(vars map[string]any) any {
+	left, right := n.left.eval(vars), n.right.eval(vars)
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+
+	if l, ok := number(left); ok {
+		r, ok := number(right)
+		if !ok {
+			return false
+		}
+		switch n.op {
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		default:
+			return l >= r
+		}
+	}
+	l, lok := left.(string)
+	r, rok := right.(string)
+	if !lok || !rok {
+		return false
+	}
+	cmp := strings.Compare(normalize(l), normalize(r))
+	switch n.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func (n inNode) eval(vars map[string]any) any {
+	value := n.value.eval(vars)
+	for _, candidate := range n.list {
+		if equal(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup resolves a dotted path, matching keys case-insensitively like the
+// template renderer does.
+func lookup(vars map[string]any, path string) (any, bool) {
+	var current any = vars
+	for _, segment := range strings.Split(path, ".") {
+		values, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		next, found := values[segment]
+		if !found {
+			for key, value := range values {
+				if strings.EqualFold(key, segment) {
+					next, found = value, true
+					break
+				}
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+func equal(left, right any) bool {
+	if left == nil || right == nil {
+		return isNull(left) && isNull(right)
+	}
+	if l, ok := number(left); ok {
+		if r, ok := number(right); ok {
+			return l == r
+		}
+	}
+	if l, ok := left.(bool); ok {
+		r, ok := right.(bool)
+		return ok && l == r
+	}
+	l, lok := left.(string)
+	r, rok := right.(string)
+	return lok && rok && normalize(l) == normalize(r)
+}
+
+func isNull(value any) bool {
+	if value == nil {
+		return true
+	}
+	s, ok := value.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
+
+// number converts numeric values, including numeric strings, to float64.
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return strings.TrimSpace(v) != ""
+	default:
+		if f, ok := number(v); ok {
+			return f != 0
+		}
+		return true
+	}
+}
+
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
@@ -15,8 +15,10 @@ import (
 )
 
 type Module struct {
-	handler *handler.Handler
-	service *service.Service
+	handler            *handler.Handler
+	service            *service.Service
+	identityRepository *identityrepo.Repository
+	leadRepository     *leadsrepo.Repository
 }
 
 func NewModule(pool *pgxpool.Pool, val *validator.Validator, bus events.Bus, log *logger.Logger) *Module {
@@ -26,8 +28,10 @@ func NewModule(pool *pgxpool.Pool, val *validator.Validator, bus events.Bus, log
 	svc := service.New(repo, identityRepository, leadRepository, bus, log)
 	h := handler.New(svc, val)
 	return &Module{
-		handler: h,
-		service: svc,
+		handler:            h,
+		service:            svc,
+		identityRepository: identityRepository,
+		leadRepository:     leadRepository,
 	}
 }
 
@@ -39,6 +43,13 @@ func (m *Module) Service() *service.Service {
 	return m.service
 }
 
+// SetLeadContactCipher lets the module read and match leads whose contact
+// details are encrypted.
+func (m *Module) SetLeadContactCipher(cipher *leadsrepo.ContactCipher) {
+	m.identityRepository.SetLeadContactCipher(cipher)
+	m.leadRepository.SetContactCipher(cipher)
+}
+
 func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	group := ctx.Protected.Group("/users/me/imap-accounts")
 	m.handler.RegisterRoutes(group)
@@ -20,6 +20,7 @@ import (
 	leadstransport "portal_final_backend/internal/leads/transport"
 	"portal_final_backend/internal/scheduler"
 	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
 	"portal_final_backend/platform/logger"
 
 	"github.com/google/uuid"
@@ -85,10 +86,6 @@ type LeadInboxSummary struct {
 	City     string
 }
 
-func (s *Service) SetSMTPEncryptionKey(_ []byte) {
-	// SMTP credentials are stored encrypted with the same key as IMAP credentials.
-}
-
 func (s *Service) SetEncryptionKey(key []byte) {
 	s.encryptionKey = key
 }
@@ -1125,9 +1122,6 @@ func addressesToStrings(items []client.Address) []string {
 }
 
 func (s *Service) syncAccount(ctx context.Context, account repository.Account) (err error) {
-	syncCtx, cancel := context.WithTimeout(ctx, defaultSyncTimeout)
-	defer cancel()
-
 	if !s.tryLock(account.ID) {
 		s.logInfo(
 			"imap account sync skipped",
@@ -1140,6 +1134,16 @@ func (s *Service) syncAccount(ctx context.Context, account repository.Account) (
 	}
 	defer s.unlock(account.ID)
 
+	// Messages are linked to leads of the user's organization, which may live
+	// in a dedicated data schema.
+	if organizationID, orgErr := s.identityRepo.GetUserOrganizationID(ctx, account.UserID); orgErr == nil {
+		if ctx, err = httpkit.BindTenant(ctx, organizationID); err != nil {
+			return err
+		}
+	}
+	syncCtx, cancel := context.WithTimeout(ctx, defaultSyncTimeout)
+	defer cancel()
+
 	startedAt := time.Now()
 	messageCount := 0
 	s.logInfo(
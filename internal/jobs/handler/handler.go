@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/jobs/service"
+	"portal_final_backend/internal/jobs/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	msgInvalidRequest   = "invalid request"
+	msgValidationFailed = "validation failed"
+)
+
+type Handler struct {
+	svc         *service.Service
+	maintenance *service.Maintenance
+	val         *validator.Validator
+}
+
+func New(svc *service.Service, maintenance *service.Maintenance, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, maintenance: maintenance, val: val}
+}
+
+// RegisterRoutes registers the job dashboard. Queues are shared by all tenants,
+// so the routes belong on the superadmin group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/queues", h.ListQueues)
+	rg.GET("/queues/:queue/history", h.GetQueueHistory)
+	rg.GET("/queues/:queue/jobs", h.ListJobs)
+	rg.GET("/queues/:queue/jobs/:jobId", h.GetJob)
+	rg.POST("/queues/:queue/jobs/:jobId/retry", h.RetryJob)
+	rg.DELETE("/queues/:queue/jobs/:jobId", h.DeleteJob)
+	rg.GET("/failures", h.ListRecentFailures)
+	rg.GET("/throughput", h.GetThroughput)
+
+	rg.GET("/maintenance/jobs", h.ListMaintenanceJobs)
+	rg.GET("/maintenance/runs", h.ListMaintenanceRuns)
+	rg.POST("/maintenance/runs", h.StartMaintenanceRun)
+	rg.GET("/maintenance/runs/:runId", h.GetMaintenanceRun)
+	rg.POST("/maintenance/runs/:runId/cancel", h.CancelMaintenanceRun)
+}
+
+func (h *Handler) ListQueues(c *gin.Context) {
+	resp, err := h.svc.ListQueues(c.Request.Context())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetQueueHistory(c *gin.Context) {
+	var req transport.HistoryRequest
+	if !h.bindQuery(c, &req) {
+		return
+	}
+
+	resp, err := h.svc.GetQueueHistory(c.Request.Context(), c.Param("queue"), req.Days)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) ListJobs(c *gin.Context) {
+	var req transport.ListJobsRequest
+	if !h.bindQuery(c, &req) {
+		return
+	}
+
+	resp, err := h.svc.ListJobs(c.Request.Context(), c.Param("queue"), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetJob(c *gin.Context) {
+	resp, err := h.svc.GetJob(c.Request.Context(), c.Param("queue"), c.Param("jobId"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) RetryJob(c *gin.Context) {
+	resp, err := h.svc.RetryJob(c.Request.Context(), c.Param("queue"), c.Param("jobId"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) DeleteJob(c *gin.Context) {
+	if err := h.svc.DeleteJob(c.Request.Context(), c.Param("queue"), c.Param("jobId")); httpkit.HandleError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ListRecentFailures(c *gin.Context) {
+	var req transport.ListFailuresRequest
+	if !h.bindQuery(c, &req) {
+		return
+	}
+
+	resp, err := h.svc.ListRecentFailures(c.Request.Context(), req.Limit)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetThroughput(c *gin.Context) {
+	var req transport.ThroughputRequest
+	if !h.bindQuery(c, &req) {
+		return
+	}
+
+	resp, err := h.svc.GetThroughput(c.Request.Context(), req.Hours)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) bindQuery(c *gin.Context, req any) bool {
+	if err := c.ShouldBindQuery(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return false
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/jobs/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) ListMaintenanceJobs(c *gin.Context) {
+	httpkit.OK(c, h.maintenance.ListJobs())
+}
+
+func (h *Handler) StartMaintenanceRun(c *gin.Context) {
+	var req transport.StartMaintenanceRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.maintenance.StartRun(c.Request.Context(), httpkit.GetIdentity(c).UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusAccepted, resp)
+}
+
+func (h *Handler) ListMaintenanceRuns(c *gin.Context) {
+	var req transport.ListMaintenanceRunsRequest
+	if !h.bindQuery(c, &req) {
+		return
+	}
+
+	resp, err := h.maintenance.ListRuns(c.Request.Context(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetMaintenanceRun(c *gin.Context) {
+	id, ok := httpkit.ParseUUIDParam(c, "runId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.maintenance.GetRun(c.Request.Context(), id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) CancelMaintenanceRun(c *gin.Context) {
+	id, ok := httpkit.ParseUUIDParam(c, "runId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.maintenance.CancelRun(c.Request.Context(), id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
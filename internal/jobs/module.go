@@ -0,0 +1,35 @@
+// Package jobs provides the operator dashboard for background jobs and starts
+// maintenance jobs on demand.
+package jobs
+
+import (
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/internal/jobs/handler"
+	"portal_final_backend/internal/jobs/repository"
+	"portal_final_backend/internal/jobs/service"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	handler *handler.Handler
+}
+
+func NewModule(inspector service.Inspector, pool *pgxpool.Pool, enqueuer service.RunEnqueuer, val *validator.Validator) *Module {
+	maintenance := service.NewMaintenance(repository.New(pool), enqueuer)
+	return &Module{handler: handler.New(service.New(inspector), maintenance, val)}
+}
+
+func (m *Module) Name() string {
+	return "jobs"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	if ctx.SuperAdmin == nil {
+		return
+	}
+	m.handler.RegisterRoutes(ctx.SuperAdmin.Group("/jobs"))
+}
+
+var _ apphttp.Module = (*Module)(nil)
@@ -0,0 +1,196 @@
+// Package repository persists the maintenance runs started from the job dashboard.
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Run statuses. A run is queued until the scheduler picks it up.
+const (
+	RunStatusQueued    = "queued"
+	RunStatusRunning   = "running"
+	RunStatusCompleted = "completed"
+	RunStatusFailed    = "failed"
+	RunStatusCancelled = "cancelled"
+)
+
+var (
+	// ErrRunNotFound is returned when a maintenance run does not exist.
+	ErrRunNotFound = errors.New("maintenance run not found")
+	// ErrRunActive is returned when the job already has a queued or running run.
+	ErrRunActive = errors.New("maintenance job already queued or running")
+)
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// RunCounts are the records handled by a run so far.
+type RunCounts struct {
+	Processed int
+	Updated   int
+	Skipped   int
+	Failed    int
+}
+
+// Run is a maintenance job started on demand.
+type Run struct {
+	ID          uuid.UUID
+	Job         string
+	Params      json.RawMessage
+	Status      string
+	Counts      RunCounts
+	Error       *string
+	RequestedBy *uuid.UUID
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	UpdatedAt   time.Time
+}
+
+const runColumns = `id, job, params, status, processed, updated, skipped, failed, error, requested_by, created_at, started_at, finished_at, updated_at`
+
+func scanRun(row pgx.Row) (Run, error) {
+	var r Run
+	err := row.Scan(&r.ID, &r.Job, &r.Params, &r.Status, &r.Counts.Processed, &r.Counts.Updated, &r.Counts.Skipped, &r.Counts.Failed,
+		&r.Error, &r.RequestedBy, &r.CreatedAt, &r.StartedAt, &r.FinishedAt, &r.UpdatedAt)
+	return r, err
+}
+
+// CreateRun queues a run. It returns ErrRunActive when the job is already
+// queued or running.
+func (r *Repository) CreateRun(ctx context.Context, job string, params json.RawMessage, requestedBy uuid.UUID) (Run, error) {
+	run, err := scanRun(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_maintenance_runs (job, params, requested_by)
+		VALUES ($1, $2, $3)
+		RETURNING `+runColumns, job, params, requestedBy,
+	))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "ux_maintenance_runs_active_job" {
+		return Run{}, ErrRunActive
+	}
+	if err != nil {
+		return Run{}, fmt.Errorf("create maintenance run: %w", err)
+	}
+	return run, nil
+}
+
+func (r *Repository) GetRun(ctx context.Context, id uuid.UUID) (Run, error) {
+	run, err := scanRun(r.pool.QueryRow(ctx, `
+		SELECT `+runColumns+`
+		FROM RAC_maintenance_runs
+		WHERE id = $1`, id,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Run{}, ErrRunNotFound
+	}
+	if err != nil {
+		return Run{}, fmt.Errorf("get maintenance run: %w", err)
+	}
+	return run, nil
+}
+
+// ListRuns returns the most recent runs, optionally of one job only.
+func (r *Repository) ListRuns(ctx context.Context, job string, limit int) ([]Run, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+runColumns+`
+		FROM RAC_maintenance_runs
+		WHERE $1 = '' OR job = $1
+		ORDER BY created_at DESC
+		LIMIT $2`, job, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list maintenance runs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Run, 0)
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan maintenance run: %w", err)
+		}
+		items = append(items, run)
+	}
+	return items, rows.Err()
+}
+
+// StartRun marks a queued run as running. It reports false when the run is
+// no longer queued, e.g. because it was cancelled before the scheduler got to it.
+func (r *Repository) StartRun(ctx context.Context, id uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_maintenance_runs
+		SET status = $2, started_at = now(), updated_at = now()
+		WHERE id = $1 AND status = $3`, id, RunStatusRunning, RunStatusQueued,
+	)
+	if err != nil {
+		return false, fmt.Errorf("start maintenance run: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// UpdateRunCounts records the progress of a run.
+func (r *Repository) UpdateRunCounts(ctx context.Context, id uuid.UUID, counts RunCounts) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_maintenance_runs
+		SET processed = $2, updated = $3, skipped = $4, failed = $5, updated_at = now()
+		WHERE id = $1`, id, counts.Processed, counts.Updated, counts.Skipped, counts.Failed,
+	); err != nil {
+		return fmt.Errorf("update maintenance run counts: %w", err)
+	}
+	return nil
+}
+
+// FinishRun ends a queued or running run with the given status. A run
+// cancelled in the meantime keeps its cancelled status.
+func (r *Repository) FinishRun(ctx context.Context, id uuid.UUID, status string, runErr *string) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_maintenance_runs
+		SET status = $2, error = $3, finished_at = now(), updated_at = now()
+		WHERE id = $1 AND status IN ($4, $5)`, id, status, runErr, RunStatusQueued, RunStatusRunning,
+	); err != nil {
+		return fmt.Errorf("finish maintenance run: %w", err)
+	}
+	return nil
+}
+
+// CancelRun cancels a queued or running run. A running run stops at the next
+// cancellation check of the scheduler. It reports false when the run had
+// already ended.
+func (r *Repository) CancelRun(ctx context.Context, id uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_maintenance_runs
+		SET status = $2, finished_at = now(), updated_at = now()
+		WHERE id = $1 AND status IN ($3, $4)`, id, RunStatusCancelled, RunStatusQueued, RunStatusRunning,
+	)
+	if err != nil {
+		return false, fmt.Errorf("cancel maintenance run: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetRunStatus returns the status of a run.
+func (r *Repository) GetRunStatus(ctx context.Context, id uuid.UUID) (string, error) {
+	var status string
+	err := r.pool.QueryRow(ctx, `SELECT status FROM RAC_maintenance_runs WHERE id = $1`, id).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrRunNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("get maintenance run status: %w", err)
+	}
+	return status, nil
+}
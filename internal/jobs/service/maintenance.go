@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"portal_final_backend/internal/jobs/repository"
+	"portal_final_backend/internal/jobs/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Maintenance jobs that can be started on demand. The scheduler registers how
+// each job runs.
+const (
+	JobLeadGeocode    = "lead_geocode"
+	JobLeadEnrichment = "lead_enrichment"
+	JobCatalogReembed = "catalog_reembed"
+	JobOutboxRequeue  = "outbox_requeue"
+)
+
+const (
+	paramBatchSize   = "batchSize"
+	paramDryRun      = "dryRun"
+	paramRestart     = "restart"
+	paramFailedSince = "failedSince"
+	paramKind        = "kind"
+)
+
+const defaultRunListLimit = 20
+
+// MaintenanceJob is a job that can be started on demand and the parameters it accepts.
+type MaintenanceJob struct {
+	Name        string
+	Description string
+	Params      []string
+}
+
+var maintenanceJobs = []MaintenanceJob{
+	{
+		Name:        JobLeadGeocode,
+		Description: "Backfill coordinates for older leads without latitude/longitude; new leads are geocoded on creation",
+		Params:      []string{paramBatchSize, paramDryRun, paramRestart},
+	},
+	{
+		Name:        JobLeadEnrichment,
+		Description: "Fetch PDOK/CBS postcode enrichment and re-score leads",
+		Params:      []string{paramBatchSize, paramDryRun, paramRestart},
+	},
+	{
+		Name:        JobCatalogReembed,
+		Description: "Re-embed catalog products with a missing or outdated vector",
+		Params:      []string{paramBatchSize},
+	},
+	{
+		Name:        JobOutboxRequeue,
+		Description: "Move failed notification outbox records back to pending",
+		Params:      []string{paramBatchSize, paramFailedSince, paramKind},
+	},
+}
+
+// RunParams are the parameters of a maintenance run. Each job accepts a subset.
+type RunParams struct {
+	BatchSize   int        `json:"batchSize,omitempty"`
+	DryRun      bool       `json:"dryRun,omitempty"`
+	Restart     bool       `json:"restart,omitempty"`
+	FailedSince *time.Time `json:"failedSince,omitempty"`
+	Kind        string     `json:"kind,omitempty"`
+}
+
+// set returns the names of the parameters that were given.
+func (p RunParams) set() []string {
+	var names []string
+	if p.BatchSize != 0 {
+		names = append(names, paramBatchSize)
+	}
+	if p.DryRun {
+		names = append(names, paramDryRun)
+	}
+	if p.Restart {
+		names = append(names, paramRestart)
+	}
+	if p.FailedSince != nil {
+		names = append(names, paramFailedSince)
+	}
+	if p.Kind != "" {
+		names = append(names, paramKind)
+	}
+	return names
+}
+
+type RunStore interface {
+	CreateRun(ctx context.Context, job string, params json.RawMessage, requestedBy uuid.UUID) (repository.Run, error)
+	GetRun(ctx context.Context, id uuid.UUID) (repository.Run, error)
+	ListRuns(ctx context.Context, job string, limit int) ([]repository.Run, error)
+	CancelRun(ctx context.Context, id uuid.UUID) (bool, error)
+	FinishRun(ctx context.Context, id uuid.UUID, status string, runErr *string) error
+}
+
+type RunEnqueuer interface {
+	EnqueueMaintenanceRun(ctx context.Context, runID uuid.UUID) error
+}
+
+// Maintenance starts, lists and cancels maintenance runs.
+type Maintenance struct {
+	store    RunStore
+	enqueuer RunEnqueuer
+}
+
+func NewMaintenance(store RunStore, enqueuer RunEnqueuer) *Maintenance {
+	return &Maintenance{store: store, enqueuer: enqueuer}
+}
+
+func (m *Maintenance) ListJobs() transport.MaintenanceJobListResponse {
+	items := make([]transport.MaintenanceJobResponse, 0, len(maintenanceJobs))
+	for _, job := range maintenanceJobs {
+		items = append(items, transport.MaintenanceJobResponse{Name: job.Name, Description: job.Description, Params: job.Params})
+	}
+	return transport.MaintenanceJobListResponse{Items: items}
+}
+
+// StartRun queues a run of a maintenance job on the scheduler. A job runs at
+// most once at a time.
+func (m *Maintenance) StartRun(ctx context.Context, userID uuid.UUID, req transport.StartMaintenanceRunRequest) (transport.MaintenanceRunResponse, error) {
+	params := RunParams{BatchSize: req.BatchSize, DryRun: req.DryRun, Restart: req.Restart, FailedSince: req.FailedSince, Kind: req.Kind}
+	if err := validateRunParams(req.Job, params); err != nil {
+		return transport.MaintenanceRunResponse{}, err
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return transport.MaintenanceRunResponse{}, err
+	}
+
+	run, err := m.store.CreateRun(ctx, req.Job, data, userID)
+	if errors.Is(err, repository.ErrRunActive) {
+		return transport.MaintenanceRunResponse{}, apperr.Conflict("maintenance job is already queued or running").WithDetails(map[string]string{"job": req.Job})
+	}
+	if err != nil {
+		return transport.MaintenanceRunResponse{}, err
+	}
+
+	if err := m.enqueuer.EnqueueMaintenanceRun(ctx, run.ID); err != nil {
+		// Fail the run so it does not block the next attempt.
+		msg := "enqueue failed: " + err.Error()
+		_ = m.store.FinishRun(context.WithoutCancel(ctx), run.ID, repository.RunStatusFailed, &msg)
+		return transport.MaintenanceRunResponse{}, err
+	}
+	return toRunResponse(run), nil
+}
+
+func (m *Maintenance) ListRuns(ctx context.Context, req transport.ListMaintenanceRunsRequest) (transport.MaintenanceRunListResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultRunListLimit
+	}
+	runs, err := m.store.ListRuns(ctx, req.Job, limit)
+	if err != nil {
+		return transport.MaintenanceRunListResponse{}, err
+	}
+
+	items := make([]transport.MaintenanceRunResponse, 0, len(runs))
+	for _, run := range runs {
+		items = append(items, toRunResponse(run))
+	}
+	return transport.MaintenanceRunListResponse{Items: items}, nil
+}
+
+func (m *Maintenance) GetRun(ctx context.Context, id uuid.UUID) (transport.MaintenanceRunResponse, error) {
+	run, err := m.store.GetRun(ctx, id)
+	if err != nil {
+		return transport.MaintenanceRunResponse{}, mapRunError(err)
+	}
+	return toRunResponse(run), nil
+}
+
+// CancelRun cancels a queued or running run. A running job stops at its next
+// cancellation check; progress made until then is kept.
+func (m *Maintenance) CancelRun(ctx context.Context, id uuid.UUID) (transport.MaintenanceRunResponse, error) {
+	cancelled, err := m.store.CancelRun(ctx, id)
+	if err != nil {
+		return transport.MaintenanceRunResponse{}, err
+	}
+	run, err := m.store.GetRun(ctx, id)
+	if err != nil {
+		return transport.MaintenanceRunResponse{}, mapRunError(err)
+	}
+	if !cancelled {
+		return transport.MaintenanceRunResponse{}, apperr.Conflict("only queued or running maintenance runs can be cancelled").WithDetails(map[string]string{"status": run.Status})
+	}
+	return toRunResponse(run), nil
+}
+
+func validateRunParams(job string, params RunParams) error {
+	def, ok := findMaintenanceJob(job)
+	if !ok {
+		return apperr.Validation("unknown maintenance job")
+	}
+
+	accepted := make(map[string]bool, len(def.Params))
+	for _, name := range def.Params {
+		accepted[name] = true
+	}
+	var unsupported []string
+	for _, name := range params.set() {
+		if !accepted[name] {
+			unsupported = append(unsupported, name)
+		}
+	}
+	if len(unsupported) > 0 {
+		return apperr.Validation("parameters not supported by this maintenance job").WithDetails(map[string]any{"job": job, "params": unsupported})
+	}
+
+	if job == JobOutboxRequeue && params.FailedSince == nil {
+		return apperr.Validation("failedSince is required to requeue outbox records")
+	}
+	return nil
+}
+
+func findMaintenanceJob(name string) (MaintenanceJob, bool) {
+	for _, job := range maintenanceJobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return MaintenanceJob{}, false
+}
+
+func toRunResponse(run repository.Run) transport.MaintenanceRunResponse {
+	params := map[string]any{}
+	_ = json.Unmarshal(run.Params, &params)
+	return transport.MaintenanceRunResponse{
+		ID:     run.ID,
+		Job:    run.Job,
+		Params: params,
+		Status: run.Status,
+		Progress: transport.MaintenanceRunProgress{
+			Processed: run.Counts.Processed,
+			Updated:   run.Counts.Updated,
+			Skipped:   run.Counts.Skipped,
+			Failed:    run.Counts.Failed,
+		},
+		Error:       run.Error,
+		RequestedBy: run.RequestedBy,
+		CreatedAt:   run.CreatedAt,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		UpdatedAt:   run.UpdatedAt,
+	}
+}
+
+func mapRunError(err error) error {
+	if errors.Is(err, repository.ErrRunNotFound) {
+		return apperr.NotFound("maintenance run not found")
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/jobs/repository"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateRunParams(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+	valid := map[string]RunParams{
+		JobLeadGeocode:    {BatchSize: 10, DryRun: true, Restart: true},
+		JobCatalogReembed: {BatchSize: 10},
+		JobOutboxRequeue:  {FailedSince: &since, Kind: "email"},
+	}
+	for job, params := range valid {
+		if err := validateRunParams(job, params); err != nil {
+			t.Fatalf("%s: unexpected error %v", job, err)
+		}
+	}
+
+	invalid := map[string]RunParams{
+		JobCatalogReembed: {DryRun: true},
+		JobOutboxRequeue:  {Kind: "email"},
+		"unknown":         {},
+	}
+	for job, params := range invalid {
+		if err := validateRunParams(job, params); err == nil {
+			t.Fatalf("%s: expected %+v to be rejected", job, params)
+		}
+	}
+}
+
+type fakeRunStore struct {
+	mu     sync.Mutex
+	run    repository.Run
+	counts repository.RunCounts
+}
+
+func (s *fakeRunStore) GetRun(context.Context, uuid.UUID) (repository.Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.run, nil
+}
+
+func (s *fakeRunStore) StartRun(context.Context, uuid.UUID) (bool, error) {
+	return s.setStatus(repository.RunStatusRunning, repository.RunStatusQueued), nil
+}
+
+func (s *fakeRunStore) UpdateRunCounts(_ context.Context, _ uuid.UUID, counts repository.RunCounts) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts = counts
+	return nil
+}
+
+func (s *fakeRunStore) FinishRun(_ context.Context, _ uuid.UUID, status string, _ *string) error {
+	s.setStatus(status, repository.RunStatusQueued, repository.RunStatusRunning)
+	return nil
+}
+
+func (s *fakeRunStore) GetRunStatus(context.Context, uuid.UUID) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.run.Status, nil
+}
+
+func (s *fakeRunStore) setStatus(status string, from ...string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range from {
+		if s.run.Status == f {
+			s.run.Status = status
+			return true
+		}
+	}
+	return false
+}
+
+func TestRunnerCompletesAndCancelsRuns(t *testing.T) {
+	newStore := func() *fakeRunStore {
+		return &fakeRunStore{run: repository.Run{ID: uuid.New(), Job: JobCatalogReembed, Params: json.RawMessage(`{"batchSize":5}`), Status: repository.RunStatusQueued}}
+	}
+
+	store := newStore()
+	runner := NewRunner(store, logger.New("development"))
+	runner.Register(JobCatalogReembed, func(_ context.Context, params RunParams, report func(repository.RunCounts)) error {
+		report(repository.RunCounts{Processed: params.BatchSize, Updated: params.BatchSize})
+		return nil
+	})
+	if err := runner.ExecuteMaintenanceRun(context.Background(), store.run.ID); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if store.run.Status != repository.RunStatusCompleted || store.counts.Updated != 5 {
+		t.Fatalf("expected completed run with 5 updates, got %s %+v", store.run.Status, store.counts)
+	}
+
+	// A run cancelled while running stops its job and stays cancelled.
+	store = newStore()
+	runner = NewRunner(store, logger.New("development"))
+	runner.pollInterval = time.Millisecond
+	runner.Register(JobCatalogReembed, func(ctx context.Context, _ RunParams, _ func(repository.RunCounts)) error {
+		store.setStatus(repository.RunStatusCancelled, repository.RunStatusRunning)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := runner.ExecuteMaintenanceRun(context.Background(), store.run.ID); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if store.run.Status != repository.RunStatusCancelled {
+		t.Fatalf("expected cancelled run, got %s", store.run.Status)
+	}
+}
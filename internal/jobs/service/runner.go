@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"portal_final_backend/internal/jobs/repository"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const defaultCancelPollInterval = 5 * time.Second
+
+// MaintenanceJobFunc runs a maintenance job. It reports the counts of the run
+// as it makes progress and must stop when ctx is cancelled.
+type MaintenanceJobFunc func(ctx context.Context, params RunParams, report func(repository.RunCounts)) error
+
+type RunnerStore interface {
+	GetRun(ctx context.Context, id uuid.UUID) (repository.Run, error)
+	StartRun(ctx context.Context, id uuid.UUID) (bool, error)
+	UpdateRunCounts(ctx context.Context, id uuid.UUID, counts repository.RunCounts) error
+	FinishRun(ctx context.Context, id uuid.UUID, status string, runErr *string) error
+	GetRunStatus(ctx context.Context, id uuid.UUID) (string, error)
+}
+
+// Runner executes maintenance runs on the scheduler. It stops a job when its
+// run is cancelled from the dashboard.
+type Runner struct {
+	store        RunnerStore
+	jobs         map[string]MaintenanceJobFunc
+	pollInterval time.Duration
+	log          *logger.Logger
+}
+
+func NewRunner(store RunnerStore, log *logger.Logger) *Runner {
+	return &Runner{store: store, jobs: map[string]MaintenanceJobFunc{}, pollInterval: defaultCancelPollInterval, log: log}
+}
+
+// Register sets how a maintenance job runs. Runs of jobs that are not
+// registered fail.
+func (r *Runner) Register(job string, fn MaintenanceJobFunc) {
+	r.jobs[job] = fn
+}
+
+// ExecuteMaintenanceRun runs a queued run and records its outcome. Failures are
+// recorded on the run rather than returned, as runs are not retried.
+func (r *Runner) ExecuteMaintenanceRun(ctx context.Context, runID uuid.UUID) error {
+	run, err := r.store.GetRun(ctx, runID)
+	if errors.Is(err, repository.ErrRunNotFound) {
+		r.log.Warn("maintenance run not found", "runId", runID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	started, err := r.store.StartRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if !started {
+		r.log.Info("maintenance run no longer queued, skipping", "runId", runID, "job", run.Job)
+		return nil
+	}
+
+	fn, ok := r.jobs[run.Job]
+	if !ok {
+		r.finish(ctx, run, errors.New("maintenance job not available on this scheduler"))
+		return nil
+	}
+	var params RunParams
+	if err := json.Unmarshal(run.Params, &params); err != nil {
+		r.finish(ctx, run, err)
+		return nil
+	}
+
+	r.log.Info("maintenance run started", "runId", runID, "job", run.Job)
+	jobCtx, cancel := context.WithCancel(ctx)
+	var cancelled atomic.Bool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if r.waitForCancel(jobCtx, runID) {
+			cancelled.Store(true)
+			cancel()
+		}
+	}()
+
+	report := func(counts repository.RunCounts) {
+		if err := r.store.UpdateRunCounts(context.WithoutCancel(ctx), runID, counts); err != nil {
+			r.log.Warn("failed to record maintenance run progress", "runId", runID, "error", err)
+		}
+	}
+	err = fn(jobCtx, params, report)
+	cancel()
+	wg.Wait()
+
+	if cancelled.Load() {
+		r.log.Info("maintenance run cancelled", "runId", runID, "job", run.Job)
+		return nil
+	}
+	r.finish(ctx, run, err)
+	return nil
+}
+
+// waitForCancel polls the run status until the run is cancelled, which it
+// reports, or ctx is done.
+func (r *Runner) waitForCancel(ctx context.Context, runID uuid.UUID) bool {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			status, err := r.store.GetRunStatus(ctx, runID)
+			if err != nil {
+				continue
+			}
+			if status == repository.RunStatusCancelled {
+				return true
+			}
+		}
+	}
+}
+
+func (r *Runner) finish(ctx context.Context, run repository.Run, runErr error) {
+	status := repository.RunStatusCompleted
+	var msg *string
+	if runErr != nil {
+		status = repository.RunStatusFailed
+		text := runErr.Error()
+		msg = &text
+		r.log.Error("maintenance run failed", "runId", run.ID, "job", run.Job, "error", runErr)
+	} else {
+		r.log.Info("maintenance run completed", "runId", run.ID, "job", run.Job)
+	}
+	if err := r.store.FinishRun(context.WithoutCancel(ctx), run.ID, status, msg); err != nil {
+		r.log.Warn("failed to record maintenance run outcome", "runId", run.ID, "error", err)
+	}
+}
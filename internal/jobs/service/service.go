@@ -0,0 +1,235 @@
+// Package service implements the background job dashboard on top of the asynq
+// inspector: queue depths, task listings, failures, retries and throughput.
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"portal_final_backend/internal/jobs/transport"
+	"portal_final_backend/internal/scheduler"
+	"portal_final_backend/platform/apperr"
+)
+
+const (
+	defaultPageSize        = 20
+	defaultFailureLimit    = 50
+	defaultHistoryDays     = 7
+	defaultThroughputHours = 24
+)
+
+type Inspector interface {
+	Queues() ([]scheduler.QueueStats, error)
+	History(queue string, days int) ([]scheduler.QueueDayStats, error)
+	ListJobs(queue, state string, page, pageSize int) ([]scheduler.JobInfo, error)
+	GetJob(queue, id string) (scheduler.JobInfo, error)
+	RetryJob(queue, id string) error
+	DeleteJob(queue, id string) error
+	Throughput(ctx context.Context, window time.Duration) ([]scheduler.JobTypeStats, error)
+}
+
+type Service struct {
+	inspector Inspector
+}
+
+func New(inspector Inspector) *Service {
+	return &Service{inspector: inspector}
+}
+
+func (s *Service) ListQueues(ctx context.Context) (transport.QueueListResponse, error) {
+	queues, err := s.inspector.Queues()
+	if err != nil {
+		return transport.QueueListResponse{}, mapError(err)
+	}
+
+	items := make([]transport.QueueResponse, 0, len(queues))
+	for _, q := range queues {
+		items = append(items, transport.QueueResponse{
+			Queue:          q.Queue,
+			Size:           q.Size,
+			Pending:        q.Pending,
+			Active:         q.Active,
+			Scheduled:      q.Scheduled,
+			Retry:          q.Retry,
+			Archived:       q.Archived,
+			Completed:      q.Completed,
+			ProcessedToday: q.ProcessedToday,
+			FailedToday:    q.FailedToday,
+			LatencyMs:      q.Latency.Milliseconds(),
+			Paused:         q.Paused,
+			Timestamp:      q.Timestamp,
+		})
+	}
+	return transport.QueueListResponse{Items: items}, nil
+}
+
+func (s *Service) GetQueueHistory(ctx context.Context, queue string, days int) (transport.QueueHistoryResponse, error) {
+	if days <= 0 {
+		days = defaultHistoryDays
+	}
+	stats, err := s.inspector.History(queue, days)
+	if err != nil {
+		return transport.QueueHistoryResponse{}, mapError(err)
+	}
+
+	resp := transport.QueueHistoryResponse{Queue: queue, Days: make([]transport.QueueDayResponse, 0, len(stats))}
+	for _, d := range stats {
+		resp.Days = append(resp.Days, transport.QueueDayResponse{Date: d.Date, Processed: d.Processed, Failed: d.Failed})
+	}
+	return resp, nil
+}
+
+func (s *Service) ListJobs(ctx context.Context, queue string, req transport.ListJobsRequest) (transport.JobListResponse, error) {
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	jobs, err := s.inspector.ListJobs(queue, req.State, req.Page, pageSize)
+	if err != nil {
+		return transport.JobListResponse{}, mapError(err)
+	}
+	return transport.JobListResponse{Items: toJobResponses(jobs)}, nil
+}
+
+// ListRecentFailures returns retrying and archived tasks across all queues, most
+// recently failed first.
+func (s *Service) ListRecentFailures(ctx context.Context, limit int) (transport.JobListResponse, error) {
+	if limit <= 0 {
+		limit = defaultFailureLimit
+	}
+	queues, err := s.inspector.Queues()
+	if err != nil {
+		return transport.JobListResponse{}, mapError(err)
+	}
+
+	failed := make([]scheduler.JobInfo, 0)
+	for _, q := range queues {
+		for _, state := range []string{scheduler.JobStateRetry, scheduler.JobStateArchived} {
+			jobs, err := s.inspector.ListJobs(q.Queue, state, 1, limit)
+			if err != nil {
+				return transport.JobListResponse{}, mapError(err)
+			}
+			failed = append(failed, jobs...)
+		}
+	}
+
+	sort.SliceStable(failed, func(i, j int) bool {
+		return failedAt(failed[i]).After(failedAt(failed[j]))
+	})
+	if len(failed) > limit {
+		failed = failed[:limit]
+	}
+	return transport.JobListResponse{Items: toJobResponses(failed)}, nil
+}
+
+func (s *Service) GetJob(ctx context.Context, queue, id string) (transport.JobResponse, error) {
+	job, err := s.inspector.GetJob(queue, id)
+	if err != nil {
+		return transport.JobResponse{}, mapError(err)
+	}
+	return toJobResponse(job), nil
+}
+
+// RetryJob runs a scheduled, retrying or archived task immediately.
+func (s *Service) RetryJob(ctx context.Context, queue, id string) (transport.JobResponse, error) {
+	job, err := s.inspector.GetJob(queue, id)
+	if err != nil {
+		return transport.JobResponse{}, mapError(err)
+	}
+	switch job.State {
+	case scheduler.JobStateScheduled, scheduler.JobStateRetry, scheduler.JobStateArchived:
+	default:
+		return transport.JobResponse{}, apperr.Conflict("only scheduled, retrying or archived jobs can be retried").WithDetails(map[string]string{"state": job.State})
+	}
+
+	if err := s.inspector.RetryJob(queue, id); err != nil {
+		return transport.JobResponse{}, mapError(err)
+	}
+	return s.GetJob(ctx, queue, id)
+}
+
+// DeleteJob removes a task that is not being processed.
+func (s *Service) DeleteJob(ctx context.Context, queue, id string) error {
+	job, err := s.inspector.GetJob(queue, id)
+	if err != nil {
+		return mapError(err)
+	}
+	if job.State == scheduler.JobStateActive {
+		return apperr.Conflict("active jobs cannot be deleted")
+	}
+	return mapError(s.inspector.DeleteJob(queue, id))
+}
+
+func (s *Service) GetThroughput(ctx context.Context, hours int) (transport.ThroughputResponse, error) {
+	if hours <= 0 {
+		hours = defaultThroughputHours
+	}
+	stats, err := s.inspector.Throughput(ctx, time.Duration(hours)*time.Hour)
+	if err != nil {
+		return transport.ThroughputResponse{}, mapError(err)
+	}
+
+	resp := transport.ThroughputResponse{WindowHours: hours, Items: make([]transport.JobTypeThroughputResponse, 0, len(stats))}
+	for _, st := range stats {
+		item := transport.JobTypeThroughputResponse{
+			Type:          st.Type,
+			Processed:     st.Processed,
+			Failed:        st.Failed,
+			AvgDurationMs: st.AvgDurationMs,
+		}
+		if st.Processed > 0 {
+			item.FailureRate = float64(st.Failed) / float64(st.Processed)
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	return resp, nil
+}
+
+func failedAt(job scheduler.JobInfo) time.Time {
+	if job.LastFailedAt == nil {
+		return time.Time{}
+	}
+	return *job.LastFailedAt
+}
+
+func toJobResponses(jobs []scheduler.JobInfo) []transport.JobResponse {
+	items := make([]transport.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		items = append(items, toJobResponse(job))
+	}
+	return items
+}
+
+func toJobResponse(job scheduler.JobInfo) transport.JobResponse {
+	return transport.JobResponse{
+		ID:               job.ID,
+		Queue:            job.Queue,
+		Type:             job.Type,
+		State:            job.State,
+		PayloadPreview:   job.PayloadPreview,
+		PayloadTruncated: job.PayloadTruncated,
+		MaxRetry:         job.MaxRetry,
+		Retried:          job.Retried,
+		LastError:        job.LastError,
+		LastFailedAt:     job.LastFailedAt,
+		NextProcessAt:    job.NextProcessAt,
+		CompletedAt:      job.CompletedAt,
+	}
+}
+
+func mapError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		return apperr.NotFound("job not found")
+	case errors.Is(err, scheduler.ErrQueueNotFound):
+		return apperr.NotFound("queue not found")
+	case errors.Is(err, scheduler.ErrUnknownJobState):
+		return apperr.Validation("unknown job state")
+	default:
+		return err
+	}
+}
@@ -0,0 +1,88 @@
+// Package transport provides DTOs for the background job dashboard.
+package transport
+
+import "time"
+
+type QueueResponse struct {
+	Queue          string    `json:"queue"`
+	Size           int       `json:"size"`
+	Pending        int       `json:"pending"`
+	Active         int       `json:"active"`
+	Scheduled      int       `json:"scheduled"`
+	Retry          int       `json:"retry"`
+	Archived       int       `json:"archived"`
+	Completed      int       `json:"completed"`
+	ProcessedToday int       `json:"processedToday"`
+	FailedToday    int       `json:"failedToday"`
+	LatencyMs      int64     `json:"latencyMs"`
+	Paused         bool      `json:"paused"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+type QueueListResponse struct {
+	Items []QueueResponse `json:"items"`
+}
+
+type QueueDayResponse struct {
+	Date      time.Time `json:"date"`
+	Processed int       `json:"processed"`
+	Failed    int       `json:"failed"`
+}
+
+type QueueHistoryResponse struct {
+	Queue string             `json:"queue"`
+	Days  []QueueDayResponse `json:"days"`
+}
+
+// JobResponse describes a task. Payloads are cut to a short preview.
+type JobResponse struct {
+	ID               string     `json:"id"`
+	Queue            string     `json:"queue"`
+	Type             string     `json:"type"`
+	State            string     `json:"state"`
+	PayloadPreview   string     `json:"payloadPreview"`
+	PayloadTruncated bool       `json:"payloadTruncated"`
+	MaxRetry         int        `json:"maxRetry"`
+	Retried          int        `json:"retried"`
+	LastError        string     `json:"lastError,omitempty"`
+	LastFailedAt     *time.Time `json:"lastFailedAt,omitempty"`
+	NextProcessAt    *time.Time `json:"nextProcessAt,omitempty"`
+	CompletedAt      *time.Time `json:"completedAt,omitempty"`
+}
+
+type JobListResponse struct {
+	Items []JobResponse `json:"items"`
+}
+
+type JobTypeThroughputResponse struct {
+	Type          string  `json:"type"`
+	Processed     int64   `json:"processed"`
+	Failed        int64   `json:"failed"`
+	FailureRate   float64 `json:"failureRate"`
+	AvgDurationMs int64   `json:"avgDurationMs"`
+}
+
+type ThroughputResponse struct {
+	WindowHours int                         `json:"windowHours"`
+	Items       []JobTypeThroughputResponse `json:"items"`
+}
+
+// ListJobsRequest selects one page of tasks in a queue.
+type ListJobsRequest struct {
+	State    string `form:"state" validate:"required,oneof=pending active scheduled retry archived completed"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+// ListFailuresRequest limits the recent failures across all queues.
+type ListFailuresRequest struct {
+	Limit int `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+type HistoryRequest struct {
+	Days int `form:"days" validate:"omitempty,min=1,max=90"`
+}
+
+type ThroughputRequest struct {
+	Hours int `form:"hours" validate:"omitempty,min=1,max=192"`
+}
@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceJobResponse describes a maintenance job that can be started on
+// demand and the parameters it accepts.
+type MaintenanceJobResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Params      []string `json:"params"`
+}
+
+type MaintenanceJobListResponse struct {
+	Items []MaintenanceJobResponse `json:"items"`
+}
+
+// StartMaintenanceRunRequest starts a maintenance job. Parameters the job does
+// not accept are rejected.
+type StartMaintenanceRunRequest struct {
+	Job         string     `json:"job" validate:"required,oneof=lead_geocode lead_enrichment catalog_reembed outbox_requeue"`
+	BatchSize   int        `json:"batchSize" validate:"omitempty,min=1,max=500"`
+	DryRun      bool       `json:"dryRun"`
+	Restart     bool       `json:"restart"`
+	FailedSince *time.Time `json:"failedSince"`
+	Kind        string     `json:"kind" validate:"omitempty,max=100"`
+}
+
+type ListMaintenanceRunsRequest struct {
+	Job   string `form:"job" validate:"omitempty,max=100"`
+	Limit int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type MaintenanceRunProgress struct {
+	Processed int `json:"processed"`
+	Updated   int `json:"updated"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+}
+
+type MaintenanceRunResponse struct {
+	ID          uuid.UUID              `json:"id"`
+	Job         string                 `json:"job"`
+	Params      map[string]any         `json:"params"`
+	Status      string                 `json:"status"`
+	Progress    MaintenanceRunProgress `json:"progress"`
+	Error       *string                `json:"error,omitempty"`
+	RequestedBy *uuid.UUID             `json:"requestedBy,omitempty"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	StartedAt   *time.Time             `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time             `json:"finishedAt,omitempty"`
+	UpdatedAt   time.Time              `json:"updatedAt"`
+}
+
+type MaintenanceRunListResponse struct {
+	Items []MaintenanceRunResponse `json:"items"`
+}
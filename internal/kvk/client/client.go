@@ -0,0 +1,247 @@
+// Package client provides the HTTP client for the KvK (Kamer van Koophandel) API.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/kvk/transport"
+	"portal_final_backend/platform/logger"
+)
+
+const (
+	defaultBaseURL = "https://api.kvk.nl"
+	kvkDateLayout  = "20060102"
+)
+
+// Client provides access to the KvK search and basisprofiel APIs.
+type Client struct {
+	httpClient *http.Client
+	log        *logger.Logger
+	baseURL    string
+	apiKey     string
+}
+
+func New(baseURL, apiKey string, log *logger.Logger) *Client {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		log:        log,
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+	}
+}
+
+// Search looks up registrations by name and/or KvK number.
+// Returns an empty slice when nothing matches.
+func (c *Client) Search(ctx context.Context, name, kvkNumber string) ([]transport.SearchResult, error) {
+	v := url.Values{}
+	if name != "" {
+		v.Set("naam", name)
+	}
+	if kvkNumber != "" {
+		v.Set("kvkNummer", kvkNumber)
+	}
+	v.Set("resultatenPerPagina", "10")
+
+	var raw apiSearchResponse
+	found, err := c.getJSON(ctx, fmt.Sprintf("%s/api/v2/zoeken?%s", c.baseURL, v.Encode()), &raw)
+	if err != nil || !found {
+		return []transport.SearchResult{}, err
+	}
+
+	res := make([]transport.SearchResult, 0, len(raw.Resultaten))
+	for _, r := range raw.Resultaten {
+		res = append(res, transport.SearchResult{
+			KVKNumber: r.KvkNummer,
+			Name:      r.Naam,
+			Type:      r.Type,
+			City:      r.Adres.BinnenlandsAdres.Plaats,
+		})
+	}
+	return res, nil
+}
+
+// GetBasisprofiel fetches the basic company profile for a KvK number.
+// Returns (nil, nil) when the KvK number is not registered.
+func (c *Client) GetBasisprofiel(ctx context.Context, kvkNumber string) (*transport.CompanyProfile, error) {
+	var raw apiBasisprofiel
+	found, err := c.getJSON(ctx, fmt.Sprintf("%s/api/v1/basisprofielen/%s", c.baseURL, url.PathEscape(kvkNumber)), &raw)
+	if err != nil || !found {
+		return nil, err
+	}
+	profile := raw.toTransport()
+	return &profile, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, reqURL string, dst any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("apikey", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("kvk http: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+			return false, fmt.Errorf("kvk decode: %w", err)
+		}
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, fmt.Errorf("kvk: unauthorized")
+	default:
+		return false, fmt.Errorf("kvk: upstream error %d", resp.StatusCode)
+	}
+}
+
+type apiSearchResponse struct {
+	Resultaten []struct {
+		KvkNummer string `json:"kvkNummer"`
+		Naam      string `json:"naam"`
+		Type      string `json:"type"`
+		Adres     struct {
+			BinnenlandsAdres struct {
+				Plaats string `json:"plaats"`
+			} `json:"binnenlandsAdres"`
+		} `json:"adres"`
+	} `json:"resultaten"`
+}
+
+type apiAdres struct {
+	Type                 string `json:"type"`
+	Straatnaam           string `json:"straatnaam"`
+	Huisnummer           int    `json:"huisnummer"`
+	Huisletter           string `json:"huisletter"`
+	HuisnummerToevoeging string `json:"huisnummerToevoeging"`
+	Postcode             string `json:"postcode"`
+	Plaats               string `json:"plaats"`
+	Land                 string `json:"land"`
+}
+
+type apiBasisprofiel struct {
+	KvkNummer      string `json:"kvkNummer"`
+	Naam           string `json:"naam"`
+	StatutaireNaam string `json:"statutaireNaam"`
+	Handelsnamen   []struct {
+		Naam string `json:"naam"`
+	} `json:"handelsnamen"`
+	MaterieleRegistratie struct {
+		DatumAanvang string `json:"datumAanvang"`
+		DatumEinde   string `json:"datumEinde"`
+	} `json:"materieleRegistratie"`
+	SBIActiviteiten []struct {
+		SBICode            string `json:"sbiCode"`
+		SBIOmschrijving    string `json:"sbiOmschrijving"`
+		IndHoofdactiviteit string `json:"indHoofdactiviteit"`
+	} `json:"sbiActiviteiten"`
+	Embedded struct {
+		Hoofdvestiging struct {
+			Adressen []apiAdres `json:"adressen"`
+		} `json:"hoofdvestiging"`
+	} `json:"_embedded"`
+}
+
+func (p apiBasisprofiel) toTransport() transport.CompanyProfile {
+	legalName := p.StatutaireNaam
+	if legalName == "" {
+		legalName = p.Naam
+	}
+
+	tradeNames := make([]string, 0, len(p.Handelsnamen))
+	for _, h := range p.Handelsnamen {
+		if h.Naam != "" {
+			tradeNames = append(tradeNames, h.Naam)
+		}
+	}
+
+	sbi := make([]transport.SBIActivity, 0, len(p.SBIActiviteiten))
+	for _, a := range p.SBIActiviteiten {
+		sbi = append(sbi, transport.SBIActivity{
+			Code:        a.SBICode,
+			Description: a.SBIOmschrijving,
+			Primary:     strings.EqualFold(a.IndHoofdactiviteit, "Ja"),
+		})
+	}
+
+	dissolvedAt := parseKvKDate(p.MaterieleRegistratie.DatumEinde)
+	return transport.CompanyProfile{
+		KVKNumber:    p.KvkNummer,
+		LegalName:    legalName,
+		TradeNames:   tradeNames,
+		Address:      visitingAddress(p.Embedded.Hoofdvestiging.Adressen),
+		SBICodes:     sbi,
+		Active:       dissolvedAt == nil,
+		RegisteredAt: parseKvKDate(p.MaterieleRegistratie.DatumAanvang),
+		DissolvedAt:  dissolvedAt,
+		FetchedAt:    time.Now().UTC(),
+	}
+}
+
+// visitingAddress prefers the "bezoekadres" and falls back to the first address.
+func visitingAddress(adressen []apiAdres) transport.Address {
+	if len(adressen) == 0 {
+		return transport.Address{}
+	}
+	a := adressen[0]
+	for _, candidate := range adressen {
+		if strings.EqualFold(candidate.Type, "bezoekadres") {
+			a = candidate
+			break
+		}
+	}
+
+	houseNumber := ""
+	if a.Huisnummer > 0 {
+		houseNumber = strconv.Itoa(a.Huisnummer) + a.Huisletter
+		if a.HuisnummerToevoeging != "" {
+			houseNumber += "-" + a.HuisnummerToevoeging
+		}
+	}
+	return transport.Address{
+		Street:      a.Straatnaam,
+		HouseNumber: houseNumber,
+		PostalCode:  a.Postcode,
+		City:        a.Plaats,
+		Country:     a.Land,
+	}
+}
+
+// parseKvKDate parses KvK dates (yyyymmdd). Partial dates such as "20200000" are
+// clamped to the first day of the known period.
+func parseKvKDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if len(s) != len(kvkDateLayout) {
+		return nil
+	}
+	month, day := s[4:6], s[6:]
+	if month == "00" {
+		month = "01"
+	}
+	if day == "00" {
+		day = "01"
+	}
+	t, err := time.Parse(kvkDateLayout, s[:4]+month+day)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
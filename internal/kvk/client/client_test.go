@@ -0,0 +1,43 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const basisprofielFixture = `{
+	"kvkNummer": "12345678",
+	"naam": "Jansen Bouw",
+	"statutaireNaam": "Jansen Bouw B.V.",
+	"handelsnamen": [{"naam": "Jansen Bouw", "volgorde": 0}],
+	"materieleRegistratie": {"datumAanvang": "20100315", "datumEinde": "20240100"},
+	"sbiActiviteiten": [
+		{"sbiCode": "4120", "sbiOmschrijving": "Algemene burgerlijke en utiliteitsbouw", "indHoofdactiviteit": "Ja"},
+		{"sbiCode": "4332", "sbiOmschrijving": "Timmerwerk", "indHoofdactiviteit": "Nee"}
+	],
+	"_embedded": {"hoofdvestiging": {"adressen": [
+		{"type": "postadres", "straatnaam": "Postbus", "huisnummer": 1, "postcode": "1000AA", "plaats": "Amsterdam"},
+		{"type": "bezoekadres", "straatnaam": "Kerkstraat", "huisnummer": 12, "huisletter": "a", "postcode": "1234AB", "plaats": "Utrecht", "land": "Nederland"}
+	]}}
+}`
+
+func TestBasisprofielToTransport(t *testing.T) {
+	var raw apiBasisprofiel
+	if err := json.Unmarshal([]byte(basisprofielFixture), &raw); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+	p := raw.toTransport()
+
+	if p.LegalName != "Jansen Bouw B.V." {
+		t.Fatalf("expected statutory name, got %q", p.LegalName)
+	}
+	if p.Address.Street != "Kerkstraat" || p.Address.HouseNumber != "12a" || p.Address.City != "Utrecht" {
+		t.Fatalf("expected visiting address, got %+v", p.Address)
+	}
+	if len(p.SBICodes) != 2 || !p.SBICodes[0].Primary || p.SBICodes[1].Primary {
+		t.Fatalf("unexpected SBI codes: %+v", p.SBICodes)
+	}
+	if p.Active || p.DissolvedAt == nil || p.DissolvedAt.Format("2006-01-02") != "2024-01-01" {
+		t.Fatalf("expected dissolved profile with clamped end date, got active=%v dissolvedAt=%v", p.Active, p.DissolvedAt)
+	}
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/kvk/service"
+	"portal_final_backend/internal/kvk/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	msgInvalidRequest   = "invalid request"
+	msgValidationFailed = "validation failed"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/search", h.Search)
+	rg.GET("/profiles/:kvkNumber", h.GetProfile)
+	rg.GET("/partners/:partnerId", h.GetPartner)
+	rg.PUT("/partners/:partnerId", h.EnrichPartner)
+	rg.POST("/partners/:partnerId/refresh", h.RefreshPartner)
+	rg.GET("/leads/:leadId", h.GetLead)
+	rg.PUT("/leads/:leadId", h.EnrichLead)
+	rg.POST("/leads/:leadId/refresh", h.RefreshLead)
+}
+
+func (h *Handler) Search(c *gin.Context) {
+	var req transport.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	results, err := h.svc.Search(c.Request.Context(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, results)
+}
+
+func (h *Handler) GetProfile(c *gin.Context) {
+	resp, err := h.svc.GetProfile(c.Request.Context(), c.Param("kvkNumber"), c.Query("refresh") == "true")
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetPartner(c *gin.Context) {
+	partnerID, tenantID, ok := parseEntity(c, "partnerId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetPartnerEnrichment(c.Request.Context(), tenantID, partnerID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) EnrichPartner(c *gin.Context) {
+	partnerID, tenantID, ok := parseEntity(c, "partnerId")
+	if !ok {
+		return
+	}
+	req, ok := h.bindEnrich(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.EnrichPartner(c.Request.Context(), tenantID, partnerID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) RefreshPartner(c *gin.Context) {
+	partnerID, tenantID, ok := parseEntity(c, "partnerId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.RefreshPartner(c.Request.Context(), tenantID, partnerID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetLead(c *gin.Context) {
+	leadID, tenantID, ok := parseEntity(c, "leadId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetLeadEnrichment(c.Request.Context(), tenantID, leadID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) EnrichLead(c *gin.Context) {
+	leadID, tenantID, ok := parseEntity(c, "leadId")
+	if !ok {
+		return
+	}
+	req, ok := h.bindEnrich(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.EnrichLead(c.Request.Context(), tenantID, leadID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) RefreshLead(c *gin.Context) {
+	leadID, tenantID, ok := parseEntity(c, "leadId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.RefreshLead(c.Request.Context(), tenantID, leadID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) bindEnrich(c *gin.Context) (transport.EnrichRequest, bool) {
+	var req transport.EnrichRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return req, false
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+func parseEntity(c *gin.Context, param string) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, tenantID, true
+}
@@ -0,0 +1,26 @@
+// Package kvk defines the public contract for the KvK (Dutch Chamber of Commerce) integration.
+// Only types and interfaces defined here should be imported by external domains.
+package kvk
+
+import (
+	"context"
+
+	"portal_final_backend/internal/kvk/transport"
+
+	"github.com/google/uuid"
+)
+
+// Service defines the public API for KvK lookups and entity enrichment.
+type Service interface {
+	// Search looks up registrations by company name and/or KvK number.
+	Search(ctx context.Context, req transport.SearchRequest) ([]transport.SearchResult, error)
+
+	// GetProfile returns the (cached) basic profile of a registered company.
+	GetProfile(ctx context.Context, kvkNumber string, forceRefresh bool) (*transport.CompanyProfile, error)
+
+	// EnrichPartner links a partner to its KvK registration.
+	EnrichPartner(ctx context.Context, organizationID, partnerID uuid.UUID, req transport.EnrichRequest) (transport.EnrichmentResponse, error)
+
+	// EnrichLead links a business lead to its KvK registration.
+	EnrichLead(ctx context.Context, organizationID, leadID uuid.UUID, req transport.EnrichRequest) (transport.EnrichmentResponse, error)
+}
@@ -0,0 +1,69 @@
+// Package kvk provides the KvK enrichment bounded context module.
+package kvk
+
+import (
+	"portal_final_backend/internal/events"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/internal/kvk/client"
+	"portal_final_backend/internal/kvk/handler"
+	"portal_final_backend/internal/kvk/repository"
+	"portal_final_backend/internal/kvk/service"
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ Service = (*service.Service)(nil)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+	enabled bool
+}
+
+// NewModule creates the KvK module. Without KVK_API_KEY it returns a disabled module.
+func NewModule(pool *pgxpool.Pool, eventBus events.Bus, val *validator.Validator, cfg config.KvKConfig, log *logger.Logger) *Module {
+	if !cfg.IsKvKEnabled() {
+		log.Info("kvk module disabled: KVK_API_KEY unset")
+		return &Module{enabled: false}
+	}
+
+	apiClient := client.New(cfg.GetKvKAPIBaseURL(), cfg.GetKvKAPIKey(), log)
+	svc := service.New(repository.New(pool), apiClient, eventBus, log)
+
+	log.Info("kvk module initialized successfully")
+
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+		enabled: true,
+	}
+}
+
+// Service returns the KvK service, or nil when the module is disabled.
+func (m *Module) Service() *service.Service {
+	if !m.IsEnabled() {
+		return nil
+	}
+	return m.service
+}
+
+// IsEnabled reports whether the KvK API is configured. Safe to call on a nil receiver.
+func (m *Module) IsEnabled() bool {
+	return m != nil && m.enabled
+}
+
+func (m *Module) Name() string {
+	return "kvk"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	if !m.IsEnabled() {
+		return
+	}
+	m.handler.RegisterRoutes(ctx.Protected.Group("/kvk"))
+}
+
+var _ apphttp.Module = (*Module)(nil)
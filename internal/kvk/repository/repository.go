@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/internal/kvk/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const enrichmentNotFoundMsg = "kvk enrichment not found"
+
+// Repository persists cached KvK profiles and entity links.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Enrichment links a partner or lead to a KvK registration.
+type Enrichment struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	EntityType     string
+	EntityID       uuid.UUID
+	KVKNumber      string
+	Status         string
+	LastCheckedAt  time.Time
+	FlaggedAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// PartnerCompany is the registration data stored on a partner record.
+type PartnerCompany struct {
+	BusinessName string
+	KVKNumber    string
+}
+
+const enrichmentColumns = `id, organization_id, entity_type, entity_id, kvk_number, status, last_checked_at, flagged_at, created_at, updated_at`
+
+func scanEnrichment(row pgx.Row) (Enrichment, error) {
+	var e Enrichment
+	err := row.Scan(&e.ID, &e.OrganizationID, &e.EntityType, &e.EntityID, &e.KVKNumber, &e.Status, &e.LastCheckedAt, &e.FlaggedAt, &e.CreatedAt, &e.UpdatedAt)
+	return e, err
+}
+
+// GetProfile returns a cached profile, or (nil, nil) when it has never been fetched.
+func (r *Repository) GetProfile(ctx context.Context, kvkNumber string) (*transport.CompanyProfile, error) {
+	var (
+		p       transport.CompanyProfile
+		sbiJSON []byte
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT kvk_number, legal_name, trade_names, COALESCE(street, ''), COALESCE(house_number, ''),
+			COALESCE(postal_code, ''), COALESCE(city, ''), COALESCE(country, ''), sbi_codes, active,
+			registered_at, dissolved_at, fetched_at
+		FROM RAC_kvk_profiles
+		WHERE kvk_number = $1
+	`, kvkNumber).Scan(
+		&p.KVKNumber, &p.LegalName, &p.TradeNames, &p.Address.Street, &p.Address.HouseNumber,
+		&p.Address.PostalCode, &p.Address.City, &p.Address.Country, &sbiJSON, &p.Active,
+		&p.RegisteredAt, &p.DissolvedAt, &p.FetchedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get kvk profile: %w", err)
+	}
+	if err := json.Unmarshal(sbiJSON, &p.SBICodes); err != nil {
+		return nil, fmt.Errorf("decode kvk sbi codes: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *Repository) UpsertProfile(ctx context.Context, p transport.CompanyProfile) error {
+	sbiJSON, err := json.Marshal(p.SBICodes)
+	if err != nil {
+		return fmt.Errorf("encode kvk sbi codes: %w", err)
+	}
+	tradeNames := p.TradeNames
+	if tradeNames == nil {
+		tradeNames = []string{}
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO RAC_kvk_profiles (
+			kvk_number, legal_name, trade_names, street, house_number, postal_code, city, country,
+			sbi_codes, active, registered_at, dissolved_at, fetched_at
+		) VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11, $12, $13)
+		ON CONFLICT (kvk_number) DO UPDATE SET
+			legal_name = EXCLUDED.legal_name,
+			trade_names = EXCLUDED.trade_names,
+			street = EXCLUDED.street,
+			house_number = EXCLUDED.house_number,
+			postal_code = EXCLUDED.postal_code,
+			city = EXCLUDED.city,
+			country = EXCLUDED.country,
+			sbi_codes = EXCLUDED.sbi_codes,
+			active = EXCLUDED.active,
+			registered_at = EXCLUDED.registered_at,
+			dissolved_at = EXCLUDED.dissolved_at,
+			fetched_at = EXCLUDED.fetched_at
+	`, p.KVKNumber, p.LegalName, tradeNames, p.Address.Street, p.Address.HouseNumber, p.Address.PostalCode,
+		p.Address.City, p.Address.Country, sbiJSON, p.Active, p.RegisteredAt, p.DissolvedAt, p.FetchedAt)
+	if err != nil {
+		return fmt.Errorf("upsert kvk profile: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetEnrichment(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID) (Enrichment, error) {
+	e, err := scanEnrichment(r.pool.QueryRow(ctx, `
+		SELECT `+enrichmentColumns+`
+		FROM RAC_kvk_enrichments
+		WHERE organization_id = $1 AND entity_type = $2 AND entity_id = $3
+	`, organizationID, entityType, entityID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Enrichment{}, apperr.NotFound(enrichmentNotFoundMsg)
+		}
+		return Enrichment{}, fmt.Errorf("get kvk enrichment: %w", err)
+	}
+	return e, nil
+}
+
+// UpsertEnrichment links an entity to a KvK number and records the latest check result.
+// flagged_at is set the first time a link turns dissolved and cleared when it becomes active again.
+func (r *Repository) UpsertEnrichment(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID, kvkNumber, status string) (Enrichment, error) {
+	e, err := scanEnrichment(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_kvk_enrichments (organization_id, entity_type, entity_id, kvk_number, status, last_checked_at, flagged_at)
+		VALUES ($1, $2, $3, $4, $5, now(), CASE WHEN $5 = 'dissolved' THEN now() END)
+		ON CONFLICT (organization_id, entity_type, entity_id) DO UPDATE SET
+			kvk_number = EXCLUDED.kvk_number,
+			status = EXCLUDED.status,
+			last_checked_at = now(),
+			flagged_at = CASE
+				WHEN EXCLUDED.status <> 'dissolved' THEN NULL
+				WHEN RAC_kvk_enrichments.kvk_number = EXCLUDED.kvk_number THEN COALESCE(RAC_kvk_enrichments.flagged_at, now())
+				ELSE now()
+			END,
+			updated_at = now()
+		RETURNING `+enrichmentColumns,
+		organizationID, entityType, entityID, kvkNumber, status,
+	))
+	if err != nil {
+		return Enrichment{}, fmt.Errorf("upsert kvk enrichment: %w", err)
+	}
+	return e, nil
+}
+
+// ListStaleEnrichments returns links whose last check is older than the cutoff, oldest first.
+func (r *Repository) ListStaleEnrichments(ctx context.Context, checkedBefore time.Time, limit int) ([]Enrichment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+enrichmentColumns+`
+		FROM RAC_kvk_enrichments
+		WHERE last_checked_at < $1
+		ORDER BY last_checked_at ASC
+		LIMIT $2
+	`, checkedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list stale kvk enrichments: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Enrichment, 0)
+	for rows.Next() {
+		e, err := scanEnrichment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan kvk enrichment: %w", err)
+		}
+		items = append(items, e)
+	}
+	return items, rows.Err()
+}
+
+func (r *Repository) GetPartnerCompany(ctx context.Context, organizationID, partnerID uuid.UUID) (PartnerCompany, error) {
+	var (
+		pc        PartnerCompany
+		kvkNumber *string
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT business_name, kvk_number FROM RAC_partners WHERE id = $1 AND organization_id = $2
+	`, partnerID, organizationID).Scan(&pc.BusinessName, &kvkNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PartnerCompany{}, apperr.NotFound("partner not found")
+		}
+		return PartnerCompany{}, fmt.Errorf("get partner company: %w", err)
+	}
+	if kvkNumber != nil {
+		pc.KVKNumber = *kvkNumber
+	}
+	return pc, nil
+}
+
+func (r *Repository) LeadExists(ctx context.Context, organizationID, leadID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM RAC_leads WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL)
+	`, leadID, organizationID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check lead exists: %w", err)
+	}
+	return exists, nil
+}
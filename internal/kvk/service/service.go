@@ -0,0 +1,378 @@
+// Package service provides KvK company lookups and the enrichment of partners and leads.
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/kvk/client"
+	"portal_final_backend/internal/kvk/repository"
+	"portal_final_backend/internal/kvk/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// profileCacheTTL bounds how long a cached profile is served before the KvK API is asked again.
+	profileCacheTTL = 7 * 24 * time.Hour
+
+	// DefaultRevalidationAge is the age after which a link is re-checked by the periodic revalidation.
+	DefaultRevalidationAge = 30 * 24 * time.Hour
+
+	kvkNumberLength = 8
+)
+
+// Service resolves KvK registrations and keeps partner/lead links up to date.
+type Service struct {
+	repo     *repository.Repository
+	client   *client.Client
+	eventBus events.Bus
+	log      *logger.Logger
+}
+
+func New(repo *repository.Repository, apiClient *client.Client, eventBus events.Bus, log *logger.Logger) *Service {
+	return &Service{repo: repo, client: apiClient, eventBus: eventBus, log: log}
+}
+
+// RevalidationResult summarises a revalidation sweep.
+type RevalidationResult struct {
+	Checked   int
+	Dissolved int
+	NotFound  int
+	Failed    int
+}
+
+// Search looks up registrations by company name and/or KvK number.
+func (s *Service) Search(ctx context.Context, req transport.SearchRequest) ([]transport.SearchResult, error) {
+	kvkNumber := ""
+	if req.KVKNumber != "" {
+		normalized, err := normalizeKVKNumber(req.KVKNumber)
+		if err != nil {
+			return nil, err
+		}
+		kvkNumber = normalized
+	}
+	return s.client.Search(ctx, strings.TrimSpace(req.Name), kvkNumber)
+}
+
+// GetProfile returns the company profile for a KvK number, served from cache unless forceRefresh is set.
+func (s *Service) GetProfile(ctx context.Context, kvkNumber string, forceRefresh bool) (*transport.CompanyProfile, error) {
+	normalized, err := normalizeKVKNumber(kvkNumber)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.lookup(ctx, normalized, forceRefresh)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, apperr.NotFound("KvK number is not registered")
+	}
+	return profile, nil
+}
+
+// GetPartnerEnrichment returns the KvK link of a partner.
+func (s *Service) GetPartnerEnrichment(ctx context.Context, organizationID, partnerID uuid.UUID) (transport.EnrichmentResponse, error) {
+	if _, err := s.repo.GetPartnerCompany(ctx, organizationID, partnerID); err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	return s.getEnrichment(ctx, organizationID, transport.EntityPartner, partnerID)
+}
+
+// EnrichPartner links a partner to a KvK registration. Without an explicit KvK number or
+// company name, the partner's own KvK number (or business name) is used.
+func (s *Service) EnrichPartner(ctx context.Context, organizationID, partnerID uuid.UUID, req transport.EnrichRequest) (transport.EnrichmentResponse, error) {
+	partner, err := s.repo.GetPartnerCompany(ctx, organizationID, partnerID)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	if req.KVKNumber == "" && req.CompanyName == "" {
+		req.KVKNumber = partner.KVKNumber
+		req.CompanyName = partner.BusinessName
+	}
+	return s.enrich(ctx, organizationID, transport.EntityPartner, partnerID, req)
+}
+
+// RefreshPartner re-fetches the partner's KvK profile, bypassing the cache.
+func (s *Service) RefreshPartner(ctx context.Context, organizationID, partnerID uuid.UUID) (transport.EnrichmentResponse, error) {
+	partner, err := s.repo.GetPartnerCompany(ctx, organizationID, partnerID)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+
+	kvkNumber := partner.KVKNumber
+	if link, err := s.repo.GetEnrichment(ctx, organizationID, transport.EntityPartner, partnerID); err == nil {
+		kvkNumber = link.KVKNumber
+	} else if !apperr.Is(err, apperr.KindNotFound) {
+		return transport.EnrichmentResponse{}, err
+	}
+	if kvkNumber == "" {
+		return transport.EnrichmentResponse{}, apperr.Validation("partner has no KvK number")
+	}
+	return s.refresh(ctx, organizationID, transport.EntityPartner, partnerID, kvkNumber)
+}
+
+// GetLeadEnrichment returns the KvK link of a business lead.
+func (s *Service) GetLeadEnrichment(ctx context.Context, organizationID, leadID uuid.UUID) (transport.EnrichmentResponse, error) {
+	if err := s.ensureLead(ctx, organizationID, leadID); err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	return s.getEnrichment(ctx, organizationID, transport.EntityLead, leadID)
+}
+
+// EnrichLead links a business lead to a KvK registration by number or company name.
+func (s *Service) EnrichLead(ctx context.Context, organizationID, leadID uuid.UUID, req transport.EnrichRequest) (transport.EnrichmentResponse, error) {
+	if err := s.ensureLead(ctx, organizationID, leadID); err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	return s.enrich(ctx, organizationID, transport.EntityLead, leadID, req)
+}
+
+// RefreshLead re-fetches the linked KvK profile of a lead, bypassing the cache.
+func (s *Service) RefreshLead(ctx context.Context, organizationID, leadID uuid.UUID) (transport.EnrichmentResponse, error) {
+	if err := s.ensureLead(ctx, organizationID, leadID); err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	link, err := s.repo.GetEnrichment(ctx, organizationID, transport.EntityLead, leadID)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	return s.refresh(ctx, organizationID, transport.EntityLead, leadID, link.KVKNumber)
+}
+
+// RevalidateStale re-checks links that were last checked before maxAge ago and flags
+// companies that have been dissolved since.
+func (s *Service) RevalidateStale(ctx context.Context, maxAge time.Duration, limit int) (RevalidationResult, error) {
+	if maxAge <= 0 {
+		maxAge = DefaultRevalidationAge
+	}
+	stale, err := s.repo.ListStaleEnrichments(ctx, time.Now().Add(-maxAge), limit)
+	if err != nil {
+		return RevalidationResult{}, err
+	}
+
+	var res RevalidationResult
+	for _, link := range stale {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		profile, err := s.lookup(ctx, link.KVKNumber, true)
+		if err != nil {
+			s.log.Warn("kvk revalidation: lookup failed", "kvkNumber", link.KVKNumber, "error", err)
+			res.Failed++
+			continue
+		}
+		updated, err := s.record(ctx, link.OrganizationID, link.EntityType, link.EntityID, link.KVKNumber, profile)
+		if err != nil {
+			s.log.Warn("kvk revalidation: update failed", "enrichmentId", link.ID, "error", err)
+			res.Failed++
+			continue
+		}
+		res.Checked++
+		switch updated.Status {
+		case transport.StatusDissolved:
+			res.Dissolved++
+		case transport.StatusNotFound:
+			res.NotFound++
+		}
+	}
+	return res, nil
+}
+
+func (s *Service) enrich(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID, req transport.EnrichRequest) (transport.EnrichmentResponse, error) {
+	kvkNumber, err := s.resolveKVKNumber(ctx, req)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	profile, err := s.lookup(ctx, kvkNumber, false)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	if profile == nil {
+		return transport.EnrichmentResponse{}, apperr.NotFound("KvK number is not registered")
+	}
+	return s.record(ctx, organizationID, entityType, entityID, kvkNumber, profile)
+}
+
+func (s *Service) refresh(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID, kvkNumber string) (transport.EnrichmentResponse, error) {
+	normalized, err := normalizeKVKNumber(kvkNumber)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	profile, err := s.lookup(ctx, normalized, true)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	return s.record(ctx, organizationID, entityType, entityID, normalized, profile)
+}
+
+// record stores the check result of a link and publishes KvKCompanyDissolved the first
+// time a linked company is seen as dissolved.
+func (s *Service) record(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID, kvkNumber string, profile *transport.CompanyProfile) (transport.EnrichmentResponse, error) {
+	var previous *repository.Enrichment
+	if existing, err := s.repo.GetEnrichment(ctx, organizationID, entityType, entityID); err == nil {
+		previous = &existing
+	} else if !apperr.Is(err, apperr.KindNotFound) {
+		return transport.EnrichmentResponse{}, err
+	}
+
+	status := linkStatus(profile)
+	link, err := s.repo.UpsertEnrichment(ctx, organizationID, entityType, entityID, kvkNumber, status)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+
+	if becameDissolved(previous, kvkNumber, status) {
+		s.eventBus.Publish(ctx, events.KvKCompanyDissolved{
+			BaseEvent:      events.NewBaseEvent(),
+			OrganizationID: organizationID,
+			EntityType:     entityType,
+			EntityID:       entityID,
+			KVKNumber:      kvkNumber,
+			LegalName:      profile.LegalName,
+			DissolvedAt:    profile.DissolvedAt,
+		})
+	}
+
+	return toEnrichmentResponse(link, profile), nil
+}
+
+func (s *Service) getEnrichment(ctx context.Context, organizationID uuid.UUID, entityType string, entityID uuid.UUID) (transport.EnrichmentResponse, error) {
+	link, err := s.repo.GetEnrichment(ctx, organizationID, entityType, entityID)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	profile, err := s.repo.GetProfile(ctx, link.KVKNumber)
+	if err != nil {
+		return transport.EnrichmentResponse{}, err
+	}
+	return toEnrichmentResponse(link, profile), nil
+}
+
+// lookup returns the profile for a normalized KvK number, or (nil, nil) when it is not registered.
+// A stale cached profile is served when the KvK API is unavailable.
+func (s *Service) lookup(ctx context.Context, kvkNumber string, forceRefresh bool) (*transport.CompanyProfile, error) {
+	cached, err := s.repo.GetProfile(ctx, kvkNumber)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && !forceRefresh && time.Since(cached.FetchedAt) < profileCacheTTL {
+		return cached, nil
+	}
+
+	profile, err := s.client.GetBasisprofiel(ctx, kvkNumber)
+	if err != nil {
+		if cached != nil && !forceRefresh {
+			s.log.Warn("kvk lookup failed, serving cached profile", "kvkNumber", kvkNumber, "error", err)
+			return cached, nil
+		}
+		return nil, apperr.Internal("KvK lookup failed").WithDetails(err.Error())
+	}
+	if profile == nil {
+		return nil, nil
+	}
+	if profile.KVKNumber == "" {
+		profile.KVKNumber = kvkNumber
+	}
+	if err := s.repo.UpsertProfile(ctx, *profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *Service) resolveKVKNumber(ctx context.Context, req transport.EnrichRequest) (string, error) {
+	if req.KVKNumber != "" {
+		return normalizeKVKNumber(req.KVKNumber)
+	}
+	name := strings.TrimSpace(req.CompanyName)
+	if name == "" {
+		return "", apperr.Validation("kvkNumber or companyName is required")
+	}
+	results, err := s.client.Search(ctx, name, "")
+	if err != nil {
+		return "", apperr.Internal("KvK search failed").WithDetails(err.Error())
+	}
+	match, ok := pickSearchResult(results)
+	if !ok {
+		return "", apperr.NotFound("no KvK registration found for company name")
+	}
+	return match.KVKNumber, nil
+}
+
+func (s *Service) ensureLead(ctx context.Context, organizationID, leadID uuid.UUID) error {
+	exists, err := s.repo.LeadExists(ctx, organizationID, leadID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperr.NotFound("lead not found")
+	}
+	return nil
+}
+
+// normalizeKVKNumber strips separators and requires the 8-digit KvK format.
+func normalizeKVKNumber(raw string) (string, error) {
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '.' || r == '-':
+		default:
+			return "", apperr.Validation("invalid KvK number")
+		}
+	}
+	if b.Len() != kvkNumberLength {
+		return "", apperr.Validation("KvK number must have 8 digits")
+	}
+	return b.String(), nil
+}
+
+// pickSearchResult prefers the main establishment or legal entity over branch offices.
+func pickSearchResult(results []transport.SearchResult) (transport.SearchResult, bool) {
+	if len(results) == 0 {
+		return transport.SearchResult{}, false
+	}
+	for _, r := range results {
+		if r.Type == "hoofdvestiging" || r.Type == "rechtspersoon" {
+			return r, true
+		}
+	}
+	return results[0], true
+}
+
+func linkStatus(profile *transport.CompanyProfile) string {
+	switch {
+	case profile == nil:
+		return transport.StatusNotFound
+	case !profile.Active:
+		return transport.StatusDissolved
+	default:
+		return transport.StatusActive
+	}
+}
+
+func becameDissolved(previous *repository.Enrichment, kvkNumber, status string) bool {
+	if status != transport.StatusDissolved {
+		return false
+	}
+	return previous == nil || previous.KVKNumber != kvkNumber || previous.Status != transport.StatusDissolved
+}
+
+func toEnrichmentResponse(link repository.Enrichment, profile *transport.CompanyProfile) transport.EnrichmentResponse {
+	return transport.EnrichmentResponse{
+		ID:            link.ID,
+		EntityType:    link.EntityType,
+		EntityID:      link.EntityID,
+		KVKNumber:     link.KVKNumber,
+		Status:        link.Status,
+		LastCheckedAt: link.LastCheckedAt,
+		FlaggedAt:     link.FlaggedAt,
+		Profile:       profile,
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/kvk/repository"
+	"portal_final_backend/internal/kvk/transport"
+)
+
+func TestNormalizeKVKNumber(t *testing.T) {
+	got, err := normalizeKVKNumber(" 1234.56-78 ")
+	if err != nil || got != "12345678" {
+		t.Fatalf("expected 12345678, got %q (%v)", got, err)
+	}
+	for _, raw := range []string{"1234567", "123456789", "12345a78"} {
+		if _, err := normalizeKVKNumber(raw); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestPickSearchResultPrefersMainEstablishment(t *testing.T) {
+	results := []transport.SearchResult{
+		{KVKNumber: "11111111", Type: "nevenvestiging"},
+		{KVKNumber: "22222222", Type: "hoofdvestiging"},
+	}
+	match, ok := pickSearchResult(results)
+	if !ok || match.KVKNumber != "22222222" {
+		t.Fatalf("expected main establishment, got %+v", match)
+	}
+	if _, ok := pickSearchResult(nil); ok {
+		t.Fatal("expected no match for empty results")
+	}
+}
+
+func TestBecameDissolvedOnlyFlagsTransitions(t *testing.T) {
+	dissolved := &transport.CompanyProfile{Active: false}
+	if linkStatus(dissolved) != transport.StatusDissolved || linkStatus(nil) != transport.StatusNotFound {
+		t.Fatal("unexpected link status mapping")
+	}
+
+	active := &repository.Enrichment{KVKNumber: "12345678", Status: transport.StatusActive}
+	if !becameDissolved(active, "12345678", transport.StatusDissolved) {
+		t.Fatal("expected active -> dissolved to be flagged")
+	}
+	if !becameDissolved(nil, "12345678", transport.StatusDissolved) {
+		t.Fatal("expected a new dissolved link to be flagged")
+	}
+
+	alreadyFlagged := &repository.Enrichment{KVKNumber: "12345678", Status: transport.StatusDissolved}
+	if becameDissolved(alreadyFlagged, "12345678", transport.StatusDissolved) {
+		t.Fatal("expected an already dissolved link not to be flagged again")
+	}
+	if becameDissolved(active, "12345678", transport.StatusActive) {
+		t.Fatal("expected active link not to be flagged")
+	}
+}
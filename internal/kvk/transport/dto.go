@@ -0,0 +1,83 @@
+// Package transport provides DTOs for the KvK enrichment domain.
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Enrichment statuses.
+const (
+	StatusActive    = "active"
+	StatusDissolved = "dissolved"
+	StatusNotFound  = "not_found"
+)
+
+// Enrichment entity types.
+const (
+	EntityPartner = "partner"
+	EntityLead    = "lead"
+)
+
+// SBIActivity is a registered business activity (Standaard Bedrijfsindeling).
+type SBIActivity struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+// Address is the visiting address of the main establishment.
+type Address struct {
+	Street      string `json:"street,omitempty"`
+	HouseNumber string `json:"houseNumber,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	City        string `json:"city,omitempty"`
+	Country     string `json:"country,omitempty"`
+}
+
+// CompanyProfile is the basic profile of a company registered at the KvK.
+type CompanyProfile struct {
+	KVKNumber    string        `json:"kvkNumber"`
+	LegalName    string        `json:"legalName"`
+	TradeNames   []string      `json:"tradeNames"`
+	Address      Address       `json:"address"`
+	SBICodes     []SBIActivity `json:"sbiCodes"`
+	Active       bool          `json:"active"`
+	RegisteredAt *time.Time    `json:"registeredAt,omitempty"`
+	DissolvedAt  *time.Time    `json:"dissolvedAt,omitempty"`
+	FetchedAt    time.Time     `json:"fetchedAt"`
+}
+
+// SearchResult is a single hit from the KvK search API.
+type SearchResult struct {
+	KVKNumber string `json:"kvkNumber"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	City      string `json:"city,omitempty"`
+}
+
+// SearchRequest looks up companies by name or KvK number.
+type SearchRequest struct {
+	Name      string `form:"name" validate:"required_without=KVKNumber,omitempty,min=2,max=200"`
+	KVKNumber string `form:"kvkNumber" validate:"required_without=Name,omitempty,max=20"`
+}
+
+// EnrichRequest links an entity to a KvK registration. When only a company name is
+// given, the best search hit is used. Partners fall back to their own KvK number.
+type EnrichRequest struct {
+	KVKNumber   string `json:"kvkNumber,omitempty" validate:"omitempty,max=20"`
+	CompanyName string `json:"companyName,omitempty" validate:"omitempty,min=2,max=200"`
+}
+
+// EnrichmentResponse describes the KvK link of a partner or lead.
+type EnrichmentResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	EntityType    string          `json:"entityType"`
+	EntityID      uuid.UUID       `json:"entityId"`
+	KVKNumber     string          `json:"kvkNumber"`
+	Status        string          `json:"status"`
+	LastCheckedAt time.Time       `json:"lastCheckedAt"`
+	FlaggedAt     *time.Time      `json:"flaggedAt,omitempty"`
+	Profile       *CompanyProfile `json:"profile,omitempty"`
+}
@@ -3,8 +3,11 @@ package leadenrichment
 
 import (
 	"portal_final_backend/internal/leadenrichment/client"
+	"portal_final_backend/internal/leadenrichment/repository"
 	"portal_final_backend/internal/leadenrichment/service"
 	"portal_final_backend/platform/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Module wires the lead enrichment service.
@@ -12,10 +15,12 @@ type Module struct {
 	service *service.Service
 }
 
-// NewModule creates a new lead enrichment module.
-func NewModule(log *logger.Logger) *Module {
+// NewModule creates a new lead enrichment module. Lookups are cached per
+// postcode in the database, shared by all processes and organizations.
+func NewModule(pool *pgxpool.Pool, log *logger.Logger) *Module {
 	cli := client.New(log)
 	svc := service.New(cli, log)
+	svc.SetCacheStore(repository.New(pool))
 	return &Module{service: svc}
 }
 
@@ -0,0 +1,56 @@
+// Package repository persists the shared postcode enrichment cache.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository stores enrichment results per postcode, shared across organizations.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// GetPostcode returns the most recent cached result for a postcode fetched after
+// the given time, or (nil, nil) when there is none.
+func (r *Repository) GetPostcode(ctx context.Context, postcode6 string, fetchedAfter time.Time) ([]byte, error) {
+	var data []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT data
+		FROM RAC_postcode_enrichment_cache
+		WHERE postcode6 = $1 AND fetched_at > $2
+		ORDER BY data_year DESC, fetched_at DESC
+		LIMIT 1
+	`, postcode6, fetchedAfter).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get postcode enrichment: %w", err)
+	}
+	return data, nil
+}
+
+// PutPostcode stores the result for a postcode and data year, replacing an
+// earlier result for the same year.
+func (r *Repository) PutPostcode(ctx context.Context, postcode6 string, dataYear int, data []byte, fetchedAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_postcode_enrichment_cache (postcode6, data_year, data, fetched_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (postcode6, data_year) DO UPDATE
+		SET data = EXCLUDED.data, fetched_at = EXCLUDED.fetched_at
+	`, postcode6, dataYear, data, fetchedAt)
+	if err != nil {
+		return fmt.Errorf("put postcode enrichment: %w", err)
+	}
+	return nil
+}
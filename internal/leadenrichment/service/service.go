@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"sync"
 	"time"
@@ -50,6 +51,13 @@ type EnrichmentData struct {
 	FetchedAt  time.Time
 }
 
+// CacheStore is the shared postcode cache, so lookups are reused across
+// processes and organizations. Results are stored as JSON.
+type CacheStore interface {
+	GetPostcode(ctx context.Context, postcode6 string, fetchedAfter time.Time) ([]byte, error)
+	PutPostcode(ctx context.Context, postcode6 string, dataYear int, data []byte, fetchedAt time.Time) error
+}
+
 type cacheEntry struct {
 	data      *EnrichmentData
 	expiresAt time.Time
@@ -59,6 +67,7 @@ type cacheEntry struct {
 type Service struct {
 	client   *client.Client
 	log      *logger.Logger
+	store    CacheStore
 	cache    map[string]cacheEntry
 	cacheMu  sync.RWMutex
 	cacheTTL time.Duration
@@ -74,8 +83,14 @@ func New(client *client.Client, log *logger.Logger) *Service {
 	}
 }
 
+// SetCacheStore sets the shared postcode cache consulted before the PDOK APIs.
+func (s *Service) SetCacheStore(store CacheStore) {
+	s.store = store
+}
+
 // GetByPostcode fetches enrichment data for a postcode.
-// Fetches PC4 first (richest data), then PC6, then buurt fallback.
+// Uses the in-memory cache, then the shared cache, and otherwise fetches PC4
+// first (richest data), then PC6, then buurt fallback.
 // Returns nil if lookup fails or data is not available.
 func (s *Service) GetByPostcode(ctx context.Context, postcode string) (*EnrichmentData, error) {
 	normalized := normalizePostcode(postcode)
@@ -86,6 +101,10 @@ func (s *Service) GetByPostcode(ctx context.Context, postcode string) (*Enrichme
 	if cached := s.getFromCache(normalized); cached != nil {
 		return cached, nil
 	}
+	if stored := s.getFromStore(ctx, normalized); stored != nil {
+		s.setCache(normalized, stored)
+		return stored, nil
+	}
 
 	now := time.Now().UTC()
 	result := &EnrichmentData{
@@ -111,9 +130,50 @@ func (s *Service) GetByPostcode(ctx context.Context, postcode string) (*Enrichme
 	result.Confidence = s.calculateConfidence(result)
 
 	s.setCache(normalized, result)
+	s.putInStore(ctx, normalized, result)
 	return result, nil
 }
 
+// getFromStore returns a shared cache hit. Cache failures fall through to the APIs.
+func (s *Service) getFromStore(ctx context.Context, postcode string) *EnrichmentData {
+	if s.store == nil {
+		return nil
+	}
+	raw, err := s.store.GetPostcode(ctx, postcode, time.Now().Add(-s.cacheTTL))
+	if err != nil {
+		s.log.Debug("postcode enrichment cache lookup failed", "postcode", postcode, "error", err)
+		return nil
+	}
+	if raw == nil {
+		return nil
+	}
+	var data EnrichmentData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		s.log.Debug("postcode enrichment cache entry unreadable", "postcode", postcode, "error", err)
+		return nil
+	}
+	return &data
+}
+
+// putInStore shares a result with other processes. Results without any data are
+// not shared, as they more likely reflect an API outage than an empty postcode.
+func (s *Service) putInStore(ctx context.Context, postcode string, data *EnrichmentData) {
+	if s.store == nil || data.Source == "pdok" {
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	dataYear := 0
+	if data.DataYear != nil {
+		dataYear = *data.DataYear
+	}
+	if err := s.store.PutPostcode(ctx, postcode, dataYear, raw, data.FetchedAt); err != nil {
+		s.log.Debug("postcode enrichment cache write failed", "postcode", postcode, "error", err)
+	}
+}
+
 // enrichFromPC4 fetches PC4-level data (most complete: gas, electricity, income, WOZ).
 func (s *Service) enrichFromPC4(ctx context.Context, pc4 string, result *EnrichmentData) {
 	pc4Data, err := s.client.GetPC4(ctx, pc4)
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"portal_final_backend/platform/logger"
+)
+
+type memoryStore struct {
+	entries map[string][]byte
+	puts    int
+}
+
+func (m *memoryStore) GetPostcode(_ context.Context, postcode6 string, _ time.Time) ([]byte, error) {
+	return m.entries[postcode6], nil
+}
+
+func (m *memoryStore) PutPostcode(_ context.Context, postcode6 string, _ int, data []byte, _ time.Time) error {
+	m.puts++
+	m.entries[postcode6] = data
+	return nil
+}
+
+func TestGetByPostcodeUsesSharedCache(t *testing.T) {
+	year := 2024
+	gas := 1250.0
+	cached, err := json.Marshal(EnrichmentData{Source: "pdok_pc4", Postcode6: "1234AB", DataYear: &year, GemAardgasverbruik: &gas})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	// A nil client panics if the service falls through to the PDOK APIs.
+	svc := New(nil, logger.New("development"))
+	store := &memoryStore{entries: map[string][]byte{"1234AB": cached}}
+	svc.SetCacheStore(store)
+
+	data, err := svc.GetByPostcode(context.Background(), "1234 ab")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if data == nil || data.GemAardgasverbruik == nil || *data.GemAardgasverbruik != gas || data.Source != "pdok_pc4" {
+		t.Fatalf("expected the cached postcode data, got %+v", data)
+	}
+	if store.puts != 0 {
+		t.Fatalf("expected a cache hit not to be written back, got %d writes", store.puts)
+	}
+}
+
+func TestPutInStoreSkipsEmptyResults(t *testing.T) {
+	svc := New(nil, logger.New("development"))
+	store := &memoryStore{entries: map[string][]byte{}}
+	svc.SetCacheStore(store)
+
+	svc.putInStore(context.Background(), "1234AB", &EnrichmentData{Source: "pdok", Postcode6: "1234AB"})
+	if store.puts != 0 {
+		t.Fatalf("expected an empty lookup not to be shared")
+	}
+
+	svc.putInStore(context.Background(), "1234AB", &EnrichmentData{Source: "pdok_pc6", Postcode6: "1234AB"})
+	if store.puts != 1 {
+		t.Fatalf("expected the lookup to be shared, got %d writes", store.puts)
+	}
+}
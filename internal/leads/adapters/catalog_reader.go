@@ -37,6 +37,14 @@ func (a *CatalogReaderAdapter) GetProductDetails(ctx context.Context, orgID uuid
 		return nil, fmt.Errorf("get products by ids: %w", err)
 	}
 
+	// Stock levels: best-effort (non-fatal if missing), untracked products have no row.
+	stockByProduct := make(map[uuid.UUID]float64)
+	if stocks, err := a.repo.ListProductStock(ctx, orgID, productIDs); err == nil {
+		for _, stock := range stocks {
+			stockByProduct[stock.ProductID] = stock.Quantity
+		}
+	}
+
 	out := make([]ports.CatalogProductDetails, 0, len(products))
 	for _, p := range products {
 		detail, err := a.getProductDetail(ctx, orgID, p)
@@ -44,6 +52,7 @@ func (a *CatalogReaderAdapter) GetProductDetails(ctx context.Context, orgID uuid
 			return nil, err
 		}
 		if detail != nil {
+			detail.StockQuantity, detail.StockTracked = stockByProduct[p.ID]
 			out = append(out, *detail)
 		}
 	}
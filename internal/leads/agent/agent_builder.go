@@ -111,7 +111,7 @@ func (b *LazyRunnerBuilder) Runner() (*runner.Runner, error) {
 		return nil, fmt.Errorf("lazy runner builder for %s not initialized", b.Name)
 	}
 
-	llm := openaicompat.NewModel(b.ModelConfig)
+	llm := BuildLLM(b.ModelConfig)
 	adkAgent, err := llmagent.New(llmagent.Config{
 		Name:        b.Name,
 		Model:       llm,
@@ -18,6 +18,7 @@ import (
 	"portal_final_backend/internal/leads/domain"
 	"portal_final_backend/internal/leads/repository"
 	apptools "portal_final_backend/internal/tools"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/openaicompat"
 )
 
@@ -94,7 +95,8 @@ func (d *AuditorToolDeps) handleSubmitAuditResult(ctx tool.Context, input Submit
 
 	// If audit failed or missing info is present, move to Manual_Intervention.
 	if !input.Passed || len(missing) > 0 {
-		if _, err := d.Repo.UpdatePipelineStage(ctx, serviceID, tenantID, domain.PipelineStageManualIntervention); err != nil {
+		attributed := repository.WithStageTransitionActor(ctx, repository.StageTransitionActor{Type: repository.ActorTypeAI, Name: "Audit Agent", Reason: summaryText})
+		if _, err := d.Repo.UpdatePipelineStage(attributed, serviceID, tenantID, domain.PipelineStageManualIntervention); err != nil {
 			log.Printf("auditor: failed to set Manual_Intervention: %v", err)
 		}
 	}
@@ -151,7 +153,7 @@ func newAuditor(modelCfg openaicompat.Config, repo repository.LeadsRepository, e
 		return nil, fmt.Errorf("failed to create SubmitAuditResult tool: %w", err)
 	}
 
-	llm := openaicompat.NewModel(modelCfg)
+	llm := BuildLLM(modelCfg)
 	kit, err := BuildAgentKit(
 		"AuditAgent",
 		"Internal reviewer that validates VisitReports and CallLogs against intake guidelines.",
@@ -180,6 +182,7 @@ func (a *Auditor) AuditVisitReport(ctx context.Context, leadID, serviceID, tenan
 	reqDeps := a.toolDeps.NewRequestDeps()
 	reqDeps.SetContext(tenantID, leadID, serviceID)
 	ctx = WithAuditorDeps(ctx, reqDeps)
+	ctx = llmrouter.WithOrganizationID(ctx, tenantID)
 
 	service, err := a.repo.GetLeadServiceByID(ctx, serviceID, tenantID)
 	if err != nil {
@@ -206,6 +209,7 @@ func (a *Auditor) AuditCallLog(ctx context.Context, leadID, serviceID, tenantID
 	reqDeps := a.toolDeps.NewRequestDeps()
 	reqDeps.SetContext(tenantID, leadID, serviceID)
 	ctx = WithAuditorDeps(ctx, reqDeps)
+	ctx = llmrouter.WithOrganizationID(ctx, tenantID)
 
 	service, err := a.repo.GetLeadServiceByID(ctx, serviceID, tenantID)
 	if err != nil {
@@ -292,7 +296,7 @@ func buildVisitReportAuditPrompt(serviceType string, intakeContext string, repor
 		recentNotes := make([]string, 0, max)
 		for i := 0; i < max; i++ {
 			n := notes[i]
-			recentNotes = append(recentNotes, fmt.Sprintf("- [%s] %s: %s", n.Type, n.AuthorEmail, sanitizeUserInput(n.Body, 300)))
+			recentNotes = append(recentNotes, fmt.Sprintf("- [%s] %s: %s", n.Type, n.AuthorEmail, sanitizeUserInput(n.PlainBody(), 300)))
 		}
 		sb.WriteString("RECENT NOTES (context):\n")
 		sb.WriteString(wrapReferenceBlock(strings.Join(recentNotes, "\n")) + "\n")
@@ -324,7 +328,7 @@ func buildCallLogAuditPrompt(serviceType string, intakeContext string, notes []r
 	recentNotes := make([]string, 0, max)
 	for i := 0; i < max; i++ {
 		n := notes[i]
-		recentNotes = append(recentNotes, fmt.Sprintf("- [%s] %s: %s", n.Type, n.AuthorEmail, sanitizeUserInput(n.Body, 400)))
+		recentNotes = append(recentNotes, fmt.Sprintf("- [%s] %s: %s", n.Type, n.AuthorEmail, sanitizeUserInput(n.PlainBody(), 400)))
 	}
 	sb.WriteString(wrapReferenceBlock(strings.Join(recentNotes, "\n")) + "\n\n")
 
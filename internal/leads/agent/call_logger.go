@@ -283,7 +283,7 @@ func NewCallLogger(modelCfg openaicompat.Config, repo repository.LeadsRepository
 		return nil, fmt.Errorf("failed to build call logger tools: %w", err)
 	}
 
-	llm := openaicompat.NewModel(modelCfg)
+	llm := BuildLLM(modelCfg)
 	kit, err := BuildAgentKit(
 		"CallLogger",
 		"Post-call processing assistant that converts natural language call summaries into structured database updates (Notes, Status changes, Appointments).",
@@ -340,6 +340,7 @@ func (c *CallLogger) ProcessSummary(ctx context.Context, leadID, serviceID, user
 	reqDeps := c.toolDeps.NewRequestDeps()
 	reqDeps.SetContext(tenantID, userID, leadID, serviceID)
 	ctx = confirmation.WithTenantID(ctx, tenantID)
+	ctx = confirmation.WithLeadService(ctx, leadID, serviceID)
 	ctx = WithCallLoggerDeps(ctx, reqDeps)
 
 	existingAppointment, hasExistingAppointment := c.resolveExistingAppointment(ctx, tenantID, serviceID, userID)
@@ -1074,6 +1075,7 @@ type callLoggerStageContext struct {
 	oldStage       string
 	currentStatus  string
 	requestedStage string
+	reason         string
 }
 
 func handleCallLoggerUpdatePipelineStage(ctx tool.Context, deps *CallLoggerToolDeps, input UpdatePipelineStageInput) (UpdatePipelineStageOutput, error) {
@@ -1137,11 +1139,19 @@ func validateAndResolveCallLoggerStageContext(deps *CallLoggerToolDeps, ctx tool
 		oldStage:       oldStage,
 		currentStatus:  currentStatus,
 		requestedStage: input.Stage,
+		reason:         input.Reason,
 	}, UpdatePipelineStageOutput{}, nil
 }
 
 func updateCallLoggerPipelineStage(deps *CallLoggerToolDeps, ctx tool.Context, stageCtx callLoggerStageContext) error {
-	_, err := deps.Repo.UpdatePipelineStage(ctx, stageCtx.serviceID, stageCtx.tenantID, stageCtx.requestedStage)
+	userID := stageCtx.userID
+	attributed := repository.WithStageTransitionActor(ctx, repository.StageTransitionActor{
+		Type:   repository.ActorTypeUser,
+		ID:     &userID,
+		Name:   userID.String(),
+		Reason: stageCtx.reason,
+	})
+	_, err := deps.Repo.UpdatePipelineStage(attributed, stageCtx.serviceID, stageCtx.tenantID, stageCtx.requestedStage)
 	return err
 }
 
@@ -96,11 +96,12 @@ type ToolDependencies struct {
 	lastQuoteReviewResult       *ports.QuoteAIReviewResult
 	lastQuoteCritiqueInput      *SubmitQuoteCritiqueInput
 	quoteCriticAttempt          int
-	quoteCritiqueSubmittedForAt int            // tracks the attempt number for which SubmitQuoteCritique was already called
-	scopeArtifact               *ScopeArtifact // Produced by Scope Analyzer and consumed by Quote Builder
-	clarificationAsked          bool           // Track if AskCustomerClarification was called in investigative mode
-	runID                       string         // Correlates all tool calls within one agent run
-	forceDraftQuote             bool           // Allows manual runs to bypass draft governance (intake + council)
+	quoteCritiqueSubmittedForAt int                        // tracks the attempt number for which SubmitQuoteCritique was already called
+	scopeArtifact               *ScopeArtifact             // Produced by Scope Analyzer and consumed by Quote Builder
+	serviceMemory               []repository.ServiceMemory // Facts remembered for the lead service, loaded before a run
+	clarificationAsked          bool                       // Track if AskCustomerClarification was called in investigative mode
+	runID                       string                     // Correlates all tool calls within one agent run
+	forceDraftQuote             bool                       // Allows manual runs to bypass draft governance (intake + council)
 	searchCache                 map[string]SearchProductMaterialsOutput
 	emittedAlertKeys            map[string]struct{} // Dedupe identical alerts within a single agent run
 	sessionDoneFunc             context.CancelFunc  // Optional: called after successful UpdatePipelineStage to end session early
@@ -301,6 +302,17 @@ func (d *ToolDependencies) GetRunID() string {
 	return d.runID
 }
 
+// agentRunDedupKey keys a timeline write to the current agent run, so a
+// retried tool call does not write the same event twice. It is empty, i.e.
+// not deduplicated, outside a run.
+func agentRunDedupKey(deps *ToolDependencies, kind string) string {
+	runID := deps.GetRunID()
+	if runID == "" {
+		return ""
+	}
+	return repository.TimelineDedupKey(kind, runID)
+}
+
 // SetLastAnalysisMetadata stores the analysis metadata for inclusion in subsequent events
 func (d *ToolDependencies) SetLastAnalysisMetadata(metadata map[string]any) {
 	d.mu.Lock()
@@ -53,7 +53,7 @@ func newDispatcher(modelCfg openaicompat.Config, repo repository.LeadsRepository
 		return nil, fmt.Errorf("failed to build CreatePartnerOffer tool: %w", err)
 	}
 
-	llm := openaicompat.NewModel(modelCfg)
+	llm := BuildLLM(modelCfg)
 	kit, err := BuildAgentKit(
 		"Dispatcher",
 		"Fulfillment manager that finds partner matches and advances the pipeline.",
@@ -103,6 +103,7 @@ func (d *Dispatcher) Run(ctx context.Context, leadID, serviceID, tenantID uuid.U
 	fmt.Printf("dispatcher: run started runID=%s lead=%s service=%s tenant=%s\n", runID, leadID, serviceID, tenantID)
 
 	ctx = confirmation.WithTenantID(ctx, tenantID)
+	ctx = confirmation.WithLeadService(ctx, leadID, serviceID)
 	ctx = WithDependencies(ctx, reqDeps)
 
 	// Preload org settings for consistency across agents (even if not used directly today).
@@ -172,7 +173,12 @@ func (d *Dispatcher) ensureDispatchPostconditions(ctx context.Context, runID str
 	}
 
 	if !reqDeps.WasStageUpdateCalled() {
-		if _, err := d.repo.UpdatePipelineStage(ctx, serviceID, tenantID, domain.PipelineStageManualIntervention); err != nil {
+		attributed := repository.WithStageTransitionActor(ctx, repository.StageTransitionActor{
+			Type:   repository.ActorTypeAI,
+			Name:   repository.ActorNameDispatcher,
+			Reason: "no stage update recorded by the dispatcher run",
+		})
+		if _, err := d.repo.UpdatePipelineStage(attributed, serviceID, tenantID, domain.PipelineStageManualIntervention); err != nil {
 			fmt.Printf("Dispatcher warning: fallback stage update failed runID=%s lead=%s service=%s err=%v\n", runID, leadID, serviceID, err)
 		} else {
 			fmt.Printf("Dispatcher warning: no stage update recorded, fallback to Manual_Intervention runID=%s lead=%s service=%s\n", runID, leadID, serviceID)
@@ -60,6 +60,10 @@ func newGatekeeper(llm model.LLM, repo repository.LeadsRepository, eventBus even
 	if err != nil {
 		return nil, fmt.Errorf("failed to build UpdateLeadServiceType tool: %w", err)
 	}
+	rememberFactTool, err := createRememberLeadFactTool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build RememberLeadFact tool: %w", err)
+	}
 	updateLeadDetailsTool, err := createUpdateLeadDetailsTool("Updates lead contact or address details when you are highly confident the current data is wrong.")
 	if err != nil {
 		return nil, fmt.Errorf("failed to build UpdateLeadDetails tool: %w", err)
@@ -72,7 +76,7 @@ func newGatekeeper(llm model.LLM, repo repository.LeadsRepository, eventBus even
 		"gatekeeper",
 		llm,
 		sessionService,
-		[]tool.Tool{saveAnalysisTool, updateLeadDetailsTool, updateServiceTypeTool, updateStageTool},
+		[]tool.Tool{saveAnalysisTool, updateLeadDetailsTool, updateServiceTypeTool, rememberFactTool, updateStageTool},
 	)
 	if err != nil {
 		return nil, err
@@ -113,6 +117,7 @@ func (g *Gatekeeper) Run(ctx context.Context, leadID, serviceID, tenantID uuid.U
 	g.mu.Unlock()
 
 	ctx = confirmation.WithTenantID(ctx, tenantID)
+	ctx = confirmation.WithLeadService(ctx, leadID, serviceID)
 	ctx = WithDependencies(ctx, reqDeps)
 
 	// Preload org AI settings so downstream tools and safeguards can use them.
@@ -120,6 +125,10 @@ func (g *Gatekeeper) Run(ctx context.Context, leadID, serviceID, tenantID uuid.U
 	if _, err := reqDeps.LoadOrganizationAISettings(ctx); err != nil {
 		log.Printf("gatekeeper: failed to load org AI settings (tenant=%s): %v", tenantID, err)
 	}
+	serviceMemory, err := reqDeps.LoadServiceMemory(ctx)
+	if err != nil {
+		log.Printf("gatekeeper: failed to load service memory (service=%s): %v", serviceID, err)
+	}
 
 	lead, service, err := g.fetchLeadAndService(ctx, leadID, serviceID, tenantID)
 	if err != nil {
@@ -131,6 +140,11 @@ func (g *Gatekeeper) Run(ctx context.Context, leadID, serviceID, tenantID uuid.U
 	}
 
 	notes, attachments, visitReport := g.fetchServiceContext(ctx, leadID, serviceID, tenantID)
+	customFields, err := g.repo.GetLeadCustomFields(ctx, leadID, tenantID)
+	if err != nil {
+		log.Printf("gatekeeper custom fields fetch failed: %v", err)
+		customFields = nil
+	}
 	intakeContext := g.buildServiceContext(ctx, tenantID, service.ServiceType)
 	estimationContext := fetchServiceTypeEstimationGuidelines(ctx, g.repo, tenantID, service.ServiceType)
 	priorAnalysis := g.loadPriorAnalysis(ctx, serviceID, tenantID)
@@ -144,6 +158,8 @@ func (g *Gatekeeper) Run(ctx context.Context, leadID, serviceID, tenantID uuid.U
 		intakeContext:      intakeContext,
 		estimationContext:  estimationContext,
 		attachments:        attachments,
+		customFields:       customFields,
+		serviceMemory:      serviceMemory,
 		priorAnalysis:      priorAnalysis,
 		nurturingLoopCount: service.GatekeeperNurturingLoopCount,
 		agentCycleCount:    service.AgentCycleCount,
@@ -340,6 +356,8 @@ type gatekeeperPromptRequest struct {
 	intakeContext      string
 	estimationContext  string
 	attachments        []repository.Attachment
+	customFields       map[string]any
+	serviceMemory      []repository.ServiceMemory
 	priorAnalysis      *repository.AIAnalysis
 	nurturingLoopCount int
 	agentCycleCount    int
@@ -354,6 +372,8 @@ func (g *Gatekeeper) runGatekeeperPrompt(ctx context.Context, req gatekeeperProm
 		intakeContext:      req.intakeContext,
 		estimationContext:  req.estimationContext,
 		attachments:        req.attachments,
+		customFields:       req.customFields,
+		serviceMemory:      req.serviceMemory,
 		priorAnalysis:      req.priorAnalysis,
 		nurturingLoopCount: req.nurturingLoopCount,
 		agentCycleCount:    req.agentCycleCount,
@@ -411,7 +431,12 @@ func (g *Gatekeeper) maybeAutoDisqualifyJunk(ctx context.Context, leadID, servic
 
 	log.Printf("gatekeeper: auto-disqualifying Junk lead (service=%s lead=%s)", serviceID, leadID)
 
-	if _, err := g.repo.UpdateServiceStatusAndPipelineStage(ctx, serviceID, tenantID, domain.LeadStatusDisqualified, domain.PipelineStageLost); err != nil {
+	attributed := repository.WithStageTransitionActor(ctx, repository.StageTransitionActor{
+		Type:   repository.ActorTypeAI,
+		Name:   repository.ActorNameGatekeeper,
+		Reason: "AI detected Junk quality",
+	})
+	if _, err := g.repo.UpdateServiceStatusAndPipelineStage(attributed, serviceID, tenantID, domain.LeadStatusDisqualified, domain.PipelineStageLost); err != nil {
 		log.Printf("gatekeeper: failed to set service state to Disqualified/Lost during junk auto-disqualify: %v", err)
 		return
 	}
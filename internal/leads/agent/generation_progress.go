@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"context"
+
+	"google.golang.org/adk/tool"
+)
+
+// Stages reported while a quote is generated from a prompt.
+const (
+	GenerationStageAnalyzing        = "analyzing"
+	GenerationStageProductsSearched = "products_searched"
+	GenerationStageItemsDrafted     = "items_drafted"
+	GenerationStageReviewing        = "reviewing"
+)
+
+// GenerationProgress is a milestone of prompt-driven quote generation. Count
+// is the number of products found for products_searched and the number of
+// drafted items for items_drafted.
+type GenerationProgress struct {
+	Stage string
+	Count int
+}
+
+type generationProgressKey struct{}
+
+// WithGenerationProgress returns a child context whose quote generation
+// reports its milestones to report.
+func WithGenerationProgress(ctx context.Context, report func(GenerationProgress)) context.Context {
+	return context.WithValue(ctx, generationProgressKey{}, report)
+}
+
+func reportGenerationProgress(ctx context.Context, progress GenerationProgress) {
+	if report, ok := ctx.Value(generationProgressKey{}).(func(GenerationProgress)); ok && report != nil {
+		report(progress)
+	}
+}
+
+// handleSearchProductMaterialsWithProgress reports every successful product
+// search as a generation milestone.
+func handleSearchProductMaterialsWithProgress(ctx tool.Context, deps *ToolDependencies, input SearchProductMaterialsInput) (SearchProductMaterialsOutput, error) {
+	output, err := handleSearchProductMaterials(ctx, deps, input)
+	if err == nil {
+		reportGenerationProgress(ctx, GenerationProgress{Stage: GenerationStageProductsSearched, Count: len(output.Products)})
+	}
+	return output, err
+}
@@ -3,13 +3,15 @@ package agent
 import (
 	"google.golang.org/adk/model"
 
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/openaicompat"
 	"portal_final_backend/platform/config"
 )
 
-// BuildLLM creates a model.LLM from a primary config.
+// BuildLLM creates a model.LLM from a primary config. Calls are routed through
+// llmrouter so organization provider settings and token budgets apply.
 func BuildLLM(primary openaicompat.Config) model.LLM {
-	return openaicompat.NewModel(primary)
+	return llmrouter.NewModel(primary)
 }
 
 // newModelConfig builds an openaicompat.Config from a resolved provider
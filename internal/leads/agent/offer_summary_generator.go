@@ -11,6 +11,7 @@ import (
 	"google.golang.org/adk/session"
 
 	"portal_final_backend/internal/leads/ports"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/openaicompat"
 )
 
@@ -24,7 +25,7 @@ type OfferSummaryGenerator struct {
 
 // NewOfferSummaryGenerator creates a summary generator agent without tools.
 func NewOfferSummaryGenerator(modelCfg openaicompat.Config, sessionService session.Service) (*OfferSummaryGenerator, error) {
-	llm := openaicompat.NewModel(modelCfg)
+	llm := BuildLLM(modelCfg)
 	kit, err := BuildAgentKit(
 		"OfferSummaryGenerator",
 		"Generates concise, readable summaries for partner offers.",
@@ -48,7 +49,7 @@ func NewOfferSummaryGenerator(modelCfg openaicompat.Config, sessionService sessi
 
 // GenerateOfferSummary renders a readable summary using only allowed fields.
 func (g *OfferSummaryGenerator) GenerateOfferSummary(ctx context.Context, tenantID uuid.UUID, input ports.OfferSummaryInput) (string, error) {
-	_ = tenantID
+	ctx = llmrouter.WithOrganizationID(ctx, tenantID)
 
 	promptText := buildOfferSummaryPrompt(input)
 	sessionID := uuid.New().String()
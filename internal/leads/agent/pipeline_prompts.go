@@ -57,6 +57,8 @@ type gatekeeperPromptInput struct {
 	intakeContext      string
 	estimationContext  string
 	attachments        []repository.Attachment
+	customFields       map[string]any
+	serviceMemory      []repository.ServiceMemory
 	priorAnalysis      *repository.AIAnalysis
 	nurturingLoopCount int
 	agentCycleCount    int
@@ -89,6 +91,7 @@ type gatekeeperPromptTemplateData struct {
 	PreferencesSummary        string
 	PreviousEstimatorBlockers string
 	KnownFacts                string
+	LeadMemory                string
 	AttachmentAwareness       string
 	LeadContext               string
 	IntakeContextSummary      string
@@ -103,13 +106,17 @@ func buildGatekeeperPrompt(input gatekeeperPromptInput) string {
 	serviceNote := getValue(input.service.ConsumerNote)
 	preferredChannel := resolvePreferredContactChannel(input.lead)
 	preferencesSummary := buildPreferencesSummary(input.service.CustomerPreferences, maxGatekeeperPreferencesChars)
-	leadContext := truncatePromptSection(buildLeadContextSection(input.lead, input.attachments), maxGatekeeperLeadCtxChars)
+	leadContext := truncatePromptSection(buildLeadContextSection(input.lead, input.attachments, input.customFields), maxGatekeeperLeadCtxChars)
 	attachmentAwareness := truncatePromptSection(buildAttachmentAwarenessSection(input.attachments), maxGatekeeperLeadCtxChars)
 	serviceNoteSummary := truncatePromptSection(wrapUserData(sanitizeUserInput(serviceNote, maxConsumerNote)), maxGatekeeperServiceNoteChars)
 	intakeContextSummary := truncatePromptSection(input.intakeContext, maxGatekeeperIntakeChars)
 	estimationContextSummary := truncatePromptSection(input.estimationContext, maxGatekeeperIntakeChars)
 	previousEstimatorBlockers := buildPreviousEstimatorBlockersSection(input.priorAnalysis)
 	knownFacts := buildKnownFactsSection(input.priorAnalysis, input.visitReport)
+	leadMemory := buildServiceMemoryLines(input.serviceMemory)
+	if leadMemory == "" {
+		leadMemory = emptyServiceMemoryText
+	}
 	consumerSummary := buildPromptConsumerSection(input.lead)
 	locationSummary := buildPromptLocationLine(input.lead)
 	recoveryModeSection := ""
@@ -156,6 +163,7 @@ func buildGatekeeperPrompt(input gatekeeperPromptInput) string {
 		PreferencesSummary:        preferencesSummary,
 		PreviousEstimatorBlockers: previousEstimatorBlockers,
 		KnownFacts:                knownFacts,
+		LeadMemory:                leadMemory,
 		AttachmentAwareness:       attachmentAwareness,
 		LeadContext:               leadContext,
 		IntakeContextSummary:      intakeContextSummary,
@@ -548,7 +556,7 @@ func resolveNotesContentBudget(maxChars int) int {
 func renderNotesWithinBudget(notes []repository.LeadNote, contentBudget int) string {
 	var sb strings.Builder
 	for _, n := range notes {
-		body := sanitizeUserInput(n.Body, maxNoteLength)
+		body := sanitizeUserInput(n.PlainBody(), maxNoteLength)
 		prefix := fmt.Sprintf("- [%s] %s: ", n.Type, n.CreatedAt.Format(time.RFC3339))
 		line := prefix + body + "\n"
 
@@ -571,7 +579,7 @@ func renderNotesWithinBudget(notes []repository.LeadNote, contentBudget int) str
 	return sb.String()
 }
 
-func buildLeadContextSection(lead repository.Lead, attachments []repository.Attachment) string {
+func buildLeadContextSection(lead repository.Lead, attachments []repository.Attachment, customFields map[string]any) string {
 	energySummary := buildEnergySummary(lead)
 	enrichmentSummary := buildEnrichmentSummary(lead)
 	attachmentsSummary := buildAttachmentsSummary(attachments)
@@ -580,9 +588,28 @@ func buildLeadContextSection(lead repository.Lead, attachments []repository.Atta
 		"Energy: " + energySummary,
 		"Enrichment: " + enrichmentSummary,
 		"Attachments: " + attachmentsSummary,
+		"Custom fields: " + buildCustomFieldsSummary(customFields),
 	}, "\n"))
 }
 
+// buildCustomFieldsSummary lists the organization-defined field values of a lead, sorted by key.
+func buildCustomFieldsSummary(customFields map[string]any) string {
+	if len(customFields) == 0 {
+		return "None"
+	}
+	keys := make([]string, 0, len(customFields))
+	for key := range customFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+sanitizeUserInput(fmt.Sprint(customFields[key]), 200))
+	}
+	return strings.Join(parts, "; ")
+}
+
 func buildEnergySummary(lead repository.Lead) string {
 	if lead.EnergyClass == nil && lead.EnergyIndex == nil && lead.EnergyBouwjaar == nil && lead.EnergyGebouwtype == nil {
 		return "No energy label data"
@@ -632,3 +632,19 @@ func TestBuildNotesSectionTruncationKeepsNewestNotes(t *testing.T) {
 		t.Fatalf("expected truncation marker, got %s", section)
 	}
 }
+
+func TestBuildGatekeeperPromptIncludesCustomFields(t *testing.T) {
+	lead, service, notes, visitReport, attachments := testPromptFixtures()
+	prompt := buildGatekeeperPrompt(gatekeeperPromptInput{
+		lead:         lead,
+		service:      service,
+		notes:        notes,
+		visitReport:  visitReport,
+		attachments:  attachments,
+		customFields: map[string]any{"roof_type": "Plat", "monument": true},
+	})
+
+	if !strings.Contains(prompt, "Custom fields: monument=true; roof_type=Plat") {
+		t.Fatalf("expected sorted custom fields in lead context, got %s", prompt)
+	}
+}
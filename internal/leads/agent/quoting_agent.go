@@ -240,7 +240,12 @@ func buildQuotingTools(deps *ToolDependencies, mode quotingAgentMode) ([]tool.To
 			return nil, fmt.Errorf("failed to build ListCatalogGaps tool: %w", err)
 		}
 
-		tools = append(tools, calculateEstimateTool, saveEstimationTool, updateStageTool, listCatalogGapsTool)
+		rememberFactTool, err := createRememberLeadFactTool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build RememberLeadFact tool: %w", err)
+		}
+
+		tools = append(tools, calculateEstimateTool, saveEstimationTool, updateStageTool, listCatalogGapsTool, rememberFactTool)
 	}
 
 	if deps.IsProductSearchEnabled() {
@@ -313,6 +318,7 @@ func (q *QuotingAgent) Run(ctx context.Context, leadID, serviceID, tenantID uuid
 	reqDeps := q.toolDeps.NewRequestDeps()
 	bgCtx := context.WithoutCancel(ctx)
 	bgCtx = confirmation.WithTenantID(bgCtx, tenantID)
+	bgCtx = confirmation.WithLeadService(bgCtx, leadID, serviceID)
 	bgCtx = WithDependencies(bgCtx, reqDeps)
 	go func() {
 		defer func() {
@@ -334,6 +340,7 @@ func (q *QuotingAgent) Execute(ctx context.Context, leadID, serviceID, tenantID
 
 	reqDeps := q.toolDeps.NewRequestDeps()
 	ctx = confirmation.WithTenantID(ctx, tenantID)
+	ctx = confirmation.WithLeadService(ctx, leadID, serviceID)
 	ctx = WithDependencies(ctx, reqDeps)
 	return q.executeAutonomousRun(ctx, reqDeps, leadID, serviceID, tenantID, force)
 }
@@ -437,6 +444,9 @@ func (q *QuotingAgent) startAutonomousRun(ctx context.Context, reqDeps *ToolDepe
 	if _, err := reqDeps.LoadOrganizationAISettings(ctx); err != nil {
 		log.Printf("quoting-agent: failed to load org AI settings (tenant=%s): %v", tenantID, err)
 	}
+	if _, err := reqDeps.LoadServiceMemory(ctx); err != nil {
+		log.Printf("quoting-agent: failed to load service memory (service=%s): %v", serviceID, err)
+	}
 
 	existingQuoteID, quoteLookupErr := q.repo.GetLatestDraftQuoteID(ctx, serviceID, tenantID)
 	if quoteLookupErr != nil {
@@ -571,6 +581,7 @@ func (q *QuotingAgent) buildEnhancedEstimationContext(ctx context.Context, tenan
 		sb.WriteString("Use balanced reasoning with explicit checks before final stage update.\n")
 	}
 
+	appendContextSection(&sb, buildServiceMemorySection(deps.GetServiceMemory()))
 	appendContextSection(&sb, memorySection)
 	appendContextSection(&sb, humanFeedbackSection)
 	appendContextSection(&sb, pricingIntelligenceSection)
@@ -756,7 +767,12 @@ func (q *QuotingAgent) maybeApplyNurturingFallback(ctx context.Context, reqDeps
 		return
 	}
 
-	if _, err := q.repo.UpdatePipelineStage(ctx, fallback.ServiceID, fallback.TenantID, domain.PipelineStageNurturing); err != nil {
+	attributed := repository.WithStageTransitionActor(ctx, repository.StageTransitionActor{
+		Type:   repository.ActorTypeAI,
+		Name:   repository.ActorNameEstimator,
+		Reason: "no stage update recorded by the quoting run",
+	})
+	if _, err := q.repo.UpdatePipelineStage(attributed, fallback.ServiceID, fallback.TenantID, domain.PipelineStageNurturing); err != nil {
 		log.Printf("quoting-agent: fallback stage update to Nurturing failed (runID=%s lead=%s service=%s): %v", fallback.RunID, fallback.LeadID, fallback.ServiceID, err)
 		return
 	}
@@ -810,6 +826,7 @@ func (q *QuotingAgent) Generate(ctx context.Context, leadID, serviceID, tenantID
 		notes = nil
 	}
 
+	reportGenerationProgress(ctx, GenerationProgress{Stage: GenerationStageAnalyzing})
 	estimationContext := q.fetchEstimationGuidelines(ctx, tenantID, service.ServiceType)
 	promptText := buildQuoteGeneratePrompt(lead, service, notes, userPrompt, estimationContext)
 
@@ -822,6 +839,7 @@ func (q *QuotingAgent) Generate(ctx context.Context, leadID, serviceID, tenantID
 		return nil, fmt.Errorf("quote generator: agent did not produce a draft quote")
 	}
 
+	reportGenerationProgress(ctx, GenerationProgress{Stage: GenerationStageReviewing, Count: result.ItemCount})
 	q.runQuoteCriticAndRepair(ctx, reqDeps, lead, service, notes, tenantID)
 
 	return &GenerateResult{
@@ -1224,7 +1242,12 @@ func (q *QuotingAgent) emitQuoteCriticHumanAlert(ctx context.Context, reqDeps *T
 			ErrorCode: "quote_critic_requires_human",
 		}.ToMap(),
 	})
-	if _, err := q.repo.UpdatePipelineStage(ctx, serviceID, tenantID, domain.PipelineStageManualIntervention); err != nil {
+	attributed := repository.WithStageTransitionActor(ctx, repository.StageTransitionActor{
+		Type:   repository.ActorTypeAI,
+		Name:   repository.ActorNameEstimator,
+		Reason: "quote critic requires human review",
+	})
+	if _, err := q.repo.UpdatePipelineStage(attributed, serviceID, tenantID, domain.PipelineStageManualIntervention); err != nil {
 		log.Printf("quoting-agent: failed to update stage to Manual_Intervention for service=%s: %v", serviceID, err)
 	}
 }
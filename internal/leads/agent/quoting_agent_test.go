@@ -81,8 +81,9 @@ func TestBuildQuotingToolsEstimatorIncludesAutonomousTools(t *testing.T) {
 	assertHasTool(t, names, "SaveEstimation")
 	assertHasTool(t, names, "UpdatePipelineStage")
 	assertHasTool(t, names, "ListCatalogGaps")
-	if len(names) != 7 {
-		t.Fatalf("expected 7 estimator tools, got %d: %v", len(names), names)
+	assertHasTool(t, names, "RememberLeadFact")
+	if len(names) != 8 {
+		t.Fatalf("expected 8 estimator tools, got %d: %v", len(names), names)
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 
 	"portal_final_backend/internal/leads/ports"
 	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/openaicompat"
 	"portal_final_backend/platform/apperr"
 )
@@ -83,6 +84,7 @@ func (a *ReplyAgent) SetContextReaders(quoteReader ports.ReplyQuoteReader, appoi
 }
 
 func (a *ReplyAgent) SuggestWhatsAppReply(ctx context.Context, input ports.WhatsAppReplyInput) (ports.ReplySuggestionDraft, error) {
+	ctx = llmrouter.WithOrganizationID(ctx, input.OrganizationID)
 	rc, err := a.loadWhatsAppReplyContext(ctx, input)
 	if err != nil {
 		return ports.ReplySuggestionDraft{}, err
@@ -633,7 +635,7 @@ func formatLeadNotesBlock(notes []repository.LeadNote) string {
 		start = len(notes) - maxLeadNoteItems
 	}
 	for _, note := range notes[start:] {
-		body := sanitizePromptField(note.Body, 500)
+		body := sanitizePromptField(note.PlainBody(), 500)
 		label := strings.ToLower(strings.TrimSpace(note.Type))
 		entry := fmt.Sprintf("- %s [%s]", body, formatFreshness(note.CreatedAt))
 		switch {
@@ -826,6 +828,7 @@ type emailReplyLookupStore interface {
 }
 
 func (a *ReplyAgent) SuggestEmailReply(ctx context.Context, input ports.EmailReplyInput) (ports.ReplySuggestionDraft, error) {
+	ctx = llmrouter.WithOrganizationID(ctx, input.OrganizationID)
 	rc, err := a.loadEmailReplyContext(ctx, input)
 	if err != nil {
 		return ports.ReplySuggestionDraft{}, err
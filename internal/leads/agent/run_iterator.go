@@ -17,6 +17,7 @@ import (
 	"google.golang.org/genai"
 
 	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/otel"
 )
 
@@ -158,6 +159,7 @@ func runPromptSession(ctx context.Context, req promptRunRequest, handle func(*se
 		RunID:     req.SessionID,
 	})
 	defer span.End()
+	ctx = llmrouter.WithSource(ctx, req.TraceLabel)
 
 	sessionStart := time.Now()
 
@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"google.golang.org/adk/tool"
+
+	"portal_final_backend/internal/leads/repository"
+	apptools "portal_final_backend/internal/tools"
+)
+
+const (
+	// maxServiceMemoryEntries is the number of remembered facts per lead service
+	// above which the oldest ones are folded into the summary entry.
+	maxServiceMemoryEntries = 40
+	// keepRecentServiceMemories is the number of facts left after compaction.
+	keepRecentServiceMemories    = 25
+	maxServiceMemoryFactChars    = 300
+	maxServiceMemorySummaryChars = 2000
+	maxServiceMemorySectionChars = 4000
+	emptyServiceMemoryText       = "- No facts remembered from earlier runs."
+)
+
+var rememberableServiceMemoryKinds = map[string]struct{}{
+	repository.ServiceMemoryKindDimension:      {},
+	repository.ServiceMemoryKindPreference:     {},
+	repository.ServiceMemoryKindRejectedOption: {},
+	repository.ServiceMemoryKindFact:           {},
+}
+
+func createRememberLeadFactTool() (tool.Tool, error) {
+	return apptools.NewRememberLeadFactTool(withDeps(handleRememberLeadFact))
+}
+
+// LoadServiceMemory fetches the facts remembered for the current lead service
+// and keeps them on the dependencies for prompt building.
+func (d *ToolDependencies) LoadServiceMemory(ctx context.Context) ([]repository.ServiceMemory, error) {
+	tenantID, err := getTenantID(d)
+	if err != nil {
+		return nil, err
+	}
+	_, serviceID, err := getLeadContext(d)
+	if err != nil {
+		return nil, err
+	}
+	memories, err := d.Repo.ListServiceMemories(ctx, serviceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.serviceMemory = memories
+	d.mu.Unlock()
+	return memories, nil
+}
+
+// GetServiceMemory returns the facts loaded by LoadServiceMemory.
+func (d *ToolDependencies) GetServiceMemory() []repository.ServiceMemory {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]repository.ServiceMemory(nil), d.serviceMemory...)
+}
+
+func handleRememberLeadFact(ctx tool.Context, deps *ToolDependencies, input RememberLeadFactInput) (RememberLeadFactOutput, error) {
+	kind := strings.ToLower(strings.TrimSpace(input.Kind))
+	if _, ok := rememberableServiceMemoryKinds[kind]; !ok {
+		return RememberLeadFactOutput{Success: false, Message: "Kind must be one of: dimension, preference, rejected_option, fact"}, nil
+	}
+	fact := strings.Join(strings.Fields(input.Fact), " ")
+	if fact == "" {
+		return RememberLeadFactOutput{Success: false, Message: "Missing fact"}, nil
+	}
+	if utf8.RuneCountInString(fact) > maxServiceMemoryFactChars {
+		return RememberLeadFactOutput{Success: false, Message: fmt.Sprintf("Fact is too long; keep it under %d characters", maxServiceMemoryFactChars)}, nil
+	}
+
+	tenantID, err := getTenantID(deps)
+	if err != nil {
+		return RememberLeadFactOutput{Success: false, Message: missingTenantContextMessage}, err
+	}
+	leadID, serviceID, err := getLeadContext(deps)
+	if err != nil {
+		return RememberLeadFactOutput{Success: false, Message: missingLeadContextMessage}, err
+	}
+
+	_, actorName := deps.GetActor()
+	var runID *string
+	if id := deps.GetRunID(); id != "" {
+		runID = &id
+	}
+	if err := deps.Repo.AddServiceMemory(ctx, repository.AddServiceMemoryParams{
+		OrganizationID: tenantID,
+		LeadID:         leadID,
+		LeadServiceID:  serviceID,
+		Kind:           kind,
+		Content:        fact,
+		Source:         actorName,
+		RunID:          runID,
+	}); err != nil {
+		return RememberLeadFactOutput{Success: false, Message: "Failed to remember fact"}, err
+	}
+
+	compactServiceMemory(ctx, deps.Repo, serviceID, tenantID)
+	return RememberLeadFactOutput{Success: true, Message: "Fact remembered"}, nil
+}
+
+// compactServiceMemory folds the oldest facts of a lead service into its summary
+// entry once there are too many. Failures are logged; the facts stay as they are.
+func compactServiceMemory(ctx context.Context, repo repository.ServiceMemoryStore, serviceID, tenantID uuid.UUID) {
+	memories, err := repo.ListServiceMemories(ctx, serviceID, tenantID)
+	if err != nil {
+		log.Printf("service memory: list for compaction failed service=%s: %v", serviceID, err)
+		return
+	}
+	removeIDs, summary, ok := planServiceMemoryCompaction(memories)
+	if !ok {
+		return
+	}
+	if err := repo.CompactServiceMemories(ctx, serviceID, tenantID, removeIDs, summary); err != nil {
+		log.Printf("service memory: compaction failed service=%s: %v", serviceID, err)
+	}
+}
+
+// planServiceMemoryCompaction decides which facts to fold into the summary when a
+// lead service has more than maxServiceMemoryEntries. The newest facts are kept;
+// the older ones are appended to the existing summary, whose oldest parts are
+// dropped when it grows beyond maxServiceMemorySummaryChars.
+func planServiceMemoryCompaction(memories []repository.ServiceMemory) ([]uuid.UUID, string, bool) {
+	var summary string
+	facts := make([]repository.ServiceMemory, 0, len(memories))
+	for _, m := range memories {
+		if m.Kind == repository.ServiceMemoryKindSummary {
+			summary = m.Content
+			continue
+		}
+		facts = append(facts, m)
+	}
+	if len(facts) <= maxServiceMemoryEntries {
+		return nil, "", false
+	}
+
+	older := facts[:len(facts)-keepRecentServiceMemories]
+	parts := make([]string, 0, len(older)+1)
+	if summary != "" {
+		parts = append(parts, strings.Split(summary, "; ")...)
+	}
+	removeIDs := make([]uuid.UUID, 0, len(older))
+	for _, m := range older {
+		parts = append(parts, m.Kind+": "+m.Content)
+		removeIDs = append(removeIDs, m.ID)
+	}
+	for len(parts) > 1 && utf8.RuneCountInString(strings.Join(parts, "; ")) > maxServiceMemorySummaryChars {
+		parts = parts[1:]
+	}
+	return removeIDs, truncateRunes(strings.Join(parts, "; "), maxServiceMemorySummaryChars), true
+}
+
+// buildServiceMemoryLines renders remembered facts as prompt lines, or "" when there are none.
+func buildServiceMemoryLines(memories []repository.ServiceMemory) string {
+	if len(memories) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(memories))
+	for _, m := range memories {
+		label := m.Kind
+		if m.Kind == repository.ServiceMemoryKindSummary {
+			label = "earlier runs (summary)"
+		}
+		lines = append(lines, fmt.Sprintf("- [%s] %s", label, sanitizeUserInput(m.Content, maxServiceMemorySummaryChars)))
+	}
+	return truncatePromptSection(strings.Join(lines, "\n"), maxServiceMemorySectionChars)
+}
+
+// buildServiceMemorySection renders the estimator prompt section with remembered facts.
+func buildServiceMemorySection(memories []repository.ServiceMemory) string {
+	lines := buildServiceMemoryLines(memories)
+	if lines == "" {
+		return ""
+	}
+	return "=== LEAD MEMORY ===\nFacts learned in earlier runs for this service. Treat them as confirmed unless newer information contradicts them.\n" + lines
+}
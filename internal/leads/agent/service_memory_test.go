@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"portal_final_backend/internal/leads/repository"
+)
+
+func testServiceMemories(n int) []repository.ServiceMemory {
+	memories := make([]repository.ServiceMemory, n)
+	for i := range memories {
+		memories[i] = repository.ServiceMemory{ID: uuid.New(), Kind: repository.ServiceMemoryKindDimension, Content: fmt.Sprintf("raam %d: 120x80 cm", i)}
+	}
+	return memories
+}
+
+func TestPlanServiceMemoryCompactionBelowLimit(t *testing.T) {
+	if _, _, ok := planServiceMemoryCompaction(testServiceMemories(maxServiceMemoryEntries)); ok {
+		t.Fatal("expected no compaction at the limit")
+	}
+}
+
+func TestPlanServiceMemoryCompactionFoldsOldestFacts(t *testing.T) {
+	summary := repository.ServiceMemory{ID: uuid.New(), Kind: repository.ServiceMemoryKindSummary, Content: "preference: liefst in het voorjaar"}
+	facts := testServiceMemories(maxServiceMemoryEntries + 1)
+	memories := append([]repository.ServiceMemory{summary}, facts...)
+
+	removeIDs, folded, ok := planServiceMemoryCompaction(memories)
+	if !ok {
+		t.Fatal("expected compaction above the limit")
+	}
+	wantRemoved := len(facts) - keepRecentServiceMemories
+	if len(removeIDs) != wantRemoved {
+		t.Fatalf("removed %d facts, want %d", len(removeIDs), wantRemoved)
+	}
+	if removeIDs[0] != facts[0].ID {
+		t.Fatalf("expected the oldest fact to be folded first")
+	}
+	if !strings.HasPrefix(folded, "preference: liefst in het voorjaar; dimension: raam 0") {
+		t.Fatalf("summary should extend the existing summary, got %q", folded)
+	}
+}
+
+func TestPlanServiceMemoryCompactionCapsSummary(t *testing.T) {
+	long := strings.Repeat("x", maxServiceMemorySummaryChars)
+	memories := append([]repository.ServiceMemory{{ID: uuid.New(), Kind: repository.ServiceMemoryKindSummary, Content: long}}, testServiceMemories(maxServiceMemoryEntries+5)...)
+
+	_, folded, ok := planServiceMemoryCompaction(memories)
+	if !ok {
+		t.Fatal("expected compaction above the limit")
+	}
+	if len(folded) > maxServiceMemorySummaryChars {
+		t.Fatalf("summary has %d chars, want at most %d", len(folded), maxServiceMemorySummaryChars)
+	}
+	if strings.Contains(folded, long) {
+		t.Fatal("expected the oldest summary part to be dropped")
+	}
+}
+
+func TestBuildServiceMemorySection(t *testing.T) {
+	if got := buildServiceMemorySection(nil); got != "" {
+		t.Fatalf("expected empty section, got %q", got)
+	}
+	section := buildServiceMemorySection([]repository.ServiceMemory{
+		{Kind: repository.ServiceMemoryKindSummary, Content: "dimension: dak 40 m2"},
+		{Kind: repository.ServiceMemoryKindRejectedOption, Content: "geen kunststof kozijnen"},
+	})
+	for _, want := range []string{"=== LEAD MEMORY ===", "- [earlier runs (summary)] dimension: dak 40 m2", "- [rejected_option] geen kunststof kozijnen"} {
+		if !strings.Contains(section, want) {
+			t.Fatalf("section missing %q:\n%s", want, section)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"portal_final_backend/internal/leads/ports"
 	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/openaicompat"
 )
 
@@ -68,6 +69,7 @@ func (a *StaleReEngagementAgent) GenerateSuggestion(
 	orgID, leadID, serviceID uuid.UUID,
 	staleReason string,
 ) (StaleReEngagementResult, error) {
+	ctx = llmrouter.WithOrganizationID(ctx, orgID)
 	lead, service, notes, timeline, analysis, err := a.loadContext(ctx, orgID, leadID, serviceID)
 	if err != nil {
 		return StaleReEngagementResult{}, fmt.Errorf("stale reengagement: load context: %w", err)
@@ -403,7 +405,7 @@ func formatReEngageNotes(notes []repository.LeadNote) string {
 		if sb.Len() > 0 {
 			sb.WriteString("\n")
 		}
-		content := sanitizePromptField(n.Body, maxReEngageNoteChars)
+		content := sanitizePromptField(n.PlainBody(), maxReEngageNoteChars)
 		fmt.Fprintf(&sb, "- [%s] %s", n.CreatedAt.Format(dateTimeLayout), content)
 	}
 	return sb.String()
@@ -12,6 +12,7 @@ import (
 	"google.golang.org/adk/session"
 
 	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/openaicompat"
 )
 
@@ -35,7 +36,7 @@ type SubsidyAnalyzerConfig struct {
 // NewSubsidyAnalyzerAgent creates a new subsidy analyzer agent.
 func NewSubsidyAnalyzerAgent(cfg SubsidyAnalyzerConfig, sessionService session.Service) (*SubsidyAnalyzer, error) {
 	modelConfig := cfg.ModelConfig
-	kimi := openaicompat.NewModel(modelConfig)
+	kimi := BuildLLM(modelConfig)
 
 	// Create ADK agent with LLM (no tools for now, agent will respond with structured text)
 	adkAgent, err := llmagent.New(llmagent.Config{
@@ -68,6 +69,7 @@ func NewSubsidyAnalyzerAgent(cfg SubsidyAnalyzerConfig, sessionService session.S
 
 // Run executes the agent for a given quote.
 func (a *SubsidyAnalyzer) Run(ctx context.Context, quoteID uuid.UUID, organizationID uuid.UUID, quoteContext string) (map[string]interface{}, error) {
+	ctx = llmrouter.WithOrganizationID(ctx, organizationID)
 	// Build the prompt for the agent
 	prompt := fmt.Sprintf("Analyze this quote context and suggest appropriate subsidy measures and installations:\n\n%s", quoteContext)
 
@@ -9,6 +9,7 @@ import (
 	"google.golang.org/adk/tool"
 
 	apptools "portal_final_backend/internal/tools"
+	"portal_final_backend/platform/adk/confirmation"
 )
 
 // withDeps wraps a handler that needs ToolDependencies so the wrapper
@@ -36,8 +37,10 @@ func createUpdateLeadServiceTypeTool() (tool.Tool, error) {
 	return apptools.NewUpdateLeadServiceTypeTool(withDeps(handleUpdateLeadServiceType))
 }
 
+// createUpdateLeadDetailsTool builds the autonomous detail update, which the
+// organization's review policy can hold for review as a detail overwrite.
 func createUpdateLeadDetailsTool(description string) (tool.Tool, error) {
-	return apptools.NewUpdateLeadDetailsTool(description, withDeps(handleUpdateLeadDetails))
+	return apptools.NewUpdateLeadDetailsTool(description, confirmation.WrapToolHandler("UpdateLeadDetails", withDeps(handleUpdateLeadDetails)))
 }
 
 func latestAnalysisInvariantInputs(ctx context.Context, deps *ToolDependencies, serviceID, tenantID uuid.UUID) (string, []string) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"portal_final_backend/internal/events"
 	"portal_final_backend/internal/leads/domain"
 	"portal_final_backend/internal/leads/repository"
 	"portal_final_backend/internal/leads/scoring"
@@ -507,6 +508,19 @@ func handleSaveAnalysis(ctx tool.Context, deps *ToolDependencies, input SaveAnal
 
 	recalculateAndRecordScore(ctx, deps, leadID, leadServiceID, tenantID, actorType, actorName)
 
+	if deps.EventBus != nil {
+		deps.EventBus.Publish(ctx, events.LeadActionRecommended{
+			BaseEvent:         events.NewBaseEvent(),
+			LeadID:            leadID,
+			LeadServiceID:     leadServiceID,
+			TenantID:          tenantID,
+			AssignedAgentID:   lead.AssignedAgentID,
+			RecommendedAction: normalized.RecommendedAction,
+			UrgencyLevel:      normalized.UrgencyLevel,
+			Summary:           normalized.Summary,
+		})
+	}
+
 	log.Printf(
 		"gatekeeper SaveAnalysis: run=%s leadId=%s serviceId=%s urgency=%s quality=%s action=%s missing=%d confidence=%.2f risk_flags=%d",
 		deps.GetRunID(),
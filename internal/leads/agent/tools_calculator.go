@@ -49,6 +49,7 @@ func createSaveEstimationTool() (tool.Tool, error) {
 				PriceRange: input.PriceRange,
 				Notes:      input.Notes,
 			}.ToMap(),
+			DedupKey: agentRunDedupKey(deps, "estimation_saved"),
 		})
 		if err != nil {
 			return SaveEstimationOutput{Success: false, Message: "Failed to save estimation"}, err
@@ -114,6 +115,7 @@ func createAskCustomerClarificationTool() (tool.Tool, error) {
 				"noteType":          "ai_clarification_request",
 				"missingDimensions": input.MissingDimensions,
 			},
+			DedupKey: agentRunDedupKey(deps, "ai_clarification_request"),
 		})
 		if err != nil {
 			return AskCustomerClarificationOutput{Success: false, Message: "Kon verduidelijkingsvraag niet opslaan"}, err
@@ -121,3 +121,13 @@ func TestCreateCalculatorToolProcessRequestIncludesExpressionSchema(t *testing.T
 		}
 	}
 }
+
+func TestAgentRunDedupKeyScopesToRun(t *testing.T) {
+	if got := agentRunDedupKey(&ToolDependencies{}, "estimation_saved"); got != "" {
+		t.Fatalf("expected no dedup key outside a run, got %q", got)
+	}
+	deps := &ToolDependencies{runID: "service-1:run-1"}
+	if got, want := agentRunDedupKey(deps, "estimation_saved"), "estimation_saved:service-1:run-1"; got != want {
+		t.Fatalf("expected dedup key %q, got %q", want, got)
+	}
+}
@@ -2,11 +2,13 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"portal_final_backend/internal/leads/ports"
 	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/circuitbreaker"
 	"portal_final_backend/platform/qdrant"
 	"sort"
 	"strings"
@@ -21,6 +23,20 @@ func noMatchMessage(query string) string {
 	return fmt.Sprintf("No relevant products found for query '%s'. Try different search terms (synonyms, broader/narrower terms, Dutch and English). If no match exists, you may add an ad-hoc item.", query)
 }
 
+// productSearchUnavailableMessage lets the agent finish its work without product
+// search while the embedding service or Qdrant is down.
+const productSearchUnavailableMessage = "Product search is temporarily unavailable. Continue without catalog products: add ad-hoc items with estimated prices and note that the prices still need to be checked."
+
+// productSearchUnavailable turns an open circuit breaker into a regular tool
+// answer instead of a failed tool call.
+func productSearchUnavailable(err error) (SearchProductMaterialsOutput, bool) {
+	if !errors.Is(err, circuitbreaker.ErrOpen) {
+		return SearchProductMaterialsOutput{}, false
+	}
+	log.Printf("SearchProductMaterials: skipped, dependency unavailable: %v", err)
+	return SearchProductMaterialsOutput{Products: nil, Message: productSearchUnavailableMessage}, true
+}
+
 func recordCatalogSearch(ctx context.Context, deps *ToolDependencies, query string, collection string, resultCount int, topScore *float64) {
 	tenantID, ok := deps.GetTenantID()
 	if !ok || tenantID == nil {
@@ -222,18 +238,16 @@ func resolveSearchParams(input SearchProductMaterialsInput) (query string, limit
 // Returns an error when the underlying vector search fails so callers can abort rather
 // than hallucinate ad-hoc products.
 func searchCatalogCollection(ctx tool.Context, deps *ToolDependencies, vector []float32, limit int, scoreThreshold float64, query string) ([]ProductResult, error) {
+	// The catalog collection is tenant-owned: fail closed instead of searching unfiltered.
 	tenantID, tenantOk := deps.GetTenantID()
-	var filter *qdrant.Filter
-	if tenantOk && tenantID != nil {
-		filter = qdrant.NewOrganizationFilter(tenantID.String())
-		log.Printf("SearchProductMaterials: catalog search with tenant filter organization_id=%s", tenantID.String())
-	} else {
-		log.Printf("SearchProductMaterials: catalog search without tenant filter (missing tenant context)")
+	if !tenantOk || tenantID == nil {
+		log.Printf("SearchProductMaterials: refusing catalog search without tenant context")
+		return nil, qdrant.ErrMissingTenant
 	}
 
 	searchCtx, searchCancel := detachedTimeout(ctx, toolIOTimeout)
 	defer searchCancel()
-	results, err := deps.CatalogQdrantClient.SearchWithFilter(searchCtx, vector, limit, scoreThreshold, filter)
+	results, err := deps.CatalogQdrantClient.SearchForTenant(searchCtx, tenantID.String(), vector, limit, scoreThreshold)
 	if err != nil {
 		log.Printf("SearchProductMaterials: catalog search failed: %v", err)
 		recordCatalogSearch(ctx, deps, query, "catalog", 0, nil)
@@ -284,12 +298,18 @@ func handleSearchProductMaterials(ctx tool.Context, deps *ToolDependencies, inpu
 	embedCtx, embedCancel := detachedTimeout(ctx, toolIOTimeout)
 	defer embedCancel()
 	vector, err := deps.EmbeddingClient.Embed(embedCtx, query)
+	if output, skipped := productSearchUnavailable(err); skipped {
+		return output, nil
+	}
 	if err != nil {
 		log.Printf("SearchProductMaterials: embedding failed: %v", err)
 		return SearchProductMaterialsOutput{Products: nil, Message: "Failed to generate embedding for query"}, err
 	}
 
 	catalogOutput, foundInCatalog, catalogErr := tryCatalogSearchFlow(ctx, deps, query, limit, scoreThreshold, useCatalog, vector)
+	if output, skipped := productSearchUnavailable(catalogErr); skipped {
+		return output, nil
+	}
 	if catalogErr != nil {
 		return SearchProductMaterialsOutput{Products: nil, Message: "Product catalog search failed"}, catalogErr
 	}
@@ -310,6 +330,9 @@ func handleSearchProductMaterials(ctx tool.Context, deps *ToolDependencies, inpu
 			setBothCaches(catalogOutput)
 			return catalogOutput, nil
 		}
+		if output, skipped := productSearchUnavailable(fallbackErr); skipped {
+			return output, nil
+		}
 		return fallbackOutput, fallbackErr
 	}
 
@@ -1132,6 +1155,11 @@ func applyProductDetails(products []ProductResult, details []ports.CatalogProduc
 		mergeOptionalString(&products[i].Unit, d.UnitLabel)
 		mergeOptionalString(&products[i].LaborTime, d.LaborTimeText)
 		mergeOptionalString(&products[i].Description, d.Description)
+		if d.StockTracked {
+			inStock, quantity := d.StockQuantity > 0, d.StockQuantity
+			products[i].InStock = &inStock
+			products[i].StockQuantity = &quantity
+		}
 	}
 	return products
 }
@@ -1144,5 +1172,5 @@ func mergeOptionalString(dst *string, src string) {
 }
 
 func createSearchProductMaterialsTool() (tool.Tool, error) {
-	return apptools.NewSearchProductMaterialsTool(withDeps(handleSearchProductMaterials))
+	return apptools.NewSearchProductMaterialsTool(withDeps(handleSearchProductMaterialsWithProgress))
 }
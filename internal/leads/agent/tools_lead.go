@@ -87,6 +87,16 @@ func handleUpdateLeadServiceType(ctx tool.Context, deps *ToolDependencies, input
 	return UpdateLeadServiceTypeOutput{Success: true, Message: "Service type updated"}, nil
 }
 
+// maxVersionConflictAttempts bounds how often a lead update is rebuilt from a
+// fresh read after losing a race with another writer.
+const maxVersionConflictAttempts = 3
+
+// leadDetailsInputError marks input the tool rejects, as opposed to a failed write.
+type leadDetailsInputError struct{ err error }
+
+func (e leadDetailsInputError) Error() string { return e.err.Error() }
+func (e leadDetailsInputError) Unwrap() error { return e.err }
+
 // leadDetailsBuilder encapsulates field update logic for handleUpdateLeadDetails
 type leadDetailsBuilder struct {
 	params        repository.UpdateLeadParams
@@ -257,32 +267,58 @@ func handleUpdateLeadDetails(ctx tool.Context, deps *ToolDependencies, input Upd
 		return UpdateLeadDetailsOutput{Success: false, Message: missingTenantContextMessage}, err
 	}
 
-	current, err := deps.Repo.GetByID(ctx, leadID, tenantID)
+	builder, err := applyLeadDetails(ctx, deps, leadID, tenantID, input)
 	if err != nil {
-		return UpdateLeadDetailsOutput{Success: false, Message: leadNotFoundMessage}, err
-	}
-
-	builder := newLeadDetailsBuilder()
-	if err := builder.buildFromInput(input, current); err != nil {
-		return UpdateLeadDetailsOutput{Success: false, Message: err.Error()}, err
+		var inputErr leadDetailsInputError
+		switch {
+		case errors.As(err, &inputErr):
+			return UpdateLeadDetailsOutput{Success: false, Message: inputErr.Error()}, inputErr.err
+		case errors.Is(err, repository.ErrNotFound):
+			return UpdateLeadDetailsOutput{Success: false, Message: leadNotFoundMessage}, err
+		default:
+			return UpdateLeadDetailsOutput{Success: false, Message: "Failed to update lead"}, err
+		}
 	}
-
 	if len(builder.updatedFields) == 0 {
 		return UpdateLeadDetailsOutput{Success: true, Message: "No updates required"}, nil
 	}
 
-	_, err = deps.Repo.Update(ctx, leadID, tenantID, builder.params)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return UpdateLeadDetailsOutput{Success: false, Message: leadNotFoundMessage}, err
-		}
-		return UpdateLeadDetailsOutput{Success: false, Message: "Failed to update lead"}, err
-	}
-
 	recordLeadDetailsUpdate(ctx, deps, leadID, tenantID, builder.updatedFields, input.Reason, input.Confidence)
 	return UpdateLeadDetailsOutput{Success: true, Message: "Lead updated", UpdatedFields: builder.updatedFields}, nil
 }
 
+// applyLeadDetails writes the fields of the input that differ from the stored
+// lead. The comparison rests on a read, so the write only goes ahead when the
+// lead is still at the version read; otherwise it starts over from a fresh read.
+func applyLeadDetails(ctx tool.Context, deps *ToolDependencies, leadID, tenantID uuid.UUID, input UpdateLeadDetailsInput) (*leadDetailsBuilder, error) {
+	for attempt := 1; ; attempt++ {
+		current, _, err := deps.Repo.GetByIDWithServices(ctx, leadID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		builder := newLeadDetailsBuilder()
+		if err := builder.buildFromInput(input, current); err != nil {
+			return nil, leadDetailsInputError{err: err}
+		}
+		if len(builder.updatedFields) == 0 {
+			return builder, nil
+		}
+
+		readVersion := current.RowVersion
+		builder.params.ExpectedRowVersion = &readVersion
+		_, err = deps.Repo.Update(ctx, leadID, tenantID, builder.params)
+		if errors.Is(err, repository.ErrVersionConflict) && attempt < maxVersionConflictAttempts {
+			log.Printf("UpdateLeadDetails: lead %s changed concurrently, retrying with a fresh read", leadID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return builder, nil
+	}
+}
+
 func recordLeadDetailsUpdate(ctx tool.Context, deps *ToolDependencies, leadID, tenantID uuid.UUID, updatedFields []string, reason string, confidence *float64) {
 	actorType, actorName := deps.GetActor()
 	reasonText := strings.TrimSpace(reason)
@@ -116,7 +116,9 @@ func applyPipelineStageUpdate(ctx context.Context, deps *ToolDependencies, input
 		return out, err
 	}
 
-	_, err = deps.Repo.UpdatePipelineStage(ctx, state.serviceID, state.tenantID, input.Stage)
+	actorType, actorName := deps.GetActor()
+	attributed := repository.WithStageTransitionActor(ctx, repository.StageTransitionActor{Type: actorType, Name: actorName, Reason: input.Reason})
+	_, err = deps.Repo.UpdatePipelineStage(attributed, state.serviceID, state.tenantID, input.Stage)
 	if err != nil {
 		return UpdatePipelineStageOutput{Success: false, Message: "Failed to update pipeline stage"}, err
 	}
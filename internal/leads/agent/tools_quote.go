@@ -91,6 +91,7 @@ func handleDraftQuote(ctx tool.Context, deps *ToolDependencies, input DraftQuote
 	deps.SetLastDraftResult(result)
 	deps.SetExistingQuoteID(&result.QuoteID)
 	deps.MarkDraftQuoteCalled()
+	reportGenerationProgress(ctx, GenerationProgress{Stage: GenerationStageItemsDrafted, Count: result.ItemCount})
 
 	return DraftQuoteOutput{
 		Success:     true,
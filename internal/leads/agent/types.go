@@ -44,6 +44,16 @@ type UpdateLeadServiceTypeOutput struct {
 	Message string `json:"message"`
 }
 
+type RememberLeadFactInput struct {
+	Kind string `json:"kind"` // dimension, preference, rejected_option or fact
+	Fact string `json:"fact"` // One short, self-contained statement
+}
+
+type RememberLeadFactOutput struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
 // UpdateLeadDetailsInput allows the agent to correct lead details with high confidence.
 type UpdateLeadDetailsInput struct {
 	LeadID          string   `json:"leadId"`
@@ -197,6 +207,8 @@ type ProductResult struct {
 	SourceCollection string   `json:"sourceCollection,omitempty"` // Qdrant collection name (fallback diagnostics)
 	Score            float64  `json:"score"`                      // Similarity score
 	HighConfidence   bool     `json:"highConfidence"`             // True when score is strong enough to use found price directly
+	InStock          *bool    `json:"inStock,omitempty"`          // Set only for stock-tracked catalog products; false means out of stock
+	StockQuantity    *float64 `json:"stockQuantity,omitempty"`    // Current stock of stock-tracked catalog products
 }
 
 // SearchProductMaterialsOutput contains the search results.
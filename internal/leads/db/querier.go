@@ -44,7 +44,6 @@ type Querier interface {
 	DeleteLead(ctx context.Context, arg DeleteLeadParams) (int64, error)
 	DeleteLeadService(ctx context.Context, arg DeleteLeadServiceParams) (int64, error)
 	DeleteStaleLeadSuggestion(ctx context.Context, arg DeleteStaleLeadSuggestionParams) error
-	FindMatchingPartnersByCoordinates(ctx context.Context, arg FindMatchingPartnersByCoordinatesParams) ([]FindMatchingPartnersByCoordinatesRow, error)
 	FindPartnersByServiceType(ctx context.Context, arg FindPartnersByServiceTypeParams) ([]FindPartnersByServiceTypeRow, error)
 	FindPartnersByServiceTypeAndCity(ctx context.Context, arg FindPartnersByServiceTypeAndCityParams) ([]FindPartnersByServiceTypeAndCityRow, error)
 	// Relaxed dedup for alert events: matches on (lead, service, event_type, title)
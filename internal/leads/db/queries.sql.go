@@ -253,19 +253,22 @@ WHERE l.organization_id = $1
 	AND ($3::text IS NULL OR st.name = $3::text)
 	AND ($4::text IS NULL OR (
 		l.consumer_first_name ILIKE $4::text OR l.consumer_last_name ILIKE $4::text OR l.consumer_phone ILIKE $4::text OR l.consumer_email ILIKE $4::text OR l.address_city ILIKE $4::text
+		OR rac_custom_fields_text(l.custom_fields) ILIKE $4::text
+		OR l.consumer_phone_bidx = $5::text OR l.consumer_email_bidx = $6::text
 	))
-	AND ($5::text IS NULL OR l.consumer_first_name ILIKE $5::text)
-	AND ($6::text IS NULL OR l.consumer_last_name ILIKE $6::text)
-	AND ($7::text IS NULL OR l.consumer_phone ILIKE $7::text)
-	AND ($8::text IS NULL OR l.consumer_email ILIKE $8::text)
-	AND ($9::text IS NULL OR l.consumer_role = $9::text)
-	AND ($10::text IS NULL OR l.address_street ILIKE $10::text)
-	AND ($11::text IS NULL OR l.address_house_number ILIKE $11::text)
-	AND ($12::text IS NULL OR l.address_zip_code ILIKE $12::text)
-	AND ($13::text IS NULL OR l.address_city ILIKE $13::text)
-	AND ($14::uuid IS NULL OR l.assigned_agent_id = $14::uuid)
-	AND ($15::timestamptz IS NULL OR l.created_at >= $15::timestamptz)
-	AND ($16::timestamptz IS NULL OR l.created_at < $16::timestamptz)
+	AND ($7::text IS NULL OR l.consumer_first_name ILIKE $7::text)
+	AND ($8::text IS NULL OR l.consumer_last_name ILIKE $8::text)
+	AND ($9::text IS NULL OR l.consumer_phone ILIKE $9::text OR l.consumer_phone_bidx = $10::text)
+	AND ($11::text IS NULL OR l.consumer_email ILIKE $11::text OR l.consumer_email_bidx = $12::text)
+	AND ($13::text IS NULL OR l.consumer_role = $13::text)
+	AND ($14::text IS NULL OR l.address_street ILIKE $14::text)
+	AND ($15::text IS NULL OR l.address_house_number ILIKE $15::text)
+	AND ($16::text IS NULL OR l.address_zip_code ILIKE $16::text)
+	AND ($17::text IS NULL OR l.address_city ILIKE $17::text)
+	AND ($18::uuid IS NULL OR l.assigned_agent_id = $18::uuid)
+	AND ($19::timestamptz IS NULL OR l.created_at >= $19::timestamptz)
+	AND ($20::timestamptz IS NULL OR l.created_at < $20::timestamptz)
+	AND ($21::jsonb IS NULL OR l.custom_fields @> $21::jsonb)
 `
 
 type CountLeadsParams struct {
@@ -273,10 +276,14 @@ type CountLeadsParams struct {
 	Status          pgtype.Text        `json:"status"`
 	ServiceType     pgtype.Text        `json:"service_type"`
 	Search          pgtype.Text        `json:"search"`
+	SearchPhoneBidx pgtype.Text        `json:"search_phone_bidx"`
+	SearchEmailBidx pgtype.Text        `json:"search_email_bidx"`
 	FirstName       pgtype.Text        `json:"first_name"`
 	LastName        pgtype.Text        `json:"last_name"`
 	Phone           pgtype.Text        `json:"phone"`
+	PhoneBidx       pgtype.Text        `json:"phone_bidx"`
 	Email           pgtype.Text        `json:"email"`
+	EmailBidx       pgtype.Text        `json:"email_bidx"`
 	Role            pgtype.Text        `json:"role"`
 	Street          pgtype.Text        `json:"street"`
 	HouseNumber     pgtype.Text        `json:"house_number"`
@@ -285,6 +292,7 @@ type CountLeadsParams struct {
 	AssignedAgentID pgtype.UUID        `json:"assigned_agent_id"`
 	CreatedAtFrom   pgtype.Timestamptz `json:"created_at_from"`
 	CreatedAtTo     pgtype.Timestamptz `json:"created_at_to"`
+	CustomFields    []byte             `json:"custom_fields"`
 }
 
 func (q *Queries) CountLeads(ctx context.Context, arg CountLeadsParams) (int32, error) {
@@ -293,10 +301,14 @@ func (q *Queries) CountLeads(ctx context.Context, arg CountLeadsParams) (int32,
 		arg.Status,
 		arg.ServiceType,
 		arg.Search,
+		arg.SearchPhoneBidx,
+		arg.SearchEmailBidx,
 		arg.FirstName,
 		arg.LastName,
 		arg.Phone,
+		arg.PhoneBidx,
 		arg.Email,
+		arg.EmailBidx,
 		arg.Role,
 		arg.Street,
 		arg.HouseNumber,
@@ -305,6 +317,7 @@ func (q *Queries) CountLeads(ctx context.Context, arg CountLeadsParams) (int32,
 		arg.AssignedAgentID,
 		arg.CreatedAtFrom,
 		arg.CreatedAtTo,
+		arg.CustomFields,
 	)
 	var column_1 int32
 	err := row.Scan(&column_1)
@@ -1237,70 +1250,6 @@ func (q *Queries) DeleteStaleLeadSuggestion(ctx context.Context, arg DeleteStale
 	return err
 }
 
-const findMatchingPartnersByCoordinates = `-- name: FindMatchingPartnersByCoordinates :many
-SELECT p.id, p.business_name, p.contact_email,
-	CAST(earth_distance(ll_to_earth($2, $1), ll_to_earth(p.latitude, p.longitude)) / 1000.0 AS double precision) AS dist_km
-FROM RAC_partners p
-JOIN RAC_partner_service_types pst ON pst.partner_id = p.id
-JOIN RAC_service_types st ON st.id = pst.service_type_id AND st.organization_id = p.organization_id
-WHERE p.organization_id = $3
-	AND st.is_active = true
-	AND (st.name = $4 OR st.slug = $4)
-	AND p.latitude IS NOT NULL AND p.longitude IS NOT NULL
-	AND earth_distance(ll_to_earth($2, $1), ll_to_earth(p.latitude, p.longitude)) <= ($5 * 1000.0)
-	AND (CARDINALITY($6::uuid[]) = 0 OR p.id != ALL($6::uuid[]))
-ORDER BY dist_km ASC
-LIMIT 5
-`
-
-type FindMatchingPartnersByCoordinatesParams struct {
-	LlToEarth      float64       `json:"ll_to_earth"`
-	LlToEarth_2    float64       `json:"ll_to_earth_2"`
-	OrganizationID pgtype.UUID   `json:"organization_id"`
-	Name           string        `json:"name"`
-	Column5        interface{}   `json:"column_5"`
-	Column6        []pgtype.UUID `json:"column_6"`
-}
-
-type FindMatchingPartnersByCoordinatesRow struct {
-	ID           pgtype.UUID `json:"id"`
-	BusinessName string      `json:"business_name"`
-	ContactEmail string      `json:"contact_email"`
-	DistKm       float64     `json:"dist_km"`
-}
-
-func (q *Queries) FindMatchingPartnersByCoordinates(ctx context.Context, arg FindMatchingPartnersByCoordinatesParams) ([]FindMatchingPartnersByCoordinatesRow, error) {
-	rows, err := q.db.Query(ctx, findMatchingPartnersByCoordinates,
-		arg.LlToEarth,
-		arg.LlToEarth_2,
-		arg.OrganizationID,
-		arg.Name,
-		arg.Column5,
-		arg.Column6,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []FindMatchingPartnersByCoordinatesRow
-	for rows.Next() {
-		var i FindMatchingPartnersByCoordinatesRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.BusinessName,
-			&i.ContactEmail,
-			&i.DistKm,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const findPartnersByServiceType = `-- name: FindPartnersByServiceType :many
 SELECT p.id, p.business_name, p.contact_email,
 	0.0::double precision AS dist_km
@@ -3884,45 +3833,48 @@ SELECT id, consumer_first_name, consumer_last_name, consumer_phone, consumer_ema
 		AND ($3::text IS NULL OR st.name = $3::text)
 		AND ($4::text IS NULL OR (
 			l.consumer_first_name ILIKE $4::text OR l.consumer_last_name ILIKE $4::text OR l.consumer_phone ILIKE $4::text OR l.consumer_email ILIKE $4::text OR l.address_city ILIKE $4::text
+			OR rac_custom_fields_text(l.custom_fields) ILIKE $4::text
+			OR l.consumer_phone_bidx = $5::text OR l.consumer_email_bidx = $6::text
 		))
-		AND ($5::text IS NULL OR l.consumer_first_name ILIKE $5::text)
-		AND ($6::text IS NULL OR l.consumer_last_name ILIKE $6::text)
-		AND ($7::text IS NULL OR l.consumer_phone ILIKE $7::text)
-		AND ($8::text IS NULL OR l.consumer_email ILIKE $8::text)
-		AND ($9::text IS NULL OR l.consumer_role = $9::text)
-		AND ($10::text IS NULL OR l.address_street ILIKE $10::text)
-		AND ($11::text IS NULL OR l.address_house_number ILIKE $11::text)
-		AND ($12::text IS NULL OR l.address_zip_code ILIKE $12::text)
-		AND ($13::text IS NULL OR l.address_city ILIKE $13::text)
-		AND ($14::uuid IS NULL OR l.assigned_agent_id = $14::uuid)
-		AND ($15::timestamptz IS NULL OR l.created_at >= $15::timestamptz)
-		AND ($16::timestamptz IS NULL OR l.created_at < $16::timestamptz)
+		AND ($7::text IS NULL OR l.consumer_first_name ILIKE $7::text)
+		AND ($8::text IS NULL OR l.consumer_last_name ILIKE $8::text)
+		AND ($9::text IS NULL OR l.consumer_phone ILIKE $9::text OR l.consumer_phone_bidx = $10::text)
+		AND ($11::text IS NULL OR l.consumer_email ILIKE $11::text OR l.consumer_email_bidx = $12::text)
+		AND ($13::text IS NULL OR l.consumer_role = $13::text)
+		AND ($14::text IS NULL OR l.address_street ILIKE $14::text)
+		AND ($15::text IS NULL OR l.address_house_number ILIKE $15::text)
+		AND ($16::text IS NULL OR l.address_zip_code ILIKE $16::text)
+		AND ($17::text IS NULL OR l.address_city ILIKE $17::text)
+		AND ($18::uuid IS NULL OR l.assigned_agent_id = $18::uuid)
+		AND ($19::timestamptz IS NULL OR l.created_at >= $19::timestamptz)
+		AND ($20::timestamptz IS NULL OR l.created_at < $20::timestamptz)
+		AND ($21::jsonb IS NULL OR l.custom_fields @> $21::jsonb)
 ) leads
 ORDER BY
-	CASE WHEN $17::text = 'createdAt' AND $18::text = 'asc' THEN leads.created_at END ASC,
-	CASE WHEN $17::text = 'createdAt' AND $18::text = 'desc' THEN leads.created_at END DESC,
-	CASE WHEN $17::text = 'firstName' AND $18::text = 'asc' THEN leads.consumer_first_name END ASC,
-	CASE WHEN $17::text = 'firstName' AND $18::text = 'desc' THEN leads.consumer_first_name END DESC,
-	CASE WHEN $17::text = 'lastName' AND $18::text = 'asc' THEN leads.consumer_last_name END ASC,
-	CASE WHEN $17::text = 'lastName' AND $18::text = 'desc' THEN leads.consumer_last_name END DESC,
-	CASE WHEN $17::text = 'phone' AND $18::text = 'asc' THEN leads.consumer_phone END ASC,
-	CASE WHEN $17::text = 'phone' AND $18::text = 'desc' THEN leads.consumer_phone END DESC,
-	CASE WHEN $17::text = 'email' AND $18::text = 'asc' THEN leads.consumer_email END ASC,
-	CASE WHEN $17::text = 'email' AND $18::text = 'desc' THEN leads.consumer_email END DESC,
-	CASE WHEN $17::text = 'role' AND $18::text = 'asc' THEN leads.consumer_role END ASC,
-	CASE WHEN $17::text = 'role' AND $18::text = 'desc' THEN leads.consumer_role END DESC,
-	CASE WHEN $17::text = 'street' AND $18::text = 'asc' THEN leads.address_street END ASC,
-	CASE WHEN $17::text = 'street' AND $18::text = 'desc' THEN leads.address_street END DESC,
-	CASE WHEN $17::text = 'houseNumber' AND $18::text = 'asc' THEN leads.address_house_number END ASC,
-	CASE WHEN $17::text = 'houseNumber' AND $18::text = 'desc' THEN leads.address_house_number END DESC,
-	CASE WHEN $17::text = 'zipCode' AND $18::text = 'asc' THEN leads.address_zip_code END ASC,
-	CASE WHEN $17::text = 'zipCode' AND $18::text = 'desc' THEN leads.address_zip_code END DESC,
-	CASE WHEN $17::text = 'city' AND $18::text = 'asc' THEN leads.address_city END ASC,
-	CASE WHEN $17::text = 'city' AND $18::text = 'desc' THEN leads.address_city END DESC,
-	CASE WHEN $17::text = 'assignedAgentId' AND $18::text = 'asc' THEN leads.assigned_agent_id END ASC,
-	CASE WHEN $17::text = 'assignedAgentId' AND $18::text = 'desc' THEN leads.assigned_agent_id END DESC,
+	CASE WHEN $22::text = 'createdAt' AND $23::text = 'asc' THEN leads.created_at END ASC,
+	CASE WHEN $22::text = 'createdAt' AND $23::text = 'desc' THEN leads.created_at END DESC,
+	CASE WHEN $22::text = 'firstName' AND $23::text = 'asc' THEN leads.consumer_first_name END ASC,
+	CASE WHEN $22::text = 'firstName' AND $23::text = 'desc' THEN leads.consumer_first_name END DESC,
+	CASE WHEN $22::text = 'lastName' AND $23::text = 'asc' THEN leads.consumer_last_name END ASC,
+	CASE WHEN $22::text = 'lastName' AND $23::text = 'desc' THEN leads.consumer_last_name END DESC,
+	CASE WHEN $22::text = 'phone' AND $23::text = 'asc' THEN leads.consumer_phone END ASC,
+	CASE WHEN $22::text = 'phone' AND $23::text = 'desc' THEN leads.consumer_phone END DESC,
+	CASE WHEN $22::text = 'email' AND $23::text = 'asc' THEN leads.consumer_email END ASC,
+	CASE WHEN $22::text = 'email' AND $23::text = 'desc' THEN leads.consumer_email END DESC,
+	CASE WHEN $22::text = 'role' AND $23::text = 'asc' THEN leads.consumer_role END ASC,
+	CASE WHEN $22::text = 'role' AND $23::text = 'desc' THEN leads.consumer_role END DESC,
+	CASE WHEN $22::text = 'street' AND $23::text = 'asc' THEN leads.address_street END ASC,
+	CASE WHEN $22::text = 'street' AND $23::text = 'desc' THEN leads.address_street END DESC,
+	CASE WHEN $22::text = 'houseNumber' AND $23::text = 'asc' THEN leads.address_house_number END ASC,
+	CASE WHEN $22::text = 'houseNumber' AND $23::text = 'desc' THEN leads.address_house_number END DESC,
+	CASE WHEN $22::text = 'zipCode' AND $23::text = 'asc' THEN leads.address_zip_code END ASC,
+	CASE WHEN $22::text = 'zipCode' AND $23::text = 'desc' THEN leads.address_zip_code END DESC,
+	CASE WHEN $22::text = 'city' AND $23::text = 'asc' THEN leads.address_city END ASC,
+	CASE WHEN $22::text = 'city' AND $23::text = 'desc' THEN leads.address_city END DESC,
+	CASE WHEN $22::text = 'assignedAgentId' AND $23::text = 'asc' THEN leads.assigned_agent_id END ASC,
+	CASE WHEN $22::text = 'assignedAgentId' AND $23::text = 'desc' THEN leads.assigned_agent_id END DESC,
 	leads.created_at DESC
-LIMIT $20 OFFSET $19
+LIMIT $25 OFFSET $24
 `
 
 type ListLeadsParams struct {
@@ -3930,10 +3882,14 @@ type ListLeadsParams struct {
 	Status          pgtype.Text        `json:"status"`
 	ServiceType     pgtype.Text        `json:"service_type"`
 	Search          pgtype.Text        `json:"search"`
+	SearchPhoneBidx pgtype.Text        `json:"search_phone_bidx"`
+	SearchEmailBidx pgtype.Text        `json:"search_email_bidx"`
 	FirstName       pgtype.Text        `json:"first_name"`
 	LastName        pgtype.Text        `json:"last_name"`
 	Phone           pgtype.Text        `json:"phone"`
+	PhoneBidx       pgtype.Text        `json:"phone_bidx"`
 	Email           pgtype.Text        `json:"email"`
+	EmailBidx       pgtype.Text        `json:"email_bidx"`
 	Role            pgtype.Text        `json:"role"`
 	Street          pgtype.Text        `json:"street"`
 	HouseNumber     pgtype.Text        `json:"house_number"`
@@ -3942,6 +3898,7 @@ type ListLeadsParams struct {
 	AssignedAgentID pgtype.UUID        `json:"assigned_agent_id"`
 	CreatedAtFrom   pgtype.Timestamptz `json:"created_at_from"`
 	CreatedAtTo     pgtype.Timestamptz `json:"created_at_to"`
+	CustomFields    []byte             `json:"custom_fields"`
 	SortBy          string             `json:"sort_by"`
 	SortOrder       string             `json:"sort_order"`
 	OffsetCount     int32              `json:"offset_count"`
@@ -3954,10 +3911,14 @@ func (q *Queries) ListLeads(ctx context.Context, arg ListLeadsParams) ([]RacLead
 		arg.Status,
 		arg.ServiceType,
 		arg.Search,
+		arg.SearchPhoneBidx,
+		arg.SearchEmailBidx,
 		arg.FirstName,
 		arg.LastName,
 		arg.Phone,
+		arg.PhoneBidx,
 		arg.Email,
+		arg.EmailBidx,
 		arg.Role,
 		arg.Street,
 		arg.HouseNumber,
@@ -3966,6 +3927,7 @@ func (q *Queries) ListLeads(ctx context.Context, arg ListLeadsParams) ([]RacLead
 		arg.AssignedAgentID,
 		arg.CreatedAtFrom,
 		arg.CreatedAtTo,
+		arg.CustomFields,
 		arg.SortBy,
 		arg.SortOrder,
 		arg.OffsetCount,
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetAgentReviewPolicy returns which AI actions the organization holds for review.
+func (h *Handler) GetAgentReviewPolicy(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.repo.GetAgentReviewPolicy(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, toAgentReviewPolicyResponse(policy))
+}
+
+// UpdateAgentReviewPolicy replaces the review policy of the organization. It
+// applies to actions requested afterwards; pending reviews keep their SLA.
+func (h *Handler) UpdateAgentReviewPolicy(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateAgentReviewPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	userID := identity.UserID()
+	policy, err := h.repo.UpsertAgentReviewPolicy(c.Request.Context(), repository.AgentReviewPolicy{
+		OrganizationID:         tenantID,
+		Enabled:                req.Enabled,
+		ReviewStageMoves:       req.ReviewStageMoves,
+		ReviewPartnerOffers:    req.ReviewPartnerOffers,
+		ReviewDetailOverwrites: req.ReviewDetailOverwrites,
+		SLAMinutes:             req.SLAMinutes,
+		TimeoutAction:          req.TimeoutAction,
+		UpdatedByID:            &userID,
+	})
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, toAgentReviewPolicyResponse(policy))
+}
+
+// requirePendingApproval writes an error response and returns false unless the
+// approval exists and still waits for a decision.
+func (h *Handler) requirePendingApproval(c *gin.Context, id, tenantID uuid.UUID) bool {
+	approval, err := h.repo.GetAgentApprovalByID(c.Request.Context(), id, tenantID)
+	if httpkit.HandleError(c, err) {
+		return false
+	}
+	if approval.Decision != "pending" {
+		httpkit.HandleError(c, apperr.Conflict("approval is already "+approval.Decision))
+		return false
+	}
+	return true
+}
+
+func toAgentReviewPolicyResponse(p repository.AgentReviewPolicy) transport.AgentReviewPolicyResponse {
+	return transport.AgentReviewPolicyResponse{
+		Enabled:                p.Enabled,
+		ReviewStageMoves:       p.ReviewStageMoves,
+		ReviewPartnerOffers:    p.ReviewPartnerOffers,
+		ReviewDetailOverwrites: p.ReviewDetailOverwrites,
+		SLAMinutes:             p.SLAMinutes,
+		TimeoutAction:          p.TimeoutAction,
+		UpdatedByID:            p.UpdatedByID,
+		UpdatedAt:              p.UpdatedAt,
+	}
+}
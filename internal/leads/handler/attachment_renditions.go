@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+
+	"github.com/google/uuid"
+)
+
+// attachWithRenditions adds the available renditions to attachment responses.
+// Renditions are optional, so a failed lookup leaves the responses unchanged.
+func (h *Handler) attachWithRenditions(ctx context.Context, tenantID uuid.UUID, items []transport.AttachmentResponse) {
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	renditions, err := h.repo.ListAttachmentRenditions(ctx, ids, tenantID)
+	if err != nil {
+		return
+	}
+	for i := range items {
+		for _, r := range renditions[items[i].ID] {
+			items[i].Renditions = append(items[i].Renditions, transport.AttachmentRenditionResponse{
+				Size:        r.Variant,
+				ContentType: r.ContentType,
+				Width:       r.Width,
+				Height:      r.Height,
+				SizeBytes:   r.SizeBytes,
+			})
+		}
+	}
+}
+
+// downloadKey returns the file key to serve for the requested size, falling back
+// to the original while the attachment has no rendition of that size.
+func (h *Handler) downloadKey(ctx context.Context, tenantID uuid.UUID, att repository.Attachment, size string) (string, string, error) {
+	if size == "" || size == transport.DownloadSizeOriginal {
+		return att.FileKey, transport.DownloadSizeOriginal, nil
+	}
+	rendition, err := h.repo.GetAttachmentRendition(ctx, att.ID, tenantID, size)
+	if errors.Is(err, repository.ErrAttachmentRenditionNotFound) {
+		return att.FileKey, transport.DownloadSizeOriginal, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return rendition.FileKey, size, nil
+}
+
+// deleteRenditionObjects removes the rendition objects of an attachment. The
+// rows go with the attachment record; leftover objects are not fatal.
+func (h *Handler) deleteRenditionObjects(ctx context.Context, tenantID, attachmentID uuid.UUID) {
+	renditions, err := h.repo.ListAttachmentRenditions(ctx, []uuid.UUID{attachmentID}, tenantID)
+	if err != nil {
+		return
+	}
+	for _, r := range renditions[attachmentID] {
+		_ = h.storage.DeleteObject(ctx, h.attachmentsBucket, r.FileKey)
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadAttachment streams a multipart file upload straight to MinIO and
+// records it as an attachment, as an alternative to presign plus CreateAttachment.
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	serviceID, ok := httpkit.ParseUUIDParam(c, "serviceId")
+	if !ok {
+		return
+	}
+
+	svc, err := h.repo.GetLeadServiceByID(c.Request.Context(), serviceID, tenantID)
+	if err != nil || svc.LeadID != leadID {
+		httpkit.Error(c, http.StatusNotFound, "lead service not found", nil)
+		return
+	}
+
+	folder := fmt.Sprintf("%s/%s/%s", tenantID.String(), leadID.String(), serviceID.String())
+	upload, err := storage.StreamMultipartUpload(c.Request.Context(), h.storage, c.Request, h.attachmentsBucket, folder, nil)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	h.recordAttachment(c, tenantID, serviceID, identity.UserID(), transport.CreateAttachmentRequest{
+		FileKey:     upload.FileKey,
+		FileName:    upload.FileName,
+		ContentType: upload.ContentType,
+		SizeBytes:   upload.SizeBytes,
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCommunicationConsent returns the channel and purpose consent of a lead
+// with its audit trail.
+func (h *Handler) GetCommunicationConsent(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.mgmt.GetCommunicationConsent(c.Request.Context(), leadID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// UpdateCommunicationConsent records consent the lead gave to an agent.
+func (h *Handler) UpdateCommunicationConsent(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateCommunicationConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	resp, err := h.mgmt.UpdateCommunicationConsent(c.Request.Context(), leadID, req, identity.UserID(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
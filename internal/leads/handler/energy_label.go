@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RefreshEnergyLabel re-fetches the energy label of a lead. A changed label is
+// recorded on the timeline and re-scores the lead.
+func (h *Handler) RefreshEnergyLabel(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.mgmt.RefreshEnergyLabel(c.Request.Context(), leadID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
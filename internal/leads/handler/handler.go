@@ -17,6 +17,7 @@ import (
 	"portal_final_backend/internal/leads/management"
 	"portal_final_backend/internal/adapters/storage"
 	"portal_final_backend/internal/leads/notes"
+	"portal_final_backend/internal/leads/portalauth"
 	"portal_final_backend/internal/leads/repository"
 	"portal_final_backend/internal/leads/transport"
 	"portal_final_backend/internal/notification/sse"
@@ -45,6 +46,7 @@ type Handler struct {
 	staleSuggester  *maintenance.StaleLeadReEngagementService
 	storage         storage.StorageService
 	attachmentsBucket string
+	portalLinks     *portalauth.Service
 }
 
 // HandlerDeps bundles dependencies for Handler construction.
@@ -106,6 +108,7 @@ func (h *Handler) SetStaleSuggester(s *maintenance.StaleLeadReEngagementService)
 }
 
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.Use(attributeStageTransitions)
 	rg.GET("", h.List)
 	rg.POST("", h.Create)
 	rg.GET("/metrics", h.GetMetrics)
@@ -130,24 +133,39 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.POST("/bulk-delete", h.BulkDelete)
 	rg.PATCH("/:id/status", h.UpdateStatus)
 	rg.PUT(":id/assign", h.Assign)
+	rg.POST("/:id/handover", h.HandoverLead)
+	rg.GET("/:id/handovers", h.ListLeadHandovers)
 	rg.POST("/:id/view", h.MarkViewed)
+	rg.GET("/:id/preferred-language", h.GetPreferredLanguage)
+	rg.PUT("/:id/preferred-language", h.UpdatePreferredLanguage)
+	rg.GET("/:id/communication-consent", h.GetCommunicationConsent)
+	rg.PUT("/:id/communication-consent", h.UpdateCommunicationConsent)
 	rg.GET("/:id/notes", h.ListNotes)
 	rg.POST("/:id/notes", h.AddNote)
+	rg.POST("/:id/portal-link", h.SendPortalLink)
+	rg.DELETE("/:id/portal-links", h.RevokePortalLinks)
 	// Service-specific routes
 	rg.POST("/:id/services", h.AddService)
 	rg.PATCH("/:id/services/:serviceId/status", h.UpdateServiceStatus)
 	rg.PATCH("/:id/services/:serviceId/type", h.UpdateServiceType)
 	rg.PATCH("/:id/services/:serviceId/complete", h.CompleteService)
+	rg.GET("/:id/services/:serviceId/transitions", h.GetStageHistory)
+	rg.GET("/:id/projects", h.ListServiceProjects)
+	rg.POST("/:id/projects", h.CreateServiceProject)
+	rg.DELETE("/:id/projects/:projectId", h.DeleteServiceProject)
 	// AI Advisor routes
 	rg.POST("/:id/analyze", h.AnalyzeLead)
 	rg.GET("/:id/analysis", h.GetAnalysis)
 	rg.GET("/:id/analysis/history", h.ListAnalyses)
+	rg.GET("/:id/score/explanation", h.GetScoreExplanation)
+	rg.POST("/:id/energy-label/refresh", h.RefreshEnergyLabel)
 	// Call Logger routes
 	rg.POST("/:id/services/:serviceId/log-call", h.LogCall)
 	// Attachment routes
 	attachments := rg.Group("/:id/services/:serviceId/attachments")
 	attachments.POST("/presign", h.GetPresignedUploadURL)
 	attachments.POST("", h.CreateAttachment)
+	attachments.POST("/upload", h.UploadAttachment)
 	attachments.GET("", h.ListAttachments)
 	attachments.GET("/:attachmentId", h.GetAttachment)
 	attachments.GET("/:attachmentId/download", h.GetDownloadURL)
@@ -156,9 +174,13 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 
 func (h *Handler) RegisterAdminRoutes(rg *gin.RouterGroup) {
 	rg.POST("/:id/transfer", h.Transfer)
+	rg.POST("/agents/:agentId/handover", h.BulkHandoverAgentLeads)
+	rg.GET("/handovers/report", h.GetHandoverReport)
 	rg.GET("/agent-health", h.AgentHealth)
 	rg.GET("/agent-approvals", h.ListAgentApprovals)
 	rg.GET("/agent-approvals/count", h.CountPendingAgentApprovals)
+	rg.GET("/agent-approvals/policy", h.GetAgentReviewPolicy)
+	rg.PUT("/agent-approvals/policy", h.UpdateAgentReviewPolicy)
 	rg.GET("/agent-approvals/:approvalId", h.GetAgentApproval)
 	rg.POST("/agent-approvals/:approvalId/approve", h.ApproveAgentApproval)
 	rg.POST("/agent-approvals/:approvalId/reject", h.RejectAgentApproval)
@@ -457,6 +479,7 @@ func (h *Handler) GetByID(c *gin.Context) {
 		return
 	}
 
+	httpkit.SetRowVersionETag(c, lead.RowVersion)
 	httpkit.OK(c, lead)
 }
 
@@ -574,6 +597,11 @@ func (h *Handler) Update(c *gin.Context) {
 		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
 		return
 	}
+	rowVersion, ok := httpkit.RowVersionPrecondition(c, req.RowVersion)
+	if !ok {
+		return
+	}
+	req.RowVersion = rowVersion
 
 	lead, err := h.mgmt.Update(c.Request.Context(), id, req, identity.UserID(), tenantID, identity.Roles())
 	if httpkit.HandleError(c, err) {
@@ -591,6 +619,7 @@ func (h *Handler) Update(c *gin.Context) {
 	}
 
 	h.publishLeadUpdate(tenantID, &lead.ID, "updated")
+	httpkit.SetRowVersionETag(c, lead.RowVersion)
 	httpkit.OK(c, lead)
 }
 
@@ -900,6 +929,11 @@ func (h *Handler) UpdateServiceStatus(c *gin.Context) {
 		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
 		return
 	}
+	rowVersion, ok := httpkit.RowVersionPrecondition(c, req.RowVersion)
+	if !ok {
+		return
+	}
+	req.RowVersion = rowVersion
 
 	lead, err := h.mgmt.UpdateServiceStatus(c.Request.Context(), leadID, serviceID, req, tenantID)
 	if httpkit.HandleError(c, err) {
@@ -989,6 +1023,45 @@ func (h *Handler) CompleteService(c *gin.Context) {
 	httpkit.OK(c, lead)
 }
 
+// GetStageHistory returns the pipeline stage transitions of a service and the
+// time it spent in each stage.
+func (h *Handler) GetStageHistory(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	serviceID, ok := httpkit.ParseUUIDParam(c, "serviceId")
+	if !ok {
+		return
+	}
+
+	history, err := h.mgmt.GetStageHistory(c.Request.Context(), leadID, serviceID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, history)
+}
+
+// attributeStageTransitions attributes the stage changes made while handling
+// a request to the authenticated user.
+func attributeStageTransitions(c *gin.Context) {
+	identity := httpkit.GetIdentity(c)
+	if identity.IsAuthenticated() {
+		userID := identity.UserID()
+		ctx := repository.WithStageTransitionActor(c.Request.Context(), repository.StageTransitionActor{
+			Type: repository.ActorTypeUser,
+			ID:   &userID,
+			Name: userID.String(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+	}
+	c.Next()
+}
+
 // AnalyzeLead triggers gatekeeper analysis for a lead service
 func (h *Handler) AnalyzeLead(c *gin.Context) {
 	identity := httpkit.MustGetIdentity(c)
@@ -1641,6 +1714,10 @@ func (h *Handler) ApproveAgentApproval(c *gin.Context) {
 		req = transport.AgentApprovalDecisionRequest{}
 	}
 
+	if !h.requirePendingApproval(c, id, tenantID) {
+		return
+	}
+
 	err = h.repo.UpdateAgentApprovalDecision(c.Request.Context(), repository.UpdateAgentApprovalDecisionParams{
 		ID:        id,
 		TenantID:  tenantID,
@@ -1677,6 +1754,10 @@ func (h *Handler) RejectAgentApproval(c *gin.Context) {
 		req = transport.AgentApprovalDecisionRequest{}
 	}
 
+	if !h.requirePendingApproval(c, id, tenantID) {
+		return
+	}
+
 	err = h.repo.UpdateAgentApprovalDecision(c.Request.Context(), repository.UpdateAgentApprovalDecisionParams{
 		ID:        id,
 		TenantID:  tenantID,
@@ -1773,7 +1854,7 @@ func (h *Handler) AddNote(c *gin.Context) {
 		ActorName:      created.AuthorEmail,
 		EventType:      repository.EventTypeNote,
 		Title:          repository.EventTitleNoteAdded,
-		Summary:        toSummaryPointer(created.Body, repository.TimelineSummaryMaxLen),
+		Summary:        toSummaryPointer(created.BodyText, repository.TimelineSummaryMaxLen),
 		Metadata: repository.NoteMetadata{
 			NoteID:   created.ID,
 			NoteType: created.Type,
@@ -1900,7 +1981,11 @@ func (h *Handler) CreateAttachment(c *gin.Context) {
 		return
 	}
 
-	uploaderID := identity.UserID()
+	h.recordAttachment(c, tenantID, serviceID, identity.UserID(), req)
+}
+
+// recordAttachment stores the attachment record of an uploaded file and announces it.
+func (h *Handler) recordAttachment(c *gin.Context, tenantID, serviceID, uploaderID uuid.UUID, req transport.CreateAttachmentRequest) {
 	att, err := h.repo.CreateAttachment(c.Request.Context(), repository.CreateAttachmentParams{
 		LeadServiceID:  serviceID,
 		OrganizationID: tenantID,
@@ -1961,6 +2046,7 @@ func (h *Handler) ListAttachments(c *gin.Context) {
 	for i, att := range attachments {
 		items[i] = toAttachmentResponse(att, nil)
 	}
+	h.attachWithRenditions(c.Request.Context(), tenantID, items)
 
 	httpkit.OK(c, transport.AttachmentListResponse{Items: items})
 }
@@ -1987,10 +2073,13 @@ func (h *Handler) GetAttachment(c *gin.Context) {
 		return
 	}
 
-	httpkit.OK(c, toAttachmentResponse(att, nil))
+	items := []transport.AttachmentResponse{toAttachmentResponse(att, nil)}
+	h.attachWithRenditions(c.Request.Context(), tenantID, items)
+	httpkit.OK(c, items[0])
 }
 
-// GetDownloadURL generates a presigned URL for downloading a file.
+// GetDownloadURL generates a presigned URL for downloading a file. The size query
+// parameter (original, web or thumbnail) selects an image rendition.
 func (h *Handler) GetDownloadURL(c *gin.Context) {
 	identity := httpkit.MustGetIdentity(c)
 	if identity == nil {
@@ -2007,12 +2096,28 @@ func (h *Handler) GetDownloadURL(c *gin.Context) {
 		return
 	}
 
+	var req transport.DownloadURLRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
 	att, err := h.repo.GetAttachmentByID(c.Request.Context(), attachmentID, tenantID)
 	if httpkit.HandleError(c, err) {
 		return
 	}
 
-	presigned, err := h.storage.GenerateDownloadURL(c.Request.Context(), h.attachmentsBucket, att.FileKey)
+	fileKey, size, err := h.downloadKey(c.Request.Context(), tenantID, att, req.Size)
+	if err != nil {
+		httpkit.Error(c, http.StatusInternalServerError, "failed to generate download URL", nil)
+		return
+	}
+
+	presigned, err := h.storage.GenerateDownloadURL(c.Request.Context(), h.attachmentsBucket, fileKey)
 	if err != nil {
 		httpkit.Error(c, http.StatusInternalServerError, "failed to generate download URL", nil)
 		return
@@ -2021,6 +2126,7 @@ func (h *Handler) GetDownloadURL(c *gin.Context) {
 	httpkit.OK(c, transport.PresignedDownloadResponse{
 		DownloadURL: presigned.URL,
 		ExpiresAt:   presigned.ExpiresAt.Unix(),
+		Size:        size,
 	})
 }
 
@@ -2048,6 +2154,7 @@ func (h *Handler) DeleteAttachment(c *gin.Context) {
 	}
 
 	// Delete from MinIO
+	h.deleteRenditionObjects(c.Request.Context(), tenantID, attachmentID)
 	if err := h.storage.DeleteObject(c.Request.Context(), h.attachmentsBucket, att.FileKey); err != nil {
 		httpkit.Error(c, http.StatusInternalServerError, "failed to delete file from storage", nil)
 		return
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandoverLead transfers a lead to another agent with a mandatory handover note.
+func (h *Handler) HandoverLead(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req transport.HandoverLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.mgmt.HandoverLead(c.Request.Context(), leadID, req, identity.UserID(), tenantID, identity.Roles())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	h.publishLeadUpdate(tenantID, &leadID, "assigned")
+	httpkit.OK(c, resp)
+}
+
+// ListLeadHandovers returns the ownership history of a lead.
+func (h *Handler) ListLeadHandovers(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.mgmt.ListLeadHandovers(c.Request.Context(), leadID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// BulkHandoverAgentLeads transfers every lead of an agent to another agent,
+// for example when the agent leaves the organization.
+func (h *Handler) BulkHandoverAgentLeads(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	agentID, ok := httpkit.ParseUUIDParam(c, "agentId")
+	if !ok {
+		return
+	}
+
+	var req transport.BulkHandoverLeadsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.mgmt.BulkHandoverAgentLeads(c.Request.Context(), agentID, req, identity.UserID(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	if resp.TransferredCount > 0 {
+		h.publishLeadUpdate(tenantID, nil, "assigned")
+	}
+	httpkit.OK(c, resp)
+}
+
+// GetHandoverReport counts lead transfers per agent.
+func (h *Handler) GetHandoverReport(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.LeadHandoverReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	from, to, errMsg := parseDateRange(req.From, req.To)
+	if errMsg != "" {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, errMsg)
+		return
+	}
+	if to != nil {
+		endExclusive := to.AddDate(0, 0, 1)
+		to = &endExclusive
+	}
+
+	resp, err := h.mgmt.GetHandoverReport(c.Request.Context(), from, to, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPreferredLanguage returns the language notifications to the lead are
+// sent in; null means the organization default.
+func (h *Handler) GetPreferredLanguage(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.mgmt.GetPreferredLanguage(c.Request.Context(), leadID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// UpdatePreferredLanguage sets or clears the notification language of a lead.
+func (h *Handler) UpdatePreferredLanguage(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req transport.UpdatePreferredLanguageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.mgmt.SetPreferredLanguage(c.Request.Context(), leadID, req.Language, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
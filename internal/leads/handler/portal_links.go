@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/leads/portalauth"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetPortalLinkService injects the lead portal magic link service.
+func (h *Handler) SetPortalLinkService(svc *portalauth.Service) {
+	h.portalLinks = svc
+}
+
+// SendPortalLink issues a magic link to the lead portal and sends it to the
+// lead over the requested channel.
+func (h *Handler) SendPortalLink(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if h.portalLinks == nil {
+		httpkit.Error(c, http.StatusServiceUnavailable, "portal links are not configured", nil)
+		return
+	}
+
+	var req transport.SendPortalLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	issued, err := h.portalLinks.Issue(c.Request.Context(), leadID, tenantID, req.Channel)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, transport.PortalLinkResponse{
+		Channel:   issued.Link.Channel,
+		URL:       issued.URL,
+		ExpiresAt: issued.Link.ExpiresAt,
+	})
+}
+
+// RevokePortalLinks revokes every magic link and the legacy public token of a
+// lead.
+func (h *Handler) RevokePortalLinks(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if h.portalLinks == nil {
+		httpkit.Error(c, http.StatusServiceUnavailable, "portal links are not configured", nil)
+		return
+	}
+
+	revoked, err := h.portalLinks.Revoke(c.Request.Context(), leadID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, transport.RevokePortalLinksResponse{Revoked: revoked})
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"portal_final_backend/internal/leads/management"
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+var consentLabels = map[string]string{
+	repository.ConsentEmail:         "E-mail",
+	repository.ConsentWhatsApp:      "WhatsApp",
+	repository.ConsentPhone:         "Telefoon",
+	repository.ConsentTransactional: "Berichten over de opdracht",
+	repository.ConsentMarketing:     "Aanbiedingen en nieuws",
+}
+
+// GetCommunicationPreferences returns the preference center of the lead: the
+// channels it may be contacted over and what for.
+func (h *PublicHandler) GetCommunicationPreferences(c *gin.Context) {
+	lead, err := h.leadByToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
+		return
+	}
+
+	consent, err := h.repo.GetCommunicationConsent(c.Request.Context(), lead.ID, lead.OrganizationID)
+	if err != nil {
+		httpkit.Error(c, http.StatusInternalServerError, "Failed to load communication preferences", nil)
+		return
+	}
+	httpkit.OK(c, management.ToCommunicationConsentResponse(consent))
+}
+
+// UpdateCommunicationPreferences grants or withdraws consents from the
+// preference center. Every change is audited with the address and browser
+// it was made from.
+func (h *PublicHandler) UpdateCommunicationPreferences(c *gin.Context) {
+	var req transport.UpdateCommunicationConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, publicMsgInvalidInput, nil)
+		return
+	}
+
+	lead, err := h.leadByToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
+		return
+	}
+
+	consent, changes, err := h.repo.UpdateCommunicationConsent(c.Request.Context(), lead.ID, lead.OrganizationID, management.ConsentPatch(req), repository.ConsentChangeOrigin{
+		Source:    repository.ConsentSourcePreferenceCenter,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		httpkit.Error(c, http.StatusInternalServerError, "Failed to save communication preferences", nil)
+		return
+	}
+
+	management.PublishChannelConsentChanges(c.Request.Context(), h.eventBus, lead.ID, lead.OrganizationID, changes)
+	if len(changes) > 0 {
+		summary := consentChangeSummary(changes)
+		_, _ = h.repo.CreateTimelineEvent(c.Request.Context(), repository.CreateTimelineEventParams{
+			LeadID:         lead.ID,
+			OrganizationID: lead.OrganizationID,
+			ActorType:      repository.ActorTypeLead,
+			ActorName:      repository.ActorNameKlant,
+			EventType:      repository.EventTypeConsentChanged,
+			Title:          repository.EventTitleConsentChanged,
+			Summary:        &summary,
+		})
+	}
+
+	httpkit.OK(c, management.ToCommunicationConsentResponse(consent))
+}
+
+func consentChangeSummary(changes []repository.ConsentChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		state := "uit"
+		if change.Granted {
+			state = "aan"
+		}
+		parts = append(parts, consentLabels[change.Consent]+": "+state)
+	}
+	return "Klant heeft communicatievoorkeuren gewijzigd (" + strings.Join(parts, ", ") + ")"
+}
+
+// RegisterMarketingConfirmationRoutes registers the double opt-in link leads
+// confirm their marketing consent with.
+func (h *PublicHandler) RegisterMarketingConfirmationRoutes(rg *gin.RouterGroup) {
+	rg.POST("/:token", h.ConfirmMarketing)
+}
+
+// ConfirmMarketing confirms the marketing consent of the lead the
+// confirmation link was sent to.
+func (h *PublicHandler) ConfirmMarketing(c *gin.Context) {
+	confirmation, err := h.repo.ConfirmMarketingByToken(c.Request.Context(), c.Param("token"), repository.ConsentChangeOrigin{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			httpkit.Error(c, http.StatusNotFound, "Link expired or invalid", nil)
+			return
+		}
+		httpkit.Error(c, http.StatusInternalServerError, "Failed to confirm marketing consent", nil)
+		return
+	}
+
+	summary := "Klant heeft aanmelding voor aanbiedingen en nieuws bevestigd via de link"
+	_, _ = h.repo.CreateTimelineEvent(c.Request.Context(), repository.CreateTimelineEventParams{
+		LeadID:         confirmation.LeadID,
+		OrganizationID: confirmation.OrganizationID,
+		ActorType:      repository.ActorTypeLead,
+		ActorName:      repository.ActorNameKlant,
+		EventType:      repository.EventTypeMarketingConfirmed,
+		Title:          repository.EventTitleMarketingConfirmed,
+		Summary:        &summary,
+	})
+
+	httpkit.OK(c, gin.H{"status": "confirmed"})
+}
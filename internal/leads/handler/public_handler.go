@@ -10,6 +10,7 @@ import (
 
 	"portal_final_backend/internal/adapters/storage"
 	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/leads/portalauth"
 	"portal_final_backend/internal/leads/ports"
 	"portal_final_backend/internal/leads/repository"
 	"portal_final_backend/internal/leads/transport"
@@ -34,6 +35,8 @@ type PublicHandler struct {
 	apptViewer       ports.AppointmentPublicViewer
 	slotViewer       ports.AppointmentSlotProvider
 	orgViewer        ports.OrganizationPublicViewer
+	portalLinks      *portalauth.Service
+	tokenLocator     httpkit.PublicTokenResolver
 	publicAPIBaseURL string
 }
 
@@ -61,6 +64,30 @@ func (h *PublicHandler) SetPublicOrgViewer(orgViewer ports.OrganizationPublicVie
 	h.orgViewer = orgViewer
 }
 
+// SetPortalLinkService injects the magic link service so portal routes
+// accept magic link tokens next to legacy public tokens.
+func (h *PublicHandler) SetPortalLinkService(svc *portalauth.Service) {
+	h.portalLinks = svc
+}
+
+// SetTokenLocator sets the lookup of the organization of legacy public tokens,
+// in its dedicated or the shared data schema.
+func (h *PublicHandler) SetTokenLocator(locator httpkit.PublicTokenResolver) {
+	h.tokenLocator = locator
+}
+
+// TokenOrganization returns the organization of a portal token. Magic links
+// name their organization.
+func (h *PublicHandler) TokenOrganization(ctx context.Context, token string) (uuid.UUID, bool, error) {
+	if h.portalLinks != nil && portalauth.IsMagicLinkToken(token) {
+		return h.portalLinks.Organization(ctx, token)
+	}
+	if h.tokenLocator == nil {
+		return uuid.Nil, false, nil
+	}
+	return h.tokenLocator(ctx, token)
+}
+
 // SetPublicAPIBaseURL sets the public API base URL used to build absolute download links.
 func (h *PublicHandler) SetPublicAPIBaseURL(url string) {
 	h.publicAPIBaseURL = url
@@ -68,11 +95,14 @@ func (h *PublicHandler) SetPublicAPIBaseURL(url string) {
 
 // RegisterRoutes registers public lead portal routes under /public/leads.
 func (h *PublicHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.Use(httpkit.PublicTokenTenant(h.TokenOrganization))
 	rg.GET("/:token", h.GetTrackAndTrace)
 	if h.sse != nil {
 		rg.GET(":token/events", h.sse.PublicLeadHandler(h.resolveLeadID))
 	}
 	rg.POST("/:token/preferences", h.UpdatePreferences)
+	rg.GET("/:token/communication", h.GetCommunicationPreferences)
+	rg.PUT("/:token/communication", h.UpdateCommunicationPreferences)
 	rg.POST("/:token/info", h.AddCustomerInfo)
 	rg.GET("/:token/availability/slots", h.GetAvailabilitySlots)
 	rg.POST("/:token/appointments/request", h.RequestAppointment)
@@ -81,9 +111,31 @@ func (h *PublicHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.DELETE("/:token/attachments/:attachmentId", h.DeleteAttachment)
 }
 
+// leadByToken resolves the lead a portal token grants access to. Magic link
+// tokens are verified and refreshed; other tokens are legacy public tokens.
+func (h *PublicHandler) leadByToken(ctx context.Context, token string) (repository.Lead, error) {
+	if h.portalLinks == nil || !portalauth.IsMagicLinkToken(token) {
+		return h.repo.GetByPublicToken(ctx, token)
+	}
+	link, err := h.portalLinks.Resolve(ctx, token)
+	if err != nil {
+		return repository.Lead{}, err
+	}
+	return h.repo.GetByID(ctx, link.LeadID, link.OrganizationID)
+}
+
 func (h *PublicHandler) resolveLeadID(token string) (uuid.UUID, error) {
 	ctx := context.Background()
-	lead, err := h.repo.GetByPublicToken(ctx, token)
+	organizationID, ok, err := h.TokenOrganization(ctx, token)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	if ok {
+		if ctx, err = httpkit.BindTenant(ctx, organizationID); err != nil {
+			return uuid.UUID{}, err
+		}
+	}
+	lead, err := h.leadByToken(ctx, token)
 	if err != nil {
 		return uuid.UUID{}, err
 	}
@@ -95,7 +147,7 @@ func (h *PublicHandler) resolveLeadID(token string) (uuid.UUID, error) {
 // GetTrackAndTrace returns the public portal data for a lead based on a token.
 func (h *PublicHandler) GetTrackAndTrace(c *gin.Context) {
 	token := c.Param("token")
-	lead, err := h.repo.GetByPublicToken(c.Request.Context(), token)
+	lead, err := h.leadByToken(c.Request.Context(), token)
 	if err != nil {
 		httpkit.Error(c, http.StatusNotFound, "Link expired or invalid", nil)
 		return
@@ -129,11 +181,15 @@ func (h *PublicHandler) GetTrackAndTrace(c *gin.Context) {
 	attachmentItems := buildAttachmentItems(c.Request.Context(), h.storage, h.bucket, attachments)
 
 	orgPhone := ""
+	var branding *ports.PublicBranding
 	if h.orgViewer != nil {
 		phone, err := h.orgViewer.GetPublicPhone(c.Request.Context(), lead.OrganizationID)
 		if err == nil {
 			orgPhone = phone
 		}
+		if b, err := h.orgViewer.GetPublicBranding(c.Request.Context(), lead.OrganizationID); err == nil && (b.PrimaryColor != nil || b.AccentColor != nil) {
+			branding = &b
+		}
 	}
 
 	response := gin.H{
@@ -151,6 +207,7 @@ func (h *PublicHandler) GetTrackAndTrace(c *gin.Context) {
 		"appointmentRequest": pendingAppt,
 		"appointments":       appointments,
 		"organizationPhone":  orgPhone,
+		"branding":           branding,
 		"slotsAvailable":     slotsAvailable,
 		"quote": gin.H{
 			"available":    quote != nil,
@@ -177,7 +234,7 @@ func (h *PublicHandler) UpdatePreferences(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.repo.GetByPublicToken(c.Request.Context(), token)
+	lead, err := h.leadByToken(c.Request.Context(), token)
 	if err != nil {
 		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
 		return
@@ -243,7 +300,7 @@ func (h *PublicHandler) AddCustomerInfo(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.repo.GetByPublicToken(c.Request.Context(), token)
+	lead, err := h.leadByToken(c.Request.Context(), token)
 	if err != nil {
 		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
 		return
@@ -297,7 +354,7 @@ func (h *PublicHandler) GetAvailabilitySlots(c *gin.Context) {
 		req.SlotDuration = 60
 	}
 
-	lead, err := h.repo.GetByPublicToken(c.Request.Context(), token)
+	lead, err := h.leadByToken(c.Request.Context(), token)
 	if err != nil {
 		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
 		return
@@ -330,7 +387,7 @@ func (h *PublicHandler) RequestAppointment(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.repo.GetByPublicToken(c.Request.Context(), token)
+	lead, err := h.leadByToken(c.Request.Context(), token)
 	if err != nil {
 		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
 		return
@@ -395,7 +452,7 @@ func (h *PublicHandler) PresignUpload(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.repo.GetByPublicToken(c.Request.Context(), token)
+	lead, err := h.leadByToken(c.Request.Context(), token)
 	if err != nil {
 		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
 		return
@@ -443,7 +500,7 @@ func (h *PublicHandler) ConfirmUpload(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.repo.GetByPublicToken(c.Request.Context(), token)
+	lead, err := h.leadByToken(c.Request.Context(), token)
 	if err != nil {
 		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
 		return
@@ -513,7 +570,7 @@ func (h *PublicHandler) DeleteAttachment(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.repo.GetByPublicToken(c.Request.Context(), token)
+	lead, err := h.leadByToken(c.Request.Context(), token)
 	if err != nil {
 		httpkit.Error(c, http.StatusNotFound, publicMsgLeadNotFound, nil)
 		return
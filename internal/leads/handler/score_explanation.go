@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetScoreExplanation returns the factor breakdown behind a lead's current score.
+func (h *Handler) GetScoreExplanation(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.mgmt.GetScoreExplanation(c.Request.Context(), leadID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
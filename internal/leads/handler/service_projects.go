@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateServiceProject groups services of a lead into one project with a
+// dependency order.
+func (h *Handler) CreateServiceProject(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req transport.CreateServiceProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.mgmt.CreateServiceProject(c.Request.Context(), leadID, req, identity.UserID(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	h.publishLeadUpdate(tenantID, &leadID, "service_project_created")
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
+
+// ListServiceProjects returns the service projects of a lead.
+func (h *Handler) ListServiceProjects(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.mgmt.ListServiceProjects(c.Request.Context(), leadID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// DeleteServiceProject ungroups the services of a project.
+func (h *Handler) DeleteServiceProject(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	leadID, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	projectID, ok := httpkit.ParseUUIDParam(c, "projectId")
+	if !ok {
+		return
+	}
+
+	if err := h.mgmt.DeleteServiceProject(c.Request.Context(), leadID, projectID, tenantID); httpkit.HandleError(c, err) {
+		return
+	}
+
+	h.publishLeadUpdate(tenantID, &leadID, "service_project_deleted")
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,164 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"portal_final_backend/internal/adapters/storage"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/imaging"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// renditionMaxSourceBytes skips originals too large to decode in memory.
+	renditionMaxSourceBytes = 64 << 20
+	renditionMaxAttempts    = 3
+	renditionBatchSize      = 25
+)
+
+// renditionSpec is the bounding box and JPEG quality of a rendition variant.
+type renditionSpec struct {
+	variant   string
+	maxWidth  int
+	maxHeight int
+	quality   int
+}
+
+var renditionSpecs = []renditionSpec{
+	{variant: leadsrepo.RenditionWeb, maxWidth: 1920, maxHeight: 1920, quality: 82},
+	{variant: leadsrepo.RenditionThumbnail, maxWidth: 320, maxHeight: 320, quality: 75},
+}
+
+type attachmentRenditionStore interface {
+	ListAttachmentsPendingRenditions(ctx context.Context, maxAttempts int, limit int) ([]leadsrepo.Attachment, error)
+	SaveAttachmentRendition(ctx context.Context, rendition leadsrepo.AttachmentRendition) (string, error)
+	MarkAttachmentImageProcessing(ctx context.Context, attachmentID uuid.UUID, status string, lastError *string) error
+}
+
+// RenditionRunResult summarizes a rendition sweep.
+type RenditionRunResult struct {
+	Processed int
+	Skipped   int
+	Failed    int
+}
+
+// AttachmentRenditionGenerator renders web-optimized and thumbnail JPEG copies of
+// image attachments next to the original in the attachments bucket.
+type AttachmentRenditionGenerator struct {
+	repo    attachmentRenditionStore
+	storage storage.StorageService
+	bucket  string
+	log     *logger.Logger
+}
+
+func NewAttachmentRenditionGenerator(repo attachmentRenditionStore, storageSvc storage.StorageService, bucket string, log *logger.Logger) *AttachmentRenditionGenerator {
+	return &AttachmentRenditionGenerator{repo: repo, storage: storageSvc, bucket: bucket, log: log}
+}
+
+// Run renders a batch of unprocessed image attachments. Images that cannot be
+// decoded are skipped for good; other failures are retried by later runs.
+func (g *AttachmentRenditionGenerator) Run(ctx context.Context) (RenditionRunResult, error) {
+	var result RenditionRunResult
+	pending, err := g.repo.ListAttachmentsPendingRenditions(ctx, renditionMaxAttempts, renditionBatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	for _, att := range pending {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		status := leadsrepo.ImageProcessingProcessed
+		var lastError *string
+		if err := g.render(ctx, att); err != nil {
+			msg := err.Error()
+			lastError = &msg
+			status = leadsrepo.ImageProcessingFailed
+			if errors.Is(err, imaging.ErrUnsupported) || errors.Is(err, imaging.ErrTooLarge) {
+				status = leadsrepo.ImageProcessingSkipped
+			} else {
+				g.log.Warn("attachment renditions: failed to render", "attachmentId", att.ID, "error", err)
+			}
+		}
+
+		if err := g.repo.MarkAttachmentImageProcessing(ctx, att.ID, status, lastError); err != nil {
+			g.log.Warn("attachment renditions: failed to record status", "attachmentId", att.ID, "error", err)
+		}
+		switch status {
+		case leadsrepo.ImageProcessingProcessed:
+			result.Processed++
+		case leadsrepo.ImageProcessingSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+func (g *AttachmentRenditionGenerator) render(ctx context.Context, att leadsrepo.Attachment) error {
+	if att.SizeBytes != nil && *att.SizeBytes > renditionMaxSourceBytes {
+		return imaging.ErrTooLarge
+	}
+
+	reader, err := g.storage.DownloadFile(ctx, g.bucket, att.FileKey)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, renditionMaxSourceBytes+1))
+	_ = reader.Close()
+	if err != nil {
+		return fmt.Errorf("read original: %w", err)
+	}
+	if len(data) > renditionMaxSourceBytes {
+		return imaging.ErrTooLarge
+	}
+
+	src, err := imaging.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	folder := path.Join(path.Dir(att.FileKey), "renditions")
+	for _, spec := range renditionSpecs {
+		img := src.Fit(spec.maxWidth, spec.maxHeight)
+		encoded, err := imaging.EncodeJPEG(img, spec.quality)
+		if err != nil {
+			return err
+		}
+
+		fileName := fmt.Sprintf("%s_%s.jpg", att.ID, spec.variant)
+		fileKey, err := g.storage.UploadFile(ctx, g.bucket, folder, fileName, "image/jpeg", bytes.NewReader(encoded), int64(len(encoded)))
+		if err != nil {
+			return err
+		}
+		replaced, err := g.repo.SaveAttachmentRendition(ctx, leadsrepo.AttachmentRendition{
+			AttachmentID:   att.ID,
+			Variant:        spec.variant,
+			OrganizationID: att.OrganizationID,
+			FileKey:        fileKey,
+			ContentType:    "image/jpeg",
+			Width:          img.Bounds().Dx(),
+			Height:         img.Bounds().Dy(),
+			SizeBytes:      int64(len(encoded)),
+		})
+		if err != nil {
+			_ = g.storage.DeleteObject(ctx, g.bucket, fileKey)
+			return err
+		}
+		if replaced != "" {
+			if err := g.storage.DeleteObject(ctx, g.bucket, replaced); err != nil {
+				g.log.Warn("attachment renditions: failed to delete replaced rendition", "key", replaced, "error", err)
+			}
+		}
+	}
+	return nil
+}
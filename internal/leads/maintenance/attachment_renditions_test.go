@@ -0,0 +1,96 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"io"
+	"strings"
+	"testing"
+
+	"portal_final_backend/internal/adapters/storage"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+type fakeRenditionStore struct {
+	pending    []leadsrepo.Attachment
+	renditions []leadsrepo.AttachmentRendition
+	statuses   map[uuid.UUID]string
+}
+
+func (f *fakeRenditionStore) ListAttachmentsPendingRenditions(context.Context, int, int) ([]leadsrepo.Attachment, error) {
+	return f.pending, nil
+}
+
+func (f *fakeRenditionStore) SaveAttachmentRendition(_ context.Context, r leadsrepo.AttachmentRendition) (string, error) {
+	f.renditions = append(f.renditions, r)
+	return "", nil
+}
+
+func (f *fakeRenditionStore) MarkAttachmentImageProcessing(_ context.Context, attachmentID uuid.UUID, status string, _ *string) error {
+	f.statuses[attachmentID] = status
+	return nil
+}
+
+type fakeObjectStorage struct {
+	storage.StorageService
+	objects map[string][]byte
+}
+
+func (f *fakeObjectStorage) DownloadFile(_ context.Context, _, fileKey string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(f.objects[fileKey])), nil
+}
+
+func (f *fakeObjectStorage) UploadFile(_ context.Context, _, folder, fileName, _ string, reader io.Reader, _ int64) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	key := folder + "/" + fileName
+	f.objects[key] = data
+	return key, nil
+}
+
+func TestAttachmentRenditionGeneratorRendersPhotos(t *testing.T) {
+	var photo bytes.Buffer
+	if err := jpeg.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 4000, 3000)), nil); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	photoAtt := leadsrepo.Attachment{ID: uuid.New(), OrganizationID: uuid.New(), FileKey: "org/lead/service/photo.jpg"}
+	brokenAtt := leadsrepo.Attachment{ID: uuid.New(), OrganizationID: uuid.New(), FileKey: "org/lead/service/broken.jpg"}
+	store := &fakeRenditionStore{pending: []leadsrepo.Attachment{photoAtt, brokenAtt}, statuses: map[uuid.UUID]string{}}
+	objects := &fakeObjectStorage{objects: map[string][]byte{
+		photoAtt.FileKey:  photo.Bytes(),
+		brokenAtt.FileKey: []byte("not an image"),
+	}}
+
+	generator := NewAttachmentRenditionGenerator(store, objects, "attachments", logger.New("development"))
+	result, err := generator.Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.Processed != 1 || result.Skipped != 1 || result.Failed != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if store.statuses[photoAtt.ID] != leadsrepo.ImageProcessingProcessed || store.statuses[brokenAtt.ID] != leadsrepo.ImageProcessingSkipped {
+		t.Fatalf("unexpected statuses %v", store.statuses)
+	}
+
+	want := map[string]image.Point{leadsrepo.RenditionWeb: {1920, 1440}, leadsrepo.RenditionThumbnail: {320, 240}}
+	if len(store.renditions) != len(want) {
+		t.Fatalf("expected %d renditions, got %d", len(want), len(store.renditions))
+	}
+	for _, r := range store.renditions {
+		if got := (image.Point{r.Width, r.Height}); got != want[r.Variant] {
+			t.Fatalf("%s rendition: expected %v, got %v", r.Variant, want[r.Variant], got)
+		}
+		if !strings.HasPrefix(r.FileKey, "org/lead/service/renditions/") || int64(len(objects.objects[r.FileKey])) != r.SizeBytes {
+			t.Fatalf("%s rendition stored at unexpected key %q", r.Variant, r.FileKey)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/maps"
+	"portal_final_backend/internal/notification/sse"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/logger"
+)
+
+// Geocoding states of a lead.
+const (
+	GeocodeStatusResolved = "resolved"
+	GeocodeStatusNotFound = "not_found"
+	GeocodeStatusRetrying = "retrying"
+	GeocodeStatusFailed   = "failed"
+)
+
+const (
+	geocodeRetryPollInterval = time.Minute
+	geocodeRetryBatchSize    = 50
+	// geocodeRetryLease keeps a claimed retry away from other processes while
+	// it runs.
+	geocodeRetryLease  = 10 * time.Minute
+	geocodeTimeout     = 30 * time.Second
+	geocodeLockStripes = 64
+)
+
+// geocodeRetryDelays is the backoff after each failed attempt. A lead is
+// marked failed once they run out.
+var geocodeRetryDelays = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+}
+
+// placeholderAddressValues are stored for leads whose address is unknown.
+var placeholderAddressValues = map[string]struct{}{
+	"unknown": {}, "onbekend": {}, "0000xx": {}, "0000aa": {}, "-": {},
+}
+
+type geocodeTrigger int
+
+const (
+	geocodeTriggerCreated geocodeTrigger = iota
+	geocodeTriggerChanged
+	geocodeTriggerRetry
+)
+
+// AddressGeocoder resolves an address to a position.
+type AddressGeocoder interface {
+	Geocode(ctx context.Context, query maps.GeocodeQuery) (*maps.GeocodeResult, error)
+}
+
+// LeadUpdatePublisher pushes lead updates to the organization's open sessions.
+type LeadUpdatePublisher interface {
+	PublishToOrganization(orgID uuid.UUID, event sse.Event)
+}
+
+type geocodeLead struct {
+	id             uuid.UUID
+	organizationID uuid.UUID
+	query          maps.GeocodeQuery
+	hasCoordinates bool
+}
+
+type geocodeState struct {
+	addressKey string
+	status     string
+	attempts   int
+}
+
+// LeadGeocoder stores coordinates for leads in the background: when a lead is
+// created or its address changes, and again with backoff when the geocoding
+// providers fail. Resolved coordinates are pushed over SSE.
+type LeadGeocoder struct {
+	pool      *pgxpool.Pool
+	geocoder  AddressGeocoder
+	publisher LeadUpdatePublisher
+	log       *logger.Logger
+	now       func() time.Time
+	// locks serialize geocoding per lead, so the events of one change do not
+	// geocode the same address twice.
+	locks [geocodeLockStripes]sync.Mutex
+}
+
+// NewLeadGeocoder creates the geocoder. The publisher may be nil.
+func NewLeadGeocoder(pool *pgxpool.Pool, geocoder AddressGeocoder, publisher LeadUpdatePublisher, log *logger.Logger) *LeadGeocoder {
+	return &LeadGeocoder{
+		pool:      pool,
+		geocoder:  geocoder,
+		publisher: publisher,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// Handle geocodes the lead of a LeadCreated or LeadDataChanged event.
+func (g *LeadGeocoder) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.LeadCreated:
+		return g.geocode(ctx, e.LeadID, e.TenantID, geocodeTriggerCreated)
+	case events.LeadDataChanged:
+		return g.geocode(ctx, e.LeadID, e.TenantID, geocodeTriggerChanged)
+	default:
+		return nil
+	}
+}
+
+// AddressChanged geocodes a lead whose address was just edited, in the
+// background. It covers updates that do not publish LeadDataChanged.
+func (g *LeadGeocoder) AddressChanged(leadID, organizationID uuid.UUID) {
+	go func() {
+		ctx, err := httpkit.BindTenant(context.Background(), organizationID)
+		if err == nil {
+			err = g.geocode(ctx, leadID, organizationID, geocodeTriggerChanged)
+		}
+		if err != nil {
+			g.log.Error("lead geocoding failed", "leadId", leadID, "error", err)
+		}
+	}()
+}
+
+// StartRetryLoop retries failed lookups whose backoff has passed, in every data
+// schema, until ctx is done.
+func (g *LeadGeocoder) StartRetryLoop(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(geocodeRetryPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := db.ForEachSchema(ctx, g.RetryDue); err != nil && ctx.Err() == nil {
+					g.log.Error("lead geocoding retry failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// RetryDue geocodes the leads whose retry is due.
+func (g *LeadGeocoder) RetryDue(ctx context.Context) error {
+	rows, err := g.pool.Query(ctx, `
+		UPDATE RAC_lead_geocoding
+		SET next_attempt_at = $1
+		WHERE lead_id IN (
+			SELECT lead_id FROM RAC_lead_geocoding
+			WHERE status = 'retrying' AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING lead_id, organization_id
+	`, g.now().Add(geocodeRetryLease), g.now(), geocodeRetryBatchSize)
+	if err != nil {
+		return fmt.Errorf("claim geocoding retries: %w", err)
+	}
+	type due struct{ leadID, organizationID uuid.UUID }
+	claimed := make([]due, 0)
+	for rows.Next() {
+		var item due
+		if err := rows.Scan(&item.leadID, &item.organizationID); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan geocoding retry: %w", err)
+		}
+		claimed = append(claimed, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("claim geocoding retries: %w", err)
+	}
+
+	for _, item := range claimed {
+		if err := g.geocode(ctx, item.leadID, item.organizationID, geocodeTriggerRetry); err != nil {
+			g.log.Error("lead geocoding retry failed", "leadId", item.leadID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (g *LeadGeocoder) geocode(ctx context.Context, leadID, organizationID uuid.UUID, trigger geocodeTrigger) error {
+	lock := &g.locks[leadID[len(leadID)-1]%geocodeLockStripes]
+	lock.Lock()
+	defer lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, geocodeTimeout)
+	defer cancel()
+
+	lead, err := g.loadLead(ctx, leadID, organizationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !isGeocodable(lead.query) {
+		return nil
+	}
+	key := lead.query.CacheKey()
+
+	state, err := g.loadState(ctx, leadID)
+	if err != nil {
+		return err
+	}
+	if !shouldGeocode(state, key, lead.hasCoordinates, trigger) {
+		if state == nil {
+			// Coordinates came with the lead; remember which address they
+			// belong to so a later change is picked up.
+			return g.saveState(ctx, lead, key, GeocodeStatusResolved, 0, nil, nil)
+		}
+		return nil
+	}
+
+	attempts := 0
+	if state != nil && state.addressKey == key {
+		attempts = state.attempts
+	}
+	attempts++
+
+	result, err := g.geocoder.Geocode(ctx, lead.query)
+	if err != nil {
+		return g.scheduleRetry(ctx, lead, key, attempts, err)
+	}
+	if result == nil {
+		return g.saveState(ctx, lead, key, GeocodeStatusNotFound, attempts, nil, nil)
+	}
+
+	rowVersion, stored, err := g.storeCoordinates(ctx, lead, result)
+	if err != nil {
+		return g.scheduleRetry(ctx, lead, key, attempts, err)
+	}
+	if !stored {
+		// The address changed while it was looked up; the change geocodes
+		// the new one.
+		return nil
+	}
+	if err := g.saveState(ctx, lead, key, GeocodeStatusResolved, attempts, nil, nil); err != nil {
+		return err
+	}
+
+	if g.publisher != nil {
+		g.publisher.PublishToOrganization(lead.organizationID, sse.Event{
+			Type:    sse.EventLeadUpdated,
+			LeadID:  lead.id,
+			Message: "Lead geocoded",
+			Data: map[string]any{
+				"action":     "geocoded",
+				"latitude":   result.Lat,
+				"longitude":  result.Lon,
+				"rowVersion": rowVersion,
+			},
+		})
+	}
+	return nil
+}
+
+// shouldGeocode decides whether a lead's address needs a lookup. Coordinates
+// that came with a new lead are trusted; an address that already has an
+// answer is only looked up again when it changes, and one that is waiting
+// for a retry only by the retry loop.
+func shouldGeocode(state *geocodeState, key string, hasCoordinates bool, trigger geocodeTrigger) bool {
+	if state == nil {
+		return !(hasCoordinates && trigger == geocodeTriggerCreated)
+	}
+	if state.addressKey != key {
+		return true
+	}
+	return state.status == GeocodeStatusRetrying && trigger == geocodeTriggerRetry
+}
+
+// geocodeRetryDelay returns the backoff after a failed attempt, and false
+// when no attempts are left.
+func geocodeRetryDelay(attempts int) (time.Duration, bool) {
+	if attempts < 1 || attempts > len(geocodeRetryDelays) {
+		return 0, false
+	}
+	return geocodeRetryDelays[attempts-1], true
+}
+
+func isGeocodable(query maps.GeocodeQuery) bool {
+	for _, value := range []string{query.Street, query.City} {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			return false
+		}
+		if _, ok := placeholderAddressValues[value]; ok {
+			return false
+		}
+	}
+	_, placeholderZip := placeholderAddressValues[strings.ToLower(strings.TrimSpace(query.ZipCode))]
+	return !placeholderZip
+}
+
+func (g *LeadGeocoder) scheduleRetry(ctx context.Context, lead geocodeLead, key string, attempts int, cause error) error {
+	message := cause.Error()
+	delay, ok := geocodeRetryDelay(attempts)
+	if !ok {
+		g.log.Warn("lead geocoding gave up", "leadId", lead.id, "attempts", attempts, "error", cause)
+		return g.saveState(ctx, lead, key, GeocodeStatusFailed, attempts, &message, nil)
+	}
+	next := g.now().Add(delay)
+	g.log.Info("lead geocoding failed, retrying", "leadId", lead.id, "attempts", attempts, "retryAt", next, "error", cause)
+	return g.saveState(ctx, lead, key, GeocodeStatusRetrying, attempts, &message, &next)
+}
+
+func (g *LeadGeocoder) loadLead(ctx context.Context, leadID, organizationID uuid.UUID) (geocodeLead, error) {
+	lead := geocodeLead{id: leadID, organizationID: organizationID}
+	var latitude, longitude *float64
+	err := g.pool.QueryRow(ctx, `
+		SELECT address_street, address_house_number, address_zip_code, address_city, latitude, longitude
+		FROM RAC_leads
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+	`, leadID, organizationID).Scan(&lead.query.Street, &lead.query.HouseNumber, &lead.query.ZipCode, &lead.query.City, &latitude, &longitude)
+	if err != nil {
+		return geocodeLead{}, err
+	}
+	lead.hasCoordinates = latitude != nil && longitude != nil
+	return lead, nil
+}
+
+func (g *LeadGeocoder) loadState(ctx context.Context, leadID uuid.UUID) (*geocodeState, error) {
+	var state geocodeState
+	err := g.pool.QueryRow(ctx, `
+		SELECT address_key, status, attempts FROM RAC_lead_geocoding WHERE lead_id = $1
+	`, leadID).Scan(&state.addressKey, &state.status, &state.attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load lead geocoding state: %w", err)
+	}
+	return &state, nil
+}
+
+func (g *LeadGeocoder) saveState(ctx context.Context, lead geocodeLead, key, status string, attempts int, lastError *string, nextAttemptAt *time.Time) error {
+	_, err := g.pool.Exec(ctx, `
+		INSERT INTO RAC_lead_geocoding (lead_id, organization_id, address_key, status, attempts, last_error, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (lead_id) DO UPDATE SET
+			address_key = EXCLUDED.address_key,
+			status = EXCLUDED.status,
+			attempts = EXCLUDED.attempts,
+			last_error = EXCLUDED.last_error,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			updated_at = now()
+	`, lead.id, lead.organizationID, key, status, attempts, lastError, nextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("save lead geocoding state: %w", err)
+	}
+	return nil
+}
+
+// storeCoordinates stores the position on the lead if its address is still
+// the one that was looked up, and returns the new row version.
+func (g *LeadGeocoder) storeCoordinates(ctx context.Context, lead geocodeLead, result *maps.GeocodeResult) (int64, bool, error) {
+	var rowVersion int64
+	err := g.pool.QueryRow(ctx, `
+		UPDATE RAC_leads
+		SET latitude = $3, longitude = $4, updated_at = now()
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+		  AND address_street = $5 AND address_house_number = $6 AND address_zip_code = $7 AND address_city = $8
+		RETURNING row_version
+	`, lead.id, lead.organizationID, result.Lat, result.Lon,
+		lead.query.Street, lead.query.HouseNumber, lead.query.ZipCode, lead.query.City,
+	).Scan(&rowVersion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("store lead coordinates: %w", err)
+	}
+	return rowVersion, true, nil
+}
@@ -0,0 +1,66 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/maps"
+)
+
+func TestShouldGeocode(t *testing.T) {
+	resolved := &geocodeState{addressKey: "a", status: GeocodeStatusResolved}
+	retrying := &geocodeState{addressKey: "a", status: GeocodeStatusRetrying, attempts: 2}
+
+	cases := []struct {
+		name           string
+		state          *geocodeState
+		key            string
+		hasCoordinates bool
+		trigger        geocodeTrigger
+		want           bool
+	}{
+		{"new lead without coordinates", nil, "a", false, geocodeTriggerCreated, true},
+		{"new lead with coordinates", nil, "a", true, geocodeTriggerCreated, false},
+		{"first change of an untracked lead", nil, "a", true, geocodeTriggerChanged, true},
+		{"unchanged resolved address", resolved, "a", true, geocodeTriggerChanged, false},
+		{"changed address", resolved, "b", true, geocodeTriggerChanged, true},
+		{"pending retry on change event", retrying, "a", false, geocodeTriggerChanged, false},
+		{"due retry", retrying, "a", false, geocodeTriggerRetry, true},
+		{"retry of a resolved address", resolved, "a", true, geocodeTriggerRetry, false},
+	}
+	for _, tc := range cases {
+		if got := shouldGeocode(tc.state, tc.key, tc.hasCoordinates, tc.trigger); got != tc.want {
+			t.Errorf("%s: shouldGeocode = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestGeocodeRetryDelay(t *testing.T) {
+	if delay, ok := geocodeRetryDelay(1); !ok || delay != time.Minute {
+		t.Errorf("first retry = %v, %v, want 1m", delay, ok)
+	}
+	if delay, ok := geocodeRetryDelay(len(geocodeRetryDelays)); !ok || delay != 6*time.Hour {
+		t.Errorf("last retry = %v, %v, want 6h", delay, ok)
+	}
+	if _, ok := geocodeRetryDelay(len(geocodeRetryDelays) + 1); ok {
+		t.Error("expected no retry once the delays run out")
+	}
+}
+
+func TestIsGeocodable(t *testing.T) {
+	cases := map[string]struct {
+		query maps.GeocodeQuery
+		want  bool
+	}{
+		"full address":         {maps.GeocodeQuery{Street: "Damrak", HouseNumber: "1", ZipCode: "1012 LG", City: "Amsterdam"}, true},
+		"without postcode":     {maps.GeocodeQuery{Street: "Damrak", City: "Amsterdam"}, true},
+		"missing street":       {maps.GeocodeQuery{ZipCode: "1012 LG", City: "Amsterdam"}, false},
+		"placeholder city":     {maps.GeocodeQuery{Street: "Damrak", City: " Onbekend "}, false},
+		"placeholder postcode": {maps.GeocodeQuery{Street: "Damrak", ZipCode: "0000XX", City: "Amsterdam"}, false},
+	}
+	for name, tc := range cases {
+		if got := isGeocodable(tc.query); got != tc.want {
+			t.Errorf("%s: isGeocodable = %v, want %v", name, got, tc.want)
+		}
+	}
+}
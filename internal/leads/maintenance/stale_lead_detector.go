@@ -66,11 +66,11 @@ candidates AS (
 		s.organization_id,
 		CASE
 			WHEN s.status = 'Needs_Rescheduling'
-				AND s.updated_at < NOW() - INTERVAL '2 days'
+				AND s.updated_at < NOW() - make_interval(days => COALESCE(ss.reschedule_days, 2))
 				THEN 'needs_rescheduling'
 			WHEN s.pipeline_stage = 'Nurturing'
 				AND s.status = 'Attempted_Contact'
-				AND s.updated_at < NOW() - INTERVAL '7 days'
+				AND s.updated_at < NOW() - make_interval(days => COALESCE(ss.nurturing_days, 7))
 				THEN 'stuck_nurturing'
 			WHEN s.pipeline_stage IN ('Estimation', 'Proposal')
 				AND NOT EXISTS (
@@ -79,20 +79,20 @@ candidates AS (
 						AND q.organization_id = s.organization_id
 						AND q.status = 'Sent'
 				)
-				AND s.updated_at < NOW() - INTERVAL '14 days'
+				AND s.updated_at < NOW() - make_interval(days => COALESCE(ss.no_quote_days, 14))
 				THEN 'no_quote_sent'
 			WHEN EXISTS (
 					SELECT 1 FROM RAC_quotes q
 					WHERE q.lead_service_id = s.id
 						AND q.organization_id = s.organization_id
 						AND q.status = 'Draft'
-						AND q.created_at < NOW() - INTERVAL '30 days'
+						AND q.created_at < NOW() - make_interval(days => COALESCE(ss.draft_quote_days, 30))
 				)
 				THEN 'stale_draft'
-			WHEN la.last_activity_at < NOW() - INTERVAL '7 days'
+			WHEN la.last_activity_at < NOW() - make_interval(days => COALESCE(ss.no_activity_days, 7))
 				THEN 'no_activity'
 			WHEN la.last_activity_at IS NULL
-				AND s.created_at < NOW() - INTERVAL '7 days'
+				AND s.created_at < NOW() - make_interval(days => COALESCE(ss.no_activity_days, 7))
 				THEN 'no_activity'
 			ELSE NULL
 		END AS stale_reason,
@@ -108,6 +108,7 @@ candidates AS (
 	JOIN RAC_leads l ON l.id = s.lead_id
 	JOIN RAC_service_types st ON st.id = s.service_type_id AND st.organization_id = s.organization_id
 	LEFT JOIN last_activity la ON la.service_id = s.id
+	LEFT JOIN RAC_organization_stale_lead_settings ss ON ss.organization_id = s.organization_id
 	WHERE s.organization_id = $1
 		AND l.deleted_at IS NULL
 		AND s.pipeline_stage NOT IN ('Completed', 'Lost')
@@ -122,7 +123,8 @@ ORDER BY last_activity_at ASC NULLS FIRST
 LIMIT $2
 `
 
-// ListStaleLeadServices returns stale lead services for an organization.
+// ListStaleLeadServices returns stale lead services for an organization,
+// using its stale lead thresholds or the defaults when it has none.
 func (d *StaleLeadDetector) ListStaleLeadServices(ctx context.Context, organizationID uuid.UUID, limit int) ([]StaleLeadItem, error) {
 	if limit <= 0 {
 		limit = 50
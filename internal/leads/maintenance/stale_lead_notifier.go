@@ -2,26 +2,53 @@ package maintenance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"portal_final_backend/internal/events"
 	"portal_final_backend/internal/notification/inapp"
 	"portal_final_backend/platform/logger"
 )
 
-// StaleLeadNotifier creates in-app notifications for the assigned agents of
-// stale lead services, prompting proactive re-engagement.
+// StaleLeadTaskTitlePrefix starts the title of every follow-up task opened
+// for a stale lead service.
+const StaleLeadTaskTitlePrefix = "Opvolgen: "
+
+// StaleLeadTask is a follow-up task for the owner of a stale lead service.
+type StaleLeadTask struct {
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	LeadServiceID  uuid.UUID
+	AssigneeID     uuid.UUID
+	Title          string
+	Description    string
+	DueAt          time.Time
+}
+
+// StaleLeadTaskCreator opens follow-up tasks for stale lead services. It
+// reports false when the service already has an open follow-up task.
+type StaleLeadTaskCreator interface {
+	CreateStaleLeadTask(ctx context.Context, task StaleLeadTask) (bool, error)
+}
+
+// StaleLeadNotifier nudges the assigned agent of a stale lead service with an
+// in-app notification and a follow-up task, and publishes a LeadStale event.
+// Services without an assigned agent notify all members of the organisation.
 //
-// Notifications are deduplicated: a new notification is only created when no
-// stale-lead notification was created for the same service within the last
-// 48 hours.
+// Nudges are deduplicated: a new nudge is only sent when no stale-lead
+// notification was created for the same service within the last 48 hours.
 type StaleLeadNotifier struct {
-	pool  *pgxpool.Pool
-	notif *inapp.Service
-	log   *logger.Logger
+	pool     *pgxpool.Pool
+	notif    *inapp.Service
+	tasks    StaleLeadTaskCreator
+	eventBus events.Bus
+	log      *logger.Logger
 }
 
 // NewStaleLeadNotifier creates a new StaleLeadNotifier.
@@ -29,6 +56,16 @@ func NewStaleLeadNotifier(pool *pgxpool.Pool, notif *inapp.Service, log *logger.
 	return &StaleLeadNotifier{pool: pool, notif: notif, log: log}
 }
 
+// SetTaskCreator enables follow-up tasks for the owners of stale lead services.
+func (n *StaleLeadNotifier) SetTaskCreator(tasks StaleLeadTaskCreator) {
+	n.tasks = tasks
+}
+
+// SetEventBus enables publishing LeadStale events.
+func (n *StaleLeadNotifier) SetEventBus(bus events.Bus) {
+	n.eventBus = bus
+}
+
 // orgMember holds the minimal user data needed to send a notification.
 type orgMember struct {
 	UserID uuid.UUID
@@ -41,6 +78,49 @@ JOIN RAC_organization_members m ON m.user_id = u.id
 WHERE m.organization_id = $1
 `
 
+// staleLeadContext is the owner of a stale lead service and the stale lead
+// settings of its organization.
+type staleLeadContext struct {
+	AssignedAgentID      *uuid.UUID
+	NoActivityDays       int
+	NurturingDays        int
+	NoQuoteDays          int
+	DraftQuoteDays       int
+	RescheduleDays       int
+	CreateOwnerTasks     bool
+	ConsumerNudgeEnabled bool
+}
+
+// thresholdDays returns after how many days a service is stale for the reason.
+func (c staleLeadContext) thresholdDays(reason StaleReason) int {
+	switch reason {
+	case StaleReasonStuckNurturing:
+		return c.NurturingDays
+	case StaleReasonNoQuoteSent:
+		return c.NoQuoteDays
+	case StaleReasonStaleDraft:
+		return c.DraftQuoteDays
+	case StaleReasonNeedsRescheduling:
+		return c.RescheduleDays
+	default:
+		return c.NoActivityDays
+	}
+}
+
+const staleLeadContextQuery = `
+SELECT l.assigned_agent_id,
+       COALESCE(ss.no_activity_days, 7),
+       COALESCE(ss.nurturing_days, 7),
+       COALESCE(ss.no_quote_days, 14),
+       COALESCE(ss.draft_quote_days, 30),
+       COALESCE(ss.reschedule_days, 2),
+       COALESCE(ss.create_owner_tasks, true),
+       COALESCE(ss.consumer_nudge_enabled, false)
+FROM RAC_leads l
+LEFT JOIN RAC_organization_stale_lead_settings ss ON ss.organization_id = l.organization_id
+WHERE l.id = $1 AND l.organization_id = $2 AND l.deleted_at IS NULL
+`
+
 const cooldownCheckQuery = `
 SELECT COUNT(*)
 FROM RAC_in_app_notifications
@@ -49,8 +129,8 @@ WHERE resource_id = $1
   AND created_at > NOW() - INTERVAL '48 hours'
 `
 
-// Notify creates in-app notifications for all members of the organisation for
-// a stale lead service, unless an identical notification was already sent
+// Notify nudges the owner of a stale lead service, or all members of the
+// organisation when the service has no owner, unless a nudge was already sent
 // within the cooldown window.
 func (n *StaleLeadNotifier) Notify(ctx context.Context, orgID, leadID, serviceID uuid.UUID, staleReason, consumerName, serviceType string) error {
 	if n == nil || n.pool == nil {
@@ -68,21 +148,38 @@ func (n *StaleLeadNotifier) Notify(ctx context.Context, orgID, leadID, serviceID
 		return nil
 	}
 
-	members, err := n.listOrgMembers(ctx, orgID)
+	var sc staleLeadContext
+	err := n.pool.QueryRow(ctx, staleLeadContextQuery, leadID, orgID).Scan(
+		&sc.AssignedAgentID, &sc.NoActivityDays, &sc.NurturingDays, &sc.NoQuoteDays,
+		&sc.DraftQuoteDays, &sc.RescheduleDays, &sc.CreateOwnerTasks, &sc.ConsumerNudgeEnabled,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("stale lead notify list members: %w", err)
+		return fmt.Errorf("stale lead notify load lead: %w", err)
 	}
-	if len(members) == 0 {
-		return nil
+
+	var recipients []uuid.UUID
+	if sc.AssignedAgentID != nil {
+		recipients = []uuid.UUID{*sc.AssignedAgentID}
+	} else {
+		members, err := n.listOrgMembers(ctx, orgID)
+		if err != nil {
+			return fmt.Errorf("stale lead notify list members: %w", err)
+		}
+		for _, m := range members {
+			recipients = append(recipients, m.UserID)
+		}
 	}
 
-	title, content := buildNotificationText(staleReason, consumerName, serviceType)
+	title, content := buildNotificationText(staleReason, consumerName, serviceType, sc.thresholdDays(StaleReason(staleReason)))
 	resourceID := serviceID
 
-	for _, m := range members {
+	for _, userID := range recipients {
 		if sendErr := n.notif.Send(ctx, inapp.SendParams{
 			OrgID:        orgID,
-			UserID:       m.UserID,
+			UserID:       userID,
 			Title:        title,
 			Content:      content,
 			ResourceID:   &resourceID,
@@ -92,7 +189,7 @@ func (n *StaleLeadNotifier) Notify(ctx context.Context, orgID, leadID, serviceID
 			if n.log != nil {
 				n.log.Warn("stale lead notify: failed to send notification",
 					"orgId", orgID,
-					"userId", m.UserID,
+					"userId", userID,
 					"serviceId", serviceID,
 					"error", sendErr,
 				)
@@ -100,9 +197,46 @@ func (n *StaleLeadNotifier) Notify(ctx context.Context, orgID, leadID, serviceID
 		}
 	}
 
+	if sc.AssignedAgentID != nil && sc.CreateOwnerTasks {
+		n.createOwnerTask(ctx, StaleLeadTask{
+			OrganizationID: orgID,
+			LeadID:         leadID,
+			LeadServiceID:  serviceID,
+			AssigneeID:     *sc.AssignedAgentID,
+			Title:          StaleLeadTaskTitlePrefix + title,
+			Description:    content,
+			DueAt:          time.Now().Add(24 * time.Hour),
+		})
+	}
+
+	if n.eventBus != nil {
+		n.eventBus.Publish(ctx, events.LeadStale{
+			BaseEvent:       events.NewBaseEvent(),
+			LeadID:          leadID,
+			LeadServiceID:   serviceID,
+			TenantID:        orgID,
+			StaleReason:     staleReason,
+			AssignedAgentID: sc.AssignedAgentID,
+			ConsumerNudge:   sc.ConsumerNudgeEnabled,
+		})
+	}
+
 	return nil
 }
 
+func (n *StaleLeadNotifier) createOwnerTask(ctx context.Context, task StaleLeadTask) {
+	if n.tasks == nil {
+		return
+	}
+	if _, err := n.tasks.CreateStaleLeadTask(ctx, task); err != nil && n.log != nil {
+		n.log.Warn("stale lead notify: failed to create follow-up task",
+			"orgId", task.OrganizationID,
+			"serviceId", task.LeadServiceID,
+			"error", err,
+		)
+	}
+}
+
 func (n *StaleLeadNotifier) listOrgMembers(ctx context.Context, orgID uuid.UUID) ([]orgMember, error) {
 	rows, err := n.pool.Query(ctx, orgMembersQuery, pgtype.UUID{Bytes: orgID, Valid: true})
 	if err != nil {
@@ -123,7 +257,7 @@ func (n *StaleLeadNotifier) listOrgMembers(ctx context.Context, orgID uuid.UUID)
 	return members, rows.Err()
 }
 
-func buildNotificationText(staleReason, consumerName, serviceType string) (title, content string) {
+func buildNotificationText(staleReason, consumerName, serviceType string, days int) (title, content string) {
 	displayName := consumerName
 	if displayName == "" {
 		displayName = "Lead"
@@ -132,19 +266,19 @@ func buildNotificationText(staleReason, consumerName, serviceType string) (title
 	switch StaleReason(staleReason) {
 	case StaleReasonNoActivity:
 		title = fmt.Sprintf("%s – geen activiteit", displayName)
-		content = fmt.Sprintf("%s (%s) heeft al meer dan 7 dagen geen activiteit gehad. Neem contact op.", displayName, serviceType)
+		content = fmt.Sprintf("%s (%s) heeft al meer dan %d dagen geen activiteit gehad. Neem contact op.", displayName, serviceType, days)
 	case StaleReasonStuckNurturing:
 		title = fmt.Sprintf("%s – vastgelopen in Nurturing", displayName)
-		content = fmt.Sprintf("%s (%s) staat al meer dan 7 dagen op 'Attempted Contact'. Probeer een andere benadering.", displayName, serviceType)
+		content = fmt.Sprintf("%s (%s) staat al meer dan %d dagen op 'Attempted Contact'. Probeer een andere benadering.", displayName, serviceType, days)
 	case StaleReasonNoQuoteSent:
 		title = fmt.Sprintf("%s – nog geen offerte verstuurd", displayName)
-		content = fmt.Sprintf("Voor %s (%s) is al meer dan 14 dagen geen offerte verstuurd. Maak een offerte aan.", displayName, serviceType)
+		content = fmt.Sprintf("Voor %s (%s) is al meer dan %d dagen geen offerte verstuurd. Maak een offerte aan.", displayName, serviceType, days)
 	case StaleReasonStaleDraft:
-		title = fmt.Sprintf("%s – offerte in concept al 30 dagen", displayName)
-		content = fmt.Sprintf("Er staat al meer dan 30 dagen een concept-offerte klaar voor %s (%s). Stuur hem op of verwijder.", displayName, serviceType)
+		title = fmt.Sprintf("%s – offerte in concept al %d dagen", displayName, days)
+		content = fmt.Sprintf("Er staat al meer dan %d dagen een concept-offerte klaar voor %s (%s). Stuur hem op of verwijder.", days, displayName, serviceType)
 	case StaleReasonNeedsRescheduling:
 		title = fmt.Sprintf("%s – afspraak moet worden verzet", displayName)
-		content = fmt.Sprintf("%s (%s) staat al meer dan 2 dagen op 'Needs Rescheduling'. Plan een nieuwe afspraak.", displayName, serviceType)
+		content = fmt.Sprintf("%s (%s) staat al meer dan %d dagen op 'Needs Rescheduling'. Plan een nieuwe afspraak.", displayName, serviceType, days)
 	default:
 		title = fmt.Sprintf("%s – lead vereist aandacht", displayName)
 		content = fmt.Sprintf("%s (%s) heeft aandacht nodig.", displayName, serviceType)
@@ -0,0 +1,39 @@
+package maintenance
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStaleLeadContextThresholdDays(t *testing.T) {
+	sc := staleLeadContext{NoActivityDays: 5, NurturingDays: 6, NoQuoteDays: 10, DraftQuoteDays: 21, RescheduleDays: 3}
+
+	cases := map[StaleReason]int{
+		StaleReasonNoActivity:        5,
+		StaleReasonStuckNurturing:    6,
+		StaleReasonNoQuoteSent:       10,
+		StaleReasonStaleDraft:        21,
+		StaleReasonNeedsRescheduling: 3,
+		StaleReason("unknown"):       5,
+	}
+	for reason, want := range cases {
+		if got := sc.thresholdDays(reason); got != want {
+			t.Errorf("thresholdDays(%q) = %d, want %d", reason, got, want)
+		}
+	}
+}
+
+func TestBuildNotificationTextUsesThreshold(t *testing.T) {
+	title, content := buildNotificationText(string(StaleReasonNoQuoteSent), "Jan de Vries", "Warmtepomp", 10)
+	if !strings.HasPrefix(title, "Jan de Vries") {
+		t.Errorf("title = %q, want it to start with the consumer name", title)
+	}
+	if !strings.Contains(content, "meer dan 10 dagen") {
+		t.Errorf("content = %q, want the configured threshold", content)
+	}
+
+	title, _ = buildNotificationText(string(StaleReasonNoActivity), "", "Warmtepomp", 7)
+	if !strings.HasPrefix(title, "Lead") {
+		t.Errorf("title = %q, want the fallback name", title)
+	}
+}
@@ -0,0 +1,75 @@
+// Package management — BAG address validation for lead intake.
+// Normalizes street/city from postcode + house number and records a validity flag for scoring.
+package management
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/internal/maps"
+
+	"github.com/google/uuid"
+)
+
+// addressValidationTimeout keeps a slow BAG lookup from stalling lead intake.
+const addressValidationTimeout = 3 * time.Second
+
+// intakeAddressCheck is the BAG validation outcome of an intake address.
+// checked is false when the lookup was skipped or the BAG was unreachable.
+type intakeAddressCheck struct {
+	checked bool
+	address *maps.BAGAddress
+}
+
+// validateIntakeAddress looks up the postcode and house number in the BAG and, when the
+// address exists, replaces the submitted address with the registered one.
+func (s *Service) validateIntakeAddress(ctx context.Context, req *transport.CreateLeadRequest) intakeAddressCheck {
+	if s.maps == nil || strings.TrimSpace(req.ZipCode) == "" || strings.TrimSpace(req.HouseNumber) == "" {
+		return intakeAddressCheck{}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, addressValidationTimeout)
+	defer cancel()
+
+	address, err := s.maps.ValidateAddress(lookupCtx, req.ZipCode, req.HouseNumber)
+	if err != nil {
+		return intakeAddressCheck{}
+	}
+	if address != nil {
+		applyBAGAddress(req, address)
+	}
+	return intakeAddressCheck{checked: true, address: address}
+}
+
+func applyBAGAddress(req *transport.CreateLeadRequest, address *maps.BAGAddress) {
+	req.Street = address.Street
+	req.HouseNumber = address.HouseNumber
+	req.ZipCode = address.ZipCode
+	req.City = address.City
+	if req.Latitude == nil && req.Longitude == nil && address.Lat != nil && address.Lon != nil {
+		req.Latitude = address.Lat
+		req.Longitude = address.Lon
+	}
+}
+
+// recordAddressValidation stores the validity flag (best effort - never fails lead creation).
+func (s *Service) recordAddressValidation(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID, check intakeAddressCheck) {
+	if !check.checked {
+		return
+	}
+
+	validation := repository.LeadAddressValidation{
+		LeadID:         leadID,
+		OrganizationID: tenantID,
+		IsValid:        check.address != nil,
+		Source:         "bag",
+	}
+	if check.address != nil {
+		validation.NummeraanduidingID = toPtr(check.address.NummeraanduidingID)
+		validation.AdresseerbaarObjectID = toPtr(check.address.AdresseerbaarObjectID)
+	}
+	_ = s.repo.UpsertLeadAddressValidation(ctx, validation)
+}
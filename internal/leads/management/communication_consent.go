@@ -0,0 +1,129 @@
+package management
+
+import (
+	"context"
+	"errors"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const consentHistoryLimit = 100
+
+// GetCommunicationConsent returns the consent of a lead with its audit trail.
+func (s *Service) GetCommunicationConsent(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) (transport.LeadCommunicationConsentResponse, error) {
+	consent, err := s.repo.GetCommunicationConsent(ctx, leadID, tenantID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.LeadCommunicationConsentResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.LeadCommunicationConsentResponse{}, err
+	}
+	return s.consentWithHistory(ctx, leadID, tenantID, consent)
+}
+
+// UpdateCommunicationConsent records consent the lead gave to an agent, for
+// example on the phone.
+func (s *Service) UpdateCommunicationConsent(ctx context.Context, leadID uuid.UUID, req transport.UpdateCommunicationConsentRequest, actorID uuid.UUID, tenantID uuid.UUID) (transport.LeadCommunicationConsentResponse, error) {
+	consent, changes, err := s.repo.UpdateCommunicationConsent(ctx, leadID, tenantID, ConsentPatch(req), repository.ConsentChangeOrigin{
+		Source:      repository.ConsentSourceAgent,
+		ActorUserID: &actorID,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.LeadCommunicationConsentResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.LeadCommunicationConsentResponse{}, err
+	}
+	PublishChannelConsentChanges(ctx, s.eventBus, leadID, tenantID, changes)
+	return s.consentWithHistory(ctx, leadID, tenantID, consent)
+}
+
+func (s *Service) consentWithHistory(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID, consent repository.CommunicationConsent) (transport.LeadCommunicationConsentResponse, error) {
+	changes, err := s.repo.ListConsentChanges(ctx, leadID, tenantID, consentHistoryLimit)
+	if err != nil {
+		return transport.LeadCommunicationConsentResponse{}, err
+	}
+	history := make([]transport.ConsentChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		history = append(history, transport.ConsentChangeResponse{
+			ID:          change.ID,
+			Consent:     change.Consent,
+			Granted:     change.Granted,
+			Source:      change.Source,
+			ActorUserID: change.ActorUserID,
+			IPAddress:   change.IPAddress,
+			UserAgent:   change.UserAgent,
+			CreatedAt:   change.CreatedAt,
+		})
+	}
+	return transport.LeadCommunicationConsentResponse{
+		Consent: ToCommunicationConsentResponse(consent),
+		History: history,
+	}, nil
+}
+
+// recordWhatsAppConsentChange audits an agent toggling WhatsApp on the lead
+// itself, so the consent history stays complete next to the preference center.
+func (s *Service) recordWhatsAppConsentChange(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID, actorID uuid.UUID, previous bool, current bool) {
+	if previous == current {
+		return
+	}
+	changes := []repository.ConsentChange{{
+		Consent: repository.ConsentWhatsApp,
+		Granted: current,
+	}}
+	_ = s.repo.RecordConsentChanges(ctx, leadID, tenantID, changes, repository.ConsentChangeOrigin{
+		Source:      repository.ConsentSourceAgent,
+		ActorUserID: &actorID,
+	})
+	PublishChannelConsentChanges(ctx, s.eventBus, leadID, tenantID, changes)
+}
+
+// PublishChannelConsentChanges announces the email and WhatsApp consent
+// changes of a lead, so opt-outs reach the suppression list.
+func PublishChannelConsentChanges(ctx context.Context, bus events.Bus, leadID uuid.UUID, tenantID uuid.UUID, changes []repository.ConsentChange) {
+	if bus == nil {
+		return
+	}
+	for _, change := range changes {
+		if change.Consent != repository.ConsentEmail && change.Consent != repository.ConsentWhatsApp {
+			continue
+		}
+		bus.Publish(ctx, events.LeadChannelConsentChanged{
+			BaseEvent: events.NewBaseEvent(),
+			LeadID:    leadID,
+			TenantID:  tenantID,
+			Channel:   change.Consent,
+			Granted:   change.Granted,
+		})
+	}
+}
+
+// ConsentPatch converts a consent update request to a repository patch.
+func ConsentPatch(req transport.UpdateCommunicationConsentRequest) repository.CommunicationConsentPatch {
+	return repository.CommunicationConsentPatch{
+		Email:         req.Email,
+		WhatsApp:      req.WhatsApp,
+		Phone:         req.Phone,
+		Transactional: req.Transactional,
+		Marketing:     req.Marketing,
+	}
+}
+
+// ToCommunicationConsentResponse converts the consent of a lead to its DTO.
+func ToCommunicationConsentResponse(consent repository.CommunicationConsent) transport.CommunicationConsentResponse {
+	return transport.CommunicationConsentResponse{
+		Email:                consent.Email,
+		WhatsApp:             consent.WhatsApp,
+		Phone:                consent.Phone,
+		Transactional:        consent.Transactional,
+		Marketing:            consent.Marketing,
+		MarketingConfirmedAt: consent.MarketingConfirmedAt,
+		UpdatedAt:            consent.UpdatedAt,
+	}
+}
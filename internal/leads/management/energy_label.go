@@ -0,0 +1,231 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/leads/ports"
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Triggers recorded on energy label change events.
+const (
+	energyLabelTriggerRead      = "lead_read"
+	energyLabelTriggerScheduled = "scheduled_refresh"
+	energyLabelTriggerManual    = "manual_refresh"
+)
+
+// EnergyLabelRefreshResult summarizes a scheduled energy label refresh.
+type EnergyLabelRefreshResult struct {
+	Checked int
+	Changed int
+	Failed  int
+}
+
+// RefreshEnergyLabel re-fetches the energy label of a lead on request, regardless
+// of when it was last fetched.
+func (s *Service) RefreshEnergyLabel(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) (transport.EnergyLabelRefreshResponse, error) {
+	if s.energyEnricher == nil {
+		return transport.EnergyLabelRefreshResponse{}, apperr.Internal("energy label lookup is not configured")
+	}
+
+	lead, err := s.repo.GetByID(ctx, leadID, tenantID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.EnergyLabelRefreshResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.EnergyLabelRefreshResponse{}, err
+	}
+
+	previousClass := lead.EnergyClass
+	changed, err := s.refreshEnergyLabel(ctx, tenantID, &lead, energyLabelTriggerManual)
+	if err != nil {
+		return transport.EnergyLabelRefreshResponse{}, apperr.Internal("energy label lookup failed").WithDetails(err.Error())
+	}
+
+	return transport.EnergyLabelRefreshResponse{
+		EnergyLabel:   energyLabelFromLead(lead),
+		Changed:       changed,
+		PreviousClass: previousClass,
+		FetchedAt:     lead.EnergyLabelFetchedAt,
+		LeadScore:     leadScoreFromLead(lead),
+	}, nil
+}
+
+// RefreshStaleEnergyLabels re-fetches a batch of energy labels last fetched
+// before the given time. Failed lookups are retried by later runs.
+func (s *Service) RefreshStaleEnergyLabels(ctx context.Context, fetchedBefore time.Time, limit int) (EnergyLabelRefreshResult, error) {
+	var result EnergyLabelRefreshResult
+	if s.energyEnricher == nil {
+		return result, nil
+	}
+
+	candidates, err := s.repo.ListLeadsWithStaleEnergyLabel(ctx, fetchedBefore, limit)
+	if err != nil {
+		return result, err
+	}
+
+	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Checked++
+
+		lead, err := s.repo.GetByID(ctx, candidate.LeadID, candidate.OrganizationID)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		changed, err := s.refreshEnergyLabel(ctx, candidate.OrganizationID, &lead, energyLabelTriggerScheduled)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		if changed {
+			result.Changed++
+		}
+	}
+	return result, nil
+}
+
+// refreshEnergyLabel fetches the current label for the lead's address and stores
+// it. When a previously fetched label changed, it records a timeline event and
+// recalculates the lead score.
+func (s *Service) refreshEnergyLabel(ctx context.Context, tenantID uuid.UUID, lead *repository.Lead, trigger string) (bool, error) {
+	data, err := s.energyEnricher.EnrichLead(ctx, ports.EnrichLeadParams{
+		Postcode:   lead.AddressZipCode,
+		Huisnummer: lead.AddressHouseNumber,
+	})
+	if err != nil {
+		s.publishEnrichmentFailed(ctx, tenantID, lead.ID, enrichmentEnergyLabel, err)
+		return false, err
+	}
+
+	previous := *lead
+	fetchedAt := time.Now().UTC()
+	updateParams := energyLabelUpdateParams(previous, data, fetchedAt)
+	if err := s.repo.UpdateEnergyLabel(ctx, lead.ID, tenantID, updateParams); err != nil {
+		return false, err
+	}
+	applyEnergyLabelUpdate(lead, updateParams)
+
+	if previous.EnergyLabelFetchedAt == nil || !energyLabelChanged(previous, *lead) {
+		return false, nil
+	}
+
+	s.recordEnergyLabelChange(ctx, tenantID, previous, *lead, trigger)
+	s.rescoreLead(ctx, tenantID, lead)
+	return true, nil
+}
+
+// energyLabelUpdateParams builds the stored label from a lookup. A lookup that
+// finds nothing keeps a label fetched earlier, as registered labels are not
+// withdrawn and an empty answer is more likely an address or upstream issue.
+func energyLabelUpdateParams(lead repository.Lead, data *ports.LeadEnergyData, fetchedAt time.Time) repository.UpdateEnergyLabelParams {
+	if data == nil && lead.EnergyClass != nil {
+		return repository.UpdateEnergyLabelParams{
+			Class:          lead.EnergyClass,
+			Index:          lead.EnergyIndex,
+			Bouwjaar:       lead.EnergyBouwjaar,
+			Gebouwtype:     lead.EnergyGebouwtype,
+			ValidUntil:     lead.EnergyLabelValidUntil,
+			RegisteredAt:   lead.EnergyLabelRegisteredAt,
+			PrimairFossiel: lead.EnergyPrimairFossiel,
+			BAGObjectID:    lead.EnergyBAGVerblijfsobjectID,
+			FetchedAt:      fetchedAt,
+		}
+	}
+
+	ptrs := buildEnergyLabelPointers(data)
+	return repository.UpdateEnergyLabelParams{
+		Class:          ptrs.class,
+		Index:          ptrs.index,
+		Bouwjaar:       ptrs.bouwjaar,
+		Gebouwtype:     ptrs.gebouwtype,
+		ValidUntil:     ptrs.validUntil,
+		RegisteredAt:   ptrs.registeredAt,
+		PrimairFossiel: ptrs.primairFossiel,
+		BAGObjectID:    ptrs.bagObjectID,
+		FetchedAt:      fetchedAt,
+	}
+}
+
+// energyLabelChanged reports whether the label class changed or a new label was
+// registered for the address.
+func energyLabelChanged(previous, current repository.Lead) bool {
+	if normalizeEnergyClass(previous.EnergyClass) != normalizeEnergyClass(current.EnergyClass) {
+		return true
+	}
+	if (previous.EnergyLabelRegisteredAt == nil) != (current.EnergyLabelRegisteredAt == nil) {
+		return true
+	}
+	return previous.EnergyLabelRegisteredAt != nil && !previous.EnergyLabelRegisteredAt.Equal(*current.EnergyLabelRegisteredAt)
+}
+
+func normalizeEnergyClass(class *string) string {
+	if class == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(*class))
+}
+
+func (s *Service) recordEnergyLabelChange(ctx context.Context, tenantID uuid.UUID, previous, current repository.Lead, trigger string) {
+	summary := fmt.Sprintf("Energielabel gewijzigd van %s naar %s", energyClassOrUnknown(previous.EnergyClass), energyClassOrUnknown(current.EnergyClass))
+	_, _ = s.repo.CreateTimelineEvent(ctx, repository.CreateTimelineEventParams{
+		LeadID:         current.ID,
+		OrganizationID: tenantID,
+		ActorType:      repository.ActorTypeSystem,
+		ActorName:      repository.ActorNameOrchestrator,
+		EventType:      repository.EventTypeEnergyLabelChanged,
+		Title:          repository.EventTitleEnergyLabelChanged,
+		Summary:        &summary,
+		Metadata: repository.EnergyLabelChangedMetadata{
+			PreviousClass:        previous.EnergyClass,
+			Class:                current.EnergyClass,
+			PreviousRegisteredAt: previous.EnergyLabelRegisteredAt,
+			RegisteredAt:         current.EnergyLabelRegisteredAt,
+			Trigger:              trigger,
+		}.ToMap(),
+		Visibility: repository.TimelineVisibilityPublic,
+	})
+}
+
+func energyClassOrUnknown(class *string) string {
+	if normalized := normalizeEnergyClass(class); normalized != "" {
+		return normalized
+	}
+	return "onbekend"
+}
+
+// rescoreLead recalculates and stores the lead score after its inputs changed.
+func (s *Service) rescoreLead(ctx context.Context, tenantID uuid.UUID, lead *repository.Lead) {
+	if s.scorer == nil {
+		return
+	}
+	result, err := s.scorer.Recalculate(ctx, lead.ID, nil, tenantID, true)
+	if err != nil {
+		return
+	}
+	params := repository.UpdateLeadScoreParams{
+		Score:          &result.Score,
+		ScorePreAI:     &result.ScorePreAI,
+		ScoreFactors:   result.FactorsJSON,
+		ScoreVersion:   &result.Version,
+		ScoreUpdatedAt: result.UpdatedAt,
+	}
+	if err := s.repo.UpdateLeadScore(ctx, lead.ID, tenantID, params); err != nil {
+		return
+	}
+	lead.LeadScore = params.Score
+	lead.LeadScorePreAI = params.ScorePreAI
+	lead.LeadScoreFactors = params.ScoreFactors
+	lead.LeadScoreVersion = params.ScoreVersion
+	lead.LeadScoreUpdatedAt = &result.UpdatedAt
+}
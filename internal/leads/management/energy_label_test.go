@@ -0,0 +1,87 @@
+package management
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/leads/ports"
+	"portal_final_backend/internal/leads/repository"
+
+	"github.com/google/uuid"
+)
+
+type energyLabelRepo struct {
+	Repository
+	leads  map[uuid.UUID]repository.Lead
+	events []repository.CreateTimelineEventParams
+}
+
+func (r *energyLabelRepo) ListLeadsWithStaleEnergyLabel(context.Context, time.Time, int) ([]repository.EnergyLabelRefreshCandidate, error) {
+	items := make([]repository.EnergyLabelRefreshCandidate, 0, len(r.leads))
+	for _, lead := range r.leads {
+		items = append(items, repository.EnergyLabelRefreshCandidate{LeadID: lead.ID, OrganizationID: lead.OrganizationID})
+	}
+	return items, nil
+}
+
+func (r *energyLabelRepo) GetByID(_ context.Context, id uuid.UUID, _ uuid.UUID) (repository.Lead, error) {
+	return r.leads[id], nil
+}
+
+func (r *energyLabelRepo) UpdateEnergyLabel(_ context.Context, id uuid.UUID, _ uuid.UUID, params repository.UpdateEnergyLabelParams) error {
+	lead := r.leads[id]
+	applyEnergyLabelUpdate(&lead, params)
+	r.leads[id] = lead
+	return nil
+}
+
+func (r *energyLabelRepo) CreateTimelineEvent(_ context.Context, params repository.CreateTimelineEventParams) (repository.TimelineEvent, error) {
+	r.events = append(r.events, params)
+	return repository.TimelineEvent{}, nil
+}
+
+type staticEnergyLabels map[string]*ports.LeadEnergyData
+
+func (s staticEnergyLabels) EnrichLead(_ context.Context, params ports.EnrichLeadParams) (*ports.LeadEnergyData, error) {
+	return s[params.Postcode], nil
+}
+
+func TestRefreshStaleEnergyLabelsRecordsChanges(t *testing.T) {
+	fetched := time.Now().AddDate(-1, 0, 0)
+	registered := time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC)
+	class := func(v string) *string { return &v }
+	lead := func(zip string, energyClass *string) repository.Lead {
+		return repository.Lead{ID: uuid.New(), OrganizationID: uuid.New(), AddressZipCode: zip, AddressHouseNumber: "1",
+			EnergyClass: energyClass, EnergyLabelRegisteredAt: &registered, EnergyLabelFetchedAt: &fetched}
+	}
+	upgraded := lead("1000AA", class("E"))
+	unchanged := lead("2000BB", class("C"))
+	missing := lead("3000CC", class("D"))
+
+	renewed := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	repo := &energyLabelRepo{leads: map[uuid.UUID]repository.Lead{upgraded.ID: upgraded, unchanged.ID: unchanged, missing.ID: missing}}
+	svc := New(repo, nil, nil)
+	svc.SetEnergyLabelEnricher(staticEnergyLabels{
+		"1000AA": {Energieklasse: "B", Registratiedatum: &renewed},
+		"2000BB": {Energieklasse: "c", Registratiedatum: &registered},
+	})
+
+	result, err := svc.RefreshStaleEnergyLabels(context.Background(), time.Now(), 10)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if result.Checked != 3 || result.Changed != 1 || result.Failed != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	if len(repo.events) != 1 || repo.events[0].LeadID != upgraded.ID || repo.events[0].EventType != repository.EventTypeEnergyLabelChanged {
+		t.Fatalf("expected one change event for the upgraded lead, got %+v", repo.events)
+	}
+	if got := *repo.events[0].Summary; got != "Energielabel gewijzigd van E naar B" {
+		t.Fatalf("unexpected summary %q", got)
+	}
+	if got := repo.leads[missing.ID]; got.EnergyClass == nil || *got.EnergyClass != "D" || !got.EnergyLabelFetchedAt.After(fetched) {
+		t.Fatalf("expected an empty lookup to keep the stored label, got %+v", got)
+	}
+}
@@ -0,0 +1,253 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	handoverNoteRequiredMsg  = "handover note is required"
+	handoverSameAgentMsg     = "lead is already owned by this agent"
+	handoverNotMemberMsg     = "receiving agent is not a member of this organization"
+	handoverReportWindowDays = 30
+)
+
+// HandoverLead transfers a lead to another agent with a handover note. Agents
+// can only hand over their own leads; admins can hand over any lead.
+func (s *Service) HandoverLead(ctx context.Context, leadID uuid.UUID, req transport.HandoverLeadRequest, actorID uuid.UUID, tenantID uuid.UUID, actorRoles []string) (transport.LeadHandoverResponse, error) {
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return transport.LeadHandoverResponse{}, apperr.Validation(handoverNoteRequiredMsg)
+	}
+
+	lead, err := s.repo.GetByID(ctx, leadID, tenantID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.LeadHandoverResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.LeadHandoverResponse{}, err
+	}
+	if !hasRole(actorRoles, "admin") && (lead.AssignedAgentID == nil || *lead.AssignedAgentID != actorID) {
+		return transport.LeadHandoverResponse{}, apperr.Forbidden("forbidden")
+	}
+	if lead.AssignedAgentID != nil && *lead.AssignedAgentID == req.ToAgentID {
+		return transport.LeadHandoverResponse{}, apperr.Validation(handoverSameAgentMsg)
+	}
+
+	actorName, err := s.resolveHandoverMembers(ctx, tenantID, req.ToAgentID, actorID)
+	if err != nil {
+		return transport.LeadHandoverResponse{}, err
+	}
+
+	handover, err := s.repo.TransferLeadOwnership(ctx, repository.TransferLeadOwnershipParams{
+		LeadID:         leadID,
+		OrganizationID: tenantID,
+		ToAgentID:      req.ToAgentID,
+		Note:           note,
+		TransferredBy:  actorID,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.LeadHandoverResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.LeadHandoverResponse{}, err
+	}
+
+	s.recordLeadHandover(ctx, handover, actorName)
+	if s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.LeadsHandedOver{
+			BaseEvent:       events.NewBaseEvent(),
+			TenantID:        tenantID,
+			LeadIDs:         []uuid.UUID{leadID},
+			FromAgentID:     handover.FromAgentID,
+			ToAgentID:       req.ToAgentID,
+			TransferredByID: actorID,
+			Note:            note,
+		})
+	}
+
+	return toLeadHandoverResponse(handover), nil
+}
+
+// BulkHandoverAgentLeads transfers every lead of an agent to another agent,
+// for example when the agent leaves the organization.
+func (s *Service) BulkHandoverAgentLeads(ctx context.Context, fromAgentID uuid.UUID, req transport.BulkHandoverLeadsRequest, actorID uuid.UUID, tenantID uuid.UUID) (transport.BulkHandoverLeadsResponse, error) {
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return transport.BulkHandoverLeadsResponse{}, apperr.Validation(handoverNoteRequiredMsg)
+	}
+	if fromAgentID == req.ToAgentID {
+		return transport.BulkHandoverLeadsResponse{}, apperr.Validation("source and receiving agent must differ")
+	}
+
+	actorName, err := s.resolveHandoverMembers(ctx, tenantID, req.ToAgentID, actorID)
+	if err != nil {
+		return transport.BulkHandoverLeadsResponse{}, err
+	}
+
+	transfer, err := s.repo.BulkTransferAgentLeads(ctx, repository.BulkTransferAgentLeadsParams{
+		OrganizationID: tenantID,
+		FromAgentID:    fromAgentID,
+		ToAgentID:      req.ToAgentID,
+		Note:           note,
+		TransferredBy:  actorID,
+	})
+	if err != nil {
+		return transport.BulkHandoverLeadsResponse{}, err
+	}
+
+	for _, leadID := range transfer.LeadIDs {
+		s.recordLeadHandover(ctx, repository.LeadHandover{
+			OrganizationID: tenantID,
+			LeadID:         leadID,
+			FromAgentID:    &fromAgentID,
+			ToAgentID:      &req.ToAgentID,
+			Note:           note,
+			TransferredBy:  &actorID,
+			BulkTransferID: &transfer.ID,
+		}, actorName)
+	}
+	if s.eventBus != nil && len(transfer.LeadIDs) > 0 {
+		s.eventBus.Publish(ctx, events.LeadsHandedOver{
+			BaseEvent:       events.NewBaseEvent(),
+			TenantID:        tenantID,
+			LeadIDs:         transfer.LeadIDs,
+			FromAgentID:     &fromAgentID,
+			ToAgentID:       req.ToAgentID,
+			TransferredByID: actorID,
+			Note:            note,
+			BulkTransferID:  &transfer.ID,
+		})
+	}
+
+	return transport.BulkHandoverLeadsResponse{
+		BulkTransferID:   transfer.ID,
+		TransferredCount: len(transfer.LeadIDs),
+		LeadIDs:          transfer.LeadIDs,
+	}, nil
+}
+
+// ListLeadHandovers returns the ownership history of a lead.
+func (s *Service) ListLeadHandovers(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) (transport.LeadHandoverListResponse, error) {
+	if _, err := s.repo.GetByID(ctx, leadID, tenantID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.LeadHandoverListResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.LeadHandoverListResponse{}, err
+	}
+
+	handovers, err := s.repo.ListLeadHandovers(ctx, leadID, tenantID)
+	if err != nil {
+		return transport.LeadHandoverListResponse{}, err
+	}
+	items := make([]transport.LeadHandoverResponse, len(handovers))
+	for i, h := range handovers {
+		items[i] = toLeadHandoverResponse(h)
+	}
+	return transport.LeadHandoverListResponse{Items: items}, nil
+}
+
+// GetHandoverReport counts lead transfers per agent. The end is exclusive;
+// omitted bounds default to the last 30 days.
+func (s *Service) GetHandoverReport(ctx context.Context, from, to *time.Time, tenantID uuid.UUID) (transport.LeadHandoverReportResponse, error) {
+	start, end := handoverReportWindow(from, to, time.Now())
+	stats, err := s.repo.ListLeadHandoverStats(ctx, tenantID, start, end)
+	if err != nil {
+		return transport.LeadHandoverReportResponse{}, err
+	}
+
+	agents := make([]transport.LeadHandoverAgentStatsResponse, len(stats))
+	for i, st := range stats {
+		agents[i] = transport.LeadHandoverAgentStatsResponse{
+			AgentID:        st.AgentID,
+			Email:          st.Email,
+			TransferredIn:  st.TransferredIn,
+			TransferredOut: st.TransferredOut,
+		}
+	}
+	return transport.LeadHandoverReportResponse{From: start, To: end, Agents: agents}, nil
+}
+
+func handoverReportWindow(from, to *time.Time, now time.Time) (time.Time, time.Time) {
+	end := now
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(0, 0, -handoverReportWindowDays)
+	if from != nil {
+		start = *from
+	}
+	return start, end
+}
+
+// resolveHandoverMembers checks that the receiving agent belongs to the
+// organization and returns the display name of the acting user.
+func (s *Service) resolveHandoverMembers(ctx context.Context, tenantID, toAgentID, actorID uuid.UUID) (string, error) {
+	members, err := s.repo.ListOrgMembers(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	receiverFound := false
+	actorName := "user"
+	for _, member := range members {
+		if member.ID == toAgentID {
+			receiverFound = true
+		}
+		if member.ID == actorID {
+			actorName = member.Email
+		}
+	}
+	if !receiverFound {
+		return "", apperr.Validation(handoverNotMemberMsg)
+	}
+	return actorName, nil
+}
+
+func (s *Service) recordLeadHandover(ctx context.Context, handover repository.LeadHandover, actorName string) {
+	actorID := uuid.Nil
+	if handover.TransferredBy != nil {
+		actorID = *handover.TransferredBy
+	}
+	_ = s.repo.AddActivity(ctx, handover.LeadID, handover.OrganizationID, actorID, "handed_over", map[string]interface{}{
+		"from": handover.FromAgentID,
+		"to":   handover.ToAgentID,
+		"note": handover.Note,
+	})
+	_, _ = s.repo.CreateTimelineEvent(ctx, repository.CreateTimelineEventParams{
+		LeadID:         handover.LeadID,
+		OrganizationID: handover.OrganizationID,
+		ActorType:      repository.ActorTypeUser,
+		ActorName:      actorName,
+		EventType:      repository.EventTypeLeadHandover,
+		Title:          repository.EventTitleLeadHandover,
+		Summary:        repository.TruncateSummary(handover.Note, repository.TimelineSummaryMaxLen),
+		Metadata: map[string]any{
+			"fromAgentId":    handover.FromAgentID,
+			"toAgentId":      handover.ToAgentID,
+			"bulkTransferId": handover.BulkTransferID,
+		},
+		Visibility: repository.TimelineVisibilityInternal,
+	})
+}
+
+func toLeadHandoverResponse(h repository.LeadHandover) transport.LeadHandoverResponse {
+	return transport.LeadHandoverResponse{
+		ID:             h.ID,
+		LeadID:         h.LeadID,
+		FromAgentID:    h.FromAgentID,
+		ToAgentID:      h.ToAgentID,
+		Note:           h.Note,
+		TransferredBy:  h.TransferredBy,
+		BulkTransferID: h.BulkTransferID,
+		CreatedAt:      h.CreatedAt,
+	}
+}
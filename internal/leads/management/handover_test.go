@@ -0,0 +1,29 @@
+package management
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHandoverReportWindowDefaultsToLast30Days(t *testing.T) {
+	now := time.Date(2026, 5, 31, 10, 0, 0, 0, time.UTC)
+	start, end := handoverReportWindow(nil, nil, now)
+	if !end.Equal(now) || !start.Equal(now.AddDate(0, 0, -30)) {
+		t.Fatalf("unexpected window %s - %s", start, end)
+	}
+}
+
+func TestHandoverReportWindowUsesGivenBounds(t *testing.T) {
+	now := time.Date(2026, 5, 31, 10, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	start, end := handoverReportWindow(&from, &to, now)
+	if !start.Equal(from) || !end.Equal(to) {
+		t.Fatalf("unexpected window %s - %s", start, end)
+	}
+
+	start, _ = handoverReportWindow(nil, &to, now)
+	if !start.Equal(to.AddDate(0, 0, -30)) {
+		t.Fatalf("expected start 30 days before end, got %s", start)
+	}
+}
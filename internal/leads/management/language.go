@@ -0,0 +1,44 @@
+package management
+
+import (
+	"context"
+	"errors"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/i18n"
+
+	"github.com/google/uuid"
+)
+
+// GetPreferredLanguage returns the notification language of a lead.
+func (s *Service) GetPreferredLanguage(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) (transport.PreferredLanguageResponse, error) {
+	language, err := s.repo.GetPreferredLanguage(ctx, leadID, tenantID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.PreferredLanguageResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.PreferredLanguageResponse{}, err
+	}
+	return transport.PreferredLanguageResponse{Language: language}, nil
+}
+
+// SetPreferredLanguage sets or clears the notification language of a lead.
+func (s *Service) SetPreferredLanguage(ctx context.Context, leadID uuid.UUID, language *string, tenantID uuid.UUID) (transport.PreferredLanguageResponse, error) {
+	var normalized *string
+	if language != nil {
+		lang, ok := i18n.Normalize(*language)
+		if !ok {
+			return transport.PreferredLanguageResponse{}, apperr.Validation("unsupported language").WithDetails(*language)
+		}
+		normalized = &lang
+	}
+	if err := s.repo.SetPreferredLanguage(ctx, leadID, tenantID, normalized); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.PreferredLanguageResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.PreferredLanguageResponse{}, err
+	}
+	return transport.PreferredLanguageResponse{Language: normalized}, nil
+}
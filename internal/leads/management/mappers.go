@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"portal_final_backend/internal/leads/domain"
+	"portal_final_backend/internal/leads/notes"
 	"portal_final_backend/internal/leads/repository"
 	"portal_final_backend/internal/leads/transport"
 )
@@ -80,6 +81,24 @@ func leadScoreFromLead(lead repository.Lead) *transport.LeadScoreResponse {
 	}
 }
 
+func attributionFromLead(lead repository.Lead) *transport.AttributionResponse {
+	attribution := transport.AttributionResponse{
+		GCLID:         lead.GCLID,
+		FBCLID:        lead.FBCLID,
+		UTMSource:     lead.UTMSource,
+		UTMMedium:     lead.UTMMedium,
+		UTMCampaign:   lead.UTMCampaign,
+		UTMContent:    lead.UTMContent,
+		UTMTerm:       lead.UTMTerm,
+		AdLandingPage: lead.AdLandingPage,
+		ReferrerURL:   lead.ReferrerURL,
+	}
+	if attribution == (transport.AttributionResponse{}) {
+		return nil
+	}
+	return &attribution
+}
+
 // ToLeadResponse converts a repository Lead to a transport LeadResponse.
 func ToLeadResponse(lead repository.Lead) transport.LeadResponse {
 	return transport.LeadResponse{
@@ -90,12 +109,14 @@ func ToLeadResponse(lead repository.Lead) transport.LeadResponse {
 		Source:          lead.Source,
 		PublicToken:     lead.PublicToken,
 		WhatsAppOptedIn: lead.WhatsAppOptedIn,
+		RowVersion:      lead.RowVersion,
 		CreatedAt:       lead.CreatedAt,
 		UpdatedAt:       lead.UpdatedAt,
 		Services:        []transport.LeadServiceResponse{},
 		EnergyLabel:     energyLabelFromLead(lead),
 		LeadEnrichment:  leadEnrichmentFromLead(lead),
 		LeadScore:       leadScoreFromLead(lead),
+		Attribution:     attributionFromLead(lead),
 		Consumer: transport.ConsumerResponse{
 			FirstName: lead.ConsumerFirstName,
 			LastName:  lead.ConsumerLastName,
@@ -156,6 +177,7 @@ func ToLeadServiceResponse(svc repository.LeadService) transport.LeadServiceResp
 		ConsumerNote:         svc.ConsumerNote,
 		ExtraWorkAmountCents: svc.ExtraWorkAmountCents,
 		ExtraWorkNotes:       svc.ExtraWorkNotes,
+		RowVersion:           svc.RowVersion,
 		CreatedAt:            svc.CreatedAt,
 		UpdatedAt:            svc.UpdatedAt,
 	}
@@ -195,16 +217,7 @@ func toAIAnalysisResponsePtr(analysis repository.AIAnalysis) *transport.AIAnalys
 }
 
 func toLeadNoteResponse(note repository.LeadNote) transport.LeadNoteResponse {
-	return transport.LeadNoteResponse{
-		ID:          note.ID,
-		LeadID:      note.LeadID,
-		AuthorID:    note.AuthorID,
-		AuthorEmail: note.AuthorEmail,
-		Type:        note.Type,
-		Body:        note.Body,
-		CreatedAt:   note.CreatedAt,
-		UpdatedAt:   note.UpdatedAt,
-	}
+	return notes.ToLeadNoteResponse(note)
 }
 
 func leadPreferencesFromService(svc repository.LeadService) *transport.LeadPreferencesResponse {
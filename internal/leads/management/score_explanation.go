@@ -0,0 +1,54 @@
+package management
+
+import (
+	"context"
+	"errors"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// GetScoreExplanation returns the factor breakdown of a lead's current score.
+func (s *Service) GetScoreExplanation(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) (transport.LeadScoreExplanationResponse, error) {
+	if s.scorer == nil {
+		return transport.LeadScoreExplanationResponse{}, apperr.Internal("lead scoring is not configured")
+	}
+
+	explanation, err := s.scorer.Explain(ctx, leadID, tenantID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return transport.LeadScoreExplanationResponse{}, apperr.NotFound(leadNotFoundMsg)
+		}
+		return transport.LeadScoreExplanationResponse{}, err
+	}
+
+	factors := make([]transport.LeadScoreFactorResponse, len(explanation.Factors))
+	for i, f := range explanation.Factors {
+		factors[i] = transport.LeadScoreFactorResponse{
+			Key:          f.Key,
+			Label:        f.Label,
+			Source:       f.Source,
+			RawValue:     f.RawValue,
+			Unit:         f.Unit,
+			BasePoints:   f.BasePoints,
+			Weight:       f.Weight,
+			Confidence:   f.Confidence,
+			Contribution: f.Contribution,
+		}
+	}
+
+	return transport.LeadScoreExplanationResponse{
+		LeadID:      explanation.LeadID,
+		ServiceID:   explanation.ServiceID,
+		ServiceType: explanation.ServiceType,
+		Score:       explanation.Score,
+		PreAI:       explanation.ScorePreAI,
+		BaseScore:   explanation.BaseScore,
+		Version:     explanation.Version,
+		UpdatedAt:   explanation.UpdatedAt,
+		Factors:     factors,
+	}, nil
+}
@@ -5,6 +5,7 @@ package management
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -43,6 +44,8 @@ type Repository interface {
 	repository.LeadReader
 	repository.LeadWriter
 	repository.LeadViewTracker
+	repository.LeadLanguageStore
+	repository.LeadConsentStore
 	repository.ActivityLogger
 	repository.LeadServiceReader
 	repository.LeadServiceWriter
@@ -55,7 +58,12 @@ type Repository interface {
 	repository.FeedReactionStore
 	repository.FeedCommentStore
 	repository.OrgMemberReader
+	repository.LeadHandoverStore
+	repository.ServiceProjectStore
+	repository.AddressValidationStore
 	UpdateEnergyLabel(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, params repository.UpdateEnergyLabelParams) error
+	ListLeadsWithStaleEnergyLabel(ctx context.Context, fetchedBefore time.Time, limit int) ([]repository.EnergyLabelRefreshCandidate, error)
+	UpdateLeadScore(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, params repository.UpdateLeadScoreParams) error
 	UpdateLeadEnrichment(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, params repository.UpdateLeadEnrichmentParams) error
 }
 
@@ -75,6 +83,12 @@ type Service struct {
 	leadEnricher           ports.LeadEnricher
 	scorer                 *scoring.Service
 	workflowOverrideWriter LeadWorkflowOverrideWriter
+	addressGeocoder        AddressGeocoder
+}
+
+// AddressGeocoder geocodes a lead in the background after its address changed.
+type AddressGeocoder interface {
+	AddressChanged(leadID uuid.UUID, organizationID uuid.UUID)
 }
 
 type AcceptedQuoteUpdater interface {
@@ -156,6 +170,11 @@ func (s *Service) SetWorkflowOverrideWriter(writer LeadWorkflowOverrideWriter) {
 	s.workflowOverrideWriter = writer
 }
 
+// SetAddressGeocoder sets the geocoder that stores coordinates after an address change.
+func (s *Service) SetAddressGeocoder(geocoder AddressGeocoder) {
+	s.addressGeocoder = geocoder
+}
+
 // SetInAppNotificationService injects the in-app notification service.
 func (s *Service) SetInAppNotificationService(svc *inapp.Service) {
 	s.inAppService = svc
@@ -188,6 +207,7 @@ func (s *Service) SetLeadDetailWorkflowContextReader(reader LeadDetailWorkflowCo
 // Create creates a new lead.
 func (s *Service) Create(ctx context.Context, req transport.CreateLeadRequest, tenantID uuid.UUID) (transport.LeadResponse, error) {
 	req.Phone = phone.NormalizeE164(req.Phone)
+	addressCheck := s.validateIntakeAddress(ctx, &req)
 
 	whatsAppOptedIn := true
 	if req.WhatsAppOptedIn != nil {
@@ -208,6 +228,7 @@ func (s *Service) Create(ctx context.Context, req transport.CreateLeadRequest, t
 		Longitude:          req.Longitude,
 		Source:             toPtr(req.Source),
 		GCLID:              toPtr(req.GCLID),
+		FBCLID:             toPtr(req.FBCLID),
 		UTMSource:          toPtr(req.UTMSource),
 		UTMMedium:          toPtr(req.UTMMedium),
 		UTMCampaign:        toPtr(req.UTMCampaign),
@@ -230,6 +251,7 @@ func (s *Service) Create(ctx context.Context, req transport.CreateLeadRequest, t
 	if err != nil {
 		return transport.LeadResponse{}, err
 	}
+	s.recordAddressValidation(ctx, lead.ID, tenantID, addressCheck)
 
 	// Create the initial service for the lead
 	initialService, err := s.repo.CreateLeadService(ctx, repository.CreateLeadServiceParams{
@@ -242,15 +264,6 @@ func (s *Service) Create(ctx context.Context, req transport.CreateLeadRequest, t
 		return transport.LeadResponse{}, err
 	}
 
-	publicToken, err := token.GenerateRandomToken(32)
-	if err != nil {
-		return transport.LeadResponse{}, err
-	}
-	publicTokenExpiresAt := time.Now().Add(30 * 24 * time.Hour)
-	if err := s.repo.SetPublicToken(ctx, lead.ID, tenantID, publicToken, publicTokenExpiresAt); err != nil {
-		return transport.LeadResponse{}, err
-	}
-
 	if req.WorkflowID != nil && strings.TrimSpace(*req.WorkflowID) != "" && s.workflowOverrideWriter != nil {
 		workflowID, err := uuid.Parse(*req.WorkflowID)
 		if err != nil {
@@ -271,7 +284,14 @@ func (s *Service) Create(ctx context.Context, req transport.CreateLeadRequest, t
 		consumerEmail = *lead.ConsumerEmail
 	}
 
-	s.eventBus.Publish(ctx, events.LeadCreated{
+	publicToken, err := token.GenerateRandomToken(32)
+	if err != nil {
+		return transport.LeadResponse{}, err
+	}
+	// Setting the public token completes the lead, so LeadCreated is stored in
+	// the outbox with it.
+	publicTokenExpiresAt := time.Now().Add(30 * 24 * time.Hour)
+	if err := s.repo.SetPublicToken(ctx, lead.ID, tenantID, publicToken, publicTokenExpiresAt, events.LeadCreated{
 		BaseEvent:       events.NewBaseEvent(),
 		LeadID:          lead.ID,
 		LeadServiceID:   initialService.ID,
@@ -284,7 +304,9 @@ func (s *Service) Create(ctx context.Context, req transport.CreateLeadRequest, t
 		ConsumerEmail:   consumerEmail,
 		WhatsAppOptedIn: lead.WhatsAppOptedIn,
 		PublicToken:     publicToken,
-	})
+	}); err != nil {
+		return transport.LeadResponse{}, err
+	}
 
 	services, _ := s.repo.ListLeadServices(ctx, lead.ID, tenantID)
 	resp := ToLeadResponseWithServices(lead, services)
@@ -709,36 +731,10 @@ func (s *Service) enrichWithEnergyLabel(ctx context.Context, tenantID uuid.UUID,
 		return
 	}
 
-	params := ports.EnrichLeadParams{
-		Postcode:   lead.AddressZipCode,
-		Huisnummer: lead.AddressHouseNumber,
-	}
-
-	data, err := s.energyEnricher.EnrichLead(ctx, params)
-	if err != nil {
+	if _, err := s.refreshEnergyLabel(ctx, tenantID, lead, energyLabelTriggerRead); err != nil {
 		return
 	}
 
-	fetchedAt := time.Now().UTC()
-	ptrs := buildEnergyLabelPointers(data)
-	updateParams := repository.UpdateEnergyLabelParams{
-		Class:          ptrs.class,
-		Index:          ptrs.index,
-		Bouwjaar:       ptrs.bouwjaar,
-		Gebouwtype:     ptrs.gebouwtype,
-		ValidUntil:     ptrs.validUntil,
-		RegisteredAt:   ptrs.registeredAt,
-		PrimairFossiel: ptrs.primairFossiel,
-		BAGObjectID:    ptrs.bagObjectID,
-		FetchedAt:      fetchedAt,
-	}
-
-	if err := s.repo.UpdateEnergyLabel(ctx, lead.ID, tenantID, updateParams); err != nil {
-		return
-	}
-
-	applyEnergyLabelUpdate(lead, updateParams)
-
 	resp.EnergyLabel = energyLabelFromLead(*lead)
 }
 
@@ -815,6 +811,27 @@ func applyEnergyLabelUpdate(lead *repository.Lead, params repository.UpdateEnerg
 	lead.EnergyLabelFetchedAt = &fetchedAt
 }
 
+// Enrichments reported in LeadEnrichmentFailed.
+const (
+	enrichmentLead        = "lead_enrichment"
+	enrichmentEnergyLabel = "energy_label"
+)
+
+// publishEnrichmentFailed reports a failed lookup, so the lead shows up in the
+// triage inbox when the data keeps missing.
+func (s *Service) publishEnrichmentFailed(ctx context.Context, tenantID uuid.UUID, leadID uuid.UUID, enrichment string, err error) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(ctx, events.LeadEnrichmentFailed{
+		BaseEvent:  events.NewBaseEvent(),
+		LeadID:     leadID,
+		TenantID:   tenantID,
+		Enrichment: enrichment,
+		Error:      err.Error(),
+	})
+}
+
 // enrichWithLeadData ensures the lead has up-to-date enrichment and score data.
 // This is a best-effort operation - failures do not block the request flow.
 func (s *Service) enrichWithLeadData(ctx context.Context, tenantID uuid.UUID, lead *repository.Lead, resp *transport.LeadResponse) {
@@ -832,7 +849,11 @@ func (s *Service) enrichWithLeadData(ctx context.Context, tenantID uuid.UUID, le
 	}
 
 	data, err := s.leadEnricher.EnrichLead(ctx, lead.AddressZipCode)
-	if err != nil || data == nil {
+	if err != nil {
+		s.publishEnrichmentFailed(ctx, tenantID, lead.ID, enrichmentLead, err)
+		return
+	}
+	if data == nil {
 		return
 	}
 
@@ -921,18 +942,32 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, req transport.Update
 		return transport.LeadResponse{}, err
 	}
 
-	addressUpdateRequested, err := s.applyAddressGeocode(ctx, id, tenantID, req, &params, &current)
+	addressUpdateRequested, addressChanged, err := s.detectAddressChange(ctx, id, tenantID, req, &current)
 	if err != nil {
 		return transport.LeadResponse{}, err
 	}
 
 	applyUpdateFields(&params, req, !addressUpdateRequested)
+	params.ExpectedRowVersion = req.RowVersion
+
+	var previousConsent repository.CommunicationConsent
+	if req.WhatsAppOptedIn != nil {
+		if previousConsent, err = s.repo.GetCommunicationConsent(ctx, id, tenantID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return transport.LeadResponse{}, apperr.NotFound(leadNotFoundMsg)
+			}
+			return transport.LeadResponse{}, err
+		}
+	}
 
 	lead, err := s.repo.Update(ctx, id, tenantID, params)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return transport.LeadResponse{}, apperr.NotFound(leadNotFoundMsg)
 		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return transport.LeadResponse{}, s.versionConflict(ctx, id, tenantID)
+		}
 		return transport.LeadResponse{}, err
 	}
 
@@ -945,10 +980,29 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, req transport.Update
 		}
 	}
 
+	if req.WhatsAppOptedIn != nil {
+		s.recordWhatsAppConsentChange(ctx, id, tenantID, actorID, previousConsent.WhatsApp, lead.WhatsAppOptedIn)
+	}
+
+	if addressChanged && s.addressGeocoder != nil {
+		s.addressGeocoder.AddressChanged(lead.ID, tenantID)
+	}
+
 	services, _ := s.repo.ListLeadServices(ctx, lead.ID, tenantID)
 	return ToLeadResponseWithServices(lead, services), nil
 }
 
+// versionConflict reports an update based on an outdated version, with the
+// current state of the lead so the client can reapply its change without
+// another read.
+func (s *Service) versionConflict(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) error {
+	current, err := s.GetByID(ctx, leadID, tenantID)
+	if err != nil {
+		return err
+	}
+	return apperr.Conflict("lead was changed by someone else").WithDetails(current)
+}
+
 // Delete soft-deletes a lead.
 func (s *Service) Delete(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) error {
 	err := s.repo.Delete(ctx, id, tenantID)
@@ -1052,9 +1106,37 @@ func buildListParams(req transport.ListLeadsRequest) (repository.ListParams, err
 	params.CreatedAtFrom = createdFrom
 	params.CreatedAtTo = createdTo
 
+	if params.CustomFields, err = parseCustomFieldFilter(req.CustomFields); err != nil {
+		return repository.ListParams{}, err
+	}
+
 	return params, nil
 }
 
+// parseCustomFieldFilter checks that a customFields filter is a JSON object of
+// scalar values and returns it for a containment match on the stored values.
+func parseCustomFieldFilter(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var values map[string]any
+	if err := json.Unmarshal([]byte(raw), &values); err != nil {
+		return nil, apperr.Validation("customFields must be a JSON object")
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	for key, value := range values {
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			return nil, apperr.Validation(fmt.Sprintf("customFields.%s must be a string, number or boolean", key))
+		}
+	}
+	return json.Marshal(values)
+}
+
 func optionalString(value string) *string {
 	if strings.TrimSpace(value) == "" {
 		return nil
@@ -1306,6 +1388,18 @@ func (s *Service) UpdateServiceStatus(ctx context.Context, leadID uuid.UUID, ser
 		return transport.LeadResponse{}, apperr.Validation("cannot update status for a service in terminal state")
 	}
 
+	if req.RowVersion != nil {
+		if err := s.repo.ClaimLeadServiceVersion(ctx, serviceID, tenantID, *req.RowVersion); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return transport.LeadResponse{}, s.versionConflict(ctx, leadID, tenantID)
+			}
+			if errors.Is(err, repository.ErrServiceNotFound) {
+				return transport.LeadResponse{}, apperr.NotFound(leadServiceNotFoundMsg)
+			}
+			return transport.LeadResponse{}, err
+		}
+	}
+
 	// Disqualified <-> Lost is an invariant. When a user sets Disqualified we auto-move the stage to Lost.
 	// Do it atomically to avoid invalid intermediate combinations that would otherwise fail validation.
 	if string(req.Status) == domain.LeadStatusDisqualified && svc.PipelineStage != domain.PipelineStageLost {
@@ -2067,31 +2161,28 @@ func applyUpdateFields(params *repository.UpdateLeadParams, req transport.Update
 	}
 }
 
-func (s *Service) applyAddressGeocode(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, req transport.UpdateLeadRequest, params *repository.UpdateLeadParams, current **repository.Lead) (bool, error) {
+// detectAddressChange reports whether the update touches the address and
+// whether it actually changes it. Coordinates of a changed address are
+// resolved in the background by the address geocoder, so the ones in the
+// request are ignored.
+func (s *Service) detectAddressChange(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, req transport.UpdateLeadRequest, current **repository.Lead) (bool, bool, error) {
 	if !hasAddressUpdate(req) {
-		return false, nil
+		return false, false, nil
 	}
 
 	if *current == nil {
 		lead, err := s.repo.GetByID(ctx, id, tenantID)
 		if err != nil {
 			if errors.Is(err, repository.ErrNotFound) {
-				return true, apperr.NotFound(leadNotFoundMsg)
+				return true, false, apperr.NotFound(leadNotFoundMsg)
 			}
-			return true, err
+			return true, false, err
 		}
 		*current = &lead
 	}
 
-	updatedAddress, changed := buildUpdatedAddress(**current, req)
-	if changed {
-		if lat, lon, ok := s.geocodeAddress(ctx, updatedAddress); ok {
-			params.Latitude = &lat
-			params.Longitude = &lon
-		}
-	}
-
-	return true, nil
+	_, changed := buildUpdatedAddress(**current, req)
+	return true, changed, nil
 }
 
 type addressUpdate struct {
@@ -2134,40 +2225,6 @@ func buildUpdatedAddress(current repository.Lead, req transport.UpdateLeadReques
 	return updated, changed
 }
 
-func (s *Service) geocodeAddress(ctx context.Context, address addressUpdate) (float64, float64, bool) {
-	if s.maps == nil {
-		return 0, 0, false
-	}
-
-	if address.street == "" || address.city == "" {
-		return 0, 0, false
-	}
-
-	query := formatGeocodeQuery(address)
-	suggestions, err := s.maps.SearchAddress(ctx, query)
-	if err != nil || len(suggestions) == 0 {
-		return 0, 0, false
-	}
-
-	lat, err := strconv.ParseFloat(suggestions[0].Lat, 64)
-	if err != nil {
-		return 0, 0, false
-	}
-	lon, err := strconv.ParseFloat(suggestions[0].Lon, 64)
-	if err != nil {
-		return 0, 0, false
-	}
-
-	return lat, lon, true
-}
-
-func formatGeocodeQuery(address addressUpdate) string {
-	streetPart := strings.TrimSpace(strings.Join([]string{address.street, address.houseNumber}, " "))
-	cityPart := strings.TrimSpace(strings.Join([]string{address.zipCode, address.city}, " "))
-	query := strings.TrimSpace(fmt.Sprintf("%s, %s", streetPart, cityPart))
-	return strings.Trim(query, ", ")
-}
-
 func hasRole(roles []string, target string) bool {
 	for _, role := range roles {
 		if role == target {
@@ -0,0 +1,205 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"portal_final_backend/internal/leads/domain"
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	serviceProjectNotFoundMsg     = "service project not found"
+	serviceProjectDuplicateMsg    = "a service can only be listed once per project"
+	serviceProjectForeignMsg      = "service does not belong to this lead"
+	serviceProjectDependencyMsg   = "dependencies must refer to services of the project"
+	serviceProjectCycleMsg        = "service dependencies contain a cycle"
+	serviceProjectAlreadyGroupMsg = "a service already belongs to another project"
+)
+
+// CreateServiceProject groups services of a lead into a project. The services
+// are stored in an order that satisfies their dependencies.
+func (s *Service) CreateServiceProject(ctx context.Context, leadID uuid.UUID, req transport.CreateServiceProjectRequest, actorID uuid.UUID, tenantID uuid.UUID) (transport.ServiceProjectResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return transport.ServiceProjectResponse{}, apperr.Validation("project name is required")
+	}
+
+	services, err := s.leadServicesByID(ctx, leadID, tenantID)
+	if err != nil {
+		return transport.ServiceProjectResponse{}, err
+	}
+	for _, id := range req.ServiceIDs {
+		if _, ok := services[id]; !ok {
+			return transport.ServiceProjectResponse{}, apperr.Validation(serviceProjectForeignMsg)
+		}
+	}
+
+	deps := make([]repository.LeadServiceDependency, len(req.Dependencies))
+	for i, d := range req.Dependencies {
+		deps[i] = repository.LeadServiceDependency{LeadServiceID: d.ServiceID, DependsOnServiceID: d.DependsOnServiceID}
+	}
+	ordered, err := orderProjectServices(req.ServiceIDs, deps)
+	if err != nil {
+		return transport.ServiceProjectResponse{}, err
+	}
+
+	project, err := s.repo.CreateServiceProject(ctx, repository.CreateServiceProjectParams{
+		OrganizationID: tenantID,
+		LeadID:         leadID,
+		Name:           name,
+		CreatedBy:      actorID,
+		ServiceIDs:     ordered,
+		Dependencies:   deps,
+	})
+	if errors.Is(err, repository.ErrServiceInProject) {
+		return transport.ServiceProjectResponse{}, apperr.Conflict(serviceProjectAlreadyGroupMsg)
+	}
+	if err != nil {
+		return transport.ServiceProjectResponse{}, err
+	}
+	return toServiceProjectResponse(project, services), nil
+}
+
+// ListServiceProjects returns the projects of a lead.
+func (s *Service) ListServiceProjects(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) (transport.ServiceProjectListResponse, error) {
+	services, err := s.leadServicesByID(ctx, leadID, tenantID)
+	if err != nil {
+		return transport.ServiceProjectListResponse{}, err
+	}
+	projects, err := s.repo.ListServiceProjects(ctx, leadID, tenantID)
+	if err != nil {
+		return transport.ServiceProjectListResponse{}, err
+	}
+	items := make([]transport.ServiceProjectResponse, len(projects))
+	for i, p := range projects {
+		items[i] = toServiceProjectResponse(p, services)
+	}
+	return transport.ServiceProjectListResponse{Items: items}, nil
+}
+
+// DeleteServiceProject ungroups the services of a project.
+func (s *Service) DeleteServiceProject(ctx context.Context, leadID uuid.UUID, projectID uuid.UUID, tenantID uuid.UUID) error {
+	project, err := s.repo.GetServiceProject(ctx, projectID, tenantID)
+	if errors.Is(err, repository.ErrServiceProjectNotFound) || (err == nil && project.LeadID != leadID) {
+		return apperr.NotFound(serviceProjectNotFoundMsg)
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteServiceProject(ctx, projectID, tenantID); err != nil {
+		if errors.Is(err, repository.ErrServiceProjectNotFound) {
+			return apperr.NotFound(serviceProjectNotFoundMsg)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Service) leadServicesByID(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) (map[uuid.UUID]repository.LeadService, error) {
+	if _, err := s.repo.GetByID(ctx, leadID, tenantID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, apperr.NotFound(leadNotFoundMsg)
+		}
+		return nil, err
+	}
+	services, err := s.repo.ListLeadServices(ctx, leadID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]repository.LeadService, len(services))
+	for _, svc := range services {
+		byID[svc.ID] = svc
+	}
+	return byID, nil
+}
+
+// orderProjectServices sorts services so that every service comes after the
+// services it depends on. Independent services keep their requested order.
+func orderProjectServices(serviceIDs []uuid.UUID, deps []repository.LeadServiceDependency) ([]uuid.UUID, error) {
+	inProject := make(map[uuid.UUID]bool, len(serviceIDs))
+	for _, id := range serviceIDs {
+		if inProject[id] {
+			return nil, apperr.Validation(serviceProjectDuplicateMsg)
+		}
+		inProject[id] = true
+	}
+
+	pending := make(map[uuid.UUID]int, len(serviceIDs))
+	dependents := make(map[uuid.UUID][]uuid.UUID)
+	for _, d := range deps {
+		if !inProject[d.LeadServiceID] || !inProject[d.DependsOnServiceID] {
+			return nil, apperr.Validation(serviceProjectDependencyMsg)
+		}
+		if d.LeadServiceID == d.DependsOnServiceID {
+			return nil, apperr.Validation(serviceProjectCycleMsg)
+		}
+		pending[d.LeadServiceID]++
+		dependents[d.DependsOnServiceID] = append(dependents[d.DependsOnServiceID], d.LeadServiceID)
+	}
+
+	ordered := make([]uuid.UUID, 0, len(serviceIDs))
+	placed := make(map[uuid.UUID]bool, len(serviceIDs))
+	for len(ordered) < len(serviceIDs) {
+		progressed := false
+		for _, id := range serviceIDs {
+			if placed[id] || pending[id] > 0 {
+				continue
+			}
+			placed[id] = true
+			ordered = append(ordered, id)
+			for _, dependent := range dependents[id] {
+				pending[dependent]--
+			}
+			progressed = true
+			break
+		}
+		if !progressed {
+			return nil, apperr.Validation(serviceProjectCycleMsg)
+		}
+	}
+	return ordered, nil
+}
+
+func toServiceProjectResponse(p repository.LeadServiceProject, services map[uuid.UUID]repository.LeadService) transport.ServiceProjectResponse {
+	dependsOn := make(map[uuid.UUID][]uuid.UUID)
+	for _, d := range p.Dependencies {
+		dependsOn[d.LeadServiceID] = append(dependsOn[d.LeadServiceID], d.DependsOnServiceID)
+	}
+
+	resp := transport.ServiceProjectResponse{
+		ID:        p.ID,
+		LeadID:    p.LeadID,
+		Name:      p.Name,
+		Services:  make([]transport.ServiceProjectServiceResponse, 0, len(p.ServiceIDs)),
+		CreatedBy: p.CreatedBy,
+		CreatedAt: p.CreatedAt,
+	}
+	for position, id := range p.ServiceIDs {
+		svc := services[id]
+		item := transport.ServiceProjectServiceResponse{
+			ServiceID:     id,
+			ServiceType:   svc.ServiceType,
+			Status:        svc.Status,
+			PipelineStage: svc.PipelineStage,
+			Position:      position,
+			DependsOn:     dependsOn[id],
+			WaitingFor:    []uuid.UUID{},
+		}
+		if item.DependsOn == nil {
+			item.DependsOn = []uuid.UUID{}
+		}
+		for _, prerequisite := range item.DependsOn {
+			if services[prerequisite].PipelineStage != domain.PipelineStageCompleted {
+				item.WaitingFor = append(item.WaitingFor, prerequisite)
+			}
+		}
+		resp.Services = append(resp.Services, item)
+	}
+	return resp
+}
@@ -0,0 +1,59 @@
+package management
+
+import (
+	"errors"
+	"testing"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+func TestOrderProjectServicesPutsPrerequisitesFirst(t *testing.T) {
+	painting, dakkapel, roofing := uuid.New(), uuid.New(), uuid.New()
+	deps := []repository.LeadServiceDependency{
+		{LeadServiceID: painting, DependsOnServiceID: dakkapel},
+		{LeadServiceID: roofing, DependsOnServiceID: dakkapel},
+		{LeadServiceID: painting, DependsOnServiceID: roofing},
+	}
+
+	ordered, err := orderProjectServices([]uuid.UUID{painting, roofing, dakkapel}, deps)
+	if err != nil {
+		t.Fatalf("orderProjectServices() error = %v", err)
+	}
+	want := []uuid.UUID{dakkapel, roofing, painting}
+	for i := range want {
+		if ordered[i] != want[i] {
+			t.Fatalf("orderProjectServices() = %v, want %v", ordered, want)
+		}
+	}
+}
+
+func TestOrderProjectServicesKeepsRequestedOrderWithoutDependencies(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	ordered, err := orderProjectServices([]uuid.UUID{b, a}, nil)
+	if err != nil || ordered[0] != b || ordered[1] != a {
+		t.Fatalf("orderProjectServices() = %v, %v", ordered, err)
+	}
+}
+
+func TestOrderProjectServicesRejectsInvalidGraphs(t *testing.T) {
+	a, b, outside := uuid.New(), uuid.New(), uuid.New()
+	cases := map[string]struct {
+		services []uuid.UUID
+		deps     []repository.LeadServiceDependency
+	}{
+		"cycle":     {[]uuid.UUID{a, b}, []repository.LeadServiceDependency{{LeadServiceID: a, DependsOnServiceID: b}, {LeadServiceID: b, DependsOnServiceID: a}}},
+		"self":      {[]uuid.UUID{a, b}, []repository.LeadServiceDependency{{LeadServiceID: a, DependsOnServiceID: a}}},
+		"outside":   {[]uuid.UUID{a, b}, []repository.LeadServiceDependency{{LeadServiceID: a, DependsOnServiceID: outside}}},
+		"duplicate": {[]uuid.UUID{a, a}, nil},
+	}
+	for name, tc := range cases {
+		_, err := orderProjectServices(tc.services, tc.deps)
+		var appErr *apperr.Error
+		if !errors.As(err, &appErr) || appErr.Kind != apperr.KindValidation {
+			t.Fatalf("%s: orderProjectServices() error = %v, want validation error", name, err)
+		}
+	}
+}
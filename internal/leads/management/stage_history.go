@@ -0,0 +1,94 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/leads/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// GetStageHistory returns every pipeline stage change of a lead service with
+// the time it spent in each stage.
+func (s *Service) GetStageHistory(ctx context.Context, leadID uuid.UUID, serviceID uuid.UUID, tenantID uuid.UUID) (transport.StageHistoryResponse, error) {
+	svc, err := s.repo.GetLeadServiceByID(ctx, serviceID, tenantID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return transport.StageHistoryResponse{}, apperr.NotFound(leadServiceNotFoundMsg)
+		}
+		return transport.StageHistoryResponse{}, err
+	}
+	if svc.LeadID != leadID {
+		return transport.StageHistoryResponse{}, apperr.NotFound(leadServiceNotFoundMsg)
+	}
+
+	transitions, err := s.repo.ListStageTransitions(ctx, serviceID, tenantID)
+	if err != nil {
+		return transport.StageHistoryResponse{}, err
+	}
+	return buildStageHistory(svc, transitions, time.Now()), nil
+}
+
+// buildStageHistory walks the transitions from the creation of the service.
+// The service is in the from stage of each transition since the previous one,
+// and in its current stage since the last one.
+func buildStageHistory(svc repository.LeadService, transitions []repository.StageTransition, now time.Time) transport.StageHistoryResponse {
+	resp := transport.StageHistoryResponse{
+		ServiceID:      svc.ID,
+		CurrentStage:   svc.PipelineStage,
+		Transitions:    make([]transport.StageTransitionResponse, 0, len(transitions)),
+		StageDurations: []transport.StageDurationResponse{},
+	}
+
+	index := make(map[string]int)
+	addDuration := func(stage string, d time.Duration, current bool) {
+		i, ok := index[stage]
+		if !ok {
+			i = len(resp.StageDurations)
+			index[stage] = i
+			resp.StageDurations = append(resp.StageDurations, transport.StageDurationResponse{Stage: stage})
+		}
+		resp.StageDurations[i].TotalSeconds += int64(d / time.Second)
+		resp.StageDurations[i].Visits++
+		if current {
+			resp.StageDurations[i].Current = true
+		}
+	}
+
+	since := svc.CreatedAt
+	for _, t := range transitions {
+		spent := nonNegative(t.OccurredAt.Sub(since))
+		// Transitions reconstructed from old service events may not know the
+		// stage they came from; that time is not attributed to any stage.
+		if t.FromStage != nil {
+			addDuration(*t.FromStage, spent, false)
+		}
+		resp.Transitions = append(resp.Transitions, transport.StageTransitionResponse{
+			ID:              t.ID,
+			FromStage:       t.FromStage,
+			ToStage:         t.ToStage,
+			ActorType:       t.ActorType,
+			ActorID:         t.ActorID,
+			ActorName:       t.ActorName,
+			Reason:          t.Reason,
+			OccurredAt:      t.OccurredAt,
+			DurationSeconds: int64(spent / time.Second),
+		})
+		since = t.OccurredAt
+	}
+
+	resp.CurrentSince = since
+	addDuration(svc.PipelineStage, nonNegative(now.Sub(since)), true)
+	return resp
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,62 @@
+package management
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"portal_final_backend/internal/leads/domain"
+	"portal_final_backend/internal/leads/repository"
+)
+
+func TestBuildStageHistoryComputesTimeInStage(t *testing.T) {
+	created := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	triage := domain.PipelineStageTriage
+	estimation := domain.PipelineStageEstimation
+	svc := repository.LeadService{ID: uuid.New(), PipelineStage: domain.PipelineStageTriage, CreatedAt: created}
+
+	history := buildStageHistory(svc, []repository.StageTransition{
+		{ID: uuid.New(), FromStage: &triage, ToStage: estimation, ActorType: repository.ActorTypeAI, ActorName: repository.ActorNameGatekeeper, OccurredAt: created.Add(2 * time.Hour)},
+		{ID: uuid.New(), FromStage: &estimation, ToStage: triage, ActorType: repository.ActorTypeUser, ActorName: "user", OccurredAt: created.Add(5 * time.Hour)},
+	}, created.Add(6*time.Hour))
+
+	if len(history.Transitions) != 2 {
+		t.Fatalf("expected 2 transitions, got %d", len(history.Transitions))
+	}
+	if got := history.Transitions[0].DurationSeconds; got != int64(2*time.Hour/time.Second) {
+		t.Fatalf("expected first transition after 2h, got %ds", got)
+	}
+	if got := history.Transitions[1].DurationSeconds; got != int64(3*time.Hour/time.Second) {
+		t.Fatalf("expected second transition after 3h, got %ds", got)
+	}
+	if !history.CurrentSince.Equal(created.Add(5 * time.Hour)) {
+		t.Fatalf("unexpected current since %s", history.CurrentSince)
+	}
+
+	if len(history.StageDurations) != 2 {
+		t.Fatalf("expected 2 stage durations, got %d", len(history.StageDurations))
+	}
+	triageTotal := history.StageDurations[0]
+	if triageTotal.Stage != triage || triageTotal.Visits != 2 || !triageTotal.Current || triageTotal.TotalSeconds != int64(3*time.Hour/time.Second) {
+		t.Fatalf("unexpected triage duration %+v", triageTotal)
+	}
+	estimationTotal := history.StageDurations[1]
+	if estimationTotal.Stage != estimation || estimationTotal.Visits != 1 || estimationTotal.Current || estimationTotal.TotalSeconds != int64(3*time.Hour/time.Second) {
+		t.Fatalf("unexpected estimation duration %+v", estimationTotal)
+	}
+}
+
+func TestBuildStageHistoryWithoutTransitions(t *testing.T) {
+	created := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	svc := repository.LeadService{ID: uuid.New(), PipelineStage: domain.PipelineStageTriage, CreatedAt: created}
+
+	history := buildStageHistory(svc, nil, created.Add(time.Minute))
+
+	if len(history.Transitions) != 0 {
+		t.Fatalf("expected no transitions, got %d", len(history.Transitions))
+	}
+	if len(history.StageDurations) != 1 || history.StageDurations[0].TotalSeconds != 60 || !history.StageDurations[0].Current {
+		t.Fatalf("unexpected stage durations %+v", history.StageDurations)
+	}
+}
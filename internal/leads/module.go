@@ -19,6 +19,7 @@ import (
 	"portal_final_backend/internal/leads/maintenance"
 	"portal_final_backend/internal/leads/management"
 	"portal_final_backend/internal/leads/notes"
+	"portal_final_backend/internal/leads/portalauth"
 	"portal_final_backend/internal/leads/ports"
 	"portal_final_backend/internal/leads/repository"
 	"portal_final_backend/internal/leads/scoring"
@@ -55,10 +56,12 @@ type Module struct {
 	offerSummaryGenerator *agent.OfferSummaryGenerator
 	replyAgent            *agent.ReplyAgent
 	staleReEngagement     *maintenance.StaleLeadReEngagementService
+	leadGeocoder          *maintenance.LeadGeocoder
+	portalLinks           *portalauth.Service
 	subsidyAnalyzerSvc    *SubsidyAnalyzerService
 	sse                   *sse.Service
 	eventBus              events.Bus
-	repo                  repository.LeadsRepository
+	repo                  *repository.Repository
 	storage               storage.StorageService
 	attachmentsBucket     string
 	log                   *logger.Logger
@@ -139,6 +142,7 @@ func NewModule(ctx context.Context, pool *pgxpool.Pool, eventBus events.Bus, sto
 
 	// Create focused services (vertical slices)
 	mapsSvc := maps.NewService(log)
+	mapsSvc.SetGeocoding(maps.NewGeocodeProviders(cfg), maps.NewRepository(pool))
 	mgmtSvc := management.New(repo, eventBus, mapsSvc)
 	mgmtSvc.SetLeadScorer(scorer)
 	notesSvc := notes.New(repo)
@@ -179,6 +183,13 @@ func NewModule(ctx context.Context, pool *pgxpool.Pool, eventBus events.Bus, sto
 	publicHandler := handler.NewPublicHandler(repo, eventBus, sseService, storageSvc, cfg.GetMinioBucketLeadServiceAttachments(), val)
 	publicHandler.SetPublicAPIBaseURL(cfg.GetPublicAPIBaseURL())
 
+	// Magic links to the lead portal, revoked once a lead closes
+	portalLinks := portalauth.New(repo, cfg.GetJWTAccessSecret(), eventBus, log)
+	portalLinks.SetPublicBaseURL(cfg.GetPublicBaseURL())
+	h.SetPortalLinkService(portalLinks)
+	publicHandler.SetPortalLinkService(portalLinks)
+	eventBus.Subscribe(events.PipelineStageChanged{}.EventName(), typedHandler(portalLinks.OnPipelineStageChanged))
+
 	// Stale lead detector for the dashboard API
 	staleDetector := maintenance.NewStaleLeadDetector(pool, log)
 	h.SetStaleLeadDetector(staleDetector)
@@ -188,6 +199,12 @@ func NewModule(ctx context.Context, pool *pgxpool.Pool, eventBus events.Bus, sto
 	staleReEngagement := maintenance.NewStaleLeadReEngagementService(pool, staleReEngagementAgent, nil, log)
 	h.SetStaleSuggester(staleReEngagement)
 
+	// Geocodes new leads and changed addresses in the background
+	leadGeocoder := maintenance.NewLeadGeocoder(pool, mapsSvc, sseService, log)
+	mgmtSvc.SetAddressGeocoder(leadGeocoder)
+	eventBus.Subscribe(events.LeadCreated{}.EventName(), events.HandlerFunc(leadGeocoder.Handle))
+	eventBus.Subscribe(events.LeadDataChanged{}.EventName(), events.HandlerFunc(leadGeocoder.Handle))
+
 	module := &Module{
 		handler:               h,
 		publicHandler:         publicHandler,
@@ -199,6 +216,8 @@ func NewModule(ctx context.Context, pool *pgxpool.Pool, eventBus events.Bus, sto
 		offerSummaryGenerator: offerSummaryGenerator,
 		replyAgent:            replyAgent,
 		staleReEngagement:     staleReEngagement,
+		leadGeocoder:          leadGeocoder,
+		portalLinks:           portalLinks,
 		subsidyAnalyzerSvc:    nil, // Will be set after instantiation
 		sse:                   sseService,
 		eventBus:              eventBus,
@@ -229,7 +248,7 @@ func NewModule(ctx context.Context, pool *pgxpool.Pool, eventBus events.Bus, sto
 	subscribeLeadServiceAdded(eventBus, repo, module, log)
 	subscribeAttachmentUploaded(eventBus, repo, log)
 	if log != nil {
-		log.Info("leads module: event subscriptions registered", "subscriptions", "lead-created,lead-service-added,attachment-uploaded,orchestrator")
+		log.Info("leads module: event subscriptions registered", "subscriptions", "lead-created,lead-service-added,attachment-uploaded,lead-geocoding,orchestrator")
 	}
 
 	return module, nil
@@ -251,6 +270,14 @@ func (m *Module) StaleLeadReEngagement() *maintenance.StaleLeadReEngagementServi
 	return m.staleReEngagement
 }
 
+// LeadGeocoder returns the background lead geocoder, whose retry loop the API starts.
+func (m *Module) LeadGeocoder() *maintenance.LeadGeocoder {
+	if m == nil {
+		return nil
+	}
+	return m.leadGeocoder
+}
+
 func (m *Module) VerifyWiring() error {
 	if m == nil {
 		return fmt.Errorf("leads module: module is nil")
@@ -299,7 +326,7 @@ func buildRuntime(cfg *config.Config, repo repository.LeadsRepository, scorer *s
 	rt.SetQdrantClients(
 		buildQdrantClient(cfg),
 		buildScopedQdrantClient(cfg, cfg.GetBouwmaatEmbeddingCollection()),
-		buildScopedQdrantClient(cfg, cfg.GetCatalogEmbeddingCollection()),
+		buildCatalogQdrantClient(cfg),
 	)
 
 	callLogger, err := agent.NewCallLogger(resolveAgentModelConfig(cfg, config.LLMModelAgentCallLogger, true), repo, nil, eventBus, sessionService)
@@ -366,6 +393,20 @@ func buildScopedQdrantClient(cfg *config.Config, collection string) *qdrant.Clie
 	})
 }
 
+// buildCatalogQdrantClient builds the client for the tenant-owned catalog collection.
+func buildCatalogQdrantClient(cfg *config.Config) *qdrant.Client {
+	collection := cfg.GetCatalogEmbeddingCollection()
+	if cfg.GetQdrantURL() == "" || collection == "" {
+		return nil
+	}
+	return qdrant.NewClient(qdrant.Config{
+		BaseURL:     cfg.GetQdrantURL(),
+		APIKey:      cfg.GetQdrantAPIKey(),
+		Collection:  collection,
+		TenancyMode: cfg.GetQdrantTenancyMode(),
+	})
+}
+
 func buildReplyAgents(cfg *config.Config, repo repository.LeadsRepository, sessionService session.Service) (*agent.ReplyAgent, error) {
 	replyAgent, err := agent.NewReplyAgent("whatsapp", resolveAgentModelConfig(cfg, config.LLMModelAgentWhatsAppReply, false), repo, sessionService)
 	if err != nil {
@@ -614,6 +655,15 @@ func (m *Module) SetAppointmentBooker(booker ports.AppointmentBooker) {
 	m.callLogger.SetAppointmentBooker(booker)
 }
 
+// SetContactCipher enables encryption of consumer phone numbers and email
+// addresses.
+func (m *Module) SetContactCipher(cipher *repository.ContactCipher) {
+	if m == nil {
+		return
+	}
+	m.repo.SetContactCipher(cipher)
+}
+
 // SetCallLogScheduler injects the scheduler-backed queue for async call logging.
 func (m *Module) SetCallLogScheduler(queue scheduler.CallLogScheduler) {
 	if m == nil || m.handler == nil {
@@ -844,6 +894,21 @@ func (m *Module) SetReplyContextReaders(quoteReader ports.ReplyQuoteReader, appt
 	}
 }
 
+// SetPublicTokenLocator injects the lookup of the organization of public lead
+// tokens.
+func (m *Module) SetPublicTokenLocator(locator httpkit.PublicTokenResolver) {
+	if m.publicHandler == nil {
+		return
+	}
+	m.publicHandler.SetTokenLocator(locator)
+}
+
+// SetPortalLinkLocator injects the lookup of the organization of magic links,
+// by link ID.
+func (m *Module) SetPortalLinkLocator(locator httpkit.PublicTokenResolver) {
+	m.portalLinks.SetLinkLocator(locator)
+}
+
 // SetPublicOrgViewer injects organization contact info for the public portal.
 func (m *Module) SetPublicOrgViewer(orgViewer ports.OrganizationPublicViewer) {
 	if m.publicHandler == nil {
@@ -924,8 +989,9 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	ctx.Protected.GET("/events", m.sseHandler())
 
 	// Public lead portal routes (no auth middleware)
-	publicGroup := ctx.V1.Group("/public/leads")
+	publicGroup := ctx.Public.Group("/leads")
 	m.publicHandler.RegisterRoutes(publicGroup)
+	m.publicHandler.RegisterMarketingConfirmationRoutes(ctx.Public.Group("/marketing-confirmations"))
 }
 
 // sseHandler returns the SSE handler with user ID extraction
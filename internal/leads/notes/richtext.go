@@ -0,0 +1,189 @@
+package notes
+
+import (
+	"bytes"
+	htmlstd "html"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// attachmentRefPrefix is the src of an inline image showing a lead attachment,
+// e.g. <img src="attachment:6f1c…">.
+const attachmentRefPrefix = "attachment:"
+
+var noteAllowedTags = map[string]bool{
+	"p": true, "br": true, "strong": true, "b": true, "em": true, "i": true, "u": true, "s": true,
+	"ul": true, "ol": true, "li": true, "blockquote": true, "a": true, "img": true,
+}
+
+// noteDroppedTags are removed with their content; other unknown tags are
+// unwrapped.
+var noteDroppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"form": true, "input": true, "button": true, "select": true, "textarea": true,
+	"meta": true, "link": true, "base": true, "svg": true, "math": true, "template": true,
+}
+
+// SanitizeBody reduces a note body to the allow-listed HTML and returns the
+// attachments it embeds. Plain-text bodies are returned trimmed and unchanged.
+func SanitizeBody(body string) (string, []uuid.UUID) {
+	body = strings.TrimSpace(body)
+	if !isHTML(body) {
+		return body, nil
+	}
+	return sanitizeNoteHTML(body, false)
+}
+
+// RenderHTML returns the body as HTML that is safe to render. Plain text is
+// escaped with line breaks kept; inline images carry their attachment in
+// data-attachment-id instead of a src.
+func RenderHTML(body string) string {
+	body = strings.TrimSpace(body)
+	if !isHTML(body) {
+		return strings.ReplaceAll(htmlstd.EscapeString(body), "\n", "<br>")
+	}
+	rendered, _ := sanitizeNoteHTML(body, true)
+	return rendered
+}
+
+// AttachmentIDs returns the attachments embedded in the body.
+func AttachmentIDs(body string) []uuid.UUID {
+	_, ids := SanitizeBody(body)
+	return ids
+}
+
+// isHTML reports whether body contains markup, as opposed to text that
+// merely uses "<", e.g. "prijs < 500".
+func isHTML(body string) bool {
+	if !strings.Contains(body, "<") {
+		return false
+	}
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return false
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			return true
+		}
+	}
+}
+
+func sanitizeNoteHTML(body string, render bool) (string, []uuid.UUID) {
+	nodes, err := html.ParseFragment(strings.NewReader(body), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return htmlstd.EscapeString(body), nil
+	}
+
+	s := noteSanitizer{render: render, seen: make(map[uuid.UUID]bool)}
+	root := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	for _, node := range nodes {
+		s.appendClean(root, node)
+	}
+
+	var buf bytes.Buffer
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		_ = html.Render(&buf, child)
+	}
+	return strings.TrimSpace(buf.String()), s.attachments
+}
+
+type noteSanitizer struct {
+	render      bool
+	attachments []uuid.UUID
+	seen        map[uuid.UUID]bool
+}
+
+func (s *noteSanitizer) appendClean(parent, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		parent.AppendChild(&html.Node{Type: html.TextNode, Data: node.Data})
+	case html.ElementNode:
+		tag := strings.ToLower(node.Data)
+		if noteDroppedTags[tag] {
+			return
+		}
+		if !noteAllowedTags[tag] {
+			s.appendChildren(parent, node)
+			return
+		}
+		clean := &html.Node{Type: html.ElementNode, Data: tag}
+		switch tag {
+		case "a":
+			clean.Attr = noteLinkAttrs(node)
+		case "img":
+			attrs, ok := s.imageAttrs(node)
+			if !ok {
+				return
+			}
+			clean.Attr = attrs
+		}
+		parent.AppendChild(clean)
+		s.appendChildren(clean, node)
+	default:
+		s.appendChildren(parent, node)
+	}
+}
+
+func (s *noteSanitizer) appendChildren(parent, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		s.appendClean(parent, child)
+	}
+}
+
+// imageAttrs keeps images of lead attachments only; other sources could
+// load remote content.
+func (s *noteSanitizer) imageAttrs(node *html.Node) ([]html.Attribute, bool) {
+	src := strings.TrimSpace(attrValue(node, "src"))
+	if !strings.HasPrefix(src, attachmentRefPrefix) {
+		return nil, false
+	}
+	id, err := uuid.Parse(strings.TrimPrefix(src, attachmentRefPrefix))
+	if err != nil {
+		return nil, false
+	}
+	if !s.seen[id] {
+		s.seen[id] = true
+		s.attachments = append(s.attachments, id)
+	}
+
+	attrs := []html.Attribute{{Key: "src", Val: attachmentRefPrefix + id.String()}}
+	if s.render {
+		attrs = []html.Attribute{{Key: "data-attachment-id", Val: id.String()}}
+	}
+	if alt := strings.TrimSpace(attrValue(node, "alt")); alt != "" {
+		attrs = append(attrs, html.Attribute{Key: "alt", Val: alt})
+	}
+	return attrs, true
+}
+
+func noteLinkAttrs(node *html.Node) []html.Attribute {
+	href := strings.TrimSpace(attrValue(node, "href"))
+	parsed, err := url.Parse(href)
+	if err != nil || href == "" {
+		return nil
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https", "mailto", "tel":
+	default:
+		return nil
+	}
+	return []html.Attribute{
+		{Key: "href", Val: href},
+		{Key: "rel", Val: "noopener noreferrer nofollow"},
+		{Key: "target", Val: "_blank"},
+	}
+}
+
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+
+	"portal_final_backend/internal/leads/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestSanitizeBodyKeepsPlainTextUnchanged(t *testing.T) {
+	body, ids := SanitizeBody("  Klant belt terug als prijs < 500 & planning ok  ")
+	if body != "Klant belt terug als prijs < 500 & planning ok" || ids != nil {
+		t.Fatalf("SanitizeBody() = %q, %v", body, ids)
+	}
+}
+
+func TestSanitizeBodyAllowListsMarkup(t *testing.T) {
+	attachmentID := uuid.New()
+	input := `<p onclick="x()">Dak <strong>lekt</strong><script>alert(1)</script></p>` +
+		`<a href="javascript:alert(1)">link</a><a href="https://example.com">site</a>` +
+		`<img src="https://tracker.example/pixel.gif"><img src="attachment:` + attachmentID.String() + `" alt="Foto">` +
+		`<font color="red">rood</font>`
+
+	body, ids := SanitizeBody(input)
+	for _, unwanted := range []string{"onclick", "script", "alert", "javascript", "tracker", "font"} {
+		if strings.Contains(body, unwanted) {
+			t.Fatalf("SanitizeBody() kept %q in %q", unwanted, body)
+		}
+	}
+	for _, wanted := range []string{"<p>Dak <strong>lekt</strong></p>", `<a href="https://example.com" rel="noopener noreferrer nofollow" target="_blank">site</a>`, `<img src="attachment:` + attachmentID.String() + `" alt="Foto"/>`, "rood"} {
+		if !strings.Contains(body, wanted) {
+			t.Fatalf("SanitizeBody() = %q, want it to contain %q", body, wanted)
+		}
+	}
+	if len(ids) != 1 || ids[0] != attachmentID {
+		t.Fatalf("SanitizeBody() attachments = %v, want [%s]", ids, attachmentID)
+	}
+}
+
+func TestToLeadNoteResponseRendersSafeAndPlainBodies(t *testing.T) {
+	attachmentID := uuid.New()
+	resp := ToLeadNoteResponse(repository.LeadNote{Body: `<p>Zie <b>foto</b></p><img src="attachment:` + attachmentID.String() + `">`})
+	if want := `<p>Zie <b>foto</b></p><img data-attachment-id="` + attachmentID.String() + `"/>`; resp.BodyHTML != want {
+		t.Fatalf("BodyHTML = %q, want %q", resp.BodyHTML, want)
+	}
+	if resp.BodyText != "Zie *foto*" {
+		t.Fatalf("BodyText = %q, want %q", resp.BodyText, "Zie *foto*")
+	}
+
+	plain := ToLeadNoteResponse(repository.LeadNote{Body: "prijs < 500\nregel 2"})
+	if want := "prijs &lt; 500<br>regel 2"; plain.BodyHTML != want {
+		t.Fatalf("BodyHTML = %q, want %q", plain.BodyHTML, want)
+	}
+}
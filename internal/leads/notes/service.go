@@ -11,6 +11,7 @@ import (
 	"portal_final_backend/internal/leads/repository"
 	"portal_final_backend/internal/leads/transport"
 	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/sanitize"
 
 	"github.com/google/uuid"
 )
@@ -29,6 +30,8 @@ var ValidNoteTypes = map[string]bool{
 type Repository interface {
 	// LeadExistenceChecker
 	GetByID(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (repository.Lead, error)
+	GetAttachmentByID(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (repository.Attachment, error)
+	GetLeadServiceByID(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (repository.LeadService, error)
 	// NoteStore
 	CreateLeadNote(ctx context.Context, params repository.CreateLeadNoteParams) (repository.LeadNote, error)
 	ListLeadNotes(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) ([]repository.LeadNote, error)
@@ -44,10 +47,11 @@ func New(repo Repository) *Service {
 	return &Service{repo: repo}
 }
 
-// Add adds a new note to a lead.
+// Add adds a new note to a lead. HTML bodies are reduced to the allowed
+// markup; inline images must show attachments of the lead.
 func (s *Service) Add(ctx context.Context, leadID uuid.UUID, authorID uuid.UUID, tenantID uuid.UUID, req transport.CreateLeadNoteRequest) (transport.LeadNoteResponse, error) {
-	body := strings.TrimSpace(req.Body)
-	if body == "" || len(body) > 2000 {
+	body, attachmentIDs := SanitizeBody(req.Body)
+	if (sanitize.WhatsAppText(body) == "" && len(attachmentIDs) == 0) || len(body) > 2000 {
 		return transport.LeadNoteResponse{}, apperr.Validation("note body must be between 1 and 2000 characters")
 	}
 
@@ -76,6 +80,9 @@ func (s *Service) Add(ctx context.Context, leadID uuid.UUID, authorID uuid.UUID,
 		}
 		return transport.LeadNoteResponse{}, err
 	}
+	if err := s.requireLeadAttachments(ctx, leadID, tenantID, attachmentIDs); err != nil {
+		return transport.LeadNoteResponse{}, err
+	}
 
 	note, err := s.repo.CreateLeadNote(ctx, repository.CreateLeadNoteParams{
 		LeadID:         leadID,
@@ -89,7 +96,7 @@ func (s *Service) Add(ctx context.Context, leadID uuid.UUID, authorID uuid.UUID,
 		return transport.LeadNoteResponse{}, err
 	}
 
-	return toLeadNoteResponse(note), nil
+	return ToLeadNoteResponse(note), nil
 }
 
 // List retrieves all notes for a lead.
@@ -109,21 +116,47 @@ func (s *Service) List(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID
 
 	items := make([]transport.LeadNoteResponse, len(notesList))
 	for i, note := range notesList {
-		items[i] = toLeadNoteResponse(note)
+		items[i] = ToLeadNoteResponse(note)
 	}
 
 	return transport.LeadNotesResponse{Items: items}, nil
 }
 
-func toLeadNoteResponse(note repository.LeadNote) transport.LeadNoteResponse {
+// requireLeadAttachments checks that the embedded attachments belong to
+// services of the lead.
+func (s *Service) requireLeadAttachments(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID, attachmentIDs []uuid.UUID) error {
+	for _, id := range attachmentIDs {
+		attachment, err := s.repo.GetAttachmentByID(ctx, id, tenantID)
+		if errors.Is(err, repository.ErrAttachmentNotFound) {
+			return apperr.Validation("inline image references an unknown attachment")
+		}
+		if err != nil {
+			return err
+		}
+		service, err := s.repo.GetLeadServiceByID(ctx, attachment.LeadServiceID, tenantID)
+		if err != nil && !errors.Is(err, repository.ErrServiceNotFound) {
+			return err
+		}
+		if err != nil || service.LeadID != leadID {
+			return apperr.Validation("inline image references an attachment of another lead")
+		}
+	}
+	return nil
+}
+
+// ToLeadNoteResponse maps a note with its rendering-safe and plain-text body.
+func ToLeadNoteResponse(note repository.LeadNote) transport.LeadNoteResponse {
 	return transport.LeadNoteResponse{
-		ID:          note.ID,
-		LeadID:      note.LeadID,
-		AuthorID:    note.AuthorID,
-		AuthorEmail: note.AuthorEmail,
-		Type:        note.Type,
-		Body:        note.Body,
-		CreatedAt:   note.CreatedAt,
-		UpdatedAt:   note.UpdatedAt,
+		ID:            note.ID,
+		LeadID:        note.LeadID,
+		AuthorID:      note.AuthorID,
+		AuthorEmail:   note.AuthorEmail,
+		Type:          note.Type,
+		Body:          note.Body,
+		BodyHTML:      RenderHTML(note.Body),
+		BodyText:      note.PlainBody(),
+		AttachmentIDs: AttachmentIDs(note.Body),
+		CreatedAt:     note.CreatedAt,
+		UpdatedAt:     note.UpdatedAt,
 	}
 }
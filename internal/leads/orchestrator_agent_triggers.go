@@ -87,6 +87,41 @@ func (o *Orchestrator) maybeReRunEstimatorForNote(svc repository.LeadService, ev
 		o.log.Error("orchestrator: estimator re-run failed to enqueue", "serviceId", evt.LeadServiceID)
 	}
 }
+// maybeReRunEstimatorForMeasurements re-estimates a service when a field report
+// brings new measurements. The estimator fingerprint does not cover the visit
+// report, so the run is forced past the deduper.
+func (o *Orchestrator) maybeReRunEstimatorForMeasurements(ctx context.Context, svc repository.LeadService, evt events.VisitReportSubmitted) {
+	if svc.PipelineStage == domain.PipelineStageProposal || svc.PipelineStage == domain.PipelineStageFulfillment {
+		o.log.Info(orchestratorAutomationLog, "agent", "calculator", "decision", "skip", "reason", "quote_stage_reached", "serviceId", evt.LeadServiceID, "stage", svc.PipelineStage)
+		return
+	}
+
+	settings, err := o.loadOrgAISettings(ctx, evt.TenantID)
+	if err != nil {
+		o.log.Warn("orchestrator: skipping estimator re-run for measurements (settings load failed)", "tenantId", evt.TenantID, "error", err)
+		return
+	}
+	if !settings.AIAutoEstimate {
+		o.log.Info(orchestratorAutomationLog, "agent", "calculator", "decision", "skip", "reason", "auto_estimate_disabled", "tenantId", evt.TenantID, "serviceId", evt.LeadServiceID)
+		return
+	}
+
+	o.log.Info(orchestratorAutomationLog, "agent", "calculator", "decision", "enqueue", "reason", "visit_measurements_changed", "leadId", evt.LeadID, "serviceId", evt.LeadServiceID, "appointmentId", evt.AppointmentID)
+	if !maybeEnqueueEstimatorRun(estimatorEnqueueRequest{
+		ctx:       ctx,
+		repo:      o.repo,
+		deduper:   o.estimatorDeduper,
+		queue:     o.automationQueue,
+		log:       o.log,
+		leadID:    evt.LeadID,
+		serviceID: evt.LeadServiceID,
+		tenantID:  evt.TenantID,
+		force:     true,
+		source:    "visit_measurements",
+	}) {
+		o.log.Error("orchestrator: estimator re-run failed to enqueue", "serviceId", evt.LeadServiceID)
+	}
+}
 func (o *Orchestrator) maybeRunGatekeeperForDataChange(svc repository.LeadService, evt events.LeadDataChanged) {
 	if svc.PipelineStage == domain.PipelineStageManualIntervention {
 		o.log.Info(orchestratorAutomationLog, "agent", "gatekeeper", "decision", "skip", "reason", "manual_intervention_active", "serviceId", evt.LeadServiceID, "leadId", evt.LeadID)
@@ -38,6 +38,10 @@ func (o *Orchestrator) OnVisitReportSubmitted(ctx context.Context, evt events.Vi
 	}); err != nil {
 		o.log.Error("orchestrator: failed to enqueue visit report audit", "error", err, "serviceId", evt.LeadServiceID)
 	}
+
+	if evt.MeasurementsChanged {
+		o.maybeReRunEstimatorForMeasurements(ctx, svc, evt)
+	}
 }
 func (o *Orchestrator) OnStageChange(ctx context.Context, evt events.PipelineStageChanged) {
 	// Terminal stages never trigger agents
@@ -248,6 +252,34 @@ func (o *Orchestrator) OnQuoteAccepted(ctx context.Context, evt events.QuoteAcce
 		NewStage:      domain.PipelineStageFulfillment,
 	})
 }
+
+// OnWorkOrderConfirmed completes the lead service once the customer confirmed
+// the delivery of the work. The work orders module already recorded the
+// confirmation on the timeline.
+func (o *Orchestrator) OnWorkOrderConfirmed(ctx context.Context, evt events.WorkOrderConfirmed) {
+	svc, err := o.repo.GetLeadServiceByID(ctx, evt.LeadServiceID, evt.OrganizationID)
+	if err != nil {
+		o.log.Error("orchestrator: failed to load service for confirmed work order", "error", err, "serviceId", evt.LeadServiceID)
+		return
+	}
+	if domain.IsTerminal(svc.Status, svc.PipelineStage) {
+		return
+	}
+
+	if _, _, err := o.updateServiceState(ctx, evt.LeadServiceID, evt.OrganizationID, svc.Status, svc.PipelineStage, domain.LeadStatusCompleted, domain.PipelineStageCompleted); err != nil {
+		o.log.Error("orchestrator: failed to complete service after work order confirmation", "error", err)
+		return
+	}
+
+	o.eventBus.Publish(ctx, events.PipelineStageChanged{
+		BaseEvent:     events.NewBaseEvent(),
+		LeadID:        evt.LeadID,
+		LeadServiceID: evt.LeadServiceID,
+		TenantID:      evt.OrganizationID,
+		OldStage:      svc.PipelineStage,
+		NewStage:      domain.PipelineStageCompleted,
+	})
+}
 func (o *Orchestrator) OnQuoteRejected(ctx context.Context, evt events.QuoteRejected) {
 	if evt.LeadServiceID == nil {
 		return
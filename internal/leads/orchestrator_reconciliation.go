@@ -189,7 +189,17 @@ func (o *Orchestrator) applyReconciledState(ctx context.Context, p applyReconcil
 		return
 	}
 
-	stageChanged, statusChanged, err := o.updateServiceState(ctx, p.ServiceID, p.TenantID, oldStatus, oldStage, p.Desired.Status, p.Desired.Stage)
+	reason := p.Desired.Reason
+	if reason == "" {
+		reason = defaultReconcileReason(p.TriggerEvent, oldStage, p.Desired.Stage)
+	}
+
+	attributed := repository.WithStageTransitionActor(ctx, repository.StageTransitionActor{
+		Type:   repository.ActorTypeSystem,
+		Name:   repository.ActorNameStateReconciler,
+		Reason: reason,
+	})
+	stageChanged, statusChanged, err := o.updateServiceState(attributed, p.ServiceID, p.TenantID, oldStatus, oldStage, p.Desired.Status, p.Desired.Stage)
 	if err != nil {
 		o.log.Error("orchestrator: failed to apply reconciled state",
 			"error", err,
@@ -228,11 +238,6 @@ func (o *Orchestrator) applyReconciledState(ctx context.Context, p applyReconcil
 		})
 	}
 
-	reason := p.Desired.Reason
-	if reason == "" {
-		reason = defaultReconcileReason(p.TriggerEvent, oldStage, p.Desired.Stage)
-	}
-
 	o.maybeWriteReconcileTimeline(ctx, maybeWriteTimelineParams{
 		LeadID:       p.LeadID,
 		ServiceID:    p.ServiceID,
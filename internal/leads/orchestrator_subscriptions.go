@@ -19,6 +19,7 @@ func subscribeOrchestratorEvents(eventBus events.Bus, o *Orchestrator) {
 	eventBus.Subscribe(events.PartnerOfferAccepted{}.EventName(), typedHandler(o.OnPartnerOfferAccepted))
 	eventBus.Subscribe(events.PartnerOfferExpired{}.EventName(), typedHandler(o.OnPartnerOfferExpired))
 	eventBus.Subscribe(events.PartnerOfferDeleted{}.EventName(), typedHandler(o.OnPartnerOfferDeleted))
+	eventBus.Subscribe(events.WorkOrderConfirmed{}.EventName(), typedHandler(o.OnWorkOrderConfirmed))
 	eventBus.Subscribe(events.PipelineStageChanged{}.EventName(), typedHandler(func(ctx context.Context, evt events.PipelineStageChanged) {
 		o.OnStageChange(ctx, evt)
 	}))
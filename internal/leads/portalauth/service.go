@@ -0,0 +1,190 @@
+package portalauth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/leads/domain"
+	"portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// LinkTTL is how long a magic link stays valid after it is sent or last
+	// used.
+	LinkTTL = 7 * 24 * time.Hour
+	// LinkMaxLifetime caps how far repeated use can extend a magic link.
+	LinkMaxLifetime = 90 * 24 * time.Hour
+
+	ChannelEmail    = "email"
+	ChannelWhatsApp = "whatsapp"
+	// ChannelNone issues a link without sending it, e.g. to copy it manually.
+	ChannelNone = "none"
+
+	revokeReasonLeadClosed = "lead closed"
+	revokeReasonManual     = "revoked by user"
+)
+
+// Repository is the storage the service needs.
+type Repository interface {
+	CreatePortalLink(ctx context.Context, params repository.CreatePortalLinkParams) (repository.PortalLink, error)
+	UsePortalLink(ctx context.Context, id uuid.UUID, ttl time.Duration) (repository.PortalLink, error)
+	PortalLinkOrganization(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	RevokeLeadPortalAccess(ctx context.Context, leadID, organizationID uuid.UUID, reason string) (int64, error)
+	HasOpenLeadServices(ctx context.Context, leadID, organizationID uuid.UUID) (bool, error)
+}
+
+// IssuedLink is a freshly issued magic link.
+type IssuedLink struct {
+	Link  repository.PortalLink
+	Token string
+	URL   string
+}
+
+// Service issues, resolves and revokes lead portal magic links.
+type Service struct {
+	repo     Repository
+	signer   *Signer
+	eventBus events.Bus
+	log      *logger.Logger
+	baseURL  string
+	now      func() time.Time
+	// locateLink finds the organization of links in a dedicated data schema.
+	locateLink httpkit.PublicTokenResolver
+}
+
+// New creates a magic link service signing tokens with secret.
+func New(repo Repository, secret string, eventBus events.Bus, log *logger.Logger) *Service {
+	return &Service{repo: repo, signer: NewSigner(secret), eventBus: eventBus, log: log, now: time.Now}
+}
+
+// SetPublicBaseURL sets the frontend base URL portal links point to.
+func (s *Service) SetPublicBaseURL(url string) {
+	s.baseURL = strings.TrimRight(strings.TrimSpace(url), "/")
+}
+
+// SetLinkLocator sets the lookup of the organization of a link ID across the
+// data schemas of all organizations.
+func (s *Service) SetLinkLocator(locator httpkit.PublicTokenResolver) {
+	s.locateLink = locator
+}
+
+// Issue creates a magic link for a lead and, unless channel is ChannelNone,
+// asks for it to be sent to the lead.
+func (s *Service) Issue(ctx context.Context, leadID, organizationID uuid.UUID, channel string) (IssuedLink, error) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	switch channel {
+	case ChannelEmail, ChannelWhatsApp, ChannelNone:
+	default:
+		return IssuedLink{}, apperr.Validation("channel must be email, whatsapp or none")
+	}
+
+	now := s.now().UTC()
+	link, err := s.repo.CreatePortalLink(ctx, repository.CreatePortalLinkParams{
+		OrganizationID: organizationID,
+		LeadID:         leadID,
+		Channel:        channel,
+		ExpiresAt:      now.Add(LinkTTL),
+		MaxExpiresAt:   now.Add(LinkMaxLifetime),
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return IssuedLink{}, apperr.NotFound("lead not found")
+	}
+	if err != nil {
+		return IssuedLink{}, err
+	}
+
+	issued := IssuedLink{Link: link, Token: s.signer.Sign(link.ID)}
+	if s.baseURL != "" {
+		issued.URL = s.baseURL + "/track/" + issued.Token
+	}
+	if channel != ChannelNone && s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.LeadPortalLinkRequested{
+			BaseEvent: events.NewBaseEvent(),
+			LeadID:    leadID,
+			TenantID:  organizationID,
+			Channel:   channel,
+			Token:     issued.Token,
+			ExpiresAt: link.ExpiresAt,
+		})
+	}
+	return issued, nil
+}
+
+// Resolve verifies a magic link token and returns the link, extending its
+// expiry. Forged, expired and revoked tokens return ErrInvalidToken.
+func (s *Service) Resolve(ctx context.Context, token string) (repository.PortalLink, error) {
+	id, err := s.signer.Parse(token)
+	if err != nil {
+		return repository.PortalLink{}, err
+	}
+	link, err := s.repo.UsePortalLink(ctx, id, LinkTTL)
+	if errors.Is(err, repository.ErrPortalLinkInvalid) {
+		return repository.PortalLink{}, ErrInvalidToken
+	}
+	return link, err
+}
+
+// Organization returns the organization a valid magic link token belongs to,
+// without extending it, and false for invalid tokens.
+func (s *Service) Organization(ctx context.Context, token string) (uuid.UUID, bool, error) {
+	id, err := s.signer.Parse(token)
+	if err != nil {
+		return uuid.Nil, false, nil
+	}
+	if s.locateLink != nil {
+		// Check the link in the schema that holds it.
+		organizationID, ok, err := s.locateLink(ctx, id.String())
+		if err != nil || !ok {
+			return uuid.Nil, false, err
+		}
+		if ctx, err = httpkit.BindTenant(ctx, organizationID); err != nil {
+			return uuid.Nil, false, err
+		}
+	}
+	organizationID, err := s.repo.PortalLinkOrganization(ctx, id)
+	if errors.Is(err, repository.ErrPortalLinkInvalid) {
+		return uuid.Nil, false, nil
+	}
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	return organizationID, true, nil
+}
+
+// Revoke revokes all portal access of a lead.
+func (s *Service) Revoke(ctx context.Context, leadID, organizationID uuid.UUID) (int64, error) {
+	return s.repo.RevokeLeadPortalAccess(ctx, leadID, organizationID, revokeReasonManual)
+}
+
+// OnPipelineStageChanged revokes portal access once the last open service of
+// a lead reaches a terminal stage.
+func (s *Service) OnPipelineStageChanged(ctx context.Context, e events.PipelineStageChanged) {
+	if !domain.IsTerminalPipelineStage(e.NewStage) {
+		return
+	}
+	open, err := s.repo.HasOpenLeadServices(ctx, e.LeadID, e.TenantID)
+	if err != nil {
+		s.logError("failed to check open lead services for portal revocation", e.LeadID, err)
+		return
+	}
+	if open {
+		return
+	}
+	if _, err := s.repo.RevokeLeadPortalAccess(ctx, e.LeadID, e.TenantID, revokeReasonLeadClosed); err != nil {
+		s.logError("failed to revoke lead portal access", e.LeadID, err)
+	}
+}
+
+func (s *Service) logError(msg string, leadID uuid.UUID, err error) {
+	if s.log != nil {
+		s.log.Error(msg, "leadId", leadID, "error", err)
+	}
+}
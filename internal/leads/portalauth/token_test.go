@@ -0,0 +1,51 @@
+package portalauth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSignerRoundTrip(t *testing.T) {
+	signer := NewSigner("secret")
+	id := uuid.New()
+
+	token := signer.Sign(id)
+	if !IsMagicLinkToken(token) {
+		t.Fatalf("expected %q to be recognised as a magic link token", token)
+	}
+	got, err := signer.Parse(token)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got != id {
+		t.Fatalf("expected id %s, got %s", id, got)
+	}
+}
+
+func TestSignerRejectsForgedTokens(t *testing.T) {
+	signer := NewSigner("secret")
+	token := signer.Sign(uuid.New())
+	rawID, _, _ := strings.Cut(token, tokenSeparator)
+
+	cases := map[string]string{
+		"other secret":    NewSigner("other").Sign(uuid.New()),
+		"swapped id":      NewSigner("secret").Sign(uuid.New())[:len(rawID)] + token[len(rawID):],
+		"missing sig":     rawID,
+		"garbage":         "not.a-token",
+		"legacy token":    "c29tZS1sZWdhY3ktcHVibGljLXRva2VuLXZhbHVlLXh4eA",
+		"truncated sig":   token[:len(token)-4],
+		"empty signature": rawID + tokenSeparator,
+	}
+	for name, forged := range cases {
+		if _, err := signer.Parse(forged); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+
+	if IsMagicLinkToken(cases["legacy token"]) {
+		t.Fatal("expected legacy public tokens not to look like magic link tokens")
+	}
+}
@@ -39,6 +39,8 @@ type CatalogProductDetails struct {
 	Materials      []string          // human-readable material names
 	Documents      []CatalogDocument // product document assets (PDFs, specs)
 	URLs           []CatalogURL      // product URL assets (terms, links)
+	StockTracked   bool              // whether the organization tracks stock for the product
+	StockQuantity  float64           // current stock; only meaningful when StockTracked
 }
 
 // CatalogReader is the ACL interface through which the leads domain can look up
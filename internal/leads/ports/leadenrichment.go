@@ -1,6 +1,9 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // LeadEnrichmentData contains enrichment data relevant for RAC_leads.
 type LeadEnrichmentData struct {
@@ -31,6 +34,7 @@ type LeadEnrichmentData struct {
 	Stedelijkheid             *int
 
 	Confidence *float64
+	FetchedAt  time.Time // When the postcode data was fetched; older than the call when cached
 }
 
 // LeadEnricher enriches a lead with PDOK/CBS data.
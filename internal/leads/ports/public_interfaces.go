@@ -47,9 +47,18 @@ type PublicAppointmentSummary struct {
 	AssignedUserName *string    `json:"assignedUserName,omitempty"`
 }
 
-// OrganizationPublicViewer allows the lead portal to fetch organization contact info.
+// PublicBranding holds the brand colors of an organization as #RRGGBB values;
+// nil colors use the portal defaults.
+type PublicBranding struct {
+	PrimaryColor *string `json:"primaryColor,omitempty"`
+	AccentColor  *string `json:"accentColor,omitempty"`
+}
+
+// OrganizationPublicViewer allows the lead portal to fetch organization contact info
+// and branding.
 type OrganizationPublicViewer interface {
 	GetPublicPhone(ctx context.Context, organizationID uuid.UUID) (string, error)
+	GetPublicBranding(ctx context.Context, organizationID uuid.UUID) (PublicBranding, error)
 }
 
 // PublicTimeSlot represents a single public-facing available time slot.
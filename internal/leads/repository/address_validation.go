@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// LeadAddressValidation is the BAG validation result of a lead's intake address.
+type LeadAddressValidation struct {
+	LeadID                uuid.UUID
+	OrganizationID        uuid.UUID
+	IsValid               bool
+	Source                string
+	NummeraanduidingID    *string
+	AdresseerbaarObjectID *string
+	ValidatedAt           time.Time
+}
+
+func (r *Repository) UpsertLeadAddressValidation(ctx context.Context, v LeadAddressValidation) error {
+	source := v.Source
+	if source == "" {
+		source = "bag"
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_lead_address_validations (
+			lead_id, organization_id, is_valid, source, nummeraanduiding_id, adresseerbaar_object_id, validated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, now())
+		ON CONFLICT (lead_id) DO UPDATE SET
+			is_valid = EXCLUDED.is_valid,
+			source = EXCLUDED.source,
+			nummeraanduiding_id = EXCLUDED.nummeraanduiding_id,
+			adresseerbaar_object_id = EXCLUDED.adresseerbaar_object_id,
+			validated_at = now()
+	`, v.LeadID, v.OrganizationID, v.IsValid, source, v.NummeraanduidingID, v.AdresseerbaarObjectID)
+	if err != nil {
+		return fmt.Errorf("upsert lead address validation: %w", err)
+	}
+	return nil
+}
+
+// GetLeadAddressValidation returns (nil, nil) when the lead's address was never validated.
+func (r *Repository) GetLeadAddressValidation(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) (*LeadAddressValidation, error) {
+	var v LeadAddressValidation
+	err := r.pool.QueryRow(ctx, `
+		SELECT lead_id, organization_id, is_valid, source, nummeraanduiding_id, adresseerbaar_object_id, validated_at
+		FROM RAC_lead_address_validations
+		WHERE lead_id = $1 AND organization_id = $2
+	`, leadID, organizationID).Scan(&v.LeadID, &v.OrganizationID, &v.IsValid, &v.Source, &v.NummeraanduidingID, &v.AdresseerbaarObjectID, &v.ValidatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get lead address validation: %w", err)
+	}
+	return &v, nil
+}
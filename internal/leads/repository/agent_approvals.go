@@ -3,13 +3,16 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	leadsdb "portal_final_backend/internal/leads/db"
+	"portal_final_backend/platform/apperr"
 )
 
 // AgentApproval represents a human-in-the-loop confirmation request.
@@ -28,6 +31,11 @@ type AgentApproval struct {
 	ServiceID     *uuid.UUID
 	TenantID      uuid.UUID
 	CreatedAt     time.Time
+	// Impact is the review policy category that held the action for review;
+	// empty for actions held by the default confirmation rules.
+	Impact        string
+	// TimeoutAction decides what happens to the action when ExpiresAt passes.
+	TimeoutAction string
 }
 
 // CreateAgentApprovalParams is the input for creating an approval request.
@@ -96,33 +104,46 @@ func (r *Repository) CreateAgentApproval(ctx context.Context, params CreateAgent
 	return mapAgentApproval(row), nil
 }
 
-// ListPendingAgentApprovals returns pending approvals for a tenant.
+// ListPendingAgentApprovals returns the review queue of a tenant: pending
+// approvals, the ones closest to their SLA deadline first.
 func (r *Repository) ListPendingAgentApprovals(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]AgentApproval, error) {
-	rows, err := r.queries.ListPendingAgentApprovals(ctx, leadsdb.ListPendingAgentApprovalsParams{
-		TenantID: pgtype.UUID{Bytes: tenantID, Valid: true},
-		Limit:    int32(limit),
-		Offset:   int32(offset),
-	})
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+agentApprovalColumns+`
+		FROM agent_approvals
+		WHERE tenant_id = $1 AND decision = 'pending'
+		ORDER BY expires_at ASC NULLS LAST, requested_at ASC
+		LIMIT $2 OFFSET $3
+	`, tenantID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("list pending approvals: %w", err)
 	}
-	approvals := make([]AgentApproval, 0, len(rows))
-	for _, row := range rows {
-		approvals = append(approvals, mapAgentApproval(row))
-	}
-	return approvals, nil
+	defer rows.Close()
+
+	approvals := make([]AgentApproval, 0)
+	for rows.Next() {
+		a, err := scanAgentApproval(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan pending approval: %w", err)
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, rows.Err()
 }
 
 // GetAgentApprovalByID fetches a single approval by ID scoped to tenant.
 func (r *Repository) GetAgentApprovalByID(ctx context.Context, id, tenantID uuid.UUID) (AgentApproval, error) {
-	row, err := r.queries.GetAgentApprovalByID(ctx, leadsdb.GetAgentApprovalByIDParams{
-		ID:       pgtype.UUID{Bytes: id, Valid: true},
-		TenantID: pgtype.UUID{Bytes: tenantID, Valid: true},
-	})
+	a, err := scanAgentApproval(r.pool.QueryRow(ctx, `
+		SELECT `+agentApprovalColumns+`
+		FROM agent_approvals
+		WHERE id = $1 AND tenant_id = $2
+	`, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return AgentApproval{}, apperr.NotFound("approval not found")
+	}
 	if err != nil {
 		return AgentApproval{}, fmt.Errorf("get agent approval: %w", err)
 	}
-	return mapAgentApproval(row), nil
+	return a, nil
 }
 
 // UpdateAgentApprovalDecision resolves a pending approval.
@@ -145,6 +166,23 @@ func (r *Repository) CountPendingAgentApprovals(ctx context.Context, tenantID uu
 	return r.queries.CountPendingAgentApprovals(ctx, pgtype.UUID{Bytes: tenantID, Valid: true})
 }
 
+const agentApprovalColumns = `id, agent_name, tool_name, arguments_json, reason, requested_at, expires_at,
+	decision, decided_at, decided_by, lead_id, service_id, tenant_id, created_at, impact, timeout_action`
+
+func scanAgentApproval(row pgx.Row) (AgentApproval, error) {
+	var a AgentApproval
+	var argsJSON []byte
+	if err := row.Scan(&a.ID, &a.AgentName, &a.ToolName, &argsJSON, &a.Reason, &a.RequestedAt, &a.ExpiresAt,
+		&a.Decision, &a.DecidedAt, &a.DecidedBy, &a.LeadID, &a.ServiceID, &a.TenantID, &a.CreatedAt,
+		&a.Impact, &a.TimeoutAction); err != nil {
+		return AgentApproval{}, err
+	}
+	if argsJSON != nil {
+		_ = json.Unmarshal(argsJSON, &a.Arguments)
+	}
+	return a, nil
+}
+
 func mapAgentApproval(row leadsdb.AgentApproval) AgentApproval {
 	a := AgentApproval{
 		ID:          uuid.UUID(row.ID.Bytes),
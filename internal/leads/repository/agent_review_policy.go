@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Review timeout actions.
+const (
+	ReviewTimeoutReject  = "reject"
+	ReviewTimeoutApprove = "approve"
+)
+
+// expiredDecidedBy matches the marker the confirmation provider writes when a
+// review runs out of time.
+const expiredDecidedBy = "system:timeout"
+
+// AgentReviewPolicy decides which AI actions wait in the review queue.
+type AgentReviewPolicy struct {
+	OrganizationID         uuid.UUID
+	Enabled                bool
+	ReviewStageMoves       bool
+	ReviewPartnerOffers    bool
+	ReviewDetailOverwrites bool
+	SLAMinutes             int
+	TimeoutAction          string
+	UpdatedByID            *uuid.UUID
+	UpdatedAt              *time.Time
+}
+
+// DefaultAgentReviewPolicy is the policy of an organization that has not
+// configured one; it is disabled so the default confirmation rules apply.
+func DefaultAgentReviewPolicy(organizationID uuid.UUID) AgentReviewPolicy {
+	return AgentReviewPolicy{
+		OrganizationID:      organizationID,
+		ReviewStageMoves:    true,
+		ReviewPartnerOffers: true,
+		SLAMinutes:          5,
+		TimeoutAction:       ReviewTimeoutReject,
+	}
+}
+
+// GetAgentReviewPolicy returns the review policy of an organization, or the
+// default policy when none is stored.
+func (r *Repository) GetAgentReviewPolicy(ctx context.Context, organizationID uuid.UUID) (AgentReviewPolicy, error) {
+	p := AgentReviewPolicy{OrganizationID: organizationID}
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT enabled, review_stage_moves, review_partner_offers, review_detail_overwrites,
+			sla_minutes, timeout_action, updated_by_id, updated_at
+		FROM RAC_agent_review_policies
+		WHERE organization_id = $1`, organizationID).Scan(
+		&p.Enabled, &p.ReviewStageMoves, &p.ReviewPartnerOffers, &p.ReviewDetailOverwrites,
+		&p.SLAMinutes, &p.TimeoutAction, &p.UpdatedByID, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DefaultAgentReviewPolicy(organizationID), nil
+	}
+	if err != nil {
+		return AgentReviewPolicy{}, fmt.Errorf("get agent review policy: %w", err)
+	}
+	p.UpdatedAt = &updatedAt
+	return p, nil
+}
+
+// UpsertAgentReviewPolicy stores the review policy of an organization.
+func (r *Repository) UpsertAgentReviewPolicy(ctx context.Context, p AgentReviewPolicy) (AgentReviewPolicy, error) {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_agent_review_policies (
+			organization_id, enabled, review_stage_moves, review_partner_offers, review_detail_overwrites,
+			sla_minutes, timeout_action, updated_by_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			review_stage_moves = EXCLUDED.review_stage_moves,
+			review_partner_offers = EXCLUDED.review_partner_offers,
+			review_detail_overwrites = EXCLUDED.review_detail_overwrites,
+			sla_minutes = EXCLUDED.sla_minutes,
+			timeout_action = EXCLUDED.timeout_action,
+			updated_by_id = EXCLUDED.updated_by_id,
+			updated_at = now()`,
+		p.OrganizationID, p.Enabled, p.ReviewStageMoves, p.ReviewPartnerOffers, p.ReviewDetailOverwrites,
+		p.SLAMinutes, p.TimeoutAction, p.UpdatedByID)
+	if err != nil {
+		return AgentReviewPolicy{}, fmt.Errorf("upsert agent review policy: %w", err)
+	}
+	return r.GetAgentReviewPolicy(ctx, p.OrganizationID)
+}
+
+// ExpireOverdueAgentApprovals marks pending approvals past their SLA deadline as
+// expired. Waiting agent runs expire their own requests; this catches requests
+// whose run ended before the deadline, so they leave the review queue.
+func (r *Repository) ExpireOverdueAgentApprovals(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE agent_approvals
+		SET decision = 'expired', decided_at = $1, decided_by = $2
+		WHERE decision = 'pending' AND expires_at IS NOT NULL AND expires_at <= $1`, now, expiredDecidedBy)
+	if err != nil {
+		return 0, fmt.Errorf("expire overdue agent approvals: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
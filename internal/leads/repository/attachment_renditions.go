@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Rendition variants of image attachments.
+const (
+	RenditionWeb       = "web"
+	RenditionThumbnail = "thumbnail"
+)
+
+// Image processing states of an attachment.
+const (
+	ImageProcessingProcessed = "processed"
+	ImageProcessingSkipped   = "skipped"
+	ImageProcessingFailed    = "failed"
+)
+
+var ErrAttachmentRenditionNotFound = errors.New("attachment rendition not found")
+
+// AttachmentRendition is a downscaled copy of an image attachment.
+type AttachmentRendition struct {
+	AttachmentID   uuid.UUID
+	Variant        string
+	OrganizationID uuid.UUID
+	FileKey        string
+	ContentType    string
+	Width          int
+	Height         int
+	SizeBytes      int64
+	CreatedAt      time.Time
+}
+
+const renditionColumns = `attachment_id, variant, organization_id, file_key, content_type, width, height, size_bytes, created_at`
+
+func scanRendition(row pgx.Row) (AttachmentRendition, error) {
+	var r AttachmentRendition
+	err := row.Scan(&r.AttachmentID, &r.Variant, &r.OrganizationID, &r.FileKey, &r.ContentType, &r.Width, &r.Height, &r.SizeBytes, &r.CreatedAt)
+	return r, err
+}
+
+// GetAttachmentRendition returns one rendition of an attachment.
+func (r *Repository) GetAttachmentRendition(ctx context.Context, attachmentID uuid.UUID, organizationID uuid.UUID, variant string) (AttachmentRendition, error) {
+	rendition, err := scanRendition(r.pool.QueryRow(ctx, `
+		SELECT `+renditionColumns+`
+		FROM RAC_lead_attachment_renditions
+		WHERE attachment_id = $1 AND organization_id = $2 AND variant = $3`, attachmentID, organizationID, variant,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return AttachmentRendition{}, ErrAttachmentRenditionNotFound
+	}
+	if err != nil {
+		return AttachmentRendition{}, fmt.Errorf("get attachment rendition: %w", err)
+	}
+	return rendition, nil
+}
+
+// ListAttachmentRenditions returns the renditions of the given attachments,
+// grouped by attachment.
+func (r *Repository) ListAttachmentRenditions(ctx context.Context, attachmentIDs []uuid.UUID, organizationID uuid.UUID) (map[uuid.UUID][]AttachmentRendition, error) {
+	result := make(map[uuid.UUID][]AttachmentRendition)
+	if len(attachmentIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+renditionColumns+`
+		FROM RAC_lead_attachment_renditions
+		WHERE attachment_id = ANY($1) AND organization_id = $2
+		ORDER BY attachment_id, width DESC`, attachmentIDs, organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list attachment renditions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rendition, err := scanRendition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan attachment rendition: %w", err)
+		}
+		result[rendition.AttachmentID] = append(result[rendition.AttachmentID], rendition)
+	}
+	return result, rows.Err()
+}
+
+// ListAttachmentsPendingRenditions returns image attachments that were not yet
+// processed, or whose processing failed fewer than maxAttempts times, newest first.
+func (r *Repository) ListAttachmentsPendingRenditions(ctx context.Context, maxAttempts int, limit int) ([]Attachment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT a.id, a.lead_service_id, a.organization_id, a.file_key, a.file_name, a.content_type, a.size_bytes, a.uploaded_by, a.created_at
+		FROM RAC_lead_service_attachments a
+		LEFT JOIN RAC_lead_attachment_image_processing p ON p.attachment_id = a.id
+		WHERE lower(a.content_type) IN ('image/jpeg', 'image/jpg', 'image/png', 'image/gif')
+			AND (p.attachment_id IS NULL OR (p.status = 'failed' AND p.attempts < $1))
+		ORDER BY a.created_at DESC
+		LIMIT $2`, maxAttempts, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list attachments pending renditions: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Attachment, 0)
+	for rows.Next() {
+		var att Attachment
+		var createdAt *time.Time
+		if err := rows.Scan(&att.ID, &att.LeadServiceID, &att.OrganizationID, &att.FileKey, &att.FileName, &att.ContentType, &att.SizeBytes, &att.UploadedBy, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan attachment: %w", err)
+		}
+		if createdAt != nil {
+			att.CreatedAt = *createdAt
+		}
+		items = append(items, att)
+	}
+	return items, rows.Err()
+}
+
+// SaveAttachmentRendition stores a rendition, replacing an earlier one of the same
+// variant. It returns the file key of the replaced rendition, if any, so the
+// caller can remove the old object.
+func (r *Repository) SaveAttachmentRendition(ctx context.Context, rendition AttachmentRendition) (string, error) {
+	var replacedKey *string
+	err := r.pool.QueryRow(ctx, `
+		WITH previous AS (
+			SELECT file_key FROM RAC_lead_attachment_renditions WHERE attachment_id = $1 AND variant = $2
+		)
+		INSERT INTO RAC_lead_attachment_renditions (attachment_id, variant, organization_id, file_key, content_type, width, height, size_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (attachment_id, variant) DO UPDATE
+		SET file_key = EXCLUDED.file_key, content_type = EXCLUDED.content_type, width = EXCLUDED.width,
+			height = EXCLUDED.height, size_bytes = EXCLUDED.size_bytes, created_at = now()
+		RETURNING (SELECT file_key FROM previous)`,
+		rendition.AttachmentID, rendition.Variant, rendition.OrganizationID, rendition.FileKey, rendition.ContentType,
+		rendition.Width, rendition.Height, rendition.SizeBytes,
+	).Scan(&replacedKey)
+	if err != nil {
+		return "", fmt.Errorf("save attachment rendition: %w", err)
+	}
+	if replacedKey == nil || *replacedKey == rendition.FileKey {
+		return "", nil
+	}
+	return *replacedKey, nil
+}
+
+// MarkAttachmentImageProcessing records the outcome of rendering an attachment.
+// Failed attempts are counted so the sweep gives up on broken files.
+func (r *Repository) MarkAttachmentImageProcessing(ctx context.Context, attachmentID uuid.UUID, status string, lastError *string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_lead_attachment_image_processing (attachment_id, status, last_error)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (attachment_id) DO UPDATE
+		SET status = EXCLUDED.status, last_error = EXCLUDED.last_error, updated_at = now(),
+			attempts = RAC_lead_attachment_image_processing.attempts + 1`,
+		attachmentID, status, lastError,
+	)
+	if err != nil {
+		return fmt.Errorf("mark attachment image processing: %w", err)
+	}
+	return nil
+}
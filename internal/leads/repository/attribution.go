@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// The fbclid column was added after the generated lead queries, so it is
+// written and read separately from the lead row.
+
+func setLeadFBCLID(ctx context.Context, tx pgx.Tx, leadID, organizationID uuid.UUID, fbclid string) error {
+	_, err := tx.Exec(ctx, `UPDATE RAC_leads SET fbclid = $3 WHERE id = $1 AND organization_id = $2`, leadID, organizationID, fbclid)
+	return err
+}
+
+func getLeadFBCLID(ctx context.Context, tx pgx.Tx, leadID, organizationID uuid.UUID) (*string, error) {
+	var fbclid *string
+	err := tx.QueryRow(ctx, `SELECT fbclid FROM RAC_leads WHERE id = $1 AND organization_id = $2`, leadID, organizationID).Scan(&fbclid)
+	if err != nil {
+		return nil, err
+	}
+	return fbclid, nil
+}
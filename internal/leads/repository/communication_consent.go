@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Consent kinds a lead can grant or withdraw: the channels messages are sent
+// over and the purposes they are sent for.
+const (
+	ConsentEmail         = "email"
+	ConsentWhatsApp      = "whatsapp"
+	ConsentPhone         = "phone"
+	ConsentTransactional = "transactional"
+	ConsentMarketing     = "marketing"
+)
+
+// Sources of a consent change.
+const (
+	ConsentSourcePreferenceCenter = "preference_center"
+	ConsentSourceAgent            = "agent"
+	ConsentSourceDoubleOptIn      = "double_opt_in"
+)
+
+// CommunicationConsent is the channel and purpose consent of a lead. A lead
+// that never changed its preferences has given every consent except WhatsApp,
+// which follows the explicit opt-in of the lead. Marketing consent only
+// counts once the lead confirmed it (double opt-in).
+type CommunicationConsent struct {
+	Email                bool
+	WhatsApp             bool
+	Phone                bool
+	Transactional        bool
+	Marketing            bool
+	MarketingConfirmedAt *time.Time
+	UpdatedAt            *time.Time
+}
+
+// MarketingUnconfirmed reports whether the lead allows marketing but has not
+// confirmed it yet.
+func (c CommunicationConsent) MarketingUnconfirmed() bool {
+	return c.Marketing && c.MarketingConfirmedAt == nil
+}
+
+// Allows reports whether a message for the purpose may be sent to the lead
+// over the channel. Unknown channels and purposes are not restricted.
+func (c CommunicationConsent) Allows(channel string, purpose string) bool {
+	switch channel {
+	case ConsentEmail:
+		if !c.Email {
+			return false
+		}
+	case ConsentWhatsApp:
+		if !c.WhatsApp {
+			return false
+		}
+	case ConsentPhone:
+		if !c.Phone {
+			return false
+		}
+	}
+	switch purpose {
+	case ConsentTransactional:
+		return c.Transactional
+	case ConsentMarketing:
+		return c.Marketing && c.MarketingConfirmedAt != nil
+	}
+	return true
+}
+
+// CommunicationConsentPatch changes the consents that are set and keeps the
+// others.
+type CommunicationConsentPatch struct {
+	Email         *bool
+	WhatsApp      *bool
+	Phone         *bool
+	Transactional *bool
+	Marketing     *bool
+}
+
+// Apply returns the consent with the patch applied and the changes it made.
+func (c CommunicationConsent) Apply(patch CommunicationConsentPatch) (CommunicationConsent, []ConsentChange) {
+	var changes []ConsentChange
+	set := func(kind string, current *bool, value *bool) {
+		if value == nil || *current == *value {
+			return
+		}
+		*current = *value
+		changes = append(changes, ConsentChange{Consent: kind, Granted: *value})
+	}
+	set(ConsentEmail, &c.Email, patch.Email)
+	set(ConsentWhatsApp, &c.WhatsApp, patch.WhatsApp)
+	set(ConsentPhone, &c.Phone, patch.Phone)
+	set(ConsentTransactional, &c.Transactional, patch.Transactional)
+	set(ConsentMarketing, &c.Marketing, patch.Marketing)
+	return c, changes
+}
+
+// ConsentChange is an audit record of a consent being granted or withdrawn.
+type ConsentChange struct {
+	ID          uuid.UUID
+	Consent     string
+	Granted     bool
+	Source      string
+	ActorUserID *uuid.UUID
+	IPAddress   *string
+	UserAgent   *string
+	CreatedAt   time.Time
+}
+
+// ConsentChangeOrigin identifies who changed a consent and from where.
+type ConsentChangeOrigin struct {
+	Source      string
+	ActorUserID *uuid.UUID
+	IPAddress   string
+	UserAgent   string
+}
+
+const communicationConsentSelect = `
+	SELECT l.whatsapp_opted_in,
+		l.marketing_confirmed_at,
+		COALESCE(c.email_allowed, true),
+		COALESCE(c.phone_allowed, true),
+		COALESCE(c.transactional_allowed, true),
+		COALESCE(c.marketing_allowed, true),
+		c.updated_at
+	FROM RAC_leads l
+	LEFT JOIN RAC_lead_communication_consent c ON c.lead_id = l.id
+	WHERE l.id = $1 AND l.organization_id = $2 AND l.deleted_at IS NULL`
+
+func scanCommunicationConsent(row pgx.Row) (CommunicationConsent, error) {
+	var consent CommunicationConsent
+	err := row.Scan(&consent.WhatsApp, &consent.MarketingConfirmedAt, &consent.Email, &consent.Phone, &consent.Transactional, &consent.Marketing, &consent.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return CommunicationConsent{}, ErrNotFound
+	}
+	if err != nil {
+		return CommunicationConsent{}, fmt.Errorf("get lead communication consent: %w", err)
+	}
+	return consent, nil
+}
+
+// GetCommunicationConsent returns the channel and purpose consent of a lead.
+func (r *Repository) GetCommunicationConsent(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) (CommunicationConsent, error) {
+	return scanCommunicationConsent(r.pool.QueryRow(ctx, communicationConsentSelect, leadID, organizationID))
+}
+
+// UpdateCommunicationConsent applies the patch to the consent of a lead and
+// records an audit entry for every consent that changed.
+func (r *Repository) UpdateCommunicationConsent(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, patch CommunicationConsentPatch, origin ConsentChangeOrigin) (CommunicationConsent, []ConsentChange, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return CommunicationConsent{}, nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, err := scanCommunicationConsent(tx.QueryRow(ctx, communicationConsentSelect+` FOR UPDATE OF l`, leadID, organizationID))
+	if err != nil {
+		return CommunicationConsent{}, nil, err
+	}
+	updated, changes := current.Apply(patch)
+	if len(changes) == 0 {
+		return current, nil, nil
+	}
+
+	if !updated.Marketing && current.Marketing {
+		// A withdrawn marketing consent has to be confirmed again when granted anew.
+		if _, err := tx.Exec(ctx, `
+			UPDATE RAC_leads SET marketing_confirmed_at = NULL, marketing_confirmation_token = NULL, marketing_confirmation_requested_at = NULL
+			WHERE id = $1 AND organization_id = $2`, leadID, organizationID,
+		); err != nil {
+			return CommunicationConsent{}, nil, fmt.Errorf("reset lead marketing confirmation: %w", err)
+		}
+		updated.MarketingConfirmedAt = nil
+	}
+	if updated.WhatsApp != current.WhatsApp {
+		if _, err := tx.Exec(ctx, `
+			UPDATE RAC_leads SET whatsapp_opted_in = $3, updated_at = now()
+			WHERE id = $1 AND organization_id = $2`, leadID, organizationID, updated.WhatsApp,
+		); err != nil {
+			return CommunicationConsent{}, nil, fmt.Errorf("update lead whatsapp consent: %w", err)
+		}
+	}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO RAC_lead_communication_consent (
+			lead_id, organization_id, email_allowed, phone_allowed, transactional_allowed, marketing_allowed
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (lead_id) DO UPDATE SET
+			email_allowed = EXCLUDED.email_allowed,
+			phone_allowed = EXCLUDED.phone_allowed,
+			transactional_allowed = EXCLUDED.transactional_allowed,
+			marketing_allowed = EXCLUDED.marketing_allowed,
+			updated_at = now()
+		RETURNING updated_at`,
+		leadID, organizationID, updated.Email, updated.Phone, updated.Transactional, updated.Marketing,
+	).Scan(&updated.UpdatedAt); err != nil {
+		return CommunicationConsent{}, nil, fmt.Errorf("upsert lead communication consent: %w", err)
+	}
+
+	changes, err = insertConsentChanges(ctx, tx, leadID, organizationID, changes, origin)
+	if err != nil {
+		return CommunicationConsent{}, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return CommunicationConsent{}, nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return updated, changes, nil
+}
+
+// RecordConsentChanges records consent changes made outside the preference
+// center, such as an agent toggling WhatsApp on the lead itself.
+func (r *Repository) RecordConsentChanges(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, changes []ConsentChange, origin ConsentChangeOrigin) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := insertConsentChanges(ctx, tx, leadID, organizationID, changes, origin); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func insertConsentChanges(ctx context.Context, tx pgx.Tx, leadID uuid.UUID, organizationID uuid.UUID, changes []ConsentChange, origin ConsentChangeOrigin) ([]ConsentChange, error) {
+	recorded := make([]ConsentChange, 0, len(changes))
+	for _, change := range changes {
+		change.Source = origin.Source
+		change.ActorUserID = origin.ActorUserID
+		change.IPAddress = nullableText(origin.IPAddress)
+		change.UserAgent = nullableText(origin.UserAgent)
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO RAC_lead_consent_changes (
+				organization_id, lead_id, consent, granted, source, actor_user_id, ip_address, user_agent
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, created_at`,
+			organizationID, leadID, change.Consent, change.Granted, change.Source, change.ActorUserID, change.IPAddress, change.UserAgent,
+		).Scan(&change.ID, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("insert lead consent change: %w", err)
+		}
+		recorded = append(recorded, change)
+	}
+	return recorded, nil
+}
+
+// ListConsentChanges returns the consent history of a lead, newest first.
+func (r *Repository) ListConsentChanges(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, limit int) ([]ConsentChange, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, consent, granted, source, actor_user_id, ip_address, user_agent, created_at
+		FROM RAC_lead_consent_changes
+		WHERE lead_id = $1 AND organization_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3`, leadID, organizationID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list lead consent changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]ConsentChange, 0)
+	for rows.Next() {
+		var change ConsentChange
+		if err := rows.Scan(&change.ID, &change.Consent, &change.Granted, &change.Source, &change.ActorUserID, &change.IPAddress, &change.UserAgent, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan lead consent change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func nullableText(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommunicationConsentAllows(t *testing.T) {
+	consent := CommunicationConsent{Email: true, WhatsApp: false, Phone: true, Transactional: true, Marketing: false}
+
+	tests := []struct {
+		channel string
+		purpose string
+		want    bool
+	}{
+		{ConsentEmail, ConsentTransactional, true},
+		{ConsentEmail, ConsentMarketing, false},
+		{ConsentWhatsApp, ConsentTransactional, false},
+		{ConsentPhone, ConsentTransactional, true},
+		{"sms", ConsentTransactional, true},
+	}
+	for _, tt := range tests {
+		if got := consent.Allows(tt.channel, tt.purpose); got != tt.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", tt.channel, tt.purpose, got, tt.want)
+		}
+	}
+}
+
+func TestCommunicationConsentApplyReportsChanges(t *testing.T) {
+	granted, withdrawn := true, false
+	consent := CommunicationConsent{Email: true, WhatsApp: false, Phone: true, Transactional: true, Marketing: true}
+
+	updated, changes := consent.Apply(CommunicationConsentPatch{Email: &granted, WhatsApp: &granted, Marketing: &withdrawn})
+	if !updated.WhatsApp || updated.Marketing || !updated.Email {
+		t.Fatalf("unexpected consent after apply: %+v", updated)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Consent != ConsentWhatsApp || !changes[0].Granted {
+		t.Errorf("expected whatsapp granted, got %+v", changes[0])
+	}
+	if changes[1].Consent != ConsentMarketing || changes[1].Granted {
+		t.Errorf("expected marketing withdrawn, got %+v", changes[1])
+	}
+}
+
+func TestCommunicationConsentMarketingNeedsConfirmation(t *testing.T) {
+	consent := CommunicationConsent{Email: true, Transactional: true, Marketing: true}
+	if consent.Allows(ConsentEmail, ConsentMarketing) {
+		t.Fatal("expected unconfirmed marketing consent to withhold marketing messages")
+	}
+	if !consent.MarketingUnconfirmed() {
+		t.Fatal("expected marketing consent to be unconfirmed")
+	}
+
+	confirmedAt := time.Now()
+	consent.MarketingConfirmedAt = &confirmedAt
+	if !consent.Allows(ConsentEmail, ConsentMarketing) {
+		t.Fatal("expected confirmed marketing consent to allow marketing messages")
+	}
+	if consent.MarketingUnconfirmed() {
+		t.Fatal("expected marketing consent to be confirmed")
+	}
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"portal_final_backend/platform/phone"
+	"portal_final_backend/platform/secrets"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Consumer phone numbers and email addresses are stored encrypted, with HMAC
+// blind indexes for exact lookups. The plaintext columns are kept next to the
+// sealed copy until every reader outside this package decrypts: quotes,
+// appointments, partner offers, notifications, exports and search still read
+// them directly. Reads here decrypt the sealed copy where plaintext is missing.
+
+// ContactCipher encrypts consumer contact details and derives their blind
+// indexes. A nil *ContactCipher means contact encryption is not configured.
+type ContactCipher struct {
+	keyring  *secrets.Keyring
+	indexKey []byte
+}
+
+// NewContactCipher returns the cipher for the given keyring and blind index
+// key, or nil when either is missing. The blind index key must never change,
+// or existing indexes stop matching until the backfill command is rerun.
+func NewContactCipher(keyring *secrets.Keyring, blindIndexKey []byte) *ContactCipher {
+	if keyring == nil || len(blindIndexKey) == 0 {
+		return nil
+	}
+	return &ContactCipher{keyring: keyring, indexKey: blindIndexKey}
+}
+
+// PrimaryID returns the ID of the key new contact details are sealed with.
+func (c *ContactCipher) PrimaryID() string {
+	if c == nil {
+		return ""
+	}
+	return c.keyring.PrimaryID()
+}
+
+// PhoneBlindIndex returns the blind index of a phone number, or "" when
+// contact encryption is not configured or the number is empty.
+func (c *ContactCipher) PhoneBlindIndex(value string) string {
+	if c == nil {
+		return ""
+	}
+	return contactBlindIndex(c.indexKey, "phone", normalizeContactPhone(value))
+}
+
+// EmailBlindIndex returns the blind index of an email address, or "" when
+// contact encryption is not configured or the address is empty.
+func (c *ContactCipher) EmailBlindIndex(value string) string {
+	if c == nil {
+		return ""
+	}
+	return contactBlindIndex(c.indexKey, "email", normalizeContactEmail(value))
+}
+
+// SetContactCipher enables encryption of consumer contact details.
+func (r *Repository) SetContactCipher(cipher *ContactCipher) {
+	r.contact = cipher
+}
+
+func normalizeContactPhone(value string) string {
+	return phone.NormalizeE164(value)
+}
+
+func normalizeContactEmail(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// contactBlindIndex is a keyed hash of a normalized value. The kind prefix
+// keeps a phone and an email with the same text from sharing an index.
+func contactBlindIndex(key []byte, kind, normalized string) string {
+	if len(key) == 0 || normalized == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(kind + ":" + normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sealedContact holds the encrypted columns of a lead's contact details.
+type sealedContact struct {
+	phone          string
+	email          *string
+	phoneEncrypted *string
+	emailEncrypted *string
+	keyID          string
+	phoneIndex     *string
+	emailIndex     *string
+}
+
+func (c *ContactCipher) seal(consumerPhone string, consumerEmail *string) (sealedContact, error) {
+	sealed := sealedContact{phone: consumerPhone, email: consumerEmail, keyID: c.keyring.PrimaryID()}
+	if consumerPhone != "" {
+		encrypted, err := c.keyring.Encrypt(consumerPhone)
+		if err != nil {
+			return sealedContact{}, fmt.Errorf("encrypt consumer phone: %w", err)
+		}
+		sealed.phoneEncrypted = &encrypted
+		sealed.phoneIndex = nonEmptyStringPtr(c.PhoneBlindIndex(consumerPhone))
+	}
+	if consumerEmail != nil && *consumerEmail != "" {
+		encrypted, err := c.keyring.Encrypt(*consumerEmail)
+		if err != nil {
+			return sealedContact{}, fmt.Errorf("encrypt consumer email: %w", err)
+		}
+		sealed.emailEncrypted = &encrypted
+		sealed.emailIndex = nonEmptyStringPtr(c.EmailBlindIndex(*consumerEmail))
+	}
+	return sealed, nil
+}
+
+// open decrypts a sealed phone number and email address. Missing values stay
+// empty.
+func (c *ContactCipher) open(phoneEncrypted, emailEncrypted *string) (string, *string, error) {
+	var consumerPhone string
+	var consumerEmail *string
+	if phoneEncrypted != nil {
+		value, err := c.keyring.Decrypt(*phoneEncrypted)
+		if err != nil {
+			return "", nil, fmt.Errorf("decrypt consumer phone: %w", err)
+		}
+		consumerPhone = value
+	}
+	if emailEncrypted != nil {
+		value, err := c.keyring.Decrypt(*emailEncrypted)
+		if err != nil {
+			return "", nil, fmt.Errorf("decrypt consumer email: %w", err)
+		}
+		consumerEmail = &value
+	}
+	return consumerPhone, consumerEmail, nil
+}
+
+func nonEmptyStringPtr(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// writeLeadContact seals the contact details of a lead. It does nothing when
+// contact encryption is not configured.
+func (r *Repository) writeLeadContact(ctx context.Context, tx pgx.Tx, leadID, organizationID uuid.UUID, consumerPhone string, consumerEmail *string) error {
+	if r.contact == nil {
+		return nil
+	}
+	sealed, err := r.contact.seal(consumerPhone, consumerEmail)
+	if err != nil {
+		return err
+	}
+	return updateLeadContact(ctx, tx, leadID, organizationID, sealed)
+}
+
+func updateLeadContact(ctx context.Context, tx pgx.Tx, leadID, organizationID uuid.UUID, sealed sealedContact) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_leads
+		SET consumer_phone = $8,
+			consumer_email = $9,
+			consumer_phone_encrypted = $3,
+			consumer_email_encrypted = $4,
+			contact_key_id = $5,
+			consumer_phone_bidx = $6,
+			consumer_email_bidx = $7
+		WHERE id = $1 AND organization_id = $2`,
+		leadID, organizationID, sealed.phoneEncrypted, sealed.emailEncrypted, sealed.keyID, sealed.phoneIndex, sealed.emailIndex, sealed.phone, sealed.email,
+	); err != nil {
+		return fmt.Errorf("store encrypted lead contact: %w", err)
+	}
+	return nil
+}
+
+// contactQuerier is satisfied by both the pool and a transaction.
+type contactQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type openedContact struct {
+	phone string
+	email *string
+}
+
+// openContacts decrypts the sealed contact details of the given leads, keyed
+// by lead ID. Leads that were never sealed are left out.
+func (r *Repository) openContacts(ctx context.Context, q contactQuerier, ids []uuid.UUID) (map[uuid.UUID]openedContact, error) {
+	if r.contact == nil || len(ids) == 0 {
+		return nil, nil
+	}
+	rows, err := q.Query(ctx, `
+		SELECT id, consumer_phone_encrypted, consumer_email_encrypted
+		FROM RAC_leads
+		WHERE id = ANY($1) AND contact_key_id IS NOT NULL`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load encrypted lead contacts: %w", err)
+	}
+	defer rows.Close()
+
+	contacts := make(map[uuid.UUID]openedContact, len(ids))
+	for rows.Next() {
+		var (
+			id                             uuid.UUID
+			phoneEncrypted, emailEncrypted *string
+		)
+		if err := rows.Scan(&id, &phoneEncrypted, &emailEncrypted); err != nil {
+			return nil, fmt.Errorf("scan encrypted lead contact: %w", err)
+		}
+		phone, email, err := r.contact.open(phoneEncrypted, emailEncrypted)
+		if err != nil {
+			return nil, err
+		}
+		contacts[id] = openedContact{phone: phone, email: email}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("load encrypted lead contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+// openLeadContacts fills in the decrypted contact details of sealed leads.
+// Plaintext that is still present wins, so rows written before sealing or by
+// code outside this package keep their current value.
+func (r *Repository) openLeadContacts(ctx context.Context, q contactQuerier, leads ...*Lead) error {
+	if r.contact == nil || len(leads) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(leads))
+	for i, lead := range leads {
+		ids[i] = lead.ID
+	}
+	contacts, err := r.openContacts(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+	for _, lead := range leads {
+		if contact, ok := contacts[lead.ID]; ok {
+			contact.fill(&lead.ConsumerPhone, &lead.ConsumerEmail)
+		}
+	}
+	return nil
+}
+
+// fill replaces empty plaintext with the decrypted values.
+func (c openedContact) fill(phone *string, email **string) {
+	if *phone == "" {
+		*phone = c.phone
+	}
+	if *email == nil || **email == "" {
+		*email = c.email
+	}
+}
+
+// findLeadIDByContact returns the newest lead of the organization with the
+// given phone number or email address.
+func (r *Repository) findLeadIDByContact(ctx context.Context, consumerPhone, consumerEmail string, organizationID uuid.UUID) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT id
+		FROM RAC_leads
+		WHERE organization_id = $1 AND deleted_at IS NULL
+			AND (
+				($2 <> '' AND consumer_phone_bidx = $2)
+				OR ($3 <> '' AND consumer_email_bidx = $3)
+				OR (contact_key_id IS NULL AND (($4 <> '' AND consumer_phone = $4) OR ($5 <> '' AND consumer_email = $5)))
+			)
+		ORDER BY created_at DESC
+		LIMIT 1`,
+		organizationID, r.contact.PhoneBlindIndex(consumerPhone), r.contact.EmailBlindIndex(consumerEmail), consumerPhone, consumerEmail,
+	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("find lead by contact: %w", err)
+	}
+	return id, nil
+}
+
+// EncryptContactBatch seals the contact details of up to limit leads that
+// have none yet or were sealed with a key other than the primary key, and
+// restores the plaintext of leads whose plaintext columns were cleared after
+// sealing. It returns the number of leads updated; callers repeat until it
+// returns zero.
+func (r *Repository) EncryptContactBatch(ctx context.Context, limit int) (int, error) {
+	if r.contact == nil {
+		return 0, errors.New("lead contact encryption not configured")
+	}
+	if limit < 1 {
+		limit = 500
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, organization_id, consumer_phone, consumer_email, consumer_phone_encrypted, consumer_email_encrypted
+		FROM RAC_leads
+		WHERE contact_key_id IS DISTINCT FROM $1
+			OR (consumer_phone = '' AND consumer_phone_encrypted IS NOT NULL)
+			OR (consumer_email IS NULL AND consumer_email_encrypted IS NOT NULL)
+		ORDER BY created_at ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, r.contact.PrimaryID(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("list lead contacts to encrypt: %w", err)
+	}
+	type pending struct {
+		id, organizationID uuid.UUID
+		sealed             sealedContact
+	}
+	var batch []pending
+	for rows.Next() {
+		var (
+			item                           pending
+			consumerPhone                  string
+			consumerEmail                  *string
+			phoneEncrypted, emailEncrypted *string
+		)
+		if err := rows.Scan(&item.id, &item.organizationID, &consumerPhone, &consumerEmail, &phoneEncrypted, &emailEncrypted); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan lead contact: %w", err)
+		}
+		item.sealed, err = r.resealContact(consumerPhone, consumerEmail, phoneEncrypted, emailEncrypted)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("lead %s: %w", item.id, err)
+		}
+		batch = append(batch, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list lead contacts to encrypt: %w", err)
+	}
+
+	for _, item := range batch {
+		if err := updateLeadContact(ctx, tx, item.id, item.organizationID, item.sealed); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return len(batch), nil
+}
+
+// resealContact seals a lead's contact details with the primary key. Values
+// already sealed are decrypted first unless plaintext is still present.
+func (r *Repository) resealContact(consumerPhone string, consumerEmail, phoneEncrypted, emailEncrypted *string) (sealedContact, error) {
+	phone, email, err := r.contact.open(phoneEncrypted, emailEncrypted)
+	if err != nil {
+		return sealedContact{}, err
+	}
+	openedContact{phone: phone, email: email}.fill(&consumerPhone, &consumerEmail)
+	return r.contact.seal(consumerPhone, consumerEmail)
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"portal_final_backend/platform/secrets"
+)
+
+func testContactCipher(t *testing.T) *ContactCipher {
+	t.Helper()
+	kr, err := secrets.ParseKeyring("k1:" + strings.Repeat("ab", secrets.KeySize))
+	if err != nil {
+		t.Fatalf("parse keyring: %v", err)
+	}
+	return NewContactCipher(kr, []byte("index-key"))
+}
+
+func TestContactBlindIndexNormalizes(t *testing.T) {
+	key := []byte(strings.Repeat("k", secrets.KeySize))
+
+	local := contactBlindIndex(key, "phone", normalizeContactPhone("06 12345678"))
+	international := contactBlindIndex(key, "phone", normalizeContactPhone("+31612345678"))
+	if local == "" || local != international {
+		t.Fatalf("expected equal phone indexes, got %q and %q", local, international)
+	}
+	if got := contactBlindIndex(key, "email", normalizeContactEmail(" Jan@Example.com ")); got != contactBlindIndex(key, "email", "jan@example.com") {
+		t.Fatal("expected email index to ignore case and whitespace")
+	}
+	if contactBlindIndex(key, "email", "x") == contactBlindIndex(key, "phone", "x") {
+		t.Fatal("expected kinds to use separate indexes")
+	}
+	if contactBlindIndex(nil, "phone", "+31612345678") != "" || contactBlindIndex(key, "phone", "") != "" {
+		t.Fatal("expected no index without key or value")
+	}
+	var disabled *ContactCipher
+	if disabled.PhoneBlindIndex("+31612345678") != "" || disabled.EmailBlindIndex("jan@example.com") != "" {
+		t.Fatal("expected no index without contact encryption")
+	}
+}
+
+func TestSealAndOpenContact(t *testing.T) {
+	cipher := testContactCipher(t)
+	email := "jan@example.com"
+	sealed, err := cipher.seal("+31612345678", &email)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if sealed.keyID != "k1" || sealed.phoneIndex == nil || sealed.emailIndex == nil || sealed.phone != "+31612345678" || sealed.email != &email {
+		t.Fatalf("unexpected sealed contact %+v", sealed)
+	}
+	phone, opened, err := cipher.open(sealed.phoneEncrypted, sealed.emailEncrypted)
+	if err != nil || phone != "+31612345678" || opened == nil || *opened != email {
+		t.Fatalf("expected contact to open, got %q, %v, %v", phone, opened, err)
+	}
+
+	anonymized, err := cipher.seal("", nil)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if anonymized.phoneEncrypted != nil || anonymized.phoneIndex != nil || anonymized.emailEncrypted != nil || anonymized.keyID != "k1" {
+		t.Fatalf("expected empty contact to seal to nothing, got %+v", anonymized)
+	}
+}
+
+func TestResealContactPrefersPlaintext(t *testing.T) {
+	cipher := testContactCipher(t)
+	repo := &Repository{contact: cipher}
+	oldEmail := "old@example.com"
+	previous, err := cipher.seal("+31600000000", &oldEmail)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	// Already sealed, plaintext cleared: the sealed values are carried over and
+	// the plaintext is restored.
+	resealed, err := repo.resealContact("", nil, previous.phoneEncrypted, previous.emailEncrypted)
+	if err != nil {
+		t.Fatalf("reseal: %v", err)
+	}
+	phone, email, _ := cipher.open(resealed.phoneEncrypted, resealed.emailEncrypted)
+	if phone != "+31600000000" || email == nil || *email != oldEmail {
+		t.Fatalf("expected sealed values to survive, got %q, %v", phone, email)
+	}
+	if resealed.phone != "+31600000000" || resealed.email == nil || *resealed.email != oldEmail {
+		t.Fatalf("expected plaintext to be restored, got %q, %v", resealed.phone, resealed.email)
+	}
+
+	// Plaintext written after sealing wins over the sealed copy.
+	resealed, err = repo.resealContact("+31612345678", nil, previous.phoneEncrypted, previous.emailEncrypted)
+	if err != nil {
+		t.Fatalf("reseal: %v", err)
+	}
+	phone, email, _ = cipher.open(resealed.phoneEncrypted, resealed.emailEncrypted)
+	if phone != "+31612345678" || email == nil || *email != oldEmail {
+		t.Fatalf("expected plaintext phone and sealed email, got %q, %v", phone, email)
+	}
+}
+
+func TestLeadListFiltersUseBlindIndexes(t *testing.T) {
+	cipher := testContactCipher(t)
+	phone := "06 12345678"
+	filters := buildLeadListFilters(ListParams{Search: "jan@example.com", Phone: &phone}, cipher)
+	if filters.phoneBidx.String != cipher.PhoneBlindIndex("+31612345678") || !filters.phoneBidx.Valid {
+		t.Fatalf("expected phone filter blind index, got %+v", filters.phoneBidx)
+	}
+	if filters.searchEmailBidx.String != cipher.EmailBlindIndex("jan@example.com") || filters.emailBidx.Valid {
+		t.Fatalf("unexpected email indexes %+v %+v", filters.searchEmailBidx, filters.emailBidx)
+	}
+	if plain := buildLeadListFilters(ListParams{Search: "jan", Phone: &phone}, nil); plain.phoneBidx.Valid || plain.searchPhoneBidx.Valid {
+		t.Fatal("expected no blind indexes without contact encryption")
+	}
+}
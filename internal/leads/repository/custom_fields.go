@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetLeadCustomFields returns the organization-defined field values of a
+// lead by field key.
+func (r *Repository) GetLeadCustomFields(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (map[string]any, error) {
+	var raw []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT custom_fields FROM RAC_leads
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL`, id, organizationID,
+	).Scan(&raw)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get lead custom fields: %w", err)
+	}
+	values := map[string]any{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("decode lead custom fields: %w", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EnergyLabelRefreshCandidate is a lead whose energy label is due for a refresh.
+type EnergyLabelRefreshCandidate struct {
+	LeadID         uuid.UUID
+	OrganizationID uuid.UUID
+}
+
+// ListLeadsWithStaleEnergyLabel returns leads across organizations whose energy
+// label was last fetched before the given time, oldest first.
+func (r *Repository) ListLeadsWithStaleEnergyLabel(ctx context.Context, fetchedBefore time.Time, limit int) ([]EnergyLabelRefreshCandidate, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, organization_id
+		FROM RAC_leads
+		WHERE deleted_at IS NULL
+			AND energy_label_fetched_at IS NOT NULL
+			AND energy_label_fetched_at < $1
+			AND address_zip_code <> ''
+			AND address_house_number <> ''
+		ORDER BY energy_label_fetched_at ASC
+		LIMIT $2`, fetchedBefore, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list leads with stale energy label: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]EnergyLabelRefreshCandidate, 0)
+	for rows.Next() {
+		var item EnergyLabelRefreshCandidate
+		if err := rows.Scan(&item.LeadID, &item.OrganizationID); err != nil {
+			return nil, fmt.Errorf("scan energy label refresh candidate: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -27,7 +27,8 @@ type FuzzyLeadMatch struct {
 // It is intentionally NOT managed via sqlc because it returns a computed
 // match_score column that sqlc cannot currently represent in the generated model.
 //
-// Requires the pg_trgm extension (migration 153).
+// Requires the pg_trgm extension (migration 153). Encrypted phone numbers only
+// match exactly, through their blind index ($4).
 const fuzzySearchLeadsQuery = `
 SELECT
 	l.id,
@@ -53,13 +54,16 @@ LEFT JOIN LATERAL (
 LEFT JOIN RAC_service_types st
 	ON st.id = cs.service_type_id AND st.organization_id = l.organization_id
 WHERE l.organization_id = $1
-	AND GREATEST(
-		word_similarity($2, l.consumer_first_name),
-		word_similarity($2, l.consumer_last_name),
-		word_similarity($2, l.consumer_first_name || ' ' || l.consumer_last_name),
-		word_similarity($2, l.consumer_phone)
-	) > 0.22
-ORDER BY GREATEST(
+	AND (
+		GREATEST(
+			word_similarity($2, l.consumer_first_name),
+			word_similarity($2, l.consumer_last_name),
+			word_similarity($2, l.consumer_first_name || ' ' || l.consumer_last_name),
+			word_similarity($2, l.consumer_phone)
+		) > 0.22
+		OR l.consumer_phone_bidx = $4
+	)
+ORDER BY l.consumer_phone_bidx = $4 DESC NULLS LAST, GREATEST(
 	word_similarity($2, l.consumer_first_name),
 	word_similarity($2, l.consumer_last_name),
 	word_similarity($2, l.consumer_first_name || ' ' || l.consumer_last_name),
@@ -75,7 +79,7 @@ func (r *Repository) FuzzySearchLeads(ctx context.Context, organizationID uuid.U
 	if limit <= 0 {
 		limit = 10
 	}
-	rows, err := r.pool.Query(ctx, fuzzySearchLeadsQuery, toPgUUID(organizationID), query, int32(limit))
+	rows, err := r.pool.Query(ctx, fuzzySearchLeadsQuery, toPgUUID(organizationID), query, int32(limit), r.contact.PhoneBlindIndex(query))
 	if err != nil {
 		// If pg_trgm is not installed yet the function word_similarity won't exist.
 		// Return an empty result instead of propagating the error so the caller can
@@ -109,7 +113,10 @@ func (r *Repository) FuzzySearchLeads(ctx context.Context, organizationID uuid.U
 		}
 		results = append(results, m)
 	}
-	return results, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, r.openMatchContacts(ctx, results)
 }
 
 // quoteBasedLeadSearchQuery finds leads via their associated quotes.
@@ -144,6 +151,7 @@ WHERE q.organization_id = $1
 		OR l.consumer_last_name ILIKE '%' || $2 || '%'
 		OR (l.consumer_first_name || ' ' || l.consumer_last_name) ILIKE '%' || $2 || '%'
 		OR l.consumer_phone ILIKE '%' || $2 || '%'
+		OR l.consumer_phone_bidx = $4
 		OR q.quote_number ILIKE '%' || $2 || '%'
 	)
 ORDER BY l.id, l.created_at DESC
@@ -157,7 +165,7 @@ func (r *Repository) QuoteBasedLeadSearch(ctx context.Context, organizationID uu
 	if limit <= 0 {
 		limit = 10
 	}
-	rows, err := r.pool.Query(ctx, quoteBasedLeadSearchQuery, toPgUUID(organizationID), query, int32(limit))
+	rows, err := r.pool.Query(ctx, quoteBasedLeadSearchQuery, toPgUUID(organizationID), query, int32(limit), r.contact.PhoneBlindIndex(query))
 	if err != nil {
 		log.Printf("whatsappagent: QuoteBasedLeadSearch SQL error org=%s query=%q: %v", organizationID, query, err)
 		return nil, nil //nolint:nilerr
@@ -188,5 +196,29 @@ func (r *Repository) QuoteBasedLeadSearch(ctx context.Context, organizationID uu
 		}
 		results = append(results, m)
 	}
-	return results, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, r.openMatchContacts(ctx, results)
+}
+
+// openMatchContacts fills in the decrypted contact details of sealed leads.
+func (r *Repository) openMatchContacts(ctx context.Context, matches []FuzzyLeadMatch) error {
+	if r.contact == nil || len(matches) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(matches))
+	for i, m := range matches {
+		ids[i] = m.LeadID
+	}
+	contacts, err := r.openContacts(ctx, r.pool, ids)
+	if err != nil {
+		return err
+	}
+	for i := range matches {
+		if contact, ok := contacts[matches[i].LeadID]; ok {
+			contact.fill(&matches[i].Phone, &matches[i].Email)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// LeadHandover records a lead changing owner together with the note the
+// receiving agent needs to pick it up.
+type LeadHandover struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	FromAgentID    *uuid.UUID
+	ToAgentID      *uuid.UUID
+	Note           string
+	TransferredBy  *uuid.UUID
+	BulkTransferID *uuid.UUID
+	CreatedAt      time.Time
+}
+
+// TransferLeadOwnershipParams moves a single lead to another agent.
+type TransferLeadOwnershipParams struct {
+	LeadID         uuid.UUID
+	OrganizationID uuid.UUID
+	ToAgentID      uuid.UUID
+	Note           string
+	TransferredBy  uuid.UUID
+}
+
+// BulkTransferAgentLeadsParams moves every lead of an agent to another agent.
+type BulkTransferAgentLeadsParams struct {
+	OrganizationID uuid.UUID
+	FromAgentID    uuid.UUID
+	ToAgentID      uuid.UUID
+	Note           string
+	TransferredBy  uuid.UUID
+}
+
+// BulkLeadTransfer is the outcome of a bulk transfer.
+type BulkLeadTransfer struct {
+	ID      uuid.UUID
+	LeadIDs []uuid.UUID
+}
+
+// LeadHandoverAgentStats counts the transfers an agent received and gave away.
+type LeadHandoverAgentStats struct {
+	AgentID        uuid.UUID
+	Email          string
+	TransferredIn  int
+	TransferredOut int
+}
+
+const leadHandoverColumns = `id, organization_id, lead_id, from_agent_id, to_agent_id, note, transferred_by, bulk_transfer_id, created_at`
+
+func scanLeadHandover(row pgx.Row) (LeadHandover, error) {
+	var h LeadHandover
+	err := row.Scan(&h.ID, &h.OrganizationID, &h.LeadID, &h.FromAgentID, &h.ToAgentID, &h.Note, &h.TransferredBy, &h.BulkTransferID, &h.CreatedAt)
+	return h, err
+}
+
+// TransferLeadOwnership assigns the lead to another agent and records the
+// handover in the same transaction.
+func (r *Repository) TransferLeadOwnership(ctx context.Context, params TransferLeadOwnershipParams) (LeadHandover, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return LeadHandover{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var fromAgentID *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT assigned_agent_id FROM RAC_leads
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+		FOR UPDATE`, params.LeadID, params.OrganizationID,
+	).Scan(&fromAgentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LeadHandover{}, ErrNotFound
+	}
+	if err != nil {
+		return LeadHandover{}, fmt.Errorf("lock lead for transfer: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_leads SET assigned_agent_id = $3, updated_at = now()
+		WHERE id = $1 AND organization_id = $2`, params.LeadID, params.OrganizationID, params.ToAgentID,
+	); err != nil {
+		return LeadHandover{}, fmt.Errorf("transfer lead owner: %w", err)
+	}
+
+	handover, err := scanLeadHandover(tx.QueryRow(ctx, `
+		INSERT INTO RAC_lead_handovers (organization_id, lead_id, from_agent_id, to_agent_id, note, transferred_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+leadHandoverColumns,
+		params.OrganizationID, params.LeadID, fromAgentID, params.ToAgentID, params.Note, params.TransferredBy,
+	))
+	if err != nil {
+		return LeadHandover{}, fmt.Errorf("insert lead handover: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return LeadHandover{}, fmt.Errorf("commit lead transfer: %w", err)
+	}
+	return handover, nil
+}
+
+// BulkTransferAgentLeads moves every lead assigned to an agent to another
+// agent, recording one handover per lead under a shared bulk transfer ID.
+func (r *Repository) BulkTransferAgentLeads(ctx context.Context, params BulkTransferAgentLeadsParams) (BulkLeadTransfer, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return BulkLeadTransfer{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		UPDATE RAC_leads SET assigned_agent_id = $3, updated_at = now()
+		WHERE organization_id = $1 AND assigned_agent_id = $2 AND deleted_at IS NULL
+		RETURNING id`, params.OrganizationID, params.FromAgentID, params.ToAgentID,
+	)
+	if err != nil {
+		return BulkLeadTransfer{}, fmt.Errorf("bulk transfer leads: %w", err)
+	}
+	leadIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return BulkLeadTransfer{}, fmt.Errorf("bulk transfer leads: %w", err)
+	}
+
+	transfer := BulkLeadTransfer{ID: uuid.New(), LeadIDs: leadIDs}
+	if len(leadIDs) == 0 {
+		return transfer, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_lead_handovers (organization_id, lead_id, from_agent_id, to_agent_id, note, transferred_by, bulk_transfer_id)
+		SELECT $1, lead_id, $3, $4, $5, $6, $7
+		FROM unnest($2::uuid[]) AS lead_id`,
+		params.OrganizationID, leadIDs, params.FromAgentID, params.ToAgentID, params.Note, params.TransferredBy, transfer.ID,
+	); err != nil {
+		return BulkLeadTransfer{}, fmt.Errorf("insert bulk lead handovers: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return BulkLeadTransfer{}, fmt.Errorf("commit bulk lead transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// ListLeadHandovers returns the ownership history of a lead, newest first.
+func (r *Repository) ListLeadHandovers(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) ([]LeadHandover, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+leadHandoverColumns+`
+		FROM RAC_lead_handovers
+		WHERE lead_id = $1 AND organization_id = $2
+		ORDER BY created_at DESC`, leadID, organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list lead handovers: %w", err)
+	}
+	defer rows.Close()
+
+	var handovers []LeadHandover
+	for rows.Next() {
+		h, err := scanLeadHandover(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan lead handover: %w", err)
+		}
+		handovers = append(handovers, h)
+	}
+	return handovers, rows.Err()
+}
+
+// ListLeadHandoverStats counts transfers per agent in [from, to).
+func (r *Repository) ListLeadHandoverStats(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]LeadHandoverAgentStats, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH moves AS (
+			SELECT to_agent_id AS agent_id, 1 AS transferred_in, 0 AS transferred_out
+			FROM RAC_lead_handovers
+			WHERE organization_id = $1 AND created_at >= $2 AND created_at < $3 AND to_agent_id IS NOT NULL
+			UNION ALL
+			SELECT from_agent_id, 0, 1
+			FROM RAC_lead_handovers
+			WHERE organization_id = $1 AND created_at >= $2 AND created_at < $3 AND from_agent_id IS NOT NULL
+		)
+		SELECT m.agent_id, COALESCE(u.email, ''), SUM(m.transferred_in)::int, SUM(m.transferred_out)::int
+		FROM moves m
+		LEFT JOIN RAC_users u ON u.id = m.agent_id
+		GROUP BY m.agent_id, u.email
+		ORDER BY SUM(m.transferred_in) + SUM(m.transferred_out) DESC, u.email`,
+		organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list lead handover stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []LeadHandoverAgentStats
+	for rows.Next() {
+		var s LeadHandoverAgentStats
+		if err := rows.Scan(&s.AgentID, &s.Email, &s.TransferredIn, &s.TransferredOut); err != nil {
+			return nil, fmt.Errorf("scan lead handover stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"portal_final_backend/internal/events"
+
 	"github.com/google/uuid"
 )
 
@@ -30,7 +32,7 @@ type LeadWriter interface {
 	Update(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, params UpdateLeadParams) (Lead, error)
 	Delete(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error
 	BulkDelete(ctx context.Context, ids []uuid.UUID, organizationID uuid.UUID) (int, error)
-	SetPublicToken(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, token string, expiresAt time.Time) error
+	SetPublicToken(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, token string, expiresAt time.Time, pendingEvents ...events.Event) error
 }
 
 // LeadValueWriter updates business value fields for RAC_leads.
@@ -45,11 +47,40 @@ type LeadEnrichmentWriter interface {
 	UpdateLeadScore(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, params UpdateLeadScoreParams) error
 }
 
+// AddressValidationStore persists BAG validation results of lead addresses.
+type AddressValidationStore interface {
+	UpsertLeadAddressValidation(ctx context.Context, v LeadAddressValidation) error
+	GetLeadAddressValidation(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) (*LeadAddressValidation, error)
+}
+
 // LeadViewTracker tracks which RAC_users have viewed RAC_leads.
 type LeadViewTracker interface {
 	SetViewedBy(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, userID uuid.UUID) error
 }
 
+// LeadLanguageStore reads and sets the notification language of RAC_leads.
+type LeadLanguageStore interface {
+	GetPreferredLanguage(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (*string, error)
+	SetPreferredLanguage(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, language *string) error
+}
+
+// LeadConsentStore reads and changes the communication consent of RAC_leads,
+// keeping an audit trail of every change.
+type LeadConsentStore interface {
+	GetCommunicationConsent(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) (CommunicationConsent, error)
+	UpdateCommunicationConsent(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, patch CommunicationConsentPatch, origin ConsentChangeOrigin) (CommunicationConsent, []ConsentChange, error)
+	RecordConsentChanges(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, changes []ConsentChange, origin ConsentChangeOrigin) error
+	ListConsentChanges(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, limit int) ([]ConsentChange, error)
+	RequestMarketingConfirmation(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, token string, resendBefore time.Time) (bool, error)
+	ConfirmMarketingByToken(ctx context.Context, token string, origin ConsentChangeOrigin) (MarketingConfirmation, error)
+	ConfirmPendingMarketing(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, origin ConsentChangeOrigin) (bool, error)
+}
+
+// LeadCustomFieldReader reads the organization-defined field values of RAC_leads.
+type LeadCustomFieldReader interface {
+	GetLeadCustomFields(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (map[string]any, error)
+}
+
 // ActivityLogger records activity/audit trail on RAC_leads.
 type ActivityLogger interface {
 	AddActivity(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, userID uuid.UUID, action string, meta map[string]interface{}) error
@@ -68,6 +99,7 @@ type LeadServiceReader interface {
 	GetServiceStateAggregates(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID) (ServiceStateAggregates, error)
 	ListLinkedWhatsAppConversations(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) ([]LinkedWhatsAppConversation, error)
 	ListLinkedIMAPMessages(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) ([]LinkedIMAPMessage, error)
+	ListStageTransitions(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID) ([]StageTransition, error)
 }
 
 // LeadServiceWriter provides write operations for lead services.
@@ -86,6 +118,7 @@ type LeadServiceWriter interface {
 	UpdateServicePreferences(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID, prefs []byte) error
 	CloseAllActiveServices(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) error
 	CompleteLeadService(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, extraWorkAmountCents *int64, extraWorkNotes *string) (LeadService, error)
+	ClaimLeadServiceVersion(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, expected int64) error
 }
 
 // ServiceContextDefinition provides context for the AI gatekeeper.
@@ -128,6 +161,13 @@ type AIDecisionMemoryStore interface {
 	ListRecentAIDecisionMemories(ctx context.Context, organizationID uuid.UUID, serviceType string, limit int) ([]AIDecisionMemory, error)
 }
 
+// ServiceMemoryStore persists the facts agents learned about a lead service across runs.
+type ServiceMemoryStore interface {
+	ListServiceMemories(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID) ([]ServiceMemory, error)
+	AddServiceMemory(ctx context.Context, params AddServiceMemoryParams) error
+	CompactServiceMemories(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID, removeIDs []uuid.UUID, summary string) error
+}
+
 // HumanFeedbackStore persists field-level human corrections over AI-generated quotes.
 type HumanFeedbackStore interface {
 	CreateHumanFeedback(ctx context.Context, params CreateHumanFeedbackParams) (HumanFeedback, error)
@@ -142,6 +182,8 @@ type AttachmentStore interface {
 	GetAttachmentByID(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (Attachment, error)
 	ListAttachmentsByService(ctx context.Context, leadServiceID uuid.UUID, organizationID uuid.UUID) ([]Attachment, error)
 	DeleteAttachment(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error
+	GetAttachmentRendition(ctx context.Context, attachmentID uuid.UUID, organizationID uuid.UUID, variant string) (AttachmentRendition, error)
+	ListAttachmentRenditions(ctx context.Context, attachmentIDs []uuid.UUID, organizationID uuid.UUID) (map[uuid.UUID][]AttachmentRendition, error)
 }
 
 // AppointmentStatsReader provides appointment stats for RAC_leads (for scoring).
@@ -236,6 +278,22 @@ type CatalogGapReader interface {
 	ListFrequentAdHocQuoteItems(ctx context.Context, organizationID uuid.UUID, lookbackDays int, minCount int, limit int) ([]AdHocQuoteItemSummary, error)
 }
 
+// LeadHandoverStore records ownership transfers of leads between agents.
+type LeadHandoverStore interface {
+	TransferLeadOwnership(ctx context.Context, params TransferLeadOwnershipParams) (LeadHandover, error)
+	BulkTransferAgentLeads(ctx context.Context, params BulkTransferAgentLeadsParams) (BulkLeadTransfer, error)
+	ListLeadHandovers(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) ([]LeadHandover, error)
+	ListLeadHandoverStats(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]LeadHandoverAgentStats, error)
+}
+
+// ServiceProjectStore groups lead services into projects with an execution order.
+type ServiceProjectStore interface {
+	CreateServiceProject(ctx context.Context, params CreateServiceProjectParams) (LeadServiceProject, error)
+	ListServiceProjects(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) ([]LeadServiceProject, error)
+	GetServiceProject(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (LeadServiceProject, error)
+	DeleteServiceProject(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error
+}
+
 // AgentRunStore provides observability for agent runs and tool calls.
 type AgentRunStore interface {
 	InsertAgentRun(ctx context.Context, params InsertAgentRunParams) (uuid.UUID, error)
@@ -252,6 +310,9 @@ type AgentApprovalStore interface {
 	GetAgentApprovalByID(ctx context.Context, id, tenantID uuid.UUID) (AgentApproval, error)
 	UpdateAgentApprovalDecision(ctx context.Context, params UpdateAgentApprovalDecisionParams) error
 	CountPendingAgentApprovals(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	ExpireOverdueAgentApprovals(ctx context.Context, now time.Time) (int64, error)
+	GetAgentReviewPolicy(ctx context.Context, organizationID uuid.UUID) (AgentReviewPolicy, error)
+	UpsertAgentReviewPolicy(ctx context.Context, policy AgentReviewPolicy) (AgentReviewPolicy, error)
 }
 
 // =====================================
@@ -272,7 +333,11 @@ type LeadsRepository interface {
 	LeadWriter
 	LeadValueWriter
 	LeadEnrichmentWriter
+	AddressValidationStore
 	LeadViewTracker
+	LeadLanguageStore
+	LeadConsentStore
+	LeadCustomFieldReader
 	ActivityLogger
 	MetricsReader
 	LeadServiceReader
@@ -281,6 +346,7 @@ type LeadsRepository interface {
 	TimelineEventStore
 	AIAnalysisStore
 	AIDecisionMemoryStore
+	ServiceMemoryStore
 	HumanFeedbackStore
 	AttachmentStore
 	ServiceTypeContextReader
@@ -292,6 +358,8 @@ type LeadsRepository interface {
 	FeedReactionStore
 	FeedCommentStore
 	OrgMemberReader
+	LeadHandoverStore
+	ServiceProjectStore
 	CatalogSearchLogStore
 	CatalogGapReader
 	AgentRunStore
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"portal_final_backend/platform/i18n"
+	"portal_final_backend/platform/phone"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetPreferredLanguage returns the language notifications to the lead are
+// sent in, or nil when the lead uses the organization default.
+func (r *Repository) GetPreferredLanguage(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (*string, error) {
+	var language *string
+	err := r.pool.QueryRow(ctx, `
+		SELECT preferred_language FROM RAC_leads
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL`, id, organizationID,
+	).Scan(&language)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get lead preferred language: %w", err)
+	}
+	return language, nil
+}
+
+// SetPreferredLanguage sets the notification language of a lead. Nil clears
+// it so the organization default applies.
+func (r *Repository) SetPreferredLanguage(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, language *string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_leads SET preferred_language = $3
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL`, id, organizationID, language,
+	)
+	if err != nil {
+		return fmt.Errorf("set lead preferred language: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// detectLeadLanguage guesses the language of a new lead from the country of
+// its phone number. Dutch numbers are not stored, so the organization default
+// keeps applying to them.
+func detectLeadLanguage(consumerPhone string) *string {
+	language := i18n.LanguageForRegion(phone.RegionCode(consumerPhone))
+	if language == "" || language == i18n.DefaultLanguage {
+		return nil
+	}
+	return &language
+}
+
+func setDetectedLeadLanguage(ctx context.Context, tx pgx.Tx, id uuid.UUID, organizationID uuid.UUID, consumerPhone string) error {
+	language := detectLeadLanguage(consumerPhone)
+	if language == nil {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_leads SET preferred_language = $3
+		WHERE id = $1 AND organization_id = $2`, id, organizationID, *language,
+	); err != nil {
+		return fmt.Errorf("set detected lead language: %w", err)
+	}
+	return nil
+}
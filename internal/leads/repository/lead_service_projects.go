@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrServiceProjectNotFound = errors.New("service project not found")
+
+// ErrServiceInProject is returned when a service already belongs to another
+// project.
+var ErrServiceInProject = errors.New("lead service already belongs to a project")
+
+// LeadServiceProject groups services of one lead that are executed as a
+// single job. ServiceIDs are in execution order.
+type LeadServiceProject struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	Name           string
+	CreatedBy      *uuid.UUID
+	ServiceIDs     []uuid.UUID
+	Dependencies   []LeadServiceDependency
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// LeadServiceDependency means LeadServiceID can only start once
+// DependsOnServiceID is done.
+type LeadServiceDependency struct {
+	LeadServiceID      uuid.UUID
+	DependsOnServiceID uuid.UUID
+}
+
+type CreateServiceProjectParams struct {
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	Name           string
+	CreatedBy      uuid.UUID
+	ServiceIDs     []uuid.UUID
+	Dependencies   []LeadServiceDependency
+}
+
+// CreateServiceProject stores a project with its services and dependencies.
+// It returns ErrServiceInProject when one of the services is already grouped.
+func (r *Repository) CreateServiceProject(ctx context.Context, params CreateServiceProjectParams) (LeadServiceProject, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return LeadServiceProject{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	project := LeadServiceProject{
+		OrganizationID: params.OrganizationID,
+		LeadID:         params.LeadID,
+		Name:           params.Name,
+		CreatedBy:      &params.CreatedBy,
+		ServiceIDs:     params.ServiceIDs,
+		Dependencies:   params.Dependencies,
+	}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO RAC_lead_service_projects (organization_id, lead_id, name, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		params.OrganizationID, params.LeadID, params.Name, params.CreatedBy,
+	).Scan(&project.ID, &project.CreatedAt, &project.UpdatedAt); err != nil {
+		return LeadServiceProject{}, fmt.Errorf("insert service project: %w", err)
+	}
+
+	for position, serviceID := range params.ServiceIDs {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO RAC_lead_service_project_services (lead_service_id, project_id, position)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (lead_service_id) DO NOTHING`,
+			serviceID, project.ID, position,
+		)
+		if err != nil {
+			return LeadServiceProject{}, fmt.Errorf("insert service project member: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return LeadServiceProject{}, ErrServiceInProject
+		}
+	}
+
+	for _, dep := range params.Dependencies {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_lead_service_dependencies (lead_service_id, depends_on_service_id, project_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`,
+			dep.LeadServiceID, dep.DependsOnServiceID, project.ID,
+		); err != nil {
+			return LeadServiceProject{}, fmt.Errorf("insert service dependency: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return LeadServiceProject{}, fmt.Errorf("commit service project: %w", err)
+	}
+	return project, nil
+}
+
+// ListServiceProjects returns the projects of a lead, oldest first.
+func (r *Repository) ListServiceProjects(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) ([]LeadServiceProject, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, organization_id, lead_id, name, created_by, created_at, updated_at
+		FROM RAC_lead_service_projects
+		WHERE lead_id = $1 AND organization_id = $2
+		ORDER BY created_at, id`, leadID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list service projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := make([]LeadServiceProject, 0)
+	byID := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var p LeadServiceProject
+		if err := rows.Scan(&p.ID, &p.OrganizationID, &p.LeadID, &p.Name, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan service project: %w", err)
+		}
+		byID[p.ID] = len(projects)
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return projects, nil
+	}
+
+	projectIDs := make([]uuid.UUID, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+	if err := r.loadServiceProjectMembers(ctx, projectIDs, func(projectID uuid.UUID, serviceID uuid.UUID) {
+		p := &projects[byID[projectID]]
+		p.ServiceIDs = append(p.ServiceIDs, serviceID)
+	}); err != nil {
+		return nil, err
+	}
+	if err := r.loadServiceProjectDependencies(ctx, projectIDs, func(projectID uuid.UUID, dep LeadServiceDependency) {
+		p := &projects[byID[projectID]]
+		p.Dependencies = append(p.Dependencies, dep)
+	}); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetServiceProject returns a project with its services and dependencies.
+func (r *Repository) GetServiceProject(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (LeadServiceProject, error) {
+	var p LeadServiceProject
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, organization_id, lead_id, name, created_by, created_at, updated_at
+		FROM RAC_lead_service_projects
+		WHERE id = $1 AND organization_id = $2`, id, organizationID,
+	).Scan(&p.ID, &p.OrganizationID, &p.LeadID, &p.Name, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LeadServiceProject{}, ErrServiceProjectNotFound
+	}
+	if err != nil {
+		return LeadServiceProject{}, fmt.Errorf("get service project: %w", err)
+	}
+
+	ids := []uuid.UUID{p.ID}
+	if err := r.loadServiceProjectMembers(ctx, ids, func(_ uuid.UUID, serviceID uuid.UUID) {
+		p.ServiceIDs = append(p.ServiceIDs, serviceID)
+	}); err != nil {
+		return LeadServiceProject{}, err
+	}
+	if err := r.loadServiceProjectDependencies(ctx, ids, func(_ uuid.UUID, dep LeadServiceDependency) {
+		p.Dependencies = append(p.Dependencies, dep)
+	}); err != nil {
+		return LeadServiceProject{}, err
+	}
+	return p, nil
+}
+
+// DeleteServiceProject ungroups the services of a project. The services
+// themselves are kept.
+func (r *Repository) DeleteServiceProject(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_lead_service_projects WHERE id = $1 AND organization_id = $2`, id, organizationID)
+	if err != nil {
+		return fmt.Errorf("delete service project: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrServiceProjectNotFound
+	}
+	return nil
+}
+
+func (r *Repository) loadServiceProjectMembers(ctx context.Context, projectIDs []uuid.UUID, add func(projectID uuid.UUID, serviceID uuid.UUID)) error {
+	rows, err := r.pool.Query(ctx, `
+		SELECT project_id, lead_service_id
+		FROM RAC_lead_service_project_services
+		WHERE project_id = ANY($1)
+		ORDER BY project_id, position`, projectIDs)
+	if err != nil {
+		return fmt.Errorf("list service project members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectID, serviceID uuid.UUID
+		if err := rows.Scan(&projectID, &serviceID); err != nil {
+			return fmt.Errorf("scan service project member: %w", err)
+		}
+		add(projectID, serviceID)
+	}
+	return rows.Err()
+}
+
+func (r *Repository) loadServiceProjectDependencies(ctx context.Context, projectIDs []uuid.UUID, add func(projectID uuid.UUID, dep LeadServiceDependency)) error {
+	rows, err := r.pool.Query(ctx, `
+		SELECT project_id, lead_service_id, depends_on_service_id
+		FROM RAC_lead_service_dependencies
+		WHERE project_id = ANY($1)
+		ORDER BY project_id, lead_service_id, depends_on_service_id`, projectIDs)
+	if err != nil {
+		return fmt.Errorf("list service dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectID uuid.UUID
+		var dep LeadServiceDependency
+		if err := rows.Scan(&projectID, &dep.LeadServiceID, &dep.DependsOnServiceID); err != nil {
+			return fmt.Errorf("scan service dependency: %w", err)
+		}
+		add(projectID, dep)
+	}
+	return rows.Err()
+}
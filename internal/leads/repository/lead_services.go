@@ -37,6 +37,7 @@ type LeadService struct {
 	ExtraWorkNotes                     *string
 	CreatedAt                          time.Time
 	UpdatedAt                          time.Time
+	RowVersion                         int64
 }
 
 type CreateLeadServiceParams struct {
@@ -106,6 +107,9 @@ func (r *Repository) ListLeadServices(ctx context.Context, leadID uuid.UUID, org
 	for _, row := range rows {
 		services = append(services, leadServiceFromListRow(row))
 	}
+	if err := attachServiceRowVersions(ctx, r.pool, leadID, organizationID, services); err != nil {
+		return nil, err
+	}
 	return services, nil
 }
 
@@ -130,13 +134,29 @@ func (r *Repository) GetCurrentLeadService(ctx context.Context, leadID uuid.UUID
 }
 
 func (r *Repository) UpdateServiceStatusAndPipelineStage(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, status string, stage string) (LeadService, error) {
-	row, err := r.queries.UpdateServiceStatusAndPipelineStage(ctx, leadsdb.UpdateServiceStatusAndPipelineStageParams{ID: toPgUUID(id), OrganizationID: toPgUUID(organizationID), Status: status, PipelineStage: leadsdb.PipelineStage(stage)})
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return LeadService{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, err := lockServiceStage(ctx, tx, id, organizationID)
+	if err != nil {
+		return LeadService{}, err
+	}
+	row, err := r.queries.WithTx(tx).UpdateServiceStatusAndPipelineStage(ctx, leadsdb.UpdateServiceStatusAndPipelineStageParams{ID: toPgUUID(id), OrganizationID: toPgUUID(organizationID), Status: status, PipelineStage: leadsdb.PipelineStage(stage)})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return LeadService{}, ErrServiceNotFound
 	}
 	if err != nil {
 		return LeadService{}, err
 	}
+	if err := recordStageTransition(ctx, tx, organizationID, current, string(row.PipelineStage)); err != nil {
+		return LeadService{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return LeadService{}, fmt.Errorf("commit tx: %w", err)
+	}
 	return leadServiceFromUpdateStatusAndStageRow(row), nil
 }
 
@@ -166,7 +186,17 @@ func (r *Repository) UpdateServiceStatus(ctx context.Context, id uuid.UUID, orga
 // CompleteLeadService moves a Fulfillment-stage service to Completed and optionally records extra work.
 // Returns ErrServiceNotFound if the service does not exist or is not in the Fulfillment stage.
 func (r *Repository) CompleteLeadService(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, extraWorkAmountCents *int64, extraWorkNotes *string) (LeadService, error) {
-	row, err := r.queries.CompleteLeadService(ctx, leadsdb.CompleteLeadServiceParams{
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return LeadService{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, err := lockServiceStage(ctx, tx, id, organizationID)
+	if err != nil {
+		return LeadService{}, err
+	}
+	row, err := r.queries.WithTx(tx).CompleteLeadService(ctx, leadsdb.CompleteLeadServiceParams{
 		ID:                   toPgUUID(id),
 		OrganizationID:       toPgUUID(organizationID),
 		ExtraWorkAmountCents: toPgInt8Ptr(extraWorkAmountCents),
@@ -178,6 +208,12 @@ func (r *Repository) CompleteLeadService(ctx context.Context, id uuid.UUID, orga
 	if err != nil {
 		return LeadService{}, err
 	}
+	if err := recordStageTransition(ctx, tx, organizationID, current, string(row.PipelineStage)); err != nil {
+		return LeadService{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return LeadService{}, fmt.Errorf("commit tx: %w", err)
+	}
 	return leadServiceFromCompleteRow(row), nil
 }
 
@@ -223,23 +259,60 @@ func (r *Repository) UpdatePipelineStage(ctx context.Context, id uuid.UUID, orga
 		return LeadService{}, fmt.Errorf("invalid state combination: %s", reason)
 	}
 
-	row, err := r.queries.UpdatePipelineStage(ctx, leadsdb.UpdatePipelineStageParams{ID: toPgUUID(id), OrganizationID: toPgUUID(organizationID), PipelineStage: leadsdb.PipelineStage(stage)})
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return LeadService{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, err := lockServiceStage(ctx, tx, id, organizationID)
+	if err != nil {
+		return LeadService{}, err
+	}
+	row, err := r.queries.WithTx(tx).UpdatePipelineStage(ctx, leadsdb.UpdatePipelineStageParams{ID: toPgUUID(id), OrganizationID: toPgUUID(organizationID), PipelineStage: leadsdb.PipelineStage(stage)})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return LeadService{}, ErrServiceNotFound
 	}
 	if err != nil {
 		return LeadService{}, err
 	}
+	if err := recordStageTransition(ctx, tx, organizationID, current, string(row.PipelineStage)); err != nil {
+		return LeadService{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return LeadService{}, fmt.Errorf("commit tx: %w", err)
+	}
 	return leadServiceFromUpdatePipelineRow(row), nil
 }
 
 // CloseAllActiveServices marks all non-terminal services for a lead as Completed/Completed.
 // Uses a single CTE query to avoid N+1 updates and ensure atomicity.
 func (r *Repository) CloseAllActiveServices(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) error {
-	return r.queries.CloseAllActiveServices(ctx, leadsdb.CloseAllActiveServicesParams{
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	active, err := lockActiveServiceStages(ctx, tx, leadID, organizationID)
+	if err != nil {
+		return err
+	}
+	if err := r.queries.WithTx(tx).CloseAllActiveServices(ctx, leadsdb.CloseAllActiveServicesParams{
 		LeadID:         toPgUUID(leadID),
 		OrganizationID: toPgUUID(organizationID),
-	})
+	}); err != nil {
+		return err
+	}
+	for _, svc := range active {
+		if err := recordStageTransition(ctx, tx, organizationID, svc, domain.PipelineStageCompleted); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
 }
 
 func (r *Repository) UpdateServicePreferences(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID, prefs []byte) error {
@@ -397,3 +470,42 @@ func leadServiceFromUpdatePipelineRow(row leadsdb.UpdatePipelineStageRow) LeadSe
 		ExtraWorkAmountCents: row.ExtraWorkAmountCents, ExtraWorkNotes: row.ExtraWorkNotes, CreatedAt: row.CreatedAt, UpdatedAt: row.UpdatedAt,
 	})
 }
+
+// ListLeadServicesByLeadIDs returns the services of several leads in one query, newest
+// first per lead. Batched readers use it instead of calling ListLeadServices per lead.
+func (r *Repository) ListLeadServicesByLeadIDs(ctx context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID) ([]LeadService, error) {
+	if len(leadIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT ls.id, ls.lead_id, ls.organization_id, st.name, ls.status, ls.pipeline_stage, ls.consumer_note, ls.source,
+			ls.customer_preferences, ls.gatekeeper_nurturing_loop_count, ls.gatekeeper_nurturing_loop_fingerprint,
+			ls.agent_cycle_count, ls.agent_cycle_fingerprint, ls.agent_cycle_last_transition,
+			ls.extra_work_amount_cents, ls.extra_work_notes,
+			ls.created_at, ls.updated_at
+		FROM RAC_lead_services ls
+		JOIN RAC_service_types st ON st.id = ls.service_type_id AND st.organization_id = ls.organization_id
+		WHERE ls.lead_id = ANY($1) AND ls.organization_id = $2
+		ORDER BY ls.lead_id, ls.created_at DESC`,
+		toPgUUIDSlice(leadIDs), toPgUUID(organizationID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var services []LeadService
+	for rows.Next() {
+		var f leadServiceFields
+		if err := rows.Scan(
+			&f.ID, &f.LeadID, &f.OrganizationID, &f.ServiceType, &f.Status, &f.PipelineStage, &f.ConsumerNote, &f.Source,
+			&f.CustomerPreferences, &f.GatekeeperNurturingLoopCount, &f.GatekeeperNurturingLoopFingerprint,
+			&f.AgentCycleCount, &f.AgentCycleFingerprint, &f.AgentCycleLastTransition,
+			&f.ExtraWorkAmountCents, &f.ExtraWorkNotes,
+			&f.CreatedAt, &f.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		services = append(services, leadServiceFromRow(f))
+	}
+	return services, rows.Err()
+}
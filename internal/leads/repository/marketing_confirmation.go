@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// MarketingConfirmation identifies the lead that confirmed its marketing
+// consent.
+type MarketingConfirmation struct {
+	LeadID         uuid.UUID
+	OrganizationID uuid.UUID
+}
+
+// RequestMarketingConfirmation stores a new confirmation token for a lead
+// that allows marketing but has not confirmed it. It returns false when the
+// lead needs no confirmation or was already asked after resendBefore.
+func (r *Repository) RequestMarketingConfirmation(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, token string, resendBefore time.Time) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_leads l
+		SET marketing_confirmation_token = $3, marketing_confirmation_requested_at = now()
+		WHERE l.id = $1 AND l.organization_id = $2 AND l.deleted_at IS NULL
+			AND l.marketing_confirmed_at IS NULL
+			AND (l.marketing_confirmation_requested_at IS NULL OR l.marketing_confirmation_requested_at < $4)
+			AND NOT EXISTS (
+				SELECT 1 FROM RAC_lead_communication_consent c
+				WHERE c.lead_id = l.id AND c.marketing_allowed = false
+			)`, leadID, organizationID, token, resendBefore,
+	)
+	if err != nil {
+		return false, fmt.Errorf("request lead marketing confirmation: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ConfirmMarketingByToken confirms the marketing consent of the lead the
+// token was sent to.
+func (r *Repository) ConfirmMarketingByToken(ctx context.Context, token string, origin ConsentChangeOrigin) (MarketingConfirmation, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return MarketingConfirmation{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var confirmation MarketingConfirmation
+	err = tx.QueryRow(ctx, `
+		UPDATE RAC_leads
+		SET marketing_confirmed_at = now(), marketing_confirmation_token = NULL
+		WHERE marketing_confirmation_token = $1 AND deleted_at IS NULL
+		RETURNING id, organization_id`, token,
+	).Scan(&confirmation.LeadID, &confirmation.OrganizationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return MarketingConfirmation{}, ErrNotFound
+	}
+	if err != nil {
+		return MarketingConfirmation{}, fmt.Errorf("confirm lead marketing: %w", err)
+	}
+	if err := recordMarketingConfirmed(ctx, tx, confirmation, origin); err != nil {
+		return MarketingConfirmation{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return MarketingConfirmation{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return confirmation, nil
+}
+
+// ConfirmPendingMarketing confirms the marketing consent of a lead that was
+// asked to confirm it, such as when it replies to the request on WhatsApp.
+// It returns false when no confirmation was pending.
+func (r *Repository) ConfirmPendingMarketing(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, origin ConsentChangeOrigin) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE RAC_leads
+		SET marketing_confirmed_at = now(), marketing_confirmation_token = NULL
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+			AND marketing_confirmation_token IS NOT NULL AND marketing_confirmed_at IS NULL`, leadID, organizationID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("confirm pending lead marketing: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	if err := recordMarketingConfirmed(ctx, tx, MarketingConfirmation{LeadID: leadID, OrganizationID: organizationID}, origin); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit tx: %w", err)
+	}
+	return true, nil
+}
+
+func recordMarketingConfirmed(ctx context.Context, tx pgx.Tx, confirmation MarketingConfirmation, origin ConsentChangeOrigin) error {
+	origin.Source = ConsentSourceDoubleOptIn
+	_, err := insertConsentChanges(ctx, tx, confirmation.LeadID, confirmation.OrganizationID, []ConsentChange{{
+		Consent: ConsentMarketing,
+		Granted: true,
+	}}, origin)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 
 	leadsdb "portal_final_backend/internal/leads/db"
+	"portal_final_backend/platform/sanitize"
 )
 
 type LeadNote struct {
@@ -23,6 +24,12 @@ type LeadNote struct {
 	UpdatedAt      time.Time
 }
 
+// PlainBody returns the body as plain text, for prompts and messages. Rich
+// text notes store sanitized HTML.
+func (n LeadNote) PlainBody() string {
+	return sanitize.WhatsAppText(n.Body)
+}
+
 type CreateLeadNoteParams struct {
 	LeadID         uuid.UUID
 	OrganizationID uuid.UUID
@@ -28,6 +28,33 @@ type PartnerOfferStats struct {
 	Open     int // pending + sent
 }
 
+// findMatchingPartnersQuery finds the nearest partners offering a service type
+// within a radius of the anchor ($1 = lon, $2 = lat, $5 = radius in km).
+// It is raw SQL because sqlc cannot parse the PostGIS functions. ST_DWithin on
+// the generated location column uses the GiST index (migration 229), so only
+// partners near the anchor are visited instead of the whole organization.
+const findMatchingPartnersQuery = `
+WITH anchor AS (
+	SELECT ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography AS point
+)
+SELECT p.id, p.business_name, p.contact_email,
+	ST_Distance(p.location, anchor.point) / 1000.0 AS dist_km
+FROM RAC_partners p, anchor
+WHERE p.organization_id = $3
+	AND ST_DWithin(p.location, anchor.point, $5 * 1000.0)
+	AND EXISTS (
+		SELECT 1
+		FROM RAC_partner_service_types pst
+		JOIN RAC_service_types st ON st.id = pst.service_type_id AND st.organization_id = p.organization_id
+		WHERE pst.partner_id = p.id
+			AND st.is_active = true
+			AND (st.name = $4 OR st.slug = $4)
+	)
+	AND (CARDINALITY($6::uuid[]) = 0 OR p.id != ALL($6::uuid[]))
+ORDER BY dist_km ASC
+LIMIT 5
+`
+
 func (r *Repository) FindMatchingPartners(ctx context.Context, organizationID uuid.UUID, leadID uuid.UUID, serviceType string, zipCode string, radiusKm int, excludePartnerIDs []uuid.UUID) ([]PartnerMatch, error) {
 	lat, lon, ok, err := r.lookupLeadCoordinates(ctx, organizationID, leadID)
 	if err != nil {
@@ -46,21 +73,27 @@ func (r *Repository) FindMatchingPartners(ctx context.Context, organizationID uu
 		}
 	}
 
-	rows, err := r.queries.FindMatchingPartnersByCoordinates(ctx, leadsdb.FindMatchingPartnersByCoordinatesParams{
-		LlToEarth:      lon,
-		LlToEarth_2:    lat,
-		OrganizationID: toPgUUID(organizationID),
-		Name:           serviceType,
-		Column5:        radiusKm,
-		Column6:        toPgUUIDSlice(excludePartnerIDs),
-	})
+	rows, err := r.pool.Query(ctx, findMatchingPartnersQuery, lon, lat, toPgUUID(organizationID), serviceType, float64(radiusKm), toPgUUIDSlice(excludePartnerIDs))
 	if err != nil {
 		return nil, err
 	}
-
-	matches := make([]PartnerMatch, 0, len(rows))
-	for _, row := range rows {
-		matches = append(matches, partnerMatchFromDistanceRow(row.ID, row.BusinessName, row.ContactEmail, row.DistKm))
+	defer rows.Close()
+
+	matches := make([]PartnerMatch, 0, 5)
+	for rows.Next() {
+		var (
+			id           pgtype.UUID
+			businessName string
+			email        string
+			distanceKm   float64
+		)
+		if err := rows.Scan(&id, &businessName, &email, &distanceKm); err != nil {
+			return nil, err
+		}
+		matches = append(matches, partnerMatchFromDistanceRow(id, businessName, email, distanceKm))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return matches, nil
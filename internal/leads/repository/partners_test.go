@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// partnerMatchSchema holds the columns FindMatchingPartners reads, so the test
+// runs against any Postgres with PostGIS instead of a fully migrated database.
+const partnerMatchSchema = `
+CREATE TABLE RAC_leads (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	latitude double precision,
+	longitude double precision
+);
+CREATE TABLE RAC_partners (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	business_name text NOT NULL,
+	contact_email text NOT NULL,
+	latitude double precision,
+	longitude double precision,
+	location geography(Point, 4326) GENERATED ALWAYS AS (
+		CASE WHEN latitude IS NOT NULL AND longitude IS NOT NULL
+			THEN ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography
+		END
+	) STORED
+);
+CREATE TABLE RAC_service_types (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	name text NOT NULL,
+	slug text NOT NULL,
+	is_active boolean NOT NULL
+);
+CREATE TABLE RAC_partner_service_types (
+	partner_id uuid NOT NULL,
+	service_type_id uuid NOT NULL
+);
+`
+
+// openPartnerMatchDB connects to TEST_DATABASE_URL with a scratch schema
+// first on the search path and drops the schema when the test ends.
+func openPartnerMatchDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+	if _, err := admin.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS postgis"); err != nil {
+		t.Skipf("postgis unavailable: %v", err)
+	}
+	schema := "partner_match_" + uuid.NewString()[:8]
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ", public"
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if _, err := pool.Exec(ctx, partnerMatchSchema); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestFindMatchingPartnersFilters(t *testing.T) {
+	pool := openPartnerMatchDB(t)
+	ctx := context.Background()
+	orgID, otherOrgID, leadID := uuid.New(), uuid.New(), uuid.New()
+
+	// The lead sits on Amsterdam Centraal.
+	mustExec(t, pool, "INSERT INTO RAC_leads (id, organization_id, latitude, longitude) VALUES ($1, $2, 52.3791, 4.9003)", leadID, orgID)
+
+	roofing, painting, retired := uuid.New(), uuid.New(), uuid.New()
+	mustExec(t, pool, `INSERT INTO RAC_service_types (id, organization_id, name, slug, is_active) VALUES
+		($1, $4, 'Dakwerk', 'dakwerk', true),
+		($2, $4, 'Schilderwerk', 'schilderwerk', true),
+		($3, $4, 'Dakgoten', 'dakgoten', false)`, roofing, painting, retired, orgID)
+
+	partners := []struct {
+		name        string
+		org         uuid.UUID
+		lat, lon    float64
+		serviceType uuid.UUID
+	}{
+		{"near", orgID, 52.3702, 4.8952, roofing},           // ~1 km
+		{"nearby", orgID, 52.3508, 4.8340, roofing},         // ~5.5 km
+		{"excluded", orgID, 52.3600, 4.8800, roofing},       // ~2.5 km
+		{"far", orgID, 52.0907, 5.1214, roofing},            // Utrecht, ~35 km
+		{"wrong service", orgID, 52.3750, 4.8900, painting}, // ~0.8 km
+		{"inactive service", orgID, 52.3760, 4.8950, retired},
+		{"other organization", otherOrgID, 52.3780, 4.9000, roofing},
+	}
+	ids := make(map[string]uuid.UUID, len(partners))
+	for _, p := range partners {
+		id := uuid.New()
+		ids[p.name] = id
+		mustExec(t, pool, "INSERT INTO RAC_partners (id, organization_id, business_name, contact_email, latitude, longitude) VALUES ($1, $2, $3, $3 || '@example.com', $4, $5)", id, p.org, p.name, p.lat, p.lon)
+		mustExec(t, pool, "INSERT INTO RAC_partner_service_types (partner_id, service_type_id) VALUES ($1, $2)", id, p.serviceType)
+	}
+
+	repo := New(pool)
+	for _, serviceType := range []string{"Dakwerk", "dakwerk"} {
+		matches, err := repo.FindMatchingPartners(ctx, orgID, leadID, serviceType, "", 10, []uuid.UUID{ids["excluded"]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, 0, len(matches))
+		for _, m := range matches {
+			got = append(got, m.BusinessName)
+		}
+		if len(got) != 2 || got[0] != "near" || got[1] != "nearby" {
+			t.Fatalf("service type %q: matches = %v, want [near nearby]", serviceType, got)
+		}
+		if matches[0].DistanceKm > matches[1].DistanceKm || matches[1].DistanceKm > 10 {
+			t.Errorf("service type %q: distances = %v, %v, want ascending within 10 km", serviceType, matches[0].DistanceKm, matches[1].DistanceKm)
+		}
+	}
+
+	matches, err := repo.FindMatchingPartners(ctx, orgID, leadID, "Dakwerk", "", 50, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 4 || matches[3].ID != ids["far"] {
+		t.Errorf("50 km radius without exclusions: got %d matches, want 4 ending with far", len(matches))
+	}
+}
+
+func mustExec(t *testing.T, pool *pgxpool.Pool, sql string, args ...any) {
+	t.Helper()
+	if _, err := pool.Exec(context.Background(), sql, args...); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/internal/leads/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrPortalLinkInvalid is returned when a portal link is unknown, expired or
+// revoked.
+var ErrPortalLinkInvalid = errors.New("portal link invalid")
+
+// PortalLink is a magic link granting a lead access to the lead portal.
+type PortalLink struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	Channel        string
+	ExpiresAt      time.Time
+	MaxExpiresAt   time.Time
+	LastUsedAt     *time.Time
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+}
+
+type CreatePortalLinkParams struct {
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	Channel        string
+	ExpiresAt      time.Time
+	MaxExpiresAt   time.Time
+}
+
+// CreatePortalLink stores a new magic link for a lead that is not deleted.
+func (r *Repository) CreatePortalLink(ctx context.Context, params CreatePortalLinkParams) (PortalLink, error) {
+	link := PortalLink{
+		OrganizationID: params.OrganizationID,
+		LeadID:         params.LeadID,
+		Channel:        params.Channel,
+		ExpiresAt:      params.ExpiresAt,
+		MaxExpiresAt:   params.MaxExpiresAt,
+	}
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_lead_portal_links (organization_id, lead_id, channel, expires_at, max_expires_at)
+		SELECT l.organization_id, l.id, $3, $4, $5
+		FROM RAC_leads l
+		WHERE l.id = $1 AND l.organization_id = $2 AND l.deleted_at IS NULL
+		RETURNING id, created_at`,
+		params.LeadID, params.OrganizationID, params.Channel, params.ExpiresAt, params.MaxExpiresAt,
+	).Scan(&link.ID, &link.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PortalLink{}, ErrNotFound
+	}
+	if err != nil {
+		return PortalLink{}, fmt.Errorf("create portal link: %w", err)
+	}
+	return link, nil
+}
+
+// UsePortalLink records a use of a valid link and slides its expiry to
+// now+ttl, capped at the link's maximum lifetime.
+func (r *Repository) UsePortalLink(ctx context.Context, id uuid.UUID, ttl time.Duration) (PortalLink, error) {
+	var link PortalLink
+	err := r.pool.QueryRow(ctx, `
+		UPDATE RAC_lead_portal_links
+		SET last_used_at = now(),
+			expires_at = LEAST(max_expires_at, GREATEST(expires_at, now() + make_interval(secs => $2)))
+		WHERE id = $1
+			AND revoked_at IS NULL
+			AND expires_at > now()
+		RETURNING id, organization_id, lead_id, channel, expires_at, max_expires_at, last_used_at, revoked_at, created_at`,
+		id, ttl.Seconds(),
+	).Scan(&link.ID, &link.OrganizationID, &link.LeadID, &link.Channel, &link.ExpiresAt, &link.MaxExpiresAt, &link.LastUsedAt, &link.RevokedAt, &link.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PortalLink{}, ErrPortalLinkInvalid
+	}
+	if err != nil {
+		return PortalLink{}, fmt.Errorf("use portal link: %w", err)
+	}
+	return link, nil
+}
+
+// PortalLinkOrganization returns the organization of a valid link without
+// recording a use.
+func (r *Repository) PortalLinkOrganization(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var organizationID uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT organization_id FROM RAC_lead_portal_links
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > now()`, id,
+	).Scan(&organizationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrPortalLinkInvalid
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("get portal link organization: %w", err)
+	}
+	return organizationID, nil
+}
+
+// RevokeLeadPortalAccess revokes all magic links of a lead and expires its
+// legacy public token.
+func (r *Repository) RevokeLeadPortalAccess(ctx context.Context, leadID, organizationID uuid.UUID, reason string) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_lead_portal_links
+		SET revoked_at = now(), revoked_reason = $3
+		WHERE lead_id = $1 AND organization_id = $2 AND revoked_at IS NULL`,
+		leadID, organizationID, reason,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("revoke portal links: %w", err)
+	}
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_leads
+		SET public_token_expires_at = now()
+		WHERE id = $1 AND organization_id = $2
+			AND public_token IS NOT NULL
+			AND (public_token_expires_at IS NULL OR public_token_expires_at > now())`,
+		leadID, organizationID,
+	); err != nil {
+		return 0, fmt.Errorf("expire lead public token: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// HasOpenLeadServices reports whether a lead still has a service outside the
+// terminal pipeline stages.
+func (r *Repository) HasOpenLeadServices(ctx context.Context, leadID, organizationID uuid.UUID) (bool, error) {
+	var open bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM RAC_lead_services
+			WHERE lead_id = $1 AND organization_id = $2
+				AND pipeline_stage::text NOT IN ($3, $4)
+		)`, leadID, organizationID, domain.PipelineStageCompleted, domain.PipelineStageLost,
+	).Scan(&open)
+	if err != nil {
+		return false, fmt.Errorf("check open lead services: %w", err)
+	}
+	return open, nil
+}
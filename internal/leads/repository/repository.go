@@ -12,6 +12,8 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"portal_final_backend/internal/eventoutbox"
+	"portal_final_backend/internal/events"
 	leadsdb "portal_final_backend/internal/leads/db"
 	"portal_final_backend/platform/apperr"
 )
@@ -21,6 +23,7 @@ var ErrNotFound = errors.New("lead not found")
 type Repository struct {
 	pool    *pgxpool.Pool
 	queries *leadsdb.Queries
+	contact *ContactCipher
 }
 
 func New(pool *pgxpool.Pool) *Repository {
@@ -63,6 +66,15 @@ type Lead struct {
 	Longitude                               *float64
 	AssignedAgentID                         *uuid.UUID
 	Source                                  *string
+	GCLID                                   *string
+	FBCLID                                  *string
+	UTMSource                               *string
+	UTMMedium                               *string
+	UTMCampaign                             *string
+	UTMContent                              *string
+	UTMTerm                                 *string
+	AdLandingPage                           *string
+	ReferrerURL                             *string
 	WhatsAppOptedIn                         bool
 	EnergyClass                             *string
 	EnergyIndex                             *float64
@@ -102,6 +114,7 @@ type Lead struct {
 	ViewedAt                                *time.Time
 	CreatedAt                               time.Time
 	UpdatedAt                               time.Time
+	RowVersion                              int64
 }
 
 // LeadSummary is a lightweight lead representation for returning customer detection
@@ -396,6 +409,14 @@ func leadFromDB(row leadsdb.RacLead) Lead {
 		Longitude:                               optionalFloat64(row.Longitude),
 		AssignedAgentID:                         optionalUUID(row.AssignedAgentID),
 		Source:                                  optionalString(row.Source),
+		GCLID:                                   optionalString(row.Gclid),
+		UTMSource:                               optionalString(row.UtmSource),
+		UTMMedium:                               optionalString(row.UtmMedium),
+		UTMCampaign:                             optionalString(row.UtmCampaign),
+		UTMContent:                              optionalString(row.UtmContent),
+		UTMTerm:                                 optionalString(row.UtmTerm),
+		AdLandingPage:                           optionalString(row.AdLandingPage),
+		ReferrerURL:                             optionalString(row.ReferrerUrl),
 		WhatsAppOptedIn:                         row.WhatsappOptedIn,
 		EnergyClass:                             optionalString(row.EnergyClass),
 		EnergyIndex:                             optionalFloat64(row.EnergyIndex),
@@ -454,6 +475,7 @@ type CreateLeadParams struct {
 	AssignedAgentID    *uuid.UUID
 	Source             *string
 	GCLID              *string
+	FBCLID             *string
 	UTMSource          *string
 	UTMMedium          *string
 	UTMCampaign        *string
@@ -465,7 +487,13 @@ type CreateLeadParams struct {
 }
 
 func (r *Repository) Create(ctx context.Context, params CreateLeadParams) (Lead, error) {
-	row, err := r.queries.CreateLead(ctx, leadsdb.CreateLeadParams{
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return Lead{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	row, err := r.queries.WithTx(tx).CreateLead(ctx, leadsdb.CreateLeadParams{
 		OrganizationID:     toPgUUID(params.OrganizationID),
 		ConsumerFirstName:  params.ConsumerFirstName,
 		ConsumerLastName:   params.ConsumerLastName,
@@ -494,7 +522,24 @@ func (r *Repository) Create(ctx context.Context, params CreateLeadParams) (Lead,
 		return Lead{}, err
 	}
 
-	return leadFromDB(row), nil
+	lead := leadFromDB(row)
+	if err := r.writeLeadContact(ctx, tx, lead.ID, lead.OrganizationID, lead.ConsumerPhone, lead.ConsumerEmail); err != nil {
+		return Lead{}, err
+	}
+	if err := setDetectedLeadLanguage(ctx, tx, lead.ID, lead.OrganizationID, lead.ConsumerPhone); err != nil {
+		return Lead{}, err
+	}
+	if params.FBCLID != nil {
+		if err := setLeadFBCLID(ctx, tx, lead.ID, lead.OrganizationID, *params.FBCLID); err != nil {
+			return Lead{}, err
+		}
+		lead.FBCLID = params.FBCLID
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Lead{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return lead, nil
 }
 
 func (r *Repository) GetByID(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (Lead, error) {
@@ -508,7 +553,11 @@ func (r *Repository) GetByID(ctx context.Context, id uuid.UUID, organizationID u
 	if row.DeletedAt.Valid {
 		return Lead{}, ErrNotFound
 	}
-	return leadFromDB(row), nil
+	lead := leadFromDB(row)
+	if err := r.openLeadContacts(ctx, r.pool, &lead); err != nil {
+		return Lead{}, err
+	}
+	return lead, nil
 }
 
 // GetByIDWithServices returns a lead with all its services populated.
@@ -533,6 +582,15 @@ func (r *Repository) GetByIDWithServices(ctx context.Context, id uuid.UUID, orga
 		return Lead{}, nil, ErrNotFound
 	}
 	lead := leadFromDB(row)
+	if err := r.openLeadContacts(ctx, tx, &lead); err != nil {
+		return Lead{}, nil, err
+	}
+	if lead.FBCLID, err = getLeadFBCLID(ctx, tx, lead.ID, lead.OrganizationID); err != nil {
+		return Lead{}, nil, err
+	}
+	if lead.RowVersion, err = getLeadRowVersion(ctx, tx, lead.ID, lead.OrganizationID); err != nil {
+		return Lead{}, nil, err
+	}
 
 	rows, err := qtx.ListLeadServices(ctx, leadsdb.ListLeadServicesParams{LeadID: toPgUUID(id), OrganizationID: toPgUUID(organizationID)})
 	if err != nil {
@@ -542,6 +600,9 @@ func (r *Repository) GetByIDWithServices(ctx context.Context, id uuid.UUID, orga
 	for _, s := range rows {
 		services = append(services, leadServiceFromListRow(s))
 	}
+	if err := attachServiceRowVersions(ctx, tx, lead.ID, lead.OrganizationID, services); err != nil {
+		return Lead{}, nil, err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return Lead{}, nil, fmt.Errorf("commit tx: %w", err)
@@ -550,6 +611,13 @@ func (r *Repository) GetByIDWithServices(ctx context.Context, id uuid.UUID, orga
 }
 
 func (r *Repository) GetByPhone(ctx context.Context, phone string, organizationID uuid.UUID) (Lead, error) {
+	if r.contact != nil {
+		id, err := r.findLeadIDByContact(ctx, phone, "", organizationID)
+		if err != nil {
+			return Lead{}, err
+		}
+		return r.GetByID(ctx, id, organizationID)
+	}
 	row, err := r.queries.GetLeadByPhone(ctx, leadsdb.GetLeadByPhoneParams{ConsumerPhone: phone, OrganizationID: toPgUUID(organizationID)})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return Lead{}, ErrNotFound
@@ -574,6 +642,9 @@ func (r *Repository) GetByPhoneOrEmail(ctx context.Context, phone string, email
 	if phone == "" && email == "" {
 		return nil, nil, nil
 	}
+	if r.contact != nil {
+		return r.getSummaryByContact(ctx, phone, email, organizationID)
+	}
 
 	row, err := r.queries.GetLeadSummaryByPhoneOrEmail(ctx, leadsdb.GetLeadSummaryByPhoneOrEmailParams{
 		Column1:        nonEmptyStringOrNil(phone),
@@ -609,6 +680,45 @@ func (r *Repository) GetByPhoneOrEmail(ctx context.Context, phone string, email
 	return &summary, services, nil
 }
 
+// getSummaryByContact is GetByPhoneOrEmail through the contact blind indexes.
+func (r *Repository) getSummaryByContact(ctx context.Context, phone string, email string, organizationID uuid.UUID) (*LeadSummary, []LeadService, error) {
+	id, err := r.findLeadIDByContact(ctx, phone, email, organizationID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	lead, err := r.GetByID(ctx, id, organizationID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	services, err := r.ListLeadServices(ctx, id, organizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary := LeadSummary{
+		ID:             lead.ID,
+		OrganizationID: lead.OrganizationID,
+		ConsumerName:   lead.ConsumerFirstName + " " + lead.ConsumerLastName,
+		ConsumerPhone:  lead.ConsumerPhone,
+		ConsumerEmail:  lead.ConsumerEmail,
+		AddressCity:    lead.AddressCity,
+		ServiceCount:   len(services),
+		CreatedAt:      lead.CreatedAt,
+	}
+	// Services are listed newest first.
+	if len(services) > 0 {
+		summary.LastServiceType = emptyStringAsNil(services[0].ServiceType)
+		summary.LastStatus = emptyStringAsNil(services[0].Status)
+	}
+	return &summary, services, nil
+}
+
 // GetLatestAcceptedQuoteIDForService returns the most recent Accepted quote ID for a lead service.
 // This is used by agent tooling to create partner offers in quote-only mode.
 func (r *Repository) GetLatestAcceptedQuoteIDForService(ctx context.Context, serviceID, organizationID uuid.UUID) (uuid.UUID, error) {
@@ -663,6 +773,9 @@ type UpdateLeadParams struct {
 	AssignedAgentIDSet bool
 	WhatsAppOptedIn    *bool
 	WhatsAppOptedInSet bool
+	// ExpectedRowVersion makes the update fail with ErrVersionConflict when the
+	// lead changed since the caller read it.
+	ExpectedRowVersion *int64
 }
 
 type UpdateEnergyLabelParams struct {
@@ -719,6 +832,13 @@ func boolValue(value *bool) bool {
 	return *value
 }
 
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
 func (r *Repository) Update(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, params UpdateLeadParams) (Lead, error) {
 	hasUpdates := params.ConsumerFirstName != nil ||
 		params.ConsumerLastName != nil ||
@@ -735,10 +855,26 @@ func (r *Repository) Update(ctx context.Context, id uuid.UUID, organizationID uu
 		params.WhatsAppOptedInSet
 
 	if !hasUpdates {
-		return r.GetByID(ctx, id, organizationID)
+		return r.getUnchanged(ctx, id, organizationID, params.ExpectedRowVersion)
 	}
 
-	row, err := r.queries.UpdateLead(ctx, leadsdb.UpdateLeadParams{
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return Lead{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if params.ExpectedRowVersion != nil {
+		current, err := lockLeadRowVersion(ctx, tx, id, organizationID)
+		if err != nil {
+			return Lead{}, err
+		}
+		if current != *params.ExpectedRowVersion {
+			return Lead{}, ErrVersionConflict
+		}
+	}
+
+	row, err := r.queries.WithTx(tx).UpdateLead(ctx, leadsdb.UpdateLeadParams{
 		ConsumerFirstName:  toPgText(params.ConsumerFirstName),
 		ConsumerLastName:   toPgText(params.ConsumerLastName),
 		ConsumerPhone:      toPgText(params.ConsumerPhone),
@@ -763,7 +899,46 @@ func (r *Repository) Update(ctx context.Context, id uuid.UUID, organizationID uu
 	if err != nil {
 		return Lead{}, err
 	}
-	return leadFromDB(row), nil
+	lead := leadFromDB(row)
+	if err := r.openLeadContacts(ctx, tx, &lead); err != nil {
+		return Lead{}, err
+	}
+	// The sealed copy is the old value; the row holds the one just written.
+	if params.ConsumerPhone != nil {
+		lead.ConsumerPhone = row.ConsumerPhone
+	}
+	if params.ConsumerEmail != nil {
+		lead.ConsumerEmail = optionalString(row.ConsumerEmail)
+	}
+	if params.ConsumerPhone != nil || params.ConsumerEmail != nil {
+		if err := r.writeLeadContact(ctx, tx, id, organizationID, lead.ConsumerPhone, lead.ConsumerEmail); err != nil {
+			return Lead{}, err
+		}
+	}
+	if lead.RowVersion, err = getLeadRowVersion(ctx, tx, id, organizationID); err != nil {
+		return Lead{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Lead{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return lead, nil
+}
+
+// getUnchanged returns the lead for an update without changes, still failing
+// when the caller's version is outdated.
+func (r *Repository) getUnchanged(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, expected *int64) (Lead, error) {
+	lead, err := r.GetByID(ctx, id, organizationID)
+	if err != nil {
+		return Lead{}, err
+	}
+	if lead.RowVersion, err = getLeadRowVersion(ctx, r.pool, id, organizationID); err != nil {
+		return Lead{}, err
+	}
+	if expected != nil && lead.RowVersion != *expected {
+		return Lead{}, ErrVersionConflict
+	}
+	return lead, nil
 }
 
 func (r *Repository) UpdateEnergyLabel(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, params UpdateEnergyLabelParams) error {
@@ -908,14 +1083,16 @@ type ListParams struct {
 	AssignedAgentID *uuid.UUID
 	CreatedAtFrom   *time.Time
 	CreatedAtTo     *time.Time
-	Offset          int
-	Limit           int
-	SortBy          string
-	SortOrder       string
+	// CustomFields is a JSON object the lead's custom field values must contain.
+	CustomFields []byte
+	Offset       int
+	Limit        int
+	SortBy       string
+	SortOrder    string
 }
 
 func (r *Repository) List(ctx context.Context, params ListParams) ([]Lead, int, error) {
-	filters := buildLeadListFilters(params)
+	filters := buildLeadListFilters(params, r.contact)
 
 	sortBy, err := resolveLeadSortBy(params.SortBy)
 	if err != nil {
@@ -936,22 +1113,27 @@ func (r *Repository) List(ctx context.Context, params ListParams) ([]Lead, int,
 	qtx := r.queries.WithTx(tx)
 
 	total, err := qtx.CountLeads(ctx, leadsdb.CountLeadsParams{
-		OrganizationID: toPgUUID(params.OrganizationID),
-		Status:         filters.status,
-		ServiceType:    filters.serviceType,
-		Search:         filters.search,
-		FirstName:      filters.firstName,
-		LastName:       filters.lastName,
-		Phone:          filters.phone,
-		Email:          filters.email,
-		Role:           filters.role,
-		Street:         filters.street,
-		HouseNumber:    filters.houseNumber,
-		ZipCode:        filters.zipCode,
-		City:           filters.city,
+		OrganizationID:  toPgUUID(params.OrganizationID),
+		Status:          filters.status,
+		ServiceType:     filters.serviceType,
+		Search:          filters.search,
+		SearchPhoneBidx: filters.searchPhoneBidx,
+		SearchEmailBidx: filters.searchEmailBidx,
+		FirstName:       filters.firstName,
+		LastName:        filters.lastName,
+		Phone:           filters.phone,
+		PhoneBidx:       filters.phoneBidx,
+		Email:           filters.email,
+		EmailBidx:       filters.emailBidx,
+		Role:            filters.role,
+		Street:          filters.street,
+		HouseNumber:     filters.houseNumber,
+		ZipCode:         filters.zipCode,
+		City:            filters.city,
 		AssignedAgentID: filters.assignedAgentID,
-		CreatedAtFrom:  filters.createdAtFrom,
-		CreatedAtTo:    filters.createdAtTo,
+		CreatedAtFrom:   filters.createdAtFrom,
+		CreatedAtTo:     filters.createdAtTo,
+		CustomFields:    params.CustomFields,
 	})
 	if err != nil {
 		return nil, 0, err
@@ -962,10 +1144,14 @@ func (r *Repository) List(ctx context.Context, params ListParams) ([]Lead, int,
 		Status:          filters.status,
 		ServiceType:     filters.serviceType,
 		Search:          filters.search,
+		SearchPhoneBidx: filters.searchPhoneBidx,
+		SearchEmailBidx: filters.searchEmailBidx,
 		FirstName:       filters.firstName,
 		LastName:        filters.lastName,
 		Phone:           filters.phone,
+		PhoneBidx:       filters.phoneBidx,
 		Email:           filters.email,
+		EmailBidx:       filters.emailBidx,
 		Role:            filters.role,
 		Street:          filters.street,
 		HouseNumber:     filters.houseNumber,
@@ -974,6 +1160,7 @@ func (r *Repository) List(ctx context.Context, params ListParams) ([]Lead, int,
 		AssignedAgentID: filters.assignedAgentID,
 		CreatedAtFrom:   filters.createdAtFrom,
 		CreatedAtTo:     filters.createdAtTo,
+		CustomFields:    params.CustomFields,
 		SortBy:          sortBy,
 		SortOrder:       sortOrder,
 		OffsetCount:     int32(params.Offset),
@@ -983,9 +1170,14 @@ func (r *Repository) List(ctx context.Context, params ListParams) ([]Lead, int,
 		return nil, 0, err
 	}
 
-	leads := make([]Lead, 0, len(rows))
-	for _, row := range rows {
-		leads = append(leads, leadFromDB(row))
+	leads := make([]Lead, len(rows))
+	sealed := make([]*Lead, len(rows))
+	for i, row := range rows {
+		leads[i] = leadFromDB(row)
+		sealed[i] = &leads[i]
+	}
+	if err := r.openLeadContacts(ctx, tx, sealed...); err != nil {
+		return nil, 0, err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -1002,6 +1194,10 @@ type leadListFilters struct {
 	lastName        pgtype.Text
 	phone           pgtype.Text
 	email           pgtype.Text
+	searchPhoneBidx pgtype.Text
+	searchEmailBidx pgtype.Text
+	phoneBidx       pgtype.Text
+	emailBidx       pgtype.Text
 	role            pgtype.Text
 	street          pgtype.Text
 	houseNumber     pgtype.Text
@@ -1012,7 +1208,10 @@ type leadListFilters struct {
 	createdAtTo     pgtype.Timestamptz
 }
 
-func buildLeadListFilters(params ListParams) leadListFilters {
+// buildLeadListFilters turns the list parameters into query filters. Sealed
+// contact details only match the phone and email filters and the search term
+// exactly, through their blind indexes.
+func buildLeadListFilters(params ListParams, contact *ContactCipher) leadListFilters {
 	return leadListFilters{
 		status:          toPgText(params.Status),
 		serviceType:     toPgText(params.ServiceType),
@@ -1021,6 +1220,10 @@ func buildLeadListFilters(params ListParams) leadListFilters {
 		lastName:        optionalLikeParam(params.LastName),
 		phone:           optionalLikeParam(params.Phone),
 		email:           optionalLikeParam(params.Email),
+		searchPhoneBidx: optionalBlindIndex(contact.PhoneBlindIndex(params.Search)),
+		searchEmailBidx: optionalBlindIndex(contact.EmailBlindIndex(params.Search)),
+		phoneBidx:       optionalBlindIndex(contact.PhoneBlindIndex(stringValue(params.Phone))),
+		emailBidx:       optionalBlindIndex(contact.EmailBlindIndex(stringValue(params.Email))),
 		role:            toPgText(params.Role),
 		street:          optionalLikeParam(params.Street),
 		houseNumber:     optionalLikeParam(params.HouseNumber),
@@ -1040,6 +1243,13 @@ func optionalLikeParam(value *string) pgtype.Text {
 	return pgtype.Text{String: text, Valid: true}
 }
 
+func optionalBlindIndex(index string) pgtype.Text {
+	if index == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: index, Valid: true}
+}
+
 func optionalSearchParam(value string) pgtype.Text {
 	if value == "" {
 		return pgtype.Text{}
@@ -1246,14 +1456,22 @@ func (r *Repository) GetByPublicToken(ctx context.Context, token string) (Lead,
 	if row.DeletedAt.Valid {
 		return Lead{}, ErrNotFound
 	}
-	return leadFromDB(row), nil
+	lead := leadFromDB(row)
+	if err := r.openLeadContacts(ctx, r.pool, &lead); err != nil {
+		return Lead{}, err
+	}
+	return lead, nil
 }
 
-func (r *Repository) SetPublicToken(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, token string, expiresAt time.Time) error {
-	return r.queries.SetLeadPublicToken(ctx, leadsdb.SetLeadPublicTokenParams{
-		ID:                   toPgUUID(id),
-		OrganizationID:       toPgUUID(organizationID),
-		PublicToken:          toPgTextValue(token),
-		PublicTokenExpiresAt: toPgTimestamp(expiresAt),
-	})
+// SetPublicToken stores the public token of a lead and writes the pending
+// events to the domain event outbox in the same transaction.
+func (r *Repository) SetPublicToken(ctx context.Context, id uuid.UUID, organizationID uuid.UUID, token string, expiresAt time.Time, pendingEvents ...events.Event) error {
+	return eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		return r.queries.WithTx(tx).SetLeadPublicToken(ctx, leadsdb.SetLeadPublicTokenParams{
+			ID:                   toPgUUID(id),
+			OrganizationID:       toPgUUID(organizationID),
+			PublicToken:          toPgTextValue(token),
+			PublicTokenExpiresAt: toPgTimestamp(expiresAt),
+		})
+	}, pendingEvents...)
 }
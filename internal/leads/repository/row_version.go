@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrVersionConflict is returned when a lead or lead service changed after the
+// version the caller based its update on.
+var ErrVersionConflict = errors.New("version conflict")
+
+// The row_version columns were added after the generated lead queries, so they
+// are read separately from the rows. A trigger bumps them on every update.
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func getLeadRowVersion(ctx context.Context, q rowQuerier, leadID, organizationID uuid.UUID) (int64, error) {
+	var version int64
+	err := q.QueryRow(ctx, `SELECT row_version FROM RAC_leads WHERE id = $1 AND organization_id = $2`, leadID, organizationID).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get lead row version: %w", err)
+	}
+	return version, nil
+}
+
+// lockLeadRowVersion locks the lead for the rest of the transaction and
+// returns its current version.
+func lockLeadRowVersion(ctx context.Context, tx pgx.Tx, leadID, organizationID uuid.UUID) (int64, error) {
+	var version int64
+	err := tx.QueryRow(ctx, `
+		SELECT row_version FROM RAC_leads
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+		FOR UPDATE`, leadID, organizationID,
+	).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("lock lead row version: %w", err)
+	}
+	return version, nil
+}
+
+func attachServiceRowVersions(ctx context.Context, q rowQuerier, leadID, organizationID uuid.UUID, services []LeadService) error {
+	if len(services) == 0 {
+		return nil
+	}
+	rows, err := q.Query(ctx, `SELECT id, row_version FROM RAC_lead_services WHERE lead_id = $1 AND organization_id = $2`, leadID, organizationID)
+	if err != nil {
+		return fmt.Errorf("list lead service row versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[uuid.UUID]int64, len(services))
+	for rows.Next() {
+		var id uuid.UUID
+		var version int64
+		if err := rows.Scan(&id, &version); err != nil {
+			return fmt.Errorf("scan lead service row version: %w", err)
+		}
+		versions[id] = version
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for i := range services {
+		services[i].RowVersion = versions[services[i].ID]
+	}
+	return nil
+}
+
+// ClaimLeadServiceVersion bumps the version of a lead service if it still is
+// the expected one, so concurrent writers that read the same version cannot
+// both go ahead. Updates that follow the claim bump the version again.
+func (r *Repository) ClaimLeadServiceVersion(ctx context.Context, serviceID, organizationID uuid.UUID, expected int64) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_lead_services SET row_version = row_version + 1
+		WHERE id = $1 AND organization_id = $2 AND row_version = $3`, serviceID, organizationID, expected,
+	)
+	if err != nil {
+		return fmt.Errorf("claim lead service version: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM RAC_lead_services WHERE id = $1 AND organization_id = $2)`, serviceID, organizationID).Scan(&exists); err != nil {
+		return fmt.Errorf("check lead service: %w", err)
+	}
+	if !exists {
+		return ErrServiceNotFound
+	}
+	return ErrVersionConflict
+}
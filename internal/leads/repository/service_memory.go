@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lead service memory kinds.
+const (
+	ServiceMemoryKindDimension      = "dimension"
+	ServiceMemoryKindPreference     = "preference"
+	ServiceMemoryKindRejectedOption = "rejected_option"
+	ServiceMemoryKindFact           = "fact"
+	ServiceMemoryKindSummary        = "summary"
+)
+
+// ServiceMemory is a distilled fact an agent learned about a lead service.
+type ServiceMemory struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	LeadServiceID  uuid.UUID
+	Kind           string
+	Content        string
+	Source         string
+	RunID          *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// AddServiceMemoryParams holds a fact to remember for a lead service.
+type AddServiceMemoryParams struct {
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	LeadServiceID  uuid.UUID
+	Kind           string
+	Content        string
+	Source         string
+	RunID          *string
+}
+
+// ListServiceMemories returns the remembered facts of a lead service: the
+// summary of compacted facts first, then the others oldest first.
+func (r *Repository) ListServiceMemories(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID) ([]ServiceMemory, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, organization_id, lead_id, lead_service_id, kind, content, source, run_id, created_at, updated_at
+		FROM RAC_lead_service_memories
+		WHERE lead_service_id = $1 AND organization_id = $2
+		ORDER BY kind <> 'summary', created_at, id`, serviceID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list service memories: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ServiceMemory, 0)
+	for rows.Next() {
+		var m ServiceMemory
+		if err := rows.Scan(&m.ID, &m.OrganizationID, &m.LeadID, &m.LeadServiceID, &m.Kind, &m.Content,
+			&m.Source, &m.RunID, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan service memory: %w", err)
+		}
+		items = append(items, m)
+	}
+	return items, rows.Err()
+}
+
+// AddServiceMemory stores a fact for a lead service. Remembering a fact that is
+// already stored only refreshes its source and run.
+func (r *Repository) AddServiceMemory(ctx context.Context, params AddServiceMemoryParams) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_lead_service_memories (organization_id, lead_id, lead_service_id, kind, content, source, run_id)
+		SELECT $1, ls.lead_id, ls.id, $4, $5, $6, $7
+		FROM RAC_lead_services ls
+		WHERE ls.id = $3 AND ls.organization_id = $1 AND ls.lead_id = $2
+		ON CONFLICT (lead_service_id, kind, lower(content)) DO UPDATE SET
+			source = EXCLUDED.source,
+			run_id = EXCLUDED.run_id,
+			updated_at = now()`,
+		params.OrganizationID, params.LeadID, params.LeadServiceID, params.Kind, params.Content, params.Source, params.RunID)
+	if err != nil {
+		return fmt.Errorf("add service memory: %w", err)
+	}
+	return nil
+}
+
+// CompactServiceMemories removes the given facts of a lead service and replaces
+// its summary entry with summary, in one transaction.
+func (r *Repository) CompactServiceMemories(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID, removeIDs []uuid.UUID, summary string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin compact service memories: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM RAC_lead_service_memories
+		WHERE lead_service_id = $1 AND organization_id = $2 AND (id = ANY($3) OR kind = $4)`,
+		serviceID, organizationID, removeIDs, ServiceMemoryKindSummary); err != nil {
+		return fmt.Errorf("remove compacted service memories: %w", err)
+	}
+	if summary != "" {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_lead_service_memories (organization_id, lead_id, lead_service_id, kind, content, source)
+			SELECT $2, ls.lead_id, ls.id, $3, $4, 'compaction'
+			FROM RAC_lead_services ls
+			WHERE ls.id = $1 AND ls.organization_id = $2`,
+			serviceID, organizationID, ServiceMemoryKindSummary, summary); err != nil {
+			return fmt.Errorf("store service memory summary: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit compact service memories: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// StageTransitionActor identifies who moved a lead service to another
+// pipeline stage and why.
+type StageTransitionActor struct {
+	Type   string
+	ID     *uuid.UUID
+	Name   string
+	Reason string
+}
+
+type stageTransitionActorKey struct{}
+
+// WithStageTransitionActor attributes the stage changes made with the returned
+// context to actor. Changes made without one are attributed to the system.
+func WithStageTransitionActor(ctx context.Context, actor StageTransitionActor) context.Context {
+	return context.WithValue(ctx, stageTransitionActorKey{}, actor)
+}
+
+func stageTransitionActorFromContext(ctx context.Context) StageTransitionActor {
+	actor, _ := ctx.Value(stageTransitionActorKey{}).(StageTransitionActor)
+	if strings.TrimSpace(actor.Type) == "" {
+		actor.Type = ActorTypeSystem
+	}
+	if strings.TrimSpace(actor.Name) == "" {
+		actor.Name = actor.Type
+	}
+	return actor
+}
+
+// StageTransition is one recorded pipeline stage change of a lead service.
+type StageTransition struct {
+	ID            uuid.UUID
+	LeadID        uuid.UUID
+	LeadServiceID uuid.UUID
+	FromStage     *string
+	ToStage       string
+	ActorType     string
+	ActorID       *uuid.UUID
+	ActorName     string
+	Reason        *string
+	OccurredAt    time.Time
+}
+
+type lockedServiceStage struct {
+	id     uuid.UUID
+	leadID uuid.UUID
+	stage  string
+}
+
+// lockServiceStage locks the service for the rest of the transaction and
+// returns its current stage.
+func lockServiceStage(ctx context.Context, tx pgx.Tx, id, organizationID uuid.UUID) (lockedServiceStage, error) {
+	locked := lockedServiceStage{id: id}
+	err := tx.QueryRow(ctx, `
+		SELECT lead_id, pipeline_stage FROM RAC_lead_services
+		WHERE id = $1 AND organization_id = $2
+		FOR UPDATE`, id, organizationID,
+	).Scan(&locked.leadID, &locked.stage)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return lockedServiceStage{}, ErrServiceNotFound
+	}
+	if err != nil {
+		return lockedServiceStage{}, fmt.Errorf("lock lead service stage: %w", err)
+	}
+	return locked, nil
+}
+
+func lockActiveServiceStages(ctx context.Context, tx pgx.Tx, leadID, organizationID uuid.UUID) ([]lockedServiceStage, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, lead_id, pipeline_stage FROM RAC_lead_services
+		WHERE lead_id = $1 AND organization_id = $2 AND pipeline_stage NOT IN ('Completed', 'Lost')
+		FOR UPDATE`, leadID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("lock active lead service stages: %w", err)
+	}
+	defer rows.Close()
+
+	var locked []lockedServiceStage
+	for rows.Next() {
+		var s lockedServiceStage
+		if err := rows.Scan(&s.id, &s.leadID, &s.stage); err != nil {
+			return nil, fmt.Errorf("scan lead service stage: %w", err)
+		}
+		locked = append(locked, s)
+	}
+	return locked, rows.Err()
+}
+
+// recordStageTransition stores the change from the locked stage to stage,
+// if there is one, in the transaction of the change.
+func recordStageTransition(ctx context.Context, tx pgx.Tx, organizationID uuid.UUID, from lockedServiceStage, stage string) error {
+	if from.stage == stage {
+		return nil
+	}
+	actor := stageTransitionActorFromContext(ctx)
+	_, err := tx.Exec(ctx, `
+		INSERT INTO RAC_lead_service_stage_transitions
+			(organization_id, lead_id, lead_service_id, from_stage, to_stage, actor_type, actor_id, actor_name, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''))`,
+		organizationID, from.leadID, from.id, from.stage, stage, actor.Type, actor.ID, actor.Name, strings.TrimSpace(actor.Reason),
+	)
+	if err != nil {
+		return fmt.Errorf("insert stage transition: %w", err)
+	}
+	return nil
+}
+
+// ListStageTransitions returns the stage changes of a lead service, oldest first.
+func (r *Repository) ListStageTransitions(ctx context.Context, serviceID uuid.UUID, organizationID uuid.UUID) ([]StageTransition, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, lead_id, lead_service_id, from_stage, to_stage, actor_type, actor_id, actor_name, reason, occurred_at
+		FROM RAC_lead_service_stage_transitions
+		WHERE lead_service_id = $1 AND organization_id = $2
+		ORDER BY occurred_at, id`, serviceID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list stage transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []StageTransition
+	for rows.Next() {
+		var t StageTransition
+		if err := rows.Scan(&t.ID, &t.LeadID, &t.LeadServiceID, &t.FromStage, &t.ToStage, &t.ActorType, &t.ActorID, &t.ActorName, &t.Reason, &t.OccurredAt); err != nil {
+			return nil, fmt.Errorf("scan stage transition: %w", err)
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
@@ -59,6 +59,19 @@ type CreateTimelineEventParams struct {
 	Summary        *string
 	Metadata       map[string]any
 	Visibility     string
+	// DedupKey makes the write idempotent: a second write with the same key
+	// for the lead returns the existing event. Build it with TimelineDedupKey.
+	DedupKey string
+}
+
+// TimelineDedupKey joins what identifies a timeline write, e.g.
+// TimelineDedupKey("partner_offer_created", offerID).
+func TimelineDedupKey(parts ...any) string {
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		values = append(values, fmt.Sprint(part))
+	}
+	return strings.Join(values, ":")
 }
 
 func normalizeTimelineVisibility(value string) string {
@@ -74,6 +87,10 @@ func normalizeTimelineVisibility(value string) string {
 
 func (r *Repository) CreateTimelineEvent(ctx context.Context, params CreateTimelineEventParams) (TimelineEvent, error) {
 	params.Visibility = normalizeTimelineVisibility(params.Visibility)
+	params.DedupKey = strings.TrimSpace(params.DedupKey)
+	if params.DedupKey != "" {
+		return r.createTimelineEventOnce(ctx, params)
+	}
 	if shouldAttemptTimelineDedup(params) {
 		var existing TimelineEvent
 		var found bool
@@ -118,6 +135,47 @@ func (r *Repository) CreateTimelineEvent(ctx context.Context, params CreateTimel
 	return event, nil
 }
 
+// createTimelineEventOnce inserts the event unless the lead already has one
+// with the same dedup key, which it then returns.
+func (r *Repository) createTimelineEventOnce(ctx context.Context, params CreateTimelineEventParams) (TimelineEvent, error) {
+	metadataJSON, err := json.Marshal(params.Metadata)
+	if err != nil {
+		return TimelineEvent{}, err
+	}
+
+	var p timelineEventSourceParams
+	err = r.pool.QueryRow(ctx, `
+		INSERT INTO lead_timeline_events (
+			lead_id, service_id, organization_id, actor_type, actor_name, event_type, title, summary, metadata, visibility, dedup_key
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (lead_id, dedup_key) WHERE dedup_key IS NOT NULL DO NOTHING
+		RETURNING id, lead_id, service_id, organization_id, actor_type, actor_name, event_type, title, summary, metadata, visibility, created_at`,
+		toPgUUID(params.LeadID), toPgUUIDPtr(params.ServiceID), toPgUUID(params.OrganizationID),
+		params.ActorType, params.ActorName, params.EventType, params.Title, toPgText(params.Summary),
+		metadataJSON, params.Visibility, params.DedupKey,
+	).Scan(&p.ID, &p.LeadID, &p.ServiceID, &p.OrganizationID, &p.ActorType, &p.ActorName, &p.EventType, &p.Title, &p.Summary, &p.Metadata, &p.Visibility, &p.CreatedAt)
+	if err == nil {
+		event := timelineEventFromSource(p)
+		event.Metadata = params.Metadata
+		return event, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return TimelineEvent{}, err
+	}
+
+	err = r.pool.QueryRow(ctx, `
+		SELECT id, lead_id, service_id, organization_id, actor_type, actor_name, event_type, title, summary, metadata, visibility, created_at
+		FROM lead_timeline_events
+		WHERE lead_id = $1 AND dedup_key = $2`,
+		toPgUUID(params.LeadID), params.DedupKey,
+	).Scan(&p.ID, &p.LeadID, &p.ServiceID, &p.OrganizationID, &p.ActorType, &p.ActorName, &p.EventType, &p.Title, &p.Summary, &p.Metadata, &p.Visibility, &p.CreatedAt)
+	if err != nil {
+		return TimelineEvent{}, err
+	}
+	return timelineEventFromSource(p), nil
+}
+
 func shouldAttemptTimelineDedup(params CreateTimelineEventParams) bool {
 	if params.ActorType != ActorTypeAI && params.ActorType != ActorTypeSystem {
 		return false
@@ -229,6 +287,38 @@ func (r *Repository) ListTimelineEventsByService(ctx context.Context, leadID uui
 	return items, nil
 }
 
+// ListTimelineEventsByLeadIDs returns the most recent timeline events of several leads in
+// one query, newest first per lead. limitPerLead caps the events per lead; zero means no cap.
+func (r *Repository) ListTimelineEventsByLeadIDs(ctx context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID, limitPerLead int) ([]TimelineEvent, error) {
+	if len(leadIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, lead_id, service_id, organization_id, actor_type, actor_name, event_type, title, summary, metadata, visibility, created_at
+		FROM (
+			SELECT e.*, ROW_NUMBER() OVER (PARTITION BY e.lead_id ORDER BY e.created_at DESC) AS lead_rank
+			FROM lead_timeline_events e
+			WHERE e.lead_id = ANY($1) AND e.organization_id = $2
+		) ranked
+		WHERE $3::int <= 0 OR lead_rank <= $3::int
+		ORDER BY lead_id, created_at DESC`,
+		toPgUUIDSlice(leadIDs), toPgUUID(organizationID), limitPerLead)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []TimelineEvent
+	for rows.Next() {
+		var p timelineEventSourceParams
+		if err := rows.Scan(&p.ID, &p.LeadID, &p.ServiceID, &p.OrganizationID, &p.ActorType, &p.ActorName, &p.EventType, &p.Title, &p.Summary, &p.Metadata, &p.Visibility, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, timelineEventFromSource(p))
+	}
+	return events, rows.Err()
+}
+
 type timelineEventSourceParams struct {
 	ID             pgtype.UUID
 	LeadID         pgtype.UUID
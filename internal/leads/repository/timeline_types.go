@@ -35,6 +35,7 @@ const (
 	EventTypeNote                   = "note"
 	EventTypeCallLog                = "call_log"
 	EventTypeCallOutcome            = "call_outcome"
+	EventTypeCallRecord             = "call_record"
 	EventTypeStageChange            = "stage_change"
 	EventTypeAI                     = "ai"
 	EventTypeAnalysis               = "analysis"
@@ -46,7 +47,13 @@ const (
 	EventTypeServiceTypeChange      = "service_type_change"
 	EventTypeLeadUpdate             = "lead_update"
 	EventTypePartnerSearch          = "partner_search"
+	EventTypeVisitStarted           = "visit_started"
 	EventTypeVisitCompleted         = "visit_completed"
+	EventTypeWorkOrder              = "work_order"
+	EventTypeEnergyLabelChanged     = "energy_label_changed"
+	EventTypeConsentChanged         = "consent_changed"
+	EventTypeMarketingConfirmed     = "marketing_confirmed"
+	EventTypeLeadHandover           = "lead_handover"
 )
 
 // EventTitle constants are the human-readable labels shown in the timeline UI.
@@ -54,6 +61,7 @@ const (
 	EventTitleNoteAdded              = "Notitie toegevoegd"
 	EventTitleCallLog                = "Gesprek geregistreerd"
 	EventTitleCallOutcome            = "Belresultaat"
+	EventTitleCallRecord             = "Telefoongesprek"
 	EventTitleStageUpdated           = "Fase bijgewerkt"
 	EventTitleAutoDisqualified       = "Auto-Disqualified"
 	EventTitleDispatcherFailed       = "Partner matching mislukt"
@@ -73,6 +81,10 @@ const (
 	EventTitlePreferencesUpdated     = "Voorkeuren bijgewerkt"
 	EventTitleCustomerInfo           = "Klant update"
 	EventTitleAppointmentRequested   = "Inspectie aangevraagd"
+	EventTitleEnergyLabelChanged     = "Energielabel gewijzigd"
+	EventTitleConsentChanged         = "Communicatievoorkeuren gewijzigd"
+	EventTitleMarketingConfirmed     = "Marketingtoestemming bevestigd"
+	EventTitleLeadHandover           = "Lead overgedragen"
 )
 
 // TimelineVisibility constants control whether an event is shown in the default timeline.
@@ -123,6 +135,19 @@ type CallOutcomeMetadata struct {
 
 func (m CallOutcomeMetadata) ToMap() map[string]any { return toMap(m) }
 
+// CallRecordMetadata is the typed metadata for EventTypeCallRecord events,
+// written from the call detail records of a telephony provider.
+type CallRecordMetadata struct {
+	CallID          string  `json:"callId"`
+	Provider        string  `json:"provider"`
+	Direction       string  `json:"direction"`
+	Status          string  `json:"status"`
+	DurationSeconds *int    `json:"durationSeconds,omitempty"`
+	RecordingURL    *string `json:"recordingUrl,omitempty"`
+}
+
+func (m CallRecordMetadata) ToMap() map[string]any { return toMap(m) }
+
 // StageChangeMetadata is the typed metadata for EventTypeStageChange events.
 type StageChangeMetadata struct {
 	OldStage string `json:"oldStage"`
@@ -161,6 +186,17 @@ type LeadScoreMetadata struct {
 
 func (m LeadScoreMetadata) ToMap() map[string]any { return toMap(m) }
 
+// EnergyLabelChangedMetadata is the typed metadata for EventTypeEnergyLabelChanged events.
+type EnergyLabelChangedMetadata struct {
+	PreviousClass        *string    `json:"previousClass,omitempty"`
+	Class                *string    `json:"class,omitempty"`
+	PreviousRegisteredAt *time.Time `json:"previousRegisteredAt,omitempty"`
+	RegisteredAt         *time.Time `json:"registeredAt,omitempty"`
+	Trigger              string     `json:"trigger"`
+}
+
+func (m EnergyLabelChangedMetadata) ToMap() map[string]any { return toMap(m) }
+
 // AlertMetadata is the typed metadata for EventTypeAlert events.
 type AlertMetadata struct {
 	Trigger      string `json:"trigger"`
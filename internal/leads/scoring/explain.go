@@ -0,0 +1,307 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/leads/repository"
+
+	"github.com/google/uuid"
+)
+
+// Data sources a score factor is derived from.
+const (
+	SourceCBS         = "cbs"
+	SourceEnergyLabel = "energy_label"
+	SourceLead        = "lead"
+	SourceActivity    = "activity"
+	SourceAddress     = "bag_address"
+	SourceAIAnalysis  = "ai_analysis"
+	SourceUnknown     = "unknown"
+)
+
+// FactorExplanation describes how a single factor contributed to the stored score.
+// BasePoints is the factor's score before the service weight and enrichment
+// confidence were applied; Contribution is what was added to the score.
+type FactorExplanation struct {
+	Key          string
+	Label        string
+	Source       string
+	RawValue     any
+	Unit         string
+	BasePoints   float64
+	Weight       float64
+	Confidence   float64
+	Contribution float64
+}
+
+// Explanation is the factor breakdown of a lead's current score.
+type Explanation struct {
+	LeadID      uuid.UUID
+	ServiceID   *uuid.UUID
+	ServiceType string
+	Score       *int
+	ScorePreAI  *int
+	BaseScore   float64
+	Version     *string
+	UpdatedAt   *time.Time
+	Factors     []FactorExplanation
+}
+
+// factorDefinition maps a stored factor key to its label, source, weight and raw input.
+type factorDefinition struct {
+	key    string
+	label  string
+	source string
+	unit   string
+	// weight returns the service weight for the factor; nil for unweighted factors.
+	weight func(serviceWeights) float64
+	// scaledByConfidence marks factors multiplied by the enrichment confidence.
+	scaledByConfidence bool
+	raw                func(lead repository.Lead, svc *repository.LeadService, data scoringData) any
+}
+
+// factorDefinitions lists the factors in the order computePreAIScore and
+// applyAIFactors add them. Keep both in sync when adding factors.
+var factorDefinitions = []factorDefinition{
+	{key: "ownership", label: "Koopwoningen in de buurt", source: SourceCBS, unit: "%", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.ownership },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentKoopwoningenPct)
+		}},
+	{key: "wealth", label: "Mediaan vermogen", source: SourceCBS, unit: "x €1.000", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.wealth },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentMediaanVermogenX1000)
+		}},
+	{key: "income", label: "Gemiddeld inkomen", source: SourceCBS, unit: "x €1.000", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.income },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentGemInkomen)
+		}},
+	{key: "household", label: "Huishoudgrootte", source: SourceCBS, unit: "personen", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.household },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentHuishoudenGrootte)
+		}},
+	{key: "children", label: "Huishoudens met kinderen", source: SourceCBS, unit: "%", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.children },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentHuishoudensMetKinderenPct)
+		}},
+	{key: "stedelijkheid", label: "Stedelijkheid", source: SourceCBS, unit: "1 (zeer sterk) - 5 (niet stedelijk)", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.stedelijkheid },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentStedelijkheid)
+		}},
+	{key: "income_high", label: "Huishoudens met hoog inkomen", source: SourceCBS, unit: "%", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.incomeHigh },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentPctHoogInkomen)
+		}},
+	{key: "income_low", label: "Huishoudens met laag inkomen", source: SourceCBS, unit: "%", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.incomeLow },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentPctLaagInkomen)
+		}},
+	{key: "energy_label", label: "Energielabel", source: SourceEnergyLabel,
+		weight: func(w serviceWeights) float64 { return w.energyLabel },
+		raw:    rawEnergyLabel},
+	{key: "gas_usage", label: "Gemiddeld gasverbruik", source: SourceCBS, unit: "m³/jaar", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.gasUsage },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentGemAardgasverbruik)
+		}},
+	{key: "electricity", label: "Gemiddeld elektriciteitsverbruik", source: SourceCBS, unit: "kWh/jaar", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.electricity },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentGemElektriciteitsverbruik)
+		}},
+	{key: "building_age", label: "Bouwjaar", source: SourceEnergyLabel,
+		weight: func(w serviceWeights) float64 { return w.buildingAge },
+		raw:    rawBuildingAge},
+	{key: "woz_value", label: "WOZ-waarde", source: SourceCBS, unit: "x €1.000", scaledByConfidence: true,
+		weight: func(w serviceWeights) float64 { return w.wozValue },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return rawValue(l.LeadEnrichmentWOZWaarde)
+		}},
+	{key: "lead_age", label: "Leeftijd van de lead", source: SourceLead, unit: "dagen",
+		weight: func(w serviceWeights) float64 { return w.leadAge },
+		raw: func(l repository.Lead, _ *repository.LeadService, _ scoringData) any {
+			return int(time.Since(l.CreatedAt).Hours() / 24)
+		}},
+	{key: "service_status", label: "Status van de dienst", source: SourceLead,
+		weight: func(w serviceWeights) float64 { return w.status },
+		raw: func(_ repository.Lead, svc *repository.LeadService, _ scoringData) any {
+			if svc == nil {
+				return nil
+			}
+			return svc.Status
+		}},
+	{key: "activity", label: "Notities en activiteit", source: SourceActivity, unit: "notities",
+		weight: func(w serviceWeights) float64 { return w.activity },
+		raw:    func(_ repository.Lead, _ *repository.LeadService, d scoringData) any { return len(d.notes) }},
+	{key: "consumer_note", label: "Omschrijving van de klant", source: SourceLead, unit: "tekens",
+		weight: func(w serviceWeights) float64 { return w.consumerNote },
+		raw: func(_ repository.Lead, svc *repository.LeadService, _ scoringData) any {
+			if svc == nil || svc.ConsumerNote == nil {
+				return nil
+			}
+			return len([]rune(strings.TrimSpace(*svc.ConsumerNote)))
+		}},
+	{key: "source", label: "Herkomst van de lead", source: SourceLead,
+		weight: func(w serviceWeights) float64 { return w.source },
+		raw: func(l repository.Lead, svc *repository.LeadService, _ scoringData) any {
+			if svc != nil && svc.Source != nil {
+				return *svc.Source
+			}
+			return rawValue(l.Source)
+		}},
+	{key: "assigned", label: "Toegewezen adviseur", source: SourceLead,
+		weight: func(w serviceWeights) float64 { return w.assigned },
+		raw:    func(l repository.Lead, _ *repository.LeadService, _ scoringData) any { return l.AssignedAgentID != nil }},
+	{key: "RAC_appointments", label: "Afspraken", source: SourceActivity, unit: "afspraken",
+		weight: func(w serviceWeights) float64 { return w.RAC_appointments },
+		raw:    func(_ repository.Lead, _ *repository.LeadService, d scoringData) any { return d.apptStats.Total }},
+	{key: "address_valid", label: "Adres gevonden in de BAG", source: SourceAddress,
+		raw: func(_ repository.Lead, _ *repository.LeadService, d scoringData) any {
+			if d.address == nil {
+				return nil
+			}
+			return d.address.IsValid
+		}},
+	{key: "ai_urgency", label: "Urgentie volgens AI-analyse", source: SourceAIAnalysis,
+		raw: func(_ repository.Lead, _ *repository.LeadService, d scoringData) any {
+			if d.ai == nil {
+				return nil
+			}
+			return d.ai.UrgencyLevel
+		}},
+	{key: "ai_quality", label: "Kwaliteit volgens AI-analyse", source: SourceAIAnalysis,
+		raw: func(_ repository.Lead, _ *repository.LeadService, d scoringData) any {
+			if d.ai == nil {
+				return nil
+			}
+			return d.ai.LeadQuality
+		}},
+}
+
+// Explain breaks the lead's stored score down into its factors. Contributions
+// come from the stored factors; raw values reflect the lead's current data.
+func (s *Service) Explain(ctx context.Context, leadID uuid.UUID, tenantID uuid.UUID) (*Explanation, error) {
+	lead, err := s.repo.GetByID(ctx, leadID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := s.resolveService(ctx, leadID, nil, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := s.fetchScoringData(ctx, leadID, tenantID, svc, true)
+	return buildExplanation(lead, svc, data), nil
+}
+
+func buildExplanation(lead repository.Lead, svc *repository.LeadService, data scoringData) *Explanation {
+	stored := map[string]float64{}
+	if len(lead.LeadScoreFactors) > 0 {
+		_ = json.Unmarshal(lead.LeadScoreFactors, &stored)
+	}
+
+	weights := getServiceWeights(data.serviceType)
+	confidence := 1.0
+	if lead.LeadEnrichmentConfidence != nil {
+		confidence = *lead.LeadEnrichmentConfidence
+	}
+
+	explanation := &Explanation{
+		LeadID:      lead.ID,
+		ServiceType: data.serviceType,
+		Score:       lead.LeadScore,
+		ScorePreAI:  lead.LeadScorePreAI,
+		BaseScore:   baseScore,
+		Version:     lead.LeadScoreVersion,
+		UpdatedAt:   lead.LeadScoreUpdatedAt,
+		Factors:     make([]FactorExplanation, 0, len(factorDefinitions)),
+	}
+	if svc != nil {
+		explanation.ServiceID = &svc.ID
+	}
+
+	known := make(map[string]bool, len(factorDefinitions))
+	for _, def := range factorDefinitions {
+		known[def.key] = true
+		factor := FactorExplanation{
+			Key:          def.key,
+			Label:        def.label,
+			Source:       def.source,
+			RawValue:     def.raw(lead, svc, data),
+			Unit:         def.unit,
+			Weight:       1,
+			Confidence:   1,
+			Contribution: stored[def.key],
+		}
+		if def.weight != nil {
+			factor.Weight = def.weight(weights)
+		}
+		if def.scaledByConfidence {
+			factor.Confidence = confidence
+		}
+		if multiplier := factor.Weight * factor.Confidence; multiplier != 0 {
+			factor.BasePoints = math.Round(factor.Contribution/multiplier*10) / 10
+		}
+		explanation.Factors = append(explanation.Factors, factor)
+	}
+
+	// Factors stored by an older scoring version are still reported.
+	unknown := make([]string, 0)
+	for key := range stored {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		contribution := stored[key]
+		explanation.Factors = append(explanation.Factors, FactorExplanation{
+			Key:          key,
+			Label:        key,
+			Source:       SourceUnknown,
+			BasePoints:   contribution,
+			Weight:       1,
+			Confidence:   1,
+			Contribution: contribution,
+		})
+	}
+
+	sort.SliceStable(explanation.Factors, func(i, j int) bool {
+		return math.Abs(explanation.Factors[i].Contribution) > math.Abs(explanation.Factors[j].Contribution)
+	})
+	return explanation
+}
+
+func rawValue[T any](v *T) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func rawEnergyLabel(lead repository.Lead, _ *repository.LeadService, _ scoringData) any {
+	if lead.EnergyClass != nil {
+		return strings.ToUpper(strings.TrimSpace(*lead.EnergyClass))
+	}
+	return rawValue(lead.EnergyIndex)
+}
+
+func rawBuildingAge(lead repository.Lead, _ *repository.LeadService, _ scoringData) any {
+	if lead.EnergyBouwjaar != nil {
+		return *lead.EnergyBouwjaar
+	}
+	return rawValue(lead.LeadEnrichmentBouwjaarVanaf2000Pct)
+}
@@ -0,0 +1,59 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/leads/repository"
+)
+
+func TestBuildExplanationFromStoredFactors(t *testing.T) {
+	pct := 80.0
+	confidence := 0.5
+	score := 71
+	label := "g"
+	lead := repository.Lead{
+		CreatedAt:                     time.Now().Add(-72 * time.Hour),
+		LeadEnrichmentKoopwoningenPct: &pct,
+		LeadEnrichmentConfidence:      &confidence,
+		EnergyClass:                   &label,
+		LeadScore:                     &score,
+		LeadScoreFactors:              []byte(`{"ownership":6.5,"energy_label":18,"ai_quality":-8,"legacy":1.5}`),
+	}
+	data := scoringData{serviceType: "insulation", ai: &repository.AIAnalysis{LeadQuality: "Low"}}
+
+	explanation := buildExplanation(lead, nil, data)
+	if explanation.BaseScore != baseScore || explanation.Score == nil || *explanation.Score != score {
+		t.Fatalf("unexpected header %+v", explanation)
+	}
+	if len(explanation.Factors) != len(factorDefinitions)+1 {
+		t.Fatalf("expected every factor plus the legacy one, got %d", len(explanation.Factors))
+	}
+
+	byKey := map[string]FactorExplanation{}
+	for _, f := range explanation.Factors {
+		byKey[f.Key] = f
+	}
+	if explanation.Factors[0].Key != "energy_label" || explanation.Factors[1].Key != "ai_quality" {
+		t.Fatalf("expected factors ordered by absolute contribution, got %s, %s", explanation.Factors[0].Key, explanation.Factors[1].Key)
+	}
+
+	// insulation weighs ownership 1.3 and scales it by the enrichment confidence
+	ownership := byKey["ownership"]
+	if ownership.Source != SourceCBS || ownership.RawValue != pct || ownership.Weight != 1.3 || ownership.Confidence != 0.5 || ownership.BasePoints != 10 {
+		t.Fatalf("unexpected ownership factor %+v", ownership)
+	}
+	energy := byKey["energy_label"]
+	if energy.RawValue != "G" || energy.Weight != 1.5 || energy.Confidence != 1 || energy.BasePoints != 12 {
+		t.Fatalf("unexpected energy label factor %+v", energy)
+	}
+	if ai := byKey["ai_quality"]; ai.Source != SourceAIAnalysis || ai.RawValue != "Low" || ai.Contribution != -8 {
+		t.Fatalf("unexpected ai factor %+v", ai)
+	}
+	if legacy := byKey["legacy"]; legacy.Source != SourceUnknown || legacy.Contribution != 1.5 {
+		t.Fatalf("unexpected legacy factor %+v", legacy)
+	}
+	if lead := byKey["lead_age"]; lead.RawValue != 3 || lead.Contribution != 0 {
+		t.Fatalf("unexpected lead age factor %+v", lead)
+	}
+}
@@ -320,7 +320,7 @@ func (s *Service) Recalculate(ctx context.Context, leadID uuid.UUID, serviceID *
 	data := s.fetchScoringData(ctx, leadID, tenantID, svc, includeAI)
 
 	now := time.Now().UTC()
-	preAI, factors := s.computePreAIScore(lead, svc, data.notes, data.apptStats, data.serviceType)
+	preAI, factors := s.computePreAIScore(lead, svc, data.notes, data.apptStats, data.address, data.serviceType)
 	finalScore, aiFactors := s.applyAIFactors(preAI, data.ai)
 	mergeFactors(factors, aiFactors)
 
@@ -340,6 +340,7 @@ type scoringData struct {
 	notes       []repository.LeadNote
 	apptStats   repository.LeadAppointmentStats
 	ai          *repository.AIAnalysis
+	address     *repository.LeadAddressValidation
 	serviceType string
 }
 
@@ -355,6 +356,10 @@ func (s *Service) fetchScoringData(ctx context.Context, leadID, tenantID uuid.UU
 		data.apptStats = stats
 	}
 
+	if address, err := s.repo.GetLeadAddressValidation(ctx, leadID, tenantID); err == nil {
+		data.address = address
+	}
+
 	if svc == nil {
 		return data
 	}
@@ -421,7 +426,7 @@ func getServiceWeights(serviceType string) serviceWeights {
 	return defaultServiceWeights
 }
 
-func (s *Service) computePreAIScore(lead repository.Lead, svc *repository.LeadService, notes []repository.LeadNote, apptStats repository.LeadAppointmentStats, serviceType string) (int, map[string]float64) {
+func (s *Service) computePreAIScore(lead repository.Lead, svc *repository.LeadService, notes []repository.LeadNote, apptStats repository.LeadAppointmentStats, address *repository.LeadAddressValidation, serviceType string) (int, map[string]float64) {
 	score := baseScore
 	factors := map[string]float64{}
 	weights := getServiceWeights(serviceType)
@@ -541,9 +546,24 @@ func (s *Service) computePreAIScore(lead repository.Lead, svc *repository.LeadSe
 	appointmentScore := s.scoreAppointments(apptStats) * weights.RAC_appointments
 	score += s.addFactor(factors, "RAC_appointments", appointmentScore)
 
+	// Address validity: Addresses not found in the BAG are often fake or mistyped
+	// Score: -8 to +2 (0 when the address was not validated)
+	score += s.addFactor(factors, "address_valid", s.scoreAddressValidity(address))
+
 	return clampScore(score), factors
 }
 
+// scoreAddressValidity rewards BAG-verified intake addresses and penalises unknown ones.
+func (s *Service) scoreAddressValidity(address *repository.LeadAddressValidation) float64 {
+	if address == nil {
+		return 0
+	}
+	if address.IsValid {
+		return 2
+	}
+	return -8
+}
+
 func (s *Service) applyAIFactors(preAI int, ai *repository.AIAnalysis) (int, map[string]float64) {
 	if ai == nil {
 		return preAI, map[string]float64{}
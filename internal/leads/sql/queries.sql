@@ -770,21 +770,6 @@ LEFT JOIN RAC_quotes q
 GROUP BY w.week_start
 ORDER BY w.week_start;
 
--- name: FindMatchingPartnersByCoordinates :many
-SELECT p.id, p.business_name, p.contact_email,
-	CAST(earth_distance(ll_to_earth($2, $1), ll_to_earth(p.latitude, p.longitude)) / 1000.0 AS double precision) AS dist_km
-FROM RAC_partners p
-JOIN RAC_partner_service_types pst ON pst.partner_id = p.id
-JOIN RAC_service_types st ON st.id = pst.service_type_id AND st.organization_id = p.organization_id
-WHERE p.organization_id = $3
-	AND st.is_active = true
-	AND (st.name = $4 OR st.slug = $4)
-	AND p.latitude IS NOT NULL AND p.longitude IS NOT NULL
-	AND earth_distance(ll_to_earth($2, $1), ll_to_earth(p.latitude, p.longitude)) <= ($5 * 1000.0)
-	AND (CARDINALITY($6::uuid[]) = 0 OR p.id != ALL($6::uuid[]))
-ORDER BY dist_km ASC
-LIMIT 5;
-
 -- name: GetPartnerOfferStatsSince :many
 SELECT partner_id,
 	COUNT(*) FILTER (WHERE status = 'rejected')::int AS rejected_count,
@@ -990,11 +975,13 @@ WHERE l.organization_id = sqlc.arg(organization_id)
 	AND (sqlc.narg(service_type)::text IS NULL OR st.name = sqlc.narg(service_type)::text)
 	AND (sqlc.narg(search)::text IS NULL OR (
 		l.consumer_first_name ILIKE sqlc.narg(search)::text OR l.consumer_last_name ILIKE sqlc.narg(search)::text OR l.consumer_phone ILIKE sqlc.narg(search)::text OR l.consumer_email ILIKE sqlc.narg(search)::text OR l.address_city ILIKE sqlc.narg(search)::text
+		OR rac_custom_fields_text(l.custom_fields) ILIKE sqlc.narg(search)::text
+		OR l.consumer_phone_bidx = sqlc.narg(search_phone_bidx)::text OR l.consumer_email_bidx = sqlc.narg(search_email_bidx)::text
 	))
 	AND (sqlc.narg(first_name)::text IS NULL OR l.consumer_first_name ILIKE sqlc.narg(first_name)::text)
 	AND (sqlc.narg(last_name)::text IS NULL OR l.consumer_last_name ILIKE sqlc.narg(last_name)::text)
-	AND (sqlc.narg(phone)::text IS NULL OR l.consumer_phone ILIKE sqlc.narg(phone)::text)
-	AND (sqlc.narg(email)::text IS NULL OR l.consumer_email ILIKE sqlc.narg(email)::text)
+	AND (sqlc.narg(phone)::text IS NULL OR l.consumer_phone ILIKE sqlc.narg(phone)::text OR l.consumer_phone_bidx = sqlc.narg(phone_bidx)::text)
+	AND (sqlc.narg(email)::text IS NULL OR l.consumer_email ILIKE sqlc.narg(email)::text OR l.consumer_email_bidx = sqlc.narg(email_bidx)::text)
 	AND (sqlc.narg(role)::text IS NULL OR l.consumer_role = sqlc.narg(role)::text)
 	AND (sqlc.narg(street)::text IS NULL OR l.address_street ILIKE sqlc.narg(street)::text)
 	AND (sqlc.narg(house_number)::text IS NULL OR l.address_house_number ILIKE sqlc.narg(house_number)::text)
@@ -1002,7 +989,8 @@ WHERE l.organization_id = sqlc.arg(organization_id)
 	AND (sqlc.narg(city)::text IS NULL OR l.address_city ILIKE sqlc.narg(city)::text)
 	AND (sqlc.narg(assigned_agent_id)::uuid IS NULL OR l.assigned_agent_id = sqlc.narg(assigned_agent_id)::uuid)
 	AND (sqlc.narg(created_at_from)::timestamptz IS NULL OR l.created_at >= sqlc.narg(created_at_from)::timestamptz)
-	AND (sqlc.narg(created_at_to)::timestamptz IS NULL OR l.created_at < sqlc.narg(created_at_to)::timestamptz);
+	AND (sqlc.narg(created_at_to)::timestamptz IS NULL OR l.created_at < sqlc.narg(created_at_to)::timestamptz)
+	AND (sqlc.narg(custom_fields)::jsonb IS NULL OR l.custom_fields @> sqlc.narg(custom_fields)::jsonb);
 
 -- name: ListLeads :many
 SELECT * FROM (
@@ -1022,11 +1010,13 @@ SELECT * FROM (
 		AND (sqlc.narg(service_type)::text IS NULL OR st.name = sqlc.narg(service_type)::text)
 		AND (sqlc.narg(search)::text IS NULL OR (
 			l.consumer_first_name ILIKE sqlc.narg(search)::text OR l.consumer_last_name ILIKE sqlc.narg(search)::text OR l.consumer_phone ILIKE sqlc.narg(search)::text OR l.consumer_email ILIKE sqlc.narg(search)::text OR l.address_city ILIKE sqlc.narg(search)::text
+			OR rac_custom_fields_text(l.custom_fields) ILIKE sqlc.narg(search)::text
+			OR l.consumer_phone_bidx = sqlc.narg(search_phone_bidx)::text OR l.consumer_email_bidx = sqlc.narg(search_email_bidx)::text
 		))
 		AND (sqlc.narg(first_name)::text IS NULL OR l.consumer_first_name ILIKE sqlc.narg(first_name)::text)
 		AND (sqlc.narg(last_name)::text IS NULL OR l.consumer_last_name ILIKE sqlc.narg(last_name)::text)
-		AND (sqlc.narg(phone)::text IS NULL OR l.consumer_phone ILIKE sqlc.narg(phone)::text)
-		AND (sqlc.narg(email)::text IS NULL OR l.consumer_email ILIKE sqlc.narg(email)::text)
+		AND (sqlc.narg(phone)::text IS NULL OR l.consumer_phone ILIKE sqlc.narg(phone)::text OR l.consumer_phone_bidx = sqlc.narg(phone_bidx)::text)
+		AND (sqlc.narg(email)::text IS NULL OR l.consumer_email ILIKE sqlc.narg(email)::text OR l.consumer_email_bidx = sqlc.narg(email_bidx)::text)
 		AND (sqlc.narg(role)::text IS NULL OR l.consumer_role = sqlc.narg(role)::text)
 		AND (sqlc.narg(street)::text IS NULL OR l.address_street ILIKE sqlc.narg(street)::text)
 		AND (sqlc.narg(house_number)::text IS NULL OR l.address_house_number ILIKE sqlc.narg(house_number)::text)
@@ -1035,6 +1025,7 @@ SELECT * FROM (
 		AND (sqlc.narg(assigned_agent_id)::uuid IS NULL OR l.assigned_agent_id = sqlc.narg(assigned_agent_id)::uuid)
 		AND (sqlc.narg(created_at_from)::timestamptz IS NULL OR l.created_at >= sqlc.narg(created_at_from)::timestamptz)
 		AND (sqlc.narg(created_at_to)::timestamptz IS NULL OR l.created_at < sqlc.narg(created_at_to)::timestamptz)
+		AND (sqlc.narg(custom_fields)::jsonb IS NULL OR l.custom_fields @> sqlc.narg(custom_fields)::jsonb)
 ) leads
 ORDER BY
 	CASE WHEN sqlc.arg(sort_by)::text = 'createdAt' AND sqlc.arg(sort_order)::text = 'asc' THEN leads.created_at END ASC,
@@ -0,0 +1,27 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UpdateAgentReviewPolicyRequest struct {
+	Enabled                bool   `json:"enabled"`
+	ReviewStageMoves       bool   `json:"reviewStageMoves"`
+	ReviewPartnerOffers    bool   `json:"reviewPartnerOffers"`
+	ReviewDetailOverwrites bool   `json:"reviewDetailOverwrites"`
+	SLAMinutes             int    `json:"slaMinutes" validate:"required,min=1,max=1440"`
+	TimeoutAction          string `json:"timeoutAction" validate:"required,oneof=reject approve"`
+}
+
+type AgentReviewPolicyResponse struct {
+	Enabled                bool       `json:"enabled"`
+	ReviewStageMoves       bool       `json:"reviewStageMoves"`
+	ReviewPartnerOffers    bool       `json:"reviewPartnerOffers"`
+	ReviewDetailOverwrites bool       `json:"reviewDetailOverwrites"`
+	SLAMinutes             int        `json:"slaMinutes"`
+	TimeoutAction          string     `json:"timeoutAction"`
+	UpdatedByID            *uuid.UUID `json:"updatedById,omitempty"`
+	UpdatedAt              *time.Time `json:"updatedAt,omitempty"`
+}
@@ -30,14 +30,37 @@ type CreateAttachmentRequest struct {
 
 // AttachmentResponse is the response DTO for an attachment.
 type AttachmentResponse struct {
-	ID          uuid.UUID  `json:"id"`
-	FileKey     string     `json:"fileKey"`
-	FileName    string     `json:"fileName"`
-	ContentType string     `json:"contentType"`
-	SizeBytes   int64      `json:"sizeBytes"`
-	UploadedBy  *uuid.UUID `json:"uploadedBy,omitempty"`
-	CreatedAt   time.Time  `json:"createdAt"`
-	DownloadURL *string    `json:"downloadUrl,omitempty"` // Presigned download URL when requested
+	ID          uuid.UUID                     `json:"id"`
+	FileKey     string                        `json:"fileKey"`
+	FileName    string                        `json:"fileName"`
+	ContentType string                        `json:"contentType"`
+	SizeBytes   int64                         `json:"sizeBytes"`
+	UploadedBy  *uuid.UUID                    `json:"uploadedBy,omitempty"`
+	CreatedAt   time.Time                     `json:"createdAt"`
+	DownloadURL *string                       `json:"downloadUrl,omitempty"` // Presigned download URL when requested
+	Renditions  []AttachmentRenditionResponse `json:"renditions,omitempty"`
+}
+
+// AttachmentRenditionResponse describes a downscaled copy of an image attachment.
+type AttachmentRenditionResponse struct {
+	Size        string `json:"size"` // web or thumbnail
+	ContentType string `json:"contentType"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	SizeBytes   int64  `json:"sizeBytes"`
+}
+
+// Download sizes of an attachment. Image attachments have web and thumbnail
+// renditions once they are processed; the original is served until then.
+const (
+	DownloadSizeOriginal  = "original"
+	DownloadSizeWeb       = "web"
+	DownloadSizeThumbnail = "thumbnail"
+)
+
+// DownloadURLRequest selects the size of the attachment to download.
+type DownloadURLRequest struct {
+	Size string `form:"size" validate:"omitempty,oneof=original web thumbnail"`
 }
 
 // AttachmentListResponse is the list of attachments for a service.
@@ -49,4 +72,5 @@ type AttachmentListResponse struct {
 type PresignedDownloadResponse struct {
 	DownloadURL string `json:"downloadUrl"`
 	ExpiresAt   int64  `json:"expiresAt"` // Unix timestamp
+	Size        string `json:"size"`      // the size served, original when no rendition exists yet
 }
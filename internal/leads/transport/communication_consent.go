@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UpdateCommunicationConsentRequest grants or withdraws the consents that are
+// set and keeps the others.
+type UpdateCommunicationConsentRequest struct {
+	Email         *bool `json:"email"`
+	WhatsApp      *bool `json:"whatsapp"`
+	Phone         *bool `json:"phone"`
+	Transactional *bool `json:"transactional"`
+	Marketing     *bool `json:"marketing"`
+}
+
+// CommunicationConsentResponse is the channel and purpose consent of a lead.
+// Marketing messages are withheld until marketingConfirmedAt is set.
+type CommunicationConsentResponse struct {
+	Email                bool       `json:"email"`
+	WhatsApp             bool       `json:"whatsapp"`
+	Phone                bool       `json:"phone"`
+	Transactional        bool       `json:"transactional"`
+	Marketing            bool       `json:"marketing"`
+	MarketingConfirmedAt *time.Time `json:"marketingConfirmedAt,omitempty"`
+	UpdatedAt            *time.Time `json:"updatedAt,omitempty"`
+}
+
+type ConsentChangeResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Consent     string     `json:"consent"`
+	Granted     bool       `json:"granted"`
+	Source      string     `json:"source"`
+	ActorUserID *uuid.UUID `json:"actorUserId,omitempty"`
+	IPAddress   *string    `json:"ipAddress,omitempty"`
+	UserAgent   *string    `json:"userAgent,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// LeadCommunicationConsentResponse is the consent of a lead with its audit
+// trail, as shown to agents.
+type LeadCommunicationConsentResponse struct {
+	Consent CommunicationConsentResponse `json:"consent"`
+	History []ConsentChangeResponse      `json:"history"`
+}
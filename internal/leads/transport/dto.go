@@ -90,6 +90,7 @@ type CreateLeadRequest struct {
 	ConsumerNote    string       `json:"consumerNote,omitempty" validate:"max=2000"`
 	Source          string       `json:"source,omitempty" validate:"max=50"`
 	GCLID           string       `json:"gclid,omitempty" validate:"max=255"`
+	FBCLID          string       `json:"fbclid,omitempty" validate:"max=255"`
 	UTMSource       string       `json:"utmSource,omitempty" validate:"max=255"`
 	UTMMedium       string       `json:"utmMedium,omitempty" validate:"max=255"`
 	UTMCampaign     string       `json:"utmCampaign,omitempty" validate:"max=255"`
@@ -115,10 +116,14 @@ type UpdateLeadRequest struct {
 	Longitude       *float64      `json:"longitude,omitempty" validate:"omitempty,gte=-180,lte=180"`
 	AssigneeID      OptionalUUID  `json:"assigneeId,omitempty" validate:"-"`
 	WhatsAppOptedIn *bool         `json:"whatsappOptedIn,omitempty"`
+	// RowVersion is the version the update is based on; the If-Match header
+	// takes precedence. Without either the update is unconditional.
+	RowVersion *int64 `json:"rowVersion,omitempty" validate:"omitempty,min=1"`
 }
 
 type UpdateServiceStatusRequest struct {
-	Status LeadStatus `json:"status" validate:"required,oneof=New Pending In_Progress Attempted_Contact Appointment_Scheduled Needs_Rescheduling Completed Disqualified"`
+	Status     LeadStatus `json:"status" validate:"required,oneof=New Pending In_Progress Attempted_Contact Appointment_Scheduled Needs_Rescheduling Completed Disqualified"`
+	RowVersion *int64     `json:"rowVersion,omitempty" validate:"omitempty,min=1"`
 }
 
 type AddServiceRequest struct {
@@ -164,10 +169,13 @@ type ListLeadsRequest struct {
 	AssignedAgentID *uuid.UUID    `form:"assignedAgentId" validate:"omitempty"`
 	CreatedAtFrom   string        `form:"createdAtFrom" validate:"omitempty"`
 	CreatedAtTo     string        `form:"createdAtTo" validate:"omitempty"`
-	Page            int           `form:"page" validate:"min=1"`
-	PageSize        int           `form:"pageSize" validate:"min=1,max=100"`
-	SortBy          string        `form:"sortBy" validate:"omitempty,oneof=createdAt firstName lastName phone email role street houseNumber zipCode city assignedAgentId"`
-	SortOrder       string        `form:"sortOrder" validate:"omitempty,oneof=asc desc"`
+	// CustomFields is a JSON object of custom field values the lead must have,
+	// e.g. {"building_permit_required":true}.
+	CustomFields string `form:"customFields" validate:"omitempty,max=2000"`
+	Page         int    `form:"page" validate:"min=1"`
+	PageSize     int    `form:"pageSize" validate:"min=1,max=100"`
+	SortBy       string `form:"sortBy" validate:"omitempty,oneof=createdAt firstName lastName phone email role street houseNumber zipCode city assignedAgentId"`
+	SortOrder    string `form:"sortOrder" validate:"omitempty,oneof=asc desc"`
 }
 
 type LeadHeatmapRequest struct {
@@ -217,10 +225,42 @@ type LeadServiceResponse struct {
 	ConsumerNote         *string                  `json:"consumerNote,omitempty"`
 	ExtraWorkAmountCents *int64                   `json:"extraWorkAmountCents,omitempty"`
 	ExtraWorkNotes       *string                  `json:"extraWorkNotes,omitempty"`
+	RowVersion           int64                    `json:"rowVersion,omitempty"`
 	CreatedAt            time.Time                `json:"createdAt"`
 	UpdatedAt            time.Time                `json:"updatedAt"`
 }
 
+// StageTransitionResponse is one pipeline stage change of a lead service.
+// DurationSeconds is how long the service was in FromStage before it moved.
+type StageTransitionResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	FromStage       *string    `json:"fromStage,omitempty"`
+	ToStage         string     `json:"toStage"`
+	ActorType       string     `json:"actorType"`
+	ActorID         *uuid.UUID `json:"actorId,omitempty"`
+	ActorName       string     `json:"actorName"`
+	Reason          *string    `json:"reason,omitempty"`
+	OccurredAt      time.Time  `json:"occurredAt"`
+	DurationSeconds int64      `json:"durationSeconds"`
+}
+
+// StageDurationResponse is the total time a lead service spent in a stage.
+type StageDurationResponse struct {
+	Stage        string `json:"stage"`
+	TotalSeconds int64  `json:"totalSeconds"`
+	Visits       int    `json:"visits"`
+	Current      bool   `json:"current"`
+}
+
+// StageHistoryResponse is the full pipeline stage history of a lead service.
+type StageHistoryResponse struct {
+	ServiceID      uuid.UUID                 `json:"serviceId"`
+	CurrentStage   string                    `json:"currentStage"`
+	CurrentSince   time.Time                 `json:"currentSince"`
+	Transitions    []StageTransitionResponse `json:"transitions"`
+	StageDurations []StageDurationResponse   `json:"stageDurations"`
+}
+
 type CompleteServiceRequest struct {
 	ExtraWorkAmountCents *int64  `json:"extraWorkAmountCents,omitempty" validate:"omitempty,min=0"`
 	ExtraWorkNotes       *string `json:"extraWorkNotes,omitempty" validate:"omitempty,max=2000"`
@@ -243,6 +283,15 @@ type EnergyLabelResponse struct {
 	PrimaireFossieleEnergie *float64   `json:"primaireFossieleEnergie,omitempty"` // Primary fossil energy use (kWh/m2·jaar)
 }
 
+// EnergyLabelRefreshResponse is the outcome of a manual energy label refresh.
+type EnergyLabelRefreshResponse struct {
+	EnergyLabel   *EnergyLabelResponse `json:"energyLabel"`
+	Changed       bool                 `json:"changed"`
+	PreviousClass *string              `json:"previousClass,omitempty"`
+	FetchedAt     *time.Time           `json:"fetchedAt,omitempty"`
+	LeadScore     *LeadScoreResponse   `json:"leadScore,omitempty"`
+}
+
 type LeadEnrichmentResponse struct {
 	Source                    *string    `json:"source,omitempty"`
 	Postcode6                 *string    `json:"postcode6,omitempty"`
@@ -378,16 +427,31 @@ type LeadResponse struct {
 	EnergyLabel     *EnergyLabelResponse    `json:"energyLabel,omitempty"`     // Energy label data from EP-Online
 	LeadEnrichment  *LeadEnrichmentResponse `json:"leadEnrichment,omitempty"`
 	LeadScore       *LeadScoreResponse      `json:"leadScore,omitempty"`
+	Attribution     *AttributionResponse    `json:"attribution,omitempty"` // Click IDs and UTM parameters captured at intake
 	AssignedAgentID *uuid.UUID              `json:"assignedAgentId,omitempty"`
 	ViewedByID      *uuid.UUID              `json:"viewedById,omitempty"`
 	ViewedAt        *time.Time              `json:"viewedAt,omitempty"`
 	Source          *string                 `json:"source,omitempty"`
 	PublicToken     *string                 `json:"publicToken,omitempty"`
 	WhatsAppOptedIn bool                    `json:"whatsappOptedIn"`
+	RowVersion      int64                   `json:"rowVersion,omitempty"` // Send back in If-Match when updating
 	CreatedAt       time.Time               `json:"createdAt"`
 	UpdatedAt       time.Time               `json:"updatedAt"`
 }
 
+// AttributionResponse holds the marketing attribution captured when the lead was created.
+type AttributionResponse struct {
+	GCLID         *string `json:"gclid,omitempty"`
+	FBCLID        *string `json:"fbclid,omitempty"`
+	UTMSource     *string `json:"utmSource,omitempty"`
+	UTMMedium     *string `json:"utmMedium,omitempty"`
+	UTMCampaign   *string `json:"utmCampaign,omitempty"`
+	UTMContent    *string `json:"utmContent,omitempty"`
+	UTMTerm       *string `json:"utmTerm,omitempty"`
+	AdLandingPage *string `json:"adLandingPage,omitempty"`
+	ReferrerURL   *string `json:"referrerUrl,omitempty"`
+}
+
 type TransferLeadResponse struct {
 	Lead                      LeadResponse `json:"lead"`
 	DestinationOrganizationID uuid.UUID    `json:"destinationOrganizationId"`
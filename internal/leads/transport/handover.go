@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HandoverLeadRequest transfers a lead to another agent. The note tells the
+// receiving agent where the lead stands.
+type HandoverLeadRequest struct {
+	ToAgentID uuid.UUID `json:"toAgentId" validate:"required"`
+	Note      string    `json:"note" validate:"required,max=4000"`
+}
+
+// BulkHandoverLeadsRequest transfers every lead of an agent, typically one
+// leaving the organization, to another agent.
+type BulkHandoverLeadsRequest struct {
+	ToAgentID uuid.UUID `json:"toAgentId" validate:"required"`
+	Note      string    `json:"note" validate:"required,max=4000"`
+}
+
+// LeadHandoverReportRequest selects the days of a transfer report; both dates
+// are inclusive. Omitted dates default to the last 30 days.
+type LeadHandoverReportRequest struct {
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type LeadHandoverResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	LeadID         uuid.UUID  `json:"leadId"`
+	FromAgentID    *uuid.UUID `json:"fromAgentId,omitempty"`
+	ToAgentID      *uuid.UUID `json:"toAgentId,omitempty"`
+	Note           string     `json:"note"`
+	TransferredBy  *uuid.UUID `json:"transferredBy,omitempty"`
+	BulkTransferID *uuid.UUID `json:"bulkTransferId,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type LeadHandoverListResponse struct {
+	Items []LeadHandoverResponse `json:"items"`
+}
+
+type BulkHandoverLeadsResponse struct {
+	BulkTransferID   uuid.UUID   `json:"bulkTransferId"`
+	TransferredCount int         `json:"transferredCount"`
+	LeadIDs          []uuid.UUID `json:"leadIds"`
+}
+
+// LeadHandoverAgentStatsResponse counts the leads an agent received and gave away.
+type LeadHandoverAgentStatsResponse struct {
+	AgentID        uuid.UUID `json:"agentId"`
+	Email          string    `json:"email"`
+	TransferredIn  int       `json:"transferredIn"`
+	TransferredOut int       `json:"transferredOut"`
+}
+
+type LeadHandoverReportResponse struct {
+	From   time.Time                        `json:"from"`
+	To     time.Time                        `json:"to"`
+	Agents []LeadHandoverAgentStatsResponse `json:"agents"`
+}
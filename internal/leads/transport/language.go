@@ -0,0 +1,11 @@
+package transport
+
+// UpdatePreferredLanguageRequest sets the language notifications to a lead
+// are sent in. A null language falls back to the organization default.
+type UpdatePreferredLanguageRequest struct {
+	Language *string `json:"language" validate:"omitempty,oneof=nl en de fr"`
+}
+
+type PreferredLanguageResponse struct {
+	Language *string `json:"language"`
+}
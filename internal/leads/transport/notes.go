@@ -19,8 +19,14 @@ type LeadNoteResponse struct {
 	AuthorEmail string    `json:"authorEmail"`
 	Type        string    `json:"type"`
 	Body        string    `json:"body"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	// BodyHTML is the body as HTML that is safe to render; inline images
+	// reference their attachment in data-attachment-id.
+	BodyHTML string `json:"bodyHtml"`
+	// BodyText is the body as plain text, as sent over WhatsApp and e-mail.
+	BodyText      string      `json:"bodyText"`
+	AttachmentIDs []uuid.UUID `json:"attachmentIds,omitempty"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
 }
 
 type LeadNotesResponse struct {
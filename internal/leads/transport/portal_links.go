@@ -0,0 +1,17 @@
+package transport
+
+import "time"
+
+type SendPortalLinkRequest struct {
+	Channel string `json:"channel" validate:"required,oneof=email whatsapp none"`
+}
+
+type PortalLinkResponse struct {
+	Channel   string    `json:"channel"`
+	URL       string    `json:"url,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type RevokePortalLinksResponse struct {
+	Revoked int64 `json:"revoked"`
+}
@@ -0,0 +1,34 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeadScoreFactorResponse explains a single factor of the lead score.
+type LeadScoreFactorResponse struct {
+	Key          string  `json:"key"`
+	Label        string  `json:"label"`
+	Source       string  `json:"source"`
+	RawValue     any     `json:"rawValue"`
+	Unit         string  `json:"unit,omitempty"`
+	BasePoints   float64 `json:"basePoints"`
+	Weight       float64 `json:"weight"`
+	Confidence   float64 `json:"confidence"`
+	Contribution float64 `json:"contribution"`
+}
+
+// LeadScoreExplanationResponse is the factor breakdown of a lead's current
+// score, ordered by the size of each factor's contribution.
+type LeadScoreExplanationResponse struct {
+	LeadID      uuid.UUID                 `json:"leadId"`
+	ServiceID   *uuid.UUID                `json:"serviceId,omitempty"`
+	ServiceType string                    `json:"serviceType"`
+	Score       *int                      `json:"score"`
+	PreAI       *int                      `json:"preAi"`
+	BaseScore   float64                   `json:"baseScore"`
+	Version     *string                   `json:"version,omitempty"`
+	UpdatedAt   *time.Time                `json:"updatedAt,omitempty"`
+	Factors     []LeadScoreFactorResponse `json:"factors"`
+}
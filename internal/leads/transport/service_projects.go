@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateServiceProjectRequest groups services of a lead into one job.
+// Dependencies decide the execution order; services without a dependency keep
+// the order of ServiceIDs.
+type CreateServiceProjectRequest struct {
+	Name         string                     `json:"name" validate:"required,max=200"`
+	ServiceIDs   []uuid.UUID                `json:"serviceIds" validate:"required,min=2,max=20"`
+	Dependencies []ServiceDependencyRequest `json:"dependencies" validate:"omitempty,max=100,dive"`
+}
+
+// ServiceDependencyRequest states that ServiceID can only start once
+// DependsOnServiceID is done, e.g. painting after the roofing.
+type ServiceDependencyRequest struct {
+	ServiceID          uuid.UUID `json:"serviceId" validate:"required"`
+	DependsOnServiceID uuid.UUID `json:"dependsOnServiceId" validate:"required"`
+}
+
+type ServiceProjectServiceResponse struct {
+	ServiceID     uuid.UUID   `json:"serviceId"`
+	ServiceType   string      `json:"serviceType"`
+	Status        string      `json:"status"`
+	PipelineStage string      `json:"pipelineStage"`
+	Position      int         `json:"position"`
+	DependsOn     []uuid.UUID `json:"dependsOn"`
+	// WaitingFor lists the prerequisites that are not completed yet.
+	WaitingFor []uuid.UUID `json:"waitingFor"`
+}
+
+type ServiceProjectResponse struct {
+	ID        uuid.UUID                       `json:"id"`
+	LeadID    uuid.UUID                       `json:"leadId"`
+	Name      string                          `json:"name"`
+	Services  []ServiceProjectServiceResponse `json:"services"`
+	CreatedBy *uuid.UUID                      `json:"createdBy,omitempty"`
+	CreatedAt time.Time                       `json:"createdAt"`
+}
+
+type ServiceProjectListResponse struct {
+	Items []ServiceProjectResponse `json:"items"`
+}
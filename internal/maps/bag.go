@@ -0,0 +1,195 @@
+package maps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pdokLocatieserverURL is the PDOK Locatieserver, which serves addresses from the BAG
+// (Basisregistratie Adressen en Gebouwen).
+const pdokLocatieserverURL = "https://api.pdok.nl/bzk/locatieserver/search/v3_1/free"
+
+var (
+	rePostcode          = regexp.MustCompile(`^[1-9][0-9]{3}[A-Z]{2}$`)
+	reHouseNumberParts  = regexp.MustCompile(`^(\d+)\s*([a-zA-Z]?)\s*[-/ ]?\s*(.*)$`)
+	rePointCoordinates  = regexp.MustCompile(`^POINT\(([-0-9.]+) ([-0-9.]+)\)$`)
+	locatieserverFields = "weergavenaam,straatnaam,huisnummer,huisletter,huisnummertoevoeging,postcode,woonplaatsnaam,nummeraanduiding_id,adresseerbaarobject_id,centroide_ll"
+)
+
+// BAGAddress is an address as registered in the BAG.
+type BAGAddress struct {
+	Label                 string   `json:"label"`
+	Street                string   `json:"street"`
+	HouseNumber           string   `json:"houseNumber"`
+	ZipCode               string   `json:"zipCode"`
+	City                  string   `json:"city"`
+	NummeraanduidingID    string   `json:"nummeraanduidingId,omitempty"`
+	AdresseerbaarObjectID string   `json:"adresseerbaarObjectId,omitempty"`
+	Lat                   *float64 `json:"lat,omitempty"`
+	Lon                   *float64 `json:"lon,omitempty"`
+}
+
+type locatieserverDoc struct {
+	Weergavenaam          string `json:"weergavenaam"`
+	Straatnaam            string `json:"straatnaam"`
+	Huisnummer            int    `json:"huisnummer"`
+	Huisletter            string `json:"huisletter"`
+	Huisnummertoevoeging  string `json:"huisnummertoevoeging"`
+	Postcode              string `json:"postcode"`
+	Woonplaatsnaam        string `json:"woonplaatsnaam"`
+	NummeraanduidingID    string `json:"nummeraanduiding_id"`
+	AdresseerbaarObjectID string `json:"adresseerbaarobject_id"`
+	CentroideLL           string `json:"centroide_ll"`
+}
+
+// NormalizePostcode uppercases a Dutch postcode and strips whitespace ("1234 ab" -> "1234AB").
+func NormalizePostcode(raw string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(raw), ""))
+}
+
+// ParseHouseNumber splits a Dutch house number into number, letter and addition ("12a-3" -> "12", "a", "3").
+func ParseHouseNumber(raw string) (number, letter, addition string) {
+	trimmed := strings.TrimSpace(raw)
+	match := reHouseNumberParts.FindStringSubmatch(trimmed)
+	if len(match) < 4 {
+		return trimmed, "", ""
+	}
+	return match[1], match[2], strings.TrimSpace(match[3])
+}
+
+// ValidateAddress resolves a postcode and house number against the BAG.
+// Returns (nil, nil) when the address does not exist.
+func (s *Service) ValidateAddress(ctx context.Context, zipCode, houseNumber string) (*BAGAddress, error) {
+	postcode := NormalizePostcode(zipCode)
+	number, letter, addition := ParseHouseNumber(houseNumber)
+	if !rePostcode.MatchString(postcode) || number == "" {
+		return nil, nil
+	}
+
+	params := url.Values{
+		"q":    {fmt.Sprintf("%s %s", postcode, number)},
+		"fq":   {"type:adres", "postcode:" + postcode, "huisnummer:" + number},
+		"fl":   {locatieserverFields},
+		"rows": {"20"},
+	}
+	docs, err := s.queryLocatieserver(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, ok := matchHouseNumber(docs, letter, addition)
+	if !ok {
+		return nil, nil
+	}
+	addr := doc.toBAGAddress()
+	return &addr, nil
+}
+
+// AutocompleteAddress returns BAG address suggestions for free-text input such as
+// "1234AB 12" or "Kerkstraat 12 Utrecht".
+func (s *Service) AutocompleteAddress(ctx context.Context, query string) ([]BAGAddress, error) {
+	params := url.Values{
+		"q":    {query},
+		"fq":   {"type:adres"},
+		"fl":   {locatieserverFields},
+		"rows": {"8"},
+	}
+	docs, err := s.queryLocatieserver(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]BAGAddress, 0, len(docs))
+	for _, doc := range docs {
+		suggestions = append(suggestions, doc.toBAGAddress())
+	}
+	return suggestions, nil
+}
+
+func (s *Service) queryLocatieserver(ctx context.Context, params url.Values) ([]locatieserverDoc, error) {
+	docs, err := fetchLocatieserver(ctx, s.client, params)
+	if err != nil {
+		s.log.Error("pdok locatieserver lookup failed", "error", err)
+		return nil, err
+	}
+	return docs, nil
+}
+
+func fetchLocatieserver(ctx context.Context, client *http.Client, params url.Values) ([]locatieserverDoc, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pdokLocatieserverURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("upstream request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &upstreamStatusError{status: resp.StatusCode}
+	}
+
+	var payload struct {
+		Response struct {
+			Docs []locatieserverDoc `json:"docs"`
+		} `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+	return payload.Response.Docs, nil
+}
+
+// matchHouseNumber picks the doc matching the house letter and addition. Without a letter
+// or addition, the plain house number is preferred over e.g. "12a".
+func matchHouseNumber(docs []locatieserverDoc, letter, addition string) (locatieserverDoc, bool) {
+	for _, doc := range docs {
+		if strings.EqualFold(doc.Huisletter, letter) && strings.EqualFold(doc.Huisnummertoevoeging, addition) {
+			return doc, true
+		}
+	}
+	// A combined addition such as "12-A" may be registered as a house letter.
+	if letter == "" && len(addition) == 1 {
+		for _, doc := range docs {
+			if strings.EqualFold(doc.Huisletter, addition) && doc.Huisnummertoevoeging == "" {
+				return doc, true
+			}
+		}
+	}
+	return locatieserverDoc{}, false
+}
+
+func (d locatieserverDoc) toBAGAddress() BAGAddress {
+	houseNumber := strconv.Itoa(d.Huisnummer) + d.Huisletter
+	if d.Huisnummertoevoeging != "" {
+		houseNumber += "-" + d.Huisnummertoevoeging
+	}
+
+	addr := BAGAddress{
+		Label:                 d.Weergavenaam,
+		Street:                d.Straatnaam,
+		HouseNumber:           houseNumber,
+		ZipCode:               d.Postcode,
+		City:                  d.Woonplaatsnaam,
+		NummeraanduidingID:    d.NummeraanduidingID,
+		AdresseerbaarObjectID: d.AdresseerbaarObjectID,
+	}
+	if match := rePointCoordinates.FindStringSubmatch(d.CentroideLL); len(match) == 3 {
+		lon, lonErr := strconv.ParseFloat(match[1], 64)
+		lat, latErr := strconv.ParseFloat(match[2], 64)
+		if lonErr == nil && latErr == nil {
+			addr.Lat = &lat
+			addr.Lon = &lon
+		}
+	}
+	return addr
+}
@@ -0,0 +1,55 @@
+package maps
+
+import "testing"
+
+func TestParseHouseNumber(t *testing.T) {
+	cases := map[string][3]string{
+		"12":    {"12", "", ""},
+		"12a":   {"12", "a", ""},
+		"12 A":  {"12", "A", ""},
+		"12-3":  {"12", "", "3"},
+		"12A-1": {"12", "A", "1"},
+	}
+	for raw, want := range cases {
+		number, letter, addition := ParseHouseNumber(raw)
+		if number != want[0] || letter != want[1] || addition != want[2] {
+			t.Fatalf("ParseHouseNumber(%q) = %q, %q, %q; want %v", raw, number, letter, addition, want)
+		}
+	}
+}
+
+func TestMatchHouseNumberPrefersExactMatch(t *testing.T) {
+	docs := []locatieserverDoc{
+		{Huisnummer: 12, Huisletter: "A"},
+		{Huisnummer: 12},
+		{Huisnummer: 12, Huisnummertoevoeging: "3"},
+	}
+	if doc, ok := matchHouseNumber(docs, "", ""); !ok || doc.Huisletter != "" || doc.Huisnummertoevoeging != "" {
+		t.Fatalf("expected plain house number, got %+v", doc)
+	}
+	if doc, ok := matchHouseNumber(docs, "", "a"); !ok || doc.Huisletter != "A" {
+		t.Fatalf("expected addition to match house letter, got %+v", doc)
+	}
+	if _, ok := matchHouseNumber(docs, "", "5"); ok {
+		t.Fatal("expected unknown addition not to match")
+	}
+}
+
+func TestLocatieserverDocToBAGAddress(t *testing.T) {
+	addr := locatieserverDoc{
+		Straatnaam:           "Kerkstraat",
+		Huisnummer:           12,
+		Huisletter:           "A",
+		Huisnummertoevoeging: "1",
+		Postcode:             "1234AB",
+		Woonplaatsnaam:       "Utrecht",
+		CentroideLL:          "POINT(5.1214 52.0907)",
+	}.toBAGAddress()
+
+	if addr.HouseNumber != "12A-1" || addr.City != "Utrecht" {
+		t.Fatalf("unexpected address: %+v", addr)
+	}
+	if addr.Lat == nil || addr.Lon == nil || *addr.Lat != 52.0907 || *addr.Lon != 5.1214 {
+		t.Fatalf("expected coordinates from centroid, got lat=%v lon=%v", addr.Lat, addr.Lon)
+	}
+}
@@ -0,0 +1,205 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/config"
+)
+
+// Geocode results are cached by normalized address. Misses are cached for a
+// shorter time, as new buildings get registered.
+const (
+	geocodeCacheTTL     = 180 * 24 * time.Hour
+	geocodeMissCacheTTL = 7 * 24 * time.Hour
+)
+
+// ErrGeocodeQuotaExceeded is returned by a geocoder whose provider refuses
+// further requests for now.
+var ErrGeocodeQuotaExceeded = errors.New("geocoding provider quota exceeded")
+
+// ErrNoGeocoderAvailable is returned when every provider is out of quota.
+var ErrNoGeocoderAvailable = errors.New("no geocoding provider available")
+
+// GeocodeQuery is an address to geocode.
+type GeocodeQuery struct {
+	Street      string
+	HouseNumber string
+	ZipCode     string
+	City        string
+}
+
+// String formats the address as "Street 12, 1234AB City".
+func (q GeocodeQuery) String() string {
+	streetPart := strings.TrimSpace(strings.Join([]string{strings.TrimSpace(q.Street), strings.TrimSpace(q.HouseNumber)}, " "))
+	cityPart := strings.TrimSpace(strings.Join([]string{NormalizePostcode(q.ZipCode), strings.TrimSpace(q.City)}, " "))
+	return strings.Trim(strings.TrimSpace(streetPart+", "+cityPart), ", ")
+}
+
+// CacheKey identifies the address regardless of casing and spacing.
+func (q GeocodeQuery) CacheKey() string {
+	collapse := func(value string) string {
+		return strings.ToLower(strings.Join(strings.Fields(value), " "))
+	}
+	return strings.Join([]string{
+		NormalizePostcode(q.ZipCode),
+		strings.ToLower(strings.Join(strings.Fields(q.HouseNumber), "")),
+		collapse(q.Street),
+		collapse(q.City),
+	}, "|")
+}
+
+// GeocodeResult is the position of an address.
+type GeocodeResult struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	Label    string  `json:"label,omitempty"`
+	Provider string  `json:"provider"`
+}
+
+// Geocoder resolves an address to a position with one provider. It returns
+// (nil, nil) when the provider does not know the address.
+type Geocoder interface {
+	Name() string
+	Geocode(ctx context.Context, query GeocodeQuery) (*GeocodeResult, error)
+}
+
+// GeocodeProvider is a geocoder with the number of requests it may serve per
+// UTC day. A DailyLimit of 0 means unlimited.
+type GeocodeProvider struct {
+	Geocoder   Geocoder
+	DailyLimit int
+}
+
+// CachedGeocode is a cached geocode answer. Result is nil for addresses no
+// provider could resolve.
+type CachedGeocode struct {
+	Result    *GeocodeResult
+	ExpiresAt time.Time
+}
+
+// GeocodeStore caches geocode answers and tracks provider quota.
+type GeocodeStore interface {
+	GetCachedGeocode(ctx context.Context, key string, now time.Time) (CachedGeocode, bool, error)
+	PutCachedGeocode(ctx context.Context, key string, entry CachedGeocode) error
+	// ReserveGeocodeQuota counts one request against the provider's quota of
+	// the day of now and reports false when the quota is used up or the
+	// provider is throttled.
+	ReserveGeocodeQuota(ctx context.Context, provider string, dailyLimit int, now time.Time) (bool, error)
+	ThrottleGeocodeProvider(ctx context.Context, provider string, now, until time.Time) error
+}
+
+// NewGeocodeProviders builds the configured providers in fallback order.
+// Unknown names and Google without an API key are skipped.
+func NewGeocodeProviders(cfg config.GeocodingConfig) []GeocodeProvider {
+	client := &http.Client{Timeout: 5 * time.Second}
+	providers := make([]GeocodeProvider, 0, 3)
+	for _, name := range cfg.GetGeocodingProviders() {
+		var geocoder Geocoder
+		switch strings.ToLower(name) {
+		case geocoderPDOK:
+			geocoder = NewPDOKGeocoder(client)
+		case geocoderNominatim:
+			geocoder = NewNominatimGeocoder(client)
+		case geocoderGoogle:
+			if cfg.GetGoogleGeocodingAPIKey() == "" {
+				continue
+			}
+			geocoder = NewGoogleGeocoder(client, cfg.GetGoogleGeocodingAPIKey())
+		default:
+			continue
+		}
+		providers = append(providers, GeocodeProvider{Geocoder: geocoder, DailyLimit: cfg.GetGeocodingDailyLimit(geocoder.Name())})
+	}
+	return providers
+}
+
+// SetGeocoding replaces the geocoding providers and sets the store that
+// caches answers and tracks quota. The store may be nil.
+func (s *Service) SetGeocoding(providers []GeocodeProvider, store GeocodeStore) {
+	s.geocoders = providers
+	s.geocodeStore = store
+}
+
+// Geocode resolves an address to a position. Cached answers are served
+// without calling any provider; otherwise the providers are tried in order,
+// skipping those out of quota, until one knows the address. It returns
+// (nil, nil) when no provider does.
+func (s *Service) Geocode(ctx context.Context, query GeocodeQuery) (*GeocodeResult, error) {
+	if query.String() == "" {
+		return nil, nil
+	}
+	key := query.CacheKey()
+	now := time.Now().UTC()
+
+	if s.geocodeStore != nil {
+		cached, ok, err := s.geocodeStore.GetCachedGeocode(ctx, key, now)
+		if err != nil {
+			s.log.Warn("geocode cache lookup failed", "error", err)
+		} else if ok {
+			return cached.Result, nil
+		}
+	}
+
+	answered := false
+	var lastErr error
+	for _, provider := range s.geocoders {
+		name := provider.Geocoder.Name()
+		if s.geocodeStore != nil {
+			available, err := s.geocodeStore.ReserveGeocodeQuota(ctx, name, provider.DailyLimit, now)
+			if err != nil {
+				s.log.Warn("geocode quota reservation failed", "provider", name, "error", err)
+			} else if !available {
+				continue
+			}
+		}
+
+		result, err := provider.Geocoder.Geocode(ctx, query)
+		if errors.Is(err, ErrGeocodeQuotaExceeded) {
+			s.log.Warn("geocoding provider out of quota, falling back", "provider", name)
+			if s.geocodeStore != nil {
+				if err := s.geocodeStore.ThrottleGeocodeProvider(ctx, name, now, nextUTCDay(now)); err != nil {
+					s.log.Warn("failed to throttle geocoding provider", "provider", name, "error", err)
+				}
+			}
+			continue
+		}
+		if err != nil {
+			s.log.Warn("geocoding provider failed, falling back", "provider", name, "error", err)
+			lastErr = err
+			continue
+		}
+
+		answered = true
+		if result != nil {
+			s.cacheGeocode(ctx, key, result, now.Add(geocodeCacheTTL))
+			return result, nil
+		}
+	}
+
+	if answered {
+		s.cacheGeocode(ctx, key, nil, now.Add(geocodeMissCacheTTL))
+		return nil, nil
+	}
+	if lastErr == nil {
+		lastErr = ErrNoGeocoderAvailable
+	}
+	return nil, lastErr
+}
+
+func (s *Service) cacheGeocode(ctx context.Context, key string, result *GeocodeResult, expiresAt time.Time) {
+	if s.geocodeStore == nil {
+		return
+	}
+	if err := s.geocodeStore.PutCachedGeocode(ctx, key, CachedGeocode{Result: result, ExpiresAt: expiresAt}); err != nil {
+		s.log.Warn("failed to cache geocode result", "error", err)
+	}
+}
+
+func nextUTCDay(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,239 @@
+package maps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	geocoderPDOK      = "pdok"
+	geocoderNominatim = "nominatim"
+	geocoderGoogle    = "google"
+
+	googleGeocodeURL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+	// nominatimInterval is the request rate the Nominatim usage policy allows.
+	nominatimInterval = time.Second
+)
+
+// isQuotaStatus reports whether an upstream answer means the provider is
+// rate limiting us.
+func isQuotaStatus(err error) bool {
+	var statusErr *upstreamStatusError
+	return errors.As(err, &statusErr) && statusErr.status == http.StatusTooManyRequests
+}
+
+// pdokGeocoder geocodes Dutch addresses with the PDOK Locatieserver. With a
+// postcode and house number it looks up the exact BAG address.
+type pdokGeocoder struct {
+	client *http.Client
+}
+
+// NewPDOKGeocoder creates a geocoder backed by the PDOK Locatieserver.
+func NewPDOKGeocoder(client *http.Client) Geocoder {
+	return &pdokGeocoder{client: client}
+}
+
+func (g *pdokGeocoder) Name() string { return geocoderPDOK }
+
+func (g *pdokGeocoder) Geocode(ctx context.Context, query GeocodeQuery) (*GeocodeResult, error) {
+	postcode := NormalizePostcode(query.ZipCode)
+	number, letter, addition := ParseHouseNumber(query.HouseNumber)
+	exact := rePostcode.MatchString(postcode) && number != ""
+
+	params := url.Values{
+		"q":    {query.String()},
+		"fq":   {"type:adres"},
+		"fl":   {locatieserverFields},
+		"rows": {"1"},
+	}
+	if exact {
+		params.Set("q", postcode+" "+number)
+		params["fq"] = []string{"type:adres", "postcode:" + postcode, "huisnummer:" + number}
+		params.Set("rows", "20")
+	}
+
+	docs, err := fetchLocatieserver(ctx, g.client, params)
+	if isQuotaStatus(err) {
+		return nil, ErrGeocodeQuotaExceeded
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc locatieserverDoc
+	switch {
+	case exact:
+		matched, ok := matchHouseNumber(docs, letter, addition)
+		if !ok {
+			return nil, nil
+		}
+		doc = matched
+	case len(docs) > 0:
+		doc = docs[0]
+	default:
+		return nil, nil
+	}
+
+	addr := doc.toBAGAddress()
+	if addr.Lat == nil || addr.Lon == nil {
+		return nil, nil
+	}
+	return &GeocodeResult{Lat: *addr.Lat, Lon: *addr.Lon, Label: addr.Label, Provider: geocoderPDOK}, nil
+}
+
+// nominatimGeocoder geocodes with OpenStreetMap Nominatim, at most one
+// request per second as its usage policy requires.
+type nominatimGeocoder struct {
+	client  *http.Client
+	limiter *intervalLimiter
+}
+
+// NewNominatimGeocoder creates a geocoder backed by Nominatim.
+func NewNominatimGeocoder(client *http.Client) Geocoder {
+	return &nominatimGeocoder{client: client, limiter: &intervalLimiter{interval: nominatimInterval}}
+}
+
+func (g *nominatimGeocoder) Name() string { return geocoderNominatim }
+
+func (g *nominatimGeocoder) Geocode(ctx context.Context, query GeocodeQuery) (*GeocodeResult, error) {
+	if err := g.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := searchNominatim(ctx, g.client, query.String())
+	if isQuotaStatus(err) {
+		return nil, ErrGeocodeQuotaExceeded
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestions) == 0 {
+		return nil, nil
+	}
+
+	lat, err := strconv.ParseFloat(suggestions[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nominatim latitude %q: %w", suggestions[0].Lat, err)
+	}
+	lon, err := strconv.ParseFloat(suggestions[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nominatim longitude %q: %w", suggestions[0].Lon, err)
+	}
+	return &GeocodeResult{Lat: lat, Lon: lon, Label: suggestions[0].Label, Provider: geocoderNominatim}, nil
+}
+
+// googleGeocoder geocodes with the Google Geocoding API.
+type googleGeocoder struct {
+	client  *http.Client
+	apiKey  string
+	baseURL string
+}
+
+// NewGoogleGeocoder creates a geocoder backed by the Google Geocoding API.
+func NewGoogleGeocoder(client *http.Client, apiKey string) Geocoder {
+	return &googleGeocoder{client: client, apiKey: apiKey, baseURL: googleGeocodeURL}
+}
+
+func (g *googleGeocoder) Name() string { return geocoderGoogle }
+
+func (g *googleGeocoder) Geocode(ctx context.Context, query GeocodeQuery) (*GeocodeResult, error) {
+	params := url.Values{
+		"address":    {query.String()},
+		"components": {"country:NL"},
+		"key":        {g.apiKey},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("upstream request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, ErrGeocodeQuotaExceeded
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &upstreamStatusError{status: resp.StatusCode}
+	}
+
+	var payload struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Results      []struct {
+			FormattedAddress string `json:"formatted_address"`
+			Geometry         struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+
+	switch payload.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, nil
+	case "OVER_QUERY_LIMIT", "OVER_DAILY_LIMIT":
+		return nil, ErrGeocodeQuotaExceeded
+	default:
+		return nil, fmt.Errorf("google geocoding status %s: %s", payload.Status, payload.ErrorMessage)
+	}
+	if len(payload.Results) == 0 {
+		return nil, nil
+	}
+
+	first := payload.Results[0]
+	return &GeocodeResult{
+		Lat:      first.Geometry.Location.Lat,
+		Lon:      first.Geometry.Location.Lng,
+		Label:    first.FormattedAddress,
+		Provider: geocoderGoogle,
+	}, nil
+}
+
+// intervalLimiter spaces calls at least interval apart.
+type intervalLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func (l *intervalLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := at.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
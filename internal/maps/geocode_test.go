@@ -0,0 +1,148 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"portal_final_backend/platform/logger"
+)
+
+type fakeGeocoder struct {
+	name   string
+	result *GeocodeResult
+	err    error
+	calls  int
+}
+
+func (g *fakeGeocoder) Name() string { return g.name }
+
+func (g *fakeGeocoder) Geocode(context.Context, GeocodeQuery) (*GeocodeResult, error) {
+	g.calls++
+	return g.result, g.err
+}
+
+type fakeGeocodeStore struct {
+	cache     map[string]CachedGeocode
+	usage     map[string]int
+	throttled map[string]time.Time
+}
+
+func newFakeGeocodeStore() *fakeGeocodeStore {
+	return &fakeGeocodeStore{cache: map[string]CachedGeocode{}, usage: map[string]int{}, throttled: map[string]time.Time{}}
+}
+
+func (s *fakeGeocodeStore) GetCachedGeocode(_ context.Context, key string, now time.Time) (CachedGeocode, bool, error) {
+	entry, ok := s.cache[key]
+	if !ok || !entry.ExpiresAt.After(now) {
+		return CachedGeocode{}, false, nil
+	}
+	return entry, true, nil
+}
+
+func (s *fakeGeocodeStore) PutCachedGeocode(_ context.Context, key string, entry CachedGeocode) error {
+	s.cache[key] = entry
+	return nil
+}
+
+func (s *fakeGeocodeStore) ReserveGeocodeQuota(_ context.Context, provider string, dailyLimit int, now time.Time) (bool, error) {
+	if until, ok := s.throttled[provider]; ok && until.After(now) {
+		return false, nil
+	}
+	if dailyLimit > 0 && s.usage[provider] >= dailyLimit {
+		return false, nil
+	}
+	s.usage[provider]++
+	return true, nil
+}
+
+func (s *fakeGeocodeStore) ThrottleGeocodeProvider(_ context.Context, provider string, _, until time.Time) error {
+	s.throttled[provider] = until
+	return nil
+}
+
+var testGeocodeQuery = GeocodeQuery{Street: "Kerkstraat", HouseNumber: "12", ZipCode: "3511 ab", City: "Utrecht"}
+
+func TestGeocodeFallsBackAndCaches(t *testing.T) {
+	quota := &fakeGeocoder{name: "pdok", err: ErrGeocodeQuotaExceeded}
+	missing := &fakeGeocoder{name: "nominatim"}
+	found := &fakeGeocoder{name: "google", result: &GeocodeResult{Lat: 52.09, Lon: 5.12, Provider: "google"}}
+	store := newFakeGeocodeStore()
+
+	svc := NewService(logger.New("development"))
+	svc.SetGeocoding([]GeocodeProvider{{Geocoder: quota}, {Geocoder: missing}, {Geocoder: found}}, store)
+
+	result, err := svc.Geocode(context.Background(), testGeocodeQuery)
+	if err != nil || result == nil || result.Provider != "google" {
+		t.Fatalf("Geocode = %+v, %v; want google result", result, err)
+	}
+	if _, ok := store.throttled["pdok"]; !ok {
+		t.Fatal("provider out of quota was not throttled")
+	}
+
+	// Same address, different spelling: answered from the cache.
+	again := GeocodeQuery{Street: "KERKSTRAAT ", HouseNumber: "12", ZipCode: "3511AB", City: "utrecht"}
+	if result, err := svc.Geocode(context.Background(), again); err != nil || result == nil {
+		t.Fatalf("cached Geocode = %+v, %v", result, err)
+	}
+	if quota.calls != 1 || missing.calls != 1 || found.calls != 1 {
+		t.Fatalf("calls = %d/%d/%d, want each provider called once", quota.calls, missing.calls, found.calls)
+	}
+}
+
+func TestGeocodeRespectsDailyLimit(t *testing.T) {
+	limited := &fakeGeocoder{name: "google", result: &GeocodeResult{Lat: 1, Lon: 1}}
+	store := newFakeGeocodeStore()
+	store.usage["google"] = 5
+
+	svc := NewService(logger.New("development"))
+	svc.SetGeocoding([]GeocodeProvider{{Geocoder: limited, DailyLimit: 5}}, store)
+
+	if _, err := svc.Geocode(context.Background(), testGeocodeQuery); !errors.Is(err, ErrNoGeocoderAvailable) {
+		t.Fatalf("err = %v, want ErrNoGeocoderAvailable", err)
+	}
+	if limited.calls != 0 {
+		t.Fatal("provider over its daily limit was called")
+	}
+}
+
+func TestGeocodeCachesMisses(t *testing.T) {
+	missing := &fakeGeocoder{name: "pdok"}
+	store := newFakeGeocodeStore()
+
+	svc := NewService(logger.New("development"))
+	svc.SetGeocoding([]GeocodeProvider{{Geocoder: missing}}, store)
+
+	for i := 0; i < 2; i++ {
+		if result, err := svc.Geocode(context.Background(), testGeocodeQuery); err != nil || result != nil {
+			t.Fatalf("Geocode = %+v, %v; want no result", result, err)
+		}
+	}
+	if missing.calls != 1 {
+		t.Fatalf("calls = %d, want miss served from cache", missing.calls)
+	}
+}
+
+func TestGoogleGeocoderQuotaStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"OVER_QUERY_LIMIT","results":[]}`))
+	}))
+	defer server.Close()
+
+	geocoder := &googleGeocoder{client: server.Client(), apiKey: "key", baseURL: server.URL}
+	if _, err := geocoder.Geocode(context.Background(), testGeocodeQuery); !errors.Is(err, ErrGeocodeQuotaExceeded) {
+		t.Fatalf("err = %v, want ErrGeocodeQuotaExceeded", err)
+	}
+}
+
+func TestGeocodeQueryCacheKey(t *testing.T) {
+	if got, want := testGeocodeQuery.CacheKey(), "3511AB|12|kerkstraat|utrecht"; got != want {
+		t.Fatalf("CacheKey = %q, want %q", got, want)
+	}
+	if got, want := testGeocodeQuery.String(), "Kerkstraat 12, 3511AB Utrecht"; got != want {
+		t.Fatalf("String = %q, want %q", got, want)
+	}
+}
@@ -37,6 +37,13 @@ func (m *Module) Name() string {
 func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	group := ctx.Protected.Group("/maps")
 	group.GET("/address-lookup", m.lookupAddress)
+	group.GET("/address-autocomplete", m.autocompleteAddress)
+	group.GET("/address-validate", m.validateAddress)
+}
+
+// Service returns the maps service.
+func (m *Module) Service() *Service {
+	return m.svc
 }
 
 // lookupAddress handles GET /api/v1/maps/address-lookup?q=...
@@ -58,17 +65,66 @@ func (m *Module) lookupAddress(c *gin.Context) {
 	httpkit.OK(c, results)
 }
 
-// Service handles address lookup via Nominatim.
+// autocompleteAddress handles GET /api/v1/maps/address-autocomplete?q=...
+func (m *Module) autocompleteAddress(c *gin.Context) {
+	var req struct {
+		Query string `form:"q" binding:"required,min=3"`
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "query 'q' is required (min 3 chars)", nil)
+		return
+	}
+
+	results, err := m.svc.AutocompleteAddress(c.Request.Context(), req.Query)
+	if err != nil {
+		httpkit.Error(c, http.StatusBadGateway, "address lookup service unavailable", nil)
+		return
+	}
+
+	httpkit.OK(c, results)
+}
+
+// validateAddress handles GET /api/v1/maps/address-validate?zipCode=...&houseNumber=...
+// and returns the normalized BAG address when it exists.
+func (m *Module) validateAddress(c *gin.Context) {
+	var req struct {
+		ZipCode     string `form:"zipCode" binding:"required"`
+		HouseNumber string `form:"houseNumber" binding:"required"`
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "zipCode and houseNumber are required", nil)
+		return
+	}
+
+	address, err := m.svc.ValidateAddress(c.Request.Context(), req.ZipCode, req.HouseNumber)
+	if err != nil {
+		httpkit.Error(c, http.StatusBadGateway, "address lookup service unavailable", nil)
+		return
+	}
+
+	httpkit.OK(c, gin.H{"valid": address != nil, "address": address})
+}
+
+// Service handles address lookup via Nominatim and the BAG, and geocoding
+// through a chain of providers.
 type Service struct {
-	client *http.Client
-	log    *logger.Logger
+	client       *http.Client
+	log          *logger.Logger
+	geocoders    []GeocodeProvider
+	geocodeStore GeocodeStore
 }
 
-// NewService creates a new Service for maps.
+// NewService creates a new Service for maps. It geocodes with PDOK and
+// Nominatim, without a cache, until SetGeocoding configures otherwise.
 func NewService(log *logger.Logger) *Service {
+	client := &http.Client{Timeout: 5 * time.Second}
 	return &Service{
-		client: &http.Client{Timeout: 5 * time.Second},
+		client: client,
 		log:    log,
+		geocoders: []GeocodeProvider{
+			{Geocoder: NewPDOKGeocoder(client)},
+			{Geocoder: NewNominatimGeocoder(client)},
+		},
 	}
 }
 
@@ -85,6 +141,24 @@ type AddressSuggestion struct {
 
 // SearchAddress queries Nominatim for address suggestions.
 func (s *Service) SearchAddress(ctx context.Context, query string) ([]AddressSuggestion, error) {
+	suggestions, err := searchNominatim(ctx, s.client, query)
+	if err != nil {
+		s.log.Error("nominatim lookup failed", "error", err)
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// upstreamStatusError is a non-200 answer of an address lookup API.
+type upstreamStatusError struct {
+	status int
+}
+
+func (e *upstreamStatusError) Error() string {
+	return fmt.Sprintf("upstream api error: %d", e.status)
+}
+
+func searchNominatim(ctx context.Context, client *http.Client, query string) ([]AddressSuggestion, error) {
 	params := url.Values{
 		"q":              {query},
 		"format":         {"json"},
@@ -100,16 +174,14 @@ func (s *Service) SearchAddress(ctx context.Context, query string) ([]AddressSug
 	// Security: Prevent blocklisting by providing an explicit User-Agent
 	req.Header.Set("User-Agent", "PortalApp/1.0")
 
-	resp, err := s.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		s.log.Error("nominatim request failed", "error", err)
 		return nil, fmt.Errorf("upstream request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		s.log.Error("nominatim upstream error", "status", resp.StatusCode)
-		return nil, fmt.Errorf("upstream api error: %d", resp.StatusCode)
+		return nil, &upstreamStatusError{status: resp.StatusCode}
 	}
 
 	var rawResults []struct {
@@ -128,7 +200,6 @@ func (s *Service) SearchAddress(ctx context.Context, query string) ([]AddressSug
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&rawResults); err != nil {
-		s.log.Error("failed to decode nominatim payload", "error", err)
 		return nil, fmt.Errorf("decode error: %w", err)
 	}
 
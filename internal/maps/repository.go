@@ -0,0 +1,118 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository is the Postgres GeocodeStore.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository creates a new geocode Repository.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+var _ GeocodeStore = (*Repository)(nil)
+
+// GetCachedGeocode returns the unexpired cache entry of an address.
+func (r *Repository) GetCachedGeocode(ctx context.Context, key string, now time.Time) (CachedGeocode, bool, error) {
+	var (
+		lat, lon        *float64
+		label, provider *string
+		entry           CachedGeocode
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT latitude, longitude, label, provider, expires_at
+		FROM RAC_geocode_cache
+		WHERE address_key = $1 AND expires_at > $2`, key, now,
+	).Scan(&lat, &lon, &label, &provider, &entry.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return CachedGeocode{}, false, nil
+	}
+	if err != nil {
+		return CachedGeocode{}, false, fmt.Errorf("get cached geocode: %w", err)
+	}
+
+	if lat != nil && lon != nil {
+		entry.Result = &GeocodeResult{Lat: *lat, Lon: *lon}
+		if label != nil {
+			entry.Result.Label = *label
+		}
+		if provider != nil {
+			entry.Result.Provider = *provider
+		}
+	}
+	return entry, true, nil
+}
+
+// PutCachedGeocode stores or replaces the cache entry of an address.
+func (r *Repository) PutCachedGeocode(ctx context.Context, key string, entry CachedGeocode) error {
+	var (
+		lat, lon        *float64
+		label, provider *string
+	)
+	if entry.Result != nil {
+		lat, lon = &entry.Result.Lat, &entry.Result.Lon
+		label, provider = &entry.Result.Label, &entry.Result.Provider
+	}
+
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_geocode_cache (address_key, latitude, longitude, label, provider, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (address_key) DO UPDATE SET
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			label = EXCLUDED.label,
+			provider = EXCLUDED.provider,
+			created_at = now(),
+			expires_at = EXCLUDED.expires_at`,
+		key, lat, lon, label, provider, entry.ExpiresAt,
+	); err != nil {
+		return fmt.Errorf("put cached geocode: %w", err)
+	}
+	return nil
+}
+
+// ReserveGeocodeQuota counts one request against the provider's quota of the
+// UTC day of now. The count only increases while the provider is within its
+// limit and not throttled.
+func (r *Repository) ReserveGeocodeQuota(ctx context.Context, provider string, dailyLimit int, now time.Time) (bool, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_geocode_provider_usage AS u (provider, usage_date, request_count)
+		VALUES ($1, $2::date, 1)
+		ON CONFLICT (provider, usage_date) DO UPDATE SET request_count = u.request_count + 1
+		WHERE ($3 = 0 OR u.request_count < $3)
+			AND (u.throttled_until IS NULL OR u.throttled_until <= $4)
+		RETURNING request_count`,
+		provider, now.UTC().Format(time.DateOnly), dailyLimit, now,
+	).Scan(&count)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reserve geocode quota: %w", err)
+	}
+	return true, nil
+}
+
+// ThrottleGeocodeProvider skips a provider until the given time.
+func (r *Repository) ThrottleGeocodeProvider(ctx context.Context, provider string, now, until time.Time) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_geocode_provider_usage (provider, usage_date, throttled_until)
+		VALUES ($1, $2::date, $3)
+		ON CONFLICT (provider, usage_date) DO UPDATE SET throttled_until = EXCLUDED.throttled_until`,
+		provider, now.UTC().Format(time.DateOnly), until,
+	); err != nil {
+		return fmt.Errorf("throttle geocode provider: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	leadrepo "portal_final_backend/internal/leads/repository"
+	notificationoutbox "portal_final_backend/internal/notification/outbox"
+
+	"github.com/google/uuid"
+)
+
+// LeadConsentReader reads the channel and purpose consent a lead manages in
+// the preference center.
+type LeadConsentReader interface {
+	GetCommunicationConsent(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) (leadrepo.CommunicationConsent, error)
+}
+
+// SetLeadConsentReader injects a reader for lead communication consent.
+func (m *Module) SetLeadConsentReader(reader LeadConsentReader) { m.leadConsentReader = reader }
+
+// marketingWorkflowTriggers are the triggers whose messages promote rather
+// than inform about work the lead asked for. Every other message is
+// transactional.
+var marketingWorkflowTriggers = map[string]bool{
+	"lead_stale":        true,
+	"warranty_expiring": true,
+}
+
+func workflowMessagePurpose(trigger string) string {
+	if marketingWorkflowTriggers[trigger] {
+		return leadrepo.ConsentMarketing
+	}
+	return leadrepo.ConsentTransactional
+}
+
+// allowOutboxLeadMessage reports whether an outbox message to a lead may be
+// sent under the consent of the lead. Refused messages are marked succeeded
+// so they are not retried; lookup failures are returned for a retry. A
+// withheld marketing message asks the lead to confirm its marketing consent.
+func (m *Module) allowOutboxLeadMessage(ctx context.Context, rec notificationoutbox.Record, leadID uuid.UUID, orgID uuid.UUID, channel string, purpose string, recipient string) (bool, error) {
+	if m.leadConsentReader == nil {
+		return true, nil
+	}
+	if purpose == "" {
+		purpose = leadrepo.ConsentTransactional
+	}
+	consent, err := m.leadConsentReader.GetCommunicationConsent(ctx, leadID, orgID)
+	if err != nil {
+		if errors.Is(err, leadrepo.ErrNotFound) {
+			m.log.Info("lead not found for outbox; marking succeeded", "outboxId", rec.ID.String(), "leadId", leadID, "orgId", orgID)
+			_ = m.notificationOutbox.MarkSucceeded(ctx, rec.ID)
+			return false, nil
+		}
+		m.log.Warn("failed to resolve lead consent for outbox; will retry", "outboxId", rec.ID.String(), "leadId", leadID, "orgId", orgID, "error", err)
+		return false, err
+	}
+	if !consent.Allows(channel, purpose) {
+		m.log.Info("lead withheld consent; skipping outbox send", "outboxId", rec.ID.String(), "leadId", leadID, "orgId", orgID, "channel", channel, "purpose", purpose)
+		_ = m.notificationOutbox.MarkSucceeded(ctx, rec.ID)
+		if purpose == leadrepo.ConsentMarketing && consent.MarketingUnconfirmed() && consent.Allows(channel, leadrepo.ConsentTransactional) {
+			m.requestMarketingConfirmation(ctx, leadID, orgID, channel, recipient)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// leadAllowsTransactionalWhatsApp checks the purpose consent for WhatsApp
+// messages sent directly from event handlers, which all concern work the
+// lead asked for.
+func (m *Module) leadAllowsTransactionalWhatsApp(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) bool {
+	if m.leadConsentReader == nil {
+		return true
+	}
+	consent, err := m.leadConsentReader.GetCommunicationConsent(ctx, leadID, organizationID)
+	if err != nil {
+		m.log.Warn("failed to resolve lead consent", "leadId", leadID, "orgId", organizationID, "error", err)
+		return false
+	}
+	if !consent.Allows(leadrepo.ConsentWhatsApp, leadrepo.ConsentTransactional) {
+		m.log.Info("lead withheld consent for transactional whatsapp, skipping message", "leadId", leadID, "orgId", organizationID)
+		return false
+	}
+	return true
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"portal_final_backend/internal/email"
 	"portal_final_backend/internal/identity/repository"
-	"portal_final_backend/internal/identity/smtpcrypto"
 	"strings"
 	"time"
 
@@ -19,8 +18,9 @@ type cachedSender struct {
 }
 
 // resolveSender returns a tenant-specific SMTPSender if the organization has SMTP
-// configured, falling back to the default (Brevo) sender. Results are cached with a
-// 5-minute TTL to avoid repeated DB lookups and decryption.
+// configured, falling back to the default (Brevo) sender, with the organization
+// branding applied. Results are cached with a 5-minute TTL to avoid repeated DB
+// lookups and decryption.
 func (m *Module) resolveSender(ctx context.Context, orgID uuid.UUID) email.Sender {
 
 	if cached, ok := m.senderCache.Load(orgID); ok {
@@ -31,41 +31,69 @@ func (m *Module) resolveSender(ctx context.Context, orgID uuid.UUID) email.Sende
 		m.senderCache.Delete(orgID)
 	}
 
+	sender, cacheable := m.resolveTransportSender(ctx, orgID)
+	sender = m.applyBranding(ctx, orgID, sender)
+	if cacheable {
+		m.senderCache.Store(orgID, cachedSender{sender: sender, expiresAt: time.Now().Add(5 * time.Minute)})
+	}
+	return sender
+}
+
+// resolveTransportSender picks the tenant SMTP sender or the default sender.
+// It reports whether the result may be cached.
+func (m *Module) resolveTransportSender(ctx context.Context, orgID uuid.UUID) (email.Sender, bool) {
 	if m.settingsReader == nil {
-		return m.sender
+		return m.sender, false
 	}
 
 	settings, err := m.settingsReader.GetOrganizationSettings(ctx, orgID)
 	if err != nil {
 		m.log.Warn("failed to fetch org settings for smtp", "error", err, "orgId", orgID)
-		return m.sender
+		return m.sender, false
 	}
 
 	if settings.SMTPHost == nil || *settings.SMTPHost == "" {
-
-		m.senderCache.Store(orgID, cachedSender{sender: m.sender, expiresAt: time.Now().Add(5 * time.Minute)})
-		return m.sender
+		return m.sender, true
 	}
 
 	smtpSender, err := m.buildSMTPSender(settings)
 	if err != nil {
 		m.log.Error("failed to build smtp sender", "error", err, "orgId", orgID)
-		return m.sender
+		return m.sender, false
 	}
 
-	m.senderCache.Store(orgID, cachedSender{sender: smtpSender, expiresAt: time.Now().Add(5 * time.Minute)})
 	m.log.Info("resolved tenant smtp sender", "orgId", orgID, "host", *settings.SMTPHost)
-	return smtpSender
+	return smtpSender, true
+}
+
+// applyBranding wraps sender with the email branding of the organization.
+// Without branding, or when it cannot be read, sender is returned unchanged.
+func (m *Module) applyBranding(ctx context.Context, orgID uuid.UUID, sender email.Sender) email.Sender {
+	if m.brandingReader == nil {
+		return sender
+	}
+	branding, err := m.brandingReader.GetOrganizationBranding(ctx, orgID)
+	if err != nil {
+		m.log.Warn("failed to fetch org branding for email", "error", err, "orgId", orgID)
+		return sender
+	}
+	return email.WithBranding(sender, email.Branding{
+		PrimaryColor: derefStr(branding.PrimaryColor),
+		AccentColor:  derefStr(branding.AccentColor),
+		HeaderHTML:   derefStr(branding.EmailHeaderHTML),
+		FooterHTML:   derefStr(branding.EmailFooterHTML),
+		SenderName:   derefStr(branding.SenderDisplayName),
+	})
 }
 
 // buildSMTPSender creates an SMTPSender from organization settings, decrypting the password.
 func (m *Module) buildSMTPSender(settings repository.OrganizationSettings) (email.Sender, error) {
 	password := ""
 	if settings.SMTPPassword != nil && *settings.SMTPPassword != "" {
-		if len(m.smtpEncryptionKey) == 0 {
+		if m.smtpKeyring == nil {
 			return nil, fmt.Errorf("smtp password is configured but SMTP_ENCRYPTION_KEY is not set")
 		}
-		decrypted, err := smtpcrypto.Decrypt(*settings.SMTPPassword, m.smtpEncryptionKey)
+		decrypted, err := m.smtpKeyring.Decrypt(*settings.SMTPPassword)
 		if err != nil {
 			return nil, fmt.Errorf("decrypt smtp password: %w", err)
 		}
@@ -108,6 +136,9 @@ type emailSendOutboxPayload struct {
 	BodyHTML    string                    `json:"bodyHtml"`
 	LeadID      *string                   `json:"leadId,omitempty"`
 	ServiceID   *string                   `json:"serviceId,omitempty"`
+	Audience    string                    `json:"audience,omitempty"`
+	Purpose     string                    `json:"purpose,omitempty"`
+	Category    string                    `json:"category,omitempty"`
 	Attachments []emailSendAttachmentSpec `json:"attachments,omitempty"`
 }
 
@@ -117,11 +148,17 @@ type emailSendAttachmentSpec struct {
 	FileKey     string                           `json:"fileKey,omitempty"`
 	FileName    string                           `json:"fileName,omitempty"`
 	MIMEType    string                           `json:"mimeType,omitempty"`
+	Signed      bool                             `json:"signed,omitempty"`
+	FallbackURL string                           `json:"fallbackUrl,omitempty"`
 	ISDESubsidy *isdeSubsidyPDFAttachmentPayload `json:"isdeSubsidy,omitempty"`
+	Calendar    *appointmentCalendarPayload      `json:"calendar,omitempty"`
 }
 
 func (m *Module) buildEmailAttachmentSpecs(dispatchCtx workflowStepDispatchContext) []emailSendAttachmentSpec {
 	trigger := strings.ToLower(strings.TrimSpace(dispatchCtx.Exec.Trigger))
+	if trigger == "appointment_created" || trigger == "appointment_updated" {
+		return buildAppointmentCalendarAttachmentSpecs(dispatchCtx.Exec.Variables)
+	}
 	if trigger != "quote_sent" && trigger != "quote_accepted" {
 		return nil
 	}
@@ -144,12 +181,15 @@ func (m *Module) buildEmailAttachmentSpecs(dispatchCtx workflowStepDispatchConte
 	}
 
 	fileKey, _ := quoteMap["pdfFileKey"].(string)
+	downloadURL, _ := quoteMap["downloadUrl"].(string)
 	attachments := []emailSendAttachmentSpec{{
-		Kind:     "quote_pdf",
-		QuoteID:  &quoteIDText,
-		FileKey:  strings.TrimSpace(fileKey),
-		FileName: buildQuotePDFAttachmentFileName(quoteNumber),
-		MIMEType: pdfMIMEType,
+		Kind:        "quote_pdf",
+		QuoteID:     &quoteIDText,
+		FileKey:     strings.TrimSpace(fileKey),
+		FileName:    buildQuotePDFAttachmentFileName(quoteNumber),
+		MIMEType:    pdfMIMEType,
+		Signed:      trigger == "quote_accepted",
+		FallbackURL: strings.TrimSpace(downloadURL),
 	}}
 
 	if subsidy := buildISDESubsidyAttachmentPayload(dispatchCtx.Exec.Variables, quoteMap); subsidy != nil {
@@ -170,6 +210,8 @@ func (m *Module) resolveEmailAttachment(ctx context.Context, orgID uuid.UUID, sp
 		return m.resolveQuotePDFAttachment(ctx, orgID, spec)
 	case "isde_subsidy_pdf":
 		return m.resolveISDESubsidyPDFAttachment(spec)
+	case "appointment_ics":
+		return resolveAppointmentCalendarAttachment(spec)
 	default:
 		return email.Attachment{}, fmt.Errorf("%w: unsupported attachment kind %q", errInvalidOutboxPayload, spec.Kind)
 	}
@@ -0,0 +1,235 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	identityrepo "portal_final_backend/internal/identity/repository"
+	identityservice "portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/imap/sanitize"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// EmailTemplateReader provides the override an organization sends instead of
+// a built-in system email.
+type EmailTemplateReader interface {
+	GetActiveEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey string) (identityrepo.EmailTemplateVersion, error)
+}
+
+const (
+	emailTemplateVerification       = "verification"
+	emailTemplatePasswordReset      = "password_reset"
+	emailTemplateOrganizationInvite = "organization_invite"
+	emailTemplatePartnerInvite      = "partner_invite"
+
+	testEmailSubjectPrefix = "[Test] "
+)
+
+type systemEmailTemplate struct {
+	Key         string
+	Description string
+	Variables   []workflowVariableSpec
+}
+
+// systemEmailTemplates lists the system emails organizations can override,
+// with the variables each one is sent with.
+var systemEmailTemplates = []systemEmailTemplate{
+	{
+		Key:         emailTemplateVerification,
+		Description: "Sent to confirm the email address of a new account.",
+		Variables: []workflowVariableSpec{
+			{Path: "user.email", Type: workflowVariableTypeString, Example: "jan@example.com"},
+			{Path: "links.verify", Type: workflowVariableTypeString, Example: "https://app.example.com/verify-email?token=abc123"},
+		},
+	},
+	{
+		Key:         emailTemplatePasswordReset,
+		Description: "Sent when a user asks to reset their password.",
+		Variables: []workflowVariableSpec{
+			{Path: "user.email", Type: workflowVariableTypeString, Example: "jan@example.com"},
+			{Path: "links.reset", Type: workflowVariableTypeString, Example: "https://app.example.com/reset-password?token=abc123"},
+		},
+	},
+	{
+		Key:         emailTemplateOrganizationInvite,
+		Description: "Invites a colleague to join the organization.",
+		Variables: []workflowVariableSpec{
+			{Path: "user.email", Type: workflowVariableTypeString, Example: "jan@example.com"},
+			{Path: "org.name", Type: workflowVariableTypeString, Example: "Vakman Portal"},
+			{Path: "links.invite", Type: workflowVariableTypeString, Example: "https://app.example.com/sign-up?token=abc123"},
+		},
+	},
+	{
+		Key:         emailTemplatePartnerInvite,
+		Description: "Invites a partner to view a work offer.",
+		Variables: []workflowVariableSpec{
+			{Path: "user.email", Type: workflowVariableTypeString, Example: "info@installateur.nl"},
+			{Path: "org.name", Type: workflowVariableTypeString, Example: "Vakman Portal"},
+			{Path: "partner.name", Type: workflowVariableTypeString, Example: "Installatiebedrijf De Vries"},
+			{Path: "links.invite", Type: workflowVariableTypeString, Example: "https://app.example.com/partner-invite?token=abc123"},
+		},
+	},
+}
+
+func findSystemEmailTemplate(key string) (systemEmailTemplate, bool) {
+	for _, tpl := range systemEmailTemplates {
+		if tpl.Key == key {
+			return tpl, true
+		}
+	}
+	return systemEmailTemplate{}, false
+}
+
+// SetEmailTemplateReader injects the reader for organization overrides of system emails.
+func (m *Module) SetEmailTemplateReader(reader EmailTemplateReader) {
+	m.emailTemplateReader = reader
+}
+
+// EmailTemplateCatalog lists the system emails organizations can override.
+func (m *Module) EmailTemplateCatalog() []identityservice.EmailTemplateDefinition {
+	catalog := make([]identityservice.EmailTemplateDefinition, 0, len(systemEmailTemplates))
+	for _, tpl := range systemEmailTemplates {
+		variables := make([]identityservice.WorkflowVariable, 0, len(tpl.Variables))
+		for _, spec := range tpl.Variables {
+			variables = append(variables, identityservice.WorkflowVariable{
+				Path:    spec.Path,
+				Type:    spec.Type,
+				Example: spec.Example,
+				Source:  workflowVariableSourceEvent,
+			})
+		}
+		catalog = append(catalog, identityservice.EmailTemplateDefinition{
+			Key:         tpl.Key,
+			Description: tpl.Description,
+			Variables:   variables,
+		})
+	}
+	return catalog
+}
+
+// RenderEmailTemplate renders a system email override with the example
+// values of its variables and the organization's name.
+func (m *Module) RenderEmailTemplate(ctx context.Context, organizationID uuid.UUID, templateKey, subject, bodyHTML string) (identityservice.RenderedEmailTemplate, error) {
+	tpl, ok := findSystemEmailTemplate(templateKey)
+	if !ok {
+		return identityservice.RenderedEmailTemplate{}, apperr.NotFound("email template not found").WithDetails(templateKey)
+	}
+	vars := systemEmailExampleVariables(tpl)
+	if orgName := m.resolveOrganizationName(ctx, organizationID); orgName != "" {
+		if org, ok := vars["org"].(map[string]any); ok {
+			org["name"] = orgName
+		}
+	}
+
+	var result identityservice.RenderedEmailTemplate
+	var err error
+	if result.Subject, err = renderTemplateText(strings.TrimSpace(subject), vars); err != nil {
+		result.TemplateErrors = append(result.TemplateErrors, "subject: "+err.Error())
+	}
+	if result.BodyHTML, err = renderSystemEmailBody(bodyHTML, vars); err != nil {
+		result.TemplateErrors = append(result.TemplateErrors, "body: "+err.Error())
+	}
+	result.UnresolvedVariables, _ = analyzeTemplateVariables(vars, &subject, &bodyHTML)
+	return result, nil
+}
+
+// SendEmailTemplateTest sends a rendered override to the requesting user
+// through the organization's resolved sender.
+func (m *Module) SendEmailTemplateTest(ctx context.Context, organizationID, userID uuid.UUID, rendered identityservice.RenderedEmailTemplate) error {
+	if m.orgMemberReader == nil {
+		return apperr.Internal("organization member lookup is not configured")
+	}
+	members, err := m.orgMemberReader.ListOrgMembers(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	toEmail := ""
+	for _, member := range members {
+		if member.ID == userID {
+			toEmail = strings.TrimSpace(member.Email)
+			break
+		}
+	}
+	if toEmail == "" {
+		return apperr.NotFound("no email address found for the requesting user")
+	}
+
+	sender := m.resolveSender(ctx, organizationID)
+	if err := sender.SendCustomEmail(ctx, toEmail, testEmailSubjectPrefix+rendered.Subject, rendered.BodyHTML); err != nil {
+		return fmt.Errorf("send test email: %w", err)
+	}
+	return nil
+}
+
+// sendSystemEmail sends the organization's override of a system email, or
+// the built-in email through builtIn when it has none or the override
+// cannot be rendered.
+func (m *Module) sendSystemEmail(ctx context.Context, orgID uuid.UUID, templateKey, toEmail string, vars map[string]any, builtIn func() error) error {
+	if m.emailTemplateReader == nil || orgID == uuid.Nil {
+		return builtIn()
+	}
+	override, err := m.emailTemplateReader.GetActiveEmailTemplate(ctx, orgID, templateKey)
+	if err != nil {
+		if !errors.Is(err, identityrepo.ErrNotFound) {
+			m.log.Warn("failed to load email template override; sending built-in email", "orgId", orgID, "template", templateKey, "error", err)
+		}
+		return builtIn()
+	}
+
+	subject, subjectErr := renderTemplateText(override.Subject, vars)
+	body, bodyErr := renderSystemEmailBody(override.BodyHTML, vars)
+	if err := errors.Join(subjectErr, bodyErr); err != nil || strings.TrimSpace(subject) == "" || body == "" {
+		m.log.Warn("failed to render email template override; sending built-in email", "orgId", orgID, "template", templateKey, "version", override.Version, "error", err)
+		return builtIn()
+	}
+	return m.resolveSender(ctx, orgID).SendCustomEmail(ctx, toEmail, strings.TrimSpace(subject), body)
+}
+
+// renderSystemEmailBody renders an override body and strips it to the HTML
+// subset allowed in branded email headers and footers.
+func renderSystemEmailBody(bodyHTML string, vars map[string]any) (string, error) {
+	rendered, err := renderTemplateText(strings.TrimSpace(bodyHTML), vars)
+	if err != nil {
+		return "", err
+	}
+	return sanitize.SanitizeHTML(rendered), nil
+}
+
+// userOrganizationID returns the organization of a user, or uuid.Nil when
+// the user has none yet, such as right after signing up.
+func (m *Module) userOrganizationID(ctx context.Context, userID uuid.UUID) uuid.UUID {
+	if m.tenancyReader == nil {
+		return uuid.Nil
+	}
+	orgID, err := m.tenancyReader.GetUserOrganizationID(ctx, userID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return orgID
+}
+
+func systemEmailExampleVariables(tpl systemEmailTemplate) map[string]any {
+	vars := map[string]any{}
+	for _, spec := range tpl.Variables {
+		setTemplateVariable(vars, spec.Path, spec.Example)
+	}
+	return vars
+}
+
+func setTemplateVariable(vars map[string]any, path string, value any) {
+	segments := strings.Split(path, ".")
+	current := vars
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := current[segment].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[segment] = next
+		}
+		current = next
+	}
+	current[segments[len(segments)-1]] = value
+}
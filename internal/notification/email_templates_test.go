@@ -0,0 +1,118 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	identityrepo "portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+type testEmailTemplateReader struct {
+	version identityrepo.EmailTemplateVersion
+	err     error
+}
+
+func (r testEmailTemplateReader) GetActiveEmailTemplate(_ context.Context, _ uuid.UUID, _ string) (identityrepo.EmailTemplateVersion, error) {
+	return r.version, r.err
+}
+
+func TestRenderEmailTemplateReportsUnresolvedVariables(t *testing.T) {
+	m := New(nil, &testSender{}, testNotificationConfig{}, logger.New("development"))
+
+	rendered, err := m.RenderEmailTemplate(context.Background(), uuid.New(), emailTemplateVerification,
+		"Bevestig {{user.email}}", `<p><a href="{{links.verify}}">Bevestigen</a> {{links.reset}}</p>`)
+	if err != nil {
+		t.Fatalf("render email template: %v", err)
+	}
+	if rendered.Subject != "Bevestig jan@example.com" {
+		t.Fatalf("unexpected subject %q", rendered.Subject)
+	}
+	if !strings.Contains(rendered.BodyHTML, `href="https://app.example.com/verify-email?token=abc123"`) {
+		t.Fatalf("expected verify link in body, got %q", rendered.BodyHTML)
+	}
+	if len(rendered.UnresolvedVariables) != 1 || rendered.UnresolvedVariables[0] != "links.reset" {
+		t.Fatalf("expected links.reset to be unresolved, got %v", rendered.UnresolvedVariables)
+	}
+	if rendered.Valid() {
+		t.Fatal("expected template with unresolved variables to be invalid")
+	}
+}
+
+func TestRenderEmailTemplateStripsUnsafeHTML(t *testing.T) {
+	m := New(nil, &testSender{}, testNotificationConfig{}, logger.New("development"))
+
+	rendered, err := m.RenderEmailTemplate(context.Background(), uuid.New(), emailTemplatePasswordReset,
+		"Nieuw wachtwoord", `<p>Klik <a href="{{links.reset}}">hier</a></p><script>alert(1)</script>`)
+	if err != nil {
+		t.Fatalf("render email template: %v", err)
+	}
+	if strings.Contains(rendered.BodyHTML, "<script") {
+		t.Fatalf("expected script to be stripped, got %q", rendered.BodyHTML)
+	}
+	if !rendered.Valid() {
+		t.Fatalf("expected valid template, got errors %v and unresolved %v", rendered.TemplateErrors, rendered.UnresolvedVariables)
+	}
+}
+
+func TestSendSystemEmailUsesOrganizationOverride(t *testing.T) {
+	sender := &testSender{}
+	m := New(nil, sender, testNotificationConfig{}, logger.New("development"))
+	m.SetEmailTemplateReader(testEmailTemplateReader{version: identityrepo.EmailTemplateVersion{
+		Version:  2,
+		Subject:  "Uitnodiging van {{org.name}}",
+		BodyHTML: `<p><a href="{{links.invite}}">Accepteren</a></p>`,
+	}})
+
+	builtInCalls := 0
+	vars := map[string]any{
+		"user":  map[string]any{"email": testLeadEmail},
+		"org":   map[string]any{"name": testOrgName},
+		"links": map[string]any{"invite": "https://app.example.com/sign-up?token=xyz"},
+	}
+	err := m.sendSystemEmail(context.Background(), uuid.New(), emailTemplateOrganizationInvite, testLeadEmail, vars, func() error {
+		builtInCalls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("send system email: %v", err)
+	}
+	if builtInCalls != 0 {
+		t.Fatalf("expected override instead of built-in email, got %d built-in calls", builtInCalls)
+	}
+	if sender.customEmailCalls != 1 {
+		t.Fatalf("expected 1 custom email call, got %d", sender.customEmailCalls)
+	}
+	if !strings.Contains(sender.lastCustomBody, "https://app.example.com/sign-up?token=xyz") {
+		t.Fatalf("expected invite link in body, got %q", sender.lastCustomBody)
+	}
+}
+
+func TestSendSystemEmailFallsBackToBuiltIn(t *testing.T) {
+	cases := map[string]testEmailTemplateReader{
+		"no override":     {err: identityrepo.ErrNotFound},
+		"broken template": {version: identityrepo.EmailTemplateVersion{Subject: "{{if}}", BodyHTML: "<p>x</p>"}},
+	}
+	for name, reader := range cases {
+		t.Run(name, func(t *testing.T) {
+			sender := &testSender{}
+			m := New(nil, sender, testNotificationConfig{}, logger.New("development"))
+			m.SetEmailTemplateReader(reader)
+
+			builtInCalls := 0
+			err := m.sendSystemEmail(context.Background(), uuid.New(), emailTemplateVerification, testLeadEmail, map[string]any{}, func() error {
+				builtInCalls++
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("send system email: %v", err)
+			}
+			if builtInCalls != 1 || sender.customEmailCalls != 0 {
+				t.Fatalf("expected built-in email, got %d built-in and %d custom calls", builtInCalls, sender.customEmailCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	leadrepo "portal_final_backend/internal/leads/repository"
+	notificationoutbox "portal_final_backend/internal/notification/outbox"
+	"portal_final_backend/internal/notification/shortlink"
+	"portal_final_backend/internal/notification/tracking"
+
+	"github.com/google/uuid"
+)
+
+const (
+	emailTrackingPixelPathFmt = "%s/api/v1/public/email-tracking/%s/open.gif"
+	// emailLinkLifetime keeps wrapped links to other sites working long after
+	// the email was sent.
+	emailLinkLifetime = 365 * 24 * time.Hour
+)
+
+var emailHrefPattern = regexp.MustCompile(`(?i)href\s*=\s*"(https?://[^"]+)"`)
+
+// instrumentTrackedEmail adds the tracking pixel to an email to a lead and
+// wraps its links in short links, when the organization opted in to email
+// tracking. It reports whether the email is tracked.
+func (m *Module) instrumentTrackedEmail(ctx context.Context, orgID uuid.UUID, rec notificationoutbox.Record, payload emailSendOutboxPayload, bodyHTML string) (string, bool) {
+	if m.emailTracking == nil || m.shortLinks == nil || payload.Audience != "lead" || parseOptionalUUID(payload.LeadID) == nil {
+		return bodyHTML, false
+	}
+	if policy, ok := m.messagingPolicy(ctx, orgID); !ok || !policy.EmailTracking {
+		return bodyHTML, false
+	}
+	base := m.shortLinkBaseURL(ctx, orgID)
+	if base == "" {
+		return bodyHTML, false
+	}
+
+	portalBase := m.publicBaseURL(ctx, orgID)
+	now := time.Now().UTC()
+	outboxID := rec.ID
+	bodyHTML = emailHrefPattern.ReplaceAllStringFunc(bodyHTML, func(match string) string {
+		target := html.UnescapeString(emailHrefPattern.FindStringSubmatch(match)[1])
+		lifetime := emailLinkLifetime
+		if path, ok := strings.CutPrefix(target, portalBase); ok && strings.HasPrefix(path, "/") {
+			lifetime = shortLinkLifetime(path)
+		}
+		code, err := m.shortLinks.Shorten(ctx, shortlink.CreateParams{
+			OrganizationID: orgID,
+			LeadID:         parseOptionalUUID(payload.LeadID),
+			ServiceID:      parseOptionalUUID(payload.ServiceID),
+			OutboxID:       &outboxID,
+			TargetURL:      target,
+			ExpiresAt:      now.Add(lifetime),
+		})
+		if err != nil {
+			m.log.Warn("failed to wrap email link; keeping original", "outboxId", rec.ID, "error", err)
+			return match
+		}
+		return `href="` + base + shortLinkPathPrefix + code + `"`
+	})
+	return appendTrackingPixel(bodyHTML, fmt.Sprintf(emailTrackingPixelPathFmt, base, rec.ID)), true
+}
+
+// appendTrackingPixel adds the pixel at the end of the body of an email.
+func appendTrackingPixel(bodyHTML, pixelURL string) string {
+	pixel := `<img src="` + pixelURL + `" width="1" height="1" alt="" style="display:none" />`
+	if i := strings.LastIndex(strings.ToLower(bodyHTML), "</body>"); i >= 0 {
+		return bodyHTML[:i] + pixel + bodyHTML[i:]
+	}
+	return bodyHTML + pixel
+}
+
+// recordTrackedEmailSent starts tracking a delivered email.
+func (m *Module) recordTrackedEmailSent(ctx context.Context, orgID uuid.UUID, rec notificationoutbox.Record, payload emailSendOutboxPayload) {
+	if err := m.emailTracking.RecordSent(ctx, tracking.RecordSentParams{
+		OutboxID:       rec.ID,
+		OrganizationID: orgID,
+		LeadID:         parseOptionalUUID(payload.LeadID),
+		ServiceID:      parseOptionalUUID(payload.ServiceID),
+		Category:       payload.Category,
+	}); err != nil {
+		m.log.Error("failed to record tracked email", "outboxId", rec.ID, "orgId", orgID, "error", err)
+	}
+}
+
+// RecordEmailEngagement writes the first open and every click of a tracked
+// email to the lead timeline.
+func (m *Module) RecordEmailEngagement(ctx context.Context, email tracking.Email, event string) {
+	if m.leadTimeline == nil || email.LeadID == nil {
+		return
+	}
+	var eventType, title, dedupKey string
+	switch {
+	case event == tracking.EventOpened && email.OpenCount == 1:
+		eventType, title = "email_opened", "E-mail geopend"
+		dedupKey = leadrepo.TimelineDedupKey(eventType, email.OutboxID)
+	case event == tracking.EventClicked:
+		eventType, title = "email_clicked", "Link in e-mail aangeklikt"
+	default:
+		return
+	}
+	if err := m.leadTimeline.CreateTimelineEvent(ctx, LeadTimelineEventParams{
+		LeadID:    *email.LeadID,
+		ServiceID: email.ServiceID,
+		OrgID:     email.OrganizationID,
+		ActorType: "Lead",
+		ActorName: "Klant",
+		EventType: eventType,
+		Title:     title,
+		Metadata: map[string]any{
+			"outboxId":   email.OutboxID,
+			"category":   email.Category,
+			"openCount":  email.OpenCount,
+			"clickCount": email.ClickCount,
+		},
+		Visibility: "internal",
+		DedupKey:   dedupKey,
+	}); err != nil {
+		m.log.Error("failed to write email engagement to timeline", "leadId", *email.LeadID, "error", err)
+	}
+}
+
+var _ tracking.EngagementRecorder = (*Module)(nil)
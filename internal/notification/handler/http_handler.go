@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"portal_final_backend/internal/notification/inapp"
 	"portal_final_backend/platform/httpkit"
@@ -25,9 +27,21 @@ func (h *HTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/unread-by-resource", h.CountUnreadByResource)
 	rg.PATCH("/:id/read", h.MarkRead)
 	rg.PATCH("/read-all", h.MarkAllRead)
+	rg.PATCH("/:id/snooze", h.Snooze)
+	rg.POST("/bulk-read", h.MarkReadBulk)
+	rg.POST("/bulk-delete", h.DeleteBulk)
+	rg.DELETE("/read", h.ClearRead)
 	rg.DELETE("/:id", h.Delete)
 }
 
+type bulkRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+type snoozeRequest struct {
+	Until time.Time `json:"until"`
+}
+
 func (h *HTTPHandler) List(c *gin.Context) {
 	identity := httpkit.MustGetIdentity(c)
 	if identity == nil {
@@ -46,7 +60,11 @@ func (h *HTTPHandler) List(c *gin.Context) {
 		limit = 50
 	}
 
-	items, total, err := h.svc.List(c.Request.Context(), identity.UserID(), page, limit)
+	list := h.svc.List
+	if c.Query("snoozed") == "true" {
+		list = h.svc.ListSnoozed
+	}
+	items, total, err := list(c.Request.Context(), identity.UserID(), page, limit)
 	if httpkit.HandleError(c, err) {
 		return
 	}
@@ -148,3 +166,82 @@ func (h *HTTPHandler) Delete(c *gin.Context) {
 
 	httpkit.OK(c, gin.H{"status": "ok"})
 }
+
+func (h *HTTPHandler) Snooze(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, 400, "invalid id", nil)
+		return
+	}
+
+	var req snoozeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := h.svc.Snooze(c.Request.Context(), identity.UserID(), id, req.Until); httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"status": "ok", "snoozedUntil": req.Until})
+}
+
+func (h *HTTPHandler) MarkReadBulk(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+
+	var req bulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	updated, err := h.svc.MarkReadBulk(c.Request.Context(), identity.UserID(), req.IDs)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"updated": updated})
+}
+
+func (h *HTTPHandler) DeleteBulk(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+
+	var req bulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	deleted, err := h.svc.DeleteBulk(c.Request.Context(), identity.UserID(), req.IDs)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"deleted": deleted})
+}
+
+func (h *HTTPHandler) ClearRead(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+
+	deleted, err := h.svc.ClearRead(c.Request.Context(), identity.UserID())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"deleted": deleted})
+}
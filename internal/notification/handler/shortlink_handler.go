@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"portal_final_backend/internal/notification/shortlink"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// previewUserAgents identify the crawlers that fetch link previews for chat
+// messages; their requests are redirected but not counted as clicks.
+var previewUserAgents = []string{"whatsapp", "facebookexternalhit", "telegrambot", "slackbot"}
+
+// ShortLinkHandler redirects the short links sent in messages.
+type ShortLinkHandler struct {
+	svc *shortlink.Service
+}
+
+func NewShortLinkHandler(svc *shortlink.Service) *ShortLinkHandler {
+	return &ShortLinkHandler{svc: svc}
+}
+
+func (h *ShortLinkHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/:code", h.Redirect)
+	rg.HEAD("/:code", h.Redirect)
+}
+
+func (h *ShortLinkHandler) Redirect(c *gin.Context) {
+	link, err := h.svc.Open(c.Request.Context(), c.Param("code"), isLinkPreview(c.Request))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, link.TargetURL)
+}
+
+func isLinkPreview(r *http.Request) bool {
+	if r.Method == http.MethodHead {
+		return true
+	}
+	userAgent := strings.ToLower(r.UserAgent())
+	for _, crawler := range previewUserAgents {
+		if strings.Contains(userAgent, crawler) {
+			return true
+		}
+	}
+	return false
+}
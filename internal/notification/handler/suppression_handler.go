@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"portal_final_backend/internal/notification/suppression"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuppressionHandler manages the addresses an organization must never contact.
+type SuppressionHandler struct {
+	svc *suppression.Service
+}
+
+func NewSuppressionHandler(svc *suppression.Service) *SuppressionHandler {
+	return &SuppressionHandler{svc: svc}
+}
+
+func (h *SuppressionHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.List)
+	rg.POST("", h.Add)
+	rg.DELETE("/:id", h.Remove)
+}
+
+type addSuppressionRequest struct {
+	Kind   string `json:"kind"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
+func (h *SuppressionHandler) List(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "50"))
+	result, err := h.svc.List(c.Request.Context(), tenantID, suppression.ListParams{
+		Kind:     c.Query("kind"),
+		Search:   c.Query("search"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, result)
+}
+
+func (h *SuppressionHandler) Add(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req addSuppressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "invalid request", nil)
+		return
+	}
+
+	entry, err := h.svc.Add(c.Request.Context(), tenantID, req.Kind, req.Value, req.Reason, req.Note, identity.UserID())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, entry)
+}
+
+func (h *SuppressionHandler) Remove(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Remove(c.Request.Context(), tenantID, id); httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, gin.H{"status": "ok"})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/notification/tracking"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// transparentGIF is a 1x1 transparent GIF served as tracking pixel.
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// EmailTrackingHandler serves the tracking pixel of emails and reports their
+// engagement.
+type EmailTrackingHandler struct {
+	svc *tracking.Service
+}
+
+func NewEmailTrackingHandler(svc *tracking.Service) *EmailTrackingHandler {
+	return &EmailTrackingHandler{svc: svc}
+}
+
+func (h *EmailTrackingHandler) RegisterPublicRoutes(rg *gin.RouterGroup) {
+	rg.GET("/:outboxID/open.gif", h.Open)
+}
+
+func (h *EmailTrackingHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.Report)
+}
+
+// Open counts an open of the email and always answers with the pixel, so a
+// failure never shows as a broken image.
+func (h *EmailTrackingHandler) Open(c *gin.Context) {
+	if outboxID, err := uuid.Parse(c.Param("outboxID")); err == nil {
+		_ = h.svc.RecordOpen(c.Request.Context(), outboxID)
+	}
+	c.Header("Cache-Control", "no-store, max-age=0")
+	c.Data(http.StatusOK, "image/gif", transparentGIF)
+}
+
+func (h *EmailTrackingHandler) Report(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.Report(c.Request.Context(), tenantID, c.Query("from"), c.Query("to"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, report)
+}
@@ -3,9 +3,12 @@ package notification
 import (
 	"context"
 	"fmt"
+	"portal_final_backend/internal/email"
 	"portal_final_backend/internal/events"
 	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/ical"
 	"portal_final_backend/platform/timekit"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,11 +20,15 @@ func (m *Module) handleAppointmentCreated(ctx context.Context, e events.Appointm
 		OrgID:         e.OrganizationID,
 		LeadID:        e.LeadID,
 		ServiceID:     e.LeadServiceID,
+		AppointmentID: e.AppointmentID,
 		Type:          e.Type,
+		Title:         e.Title,
 		ConsumerPhone: e.ConsumerPhone,
 		ConsumerEmail: e.ConsumerEmail,
 		ConsumerName:  e.ConsumerName,
 		StartTime:     e.StartTime,
+		EndTime:       e.EndTime,
+		AllDay:        e.AllDay,
 		Location:      e.Location,
 		Trigger:       "appointment_created",
 		Category:      "appointment_created",
@@ -35,6 +42,34 @@ func (m *Module) handleAppointmentCreated(ctx context.Context, e events.Appointm
 	return m.handleAppointmentEmail(ctx, params)
 }
 
+func (m *Module) handleAppointmentUpdated(ctx context.Context, e events.AppointmentUpdated) error {
+	params := appointmentWhatsAppParams{
+		OrgID:         e.OrganizationID,
+		LeadID:        e.LeadID,
+		ServiceID:     e.LeadServiceID,
+		AppointmentID: e.AppointmentID,
+		Type:          e.Type,
+		Title:         e.Title,
+		ConsumerPhone: e.ConsumerPhone,
+		ConsumerEmail: e.ConsumerEmail,
+		ConsumerName:  e.ConsumerName,
+		StartTime:     e.StartTime,
+		EndTime:       e.EndTime,
+		AllDay:        e.AllDay,
+		Sequence:      e.Sequence,
+		Location:      e.Location,
+		Trigger:       "appointment_updated",
+		Category:      "appointment_updated",
+		SummaryFmt:    "WhatsApp afspraakwijziging verstuurd naar %s",
+	}
+
+	if err := m.handleAppointmentWhatsApp(ctx, params); err != nil {
+		return err
+	}
+
+	return m.handleAppointmentEmail(ctx, params)
+}
+
 func (m *Module) handleAppointmentReminderDue(ctx context.Context, e events.AppointmentReminderDue) error {
 	params := appointmentWhatsAppParams{
 		OrgID:         e.OrganizationID,
@@ -62,11 +97,16 @@ type appointmentWhatsAppParams struct {
 	OrgID         uuid.UUID
 	LeadID        *uuid.UUID
 	ServiceID     *uuid.UUID
+	AppointmentID uuid.UUID
 	Type          string
+	Title         string
 	ConsumerPhone string
 	ConsumerEmail string
 	ConsumerName  string
 	StartTime     time.Time
+	EndTime       time.Time
+	AllDay        bool
+	Sequence      int
 	Location      string
 	Trigger       string
 	Category      string
@@ -93,7 +133,7 @@ func (m *Module) handleAppointmentWhatsApp(ctx context.Context, p appointmentWha
 	timeStr := localStart.Format("15:04")
 	details := m.resolveLeadDetails(ctx, *p.LeadID, p.OrgID)
 	orgName := defaultName(strings.TrimSpace(m.resolveOrganizationName(ctx, p.OrgID)), defaultOrgNameFallback)
-	templateVars := buildAppointmentTemplateVars(name, p.ConsumerPhone, p.ConsumerEmail, dateStr, timeStr, strings.TrimSpace(p.Location), orgName)
+	templateVars := buildAppointmentTemplateVars(name, p.ConsumerPhone, p.ConsumerEmail, dateStr, timeStr, strings.TrimSpace(p.Location), orgName, localStart)
 	enrichLeadVars(templateVars, details)
 	bodyText, err := renderWorkflowTemplateTextWithError(rule, templateVars)
 	if err != nil {
@@ -124,8 +164,9 @@ func (m *Module) handleAppointmentEmail(ctx context.Context, p appointmentWhatsA
 	timeStr := localStart.Format("15:04")
 	details := m.resolveLeadDetails(ctx, *p.LeadID, p.OrgID)
 	orgName := defaultName(strings.TrimSpace(m.resolveOrganizationName(ctx, p.OrgID)), defaultOrgNameFallback)
-	templateVars := buildAppointmentTemplateVars(name, p.ConsumerPhone, p.ConsumerEmail, dateStr, timeStr, strings.TrimSpace(p.Location), orgName)
+	templateVars := buildAppointmentTemplateVars(name, p.ConsumerPhone, p.ConsumerEmail, dateStr, timeStr, strings.TrimSpace(p.Location), orgName, localStart)
 	enrichLeadVars(templateVars, details)
+	addAppointmentCalendarVars(templateVars, p)
 
 	_ = m.dispatchQuoteEmailWorkflow(ctx, dispatchQuoteEmailWorkflowParams{
 		Rule:         rule,
@@ -142,7 +183,10 @@ func (m *Module) handleAppointmentEmail(ctx context.Context, p appointmentWhatsA
 	return nil
 }
 
-func buildAppointmentTemplateVars(consumerName, consumerPhone, consumerEmail, date, timeText, location, orgName string) map[string]any {
+// buildAppointmentTemplateVars builds the appointment template variables.
+// startsAt is the local start time; renderStepTemplate formats date and time
+// from it in the recipient's language.
+func buildAppointmentTemplateVars(consumerName, consumerPhone, consumerEmail, date, timeText, location, orgName string, startsAt time.Time) map[string]any {
 	return map[string]any{
 		"lead": map[string]any{
 			"name":  consumerName,
@@ -153,6 +197,7 @@ func buildAppointmentTemplateVars(consumerName, consumerPhone, consumerEmail, da
 			"date":     date,
 			"time":     timeText,
 			"location": location,
+			"startsAt": startsAt,
 		},
 		"org": map[string]any{
 			"name": orgName,
@@ -171,11 +216,14 @@ func (m *Module) enqueueAppointmentOutbox(ctx context.Context, p appointmentWhat
 	}
 	messageText := message
 	steps := []repository.WorkflowStep{{
-		Enabled:      true,
-		Channel:      "whatsapp",
-		Audience:     "lead",
-		DelayMinutes: delayMinutes,
-		TemplateBody: &messageText,
+		ID:            rule.stepID(),
+		WorkflowID:    rule.workflowID(),
+		Enabled:       true,
+		Channel:       "whatsapp",
+		Audience:      "lead",
+		DelayMinutes:  delayMinutes,
+		TemplateBody:  &messageText,
+		SendCondition: rule.sendCondition(),
 		RecipientConfig: map[string]any{
 			"includeLeadContact": true,
 		},
@@ -190,6 +238,117 @@ func (m *Module) enqueueAppointmentOutbox(ctx context.Context, p appointmentWhat
 		DefaultSummary: fmt.Sprintf(p.SummaryFmt, name),
 		DefaultActor:   "System",
 		DefaultOrigin:  "Portal",
+		Language:       rule.language(),
+		VariantKey:     rule.variantKey(),
 	})
 	return err == nil
 }
+
+// appointmentCalendarPayload carries the appointment details needed to render the .ics
+// invite when the outbox email is sent.
+type appointmentCalendarPayload struct {
+	AppointmentID string    `json:"appointmentId"`
+	Title         string    `json:"title"`
+	StartTime     time.Time `json:"startTime"`
+	EndTime       time.Time `json:"endTime"`
+	AllDay        bool      `json:"allDay,omitempty"`
+	Sequence      int       `json:"sequence"`
+	Location      string    `json:"location,omitempty"`
+	Organizer     string    `json:"organizer,omitempty"`
+}
+
+const appointmentCalendarFileName = "afspraak.ics"
+
+// addAppointmentCalendarVars exposes the fields used for calendar invites next to the
+// display fields of the appointment template variables.
+func addAppointmentCalendarVars(vars map[string]any, p appointmentWhatsAppParams) {
+	appointment, ok := vars["appointment"].(map[string]any)
+	if !ok || p.AppointmentID == uuid.Nil {
+		return
+	}
+	appointment["id"] = p.AppointmentID.String()
+	appointment["title"] = p.Title
+	appointment["startTime"] = p.StartTime.UTC().Format(time.RFC3339)
+	appointment["endTime"] = p.EndTime.UTC().Format(time.RFC3339)
+	appointment["allDay"] = p.AllDay
+	appointment["sequence"] = p.Sequence
+}
+
+func buildAppointmentCalendarAttachmentSpecs(vars map[string]any) []emailSendAttachmentSpec {
+	appointment, ok := vars["appointment"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	id, _ := appointment["id"].(string)
+	startText, _ := appointment["startTime"].(string)
+	endText, _ := appointment["endTime"].(string)
+	start, startErr := time.Parse(time.RFC3339, startText)
+	end, endErr := time.Parse(time.RFC3339, endText)
+	if strings.TrimSpace(id) == "" || startErr != nil || endErr != nil {
+		return nil
+	}
+
+	payload := &appointmentCalendarPayload{
+		AppointmentID: strings.TrimSpace(id),
+		StartTime:     start,
+		EndTime:       end,
+		Sequence:      intFromVar(appointment["sequence"]),
+	}
+	payload.Title, _ = appointment["title"].(string)
+	payload.Location, _ = appointment["location"].(string)
+	payload.AllDay, _ = appointment["allDay"].(bool)
+	if org, ok := vars["org"].(map[string]any); ok {
+		payload.Organizer, _ = org["name"].(string)
+	}
+
+	return []emailSendAttachmentSpec{{
+		Kind:     "appointment_ics",
+		FileName: appointmentCalendarFileName,
+		MIMEType: ical.MIMEType,
+		Calendar: payload,
+	}}
+}
+
+func resolveAppointmentCalendarAttachment(spec emailSendAttachmentSpec) (email.Attachment, error) {
+	if spec.Calendar == nil || strings.TrimSpace(spec.Calendar.AppointmentID) == "" {
+		return email.Attachment{}, fmt.Errorf("%w: missing calendar payload", errInvalidOutboxPayload)
+	}
+	p := spec.Calendar
+	description := ""
+	if p.Organizer != "" {
+		description = "Afspraak met " + p.Organizer
+	}
+	content := ical.Calendar{Method: ical.MethodRequest, Events: []ical.Event{{
+		UID:         ical.UID(p.AppointmentID),
+		Sequence:    p.Sequence,
+		Start:       p.StartTime,
+		End:         p.EndTime,
+		AllDay:      p.AllDay,
+		Summary:     p.Title,
+		Description: description,
+		Location:    p.Location,
+	}}}.Encode()
+
+	fileName := strings.TrimSpace(spec.FileName)
+	if fileName == "" {
+		fileName = appointmentCalendarFileName
+	}
+	return email.Attachment{Content: content, FileName: fileName, MIMEType: ical.MIMEType}, nil
+}
+
+// intFromVar reads an integer template variable that may have been decoded from JSON.
+func intFromVar(value any) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	default:
+		return 0
+	}
+}
@@ -2,12 +2,24 @@ package notification
 
 import (
 	"context"
+	"fmt"
+	"html"
+	"time"
+
 	"portal_final_backend/internal/events"
+	"portal_final_backend/platform/timekit"
 )
 
 func (m *Module) handleUserSignedUp(ctx context.Context, e events.UserSignedUp) error {
 	verifyURL := m.buildURL("/verify-email", e.VerifyToken)
-	if err := m.sender.SendVerificationEmail(ctx, e.Email, verifyURL); err != nil {
+	vars := map[string]any{
+		"user":  map[string]any{"email": e.Email},
+		"links": map[string]any{"verify": verifyURL},
+	}
+	err := m.sendSystemEmail(ctx, m.userOrganizationID(ctx, e.UserID), emailTemplateVerification, e.Email, vars, func() error {
+		return m.sender.SendVerificationEmail(ctx, e.Email, verifyURL)
+	})
+	if err != nil {
 		m.log.Error("failed to send verification email",
 			"userId", e.UserID,
 			"email", e.Email,
@@ -21,7 +33,14 @@ func (m *Module) handleUserSignedUp(ctx context.Context, e events.UserSignedUp)
 
 func (m *Module) handleEmailVerificationRequested(ctx context.Context, e events.EmailVerificationRequested) error {
 	verifyURL := m.buildURL("/verify-email", e.VerifyToken)
-	if err := m.sender.SendVerificationEmail(ctx, e.Email, verifyURL); err != nil {
+	vars := map[string]any{
+		"user":  map[string]any{"email": e.Email},
+		"links": map[string]any{"verify": verifyURL},
+	}
+	err := m.sendSystemEmail(ctx, m.userOrganizationID(ctx, e.UserID), emailTemplateVerification, e.Email, vars, func() error {
+		return m.sender.SendVerificationEmail(ctx, e.Email, verifyURL)
+	})
+	if err != nil {
 		m.log.Error("failed to send verification email",
 			"userId", e.UserID,
 			"email", e.Email,
@@ -35,7 +54,14 @@ func (m *Module) handleEmailVerificationRequested(ctx context.Context, e events.
 
 func (m *Module) handlePasswordResetRequested(ctx context.Context, e events.PasswordResetRequested) error {
 	resetURL := m.buildURL("/reset-password", e.ResetToken)
-	if err := m.sender.SendPasswordResetEmail(ctx, e.Email, resetURL); err != nil {
+	vars := map[string]any{
+		"user":  map[string]any{"email": e.Email},
+		"links": map[string]any{"reset": resetURL},
+	}
+	err := m.sendSystemEmail(ctx, m.userOrganizationID(ctx, e.UserID), emailTemplatePasswordReset, e.Email, vars, func() error {
+		return m.sender.SendPasswordResetEmail(ctx, e.Email, resetURL)
+	})
+	if err != nil {
 		m.log.Error("failed to send password reset email",
 			"userId", e.UserID,
 			"email", e.Email,
@@ -46,3 +72,58 @@ func (m *Module) handlePasswordResetRequested(ctx context.Context, e events.Pass
 	m.log.Info("password reset email sent", "userId", e.UserID, "email", e.Email)
 	return nil
 }
+
+func (m *Module) handleAccountLockedOut(ctx context.Context, e events.AccountLockedOut) error {
+	subject, body := buildAccountLockedOutEmail(e.LockedUntil, e.IPAddress)
+	if err := m.sender.SendCustomEmail(ctx, e.Email, subject, body); err != nil {
+		m.log.Error("failed to send account lockout email",
+			"userId", e.UserID,
+			"email", e.Email,
+			"error", err,
+		)
+		return err
+	}
+	m.log.Info("account lockout email sent", "userId", e.UserID, "email", e.Email)
+	return nil
+}
+
+func (m *Module) handleNewSignInDetected(ctx context.Context, e events.NewSignInDetected) error {
+	subject, body := buildNewSignInEmail(e)
+	if err := m.sender.SendCustomEmail(ctx, e.Email, subject, body); err != nil {
+		m.log.Error("failed to send new sign-in email",
+			"userId", e.UserID,
+			"email", e.Email,
+			"error", err,
+		)
+		return err
+	}
+	m.log.Info("new sign-in email sent", "userId", e.UserID, "email", e.Email)
+	return nil
+}
+
+func buildAccountLockedOutEmail(lockedUntil time.Time, ipAddress string) (string, string) {
+	until := lockedUntil.In(timekit.ResolveLocation("Europe/Amsterdam")).Format("02-01-2006 15:04")
+	body := fmt.Sprintf(
+		`<p>Na meerdere mislukte inlogpogingen is uw account tijdelijk geblokkeerd tot %s.</p><p>De laatste poging kwam van IP-adres %s.</p><p>Was u dit niet? Wijzig dan uw wachtwoord zodra de blokkade is opgeheven.</p>`,
+		html.EscapeString(until), html.EscapeString(ipAddress),
+	)
+	return "Uw account is tijdelijk geblokkeerd", body
+}
+
+func buildNewSignInEmail(e events.NewSignInDetected) (string, string) {
+	var what string
+	switch {
+	case e.NewDevice && e.NewLocation:
+		what = "een nieuw apparaat en een nieuwe locatie"
+	case e.NewDevice:
+		what = "een nieuw apparaat"
+	default:
+		what = "een nieuwe locatie"
+	}
+	at := e.SignedInAt.In(timekit.ResolveLocation("Europe/Amsterdam")).Format("02-01-2006 15:04")
+	body := fmt.Sprintf(
+		`<p>Er is op %s ingelogd op uw account vanaf %s.</p><p>IP-adres: %s<br>Apparaat: %s</p><p>Was u dit niet? Wijzig dan direct uw wachtwoord.</p>`,
+		html.EscapeString(at), what, html.EscapeString(e.IPAddress), html.EscapeString(e.UserAgent),
+	)
+	return "Nieuwe aanmelding op uw account", body
+}
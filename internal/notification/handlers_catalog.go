@@ -0,0 +1,93 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/notification/inapp"
+)
+
+// maxListedPriceJumps caps the products named in a price jump or low stock notification.
+const maxListedPriceJumps = 3
+
+func (m *Module) handleCatalogPriceJumpsDetected(ctx context.Context, e events.CatalogPriceJumpsDetected) error {
+	if len(e.Jumps) == 0 {
+		return nil
+	}
+
+	listed := make([]string, 0, maxListedPriceJumps)
+	for _, jump := range e.Jumps {
+		if len(listed) == maxListedPriceJumps {
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s (%s → %s, %s)", truncate(jump.Title, 60),
+			formatCurrencyEURCents(jump.OldCostCents), formatCurrencyEURCents(jump.NewCostCents), formatBpsChange(jump.ChangeBps)))
+	}
+	content := fmt.Sprintf("%d kostprijzen van %s zijn meer dan %s veranderd: %s",
+		len(e.Jumps), e.SupplierName, formatBps(e.ThresholdBps), strings.Join(listed, ", "))
+	if len(e.Jumps) > maxListedPriceJumps {
+		content += fmt.Sprintf(" en %d meer", len(e.Jumps)-maxListedPriceJumps)
+	}
+
+	m.notifyOrgMembersInAppByRoles(ctx, e.OrganizationID, adminOnlyRoles, inapp.SendParams{
+		Title:        "Grote prijswijziging bij leverancier",
+		Content:      content,
+		ResourceID:   &e.FeedID,
+		ResourceType: "catalog_price_feed",
+		Category:     "warning",
+	})
+	m.log.Info("catalog price jumps event processed", "feedId", e.FeedID, "jumps", len(e.Jumps))
+	return nil
+}
+
+func (m *Module) handleCatalogLowStockDetected(ctx context.Context, e events.CatalogLowStockDetected) error {
+	if len(e.Items) == 0 {
+		return nil
+	}
+
+	listed := make([]string, 0, maxListedPriceJumps)
+	for _, item := range e.Items {
+		if len(listed) == maxListedPriceJumps {
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s (%s op voorraad)", truncate(item.Title, 60), formatStockQuantity(item.Quantity)))
+	}
+	content := fmt.Sprintf("%d producten hebben na een geaccepteerde offerte een lage voorraad: %s",
+		len(e.Items), strings.Join(listed, ", "))
+	if len(e.Items) > maxListedPriceJumps {
+		content += fmt.Sprintf(" en %d meer", len(e.Items)-maxListedPriceJumps)
+	}
+
+	resourceID := e.Items[0].ProductID
+	m.notifyOrgMembersInAppByRoles(ctx, e.OrganizationID, adminOnlyRoles, inapp.SendParams{
+		Title:        "Lage voorraad",
+		Content:      content,
+		ResourceID:   &resourceID,
+		ResourceType: "catalog_product",
+		Category:     "warning",
+	})
+	m.log.Info("catalog low stock event processed", "quoteId", e.QuoteID, "products", len(e.Items))
+	return nil
+}
+
+// formatStockQuantity formats a stock quantity with a decimal comma and without
+// trailing zeros, e.g. "2,5".
+func formatStockQuantity(quantity float64) string {
+	return strings.Replace(strconv.FormatFloat(quantity, 'f', -1, 64), ".", ",", 1)
+}
+
+// formatBps formats basis points as a percentage, e.g. "12,5%".
+func formatBps(bps int) string {
+	return strings.Replace(fmt.Sprintf("%d.%d%%", bps/100, (bps%100)/10), ".", ",", 1)
+}
+
+// formatBpsChange formats basis points as a signed percentage, e.g. "-4,2%".
+func formatBpsChange(bps int) string {
+	if bps < 0 {
+		return "-" + formatBps(-bps)
+	}
+	return "+" + formatBps(bps)
+}
@@ -36,6 +36,7 @@ type LeadTimelineEventParams struct {
 	Summary    *string
 	Metadata   map[string]any
 	Visibility string
+	DedupKey   string
 }
 
 // LeadTimelineWriter persists lead timeline events.
@@ -69,10 +70,13 @@ type leadDetails struct {
 	City        string
 	ServiceType string
 	PublicToken string
+	// CustomFields holds the organization-defined field values by key.
+	CustomFields map[string]any
 }
 
-// enrichLeadVars adds first name, last name, address, city, zip code and service type
-// into an existing "lead" template variable map. Creates the map if nil.
+// enrichLeadVars adds first name, last name, address, city, zip code, service type and
+// custom fields (as {{lead.customFields.<key>}}) into an existing "lead" template
+// variable map. Creates the map if nil.
 func enrichLeadVars(vars map[string]any, d *leadDetails) {
 	if d == nil {
 		return
@@ -90,6 +94,9 @@ func enrichLeadVars(vars map[string]any, d *leadDetails) {
 	leadMap["zipCode"] = strings.TrimSpace(d.ZipCode)
 	leadMap["city"] = strings.TrimSpace(d.City)
 	leadMap["serviceType"] = strings.TrimSpace(d.ServiceType)
+	if d.CustomFields != nil {
+		leadMap["customFields"] = d.CustomFields
+	}
 }
 
 func buildLeadAddressFromWorkflowVars(vars map[string]any) string {
@@ -146,7 +153,7 @@ func (m *Module) handleLeadCreated(ctx context.Context, e events.LeadCreated) er
 			"name": defaultName(orgName, defaultOrgNameFallback),
 		},
 		"links": map[string]any{
-			"track": m.buildLeadTrackLink(e.PublicToken),
+			"track": m.buildLeadTrackLink(ctx, e.TenantID, e.PublicToken),
 		},
 	}
 	enrichLeadVars(templateVars, details)
@@ -239,11 +246,11 @@ func (m *Module) handleWhatsAppOutboxLeadLookupError(ctx context.Context, rec no
 	return err
 }
 
-func (m *Module) buildLeadTrackLink(publicToken string) string {
+func (m *Module) buildLeadTrackLink(ctx context.Context, orgID uuid.UUID, publicToken string) string {
 	if strings.TrimSpace(publicToken) == "" {
 		return ""
 	}
-	base := strings.TrimRight(m.cfg.GetPublicBaseURL(), "/")
+	base := m.publicBaseURL(ctx, orgID)
 	if base == "" {
 		return ""
 	}
@@ -325,6 +332,29 @@ func (m *Module) handleLeadAssigned(ctx context.Context, e events.LeadAssigned)
 	return nil
 }
 
+func (m *Module) handleLeadsHandedOver(ctx context.Context, e events.LeadsHandedOver) error {
+	if m.inAppService == nil || len(e.LeadIDs) == 0 {
+		return nil
+	}
+
+	params := inapp.SendParams{
+		OrgID:        e.TenantID,
+		UserID:       e.ToAgentID,
+		Title:        "Lead aan je overgedragen",
+		Content:      "Overdrachtsnotitie: " + e.Note,
+		ResourceType: "lead",
+		Category:     "info",
+	}
+	if len(e.LeadIDs) == 1 {
+		params.ResourceID = &e.LeadIDs[0]
+	} else {
+		params.Title = fmt.Sprintf("%d leads aan je overgedragen", len(e.LeadIDs))
+	}
+	_ = m.inAppService.Send(ctx, params)
+
+	return nil
+}
+
 func (m *Module) isLeadWhatsAppOptedIn(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) bool {
 	if m.leadWhatsAppReader == nil {
 		return true
@@ -336,6 +366,7 @@ func (m *Module) isLeadWhatsAppOptedIn(ctx context.Context, leadID uuid.UUID, or
 	}
 	if !optedIn {
 		m.log.Info("whatsapp disabled for lead, skipping message", "leadId", leadID, "orgId", organizationID)
+		return false
 	}
-	return optedIn
+	return m.leadAllowsTransactionalWhatsApp(ctx, leadID, organizationID)
 }
@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/events"
+	notificationoutbox "portal_final_backend/internal/notification/outbox"
+	"portal_final_backend/platform/timekit"
+)
+
+const (
+	leadPortalLinkSubject = "Uw persoonlijke link naar uw aanvraag"
+	leadPortalLinkSummary = "Portaallink verstuurd"
+)
+
+// handleLeadPortalLinkRequested sends a lead portal magic link through the
+// outbox, so opt-outs and the messaging policy apply as for other messages.
+func (m *Module) handleLeadPortalLinkRequested(ctx context.Context, e events.LeadPortalLinkRequested) error {
+	if m.notificationOutbox == nil {
+		m.log.Warn("notification outbox not configured; portal link not sent", "leadId", e.LeadID, "orgId", e.TenantID)
+		return nil
+	}
+	link := m.buildLeadTrackLink(ctx, e.TenantID, e.Token)
+	if link == "" {
+		m.log.Warn("public base url not configured; portal link not sent", "leadId", e.LeadID, "orgId", e.TenantID)
+		return nil
+	}
+	details := m.resolveLeadDetails(ctx, e.LeadID, e.TenantID)
+	if details == nil {
+		m.log.Warn("lead not found; portal link not sent", "leadId", e.LeadID, "orgId", e.TenantID)
+		return nil
+	}
+
+	leadID := e.LeadID
+	validUntil := e.ExpiresAt.In(timekit.ResolveLocation("Europe/Amsterdam")).Format("02-01-2006")
+	params := notificationoutbox.InsertParams{
+		TenantID: e.TenantID,
+		LeadID:   &leadID,
+		RunAt:    time.Now().UTC(),
+	}
+
+	switch e.Channel {
+	case "email":
+		toEmail := strings.TrimSpace(details.Email)
+		if toEmail == "" {
+			m.log.Info("lead has no email address; portal link not sent", "leadId", e.LeadID, "orgId", e.TenantID)
+			return nil
+		}
+		params.Kind = "email"
+		params.Template = "email_send"
+		params.Payload = emailSendOutboxPayload{
+			OrgID:    e.TenantID.String(),
+			ToEmail:  toEmail,
+			Subject:  leadPortalLinkSubject,
+			BodyHTML: buildLeadPortalLinkEmail(details.FirstName, link, validUntil),
+			LeadID:   ptrUUIDString(&leadID),
+			Audience: "lead",
+		}
+	case "whatsapp":
+		phone := strings.TrimSpace(details.Phone)
+		if phone == "" {
+			m.log.Info("lead has no phone number; portal link not sent", "leadId", e.LeadID, "orgId", e.TenantID)
+			return nil
+		}
+		params.Kind = "whatsapp"
+		params.Template = "whatsapp_send"
+		params.Payload = whatsAppSendOutboxPayload{
+			OrgID:       e.TenantID.String(),
+			LeadID:      ptrUUIDString(&leadID),
+			PhoneNumber: phone,
+			Message:     buildLeadPortalLinkWhatsApp(details.FirstName, link, validUntil),
+			Category:    "lead_portal_link",
+			Audience:    "lead",
+			Summary:     leadPortalLinkSummary,
+			ActorType:   "System",
+			ActorName:   "Portaal",
+		}
+	default:
+		m.log.Warn("unsupported portal link channel", "leadId", e.LeadID, "channel", e.Channel)
+		return nil
+	}
+
+	rec, err := m.notificationOutbox.Insert(ctx, params)
+	if err != nil {
+		m.log.Error("failed to enqueue portal link", "leadId", e.LeadID, "orgId", e.TenantID, "channel", e.Channel, "error", err)
+		return err
+	}
+	m.log.Info("portal link enqueued", "outboxId", rec.String(), "leadId", e.LeadID, "orgId", e.TenantID, "channel", e.Channel)
+	return nil
+}
+
+func leadPortalGreeting(firstName string) string {
+	if name := strings.TrimSpace(firstName); name != "" {
+		return "Beste " + name + ","
+	}
+	return "Beste klant,"
+}
+
+func buildLeadPortalLinkWhatsApp(firstName, link, validUntil string) string {
+	return fmt.Sprintf("%s\n\nVia deze persoonlijke link bekijkt u de status van uw aanvraag:\n%s\n\nDe link is geldig tot %s en wordt verlengd zolang u hem gebruikt.", leadPortalGreeting(firstName), link, validUntil)
+}
+
+func buildLeadPortalLinkEmail(firstName, link, validUntil string) string {
+	return fmt.Sprintf(
+		`<p>%s</p><p>Via onderstaande persoonlijke link bekijkt u de status van uw aanvraag.</p><p><a href="%s">Bekijk uw aanvraag</a></p><p>De link is geldig tot %s en wordt verlengd zolang u hem gebruikt. Deel deze link niet met anderen.</p>`,
+		html.EscapeString(leadPortalGreeting(firstName)), html.EscapeString(link), html.EscapeString(validUntil),
+	)
+}
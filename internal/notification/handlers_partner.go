@@ -6,6 +6,8 @@ import (
 	"net/url"
 	"portal_final_backend/internal/events"
 	"portal_final_backend/internal/identity/repository"
+	leadrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/notification/inapp"
 	"portal_final_backend/platform/phone"
 	"strings"
 
@@ -25,6 +27,7 @@ type PartnerOfferTimelineEventParams struct {
 	Summary    *string
 	Metadata   map[string]any
 	Visibility string
+	DedupKey   string
 }
 
 // PartnerOfferTimelineWriter writes partner-offer events into the leads timeline.
@@ -34,7 +37,15 @@ type PartnerOfferTimelineWriter interface {
 
 func (m *Module) handleOrganizationInviteCreated(ctx context.Context, e events.OrganizationInviteCreated) error {
 	inviteURL := m.buildURL("/sign-up", e.InviteToken)
-	if err := m.sender.SendOrganizationInviteEmail(ctx, e.Email, e.OrganizationName, inviteURL); err != nil {
+	vars := map[string]any{
+		"user":  map[string]any{"email": e.Email},
+		"org":   map[string]any{"name": e.OrganizationName},
+		"links": map[string]any{"invite": inviteURL},
+	}
+	err := m.sendSystemEmail(ctx, e.OrganizationID, emailTemplateOrganizationInvite, e.Email, vars, func() error {
+		return m.sender.SendOrganizationInviteEmail(ctx, e.Email, e.OrganizationName, inviteURL)
+	})
+	if err != nil {
 		m.log.Error("failed to send organization invite email",
 			"organizationId", e.OrganizationID,
 			"email", e.Email,
@@ -46,10 +57,46 @@ func (m *Module) handleOrganizationInviteCreated(ctx context.Context, e events.O
 	return nil
 }
 
+func (m *Module) handlePartnerApplicationSubmitted(ctx context.Context, e events.PartnerApplicationSubmitted) error {
+	m.notifyOrgMembersInAppByRoles(ctx, e.OrganizationID, adminOnlyRoles, inapp.SendParams{
+		Title:        "Nieuwe partneraanmelding",
+		Content:      fmt.Sprintf("%s (KvK %s) heeft zich aangemeld als partner en wacht op beoordeling.", e.BusinessName, e.KVKNumber),
+		ResourceID:   &e.ApplicationID,
+		ResourceType: "partner_application",
+		Category:     "info",
+	})
+	m.log.Info("partner application submitted event processed", "applicationId", e.ApplicationID)
+	return nil
+}
+
+func (m *Module) handleKvKCompanyDissolved(ctx context.Context, e events.KvKCompanyDissolved) error {
+	subject := "Partner"
+	if e.EntityType == "lead" {
+		subject = "Lead"
+	}
+	m.notifyOrgMembersInAppByRoles(ctx, e.OrganizationID, adminOnlyRoles, inapp.SendParams{
+		Title:        "Bedrijf uitgeschreven bij KvK",
+		Content:      fmt.Sprintf("%s %s (KvK %s) staat niet langer ingeschreven bij de KvK.", subject, e.LegalName, e.KVKNumber),
+		ResourceID:   &e.EntityID,
+		ResourceType: e.EntityType,
+		Category:     "warning",
+	})
+	m.log.Info("kvk company dissolved event processed", "entityType", e.EntityType, "entityId", e.EntityID, "kvkNumber", e.KVKNumber)
+	return nil
+}
+
 func (m *Module) handlePartnerInviteCreated(ctx context.Context, e events.PartnerInviteCreated) error {
 	inviteURL := m.buildURL("/partner-invite", e.InviteToken)
-	sender := m.resolveSender(ctx, e.OrganizationID)
-	if err := sender.SendPartnerInviteEmail(ctx, e.Email, e.OrganizationName, e.PartnerName, inviteURL); err != nil {
+	vars := map[string]any{
+		"user":    map[string]any{"email": e.Email},
+		"org":     map[string]any{"name": e.OrganizationName},
+		"partner": map[string]any{"name": e.PartnerName},
+		"links":   map[string]any{"invite": inviteURL},
+	}
+	err := m.sendSystemEmail(ctx, e.OrganizationID, emailTemplatePartnerInvite, e.Email, vars, func() error {
+		return m.resolveSender(ctx, e.OrganizationID).SendPartnerInviteEmail(ctx, e.Email, e.OrganizationName, e.PartnerName, inviteURL)
+	})
+	if err != nil {
 		m.log.Error("failed to send partner invite email",
 			"organizationId", e.OrganizationID,
 			"partnerId", e.PartnerID,
@@ -64,7 +111,7 @@ func (m *Module) handlePartnerInviteCreated(ctx context.Context, e events.Partne
 
 func (m *Module) handlePartnerOfferCreated(ctx context.Context, e events.PartnerOfferCreated) error {
 
-	acceptURL := m.buildPublicURL("/partner-offer", e.PublicToken)
+	acceptURL := m.buildPublicURL(ctx, e.OrganizationID, "/partner-offer", e.PublicToken)
 
 	priceFormatted := formatCurrencyEURCents(e.VakmanPriceCents)
 	whatsappMsg := fmt.Sprintf(
@@ -101,6 +148,7 @@ func (m *Module) handlePartnerOfferCreated(ctx context.Context, e events.Partner
 			EventType: "partner_offer_created",
 			Title:     "Werkaanbod verstuurd naar vakman",
 			Summary:   &summary,
+			DedupKey:  leadrepo.TimelineDedupKey("partner_offer_created", e.OfferID),
 			Metadata: map[string]any{
 				"offerId":          e.OfferID.String(),
 				"partnerId":        e.PartnerID.String(),
@@ -164,11 +212,14 @@ func (m *Module) handlePartnerOfferCreated(ctx context.Context, e events.Partner
 			return nil
 		}
 		steps := []repository.WorkflowStep{{
-			Enabled:      true,
-			Channel:      "whatsapp",
-			Audience:     "partner",
-			DelayMinutes: whatsAppRule.DelayMinutes,
-			TemplateBody: &messageText,
+			ID:            whatsAppRule.stepID(),
+			WorkflowID:    whatsAppRule.workflowID(),
+			Enabled:       true,
+			Channel:       "whatsapp",
+			Audience:      "partner",
+			DelayMinutes:  whatsAppRule.DelayMinutes,
+			TemplateBody:  &messageText,
+			SendCondition: whatsAppRule.sendCondition(),
 			RecipientConfig: map[string]any{
 				"includePartner": true,
 			},
@@ -183,6 +234,8 @@ func (m *Module) handlePartnerOfferCreated(ctx context.Context, e events.Partner
 			DefaultSummary: fmt.Sprintf("WhatsApp werkaanbod verstuurd naar %s", e.PartnerName),
 			DefaultActor:   "System",
 			DefaultOrigin:  workflowEngineActorName,
+			Language:       whatsAppRule.language(),
+			VariantKey:     whatsAppRule.variantKey(),
 		})
 	}
 
@@ -209,6 +262,7 @@ func (m *Module) handlePartnerOfferAccepted(ctx context.Context, e events.Partne
 			EventType: "partner_offer_accepted",
 			Title:     "Werkaanbod geaccepteerd",
 			Summary:   &summary,
+			DedupKey:  leadrepo.TimelineDedupKey("partner_offer_accepted", e.OfferID),
 			Metadata: map[string]any{
 				"offerId":     e.OfferID.String(),
 				"partnerId":   e.PartnerID.String(),
@@ -284,6 +338,7 @@ func (m *Module) handlePartnerOfferRejected(ctx context.Context, e events.Partne
 			EventType: "partner_offer_rejected",
 			Title:     "Werkaanbod afgewezen",
 			Summary:   &summary,
+			DedupKey:  leadrepo.TimelineDedupKey("partner_offer_rejected", e.OfferID),
 			Metadata: map[string]any{
 				"offerId":     e.OfferID.String(),
 				"partnerId":   e.PartnerID.String(),
@@ -336,6 +391,7 @@ func (m *Module) handlePartnerOfferExpired(ctx context.Context, e events.Partner
 			EventType: "partner_offer_expired",
 			Title:     "Werkaanbod verlopen",
 			Summary:   &summary,
+			DedupKey:  leadrepo.TimelineDedupKey("partner_offer_expired", e.OfferID),
 			Metadata: map[string]any{
 				"offerId":     e.OfferID.String(),
 				"partnerId":   e.PartnerID.String(),
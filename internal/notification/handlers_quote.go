@@ -10,8 +10,10 @@ import (
 	"portal_final_backend/internal/identity/repository"
 	"portal_final_backend/internal/notification/inapp"
 	"portal_final_backend/internal/notification/sse"
+	"portal_final_backend/internal/notification/suppression"
 	"portal_final_backend/internal/pdf"
 	"portal_final_backend/internal/scheduler"
+	"portal_final_backend/platform/timekit"
 	"strings"
 	"time"
 
@@ -44,6 +46,10 @@ type subsidyPDFGeneratorFunc func(data isdeSubsidyPDFAttachmentPayload) ([]byte,
 
 const pdfMIMEType = "application/pdf"
 
+// maxQuotePDFAttachmentBytes caps the quote PDF attached to an email. Larger
+// PDFs are left out and the email links to the download instead.
+const maxQuotePDFAttachmentBytes = 10 << 20
+
 func (f subsidyPDFGeneratorFunc) GenerateSubsidyPDF(data isdeSubsidyPDFAttachmentPayload) ([]byte, error) {
 	return f(data)
 }
@@ -199,15 +205,48 @@ func (m *Module) resolveQuotePDFAttachment(ctx context.Context, orgID uuid.UUID,
 		mimeType = pdfMIMEType
 	}
 
-	if m.quotePDFGen == nil {
-		return email.Attachment{}, fmt.Errorf("quote pdf generator not configured")
+	data, err := m.loadQuotePDFAttachmentData(ctx, quoteID, orgID, spec)
+	if err != nil {
+		return email.Attachment{}, err
+	}
+	if len(data) > maxQuotePDFAttachmentBytes {
+		return email.Attachment{}, fmt.Errorf("%w: quote pdf is %d bytes", errAttachmentTooLarge, len(data))
+	}
+	return email.Attachment{Content: data, FileName: fileName, MIMEType: mimeType}, nil
+}
+
+// loadQuotePDFAttachmentData returns the PDF to attach for a quote. A signed
+// PDF no longer changes once stored, so it is read from storage; any other
+// quote may have been edited since its PDF was stored and is regenerated.
+func (m *Module) loadQuotePDFAttachmentData(ctx context.Context, quoteID, orgID uuid.UUID, spec emailSendAttachmentSpec) ([]byte, error) {
+	fileKey := strings.TrimSpace(spec.FileKey)
+	if spec.Signed && fileKey != "" && m.quotePDFStorage != nil && m.quotePDFBucket != "" {
+		data, err := m.downloadStoredQuotePDF(ctx, fileKey)
+		if err == nil {
+			return data, nil
+		}
+		m.log.Warn("failed to download signed quote pdf; regenerating", "quoteId", quoteID, "fileKey", fileKey, "error", err)
 	}
 
+	if m.quotePDFGen == nil {
+		return nil, fmt.Errorf("quote pdf generator not configured")
+	}
 	_, data, err := m.quotePDFGen.RegeneratePDF(ctx, quoteID, orgID)
 	if err != nil {
-		return email.Attachment{}, fmt.Errorf("generate quote pdf attachment: %w", err)
+		return nil, fmt.Errorf("generate quote pdf attachment: %w", err)
 	}
-	return email.Attachment{Content: data, FileName: fileName, MIMEType: mimeType}, nil
+	return data, nil
+}
+
+// downloadStoredQuotePDF reads a stored quote PDF, reading at most one byte
+// past the attachment limit so oversized files are not buffered in full.
+func (m *Module) downloadStoredQuotePDF(ctx context.Context, fileKey string) ([]byte, error) {
+	reader, err := m.quotePDFStorage.DownloadFile(ctx, m.quotePDFBucket, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(io.LimitReader(reader, maxQuotePDFAttachmentBytes+1))
 }
 
 func (m *Module) resolveISDESubsidyPDFAttachment(spec emailSendAttachmentSpec) (email.Attachment, error) {
@@ -308,6 +347,8 @@ func (m *Module) dispatchQuoteEmailWorkflow(ctx context.Context, p dispatchQuote
 		m.log.Info(msgWorkflowEmailDispatchSkipped, "orgId", p.OrgID, "trigger", p.Trigger, "reason", "rule_disabled")
 		return true
 	}
+	p.LeadEmail = m.unsuppressedEmail(ctx, p.OrgID, p.LeadEmail)
+	p.PartnerEmail = m.unsuppressedEmail(ctx, p.OrgID, p.PartnerEmail)
 	if strings.TrimSpace(p.LeadEmail) == "" && strings.TrimSpace(p.PartnerEmail) == "" {
 		m.log.Info(msgWorkflowEmailDispatchSkipped, "orgId", p.OrgID, "trigger", p.Trigger, "reason", "no_recipients")
 		return true
@@ -339,6 +380,8 @@ func (m *Module) dispatchQuoteEmailWorkflow(ctx context.Context, p dispatchQuote
 		"includePartner":     strings.TrimSpace(p.PartnerEmail) != "",
 	}
 	steps := []repository.WorkflowStep{{
+		ID:              p.Rule.stepID(),
+		WorkflowID:      p.Rule.workflowID(),
 		Enabled:         true,
 		Channel:         "email",
 		Audience:        "lead",
@@ -346,6 +389,7 @@ func (m *Module) dispatchQuoteEmailWorkflow(ctx context.Context, p dispatchQuote
 		TemplateSubject: &subject,
 		TemplateBody:    &bodyHTML,
 		RecipientConfig: recipientConfig,
+		SendCondition:   p.Rule.sendCondition(),
 	}}
 
 	err := m.enqueueWorkflowSteps(ctx, steps, workflowStepExecutionContext{
@@ -358,6 +402,8 @@ func (m *Module) dispatchQuoteEmailWorkflow(ctx context.Context, p dispatchQuote
 		DefaultSummary: p.Summary,
 		DefaultActor:   "System",
 		DefaultOrigin:  workflowEngineActorName,
+		Language:       p.Rule.language(),
+		VariantKey:     p.Rule.variantKey(),
 		Variables:      p.TemplateVars,
 	})
 	if err != nil {
@@ -393,6 +439,10 @@ func (m *Module) dispatchQuoteWhatsAppWorkflow(ctx context.Context, p dispatchQu
 		m.log.Info(msgWorkflowWhatsAppDispatchSkipped, "orgId", p.OrgID, "trigger", p.Trigger, "reason", "missing_phone")
 		return true
 	}
+	if suppressed, _ := m.isAddressSuppressed(ctx, p.OrgID, suppression.KindPhone, p.LeadPhone); suppressed {
+		m.log.Info(msgWorkflowWhatsAppDispatchSkipped, "orgId", p.OrgID, "trigger", p.Trigger, "reason", "suppressed")
+		return true
+	}
 	if p.LeadID != nil && !m.isLeadWhatsAppOptedIn(ctx, *p.LeadID, p.OrgID) {
 		m.log.Info(msgWorkflowWhatsAppDispatchSkipped, "orgId", p.OrgID, "trigger", p.Trigger, "leadId", *p.LeadID, "reason", "lead_opted_out_in_db")
 		return true
@@ -414,11 +464,14 @@ func (m *Module) dispatchQuoteWhatsAppWorkflow(ctx context.Context, p dispatchQu
 	}
 
 	steps := []repository.WorkflowStep{{
-		Enabled:      true,
-		Channel:      "whatsapp",
-		Audience:     "lead",
-		DelayMinutes: p.Rule.DelayMinutes,
-		TemplateBody: &messageText,
+		ID:            p.Rule.stepID(),
+		WorkflowID:    p.Rule.workflowID(),
+		Enabled:       true,
+		Channel:       "whatsapp",
+		Audience:      "lead",
+		DelayMinutes:  p.Rule.DelayMinutes,
+		TemplateBody:  &messageText,
+		SendCondition: p.Rule.sendCondition(),
 		RecipientConfig: map[string]any{
 			"includeLeadContact": true,
 		},
@@ -432,6 +485,8 @@ func (m *Module) dispatchQuoteWhatsAppWorkflow(ctx context.Context, p dispatchQu
 		DefaultSummary: p.Summary,
 		DefaultActor:   "System",
 		DefaultOrigin:  workflowEngineActorName,
+		Language:       p.Rule.language(),
+		VariantKey:     p.Rule.variantKey(),
 	})
 	if enqueueErr != nil {
 		m.log.Warn(p.FallbackNote, "error", enqueueErr, "orgId", p.OrgID)
@@ -474,7 +529,7 @@ func (m *Module) dispatchQuoteSentLeadWhatsAppWorkflow(ctx context.Context, e ev
 	}
 
 	rule := m.resolveWorkflowRule(ctx, e.OrganizationID, e.LeadID, "quote_sent", "whatsapp", "lead", nil)
-	proposalURL := strings.TrimRight(m.cfg.GetPublicBaseURL(), "/") + quotePublicPathPrefix + e.PublicToken
+	proposalURL := m.publicBaseURL(ctx, e.OrganizationID) + quotePublicPathPrefix + e.PublicToken
 	downloadURL := m.buildPublicQuotePDFURL(e.PublicToken)
 	name := defaultName(strings.TrimSpace(e.ConsumerName), "klant")
 	details := m.resolveLeadDetails(ctx, e.LeadID, e.OrganizationID)
@@ -503,7 +558,7 @@ func (m *Module) dispatchQuoteSentLeadEmailWorkflow(ctx context.Context, e event
 		return true
 	}
 
-	proposalURL := strings.TrimRight(m.cfg.GetPublicBaseURL(), "/") + quotePublicPathPrefix + e.PublicToken
+	proposalURL := m.publicBaseURL(ctx, e.OrganizationID) + quotePublicPathPrefix + e.PublicToken
 	downloadURL := m.buildPublicQuotePDFURL(e.PublicToken)
 	name := defaultName(strings.TrimSpace(e.ConsumerName), "klant")
 	details := m.resolveLeadDetails(ctx, e.LeadID, e.OrganizationID)
@@ -556,12 +611,59 @@ func (m *Module) handleQuoteViewed(ctx context.Context, e events.QuoteViewed) er
 		ResourceID:   &e.QuoteID,
 		ResourceType: "quote",
 		Category:     "info",
+		GroupKey:     "quote_viewed",
+		GroupTitle:   "%d offertes vandaag bekeken",
 	})
 
 	m.log.Info("quote viewed event processed", "quoteId", e.QuoteID)
 	return nil
 }
 
+func (m *Module) handleQuoteApprovalRequested(ctx context.Context, e events.QuoteApprovalRequested) error {
+	quoteNumber := strings.TrimSpace(e.QuoteNumber)
+	if quoteNumber == "" {
+		quoteNumber = "onbekend"
+	}
+	m.notifyOrgMembersInAppByRoles(ctx, e.OrganizationID, adminOnlyRoles, inapp.SendParams{
+		Title:        "Offerte wacht op goedkeuring",
+		Content:      fmt.Sprintf("Offerte %s (%s) moet worden goedgekeurd voordat deze verzonden kan worden.", quoteNumber, formatCurrencyEURCents(e.QuoteTotalCents)),
+		ResourceID:   &e.QuoteID,
+		ResourceType: "quote",
+		Category:     "warning",
+	})
+	m.log.Info("quote approval requested event processed", "quoteId", e.QuoteID, "approvalId", e.ApprovalID)
+	return nil
+}
+
+func (m *Module) handleQuoteApprovalDecided(ctx context.Context, e events.QuoteApprovalDecided) error {
+	if m.inAppService == nil || e.RequestedByID == nil {
+		return nil
+	}
+	quoteNumber := strings.TrimSpace(e.QuoteNumber)
+	if quoteNumber == "" {
+		quoteNumber = "onbekend"
+	}
+
+	params := inapp.SendParams{
+		OrgID:        e.OrganizationID,
+		UserID:       *e.RequestedByID,
+		Title:        "Offerte goedgekeurd",
+		Content:      fmt.Sprintf("Offerte %s is goedgekeurd en kan nu verzonden worden.", quoteNumber),
+		ResourceID:   &e.QuoteID,
+		ResourceType: "quote",
+		Category:     "success",
+	}
+	if !e.Approved {
+		params.Title = "Offerte afgekeurd"
+		params.Content = fmt.Sprintf("Offerte %s is afgekeurd: %s", quoteNumber, e.Note)
+		params.Category = "warning"
+	}
+	if err := m.inAppService.Send(ctx, params); err != nil {
+		m.log.Warn("failed to send quote approval decision notification", "error", err, "quoteId", e.QuoteID)
+	}
+	return nil
+}
+
 func (m *Module) handleQuoteUpdatedByCustomer(ctx context.Context, e events.QuoteUpdatedByCustomer) error {
 	m.pushQuoteSSE(e.OrganizationID, sse.EventQuoteItemToggled, e.QuoteID, map[string]interface{}{
 		"itemId":          e.ItemID,
@@ -586,7 +688,7 @@ func (m *Module) handleQuoteUpdatedByCustomer(ctx context.Context, e events.Quot
 
 func (m *Module) handleQuoteAnnotated(ctx context.Context, e events.QuoteAnnotated) error {
 	m.pushQuoteSSE(e.OrganizationID, sse.EventQuoteAnnotated, e.QuoteID, map[string]interface{}{
-		"itemId":     e.ItemID,
+		"itemId":     quoteAnnotationItemRef(e.ItemID),
 		"authorType": e.AuthorType,
 		"text":       e.Text,
 	})
@@ -596,7 +698,7 @@ func (m *Module) handleQuoteAnnotated(ctx context.Context, e events.QuoteAnnotat
 	}
 	m.logQuoteActivity(ctx, e.QuoteID, e.OrganizationID, "quote_annotated",
 		activityMessage,
-		map[string]interface{}{"itemId": e.ItemID.String(), "authorType": e.AuthorType, "text": e.Text})
+		map[string]interface{}{"itemId": quoteAnnotationItemRef(e.ItemID), "authorType": e.AuthorType, "text": e.Text})
 	if strings.EqualFold(e.AuthorType, "customer") {
 		_ = m.dispatchQuoteQuestionAskedPartnerEmailWorkflow(ctx, e)
 		_ = m.dispatchQuoteQuestionAskedPartnerWhatsAppWorkflow(ctx, e)
@@ -608,10 +710,19 @@ func (m *Module) handleQuoteAnnotated(ctx context.Context, e events.QuoteAnnotat
 	return nil
 }
 
+// quoteAnnotationItemRef returns the item of an annotation, or "" for a
+// comment on the quote as a whole.
+func quoteAnnotationItemRef(itemID uuid.UUID) string {
+	if itemID == uuid.Nil {
+		return ""
+	}
+	return itemID.String()
+}
+
 func (m *Module) buildQuoteAnnotationTemplateVars(ctx context.Context, e events.QuoteAnnotated) map[string]any {
 	previewURL := ""
 	if strings.TrimSpace(e.PublicToken) != "" {
-		previewURL = strings.TrimRight(m.cfg.GetPublicBaseURL(), "/") + quotePublicPathPrefix + strings.TrimSpace(e.PublicToken)
+		previewURL = m.publicBaseURL(ctx, e.OrganizationID) + quotePublicPathPrefix + strings.TrimSpace(e.PublicToken)
 	}
 
 	leadName := defaultName(strings.TrimSpace(e.ConsumerName), "klant")
@@ -635,7 +746,7 @@ func (m *Module) buildQuoteAnnotationTemplateVars(ctx context.Context, e events.
 		"annotation": map[string]any{
 			"text":            e.Text,
 			"authorType":      e.AuthorType,
-			"itemId":          e.ItemID.String(),
+			"itemId":          quoteAnnotationItemRef(e.ItemID),
 			"itemDescription": e.ItemDescription,
 		},
 		"links": map[string]any{
@@ -690,11 +801,14 @@ func (m *Module) dispatchQuoteQuestionAskedPartnerWhatsAppWorkflow(ctx context.C
 		return true
 	}
 	steps := []repository.WorkflowStep{{
-		Enabled:      true,
-		Channel:      "whatsapp",
-		Audience:     "partner",
-		DelayMinutes: rule.DelayMinutes,
-		TemplateBody: &messageText,
+		ID:            rule.stepID(),
+		WorkflowID:    rule.workflowID(),
+		Enabled:       true,
+		Channel:       "whatsapp",
+		Audience:      "partner",
+		DelayMinutes:  rule.DelayMinutes,
+		TemplateBody:  &messageText,
+		SendCondition: rule.sendCondition(),
 		RecipientConfig: map[string]any{
 			"includePartner": true,
 		},
@@ -709,6 +823,8 @@ func (m *Module) dispatchQuoteQuestionAskedPartnerWhatsAppWorkflow(ctx context.C
 		DefaultSummary: fmt.Sprintf("WhatsApp offertevraag verstuurd naar %s", defaultName(strings.TrimSpace(e.CreatorName), "adviseur")),
 		DefaultActor:   "System",
 		DefaultOrigin:  workflowEngineActorName,
+		Language:       rule.language(),
+		VariantKey:     rule.variantKey(),
 		Variables:      templateVars,
 	}); err != nil {
 		m.log.Warn("failed to enqueue quote_question_asked partner whatsapp workflow", "error", err, "orgId", e.OrganizationID)
@@ -800,7 +916,7 @@ func (m *Module) handleQuoteAccepted(ctx context.Context, e events.QuoteAccepted
 
 func (m *Module) dispatchQuoteAcceptedLeadEmailWorkflow(ctx context.Context, e events.QuoteAccepted, pdfFileKey string) bool {
 	name := defaultName(strings.TrimSpace(e.ConsumerName), "klant")
-	baseURL := strings.TrimRight(m.cfg.GetPublicBaseURL(), "/")
+	baseURL := m.publicBaseURL(ctx, e.OrganizationID)
 	downloadURL := m.buildPublicQuotePDFURL(e.PublicToken)
 	viewURL := baseURL + quotePublicPathPrefix + e.PublicToken
 	formattedPrice := formatCurrencyEURCents(e.TotalCents)
@@ -808,7 +924,7 @@ func (m *Module) dispatchQuoteAcceptedLeadEmailWorkflow(ctx context.Context, e e
 	templateVars := map[string]any{
 		"lead":  map[string]any{"name": name, "phone": e.ConsumerPhone, "email": e.ConsumerEmail},
 		"quote": map[string]any{"id": e.QuoteID.String(), "number": e.QuoteNumber, "totalCents": e.TotalCents, "total": formattedPrice, "totalFormatted": formattedPrice, "downloadUrl": downloadURL, "pdfFileKey": strings.TrimSpace(pdfFileKey)},
-		"links": map[string]any{"view": viewURL, "download": downloadURL, "scheduling": m.buildSchedulingLink(details)},
+		"links": map[string]any{"view": viewURL, "download": downloadURL, "scheduling": m.buildSchedulingLink(ctx, e.OrganizationID, details)},
 		"org":   map[string]any{"name": e.OrganizationName},
 	}
 	injectQuoteSubsidyTemplateVars(templateVars, e.ISDESubsidy)
@@ -835,7 +951,7 @@ func (m *Module) dispatchQuoteAcceptedAgentEmailWorkflow(ctx context.Context, e
 		"partner": map[string]any{"name": name, "email": e.AgentEmail},
 		"lead":    map[string]any{"name": defaultName(strings.TrimSpace(e.ConsumerName), "de klant")},
 		"quote":   map[string]any{"number": e.QuoteNumber, "totalCents": e.TotalCents, "total": formattedPrice, "totalFormatted": formattedPrice},
-		"links":   map[string]any{"scheduling": m.buildSchedulingLink(details)},
+		"links":   map[string]any{"scheduling": m.buildSchedulingLink(ctx, e.OrganizationID, details)},
 		"org":     map[string]any{"name": e.OrganizationName},
 	}
 	enrichLeadVars(templateVars, details)
@@ -861,7 +977,7 @@ func (m *Module) dispatchQuoteAcceptedLeadWhatsAppWorkflow(ctx context.Context,
 	templateVars := map[string]any{
 		"lead":  map[string]any{"name": name, "phone": e.ConsumerPhone, "email": e.ConsumerEmail},
 		"quote": map[string]any{"number": e.QuoteNumber, "totalCents": e.TotalCents, "total": formattedPrice, "totalFormatted": formattedPrice, "downloadUrl": downloadURL},
-		"links": map[string]any{"download": downloadURL, "scheduling": m.buildSchedulingLink(details)},
+		"links": map[string]any{"download": downloadURL, "scheduling": m.buildSchedulingLink(ctx, e.OrganizationID, details)},
 		"org":   map[string]any{"name": e.OrganizationName},
 	}
 	enrichLeadVars(templateVars, details)
@@ -1016,3 +1132,95 @@ func (m *Module) logQuoteActivity(ctx context.Context, quoteID, orgID uuid.UUID,
 		)
 	}
 }
+
+func (m *Module) handleQuoteInstallmentDue(ctx context.Context, e events.QuoteInstallmentDue) error {
+	details := m.resolveLeadDetails(ctx, e.LeadID, e.OrganizationID)
+	orgName := defaultName(strings.TrimSpace(m.resolveOrganizationName(ctx, e.OrganizationID)), defaultOrgNameFallback)
+
+	name := "klant"
+	phone := ""
+	email := ""
+	if details != nil {
+		name = defaultName(strings.TrimSpace(details.FirstName+" "+details.LastName), "klant")
+		phone = details.Phone
+		email = details.Email
+	}
+
+	viewURL := ""
+	if e.PublicToken != "" {
+		viewURL = m.publicBaseURL(ctx, e.OrganizationID) + quotePublicPathPrefix + e.PublicToken
+	}
+	amount := formatCurrencyEURCents(e.AmountCents)
+	dueAt := e.DueAt.In(timekit.ResolveLocation("Europe/Amsterdam"))
+	dueDate := dueAt.Format("02-01-2006")
+	templateVars := map[string]any{
+		"lead":        map[string]any{"name": name, "phone": phone, "email": email},
+		"quote":       map[string]any{"id": e.QuoteID.String(), "number": e.QuoteNumber, "previewUrl": viewURL},
+		"installment": map[string]any{"label": e.Label, "sequence": e.Sequence, "amountCents": e.AmountCents, "amount": amount, "dueDate": dueDate, "dueAt": dueAt},
+		"links":       map[string]any{"view": viewURL},
+		"org":         map[string]any{"name": orgName},
+	}
+	enrichLeadVars(templateVars, details)
+
+	if strings.TrimSpace(phone) != "" && m.isLeadWhatsAppOptedIn(ctx, e.LeadID, e.OrganizationID) {
+		m.dispatchQuoteWhatsAppWorkflow(ctx, dispatchQuoteWhatsAppWorkflowParams{
+			Rule:         m.resolveWorkflowRule(ctx, e.OrganizationID, e.LeadID, "quote_installment_due", "whatsapp", "lead", nil),
+			OrgID:        e.OrganizationID,
+			LeadID:       &e.LeadID,
+			ServiceID:    e.LeadServiceID,
+			LeadPhone:    phone,
+			Trigger:      "quote_installment_due",
+			TemplateVars: templateVars,
+			Summary:      fmt.Sprintf("WhatsApp betalingsherinnering verstuurd naar %s", name),
+			FallbackNote: "failed to enqueue quote_installment_due lead whatsapp workflow",
+		})
+	}
+
+	m.dispatchQuoteEmailWorkflow(ctx, dispatchQuoteEmailWorkflowParams{
+		Rule:         m.resolveWorkflowRule(ctx, e.OrganizationID, e.LeadID, "quote_installment_due", "email", "lead", nil),
+		OrgID:        e.OrganizationID,
+		LeadID:       &e.LeadID,
+		ServiceID:    e.LeadServiceID,
+		LeadEmail:    email,
+		Trigger:      "quote_installment_due",
+		TemplateVars: templateVars,
+		Summary:      fmt.Sprintf("Email betalingsherinnering verstuurd naar %s", name),
+		FallbackNote: "failed to enqueue quote_installment_due lead email workflow",
+	})
+
+	m.logQuoteActivity(ctx, e.QuoteID, e.OrganizationID, "quote_installment_due",
+		fmt.Sprintf("Termijn '%s' (%s) is verschuldigd", truncate(e.Label, 60), amount),
+		map[string]interface{}{"installmentId": e.InstallmentID.String(), "amountCents": e.AmountCents, "dueAt": e.DueAt})
+	m.log.Info("quote installment due event processed", "quoteId", e.QuoteID, "installmentId", e.InstallmentID)
+	return nil
+}
+
+func (m *Module) handleQuotePaymentUpdated(ctx context.Context, e events.QuotePaymentUpdated) error {
+	m.pushQuoteSSE(e.OrganizationID, sse.EventQuotePaymentUpdated, e.QuoteID, map[string]interface{}{
+		"paymentId":   e.PaymentID,
+		"status":      e.Status,
+		"amountCents": e.AmountCents,
+		"paidAt":      e.PaidAt,
+	})
+
+	amount := formatCurrencyEURCents(e.AmountCents)
+	if e.Status == "paid" {
+		quoteNumber := strings.TrimSpace(e.QuoteNumber)
+		if quoteNumber == "" {
+			quoteNumber = "onbekend"
+		}
+		m.sendToAgentOrAdmins(ctx, e.OrganizationID, e.LeadID, inapp.SendParams{
+			Title:        "Aanbetaling ontvangen",
+			Content:      fmt.Sprintf("De aanbetaling van %s voor offerte %s is betaald.", amount, quoteNumber),
+			ResourceID:   &e.QuoteID,
+			ResourceType: "quote",
+			Category:     "success",
+		})
+	}
+
+	m.logQuoteActivity(ctx, e.QuoteID, e.OrganizationID, "quote_payment_updated",
+		fmt.Sprintf("Betaling van %s: %s", amount, e.Status),
+		map[string]interface{}{"paymentId": e.PaymentID.String(), "provider": e.Provider, "status": e.Status})
+	m.log.Info("quote payment updated event processed", "quoteId", e.QuoteID, "paymentId", e.PaymentID, "status", e.Status)
+	return nil
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"portal_final_backend/internal/email"
 	"portal_final_backend/internal/events"
+	leadrepo "portal_final_backend/internal/leads/repository"
 	"portal_final_backend/internal/notification/inapp"
 	notificationoutbox "portal_final_backend/internal/notification/outbox"
+	"portal_final_backend/internal/notification/suppression"
 	"strings"
 	"time"
 
@@ -55,10 +58,16 @@ func (m *Module) handleNotificationOutboxDue(ctx context.Context, e events.Notif
 		return err
 	}
 
+	if rec.Kind == inapp.OutboxKind {
+		return m.processInAppOutbox(ctx, rec)
+	}
 	if rec.Kind != "whatsapp" && rec.Kind != "email" {
 		m.markOutboxUnsupported(ctx, rec)
 		return nil
 	}
+	if m.deferForMessagingPolicy(ctx, rec) {
+		return nil
+	}
 
 	var processErr error
 	switch rec.Template {
@@ -84,6 +93,7 @@ func (m *Module) handleOutboxDeliveryError(ctx context.Context, rec notification
 	attempt := rec.Attempts + 1
 	if attempt >= maxOutboxRetryAttempts {
 		_ = m.notificationOutbox.MarkFailed(ctx, rec.ID, deliveryErr.Error())
+		m.reportUndeliverable(ctx, rec, deliveryErr.Error())
 		m.log.Warn("notification outbox exhausted retries",
 			"outboxId", rec.ID.String(),
 			"kind", rec.Kind,
@@ -163,6 +173,13 @@ func (m *Module) processGenericWhatsAppOutbox(ctx context.Context, e events.Noti
 		}
 	}
 
+	if suppressed, err := m.isAddressSuppressed(ctx, orgID, suppression.KindPhone, payload.PhoneNumber); err != nil || suppressed {
+		if suppressed {
+			_ = m.notificationOutbox.MarkSucceeded(ctx, rec.ID)
+		}
+		return err
+	}
+
 	leadID := parseOptionalUUID(payload.LeadID)
 	svcID := parseOptionalUUID(payload.ServiceID)
 	if leadID != nil {
@@ -179,6 +196,12 @@ func (m *Module) processGenericWhatsAppOutbox(ctx context.Context, e events.Noti
 			_ = m.notificationOutbox.MarkSucceeded(ctx, rec.ID)
 			return nil
 		}
+		if payload.Audience == "lead" {
+			allowed, err := m.allowOutboxLeadMessage(ctx, rec, *leadID, orgID, leadrepo.ConsentWhatsApp, payload.Purpose, payload.PhoneNumber)
+			if !allowed {
+				return err
+			}
+		}
 	}
 
 	err := m.sendWhatsAppBestEffort(whatsAppBestEffortParams{
@@ -229,7 +252,20 @@ func (m *Module) processGenericEmailOutbox(ctx context.Context, e events.Notific
 		}
 	}
 
-	attachments, err := m.resolveEmailOutboxAttachments(ctx, orgID, payload)
+	if suppressed, err := m.isAddressSuppressed(ctx, orgID, suppression.KindEmail, payload.ToEmail); err != nil || suppressed {
+		if suppressed {
+			_ = m.notificationOutbox.MarkSucceeded(ctx, rec.ID)
+		}
+		return err
+	}
+	if leadID := parseOptionalUUID(payload.LeadID); leadID != nil && payload.Audience == "lead" {
+		allowed, err := m.allowOutboxLeadMessage(ctx, rec, *leadID, orgID, leadrepo.ConsentEmail, payload.Purpose, payload.ToEmail)
+		if !allowed {
+			return err
+		}
+	}
+
+	attachments, fallbackURLs, err := m.resolveEmailOutboxAttachments(ctx, orgID, payload)
 	if err != nil {
 		if errors.Is(err, errInvalidOutboxPayload) {
 			_ = m.notificationOutbox.MarkFailed(ctx, rec.ID, invalidOutboxPayloadPrefix+err.Error())
@@ -237,31 +273,89 @@ func (m *Module) processGenericEmailOutbox(ctx context.Context, e events.Notific
 		}
 		return err
 	}
+	bodyHTML := appendAttachmentFallbackLinks(payload.BodyHTML, fallbackURLs)
+	bodyHTML, tracked := m.instrumentTrackedEmail(ctx, orgID, rec, payload, bodyHTML)
 
 	sender := m.resolveSender(ctx, orgID)
-	if err := sender.SendCustomEmail(ctx, payload.ToEmail, payload.Subject, payload.BodyHTML, attachments...); err != nil {
+	if err := sender.SendCustomEmail(ctx, payload.ToEmail, payload.Subject, bodyHTML, attachments...); err != nil {
+		if email.IsHardBounce(err) {
+			m.suppressAddress(ctx, orgID, suppression.KindEmail, payload.ToEmail, suppression.ReasonHardBounce)
+			_ = m.notificationOutbox.MarkFailed(ctx, rec.ID, "hard bounce: "+err.Error())
+			m.reportUndeliverable(ctx, rec, "hard bounce: "+err.Error())
+			return nil
+		}
 		return err
 	}
 
 	_ = m.notificationOutbox.MarkSucceeded(ctx, rec.ID)
+	if tracked {
+		m.recordTrackedEmailSent(ctx, orgID, rec, payload)
+	}
 	m.log.Info("email outbox delivered", "outboxId", rec.ID.String(), "orgId", orgID, "toEmail", payload.ToEmail)
 	return nil
 }
 
-func (m *Module) resolveEmailOutboxAttachments(ctx context.Context, orgID uuid.UUID, payload emailSendOutboxPayload) ([]email.Attachment, error) {
+// resolveEmailOutboxAttachments resolves the attachments of an outbox email.
+// Attachments over the size limit that carry a fallback URL are left out and
+// their URLs returned, so the email can link to them instead.
+func (m *Module) resolveEmailOutboxAttachments(ctx context.Context, orgID uuid.UUID, payload emailSendOutboxPayload) ([]email.Attachment, []string, error) {
 	if len(payload.Attachments) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	attachments := make([]email.Attachment, 0, len(payload.Attachments))
+	var fallbackURLs []string
 	for _, spec := range payload.Attachments {
 		attachment, err := m.resolveEmailAttachment(ctx, orgID, spec)
 		if err != nil {
-			return nil, err
+			fallbackURL := strings.TrimSpace(spec.FallbackURL)
+			if errors.Is(err, errAttachmentTooLarge) && fallbackURL != "" {
+				m.log.Info("attachment too large for email; linking instead", "orgId", orgID, "kind", spec.Kind, "error", err)
+				fallbackURLs = append(fallbackURLs, fallbackURL)
+				continue
+			}
+			return nil, nil, err
 		}
 		attachments = append(attachments, attachment)
 	}
-	return attachments, nil
+	return attachments, fallbackURLs, nil
+}
+
+// appendAttachmentFallbackLinks adds a download link to the email body for
+// each attachment that was too large to send along.
+func appendAttachmentFallbackLinks(bodyHTML string, urls []string) string {
+	if len(urls) == 0 {
+		return bodyHTML
+	}
+	var b strings.Builder
+	b.WriteString(bodyHTML)
+	for _, url := range urls {
+		escaped := html.EscapeString(url)
+		fmt.Fprintf(&b, `<p>De bijlage is te groot om mee te sturen. Je kunt het document downloaden via <a href="%s">%s</a>.</p>`, escaped, escaped)
+	}
+	return b.String()
+}
+
+// processInAppOutbox resurfaces snoozed in-app notifications. Snoozes don't
+// reach the customer, so they skip the messaging policy.
+func (m *Module) processInAppOutbox(ctx context.Context, rec notificationoutbox.Record) error {
+	if rec.Template != inapp.OutboxTemplateResurface || m.inAppService == nil {
+		m.markOutboxUnsupported(ctx, rec)
+		return nil
+	}
+
+	var payload inapp.ResurfacePayload
+	if err := json.Unmarshal(rec.Payload, &payload); err != nil {
+		_ = m.notificationOutbox.MarkFailed(ctx, rec.ID, invalidOutboxPayloadPrefix+err.Error())
+		return nil
+	}
+	if err := m.inAppService.Resurface(ctx, payload); err != nil {
+		m.handleOutboxDeliveryError(ctx, rec, err)
+		return err
+	}
+	_ = m.notificationOutbox.MarkSucceeded(ctx, rec.ID)
+	m.log.Info("outbox record processed successfully", "outboxId", rec.ID.String(), "kind", rec.Kind, "template", rec.Template)
+	return nil
 }
 
 func (m *Module) markOutboxUnsupported(ctx context.Context, rec notificationoutbox.Record) {
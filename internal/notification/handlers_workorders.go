@@ -0,0 +1,20 @@
+package notification
+
+import (
+	"context"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/notification/inapp"
+)
+
+func (m *Module) handleWarrantyClaimSubmitted(ctx context.Context, e events.WarrantyClaimSubmitted) error {
+	m.notifyOrgMembersInAppByRoles(ctx, e.OrganizationID, operationsNotificationRoles, inapp.SendParams{
+		Title:        "Nieuwe garantieclaim",
+		Content:      "Een klant heeft een garantieclaim ingediend die beoordeeld moet worden.",
+		ResourceID:   &e.ClaimID,
+		ResourceType: "warranty_claim",
+		Category:     "warning",
+	})
+	m.log.Info("warranty claim submitted event processed", "claimId", e.ClaimID, "workOrderId", e.WorkOrderID)
+	return nil
+}
@@ -0,0 +1,275 @@
+package inapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/timekit"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	opSnooze       = "notification.inapp.repository.snooze"
+	opResurface    = "notification.inapp.repository.resurface"
+	opMarkReadBulk = "notification.inapp.repository.mark_read_bulk"
+	opDeleteBulk   = "notification.inapp.repository.delete_bulk"
+	opDeleteRead   = "notification.inapp.repository.delete_read"
+
+	errNotificationNotFound = "notification not found"
+)
+
+// visibleCondition excludes notifications that are still snoozed.
+const visibleCondition = `(snoozed_until IS NULL OR snoozed_until <= now())`
+
+const notificationColumns = `id, user_id, title, content, resource_id, resource_type, category, is_read,
+	group_key, group_count, snoozed_until, surfaced_at, created_at`
+
+// groupingLocation decides which calendar day a grouped notification belongs to.
+var groupingLocation = timekit.ResolveLocation("Europe/Amsterdam")
+
+func scanNotification(row pgx.Row) (Notification, error) {
+	var n Notification
+	err := row.Scan(&n.ID, &n.UserID, &n.Title, &n.Content, &n.ResourceID, &n.ResourceType, &n.Category, &n.IsRead,
+		&n.GroupKey, &n.GroupCount, &n.SnoozedUntil, &n.SurfacedAt, &n.CreatedAt)
+	return n, err
+}
+
+// startOfDay returns midnight of the day of t in the grouping location.
+func startOfDay(t time.Time) time.Time {
+	local := t.In(groupingLocation)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, groupingLocation)
+}
+
+// groupTitle returns the title of a group of count notifications.
+func groupTitle(format, fallback string, count int) string {
+	if format == "" || count < 2 {
+		return fallback
+	}
+	return fmt.Sprintf(format, count)
+}
+
+func (r *Repository) listNotifications(ctx context.Context, userID uuid.UUID, snoozed bool, limit, offset int) ([]Notification, int, error) {
+	condition, order := visibleCondition, "surfaced_at DESC, id DESC"
+	if snoozed {
+		condition, order = `snoozed_until > now()`, "snoozed_until ASC, id ASC"
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM RAC_in_app_notifications
+		WHERE user_id = $1 AND `+condition, userID,
+	).Scan(&total); err != nil {
+		return nil, 0, apperr.Internal(fmt.Sprintf("count notifications failed: %v", err)).WithOp(opList)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM RAC_in_app_notifications
+		WHERE user_id = $1 AND `+condition+`
+		ORDER BY `+order+`
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, apperr.Internal(fmt.Sprintf("list notifications query failed: %v", err)).WithOp(opList)
+	}
+	defer rows.Close()
+
+	items := make([]Notification, 0, limit)
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, 0, apperr.Internal(fmt.Sprintf("scan notification failed: %v", err)).WithOp(opList)
+		}
+		items = append(items, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, apperr.Internal(fmt.Sprintf("list notifications query failed: %v", err)).WithOp(opList)
+	}
+
+	return items, total, nil
+}
+
+// ListSnoozed returns the notifications of a user that are snoozed, soonest to
+// resurface first.
+func (r *Repository) ListSnoozed(ctx context.Context, userID uuid.UUID, limit, offset int) ([]Notification, int, error) {
+	if r == nil || r.pool == nil {
+		return nil, 0, apperr.Internal(errRepoNotConfigured).WithOp(opList)
+	}
+	if userID == uuid.Nil {
+		return nil, 0, apperr.Validation(errUserIDRequired).WithOp(opList)
+	}
+	return r.listNotifications(ctx, userID, true, limit, offset)
+}
+
+// createGrouped folds the notification into the unread notification with the
+// same group key that surfaced today, or creates the first of the group.
+func (r *Repository) createGrouped(ctx context.Context, p CreateParams) (Notification, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return Notification{}, apperr.Internal(fmt.Sprintf("begin grouped notification failed: %v", err)).WithOp(opCreate)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		existingID uuid.UUID
+		count      int
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT id, group_count
+		FROM RAC_in_app_notifications
+		WHERE user_id = $1 AND group_key = $2 AND is_read = FALSE
+			AND surfaced_at >= $3 AND `+visibleCondition+`
+		ORDER BY surfaced_at DESC
+		LIMIT 1
+		FOR UPDATE`, p.UserID, p.GroupKey, startOfDay(time.Now()),
+	).Scan(&existingID, &count)
+
+	var n Notification
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		n, err = scanNotification(tx.QueryRow(ctx, `
+			INSERT INTO RAC_in_app_notifications (
+				organization_id, user_id, title, content, resource_id, resource_type, category, group_key
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING `+notificationColumns,
+			p.OrganizationID, p.UserID, p.Title, p.Content, p.ResourceID, p.ResourceType, p.Category, p.GroupKey))
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return Notification{}, apperr.Validation("invalid organizationId or userId").WithOp(opCreate)
+			}
+			return Notification{}, apperr.Internal(fmt.Sprintf("create in-app notification failed: %v", err)).WithOp(opCreate)
+		}
+	case err != nil:
+		return Notification{}, apperr.Internal(fmt.Sprintf("find notification group failed: %v", err)).WithOp(opCreate)
+	default:
+		count++
+		n, err = scanNotification(tx.QueryRow(ctx, `
+			UPDATE RAC_in_app_notifications
+			SET title = $2, content = $3, resource_id = $4, resource_type = $5, category = $6,
+				group_count = $7, surfaced_at = now()
+			WHERE id = $1
+			RETURNING `+notificationColumns,
+			existingID, groupTitle(p.GroupTitle, p.Title, count), p.Content, p.ResourceID, p.ResourceType, p.Category, count))
+		if err != nil {
+			return Notification{}, apperr.Internal(fmt.Sprintf("update notification group failed: %v", err)).WithOp(opCreate)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Notification{}, apperr.Internal(fmt.Sprintf("commit grouped notification failed: %v", err)).WithOp(opCreate)
+	}
+	return n, nil
+}
+
+// Snooze hides a notification until the given time and returns the
+// organization it belongs to.
+func (r *Repository) Snooze(ctx context.Context, userID, notificationID uuid.UUID, until time.Time) (uuid.UUID, error) {
+	if r == nil || r.pool == nil {
+		return uuid.Nil, apperr.Internal(errRepoNotConfigured).WithOp(opSnooze)
+	}
+	if userID == uuid.Nil || notificationID == uuid.Nil {
+		return uuid.Nil, apperr.Validation("userId and notificationId are required").WithOp(opSnooze)
+	}
+
+	var orgID uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		UPDATE RAC_in_app_notifications
+		SET snoozed_until = $3
+		WHERE id = $1 AND user_id = $2
+		RETURNING organization_id`, notificationID, userID, until,
+	).Scan(&orgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, apperr.NotFound(errNotificationNotFound).WithOp(opSnooze)
+	}
+	if err != nil {
+		return uuid.Nil, apperr.Internal(fmt.Sprintf("snooze notification failed: %v", err)).WithOp(opSnooze)
+	}
+	return orgID, nil
+}
+
+// Resurface brings a notification whose snooze has expired back to the top of
+// the feed. It returns the notification and false when the notification was
+// deleted, or was snoozed again until a later time.
+func (r *Repository) Resurface(ctx context.Context, userID, notificationID uuid.UUID) (Notification, bool, error) {
+	if r == nil || r.pool == nil {
+		return Notification{}, false, apperr.Internal(errRepoNotConfigured).WithOp(opResurface)
+	}
+
+	n, err := scanNotification(r.pool.QueryRow(ctx, `
+		UPDATE RAC_in_app_notifications
+		SET snoozed_until = NULL, surfaced_at = now()
+		WHERE id = $1 AND user_id = $2 AND snoozed_until IS NOT NULL AND snoozed_until <= now()
+		RETURNING `+notificationColumns, notificationID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Notification{}, false, nil
+	}
+	if err != nil {
+		return Notification{}, false, apperr.Internal(fmt.Sprintf("resurface notification failed: %v", err)).WithOp(opResurface)
+	}
+	return n, true, nil
+}
+
+// MarkReadBulk marks the given notifications of a user as read and returns how
+// many changed.
+func (r *Repository) MarkReadBulk(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if r == nil || r.pool == nil {
+		return 0, apperr.Internal(errRepoNotConfigured).WithOp(opMarkReadBulk)
+	}
+	if userID == uuid.Nil {
+		return 0, apperr.Validation(errUserIDRequired).WithOp(opMarkReadBulk)
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_in_app_notifications
+		SET is_read = TRUE, read_at = now()
+		WHERE user_id = $1 AND id = ANY($2::uuid[]) AND is_read = FALSE`, userID, ids)
+	if err != nil {
+		return 0, apperr.Internal(fmt.Sprintf("mark notifications read failed: %v", err)).WithOp(opMarkReadBulk)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// DeleteBulk deletes the given notifications of a user and returns how many
+// were deleted.
+func (r *Repository) DeleteBulk(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if r == nil || r.pool == nil {
+		return 0, apperr.Internal(errRepoNotConfigured).WithOp(opDeleteBulk)
+	}
+	if userID == uuid.Nil {
+		return 0, apperr.Validation(errUserIDRequired).WithOp(opDeleteBulk)
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_in_app_notifications
+		WHERE user_id = $1 AND id = ANY($2::uuid[])`, userID, ids)
+	if err != nil {
+		return 0, apperr.Internal(fmt.Sprintf("delete notifications failed: %v", err)).WithOp(opDeleteBulk)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// DeleteRead clears all read notifications of a user and returns how many
+// were deleted.
+func (r *Repository) DeleteRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	if r == nil || r.pool == nil {
+		return 0, apperr.Internal(errRepoNotConfigured).WithOp(opDeleteRead)
+	}
+	if userID == uuid.Nil {
+		return 0, apperr.Validation(errUserIDRequired).WithOp(opDeleteRead)
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_in_app_notifications
+		WHERE user_id = $1 AND is_read = TRUE`, userID)
+	if err != nil {
+		return 0, apperr.Internal(fmt.Sprintf("delete read notifications failed: %v", err)).WithOp(opDeleteRead)
+	}
+	return int(tag.RowsAffected()), nil
+}
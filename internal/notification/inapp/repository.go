@@ -37,6 +37,10 @@ type Notification struct {
 	ResourceType *string    `json:"resourceType,omitempty"`
 	Category     string     `json:"category"`
 	IsRead       bool       `json:"isRead"`
+	GroupKey     *string    `json:"groupKey,omitempty"`
+	GroupCount   int        `json:"groupCount"`
+	SnoozedUntil *time.Time `json:"snoozedUntil,omitempty"`
+	SurfacedAt   time.Time  `json:"surfacedAt"`
 	CreatedAt    time.Time  `json:"createdAt"`
 }
 
@@ -48,6 +52,11 @@ type CreateParams struct {
 	ResourceID     *uuid.UUID
 	ResourceType   *string
 	Category       string
+	// GroupKey folds repeated notifications of the same kind into one unread
+	// notification per user and day. GroupTitle is the title of a grouped
+	// notification, with %d for the number of notifications in the group.
+	GroupKey   string
+	GroupTitle string
 }
 
 type Repository struct {
@@ -98,6 +107,8 @@ func notificationFromModel(model notificationdb.RacInAppNotification) Notificati
 		ResourceType: optionalString(model.ResourceType),
 		Category:     model.Category,
 		IsRead:       model.IsRead,
+		GroupCount:   1,
+		SurfacedAt:   model.CreatedAt.Time,
 		CreatedAt:    model.CreatedAt.Time,
 	}
 }
@@ -125,6 +136,10 @@ func (r *Repository) Create(ctx context.Context, p CreateParams) (Notification,
 	if category == "" {
 		category = "info"
 	}
+	if p.GroupKey != "" {
+		p.Category = category
+		return r.createGrouped(ctx, p)
+	}
 
 	model, err := r.queries.CreateInAppNotification(ctx, notificationdb.CreateInAppNotificationParams{
 		OrganizationID: toPgUUID(p.OrganizationID),
@@ -154,26 +169,7 @@ func (r *Repository) List(ctx context.Context, userID uuid.UUID, limit, offset i
 		return nil, 0, apperr.Validation(errUserIDRequired).WithOp(opList)
 	}
 
-	total, err := r.queries.CountInAppNotifications(ctx, toPgUUID(userID))
-	if err != nil {
-		return nil, 0, apperr.Internal(fmt.Sprintf("count notifications failed: %v", err)).WithOp(opList)
-	}
-
-	rows, err := r.queries.ListInAppNotifications(ctx, notificationdb.ListInAppNotificationsParams{
-		UserID:      toPgUUID(userID),
-		OffsetCount: int32(offset),
-		LimitCount:  int32(limit),
-	})
-	if err != nil {
-		return nil, 0, apperr.Internal(fmt.Sprintf("list notifications query failed: %v", err)).WithOp(opList)
-	}
-
-	items := make([]Notification, 0, limit)
-	for _, row := range rows {
-		items = append(items, notificationFromModel(row))
-	}
-
-	return items, int(total), nil
+	return r.listNotifications(ctx, userID, false, limit, offset)
 }
 
 func (r *Repository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
@@ -184,12 +180,18 @@ func (r *Repository) CountUnread(ctx context.Context, userID uuid.UUID) (int, er
 		return 0, apperr.Validation(errUserIDRequired).WithOp(opCountUnread)
 	}
 
-	count, err := r.queries.CountUnreadInAppNotifications(ctx, toPgUUID(userID))
+	var count int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM RAC_in_app_notifications
+		WHERE user_id = $1 AND is_read = FALSE AND `+visibleCondition,
+		userID,
+	).Scan(&count)
 	if err != nil {
 		return 0, apperr.Internal(fmt.Sprintf("count unread notifications failed: %v", err)).WithOp(opCountUnread)
 	}
 
-	return int(count), nil
+	return count, nil
 }
 
 func (r *Repository) CountUnreadByResourceTypes(ctx context.Context, userID uuid.UUID, resourceTypes []string) (int, error) {
@@ -203,15 +205,19 @@ func (r *Repository) CountUnreadByResourceTypes(ctx context.Context, userID uuid
 		return r.CountUnread(ctx, userID)
 	}
 
-	count, err := r.queries.CountUnreadInAppNotificationsByResourceTypes(ctx, notificationdb.CountUnreadInAppNotificationsByResourceTypesParams{
-		UserID:        toPgUUID(userID),
-		ResourceTypes: resourceTypes,
-	})
+	var count int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM RAC_in_app_notifications
+		WHERE user_id = $1 AND is_read = FALSE
+			AND resource_type = ANY($2::text[]) AND `+visibleCondition,
+		userID, resourceTypes,
+	).Scan(&count)
 	if err != nil {
 		return 0, apperr.Internal(fmt.Sprintf("count unread notifications by resource failed: %v", err)).WithOp(opCountUnreadByResource)
 	}
 
-	return int(count), nil
+	return count, nil
 }
 
 func (r *Repository) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
@@ -3,7 +3,9 @@ package inapp
 import (
 	"context"
 	"strings"
+	"time"
 
+	"portal_final_backend/internal/notification/outbox"
 	"portal_final_backend/internal/notification/sse"
 	"portal_final_backend/platform/apperr"
 	"portal_final_backend/platform/logger"
@@ -11,10 +13,23 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	// OutboxKind and OutboxTemplateResurface identify the outbox records that
+	// bring snoozed notifications back.
+	OutboxKind              = "inapp"
+	OutboxTemplateResurface = "inapp_resurface"
+
+	// MaxSnooze is how far ahead a notification can be snoozed.
+	MaxSnooze = 30 * 24 * time.Hour
+	// MaxBulkIDs bounds the number of notifications in one bulk action.
+	MaxBulkIDs = 100
+)
+
 type Service struct {
-	repo *Repository
-	sse  *sse.Service
-	log  *logger.Logger
+	repo   *Repository
+	sse    *sse.Service
+	outbox *outbox.Repository
+	log    *logger.Logger
 }
 
 func NewService(repo *Repository, log *logger.Logger) *Service {
@@ -29,6 +44,11 @@ func (s *Service) SetSSE(sseSvc *sse.Service) {
 	s.sse = sseSvc
 }
 
+// SetOutbox injects the outbox that resurfaces snoozed notifications.
+func (s *Service) SetOutbox(repo *outbox.Repository) {
+	s.outbox = repo
+}
+
 type SendParams struct {
 	OrgID        uuid.UUID
 	UserID       uuid.UUID
@@ -37,6 +57,10 @@ type SendParams struct {
 	ResourceID   *uuid.UUID
 	ResourceType string
 	Category     string // "info", "success", "warning", "error"
+	// GroupKey folds repeated notifications into one per day, titled with
+	// GroupTitle (a format with %d for the count), e.g. "%d offertes bekeken".
+	GroupKey   string
+	GroupTitle string
 }
 
 // Send persists the notification and pushes it via SSE if the user is online.
@@ -62,6 +86,8 @@ func (s *Service) Send(ctx context.Context, p SendParams) error {
 		ResourceID:     p.ResourceID,
 		ResourceType:   resourceType,
 		Category:       p.Category,
+		GroupKey:       p.GroupKey,
+		GroupTitle:     p.GroupTitle,
 	})
 	if err != nil {
 		if s.log != nil {
@@ -72,30 +98,46 @@ func (s *Service) Send(ctx context.Context, p SendParams) error {
 
 	if s.sse != nil {
 		s.sse.Publish(p.UserID, sse.Event{
-			Type:    "in_app_notification",
+			Type:    sse.EventInAppNotification,
 			Message: "New Notification",
 			Data:    notif,
 		})
 	}
+	s.publishUnreadCount(ctx, p.UserID)
 
 	return nil
 }
 
-func (s *Service) List(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]Notification, int, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 {
-		pageSize = 20
+// publishUnreadCount pushes the current unread count to the user's open
+// sessions so badges stay in sync across tabs and devices.
+func (s *Service) publishUnreadCount(ctx context.Context, userID uuid.UUID) {
+	if s.sse == nil {
+		return
 	}
-	if pageSize > 100 {
-		pageSize = 100
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		if s.log != nil {
+			s.log.Warn("failed to count unread in-app notifications", "error", err, "userId", userID)
+		}
+		return
 	}
+	s.sse.Publish(userID, sse.Event{
+		Type: sse.EventInAppUnreadCount,
+		Data: map[string]int{"count": count},
+	})
+}
 
+func (s *Service) List(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]Notification, int, error) {
+	page, pageSize = normalizePage(page, pageSize)
 	offset := (page - 1) * pageSize
 	return s.repo.List(ctx, userID, pageSize, offset)
 }
 
+func (s *Service) ListSnoozed(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]Notification, int, error) {
+	page, pageSize = normalizePage(page, pageSize)
+	return s.repo.ListSnoozed(ctx, userID, pageSize, (page-1)*pageSize)
+}
+
 func (s *Service) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
 	return s.repo.CountUnread(ctx, userID)
 }
@@ -113,13 +155,142 @@ func (s *Service) CountUnreadByResourceTypes(ctx context.Context, userID uuid.UU
 }
 
 func (s *Service) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
-	return s.repo.MarkRead(ctx, userID, id)
+	if err := s.repo.MarkRead(ctx, userID, id); err != nil {
+		return err
+	}
+	s.publishUnreadCount(ctx, userID)
+	return nil
 }
 
 func (s *Service) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
-	return s.repo.MarkAllRead(ctx, userID)
+	if err := s.repo.MarkAllRead(ctx, userID); err != nil {
+		return err
+	}
+	s.publishUnreadCount(ctx, userID)
+	return nil
 }
 
 func (s *Service) Delete(ctx context.Context, userID, id uuid.UUID) error {
-	return s.repo.Delete(ctx, userID, id)
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	s.publishUnreadCount(ctx, userID)
+	return nil
+}
+
+// MarkReadBulk marks the given notifications as read and returns how many
+// were unread.
+func (s *Service) MarkReadBulk(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if err := validateBulkIDs(ids); err != nil {
+		return 0, err
+	}
+	updated, err := s.repo.MarkReadBulk(ctx, userID, ids)
+	if err != nil {
+		return 0, err
+	}
+	s.publishUnreadCount(ctx, userID)
+	return updated, nil
+}
+
+// DeleteBulk deletes the given notifications and returns how many existed.
+func (s *Service) DeleteBulk(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if err := validateBulkIDs(ids); err != nil {
+		return 0, err
+	}
+	deleted, err := s.repo.DeleteBulk(ctx, userID, ids)
+	if err != nil {
+		return 0, err
+	}
+	s.publishUnreadCount(ctx, userID)
+	return deleted, nil
+}
+
+// ClearRead deletes all read notifications of the user.
+func (s *Service) ClearRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.DeleteRead(ctx, userID)
+}
+
+// Snooze hides a notification until the given time. The outbox brings it back
+// to the top of the feed once the snooze expires.
+func (s *Service) Snooze(ctx context.Context, userID, id uuid.UUID, until time.Time) error {
+	if err := validateSnoozeUntil(until, time.Now()); err != nil {
+		return err
+	}
+	if s.outbox == nil {
+		return apperr.Internal("in-app notification snooze not configured")
+	}
+
+	orgID, err := s.repo.Snooze(ctx, userID, id, until)
+	if err != nil {
+		return err
+	}
+	if _, err := s.outbox.Insert(ctx, outbox.InsertParams{
+		TenantID: orgID,
+		Kind:     OutboxKind,
+		Template: OutboxTemplateResurface,
+		Payload:  ResurfacePayload{NotificationID: id, UserID: userID},
+		RunAt:    until,
+	}); err != nil {
+		return apperr.Internal("failed to schedule notification resurface: " + err.Error())
+	}
+	s.publishUnreadCount(ctx, userID)
+	return nil
+}
+
+// ResurfacePayload is the outbox payload of a snoozed notification.
+type ResurfacePayload struct {
+	NotificationID uuid.UUID `json:"notificationId"`
+	UserID         uuid.UUID `json:"userId"`
+}
+
+// Resurface returns a notification whose snooze expired to the feed and
+// pushes it to the user as if it were new. Notifications that were deleted or
+// snoozed again in the meantime are left alone.
+func (s *Service) Resurface(ctx context.Context, p ResurfacePayload) error {
+	notif, ok, err := s.repo.Resurface(ctx, p.UserID, p.NotificationID)
+	if err != nil || !ok {
+		return err
+	}
+	if s.sse != nil {
+		s.sse.Publish(p.UserID, sse.Event{
+			Type:    sse.EventInAppNotification,
+			Message: "Snoozed Notification",
+			Data:    notif,
+		})
+	}
+	s.publishUnreadCount(ctx, p.UserID)
+	return nil
+}
+
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
+
+func validateBulkIDs(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return apperr.Validation("ids is required")
+	}
+	if len(ids) > MaxBulkIDs {
+		return apperr.Validation("at most 100 ids are allowed")
+	}
+	return nil
+}
+
+func validateSnoozeUntil(until, now time.Time) error {
+	if !until.After(now) {
+		return apperr.Validation("until must be in the future")
+	}
+	if until.Sub(now) > MaxSnooze {
+		return apperr.Validation("until must be within 30 days")
+	}
+	return nil
 }
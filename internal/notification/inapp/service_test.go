@@ -0,0 +1,64 @@
+package inapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateSnoozeUntil(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		until   time.Time
+		wantErr bool
+	}{
+		{name: "past", until: now.Add(-time.Minute), wantErr: true},
+		{name: "now", until: now, wantErr: true},
+		{name: "one hour", until: now.Add(time.Hour)},
+		{name: "thirty days", until: now.Add(MaxSnooze)},
+		{name: "too far", until: now.Add(MaxSnooze + time.Minute), wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSnoozeUntil(tc.until, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("validateSnoozeUntil() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBulkIDs(t *testing.T) {
+	if err := validateBulkIDs(nil); err == nil {
+		t.Fatal("expected error for empty ids")
+	}
+	if err := validateBulkIDs(make([]uuid.UUID, MaxBulkIDs+1)); err == nil {
+		t.Fatal("expected error for too many ids")
+	}
+	if err := validateBulkIDs([]uuid.UUID{uuid.New()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGroupTitle(t *testing.T) {
+	if got := groupTitle("%d offertes vandaag bekeken", "Offerte bekeken door klant", 1); got != "Offerte bekeken door klant" {
+		t.Fatalf("single notification title = %q", got)
+	}
+	if got := groupTitle("%d offertes vandaag bekeken", "Offerte bekeken door klant", 3); got != "3 offertes vandaag bekeken" {
+		t.Fatalf("grouped title = %q", got)
+	}
+	if got := groupTitle("", "Offerte bekeken door klant", 3); got != "Offerte bekeken door klant" {
+		t.Fatalf("title without format = %q", got)
+	}
+}
+
+func TestStartOfDayUsesAmsterdamDay(t *testing.T) {
+	// 23:30 UTC on 10 March is already 11 March in Amsterdam.
+	got := startOfDay(time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC))
+	want := time.Date(2026, 3, 11, 0, 0, 0, 0, groupingLocation)
+	if !got.Equal(want) {
+		t.Fatalf("startOfDay() = %v, want %v", got, want)
+	}
+}
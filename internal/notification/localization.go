@@ -0,0 +1,171 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"text/template"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	"portal_final_backend/platform/i18n"
+
+	"github.com/google/uuid"
+)
+
+// LeadLanguageReader provides the preferred notification language of a lead.
+type LeadLanguageReader interface {
+	GetPreferredLanguage(ctx context.Context, id uuid.UUID, organizationID uuid.UUID) (*string, error)
+}
+
+// OrganizationLanguageReader provides the default notification language of
+// an organization.
+type OrganizationLanguageReader interface {
+	GetOrganizationDefaultLanguage(ctx context.Context, organizationID uuid.UUID) (string, error)
+}
+
+// SetLeadLanguageReader injects the reader for lead notification languages.
+func (m *Module) SetLeadLanguageReader(reader LeadLanguageReader) {
+	m.leadLanguageReader = reader
+}
+
+// SetOrganizationLanguageReader injects the reader for organization default
+// languages.
+func (m *Module) SetOrganizationLanguageReader(reader OrganizationLanguageReader) {
+	m.orgLanguageReader = reader
+}
+
+// resolveLanguage returns the language a message to audience is sent in.
+// Messages to the lead use the lead's preferred language; every audience
+// falls back to the organization default and then to Dutch.
+func (m *Module) resolveLanguage(ctx context.Context, orgID uuid.UUID, leadID *uuid.UUID, audience string) string {
+	candidates := make([]string, 0, 2)
+	if leadID != nil && m.leadLanguageReader != nil && strings.EqualFold(strings.TrimSpace(audience), "lead") {
+		language, err := m.leadLanguageReader.GetPreferredLanguage(ctx, *leadID, orgID)
+		if err != nil {
+			m.log.Warn("failed to load lead language", "orgId", orgID, "leadId", *leadID, "error", err)
+		} else if language != nil {
+			candidates = append(candidates, *language)
+		}
+	}
+	if m.orgLanguageReader != nil {
+		language, err := m.orgLanguageReader.GetOrganizationDefaultLanguage(ctx, orgID)
+		if err != nil {
+			m.log.Warn("failed to load organization default language", "orgId", orgID, "error", err)
+		} else {
+			candidates = append(candidates, language)
+		}
+	}
+	return i18n.Resolve(candidates...)
+}
+
+// localizedStepTemplates returns the subject and body of step in language,
+// falling back per field to the step's base templates.
+func localizedStepTemplates(step repository.WorkflowStep, language string) (subject *string, body *string) {
+	subject, body = step.TemplateSubject, step.TemplateBody
+	translation, ok := step.TemplateTranslations[language]
+	if !ok {
+		return subject, body
+	}
+	if translation.TemplateSubject != nil && strings.TrimSpace(*translation.TemplateSubject) != "" {
+		subject = translation.TemplateSubject
+	}
+	if translation.TemplateBody != nil && strings.TrimSpace(*translation.TemplateBody) != "" {
+		body = translation.TemplateBody
+	}
+	return subject, body
+}
+
+// localizeTemplateVars reformats the preformatted amounts and dates of vars
+// in language, using the raw cents and times events provide next to them.
+func localizeTemplateVars(vars map[string]any, language string) map[string]any {
+	vars["locale"] = language
+	if quote, ok := vars["quote"].(map[string]any); ok {
+		if cents, ok := templateCents(quote["totalCents"]); ok {
+			formatted := i18n.FormatCurrencyCents(cents, language)
+			quote["total"] = formatted
+			quote["totalFormatted"] = formatted
+		}
+	}
+	if installment, ok := vars["installment"].(map[string]any); ok {
+		if cents, ok := templateCents(installment["amountCents"]); ok {
+			installment["amount"] = i18n.FormatCurrencyCents(cents, language)
+		}
+		if dueAt, ok := installment["dueAt"].(time.Time); ok && !dueAt.IsZero() {
+			installment["dueDate"] = i18n.FormatDate(dueAt, language)
+		}
+	}
+	if offer, ok := vars["offer"].(map[string]any); ok {
+		if cents, ok := templateCents(offer["priceCents"]); ok {
+			formatted := i18n.FormatCurrencyCents(cents, language)
+			offer["price"] = formatted
+			offer["priceFormatted"] = formatted
+		}
+	}
+	if appointment, ok := vars["appointment"].(map[string]any); ok {
+		if startsAt, ok := appointment["startsAt"].(time.Time); ok && !startsAt.IsZero() {
+			appointment["date"] = i18n.FormatDate(startsAt, language)
+			appointment["time"] = i18n.FormatTime(startsAt, language)
+		}
+	}
+	return vars
+}
+
+// localizedTemplateFuncs lets templates format raw values themselves, e.g.
+// {{formatCurrency .quote.totalCents}} or {{formatDate .appointment.startsAt}}.
+func localizedTemplateFuncs(language string) template.FuncMap {
+	return template.FuncMap{
+		"formatCurrency": func(value any) string {
+			cents, ok := templateCents(value)
+			if !ok {
+				return ""
+			}
+			return i18n.FormatCurrencyCents(cents, language)
+		},
+		"formatDate": func(value any) string {
+			t, ok := templateTime(value)
+			if !ok {
+				return ""
+			}
+			return i18n.FormatDate(t, language)
+		},
+		"formatTime": func(value any) string {
+			t, ok := templateTime(value)
+			if !ok {
+				return ""
+			}
+			return i18n.FormatTime(t, language)
+		},
+	}
+}
+
+func templateCents(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func templateTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case *time.Time:
+		if v == nil || v.IsZero() {
+			return time.Time{}, false
+		}
+		return *v, true
+	case string:
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(v))
+		return t, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
@@ -0,0 +1,72 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+)
+
+func TestLocalizedStepTemplatesFallsBackPerField(t *testing.T) {
+	subject := "Uw offerte"
+	body := "Beste {{lead.name}}"
+	englishBody := "Dear {{lead.name}}"
+	step := repository.WorkflowStep{
+		TemplateSubject: &subject,
+		TemplateBody:    &body,
+		TemplateTranslations: map[string]repository.WorkflowStepTranslation{
+			"en": {TemplateBody: &englishBody},
+		},
+	}
+
+	gotSubject, gotBody := localizedStepTemplates(step, "en")
+	if gotSubject != &subject || gotBody != &englishBody {
+		t.Fatalf("expected base subject and english body, got %q / %q", *gotSubject, *gotBody)
+	}
+	gotSubject, gotBody = localizedStepTemplates(step, "de")
+	if gotSubject != &subject || gotBody != &body {
+		t.Fatalf("expected base templates for missing translation, got %q / %q", *gotSubject, *gotBody)
+	}
+}
+
+func TestRenderStepTemplateFormatsAmountsAndDatesForLanguage(t *testing.T) {
+	tpl := "{{lead.name}}: {{quote.total}} on {{appointment.date}} at {{appointment.time}} ({{locale}})"
+	startsAt := time.Date(2026, time.April, 9, 14, 30, 0, 0, time.UTC)
+	vars := func() map[string]any {
+		vars := buildAppointmentTemplateVars("Robin", "", "", "09-04-2026", "14:30", "", testOrgName, startsAt)
+		vars["quote"] = map[string]any{"totalCents": int64(249500), "total": "€2495,00"}
+		return vars
+	}
+
+	english, err := renderStepTemplate(&tpl, vars(), "en")
+	if err != nil {
+		t.Fatalf("render english: %v", err)
+	}
+	if want := "Robin: €2,495.00 on 09/04/2026 at 14:30 (en)"; english != want {
+		t.Fatalf("english = %q, want %q", english, want)
+	}
+
+	dutch, err := renderStepTemplate(&tpl, vars(), "")
+	if err != nil {
+		t.Fatalf("render dutch: %v", err)
+	}
+	if want := "Robin: €2.495,00 on 09-04-2026 at 14:30 (nl)"; dutch != want {
+		t.Fatalf("dutch = %q, want %q", dutch, want)
+	}
+}
+
+func TestRenderStepTemplateFormatFuncs(t *testing.T) {
+	tpl := `{{formatCurrency .offer.priceCents}} / {{formatDate .appointment.startTime}}`
+	vars := map[string]any{
+		"offer":       map[string]any{"priceCents": int64(85000)},
+		"appointment": map[string]any{"startTime": "2026-04-09T08:00:00Z"},
+	}
+
+	rendered, err := renderStepTemplate(&tpl, vars, "de")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if want := "850,00 € / 09.04.2026"; rendered != want {
+		t.Fatalf("rendered = %q, want %q", rendered, want)
+	}
+}
@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"portal_final_backend/internal/auth/token"
+	leadrepo "portal_final_backend/internal/leads/repository"
+	notificationoutbox "portal_final_backend/internal/notification/outbox"
+
+	"github.com/google/uuid"
+)
+
+const (
+	marketingConfirmationTokenBytes = 32
+	// marketingConfirmationResendAfter keeps a lead that ignores the request
+	// from being asked again with every withheld message.
+	marketingConfirmationResendAfter = 30 * 24 * time.Hour
+
+	marketingConfirmationSubject = "Bevestig uw aanmelding voor aanbiedingen en nieuws"
+	marketingConfirmationSummary = "Bevestiging marketingtoestemming gevraagd"
+)
+
+// LeadMarketingConfirmationStore issues the double opt-in requests of leads
+// for marketing messages.
+type LeadMarketingConfirmationStore interface {
+	RequestMarketingConfirmation(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID, token string, resendBefore time.Time) (bool, error)
+}
+
+// SetLeadMarketingConfirmationStore injects the store for double opt-in
+// requests.
+func (m *Module) SetLeadMarketingConfirmationStore(store LeadMarketingConfirmationStore) {
+	m.marketingConfirmer = store
+}
+
+// requestMarketingConfirmation asks a lead whose marketing message was
+// withheld to confirm its marketing consent, over the channel of that
+// message. Leads asked recently are not asked again.
+func (m *Module) requestMarketingConfirmation(ctx context.Context, leadID uuid.UUID, orgID uuid.UUID, channel string, recipient string) {
+	if m.marketingConfirmer == nil || m.notificationOutbox == nil {
+		return
+	}
+	confirmationToken, err := token.GenerateRandomToken(marketingConfirmationTokenBytes)
+	if err != nil {
+		m.log.Error("failed to generate marketing confirmation token", "leadId", leadID, "error", err)
+		return
+	}
+	requested, err := m.marketingConfirmer.RequestMarketingConfirmation(ctx, leadID, orgID, confirmationToken, time.Now().Add(-marketingConfirmationResendAfter))
+	if err != nil {
+		m.log.Error("failed to request marketing confirmation", "leadId", leadID, "orgId", orgID, "error", err)
+		return
+	}
+	if !requested {
+		return
+	}
+
+	firstName := ""
+	if details := m.resolveLeadDetails(ctx, leadID, orgID); details != nil {
+		firstName = details.FirstName
+	}
+	link := m.buildPublicURL(ctx, orgID, "/marketing-confirmation", confirmationToken)
+	params := notificationoutbox.InsertParams{
+		TenantID: orgID,
+		LeadID:   &leadID,
+		RunAt:    time.Now().UTC(),
+	}
+	switch channel {
+	case leadrepo.ConsentEmail:
+		params.Kind = "email"
+		params.Template = "email_send"
+		params.Payload = emailSendOutboxPayload{
+			OrgID:    orgID.String(),
+			ToEmail:  recipient,
+			Subject:  marketingConfirmationSubject,
+			BodyHTML: buildMarketingConfirmationEmail(firstName, link),
+			LeadID:   ptrUUIDString(&leadID),
+			Audience: "lead",
+			Purpose:  leadrepo.ConsentTransactional,
+		}
+	case leadrepo.ConsentWhatsApp:
+		params.Kind = "whatsapp"
+		params.Template = "whatsapp_send"
+		params.Payload = whatsAppSendOutboxPayload{
+			OrgID:       orgID.String(),
+			LeadID:      ptrUUIDString(&leadID),
+			PhoneNumber: recipient,
+			Message:     buildMarketingConfirmationWhatsApp(firstName, link),
+			Category:    "marketing_confirmation",
+			Audience:    "lead",
+			Purpose:     leadrepo.ConsentTransactional,
+			Summary:     marketingConfirmationSummary,
+			ActorType:   "System",
+			ActorName:   "Portaal",
+		}
+	default:
+		return
+	}
+
+	rec, err := m.notificationOutbox.Insert(ctx, params)
+	if err != nil {
+		m.log.Error("failed to enqueue marketing confirmation", "leadId", leadID, "orgId", orgID, "channel", channel, "error", err)
+		return
+	}
+	if channel == leadrepo.ConsentEmail && m.leadTimeline != nil {
+		summary := "Klant gevraagd de aanmelding voor aanbiedingen en nieuws per e-mail te bevestigen"
+		_ = m.leadTimeline.CreateTimelineEvent(ctx, LeadTimelineEventParams{
+			LeadID:    leadID,
+			OrgID:     orgID,
+			ActorType: "System",
+			ActorName: "Portaal",
+			EventType: "marketing_confirmation_requested",
+			Title:     marketingConfirmationSummary,
+			Summary:   &summary,
+		})
+	}
+	m.log.Info("marketing confirmation enqueued", "outboxId", rec.String(), "leadId", leadID, "orgId", orgID, "channel", channel)
+}
+
+func buildMarketingConfirmationWhatsApp(firstName, link string) string {
+	return fmt.Sprintf("%s\n\nWilt u ook aanbiedingen en nieuws van ons ontvangen? Antwoord dan met JA of bevestig via deze link:\n%s\n\nZonder bevestiging sturen wij u alleen berichten over uw aanvraag.", leadPortalGreeting(firstName), link)
+}
+
+func buildMarketingConfirmationEmail(firstName, link string) string {
+	return fmt.Sprintf(
+		`<p>%s</p><p>Wilt u ook aanbiedingen en nieuws van ons ontvangen? Bevestig dat dan via onderstaande link.</p><p><a href="%s">Ja, ik wil aanbiedingen en nieuws ontvangen</a></p><p>Zonder bevestiging sturen wij u alleen berichten over uw aanvraag.</p>`,
+		html.EscapeString(leadPortalGreeting(firstName)), html.EscapeString(link),
+	)
+}
@@ -0,0 +1,195 @@
+package notification
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"portal_final_backend/internal/identity/repository"
+	notificationoutbox "portal_final_backend/internal/notification/outbox"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxPolicyScheduleSteps bounds the search for a send time that satisfies
+	// quiet hours and caps; each step moves the candidate forward.
+	maxPolicyScheduleSteps = 500
+	// maxPolicyLookahead is how far ahead already scheduled messages are
+	// considered when placing a new one.
+	maxPolicyLookahead = 30 * 24 * time.Hour
+
+	msgMessagingPolicyDeferred = "deferred by messaging policy"
+)
+
+// policyAppliesTo reports whether a policy limits messages of an outbox kind.
+func policyAppliesTo(policy repository.OrganizationMessagingPolicy, kind string) bool {
+	if !policy.QuietHoursEnabled && policy.MaxMessagesPerLeadPerHour == nil && policy.MaxMessagesPerLeadPerDay == nil {
+		return false
+	}
+	switch kind {
+	case "whatsapp":
+		return true
+	case "email":
+		return policy.IncludeEmail
+	default:
+		return false
+	}
+}
+
+func policyKinds(policy repository.OrganizationMessagingPolicy) []string {
+	if policy.IncludeEmail {
+		return []string{"whatsapp", "email"}
+	}
+	return []string{"whatsapp"}
+}
+
+func policyLocation(policy repository.OrganizationMessagingPolicy) *time.Location {
+	loc, err := time.LoadLocation(policy.Timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(repository.DefaultMessagingTimezone)
+	}
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// quietHoursEnd returns when the quiet hours containing t end, and false when
+// t is outside quiet hours. The window may wrap past midnight.
+func quietHoursEnd(t time.Time, policy repository.OrganizationMessagingPolicy) (time.Time, bool) {
+	if !policy.QuietHoursEnabled || policy.QuietHoursStart == policy.QuietHoursEnd {
+		return time.Time{}, false
+	}
+	local := t.In(policyLocation(policy))
+	minute := local.Hour()*60 + local.Minute()
+	start, end := policy.QuietHoursStart, policy.QuietHoursEnd
+
+	var inQuiet bool
+	if start < end {
+		inQuiet = minute >= start && minute < end
+	} else {
+		inQuiet = minute >= start || minute < end
+	}
+	if !inQuiet {
+		return time.Time{}, false
+	}
+
+	endAt := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+	if !endAt.After(local) {
+		endAt = time.Date(local.Year(), local.Month(), local.Day()+1, end/60, end%60, 0, 0, local.Location())
+	}
+	return endAt.UTC(), true
+}
+
+// capReleaseTime returns when a message at t fits the cap of limit messages
+// per window, given the sorted run times of the lead's other messages.
+func capReleaseTime(t time.Time, existing []time.Time, limit *int, window time.Duration) (time.Time, bool) {
+	if limit == nil || *limit <= 0 {
+		return time.Time{}, false
+	}
+	var inWindow []time.Time
+	for _, at := range existing {
+		if at.After(t.Add(-window)) && !at.After(t) {
+			inWindow = append(inWindow, at)
+		}
+	}
+	if len(inWindow) < *limit {
+		return time.Time{}, false
+	}
+	// The window has room once enough of its oldest messages drop out.
+	return inWindow[len(inWindow)-*limit].Add(window), true
+}
+
+// nextAllowedSendTime returns the first moment at or after runAt outside
+// quiet hours with room under the caps. existing are the run times of the
+// lead's other messages.
+func nextAllowedSendTime(runAt time.Time, policy repository.OrganizationMessagingPolicy, existing []time.Time) time.Time {
+	sorted := append([]time.Time(nil), existing...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	candidate := runAt.UTC()
+	for i := 0; i < maxPolicyScheduleSteps; i++ {
+		if end, ok := quietHoursEnd(candidate, policy); ok {
+			candidate = end
+			continue
+		}
+		if release, ok := capReleaseTime(candidate, sorted, policy.MaxMessagesPerLeadPerHour, time.Hour); ok {
+			candidate = release
+			continue
+		}
+		if release, ok := capReleaseTime(candidate, sorted, policy.MaxMessagesPerLeadPerDay, 24*time.Hour); ok {
+			candidate = release
+			continue
+		}
+		return candidate
+	}
+	return candidate
+}
+
+func (m *Module) messagingPolicy(ctx context.Context, orgID uuid.UUID) (repository.OrganizationMessagingPolicy, bool) {
+	if m.policyReader == nil {
+		return repository.OrganizationMessagingPolicy{}, false
+	}
+	policy, err := m.policyReader.GetOrganizationMessagingPolicy(ctx, orgID)
+	if err != nil {
+		m.log.Warn("failed to load messaging policy", "orgId", orgID, "error", err)
+		return repository.OrganizationMessagingPolicy{}, false
+	}
+	return policy, true
+}
+
+// scheduleWithinMessagingPolicy moves the run time of a new outbox message
+// out of quiet hours and past the lead's frequency caps.
+func (m *Module) scheduleWithinMessagingPolicy(ctx context.Context, orgID uuid.UUID, leadID *uuid.UUID, kind string, runAt time.Time) time.Time {
+	policy, ok := m.messagingPolicy(ctx, orgID)
+	if !ok || !policyAppliesTo(policy, kind) {
+		return runAt
+	}
+
+	var existing []time.Time
+	if leadID != nil && (policy.MaxMessagesPerLeadPerHour != nil || policy.MaxMessagesPerLeadPerDay != nil) {
+		times, err := m.notificationOutbox.ListLeadRunTimes(ctx, orgID, *leadID, policyKinds(policy), runAt.Add(-24*time.Hour), runAt.Add(maxPolicyLookahead), uuid.Nil)
+		if err != nil {
+			m.log.Warn("failed to list lead messages for frequency cap", "orgId", orgID, "leadId", *leadID, "error", err)
+		}
+		existing = times
+	}
+
+	scheduled := nextAllowedSendTime(runAt, policy, existing)
+	if scheduled.After(runAt) {
+		m.log.Info("outbox message deferred by messaging policy", "orgId", orgID, "kind", kind, "requestedRunAt", runAt, "runAt", scheduled)
+	}
+	return scheduled
+}
+
+// deferForMessagingPolicy re-checks quiet hours and caps when a message is
+// due and moves it to the next allowed time instead of sending it. It reports
+// whether the record was deferred.
+func (m *Module) deferForMessagingPolicy(ctx context.Context, rec notificationoutbox.Record) bool {
+	policy, ok := m.messagingPolicy(ctx, rec.TenantID)
+	if !ok || !policyAppliesTo(policy, rec.Kind) {
+		return false
+	}
+
+	now := time.Now().UTC()
+	var sent []time.Time
+	if rec.LeadID != nil && (policy.MaxMessagesPerLeadPerHour != nil || policy.MaxMessagesPerLeadPerDay != nil) {
+		times, err := m.notificationOutbox.ListLeadRunTimes(ctx, rec.TenantID, *rec.LeadID, policyKinds(policy), now.Add(-24*time.Hour), now.Add(time.Second), rec.ID)
+		if err != nil {
+			m.log.Warn("failed to list lead messages for frequency cap", "outboxId", rec.ID.String(), "error", err)
+		}
+		sent = times
+	}
+
+	next := nextAllowedSendTime(now, policy, sent)
+	if !next.After(now) {
+		return false
+	}
+	if err := m.notificationOutbox.Defer(ctx, rec.ID, next, msgMessagingPolicyDeferred); err != nil {
+		m.log.Error("failed to defer outbox record", "outboxId", rec.ID.String(), "error", err)
+		return false
+	}
+	m.log.Info("outbox record deferred by messaging policy", "outboxId", rec.ID.String(), "kind", rec.Kind, "runAt", next)
+	return true
+}
@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	identityrepo "portal_final_backend/internal/identity/repository"
+)
+
+func TestNextAllowedSendTimeDefersQuietHoursPastMidnight(t *testing.T) {
+	policy := identityrepo.OrganizationMessagingPolicy{
+		QuietHoursEnabled: true,
+		QuietHoursStart:   21 * 60,
+		QuietHoursEnd:     8 * 60,
+		Timezone:          "Europe/Amsterdam",
+	}
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	late := time.Date(2026, time.March, 2, 23, 0, 0, 0, loc)
+	want := time.Date(2026, time.March, 3, 8, 0, 0, 0, loc)
+	if got := nextAllowedSendTime(late, policy, nil); !got.Equal(want) {
+		t.Fatalf("expected 23:00 to move to %s, got %s", want, got.In(loc))
+	}
+
+	early := time.Date(2026, time.March, 3, 6, 30, 0, 0, loc)
+	if got := nextAllowedSendTime(early, policy, nil); !got.Equal(want) {
+		t.Fatalf("expected 06:30 to move to %s, got %s", want, got.In(loc))
+	}
+
+	daytime := time.Date(2026, time.March, 3, 14, 0, 0, 0, loc)
+	if got := nextAllowedSendTime(daytime, policy, nil); !got.Equal(daytime) {
+		t.Fatalf("expected daytime send to stay at %s, got %s", daytime, got.In(loc))
+	}
+}
+
+func TestNextAllowedSendTimeDefersPastHourlyCap(t *testing.T) {
+	hourly := 2
+	policy := identityrepo.OrganizationMessagingPolicy{
+		MaxMessagesPerLeadPerHour: &hourly,
+		Timezone:                  "UTC",
+	}
+	base := time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC)
+	existing := []time.Time{base.Add(20 * time.Minute), base}
+
+	got := nextAllowedSendTime(base.Add(30*time.Minute), policy, existing)
+	if want := base.Add(time.Hour); !got.Equal(want) {
+		t.Fatalf("expected overflow message deferred to %s, got %s", want, got)
+	}
+
+	if got := nextAllowedSendTime(base.Add(90*time.Minute), policy, existing); !got.Equal(base.Add(90 * time.Minute)) {
+		t.Fatalf("expected message outside the window to keep its run time, got %s", got)
+	}
+}
+
+func TestPolicyAppliesToEmailOnlyWhenIncluded(t *testing.T) {
+	daily := 3
+	policy := identityrepo.OrganizationMessagingPolicy{MaxMessagesPerLeadPerDay: &daily}
+	if !policyAppliesTo(policy, "whatsapp") {
+		t.Fatal("expected policy to apply to whatsapp")
+	}
+	if policyAppliesTo(policy, "email") {
+		t.Fatal("expected policy to skip email unless included")
+	}
+	policy.IncludeEmail = true
+	if !policyAppliesTo(policy, "email") {
+		t.Fatal("expected policy to apply to email when included")
+	}
+}
@@ -3,7 +3,6 @@ package notification
 import (
 	"context"
 	"errors"
-	"fmt"
 	"regexp"
 	"strings"
 	"sync"
@@ -20,8 +19,13 @@ import (
 	"portal_final_backend/internal/notification/inapp"
 	notificationoutbox "portal_final_backend/internal/notification/outbox"
 	"portal_final_backend/internal/notification/sse"
+	"portal_final_backend/internal/notification/shortlink"
+	"portal_final_backend/internal/notification/suppression"
+	"portal_final_backend/internal/notification/tracking"
 	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/i18n"
 	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/secrets"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -32,6 +36,16 @@ type OrganizationSettingsReader interface {
 	GetOrganizationSettings(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationSettings, error)
 }
 
+// MessagingPolicyReader provides the quiet hours and frequency caps of an organization.
+type MessagingPolicyReader interface {
+	GetOrganizationMessagingPolicy(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationMessagingPolicy, error)
+}
+
+// BrandingReader provides the email branding of an organization.
+type BrandingReader interface {
+	GetOrganizationBranding(ctx context.Context, organizationID uuid.UUID) (repository.OrganizationBranding, error)
+}
+
 // UserTenancyReader resolves organization membership for users.
 type UserTenancyReader interface {
 	GetUserOrganizationID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
@@ -65,15 +79,27 @@ type Module struct {
 	whatsAppInboxWriter WhatsAppInboxWriter
 	leadTimeline        LeadTimelineWriter
 	settingsReader      OrganizationSettingsReader
+	policyReader        MessagingPolicyReader
+	brandingReader      BrandingReader
+	emailTemplateReader EmailTemplateReader
+	leadLanguageReader  LeadLanguageReader
+	orgLanguageReader   OrganizationLanguageReader
 	tenancyReader       UserTenancyReader
 	workflowResolver    WorkflowResolver
 	leadWhatsAppReader  LeadWhatsAppReader
+	leadConsentReader   LeadConsentReader
+	marketingConfirmer  LeadMarketingConfirmationStore
 	orgMemberReader     OrganizationMemberReader
 	leadAssigneeReader  LeadAssigneeReader
 	notificationOutbox  *notificationoutbox.Repository
 	inAppService        *inapp.Service
 	inAppHandler        *notifhandler.HTTPHandler
-	smtpEncryptionKey   []byte
+	suppressions        *suppression.Service
+	shortLinks          *shortlink.Service
+	emailTracking       *tracking.Service
+	triageRecorder      LeadTriageRecorder
+	portalDomains       PortalDomainResolver
+	smtpKeyring         *secrets.Keyring
 	senderCache         sync.Map // map[uuid.UUID]cachedSender
 	orgNameCache        sync.Map // map[uuid.UUID]cachedOrgName
 	quoteViewedDebounce sync.Map // map[uuid.UUID]time.Time
@@ -85,11 +111,17 @@ func New(pool *pgxpool.Pool, sender email.Sender, cfg config.NotificationConfig,
 	inAppRepo := inapp.NewRepository(pool)
 	inAppSvc := inapp.NewService(inAppRepo, log)
 	var queries *notificationdb.Queries
+	var suppressions *suppression.Service
+	var shortLinks *shortlink.Service
+	var emailTracking *tracking.Service
 	if pool != nil {
 		queries = notificationdb.New(pool)
+		suppressions = suppression.NewService(suppression.NewRepository(pool))
+		shortLinks = shortlink.NewService(shortlink.NewRepository(pool))
+		emailTracking = tracking.NewService(tracking.NewRepository(pool))
 	}
 
-	return &Module{
+	m := &Module{
 		pool:          pool,
 		sender:        sender,
 		cfg:           cfg,
@@ -98,7 +130,17 @@ func New(pool *pgxpool.Pool, sender email.Sender, cfg config.NotificationConfig,
 		subsidyPDFGen: subsidyPDFGeneratorFunc(generateISDESubsidyPDF),
 		inAppService:  inAppSvc,
 		inAppHandler:  notifhandler.NewHTTPHandler(inAppSvc),
+		suppressions:  suppressions,
+		shortLinks:    shortLinks,
+		emailTracking: emailTracking,
 	}
+	if shortLinks != nil {
+		shortLinks.SetClickRecorder(m)
+	}
+	if emailTracking != nil {
+		emailTracking.SetEngagementRecorder(m)
+	}
+	return m
 }
 
 // Name returns the module identifier.
@@ -112,6 +154,17 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 
 	notifications := ctx.Protected.Group("/notifications")
 	m.inAppHandler.RegisterRoutes(notifications)
+	if m.suppressions != nil {
+		notifhandler.NewSuppressionHandler(m.suppressions).RegisterRoutes(ctx.Admin.Group("/notification-suppressions"))
+	}
+	if m.shortLinks != nil {
+		notifhandler.NewShortLinkHandler(m.shortLinks).RegisterRoutes(ctx.Public.Group("/s"))
+	}
+	if m.emailTracking != nil {
+		trackingHandler := notifhandler.NewEmailTrackingHandler(m.emailTracking)
+		trackingHandler.RegisterPublicRoutes(ctx.Public.Group("/email-tracking"))
+		trackingHandler.RegisterRoutes(ctx.Admin.Group("/notification-engagement"))
+	}
 }
 
 // SetSSE injects the SSE service so quote events can be pushed to agents.
@@ -144,6 +197,16 @@ func (m *Module) SetOrganizationSettingsReader(reader OrganizationSettingsReader
 	m.settingsReader = reader
 }
 
+// SetMessagingPolicyReader injects the reader for quiet hours and frequency caps.
+func (m *Module) SetMessagingPolicyReader(reader MessagingPolicyReader) {
+	m.policyReader = reader
+}
+
+// SetBrandingReader injects the reader for organization email branding.
+func (m *Module) SetBrandingReader(reader BrandingReader) {
+	m.brandingReader = reader
+}
+
 // SetUserTenancyReader injects user-to-organization lookup for in-app fanout.
 func (m *Module) SetUserTenancyReader(reader UserTenancyReader) {
 	m.tenancyReader = reader
@@ -186,12 +249,18 @@ func (m *Module) SetQuoteAcceptedPDFScheduler(scheduler QuoteAcceptedPDFSchedule
 // SetSubsidyPDFGenerator injects the generator for ISDE subsidy summary attachments.
 func (m *Module) SetSubsidyPDFGenerator(gen SubsidyPDFGenerator) { m.subsidyPDFGen = gen }
 
-// SetSMTPEncryptionKey sets the AES key used to decrypt SMTP passwords from org settings.
-func (m *Module) SetSMTPEncryptionKey(key []byte) { m.smtpEncryptionKey = key }
+// SetSMTPEncryptionKey sets a single legacy AES key used to decrypt SMTP passwords from org settings.
+func (m *Module) SetSMTPEncryptionKey(key []byte) { m.smtpKeyring = secrets.SingleKeyring(key) }
+
+// SetSMTPKeyring sets the keyring used to decrypt SMTP passwords from org settings.
+func (m *Module) SetSMTPKeyring(keyring *secrets.Keyring) { m.smtpKeyring = keyring }
 
 // SetNotificationOutbox injects the notification outbox repository.
 func (m *Module) SetNotificationOutbox(repo *notificationoutbox.Repository) {
 	m.notificationOutbox = repo
+	if m.inAppService != nil {
+		m.inAppService.SetOutbox(repo)
+	}
 }
 
 // RegisterHandlers subscribes to all relevant domain events on the event bus.
@@ -200,29 +269,47 @@ func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
 	bus.Subscribe(events.UserSignedUp{}.EventName(), m)
 	bus.Subscribe(events.EmailVerificationRequested{}.EventName(), m)
 	bus.Subscribe(events.PasswordResetRequested{}.EventName(), m)
+	bus.Subscribe(events.AccountLockedOut{}.EventName(), m)
+	bus.Subscribe(events.NewSignInDetected{}.EventName(), m)
 
 	bus.Subscribe(events.OrganizationInviteCreated{}.EventName(), m)
 
 	bus.Subscribe(events.PartnerInviteCreated{}.EventName(), m)
+	bus.Subscribe(events.PartnerApplicationSubmitted{}.EventName(), m)
+	bus.Subscribe(events.KvKCompanyDissolved{}.EventName(), m)
+	bus.Subscribe(events.CatalogPriceJumpsDetected{}.EventName(), m)
+	bus.Subscribe(events.CatalogLowStockDetected{}.EventName(), m)
 	bus.Subscribe(events.PartnerOfferCreated{}.EventName(), m)
 	bus.Subscribe(events.PartnerOfferAccepted{}.EventName(), m)
 	bus.Subscribe(events.PartnerOfferRejected{}.EventName(), m)
 	bus.Subscribe(events.PartnerOfferExpired{}.EventName(), m)
+	bus.Subscribe(events.WorkOrderCompleted{}.EventName(), m)
+	bus.Subscribe(events.WarrantyClaimSubmitted{}.EventName(), m)
+	bus.Subscribe(events.WarrantyExpiring{}.EventName(), m)
 
 	bus.Subscribe(events.LeadCreated{}.EventName(), m)
 	bus.Subscribe(events.LeadAssigned{}.EventName(), m)
+	bus.Subscribe(events.LeadsHandedOver{}.EventName(), m)
 	bus.Subscribe(events.LeadDataChanged{}.EventName(), m)
+	bus.Subscribe(events.LeadPortalLinkRequested{}.EventName(), m)
+	bus.Subscribe(events.LeadChannelConsentChanged{}.EventName(), m)
 	bus.Subscribe(events.PipelineStageChanged{}.EventName(), m)
 	bus.Subscribe(events.ManualInterventionRequired{}.EventName(), m)
+	bus.Subscribe(events.LeadStale{}.EventName(), m)
 
 	bus.Subscribe(events.QuoteSent{}.EventName(), m)
 	bus.Subscribe(events.QuoteViewed{}.EventName(), m)
+	bus.Subscribe(events.QuoteApprovalRequested{}.EventName(), m)
+	bus.Subscribe(events.QuoteApprovalDecided{}.EventName(), m)
 	bus.Subscribe(events.QuoteUpdatedByCustomer{}.EventName(), m)
 	bus.Subscribe(events.QuoteAnnotated{}.EventName(), m)
 	bus.Subscribe(events.QuoteAccepted{}.EventName(), m)
 	bus.Subscribe(events.QuoteRejected{}.EventName(), m)
+	bus.Subscribe(events.QuoteInstallmentDue{}.EventName(), m)
+	bus.Subscribe(events.QuotePaymentUpdated{}.EventName(), m)
 
 	bus.Subscribe(events.AppointmentCreated{}.EventName(), m)
+	bus.Subscribe(events.AppointmentUpdated{}.EventName(), m)
 	bus.Subscribe(events.AppointmentReminderDue{}.EventName(), m)
 	bus.Subscribe(events.NotificationOutboxDue{}.EventName(), m)
 
@@ -269,10 +356,22 @@ func (m *Module) Handle(ctx context.Context, event events.Event) error {
 		return m.handleEmailVerificationRequested(ctx, e)
 	case events.PasswordResetRequested:
 		return m.handlePasswordResetRequested(ctx, e)
+	case events.AccountLockedOut:
+		return m.handleAccountLockedOut(ctx, e)
+	case events.NewSignInDetected:
+		return m.handleNewSignInDetected(ctx, e)
 	case events.OrganizationInviteCreated:
 		return m.handleOrganizationInviteCreated(ctx, e)
 	case events.PartnerInviteCreated:
 		return m.handlePartnerInviteCreated(ctx, e)
+	case events.PartnerApplicationSubmitted:
+		return m.handlePartnerApplicationSubmitted(ctx, e)
+	case events.KvKCompanyDissolved:
+		return m.handleKvKCompanyDissolved(ctx, e)
+	case events.CatalogPriceJumpsDetected:
+		return m.handleCatalogPriceJumpsDetected(ctx, e)
+	case events.CatalogLowStockDetected:
+		return m.handleCatalogLowStockDetected(ctx, e)
 	case events.PartnerOfferCreated:
 		return m.handlePartnerOfferCreated(ctx, e)
 	case events.PartnerOfferAccepted:
@@ -281,21 +380,41 @@ func (m *Module) Handle(ctx context.Context, event events.Event) error {
 		return m.handlePartnerOfferRejected(ctx, e)
 	case events.PartnerOfferExpired:
 		return m.handlePartnerOfferExpired(ctx, e)
+	case events.WorkOrderCompleted:
+		m.dispatchWorkOrderCompletedWorkflows(ctx, e)
+		return nil
+	case events.WarrantyClaimSubmitted:
+		return m.handleWarrantyClaimSubmitted(ctx, e)
+	case events.WarrantyExpiring:
+		m.dispatchWarrantyExpiringWorkflows(ctx, e)
+		return nil
 	case events.LeadCreated:
 		return m.handleLeadCreated(ctx, e)
 	case events.LeadAssigned:
 		return m.handleLeadAssigned(ctx, e)
+	case events.LeadsHandedOver:
+		return m.handleLeadsHandedOver(ctx, e)
 	case events.LeadDataChanged:
 		return m.handleLeadDataChanged(ctx, e)
+	case events.LeadPortalLinkRequested:
+		return m.handleLeadPortalLinkRequested(ctx, e)
+	case events.LeadChannelConsentChanged:
+		return m.handleLeadChannelConsentChanged(ctx, e)
 	case events.PipelineStageChanged:
 		return m.handlePipelineStageChanged(ctx, e)
 	case events.ManualInterventionRequired:
 		return m.handleManualInterventionRequired(ctx, e)
+	case events.LeadStale:
+		return m.handleLeadStale(ctx, e)
 
 	case events.QuoteSent:
 		return m.handleQuoteSent(ctx, e)
 	case events.QuoteViewed:
 		return m.handleQuoteViewed(ctx, e)
+	case events.QuoteApprovalRequested:
+		return m.handleQuoteApprovalRequested(ctx, e)
+	case events.QuoteApprovalDecided:
+		return m.handleQuoteApprovalDecided(ctx, e)
 	case events.QuoteUpdatedByCustomer:
 		return m.handleQuoteUpdatedByCustomer(ctx, e)
 	case events.QuoteAnnotated:
@@ -304,8 +423,14 @@ func (m *Module) Handle(ctx context.Context, event events.Event) error {
 		return m.handleQuoteAccepted(ctx, e)
 	case events.QuoteRejected:
 		return m.handleQuoteRejected(ctx, e)
+	case events.QuoteInstallmentDue:
+		return m.handleQuoteInstallmentDue(ctx, e)
+	case events.QuotePaymentUpdated:
+		return m.handleQuotePaymentUpdated(ctx, e)
 	case events.AppointmentCreated:
 		return m.handleAppointmentCreated(ctx, e)
+	case events.AppointmentUpdated:
+		return m.handleAppointmentUpdated(ctx, e)
 	case events.AppointmentReminderDue:
 		return m.handleAppointmentReminderDue(ctx, e)
 	case events.NotificationOutboxDue:
@@ -319,11 +444,15 @@ func (m *Module) Handle(ctx context.Context, event events.Event) error {
 }
 
 func renderTemplateText(tpl string, data map[string]any) (string, error) {
+	return renderLocalizedTemplateText(tpl, data, i18n.DefaultLanguage)
+}
+
+func renderLocalizedTemplateText(tpl string, data map[string]any, language string) (string, error) {
 	normalizedTpl := tpl
 	if !strings.Contains(tpl, "{{.") {
 		normalizedTpl = normalizeFrontendTemplateSyntax(tpl, data)
 	}
-	parsed, err := template.New("msg").Option("missingkey=zero").Parse(normalizedTpl)
+	parsed, err := template.New("msg").Option("missingkey=zero").Funcs(localizedTemplateFuncs(language)).Parse(normalizedTpl)
 	if err != nil {
 		return "", err
 	}
@@ -412,7 +541,9 @@ func stringFromNestedMap(values map[string]any, key string, nestedKey string) st
 	return stringFromMap(nested, nestedKey)
 }
 
-func renderStepTemplate(raw *string, vars map[string]any) (string, error) {
+// renderStepTemplate renders a workflow template with amounts and dates
+// formatted in language.
+func renderStepTemplate(raw *string, vars map[string]any, language string) (string, error) {
 	if raw == nil {
 		return "", nil
 	}
@@ -420,7 +551,9 @@ func renderStepTemplate(raw *string, vars map[string]any) (string, error) {
 	if text == "" {
 		return "", nil
 	}
-	rendered, err := renderTemplateText(text, mergeWorkflowTemplateVars(buildWorkflowStepVariables(workflowStepExecutionContext{}), vars))
+	language = i18n.Resolve(language)
+	data := localizeTemplateVars(mergeWorkflowTemplateVars(buildWorkflowStepVariables(workflowStepExecutionContext{}), vars), language)
+	rendered, err := renderLocalizedTemplateText(text, data, language)
 	if err != nil {
 		return "", err
 	}
@@ -532,6 +665,10 @@ func ptrUUIDString(v *uuid.UUID) *string {
 
 var errInvalidOutboxPayload = errors.New("invalid outbox payload")
 
+// errAttachmentTooLarge marks an attachment that exceeds the email size limit
+// and is replaced by a download link when the spec provides one.
+var errAttachmentTooLarge = errors.New("attachment exceeds email size limit")
+
 func parseOptionalUUID(value *string) *uuid.UUID {
 	if value == nil || strings.TrimSpace(*value) == "" {
 		return nil
@@ -544,11 +681,11 @@ func parseOptionalUUID(value *string) *uuid.UUID {
 }
 
 // buildSchedulingLink builds a /track/:token link from leadDetails.
-func (m *Module) buildSchedulingLink(d *leadDetails) string {
+func (m *Module) buildSchedulingLink(ctx context.Context, orgID uuid.UUID, d *leadDetails) string {
 	if d == nil {
 		return ""
 	}
-	return m.buildLeadTrackLink(d.PublicToken)
+	return m.buildLeadTrackLink(ctx, orgID, d.PublicToken)
 }
 
 func (m *Module) handleManualInterventionRequired(ctx context.Context, e events.ManualInterventionRequired) error {
@@ -582,6 +719,15 @@ func (m *Module) handlePipelineStageChanged(ctx context.Context, e events.Pipeli
 	return nil
 }
 
+// handleLeadStale re-engages the consumer of a stale lead service through the
+// lead_stale workflow when the organization enabled consumer nudges.
+func (m *Module) handleLeadStale(ctx context.Context, e events.LeadStale) error {
+	if e.ConsumerNudge {
+		m.dispatchLeadStaleWorkflows(ctx, e)
+	}
+	return nil
+}
+
 func defaultName(value, fallback string) string {
 	if strings.TrimSpace(value) == "" {
 		return fallback
@@ -590,13 +736,7 @@ func defaultName(value, fallback string) string {
 }
 
 func formatCurrencyEURCents(cents int64) string {
-	sign := ""
-	abs := cents
-	if cents < 0 {
-		sign = "-"
-		abs = -cents
-	}
-	return fmt.Sprintf("%s€%d,%02d", sign, abs/100, abs%100)
+	return i18n.FormatCurrencyCents(cents, i18n.DefaultLanguage)
 }
 
 // truncate shortens a string to max characters, appending "…" when truncated.
@@ -7,6 +7,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"portal_final_backend/internal/email"
 	"portal_final_backend/internal/events"
@@ -51,6 +52,7 @@ type testSender struct {
 	quoteAcceptedCalls         int
 	quoteAcceptedThankYouCalls int
 	customEmailCalls           int
+	lastCustomBody             string
 	lastCustomAttachments      []email.Attachment
 }
 
@@ -118,7 +120,7 @@ const testQuoteNumber0042 = "OFF-2026-0042"
 
 func (s *testSender) SendVerificationEmail(context.Context, string, string) error  { return nil }
 func (s *testSender) SendPasswordResetEmail(context.Context, string, string) error { return nil }
-func (s *testSender) SendVisitInviteEmail(context.Context, string, string, string, string) error {
+func (s *testSender) SendVisitInviteEmail(context.Context, string, string, string, string, ...email.Attachment) error {
 	return nil
 }
 func (s *testSender) SendOrganizationInviteEmail(context.Context, string, string, string) error {
@@ -148,8 +150,9 @@ func (s *testSender) SendPartnerOfferAcceptedConfirmationEmail(context.Context,
 func (s *testSender) SendPartnerOfferRejectedEmail(context.Context, string, string, string, string) error {
 	return nil
 }
-func (s *testSender) SendCustomEmail(_ context.Context, _ string, _ string, _ string, attachments ...email.Attachment) error {
+func (s *testSender) SendCustomEmail(_ context.Context, _ string, _ string, body string, attachments ...email.Attachment) error {
 	s.customEmailCalls++
+	s.lastCustomBody = body
 	s.lastCustomAttachments = append([]email.Attachment(nil), attachments...)
 	return nil
 }
@@ -373,6 +376,19 @@ func TestRenderTemplateTextAcceptsMixedCaseNestedKeys(t *testing.T) {
 	}
 }
 
+func TestRenderTemplateTextResolvesLeadCustomFields(t *testing.T) {
+	vars := map[string]any{}
+	enrichLeadVars(vars, &leadDetails{FirstName: "Jan", CustomFields: map[string]any{"roof_type": "Plat"}})
+
+	rendered, err := renderTemplateText("Dak: {{lead.customFields.roof_type}}", vars)
+	if err != nil {
+		t.Fatalf("expected custom field placeholder to render, got error: %v", err)
+	}
+	if rendered != "Dak: Plat" {
+		t.Fatalf(errUnexpectedRenderedText, rendered)
+	}
+}
+
 func TestRenderTemplateTextAcceptsLegacyDotSyntax(t *testing.T) {
 	rendered, err := renderTemplateText("Test bericht {{.lead.name}}", map[string]any{
 		"lead": map[string]any{"name": "Robin"},
@@ -506,6 +522,7 @@ func TestBuildAppointmentTemplateVarsIncludesOrganizationName(t *testing.T) {
 		"14:30",
 		"Utrecht",
 		testOrgName,
+		time.Time{},
 	)
 
 	orgVars, ok := vars["org"].(map[string]any)
@@ -604,6 +621,90 @@ func TestProcessGenericEmailOutboxRegeneratesQuotePDFWithoutStoredFileKey(t *tes
 	}
 }
 
+func TestProcessGenericEmailOutboxAttachesStoredSignedQuotePDF(t *testing.T) {
+	sender := &testSender{}
+	storage := &testQuotePDFStorage{data: []byte("signed-pdf")}
+	generator := &testQuotePDFGenerator{pdfData: []byte(generatedPDFContent)}
+	orgID := uuid.New()
+	quoteID := uuid.New().String()
+
+	m := New(nil, sender, testNotificationConfig{}, logger.New("development"))
+	m.SetQuotePDFStorage(storage, "quote-pdfs")
+	m.SetQuotePDFGenerator(generator)
+
+	payloadBytes, err := json.Marshal(emailSendOutboxPayload{
+		OrgID:    orgID.String(),
+		ToEmail:  testLeadEmail,
+		Subject:  "Onderwerp",
+		BodyHTML: testEmailHTMLBody,
+		Attachments: []emailSendAttachmentSpec{{
+			Kind:    "quote_pdf",
+			QuoteID: &quoteID,
+			FileKey: "quotes/file.pdf",
+			Signed:  true,
+		}},
+	})
+	if err != nil {
+		t.Fatalf(errMarshalPayloadFmt, err)
+	}
+
+	err = m.processGenericEmailOutbox(context.Background(), events.NotificationOutboxDue{TenantID: orgID}, notificationoutbox.Record{Payload: payloadBytes})
+	if err != nil {
+		t.Fatalf(errProcessGenericEmailOutboxFmt, err)
+	}
+	if len(sender.lastCustomAttachments) != 1 {
+		t.Fatalf(errExpectedOneAttachmentFmt, len(sender.lastCustomAttachments))
+	}
+	if string(sender.lastCustomAttachments[0].Content) != "signed-pdf" {
+		t.Fatalf("expected stored signed pdf content, got %q", string(sender.lastCustomAttachments[0].Content))
+	}
+	if generator.calls != 0 {
+		t.Fatalf("expected signed pdf not to be regenerated, got %d generator calls", generator.calls)
+	}
+}
+
+func TestProcessGenericEmailOutboxLinksOversizedQuotePDF(t *testing.T) {
+	sender := &testSender{}
+	storage := &testQuotePDFStorage{data: make([]byte, maxQuotePDFAttachmentBytes+1)}
+	orgID := uuid.New()
+	quoteID := uuid.New().String()
+	downloadURL := "https://api.example.com/api/v1/public/quotes/token/pdf"
+
+	m := New(nil, sender, testNotificationConfig{}, logger.New("development"))
+	m.SetQuotePDFStorage(storage, "quote-pdfs")
+
+	payloadBytes, err := json.Marshal(emailSendOutboxPayload{
+		OrgID:    orgID.String(),
+		ToEmail:  testLeadEmail,
+		Subject:  "Onderwerp",
+		BodyHTML: testEmailHTMLBody,
+		Attachments: []emailSendAttachmentSpec{{
+			Kind:        "quote_pdf",
+			QuoteID:     &quoteID,
+			FileKey:     "quotes/file.pdf",
+			Signed:      true,
+			FallbackURL: downloadURL,
+		}},
+	})
+	if err != nil {
+		t.Fatalf(errMarshalPayloadFmt, err)
+	}
+
+	err = m.processGenericEmailOutbox(context.Background(), events.NotificationOutboxDue{TenantID: orgID}, notificationoutbox.Record{Payload: payloadBytes})
+	if err != nil {
+		t.Fatalf(errProcessGenericEmailOutboxFmt, err)
+	}
+	if sender.customEmailCalls != 1 {
+		t.Fatalf("expected 1 custom email call, got %d", sender.customEmailCalls)
+	}
+	if len(sender.lastCustomAttachments) != 0 {
+		t.Fatalf("expected oversized pdf to be left out, got %d attachments", len(sender.lastCustomAttachments))
+	}
+	if !strings.HasPrefix(sender.lastCustomBody, testEmailHTMLBody) || !strings.Contains(sender.lastCustomBody, `href="`+downloadURL+`"`) {
+		t.Fatalf("expected body to link to the pdf download, got %q", sender.lastCustomBody)
+	}
+}
+
 func TestProcessGenericEmailOutboxReturnsErrorWhenQuotePDFRegenerationFails(t *testing.T) {
 	sender := &testSender{}
 	generator := &testQuotePDFGenerator{err: errors.New("generator unavailable")}
@@ -1026,3 +1127,49 @@ func TestDispatchQuoteQuestionAskedPartnerWhatsAppWorkflowSkipsWithoutPhone(t *t
 		t.Fatal("expected missing partner phone to skip cleanly")
 	}
 }
+
+func TestProcessGenericEmailOutboxAttachesAppointmentInvite(t *testing.T) {
+	sender := &testSender{}
+	orgID := uuid.New()
+	appointmentID := uuid.New()
+	start := time.Date(2026, time.May, 4, 8, 0, 0, 0, time.UTC)
+
+	vars := buildAppointmentTemplateVars("Jan", "", testLeadEmail, "04-05-2026", "10:00", testLeadAddress, testOrgName, start)
+	addAppointmentCalendarVars(vars, appointmentWhatsAppParams{
+		AppointmentID: appointmentID,
+		Title:         "Inmeting",
+		StartTime:     start,
+		EndTime:       start.Add(time.Hour),
+		Sequence:      7,
+	})
+	specs := buildAppointmentCalendarAttachmentSpecs(vars)
+	if len(specs) != 1 || specs[0].Kind != "appointment_ics" {
+		t.Fatalf("expected one appointment_ics attachment spec, got %+v", specs)
+	}
+
+	payloadBytes, err := json.Marshal(emailSendOutboxPayload{
+		OrgID:       orgID.String(),
+		ToEmail:     testLeadEmail,
+		Subject:     "Onderwerp",
+		BodyHTML:    testEmailHTMLBody,
+		Attachments: specs,
+	})
+	if err != nil {
+		t.Fatalf(errMarshalPayloadFmt, err)
+	}
+
+	m := New(nil, sender, testNotificationConfig{}, logger.New("development"))
+	err = m.processGenericEmailOutbox(context.Background(), events.NotificationOutboxDue{TenantID: orgID}, notificationoutbox.Record{Payload: payloadBytes})
+	if err != nil {
+		t.Fatalf(errProcessGenericEmailOutboxFmt, err)
+	}
+	if len(sender.lastCustomAttachments) != 1 {
+		t.Fatalf(errExpectedOneAttachmentFmt, len(sender.lastCustomAttachments))
+	}
+	content := string(sender.lastCustomAttachments[0].Content)
+	for _, want := range []string{"METHOD:REQUEST", "UID:" + appointmentID.String() + "@portal", "SEQUENCE:7", "DTSTART:20260504T080000Z"} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in invite:\n%s", want, content)
+		}
+	}
+}
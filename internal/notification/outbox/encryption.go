@@ -0,0 +1,149 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"portal_final_backend/platform/secrets"
+
+	"github.com/google/uuid"
+)
+
+// emptyPayload is stored in the payload column of rows whose payload is
+// encrypted.
+var emptyPayload = []byte("{}")
+
+var (
+	keyringMu sync.RWMutex
+	keyring   *secrets.Keyring
+)
+
+// SetKeyring sets the keyring used to encrypt outbox payloads at rest. Without
+// one, payloads are stored as plaintext JSON.
+func SetKeyring(kr *secrets.Keyring) {
+	keyringMu.Lock()
+	defer keyringMu.Unlock()
+	keyring = kr
+}
+
+func currentKeyring() *secrets.Keyring {
+	keyringMu.RLock()
+	defer keyringMu.RUnlock()
+	return keyring
+}
+
+// sealedPayload is a payload as written to the database.
+type sealedPayload struct {
+	plain     []byte
+	encrypted *string
+	keyID     *string
+}
+
+// sealPayload encrypts payload with the primary key of kr, or keeps it as
+// plaintext when kr is nil.
+func sealPayload(kr *secrets.Keyring, payload []byte) (sealedPayload, error) {
+	if kr == nil {
+		return sealedPayload{plain: payload}, nil
+	}
+	encrypted, err := kr.Encrypt(string(payload))
+	if err != nil {
+		return sealedPayload{}, fmt.Errorf("encrypt payload: %w", err)
+	}
+	keyID := kr.PrimaryID()
+	return sealedPayload{plain: emptyPayload, encrypted: &encrypted, keyID: &keyID}, nil
+}
+
+// openPayload returns the JSON payload of a row, decrypting it when needed.
+func openPayload(kr *secrets.Keyring, plain []byte, encrypted *string) (json.RawMessage, error) {
+	if encrypted == nil {
+		return json.RawMessage(plain), nil
+	}
+	if kr == nil {
+		return nil, errors.New("decrypt payload: outbox keyring not configured")
+	}
+	decrypted, err := kr.Decrypt(*encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt payload: %w", err)
+	}
+	return json.RawMessage(decrypted), nil
+}
+
+// EncryptBatch encrypts up to limit rows that are stored as plaintext or were
+// sealed with a key other than the primary key. It returns the number of rows
+// rewritten; callers repeat until it returns zero.
+func (r *Repository) EncryptBatch(ctx context.Context, limit int) (int, error) {
+	if r == nil || r.pool == nil {
+		return 0, errors.New(errRepoNotConfigured)
+	}
+	kr := currentKeyring()
+	if kr == nil {
+		return 0, errors.New("outbox keyring not configured")
+	}
+	if limit < 1 {
+		limit = 500
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin outbox encryption: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, payload, payload_encrypted
+		FROM RAC_notification_outbox
+		WHERE payload_encrypted IS NULL OR payload_key_id IS DISTINCT FROM $1
+		ORDER BY created_at ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, kr.PrimaryID(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("list outbox payloads to encrypt: %w", err)
+	}
+	type pending struct {
+		id      uuid.UUID
+		payload json.RawMessage
+	}
+	var batch []pending
+	for rows.Next() {
+		var (
+			id        uuid.UUID
+			plain     []byte
+			encrypted *string
+		)
+		if err := rows.Scan(&id, &plain, &encrypted); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan outbox payload: %w", err)
+		}
+		payload, err := openPayload(kr, plain, encrypted)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("outbox record %s: %w", id, err)
+		}
+		batch = append(batch, pending{id: id, payload: payload})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list outbox payloads to encrypt: %w", err)
+	}
+
+	for _, item := range batch {
+		sealed, err := sealPayload(kr, item.payload)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE RAC_notification_outbox
+			SET payload = $2, payload_encrypted = $3, payload_key_id = $4
+			WHERE id = $1`, item.id, sealed.plain, sealed.encrypted, sealed.keyID,
+		); err != nil {
+			return 0, fmt.Errorf("encrypt outbox payload: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit outbox encryption: %w", err)
+	}
+	return len(batch), nil
+}
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"portal_final_backend/platform/secrets"
+)
+
+func testKeyring(t *testing.T, spec string) *secrets.Keyring {
+	t.Helper()
+	kr, err := secrets.ParseKeyring(spec)
+	if err != nil {
+		t.Fatalf("parse keyring: %v", err)
+	}
+	return kr
+}
+
+func TestSealPayloadRoundTrip(t *testing.T) {
+	kr := testKeyring(t, "k1:"+strings.Repeat("ab", secrets.KeySize))
+	payload := []byte(`{"phoneNumber":"+31612345678","message":"Hallo"}`)
+
+	sealed, err := sealPayload(kr, payload)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if !bytes.Equal(sealed.plain, emptyPayload) {
+		t.Fatalf("expected plaintext column to be emptied, got %s", sealed.plain)
+	}
+	if sealed.encrypted == nil || strings.Contains(*sealed.encrypted, "31612345678") {
+		t.Fatal("expected payload to be encrypted")
+	}
+	if sealed.keyID == nil || *sealed.keyID != "k1" {
+		t.Fatalf("expected key id k1, got %v", sealed.keyID)
+	}
+
+	opened, err := openPayload(kr, sealed.plain, sealed.encrypted)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if !bytes.Equal(opened, payload) {
+		t.Fatalf("expected %s, got %s", payload, opened)
+	}
+
+	// Rotating keeps old payloads readable.
+	rotated := testKeyring(t, "k2:"+strings.Repeat("cd", secrets.KeySize)+",k1:"+strings.Repeat("ab", secrets.KeySize))
+	if opened, err := openPayload(rotated, sealed.plain, sealed.encrypted); err != nil || !bytes.Equal(opened, payload) {
+		t.Fatalf("expected rotated keyring to open payload, got %s, %v", opened, err)
+	}
+	if _, err := openPayload(nil, sealed.plain, sealed.encrypted); err == nil {
+		t.Fatal("expected error without keyring")
+	}
+}
+
+func TestSealPayloadWithoutKeyring(t *testing.T) {
+	payload := []byte(`{"email":"jan@example.com"}`)
+	sealed, err := sealPayload(nil, payload)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if sealed.encrypted != nil || sealed.keyID != nil || !bytes.Equal(sealed.plain, payload) {
+		t.Fatalf("expected plaintext payload, got %+v", sealed)
+	}
+	opened, err := openPayload(nil, sealed.plain, nil)
+	if err != nil || !bytes.Equal(opened, payload) {
+		t.Fatalf("expected %s, got %s, %v", payload, opened, err)
+	}
+}
@@ -27,9 +27,12 @@ const (
 	errRepoNotConfigured        = "outbox repository not configured"
 )
 
+// Record is an outbox row. Payload is only decrypted by GetByID; records
+// from ClaimPending carry an empty object for encrypted payloads.
 type Record struct {
 	ID       uuid.UUID
 	TenantID uuid.UUID
+	LeadID   *uuid.UUID
 	Kind     string
 	Template string
 	Payload  json.RawMessage
@@ -85,7 +88,7 @@ func toPgTimestamp(value time.Time) pgtype.Timestamptz {
 }
 
 func recordFromModel(model notificationdb.RacNotificationOutbox) Record {
-	return Record{
+	rec := Record{
 		ID:       uuid.UUID(model.ID.Bytes),
 		TenantID: uuid.UUID(model.TenantID.Bytes),
 		Kind:     model.Kind,
@@ -95,6 +98,11 @@ func recordFromModel(model notificationdb.RacNotificationOutbox) Record {
 		Status:   Status(model.Status),
 		Attempts: int(model.Attempts),
 	}
+	if model.LeadID.Valid {
+		leadID := uuid.UUID(model.LeadID.Bytes)
+		rec.LeadID = &leadID
+	}
+	return rec
 }
 
 func (r *Repository) Insert(ctx context.Context, p InsertParams) (uuid.UUID, error) {
@@ -123,33 +131,55 @@ func (r *Repository) Insert(ctx context.Context, p InsertParams) (uuid.UUID, err
 		return uuid.Nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	id, err := r.queries.InsertNotificationOutbox(ctx, notificationdb.InsertNotificationOutboxParams{
-		TenantID:  toPgUUID(p.TenantID),
-		LeadID:    toPgUUIDPtr(p.LeadID),
-		ServiceID: toPgUUIDPtr(p.ServiceID),
-		Kind:      p.Kind,
-		Template:  p.Template,
-		Payload:   payloadBytes,
-		RunAt:     toPgTimestamp(p.RunAt),
-		Status:    string(status),
-		LastError: toPgText(p.LastError),
-	})
+	sealed, err := sealPayload(currentKeyring(), payloadBytes)
 	if err != nil {
 		return uuid.Nil, err
 	}
-	return uuid.UUID(id.Bytes), nil
+
+	var id uuid.UUID
+	err = r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_notification_outbox (
+			tenant_id, lead_id, service_id, kind, template,
+			payload, payload_encrypted, payload_key_id,
+			run_at, status, last_error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id`,
+		p.TenantID, p.LeadID, p.ServiceID, p.Kind, p.Template,
+		sealed.plain, sealed.encrypted, sealed.keyID,
+		p.RunAt, string(status), p.LastError,
+	).Scan(&id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("insert outbox record: %w", err)
+	}
+	return id, nil
 }
 
+// GetByID loads a record for dispatch, decrypting its payload.
 func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (Record, error) {
 	if r == nil || r.pool == nil {
 		return Record{}, errors.New(errRepoNotConfigured)
 	}
 
-	model, err := r.queries.GetNotificationOutboxByID(ctx, toPgUUID(id))
+	var (
+		rec       Record
+		status    string
+		plain     []byte
+		encrypted *string
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, tenant_id, lead_id, kind, template, payload, payload_encrypted, run_at, status, attempts
+		FROM RAC_notification_outbox
+		WHERE id = $1`, id,
+	).Scan(&rec.ID, &rec.TenantID, &rec.LeadID, &rec.Kind, &rec.Template, &plain, &encrypted, &rec.RunAt, &status, &rec.Attempts)
 	if err != nil {
 		return Record{}, err
 	}
-	return recordFromModel(model), nil
+	rec.Status = Status(status)
+	rec.Payload, err = openPayload(currentKeyring(), plain, encrypted)
+	if err != nil {
+		return Record{}, fmt.Errorf("outbox record %s: %w", id, err)
+	}
+	return rec, nil
 }
 
 func (r *Repository) ClaimPending(ctx context.Context, limit int) ([]Record, error) {
@@ -254,3 +284,79 @@ func (r *Repository) CancelPendingForLead(ctx context.Context, tenantID, leadID
 
 	return result, nil
 }
+
+// ListLeadRunTimes returns the run times of the lead's messages of the given
+// kinds in [from, to) that are still to be sent or were sent, excluding
+// excludeID.
+func (r *Repository) ListLeadRunTimes(ctx context.Context, tenantID, leadID uuid.UUID, kinds []string, from, to time.Time, excludeID uuid.UUID) ([]time.Time, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New(errRepoNotConfigured)
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT run_at
+		FROM RAC_notification_outbox
+		WHERE tenant_id = $1 AND lead_id = $2 AND kind = ANY($3)
+			AND run_at >= $4 AND run_at < $5
+			AND status NOT IN ($6, $7)
+			AND id <> $8
+		ORDER BY run_at ASC`,
+		tenantID, leadID, kinds, from, to, string(StatusCancelled), string(StatusFailed), excludeID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list lead outbox run times: %w", err)
+	}
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var runAt time.Time
+		if err := rows.Scan(&runAt); err != nil {
+			return nil, fmt.Errorf("scan lead outbox run time: %w", err)
+		}
+		times = append(times, runAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list lead outbox run times: %w", err)
+	}
+	return times, nil
+}
+
+// Defer moves a record back to pending at runAt without counting the
+// processing attempt that deferred it against its retry budget.
+func (r *Repository) Defer(ctx context.Context, id uuid.UUID, runAt time.Time, reason string) error {
+	if r == nil || r.pool == nil {
+		return errors.New(errRepoNotConfigured)
+	}
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_notification_outbox
+		SET status = $2, run_at = $3, last_error = $4, attempts = GREATEST(attempts - 1, 0), updated_at = now()
+		WHERE id = $1`, id, string(StatusPending), runAt, reason,
+	); err != nil {
+		return fmt.Errorf("defer outbox record: %w", err)
+	}
+	return nil
+}
+
+// RequeueFailed moves up to limit records that failed at or after failedSince
+// back to pending with a fresh retry budget. An empty kind matches all kinds.
+// It returns the number of records requeued.
+func (r *Repository) RequeueFailed(ctx context.Context, failedSince time.Time, kind string, limit int) (int, error) {
+	if r == nil || r.pool == nil {
+		return 0, errors.New(errRepoNotConfigured)
+	}
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_notification_outbox
+		SET status = $1, run_at = now(), attempts = 0, updated_at = now()
+		WHERE id IN (
+			SELECT id
+			FROM RAC_notification_outbox
+			WHERE status = $2 AND updated_at >= $3 AND ($4 = '' OR kind = $4)
+			ORDER BY updated_at ASC
+			LIMIT $5
+		)`, string(StatusPending), string(StatusFailed), failedSince, kind, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("requeue failed outbox records: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,33 @@
+package notification
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PortalDomainResolver provides the base URL of an organization's public portal
+// on its verified custom domain, or an empty string when it has none.
+type PortalDomainResolver interface {
+	ResolvePublicBaseURL(ctx context.Context, organizationID uuid.UUID) (string, error)
+}
+
+// SetPortalDomainResolver injects the resolver for white-label portal domains.
+func (m *Module) SetPortalDomainResolver(resolver PortalDomainResolver) {
+	m.portalDomains = resolver
+}
+
+// publicBaseURL returns the base URL of the public portal links sent for an
+// organization: its custom domain when verified, otherwise the shared portal.
+func (m *Module) publicBaseURL(ctx context.Context, orgID uuid.UUID) string {
+	if m.portalDomains != nil && orgID != uuid.Nil {
+		base, err := m.portalDomains.ResolvePublicBaseURL(ctx, orgID)
+		if err != nil {
+			m.log.Warn("failed to resolve portal domain; using shared portal URL", "orgId", orgID, "error", err)
+		} else if base != "" {
+			return strings.TrimRight(base, "/")
+		}
+	}
+	return strings.TrimRight(m.cfg.GetPublicBaseURL(), "/")
+}
@@ -0,0 +1,112 @@
+package shortlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrNotFound is returned when a code does not exist or has expired.
+var ErrNotFound = errors.New("short link not found")
+
+var errDuplicateCode = errors.New("short link code already exists")
+
+// Link is a short link. OutboxID is set for links wrapped in a tracked email.
+type Link struct {
+	Code           string
+	OrganizationID uuid.UUID
+	LeadID         *uuid.UUID
+	ServiceID      *uuid.UUID
+	OutboxID       *uuid.UUID
+	TargetURL      string
+	ExpiresAt      time.Time
+	ClickCount     int
+	LastClickedAt  *time.Time
+	CreatedAt      time.Time
+}
+
+type CreateParams struct {
+	Code           string
+	OrganizationID uuid.UUID
+	LeadID         *uuid.UUID
+	ServiceID      *uuid.UUID
+	OutboxID       *uuid.UUID
+	TargetURL      string
+	ExpiresAt      time.Time
+}
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+const linkColumns = `code, organization_id, lead_id, service_id, outbox_id, target_url, expires_at, click_count, last_clicked_at, created_at`
+
+func scanLink(row pgx.Row) (Link, error) {
+	var l Link
+	err := row.Scan(&l.Code, &l.OrganizationID, &l.LeadID, &l.ServiceID, &l.OutboxID, &l.TargetURL, &l.ExpiresAt, &l.ClickCount, &l.LastClickedAt, &l.CreatedAt)
+	return l, err
+}
+
+// Create stores a short link. It returns errDuplicateCode when the code is
+// taken so the caller can retry with another one.
+func (r *Repository) Create(ctx context.Context, p CreateParams) (Link, error) {
+	link, err := scanLink(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_notification_short_links (code, organization_id, lead_id, service_id, outbox_id, target_url, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+linkColumns,
+		p.Code, p.OrganizationID, p.LeadID, p.ServiceID, p.OutboxID, p.TargetURL, p.ExpiresAt,
+	))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return Link{}, errDuplicateCode
+	}
+	if err != nil {
+		return Link{}, fmt.Errorf("create short link: %w", err)
+	}
+	return link, nil
+}
+
+// RecordClick counts a click on an unexpired link and returns it.
+func (r *Repository) RecordClick(ctx context.Context, code string, now time.Time) (Link, error) {
+	link, err := scanLink(r.pool.QueryRow(ctx, `
+		UPDATE RAC_notification_short_links SET
+			click_count = click_count + 1,
+			last_clicked_at = $2
+		WHERE code = $1 AND expires_at > $2
+		RETURNING `+linkColumns,
+		code, now,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Link{}, ErrNotFound
+	}
+	if err != nil {
+		return Link{}, fmt.Errorf("record short link click: %w", err)
+	}
+	return link, nil
+}
+
+// Get returns an unexpired link without counting a click.
+func (r *Repository) Get(ctx context.Context, code string, now time.Time) (Link, error) {
+	link, err := scanLink(r.pool.QueryRow(ctx, `
+		SELECT `+linkColumns+` FROM RAC_notification_short_links
+		WHERE code = $1 AND expires_at > $2`,
+		code, now,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Link{}, ErrNotFound
+	}
+	if err != nil {
+		return Link{}, fmt.Errorf("get short link: %w", err)
+	}
+	return link, nil
+}
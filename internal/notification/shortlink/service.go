@@ -0,0 +1,106 @@
+// Package shortlink replaces the long tokenized portal URLs in outgoing
+// messages with short codes, redirects them and tracks their clicks.
+package shortlink
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+)
+
+const (
+	codeAlphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLength   = 8
+	maxAttempts  = 5
+)
+
+// ClickRecorder is told about every counted click, e.g. to write it to the
+// lead timeline.
+type ClickRecorder interface {
+	RecordShortLinkClick(ctx context.Context, link Link)
+}
+
+type Service struct {
+	repo     *Repository
+	recorder ClickRecorder
+	now      func() time.Time
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// SetClickRecorder injects the recorder notified of clicks.
+func (s *Service) SetClickRecorder(recorder ClickRecorder) { s.recorder = recorder }
+
+// Shorten stores a short link to the target URL and returns its code.
+func (s *Service) Shorten(ctx context.Context, p CreateParams) (string, error) {
+	if strings.TrimSpace(p.TargetURL) == "" {
+		return "", apperr.Validation("target url is required")
+	}
+	if !p.ExpiresAt.After(s.now()) {
+		return "", apperr.Validation("expiry must be in the future")
+	}
+	for range maxAttempts {
+		code, err := generateCode()
+		if err != nil {
+			return "", err
+		}
+		p.Code = code
+		link, err := s.repo.Create(ctx, p)
+		if errors.Is(err, errDuplicateCode) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return link.Code, nil
+	}
+	return "", fmt.Errorf("generate short link code: %d collisions", maxAttempts)
+}
+
+// Open resolves a code to its link. Link previews, such as the one WhatsApp
+// fetches when a message is delivered, are not counted as clicks.
+func (s *Service) Open(ctx context.Context, code string, preview bool) (Link, error) {
+	code = strings.TrimSpace(code)
+	if code == "" || len(code) > codeLength {
+		return Link{}, apperr.NotFound("link not found")
+	}
+	if preview {
+		return s.mapNotFound(s.repo.Get(ctx, code, s.now()))
+	}
+	link, err := s.mapNotFound(s.repo.RecordClick(ctx, code, s.now()))
+	if err != nil {
+		return Link{}, err
+	}
+	if s.recorder != nil {
+		s.recorder.RecordShortLinkClick(ctx, link)
+	}
+	return link, nil
+}
+
+func (s *Service) mapNotFound(link Link, err error) (Link, error) {
+	if errors.Is(err, ErrNotFound) {
+		return Link{}, apperr.NotFound("link not found or expired")
+	}
+	return link, err
+}
+
+func generateCode() (string, error) {
+	limit := big.NewInt(int64(len(codeAlphabet)))
+	var b strings.Builder
+	for range codeLength {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", fmt.Errorf("generate short link code: %w", err)
+		}
+		b.WriteByte(codeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/notification/shortlink"
+
+	"github.com/google/uuid"
+)
+
+const (
+	shortLinkPathPrefix      = "/api/v1/public/s/"
+	defaultShortLinkLifetime = 30 * 24 * time.Hour
+)
+
+// shortLinkLifetimes keeps a short link valid as long as the token in the
+// portal page it points to, keyed on the first path segment.
+var shortLinkLifetimes = map[string]time.Duration{
+	"/track": 90 * 24 * time.Hour, // maximum lifetime of a lead magic link
+	"/quote": 30 * 24 * time.Hour, // default lifetime of a public quote token
+}
+
+var messageURLPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// shortenPortalLinks replaces the portal URLs in a WhatsApp message with short
+// links. Links that cannot be shortened are kept as they are.
+func (m *Module) shortenPortalLinks(ctx context.Context, execCtx workflowStepExecutionContext, message string) string {
+	if m.shortLinks == nil {
+		return message
+	}
+	portalBase := m.publicBaseURL(ctx, execCtx.OrgID)
+	shortBase := m.shortLinkBaseURL(ctx, execCtx.OrgID)
+	if portalBase == "" || shortBase == "" {
+		return message
+	}
+	now := time.Now().UTC()
+	return rewritePortalLinks(message, portalBase, func(target, path string) (string, bool) {
+		code, err := m.shortLinks.Shorten(ctx, shortlink.CreateParams{
+			OrganizationID: execCtx.OrgID,
+			LeadID:         execCtx.LeadID,
+			ServiceID:      execCtx.ServiceID,
+			TargetURL:      target,
+			ExpiresAt:      now.Add(shortLinkLifetime(path)),
+		})
+		if err != nil {
+			m.log.Warn("failed to shorten portal link; sending full url", "orgId", execCtx.OrgID, "error", err)
+			return "", false
+		}
+		return shortBase + shortLinkPathPrefix + code, true
+	})
+}
+
+// shortLinkBaseURL returns the host of the redirect endpoint: the custom
+// portal domain, which proxies the API, or the public API.
+func (m *Module) shortLinkBaseURL(ctx context.Context, orgID uuid.UUID) string {
+	if m.portalDomains != nil {
+		if base, err := m.portalDomains.ResolvePublicBaseURL(ctx, orgID); err == nil && base != "" {
+			return strings.TrimRight(base, "/")
+		}
+	}
+	return strings.TrimRight(m.cfg.GetPublicAPIBaseURL(), "/")
+}
+
+// rewritePortalLinks calls shorten for every URL under portalBase with the URL
+// and its path, and replaces the URL with the result when shorten succeeds.
+func rewritePortalLinks(message, portalBase string, shorten func(target, path string) (string, bool)) string {
+	portalBase = strings.TrimRight(portalBase, "/")
+	return messageURLPattern.ReplaceAllStringFunc(message, func(match string) string {
+		target := strings.TrimRight(match, ".,;:!?)")
+		trailing := match[len(target):]
+		path, ok := strings.CutPrefix(target, portalBase)
+		if !ok || !strings.HasPrefix(path, "/") || len(path) < 2 {
+			return match
+		}
+		short, ok := shorten(target, path)
+		if !ok {
+			return match
+		}
+		return short + trailing
+	})
+}
+
+func shortLinkLifetime(path string) time.Duration {
+	segment := path
+	if i := strings.IndexByte(path[1:], '/'); i >= 0 {
+		segment = path[:i+1]
+	}
+	if lifetime, ok := shortLinkLifetimes[segment]; ok {
+		return lifetime
+	}
+	return defaultShortLinkLifetime
+}
+
+// RecordShortLinkClick writes a click on a short link to the lead timeline.
+// Clicks on links wrapped in a tracked email count as email engagement.
+func (m *Module) RecordShortLinkClick(ctx context.Context, link shortlink.Link) {
+	if link.OutboxID != nil {
+		if m.emailTracking != nil {
+			if err := m.emailTracking.RecordClick(ctx, *link.OutboxID); err != nil {
+				m.log.Error("failed to record email click", "outboxId", *link.OutboxID, "error", err)
+			}
+		}
+		return
+	}
+	if m.leadTimeline == nil || link.LeadID == nil {
+		return
+	}
+	summary := fmt.Sprintf("Klant opende de link uit WhatsApp (%d keer geopend)", link.ClickCount)
+	if err := m.leadTimeline.CreateTimelineEvent(ctx, LeadTimelineEventParams{
+		LeadID:    *link.LeadID,
+		ServiceID: link.ServiceID,
+		OrgID:     link.OrganizationID,
+		ActorType: "Lead",
+		ActorName: "Klant",
+		EventType: "short_link_clicked",
+		Title:     "Link geopend",
+		Summary:   &summary,
+		Metadata: map[string]any{
+			"code":       link.Code,
+			"targetUrl":  link.TargetURL,
+			"clickCount": link.ClickCount,
+		},
+		Visibility: "internal",
+	}); err != nil {
+		m.log.Error("failed to write short link click to timeline", "leadId", *link.LeadID, "error", err)
+	}
+}
+
+var _ shortlink.ClickRecorder = (*Module)(nil)
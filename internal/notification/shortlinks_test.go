@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRewritePortalLinksShortensOnlyPortalURLs(t *testing.T) {
+	message := "Bekijk uw offerte: https://portal.example.com/quote/abc123. Vragen? Zie https://example.com/faq of https://portal.example.com/"
+	var paths []string
+	got := rewritePortalLinks(message, "https://portal.example.com/", func(target, path string) (string, bool) {
+		paths = append(paths, path)
+		return "https://api.example.com/s/XyZ", true
+	})
+
+	want := "Bekijk uw offerte: https://api.example.com/s/XyZ. Vragen? Zie https://example.com/faq of https://portal.example.com/"
+	if got != want {
+		t.Fatalf("unexpected message:\n got: %s\nwant: %s", got, want)
+	}
+	if len(paths) != 1 || paths[0] != "/quote/abc123" {
+		t.Fatalf("expected only the quote path to be shortened, got %v", paths)
+	}
+}
+
+func TestRewritePortalLinksKeepsURLWhenShorteningFails(t *testing.T) {
+	message := "Volg uw aanvraag: https://portal.example.com/track/token"
+	got := rewritePortalLinks(message, "https://portal.example.com", func(string, string) (string, bool) {
+		return "", false
+	})
+	if got != message {
+		t.Fatalf("expected message unchanged, got %s", got)
+	}
+}
+
+func TestShortLinkLifetime(t *testing.T) {
+	if got := shortLinkLifetime("/track/token"); got != 90*24*time.Hour {
+		t.Fatalf("expected track links to live 90 days, got %s", got)
+	}
+	if got := shortLinkLifetime("/partner-offer/token"); got != defaultShortLinkLifetime {
+		t.Fatalf("expected default lifetime, got %s", got)
+	}
+}
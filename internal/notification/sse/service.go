@@ -28,12 +28,13 @@ const (
 	EventLeadStatusChanged        EventType = "lead_status_changed"
 
 	// Quote events (pushed to agents watching a quote)
-	EventQuoteSent        EventType = "quote_sent"
-	EventQuoteViewed      EventType = "quote_viewed"
-	EventQuoteItemToggled EventType = "quote_item_toggled"
-	EventQuoteAnnotated   EventType = "quote_annotated"
-	EventQuoteAccepted    EventType = "quote_accepted"
-	EventQuoteRejected    EventType = "quote_rejected"
+	EventQuoteSent           EventType = "quote_sent"
+	EventQuoteViewed         EventType = "quote_viewed"
+	EventQuoteItemToggled    EventType = "quote_item_toggled"
+	EventQuoteAnnotated      EventType = "quote_annotated"
+	EventQuoteAccepted       EventType = "quote_accepted"
+	EventQuoteRejected       EventType = "quote_rejected"
+	EventQuotePaymentUpdated EventType = "quote_payment_updated"
 
 	// Appointment events (pushed to org members)
 	EventAppointmentCreated       EventType = "appointment_created"
@@ -54,6 +55,19 @@ const (
 	EventWhatsAppMessageReceived     EventType = "whatsapp_message_received"
 	EventWhatsAppMessageSent         EventType = "whatsapp_message_sent"
 	EventWhatsAppMessageUpdated      EventType = "whatsapp_message_updated"
+
+	// In-app notification center events (pushed to the notified user)
+	EventInAppNotification EventType = "in_app_notification"
+	EventInAppUnreadCount  EventType = "in_app_unread_count"
+
+	// Task events
+	EventTaskCreated EventType = "task_created"
+	EventTaskUpdated EventType = "task_updated"
+	EventTaskDeleted EventType = "task_deleted"
+
+	// Telephony events
+	EventIncomingCall EventType = "incoming_call"
+	EventCallUpdated  EventType = "call_updated"
 )
 
 // Event represents an SSE event payload
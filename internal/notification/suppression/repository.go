@@ -0,0 +1,152 @@
+package suppression
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Kinds of suppressed addresses.
+const (
+	KindEmail = "email"
+	KindPhone = "phone"
+)
+
+// Reasons an address is suppressed.
+const (
+	ReasonManual     = "manual"
+	ReasonComplaint  = "complaint"
+	ReasonHardBounce = "hard_bounce"
+	ReasonOptOut     = "opt_out"
+)
+
+type Entry struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	Kind           string     `json:"kind"`
+	Value          string     `json:"value"`
+	Reason         string     `json:"reason"`
+	Note           *string    `json:"note,omitempty"`
+	CreatedBy      *uuid.UUID `json:"createdBy,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type AddParams struct {
+	OrganizationID uuid.UUID
+	Kind           string
+	Value          string
+	Reason         string
+	Note           *string
+	CreatedBy      *uuid.UUID
+}
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+const entryColumns = `id, organization_id, kind, value, reason, note, created_by, created_at`
+
+type entryScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(row entryScanner) (Entry, error) {
+	var e Entry
+	err := row.Scan(&e.ID, &e.OrganizationID, &e.Kind, &e.Value, &e.Reason, &e.Note, &e.CreatedBy, &e.CreatedAt)
+	return e, err
+}
+
+// Add suppresses an address. An address that is already suppressed keeps its
+// original entry, which is returned.
+func (r *Repository) Add(ctx context.Context, p AddParams) (Entry, error) {
+	entry, err := scanEntry(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_notification_suppressions (organization_id, kind, value, reason, note, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (organization_id, kind, value) DO UPDATE SET kind = EXCLUDED.kind
+		RETURNING `+entryColumns,
+		p.OrganizationID, p.Kind, p.Value, p.Reason, p.Note, p.CreatedBy,
+	))
+	if err != nil {
+		return Entry{}, fmt.Errorf("add notification suppression: %w", err)
+	}
+	return entry, nil
+}
+
+// List returns the suppressed addresses of an organization, newest first,
+// optionally filtered on kind and a part of the address.
+func (r *Repository) List(ctx context.Context, organizationID uuid.UUID, kind string, search string, limit int, offset int) ([]Entry, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM RAC_notification_suppressions
+		WHERE organization_id = $1 AND ($2 = '' OR kind = $2) AND ($3 = '' OR value ILIKE '%' || $3 || '%')`,
+		organizationID, kind, search,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count notification suppressions: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+entryColumns+` FROM RAC_notification_suppressions
+		WHERE organization_id = $1 AND ($2 = '' OR kind = $2) AND ($3 = '' OR value ILIKE '%' || $3 || '%')
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5`,
+		organizationID, kind, search, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list notification suppressions: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]Entry, 0)
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan notification suppression: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
+
+// Delete lifts a suppression. It returns false when the entry does not exist.
+func (r *Repository) Delete(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_notification_suppressions WHERE id = $1 AND organization_id = $2`, id, organizationID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("delete notification suppression: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteByValue lifts the suppression of an address added for the reason.
+func (r *Repository) DeleteByValue(ctx context.Context, organizationID uuid.UUID, kind string, value string, reason string) error {
+	if _, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_notification_suppressions
+		WHERE organization_id = $1 AND kind = $2 AND value = $3 AND reason = $4`,
+		organizationID, kind, value, reason,
+	); err != nil {
+		return fmt.Errorf("delete notification suppression by value: %w", err)
+	}
+	return nil
+}
+
+// Exists reports whether an address is suppressed.
+func (r *Repository) Exists(ctx context.Context, organizationID uuid.UUID, kind string, value string) (bool, error) {
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM RAC_notification_suppressions
+			WHERE organization_id = $1 AND kind = $2 AND value = $3
+		)`, organizationID, kind, value,
+	).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check notification suppression: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,181 @@
+// Package suppression keeps the email addresses and phone numbers an
+// organization must never contact, and checks outgoing notifications
+// against them.
+package suppression
+
+import (
+	"context"
+	"strings"
+
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/phone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	maxNoteLength   = 500
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+type ListParams struct {
+	Kind     string
+	Search   string
+	Page     int
+	PageSize int
+}
+
+type ListResult struct {
+	Items    []Entry `json:"items"`
+	Total    int     `json:"total"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"pageSize"`
+}
+
+// List returns a page of the suppression list of an organization.
+func (s *Service) List(ctx context.Context, organizationID uuid.UUID, p ListParams) (ListResult, error) {
+	if p.Kind != "" && p.Kind != KindEmail && p.Kind != KindPhone {
+		return ListResult{}, apperr.Validation("kind must be email or phone")
+	}
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PageSize < 1 {
+		p.PageSize = defaultPageSize
+	}
+	p.PageSize = min(p.PageSize, maxPageSize)
+
+	items, total, err := s.repo.List(ctx, organizationID, p.Kind, strings.TrimSpace(p.Search), p.PageSize, (p.Page-1)*p.PageSize)
+	if err != nil {
+		return ListResult{}, err
+	}
+	return ListResult{Items: items, Total: total, Page: p.Page, PageSize: p.PageSize}, nil
+}
+
+// Add suppresses an address on request of a user, for example after a
+// complaint.
+func (s *Service) Add(ctx context.Context, organizationID uuid.UUID, kind string, value string, reason string, note string, actorID uuid.UUID) (Entry, error) {
+	normalized := Normalize(kind, value)
+	if normalized == "" {
+		return Entry{}, apperr.Validation("invalid email address or phone number").WithDetails(value)
+	}
+	switch reason {
+	case "":
+		reason = ReasonManual
+	case ReasonManual, ReasonComplaint:
+	default:
+		return Entry{}, apperr.Validation("reason must be manual or complaint")
+	}
+	note = strings.TrimSpace(note)
+	if len(note) > maxNoteLength {
+		return Entry{}, apperr.Validation("note is too long")
+	}
+	var notePtr *string
+	if note != "" {
+		notePtr = &note
+	}
+	return s.repo.Add(ctx, AddParams{
+		OrganizationID: organizationID,
+		Kind:           kind,
+		Value:          normalized,
+		Reason:         reason,
+		Note:           notePtr,
+		CreatedBy:      &actorID,
+	})
+}
+
+// Remove lifts a suppression.
+func (s *Service) Remove(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) error {
+	deleted, err := s.repo.Delete(ctx, organizationID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperr.NotFound("suppression not found")
+	}
+	return nil
+}
+
+// Suppress adds an address automatically, after a hard bounce or an opt-out.
+// Invalid addresses are ignored.
+func (s *Service) Suppress(ctx context.Context, organizationID uuid.UUID, kind string, value string, reason string) error {
+	normalized := Normalize(kind, value)
+	if normalized == "" {
+		return nil
+	}
+	_, err := s.repo.Add(ctx, AddParams{OrganizationID: organizationID, Kind: kind, Value: normalized, Reason: reason})
+	return err
+}
+
+// Lift removes an automatic suppression of an address that was added for the
+// reason, such as an opt-out the lead reverted. Suppressions for other
+// reasons stay.
+func (s *Service) Lift(ctx context.Context, organizationID uuid.UUID, kind string, value string, reason string) error {
+	normalized := Normalize(kind, value)
+	if normalized == "" {
+		return nil
+	}
+	return s.repo.DeleteByValue(ctx, organizationID, kind, normalized, reason)
+}
+
+// IsSuppressed reports whether the address must not be contacted.
+func (s *Service) IsSuppressed(ctx context.Context, organizationID uuid.UUID, kind string, value string) (bool, error) {
+	normalized := Normalize(kind, value)
+	if normalized == "" {
+		return false, nil
+	}
+	return s.repo.Exists(ctx, organizationID, kind, normalized)
+}
+
+// Normalize returns the form addresses of the kind are stored and compared
+// in, or "" when the value is not a valid address.
+func Normalize(kind string, value string) string {
+	value = strings.TrimSpace(value)
+	switch kind {
+	case KindEmail:
+		value = strings.ToLower(value)
+		at := strings.LastIndex(value, "@")
+		if at <= 0 || at == len(value)-1 || strings.ContainsAny(value, " \t") {
+			return ""
+		}
+		return value
+	case KindPhone:
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, value)
+		if len(digits) < 6 {
+			return ""
+		}
+		if normalized := phone.NormalizeE164(value); isE164(normalized) {
+			return normalized
+		}
+		// WhatsApp numbers come without the leading plus.
+		return phone.NormalizeE164("+" + digits)
+	default:
+		return ""
+	}
+}
+
+func isE164(value string) bool {
+	if len(value) < 2 || value[0] != '+' {
+		return false
+	}
+	for _, r := range value[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
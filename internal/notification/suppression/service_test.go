@@ -0,0 +1,25 @@
+package suppression
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		kind  string
+		value string
+		want  string
+	}{
+		{KindEmail, " Jan@Example.COM ", "jan@example.com"},
+		{KindEmail, "not-an-address", ""},
+		{KindEmail, "@example.com", ""},
+		{KindPhone, "06 12345678", "+31612345678"},
+		{KindPhone, "+31 6 1234 5678", "+31612345678"},
+		{KindPhone, "31612345678", "+31612345678"},
+		{KindPhone, "123", ""},
+		{"fax", "0201234567", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.kind, tt.value); got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, want %q", tt.kind, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package notification
+
+import (
+	"context"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/notification/suppression"
+
+	"github.com/google/uuid"
+)
+
+// isAddressSuppressed reports whether the organization must never contact
+// the address. Lookup failures are returned so callers can retry instead of
+// risking a message to a suppressed address.
+func (m *Module) isAddressSuppressed(ctx context.Context, orgID uuid.UUID, kind string, value string) (bool, error) {
+	if m.suppressions == nil {
+		return false, nil
+	}
+	suppressed, err := m.suppressions.IsSuppressed(ctx, orgID, kind, value)
+	if err != nil {
+		m.log.Warn("failed to check notification suppression", "orgId", orgID, "kind", kind, "error", err)
+		return false, err
+	}
+	if suppressed {
+		m.log.Info("address is on the suppression list; skipping message", "orgId", orgID, "kind", kind)
+	}
+	return suppressed, nil
+}
+
+// suppressAddress adds an address to the suppression list automatically.
+func (m *Module) suppressAddress(ctx context.Context, orgID uuid.UUID, kind string, value string, reason string) {
+	if m.suppressions == nil {
+		return
+	}
+	if err := m.suppressions.Suppress(ctx, orgID, kind, value, reason); err != nil {
+		m.log.Error("failed to add notification suppression", "orgId", orgID, "kind", kind, "reason", reason, "error", err)
+		return
+	}
+	m.log.Info("address added to suppression list", "orgId", orgID, "kind", kind, "reason", reason)
+}
+
+// handleLeadChannelConsentChanged keeps the opt-outs of a lead on the
+// suppression list, so they also apply to other leads with the same address.
+// Granting consent again lifts only the opt-out suppression.
+func (m *Module) handleLeadChannelConsentChanged(ctx context.Context, e events.LeadChannelConsentChanged) error {
+	if m.suppressions == nil {
+		return nil
+	}
+	details := m.resolveLeadDetails(ctx, e.LeadID, e.TenantID)
+	if details == nil {
+		return nil
+	}
+
+	kind, value := suppression.KindEmail, details.Email
+	if e.Channel == "whatsapp" {
+		kind, value = suppression.KindPhone, details.Phone
+	}
+	if !e.Granted {
+		m.suppressAddress(ctx, e.TenantID, kind, value, suppression.ReasonOptOut)
+		return nil
+	}
+	if err := m.suppressions.Lift(ctx, e.TenantID, kind, value, suppression.ReasonOptOut); err != nil {
+		m.log.Error("failed to lift opt-out suppression", "leadId", e.LeadID, "orgId", e.TenantID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// unsuppressedEmail returns the address, or "" when it is on the suppression
+// list. Addresses whose check fails are kept; the outbox checks them again
+// before sending.
+func (m *Module) unsuppressedEmail(ctx context.Context, orgID uuid.UUID, address string) string {
+	if address == "" {
+		return ""
+	}
+	if suppressed, _ := m.isAddressSuppressed(ctx, orgID, suppression.KindEmail, address); suppressed {
+		return ""
+	}
+	return address
+}
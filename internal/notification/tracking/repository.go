@@ -0,0 +1,148 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrNotFound is returned for emails that are not tracked.
+var ErrNotFound = errors.New("tracked email not found")
+
+// Email holds the engagement of one tracked outbox email.
+type Email struct {
+	OutboxID       uuid.UUID
+	OrganizationID uuid.UUID
+	LeadID         *uuid.UUID
+	ServiceID      *uuid.UUID
+	Category       string
+	SentAt         time.Time
+	OpenCount      int
+	FirstOpenedAt  *time.Time
+	LastOpenedAt   *time.Time
+	ClickCount     int
+	FirstClickedAt *time.Time
+	LastClickedAt  *time.Time
+}
+
+type RecordSentParams struct {
+	OutboxID       uuid.UUID
+	OrganizationID uuid.UUID
+	LeadID         *uuid.UUID
+	ServiceID      *uuid.UUID
+	Category       string
+}
+
+// CategoryCounts are the engagement counters of the emails of one category.
+type CategoryCounts struct {
+	Category string
+	Sent     int64
+	Opened   int64
+	Clicked  int64
+	Opens    int64
+	Clicks   int64
+}
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+const emailColumns = `outbox_id, organization_id, lead_id, service_id, category, sent_at,
+	open_count, first_opened_at, last_opened_at, click_count, first_clicked_at, last_clicked_at`
+
+func scanEmail(row pgx.Row) (Email, error) {
+	var e Email
+	err := row.Scan(&e.OutboxID, &e.OrganizationID, &e.LeadID, &e.ServiceID, &e.Category, &e.SentAt,
+		&e.OpenCount, &e.FirstOpenedAt, &e.LastOpenedAt, &e.ClickCount, &e.FirstClickedAt, &e.LastClickedAt)
+	return e, err
+}
+
+// RecordSent starts tracking a delivered email. A retried delivery keeps the
+// original record.
+func (r *Repository) RecordSent(ctx context.Context, p RecordSentParams) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_notification_email_tracking (outbox_id, organization_id, lead_id, service_id, category)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (outbox_id) DO NOTHING`,
+		p.OutboxID, p.OrganizationID, p.LeadID, p.ServiceID, p.Category,
+	); err != nil {
+		return fmt.Errorf("record tracked email: %w", err)
+	}
+	return nil
+}
+
+// RecordOpen counts an open of a tracked email.
+func (r *Repository) RecordOpen(ctx context.Context, outboxID uuid.UUID, now time.Time) (Email, error) {
+	return r.update(ctx, "record email open", `
+		UPDATE RAC_notification_email_tracking SET
+			open_count = open_count + 1,
+			first_opened_at = COALESCE(first_opened_at, $2),
+			last_opened_at = $2
+		WHERE outbox_id = $1
+		RETURNING `+emailColumns, outboxID, now)
+}
+
+// RecordClick counts a click on a link in a tracked email. A click implies
+// the email was opened, also when its images were blocked.
+func (r *Repository) RecordClick(ctx context.Context, outboxID uuid.UUID, now time.Time) (Email, error) {
+	return r.update(ctx, "record email click", `
+		UPDATE RAC_notification_email_tracking SET
+			click_count = click_count + 1,
+			first_clicked_at = COALESCE(first_clicked_at, $2),
+			last_clicked_at = $2,
+			first_opened_at = COALESCE(first_opened_at, $2)
+		WHERE outbox_id = $1
+		RETURNING `+emailColumns, outboxID, now)
+}
+
+func (r *Repository) update(ctx context.Context, op string, query string, outboxID uuid.UUID, now time.Time) (Email, error) {
+	email, err := scanEmail(r.pool.QueryRow(ctx, query, outboxID, now))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Email{}, ErrNotFound
+	}
+	if err != nil {
+		return Email{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return email, nil
+}
+
+// ListCategoryCounts returns the engagement per category of the emails an
+// organization sent in [from, to).
+func (r *Repository) ListCategoryCounts(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]CategoryCounts, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT category,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE first_opened_at IS NOT NULL),
+			COUNT(*) FILTER (WHERE click_count > 0),
+			COALESCE(SUM(open_count), 0),
+			COALESCE(SUM(click_count), 0)
+		FROM RAC_notification_email_tracking
+		WHERE organization_id = $1 AND sent_at >= $2 AND sent_at < $3
+		GROUP BY category
+		ORDER BY category`,
+		organizationID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list email engagement: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]CategoryCounts, 0)
+	for rows.Next() {
+		var c CategoryCounts
+		if err := rows.Scan(&c.Category, &c.Sent, &c.Opened, &c.Clicked, &c.Opens, &c.Clicks); err != nil {
+			return nil, fmt.Errorf("scan email engagement: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
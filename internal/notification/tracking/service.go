@@ -0,0 +1,169 @@
+// Package tracking records the opens and clicks of outbox emails for
+// organizations that opted in, and reports the engagement per workflow.
+package tracking
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventOpened  = "opened"
+	EventClicked = "clicked"
+
+	defaultReportDays = 30
+	maxReportDays     = 366
+)
+
+// EngagementRecorder is told about every open and click, e.g. to write it to
+// the lead timeline.
+type EngagementRecorder interface {
+	RecordEmailEngagement(ctx context.Context, email Email, event string)
+}
+
+type Service struct {
+	repo     *Repository
+	recorder EngagementRecorder
+	now      func() time.Time
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// SetEngagementRecorder injects the recorder notified of opens and clicks.
+func (s *Service) SetEngagementRecorder(recorder EngagementRecorder) { s.recorder = recorder }
+
+// RecordSent starts tracking a delivered email.
+func (s *Service) RecordSent(ctx context.Context, p RecordSentParams) error {
+	return s.repo.RecordSent(ctx, p)
+}
+
+// RecordOpen counts an open of a tracked email. Untracked emails are ignored.
+func (s *Service) RecordOpen(ctx context.Context, outboxID uuid.UUID) error {
+	return s.record(ctx, s.repo.RecordOpen, outboxID, EventOpened)
+}
+
+// RecordClick counts a click on a link in a tracked email. Untracked emails
+// are ignored.
+func (s *Service) RecordClick(ctx context.Context, outboxID uuid.UUID) error {
+	return s.record(ctx, s.repo.RecordClick, outboxID, EventClicked)
+}
+
+func (s *Service) record(ctx context.Context, update func(context.Context, uuid.UUID, time.Time) (Email, error), outboxID uuid.UUID, event string) error {
+	email, err := update(ctx, outboxID, s.now().UTC())
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if s.recorder != nil {
+		s.recorder.RecordEmailEngagement(ctx, email, event)
+	}
+	return nil
+}
+
+// Engagement is the open and click rate of emails. Rates are fractions of the
+// emails sent between 0 and 1.
+type Engagement struct {
+	Category  string  `json:"category,omitempty"`
+	Sent      int64   `json:"sent"`
+	Opened    int64   `json:"opened"`
+	Clicked   int64   `json:"clicked"`
+	Opens     int64   `json:"opens"`
+	Clicks    int64   `json:"clicks"`
+	OpenRate  float64 `json:"openRate"`
+	ClickRate float64 `json:"clickRate"`
+}
+
+type Report struct {
+	From       string       `json:"from"`
+	To         string       `json:"to"`
+	Totals     Engagement   `json:"totals"`
+	Categories []Engagement `json:"categories"`
+}
+
+// Report returns the engagement of the tracked emails an organization sent
+// between the inclusive UTC dates, per workflow trigger. Omitted dates default
+// to the last 30 days.
+func (s *Service) Report(ctx context.Context, organizationID uuid.UUID, fromDate, toDate string) (Report, error) {
+	from, to, err := s.reportRange(fromDate, toDate)
+	if err != nil {
+		return Report{}, err
+	}
+	counts, err := s.repo.ListCategoryCounts(ctx, organizationID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		From:       from.Format(time.DateOnly),
+		To:         to.Format(time.DateOnly),
+		Categories: make([]Engagement, 0, len(counts)),
+	}
+	var totals CategoryCounts
+	for _, c := range counts {
+		report.Categories = append(report.Categories, toEngagement(c))
+		totals.Sent += c.Sent
+		totals.Opened += c.Opened
+		totals.Clicked += c.Clicked
+		totals.Opens += c.Opens
+		totals.Clicks += c.Clicks
+	}
+	report.Totals = toEngagement(totals)
+	return report, nil
+}
+
+func (s *Service) reportRange(fromDate, toDate string) (time.Time, time.Time, error) {
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	to := today
+	if toDate != "" {
+		parsed, err := time.Parse(time.DateOnly, toDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperr.Validation("to must be a date (YYYY-MM-DD)")
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -(defaultReportDays - 1))
+	if fromDate != "" {
+		parsed, err := time.Parse(time.DateOnly, fromDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperr.Validation("from must be a date (YYYY-MM-DD)")
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, apperr.Validation("from must not be after to")
+	}
+	if to.Sub(from) >= maxReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, apperr.Validation("the period cannot exceed a year")
+	}
+	return from, to, nil
+}
+
+func toEngagement(c CategoryCounts) Engagement {
+	return Engagement{
+		Category:  c.Category,
+		Sent:      c.Sent,
+		Opened:    c.Opened,
+		Clicked:   c.Clicked,
+		Opens:     c.Opens,
+		Clicks:    c.Clicks,
+		OpenRate:  rate(c.Opened, c.Sent),
+		ClickRate: rate(c.Clicked, c.Sent),
+	}
+}
+
+func rate(count, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(count)/float64(total)*1000) / 1000
+}
@@ -0,0 +1,35 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportRangeDefaultsToLast30Days(t *testing.T) {
+	s := &Service{now: func() time.Time { return time.Date(2026, time.March, 31, 15, 0, 0, 0, time.UTC) }}
+
+	from, to, err := s.reportRange("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := from.Format(time.DateOnly); got != "2026-03-02" {
+		t.Fatalf("expected from 2026-03-02, got %s", got)
+	}
+	if got := to.Format(time.DateOnly); got != "2026-03-31" {
+		t.Fatalf("expected to 2026-03-31, got %s", got)
+	}
+
+	if _, _, err := s.reportRange("2026-04-01", "2026-03-01"); err == nil {
+		t.Fatal("expected an error when from is after to")
+	}
+}
+
+func TestToEngagementRates(t *testing.T) {
+	got := toEngagement(CategoryCounts{Category: "quote_sent", Sent: 3, Opened: 2, Clicked: 1, Opens: 5, Clicks: 1})
+	if got.OpenRate != 0.667 || got.ClickRate != 0.333 {
+		t.Fatalf("unexpected rates: open %v, click %v", got.OpenRate, got.ClickRate)
+	}
+	if empty := toEngagement(CategoryCounts{}); empty.OpenRate != 0 || empty.ClickRate != 0 {
+		t.Fatalf("expected zero rates without emails, got %+v", empty)
+	}
+}
@@ -0,0 +1,29 @@
+package notification
+
+import (
+	"context"
+
+	notificationoutbox "portal_final_backend/internal/notification/outbox"
+
+	"github.com/google/uuid"
+)
+
+// LeadTriageRecorder queues leads that need a human in the triage inbox.
+type LeadTriageRecorder interface {
+	RaiseMessageUndeliverable(ctx context.Context, organizationID, leadID uuid.UUID, channel string, reason string) error
+}
+
+// SetLeadTriageRecorder injects the triage inbox that undeliverable lead
+// messages are reported to.
+func (m *Module) SetLeadTriageRecorder(recorder LeadTriageRecorder) { m.triageRecorder = recorder }
+
+// reportUndeliverable queues the lead of an outbox email or WhatsApp message
+// that failed for good, so someone can check its contact details.
+func (m *Module) reportUndeliverable(ctx context.Context, rec notificationoutbox.Record, reason string) {
+	if m.triageRecorder == nil || rec.LeadID == nil || (rec.Kind != "email" && rec.Kind != "whatsapp") {
+		return
+	}
+	if err := m.triageRecorder.RaiseMessageUndeliverable(ctx, rec.TenantID, *rec.LeadID, rec.Kind, reason); err != nil {
+		m.log.Warn("failed to report undeliverable message", "outboxId", rec.ID.String(), "leadId", rec.LeadID.String(), "error", err)
+	}
+}
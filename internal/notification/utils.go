@@ -2,6 +2,7 @@ package notification
 
 import (
 	"context"
+	"encoding/json"
 	notificationdb "portal_final_backend/internal/notification/db"
 	"strings"
 	"time"
@@ -47,7 +48,7 @@ func (m *Module) resolveOrganizationName(ctx context.Context, orgID uuid.UUID) s
 	return name
 }
 
-// resolveLeadDetails fetches first/last name, address and service type for a lead.
+// resolveLeadDetails fetches first/last name, address, service type and custom fields for a lead.
 func (m *Module) resolveLeadDetails(ctx context.Context, leadID uuid.UUID, orgID uuid.UUID) *leadDetails {
 	if m.queries == nil || leadID == uuid.Nil {
 		return nil
@@ -59,17 +60,22 @@ func (m *Module) resolveLeadDetails(ctx context.Context, leadID uuid.UUID, orgID
 	if err != nil {
 		return nil
 	}
+	customFields := map[string]any{}
+	if len(row.CustomFields) > 0 {
+		_ = json.Unmarshal(row.CustomFields, &customFields)
+	}
 	return &leadDetails{
-		FirstName:   row.ConsumerFirstName,
-		LastName:    row.ConsumerLastName,
-		Phone:       row.ConsumerPhone,
-		Email:       optionalTextValue(row.ConsumerEmail),
-		Street:      row.AddressStreet,
-		HouseNumber: row.AddressHouseNumber,
-		ZipCode:     row.AddressZipCode,
-		City:        row.AddressCity,
-		ServiceType: row.ServiceType,
-		PublicToken: optionalTextValue(row.PublicToken),
+		FirstName:    row.ConsumerFirstName,
+		LastName:     row.ConsumerLastName,
+		Phone:        row.ConsumerPhone,
+		Email:        optionalTextValue(row.ConsumerEmail),
+		Street:       row.AddressStreet,
+		HouseNumber:  row.AddressHouseNumber,
+		ZipCode:      row.AddressZipCode,
+		City:         row.AddressCity,
+		ServiceType:  row.ServiceType,
+		PublicToken:  optionalTextValue(row.PublicToken),
+		CustomFields: customFields,
 	}
 }
 
@@ -85,8 +91,8 @@ func (m *Module) buildURL(path string, tokenValue string) string {
 	return base + path + "?token=" + tokenValue
 }
 
-func (m *Module) buildPublicURL(path string, tokenValue string) string {
-	base := strings.TrimRight(m.cfg.GetPublicBaseURL(), "/")
+func (m *Module) buildPublicURL(ctx context.Context, orgID uuid.UUID, path string, tokenValue string) string {
+	base := m.publicBaseURL(ctx, orgID)
 	return base + path + "/" + tokenValue
 }
 
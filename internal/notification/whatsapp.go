@@ -4,15 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	htmlstd "html"
+	"portal_final_backend/internal/notification/suppression"
 	"portal_final_backend/internal/whatsapp"
 	"portal_final_backend/platform/apperr"
 	"portal_final_backend/platform/phone"
+	"portal_final_backend/platform/sanitize"
 	"strings"
 	"time"
 
-	htmlnode "golang.org/x/net/html"
-
 	"github.com/google/uuid"
 )
 
@@ -85,6 +84,7 @@ type whatsAppSendOutboxPayload struct {
 	Message     string         `json:"message"`
 	Category    string         `json:"category"`
 	Audience    string         `json:"audience"`
+	Purpose     string         `json:"purpose,omitempty"`
 	Summary     string         `json:"summary"`
 	ActorType   string         `json:"actorType"`
 	ActorName   string         `json:"actorName"`
@@ -92,128 +92,7 @@ type whatsAppSendOutboxPayload struct {
 }
 
 func normalizeWhatsAppMessage(value string) string {
-	text := strings.TrimSpace(value)
-	if text == "" {
-		return ""
-	}
-
-	text = htmlToWhatsAppMarkdown(text)
-	text = htmlstd.UnescapeString(text)
-	text = strings.ReplaceAll(text, "\u00a0", " ")
-
-	var b strings.Builder
-	b.Grow(len(text))
-
-	writeWhatsAppNormalizedBytes(&b, text)
-
-	return strings.TrimSpace(b.String())
-}
-
-func writeWhatsAppNormalizedBytes(b *strings.Builder, text string) {
-	blankCount := 0
-	inWord := false
-	lineEmpty := true
-
-	for i := 0; i < len(text); i++ {
-		c := text[i]
-		if c == '\n' {
-			blankCount, lineEmpty, inWord = handleWhatsAppNewline(b, blankCount, lineEmpty)
-			continue
-		}
-
-		if c == ' ' || c == '\t' || c == '\r' {
-			inWord = false
-			continue
-		}
-
-		if !inWord && !lineEmpty {
-			b.WriteByte(' ')
-		}
-		b.WriteByte(c)
-		inWord = true
-		lineEmpty = false
-	}
-}
-
-func handleWhatsAppNewline(b *strings.Builder, blankCount int, lineEmpty bool) (int, bool, bool) {
-	if lineEmpty {
-		blankCount++
-		if blankCount <= 1 && b.Len() > 0 {
-			b.WriteByte('\n')
-		}
-	} else {
-		blankCount = 0
-		b.WriteByte('\n')
-	}
-	return blankCount, true, false
-}
-
-func htmlToWhatsAppMarkdown(input string) string {
-	raw := strings.TrimSpace(input)
-	if raw == "" {
-		return ""
-	}
-
-	doc, err := htmlnode.Parse(strings.NewReader(raw))
-	if err != nil {
-		return htmlstd.UnescapeString(raw)
-	}
-
-	var b strings.Builder
-	for child := doc.FirstChild; child != nil; child = child.NextSibling {
-		appendWhatsAppNode(&b, child)
-	}
-
-	return b.String()
-}
-
-func appendWhatsAppNode(b *strings.Builder, node *htmlnode.Node) {
-	if node == nil {
-		return
-	}
-
-	switch node.Type {
-	case htmlnode.TextNode:
-		b.WriteString(htmlstd.UnescapeString(node.Data))
-	case htmlnode.ElementNode:
-		tag := strings.ToLower(node.Data)
-		switch tag {
-		case "br":
-			b.WriteString("\n")
-		case "strong", "b":
-			appendWrappedWhatsAppNode(b, node, "*")
-		case "em", "i":
-			appendWrappedWhatsAppNode(b, node, "_")
-		case "del", "s", "strike":
-			appendWrappedWhatsAppNode(b, node, "~")
-		case "p", "div", "li":
-			appendWhatsAppChildren(b, node)
-			b.WriteString("\n")
-		default:
-			appendWhatsAppChildren(b, node)
-		}
-	default:
-		appendWhatsAppChildren(b, node)
-	}
-}
-
-func appendWhatsAppChildren(b *strings.Builder, node *htmlnode.Node) {
-	for child := node.FirstChild; child != nil; child = child.NextSibling {
-		appendWhatsAppNode(b, child)
-	}
-}
-
-func appendWrappedWhatsAppNode(b *strings.Builder, node *htmlnode.Node, marker string) {
-	var inner strings.Builder
-	appendWhatsAppChildren(&inner, node)
-	content := inner.String()
-	if strings.TrimSpace(content) == "" {
-		b.WriteString(content)
-		return
-	}
-	b.WriteString(marker)
-	b.WriteString(content)
-	b.WriteString(marker)
+	return sanitize.WhatsAppText(value)
 }
 
 type whatsAppBestEffortParams struct {
@@ -235,6 +114,9 @@ func (m *Module) sendWhatsAppBestEffort(params whatsAppBestEffortParams) error {
 	if m.whatsapp == nil || params.PhoneNumber == "" {
 		return nil
 	}
+	if suppressed, err := m.isAddressSuppressed(params.Ctx, params.OrgID, suppression.KindPhone, params.PhoneNumber); err != nil || suppressed {
+		return err
+	}
 
 	deviceID := m.resolveWhatsAppDeviceID(params.Ctx, params.OrgID)
 	result, err := m.whatsapp.SendMessage(params.Ctx, deviceID, params.PhoneNumber, params.Message)
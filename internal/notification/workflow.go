@@ -19,10 +19,54 @@ type WorkflowResolver interface {
 }
 
 type workflowRule struct {
+	WorkflowID      uuid.UUID
+	StepID          uuid.UUID
 	Enabled         bool
 	DelayMinutes    int
 	TemplateSubject *string
 	TemplateText    *string
+	SendCondition   *string
+	// Language is the language the templates are written in and amounts and
+	// dates are formatted for.
+	Language string
+	// VariantKey is the A/B template variant the lead was assigned; empty
+	// when the step is not being tested.
+	VariantKey string
+}
+
+func (r *workflowRule) sendCondition() *string {
+	if r == nil {
+		return nil
+	}
+	return r.SendCondition
+}
+
+func (r *workflowRule) workflowID() uuid.UUID {
+	if r == nil {
+		return uuid.Nil
+	}
+	return r.WorkflowID
+}
+
+func (r *workflowRule) stepID() uuid.UUID {
+	if r == nil {
+		return uuid.Nil
+	}
+	return r.StepID
+}
+
+func (r *workflowRule) language() string {
+	if r == nil {
+		return ""
+	}
+	return r.Language
+}
+
+func (r *workflowRule) variantKey() string {
+	if r == nil {
+		return ""
+	}
+	return r.VariantKey
 }
 
 type workflowStepExecutionContext struct {
@@ -37,7 +81,12 @@ type workflowStepExecutionContext struct {
 	DefaultSummary string
 	DefaultActor   string
 	DefaultOrigin  string
-	Variables      map[string]any
+	// Language selects the step's template translation and the formatting of
+	// amounts and dates; empty means the default language.
+	Language string
+	// VariantKey is the A/B template variant the message was rendered from.
+	VariantKey string
+	Variables  map[string]any
 }
 
 type workflowStepDispatchContext struct {
@@ -119,11 +168,19 @@ func (m *Module) resolveWorkflowRule(
 			"templateBodyLen", bodyLen,
 			"templateBodyTrimLen", bodyTrimLen,
 		)
+		language := m.resolveLanguage(ctx, orgID, &leadID, audience)
+		variantStep, variantKey := applyStepVariant(step, language, &leadID)
+		subject, body := localizedStepTemplates(variantStep, language)
 		return &workflowRule{
+			WorkflowID:      resolved.Workflow.ID,
+			StepID:          step.ID,
 			Enabled:         step.Enabled,
 			DelayMinutes:    step.DelayMinutes,
-			TemplateSubject: step.TemplateSubject,
-			TemplateText:    step.TemplateBody,
+			TemplateSubject: subject,
+			TemplateText:    body,
+			SendCondition:   step.SendCondition,
+			Language:        language,
+			VariantKey:      variantKey,
 		}
 	}
 
@@ -149,11 +206,20 @@ func (m *Module) enqueueWorkflowSteps(ctx context.Context, steps []repository.Wo
 		return sorted[i].StepOrder < sorted[j].StepOrder
 	})
 
+	var conditionVars map[string]any
+	if hasWorkflowStepConditions(sorted) {
+		conditionVars = m.buildWorkflowConditionVariables(ctx, execCtx)
+	}
+
 	for _, step := range sorted {
 		if !step.Enabled {
 			m.log.Debug("skipping disabled workflow step", "orgId", execCtx.OrgID, "stepId", step.ID, "trigger", execCtx.Trigger)
 			continue
 		}
+		if !m.workflowStepConditionMet(step, conditionVars, execCtx) {
+			m.log.Info("workflow step condition not met; skipping", "orgId", execCtx.OrgID, "stepId", step.ID, "trigger", execCtx.Trigger)
+			continue
+		}
 		if err := m.enqueueSingleWorkflowStep(ctx, step, execCtx); err != nil {
 			return err
 		}
@@ -164,10 +230,15 @@ func (m *Module) enqueueWorkflowSteps(ctx context.Context, steps []repository.Wo
 }
 
 func (m *Module) enqueueSingleWorkflowStep(ctx context.Context, step repository.WorkflowStep, execCtx workflowStepExecutionContext) error {
+	step, variantKey := applyStepVariant(step, execCtx.Language, execCtx.LeadID)
+	if variantKey != "" {
+		execCtx.VariantKey = variantKey
+	}
 	runAt := time.Now().UTC().Add(time.Duration(step.DelayMinutes) * time.Minute)
 	vars := buildWorkflowStepVariables(execCtx)
 
-	body, err := renderStepTemplate(step.TemplateBody, vars)
+	_, bodyTemplate := localizedStepTemplates(step, execCtx.Language)
+	body, err := renderStepTemplate(bodyTemplate, vars, execCtx.Language)
 	if err != nil {
 		return err
 	}
@@ -213,6 +284,7 @@ func (m *Module) enqueueWhatsAppWorkflowStep(ctx context.Context, dispatchCtx wo
 		m.log.Debug("workflow whatsapp step has no recipients", "orgId", dispatchCtx.Exec.OrgID, "trigger", dispatchCtx.Exec.Trigger, "stepId", dispatchCtx.Step.ID)
 		return nil
 	}
+	message = m.shortenPortalLinks(ctx, dispatchCtx.Exec, message)
 	for _, phoneNumber := range phones {
 		payload := whatsAppSendOutboxPayload{
 			OrgID:       dispatchCtx.Exec.OrgID.String(),
@@ -222,10 +294,12 @@ func (m *Module) enqueueWhatsAppWorkflowStep(ctx context.Context, dispatchCtx wo
 			Message:     message,
 			Category:    dispatchCtx.Category,
 			Audience:    dispatchCtx.Audience,
+			Purpose:     workflowMessagePurpose(dispatchCtx.Exec.Trigger),
 			Summary:     dispatchCtx.Summary,
 			ActorType:   dispatchCtx.ActorType,
 			ActorName:   dispatchCtx.ActorName,
 		}
+		runAt := m.scheduleWithinMessagingPolicy(ctx, dispatchCtx.Exec.OrgID, dispatchCtx.Exec.LeadID, "whatsapp", dispatchCtx.RunAt)
 		rec, err := m.notificationOutbox.Insert(ctx, notificationoutbox.InsertParams{
 			TenantID:  dispatchCtx.Exec.OrgID,
 			LeadID:    dispatchCtx.Exec.LeadID,
@@ -233,12 +307,13 @@ func (m *Module) enqueueWhatsAppWorkflowStep(ctx context.Context, dispatchCtx wo
 			Kind:      "whatsapp",
 			Template:  "whatsapp_send",
 			Payload:   payload,
-			RunAt:     dispatchCtx.RunAt,
+			RunAt:     runAt,
 		})
 		if err != nil {
 			return err
 		}
-		m.log.Info("outbox message enqueued", "outboxId", rec.String(), "kind", "whatsapp", "template", "whatsapp_send", "orgId", dispatchCtx.Exec.OrgID, "trigger", dispatchCtx.Exec.Trigger, "runAt", dispatchCtx.RunAt)
+		m.recordWorkflowMessage(ctx, rec, dispatchCtx)
+		m.log.Info("outbox message enqueued", "outboxId", rec.String(), "kind", "whatsapp", "template", "whatsapp_send", "orgId", dispatchCtx.Exec.OrgID, "trigger", dispatchCtx.Exec.Trigger, "runAt", runAt)
 	}
 
 	return nil
@@ -249,7 +324,8 @@ func (m *Module) enqueueEmailWorkflowStep(
 	vars map[string]any,
 	dispatchCtx workflowStepDispatchContext,
 ) error {
-	subject, err := renderStepTemplate(dispatchCtx.Step.TemplateSubject, vars)
+	subjectTemplate, _ := localizedStepTemplates(dispatchCtx.Step, dispatchCtx.Exec.Language)
+	subject, err := renderStepTemplate(subjectTemplate, vars, dispatchCtx.Exec.Language)
 	if err != nil {
 		return err
 	}
@@ -272,8 +348,12 @@ func (m *Module) enqueueEmailWorkflowStep(
 			BodyHTML:    dispatchCtx.Body,
 			LeadID:      ptrUUIDString(dispatchCtx.Exec.LeadID),
 			ServiceID:   ptrUUIDString(dispatchCtx.Exec.ServiceID),
+			Audience:    dispatchCtx.Audience,
+			Purpose:     workflowMessagePurpose(dispatchCtx.Exec.Trigger),
+			Category:    dispatchCtx.Category,
 			Attachments: attachments,
 		}
+		runAt := m.scheduleWithinMessagingPolicy(ctx, dispatchCtx.Exec.OrgID, dispatchCtx.Exec.LeadID, "email", dispatchCtx.RunAt)
 		rec, err := m.notificationOutbox.Insert(ctx, notificationoutbox.InsertParams{
 			TenantID:  dispatchCtx.Exec.OrgID,
 			LeadID:    dispatchCtx.Exec.LeadID,
@@ -281,12 +361,13 @@ func (m *Module) enqueueEmailWorkflowStep(
 			Kind:      "email",
 			Template:  "email_send",
 			Payload:   payload,
-			RunAt:     dispatchCtx.RunAt,
+			RunAt:     runAt,
 		})
 		if err != nil {
 			return err
 		}
-		m.log.Info("outbox message enqueued", "outboxId", rec.String(), "kind", "email", "template", "email_send", "orgId", dispatchCtx.Exec.OrgID, "trigger", dispatchCtx.Exec.Trigger, "runAt", dispatchCtx.RunAt)
+		m.recordWorkflowMessage(ctx, rec, dispatchCtx)
+		m.log.Info("outbox message enqueued", "outboxId", rec.String(), "kind", "email", "template", "email_send", "orgId", dispatchCtx.Exec.OrgID, "trigger", dispatchCtx.Exec.Trigger, "runAt", runAt)
 	}
 
 	return nil
@@ -330,6 +411,7 @@ func buildWorkflowStepVariables(execCtx workflowStepExecutionContext) map[string
 		"offer": map[string]any{
 			"id": "",
 		},
+		"locale": "",
 	}
 
 	return mergeWorkflowTemplateVars(vars, execCtx.Variables)
@@ -378,14 +460,14 @@ func renderWorkflowTemplateTextWithError(rule *workflowRule, vars map[string]any
 	if rule == nil || rule.TemplateText == nil {
 		return "", nil
 	}
-	return renderStepTemplate(rule.TemplateText, vars)
+	return renderStepTemplate(rule.TemplateText, vars, rule.Language)
 }
 
 func renderWorkflowTemplateSubjectWithError(rule *workflowRule, vars map[string]any) (string, error) {
 	if rule == nil || rule.TemplateSubject == nil {
 		return "", nil
 	}
-	return renderStepTemplate(rule.TemplateSubject, vars)
+	return renderStepTemplate(rule.TemplateSubject, vars, rule.Language)
 }
 
 func resolveWorkflowStepPhoneRecipients(config map[string]any, execCtx workflowStepExecutionContext) []string {
@@ -474,3 +556,171 @@ func (m *Module) dispatchJobCompletedWorkflows(ctx context.Context, e events.Pip
 
 	m.log.Info("job_completed workflows dispatched", "leadId", e.LeadID, "orgId", e.TenantID)
 }
+
+// dispatchWorkOrderCompletedWorkflows asks the customer to confirm the
+// completed work through the public confirmation link.
+func (m *Module) dispatchWorkOrderCompletedWorkflows(ctx context.Context, e events.WorkOrderCompleted) {
+	details := m.resolveLeadDetails(ctx, e.LeadID, e.OrganizationID)
+	orgName := m.resolveOrganizationName(ctx, e.OrganizationID)
+	confirmURL := m.buildPublicURL(ctx, e.OrganizationID, "/work-order", e.ConfirmationToken)
+
+	leadName := "klant"
+	leadPhone := ""
+	leadEmail := ""
+	if details != nil {
+		if n := strings.TrimSpace(details.FirstName + " " + details.LastName); n != "" {
+			leadName = n
+		}
+		leadPhone = details.Phone
+		leadEmail = details.Email
+	}
+
+	templateVars := map[string]any{
+		"lead":      map[string]any{"name": leadName, "phone": leadPhone, "email": leadEmail},
+		"org":       map[string]any{"name": orgName},
+		"workOrder": map[string]any{"id": e.WorkOrderID.String()},
+		"links":     map[string]any{"confirm": confirmURL},
+	}
+	enrichLeadVars(templateVars, details)
+
+	serviceID := e.LeadServiceID
+
+	whatsAppRule := m.resolveWorkflowRule(ctx, e.OrganizationID, e.LeadID, "work_order_completed", "whatsapp", "lead", nil)
+	m.dispatchQuoteWhatsAppWorkflow(ctx, dispatchQuoteWhatsAppWorkflowParams{
+		Rule:         whatsAppRule,
+		OrgID:        e.OrganizationID,
+		LeadID:       &e.LeadID,
+		ServiceID:    &serviceID,
+		LeadPhone:    leadPhone,
+		Trigger:      "work_order_completed",
+		TemplateVars: templateVars,
+		Summary:      fmt.Sprintf("WhatsApp opleverbevestiging verstuurd naar %s", leadName),
+		FallbackNote: "failed to enqueue work_order_completed lead whatsapp workflow",
+	})
+
+	emailRule := m.resolveWorkflowRule(ctx, e.OrganizationID, e.LeadID, "work_order_completed", "email", "lead", nil)
+	m.dispatchQuoteEmailWorkflow(ctx, dispatchQuoteEmailWorkflowParams{
+		Rule:         emailRule,
+		OrgID:        e.OrganizationID,
+		LeadID:       &e.LeadID,
+		ServiceID:    &serviceID,
+		LeadEmail:    leadEmail,
+		Trigger:      "work_order_completed",
+		TemplateVars: templateVars,
+		Summary:      fmt.Sprintf("Email opleverbevestiging verstuurd naar %s", leadName),
+		FallbackNote: "failed to enqueue work_order_completed lead email workflow",
+	})
+
+	m.log.Info("work_order_completed workflows dispatched", "workOrderId", e.WorkOrderID, "leadId", e.LeadID, "orgId", e.OrganizationID)
+}
+
+// dispatchWarrantyExpiringWorkflows offers the customer maintenance before the
+// warranty on the completed work runs out.
+func (m *Module) dispatchWarrantyExpiringWorkflows(ctx context.Context, e events.WarrantyExpiring) {
+	details := m.resolveLeadDetails(ctx, e.LeadID, e.OrganizationID)
+	orgName := m.resolveOrganizationName(ctx, e.OrganizationID)
+
+	leadName := "klant"
+	leadPhone := ""
+	leadEmail := ""
+	if details != nil {
+		if n := strings.TrimSpace(details.FirstName + " " + details.LastName); n != "" {
+			leadName = n
+		}
+		leadPhone = details.Phone
+		leadEmail = details.Email
+	}
+
+	templateVars := map[string]any{
+		"lead": map[string]any{"name": leadName, "phone": leadPhone, "email": leadEmail},
+		"org":  map[string]any{"name": orgName},
+		"warranty": map[string]any{
+			"expiresOn":    e.ExpiresOn.Format("02-01-2006"),
+			"coveredItems": strings.Join(e.CoveredItems, ", "),
+		},
+	}
+	enrichLeadVars(templateVars, details)
+
+	serviceID := e.LeadServiceID
+
+	whatsAppRule := m.resolveWorkflowRule(ctx, e.OrganizationID, e.LeadID, "warranty_expiring", "whatsapp", "lead", nil)
+	m.dispatchQuoteWhatsAppWorkflow(ctx, dispatchQuoteWhatsAppWorkflowParams{
+		Rule:         whatsAppRule,
+		OrgID:        e.OrganizationID,
+		LeadID:       &e.LeadID,
+		ServiceID:    &serviceID,
+		LeadPhone:    leadPhone,
+		Trigger:      "warranty_expiring",
+		TemplateVars: templateVars,
+		Summary:      fmt.Sprintf("WhatsApp garantieherinnering verstuurd naar %s", leadName),
+		FallbackNote: "failed to enqueue warranty_expiring lead whatsapp workflow",
+	})
+
+	emailRule := m.resolveWorkflowRule(ctx, e.OrganizationID, e.LeadID, "warranty_expiring", "email", "lead", nil)
+	m.dispatchQuoteEmailWorkflow(ctx, dispatchQuoteEmailWorkflowParams{
+		Rule:         emailRule,
+		OrgID:        e.OrganizationID,
+		LeadID:       &e.LeadID,
+		ServiceID:    &serviceID,
+		LeadEmail:    leadEmail,
+		Trigger:      "warranty_expiring",
+		TemplateVars: templateVars,
+		Summary:      fmt.Sprintf("Email garantieherinnering verstuurd naar %s", leadName),
+		FallbackNote: "failed to enqueue warranty_expiring lead email workflow",
+	})
+
+	m.log.Info("warranty_expiring workflows dispatched", "warrantyId", e.WarrantyID, "leadId", e.LeadID, "orgId", e.OrganizationID)
+}
+
+func (m *Module) dispatchLeadStaleWorkflows(ctx context.Context, e events.LeadStale) {
+	details := m.resolveLeadDetails(ctx, e.LeadID, e.TenantID)
+	orgName := m.resolveOrganizationName(ctx, e.TenantID)
+
+	leadName := "klant"
+	leadPhone := ""
+	leadEmail := ""
+	if details != nil {
+		if n := strings.TrimSpace(details.FirstName + " " + details.LastName); n != "" {
+			leadName = n
+		}
+		leadPhone = details.Phone
+		leadEmail = details.Email
+	}
+
+	templateVars := map[string]any{
+		"lead":  map[string]any{"name": leadName, "phone": leadPhone, "email": leadEmail},
+		"org":   map[string]any{"name": orgName},
+		"stale": map[string]any{"reason": e.StaleReason},
+	}
+	enrichLeadVars(templateVars, details)
+
+	serviceID := e.LeadServiceID
+
+	whatsAppRule := m.resolveWorkflowRule(ctx, e.TenantID, e.LeadID, "lead_stale", "whatsapp", "lead", nil)
+	m.dispatchQuoteWhatsAppWorkflow(ctx, dispatchQuoteWhatsAppWorkflowParams{
+		Rule:         whatsAppRule,
+		OrgID:        e.TenantID,
+		LeadID:       &e.LeadID,
+		ServiceID:    &serviceID,
+		LeadPhone:    leadPhone,
+		Trigger:      "lead_stale",
+		TemplateVars: templateVars,
+		Summary:      fmt.Sprintf("WhatsApp heractivering verstuurd naar %s", leadName),
+		FallbackNote: "failed to enqueue lead_stale lead whatsapp workflow",
+	})
+
+	emailRule := m.resolveWorkflowRule(ctx, e.TenantID, e.LeadID, "lead_stale", "email", "lead", nil)
+	m.dispatchQuoteEmailWorkflow(ctx, dispatchQuoteEmailWorkflowParams{
+		Rule:         emailRule,
+		OrgID:        e.TenantID,
+		LeadID:       &e.LeadID,
+		ServiceID:    &serviceID,
+		LeadEmail:    leadEmail,
+		Trigger:      "lead_stale",
+		TemplateVars: templateVars,
+		Summary:      fmt.Sprintf("Email heractivering verstuurd naar %s", leadName),
+		FallbackNote: "failed to enqueue lead_stale lead email workflow",
+	})
+
+	m.log.Info("lead_stale workflows dispatched", "leadId", e.LeadID, "orgId", e.TenantID, "reason", e.StaleReason)
+}
@@ -0,0 +1,143 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"portal_final_backend/internal/identity/repository"
+	identityservice "portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/identity/workflowcondition"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// leadConditionFacts are lead attributes send conditions can filter on that
+// are not template variables.
+type leadConditionFacts struct {
+	Score  *int
+	Source string
+}
+
+// workflowStepConditionMet reports whether a step's send condition holds for
+// vars. Steps without a condition always send; a condition that no longer
+// parses never sends.
+func (m *Module) workflowStepConditionMet(step repository.WorkflowStep, vars map[string]any, execCtx workflowStepExecutionContext) bool {
+	if step.SendCondition == nil || strings.TrimSpace(*step.SendCondition) == "" {
+		return true
+	}
+	matched, err := workflowcondition.Evaluate(*step.SendCondition, vars)
+	if err != nil {
+		m.log.Warn("invalid workflow step condition; skipping step", "orgId", execCtx.OrgID, "stepId", step.ID, "trigger", execCtx.Trigger, "error", err)
+		return false
+	}
+	return matched
+}
+
+func hasWorkflowStepConditions(steps []repository.WorkflowStep) bool {
+	for _, step := range steps {
+		if step.SendCondition != nil && strings.TrimSpace(*step.SendCondition) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildWorkflowConditionVariables returns the step variables extended with
+// the lead's details, score and source.
+func (m *Module) buildWorkflowConditionVariables(ctx context.Context, execCtx workflowStepExecutionContext) map[string]any {
+	vars := buildWorkflowStepVariables(execCtx)
+	if execCtx.LeadID == nil {
+		return withLeadConditionFacts(vars, nil, nil)
+	}
+	details := m.resolveLeadDetails(ctx, *execCtx.LeadID, execCtx.OrgID)
+	facts, err := m.resolveLeadConditionFacts(ctx, *execCtx.LeadID, execCtx.OrgID, execCtx.ServiceID)
+	if err != nil {
+		m.log.Warn("failed to resolve lead condition facts", "orgId", execCtx.OrgID, "leadId", execCtx.LeadID, "error", err)
+	}
+	return withLeadConditionFacts(vars, details, facts)
+}
+
+func withLeadConditionFacts(vars map[string]any, details *leadDetails, facts *leadConditionFacts) map[string]any {
+	enrichLeadVars(vars, details)
+	lead, ok := vars["lead"].(map[string]any)
+	if !ok {
+		lead = map[string]any{}
+		vars["lead"] = lead
+	}
+	if details != nil {
+		if strings.TrimSpace(stringFromMap(lead, "email")) == "" {
+			lead["email"] = strings.TrimSpace(details.Email)
+		}
+		if strings.TrimSpace(stringFromMap(lead, "phone")) == "" {
+			lead["phone"] = strings.TrimSpace(details.Phone)
+		}
+	}
+	if _, ok := lead["score"]; !ok {
+		lead["score"] = nil
+	}
+	if _, ok := lead["source"]; !ok {
+		lead["source"] = ""
+	}
+	if facts != nil {
+		if facts.Score != nil {
+			lead["score"] = *facts.Score
+		}
+		if strings.TrimSpace(stringFromMap(lead, "source")) == "" {
+			lead["source"] = facts.Source
+		}
+	}
+	return vars
+}
+
+// resolveLeadConditionFacts reads the lead score and the source of the
+// service the event is about, or of the lead's latest service.
+func (m *Module) resolveLeadConditionFacts(ctx context.Context, leadID, orgID uuid.UUID, serviceID *uuid.UUID) (*leadConditionFacts, error) {
+	if m.pool == nil {
+		return nil, nil
+	}
+	var facts leadConditionFacts
+	err := m.pool.QueryRow(ctx, `
+		SELECT l.lead_score, COALESCE(ls.source, '')
+		FROM RAC_leads l
+		LEFT JOIN LATERAL (
+			SELECT s.source
+			FROM RAC_lead_services s
+			WHERE s.lead_id = l.id AND s.organization_id = l.organization_id
+			ORDER BY (s.id = $3) DESC, s.created_at DESC
+			LIMIT 1
+		) ls ON true
+		WHERE l.id = $1 AND l.organization_id = $2`, leadID, orgID, serviceID,
+	).Scan(&facts.Score, &facts.Source)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &facts, nil
+}
+
+// WorkflowConditionVariables returns the variables a send condition is
+// evaluated against for a lead, so conditions can be tested from the editor.
+func (m *Module) WorkflowConditionVariables(ctx context.Context, input identityservice.WorkflowConditionVariablesInput) (map[string]any, error) {
+	details, err := m.simulationLeadDetails(ctx, identityservice.SimulateWorkflowStepsInput{
+		OrganizationID: input.OrganizationID,
+		LeadID:         input.LeadID,
+		SampleLead:     input.SampleLead,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var facts *leadConditionFacts
+	if input.LeadID != nil {
+		if facts, err = m.resolveLeadConditionFacts(ctx, *input.LeadID, input.OrganizationID, nil); err != nil {
+			return nil, err
+		}
+	}
+	orgName := m.resolveOrganizationName(ctx, input.OrganizationID)
+	vars := buildSimulationVariables(orgName, details, input.Trigger, nil)
+	withLeadConditionFacts(vars, details, facts)
+	return mergeWorkflowTemplateVars(vars, input.Variables), nil
+}
@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// recordWorkflowMessage links an outbox message to the workflow step it was
+// sent for, and the A/B template variant it was rendered from, so workflow
+// analytics can attribute its delivery and engagement.
+// Messages outside a configured workflow step are not recorded.
+func (m *Module) recordWorkflowMessage(ctx context.Context, outboxID uuid.UUID, dispatchCtx workflowStepDispatchContext) {
+	step := dispatchCtx.Step
+	if m.pool == nil || step.WorkflowID == uuid.Nil || step.ID == uuid.Nil {
+		return
+	}
+	if _, err := m.pool.Exec(ctx, `
+		INSERT INTO RAC_workflow_messages (outbox_id, organization_id, workflow_id, step_id, lead_id, channel, trigger, variant_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
+		ON CONFLICT (outbox_id) DO NOTHING`,
+		outboxID, dispatchCtx.Exec.OrgID, step.WorkflowID, step.ID, dispatchCtx.Exec.LeadID,
+		strings.ToLower(strings.TrimSpace(step.Channel)), dispatchCtx.Category, dispatchCtx.Exec.VariantKey,
+	); err != nil {
+		m.log.Error("failed to record workflow message", "outboxId", outboxID, "workflowId", step.WorkflowID, "stepId", step.ID, "error", err)
+	}
+}
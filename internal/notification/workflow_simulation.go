@@ -0,0 +1,233 @@
+package notification
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	identityservice "portal_final_backend/internal/identity/service"
+	"portal_final_backend/internal/identity/workflowcondition"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/i18n"
+
+	"github.com/google/uuid"
+)
+
+var templateVariablePattern = regexp.MustCompile(`{{\s*\.?([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*)\s*}}`)
+
+// SimulateWorkflowSteps renders workflow steps with the variables and
+// recipients a real dispatch would use, without enqueueing anything.
+func (m *Module) SimulateWorkflowSteps(ctx context.Context, input identityservice.SimulateWorkflowStepsInput) ([]identityservice.SimulatedWorkflowStep, error) {
+	details, err := m.simulationLeadDetails(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	var facts *leadConditionFacts
+	if input.LeadID != nil {
+		if facts, err = m.resolveLeadConditionFacts(ctx, *input.LeadID, input.OrganizationID, nil); err != nil {
+			return nil, err
+		}
+	}
+	orgName := m.resolveOrganizationName(ctx, input.OrganizationID)
+
+	simulated := make([]identityservice.SimulatedWorkflowStep, 0, len(input.Steps))
+	for _, step := range input.Steps {
+		audience := defaultName(strings.TrimSpace(step.Audience), "lead")
+		language := m.simulationLanguage(ctx, input, audience)
+		vars := localizeTemplateVars(buildSimulationVariables(orgName, details, step.Trigger, input.Variables), language)
+		execCtx := workflowStepExecutionContext{
+			OrgID:        input.OrganizationID,
+			LeadPhone:    stringFromNestedMap(vars, "lead", "phone"),
+			LeadEmail:    stringFromNestedMap(vars, "lead", "email"),
+			PartnerPhone: stringFromNestedMap(vars, "partner", "phone"),
+			PartnerEmail: stringFromNestedMap(vars, "partner", "email"),
+		}
+		result := identityservice.SimulatedWorkflowStep{
+			StepOrder:    step.StepOrder,
+			Trigger:      step.Trigger,
+			Channel:      strings.ToLower(strings.TrimSpace(step.Channel)),
+			Audience:     audience,
+			Language:     language,
+			Enabled:      step.Enabled,
+			DelayMinutes: step.DelayMinutes,
+			SendAt:       input.StartAt.Add(time.Duration(step.DelayMinutes) * time.Minute),
+		}
+		if step.ID != uuid.Nil {
+			stepID := step.ID
+			result.StepID = &stepID
+		}
+
+		subjectTemplate, bodyTemplate := localizedStepTemplates(step, language)
+		body, err := renderStepTemplate(bodyTemplate, vars, language)
+		if err != nil {
+			result.TemplateErrors = append(result.TemplateErrors, "body: "+err.Error())
+		}
+		result.Body = body
+		if result.Channel == "email" {
+			subject, err := renderStepTemplate(subjectTemplate, vars, language)
+			if err != nil {
+				result.TemplateErrors = append(result.TemplateErrors, "subject: "+err.Error())
+			}
+			result.Subject = subject
+		}
+		result.UnresolvedVariables, result.EmptyVariables = analyzeTemplateVariables(vars, subjectTemplate, bodyTemplate)
+
+		result.ConditionMet = true
+		if step.SendCondition != nil && strings.TrimSpace(*step.SendCondition) != "" {
+			result.SendCondition = step.SendCondition
+			conditionVars := withLeadConditionFacts(buildSimulationVariables(orgName, details, step.Trigger, input.Variables), details, facts)
+			matched, err := workflowcondition.Evaluate(*step.SendCondition, conditionVars)
+			if err != nil {
+				result.TemplateErrors = append(result.TemplateErrors, "condition: "+err.Error())
+			}
+			result.ConditionMet = matched
+		}
+
+		switch result.Channel {
+		case "whatsapp":
+			result.Body = normalizeWhatsAppMessage(result.Body)
+			result.Recipients = resolveWorkflowStepPhoneRecipients(step.RecipientConfig, execCtx)
+		case "email":
+			result.Recipients = resolveWorkflowStepEmailRecipients(step.RecipientConfig, execCtx)
+		}
+		result.SkipReason = simulationSkipReason(result)
+		result.Skipped = result.SkipReason != ""
+
+		simulated = append(simulated, result)
+	}
+	return simulated, nil
+}
+
+// simulationLanguage returns the requested simulation language, or the
+// language a real dispatch to audience would use.
+func (m *Module) simulationLanguage(ctx context.Context, input identityservice.SimulateWorkflowStepsInput, audience string) string {
+	if input.Language != nil {
+		if language, ok := i18n.Normalize(*input.Language); ok {
+			return language
+		}
+	}
+	return m.resolveLanguage(ctx, input.OrganizationID, input.LeadID, audience)
+}
+
+func (m *Module) simulationLeadDetails(ctx context.Context, input identityservice.SimulateWorkflowStepsInput) (*leadDetails, error) {
+	if input.LeadID != nil {
+		details := m.resolveLeadDetails(ctx, *input.LeadID, input.OrganizationID)
+		if details == nil {
+			return nil, apperr.NotFound("lead not found")
+		}
+		return details, nil
+	}
+	if input.SampleLead == nil {
+		return nil, nil
+	}
+	sample := input.SampleLead
+	return &leadDetails{
+		FirstName:   sample.FirstName,
+		LastName:    sample.LastName,
+		Phone:       sample.Phone,
+		Email:       sample.Email,
+		Street:      sample.Street,
+		HouseNumber: sample.HouseNumber,
+		ZipCode:     sample.ZipCode,
+		City:        sample.City,
+		ServiceType: sample.ServiceType,
+	}, nil
+}
+
+// buildSimulationVariables builds the variables a step of trigger would be
+// rendered with. Variables the event would fill in are empty unless the
+// caller provides them.
+func buildSimulationVariables(orgName string, details *leadDetails, trigger string, overrides map[string]any) map[string]any {
+	lead := map[string]any{}
+	if details != nil {
+		lead["name"] = strings.TrimSpace(details.FirstName + " " + details.LastName)
+		lead["phone"] = strings.TrimSpace(details.Phone)
+		lead["email"] = strings.TrimSpace(details.Email)
+	}
+	vars := buildWorkflowStepVariables(workflowStepExecutionContext{
+		Variables: map[string]any{
+			"lead": lead,
+			"org":  map[string]any{"name": orgName},
+		},
+	})
+	enrichLeadVars(vars, details)
+	withTriggerVariables(vars, trigger)
+	return mergeWorkflowTemplateVars(vars, overrides)
+}
+
+// analyzeTemplateVariables lists the placeholders of the templates that do
+// not exist for the trigger (unresolved) and those that exist but render
+// empty for this lead.
+func analyzeTemplateVariables(vars map[string]any, templates ...*string) (unresolved []string, empty []string) {
+	seen := make(map[string]struct{})
+	for _, tpl := range templates {
+		if tpl == nil {
+			continue
+		}
+		for _, match := range templateVariablePattern.FindAllStringSubmatch(*tpl, -1) {
+			path := match[1]
+			if _, ok := seen[path]; ok {
+				continue
+			}
+			seen[path] = struct{}{}
+
+			value, found := lookupTemplateVariable(vars, path)
+			switch {
+			case !found:
+				unresolved = append(unresolved, path)
+			case isEmptyTemplateValue(value):
+				empty = append(empty, path)
+			}
+		}
+	}
+	sort.Strings(unresolved)
+	sort.Strings(empty)
+	return unresolved, empty
+}
+
+func lookupTemplateVariable(vars map[string]any, path string) (any, bool) {
+	var current any = vars
+	for _, segment := range strings.Split(path, ".") {
+		next, ok := findCaseInsensitiveMapValue(current, segment)
+		if !ok {
+			return nil, false
+		}
+		current = next.value
+	}
+	return current, true
+}
+
+func isEmptyTemplateValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	default:
+		return false
+	}
+}
+
+// simulationSkipReason mirrors the checks that make a dispatch skip a step.
+func simulationSkipReason(step identityservice.SimulatedWorkflowStep) string {
+	switch {
+	case !step.Enabled:
+		return "step is disabled"
+	case step.Channel != "whatsapp" && step.Channel != "email":
+		return "unsupported channel"
+	case len(step.TemplateErrors) > 0:
+		return "template error"
+	case !step.ConditionMet:
+		return "send condition not met"
+	case step.Channel == "email" && (strings.TrimSpace(step.Subject) == "" || strings.TrimSpace(step.Body) == ""):
+		return "email subject or body is empty"
+	case strings.TrimSpace(step.Body) == "":
+		return "message body is empty"
+	case len(step.Recipients) == 0:
+		return "no recipients"
+	default:
+		return ""
+	}
+}
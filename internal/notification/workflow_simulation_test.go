@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	identityrepo "portal_final_backend/internal/identity/repository"
+	identityservice "portal_final_backend/internal/identity/service"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+func TestSimulateWorkflowStepsRendersAndReportsVariables(t *testing.T) {
+	m := New(nil, &testSender{}, testNotificationConfig{}, logger.New("development"))
+	startAt := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	body := "Hallo {{lead.firstName}}, offerte {{quote.number}} in {{lead.city}}. {{lead.nickname}} {{links.scheduling}}"
+	subject := "Offerte {{quote.number}}"
+	broken := "Hallo {{.lead.firstName"
+
+	steps, err := m.SimulateWorkflowSteps(context.Background(), identityservice.SimulateWorkflowStepsInput{
+		OrganizationID: uuid.New(),
+		Steps: []identityrepo.WorkflowStep{
+			{StepOrder: 1, Trigger: "quote_accepted", Channel: "email", Enabled: true, DelayMinutes: 30, TemplateSubject: &subject, TemplateBody: &body, RecipientConfig: map[string]any{"includeLeadContact": true}},
+			{StepOrder: 2, Trigger: "quote_accepted", Channel: "whatsapp", Enabled: true, TemplateBody: &broken, RecipientConfig: map[string]any{"includeLeadContact": true}},
+		},
+		SampleLead: &identityservice.WorkflowSampleLead{FirstName: "Jan", Email: "jan@example.com", Phone: "+31612345678"},
+		Variables:  map[string]any{"quote": map[string]any{"number": "OFF-1"}},
+		StartAt:    startAt,
+	})
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+
+	email := steps[0]
+	if email.Subject != "Offerte OFF-1" || !strings.HasPrefix(email.Body, "Hallo Jan, offerte OFF-1") {
+		t.Fatalf("unexpected rendering subject=%q body=%q", email.Subject, email.Body)
+	}
+	if !email.SendAt.Equal(startAt.Add(30 * time.Minute)) {
+		t.Fatalf("unexpected send time %s", email.SendAt)
+	}
+	if len(email.UnresolvedVariables) != 1 || email.UnresolvedVariables[0] != "lead.nickname" {
+		t.Fatalf("expected lead.nickname unresolved, got %v", email.UnresolvedVariables)
+	}
+	if strings.Join(email.EmptyVariables, ",") != "lead.city,links.scheduling" {
+		t.Fatalf("unexpected empty variables %v", email.EmptyVariables)
+	}
+	if email.Skipped || len(email.Recipients) != 1 || email.Recipients[0] != "jan@example.com" {
+		t.Fatalf("expected email to be sent to the lead, got skipped=%v recipients=%v", email.Skipped, email.Recipients)
+	}
+
+	whatsApp := steps[1]
+	if len(whatsApp.TemplateErrors) != 1 || !whatsApp.Skipped {
+		t.Fatalf("expected a template error, got %+v", whatsApp)
+	}
+}
+
+func TestWorkflowVariableCatalogCoversTriggerVariables(t *testing.T) {
+	m := New(nil, &testSender{}, testNotificationConfig{}, logger.New("development"))
+
+	catalog := m.WorkflowVariableCatalog()
+	if len(catalog) != len(workflowTriggerOrder) {
+		t.Fatalf("expected %d triggers, got %d", len(workflowTriggerOrder), len(catalog))
+	}
+	for _, entry := range catalog {
+		paths := make(map[string]identityservice.WorkflowVariable, len(entry.Variables))
+		for _, variable := range entry.Variables {
+			paths[variable.Path] = variable
+		}
+		if first := paths["lead.firstName"]; first.Source != workflowVariableSourceBase || first.Example == "" {
+			t.Fatalf("expected lead.firstName base variable for %s, got %+v", entry.Trigger, first)
+		}
+		if entry.Trigger == "quote_accepted" {
+			if _, ok := paths["links.scheduling"]; !ok {
+				t.Fatalf("expected links.scheduling for quote_accepted")
+			}
+			if paths["quote.totalCents"].Type != workflowVariableTypeNumber {
+				t.Fatalf("expected quote.totalCents to be a number, got %+v", paths["quote.totalCents"])
+			}
+		}
+	}
+}
+
+func TestSimulateWorkflowStepsEvaluatesSendConditions(t *testing.T) {
+	m := New(nil, &testSender{}, testNotificationConfig{}, logger.New("development"))
+	body := "Hallo {{lead.firstName}}"
+	hasEmail := "has(lead.email) && lead.serviceType == 'warmtepomp'"
+	highScore := "lead.score >= 50"
+
+	steps, err := m.SimulateWorkflowSteps(context.Background(), identityservice.SimulateWorkflowStepsInput{
+		OrganizationID: uuid.New(),
+		Steps: []identityrepo.WorkflowStep{
+			{StepOrder: 1, Trigger: "lead_welcome", Channel: "whatsapp", Enabled: true, TemplateBody: &body, SendCondition: &hasEmail, RecipientConfig: map[string]any{"includeLeadContact": true}},
+			{StepOrder: 2, Trigger: "lead_welcome", Channel: "whatsapp", Enabled: true, TemplateBody: &body, SendCondition: &highScore, RecipientConfig: map[string]any{"includeLeadContact": true}},
+		},
+		SampleLead: &identityservice.WorkflowSampleLead{FirstName: "Jan", Email: "jan@example.com", Phone: "+31612345678", ServiceType: "Warmtepomp"},
+		StartAt:    time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if !steps[0].ConditionMet || steps[0].Skipped {
+		t.Fatalf("expected first step to be sent, got %+v", steps[0])
+	}
+	if steps[1].ConditionMet || steps[1].SkipReason != "send condition not met" {
+		t.Fatalf("expected second step to be skipped on its condition, got %+v", steps[1])
+	}
+}
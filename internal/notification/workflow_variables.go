@@ -0,0 +1,223 @@
+package notification
+
+import (
+	"sort"
+	"strings"
+
+	identityservice "portal_final_backend/internal/identity/service"
+)
+
+const (
+	workflowVariableTypeString = "string"
+	workflowVariableTypeNumber = "number"
+
+	workflowVariableSourceBase  = "base"
+	workflowVariableSourceEvent = "event"
+)
+
+type workflowVariableSpec struct {
+	Path    string
+	Type    string
+	Example string
+}
+
+// workflowTriggerOrder lists the triggers the notification engine dispatches
+// workflow steps for.
+var workflowTriggerOrder = []string{
+	"lead_welcome",
+	"quote_sent",
+	"quote_accepted",
+	"quote_rejected",
+	"quote_question_asked",
+	"quote_question_answered",
+	"quote_installment_due",
+	"appointment_created",
+	"appointment_updated",
+	"appointment_reminder",
+	"partner_offer_created",
+	"job_completed",
+	"work_order_completed",
+	"warranty_expiring",
+	"lead_stale",
+}
+
+// workflowTriggerVariables lists the template variables each trigger adds on
+// top of buildWorkflowStepVariables when its event is dispatched.
+var workflowTriggerVariables = map[string][]workflowVariableSpec{
+	"lead_welcome": {
+		{Path: "lead.source", Type: workflowVariableTypeString, Example: "website"},
+	},
+	"quote_sent": {
+		{Path: "quote.id", Type: workflowVariableTypeString, Example: "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f"},
+		{Path: "quote.pdfFileKey", Type: workflowVariableTypeString, Example: "quotes/OFF-2026-0042.pdf"},
+	},
+	"quote_accepted": {
+		{Path: "quote.id", Type: workflowVariableTypeString, Example: "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f"},
+		{Path: "quote.totalCents", Type: workflowVariableTypeNumber, Example: "249500"},
+		{Path: "quote.total", Type: workflowVariableTypeString, Example: "€2.495,00"},
+		{Path: "quote.totalFormatted", Type: workflowVariableTypeString, Example: "€2.495,00"},
+		{Path: "quote.pdfFileKey", Type: workflowVariableTypeString, Example: "quotes/OFF-2026-0042.pdf"},
+		{Path: "links.view", Type: workflowVariableTypeString, Example: "https://app.example.com/offerte/abc123"},
+		{Path: "links.download", Type: workflowVariableTypeString, Example: "https://app.example.com/offerte/abc123/pdf"},
+		{Path: "links.scheduling", Type: workflowVariableTypeString, Example: "https://app.example.com/afspraak/abc123"},
+	},
+	"quote_rejected": {
+		{Path: "quote.reason", Type: workflowVariableTypeString, Example: "Te duur"},
+	},
+	"quote_question_asked":    quoteAnnotationVariables,
+	"quote_question_answered": quoteAnnotationVariables,
+	"quote_installment_due": {
+		{Path: "quote.id", Type: workflowVariableTypeString, Example: "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f"},
+		{Path: "installment.label", Type: workflowVariableTypeString, Example: "Tweede termijn"},
+		{Path: "installment.sequence", Type: workflowVariableTypeNumber, Example: "2"},
+		{Path: "installment.amountCents", Type: workflowVariableTypeNumber, Example: "100000"},
+		{Path: "installment.amount", Type: workflowVariableTypeString, Example: "€1.000,00"},
+		{Path: "installment.dueDate", Type: workflowVariableTypeString, Example: "15-04-2026"},
+		{Path: "links.view", Type: workflowVariableTypeString, Example: "https://app.example.com/offerte/abc123"},
+	},
+	"appointment_created":  appointmentVariables,
+	"appointment_updated":  appointmentVariables,
+	"appointment_reminder": appointmentVariables,
+	"partner_offer_created": {
+		{Path: "offer.price", Type: workflowVariableTypeString, Example: "€850,00"},
+		{Path: "offer.priceFormatted", Type: workflowVariableTypeString, Example: "€850,00"},
+		{Path: "offer.priceCents", Type: workflowVariableTypeNumber, Example: "85000"},
+		{Path: "links.accept", Type: workflowVariableTypeString, Example: "https://app.example.com/werkaanbod/abc123"},
+	},
+	"job_completed": {
+		{Path: "org.reviewUrl", Type: workflowVariableTypeString, Example: "https://g.page/r/voorbeeld/review"},
+	},
+	"work_order_completed": {
+		{Path: "workOrder.id", Type: workflowVariableTypeString, Example: "8d7e6f5a-4b3c-4d2e-9f1a-0b9c8d7e6f5a"},
+		{Path: "links.confirm", Type: workflowVariableTypeString, Example: "https://app.example.com/work-order/abc123"},
+	},
+	"warranty_expiring": {
+		{Path: "warranty.expiresOn", Type: workflowVariableTypeString, Example: "01-06-2027"},
+		{Path: "warranty.coveredItems", Type: workflowVariableTypeString, Example: "Omvormer, zonnepanelen"},
+	},
+	"lead_stale": {
+		{Path: "stale.reason", Type: workflowVariableTypeString, Example: "no_activity"},
+	},
+}
+
+var quoteAnnotationVariables = []workflowVariableSpec{
+	{Path: "quote.id", Type: workflowVariableTypeString, Example: "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f"},
+	{Path: "annotation.text", Type: workflowVariableTypeString, Example: "Is de montage inbegrepen?"},
+	{Path: "annotation.authorType", Type: workflowVariableTypeString, Example: "customer"},
+	{Path: "annotation.itemId", Type: workflowVariableTypeString, Example: "0b9a8c7d-6e5f-4a3b-9c2d-1e0f9a8b7c6d"},
+	{Path: "annotation.itemDescription", Type: workflowVariableTypeString, Example: "Warmtepomp installatie"},
+	{Path: "links.view", Type: workflowVariableTypeString, Example: "https://app.example.com/offerte/abc123"},
+}
+
+var appointmentVariables = []workflowVariableSpec{
+	{Path: "appointment.location", Type: workflowVariableTypeString, Example: "Hoofdstraat 12, Utrecht"},
+}
+
+// workflowBaseVariableExamples are the example values of the variables every
+// trigger has.
+var workflowBaseVariableExamples = map[string]string{
+	"lead.name":         "Jan de Vries",
+	"lead.firstName":    "Jan",
+	"lead.lastName":     "de Vries",
+	"lead.phone":        "+31612345678",
+	"lead.email":        "jan@example.com",
+	"lead.address":      "Hoofdstraat 12",
+	"lead.street":       "Hoofdstraat",
+	"lead.houseNumber":  "12",
+	"lead.zipCode":      "3511 AB",
+	"lead.city":         "Utrecht",
+	"lead.serviceType":  "Warmtepomp",
+	"partner.name":      "Installatiebedrijf Bakker",
+	"partner.phone":     "+31687654321",
+	"partner.email":     "info@bakker.example.com",
+	"org.name":          "Voorbeeld B.V.",
+	"quote.number":      "OFF-2026-0042",
+	"quote.previewUrl":  "https://app.example.com/offerte/abc123",
+	"quote.downloadUrl": "https://app.example.com/offerte/abc123/pdf",
+	"links.track":       "https://app.example.com/aanvraag/abc123",
+	"appointment.date":  "15-04-2026",
+	"appointment.time":  "09:30",
+	"offer.id":          "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f",
+	"locale":            "nl",
+}
+
+// WorkflowVariableCatalog returns the template variables available to the
+// steps of each trigger.
+func (m *Module) WorkflowVariableCatalog() []identityservice.WorkflowTriggerVariables {
+	base := workflowBaseVariables()
+	catalog := make([]identityservice.WorkflowTriggerVariables, 0, len(workflowTriggerOrder))
+	for _, trigger := range workflowTriggerOrder {
+		variables := append([]identityservice.WorkflowVariable(nil), base...)
+		for _, spec := range workflowTriggerVariables[trigger] {
+			variables = append(variables, identityservice.WorkflowVariable{
+				Path:    spec.Path,
+				Type:    spec.Type,
+				Example: spec.Example,
+				Source:  workflowVariableSourceEvent,
+			})
+		}
+		catalog = append(catalog, identityservice.WorkflowTriggerVariables{Trigger: trigger, Variables: variables})
+	}
+	return catalog
+}
+
+// workflowBaseVariables derives the variables every trigger has from
+// buildWorkflowStepVariables.
+func workflowBaseVariables() []identityservice.WorkflowVariable {
+	var variables []identityservice.WorkflowVariable
+	var walk func(prefix string, values map[string]any)
+	walk = func(prefix string, values map[string]any) {
+		for key, value := range values {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			if nested, ok := value.(map[string]any); ok {
+				walk(path, nested)
+				continue
+			}
+			variables = append(variables, identityservice.WorkflowVariable{
+				Path:    path,
+				Type:    workflowVariableType(value),
+				Example: workflowBaseVariableExamples[path],
+				Source:  workflowVariableSourceBase,
+			})
+		}
+	}
+	walk("", buildWorkflowStepVariables(workflowStepExecutionContext{}))
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Path < variables[j].Path })
+	return variables
+}
+
+func workflowVariableType(value any) string {
+	switch value.(type) {
+	case int, int32, int64, float64:
+		return workflowVariableTypeNumber
+	default:
+		return workflowVariableTypeString
+	}
+}
+
+// withTriggerVariables adds the variables of trigger that vars does not have
+// yet as empty values, so templates using them count as resolved.
+func withTriggerVariables(vars map[string]any, trigger string) map[string]any {
+	for _, spec := range workflowTriggerVariables[strings.TrimSpace(trigger)] {
+		segments := strings.Split(spec.Path, ".")
+		current := vars
+		for i, segment := range segments {
+			if i == len(segments)-1 {
+				if _, ok := current[segment]; !ok {
+					current[segment] = ""
+				}
+				break
+			}
+			next, ok := current[segment].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				current[segment] = next
+			}
+			current = next
+		}
+	}
+	return vars
+}
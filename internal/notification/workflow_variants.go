@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"portal_final_backend/internal/identity/repository"
+
+	"github.com/google/uuid"
+)
+
+// applyStepVariant assigns the lead one of the step's A/B template variants
+// and returns the step with that variant's templates as its base templates,
+// together with the variant key. Messages without a lead and leads who
+// receive a translated template take no part in the test; for them the step
+// is returned unchanged with an empty key.
+func applyStepVariant(step repository.WorkflowStep, language string, leadID *uuid.UUID) (repository.WorkflowStep, string) {
+	if len(step.TemplateVariants) == 0 || leadID == nil || *leadID == uuid.Nil {
+		return step, ""
+	}
+	if _, translated := step.TemplateTranslations[language]; translated {
+		return step, ""
+	}
+	variant, ok := assignStepVariant(step.TemplateVariants, step.ID, *leadID)
+	if !ok {
+		return step, ""
+	}
+	if variant.TemplateSubject != nil && strings.TrimSpace(*variant.TemplateSubject) != "" {
+		step.TemplateSubject = variant.TemplateSubject
+	}
+	if variant.TemplateBody != nil && strings.TrimSpace(*variant.TemplateBody) != "" {
+		step.TemplateBody = variant.TemplateBody
+	}
+	return step, variant.Key
+}
+
+// assignStepVariant picks a variant in proportion to the weights from a hash
+// of the step and lead, so a lead sees the same variant every time the step
+// runs for them.
+func assignStepVariant(variants []repository.WorkflowStepVariant, stepID, leadID uuid.UUID) (repository.WorkflowStepVariant, bool) {
+	total := 0
+	for _, variant := range variants {
+		if variant.Weight > 0 {
+			total += variant.Weight
+		}
+	}
+	if total == 0 {
+		return repository.WorkflowStepVariant{}, false
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write(stepID[:])
+	_, _ = hash.Write(leadID[:])
+	point := int(hash.Sum32() % uint32(total))
+	for _, variant := range variants {
+		if variant.Weight <= 0 {
+			continue
+		}
+		if point < variant.Weight {
+			return variant, true
+		}
+		point -= variant.Weight
+	}
+	return repository.WorkflowStepVariant{}, false
+}
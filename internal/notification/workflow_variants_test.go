@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/identity/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestApplyStepVariantIsDeterministicPerLead(t *testing.T) {
+	base, bodyA, bodyB := "Basis", "Variant A", "Variant B"
+	step := repository.WorkflowStep{
+		ID:           uuid.New(),
+		TemplateBody: &base,
+		TemplateVariants: []repository.WorkflowStepVariant{
+			{Key: "A", Weight: 50, TemplateBody: &bodyA},
+			{Key: "B", Weight: 50, TemplateBody: &bodyB},
+		},
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		leadID := uuid.New()
+		applied, key := applyStepVariant(step, "nl", &leadID)
+		again, keyAgain := applyStepVariant(step, "nl", &leadID)
+		if key == "" || key != keyAgain || *applied.TemplateBody != *again.TemplateBody {
+			t.Fatalf("applyStepVariant() assigned %q then %q for the same lead", key, keyAgain)
+		}
+		if want := "Variant " + key; *applied.TemplateBody != want {
+			t.Fatalf("applyStepVariant() body = %q, want %q", *applied.TemplateBody, want)
+		}
+		counts[key]++
+	}
+	if counts["A"] < 120 || counts["B"] < 120 {
+		t.Fatalf("applyStepVariant() split = %v, want roughly even", counts)
+	}
+}
+
+func TestApplyStepVariantSkipsTranslatedAndLeadlessMessages(t *testing.T) {
+	body := "Variant A"
+	step := repository.WorkflowStep{
+		ID:                   uuid.New(),
+		TemplateTranslations: map[string]repository.WorkflowStepTranslation{"en": {}},
+		TemplateVariants: []repository.WorkflowStepVariant{
+			{Key: "A", Weight: 1, TemplateBody: &body},
+			{Key: "B", Weight: 1, TemplateBody: &body},
+		},
+	}
+	leadID := uuid.New()
+	if _, key := applyStepVariant(step, "en", &leadID); key != "" {
+		t.Fatalf("applyStepVariant() for a translated language = %q, want no variant", key)
+	}
+	if _, key := applyStepVariant(step, "nl", nil); key != "" {
+		t.Fatalf("applyStepVariant() without lead = %q, want no variant", key)
+	}
+}
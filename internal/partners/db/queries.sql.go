@@ -388,6 +388,7 @@ func (q *Queries) CreatePartnerInvite(ctx context.Context, arg CreatePartnerInvi
 
 const createPartnerOffer = `-- name: CreatePartnerOffer :one
 INSERT INTO RAC_partner_offers (
+	id,
 	organization_id,
 	partner_id,
 	lead_service_id,
@@ -406,16 +407,17 @@ INSERT INTO RAC_partner_offers (
 	$1::uuid,
 	$2::uuid,
 	$3::uuid,
-	$4::text,
-	$5::timestamptz,
-	$6::pricing_source,
-	$7::bigint,
+	$4::uuid,
+	$5::text,
+	$6::timestamptz,
+	$7::pricing_source,
 	$8::bigint,
-	$9::int,
-	$10::jsonb,
-	$11::text,
+	$9::bigint,
+	$10::int,
+	$11::jsonb,
 	$12::text,
-	$13::bool,
+	$13::text,
+	$14::bool,
 	'pending'
 )
 RETURNING id,
@@ -448,6 +450,7 @@ RETURNING id,
 `
 
 type CreatePartnerOfferParams struct {
+	ID                 pgtype.UUID        `json:"id"`
 	OrganizationID     pgtype.UUID        `json:"organization_id"`
 	PartnerID          pgtype.UUID        `json:"partner_id"`
 	LeadServiceID      pgtype.UUID        `json:"lead_service_id"`
@@ -495,6 +498,7 @@ type CreatePartnerOfferRow struct {
 
 func (q *Queries) CreatePartnerOffer(ctx context.Context, arg CreatePartnerOfferParams) (CreatePartnerOfferRow, error) {
 	row := q.db.QueryRow(ctx, createPartnerOffer,
+		arg.ID,
 		arg.OrganizationID,
 		arg.PartnerID,
 		arg.LeadServiceID,
@@ -611,12 +615,14 @@ func (q *Queries) DeletePartnerServiceTypes(ctx context.Context, partnerID pgtyp
 }
 
 const expirePartnerOffers = `-- name: ExpirePartnerOffers :many
-UPDATE RAC_partner_offers
+UPDATE RAC_partner_offers o
 SET status = 'expired',
 	updated_at = now()
-WHERE status IN ('pending', 'sent')
-  AND expires_at < now()
-RETURNING id, organization_id, partner_id, lead_service_id
+WHERE o.status IN ('pending', 'sent')
+  AND o.expires_at < now()
+RETURNING o.id, o.organization_id, o.partner_id, o.lead_service_id,
+	(SELECT ls.lead_id FROM RAC_lead_services ls WHERE ls.id = o.lead_service_id)::uuid AS lead_id,
+	COALESCE((SELECT p.business_name FROM RAC_partners p WHERE p.id = o.partner_id), '')::text AS partner_name
 `
 
 type ExpirePartnerOffersRow struct {
@@ -624,6 +630,8 @@ type ExpirePartnerOffersRow struct {
 	OrganizationID pgtype.UUID `json:"organization_id"`
 	PartnerID      pgtype.UUID `json:"partner_id"`
 	LeadServiceID  pgtype.UUID `json:"lead_service_id"`
+	LeadID         pgtype.UUID `json:"lead_id"`
+	PartnerName    string      `json:"partner_name"`
 }
 
 func (q *Queries) ExpirePartnerOffers(ctx context.Context) ([]ExpirePartnerOffersRow, error) {
@@ -640,6 +648,8 @@ func (q *Queries) ExpirePartnerOffers(ctx context.Context) ([]ExpirePartnerOffer
 			&i.OrganizationID,
 			&i.PartnerID,
 			&i.LeadServiceID,
+			&i.LeadID,
+			&i.PartnerName,
 		); err != nil {
 			return nil, err
 		}
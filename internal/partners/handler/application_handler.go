@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/partners/service"
+	"portal_final_backend/internal/partners/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ApplicationHandler handles the public partner application form and the admin vetting queue.
+type ApplicationHandler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+// NewApplicationHandler creates a new partner application handler.
+func NewApplicationHandler(svc *service.Service, val *validator.Validator) *ApplicationHandler {
+	return &ApplicationHandler{svc: svc, val: val}
+}
+
+// RegisterPublicRoutes mounts the public application form routes (no auth middleware).
+func (h *ApplicationHandler) RegisterPublicRoutes(rg *gin.RouterGroup) {
+	rg.POST("/organizations/:orgId", h.Submit)
+	rg.POST("/:token/documents/presign", h.PresignDocument)
+	rg.POST("/:token/documents", h.ConfirmDocument)
+}
+
+// RegisterAdminRoutes mounts the vetting queue routes.
+func (h *ApplicationHandler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.List)
+	rg.GET("/:id", h.GetByID)
+	rg.GET("/:id/documents/:documentId/download", h.GetDocumentDownload)
+	rg.POST("/:id/approve", h.Approve)
+	rg.POST("/:id/reject", h.Reject)
+}
+
+func (h *ApplicationHandler) Submit(c *gin.Context) {
+	orgID, err := uuid.Parse(c.Param("orgId"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.SubmitPartnerApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.svc.SubmitApplication(c.Request.Context(), orgID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
+
+func (h *ApplicationHandler) PresignDocument(c *gin.Context) {
+	var req transport.PartnerApplicationDocumentPresignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.svc.PresignApplicationDocumentUpload(c.Request.Context(), c.Param("token"), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *ApplicationHandler) ConfirmDocument(c *gin.Context) {
+	var req transport.ConfirmPartnerApplicationDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.svc.ConfirmApplicationDocument(c.Request.Context(), c.Param("token"), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
+
+func (h *ApplicationHandler) List(c *gin.Context) {
+	var req transport.ListPartnerApplicationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListApplications(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *ApplicationHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetApplication(c.Request.Context(), tenantID, id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *ApplicationHandler) GetDocumentDownload(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetApplicationDocumentDownloadURL(c.Request.Context(), tenantID, id, documentID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *ApplicationHandler) Approve(c *gin.Context) {
+	id, req, tenantID, reviewerID, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ApproveApplication(c.Request.Context(), tenantID, id, reviewerID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *ApplicationHandler) Reject(c *gin.Context) {
+	id, req, tenantID, reviewerID, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.RejectApplication(c.Request.Context(), tenantID, id, reviewerID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+func (h *ApplicationHandler) bindReview(c *gin.Context) (uuid.UUID, transport.ReviewPartnerApplicationRequest, uuid.UUID, uuid.UUID, bool) {
+	var req transport.ReviewPartnerApplicationRequest
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return uuid.Nil, req, uuid.Nil, uuid.Nil, false
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return uuid.Nil, req, uuid.Nil, uuid.Nil, false
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return uuid.Nil, req, uuid.Nil, uuid.Nil, false
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return uuid.Nil, req, uuid.Nil, uuid.Nil, false
+	}
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return uuid.Nil, req, uuid.Nil, uuid.Nil, false
+	}
+	return id, req, tenantID, identity.UserID(), true
+}
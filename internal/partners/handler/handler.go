@@ -61,6 +61,10 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 
 	rg.GET("/:id/offers", h.ListPartnerOffers)
 
+	rg.GET("/:id/price-agreements", h.ListPriceAgreements)
+	rg.POST("/:id/price-agreements", h.CreatePriceAgreement)
+	rg.POST("/:id/price-agreements/:agreementId/end", h.EndPriceAgreement)
+
 	// Offer routes (authenticated / admin)
 	rg.POST("/offers/from-quote", h.CreateOfferFromQuote)
 	rg.GET("/offers", h.ListOffers)
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -20,6 +21,8 @@ import (
 type PublicHandler struct {
 	svc *service.Service
 	val *validator.Validator
+	// tokenLocator finds the organization of offer tokens across data schemas.
+	tokenLocator httpkit.PublicTokenResolver
 }
 
 const headerContentType = "Content-Type"
@@ -30,8 +33,22 @@ func NewPublicHandler(svc *service.Service, val *validator.Validator) *PublicHan
 	return &PublicHandler{svc: svc, val: val}
 }
 
+// SetTokenLocator sets the lookup of public offer tokens that live in the
+// dedicated data schema of their organization.
+func (h *PublicHandler) SetTokenLocator(locator httpkit.PublicTokenResolver) {
+	h.tokenLocator = locator
+}
+
+func (h *PublicHandler) tokenOrganization(ctx context.Context, token string) (uuid.UUID, bool, error) {
+	if h.tokenLocator == nil {
+		return uuid.Nil, false, nil
+	}
+	return h.tokenLocator(ctx, token)
+}
+
 // RegisterRoutes mounts public partner offer routes (no auth middleware).
 func (h *PublicHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.Use(httpkit.PublicTokenTenant(h.tokenOrganization))
 	rg.GET("/:token/photos/:attachmentId", h.GetOfferPhoto)
 	rg.GET("/:token", h.GetOffer)
 	rg.GET("/:token/terms", h.GetTerms)
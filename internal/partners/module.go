@@ -8,6 +8,7 @@ import (
 	"portal_final_backend/internal/partners/handler"
 	"portal_final_backend/internal/partners/repository"
 	"portal_final_backend/internal/partners/service"
+	"portal_final_backend/platform/httpkit"
 	"portal_final_backend/platform/validator"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -15,9 +16,10 @@ import (
 
 // Module is the partners bounded context module implementing http.Module.
 type Module struct {
-	handler       *handler.Handler
-	publicHandler *handler.PublicHandler
-	service       *service.Service
+	handler            *handler.Handler
+	publicHandler      *handler.PublicHandler
+	applicationHandler *handler.ApplicationHandler
+	service            *service.Service
 }
 
 // NewModule creates and initializes the partners module with all its dependencies.
@@ -32,8 +34,9 @@ func NewModule(
 	svc := service.New(repo, eventBus, storageSvc, logoBucket)
 	h := handler.New(svc, val)
 	ph := handler.NewPublicHandler(svc, val)
+	ah := handler.NewApplicationHandler(svc, val)
 
-	return &Module{handler: h, publicHandler: ph, service: svc}
+	return &Module{handler: h, publicHandler: ph, applicationHandler: ah, service: svc}
 }
 
 // Name returns the module identifier.
@@ -50,14 +53,26 @@ func (m *Module) SetOfferPDFRegenerator(pdfGen handler.OfferPDFRegenerator) {
 	m.handler.SetOfferPDFRegenerator(pdfGen)
 }
 
+// SetPublicTokenLocator injects the lookup of public offer tokens whose offers
+// live in a dedicated data schema.
+func (m *Module) SetPublicTokenLocator(locator httpkit.PublicTokenResolver) {
+	m.publicHandler.SetTokenLocator(locator)
+}
+
 // RegisterRoutes mounts partner routes on the provided router context.
 func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	partnersGroup := ctx.Protected.Group("/partners")
 	m.handler.RegisterRoutes(partnersGroup)
 
 	// Public routes for vakman-facing offer pages (no auth middleware)
-	publicGroup := ctx.V1.Group("/public/partner-offers")
+	publicGroup := ctx.Public.Group("/partner-offers")
 	m.publicHandler.RegisterRoutes(publicGroup)
+
+	// Public partner application form and document uploads (no auth middleware)
+	m.applicationHandler.RegisterPublicRoutes(ctx.Public.Group("/partner-applications"))
+
+	// Vetting queue for partner applications
+	m.applicationHandler.RegisterAdminRoutes(ctx.Admin.Group("/partner-applications"))
 }
 
 // Compile-time check that Module implements http.Module
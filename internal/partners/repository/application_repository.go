@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const partnerApplicationNotFoundMsg = "partner application not found"
+
+// Partner application statuses.
+const (
+	ApplicationStatusPending  = "pending"
+	ApplicationStatusApproved = "approved"
+	ApplicationStatusRejected = "rejected"
+)
+
+// PartnerApplication is a self-registration submitted through the public application form.
+type PartnerApplication struct {
+	ID                   uuid.UUID
+	OrganizationID       uuid.UUID
+	Status               string
+	BusinessName         string
+	KVKNumber            string
+	VATNumber            *string
+	AddressLine1         string
+	HouseNumber          string
+	PostalCode           string
+	City                 string
+	Country              string
+	ContactName          string
+	ContactEmail         string
+	ContactPhone         string
+	ServiceTypeIDs       []uuid.UUID
+	Motivation           *string
+	UploadTokenHash      string
+	UploadTokenExpiresAt time.Time
+	ReviewedByID         *uuid.UUID
+	ReviewNote           *string
+	ReviewedAt           *time.Time
+	PartnerID            *uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// PartnerApplicationDocument is an insurance or certification document attached to an application.
+type PartnerApplicationDocument struct {
+	ID             uuid.UUID
+	ApplicationID  uuid.UUID
+	OrganizationID uuid.UUID
+	DocumentType   string
+	FileKey        string
+	FileName       string
+	ContentType    string
+	SizeBytes      int64
+	CreatedAt      time.Time
+}
+
+// ListApplicationsParams filters the vetting queue.
+type ListApplicationsParams struct {
+	OrganizationID uuid.UUID
+	Status         string
+	Offset         int
+	Limit          int
+}
+
+// ListApplicationsResult is a page of the vetting queue.
+type ListApplicationsResult struct {
+	Items []PartnerApplication
+	Total int
+}
+
+const partnerApplicationColumns = `id, organization_id, status, business_name, kvk_number, vat_number,
+	address_line1, house_number, postal_code, city, country, contact_name, contact_email, contact_phone,
+	service_type_ids, motivation, upload_token_hash, upload_token_expires_at, reviewed_by_id, review_note,
+	reviewed_at, partner_id, created_at, updated_at`
+
+func scanPartnerApplication(row pgx.Row) (PartnerApplication, error) {
+	var a PartnerApplication
+	err := row.Scan(
+		&a.ID, &a.OrganizationID, &a.Status, &a.BusinessName, &a.KVKNumber, &a.VATNumber,
+		&a.AddressLine1, &a.HouseNumber, &a.PostalCode, &a.City, &a.Country, &a.ContactName, &a.ContactEmail, &a.ContactPhone,
+		&a.ServiceTypeIDs, &a.Motivation, &a.UploadTokenHash, &a.UploadTokenExpiresAt, &a.ReviewedByID, &a.ReviewNote,
+		&a.ReviewedAt, &a.PartnerID, &a.CreatedAt, &a.UpdatedAt,
+	)
+	return a, err
+}
+
+func (r *Repository) OrganizationExists(ctx context.Context, organizationID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM RAC_organizations WHERE id = $1)`, organizationID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check organization exists: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *Repository) PartnerKVKExists(ctx context.Context, organizationID uuid.UUID, kvkNumber string) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM RAC_partners WHERE organization_id = $1 AND kvk_number = $2)
+	`, organizationID, kvkNumber).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check partner kvk exists: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *Repository) CreateApplication(ctx context.Context, app PartnerApplication) (PartnerApplication, error) {
+	serviceTypeIDs := app.ServiceTypeIDs
+	if serviceTypeIDs == nil {
+		serviceTypeIDs = []uuid.UUID{}
+	}
+	created, err := scanPartnerApplication(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_partner_applications (
+			id, organization_id, business_name, kvk_number, vat_number, address_line1, house_number,
+			postal_code, city, country, contact_name, contact_email, contact_phone, service_type_ids,
+			motivation, upload_token_hash, upload_token_expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING `+partnerApplicationColumns,
+		app.ID, app.OrganizationID, app.BusinessName, app.KVKNumber, app.VATNumber, app.AddressLine1, app.HouseNumber,
+		app.PostalCode, app.City, app.Country, app.ContactName, app.ContactEmail, app.ContactPhone, serviceTypeIDs,
+		app.Motivation, app.UploadTokenHash, app.UploadTokenExpiresAt,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return PartnerApplication{}, apperr.Conflict("an application for this KVK number is already pending")
+		}
+		return PartnerApplication{}, fmt.Errorf("create partner application: %w", err)
+	}
+	return created, nil
+}
+
+func (r *Repository) GetApplication(ctx context.Context, organizationID, id uuid.UUID) (PartnerApplication, error) {
+	app, err := scanPartnerApplication(r.pool.QueryRow(ctx, `
+		SELECT `+partnerApplicationColumns+`
+		FROM RAC_partner_applications
+		WHERE id = $1 AND organization_id = $2
+	`, id, organizationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PartnerApplication{}, apperr.NotFound(partnerApplicationNotFoundMsg)
+		}
+		return PartnerApplication{}, fmt.Errorf("get partner application: %w", err)
+	}
+	return app, nil
+}
+
+func (r *Repository) GetApplicationByUploadTokenHash(ctx context.Context, tokenHash string) (PartnerApplication, error) {
+	app, err := scanPartnerApplication(r.pool.QueryRow(ctx, `
+		SELECT `+partnerApplicationColumns+`
+		FROM RAC_partner_applications
+		WHERE upload_token_hash = $1
+	`, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PartnerApplication{}, apperr.NotFound(partnerApplicationNotFoundMsg)
+		}
+		return PartnerApplication{}, fmt.Errorf("get partner application by token: %w", err)
+	}
+	return app, nil
+}
+
+func (r *Repository) ListApplications(ctx context.Context, params ListApplicationsParams) (ListApplicationsResult, error) {
+	var status any
+	if params.Status != "" {
+		status = params.Status
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM RAC_partner_applications
+		WHERE organization_id = $1 AND ($2::text IS NULL OR status = $2)
+	`, params.OrganizationID, status).Scan(&total); err != nil {
+		return ListApplicationsResult{}, fmt.Errorf("count partner applications: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+partnerApplicationColumns+`
+		FROM RAC_partner_applications
+		WHERE organization_id = $1 AND ($2::text IS NULL OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`, params.OrganizationID, status, params.Limit, params.Offset)
+	if err != nil {
+		return ListApplicationsResult{}, fmt.Errorf("list partner applications: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]PartnerApplication, 0)
+	for rows.Next() {
+		app, err := scanPartnerApplication(rows)
+		if err != nil {
+			return ListApplicationsResult{}, fmt.Errorf("scan partner application: %w", err)
+		}
+		items = append(items, app)
+	}
+	if err := rows.Err(); err != nil {
+		return ListApplicationsResult{}, fmt.Errorf("iterate partner applications: %w", err)
+	}
+	return ListApplicationsResult{Items: items, Total: total}, nil
+}
+
+// ReviewApplication records the review decision of a pending application.
+func (r *Repository) ReviewApplication(ctx context.Context, organizationID, id uuid.UUID, status string, reviewedBy uuid.UUID, note *string, partnerID *uuid.UUID) (PartnerApplication, error) {
+	app, err := scanPartnerApplication(r.pool.QueryRow(ctx, `
+		UPDATE RAC_partner_applications
+		SET status = $3, reviewed_by_id = $4, review_note = $5, partner_id = $6, reviewed_at = now(), updated_at = now()
+		WHERE id = $1 AND organization_id = $2 AND status = 'pending'
+		RETURNING `+partnerApplicationColumns,
+		id, organizationID, status, reviewedBy, note, partnerID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PartnerApplication{}, apperr.Conflict("partner application is not pending")
+		}
+		return PartnerApplication{}, fmt.Errorf("review partner application: %w", err)
+	}
+	return app, nil
+}
+
+func (r *Repository) CreateApplicationDocument(ctx context.Context, doc PartnerApplicationDocument) (PartnerApplicationDocument, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_partner_application_documents (
+			id, application_id, organization_id, document_type, file_key, file_name, content_type, size_bytes
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`, doc.ID, doc.ApplicationID, doc.OrganizationID, doc.DocumentType, doc.FileKey, doc.FileName, doc.ContentType, doc.SizeBytes).Scan(&doc.CreatedAt)
+	if err != nil {
+		return PartnerApplicationDocument{}, fmt.Errorf("create partner application document: %w", err)
+	}
+	return doc, nil
+}
+
+func (r *Repository) ListApplicationDocuments(ctx context.Context, organizationID, applicationID uuid.UUID) ([]PartnerApplicationDocument, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, application_id, organization_id, document_type, file_key, file_name, content_type, size_bytes, created_at
+		FROM RAC_partner_application_documents
+		WHERE application_id = $1 AND organization_id = $2
+		ORDER BY created_at ASC
+	`, applicationID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list partner application documents: %w", err)
+	}
+	defer rows.Close()
+
+	docs := make([]PartnerApplicationDocument, 0)
+	for rows.Next() {
+		var d PartnerApplicationDocument
+		if err := rows.Scan(&d.ID, &d.ApplicationID, &d.OrganizationID, &d.DocumentType, &d.FileKey, &d.FileName, &d.ContentType, &d.SizeBytes, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan partner application document: %w", err)
+		}
+		docs = append(docs, d)
+	}
+	return docs, rows.Err()
+}
+
+func (r *Repository) GetApplicationDocument(ctx context.Context, organizationID, applicationID, documentID uuid.UUID) (PartnerApplicationDocument, error) {
+	var d PartnerApplicationDocument
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, application_id, organization_id, document_type, file_key, file_name, content_type, size_bytes, created_at
+		FROM RAC_partner_application_documents
+		WHERE id = $1 AND application_id = $2 AND organization_id = $3
+	`, documentID, applicationID, organizationID).Scan(&d.ID, &d.ApplicationID, &d.OrganizationID, &d.DocumentType, &d.FileKey, &d.FileName, &d.ContentType, &d.SizeBytes, &d.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PartnerApplicationDocument{}, apperr.NotFound("partner application document not found")
+		}
+		return PartnerApplicationDocument{}, fmt.Errorf("get partner application document: %w", err)
+	}
+	return d, nil
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"portal_final_backend/internal/eventoutbox"
+	"portal_final_backend/internal/events"
 	partnersdb "portal_final_backend/internal/partners/db"
 	"portal_final_backend/platform/apperr"
 
@@ -364,29 +366,43 @@ func offerWithContext(data offerContext) PartnerOfferWithContext {
 	return result
 }
 
-// CreateOffer inserts a new partner offer.
-func (r *Repository) CreateOffer(ctx context.Context, offer PartnerOffer) (PartnerOffer, error) {
+// CreateOffer inserts a new partner offer, with offer.ID when set. The write
+// methods of offers store the pending events in the domain event outbox in the
+// same transaction.
+func (r *Repository) CreateOffer(ctx context.Context, offer PartnerOffer, pendingEvents ...events.Event) (PartnerOffer, error) {
 	offerLineItems, err := json.Marshal(offer.OfferLineItems)
 	if err != nil {
 		return PartnerOffer{}, fmt.Errorf("marshal offer line items: %w", err)
 	}
-	created, err := r.queries.CreatePartnerOffer(ctx, partnersdb.CreatePartnerOfferParams{
-		OrganizationID:     toPgUUID(offer.OrganizationID),
-		PartnerID:          toPgUUID(offer.PartnerID),
-		LeadServiceID:      toPgUUID(offer.LeadServiceID),
-		PublicToken:        offer.PublicToken,
-		ExpiresAt:          pgtype.Timestamptz{Time: offer.ExpiresAt, Valid: true},
-		PricingSource:      partnersdb.PricingSource(offer.PricingSource),
-		CustomerPriceCents: offer.CustomerPriceCents,
-		VakmanPriceCents:   offer.VakmanPriceCents,
-		MarginBasisPoints:  int32(offer.MarginBasisPoints),
-		OfferLineItems:     offerLineItems,
-		JobSummaryShort:    toPgText(offer.JobSummaryShort),
-		BuilderSummary:     toPgText(offer.BuilderSummary),
-		RequiresInspection: offer.RequiresInspection,
-	})
+	if offer.ID == uuid.Nil {
+		offer.ID = uuid.New()
+	}
+	var created partnersdb.CreatePartnerOfferRow
+	err = eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		row, err := r.queries.WithTx(tx).CreatePartnerOffer(ctx, partnersdb.CreatePartnerOfferParams{
+			ID:                 toPgUUID(offer.ID),
+			OrganizationID:     toPgUUID(offer.OrganizationID),
+			PartnerID:          toPgUUID(offer.PartnerID),
+			LeadServiceID:      toPgUUID(offer.LeadServiceID),
+			PublicToken:        offer.PublicToken,
+			ExpiresAt:          pgtype.Timestamptz{Time: offer.ExpiresAt, Valid: true},
+			PricingSource:      partnersdb.PricingSource(offer.PricingSource),
+			CustomerPriceCents: offer.CustomerPriceCents,
+			VakmanPriceCents:   offer.VakmanPriceCents,
+			MarginBasisPoints:  int32(offer.MarginBasisPoints),
+			OfferLineItems:     offerLineItems,
+			JobSummaryShort:    toPgText(offer.JobSummaryShort),
+			BuilderSummary:     toPgText(offer.BuilderSummary),
+			RequiresInspection: offer.RequiresInspection,
+		})
+		if err != nil {
+			return fmt.Errorf("create partner offer: %w", err)
+		}
+		created = row
+		return nil
+	}, pendingEvents...)
 	if err != nil {
-		return PartnerOffer{}, fmt.Errorf("create partner offer: %w", err)
+		return PartnerOffer{}, err
 	}
 	return offerFromCreatePartnerOfferRow(created), nil
 }
@@ -477,20 +493,21 @@ func (r *Repository) GetOfferByID(ctx context.Context, offerID uuid.UUID, organi
 
 // DeleteOffer deletes an offer within a tenant if it is still in a deletable state.
 // Accepted and rejected offers are intentionally not deletable.
-func (r *Repository) DeleteOffer(ctx context.Context, offerID uuid.UUID, organizationID uuid.UUID) error {
-	rowsAffected, err := r.queries.DeletePartnerOffer(ctx, partnersdb.DeletePartnerOfferParams{
-		OfferID:        toPgUUID(offerID),
-		OrganizationID: toPgUUID(organizationID),
-		Statuses:       deletableOfferStatuses,
-	})
-	if err != nil {
-		return fmt.Errorf("delete offer: %w", err)
-	}
-	if rowsAffected == 0 {
-		return apperr.Conflict("offer cannot be deleted")
-	}
-
-	return nil
+func (r *Repository) DeleteOffer(ctx context.Context, offerID uuid.UUID, organizationID uuid.UUID, pendingEvents ...events.Event) error {
+	return eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		rowsAffected, err := r.queries.WithTx(tx).DeletePartnerOffer(ctx, partnersdb.DeletePartnerOfferParams{
+			OfferID:        toPgUUID(offerID),
+			OrganizationID: toPgUUID(organizationID),
+			Statuses:       deletableOfferStatuses,
+		})
+		if err != nil {
+			return fmt.Errorf("delete offer: %w", err)
+		}
+		if rowsAffected == 0 {
+			return apperr.Conflict("offer cannot be deleted")
+		}
+		return nil
+	}, pendingEvents...)
 }
 
 // GetLeadServiceSummaryContext fetches non-PII data used to build offer summaries.
@@ -941,6 +958,49 @@ func (r *Repository) ListOffersForService(ctx context.Context, leadServiceID uui
 	return offers, nil
 }
 
+// ListOffersForServices returns the offers of several lead services in one query, newest
+// first per service. Batched readers use it instead of calling ListOffersForService per service.
+func (r *Repository) ListOffersForServices(ctx context.Context, leadServiceIDs []uuid.UUID, organizationID uuid.UUID) ([]PartnerOfferWithContext, error) {
+	if len(leadServiceIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT o.id, o.organization_id, o.partner_id, o.lead_service_id, o.public_token, o.expires_at,
+			o.pricing_source::text, o.customer_price_cents, o.vakman_price_cents, o.margin_basis_points,
+			o.offer_line_items, o.status::text, o.accepted_at, o.rejected_at, o.rejection_reason,
+			o.inspection_availability, o.job_availability, o.created_at, o.updated_at,
+			p.business_name
+		FROM RAC_partner_offers o
+		JOIN RAC_partners p ON p.id = o.partner_id
+		WHERE o.lead_service_id = ANY($1) AND o.organization_id = $2
+		ORDER BY o.lead_service_id, o.created_at DESC`,
+		toPgUUIDSlice(leadServiceIDs), toPgUUID(organizationID))
+	if err != nil {
+		return nil, fmt.Errorf("list offers for services: %w", err)
+	}
+	defer rows.Close()
+
+	var offers []PartnerOfferWithContext
+	for rows.Next() {
+		var snapshot offerSnapshot
+		var partnerName string
+		if err := rows.Scan(
+			&snapshot.ID, &snapshot.OrganizationID, &snapshot.PartnerID, &snapshot.LeadServiceID, &snapshot.PublicToken, &snapshot.ExpiresAt,
+			&snapshot.PricingSource, &snapshot.CustomerPriceCents, &snapshot.VakmanPriceCents, &snapshot.MarginBasisPoints,
+			&snapshot.OfferLineItems, &snapshot.Status, &snapshot.AcceptedAt, &snapshot.RejectedAt, &snapshot.RejectionReason,
+			&snapshot.InspectionAvailability, &snapshot.JobAvailability, &snapshot.CreatedAt, &snapshot.UpdatedAt,
+			&partnerName,
+		); err != nil {
+			return nil, fmt.Errorf("scan offer for services: %w", err)
+		}
+		offers = append(offers, PartnerOfferWithContext{PartnerOffer: offerFromSnapshot(snapshot), PartnerName: partnerName})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list offers for services: %w", err)
+	}
+	return offers, nil
+}
+
 // ListOffersByPartner returns all offers for a given partner within a tenant.
 func (r *Repository) ListOffersByPartner(ctx context.Context, partnerID uuid.UUID, organizationID uuid.UUID) ([]PartnerOfferWithContext, error) {
 	rows, err := r.queries.ListPartnerOffersByPartner(ctx, partnersdb.ListPartnerOffersByPartnerParams{
@@ -981,28 +1041,29 @@ type AcceptOfferParams struct {
 }
 
 // AcceptOffer atomically accepts an offer and records availability + signer data.
-func (r *Repository) AcceptOffer(ctx context.Context, p AcceptOfferParams) error {
-	rowsAffected, err := r.queries.AcceptPartnerOffer(ctx, partnersdb.AcceptPartnerOfferParams{
-		InspectionSlots:    p.InspectionSlots,
-		JobSlots:           p.JobSlots,
-		SignerName:         toPgText(p.SignerName),
-		SignerBusinessName: toPgText(p.SignerBusinessName),
-		SignerAddress:      toPgText(p.SignerAddress),
-		SignatureData:      toPgText(p.SignatureData),
-		OfferID:            toPgUUID(p.OfferID),
-	})
-	if err != nil {
-		errMsg := err.Error()
-		if strings.Contains(errMsg, "idx_partner_offers_exclusive_acceptance") {
-			return apperr.Conflict("job already assigned to another partner")
+func (r *Repository) AcceptOffer(ctx context.Context, p AcceptOfferParams, pendingEvents ...events.Event) error {
+	return eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		rowsAffected, err := r.queries.WithTx(tx).AcceptPartnerOffer(ctx, partnersdb.AcceptPartnerOfferParams{
+			InspectionSlots:    p.InspectionSlots,
+			JobSlots:           p.JobSlots,
+			SignerName:         toPgText(p.SignerName),
+			SignerBusinessName: toPgText(p.SignerBusinessName),
+			SignerAddress:      toPgText(p.SignerAddress),
+			SignatureData:      toPgText(p.SignatureData),
+			OfferID:            toPgUUID(p.OfferID),
+		})
+		if err != nil {
+			errMsg := err.Error()
+			if strings.Contains(errMsg, "idx_partner_offers_exclusive_acceptance") {
+				return apperr.Conflict("job already assigned to another partner")
+			}
+			return fmt.Errorf("accept offer: %w", err)
 		}
-		return fmt.Errorf("accept offer: %w", err)
-	}
-	if rowsAffected == 0 {
-		return apperr.Conflict("offer is not in a valid state to be accepted")
-	}
-
-	return nil
+		if rowsAffected == 0 {
+			return apperr.Conflict("offer is not in a valid state to be accepted")
+		}
+		return nil
+	}, pendingEvents...)
 }
 
 // SetOfferPDFFileKey persists the generated PDF file key on the offer record.
@@ -1018,38 +1079,63 @@ func (r *Repository) SetOfferPDFFileKey(ctx context.Context, offerID uuid.UUID,
 }
 
 // RejectOffer marks an offer as rejected with an optional reason.
-func (r *Repository) RejectOffer(ctx context.Context, offerID uuid.UUID, reason string) error {
-	rowsAffected, err := r.queries.RejectPartnerOffer(ctx, partnersdb.RejectPartnerOfferParams{
-		RejectionReason: optionalExactText(reason),
-		OfferID:         toPgUUID(offerID),
-	})
-	if err != nil {
-		return fmt.Errorf("reject offer: %w", err)
-	}
-	if rowsAffected == 0 {
-		return apperr.Conflict("offer is not in a valid state to be rejected")
-	}
+func (r *Repository) RejectOffer(ctx context.Context, offerID uuid.UUID, reason string, pendingEvents ...events.Event) error {
+	return eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		rowsAffected, err := r.queries.WithTx(tx).RejectPartnerOffer(ctx, partnersdb.RejectPartnerOfferParams{
+			RejectionReason: optionalExactText(reason),
+			OfferID:         toPgUUID(offerID),
+		})
+		if err != nil {
+			return fmt.Errorf("reject offer: %w", err)
+		}
+		if rowsAffected == 0 {
+			return apperr.Conflict("offer is not in a valid state to be rejected")
+		}
+		return nil
+	}, pendingEvents...)
+}
 
-	return nil
+// ExpiredOffer is an offer ExpireOffers expired.
+type ExpiredOffer struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	PartnerID      uuid.UUID
+	LeadServiceID  uuid.UUID
+	LeadID         uuid.UUID
+	PartnerName    string
 }
 
-// ExpireOffers marks all pending/sent offers past their expiry as expired.
-func (r *Repository) ExpireOffers(ctx context.Context) ([]PartnerOffer, error) {
-	rows, err := r.queries.ExpirePartnerOffers(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("expire offers: %w", err)
-	}
+// ExpireOffers marks all pending/sent offers past their expiry as expired and
+// stores the event expiredEvent builds for each in the domain event outbox in
+// the same transaction.
+func (r *Repository) ExpireOffers(ctx context.Context, expiredEvent func(ExpiredOffer) events.Event) ([]ExpiredOffer, error) {
+	var expired []ExpiredOffer
+	err := eventoutbox.InTx(ctx, r.pool, func(tx pgx.Tx) error {
+		rows, err := r.queries.WithTx(tx).ExpirePartnerOffers(ctx)
+		if err != nil {
+			return fmt.Errorf("expire offers: %w", err)
+		}
 
-	expired := make([]PartnerOffer, 0, len(rows))
-	for _, row := range rows {
-		expired = append(expired, PartnerOffer{
-			ID:             uuid.UUID(row.ID.Bytes),
-			OrganizationID: uuid.UUID(row.OrganizationID.Bytes),
-			PartnerID:      uuid.UUID(row.PartnerID.Bytes),
-			LeadServiceID:  uuid.UUID(row.LeadServiceID.Bytes),
-		})
+		expired = make([]ExpiredOffer, 0, len(rows))
+		for _, row := range rows {
+			offer := ExpiredOffer{
+				ID:             uuid.UUID(row.ID.Bytes),
+				OrganizationID: uuid.UUID(row.OrganizationID.Bytes),
+				PartnerID:      uuid.UUID(row.PartnerID.Bytes),
+				LeadServiceID:  uuid.UUID(row.LeadServiceID.Bytes),
+				LeadID:         uuid.UUID(row.LeadID.Bytes),
+				PartnerName:    row.PartnerName,
+			}
+			if _, err := eventoutbox.Enqueue(ctx, tx, expiredEvent(offer)); err != nil {
+				return err
+			}
+			expired = append(expired, offer)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return expired, nil
 }
 
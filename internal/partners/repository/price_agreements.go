@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Pricing models of a price agreement.
+const (
+	PricingModelPercentage = "percentage"
+	PricingModelRateCard   = "rate_card"
+	PricingModelItemPrices = "item_prices"
+)
+
+const priceAgreementNotFoundMsg = "price agreement not found"
+
+// PriceAgreementRate is a partner price per unit. Rate cards match quote
+// items on Keyword in their description, item prices on CatalogProductID.
+type PriceAgreementRate struct {
+	CatalogProductID *uuid.UUID `json:"catalogProductId,omitempty"`
+	Keyword          string     `json:"keyword,omitempty"`
+	UnitPriceCents   int64      `json:"unitPriceCents"`
+}
+
+// PriceAgreement fixes what a partner earns for a service type during a
+// period. A nil ServiceTypeID covers all service types of the partner.
+type PriceAgreement struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	PartnerID      uuid.UUID
+	ServiceTypeID  *uuid.UUID
+	ServiceType    *string
+	PricingModel   string
+	PercentageBps  int
+	Rates          []PriceAgreementRate
+	LeadFeeCents   int64
+	EffectiveFrom  time.Time
+	EffectiveTo    *time.Time
+	Notes          *string
+	SupersededBy   *uuid.UUID
+	CreatedBy      *uuid.UUID
+	CreatedAt      time.Time
+}
+
+const priceAgreementColumns = `a.id, a.organization_id, a.partner_id, a.service_type_id, st.name, a.pricing_model, a.percentage_bps,
+	a.rates, a.lead_fee_cents, a.effective_from, a.effective_to, a.notes, a.superseded_by, a.created_by, a.created_at`
+
+const priceAgreementFrom = `FROM RAC_partner_price_agreements a
+	LEFT JOIN RAC_service_types st ON st.id = a.service_type_id`
+
+func scanPriceAgreement(row pgx.Row) (PriceAgreement, error) {
+	var a PriceAgreement
+	var rates []byte
+	if err := row.Scan(&a.ID, &a.OrganizationID, &a.PartnerID, &a.ServiceTypeID, &a.ServiceType, &a.PricingModel, &a.PercentageBps,
+		&rates, &a.LeadFeeCents, &a.EffectiveFrom, &a.EffectiveTo, &a.Notes, &a.SupersededBy, &a.CreatedBy, &a.CreatedAt); err != nil {
+		return PriceAgreement{}, err
+	}
+	if len(rates) > 0 {
+		if err := json.Unmarshal(rates, &a.Rates); err != nil {
+			return PriceAgreement{}, fmt.Errorf("decode price agreement rates: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// CreatePriceAgreement stores a new agreement. An agreement in effect for the
+// same partner and service type is renegotiated: it ends the day before the
+// new one starts and points to it.
+func (r *Repository) CreatePriceAgreement(ctx context.Context, a PriceAgreement) (PriceAgreement, error) {
+	rates, err := json.Marshal(a.Rates)
+	if err != nil {
+		return PriceAgreement{}, fmt.Errorf("encode price agreement rates: %w", err)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return PriceAgreement{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var laterStart bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM RAC_partner_price_agreements
+			WHERE organization_id = $1 AND partner_id = $2 AND service_type_id IS NOT DISTINCT FROM $3
+			  AND effective_from >= $4
+		)`, a.OrganizationID, a.PartnerID, a.ServiceTypeID, a.EffectiveFrom,
+	).Scan(&laterStart); err != nil {
+		return PriceAgreement{}, fmt.Errorf("check later price agreements: %w", err)
+	}
+	if laterStart {
+		return PriceAgreement{}, apperr.Conflict("an agreement starting on or after this date already exists")
+	}
+
+	a.ID = uuid.New()
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_partner_price_agreements
+			(id, organization_id, partner_id, service_type_id, pricing_model, percentage_bps, rates, lead_fee_cents, effective_from, effective_to, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		a.ID, a.OrganizationID, a.PartnerID, a.ServiceTypeID, a.PricingModel, a.PercentageBps, rates, a.LeadFeeCents, a.EffectiveFrom, a.EffectiveTo, a.Notes, a.CreatedBy,
+	); err != nil {
+		return PriceAgreement{}, fmt.Errorf("insert price agreement: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_partner_price_agreements
+		SET effective_to = $5::date - 1, superseded_by = $4
+		WHERE organization_id = $1 AND partner_id = $2 AND service_type_id IS NOT DISTINCT FROM $3
+		  AND id <> $4
+		  AND (effective_to IS NULL OR effective_to >= $5::date)`,
+		a.OrganizationID, a.PartnerID, a.ServiceTypeID, a.ID, a.EffectiveFrom,
+	); err != nil {
+		return PriceAgreement{}, fmt.Errorf("end previous price agreement: %w", err)
+	}
+
+	created, err := scanPriceAgreement(tx.QueryRow(ctx, `SELECT `+priceAgreementColumns+` `+priceAgreementFrom+` WHERE a.id = $1`, a.ID))
+	if err != nil {
+		return PriceAgreement{}, fmt.Errorf("read price agreement: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return PriceAgreement{}, fmt.Errorf("commit price agreement: %w", err)
+	}
+	return created, nil
+}
+
+// ListPriceAgreements returns all agreements of a partner, including ended
+// ones, newest first per service type.
+func (r *Repository) ListPriceAgreements(ctx context.Context, partnerID, organizationID uuid.UUID) ([]PriceAgreement, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+priceAgreementColumns+` `+priceAgreementFrom+`
+		WHERE a.partner_id = $1 AND a.organization_id = $2
+		ORDER BY st.name NULLS FIRST, a.effective_from DESC`, partnerID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list price agreements: %w", err)
+	}
+	defer rows.Close()
+
+	agreements := make([]PriceAgreement, 0)
+	for rows.Next() {
+		a, err := scanPriceAgreement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan price agreement: %w", err)
+		}
+		agreements = append(agreements, a)
+	}
+	return agreements, rows.Err()
+}
+
+// GetEffectivePriceAgreement returns the agreement of a partner that applies
+// to a lead service on the given day, preferring one for the service type of
+// the service over a general one. It returns nil when there is none.
+func (r *Repository) GetEffectivePriceAgreement(ctx context.Context, organizationID, partnerID, leadServiceID uuid.UUID, on time.Time) (*PriceAgreement, error) {
+	a, err := scanPriceAgreement(r.pool.QueryRow(ctx, `SELECT `+priceAgreementColumns+` `+priceAgreementFrom+`
+		JOIN RAC_lead_services ls ON ls.id = $3 AND ls.organization_id = a.organization_id
+		WHERE a.organization_id = $1 AND a.partner_id = $2
+		  AND (a.service_type_id = ls.service_type_id OR a.service_type_id IS NULL)
+		  AND a.effective_from <= $4::date
+		  AND (a.effective_to IS NULL OR a.effective_to >= $4::date)
+		ORDER BY a.service_type_id NULLS LAST, a.effective_from DESC
+		LIMIT 1`, organizationID, partnerID, leadServiceID, on))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get effective price agreement: %w", err)
+	}
+	return &a, nil
+}
+
+// EndPriceAgreement ends an agreement on the given day without a successor.
+func (r *Repository) EndPriceAgreement(ctx context.Context, id, partnerID, organizationID uuid.UUID, endDate time.Time) (PriceAgreement, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_partner_price_agreements
+		SET effective_to = $4::date
+		WHERE id = $1 AND partner_id = $2 AND organization_id = $3
+		  AND effective_from <= $4::date
+		  AND (effective_to IS NULL OR effective_to > $4::date)`,
+		id, partnerID, organizationID, endDate)
+	if err != nil {
+		return PriceAgreement{}, fmt.Errorf("end price agreement: %w", err)
+	}
+
+	a, err := scanPriceAgreement(r.pool.QueryRow(ctx, `SELECT `+priceAgreementColumns+` `+priceAgreementFrom+`
+		WHERE a.id = $1 AND a.partner_id = $2 AND a.organization_id = $3`, id, partnerID, organizationID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PriceAgreement{}, apperr.NotFound(priceAgreementNotFoundMsg)
+	}
+	if err != nil {
+		return PriceAgreement{}, fmt.Errorf("get price agreement: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return PriceAgreement{}, apperr.Validation("end date must lie within the agreement period")
+	}
+	return a, nil
+}
+
+// RecordOfferPriceAgreement links an offer to the agreement it was priced with.
+func (r *Repository) RecordOfferPriceAgreement(ctx context.Context, offerID, agreementID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_partner_offer_price_agreements (offer_id, agreement_id)
+		VALUES ($1, $2)
+		ON CONFLICT (offer_id) DO UPDATE SET agreement_id = EXCLUDED.agreement_id`,
+		offerID, agreementID,
+	); err != nil {
+		return fmt.Errorf("record offer price agreement: %w", err)
+	}
+	return nil
+}
+
+// GetQuoteItemCatalogProducts maps the items of a quote to their catalog
+// product, for items that have one.
+func (r *Repository) GetQuoteItemCatalogProducts(ctx context.Context, quoteID, organizationID uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, catalog_product_id FROM RAC_quote_items
+		WHERE quote_id = $1 AND organization_id = $2 AND catalog_product_id IS NOT NULL`,
+		quoteID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list quote item catalog products: %w", err)
+	}
+	defer rows.Close()
+
+	products := make(map[uuid.UUID]uuid.UUID)
+	for rows.Next() {
+		var itemID, productID uuid.UUID
+		if err := rows.Scan(&itemID, &productID); err != nil {
+			return nil, fmt.Errorf("scan quote item catalog product: %w", err)
+		}
+		products[itemID] = productID
+	}
+	return products, rows.Err()
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ServicePrerequisite is a service another service of the same project
+// depends on.
+type ServicePrerequisite struct {
+	LeadServiceID uuid.UUID
+	ServiceType   string
+}
+
+// ListQuoteCoveredServiceIDs returns the services a quote covers besides its
+// own lead service.
+func (r *Repository) ListQuoteCoveredServiceIDs(ctx context.Context, quoteID uuid.UUID, organizationID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT lead_service_id FROM RAC_quote_covered_services
+		WHERE quote_id = $1 AND organization_id = $2`, quoteID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list quote covered services: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan quote covered service: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListUndispatchedPrerequisites returns the prerequisites of a lead service
+// that are neither completed nor accepted by a partner, i.e. the work that
+// has to be scheduled before this service can be dispatched.
+func (r *Repository) ListUndispatchedPrerequisites(ctx context.Context, leadServiceID uuid.UUID, organizationID uuid.UUID) ([]ServicePrerequisite, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT ls.id, st.name
+		FROM RAC_lead_service_dependencies d
+		JOIN RAC_lead_services ls ON ls.id = d.depends_on_service_id
+		JOIN RAC_service_types st ON st.id = ls.service_type_id AND st.organization_id = ls.organization_id
+		WHERE d.lead_service_id = $1
+		  AND ls.organization_id = $2
+		  AND ls.pipeline_stage <> 'Completed'
+		  AND NOT EXISTS (
+			SELECT 1 FROM RAC_partner_offers o
+			WHERE o.lead_service_id = ls.id AND o.status = 'accepted'
+		  )
+		ORDER BY st.name`, leadServiceID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list service prerequisites: %w", err)
+	}
+	defer rows.Close()
+
+	prerequisites := make([]ServicePrerequisite, 0)
+	for rows.Next() {
+		var p ServicePrerequisite
+		if err := rows.Scan(&p.LeadServiceID, &p.ServiceType); err != nil {
+			return nil, fmt.Errorf("scan service prerequisite: %w", err)
+		}
+		prerequisites = append(prerequisites, p)
+	}
+	return prerequisites, rows.Err()
+}
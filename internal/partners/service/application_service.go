@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/auth/token"
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/partners/repository"
+	"portal_final_backend/internal/partners/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/phone"
+	"portal_final_backend/platform/sanitize"
+
+	"github.com/google/uuid"
+)
+
+const (
+	applicationUploadTokenBytes = 32
+	applicationUploadTTL        = 14 * 24 * time.Hour
+	maxApplicationDocuments     = 20
+
+	defaultApplicationsPageSize = 20
+)
+
+// SubmitApplication stores a public partner application in the organization's vetting queue. The
+// returned upload token lets the applicant attach insurance and certification documents.
+func (s *Service) SubmitApplication(ctx context.Context, organizationID uuid.UUID, req transport.SubmitPartnerApplicationRequest) (transport.SubmitPartnerApplicationResponse, error) {
+	exists, err := s.repo.OrganizationExists(ctx, organizationID)
+	if err != nil {
+		return transport.SubmitPartnerApplicationResponse{}, err
+	}
+	if !exists {
+		return transport.SubmitPartnerApplicationResponse{}, apperr.NotFound("organization not found")
+	}
+
+	kvk := strings.TrimSpace(req.KVKNumber)
+	vat := normalizeOptionalString(req.VATNumber, func(value string) string { return strings.ToUpper(strings.TrimSpace(value)) })
+	vatValue := ""
+	if vat != nil {
+		vatValue = *vat
+	}
+	if kvk == "" {
+		return transport.SubmitPartnerApplicationResponse{}, apperr.Validation("invalid KVK number")
+	}
+	if err := validatePartnerNumbers(kvk, vatValue); err != nil {
+		return transport.SubmitPartnerApplicationResponse{}, err
+	}
+	registered, err := s.repo.PartnerKVKExists(ctx, organizationID, kvk)
+	if err != nil {
+		return transport.SubmitPartnerApplicationResponse{}, err
+	}
+	if registered {
+		return transport.SubmitPartnerApplicationResponse{}, apperr.Conflict("a partner with this KVK number is already registered")
+	}
+	if err := s.ensureServiceTypeIDsValid(ctx, organizationID, req.ServiceTypeIDs); err != nil {
+		return transport.SubmitPartnerApplicationResponse{}, err
+	}
+
+	rawToken, err := token.GenerateRandomToken(applicationUploadTokenBytes)
+	if err != nil {
+		return transport.SubmitPartnerApplicationResponse{}, err
+	}
+
+	created, err := s.repo.CreateApplication(ctx, repository.PartnerApplication{
+		ID:                   uuid.New(),
+		OrganizationID:       organizationID,
+		BusinessName:         sanitize.Text(req.BusinessName),
+		KVKNumber:            kvk,
+		VATNumber:            vat,
+		AddressLine1:         sanitize.Text(req.AddressLine1),
+		HouseNumber:          strings.TrimSpace(req.HouseNumber),
+		PostalCode:           strings.TrimSpace(req.PostalCode),
+		City:                 sanitize.Text(req.City),
+		Country:              sanitize.Text(req.Country),
+		ContactName:          sanitize.Text(req.ContactName),
+		ContactEmail:         normalizeEmail(req.ContactEmail),
+		ContactPhone:         phone.NormalizeE164(req.ContactPhone),
+		ServiceTypeIDs:       req.ServiceTypeIDs,
+		Motivation:           normalizeOptional(req.Motivation),
+		UploadTokenHash:      token.HashSHA256(rawToken),
+		UploadTokenExpiresAt: time.Now().Add(applicationUploadTTL),
+	})
+	if err != nil {
+		return transport.SubmitPartnerApplicationResponse{}, err
+	}
+
+	if s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.PartnerApplicationSubmitted{
+			BaseEvent:      events.NewBaseEvent(),
+			ApplicationID:  created.ID,
+			OrganizationID: organizationID,
+			BusinessName:   created.BusinessName,
+			KVKNumber:      created.KVKNumber,
+			ContactEmail:   created.ContactEmail,
+		})
+	}
+
+	return transport.SubmitPartnerApplicationResponse{
+		ApplicationID:        created.ID,
+		UploadToken:          rawToken,
+		UploadTokenExpiresAt: created.UploadTokenExpiresAt,
+	}, nil
+}
+
+// PresignApplicationDocumentUpload returns an upload URL in the partner bucket for a vetting document.
+func (s *Service) PresignApplicationDocumentUpload(ctx context.Context, uploadToken string, req transport.PartnerApplicationDocumentPresignRequest) (transport.PartnerApplicationDocumentPresignResponse, error) {
+	app, err := s.resolveApplicationUploadToken(ctx, uploadToken)
+	if err != nil {
+		return transport.PartnerApplicationDocumentPresignResponse{}, err
+	}
+	if err := validateApplicationDocumentContentType(req.ContentType); err != nil {
+		return transport.PartnerApplicationDocumentPresignResponse{}, err
+	}
+
+	presigned, err := s.storage.GenerateUploadURL(
+		ctx,
+		s.logoBucket,
+		applicationFolder(app.OrganizationID, app.ID),
+		req.FileName,
+		req.ContentType,
+		req.SizeBytes,
+	)
+	if err != nil {
+		return transport.PartnerApplicationDocumentPresignResponse{}, err
+	}
+
+	return transport.PartnerApplicationDocumentPresignResponse{
+		UploadURL: presigned.URL,
+		FileKey:   presigned.FileKey,
+		ExpiresAt: presigned.ExpiresAt.Unix(),
+	}, nil
+}
+
+// ConfirmApplicationDocument records an uploaded vetting document on the application.
+func (s *Service) ConfirmApplicationDocument(ctx context.Context, uploadToken string, req transport.ConfirmPartnerApplicationDocumentRequest) (transport.PartnerApplicationDocumentResponse, error) {
+	app, err := s.resolveApplicationUploadToken(ctx, uploadToken)
+	if err != nil {
+		return transport.PartnerApplicationDocumentResponse{}, err
+	}
+	if err := validateApplicationDocumentContentType(req.ContentType); err != nil {
+		return transport.PartnerApplicationDocumentResponse{}, err
+	}
+	if err := s.storage.ValidateFileSize(req.SizeBytes); err != nil {
+		return transport.PartnerApplicationDocumentResponse{}, err
+	}
+	if !strings.HasPrefix(req.FileKey, applicationFolder(app.OrganizationID, app.ID)+"/") {
+		return transport.PartnerApplicationDocumentResponse{}, apperr.Validation("invalid document file key")
+	}
+
+	existing, err := s.repo.ListApplicationDocuments(ctx, app.OrganizationID, app.ID)
+	if err != nil {
+		return transport.PartnerApplicationDocumentResponse{}, err
+	}
+	if len(existing) >= maxApplicationDocuments {
+		return transport.PartnerApplicationDocumentResponse{}, apperr.Validation("too many documents for this application")
+	}
+
+	doc, err := s.repo.CreateApplicationDocument(ctx, repository.PartnerApplicationDocument{
+		ID:             uuid.New(),
+		ApplicationID:  app.ID,
+		OrganizationID: app.OrganizationID,
+		DocumentType:   req.DocumentType,
+		FileKey:        req.FileKey,
+		FileName:       sanitize.Text(req.FileName),
+		ContentType:    req.ContentType,
+		SizeBytes:      req.SizeBytes,
+	})
+	if err != nil {
+		return transport.PartnerApplicationDocumentResponse{}, err
+	}
+	return mapApplicationDocumentResponse(doc), nil
+}
+
+func (s *Service) ListApplications(ctx context.Context, tenantID uuid.UUID, req transport.ListPartnerApplicationsRequest) (transport.ListPartnerApplicationsResponse, error) {
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultApplicationsPageSize
+	}
+
+	result, err := s.repo.ListApplications(ctx, repository.ListApplicationsParams{
+		OrganizationID: tenantID,
+		Status:         req.Status,
+		Offset:         (page - 1) * pageSize,
+		Limit:          pageSize,
+	})
+	if err != nil {
+		return transport.ListPartnerApplicationsResponse{}, err
+	}
+
+	items := make([]transport.PartnerApplicationResponse, 0, len(result.Items))
+	for _, app := range result.Items {
+		items = append(items, mapApplicationResponse(app, nil))
+	}
+
+	totalPages := (result.Total + pageSize - 1) / pageSize
+	return transport.ListPartnerApplicationsResponse{
+		Items:      items,
+		Total:      result.Total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *Service) GetApplication(ctx context.Context, tenantID uuid.UUID, id uuid.UUID) (transport.PartnerApplicationResponse, error) {
+	app, err := s.repo.GetApplication(ctx, tenantID, id)
+	if err != nil {
+		return transport.PartnerApplicationResponse{}, err
+	}
+	docs, err := s.repo.ListApplicationDocuments(ctx, tenantID, id)
+	if err != nil {
+		return transport.PartnerApplicationResponse{}, err
+	}
+	return mapApplicationResponse(app, docs), nil
+}
+
+func (s *Service) GetApplicationDocumentDownloadURL(ctx context.Context, tenantID uuid.UUID, applicationID uuid.UUID, documentID uuid.UUID) (transport.PartnerLogoDownloadResponse, error) {
+	doc, err := s.repo.GetApplicationDocument(ctx, tenantID, applicationID, documentID)
+	if err != nil {
+		return transport.PartnerLogoDownloadResponse{}, err
+	}
+	presigned, err := s.storage.GenerateDownloadURL(ctx, s.logoBucket, doc.FileKey)
+	if err != nil {
+		return transport.PartnerLogoDownloadResponse{}, err
+	}
+	return transport.PartnerLogoDownloadResponse{
+		DownloadURL: presigned.URL,
+		ExpiresAt:   presigned.ExpiresAt.Unix(),
+	}, nil
+}
+
+// ApproveApplication converts a pending application into an active partner.
+func (s *Service) ApproveApplication(ctx context.Context, tenantID uuid.UUID, id uuid.UUID, reviewerID uuid.UUID, req transport.ReviewPartnerApplicationRequest) (transport.ApprovePartnerApplicationResponse, error) {
+	app, err := s.repo.GetApplication(ctx, tenantID, id)
+	if err != nil {
+		return transport.ApprovePartnerApplicationResponse{}, err
+	}
+	if app.Status != repository.ApplicationStatusPending {
+		return transport.ApprovePartnerApplicationResponse{}, apperr.Conflict("partner application is not pending")
+	}
+
+	partner, err := s.Create(ctx, tenantID, partnerRequestFromApplication(app))
+	if err != nil {
+		return transport.ApprovePartnerApplicationResponse{}, err
+	}
+
+	reviewed, err := s.repo.ReviewApplication(ctx, tenantID, id, repository.ApplicationStatusApproved, reviewerID, normalizeOptional(req.Note), &partner.ID)
+	if err != nil {
+		_ = s.repo.Delete(ctx, partner.ID, tenantID)
+		return transport.ApprovePartnerApplicationResponse{}, err
+	}
+
+	docs, err := s.repo.ListApplicationDocuments(ctx, tenantID, id)
+	if err != nil {
+		return transport.ApprovePartnerApplicationResponse{}, err
+	}
+	return transport.ApprovePartnerApplicationResponse{
+		Application: mapApplicationResponse(reviewed, docs),
+		Partner:     partner,
+	}, nil
+}
+
+// RejectApplication closes a pending application. A note explaining the rejection is required.
+func (s *Service) RejectApplication(ctx context.Context, tenantID uuid.UUID, id uuid.UUID, reviewerID uuid.UUID, req transport.ReviewPartnerApplicationRequest) (transport.PartnerApplicationResponse, error) {
+	note := normalizeOptional(req.Note)
+	if note == nil {
+		return transport.PartnerApplicationResponse{}, apperr.Validation("a note is required when rejecting an application")
+	}
+
+	reviewed, err := s.repo.ReviewApplication(ctx, tenantID, id, repository.ApplicationStatusRejected, reviewerID, note, nil)
+	if err != nil {
+		return transport.PartnerApplicationResponse{}, err
+	}
+	return mapApplicationResponse(reviewed, nil), nil
+}
+
+func (s *Service) resolveApplicationUploadToken(ctx context.Context, uploadToken string) (repository.PartnerApplication, error) {
+	uploadToken = strings.TrimSpace(uploadToken)
+	if uploadToken == "" {
+		return repository.PartnerApplication{}, apperr.NotFound("partner application not found")
+	}
+	app, err := s.repo.GetApplicationByUploadTokenHash(ctx, token.HashSHA256(uploadToken))
+	if err != nil {
+		return repository.PartnerApplication{}, err
+	}
+	if app.Status != repository.ApplicationStatusPending {
+		return repository.PartnerApplication{}, apperr.Conflict("partner application is no longer accepting documents")
+	}
+	if time.Now().After(app.UploadTokenExpiresAt) {
+		return repository.PartnerApplication{}, apperr.Forbidden("upload link has expired")
+	}
+	return app, nil
+}
+
+func validateApplicationDocumentContentType(contentType string) error {
+	if !storage.IsDocumentContentType(contentType) && !storage.IsImageContentType(contentType) {
+		return apperr.Validation("document must be a PDF, office document or image")
+	}
+	return nil
+}
+
+func partnerRequestFromApplication(app repository.PartnerApplication) transport.CreatePartnerRequest {
+	kvk := app.KVKNumber
+	return transport.CreatePartnerRequest{
+		BusinessName:   app.BusinessName,
+		KVKNumber:      &kvk,
+		VATNumber:      app.VATNumber,
+		AddressLine1:   app.AddressLine1,
+		HouseNumber:    app.HouseNumber,
+		PostalCode:     app.PostalCode,
+		City:           app.City,
+		Country:        app.Country,
+		ContactName:    app.ContactName,
+		ContactEmail:   app.ContactEmail,
+		ContactPhone:   app.ContactPhone,
+		ServiceTypeIDs: app.ServiceTypeIDs,
+	}
+}
+
+func applicationFolder(tenantID uuid.UUID, applicationID uuid.UUID) string {
+	return "partner-applications/" + tenantID.String() + "/" + applicationID.String()
+}
+
+func mapApplicationResponse(app repository.PartnerApplication, docs []repository.PartnerApplicationDocument) transport.PartnerApplicationResponse {
+	resp := transport.PartnerApplicationResponse{
+		ID:             app.ID,
+		Status:         app.Status,
+		BusinessName:   app.BusinessName,
+		KVKNumber:      app.KVKNumber,
+		VATNumber:      app.VATNumber,
+		AddressLine1:   app.AddressLine1,
+		HouseNumber:    app.HouseNumber,
+		PostalCode:     app.PostalCode,
+		City:           app.City,
+		Country:        app.Country,
+		ContactName:    app.ContactName,
+		ContactEmail:   app.ContactEmail,
+		ContactPhone:   app.ContactPhone,
+		ServiceTypeIDs: app.ServiceTypeIDs,
+		Motivation:     app.Motivation,
+		ReviewedByID:   app.ReviewedByID,
+		ReviewNote:     app.ReviewNote,
+		ReviewedAt:     app.ReviewedAt,
+		PartnerID:      app.PartnerID,
+		CreatedAt:      app.CreatedAt,
+		UpdatedAt:      app.UpdatedAt,
+	}
+	if len(docs) > 0 {
+		resp.Documents = make([]transport.PartnerApplicationDocumentResponse, 0, len(docs))
+		for _, doc := range docs {
+			resp.Documents = append(resp.Documents, mapApplicationDocumentResponse(doc))
+		}
+	}
+	return resp
+}
+
+func mapApplicationDocumentResponse(doc repository.PartnerApplicationDocument) transport.PartnerApplicationDocumentResponse {
+	return transport.PartnerApplicationDocumentResponse{
+		ID:           doc.ID,
+		DocumentType: doc.DocumentType,
+		FileName:     doc.FileName,
+		ContentType:  doc.ContentType,
+		SizeBytes:    doc.SizeBytes,
+		CreatedAt:    doc.CreatedAt,
+	}
+}
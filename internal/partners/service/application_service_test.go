@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/partners/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateApplicationDocumentContentType(t *testing.T) {
+	for _, contentType := range []string{"application/pdf", "image/jpeg", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"} {
+		if err := validateApplicationDocumentContentType(contentType); err != nil {
+			t.Fatalf("expected %q to be accepted, got %v", contentType, err)
+		}
+	}
+	if err := validateApplicationDocumentContentType("application/x-msdownload"); err == nil {
+		t.Fatal("expected executable content type to be rejected")
+	}
+}
+
+func TestPartnerRequestFromApplicationCarriesVettedFields(t *testing.T) {
+	vat := "NL123456789B01"
+	serviceTypeID := uuid.New()
+	app := repository.PartnerApplication{
+		BusinessName:   "Bouwbedrijf Jansen",
+		KVKNumber:      "12345678",
+		VATNumber:      &vat,
+		HouseNumber:    "12a",
+		ContactEmail:   "info@jansen.nl",
+		ServiceTypeIDs: []uuid.UUID{serviceTypeID},
+	}
+
+	req := partnerRequestFromApplication(app)
+	if req.KVKNumber == nil || *req.KVKNumber != app.KVKNumber {
+		t.Fatalf("expected KVK number to be carried over, got %v", req.KVKNumber)
+	}
+	if req.VATNumber != app.VATNumber || req.HouseNumber != app.HouseNumber || req.ContactEmail != app.ContactEmail {
+		t.Fatalf("unexpected partner request: %+v", req)
+	}
+	if len(req.ServiceTypeIDs) != 1 || req.ServiceTypeIDs[0] != serviceTypeID {
+		t.Fatalf("expected service types to be carried over, got %v", req.ServiceTypeIDs)
+	}
+}
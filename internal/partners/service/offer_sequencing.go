@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"portal_final_backend/internal/partners/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// resolveOfferLeadService returns the service an offer from quote q
+// dispatches. A quote covering several services can dispatch each of them.
+func (s *Service) resolveOfferLeadService(ctx context.Context, tenantID uuid.UUID, q repository.QuoteForOffer, requested *uuid.UUID) (uuid.UUID, error) {
+	primary := *q.LeadServiceID
+	if requested == nil || *requested == primary {
+		return primary, nil
+	}
+	covered, err := s.repo.ListQuoteCoveredServiceIDs(ctx, q.ID, tenantID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return pickOfferLeadService(primary, covered, requested)
+}
+
+func pickOfferLeadService(primary uuid.UUID, covered []uuid.UUID, requested *uuid.UUID) (uuid.UUID, error) {
+	if requested == nil || *requested == primary {
+		return primary, nil
+	}
+	for _, id := range covered {
+		if id == *requested {
+			return id, nil
+		}
+	}
+	return uuid.Nil, apperr.Validation("quote does not cover this lead service")
+}
+
+// ensurePrerequisitesDispatched keeps the sequencing of a project: a service
+// is only offered to partners once the services it depends on are completed
+// or accepted by a partner.
+func (s *Service) ensurePrerequisitesDispatched(ctx context.Context, tenantID uuid.UUID, leadServiceID uuid.UUID) error {
+	prerequisites, err := s.repo.ListUndispatchedPrerequisites(ctx, leadServiceID, tenantID)
+	if err != nil {
+		return err
+	}
+	return prerequisitesConflict(prerequisites)
+}
+
+func prerequisitesConflict(prerequisites []repository.ServicePrerequisite) error {
+	if len(prerequisites) == 0 {
+		return nil
+	}
+	names := make([]string, len(prerequisites))
+	for i, p := range prerequisites {
+		names[i] = p.ServiceType
+	}
+	return apperr.Conflict(fmt.Sprintf("service depends on %s, which must be dispatched first", strings.Join(names, ", ")))
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"portal_final_backend/internal/partners/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestPickOfferLeadServiceAllowsCoveredServices(t *testing.T) {
+	primary, covered, other := uuid.New(), uuid.New(), uuid.New()
+
+	if got, err := pickOfferLeadService(primary, []uuid.UUID{covered}, nil); err != nil || got != primary {
+		t.Fatalf("default = %s, %v; want primary", got, err)
+	}
+	if got, err := pickOfferLeadService(primary, []uuid.UUID{covered}, &covered); err != nil || got != covered {
+		t.Fatalf("covered = %s, %v; want covered service", got, err)
+	}
+	if _, err := pickOfferLeadService(primary, []uuid.UUID{covered}, &other); err == nil {
+		t.Fatal("expected an error for a service the quote does not cover")
+	}
+}
+
+func TestPrerequisitesConflictNamesBlockingServices(t *testing.T) {
+	if err := prerequisitesConflict(nil); err != nil {
+		t.Fatalf("prerequisitesConflict(nil) = %v", err)
+	}
+	err := prerequisitesConflict([]repository.ServicePrerequisite{{ServiceType: "Dakkapel"}, {ServiceType: "Dakwerk"}})
+	if err == nil || !strings.Contains(err.Error(), "Dakkapel, Dakwerk") {
+		t.Fatalf("prerequisitesConflict() = %v", err)
+	}
+}
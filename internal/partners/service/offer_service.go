@@ -69,7 +69,10 @@ func (s *Service) CreateOfferFromQuote(ctx context.Context, tenantID uuid.UUID,
 		return transport.CreateOfferResponse{}, err
 	}
 
-	leadServiceID := *q.LeadServiceID
+	leadServiceID, err := s.resolveOfferLeadService(ctx, tenantID, q, req.LeadServiceID)
+	if err != nil {
+		return transport.CreateOfferResponse{}, err
+	}
 
 	serviceCtx, err := s.resolveOfferContext(ctx, tenantID, leadServiceID)
 	if err != nil {
@@ -79,6 +82,9 @@ func (s *Service) CreateOfferFromQuote(ctx context.Context, tenantID uuid.UUID,
 	if err := s.ensureOfferAvailable(ctx, leadServiceID); err != nil {
 		return transport.CreateOfferResponse{}, err
 	}
+	if err := s.ensurePrerequisitesDispatched(ctx, tenantID, leadServiceID); err != nil {
+		return transport.CreateOfferResponse{}, err
+	}
 
 	rawToken, err := token.GenerateRandomToken(offerTokenBytes)
 	if err != nil {
@@ -110,12 +116,23 @@ func (s *Service) CreateOfferFromQuote(ctx context.Context, tenantID uuid.UUID,
 
 	marginBasisPoints := s.resolveOfferMarginBasisPoints(ctx, tenantID, req.MarginBasisPoints)
 	vakmanPrice := resolveVakmanPrice(customerPrice, marginBasisPoints, req.VakmanPriceCents)
+	var agreement *repository.PriceAgreement
+	if req.VakmanPriceCents == nil && req.MarginBasisPoints == nil {
+		var agreedPrice int64
+		if agreement, agreedPrice = s.resolveAgreementPrice(ctx, tenantID, req.PartnerID, leadServiceID, req.QuoteID, items); agreement != nil {
+			vakmanPrice = resolveVakmanPrice(customerPrice, 0, &agreedPrice)
+			marginBasisPoints = marginFromPrices(customerPrice, vakmanPrice)
+		}
+	}
 
 	scopeAssessment := buildScopeAssessment(items)
 	jobSummaryPtr := sanitizeJobSummary(req.JobSummaryShort)
 	offerLineItems := buildOfferLineItems(items)
 
+	offerID := uuid.New()
+	organizationName, _ := s.repo.GetOrganizationName(ctx, tenantID)
 	offer, err := s.repo.CreateOffer(ctx, repository.PartnerOffer{
+		ID:                 offerID,
 		OrganizationID:     tenantID,
 		PartnerID:          req.PartnerID,
 		LeadServiceID:      leadServiceID,
@@ -128,10 +145,25 @@ func (s *Service) CreateOfferFromQuote(ctx context.Context, tenantID uuid.UUID,
 		OfferLineItems:     offerLineItems,
 		JobSummaryShort:    jobSummaryPtr,
 		RequiresInspection: resolveRequiresInspection(req.RequiresInspection),
-	})
+	}, offerCreatedEvent(offerCreatedParams{
+		offerID:       offerID,
+		tenantID:      tenantID,
+		orgName:       organizationName,
+		partnerID:     req.PartnerID,
+		leadServiceID: leadServiceID,
+		leadID:        serviceCtx.LeadID,
+		vakmanPrice:   vakmanPrice,
+		rawToken:      rawToken,
+		partner:       partner,
+	}))
 	if err != nil {
 		return transport.CreateOfferResponse{}, err
 	}
+	if agreement != nil {
+		if err := s.repo.RecordOfferPriceAgreement(ctx, offer.ID, agreement.ID); err != nil {
+			log.Printf("partners: failed to record price agreement for offer=%s: %v", offer.ID, err)
+		}
+	}
 
 	if payload, ok := s.buildOfferSummaryPayload(offer.ID, tenantID, leadServiceID, serviceCtx, scopeAssessment, items); ok {
 		if err := s.summaryQueue.EnqueuePartnerOfferSummary(ctx, payload); err != nil {
@@ -139,26 +171,16 @@ func (s *Service) CreateOfferFromQuote(ctx context.Context, tenantID uuid.UUID,
 		}
 	}
 
-	organizationName, _ := s.repo.GetOrganizationName(ctx, tenantID)
-
-	s.publishOfferCreated(ctx, offerCreatedParams{
-		offerID:       offer.ID,
-		tenantID:      tenantID,
-		orgName:       organizationName,
-		partnerID:     req.PartnerID,
-		leadServiceID: leadServiceID,
-		leadID:        serviceCtx.LeadID,
-		vakmanPrice:   vakmanPrice,
-		rawToken:      rawToken,
-		partner:       partner,
-	})
-
-	return transport.CreateOfferResponse{
+	resp := transport.CreateOfferResponse{
 		ID:               offer.ID,
 		PublicToken:      rawToken,
 		VakmanPriceCents: vakmanPrice,
 		ExpiresAt:        expiry,
-	}, nil
+	}
+	if agreement != nil {
+		resp.PriceAgreementID = &agreement.ID
+	}
+	return resp, nil
 }
 
 // GetPublicOffer retrieves offer details for the vakman-facing view.
@@ -350,12 +372,11 @@ func (s *Service) AcceptOffer(ctx context.Context, publicToken string, req trans
 		SignerBusinessName: signerBusiness,
 		SignerAddress:      signerAddress,
 		SignatureData:      signatureData,
-	}); err != nil {
+	}, s.acceptedOfferEvent(ctx, oc)); err != nil {
 		return err
 	}
 
 	s.enqueueAcceptedOfferPDF(ctx, oc)
-	s.publishAcceptedOfferEvent(ctx, oc)
 
 	return nil
 }
@@ -393,7 +414,9 @@ func (s *Service) enqueueAcceptedOfferPDF(ctx context.Context, oc repository.Par
 	}
 }
 
-func (s *Service) publishAcceptedOfferEvent(ctx context.Context, oc repository.PartnerOfferWithContext) {
+// acceptedOfferEvent builds the PartnerOfferAccepted event AcceptOffer stores with
+// the acceptance.
+func (s *Service) acceptedOfferEvent(ctx context.Context, oc repository.PartnerOfferWithContext) events.PartnerOfferAccepted {
 	leadID, _ := s.repo.GetLeadIDForService(ctx, oc.LeadServiceID, oc.OrganizationID)
 
 	var partnerEmail string
@@ -405,7 +428,7 @@ func (s *Service) publishAcceptedOfferEvent(ctx context.Context, oc repository.P
 		partnerWhatsAppOptedIn = partner.WhatsAppOptedIn
 	}
 
-	s.eventBus.Publish(ctx, events.PartnerOfferAccepted{
+	return events.PartnerOfferAccepted{
 		BaseEvent:              events.NewBaseEvent(),
 		OfferID:                oc.ID,
 		OrganizationID:         oc.OrganizationID,
@@ -416,7 +439,7 @@ func (s *Service) publishAcceptedOfferEvent(ctx context.Context, oc repository.P
 		PartnerEmail:           partnerEmail,
 		PartnerPhone:           partnerPhone,
 		PartnerWhatsAppOptedIn: partnerWhatsAppOptedIn,
-	})
+	}
 }
 
 func nilIfEmpty(s string) *string {
@@ -437,14 +460,10 @@ func (s *Service) RejectOffer(ctx context.Context, publicToken string, req trans
 		return apperr.Conflict("offer cannot be rejected in current state")
 	}
 
-	if err := s.repo.RejectOffer(ctx, oc.ID, req.Reason); err != nil {
-		return err
-	}
-
 	// Resolve lead ID for timeline/notification handlers
 	leadID, _ := s.repo.GetLeadIDForService(ctx, oc.LeadServiceID, oc.OrganizationID)
 
-	s.eventBus.Publish(ctx, events.PartnerOfferRejected{
+	return s.repo.RejectOffer(ctx, oc.ID, req.Reason, events.PartnerOfferRejected{
 		BaseEvent:      events.NewBaseEvent(),
 		OfferID:        oc.ID,
 		OrganizationID: oc.OrganizationID,
@@ -454,8 +473,6 @@ func (s *Service) RejectOffer(ctx context.Context, publicToken string, req trans
 		PartnerName:    oc.PartnerName,
 		Reason:         req.Reason,
 	})
-
-	return nil
 }
 
 // GetOfferPreview returns the same vakman-facing view but requires authentication.
@@ -762,11 +779,10 @@ type offerCreatedParams struct {
 	partner       repository.Partner
 }
 
-func (s *Service) publishOfferCreated(ctx context.Context, params offerCreatedParams) {
-	if s.eventBus == nil {
-		return
-	}
-	s.eventBus.Publish(ctx, events.PartnerOfferCreated{
+// offerCreatedEvent builds the PartnerOfferCreated event. CreateOffer stores it
+// with the offer; ResendOffer publishes it again.
+func offerCreatedEvent(params offerCreatedParams) events.PartnerOfferCreated {
+	return events.PartnerOfferCreated{
 		BaseEvent:        events.NewBaseEvent(),
 		OfferID:          params.offerID,
 		OrganizationID:   params.tenantID,
@@ -779,7 +795,14 @@ func (s *Service) publishOfferCreated(ctx context.Context, params offerCreatedPa
 		PartnerName:      params.partner.BusinessName,
 		PartnerPhone:     params.partner.ContactPhone,
 		PartnerEmail:     params.partner.ContactEmail,
-	})
+	}
+}
+
+func (s *Service) publishOfferCreated(ctx context.Context, params offerCreatedParams) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(ctx, offerCreatedEvent(params))
 }
 
 func buildSummaryHeader(scopeAssessment *string, urgencyLevel *string) []string {
@@ -1116,13 +1139,9 @@ func (s *Service) DeleteOffer(ctx context.Context, tenantID uuid.UUID, offerID u
 		return apperr.Conflict("offer cannot be deleted").WithDetails(map[string]any{"status": offer.Status})
 	}
 
-	if err := s.repo.DeleteOffer(ctx, offerID, tenantID); err != nil {
-		return err
-	}
-
-	// Publish event so the orchestrator can reconcile the pipeline stage.
+	// The event lets the orchestrator reconcile the pipeline stage.
 	leadID, _ := s.repo.GetLeadIDForService(ctx, offer.LeadServiceID, tenantID)
-	s.eventBus.Publish(ctx, events.PartnerOfferDeleted{
+	return s.repo.DeleteOffer(ctx, offerID, tenantID, events.PartnerOfferDeleted{
 		BaseEvent:      events.NewBaseEvent(),
 		OfferID:        offerID,
 		OrganizationID: tenantID,
@@ -1130,8 +1149,6 @@ func (s *Service) DeleteOffer(ctx context.Context, tenantID uuid.UUID, offerID u
 		LeadServiceID:  offer.LeadServiceID,
 		LeadID:         leadID,
 	})
-
-	return nil
 }
 
 // ListOffersByPartner returns all offers for a given partner (admin view).
@@ -1170,30 +1187,20 @@ func (s *Service) ListOffersForService(ctx context.Context, tenantID uuid.UUID,
 
 // ExpireOffers is called by a background job to expire stale offers.
 func (s *Service) ExpireOffers(ctx context.Context) (int, error) {
-	expired, err := s.repo.ExpireOffers(ctx)
-	if err != nil {
-		return 0, err
-	}
-
-	for _, o := range expired {
-		// Resolve lead ID and partner name for timeline handlers
-		leadID, _ := s.repo.GetLeadIDForService(ctx, o.LeadServiceID, o.OrganizationID)
-		var partnerName string
-		if p, err := s.repo.GetByID(ctx, o.PartnerID, o.OrganizationID); err == nil {
-			partnerName = p.BusinessName
-		}
-
-		s.eventBus.Publish(ctx, events.PartnerOfferExpired{
+	expired, err := s.repo.ExpireOffers(ctx, func(o repository.ExpiredOffer) events.Event {
+		return events.PartnerOfferExpired{
 			BaseEvent:      events.NewBaseEvent(),
 			OfferID:        o.ID,
 			OrganizationID: o.OrganizationID,
 			PartnerID:      o.PartnerID,
 			LeadServiceID:  o.LeadServiceID,
-			LeadID:         leadID,
-			PartnerName:    partnerName,
-		})
+			LeadID:         o.LeadID,
+			PartnerName:    o.PartnerName,
+		}
+	})
+	if err != nil {
+		return 0, err
 	}
-
 	return len(expired), nil
 }
 
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/partners/repository"
+	"portal_final_backend/internal/partners/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const agreementDateLayout = "2006-01-02"
+
+var offerQuantityPattern = regexp.MustCompile(`^\s*(\d+(?:[.,]\d+)?)`)
+
+// CreatePriceAgreement records a price agreement with a partner. An agreement
+// already in effect for the same service type is renegotiated: it ends the
+// day before the new one starts.
+func (s *Service) CreatePriceAgreement(ctx context.Context, tenantID, partnerID, userID uuid.UUID, req transport.CreatePriceAgreementRequest) (transport.PriceAgreementResponse, error) {
+	if _, err := s.repo.GetByID(ctx, partnerID, tenantID); err != nil {
+		return transport.PriceAgreementResponse{}, err
+	}
+	if req.ServiceTypeID != nil {
+		if err := s.repo.ValidateServiceTypeIDs(ctx, tenantID, []uuid.UUID{*req.ServiceTypeID}); err != nil {
+			return transport.PriceAgreementResponse{}, err
+		}
+	}
+
+	from, to, err := parseAgreementPeriod(req.EffectiveFrom, req.EffectiveTo)
+	if err != nil {
+		return transport.PriceAgreementResponse{}, err
+	}
+	rates, err := normalizeAgreementRates(req.PricingModel, req.Rates)
+	if err != nil {
+		return transport.PriceAgreementResponse{}, err
+	}
+
+	agreement, err := s.repo.CreatePriceAgreement(ctx, repository.PriceAgreement{
+		OrganizationID: tenantID,
+		PartnerID:      partnerID,
+		ServiceTypeID:  req.ServiceTypeID,
+		PricingModel:   req.PricingModel,
+		PercentageBps:  req.PercentageBps,
+		Rates:          rates,
+		LeadFeeCents:   req.LeadFeeCents,
+		EffectiveFrom:  from,
+		EffectiveTo:    to,
+		Notes:          normalizeOptionalText(req.Notes),
+		CreatedBy:      &userID,
+	})
+	if err != nil {
+		return transport.PriceAgreementResponse{}, err
+	}
+	return mapPriceAgreementResponse(agreement, time.Now()), nil
+}
+
+// ListPriceAgreements returns the pricing history of a partner.
+func (s *Service) ListPriceAgreements(ctx context.Context, tenantID, partnerID uuid.UUID) (transport.PriceAgreementListResponse, error) {
+	if _, err := s.repo.GetByID(ctx, partnerID, tenantID); err != nil {
+		return transport.PriceAgreementListResponse{}, err
+	}
+	agreements, err := s.repo.ListPriceAgreements(ctx, partnerID, tenantID)
+	if err != nil {
+		return transport.PriceAgreementListResponse{}, err
+	}
+	now := time.Now()
+	items := make([]transport.PriceAgreementResponse, 0, len(agreements))
+	for _, a := range agreements {
+		items = append(items, mapPriceAgreementResponse(a, now))
+	}
+	return transport.PriceAgreementListResponse{Items: items}, nil
+}
+
+// EndPriceAgreement ends an agreement without a successor.
+func (s *Service) EndPriceAgreement(ctx context.Context, tenantID, partnerID, agreementID uuid.UUID, req transport.EndPriceAgreementRequest) (transport.PriceAgreementResponse, error) {
+	endDate, err := time.Parse(agreementDateLayout, req.EffectiveTo)
+	if err != nil {
+		return transport.PriceAgreementResponse{}, apperr.Validation("invalid end date")
+	}
+	agreement, err := s.repo.EndPriceAgreement(ctx, agreementID, partnerID, tenantID, endDate)
+	if err != nil {
+		return transport.PriceAgreementResponse{}, err
+	}
+	return mapPriceAgreementResponse(agreement, time.Now()), nil
+}
+
+// resolveAgreementPrice prices an offer with the agreement in effect for the
+// partner and service. It returns nil when there is no agreement; lookup
+// failures fall back to margin pricing.
+func (s *Service) resolveAgreementPrice(ctx context.Context, tenantID, partnerID, leadServiceID, quoteID uuid.UUID, items []repository.QuoteItemSummary) (*repository.PriceAgreement, int64) {
+	agreement, err := s.repo.GetEffectivePriceAgreement(ctx, tenantID, partnerID, leadServiceID, time.Now())
+	if err != nil {
+		log.Printf("partners: failed to load price agreement for partner=%s service=%s: %v", partnerID, leadServiceID, err)
+		return nil, 0
+	}
+	if agreement == nil {
+		return nil, 0
+	}
+
+	var products map[uuid.UUID]uuid.UUID
+	if agreement.PricingModel == repository.PricingModelItemPrices {
+		products, err = s.repo.GetQuoteItemCatalogProducts(ctx, quoteID, tenantID)
+		if err != nil {
+			log.Printf("partners: failed to load catalog products of quote=%s: %v", quoteID, err)
+			return nil, 0
+		}
+	}
+	return agreement, agreementVakmanPrice(*agreement, items, products)
+}
+
+// agreementVakmanPrice sums the partner price of the offered items: the rate
+// of a matching rate card or item price times the quantity, otherwise the
+// agreed share of the customer price of the item.
+func agreementVakmanPrice(agreement repository.PriceAgreement, items []repository.QuoteItemSummary, products map[uuid.UUID]uuid.UUID) int64 {
+	var total int64
+	for _, item := range items {
+		if rate, ok := matchAgreementRate(agreement, item, products); ok {
+			total += int64(math.Round(parseOfferQuantity(item.Quantity) * float64(rate.UnitPriceCents)))
+			continue
+		}
+		total += item.LineTotalCents * int64(agreement.PercentageBps) / 10000
+	}
+	if total < 0 {
+		return 0
+	}
+	return total
+}
+
+func matchAgreementRate(agreement repository.PriceAgreement, item repository.QuoteItemSummary, products map[uuid.UUID]uuid.UUID) (repository.PriceAgreementRate, bool) {
+	switch agreement.PricingModel {
+	case repository.PricingModelRateCard:
+		description := strings.ToLower(item.Description)
+		for _, rate := range agreement.Rates {
+			if rate.Keyword != "" && strings.Contains(description, strings.ToLower(rate.Keyword)) {
+				return rate, true
+			}
+		}
+	case repository.PricingModelItemPrices:
+		productID, ok := products[item.ID]
+		if !ok {
+			break
+		}
+		for _, rate := range agreement.Rates {
+			if rate.CatalogProductID != nil && *rate.CatalogProductID == productID {
+				return rate, true
+			}
+		}
+	}
+	return repository.PriceAgreementRate{}, false
+}
+
+// marginFromPrices is the margin an offer keeps between the customer and the
+// vakman price.
+func marginFromPrices(customerPriceCents, vakmanPriceCents int64) int {
+	if customerPriceCents <= 0 || vakmanPriceCents >= customerPriceCents {
+		return 0
+	}
+	return int((customerPriceCents - vakmanPriceCents) * 10000 / customerPriceCents)
+}
+
+func parseOfferQuantity(quantity string) float64 {
+	matches := offerQuantityPattern.FindStringSubmatch(quantity)
+	if len(matches) < 2 {
+		return 1
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(matches[1], ",", "."), 64)
+	if err != nil || value <= 0 {
+		return 1
+	}
+	return value
+}
+
+func parseAgreementPeriod(fromRaw string, toRaw *string) (time.Time, *time.Time, error) {
+	from, err := time.Parse(agreementDateLayout, fromRaw)
+	if err != nil {
+		return time.Time{}, nil, apperr.Validation("invalid start date")
+	}
+	if toRaw == nil || strings.TrimSpace(*toRaw) == "" {
+		return from, nil, nil
+	}
+	to, err := time.Parse(agreementDateLayout, *toRaw)
+	if err != nil {
+		return time.Time{}, nil, apperr.Validation("invalid end date")
+	}
+	if to.Before(from) {
+		return time.Time{}, nil, apperr.Validation("end date must not be before the start date")
+	}
+	return from, &to, nil
+}
+
+func normalizeAgreementRates(model string, rates []transport.PriceAgreementRateDTO) ([]repository.PriceAgreementRate, error) {
+	if model == repository.PricingModelPercentage {
+		if len(rates) > 0 {
+			return nil, apperr.Validation("percentage agreements have no rates")
+		}
+		return []repository.PriceAgreementRate{}, nil
+	}
+	if len(rates) == 0 {
+		return nil, apperr.Validation("agreement needs at least one rate")
+	}
+
+	result := make([]repository.PriceAgreementRate, 0, len(rates))
+	for _, rate := range rates {
+		keyword := strings.TrimSpace(rate.Keyword)
+		switch model {
+		case repository.PricingModelRateCard:
+			if keyword == "" {
+				return nil, apperr.Validation("rate card rates need a keyword")
+			}
+			result = append(result, repository.PriceAgreementRate{Keyword: keyword, UnitPriceCents: rate.UnitPriceCents})
+		case repository.PricingModelItemPrices:
+			if rate.CatalogProductID == nil || *rate.CatalogProductID == uuid.Nil {
+				return nil, apperr.Validation("item prices need a catalog product")
+			}
+			result = append(result, repository.PriceAgreementRate{CatalogProductID: rate.CatalogProductID, UnitPriceCents: rate.UnitPriceCents})
+		}
+	}
+	return result, nil
+}
+
+func normalizeOptionalText(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func mapPriceAgreementResponse(a repository.PriceAgreement, now time.Time) transport.PriceAgreementResponse {
+	rates := make([]transport.PriceAgreementRateDTO, 0, len(a.Rates))
+	for _, rate := range a.Rates {
+		rates = append(rates, transport.PriceAgreementRateDTO{CatalogProductID: rate.CatalogProductID, Keyword: rate.Keyword, UnitPriceCents: rate.UnitPriceCents})
+	}
+
+	today := now.Format(agreementDateLayout)
+	from := a.EffectiveFrom.Format(agreementDateLayout)
+	resp := transport.PriceAgreementResponse{
+		ID:            a.ID,
+		PartnerID:     a.PartnerID,
+		ServiceTypeID: a.ServiceTypeID,
+		ServiceType:   a.ServiceType,
+		PricingModel:  a.PricingModel,
+		PercentageBps: a.PercentageBps,
+		Rates:         rates,
+		LeadFeeCents:  a.LeadFeeCents,
+		EffectiveFrom: from,
+		Notes:         a.Notes,
+		SupersededBy:  a.SupersededBy,
+		IsActive:      from <= today,
+		CreatedBy:     a.CreatedBy,
+		CreatedAt:     a.CreatedAt,
+	}
+	if a.EffectiveTo != nil {
+		to := a.EffectiveTo.Format(agreementDateLayout)
+		resp.EffectiveTo = &to
+		resp.IsActive = resp.IsActive && to >= today
+	}
+	return resp
+}
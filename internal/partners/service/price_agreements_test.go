@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/partners/repository"
+	"portal_final_backend/internal/partners/transport"
+
+	"github.com/google/uuid"
+)
+
+func TestAgreementVakmanPriceUsesRatesAndFallsBackToPercentage(t *testing.T) {
+	items := []repository.QuoteItemSummary{
+		{ID: uuid.New(), Description: "Dakbedekking EPDM", Quantity: "12,5 m²", LineTotalCents: 100000},
+		{ID: uuid.New(), Description: "Afvoer bouwafval", Quantity: "1", LineTotalCents: 20000},
+	}
+	agreement := repository.PriceAgreement{
+		PricingModel:  repository.PricingModelRateCard,
+		PercentageBps: 7000,
+		Rates:         []repository.PriceAgreementRate{{Keyword: "epdm", UnitPriceCents: 4000}},
+	}
+
+	// 12.5 × €40 for the matched item, 70% of €200 for the other.
+	if got := agreementVakmanPrice(agreement, items, nil); got != 50000+14000 {
+		t.Fatalf("rate card price = %d, want %d", got, 64000)
+	}
+
+	productID := uuid.New()
+	agreement = repository.PriceAgreement{
+		PricingModel:  repository.PricingModelItemPrices,
+		PercentageBps: 5000,
+		Rates:         []repository.PriceAgreementRate{{CatalogProductID: &productID, UnitPriceCents: 3000}},
+	}
+	products := map[uuid.UUID]uuid.UUID{items[1].ID: productID}
+	if got := agreementVakmanPrice(agreement, items, products); got != 50000+3000 {
+		t.Fatalf("item price = %d, want %d", got, 53000)
+	}
+}
+
+func TestMarginFromPrices(t *testing.T) {
+	if got := marginFromPrices(100000, 75000); got != 2500 {
+		t.Fatalf("marginFromPrices() = %d, want 2500", got)
+	}
+	if got := marginFromPrices(100000, 120000); got != 0 {
+		t.Fatalf("marginFromPrices() = %d, want 0 when the partner gets everything", got)
+	}
+}
+
+func TestNormalizeAgreementRatesValidatesPerModel(t *testing.T) {
+	if _, err := normalizeAgreementRates(repository.PricingModelRateCard, []transport.PriceAgreementRateDTO{{UnitPriceCents: 100}}); err == nil {
+		t.Fatal("expected rate card rates without keyword to be rejected")
+	}
+	if _, err := normalizeAgreementRates(repository.PricingModelItemPrices, nil); err == nil {
+		t.Fatal("expected item prices without rates to be rejected")
+	}
+	if _, err := normalizeAgreementRates(repository.PricingModelPercentage, []transport.PriceAgreementRateDTO{{Keyword: "x"}}); err == nil {
+		t.Fatal("expected percentage agreements with rates to be rejected")
+	}
+	rates, err := normalizeAgreementRates(repository.PricingModelRateCard, []transport.PriceAgreementRateDTO{{Keyword: " Stucwerk ", UnitPriceCents: 2500}})
+	if err != nil || len(rates) != 1 || rates[0].Keyword != "Stucwerk" {
+		t.Fatalf("normalizeAgreementRates() = %v, %v", rates, err)
+	}
+}
@@ -271,6 +271,7 @@ WHERE id = sqlc.arg(organization_id)::uuid;
 
 -- name: CreatePartnerOffer :one
 INSERT INTO RAC_partner_offers (
+	id,
 	organization_id,
 	partner_id,
 	lead_service_id,
@@ -286,6 +287,7 @@ INSERT INTO RAC_partner_offers (
 	requires_inspection,
 	status
 ) VALUES (
+	sqlc.arg(id)::uuid,
 	sqlc.arg(organization_id)::uuid,
 	sqlc.arg(partner_id)::uuid,
 	sqlc.arg(lead_service_id)::uuid,
@@ -679,12 +681,14 @@ WHERE id = sqlc.arg(offer_id)::uuid
   AND status IN ('pending', 'sent');
 
 -- name: ExpirePartnerOffers :many
-UPDATE RAC_partner_offers
+UPDATE RAC_partner_offers o
 SET status = 'expired',
 	updated_at = now()
-WHERE status IN ('pending', 'sent')
-  AND expires_at < now()
-RETURNING id, organization_id, partner_id, lead_service_id;
+WHERE o.status IN ('pending', 'sent')
+  AND o.expires_at < now()
+RETURNING o.id, o.organization_id, o.partner_id, o.lead_service_id,
+	(SELECT ls.lead_id FROM RAC_lead_services ls WHERE ls.id = o.lead_service_id)::uuid AS lead_id,
+	COALESCE((SELECT p.business_name FROM RAC_partners p WHERE p.id = o.partner_id), '')::text AS partner_name;
 
 -- name: CountPartnerOffers :one
 SELECT COUNT(*)::bigint
@@ -0,0 +1,114 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubmitPartnerApplicationRequest is the public partner application form.
+type SubmitPartnerApplicationRequest struct {
+	BusinessName   string      `json:"businessName" validate:"required,min=1,max=200"`
+	KVKNumber      string      `json:"kvkNumber" validate:"required,max=20"`
+	VATNumber      *string     `json:"vatNumber,omitempty" validate:"omitempty,max=20"`
+	AddressLine1   string      `json:"addressLine1" validate:"required,max=200"`
+	HouseNumber    string      `json:"houseNumber" validate:"required,max=20"`
+	PostalCode     string      `json:"postalCode" validate:"required,max=20"`
+	City           string      `json:"city" validate:"required,max=120"`
+	Country        string      `json:"country" validate:"required,max=120"`
+	ContactName    string      `json:"contactName" validate:"required,max=120"`
+	ContactEmail   string      `json:"contactEmail" validate:"required,email"`
+	ContactPhone   string      `json:"contactPhone" validate:"required,max=50"`
+	ServiceTypeIDs []uuid.UUID `json:"serviceTypeIds,omitempty" validate:"omitempty,dive,required"`
+	Motivation     string      `json:"motivation,omitempty" validate:"omitempty,max=2000"`
+}
+
+// SubmitPartnerApplicationResponse returns the token used to upload vetting documents.
+type SubmitPartnerApplicationResponse struct {
+	ApplicationID        uuid.UUID `json:"applicationId"`
+	UploadToken          string    `json:"uploadToken"`
+	UploadTokenExpiresAt time.Time `json:"uploadTokenExpiresAt"`
+}
+
+// PartnerApplicationDocumentPresignRequest requests an upload URL for a vetting document.
+type PartnerApplicationDocumentPresignRequest struct {
+	DocumentType string `json:"documentType" validate:"required,oneof=insurance certification other"`
+	FileName     string `json:"fileName" validate:"required,min=1,max=255"`
+	ContentType  string `json:"contentType" validate:"required,min=1,max=100"`
+	SizeBytes    int64  `json:"sizeBytes" validate:"required,min=1"`
+}
+
+// PartnerApplicationDocumentPresignResponse returns a presigned document upload URL.
+type PartnerApplicationDocumentPresignResponse struct {
+	UploadURL string `json:"uploadUrl"`
+	FileKey   string `json:"fileKey"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+// ConfirmPartnerApplicationDocumentRequest stores document metadata after upload.
+type ConfirmPartnerApplicationDocumentRequest struct {
+	DocumentType string `json:"documentType" validate:"required,oneof=insurance certification other"`
+	FileKey      string `json:"fileKey" validate:"required,min=1,max=500"`
+	FileName     string `json:"fileName" validate:"required,min=1,max=255"`
+	ContentType  string `json:"contentType" validate:"required,min=1,max=100"`
+	SizeBytes    int64  `json:"sizeBytes" validate:"required,min=1"`
+}
+
+type PartnerApplicationDocumentResponse struct {
+	ID           uuid.UUID `json:"id"`
+	DocumentType string    `json:"documentType"`
+	FileName     string    `json:"fileName"`
+	ContentType  string    `json:"contentType"`
+	SizeBytes    int64     `json:"sizeBytes"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type PartnerApplicationResponse struct {
+	ID             uuid.UUID                            `json:"id"`
+	Status         string                               `json:"status"`
+	BusinessName   string                               `json:"businessName"`
+	KVKNumber      string                               `json:"kvkNumber"`
+	VATNumber      *string                              `json:"vatNumber,omitempty"`
+	AddressLine1   string                               `json:"addressLine1"`
+	HouseNumber    string                               `json:"houseNumber"`
+	PostalCode     string                               `json:"postalCode"`
+	City           string                               `json:"city"`
+	Country        string                               `json:"country"`
+	ContactName    string                               `json:"contactName"`
+	ContactEmail   string                               `json:"contactEmail"`
+	ContactPhone   string                               `json:"contactPhone"`
+	ServiceTypeIDs []uuid.UUID                          `json:"serviceTypeIds"`
+	Motivation     *string                              `json:"motivation,omitempty"`
+	ReviewedByID   *uuid.UUID                           `json:"reviewedById,omitempty"`
+	ReviewNote     *string                              `json:"reviewNote,omitempty"`
+	ReviewedAt     *time.Time                           `json:"reviewedAt,omitempty"`
+	PartnerID      *uuid.UUID                           `json:"partnerId,omitempty"`
+	Documents      []PartnerApplicationDocumentResponse `json:"documents,omitempty"`
+	CreatedAt      time.Time                            `json:"createdAt"`
+	UpdatedAt      time.Time                            `json:"updatedAt"`
+}
+
+type ListPartnerApplicationsRequest struct {
+	Status   string `form:"status" validate:"omitempty,oneof=pending approved rejected"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type ListPartnerApplicationsResponse struct {
+	Items      []PartnerApplicationResponse `json:"items"`
+	Total      int                          `json:"total"`
+	Page       int                          `json:"page"`
+	PageSize   int                          `json:"pageSize"`
+	TotalPages int                          `json:"totalPages"`
+}
+
+// ReviewPartnerApplicationRequest carries the reviewer's note. A note is required when rejecting.
+type ReviewPartnerApplicationRequest struct {
+	Note string `json:"note,omitempty" validate:"omitempty,max=2000"`
+}
+
+// ApprovePartnerApplicationResponse returns the reviewed application and the partner it was converted into.
+type ApprovePartnerApplicationResponse struct {
+	Application PartnerApplicationResponse `json:"application"`
+	Partner     PartnerResponse            `json:"partner"`
+}
@@ -19,6 +19,9 @@ type CreateOfferFromQuoteRequest struct {
 	VakmanPriceCents   *int64      `json:"vakmanPriceCents,omitempty" validate:"omitempty,min=0"`
 	SelectedItemIDs    []uuid.UUID `json:"selectedItemIds,omitempty" validate:"omitempty,dive,uuid"`
 	RequiresInspection *bool       `json:"requiresInspection,omitempty"`
+	// LeadServiceID picks the service to dispatch when the quote covers
+	// several; it defaults to the quote's own lead service.
+	LeadServiceID *uuid.UUID `json:"leadServiceId,omitempty"`
 }
 
 // CreateOfferResponse is returned after successfully creating an offer.
@@ -27,6 +30,8 @@ type CreateOfferResponse struct {
 	PublicToken      string    `json:"publicToken"`
 	VakmanPriceCents int64     `json:"vakmanPriceCents"`
 	ExpiresAt        time.Time `json:"expiresAt"`
+	// PriceAgreementID is the agreement the vakman price was derived from.
+	PriceAgreementID *uuid.UUID `json:"priceAgreementId,omitempty"`
 }
 
 // OfferResponse is the admin/agent view of an offer.
@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreatePriceAgreementRequest records or renegotiates what a partner earns
+// for a service type. PercentageBps is the partner's share of the customer
+// price; for rate cards and item prices it covers items without a rate.
+// LeadFeeCents is charged to the partner for every job it completes.
+type CreatePriceAgreementRequest struct {
+	ServiceTypeID *uuid.UUID              `json:"serviceTypeId,omitempty"`
+	PricingModel  string                  `json:"pricingModel" validate:"required,oneof=percentage rate_card item_prices"`
+	PercentageBps int                     `json:"percentageBps" validate:"min=0,max=10000"`
+	Rates         []PriceAgreementRateDTO `json:"rates" validate:"omitempty,max=200,dive"`
+	LeadFeeCents  int64                   `json:"leadFeeCents" validate:"min=0"`
+	EffectiveFrom string                  `json:"effectiveFrom" validate:"required,datetime=2006-01-02"`
+	EffectiveTo   *string                 `json:"effectiveTo,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Notes         *string                 `json:"notes,omitempty" validate:"omitempty,max=2000"`
+}
+
+// PriceAgreementRateDTO is a partner price per unit, matched on a keyword in
+// the item description (rate card) or on a catalog product (item prices).
+type PriceAgreementRateDTO struct {
+	CatalogProductID *uuid.UUID `json:"catalogProductId,omitempty"`
+	Keyword          string     `json:"keyword,omitempty" validate:"max=100"`
+	UnitPriceCents   int64      `json:"unitPriceCents" validate:"min=0"`
+}
+
+// EndPriceAgreementRequest ends an agreement without a successor.
+type EndPriceAgreementRequest struct {
+	EffectiveTo string `json:"effectiveTo" validate:"required,datetime=2006-01-02"`
+}
+
+type PriceAgreementResponse struct {
+	ID            uuid.UUID               `json:"id"`
+	PartnerID     uuid.UUID               `json:"partnerId"`
+	ServiceTypeID *uuid.UUID              `json:"serviceTypeId,omitempty"`
+	ServiceType   *string                 `json:"serviceType,omitempty"`
+	PricingModel  string                  `json:"pricingModel"`
+	PercentageBps int                     `json:"percentageBps"`
+	Rates         []PriceAgreementRateDTO `json:"rates"`
+	LeadFeeCents  int64                   `json:"leadFeeCents"`
+	EffectiveFrom string                  `json:"effectiveFrom"`
+	EffectiveTo   *string                 `json:"effectiveTo,omitempty"`
+	Notes         *string                 `json:"notes,omitempty"`
+	SupersededBy  *uuid.UUID              `json:"supersededBy,omitempty"`
+	IsActive      bool                    `json:"isActive"`
+	CreatedBy     *uuid.UUID              `json:"createdBy,omitempty"`
+	CreatedAt     time.Time               `json:"createdAt"`
+}
+
+type PriceAgreementListResponse struct {
+	Items []PriceAgreementResponse `json:"items"`
+}
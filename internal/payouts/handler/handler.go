@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/payouts/service"
+	"portal_final_backend/internal/payouts/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StatementPDFGenerator renders and stores the PDF of a payout statement.
+type StatementPDFGenerator interface {
+	GeneratePayoutStatementPDF(ctx context.Context, statementID, organizationID uuid.UUID) (fileKey string, pdfBytes []byte, err error)
+}
+
+type Handler struct {
+	svc        *service.Service
+	val        *validator.Validator
+	pdfGen     StatementPDFGenerator
+	storageSvc storage.StorageService
+	pdfBucket  string
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+// SetPDFGenerator injects the generator used for statement PDF downloads.
+func (h *Handler) SetPDFGenerator(gen StatementPDFGenerator) {
+	h.pdfGen = gen
+}
+
+// SetStorageForPDF injects the storage service and bucket generated statement
+// PDFs are served from.
+func (h *Handler) SetStorageForPDF(svc storage.StorageService, bucket string) {
+	h.storageSvc = svc
+	h.pdfBucket = bucket
+}
+
+// RegisterRoutes registers commissions, adjustments and payout statements.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/commissions", h.ListCommissions)
+	rg.GET("/adjustments", h.ListAdjustments)
+	rg.POST("/adjustments", h.CreateAdjustment)
+	rg.DELETE("/adjustments/:id", h.DeleteAdjustment)
+	rg.GET("/statements", h.ListStatements)
+	rg.POST("/statements", h.GenerateStatements)
+	rg.GET("/statements/:id", h.GetStatement)
+	rg.GET("/statements/:id/pdf", h.DownloadStatementPDF)
+	rg.POST("/statements/:id/export/:provider", h.ExportStatement)
+}
+
+func (h *Handler) ListCommissions(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListCommissionsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListCommissions(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) ListAdjustments(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListAdjustmentsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListAdjustments(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) CreateAdjustment(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.CreateAdjustmentRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.CreateAdjustment(c.Request.Context(), tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
+
+func (h *Handler) DeleteAdjustment(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.DeleteAdjustment(c.Request.Context(), tenantID, id)) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ListStatements(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListStatementsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListStatements(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GenerateStatements(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.GenerateStatementsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GenerateStatements(c.Request.Context(), tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
+
+func (h *Handler) GetStatement(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetStatement(c.Request.Context(), tenantID, id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// DownloadStatementPDF generates the PDF of a statement on first download and
+// serves it from storage afterwards.
+func (h *Handler) DownloadStatementPDF(c *gin.Context) {
+	if h.storageSvc == nil {
+		httpkit.Error(c, http.StatusServiceUnavailable, "PDF downloads are not configured", nil)
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	number, fileKey, err := h.svc.GetStatementPDFFileKey(c.Request.Context(), tenantID, id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	fileName := fmt.Sprintf("Uitbetaling-%s.pdf", number)
+
+	if fileKey == nil || *fileKey == "" {
+		if h.pdfGen == nil {
+			httpkit.Error(c, http.StatusNotFound, "no PDF available for this statement", nil)
+			return
+		}
+		_, pdfBytes, genErr := h.pdfGen.GeneratePayoutStatementPDF(c.Request.Context(), id, tenantID)
+		if genErr != nil {
+			httpkit.Error(c, http.StatusInternalServerError, "failed to generate PDF", genErr.Error())
+			return
+		}
+		servePDF(c, fileName, pdfBytes)
+		return
+	}
+
+	reader, err := h.storageSvc.DownloadFile(c.Request.Context(), h.pdfBucket, *fileKey)
+	if err != nil {
+		httpkit.Error(c, http.StatusInternalServerError, "failed to retrieve PDF", err.Error())
+		return
+	}
+	defer func() { _ = reader.Close() }()
+	pdfBytes, err := io.ReadAll(reader)
+	if err != nil {
+		httpkit.Error(c, http.StatusInternalServerError, "failed to read PDF", err.Error())
+		return
+	}
+	servePDF(c, fileName, pdfBytes)
+}
+
+func (h *Handler) ExportStatement(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ExportStatement(c.Request.Context(), tenantID, id, c.Param("provider"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func servePDF(c *gin.Context, fileName string, pdfBytes []byte) {
+	if !bytes.HasPrefix(pdfBytes, []byte("%PDF-")) {
+		httpkit.Error(c, http.StatusInternalServerError, "failed to serve PDF", "invalid PDF")
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
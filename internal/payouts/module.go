@@ -0,0 +1,87 @@
+// Package payouts settles with partners: it records a commission per accepted
+// offer once the customer confirmed the work, keeps manual adjustments, and
+// bundles both into monthly payout statements per partner that are rendered
+// as PDF and exported to the accounting integration.
+package payouts
+
+import (
+	"context"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/events"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/internal/payouts/handler"
+	"portal_final_backend/internal/payouts/repository"
+	"portal_final_backend/internal/payouts/service"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	repo    *repository.Repository
+	service *service.Service
+	handler *handler.Handler
+	log     *logger.Logger
+}
+
+func NewModule(pool *pgxpool.Pool, val *validator.Validator, log *logger.Logger) *Module {
+	repo := repository.New(pool)
+	svc := service.New(repo, log)
+	return &Module{
+		repo:    repo,
+		service: svc,
+		handler: handler.New(svc, val),
+		log:     log,
+	}
+}
+
+// Service returns the payouts service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+// Repository returns the payouts repository, used to render statement PDFs.
+func (m *Module) Repository() *repository.Repository {
+	return m.repo
+}
+
+// SetPDFGenerator injects the generator of statement PDFs.
+func (m *Module) SetPDFGenerator(gen handler.StatementPDFGenerator) {
+	m.handler.SetPDFGenerator(gen)
+}
+
+// SetStorageForPDF injects the storage statement PDFs are served from.
+func (m *Module) SetStorageForPDF(svc storage.StorageService, bucket string) {
+	m.handler.SetStorageForPDF(svc, bucket)
+}
+
+func (m *Module) Name() string {
+	return "payouts"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterRoutes(ctx.Admin.Group("/payouts"))
+}
+
+// RegisterHandlers subscribes the module to system-wide events.
+func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
+	bus.Subscribe(events.WorkOrderConfirmed{}.EventName(), m)
+}
+
+// Handle processes subscribed domain events.
+func (m *Module) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.WorkOrderConfirmed:
+		if err := m.service.RecordCommission(ctx, e); err != nil {
+			m.log.Error("failed to record partner commission", "workOrderId", e.WorkOrderID, "error", err)
+			return err
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+var _ apphttp.Module = (*Module)(nil)
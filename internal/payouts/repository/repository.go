@@ -0,0 +1,552 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrStatementNotFound = errors.New("payout statement not found")
+
+const dateFormat = "2006-01-02"
+
+// statementPeriodConstraint allows one statement per partner and month.
+const statementPeriodConstraint = "ux_partner_payout_statements_period"
+
+// Repository persists partner commissions, payout adjustments and statements.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// CommissionSource is the accepted offer behind a confirmed work order, with
+// the lead fee of the price agreement that applies to it.
+type CommissionSource struct {
+	OrganizationID     uuid.UUID
+	PartnerID          uuid.UUID
+	PartnerOfferID     uuid.UUID
+	WorkOrderID        uuid.UUID
+	LeadServiceID      uuid.UUID
+	AgreementID        *uuid.UUID
+	CustomerPriceCents int64
+	VakmanPriceCents   int64
+	LeadFeeCents       int64
+}
+
+// Commission is what an accepted offer earns the organization and its partner.
+type Commission struct {
+	ID                 uuid.UUID
+	OrganizationID     uuid.UUID
+	PartnerID          uuid.UUID
+	PartnerOfferID     uuid.UUID
+	WorkOrderID        *uuid.UUID
+	LeadServiceID      uuid.UUID
+	ServiceType        *string
+	AgreementID        *uuid.UUID
+	CustomerPriceCents int64
+	VakmanPriceCents   int64
+	CommissionCents    int64
+	LeadFeeCents       int64
+	PayoutCents        int64
+	EarnedAt           time.Time
+	StatementID        *uuid.UUID
+	CreatedAt          time.Time
+}
+
+// CommissionListParams filters the commissions of an organization.
+type CommissionListParams struct {
+	OrganizationID uuid.UUID
+	PartnerID      *uuid.UUID
+	StatementID    *uuid.UUID
+	OpenOnly       bool
+	Limit          int
+	Offset         int
+}
+
+// Adjustment is a manual correction on the payout of a partner.
+type Adjustment struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	PartnerID      uuid.UUID
+	AmountCents    int64
+	Description    string
+	EffectiveDate  time.Time
+	StatementID    *uuid.UUID
+	CreatedBy      *uuid.UUID
+	CreatedAt      time.Time
+}
+
+// Statement is the monthly payout statement of a partner.
+type Statement struct {
+	ID               uuid.UUID
+	OrganizationID   uuid.UUID
+	PartnerID        uuid.UUID
+	PartnerName      string
+	PartnerEmail     string
+	PartnerKVK       string
+	PartnerVAT       string
+	PartnerAddress   string
+	PartnerZipCode   string
+	PartnerCity      string
+	StatementNumber  string
+	PeriodStart      time.Time
+	PeriodEnd        time.Time
+	EarningsCents    int64
+	LeadFeesCents    int64
+	AdjustmentsCents int64
+	PayoutCents      int64
+	PDFFileKey       *string
+	CreatedBy        *uuid.UUID
+	CreatedAt        time.Time
+}
+
+// StatementListParams filters the payout statements of an organization.
+type StatementListParams struct {
+	OrganizationID uuid.UUID
+	PartnerID      *uuid.UUID
+	PeriodStart    *time.Time
+	Limit          int
+	Offset         int
+}
+
+// StatementExport records a statement booked in an accounting integration.
+type StatementExport struct {
+	StatementID    uuid.UUID
+	OrganizationID uuid.UUID
+	Provider       string
+	ExternalID     string
+	ExternalURL    *string
+	CreatedAt      time.Time
+}
+
+// GetCommissionSource returns the accepted offer of a work order. The lead fee
+// comes from the agreement the offer was priced with, otherwise from the
+// agreement in effect for the partner and service on the given day. It
+// returns nil when the work order has no accepted offer.
+func (r *Repository) GetCommissionSource(ctx context.Context, workOrderID, organizationID uuid.UUID, on time.Time) (*CommissionSource, error) {
+	var s CommissionSource
+	err := r.pool.QueryRow(ctx, `
+		SELECT w.organization_id, o.partner_id, o.id, w.id, w.lead_service_id,
+			a.id, o.customer_price_cents, o.vakman_price_cents, COALESCE(a.lead_fee_cents, 0)
+		FROM RAC_work_orders w
+		JOIN RAC_partner_offers o ON o.id = w.partner_offer_id AND o.status = 'accepted'
+		JOIN RAC_lead_services ls ON ls.id = w.lead_service_id
+		LEFT JOIN RAC_partner_offer_price_agreements opa ON opa.offer_id = o.id
+		LEFT JOIN LATERAL (
+			SELECT pa.id, pa.lead_fee_cents
+			FROM RAC_partner_price_agreements pa
+			WHERE pa.id = opa.agreement_id
+			   OR (opa.agreement_id IS NULL
+			       AND pa.organization_id = w.organization_id AND pa.partner_id = o.partner_id
+			       AND (pa.service_type_id = ls.service_type_id OR pa.service_type_id IS NULL)
+			       AND pa.effective_from <= $3::date
+			       AND (pa.effective_to IS NULL OR pa.effective_to >= $3::date))
+			ORDER BY pa.service_type_id NULLS LAST, pa.effective_from DESC
+			LIMIT 1
+		) a ON TRUE
+		WHERE w.id = $1 AND w.organization_id = $2`,
+		workOrderID, organizationID, on,
+	).Scan(&s.OrganizationID, &s.PartnerID, &s.PartnerOfferID, &s.WorkOrderID, &s.LeadServiceID,
+		&s.AgreementID, &s.CustomerPriceCents, &s.VakmanPriceCents, &s.LeadFeeCents)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get commission source: %w", err)
+	}
+	return &s, nil
+}
+
+// CreateCommission stores the commission of an offer. The boolean is false
+// when the offer already has one, so replayed events are a no-op.
+func (r *Repository) CreateCommission(ctx context.Context, c Commission) (Commission, bool, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_partner_commissions
+			(organization_id, partner_id, partner_offer_id, work_order_id, lead_service_id, agreement_id,
+			 customer_price_cents, vakman_price_cents, commission_cents, lead_fee_cents, payout_cents, earned_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (partner_offer_id) DO NOTHING
+		RETURNING id, created_at`,
+		c.OrganizationID, c.PartnerID, c.PartnerOfferID, c.WorkOrderID, c.LeadServiceID, c.AgreementID,
+		c.CustomerPriceCents, c.VakmanPriceCents, c.CommissionCents, c.LeadFeeCents, c.PayoutCents, c.EarnedAt,
+	).Scan(&c.ID, &c.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Commission{}, false, nil
+	}
+	if err != nil {
+		return Commission{}, false, fmt.Errorf("create commission: %w", err)
+	}
+	return c, true, nil
+}
+
+// ListCommissions returns commissions, newest first, with the total count.
+func (r *Repository) ListCommissions(ctx context.Context, p CommissionListParams) ([]Commission, int, error) {
+	where := `c.organization_id = $1
+		AND ($2::uuid IS NULL OR c.partner_id = $2)
+		AND ($3::uuid IS NULL OR c.statement_id = $3)
+		AND (NOT $4 OR c.statement_id IS NULL)`
+	args := []any{p.OrganizationID, p.PartnerID, p.StatementID, p.OpenOnly}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM RAC_partner_commissions c WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count commissions: %w", err)
+	}
+
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 1000
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT c.id, c.organization_id, c.partner_id, c.partner_offer_id, c.work_order_id, c.lead_service_id, st.name,
+			c.agreement_id, c.customer_price_cents, c.vakman_price_cents, c.commission_cents, c.lead_fee_cents,
+			c.payout_cents, c.earned_at, c.statement_id, c.created_at
+		FROM RAC_partner_commissions c
+		LEFT JOIN RAC_lead_services ls ON ls.id = c.lead_service_id
+		LEFT JOIN RAC_service_types st ON st.id = ls.service_type_id
+		WHERE `+where+`
+		ORDER BY c.earned_at DESC
+		LIMIT $5 OFFSET $6`, append(args, limit, p.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list commissions: %w", err)
+	}
+	defer rows.Close()
+
+	commissions := make([]Commission, 0)
+	for rows.Next() {
+		var c Commission
+		if err := rows.Scan(&c.ID, &c.OrganizationID, &c.PartnerID, &c.PartnerOfferID, &c.WorkOrderID, &c.LeadServiceID, &c.ServiceType,
+			&c.AgreementID, &c.CustomerPriceCents, &c.VakmanPriceCents, &c.CommissionCents, &c.LeadFeeCents,
+			&c.PayoutCents, &c.EarnedAt, &c.StatementID, &c.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan commission: %w", err)
+		}
+		commissions = append(commissions, c)
+	}
+	return commissions, total, rows.Err()
+}
+
+// PartnerExists reports whether the partner belongs to the organization.
+func (r *Repository) PartnerExists(ctx context.Context, partnerID, organizationID uuid.UUID) (bool, error) {
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM RAC_partners WHERE id = $1 AND organization_id = $2)`,
+		partnerID, organizationID,
+	).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check partner: %w", err)
+	}
+	return exists, nil
+}
+
+const adjustmentColumns = `id, organization_id, partner_id, amount_cents, description, effective_date, statement_id, created_by, created_at`
+
+func scanAdjustment(row pgx.Row) (Adjustment, error) {
+	var a Adjustment
+	err := row.Scan(&a.ID, &a.OrganizationID, &a.PartnerID, &a.AmountCents, &a.Description, &a.EffectiveDate, &a.StatementID, &a.CreatedBy, &a.CreatedAt)
+	return a, err
+}
+
+// CreateAdjustment stores a manual payout correction.
+func (r *Repository) CreateAdjustment(ctx context.Context, a Adjustment) (Adjustment, error) {
+	created, err := scanAdjustment(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_partner_payout_adjustments (organization_id, partner_id, amount_cents, description, effective_date, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+adjustmentColumns,
+		a.OrganizationID, a.PartnerID, a.AmountCents, a.Description, a.EffectiveDate, a.CreatedBy))
+	if err != nil {
+		return Adjustment{}, fmt.Errorf("create payout adjustment: %w", err)
+	}
+	return created, nil
+}
+
+// ListAdjustments returns the adjustments of an organization, newest first.
+func (r *Repository) ListAdjustments(ctx context.Context, organizationID uuid.UUID, partnerID, statementID *uuid.UUID, openOnly bool) ([]Adjustment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+adjustmentColumns+` FROM RAC_partner_payout_adjustments
+		WHERE organization_id = $1
+		  AND ($2::uuid IS NULL OR partner_id = $2)
+		  AND ($3::uuid IS NULL OR statement_id = $3)
+		  AND (NOT $4 OR statement_id IS NULL)
+		ORDER BY effective_date DESC, created_at DESC`,
+		organizationID, partnerID, statementID, openOnly)
+	if err != nil {
+		return nil, fmt.Errorf("list payout adjustments: %w", err)
+	}
+	defer rows.Close()
+
+	adjustments := make([]Adjustment, 0)
+	for rows.Next() {
+		a, err := scanAdjustment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan payout adjustment: %w", err)
+		}
+		adjustments = append(adjustments, a)
+	}
+	return adjustments, rows.Err()
+}
+
+// DeleteAdjustment removes an adjustment that is not on a statement yet.
+func (r *Repository) DeleteAdjustment(ctx context.Context, id, organizationID uuid.UUID) error {
+	var onStatement bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT statement_id IS NOT NULL FROM RAC_partner_payout_adjustments
+		WHERE id = $1 AND organization_id = $2`, id, organizationID,
+	).Scan(&onStatement)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.NotFound("payout adjustment not found")
+	}
+	if err != nil {
+		return fmt.Errorf("get payout adjustment: %w", err)
+	}
+	if onStatement {
+		return apperr.Conflict("adjustment is already on a payout statement")
+	}
+
+	if _, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_partner_payout_adjustments
+		WHERE id = $1 AND organization_id = $2 AND statement_id IS NULL`, id, organizationID); err != nil {
+		return fmt.Errorf("delete payout adjustment: %w", err)
+	}
+	return nil
+}
+
+// ListPartnersWithOpenItems returns the partners with commissions or
+// adjustments up to periodEnd that are not on a statement yet.
+func (r *Repository) ListPartnersWithOpenItems(ctx context.Context, organizationID uuid.UUID, periodEnd time.Time) ([]uuid.UUID, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT partner_id FROM RAC_partner_commissions
+		WHERE organization_id = $1 AND statement_id IS NULL AND earned_at < $2::date + 1
+		UNION
+		SELECT partner_id FROM RAC_partner_payout_adjustments
+		WHERE organization_id = $1 AND statement_id IS NULL AND effective_date <= $2::date`,
+		organizationID, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("list partners with open payout items: %w", err)
+	}
+	defer rows.Close()
+
+	partnerIDs := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan partner id: %w", err)
+		}
+		partnerIDs = append(partnerIDs, id)
+	}
+	return partnerIDs, rows.Err()
+}
+
+// CreateStatementParams is the partner and month of a new statement.
+type CreateStatementParams struct {
+	OrganizationID uuid.UUID
+	PartnerID      uuid.UUID
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	CreatedBy      *uuid.UUID
+}
+
+// CreateStatement puts the open commissions and adjustments of a partner up to
+// the end of the period on a new statement and totals it. Items from before
+// the period that missed an earlier statement are included. It returns nil
+// when the partner has nothing to pay out.
+func (r *Repository) CreateStatement(ctx context.Context, p CreateStatementParams) (*Statement, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// The counter row stays locked until commit, so concurrent statements of
+	// the organization take the numbers one after the other.
+	var sequence int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO RAC_partner_payout_statement_counters (organization_id, period_start, last_number)
+		VALUES ($1, $2::date, 1)
+		ON CONFLICT (organization_id, period_start)
+		DO UPDATE SET last_number = RAC_partner_payout_statement_counters.last_number + 1
+		RETURNING last_number`, p.OrganizationID, p.PeriodStart,
+	).Scan(&sequence); err != nil {
+		return nil, fmt.Errorf("number payout statement: %w", err)
+	}
+
+	id := uuid.New()
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_partner_payout_statements (id, organization_id, partner_id, statement_number, period_start, period_end, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		id, p.OrganizationID, p.PartnerID, StatementNumber(p.PeriodStart, sequence), p.PeriodStart, p.PeriodEnd, p.CreatedBy,
+	); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			if pgErr.ConstraintName == statementPeriodConstraint {
+				return nil, apperr.Conflict("partner already has a payout statement for this month")
+			}
+			return nil, apperr.Conflict("payout statement conflicts with an existing statement")
+		}
+		return nil, fmt.Errorf("insert payout statement: %w", err)
+	}
+
+	commissions, err := tx.Exec(ctx, `
+		UPDATE RAC_partner_commissions SET statement_id = $1
+		WHERE organization_id = $2 AND partner_id = $3 AND statement_id IS NULL AND earned_at < $4::date + 1`,
+		id, p.OrganizationID, p.PartnerID, p.PeriodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("assign commissions to statement: %w", err)
+	}
+	adjustments, err := tx.Exec(ctx, `
+		UPDATE RAC_partner_payout_adjustments SET statement_id = $1
+		WHERE organization_id = $2 AND partner_id = $3 AND statement_id IS NULL AND effective_date <= $4::date`,
+		id, p.OrganizationID, p.PartnerID, p.PeriodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("assign adjustments to statement: %w", err)
+	}
+	if commissions.RowsAffected() == 0 && adjustments.RowsAffected() == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_partner_payout_statements s SET
+			earnings_cents = c.vakman, lead_fees_cents = c.lead_fees, adjustments_cents = a.amount,
+			payout_cents = c.payout + a.amount
+		FROM (
+			SELECT COALESCE(SUM(vakman_price_cents), 0) AS vakman, COALESCE(SUM(lead_fee_cents), 0) AS lead_fees,
+				COALESCE(SUM(payout_cents), 0) AS payout
+			FROM RAC_partner_commissions WHERE statement_id = $1
+		) c, (
+			SELECT COALESCE(SUM(amount_cents), 0) AS amount
+			FROM RAC_partner_payout_adjustments WHERE statement_id = $1
+		) a
+		WHERE s.id = $1`, id); err != nil {
+		return nil, fmt.Errorf("total payout statement: %w", err)
+	}
+
+	statement, err := scanStatement(tx.QueryRow(ctx, `SELECT `+statementColumns+` `+statementFrom+` WHERE s.id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("read payout statement: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit payout statement: %w", err)
+	}
+	return &statement, nil
+}
+
+// StatementNumber numbers the statements of a month, e.g. UB-202609-0003.
+func StatementNumber(periodStart time.Time, sequence int) string {
+	return fmt.Sprintf("UB-%s-%04d", periodStart.Format("200601"), sequence)
+}
+
+const statementColumns = `s.id, s.organization_id, s.partner_id, p.business_name, p.contact_email, p.kvk_number, p.vat_number,
+	p.address_line1, p.postal_code, p.city, s.statement_number, s.period_start, s.period_end,
+	s.earnings_cents, s.lead_fees_cents, s.adjustments_cents, s.payout_cents, s.pdf_file_key, s.created_by, s.created_at`
+
+const statementFrom = `FROM RAC_partner_payout_statements s
+	JOIN RAC_partners p ON p.id = s.partner_id`
+
+func scanStatement(row pgx.Row) (Statement, error) {
+	var s Statement
+	err := row.Scan(&s.ID, &s.OrganizationID, &s.PartnerID, &s.PartnerName, &s.PartnerEmail, &s.PartnerKVK, &s.PartnerVAT,
+		&s.PartnerAddress, &s.PartnerZipCode, &s.PartnerCity, &s.StatementNumber, &s.PeriodStart, &s.PeriodEnd,
+		&s.EarningsCents, &s.LeadFeesCents, &s.AdjustmentsCents, &s.PayoutCents, &s.PDFFileKey, &s.CreatedBy, &s.CreatedAt)
+	return s, err
+}
+
+// GetStatement returns a payout statement of the organization.
+func (r *Repository) GetStatement(ctx context.Context, id, organizationID uuid.UUID) (Statement, error) {
+	s, err := scanStatement(r.pool.QueryRow(ctx, `SELECT `+statementColumns+` `+statementFrom+`
+		WHERE s.id = $1 AND s.organization_id = $2`, id, organizationID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Statement{}, ErrStatementNotFound
+	}
+	if err != nil {
+		return Statement{}, fmt.Errorf("get payout statement: %w", err)
+	}
+	return s, nil
+}
+
+// ListStatements returns payout statements, newest period first, with the
+// total count.
+func (r *Repository) ListStatements(ctx context.Context, p StatementListParams) ([]Statement, int, error) {
+	var periodStart *string
+	if p.PeriodStart != nil {
+		formatted := p.PeriodStart.Format(dateFormat)
+		periodStart = &formatted
+	}
+	where := `s.organization_id = $1
+		AND ($2::uuid IS NULL OR s.partner_id = $2)
+		AND ($3::date IS NULL OR s.period_start = $3::date)`
+	args := []any{p.OrganizationID, p.PartnerID, periodStart}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM RAC_partner_payout_statements s WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count payout statements: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT `+statementColumns+` `+statementFrom+`
+		WHERE `+where+`
+		ORDER BY s.period_start DESC, p.business_name
+		LIMIT $4 OFFSET $5`, append(args, p.Limit, p.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list payout statements: %w", err)
+	}
+	defer rows.Close()
+
+	statements := make([]Statement, 0)
+	for rows.Next() {
+		s, err := scanStatement(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan payout statement: %w", err)
+		}
+		statements = append(statements, s)
+	}
+	return statements, total, rows.Err()
+}
+
+// SetStatementPDFFileKey stores the object key of the generated statement PDF.
+func (r *Repository) SetStatementPDFFileKey(ctx context.Context, id, organizationID uuid.UUID, fileKey string) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_partner_payout_statements SET pdf_file_key = $3
+		WHERE id = $1 AND organization_id = $2`, id, organizationID, fileKey); err != nil {
+		return fmt.Errorf("set payout statement pdf: %w", err)
+	}
+	return nil
+}
+
+// GetStatementExport returns the export of a statement to a provider, or nil.
+func (r *Repository) GetStatementExport(ctx context.Context, statementID, organizationID uuid.UUID, provider string) (*StatementExport, error) {
+	var e StatementExport
+	err := r.pool.QueryRow(ctx, `
+		SELECT statement_id, organization_id, provider, external_id, external_url, created_at
+		FROM RAC_partner_payout_statement_exports
+		WHERE statement_id = $1 AND organization_id = $2 AND provider = $3`,
+		statementID, organizationID, provider,
+	).Scan(&e.StatementID, &e.OrganizationID, &e.Provider, &e.ExternalID, &e.ExternalURL, &e.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get payout statement export: %w", err)
+	}
+	return &e, nil
+}
+
+// CreateStatementExport records a statement booked in a provider.
+func (r *Repository) CreateStatementExport(ctx context.Context, e StatementExport) (StatementExport, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_partner_payout_statement_exports (statement_id, organization_id, provider, external_id, external_url)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`,
+		e.StatementID, e.OrganizationID, e.Provider, e.ExternalID, e.ExternalURL,
+	).Scan(&e.CreatedAt)
+	if err != nil {
+		return StatementExport{}, fmt.Errorf("create payout statement export: %w", err)
+	}
+	return e, nil
+}
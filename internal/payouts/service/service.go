@@ -0,0 +1,485 @@
+// Package service calculates what partners earn and owe: a commission per
+// accepted offer once the customer confirmed the work, manual adjustments,
+// and monthly payout statements that are rendered as PDF and booked in the
+// accounting integration.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/payouts/repository"
+	"portal_final_backend/internal/payouts/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	dateFormat           = "2006-01-02"
+	monthFormat          = "2006-01"
+	defaultPageSize      = 25
+	msgStatementNotFound = "payout statement not found"
+	msgPartnerNotFound   = "partner not found"
+)
+
+type Repository interface {
+	GetCommissionSource(ctx context.Context, workOrderID, organizationID uuid.UUID, on time.Time) (*repository.CommissionSource, error)
+	CreateCommission(ctx context.Context, c repository.Commission) (repository.Commission, bool, error)
+	ListCommissions(ctx context.Context, p repository.CommissionListParams) ([]repository.Commission, int, error)
+	PartnerExists(ctx context.Context, partnerID, organizationID uuid.UUID) (bool, error)
+	CreateAdjustment(ctx context.Context, a repository.Adjustment) (repository.Adjustment, error)
+	ListAdjustments(ctx context.Context, organizationID uuid.UUID, partnerID, statementID *uuid.UUID, openOnly bool) ([]repository.Adjustment, error)
+	DeleteAdjustment(ctx context.Context, id, organizationID uuid.UUID) error
+	ListPartnersWithOpenItems(ctx context.Context, organizationID uuid.UUID, periodEnd time.Time) ([]uuid.UUID, error)
+	CreateStatement(ctx context.Context, p repository.CreateStatementParams) (*repository.Statement, error)
+	GetStatement(ctx context.Context, id, organizationID uuid.UUID) (repository.Statement, error)
+	ListStatements(ctx context.Context, p repository.StatementListParams) ([]repository.Statement, int, error)
+	GetStatementExport(ctx context.Context, statementID, organizationID uuid.UUID, provider string) (*repository.StatementExport, error)
+	CreateStatementExport(ctx context.Context, e repository.StatementExport) (repository.StatementExport, error)
+}
+
+// ExportLine is a line of a statement booked in the accounting integration.
+type ExportLine struct {
+	Description string
+	AmountCents int64
+}
+
+// AccountingExporter books a payout statement as a purchase invoice of the
+// partner in the accounting integration of the organization.
+type AccountingExporter interface {
+	ExportPayoutStatement(ctx context.Context, organizationID uuid.UUID, provider string, statement repository.Statement, lines []ExportLine) (externalID, externalURL string, err error)
+}
+
+type Service struct {
+	repo     Repository
+	exporter AccountingExporter
+	log      *logger.Logger
+}
+
+func New(repo Repository, log *logger.Logger) *Service {
+	return &Service{repo: repo, log: log}
+}
+
+// SetAccountingExporter injects the accounting integration statements are
+// exported to.
+func (s *Service) SetAccountingExporter(exporter AccountingExporter) {
+	s.exporter = exporter
+}
+
+// RecordCommission calculates the commission of the accepted offer behind a
+// work order confirmed by the customer. Work orders without an accepted offer
+// and replayed events are ignored.
+func (s *Service) RecordCommission(ctx context.Context, e events.WorkOrderConfirmed) error {
+	earnedAt := e.OccurredAt()
+	if earnedAt.IsZero() {
+		earnedAt = time.Now()
+	}
+	source, err := s.repo.GetCommissionSource(ctx, e.WorkOrderID, e.OrganizationID, earnedAt)
+	if err != nil || source == nil {
+		return err
+	}
+
+	_, created, err := s.repo.CreateCommission(ctx, calculateCommission(*source, earnedAt))
+	if err != nil {
+		return err
+	}
+	if created {
+		s.log.Info("partner commission recorded", "offerId", source.PartnerOfferID, "partnerId", source.PartnerID)
+	}
+	return nil
+}
+
+// calculateCommission splits the customer price of an offer into what the
+// organization keeps and what the partner is paid after its lead fee.
+func calculateCommission(source repository.CommissionSource, earnedAt time.Time) repository.Commission {
+	return repository.Commission{
+		OrganizationID:     source.OrganizationID,
+		PartnerID:          source.PartnerID,
+		PartnerOfferID:     source.PartnerOfferID,
+		WorkOrderID:        &source.WorkOrderID,
+		LeadServiceID:      source.LeadServiceID,
+		AgreementID:        source.AgreementID,
+		CustomerPriceCents: source.CustomerPriceCents,
+		VakmanPriceCents:   source.VakmanPriceCents,
+		CommissionCents:    source.CustomerPriceCents - source.VakmanPriceCents,
+		LeadFeeCents:       source.LeadFeeCents,
+		PayoutCents:        source.VakmanPriceCents - source.LeadFeeCents,
+		EarnedAt:           earnedAt,
+	}
+}
+
+func (s *Service) ListCommissions(ctx context.Context, organizationID uuid.UUID, req transport.ListCommissionsRequest) (transport.CommissionListResponse, error) {
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	partnerID, err := parseOptionalID(req.PartnerID, "partnerId")
+	if err != nil {
+		return transport.CommissionListResponse{}, err
+	}
+
+	items, total, err := s.repo.ListCommissions(ctx, repository.CommissionListParams{
+		OrganizationID: organizationID,
+		PartnerID:      partnerID,
+		OpenOnly:       req.OpenOnly,
+		Limit:          pageSize,
+		Offset:         (page - 1) * pageSize,
+	})
+	if err != nil {
+		return transport.CommissionListResponse{}, err
+	}
+	return transport.CommissionListResponse{Items: toCommissionResponses(items), Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// CreateAdjustment records a manual correction on the payout of a partner.
+func (s *Service) CreateAdjustment(ctx context.Context, organizationID, userID uuid.UUID, req transport.CreateAdjustmentRequest) (transport.AdjustmentResponse, error) {
+	if err := s.requirePartner(ctx, req.PartnerID, organizationID); err != nil {
+		return transport.AdjustmentResponse{}, err
+	}
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return transport.AdjustmentResponse{}, apperr.Validation("description is required")
+	}
+	effectiveDate, err := time.Parse(dateFormat, req.EffectiveDate)
+	if err != nil {
+		return transport.AdjustmentResponse{}, apperr.Validation("invalid effectiveDate")
+	}
+
+	a, err := s.repo.CreateAdjustment(ctx, repository.Adjustment{
+		OrganizationID: organizationID,
+		PartnerID:      req.PartnerID,
+		AmountCents:    req.AmountCents,
+		Description:    description,
+		EffectiveDate:  effectiveDate,
+		CreatedBy:      &userID,
+	})
+	if err != nil {
+		return transport.AdjustmentResponse{}, err
+	}
+	return toAdjustmentResponse(a), nil
+}
+
+func (s *Service) ListAdjustments(ctx context.Context, organizationID uuid.UUID, req transport.ListAdjustmentsRequest) (transport.AdjustmentListResponse, error) {
+	partnerID, err := parseOptionalID(req.PartnerID, "partnerId")
+	if err != nil {
+		return transport.AdjustmentListResponse{}, err
+	}
+	items, err := s.repo.ListAdjustments(ctx, organizationID, partnerID, nil, req.OpenOnly)
+	if err != nil {
+		return transport.AdjustmentListResponse{}, err
+	}
+	return transport.AdjustmentListResponse{Items: toAdjustmentResponses(items)}, nil
+}
+
+// DeleteAdjustment removes an adjustment that is not on a statement yet.
+func (s *Service) DeleteAdjustment(ctx context.Context, organizationID, id uuid.UUID) error {
+	return s.repo.DeleteAdjustment(ctx, id, organizationID)
+}
+
+// GenerateStatements creates the payout statements of a month that has
+// ended. Without a partner, every partner with open commissions or
+// adjustments gets one; partners that already have a statement for the month
+// are skipped.
+func (s *Service) GenerateStatements(ctx context.Context, organizationID, userID uuid.UUID, req transport.GenerateStatementsRequest) (transport.GenerateStatementsResponse, error) {
+	start, end, err := monthPeriod(req.Month, time.Now())
+	if err != nil {
+		return transport.GenerateStatementsResponse{}, err
+	}
+
+	var partnerIDs []uuid.UUID
+	if req.PartnerID != nil {
+		if err := s.requirePartner(ctx, *req.PartnerID, organizationID); err != nil {
+			return transport.GenerateStatementsResponse{}, err
+		}
+		partnerIDs = []uuid.UUID{*req.PartnerID}
+	} else {
+		partnerIDs, err = s.repo.ListPartnersWithOpenItems(ctx, organizationID, end)
+		if err != nil {
+			return transport.GenerateStatementsResponse{}, err
+		}
+	}
+
+	items := make([]transport.StatementResponse, 0, len(partnerIDs))
+	for _, partnerID := range partnerIDs {
+		statement, err := s.repo.CreateStatement(ctx, repository.CreateStatementParams{
+			OrganizationID: organizationID,
+			PartnerID:      partnerID,
+			PeriodStart:    start,
+			PeriodEnd:      end,
+			CreatedBy:      &userID,
+		})
+		var appErr *apperr.Error
+		if req.PartnerID == nil && errors.As(err, &appErr) && appErr.Kind == apperr.KindConflict {
+			continue
+		}
+		if err != nil {
+			return transport.GenerateStatementsResponse{}, err
+		}
+		if statement != nil {
+			items = append(items, toStatementResponse(*statement))
+		}
+	}
+	return transport.GenerateStatementsResponse{Items: items}, nil
+}
+
+func (s *Service) ListStatements(ctx context.Context, organizationID uuid.UUID, req transport.ListStatementsRequest) (transport.StatementListResponse, error) {
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	partnerID, err := parseOptionalID(req.PartnerID, "partnerId")
+	if err != nil {
+		return transport.StatementListResponse{}, err
+	}
+	params := repository.StatementListParams{
+		OrganizationID: organizationID,
+		PartnerID:      partnerID,
+		Limit:          pageSize,
+		Offset:         (page - 1) * pageSize,
+	}
+	if req.Month != "" {
+		month, err := time.Parse(monthFormat, req.Month)
+		if err != nil {
+			return transport.StatementListResponse{}, apperr.Validation("invalid month")
+		}
+		params.PeriodStart = &month
+	}
+
+	items, total, err := s.repo.ListStatements(ctx, params)
+	if err != nil {
+		return transport.StatementListResponse{}, err
+	}
+	resp := transport.StatementListResponse{Items: make([]transport.StatementResponse, len(items)), Total: total, Page: page, PageSize: pageSize}
+	for i, statement := range items {
+		resp.Items[i] = toStatementResponse(statement)
+	}
+	return resp, nil
+}
+
+// GetStatement returns a statement with the commissions and adjustments on it.
+func (s *Service) GetStatement(ctx context.Context, organizationID, id uuid.UUID) (transport.StatementDetailResponse, error) {
+	statement, commissions, adjustments, err := s.loadStatement(ctx, organizationID, id)
+	if err != nil {
+		return transport.StatementDetailResponse{}, err
+	}
+	return transport.StatementDetailResponse{
+		StatementResponse: toStatementResponse(statement),
+		Commissions:       toCommissionResponses(commissions),
+		Adjustments:       toAdjustmentResponses(adjustments),
+	}, nil
+}
+
+// GetStatementPDFFileKey returns the number of a statement and the storage key
+// of its PDF, which is nil until the PDF was generated.
+func (s *Service) GetStatementPDFFileKey(ctx context.Context, organizationID, id uuid.UUID) (string, *string, error) {
+	statement, err := s.repo.GetStatement(ctx, id, organizationID)
+	if err != nil {
+		return "", nil, mapNotFound(err)
+	}
+	return statement.StatementNumber, statement.PDFFileKey, nil
+}
+
+// ExportStatement books a statement in the accounting integration. A
+// statement is exported once per provider; exporting again returns the
+// existing booking.
+func (s *Service) ExportStatement(ctx context.Context, organizationID, id uuid.UUID, provider string) (transport.StatementExportResponse, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider != "moneybird" {
+		return transport.StatementExportResponse{}, apperr.BadRequest("provider not yet implemented")
+	}
+	if s.exporter == nil {
+		return transport.StatementExportResponse{}, apperr.BadRequest("accounting integration is not configured")
+	}
+
+	statement, commissions, adjustments, err := s.loadStatement(ctx, organizationID, id)
+	if err != nil {
+		return transport.StatementExportResponse{}, err
+	}
+	existing, err := s.repo.GetStatementExport(ctx, id, organizationID, provider)
+	if err != nil {
+		return transport.StatementExportResponse{}, err
+	}
+	if existing == nil {
+		externalID, externalURL, err := s.exporter.ExportPayoutStatement(ctx, organizationID, provider, statement, statementExportLines(commissions, adjustments))
+		if err != nil {
+			return transport.StatementExportResponse{}, err
+		}
+		created, err := s.repo.CreateStatementExport(ctx, repository.StatementExport{
+			StatementID:    id,
+			OrganizationID: organizationID,
+			Provider:       provider,
+			ExternalID:     externalID,
+			ExternalURL:    optionalString(externalURL),
+		})
+		if err != nil {
+			return transport.StatementExportResponse{}, err
+		}
+		existing = &created
+	}
+
+	resp := transport.StatementExportResponse{StatementID: id, Provider: provider, ExternalID: existing.ExternalID, ExportedAt: existing.CreatedAt}
+	if existing.ExternalURL != nil {
+		resp.ExternalURL = *existing.ExternalURL
+	}
+	return resp, nil
+}
+
+func (s *Service) loadStatement(ctx context.Context, organizationID, id uuid.UUID) (repository.Statement, []repository.Commission, []repository.Adjustment, error) {
+	statement, err := s.repo.GetStatement(ctx, id, organizationID)
+	if err != nil {
+		return repository.Statement{}, nil, nil, mapNotFound(err)
+	}
+	commissions, _, err := s.repo.ListCommissions(ctx, repository.CommissionListParams{OrganizationID: organizationID, StatementID: &id})
+	if err != nil {
+		return repository.Statement{}, nil, nil, err
+	}
+	adjustments, err := s.repo.ListAdjustments(ctx, organizationID, nil, &id, false)
+	if err != nil {
+		return repository.Statement{}, nil, nil, err
+	}
+	return statement, commissions, adjustments, nil
+}
+
+// statementExportLines books the earnings of every job, the lead fees charged
+// on them and the adjustments as separate lines, so the booked total equals
+// the payout of the statement.
+func statementExportLines(commissions []repository.Commission, adjustments []repository.Adjustment) []ExportLine {
+	lines := make([]ExportLine, 0, len(commissions)*2+len(adjustments))
+	for _, c := range commissions {
+		label := "Opdracht"
+		if c.ServiceType != nil && *c.ServiceType != "" {
+			label = *c.ServiceType
+		}
+		lines = append(lines, ExportLine{
+			Description: fmt.Sprintf("%s (%s)", label, c.EarnedAt.Format("02-01-2006")),
+			AmountCents: c.VakmanPriceCents,
+		})
+		if c.LeadFeeCents != 0 {
+			lines = append(lines, ExportLine{Description: fmt.Sprintf("Leadvergoeding %s", strings.ToLower(label)), AmountCents: -c.LeadFeeCents})
+		}
+	}
+	for _, a := range adjustments {
+		lines = append(lines, ExportLine{Description: a.Description, AmountCents: a.AmountCents})
+	}
+	return lines
+}
+
+// monthPeriod returns the first and last day of a month formatted as YYYY-MM.
+// Statements are only generated for months that have ended.
+func monthPeriod(month string, now time.Time) (time.Time, time.Time, error) {
+	start, err := time.Parse(monthFormat, month)
+	if err != nil {
+		return time.Time{}, time.Time{}, apperr.Validation("invalid month")
+	}
+	end := start.AddDate(0, 1, -1)
+	today, _ := time.Parse(dateFormat, now.Format(dateFormat))
+	if !end.Before(today) {
+		return time.Time{}, time.Time{}, apperr.Validation("statements can only be generated for months that have ended")
+	}
+	return start, end, nil
+}
+
+func (s *Service) requirePartner(ctx context.Context, partnerID, organizationID uuid.UUID) error {
+	exists, err := s.repo.PartnerExists(ctx, partnerID, organizationID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperr.NotFound(msgPartnerNotFound)
+	}
+	return nil
+}
+
+func parseOptionalID(raw, field string) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, apperr.Validation("invalid " + field)
+	}
+	return &id, nil
+}
+
+func mapNotFound(err error) error {
+	if errors.Is(err, repository.ErrStatementNotFound) {
+		return apperr.NotFound(msgStatementNotFound)
+	}
+	return err
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func toCommissionResponses(items []repository.Commission) []transport.CommissionResponse {
+	resp := make([]transport.CommissionResponse, len(items))
+	for i, c := range items {
+		resp[i] = transport.CommissionResponse{
+			ID:                 c.ID,
+			PartnerID:          c.PartnerID,
+			PartnerOfferID:     c.PartnerOfferID,
+			WorkOrderID:        c.WorkOrderID,
+			LeadServiceID:      c.LeadServiceID,
+			ServiceType:        c.ServiceType,
+			AgreementID:        c.AgreementID,
+			CustomerPriceCents: c.CustomerPriceCents,
+			VakmanPriceCents:   c.VakmanPriceCents,
+			CommissionCents:    c.CommissionCents,
+			LeadFeeCents:       c.LeadFeeCents,
+			PayoutCents:        c.PayoutCents,
+			EarnedAt:           c.EarnedAt,
+			StatementID:        c.StatementID,
+		}
+	}
+	return resp
+}
+
+func toAdjustmentResponse(a repository.Adjustment) transport.AdjustmentResponse {
+	return transport.AdjustmentResponse{
+		ID:            a.ID,
+		PartnerID:     a.PartnerID,
+		AmountCents:   a.AmountCents,
+		Description:   a.Description,
+		EffectiveDate: a.EffectiveDate.Format(dateFormat),
+		StatementID:   a.StatementID,
+		CreatedBy:     a.CreatedBy,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+func toAdjustmentResponses(items []repository.Adjustment) []transport.AdjustmentResponse {
+	resp := make([]transport.AdjustmentResponse, len(items))
+	for i, a := range items {
+		resp[i] = toAdjustmentResponse(a)
+	}
+	return resp
+}
+
+func toStatementResponse(s repository.Statement) transport.StatementResponse {
+	return transport.StatementResponse{
+		ID:               s.ID,
+		PartnerID:        s.PartnerID,
+		PartnerName:      s.PartnerName,
+		StatementNumber:  s.StatementNumber,
+		PeriodStart:      s.PeriodStart.Format(dateFormat),
+		PeriodEnd:        s.PeriodEnd.Format(dateFormat),
+		EarningsCents:    s.EarningsCents,
+		LeadFeesCents:    s.LeadFeesCents,
+		AdjustmentsCents: s.AdjustmentsCents,
+		PayoutCents:      s.PayoutCents,
+		HasPDF:           s.PDFFileKey != nil && *s.PDFFileKey != "",
+		CreatedAt:        s.CreatedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/payouts/repository"
+)
+
+func TestCalculateCommission(t *testing.T) {
+	c := calculateCommission(repository.CommissionSource{
+		CustomerPriceCents: 250000,
+		VakmanPriceCents:   180000,
+		LeadFeeCents:       5000,
+	}, time.Now())
+
+	if c.CommissionCents != 70000 {
+		t.Errorf("commission = %d, want 70000", c.CommissionCents)
+	}
+	if c.PayoutCents != 175000 {
+		t.Errorf("payout = %d, want 175000", c.PayoutCents)
+	}
+}
+
+func TestStatementExportLinesAddUpToPayout(t *testing.T) {
+	roofing := "Dakwerk"
+	commissions := []repository.Commission{
+		{ServiceType: &roofing, VakmanPriceCents: 180000, LeadFeeCents: 5000, PayoutCents: 175000},
+		{VakmanPriceCents: 40000, PayoutCents: 40000},
+	}
+	adjustments := []repository.Adjustment{{Description: "Huur steiger", AmountCents: -12500}}
+
+	lines := statementExportLines(commissions, adjustments)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	var total int64
+	for _, line := range lines {
+		total += line.AmountCents
+	}
+	if want := int64(175000 + 40000 - 12500); total != want {
+		t.Errorf("lines total %d, want %d", total, want)
+	}
+}
+
+func TestMonthPeriod(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+
+	start, end, err := monthPeriod("2026-02", now)
+	if err != nil {
+		t.Fatalf("monthPeriod() error = %v", err)
+	}
+	if start.Format(dateFormat) != "2026-02-01" || end.Format(dateFormat) != "2026-02-28" {
+		t.Errorf("monthPeriod() = %s..%s", start.Format(dateFormat), end.Format(dateFormat))
+	}
+	if _, _, err := monthPeriod("2026-10", now); err == nil {
+		t.Error("expected the current month to be rejected")
+	}
+	if _, _, err := monthPeriod("10-2026", now); err == nil {
+		t.Error("expected an invalid month to be rejected")
+	}
+}
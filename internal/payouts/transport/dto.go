@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListCommissionsRequest struct {
+	PartnerID string `form:"partnerId" validate:"omitempty,uuid"`
+	OpenOnly  bool   `form:"openOnly"`
+	Page      int    `form:"page" validate:"omitempty,min=1"`
+	PageSize  int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type CommissionResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	PartnerID          uuid.UUID  `json:"partnerId"`
+	PartnerOfferID     uuid.UUID  `json:"partnerOfferId"`
+	WorkOrderID        *uuid.UUID `json:"workOrderId,omitempty"`
+	LeadServiceID      uuid.UUID  `json:"leadServiceId"`
+	ServiceType        *string    `json:"serviceType,omitempty"`
+	AgreementID        *uuid.UUID `json:"agreementId,omitempty"`
+	CustomerPriceCents int64      `json:"customerPriceCents"`
+	VakmanPriceCents   int64      `json:"vakmanPriceCents"`
+	CommissionCents    int64      `json:"commissionCents"`
+	LeadFeeCents       int64      `json:"leadFeeCents"`
+	PayoutCents        int64      `json:"payoutCents"`
+	EarnedAt           time.Time  `json:"earnedAt"`
+	StatementID        *uuid.UUID `json:"statementId,omitempty"`
+}
+
+type CommissionListResponse struct {
+	Items    []CommissionResponse `json:"items"`
+	Total    int                  `json:"total"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"pageSize"`
+}
+
+// CreateAdjustmentRequest corrects the payout of a partner. Positive amounts
+// are paid out, negative amounts withheld. The adjustment goes on the
+// statement of the month of EffectiveDate, or the next one generated.
+type CreateAdjustmentRequest struct {
+	PartnerID     uuid.UUID `json:"partnerId" validate:"required"`
+	AmountCents   int64     `json:"amountCents" validate:"required"`
+	Description   string    `json:"description" validate:"required,max=500"`
+	EffectiveDate string    `json:"effectiveDate" validate:"required,datetime=2006-01-02"`
+}
+
+type ListAdjustmentsRequest struct {
+	PartnerID string `form:"partnerId" validate:"omitempty,uuid"`
+	OpenOnly  bool   `form:"openOnly"`
+}
+
+type AdjustmentResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	PartnerID     uuid.UUID  `json:"partnerId"`
+	AmountCents   int64      `json:"amountCents"`
+	Description   string     `json:"description"`
+	EffectiveDate string     `json:"effectiveDate"`
+	StatementID   *uuid.UUID `json:"statementId,omitempty"`
+	CreatedBy     *uuid.UUID `json:"createdBy,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+type AdjustmentListResponse struct {
+	Items []AdjustmentResponse `json:"items"`
+}
+
+// GenerateStatementsRequest generates the statements of a month, formatted as
+// YYYY-MM, for one partner or for every partner with open items.
+type GenerateStatementsRequest struct {
+	Month     string     `json:"month" validate:"required,datetime=2006-01"`
+	PartnerID *uuid.UUID `json:"partnerId,omitempty"`
+}
+
+type ListStatementsRequest struct {
+	PartnerID string `form:"partnerId" validate:"omitempty,uuid"`
+	Month     string `form:"month" validate:"omitempty,datetime=2006-01"`
+	Page      int    `form:"page" validate:"omitempty,min=1"`
+	PageSize  int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type StatementResponse struct {
+	ID               uuid.UUID `json:"id"`
+	PartnerID        uuid.UUID `json:"partnerId"`
+	PartnerName      string    `json:"partnerName"`
+	StatementNumber  string    `json:"statementNumber"`
+	PeriodStart      string    `json:"periodStart"`
+	PeriodEnd        string    `json:"periodEnd"`
+	EarningsCents    int64     `json:"earningsCents"`
+	LeadFeesCents    int64     `json:"leadFeesCents"`
+	AdjustmentsCents int64     `json:"adjustmentsCents"`
+	PayoutCents      int64     `json:"payoutCents"`
+	HasPDF           bool      `json:"hasPdf"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// StatementDetailResponse is a statement with the commissions and adjustments
+// on it.
+type StatementDetailResponse struct {
+	StatementResponse
+	Commissions []CommissionResponse `json:"commissions"`
+	Adjustments []AdjustmentResponse `json:"adjustments"`
+}
+
+type StatementListResponse struct {
+	Items    []StatementResponse `json:"items"`
+	Total    int                 `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+}
+
+type GenerateStatementsResponse struct {
+	Items []StatementResponse `json:"items"`
+}
+
+type StatementExportResponse struct {
+	StatementID uuid.UUID `json:"statementId"`
+	Provider    string    `json:"provider"`
+	ExternalID  string    `json:"externalId"`
+	ExternalURL string    `json:"externalUrl,omitempty"`
+	ExportedAt  time.Time `json:"exportedAt"`
+}
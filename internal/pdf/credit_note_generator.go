@@ -0,0 +1,146 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// CreditNotePDFData holds all data needed to generate a credit note PDF.
+type CreditNotePDFData struct {
+	CreditNoteNumber string
+	QuoteNumber      string
+	Reason           string
+	CreatedAt        time.Time
+
+	// Organization issuing the credit note
+	OrganizationName string
+	OrgEmail         string
+	OrgPhone         string
+	OrgVatNumber     string
+	OrgKvkNumber     string
+	OrgAddressLine1  string
+	OrgPostalCode    string
+	OrgCity          string
+	OrgLogo          []byte
+
+	// Customer of the original quote
+	CustomerName         string
+	CustomerAddressLine1 string
+	CustomerPostalCode   string
+	CustomerCity         string
+
+	// Negative amounts, as booked
+	Items         []CreditNoteLinePDF
+	SubtotalCents int64
+	TaxTotalCents int64
+	TotalCents    int64
+}
+
+// CreditNoteLinePDF is the per-line view for the credit note PDF.
+type CreditNoteLinePDF struct {
+	Description    string
+	Quantity       string
+	UnitPriceCents int64
+	TaxRateBps     int
+	LineTotalCents int64
+}
+
+// GenerateCreditNotePDF produces the PDF of a credit note referencing its quote.
+func GenerateCreditNotePDF(data CreditNotePDFData) ([]byte, error) {
+	if gotenbergClient == nil {
+		return nil, fmt.Errorf("gotenberg client not initialized — call pdf.Init first")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	htmlContent, err := renderTemplate("templates/credit_note.html", buildCreditNoteVM(data))
+	if err != nil {
+		return nil, fmt.Errorf("render credit note template: %w", err)
+	}
+
+	pdfBytes, err := gotenbergClient.ConvertHTML(ctx, htmlContent, DefaultContentOpts())
+	if err != nil {
+		return nil, fmt.Errorf("convert credit note to PDF: %w", err)
+	}
+
+	return pdfBytes, nil
+}
+
+// ── View models ──────────────────────────────────────────────────────────────
+
+type creditNoteViewModel struct {
+	LogoBase64           string
+	LogoMimeType         string
+	OrganizationName     string
+	OrgEmail             string
+	OrgPhone             string
+	OrgVatNumber         string
+	OrgKvkNumber         string
+	OrgAddressLine1      string
+	OrgPostalCode        string
+	OrgCity              string
+	CustomerName         string
+	CustomerAddressLine1 string
+	CustomerPostalCode   string
+	CustomerCity         string
+	CreditNoteNumber     string
+	QuoteNumber          string
+	Reason               string
+	CreatedAtFormatted   string
+	Items                []creditNoteItemViewModel
+	SubtotalFormatted    string
+	TaxTotalFormatted    string
+	TotalFormatted       string
+}
+
+type creditNoteItemViewModel struct {
+	Description        template.HTML
+	Quantity           string
+	UnitPriceFormatted string
+	TaxRateFormatted   string
+	LineTotalFormatted string
+}
+
+func buildCreditNoteVM(data CreditNotePDFData) creditNoteViewModel {
+	logoB64, logoMime := encodeLogoBase64(data.OrgLogo)
+
+	items := make([]creditNoteItemViewModel, len(data.Items))
+	for i, item := range data.Items {
+		items[i] = creditNoteItemViewModel{
+			Description:        formatDescriptionHTML(item.Description),
+			Quantity:           normalizePDFQuantity(item.Quantity),
+			UnitPriceFormatted: formatCurrency(item.UnitPriceCents),
+			TaxRateFormatted:   fmt.Sprintf("%g%%", float64(item.TaxRateBps)/100),
+			LineTotalFormatted: formatCurrency(item.LineTotalCents),
+		}
+	}
+
+	return creditNoteViewModel{
+		LogoBase64:           logoB64,
+		LogoMimeType:         logoMime,
+		OrganizationName:     data.OrganizationName,
+		OrgEmail:             data.OrgEmail,
+		OrgPhone:             data.OrgPhone,
+		OrgVatNumber:         data.OrgVatNumber,
+		OrgKvkNumber:         data.OrgKvkNumber,
+		OrgAddressLine1:      data.OrgAddressLine1,
+		OrgPostalCode:        data.OrgPostalCode,
+		OrgCity:              data.OrgCity,
+		CustomerName:         data.CustomerName,
+		CustomerAddressLine1: data.CustomerAddressLine1,
+		CustomerPostalCode:   data.CustomerPostalCode,
+		CustomerCity:         data.CustomerCity,
+		CreditNoteNumber:     data.CreditNoteNumber,
+		QuoteNumber:          data.QuoteNumber,
+		Reason:               strings.TrimSpace(data.Reason),
+		CreatedAtFormatted:   data.CreatedAt.Format(dateFormatDMY),
+		Items:                items,
+		SubtotalFormatted:    formatCurrency(data.SubtotalCents),
+		TaxTotalFormatted:    formatCurrency(data.TaxTotalCents),
+		TotalFormatted:       formatCurrency(data.TotalCents),
+	}
+}
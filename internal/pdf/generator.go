@@ -70,6 +70,10 @@ type QuotePDFData struct {
 	OrgCountry       string
 	OrgLogo          []byte // raw image bytes (PNG or JPEG)
 
+	// Branding colors as #RRGGBB; empty uses the default charcoal and gold.
+	PrimaryColor string
+	AccentColor  string
+
 	// Customer
 	CustomerName         string
 	CustomerEmail        string
@@ -103,6 +107,21 @@ type QuotePDFData struct {
 
 	// URLs for the signature/acceptance page (terms & conditions links).
 	URLs []QuoteURLEntry
+
+	// Payment schedule printed below the totals; empty when paid in one go.
+	PaymentSchedule []transport.PaymentInstallmentResponse
+
+	// Terms & conditions version printed after the quote; empty when the
+	// organization keeps no terms library.
+	Terms *QuoteTermsEntry
+}
+
+// QuoteTermsEntry is the terms & conditions version a quote was sent with.
+type QuoteTermsEntry struct {
+	Title         string
+	Version       int
+	EffectiveFrom time.Time
+	Content       string
 }
 
 // AttachmentPDFEntry holds a pre-downloaded PDF to be appended to the quote document.
@@ -119,7 +138,19 @@ type QuoteURLEntry struct {
 
 // ── Template view models ────────────────────────────────────────────────
 
+const (
+	defaultPrimaryColor = "#1C1917" // Charcoal
+	defaultAccentColor  = "#C5A065" // Gold
+)
+
+// brandViewModel holds the colors the templates use for text, rules and accents.
+type brandViewModel struct {
+	Primary string
+	Accent  string
+}
+
 type coverViewModel struct {
+	Brand                brandViewModel
 	LogoBase64           string
 	LogoMimeType         string
 	OrganizationName     string
@@ -142,6 +173,7 @@ type coverViewModel struct {
 }
 
 type quoteViewModel struct {
+	Brand                brandViewModel
 	LogoBase64           string
 	LogoMimeType         string
 	OrganizationName     string
@@ -178,6 +210,15 @@ type quoteViewModel struct {
 	PaymentDays          int
 	QuoteValidDays       int
 	PagePerItem          bool
+	PaymentSchedule      []installmentViewModel
+	Terms                *termsViewModel
+}
+
+type termsViewModel struct {
+	Title                  string
+	Version                int
+	EffectiveFromFormatted string
+	Content                template.HTML
 }
 
 type itemViewModel struct {
@@ -193,16 +234,25 @@ type itemViewModel struct {
 	HasTitle           bool
 }
 
+type installmentViewModel struct {
+	Label           string
+	PctFormatted    string
+	AmountFormatted string
+	DueLabel        string
+}
+
 type vatLineViewModel struct {
 	PctFormatted    string
 	AmountFormatted string
 }
 
 type footerViewModel struct {
+	Brand      brandViewModel
 	FooterText string
 }
 
 type signatureViewModel struct {
+	Brand               brandViewModel
 	LogoBase64          string
 	LogoMimeType        string
 	OrganizationName    string
@@ -357,6 +407,7 @@ func addSignaturePageIfNeeded(mergeMap map[string][]byte, data QuotePDFData, log
 
 func buildCoverVM(data QuotePDFData, logoB64, logoMime string) coverViewModel {
 	vm := coverViewModel{
+		Brand:                buildBrandVM(data),
 		LogoBase64:           logoB64,
 		LogoMimeType:         logoMime,
 		OrganizationName:     clampPDFText(data.OrganizationName, maxPDFShortText),
@@ -384,6 +435,7 @@ func buildCoverVM(data QuotePDFData, logoB64, logoMime string) coverViewModel {
 
 func buildQuoteVM(data QuotePDFData, logoB64, logoMime string) quoteViewModel {
 	vm := quoteViewModel{
+		Brand:                buildBrandVM(data),
 		LogoBase64:           logoB64,
 		LogoMimeType:         logoMime,
 		OrganizationName:     clampPDFText(data.OrganizationName, maxPDFShortText),
@@ -423,6 +475,14 @@ func buildQuoteVM(data QuotePDFData, logoB64, logoMime string) quoteViewModel {
 	if data.Notes != nil && *data.Notes != "" {
 		vm.Notes = template.HTML(clampPDFText(*data.Notes, maxPDFLongText)) //nolint:gosec // content from trusted org editors
 	}
+	if data.Terms != nil {
+		vm.Terms = &termsViewModel{
+			Title:                  clampPDFText(data.Terms.Title, maxPDFShortText),
+			Version:                data.Terms.Version,
+			EffectiveFromFormatted: data.Terms.EffectiveFrom.Format(dateFormatDMY),
+			Content:                template.HTML(formatOfferTermsHTML(data.Terms.Content)), //nolint:gosec // sanitized plain-text formatting
+		}
+	}
 
 	// Items
 	vm.Items = make([]itemViewModel, len(data.Items))
@@ -457,6 +517,16 @@ func buildQuoteVM(data QuotePDFData, logoB64, logoMime string) quoteViewModel {
 		}
 	}
 
+	vm.PaymentSchedule = make([]installmentViewModel, len(data.PaymentSchedule))
+	for i, inst := range data.PaymentSchedule {
+		vm.PaymentSchedule[i] = installmentViewModel{
+			Label:           clampPDFText(inst.Label, maxPDFShortText),
+			PctFormatted:    fmt.Sprintf("%g%%", float64(inst.PercentageBps)/100.0),
+			AmountFormatted: formatCurrency(inst.AmountCents),
+			DueLabel:        installmentDueLabel(inst),
+		}
+	}
+
 	// Organization settings — use defaults if not provided
 	vm.PaymentDays = data.PaymentDays
 	if vm.PaymentDays <= 0 {
@@ -470,6 +540,23 @@ func buildQuoteVM(data QuotePDFData, logoB64, logoMime string) quoteViewModel {
 	return vm
 }
 
+// installmentDueLabel describes when an installment is due, using the concrete date
+// once it is known.
+func installmentDueLabel(inst transport.PaymentInstallmentResponse) string {
+	if inst.DueAt != nil {
+		return inst.DueAt.Format(dateFormatDMY)
+	}
+	switch inst.DueTrigger {
+	case "days_after_acceptance":
+		if inst.DueAfterDays != nil {
+			return fmt.Sprintf("%d dagen na akkoord", *inst.DueAfterDays)
+		}
+	case "on_acceptance":
+		return "Bij akkoord"
+	}
+	return ""
+}
+
 func normalizePDFQuantity(quantity string) string {
 	trimmed := strings.TrimSpace(quantity)
 	if trimmed == "" {
@@ -490,6 +577,7 @@ func normalizePDFQuantity(quantity string) string {
 
 func buildSignatureVM(data QuotePDFData, logoB64, logoMime string) signatureViewModel {
 	vm := signatureViewModel{
+		Brand:            buildBrandVM(data),
 		LogoBase64:       logoB64,
 		LogoMimeType:     logoMime,
 		OrganizationName: clampPDFText(data.OrganizationName, maxPDFShortText),
@@ -535,10 +623,22 @@ func buildFooterVM(data QuotePDFData) footerViewModel {
 		parts = append(parts, data.OrgEmail)
 	}
 	return footerViewModel{
+		Brand:      buildBrandVM(data),
 		FooterText: strings.Join(parts, "  ·  "),
 	}
 }
 
+func buildBrandVM(data QuotePDFData) brandViewModel {
+	vm := brandViewModel{Primary: defaultPrimaryColor, Accent: defaultAccentColor}
+	if data.PrimaryColor != "" {
+		vm.Primary = data.PrimaryColor
+	}
+	if data.AccentColor != "" {
+		vm.Accent = data.AccentColor
+	}
+	return vm
+}
+
 // ── Template rendering ──────────────────────────────────────────────────
 
 // templateFuncs provides custom functions available in all PDF templates.
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"portal_final_backend/internal/quotes/transport"
 )
 
 const (
@@ -102,6 +104,70 @@ func TestQuotePDFTemplatesIncludeCustomerContactDetails(t *testing.T) {
 	}
 }
 
+func TestQuotePDFTemplatesIncludePaymentSchedule(t *testing.T) {
+	days := 14
+	data := QuotePDFData{
+		QuoteNumber: "OFF-2026-0043",
+		Status:      "Sent",
+		CreatedAt:   time.Date(2026, time.March, 18, 10, 30, 0, 0, time.UTC),
+		PaymentSchedule: []transport.PaymentInstallmentResponse{
+			{Label: "Aanbetaling", PercentageBps: 3000, AmountCents: 30000, DueTrigger: "on_acceptance"},
+			{Label: "Start werkzaamheden", PercentageBps: 7000, AmountCents: 70000, DueTrigger: "days_after_acceptance", DueAfterDays: &days},
+		},
+	}
+
+	for _, name := range []string{"templates/quote.html", "templates/quote_page_per_item.html"} {
+		rendered, err := renderTemplate(name, buildQuoteVM(data, "", ""))
+		if err != nil {
+			t.Fatalf("render %s: %v", name, err)
+		}
+		decoded := html.UnescapeString(string(rendered))
+		for _, expected := range []string{"Betalingsschema", "Aanbetaling", "30%", "Bij akkoord", "14 dagen na akkoord", "Betaling volgens het betalingsschema"} {
+			if !strings.Contains(decoded, expected) {
+				t.Fatalf("%s output missing %q", name, expected)
+			}
+		}
+	}
+}
+
+func TestQuotePDFTemplatesUseBrandColors(t *testing.T) {
+	data := QuotePDFData{
+		QuoteNumber:  "OFF-2026-0044",
+		Status:       "Sent",
+		CreatedAt:    time.Date(2026, time.March, 18, 10, 30, 0, 0, time.UTC),
+		PrimaryColor: "#0a3d62",
+		AccentColor:  "#e58e26",
+	}
+
+	rendered := map[string]any{
+		"templates/cover.html":               buildCoverVM(data, "", ""),
+		"templates/quote.html":               buildQuoteVM(data, "", ""),
+		"templates/quote_page_per_item.html": buildQuoteVM(data, "", ""),
+		"templates/signature.html":           buildSignatureVM(data, "", ""),
+		"templates/footer.html":              buildFooterVM(data),
+	}
+	for name, vm := range rendered {
+		out, err := renderTemplate(name, vm)
+		if err != nil {
+			t.Fatalf("render %s: %v", name, err)
+		}
+		if !strings.Contains(string(out), "#e58e26") {
+			t.Fatalf("%s output missing accent color", name)
+		}
+		if strings.Contains(strings.ToUpper(string(out)), defaultAccentColor) {
+			t.Fatalf("%s output still uses the default accent color", name)
+		}
+	}
+
+	footer, err := renderTemplate("templates/footer.html", buildFooterVM(QuotePDFData{}))
+	if err != nil {
+		t.Fatalf("render footer: %v", err)
+	}
+	if !strings.Contains(string(footer), defaultAccentColor) {
+		t.Fatalf("footer without branding should use the default accent color")
+	}
+}
+
 func TestISDESummaryTemplateIncludesEmbeddedQRCodes(t *testing.T) {
 	vm, err := buildISDESummaryViewModel(ISDESummaryPDFData{
 		QuoteNumber:          "OFF-2026-0042",
@@ -10,7 +10,10 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"strconv"
 	"time"
+
+	"portal_final_backend/platform/circuitbreaker"
 )
 
 // GotenbergClient converts HTML to PDF via a Gotenberg instance.
@@ -29,7 +32,8 @@ func NewGotenbergClient(baseURL, username, password string) *GotenbergClient {
 		username: username,
 		password: password,
 		http: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: circuitbreaker.Transport(circuitbreaker.For(circuitbreaker.DependencyGotenberg), nil),
 		},
 	}
 }
@@ -130,6 +134,60 @@ func (g *GotenbergClient) MergePDFs(ctx context.Context, pdfs map[string][]byte)
 	return g.doPost(ctx, "/forms/pdfengines/merge", body, writer.FormDataContentType())
 }
 
+// ConvertOffice converts an office document (Word, Excel, PowerPoint,
+// OpenDocument, RTF) to PDF using Gotenberg's LibreOffice route. The filename
+// extension tells LibreOffice which importer to use.
+func (g *GotenbergClient) ConvertOffice(ctx context.Context, filename string, data []byte) ([]byte, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := addFilePart(writer, filename, "application/octet-stream", data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	return g.doPost(ctx, "/forms/libreoffice/convert", body, writer.FormDataContentType())
+}
+
+// ExtractPage returns a single-page PDF holding the given 1-based page.
+// Gotenberg answers 400 when the page does not exist.
+func (g *GotenbergClient) ExtractPage(ctx context.Context, data []byte, page int) ([]byte, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	fields := map[string]string{
+		"splitMode":  "pages",
+		"splitSpan":  strconv.Itoa(page),
+		"splitUnify": "true",
+	}
+	for k, v := range fields {
+		if err := writer.WriteField(k, v); err != nil {
+			return nil, fmt.Errorf("write field %s: %w", k, err)
+		}
+	}
+	if err := addFilePart(writer, "document.pdf", "application/pdf", data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	return g.doPost(ctx, "/forms/pdfengines/split", body, writer.FormDataContentType())
+}
+
+// StatusError is returned when Gotenberg answers with a non-200 status.
+type StatusError struct {
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("gotenberg %s returned %d: %s", e.Path, e.StatusCode, e.Body)
+}
+
 // doPost sends a POST request and reads the response body.
 func (g *GotenbergClient) doPost(ctx context.Context, path string, body *bytes.Buffer, contentType string) ([]byte, error) {
 	url := g.baseURL + path
@@ -150,7 +208,7 @@ func (g *GotenbergClient) doPost(ctx context.Context, path string, body *bytes.B
 
 	if resp.StatusCode != http.StatusOK {
 		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-		return nil, fmt.Errorf("gotenberg %s returned %d: %s", path, resp.StatusCode, string(errBody))
+		return nil, &StatusError{Path: path, StatusCode: resp.StatusCode, Body: string(errBody)}
 	}
 
 	respCT := resp.Header.Get("Content-Type")
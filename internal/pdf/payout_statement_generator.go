@@ -0,0 +1,175 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PayoutStatementPDFData holds all data needed to generate the monthly payout
+// statement of a partner.
+type PayoutStatementPDFData struct {
+	StatementNumber string
+	PeriodStart     time.Time
+	PeriodEnd       time.Time
+	CreatedAt       time.Time
+
+	// Organization paying out
+	OrganizationName string
+	OrgEmail         string
+	OrgPhone         string
+	OrgVatNumber     string
+	OrgKvkNumber     string
+	OrgAddressLine1  string
+	OrgPostalCode    string
+	OrgCity          string
+	OrgLogo          []byte
+
+	// Partner being paid
+	PartnerName         string
+	PartnerKvkNumber    string
+	PartnerVatNumber    string
+	PartnerAddressLine1 string
+	PartnerPostalCode   string
+	PartnerCity         string
+
+	Jobs             []PayoutJobLinePDF
+	Adjustments      []PayoutAdjustmentLinePDF
+	EarningsCents    int64
+	LeadFeesCents    int64
+	AdjustmentsCents int64
+	PayoutCents      int64
+}
+
+// PayoutJobLinePDF is a completed job on the statement.
+type PayoutJobLinePDF struct {
+	Description      string
+	CompletedAt      time.Time
+	VakmanPriceCents int64
+	LeadFeeCents     int64
+	PayoutCents      int64
+}
+
+// PayoutAdjustmentLinePDF is a manual correction on the statement.
+type PayoutAdjustmentLinePDF struct {
+	Description   string
+	EffectiveDate time.Time
+	AmountCents   int64
+}
+
+// GeneratePayoutStatementPDF produces the PDF of a partner payout statement.
+func GeneratePayoutStatementPDF(data PayoutStatementPDFData) ([]byte, error) {
+	if gotenbergClient == nil {
+		return nil, fmt.Errorf("gotenberg client not initialized — call pdf.Init first")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	htmlContent, err := renderTemplate("templates/payout_statement.html", buildPayoutStatementVM(data))
+	if err != nil {
+		return nil, fmt.Errorf("render payout statement template: %w", err)
+	}
+
+	pdfBytes, err := gotenbergClient.ConvertHTML(ctx, htmlContent, DefaultContentOpts())
+	if err != nil {
+		return nil, fmt.Errorf("convert payout statement to PDF: %w", err)
+	}
+
+	return pdfBytes, nil
+}
+
+// ── View models ──────────────────────────────────────────────────────────────
+
+type payoutStatementViewModel struct {
+	LogoBase64           string
+	LogoMimeType         string
+	OrganizationName     string
+	OrgEmail             string
+	OrgPhone             string
+	OrgVatNumber         string
+	OrgKvkNumber         string
+	OrgAddressLine1      string
+	OrgPostalCode        string
+	OrgCity              string
+	PartnerName          string
+	PartnerKvkNumber     string
+	PartnerVatNumber     string
+	PartnerAddressLine1  string
+	PartnerPostalCode    string
+	PartnerCity          string
+	StatementNumber      string
+	PeriodFormatted      string
+	CreatedAtFormatted   string
+	Jobs                 []payoutJobViewModel
+	Adjustments          []payoutAdjustmentViewModel
+	EarningsFormatted    string
+	LeadFeesFormatted    string
+	AdjustmentsFormatted string
+	PayoutFormatted      string
+}
+
+type payoutJobViewModel struct {
+	Description          string
+	CompletedAtFormatted string
+	VakmanPriceFormatted string
+	LeadFeeFormatted     string
+	PayoutFormatted      string
+}
+
+type payoutAdjustmentViewModel struct {
+	Description     string
+	DateFormatted   string
+	AmountFormatted string
+}
+
+func buildPayoutStatementVM(data PayoutStatementPDFData) payoutStatementViewModel {
+	logoB64, logoMime := encodeLogoBase64(data.OrgLogo)
+
+	jobs := make([]payoutJobViewModel, len(data.Jobs))
+	for i, job := range data.Jobs {
+		jobs[i] = payoutJobViewModel{
+			Description:          job.Description,
+			CompletedAtFormatted: job.CompletedAt.Format(dateFormatDMY),
+			VakmanPriceFormatted: formatCurrency(job.VakmanPriceCents),
+			LeadFeeFormatted:     formatCurrency(-job.LeadFeeCents),
+			PayoutFormatted:      formatCurrency(job.PayoutCents),
+		}
+	}
+	adjustments := make([]payoutAdjustmentViewModel, len(data.Adjustments))
+	for i, adjustment := range data.Adjustments {
+		adjustments[i] = payoutAdjustmentViewModel{
+			Description:     adjustment.Description,
+			DateFormatted:   adjustment.EffectiveDate.Format(dateFormatDMY),
+			AmountFormatted: formatCurrency(adjustment.AmountCents),
+		}
+	}
+
+	return payoutStatementViewModel{
+		LogoBase64:           logoB64,
+		LogoMimeType:         logoMime,
+		OrganizationName:     data.OrganizationName,
+		OrgEmail:             data.OrgEmail,
+		OrgPhone:             data.OrgPhone,
+		OrgVatNumber:         data.OrgVatNumber,
+		OrgKvkNumber:         data.OrgKvkNumber,
+		OrgAddressLine1:      data.OrgAddressLine1,
+		OrgPostalCode:        data.OrgPostalCode,
+		OrgCity:              data.OrgCity,
+		PartnerName:          data.PartnerName,
+		PartnerKvkNumber:     data.PartnerKvkNumber,
+		PartnerVatNumber:     data.PartnerVatNumber,
+		PartnerAddressLine1:  data.PartnerAddressLine1,
+		PartnerPostalCode:    data.PartnerPostalCode,
+		PartnerCity:          data.PartnerCity,
+		StatementNumber:      data.StatementNumber,
+		PeriodFormatted:      data.PeriodStart.Format(dateFormatDMY) + " t/m " + data.PeriodEnd.Format(dateFormatDMY),
+		CreatedAtFormatted:   data.CreatedAt.Format(dateFormatDMY),
+		Jobs:                 jobs,
+		Adjustments:          adjustments,
+		EarningsFormatted:    formatCurrency(data.EarningsCents),
+		LeadFeesFormatted:    formatCurrency(-data.LeadFeesCents),
+		AdjustmentsFormatted: formatCurrency(data.AdjustmentsCents),
+		PayoutFormatted:      formatCurrency(data.PayoutCents),
+	}
+}
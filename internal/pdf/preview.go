@@ -0,0 +1,37 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrPageNotFound is returned by ExtractPDFPage for a page the PDF does not have.
+var ErrPageNotFound = errors.New("pdf page not found")
+
+// ConvertOfficeToPDF converts an office document to PDF so it can be
+// previewed in the browser.
+func ConvertOfficeToPDF(ctx context.Context, filename string, data []byte) ([]byte, error) {
+	if gotenbergClient == nil {
+		return nil, fmt.Errorf("gotenberg client not initialized — call pdf.Init first")
+	}
+	return gotenbergClient.ConvertOffice(ctx, filename, data)
+}
+
+// ExtractPDFPage returns a single-page PDF holding the given 1-based page.
+func ExtractPDFPage(ctx context.Context, data []byte, page int) ([]byte, error) {
+	if gotenbergClient == nil {
+		return nil, fmt.Errorf("gotenberg client not initialized — call pdf.Init first")
+	}
+	if page < 1 {
+		return nil, ErrPageNotFound
+	}
+
+	result, err := gotenbergClient.ExtractPage(ctx, data, page)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: %s", ErrPageNotFound, statusErr.Body)
+	}
+	return result, err
+}
@@ -209,6 +209,7 @@ WHERE q.organization_id = $1::uuid
     OR l.address_street ILIKE $4::text OR l.address_house_number ILIKE $4::text
     OR l.address_zip_code ILIKE $4::text OR l.address_city ILIKE $4::text
     OR u.first_name ILIKE $4::text OR u.last_name ILIKE $4::text OR u.email ILIKE $4::text
+    OR rac_custom_fields_text(q.custom_fields) ILIKE $4::text
     OR EXISTS (
       SELECT 1
       FROM RAC_quote_items qi
@@ -222,6 +223,7 @@ WHERE q.organization_id = $1::uuid
   AND ($8::timestamptz IS NULL OR q.valid_until < $8::timestamptz)
   AND ($9::bigint IS NULL OR q.total_cents >= $9::bigint)
   AND ($10::bigint IS NULL OR q.total_cents <= $10::bigint)
+  AND ($11::jsonb IS NULL OR q.custom_fields @> $11::jsonb)
 `
 
 type CountQuotesParams struct {
@@ -235,6 +237,7 @@ type CountQuotesParams struct {
 	ValidUntilTo   pgtype.Timestamptz `json:"valid_until_to"`
 	TotalFrom      pgtype.Int8        `json:"total_from"`
 	TotalTo        pgtype.Int8        `json:"total_to"`
+	CustomFields   []byte             `json:"custom_fields"`
 }
 
 func (q *Queries) CountQuotes(ctx context.Context, arg CountQuotesParams) (int64, error) {
@@ -249,6 +252,7 @@ func (q *Queries) CountQuotes(ctx context.Context, arg CountQuotesParams) (int64
 		arg.ValidUntilTo,
 		arg.TotalFrom,
 		arg.TotalTo,
+		arg.CustomFields,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -2695,6 +2699,7 @@ WHERE q.organization_id = $1::uuid
     OR l.address_street ILIKE $4::text OR l.address_house_number ILIKE $4::text
     OR l.address_zip_code ILIKE $4::text OR l.address_city ILIKE $4::text
     OR u.first_name ILIKE $4::text OR u.last_name ILIKE $4::text OR u.email ILIKE $4::text
+    OR rac_custom_fields_text(q.custom_fields) ILIKE $4::text
     OR EXISTS (
       SELECT 1
       FROM RAC_quote_items qi
@@ -2708,29 +2713,30 @@ WHERE q.organization_id = $1::uuid
   AND ($8::timestamptz IS NULL OR q.valid_until < $8::timestamptz)
   AND ($9::bigint IS NULL OR q.total_cents >= $9::bigint)
   AND ($10::bigint IS NULL OR q.total_cents <= $10::bigint)
+  AND ($11::jsonb IS NULL OR q.custom_fields @> $11::jsonb)
 ORDER BY
-  CASE WHEN $11::text = 'quoteNumber' AND $12::text = 'asc' THEN q.quote_number END ASC,
-  CASE WHEN $11::text = 'quoteNumber' AND $12::text = 'desc' THEN q.quote_number END DESC,
-  CASE WHEN $11::text = 'status' AND $12::text = 'asc' THEN q.status::text END ASC,
-  CASE WHEN $11::text = 'status' AND $12::text = 'desc' THEN q.status::text END DESC,
-  CASE WHEN $11::text = 'total' AND $12::text = 'asc' THEN q.total_cents END ASC,
-  CASE WHEN $11::text = 'total' AND $12::text = 'desc' THEN q.total_cents END DESC,
-  CASE WHEN $11::text = 'validUntil' AND $12::text = 'asc' THEN q.valid_until END ASC,
-  CASE WHEN $11::text = 'validUntil' AND $12::text = 'desc' THEN q.valid_until END DESC,
-  CASE WHEN $11::text = 'customerName' AND $12::text = 'asc' THEN l.consumer_last_name END ASC,
-  CASE WHEN $11::text = 'customerName' AND $12::text = 'desc' THEN l.consumer_last_name END DESC,
-  CASE WHEN $11::text = 'customerPhone' AND $12::text = 'asc' THEN l.consumer_phone END ASC,
-  CASE WHEN $11::text = 'customerPhone' AND $12::text = 'desc' THEN l.consumer_phone END DESC,
-  CASE WHEN $11::text = 'customerAddress' AND $12::text = 'asc' THEN l.address_city END ASC,
-  CASE WHEN $11::text = 'customerAddress' AND $12::text = 'desc' THEN l.address_city END DESC,
-  CASE WHEN $11::text = 'createdBy' AND $12::text = 'asc' THEN u.last_name END ASC,
-  CASE WHEN $11::text = 'createdBy' AND $12::text = 'desc' THEN u.last_name END DESC,
-  CASE WHEN $11::text = 'createdAt' AND $12::text = 'asc' THEN q.created_at END ASC,
-  CASE WHEN $11::text = 'createdAt' AND $12::text = 'desc' THEN q.created_at END DESC,
-  CASE WHEN $11::text = 'updatedAt' AND $12::text = 'asc' THEN q.updated_at END ASC,
-  CASE WHEN $11::text = 'updatedAt' AND $12::text = 'desc' THEN q.updated_at END DESC,
+  CASE WHEN $12::text = 'quoteNumber' AND $13::text = 'asc' THEN q.quote_number END ASC,
+  CASE WHEN $12::text = 'quoteNumber' AND $13::text = 'desc' THEN q.quote_number END DESC,
+  CASE WHEN $12::text = 'status' AND $13::text = 'asc' THEN q.status::text END ASC,
+  CASE WHEN $12::text = 'status' AND $13::text = 'desc' THEN q.status::text END DESC,
+  CASE WHEN $12::text = 'total' AND $13::text = 'asc' THEN q.total_cents END ASC,
+  CASE WHEN $12::text = 'total' AND $13::text = 'desc' THEN q.total_cents END DESC,
+  CASE WHEN $12::text = 'validUntil' AND $13::text = 'asc' THEN q.valid_until END ASC,
+  CASE WHEN $12::text = 'validUntil' AND $13::text = 'desc' THEN q.valid_until END DESC,
+  CASE WHEN $12::text = 'customerName' AND $13::text = 'asc' THEN l.consumer_last_name END ASC,
+  CASE WHEN $12::text = 'customerName' AND $13::text = 'desc' THEN l.consumer_last_name END DESC,
+  CASE WHEN $12::text = 'customerPhone' AND $13::text = 'asc' THEN l.consumer_phone END ASC,
+  CASE WHEN $12::text = 'customerPhone' AND $13::text = 'desc' THEN l.consumer_phone END DESC,
+  CASE WHEN $12::text = 'customerAddress' AND $13::text = 'asc' THEN l.address_city END ASC,
+  CASE WHEN $12::text = 'customerAddress' AND $13::text = 'desc' THEN l.address_city END DESC,
+  CASE WHEN $12::text = 'createdBy' AND $13::text = 'asc' THEN u.last_name END ASC,
+  CASE WHEN $12::text = 'createdBy' AND $13::text = 'desc' THEN u.last_name END DESC,
+  CASE WHEN $12::text = 'createdAt' AND $13::text = 'asc' THEN q.created_at END ASC,
+  CASE WHEN $12::text = 'createdAt' AND $13::text = 'desc' THEN q.created_at END DESC,
+  CASE WHEN $12::text = 'updatedAt' AND $13::text = 'asc' THEN q.updated_at END ASC,
+  CASE WHEN $12::text = 'updatedAt' AND $13::text = 'desc' THEN q.updated_at END DESC,
   q.created_at DESC
-LIMIT $14 OFFSET $13
+LIMIT $15 OFFSET $14
 `
 
 type ListQuotesParams struct {
@@ -2744,6 +2750,7 @@ type ListQuotesParams struct {
 	ValidUntilTo   pgtype.Timestamptz `json:"valid_until_to"`
 	TotalFrom      pgtype.Int8        `json:"total_from"`
 	TotalTo        pgtype.Int8        `json:"total_to"`
+	CustomFields   []byte             `json:"custom_fields"`
 	SortBy         string             `json:"sort_by"`
 	SortOrder      string             `json:"sort_order"`
 	OffsetCount    int32              `json:"offset_count"`
@@ -2809,6 +2816,7 @@ func (q *Queries) ListQuotes(ctx context.Context, arg ListQuotesParams) ([]ListQ
 		arg.ValidUntilTo,
 		arg.TotalFrom,
 		arg.TotalTo,
+		arg.CustomFields,
 		arg.SortBy,
 		arg.SortOrder,
 		arg.OffsetCount,
@@ -3534,7 +3542,8 @@ UPDATE RAC_quotes SET
   notes = $10,
   financing_disclaimer = $11,
   page_per_item = $12,
-  updated_at = $13
+  updated_at = $13,
+  row_version = row_version + 1
 WHERE id = $1 AND organization_id = $14
 `
 
@@ -40,6 +40,7 @@ type Handler struct {
 	attachmentBucket   string
 	catalogBucket      string
 	pdfGen             PDFOnDemandGenerator
+	creditNotePDFGen   CreditNotePDFGenerator
 	subsidyAnalyzerSvc SubsidyAnalyzerService
 }
 
@@ -80,6 +81,12 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/integrations/moneybird/authorize-url", h.GetMoneybirdAuthorizeURL)
 	rg.GET("/integrations/:provider/status", h.GetProviderIntegrationStatus)
 	rg.GET("/pending-approval", h.ListPendingApprovals)
+	rg.GET("/templates", h.ListTemplates)
+	rg.POST("/templates", h.CreateTemplate)
+	rg.GET("/templates/:templateId", h.GetTemplate)
+	rg.PUT("/templates/:templateId", h.UpdateTemplate)
+	rg.DELETE("/templates/:templateId", h.DeleteTemplate)
+	rg.POST("/templates/:templateId/instantiate", h.InstantiateTemplate)
 	rg.POST("", h.Create)
 	rg.POST("/calculate", h.PreviewCalculation)
 	rg.POST("/analyze-subsidy-preview", h.AnalyzeSubsidyPreview)
@@ -106,13 +113,30 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.PATCH("/:id/status", h.UpdateStatus)
 	rg.PATCH("/:id/lead-service", h.SetLeadService)
 	rg.POST("/:id/send", h.Send)
+	rg.GET("/:id/approvals", h.ListApprovals)
+	rg.POST("/:id/approval/request", h.RequestApproval)
+	rg.GET("/:id/auto-send", h.GetAutoSend)
+	rg.POST("/:id/auto-send/cancel", h.CancelAutoSend)
 	rg.GET("/:id/preview-link", h.GetPreviewLink)
 	rg.POST("/:id/items/:itemId/annotations", h.AgentAnnotate)
 	rg.POST("/:id/items/:itemId/annotations/draft-reply", h.SuggestAnnotationReplyDraft)
+	rg.GET("/:id/thread", h.GetThread)
+	rg.POST("/:id/thread/read", h.MarkThreadRead)
+	rg.POST("/:id/comments", h.AddComment)
 	rg.GET("/:id/activities", h.ListActivities)
 	rg.GET("/:id/pdf", h.DownloadPDF)
+	rg.GET("/:id/payment-schedule", h.GetPaymentSchedule)
+	rg.PUT("/:id/payment-schedule", h.SetPaymentSchedule)
+	rg.GET("/:id/terms", h.GetQuoteTerms)
+	rg.GET("/:id/payments", h.ListPayments)
+	rg.GET("/:id/credit-notes", h.ListCreditNotes)
+	rg.POST("/:id/credit-notes", h.CreateCreditNote)
+	rg.GET("/:id/credit-notes/:creditNoteId", h.GetCreditNote)
+	rg.GET("/:id/credit-notes/:creditNoteId/pdf", h.DownloadCreditNotePDF)
+	rg.POST("/:id/credit-notes/:creditNoteId/export/:provider", h.ExportCreditNoteToProvider)
 	rg.POST("/:id/analyze-subsidy", h.StartAnalyzeSubsidy)
 	rg.POST("/:id/attachments/presign", h.PresignAttachmentUpload)
+	rg.POST("/:id/attachments/upload", h.UploadAttachment)
 	rg.GET("/:id/attachments/:attachmentId/download", h.GetAttachmentDownloadURL)
 	rg.DELETE("/:id", h.Delete)
 	rg.GET("/subsidy-analysis-jobs/:id", h.GetAnalyzeSubsidyJob)
@@ -151,7 +175,21 @@ func (h *Handler) AnalyzeSubsidyPreview(c *gin.Context) {
 }
 
 func (h *Handler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/approval-policy", h.GetApprovalPolicy)
+	rg.PUT("/approval-policy", h.UpdateApprovalPolicy)
+	rg.GET("/auto-send-policy", h.GetAutoSendPolicy)
+	rg.PUT("/auto-send-policy", h.UpdateAutoSendPolicy)
+	rg.PUT("/integrations/mollie", h.ConnectMollie)
+	rg.GET("/terms-documents", h.ListTermsDocuments)
+	rg.POST("/terms-documents", h.CreateTermsDocument)
+	rg.GET("/terms-documents/:documentId", h.GetTermsDocument)
+	rg.PUT("/terms-documents/:documentId", h.UpdateTermsDocument)
+	rg.DELETE("/terms-documents/:documentId", h.ArchiveTermsDocument)
+	rg.POST("/terms-documents/:documentId/versions", h.AddTermsVersion)
+	rg.PUT("/terms-documents/:documentId/overrides", h.SetTermsOverrides)
 	rg.POST("/:id/transfer", h.Transfer)
+	rg.POST("/:id/approval/approve", h.ApproveQuote)
+	rg.POST("/:id/approval/reject", h.RejectQuote)
 }
 
 // CancelGenerateJob handles POST /api/v1/quotes/generate-jobs/:id/cancel
@@ -362,6 +400,7 @@ func (h *Handler) ClearCompletedGenerateJobs(c *gin.Context) {
 
 func (h *Handler) RegisterPublicRoutes(rg *gin.RouterGroup) {
 	rg.GET("/integrations/moneybird/callback", h.HandleMoneybirdOAuthCallback)
+	rg.POST("/integrations/mollie/webhook", h.HandleMollieWebhook)
 }
 
 func (h *Handler) GetMoneybirdAuthorizeURL(c *gin.Context) {
@@ -644,6 +683,10 @@ func (h *Handler) GetGenerateJob(c *gin.Context) {
 		StartedAt:          job.StartedAt,
 		UpdatedAt:          job.UpdatedAt,
 		FinishedAt:         job.FinishedAt,
+		Attempt:            job.Attempt,
+		MaxAttempts:        service.MaxGenerateQuoteJobAttempts,
+		RecoveryCount:      job.RecoveryCount,
+		LastHeartbeatAt:    job.HeartbeatAt,
 	})
 }
 
@@ -667,6 +710,10 @@ func toGenerateQuoteJobResponse(job *service.GenerateQuoteJob) transport.Generat
 		StartedAt:          job.StartedAt,
 		UpdatedAt:          job.UpdatedAt,
 		FinishedAt:         job.FinishedAt,
+		Attempt:            job.Attempt,
+		MaxAttempts:        service.MaxGenerateQuoteJobAttempts,
+		RecoveryCount:      job.RecoveryCount,
+		LastHeartbeatAt:    job.HeartbeatAt,
 	}
 }
 
@@ -696,6 +743,7 @@ func (h *Handler) GetByID(c *gin.Context) {
 		return
 	}
 
+	httpkit.SetRowVersionETag(c, result.RowVersion)
 	httpkit.OK(c, result)
 }
 
@@ -716,6 +764,11 @@ func (h *Handler) Update(c *gin.Context) {
 		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
 		return
 	}
+	rowVersion, ok := httpkit.RowVersionPrecondition(c, req.RowVersion)
+	if !ok {
+		return
+	}
+	req.RowVersion = rowVersion
 
 	tenantID, ok := httpkit.RequireTenant(c)
 	if !ok {
@@ -732,6 +785,7 @@ func (h *Handler) Update(c *gin.Context) {
 		return
 	}
 
+	httpkit.SetRowVersionETag(c, result.RowVersion)
 	httpkit.OK(c, result)
 }
 
@@ -954,7 +1008,7 @@ func (h *Handler) Send(c *gin.Context) {
 	}
 
 	identity := httpkit.MustGetIdentity(c)
-	result, err := h.svc.Send(c.Request.Context(), id, tenantID, identity.UserID())
+	result, err := h.svc.Send(c.Request.Context(), id, tenantID, identity.UserID(), identity.Roles())
 	if httpkit.HandleError(c, err) {
 		return
 	}
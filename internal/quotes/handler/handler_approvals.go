@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListApprovals handles GET /api/v1/quotes/:id/approvals
+// Returns the approval trail of a quote.
+func (h *Handler) ListApprovals(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListApprovals(c.Request.Context(), id, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// RequestApproval handles POST /api/v1/quotes/:id/approval/request
+// Submits a quote for admin approval before it is sent.
+func (h *Handler) RequestApproval(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.RequestQuoteApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.RequestApproval(c.Request.Context(), id, tenantID, identity.UserID(), identity.Roles(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, result)
+}
+
+// ApproveQuote handles POST /api/v1/admin/quotes/:id/approval/approve
+func (h *Handler) ApproveQuote(c *gin.Context) {
+	h.decideApproval(c, true)
+}
+
+// RejectQuote handles POST /api/v1/admin/quotes/:id/approval/reject
+func (h *Handler) RejectQuote(c *gin.Context) {
+	h.decideApproval(c, false)
+}
+
+func (h *Handler) decideApproval(c *gin.Context, approve bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.DecideQuoteApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	decide := h.svc.RejectQuote
+	if approve {
+		decide = h.svc.ApproveQuote
+	}
+	result, err := decide(c.Request.Context(), id, tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// GetApprovalPolicy handles GET /api/v1/admin/quotes/approval-policy
+func (h *Handler) GetApprovalPolicy(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetApprovalPolicy(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// UpdateApprovalPolicy handles PUT /api/v1/admin/quotes/approval-policy
+func (h *Handler) UpdateApprovalPolicy(c *gin.Context) {
+	var req transport.UpdateQuoteApprovalPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.UpdateApprovalPolicy(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadAttachment handles POST /api/v1/quotes/:id/attachments/upload
+// It streams a multipart PDF upload straight to MinIO, the same way a presigned
+// upload would land, and returns the stored file for attaching to the quote.
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	if h.storageSvc == nil || h.attachmentBucket == "" {
+		httpkit.Error(c, http.StatusServiceUnavailable, "attachment uploads are not configured", nil)
+		return
+	}
+
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	// Verify the quote exists and belongs to this tenant
+	if _, err := h.svc.GetByID(c.Request.Context(), id, tenantID); httpkit.HandleError(c, err) {
+		return
+	}
+
+	// Folder path: {org_id}/quotes/{quote_id}
+	folder := fmt.Sprintf("%s/quotes/%s", tenantID.String(), id.String())
+	upload, err := storage.StreamMultipartUpload(c.Request.Context(), h.storageSvc, c.Request, h.attachmentBucket, folder, acceptPDF)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, transport.UploadedAttachmentResponse{
+		FileKey:        upload.FileKey,
+		FileName:       upload.FileName,
+		ContentType:    upload.ContentType,
+		SizeBytes:      upload.SizeBytes,
+		ChecksumSHA256: upload.ChecksumSHA256,
+	})
+}
+
+func acceptPDF(contentType string) error {
+	if contentType != contentTypePDF {
+		return apperr.Validation("quote attachments must be PDF files")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetAutoSend handles GET /api/v1/quotes/:id/auto-send
+// Returns the scheduled auto-send of a quote and its audit trail.
+func (h *Handler) GetAutoSend(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetQuoteAutoSend(c.Request.Context(), id, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// CancelAutoSend handles POST /api/v1/quotes/:id/auto-send/cancel
+// Stops the scheduled auto-send of a quote so an agent can take over.
+func (h *Handler) CancelAutoSend(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.CancelQuoteAutoSendRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.CancelQuoteAutoSend(c.Request.Context(), id, tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// GetAutoSendPolicy handles GET /api/v1/admin/quotes/auto-send-policy
+func (h *Handler) GetAutoSendPolicy(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetAutoSendPolicy(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// UpdateAutoSendPolicy handles PUT /api/v1/admin/quotes/auto-send-policy
+// Disabling the policy cancels every scheduled auto-send.
+func (h *Handler) UpdateAutoSendPolicy(c *gin.Context) {
+	var req transport.UpdateQuoteAutoSendPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.UpdateAutoSendPolicy(c.Request.Context(), tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
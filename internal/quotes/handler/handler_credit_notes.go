@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreditNotePDFGenerator generates and stores a credit note PDF on the fly.
+type CreditNotePDFGenerator interface {
+	GenerateCreditNotePDF(ctx context.Context, quoteID, creditNoteID, organizationID uuid.UUID) (fileKey string, pdfBytes []byte, err error)
+}
+
+// SetCreditNotePDFGenerator injects the generator used for credit note PDF downloads.
+func (h *Handler) SetCreditNotePDFGenerator(gen CreditNotePDFGenerator) {
+	h.creditNotePDFGen = gen
+}
+
+// ListCreditNotes handles GET /api/v1/quotes/:id/credit-notes
+func (h *Handler) ListCreditNotes(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListCreditNotes(c.Request.Context(), id, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// CreateCreditNote handles POST /api/v1/quotes/:id/credit-notes
+// Issues a credit note with negative lines against an accepted quote.
+func (h *Handler) CreateCreditNote(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.CreateCreditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.CreateCreditNote(c.Request.Context(), id, tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, result)
+}
+
+// GetCreditNote handles GET /api/v1/quotes/:id/credit-notes/:creditNoteId
+func (h *Handler) GetCreditNote(c *gin.Context) {
+	id, creditNoteID, ok := parseCreditNoteParams(c)
+	if !ok {
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetCreditNote(c.Request.Context(), id, creditNoteID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// DownloadCreditNotePDF handles GET /api/v1/quotes/:id/credit-notes/:creditNoteId/pdf
+// The PDF is generated on first download and served from storage afterwards.
+func (h *Handler) DownloadCreditNotePDF(c *gin.Context) {
+	if h.storageSvc == nil {
+		httpkit.Error(c, http.StatusServiceUnavailable, "PDF downloads are not configured", nil)
+		return
+	}
+
+	id, creditNoteID, ok := parseCreditNoteParams(c)
+	if !ok {
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	number, fileKey, err := h.svc.GetCreditNotePDFFileKey(c.Request.Context(), id, creditNoteID, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	fileName := fmt.Sprintf("Creditnota-%s.pdf", number)
+
+	if fileKey == nil || *fileKey == "" {
+		if h.creditNotePDFGen == nil {
+			httpkit.Error(c, http.StatusNotFound, "no PDF available for this credit note", nil)
+			return
+		}
+		_, pdfBytes, genErr := h.creditNotePDFGen.GenerateCreditNotePDF(c.Request.Context(), id, creditNoteID, tenantID)
+		if genErr != nil {
+			httpkit.Error(c, http.StatusInternalServerError, msgPDFGenerationFailed, genErr.Error())
+			return
+		}
+		serveNamedPDFBytes(c, fileName, pdfBytes)
+		return
+	}
+
+	reader, err := h.storageSvc.DownloadFile(c.Request.Context(), h.pdfBucket, *fileKey)
+	if err != nil {
+		httpkit.Error(c, http.StatusInternalServerError, "failed to retrieve PDF", err.Error())
+		return
+	}
+	streamNamedPDFFromReader(c, fileName, reader)
+}
+
+// ExportCreditNoteToProvider handles POST /api/v1/quotes/:id/credit-notes/:creditNoteId/export/:provider
+func (h *Handler) ExportCreditNoteToProvider(c *gin.Context) {
+	id, creditNoteID, ok := parseCreditNoteParams(c)
+	if !ok {
+		return
+	}
+	provider := c.Param("provider")
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ExportCreditNoteToProvider(c.Request.Context(), id, creditNoteID, tenantID, provider)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+func parseCreditNoteParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	creditNoteID, err := uuid.Parse(c.Param("creditNoteId"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, creditNoteID, true
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ConnectMollie handles PUT /api/v1/admin/quotes/integrations/mollie
+func (h *Handler) ConnectMollie(c *gin.Context) {
+	var req transport.ConnectMollieRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.ConnectMollie(c.Request.Context(), tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// ListPayments handles GET /api/v1/quotes/:id/payments
+func (h *Handler) ListPayments(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListQuotePayments(c.Request.Context(), id, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// HandleMollieWebhook handles POST /api/v1/quotes/integrations/mollie/webhook
+// Mollie posts the payment ID as a form value; a non-2xx response makes Mollie retry.
+func (h *Handler) HandleMollieWebhook(c *gin.Context) {
+	if err := h.svc.HandleMollieWebhook(c.Request.Context(), c.PostForm("id")); httpkit.HandleError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
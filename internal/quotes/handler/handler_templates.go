@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListTemplates handles GET /api/v1/quotes/templates
+func (h *Handler) ListTemplates(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListQuoteTemplates(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// GetTemplate handles GET /api/v1/quotes/templates/:templateId
+func (h *Handler) GetTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetQuoteTemplate(c.Request.Context(), id, tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// CreateTemplate handles POST /api/v1/quotes/templates
+func (h *Handler) CreateTemplate(c *gin.Context) {
+	var req transport.SaveQuoteTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.CreateQuoteTemplate(c.Request.Context(), tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, result)
+}
+
+// UpdateTemplate handles PUT /api/v1/quotes/templates/:templateId
+func (h *Handler) UpdateTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.SaveQuoteTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.UpdateQuoteTemplate(c.Request.Context(), id, tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// DeleteTemplate handles DELETE /api/v1/quotes/templates/:templateId
+func (h *Handler) DeleteTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteQuoteTemplate(c.Request.Context(), id, tenantID); httpkit.HandleError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// InstantiateTemplate handles POST /api/v1/quotes/templates/:templateId/instantiate
+// Creates a draft quote for a lead service from the template.
+func (h *Handler) InstantiateTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.InstantiateQuoteTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	result, err := h.svc.InstantiateQuoteTemplate(c.Request.Context(), id, tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, result)
+}
@@ -13,16 +13,24 @@ import (
 )
 
 func servePDFBytes(c *gin.Context, quoteNumber string, pdfBytes []byte) {
+	serveNamedPDFBytes(c, fmt.Sprintf("Offerte-%s.pdf", quoteNumber), pdfBytes)
+}
+
+func serveNamedPDFBytes(c *gin.Context, fileName string, pdfBytes []byte) {
 	if err := validatePDFBytes(pdfBytes); err != nil {
 		httpkit.Error(c, http.StatusInternalServerError, "failed to serve PDF", err.Error())
 		return
 	}
-	slog.Info("serving PDF bytes", "quoteNumber", quoteNumber, "bytes", len(pdfBytes))
-	setPDFHeaders(c, quoteNumber)
+	slog.Info("serving PDF bytes", "fileName", fileName, "bytes", len(pdfBytes))
+	setPDFHeaders(c, fileName)
 	c.Data(http.StatusOK, contentTypePDF, pdfBytes)
 }
 
 func streamPDFFromReader(c *gin.Context, quoteNumber string, reader io.ReadCloser) {
+	streamNamedPDFFromReader(c, fmt.Sprintf("Offerte-%s.pdf", quoteNumber), reader)
+}
+
+func streamNamedPDFFromReader(c *gin.Context, fileName string, reader io.ReadCloser) {
 	defer func() { _ = reader.Close() }()
 
 	pdfBytes, err := io.ReadAll(reader)
@@ -31,8 +39,8 @@ func streamPDFFromReader(c *gin.Context, quoteNumber string, reader io.ReadClose
 		return
 	}
 
-	slog.Info("streaming PDF from storage", "quoteNumber", quoteNumber, "bytes", len(pdfBytes))
-	servePDFBytes(c, quoteNumber, pdfBytes)
+	slog.Info("streaming PDF from storage", "fileName", fileName, "bytes", len(pdfBytes))
+	serveNamedPDFBytes(c, fileName, pdfBytes)
 }
 
 func validatePDFBytes(pdfBytes []byte) error {
@@ -51,8 +59,7 @@ func validatePDFBytes(pdfBytes []byte) error {
 	return nil
 }
 
-func setPDFHeaders(c *gin.Context, quoteNumber string) {
-	fileName := fmt.Sprintf("Offerte-%s.pdf", quoteNumber)
+func setPDFHeaders(c *gin.Context, fileName string) {
 	c.Header("Content-Type", contentTypePDF)
 	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
 }
@@ -8,6 +8,7 @@ import (
 
 	"portal_final_backend/internal/adapters/storage"
 	"portal_final_backend/internal/notification/sse"
+	"portal_final_backend/internal/quotes/repository"
 	"portal_final_backend/internal/quotes/service"
 	"portal_final_backend/internal/quotes/transport"
 	"portal_final_backend/platform/httpkit"
@@ -29,6 +30,11 @@ type PDFOnDemandGenerator interface {
 	RegeneratePDF(ctx context.Context, quoteID, organizationID uuid.UUID) (fileKey string, pdfBytes []byte, err error)
 }
 
+// AttachmentPreviewer renders browser-friendly previews of quote attachments.
+type AttachmentPreviewer interface {
+	RenderAttachmentPreview(ctx context.Context, attachment repository.QuoteAttachment, variant string, page int) (data []byte, contentType string, err error)
+}
+
 // PublicHandler handles unauthenticated HTTP requests for public quote proposals.
 type PublicHandler struct {
 	svc        *service.Service
@@ -37,6 +43,9 @@ type PublicHandler struct {
 	storageSvc storage.StorageService
 	pdfBucket  string
 	pdfGen     PDFOnDemandGenerator
+	previewer  AttachmentPreviewer
+	// tokenLocator finds the organization of quote tokens in any data schema.
+	tokenLocator httpkit.PublicTokenResolver
 }
 
 // NewPublicHandler creates a new public quotes handler.
@@ -60,16 +69,42 @@ func (h *PublicHandler) SetPDFGenerator(gen PDFOnDemandGenerator) {
 	h.pdfGen = gen
 }
 
+// SetAttachmentPreviewer injects the renderer for attachment previews.
+func (h *PublicHandler) SetAttachmentPreviewer(previewer AttachmentPreviewer) {
+	h.previewer = previewer
+}
+
+// SetTokenLocator sets the lookup of the organization of public tokens, in its
+// dedicated or the shared data schema.
+func (h *PublicHandler) SetTokenLocator(locator httpkit.PublicTokenResolver) {
+	h.tokenLocator = locator
+}
+
+// tokenOrganization returns the organization of a public token when its
+// quote lives in a dedicated data schema.
+func (h *PublicHandler) tokenOrganization(ctx context.Context, token string) (uuid.UUID, bool, error) {
+	if h.tokenLocator == nil {
+		return uuid.Nil, false, nil
+	}
+	return h.tokenLocator(ctx, token)
+}
+
 // RegisterRoutes registers the public quote routes (no auth middleware).
 func (h *PublicHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.Use(httpkit.PublicTokenTenant(h.tokenOrganization))
 	rg.GET("/:token", h.GetPublicQuote)
 	rg.PATCH("/:token/items/:itemId/toggle", h.ToggleItem)
 	rg.POST("/:token/items/:itemId/annotations", h.AnnotateItem)
 	rg.PATCH(":token/items/:itemId/annotations/:annotationId", h.UpdateAnnotation)
 	rg.DELETE(":token/items/:itemId/annotations/:annotationId", h.DeleteAnnotation)
+	rg.GET("/:token/thread", h.GetThread)
+	rg.POST("/:token/thread/read", h.MarkThreadRead)
+	rg.POST("/:token/comments", h.AddComment)
 	rg.POST("/:token/accept", h.Accept)
 	rg.POST("/:token/reject", h.Reject)
+	rg.POST("/:token/deposit-payment", h.CreateDepositPayment)
 	rg.GET("/:token/pdf", h.DownloadPDF)
+	rg.GET("/:token/attachments/:attachmentId/preview", h.PreviewAttachment)
 
 	// Public SSE — customer page gets real-time updates
 	if h.sse != nil {
@@ -80,6 +115,15 @@ func (h *PublicHandler) RegisterRoutes(rg *gin.RouterGroup) {
 // resolveQuoteID maps a public token to a quote UUID.
 func (h *PublicHandler) resolveQuoteID(token string) (uuid.UUID, error) {
 	ctx := context.Background()
+	organizationID, ok, err := h.tokenOrganization(ctx, token)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	if ok {
+		if ctx, err = httpkit.BindTenant(ctx, organizationID); err != nil {
+			return uuid.UUID{}, err
+		}
+	}
 	return h.svc.GetPublicQuoteID(ctx, token)
 }
 
@@ -151,6 +195,48 @@ func (h *PublicHandler) AnnotateItem(c *gin.Context) {
 	httpkit.JSON(c, http.StatusCreated, result)
 }
 
+// GetThread handles GET /api/v1/public/quotes/:token/thread
+// Returns the comments on the quote and its line items, with the unread count
+// of the customer.
+func (h *PublicHandler) GetThread(c *gin.Context) {
+	result, err := h.svc.GetPublicThread(c.Request.Context(), c.Param("token"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// MarkThreadRead handles POST /api/v1/public/quotes/:token/thread/read
+func (h *PublicHandler) MarkThreadRead(c *gin.Context) {
+	if err := h.svc.MarkPublicThreadRead(c.Request.Context(), c.Param("token")); httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, gin.H{"status": "read"})
+}
+
+// AddComment handles POST /api/v1/public/quotes/:token/comments
+// Adds a customer comment on the quote as a whole.
+func (h *PublicHandler) AddComment(c *gin.Context) {
+	var req transport.QuoteCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	result, err := h.svc.AddPublicComment(c.Request.Context(), c.Param("token"), req.Text)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.JSON(c, http.StatusCreated, result)
+}
+
 // UpdateAnnotation handles PATCH /api/v1/public/quotes/:token/items/:itemId/annotations/:annotationId
 func (h *PublicHandler) UpdateAnnotation(c *gin.Context) {
 	token := c.Param("token")
@@ -227,6 +313,17 @@ func (h *PublicHandler) Accept(c *gin.Context) {
 	httpkit.OK(c, result)
 }
 
+// CreateDepositPayment handles POST /api/v1/public/quotes/:token/deposit-payment
+// Returns the open deposit payment of an accepted quote, creating a new payment link when needed.
+func (h *PublicHandler) CreateDepositPayment(c *gin.Context) {
+	result, err := h.svc.EnsureDepositPayment(c.Request.Context(), c.Param("token"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
 // DownloadPDF handles GET /api/v1/public/quotes/:token/pdf
 // Allows customers to download the generated PDF using the public token.
 func (h *PublicHandler) DownloadPDF(c *gin.Context) {
@@ -302,6 +399,48 @@ func (h *PublicHandler) DownloadPDF(c *gin.Context) {
 	servePDFBytes(c, result.QuoteNumber, pdfBytes)
 }
 
+// PreviewAttachment handles GET /api/v1/public/quotes/:token/attachments/:attachmentId/preview
+// Serves an inline preview of an attachment: a JPEG for images, or a single
+// page as PDF for documents. Previews are rendered on first request.
+func (h *PublicHandler) PreviewAttachment(c *gin.Context) {
+	if h.previewer == nil {
+		httpkit.Error(c, http.StatusServiceUnavailable, "attachment previews are not configured", nil)
+		return
+	}
+
+	token := c.Param("token")
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if token == "" || err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+
+	var req transport.AttachmentPreviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, err.Error())
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	attachment, err := h.svc.GetPublicAttachment(c.Request.Context(), token, attachmentID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	data, contentType, err := h.previewer.RenderAttachmentPreview(c.Request.Context(), *attachment, req.Variant, req.Page)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	c.Header("Content-Disposition", "inline")
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, contentType, data)
+}
+
 func (h *PublicHandler) tryServeOnDemandPDF(c *gin.Context, quoteID uuid.UUID, organizationID uuid.UUID, quoteNumber string) bool {
 	if h.pdfGen == nil {
 		slog.Warn("on-demand PDF generation skipped: no generator injected", "quoteID", quoteID)
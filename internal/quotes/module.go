@@ -2,13 +2,16 @@
 package quotes
 
 import (
+	"context"
+
 	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/events"
 	apphttp "portal_final_backend/internal/http"
 	"portal_final_backend/internal/notification/sse"
 	"portal_final_backend/internal/quotes/handler"
 	"portal_final_backend/internal/quotes/repository"
 	"portal_final_backend/internal/quotes/service"
-	"portal_final_backend/platform/events"
+	"portal_final_backend/platform/httpkit"
 	"portal_final_backend/platform/validator"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -40,6 +43,12 @@ func NewModule(pool *pgxpool.Pool, eventBus *events.InMemoryBus, val *validator.
 	}
 }
 
+// AttachmentPreviewer renders attachment previews and prunes outdated ones.
+type AttachmentPreviewer interface {
+	handler.AttachmentPreviewer
+	service.AttachmentPreviewPruner
+}
+
 // Name returns the module name for logging
 func (m *Module) Name() string {
 	return "quotes"
@@ -93,6 +102,30 @@ func (m *Module) SetPDFGenerator(gen handler.PDFOnDemandGenerator) {
 	m.publicHandler.SetPDFGenerator(gen)
 }
 
+// SetPublicTokenLocator injects the lookup of the organization of public quote
+// tokens.
+func (m *Module) SetPublicTokenLocator(locator httpkit.PublicTokenResolver) {
+	m.publicHandler.SetTokenLocator(locator)
+}
+
+// SetPaymentLocator injects the lookup of the organization of Mollie payments,
+// for webhooks of payments in a dedicated data schema.
+func (m *Module) SetPaymentLocator(locator httpkit.PublicTokenResolver) {
+	m.service.SetMolliePaymentLocator(locator)
+}
+
+// SetAttachmentPreviewer injects the renderer for public attachment previews.
+// It also prunes stored previews when a quote's attachments change.
+func (m *Module) SetAttachmentPreviewer(previewer AttachmentPreviewer) {
+	m.publicHandler.SetAttachmentPreviewer(previewer)
+	m.service.SetAttachmentPreviewPruner(previewer)
+}
+
+// SetCreditNotePDFGenerator injects the generator for credit note PDF downloads.
+func (m *Module) SetCreditNotePDFGenerator(gen handler.CreditNotePDFGenerator) {
+	m.handler.SetCreditNotePDFGenerator(gen)
+}
+
 // SetSubsidyAnalyzerService injects subsidy analysis support into quote handlers.
 func (m *Module) SetSubsidyAnalyzerService(svc handler.SubsidyAnalyzerService) {
 	m.handler.SetSubsidyAnalyzerService(svc)
@@ -103,6 +136,11 @@ func (m *Module) SetLogoPresigner(lp service.LogoPresigner) {
 	m.service.SetLogoPresigner(lp)
 }
 
+// SetBrandingReader injects the reader for brand colors in public responses.
+func (m *Module) SetBrandingReader(reader service.BrandingReader) {
+	m.service.SetBrandingReader(reader)
+}
+
 // RegisterRoutes registers the module's routes
 func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	quotes := ctx.Protected.Group(quotesRoutePath)
@@ -115,9 +153,27 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	m.handler.RegisterPublicRoutes(publicIntegrations)
 
 	// Public routes — no auth middleware
-	publicQuotes := ctx.V1.Group("/public/quotes")
+	publicQuotes := ctx.Public.Group("/quotes")
 	m.publicHandler.RegisterRoutes(publicQuotes)
 }
 
+// RegisterHandlers subscribes the module to system-wide events.
+func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
+	bus.Subscribe(events.WorkOrderConfirmed{}.EventName(), m)
+}
+
+// Handle processes subscribed domain events.
+func (m *Module) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.WorkOrderConfirmed:
+		if e.QuoteID == nil {
+			return nil
+		}
+		return m.service.InvoiceConfirmedWorkOrder(ctx, *e.QuoteID, e.OrganizationID)
+	default:
+		return nil
+	}
+}
+
 // Compile-time check that Module implements http.Module
 var _ apphttp.Module = (*Module)(nil)
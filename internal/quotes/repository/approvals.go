@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Quote approval statuses.
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+)
+
+// ApprovalPolicy is the per-organization configuration of the quote approval gate.
+type ApprovalPolicy struct {
+	OrganizationID  uuid.UUID
+	Enabled         bool
+	ThresholdCents  *int64
+	RequireForRoles []string
+	UpdatedAt       time.Time
+}
+
+// QuoteApproval is a single approval request and its decision.
+type QuoteApproval struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	QuoteID         uuid.UUID
+	Status          string
+	Reasons         []string
+	QuoteTotalCents int64
+	QuoteItemsHash  *string
+	RequestedByID   *uuid.UUID
+	RequestNote     *string
+	DecidedByID     *uuid.UUID
+	DecisionNote    *string
+	DecidedAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// CreateQuoteApprovalParams holds the fields for a new pending approval request.
+type CreateQuoteApprovalParams struct {
+	OrganizationID  uuid.UUID
+	QuoteID         uuid.UUID
+	Reasons         []string
+	QuoteTotalCents int64
+	QuoteItemsHash  string
+	RequestedByID   *uuid.UUID
+	RequestNote     *string
+}
+
+const quoteApprovalColumns = `id, organization_id, quote_id, status, reasons, quote_total_cents, quote_items_hash,
+	requested_by_id, request_note, decided_by_id, decision_note, decided_at, created_at, updated_at`
+
+func scanQuoteApproval(row pgx.Row) (*QuoteApproval, error) {
+	var a QuoteApproval
+	if err := row.Scan(
+		&a.ID, &a.OrganizationID, &a.QuoteID, &a.Status, &a.Reasons, &a.QuoteTotalCents, &a.QuoteItemsHash,
+		&a.RequestedByID, &a.RequestNote, &a.DecidedByID, &a.DecisionNote, &a.DecidedAt, &a.CreatedAt, &a.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetApprovalPolicy returns the organization's approval policy, or a disabled default when none is stored.
+func (r *Repository) GetApprovalPolicy(ctx context.Context, orgID uuid.UUID) (*ApprovalPolicy, error) {
+	policy := ApprovalPolicy{OrganizationID: orgID, RequireForRoles: []string{}}
+	err := r.pool.QueryRow(ctx, `
+		SELECT enabled, threshold_cents, require_for_roles, updated_at
+		FROM RAC_quote_approval_policies
+		WHERE organization_id = $1
+	`, orgID).Scan(&policy.Enabled, &policy.ThresholdCents, &policy.RequireForRoles, &policy.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &policy, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote approval policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// UpsertApprovalPolicy stores the organization's approval policy.
+func (r *Repository) UpsertApprovalPolicy(ctx context.Context, policy ApprovalPolicy) (*ApprovalPolicy, error) {
+	roles := policy.RequireForRoles
+	if roles == nil {
+		roles = []string{}
+	}
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_quote_approval_policies (organization_id, enabled, threshold_cents, require_for_roles)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			threshold_cents = EXCLUDED.threshold_cents,
+			require_for_roles = EXCLUDED.require_for_roles,
+			updated_at = now()
+		RETURNING enabled, threshold_cents, require_for_roles, updated_at
+	`, policy.OrganizationID, policy.Enabled, policy.ThresholdCents, roles).Scan(
+		&policy.Enabled, &policy.ThresholdCents, &policy.RequireForRoles, &policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert quote approval policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// CreateQuoteApproval inserts a pending approval request. Only one pending request may exist per quote.
+func (r *Repository) CreateQuoteApproval(ctx context.Context, params CreateQuoteApprovalParams) (*QuoteApproval, error) {
+	reasons := params.Reasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+	approval, err := scanQuoteApproval(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_quote_approvals (organization_id, quote_id, reasons, quote_total_cents, quote_items_hash, requested_by_id, request_note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+quoteApprovalColumns,
+		params.OrganizationID, params.QuoteID, reasons, params.QuoteTotalCents, params.QuoteItemsHash, params.RequestedByID, params.RequestNote,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperr.Conflict("an approval request is already pending for this quote")
+		}
+		return nil, fmt.Errorf("failed to create quote approval: %w", err)
+	}
+	return approval, nil
+}
+
+// GetPendingQuoteApproval returns the pending approval request for a quote, or nil when there is none.
+func (r *Repository) GetPendingQuoteApproval(ctx context.Context, quoteID, orgID uuid.UUID) (*QuoteApproval, error) {
+	approval, err := scanQuoteApproval(r.pool.QueryRow(ctx, `
+		SELECT `+quoteApprovalColumns+`
+		FROM RAC_quote_approvals
+		WHERE quote_id = $1 AND organization_id = $2 AND status = 'pending'
+	`, quoteID, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending quote approval: %w", err)
+	}
+	return approval, nil
+}
+
+// GetLatestApprovedQuoteApproval returns the most recent approved request for a quote, or nil.
+func (r *Repository) GetLatestApprovedQuoteApproval(ctx context.Context, quoteID, orgID uuid.UUID) (*QuoteApproval, error) {
+	approval, err := scanQuoteApproval(r.pool.QueryRow(ctx, `
+		SELECT `+quoteApprovalColumns+`
+		FROM RAC_quote_approvals
+		WHERE quote_id = $1 AND organization_id = $2 AND status = 'approved'
+		ORDER BY decided_at DESC
+		LIMIT 1
+	`, quoteID, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approved quote approval: %w", err)
+	}
+	return approval, nil
+}
+
+// DecideQuoteApproval resolves the pending approval request of a quote.
+func (r *Repository) DecideQuoteApproval(ctx context.Context, quoteID, orgID uuid.UUID, status string, decidedByID uuid.UUID, note *string) (*QuoteApproval, error) {
+	approval, err := scanQuoteApproval(r.pool.QueryRow(ctx, `
+		UPDATE RAC_quote_approvals
+		SET status = $3, decided_by_id = $4, decision_note = $5, decided_at = now(), updated_at = now()
+		WHERE quote_id = $1 AND organization_id = $2 AND status = 'pending'
+		RETURNING `+quoteApprovalColumns,
+		quoteID, orgID, status, decidedByID, note,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound("no pending approval request for this quote")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decide quote approval: %w", err)
+	}
+	return approval, nil
+}
+
+// ListQuoteApprovals returns the approval trail of a quote, newest first.
+func (r *Repository) ListQuoteApprovals(ctx context.Context, quoteID, orgID uuid.UUID) ([]QuoteApproval, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+quoteApprovalColumns+`
+		FROM RAC_quote_approvals
+		WHERE quote_id = $1 AND organization_id = $2
+		ORDER BY created_at DESC
+	`, quoteID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote approvals: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]QuoteApproval, 0)
+	for rows.Next() {
+		approval, err := scanQuoteApproval(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote approval: %w", err)
+		}
+		items = append(items, *approval)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const attachmentPreviewNotFoundMsg = "attachment preview not found"
+
+// QuoteAttachmentPreview is a rendered preview of a quote attachment, stored
+// in object storage under PreviewKey.
+type QuoteAttachmentPreview struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	QuoteID        uuid.UUID
+	Source         string
+	FileKey        string
+	Variant        string
+	Page           int
+	PreviewKey     string
+	ContentType    string
+	SizeBytes      int64
+	CreatedAt      time.Time
+}
+
+const attachmentPreviewColumns = `id, organization_id, quote_id, source, file_key, variant, page,
+	preview_key, content_type, size_bytes, created_at`
+
+func scanAttachmentPreview(row pgx.Row) (*QuoteAttachmentPreview, error) {
+	var p QuoteAttachmentPreview
+	if err := row.Scan(&p.ID, &p.OrganizationID, &p.QuoteID, &p.Source, &p.FileKey, &p.Variant, &p.Page,
+		&p.PreviewKey, &p.ContentType, &p.SizeBytes, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetAttachmentPreview returns the stored preview of one variant and page of an attachment file.
+func (r *Repository) GetAttachmentPreview(ctx context.Context, quoteID, orgID uuid.UUID, source, fileKey, variant string, page int) (*QuoteAttachmentPreview, error) {
+	preview, err := scanAttachmentPreview(r.pool.QueryRow(ctx, `
+		SELECT `+attachmentPreviewColumns+`
+		FROM RAC_quote_attachment_previews
+		WHERE quote_id = $1 AND organization_id = $2
+			AND source = $3 AND file_key = $4 AND variant = $5 AND page = $6
+	`, quoteID, orgID, source, fileKey, variant, page))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound(attachmentPreviewNotFoundMsg)
+		}
+		return nil, fmt.Errorf("failed to get attachment preview: %w", err)
+	}
+	return preview, nil
+}
+
+// CreateAttachmentPreview records a stored preview. It reports false when a
+// concurrent request already recorded the same variant and page.
+func (r *Repository) CreateAttachmentPreview(ctx context.Context, preview QuoteAttachmentPreview) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_quote_attachment_previews (
+			organization_id, quote_id, source, file_key, variant, page,
+			preview_key, content_type, size_bytes
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (quote_id, source, file_key, variant, page) DO NOTHING
+	`, preview.OrganizationID, preview.QuoteID, preview.Source, preview.FileKey, preview.Variant, preview.Page,
+		preview.PreviewKey, preview.ContentType, preview.SizeBytes)
+	if err != nil {
+		return false, fmt.Errorf("failed to create attachment preview: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListStaleAttachmentPreviews returns the previews of a quote whose source file
+// is no longer attached to it.
+func (r *Repository) ListStaleAttachmentPreviews(ctx context.Context, quoteID, orgID uuid.UUID) ([]QuoteAttachmentPreview, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+attachmentPreviewColumns+`
+		FROM RAC_quote_attachment_previews p
+		WHERE p.quote_id = $1 AND p.organization_id = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM RAC_quote_attachments a
+				WHERE a.quote_id = p.quote_id AND a.organization_id = p.organization_id
+					AND a.source = p.source AND a.file_key = p.file_key
+			)
+	`, quoteID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale attachment previews: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]QuoteAttachmentPreview, 0)
+	for rows.Next() {
+		preview, err := scanAttachmentPreview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment preview: %w", err)
+		}
+		items = append(items, *preview)
+	}
+	return items, rows.Err()
+}
+
+// DeleteAttachmentPreview removes the record of a stored preview.
+func (r *Repository) DeleteAttachmentPreview(ctx context.Context, id, orgID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_quote_attachment_previews WHERE id = $1 AND organization_id = $2
+	`, id, orgID); err != nil {
+		return fmt.Errorf("failed to delete attachment preview: %w", err)
+	}
+	return nil
+}
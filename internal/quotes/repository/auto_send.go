@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Quote auto-send statuses.
+const (
+	AutoSendStatusScheduled  = "scheduled"
+	AutoSendStatusProcessing = "processing"
+	AutoSendStatusSent       = "sent"
+	AutoSendStatusCancelled  = "cancelled"
+	AutoSendStatusSkipped    = "skipped"
+	AutoSendStatusFailed     = "failed"
+)
+
+const defaultAutoSendReviewWindowMinutes = 60
+
+// AutoSendPolicy is the per-organization configuration of automatic sending of AI-drafted quotes.
+type AutoSendPolicy struct {
+	OrganizationID      uuid.UUID
+	Enabled             bool
+	ReviewWindowMinutes int
+	MinLeadScore        *int
+	MaxTotalCents       *int64
+	ServiceTypeIDs      []uuid.UUID
+	UpdatedByID         *uuid.UUID
+	UpdatedAt           time.Time
+}
+
+// QuoteAutoSend is one auto-send decision for a quote and its outcome.
+type QuoteAutoSend struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	QuoteID         uuid.UUID
+	ReviewID        *uuid.UUID
+	Status          string
+	Reason          *string
+	ScheduledFor    *time.Time
+	QuoteTotalCents int64
+	QuoteRowVersion int64
+	LeadScore       *int
+	CancelledByID   *uuid.UUID
+	DecidedAt       *time.Time
+	CreatedAt       time.Time
+}
+
+// CreateQuoteAutoSendParams holds the fields of a new auto-send decision. A scheduled
+// decision replaces the schedule that is still pending for the quote, if any.
+type CreateQuoteAutoSendParams struct {
+	OrganizationID  uuid.UUID
+	QuoteID         uuid.UUID
+	ReviewID        *uuid.UUID
+	Status          string
+	Reason          *string
+	ScheduledFor    *time.Time
+	QuoteTotalCents int64
+	QuoteRowVersion int64
+	LeadScore       *int
+}
+
+// QuoteAutoSendContext holds the lead facts an auto-send decision is based on.
+type QuoteAutoSendContext struct {
+	LeadScore     *int
+	ServiceTypeID *uuid.UUID
+}
+
+const quoteAutoSendColumns = `id, organization_id, quote_id, review_id, status, reason, scheduled_for,
+	quote_total_cents, quote_row_version, lead_score, cancelled_by_id, decided_at, created_at`
+
+func scanQuoteAutoSend(row pgx.Row) (*QuoteAutoSend, error) {
+	var a QuoteAutoSend
+	if err := row.Scan(
+		&a.ID, &a.OrganizationID, &a.QuoteID, &a.ReviewID, &a.Status, &a.Reason, &a.ScheduledFor,
+		&a.QuoteTotalCents, &a.QuoteRowVersion, &a.LeadScore, &a.CancelledByID, &a.DecidedAt, &a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetAutoSendPolicy returns the organization's auto-send policy, or a disabled default when none is stored.
+func (r *Repository) GetAutoSendPolicy(ctx context.Context, orgID uuid.UUID) (*AutoSendPolicy, error) {
+	policy := AutoSendPolicy{OrganizationID: orgID, ReviewWindowMinutes: defaultAutoSendReviewWindowMinutes, ServiceTypeIDs: []uuid.UUID{}}
+	err := r.pool.QueryRow(ctx, `
+		SELECT enabled, review_window_minutes, min_lead_score, max_total_cents, service_type_ids, updated_by_id, updated_at
+		FROM RAC_quote_auto_send_policies
+		WHERE organization_id = $1
+	`, orgID).Scan(&policy.Enabled, &policy.ReviewWindowMinutes, &policy.MinLeadScore, &policy.MaxTotalCents,
+		&policy.ServiceTypeIDs, &policy.UpdatedByID, &policy.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &policy, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote auto-send policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// UpsertAutoSendPolicy stores the organization's auto-send policy. Disabling the policy
+// cancels every auto-send that is still scheduled, in the same transaction.
+func (r *Repository) UpsertAutoSendPolicy(ctx context.Context, policy AutoSendPolicy, cancelReason string) (*AutoSendPolicy, error) {
+	serviceTypeIDs := policy.ServiceTypeIDs
+	if serviceTypeIDs == nil {
+		serviceTypeIDs = []uuid.UUID{}
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO RAC_quote_auto_send_policies (
+			organization_id, enabled, review_window_minutes, min_lead_score, max_total_cents, service_type_ids, updated_by_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			review_window_minutes = EXCLUDED.review_window_minutes,
+			min_lead_score = EXCLUDED.min_lead_score,
+			max_total_cents = EXCLUDED.max_total_cents,
+			service_type_ids = EXCLUDED.service_type_ids,
+			updated_by_id = EXCLUDED.updated_by_id,
+			updated_at = now()
+		RETURNING enabled, review_window_minutes, min_lead_score, max_total_cents, service_type_ids, updated_by_id, updated_at
+	`, policy.OrganizationID, policy.Enabled, policy.ReviewWindowMinutes, policy.MinLeadScore, policy.MaxTotalCents,
+		serviceTypeIDs, policy.UpdatedByID).Scan(
+		&policy.Enabled, &policy.ReviewWindowMinutes, &policy.MinLeadScore, &policy.MaxTotalCents,
+		&policy.ServiceTypeIDs, &policy.UpdatedByID, &policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert quote auto-send policy: %w", err)
+	}
+
+	if !policy.Enabled {
+		if _, err := tx.Exec(ctx, `
+			UPDATE RAC_quote_auto_sends
+			SET status = 'cancelled', reason = $2, cancelled_by_id = $3, decided_at = now(), updated_at = now()
+			WHERE organization_id = $1 AND status = 'scheduled'
+		`, policy.OrganizationID, cancelReason, policy.UpdatedByID); err != nil {
+			return nil, fmt.Errorf("failed to cancel scheduled quote auto-sends: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit quote auto-send policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// GetQuoteAutoSendContext returns the lead score and service type of the lead service a quote belongs to.
+func (r *Repository) GetQuoteAutoSendContext(ctx context.Context, quoteID, orgID uuid.UUID) (*QuoteAutoSendContext, error) {
+	var c QuoteAutoSendContext
+	err := r.pool.QueryRow(ctx, `
+		SELECT l.lead_score, ls.service_type_id
+		FROM RAC_quotes q
+		JOIN RAC_leads l ON l.id = q.lead_id AND l.organization_id = q.organization_id
+		LEFT JOIN RAC_lead_services ls ON ls.id = q.lead_service_id
+		WHERE q.id = $1 AND q.organization_id = $2
+	`, quoteID, orgID).Scan(&c.LeadScore, &c.ServiceTypeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound(quoteNotFoundMsg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote auto-send context: %w", err)
+	}
+	return &c, nil
+}
+
+// CreateQuoteAutoSend records an auto-send decision. A new schedule for a quote
+// cancels the one that is still pending, so only one schedule is active per quote.
+func (r *Repository) CreateQuoteAutoSend(ctx context.Context, params CreateQuoteAutoSendParams) (*QuoteAutoSend, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if params.Status == AutoSendStatusScheduled {
+		if _, err := tx.Exec(ctx, `
+			UPDATE RAC_quote_auto_sends
+			SET status = 'cancelled', reason = 'rescheduled', decided_at = now(), updated_at = now()
+			WHERE quote_id = $1 AND organization_id = $2 AND status = 'scheduled'
+		`, params.QuoteID, params.OrganizationID); err != nil {
+			return nil, fmt.Errorf("failed to replace scheduled quote auto-send: %w", err)
+		}
+	}
+
+	var decidedAt *time.Time
+	if params.Status != AutoSendStatusScheduled {
+		now := time.Now()
+		decidedAt = &now
+	}
+	autoSend, err := scanQuoteAutoSend(tx.QueryRow(ctx, `
+		INSERT INTO RAC_quote_auto_sends (
+			organization_id, quote_id, review_id, status, reason, scheduled_for,
+			quote_total_cents, quote_row_version, lead_score, decided_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+quoteAutoSendColumns,
+		params.OrganizationID, params.QuoteID, params.ReviewID, params.Status, params.Reason, params.ScheduledFor,
+		params.QuoteTotalCents, params.QuoteRowVersion, params.LeadScore, decidedAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quote auto-send: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit quote auto-send: %w", err)
+	}
+	return autoSend, nil
+}
+
+// ListQuoteAutoSends returns the auto-send decisions of a quote, newest first.
+func (r *Repository) ListQuoteAutoSends(ctx context.Context, quoteID, orgID uuid.UUID) ([]QuoteAutoSend, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+quoteAutoSendColumns+`
+		FROM RAC_quote_auto_sends
+		WHERE quote_id = $1 AND organization_id = $2
+		ORDER BY created_at DESC
+	`, quoteID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote auto-sends: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]QuoteAutoSend, 0)
+	for rows.Next() {
+		a, err := scanQuoteAutoSend(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote auto-send: %w", err)
+		}
+		items = append(items, *a)
+	}
+	return items, rows.Err()
+}
+
+// ListDueQuoteAutoSends returns scheduled auto-sends whose review window ended at or before now, oldest first.
+func (r *Repository) ListDueQuoteAutoSends(ctx context.Context, now time.Time, limit int) ([]QuoteAutoSend, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+quoteAutoSendColumns+`
+		FROM RAC_quote_auto_sends
+		WHERE status = 'scheduled' AND scheduled_for <= $1
+		ORDER BY scheduled_for
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due quote auto-sends: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]QuoteAutoSend, 0)
+	for rows.Next() {
+		a, err := scanQuoteAutoSend(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote auto-send: %w", err)
+		}
+		items = append(items, *a)
+	}
+	return items, rows.Err()
+}
+
+// ClaimQuoteAutoSend moves a scheduled auto-send to processing. It reports false when
+// the schedule was cancelled or another sweep already claimed it.
+func (r *Repository) ClaimQuoteAutoSend(ctx context.Context, id, orgID uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_quote_auto_sends SET status = 'processing', updated_at = now()
+		WHERE id = $1 AND organization_id = $2 AND status = 'scheduled'
+	`, id, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim quote auto-send: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// FinishQuoteAutoSend records the outcome of a claimed auto-send.
+func (r *Repository) FinishQuoteAutoSend(ctx context.Context, id, orgID uuid.UUID, status string, reason *string) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_quote_auto_sends
+		SET status = $3, reason = $4, decided_at = now(), updated_at = now()
+		WHERE id = $1 AND organization_id = $2 AND status = 'processing'
+	`, id, orgID, status, reason); err != nil {
+		return fmt.Errorf("failed to finish quote auto-send: %w", err)
+	}
+	return nil
+}
+
+// CancelQuoteAutoSend cancels the auto-send that is scheduled for a quote. It returns
+// nil when nothing was scheduled.
+func (r *Repository) CancelQuoteAutoSend(ctx context.Context, quoteID, orgID uuid.UUID, reason string, cancelledByID *uuid.UUID) (*QuoteAutoSend, error) {
+	autoSend, err := scanQuoteAutoSend(r.pool.QueryRow(ctx, `
+		UPDATE RAC_quote_auto_sends
+		SET status = 'cancelled', reason = $3, cancelled_by_id = $4, decided_at = now(), updated_at = now()
+		WHERE quote_id = $1 AND organization_id = $2 AND status = 'scheduled'
+		RETURNING `+quoteAutoSendColumns,
+		quoteID, orgID, reason, cancelledByID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel quote auto-send: %w", err)
+	}
+	return autoSend, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Thread parties, matching the author types of annotations and comments.
+const (
+	ThreadPartyCustomer = "customer"
+	ThreadPartyAgent    = "agent"
+)
+
+// QuoteComment is a comment on a quote as a whole.
+type QuoteComment struct {
+	ID             uuid.UUID
+	QuoteID        uuid.UUID
+	OrganizationID uuid.UUID
+	AuthorType     string
+	AuthorID       *uuid.UUID
+	Text           string
+	CreatedAt      time.Time
+}
+
+// CreateQuoteComment inserts a quote-level comment.
+func (r *Repository) CreateQuoteComment(ctx context.Context, c *QuoteComment) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_quote_comments (id, quote_id, organization_id, author_type, author_id, text, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		c.ID, c.QuoteID, c.OrganizationID, c.AuthorType, c.AuthorID, c.Text, c.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create quote comment: %w", err)
+	}
+	return nil
+}
+
+// ListQuoteComments returns the quote-level comments of a quote, oldest first.
+func (r *Repository) ListQuoteComments(ctx context.Context, quoteID uuid.UUID) ([]QuoteComment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, quote_id, organization_id, author_type, author_id, text, created_at
+		FROM RAC_quote_comments
+		WHERE quote_id = $1
+		ORDER BY created_at, id`, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make([]QuoteComment, 0)
+	for rows.Next() {
+		var c QuoteComment
+		if err := rows.Scan(&c.ID, &c.QuoteID, &c.OrganizationID, &c.AuthorType, &c.AuthorID, &c.Text, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan quote comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// GetThreadReadAt returns until when party read the thread of a quote, or nil
+// when it never did.
+func (r *Repository) GetThreadReadAt(ctx context.Context, quoteID uuid.UUID, party string) (*time.Time, error) {
+	var readAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT last_read_at FROM RAC_quote_thread_reads WHERE quote_id = $1 AND party = $2`,
+		quoteID, party,
+	).Scan(&readAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote thread read marker: %w", err)
+	}
+	return &readAt, nil
+}
+
+// MarkThreadRead records that party read the thread of a quote up to readAt.
+// The marker never moves back.
+func (r *Repository) MarkThreadRead(ctx context.Context, quoteID uuid.UUID, party string, readAt time.Time) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_quote_thread_reads (quote_id, party, last_read_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (quote_id, party) DO UPDATE
+		SET last_read_at = GREATEST(RAC_quote_thread_reads.last_read_at, EXCLUDED.last_read_at)`,
+		quoteID, party, readAt,
+	); err != nil {
+		return fmt.Errorf("failed to mark quote thread read: %w", err)
+	}
+	return nil
+}
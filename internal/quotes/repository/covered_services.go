@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CountLeadServicesOfLead counts how many of serviceIDs are services of the
+// lead.
+func (r *Repository) CountLeadServicesOfLead(ctx context.Context, leadID, organizationID uuid.UUID, serviceIDs []uuid.UUID) (int, error) {
+	var count int
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM RAC_lead_services
+		WHERE lead_id = $1 AND organization_id = $2 AND id = ANY($3)`,
+		leadID, organizationID, serviceIDs,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count lead services: %w", err)
+	}
+	return count, nil
+}
+
+// ReplaceCoveredServices sets the services a quote covers besides its own
+// lead service.
+func (r *Repository) ReplaceCoveredServices(ctx context.Context, quoteID, organizationID uuid.UUID, serviceIDs []uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM RAC_quote_covered_services WHERE quote_id = $1 AND organization_id = $2`,
+		quoteID, organizationID,
+	); err != nil {
+		return fmt.Errorf("delete covered services: %w", err)
+	}
+	for _, serviceID := range serviceIDs {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_quote_covered_services (quote_id, lead_service_id, organization_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`,
+			quoteID, serviceID, organizationID,
+		); err != nil {
+			return fmt.Errorf("insert covered service: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// CopyCoveredServices copies the covered services of one quote to another,
+// skipping services that do not belong to the lead of the target quote.
+func (r *Repository) CopyCoveredServices(ctx context.Context, fromQuoteID, toQuoteID, leadID, organizationID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_quote_covered_services (quote_id, lead_service_id, organization_id)
+		SELECT $2, c.lead_service_id, c.organization_id
+		FROM RAC_quote_covered_services c
+		JOIN RAC_lead_services ls ON ls.id = c.lead_service_id AND ls.lead_id = $3
+		WHERE c.quote_id = $1 AND c.organization_id = $4
+		ON CONFLICT DO NOTHING`,
+		fromQuoteID, toQuoteID, leadID, organizationID,
+	); err != nil {
+		return fmt.Errorf("copy covered services: %w", err)
+	}
+	return nil
+}
+
+// ListCoveredServiceIDs returns the services a quote covers besides its own
+// lead service.
+func (r *Repository) ListCoveredServiceIDs(ctx context.Context, quoteID, organizationID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT c.lead_service_id
+		FROM RAC_quote_covered_services c
+		JOIN RAC_lead_services ls ON ls.id = c.lead_service_id
+		WHERE c.quote_id = $1 AND c.organization_id = $2
+		ORDER BY ls.created_at, ls.id`, quoteID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list covered services: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan covered service: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	quotesdb "portal_final_backend/internal/quotes/db"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const creditNoteNotFoundMsg = "credit note not found"
+
+// CreditQuantityTolerance absorbs the rounding of quantities stored with three
+// decimals.
+const CreditQuantityTolerance = 0.0005
+
+// CreditNote is a downward correction of an accepted quote. Amounts are negative.
+type CreditNote struct {
+	ID               uuid.UUID
+	OrganizationID   uuid.UUID
+	QuoteID          uuid.UUID
+	Sequence         int
+	CreditNoteNumber string
+	Reason           string
+	SubtotalCents    int64
+	TaxTotalCents    int64
+	TotalCents       int64
+	PDFFileKey       *string
+	CreatedByID      *uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// CreditNoteItem is a single negative line of a credit note.
+type CreditNoteItem struct {
+	ID           uuid.UUID
+	CreditNoteID uuid.UUID
+	QuoteItemID  *uuid.UUID
+	Description  string
+	Quantity     string
+	// QuantityNumeric is the parsed quantity, summed per quote item to cap the
+	// credited quantity.
+	QuantityNumeric float64
+	UnitPriceCents  int64
+	TaxRateBps      int
+	LineTotalCents  int64
+	SortOrder       int
+}
+
+// CreateCreditNoteParams holds the fields for a new credit note. The number is
+// derived from the quote number and the next sequence of the quote.
+type CreateCreditNoteParams struct {
+	OrganizationID uuid.UUID
+	QuoteID        uuid.UUID
+	QuoteNumber    string
+	Reason         string
+	SubtotalCents  int64
+	TaxTotalCents  int64
+	TotalCents     int64
+	CreatedByID    *uuid.UUID
+	Items          []CreditNoteItem
+}
+
+// CreditNoteExport records a credit note pushed to an accounting provider.
+type CreditNoteExport struct {
+	CreditNoteID   uuid.UUID
+	OrganizationID uuid.UUID
+	Provider       string
+	ExternalID     string
+	ExternalURL    *string
+	State          string
+	CreatedAt      time.Time
+}
+
+const creditNoteColumns = `id, organization_id, quote_id, sequence, credit_note_number, reason,
+	subtotal_cents, tax_total_cents, total_cents, pdf_file_key, created_by_id, created_at, updated_at`
+
+func scanCreditNote(row pgx.Row) (*CreditNote, error) {
+	var n CreditNote
+	if err := row.Scan(
+		&n.ID, &n.OrganizationID, &n.QuoteID, &n.Sequence, &n.CreditNoteNumber, &n.Reason,
+		&n.SubtotalCents, &n.TaxTotalCents, &n.TotalCents, &n.PDFFileKey, &n.CreatedByID, &n.CreatedAt, &n.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// CreateCreditNote inserts a credit note and its items. The quote row is locked so
+// concurrent credit notes cannot together credit more than the quote total, nor
+// more than the quoted quantity of an item.
+func (r *Repository) CreateCreditNote(ctx context.Context, params CreateCreditNoteParams) (*CreditNote, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var quoteTotal int64
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT total_cents, status FROM RAC_quotes
+		WHERE id = $1 AND organization_id = $2
+		FOR UPDATE
+	`, params.QuoteID, params.OrganizationID).Scan(&quoteTotal, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound(quoteNotFoundMsg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock quote for credit note: %w", err)
+	}
+	if status != string(quotesdb.QuoteStatusAccepted) {
+		return nil, apperr.Validation("credit notes can only be created for accepted quotes")
+	}
+
+	var sequence int
+	var creditedCents int64
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(sequence), 0) + 1, COALESCE(-SUM(total_cents), 0)
+		FROM RAC_quote_credit_notes
+		WHERE quote_id = $1 AND organization_id = $2
+	`, params.QuoteID, params.OrganizationID).Scan(&sequence, &creditedCents); err != nil {
+		return nil, fmt.Errorf("failed to read existing credit notes: %w", err)
+	}
+	if remaining := quoteTotal - creditedCents; -params.TotalCents > remaining {
+		return nil, apperr.Validation("credit note exceeds the remaining quote total").WithDetails(map[string]any{
+			"quoteTotalCents":     quoteTotal,
+			"creditedCents":       creditedCents,
+			"remainingCents":      remaining,
+			"requestedTotalCents": -params.TotalCents,
+		})
+	}
+
+	if err := checkCreditedQuantities(ctx, tx, params); err != nil {
+		return nil, err
+	}
+
+	note, err := scanCreditNote(tx.QueryRow(ctx, `
+		INSERT INTO RAC_quote_credit_notes (
+			organization_id, quote_id, sequence, credit_note_number, reason,
+			subtotal_cents, tax_total_cents, total_cents, created_by_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+creditNoteColumns,
+		params.OrganizationID, params.QuoteID, sequence, fmt.Sprintf("%s-C%d", params.QuoteNumber, sequence), params.Reason,
+		params.SubtotalCents, params.TaxTotalCents, params.TotalCents, params.CreatedByID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert credit note: %w", err)
+	}
+
+	for i, item := range params.Items {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_quote_credit_note_items (
+				credit_note_id, organization_id, quote_item_id, description, quantity, quantity_numeric,
+				unit_price_cents, tax_rate_bps, line_total_cents, sort_order
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, note.ID, params.OrganizationID, item.QuoteItemID, item.Description, item.Quantity, item.QuantityNumeric,
+			item.UnitPriceCents, item.TaxRateBps, item.LineTotalCents, i); err != nil {
+			return nil, fmt.Errorf("failed to insert credit note item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit credit note: %w", err)
+	}
+	return note, nil
+}
+
+// checkCreditedQuantities rejects lines that, together with the credit notes
+// already issued, credit more of a quote item than was quoted. The caller holds
+// the quote lock, so the earlier credit notes cannot change meanwhile.
+func checkCreditedQuantities(ctx context.Context, tx pgx.Tx, params CreateCreditNoteParams) error {
+	requested := make(map[uuid.UUID]float64)
+	order := make([]uuid.UUID, 0, len(params.Items))
+	for _, item := range params.Items {
+		if item.QuoteItemID == nil {
+			continue
+		}
+		if _, seen := requested[*item.QuoteItemID]; !seen {
+			order = append(order, *item.QuoteItemID)
+		}
+		requested[*item.QuoteItemID] += item.QuantityNumeric
+	}
+
+	for _, quoteItemID := range order {
+		var quoted, credited float64
+		err := tx.QueryRow(ctx, `
+			SELECT qi.quantity_numeric, COALESCE((
+				SELECT SUM(ci.quantity_numeric)
+				FROM RAC_quote_credit_note_items ci
+				JOIN RAC_quote_credit_notes cn ON cn.id = ci.credit_note_id
+				WHERE ci.quote_item_id = qi.id AND cn.quote_id = qi.quote_id
+			), 0)
+			FROM RAC_quote_items qi
+			WHERE qi.id = $1 AND qi.quote_id = $2 AND qi.organization_id = $3
+		`, quoteItemID, params.QuoteID, params.OrganizationID).Scan(&quoted, &credited)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.Validation("credit note item does not belong to this quote").WithDetails(map[string]any{"quoteItemId": quoteItemID})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read credited quantity: %w", err)
+		}
+		if credited+requested[quoteItemID] > quoted+CreditQuantityTolerance {
+			return apperr.Validation("credit note exceeds the remaining quantity of a quote item").WithDetails(map[string]any{
+				"quoteItemId":       quoteItemID,
+				"quotedQuantity":    quoted,
+				"creditedQuantity":  credited,
+				"requestedQuantity": requested[quoteItemID],
+			})
+		}
+	}
+	return nil
+}
+
+// GetCreditNote returns a credit note of a quote.
+func (r *Repository) GetCreditNote(ctx context.Context, id, quoteID, orgID uuid.UUID) (*CreditNote, error) {
+	note, err := scanCreditNote(r.pool.QueryRow(ctx, `
+		SELECT `+creditNoteColumns+`
+		FROM RAC_quote_credit_notes
+		WHERE id = $1 AND quote_id = $2 AND organization_id = $3
+	`, id, quoteID, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound(creditNoteNotFoundMsg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit note: %w", err)
+	}
+	return note, nil
+}
+
+// ListCreditNotes returns the credit notes of a quote in issue order.
+func (r *Repository) ListCreditNotes(ctx context.Context, quoteID, orgID uuid.UUID) ([]CreditNote, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+creditNoteColumns+`
+		FROM RAC_quote_credit_notes
+		WHERE quote_id = $1 AND organization_id = $2
+		ORDER BY sequence
+	`, quoteID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit notes: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]CreditNote, 0)
+	for rows.Next() {
+		note, err := scanCreditNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan credit note: %w", err)
+		}
+		items = append(items, *note)
+	}
+	return items, rows.Err()
+}
+
+// GetCreditNoteItems returns the lines of a credit note.
+func (r *Repository) GetCreditNoteItems(ctx context.Context, creditNoteID, orgID uuid.UUID) ([]CreditNoteItem, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, credit_note_id, quote_item_id, description, quantity,
+			unit_price_cents, tax_rate_bps, line_total_cents, sort_order
+		FROM RAC_quote_credit_note_items
+		WHERE credit_note_id = $1 AND organization_id = $2
+		ORDER BY sort_order
+	`, creditNoteID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit note items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]CreditNoteItem, 0)
+	for rows.Next() {
+		var it CreditNoteItem
+		if err := rows.Scan(&it.ID, &it.CreditNoteID, &it.QuoteItemID, &it.Description, &it.Quantity,
+			&it.UnitPriceCents, &it.TaxRateBps, &it.LineTotalCents, &it.SortOrder); err != nil {
+			return nil, fmt.Errorf("failed to scan credit note item: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// SetCreditNotePDFFileKey stores the object key of the generated credit note PDF.
+func (r *Repository) SetCreditNotePDFFileKey(ctx context.Context, id, orgID uuid.UUID, fileKey string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_quote_credit_notes SET pdf_file_key = $3, updated_at = now()
+		WHERE id = $1 AND organization_id = $2
+	`, id, orgID, fileKey)
+	if err != nil {
+		return fmt.Errorf("failed to set credit note pdf file key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound(creditNoteNotFoundMsg)
+	}
+	return nil
+}
+
+// GetCreditNoteExport returns the export of a credit note to a provider, or nil.
+func (r *Repository) GetCreditNoteExport(ctx context.Context, creditNoteID, orgID uuid.UUID, provider string) (*CreditNoteExport, error) {
+	export := CreditNoteExport{CreditNoteID: creditNoteID, OrganizationID: orgID, Provider: provider}
+	err := r.pool.QueryRow(ctx, `
+		SELECT external_id, external_url, state, created_at
+		FROM RAC_quote_credit_note_exports
+		WHERE credit_note_id = $1 AND organization_id = $2 AND provider = $3
+	`, creditNoteID, orgID, provider).Scan(&export.ExternalID, &export.ExternalURL, &export.State, &export.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit note export: %w", err)
+	}
+	return &export, nil
+}
+
+// CreateCreditNoteExport records a credit note pushed to a provider.
+func (r *Repository) CreateCreditNoteExport(ctx context.Context, export CreditNoteExport) (*CreditNoteExport, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_quote_credit_note_exports (credit_note_id, organization_id, provider, external_id, external_url, state)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, export.CreditNoteID, export.OrganizationID, export.Provider, export.ExternalID, export.ExternalURL, export.State).Scan(&export.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credit note export: %w", err)
+	}
+	return &export, nil
+}
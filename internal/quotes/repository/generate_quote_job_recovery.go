@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GenerateQuoteJobRequest is what an async quote generation job was asked to
+// do, kept so an interrupted job can be queued again.
+type GenerateQuoteJobRequest struct {
+	Prompt          *string
+	ExistingQuoteID *uuid.UUID
+	Force           bool
+}
+
+// GenerateQuoteJobExecution tracks the runs of an async quote generation job.
+type GenerateQuoteJobExecution struct {
+	AttemptCount  int
+	RecoveryCount int
+	HeartbeatAt   *time.Time
+}
+
+// InterruptedGenerateQuoteJob is an active job whose worker stopped reporting.
+type InterruptedGenerateQuoteJob struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	LeadID         uuid.UUID
+	LeadServiceID  uuid.UUID
+	Status         string
+	Request        GenerateQuoteJobRequest
+	Execution      GenerateQuoteJobExecution
+}
+
+// generateQuoteJobLastSeen is the last moment the worker of a job reported.
+const generateQuoteJobLastSeen = `GREATEST(heartbeat_at, updated_at)`
+
+// SaveGenerateQuoteJobRequest stores the request of a job.
+func (r *Repository) SaveGenerateQuoteJobRequest(ctx context.Context, jobID uuid.UUID, req GenerateQuoteJobRequest) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_ai_quote_jobs
+		SET prompt = $2, existing_quote_id = $3, force_regenerate = $4
+		WHERE id = $1`, jobID, req.Prompt, req.ExistingQuoteID, req.Force,
+	); err != nil {
+		return fmt.Errorf("save generate quote job request: %w", err)
+	}
+	return nil
+}
+
+// GetGenerateQuoteJobExecution returns the attempts and heartbeat of a job.
+func (r *Repository) GetGenerateQuoteJobExecution(ctx context.Context, jobID uuid.UUID) (GenerateQuoteJobExecution, error) {
+	var e GenerateQuoteJobExecution
+	err := r.pool.QueryRow(ctx, `
+		SELECT attempt_count, recovery_count, heartbeat_at
+		FROM RAC_ai_quote_jobs
+		WHERE id = $1`, jobID,
+	).Scan(&e.AttemptCount, &e.RecoveryCount, &e.HeartbeatAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return GenerateQuoteJobExecution{}, apperr.NotFound(quoteGenerateJobNotFoundMsg)
+	}
+	if err != nil {
+		return GenerateQuoteJobExecution{}, fmt.Errorf("get generate quote job execution: %w", err)
+	}
+	return e, nil
+}
+
+// StartGenerateQuoteJobAttempt counts a run of a job that was just claimed and
+// records its first heartbeat. It returns the number of the attempt.
+func (r *Repository) StartGenerateQuoteJobAttempt(ctx context.Context, jobID uuid.UUID, now time.Time) (int, error) {
+	var attempt int
+	err := r.pool.QueryRow(ctx, `
+		UPDATE RAC_ai_quote_jobs
+		SET attempt_count = attempt_count + 1, heartbeat_at = $2
+		WHERE id = $1 AND status = 'running'
+		RETURNING attempt_count`, jobID, now,
+	).Scan(&attempt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, apperr.NotFound(quoteGenerateJobNotFoundMsg)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("start generate quote job attempt: %w", err)
+	}
+	return attempt, nil
+}
+
+// TouchGenerateQuoteJobHeartbeat records that the worker of a running job is
+// still alive.
+func (r *Repository) TouchGenerateQuoteJobHeartbeat(ctx context.Context, jobID uuid.UUID, now time.Time) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_ai_quote_jobs SET heartbeat_at = $2
+		WHERE id = $1 AND status = 'running'`, jobID, now,
+	); err != nil {
+		return fmt.Errorf("touch generate quote job heartbeat: %w", err)
+	}
+	return nil
+}
+
+// ListInterruptedGenerateQuoteJobs returns running jobs without a sign of life
+// since runningBefore and pending jobs not picked up since pendingBefore,
+// longest silent first.
+func (r *Repository) ListInterruptedGenerateQuoteJobs(ctx context.Context, runningBefore, pendingBefore time.Time, limit int) ([]InterruptedGenerateQuoteJob, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, organization_id, user_id, lead_id, lead_service_id, status,
+			prompt, existing_quote_id, force_regenerate, attempt_count, recovery_count, heartbeat_at
+		FROM RAC_ai_quote_jobs
+		WHERE (status = 'running' AND `+generateQuoteJobLastSeen+` < $1)
+			OR (status = 'pending' AND `+generateQuoteJobLastSeen+` < $2)
+		ORDER BY `+generateQuoteJobLastSeen+` ASC
+		LIMIT $3`, runningBefore, pendingBefore, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list interrupted generate quote jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]InterruptedGenerateQuoteJob, 0)
+	for rows.Next() {
+		var j InterruptedGenerateQuoteJob
+		if err := rows.Scan(&j.ID, &j.OrganizationID, &j.UserID, &j.LeadID, &j.LeadServiceID, &j.Status,
+			&j.Request.Prompt, &j.Request.ExistingQuoteID, &j.Request.Force,
+			&j.Execution.AttemptCount, &j.Execution.RecoveryCount, &j.Execution.HeartbeatAt); err != nil {
+			return nil, fmt.Errorf("scan interrupted generate quote job: %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// RequeueInterruptedGenerateQuoteJob puts an active job without a sign of
+// life since silentBefore back to pending and counts the recovery. It returns
+// nil when the job reported or finished in the meantime.
+func (r *Repository) RequeueInterruptedGenerateQuoteJob(ctx context.Context, jobID uuid.UUID, silentBefore time.Time, step string, now time.Time) (*GenerateQuoteJob, error) {
+	return r.updateInterruptedGenerateQuoteJob(ctx, `
+		UPDATE RAC_ai_quote_jobs
+		SET status = 'pending', step = $3, progress_percent = 0, error = NULL,
+			recovery_count = recovery_count + 1, heartbeat_at = NULL, updated_at = $4
+		WHERE id = $1 AND status IN ('pending', 'running') AND `+generateQuoteJobLastSeen+` < $2
+		RETURNING `+generateQuoteJobColumns, jobID, silentBefore, step, now)
+}
+
+// FailInterruptedGenerateQuoteJob gives up on an active job without a sign of
+// life since silentBefore. It returns nil when the job reported or finished in
+// the meantime.
+func (r *Repository) FailInterruptedGenerateQuoteJob(ctx context.Context, jobID uuid.UUID, silentBefore time.Time, step string, errText string, now time.Time) (*GenerateQuoteJob, error) {
+	return r.updateInterruptedGenerateQuoteJob(ctx, `
+		UPDATE RAC_ai_quote_jobs
+		SET status = 'failed', step = $3, progress_percent = 100, error = $4,
+			heartbeat_at = NULL, updated_at = $5, finished_at = $5
+		WHERE id = $1 AND status IN ('pending', 'running') AND `+generateQuoteJobLastSeen+` < $2
+		RETURNING `+generateQuoteJobColumns, jobID, silentBefore, step, errText, now)
+}
+
+func (r *Repository) updateInterruptedGenerateQuoteJob(ctx context.Context, query string, args ...any) (*GenerateQuoteJob, error) {
+	var j GenerateQuoteJob
+	err := r.pool.QueryRow(ctx, query, args...).Scan(&j.ID, &j.OrganizationID, &j.UserID, &j.LeadID, &j.LeadServiceID,
+		&j.Status, &j.Step, &j.ProgressPercent, &j.Error, &j.QuoteID, &j.QuoteNumber, &j.ItemCount,
+		&j.StartedAt, &j.UpdatedAt, &j.FinishedAt,
+		&j.FeedbackRating, &j.FeedbackComment, &j.FeedbackSubmittedAt, &j.CancellationReason, &j.ViewedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("recover generate quote job: %w", err)
+	}
+	return &j, nil
+}
+
+const generateQuoteJobColumns = `id, organization_id, user_id, lead_id, lead_service_id,
+	status, step, progress_percent, error,
+	quote_id, quote_number, item_count,
+	started_at, updated_at, finished_at,
+	feedback_rating, feedback_comment, feedback_submitted_at, cancellation_reason, viewed_at`
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Due triggers of a payment installment.
+const (
+	InstallmentDueOnAcceptance        = "on_acceptance"
+	InstallmentDueDaysAfterAcceptance = "days_after_acceptance"
+	InstallmentDueOnDate              = "on_date"
+)
+
+// PaymentInstallment is one installment of a quote payment schedule.
+type PaymentInstallment struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	QuoteID        uuid.UUID
+	Sequence       int
+	Label          string
+	PercentageBps  int
+	DueTrigger     string
+	DueAfterDays   *int
+	DueDate        *time.Time
+	DueNotifiedAt  *time.Time
+	CreatedAt      time.Time
+}
+
+// DueInstallment is an installment of an accepted quote whose due moment has passed
+// and for which no due event was published yet.
+type DueInstallment struct {
+	InstallmentID  uuid.UUID
+	OrganizationID uuid.UUID
+	QuoteID        uuid.UUID
+	DueAt          time.Time
+}
+
+const paymentInstallmentColumns = `id, organization_id, quote_id, sequence, label, percentage_bps,
+	due_trigger, due_after_days, due_date, due_notified_at, created_at`
+
+// ReplacePaymentSchedule swaps the payment schedule of a quote for the given installments.
+// An empty slice removes the schedule.
+func (r *Repository) ReplacePaymentSchedule(ctx context.Context, quoteID, orgID uuid.UUID, installments []PaymentInstallment) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM RAC_quote_payment_installments
+		WHERE quote_id = $1 AND organization_id = $2
+	`, quoteID, orgID); err != nil {
+		return fmt.Errorf("failed to clear payment schedule: %w", err)
+	}
+
+	for i, inst := range installments {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_quote_payment_installments (
+				organization_id, quote_id, sequence, label, percentage_bps,
+				due_trigger, due_after_days, due_date
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, orgID, quoteID, i+1, inst.Label, inst.PercentageBps,
+			inst.DueTrigger, inst.DueAfterDays, inst.DueDate); err != nil {
+			return fmt.Errorf("failed to insert payment installment: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit payment schedule: %w", err)
+	}
+	return nil
+}
+
+// ListPaymentInstallments returns the payment schedule of a quote in sequence order.
+func (r *Repository) ListPaymentInstallments(ctx context.Context, quoteID, orgID uuid.UUID) ([]PaymentInstallment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+paymentInstallmentColumns+`
+		FROM RAC_quote_payment_installments
+		WHERE quote_id = $1 AND organization_id = $2
+		ORDER BY sequence
+	`, quoteID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment installments: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]PaymentInstallment, 0)
+	for rows.Next() {
+		var inst PaymentInstallment
+		if err := rows.Scan(&inst.ID, &inst.OrganizationID, &inst.QuoteID, &inst.Sequence, &inst.Label, &inst.PercentageBps,
+			&inst.DueTrigger, &inst.DueAfterDays, &inst.DueDate, &inst.DueNotifiedAt, &inst.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan payment installment: %w", err)
+		}
+		items = append(items, inst)
+	}
+	return items, rows.Err()
+}
+
+// ListDueInstallments returns installments of accepted quotes that became due at or
+// before now and were not announced yet, oldest first.
+func (r *Repository) ListDueInstallments(ctx context.Context, now time.Time, limit int) ([]DueInstallment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, organization_id, quote_id, due_at
+		FROM (
+			SELECT i.id, i.organization_id, i.quote_id,
+				CASE i.due_trigger
+					WHEN 'on_acceptance' THEN q.accepted_at
+					WHEN 'days_after_acceptance' THEN q.accepted_at + make_interval(days => COALESCE(i.due_after_days, 0))
+					ELSE i.due_date::timestamptz
+				END AS due_at
+			FROM RAC_quote_payment_installments i
+			JOIN RAC_quotes q ON q.id = i.quote_id AND q.organization_id = i.organization_id
+			WHERE q.status = 'Accepted' AND q.accepted_at IS NOT NULL AND i.due_notified_at IS NULL
+		) due
+		WHERE due_at <= $1
+		ORDER BY due_at
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due installments: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]DueInstallment, 0)
+	for rows.Next() {
+		var d DueInstallment
+		if err := rows.Scan(&d.InstallmentID, &d.OrganizationID, &d.QuoteID, &d.DueAt); err != nil {
+			return nil, fmt.Errorf("failed to scan due installment: %w", err)
+		}
+		items = append(items, d)
+	}
+	return items, rows.Err()
+}
+
+// MarkInstallmentDueNotified records that the due event of an installment was published.
+// It reports false when another sweep already claimed the installment.
+func (r *Repository) MarkInstallmentDueNotified(ctx context.Context, installmentID, orgID uuid.UUID, notifiedAt time.Time) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_quote_payment_installments SET due_notified_at = $3
+		WHERE id = $1 AND organization_id = $2 AND due_notified_at IS NULL
+	`, installmentID, orgID, notifiedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark installment due notified: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const quotePaymentNotFoundMsg = "quote payment not found"
+
+// openInstallmentPaymentIndex allows one payment per installment that can still be completed.
+const openInstallmentPaymentIndex = "ux_quote_payments_open_installment"
+
+// Statuses of a quote payment, mirroring the payment statuses reported by Mollie.
+const (
+	PaymentStatusOpen       = "open"
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusPaid       = "paid"
+	PaymentStatusCanceled   = "canceled"
+	PaymentStatusExpired    = "expired"
+	PaymentStatusFailed     = "failed"
+)
+
+// QuotePayment is an online payment created for (part of) a quote.
+type QuotePayment struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	QuoteID        uuid.UUID
+	InstallmentID  *uuid.UUID
+	Provider       string
+	ExternalID     string
+	CheckoutURL    *string
+	AmountCents    int64
+	Currency       string
+	Status         string
+	PaidAt         *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+const quotePaymentColumns = `id, organization_id, quote_id, installment_id, provider, external_id,
+	checkout_url, amount_cents, currency, status, paid_at, created_at, updated_at`
+
+func scanQuotePayment(row pgx.Row) (*QuotePayment, error) {
+	var p QuotePayment
+	if err := row.Scan(&p.ID, &p.OrganizationID, &p.QuoteID, &p.InstallmentID, &p.Provider, &p.ExternalID,
+		&p.CheckoutURL, &p.AmountCents, &p.Currency, &p.Status, &p.PaidAt, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreateQuotePayment stores a payment that was created at the payment provider. It returns a
+// conflict when the installment already has a payment that can still be completed.
+func (r *Repository) CreateQuotePayment(ctx context.Context, payment QuotePayment) (*QuotePayment, error) {
+	created, err := scanQuotePayment(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_quote_payments (
+			organization_id, quote_id, installment_id, provider, external_id,
+			checkout_url, amount_cents, currency, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+quotePaymentColumns,
+		payment.OrganizationID, payment.QuoteID, payment.InstallmentID, payment.Provider, payment.ExternalID,
+		payment.CheckoutURL, payment.AmountCents, payment.Currency, payment.Status))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == openInstallmentPaymentIndex {
+			return nil, apperr.Conflict("the installment already has an open payment")
+		}
+		return nil, fmt.Errorf("failed to create quote payment: %w", err)
+	}
+	return created, nil
+}
+
+// GetQuotePaymentByExternalID looks up a payment by its provider ID. It is not scoped to an
+// organization because provider webhooks only carry the payment ID.
+func (r *Repository) GetQuotePaymentByExternalID(ctx context.Context, provider, externalID string) (*QuotePayment, error) {
+	payment, err := scanQuotePayment(r.pool.QueryRow(ctx, `
+		SELECT `+quotePaymentColumns+`
+		FROM RAC_quote_payments
+		WHERE provider = $1 AND external_id = $2
+	`, provider, externalID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound(quotePaymentNotFoundMsg)
+		}
+		return nil, fmt.Errorf("failed to get quote payment: %w", err)
+	}
+	return payment, nil
+}
+
+// ListQuotePayments returns the payments of a quote, newest first.
+func (r *Repository) ListQuotePayments(ctx context.Context, quoteID, orgID uuid.UUID) ([]QuotePayment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+quotePaymentColumns+`
+		FROM RAC_quote_payments
+		WHERE quote_id = $1 AND organization_id = $2
+		ORDER BY created_at DESC
+	`, quoteID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote payments: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]QuotePayment, 0)
+	for rows.Next() {
+		payment, err := scanQuotePayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote payment: %w", err)
+		}
+		items = append(items, *payment)
+	}
+	return items, rows.Err()
+}
+
+// UpdateQuotePaymentStatus stores the latest provider status of a payment. It reports
+// false when the status was already recorded, so repeated webhooks are no-ops.
+func (r *Repository) UpdateQuotePaymentStatus(ctx context.Context, id, orgID uuid.UUID, status string, paidAt *time.Time) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_quote_payments
+		SET status = $3, paid_at = COALESCE($4, paid_at), updated_at = now()
+		WHERE id = $1 AND organization_id = $2 AND status <> $3
+	`, id, orgID, status, paidAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update quote payment status: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"portal_final_backend/internal/eventoutbox"
+	"portal_final_backend/internal/events"
 	quotesdb "portal_final_backend/internal/quotes/db"
 	"portal_final_backend/platform/apperr"
 
@@ -70,6 +72,10 @@ type Quote struct {
 	PagePerItem                bool       `db:"page_per_item"`
 	CreatedAt                  time.Time  `db:"created_at"`
 	UpdatedAt                  time.Time  `db:"updated_at"`
+	RowVersion                 int64      `db:"row_version"`
+	// ExpectedRowVersion makes UpdateWithItems fail with ErrVersionConflict
+	// when the quote changed since the caller read it.
+	ExpectedRowVersion *int64 `db:"-"`
 }
 
 // QuoteItem is the database model for a quote line item
@@ -114,10 +120,12 @@ type ListParams struct {
 	ValidUntilTo   *time.Time
 	TotalFrom      *int64
 	TotalTo        *int64
-	SortBy         string
-	SortOrder      string
-	Page           int
-	PageSize       int
+	// CustomFields is a JSON object the quote's custom field values must contain.
+	CustomFields []byte
+	SortBy       string
+	SortOrder    string
+	Page         int
+	PageSize     int
 }
 
 // ListResult contains the paginated result of listing quotes
@@ -210,6 +218,12 @@ func (r *Repository) UpdateWithItems(ctx context.Context, quote *Quote, items []
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
+	if quote.ExpectedRowVersion != nil {
+		if err := checkQuoteRowVersion(ctx, tx, quote.ID, quote.OrganizationID, *quote.ExpectedRowVersion); err != nil {
+			return err
+		}
+	}
+
 	qtx := r.queries.WithTx(tx)
 	previousPricingSnapshot, err := r.getLatestPricingSnapshot(ctx, qtx, quote.ID, quote.OrganizationID)
 	if err != nil {
@@ -250,6 +264,9 @@ func (r *Repository) UpdateWithItems(ctx context.Context, quote *Quote, items []
 	if err := r.updateQuoteSubsidyData(ctx, tx, quote.ID, quote.OrganizationID, quote.SubsidyData, quote.UpdatedAt); err != nil {
 		return err
 	}
+	if err := tx.QueryRow(ctx, `SELECT row_version FROM RAC_quotes WHERE id = $1`, quote.ID).Scan(&quote.RowVersion); err != nil {
+		return fmt.Errorf("failed to read quote row version: %w", err)
+	}
 
 	return tx.Commit(ctx)
 }
@@ -564,6 +581,7 @@ func (r *Repository) List(ctx context.Context, params ListParams) (*ListResult,
 		ValidUntilTo:   toPgTimestampPtr(params.ValidUntilTo),
 		TotalFrom:      toPgInt8Ptr(params.TotalFrom),
 		TotalTo:        toPgInt8Ptr(params.TotalTo),
+		CustomFields:   params.CustomFields,
 	}
 
 	total, err := r.queries.CountQuotes(ctx, countParams)
@@ -589,6 +607,7 @@ func (r *Repository) List(ctx context.Context, params ListParams) (*ListResult,
 		ValidUntilTo:   toPgTimestampPtr(params.ValidUntilTo),
 		TotalFrom:      toPgInt8Ptr(params.TotalFrom),
 		TotalTo:        toPgInt8Ptr(params.TotalTo),
+		CustomFields:   params.CustomFields,
 		SortBy:         sortBy,
 		SortOrder:      sortOrder,
 		OffsetCount:    int32(offset),
@@ -612,6 +631,61 @@ func (r *Repository) List(ctx context.Context, params ListParams) (*ListResult,
 	}, nil
 }
 
+// ListByLeadIDs returns the quotes of several leads in one query, newest first per lead.
+// Batched readers use it instead of listing quotes per lead.
+func (r *Repository) ListByLeadIDs(ctx context.Context, leadIDs []uuid.UUID, organizationID uuid.UUID) ([]Quote, error) {
+	if len(leadIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT q.id, q.organization_id, q.lead_id, q.lead_service_id,
+			q.created_by_id, u.first_name, u.last_name, u.email, u.phone,
+			l.consumer_first_name, l.consumer_last_name, l.consumer_phone, l.consumer_email,
+			l.address_street, l.address_house_number, l.address_zip_code, l.address_city,
+			q.quote_number, q.status, q.pricing_mode, q.discount_type, q.discount_value,
+			q.subtotal_cents, q.discount_amount_cents, q.tax_total_cents, q.total_cents,
+			q.valid_until, q.notes, q.created_at, q.updated_at,
+			q.public_token, q.public_token_expires_at, q.preview_token, q.preview_token_expires_at,
+			q.viewed_at, q.accepted_at, q.rejected_at,
+			q.rejection_reason, q.signature_name, q.signature_data, q.signature_ip, q.pdf_file_key,
+			q.financing_disclaimer, q.page_per_item
+		FROM RAC_quotes q
+		LEFT JOIN RAC_leads l ON l.id = q.lead_id AND l.organization_id = q.organization_id
+		LEFT JOIN RAC_users u ON u.id = q.created_by_id
+		WHERE q.lead_id = ANY($1) AND q.organization_id = $2
+		ORDER BY q.lead_id, q.created_at DESC`,
+		toPgUUIDSlice(leadIDs), toPgUUID(organizationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotes by lead: %w", err)
+	}
+	defer rows.Close()
+
+	var items []Quote
+	for rows.Next() {
+		var row quotesdb.ListQuotesRow
+		if err := rows.Scan(
+			&row.ID, &row.OrganizationID, &row.LeadID, &row.LeadServiceID,
+			&row.CreatedByID, &row.FirstName, &row.LastName, &row.Email, &row.Phone,
+			&row.ConsumerFirstName, &row.ConsumerLastName, &row.ConsumerPhone, &row.ConsumerEmail,
+			&row.AddressStreet, &row.AddressHouseNumber, &row.AddressZipCode, &row.AddressCity,
+			&row.QuoteNumber, &row.Status, &row.PricingMode, &row.DiscountType, &row.DiscountValue,
+			&row.SubtotalCents, &row.DiscountAmountCents, &row.TaxTotalCents, &row.TotalCents,
+			&row.ValidUntil, &row.Notes, &row.CreatedAt, &row.UpdatedAt,
+			&row.PublicToken, &row.PublicTokenExpiresAt, &row.PreviewToken, &row.PreviewTokenExpiresAt,
+			&row.ViewedAt, &row.AcceptedAt, &row.RejectedAt,
+			&row.RejectionReason, &row.SignatureName, &row.SignatureData, &row.SignatureIp, &row.PdfFileKey,
+			&row.FinancingDisclaimer, &row.PagePerItem,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		items = append(items, quoteFromListRow(row))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list quotes by lead: %w", err)
+	}
+	return items, nil
+}
+
 // ListPendingApprovals lists draft quotes for dashboard review queue.
 func (r *Repository) ListPendingApprovals(ctx context.Context, orgID uuid.UUID, page int, pageSize int) (*PendingApprovalsResult, error) {
 	total, err := r.queries.CountPendingApprovals(ctx, toPgUUID(orgID))
@@ -903,7 +977,8 @@ func (r *Repository) UpdateQuoteTotals(ctx context.Context, quoteID uuid.UUID, s
 }
 
 // AcceptQuote sets the quote to Accepted status with signature data and records the pricing outcome.
-func (r *Repository) AcceptQuote(ctx context.Context, quote *Quote, signatureName, signatureData, signatureIP string) error {
+// The pending events are written to the domain event outbox in the same transaction.
+func (r *Repository) AcceptQuote(ctx context.Context, quote *Quote, signatureName, signatureData, signatureIP string, pendingEvents ...events.Event) error {
 	now := time.Now()
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
@@ -938,11 +1013,18 @@ func (r *Repository) AcceptQuote(ctx context.Context, quote *Quote, signatureNam
 		return err
 	}
 
+	for _, event := range pendingEvents {
+		if _, err := eventoutbox.Enqueue(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit(ctx)
 }
 
 // RejectQuote sets the quote to Rejected status with an optional reason and records the pricing outcome.
-func (r *Repository) RejectQuote(ctx context.Context, quote *Quote, reason *string) error {
+// The pending events are written to the domain event outbox in the same transaction.
+func (r *Repository) RejectQuote(ctx context.Context, quote *Quote, reason *string, pendingEvents ...events.Event) error {
 	now := time.Now()
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
@@ -974,6 +1056,12 @@ func (r *Repository) RejectQuote(ctx context.Context, quote *Quote, reason *stri
 		return err
 	}
 
+	for _, event := range pendingEvents {
+		if _, err := eventoutbox.Enqueue(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit(ctx)
 }
 
@@ -1686,10 +1774,10 @@ func (r *Repository) loadQuoteLineage(ctx context.Context, quote *Quote) error {
 	var versionNumber int32
 
 	err := r.pool.QueryRow(ctx, `
-		SELECT duplicated_from_quote_id, previous_version_quote_id, version_root_quote_id, version_number, subsidy_payload
+		SELECT duplicated_from_quote_id, previous_version_quote_id, version_root_quote_id, version_number, subsidy_payload, row_version
 		FROM RAC_quotes
 		WHERE id = $1
-	`, quote.ID).Scan(&duplicatedFrom, &previousVersion, &versionRoot, &versionNumber, &quote.SubsidyData)
+	`, quote.ID).Scan(&duplicatedFrom, &previousVersion, &versionRoot, &versionNumber, &quote.SubsidyData, &quote.RowVersion)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return apperr.NotFound(quoteNotFoundMsg)
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrVersionConflict is returned when a quote changed after the version the
+// caller based its update on. A trigger bumps row_version on every update.
+var ErrVersionConflict = errors.New("quote version conflict")
+
+// checkQuoteRowVersion locks the quote for the rest of the transaction and
+// compares its version with the expected one.
+func checkQuoteRowVersion(ctx context.Context, tx pgx.Tx, quoteID, organizationID uuid.UUID, expected int64) error {
+	var current int64
+	err := tx.QueryRow(ctx, `
+		SELECT row_version FROM RAC_quotes
+		WHERE id = $1 AND organization_id = $2
+		FOR UPDATE`, quoteID, organizationID,
+	).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.NotFound(quoteNotFoundMsg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock quote row version: %w", err)
+	}
+	if current != expected {
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	quoteTemplateNotFoundMsg  = "quote template not found"
+	quoteTemplateDuplicateMsg = "a quote template with this name already exists"
+)
+
+// QuoteTemplate is a named, reusable bundle of quote line items.
+type QuoteTemplate struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	Description    *string
+	PricingMode    string
+	Notes          *string
+	CreatedByID    *uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Stats          QuoteTemplateStats
+}
+
+// QuoteTemplateStats summarizes how often a template was used and how the
+// resulting quotes ended up.
+type QuoteTemplateStats struct {
+	UsageCount    int
+	AcceptedCount int
+	LastUsedAt    *time.Time
+}
+
+// QuoteTemplateItem is a line of a quote template. Quantity may contain
+// "{{key}}" placeholders that are filled in when the template is used.
+type QuoteTemplateItem struct {
+	ID               uuid.UUID
+	TemplateID       uuid.UUID
+	Title            string
+	Description      string
+	Quantity         string
+	UnitPriceCents   int64
+	TaxRateBps       int
+	IsOptional       bool
+	IsSelected       bool
+	CatalogProductID *uuid.UUID
+	SortOrder        int
+}
+
+// SaveQuoteTemplateParams holds the fields of a new or updated template. The
+// items replace any existing items of the template.
+type SaveQuoteTemplateParams struct {
+	OrganizationID uuid.UUID
+	Name           string
+	Description    *string
+	PricingMode    string
+	Notes          *string
+	CreatedByID    *uuid.UUID
+	Items          []QuoteTemplateItem
+}
+
+const quoteTemplateColumns = `t.id, t.organization_id, t.name, t.description, t.pricing_mode, t.notes,
+	t.created_by_id, t.created_at, t.updated_at,
+	COALESCE(s.usage_count, 0), COALESCE(s.accepted_count, 0), s.last_used_at`
+
+const quoteTemplateFrom = `
+	FROM RAC_quote_templates t
+	LEFT JOIN LATERAL (
+		SELECT COUNT(*)::int AS usage_count,
+			(COUNT(*) FILTER (WHERE q.status = 'Accepted'))::int AS accepted_count,
+			MAX(u.created_at) AS last_used_at
+		FROM RAC_quote_template_usages u
+		JOIN RAC_quotes q ON q.id = u.quote_id
+		WHERE u.template_id = t.id
+	) s ON true`
+
+func scanQuoteTemplate(row pgx.Row) (*QuoteTemplate, error) {
+	var t QuoteTemplate
+	if err := row.Scan(
+		&t.ID, &t.OrganizationID, &t.Name, &t.Description, &t.PricingMode, &t.Notes,
+		&t.CreatedByID, &t.CreatedAt, &t.UpdatedAt,
+		&t.Stats.UsageCount, &t.Stats.AcceptedCount, &t.Stats.LastUsedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListQuoteTemplates returns the quote templates of an organization by name,
+// including their usage statistics.
+func (r *Repository) ListQuoteTemplates(ctx context.Context, orgID uuid.UUID) ([]QuoteTemplate, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+quoteTemplateColumns+quoteTemplateFrom+`
+		WHERE t.organization_id = $1
+		ORDER BY lower(t.name)
+	`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]QuoteTemplate, 0)
+	for rows.Next() {
+		t, err := scanQuoteTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote template: %w", err)
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+// GetQuoteTemplate returns a quote template of an organization.
+func (r *Repository) GetQuoteTemplate(ctx context.Context, id, orgID uuid.UUID) (*QuoteTemplate, error) {
+	t, err := scanQuoteTemplate(r.pool.QueryRow(ctx, `
+		SELECT `+quoteTemplateColumns+quoteTemplateFrom+`
+		WHERE t.id = $1 AND t.organization_id = $2
+	`, id, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound(quoteTemplateNotFoundMsg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote template: %w", err)
+	}
+	return t, nil
+}
+
+// GetQuoteTemplateItems returns the lines of a quote template in order.
+func (r *Repository) GetQuoteTemplateItems(ctx context.Context, templateID, orgID uuid.UUID) ([]QuoteTemplateItem, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, template_id, title, description, quantity, unit_price_cents, tax_rate_bps,
+			is_optional, is_selected, catalog_product_id, sort_order
+		FROM RAC_quote_template_items
+		WHERE template_id = $1 AND organization_id = $2
+		ORDER BY sort_order
+	`, templateID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote template items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]QuoteTemplateItem, 0)
+	for rows.Next() {
+		var it QuoteTemplateItem
+		if err := rows.Scan(&it.ID, &it.TemplateID, &it.Title, &it.Description, &it.Quantity, &it.UnitPriceCents,
+			&it.TaxRateBps, &it.IsOptional, &it.IsSelected, &it.CatalogProductID, &it.SortOrder); err != nil {
+			return nil, fmt.Errorf("failed to scan quote template item: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// CreateQuoteTemplate inserts a quote template with its items.
+func (r *Repository) CreateQuoteTemplate(ctx context.Context, params SaveQuoteTemplateParams) (uuid.UUID, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO RAC_quote_templates (organization_id, name, description, pricing_mode, notes, created_by_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, params.OrganizationID, params.Name, params.Description, params.PricingMode, params.Notes, params.CreatedByID).Scan(&id)
+	if err != nil {
+		return uuid.Nil, quoteTemplateWriteError("create", err)
+	}
+	if err := insertQuoteTemplateItems(ctx, tx, id, params.OrganizationID, params.Items); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit quote template: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateQuoteTemplate replaces the fields and items of a quote template.
+func (r *Repository) UpdateQuoteTemplate(ctx context.Context, id uuid.UUID, params SaveQuoteTemplateParams) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE RAC_quote_templates
+		SET name = $3, description = $4, pricing_mode = $5, notes = $6, updated_at = now()
+		WHERE id = $1 AND organization_id = $2
+	`, id, params.OrganizationID, params.Name, params.Description, params.PricingMode, params.Notes)
+	if err != nil {
+		return quoteTemplateWriteError("update", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound(quoteTemplateNotFoundMsg)
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM RAC_quote_template_items WHERE template_id = $1 AND organization_id = $2
+	`, id, params.OrganizationID); err != nil {
+		return fmt.Errorf("failed to clear quote template items: %w", err)
+	}
+	if err := insertQuoteTemplateItems(ctx, tx, id, params.OrganizationID, params.Items); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit quote template: %w", err)
+	}
+	return nil
+}
+
+// DeleteQuoteTemplate removes a quote template. Quotes created from it are kept.
+func (r *Repository) DeleteQuoteTemplate(ctx context.Context, id, orgID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_quote_templates WHERE id = $1 AND organization_id = $2
+	`, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete quote template: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound(quoteTemplateNotFoundMsg)
+	}
+	return nil
+}
+
+// RecordQuoteTemplateUsage records that a quote was created from a template.
+func (r *Repository) RecordQuoteTemplateUsage(ctx context.Context, templateID, quoteID, orgID uuid.UUID, usedByID *uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_quote_template_usages (template_id, organization_id, quote_id, used_by_id)
+		VALUES ($1, $2, $3, $4)
+	`, templateID, orgID, quoteID, usedByID); err != nil {
+		return fmt.Errorf("failed to record quote template usage: %w", err)
+	}
+	return nil
+}
+
+func insertQuoteTemplateItems(ctx context.Context, tx pgx.Tx, templateID, orgID uuid.UUID, items []QuoteTemplateItem) error {
+	for i, it := range items {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_quote_template_items (
+				template_id, organization_id, title, description, quantity, unit_price_cents,
+				tax_rate_bps, is_optional, is_selected, catalog_product_id, sort_order
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`, templateID, orgID, it.Title, it.Description, it.Quantity, it.UnitPriceCents,
+			it.TaxRateBps, it.IsOptional, it.IsSelected, it.CatalogProductID, i); err != nil {
+			return fmt.Errorf("failed to insert quote template item: %w", err)
+		}
+	}
+	return nil
+}
+
+func quoteTemplateWriteError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return apperr.Conflict(quoteTemplateDuplicateMsg)
+	}
+	return fmt.Errorf("failed to %s quote template: %w", op, err)
+}
@@ -0,0 +1,505 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	termsDocumentNotFoundMsg  = "terms document not found"
+	termsDocumentDuplicateMsg = "a terms document with this name already exists"
+	termsWorkflowNotFoundMsg  = "workflow not found"
+)
+
+// TermsDocument is a named terms & conditions document of an organization.
+type TermsDocument struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	IsDefault      bool
+	CreatedBy      *uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	// ServiceTypes and WorkflowIDs are the overrides that select the document.
+	ServiceTypes []string
+	WorkflowIDs  []uuid.UUID
+}
+
+// TermsVersion is an immutable text of a terms document. It is in effect from
+// EffectiveFrom until a later version takes effect.
+type TermsVersion struct {
+	ID             uuid.UUID
+	DocumentID     uuid.UUID
+	OrganizationID uuid.UUID
+	VersionNumber  int
+	Content        string
+	ChangeNote     *string
+	EffectiveFrom  time.Time
+	CreatedBy      *uuid.UUID
+	CreatedAt      time.Time
+}
+
+// QuoteTerms is the terms version that applies to a quote.
+type QuoteTerms struct {
+	DocumentID   uuid.UUID
+	DocumentName string
+	Version      TermsVersion
+}
+
+// CreateTermsDocumentParams holds a new terms document and its first version.
+type CreateTermsDocumentParams struct {
+	OrganizationID uuid.UUID
+	Name           string
+	IsDefault      bool
+	CreatedBy      *uuid.UUID
+	FirstVersion   AddTermsVersionParams
+}
+
+// AddTermsVersionParams holds a new version of a terms document.
+type AddTermsVersionParams struct {
+	Content       string
+	ChangeNote    *string
+	EffectiveFrom time.Time
+	CreatedBy     *uuid.UUID
+}
+
+const termsVersionColumns = `v.id, v.document_id, v.organization_id, v.version_number, v.content,
+	v.change_note, v.effective_from, v.created_by, v.created_at`
+
+func scanTermsVersion(row pgx.Row, extra ...any) (*TermsVersion, error) {
+	var v TermsVersion
+	dest := append([]any{&v.ID, &v.DocumentID, &v.OrganizationID, &v.VersionNumber, &v.Content,
+		&v.ChangeNote, &v.EffectiveFrom, &v.CreatedBy, &v.CreatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListTermsDocuments returns the active terms documents of an organization
+// with their overrides, the default document first.
+func (r *Repository) ListTermsDocuments(ctx context.Context, orgID uuid.UUID) ([]TermsDocument, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, organization_id, name, is_default, created_by, created_at, updated_at
+		FROM RAC_quote_terms_documents
+		WHERE organization_id = $1 AND archived_at IS NULL
+		ORDER BY is_default DESC, lower(name)
+	`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list terms documents: %w", err)
+	}
+	defer rows.Close()
+
+	docs := make([]TermsDocument, 0)
+	for rows.Next() {
+		var d TermsDocument
+		if err := rows.Scan(&d.ID, &d.OrganizationID, &d.Name, &d.IsDefault, &d.CreatedBy, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan terms document: %w", err)
+		}
+		docs = append(docs, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadTermsOverrides(ctx, orgID, docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// GetTermsDocument returns an active terms document with its overrides.
+func (r *Repository) GetTermsDocument(ctx context.Context, id, orgID uuid.UUID) (*TermsDocument, error) {
+	var d TermsDocument
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, organization_id, name, is_default, created_by, created_at, updated_at
+		FROM RAC_quote_terms_documents
+		WHERE id = $1 AND organization_id = $2 AND archived_at IS NULL
+	`, id, orgID).Scan(&d.ID, &d.OrganizationID, &d.Name, &d.IsDefault, &d.CreatedBy, &d.CreatedAt, &d.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound(termsDocumentNotFoundMsg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get terms document: %w", err)
+	}
+	docs := []TermsDocument{d}
+	if err := r.loadTermsOverrides(ctx, orgID, docs); err != nil {
+		return nil, err
+	}
+	return &docs[0], nil
+}
+
+func (r *Repository) loadTermsOverrides(ctx context.Context, orgID uuid.UUID, docs []TermsDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	index := make(map[uuid.UUID]int, len(docs))
+	for i := range docs {
+		docs[i].ServiceTypes = make([]string, 0)
+		docs[i].WorkflowIDs = make([]uuid.UUID, 0)
+		index[docs[i].ID] = i
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT document_id, service_type, workflow_id
+		FROM RAC_quote_terms_overrides
+		WHERE organization_id = $1
+		ORDER BY lower(service_type), created_at
+	`, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to list terms overrides: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var documentID uuid.UUID
+		var serviceType *string
+		var workflowID *uuid.UUID
+		if err := rows.Scan(&documentID, &serviceType, &workflowID); err != nil {
+			return fmt.Errorf("failed to scan terms override: %w", err)
+		}
+		i, ok := index[documentID]
+		if !ok {
+			continue
+		}
+		if serviceType != nil {
+			docs[i].ServiceTypes = append(docs[i].ServiceTypes, *serviceType)
+		}
+		if workflowID != nil {
+			docs[i].WorkflowIDs = append(docs[i].WorkflowIDs, *workflowID)
+		}
+	}
+	return rows.Err()
+}
+
+// CreateTermsDocument inserts a terms document with its first version. A new
+// default document replaces the previous default.
+func (r *Repository) CreateTermsDocument(ctx context.Context, params CreateTermsDocumentParams) (uuid.UUID, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if params.IsDefault {
+		if err := clearDefaultTermsDocument(ctx, tx, params.OrganizationID); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO RAC_quote_terms_documents (organization_id, name, is_default, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, params.OrganizationID, params.Name, params.IsDefault, params.CreatedBy).Scan(&id)
+	if err != nil {
+		return uuid.Nil, termsDocumentWriteError("create", err)
+	}
+	if _, err := insertTermsVersion(ctx, tx, id, params.OrganizationID, params.FirstVersion); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit terms document: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateTermsDocument renames a terms document and sets whether it is the
+// default of its organization.
+func (r *Repository) UpdateTermsDocument(ctx context.Context, id, orgID uuid.UUID, name string, isDefault bool) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if isDefault {
+		if err := clearDefaultTermsDocument(ctx, tx, orgID); err != nil {
+			return err
+		}
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE RAC_quote_terms_documents
+		SET name = $3, is_default = $4, updated_at = now()
+		WHERE id = $1 AND organization_id = $2 AND archived_at IS NULL
+	`, id, orgID, name, isDefault)
+	if err != nil {
+		return termsDocumentWriteError("update", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound(termsDocumentNotFoundMsg)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit terms document: %w", err)
+	}
+	return nil
+}
+
+// ArchiveTermsDocument retires a terms document and drops its overrides.
+// Versions stay, so quotes sent with them keep their terms.
+func (r *Repository) ArchiveTermsDocument(ctx context.Context, id, orgID uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE RAC_quote_terms_documents
+		SET archived_at = now(), is_default = false, updated_at = now()
+		WHERE id = $1 AND organization_id = $2 AND archived_at IS NULL
+	`, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to archive terms document: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound(termsDocumentNotFoundMsg)
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM RAC_quote_terms_overrides WHERE document_id = $1 AND organization_id = $2
+	`, id, orgID); err != nil {
+		return fmt.Errorf("failed to clear terms overrides: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit terms document: %w", err)
+	}
+	return nil
+}
+
+// AddTermsVersion appends a version to an active terms document.
+func (r *Repository) AddTermsVersion(ctx context.Context, documentID, orgID uuid.UUID, params AddTermsVersionParams) (*TermsVersion, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Locking the document serializes version numbers.
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT true FROM RAC_quote_terms_documents
+		WHERE id = $1 AND organization_id = $2 AND archived_at IS NULL
+		FOR UPDATE
+	`, documentID, orgID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound(termsDocumentNotFoundMsg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock terms document: %w", err)
+	}
+
+	version, err := insertTermsVersion(ctx, tx, documentID, orgID, params)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_quote_terms_documents SET updated_at = now() WHERE id = $1
+	`, documentID); err != nil {
+		return nil, fmt.Errorf("failed to touch terms document: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit terms version: %w", err)
+	}
+	return version, nil
+}
+
+// ListTermsVersions returns the version history of a terms document, newest first.
+func (r *Repository) ListTermsVersions(ctx context.Context, documentID, orgID uuid.UUID) ([]TermsVersion, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+termsVersionColumns+`
+		FROM RAC_quote_terms_versions v
+		WHERE v.document_id = $1 AND v.organization_id = $2
+		ORDER BY v.version_number DESC
+	`, documentID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list terms versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]TermsVersion, 0)
+	for rows.Next() {
+		v, err := scanTermsVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan terms version: %w", err)
+		}
+		versions = append(versions, *v)
+	}
+	return versions, rows.Err()
+}
+
+// ReplaceTermsOverrides makes a terms document the one for the given service
+// types and workflows, taking them over from other documents.
+func (r *Repository) ReplaceTermsOverrides(ctx context.Context, documentID, orgID uuid.UUID, serviceTypes []string, workflowIDs []uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf(errBeginTransactionFmt, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT true FROM RAC_quote_terms_documents
+		WHERE id = $1 AND organization_id = $2 AND archived_at IS NULL
+		FOR UPDATE
+	`, documentID, orgID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.NotFound(termsDocumentNotFoundMsg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock terms document: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM RAC_quote_terms_overrides
+		WHERE organization_id = $1
+			AND (document_id = $2
+				OR lower(service_type) IN (SELECT lower(t) FROM unnest($3::text[]) t)
+				OR workflow_id = ANY($4::uuid[]))
+	`, orgID, documentID, serviceTypes, workflowIDs); err != nil {
+		return fmt.Errorf("failed to clear terms overrides: %w", err)
+	}
+
+	for _, serviceType := range serviceTypes {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_quote_terms_overrides (organization_id, document_id, service_type)
+			VALUES ($1, $2, $3)
+		`, orgID, documentID, serviceType); err != nil {
+			return fmt.Errorf("failed to insert terms override: %w", err)
+		}
+	}
+	for _, workflowID := range workflowIDs {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO RAC_quote_terms_overrides (organization_id, document_id, workflow_id)
+			SELECT $1, $2, w.id FROM RAC_workflows w
+			WHERE w.id = $3 AND w.organization_id = $1
+		`, orgID, documentID, workflowID)
+		if err != nil {
+			return fmt.Errorf("failed to insert terms override: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return apperr.NotFound(termsWorkflowNotFoundMsg)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit terms overrides: %w", err)
+	}
+	return nil
+}
+
+// ResolveTermsVersion returns the terms version in effect at the given moment
+// for a quote of the service type and workflow: a workflow override wins over
+// a service type override, which wins over the default document. Documents
+// without a version in effect yet are skipped. It returns nil when no terms
+// apply.
+func (r *Repository) ResolveTermsVersion(ctx context.Context, orgID uuid.UUID, serviceType string, workflowID *uuid.UUID, at time.Time) (*QuoteTerms, error) {
+	var terms QuoteTerms
+	version, err := scanTermsVersion(r.pool.QueryRow(ctx, `
+		SELECT `+termsVersionColumns+`, d.name
+		FROM RAC_quote_terms_documents d
+		LEFT JOIN RAC_quote_terms_overrides o ON o.document_id = d.id
+			AND (o.workflow_id = $3 OR lower(o.service_type) = lower($2))
+		JOIN LATERAL (
+			SELECT * FROM RAC_quote_terms_versions tv
+			WHERE tv.document_id = d.id AND tv.effective_from <= $4
+			ORDER BY tv.effective_from DESC, tv.version_number DESC
+			LIMIT 1
+		) v ON true
+		WHERE d.organization_id = $1 AND d.archived_at IS NULL
+			AND (o.id IS NOT NULL OR d.is_default)
+		ORDER BY CASE
+			WHEN o.workflow_id IS NOT NULL THEN 1
+			WHEN o.service_type IS NOT NULL THEN 2
+			ELSE 3
+		END
+		LIMIT 1
+	`, orgID, serviceType, workflowID, at), &terms.DocumentName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve terms version: %w", err)
+	}
+	terms.DocumentID = version.DocumentID
+	terms.Version = *version
+	return &terms, nil
+}
+
+// GetQuoteTermsVersion returns the terms version a quote was sent with, or nil
+// when none was recorded.
+func (r *Repository) GetQuoteTermsVersion(ctx context.Context, quoteID, orgID uuid.UUID) (*QuoteTerms, error) {
+	var terms QuoteTerms
+	version, err := scanTermsVersion(r.pool.QueryRow(ctx, `
+		SELECT `+termsVersionColumns+`, d.name
+		FROM RAC_quotes q
+		JOIN RAC_quote_terms_versions v ON v.id = q.terms_version_id
+		JOIN RAC_quote_terms_documents d ON d.id = v.document_id
+		WHERE q.id = $1 AND q.organization_id = $2
+	`, quoteID, orgID), &terms.DocumentName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote terms version: %w", err)
+	}
+	terms.DocumentID = version.DocumentID
+	terms.Version = *version
+	return &terms, nil
+}
+
+// PinQuoteTermsVersion records the terms version a quote is sent with. A
+// version recorded at an earlier send is kept.
+func (r *Repository) PinQuoteTermsVersion(ctx context.Context, quoteID, orgID, versionID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `
+		UPDATE RAC_quotes SET terms_version_id = COALESCE(terms_version_id, $3)
+		WHERE id = $1 AND organization_id = $2
+	`, quoteID, orgID, versionID); err != nil {
+		return fmt.Errorf("failed to pin quote terms version: %w", err)
+	}
+	return nil
+}
+
+func clearDefaultTermsDocument(ctx context.Context, tx pgx.Tx, orgID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_quote_terms_documents SET is_default = false, updated_at = now()
+		WHERE organization_id = $1 AND is_default
+	`, orgID); err != nil {
+		return fmt.Errorf("failed to clear default terms document: %w", err)
+	}
+	return nil
+}
+
+func insertTermsVersion(ctx context.Context, tx pgx.Tx, documentID, orgID uuid.UUID, params AddTermsVersionParams) (*TermsVersion, error) {
+	version, err := scanTermsVersion(tx.QueryRow(ctx, `
+		INSERT INTO RAC_quote_terms_versions AS v (
+			document_id, organization_id, version_number, content, change_note, effective_from, created_by
+		)
+		SELECT $1, $2, COALESCE(MAX(version_number), 0) + 1, $3, $4, $5, $6
+		FROM RAC_quote_terms_versions WHERE document_id = $1
+		RETURNING `+termsVersionColumns,
+		documentID, orgID, params.Content, params.ChangeNote, params.EffectiveFrom, params.CreatedBy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert terms version: %w", err)
+	}
+	return version, nil
+}
+
+func termsDocumentWriteError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return apperr.Conflict(termsDocumentDuplicateMsg)
+	}
+	return fmt.Errorf("failed to %s terms document: %w", op, err)
+}
@@ -9,6 +9,7 @@ import (
 	leadstransport "portal_final_backend/internal/leads/transport"
 	"portal_final_backend/internal/notification/sse"
 	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
 	"portal_final_backend/internal/scheduler"
 
 	"github.com/google/uuid"
@@ -51,6 +52,8 @@ type TimelineEventParams struct {
 	Summary        *string
 	Metadata       map[string]any
 	Visibility     string
+	// DedupKey makes the write idempotent per lead, for writes that retries repeat.
+	DedupKey string
 }
 
 // QuoteContactData holds the consumer/organization/agent info needed for quote workflows.
@@ -73,9 +76,18 @@ type QuoteContactReader interface {
 	GetQuoteContactData(ctx context.Context, leadID uuid.UUID, organizationID uuid.UUID) (QuoteContactData, error)
 }
 
-// QuoteTermsResolver resolves effective quote terms (payment + validity days).
+// QuoteTermsResolver resolves effective quote terms (payment + validity days)
+// and the service type and workflow that select the terms document.
 type QuoteTermsResolver interface {
 	ResolveQuoteTerms(ctx context.Context, organizationID uuid.UUID, leadID uuid.UUID, leadServiceID *uuid.UUID) (paymentDays int, validDays int, err error)
+	ResolveQuoteTermsScope(ctx context.Context, organizationID uuid.UUID, leadID uuid.UUID, leadServiceID *uuid.UUID) (QuoteTermsScope, error)
+}
+
+// QuoteTermsScope is what selects the terms document of a quote. Both fields
+// are empty when the lead has no service or workflow.
+type QuoteTermsScope struct {
+	ServiceType string
+	WorkflowID  *uuid.UUID
 }
 
 // QuotePromptGenerator generates quotes from prompt input.
@@ -95,6 +107,11 @@ type LogoPresigner interface {
 	GenerateLogoURL(ctx context.Context, fileKey string) (string, error)
 }
 
+// BrandingReader provides the brand colors shown on public quote pages.
+type BrandingReader interface {
+	GetPublicBranding(ctx context.Context, organizationID uuid.UUID) (transport.PublicBrandingResponse, error)
+}
+
 type LeadTransferCreator interface {
 	Create(ctx context.Context, req leadstransport.CreateLeadRequest, tenantID uuid.UUID) (leadstransport.LeadResponse, error)
 }
@@ -145,10 +162,13 @@ type Service struct {
 	jobQueue      GenerateQuoteJobQueue
 	feedbackQueue HumanFeedbackMemoryQueue
 	moneybird     *moneybirdConfig
+	mollie        *mollieConfig
 	logoPresigner LogoPresigner
+	branding      BrandingReader
 	leadCreator   LeadTransferCreator
 	leadRepo      LeadTransferRepository
 	replyDrafter  QuoteAnnotationReplyDraftSuggester
+	previews      AttachmentPreviewPruner
 }
 
 // GenerateQuoteJobQueue enqueues async quote generation tasks.
@@ -194,6 +214,11 @@ type GenerateQuoteJob struct {
 	StartedAt          time.Time
 	UpdatedAt          time.Time
 	FinishedAt         *time.Time
+	// Attempt is the run of the job; RecoveryCount how often it was queued
+	// again after its worker was interrupted.
+	Attempt       int
+	RecoveryCount int
+	HeartbeatAt   *time.Time
 }
 
 // DraftQuoteParams contains the data needed to create or update an AI-drafted quote.
@@ -313,6 +338,7 @@ func (s *Service) SetHumanFeedbackMemoryQueue(queue HumanFeedbackMemoryQueue) {
 	s.feedbackQueue = queue
 }
 func (s *Service) SetLogoPresigner(lp LogoPresigner)                     { s.logoPresigner = lp }
+func (s *Service) SetBrandingReader(reader BrandingReader)               { s.branding = reader }
 func (s *Service) SetLeadTransferCreator(creator LeadTransferCreator)    { s.leadCreator = creator }
 func (s *Service) SetLeadTransferRepository(repo LeadTransferRepository) { s.leadRepo = repo }
 func (s *Service) SetQuoteAnnotationReplyDraftSuggester(drafter QuoteAnnotationReplyDraftSuggester) {
@@ -57,6 +57,11 @@ func (s *Service) RecordQuoteAIReview(ctx context.Context, params RecordQuoteAIR
 		CreatedAt:      createdAt,
 	})
 
+	// Auto-send is best effort; an unscheduled quote simply waits for an agent.
+	if review.Decision == QuoteAIReviewDecisionApproved {
+		_ = s.scheduleQuoteAutoSend(ctx, params.QuoteID, params.OrganizationID, review.ID, createdAt)
+	}
+
 	return &QuoteAIReviewResult{
 		ReviewID:     review.ID,
 		QuoteID:      review.QuoteID,
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	approvalReasonThreshold = "threshold"
+	approvalReasonRole      = "role"
+
+	approvalBypassRole = "admin"
+)
+
+// evaluateApprovalRequirement reports whether a quote with the given total, sent by an actor with the
+// given roles, must be approved first, and why. Admins are never gated.
+func evaluateApprovalRequirement(policy *repository.ApprovalPolicy, totalCents int64, actorRoles []string) (bool, []string) {
+	if policy == nil || !policy.Enabled || hasRole(actorRoles, approvalBypassRole) {
+		return false, nil
+	}
+
+	reasons := make([]string, 0, 2)
+	if policy.ThresholdCents != nil && totalCents > *policy.ThresholdCents {
+		reasons = append(reasons, approvalReasonThreshold)
+	}
+	for _, role := range policy.RequireForRoles {
+		if hasRole(actorRoles, role) {
+			reasons = append(reasons, approvalReasonRole)
+			break
+		}
+	}
+	return len(reasons) > 0, reasons
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if strings.EqualFold(strings.TrimSpace(r), role) {
+			return true
+		}
+	}
+	return false
+}
+
+// approvalCoversQuote reports whether an approval still applies to the quote. An approval is
+// invalidated when the line items changed since it was requested or the total grew above the
+// approved amount.
+func approvalCoversQuote(approval *repository.QuoteApproval, totalCents int64, itemsHash string) bool {
+	return approval != nil && approval.Status == repository.ApprovalStatusApproved &&
+		approval.QuoteItemsHash != nil && *approval.QuoteItemsHash == itemsHash &&
+		totalCents <= approval.QuoteTotalCents
+}
+
+// quoteItemsHash fingerprints the line items of a quote in their display order. Item IDs are
+// left out because saving a quote replaces its items.
+func quoteItemsHash(items []repository.QuoteItem) string {
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b repository.QuoteItem) int { return a.SortOrder - b.SortOrder })
+
+	h := sha256.New()
+	for _, item := range sorted {
+		catalogProductID := ""
+		if item.CatalogProductID != nil {
+			catalogProductID = item.CatalogProductID.String()
+		}
+		for _, field := range []string{
+			item.Title, item.Description, item.Quantity,
+			strconv.FormatInt(item.UnitPriceCents, 10), strconv.Itoa(item.TaxRateBps),
+			strconv.FormatBool(item.IsOptional), strconv.FormatBool(item.IsSelected), catalogProductID,
+		} {
+			// Length-prefix every field so no two item lists share an encoding.
+			fmt.Fprintf(h, "%d:%s", len(field), field)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Service) currentQuoteItemsHash(ctx context.Context, quoteID, tenantID uuid.UUID) (string, error) {
+	items, err := s.repo.GetItemsByQuoteID(ctx, quoteID, tenantID)
+	if err != nil {
+		return "", err
+	}
+	return quoteItemsHash(items), nil
+}
+
+// autoSendRequiresApproval applies the approval policy to an automatic send. No user is behind
+// it whose role could exempt it, so any role rule of the policy requires approval.
+func autoSendRequiresApproval(policy *repository.ApprovalPolicy, totalCents int64) bool {
+	if required, _ := evaluateApprovalRequirement(policy, totalCents, nil); required {
+		return true
+	}
+	return policy != nil && policy.Enabled && len(policy.RequireForRoles) > 0
+}
+
+// ensureQuoteApproved enforces the organization's approval policy before a quote is sent. When approval
+// is required but missing, a pending request is opened on behalf of the sender.
+func (s *Service) ensureQuoteApproved(ctx context.Context, quote *repository.Quote, tenantID, agentID uuid.UUID, agentRoles []string) error {
+	policy, err := s.repo.GetApprovalPolicy(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	required, reasons := evaluateApprovalRequirement(policy, quote.TotalCents, agentRoles)
+	if !required {
+		return nil
+	}
+
+	approved, err := s.repo.GetLatestApprovedQuoteApproval(ctx, quote.ID, tenantID)
+	if err != nil {
+		return err
+	}
+	itemsHash, err := s.currentQuoteItemsHash(ctx, quote.ID, tenantID)
+	if err != nil {
+		return err
+	}
+	if approvalCoversQuote(approved, quote.TotalCents, itemsHash) {
+		return nil
+	}
+
+	pending, err := s.repo.GetPendingQuoteApproval(ctx, quote.ID, tenantID)
+	if err != nil {
+		return err
+	}
+	if pending == nil {
+		if _, err := s.createApprovalRequest(ctx, quote, itemsHash, tenantID, agentID, reasons, ""); err != nil {
+			return err
+		}
+	}
+	return apperr.Conflict("quote requires approval before it can be sent").WithDetails(map[string]any{"reasons": reasons})
+}
+
+// RequestApproval submits a quote for admin approval ahead of sending it.
+func (s *Service) RequestApproval(ctx context.Context, id, tenantID, agentID uuid.UUID, agentRoles []string, req transport.RequestQuoteApprovalRequest) (*transport.QuoteApprovalResponse, error) {
+	quote, err := s.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSendableQuoteStatus(quote.Status); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.repo.GetApprovalPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	_, reasons := evaluateApprovalRequirement(policy, quote.TotalCents, agentRoles)
+	itemsHash, err := s.currentQuoteItemsHash(ctx, quote.ID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	approval, err := s.createApprovalRequest(ctx, quote, itemsHash, tenantID, agentID, reasons, strings.TrimSpace(req.Note))
+	if err != nil {
+		return nil, err
+	}
+	resp := toQuoteApprovalResponse(*approval)
+	return &resp, nil
+}
+
+func (s *Service) createApprovalRequest(ctx context.Context, quote *repository.Quote, itemsHash string, tenantID, agentID uuid.UUID, reasons []string, note string) (*repository.QuoteApproval, error) {
+	params := repository.CreateQuoteApprovalParams{
+		OrganizationID:  tenantID,
+		QuoteID:         quote.ID,
+		Reasons:         reasons,
+		QuoteTotalCents: quote.TotalCents,
+		QuoteItemsHash:  itemsHash,
+		RequestedByID:   &agentID,
+	}
+	if note != "" {
+		params.RequestNote = &note
+	}
+	approval, err := s.repo.CreateQuoteApproval(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.QuoteApprovalRequested{
+			BaseEvent:       events.NewBaseEvent(),
+			ApprovalID:      approval.ID,
+			QuoteID:         quote.ID,
+			OrganizationID:  tenantID,
+			LeadID:          quote.LeadID,
+			QuoteNumber:     quote.QuoteNumber,
+			QuoteTotalCents: quote.TotalCents,
+			RequestedByID:   agentID,
+			Reasons:         approval.Reasons,
+		})
+	}
+	s.emitTimelineEvent(ctx, TimelineEventParams{LeadID: quote.LeadID, ServiceID: quote.LeadServiceID, OrganizationID: tenantID, ActorType: "User", ActorName: agentID.String(), EventType: "quote_approval_requested", Title: fmt.Sprintf("Approval requested for quote %s", quote.QuoteNumber), Summary: toPtr(fmt.Sprintf(msgTotalFormat, float64(quote.TotalCents)/100)), Metadata: map[string]any{"quoteId": quote.ID, "approvalId": approval.ID, "reasons": approval.Reasons}})
+	return approval, nil
+}
+
+// ApproveQuote approves the pending approval request of a quote.
+func (s *Service) ApproveQuote(ctx context.Context, id, tenantID, adminID uuid.UUID, req transport.DecideQuoteApprovalRequest) (*transport.QuoteApprovalResponse, error) {
+	return s.decideApproval(ctx, id, tenantID, adminID, true, strings.TrimSpace(req.Note))
+}
+
+// RejectQuote rejects the pending approval request of a quote. A note explaining the rejection is required.
+func (s *Service) RejectQuote(ctx context.Context, id, tenantID, adminID uuid.UUID, req transport.DecideQuoteApprovalRequest) (*transport.QuoteApprovalResponse, error) {
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return nil, apperr.Validation("a note is required when rejecting a quote")
+	}
+	return s.decideApproval(ctx, id, tenantID, adminID, false, note)
+}
+
+func (s *Service) decideApproval(ctx context.Context, id, tenantID, adminID uuid.UUID, approved bool, note string) (*transport.QuoteApprovalResponse, error) {
+	quote, err := s.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := repository.ApprovalStatusRejected
+	eventType := "quote_approval_rejected"
+	title := fmt.Sprintf("Quote %s rejected for sending", quote.QuoteNumber)
+	if approved {
+		status = repository.ApprovalStatusApproved
+		eventType = "quote_approved"
+		title = fmt.Sprintf("Quote %s approved for sending", quote.QuoteNumber)
+	}
+
+	var notePtr *string
+	if note != "" {
+		notePtr = &note
+	}
+	approval, err := s.repo.DecideQuoteApproval(ctx, id, tenantID, status, adminID, notePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.QuoteApprovalDecided{
+			BaseEvent:      events.NewBaseEvent(),
+			ApprovalID:     approval.ID,
+			QuoteID:        quote.ID,
+			OrganizationID: tenantID,
+			LeadID:         quote.LeadID,
+			QuoteNumber:    quote.QuoteNumber,
+			Approved:       approved,
+			DecidedByID:    adminID,
+			RequestedByID:  approval.RequestedByID,
+			Note:           note,
+		})
+	}
+	s.emitTimelineEvent(ctx, TimelineEventParams{LeadID: quote.LeadID, ServiceID: quote.LeadServiceID, OrganizationID: tenantID, ActorType: "User", ActorName: adminID.String(), EventType: eventType, Title: title, Summary: notePtr, Metadata: map[string]any{"quoteId": quote.ID, "approvalId": approval.ID, "status": status}})
+
+	resp := toQuoteApprovalResponse(*approval)
+	return &resp, nil
+}
+
+// ListApprovals returns the approval trail of a quote, newest first.
+func (s *Service) ListApprovals(ctx context.Context, id, tenantID uuid.UUID) ([]transport.QuoteApprovalResponse, error) {
+	if _, err := s.repo.GetByID(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListQuoteApprovals(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]transport.QuoteApprovalResponse, len(items))
+	for i, item := range items {
+		out[i] = toQuoteApprovalResponse(item)
+	}
+	return out, nil
+}
+
+// GetApprovalPolicy returns the organization's quote approval policy.
+func (s *Service) GetApprovalPolicy(ctx context.Context, tenantID uuid.UUID) (*transport.QuoteApprovalPolicyResponse, error) {
+	policy, err := s.repo.GetApprovalPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return toQuoteApprovalPolicyResponse(policy), nil
+}
+
+// UpdateApprovalPolicy replaces the organization's quote approval policy.
+func (s *Service) UpdateApprovalPolicy(ctx context.Context, tenantID uuid.UUID, req transport.UpdateQuoteApprovalPolicyRequest) (*transport.QuoteApprovalPolicyResponse, error) {
+	roles := make([]string, 0, len(req.RequireForRoles))
+	for _, role := range req.RequireForRoles {
+		if role = strings.TrimSpace(role); role != "" && !hasRole(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	if req.Enabled && req.ThresholdCents == nil && len(roles) == 0 {
+		return nil, apperr.Validation("an enabled approval policy needs a threshold or at least one role")
+	}
+
+	policy, err := s.repo.UpsertApprovalPolicy(ctx, repository.ApprovalPolicy{
+		OrganizationID:  tenantID,
+		Enabled:         req.Enabled,
+		ThresholdCents:  req.ThresholdCents,
+		RequireForRoles: roles,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toQuoteApprovalPolicyResponse(policy), nil
+}
+
+func toQuoteApprovalResponse(a repository.QuoteApproval) transport.QuoteApprovalResponse {
+	return transport.QuoteApprovalResponse{
+		ID:              a.ID,
+		QuoteID:         a.QuoteID,
+		Status:          a.Status,
+		Reasons:         a.Reasons,
+		QuoteTotalCents: a.QuoteTotalCents,
+		RequestedByID:   a.RequestedByID,
+		RequestNote:     a.RequestNote,
+		DecidedByID:     a.DecidedByID,
+		DecisionNote:    a.DecisionNote,
+		DecidedAt:       a.DecidedAt,
+		CreatedAt:       a.CreatedAt,
+	}
+}
+
+func toQuoteApprovalPolicyResponse(p *repository.ApprovalPolicy) *transport.QuoteApprovalPolicyResponse {
+	return &transport.QuoteApprovalPolicyResponse{
+		Enabled:         p.Enabled,
+		ThresholdCents:  p.ThresholdCents,
+		RequireForRoles: p.RequireForRoles,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
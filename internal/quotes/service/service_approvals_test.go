@@ -0,0 +1,86 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"portal_final_backend/internal/quotes/repository"
+)
+
+func TestEvaluateApprovalRequirement(t *testing.T) {
+	threshold := int64(500000)
+	policy := &repository.ApprovalPolicy{Enabled: true, ThresholdCents: &threshold, RequireForRoles: []string{"junior"}}
+
+	cases := []struct {
+		name        string
+		policy      *repository.ApprovalPolicy
+		totalCents  int64
+		roles       []string
+		wantReq     bool
+		wantReasons []string
+	}{
+		{name: "no policy", policy: nil, totalCents: 900000, roles: []string{"user"}},
+		{name: "disabled", policy: &repository.ApprovalPolicy{ThresholdCents: &threshold}, totalCents: 900000, roles: []string{"user"}},
+		{name: "below threshold", policy: policy, totalCents: 500000, roles: []string{"user"}},
+		{name: "above threshold", policy: policy, totalCents: 500001, roles: []string{"user"}, wantReq: true, wantReasons: []string{approvalReasonThreshold}},
+		{name: "junior role", policy: policy, totalCents: 1000, roles: []string{"Junior"}, wantReq: true, wantReasons: []string{approvalReasonRole}},
+		{name: "both", policy: policy, totalCents: 900000, roles: []string{"junior"}, wantReq: true, wantReasons: []string{approvalReasonThreshold, approvalReasonRole}},
+		{name: "admin bypass", policy: policy, totalCents: 900000, roles: []string{"junior", "admin"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			required, reasons := evaluateApprovalRequirement(tc.policy, tc.totalCents, tc.roles)
+			if required != tc.wantReq {
+				t.Fatalf("required = %v, want %v", required, tc.wantReq)
+			}
+			if tc.wantReq && !reflect.DeepEqual(reasons, tc.wantReasons) {
+				t.Fatalf("reasons = %v, want %v", reasons, tc.wantReasons)
+			}
+		})
+	}
+}
+
+func TestApprovalCoversQuote(t *testing.T) {
+	items := []repository.QuoteItem{
+		{Title: "Dakgoot", Quantity: "2", UnitPriceCents: 25000, TaxRateBps: 2100, IsSelected: true, SortOrder: 1},
+		{Title: "Montage", Quantity: "1", UnitPriceCents: 40000, TaxRateBps: 2100, IsSelected: true, SortOrder: 2},
+	}
+	hash := quoteItemsHash(items)
+	approval := &repository.QuoteApproval{Status: repository.ApprovalStatusApproved, QuoteTotalCents: 100000, QuoteItemsHash: &hash}
+	if !approvalCoversQuote(approval, 90000, hash) {
+		t.Fatal("expected approval to cover a lower total")
+	}
+	if approvalCoversQuote(approval, 100001, hash) {
+		t.Fatal("expected approval to be invalidated by a higher total")
+	}
+	if approvalCoversQuote(nil, 1, hash) {
+		t.Fatal("expected nil approval not to cover quote")
+	}
+	if approvalCoversQuote(&repository.QuoteApproval{Status: repository.ApprovalStatusApproved, QuoteTotalCents: 100000}, 90000, hash) {
+		t.Fatal("expected approval without items hash not to cover quote")
+	}
+
+	swapped := []repository.QuoteItem{items[0], items[1]}
+	swapped[1].Title = "Montage en afvoer"
+	if approvalCoversQuote(approval, 90000, quoteItemsHash(swapped)) {
+		t.Fatal("expected approval to be invalidated by changed line items at the same total")
+	}
+	reordered := []repository.QuoteItem{items[1], items[0]}
+	if quoteItemsHash(reordered) != hash {
+		t.Fatal("expected items hash to follow the sort order, not the slice order")
+	}
+}
+
+func TestAutoSendRequiresApprovalAppliesRoleRules(t *testing.T) {
+	threshold := int64(500000)
+	if !autoSendRequiresApproval(&repository.ApprovalPolicy{Enabled: true, RequireForRoles: []string{"junior"}}, 1000) {
+		t.Fatal("expected role rule to require approval for an automatic send")
+	}
+	if autoSendRequiresApproval(&repository.ApprovalPolicy{Enabled: true, ThresholdCents: &threshold}, 1000) {
+		t.Fatal("expected quote below threshold to be sent without approval")
+	}
+	if autoSendRequiresApproval(&repository.ApprovalPolicy{RequireForRoles: []string{"junior"}}, 1000) {
+		t.Fatal("expected disabled policy not to require approval")
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentPreviewPruner removes stored previews of files that are no longer
+// attached to a quote.
+type AttachmentPreviewPruner interface {
+	PruneAttachmentPreviews(ctx context.Context, quoteID, orgID uuid.UUID) error
+}
+
+// SetAttachmentPreviewPruner injects the pruner that runs when attachments change.
+func (s *Service) SetAttachmentPreviewPruner(pruner AttachmentPreviewPruner) {
+	s.previews = pruner
+}
+
+// GetPublicAttachment returns an enabled attachment of the quote behind a public token.
+func (s *Service) GetPublicAttachment(ctx context.Context, token string, attachmentID uuid.UUID) (*repository.QuoteAttachment, error) {
+	quote, _, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	att, err := s.repo.GetAttachmentByID(ctx, attachmentID, quote.ID, quote.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !att.Enabled {
+		return nil, apperr.NotFound("attachment not found")
+	}
+	return att, nil
+}
+
+// pruneAttachmentPreviews drops previews of replaced attachments. Failures
+// only leave unused objects behind, so they are logged and not returned.
+func (s *Service) pruneAttachmentPreviews(ctx context.Context, quoteID, orgID uuid.UUID) {
+	if s.previews == nil {
+		return
+	}
+	if err := s.previews.PruneAttachmentPreviews(ctx, quoteID, orgID); err != nil {
+		slog.Warn("failed to prune quote attachment previews", "quoteID", quoteID, "error", err)
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	autoSendReasonPolicyDisabled   = "policy_disabled"
+	autoSendReasonServiceType      = "service_type_not_allowed"
+	autoSendReasonLeadScore        = "lead_score_below_minimum"
+	autoSendReasonTotal            = "total_above_maximum"
+	autoSendReasonNotAIDraft       = "not_ai_draft"
+	autoSendReasonNotDraft         = "quote_not_draft"
+	autoSendReasonEdited           = "quote_edited"
+	autoSendReasonApprovalRequired = "approval_required"
+	autoSendReasonCancelledByAgent = "cancelled_by_agent"
+	autoSendReasonPolicyKillSwitch = "policy_disabled_by_admin"
+	autoSendActorName              = "Auto-send"
+	dueAutoSendsBatchSize          = 100
+	maxAutoSendReviewWindowMinutes = 7 * 24 * 60
+	msgNoAutoSendScheduled         = "no auto-send is scheduled for this quote"
+	msgAutoSendPolicyNeedsServices = "an enabled auto-send policy needs at least one service type"
+	msgAutoSendPolicyReviewWindow  = "reviewWindowMinutes must be between 0 and 10080"
+)
+
+// evaluateAutoSendEligibility reports whether a quote qualifies for automatic sending under
+// the policy, or the reason it does not. Only listed service types qualify, and a lead
+// without a score never passes a score threshold.
+func evaluateAutoSendEligibility(policy *repository.AutoSendPolicy, totalCents int64, lead *repository.QuoteAutoSendContext) (bool, string) {
+	if policy == nil || !policy.Enabled {
+		return false, autoSendReasonPolicyDisabled
+	}
+	if lead == nil || lead.ServiceTypeID == nil || !containsUUID(policy.ServiceTypeIDs, *lead.ServiceTypeID) {
+		return false, autoSendReasonServiceType
+	}
+	if policy.MinLeadScore != nil && (lead.LeadScore == nil || *lead.LeadScore < *policy.MinLeadScore) {
+		return false, autoSendReasonLeadScore
+	}
+	if policy.MaxTotalCents != nil && totalCents > *policy.MaxTotalCents {
+		return false, autoSendReasonTotal
+	}
+	return true, ""
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleQuoteAutoSend records the auto-send decision for an AI-drafted quote that passed
+// its AI review. Quotes that do not qualify are recorded as skipped so the audit trail shows
+// why a quote was left for an agent. Nothing is recorded while the policy is disabled.
+func (s *Service) scheduleQuoteAutoSend(ctx context.Context, quoteID, tenantID, reviewID uuid.UUID, now time.Time) error {
+	policy, err := s.repo.GetAutoSendPolicy(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if !policy.Enabled {
+		return nil
+	}
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return err
+	}
+	if quote.Status != string(transport.QuoteStatusDraft) {
+		return nil
+	}
+	lead, err := s.repo.GetQuoteAutoSendContext(ctx, quoteID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	params := repository.CreateQuoteAutoSendParams{
+		OrganizationID:  tenantID,
+		QuoteID:         quoteID,
+		ReviewID:        &reviewID,
+		Status:          repository.AutoSendStatusScheduled,
+		QuoteTotalCents: quote.TotalCents,
+		QuoteRowVersion: quote.RowVersion,
+		LeadScore:       lead.LeadScore,
+	}
+	eligible, reason := evaluateAutoSendEligibility(policy, quote.TotalCents, lead)
+	if eligible && quote.CreatedByID != nil {
+		eligible, reason = false, autoSendReasonNotAIDraft
+	}
+	if !eligible {
+		params.Status = repository.AutoSendStatusSkipped
+		params.Reason = &reason
+		_, err := s.repo.CreateQuoteAutoSend(ctx, params)
+		return err
+	}
+
+	scheduledFor := now.Add(time.Duration(policy.ReviewWindowMinutes) * time.Minute)
+	params.ScheduledFor = &scheduledFor
+	autoSend, err := s.repo.CreateQuoteAutoSend(ctx, params)
+	if err != nil {
+		return err
+	}
+	s.emitTimelineEvent(ctx, TimelineEventParams{LeadID: quote.LeadID, ServiceID: quote.LeadServiceID, OrganizationID: tenantID, ActorType: "System", ActorName: autoSendActorName, EventType: "quote_auto_send_scheduled", Title: fmt.Sprintf("Quote %s will be sent automatically", quote.QuoteNumber), Summary: toPtr(fmt.Sprintf("Scheduled for %s unless an agent intervenes", scheduledFor.Format(time.RFC3339))), Metadata: map[string]any{"quoteId": quote.ID, "autoSendId": autoSend.ID, "scheduledFor": scheduledFor}})
+	return nil
+}
+
+// GetQuoteAutoSend returns the pending auto-send of a quote, if any, and all earlier decisions.
+func (s *Service) GetQuoteAutoSend(ctx context.Context, id, tenantID uuid.UUID) (*transport.QuoteAutoSendStatusResponse, error) {
+	if _, err := s.repo.GetByID(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListQuoteAutoSends(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transport.QuoteAutoSendStatusResponse{History: make([]transport.QuoteAutoSendResponse, len(items))}
+	for i, item := range items {
+		resp.History[i] = toQuoteAutoSendResponse(item)
+		if item.Status == repository.AutoSendStatusScheduled {
+			scheduled := resp.History[i]
+			resp.Scheduled = &scheduled
+		}
+	}
+	return resp, nil
+}
+
+// CancelQuoteAutoSend stops the scheduled auto-send of a quote so an agent can take over.
+func (s *Service) CancelQuoteAutoSend(ctx context.Context, id, tenantID, agentID uuid.UUID, req transport.CancelQuoteAutoSendRequest) (*transport.QuoteAutoSendResponse, error) {
+	quote, err := s.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	reason := autoSendReasonCancelledByAgent
+	if note := strings.TrimSpace(req.Reason); note != "" {
+		reason = reason + ": " + note
+	}
+	autoSend, err := s.repo.CancelQuoteAutoSend(ctx, id, tenantID, reason, &agentID)
+	if err != nil {
+		return nil, err
+	}
+	if autoSend == nil {
+		return nil, apperr.NotFound(msgNoAutoSendScheduled)
+	}
+
+	s.emitTimelineEvent(ctx, TimelineEventParams{LeadID: quote.LeadID, ServiceID: quote.LeadServiceID, OrganizationID: tenantID, ActorType: "User", ActorName: agentID.String(), EventType: "quote_auto_send_cancelled", Title: fmt.Sprintf("Automatic sending of quote %s cancelled", quote.QuoteNumber), Summary: autoSend.Reason, Metadata: map[string]any{"quoteId": quote.ID, "autoSendId": autoSend.ID}})
+	resp := toQuoteAutoSendResponse(*autoSend)
+	return &resp, nil
+}
+
+// SendDueAutoSends sends the quotes whose review window ended. Every schedule is claimed
+// before it is handled so overlapping sweeps process it only once, and the quote, policy
+// and approval gate are checked again at send time. It returns the number of sent quotes.
+func (s *Service) SendDueAutoSends(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ListDueQuoteAutoSends(ctx, now, dueAutoSendsBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, autoSend := range due {
+		claimed, err := s.repo.ClaimQuoteAutoSend(ctx, autoSend.ID, autoSend.OrganizationID)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		status, reason := s.runQuoteAutoSend(ctx, autoSend)
+		if err := s.repo.FinishQuoteAutoSend(ctx, autoSend.ID, autoSend.OrganizationID, status, reason); err != nil {
+			return sent, err
+		}
+		if status == repository.AutoSendStatusSent {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// runQuoteAutoSend sends a claimed quote when it still qualifies and returns the outcome.
+// Any edit or status change since scheduling counts as an agent intervention.
+func (s *Service) runQuoteAutoSend(ctx context.Context, autoSend repository.QuoteAutoSend) (string, *string) {
+	tenantID := autoSend.OrganizationID
+	failed := func(err error) (string, *string) {
+		return repository.AutoSendStatusFailed, toPtr(err.Error())
+	}
+	cancelled := func(reason string) (string, *string) {
+		return repository.AutoSendStatusCancelled, toPtr(reason)
+	}
+
+	quote, err := s.repo.GetByID(ctx, autoSend.QuoteID, tenantID)
+	if err != nil {
+		return failed(err)
+	}
+	if quote.Status != string(transport.QuoteStatusDraft) {
+		return cancelled(autoSendReasonNotDraft)
+	}
+	if quote.RowVersion != autoSend.QuoteRowVersion {
+		return cancelled(autoSendReasonEdited)
+	}
+
+	policy, err := s.repo.GetAutoSendPolicy(ctx, tenantID)
+	if err != nil {
+		return failed(err)
+	}
+	lead, err := s.repo.GetQuoteAutoSendContext(ctx, quote.ID, tenantID)
+	if err != nil {
+		return failed(err)
+	}
+	if eligible, reason := evaluateAutoSendEligibility(policy, quote.TotalCents, lead); !eligible {
+		return cancelled(reason)
+	}
+
+	approvalPolicy, err := s.repo.GetApprovalPolicy(ctx, tenantID)
+	if err != nil {
+		return failed(err)
+	}
+	if autoSendRequiresApproval(approvalPolicy, quote.TotalCents) {
+		approved, err := s.repo.GetLatestApprovedQuoteApproval(ctx, quote.ID, tenantID)
+		if err != nil {
+			return failed(err)
+		}
+		itemsHash, err := s.currentQuoteItemsHash(ctx, quote.ID, tenantID)
+		if err != nil {
+			return failed(err)
+		}
+		if !approvalCoversQuote(approved, quote.TotalCents, itemsHash) {
+			return cancelled(autoSendReasonApprovalRequired)
+		}
+	}
+
+	token, err := s.ensureQuotePublicToken(ctx, quote, tenantID)
+	if err != nil {
+		return failed(err)
+	}
+	if err := s.pinQuoteTermsVersion(ctx, quote, tenantID, time.Now()); err != nil {
+		return failed(err)
+	}
+	if err := s.ensureQuoteStatusSent(ctx, quote.ID, tenantID, quote.Status); err != nil {
+		return failed(err)
+	}
+	s.publishQuoteSentEvent(ctx, quote, tenantID, uuid.Nil, token)
+	s.emitTimelineEvent(ctx, TimelineEventParams{LeadID: quote.LeadID, ServiceID: quote.LeadServiceID, OrganizationID: tenantID, ActorType: "System", ActorName: autoSendActorName, EventType: "quote_sent", Title: fmt.Sprintf("Quote %s sent automatically", quote.QuoteNumber), Summary: toPtr(fmt.Sprintf(msgTotalFormat, float64(quote.TotalCents)/100)), Metadata: map[string]any{"quoteId": quote.ID, "status": "Sent", "autoSendId": autoSend.ID}, DedupKey: "quote_auto_sent:" + autoSend.ID.String()})
+	return repository.AutoSendStatusSent, nil
+}
+
+// GetAutoSendPolicy returns the organization's quote auto-send policy.
+func (s *Service) GetAutoSendPolicy(ctx context.Context, tenantID uuid.UUID) (*transport.QuoteAutoSendPolicyResponse, error) {
+	policy, err := s.repo.GetAutoSendPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return toQuoteAutoSendPolicyResponse(policy), nil
+}
+
+// UpdateAutoSendPolicy replaces the organization's quote auto-send policy. Disabling it
+// acts as a kill switch: every scheduled auto-send is cancelled.
+func (s *Service) UpdateAutoSendPolicy(ctx context.Context, tenantID, adminID uuid.UUID, req transport.UpdateQuoteAutoSendPolicyRequest) (*transport.QuoteAutoSendPolicyResponse, error) {
+	if req.ReviewWindowMinutes < 0 || req.ReviewWindowMinutes > maxAutoSendReviewWindowMinutes {
+		return nil, apperr.Validation(msgAutoSendPolicyReviewWindow)
+	}
+	serviceTypeIDs := make([]uuid.UUID, 0, len(req.ServiceTypeIDs))
+	for _, id := range req.ServiceTypeIDs {
+		if id != uuid.Nil && !containsUUID(serviceTypeIDs, id) {
+			serviceTypeIDs = append(serviceTypeIDs, id)
+		}
+	}
+	if req.Enabled && len(serviceTypeIDs) == 0 {
+		return nil, apperr.Validation(msgAutoSendPolicyNeedsServices)
+	}
+
+	policy, err := s.repo.UpsertAutoSendPolicy(ctx, repository.AutoSendPolicy{
+		OrganizationID:      tenantID,
+		Enabled:             req.Enabled,
+		ReviewWindowMinutes: req.ReviewWindowMinutes,
+		MinLeadScore:        req.MinLeadScore,
+		MaxTotalCents:       req.MaxTotalCents,
+		ServiceTypeIDs:      serviceTypeIDs,
+		UpdatedByID:         &adminID,
+	}, autoSendReasonPolicyKillSwitch)
+	if err != nil {
+		return nil, err
+	}
+	return toQuoteAutoSendPolicyResponse(policy), nil
+}
+
+func toQuoteAutoSendResponse(a repository.QuoteAutoSend) transport.QuoteAutoSendResponse {
+	return transport.QuoteAutoSendResponse{
+		ID:              a.ID,
+		Status:          a.Status,
+		Reason:          a.Reason,
+		ScheduledFor:    a.ScheduledFor,
+		QuoteTotalCents: a.QuoteTotalCents,
+		LeadScore:       a.LeadScore,
+		CancelledByID:   a.CancelledByID,
+		DecidedAt:       a.DecidedAt,
+		CreatedAt:       a.CreatedAt,
+	}
+}
+
+func toQuoteAutoSendPolicyResponse(p *repository.AutoSendPolicy) *transport.QuoteAutoSendPolicyResponse {
+	return &transport.QuoteAutoSendPolicyResponse{
+		Enabled:             p.Enabled,
+		ReviewWindowMinutes: p.ReviewWindowMinutes,
+		MinLeadScore:        p.MinLeadScore,
+		MaxTotalCents:       p.MaxTotalCents,
+		ServiceTypeIDs:      p.ServiceTypeIDs,
+		UpdatedByID:         p.UpdatedByID,
+		UpdatedAt:           p.UpdatedAt,
+	}
+}
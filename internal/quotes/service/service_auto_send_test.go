@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/quotes/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestEvaluateAutoSendEligibility(t *testing.T) {
+	allowed := uuid.New()
+	other := uuid.New()
+	minScore := 70
+	maxTotal := int64(250000)
+	policy := &repository.AutoSendPolicy{Enabled: true, MinLeadScore: &minScore, MaxTotalCents: &maxTotal, ServiceTypeIDs: []uuid.UUID{allowed}}
+	score := func(v int) *int { return &v }
+
+	cases := []struct {
+		name       string
+		policy     *repository.AutoSendPolicy
+		totalCents int64
+		lead       *repository.QuoteAutoSendContext
+		want       bool
+		wantReason string
+	}{
+		{name: "no policy", policy: nil, lead: &repository.QuoteAutoSendContext{ServiceTypeID: &allowed, LeadScore: score(90)}, wantReason: autoSendReasonPolicyDisabled},
+		{name: "disabled", policy: &repository.AutoSendPolicy{ServiceTypeIDs: []uuid.UUID{allowed}}, lead: &repository.QuoteAutoSendContext{ServiceTypeID: &allowed}, wantReason: autoSendReasonPolicyDisabled},
+		{name: "other service type", policy: policy, lead: &repository.QuoteAutoSendContext{ServiceTypeID: &other, LeadScore: score(90)}, wantReason: autoSendReasonServiceType},
+		{name: "no service", policy: policy, lead: &repository.QuoteAutoSendContext{LeadScore: score(90)}, wantReason: autoSendReasonServiceType},
+		{name: "low score", policy: policy, lead: &repository.QuoteAutoSendContext{ServiceTypeID: &allowed, LeadScore: score(69)}, wantReason: autoSendReasonLeadScore},
+		{name: "unscored lead", policy: policy, lead: &repository.QuoteAutoSendContext{ServiceTypeID: &allowed}, wantReason: autoSendReasonLeadScore},
+		{name: "above maximum", policy: policy, totalCents: 250001, lead: &repository.QuoteAutoSendContext{ServiceTypeID: &allowed, LeadScore: score(70)}, wantReason: autoSendReasonTotal},
+		{name: "eligible", policy: policy, totalCents: 250000, lead: &repository.QuoteAutoSendContext{ServiceTypeID: &allowed, LeadScore: score(70)}, want: true},
+		{name: "no thresholds", policy: &repository.AutoSendPolicy{Enabled: true, ServiceTypeIDs: []uuid.UUID{allowed}}, totalCents: 9000000, lead: &repository.QuoteAutoSendContext{ServiceTypeID: &allowed}, want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			eligible, reason := evaluateAutoSendEligibility(tc.policy, tc.totalCents, tc.lead)
+			if eligible != tc.want || reason != tc.wantReason {
+				t.Fatalf("got (%v, %q), want (%v, %q)", eligible, reason, tc.want, tc.wantReason)
+			}
+		})
+	}
+}
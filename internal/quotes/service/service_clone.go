@@ -190,6 +190,15 @@ func (s *Service) persistClonedQuote(ctx context.Context, tenantID uuid.UUID, ac
 	if err := s.saveURLs(ctx, payload.quote.ID, tenantID, cloneURLRequests(payload.urls)); err != nil {
 		return fmt.Errorf(errSaveURLsFmt, err)
 	}
+	sourceID := payload.quote.DuplicatedFromQuoteID
+	if payload.quote.PreviousVersionQuoteID != nil {
+		sourceID = payload.quote.PreviousVersionQuoteID
+	}
+	if sourceID != nil {
+		if err := s.repo.CopyCoveredServices(ctx, *sourceID, payload.quote.ID, payload.quote.LeadID, tenantID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
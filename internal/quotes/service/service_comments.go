@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// GetPublicThread returns the comment thread of the quote behind token as the
+// customer sees it.
+func (s *Service) GetPublicThread(ctx context.Context, token string) (*transport.QuoteThreadResponse, error) {
+	quote, _, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadThread(ctx, quote, repository.ThreadPartyCustomer)
+}
+
+// AddPublicComment adds a customer comment on the quote as a whole. The agent
+// is notified through the QuoteAnnotated event, as for item questions.
+func (s *Service) AddPublicComment(ctx context.Context, token, text string) (*transport.QuoteThreadMessage, error) {
+	quote, tokenKind, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if isReadOnlyToken(tokenKind) {
+		return nil, apperr.Forbidden(msgReadOnly)
+	}
+	if expAt := tokenExpiresAt(quote, tokenKind); expAt != nil && expAt.Before(time.Now()) {
+		return nil, apperr.Gone(msgLinkExpired)
+	}
+	return s.addComment(ctx, quote, repository.ThreadPartyCustomer, nil, text, token)
+}
+
+// MarkPublicThreadRead records that the customer read the thread. Preview
+// links are used by agents and do not count.
+func (s *Service) MarkPublicThreadRead(ctx context.Context, token string) error {
+	quote, tokenKind, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if isReadOnlyToken(tokenKind) {
+		return nil
+	}
+	return s.repo.MarkThreadRead(ctx, quote.ID, repository.ThreadPartyCustomer, time.Now())
+}
+
+// GetThread returns the comment thread of a quote as agents see it.
+func (s *Service) GetThread(ctx context.Context, quoteID, tenantID uuid.UUID) (*transport.QuoteThreadResponse, error) {
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadThread(ctx, quote, repository.ThreadPartyAgent)
+}
+
+// AddComment adds an agent comment on the quote as a whole. The customer is
+// notified through the QuoteAnnotated event, as for item answers.
+func (s *Service) AddComment(ctx context.Context, quoteID, tenantID, agentID uuid.UUID, text string) (*transport.QuoteThreadMessage, error) {
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.addComment(ctx, quote, repository.ThreadPartyAgent, &agentID, text, "")
+}
+
+// MarkThreadRead records that the agents read the thread of a quote.
+func (s *Service) MarkThreadRead(ctx context.Context, quoteID, tenantID uuid.UUID) error {
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return err
+	}
+	return s.repo.MarkThreadRead(ctx, quote.ID, repository.ThreadPartyAgent, time.Now())
+}
+
+func (s *Service) addComment(ctx context.Context, quote *repository.Quote, party string, authorID *uuid.UUID, text, token string) (*transport.QuoteThreadMessage, error) {
+	comment := repository.QuoteComment{
+		ID:             uuid.New(),
+		QuoteID:        quote.ID,
+		OrganizationID: quote.OrganizationID,
+		AuthorType:     party,
+		AuthorID:       authorID,
+		Text:           text,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.repo.CreateQuoteComment(ctx, &comment); err != nil {
+		return nil, err
+	}
+	s.markThreadRead(ctx, quote.ID, party, comment.CreatedAt)
+
+	if s.eventBus != nil {
+		authorRef := ""
+		if authorID != nil {
+			authorRef = authorID.String()
+		}
+		s.eventBus.Publish(ctx, s.buildQuoteAnnotatedEvent(ctx, quote, nil, party, authorRef, text, token))
+	}
+	return &transport.QuoteThreadMessage{ID: comment.ID, AuthorType: comment.AuthorType, AuthorID: comment.AuthorID, Text: comment.Text, CreatedAt: comment.CreatedAt}, nil
+}
+
+// markThreadRead moves the read marker of the party that just commented:
+// replying implies having read the thread.
+func (s *Service) markThreadRead(ctx context.Context, quoteID uuid.UUID, party string, at time.Time) {
+	if err := s.repo.MarkThreadRead(ctx, quoteID, party, at); err != nil {
+		slog.Warn("failed to mark quote thread read", "quoteID", quoteID, "party", party, "error", err)
+	}
+}
+
+func (s *Service) loadThread(ctx context.Context, quote *repository.Quote, party string) (*transport.QuoteThreadResponse, error) {
+	comments, err := s.repo.ListQuoteComments(ctx, quote.ID)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := s.repo.ListAnnotationsByQuoteID(ctx, quote.ID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetItemsByQuoteID(ctx, quote.ID, quote.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	readAt, err := s.repo.GetThreadReadAt(ctx, quote.ID, party)
+	if err != nil {
+		return nil, err
+	}
+	return buildQuoteThread(quote.ID, items, comments, annotations, party, readAt), nil
+}
+
+// buildQuoteThread arranges quote comments and item annotations into one
+// thread. Comments of the other party after readAt are unread.
+func buildQuoteThread(quoteID uuid.UUID, items []repository.QuoteItem, comments []repository.QuoteComment, annotations []repository.QuoteAnnotation, party string, readAt *time.Time) *transport.QuoteThreadResponse {
+	isUnread := func(authorType string, createdAt time.Time) bool {
+		return authorType != party && (readAt == nil || createdAt.After(*readAt))
+	}
+
+	thread := &transport.QuoteThreadResponse{
+		QuoteID:    quoteID,
+		Messages:   make([]transport.QuoteThreadMessage, 0, len(comments)),
+		Items:      make([]transport.QuoteItemThread, 0),
+		LastReadAt: readAt,
+	}
+	for _, c := range comments {
+		unread := isUnread(c.AuthorType, c.CreatedAt)
+		if unread {
+			thread.UnreadCount++
+		}
+		thread.Messages = append(thread.Messages, transport.QuoteThreadMessage{ID: c.ID, AuthorType: c.AuthorType, AuthorID: c.AuthorID, Text: c.Text, IsUnread: unread, CreatedAt: c.CreatedAt})
+	}
+
+	byItem := make(map[uuid.UUID][]repository.QuoteAnnotation)
+	for _, a := range annotations {
+		byItem[a.QuoteItemID] = append(byItem[a.QuoteItemID], a)
+	}
+	for i := range items {
+		itemAnnotations := byItem[items[i].ID]
+		if len(itemAnnotations) == 0 {
+			continue
+		}
+		sort.SliceStable(itemAnnotations, func(a, b int) bool { return itemAnnotations[a].CreatedAt.Before(itemAnnotations[b].CreatedAt) })
+
+		itemThread := transport.QuoteItemThread{ItemID: items[i].ID, Description: quoteAnnotationItemDescription(&items[i]), Messages: make([]transport.QuoteThreadMessage, 0, len(itemAnnotations))}
+		for _, a := range itemAnnotations {
+			itemID := a.QuoteItemID
+			unread := isUnread(a.AuthorType, a.CreatedAt)
+			if unread {
+				itemThread.UnreadCount++
+			}
+			itemThread.Messages = append(itemThread.Messages, transport.QuoteThreadMessage{ID: a.ID, ItemID: &itemID, AuthorType: a.AuthorType, AuthorID: a.AuthorID, Text: a.Text, IsUnread: unread, CreatedAt: a.CreatedAt})
+		}
+		thread.UnreadCount += itemThread.UnreadCount
+		thread.Items = append(thread.Items, itemThread)
+	}
+	return thread
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/quotes/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildQuoteThreadCountsUnreadOfOtherParty(t *testing.T) {
+	quoteID := uuid.New()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	readAt := base.Add(30 * time.Minute)
+	roof := repository.QuoteItem{ID: uuid.New(), Title: "Dakisolatie"}
+	untouched := repository.QuoteItem{ID: uuid.New(), Title: "Steiger"}
+
+	comments := []repository.QuoteComment{
+		{ID: uuid.New(), AuthorType: "customer", Text: "Kan het in mei?", CreatedAt: base},
+		{ID: uuid.New(), AuthorType: "agent", Text: "Ja", CreatedAt: base.Add(time.Hour)},
+		{ID: uuid.New(), AuthorType: "customer", Text: "Top", CreatedAt: base.Add(2 * time.Hour)},
+	}
+	annotations := []repository.QuoteAnnotation{
+		{ID: uuid.New(), QuoteItemID: roof.ID, AuthorType: "customer", Text: "Welke dikte?", CreatedAt: base.Add(3 * time.Hour)},
+		{ID: uuid.New(), QuoteItemID: roof.ID, AuthorType: "agent", Text: "12 cm", CreatedAt: base.Add(4 * time.Hour)},
+	}
+
+	agentView := buildQuoteThread(quoteID, []repository.QuoteItem{roof, untouched}, comments, annotations, repository.ThreadPartyAgent, &readAt)
+	if agentView.UnreadCount != 2 {
+		t.Fatalf("agent unread = %d, want 2", agentView.UnreadCount)
+	}
+	if len(agentView.Messages) != 3 || agentView.Messages[0].IsUnread || !agentView.Messages[2].IsUnread {
+		t.Fatalf("agent quote messages = %+v", agentView.Messages)
+	}
+	if len(agentView.Items) != 1 || agentView.Items[0].ItemID != roof.ID || agentView.Items[0].Description != "Dakisolatie" || agentView.Items[0].UnreadCount != 1 {
+		t.Fatalf("agent item threads = %+v", agentView.Items)
+	}
+
+	customerView := buildQuoteThread(quoteID, []repository.QuoteItem{roof, untouched}, comments, annotations, repository.ThreadPartyCustomer, nil)
+	if customerView.UnreadCount != 2 {
+		t.Fatalf("customer unread = %d, want 2", customerView.UnreadCount)
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	msgCoveredServicesNeedPrimary = "a quote covering several services must be linked to a lead service"
+	msgCoveredServiceForeign      = "covered services must belong to the lead of the quote"
+)
+
+// resolveCoveredServices validates the services a quote covers besides its
+// own lead service and returns them without duplicates.
+func (s *Service) resolveCoveredServices(ctx context.Context, tenantID, leadID uuid.UUID, leadServiceID *uuid.UUID, requested []uuid.UUID) ([]uuid.UUID, error) {
+	covered := dedupeCoveredServices(leadServiceID, requested)
+	if len(covered) == 0 {
+		return covered, nil
+	}
+	if leadServiceID == nil {
+		return nil, apperr.Validation(msgCoveredServicesNeedPrimary)
+	}
+	count, err := s.repo.CountLeadServicesOfLead(ctx, leadID, tenantID, covered)
+	if err != nil {
+		return nil, err
+	}
+	if count != len(covered) {
+		return nil, apperr.Validation(msgCoveredServiceForeign)
+	}
+	return covered, nil
+}
+
+// dedupeCoveredServices drops duplicates and the quote's own lead service.
+func dedupeCoveredServices(leadServiceID *uuid.UUID, requested []uuid.UUID) []uuid.UUID {
+	covered := make([]uuid.UUID, 0, len(requested))
+	seen := make(map[uuid.UUID]bool, len(requested))
+	for _, id := range requested {
+		if id == uuid.Nil || seen[id] || (leadServiceID != nil && id == *leadServiceID) {
+			continue
+		}
+		seen[id] = true
+		covered = append(covered, id)
+	}
+	return covered
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// CreateCreditNote credits part of an accepted quote. The credited lines are stored as
+// negative amounts using the quote's pricing mode, and together all credit notes of a
+// quote can never exceed the quote total.
+func (s *Service) CreateCreditNote(ctx context.Context, quoteID, tenantID, agentID uuid.UUID, req transport.CreateCreditNoteRequest) (*transport.CreditNoteResponse, error) {
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != string(transport.QuoteStatusAccepted) {
+		return nil, apperr.Validation("credit notes can only be created for accepted quotes")
+	}
+
+	quoteItems, err := s.repo.GetItemsByQuoteID(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := resolveCreditNoteItems(req.Items, quoteItems)
+	if err != nil {
+		return nil, err
+	}
+	calc := calculateCreditNote(items, quote.PricingMode)
+	for i := range items {
+		items[i].LineTotalCents = calc.Lines[i].LineTotalCents
+	}
+
+	note, err := s.repo.CreateCreditNote(ctx, repository.CreateCreditNoteParams{
+		OrganizationID: tenantID,
+		QuoteID:        quote.ID,
+		QuoteNumber:    quote.QuoteNumber,
+		Reason:         strings.TrimSpace(req.Reason),
+		SubtotalCents:  calc.SubtotalCents,
+		TaxTotalCents:  calc.VatTotalCents,
+		TotalCents:     calc.TotalCents,
+		CreatedByID:    &agentID,
+		Items:          items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.emitTimelineEvent(ctx, TimelineEventParams{
+		LeadID:         quote.LeadID,
+		ServiceID:      quote.LeadServiceID,
+		OrganizationID: tenantID,
+		ActorType:      "User",
+		ActorName:      agentID.String(),
+		EventType:      "quote_credit_note_created",
+		Title:          fmt.Sprintf("Credit note %s issued for quote %s", note.CreditNoteNumber, quote.QuoteNumber),
+		Summary:        toPtr(fmt.Sprintf(msgTotalFormat, float64(note.TotalCents)/100)),
+		Metadata:       map[string]any{"quoteId": quote.ID, "creditNoteId": note.ID, "reason": note.Reason},
+	})
+
+	s.propagateCreditNoteExport(ctx, quote.ID, note.ID, tenantID)
+
+	saved, err := s.repo.GetCreditNoteItems(ctx, note.ID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toCreditNoteResponse(*note, saved)
+	return &resp, nil
+}
+
+// propagateCreditNoteExport exports a new credit note to Moneybird when the quote itself
+// was exported there, so the accounting stays in sync. Failures are not fatal; the
+// export endpoint can be retried.
+func (s *Service) propagateCreditNoteExport(ctx context.Context, quoteID, creditNoteID, tenantID uuid.UUID) {
+	export, err := s.repo.GetQuoteExport(ctx, quoteID, tenantID, "moneybird")
+	if err != nil || export == nil {
+		return
+	}
+	_, _ = s.ExportCreditNoteToProvider(ctx, quoteID, creditNoteID, tenantID, "moneybird")
+}
+
+// resolveCreditNoteItems validates the requested lines against the quote and turns
+// them into negative credit note items. A line of a quote item credits at most the
+// quoted unit price, and the lines of one item together at most its quantity; the
+// repository caps the quantity across earlier credit notes.
+func resolveCreditNoteItems(reqItems []transport.CreateCreditNoteItemRequest, quoteItems []repository.QuoteItem) ([]repository.CreditNoteItem, error) {
+	byID := make(map[uuid.UUID]repository.QuoteItem, len(quoteItems))
+	for _, item := range quoteItems {
+		byID[item.ID] = item
+	}
+	creditedQuantity := make(map[uuid.UUID]float64)
+
+	items := make([]repository.CreditNoteItem, 0, len(reqItems))
+	for i, req := range reqItems {
+		item := repository.CreditNoteItem{
+			QuoteItemID:    req.QuoteItemID,
+			Description:    strings.TrimSpace(req.Description),
+			Quantity:       normalizeQuantityString(req.Quantity),
+			UnitPriceCents: -req.UnitPriceCents,
+		}
+		item.QuantityNumeric = parseQuantityNumber(item.Quantity)
+		if req.TaxRateBps != nil {
+			item.TaxRateBps = *req.TaxRateBps
+		}
+
+		if req.QuoteItemID != nil {
+			source, ok := byID[*req.QuoteItemID]
+			if !ok {
+				return nil, apperr.Validation("credit note item does not belong to this quote").WithDetails(map[string]any{"index": i, "quoteItemId": *req.QuoteItemID})
+			}
+			if item.Description == "" {
+				item.Description = source.Description
+			}
+			if req.TaxRateBps == nil {
+				item.TaxRateBps = source.TaxRateBps
+			}
+			if req.UnitPriceCents > source.UnitPriceCents {
+				return nil, apperr.Validation("credit note item exceeds the quoted unit price").WithDetails(map[string]any{
+					"index": i, "quoteItemId": source.ID, "quotedUnitPriceCents": source.UnitPriceCents,
+				})
+			}
+			creditedQuantity[source.ID] += item.QuantityNumeric
+			if creditedQuantity[source.ID] > source.QuantityNumeric+repository.CreditQuantityTolerance {
+				return nil, apperr.Validation("credit note items exceed the quoted quantity").WithDetails(map[string]any{
+					"index": i, "quoteItemId": source.ID, "quotedQuantity": source.QuantityNumeric,
+				})
+			}
+		}
+		if item.Description == "" {
+			return nil, apperr.Validation("credit note items need a description").WithDetails(map[string]any{"index": i})
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// calculateCreditNote totals credit note lines with the quote calculator so VAT is
+// rounded exactly like on the original quote. The calculator works on the credited
+// (positive) amounts because its discount cap assumes a non-negative subtotal; the
+// result is negated afterwards.
+func calculateCreditNote(items []repository.CreditNoteItem, pricingMode string) transport.QuoteCalculationResponse {
+	reqItems := make([]transport.QuoteItemRequest, len(items))
+	for i, item := range items {
+		reqItems[i] = transport.QuoteItemRequest{
+			Description:    item.Description,
+			Quantity:       item.Quantity,
+			UnitPriceCents: -item.UnitPriceCents,
+			TaxRateBps:     item.TaxRateBps,
+		}
+	}
+	calc := CalculateQuote(transport.QuoteCalculationRequest{Items: reqItems, PricingMode: pricingMode})
+
+	for i := range calc.Lines {
+		line := &calc.Lines[i]
+		line.UnitPriceCents = -line.UnitPriceCents
+		line.TotalBeforeTaxCents = -line.TotalBeforeTaxCents
+		line.TotalTaxCents = -line.TotalTaxCents
+		line.LineTotalCents = -line.LineTotalCents
+	}
+	for i := range calc.VatBreakdown {
+		calc.VatBreakdown[i].AmountCents = -calc.VatBreakdown[i].AmountCents
+	}
+	calc.SubtotalCents = -calc.SubtotalCents
+	calc.VatTotalCents = -calc.VatTotalCents
+	calc.TotalCents = -calc.TotalCents
+	return calc
+}
+
+// ListCreditNotes returns the credit notes of a quote and how much can still be credited.
+func (s *Service) ListCreditNotes(ctx context.Context, quoteID, tenantID uuid.UUID) (*transport.CreditNoteListResponse, error) {
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.repo.ListCreditNotes(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &transport.CreditNoteListResponse{
+		Items:           make([]transport.CreditNoteResponse, 0, len(notes)),
+		QuoteTotalCents: quote.TotalCents,
+	}
+	for _, note := range notes {
+		items, err := s.repo.GetCreditNoteItems(ctx, note.ID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Items = append(resp.Items, toCreditNoteResponse(note, items))
+		resp.CreditedTotalCents -= note.TotalCents
+	}
+	resp.RemainingCreditCents = max(quote.TotalCents-resp.CreditedTotalCents, 0)
+	return resp, nil
+}
+
+// GetCreditNote returns a single credit note of a quote.
+func (s *Service) GetCreditNote(ctx context.Context, quoteID, creditNoteID, tenantID uuid.UUID) (*transport.CreditNoteResponse, error) {
+	note, err := s.repo.GetCreditNote(ctx, creditNoteID, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetCreditNoteItems(ctx, note.ID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toCreditNoteResponse(*note, items)
+	return &resp, nil
+}
+
+// GetCreditNotePDFFileKey returns the credit note number and its stored PDF key, if any.
+func (s *Service) GetCreditNotePDFFileKey(ctx context.Context, quoteID, creditNoteID, tenantID uuid.UUID) (string, *string, error) {
+	note, err := s.repo.GetCreditNote(ctx, creditNoteID, quoteID, tenantID)
+	if err != nil {
+		return "", nil, err
+	}
+	return note.CreditNoteNumber, note.PDFFileKey, nil
+}
+
+// ExportCreditNoteToProvider pushes a credit note to an accounting provider. Moneybird
+// books it as a sales invoice with negative lines. Exports are idempotent.
+func (s *Service) ExportCreditNoteToProvider(ctx context.Context, quoteID, creditNoteID, tenantID uuid.UUID, provider string) (*transport.CreditNoteExportResponse, error) {
+	normalizedProvider, err := normalizeProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	integration, err := s.repo.GetProviderIntegration(ctx, tenantID, normalizedProvider)
+	if err != nil {
+		return nil, err
+	}
+	if integration == nil || !integration.IsConnected {
+		return nil, apperr.BadRequest("provider is not connected")
+	}
+	if normalizedProvider != "moneybird" {
+		return nil, apperr.BadRequest("provider not yet implemented")
+	}
+
+	note, err := s.repo.GetCreditNote(ctx, creditNoteID, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetCreditNoteExport(ctx, note.ID, tenantID, normalizedProvider)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		existing, err = s.exportCreditNoteToMoneybird(ctx, note, tenantID, integration)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &transport.CreditNoteExportResponse{
+		CreditNoteID: note.ID,
+		Provider:     normalizedProvider,
+		ExternalID:   existing.ExternalID,
+		ExternalURL:  ptrToString(existing.ExternalURL),
+		State:        existing.State,
+		ExportedAt:   existing.CreatedAt,
+	}, nil
+}
+
+func (s *Service) exportCreditNoteToMoneybird(ctx context.Context, note *repository.CreditNote, tenantID uuid.UUID, integration *repository.ProviderIntegration) (*repository.CreditNoteExport, error) {
+	if err := s.validateMoneybirdExportIntegration(integration); err != nil {
+		return nil, err
+	}
+	accessToken, err := s.resolveMoneybirdAccessToken(ctx, tenantID, integration)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.repo.GetByID(ctx, note.QuoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetCreditNoteItems(ctx, note.ID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	administrationID := *integration.AdministrationID
+	contactID, err := s.moneybirdResolveContactID(ctx, administrationID, accessToken, quote.ID.String(), quote)
+	if err != nil {
+		return nil, err
+	}
+	taxRateIDByBPS, err := s.moneybirdResolveTaxRateIDByBPS(ctx, administrationID, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := buildMoneybirdCreditNoteLines(items, taxRateIDByBPS)
+	if err != nil {
+		return nil, err
+	}
+
+	invoiceID, invoiceURL, err := s.createMoneybirdInvoice(ctx, administrationID, accessToken, note.CreditNoteNumber, contactID, lines)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateCreditNoteExport(ctx, repository.CreditNoteExport{
+		CreditNoteID:   note.ID,
+		OrganizationID: tenantID,
+		Provider:       "moneybird",
+		ExternalID:     invoiceID,
+		ExternalURL:    optionalString(invoiceURL),
+		State:          "draft",
+	})
+}
+
+// buildMoneybirdCreditNoteLines exports credit note lines with their negative prices,
+// mirroring how the quote lines were exported.
+func buildMoneybirdCreditNoteLines(items []repository.CreditNoteItem, taxRateIDByBPS map[int]int64) ([]moneybirdExportLine, error) {
+	lines := make([]moneybirdExportLine, 0, len(items))
+	for _, item := range items {
+		taxID, ok := taxRateIDByBPS[item.TaxRateBps]
+		if !ok {
+			return nil, apperr.BadRequest("moneybird export failed: tax rate not found")
+		}
+		lines = append(lines, moneybirdExportLine{
+			Description: item.Description,
+			Price:       float64(item.UnitPriceCents) / 100,
+			Amount:      moneybirdExportAmount(item.Quantity),
+			TaxRateID:   taxID,
+		})
+	}
+	return lines, nil
+}
+
+func toCreditNoteResponse(note repository.CreditNote, items []repository.CreditNoteItem) transport.CreditNoteResponse {
+	resp := transport.CreditNoteResponse{
+		ID:               note.ID,
+		QuoteID:          note.QuoteID,
+		CreditNoteNumber: note.CreditNoteNumber,
+		Reason:           note.Reason,
+		SubtotalCents:    note.SubtotalCents,
+		TaxTotalCents:    note.TaxTotalCents,
+		TotalCents:       note.TotalCents,
+		HasPDF:           note.PDFFileKey != nil && *note.PDFFileKey != "",
+		CreatedByID:      note.CreatedByID,
+		CreatedAt:        note.CreatedAt,
+		Items:            make([]transport.CreditNoteItemResponse, len(items)),
+	}
+	for i, item := range items {
+		resp.Items[i] = transport.CreditNoteItemResponse{
+			ID:             item.ID,
+			QuoteItemID:    item.QuoteItemID,
+			Description:    item.Description,
+			Quantity:       item.Quantity,
+			UnitPriceCents: item.UnitPriceCents,
+			TaxRateBps:     item.TaxRateBps,
+			LineTotalCents: item.LineTotalCents,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveCreditNoteItemsDefaultsFromQuoteItem(t *testing.T) {
+	quoteItem := repository.QuoteItem{ID: uuid.New(), Description: "Dakisolatie", Quantity: "1", QuantityNumeric: 1, UnitPriceCents: 5000, TaxRateBps: 900}
+
+	items, err := resolveCreditNoteItems([]transport.CreateCreditNoteItemRequest{
+		{QuoteItemID: &quoteItem.ID, UnitPriceCents: 2500},
+	}, []repository.QuoteItem{quoteItem})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	got := items[0]
+	if got.Description != "Dakisolatie" || got.TaxRateBps != 900 || got.Quantity != "1" {
+		t.Fatalf("expected defaults from quote item, got %+v", got)
+	}
+	if got.UnitPriceCents != -2500 {
+		t.Fatalf("expected negative unit price, got %d", got.UnitPriceCents)
+	}
+}
+
+func TestResolveCreditNoteItemsRejectsForeignQuoteItem(t *testing.T) {
+	foreign := uuid.New()
+	_, err := resolveCreditNoteItems([]transport.CreateCreditNoteItemRequest{
+		{QuoteItemID: &foreign, UnitPriceCents: 100},
+	}, []repository.QuoteItem{{ID: uuid.New(), Description: "Other"}})
+	if err == nil {
+		t.Fatal("expected error for item of another quote")
+	}
+}
+
+func TestResolveCreditNoteItemsRejectsMoreThanQuoted(t *testing.T) {
+	quoteItem := repository.QuoteItem{ID: uuid.New(), Description: "Dakpannen", Quantity: "10 m²", QuantityNumeric: 10, UnitPriceCents: 4500}
+
+	tests := []struct {
+		name  string
+		items []transport.CreateCreditNoteItemRequest
+	}{
+		{"unit price", []transport.CreateCreditNoteItemRequest{
+			{QuoteItemID: &quoteItem.ID, Quantity: "1", UnitPriceCents: 4501},
+		}},
+		{"quantity", []transport.CreateCreditNoteItemRequest{
+			{QuoteItemID: &quoteItem.ID, Quantity: "11 m²", UnitPriceCents: 4500},
+		}},
+		{"quantity over several lines", []transport.CreateCreditNoteItemRequest{
+			{QuoteItemID: &quoteItem.ID, Quantity: "6", UnitPriceCents: 4500},
+			{QuoteItemID: &quoteItem.ID, Quantity: "4,5", UnitPriceCents: 100},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := resolveCreditNoteItems(tt.items, []repository.QuoteItem{quoteItem}); err == nil {
+				t.Fatal("expected error for credit above the quote item")
+			}
+		})
+	}
+
+	items, err := resolveCreditNoteItems([]transport.CreateCreditNoteItemRequest{
+		{QuoteItemID: &quoteItem.ID, Quantity: "6", UnitPriceCents: 4500},
+		{QuoteItemID: &quoteItem.ID, Quantity: "4 m²", UnitPriceCents: 100},
+		{Description: "Coulance", Quantity: "20", UnitPriceCents: 10000},
+	}, []repository.QuoteItem{quoteItem})
+	if err != nil {
+		t.Fatalf("unexpected error for credit within the quote item: %v", err)
+	}
+	if items[1].QuantityNumeric != 4 {
+		t.Fatalf("expected parsed quantity 4, got %v", items[1].QuantityNumeric)
+	}
+}
+
+func TestCalculateCreditNoteProducesNegativeTotals(t *testing.T) {
+	calc := calculateCreditNote([]repository.CreditNoteItem{
+		{Description: "Korting", Quantity: "2", UnitPriceCents: -1000, TaxRateBps: 2100},
+	}, "exclusive")
+
+	if calc.SubtotalCents != -2000 {
+		t.Fatalf("expected subtotal -2000, got %d", calc.SubtotalCents)
+	}
+	if calc.VatTotalCents != -420 {
+		t.Fatalf("expected VAT -420, got %d", calc.VatTotalCents)
+	}
+	if calc.TotalCents != -2420 {
+		t.Fatalf("expected total -2420, got %d", calc.TotalCents)
+	}
+}
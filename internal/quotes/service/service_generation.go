@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -15,6 +16,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxVersionConflictAttempts bounds how often a draft update is rebuilt from a
+// fresh read after losing a race with another writer.
+const maxVersionConflictAttempts = 3
+
 type StartGenerateQuoteJobParams struct {
 	TenantID        uuid.UUID
 	UserID          uuid.UUID
@@ -76,6 +81,13 @@ func (s *Service) StartGenerateQuoteJob(ctx context.Context, params StartGenerat
 	}); err != nil {
 		return uuid.Nil, err
 	}
+	if err := s.repo.SaveGenerateQuoteJobRequest(ctx, job.JobID, repository.GenerateQuoteJobRequest{
+		Prompt:          &params.Prompt,
+		ExistingQuoteID: params.ExistingQuoteID,
+		Force:           params.Force,
+	}); err != nil {
+		return uuid.Nil, err
+	}
 
 	s.publishJobProgress(job)
 
@@ -104,6 +116,10 @@ func (s *Service) GetGenerateQuoteJob(ctx context.Context, tenantID, userID, job
 	if err != nil {
 		return nil, err
 	}
+	exec, err := s.repo.GetGenerateQuoteJobExecution(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
 
 	return &GenerateQuoteJob{
 		JobID:           job.ID,
@@ -121,6 +137,9 @@ func (s *Service) GetGenerateQuoteJob(ctx context.Context, tenantID, userID, job
 		StartedAt:       job.StartedAt,
 		UpdatedAt:       job.UpdatedAt,
 		FinishedAt:      job.FinishedAt,
+		Attempt:         exec.AttemptCount,
+		RecoveryCount:   exec.RecoveryCount,
+		HeartbeatAt:     exec.HeartbeatAt,
 	}, nil
 }
 
@@ -129,18 +148,25 @@ func (s *Service) ProcessGenerateQuoteJob(ctx context.Context, jobID uuid.UUID,
 	if err != nil || shouldStop {
 		return err
 	}
+	attempt, err := s.repo.StartGenerateQuoteJobAttempt(ctx, jobID, time.Now())
+	if err != nil {
+		return err
+	}
+	defer s.keepGenerateQuoteJobAlive(ctx, jobID)()
 
-	s.publishJobProgress(repositoryJobToServiceJob(claimed))
+	started := repositoryJobToServiceJob(claimed)
+	started.Attempt = attempt
+	s.publishJobProgress(started)
 
 	if shouldStop, err := s.stopIfCancelled(ctx, jobID); err != nil || shouldStop {
 		return err
 	}
 
-	if err := s.updateJobProgress(ctx, jobID, GenerateQuoteJobStatusRunning, jobStepGeneratingAIQuote, 55, nil); err != nil {
+	if err := s.updateJobProgress(ctx, jobID, GenerateQuoteJobStatusRunning, jobStepGeneratingAIQuote, jobProgressGenerationStart, nil); err != nil {
 		return err
 	}
 
-	result, err := s.GenerateQuote(ctx, claimed.OrganizationID, claimed.LeadID, claimed.LeadServiceID, prompt, existingQuoteID, force)
+	result, err := s.GenerateQuote(s.withGenerateQuoteJobProgress(ctx, jobID), claimed.OrganizationID, claimed.LeadID, claimed.LeadServiceID, prompt, existingQuoteID, force)
 	if shouldStop, cancelErr := s.stopIfCancelled(ctx, jobID); cancelErr != nil {
 		return cancelErr
 	} else if shouldStop {
@@ -202,7 +228,19 @@ func (s *Service) claimGenerateQuoteJobForProcessing(ctx context.Context, jobID
 		return nil, false, currentErr
 	}
 	switch current.Status {
-	case string(GenerateQuoteJobStatusRunning), string(GenerateQuoteJobStatusCompleted), string(GenerateQuoteJobStatusFailed), string(GenerateQuoteJobStatusCancelled):
+	case string(GenerateQuoteJobStatusRunning):
+		// A retried task finds the job still running when its previous worker
+		// died mid-run; take the job over once it stopped reporting.
+		requeued, err := s.takeOverInterruptedGenerateQuoteJob(ctx, jobID)
+		if err != nil || !requeued {
+			return nil, true, err
+		}
+		claimed, err := s.repo.ClaimGenerateQuoteJob(ctx, jobID, jobStepPreparingContext, 10, time.Now())
+		if err != nil {
+			return nil, false, err
+		}
+		return claimed, claimed == nil, nil
+	case string(GenerateQuoteJobStatusCompleted), string(GenerateQuoteJobStatusFailed), string(GenerateQuoteJobStatusCancelled):
 		return nil, true, nil
 	default:
 		return nil, false, fmt.Errorf("generate quote job %s cannot be claimed from status %s", jobID, current.Status)
@@ -327,6 +365,8 @@ func (s *Service) publishJobProgress(job *GenerateQuoteJob) {
 			"quoteId":         job.QuoteID,
 			"quoteNumber":     job.QuoteNumber,
 			"itemCount":       job.ItemCount,
+			"attempt":         job.Attempt,
+			"maxAttempts":     MaxGenerateQuoteJobAttempts,
 		},
 	}
 	s.sse.Publish(job.UserID, sse.Event{Type: sse.EventAIJobProgress, Message: "AI quote generation progress", Data: data})
@@ -361,12 +401,27 @@ func (s *Service) createDraftQuote(ctx context.Context, params DraftQuoteParams)
 	return &DraftQuoteResult{QuoteID: quote.ID, QuoteNumber: quoteNumber, ItemCount: len(items)}, nil
 }
 
+// updateDraftQuote replaces the items of an existing draft. Drafts are rebuilt
+// from the latest quote when an agent edited it concurrently, so the write
+// never rests on a stale read.
 func (s *Service) updateDraftQuote(ctx context.Context, params DraftQuoteParams) (*DraftQuoteResult, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := s.tryUpdateDraftQuote(ctx, params)
+		if errors.Is(err, repository.ErrVersionConflict) && attempt < maxVersionConflictAttempts {
+			continue
+		}
+		return result, err
+	}
+}
+
+func (s *Service) tryUpdateDraftQuote(ctx context.Context, params DraftQuoteParams) (*DraftQuoteResult, error) {
 	quoteID := *params.QuoteID
 	quote, err := s.repo.GetByID(ctx, quoteID, params.OrganizationID)
 	if err != nil {
 		return nil, fmt.Errorf("update draft quote: load existing: %w", err)
 	}
+	readVersion := quote.RowVersion
+	quote.ExpectedRowVersion = &readVersion
 
 	calc := CalculateQuote(transport.QuoteCalculationRequest{Items: buildDraftCalcItems(params.Items), PricingMode: quote.PricingMode})
 	now := time.Now()
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Milestones the prompt generator reports while it generates a quote.
+const (
+	GenerateQuoteStageAnalyzing        = "analyzing"
+	GenerateQuoteStageProductsSearched = "products_searched"
+	GenerateQuoteStageItemsDrafted     = "items_drafted"
+	GenerateQuoteStageReviewing        = "reviewing"
+)
+
+const (
+	jobStepAnalyzingRequest     = "Analyzing request"
+	jobStepSearchedProductsFmt  = "Searched products (%d searches, %d matches)"
+	jobStepDraftedItemsFmt      = "Drafted %d items"
+	jobStepDraftedSingleItemFmt = "Drafted %d item"
+	jobStepReviewingDraft       = "Reviewing draft quote"
+
+	// Generation reports its milestones between preparing the context and
+	// finalizing; product searches advance the job until the draft is made.
+	jobProgressGenerationStart = 15
+	jobProgressAnalyzing       = 20
+	jobProgressFirstSearch     = 30
+	jobProgressPerSearch       = 5
+	jobProgressSearchesCap     = 60
+	jobProgressItemsDrafted    = 70
+	jobProgressReviewing       = 80
+)
+
+// GenerateQuoteProgress is a milestone of quote generation. Count is the
+// number of products found by a search or the number of drafted items.
+type GenerateQuoteProgress struct {
+	Stage string
+	Count int
+}
+
+type generateQuoteProgressKey struct{}
+
+// WithGenerateQuoteProgress returns a child context whose quote generation
+// reports its milestones to report.
+func WithGenerateQuoteProgress(ctx context.Context, report func(GenerateQuoteProgress)) context.Context {
+	return context.WithValue(ctx, generateQuoteProgressKey{}, report)
+}
+
+// GenerateQuoteProgressReporter returns the milestone reporter of ctx, or nil.
+func GenerateQuoteProgressReporter(ctx context.Context) func(GenerateQuoteProgress) {
+	report, _ := ctx.Value(generateQuoteProgressKey{}).(func(GenerateQuoteProgress))
+	return report
+}
+
+// generateQuoteJobProgress turns milestones into the step and percentage of a
+// job. The percentage never goes back, since a repair round may search again
+// after the draft was made.
+type generateQuoteJobProgress struct {
+	mu       sync.Mutex
+	percent  int
+	searches int
+	matches  int
+}
+
+func (p *generateQuoteJobProgress) apply(progress GenerateQuoteProgress) (string, int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var step string
+	var percent int
+	switch progress.Stage {
+	case GenerateQuoteStageAnalyzing:
+		step, percent = jobStepAnalyzingRequest, jobProgressAnalyzing
+	case GenerateQuoteStageProductsSearched:
+		p.searches++
+		p.matches += progress.Count
+		step = fmt.Sprintf(jobStepSearchedProductsFmt, p.searches, p.matches)
+		percent = min(jobProgressFirstSearch+(p.searches-1)*jobProgressPerSearch, jobProgressSearchesCap)
+	case GenerateQuoteStageItemsDrafted:
+		format := jobStepDraftedItemsFmt
+		if progress.Count == 1 {
+			format = jobStepDraftedSingleItemFmt
+		}
+		step, percent = fmt.Sprintf(format, progress.Count), jobProgressItemsDrafted
+	case GenerateQuoteStageReviewing:
+		step, percent = jobStepReviewingDraft, jobProgressReviewing
+	default:
+		return "", 0, false
+	}
+
+	p.percent = max(p.percent, percent)
+	return step, p.percent, true
+}
+
+// withGenerateQuoteJobProgress makes the milestones of a generation update
+// the job and stream to its user.
+func (s *Service) withGenerateQuoteJobProgress(ctx context.Context, jobID uuid.UUID) context.Context {
+	tracker := &generateQuoteJobProgress{percent: jobProgressGenerationStart}
+	return WithGenerateQuoteProgress(ctx, func(progress GenerateQuoteProgress) {
+		step, percent, ok := tracker.apply(progress)
+		if !ok {
+			return
+		}
+		// Progress is informative; a failed update must not fail the generation.
+		_ = s.updateJobProgress(ctx, jobID, GenerateQuoteJobStatusRunning, step, percent, nil)
+	})
+}
@@ -0,0 +1,44 @@
+package service
+
+import "testing"
+
+func TestGenerateQuoteJobProgressAdvancesThroughMilestones(t *testing.T) {
+	tracker := &generateQuoteJobProgress{percent: jobProgressGenerationStart}
+	steps := []struct {
+		progress    GenerateQuoteProgress
+		wantStep    string
+		wantPercent int
+	}{
+		{GenerateQuoteProgress{Stage: GenerateQuoteStageAnalyzing}, jobStepAnalyzingRequest, 20},
+		{GenerateQuoteProgress{Stage: GenerateQuoteStageProductsSearched, Count: 4}, "Searched products (1 searches, 4 matches)", 30},
+		{GenerateQuoteProgress{Stage: GenerateQuoteStageProductsSearched, Count: 2}, "Searched products (2 searches, 6 matches)", 35},
+		{GenerateQuoteProgress{Stage: GenerateQuoteStageItemsDrafted, Count: 5}, "Drafted 5 items", 70},
+		{GenerateQuoteProgress{Stage: GenerateQuoteStageReviewing}, jobStepReviewingDraft, 80},
+		// A repair round searching again must not move the job backwards.
+		{GenerateQuoteProgress{Stage: GenerateQuoteStageProductsSearched}, "Searched products (3 searches, 6 matches)", 80},
+	}
+	for i, tc := range steps {
+		step, percent, ok := tracker.apply(tc.progress)
+		if !ok || step != tc.wantStep || percent != tc.wantPercent {
+			t.Fatalf("step %d: got (%q, %d, %v), want (%q, %d, true)", i, step, percent, ok, tc.wantStep, tc.wantPercent)
+		}
+	}
+}
+
+func TestGenerateQuoteJobProgressCapsSearches(t *testing.T) {
+	tracker := &generateQuoteJobProgress{}
+	var percent int
+	for range 20 {
+		_, percent, _ = tracker.apply(GenerateQuoteProgress{Stage: GenerateQuoteStageProductsSearched})
+	}
+	if percent != jobProgressSearchesCap {
+		t.Fatalf("expected searches to cap at %d, got %d", jobProgressSearchesCap, percent)
+	}
+}
+
+func TestGenerateQuoteJobProgressIgnoresUnknownStage(t *testing.T) {
+	tracker := &generateQuoteJobProgress{}
+	if _, _, ok := tracker.apply(GenerateQuoteProgress{Stage: "rendering"}); ok {
+		t.Fatal("expected unknown stage to be ignored")
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/scheduler"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxGenerateQuoteJobAttempts bounds how often a job is started or queued
+	// again after its worker was interrupted.
+	MaxGenerateQuoteJobAttempts = 3
+
+	// generateQuoteJobHeartbeatInterval is how often a running job reports;
+	// a running job silent for generateQuoteJobStaleAfter is interrupted.
+	generateQuoteJobHeartbeatInterval = 30 * time.Second
+	generateQuoteJobStaleAfter        = 3 * time.Minute
+	// generateQuoteJobPendingStaleAfter is how long a queued job may wait
+	// before its task is considered lost.
+	generateQuoteJobPendingStaleAfter = 15 * time.Minute
+	interruptedJobsBatchSize          = 50
+
+	jobStepRequeued    = "Requeued after interruption"
+	jobStepInterrupted = "Interrupted"
+
+	msgJobAttemptsExhausted = "quote generation was interrupted too often"
+	msgJobRequestMissing    = "quote generation was interrupted and cannot be resumed"
+)
+
+// GenerateQuoteJobRecoveryResult counts what a recovery sweep did.
+type GenerateQuoteJobRecoveryResult struct {
+	Requeued int
+	Failed   int
+}
+
+// interruptedJobOutcome decides what happens to an interrupted job: queued
+// again while it has attempts left and its request is known, failed
+// otherwise with the reason.
+func interruptedJobOutcome(exec repository.GenerateQuoteJobExecution, hasRequest bool) (bool, string) {
+	if max(exec.AttemptCount, exec.RecoveryCount) >= MaxGenerateQuoteJobAttempts {
+		return false, msgJobAttemptsExhausted
+	}
+	if !hasRequest {
+		return false, msgJobRequestMissing
+	}
+	return true, ""
+}
+
+// RecoverInterruptedGenerateQuoteJobs queues running jobs whose worker stopped
+// reporting, and queued jobs whose task got lost, again. Jobs out of attempts
+// fail so the user can start over.
+func (s *Service) RecoverInterruptedGenerateQuoteJobs(ctx context.Context, now time.Time) (GenerateQuoteJobRecoveryResult, error) {
+	var result GenerateQuoteJobRecoveryResult
+	jobs, err := s.repo.ListInterruptedGenerateQuoteJobs(ctx, now.Add(-generateQuoteJobStaleAfter), now.Add(-generateQuoteJobPendingStaleAfter), interruptedJobsBatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	for _, job := range jobs {
+		silentBefore := now.Add(-generateQuoteJobStaleAfter)
+		if job.Status == string(GenerateQuoteJobStatusPending) {
+			silentBefore = now.Add(-generateQuoteJobPendingStaleAfter)
+		}
+
+		requeue, reason := interruptedJobOutcome(job.Execution, job.Request.Prompt != nil)
+		if !requeue {
+			failed, err := s.repo.FailInterruptedGenerateQuoteJob(ctx, job.ID, silentBefore, jobStepInterrupted, reason, now)
+			if err != nil {
+				return result, err
+			}
+			if failed != nil {
+				result.Failed++
+				s.publishJobProgress(repositoryJobToServiceJob(failed))
+			}
+			continue
+		}
+
+		requeued, err := s.repo.RequeueInterruptedGenerateQuoteJob(ctx, job.ID, silentBefore, jobStepRequeued, now)
+		if err != nil {
+			return result, err
+		}
+		if requeued == nil {
+			continue
+		}
+		s.publishJobProgress(repositoryJobToServiceJob(requeued))
+		if err := s.enqueueInterruptedGenerateQuoteJob(ctx, job); err != nil {
+			// The job stays pending, so a later sweep queues it again.
+			return result, err
+		}
+		result.Requeued++
+	}
+	return result, nil
+}
+
+func (s *Service) enqueueInterruptedGenerateQuoteJob(ctx context.Context, job repository.InterruptedGenerateQuoteJob) error {
+	if s.jobQueue == nil {
+		return nil
+	}
+	err := s.jobQueue.EnqueueGenerateQuoteJobRequest(ctx, scheduler.GenerateQuoteJobRequest{
+		JobID:         job.ID,
+		TenantID:      job.OrganizationID,
+		UserID:        job.UserID,
+		LeadID:        job.LeadID,
+		LeadServiceID: job.LeadServiceID,
+		Prompt:        *job.Request.Prompt,
+		QuoteID:       job.Request.ExistingQuoteID,
+		Force:         job.Request.Force,
+	})
+	if errors.Is(err, scheduler.ErrDuplicateTask) {
+		// The original task is still held by the queue and will be retried.
+		return nil
+	}
+	return err
+}
+
+// takeOverInterruptedGenerateQuoteJob lets a retried task pick up a running
+// job whose previous worker stopped reporting. It returns false when the job
+// is still alive or out of attempts.
+func (s *Service) takeOverInterruptedGenerateQuoteJob(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	exec, err := s.repo.GetGenerateQuoteJobExecution(ctx, jobID)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	silentBefore := now.Add(-generateQuoteJobStaleAfter)
+	if requeue, reason := interruptedJobOutcome(exec, true); !requeue {
+		failed, err := s.repo.FailInterruptedGenerateQuoteJob(ctx, jobID, silentBefore, jobStepInterrupted, reason, now)
+		if err != nil {
+			return false, err
+		}
+		s.publishJobProgress(repositoryJobToServiceJob(failed))
+		return false, nil
+	}
+	requeued, err := s.repo.RequeueInterruptedGenerateQuoteJob(ctx, jobID, silentBefore, jobStepRequeued, now)
+	if err != nil {
+		return false, err
+	}
+	return requeued != nil, nil
+}
+
+// keepGenerateQuoteJobAlive reports that the job is running until the
+// returned stop function is called.
+func (s *Service) keepGenerateQuoteJobAlive(ctx context.Context, jobID uuid.UUID) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(generateQuoteJobHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				_ = s.repo.TouchGenerateQuoteJobHeartbeat(ctx, jobID, now)
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/quotes/repository"
+)
+
+func TestInterruptedJobOutcomeRequeuesWithAttemptsLeft(t *testing.T) {
+	requeue, reason := interruptedJobOutcome(repository.GenerateQuoteJobExecution{AttemptCount: 1, RecoveryCount: 1}, true)
+	if !requeue || reason != "" {
+		t.Fatalf("expected requeue, got requeue=%v reason=%q", requeue, reason)
+	}
+}
+
+func TestInterruptedJobOutcomeFailsWhenAttemptsExhausted(t *testing.T) {
+	for _, exec := range []repository.GenerateQuoteJobExecution{
+		{AttemptCount: MaxGenerateQuoteJobAttempts},
+		{AttemptCount: 1, RecoveryCount: MaxGenerateQuoteJobAttempts},
+	} {
+		if requeue, reason := interruptedJobOutcome(exec, true); requeue || reason != msgJobAttemptsExhausted {
+			t.Fatalf("expected failure for %+v, got requeue=%v reason=%q", exec, requeue, reason)
+		}
+	}
+}
+
+func TestInterruptedJobOutcomeFailsWithoutStoredRequest(t *testing.T) {
+	requeue, reason := interruptedJobOutcome(repository.GenerateQuoteJobExecution{}, false)
+	if requeue || reason != msgJobRequestMissing {
+		t.Fatalf("expected failure without request, got requeue=%v reason=%q", requeue, reason)
+	}
+}
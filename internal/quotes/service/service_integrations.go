@@ -15,10 +15,10 @@ import (
 	"strings"
 	"time"
 
-	"portal_final_backend/internal/identity/smtpcrypto"
 	"portal_final_backend/internal/quotes/repository"
 	"portal_final_backend/internal/quotes/transport"
 	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/secrets"
 
 	"github.com/google/uuid"
 )
@@ -26,7 +26,7 @@ import (
 const (
 	moneybirdAPIBaseURL         = "https://moneybird.com/api/v2"
 	defaultFrontendBaseURL      = "http://localhost:4200"
-	moneybirdOAuthDefaultScope  = "sales_invoices"
+	moneybirdOAuthDefaultScope  = "sales_invoices documents"
 	httpHeaderContentType       = "Content-Type"
 	httpHeaderAccept            = "Accept"
 	httpHeaderAuthorization     = "Authorization"
@@ -85,11 +85,11 @@ type oauthStatePayload struct {
 }
 
 type moneybirdConfig struct {
-	ClientID      string
-	ClientSecret  string
-	RedirectURI   string
-	FrontendURL   string
-	EncryptionKey []byte
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+	FrontendURL  string
+	Keyring      *secrets.Keyring
 }
 
 func (s *Service) SetMoneybirdConfig(clientID string, clientSecret string, redirectURI string, frontendURL string) {
@@ -103,10 +103,15 @@ func (s *Service) SetMoneybirdConfig(clientID string, clientSecret string, redir
 }
 
 func (s *Service) SetMoneybirdEncryptionKey(key []byte) {
+	s.SetMoneybirdKeyring(secrets.SingleKeyring(key))
+}
+
+// SetMoneybirdKeyring sets the keyring used to encrypt stored Moneybird tokens and sign OAuth state.
+func (s *Service) SetMoneybirdKeyring(keyring *secrets.Keyring) {
 	if s.moneybird == nil {
 		s.moneybird = &moneybirdConfig{}
 	}
-	s.moneybird.EncryptionKey = key
+	s.moneybird.Keyring = keyring
 }
 
 func (s *Service) MoneybirdIntegrationRedirectURL(status string) string {
@@ -123,7 +128,7 @@ func (s *Service) MoneybirdIntegrationRedirectURL(status string) string {
 }
 
 func (s *Service) buildOAuthState(tenantID uuid.UUID) (string, error) {
-	if s.moneybird == nil || len(s.moneybird.EncryptionKey) == 0 {
+	if s.moneybird == nil || s.moneybird.Keyring == nil {
 		return "", apperr.BadRequest("moneybird integration is not configured")
 	}
 	payload := oauthStatePayload{
@@ -136,7 +141,7 @@ func (s *Service) buildOAuthState(tenantID uuid.UUID) (string, error) {
 		return "", fmt.Errorf("marshal oauth state: %w", err)
 	}
 
-	mac := hmac.New(sha256.New, s.moneybird.EncryptionKey)
+	mac := hmac.New(sha256.New, s.moneybird.Keyring.PrimaryKey())
 	_, _ = mac.Write(raw)
 	sig := mac.Sum(nil)
 	combined := append(raw, sig...)
@@ -144,7 +149,7 @@ func (s *Service) buildOAuthState(tenantID uuid.UUID) (string, error) {
 }
 
 func (s *Service) parseOAuthState(state string) (uuid.UUID, error) {
-	if s.moneybird == nil || len(s.moneybird.EncryptionKey) == 0 {
+	if s.moneybird == nil || s.moneybird.Keyring == nil {
 		return uuid.Nil, apperr.BadRequest("moneybird integration is not configured")
 	}
 	decoded, err := base64.RawURLEncoding.DecodeString(state)
@@ -157,10 +162,18 @@ func (s *Service) parseOAuthState(state string) (uuid.UUID, error) {
 	raw := decoded[:len(decoded)-sha256.Size]
 	receivedSig := decoded[len(decoded)-sha256.Size:]
 
-	mac := hmac.New(sha256.New, s.moneybird.EncryptionKey)
-	_, _ = mac.Write(raw)
-	expectedSig := mac.Sum(nil)
-	if !hmac.Equal(receivedSig, expectedSig) {
+	// A state signed just before a key rotation still verifies against the
+	// previous key.
+	validSig := false
+	for _, key := range s.moneybird.Keyring.Keys() {
+		mac := hmac.New(sha256.New, key)
+		_, _ = mac.Write(raw)
+		if hmac.Equal(receivedSig, mac.Sum(nil)) {
+			validSig = true
+			break
+		}
+	}
+	if !validSig {
 		return uuid.Nil, apperr.BadRequest("invalid oauth state signature")
 	}
 
@@ -222,11 +235,11 @@ func (s *Service) HandleMoneybirdOAuthCallback(ctx context.Context, code string,
 		return nil, "", err
 	}
 
-	encryptedAccess, err := smtpcrypto.Encrypt(tokens.AccessToken, s.moneybird.EncryptionKey)
+	encryptedAccess, err := s.moneybird.Keyring.Encrypt(tokens.AccessToken)
 	if err != nil {
 		return nil, "", fmt.Errorf("encrypt access token: %w", err)
 	}
-	encryptedRefresh, err := smtpcrypto.Encrypt(tokens.RefreshToken, s.moneybird.EncryptionKey)
+	encryptedRefresh, err := s.moneybird.Keyring.Encrypt(tokens.RefreshToken)
 	if err != nil {
 		return nil, "", fmt.Errorf("encrypt refresh token: %w", err)
 	}
@@ -376,7 +389,7 @@ func (s *Service) moneybirdResolveAdministrationID(ctx context.Context, accessTo
 
 func normalizeProvider(provider string) (string, error) {
 	switch provider {
-	case "moneybird", "rompslomp", "stripe":
+	case "moneybird", "rompslomp", "stripe", mollieProvider:
 		return provider, nil
 	default:
 		return "", apperr.BadRequest("unsupported provider")
@@ -446,6 +459,21 @@ func (s *Service) GetQuoteExportStatus(ctx context.Context, quoteID, tenantID uu
 	}, nil
 }
 
+// InvoiceConfirmedWorkOrder exports the quote of a work order confirmed by the
+// customer to Moneybird. It does nothing when Moneybird is not connected.
+func (s *Service) InvoiceConfirmedWorkOrder(ctx context.Context, quoteID, tenantID uuid.UUID) error {
+	integration, err := s.repo.GetProviderIntegration(ctx, tenantID, "moneybird")
+	if err != nil {
+		return err
+	}
+	if integration == nil || !integration.IsConnected {
+		return nil
+	}
+
+	_, err = s.ExportQuoteToProvider(ctx, quoteID, tenantID, "moneybird")
+	return err
+}
+
 func (s *Service) ExportQuoteToProvider(ctx context.Context, quoteID, tenantID uuid.UUID, provider string) (*transport.QuoteExportResponse, error) {
 	normalizedProvider, err := normalizeProvider(provider)
 	if err != nil {
@@ -622,7 +650,7 @@ func (s *Service) moneybirdResolveContactID(
 }
 
 func (s *Service) validateMoneybirdExportIntegration(integration *repository.ProviderIntegration) error {
-	if s.moneybird == nil || len(s.moneybird.EncryptionKey) == 0 {
+	if s.moneybird == nil || s.moneybird.Keyring == nil {
 		return apperr.BadRequest("moneybird encryption key is not configured")
 	}
 	if integration.AccessToken == nil || integration.RefreshToken == nil || integration.AdministrationID == nil {
@@ -655,12 +683,12 @@ func (s *Service) resolveMoneybirdAccessToken(ctx context.Context, tenantID uuid
 }
 
 func (s *Service) decryptMoneybirdTokens(integration *repository.ProviderIntegration) (string, string, error) {
-	accessToken, err := smtpcrypto.Decrypt(*integration.AccessToken, s.moneybird.EncryptionKey)
+	accessToken, err := s.moneybird.Keyring.Decrypt(*integration.AccessToken)
 	if err != nil {
 		return "", "", fmt.Errorf("decrypt moneybird access token: %w", err)
 	}
 
-	refreshToken, err := smtpcrypto.Decrypt(*integration.RefreshToken, s.moneybird.EncryptionKey)
+	refreshToken, err := s.moneybird.Keyring.Decrypt(*integration.RefreshToken)
 	if err != nil {
 		return "", "", fmt.Errorf("decrypt moneybird refresh token: %w", err)
 	}
@@ -674,12 +702,12 @@ func (s *Service) persistRefreshedMoneybirdTokens(
 	integration *repository.ProviderIntegration,
 	refreshed *moneybirdOAuthTokenResponse,
 ) error {
-	encAccess, err := smtpcrypto.Encrypt(refreshed.AccessToken, s.moneybird.EncryptionKey)
+	encAccess, err := s.moneybird.Keyring.Encrypt(refreshed.AccessToken)
 	if err != nil {
 		return fmt.Errorf("encrypt refreshed access token: %w", err)
 	}
 
-	encRefresh, err := smtpcrypto.Encrypt(refreshed.RefreshToken, s.moneybird.EncryptionKey)
+	encRefresh, err := s.moneybird.Keyring.Encrypt(refreshed.RefreshToken)
 	if err != nil {
 		return fmt.Errorf("encrypt refreshed refresh token: %w", err)
 	}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/secrets"
+
+	"github.com/google/uuid"
+)
+
+func testKeyring(t *testing.T, spec string) *secrets.Keyring {
+	t.Helper()
+	kr, err := secrets.ParseKeyring(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kr
+}
+
+func TestParseOAuthStateAcceptsStatesSignedBeforeRotation(t *testing.T) {
+	oldKey := "old:" + hex.EncodeToString([]byte(strings.Repeat("a", secrets.KeySize)))
+	newKey := "new:" + hex.EncodeToString([]byte(strings.Repeat("b", secrets.KeySize)))
+	otherKey := "other:" + hex.EncodeToString([]byte(strings.Repeat("c", secrets.KeySize)))
+	tenantID := uuid.New()
+
+	before := &Service{moneybird: &moneybirdConfig{Keyring: testKeyring(t, oldKey)}}
+	state, err := before.buildOAuthState(tenantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := &Service{moneybird: &moneybirdConfig{Keyring: testKeyring(t, newKey+","+oldKey)}}
+	got, err := rotated.parseOAuthState(state)
+	if err != nil || got != tenantID {
+		t.Fatalf("parseOAuthState() = %v, %v, want %v", got, err, tenantID)
+	}
+
+	dropped := &Service{moneybird: &moneybirdConfig{Keyring: testKeyring(t, newKey+","+otherKey)}}
+	if _, err := dropped.parseOAuthState(state); !apperr.Is(err, apperr.KindBadRequest) {
+		t.Fatalf("expected a state signed by a removed key to be rejected, got %v", err)
+	}
+}
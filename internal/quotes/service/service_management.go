@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -108,6 +109,10 @@ func (s *Service) Create(ctx context.Context, tenantID uuid.UUID, actorID uuid.U
 	if err := applyQuoteSubsidySnapshot(&quote, req.ISDESubsidy); err != nil {
 		return nil, err
 	}
+	covered, err := s.resolveCoveredServices(ctx, tenantID, req.LeadID, req.LeadServiceID, req.CoveredLeadServiceIDs)
+	if err != nil {
+		return nil, err
+	}
 
 	items := make([]repository.QuoteItem, len(req.Items))
 	for i, it := range req.Items {
@@ -157,6 +162,11 @@ func (s *Service) Create(ctx context.Context, tenantID uuid.UUID, actorID uuid.U
 	if err := s.saveURLs(ctx, quote.ID, tenantID, req.URLs); err != nil {
 		return nil, fmt.Errorf(errSaveURLsFmt, err)
 	}
+	if len(covered) > 0 {
+		if err := s.repo.ReplaceCoveredServices(ctx, quote.ID, tenantID, covered); err != nil {
+			return nil, err
+		}
+	}
 
 	s.emitTimelineEvent(ctx, TimelineEventParams{
 		LeadID:         quote.LeadID,
@@ -290,10 +300,20 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, tenantID uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
+	if req.RowVersion != nil && quote.RowVersion != *req.RowVersion {
+		return nil, s.versionConflict(ctx, id, tenantID)
+	}
+	quote.ExpectedRowVersion = req.RowVersion
 
 	if err := s.validateQuoteUpdate(quote, req); err != nil {
 		return nil, err
 	}
+	var covered []uuid.UUID
+	if req.CoveredLeadServiceIDs != nil {
+		if covered, err = s.resolveCoveredServices(ctx, tenantID, quote.LeadID, quote.LeadServiceID, *req.CoveredLeadServiceIDs); err != nil {
+			return nil, err
+		}
+	}
 
 	pdfShouldInvalidate := quoteUpdateAffectsRenderedPDF(req)
 	applyQuoteUpdates(quote, req)
@@ -322,8 +342,16 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, tenantID uuid.UUID,
 	quote.UpdatedAt = time.Now()
 
 	if err := s.persistQuoteUpdate(ctx, quote, items, tenantID, actorID, req, pdfShouldInvalidate); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, s.versionConflict(ctx, id, tenantID)
+		}
 		return nil, err
 	}
+	if req.CoveredLeadServiceIDs != nil {
+		if err := s.repo.ReplaceCoveredServices(ctx, quote.ID, tenantID, covered); err != nil {
+			return nil, err
+		}
+	}
 
 	annotations, err := s.repo.ListAnnotationsByQuoteID(ctx, quote.ID)
 	if err != nil {
@@ -333,6 +361,16 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, tenantID uuid.UUID,
 	return s.buildResponse(ctx, quote, items, annotations)
 }
 
+// versionConflict reports an update based on an outdated version, with the
+// current quote so the client can reapply its change without another read.
+func (s *Service) versionConflict(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) error {
+	current, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return err
+	}
+	return apperr.Conflict("quote was changed by someone else").WithDetails(current)
+}
+
 func (s *Service) validateQuoteUpdate(quote *repository.Quote, req transport.UpdateQuoteRequest) error {
 	if req.ValidUntil != nil && quote.Status != string(transport.QuoteStatusDraft) &&
 		quote.Status != string(transport.QuoteStatusSent) &&
@@ -577,6 +615,9 @@ func (s *Service) buildListParams(tenantID uuid.UUID, req transport.ListQuotesRe
 	params.ValidUntilTo = validTo
 	params.TotalFrom = totalFrom
 	params.TotalTo = totalTo
+	if params.CustomFields, err = parseCustomFieldFilter(req.CustomFields); err != nil {
+		return repository.ListParams{}, err
+	}
 
 	if req.LeadID != "" {
 		parsed, err := uuid.Parse(req.LeadID)
@@ -751,7 +792,19 @@ func (s *Service) buildResponse(ctx context.Context, q *repository.Quote, items
 		annotationSlice = annotations[0]
 	}
 
-	return s.assembleQuoteResponse(q, items, annotationSlice, attachments, urls, duplicatedFromQuoteNumber, previousVersionQuoteNumber)
+	covered, err := s.repo.ListCoveredServiceIDs(ctx, q.ID, q.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.assembleQuoteResponse(q, items, annotationSlice, attachments, urls, duplicatedFromQuoteNumber, previousVersionQuoteNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(covered) > 0 {
+		resp.CoveredLeadServiceIDs = covered
+	}
+	return resp, nil
 }
 
 // assembleQuoteResponse maps fully loaded domain data to a transport response without performing any database calls.
@@ -797,6 +850,7 @@ func (s *Service) assembleQuoteResponse(q *repository.Quote, items []repository.
 		PreviousVersionQuoteNumber: previousVersionQuoteNumber,
 		VersionRootQuoteID:        q.VersionRootQuoteID,
 		VersionNumber:             q.VersionNumber,
+		RowVersion:                q.RowVersion,
 		LeadID:                    q.LeadID,
 		LeadServiceID:             q.LeadServiceID,
 		CreatedByID:               q.CreatedByID,
@@ -874,7 +928,11 @@ func (s *Service) saveAttachments(ctx context.Context, quoteID, orgID uuid.UUID,
 	for i, r := range reqs {
 		models[i] = repository.QuoteAttachment{ID: uuid.New(), QuoteID: quoteID, OrganizationID: orgID, Filename: r.Filename, FileKey: r.FileKey, Source: r.Source, CatalogProductID: r.CatalogProductID, Enabled: r.Enabled, SortOrder: r.SortOrder, CreatedAt: now}
 	}
-	return s.repo.ReplaceAttachments(ctx, quoteID, orgID, models)
+	if err := s.repo.ReplaceAttachments(ctx, quoteID, orgID, models); err != nil {
+		return err
+	}
+	s.pruneAttachmentPreviews(ctx, quoteID, orgID)
+	return nil
 }
 
 func (s *Service) saveURLs(ctx context.Context, quoteID, orgID uuid.UUID, reqs []transport.QuoteURLRequest) error {
@@ -1039,6 +1097,30 @@ func parseInt64Range(from string, to string, fromField string, toField string) (
 	return start, end, nil
 }
 
+// parseCustomFieldFilter checks that a customFields filter is a JSON object of
+// scalar values and returns it for a containment match on the stored values.
+func parseCustomFieldFilter(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var values map[string]any
+	if err := json.Unmarshal([]byte(raw), &values); err != nil {
+		return nil, apperr.Validation("customFields must be a JSON object")
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	for key, value := range values {
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			return nil, apperr.Validation(fmt.Sprintf("customFields.%s must be a string, number or boolean", key))
+		}
+	}
+	return json.Marshal(values)
+}
+
 func (s *Service) ListActivities(ctx context.Context, quoteID, tenantID uuid.UUID) ([]transport.QuoteActivityResponse, error) {
 	activities, err := s.repo.ListActivities(ctx, quoteID, tenantID)
 	if err != nil {
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	paymentScheduleFullBps   = 10000
+	installmentDateLayout    = "2006-01-02"
+	dueInstallmentsBatchSize = 200
+)
+
+// SetPaymentSchedule replaces the payment schedule of a quote. The schedule is part of
+// what the customer signs, so it is frozen once the quote is accepted or rejected.
+func (s *Service) SetPaymentSchedule(ctx context.Context, quoteID, tenantID, agentID uuid.UUID, req transport.SetPaymentScheduleRequest) (*transport.PaymentScheduleResponse, error) {
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status == string(transport.QuoteStatusAccepted) || quote.Status == string(transport.QuoteStatusRejected) {
+		return nil, apperr.Conflict("payment schedule cannot change after the quote is accepted or rejected")
+	}
+
+	installments, err := resolvePaymentInstallments(req.Installments)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplacePaymentSchedule(ctx, quote.ID, tenantID, installments); err != nil {
+		return nil, err
+	}
+
+	s.emitTimelineEvent(ctx, TimelineEventParams{
+		LeadID:         quote.LeadID,
+		ServiceID:      quote.LeadServiceID,
+		OrganizationID: tenantID,
+		ActorType:      "User",
+		ActorName:      agentID.String(),
+		EventType:      "quote_payment_schedule_updated",
+		Title:          fmt.Sprintf("Payment schedule updated for quote %s", quote.QuoteNumber),
+		Summary:        toPtr(fmt.Sprintf("%d installments", len(installments))),
+		Metadata:       map[string]any{"quoteId": quote.ID},
+	})
+
+	return s.GetPaymentSchedule(ctx, quoteID, tenantID)
+}
+
+// GetPaymentSchedule returns the payment schedule of a quote with derived amounts and due dates.
+func (s *Service) GetPaymentSchedule(ctx context.Context, quoteID, tenantID uuid.UUID) (*transport.PaymentScheduleResponse, error) {
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	installments, err := s.repo.ListPaymentInstallments(ctx, quote.ID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &transport.PaymentScheduleResponse{
+		QuoteID:      quote.ID,
+		TotalCents:   quote.TotalCents,
+		Installments: BuildPaymentSchedule(installments, quote.TotalCents, quote.AcceptedAt, time.Now()),
+	}, nil
+}
+
+// PublishDueInstallments publishes a QuoteInstallmentDue event for every installment of
+// an accepted quote that became due. Each installment is claimed before publishing so
+// overlapping sweeps announce it only once. It returns the number of published events.
+func (s *Service) PublishDueInstallments(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ListDueInstallments(ctx, now, dueInstallmentsBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, d := range due {
+		claimed, err := s.repo.MarkInstallmentDueNotified(ctx, d.InstallmentID, d.OrganizationID, now)
+		if err != nil {
+			return published, err
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.publishInstallmentDue(ctx, d); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+func (s *Service) publishInstallmentDue(ctx context.Context, d repository.DueInstallment) error {
+	quote, err := s.repo.GetByID(ctx, d.QuoteID, d.OrganizationID)
+	if err != nil {
+		return err
+	}
+	installments, err := s.repo.ListPaymentInstallments(ctx, quote.ID, quote.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	schedule := BuildPaymentSchedule(installments, quote.TotalCents, quote.AcceptedAt, d.DueAt)
+	for _, inst := range schedule {
+		if inst.ID != d.InstallmentID {
+			continue
+		}
+		if s.eventBus != nil {
+			publicToken := ""
+			if quote.PublicToken != nil {
+				publicToken = *quote.PublicToken
+			}
+			s.eventBus.Publish(ctx, events.QuoteInstallmentDue{
+				BaseEvent:      events.NewBaseEvent(),
+				QuoteID:        quote.ID,
+				OrganizationID: quote.OrganizationID,
+				LeadID:         quote.LeadID,
+				LeadServiceID:  quote.LeadServiceID,
+				InstallmentID:  inst.ID,
+				QuoteNumber:    quote.QuoteNumber,
+				Sequence:       inst.Sequence,
+				Label:          inst.Label,
+				AmountCents:    inst.AmountCents,
+				DueAt:          d.DueAt,
+				PublicToken:    publicToken,
+			})
+		}
+		s.emitTimelineEvent(ctx, TimelineEventParams{
+			LeadID:         quote.LeadID,
+			ServiceID:      quote.LeadServiceID,
+			OrganizationID: quote.OrganizationID,
+			ActorType:      "System",
+			ActorName:      "Scheduler",
+			EventType:      "quote_installment_due",
+			Title:          fmt.Sprintf("Installment %q of quote %s is due", inst.Label, quote.QuoteNumber),
+			Summary:        toPtr(fmt.Sprintf(msgTotalFormat, float64(inst.AmountCents)/100)),
+			Metadata:       map[string]any{"quoteId": quote.ID, "installmentId": inst.ID, "dueAt": d.DueAt},
+		})
+		return nil
+	}
+	return nil
+}
+
+// resolvePaymentInstallments validates a requested schedule and keeps only the due
+// fields that match each installment's trigger.
+func resolvePaymentInstallments(reqs []transport.PaymentInstallmentRequest) ([]repository.PaymentInstallment, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	installments := make([]repository.PaymentInstallment, 0, len(reqs))
+	totalBps := 0
+	for i, req := range reqs {
+		inst := repository.PaymentInstallment{
+			Label:         strings.TrimSpace(req.Label),
+			PercentageBps: req.PercentageBps,
+			DueTrigger:    req.DueTrigger,
+		}
+		if inst.Label == "" {
+			return nil, apperr.Validation("installment label is required").WithDetails(map[string]any{"index": i})
+		}
+
+		switch req.DueTrigger {
+		case repository.InstallmentDueDaysAfterAcceptance:
+			if req.DueAfterDays == nil {
+				return nil, apperr.Validation("dueAfterDays is required for days_after_acceptance installments").WithDetails(map[string]any{"index": i})
+			}
+			days := *req.DueAfterDays
+			inst.DueAfterDays = &days
+		case repository.InstallmentDueOnDate:
+			if req.DueDate == nil {
+				return nil, apperr.Validation("dueDate is required for on_date installments").WithDetails(map[string]any{"index": i})
+			}
+			date, err := time.Parse(installmentDateLayout, *req.DueDate)
+			if err != nil {
+				return nil, apperr.Validation("dueDate must be formatted as YYYY-MM-DD").WithDetails(map[string]any{"index": i})
+			}
+			inst.DueDate = &date
+		}
+
+		totalBps += req.PercentageBps
+		installments = append(installments, inst)
+	}
+
+	if totalBps != paymentScheduleFullBps {
+		return nil, apperr.Validation("installment percentages must add up to 100%").WithDetails(map[string]any{"totalBps": totalBps})
+	}
+	return installments, nil
+}
+
+// BuildPaymentSchedule derives amounts and due dates for a stored schedule. Amounts are
+// rounded per installment and the last installment absorbs the rounding difference, so
+// the installments always add up to the quote total.
+func BuildPaymentSchedule(installments []repository.PaymentInstallment, totalCents int64, acceptedAt *time.Time, now time.Time) []transport.PaymentInstallmentResponse {
+	if len(installments) == 0 {
+		return nil
+	}
+
+	result := make([]transport.PaymentInstallmentResponse, len(installments))
+	var allocated int64
+	for i, inst := range installments {
+		amount := roundCents(float64(totalCents) * float64(inst.PercentageBps) / paymentScheduleFullBps)
+		if i == len(installments)-1 {
+			amount = totalCents - allocated
+		}
+		allocated += amount
+
+		resp := transport.PaymentInstallmentResponse{
+			ID:            inst.ID,
+			Sequence:      inst.Sequence,
+			Label:         inst.Label,
+			PercentageBps: inst.PercentageBps,
+			AmountCents:   amount,
+			DueTrigger:    inst.DueTrigger,
+			DueAfterDays:  inst.DueAfterDays,
+			DueAt:         installmentDueAt(inst, acceptedAt),
+		}
+		if inst.DueDate != nil {
+			resp.DueDate = toPtr(inst.DueDate.Format(installmentDateLayout))
+		}
+		resp.IsDue = acceptedAt != nil && resp.DueAt != nil && !resp.DueAt.After(now)
+		result[i] = resp
+	}
+	return result
+}
+
+// installmentDueAt resolves the moment an installment becomes due. Acceptance-relative
+// installments have no due date until the quote is accepted.
+func installmentDueAt(inst repository.PaymentInstallment, acceptedAt *time.Time) *time.Time {
+	switch inst.DueTrigger {
+	case repository.InstallmentDueOnDate:
+		return inst.DueDate
+	case repository.InstallmentDueDaysAfterAcceptance:
+		if acceptedAt == nil {
+			return nil
+		}
+		days := 0
+		if inst.DueAfterDays != nil {
+			days = *inst.DueAfterDays
+		}
+		due := acceptedAt.AddDate(0, 0, days)
+		return &due
+	default:
+		return acceptedAt
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+)
+
+func TestResolvePaymentInstallmentsRequiresFullPercentage(t *testing.T) {
+	_, err := resolvePaymentInstallments([]transport.PaymentInstallmentRequest{
+		{Label: "Aanbetaling", PercentageBps: 3000, DueTrigger: repository.InstallmentDueOnAcceptance},
+		{Label: "Oplevering", PercentageBps: 6000, DueTrigger: repository.InstallmentDueOnAcceptance},
+	})
+	if err == nil {
+		t.Fatal("expected error when percentages do not add up to 100%")
+	}
+}
+
+func TestResolvePaymentInstallmentsKeepsOnlyMatchingDueFields(t *testing.T) {
+	days := 30
+	date := "2026-06-01"
+	installments, err := resolvePaymentInstallments([]transport.PaymentInstallmentRequest{
+		{Label: "Aanbetaling", PercentageBps: 3000, DueTrigger: repository.InstallmentDueOnAcceptance, DueAfterDays: &days},
+		{Label: "Start", PercentageBps: 6000, DueTrigger: repository.InstallmentDueDaysAfterAcceptance, DueAfterDays: &days},
+		{Label: "Oplevering", PercentageBps: 1000, DueTrigger: repository.InstallmentDueOnDate, DueDate: &date},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if installments[0].DueAfterDays != nil {
+		t.Fatal("expected dueAfterDays to be dropped for on_acceptance installment")
+	}
+	if installments[1].DueAfterDays == nil || *installments[1].DueAfterDays != 30 {
+		t.Fatalf("expected dueAfterDays 30, got %v", installments[1].DueAfterDays)
+	}
+	if installments[2].DueDate == nil || installments[2].DueDate.Format("2006-01-02") != date {
+		t.Fatalf("expected due date %s, got %v", date, installments[2].DueDate)
+	}
+
+	if _, err := resolvePaymentInstallments([]transport.PaymentInstallmentRequest{
+		{Label: "Start", PercentageBps: 10000, DueTrigger: repository.InstallmentDueDaysAfterAcceptance},
+	}); err == nil {
+		t.Fatal("expected error when dueAfterDays is missing")
+	}
+}
+
+func TestBuildPaymentScheduleAllocatesRemainderToLastInstallment(t *testing.T) {
+	days := 14
+	acceptedAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	installments := []repository.PaymentInstallment{
+		{Sequence: 1, Label: "Aanbetaling", PercentageBps: 3333, DueTrigger: repository.InstallmentDueOnAcceptance},
+		{Sequence: 2, Label: "Start", PercentageBps: 3333, DueTrigger: repository.InstallmentDueDaysAfterAcceptance, DueAfterDays: &days},
+		{Sequence: 3, Label: "Oplevering", PercentageBps: 3334, DueTrigger: repository.InstallmentDueDaysAfterAcceptance, DueAfterDays: &days},
+	}
+
+	schedule := BuildPaymentSchedule(installments, 100001, &acceptedAt, acceptedAt.AddDate(0, 0, 1))
+
+	var total int64
+	for _, inst := range schedule {
+		total += inst.AmountCents
+	}
+	if total != 100001 {
+		t.Fatalf("expected installments to add up to the quote total, got %d", total)
+	}
+	if !schedule[0].IsDue || schedule[1].IsDue {
+		t.Fatalf("expected only the first installment to be due, got %v and %v", schedule[0].IsDue, schedule[1].IsDue)
+	}
+	if want := acceptedAt.AddDate(0, 0, 14); schedule[1].DueAt == nil || !schedule[1].DueAt.Equal(want) {
+		t.Fatalf("expected due at %v, got %v", want, schedule[1].DueAt)
+	}
+}
+
+func TestBuildPaymentScheduleHasNoRelativeDueDatesBeforeAcceptance(t *testing.T) {
+	schedule := BuildPaymentSchedule([]repository.PaymentInstallment{
+		{Sequence: 1, Label: "Aanbetaling", PercentageBps: 10000, DueTrigger: repository.InstallmentDueOnAcceptance},
+	}, 5000, nil, time.Now())
+
+	if schedule[0].DueAt != nil || schedule[0].IsDue {
+		t.Fatalf("expected no due date before acceptance, got %+v", schedule[0])
+	}
+}
@@ -0,0 +1,465 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/mollie"
+	"portal_final_backend/platform/secrets"
+
+	"github.com/google/uuid"
+)
+
+const (
+	mollieProvider         = "mollie"
+	mollieWebhookPath      = "/api/v1/quotes/integrations/mollie/webhook"
+	mollieCurrencyEUR      = "EUR"
+	mollieNotConnectedMsg  = "mollie integration is not connected"
+	mollieNotConfiguredMsg = "mollie integration is not configured"
+	quotePublicPathPrefix  = "/quote/"
+	depositPaymentDescFmt  = "Aanbetaling offerte %s"
+)
+
+type mollieConfig struct {
+	PublicBaseURL    string
+	PublicAPIBaseURL string
+	Client           *mollie.Client
+	Keyring          *secrets.Keyring
+	// PaymentLocator finds the organization of a Mollie payment ID across the
+	// data schemas of all organizations.
+	PaymentLocator httpkit.PublicTokenResolver
+}
+
+type mollieAmount struct {
+	Currency string `json:"currency"`
+	Value    string `json:"value"`
+}
+
+type mollieCreatePaymentBody struct {
+	Amount      mollieAmount      `json:"amount"`
+	Description string            `json:"description"`
+	RedirectURL string            `json:"redirectUrl"`
+	WebhookURL  string            `json:"webhookUrl,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+type molliePayment struct {
+	ID     string     `json:"id"`
+	Status string     `json:"status"`
+	PaidAt *time.Time `json:"paidAt"`
+	Links  struct {
+		Checkout *struct {
+			Href string `json:"href"`
+		} `json:"checkout"`
+	} `json:"_links"`
+}
+
+// SetMollieConfig sets the public URLs used for Mollie redirect and webhook URLs.
+func (s *Service) SetMollieConfig(publicBaseURL string, publicAPIBaseURL string) {
+	if s.mollie == nil {
+		s.mollie = &mollieConfig{}
+	}
+	s.mollie.PublicBaseURL = strings.TrimSpace(publicBaseURL)
+	s.mollie.PublicAPIBaseURL = strings.TrimSpace(publicAPIBaseURL)
+}
+
+// SetMollieKeyring sets the keyring used to encrypt stored Mollie API keys.
+func (s *Service) SetMollieKeyring(keyring *secrets.Keyring) {
+	if s.mollie == nil {
+		s.mollie = &mollieConfig{}
+	}
+	s.mollie.Keyring = keyring
+}
+
+// SetMolliePaymentLocator sets the lookup of the organization of a Mollie
+// payment ID, so webhooks read the data schema the payment lives in.
+func (s *Service) SetMolliePaymentLocator(locator httpkit.PublicTokenResolver) {
+	if s.mollie == nil {
+		s.mollie = &mollieConfig{}
+	}
+	s.mollie.PaymentLocator = locator
+}
+
+func (s *Service) mollieConfigured() bool {
+	return s.mollie != nil && s.mollie.Keyring != nil
+}
+
+// ConnectMollie verifies and stores the Mollie API key of an organization.
+func (s *Service) ConnectMollie(ctx context.Context, tenantID, userID uuid.UUID, req transport.ConnectMollieRequest) (*transport.ProviderIntegrationStatusResponse, error) {
+	if !s.mollieConfigured() {
+		return nil, apperr.BadRequest(mollieNotConfiguredMsg)
+	}
+	apiKey := strings.TrimSpace(req.APIKey)
+	if !strings.HasPrefix(apiKey, "live_") && !strings.HasPrefix(apiKey, "test_") {
+		return nil, apperr.Validation("mollie API key must start with live_ or test_")
+	}
+	if err := s.mollieClient().Do(ctx, apiKey, http.MethodGet, "/methods", nil, nil); err != nil {
+		return nil, apperr.BadRequest("mollie rejected the API key")
+	}
+
+	encrypted, err := s.mollie.Keyring.Encrypt(apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt mollie api key: %w", err)
+	}
+	if err := s.repo.UpsertProviderIntegration(ctx, repository.ProviderIntegration{
+		OrganizationID: tenantID,
+		Provider:       mollieProvider,
+		IsConnected:    true,
+		AccessToken:    &encrypted,
+		ConnectedBy:    &userID,
+	}); err != nil {
+		return nil, err
+	}
+	return s.GetProviderIntegrationStatus(ctx, tenantID, mollieProvider)
+}
+
+// ListQuotePayments returns the online payments of a quote, newest first.
+func (s *Service) ListQuotePayments(ctx context.Context, quoteID, tenantID uuid.UUID) ([]transport.QuotePaymentResponse, error) {
+	if _, err := s.repo.GetByID(ctx, quoteID, tenantID); err != nil {
+		return nil, err
+	}
+	payments, err := s.repo.ListQuotePayments(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]transport.QuotePaymentResponse, len(payments))
+	for i := range payments {
+		result[i] = toQuotePaymentResponse(payments[i])
+	}
+	return result, nil
+}
+
+// EnsureDepositPayment returns the deposit payment of an accepted quote for the customer,
+// creating a new Mollie payment link when there is none or the previous one lapsed.
+func (s *Service) EnsureDepositPayment(ctx context.Context, token string) (*transport.QuotePaymentResponse, error) {
+	quote, _, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != string(transport.QuoteStatusAccepted) {
+		return nil, apperr.Conflict("deposit can only be paid after the quote is accepted")
+	}
+	payment, err := s.ensureDepositPayment(ctx, quote)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, apperr.NotFound("this quote has no deposit to pay")
+	}
+	resp := toQuotePaymentResponse(*payment)
+	return &resp, nil
+}
+
+// createDepositPaymentOnAccept creates the deposit payment link right after acceptance.
+// It is best-effort: the customer can still request the link later.
+func (s *Service) createDepositPaymentOnAccept(ctx context.Context, quote *repository.Quote) {
+	if !s.mollieConfigured() {
+		return
+	}
+	_, _ = s.ensureDepositPayment(ctx, quote)
+}
+
+func (s *Service) ensureDepositPayment(ctx context.Context, quote *repository.Quote) (*repository.QuotePayment, error) {
+	installments, err := s.repo.ListPaymentInstallments(ctx, quote.ID, quote.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	deposit := depositInstallment(BuildPaymentSchedule(installments, quote.TotalCents, quote.AcceptedAt, time.Now()))
+	if deposit == nil || deposit.AmountCents <= 0 {
+		return nil, nil
+	}
+
+	payments, err := s.repo.ListQuotePayments(ctx, quote.ID, quote.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if existing := latestInstallmentPayment(payments, deposit.ID); existing != nil && !paymentLapsed(existing.Status) {
+		return existing, nil
+	}
+
+	if !s.mollieConfigured() {
+		return nil, apperr.BadRequest(mollieNotConfiguredMsg)
+	}
+	apiKey, err := s.resolveMollieAPIKey(ctx, quote.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := mollieCreatePaymentBody{
+		Amount:      mollieAmount{Currency: mollieCurrencyEUR, Value: formatMollieAmount(deposit.AmountCents)},
+		Description: fmt.Sprintf(depositPaymentDescFmt, quote.QuoteNumber),
+		RedirectURL: s.mollieRedirectURL(quote),
+		WebhookURL:  s.mollieWebhookURL(),
+		Metadata: map[string]string{
+			"quoteId":        quote.ID.String(),
+			"organizationId": quote.OrganizationID.String(),
+			"installmentId":  deposit.ID.String(),
+		},
+	}
+	var remote molliePayment
+	if err := s.mollieClient().Do(ctx, apiKey, http.MethodPost, "/payments", body, &remote); err != nil {
+		return nil, err
+	}
+
+	var checkoutURL *string
+	if remote.Links.Checkout != nil && remote.Links.Checkout.Href != "" {
+		checkoutURL = &remote.Links.Checkout.Href
+	}
+	installmentID := deposit.ID
+	payment, err := s.repo.CreateQuotePayment(ctx, repository.QuotePayment{
+		OrganizationID: quote.OrganizationID,
+		QuoteID:        quote.ID,
+		InstallmentID:  &installmentID,
+		Provider:       mollieProvider,
+		ExternalID:     remote.ID,
+		CheckoutURL:    checkoutURL,
+		AmountCents:    deposit.AmountCents,
+		Currency:       mollieCurrencyEUR,
+		Status:         normalizeMolliePaymentStatus(remote.Status),
+	})
+	if apperr.Is(err, apperr.KindConflict) {
+		// A concurrent request stored its payment first: cancel ours and hand out that one.
+		_ = s.mollieClient().Do(ctx, apiKey, http.MethodDelete, "/payments/"+url.PathEscape(remote.ID), nil, nil)
+		payments, err := s.repo.ListQuotePayments(ctx, quote.ID, quote.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if existing := latestInstallmentPayment(payments, deposit.ID); existing != nil && !paymentLapsed(existing.Status) {
+			return existing, nil
+		}
+		return nil, apperr.Conflict("the deposit payment is being created, try again")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.emitTimelineEvent(ctx, TimelineEventParams{
+		LeadID:         quote.LeadID,
+		ServiceID:      quote.LeadServiceID,
+		OrganizationID: quote.OrganizationID,
+		ActorType:      "System",
+		ActorName:      "Mollie",
+		EventType:      "quote_payment_link_created",
+		Title:          fmt.Sprintf("Deposit payment link created for quote %s", quote.QuoteNumber),
+		Summary:        toPtr(fmt.Sprintf(msgTotalFormat, float64(deposit.AmountCents)/100)),
+		Metadata:       map[string]any{"quoteId": quote.ID, "paymentId": payment.ID, "installmentId": deposit.ID},
+	})
+	return payment, nil
+}
+
+// HandleMollieWebhook processes a Mollie payment webhook. Mollie only posts the payment ID,
+// so the status is always fetched from the Mollie API instead of trusting the request.
+// Unknown payment IDs are acknowledged without error.
+func (s *Service) HandleMollieWebhook(ctx context.Context, externalID string) error {
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" {
+		return apperr.BadRequest("payment id is required")
+	}
+	if s.mollie != nil && s.mollie.PaymentLocator != nil {
+		organizationID, ok, err := s.mollie.PaymentLocator(ctx, externalID)
+		if err != nil || !ok {
+			return err
+		}
+		if ctx, err = httpkit.BindTenant(ctx, organizationID); err != nil {
+			return err
+		}
+	}
+	payment, err := s.repo.GetQuotePaymentByExternalID(ctx, mollieProvider, externalID)
+	if err != nil {
+		if apperr.Is(err, apperr.KindNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !s.mollieConfigured() {
+		return apperr.BadRequest(mollieNotConfiguredMsg)
+	}
+	apiKey, err := s.resolveMollieAPIKey(ctx, payment.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	var remote molliePayment
+	if err := s.mollieClient().Do(ctx, apiKey, http.MethodGet, "/payments/"+url.PathEscape(externalID), nil, &remote); err != nil {
+		return err
+	}
+	status := normalizeMolliePaymentStatus(remote.Status)
+	changed, err := s.repo.UpdateQuotePaymentStatus(ctx, payment.ID, payment.OrganizationID, status, remote.PaidAt)
+	if err != nil || !changed {
+		return err
+	}
+
+	quote, err := s.repo.GetByID(ctx, payment.QuoteID, payment.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.QuotePaymentUpdated{
+			BaseEvent:      events.NewBaseEvent(),
+			QuoteID:        quote.ID,
+			OrganizationID: quote.OrganizationID,
+			LeadID:         quote.LeadID,
+			LeadServiceID:  quote.LeadServiceID,
+			PaymentID:      payment.ID,
+			InstallmentID:  payment.InstallmentID,
+			QuoteNumber:    quote.QuoteNumber,
+			Provider:       payment.Provider,
+			Status:         status,
+			AmountCents:    payment.AmountCents,
+			PaidAt:         remote.PaidAt,
+		})
+	}
+
+	eventType := "quote_payment_status_changed"
+	title := fmt.Sprintf("Deposit payment for quote %s is %s", quote.QuoteNumber, status)
+	if status == repository.PaymentStatusPaid {
+		eventType = "quote_payment_paid"
+		title = fmt.Sprintf("Deposit for quote %s paid", quote.QuoteNumber)
+	}
+	s.emitTimelineEvent(ctx, TimelineEventParams{
+		LeadID:         quote.LeadID,
+		ServiceID:      quote.LeadServiceID,
+		OrganizationID: quote.OrganizationID,
+		ActorType:      "System",
+		ActorName:      "Mollie",
+		EventType:      eventType,
+		Title:          title,
+		Summary:        toPtr(fmt.Sprintf(msgTotalFormat, float64(payment.AmountCents)/100)),
+		Metadata:       map[string]any{"quoteId": quote.ID, "paymentId": payment.ID, "status": status},
+	})
+	return nil
+}
+
+// loadDepositPayment returns the latest payment of the deposit installment for the public
+// quote page, or nil when the quote has no deposit payment yet.
+func (s *Service) loadDepositPayment(ctx context.Context, quote *repository.Quote, schedule []transport.PaymentInstallmentResponse) *transport.QuotePaymentResponse {
+	deposit := depositInstallment(schedule)
+	if deposit == nil {
+		return nil
+	}
+	payments, err := s.repo.ListQuotePayments(ctx, quote.ID, quote.OrganizationID)
+	if err != nil {
+		return nil
+	}
+	payment := latestInstallmentPayment(payments, deposit.ID)
+	if payment == nil {
+		return nil
+	}
+	resp := toQuotePaymentResponse(*payment)
+	return &resp
+}
+
+func (s *Service) resolveMollieAPIKey(ctx context.Context, orgID uuid.UUID) (string, error) {
+	integration, err := s.repo.GetProviderIntegration(ctx, orgID, mollieProvider)
+	if err != nil {
+		return "", err
+	}
+	if integration == nil || !integration.IsConnected || integration.AccessToken == nil {
+		return "", apperr.BadRequest(mollieNotConnectedMsg)
+	}
+	apiKey, err := s.mollie.Keyring.Decrypt(*integration.AccessToken)
+	if err != nil {
+		return "", fmt.Errorf("decrypt mollie api key: %w", err)
+	}
+	return apiKey, nil
+}
+
+func (s *Service) mollieRedirectURL(quote *repository.Quote) string {
+	base := strings.TrimRight(s.mollie.PublicBaseURL, "/")
+	if base == "" {
+		base = defaultFrontendBaseURL
+	}
+	token := ""
+	if quote.PublicToken != nil {
+		token = *quote.PublicToken
+	}
+	return base + quotePublicPathPrefix + url.PathEscape(token) + "?payment=return"
+}
+
+// mollieWebhookURL returns the webhook URL, or an empty string when no public API URL is
+// configured (Mollie rejects webhook URLs it cannot reach, e.g. localhost).
+func (s *Service) mollieWebhookURL() string {
+	base := strings.TrimRight(s.mollie.PublicAPIBaseURL, "/")
+	if base == "" {
+		return ""
+	}
+	return base + mollieWebhookPath
+}
+
+func (s *Service) mollieClient() *mollie.Client {
+	if s.mollie != nil && s.mollie.Client != nil {
+		return s.mollie.Client
+	}
+	return mollie.NewClient("")
+}
+
+// depositInstallment returns the installment that is due on acceptance; that is the
+// deposit the customer pays online.
+func depositInstallment(schedule []transport.PaymentInstallmentResponse) *transport.PaymentInstallmentResponse {
+	for i := range schedule {
+		if schedule[i].DueTrigger == repository.InstallmentDueOnAcceptance {
+			return &schedule[i]
+		}
+	}
+	return nil
+}
+
+func latestInstallmentPayment(payments []repository.QuotePayment, installmentID uuid.UUID) *repository.QuotePayment {
+	for i := range payments {
+		if payments[i].InstallmentID != nil && *payments[i].InstallmentID == installmentID {
+			return &payments[i]
+		}
+	}
+	return nil
+}
+
+// paymentLapsed reports whether a payment can no longer be completed and a new payment
+// link is needed.
+func paymentLapsed(status string) bool {
+	switch status {
+	case repository.PaymentStatusCanceled, repository.PaymentStatusExpired, repository.PaymentStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+func normalizeMolliePaymentStatus(status string) string {
+	switch status {
+	case repository.PaymentStatusPending, repository.PaymentStatusAuthorized, repository.PaymentStatusPaid,
+		repository.PaymentStatusCanceled, repository.PaymentStatusExpired, repository.PaymentStatusFailed:
+		return status
+	default:
+		return repository.PaymentStatusOpen
+	}
+}
+
+func formatMollieAmount(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+func toQuotePaymentResponse(p repository.QuotePayment) transport.QuotePaymentResponse {
+	resp := transport.QuotePaymentResponse{
+		ID:            p.ID,
+		InstallmentID: p.InstallmentID,
+		Provider:      p.Provider,
+		Status:        p.Status,
+		AmountCents:   p.AmountCents,
+		Currency:      p.Currency,
+		PaidAt:        p.PaidAt,
+		CreatedAt:     p.CreatedAt,
+	}
+	if p.Status == repository.PaymentStatusOpen {
+		resp.CheckoutURL = p.CheckoutURL
+	}
+	return resp
+}
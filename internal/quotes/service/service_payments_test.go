@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/mollie"
+
+	"github.com/google/uuid"
+)
+
+func TestFormatMollieAmount(t *testing.T) {
+	cases := map[int64]string{5: "0.05", 100: "1.00", 123456: "1234.56"}
+	for cents, want := range cases {
+		if got := formatMollieAmount(cents); got != want {
+			t.Fatalf("formatMollieAmount(%d) = %q, want %q", cents, got, want)
+		}
+	}
+}
+
+func TestDepositInstallmentPicksInstallmentDueOnAcceptance(t *testing.T) {
+	depositID := uuid.New()
+	schedule := []transport.PaymentInstallmentResponse{
+		{ID: uuid.New(), DueTrigger: repository.InstallmentDueOnDate},
+		{ID: depositID, DueTrigger: repository.InstallmentDueOnAcceptance},
+	}
+
+	deposit := depositInstallment(schedule)
+	if deposit == nil || deposit.ID != depositID {
+		t.Fatalf("expected deposit installment %s, got %+v", depositID, deposit)
+	}
+	if depositInstallment(schedule[:1]) != nil {
+		t.Fatal("expected no deposit without an on_acceptance installment")
+	}
+}
+
+func TestToQuotePaymentResponseOnlyExposesCheckoutURLWhileOpen(t *testing.T) {
+	checkout := "https://www.mollie.com/checkout/test"
+	payment := repository.QuotePayment{ID: uuid.New(), Status: repository.PaymentStatusOpen, CheckoutURL: &checkout}
+
+	if resp := toQuotePaymentResponse(payment); resp.CheckoutURL == nil {
+		t.Fatal("expected checkout URL for an open payment")
+	}
+	payment.Status = repository.PaymentStatusPaid
+	if resp := toQuotePaymentResponse(payment); resp.CheckoutURL != nil {
+		t.Fatal("expected no checkout URL for a paid payment")
+	}
+}
+
+func TestMollieClientCreatesPaymentWithAPIKey(t *testing.T) {
+	var gotAuth string
+	var gotBody mollieCreatePaymentBody
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"tr_test","status":"open","_links":{"checkout":{"href":"https://www.mollie.com/checkout/tr_test"}}}`))
+	}))
+	defer srv.Close()
+
+	svc := &Service{mollie: &mollieConfig{Client: mollie.NewClient(srv.URL)}}
+	var remote molliePayment
+	err := svc.mollieClient().Do(context.Background(), "test_key", http.MethodPost, "/payments", mollieCreatePaymentBody{
+		Amount: mollieAmount{Currency: mollieCurrencyEUR, Value: "10.00"},
+	}, &remote)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "Bearer test_key" {
+		t.Fatalf("expected bearer API key, got %q", gotAuth)
+	}
+	if gotBody.Amount.Value != "10.00" {
+		t.Fatalf("expected amount 10.00, got %q", gotBody.Amount.Value)
+	}
+	if remote.ID != "tr_test" || remote.Links.Checkout == nil {
+		t.Fatalf("unexpected mollie payment %+v", remote)
+	}
+	if normalizeMolliePaymentStatus("unknown") != repository.PaymentStatusOpen {
+		t.Fatal("expected unknown statuses to map to open")
+	}
+}
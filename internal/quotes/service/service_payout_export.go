@@ -0,0 +1,201 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// PayoutInvoice is a partner payout statement booked as a purchase invoice of
+// the partner.
+type PayoutInvoice struct {
+	Reference    string
+	Date         time.Time
+	PartnerID    uuid.UUID
+	PartnerName  string
+	PartnerEmail string
+	Lines        []PayoutInvoiceLine
+}
+
+// PayoutInvoiceLine is a line of a payout invoice; negative amounts are
+// withheld from the partner.
+type PayoutInvoiceLine struct {
+	Description string
+	AmountCents int64
+}
+
+type moneybirdPurchaseInvoiceLine struct {
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+}
+
+type moneybirdCreatePurchaseInvoiceBody struct {
+	PurchaseInvoice struct {
+		ContactID         string                         `json:"contact_id"`
+		Reference         string                         `json:"reference"`
+		Date              string                         `json:"date"`
+		PricesAreInclTax  bool                           `json:"prices_are_incl_tax"`
+		DetailsAttributes []moneybirdPurchaseInvoiceLine `json:"details_attributes"`
+	} `json:"purchase_invoice"`
+}
+
+// ExportPayoutInvoice books a partner payout in the accounting provider and
+// returns the external id and URL of the booking. The partner is matched on
+// its id as Moneybird customer id, then on email, and created otherwise.
+func (s *Service) ExportPayoutInvoice(ctx context.Context, tenantID uuid.UUID, provider string, invoice PayoutInvoice) (string, string, error) {
+	normalizedProvider, err := normalizeProvider(provider)
+	if err != nil {
+		return "", "", err
+	}
+	integration, err := s.repo.GetProviderIntegration(ctx, tenantID, normalizedProvider)
+	if err != nil {
+		return "", "", err
+	}
+	if integration == nil || !integration.IsConnected {
+		return "", "", apperr.BadRequest("provider is not connected")
+	}
+	if normalizedProvider != "moneybird" {
+		return "", "", apperr.BadRequest("provider not yet implemented")
+	}
+	if err := s.validateMoneybirdExportIntegration(integration); err != nil {
+		return "", "", err
+	}
+	accessToken, err := s.resolveMoneybirdAccessToken(ctx, tenantID, integration)
+	if err != nil {
+		return "", "", err
+	}
+
+	administrationID := *integration.AdministrationID
+	contactID, err := s.moneybirdFindContactIDByCustomerID(ctx, administrationID, accessToken, invoice.PartnerID.String())
+	if err != nil {
+		return "", "", err
+	}
+	if contactID == "" {
+		email := invoice.PartnerEmail
+		contactID, err = s.moneybirdFindContactIDByEmail(ctx, administrationID, accessToken, &email)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if contactID == "" {
+		contactID, err = s.moneybirdCreateCompanyContact(ctx, administrationID, accessToken, invoice.PartnerID.String(), invoice.PartnerName, invoice.PartnerEmail)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return s.createMoneybirdPurchaseInvoice(ctx, administrationID, accessToken, contactID, invoice)
+}
+
+func (s *Service) createMoneybirdPurchaseInvoice(ctx context.Context, administrationID, accessToken, contactID string, invoice PayoutInvoice) (string, string, error) {
+	body := moneybirdCreatePurchaseInvoiceBody{}
+	body.PurchaseInvoice.ContactID = contactID
+	body.PurchaseInvoice.Reference = invoice.Reference
+	body.PurchaseInvoice.Date = invoice.Date.Format("2006-01-02")
+	body.PurchaseInvoice.DetailsAttributes = make([]moneybirdPurchaseInvoiceLine, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		body.PurchaseInvoice.DetailsAttributes = append(body.PurchaseInvoice.DetailsAttributes, moneybirdPurchaseInvoiceLine{
+			Description: line.Description,
+			Price:       float64(line.AmountCents) / 100,
+		})
+	}
+
+	rawBody, err := json.Marshal(body)
+	if err != nil {
+		return "", "", fmt.Errorf("marshal moneybird purchase invoice body: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/documents/purchase_invoices", moneybirdAPIBaseURL, administrationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(rawBody))
+	if err != nil {
+		return "", "", fmt.Errorf("build moneybird purchase invoice request: %w", err)
+	}
+	req.Header.Set(httpHeaderAuthorization, authorizationBearerPrefix+accessToken)
+	req.Header.Set(httpHeaderAccept, mimeApplicationJSON)
+	req.Header.Set(httpHeaderContentType, mimeApplicationJSON)
+
+	resp, err := (&http.Client{Timeout: 20 * time.Second}).Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("moneybird purchase invoice request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		details := strings.TrimSpace(string(respBody))
+		if details == "" {
+			return "", "", apperr.BadRequest("moneybird purchase invoice creation failed")
+		}
+		return "", "", apperr.BadRequest(fmt.Sprintf("moneybird purchase invoice creation failed: %s", details))
+	}
+
+	var created moneybirdInvoiceResponse
+	if err := json.Unmarshal(respBody, &created); err != nil {
+		return "", "", fmt.Errorf("decode moneybird purchase invoice response: %w", err)
+	}
+	if created.ID == "" {
+		return "", "", apperr.BadRequest("moneybird purchase invoice id missing in response")
+	}
+
+	invoiceURL := fmt.Sprintf("https://moneybird.com/app/%s/documents/%s", administrationID, created.ID)
+	return created.ID, invoiceURL, nil
+}
+
+// moneybirdCreateCompanyContact creates the Moneybird contact of a business,
+// such as a partner, keyed on our id as customer id.
+func (s *Service) moneybirdCreateCompanyContact(ctx context.Context, administrationID, accessToken, externalCustomerID, companyName, email string) (string, error) {
+	contactPayload := map[string]any{
+		"company_name": moneybirdContactCompanyNameFallback(companyName, "", email, externalCustomerID),
+		"customer_id":  externalCustomerID,
+	}
+	if email = strings.TrimSpace(email); email != "" {
+		contactPayload["email"] = email
+	}
+
+	body, _ := json.Marshal(map[string]any{"contact": contactPayload})
+	endpoint := fmt.Sprintf("%s/%s/contacts", moneybirdAPIBaseURL, administrationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("build moneybird create contact request: %w", err)
+	}
+	req.Header.Set(httpHeaderAuthorization, authorizationBearerPrefix+accessToken)
+	req.Header.Set(httpHeaderAccept, mimeApplicationJSON)
+	req.Header.Set(httpHeaderContentType, mimeApplicationJSON)
+
+	resp, err := (&http.Client{Timeout: 20 * time.Second}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("moneybird create contact request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		details := strings.TrimSpace(string(respBody))
+		if details == "" {
+			return "", apperr.BadRequest("moneybird contact create failed")
+		}
+		return "", apperr.BadRequest(fmt.Sprintf("moneybird contact create failed: %s", details))
+	}
+
+	var contact moneybirdContact
+	if err := json.Unmarshal(respBody, &contact); err != nil {
+		return "", fmt.Errorf("decode moneybird contact response: %w", err)
+	}
+	if contact.ID == "" {
+		return "", apperr.BadRequest("moneybird contact id missing in response")
+	}
+	return contact.ID, nil
+}
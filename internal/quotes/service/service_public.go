@@ -139,6 +139,7 @@ func (s *Service) AnnotateItem(ctx context.Context, token string, itemID uuid.UU
 	if err := s.repo.CreateAnnotation(ctx, &annotation); err != nil {
 		return nil, err
 	}
+	s.markThreadRead(ctx, quote.ID, authorType, annotation.CreatedAt)
 	if s.eventBus != nil {
 		s.eventBus.Publish(ctx, s.buildQuoteAnnotatedEvent(ctx, quote, item, authorType, authorID, text, token))
 	}
@@ -205,6 +206,7 @@ func (s *Service) AgentAnnotateItem(ctx context.Context, quoteID, itemID, tenant
 	if err := s.repo.CreateAnnotation(ctx, &annotation); err != nil {
 		return nil, err
 	}
+	s.markThreadRead(ctx, quote.ID, repository.ThreadPartyAgent, annotation.CreatedAt)
 	if s.eventBus != nil {
 		s.eventBus.Publish(ctx, s.buildQuoteAnnotatedEvent(ctx, quote, item, "agent", agentID.String(), text, ""))
 	}
@@ -220,7 +222,6 @@ func (s *Service) buildQuoteAnnotatedEvent(ctx context.Context, quote *repositor
 		LeadServiceID:    quote.LeadServiceID,
 		QuoteNumber:      quote.QuoteNumber,
 		PublicToken:      strings.TrimSpace(fallbackPublicToken),
-		ItemID:           item.ID,
 		ItemDescription:  quoteAnnotationItemDescription(item),
 		AuthorType:       authorType,
 		AuthorID:         authorID,
@@ -232,6 +233,9 @@ func (s *Service) buildQuoteAnnotatedEvent(ctx context.Context, quote *repositor
 		OrganizationName: "",
 	}
 
+	if item != nil {
+		evt.ItemID = item.ID
+	}
 	if quote.PublicToken != nil && strings.TrimSpace(*quote.PublicToken) != "" {
 		evt.PublicToken = strings.TrimSpace(*quote.PublicToken)
 	}
@@ -283,10 +287,9 @@ func (s *Service) reloadAcceptedQuote(ctx context.Context, quote *repository.Quo
 	return quote
 }
 
-func (s *Service) publishQuoteAcceptedEvent(ctx context.Context, quote *repository.Quote, signatureName, token string) {
-	if s.eventBus == nil {
-		return
-	}
+// buildQuoteAcceptedEvent assembles the QuoteAccepted event. Accept writes it to
+// the domain event outbox in the same transaction as the acceptance.
+func (s *Service) buildQuoteAcceptedEvent(ctx context.Context, quote *repository.Quote, signatureName, token string) events.QuoteAccepted {
 	evt := events.QuoteAccepted{BaseEvent: events.NewBaseEvent(), QuoteID: quote.ID, OrganizationID: quote.OrganizationID, LeadID: quote.LeadID, LeadServiceID: quote.LeadServiceID, ISDESubsidy: quoteSubsidyEventPayload(quote.SubsidyData), SignatureName: signatureName, TotalCents: quote.TotalCents, QuoteNumber: quote.QuoteNumber, PublicToken: token}
 	if s.contacts != nil {
 		if contactData, lookupErr := s.contacts.GetQuoteContactData(ctx, quote.LeadID, quote.OrganizationID); lookupErr == nil {
@@ -298,7 +301,7 @@ func (s *Service) publishQuoteAcceptedEvent(ctx context.Context, quote *reposito
 			evt.AgentName = contactData.AgentName
 		}
 	}
-	s.eventBus.Publish(ctx, evt)
+	return evt
 }
 
 func (s *Service) Accept(ctx context.Context, token string, req transport.AcceptQuoteRequest, clientIP string) (*transport.PublicQuoteResponse, error) {
@@ -318,7 +321,8 @@ func (s *Service) Accept(ctx context.Context, token string, req transport.Accept
 	if quote.Status == string(transport.QuoteStatusRejected) {
 		return nil, apperr.BadRequest("this quote has been rejected")
 	}
-	if err := s.repo.AcceptQuote(ctx, quote, req.SignatureName, req.SignatureData, clientIP); err != nil {
+	acceptedEvent := s.buildQuoteAcceptedEvent(ctx, quote, req.SignatureName, token)
+	if err := s.repo.AcceptQuote(ctx, quote, req.SignatureName, req.SignatureData, clientIP, acceptedEvent); err != nil {
 		return nil, err
 	}
 	quote, _, err = s.resolveToken(ctx, token)
@@ -330,11 +334,11 @@ func (s *Service) Accept(ctx context.Context, token string, req transport.Accept
 	if err != nil {
 		return nil, err
 	}
-	s.publishQuoteAcceptedEvent(ctx, quote, req.SignatureName, token)
+	s.createDepositPaymentOnAccept(ctx, quote)
 
 	orgName, customerName, logoFileKey := s.lookupContactNames(ctx, quote.LeadID, quote.OrganizationID)
 	drafts := buildQuoteAcceptedDrafts(quote.QuoteNumber, orgName, customerName, req.SignatureName, quote.TotalCents)
-	s.emitTimelineEvent(ctx, TimelineEventParams{LeadID: quote.LeadID, ServiceID: quote.LeadServiceID, OrganizationID: quote.OrganizationID, ActorType: "Lead", ActorName: req.SignatureName, EventType: "quote_accepted", Title: fmt.Sprintf("Quote %s accepted", quote.QuoteNumber), Summary: toPtr(fmt.Sprintf("Signed by %s — "+msgTotalFormat, req.SignatureName, float64(quote.TotalCents)/100)), Metadata: map[string]any{"quoteId": quote.ID, "status": "Accepted", "signatureName": req.SignatureName, "drafts": drafts}})
+	s.emitTimelineEvent(ctx, TimelineEventParams{LeadID: quote.LeadID, ServiceID: quote.LeadServiceID, OrganizationID: quote.OrganizationID, ActorType: "Lead", ActorName: req.SignatureName, EventType: "quote_accepted", Title: fmt.Sprintf("Quote %s accepted", quote.QuoteNumber), Summary: toPtr(fmt.Sprintf("Signed by %s — "+msgTotalFormat, req.SignatureName, float64(quote.TotalCents)/100)), Metadata: map[string]any{"quoteId": quote.ID, "status": "Accepted", "signatureName": req.SignatureName, "drafts": drafts}, DedupKey: "quote_accepted:" + quote.ID.String()})
 	return s.buildPublicResponse(ctx, quote, items, orgName, customerName, logoFileKey, false)
 }
 
@@ -356,7 +360,8 @@ func (s *Service) Reject(ctx context.Context, token string, req transport.Reject
 	if req.Reason == "" {
 		reasonPtr = nil
 	}
-	if err := s.repo.RejectQuote(ctx, quote, reasonPtr); err != nil {
+	rejectedEvent := s.buildQuoteRejectedEvent(ctx, quote, req.Reason)
+	if err := s.repo.RejectQuote(ctx, quote, reasonPtr, rejectedEvent); err != nil {
 		return nil, err
 	}
 	quote, _, err = s.resolveToken(ctx, token)
@@ -367,17 +372,15 @@ func (s *Service) Reject(ctx context.Context, token string, req transport.Reject
 	if err != nil {
 		return nil, err
 	}
-	s.publishQuoteRejectedEvent(ctx, quote, req.Reason)
 	orgName, customerName, logoFileKey := s.lookupContactNames(ctx, quote.LeadID, quote.OrganizationID)
 	drafts := buildQuoteRejectedDrafts(quote.QuoteNumber, orgName, customerName, req.Reason)
 	s.emitTimelineEvent(ctx, TimelineEventParams{LeadID: quote.LeadID, ServiceID: quote.LeadServiceID, OrganizationID: quote.OrganizationID, ActorType: "Lead", ActorName: "Customer", EventType: "quote_rejected", Title: fmt.Sprintf("Quote %s rejected", quote.QuoteNumber), Summary: nilIfEmpty(req.Reason), Metadata: map[string]any{"quoteId": quote.ID, "status": "Rejected", "reason": req.Reason, "drafts": drafts}})
 	return s.buildPublicResponse(ctx, quote, items, orgName, customerName, logoFileKey, false)
 }
 
-func (s *Service) publishQuoteRejectedEvent(ctx context.Context, quote *repository.Quote, reason string) {
-	if s.eventBus == nil {
-		return
-	}
+// buildQuoteRejectedEvent assembles the QuoteRejected event. Reject writes it to
+// the domain event outbox in the same transaction as the rejection.
+func (s *Service) buildQuoteRejectedEvent(ctx context.Context, quote *repository.Quote, reason string) events.QuoteRejected {
 	evt := events.QuoteRejected{BaseEvent: events.NewBaseEvent(), QuoteID: quote.ID, OrganizationID: quote.OrganizationID, LeadID: quote.LeadID, LeadServiceID: quote.LeadServiceID, QuoteNumber: quote.QuoteNumber, Reason: reason}
 	if s.contacts != nil {
 		if contactData, lookupErr := s.contacts.GetQuoteContactData(ctx, quote.LeadID, quote.OrganizationID); lookupErr == nil {
@@ -387,7 +390,7 @@ func (s *Service) publishQuoteRejectedEvent(ctx context.Context, quote *reposito
 			evt.OrganizationName = contactData.OrganizationName
 		}
 	}
-	s.eventBus.Publish(ctx, evt)
+	return evt
 }
 
 func (s *Service) buildPublicResponse(ctx context.Context, q *repository.Quote, items []repository.QuoteItem, organizationName, customerName string, logoFileKey *string, readOnly bool) (*transport.PublicQuoteResponse, error) {
@@ -438,12 +441,19 @@ func (s *Service) buildPublicResponse(ctx context.Context, q *repository.Quote,
 	}
 
 	logoURL := s.presignLogoURL(ctx, logoFileKey)
+	branding := s.publicBranding(ctx, q.OrganizationID)
+
+	var paymentSchedule []transport.PaymentInstallmentResponse
+	if installments, scheduleErr := s.repo.ListPaymentInstallments(ctx, q.ID, q.OrganizationID); scheduleErr == nil {
+		paymentSchedule = BuildPaymentSchedule(installments, calc.TotalCents, q.AcceptedAt, time.Now())
+	}
+	depositPayment := s.loadDepositPayment(ctx, q, paymentSchedule)
 
 	publicToken := ""
 	if q.PublicToken != nil {
 		publicToken = *q.PublicToken
 	}
-	return &transport.PublicQuoteResponse{ID: q.ID, QuoteNumber: q.QuoteNumber, Status: transport.QuoteStatus(q.Status), PricingMode: q.PricingMode, OrganizationName: organizationName, LogoURL: logoURL, CustomerName: customerName, DiscountType: q.DiscountType, DiscountValue: q.DiscountValue, SubtotalCents: calc.SubtotalCents, DiscountAmountCents: calc.DiscountAmountCents, TaxTotalCents: calc.VatTotalCents, TotalCents: calc.TotalCents, VatBreakdown: calc.VatBreakdown, ValidUntil: q.ValidUntil, Notes: q.Notes, Items: respItems, Attachments: attachments, URLs: urls, PublicToken: publicToken, AcceptedAt: q.AcceptedAt, RejectedAt: q.RejectedAt, FinancingDisclaimer: q.FinancingDisclaimer, PagePerItem: q.PagePerItem, IsReadOnly: readOnly}, nil
+	return &transport.PublicQuoteResponse{ID: q.ID, QuoteNumber: q.QuoteNumber, Status: transport.QuoteStatus(q.Status), PricingMode: q.PricingMode, OrganizationName: organizationName, LogoURL: logoURL, Branding: branding, CustomerName: customerName, DiscountType: q.DiscountType, DiscountValue: q.DiscountValue, SubtotalCents: calc.SubtotalCents, DiscountAmountCents: calc.DiscountAmountCents, TaxTotalCents: calc.VatTotalCents, TotalCents: calc.TotalCents, VatBreakdown: calc.VatBreakdown, ValidUntil: q.ValidUntil, Notes: q.Notes, Items: respItems, Attachments: attachments, URLs: urls, PublicToken: publicToken, AcceptedAt: q.AcceptedAt, RejectedAt: q.RejectedAt, FinancingDisclaimer: q.FinancingDisclaimer, PagePerItem: q.PagePerItem, IsReadOnly: readOnly, PaymentSchedule: paymentSchedule, DepositPayment: depositPayment}, nil
 }
 
 func (s *Service) presignLogoURL(ctx context.Context, logoFileKey *string) *string {
@@ -455,6 +465,19 @@ func (s *Service) presignLogoURL(ctx context.Context, logoFileKey *string) *stri
 	return nil
 }
 
+// publicBranding returns the brand colors of the organization, or nil when
+// none are configured or they cannot be read.
+func (s *Service) publicBranding(ctx context.Context, organizationID uuid.UUID) *transport.PublicBrandingResponse {
+	if s.branding == nil {
+		return nil
+	}
+	branding, err := s.branding.GetPublicBranding(ctx, organizationID)
+	if err != nil || (branding.PrimaryColor == nil && branding.AccentColor == nil) {
+		return nil
+	}
+	return &branding
+}
+
 func buildQuoteAcceptedDrafts(quoteNumber, orgName, customerName, signatureName string, totalCents int64) map[string]any {
 	if strings.TrimSpace(customerName) == "" {
 		customerName = draftFallbackCustomerName
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+var (
+	templatePlaceholderRegex = regexp.MustCompile(`\{\{\s*([a-z][a-z0-9_]{0,49})\s*\}\}`)
+	templateQuantityRegex    = regexp.MustCompile(`^\d+([.,]\d+)?$`)
+)
+
+// ListQuoteTemplates returns the quote templates of an organization with their usage statistics.
+func (s *Service) ListQuoteTemplates(ctx context.Context, tenantID uuid.UUID) (*transport.QuoteTemplateListResponse, error) {
+	templates, err := s.repo.ListQuoteTemplates(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transport.QuoteTemplateListResponse{Items: make([]transport.QuoteTemplateResponse, 0, len(templates))}
+	for i := range templates {
+		items, err := s.repo.GetQuoteTemplateItems(ctx, templates[i].ID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Items = append(resp.Items, toQuoteTemplateResponse(&templates[i], items))
+	}
+	return resp, nil
+}
+
+// GetQuoteTemplate returns a quote template with its items.
+func (s *Service) GetQuoteTemplate(ctx context.Context, id, tenantID uuid.UUID) (*transport.QuoteTemplateResponse, error) {
+	template, err := s.repo.GetQuoteTemplate(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetQuoteTemplateItems(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toQuoteTemplateResponse(template, items)
+	return &resp, nil
+}
+
+// CreateQuoteTemplate stores a new reusable bundle of line items.
+func (s *Service) CreateQuoteTemplate(ctx context.Context, tenantID, agentID uuid.UUID, req transport.SaveQuoteTemplateRequest) (*transport.QuoteTemplateResponse, error) {
+	params, err := buildQuoteTemplateParams(tenantID, req)
+	if err != nil {
+		return nil, err
+	}
+	params.CreatedByID = &agentID
+
+	id, err := s.repo.CreateQuoteTemplate(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetQuoteTemplate(ctx, id, tenantID)
+}
+
+// UpdateQuoteTemplate replaces the fields and items of a quote template.
+// Quotes created from the template earlier are not changed.
+func (s *Service) UpdateQuoteTemplate(ctx context.Context, id, tenantID uuid.UUID, req transport.SaveQuoteTemplateRequest) (*transport.QuoteTemplateResponse, error) {
+	params, err := buildQuoteTemplateParams(tenantID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateQuoteTemplate(ctx, id, params); err != nil {
+		return nil, err
+	}
+	return s.GetQuoteTemplate(ctx, id, tenantID)
+}
+
+// DeleteQuoteTemplate removes a quote template.
+func (s *Service) DeleteQuoteTemplate(ctx context.Context, id, tenantID uuid.UUID) error {
+	return s.repo.DeleteQuoteTemplate(ctx, id, tenantID)
+}
+
+// InstantiateQuoteTemplate creates a draft quote for a lead service from a template,
+// filling the quantity placeholders with the given values.
+func (s *Service) InstantiateQuoteTemplate(ctx context.Context, id, tenantID, agentID uuid.UUID, req transport.InstantiateQuoteTemplateRequest) (*transport.QuoteResponse, error) {
+	template, err := s.repo.GetQuoteTemplate(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	templateItems, err := s.repo.GetQuoteTemplateItems(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := resolveQuoteTemplateItems(templateItems, req.Quantities)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.Create(ctx, tenantID, agentID, transport.CreateQuoteRequest{
+		LeadID:        req.LeadID,
+		LeadServiceID: req.LeadServiceID,
+		PricingMode:   template.PricingMode,
+		Notes:         ptrToString(template.Notes),
+		Items:         items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Usage statistics are best effort; the draft quote already exists.
+	_ = s.repo.RecordQuoteTemplateUsage(ctx, template.ID, quote.ID, tenantID, &agentID)
+	return quote, nil
+}
+
+func buildQuoteTemplateParams(tenantID uuid.UUID, req transport.SaveQuoteTemplateRequest) (repository.SaveQuoteTemplateParams, error) {
+	pricingMode := req.PricingMode
+	if pricingMode == "" {
+		pricingMode = "exclusive"
+	}
+	items := make([]repository.QuoteTemplateItem, len(req.Items))
+	for i, it := range req.Items {
+		quantity := normalizeQuantityString(it.Quantity)
+		if err := validateTemplateQuantity(quantity); err != nil {
+			return repository.SaveQuoteTemplateParams{}, apperr.Validation(fmt.Sprintf("items[%d]: %s", i, err.Error()))
+		}
+		selected := true
+		if it.IsOptional {
+			selected = it.IsSelected
+		}
+		items[i] = repository.QuoteTemplateItem{
+			Title:            strings.TrimSpace(it.Title),
+			Description:      strings.TrimSpace(it.Description),
+			Quantity:         quantity,
+			UnitPriceCents:   it.UnitPriceCents,
+			TaxRateBps:       it.TaxRateBps,
+			IsOptional:       it.IsOptional,
+			IsSelected:       selected,
+			CatalogProductID: it.CatalogProductID,
+		}
+	}
+	return repository.SaveQuoteTemplateParams{
+		OrganizationID: tenantID,
+		Name:           strings.TrimSpace(req.Name),
+		Description:    nilIfEmpty(strings.TrimSpace(req.Description)),
+		PricingMode:    pricingMode,
+		Notes:          nilIfEmpty(strings.TrimSpace(req.Notes)),
+		Items:          items,
+	}, nil
+}
+
+// validateTemplateQuantity rejects braces that are not a well-formed placeholder.
+func validateTemplateQuantity(quantity string) error {
+	rest := templatePlaceholderRegex.ReplaceAllString(quantity, "")
+	if strings.Contains(rest, "{") || strings.Contains(rest, "}") {
+		return fmt.Errorf("invalid placeholder in quantity %q; use {{name}} with lowercase letters, digits and underscores", quantity)
+	}
+	return nil
+}
+
+// resolveQuoteTemplateItems turns template lines into quote item requests. Every
+// placeholder must have a numeric value; all missing or invalid ones are reported together.
+func resolveQuoteTemplateItems(items []repository.QuoteTemplateItem, quantities map[string]string) ([]transport.QuoteItemRequest, error) {
+	problems := map[string]string{}
+	for _, key := range quoteTemplatePlaceholders(items) {
+		value, ok := quantities[key]
+		value = strings.TrimSpace(value)
+		switch {
+		case !ok || value == "":
+			problems[key] = "is required"
+		case !templateQuantityRegex.MatchString(value):
+			problems[key] = "must be a number"
+		}
+	}
+	if len(problems) > 0 {
+		return nil, apperr.Validation("missing or invalid template quantities").WithDetails(problems)
+	}
+
+	result := make([]transport.QuoteItemRequest, len(items))
+	for i, it := range items {
+		quantity := templatePlaceholderRegex.ReplaceAllStringFunc(it.Quantity, func(match string) string {
+			key := templatePlaceholderRegex.FindStringSubmatch(match)[1]
+			return strings.TrimSpace(quantities[key])
+		})
+		result[i] = transport.QuoteItemRequest{
+			Title:            it.Title,
+			Description:      it.Description,
+			Quantity:         quantity,
+			UnitPriceCents:   it.UnitPriceCents,
+			TaxRateBps:       it.TaxRateBps,
+			IsOptional:       it.IsOptional,
+			IsSelected:       it.IsSelected,
+			CatalogProductID: it.CatalogProductID,
+		}
+	}
+	return result, nil
+}
+
+// quoteTemplatePlaceholders returns the sorted, distinct placeholder names of the template lines.
+func quoteTemplatePlaceholders(items []repository.QuoteTemplateItem) []string {
+	seen := map[string]struct{}{}
+	keys := make([]string, 0)
+	for _, it := range items {
+		for _, match := range templatePlaceholderRegex.FindAllStringSubmatch(it.Quantity, -1) {
+			if _, ok := seen[match[1]]; ok {
+				continue
+			}
+			seen[match[1]] = struct{}{}
+			keys = append(keys, match[1])
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toQuoteTemplateResponse(t *repository.QuoteTemplate, items []repository.QuoteTemplateItem) transport.QuoteTemplateResponse {
+	resp := transport.QuoteTemplateResponse{
+		ID:           t.ID,
+		Name:         t.Name,
+		Description:  t.Description,
+		PricingMode:  t.PricingMode,
+		Notes:        t.Notes,
+		Placeholders: quoteTemplatePlaceholders(items),
+		Items:        make([]transport.QuoteTemplateItemResponse, len(items)),
+		Stats: transport.QuoteTemplateStatsResponse{
+			UsageCount:    t.Stats.UsageCount,
+			AcceptedCount: t.Stats.AcceptedCount,
+			LastUsedAt:    t.Stats.LastUsedAt,
+		},
+		CreatedByID: t.CreatedByID,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+	if t.Stats.UsageCount > 0 {
+		resp.Stats.AcceptanceRate = float64(t.Stats.AcceptedCount) / float64(t.Stats.UsageCount)
+	}
+	for i, it := range items {
+		resp.Items[i] = transport.QuoteTemplateItemResponse{
+			ID:               it.ID,
+			Title:            it.Title,
+			Description:      it.Description,
+			Quantity:         it.Quantity,
+			UnitPriceCents:   it.UnitPriceCents,
+			TaxRateBps:       it.TaxRateBps,
+			IsOptional:       it.IsOptional,
+			IsSelected:       it.IsSelected,
+			CatalogProductID: it.CatalogProductID,
+		}
+	}
+	return resp
+}
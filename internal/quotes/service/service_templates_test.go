@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/platform/apperr"
+)
+
+func testTemplateItems() []repository.QuoteTemplateItem {
+	return []repository.QuoteTemplateItem{
+		{Description: "Dakkapel", Quantity: "{{breedte}} m", UnitPriceCents: 150000, IsSelected: true},
+		{Description: "Kozijnen", Quantity: "{{ ramen }}", UnitPriceCents: 45000, IsSelected: true},
+		{Description: "Steiger", Quantity: "1", UnitPriceCents: 60000, IsSelected: true},
+		{Description: "Afvoer", Quantity: "{{breedte}} m", UnitPriceCents: 2000, IsOptional: true},
+	}
+}
+
+func TestResolveQuoteTemplateItemsFillsPlaceholders(t *testing.T) {
+	items, err := resolveQuoteTemplateItems(testTemplateItems(), map[string]string{"breedte": "3,5", "ramen": " 2 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := []string{items[0].Quantity, items[1].Quantity, items[2].Quantity, items[3].Quantity}
+	want := []string{"3,5 m", "2", "1", "3,5 m"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("quantities = %v, want %v", got, want)
+	}
+	if !items[3].IsOptional || items[3].IsSelected {
+		t.Fatalf("expected optional line to stay deselected, got %+v", items[3])
+	}
+}
+
+func TestResolveQuoteTemplateItemsReportsMissingAndInvalidValues(t *testing.T) {
+	_, err := resolveQuoteTemplateItems(testTemplateItems(), map[string]string{"breedte": "drie"})
+
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.KindValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	want := map[string]string{"breedte": "must be a number", "ramen": "is required"}
+	if !reflect.DeepEqual(appErr.Details, want) {
+		t.Fatalf("details = %v, want %v", appErr.Details, want)
+	}
+}
+
+func TestQuoteTemplatePlaceholdersAreSortedAndDistinct(t *testing.T) {
+	if got := quoteTemplatePlaceholders(testTemplateItems()); !reflect.DeepEqual(got, []string{"breedte", "ramen"}) {
+		t.Fatalf("placeholders = %v", got)
+	}
+	if err := validateTemplateQuantity("{{Breedte}} m"); err == nil {
+		t.Fatal("expected malformed placeholder to be rejected")
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/quotes/repository"
+	"portal_final_backend/internal/quotes/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	msgTermsEffectiveInPast = "effectiveFrom cannot be in the past"
+	msgQuoteTermsNotFound   = "no terms apply to this quote"
+
+	// termsEffectiveFromGrace absorbs clock skew for versions that should take
+	// effect right away.
+	termsEffectiveFromGrace = 5 * time.Minute
+)
+
+// ListTermsDocuments returns the terms documents of an organization with the
+// version each has in effect now.
+func (s *Service) ListTermsDocuments(ctx context.Context, tenantID uuid.UUID) (*transport.TermsDocumentListResponse, error) {
+	docs, err := s.repo.ListTermsDocuments(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp := &transport.TermsDocumentListResponse{Items: make([]transport.TermsDocumentResponse, 0, len(docs))}
+	for i := range docs {
+		versions, err := s.repo.ListTermsVersions(ctx, docs[i].ID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		item := toTermsDocumentResponse(&docs[i], currentTermsVersion(versions, now))
+		resp.Items = append(resp.Items, item)
+	}
+	return resp, nil
+}
+
+// GetTermsDocument returns a terms document with its version history.
+func (s *Service) GetTermsDocument(ctx context.Context, id, tenantID uuid.UUID) (*transport.TermsDocumentResponse, error) {
+	doc, err := s.repo.GetTermsDocument(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.repo.ListTermsVersions(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toTermsDocumentResponse(doc, currentTermsVersion(versions, time.Now()))
+	resp.Versions = make([]transport.TermsVersionResponse, len(versions))
+	for i := range versions {
+		resp.Versions[i] = toTermsVersionResponse(&versions[i])
+	}
+	return &resp, nil
+}
+
+// CreateTermsDocument stores a new terms document with its first version.
+func (s *Service) CreateTermsDocument(ctx context.Context, tenantID, agentID uuid.UUID, req transport.CreateTermsDocumentRequest) (*transport.TermsDocumentResponse, error) {
+	effectiveFrom, err := resolveTermsEffectiveFrom(req.EffectiveFrom, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.repo.CreateTermsDocument(ctx, repository.CreateTermsDocumentParams{
+		OrganizationID: tenantID,
+		Name:           strings.TrimSpace(req.Name),
+		IsDefault:      req.IsDefault,
+		CreatedBy:      &agentID,
+		FirstVersion: repository.AddTermsVersionParams{
+			Content:       strings.TrimSpace(req.Content),
+			ChangeNote:    nilIfEmpty(strings.TrimSpace(req.ChangeNote)),
+			EffectiveFrom: effectiveFrom,
+			CreatedBy:     &agentID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTermsDocument(ctx, id, tenantID)
+}
+
+// UpdateTermsDocument renames a terms document or makes it the default.
+func (s *Service) UpdateTermsDocument(ctx context.Context, id, tenantID uuid.UUID, req transport.UpdateTermsDocumentRequest) (*transport.TermsDocumentResponse, error) {
+	if err := s.repo.UpdateTermsDocument(ctx, id, tenantID, strings.TrimSpace(req.Name), req.IsDefault); err != nil {
+		return nil, err
+	}
+	return s.GetTermsDocument(ctx, id, tenantID)
+}
+
+// ArchiveTermsDocument retires a terms document. Quotes sent with one of its
+// versions keep that version.
+func (s *Service) ArchiveTermsDocument(ctx context.Context, id, tenantID uuid.UUID) error {
+	return s.repo.ArchiveTermsDocument(ctx, id, tenantID)
+}
+
+// AddTermsVersion publishes a new version of a terms document.
+func (s *Service) AddTermsVersion(ctx context.Context, id, tenantID, agentID uuid.UUID, req transport.AddTermsVersionRequest) (*transport.TermsVersionResponse, error) {
+	effectiveFrom, err := resolveTermsEffectiveFrom(req.EffectiveFrom, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.repo.AddTermsVersion(ctx, id, tenantID, repository.AddTermsVersionParams{
+		Content:       strings.TrimSpace(req.Content),
+		ChangeNote:    nilIfEmpty(strings.TrimSpace(req.ChangeNote)),
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     &agentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := toTermsVersionResponse(version)
+	return &resp, nil
+}
+
+// SetTermsOverrides makes a terms document the one for the given service
+// types and workflows.
+func (s *Service) SetTermsOverrides(ctx context.Context, id, tenantID uuid.UUID, req transport.SetTermsOverridesRequest) (*transport.TermsDocumentResponse, error) {
+	if err := s.repo.ReplaceTermsOverrides(ctx, id, tenantID, normalizeTermsServiceTypes(req.ServiceTypes), uniqueUUIDs(req.WorkflowIDs)); err != nil {
+		return nil, err
+	}
+	return s.GetTermsDocument(ctx, id, tenantID)
+}
+
+// GetQuoteTerms returns the terms version a quote was sent with, or the one
+// that would apply if it were sent now.
+func (s *Service) GetQuoteTerms(ctx context.Context, quoteID, tenantID uuid.UUID) (*transport.QuoteTermsResponse, error) {
+	quote, err := s.repo.GetByID(ctx, quoteID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	terms, pinned, err := s.resolveQuoteTerms(ctx, quote, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if terms == nil {
+		return nil, apperr.NotFound(msgQuoteTermsNotFound)
+	}
+	return &transport.QuoteTermsResponse{
+		DocumentID:   terms.DocumentID,
+		DocumentName: terms.DocumentName,
+		Version:      toTermsVersionResponse(&terms.Version),
+		Pinned:       pinned,
+	}, nil
+}
+
+// ResolveQuoteTermsVersion returns the terms version to print on the PDF of a
+// quote: the one it was sent with, or the one in effect now for a quote that
+// was not sent yet. It returns nil when the organization has no terms.
+func (s *Service) ResolveQuoteTermsVersion(ctx context.Context, quote *repository.Quote) (*repository.QuoteTerms, error) {
+	terms, _, err := s.resolveQuoteTerms(ctx, quote, time.Now())
+	return terms, err
+}
+
+func (s *Service) resolveQuoteTerms(ctx context.Context, quote *repository.Quote, at time.Time) (*repository.QuoteTerms, bool, error) {
+	pinned, err := s.repo.GetQuoteTermsVersion(ctx, quote.ID, quote.OrganizationID)
+	if err != nil || pinned != nil {
+		return pinned, pinned != nil, err
+	}
+	scope := s.resolveQuoteTermsScope(ctx, quote)
+	terms, err := s.repo.ResolveTermsVersion(ctx, quote.OrganizationID, scope.ServiceType, scope.WorkflowID, at)
+	return terms, false, err
+}
+
+// pinQuoteTermsVersion records the terms version in effect when a quote is
+// sent, so its PDF keeps showing those terms after newer versions are published.
+func (s *Service) pinQuoteTermsVersion(ctx context.Context, quote *repository.Quote, tenantID uuid.UUID, now time.Time) error {
+	scope := s.resolveQuoteTermsScope(ctx, quote)
+	terms, err := s.repo.ResolveTermsVersion(ctx, tenantID, scope.ServiceType, scope.WorkflowID, now)
+	if err != nil || terms == nil {
+		return err
+	}
+	return s.repo.PinQuoteTermsVersion(ctx, quote.ID, tenantID, terms.Version.ID)
+}
+
+// resolveQuoteTermsScope falls back to the default terms document when the
+// service type and workflow of the quote cannot be determined.
+func (s *Service) resolveQuoteTermsScope(ctx context.Context, quote *repository.Quote) QuoteTermsScope {
+	if s.quoteTerms == nil {
+		return QuoteTermsScope{}
+	}
+	scope, err := s.quoteTerms.ResolveQuoteTermsScope(ctx, quote.OrganizationID, quote.LeadID, quote.LeadServiceID)
+	if err != nil {
+		return QuoteTermsScope{}
+	}
+	return scope
+}
+
+// currentTermsVersion returns the version in effect at the given moment from
+// a version history, or nil when none took effect yet.
+func currentTermsVersion(versions []repository.TermsVersion, at time.Time) *repository.TermsVersion {
+	var current *repository.TermsVersion
+	for i := range versions {
+		v := &versions[i]
+		if v.EffectiveFrom.After(at) {
+			continue
+		}
+		if current == nil || v.EffectiveFrom.After(current.EffectiveFrom) ||
+			(v.EffectiveFrom.Equal(current.EffectiveFrom) && v.VersionNumber > current.VersionNumber) {
+			current = v
+		}
+	}
+	return current
+}
+
+func resolveTermsEffectiveFrom(requested *time.Time, now time.Time) (time.Time, error) {
+	if requested == nil {
+		return now, nil
+	}
+	if requested.Before(now.Add(-termsEffectiveFromGrace)) {
+		return time.Time{}, apperr.Validation(msgTermsEffectiveInPast)
+	}
+	return *requested, nil
+}
+
+func normalizeTermsServiceTypes(serviceTypes []string) []string {
+	seen := make(map[string]struct{}, len(serviceTypes))
+	normalized := make([]string, 0, len(serviceTypes))
+	for _, serviceType := range serviceTypes {
+		trimmed := strings.TrimSpace(serviceType)
+		key := strings.ToLower(trimmed)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		normalized = append(normalized, trimmed)
+	}
+	return normalized
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+func toTermsDocumentResponse(doc *repository.TermsDocument, current *repository.TermsVersion) transport.TermsDocumentResponse {
+	resp := transport.TermsDocumentResponse{
+		ID:           doc.ID,
+		Name:         doc.Name,
+		IsDefault:    doc.IsDefault,
+		ServiceTypes: doc.ServiceTypes,
+		WorkflowIDs:  doc.WorkflowIDs,
+		CreatedBy:    doc.CreatedBy,
+		CreatedAt:    doc.CreatedAt,
+		UpdatedAt:    doc.UpdatedAt,
+	}
+	if current != nil {
+		version := toTermsVersionResponse(current)
+		resp.CurrentVersion = &version
+	}
+	return resp
+}
+
+func toTermsVersionResponse(v *repository.TermsVersion) transport.TermsVersionResponse {
+	return transport.TermsVersionResponse{
+		ID:            v.ID,
+		DocumentID:    v.DocumentID,
+		VersionNumber: v.VersionNumber,
+		Content:       v.Content,
+		ChangeNote:    v.ChangeNote,
+		EffectiveFrom: v.EffectiveFrom,
+		CreatedBy:     v.CreatedBy,
+		CreatedAt:     v.CreatedAt,
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/quotes/repository"
+)
+
+func TestCurrentTermsVersionPicksLatestEffective(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	versions := []repository.TermsVersion{
+		{VersionNumber: 3, EffectiveFrom: now.Add(24 * time.Hour)},
+		{VersionNumber: 2, EffectiveFrom: now.Add(-time.Hour)},
+		{VersionNumber: 1, EffectiveFrom: now.Add(-48 * time.Hour)},
+	}
+
+	current := currentTermsVersion(versions, now)
+	if current == nil || current.VersionNumber != 2 {
+		t.Fatalf("expected version 2, got %+v", current)
+	}
+	if current := currentTermsVersion(versions, now.Add(-72*time.Hour)); current != nil {
+		t.Fatalf("expected no version before the first took effect, got %+v", current)
+	}
+}
+
+func TestResolveTermsEffectiveFromRejectsPast(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got, err := resolveTermsEffectiveFrom(nil, now); err != nil || !got.Equal(now) {
+		t.Fatalf("expected now for nil, got %v %v", got, err)
+	}
+	past := now.Add(-time.Hour)
+	if _, err := resolveTermsEffectiveFrom(&past, now); err == nil {
+		t.Fatal("expected validation error for a past effective date")
+	}
+}
+
+func TestNormalizeTermsServiceTypesDedupesCaseInsensitive(t *testing.T) {
+	got := normalizeTermsServiceTypes([]string{" Dakkapel ", "dakkapel", "", "Zonnepanelen"})
+	if len(got) != 2 || got[0] != "Dakkapel" || got[1] != "Zonnepanelen" {
+		t.Fatalf("unexpected service types %v", got)
+	}
+}
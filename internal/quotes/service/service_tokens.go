@@ -136,7 +136,7 @@ func (s *Service) publishQuoteSentEvent(ctx context.Context, quote *repository.Q
 	s.eventBus.Publish(ctx, evt)
 }
 
-func (s *Service) Send(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, agentID uuid.UUID) (*transport.QuoteResponse, error) {
+func (s *Service) Send(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, agentID uuid.UUID, agentRoles []string) (*transport.QuoteResponse, error) {
 	quote, err := s.repo.GetByID(ctx, id, tenantID)
 	if err != nil {
 		return nil, err
@@ -144,11 +144,17 @@ func (s *Service) Send(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, ag
 	if err := validateSendableQuoteStatus(quote.Status); err != nil {
 		return nil, err
 	}
+	if err := s.ensureQuoteApproved(ctx, quote, tenantID, agentID, agentRoles); err != nil {
+		return nil, err
+	}
 
 	token, err := s.ensureQuotePublicToken(ctx, quote, tenantID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.pinQuoteTermsVersion(ctx, quote, tenantID, time.Now()); err != nil {
+		return nil, err
+	}
 	if err := s.ensureQuoteStatusSent(ctx, id, tenantID, quote.Status); err != nil {
 		return nil, err
 	}
@@ -28,7 +28,8 @@ UPDATE RAC_quotes SET
   notes = $10,
   financing_disclaimer = $11,
   page_per_item = $12,
-  updated_at = $13
+  updated_at = $13,
+  row_version = row_version + 1
 WHERE id = $1 AND organization_id = $14;
 
 -- name: DeleteQuoteItemsByQuote :exec
@@ -129,6 +130,7 @@ WHERE q.organization_id = sqlc.arg('organization_id')::uuid
     OR l.address_street ILIKE sqlc.narg('search')::text OR l.address_house_number ILIKE sqlc.narg('search')::text
     OR l.address_zip_code ILIKE sqlc.narg('search')::text OR l.address_city ILIKE sqlc.narg('search')::text
     OR u.first_name ILIKE sqlc.narg('search')::text OR u.last_name ILIKE sqlc.narg('search')::text OR u.email ILIKE sqlc.narg('search')::text
+    OR rac_custom_fields_text(q.custom_fields) ILIKE sqlc.narg('search')::text
     OR EXISTS (
       SELECT 1
       FROM RAC_quote_items qi
@@ -141,7 +143,8 @@ WHERE q.organization_id = sqlc.arg('organization_id')::uuid
   AND (sqlc.narg('valid_until_from')::timestamptz IS NULL OR q.valid_until >= sqlc.narg('valid_until_from')::timestamptz)
   AND (sqlc.narg('valid_until_to')::timestamptz IS NULL OR q.valid_until < sqlc.narg('valid_until_to')::timestamptz)
   AND (sqlc.narg('total_from')::bigint IS NULL OR q.total_cents >= sqlc.narg('total_from')::bigint)
-  AND (sqlc.narg('total_to')::bigint IS NULL OR q.total_cents <= sqlc.narg('total_to')::bigint);
+  AND (sqlc.narg('total_to')::bigint IS NULL OR q.total_cents <= sqlc.narg('total_to')::bigint)
+  AND (sqlc.narg('custom_fields')::jsonb IS NULL OR q.custom_fields @> sqlc.narg('custom_fields')::jsonb);
 
 -- name: ListQuotes :many
 SELECT q.id, q.organization_id, q.lead_id, q.lead_service_id,
@@ -168,6 +171,7 @@ WHERE q.organization_id = sqlc.arg('organization_id')::uuid
     OR l.address_street ILIKE sqlc.narg('search')::text OR l.address_house_number ILIKE sqlc.narg('search')::text
     OR l.address_zip_code ILIKE sqlc.narg('search')::text OR l.address_city ILIKE sqlc.narg('search')::text
     OR u.first_name ILIKE sqlc.narg('search')::text OR u.last_name ILIKE sqlc.narg('search')::text OR u.email ILIKE sqlc.narg('search')::text
+    OR rac_custom_fields_text(q.custom_fields) ILIKE sqlc.narg('search')::text
     OR EXISTS (
       SELECT 1
       FROM RAC_quote_items qi
@@ -181,6 +185,7 @@ WHERE q.organization_id = sqlc.arg('organization_id')::uuid
   AND (sqlc.narg('valid_until_to')::timestamptz IS NULL OR q.valid_until < sqlc.narg('valid_until_to')::timestamptz)
   AND (sqlc.narg('total_from')::bigint IS NULL OR q.total_cents >= sqlc.narg('total_from')::bigint)
   AND (sqlc.narg('total_to')::bigint IS NULL OR q.total_cents <= sqlc.narg('total_to')::bigint)
+  AND (sqlc.narg('custom_fields')::jsonb IS NULL OR q.custom_fields @> sqlc.narg('custom_fields')::jsonb)
 ORDER BY
   CASE WHEN sqlc.arg('sort_by')::text = 'quoteNumber' AND sqlc.arg('sort_order')::text = 'asc' THEN q.quote_number END ASC,
   CASE WHEN sqlc.arg('sort_by')::text = 'quoteNumber' AND sqlc.arg('sort_order')::text = 'desc' THEN q.quote_number END DESC,
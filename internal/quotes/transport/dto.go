@@ -118,34 +118,41 @@ type QuoteISDESubsidy struct {
 
 // CreateQuoteRequest is the request body for creating a new quote
 type CreateQuoteRequest struct {
-	LeadID              uuid.UUID                `json:"leadId" validate:"required"`
-	LeadServiceID       *uuid.UUID               `json:"leadServiceId"`
-	PricingMode         string                   `json:"pricingMode" validate:"omitempty,oneof=exclusive inclusive"`
-	DiscountType        string                   `json:"discountType" validate:"omitempty,oneof=percentage fixed"`
-	DiscountValue       int64                    `json:"discountValue" validate:"min=0"`
-	ValidUntil          *Date                    `json:"validUntil"`
-	Notes               string                   `json:"notes"`
-	Items               []QuoteItemRequest       `json:"items" validate:"required,dive"`
-	Attachments         []QuoteAttachmentRequest `json:"attachments" validate:"omitempty,dive"`
-	URLs                []QuoteURLRequest        `json:"urls" validate:"omitempty,dive"`
-	ISDESubsidy         *QuoteISDESubsidy        `json:"isdeSubsidy,omitempty"`
-	FinancingDisclaimer bool                     `json:"financingDisclaimer"`
-	PagePerItem         bool                     `json:"pagePerItem"`
+	LeadID        uuid.UUID  `json:"leadId" validate:"required"`
+	LeadServiceID *uuid.UUID `json:"leadServiceId"`
+	// CoveredLeadServiceIDs are further services of the lead that this quote
+	// prices, so one quote can span a project. Requires LeadServiceID.
+	CoveredLeadServiceIDs []uuid.UUID              `json:"coveredLeadServiceIds" validate:"omitempty,max=20"`
+	PricingMode           string                   `json:"pricingMode" validate:"omitempty,oneof=exclusive inclusive"`
+	DiscountType          string                   `json:"discountType" validate:"omitempty,oneof=percentage fixed"`
+	DiscountValue         int64                    `json:"discountValue" validate:"min=0"`
+	ValidUntil            *Date                    `json:"validUntil"`
+	Notes                 string                   `json:"notes"`
+	Items                 []QuoteItemRequest       `json:"items" validate:"required,dive"`
+	Attachments           []QuoteAttachmentRequest `json:"attachments" validate:"omitempty,dive"`
+	URLs                  []QuoteURLRequest        `json:"urls" validate:"omitempty,dive"`
+	ISDESubsidy           *QuoteISDESubsidy        `json:"isdeSubsidy,omitempty"`
+	FinancingDisclaimer   bool                     `json:"financingDisclaimer"`
+	PagePerItem           bool                     `json:"pagePerItem"`
 }
 
 // UpdateQuoteRequest is the request body for updating a quote
 type UpdateQuoteRequest struct {
-	PricingMode         *string                   `json:"pricingMode" validate:"omitempty,oneof=exclusive inclusive"`
-	DiscountType        *string                   `json:"discountType" validate:"omitempty,oneof=percentage fixed"`
-	DiscountValue       *int64                    `json:"discountValue" validate:"omitempty,min=0"`
-	ValidUntil          *Date                     `json:"validUntil"`
-	Notes               *string                   `json:"notes"`
-	Items               *[]QuoteItemRequest       `json:"items" validate:"omitempty,dive"`
-	Attachments         *[]QuoteAttachmentRequest `json:"attachments" validate:"omitempty,dive"`
-	URLs                *[]QuoteURLRequest        `json:"urls" validate:"omitempty,dive"`
-	ISDESubsidy         *QuoteISDESubsidy         `json:"isdeSubsidy,omitempty"`
-	FinancingDisclaimer *bool                     `json:"financingDisclaimer"`
-	PagePerItem         *bool                     `json:"pagePerItem"`
+	PricingMode           *string                   `json:"pricingMode" validate:"omitempty,oneof=exclusive inclusive"`
+	DiscountType          *string                   `json:"discountType" validate:"omitempty,oneof=percentage fixed"`
+	DiscountValue         *int64                    `json:"discountValue" validate:"omitempty,min=0"`
+	ValidUntil            *Date                     `json:"validUntil"`
+	Notes                 *string                   `json:"notes"`
+	Items                 *[]QuoteItemRequest       `json:"items" validate:"omitempty,dive"`
+	Attachments           *[]QuoteAttachmentRequest `json:"attachments" validate:"omitempty,dive"`
+	URLs                  *[]QuoteURLRequest        `json:"urls" validate:"omitempty,dive"`
+	CoveredLeadServiceIDs *[]uuid.UUID              `json:"coveredLeadServiceIds" validate:"omitempty,max=20"`
+	ISDESubsidy           *QuoteISDESubsidy         `json:"isdeSubsidy,omitempty"`
+	FinancingDisclaimer   *bool                     `json:"financingDisclaimer"`
+	PagePerItem           *bool                     `json:"pagePerItem"`
+	// RowVersion is the version the update is based on; the If-Match header
+	// takes precedence. Without either the update is unconditional.
+	RowVersion *int64 `json:"rowVersion,omitempty" validate:"omitempty,min=1"`
 }
 
 // UpdateQuoteStatusRequest is the request body for updating a quote's status
@@ -164,6 +171,133 @@ type TransferQuoteRequest struct {
 	DestinationOrganizationID uuid.UUID `json:"destinationOrganizationId" validate:"required"`
 }
 
+// RequestQuoteApprovalRequest is the request body for submitting a quote for admin approval.
+type RequestQuoteApprovalRequest struct {
+	Note string `json:"note,omitempty" validate:"max=2000"`
+}
+
+// DecideQuoteApprovalRequest is the request body for approving or rejecting a quote.
+type DecideQuoteApprovalRequest struct {
+	Note string `json:"note,omitempty" validate:"max=2000"`
+}
+
+// UpdateQuoteApprovalPolicyRequest configures when quotes require admin approval before sending.
+type UpdateQuoteApprovalPolicyRequest struct {
+	Enabled         bool     `json:"enabled"`
+	ThresholdCents  *int64   `json:"thresholdCents,omitempty" validate:"omitempty,min=0"`
+	RequireForRoles []string `json:"requireForRoles" validate:"omitempty,dive,required,max=50"`
+}
+
+// CreateCreditNoteRequest is the request body for crediting part of an accepted quote.
+type CreateCreditNoteRequest struct {
+	Reason string                        `json:"reason" validate:"required,max=2000"`
+	Items  []CreateCreditNoteItemRequest `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// CreateCreditNoteItemRequest is one credited line. UnitPriceCents is the positive
+// amount credited per unit; it is stored and exported as a negative price.
+// Omitted description and tax rate default to those of the referenced quote item.
+type CreateCreditNoteItemRequest struct {
+	QuoteItemID    *uuid.UUID `json:"quoteItemId,omitempty"`
+	Description    string     `json:"description" validate:"max=1000"`
+	Quantity       string     `json:"quantity" validate:"max=50"`
+	UnitPriceCents int64      `json:"unitPriceCents" validate:"gt=0"`
+	TaxRateBps     *int       `json:"taxRateBps,omitempty" validate:"omitempty,min=0,max=10000"`
+}
+
+// SaveQuoteTemplateRequest creates or replaces a quote template. An item quantity
+// may contain placeholders such as "{{breedte}} m" that are filled in when a quote
+// is created from the template.
+type SaveQuoteTemplateRequest struct {
+	Name        string                     `json:"name" validate:"required,min=1,max=200"`
+	Description string                     `json:"description" validate:"max=2000"`
+	PricingMode string                     `json:"pricingMode" validate:"omitempty,oneof=exclusive inclusive"`
+	Notes       string                     `json:"notes" validate:"max=5000"`
+	Items       []QuoteTemplateItemRequest `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// QuoteTemplateItemRequest is one line of a quote template.
+type QuoteTemplateItemRequest struct {
+	Title            string     `json:"title" validate:"max=500"`
+	Description      string     `json:"description" validate:"required,max=5000"`
+	Quantity         string     `json:"quantity" validate:"required,max=100"`
+	UnitPriceCents   int64      `json:"unitPriceCents" validate:"min=0"`
+	TaxRateBps       int        `json:"taxRateBps" validate:"min=0,max=10000"`
+	IsOptional       bool       `json:"isOptional"`
+	IsSelected       bool       `json:"isSelected"`
+	CatalogProductID *uuid.UUID `json:"catalogProductId,omitempty"`
+}
+
+// InstantiateQuoteTemplateRequest creates a draft quote from a template for a lead
+// service. Quantities holds a value for every placeholder used by the template.
+type InstantiateQuoteTemplateRequest struct {
+	LeadID        uuid.UUID         `json:"leadId" validate:"required"`
+	LeadServiceID *uuid.UUID        `json:"leadServiceId"`
+	Quantities    map[string]string `json:"quantities" validate:"max=100,dive,max=50"`
+}
+
+// CreateTermsDocumentRequest creates a terms & conditions document with its
+// first version. EffectiveFrom defaults to now.
+type CreateTermsDocumentRequest struct {
+	Name          string     `json:"name" validate:"required,min=1,max=200"`
+	IsDefault     bool       `json:"isDefault"`
+	Content       string     `json:"content" validate:"required,min=1,max=100000"`
+	ChangeNote    string     `json:"changeNote" validate:"max=1000"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+}
+
+// UpdateTermsDocumentRequest renames a terms document or makes it the default.
+type UpdateTermsDocumentRequest struct {
+	Name      string `json:"name" validate:"required,min=1,max=200"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+// AddTermsVersionRequest publishes a new version of a terms document. Quotes
+// sent before EffectiveFrom keep the version they were sent with.
+type AddTermsVersionRequest struct {
+	Content       string     `json:"content" validate:"required,min=1,max=100000"`
+	ChangeNote    string     `json:"changeNote" validate:"max=1000"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+}
+
+// SetTermsOverridesRequest makes a terms document the one for quotes of the
+// listed service types and workflows, replacing its previous overrides.
+type SetTermsOverridesRequest struct {
+	ServiceTypes []string    `json:"serviceTypes" validate:"max=100,dive,required,max=200"`
+	WorkflowIDs  []uuid.UUID `json:"workflowIds" validate:"max=100"`
+}
+
+// UpdateQuoteAutoSendPolicyRequest configures when AI-drafted quotes are sent automatically.
+// Only quotes for the listed service types qualify; disabling the policy cancels every
+// scheduled auto-send.
+type UpdateQuoteAutoSendPolicyRequest struct {
+	Enabled             bool        `json:"enabled"`
+	ReviewWindowMinutes int         `json:"reviewWindowMinutes" validate:"min=0,max=10080"`
+	MinLeadScore        *int        `json:"minLeadScore,omitempty" validate:"omitempty,min=0,max=100"`
+	MaxTotalCents       *int64      `json:"maxTotalCents,omitempty" validate:"omitempty,min=0"`
+	ServiceTypeIDs      []uuid.UUID `json:"serviceTypeIds" validate:"max=100"`
+}
+
+// CancelQuoteAutoSendRequest is the optional body for stopping a scheduled auto-send.
+type CancelQuoteAutoSendRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// SetPaymentScheduleRequest replaces the payment schedule of a quote. Percentages
+// must add up to 100%; an empty list removes the schedule.
+type SetPaymentScheduleRequest struct {
+	Installments []PaymentInstallmentRequest `json:"installments" validate:"max=12,dive"`
+}
+
+// PaymentInstallmentRequest is one installment of a payment schedule.
+type PaymentInstallmentRequest struct {
+	Label         string  `json:"label" validate:"required,max=200"`
+	PercentageBps int     `json:"percentageBps" validate:"gt=0,max=10000"`
+	DueTrigger    string  `json:"dueTrigger" validate:"required,oneof=on_acceptance days_after_acceptance on_date"`
+	DueAfterDays  *int    `json:"dueAfterDays,omitempty" validate:"omitempty,min=0,max=730"`
+	DueDate       *string `json:"dueDate,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
 // QuoteCalculationRequest is the request body for the preview calculation endpoint
 type QuoteCalculationRequest struct {
 	Items         []QuoteItemRequest `json:"items" validate:"required,dive"`
@@ -200,10 +334,12 @@ type ListQuotesRequest struct {
 	ValidUntilTo   string `form:"validUntilTo" validate:"omitempty"`
 	TotalFrom      string `form:"totalFrom" validate:"omitempty"`
 	TotalTo        string `form:"totalTo" validate:"omitempty"`
-	SortBy         string `form:"sortBy" validate:"omitempty,oneof=quoteNumber status total validUntil customerName customerPhone customerAddress createdBy createdAt updatedAt"`
-	SortOrder      string `form:"sortOrder" validate:"omitempty,oneof=asc desc"`
-	Page           int    `form:"page" validate:"omitempty,min=1"`
-	PageSize       int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+	// CustomFields is a JSON object of custom field values the quote must have.
+	CustomFields string `form:"customFields" validate:"omitempty,max=2000"`
+	SortBy       string `form:"sortBy" validate:"omitempty,oneof=quoteNumber status total validUntil customerName customerPhone customerAddress createdBy createdAt updatedAt"`
+	SortOrder    string `form:"sortOrder" validate:"omitempty,oneof=asc desc"`
+	Page         int    `form:"page" validate:"omitempty,min=1"`
+	PageSize     int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
 }
 
 // ListPendingApprovalsRequest defines query parameters for draft-approval queue.
@@ -271,12 +407,29 @@ type PresignedUploadResponse struct {
 	ExpiresAt int64  `json:"expiresAt"`
 }
 
+// UploadedAttachmentResponse describes a quote attachment streamed to storage.
+// Its fields are then used to attach the file to the quote.
+type UploadedAttachmentResponse struct {
+	FileKey        string `json:"fileKey"`
+	FileName       string `json:"fileName"`
+	ContentType    string `json:"contentType"`
+	SizeBytes      int64  `json:"sizeBytes"`
+	ChecksumSHA256 string `json:"checksumSha256"`
+}
+
 // PresignedDownloadResponse is the presigned URL for downloading an attachment.
 type PresignedDownloadResponse struct {
 	DownloadURL string `json:"downloadUrl"`
 	ExpiresAt   int64  `json:"expiresAt"`
 }
 
+// AttachmentPreviewRequest selects the preview of a quote attachment. Page
+// applies to PDF and office documents only.
+type AttachmentPreviewRequest struct {
+	Variant string `form:"variant" validate:"omitempty,oneof=thumbnail preview"`
+	Page    int    `form:"page" validate:"omitempty,min=1,max=500"`
+}
+
 // QuoteResponse is the response for a quote
 type QuoteResponse struct {
 	ID                         uuid.UUID                 `json:"id"`
@@ -287,8 +440,10 @@ type QuoteResponse struct {
 	PreviousVersionQuoteNumber *string                   `json:"previousVersionQuoteNumber,omitempty"`
 	VersionRootQuoteID         *uuid.UUID                `json:"versionRootQuoteId,omitempty"`
 	VersionNumber              int                       `json:"versionNumber"`
+	RowVersion                 int64                     `json:"rowVersion,omitempty"` // Send back in If-Match when updating
 	LeadID                     uuid.UUID                 `json:"leadId"`
 	LeadServiceID              *uuid.UUID                `json:"leadServiceId,omitempty"`
+	CoveredLeadServiceIDs      []uuid.UUID               `json:"coveredLeadServiceIds,omitempty"`
 	CreatedByID                *uuid.UUID                `json:"createdById,omitempty"`
 	CreatedByFirstName         *string                   `json:"createdByFirstName,omitempty"`
 	CreatedByLastName          *string                   `json:"createdByLastName,omitempty"`
@@ -388,6 +543,109 @@ type TransferQuoteResponse struct {
 	SourceLeadDeleted         bool          `json:"sourceLeadDeleted"`
 }
 
+// QuoteApprovalResponse is one entry of a quote's approval trail.
+type QuoteApprovalResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	QuoteID         uuid.UUID  `json:"quoteId"`
+	Status          string     `json:"status"`
+	Reasons         []string   `json:"reasons"`
+	QuoteTotalCents int64      `json:"quoteTotalCents"`
+	RequestedByID   *uuid.UUID `json:"requestedById,omitempty"`
+	RequestNote     *string    `json:"requestNote,omitempty"`
+	DecidedByID     *uuid.UUID `json:"decidedById,omitempty"`
+	DecisionNote    *string    `json:"decisionNote,omitempty"`
+	DecidedAt       *time.Time `json:"decidedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// CreditNoteResponse is a credit note of an accepted quote. Amounts are negative.
+type CreditNoteResponse struct {
+	ID               uuid.UUID                `json:"id"`
+	QuoteID          uuid.UUID                `json:"quoteId"`
+	CreditNoteNumber string                   `json:"creditNoteNumber"`
+	Reason           string                   `json:"reason"`
+	SubtotalCents    int64                    `json:"subtotalCents"`
+	TaxTotalCents    int64                    `json:"taxTotalCents"`
+	TotalCents       int64                    `json:"totalCents"`
+	HasPDF           bool                     `json:"hasPdf"`
+	CreatedByID      *uuid.UUID               `json:"createdById,omitempty"`
+	CreatedAt        time.Time                `json:"createdAt"`
+	Items            []CreditNoteItemResponse `json:"items"`
+}
+
+// CreditNoteItemResponse is a single line of a credit note.
+type CreditNoteItemResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	QuoteItemID    *uuid.UUID `json:"quoteItemId,omitempty"`
+	Description    string     `json:"description"`
+	Quantity       string     `json:"quantity"`
+	UnitPriceCents int64      `json:"unitPriceCents"`
+	TaxRateBps     int        `json:"taxRateBps"`
+	LineTotalCents int64      `json:"lineTotalCents"`
+}
+
+// CreditNoteListResponse lists the credit notes of a quote with the amount still creditable.
+type CreditNoteListResponse struct {
+	Items                []CreditNoteResponse `json:"items"`
+	QuoteTotalCents      int64                `json:"quoteTotalCents"`
+	CreditedTotalCents   int64                `json:"creditedTotalCents"`
+	RemainingCreditCents int64                `json:"remainingCreditCents"`
+}
+
+// CreditNoteExportResponse is the result of pushing a credit note to an accounting provider.
+type CreditNoteExportResponse struct {
+	CreditNoteID uuid.UUID `json:"creditNoteId"`
+	Provider     string    `json:"provider"`
+	ExternalID   string    `json:"externalId"`
+	ExternalURL  string    `json:"externalUrl,omitempty"`
+	State        string    `json:"state"`
+	ExportedAt   time.Time `json:"exportedAt"`
+}
+
+// PaymentScheduleResponse is the payment schedule of a quote.
+type PaymentScheduleResponse struct {
+	QuoteID      uuid.UUID                    `json:"quoteId"`
+	TotalCents   int64                        `json:"totalCents"`
+	Installments []PaymentInstallmentResponse `json:"installments"`
+}
+
+// PaymentInstallmentResponse is one installment with its derived amount and due date.
+// DueAt is empty while the quote is not accepted for acceptance-relative installments.
+type PaymentInstallmentResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	Sequence      int        `json:"sequence"`
+	Label         string     `json:"label"`
+	PercentageBps int        `json:"percentageBps"`
+	AmountCents   int64      `json:"amountCents"`
+	DueTrigger    string     `json:"dueTrigger"`
+	DueAfterDays  *int       `json:"dueAfterDays,omitempty"`
+	DueDate       *string    `json:"dueDate,omitempty"`
+	DueAt         *time.Time `json:"dueAt,omitempty"`
+	IsDue         bool       `json:"isDue"`
+}
+
+// QuotePaymentResponse is an online payment of a quote. CheckoutURL is only set while
+// the payment can still be completed by the customer.
+type QuotePaymentResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	InstallmentID *uuid.UUID `json:"installmentId,omitempty"`
+	Provider      string     `json:"provider"`
+	Status        string     `json:"status"`
+	AmountCents   int64      `json:"amountCents"`
+	Currency      string     `json:"currency"`
+	CheckoutURL   *string    `json:"checkoutUrl,omitempty"`
+	PaidAt        *time.Time `json:"paidAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// QuoteApprovalPolicyResponse is the organization's quote approval configuration.
+type QuoteApprovalPolicyResponse struct {
+	Enabled         bool      `json:"enabled"`
+	ThresholdCents  *int64    `json:"thresholdCents,omitempty"`
+	RequireForRoles []string  `json:"requireForRoles"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
 // PendingApprovalItem represents one draft quote ready for agent review.
 type PendingApprovalItem struct {
 	QuoteID         uuid.UUID `json:"quoteId"`
@@ -544,31 +802,41 @@ type PublicQuoteItemResponse struct {
 
 // PublicQuoteResponse is the public-facing response for a quote proposal.
 type PublicQuoteResponse struct {
-	ID                  uuid.UUID                 `json:"id"`
-	QuoteNumber         string                    `json:"quoteNumber"`
-	Status              QuoteStatus               `json:"status"`
-	PricingMode         string                    `json:"pricingMode"`
-	OrganizationName    string                    `json:"organizationName"`
-	LogoURL             *string                   `json:"logoUrl,omitempty"`
-	CustomerName        string                    `json:"customerName"`
-	DiscountType        string                    `json:"discountType"`
-	DiscountValue       int64                     `json:"discountValue"`
-	SubtotalCents       int64                     `json:"subtotalCents"`
-	DiscountAmountCents int64                     `json:"discountAmountCents"`
-	TaxTotalCents       int64                     `json:"taxTotalCents"`
-	TotalCents          int64                     `json:"totalCents"`
-	VatBreakdown        []VatBreakdown            `json:"vatBreakdown"`
-	ValidUntil          *time.Time                `json:"validUntil,omitempty"`
-	Notes               *string                   `json:"notes,omitempty"`
-	Items               []PublicQuoteItemResponse `json:"items"`
-	Attachments         []QuoteAttachmentResponse `json:"attachments"`
-	URLs                []QuoteURLResponse        `json:"urls"`
-	PublicToken         string                    `json:"publicToken"`
-	AcceptedAt          *time.Time                `json:"acceptedAt,omitempty"`
-	RejectedAt          *time.Time                `json:"rejectedAt,omitempty"`
-	FinancingDisclaimer bool                      `json:"financingDisclaimer"`
-	PagePerItem         bool                      `json:"pagePerItem"`
-	IsReadOnly          bool                      `json:"isReadOnly,omitempty"`
+	ID                  uuid.UUID                    `json:"id"`
+	QuoteNumber         string                       `json:"quoteNumber"`
+	Status              QuoteStatus                  `json:"status"`
+	PricingMode         string                       `json:"pricingMode"`
+	OrganizationName    string                       `json:"organizationName"`
+	LogoURL             *string                      `json:"logoUrl,omitempty"`
+	Branding            *PublicBrandingResponse      `json:"branding,omitempty"`
+	CustomerName        string                       `json:"customerName"`
+	DiscountType        string                       `json:"discountType"`
+	DiscountValue       int64                        `json:"discountValue"`
+	SubtotalCents       int64                        `json:"subtotalCents"`
+	DiscountAmountCents int64                        `json:"discountAmountCents"`
+	TaxTotalCents       int64                        `json:"taxTotalCents"`
+	TotalCents          int64                        `json:"totalCents"`
+	VatBreakdown        []VatBreakdown               `json:"vatBreakdown"`
+	ValidUntil          *time.Time                   `json:"validUntil,omitempty"`
+	Notes               *string                      `json:"notes,omitempty"`
+	Items               []PublicQuoteItemResponse    `json:"items"`
+	Attachments         []QuoteAttachmentResponse    `json:"attachments"`
+	URLs                []QuoteURLResponse           `json:"urls"`
+	PublicToken         string                       `json:"publicToken"`
+	AcceptedAt          *time.Time                   `json:"acceptedAt,omitempty"`
+	RejectedAt          *time.Time                   `json:"rejectedAt,omitempty"`
+	FinancingDisclaimer bool                         `json:"financingDisclaimer"`
+	PagePerItem         bool                         `json:"pagePerItem"`
+	IsReadOnly          bool                         `json:"isReadOnly,omitempty"`
+	PaymentSchedule     []PaymentInstallmentResponse `json:"paymentSchedule,omitempty"`
+	DepositPayment      *QuotePaymentResponse        `json:"depositPayment,omitempty"`
+}
+
+// PublicBrandingResponse holds the brand colors of the organization as
+// #RRGGBB values; nil colors use the portal defaults.
+type PublicBrandingResponse struct {
+	PrimaryColor *string `json:"primaryColor,omitempty"`
+	AccentColor  *string `json:"accentColor,omitempty"`
 }
 
 // ToggleItemRequest is the request body for toggling an optional item.
@@ -590,6 +858,41 @@ type AnnotateItemRequest struct {
 	Text string `json:"text" validate:"required,min=1,max=2000"`
 }
 
+// QuoteCommentRequest is the request body for a comment on the quote as a whole.
+type QuoteCommentRequest struct {
+	Text string `json:"text" validate:"required,min=1,max=2000"`
+}
+
+// QuoteThreadMessage is a comment in the thread of a quote. ItemID is set
+// for comments on a line item.
+type QuoteThreadMessage struct {
+	ID         uuid.UUID  `json:"id"`
+	ItemID     *uuid.UUID `json:"itemId,omitempty"`
+	AuthorType string     `json:"authorType"`
+	AuthorID   *uuid.UUID `json:"authorId,omitempty"`
+	Text       string     `json:"text"`
+	IsUnread   bool       `json:"isUnread"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// QuoteItemThread holds the comments on a single line item.
+type QuoteItemThread struct {
+	ItemID      uuid.UUID            `json:"itemId"`
+	Description string               `json:"description"`
+	UnreadCount int                  `json:"unreadCount"`
+	Messages    []QuoteThreadMessage `json:"messages"`
+}
+
+// QuoteThreadResponse is the comment thread of a quote as seen by one party.
+// Unread counts cover the other party's comments since LastReadAt.
+type QuoteThreadResponse struct {
+	QuoteID     uuid.UUID            `json:"quoteId"`
+	Messages    []QuoteThreadMessage `json:"messages"`
+	Items       []QuoteItemThread    `json:"items"`
+	UnreadCount int                  `json:"unreadCount"`
+	LastReadAt  *time.Time           `json:"lastReadAt,omitempty"`
+}
+
 type SuggestAnnotationReplyDraftResponse struct {
 	Text string `json:"text"`
 }
@@ -649,6 +952,12 @@ type GenerateQuoteJobResponse struct {
 	StartedAt          time.Time  `json:"startedAt"`
 	UpdatedAt          time.Time  `json:"updatedAt"`
 	FinishedAt         *time.Time `json:"finishedAt,omitempty"`
+	// Attempt counts the runs of the job, up to MaxAttempts; RecoveryCount how
+	// often it was queued again after its worker was interrupted.
+	Attempt         int        `json:"attempt"`
+	MaxAttempts     int        `json:"maxAttempts"`
+	RecoveryCount   int        `json:"recoveryCount"`
+	LastHeartbeatAt *time.Time `json:"lastHeartbeatAt,omitempty"`
 }
 
 // GenerateQuoteJobsListResponse returns a paginated list of async generation jobs.
@@ -712,6 +1021,11 @@ type BulkQuoteExportResponse struct {
 	Items []BulkQuoteExportItem `json:"items"`
 }
 
+// ConnectMollieRequest stores the Mollie API key of the organization.
+type ConnectMollieRequest struct {
+	APIKey string `json:"apiKey" validate:"required,min=10,max=200"`
+}
+
 type MoneybirdAuthorizeURLResponse struct {
 	Provider     string `json:"provider"`
 	AuthorizeURL string `json:"authorizeUrl"`
@@ -722,3 +1036,116 @@ type MoneybirdCallbackResponse struct {
 	IsConnected      bool   `json:"isConnected"`
 	AdministrationID string `json:"administrationId,omitempty"`
 }
+
+// TermsDocumentResponse is a terms & conditions document with its overrides,
+// the version in effect now and, for a single document, its version history.
+type TermsDocumentResponse struct {
+	ID             uuid.UUID              `json:"id"`
+	Name           string                 `json:"name"`
+	IsDefault      bool                   `json:"isDefault"`
+	ServiceTypes   []string               `json:"serviceTypes"`
+	WorkflowIDs    []uuid.UUID            `json:"workflowIds"`
+	CurrentVersion *TermsVersionResponse  `json:"currentVersion,omitempty"`
+	Versions       []TermsVersionResponse `json:"versions,omitempty"`
+	CreatedBy      *uuid.UUID             `json:"createdBy,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
+
+// TermsDocumentListResponse lists the terms documents of an organization.
+type TermsDocumentListResponse struct {
+	Items []TermsDocumentResponse `json:"items"`
+}
+
+// TermsVersionResponse is one version of a terms document.
+type TermsVersionResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	DocumentID    uuid.UUID  `json:"documentId"`
+	VersionNumber int        `json:"versionNumber"`
+	Content       string     `json:"content"`
+	ChangeNote    *string    `json:"changeNote,omitempty"`
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	CreatedBy     *uuid.UUID `json:"createdBy,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// QuoteTermsResponse is the terms version of a quote. Pinned is true once the
+// quote was sent with it; before that it is the version that would apply now.
+type QuoteTermsResponse struct {
+	DocumentID   uuid.UUID            `json:"documentId"`
+	DocumentName string               `json:"documentName"`
+	Version      TermsVersionResponse `json:"version"`
+	Pinned       bool                 `json:"pinned"`
+}
+
+// QuoteTemplateResponse is a quote template with its items and usage statistics.
+type QuoteTemplateResponse struct {
+	ID           uuid.UUID                   `json:"id"`
+	Name         string                      `json:"name"`
+	Description  *string                     `json:"description,omitempty"`
+	PricingMode  string                      `json:"pricingMode"`
+	Notes        *string                     `json:"notes,omitempty"`
+	Placeholders []string                    `json:"placeholders"`
+	Items        []QuoteTemplateItemResponse `json:"items,omitempty"`
+	Stats        QuoteTemplateStatsResponse  `json:"stats"`
+	CreatedByID  *uuid.UUID                  `json:"createdById,omitempty"`
+	CreatedAt    time.Time                   `json:"createdAt"`
+	UpdatedAt    time.Time                   `json:"updatedAt"`
+}
+
+// QuoteTemplateItemResponse is one line of a quote template.
+type QuoteTemplateItemResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Quantity         string     `json:"quantity"`
+	UnitPriceCents   int64      `json:"unitPriceCents"`
+	TaxRateBps       int        `json:"taxRateBps"`
+	IsOptional       bool       `json:"isOptional"`
+	IsSelected       bool       `json:"isSelected"`
+	CatalogProductID *uuid.UUID `json:"catalogProductId,omitempty"`
+}
+
+// QuoteTemplateStatsResponse summarizes how often a template was used and how
+// many of the resulting quotes were accepted.
+type QuoteTemplateStatsResponse struct {
+	UsageCount     int        `json:"usageCount"`
+	AcceptedCount  int        `json:"acceptedCount"`
+	AcceptanceRate float64    `json:"acceptanceRate"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// QuoteTemplateListResponse lists the quote templates of an organization.
+type QuoteTemplateListResponse struct {
+	Items []QuoteTemplateResponse `json:"items"`
+}
+
+// QuoteAutoSendPolicyResponse is the organization's quote auto-send configuration.
+type QuoteAutoSendPolicyResponse struct {
+	Enabled             bool        `json:"enabled"`
+	ReviewWindowMinutes int         `json:"reviewWindowMinutes"`
+	MinLeadScore        *int        `json:"minLeadScore,omitempty"`
+	MaxTotalCents       *int64      `json:"maxTotalCents,omitempty"`
+	ServiceTypeIDs      []uuid.UUID `json:"serviceTypeIds"`
+	UpdatedByID         *uuid.UUID  `json:"updatedById,omitempty"`
+	UpdatedAt           time.Time   `json:"updatedAt"`
+}
+
+// QuoteAutoSendResponse is one auto-send decision for a quote.
+type QuoteAutoSendResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	Status          string     `json:"status"`
+	Reason          *string    `json:"reason,omitempty"`
+	ScheduledFor    *time.Time `json:"scheduledFor,omitempty"`
+	QuoteTotalCents int64      `json:"quoteTotalCents"`
+	LeadScore       *int       `json:"leadScore,omitempty"`
+	CancelledByID   *uuid.UUID `json:"cancelledById,omitempty"`
+	DecidedAt       *time.Time `json:"decidedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// QuoteAutoSendStatusResponse holds the pending auto-send of a quote, if any, and its audit trail.
+type QuoteAutoSendStatusResponse struct {
+	Scheduled *QuoteAutoSendResponse  `json:"scheduled,omitempty"`
+	History   []QuoteAutoSendResponse `json:"history"`
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/residency/service"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc *service.Service
+}
+
+func New(svc *service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterSuperAdminRoutes registers the data schema placement of organizations.
+func (h *Handler) RegisterSuperAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/organizations/:organizationID/data-schema", h.Get)
+	rg.POST("/organizations/:organizationID/data-schema", h.Provision)
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	organizationID, ok := httpkit.ParseUUIDParam(c, "organizationID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.Get(c.Request.Context(), organizationID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// Provision moves a new organization to a dedicated data schema.
+func (h *Handler) Provision(c *gin.Context) {
+	organizationID, ok := httpkit.ParseUUIDParam(c, "organizationID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.Provision(c.Request.Context(), organizationID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
@@ -0,0 +1,44 @@
+// Package residency lets an organization keep its leads, quotes, appointments
+// and tasks in a dedicated Postgres schema, chosen when the organization is set
+// up, and keeps those schemas in line with the migrations of the shared one.
+package residency
+
+import (
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/internal/residency/handler"
+	"portal_final_backend/internal/residency/repository"
+	"portal_final_backend/internal/residency/service"
+	"portal_final_backend/platform/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+}
+
+func NewModule(pool *pgxpool.Pool, cfg service.Config, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), cfg, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc),
+	}
+}
+
+// Service returns the residency service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "residency"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	if ctx.SuperAdmin != nil {
+		m.handler.RegisterSuperAdminRoutes(ctx.SuperAdmin)
+	}
+}
+
+var _ apphttp.Module = (*Module)(nil)
@@ -0,0 +1,655 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TenantTables are the tables a dedicated schema is built around. Every table
+// that references one of them through a foreign key, directly or through
+// another such table, moves along, so no row in the shared schema points at data
+// of a dedicated one. Everything else, such as users, organizations, partners
+// and settings, stays in the shared public schema.
+var TenantTables = []string{
+	"rac_leads",
+	"rac_lead_services",
+	"rac_lead_notes",
+	"rac_lead_activity",
+	"rac_lead_service_events",
+	"rac_lead_service_stage_transitions",
+	"rac_lead_service_attachments",
+	"rac_lead_attachment_renditions",
+	"rac_lead_attachment_image_processing",
+	"rac_lead_ai_analysis",
+	"lead_timeline_events",
+	"rac_quotes",
+	"rac_quote_items",
+	"rac_quote_activity",
+	"rac_quote_attachments",
+	"rac_appointments",
+	"rac_appointment_visit_reports",
+	"rac_tasks",
+	"rac_task_reminders",
+	// The analytics aggregates are rebuilt from the tables above, once per
+	// schema.
+	"rac_analytics_funnel_daily",
+	"rac_analytics_stage_daily",
+	"rac_analytics_refresh_state",
+}
+
+// PlacementChannel is notified with the organization ID whenever an
+// organization moves to a dedicated schema.
+const PlacementChannel = "organization_data_schemas"
+
+// Repository stores the dedicated schemas of organizations and keeps their
+// tables in line with the public schema. All statements run against the
+// shared schema regardless of the routing of the caller's context.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Placement is the dedicated schema of an organization.
+type Placement struct {
+	OrganizationID uuid.UUID
+	SchemaName     string
+	SyncedVersion  int64
+	CreatedAt      time.Time
+	SyncedAt       *time.Time
+}
+
+// tableObject is a constraint or trigger definition of a tenant table.
+type tableObject struct {
+	table      string
+	name       string
+	definition string
+}
+
+func shared(ctx context.Context) context.Context {
+	return db.WithSchema(ctx, "")
+}
+
+// GetPlacement returns the dedicated schema of an organization, or nil when its
+// data lives in the shared schema.
+func (r *Repository) GetPlacement(ctx context.Context, organizationID uuid.UUID) (*Placement, error) {
+	var p Placement
+	err := r.pool.QueryRow(shared(ctx), `
+		SELECT organization_id, schema_name, synced_version, created_at, synced_at
+		FROM public.RAC_organization_data_schemas
+		WHERE organization_id = $1`, organizationID,
+	).Scan(&p.OrganizationID, &p.SchemaName, &p.SyncedVersion, &p.CreatedAt, &p.SyncedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get data schema: %w", err)
+	}
+	return &p, nil
+}
+
+// ListOutdatedPlacements returns the dedicated schemas synced to an older
+// migration version than the given one.
+func (r *Repository) ListOutdatedPlacements(ctx context.Context, version int64) ([]Placement, error) {
+	rows, err := r.pool.Query(shared(ctx), `
+		SELECT organization_id, schema_name, synced_version, created_at, synced_at
+		FROM public.RAC_organization_data_schemas
+		WHERE synced_version < $1
+		ORDER BY created_at`, version,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list data schemas: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Placement, 0)
+	for rows.Next() {
+		var p Placement
+		if err := rows.Scan(&p.OrganizationID, &p.SchemaName, &p.SyncedVersion, &p.CreatedAt, &p.SyncedAt); err != nil {
+			return nil, fmt.Errorf("scan data schema: %w", err)
+		}
+		items = append(items, p)
+	}
+	return items, rows.Err()
+}
+
+// ListSchemas returns the names of all dedicated schemas.
+func (r *Repository) ListSchemas(ctx context.Context) ([]string, error) {
+	rows, err := r.pool.Query(shared(ctx), `SELECT schema_name FROM public.RAC_organization_data_schemas ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list data schemas: %w", err)
+	}
+	schemas, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("list data schemas: %w", err)
+	}
+	return schemas, nil
+}
+
+// FindTokenOrganization returns the organization that holds the row of table
+// with the token in one of the columns, looking in the dedicated schemas before
+// the shared one, and false when no schema has it. table is a tenant table with
+// an organization_id column.
+func (r *Repository) FindTokenOrganization(ctx context.Context, table string, columns []string, token string) (uuid.UUID, bool, error) {
+	ctx = shared(ctx)
+	schemas, err := r.ListSchemas(ctx)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	schemas = append(schemas, "public")
+
+	matches := make([]string, 0, len(columns))
+	for _, column := range columns {
+		matches = append(matches, pgx.Identifier{column}.Sanitize()+" = $1")
+	}
+	selects := make([]string, 0, len(schemas))
+	for _, schema := range schemas {
+		selects = append(selects, fmt.Sprintf("SELECT organization_id FROM %s WHERE %s", qualified(schema, table), strings.Join(matches, " OR ")))
+	}
+	var organizationID uuid.UUID
+	err = r.pool.QueryRow(ctx, strings.Join(selects, " UNION ALL ")+" LIMIT 1", token).Scan(&organizationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, false, nil
+	}
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("find public token: %w", err)
+	}
+	return organizationID, true, nil
+}
+
+// MigrationVersion returns the latest applied goose migration.
+func (r *Repository) MigrationVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := r.pool.QueryRow(shared(ctx), `
+		SELECT COALESCE(MAX(version_id), 0)::bigint
+		FROM public.goose_db_version
+		WHERE is_applied`,
+	).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("get migration version: %w", err)
+	}
+	return version, nil
+}
+
+// HasTenantData reports whether an organization already has leads or quotes in
+// the shared schema.
+func (r *Repository) HasTenantData(ctx context.Context, organizationID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(shared(ctx), `
+		SELECT EXISTS (SELECT 1 FROM public.RAC_leads WHERE organization_id = $1)
+			OR EXISTS (SELECT 1 FROM public.RAC_quotes WHERE organization_id = $1)`, organizationID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check organization data: %w", err)
+	}
+	return exists, nil
+}
+
+// Provision creates the dedicated schema of an organization with copies of the
+// tenant tables, including their indexes, checks, foreign keys and triggers,
+// moves the rows the organization already has in them and records the
+// placement. Other instances learn about it through PlacementChannel.
+func (r *Repository) Provision(ctx context.Context, organizationID uuid.UUID, schema string, version int64) (Placement, error) {
+	ctx = shared(ctx)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return Placement{}, fmt.Errorf("begin provision data schema: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tables, err := publicTenantTables(ctx, tx)
+	if err != nil {
+		return Placement{}, err
+	}
+	if _, err := tx.Exec(ctx, "CREATE SCHEMA "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		return Placement{}, fmt.Errorf("create data schema: %w", err)
+	}
+	if err := copyTables(ctx, tx, schema, tables); err != nil {
+		return Placement{}, err
+	}
+	if err := moveRows(ctx, tx, schema, organizationID, tables); err != nil {
+		return Placement{}, err
+	}
+
+	var p Placement
+	err = tx.QueryRow(ctx, `
+		INSERT INTO public.RAC_organization_data_schemas (organization_id, schema_name, synced_version, synced_at)
+		VALUES ($1, $2, $3, now())
+		RETURNING organization_id, schema_name, synced_version, created_at, synced_at`, organizationID, schema, version,
+	).Scan(&p.OrganizationID, &p.SchemaName, &p.SyncedVersion, &p.CreatedAt, &p.SyncedAt)
+	if err != nil {
+		return Placement{}, fmt.Errorf("store data schema: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "SELECT pg_notify($1, $2)", PlacementChannel, organizationID.String()); err != nil {
+		return Placement{}, fmt.Errorf("notify data schema: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Placement{}, fmt.Errorf("commit provision data schema: %w", err)
+	}
+	return p, nil
+}
+
+// Sync applies the migrations of the public schema to a dedicated schema: it
+// copies tenant tables the schema does not have yet, together with the rows the
+// organization already has in them, and adds missing columns and triggers.
+// Dropped columns, new indexes and new constraints on existing tables are not
+// carried over.
+func (r *Repository) Sync(ctx context.Context, p Placement, version int64) error {
+	ctx = shared(ctx)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin sync data schema: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tables, err := publicTenantTables(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	var missing, existing []string
+	for _, table := range tables {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, qualified(p.SchemaName, table)).Scan(&exists); err != nil {
+			return fmt.Errorf("check table %s: %w", table, err)
+		}
+		if exists {
+			existing = append(existing, table)
+		} else {
+			missing = append(missing, table)
+		}
+	}
+
+	// Read the trigger definitions before the search_path changes.
+	triggers, err := missingTriggers(ctx, tx, p.SchemaName, existing)
+	if err != nil {
+		return err
+	}
+
+	var alters []string
+	for _, table := range existing {
+		statements, err := missingColumns(ctx, tx, p.SchemaName, table)
+		if err != nil {
+			return err
+		}
+		alters = append(alters, statements...)
+	}
+	for _, stmt := range alters {
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("add column: %w", err)
+		}
+	}
+	if err := copyTables(ctx, tx, p.SchemaName, missing); err != nil {
+		return err
+	}
+	if err := moveRows(ctx, tx, p.SchemaName, p.OrganizationID, missing); err != nil {
+		return err
+	}
+	if len(triggers) > 0 {
+		if err := useSchema(ctx, tx, p.SchemaName); err != nil {
+			return err
+		}
+		if err := createTriggers(ctx, tx, triggers); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE public.RAC_organization_data_schemas
+		SET synced_version = $2, synced_at = now()
+		WHERE organization_id = $1`, p.OrganizationID, version,
+	); err != nil {
+		return fmt.Errorf("mark data schema synced: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit sync data schema: %w", err)
+	}
+	return nil
+}
+
+// publicTenantTables returns the tenant tables of the public schema: the
+// TenantTables and every public table that references one of them, directly or
+// through another tenant table.
+func publicTenantTables(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		WITH RECURSIVE tenant(oid) AS (
+			SELECT c.oid
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = 'public' AND c.relkind = 'r' AND c.relname = ANY($1)
+			UNION
+			SELECT con.conrelid
+			FROM pg_constraint con
+			JOIN tenant t ON t.oid = con.confrelid
+			JOIN pg_class c ON c.oid = con.conrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE con.contype = 'f' AND n.nspname = 'public' AND c.relkind = 'r'
+		)
+		SELECT c.relname
+		FROM tenant t
+		JOIN pg_class c ON c.oid = t.oid
+		ORDER BY c.relname`, TenantTables,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list tenant tables: %w", err)
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+const foreignKeysQuery = `
+	SELECT t.relname, c.conname, pg_get_constraintdef(c.oid)
+	FROM pg_constraint c
+	JOIN pg_class t ON t.oid = c.conrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE c.contype = 'f' AND n.nspname = 'public' AND t.relname = ANY($1)
+	ORDER BY t.relname, c.conname`
+
+const triggersQuery = `
+	SELECT t.relname, tg.tgname, pg_get_triggerdef(tg.oid)
+	FROM pg_trigger tg
+	JOIN pg_class t ON t.oid = tg.tgrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE NOT tg.tgisinternal AND n.nspname = 'public' AND t.relname = ANY($1)
+	ORDER BY t.relname, tg.tgname`
+
+// copyTables creates the tables in the schema from their public definition,
+// with their foreign keys and triggers. The definitions are read while public
+// is on the search_path, so the tables they name are unqualified and resolve
+// to the copies in the schema where those exist.
+func copyTables(ctx context.Context, tx pgx.Tx, schema string, tables []string) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	fks, err := listTableObjects(ctx, tx, "foreign keys", foreignKeysQuery, tables)
+	if err != nil {
+		return err
+	}
+	triggers, err := listTableObjects(ctx, tx, "triggers", triggersQuery, tables)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		stmt := fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING ALL)", qualified(schema, table), qualified("public", table))
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("copy table %s: %w", table, err)
+		}
+	}
+
+	if err := useSchema(ctx, tx, schema); err != nil {
+		return err
+	}
+	for _, fk := range fks {
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s", qualified(schema, fk.table), pgx.Identifier{fk.name}.Sanitize(), fk.definition)
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("copy foreign key %s: %w", fk.name, err)
+		}
+	}
+	return createTriggers(ctx, tx, triggers)
+}
+
+// missingTriggers returns the public triggers of the tables that their copies
+// in the schema lack.
+func missingTriggers(ctx context.Context, tx pgx.Tx, schema string, tables []string) ([]tableObject, error) {
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	triggers, err := listTableObjects(ctx, tx, "triggers", triggersQuery, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([]tableObject, 0, len(triggers))
+	for _, trigger := range triggers {
+		var exists bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_trigger WHERE tgrelid = $1::regclass AND tgname = $2)`,
+			qualified(schema, trigger.table), trigger.name).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("check trigger %s: %w", trigger.name, err)
+		}
+		if !exists {
+			missing = append(missing, trigger)
+		}
+	}
+	return missing, nil
+}
+
+func createTriggers(ctx context.Context, tx pgx.Tx, triggers []tableObject) error {
+	for _, trigger := range triggers {
+		if _, err := tx.Exec(ctx, trigger.definition); err != nil {
+			return fmt.Errorf("copy trigger %s: %w", trigger.name, err)
+		}
+	}
+	return nil
+}
+
+func listTableObjects(ctx context.Context, tx pgx.Tx, what, query string, tables []string) ([]tableObject, error) {
+	rows, err := tx.Query(ctx, query, tables)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", what, err)
+	}
+	objects, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (tableObject, error) {
+		var o tableObject
+		err := row.Scan(&o.table, &o.name, &o.definition)
+		return o, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", what, err)
+	}
+	return objects, nil
+}
+
+// moveRows moves the rows an organization has in the public copies of the
+// tables to the schema. Referenced tables are filled before the tables that
+// point at them and emptied after them, so foreign keys hold throughout.
+func moveRows(ctx context.Context, tx pgx.Tx, schema string, organizationID uuid.UUID, tables []string) error {
+	if len(tables) == 0 {
+		return nil
+	}
+	ordered, err := referencedFirst(ctx, tx, tables)
+	if err != nil {
+		return err
+	}
+
+	moved := make([]string, 0, len(ordered))
+	for _, table := range ordered {
+		columns, err := movableColumns(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(columns, "organization_id") {
+			continue
+		}
+		list := make([]string, 0, len(columns))
+		for _, column := range columns {
+			list = append(list, pgx.Identifier{column}.Sanitize())
+		}
+		stmt := fmt.Sprintf("INSERT INTO %s (%s) OVERRIDING SYSTEM VALUE SELECT %[2]s FROM %s WHERE organization_id = $1",
+			qualified(schema, table), strings.Join(list, ", "), qualified("public", table))
+		if _, err := tx.Exec(ctx, stmt, organizationID); err != nil {
+			return fmt.Errorf("move rows of %s: %w", table, err)
+		}
+		moved = append(moved, table)
+	}
+	for i := len(moved) - 1; i >= 0; i-- {
+		stmt := fmt.Sprintf("DELETE FROM %s WHERE organization_id = $1", qualified("public", moved[i]))
+		if _, err := tx.Exec(ctx, stmt, organizationID); err != nil {
+			return fmt.Errorf("remove moved rows of %s: %w", moved[i], err)
+		}
+	}
+	return nil
+}
+
+// referencedFirst orders the tables so every table comes after the tables it
+// references. Tables in a reference cycle keep their original order.
+func referencedFirst(ctx context.Context, tx pgx.Tx, tables []string) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT t.relname, rt.relname
+		FROM pg_constraint c
+		JOIN pg_class t ON t.oid = c.conrelid
+		JOIN pg_class rt ON rt.oid = c.confrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_namespace rn ON rn.oid = rt.relnamespace
+		WHERE c.contype = 'f' AND n.nspname = 'public' AND rn.nspname = 'public'
+			AND t.relname = ANY($1) AND rt.relname = ANY($1) AND t.oid <> rt.oid`, tables,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list table references: %w", err)
+	}
+	references := make(map[string][]string)
+	for rows.Next() {
+		var table, referenced string
+		if err := rows.Scan(&table, &referenced); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan table reference: %w", err)
+		}
+		references[table] = append(references[table], referenced)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list table references: %w", err)
+	}
+
+	ordered := make([]string, 0, len(tables))
+	placed := make(map[string]bool, len(tables))
+	for len(ordered) < len(tables) {
+		progress := false
+		for _, table := range tables {
+			if placed[table] || !allPlaced(references[table], placed) {
+				continue
+			}
+			ordered = append(ordered, table)
+			placed[table] = true
+			progress = true
+		}
+		if !progress {
+			for _, table := range tables {
+				if !placed[table] {
+					ordered = append(ordered, table)
+					placed[table] = true
+				}
+			}
+		}
+	}
+	return ordered, nil
+}
+
+func allPlaced(tables []string, placed map[string]bool) bool {
+	for _, table := range tables {
+		if !placed[table] {
+			return false
+		}
+	}
+	return true
+}
+
+// movableColumns returns the columns of a public table that can be inserted,
+// which leaves out generated columns.
+func movableColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT attname
+		FROM pg_attribute
+		WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped AND attgenerated = ''
+		ORDER BY attnum`, qualified("public", table),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list columns of %s: %w", table, err)
+	}
+	columns, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("list columns of %s: %w", table, err)
+	}
+	return columns, nil
+}
+
+// ListenPlacements calls changed with the organization of every placement
+// change until ctx is done or the connection fails. It listens on a connection
+// of its own, outside the pool; ready is called once it listens.
+func (r *Repository) ListenPlacements(ctx context.Context, ready func(), changed func(uuid.UUID)) error {
+	conn, err := pgx.ConnectConfig(ctx, r.pool.Config().ConnConfig.Copy())
+	if err != nil {
+		return fmt.Errorf("connect placement listener: %w", err)
+	}
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{PlacementChannel}.Sanitize()); err != nil {
+		return fmt.Errorf("listen for placements: %w", err)
+	}
+	ready()
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for placement: %w", err)
+		}
+		if organizationID, err := uuid.Parse(notification.Payload); err == nil {
+			changed(organizationID)
+		}
+	}
+}
+
+// useSchema puts the schema in front of public for the rest of the transaction.
+func useSchema(ctx context.Context, tx pgx.Tx, schema string) error {
+	if _, err := tx.Exec(ctx, "SELECT set_config('search_path', $1, true)", pgx.Identifier{schema}.Sanitize()+", public"); err != nil {
+		return fmt.Errorf("set search path: %w", err)
+	}
+	return nil
+}
+
+// missingColumns returns the statements adding the columns of a public table
+// that its copy in the schema lacks. NOT NULL is only carried over together
+// with a default, so existing rows stay valid.
+func missingColumns(ctx context.Context, tx pgx.Tx, schema, table string) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull, pg_get_expr(d.adbin, d.adrelid)
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_attribute b
+				WHERE b.attrelid = $2::regclass AND b.attname = a.attname AND NOT b.attisdropped
+			)
+		ORDER BY a.attnum`, qualified("public", table), qualified(schema, table),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list missing columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	statements := make([]string, 0)
+	for rows.Next() {
+		var name, dataType string
+		var notNull bool
+		var defaultExpr *string
+		if err := rows.Scan(&name, &dataType, &notNull, &defaultExpr); err != nil {
+			return nil, fmt.Errorf("scan missing column of %s: %w", table, err)
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", qualified(schema, table), pgx.Identifier{name}.Sanitize(), dataType)
+		if defaultExpr != nil {
+			stmt += " DEFAULT " + *defaultExpr
+			if notNull {
+				stmt += " NOT NULL"
+			}
+		}
+		statements = append(statements, stmt)
+	}
+	return statements, rows.Err()
+}
+
+func qualified(schema, table string) string {
+	return pgx.Identifier{schema, table}.Sanitize()
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"portal_final_backend/platform/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// residencySchema holds a lead with its quote and partner offer tables, so the
+// test covers the tables that reference tenant tables without a fully migrated
+// database.
+const residencySchema = `
+CREATE TABLE RAC_organization_data_schemas (
+	organization_id uuid PRIMARY KEY,
+	schema_name text NOT NULL UNIQUE,
+	synced_version bigint NOT NULL DEFAULT 0,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	synced_at timestamptz
+);
+CREATE TABLE RAC_leads (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL
+);
+CREATE TABLE RAC_lead_services (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	lead_id uuid NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE
+);
+CREATE TABLE RAC_quotes (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	lead_id uuid NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE
+);
+CREATE TABLE RAC_quote_payment_installments (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	quote_id uuid NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE
+);
+CREATE TABLE RAC_quote_payments (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	quote_id uuid NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+	installment_id uuid REFERENCES RAC_quote_payment_installments(id) ON DELETE SET NULL,
+	external_id text UNIQUE
+);
+CREATE TABLE RAC_partner_offers (
+	id uuid PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	lead_service_id uuid NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+	public_token text NOT NULL UNIQUE
+);
+`
+
+// openResidencyDB creates a scratch database on the server of
+// TEST_DATABASE_URL, because the repository addresses the public schema by
+// name, and drops it when the test ends.
+func openResidencyDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+	name := "residency_" + uuid.NewString()[:8]
+	if _, err := admin.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		t.Skipf("cannot create scratch database: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP DATABASE "+name+" WITH (FORCE)")
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Path = "/" + name
+	pool, err := db.NewPool(ctx, databaseURL(u.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if _, err := pool.Exec(ctx, residencySchema); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+// databaseURL points a connection string at another database.
+type databaseURL string
+
+func (u databaseURL) GetDatabaseURL() string { return string(u) }
+
+func TestProvisionMovesTablesReferencingTenantTables(t *testing.T) {
+	pool := openResidencyDB(t)
+	ctx := context.Background()
+	orgID, otherOrgID := uuid.New(), uuid.New()
+	leadID, serviceID, quoteID := uuid.New(), uuid.New(), uuid.New()
+
+	mustExec(t, pool, "INSERT INTO RAC_leads (id, organization_id) VALUES ($1, $2)", leadID, orgID)
+	mustExec(t, pool, "INSERT INTO RAC_lead_services (id, organization_id, lead_id) VALUES ($1, $2, $3)", serviceID, orgID, leadID)
+	mustExec(t, pool, "INSERT INTO RAC_quotes (id, organization_id, lead_id) VALUES ($1, $2, $3)", quoteID, orgID, leadID)
+	mustExec(t, pool, "INSERT INTO RAC_quote_payments (id, organization_id, quote_id, external_id) VALUES ($1, $2, $3, 'tr_before')", uuid.New(), orgID, quoteID)
+	otherLeadID := uuid.New()
+	mustExec(t, pool, "INSERT INTO RAC_leads (id, organization_id) VALUES ($1, $2)", otherLeadID, otherOrgID)
+
+	repo := New(pool)
+	schema := "org_" + strings.ReplaceAll(orgID.String(), "-", "")
+	if _, err := repo.Provision(ctx, orgID, schema, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes of the organization land in its schema, including the tables that
+	// only reference tenant tables.
+	orgCtx := db.WithSchema(ctx, schema)
+	installmentID := uuid.New()
+	if _, err := pool.Exec(orgCtx, "INSERT INTO RAC_quote_payment_installments (id, organization_id, quote_id) VALUES ($1, $2, $3)", installmentID, orgID, quoteID); err != nil {
+		t.Fatalf("insert installment: %v", err)
+	}
+	if _, err := pool.Exec(orgCtx, "INSERT INTO RAC_quote_payments (id, organization_id, quote_id, installment_id, external_id) VALUES ($1, $2, $3, $4, 'tr_after')", uuid.New(), orgID, quoteID, installmentID); err != nil {
+		t.Fatalf("insert quote payment: %v", err)
+	}
+	if _, err := pool.Exec(orgCtx, "INSERT INTO RAC_partner_offers (id, organization_id, lead_service_id, public_token) VALUES ($1, $2, $3, 'offer-token')", uuid.New(), orgID, serviceID); err != nil {
+		t.Fatalf("insert partner offer: %v", err)
+	}
+
+	counts := map[string]int{
+		schema + ".rac_leads":          1,
+		schema + ".rac_quote_payments": 2,
+		schema + ".rac_partner_offers": 1,
+		"public.rac_leads":             1,
+		"public.rac_quotes":            0,
+		"public.rac_quote_payments":    0,
+		"public.rac_partner_offers":    0,
+	}
+	for table, want := range counts {
+		var got int
+		if err := pool.QueryRow(ctx, "SELECT count(*) FROM "+table).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s has %d rows, want %d", table, got, want)
+		}
+	}
+
+	for _, token := range []string{"tr_before", "tr_after"} {
+		found, ok, err := repo.FindTokenOrganization(ctx, "rac_quote_payments", []string{"external_id"}, token)
+		if err != nil || !ok || found != orgID {
+			t.Errorf("payment %s: organization = %v, %v, %v, want %v", token, found, ok, err, orgID)
+		}
+	}
+	found, ok, err := repo.FindTokenOrganization(ctx, "rac_partner_offers", []string{"public_token"}, "offer-token")
+	if err != nil || !ok || found != orgID {
+		t.Errorf("partner offer: organization = %v, %v, %v, want %v", found, ok, err, orgID)
+	}
+}
+
+func mustExec(t *testing.T, pool *pgxpool.Pool, sql string, args ...any) {
+	t.Helper()
+	if _, err := pool.Exec(context.Background(), sql, args...); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,275 @@
+// Package service places the leads, quotes, appointments and tasks of an
+// organization in a dedicated Postgres schema and routes its requests there.
+//
+// Routing works through the search_path of pooled connections: the tenant
+// context of an authenticated request or a scheduler task carries the schema
+// of its organization, and unqualified table names resolve to that schema
+// first. Public portal tokens and provider IDs are looked up in the dedicated
+// schemas before their request is bound; maintenance jobs that span
+// organizations visit every schema through db.ForEachSchema.
+//
+// Placements are cached for a minute. Instances drop the cached placement of
+// an organization as soon as it moves, through WatchPlacements.
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"portal_final_backend/internal/residency/repository"
+	"portal_final_backend/internal/residency/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	schemaPrefix = "tenant_"
+	// defaultCacheTTL bounds how long a request may still be routed to the old
+	// placement of an organization on an instance that missed its notification.
+	defaultCacheTTL = time.Minute
+	// listenRetryDelay is the pause before the placement listener reconnects.
+	listenRetryDelay = 5 * time.Second
+)
+
+type Repository interface {
+	GetPlacement(ctx context.Context, organizationID uuid.UUID) (*repository.Placement, error)
+	ListOutdatedPlacements(ctx context.Context, version int64) ([]repository.Placement, error)
+	MigrationVersion(ctx context.Context) (int64, error)
+	HasTenantData(ctx context.Context, organizationID uuid.UUID) (bool, error)
+	ListSchemas(ctx context.Context) ([]string, error)
+	FindTokenOrganization(ctx context.Context, table string, columns []string, token string) (uuid.UUID, bool, error)
+	ListenPlacements(ctx context.Context, ready func(), changed func(uuid.UUID)) error
+	Provision(ctx context.Context, organizationID uuid.UUID, schema string, version int64) (repository.Placement, error)
+	Sync(ctx context.Context, p repository.Placement, version int64) error
+}
+
+// Config controls how long resolved placements are cached.
+type Config struct {
+	CacheTTL time.Duration
+}
+
+type cachedSchema struct {
+	schema    string
+	expiresAt time.Time
+}
+
+type Service struct {
+	repo Repository
+	cfg  Config
+	log  *logger.Logger
+	now  func() time.Time
+
+	mu    sync.RWMutex
+	cache map[uuid.UUID]cachedSchema
+}
+
+func New(repo Repository, cfg Config, log *logger.Logger) *Service {
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = defaultCacheTTL
+	}
+	return &Service{
+		repo:  repo,
+		cfg:   cfg,
+		log:   log,
+		now:   time.Now,
+		cache: make(map[uuid.UUID]cachedSchema),
+	}
+}
+
+// SchemaName returns the dedicated schema name of an organization.
+func SchemaName(organizationID uuid.UUID) string {
+	return schemaPrefix + strings.ReplaceAll(organizationID.String(), "-", "")
+}
+
+// BindTenant routes the queries made with the returned context to the data
+// schema of the organization.
+func (s *Service) BindTenant(ctx context.Context, organizationID uuid.UUID) (context.Context, error) {
+	schema, err := s.schemaFor(ctx, organizationID)
+	if err != nil {
+		return ctx, err
+	}
+	return db.WithSchema(ctx, schema), nil
+}
+
+// TokenLocator returns a lookup of the organization whose row of a tenant
+// table holds a token in one of the columns, in a dedicated or the shared
+// schema. The lookup reports false for unknown tokens.
+func (s *Service) TokenLocator(table string, columns ...string) func(ctx context.Context, token string) (uuid.UUID, bool, error) {
+	return func(ctx context.Context, token string) (uuid.UUID, bool, error) {
+		return s.repo.FindTokenOrganization(ctx, table, columns, token)
+	}
+}
+
+// ListSchemas returns the dedicated schemas of all organizations, for
+// db.ForEachSchema.
+func (s *Service) ListSchemas(ctx context.Context) ([]string, error) {
+	schemas, err := s.repo.ListSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, schema := range schemas {
+		if !db.ValidSchemaName(schema) {
+			return nil, apperr.Internal("invalid data schema name " + schema)
+		}
+	}
+	return schemas, nil
+}
+
+// WatchPlacements drops cached placements as soon as an organization moves to
+// a dedicated schema on any instance, until ctx is done. While the listener is
+// down, cached placements expire after the cache TTL.
+func (s *Service) WatchPlacements(ctx context.Context) {
+	for {
+		err := s.repo.ListenPlacements(ctx, s.forgetAll, s.forget)
+		if ctx.Err() != nil {
+			return
+		}
+		s.log.Warn("residency: placement listener stopped", "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// Get returns where the data of an organization lives.
+func (s *Service) Get(ctx context.Context, organizationID uuid.UUID) (transport.DataSchemaResponse, error) {
+	placement, err := s.repo.GetPlacement(ctx, organizationID)
+	if err != nil {
+		return transport.DataSchemaResponse{}, err
+	}
+	if placement == nil {
+		return transport.DataSchemaResponse{OrganizationID: organizationID, Dedicated: false}, nil
+	}
+	return toResponse(*placement), nil
+}
+
+// Provision moves an organization to a dedicated schema. It is only possible
+// before the organization has any leads or quotes; rows it already has in other
+// tenant tables move along.
+func (s *Service) Provision(ctx context.Context, organizationID uuid.UUID) (transport.DataSchemaResponse, error) {
+	existing, err := s.repo.GetPlacement(ctx, organizationID)
+	if err != nil {
+		return transport.DataSchemaResponse{}, err
+	}
+	if existing != nil {
+		return transport.DataSchemaResponse{}, apperr.Conflict("organization already has a dedicated data schema")
+	}
+
+	hasData, err := s.repo.HasTenantData(ctx, organizationID)
+	if err != nil {
+		return transport.DataSchemaResponse{}, err
+	}
+	if hasData {
+		return transport.DataSchemaResponse{}, apperr.Validation("a dedicated data schema can only be chosen before the organization has leads or quotes")
+	}
+
+	version, err := s.repo.MigrationVersion(ctx)
+	if err != nil {
+		return transport.DataSchemaResponse{}, err
+	}
+	schema := SchemaName(organizationID)
+	if !db.ValidSchemaName(schema) {
+		return transport.DataSchemaResponse{}, apperr.Internal("invalid data schema name")
+	}
+
+	placement, err := s.repo.Provision(ctx, organizationID, schema, version)
+	if err != nil {
+		return transport.DataSchemaResponse{}, err
+	}
+	s.remember(organizationID, schema)
+	s.log.Info("residency: provisioned data schema", "organizationId", organizationID, "schema", schema)
+	return toResponse(placement), nil
+}
+
+// SyncSchemas brings every dedicated schema up to the current migration
+// version. It runs after the migrations of the shared schema; a failing schema
+// is logged and retried on the next start.
+func (s *Service) SyncSchemas(ctx context.Context) error {
+	version, err := s.repo.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	placements, err := s.repo.ListOutdatedPlacements(ctx, version)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range placements {
+		if !db.ValidSchemaName(p.SchemaName) {
+			errs = append(errs, apperr.Internal("invalid data schema name "+p.SchemaName))
+			continue
+		}
+		if err := s.repo.Sync(ctx, p, version); err != nil {
+			s.log.Error("residency: failed to sync data schema", "organizationId", p.OrganizationID, "schema", p.SchemaName, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		s.log.Info("residency: synced data schema", "organizationId", p.OrganizationID, "schema", p.SchemaName, "fromVersion", p.SyncedVersion, "toVersion", version)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) schemaFor(ctx context.Context, organizationID uuid.UUID) (string, error) {
+	now := s.now()
+	s.mu.RLock()
+	cached, ok := s.cache[organizationID]
+	s.mu.RUnlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.schema, nil
+	}
+
+	placement, err := s.repo.GetPlacement(ctx, organizationID)
+	if err != nil {
+		return "", err
+	}
+	schema := ""
+	if placement != nil {
+		if !db.ValidSchemaName(placement.SchemaName) {
+			return "", apperr.Internal("invalid data schema name")
+		}
+		schema = placement.SchemaName
+	}
+	s.remember(organizationID, schema)
+	return schema, nil
+}
+
+func (s *Service) remember(organizationID uuid.UUID, schema string) {
+	s.mu.Lock()
+	s.cache[organizationID] = cachedSchema{schema: schema, expiresAt: s.now().Add(s.cfg.CacheTTL)}
+	s.mu.Unlock()
+}
+
+func (s *Service) forget(organizationID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.cache, organizationID)
+	s.mu.Unlock()
+}
+
+// forgetAll drops every cached placement, since notifications sent while the
+// listener was down are lost.
+func (s *Service) forgetAll() {
+	s.mu.Lock()
+	clear(s.cache)
+	s.mu.Unlock()
+}
+
+func toResponse(p repository.Placement) transport.DataSchemaResponse {
+	createdAt := p.CreatedAt
+	return transport.DataSchemaResponse{
+		OrganizationID: p.OrganizationID,
+		Dedicated:      true,
+		SchemaName:     p.SchemaName,
+		SyncedVersion:  p.SyncedVersion,
+		CreatedAt:      &createdAt,
+		SyncedAt:       p.SyncedAt,
+	}
+}
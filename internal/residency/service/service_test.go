@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/residency/repository"
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	Repository
+	placement *repository.Placement
+	lookups   int
+	moved     []uuid.UUID
+}
+
+func (f *fakeRepository) GetPlacement(context.Context, uuid.UUID) (*repository.Placement, error) {
+	f.lookups++
+	return f.placement, nil
+}
+
+func (f *fakeRepository) ListenPlacements(ctx context.Context, ready func(), changed func(uuid.UUID)) error {
+	ready()
+	for _, organizationID := range f.moved {
+		changed(organizationID)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestSchemaNameIsValidIdentifier(t *testing.T) {
+	name := SchemaName(uuid.MustParse("0b6f3c1e-2a4d-4c1b-9e7f-5d8a6b2c4e10"))
+	if name != "tenant_0b6f3c1e2a4d4c1b9e7f5d8a6b2c4e10" {
+		t.Fatalf("SchemaName() = %q", name)
+	}
+	if !db.ValidSchemaName(name) {
+		t.Fatalf("SchemaName() = %q is not a valid schema name", name)
+	}
+}
+
+func TestBindTenantCachesPlacement(t *testing.T) {
+	orgID := uuid.New()
+	repo := &fakeRepository{placement: &repository.Placement{OrganizationID: orgID, SchemaName: SchemaName(orgID)}}
+	svc := New(repo, Config{CacheTTL: time.Minute}, logger.New("development"))
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	for range 3 {
+		ctx, err := svc.BindTenant(context.Background(), orgID)
+		if err != nil {
+			t.Fatalf("BindTenant() error = %v", err)
+		}
+		if got := db.SchemaFromContext(ctx); got != SchemaName(orgID) {
+			t.Fatalf("schema = %q, want %q", got, SchemaName(orgID))
+		}
+	}
+	if repo.lookups != 1 {
+		t.Fatalf("lookups = %d, want 1", repo.lookups)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := svc.BindTenant(context.Background(), orgID); err != nil {
+		t.Fatalf("BindTenant() error = %v", err)
+	}
+	if repo.lookups != 2 {
+		t.Fatalf("lookups after expiry = %d, want 2", repo.lookups)
+	}
+}
+
+func TestBindTenantWithoutPlacementUsesSharedSchema(t *testing.T) {
+	svc := New(&fakeRepository{}, Config{}, logger.New("development"))
+
+	ctx, err := svc.BindTenant(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("BindTenant() error = %v", err)
+	}
+	if got := db.SchemaFromContext(ctx); got != "" {
+		t.Fatalf("schema = %q, want shared", got)
+	}
+}
+
+func TestWatchPlacementsDropsCachedPlacementOfMovedOrganization(t *testing.T) {
+	movedID, otherID := uuid.New(), uuid.New()
+	repo := &fakeRepository{}
+	svc := New(repo, Config{CacheTTL: time.Hour}, logger.New("development"))
+	for _, id := range []uuid.UUID{movedID, otherID} {
+		if _, err := svc.BindTenant(context.Background(), id); err != nil {
+			t.Fatalf("BindTenant() error = %v", err)
+		}
+	}
+
+	// The organization moves on another instance while this one is listening.
+	repo.placement = &repository.Placement{OrganizationID: movedID, SchemaName: SchemaName(movedID)}
+	repo.moved = []uuid.UUID{movedID}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.WatchPlacements(ctx)
+		close(done)
+	}()
+	for {
+		svc.mu.RLock()
+		_, cached := svc.cache[movedID]
+		svc.mu.RUnlock()
+		if !cached {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	ctx, err := svc.BindTenant(context.Background(), movedID)
+	if err != nil {
+		t.Fatalf("BindTenant() error = %v", err)
+	}
+	if got := db.SchemaFromContext(ctx); got != SchemaName(movedID) {
+		t.Fatalf("schema after move = %q, want %q", got, SchemaName(movedID))
+	}
+}
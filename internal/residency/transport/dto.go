@@ -0,0 +1,20 @@
+// Package transport provides DTOs for organization data schemas.
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataSchemaResponse describes where the leads, quotes, appointments and tasks
+// of an organization are stored. Organizations without a dedicated schema use
+// the shared one.
+type DataSchemaResponse struct {
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	Dedicated      bool       `json:"dedicated"`
+	SchemaName     string     `json:"schemaName,omitempty"`
+	SyncedVersion  int64      `json:"syncedVersion,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+	SyncedAt       *time.Time `json:"syncedAt,omitempty"`
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"time"
+
+	"portal_final_backend/internal/retention/service"
+	"portal_final_backend/internal/retention/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+// RegisterAdminRoutes registers the retention policy settings and dry-run report.
+func (h *Handler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/retention/policy", h.GetPolicy)
+	rg.PUT("/retention/policy", h.UpdatePolicy)
+	rg.GET("/retention/report", h.GetReport)
+}
+
+func (h *Handler) GetPolicy(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetPolicy(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) UpdatePolicy(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.UpdateRetentionPolicyRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.UpdatePolicy(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+// GetReport reports what the retention policy would change if it ran now.
+func (h *Handler) GetReport(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.DryRun(c.Request.Context(), tenantID, time.Now())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
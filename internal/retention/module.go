@@ -0,0 +1,43 @@
+// Package retention provides organization-configurable data retention policies
+// that the scheduler applies to rejected leads, timeline events and orphaned
+// draft quotes.
+package retention
+
+import (
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/internal/retention/handler"
+	"portal_final_backend/internal/retention/repository"
+	"portal_final_backend/internal/retention/service"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+}
+
+func NewModule(pool *pgxpool.Pool, cfg service.Config, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), cfg, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+	}
+}
+
+// Service returns the retention service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "retention"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterAdminRoutes(ctx.Admin)
+}
+
+var _ apphttp.Module = (*Module)(nil)
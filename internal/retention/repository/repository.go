@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository persists retention policies and applies them to the tenant data.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Policy is the retention configuration of an organization. Nil windows keep
+// the data forever.
+type Policy struct {
+	OrganizationID     uuid.UUID
+	RejectedLeadAction string
+	RejectedLeadDays   int
+	TimelineEventDays  *int
+	DraftQuoteDays     *int
+	LastAppliedAt      *time.Time
+	UpdatedAt          time.Time
+}
+
+const policyColumns = `organization_id, rejected_lead_action, rejected_lead_days, timeline_event_days, draft_quote_days, last_applied_at, updated_at`
+
+// rejectedLeadsQuery selects the leads of an organization whose services are
+// all lost, or that arrived incomplete without any service, and that saw no
+// activity since the cutoff. Leads with an accepted quote are kept for the
+// administration.
+const rejectedLeadsQuery = `
+	SELECT l.id
+	FROM RAC_leads l
+	WHERE l.organization_id = $1
+		AND NOT EXISTS (
+			SELECT 1 FROM RAC_lead_services s
+			WHERE s.lead_id = l.id AND s.pipeline_stage <> 'Lost'
+		)
+		AND (l.is_incomplete OR EXISTS (SELECT 1 FROM RAC_lead_services s WHERE s.lead_id = l.id))
+		AND NOT EXISTS (
+			SELECT 1 FROM RAC_quotes q
+			WHERE q.lead_id = l.id AND q.status = 'Accepted'
+		)
+		AND GREATEST(l.updated_at, COALESCE((SELECT max(s.updated_at) FROM RAC_lead_services s WHERE s.lead_id = l.id), l.updated_at)) < $2`
+
+// notAnonymizedFilter skips leads that an earlier run already anonymized.
+const notAnonymizedFilter = `
+		AND l.anonymized_at IS NULL`
+
+const timelineEventsQuery = `
+	SELECT e.id
+	FROM lead_timeline_events e
+	WHERE e.organization_id = $1 AND e.created_at < $2`
+
+// orphanedDraftQuotesQuery selects untouched draft quotes whose lead was
+// deleted, or whose service was removed or closed.
+const orphanedDraftQuotesQuery = `
+	SELECT q.id
+	FROM RAC_quotes q
+	JOIN RAC_leads l ON l.id = q.lead_id
+	LEFT JOIN RAC_lead_services s ON s.id = q.lead_service_id
+	WHERE q.organization_id = $1
+		AND q.status = 'Draft'
+		AND q.updated_at < $2
+		AND (l.deleted_at IS NOT NULL OR s.id IS NULL OR s.pipeline_stage IN ('Completed', 'Lost'))`
+
+func scanPolicy(row pgx.Row) (Policy, error) {
+	var p Policy
+	err := row.Scan(&p.OrganizationID, &p.RejectedLeadAction, &p.RejectedLeadDays, &p.TimelineEventDays, &p.DraftQuoteDays, &p.LastAppliedAt, &p.UpdatedAt)
+	return p, err
+}
+
+// GetPolicy returns the retention policy of an organization, or nil when the
+// organization never configured one.
+func (r *Repository) GetPolicy(ctx context.Context, organizationID uuid.UUID) (*Policy, error) {
+	p, err := scanPolicy(r.pool.QueryRow(ctx, `
+		SELECT `+policyColumns+`
+		FROM RAC_organization_retention_policies
+		WHERE organization_id = $1`, organizationID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get retention policy: %w", err)
+	}
+	return &p, nil
+}
+
+// UpsertPolicy stores the retention policy of an organization.
+func (r *Repository) UpsertPolicy(ctx context.Context, p Policy) (Policy, error) {
+	stored, err := scanPolicy(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_organization_retention_policies (organization_id, rejected_lead_action, rejected_lead_days, timeline_event_days, draft_quote_days)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			rejected_lead_action = EXCLUDED.rejected_lead_action,
+			rejected_lead_days = EXCLUDED.rejected_lead_days,
+			timeline_event_days = EXCLUDED.timeline_event_days,
+			draft_quote_days = EXCLUDED.draft_quote_days,
+			updated_at = now()
+		RETURNING `+policyColumns, p.OrganizationID, p.RejectedLeadAction, p.RejectedLeadDays, p.TimelineEventDays, p.DraftQuoteDays,
+	))
+	if err != nil {
+		return Policy{}, fmt.Errorf("upsert retention policy: %w", err)
+	}
+	return stored, nil
+}
+
+// ListActivePolicies returns the policies that retain at least one kind of data
+// for a limited time.
+func (r *Repository) ListActivePolicies(ctx context.Context) ([]Policy, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+policyColumns+`
+		FROM RAC_organization_retention_policies
+		WHERE rejected_lead_action <> 'off' OR timeline_event_days IS NOT NULL OR draft_quote_days IS NOT NULL
+		ORDER BY organization_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list retention policies: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Policy, 0)
+	for rows.Next() {
+		p, err := scanPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan retention policy: %w", err)
+		}
+		items = append(items, p)
+	}
+	return items, rows.Err()
+}
+
+// MarkApplied records when the policy of an organization was last applied.
+func (r *Repository) MarkApplied(ctx context.Context, organizationID uuid.UUID, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE RAC_organization_retention_policies
+		SET last_applied_at = $2
+		WHERE organization_id = $1`, organizationID, at,
+	)
+	if err != nil {
+		return fmt.Errorf("mark retention policy applied: %w", err)
+	}
+	return nil
+}
+
+// CountRejectedLeads counts the rejected leads that were inactive since the
+// cutoff. Already anonymized leads only count when they are to be purged.
+func (r *Repository) CountRejectedLeads(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, includeAnonymized bool) (int64, error) {
+	query := rejectedLeadsQuery
+	if !includeAnonymized {
+		query += notAnonymizedFilter
+	}
+	return r.count(ctx, "rejected leads", query, organizationID, cutoff)
+}
+
+// AnonymizeRejectedLeads strips the personal data of at most limit rejected
+// leads. Services and quotes stay for reporting; lead notes and intake notes
+// are removed because they are free text about the consumer.
+func (r *Repository) AnonymizeRejectedLeads(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `
+		WITH targets AS (`+rejectedLeadsQuery+notAnonymizedFilter+`
+			LIMIT $3
+		), cleared_services AS (
+			UPDATE RAC_lead_services SET consumer_note = NULL
+			WHERE lead_id IN (SELECT id FROM targets)
+		), deleted_notes AS (
+			DELETE FROM RAC_lead_notes
+			WHERE lead_id IN (SELECT id FROM targets)
+		)
+		UPDATE RAC_leads SET
+			consumer_first_name = 'Geanonimiseerd',
+			consumer_last_name = '',
+			consumer_phone = '',
+			consumer_email = NULL,
+			consumer_phone_encrypted = NULL,
+			consumer_email_encrypted = NULL,
+			contact_key_id = NULL,
+			consumer_phone_bidx = NULL,
+			consumer_email_bidx = NULL,
+			address_street = '',
+			address_house_number = '',
+			address_zip_code = '',
+			address_city = '',
+			latitude = NULL,
+			longitude = NULL,
+			raw_form_data = NULL,
+			public_token = NULL,
+			gclid = NULL,
+			fbclid = NULL,
+			utm_source = NULL,
+			utm_medium = NULL,
+			utm_campaign = NULL,
+			utm_content = NULL,
+			utm_term = NULL,
+			ad_landing_page = NULL,
+			referrer_url = NULL,
+			anonymized_at = now(),
+			updated_at = now()
+		WHERE id IN (SELECT id FROM targets)`, organizationID, cutoff, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("anonymize rejected leads: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PurgeRejectedLeads deletes at most limit rejected leads with everything that
+// cascades from them.
+func (r *Repository) PurgeRejectedLeads(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error) {
+	return r.deleteBatch(ctx, "rejected leads", `DELETE FROM RAC_leads WHERE id IN (`+rejectedLeadsQuery+`
+			LIMIT $3)`, organizationID, cutoff, limit)
+}
+
+// CountTimelineEvents counts the timeline events created before the cutoff.
+func (r *Repository) CountTimelineEvents(ctx context.Context, organizationID uuid.UUID, cutoff time.Time) (int64, error) {
+	return r.count(ctx, "timeline events", timelineEventsQuery, organizationID, cutoff)
+}
+
+// DeleteTimelineEvents deletes at most limit timeline events created before the cutoff.
+func (r *Repository) DeleteTimelineEvents(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error) {
+	return r.deleteBatch(ctx, "timeline events", `DELETE FROM lead_timeline_events WHERE id IN (`+timelineEventsQuery+`
+			LIMIT $3)`, organizationID, cutoff, limit)
+}
+
+// CountOrphanedDraftQuotes counts the orphaned draft quotes untouched since the cutoff.
+func (r *Repository) CountOrphanedDraftQuotes(ctx context.Context, organizationID uuid.UUID, cutoff time.Time) (int64, error) {
+	return r.count(ctx, "orphaned draft quotes", orphanedDraftQuotesQuery, organizationID, cutoff)
+}
+
+// DeleteOrphanedDraftQuotes deletes at most limit orphaned draft quotes untouched since the cutoff.
+func (r *Repository) DeleteOrphanedDraftQuotes(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error) {
+	return r.deleteBatch(ctx, "orphaned draft quotes", `DELETE FROM RAC_quotes WHERE id IN (`+orphanedDraftQuotesQuery+`
+			LIMIT $3)`, organizationID, cutoff, limit)
+}
+
+func (r *Repository) count(ctx context.Context, what, query string, organizationID uuid.UUID, cutoff time.Time) (int64, error) {
+	var total int64
+	if err := r.pool.QueryRow(ctx, `SELECT count(*) FROM (`+query+`) candidates`, organizationID, cutoff).Scan(&total); err != nil {
+		return 0, fmt.Errorf("count %s: %w", what, err)
+	}
+	return total, nil
+}
+
+func (r *Repository) deleteBatch(ctx context.Context, what, query string, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error) {
+	tag, err := r.pool.Exec(ctx, query, organizationID, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("delete %s: %w", what, err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,278 @@
+// Package service implements per-organization data retention: anonymizing or
+// purging rejected leads, trimming old timeline events and cleaning orphaned
+// draft quotes.
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"portal_final_backend/internal/retention/repository"
+	"portal_final_backend/internal/retention/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+// Rejected lead actions.
+const (
+	ActionOff       = "off"
+	ActionAnonymize = "anonymize"
+	ActionPurge     = "purge"
+)
+
+const (
+	// DefaultBatchSize bounds the rows a single statement changes.
+	DefaultBatchSize = 500
+	// maxBatchesPerCategory bounds one run per organization and kind of data;
+	// whatever remains is handled by the next run.
+	maxBatchesPerCategory   = 20
+	defaultRejectedLeadDays = 365
+	actionDelete            = "delete"
+)
+
+type Repository interface {
+	GetPolicy(ctx context.Context, organizationID uuid.UUID) (*repository.Policy, error)
+	UpsertPolicy(ctx context.Context, p repository.Policy) (repository.Policy, error)
+	ListActivePolicies(ctx context.Context) ([]repository.Policy, error)
+	MarkApplied(ctx context.Context, organizationID uuid.UUID, at time.Time) error
+	CountRejectedLeads(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, includeAnonymized bool) (int64, error)
+	AnonymizeRejectedLeads(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error)
+	PurgeRejectedLeads(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error)
+	CountTimelineEvents(ctx context.Context, organizationID uuid.UUID, cutoff time.Time) (int64, error)
+	DeleteTimelineEvents(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error)
+	CountOrphanedDraftQuotes(ctx context.Context, organizationID uuid.UUID, cutoff time.Time) (int64, error)
+	DeleteOrphanedDraftQuotes(ctx context.Context, organizationID uuid.UUID, cutoff time.Time, limit int) (int64, error)
+}
+
+// Config controls how much a retention run changes per statement.
+type Config struct {
+	BatchSize int
+}
+
+// ApplyResult summarizes a retention run over all organizations.
+type ApplyResult struct {
+	Organizations  int
+	RejectedLeads  int64
+	TimelineEvents int64
+	DraftQuotes    int64
+	Failed         int
+}
+
+type Service struct {
+	repo Repository
+	cfg  Config
+	log  *logger.Logger
+}
+
+func New(repo Repository, cfg Config, log *logger.Logger) *Service {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	return &Service{repo: repo, cfg: cfg, log: log}
+}
+
+// GetPolicy returns the retention policy of an organization; organizations
+// without a stored policy retain everything.
+func (s *Service) GetPolicy(ctx context.Context, organizationID uuid.UUID) (transport.RetentionPolicyResponse, error) {
+	policy, err := s.policy(ctx, organizationID)
+	if err != nil {
+		return transport.RetentionPolicyResponse{}, err
+	}
+	return toPolicyResponse(policy), nil
+}
+
+// UpdatePolicy replaces the retention policy of an organization. It takes
+// effect on the next scheduled run.
+func (s *Service) UpdatePolicy(ctx context.Context, organizationID uuid.UUID, req transport.UpdateRetentionPolicyRequest) (transport.RetentionPolicyResponse, error) {
+	stored, err := s.repo.UpsertPolicy(ctx, repository.Policy{
+		OrganizationID:     organizationID,
+		RejectedLeadAction: req.RejectedLeadAction,
+		RejectedLeadDays:   req.RejectedLeadDays,
+		TimelineEventDays:  req.TimelineEventDays,
+		DraftQuoteDays:     req.DraftQuoteDays,
+	})
+	if err != nil {
+		return transport.RetentionPolicyResponse{}, err
+	}
+	return toPolicyResponse(stored), nil
+}
+
+// DryRun reports how many records the retention policy of an organization
+// would anonymize or delete if it ran now, without changing anything.
+func (s *Service) DryRun(ctx context.Context, organizationID uuid.UUID, now time.Time) (transport.RetentionReportResponse, error) {
+	policy, err := s.policy(ctx, organizationID)
+	if err != nil {
+		return transport.RetentionReportResponse{}, err
+	}
+
+	resp := transport.RetentionReportResponse{
+		Policy:         toPolicyResponse(policy),
+		GeneratedAt:    now,
+		RejectedLeads:  transport.RetentionCategoryReport{Action: ActionOff},
+		TimelineEvents: transport.RetentionCategoryReport{Action: ActionOff},
+		DraftQuotes:    transport.RetentionCategoryReport{Action: ActionOff},
+	}
+
+	if policy.RejectedLeadAction != ActionOff {
+		cutoff := cutoffFor(now, policy.RejectedLeadDays)
+		affected, err := s.repo.CountRejectedLeads(ctx, organizationID, cutoff, policy.RejectedLeadAction == ActionPurge)
+		if err != nil {
+			return transport.RetentionReportResponse{}, err
+		}
+		resp.RejectedLeads = transport.RetentionCategoryReport{Action: policy.RejectedLeadAction, Cutoff: &cutoff, Affected: affected}
+	}
+	if policy.TimelineEventDays != nil {
+		cutoff := cutoffFor(now, *policy.TimelineEventDays)
+		affected, err := s.repo.CountTimelineEvents(ctx, organizationID, cutoff)
+		if err != nil {
+			return transport.RetentionReportResponse{}, err
+		}
+		resp.TimelineEvents = transport.RetentionCategoryReport{Action: actionDelete, Cutoff: &cutoff, Affected: affected}
+	}
+	if policy.DraftQuoteDays != nil {
+		cutoff := cutoffFor(now, *policy.DraftQuoteDays)
+		affected, err := s.repo.CountOrphanedDraftQuotes(ctx, organizationID, cutoff)
+		if err != nil {
+			return transport.RetentionReportResponse{}, err
+		}
+		resp.DraftQuotes = transport.RetentionCategoryReport{Action: actionDelete, Cutoff: &cutoff, Affected: affected}
+	}
+	return resp, nil
+}
+
+// ApplyAll applies every active retention policy. A failing organization is
+// logged and skipped so it does not hold back the others.
+func (s *Service) ApplyAll(ctx context.Context, now time.Time) (ApplyResult, error) {
+	policies, err := s.repo.ListActivePolicies(ctx)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	var result ApplyResult
+	var errs []error
+	for _, policy := range policies {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Organizations++
+		orgCtx, err := httpkit.BindTenant(ctx, policy.OrganizationID)
+		if err != nil {
+			result.Failed++
+			errs = append(errs, err)
+			s.log.Error("retention: failed to bind organization", "organizationId", policy.OrganizationID, "error", err)
+			continue
+		}
+		leads, events, quotes, err := s.apply(orgCtx, policy, now)
+		result.RejectedLeads += leads
+		result.TimelineEvents += events
+		result.DraftQuotes += quotes
+		if err != nil {
+			result.Failed++
+			errs = append(errs, err)
+			s.log.Error("retention: failed to apply policy", "organizationId", policy.OrganizationID, "error", err)
+			continue
+		}
+		if err := s.repo.MarkApplied(ctx, policy.OrganizationID, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+func (s *Service) apply(ctx context.Context, policy repository.Policy, now time.Time) (int64, int64, int64, error) {
+	orgID := policy.OrganizationID
+	var leads, events, quotes int64
+	var err error
+
+	switch policy.RejectedLeadAction {
+	case ActionAnonymize:
+		leads, err = s.drain(ctx, func(limit int) (int64, error) {
+			return s.repo.AnonymizeRejectedLeads(ctx, orgID, cutoffFor(now, policy.RejectedLeadDays), limit)
+		})
+	case ActionPurge:
+		leads, err = s.drain(ctx, func(limit int) (int64, error) {
+			return s.repo.PurgeRejectedLeads(ctx, orgID, cutoffFor(now, policy.RejectedLeadDays), limit)
+		})
+	}
+	if err != nil {
+		return leads, events, quotes, err
+	}
+
+	if policy.TimelineEventDays != nil {
+		cutoff := cutoffFor(now, *policy.TimelineEventDays)
+		events, err = s.drain(ctx, func(limit int) (int64, error) {
+			return s.repo.DeleteTimelineEvents(ctx, orgID, cutoff, limit)
+		})
+		if err != nil {
+			return leads, events, quotes, err
+		}
+	}
+
+	if policy.DraftQuoteDays != nil {
+		cutoff := cutoffFor(now, *policy.DraftQuoteDays)
+		quotes, err = s.drain(ctx, func(limit int) (int64, error) {
+			return s.repo.DeleteOrphanedDraftQuotes(ctx, orgID, cutoff, limit)
+		})
+	}
+	return leads, events, quotes, err
+}
+
+// drain runs a batch operation until it changes less than a full batch.
+func (s *Service) drain(ctx context.Context, batch func(limit int) (int64, error)) (int64, error) {
+	var total int64
+	for i := 0; i < maxBatchesPerCategory; i++ {
+		if ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+		affected, err := batch(s.cfg.BatchSize)
+		total += affected
+		if err != nil {
+			return total, err
+		}
+		if affected < int64(s.cfg.BatchSize) {
+			break
+		}
+	}
+	return total, nil
+}
+
+func (s *Service) policy(ctx context.Context, organizationID uuid.UUID) (repository.Policy, error) {
+	policy, err := s.repo.GetPolicy(ctx, organizationID)
+	if err != nil {
+		return repository.Policy{}, err
+	}
+	if policy == nil {
+		return defaultPolicy(organizationID), nil
+	}
+	return *policy, nil
+}
+
+func defaultPolicy(organizationID uuid.UUID) repository.Policy {
+	return repository.Policy{
+		OrganizationID:     organizationID,
+		RejectedLeadAction: ActionOff,
+		RejectedLeadDays:   defaultRejectedLeadDays,
+	}
+}
+
+func cutoffFor(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
+
+func toPolicyResponse(p repository.Policy) transport.RetentionPolicyResponse {
+	resp := transport.RetentionPolicyResponse{
+		RejectedLeadAction: p.RejectedLeadAction,
+		RejectedLeadDays:   p.RejectedLeadDays,
+		TimelineEventDays:  p.TimelineEventDays,
+		DraftQuoteDays:     p.DraftQuoteDays,
+		LastAppliedAt:      p.LastAppliedAt,
+	}
+	if !p.UpdatedAt.IsZero() {
+		updatedAt := p.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/retention/repository"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	Repository
+	policy    *repository.Policy
+	remaining int64
+	cutoffs   []time.Time
+}
+
+func (f *fakeRepository) GetPolicy(context.Context, uuid.UUID) (*repository.Policy, error) {
+	return f.policy, nil
+}
+
+func (f *fakeRepository) DeleteTimelineEvents(_ context.Context, _ uuid.UUID, cutoff time.Time, limit int) (int64, error) {
+	f.cutoffs = append(f.cutoffs, cutoff)
+	n := min(f.remaining, int64(limit))
+	f.remaining -= n
+	return n, nil
+}
+
+func TestDryRunWithoutPolicyRetainsEverything(t *testing.T) {
+	svc := New(&fakeRepository{}, Config{}, logger.New("development"))
+
+	resp, err := svc.DryRun(context.Background(), uuid.New(), time.Now())
+	if err != nil {
+		t.Fatalf("DryRun() error = %v", err)
+	}
+	if resp.Policy.RejectedLeadAction != ActionOff || resp.RejectedLeads.Affected != 0 || resp.TimelineEvents.Cutoff != nil {
+		t.Fatalf("unexpected report: %+v", resp)
+	}
+}
+
+func TestApplyDrainsBatchesUntilPartialBatch(t *testing.T) {
+	days := 90
+	repo := &fakeRepository{remaining: 25}
+	svc := New(repo, Config{BatchSize: 10}, logger.New("development"))
+	now := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+
+	_, events, _, err := svc.apply(context.Background(), repository.Policy{
+		OrganizationID:     uuid.New(),
+		RejectedLeadAction: ActionOff,
+		TimelineEventDays:  &days,
+	}, now)
+	if err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+	if events != 25 || len(repo.cutoffs) != 3 {
+		t.Fatalf("deleted %d events in %d batches, want 25 in 3", events, len(repo.cutoffs))
+	}
+	if want := now.AddDate(0, 0, -90); !repo.cutoffs[0].Equal(want) {
+		t.Fatalf("cutoff = %s, want %s", repo.cutoffs[0], want)
+	}
+}
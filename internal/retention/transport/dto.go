@@ -0,0 +1,42 @@
+// Package transport provides DTOs for organization data retention policies.
+package transport
+
+import "time"
+
+// RetentionPolicyResponse is the retention policy of an organization. Omitted
+// windows keep the data forever.
+type RetentionPolicyResponse struct {
+	RejectedLeadAction string     `json:"rejectedLeadAction"`
+	RejectedLeadDays   int        `json:"rejectedLeadDays"`
+	TimelineEventDays  *int       `json:"timelineEventDays,omitempty"`
+	DraftQuoteDays     *int       `json:"draftQuoteDays,omitempty"`
+	LastAppliedAt      *time.Time `json:"lastAppliedAt,omitempty"`
+	UpdatedAt          *time.Time `json:"updatedAt,omitempty"`
+}
+
+// UpdateRetentionPolicyRequest replaces the retention policy of an organization.
+// Rejected leads are leads whose services are all lost, or incomplete webhook
+// leads without a service.
+type UpdateRetentionPolicyRequest struct {
+	RejectedLeadAction string `json:"rejectedLeadAction" validate:"required,oneof=off anonymize purge"`
+	RejectedLeadDays   int    `json:"rejectedLeadDays" validate:"required,gte=30,lte=3650"`
+	TimelineEventDays  *int   `json:"timelineEventDays" validate:"omitempty,gte=90,lte=3650"`
+	DraftQuoteDays     *int   `json:"draftQuoteDays" validate:"omitempty,gte=7,lte=3650"`
+}
+
+// RetentionCategoryReport describes what a policy would do to one kind of data.
+type RetentionCategoryReport struct {
+	Action   string     `json:"action"`
+	Cutoff   *time.Time `json:"cutoff,omitempty"`
+	Affected int64      `json:"affected"`
+}
+
+// RetentionReportResponse is the dry-run outcome of the retention policy of an
+// organization at the time of the request.
+type RetentionReportResponse struct {
+	Policy         RetentionPolicyResponse `json:"policy"`
+	GeneratedAt    time.Time               `json:"generatedAt"`
+	RejectedLeads  RetentionCategoryReport `json:"rejectedLeads"`
+	TimelineEvents RetentionCategoryReport `json:"timelineEvents"`
+	DraftQuotes    RetentionCategoryReport `json:"draftQuotes"`
+}
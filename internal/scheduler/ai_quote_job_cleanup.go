@@ -11,24 +11,20 @@ import (
 )
 
 const (
-	defaultAIQuoteJobCleanupInterval = time.Hour
-	defaultCompletedJobRetention     = 14 * 24 * time.Hour
-	defaultFailedJobRetention        = 30 * 24 * time.Hour
+	defaultCompletedJobRetention = 14 * 24 * time.Hour
+	defaultFailedJobRetention    = 30 * 24 * time.Hour
 )
 
-// AIQuoteJobCleanup periodically removes old finished AI quote jobs.
+// AIQuoteJobCleanup removes old finished AI quote jobs. It runs as the
+// TaskAIQuoteJobCleanup periodic task.
 type AIQuoteJobCleanup struct {
 	repo               *quotesrepo.Repository
 	log                *logger.Logger
-	interval           time.Duration
 	completedRetention time.Duration
 	failedRetention    time.Duration
 }
 
-func NewAIQuoteJobCleanup(pool *pgxpool.Pool, log *logger.Logger, interval, completedRetention, failedRetention time.Duration) *AIQuoteJobCleanup {
-	if interval <= 0 {
-		interval = defaultAIQuoteJobCleanupInterval
-	}
+func NewAIQuoteJobCleanup(pool *pgxpool.Pool, log *logger.Logger, completedRetention, failedRetention time.Duration) *AIQuoteJobCleanup {
 	if completedRetention <= 0 {
 		completedRetention = defaultCompletedJobRetention
 	}
@@ -39,33 +35,17 @@ func NewAIQuoteJobCleanup(pool *pgxpool.Pool, log *logger.Logger, interval, comp
 	return &AIQuoteJobCleanup{
 		repo:               quotesrepo.New(pool),
 		log:                log,
-		interval:           interval,
 		completedRetention: completedRetention,
 		failedRetention:    failedRetention,
 	}
 }
 
-func (c *AIQuoteJobCleanup) Run(ctx context.Context) {
+// Cleanup deletes finished jobs past their retention.
+func (c *AIQuoteJobCleanup) Cleanup(ctx context.Context) error {
 	if c == nil || c.repo == nil {
-		return
-	}
-
-	c.cleanup(ctx)
-
-	ticker := time.NewTicker(c.interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			c.cleanup(ctx)
-		}
+		return nil
 	}
-}
 
-func (c *AIQuoteJobCleanup) cleanup(ctx context.Context) {
 	now := time.Now()
 	completedBefore := now.Add(-c.completedRetention)
 	failedBefore := now.Add(-c.failedRetention)
@@ -73,10 +53,11 @@ func (c *AIQuoteJobCleanup) cleanup(ctx context.Context) {
 	deleted, err := c.repo.DeleteFinishedGenerateQuoteJobsBefore(ctx, completedBefore, failedBefore)
 	if err != nil {
 		c.log.Warn("ai quote job cleanup failed", "error", err)
-		return
+		return err
 	}
 
 	if deleted > 0 {
 		c.log.Info("ai quote job cleanup deleted finished jobs", "deleted", deleted)
 	}
+	return nil
 }
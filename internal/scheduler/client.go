@@ -37,6 +37,10 @@ const (
 	staleLeadReEngageTaskTimeout   = 3 * time.Minute
 	staleLeadReEngageTaskUniqueTTL = 24 * time.Hour
 	staleLeadReEngageTaskMaxRetry  = 2
+	catalogGapTaskTimeout          = 10 * time.Minute
+	catalogGapTaskUniqueTTL        = time.Hour
+	catalogGapTaskMaxRetry         = 2
+	maintenanceRunTaskTimeout      = 12 * time.Hour
 )
 
 type Client struct {
@@ -93,6 +97,10 @@ type StaleLeadReEngageScheduler interface {
 	EnqueueStaleLeadReEngage(ctx context.Context, payload StaleLeadReEngagePayload) error
 }
 
+type CatalogGapScheduler interface {
+	EnqueueCatalogGapAnalysis(ctx context.Context, payload CatalogGapAnalyzePayload) error
+}
+
 type QuoteJobRunner interface {
 	EnqueueGenerateQuoteJobRequest(ctx context.Context, req GenerateQuoteJobRequest) error
 }
@@ -144,6 +152,11 @@ func NewClient(cfg config.SchedulerConfig) (*Client, error) {
 	}, nil
 }
 
+// queueFor returns the priority queue for a task type.
+func (c *Client) queueFor(taskType string) string {
+	return QueueFor(c.queue, taskType)
+}
+
 func (c *Client) Close() error {
 	if c == nil || c.client == nil {
 		return nil
@@ -161,7 +174,7 @@ func (c *Client) ScheduleAppointmentReminder(ctx context.Context, payload Appoin
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, asynq.ProcessAt(runAt), asynq.Queue(c.queue))
+	_, err = c.client.EnqueueContext(ctx, task, asynq.ProcessAt(runAt), asynq.Queue(c.queueFor(task.Type())))
 	return err
 }
 
@@ -175,7 +188,7 @@ func (c *Client) ScheduleTaskReminder(ctx context.Context, payload TaskReminderP
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, asynq.ProcessAt(runAt), asynq.Queue(c.queue))
+	_, err = c.client.EnqueueContext(ctx, task, asynq.ProcessAt(runAt), asynq.Queue(c.queueFor(task.Type())))
 	return err
 }
 
@@ -189,7 +202,7 @@ func (c *Client) EnqueueGenerateQuoteJob(ctx context.Context, payload GenerateQu
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, estimatorTaskOptions(c.queue)...)
+	_, err = c.client.EnqueueContext(ctx, task, estimatorTaskOptions(c.queueFor(task.Type()))...)
 	return err
 }
 
@@ -203,7 +216,7 @@ func (c *Client) EnqueueGenerateAcceptedQuotePDF(ctx context.Context, payload Ge
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queue))
+	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queueFor(task.Type())))
 	return err
 }
 
@@ -217,7 +230,7 @@ func (c *Client) EnqueueLogCall(ctx context.Context, payload LogCallPayload) err
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queue))
+	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queueFor(task.Type())))
 	return err
 }
 
@@ -231,7 +244,7 @@ func (c *Client) EnqueueSubsidyAnalyzerJob(ctx context.Context, payload SubsidyA
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queue))
+	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queueFor(task.Type())))
 	return err
 }
 
@@ -245,7 +258,7 @@ func (c *Client) EnqueuePartnerOfferSummary(ctx context.Context, payload Partner
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, offerSummaryTaskOptions(c.queue)...)
+	_, err = c.client.EnqueueContext(ctx, task, offerSummaryTaskOptions(c.queueFor(task.Type()))...)
 	return normalizeEnqueueError(err)
 }
 
@@ -259,7 +272,7 @@ func (c *Client) EnqueuePartnerOfferPDF(ctx context.Context, payload PartnerOffe
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queue))
+	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queueFor(task.Type())))
 	return normalizeEnqueueError(err)
 }
 
@@ -279,7 +292,7 @@ func (c *Client) EnqueueAgentTask(ctx context.Context, payload AgentTaskPayload)
 	}
 
 	var opts []asynq.Option
-	opts = append(opts, asynq.Queue(c.queue))
+	opts = append(opts, asynq.Queue(c.queueFor(task.Type())))
 	opts = append(opts, asynq.Timeout(leadAutomationTaskTimeout))
 	opts = append(opts, asynq.MaxRetry(leadAutomationTaskMaxRetry))
 	switch payload.Workspace {
@@ -310,7 +323,7 @@ func (c *Client) EnqueueWAAgentVoiceTranscription(ctx context.Context, payload W
 		return err
 	}
 
-	_, err = c.client.EnqueueContext(ctx, task, waAgentVoiceTaskOptions(c.queue)...)
+	_, err = c.client.EnqueueContext(ctx, task, waAgentVoiceTaskOptions(c.queueFor(task.Type()))...)
 	return normalizeEnqueueError(err)
 }
 
@@ -325,7 +338,7 @@ func (c *Client) EnqueueStaleLeadNotify(ctx context.Context, payload StaleLeadNo
 	}
 
 	_, err = c.client.EnqueueContext(ctx, task,
-		asynq.Queue(c.queue),
+		asynq.Queue(c.queueFor(task.Type())),
 		asynq.MaxRetry(2),
 		asynq.Unique(staleLeadNotifyTaskUniqueTTL),
 	)
@@ -343,7 +356,7 @@ func (c *Client) EnqueueStaleLeadReEngage(ctx context.Context, payload StaleLead
 	}
 
 	_, err = c.client.EnqueueContext(ctx, task,
-		asynq.Queue(c.queue),
+		asynq.Queue(c.queueFor(task.Type())),
 		asynq.MaxRetry(staleLeadReEngageTaskMaxRetry),
 		asynq.Timeout(staleLeadReEngageTaskTimeout),
 		asynq.Unique(staleLeadReEngageTaskUniqueTTL),
@@ -359,7 +372,7 @@ func (c *Client) EnqueueIMAPSyncAccount(ctx context.Context, payload IMAPSyncAcc
 	if err != nil {
 		return err
 	}
-	_, err = c.client.EnqueueContext(ctx, task, imapSyncAccountTaskOptions(c.queue)...)
+	_, err = c.client.EnqueueContext(ctx, task, imapSyncAccountTaskOptions(c.queueFor(task.Type()))...)
 	return normalizeEnqueueError(err)
 }
 
@@ -371,7 +384,7 @@ func (c *Client) EnqueueIMAPSyncSweep(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.client.EnqueueContext(ctx, task, imapSyncSweepTaskOptions(c.queue)...)
+	_, err = c.client.EnqueueContext(ctx, task, imapSyncSweepTaskOptions(c.queueFor(task.Type()))...)
 	return normalizeEnqueueError(err)
 }
 
@@ -453,7 +466,44 @@ func (c *Client) EnqueueApplyHumanFeedbackMemory(ctx context.Context, payload Ap
 	if err != nil {
 		return err
 	}
-	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queue))
+	_, err = c.client.EnqueueContext(ctx, task, asynq.Queue(c.queueFor(task.Type())))
+	return err
+}
+
+// EnqueueCatalogGapAnalysis schedules the gap analysis of one organization. Runs
+// are unique per organization so overlapping sweeps do not pile up work.
+func (c *Client) EnqueueCatalogGapAnalysis(ctx context.Context, payload CatalogGapAnalyzePayload) error {
+	if c == nil || c.client == nil {
+		return nil
+	}
+	task, err := NewCatalogGapAnalyzeTask(payload)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.EnqueueContext(ctx, task,
+		asynq.Queue(c.queueFor(task.Type())),
+		asynq.MaxRetry(catalogGapTaskMaxRetry),
+		asynq.Timeout(catalogGapTaskTimeout),
+		asynq.Unique(catalogGapTaskUniqueTTL),
+	)
+	return normalizeEnqueueError(err)
+}
+
+// EnqueueMaintenanceRun schedules an operator-started maintenance run. Runs are
+// not retried: the run records the failure and the operator starts a new one.
+func (c *Client) EnqueueMaintenanceRun(ctx context.Context, runID uuid.UUID) error {
+	if c == nil || c.client == nil {
+		return fmt.Errorf("scheduler client not configured")
+	}
+	task, err := NewMaintenanceRunTask(MaintenanceRunPayload{RunID: runID.String()})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.EnqueueContext(ctx, task,
+		asynq.Queue(c.queueFor(task.Type())),
+		asynq.MaxRetry(0),
+		asynq.Timeout(maintenanceRunTaskTimeout),
+	)
 	return err
 }
 
@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/circuitbreaker"
+	"portal_final_backend/platform/logger"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	orgSlotKeyPrefix = "portal:jobs:org_slots:"
+	// orgSlotLease bounds how long a crashed worker can hold a slot. It exceeds
+	// the longest task timeout.
+	orgSlotLease = 15 * time.Minute
+
+	throttledRetryMinDelay = 5 * time.Second
+	throttledRetryJitter   = 10 * time.Second
+)
+
+// errOrganizationThrottled is returned for a task whose organization already
+// uses all its slots. It is not counted as a failure and does not consume retries.
+var errOrganizationThrottled = errors.New("organization job concurrency limit reached")
+
+// acquireOrgSlotScript holds a slot per task in a sorted set scored by lease
+// expiry, so slots of crashed workers expire on their own.
+var acquireOrgSlotScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if redis.call('ZSCORE', KEYS[1], ARGV[4]) then
+	redis.call('ZADD', KEYS[1], ARGV[2], ARGV[4])
+	return 1
+end
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[3]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[4])
+redis.call('PEXPIRE', KEYS[1], ARGV[5])
+return 1
+`)
+
+// orgLimiter caps the tasks one organization runs concurrently per priority.
+type orgLimiter struct {
+	rdb   redis.UniversalClient
+	limit int
+	now   func() time.Time
+}
+
+func newOrgLimiter(rdb redis.UniversalClient, limit int) *orgLimiter {
+	return &orgLimiter{rdb: rdb, limit: limit, now: time.Now}
+}
+
+func orgSlotKey(organizationID string, p Priority) string {
+	return orgSlotKeyPrefix + string(p) + ":" + organizationID
+}
+
+func (l *orgLimiter) acquire(ctx context.Context, organizationID string, p Priority, taskID string) (bool, error) {
+	now := l.now()
+	res, err := acquireOrgSlotScript.Run(ctx, l.rdb, []string{orgSlotKey(organizationID, p)},
+		now.UnixMilli(),
+		now.Add(orgSlotLease).UnixMilli(),
+		l.limit,
+		taskID,
+		orgSlotLease.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+func (l *orgLimiter) release(organizationID string, p Priority, taskID string) {
+	_ = l.rdb.ZRem(context.Background(), orgSlotKey(organizationID, p), taskID).Err()
+}
+
+// taskOrganization extracts the organization a task runs for. Payloads use
+// either "organizationId" or "tenantId"; tasks without one are not capped.
+func taskOrganization(payload []byte) string {
+	var scope struct {
+		OrganizationID string `json:"organizationId"`
+		TenantID       string `json:"tenantId"`
+	}
+	if len(payload) == 0 || json.Unmarshal(payload, &scope) != nil {
+		return ""
+	}
+	if id := strings.TrimSpace(scope.OrganizationID); id != "" {
+		return id
+	}
+	return strings.TrimSpace(scope.TenantID)
+}
+
+// fairnessMiddleware enforces the per-organization concurrency cap. A throttled
+// task goes back to the queue with a short jittered delay so other organizations'
+// tasks are picked up in the meantime. Tasks on their last attempt are never
+// throttled because asynq would archive them instead of retrying.
+func fairnessMiddleware(limiter *orgLimiter, log *logger.Logger) asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			organizationID := taskOrganization(task.Payload())
+			taskID, ok := asynq.GetTaskID(ctx)
+			if organizationID == "" || !ok || isLastAttempt(ctx) {
+				return next.ProcessTask(ctx, task)
+			}
+
+			priority := PriorityFor(task.Type())
+			acquired, err := limiter.acquire(ctx, organizationID, priority, taskID)
+			if err != nil {
+				// Fail open: fairness must not block processing when Redis hiccups.
+				log.Warn("scheduler: failed to acquire organization slot", "type", task.Type(), "organizationId", organizationID, "error", err)
+				return next.ProcessTask(ctx, task)
+			}
+			if !acquired {
+				return errOrganizationThrottled
+			}
+			defer limiter.release(organizationID, priority, taskID)
+
+			return next.ProcessTask(ctx, task)
+		})
+	}
+}
+
+func isLastAttempt(ctx context.Context) bool {
+	retried, ok := asynq.GetRetryCount(ctx)
+	if !ok {
+		return false
+	}
+	maxRetry, ok := asynq.GetMaxRetry(ctx)
+	return ok && retried >= maxRetry
+}
+
+// isTaskFailure excludes throttling and open circuit breakers from failure
+// counts and retry budgets, so a dependency outage defers tasks such as PDF
+// generation instead of exhausting their retries.
+func isTaskFailure(err error) bool {
+	return !errors.Is(err, errOrganizationThrottled) && !errors.Is(err, circuitbreaker.ErrOpen)
+}
+
+// taskRetryDelay retries throttled tasks quickly, tasks rejected by an open
+// circuit breaker once it probes again, and everything else with the default
+// exponential backoff.
+func taskRetryDelay(n int, err error, task *asynq.Task) time.Duration {
+	if errors.Is(err, errOrganizationThrottled) {
+		return throttledRetryMinDelay + rand.N(throttledRetryJitter)
+	}
+	if retryAfter, ok := circuitbreaker.RetryAfter(err); ok {
+		return retryAfter + rand.N(throttledRetryJitter)
+	}
+	return asynq.DefaultRetryDelayFunc(n, err, task)
+}
+
+// orgConcurrencyLimit resolves the per-organization cap from the configured value
+// and the worker concurrency. The cap never exceeds the worker concurrency.
+func orgConcurrencyLimit(configured, concurrency int) int {
+	if configured <= 0 || configured > concurrency {
+		return concurrency
+	}
+	return configured
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"portal_final_backend/platform/circuitbreaker"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestQueueForRoutesByPriority(t *testing.T) {
+	cases := map[string]string{
+		TaskAppointmentReminder: "portal:critical",
+		TaskGenerateQuoteJob:    "portal",
+		TaskCatalogGapSweep:     "portal:low",
+	}
+	for taskType, want := range cases {
+		if got := QueueFor("portal", taskType); got != want {
+			t.Fatalf("QueueFor(%q) = %q, want %q", taskType, got, want)
+		}
+	}
+}
+
+func TestTaskOrganizationReadsOrganizationOrTenant(t *testing.T) {
+	cases := map[string]string{
+		`{"organizationId":"org-1","tenantId":"ten-1"}`: "org-1",
+		`{"tenantId":" ten-1 "}`:                        "ten-1",
+		`{"jobId":"1"}`:                                 "",
+		`not json`:                                      "",
+	}
+	for payload, want := range cases {
+		if got := taskOrganization([]byte(payload)); got != want {
+			t.Fatalf("taskOrganization(%s) = %q, want %q", payload, got, want)
+		}
+	}
+}
+
+func TestThrottlingIsNotAFailure(t *testing.T) {
+	if isTaskFailure(fmt.Errorf("wrapped: %w", errOrganizationThrottled)) {
+		t.Fatal("throttled task must not count as failure")
+	}
+	if !isTaskFailure(fmt.Errorf("boom")) {
+		t.Fatal("regular error must count as failure")
+	}
+}
+
+func TestOpenCircuitBreakerDefersTask(t *testing.T) {
+	err := fmt.Errorf("generate PDF: %w", &circuitbreaker.OpenError{Dependency: circuitbreaker.DependencyGotenberg, RetryAfter: 20 * time.Second})
+	if isTaskFailure(err) {
+		t.Fatal("task rejected by an open circuit breaker must not count as failure")
+	}
+	delay := taskRetryDelay(1, err, nil)
+	if delay < 20*time.Second || delay >= 20*time.Second+throttledRetryJitter {
+		t.Fatalf("expected the retry after the breaker timeout, got %s", delay)
+	}
+}
+
+func TestOrgLimiterCapsConcurrentSlots(t *testing.T) {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer redisServer.Close()
+
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	defer func() { _ = redisClient.Close() }()
+
+	ctx := context.Background()
+	limiter := newOrgLimiter(redisClient, 2)
+	for _, taskID := range []string{"a", "b", "a"} {
+		ok, err := limiter.acquire(ctx, "org-1", PriorityDefault, taskID)
+		if err != nil || !ok {
+			t.Fatalf("expected slot for task %s, got %v %v", taskID, ok, err)
+		}
+	}
+	if ok, _ := limiter.acquire(ctx, "org-1", PriorityDefault, "c"); ok {
+		t.Fatal("expected third task to be throttled")
+	}
+	if ok, _ := limiter.acquire(ctx, "org-2", PriorityDefault, "c"); !ok {
+		t.Fatal("expected other organization to get a slot")
+	}
+
+	limiter.release("org-1", PriorityDefault, "a")
+	if ok, _ := limiter.acquire(ctx, "org-1", PriorityDefault, "c"); !ok {
+		t.Fatal("expected slot after release")
+	}
+}
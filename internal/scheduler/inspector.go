@@ -0,0 +1,282 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"portal_final_backend/platform/config"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	payloadPreviewLimit = 512
+	historyDaysLimit    = 90
+)
+
+// Task states that can be listed from the dashboard.
+const (
+	JobStatePending   = "pending"
+	JobStateActive    = "active"
+	JobStateScheduled = "scheduled"
+	JobStateRetry     = "retry"
+	JobStateArchived  = "archived"
+	JobStateCompleted = "completed"
+)
+
+var (
+	// ErrJobNotFound is returned when a task does not exist in the queue.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrQueueNotFound is returned when a queue does not exist.
+	ErrQueueNotFound = errors.New("queue not found")
+	// ErrUnknownJobState is returned when listing an unsupported task state.
+	ErrUnknownJobState = errors.New("unknown job state")
+)
+
+// QueueStats is a snapshot of one queue.
+type QueueStats struct {
+	Queue          string
+	Size           int
+	Pending        int
+	Active         int
+	Scheduled      int
+	Retry          int
+	Archived       int
+	Completed      int
+	ProcessedToday int
+	FailedToday    int
+	Latency        time.Duration
+	Paused         bool
+	Timestamp      time.Time
+}
+
+// QueueDayStats is the processed and failed count of a queue on one day.
+type QueueDayStats struct {
+	Date      time.Time
+	Processed int
+	Failed    int
+}
+
+// JobInfo describes a single task. The payload is truncated to a preview.
+type JobInfo struct {
+	ID               string
+	Queue            string
+	Type             string
+	State            string
+	PayloadPreview   string
+	PayloadTruncated bool
+	MaxRetry         int
+	Retried          int
+	LastError        string
+	LastFailedAt     *time.Time
+	NextProcessAt    *time.Time
+	CompletedAt      *time.Time
+}
+
+// Inspector exposes queue and task state for operators.
+type Inspector struct {
+	inspector *asynq.Inspector
+	stats     redis.UniversalClient
+	queue     string
+}
+
+func NewInspector(cfg config.SchedulerConfig) (*Inspector, error) {
+	redisURL := cfg.GetRedisURL()
+	if redisURL == "" {
+		return nil, fmt.Errorf("redis url not configured")
+	}
+
+	opt, err := redisClientOpt(redisURL, cfg.GetRedisTLSInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	queue := cfg.GetAsynqQueueName()
+	if queue == "" {
+		queue = "default"
+	}
+
+	stats, ok := opt.MakeRedisClient().(redis.UniversalClient)
+	if !ok {
+		return nil, fmt.Errorf("unexpected redis client type")
+	}
+
+	return &Inspector{
+		inspector: asynq.NewInspector(opt),
+		stats:     stats,
+		queue:     queue,
+	}, nil
+}
+
+func (i *Inspector) Close() error {
+	if i == nil {
+		return nil
+	}
+	return errors.Join(i.inspector.Close(), i.stats.Close())
+}
+
+// Queues returns a snapshot of every known queue.
+func (i *Inspector) Queues() ([]QueueStats, error) {
+	names, err := i.inspector.Queues()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		names = []string{i.queue}
+	}
+
+	items := make([]QueueStats, 0, len(names))
+	for _, name := range names {
+		info, err := i.inspector.GetQueueInfo(name)
+		if errors.Is(err, asynq.ErrQueueNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, QueueStats{
+			Queue:          info.Queue,
+			Size:           info.Size,
+			Pending:        info.Pending,
+			Active:         info.Active,
+			Scheduled:      info.Scheduled,
+			Retry:          info.Retry,
+			Archived:       info.Archived,
+			Completed:      info.Completed,
+			ProcessedToday: info.Processed,
+			FailedToday:    info.Failed,
+			Latency:        info.Latency,
+			Paused:         info.Paused,
+			Timestamp:      info.Timestamp,
+		})
+	}
+	return items, nil
+}
+
+// History returns daily processed and failed counts of a queue, newest first.
+func (i *Inspector) History(queue string, days int) ([]QueueDayStats, error) {
+	days = min(max(days, 1), historyDaysLimit)
+	stats, err := i.inspector.History(queue, days)
+	if err != nil {
+		return nil, mapInspectorError(err)
+	}
+
+	items := make([]QueueDayStats, 0, len(stats))
+	for _, s := range stats {
+		items = append(items, QueueDayStats{Date: s.Date, Processed: s.Processed, Failed: s.Failed})
+	}
+	return items, nil
+}
+
+// ListJobs returns one page of tasks in the given state. Pages start at 1.
+func (i *Inspector) ListJobs(queue, state string, page, pageSize int) ([]JobInfo, error) {
+	opts := []asynq.ListOption{asynq.Page(max(page, 1)), asynq.PageSize(pageSize)}
+
+	var (
+		tasks []*asynq.TaskInfo
+		err   error
+	)
+	switch state {
+	case JobStatePending:
+		tasks, err = i.inspector.ListPendingTasks(queue, opts...)
+	case JobStateActive:
+		tasks, err = i.inspector.ListActiveTasks(queue, opts...)
+	case JobStateScheduled:
+		tasks, err = i.inspector.ListScheduledTasks(queue, opts...)
+	case JobStateRetry:
+		tasks, err = i.inspector.ListRetryTasks(queue, opts...)
+	case JobStateArchived:
+		tasks, err = i.inspector.ListArchivedTasks(queue, opts...)
+	case JobStateCompleted:
+		tasks, err = i.inspector.ListCompletedTasks(queue, opts...)
+	default:
+		return nil, ErrUnknownJobState
+	}
+	if err != nil {
+		return nil, mapInspectorError(err)
+	}
+
+	items := make([]JobInfo, 0, len(tasks))
+	for _, t := range tasks {
+		items = append(items, toJobInfo(t))
+	}
+	return items, nil
+}
+
+// GetJob returns a single task.
+func (i *Inspector) GetJob(queue, id string) (JobInfo, error) {
+	task, err := i.inspector.GetTaskInfo(queue, id)
+	if err != nil {
+		return JobInfo{}, mapInspectorError(err)
+	}
+	return toJobInfo(task), nil
+}
+
+// RetryJob moves a scheduled, retry or archived task to pending so it runs now.
+func (i *Inspector) RetryJob(queue, id string) error {
+	return mapInspectorError(i.inspector.RunTask(queue, id))
+}
+
+// DeleteJob removes a task that is not currently being processed.
+func (i *Inspector) DeleteJob(queue, id string) error {
+	return mapInspectorError(i.inspector.DeleteTask(queue, id))
+}
+
+// Throughput returns per task type counts recorded by the worker over the window.
+func (i *Inspector) Throughput(ctx context.Context, window time.Duration) ([]JobTypeStats, error) {
+	return readJobStats(ctx, i.stats, time.Now(), min(window, jobStatsRetention))
+}
+
+func toJobInfo(t *asynq.TaskInfo) JobInfo {
+	preview, truncated := payloadPreview(t.Payload)
+	return JobInfo{
+		ID:               t.ID,
+		Queue:            t.Queue,
+		Type:             t.Type,
+		State:            t.State.String(),
+		PayloadPreview:   preview,
+		PayloadTruncated: truncated,
+		MaxRetry:         t.MaxRetry,
+		Retried:          t.Retried,
+		LastError:        t.LastErr,
+		LastFailedAt:     timeOrNil(t.LastFailedAt),
+		NextProcessAt:    timeOrNil(t.NextProcessAt),
+		CompletedAt:      timeOrNil(t.CompletedAt),
+	}
+}
+
+// payloadPreview returns the payload as text, cut at a rune boundary.
+func payloadPreview(payload []byte) (string, bool) {
+	if len(payload) <= payloadPreviewLimit {
+		return string(payload), false
+	}
+	cut := payloadPreviewLimit
+	for cut > 0 && !utf8.RuneStart(payload[cut]) {
+		cut--
+	}
+	return string(payload[:cut]), true
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func mapInspectorError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, asynq.ErrTaskNotFound):
+		return ErrJobNotFound
+	case errors.Is(err, asynq.ErrQueueNotFound):
+		return ErrQueueNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	jobStatsKeyPrefix = "portal:jobstats:"
+	jobStatsRetention = 8 * 24 * time.Hour
+
+	jobStatsFieldProcessed = "processed"
+	jobStatsFieldFailed    = "failed"
+	jobStatsFieldDuration  = "duration_ms"
+)
+
+// JobTypeStats is the throughput of one task type over a window.
+type JobTypeStats struct {
+	Type          string
+	Processed     int64
+	Failed        int64
+	AvgDurationMs int64
+}
+
+// jobStatsKey returns the hourly bucket key for t. Buckets are hourly so the
+// dashboard can report any window up to the retention without scanning keys.
+func jobStatsKey(t time.Time) string {
+	return jobStatsKeyPrefix + t.UTC().Format("2006010215")
+}
+
+func jobStatsField(taskType, metric string) string {
+	return taskType + "|" + metric
+}
+
+// jobStatsMiddleware records processed and failed counts and the handler duration
+// per task type. Recording is best-effort and never affects the task outcome.
+func jobStatsMiddleware(rdb redis.UniversalClient) asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			start := time.Now()
+			err := next.ProcessTask(ctx, task)
+			if errors.Is(err, errOrganizationThrottled) {
+				// Throttled tasks did not run; they are counted when they do.
+				return err
+			}
+
+			key := jobStatsKey(start)
+			pipe := rdb.Pipeline()
+			pipe.HIncrBy(context.Background(), key, jobStatsField(task.Type(), jobStatsFieldProcessed), 1)
+			if err != nil {
+				pipe.HIncrBy(context.Background(), key, jobStatsField(task.Type(), jobStatsFieldFailed), 1)
+			}
+			pipe.HIncrBy(context.Background(), key, jobStatsField(task.Type(), jobStatsFieldDuration), time.Since(start).Milliseconds())
+			pipe.Expire(context.Background(), key, jobStatsRetention)
+			_, _ = pipe.Exec(context.Background())
+
+			return err
+		})
+	}
+}
+
+// readJobStats aggregates the hourly buckets covering the window ending at now.
+func readJobStats(ctx context.Context, rdb redis.UniversalClient, now time.Time, window time.Duration) ([]JobTypeStats, error) {
+	hours := int(window / time.Hour)
+	if hours < 1 {
+		hours = 1
+	}
+
+	pipe := rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, hours)
+	for i := 0; i < hours; i++ {
+		cmds = append(cmds, pipe.HGetAll(ctx, jobStatsKey(now.Add(-time.Duration(i)*time.Hour))))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	buckets := make([]map[string]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		values, err := cmd.Result()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		buckets = append(buckets, values)
+	}
+	return aggregateJobStats(buckets), nil
+}
+
+func aggregateJobStats(buckets []map[string]string) []JobTypeStats {
+	type totals struct{ processed, failed, durationMs int64 }
+	byType := make(map[string]*totals)
+
+	for _, values := range buckets {
+		for field, raw := range values {
+			taskType, metric, ok := strings.Cut(field, "|")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				continue
+			}
+			t := byType[taskType]
+			if t == nil {
+				t = &totals{}
+				byType[taskType] = t
+			}
+			switch metric {
+			case jobStatsFieldProcessed:
+				t.processed += n
+			case jobStatsFieldFailed:
+				t.failed += n
+			case jobStatsFieldDuration:
+				t.durationMs += n
+			}
+		}
+	}
+
+	stats := make([]JobTypeStats, 0, len(byType))
+	for taskType, t := range byType {
+		s := JobTypeStats{Type: taskType, Processed: t.processed, Failed: t.failed}
+		if t.processed > 0 {
+			s.AvgDurationMs = t.durationMs / t.processed
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Processed != stats[j].Processed {
+			return stats[i].Processed > stats[j].Processed
+		}
+		return stats[i].Type < stats[j].Type
+	})
+	return stats
+}
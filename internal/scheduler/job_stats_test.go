@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAggregateJobStatsSumsHourlyBuckets(t *testing.T) {
+	stats := aggregateJobStats([]map[string]string{
+		{
+			jobStatsField(TaskGenerateQuoteJob, jobStatsFieldProcessed): "3",
+			jobStatsField(TaskGenerateQuoteJob, jobStatsFieldFailed):    "1",
+			jobStatsField(TaskGenerateQuoteJob, jobStatsFieldDuration):  "900",
+			"malformed": "7",
+		},
+		{
+			jobStatsField(TaskGenerateQuoteJob, jobStatsFieldProcessed):    "1",
+			jobStatsField(TaskGenerateQuoteJob, jobStatsFieldDuration):     "300",
+			jobStatsField(TaskAppointmentReminder, jobStatsFieldProcessed): "2",
+		},
+	})
+
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 task types, got %#v", stats)
+	}
+	quote := stats[0]
+	if quote.Type != TaskGenerateQuoteJob || quote.Processed != 4 || quote.Failed != 1 || quote.AvgDurationMs != 300 {
+		t.Fatalf("unexpected quote job stats: %#v", quote)
+	}
+	if stats[1].Type != TaskAppointmentReminder || stats[1].Processed != 2 {
+		t.Fatalf("unexpected reminder stats: %#v", stats[1])
+	}
+}
+
+func TestPayloadPreviewCutsAtRuneBoundary(t *testing.T) {
+	payload := []byte(strings.Repeat("a", payloadPreviewLimit-1) + "é")
+	preview, truncated := payloadPreview(payload)
+	if !truncated {
+		t.Fatal("expected payload to be truncated")
+	}
+	if len(preview) != payloadPreviewLimit-1 {
+		t.Fatalf("expected preview to stop before the split rune, got %d bytes", len(preview))
+	}
+
+	if preview, truncated := payloadPreview([]byte(`{"id":"1"}`)); truncated || preview != `{"id":"1"}` {
+		t.Fatalf("unexpected preview of short payload: %q %v", preview, truncated)
+	}
+}
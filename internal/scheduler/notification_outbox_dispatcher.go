@@ -86,7 +86,7 @@ func (d *NotificationOutboxDispatcher) Run(ctx context.Context) {
 				continue
 			}
 
-			_, err = d.client.EnqueueContext(ctx, task, asynq.ProcessAt(rec.RunAt), asynq.Queue(d.queue))
+			_, err = d.client.EnqueueContext(ctx, task, asynq.ProcessAt(rec.RunAt), asynq.Queue(QueueFor(d.queue, task.Type())))
 			if err != nil {
 				msg := err.Error()
 				_ = d.repo.MarkPending(ctx, rec.ID, &msg)
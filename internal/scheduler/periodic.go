@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/logger"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	periodicTaskMaxRetry = 1
+	// periodicStartupDelay keeps the first runs away from the connection churn
+	// right after startup.
+	periodicStartupDelay = 30 * time.Second
+)
+
+type periodicEntry struct {
+	taskType string
+	interval time.Duration
+}
+
+// PeriodicScheduler enqueues maintenance tasks on a fixed interval. Tasks are
+// unique per interval, so running several scheduler instances does not multiply
+// the work; the worker processes them like any other task.
+type PeriodicScheduler struct {
+	scheduler *asynq.Scheduler
+	client    *asynq.Client
+	queue     string
+	entries   []periodicEntry
+	log       *logger.Logger
+}
+
+func NewPeriodicScheduler(cfg config.SchedulerConfig, log *logger.Logger) (*PeriodicScheduler, error) {
+	redisURL := cfg.GetRedisURL()
+	if redisURL == "" {
+		return nil, fmt.Errorf("redis url not configured")
+	}
+
+	opt, err := redisClientOpt(redisURL, cfg.GetRedisTLSInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	queue := cfg.GetAsynqQueueName()
+	if queue == "" {
+		queue = "default"
+	}
+
+	return &PeriodicScheduler{
+		scheduler: asynq.NewScheduler(opt, &asynq.SchedulerOpts{
+			PostEnqueueFunc: func(info *asynq.TaskInfo, err error) {
+				if err != nil && normalizeEnqueueError(err) != nil {
+					log.Warn("periodic task enqueue failed", "error", err)
+				}
+			},
+		}),
+		client: asynq.NewClient(opt),
+		queue:  queue,
+		log:    log,
+	}, nil
+}
+
+// Register schedules taskType every interval. Call before Run.
+func (s *PeriodicScheduler) Register(taskType string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("periodic task %s: interval must be positive", taskType)
+	}
+
+	task := asynq.NewTask(taskType, nil)
+	if _, err := s.scheduler.Register("@every "+interval.String(), task, s.taskOptions(taskType, interval)...); err != nil {
+		return err
+	}
+	s.entries = append(s.entries, periodicEntry{taskType: taskType, interval: interval})
+	return nil
+}
+
+func (s *PeriodicScheduler) taskOptions(taskType string, interval time.Duration) []asynq.Option {
+	return []asynq.Option{
+		asynq.Queue(QueueFor(s.queue, taskType)),
+		asynq.MaxRetry(periodicTaskMaxRetry),
+		asynq.Unique(interval),
+	}
+}
+
+// Run enqueues every registered task once shortly after startup, then keeps
+// scheduling them until ctx is cancelled.
+func (s *PeriodicScheduler) Run(ctx context.Context) {
+	for _, entry := range s.entries {
+		opts := append(s.taskOptions(entry.taskType, entry.interval), asynq.ProcessIn(periodicStartupDelay))
+		if _, err := s.client.EnqueueContext(ctx, asynq.NewTask(entry.taskType, nil), opts...); normalizeEnqueueError(err) != nil {
+			s.log.Warn("periodic task startup enqueue failed", "type", entry.taskType, "error", err)
+		}
+	}
+
+	if err := s.scheduler.Start(); err != nil {
+		s.log.Error("periodic scheduler failed to start", "error", err)
+		return
+	}
+
+	<-ctx.Done()
+	s.scheduler.Shutdown()
+	_ = s.client.Close()
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+// Priority selects the queue a task is enqueued on. The worker polls the queues
+// with weighted (not strict) priority, so low priority work is slowed down under
+// load but never starved.
+type Priority string
+
+const (
+	PriorityCritical Priority = "critical"
+	PriorityDefault  Priority = "default"
+	PriorityLow      Priority = "low"
+)
+
+const (
+	criticalQueueWeight = 6
+	defaultQueueWeight  = 3
+	lowQueueWeight      = 1
+)
+
+// taskPriorities lists the task types that do not run at default priority.
+// User-facing, time-sensitive work is critical; sweeps and housekeeping are low.
+var taskPriorities = map[string]Priority{
+	TaskAppointmentReminder:       PriorityCritical,
+	TaskTaskReminder:              PriorityCritical,
+	TaskNotificationOutboxDue:     PriorityCritical,
+	TaskWAAgentVoiceTranscription: PriorityCritical,
+	TaskIMAPSyncSweep:             PriorityLow,
+	TaskApplyHumanFeedbackMemory:  PriorityLow,
+	TaskStaleLeadNotify:           PriorityLow,
+	TaskStaleLeadReEngage:         PriorityLow,
+	TaskCatalogGapSweep:           PriorityLow,
+	TaskCatalogGapAnalyze:         PriorityLow,
+	TaskAIQuoteJobCleanup:         PriorityLow,
+	TaskAIQuoteJobRecovery:        PriorityLow,
+	TaskFileOrphanCleanup:         PriorityLow,
+	TaskUploadExpirySweep:         PriorityLow,
+	TaskQuoteInstallmentDueSweep:  PriorityLow,
+	TaskAnalyticsRefresh:          PriorityLow,
+	TaskRetentionApply:            PriorityLow,
+	TaskAuditExportRun:            PriorityLow,
+	TaskEnergyLabelRefresh:        PriorityLow,
+	TaskWarrantyExpirySweep:       PriorityLow,
+	TaskPortalDomainMaintenance:   PriorityLow,
+	TaskMaintenanceRun:            PriorityLow,
+}
+
+// PriorityFor returns the priority of a task type.
+func PriorityFor(taskType string) Priority {
+	if p, ok := taskPriorities[taskType]; ok {
+		return p
+	}
+	return PriorityDefault
+}
+
+// QueueName returns the queue for a priority. Default priority keeps the base
+// queue name so tasks enqueued before priorities existed are still processed.
+func QueueName(base string, p Priority) string {
+	if p == PriorityDefault || p == "" {
+		return base
+	}
+	return base + ":" + string(p)
+}
+
+// QueueFor returns the queue a task type is enqueued on.
+func QueueFor(base, taskType string) string {
+	return QueueName(base, PriorityFor(taskType))
+}
+
+// queueWeights returns the weighted queue configuration for the worker.
+func queueWeights(base string) map[string]int {
+	return map[string]int{
+		QueueName(base, PriorityCritical): criticalQueueWeight,
+		QueueName(base, PriorityDefault):  defaultQueueWeight,
+		QueueName(base, PriorityLow):      lowQueueWeight,
+	}
+}
@@ -30,6 +30,29 @@ const TaskStaleLeadNotify = "leads.stale.notify"
 const TaskStaleLeadReEngage = "leads.stale.reengage"
 const TaskAgentRun = "agent:run"
 
+// Maintenance tasks are enqueued by the periodic scheduler.
+const TaskCatalogGapSweep = "maintenance.catalog_gap.sweep"
+const TaskCatalogGapAnalyze = "maintenance.catalog_gap.analyze"
+const TaskAIQuoteJobCleanup = "maintenance.ai_quote_jobs.cleanup"
+const TaskAIQuoteJobRecovery = "maintenance.ai_quote_jobs.recovery"
+const TaskFileOrphanCleanup = "maintenance.files.orphan_cleanup"
+const TaskQuoteInstallmentDueSweep = "maintenance.quote_installments.due_sweep"
+const TaskQuoteAutoSendSweep = "maintenance.quote_auto_send.sweep"
+const TaskAgentApprovalExpirySweep = "maintenance.agent_approvals.expiry_sweep"
+const TaskLeadConnectorPollSweep = "maintenance.lead_connectors.poll_sweep"
+const TaskCatalogPriceFeedSweep = "maintenance.catalog.price_feed_sweep"
+const TaskAnalyticsRefresh = "maintenance.analytics.refresh"
+const TaskRetentionApply = "maintenance.retention.apply"
+const TaskAuditExportRun = "maintenance.audit_export.run"
+const TaskUploadExpirySweep = "maintenance.uploads.expiry_sweep"
+const TaskAttachmentRenditionSweep = "maintenance.attachments.rendition_sweep"
+const TaskEnergyLabelRefresh = "maintenance.energy_labels.refresh"
+const TaskWarrantyExpirySweep = "maintenance.warranties.expiry_sweep"
+const TaskPortalDomainMaintenance = "maintenance.portal_domains.check"
+
+// TaskMaintenanceRun executes a maintenance job started on demand by an operator.
+const TaskMaintenanceRun = "maintenance.runs.execute"
+
 // AgentTaskPayload is the unified payload for all agent runs.
 type AgentTaskPayload struct {
 	Workspace     string `json:"workspace"`
@@ -419,3 +442,49 @@ func ParseStaleLeadNotifyPayload(task *asynq.Task) (StaleLeadNotifyPayload, erro
 	}
 	return payload, nil
 }
+
+// CatalogGapAnalyzePayload runs the catalog gap analysis for one organization.
+type CatalogGapAnalyzePayload struct {
+	OrganizationID string `json:"organizationId"`
+	Threshold      int    `json:"threshold"`
+	LookbackDays   int    `json:"lookbackDays"`
+	MaxDrafts      int    `json:"maxDrafts"`
+}
+
+func NewCatalogGapAnalyzeTask(payload CatalogGapAnalyzePayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TaskCatalogGapAnalyze, data), nil
+}
+
+func ParseCatalogGapAnalyzePayload(task *asynq.Task) (CatalogGapAnalyzePayload, error) {
+	var payload CatalogGapAnalyzePayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return CatalogGapAnalyzePayload{}, err
+	}
+	return payload, nil
+}
+
+// MaintenanceRunPayload identifies the maintenance run to execute. The job and
+// its parameters are stored with the run.
+type MaintenanceRunPayload struct {
+	RunID string `json:"runId"`
+}
+
+func NewMaintenanceRunTask(payload MaintenanceRunPayload) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TaskMaintenanceRun, data), nil
+}
+
+func ParseMaintenanceRunPayload(task *asynq.Task) (MaintenanceRunPayload, error) {
+	var payload MaintenanceRunPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return MaintenanceRunPayload{}, err
+	}
+	return payload, nil
+}
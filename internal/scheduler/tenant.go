@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// TenantContextBinder attaches organization-scoped state, such as the data
+// schema of the organization, to the context a task runs with.
+type TenantContextBinder func(ctx context.Context, organizationID uuid.UUID) (context.Context, error)
+
+// SetTenantContextBinder binds the context of every task that carries an
+// organization. It must be called before Run.
+func (w *Worker) SetTenantContextBinder(binder TenantContextBinder) {
+	if binder == nil {
+		return
+	}
+	w.mux.Use(tenantMiddleware(binder, w.log))
+}
+
+// tenantMiddleware fails closed: a task whose organization cannot be resolved
+// is retried rather than run against the wrong data.
+func tenantMiddleware(binder TenantContextBinder, log *logger.Logger) asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			organizationID, err := uuid.Parse(taskOrganization(task.Payload()))
+			if err != nil {
+				return next.ProcessTask(ctx, task)
+			}
+			bound, err := binder(ctx, organizationID)
+			if err != nil {
+				log.Warn("scheduler: failed to bind organization context", "type", task.Type(), "organizationId", organizationID, "error", err)
+				return err
+			}
+			return next.ProcessTask(bound, task)
+		})
+	}
+}
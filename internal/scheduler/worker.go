@@ -12,18 +12,22 @@ import (
 	"portal_final_backend/internal/events"
 	leadrepo "portal_final_backend/internal/leads/repository"
 	"portal_final_backend/platform/ai/embeddings"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/db"
 	"portal_final_backend/platform/logger"
 	"portal_final_backend/platform/qdrant"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 type Worker struct {
 	server          *asynq.Server
 	mux             *asynq.ServeMux
+	stats           redis.UniversalClient
 	repo            *repository.Repository
 	leads           *leadrepo.Repository
 	bus             events.Bus
@@ -40,6 +44,8 @@ type Worker struct {
 	subsidyAnalyzer SubsidyAnalyzerProcessor
 	staleNotifier   StaleLeadNotifyProcessor
 	staleReEngage   StaleLeadReEngageProcessor
+	catalogGaps     CatalogGapProcessor
+	maintenanceRuns MaintenanceRunProcessor
 	embed           *embeddings.Client
 	qdrant          *qdrant.Client
 }
@@ -96,6 +102,14 @@ type StaleLeadReEngageProcessor interface {
 	ProcessReEngagement(ctx context.Context, orgID, leadID, serviceID uuid.UUID, staleReason string) error
 }
 
+type CatalogGapProcessor interface {
+	ProcessCatalogGapAnalysis(ctx context.Context, payload CatalogGapAnalyzePayload) error
+}
+
+type MaintenanceRunProcessor interface {
+	ExecuteMaintenanceRun(ctx context.Context, runID uuid.UUID) error
+}
+
 func NewWorker(cfg config.SchedulerConfig, pool *pgxpool.Pool, bus events.Bus, log *logger.Logger) (*Worker, error) {
 	redisURL := cfg.GetRedisURL()
 	if redisURL == "" {
@@ -118,10 +132,10 @@ func NewWorker(cfg config.SchedulerConfig, pool *pgxpool.Pool, bus events.Bus, l
 	}
 
 	server := asynq.NewServer(opt, asynq.Config{
-		Concurrency: concurrency,
-		Queues: map[string]int{
-			queue: 1,
-		},
+		Concurrency:    concurrency,
+		Queues:         queueWeights(queue),
+		IsFailure:      isTaskFailure,
+		RetryDelayFunc: taskRetryDelay,
 	})
 
 	mux := asynq.NewServeMux()
@@ -134,6 +148,17 @@ func NewWorker(cfg config.SchedulerConfig, pool *pgxpool.Pool, bus events.Bus, l
 		log:    log,
 	}
 
+	if stats, ok := opt.MakeRedisClient().(redis.UniversalClient); ok {
+		w.stats = stats
+		mux.Use(jobStatsMiddleware(stats))
+
+		// Without a cap below the worker concurrency one organization could
+		// still occupy every slot, so the limiter only runs when it matters.
+		if limit := orgConcurrencyLimit(cfg.GetAsynqOrgConcurrency(), concurrency); limit < concurrency {
+			mux.Use(fairnessMiddleware(newOrgLimiter(stats, limit), log))
+		}
+	}
+
 	if embeddingCfg, ok := any(cfg).(interface {
 		IsEmbeddingEnabled() bool
 		GetEmbeddingAPIURL() string
@@ -179,6 +204,8 @@ func NewWorker(cfg config.SchedulerConfig, pool *pgxpool.Pool, bus events.Bus, l
 	mux.HandleFunc(TaskApplyHumanFeedbackMemory, w.handleApplyHumanFeedbackMemory)
 	mux.HandleFunc(TaskStaleLeadNotify, w.handleStaleLeadNotify)
 	mux.HandleFunc(TaskStaleLeadReEngage, w.handleStaleLeadReEngage)
+	mux.HandleFunc(TaskCatalogGapAnalyze, w.handleCatalogGapAnalyze)
+	mux.HandleFunc(TaskMaintenanceRun, w.handleMaintenanceRun)
 
 	return w, nil
 }
@@ -231,6 +258,59 @@ func (w *Worker) SetStaleLeadReEngageProcessor(processor StaleLeadReEngageProces
 	w.staleReEngage = processor
 }
 
+func (w *Worker) SetCatalogGapProcessor(processor CatalogGapProcessor) {
+	w.catalogGaps = processor
+}
+
+func (w *Worker) SetMaintenanceRunProcessor(processor MaintenanceRunProcessor) {
+	w.maintenanceRuns = processor
+}
+
+// HandleMaintenance registers a periodic maintenance task. The task carries no
+// payload; fn runs the whole job. Register before Run.
+func (w *Worker) HandleMaintenance(taskType string, fn func(ctx context.Context) error) {
+	w.mux.HandleFunc(taskType, func(ctx context.Context, _ *asynq.Task) error {
+		return fn(ctx)
+	})
+}
+
+// HandleTenantMaintenance registers a periodic maintenance task that scans
+// tenant data of all organizations: fn runs once for the shared schema and once
+// for every dedicated data schema. Register before Run.
+func (w *Worker) HandleTenantMaintenance(taskType string, fn func(ctx context.Context) error) {
+	w.HandleMaintenance(taskType, func(ctx context.Context) error {
+		return db.ForEachSchema(ctx, fn)
+	})
+}
+
+func (w *Worker) handleCatalogGapAnalyze(ctx context.Context, task *asynq.Task) error {
+	if w.catalogGaps == nil {
+		return nil
+	}
+
+	payload, err := ParseCatalogGapAnalyzePayload(task)
+	if err != nil {
+		return err
+	}
+	return w.catalogGaps.ProcessCatalogGapAnalysis(ctx, payload)
+}
+
+func (w *Worker) handleMaintenanceRun(ctx context.Context, task *asynq.Task) error {
+	if w.maintenanceRuns == nil {
+		return nil
+	}
+
+	payload, err := ParseMaintenanceRunPayload(task)
+	if err != nil {
+		return err
+	}
+	runID, err := uuid.Parse(payload.RunID)
+	if err != nil {
+		return err
+	}
+	return w.maintenanceRuns.ExecuteMaintenanceRun(ctx, runID)
+}
+
 func (w *Worker) handleNotificationOutboxDue(ctx context.Context, task *asynq.Task) error {
 	if w.bus == nil {
 		return nil
@@ -313,7 +393,8 @@ func (w *Worker) handleApplyHumanFeedbackMemory(ctx context.Context, task *asynq
 	}
 
 	text := buildHumanFeedbackMemoryDocument(feedback)
-	vector, err := w.embed.Embed(ctx, text)
+	embedCtx := llmrouter.WithSource(llmrouter.WithOrganizationID(ctx, tenantID), "human-feedback-memory")
+	vector, err := w.embed.Embed(embedCtx, text)
 	if err != nil {
 		return err
 	}
@@ -447,6 +528,9 @@ func (w *Worker) Run(ctx context.Context) {
 	go func() {
 		<-ctx.Done()
 		w.server.Shutdown()
+		if w.stats != nil {
+			_ = w.stats.Close()
+		}
 	}()
 
 	if err := w.server.Run(w.mux); err != nil {
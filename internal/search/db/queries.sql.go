@@ -92,6 +92,7 @@ matching_leads AS (
 			)
 			+ COALESCE(ln.notes_rank * 0.30, 0)
 			+ COALESCE(lsn.service_note_rank * 0.25, 0)
+			+ ts_rank(to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(l.custom_fields))), sq.q_all) * 0.50
 		) AS lead_score
 	FROM RAC_leads l
 	JOIN scoped_leads sl ON sl.lead_id = l.id
@@ -169,6 +170,7 @@ matching_leads AS (
 		) @@ sq.q_all
 		OR ln.notes_rank IS NOT NULL
 		OR lsn.service_note_rank IS NOT NULL
+		OR to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(l.custom_fields))) @@ sq.q_all
 ),
 related_partners AS (
 	SELECT
@@ -201,6 +203,7 @@ results AS (
 			WHEN to_tsvector('simple', rac_immutable_unaccent(coalesce(l.consumer_email, ''))) @@ sq.q_all THEN 'email'
 			WHEN to_tsvector('simple', rac_immutable_unaccent(coalesce(l.consumer_phone, ''))) @@ sq.q_all THEN 'phone'
 			WHEN to_tsvector('dutch', rac_immutable_unaccent(coalesce(l.address_city, ''))) @@ sq.q_all THEN 'city'
+			WHEN to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(l.custom_fields))) @@ sq.q_all THEN 'custom_field'
 			ELSE 'address'
 		END AS matched_field,
 		ml.lead_score AS score,
@@ -236,6 +239,7 @@ results AS (
 			) @@ sq.q_all THEN 'lead'
 			WHEN to_tsvector('simple', rac_immutable_unaccent(coalesce(q.quote_number, ''))) @@ sq.q_all THEN 'quote_number'
 			WHEN to_tsvector('dutch', rac_immutable_unaccent(coalesce(q.notes, ''))) @@ sq.q_all THEN 'notes'
+			WHEN to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(q.custom_fields))) @@ sq.q_all THEN 'custom_field'
 			ELSE 'content'
 		END AS matched_field,
 		(
@@ -250,6 +254,7 @@ results AS (
 			)
 			ELSE 0
 			END + COALESCE(ml.lead_score * 0.20, 0)
+			+ ts_rank(to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(q.custom_fields))), sq.q_all) * 0.50
 		) AS score,
 		q.created_at
 	FROM RAC_quotes q
@@ -260,7 +265,7 @@ results AS (
 		AND ((
 			setweight(to_tsvector('simple', rac_immutable_unaccent(coalesce(q.quote_number, ''))), 'A') ||
 			setweight(to_tsvector('dutch', rac_immutable_unaccent(coalesce(q.notes, ''))), 'D')
-		) @@ sq.q_all OR ml.lead_id IS NOT NULL)
+		) @@ sq.q_all OR ml.lead_id IS NOT NULL OR to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(q.custom_fields))) @@ sq.q_all)
 
 	UNION ALL
 
@@ -360,6 +365,7 @@ results AS (
 	LEFT JOIN matching_leads ml ON ml.lead_id = a.lead_id
 	CROSS JOIN search_query sq
 	WHERE a.organization_id = $4
+		AND ($6::uuid IS NULL OR a.user_id = $6::uuid)
 		AND ((
 			setweight(to_tsvector('simple', rac_immutable_unaccent(coalesce(a.title, ''))), 'A') ||
 			setweight(to_tsvector('dutch', rac_immutable_unaccent(coalesce(a.description, ''))), 'D') ||
@@ -425,19 +431,30 @@ results AS (
 			rac_immutable_unaccent(coalesce(st.name, '')) ILIKE sq.q_text_prefix
 			OR rac_immutable_unaccent(coalesce(st.slug, '')) ILIKE sq.q_text_prefix
 		)
+),
+ranked AS (
+	SELECT
+		results.*,
+		ROW_NUMBER() OVER (PARTITION BY type ORDER BY score DESC, created_at DESC) AS type_rank,
+		COUNT(*) OVER () AS total,
+		COUNT(*) OVER (PARTITION BY type) AS type_total
+	FROM results
+	WHERE ($1::text[] IS NULL OR type = ANY($1::text[]))
 )
-SELECT id, type::text, title::text, subtitle::text, preview::text, status::text, link_id::text, matched_field::text, score::real AS score, created_at, COUNT(*) OVER() AS total
-FROM results
-WHERE ($1::text[] IS NULL OR type = ANY($1::text[]))
+SELECT id, type::text, title::text, subtitle::text, preview::text, status::text, link_id::text, matched_field::text, score::real AS score, created_at, total, type_total
+FROM ranked
+WHERE $5::int IS NULL OR type_rank <= $5::int
 ORDER BY score DESC, created_at DESC
 LIMIT $2
 `
 
 type GlobalSearchParams struct {
-	Types      []string    `json:"types"`
-	LimitCount int32       `json:"limit_count"`
-	QueryText  string      `json:"query_text"`
-	OrgID      pgtype.UUID `json:"org_id"`
+	Types        []string    `json:"types"`
+	LimitCount   int32       `json:"limit_count"`
+	QueryText    string      `json:"query_text"`
+	OrgID        pgtype.UUID `json:"org_id"`
+	PerTypeLimit pgtype.Int4 `json:"per_type_limit"`
+	ViewerUserID pgtype.UUID `json:"viewer_user_id"`
 }
 
 type GlobalSearchRow struct {
@@ -452,6 +469,7 @@ type GlobalSearchRow struct {
 	Score        float32            `json:"score"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	Total        int64              `json:"total"`
+	TypeTotal    int64              `json:"type_total"`
 }
 
 func (q *Queries) GlobalSearch(ctx context.Context, arg GlobalSearchParams) ([]GlobalSearchRow, error) {
@@ -460,6 +478,8 @@ func (q *Queries) GlobalSearch(ctx context.Context, arg GlobalSearchParams) ([]G
 		arg.LimitCount,
 		arg.QueryText,
 		arg.OrgID,
+		arg.PerTypeLimit,
+		arg.ViewerUserID,
 	)
 	if err != nil {
 		return nil, err
@@ -480,6 +500,7 @@ func (q *Queries) GlobalSearch(ctx context.Context, arg GlobalSearchParams) ([]G
 			&i.Score,
 			&i.CreatedAt,
 			&i.Total,
+			&i.TypeTotal,
 		); err != nil {
 			return nil, err
 		}
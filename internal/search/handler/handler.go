@@ -27,6 +27,7 @@ func New(svc *service.Service, val *validator.Validator) *Handler {
 
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("", h.GlobalSearch)
+	rg.GET("/global", h.CommandPalette)
 }
 
 func (h *Handler) GlobalSearch(c *gin.Context) {
@@ -60,3 +61,33 @@ func (h *Handler) GlobalSearch(c *gin.Context) {
 
 	httpkit.OK(c, result)
 }
+
+// CommandPalette returns mixed-type results capped per type, for the frontend
+// command palette.
+func (h *Handler) CommandPalette(c *gin.Context) {
+	var req transport.GlobalSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, err.Error())
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GlobalSearchGrouped(c.Request.Context(), tenantID, identity.UserID(), req, identity.HasRole("admin"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
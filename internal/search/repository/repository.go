@@ -37,6 +37,7 @@ type SearchResult struct {
 	Score        float32
 	CreatedAt    time.Time
 	Total        int64
+	TypeTotal    int64
 }
 
 func (r *Repository) GlobalSearch(ctx context.Context, orgID uuid.UUID, query string, limit int, types []string) ([]SearchResult, error) {
@@ -45,12 +46,50 @@ func (r *Repository) GlobalSearch(ctx context.Context, orgID uuid.UUID, query st
 		typesArg = nil
 	}
 
-	rows, err := r.queries.GlobalSearch(ctx, searchdb.GlobalSearchParams{
+	return r.search(ctx, searchdb.GlobalSearchParams{
 		Types:      typesArg,
 		LimitCount: int32(limit),
 		QueryText:  query,
 		OrgID:      toPgUUID(orgID),
 	})
+}
+
+// GroupedSearchParams configures a search that returns at most PerTypeLimit
+// results of each type. ViewerUserID, when set, restricts appointments to the
+// ones of that user.
+type GroupedSearchParams struct {
+	OrgID        uuid.UUID
+	Query        string
+	Types        []string
+	PerTypeLimit int
+	Limit        int
+	ViewerUserID *uuid.UUID
+}
+
+// GroupedSearch runs the global search capped per type. TypeTotal of each result
+// holds the number of matches of its type before the cap.
+func (r *Repository) GroupedSearch(ctx context.Context, params GroupedSearchParams) ([]SearchResult, error) {
+	var typesArg []string
+	if len(params.Types) > 0 {
+		typesArg = params.Types
+	}
+	viewer := pgtype.UUID{}
+	if params.ViewerUserID != nil {
+		viewer = toPgUUID(*params.ViewerUserID)
+	}
+
+	return r.search(ctx, searchdb.GlobalSearchParams{
+		Types:        typesArg,
+		LimitCount:   int32(params.Limit),
+		QueryText:    params.Query,
+		OrgID:        toPgUUID(params.OrgID),
+		PerTypeLimit: pgtype.Int4{Int32: int32(params.PerTypeLimit), Valid: true},
+		ViewerUserID: viewer,
+	})
+}
+
+func (r *Repository) search(ctx context.Context, params searchdb.GlobalSearchParams) ([]SearchResult, error) {
+	rows, err := r.queries.GlobalSearch(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("fts global search query failed: %w", err)
 	}
@@ -69,6 +108,7 @@ func (r *Repository) GlobalSearch(ctx context.Context, orgID uuid.UUID, query st
 			Score:        row.Score,
 			CreatedAt:    row.CreatedAt.Time,
 			Total:        row.Total,
+			TypeTotal:    row.TypeTotal,
 		})
 	}
 
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
@@ -20,6 +21,14 @@ var allowedSearchTypes = map[string]struct{}{
 	"service_type":    {},
 }
 
+// searchTypeOrder is the order of the type summaries of a global search.
+var searchTypeOrder = []string{"lead", "quote", "partner", "appointment", "catalog_product", "service_type"}
+
+const (
+	defaultPerTypeLimit = 5
+	maxPerTypeLimit     = 20
+)
+
 type Service struct {
 	repo *repository.Repository
 }
@@ -68,23 +77,149 @@ func (s *Service) GlobalSearch(ctx context.Context, orgID uuid.UUID, req transpo
 
 	items := make([]transport.SearchResultItem, len(results))
 	for i, r := range results {
-		items[i] = transport.SearchResultItem{
-			ID:           r.ID.String(),
-			Type:         r.Type,
-			Title:        r.Title,
-			Subtitle:     r.Subtitle,
-			Preview:      r.Preview,
-			Status:       r.Status,
-			Link:         buildFrontendLink(r.Type, r.LinkID),
-			Score:        float64(r.Score),
-			MatchedField: r.MatchedField,
-			CreatedAt:    r.CreatedAt,
-		}
+		items[i] = toResultItem(r)
 	}
 
 	return &transport.SearchResponse{Items: items, Total: total}, nil
 }
 
+// GlobalSearchGrouped searches all permitted types at once for the command
+// palette, returning at most the requested number of results per type. Non-admin
+// users only find their own appointments and no service types.
+func (s *Service) GlobalSearchGrouped(ctx context.Context, orgID, userID uuid.UUID, req transport.GlobalSearchRequest, isAdmin bool) (*transport.GlobalSearchResponse, error) {
+	q := strings.TrimSpace(req.Query)
+	if q == "" {
+		return &transport.GlobalSearchResponse{Items: []transport.SearchResultItem{}, Groups: []transport.SearchTypeSummary{}}, nil
+	}
+
+	types, err := parseSearchTypes(req.Types)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		types = searchTypeOrder
+	}
+	if !isAdmin {
+		types = restrictTypesForNonAdmin(types)
+	}
+	if len(types) == 0 {
+		return &transport.GlobalSearchResponse{Items: []transport.SearchResultItem{}, Groups: []transport.SearchTypeSummary{}}, nil
+	}
+
+	defaultLimit := req.Limit
+	if defaultLimit <= 0 {
+		defaultLimit = defaultPerTypeLimit
+	}
+	limits, err := parseTypeLimits(req.Limits, types, defaultLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLimit, totalLimit := 0, 0
+	for _, limit := range limits {
+		maxLimit = max(maxLimit, limit)
+		totalLimit += limit
+	}
+
+	var viewer *uuid.UUID
+	if !isAdmin {
+		viewer = &userID
+	}
+	results, err := s.repo.GroupedSearch(ctx, repository.GroupedSearchParams{
+		OrgID:        orgID,
+		Query:        q,
+		Types:        types,
+		PerTypeLimit: maxLimit,
+		Limit:        totalLimit,
+		ViewerUserID: viewer,
+	})
+	if err != nil {
+		appErr := apperr.Internal("search failed").WithOp("search.GlobalSearchGrouped").WithDetails(err.Error())
+		appErr.Err = err
+		return nil, appErr
+	}
+
+	items, groups := groupResults(results, types, limits)
+	return &transport.GlobalSearchResponse{Items: items, Groups: groups}, nil
+}
+
+// groupResults keeps the first limits[type] results of each type, preserving the
+// relevance order, and summarizes every searched type.
+func groupResults(results []repository.SearchResult, types []string, limits map[string]int) ([]transport.SearchResultItem, []transport.SearchTypeSummary) {
+	returned := make(map[string]int, len(types))
+	totals := make(map[string]int, len(types))
+	items := make([]transport.SearchResultItem, 0, len(results))
+	for _, r := range results {
+		totals[r.Type] = int(r.TypeTotal)
+		if returned[r.Type] >= limits[r.Type] {
+			continue
+		}
+		returned[r.Type]++
+		items = append(items, toResultItem(r))
+	}
+
+	groups := make([]transport.SearchTypeSummary, 0, len(types))
+	for _, t := range searchTypeOrder {
+		if _, ok := limits[t]; !ok {
+			continue
+		}
+		groups = append(groups, transport.SearchTypeSummary{
+			Type:     t,
+			Total:    totals[t],
+			Returned: returned[t],
+			HasMore:  totals[t] > returned[t],
+		})
+	}
+	return items, groups
+}
+
+// parseTypeLimits resolves the result limit of each searched type from a list
+// such as "lead:8,quote:3". Types without an override get defaultLimit.
+func parseTypeLimits(raw string, types []string, defaultLimit int) (map[string]int, error) {
+	limits := make(map[string]int, len(types))
+	for _, t := range types {
+		limits[t] = defaultLimit
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		t, value, ok := strings.Cut(part, ":")
+		t = strings.TrimSpace(t)
+		if !ok {
+			return nil, apperr.BadRequest("invalid search limits").WithDetails("expected type:limit, got: " + part)
+		}
+		if _, allowed := allowedSearchTypes[t]; !allowed {
+			return nil, apperr.BadRequest("invalid search type").WithDetails("unsupported type: " + t)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 1 || limit > maxPerTypeLimit {
+			return nil, apperr.BadRequest("invalid search limits").WithDetails("limit of " + t + " must be between 1 and " + strconv.Itoa(maxPerTypeLimit))
+		}
+		if _, searched := limits[t]; searched {
+			limits[t] = limit
+		}
+	}
+	return limits, nil
+}
+
+func toResultItem(r repository.SearchResult) transport.SearchResultItem {
+	return transport.SearchResultItem{
+		ID:           r.ID.String(),
+		Type:         r.Type,
+		Title:        r.Title,
+		Subtitle:     r.Subtitle,
+		Preview:      r.Preview,
+		Status:       r.Status,
+		Link:         buildFrontendLink(r.Type, r.LinkID),
+		Score:        float64(r.Score),
+		MatchedField: r.MatchedField,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
 func restrictTypesForNonAdmin(types []string) []string {
 	if len(types) == 0 {
 		return []string{"lead", "quote", "partner", "appointment", "catalog_product"}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"portal_final_backend/internal/search/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestParseTypeLimits(t *testing.T) {
+	limits, err := parseTypeLimits("lead:8, quote:3, partner:2", []string{"lead", "quote", "appointment"}, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]int{"lead": 8, "quote": 3, "appointment": 5}
+	if len(limits) != len(want) {
+		t.Fatalf("expected %v, got %v", want, limits)
+	}
+	for typ, limit := range want {
+		if limits[typ] != limit {
+			t.Fatalf("expected %v, got %v", want, limits)
+		}
+	}
+
+	for _, raw := range []string{"lead", "lead:0", "lead:21", "invoice:3"} {
+		if _, err := parseTypeLimits(raw, []string{"lead"}, 5); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestGroupResultsCapsEachType(t *testing.T) {
+	result := func(typ string, typeTotal int64) repository.SearchResult {
+		return repository.SearchResult{ID: uuid.New(), Type: typ, TypeTotal: typeTotal}
+	}
+	results := []repository.SearchResult{
+		result("lead", 3), result("quote", 1), result("lead", 3), result("lead", 3),
+	}
+
+	items, groups := groupResults(results, []string{"lead", "quote", "partner"}, map[string]int{"lead": 2, "quote": 2, "partner": 2})
+	if len(items) != 3 || items[0].Type != "lead" || items[1].Type != "quote" || items[2].Type != "lead" {
+		t.Fatalf("expected two leads and a quote in relevance order, got %+v", items)
+	}
+
+	want := map[string][3]int{"lead": {3, 2, 1}, "quote": {1, 1, 0}, "partner": {0, 0, 0}}
+	if len(groups) != len(want) {
+		t.Fatalf("expected %d groups, got %+v", len(want), groups)
+	}
+	for _, g := range groups {
+		w := want[g.Type]
+		if g.Total != w[0] || g.Returned != w[1] || g.HasMore != (w[2] == 1) {
+			t.Fatalf("unexpected summary %+v", g)
+		}
+	}
+}
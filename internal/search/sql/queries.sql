@@ -79,6 +79,7 @@ matching_leads AS (
 			)
 			+ COALESCE(ln.notes_rank * 0.30, 0)
 			+ COALESCE(lsn.service_note_rank * 0.25, 0)
+			+ ts_rank(to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(l.custom_fields))), sq.q_all) * 0.50
 		) AS lead_score
 	FROM RAC_leads l
 	JOIN scoped_leads sl ON sl.lead_id = l.id
@@ -156,6 +157,7 @@ matching_leads AS (
 		) @@ sq.q_all
 		OR ln.notes_rank IS NOT NULL
 		OR lsn.service_note_rank IS NOT NULL
+		OR to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(l.custom_fields))) @@ sq.q_all
 ),
 related_partners AS (
 	SELECT
@@ -188,6 +190,7 @@ results AS (
 			WHEN to_tsvector('simple', rac_immutable_unaccent(coalesce(l.consumer_email, ''))) @@ sq.q_all THEN 'email'
 			WHEN to_tsvector('simple', rac_immutable_unaccent(coalesce(l.consumer_phone, ''))) @@ sq.q_all THEN 'phone'
 			WHEN to_tsvector('dutch', rac_immutable_unaccent(coalesce(l.address_city, ''))) @@ sq.q_all THEN 'city'
+			WHEN to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(l.custom_fields))) @@ sq.q_all THEN 'custom_field'
 			ELSE 'address'
 		END AS matched_field,
 		ml.lead_score AS score,
@@ -223,6 +226,7 @@ results AS (
 			) @@ sq.q_all THEN 'lead'
 			WHEN to_tsvector('simple', rac_immutable_unaccent(coalesce(q.quote_number, ''))) @@ sq.q_all THEN 'quote_number'
 			WHEN to_tsvector('dutch', rac_immutable_unaccent(coalesce(q.notes, ''))) @@ sq.q_all THEN 'notes'
+			WHEN to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(q.custom_fields))) @@ sq.q_all THEN 'custom_field'
 			ELSE 'content'
 		END AS matched_field,
 		(
@@ -237,6 +241,7 @@ results AS (
 			)
 			ELSE 0
 			END + COALESCE(ml.lead_score * 0.20, 0)
+			+ ts_rank(to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(q.custom_fields))), sq.q_all) * 0.50
 		) AS score,
 		q.created_at
 	FROM RAC_quotes q
@@ -247,7 +252,7 @@ results AS (
 		AND ((
 			setweight(to_tsvector('simple', rac_immutable_unaccent(coalesce(q.quote_number, ''))), 'A') ||
 			setweight(to_tsvector('dutch', rac_immutable_unaccent(coalesce(q.notes, ''))), 'D')
-		) @@ sq.q_all OR ml.lead_id IS NOT NULL)
+		) @@ sq.q_all OR ml.lead_id IS NOT NULL OR to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(q.custom_fields))) @@ sq.q_all)
 
 	UNION ALL
 
@@ -347,6 +352,7 @@ results AS (
 	LEFT JOIN matching_leads ml ON ml.lead_id = a.lead_id
 	CROSS JOIN search_query sq
 	WHERE a.organization_id = sqlc.arg(org_id)
+		AND (sqlc.narg(viewer_user_id)::uuid IS NULL OR a.user_id = sqlc.narg(viewer_user_id)::uuid)
 		AND ((
 			setweight(to_tsvector('simple', rac_immutable_unaccent(coalesce(a.title, ''))), 'A') ||
 			setweight(to_tsvector('dutch', rac_immutable_unaccent(coalesce(a.description, ''))), 'D') ||
@@ -412,9 +418,18 @@ results AS (
 			rac_immutable_unaccent(coalesce(st.name, '')) ILIKE sq.q_text_prefix
 			OR rac_immutable_unaccent(coalesce(st.slug, '')) ILIKE sq.q_text_prefix
 		)
+),
+ranked AS (
+	SELECT
+		results.*,
+		ROW_NUMBER() OVER (PARTITION BY type ORDER BY score DESC, created_at DESC) AS type_rank,
+		COUNT(*) OVER () AS total,
+		COUNT(*) OVER (PARTITION BY type) AS type_total
+	FROM results
+	WHERE (sqlc.narg(types)::text[] IS NULL OR type = ANY(sqlc.narg(types)::text[]))
 )
-SELECT id, type::text, title::text, subtitle::text, preview::text, status::text, link_id::text, matched_field::text, score::real AS score, created_at, COUNT(*) OVER() AS total
-FROM results
-WHERE (sqlc.narg(types)::text[] IS NULL OR type = ANY(sqlc.narg(types)::text[]))
+SELECT id, type::text, title::text, subtitle::text, preview::text, status::text, link_id::text, matched_field::text, score::real AS score, created_at, total, type_total
+FROM ranked
+WHERE sqlc.narg(per_type_limit)::int IS NULL OR type_rank <= sqlc.narg(per_type_limit)::int
 ORDER BY score DESC, created_at DESC
 LIMIT sqlc.arg(limit_count);
\ No newline at end of file
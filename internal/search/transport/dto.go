@@ -25,3 +25,27 @@ type SearchResponse struct {
 	Items []SearchResultItem `json:"items"`
 	Total int                `json:"total"`
 }
+
+// GlobalSearchRequest backs the command palette. Limit caps the results of each
+// type; Limits overrides it per type, e.g. "lead:8,quote:3".
+type GlobalSearchRequest struct {
+	Query  string `form:"q" validate:"required,min=2,max=100"`
+	Types  string `form:"types" validate:"omitempty,max=200"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=20"`
+	Limits string `form:"limits" validate:"omitempty,max=200"`
+}
+
+// SearchTypeSummary counts the matches of one type.
+type SearchTypeSummary struct {
+	Type     string `json:"type"`
+	Total    int    `json:"total"`    // All matches of the type
+	Returned int    `json:"returned"` // Matches included in items
+	HasMore  bool   `json:"hasMore"`
+}
+
+// GlobalSearchResponse lists the results of all types ranked by relevance, with
+// a summary per searched type.
+type GlobalSearchResponse struct {
+	Items  []SearchResultItem  `json:"items"`
+	Groups []SearchTypeSummary `json:"groups"`
+}
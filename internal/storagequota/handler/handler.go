@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/storagequota/service"
+	"portal_final_backend/internal/storagequota/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	msgInvalidRequest   = "invalid request"
+	msgValidationFailed = "validation failed"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+func (h *Handler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/organizations/me/storage-usage", h.GetOwnStorageUsage)
+}
+
+// RegisterSuperAdminRoutes registers the cross-tenant usage report and quota overrides.
+func (h *Handler) RegisterSuperAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/organizations/:organizationID/storage-usage", h.GetStorageUsage)
+	rg.PUT("/organizations/:organizationID/storage-quota", h.UpdateStorageQuota)
+}
+
+func (h *Handler) GetOwnStorageUsage(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetUsage(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetStorageUsage(c *gin.Context) {
+	organizationID, ok := httpkit.ParseUUIDParam(c, "organizationID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetUsage(c.Request.Context(), organizationID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) UpdateStorageQuota(c *gin.Context) {
+	organizationID, ok := httpkit.ParseUUIDParam(c, "organizationID")
+	if !ok {
+		return
+	}
+
+	var req transport.UpdateStorageQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.svc.UpdateQuota(c.Request.Context(), organizationID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
@@ -0,0 +1,55 @@
+// Package storagequota provides per-organization storage accounting and quota
+// enforcement for every bucket behind the shared storage service.
+package storagequota
+
+import (
+	"portal_final_backend/internal/adapters/storage"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/internal/storagequota/handler"
+	"portal_final_backend/internal/storagequota/repository"
+	"portal_final_backend/internal/storagequota/service"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+	storage storage.StorageService
+}
+
+func NewModule(pool *pgxpool.Pool, storageSvc storage.StorageService, cfg service.Config, val *validator.Validator, log *logger.Logger) *Module {
+	inventory, _ := storageSvc.(storage.ObjectInventory)
+	svc := service.New(repository.New(pool), inventory, cfg, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+		storage: &quotaStorage{StorageService: storageSvc, quotas: svc, log: log},
+	}
+}
+
+// Service returns the storage quota service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+// Storage returns the storage service with quota enforcement and usage accounting.
+// Modules should be wired with this instead of the raw storage service.
+func (m *Module) Storage() storage.StorageService {
+	return m.storage
+}
+
+func (m *Module) Name() string {
+	return "storagequota"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterProtectedRoutes(ctx.Protected)
+	if ctx.SuperAdmin != nil {
+		m.handler.RegisterSuperAdminRoutes(ctx.SuperAdmin)
+	}
+}
+
+var _ apphttp.Module = (*Module)(nil)
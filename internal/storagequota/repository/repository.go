@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository persists organization storage usage and quota overrides.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// BucketUsage is the storage an organization uses in one bucket.
+type BucketUsage struct {
+	Bucket       string
+	ObjectCount  int64
+	SizeBytes    int64
+	ReconciledAt *time.Time
+	UpdatedAt    time.Time
+}
+
+// Quota holds the per-organization limit overrides. Nil values fall back to the
+// configured defaults.
+type Quota struct {
+	SoftLimitBytes *int64
+	HardLimitBytes *int64
+}
+
+// AddUsage adjusts the usage counters of an organization in a bucket. Counters
+// never drop below zero.
+func (r *Repository) AddUsage(ctx context.Context, organizationID uuid.UUID, bucket string, objects, bytes int64) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_organization_storage_usage (organization_id, bucket, object_count, size_bytes)
+		VALUES ($1, $2, GREATEST($3::bigint, 0), GREATEST($4::bigint, 0))
+		ON CONFLICT (organization_id, bucket) DO UPDATE SET
+			object_count = GREATEST(RAC_organization_storage_usage.object_count + $3, 0),
+			size_bytes = GREATEST(RAC_organization_storage_usage.size_bytes + $4, 0),
+			updated_at = now()`, organizationID, bucket, objects, bytes,
+	)
+	if err != nil {
+		return fmt.Errorf("add storage usage: %w", err)
+	}
+	return nil
+}
+
+// TotalBytes returns the bytes an organization uses across all buckets.
+func (r *Repository) TotalBytes(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(size_bytes), 0)::bigint
+		FROM RAC_organization_storage_usage
+		WHERE organization_id = $1`, organizationID,
+	).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("get storage usage total: %w", err)
+	}
+	return total, nil
+}
+
+// ListUsage returns the per-bucket usage of an organization.
+func (r *Repository) ListUsage(ctx context.Context, organizationID uuid.UUID) ([]BucketUsage, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT bucket, object_count, size_bytes, reconciled_at, updated_at
+		FROM RAC_organization_storage_usage
+		WHERE organization_id = $1
+		ORDER BY bucket`, organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list storage usage: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]BucketUsage, 0)
+	for rows.Next() {
+		var u BucketUsage
+		if err := rows.Scan(&u.Bucket, &u.ObjectCount, &u.SizeBytes, &u.ReconciledAt, &u.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan storage usage: %w", err)
+		}
+		items = append(items, u)
+	}
+	return items, rows.Err()
+}
+
+// ReplaceBucketUsage overwrites the usage of every organization in a bucket with
+// the reconciled totals. Organizations without objects in the bucket are reset to zero.
+func (r *Repository) ReplaceBucketUsage(ctx context.Context, bucket string, usage map[uuid.UUID]BucketUsage) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin reconcile storage usage: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE RAC_organization_storage_usage
+		SET object_count = 0, size_bytes = 0, reconciled_at = now(), updated_at = now()
+		WHERE bucket = $1`, bucket,
+	); err != nil {
+		return fmt.Errorf("reset storage usage: %w", err)
+	}
+
+	for organizationID, u := range usage {
+		// Objects of deleted organizations are left for the orphan cleanups.
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO RAC_organization_storage_usage (organization_id, bucket, object_count, size_bytes, reconciled_at)
+			SELECT $1, $2, $3, $4, now()
+			WHERE EXISTS (SELECT 1 FROM RAC_organizations WHERE id = $1)
+			ON CONFLICT (organization_id, bucket) DO UPDATE SET
+				object_count = EXCLUDED.object_count,
+				size_bytes = EXCLUDED.size_bytes,
+				reconciled_at = now(),
+				updated_at = now()`, organizationID, bucket, u.ObjectCount, u.SizeBytes,
+		); err != nil {
+			return fmt.Errorf("store reconciled storage usage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit reconcile storage usage: %w", err)
+	}
+	return nil
+}
+
+// GetQuota returns the limit overrides of an organization.
+func (r *Repository) GetQuota(ctx context.Context, organizationID uuid.UUID) (Quota, error) {
+	var q Quota
+	err := r.pool.QueryRow(ctx, `
+		SELECT soft_limit_bytes, hard_limit_bytes
+		FROM RAC_organization_storage_quotas
+		WHERE organization_id = $1`, organizationID,
+	).Scan(&q.SoftLimitBytes, &q.HardLimitBytes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Quota{}, nil
+	}
+	if err != nil {
+		return Quota{}, fmt.Errorf("get storage quota: %w", err)
+	}
+	return q, nil
+}
+
+// UpsertQuota stores the limit overrides of an organization.
+func (r *Repository) UpsertQuota(ctx context.Context, organizationID uuid.UUID, q Quota) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO RAC_organization_storage_quotas (organization_id, soft_limit_bytes, hard_limit_bytes)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			soft_limit_bytes = EXCLUDED.soft_limit_bytes,
+			hard_limit_bytes = EXCLUDED.hard_limit_bytes,
+			updated_at = now()`, organizationID, q.SoftLimitBytes, q.HardLimitBytes,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert storage quota: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// Package service implements per-organization storage accounting and quota
+// enforcement across all object storage buckets.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/storagequota/repository"
+	"portal_final_backend/internal/storagequota/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const msgQuotaExceeded = "storage quota exceeded"
+
+type Repository interface {
+	AddUsage(ctx context.Context, organizationID uuid.UUID, bucket string, objects, bytes int64) error
+	TotalBytes(ctx context.Context, organizationID uuid.UUID) (int64, error)
+	ListUsage(ctx context.Context, organizationID uuid.UUID) ([]repository.BucketUsage, error)
+	ReplaceBucketUsage(ctx context.Context, bucket string, usage map[uuid.UUID]repository.BucketUsage) error
+	GetQuota(ctx context.Context, organizationID uuid.UUID) (repository.Quota, error)
+	UpsertQuota(ctx context.Context, organizationID uuid.UUID, q repository.Quota) error
+}
+
+// Config holds the default limits and the buckets covered by reconciliation.
+// A limit of zero disables it.
+type Config struct {
+	SoftLimitBytes int64
+	HardLimitBytes int64
+	Buckets        []string
+}
+
+// ReconcileResult summarizes a reconciliation run.
+type ReconcileResult struct {
+	Buckets      int
+	Objects      int64
+	Unattributed int64
+	Failed       int
+}
+
+type Service struct {
+	repo      Repository
+	inventory storage.ObjectInventory
+	cfg       Config
+	log       *logger.Logger
+}
+
+// New creates the quota service. The inventory is used to size deleted objects
+// and to reconcile usage; it may be nil, in which case only uploads are counted.
+func New(repo Repository, inventory storage.ObjectInventory, cfg Config, log *logger.Logger) *Service {
+	return &Service{repo: repo, inventory: inventory, cfg: cfg, log: log}
+}
+
+// OrganizationFromKey extracts the organization owning a folder or object key.
+// Keys are either "{org}/..." or "{kind}/{org}/...".
+func OrganizationFromKey(key string) (uuid.UUID, bool) {
+	segments := strings.SplitN(strings.TrimPrefix(key, "/"), "/", 3)
+	for i := 0; i < len(segments) && i < 2; i++ {
+		if id, err := uuid.Parse(segments[i]); err == nil {
+			return id, true
+		}
+	}
+	return uuid.UUID{}, false
+}
+
+// limits resolves the effective soft and hard limit of an organization.
+func (s *Service) limits(ctx context.Context, organizationID uuid.UUID) (int64, int64, error) {
+	quota, err := s.repo.GetQuota(ctx, organizationID)
+	if err != nil {
+		return 0, 0, err
+	}
+	soft, hard := s.cfg.SoftLimitBytes, s.cfg.HardLimitBytes
+	if quota.SoftLimitBytes != nil {
+		soft = *quota.SoftLimitBytes
+	}
+	if quota.HardLimitBytes != nil {
+		hard = *quota.HardLimitBytes
+	}
+	return soft, hard, nil
+}
+
+// CheckUpload rejects an upload that would take the organization past its hard
+// limit. Crossing the soft limit is only logged.
+func (s *Service) CheckUpload(ctx context.Context, organizationID uuid.UUID, sizeBytes int64) error {
+	soft, hard, err := s.limits(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if soft <= 0 && hard <= 0 {
+		return nil
+	}
+
+	used, err := s.repo.TotalBytes(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	projected := used + max(sizeBytes, 0)
+	if hard > 0 && projected > hard {
+		return apperr.Forbidden(msgQuotaExceeded).WithDetails(map[string]int64{
+			"usedBytes":      used,
+			"requestedBytes": sizeBytes,
+			"hardLimitBytes": hard,
+		})
+	}
+	if soft > 0 && projected > soft {
+		s.log.Warn("storage quota: soft limit exceeded", "organizationId", organizationID, "usedBytes", projected, "softLimitBytes", soft)
+	}
+	return nil
+}
+
+// RecordUpload adds an uploaded object to the usage of an organization.
+func (s *Service) RecordUpload(ctx context.Context, organizationID uuid.UUID, bucket string, sizeBytes int64) error {
+	return s.repo.AddUsage(ctx, organizationID, bucket, 1, max(sizeBytes, 0))
+}
+
+// RecordDelete removes a deleted object from the usage of an organization.
+func (s *Service) RecordDelete(ctx context.Context, organizationID uuid.UUID, bucket string, sizeBytes int64) error {
+	return s.repo.AddUsage(ctx, organizationID, bucket, -1, -max(sizeBytes, 0))
+}
+
+// ObjectSize returns the size of a stored object, if the backend can report it.
+func (s *Service) ObjectSize(ctx context.Context, bucket, key string) (int64, bool) {
+	if s.inventory == nil {
+		return 0, false
+	}
+	info, err := s.inventory.StatObject(ctx, bucket, key)
+	if err != nil {
+		return 0, false
+	}
+	return info.Size, true
+}
+
+// GetUsage returns the storage of an organization with a per-bucket breakdown.
+func (s *Service) GetUsage(ctx context.Context, organizationID uuid.UUID) (transport.StorageUsageResponse, error) {
+	usage, err := s.repo.ListUsage(ctx, organizationID)
+	if err != nil {
+		return transport.StorageUsageResponse{}, err
+	}
+	soft, hard, err := s.limits(ctx, organizationID)
+	if err != nil {
+		return transport.StorageUsageResponse{}, err
+	}
+
+	resp := transport.StorageUsageResponse{
+		OrganizationID: organizationID,
+		SoftLimitBytes: soft,
+		HardLimitBytes: hard,
+		Buckets:        make([]transport.BucketUsageResponse, 0, len(usage)),
+	}
+	for _, u := range usage {
+		resp.TotalObjects += u.ObjectCount
+		resp.TotalBytes += u.SizeBytes
+		resp.Buckets = append(resp.Buckets, transport.BucketUsageResponse{
+			Bucket:       u.Bucket,
+			Objects:      u.ObjectCount,
+			SizeBytes:    u.SizeBytes,
+			ReconciledAt: u.ReconciledAt,
+		})
+	}
+	resp.SoftLimitExceeded = soft > 0 && resp.TotalBytes > soft
+	resp.HardLimitExceeded = hard > 0 && resp.TotalBytes > hard
+	return resp, nil
+}
+
+// UpdateQuota overrides the limits of an organization.
+func (s *Service) UpdateQuota(ctx context.Context, organizationID uuid.UUID, req transport.UpdateStorageQuotaRequest) (transport.StorageUsageResponse, error) {
+	if req.SoftLimitBytes != nil && req.HardLimitBytes != nil && *req.HardLimitBytes > 0 && *req.SoftLimitBytes > *req.HardLimitBytes {
+		return transport.StorageUsageResponse{}, apperr.Validation("soft limit must not exceed hard limit")
+	}
+	if err := s.repo.UpsertQuota(ctx, organizationID, repository.Quota{
+		SoftLimitBytes: req.SoftLimitBytes,
+		HardLimitBytes: req.HardLimitBytes,
+	}); err != nil {
+		return transport.StorageUsageResponse{}, err
+	}
+	return s.GetUsage(ctx, organizationID)
+}
+
+// Reconcile recomputes the usage of every organization from the object listing of
+// each configured bucket. A bucket that fails to list keeps its current counters.
+func (s *Service) Reconcile(ctx context.Context) (ReconcileResult, error) {
+	if s.inventory == nil {
+		return ReconcileResult{}, errors.New("storage backend cannot list objects")
+	}
+
+	var result ReconcileResult
+	for _, bucket := range s.cfg.Buckets {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		usage := make(map[uuid.UUID]repository.BucketUsage)
+		err := s.inventory.ListObjects(ctx, bucket, "", func(obj storage.ObjectInfo) error {
+			result.Objects++
+			organizationID, ok := OrganizationFromKey(obj.Key)
+			if !ok {
+				result.Unattributed++
+				return nil
+			}
+			u := usage[organizationID]
+			u.ObjectCount++
+			u.SizeBytes += obj.Size
+			usage[organizationID] = u
+			return nil
+		})
+		if err == nil {
+			err = s.repo.ReplaceBucketUsage(ctx, bucket, usage)
+		}
+		if err != nil {
+			result.Failed++
+			s.log.Warn("storage quota: failed to reconcile bucket", "bucket", bucket, "error", err)
+			continue
+		}
+		result.Buckets++
+	}
+	if result.Failed > 0 && result.Buckets == 0 {
+		return result, fmt.Errorf("reconcile storage usage: all %d buckets failed", result.Failed)
+	}
+	return result, nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestOrganizationFromKey(t *testing.T) {
+	orgID := uuid.MustParse("7b1c4a3e-0f2d-4c5e-9a8b-1d2e3f4a5b6c")
+
+	cases := map[string]bool{
+		orgID.String() + "/lead/service/photo.jpg":         true,
+		"organizations/" + orgID.String():                  true,
+		"partners/" + orgID.String() + "/partner/logo.png": true,
+		"/" + orgID.String() + "/quotes/offer.pdf":         true,
+		"partners/logos/" + orgID.String() + "/logo.png":   false,
+		"shared/templates/offer.pdf":                       false,
+		"":                                                 false,
+	}
+	for key, want := range cases {
+		got, ok := OrganizationFromKey(key)
+		if ok != want {
+			t.Fatalf("OrganizationFromKey(%q) ok = %v, want %v", key, ok, want)
+		}
+		if ok && got != orgID {
+			t.Fatalf("OrganizationFromKey(%q) = %s, want %s", key, got, orgID)
+		}
+	}
+}
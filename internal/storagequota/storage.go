@@ -0,0 +1,160 @@
+package storagequota
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/storagequota/service"
+	"portal_final_backend/platform/logger"
+)
+
+var (
+	errInventoryUnsupported = errors.New("storage backend cannot list objects")
+	errMultipartUnsupported = errors.New("storage backend cannot assemble multipart uploads")
+)
+
+// quotaStorage enforces organization quotas and keeps usage counters up to date
+// for every upload and delete that goes through the wrapped storage service.
+// Objects whose key carries no organization are passed through unaccounted.
+type quotaStorage struct {
+	storage.StorageService
+	quotas *service.Service
+	log    *logger.Logger
+}
+
+var (
+	_ storage.StorageService    = (*quotaStorage)(nil)
+	_ storage.ObjectInventory   = (*quotaStorage)(nil)
+	_ storage.MultipartUploader = (*quotaStorage)(nil)
+)
+
+// GenerateUploadURL checks the quota for the declared size before presigning.
+// The declared size is counted right away; reconciliation corrects uploads that
+// never happen.
+func (s *quotaStorage) GenerateUploadURL(ctx context.Context, bucket, folder, fileName, contentType string, sizeBytes int64) (*storage.PresignedURL, error) {
+	organizationID, ok := service.OrganizationFromKey(folder)
+	if ok {
+		if err := s.quotas.CheckUpload(ctx, organizationID, sizeBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	presigned, err := s.StorageService.GenerateUploadURL(ctx, bucket, folder, fileName, contentType, sizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if err := s.quotas.RecordUpload(ctx, organizationID, bucket, sizeBytes); err != nil {
+			s.log.Warn("storage quota: failed to record upload", "bucket", bucket, "key", presigned.FileKey, "error", err)
+		}
+	}
+	return presigned, nil
+}
+
+func (s *quotaStorage) UploadFile(ctx context.Context, bucket, folder, fileName, contentType string, reader io.Reader, size int64) (string, error) {
+	organizationID, ok := service.OrganizationFromKey(folder)
+	if ok {
+		if err := s.quotas.CheckUpload(ctx, organizationID, size); err != nil {
+			return "", err
+		}
+	}
+
+	fileKey, err := s.StorageService.UploadFile(ctx, bucket, folder, fileName, contentType, reader, size)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		if err := s.quotas.RecordUpload(ctx, organizationID, bucket, size); err != nil {
+			s.log.Warn("storage quota: failed to record upload", "bucket", bucket, "key", fileKey, "error", err)
+		}
+	}
+	return fileKey, nil
+}
+
+func (s *quotaStorage) DeleteObject(ctx context.Context, bucket, fileKey string) error {
+	organizationID, ok := service.OrganizationFromKey(fileKey)
+	var size int64
+	if ok {
+		// An object that cannot be sized is most likely gone already.
+		size, ok = s.quotas.ObjectSize(ctx, bucket, fileKey)
+	}
+
+	if err := s.StorageService.DeleteObject(ctx, bucket, fileKey); err != nil {
+		return err
+	}
+	if ok {
+		if err := s.quotas.RecordDelete(ctx, organizationID, bucket, size); err != nil {
+			s.log.Warn("storage quota: failed to record delete", "bucket", bucket, "key", fileKey, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *quotaStorage) StatObject(ctx context.Context, bucket, fileKey string) (storage.ObjectInfo, error) {
+	inventory, ok := s.StorageService.(storage.ObjectInventory)
+	if !ok {
+		return storage.ObjectInfo{}, errInventoryUnsupported
+	}
+	return inventory.StatObject(ctx, bucket, fileKey)
+}
+
+func (s *quotaStorage) ListObjects(ctx context.Context, bucket, prefix string, fn func(storage.ObjectInfo) error) error {
+	inventory, ok := s.StorageService.(storage.ObjectInventory)
+	if !ok {
+		return errInventoryUnsupported
+	}
+	return inventory.ListObjects(ctx, bucket, prefix, fn)
+}
+
+// StartMultipartUpload checks the quota for the expected size of the object.
+// Usage is recorded once the upload completes.
+func (s *quotaStorage) StartMultipartUpload(ctx context.Context, bucket, folder, fileName, contentType string, size int64) (string, string, error) {
+	uploader, ok := s.StorageService.(storage.MultipartUploader)
+	if !ok {
+		return "", "", errMultipartUnsupported
+	}
+	if organizationID, ok := service.OrganizationFromKey(folder); ok {
+		if err := s.quotas.CheckUpload(ctx, organizationID, size); err != nil {
+			return "", "", err
+		}
+	}
+	return uploader.StartMultipartUpload(ctx, bucket, folder, fileName, contentType, size)
+}
+
+func (s *quotaStorage) UploadPart(ctx context.Context, bucket, fileKey, uploadID string, number int, reader io.Reader, size int64, sha256Hex string) (storage.UploadedPart, error) {
+	uploader, ok := s.StorageService.(storage.MultipartUploader)
+	if !ok {
+		return storage.UploadedPart{}, errMultipartUnsupported
+	}
+	return uploader.UploadPart(ctx, bucket, fileKey, uploadID, number, reader, size, sha256Hex)
+}
+
+func (s *quotaStorage) CompleteMultipartUpload(ctx context.Context, bucket, fileKey, uploadID string, parts []storage.UploadedPart) error {
+	uploader, ok := s.StorageService.(storage.MultipartUploader)
+	if !ok {
+		return errMultipartUnsupported
+	}
+	if err := uploader.CompleteMultipartUpload(ctx, bucket, fileKey, uploadID, parts); err != nil {
+		return err
+	}
+	if organizationID, ok := service.OrganizationFromKey(fileKey); ok {
+		var size int64
+		for _, part := range parts {
+			size += part.Size
+		}
+		if err := s.quotas.RecordUpload(ctx, organizationID, bucket, size); err != nil {
+			s.log.Warn("storage quota: failed to record upload", "bucket", bucket, "key", fileKey, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *quotaStorage) AbortMultipartUpload(ctx context.Context, bucket, fileKey, uploadID string) error {
+	uploader, ok := s.StorageService.(storage.MultipartUploader)
+	if !ok {
+		return errMultipartUnsupported
+	}
+	return uploader.AbortMultipartUpload(ctx, bucket, fileKey, uploadID)
+}
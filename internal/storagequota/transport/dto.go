@@ -0,0 +1,35 @@
+// Package transport provides DTOs for organization storage quotas.
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BucketUsageResponse struct {
+	Bucket       string     `json:"bucket"`
+	Objects      int64      `json:"objects"`
+	SizeBytes    int64      `json:"sizeBytes"`
+	ReconciledAt *time.Time `json:"reconciledAt,omitempty"`
+}
+
+// StorageUsageResponse reports the storage of an organization across all buckets.
+// A limit of zero means the limit is disabled.
+type StorageUsageResponse struct {
+	OrganizationID    uuid.UUID             `json:"organizationId"`
+	TotalObjects      int64                 `json:"totalObjects"`
+	TotalBytes        int64                 `json:"totalBytes"`
+	SoftLimitBytes    int64                 `json:"softLimitBytes"`
+	HardLimitBytes    int64                 `json:"hardLimitBytes"`
+	SoftLimitExceeded bool                  `json:"softLimitExceeded"`
+	HardLimitExceeded bool                  `json:"hardLimitExceeded"`
+	Buckets           []BucketUsageResponse `json:"buckets"`
+}
+
+// UpdateStorageQuotaRequest overrides the default limits of an organization.
+// Omitted limits fall back to the configured defaults, zero disables a limit.
+type UpdateStorageQuotaRequest struct {
+	SoftLimitBytes *int64 `json:"softLimitBytes" validate:"omitempty,gte=0"`
+	HardLimitBytes *int64 `json:"hardLimitBytes" validate:"omitempty,gte=0"`
+}
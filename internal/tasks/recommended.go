@@ -0,0 +1,103 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"portal_final_backend/internal/events"
+)
+
+const recommendedTaskDescriptionMaxLen = 2000
+
+// recommendedTask is the follow-up task opened for an AI recommended action.
+type recommendedTask struct {
+	Title    string
+	Priority string
+	DueIn    time.Duration
+}
+
+// recommendedTaskFor returns the task an AI recommended action calls for.
+// Actions the pipeline handles on its own, such as RequestInfo and Reject,
+// open no task.
+func recommendedTaskFor(action, urgencyLevel string) (recommendedTask, bool) {
+	switch action {
+	case "CallImmediately":
+		task := recommendedTask{Title: "Klant direct bellen", Priority: PriorityHigh, DueIn: 4 * time.Hour}
+		if urgencyLevel == "High" {
+			task.Priority, task.DueIn = PriorityUrgent, time.Hour
+		}
+		return task, true
+	case "ScheduleSurvey":
+		return recommendedTask{Title: "Opname inplannen", Priority: PriorityNormal, DueIn: 48 * time.Hour}, true
+	default:
+		return recommendedTask{}, false
+	}
+}
+
+// CreateFromRecommendedAction opens a task for the assigned agent of a lead
+// when its AI analysis recommends an action that needs a person, unless the
+// service already has the same task open. Leads without an assigned agent
+// get no task.
+func (s *Service) CreateFromRecommendedAction(ctx context.Context, e events.LeadActionRecommended) error {
+	spec, ok := recommendedTaskFor(e.RecommendedAction, e.UrgencyLevel)
+	if !ok || e.AssignedAgentID == nil {
+		return nil
+	}
+
+	open, err := s.List(ctx, e.TenantID, ListTasksRequest{
+		ScopeType:     ScopeLeadService,
+		Status:        StatusOpen,
+		LeadServiceID: e.LeadServiceID.String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, task := range open {
+		if task.Title == spec.Title {
+			return nil
+		}
+	}
+
+	leadID := e.LeadID.String()
+	serviceID := e.LeadServiceID.String()
+	dueAt := time.Now().UTC().Add(spec.DueIn)
+	req := CreateTaskRequest{
+		ScopeType:      ScopeLeadService,
+		LeadID:         &leadID,
+		LeadServiceID:  &serviceID,
+		AssignedUserID: e.AssignedAgentID.String(),
+		Title:          spec.Title,
+		Priority:       spec.Priority,
+		DueAt:          &dueAt,
+	}
+	if summary := []rune(e.Summary); len(summary) > 0 {
+		if len(summary) > recommendedTaskDescriptionMaxLen {
+			summary = summary[:recommendedTaskDescriptionMaxLen]
+		}
+		description := string(summary)
+		req.Description = &description
+	}
+
+	// The analysis has no human author; the agent is recorded as creator.
+	_, err = s.Create(ctx, e.TenantID, *e.AssignedAgentID, req)
+	return err
+}
+
+// RegisterHandlers subscribes the module to system-wide events.
+func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
+	bus.Subscribe(events.LeadActionRecommended{}.EventName(), m)
+}
+
+// Handle processes subscribed domain events.
+func (m *Module) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.LeadActionRecommended:
+		if err := m.svc.CreateFromRecommendedAction(ctx, e); err != nil && m.svc.log != nil {
+			m.svc.log.Warn("failed to create task for recommended action",
+				"leadServiceId", e.LeadServiceID, "action", e.RecommendedAction, "error", err)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecommendedTaskFor(t *testing.T) {
+	task, ok := recommendedTaskFor("CallImmediately", "High")
+	if !ok || task.Priority != PriorityUrgent || task.DueIn != time.Hour {
+		t.Fatalf("CallImmediately/High = %+v, %v; want an urgent task due within the hour", task, ok)
+	}
+
+	task, ok = recommendedTaskFor("CallImmediately", "Medium")
+	if !ok || task.Priority != PriorityHigh {
+		t.Fatalf("CallImmediately/Medium = %+v, %v; want a high priority task", task, ok)
+	}
+
+	if _, ok := recommendedTaskFor("ScheduleSurvey", "Low"); !ok {
+		t.Fatal("ScheduleSurvey should open a task")
+	}
+
+	for _, action := range []string{"RequestInfo", "Reject", ""} {
+		if _, ok := recommendedTaskFor(action, "High"); ok {
+			t.Errorf("%q should not open a task", action)
+		}
+	}
+}
+
+func TestNormalizeReminderConfigDefaultsToDueAt(t *testing.T) {
+	dueAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	cfg, err := normalizeReminderConfig(&ReminderConfig{Enabled: true, SendEmail: true}, &dueAt)
+	if err != nil {
+		t.Fatalf("normalizeReminderConfig: %v", err)
+	}
+	if cfg.RunAt == nil || !cfg.RunAt.Equal(dueAt) {
+		t.Fatalf("runAt = %v, want the due date %v", cfg.RunAt, dueAt)
+	}
+
+	if _, err := normalizeReminderConfig(&ReminderConfig{Enabled: true, SendEmail: true}, nil); err == nil {
+		t.Fatal("expected an error without runAt and due date")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	leadrepo "portal_final_backend/internal/leads/repository"
 	notificationoutbox "portal_final_backend/internal/notification/outbox"
+	"portal_final_backend/internal/notification/sse"
 	"portal_final_backend/internal/scheduler"
 	"portal_final_backend/platform/logger"
 	"portal_final_backend/platform/phone"
@@ -21,6 +22,7 @@ type Service struct {
 	notificationOutbox *notificationoutbox.Repository
 	reminderScheduler  scheduler.TaskReminderScheduler
 	timeline           leadrepo.TimelineEventStore
+	sse                *sse.Service
 	log                *logger.Logger
 }
 
@@ -34,6 +36,12 @@ func NewService(repo *Repository, reminderScheduler scheduler.TaskReminderSchedu
 	}
 }
 
+// SetSSE enables pushing task changes to the connected members of the
+// organization.
+func (s *Service) SetSSE(sseService *sse.Service) {
+	s.sse = sseService
+}
+
 func (s *Service) List(ctx context.Context, tenantID uuid.UUID, req ListTasksRequest) ([]TaskRecord, error) {
 	filter, err := parseListFilter(req)
 	if err != nil {
@@ -73,6 +81,7 @@ func (s *Service) Create(ctx context.Context, tenantID, actorID uuid.UUID, req C
 		s.scheduleReminderBestEffort(ctx, *created.Reminder)
 	}
 	s.writeTimelineBestEffort(ctx, created, leadrepo.EventTitleCustomerInfo, "Taak aangemaakt")
+	s.publishTaskEvent(sse.EventTaskCreated, created)
 	return created, nil
 }
 
@@ -104,6 +113,7 @@ func (s *Service) Update(ctx context.Context, tenantID, taskID uuid.UUID, req Up
 	if updated.Reminder != nil {
 		s.scheduleReminderBestEffort(ctx, *updated.Reminder)
 	}
+	s.publishTaskEvent(sse.EventTaskUpdated, updated)
 	return updated, nil
 }
 
@@ -122,6 +132,7 @@ func (s *Service) Complete(ctx context.Context, tenantID, taskID uuid.UUID) (Tas
 		return TaskRecord{}, err
 	}
 	s.writeTimelineBestEffort(ctx, task, leadrepo.EventTitleLeadDetailsUpdated, "Taak afgerond")
+	s.publishTaskEvent(sse.EventTaskUpdated, task)
 	return task, nil
 }
 
@@ -135,6 +146,7 @@ func (s *Service) Cancel(ctx context.Context, tenantID, taskID uuid.UUID) (TaskR
 		return TaskRecord{}, err
 	}
 	s.writeTimelineBestEffort(ctx, task, leadrepo.EventTitleLeadDetailsUpdated, "Taak geannuleerd")
+	s.publishTaskEvent(sse.EventTaskUpdated, task)
 	return task, nil
 }
 
@@ -147,6 +159,7 @@ func (s *Service) Reopen(ctx context.Context, tenantID, taskID uuid.UUID) (TaskR
 		return TaskRecord{}, err
 	}
 	s.writeTimelineBestEffort(ctx, task, leadrepo.EventTitleLeadDetailsUpdated, "Taak heropend")
+	s.publishTaskEvent(sse.EventTaskUpdated, task)
 	return task, nil
 }
 
@@ -159,6 +172,7 @@ func (s *Service) Delete(ctx context.Context, tenantID, taskID uuid.UUID) error
 		return err
 	}
 	s.writeTimelineBestEffort(ctx, task, leadrepo.EventTitleLeadDetailsUpdated, "Taak verwijderd")
+	s.publishTaskEvent(sse.EventTaskDeleted, task)
 	return nil
 }
 
@@ -218,13 +232,33 @@ func (s *Service) writeTimelineBestEffort(ctx context.Context, task TaskRecord,
 	})
 }
 
-func normalizeReminderConfig(cfg *ReminderConfig) (*ReminderConfig, error) {
+// publishTaskEvent pushes a task change to the members of its organization.
+func (s *Service) publishTaskEvent(eventType sse.EventType, task TaskRecord) {
+	if s.sse == nil {
+		return
+	}
+	event := sse.Event{Type: eventType, Data: task}
+	if task.LeadID != nil {
+		event.LeadID = *task.LeadID
+	}
+	if task.LeadServiceID != nil {
+		event.ServiceID = *task.LeadServiceID
+	}
+	s.sse.PublishToOrganization(task.TenantID, event)
+}
+
+// normalizeReminderConfig validates a reminder. An enabled reminder without
+// runAt fires at the due date of its task.
+func normalizeReminderConfig(cfg *ReminderConfig, dueAt *time.Time) (*ReminderConfig, error) {
 	if cfg == nil {
 		return nil, nil
 	}
 	if !cfg.Enabled {
 		return &ReminderConfig{Enabled: false}, nil
 	}
+	if (cfg.RunAt == nil || cfg.RunAt.IsZero()) && dueAt != nil && !dueAt.IsZero() {
+		cfg.RunAt = dueAt
+	}
 	if cfg.RunAt == nil || cfg.RunAt.IsZero() {
 		return nil, fmt.Errorf("reminder runAt is required when reminder is enabled")
 	}
@@ -350,7 +384,7 @@ func buildReminderWhatsAppMessage(task TaskRecord) string {
 }
 
 func (s *Service) prepareCreateTask(tenantID, actorID uuid.UUID, req CreateTaskRequest) (TaskRecord, *ReminderConfig, error) {
-	reminderCfg, err := normalizeReminderConfig(req.Reminder)
+	reminderCfg, err := normalizeReminderConfig(req.Reminder, req.DueAt)
 	if err != nil {
 		return TaskRecord{}, nil, err
 	}
@@ -475,7 +509,7 @@ func (s *Service) applyReminderUpdate(ctx context.Context, tx pgx.Tx, tenantID,
 	if req.Reminder == nil {
 		return nil
 	}
-	reminderCfg, err := normalizeReminderConfig(req.Reminder)
+	reminderCfg, err := normalizeReminderConfig(req.Reminder, req.DueAt)
 	if err != nil {
 		return err
 	}
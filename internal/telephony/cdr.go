@@ -0,0 +1,152 @@
+package telephony
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/phone"
+)
+
+var errInvalidCallRecord = errors.New("invalid call record")
+
+// parseCallRecord reads the webhook body of a provider: Twilio posts form
+// values, VoIPGrid posts JSON.
+func parseCallRecord(provider string, body []byte, now time.Time) (CallRecord, error) {
+	switch provider {
+	case ProviderTwilio:
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return CallRecord{}, errInvalidCallRecord
+		}
+		return parseTwilioCallRecord(form, now)
+	case ProviderVoIPGrid:
+		return parseVoIPGridCallRecord(body, now)
+	default:
+		return CallRecord{}, errInvalidCallRecord
+	}
+}
+
+// parseTwilioCallRecord reads a Twilio status callback. Twilio reports
+// outbound-api and outbound-dial legs as outbound.
+func parseTwilioCallRecord(form url.Values, now time.Time) (CallRecord, error) {
+	rec := CallRecord{
+		ProviderCallID: strings.TrimSpace(form.Get("CallSid")),
+		Direction:      DirectionOutbound,
+		Status:         twilioStatus(form.Get("CallStatus")),
+		FromNumber:     phone.NormalizeE164(form.Get("From")),
+		ToNumber:       phone.NormalizeE164(form.Get("To")),
+		OccurredAt:     now,
+	}
+	if rec.ProviderCallID == "" || rec.Status == "" {
+		return CallRecord{}, errInvalidCallRecord
+	}
+	if strings.EqualFold(strings.TrimSpace(form.Get("Direction")), "inbound") {
+		rec.Direction = DirectionInbound
+	}
+	if ts, err := time.Parse(time.RFC1123Z, strings.TrimSpace(form.Get("Timestamp"))); err == nil {
+		rec.OccurredAt = ts
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(form.Get("CallDuration"))); err == nil && seconds >= 0 {
+		rec.DurationSeconds = &seconds
+	}
+	if recording := strings.TrimSpace(form.Get("RecordingUrl")); recording != "" {
+		rec.RecordingURL = &recording
+	}
+	return rec, nil
+}
+
+func twilioStatus(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "queued", "initiated":
+		return StatusInitiated
+	case "ringing":
+		return StatusRinging
+	case "in-progress", "answered":
+		return StatusAnswered
+	case "completed":
+		return StatusCompleted
+	case "no-answer", "canceled":
+		return StatusMissed
+	case "busy":
+		return StatusBusy
+	case "failed":
+		return StatusFailed
+	default:
+		return ""
+	}
+}
+
+// voipgridNotification is a VoIPGrid call notification. Ended calls carry the
+// reason they ended.
+type voipgridNotification struct {
+	CallID            string `json:"call_id"`
+	Direction         string `json:"direction"`
+	Status            string `json:"status"`
+	Reason            string `json:"reason"`
+	CallerNumber      string `json:"caller_number"`
+	DestinationNumber string `json:"destination_number"`
+	Timestamp         string `json:"timestamp"`
+	RecordingURL      string `json:"recording_url"`
+}
+
+func parseVoIPGridCallRecord(body []byte, now time.Time) (CallRecord, error) {
+	var n voipgridNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return CallRecord{}, errInvalidCallRecord
+	}
+	rec := CallRecord{
+		ProviderCallID: strings.TrimSpace(n.CallID),
+		Direction:      DirectionOutbound,
+		Status:         voipgridStatus(n.Status, n.Reason),
+		FromNumber:     phone.NormalizeE164(n.CallerNumber),
+		ToNumber:       phone.NormalizeE164(n.DestinationNumber),
+		OccurredAt:     now,
+	}
+	if rec.ProviderCallID == "" || rec.Status == "" {
+		return CallRecord{}, errInvalidCallRecord
+	}
+	if strings.EqualFold(strings.TrimSpace(n.Direction), "inbound") {
+		rec.Direction = DirectionInbound
+	}
+	if ts, err := time.Parse(time.RFC3339, strings.TrimSpace(n.Timestamp)); err == nil {
+		rec.OccurredAt = ts
+	}
+	if recording := strings.TrimSpace(n.RecordingURL); recording != "" {
+		rec.RecordingURL = &recording
+	}
+	return rec, nil
+}
+
+func voipgridStatus(status, reason string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "ringing":
+		return StatusRinging
+	case "in-progress", "answered":
+		return StatusAnswered
+	case "ended":
+		switch strings.ToLower(strings.TrimSpace(reason)) {
+		case "", "completed":
+			return StatusCompleted
+		case "busy":
+			return StatusBusy
+		case "no-answer", "cancelled", "canceled", "abandoned":
+			return StatusMissed
+		default:
+			return StatusFailed
+		}
+	default:
+		return ""
+	}
+}
+
+// remoteNumber returns the number of the other party of a call.
+func (r CallRecord) remoteNumber() string {
+	if r.Direction == DirectionInbound {
+		return r.FromNumber
+	}
+	return r.ToNumber
+}
@@ -0,0 +1,116 @@
+package telephony
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTwilioCallRecord(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	body := url.Values{
+		"CallSid":      {"CA123"},
+		"CallStatus":   {"completed"},
+		"Direction":    {"inbound"},
+		"From":         {"+31612345678"},
+		"To":           {"+31201234567"},
+		"CallDuration": {"95"},
+		"Timestamp":    {"Mon, 02 Mar 2026 10:05:00 +0000"},
+	}.Encode()
+
+	rec, err := parseCallRecord(ProviderTwilio, []byte(body), now)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if rec.ProviderCallID != "CA123" || rec.Status != StatusCompleted || rec.Direction != DirectionInbound {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+	if rec.DurationSeconds == nil || *rec.DurationSeconds != 95 {
+		t.Fatalf("duration = %v, want 95", rec.DurationSeconds)
+	}
+	if !rec.OccurredAt.Equal(now.Add(5 * time.Minute)) {
+		t.Fatalf("occurredAt = %v", rec.OccurredAt)
+	}
+	if rec.remoteNumber() != "+31612345678" {
+		t.Fatalf("remote number = %q, want the caller", rec.remoteNumber())
+	}
+
+	if _, err := parseCallRecord(ProviderTwilio, []byte("CallSid=CA1&CallStatus=unknown"), now); err == nil {
+		t.Fatal("expected an unknown status to be rejected")
+	}
+}
+
+func TestParseVoIPGridCallRecord(t *testing.T) {
+	now := time.Now()
+	rec, err := parseCallRecord(ProviderVoIPGrid, []byte(`{
+		"call_id": "vg-1",
+		"direction": "outbound",
+		"status": "ended",
+		"reason": "no-answer",
+		"caller_number": "+31201234567",
+		"destination_number": "+31612345678"
+	}`), now)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if rec.Status != StatusMissed || rec.Direction != DirectionOutbound || rec.remoteNumber() != "+31612345678" {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+	if !rec.OccurredAt.Equal(now) {
+		t.Fatalf("occurredAt = %v, want the receive time", rec.OccurredAt)
+	}
+}
+
+func TestCallSummary(t *testing.T) {
+	duration := 125
+	cases := []struct {
+		call Call
+		want string
+	}{
+		{Call{Direction: DirectionOutbound, Status: StatusCompleted, DurationSeconds: &duration}, "Uitgaand gesprek, duur 2:05"},
+		{Call{Direction: DirectionInbound, Status: StatusMissed}, "Inkomend gesprek, niet beantwoord"},
+		{Call{Direction: DirectionInbound, Status: StatusBusy}, "Inkomend gesprek, in gesprek"},
+	}
+	for _, tc := range cases {
+		if got := callSummary(tc.call); got != tc.want {
+			t.Errorf("callSummary(%s/%s) = %q, want %q", tc.call.Direction, tc.call.Status, got, tc.want)
+		}
+	}
+}
+
+func TestTwilioProviderStartCall(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "AC1" || pass != "secret" {
+			t.Errorf("basic auth = %q/%q", user, pass)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/Accounts/AC1/Calls.json") {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		form = r.PostForm
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sid":"CA999"}`))
+	}))
+	defer server.Close()
+
+	p := twilioProvider{baseURL: server.URL}
+	sid, err := p.StartCall(context.Background(), Credentials{AccountID: "AC1", APIToken: "secret", CallerID: "+31201234567"},
+		"+31611111111", "+31622222222", "https://api.example.com/api/v1/webhook/telephony/token")
+	if err != nil {
+		t.Fatalf("start call: %v", err)
+	}
+	if sid != "CA999" {
+		t.Fatalf("sid = %q", sid)
+	}
+	if form.Get("To") != "+31611111111" || !strings.Contains(form.Get("Twiml"), "<Number>+31622222222</Number>") {
+		t.Fatalf("unexpected form %v", form)
+	}
+	if len(form["StatusCallbackEvent"]) != 4 {
+		t.Fatalf("status callback events = %v", form["StatusCallbackEvent"])
+	}
+}
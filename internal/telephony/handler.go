@@ -0,0 +1,134 @@
+package telephony
+
+import (
+	"io"
+	"net/http"
+
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	errOrganizationRequired = "organization required"
+	errInvalidRequest       = "invalid request"
+	errValidationFailed     = "validation failed"
+
+	maxWebhookBodyBytes = 64 << 10
+)
+
+type Handler struct {
+	svc *Service
+	val *validator.Validator
+}
+
+func NewHandler(svc *Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+func (h *Handler) ListCalls(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	if identity.TenantID() == nil {
+		httpkit.Error(c, http.StatusForbidden, errOrganizationRequired, nil)
+		return
+	}
+	var req ListCallsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, errInvalidRequest, err.Error())
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, errValidationFailed, err.Error())
+		return
+	}
+	items, err := h.svc.ListCalls(c.Request.Context(), *identity.TenantID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, gin.H{"items": items})
+}
+
+func (h *Handler) StartCall(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	if identity.TenantID() == nil {
+		httpkit.Error(c, http.StatusForbidden, errOrganizationRequired, nil)
+		return
+	}
+	var req StartCallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, errInvalidRequest, err.Error())
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, errValidationFailed, err.Error())
+		return
+	}
+	call, err := h.svc.StartCall(c.Request.Context(), *identity.TenantID(), identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, call)
+}
+
+func (h *Handler) GetSettings(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	if identity.TenantID() == nil {
+		httpkit.Error(c, http.StatusForbidden, errOrganizationRequired, nil)
+		return
+	}
+	settings, err := h.svc.GetSettings(c.Request.Context(), *identity.TenantID())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, settings)
+}
+
+func (h *Handler) UpdateSettings(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	if identity.TenantID() == nil {
+		httpkit.Error(c, http.StatusForbidden, errOrganizationRequired, nil)
+		return
+	}
+	var req UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, errInvalidRequest, err.Error())
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, errValidationFailed, err.Error())
+		return
+	}
+	settings, err := h.svc.UpdateSettings(c.Request.Context(), *identity.TenantID(), identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, settings)
+}
+
+// HandleWebhook receives the call detail records of a provider. The token in
+// the path identifies and authenticates the organization.
+// POST /api/v1/webhook/telephony/:token
+func (h *Handler) HandleWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodyBytes))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, errInvalidRequest, err.Error())
+		return
+	}
+	if httpkit.HandleError(c, h.svc.HandleWebhook(c.Request.Context(), c.Param("token"), body)) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
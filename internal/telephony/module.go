@@ -0,0 +1,44 @@
+package telephony
+
+import (
+	apphttp "portal_final_backend/internal/http"
+	leadrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Module connects a telephony provider: click-to-call from lead pages, call
+// detail records on the lead timeline and incoming call notifications.
+type Module struct {
+	handler *Handler
+	svc     *Service
+}
+
+func NewModule(pool *pgxpool.Pool, val *validator.Validator, leads leadrepo.LeadReader, timeline leadrepo.TimelineEventStore, log *logger.Logger) *Module {
+	svc := NewService(NewRepository(pool), leads, timeline, log)
+	return &Module{handler: NewHandler(svc, val), svc: svc}
+}
+
+func (m *Module) Name() string {
+	return "telephony"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	calls := ctx.Protected.Group("/telephony/calls")
+	calls.GET("", m.handler.ListCalls)
+	calls.POST("", m.handler.StartCall)
+
+	settings := ctx.Admin.Group("/telephony/settings")
+	settings.GET("", m.handler.GetSettings)
+	settings.PUT("", m.handler.UpdateSettings)
+
+	ctx.V1.POST("/webhook/telephony/:token", m.handler.HandleWebhook)
+}
+
+func (m *Module) Service() *Service {
+	return m.svc
+}
+
+var _ apphttp.Module = (*Module)(nil)
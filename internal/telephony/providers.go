@@ -0,0 +1,138 @@
+package telephony
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	twilioAPIBaseURL   = "https://api.twilio.com/2010-04-01"
+	voipgridAPIBaseURL = "https://partner.voipgrid.nl/api"
+)
+
+// Credentials are the decrypted provider credentials of an organization.
+type Credentials struct {
+	AccountID string
+	APIToken  string
+	CallerID  string
+}
+
+// Provider places click-to-call calls: it rings the agent first and connects
+// the consumer once the agent answers.
+type Provider interface {
+	StartCall(ctx context.Context, creds Credentials, agentNumber, consumerNumber, statusCallbackURL string) (string, error)
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// twilioProvider starts calls through the Twilio Voice API. The status
+// callback reports every state of the agent leg to the CDR webhook.
+type twilioProvider struct {
+	baseURL string
+}
+
+func (p twilioProvider) StartCall(ctx context.Context, creds Credentials, agentNumber, consumerNumber, statusCallbackURL string) (string, error) {
+	var dial bytes.Buffer
+	dial.WriteString(`<Response><Dial callerId="`)
+	_ = xml.EscapeText(&dial, []byte(creds.CallerID))
+	dial.WriteString(`"><Number>`)
+	_ = xml.EscapeText(&dial, []byte(consumerNumber))
+	dial.WriteString(`</Number></Dial></Response>`)
+
+	form := url.Values{}
+	form.Set("To", agentNumber)
+	form.Set("From", creds.CallerID)
+	form.Set("Twiml", dial.String())
+	if statusCallbackURL != "" {
+		form.Set("StatusCallback", statusCallbackURL)
+		for _, event := range []string{"initiated", "ringing", "answered", "completed"} {
+			form.Add("StatusCallbackEvent", event)
+		}
+	}
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Calls.json", p.baseURL, url.PathEscape(creds.AccountID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(creds.AccountID, creds.APIToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var out struct {
+		SID string `json:"sid"`
+	}
+	if err := doProviderRequest(req, &out); err != nil {
+		return "", fmt.Errorf("twilio start call: %w", err)
+	}
+	return out.SID, nil
+}
+
+// voipgridProvider starts calls through the VoIPGrid click-to-dial API.
+type voipgridProvider struct {
+	baseURL string
+}
+
+func (p voipgridProvider) StartCall(ctx context.Context, creds Credentials, agentNumber, consumerNumber, _ string) (string, error) {
+	body := map[string]any{
+		"a_number":    agentNumber,
+		"b_number":    consumerNumber,
+		"auto_answer": false,
+	}
+	if creds.CallerID != "" {
+		body["b_cli"] = creds.CallerID
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/clicktodial/", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Token "+creds.AccountID+":"+creds.APIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	var out struct {
+		CallID string `json:"callid"`
+	}
+	if err := doProviderRequest(req, &out); err != nil {
+		return "", fmt.Errorf("voipgrid start call: %w", err)
+	}
+	return out.CallID, nil
+}
+
+func doProviderRequest(req *http.Request, out any) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("provider returned status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode provider response: %w", err)
+	}
+	return nil
+}
+
+func defaultProviders() map[string]Provider {
+	return map[string]Provider{
+		ProviderTwilio:   twilioProvider{baseURL: twilioAPIBaseURL},
+		ProviderVoIPGrid: voipgridProvider{baseURL: voipgridAPIBaseURL},
+	}
+}
@@ -0,0 +1,197 @@
+package telephony
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var errSettingsNotFound = errors.New("telephony settings not found")
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+const settingsColumns = `organization_id, provider, account_id, api_token_encrypted, caller_id,
+	webhook_token_hash, webhook_token_encrypted, enabled, updated_by, updated_at`
+
+const callColumns = `id, organization_id, provider, provider_call_id, direction, status, from_number, to_number,
+	lead_id, lead_service_id, user_id, started_at, answered_at, ended_at, duration_seconds, recording_url,
+	created_at, updated_at`
+
+func scanSettings(row pgx.Row) (Settings, error) {
+	var s Settings
+	err := row.Scan(&s.OrganizationID, &s.Provider, &s.AccountID, &s.APITokenEncrypted, &s.CallerID,
+		&s.WebhookTokenHash, &s.WebhookTokenEncrypted, &s.Enabled, &s.UpdatedBy, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Settings{}, errSettingsNotFound
+	}
+	return s, err
+}
+
+func scanCall(row pgx.Row) (Call, error) {
+	var c Call
+	err := row.Scan(&c.ID, &c.OrganizationID, &c.Provider, &c.ProviderCallID, &c.Direction, &c.Status,
+		&c.FromNumber, &c.ToNumber, &c.LeadID, &c.LeadServiceID, &c.UserID, &c.StartedAt, &c.AnsweredAt,
+		&c.EndedAt, &c.DurationSeconds, &c.RecordingURL, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+func (r *Repository) getSettings(ctx context.Context, organizationID uuid.UUID) (Settings, error) {
+	s, err := scanSettings(r.pool.QueryRow(ctx, `
+		SELECT `+settingsColumns+`
+		FROM RAC_telephony_settings
+		WHERE organization_id = $1`, organizationID))
+	if err != nil && !errors.Is(err, errSettingsNotFound) {
+		return Settings{}, fmt.Errorf("get telephony settings: %w", err)
+	}
+	return s, err
+}
+
+func (r *Repository) getSettingsByWebhookTokenHash(ctx context.Context, tokenHash string) (Settings, error) {
+	s, err := scanSettings(r.pool.QueryRow(ctx, `
+		SELECT `+settingsColumns+`
+		FROM RAC_telephony_settings
+		WHERE webhook_token_hash = $1`, tokenHash))
+	if err != nil && !errors.Is(err, errSettingsNotFound) {
+		return Settings{}, fmt.Errorf("get telephony settings by webhook token: %w", err)
+	}
+	return s, err
+}
+
+func (r *Repository) upsertSettings(ctx context.Context, s Settings) (Settings, error) {
+	saved, err := scanSettings(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_telephony_settings (
+			organization_id, provider, account_id, api_token_encrypted, caller_id,
+			webhook_token_hash, webhook_token_encrypted, enabled, updated_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			provider = EXCLUDED.provider,
+			account_id = EXCLUDED.account_id,
+			api_token_encrypted = EXCLUDED.api_token_encrypted,
+			caller_id = EXCLUDED.caller_id,
+			webhook_token_hash = EXCLUDED.webhook_token_hash,
+			webhook_token_encrypted = EXCLUDED.webhook_token_encrypted,
+			enabled = EXCLUDED.enabled,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = now()
+		RETURNING `+settingsColumns,
+		s.OrganizationID, s.Provider, s.AccountID, s.APITokenEncrypted, s.CallerID,
+		s.WebhookTokenHash, s.WebhookTokenEncrypted, s.Enabled, s.UpdatedBy))
+	if err != nil {
+		return Settings{}, fmt.Errorf("upsert telephony settings: %w", err)
+	}
+	return saved, nil
+}
+
+// getUserPhone returns the phone number of a member of the organization.
+func (r *Repository) getUserPhone(ctx context.Context, organizationID, userID uuid.UUID) (string, error) {
+	var phone *string
+	err := r.pool.QueryRow(ctx, `
+		SELECT u.phone
+		FROM RAC_users u
+		JOIN RAC_organization_members m ON m.user_id = u.id
+		WHERE m.organization_id = $1 AND u.id = $2`, organizationID, userID,
+	).Scan(&phone)
+	if errors.Is(err, pgx.ErrNoRows) || phone == nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get user phone: %w", err)
+	}
+	return *phone, nil
+}
+
+func (r *Repository) createCall(ctx context.Context, c Call) (Call, error) {
+	created, err := scanCall(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_telephony_calls (
+			organization_id, provider, provider_call_id, direction, status, from_number, to_number,
+			lead_id, lead_service_id, user_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+callColumns,
+		c.OrganizationID, c.Provider, c.ProviderCallID, c.Direction, c.Status, c.FromNumber, c.ToNumber,
+		c.LeadID, c.LeadServiceID, c.UserID))
+	if err != nil {
+		return Call{}, fmt.Errorf("create telephony call: %w", err)
+	}
+	return created, nil
+}
+
+// recordCall stores a call detail record. A call seen for the first time is
+// inserted with the given lead; later records of the same call only advance
+// its status, timestamps, duration and recording. Final statuses are never
+// overwritten by late, out-of-order records.
+func (r *Repository) recordCall(ctx context.Context, organizationID uuid.UUID, provider string, rec CallRecord, leadID *uuid.UUID) (Call, error) {
+	c, err := scanCall(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_telephony_calls AS c (
+			organization_id, provider, provider_call_id, direction, status, from_number, to_number, lead_id,
+			started_at, answered_at, ended_at, duration_seconds, recording_url
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9,
+			CASE WHEN $5 IN ('answered', 'completed') THEN $9::timestamptz END,
+			CASE WHEN $5 IN ('completed', 'missed', 'busy', 'failed') THEN $9::timestamptz END,
+			$10, $11)
+		ON CONFLICT (organization_id, provider, provider_call_id) DO UPDATE SET
+			status = CASE WHEN c.status IN ('completed', 'missed', 'busy', 'failed') THEN c.status ELSE EXCLUDED.status END,
+			answered_at = COALESCE(c.answered_at, EXCLUDED.answered_at),
+			ended_at = COALESCE(c.ended_at, EXCLUDED.ended_at),
+			duration_seconds = COALESCE(EXCLUDED.duration_seconds, c.duration_seconds,
+				CASE WHEN EXCLUDED.ended_at IS NOT NULL AND c.answered_at IS NOT NULL
+					THEN EXTRACT(EPOCH FROM EXCLUDED.ended_at - c.answered_at)::int END),
+			recording_url = COALESCE(EXCLUDED.recording_url, c.recording_url),
+			lead_id = COALESCE(c.lead_id, EXCLUDED.lead_id),
+			updated_at = now()
+		RETURNING `+callColumns,
+		organizationID, provider, rec.ProviderCallID, rec.Direction, rec.Status, rec.FromNumber, rec.ToNumber, leadID,
+		rec.OccurredAt, rec.DurationSeconds, rec.RecordingURL))
+	if err != nil {
+		return Call{}, fmt.Errorf("record telephony call: %w", err)
+	}
+	return c, nil
+}
+
+// claimTimelineLog marks an ended call as written to the lead timeline. It
+// returns false when another delivery of the record already did.
+func (r *Repository) claimTimelineLog(ctx context.Context, callID uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_telephony_calls
+		SET timeline_logged = true
+		WHERE id = $1 AND timeline_logged = false AND lead_id IS NOT NULL`, callID)
+	if err != nil {
+		return false, fmt.Errorf("claim telephony call timeline log: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *Repository) listCalls(ctx context.Context, organizationID uuid.UUID, leadID *uuid.UUID, limit int) ([]Call, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+callColumns+`
+		FROM RAC_telephony_calls
+		WHERE organization_id = $1 AND ($2::uuid IS NULL OR lead_id = $2)
+		ORDER BY started_at DESC
+		LIMIT $3`, organizationID, leadID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list telephony calls: %w", err)
+	}
+	defer rows.Close()
+
+	calls := make([]Call, 0)
+	for rows.Next() {
+		c, err := scanCall(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan telephony call: %w", err)
+		}
+		calls = append(calls, c)
+	}
+	return calls, rows.Err()
+}
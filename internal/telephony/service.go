@@ -0,0 +1,449 @@
+package telephony
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	leadrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/notification/sse"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/phone"
+	"portal_final_backend/platform/secrets"
+
+	"github.com/google/uuid"
+)
+
+const (
+	errNotConfigured      = "telephony integration is not configured"
+	errSettingsMissing    = "telephony is not set up for this organization"
+	errTelephonyDisabled  = "telephony is disabled for this organization"
+	errUserPhoneRequired  = "add your phone number to your profile to place calls"
+	errLeadPhoneMissing   = "lead has no phone number"
+	errAPITokenRequired   = "apiToken is required"
+	errInvalidWebhookAuth = "invalid webhook token"
+
+	defaultCallListLimit = 50
+	webhookPath          = "/api/v1/webhook/telephony/"
+)
+
+type Service struct {
+	repo             *Repository
+	leads            leadrepo.LeadReader
+	timeline         leadrepo.TimelineEventStore
+	sse              *sse.Service
+	keyring          *secrets.Keyring
+	providers        map[string]Provider
+	publicAPIBaseURL string
+	log              *logger.Logger
+}
+
+func NewService(repo *Repository, leads leadrepo.LeadReader, timeline leadrepo.TimelineEventStore, log *logger.Logger) *Service {
+	return &Service{
+		repo:      repo,
+		leads:     leads,
+		timeline:  timeline,
+		providers: defaultProviders(),
+		log:       log,
+	}
+}
+
+// SetSSE enables incoming call and call update notifications.
+func (s *Service) SetSSE(sseService *sse.Service) {
+	s.sse = sseService
+}
+
+// SetKeyring sets the keyring that encrypts provider credentials. The
+// integration stays disabled without it.
+func (s *Service) SetKeyring(keyring *secrets.Keyring) {
+	s.keyring = keyring
+}
+
+// SetPublicAPIBaseURL sets the base URL providers reach the CDR webhook on.
+func (s *Service) SetPublicAPIBaseURL(baseURL string) {
+	s.publicAPIBaseURL = strings.TrimRight(baseURL, "/")
+}
+
+func (s *Service) GetSettings(ctx context.Context, organizationID uuid.UUID) (SettingsResponse, error) {
+	settings, err := s.repo.getSettings(ctx, organizationID)
+	if errors.Is(err, errSettingsNotFound) {
+		return SettingsResponse{Configured: false}, nil
+	}
+	if err != nil {
+		return SettingsResponse{}, err
+	}
+	return s.toSettingsResponse(settings), nil
+}
+
+// UpdateSettings stores the provider of an organization. The API token is
+// kept when omitted; a webhook token is generated on first save and when
+// rotation is requested.
+func (s *Service) UpdateSettings(ctx context.Context, organizationID, actorID uuid.UUID, req UpdateSettingsRequest) (SettingsResponse, error) {
+	if s.keyring == nil {
+		return SettingsResponse{}, apperr.BadRequest(errNotConfigured)
+	}
+
+	current, err := s.repo.getSettings(ctx, organizationID)
+	exists := err == nil
+	if err != nil && !errors.Is(err, errSettingsNotFound) {
+		return SettingsResponse{}, err
+	}
+
+	next := Settings{
+		OrganizationID:        organizationID,
+		Provider:              req.Provider,
+		AccountID:             strings.TrimSpace(req.AccountID),
+		APITokenEncrypted:     current.APITokenEncrypted,
+		CallerID:              normalizeCallerID(req.CallerID),
+		WebhookTokenHash:      current.WebhookTokenHash,
+		WebhookTokenEncrypted: current.WebhookTokenEncrypted,
+		Enabled:               req.Enabled,
+		UpdatedBy:             &actorID,
+	}
+
+	if token := strings.TrimSpace(req.APIToken); token != "" {
+		if next.APITokenEncrypted, err = s.keyring.Encrypt(token); err != nil {
+			return SettingsResponse{}, fmt.Errorf("encrypt telephony api token: %w", err)
+		}
+	} else if !exists {
+		return SettingsResponse{}, apperr.Validation(errAPITokenRequired)
+	}
+
+	if !exists || req.RotateWebhookToken {
+		token, err := generateWebhookToken()
+		if err != nil {
+			return SettingsResponse{}, err
+		}
+		next.WebhookTokenHash = hashWebhookToken(token)
+		if next.WebhookTokenEncrypted, err = s.keyring.Encrypt(token); err != nil {
+			return SettingsResponse{}, fmt.Errorf("encrypt telephony webhook token: %w", err)
+		}
+	}
+
+	saved, err := s.repo.upsertSettings(ctx, next)
+	if err != nil {
+		return SettingsResponse{}, err
+	}
+	return s.toSettingsResponse(saved), nil
+}
+
+func (s *Service) toSettingsResponse(settings Settings) SettingsResponse {
+	updatedAt := settings.UpdatedAt
+	resp := SettingsResponse{
+		Configured: true,
+		Provider:   settings.Provider,
+		AccountID:  settings.AccountID,
+		CallerID:   settings.CallerID,
+		Enabled:    settings.Enabled,
+		UpdatedAt:  &updatedAt,
+	}
+	if webhookURL := s.webhookURL(settings); webhookURL != "" {
+		resp.WebhookURL = &webhookURL
+	}
+	return resp
+}
+
+// webhookURL returns the CDR webhook of an organization, or "" when the
+// token cannot be decrypted.
+func (s *Service) webhookURL(settings Settings) string {
+	if s.keyring == nil || s.publicAPIBaseURL == "" {
+		return ""
+	}
+	token, err := s.keyring.Decrypt(settings.WebhookTokenEncrypted)
+	if err != nil {
+		s.log.Warn("failed to decrypt telephony webhook token", "organizationId", settings.OrganizationID, "error", err)
+		return ""
+	}
+	return s.publicAPIBaseURL + webhookPath + token
+}
+
+// StartCall places a click-to-call call: the provider rings the phone of the
+// agent and connects the lead once the agent answers.
+func (s *Service) StartCall(ctx context.Context, organizationID, userID uuid.UUID, req StartCallRequest) (Call, error) {
+	settings, err := s.enabledSettings(ctx, organizationID)
+	if err != nil {
+		return Call{}, err
+	}
+	provider, ok := s.providers[settings.Provider]
+	if !ok {
+		return Call{}, apperr.BadRequest(errNotConfigured)
+	}
+
+	agentPhone, err := s.repo.getUserPhone(ctx, organizationID, userID)
+	if err != nil {
+		return Call{}, err
+	}
+	agentNumber := phone.NormalizeE164(agentPhone)
+	if agentNumber == "" {
+		return Call{}, apperr.Validation(errUserPhoneRequired)
+	}
+
+	leadID := uuid.MustParse(req.LeadID)
+	lead, err := s.leads.GetByID(ctx, leadID, organizationID)
+	if errors.Is(err, leadrepo.ErrNotFound) {
+		return Call{}, apperr.NotFound("lead not found")
+	}
+	if err != nil {
+		return Call{}, err
+	}
+	consumerNumber := phone.NormalizeE164(lead.ConsumerPhone)
+	if consumerNumber == "" {
+		return Call{}, apperr.Validation(errLeadPhoneMissing)
+	}
+
+	creds, err := s.credentials(settings)
+	if err != nil {
+		return Call{}, err
+	}
+	providerCallID, err := provider.StartCall(ctx, creds, agentNumber, consumerNumber, s.webhookURL(settings))
+	if err != nil {
+		s.log.Error("failed to start telephony call", "organizationId", organizationID, "provider", settings.Provider, "error", err)
+		return Call{}, apperr.BadRequest("the telephony provider could not start the call")
+	}
+
+	call := Call{
+		OrganizationID: organizationID,
+		Provider:       settings.Provider,
+		ProviderCallID: providerCallID,
+		Direction:      DirectionOutbound,
+		Status:         StatusInitiated,
+		FromNumber:     agentNumber,
+		ToNumber:       consumerNumber,
+		LeadID:         &leadID,
+		UserID:         &userID,
+	}
+	if req.LeadServiceID != "" {
+		serviceID := uuid.MustParse(req.LeadServiceID)
+		call.LeadServiceID = &serviceID
+	}
+	created, err := s.repo.createCall(ctx, call)
+	if err != nil {
+		return Call{}, err
+	}
+	s.publishCallEvent(sse.EventCallUpdated, created, nil)
+	return created, nil
+}
+
+func (s *Service) ListCalls(ctx context.Context, organizationID uuid.UUID, req ListCallsRequest) ([]Call, error) {
+	var leadID *uuid.UUID
+	if req.LeadID != "" {
+		id := uuid.MustParse(req.LeadID)
+		leadID = &id
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultCallListLimit
+	}
+	return s.repo.listCalls(ctx, organizationID, leadID, limit)
+}
+
+// HandleWebhook stores a call detail record posted to the webhook of an
+// organization. It matches the remote number to a lead, announces ringing
+// inbound calls and writes ended calls to the lead timeline once.
+func (s *Service) HandleWebhook(ctx context.Context, token string, body []byte) error {
+	settings, err := s.settingsForWebhook(ctx, token)
+	if err != nil {
+		return err
+	}
+	// The token identified the organization; its leads and timeline may live
+	// in a dedicated schema.
+	if ctx, err = httpkit.BindTenant(ctx, settings.OrganizationID); err != nil {
+		return fmt.Errorf("bind organization: %w", err)
+	}
+	rec, err := parseCallRecord(settings.Provider, body, time.Now())
+	if err != nil {
+		return apperr.BadRequest(err.Error())
+	}
+
+	var lead *leadrepo.Lead
+	if remote := rec.remoteNumber(); remote != "" {
+		found, err := s.leads.GetByPhone(ctx, remote, settings.OrganizationID)
+		switch {
+		case err == nil:
+			lead = &found
+		case !errors.Is(err, leadrepo.ErrNotFound):
+			return err
+		}
+	}
+	var leadID *uuid.UUID
+	if lead != nil {
+		leadID = &lead.ID
+	}
+
+	call, err := s.repo.recordCall(ctx, settings.OrganizationID, settings.Provider, rec, leadID)
+	if err != nil {
+		return err
+	}
+
+	if call.Direction == DirectionInbound && rec.Status == StatusRinging && !call.IsFinal() {
+		s.publishCallEvent(sse.EventIncomingCall, call, lead)
+	}
+	s.publishCallEvent(sse.EventCallUpdated, call, lead)
+
+	if call.IsFinal() && call.LeadID != nil {
+		s.logToTimeline(ctx, call)
+	}
+	return nil
+}
+
+func (s *Service) settingsForWebhook(ctx context.Context, token string) (Settings, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return Settings{}, apperr.Unauthorized(errInvalidWebhookAuth)
+	}
+	settings, err := s.repo.getSettingsByWebhookTokenHash(ctx, hashWebhookToken(token))
+	if errors.Is(err, errSettingsNotFound) {
+		return Settings{}, apperr.Unauthorized(errInvalidWebhookAuth)
+	}
+	if err != nil {
+		return Settings{}, err
+	}
+	if !settings.Enabled {
+		return Settings{}, apperr.Forbidden(errTelephonyDisabled)
+	}
+	return settings, nil
+}
+
+func (s *Service) enabledSettings(ctx context.Context, organizationID uuid.UUID) (Settings, error) {
+	if s.keyring == nil {
+		return Settings{}, apperr.BadRequest(errNotConfigured)
+	}
+	settings, err := s.repo.getSettings(ctx, organizationID)
+	if errors.Is(err, errSettingsNotFound) {
+		return Settings{}, apperr.BadRequest(errSettingsMissing)
+	}
+	if err != nil {
+		return Settings{}, err
+	}
+	if !settings.Enabled {
+		return Settings{}, apperr.Forbidden(errTelephonyDisabled)
+	}
+	return settings, nil
+}
+
+func (s *Service) credentials(settings Settings) (Credentials, error) {
+	token, err := s.keyring.Decrypt(settings.APITokenEncrypted)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("decrypt telephony api token: %w", err)
+	}
+	creds := Credentials{AccountID: settings.AccountID, APIToken: token}
+	if settings.CallerID != nil {
+		creds.CallerID = *settings.CallerID
+	}
+	return creds, nil
+}
+
+// logToTimeline writes an ended call to the timeline of its lead. Providers
+// may deliver the final record more than once; only the first is logged.
+func (s *Service) logToTimeline(ctx context.Context, call Call) {
+	claimed, err := s.repo.claimTimelineLog(ctx, call.ID)
+	if err != nil {
+		s.log.Error("failed to claim telephony call timeline log", "callId", call.ID, "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+	_, err = s.timeline.CreateTimelineEvent(ctx, leadrepo.CreateTimelineEventParams{
+		LeadID:         *call.LeadID,
+		ServiceID:      call.LeadServiceID,
+		OrganizationID: call.OrganizationID,
+		ActorType:      leadrepo.ActorTypeSystem,
+		ActorName:      "Telefonie",
+		EventType:      leadrepo.EventTypeCallRecord,
+		Title:          leadrepo.EventTitleCallRecord,
+		Summary:        leadrepo.TruncateSummary(callSummary(call), leadrepo.TimelineSummaryMaxLen),
+		Metadata: leadrepo.CallRecordMetadata{
+			CallID:          call.ID.String(),
+			Provider:        call.Provider,
+			Direction:       call.Direction,
+			Status:          call.Status,
+			DurationSeconds: call.DurationSeconds,
+			RecordingURL:    call.RecordingURL,
+		}.ToMap(),
+		Visibility: leadrepo.TimelineVisibilityInternal,
+	})
+	if err != nil {
+		s.log.Error("failed to write telephony call to timeline", "callId", call.ID, "leadId", *call.LeadID, "error", err)
+	}
+}
+
+// callSummary describes an ended call for the lead timeline.
+func callSummary(call Call) string {
+	direction := "Uitgaand gesprek"
+	if call.Direction == DirectionInbound {
+		direction = "Inkomend gesprek"
+	}
+	switch call.Status {
+	case StatusCompleted:
+		if call.DurationSeconds != nil {
+			return fmt.Sprintf("%s, duur %s", direction, formatDuration(*call.DurationSeconds))
+		}
+		return direction + ", beantwoord"
+	case StatusMissed:
+		return direction + ", niet beantwoord"
+	case StatusBusy:
+		return direction + ", in gesprek"
+	default:
+		return direction + ", mislukt"
+	}
+}
+
+func formatDuration(seconds int) string {
+	d := time.Duration(seconds) * time.Second
+	minutes := int(d / time.Minute)
+	return fmt.Sprintf("%d:%02d", minutes, int((d%time.Minute)/time.Second))
+}
+
+// callEventData is the SSE payload of a call, with the name of the matched
+// lead for incoming call notifications.
+type callEventData struct {
+	Call
+	LeadName string `json:"leadName,omitempty"`
+}
+
+func (s *Service) publishCallEvent(eventType sse.EventType, call Call, lead *leadrepo.Lead) {
+	if s.sse == nil {
+		return
+	}
+	data := callEventData{Call: call}
+	if lead != nil {
+		data.LeadName = strings.TrimSpace(lead.ConsumerFirstName + " " + lead.ConsumerLastName)
+	}
+	event := sse.Event{Type: eventType, Data: data}
+	if call.LeadID != nil {
+		event.LeadID = *call.LeadID
+	}
+	if call.LeadServiceID != nil {
+		event.ServiceID = *call.LeadServiceID
+	}
+	s.sse.PublishToOrganization(call.OrganizationID, event)
+}
+
+func normalizeCallerID(value string) *string {
+	normalized := phone.NormalizeE164(value)
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+func generateWebhookToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate telephony webhook token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashWebhookToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,115 @@
+package telephony
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ProviderTwilio   = "twilio"
+	ProviderVoIPGrid = "voipgrid"
+
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+
+	StatusInitiated = "initiated"
+	StatusRinging   = "ringing"
+	StatusAnswered  = "answered"
+	StatusCompleted = "completed"
+	StatusMissed    = "missed"
+	StatusBusy      = "busy"
+	StatusFailed    = "failed"
+)
+
+// Settings is the telephony provider of an organization.
+type Settings struct {
+	OrganizationID        uuid.UUID
+	Provider              string
+	AccountID             string
+	APITokenEncrypted     string
+	CallerID              *string
+	WebhookTokenHash      string
+	WebhookTokenEncrypted string
+	Enabled               bool
+	UpdatedBy             *uuid.UUID
+	UpdatedAt             time.Time
+}
+
+type Call struct {
+	ID              uuid.UUID  `json:"id"`
+	OrganizationID  uuid.UUID  `json:"organizationId"`
+	Provider        string     `json:"provider"`
+	ProviderCallID  string     `json:"providerCallId"`
+	Direction       string     `json:"direction"`
+	Status          string     `json:"status"`
+	FromNumber      string     `json:"fromNumber"`
+	ToNumber        string     `json:"toNumber"`
+	LeadID          *uuid.UUID `json:"leadId,omitempty"`
+	LeadServiceID   *uuid.UUID `json:"leadServiceId,omitempty"`
+	UserID          *uuid.UUID `json:"userId,omitempty"`
+	StartedAt       time.Time  `json:"startedAt"`
+	AnsweredAt      *time.Time `json:"answeredAt,omitempty"`
+	EndedAt         *time.Time `json:"endedAt,omitempty"`
+	DurationSeconds *int       `json:"durationSeconds,omitempty"`
+	RecordingURL    *string    `json:"recordingUrl,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// IsFinal reports whether the call has ended.
+func (c Call) IsFinal() bool {
+	return isFinalStatus(c.Status)
+}
+
+func isFinalStatus(status string) bool {
+	switch status {
+	case StatusCompleted, StatusMissed, StatusBusy, StatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+type UpdateSettingsRequest struct {
+	Provider           string `json:"provider" validate:"required,oneof=twilio voipgrid"`
+	AccountID          string `json:"accountId" validate:"required,max=200"`
+	APIToken           string `json:"apiToken" validate:"omitempty,max=500"`
+	CallerID           string `json:"callerId" validate:"omitempty,max=32"`
+	Enabled            bool   `json:"enabled"`
+	RotateWebhookToken bool   `json:"rotateWebhookToken"`
+}
+
+type SettingsResponse struct {
+	Configured bool       `json:"configured"`
+	Provider   string     `json:"provider,omitempty"`
+	AccountID  string     `json:"accountId,omitempty"`
+	CallerID   *string    `json:"callerId,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	// WebhookURL is the CDR webhook to configure at the provider.
+	WebhookURL *string `json:"webhookUrl,omitempty"`
+}
+
+type StartCallRequest struct {
+	LeadID        string `json:"leadId" validate:"required,uuid4"`
+	LeadServiceID string `json:"leadServiceId" validate:"omitempty,uuid4"`
+}
+
+type ListCallsRequest struct {
+	LeadID string `form:"leadId" validate:"omitempty,uuid4"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// CallRecord is a call detail record reported by a provider, normalized
+// across providers.
+type CallRecord struct {
+	ProviderCallID  string
+	Direction       string
+	Status          string
+	FromNumber      string
+	ToNumber        string
+	OccurredAt      time.Time
+	DurationSeconds *int
+	RecordingURL    *string
+}
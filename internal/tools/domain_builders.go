@@ -27,6 +27,10 @@ func NewUpdateLeadServiceTypeTool[In any, Out any](handler func(tool.Context, In
 	return newDomainTool("UpdateLeadServiceType", "Updates the service type for a lead service when there is a confident mismatch. The service type must match an active service type name or slug.", handler)
 }
 
+func NewRememberLeadFactTool[In any, Out any](handler func(tool.Context, In) (Out, error)) (tool.Tool, error) {
+	return newDomainTool("RememberLeadFact", "Remembers a distilled fact about this lead service for future agent runs. Use it for durable facts confirmed in this run: a measured or confirmed dimension (kind=dimension), a customer preference (kind=preference), an option the customer rejected (kind=rejected_option) or another fact worth keeping (kind=fact). Keep each fact short and self-contained; do not repeat facts already listed in the lead memory.", handler)
+}
+
 func NewUpdateLeadDetailsTool[In any, Out any](description string, handler func(tool.Context, In) (Out, error)) (tool.Tool, error) {
 	return newDomainTool("UpdateLeadDetails", description, handler)
 }
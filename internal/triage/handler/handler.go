@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"portal_final_backend/internal/triage/service"
+	"portal_final_backend/internal/triage/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+// RegisterRoutes registers working off the triage inbox of the organization.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.List)
+	rg.GET("/metrics", h.Metrics)
+	rg.GET("/:id", h.Get)
+	rg.POST("/:id/claim", h.Claim)
+	rg.POST("/:id/release", h.Release)
+	rg.PUT("/:id/assignee", h.Assign)
+	rg.POST("/:id/resolve", h.Resolve)
+}
+
+func (h *Handler) List(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListItemsRequest](c, h.val)
+	if !ok {
+		return
+	}
+	identity := httpkit.MustGetIdentity(c)
+
+	resp, err := h.svc.List(c.Request.Context(), tenantID, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) Metrics(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.Metrics(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.Get(c.Request.Context(), tenantID, id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) Claim(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	identity := httpkit.MustGetIdentity(c)
+
+	resp, err := h.svc.Claim(c.Request.Context(), tenantID, id, identity.UserID())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) Release(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	identity := httpkit.MustGetIdentity(c)
+
+	resp, err := h.svc.Release(c.Request.Context(), tenantID, id, identity.UserID())
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) Assign(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.AssignItemRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.Assign(c.Request.Context(), tenantID, id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) Resolve(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.ResolveItemRequest](c, h.val)
+	if !ok {
+		return
+	}
+	identity := httpkit.MustGetIdentity(c)
+
+	resp, err := h.svc.Resolve(c.Request.Context(), tenantID, id, identity.UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
@@ -0,0 +1,75 @@
+// Package triage is the priority inbox of leads that need a human: blocked
+// agent runs, failed enrichments, undeliverable messages and missed SLAs.
+// Items are raised from domain events and worked off by claiming, assigning
+// and resolving them.
+package triage
+
+import (
+	"context"
+
+	"portal_final_backend/internal/events"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/internal/triage/handler"
+	"portal_final_backend/internal/triage/repository"
+	"portal_final_backend/internal/triage/service"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+	log     *logger.Logger
+}
+
+func NewModule(pool *pgxpool.Pool, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+		log:     log,
+	}
+}
+
+// Service returns the triage service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "triage"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterRoutes(ctx.Protected.Group("/triage"))
+}
+
+// RegisterHandlers subscribes the module to the events that raise items.
+func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
+	bus.Subscribe(events.ManualInterventionRequired{}.EventName(), m)
+	bus.Subscribe(events.LeadStale{}.EventName(), m)
+	bus.Subscribe(events.LeadEnrichmentFailed{}.EventName(), m)
+}
+
+// Handle processes subscribed domain events.
+func (m *Module) Handle(ctx context.Context, event events.Event) error {
+	var err error
+	switch e := event.(type) {
+	case events.ManualInterventionRequired:
+		err = m.service.RaiseManualIntervention(ctx, e)
+	case events.LeadStale:
+		err = m.service.RaiseSLABreach(ctx, e)
+	case events.LeadEnrichmentFailed:
+		err = m.service.RaiseEnrichmentFailed(ctx, e)
+	default:
+		return nil
+	}
+	if err != nil {
+		m.log.Error("failed to raise triage item", "event", event.EventName(), "error", err)
+	}
+	return err
+}
+
+var _ apphttp.Module = (*Module)(nil)
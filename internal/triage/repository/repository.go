@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Kinds of triage items, by what needs a human.
+const (
+	KindManualIntervention   = "manual_intervention"
+	KindAgentRunBlocked      = "agent_run_blocked"
+	KindEnrichmentFailed     = "enrichment_failed"
+	KindMessageUndeliverable = "message_undeliverable"
+	KindSLABreach            = "sla_breach"
+)
+
+// Triage item statuses.
+const (
+	StatusOpen     = "open"
+	StatusClaimed  = "claimed"
+	StatusResolved = "resolved"
+)
+
+var ErrNotFound = errors.New("triage item not found")
+
+// Repository persists the triage inbox.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Item is a lead waiting for a human to act on it.
+type Item struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	LeadServiceID  *uuid.UUID
+	LeadName       string
+	Kind           string
+	Priority       int
+	Reason         string
+	ReasonCode     *string
+	Context        *string
+	Status         string
+	AssigneeID     *uuid.UUID
+	ClaimedBy      *uuid.UUID
+	ClaimedAt      *time.Time
+	ResolvedBy     *uuid.UUID
+	ResolvedAt     *time.Time
+	ResolutionNote *string
+	Occurrences    int
+	LastRaisedAt   time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// RaiseParams describes why a lead needs a human.
+type RaiseParams struct {
+	OrganizationID uuid.UUID
+	LeadID         uuid.UUID
+	LeadServiceID  *uuid.UUID
+	Kind           string
+	Priority       int
+	Reason         string
+	ReasonCode     string
+	Context        string
+}
+
+// ListParams filters the triage inbox of an organization. An empty status
+// lists the unresolved items.
+type ListParams struct {
+	OrganizationID uuid.UUID
+	Status         string
+	Kind           string
+	AssigneeID     *uuid.UUID
+	Unassigned     bool
+	LeadID         *uuid.UUID
+	Limit          int
+	Offset         int
+}
+
+const itemColumns = `i.id, i.organization_id, i.lead_id, i.lead_service_id,
+	COALESCE(TRIM(l.consumer_first_name || ' ' || l.consumer_last_name), ''),
+	i.kind, i.priority, i.reason, i.reason_code, i.context, i.status, i.assignee_id, i.claimed_by, i.claimed_at,
+	i.resolved_by, i.resolved_at, i.resolution_note, i.occurrences, i.last_raised_at, i.created_at, i.updated_at`
+
+func scanItem(row pgx.Row, extra ...any) (Item, error) {
+	var it Item
+	dest := append([]any{&it.ID, &it.OrganizationID, &it.LeadID, &it.LeadServiceID, &it.LeadName,
+		&it.Kind, &it.Priority, &it.Reason, &it.ReasonCode, &it.Context, &it.Status, &it.AssigneeID, &it.ClaimedBy, &it.ClaimedAt,
+		&it.ResolvedBy, &it.ResolvedAt, &it.ResolutionNote, &it.Occurrences, &it.LastRaisedAt, &it.CreatedAt, &it.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Item{}, ErrNotFound
+		}
+		return Item{}, err
+	}
+	return it, nil
+}
+
+// changedItem selects the item changed by the statement in the CTE named
+// changed, with the name of its lead.
+const changedItem = `
+	SELECT ` + itemColumns + `
+	FROM changed i
+	JOIN RAC_leads l ON l.id = i.lead_id`
+
+// Raise opens a triage item for the lead. When the lead already has an
+// unresolved item of the kind, that item takes the new reason and counts the
+// occurrence instead. It returns true when a new item was opened.
+func (r *Repository) Raise(ctx context.Context, p RaiseParams) (Item, bool, error) {
+	var inserted bool
+	it, err := scanItem(r.pool.QueryRow(ctx, `
+		WITH changed AS (
+			INSERT INTO RAC_lead_triage_items AS t (
+				organization_id, lead_id, lead_service_id, kind, priority, reason, reason_code, context
+			)
+			SELECT $1, l.id, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, '')
+			FROM RAC_leads l
+			WHERE l.id = $2 AND l.organization_id = $1
+			ON CONFLICT (lead_id, kind) WHERE status <> 'resolved' DO UPDATE SET
+				lead_service_id = COALESCE(EXCLUDED.lead_service_id, t.lead_service_id),
+				reason = EXCLUDED.reason,
+				reason_code = EXCLUDED.reason_code,
+				context = EXCLUDED.context,
+				occurrences = t.occurrences + 1,
+				last_raised_at = now(),
+				updated_at = now()
+			RETURNING t.*, (xmax = 0) AS inserted
+		)
+		SELECT `+itemColumns+`, i.inserted
+		FROM changed i
+		JOIN RAC_leads l ON l.id = i.lead_id`,
+		p.OrganizationID, p.LeadID, p.LeadServiceID, p.Kind, p.Priority, p.Reason, p.ReasonCode, p.Context,
+	), &inserted)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Item{}, false, err
+		}
+		return Item{}, false, fmt.Errorf("raise triage item: %w", err)
+	}
+	return it, inserted, nil
+}
+
+func (r *Repository) Get(ctx context.Context, id, organizationID uuid.UUID) (Item, error) {
+	return scanItem(r.pool.QueryRow(ctx, `
+		SELECT `+itemColumns+`
+		FROM RAC_lead_triage_items i
+		JOIN RAC_leads l ON l.id = i.lead_id
+		WHERE i.id = $1 AND i.organization_id = $2`, id, organizationID,
+	))
+}
+
+// List returns the triage inbox of an organization, most urgent and then
+// longest waiting first, and the total number of matches.
+func (r *Repository) List(ctx context.Context, p ListParams) ([]Item, int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+itemColumns+`, count(*) OVER ()
+		FROM RAC_lead_triage_items i
+		JOIN RAC_leads l ON l.id = i.lead_id
+		WHERE i.organization_id = $1
+			AND (($2 = '' AND i.status <> 'resolved') OR i.status = $2)
+			AND ($3 = '' OR i.kind = $3)
+			AND ($4::uuid IS NULL OR i.assignee_id = $4)
+			AND (NOT $5 OR i.assignee_id IS NULL)
+			AND ($6::uuid IS NULL OR i.lead_id = $6)
+		ORDER BY i.priority ASC, i.created_at ASC
+		LIMIT $7 OFFSET $8`,
+		p.OrganizationID, p.Status, p.Kind, p.AssigneeID, p.Unassigned, p.LeadID, p.Limit, p.Offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list triage items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Item, 0)
+	total := 0
+	for rows.Next() {
+		it, err := scanItem(rows, &total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan triage item: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, total, rows.Err()
+}
+
+// Claim makes the user work on an open item, and its assignee when it had
+// none. It returns ErrNotFound when the item is not open.
+func (r *Repository) Claim(ctx context.Context, id, organizationID, userID uuid.UUID) (Item, error) {
+	return scanItem(r.pool.QueryRow(ctx, `
+		WITH changed AS (
+			UPDATE RAC_lead_triage_items
+			SET status = 'claimed', claimed_by = $3, claimed_at = now(),
+				assignee_id = COALESCE(assignee_id, $3), updated_at = now()
+			WHERE id = $1 AND organization_id = $2 AND status = 'open'
+			RETURNING *
+		)`+changedItem, id, organizationID, userID,
+	))
+}
+
+// Release puts an item claimed by the user back in the open queue. It returns
+// ErrNotFound when the user did not claim the item.
+func (r *Repository) Release(ctx context.Context, id, organizationID, userID uuid.UUID) (Item, error) {
+	return scanItem(r.pool.QueryRow(ctx, `
+		WITH changed AS (
+			UPDATE RAC_lead_triage_items
+			SET status = 'open', claimed_by = NULL, claimed_at = NULL, updated_at = now()
+			WHERE id = $1 AND organization_id = $2 AND status = 'claimed' AND claimed_by = $3
+			RETURNING *
+		)`+changedItem, id, organizationID, userID,
+	))
+}
+
+// Assign sets or clears the assignee of an unresolved item. It returns
+// ErrNotFound when the item is resolved.
+func (r *Repository) Assign(ctx context.Context, id, organizationID uuid.UUID, assigneeID *uuid.UUID) (Item, error) {
+	return scanItem(r.pool.QueryRow(ctx, `
+		WITH changed AS (
+			UPDATE RAC_lead_triage_items
+			SET assignee_id = $3, updated_at = now()
+			WHERE id = $1 AND organization_id = $2 AND status <> 'resolved'
+			RETURNING *
+		)`+changedItem, id, organizationID, assigneeID,
+	))
+}
+
+// Resolve closes an unresolved item. It returns ErrNotFound when the item is
+// resolved already.
+func (r *Repository) Resolve(ctx context.Context, id, organizationID, userID uuid.UUID, note *string) (Item, error) {
+	return scanItem(r.pool.QueryRow(ctx, `
+		WITH changed AS (
+			UPDATE RAC_lead_triage_items
+			SET status = 'resolved', resolved_by = $3, resolved_at = now(), resolution_note = $4, updated_at = now()
+			WHERE id = $1 AND organization_id = $2 AND status <> 'resolved'
+			RETURNING *
+		)`+changedItem, id, organizationID, userID, note,
+	))
+}
+
+// IsOrganizationMember reports whether the user belongs to the organization.
+func (r *Repository) IsOrganizationMember(ctx context.Context, organizationID, userID uuid.UUID) (bool, error) {
+	var member bool
+	if err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM RAC_organization_members
+			WHERE organization_id = $1 AND user_id = $2
+		)`, organizationID, userID,
+	).Scan(&member); err != nil {
+		return false, fmt.Errorf("check organization member: %w", err)
+	}
+	return member, nil
+}
+
+// KindStats counts the unresolved items of a kind.
+type KindStats struct {
+	Kind         string
+	Open         int
+	Claimed      int
+	Unassigned   int
+	OldestOpenAt *time.Time
+}
+
+// AgingStats counts the unresolved items by how long they have been waiting.
+type AgingStats struct {
+	UnderHour   int
+	UnderDay    int
+	UnderWeek   int
+	OverWeek    int
+	AverageWait float64
+}
+
+// ResolutionStats describes the items resolved since a moment.
+type ResolutionStats struct {
+	Resolved           int
+	AverageResolveSecs float64
+	AverageClaimSecs   float64
+}
+
+// Metrics are the aging figures of the triage inbox of an organization.
+type Metrics struct {
+	Kinds      []KindStats
+	Aging      AgingStats
+	Resolution ResolutionStats
+}
+
+// Metrics returns the unresolved items by kind and age at the moment now, and
+// how fast items resolved since the moment since were handled.
+func (r *Repository) Metrics(ctx context.Context, organizationID uuid.UUID, now, since time.Time) (Metrics, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT kind,
+			count(*) FILTER (WHERE status = 'open'),
+			count(*) FILTER (WHERE status = 'claimed'),
+			count(*) FILTER (WHERE assignee_id IS NULL),
+			min(created_at)
+		FROM RAC_lead_triage_items
+		WHERE organization_id = $1 AND status <> 'resolved'
+		GROUP BY kind
+		ORDER BY kind`, organizationID,
+	)
+	if err != nil {
+		return Metrics{}, fmt.Errorf("count triage items: %w", err)
+	}
+	defer rows.Close()
+
+	m := Metrics{Kinds: make([]KindStats, 0)}
+	for rows.Next() {
+		var k KindStats
+		if err := rows.Scan(&k.Kind, &k.Open, &k.Claimed, &k.Unassigned, &k.OldestOpenAt); err != nil {
+			return Metrics{}, fmt.Errorf("scan triage kind stats: %w", err)
+		}
+		m.Kinds = append(m.Kinds, k)
+	}
+	if err := rows.Err(); err != nil {
+		return Metrics{}, err
+	}
+
+	if err := r.pool.QueryRow(ctx, `
+		SELECT
+			count(*) FILTER (WHERE created_at > $2::timestamptz - interval '1 hour'),
+			count(*) FILTER (WHERE created_at <= $2::timestamptz - interval '1 hour' AND created_at > $2::timestamptz - interval '1 day'),
+			count(*) FILTER (WHERE created_at <= $2::timestamptz - interval '1 day' AND created_at > $2::timestamptz - interval '7 days'),
+			count(*) FILTER (WHERE created_at <= $2::timestamptz - interval '7 days'),
+			COALESCE(avg(EXTRACT(EPOCH FROM ($2::timestamptz - created_at))), 0)
+		FROM RAC_lead_triage_items
+		WHERE organization_id = $1 AND status <> 'resolved'`, organizationID, now,
+	).Scan(&m.Aging.UnderHour, &m.Aging.UnderDay, &m.Aging.UnderWeek, &m.Aging.OverWeek, &m.Aging.AverageWait); err != nil {
+		return Metrics{}, fmt.Errorf("age triage items: %w", err)
+	}
+
+	if err := r.pool.QueryRow(ctx, `
+		SELECT count(*),
+			COALESCE(avg(EXTRACT(EPOCH FROM (resolved_at - created_at))), 0),
+			COALESCE(avg(EXTRACT(EPOCH FROM (claimed_at - created_at))) FILTER (WHERE claimed_at IS NOT NULL), 0)
+		FROM RAC_lead_triage_items
+		WHERE organization_id = $1 AND status = 'resolved' AND resolved_at >= $2`, organizationID, since,
+	).Scan(&m.Resolution.Resolved, &m.Resolution.AverageResolveSecs, &m.Resolution.AverageClaimSecs); err != nil {
+		return Metrics{}, fmt.Errorf("summarize resolved triage items: %w", err)
+	}
+	return m, nil
+}
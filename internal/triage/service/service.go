@@ -0,0 +1,369 @@
+// Package service runs the triage inbox: leads that need a human because the
+// automation could not continue, raised from domain events and worked off by
+// claiming, assigning and resolving them.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/events"
+	"portal_final_backend/internal/triage/repository"
+	"portal_final_backend/internal/triage/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize       = 25
+	resolutionWindowDays  = 30
+	msgTriageItemNotFound = "triage item not found"
+)
+
+// priorities order the inbox: a missed SLA or a lead that cannot be reached
+// costs the most when it waits.
+var priorities = map[string]int{
+	repository.KindSLABreach:            1,
+	repository.KindMessageUndeliverable: 2,
+	repository.KindAgentRunBlocked:      2,
+	repository.KindManualIntervention:   3,
+	repository.KindEnrichmentFailed:     4,
+}
+
+// agentBlockedReasonCodes are the intervention reasons of an agent run that
+// got stuck, rather than a case the automation hands over by design.
+var agentBlockedReasonCodes = map[string]bool{
+	"nurturing_loop_threshold":    true,
+	"cross_agent_cycle_threshold": true,
+	"ai_loop_detected":            true,
+	"agent_cycle_detected":        true,
+}
+
+type Repository interface {
+	Raise(ctx context.Context, p repository.RaiseParams) (repository.Item, bool, error)
+	Get(ctx context.Context, id, organizationID uuid.UUID) (repository.Item, error)
+	List(ctx context.Context, p repository.ListParams) ([]repository.Item, int, error)
+	Claim(ctx context.Context, id, organizationID, userID uuid.UUID) (repository.Item, error)
+	Release(ctx context.Context, id, organizationID, userID uuid.UUID) (repository.Item, error)
+	Assign(ctx context.Context, id, organizationID uuid.UUID, assigneeID *uuid.UUID) (repository.Item, error)
+	Resolve(ctx context.Context, id, organizationID, userID uuid.UUID, note *string) (repository.Item, error)
+	IsOrganizationMember(ctx context.Context, organizationID, userID uuid.UUID) (bool, error)
+	Metrics(ctx context.Context, organizationID uuid.UUID, now, since time.Time) (repository.Metrics, error)
+}
+
+type Service struct {
+	repo Repository
+	log  *logger.Logger
+	now  func() time.Time
+}
+
+func New(repo Repository, log *logger.Logger) *Service {
+	return &Service{repo: repo, log: log, now: time.Now}
+}
+
+// InterventionKind returns the kind of item a manual intervention raises.
+func InterventionKind(reasonCode string) string {
+	if agentBlockedReasonCodes[reasonCode] {
+		return repository.KindAgentRunBlocked
+	}
+	return repository.KindManualIntervention
+}
+
+// RaiseManualIntervention queues a lead the pipeline handed over to a human.
+func (s *Service) RaiseManualIntervention(ctx context.Context, e events.ManualInterventionRequired) error {
+	serviceID := e.LeadServiceID
+	return s.raise(ctx, repository.RaiseParams{
+		OrganizationID: e.TenantID,
+		LeadID:         e.LeadID,
+		LeadServiceID:  &serviceID,
+		Kind:           InterventionKind(e.ReasonCode),
+		Reason:         e.Reason,
+		ReasonCode:     e.ReasonCode,
+		Context:        e.Context,
+	})
+}
+
+// RaiseSLABreach queues a lead service that made no progress for longer than
+// the organization allows.
+func (s *Service) RaiseSLABreach(ctx context.Context, e events.LeadStale) error {
+	serviceID := e.LeadServiceID
+	return s.raise(ctx, repository.RaiseParams{
+		OrganizationID: e.TenantID,
+		LeadID:         e.LeadID,
+		LeadServiceID:  &serviceID,
+		Kind:           repository.KindSLABreach,
+		Reason:         "Geen voortgang binnen de afgesproken termijn",
+		ReasonCode:     e.StaleReason,
+	})
+}
+
+// RaiseEnrichmentFailed queues a lead whose external data could not be looked
+// up.
+func (s *Service) RaiseEnrichmentFailed(ctx context.Context, e events.LeadEnrichmentFailed) error {
+	return s.raise(ctx, repository.RaiseParams{
+		OrganizationID: e.TenantID,
+		LeadID:         e.LeadID,
+		Kind:           repository.KindEnrichmentFailed,
+		Reason:         "Verrijken van leadgegevens mislukt",
+		ReasonCode:     e.Enrichment,
+		Context:        e.Error,
+	})
+}
+
+// RaiseMessageUndeliverable queues a lead a message could not be delivered
+// to, so someone can check the contact details.
+func (s *Service) RaiseMessageUndeliverable(ctx context.Context, organizationID, leadID uuid.UUID, channel string, reason string) error {
+	return s.raise(ctx, repository.RaiseParams{
+		OrganizationID: organizationID,
+		LeadID:         leadID,
+		Kind:           repository.KindMessageUndeliverable,
+		Reason:         fmt.Sprintf("Bericht via %s kon niet worden afgeleverd", channel),
+		ReasonCode:     channel,
+		Context:        reason,
+	})
+}
+
+func (s *Service) raise(ctx context.Context, p repository.RaiseParams) error {
+	p.Priority = priorities[p.Kind]
+	if strings.TrimSpace(p.Reason) == "" {
+		p.Reason = "Handmatige actie vereist"
+	}
+	it, opened, err := s.repo.Raise(ctx, p)
+	if errors.Is(err, repository.ErrNotFound) {
+		// The lead was deleted or belongs to another organization.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if opened {
+		s.log.Info("triage item opened", "itemId", it.ID, "leadId", it.LeadID, "kind", it.Kind)
+	}
+	return nil
+}
+
+func (s *Service) List(ctx context.Context, organizationID, userID uuid.UUID, req transport.ListItemsRequest) (transport.ItemListResponse, error) {
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	params := repository.ListParams{
+		OrganizationID: organizationID,
+		Status:         req.Status,
+		Kind:           req.Kind,
+		Unassigned:     req.Unassigned,
+		Limit:          pageSize,
+		Offset:         (page - 1) * pageSize,
+	}
+	switch {
+	case req.Mine:
+		params.AssigneeID = &userID
+	case req.AssigneeID != "":
+		id, err := uuid.Parse(req.AssigneeID)
+		if err != nil {
+			return transport.ItemListResponse{}, apperr.Validation("invalid assigneeId")
+		}
+		params.AssigneeID = &id
+	}
+	if req.LeadID != "" {
+		id, err := uuid.Parse(req.LeadID)
+		if err != nil {
+			return transport.ItemListResponse{}, apperr.Validation("invalid leadId")
+		}
+		params.LeadID = &id
+	}
+
+	items, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return transport.ItemListResponse{}, err
+	}
+	now := s.now()
+	resp := transport.ItemListResponse{Items: make([]transport.ItemResponse, len(items)), Total: total, Page: page, PageSize: pageSize}
+	for i, it := range items {
+		resp.Items[i] = toResponse(it, now)
+	}
+	return resp, nil
+}
+
+func (s *Service) Get(ctx context.Context, organizationID, id uuid.UUID) (transport.ItemResponse, error) {
+	it, err := s.repo.Get(ctx, id, organizationID)
+	if err != nil {
+		return transport.ItemResponse{}, mapNotFound(err)
+	}
+	return toResponse(it, s.now()), nil
+}
+
+// Claim makes the user work on an open item.
+func (s *Service) Claim(ctx context.Context, organizationID, id, userID uuid.UUID) (transport.ItemResponse, error) {
+	it, err := s.repo.Claim(ctx, id, organizationID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.ItemResponse{}, s.conflictOrNotFound(ctx, organizationID, id, func(current repository.Item) string {
+			if current.Status == repository.StatusClaimed {
+				return "triage item is already claimed"
+			}
+			return "resolved triage items cannot be claimed"
+		})
+	}
+	if err != nil {
+		return transport.ItemResponse{}, err
+	}
+	return toResponse(it, s.now()), nil
+}
+
+// Release hands an item the user claimed back to the queue.
+func (s *Service) Release(ctx context.Context, organizationID, id, userID uuid.UUID) (transport.ItemResponse, error) {
+	it, err := s.repo.Release(ctx, id, organizationID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.ItemResponse{}, s.conflictOrNotFound(ctx, organizationID, id, func(current repository.Item) string {
+			if current.Status == repository.StatusClaimed {
+				return "triage item is claimed by someone else"
+			}
+			return "only claimed triage items can be released"
+		})
+	}
+	if err != nil {
+		return transport.ItemResponse{}, err
+	}
+	return toResponse(it, s.now()), nil
+}
+
+// Assign sets or clears the member responsible for an unresolved item.
+func (s *Service) Assign(ctx context.Context, organizationID, id uuid.UUID, req transport.AssignItemRequest) (transport.ItemResponse, error) {
+	if req.AssigneeID != nil {
+		member, err := s.repo.IsOrganizationMember(ctx, organizationID, *req.AssigneeID)
+		if err != nil {
+			return transport.ItemResponse{}, err
+		}
+		if !member {
+			return transport.ItemResponse{}, apperr.Validation("assignee is not a member of the organization")
+		}
+	}
+	it, err := s.repo.Assign(ctx, id, organizationID, req.AssigneeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.ItemResponse{}, s.conflictOrNotFound(ctx, organizationID, id, func(repository.Item) string {
+			return "resolved triage items cannot be reassigned"
+		})
+	}
+	if err != nil {
+		return transport.ItemResponse{}, err
+	}
+	return toResponse(it, s.now()), nil
+}
+
+// Resolve closes an item, open or claimed by anyone.
+func (s *Service) Resolve(ctx context.Context, organizationID, id, userID uuid.UUID, req transport.ResolveItemRequest) (transport.ItemResponse, error) {
+	var note *string
+	if req.Note != nil {
+		if trimmed := strings.TrimSpace(*req.Note); trimmed != "" {
+			note = &trimmed
+		}
+	}
+	it, err := s.repo.Resolve(ctx, id, organizationID, userID, note)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.ItemResponse{}, s.conflictOrNotFound(ctx, organizationID, id, func(repository.Item) string {
+			return "triage item is already resolved"
+		})
+	}
+	if err != nil {
+		return transport.ItemResponse{}, err
+	}
+	return toResponse(it, s.now()), nil
+}
+
+// Metrics returns the size and age of the inbox and how fast it is worked off.
+func (s *Service) Metrics(ctx context.Context, organizationID uuid.UUID) (transport.MetricsResponse, error) {
+	now := s.now()
+	m, err := s.repo.Metrics(ctx, organizationID, now, now.AddDate(0, 0, -resolutionWindowDays))
+	if err != nil {
+		return transport.MetricsResponse{}, err
+	}
+
+	resp := transport.MetricsResponse{
+		Kinds: make([]transport.KindMetricsResponse, len(m.Kinds)),
+		Aging: transport.AgingMetricsResponse{
+			UnderHour:          m.Aging.UnderHour,
+			HourToDay:          m.Aging.UnderDay,
+			DayToWeek:          m.Aging.UnderWeek,
+			OverWeek:           m.Aging.OverWeek,
+			AverageWaitSeconds: int64(m.Aging.AverageWait),
+		},
+		Resolution: transport.ResolutionMetricsResponse{
+			WindowDays:                  resolutionWindowDays,
+			Resolved:                    m.Resolution.Resolved,
+			AverageTimeToClaimSeconds:   int64(m.Resolution.AverageClaimSecs),
+			AverageTimeToResolveSeconds: int64(m.Resolution.AverageResolveSecs),
+		},
+	}
+	for i, k := range m.Kinds {
+		resp.Unresolved += k.Open + k.Claimed
+		resp.Kinds[i] = transport.KindMetricsResponse{
+			Kind:       k.Kind,
+			Open:       k.Open,
+			Claimed:    k.Claimed,
+			Unassigned: k.Unassigned,
+		}
+		if k.OldestOpenAt != nil {
+			resp.Kinds[i].OldestAgeSeconds = int64(now.Sub(*k.OldestOpenAt).Seconds())
+		}
+	}
+	return resp, nil
+}
+
+// conflictOrNotFound explains why a guarded update matched no item: it does
+// not exist, or it is in a state the update does not apply to.
+func (s *Service) conflictOrNotFound(ctx context.Context, organizationID, id uuid.UUID, conflict func(repository.Item) string) error {
+	current, err := s.repo.Get(ctx, id, organizationID)
+	if err != nil {
+		return mapNotFound(err)
+	}
+	return apperr.Conflict(conflict(current))
+}
+
+// ItemAge returns how long an item has been waiting at the moment now, or how
+// long it waited until it was resolved.
+func ItemAge(it repository.Item, now time.Time) time.Duration {
+	end := now
+	if it.ResolvedAt != nil {
+		end = *it.ResolvedAt
+	}
+	return max(end.Sub(it.CreatedAt), 0)
+}
+
+func toResponse(it repository.Item, now time.Time) transport.ItemResponse {
+	return transport.ItemResponse{
+		ID:             it.ID,
+		LeadID:         it.LeadID,
+		LeadServiceID:  it.LeadServiceID,
+		LeadName:       it.LeadName,
+		Kind:           it.Kind,
+		Priority:       it.Priority,
+		Reason:         it.Reason,
+		ReasonCode:     it.ReasonCode,
+		Context:        it.Context,
+		Status:         it.Status,
+		AssigneeID:     it.AssigneeID,
+		ClaimedBy:      it.ClaimedBy,
+		ClaimedAt:      it.ClaimedAt,
+		ResolvedBy:     it.ResolvedBy,
+		ResolvedAt:     it.ResolvedAt,
+		ResolutionNote: it.ResolutionNote,
+		Occurrences:    it.Occurrences,
+		LastRaisedAt:   it.LastRaisedAt,
+		AgeSeconds:     int64(ItemAge(it, now).Seconds()),
+		CreatedAt:      it.CreatedAt,
+		UpdatedAt:      it.UpdatedAt,
+	}
+}
+
+func mapNotFound(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.NotFound(msgTriageItemNotFound)
+	}
+	return err
+}
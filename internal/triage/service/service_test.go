@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/triage/repository"
+)
+
+func TestInterventionKind(t *testing.T) {
+	cases := map[string]string{
+		"nurturing_loop_threshold":     repository.KindAgentRunBlocked,
+		"cross_agent_cycle_threshold":  repository.KindAgentRunBlocked,
+		"manual_intervention_required": repository.KindManualIntervention,
+		"":                             repository.KindManualIntervention,
+	}
+	for code, want := range cases {
+		if got := InterventionKind(code); got != want {
+			t.Errorf("InterventionKind(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestItemAge(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	now := created.Add(5 * time.Hour)
+
+	open := repository.Item{CreatedAt: created}
+	if got := ItemAge(open, now); got != 5*time.Hour {
+		t.Errorf("open item age = %v, want 5h", got)
+	}
+
+	resolvedAt := created.Add(90 * time.Minute)
+	resolved := repository.Item{CreatedAt: created, ResolvedAt: &resolvedAt}
+	if got := ItemAge(resolved, now); got != 90*time.Minute {
+		t.Errorf("resolved item age = %v, want 1h30m", got)
+	}
+
+	if got := ItemAge(open, created.Add(-time.Minute)); got != 0 {
+		t.Errorf("age before creation = %v, want 0", got)
+	}
+}
@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListItemsRequest struct {
+	Status     string `form:"status" validate:"omitempty,oneof=open claimed resolved"`
+	Kind       string `form:"kind" validate:"omitempty,oneof=manual_intervention agent_run_blocked enrichment_failed message_undeliverable sla_breach"`
+	AssigneeID string `form:"assigneeId" validate:"omitempty,uuid"`
+	Unassigned bool   `form:"unassigned"`
+	Mine       bool   `form:"mine"`
+	LeadID     string `form:"leadId" validate:"omitempty,uuid"`
+	Page       int    `form:"page" validate:"omitempty,min=1"`
+	PageSize   int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+// AssignItemRequest sets the assignee of an item. An omitted assignee clears
+// it.
+type AssignItemRequest struct {
+	AssigneeID *uuid.UUID `json:"assigneeId"`
+}
+
+type ResolveItemRequest struct {
+	Note *string `json:"note" validate:"omitempty,max=2000"`
+}
+
+type ItemResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	LeadID         uuid.UUID  `json:"leadId"`
+	LeadServiceID  *uuid.UUID `json:"leadServiceId,omitempty"`
+	LeadName       string     `json:"leadName"`
+	Kind           string     `json:"kind"`
+	Priority       int        `json:"priority"`
+	Reason         string     `json:"reason"`
+	ReasonCode     *string    `json:"reasonCode,omitempty"`
+	Context        *string    `json:"context,omitempty"`
+	Status         string     `json:"status"`
+	AssigneeID     *uuid.UUID `json:"assigneeId,omitempty"`
+	ClaimedBy      *uuid.UUID `json:"claimedBy,omitempty"`
+	ClaimedAt      *time.Time `json:"claimedAt,omitempty"`
+	ResolvedBy     *uuid.UUID `json:"resolvedBy,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+	ResolutionNote *string    `json:"resolutionNote,omitempty"`
+	Occurrences    int        `json:"occurrences"`
+	LastRaisedAt   time.Time  `json:"lastRaisedAt"`
+	// AgeSeconds is how long the item has been waiting, or took until it was
+	// resolved.
+	AgeSeconds int64     `json:"ageSeconds"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type ItemListResponse struct {
+	Items    []ItemResponse `json:"items"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+}
+
+type KindMetricsResponse struct {
+	Kind             string `json:"kind"`
+	Open             int    `json:"open"`
+	Claimed          int    `json:"claimed"`
+	Unassigned       int    `json:"unassigned"`
+	OldestAgeSeconds int64  `json:"oldestAgeSeconds"`
+}
+
+// AgingMetricsResponse counts the unresolved items by how long they have been
+// waiting.
+type AgingMetricsResponse struct {
+	UnderHour          int   `json:"underHour"`
+	HourToDay          int   `json:"hourToDay"`
+	DayToWeek          int   `json:"dayToWeek"`
+	OverWeek           int   `json:"overWeek"`
+	AverageWaitSeconds int64 `json:"averageWaitSeconds"`
+}
+
+// ResolutionMetricsResponse describes the items resolved in the last
+// WindowDays days.
+type ResolutionMetricsResponse struct {
+	WindowDays                  int   `json:"windowDays"`
+	Resolved                    int   `json:"resolved"`
+	AverageTimeToClaimSeconds   int64 `json:"averageTimeToClaimSeconds"`
+	AverageTimeToResolveSeconds int64 `json:"averageTimeToResolveSeconds"`
+}
+
+type MetricsResponse struct {
+	Unresolved int                       `json:"unresolved"`
+	Kinds      []KindMetricsResponse     `json:"kinds"`
+	Aging      AgingMetricsResponse      `json:"aging"`
+	Resolution ResolutionMetricsResponse `json:"resolution"`
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"portal_final_backend/internal/uploads/service"
+	"portal_final_backend/internal/uploads/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	msgInvalidRequest   = "invalid request"
+	msgValidationFailed = "validation failed"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.ListUploads)
+	rg.POST("", h.StartUpload)
+	rg.GET("/:uploadId", h.GetUpload)
+	rg.PUT("/:uploadId/parts/:partNumber", h.UploadPart)
+	rg.POST("/:uploadId/complete", h.CompleteUpload)
+	rg.DELETE("/:uploadId", h.AbortUpload)
+}
+
+func (h *Handler) StartUpload(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.StartUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.svc.StartUpload(c.Request.Context(), tenantID, httpkit.GetIdentity(c).UserID(), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.JSON(c, http.StatusCreated, resp)
+}
+
+// UploadPart stores the raw request body as one part. The Content-Length header
+// is required; the optional X-Content-SHA256 header is verified by storage.
+func (h *Handler) UploadPart(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	uploadID, ok := httpkit.ParseUUIDParam(c, "uploadId")
+	if !ok {
+		return
+	}
+	partNumber, err := strconv.Atoi(c.Param("partNumber"))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "invalid partNumber", nil)
+		return
+	}
+	if c.Request.ContentLength <= 0 {
+		httpkit.Error(c, http.StatusLengthRequired, "Content-Length is required", nil)
+		return
+	}
+
+	resp, err := h.svc.UploadPart(c.Request.Context(), tenantID, uploadID, partNumber, c.Request.Body, c.Request.ContentLength, c.GetHeader(transport.PartChecksumHeader))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetUpload(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	uploadID, ok := httpkit.ParseUUIDParam(c, "uploadId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.GetUpload(c.Request.Context(), tenantID, uploadID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) ListUploads(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	var req transport.ListUploadsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgInvalidRequest, nil)
+		return
+	}
+	if err := h.val.Struct(req); err != nil {
+		httpkit.Error(c, http.StatusBadRequest, msgValidationFailed, err.Error())
+		return
+	}
+
+	resp, err := h.svc.ListUploads(c.Request.Context(), tenantID, req.TargetType, uuid.MustParse(req.TargetID))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) CompleteUpload(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	uploadID, ok := httpkit.ParseUUIDParam(c, "uploadId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.CompleteUpload(c.Request.Context(), tenantID, uploadID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) AbortUpload(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	uploadID, ok := httpkit.ParseUUIDParam(c, "uploadId")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.AbortUpload(c.Request.Context(), tenantID, uploadID); httpkit.HandleError(c, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,44 @@
+// Package uploads provides resumable uploads for large lead service and quote
+// attachments: start an upload, send its parts in any order, query progress and
+// complete it. Abandoned uploads expire and are aborted by the scheduler.
+package uploads
+
+import (
+	"portal_final_backend/internal/adapters/storage"
+	apphttp "portal_final_backend/internal/http"
+	"portal_final_backend/internal/uploads/handler"
+	"portal_final_backend/internal/uploads/repository"
+	"portal_final_backend/internal/uploads/service"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+}
+
+func NewModule(pool *pgxpool.Pool, storageSvc storage.StorageService, cfg service.Config, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), storageSvc, cfg, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+	}
+}
+
+// Service returns the uploads service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "uploads"
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterRoutes(ctx.Protected.Group("/uploads"))
+}
+
+var _ apphttp.Module = (*Module)(nil)
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const sessionNotFoundMsg = "upload not found"
+
+// Repository persists resumable upload sessions and their parts.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Session is a resumable upload.
+type Session struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	TargetType      string
+	TargetID        uuid.UUID
+	Bucket          string
+	FileKey         string
+	StorageUploadID string
+	FileName        string
+	ContentType     string
+	SizeBytes       int64
+	PartSize        int64
+	ChecksumSHA256  *string
+	Status          string
+	CreatedBy       *uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ExpiresAt       time.Time
+	CompletedAt     *time.Time
+}
+
+// Part is an uploaded part of a session.
+type Part struct {
+	Number     int
+	ETag       string
+	SizeBytes  int64
+	UploadedAt time.Time
+}
+
+const sessionColumns = `id, organization_id, target_type, target_id, bucket, file_key, storage_upload_id, file_name, content_type,
+	size_bytes, part_size, checksum_sha256, status, created_by, created_at, updated_at, expires_at, completed_at`
+
+func scanSession(row pgx.Row) (Session, error) {
+	var s Session
+	err := row.Scan(&s.ID, &s.OrganizationID, &s.TargetType, &s.TargetID, &s.Bucket, &s.FileKey, &s.StorageUploadID, &s.FileName, &s.ContentType,
+		&s.SizeBytes, &s.PartSize, &s.ChecksumSHA256, &s.Status, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt, &s.ExpiresAt, &s.CompletedAt)
+	return s, err
+}
+
+func collectSessions(rows pgx.Rows) ([]Session, error) {
+	defer rows.Close()
+	items := make([]Session, 0)
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan upload session: %w", err)
+		}
+		items = append(items, s)
+	}
+	return items, rows.Err()
+}
+
+// TargetFolder returns the storage folder of a target in the organization, using
+// the same layout as presigned uploads. ok is false when the target does not exist.
+func (r *Repository) TargetFolder(ctx context.Context, organizationID uuid.UUID, targetType string, targetID uuid.UUID) (string, bool, error) {
+	switch targetType {
+	case "lead_service":
+		var leadID uuid.UUID
+		err := r.pool.QueryRow(ctx, `SELECT lead_id FROM RAC_lead_services WHERE id = $1 AND organization_id = $2`, targetID, organizationID).Scan(&leadID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("get upload target: %w", err)
+		}
+		return fmt.Sprintf("%s/%s/%s", organizationID, leadID, targetID), true, nil
+	case "quote":
+		var exists bool
+		err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM RAC_quotes WHERE id = $1 AND organization_id = $2)`, targetID, organizationID).Scan(&exists)
+		if err != nil {
+			return "", false, fmt.Errorf("get upload target: %w", err)
+		}
+		return fmt.Sprintf("%s/quotes/%s", organizationID, targetID), exists, nil
+	default:
+		return "", false, nil
+	}
+}
+
+// CreateSession inserts an upload session.
+func (r *Repository) CreateSession(ctx context.Context, s Session) (Session, error) {
+	created, err := scanSession(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_upload_sessions (organization_id, target_type, target_id, bucket, file_key, storage_upload_id, file_name, content_type,
+			size_bytes, part_size, checksum_sha256, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING `+sessionColumns,
+		s.OrganizationID, s.TargetType, s.TargetID, s.Bucket, s.FileKey, s.StorageUploadID, s.FileName, s.ContentType,
+		s.SizeBytes, s.PartSize, s.ChecksumSHA256, s.CreatedBy, s.ExpiresAt,
+	))
+	if err != nil {
+		return Session{}, fmt.Errorf("create upload session: %w", err)
+	}
+	return created, nil
+}
+
+// GetSession returns an upload session of the organization.
+func (r *Repository) GetSession(ctx context.Context, organizationID, id uuid.UUID) (Session, error) {
+	s, err := scanSession(r.pool.QueryRow(ctx, `SELECT `+sessionColumns+` FROM RAC_upload_sessions WHERE id = $1 AND organization_id = $2`, id, organizationID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Session{}, apperr.NotFound(sessionNotFoundMsg)
+	}
+	if err != nil {
+		return Session{}, fmt.Errorf("get upload session: %w", err)
+	}
+	return s, nil
+}
+
+// ListActiveSessions returns the unfinished uploads of a target, newest first.
+func (r *Repository) ListActiveSessions(ctx context.Context, organizationID uuid.UUID, targetType string, targetID uuid.UUID) ([]Session, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+sessionColumns+`
+		FROM RAC_upload_sessions
+		WHERE organization_id = $1 AND target_type = $2 AND target_id = $3 AND status = 'active' AND expires_at > now()
+		ORDER BY created_at DESC`, organizationID, targetType, targetID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list upload sessions: %w", err)
+	}
+	return collectSessions(rows)
+}
+
+// ListParts returns the uploaded parts of a session ordered by part number.
+func (r *Repository) ListParts(ctx context.Context, sessionID uuid.UUID) ([]Part, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT part_number, etag, size_bytes, uploaded_at
+		FROM RAC_upload_session_parts
+		WHERE session_id = $1
+		ORDER BY part_number`, sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list upload parts: %w", err)
+	}
+	defer rows.Close()
+
+	parts := make([]Part, 0)
+	for rows.Next() {
+		var p Part
+		if err := rows.Scan(&p.Number, &p.ETag, &p.SizeBytes, &p.UploadedAt); err != nil {
+			return nil, fmt.Errorf("scan upload part: %w", err)
+		}
+		parts = append(parts, p)
+	}
+	return parts, rows.Err()
+}
+
+// SavePart records an uploaded part, replacing an earlier upload of the same
+// part, and extends the expiry of the session.
+func (r *Repository) SavePart(ctx context.Context, sessionID uuid.UUID, part Part, expiresAt time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin save upload part: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE RAC_upload_sessions SET expires_at = $2, updated_at = now()
+		WHERE id = $1 AND status = 'active'`, sessionID, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("touch upload session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.Conflict("upload is no longer active")
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO RAC_upload_session_parts (session_id, part_number, etag, size_bytes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (session_id, part_number) DO UPDATE
+		SET etag = EXCLUDED.etag, size_bytes = EXCLUDED.size_bytes, uploaded_at = now()`,
+		sessionID, part.Number, part.ETag, part.SizeBytes,
+	); err != nil {
+		return fmt.Errorf("save upload part: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit upload part: %w", err)
+	}
+	return nil
+}
+
+// FinishSession moves an active session to a final status. It reports false when
+// the session was no longer active.
+func (r *Repository) FinishSession(ctx context.Context, id uuid.UUID, status string) (Session, bool, error) {
+	s, err := scanSession(r.pool.QueryRow(ctx, `
+		UPDATE RAC_upload_sessions
+		SET status = $2, updated_at = now(), completed_at = CASE WHEN $2 = 'completed' THEN now() END
+		WHERE id = $1 AND status = 'active'
+		RETURNING `+sessionColumns, id, status,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Session{}, false, nil
+	}
+	if err != nil {
+		return Session{}, false, fmt.Errorf("finish upload session: %w", err)
+	}
+	return s, true, nil
+}
+
+// ListExpired returns active sessions whose expiry passed, oldest first.
+func (r *Repository) ListExpired(ctx context.Context, now time.Time, limit int) ([]Session, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+sessionColumns+`
+		FROM RAC_upload_sessions
+		WHERE status = 'active' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2`, now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list expired upload sessions: %w", err)
+	}
+	return collectSessions(rows)
+}
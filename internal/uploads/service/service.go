@@ -0,0 +1,424 @@
+// Package service implements resumable uploads: large lead service and quote
+// attachments are uploaded in parts that are relayed to an S3 multipart upload,
+// so an interrupted upload resumes with the missing parts only.
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/uploads/repository"
+	"portal_final_backend/internal/uploads/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultPartSize is the part size offered to clients; small enough to retry
+	// cheaply on a mobile connection.
+	DefaultPartSize = 8 << 20
+	// DefaultSessionTTL is how long an upload may go without a new part before it
+	// is considered abandoned.
+	DefaultSessionTTL = 24 * time.Hour
+
+	expirySweepBatchSize = 200
+	contentTypePDF       = "application/pdf"
+)
+
+var errMultipartUnsupported = errors.New("storage backend does not support resumable uploads")
+
+type Repository interface {
+	TargetFolder(ctx context.Context, organizationID uuid.UUID, targetType string, targetID uuid.UUID) (string, bool, error)
+	CreateSession(ctx context.Context, s repository.Session) (repository.Session, error)
+	GetSession(ctx context.Context, organizationID, id uuid.UUID) (repository.Session, error)
+	ListActiveSessions(ctx context.Context, organizationID uuid.UUID, targetType string, targetID uuid.UUID) ([]repository.Session, error)
+	ListParts(ctx context.Context, sessionID uuid.UUID) ([]repository.Part, error)
+	SavePart(ctx context.Context, sessionID uuid.UUID, part repository.Part, expiresAt time.Time) error
+	FinishSession(ctx context.Context, id uuid.UUID, status string) (repository.Session, bool, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]repository.Session, error)
+}
+
+// Config holds the part size, the session expiry and the bucket of every target type.
+type Config struct {
+	PartSize   int64
+	SessionTTL time.Duration
+	Buckets    map[string]string
+}
+
+type Service struct {
+	repo     Repository
+	storage  storage.StorageService
+	uploader storage.MultipartUploader
+	cfg      Config
+	log      *logger.Logger
+	now      func() time.Time
+}
+
+// New creates the uploads service. Resumable uploads are rejected when the storage
+// backend cannot assemble multipart uploads.
+func New(repo Repository, storageSvc storage.StorageService, cfg Config, log *logger.Logger) *Service {
+	if cfg.PartSize < storage.MinMultipartPartSize {
+		cfg.PartSize = DefaultPartSize
+	}
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = DefaultSessionTTL
+	}
+	uploader, _ := storageSvc.(storage.MultipartUploader)
+	return &Service{repo: repo, storage: storageSvc, uploader: uploader, cfg: cfg, log: log, now: time.Now}
+}
+
+// StartUpload validates the file and its target and starts a multipart upload.
+func (s *Service) StartUpload(ctx context.Context, organizationID, userID uuid.UUID, req transport.StartUploadRequest) (transport.UploadResponse, error) {
+	if s.uploader == nil {
+		return transport.UploadResponse{}, errMultipartUnsupported
+	}
+	if err := s.storage.ValidateContentType(req.ContentType); err != nil {
+		return transport.UploadResponse{}, apperr.Validation("file type not allowed")
+	}
+	if req.TargetType == transport.TargetQuote && req.ContentType != contentTypePDF {
+		return transport.UploadResponse{}, apperr.Validation("quote attachments must be PDF files")
+	}
+	if err := s.storage.ValidateFileSize(req.SizeBytes); err != nil {
+		return transport.UploadResponse{}, apperr.Validation(err.Error())
+	}
+	bucket := s.cfg.Buckets[req.TargetType]
+	if bucket == "" {
+		return transport.UploadResponse{}, fmt.Errorf("no bucket configured for %s uploads", req.TargetType)
+	}
+
+	folder, ok, err := s.repo.TargetFolder(ctx, organizationID, req.TargetType, req.TargetID)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	if !ok {
+		return transport.UploadResponse{}, apperr.NotFound("upload target not found").WithDetails(map[string]string{"targetType": req.TargetType, "targetId": req.TargetID.String()})
+	}
+
+	fileName := path.Base(strings.TrimSpace(req.FileName))
+	fileKey, storageUploadID, err := s.uploader.StartMultipartUpload(ctx, bucket, folder, fileName, req.ContentType, req.SizeBytes)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+
+	var checksum *string
+	if req.ChecksumSHA256 != "" {
+		normalized := strings.ToLower(req.ChecksumSHA256)
+		checksum = &normalized
+	}
+	session, err := s.repo.CreateSession(ctx, repository.Session{
+		OrganizationID:  organizationID,
+		TargetType:      req.TargetType,
+		TargetID:        req.TargetID,
+		Bucket:          bucket,
+		FileKey:         fileKey,
+		StorageUploadID: storageUploadID,
+		FileName:        fileName,
+		ContentType:     req.ContentType,
+		SizeBytes:       req.SizeBytes,
+		PartSize:        partSizeFor(req.SizeBytes, s.cfg.PartSize),
+		ChecksumSHA256:  checksum,
+		CreatedBy:       &userID,
+		ExpiresAt:       s.now().Add(s.cfg.SessionTTL),
+	})
+	if err != nil {
+		s.abortStorageUpload(ctx, repository.Session{Bucket: bucket, FileKey: fileKey, StorageUploadID: storageUploadID})
+		return transport.UploadResponse{}, err
+	}
+	return toUploadResponse(session, nil), nil
+}
+
+// UploadPart stores one part of an upload. Every part but the last must be exactly
+// the part size of the session. Uploading a part again replaces it, so a client
+// can retry a part whose response it never received.
+func (s *Service) UploadPart(ctx context.Context, organizationID, id uuid.UUID, number int, reader io.Reader, size int64, checksum string) (transport.UploadResponse, error) {
+	session, err := s.activeSession(ctx, organizationID, id)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+
+	total := totalParts(session.SizeBytes, session.PartSize)
+	if number < 1 || number > total {
+		return transport.UploadResponse{}, apperr.Validation(fmt.Sprintf("part number must be between 1 and %d", total))
+	}
+	if expected := expectedPartSize(session, number); size != expected {
+		return transport.UploadResponse{}, apperr.Validation("part size does not match the upload").WithDetails(map[string]int64{
+			"expectedSizeBytes": expected,
+			"actualSizeBytes":   size,
+		})
+	}
+	checksum = strings.ToLower(strings.TrimSpace(checksum))
+	if decoded, err := hex.DecodeString(checksum); checksum != "" && (err != nil || len(decoded) != sha256.Size) {
+		return transport.UploadResponse{}, apperr.Validation("part checksum must be a hex encoded SHA-256")
+	}
+
+	part, err := s.uploader.UploadPart(ctx, session.Bucket, session.FileKey, session.StorageUploadID, number, reader, size, checksum)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	session.ExpiresAt = s.now().Add(s.cfg.SessionTTL)
+	if err := s.repo.SavePart(ctx, session.ID, repository.Part{Number: number, ETag: part.ETag, SizeBytes: part.Size}, session.ExpiresAt); err != nil {
+		return transport.UploadResponse{}, err
+	}
+	return s.progress(ctx, session)
+}
+
+// GetUpload returns an upload and the parts that are still missing.
+func (s *Service) GetUpload(ctx context.Context, organizationID, id uuid.UUID) (transport.UploadResponse, error) {
+	session, err := s.repo.GetSession(ctx, organizationID, id)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	return s.progress(ctx, session)
+}
+
+// ListUploads returns the unfinished uploads of a target, so a client can resume
+// them after a restart.
+func (s *Service) ListUploads(ctx context.Context, organizationID uuid.UUID, targetType string, targetID uuid.UUID) (transport.UploadListResponse, error) {
+	sessions, err := s.repo.ListActiveSessions(ctx, organizationID, targetType, targetID)
+	if err != nil {
+		return transport.UploadListResponse{}, err
+	}
+	items := make([]transport.UploadResponse, 0, len(sessions))
+	for _, session := range sessions {
+		item, err := s.progress(ctx, session)
+		if err != nil {
+			return transport.UploadListResponse{}, err
+		}
+		items = append(items, item)
+	}
+	return transport.UploadListResponse{Items: items}, nil
+}
+
+// CompleteUpload assembles the file once every part is uploaded. When the upload
+// declared a checksum, the assembled file is verified and discarded on mismatch.
+// Completing an upload again returns the completed upload.
+func (s *Service) CompleteUpload(ctx context.Context, organizationID, id uuid.UUID) (transport.UploadResponse, error) {
+	session, err := s.repo.GetSession(ctx, organizationID, id)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	if session.Status == transport.StatusCompleted {
+		return s.progress(ctx, session)
+	}
+	if session, err = s.activeSession(ctx, organizationID, id); err != nil {
+		return transport.UploadResponse{}, err
+	}
+
+	parts, err := s.repo.ListParts(ctx, session.ID)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	if missing := missingParts(session, parts); len(missing) > 0 {
+		return transport.UploadResponse{}, apperr.Validation("upload is missing parts").WithDetails(map[string][]int{"missingParts": missing})
+	}
+
+	stored := make([]storage.UploadedPart, 0, len(parts))
+	for _, part := range parts {
+		stored = append(stored, storage.UploadedPart{Number: part.Number, ETag: part.ETag, Size: part.SizeBytes})
+	}
+	if err := s.uploader.CompleteMultipartUpload(ctx, session.Bucket, session.FileKey, session.StorageUploadID, stored); err != nil {
+		return transport.UploadResponse{}, err
+	}
+
+	if session.ChecksumSHA256 != nil {
+		checksum, err := s.hashObject(ctx, session.Bucket, session.FileKey)
+		if err != nil || checksum != *session.ChecksumSHA256 {
+			s.discard(ctx, session)
+			if err != nil {
+				return transport.UploadResponse{}, err
+			}
+			return transport.UploadResponse{}, apperr.Validation("uploaded file does not match its checksum")
+		}
+	}
+
+	completed, ok, err := s.repo.FinishSession(ctx, session.ID, transport.StatusCompleted)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	if !ok {
+		return transport.UploadResponse{}, apperr.Conflict("upload is no longer active")
+	}
+	s.log.Info("resumable upload completed", "uploadId", completed.ID, "targetType", completed.TargetType, "targetId", completed.TargetID, "sizeBytes", completed.SizeBytes)
+	return toUploadResponse(completed, parts), nil
+}
+
+// AbortUpload discards an unfinished upload and its parts.
+func (s *Service) AbortUpload(ctx context.Context, organizationID, id uuid.UUID) error {
+	session, err := s.repo.GetSession(ctx, organizationID, id)
+	if err != nil {
+		return err
+	}
+	switch session.Status {
+	case transport.StatusAborted, transport.StatusExpired:
+		return nil
+	case transport.StatusCompleted:
+		return apperr.Conflict("upload is already completed")
+	}
+
+	if _, ok, err := s.repo.FinishSession(ctx, session.ID, transport.StatusAborted); err != nil || !ok {
+		return err
+	}
+	s.abortStorageUpload(ctx, session)
+	return nil
+}
+
+// ExpireAbandoned aborts uploads that received no part within the session TTL
+// and returns how many were expired.
+func (s *Service) ExpireAbandoned(ctx context.Context, now time.Time) (int, error) {
+	expired := 0
+	for {
+		sessions, err := s.repo.ListExpired(ctx, now, expirySweepBatchSize)
+		if err != nil {
+			return expired, err
+		}
+		for _, session := range sessions {
+			if _, ok, err := s.repo.FinishSession(ctx, session.ID, transport.StatusExpired); err != nil {
+				return expired, err
+			} else if !ok {
+				continue
+			}
+			s.abortStorageUpload(ctx, session)
+			expired++
+		}
+		if len(sessions) < expirySweepBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// activeSession returns a session that still accepts parts.
+func (s *Service) activeSession(ctx context.Context, organizationID, id uuid.UUID) (repository.Session, error) {
+	session, err := s.repo.GetSession(ctx, organizationID, id)
+	if err != nil {
+		return repository.Session{}, err
+	}
+	if session.Status == transport.StatusExpired || (session.Status == transport.StatusActive && !s.now().Before(session.ExpiresAt)) {
+		return repository.Session{}, apperr.Gone("upload expired; start a new upload")
+	}
+	if session.Status != transport.StatusActive {
+		return repository.Session{}, apperr.Conflict("upload is " + session.Status)
+	}
+	if s.uploader == nil {
+		return repository.Session{}, errMultipartUnsupported
+	}
+	return session, nil
+}
+
+func (s *Service) progress(ctx context.Context, session repository.Session) (transport.UploadResponse, error) {
+	parts, err := s.repo.ListParts(ctx, session.ID)
+	if err != nil {
+		return transport.UploadResponse{}, err
+	}
+	return toUploadResponse(session, parts), nil
+}
+
+func (s *Service) abortStorageUpload(ctx context.Context, session repository.Session) {
+	if s.uploader == nil {
+		return
+	}
+	if err := s.uploader.AbortMultipartUpload(ctx, session.Bucket, session.FileKey, session.StorageUploadID); err != nil {
+		s.log.Warn("uploads: failed to abort multipart upload", "key", session.FileKey, "error", err)
+	}
+}
+
+// discard removes an assembled file that failed verification.
+func (s *Service) discard(ctx context.Context, session repository.Session) {
+	if _, _, err := s.repo.FinishSession(ctx, session.ID, transport.StatusAborted); err != nil {
+		s.log.Warn("uploads: failed to abort rejected upload", "uploadId", session.ID, "error", err)
+	}
+	if err := s.storage.DeleteObject(ctx, session.Bucket, session.FileKey); err != nil {
+		s.log.Warn("uploads: failed to delete rejected upload", "uploadId", session.ID, "error", err)
+	}
+}
+
+// hashObject streams an object and returns its SHA-256 checksum.
+func (s *Service) hashObject(ctx context.Context, bucket, key string) (string, error) {
+	reader, err := s.storage.DownloadFile(ctx, bucket, key)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = reader.Close() }()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return "", fmt.Errorf("read object %s: %w", key, err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// partSizeFor grows the configured part size when a file would otherwise need
+// more parts than S3 allows.
+func partSizeFor(sizeBytes, partSize int64) int64 {
+	if minimum := (sizeBytes + storage.MaxMultipartParts - 1) / storage.MaxMultipartParts; partSize < minimum {
+		return minimum
+	}
+	return partSize
+}
+
+func totalParts(sizeBytes, partSize int64) int {
+	return int((sizeBytes + partSize - 1) / partSize)
+}
+
+func expectedPartSize(session repository.Session, number int) int64 {
+	if number < totalParts(session.SizeBytes, session.PartSize) {
+		return session.PartSize
+	}
+	return session.SizeBytes - int64(number-1)*session.PartSize
+}
+
+func missingParts(session repository.Session, parts []repository.Part) []int {
+	uploaded := make(map[int]bool, len(parts))
+	for _, part := range parts {
+		uploaded[part.Number] = true
+	}
+	missing := make([]int, 0)
+	for number := 1; number <= totalParts(session.SizeBytes, session.PartSize); number++ {
+		if !uploaded[number] {
+			missing = append(missing, number)
+		}
+	}
+	return missing
+}
+
+func toUploadResponse(session repository.Session, parts []repository.Part) transport.UploadResponse {
+	uploadedParts := make([]int, 0, len(parts))
+	var uploadedBytes int64
+	for _, part := range parts {
+		uploadedParts = append(uploadedParts, part.Number)
+		uploadedBytes += part.SizeBytes
+	}
+
+	resp := transport.UploadResponse{
+		ID:             session.ID,
+		TargetType:     session.TargetType,
+		TargetID:       session.TargetID,
+		FileName:       session.FileName,
+		ContentType:    session.ContentType,
+		SizeBytes:      session.SizeBytes,
+		ChecksumSHA256: session.ChecksumSHA256,
+		PartSize:       session.PartSize,
+		TotalParts:     totalParts(session.SizeBytes, session.PartSize),
+		UploadedParts:  uploadedParts,
+		MissingParts:   missingParts(session, parts),
+		UploadedBytes:  uploadedBytes,
+		Progress:       float64(uploadedBytes) / float64(session.SizeBytes),
+		Status:         session.Status,
+		ExpiresAt:      session.ExpiresAt,
+		CreatedAt:      session.CreatedAt,
+		CompletedAt:    session.CompletedAt,
+	}
+	if session.Status == transport.StatusCompleted {
+		resp.FileKey = session.FileKey
+	}
+	return resp
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/adapters/storage"
+	"portal_final_backend/internal/uploads/repository"
+	"portal_final_backend/internal/uploads/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+type memoryRepo struct {
+	sessions map[uuid.UUID]repository.Session
+	parts    map[uuid.UUID]map[int]repository.Part
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{sessions: map[uuid.UUID]repository.Session{}, parts: map[uuid.UUID]map[int]repository.Part{}}
+}
+
+func (r *memoryRepo) TargetFolder(_ context.Context, organizationID uuid.UUID, _ string, targetID uuid.UUID) (string, bool, error) {
+	return organizationID.String() + "/quotes/" + targetID.String(), true, nil
+}
+
+func (r *memoryRepo) CreateSession(_ context.Context, s repository.Session) (repository.Session, error) {
+	s.ID = uuid.New()
+	s.Status = transport.StatusActive
+	r.sessions[s.ID] = s
+	r.parts[s.ID] = map[int]repository.Part{}
+	return s, nil
+}
+
+func (r *memoryRepo) GetSession(_ context.Context, organizationID, id uuid.UUID) (repository.Session, error) {
+	s, ok := r.sessions[id]
+	if !ok || s.OrganizationID != organizationID {
+		return repository.Session{}, apperr.NotFound("upload not found")
+	}
+	return s, nil
+}
+
+func (r *memoryRepo) ListActiveSessions(context.Context, uuid.UUID, string, uuid.UUID) ([]repository.Session, error) {
+	return nil, nil
+}
+
+func (r *memoryRepo) ListParts(_ context.Context, sessionID uuid.UUID) ([]repository.Part, error) {
+	parts := make([]repository.Part, 0)
+	for _, p := range r.parts[sessionID] {
+		parts = append(parts, p)
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts, nil
+}
+
+func (r *memoryRepo) SavePart(_ context.Context, sessionID uuid.UUID, part repository.Part, expiresAt time.Time) error {
+	s := r.sessions[sessionID]
+	s.ExpiresAt = expiresAt
+	r.sessions[sessionID] = s
+	r.parts[sessionID][part.Number] = part
+	return nil
+}
+
+func (r *memoryRepo) FinishSession(_ context.Context, id uuid.UUID, status string) (repository.Session, bool, error) {
+	s := r.sessions[id]
+	if s.Status != transport.StatusActive {
+		return repository.Session{}, false, nil
+	}
+	s.Status = status
+	r.sessions[id] = s
+	return s, true, nil
+}
+
+func (r *memoryRepo) ListExpired(_ context.Context, now time.Time, _ int) ([]repository.Session, error) {
+	var expired []repository.Session
+	for _, s := range r.sessions {
+		if s.Status == transport.StatusActive && !s.ExpiresAt.After(now) {
+			expired = append(expired, s)
+		}
+	}
+	return expired, nil
+}
+
+// memoryStorage assembles multipart uploads in memory.
+type memoryStorage struct {
+	storage.StorageService
+	parts   map[string]map[int][]byte
+	objects map[string][]byte
+	aborted []string
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{parts: map[string]map[int][]byte{}, objects: map[string][]byte{}}
+}
+
+func (m *memoryStorage) ValidateContentType(string) error { return nil }
+func (m *memoryStorage) ValidateFileSize(int64) error     { return nil }
+
+func (m *memoryStorage) StartMultipartUpload(_ context.Context, _, folder, fileName, _ string, _ int64) (string, string, error) {
+	uploadID := uuid.NewString()
+	m.parts[uploadID] = map[int][]byte{}
+	return folder + "/" + fileName, uploadID, nil
+}
+
+func (m *memoryStorage) UploadPart(_ context.Context, _, _, uploadID string, number int, reader io.Reader, _ int64, _ string) (storage.UploadedPart, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return storage.UploadedPart{}, err
+	}
+	m.parts[uploadID][number] = data
+	return storage.UploadedPart{Number: number, ETag: "etag", Size: int64(len(data))}, nil
+}
+
+func (m *memoryStorage) CompleteMultipartUpload(_ context.Context, _, fileKey, uploadID string, parts []storage.UploadedPart) error {
+	var object []byte
+	for _, part := range parts {
+		object = append(object, m.parts[uploadID][part.Number]...)
+	}
+	m.objects[fileKey] = object
+	return nil
+}
+
+func (m *memoryStorage) AbortMultipartUpload(_ context.Context, _, _, uploadID string) error {
+	m.aborted = append(m.aborted, uploadID)
+	return nil
+}
+
+func (m *memoryStorage) DownloadFile(_ context.Context, _, fileKey string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.objects[fileKey])), nil
+}
+
+func (m *memoryStorage) DeleteObject(_ context.Context, _, fileKey string) error {
+	delete(m.objects, fileKey)
+	return nil
+}
+
+func newTestService(repo *memoryRepo, store *memoryStorage) *Service {
+	svc := New(repo, store, Config{
+		PartSize: storage.MinMultipartPartSize,
+		Buckets:  map[string]string{transport.TargetQuote: "quote-attachments"},
+	}, logger.New("development"))
+	return svc
+}
+
+func TestResumableUploadInParts(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newMemoryRepo(), newMemoryStorage()
+	svc := newTestService(repo, store)
+	organizationID := uuid.New()
+
+	content := bytes.Repeat([]byte("a"), storage.MinMultipartPartSize*2+10)
+	sum := sha256.Sum256(content)
+	upload, err := svc.StartUpload(ctx, organizationID, uuid.New(), transport.StartUploadRequest{
+		TargetType:     transport.TargetQuote,
+		TargetID:       uuid.New(),
+		FileName:       "survey.pdf",
+		ContentType:    "application/pdf",
+		SizeBytes:      int64(len(content)),
+		ChecksumSHA256: hex.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		t.Fatalf("start upload: %v", err)
+	}
+	if upload.TotalParts != 3 || len(upload.MissingParts) != 3 {
+		t.Fatalf("expected 3 missing parts, got %+v", upload)
+	}
+
+	part := func(number int) []byte {
+		start := (number - 1) * storage.MinMultipartPartSize
+		return content[start:min(start+storage.MinMultipartPartSize, len(content))]
+	}
+	if _, err := svc.UploadPart(ctx, organizationID, upload.ID, 3, bytes.NewReader(part(3)), storage.MinMultipartPartSize, ""); !apperr.Is(err, apperr.KindValidation) {
+		t.Fatalf("expected a wrongly sized last part to be rejected, got %v", err)
+	}
+	for _, number := range []int{3, 1} {
+		if _, err := svc.UploadPart(ctx, organizationID, upload.ID, number, bytes.NewReader(part(number)), int64(len(part(number))), ""); err != nil {
+			t.Fatalf("upload part %d: %v", number, err)
+		}
+	}
+
+	if _, err := svc.CompleteUpload(ctx, organizationID, upload.ID); !apperr.Is(err, apperr.KindValidation) {
+		t.Fatalf("expected completing with a missing part to fail, got %v", err)
+	}
+	progress, err := svc.GetUpload(ctx, organizationID, upload.ID)
+	if err != nil || len(progress.MissingParts) != 1 || progress.MissingParts[0] != 2 {
+		t.Fatalf("expected part 2 to be missing, got %+v (%v)", progress, err)
+	}
+
+	if _, err := svc.UploadPart(ctx, organizationID, upload.ID, 2, bytes.NewReader(part(2)), int64(len(part(2))), ""); err != nil {
+		t.Fatalf("upload part 2: %v", err)
+	}
+	completed, err := svc.CompleteUpload(ctx, organizationID, upload.ID)
+	if err != nil {
+		t.Fatalf("complete upload: %v", err)
+	}
+	if completed.Status != transport.StatusCompleted || completed.Progress != 1 || !strings.HasSuffix(completed.FileKey, "/survey.pdf") {
+		t.Fatalf("unexpected completed upload %+v", completed)
+	}
+	if !bytes.Equal(store.objects[completed.FileKey], content) {
+		t.Fatal("expected the parts to be assembled in order")
+	}
+}
+
+func TestExpireAbandonedUploads(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newMemoryRepo(), newMemoryStorage()
+	svc := newTestService(repo, store)
+	organizationID := uuid.New()
+
+	upload, err := svc.StartUpload(ctx, organizationID, uuid.New(), transport.StartUploadRequest{
+		TargetType: transport.TargetQuote, TargetID: uuid.New(), FileName: "offer.pdf", ContentType: "application/pdf", SizeBytes: 10,
+	})
+	if err != nil {
+		t.Fatalf("start upload: %v", err)
+	}
+
+	expired, err := svc.ExpireAbandoned(ctx, time.Now().Add(DefaultSessionTTL+time.Minute))
+	if err != nil || expired != 1 || len(store.aborted) != 1 {
+		t.Fatalf("expected the abandoned upload to be aborted, got %d (%v), aborted %v", expired, err, store.aborted)
+	}
+	if _, err := svc.UploadPart(ctx, organizationID, upload.ID, 1, strings.NewReader("0123456789"), 10, ""); !apperr.Is(err, apperr.KindGone) {
+		t.Fatalf("expected parts of an expired upload to be rejected, got %v", err)
+	}
+}
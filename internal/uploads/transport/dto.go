@@ -0,0 +1,71 @@
+// Package transport provides DTOs for resumable uploads.
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Targets a resumable upload can be started for.
+const (
+	TargetLeadService = "lead_service"
+	TargetQuote       = "quote"
+)
+
+// Upload session statuses.
+const (
+	StatusActive    = "active"
+	StatusCompleted = "completed"
+	StatusAborted   = "aborted"
+	StatusExpired   = "expired"
+)
+
+// PartChecksumHeader optionally carries the hex SHA-256 of an uploaded part.
+const PartChecksumHeader = "X-Content-SHA256"
+
+// StartUploadRequest starts a resumable upload. The checksum is optional; when
+// given, the assembled file is verified against it on completion.
+type StartUploadRequest struct {
+	TargetType     string    `json:"targetType" validate:"required,oneof=lead_service quote"`
+	TargetID       uuid.UUID `json:"targetId" validate:"required"`
+	FileName       string    `json:"fileName" validate:"required,min=1,max=255"`
+	ContentType    string    `json:"contentType" validate:"required,max=255"`
+	SizeBytes      int64     `json:"sizeBytes" validate:"required,gt=0"`
+	ChecksumSHA256 string    `json:"checksumSha256,omitempty" validate:"omitempty,len=64,hexadecimal"`
+}
+
+// ListUploadsRequest selects the unfinished uploads of one target.
+type ListUploadsRequest struct {
+	TargetType string `form:"targetType" validate:"required,oneof=lead_service quote"`
+	TargetID   string `form:"targetId" validate:"required,uuid"`
+}
+
+// UploadResponse describes an upload session and its progress. Parts are
+// numbered from 1; every part but the last is exactly PartSize bytes.
+type UploadResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	TargetType     string     `json:"targetType"`
+	TargetID       uuid.UUID  `json:"targetId"`
+	FileName       string     `json:"fileName"`
+	ContentType    string     `json:"contentType"`
+	SizeBytes      int64      `json:"sizeBytes"`
+	ChecksumSHA256 *string    `json:"checksumSha256,omitempty"`
+	PartSize       int64      `json:"partSize"`
+	TotalParts     int        `json:"totalParts"`
+	UploadedParts  []int      `json:"uploadedParts"`
+	MissingParts   []int      `json:"missingParts"`
+	UploadedBytes  int64      `json:"uploadedBytes"`
+	Progress       float64    `json:"progress"`
+	Status         string     `json:"status"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	CompletedAt    *time.Time `json:"completedAt,omitempty"`
+	// FileKey is set once the upload is completed. It is used like the file key
+	// of a presigned upload to record the attachment on the lead service or quote.
+	FileKey string `json:"fileKey,omitempty"`
+}
+
+type UploadListResponse struct {
+	Items []UploadResponse `json:"items"`
+}
@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Connector ingestion modes.
+const (
+	ConnectorModeWebhook = "webhook"
+	ConnectorModePolling = "polling"
+)
+
+// Statuses of a lead received through a connector.
+const (
+	ConnectorLeadProcessing = "processing"
+	ConnectorLeadCreated    = "created"
+	ConnectorLeadDuplicate  = "duplicate"
+	ConnectorLeadFailed     = "failed"
+)
+
+// LeadSourceAdapter knows the lead format and API of one lead marketplace.
+// Adapters only translate; the connector service owns deduplication and lead creation.
+type LeadSourceAdapter interface {
+	// Provider is the identifier stored on connectors, e.g. "bobex".
+	Provider() string
+	// DisplayName is the marketplace name shown to admins.
+	DisplayName() string
+	// SupportsMode reports whether the marketplace can deliver leads in mode.
+	SupportsMode(mode string) bool
+	// DefaultMapping maps the marketplace lead payload onto lead fields.
+	DefaultMapping() map[string][]string
+	// ExternalID returns the marketplace's identifier of a lead payload.
+	ExternalID(lead map[string]any) string
+	// DecodeWebhook splits a pushed request body into lead payloads.
+	DecodeWebhook(body []byte) ([]map[string]any, error)
+	// NewPollRequest builds the request for the leads received after cursor.
+	NewPollRequest(ctx context.Context, baseURL, token, cursor string) (*http.Request, error)
+	// DecodePoll parses a poll response into lead payloads and the next cursor.
+	DecodePoll(body []byte, cursor string) ([]map[string]any, string, error)
+}
+
+var leadSourceAdapters = map[string]LeadSourceAdapter{}
+
+func registerLeadSourceAdapter(adapter LeadSourceAdapter) {
+	leadSourceAdapters[adapter.Provider()] = adapter
+}
+
+// LookupLeadSourceAdapter returns the adapter of a provider.
+func LookupLeadSourceAdapter(provider string) (LeadSourceAdapter, bool) {
+	adapter, ok := leadSourceAdapters[provider]
+	return adapter, ok
+}
+
+// LeadSourceConnector is an organization's configured connection to a lead marketplace.
+type LeadSourceConnector struct {
+	ID                   uuid.UUID           `json:"id"`
+	OrganizationID       uuid.UUID           `json:"organizationId"`
+	Provider             string              `json:"provider"`
+	Name                 string              `json:"name"`
+	Mode                 string              `json:"mode"`
+	IsActive             bool                `json:"isActive"`
+	SecretHash           string              `json:"-"`
+	SecretPrefix         string              `json:"secretPrefix"`
+	APIBaseURL           *string             `json:"apiBaseUrl,omitempty"`
+	APITokenEncrypted    *string             `json:"-"`
+	HasAPIToken          bool                `json:"hasApiToken"`
+	PollIntervalMinutes  int                 `json:"pollIntervalMinutes"`
+	PollCursor           *string             `json:"pollCursor,omitempty"`
+	LastPolledAt         *time.Time          `json:"lastPolledAt,omitempty"`
+	LastError            *string             `json:"lastError,omitempty"`
+	FieldMappings        map[string][]string `json:"fieldMappings"`
+	ServiceTypeRules     []ServiceTypeRule   `json:"serviceTypeRules"`
+	DefaultValues        map[string]string   `json:"defaultValues"`
+	DuplicateWindowHours int                 `json:"duplicateWindowHours"`
+	CreatedAt            time.Time           `json:"createdAt"`
+	UpdatedAt            time.Time           `json:"updatedAt"`
+}
+
+// ConnectorLead is the ingestion record of one marketplace lead.
+type ConnectorLead struct {
+	ID          uuid.UUID  `json:"id"`
+	ConnectorID uuid.UUID  `json:"connectorId"`
+	ExternalID  string     `json:"externalId"`
+	Status      string     `json:"status"`
+	LeadID      *uuid.UUID `json:"leadId,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	ReceivedAt  time.Time  `json:"receivedAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// ConnectorIngestResult counts what happened to the leads of one delivery or poll.
+type ConnectorIngestResult struct {
+	Received   int `json:"received"`
+	Created    int `json:"created"`
+	Duplicates int `json:"duplicates"`
+	Failed     int `json:"failed"`
+}
+
+// LeadSourceProviderResponse describes a supported marketplace.
+type LeadSourceProviderResponse struct {
+	Provider       string              `json:"provider"`
+	DisplayName    string              `json:"displayName"`
+	Modes          []string            `json:"modes"`
+	DefaultMapping map[string][]string `json:"defaultMapping"`
+}
+
+// CreateConnectorRequest is the admin request to connect a lead marketplace.
+type CreateConnectorRequest struct {
+	Provider string `json:"provider" validate:"required,max=50"`
+	Name     string `json:"name" validate:"required,min=1,max=100"`
+	Mode     string `json:"mode" validate:"required,oneof=webhook polling"`
+	UpdateConnectorRequest
+}
+
+// UpdateConnectorRequest holds the editable connector settings. An omitted
+// API token keeps the stored one.
+type UpdateConnectorRequest struct {
+	IsActive             *bool               `json:"isActive,omitempty"`
+	APIBaseURL           string              `json:"apiBaseUrl" validate:"omitempty,url,max=500"`
+	APIToken             *string             `json:"apiToken,omitempty" validate:"omitempty,max=1000"`
+	PollIntervalMinutes  int                 `json:"pollIntervalMinutes" validate:"omitempty,min=5,max=1440"`
+	FieldMappings        map[string][]string `json:"fieldMappings" validate:"max=30,dive,min=1,max=10,dive,required,max=200"`
+	ServiceTypeRules     []ServiceTypeRule   `json:"serviceTypeRules" validate:"max=50,dive"`
+	DefaultValues        map[string]string   `json:"defaultValues" validate:"max=30,dive,max=500"`
+	DuplicateWindowHours *int                `json:"duplicateWindowHours,omitempty" validate:"omitempty,min=0,max=8760"`
+}
+
+// CreateConnectorResponse returns the new connector with its webhook secret,
+// which is shown only once.
+type CreateConnectorResponse struct {
+	LeadSourceConnector
+	Secret     string `json:"secret"`
+	WebhookURL string `json:"webhookUrl"`
+}
+
+// ConnectorWebhookResponse is returned to the marketplace after a delivery.
+type ConnectorWebhookResponse struct {
+	ConnectorIngestResult
+	Message string `json:"message"`
+}
+
+// ListLeadSourceProviders returns the supported marketplaces by provider name.
+func ListLeadSourceProviders() []LeadSourceProviderResponse {
+	providers := make([]LeadSourceProviderResponse, 0, len(leadSourceAdapters))
+	for _, adapter := range leadSourceAdapters {
+		modes := make([]string, 0, 2)
+		for _, mode := range []string{ConnectorModeWebhook, ConnectorModePolling} {
+			if adapter.SupportsMode(mode) {
+				modes = append(modes, mode)
+			}
+		}
+		providers = append(providers, LeadSourceProviderResponse{
+			Provider:       adapter.Provider(),
+			DisplayName:    adapter.DisplayName(),
+			Modes:          modes,
+			DefaultMapping: adapter.DefaultMapping(),
+		})
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Provider < providers[j].Provider })
+	return providers
+}
+
+// connectorMappingConfig combines the provider's default mapping with the
+// connector's overrides. A field mapped on the connector replaces the default
+// paths of that field; heuristics are off since the payload format is known.
+func connectorMappingConfig(adapter LeadSourceAdapter, conn LeadSourceConnector) FieldMappingConfig {
+	mappings := adapter.DefaultMapping()
+	for field, paths := range conn.FieldMappings {
+		mappings[field] = paths
+	}
+	return FieldMappingConfig{
+		OrganizationID:   conn.OrganizationID,
+		FieldMappings:    mappings,
+		ServiceTypeRules: conn.ServiceTypeRules,
+		DefaultValues:    conn.DefaultValues,
+		IsActive:         true,
+	}
+}
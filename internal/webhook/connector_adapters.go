@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	registerLeadSourceAdapter(bobexAdapter{})
+	registerLeadSourceAdapter(solvariAdapter{})
+}
+
+// bobexAdapter ingests Bobex leads. Bobex pushes one lead per request or
+// returns them in pages under "data", with "next_since" as the cursor.
+type bobexAdapter struct{}
+
+func (bobexAdapter) Provider() string    { return "bobex" }
+func (bobexAdapter) DisplayName() string { return "Bobex" }
+
+func (bobexAdapter) SupportsMode(mode string) bool {
+	return mode == ConnectorModeWebhook || mode == ConnectorModePolling
+}
+
+func (bobexAdapter) DefaultMapping() map[string][]string {
+	return map[string][]string{
+		MappedFieldFirstName:   {"contact.first_name"},
+		MappedFieldLastName:    {"contact.last_name"},
+		MappedFieldEmail:       {"contact.email"},
+		MappedFieldPhone:       {"contact.phone", "contact.mobile"},
+		MappedFieldStreet:      {"address.street"},
+		MappedFieldHouseNumber: {"address.house_number"},
+		MappedFieldZipCode:     {"address.postal_code"},
+		MappedFieldCity:        {"address.city"},
+		MappedFieldMessage:     {"description"},
+		MappedFieldServiceType: {"category.name"},
+	}
+}
+
+func (bobexAdapter) ExternalID(lead map[string]any) string {
+	return payloadString(lead, "id")
+}
+
+func (bobexAdapter) DecodeWebhook(body []byte) ([]map[string]any, error) {
+	return decodeLeadPayloads(body, "data")
+}
+
+func (bobexAdapter) NewPollRequest(ctx context.Context, baseURL, token, cursor string) (*http.Request, error) {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("since", cursor)
+	}
+	return newConnectorPollRequest(ctx, baseURL, "/leads", query, token)
+}
+
+func (bobexAdapter) DecodePoll(body []byte, cursor string) ([]map[string]any, string, error) {
+	var page struct {
+		Data      []map[string]any `json:"data"`
+		NextSince string           `json:"next_since"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, cursor, fmt.Errorf("decode bobex leads: %w", err)
+	}
+	if page.NextSince != "" {
+		cursor = page.NextSince
+	}
+	return page.Data, cursor, nil
+}
+
+// solvariAdapter ingests Solvari leads. Solvari sends flat lead objects with a
+// numeric "lead_id"; polling asks for the leads after the highest ID seen.
+type solvariAdapter struct{}
+
+func (solvariAdapter) Provider() string    { return "solvari" }
+func (solvariAdapter) DisplayName() string { return "Solvari" }
+
+func (solvariAdapter) SupportsMode(mode string) bool {
+	return mode == ConnectorModeWebhook || mode == ConnectorModePolling
+}
+
+func (solvariAdapter) DefaultMapping() map[string][]string {
+	return map[string][]string{
+		MappedFieldFirstName:   {"firstname"},
+		MappedFieldLastName:    {"lastname"},
+		MappedFieldEmail:       {"email"},
+		MappedFieldPhone:       {"phone", "mobile"},
+		MappedFieldStreet:      {"street"},
+		MappedFieldHouseNumber: {"house_number"},
+		MappedFieldZipCode:     {"zipcode"},
+		MappedFieldCity:        {"city"},
+		MappedFieldMessage:     {"comment", "description"},
+		MappedFieldServiceType: {"product.name", "product_name"},
+	}
+}
+
+func (solvariAdapter) ExternalID(lead map[string]any) string {
+	return payloadString(lead, "lead_id")
+}
+
+func (solvariAdapter) DecodeWebhook(body []byte) ([]map[string]any, error) {
+	return decodeLeadPayloads(body, "leads")
+}
+
+func (solvariAdapter) NewPollRequest(ctx context.Context, baseURL, token, cursor string) (*http.Request, error) {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("after_id", cursor)
+	}
+	return newConnectorPollRequest(ctx, baseURL, "/leads", query, token)
+}
+
+func (a solvariAdapter) DecodePoll(body []byte, cursor string) ([]map[string]any, string, error) {
+	var page struct {
+		Leads []map[string]any `json:"leads"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, cursor, fmt.Errorf("decode solvari leads: %w", err)
+	}
+	highest, _ := strconv.ParseInt(cursor, 10, 64)
+	for _, lead := range page.Leads {
+		if id, err := strconv.ParseInt(a.ExternalID(lead), 10, 64); err == nil && id > highest {
+			highest = id
+		}
+	}
+	if highest > 0 {
+		cursor = strconv.FormatInt(highest, 10)
+	}
+	return page.Leads, cursor, nil
+}
+
+// decodeLeadPayloads accepts a single lead object, an array of leads or an
+// object with the leads under listKey.
+func decodeLeadPayloads(body []byte, listKey string) ([]map[string]any, error) {
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") {
+		var leads []map[string]any
+		if err := json.Unmarshal(body, &leads); err != nil {
+			return nil, fmt.Errorf("decode leads: %w", err)
+		}
+		return leads, nil
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode lead: %w", err)
+	}
+	if payload == nil {
+		return nil, errors.New("empty lead payload")
+	}
+	list, ok := payload[listKey].([]any)
+	if !ok {
+		return []map[string]any{payload}, nil
+	}
+	leads := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if lead, ok := item.(map[string]any); ok {
+			leads = append(leads, lead)
+		}
+	}
+	return leads, nil
+}
+
+func newConnectorPollRequest(ctx context.Context, baseURL, path string, query url.Values, token string) (*http.Request, error) {
+	endpoint := strings.TrimRight(baseURL, "/") + path
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// payloadString reads a top-level scalar of a lead payload as a string.
+func payloadString(lead map[string]any, key string) string {
+	return strings.TrimSpace(scalarToString(lead[key]))
+}
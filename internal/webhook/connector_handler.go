@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	connectorTokenHeader       = "X-Connector-Token"
+	maxConnectorWebhookBytes   = 1 << 20
+	errConnectorNotFoundPublic = "invalid connector or token"
+)
+
+// HandleListLeadSourceProviders returns the lead marketplaces that can be connected.
+// GET /api/v1/admin/webhook/connectors/providers
+func (h *Handler) HandleListLeadSourceProviders(c *gin.Context) {
+	httpkit.OK(c, ListLeadSourceProviders())
+}
+
+// HandleListConnectors lists the lead source connectors of the organization.
+// GET /api/v1/admin/webhook/connectors
+func (h *Handler) HandleListConnectors(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	connectors, err := h.service.ListConnectors(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, connectors)
+}
+
+// HandleCreateConnector connects a lead marketplace. The webhook secret is only returned here.
+// POST /api/v1/admin/webhook/connectors
+func (h *Handler) HandleCreateConnector(c *gin.Context) {
+	req, ok := httpkit.BindJSON[CreateConnectorRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	conn, secret, err := h.service.CreateConnector(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateConnectorResponse{
+		LeadSourceConnector: conn,
+		Secret:              secret,
+		WebhookURL:          buildWebhookURL(c, "/api/v1/webhook/connectors/"+conn.ID.String()+"/leads"),
+	})
+}
+
+// HandleUpdateConnector replaces the settings of a connector.
+// PUT /api/v1/admin/webhook/connectors/:connectorId
+func (h *Handler) HandleUpdateConnector(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	connectorID, ok := httpkit.ParseUUIDParam(c, "connectorId")
+	if !ok {
+		return
+	}
+
+	req, ok := httpkit.BindJSON[UpdateConnectorRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	conn, err := h.service.UpdateConnector(c.Request.Context(), connectorID, tenantID, req)
+	if h.handleConnectorError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, conn)
+}
+
+// HandleDeleteConnector disconnects a lead marketplace.
+// DELETE /api/v1/admin/webhook/connectors/:connectorId
+func (h *Handler) HandleDeleteConnector(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	connectorID, ok := httpkit.ParseUUIDParam(c, "connectorId")
+	if !ok {
+		return
+	}
+
+	if h.handleConnectorError(c, h.service.DeleteConnector(c.Request.Context(), connectorID, tenantID)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "connector deleted"})
+}
+
+// HandlePollConnector fetches new leads of a polling connector without waiting for its interval.
+// POST /api/v1/admin/webhook/connectors/:connectorId/poll
+func (h *Handler) HandlePollConnector(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	connectorID, ok := httpkit.ParseUUIDParam(c, "connectorId")
+	if !ok {
+		return
+	}
+
+	result, err := h.service.PollConnector(c.Request.Context(), connectorID, tenantID)
+	if h.handleConnectorError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, result)
+}
+
+// HandleListConnectorLeads lists the recently received leads of a connector.
+// GET /api/v1/admin/webhook/connectors/:connectorId/leads
+func (h *Handler) HandleListConnectorLeads(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	connectorID, ok := httpkit.ParseUUIDParam(c, "connectorId")
+	if !ok {
+		return
+	}
+
+	leads, err := h.service.ListConnectorLeads(c.Request.Context(), connectorID, tenantID)
+	if h.handleConnectorError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, leads)
+}
+
+// HandleConnectorWebhook receives leads pushed by a lead marketplace. The
+// connector secret is sent in the X-Connector-Token header or the token query parameter.
+// POST /api/v1/webhook/connectors/:connectorId/leads
+func (h *Handler) HandleConnectorWebhook(c *gin.Context) {
+	connectorID, ok := httpkit.ParseUUIDParam(c, "connectorId")
+	if !ok {
+		return
+	}
+
+	token := c.GetHeader(connectorTokenHeader)
+	if token == "" {
+		token = c.Query("token")
+	}
+	if token == "" {
+		httpkit.Error(c, http.StatusUnauthorized, "missing connector token", nil)
+		return
+	}
+
+	conn, err := h.repo.GetConnectorBySecret(c.Request.Context(), connectorID, HashKey(token))
+	if err == ErrConnectorNotFound {
+		httpkit.Error(c, http.StatusUnauthorized, errConnectorNotFoundPublic, nil)
+		return
+	}
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	if !httpkit.BindTenantContext(c, conn.OrganizationID) {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxConnectorWebhookBytes))
+	if err != nil {
+		httpkit.Error(c, http.StatusBadRequest, "failed to read request body", nil)
+		return
+	}
+
+	result, err := h.service.IngestConnectorWebhook(c.Request.Context(), conn, body)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, ConnectorWebhookResponse{ConnectorIngestResult: result, Message: "leads received"})
+}
+
+func (h *Handler) handleConnectorError(c *gin.Context, err error) bool {
+	if err == ErrConnectorNotFound {
+		httpkit.Error(c, http.StatusNotFound, "connector not found", nil)
+		return true
+	}
+	return httpkit.HandleError(c, err)
+}
@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrConnectorNotFound = errors.New("lead source connector not found")
+
+const connectorColumns = `id, organization_id, provider, name, mode, is_active, secret_hash, secret_prefix,
+	api_base_url, api_token_encrypted, poll_interval_minutes, poll_cursor, last_polled_at, last_error,
+	field_mappings, service_type_rules, default_values, duplicate_window_hours, created_at, updated_at`
+
+func scanConnector(row pgx.Row) (LeadSourceConnector, error) {
+	var conn LeadSourceConnector
+	var fieldMappings, serviceTypeRules, defaultValues []byte
+	if err := row.Scan(
+		&conn.ID, &conn.OrganizationID, &conn.Provider, &conn.Name, &conn.Mode, &conn.IsActive,
+		&conn.SecretHash, &conn.SecretPrefix, &conn.APIBaseURL, &conn.APITokenEncrypted,
+		&conn.PollIntervalMinutes, &conn.PollCursor, &conn.LastPolledAt, &conn.LastError,
+		&fieldMappings, &serviceTypeRules, &defaultValues, &conn.DuplicateWindowHours,
+		&conn.CreatedAt, &conn.UpdatedAt,
+	); err != nil {
+		return LeadSourceConnector{}, err
+	}
+	if err := json.Unmarshal(fieldMappings, &conn.FieldMappings); err != nil || conn.FieldMappings == nil {
+		conn.FieldMappings = map[string][]string{}
+	}
+	if err := json.Unmarshal(serviceTypeRules, &conn.ServiceTypeRules); err != nil || conn.ServiceTypeRules == nil {
+		conn.ServiceTypeRules = []ServiceTypeRule{}
+	}
+	if err := json.Unmarshal(defaultValues, &conn.DefaultValues); err != nil || conn.DefaultValues == nil {
+		conn.DefaultValues = map[string]string{}
+	}
+	conn.HasAPIToken = conn.APITokenEncrypted != nil && *conn.APITokenEncrypted != ""
+	return conn, nil
+}
+
+func scanConnectors(rows pgx.Rows) ([]LeadSourceConnector, error) {
+	defer rows.Close()
+	connectors := make([]LeadSourceConnector, 0)
+	for rows.Next() {
+		conn, err := scanConnector(rows)
+		if err != nil {
+			return nil, err
+		}
+		connectors = append(connectors, conn)
+	}
+	return connectors, rows.Err()
+}
+
+// CreateConnector stores a new lead source connector.
+func (r *Repository) CreateConnector(ctx context.Context, conn LeadSourceConnector) (LeadSourceConnector, error) {
+	fieldMappings, serviceTypeRules, defaultValues, err := marshalConnectorMapping(conn)
+	if err != nil {
+		return LeadSourceConnector{}, err
+	}
+	return scanConnector(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_lead_source_connectors (
+			organization_id, provider, name, mode, is_active, secret_hash, secret_prefix,
+			api_base_url, api_token_encrypted, poll_interval_minutes,
+			field_mappings, service_type_rules, default_values, duplicate_window_hours
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING `+connectorColumns,
+		conn.OrganizationID, conn.Provider, conn.Name, conn.Mode, conn.IsActive, conn.SecretHash, conn.SecretPrefix,
+		conn.APIBaseURL, conn.APITokenEncrypted, conn.PollIntervalMinutes,
+		fieldMappings, serviceTypeRules, defaultValues, conn.DuplicateWindowHours,
+	))
+}
+
+// UpdateConnector replaces the editable settings of a connector.
+func (r *Repository) UpdateConnector(ctx context.Context, conn LeadSourceConnector) (LeadSourceConnector, error) {
+	fieldMappings, serviceTypeRules, defaultValues, err := marshalConnectorMapping(conn)
+	if err != nil {
+		return LeadSourceConnector{}, err
+	}
+	updated, err := scanConnector(r.pool.QueryRow(ctx, `
+		UPDATE RAC_lead_source_connectors
+		SET is_active = $3, api_base_url = $4, api_token_encrypted = $5, poll_interval_minutes = $6,
+			field_mappings = $7, service_type_rules = $8, default_values = $9, duplicate_window_hours = $10,
+			updated_at = now()
+		WHERE id = $1 AND organization_id = $2
+		RETURNING `+connectorColumns,
+		conn.ID, conn.OrganizationID, conn.IsActive, conn.APIBaseURL, conn.APITokenEncrypted, conn.PollIntervalMinutes,
+		fieldMappings, serviceTypeRules, defaultValues, conn.DuplicateWindowHours,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LeadSourceConnector{}, ErrConnectorNotFound
+	}
+	return updated, err
+}
+
+// GetConnector returns a connector of an organization.
+func (r *Repository) GetConnector(ctx context.Context, id, orgID uuid.UUID) (LeadSourceConnector, error) {
+	conn, err := scanConnector(r.pool.QueryRow(ctx, `SELECT `+connectorColumns+`
+		FROM RAC_lead_source_connectors
+		WHERE id = $1 AND organization_id = $2`, id, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LeadSourceConnector{}, ErrConnectorNotFound
+	}
+	return conn, err
+}
+
+// GetConnectorBySecret returns the connector a webhook secret belongs to.
+func (r *Repository) GetConnectorBySecret(ctx context.Context, id uuid.UUID, secretHash string) (LeadSourceConnector, error) {
+	conn, err := scanConnector(r.pool.QueryRow(ctx, `SELECT `+connectorColumns+`
+		FROM RAC_lead_source_connectors
+		WHERE id = $1 AND secret_hash = $2`, id, secretHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LeadSourceConnector{}, ErrConnectorNotFound
+	}
+	return conn, err
+}
+
+// ListConnectors returns the connectors of an organization, oldest first.
+func (r *Repository) ListConnectors(ctx context.Context, orgID uuid.UUID) ([]LeadSourceConnector, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+connectorColumns+`
+		FROM RAC_lead_source_connectors
+		WHERE organization_id = $1
+		ORDER BY created_at`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("list connectors: %w", err)
+	}
+	return scanConnectors(rows)
+}
+
+// ListDueConnectors returns the active polling connectors whose interval has
+// passed, least recently polled first.
+func (r *Repository) ListDueConnectors(ctx context.Context, now time.Time) ([]LeadSourceConnector, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+connectorColumns+`
+		FROM RAC_lead_source_connectors
+		WHERE is_active AND mode = 'polling'
+			AND (last_polled_at IS NULL OR last_polled_at + make_interval(mins => poll_interval_minutes) <= $1)
+		ORDER BY last_polled_at NULLS FIRST`, now)
+	if err != nil {
+		return nil, fmt.Errorf("list due connectors: %w", err)
+	}
+	return scanConnectors(rows)
+}
+
+// DeleteConnector removes a connector. Leads it created are kept.
+func (r *Repository) DeleteConnector(ctx context.Context, id, orgID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM RAC_lead_source_connectors WHERE id = $1 AND organization_id = $2`, id, orgID)
+	if err != nil {
+		return fmt.Errorf("delete connector: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrConnectorNotFound
+	}
+	return nil
+}
+
+// RecordConnectorPoll stores the outcome of a poll: the new cursor, or the error
+// that stopped it.
+func (r *Repository) RecordConnectorPoll(ctx context.Context, id uuid.UUID, cursor *string, polledAt time.Time, lastError *string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE RAC_lead_source_connectors
+		SET poll_cursor = COALESCE($2, poll_cursor), last_polled_at = $3, last_error = $4
+		WHERE id = $1`, id, cursor, polledAt, lastError)
+	if err != nil {
+		return fmt.Errorf("record connector poll: %w", err)
+	}
+	return nil
+}
+
+// ClaimConnectorLead records a marketplace lead as being processed. It returns
+// false when the lead was received before and did not fail, so it must be skipped.
+func (r *Repository) ClaimConnectorLead(ctx context.Context, conn LeadSourceConnector, externalID string, payload map[string]any) (uuid.UUID, bool, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("marshal connector lead payload: %w", err)
+	}
+	var id uuid.UUID
+	err = r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_lead_source_connector_leads (connector_id, organization_id, external_id, status, payload)
+		VALUES ($1, $2, $3, 'processing', $4)
+		ON CONFLICT (connector_id, external_id) DO UPDATE
+			SET status = 'processing', payload = EXCLUDED.payload, error = NULL, updated_at = now()
+			WHERE RAC_lead_source_connector_leads.status = 'failed'
+		RETURNING id`, conn.ID, conn.OrganizationID, externalID, payloadJSON).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, false, nil
+	}
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("claim connector lead: %w", err)
+	}
+	return id, true, nil
+}
+
+// FinishConnectorLead stores the outcome of a claimed marketplace lead.
+func (r *Repository) FinishConnectorLead(ctx context.Context, id uuid.UUID, status string, leadID *uuid.UUID, errText *string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE RAC_lead_source_connector_leads
+		SET status = $2, lead_id = $3, error = $4, updated_at = now()
+		WHERE id = $1`, id, status, leadID, errText)
+	if err != nil {
+		return fmt.Errorf("finish connector lead: %w", err)
+	}
+	return nil
+}
+
+// ListConnectorLeads returns the most recently received leads of a connector.
+func (r *Repository) ListConnectorLeads(ctx context.Context, connectorID, orgID uuid.UUID, limit int) ([]ConnectorLead, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, connector_id, external_id, status, lead_id, error, received_at, updated_at
+		FROM RAC_lead_source_connector_leads
+		WHERE connector_id = $1 AND organization_id = $2
+		ORDER BY received_at DESC
+		LIMIT $3`, connectorID, orgID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list connector leads: %w", err)
+	}
+	defer rows.Close()
+
+	leads := make([]ConnectorLead, 0)
+	for rows.Next() {
+		var lead ConnectorLead
+		if err := rows.Scan(&lead.ID, &lead.ConnectorID, &lead.ExternalID, &lead.Status, &lead.LeadID,
+			&lead.Error, &lead.ReceivedAt, &lead.UpdatedAt); err != nil {
+			return nil, err
+		}
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+func marshalConnectorMapping(conn LeadSourceConnector) (fieldMappings, serviceTypeRules, defaultValues []byte, err error) {
+	if fieldMappings, err = json.Marshal(conn.FieldMappings); err != nil {
+		return nil, nil, nil, err
+	}
+	if serviceTypeRules, err = json.Marshal(conn.ServiceTypeRules); err != nil {
+		return nil, nil, nil, err
+	}
+	if defaultValues, err = json.Marshal(conn.DefaultValues); err != nil {
+		return nil, nil, nil, err
+	}
+	return fieldMappings, serviceTypeRules, defaultValues, nil
+}
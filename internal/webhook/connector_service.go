@@ -0,0 +1,358 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/secrets"
+
+	"github.com/google/uuid"
+)
+
+const (
+	connectorSourcePrefix      = "connector-"
+	defaultPollIntervalMinutes = 15
+	defaultDuplicateWindowHrs  = 720
+	connectorLeadsLimit        = 100
+	maxPollResponseBytes       = 5 << 20
+)
+
+// SetConnectorKeyring sets the keyring that encrypts the marketplace API tokens
+// of polling connectors.
+func (s *Service) SetConnectorKeyring(keyring *secrets.Keyring) {
+	s.connectorKeyring = keyring
+}
+
+// GenerateConnectorSecret creates the secret a marketplace sends along with
+// webhook deliveries. Only its hash is stored.
+func GenerateConnectorSecret() (plaintext string, hash string, prefix string, err error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", "", err
+	}
+	plaintext = "lsc_" + hex.EncodeToString(bytes)
+	return plaintext, HashKey(plaintext), plaintext[:12], nil
+}
+
+// ListConnectors returns the lead source connectors of an organization.
+func (s *Service) ListConnectors(ctx context.Context, orgID uuid.UUID) ([]LeadSourceConnector, error) {
+	return s.repo.ListConnectors(ctx, orgID)
+}
+
+// CreateConnector connects a lead marketplace and returns the connector with
+// its webhook secret.
+func (s *Service) CreateConnector(ctx context.Context, orgID uuid.UUID, req CreateConnectorRequest) (LeadSourceConnector, string, error) {
+	adapter, ok := LookupLeadSourceAdapter(req.Provider)
+	if !ok {
+		return LeadSourceConnector{}, "", apperr.Validation(fmt.Sprintf("unknown lead source provider %q", req.Provider))
+	}
+	if !adapter.SupportsMode(req.Mode) {
+		return LeadSourceConnector{}, "", apperr.Validation(fmt.Sprintf("%s does not support %s ingestion", adapter.DisplayName(), req.Mode))
+	}
+
+	plaintext, hash, prefix, err := GenerateConnectorSecret()
+	if err != nil {
+		return LeadSourceConnector{}, "", fmt.Errorf("generate connector secret: %w", err)
+	}
+	conn := LeadSourceConnector{
+		OrganizationID:       orgID,
+		Provider:             adapter.Provider(),
+		Name:                 strings.TrimSpace(req.Name),
+		Mode:                 req.Mode,
+		IsActive:             true,
+		SecretHash:           hash,
+		SecretPrefix:         prefix,
+		PollIntervalMinutes:  defaultPollIntervalMinutes,
+		DuplicateWindowHours: defaultDuplicateWindowHrs,
+	}
+	if err := s.applyConnectorSettings(&conn, req.UpdateConnectorRequest); err != nil {
+		return LeadSourceConnector{}, "", err
+	}
+
+	created, err := s.repo.CreateConnector(ctx, conn)
+	if err != nil {
+		return LeadSourceConnector{}, "", err
+	}
+	return created, plaintext, nil
+}
+
+// UpdateConnector replaces the settings of a connector. Provider and mode are fixed.
+func (s *Service) UpdateConnector(ctx context.Context, id, orgID uuid.UUID, req UpdateConnectorRequest) (LeadSourceConnector, error) {
+	conn, err := s.repo.GetConnector(ctx, id, orgID)
+	if err != nil {
+		return LeadSourceConnector{}, err
+	}
+	if err := s.applyConnectorSettings(&conn, req); err != nil {
+		return LeadSourceConnector{}, err
+	}
+	return s.repo.UpdateConnector(ctx, conn)
+}
+
+// DeleteConnector disconnects a lead marketplace.
+func (s *Service) DeleteConnector(ctx context.Context, id, orgID uuid.UUID) error {
+	return s.repo.DeleteConnector(ctx, id, orgID)
+}
+
+// ListConnectorLeads returns the recently received leads of a connector.
+func (s *Service) ListConnectorLeads(ctx context.Context, id, orgID uuid.UUID) ([]ConnectorLead, error) {
+	if _, err := s.repo.GetConnector(ctx, id, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListConnectorLeads(ctx, id, orgID, connectorLeadsLimit)
+}
+
+// IngestConnectorWebhook creates the leads of a webhook delivery.
+func (s *Service) IngestConnectorWebhook(ctx context.Context, conn LeadSourceConnector, body []byte) (ConnectorIngestResult, error) {
+	adapter, ok := LookupLeadSourceAdapter(conn.Provider)
+	if !ok {
+		return ConnectorIngestResult{}, fmt.Errorf("no adapter for lead source provider %q", conn.Provider)
+	}
+	if !conn.IsActive || conn.Mode != ConnectorModeWebhook {
+		return ConnectorIngestResult{}, apperr.Forbidden("connector does not accept webhook deliveries")
+	}
+	payloads, err := adapter.DecodeWebhook(body)
+	if err != nil {
+		return ConnectorIngestResult{}, apperr.Validation(err.Error())
+	}
+	return s.ingestConnectorLeads(ctx, conn, adapter, payloads), nil
+}
+
+// PollConnector fetches new leads of a polling connector right away.
+func (s *Service) PollConnector(ctx context.Context, id, orgID uuid.UUID) (ConnectorIngestResult, error) {
+	conn, err := s.repo.GetConnector(ctx, id, orgID)
+	if err != nil {
+		return ConnectorIngestResult{}, err
+	}
+	if conn.Mode != ConnectorModePolling {
+		return ConnectorIngestResult{}, apperr.Validation("only polling connectors can be polled")
+	}
+	return s.pollConnector(ctx, conn, time.Now())
+}
+
+// PollDueConnectors polls every active polling connector whose interval has
+// passed. A failing connector is recorded on the connector and does not stop the others.
+func (s *Service) PollDueConnectors(ctx context.Context, now time.Time) (ConnectorIngestResult, error) {
+	connectors, err := s.repo.ListDueConnectors(ctx, now)
+	if err != nil {
+		return ConnectorIngestResult{}, err
+	}
+	var total ConnectorIngestResult
+	for _, conn := range connectors {
+		connCtx, err := httpkit.BindTenant(ctx, conn.OrganizationID)
+		if err != nil {
+			s.log.Error("webhook: failed to bind connector organization", "error", err, "connectorId", conn.ID)
+			continue
+		}
+		result, err := s.pollConnector(connCtx, conn, now)
+		if err != nil {
+			s.log.Error("webhook: connector poll failed", "error", err, "connectorId", conn.ID, "provider", conn.Provider)
+		}
+		total.Received += result.Received
+		total.Created += result.Created
+		total.Duplicates += result.Duplicates
+		total.Failed += result.Failed
+	}
+	return total, nil
+}
+
+func (s *Service) pollConnector(ctx context.Context, conn LeadSourceConnector, now time.Time) (ConnectorIngestResult, error) {
+	cursor := ""
+	if conn.PollCursor != nil {
+		cursor = *conn.PollCursor
+	}
+	result, nextCursor, err := s.fetchConnectorLeads(ctx, conn, cursor)
+	if err != nil {
+		errText := err.Error()
+		if recordErr := s.repo.RecordConnectorPoll(ctx, conn.ID, nil, now, &errText); recordErr != nil {
+			s.log.Error("webhook: failed to record connector poll", "error", recordErr, "connectorId", conn.ID)
+		}
+		return result, err
+	}
+	if err := s.repo.RecordConnectorPoll(ctx, conn.ID, &nextCursor, now, nil); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (s *Service) fetchConnectorLeads(ctx context.Context, conn LeadSourceConnector, cursor string) (ConnectorIngestResult, string, error) {
+	adapter, ok := LookupLeadSourceAdapter(conn.Provider)
+	if !ok {
+		return ConnectorIngestResult{}, cursor, fmt.Errorf("no adapter for lead source provider %q", conn.Provider)
+	}
+	if conn.APIBaseURL == nil || *conn.APIBaseURL == "" {
+		return ConnectorIngestResult{}, cursor, errors.New("connector has no API base URL")
+	}
+	token, err := s.connectorAPIToken(conn)
+	if err != nil {
+		return ConnectorIngestResult{}, cursor, err
+	}
+
+	req, err := adapter.NewPollRequest(ctx, *conn.APIBaseURL, token, cursor)
+	if err != nil {
+		return ConnectorIngestResult{}, cursor, fmt.Errorf("build poll request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return ConnectorIngestResult{}, cursor, fmt.Errorf("poll %s: %w", adapter.DisplayName(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ConnectorIngestResult{}, cursor, fmt.Errorf("poll %s: unexpected status %d", adapter.DisplayName(), resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPollResponseBytes))
+	if err != nil {
+		return ConnectorIngestResult{}, cursor, fmt.Errorf("read %s response: %w", adapter.DisplayName(), err)
+	}
+
+	payloads, nextCursor, err := adapter.DecodePoll(body, cursor)
+	if err != nil {
+		return ConnectorIngestResult{}, cursor, err
+	}
+	return s.ingestConnectorLeads(ctx, conn, adapter, payloads), nextCursor, nil
+}
+
+// ingestConnectorLeads creates a lead for every marketplace lead that was not
+// received before and does not match a recent lead of the organization.
+func (s *Service) ingestConnectorLeads(ctx context.Context, conn LeadSourceConnector, adapter LeadSourceAdapter, payloads []map[string]any) ConnectorIngestResult {
+	cfg := connectorMappingConfig(adapter, conn)
+	result := ConnectorIngestResult{Received: len(payloads)}
+	for _, payload := range payloads {
+		switch s.ingestConnectorLead(ctx, conn, adapter, cfg, payload) {
+		case ConnectorLeadCreated:
+			result.Created++
+		case ConnectorLeadDuplicate:
+			result.Duplicates++
+		default:
+			result.Failed++
+		}
+	}
+	return result
+}
+
+func (s *Service) ingestConnectorLead(ctx context.Context, conn LeadSourceConnector, adapter LeadSourceAdapter, cfg FieldMappingConfig, payload map[string]any) string {
+	externalID := adapter.ExternalID(payload)
+	if externalID == "" {
+		s.log.Error("webhook: connector lead without external ID", "connectorId", conn.ID, "provider", conn.Provider)
+		return ConnectorLeadFailed
+	}
+	recordID, claimed, err := s.repo.ClaimConnectorLead(ctx, conn, externalID, payload)
+	if err != nil {
+		s.log.Error("webhook: failed to claim connector lead", "error", err, "connectorId", conn.ID, "externalId", externalID)
+		return ConnectorLeadFailed
+	}
+	if !claimed {
+		return ConnectorLeadDuplicate
+	}
+
+	status, leadID, errText := s.createConnectorLead(ctx, conn, adapter, cfg, payload)
+	if err := s.repo.FinishConnectorLead(ctx, recordID, status, leadID, errText); err != nil {
+		s.log.Error("webhook: failed to finish connector lead", "error", err, "connectorId", conn.ID, "externalId", externalID)
+	}
+	return status
+}
+
+func (s *Service) createConnectorLead(ctx context.Context, conn LeadSourceConnector, adapter LeadSourceAdapter, cfg FieldMappingConfig, payload map[string]any) (string, *uuid.UUID, *string) {
+	fields := flattenPayloadFields(payload)
+	extracted := ApplyFieldMapping(cfg, fields, payload)
+
+	if conn.DuplicateWindowHours > 0 {
+		window := time.Duration(conn.DuplicateWindowHours) * time.Hour
+		dupID, err := s.repo.FindRecentDuplicateLead(ctx, conn.OrganizationID, extracted.Email, extracted.Phone, window)
+		if err != nil {
+			// Better a duplicate than a lost lead, as for form submissions.
+			s.log.Error("webhook: failed to check connector lead for duplicates", "error", err, "connectorId", conn.ID)
+		} else if dupID != nil {
+			return ConnectorLeadDuplicate, dupID, nil
+		}
+	}
+
+	sub := FormSubmission{
+		Fields:       fields,
+		SourceDomain: connectorSourcePrefix + adapter.Provider(),
+		RawJSON:      payload,
+	}
+	lead, err := s.createSubmissionLead(ctx, sub, extracted, conn.OrganizationID)
+	if err != nil {
+		errText := err.Error()
+		return ConnectorLeadFailed, nil, &errText
+	}
+	return ConnectorLeadCreated, &lead.ID, nil
+}
+
+// applyConnectorSettings validates req and writes it onto conn.
+func (s *Service) applyConnectorSettings(conn *LeadSourceConnector, req UpdateConnectorRequest) error {
+	cfg := NewFieldMappingConfig(conn.OrganizationID, uuid.Nil, UpsertFieldMappingRequest{
+		FieldMappings:    req.FieldMappings,
+		ServiceTypeRules: req.ServiceTypeRules,
+		DefaultValues:    req.DefaultValues,
+	})
+	if err := ValidateFieldMappingConfig(cfg); err != nil {
+		return err
+	}
+	conn.FieldMappings = cfg.FieldMappings
+	conn.ServiceTypeRules = cfg.ServiceTypeRules
+	conn.DefaultValues = cfg.DefaultValues
+
+	if req.IsActive != nil {
+		conn.IsActive = *req.IsActive
+	}
+	if req.PollIntervalMinutes > 0 {
+		conn.PollIntervalMinutes = req.PollIntervalMinutes
+	}
+	if req.DuplicateWindowHours != nil {
+		conn.DuplicateWindowHours = *req.DuplicateWindowHours
+	}
+	conn.APIBaseURL = nil
+	if baseURL := strings.TrimSpace(req.APIBaseURL); baseURL != "" {
+		conn.APIBaseURL = &baseURL
+	}
+	if req.APIToken != nil {
+		if err := s.setConnectorAPIToken(conn, strings.TrimSpace(*req.APIToken)); err != nil {
+			return err
+		}
+	}
+
+	if conn.Mode == ConnectorModePolling && conn.APIBaseURL == nil {
+		return apperr.Validation("apiBaseUrl is required for polling connectors")
+	}
+	return nil
+}
+
+func (s *Service) setConnectorAPIToken(conn *LeadSourceConnector, token string) error {
+	if token == "" {
+		conn.APITokenEncrypted = nil
+		return nil
+	}
+	if s.connectorKeyring == nil {
+		return apperr.Validation("storing marketplace API tokens is not configured")
+	}
+	encrypted, err := s.connectorKeyring.Encrypt(token)
+	if err != nil {
+		return fmt.Errorf("encrypt connector API token: %w", err)
+	}
+	conn.APITokenEncrypted = &encrypted
+	return nil
+}
+
+func (s *Service) connectorAPIToken(conn LeadSourceConnector) (string, error) {
+	if conn.APITokenEncrypted == nil || *conn.APITokenEncrypted == "" {
+		return "", nil
+	}
+	if s.connectorKeyring == nil {
+		return "", errors.New("connector API token cannot be decrypted: no keyring configured")
+	}
+	token, err := s.connectorKeyring.Decrypt(*conn.APITokenEncrypted)
+	if err != nil {
+		return "", fmt.Errorf("decrypt connector API token: %w", err)
+	}
+	return token, nil
+}
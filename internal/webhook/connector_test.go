@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBobexWebhookDecodesSingleAndListedLeads(t *testing.T) {
+	adapter, ok := LookupLeadSourceAdapter("bobex")
+	if !ok {
+		t.Fatal("expected bobex adapter to be registered")
+	}
+
+	single, err := adapter.DecodeWebhook([]byte(`{"id": 42, "description": "Dakkapel"}`))
+	if err != nil || len(single) != 1 {
+		t.Fatalf("expected one lead, got %d (err %v)", len(single), err)
+	}
+	if got := adapter.ExternalID(single[0]); got != "42" {
+		t.Fatalf("expected external ID 42, got %q", got)
+	}
+
+	listed, err := adapter.DecodeWebhook([]byte(`{"data": [{"id": "a"}, {"id": "b"}]}`))
+	if err != nil || len(listed) != 2 {
+		t.Fatalf("expected two leads, got %d (err %v)", len(listed), err)
+	}
+}
+
+func TestSolvariPollAdvancesCursorToHighestLeadID(t *testing.T) {
+	adapter, _ := LookupLeadSourceAdapter("solvari")
+
+	leads, cursor, err := adapter.DecodePoll([]byte(`{"leads": [{"lead_id": 105}, {"lead_id": 112}, {"lead_id": 108}]}`), "100")
+	if err != nil {
+		t.Fatalf("decode poll: %v", err)
+	}
+	if len(leads) != 3 || cursor != "112" {
+		t.Fatalf("expected 3 leads and cursor 112, got %d and %q", len(leads), cursor)
+	}
+
+	_, cursor, err = adapter.DecodePoll([]byte(`{"leads": []}`), "112")
+	if err != nil || cursor != "112" {
+		t.Fatalf("expected an empty page to keep the cursor, got %q (err %v)", cursor, err)
+	}
+}
+
+func TestConnectorMappingConfigOverridesProviderDefaults(t *testing.T) {
+	adapter, _ := LookupLeadSourceAdapter("bobex")
+	conn := LeadSourceConnector{
+		OrganizationID: uuid.New(),
+		FieldMappings:  map[string][]string{MappedFieldMessage: {"notes"}},
+	}
+	raw := map[string]any{
+		"id":          "7",
+		"description": "ignored",
+		"notes":       "Graag offerte voor zonnepanelen",
+		"contact": map[string]any{
+			"first_name": "Anna",
+			"last_name":  "Jansen",
+			"email":      "anna@example.nl",
+		},
+		"address": map[string]any{"postal_code": "1234AB", "house_number": "12"},
+	}
+
+	got := ApplyFieldMapping(connectorMappingConfig(adapter, conn), flattenPayloadFields(raw), raw)
+
+	if got.FirstName != "Anna" || got.LastName != "Jansen" || got.Email != "anna@example.nl" {
+		t.Fatalf("unexpected contact fields: %+v", got)
+	}
+	if got.HouseNumber != "12" {
+		t.Fatalf("expected house number from default mapping, got %q", got.HouseNumber)
+	}
+	if got.Message != "Graag offerte voor zonnepanelen" {
+		t.Fatalf("expected connector override for message, got %q", got.Message)
+	}
+	if paths := adapter.DefaultMapping()[MappedFieldMessage]; len(paths) != 1 || paths[0] != "description" {
+		t.Fatalf("expected the provider default mapping to be left untouched, got %v", paths)
+	}
+}
@@ -167,8 +167,8 @@ SELECT id
 FROM RAC_leads
 WHERE organization_id = $1
 	AND created_at >= now() - make_interval(secs => $2)
-	AND (CAST($3 AS text) = '' OR consumer_email = CAST($3 AS text))
-	AND (CAST($4 AS text) = '' OR consumer_phone = CAST($4 AS text))
+	AND (CAST($3 AS text) = '' OR consumer_email = CAST($3 AS text) OR consumer_email_bidx = CAST($5 AS text))
+	AND (CAST($4 AS text) = '' OR consumer_phone = CAST($4 AS text) OR consumer_phone_bidx = CAST($6 AS text))
 ORDER BY created_at DESC
 LIMIT 1
 `
@@ -178,6 +178,8 @@ type FindRecentDuplicateLeadParams struct {
 	Secs           float64     `json:"secs"`
 	Column3        string      `json:"column_3"`
 	Column4        string      `json:"column_4"`
+	Column5        string      `json:"column_5"`
+	Column6        string      `json:"column_6"`
 }
 
 func (q *Queries) FindRecentDuplicateLead(ctx context.Context, arg FindRecentDuplicateLeadParams) (pgtype.UUID, error) {
@@ -186,6 +188,8 @@ func (q *Queries) FindRecentDuplicateLead(ctx context.Context, arg FindRecentDup
 		arg.Secs,
 		arg.Column3,
 		arg.Column4,
+		arg.Column5,
+		arg.Column6,
 	)
 	var id pgtype.UUID
 	err := row.Scan(&id)
@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -18,6 +19,7 @@ type ExtractedFields struct {
 	Message       string
 	ServiceType   string // Matched against known service type slugs/keywords
 	GCLID         string
+	FBCLID        string
 	UTMSource     string
 	UTMMedium     string
 	UTMCampaign   string
@@ -25,6 +27,8 @@ type ExtractedFields struct {
 	UTMTerm       string
 	AdLandingPage string
 	ReferrerURL   string
+	// CustomFields holds mapped custom field values by key, as received.
+	CustomFields map[string]string
 }
 
 // IsIncomplete returns true if minimum required fields (name + at least one contact method) are missing.
@@ -94,6 +98,8 @@ func applyExtractedField(result *ExtractedFields, key, value string) {
 		}
 	case matchesAny(key, gclidPatterns):
 		result.GCLID = value
+	case matchesAny(key, fbclidPatterns):
+		result.FBCLID = value
 	case matchesAny(key, utmSourcePatterns):
 		result.UTMSource = value
 	case matchesAny(key, utmMediumPatterns):
@@ -126,6 +132,7 @@ var (
 	addressPatterns     = []string{"address", "adres", "full_address", "fulladdress"}
 	serviceTypePatterns = []string{"service", "dienst", "project_type", "projecttype", "service_type", "servicetype", "type", "werkzaamheden", "soort", "category", "categorie", "product"}
 	gclidPatterns       = []string{"gclid", "google_click_id", "googleclickid"}
+	fbclidPatterns      = []string{"fbclid", "facebook_click_id", "facebookclickid"}
 	utmSourcePatterns   = []string{"utm_source", "utmsource"}
 	utmMediumPatterns   = []string{"utm_medium", "utmmedium"}
 	utmCampaignPatterns = []string{"utm_campaign", "utmcampaign"}
@@ -152,6 +159,35 @@ func matchesAny(label string, patterns []string) bool {
 	return false
 }
 
+// fillAttributionFromURLs fills click IDs and UTM parameters that the form did not
+// send as separate fields from the query string of the landing page, falling back
+// to the referrer.
+func fillAttributionFromURLs(result *ExtractedFields) {
+	for _, raw := range []string{result.AdLandingPage, result.ReferrerURL} {
+		if raw == "" {
+			continue
+		}
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		query := parsed.Query()
+		fillIfEmpty(&result.GCLID, query.Get("gclid"))
+		fillIfEmpty(&result.FBCLID, query.Get("fbclid"))
+		fillIfEmpty(&result.UTMSource, query.Get("utm_source"))
+		fillIfEmpty(&result.UTMMedium, query.Get("utm_medium"))
+		fillIfEmpty(&result.UTMCampaign, query.Get("utm_campaign"))
+		fillIfEmpty(&result.UTMContent, query.Get("utm_content"))
+		fillIfEmpty(&result.UTMTerm, query.Get("utm_term"))
+	}
+}
+
+func fillIfEmpty(target *string, value string) {
+	if *target == "" {
+		*target = strings.TrimSpace(value)
+	}
+}
+
 func normalizePhone(value string) string {
 	// Remove common formatting characters
 	cleaned := strings.Map(func(r rune) rune {
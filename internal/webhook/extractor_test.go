@@ -0,0 +1,32 @@
+package webhook
+
+import "testing"
+
+func TestExtractFieldsCapturesClickIDs(t *testing.T) {
+	result := ExtractFields(map[string]string{
+		"gclid":      "gclid-123",
+		"fbclid":     "fbclid-456",
+		"utm_source": "facebook",
+	})
+	if result.GCLID != "gclid-123" || result.FBCLID != "fbclid-456" || result.UTMSource != "facebook" {
+		t.Fatalf("unexpected attribution: %+v", result)
+	}
+}
+
+func TestBuildCreateLeadRequestReadsAttributionFromLandingPage(t *testing.T) {
+	extracted := ExtractFields(map[string]string{
+		"utm_source":   "newsletter",
+		"landing_page": "https://example.nl/offerte?utm_source=facebook&utm_medium=cpc&utm_campaign=warmtepomp&fbclid=abc",
+	})
+
+	req := buildCreateLeadRequest(extracted, "example.nl")
+	if req.UTMSource != "newsletter" {
+		t.Fatalf("UTMSource = %q, want form value to win", req.UTMSource)
+	}
+	if req.UTMMedium != "cpc" || req.UTMCampaign != "warmtepomp" || req.FBCLID != "abc" {
+		t.Fatalf("unexpected attribution: %+v", req)
+	}
+	if req.GCLID != "" {
+		t.Fatalf("GCLID = %q, want empty", req.GCLID)
+	}
+}
@@ -0,0 +1,503 @@
+package webhook
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Lead fields a webhook field mapping may target.
+const (
+	MappedFieldFirstName     = "firstName"
+	MappedFieldLastName      = "lastName"
+	MappedFieldFullName      = "fullName"
+	MappedFieldEmail         = "email"
+	MappedFieldPhone         = "phone"
+	MappedFieldStreet        = "street"
+	MappedFieldHouseNumber   = "houseNumber"
+	MappedFieldZipCode       = "zipCode"
+	MappedFieldCity          = "city"
+	MappedFieldAddress       = "address"
+	MappedFieldMessage       = "message"
+	MappedFieldServiceType   = "serviceType"
+	MappedFieldGCLID         = "gclid"
+	MappedFieldFBCLID        = "fbclid"
+	MappedFieldUTMSource     = "utmSource"
+	MappedFieldUTMMedium     = "utmMedium"
+	MappedFieldUTMCampaign   = "utmCampaign"
+	MappedFieldUTMContent    = "utmContent"
+	MappedFieldUTMTerm       = "utmTerm"
+	MappedFieldAdLandingPage = "adLandingPage"
+	MappedFieldReferrerURL   = "referrerUrl"
+)
+
+// CustomFieldPrefix marks a mapping target as an organization-defined custom
+// field, e.g. "custom.roof_type".
+const CustomFieldPrefix = "custom."
+
+// Operators supported by service type resolution rules.
+const (
+	RuleOperatorEquals   = "equals"
+	RuleOperatorContains = "contains"
+	RuleOperatorPrefix   = "prefix"
+)
+
+var mappableLeadFields = map[string]struct{}{
+	MappedFieldFirstName: {}, MappedFieldLastName: {}, MappedFieldFullName: {}, MappedFieldEmail: {},
+	MappedFieldPhone: {}, MappedFieldStreet: {}, MappedFieldHouseNumber: {}, MappedFieldZipCode: {},
+	MappedFieldCity: {}, MappedFieldAddress: {}, MappedFieldMessage: {}, MappedFieldServiceType: {},
+	MappedFieldGCLID: {}, MappedFieldFBCLID: {}, MappedFieldUTMSource: {}, MappedFieldUTMMedium: {}, MappedFieldUTMCampaign: {},
+	MappedFieldUTMContent: {}, MappedFieldUTMTerm: {}, MappedFieldAdLandingPage: {}, MappedFieldReferrerURL: {},
+}
+
+// FieldMappingConfig describes how the payload of one webhook source is mapped onto lead fields.
+type FieldMappingConfig struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	APIKeyID       uuid.UUID `json:"apiKeyId"`
+	// FieldMappings maps a lead field to an ordered list of candidate source paths.
+	// A path is either a form field name or a dot-separated JSON path ("contact.email", "answers.0.value").
+	FieldMappings    map[string][]string `json:"fieldMappings"`
+	ServiceTypeRules []ServiceTypeRule   `json:"serviceTypeRules"`
+	DefaultValues    map[string]string   `json:"defaultValues"`
+	// FallbackToHeuristics keeps the built-in label matching for fields that are not mapped explicitly.
+	FallbackToHeuristics bool      `json:"fallbackToHeuristics"`
+	IsActive             bool      `json:"isActive"`
+	CreatedAt            time.Time `json:"createdAt"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
+
+// ServiceTypeRule resolves the service type from a source value. Rules are evaluated in order; first match wins.
+type ServiceTypeRule struct {
+	SourcePath  string `json:"sourcePath" validate:"required,max=200"`
+	Operator    string `json:"operator" validate:"required,oneof=equals contains prefix"`
+	Value       string `json:"value" validate:"required,max=200"`
+	ServiceType string `json:"serviceType" validate:"required,max=100"`
+}
+
+// UpsertFieldMappingRequest is the admin request body for saving a field mapping.
+type UpsertFieldMappingRequest struct {
+	FieldMappings        map[string][]string `json:"fieldMappings" validate:"max=30,dive,min=1,max=10,dive,required,max=200"`
+	ServiceTypeRules     []ServiceTypeRule   `json:"serviceTypeRules" validate:"max=50,dive"`
+	DefaultValues        map[string]string   `json:"defaultValues" validate:"max=30,dive,max=500"`
+	FallbackToHeuristics *bool               `json:"fallbackToHeuristics,omitempty"`
+	IsActive             *bool               `json:"isActive,omitempty"`
+}
+
+// PreviewFieldMappingRequest runs a sample payload through a mapping without creating a lead.
+// When Mapping is omitted the stored mapping of the API key is used.
+type PreviewFieldMappingRequest struct {
+	Payload map[string]any             `json:"payload" validate:"required"`
+	Mapping *UpsertFieldMappingRequest `json:"mapping,omitempty"`
+}
+
+// FieldMappingPreview is the result of applying a mapping to a sample payload.
+type FieldMappingPreview struct {
+	Extracted           map[string]string `json:"extractedFields"`
+	Sources             map[string]string `json:"sources"`
+	ResolvedServiceType string            `json:"resolvedServiceType"`
+	MatchedRuleIndex    *int              `json:"matchedRuleIndex,omitempty"`
+	IsIncomplete        bool              `json:"isIncomplete"`
+	MissingRequired     []string          `json:"missingRequired"`
+	Warnings            []string          `json:"warnings"`
+}
+
+// mappingTrace records where each mapped value originated, for previews.
+type mappingTrace struct {
+	sources          map[string]string
+	matchedRuleIndex *int
+	unresolvedPaths  []string
+}
+
+// NewFieldMappingConfig builds a mapping config from an admin request, applying defaults.
+func NewFieldMappingConfig(orgID, apiKeyID uuid.UUID, req UpsertFieldMappingRequest) FieldMappingConfig {
+	cfg := FieldMappingConfig{
+		OrganizationID:       orgID,
+		APIKeyID:             apiKeyID,
+		FieldMappings:        req.FieldMappings,
+		ServiceTypeRules:     req.ServiceTypeRules,
+		DefaultValues:        req.DefaultValues,
+		FallbackToHeuristics: true,
+		IsActive:             true,
+	}
+	if req.FallbackToHeuristics != nil {
+		cfg.FallbackToHeuristics = *req.FallbackToHeuristics
+	}
+	if req.IsActive != nil {
+		cfg.IsActive = *req.IsActive
+	}
+	if cfg.FieldMappings == nil {
+		cfg.FieldMappings = map[string][]string{}
+	}
+	if cfg.ServiceTypeRules == nil {
+		cfg.ServiceTypeRules = []ServiceTypeRule{}
+	}
+	if cfg.DefaultValues == nil {
+		cfg.DefaultValues = map[string]string{}
+	}
+	return cfg
+}
+
+// ValidateFieldMappingConfig checks that a mapping only targets known lead fields or custom fields.
+func ValidateFieldMappingConfig(cfg FieldMappingConfig) error {
+	for field, paths := range cfg.FieldMappings {
+		if !isMappableField(field) {
+			return apperr.Validation(fmt.Sprintf("unknown lead field %q in fieldMappings", field))
+		}
+		for _, path := range paths {
+			if strings.TrimSpace(path) == "" {
+				return apperr.Validation(fmt.Sprintf("empty source path for lead field %q", field))
+			}
+		}
+	}
+	for field := range cfg.DefaultValues {
+		if !isMappableField(field) {
+			return apperr.Validation(fmt.Sprintf("unknown lead field %q in defaultValues", field))
+		}
+	}
+	for i, rule := range cfg.ServiceTypeRules {
+		switch rule.Operator {
+		case RuleOperatorEquals, RuleOperatorContains, RuleOperatorPrefix:
+		default:
+			return apperr.Validation(fmt.Sprintf("serviceTypeRules[%d]: unsupported operator %q", i, rule.Operator))
+		}
+		if strings.TrimSpace(rule.SourcePath) == "" || strings.TrimSpace(rule.ServiceType) == "" {
+			return apperr.Validation(fmt.Sprintf("serviceTypeRules[%d]: sourcePath and serviceType are required", i))
+		}
+	}
+	return nil
+}
+
+// isMappableField reports whether field is a known lead field or a custom field
+// target. Custom field keys are checked against the definitions when the lead
+// is stored, since definitions may change after the mapping is saved.
+func isMappableField(field string) bool {
+	if key, ok := strings.CutPrefix(field, CustomFieldPrefix); ok {
+		return key != ""
+	}
+	_, ok := mappableLeadFields[field]
+	return ok
+}
+
+// ApplyFieldMapping extracts lead fields from a submission using the configured mapping.
+func ApplyFieldMapping(cfg FieldMappingConfig, fields map[string]string, raw map[string]any) ExtractedFields {
+	result, _ := applyFieldMapping(cfg, fields, raw)
+	return result
+}
+
+// PreviewFieldMapping applies a mapping to a sample payload and reports how each field was resolved.
+func PreviewFieldMapping(cfg FieldMappingConfig, payload map[string]any) FieldMappingPreview {
+	fields := flattenPayloadFields(payload)
+	extracted, trace := applyFieldMapping(cfg, fields, payload)
+
+	missing := make([]string, 0, 2)
+	if extracted.FirstName == "" && extracted.LastName == "" {
+		missing = append(missing, "name")
+	}
+	if extracted.Phone == "" && extracted.Email == "" {
+		missing = append(missing, "phone|email")
+	}
+
+	warnings := make([]string, 0, len(trace.unresolvedPaths))
+	for _, path := range trace.unresolvedPaths {
+		warnings = append(warnings, fmt.Sprintf("source path %q not found in payload", path))
+	}
+
+	return FieldMappingPreview{
+		Extracted:           buildExtractedMap(extracted),
+		Sources:             trace.sources,
+		ResolvedServiceType: resolveServiceType(extracted.ServiceType),
+		MatchedRuleIndex:    trace.matchedRuleIndex,
+		IsIncomplete:        extracted.IsIncomplete(),
+		MissingRequired:     missing,
+		Warnings:            warnings,
+	}
+}
+
+func applyFieldMapping(cfg FieldMappingConfig, fields map[string]string, raw map[string]any) (ExtractedFields, mappingTrace) {
+	trace := mappingTrace{sources: map[string]string{}}
+
+	var result ExtractedFields
+	if cfg.FallbackToHeuristics {
+		result = ExtractFields(fields)
+	}
+
+	for _, field := range sortedMappingFields(cfg.FieldMappings) {
+		for _, path := range cfg.FieldMappings[field] {
+			value, ok := resolveSourcePath(path, fields, raw)
+			if !ok {
+				trace.unresolvedPaths = append(trace.unresolvedPaths, path)
+				continue
+			}
+			if setMappedField(&result, field, value) {
+				trace.sources[field] = path
+				break
+			}
+		}
+	}
+
+	for i, rule := range cfg.ServiceTypeRules {
+		value, ok := resolveSourcePath(rule.SourcePath, fields, raw)
+		if !ok || !ruleMatches(rule, value) {
+			continue
+		}
+		result.ServiceType = strings.TrimSpace(rule.ServiceType)
+		trace.sources[MappedFieldServiceType] = "rule:" + strconv.Itoa(i)
+		idx := i
+		trace.matchedRuleIndex = &idx
+		break
+	}
+
+	for _, field := range sortedDefaultFields(cfg.DefaultValues) {
+		if mappedFieldValue(result, field) != "" {
+			continue
+		}
+		if setMappedField(&result, field, cfg.DefaultValues[field]) {
+			trace.sources[field] = "default"
+		}
+	}
+
+	if result.FirstName != "" && result.LastName == "" && strings.Contains(result.FirstName, " ") {
+		parts := strings.SplitN(result.FirstName, " ", 2)
+		result.FirstName = parts[0]
+		result.LastName = parts[1]
+	}
+
+	return result, trace
+}
+
+func ruleMatches(rule ServiceTypeRule, value string) bool {
+	v := strings.ToLower(strings.TrimSpace(value))
+	expected := strings.ToLower(strings.TrimSpace(rule.Value))
+	switch rule.Operator {
+	case RuleOperatorEquals:
+		return v == expected
+	case RuleOperatorContains:
+		return strings.Contains(v, expected)
+	case RuleOperatorPrefix:
+		return strings.HasPrefix(v, expected)
+	default:
+		return false
+	}
+}
+
+// setMappedField writes a normalized value to the target lead field. It returns false when the value was rejected.
+func setMappedField(result *ExtractedFields, field, value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+	if key, ok := strings.CutPrefix(field, CustomFieldPrefix); ok {
+		if key == "" {
+			return false
+		}
+		if result.CustomFields == nil {
+			result.CustomFields = map[string]string{}
+		}
+		result.CustomFields[key] = value
+		return true
+	}
+	switch field {
+	case MappedFieldFirstName:
+		result.FirstName = value
+	case MappedFieldLastName:
+		result.LastName = value
+	case MappedFieldFullName:
+		parts := strings.SplitN(value, " ", 2)
+		result.FirstName = parts[0]
+		result.LastName = ""
+		if len(parts) > 1 {
+			result.LastName = parts[1]
+		}
+	case MappedFieldEmail:
+		if !emailRegex.MatchString(value) {
+			return false
+		}
+		result.Email = value
+	case MappedFieldPhone:
+		result.Phone = normalizePhone(value)
+	case MappedFieldStreet:
+		result.Street = value
+	case MappedFieldHouseNumber:
+		result.HouseNumber = value
+	case MappedFieldZipCode:
+		result.ZipCode = normalizeZipCode(value)
+	case MappedFieldCity:
+		result.City = value
+	case MappedFieldAddress:
+		parseFullAddress(value, result)
+	case MappedFieldMessage:
+		result.Message = value
+	case MappedFieldServiceType:
+		if st := matchServiceType(value); st != "" {
+			result.ServiceType = st
+		} else {
+			result.ServiceType = value
+		}
+	case MappedFieldGCLID:
+		result.GCLID = value
+	case MappedFieldFBCLID:
+		result.FBCLID = value
+	case MappedFieldUTMSource:
+		result.UTMSource = value
+	case MappedFieldUTMMedium:
+		result.UTMMedium = value
+	case MappedFieldUTMCampaign:
+		result.UTMCampaign = value
+	case MappedFieldUTMContent:
+		result.UTMContent = value
+	case MappedFieldUTMTerm:
+		result.UTMTerm = value
+	case MappedFieldAdLandingPage:
+		result.AdLandingPage = value
+	case MappedFieldReferrerURL:
+		result.ReferrerURL = value
+	default:
+		return false
+	}
+	return true
+}
+
+func mappedFieldValue(result ExtractedFields, field string) string {
+	if key, ok := strings.CutPrefix(field, CustomFieldPrefix); ok {
+		return result.CustomFields[key]
+	}
+	switch field {
+	case MappedFieldFirstName, MappedFieldFullName:
+		return result.FirstName
+	case MappedFieldLastName:
+		return result.LastName
+	case MappedFieldEmail:
+		return result.Email
+	case MappedFieldPhone:
+		return result.Phone
+	case MappedFieldStreet, MappedFieldAddress:
+		return result.Street
+	case MappedFieldHouseNumber:
+		return result.HouseNumber
+	case MappedFieldZipCode:
+		return result.ZipCode
+	case MappedFieldCity:
+		return result.City
+	case MappedFieldMessage:
+		return result.Message
+	case MappedFieldServiceType:
+		return result.ServiceType
+	case MappedFieldGCLID:
+		return result.GCLID
+	case MappedFieldFBCLID:
+		return result.FBCLID
+	case MappedFieldUTMSource:
+		return result.UTMSource
+	case MappedFieldUTMMedium:
+		return result.UTMMedium
+	case MappedFieldUTMCampaign:
+		return result.UTMCampaign
+	case MappedFieldUTMContent:
+		return result.UTMContent
+	case MappedFieldUTMTerm:
+		return result.UTMTerm
+	case MappedFieldAdLandingPage:
+		return result.AdLandingPage
+	case MappedFieldReferrerURL:
+		return result.ReferrerURL
+	default:
+		return ""
+	}
+}
+
+// resolveSourcePath looks a path up as a literal form field first, then as a dot-separated JSON path.
+func resolveSourcePath(path string, fields map[string]string, raw map[string]any) (string, bool) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", false
+	}
+	if value, ok := fields[path]; ok && strings.TrimSpace(value) != "" {
+		return value, true
+	}
+	if raw == nil {
+		return "", false
+	}
+
+	var current any = raw
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[segment]
+			if !ok {
+				return "", false
+			}
+			current = next
+		case []any:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return "", false
+			}
+			current = node[idx]
+		default:
+			return "", false
+		}
+	}
+
+	value := scalarToString(current)
+	return value, strings.TrimSpace(value) != ""
+}
+
+func scalarToString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := scalarToString(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return ""
+	}
+}
+
+// flattenPayloadFields mirrors how the form handler exposes top-level JSON scalars as form fields.
+func flattenPayloadFields(payload map[string]any) map[string]string {
+	fields := make(map[string]string, len(payload))
+	for key, value := range payload {
+		switch v := value.(type) {
+		case string:
+			fields[key] = v
+		case float64:
+			fields[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return fields
+}
+
+func sortedMappingFields(mappings map[string][]string) []string {
+	keys := make([]string, 0, len(mappings))
+	for key := range mappings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedDefaultFields(defaults map[string]string) []string {
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestMappingConfig(req UpsertFieldMappingRequest) FieldMappingConfig {
+	return NewFieldMappingConfig(uuid.New(), uuid.New(), req)
+}
+
+func TestApplyFieldMappingResolvesNestedPaths(t *testing.T) {
+	cfg := newTestMappingConfig(UpsertFieldMappingRequest{
+		FieldMappings: map[string][]string{
+			MappedFieldFullName: {"contact.name"},
+			MappedFieldEmail:    {"contact.emails.1", "contact.emails.0"},
+			MappedFieldPhone:    {"$.contact.phone"},
+			MappedFieldZipCode:  {"address.postcode"},
+		},
+	})
+	raw := map[string]any{
+		"contact": map[string]any{
+			"name":   "Jan de Vries",
+			"emails": []any{"jan@example.nl"},
+			"phone":  "06 12345678",
+		},
+		"address": map[string]any{"postcode": "1234ab"},
+	}
+
+	got := ApplyFieldMapping(cfg, flattenPayloadFields(raw), raw)
+
+	if got.FirstName != "Jan" || got.LastName != "de Vries" {
+		t.Fatalf("unexpected name split: %q %q", got.FirstName, got.LastName)
+	}
+	if got.Email != "jan@example.nl" {
+		t.Fatalf("expected fallback to second email candidate, got %q", got.Email)
+	}
+	if got.Phone != "+31612345678" {
+		t.Fatalf("expected normalized phone, got %q", got.Phone)
+	}
+	if got.ZipCode != "1234 AB" {
+		t.Fatalf("expected normalized zip code, got %q", got.ZipCode)
+	}
+}
+
+func TestApplyFieldMappingRulesAndDefaults(t *testing.T) {
+	disabled := false
+	cfg := newTestMappingConfig(UpsertFieldMappingRequest{
+		FieldMappings: map[string][]string{MappedFieldEmail: {"mail"}},
+		ServiceTypeRules: []ServiceTypeRule{
+			{SourcePath: "product", Operator: RuleOperatorPrefix, Value: "zon", ServiceType: "Zonnepanelen"},
+			{SourcePath: "product", Operator: RuleOperatorContains, Value: "panel", ServiceType: "Other"},
+		},
+		DefaultValues:        map[string]string{MappedFieldCity: "Utrecht", MappedFieldEmail: "fallback@example.nl"},
+		FallbackToHeuristics: &disabled,
+	})
+	fields := map[string]string{"mail": "klant@example.nl", "product": "Zonnepanelen 10x", "city": "Amsterdam"}
+
+	got := ApplyFieldMapping(cfg, fields, nil)
+
+	if got.ServiceType != "Zonnepanelen" {
+		t.Fatalf("expected first matching rule to win, got %q", got.ServiceType)
+	}
+	if got.Email != "klant@example.nl" {
+		t.Fatalf("expected mapped email to take precedence over default, got %q", got.Email)
+	}
+	if got.City != "Utrecht" {
+		t.Fatalf("expected default city without heuristics, got %q", got.City)
+	}
+}
+
+func TestPreviewFieldMappingReportsWarnings(t *testing.T) {
+	cfg := newTestMappingConfig(UpsertFieldMappingRequest{
+		FieldMappings: map[string][]string{MappedFieldPhone: {"lead.phone"}},
+	})
+
+	preview := PreviewFieldMapping(cfg, map[string]any{"name": "Piet Jansen"})
+
+	if !preview.IsIncomplete {
+		t.Fatal("expected preview without contact method to be incomplete")
+	}
+	if len(preview.Warnings) != 1 {
+		t.Fatalf("expected one unresolved path warning, got %v", preview.Warnings)
+	}
+	if len(preview.MissingRequired) != 1 || preview.MissingRequired[0] != "phone|email" {
+		t.Fatalf("unexpected missing fields: %v", preview.MissingRequired)
+	}
+}
+
+func TestValidateFieldMappingConfigRejectsUnknownField(t *testing.T) {
+	cfg := newTestMappingConfig(UpsertFieldMappingRequest{
+		FieldMappings: map[string][]string{"favouriteColour": {"colour"}},
+	})
+	if err := ValidateFieldMappingConfig(cfg); err == nil {
+		t.Fatal("expected validation error for unknown lead field")
+	}
+}
+
+func TestApplyFieldMappingCustomFields(t *testing.T) {
+	cfg := newTestMappingConfig(UpsertFieldMappingRequest{
+		FieldMappings: map[string][]string{CustomFieldPrefix + "roof_type": {"answers.roof"}},
+		DefaultValues: map[string]string{CustomFieldPrefix + "campaign": "spring"},
+	})
+	if err := ValidateFieldMappingConfig(cfg); err != nil {
+		t.Fatalf("ValidateFieldMappingConfig() error = %v", err)
+	}
+	raw := map[string]any{"answers": map[string]any{"roof": "Plat"}}
+
+	got := ApplyFieldMapping(cfg, flattenPayloadFields(raw), raw)
+
+	if got.CustomFields["roof_type"] != "Plat" || got.CustomFields["campaign"] != "spring" {
+		t.Fatalf("unexpected custom fields: %v", got.CustomFields)
+	}
+	if extracted := buildExtractedMap(got); extracted["custom.roof_type"] != "Plat" {
+		t.Fatalf("expected custom field in extracted map, got %v", extracted)
+	}
+
+	empty := newTestMappingConfig(UpsertFieldMappingRequest{
+		FieldMappings: map[string][]string{CustomFieldPrefix: {"colour"}},
+	})
+	if err := ValidateFieldMappingConfig(empty); err == nil {
+		t.Fatal("expected validation error for empty custom field key")
+	}
+}
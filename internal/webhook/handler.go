@@ -400,6 +400,9 @@ func (h *Handler) HandleGoogleLeadWebhook(c *gin.Context) {
 		httpkit.HandleError(c, err)
 		return
 	}
+	if !httpkit.BindTenantContext(c, config.OrganizationID) {
+		return
+	}
 
 	result, err := h.service.ProcessGoogleLeadWebhook(c.Request.Context(), payload, config)
 	if httpkit.HandleError(c, err) {
@@ -611,7 +614,7 @@ func (h *Handler) parseFormSubmission(c *gin.Context, apiKeyID interface{}) (For
 
 	fields := h.collectFormFields(c)
 	files := h.collectFormFiles(c)
-	h.collectJSONFields(c, fields)
+	rawJSON := h.collectJSONFields(c, fields)
 
 	if len(fields) == 0 && len(files) == 0 {
 		httpkit.Error(c, http.StatusBadRequest, "no form data received", nil)
@@ -622,6 +625,7 @@ func (h *Handler) parseFormSubmission(c *gin.Context, apiKeyID interface{}) (For
 		Fields:       fields,
 		Files:        files,
 		SourceDomain: c.GetHeader("Origin"),
+		RawJSON:      rawJSON,
 	}
 	if keyID, ok := apiKeyID.(uuid.UUID); ok {
 		submission.APIKeyID = keyID
@@ -670,14 +674,14 @@ func (h *Handler) collectFormFiles(c *gin.Context) []FormFile {
 	return files
 }
 
-func (h *Handler) collectJSONFields(c *gin.Context, fields map[string]string) {
+func (h *Handler) collectJSONFields(c *gin.Context, fields map[string]string) map[string]interface{} {
 	if c.ContentType() != "application/json" {
-		return
+		return nil
 	}
 
 	var jsonBody map[string]interface{}
 	if err := c.ShouldBindJSON(&jsonBody); err != nil {
-		return
+		return nil
 	}
 	for key, val := range jsonBody {
 		switch v := val.(type) {
@@ -687,4 +691,357 @@ func (h *Handler) collectJSONFields(c *gin.Context, fields map[string]string) {
 			fields[key] = strconv.FormatFloat(v, 'f', -1, 64)
 		}
 	}
+	return jsonBody
+}
+
+// HandleGetFieldMapping returns the field mapping configured for an API key.
+// GET /api/v1/admin/webhook/keys/:keyId/mapping
+func (h *Handler) HandleGetFieldMapping(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	keyID, ok := httpkit.ParseUUIDParam(c, "keyId")
+	if !ok {
+		return
+	}
+
+	cfg, err := h.service.GetFieldMapping(c.Request.Context(), keyID, tenantID)
+	if err != nil {
+		if err == ErrFieldMappingNotFound {
+			httpkit.Error(c, http.StatusNotFound, "field mapping not found", nil)
+			return
+		}
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	httpkit.OK(c, cfg)
+}
+
+// HandleUpsertFieldMapping creates or replaces the field mapping for an API key.
+// PUT /api/v1/admin/webhook/keys/:keyId/mapping
+func (h *Handler) HandleUpsertFieldMapping(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	keyID, ok := httpkit.ParseUUIDParam(c, "keyId")
+	if !ok {
+		return
+	}
+
+	req, ok := httpkit.BindJSON[UpsertFieldMappingRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	cfg, err := h.service.SaveFieldMapping(c.Request.Context(), keyID, tenantID, req)
+	if err != nil {
+		if err == ErrAPIKeyNotFound {
+			httpkit.Error(c, http.StatusNotFound, "API key not found", nil)
+			return
+		}
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	httpkit.OK(c, cfg)
+}
+
+// HandleDeleteFieldMapping removes the field mapping for an API key.
+// DELETE /api/v1/admin/webhook/keys/:keyId/mapping
+func (h *Handler) HandleDeleteFieldMapping(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	keyID, ok := httpkit.ParseUUIDParam(c, "keyId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteFieldMapping(c.Request.Context(), keyID, tenantID); err != nil {
+		if err == ErrFieldMappingNotFound {
+			httpkit.Error(c, http.StatusNotFound, "field mapping not found", nil)
+			return
+		}
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandlePreviewFieldMapping runs a sample payload through a mapping without creating a lead.
+// POST /api/v1/admin/webhook/keys/:keyId/mapping/preview
+func (h *Handler) HandlePreviewFieldMapping(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	keyID, ok := httpkit.ParseUUIDParam(c, "keyId")
+	if !ok {
+		return
+	}
+
+	req, ok := httpkit.BindJSON[PreviewFieldMappingRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	preview, err := h.service.PreviewFieldMapping(c.Request.Context(), keyID, tenantID, req)
+	if err != nil {
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	httpkit.OK(c, preview)
+}
+
+// ---- Spam guard (JWT authenticated) ----
+
+// HandleGetSpamPolicy returns the spam policy of the tenant.
+// GET /api/v1/admin/webhook/spam-policy
+func (h *Handler) HandleGetSpamPolicy(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.GetSpamPolicy(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, policy)
+}
+
+// HandleUpdateSpamPolicy replaces the spam policy of the tenant.
+// PUT /api/v1/admin/webhook/spam-policy
+func (h *Handler) HandleUpdateSpamPolicy(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := httpkit.BindJSON[UpdateSpamPolicyRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.UpdateSpamPolicy(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, policy)
+}
+
+// HandleListSpamReviews lists the submissions held back as junk.
+// GET /api/v1/admin/webhook/spam-reviews
+func (h *Handler) HandleListSpamReviews(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := httpkit.BindQuery[ListSpamReviewsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListSpamReviews(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+// HandleReleaseSpamReview overrides the spam verdict and creates the lead.
+// POST /api/v1/admin/webhook/spam-reviews/:reviewId/release
+func (h *Handler) HandleReleaseSpamReview(c *gin.Context) {
+	h.resolveSpamReview(c, h.service.ReleaseSpamReview)
+}
+
+// HandleDismissSpamReview confirms a held submission as junk.
+// POST /api/v1/admin/webhook/spam-reviews/:reviewId/dismiss
+func (h *Handler) HandleDismissSpamReview(c *gin.Context) {
+	h.resolveSpamReview(c, h.service.DismissSpamReview)
+}
+
+func (h *Handler) resolveSpamReview(c *gin.Context, resolve func(ctx context.Context, id, orgID, userID uuid.UUID) (SpamReview, error)) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	reviewID, ok := httpkit.ParseUUIDParam(c, "reviewId")
+	if !ok {
+		return
+	}
+
+	review, err := resolve(c.Request.Context(), reviewID, tenantID, httpkit.MustGetIdentity(c).UserID())
+	if err != nil {
+		if err == ErrSpamReviewNotFound {
+			httpkit.Error(c, http.StatusNotFound, "pending spam review not found", nil)
+			return
+		}
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	httpkit.OK(c, review)
+}
+
+// ---- Validation policies and quarantine (JWT authenticated) ----
+
+// HandleGetValidationPolicy returns the validation policy configured for an API key.
+// GET /api/v1/admin/webhook/keys/:keyId/validation
+func (h *Handler) HandleGetValidationPolicy(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	keyID, ok := httpkit.ParseUUIDParam(c, "keyId")
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.GetValidationPolicy(c.Request.Context(), keyID, tenantID)
+	if err != nil {
+		if err == ErrValidationPolicyNotFound {
+			httpkit.Error(c, http.StatusNotFound, "validation policy not found", nil)
+			return
+		}
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	httpkit.OK(c, policy)
+}
+
+// HandleUpsertValidationPolicy creates or replaces the validation policy for an API key.
+// PUT /api/v1/admin/webhook/keys/:keyId/validation
+func (h *Handler) HandleUpsertValidationPolicy(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	keyID, ok := httpkit.ParseUUIDParam(c, "keyId")
+	if !ok {
+		return
+	}
+
+	req, ok := httpkit.BindJSON[UpsertValidationPolicyRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.SaveValidationPolicy(c.Request.Context(), keyID, tenantID, req)
+	if err != nil {
+		if err == ErrAPIKeyNotFound {
+			httpkit.Error(c, http.StatusNotFound, "API key not found", nil)
+			return
+		}
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	httpkit.OK(c, policy)
+}
+
+// HandleDeleteValidationPolicy removes the validation policy for an API key.
+// DELETE /api/v1/admin/webhook/keys/:keyId/validation
+func (h *Handler) HandleDeleteValidationPolicy(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	keyID, ok := httpkit.ParseUUIDParam(c, "keyId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteValidationPolicy(c.Request.Context(), keyID, tenantID); err != nil {
+		if err == ErrValidationPolicyNotFound {
+			httpkit.Error(c, http.StatusNotFound, "validation policy not found", nil)
+			return
+		}
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleListQuarantine lists the submissions that failed validation.
+// GET /api/v1/webhook/quarantine
+func (h *Handler) HandleListQuarantine(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := httpkit.BindQuery[ListQuarantineRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListQuarantine(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+
+	httpkit.OK(c, resp)
+}
+
+// HandleResubmitQuarantined applies corrections to a quarantined submission and creates the lead.
+// POST /api/v1/webhook/quarantine/:quarantineId/resubmit
+func (h *Handler) HandleResubmitQuarantined(c *gin.Context) {
+	req, ok := httpkit.BindJSON[ResubmitQuarantineRequest](c, h.val)
+	if !ok {
+		return
+	}
+	h.resolveQuarantined(c, func(ctx context.Context, id, orgID, userID uuid.UUID) (QuarantinedSubmission, error) {
+		return h.service.ResubmitQuarantined(ctx, id, orgID, userID, req)
+	})
+}
+
+// HandleDismissQuarantined drops a quarantined submission.
+// POST /api/v1/webhook/quarantine/:quarantineId/dismiss
+func (h *Handler) HandleDismissQuarantined(c *gin.Context) {
+	h.resolveQuarantined(c, h.service.DismissQuarantined)
+}
+
+func (h *Handler) resolveQuarantined(c *gin.Context, resolve func(ctx context.Context, id, orgID, userID uuid.UUID) (QuarantinedSubmission, error)) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	quarantineID, ok := httpkit.ParseUUIDParam(c, "quarantineId")
+	if !ok {
+		return
+	}
+
+	item, err := resolve(c.Request.Context(), quarantineID, tenantID, httpkit.MustGetIdentity(c).UserID())
+	if err != nil {
+		if err == ErrQuarantineNotFound {
+			httpkit.Error(c, http.StatusNotFound, "pending quarantined submission not found", nil)
+			return
+		}
+		httpkit.HandleError(c, err)
+		return
+	}
+
+	httpkit.OK(c, item)
 }
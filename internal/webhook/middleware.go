@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"strings"
 
+	"portal_final_backend/platform/httpkit"
 	"portal_final_backend/platform/logger"
 
 	"github.com/gin-gonic/gin"
@@ -56,6 +57,9 @@ func WhatsAppAPIKeyAuthMiddleware(repo webhookAuthRepository, webhookSecret stri
 
 			c.Set("webhookOrgID", key.OrganizationID)
 			c.Set("webhookKeyID", key.ID)
+			if !httpkit.BindTenantContext(c, key.OrganizationID) {
+				return
+			}
 			logWhatsAppWebhookAuthSuccess(c, log, "api_key", key.OrganizationID, false)
 			c.Next()
 			return
@@ -68,6 +72,9 @@ func WhatsAppAPIKeyAuthMiddleware(repo webhookAuthRepository, webhookSecret stri
 			}
 
 			c.Set("webhookOrgID", organizationID)
+			if !httpkit.BindTenantContext(c, organizationID) {
+				return
+			}
 			c.Next()
 			return
 		}
@@ -91,6 +98,9 @@ func webhookAPIKeyAuthMiddleware(repo webhookAuthRepository, allowQueryParam boo
 		// Set organization context for downstream handlers
 		c.Set("webhookOrgID", key.OrganizationID)
 		c.Set("webhookKeyID", key.ID)
+		if !httpkit.BindTenantContext(c, key.OrganizationID) {
+			return
+		}
 		c.Next()
 	}
 }
@@ -6,7 +6,9 @@ import (
 	"portal_final_backend/internal/adapters/storage"
 	"portal_final_backend/internal/events"
 	apphttp "portal_final_backend/internal/http"
+	leadsrepo "portal_final_backend/internal/leads/repository"
 	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/secrets"
 	"portal_final_backend/platform/validator"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -14,6 +16,7 @@ import (
 
 // Module is the webhook bounded context module implementing http.Module.
 type Module struct {
+	service               *Service
 	handler               *Handler
 	repo                  *Repository
 	log                   *logger.Logger
@@ -27,6 +30,7 @@ func NewModule(pool *pgxpool.Pool, leadCreator LeadCreator, storageSvc storage.S
 	handler := NewHandler(service, repo, val, nil)
 
 	return &Module{
+		service: service,
 		handler: handler,
 		repo:    repo,
 		log:     log,
@@ -39,6 +43,27 @@ func (m *Module) SetWhatsAppInboxIngester(whatsappInbox WhatsAppInboxIngester) {
 	}
 }
 
+// SetCustomFieldWriter enables storing mapped custom field values on webhook leads.
+func (m *Module) SetCustomFieldWriter(writer CustomFieldWriter) {
+	m.service.SetCustomFieldWriter(writer)
+}
+
+// SetLeadContactCipher lets duplicate detection match leads whose contact
+// details are encrypted.
+func (m *Module) SetLeadContactCipher(cipher *leadsrepo.ContactCipher) {
+	m.repo.SetLeadContactCipher(cipher)
+}
+
+// SetConnectorKeyring enables storing marketplace API tokens on polling connectors.
+func (m *Module) SetConnectorKeyring(keyring *secrets.Keyring) {
+	m.service.SetConnectorKeyring(keyring)
+}
+
+// Service exposes the webhook service for background jobs such as connector polling.
+func (m *Module) Service() *Service {
+	return m.service
+}
+
 func (m *Module) SetWhatsAppWebhookSecret(secret string) {
 	m.whatsAppWebhookSecret = secret
 }
@@ -82,6 +107,13 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	adminGroup.GET("", m.handler.HandleListAPIKeys)
 	adminGroup.POST("/:keyId/rotate", m.handler.HandleRotateAPIKey)
 	adminGroup.DELETE("/:keyId", m.handler.HandleRevokeAPIKey)
+	adminGroup.GET("/:keyId/mapping", m.handler.HandleGetFieldMapping)
+	adminGroup.PUT("/:keyId/mapping", m.handler.HandleUpsertFieldMapping)
+	adminGroup.DELETE("/:keyId/mapping", m.handler.HandleDeleteFieldMapping)
+	adminGroup.POST("/:keyId/mapping/preview", m.handler.HandlePreviewFieldMapping)
+	adminGroup.GET("/:keyId/validation", m.handler.HandleGetValidationPolicy)
+	adminGroup.PUT("/:keyId/validation", m.handler.HandleUpsertValidationPolicy)
+	adminGroup.DELETE("/:keyId/validation", m.handler.HandleDeleteValidationPolicy)
 
 	// Admin GTM config management (JWT auth + admin role)
 	gtmAdmin := ctx.Admin.Group("/webhook/gtm-config")
@@ -97,6 +129,31 @@ func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
 	googleAdmin.DELETE("/:configId/campaigns/:campaignId", m.handler.HandleDeleteGoogleCampaignMapping)
 	googleAdmin.DELETE("/:configId", m.handler.HandleDeleteGoogleWebhookConfig)
 
+	// Admin spam guard: policy and the review queue of held back submissions
+	spamAdmin := ctx.Admin.Group("/webhook")
+	spamAdmin.GET("/spam-policy", m.handler.HandleGetSpamPolicy)
+	spamAdmin.PUT("/spam-policy", m.handler.HandleUpdateSpamPolicy)
+	spamAdmin.GET("/spam-reviews", m.handler.HandleListSpamReviews)
+	spamAdmin.POST("/spam-reviews/:reviewId/release", m.handler.HandleReleaseSpamReview)
+	spamAdmin.POST("/spam-reviews/:reviewId/dismiss", m.handler.HandleDismissSpamReview)
+
+	// Quarantine of submissions that failed validation: agents fix and resubmit them
+	quarantine := ctx.Protected.Group("/webhook/quarantine")
+	quarantine.GET("", m.handler.HandleListQuarantine)
+	quarantine.POST("/:quarantineId/resubmit", m.handler.HandleResubmitQuarantined)
+	quarantine.POST("/:quarantineId/dismiss", m.handler.HandleDismissQuarantined)
+
+	// Lead source connectors: marketplaces push to a per-connector URL (secret auth)
+	ctx.V1.POST("/webhook/connectors/:connectorId/leads", m.handler.HandleConnectorWebhook)
+	connectorAdmin := ctx.Admin.Group("/webhook/connectors")
+	connectorAdmin.GET("/providers", m.handler.HandleListLeadSourceProviders)
+	connectorAdmin.GET("", m.handler.HandleListConnectors)
+	connectorAdmin.POST("", m.handler.HandleCreateConnector)
+	connectorAdmin.PUT("/:connectorId", m.handler.HandleUpdateConnector)
+	connectorAdmin.DELETE("/:connectorId", m.handler.HandleDeleteConnector)
+	connectorAdmin.POST("/:connectorId/poll", m.handler.HandlePollConnector)
+	connectorAdmin.GET("/:connectorId/leads", m.handler.HandleListConnectorLeads)
+
 	// SDK serving (public, no auth)
 	ctx.V1.GET("/webhook/sdk.js", m.handler.HandleServeSDK)
 }
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	leadsrepo "portal_final_backend/internal/leads/repository"
 	webhookdb "portal_final_backend/internal/webhook/db"
 	whatsappagentdb "portal_final_backend/internal/whatsappagent/db"
 
@@ -24,6 +25,10 @@ var ErrAPIKeyNotFound = errors.New("webhook API key not found")
 var ErrGoogleConfigNotFound = errors.New("google webhook config not found")
 var ErrDuplicateGoogleLeadID = errors.New("google lead ID already processed")
 var ErrWhatsAppDeviceNotFound = errors.New("whatsapp device not found")
+var ErrFieldMappingNotFound = errors.New("webhook field mapping not found")
+var ErrSpamReviewNotFound = errors.New("pending spam review not found")
+var ErrValidationPolicyNotFound = errors.New("webhook validation policy not found")
+var ErrQuarantineNotFound = errors.New("pending quarantined submission not found")
 
 // APIKey represents a webhook API key stored in the database.
 type APIKey struct {
@@ -40,8 +45,9 @@ type APIKey struct {
 
 // Repository provides data access for webhook API keys.
 type Repository struct {
-	pool    *pgxpool.Pool
-	queries *webhookdb.Queries
+	pool        *pgxpool.Pool
+	queries     *webhookdb.Queries
+	leadContact *leadsrepo.ContactCipher
 }
 
 type createTimelineEventParams struct {
@@ -378,6 +384,22 @@ func (r *Repository) Rotate(ctx context.Context, keyID uuid.UUID, orgID uuid.UUI
 		return APIKey{}, ErrAPIKeyNotFound
 	}
 
+	const moveFieldMapping = `
+		UPDATE RAC_webhook_field_mappings
+		SET api_key_id = $1, updated_at = now()
+		WHERE api_key_id = $2 AND organization_id = $3`
+	if _, err := tx.Exec(ctx, moveFieldMapping, created.ID, keyID, orgID); err != nil {
+		return APIKey{}, err
+	}
+
+	const moveValidationPolicy = `
+		UPDATE RAC_webhook_validation_policies
+		SET api_key_id = $1, updated_at = now()
+		WHERE api_key_id = $2 AND organization_id = $3`
+	if _, err := tx.Exec(ctx, moveValidationPolicy, created.ID, keyID, orgID); err != nil {
+		return APIKey{}, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return APIKey{}, err
 	}
@@ -396,7 +418,14 @@ func (r *Repository) UpdateWebhookLeadData(ctx context.Context, leadID uuid.UUID
 	})
 }
 
+// SetLeadContactCipher lets duplicate detection match leads whose contact
+// details are encrypted.
+func (r *Repository) SetLeadContactCipher(cipher *leadsrepo.ContactCipher) {
+	r.leadContact = cipher
+}
+
 // FindRecentDuplicateLead checks if a lead with the same email and phone was created recently.
+// Encrypted contact details are compared through their blind indexes.
 func (r *Repository) FindRecentDuplicateLead(ctx context.Context, orgID uuid.UUID, email, phone string, window time.Duration) (*uuid.UUID, error) {
 	if email == "" && phone == "" {
 		return nil, nil
@@ -407,6 +436,8 @@ func (r *Repository) FindRecentDuplicateLead(ctx context.Context, orgID uuid.UUI
 		Secs:           window.Seconds(),
 		Column3:        email,
 		Column4:        phone,
+		Column5:        r.leadContact.EmailBlindIndex(email),
+		Column6:        r.leadContact.PhoneBlindIndex(phone),
 	})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -563,3 +594,542 @@ func (r *Repository) SetGTMContainerID(ctx context.Context, orgID uuid.UUID, con
 func (r *Repository) ClearGTMContainerID(ctx context.Context, orgID uuid.UUID) error {
 	return r.queries.ClearOrganizationGTMContainerID(ctx, toPgUUID(orgID))
 }
+
+const fieldMappingColumns = `id, organization_id, api_key_id, field_mappings, service_type_rules, default_values,
+	fallback_to_heuristics, is_active, created_at, updated_at`
+
+func scanFieldMapping(row pgx.Row) (FieldMappingConfig, error) {
+	var cfg FieldMappingConfig
+	var fieldMappings, rules, defaults []byte
+	if err := row.Scan(
+		&cfg.ID,
+		&cfg.OrganizationID,
+		&cfg.APIKeyID,
+		&fieldMappings,
+		&rules,
+		&defaults,
+		&cfg.FallbackToHeuristics,
+		&cfg.IsActive,
+		&cfg.CreatedAt,
+		&cfg.UpdatedAt,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return FieldMappingConfig{}, ErrFieldMappingNotFound
+	} else if err != nil {
+		return FieldMappingConfig{}, err
+	}
+
+	cfg.FieldMappings = map[string][]string{}
+	cfg.ServiceTypeRules = []ServiceTypeRule{}
+	cfg.DefaultValues = map[string]string{}
+	if err := json.Unmarshal(fieldMappings, &cfg.FieldMappings); err != nil {
+		return FieldMappingConfig{}, err
+	}
+	if err := json.Unmarshal(rules, &cfg.ServiceTypeRules); err != nil {
+		return FieldMappingConfig{}, err
+	}
+	if err := json.Unmarshal(defaults, &cfg.DefaultValues); err != nil {
+		return FieldMappingConfig{}, err
+	}
+	return cfg, nil
+}
+
+// GetFieldMapping returns the field mapping attached to an API key.
+func (r *Repository) GetFieldMapping(ctx context.Context, apiKeyID uuid.UUID, orgID uuid.UUID) (FieldMappingConfig, error) {
+	query := `SELECT ` + fieldMappingColumns + `
+		FROM RAC_webhook_field_mappings
+		WHERE api_key_id = $1 AND organization_id = $2`
+	return scanFieldMapping(r.pool.QueryRow(ctx, query, apiKeyID, orgID))
+}
+
+// UpsertFieldMapping creates or replaces the field mapping of an active API key.
+func (r *Repository) UpsertFieldMapping(ctx context.Context, cfg FieldMappingConfig) (FieldMappingConfig, error) {
+	fieldMappings, err := json.Marshal(cfg.FieldMappings)
+	if err != nil {
+		return FieldMappingConfig{}, err
+	}
+	rules, err := json.Marshal(cfg.ServiceTypeRules)
+	if err != nil {
+		return FieldMappingConfig{}, err
+	}
+	defaults, err := json.Marshal(cfg.DefaultValues)
+	if err != nil {
+		return FieldMappingConfig{}, err
+	}
+
+	query := `
+		INSERT INTO RAC_webhook_field_mappings (
+			organization_id, api_key_id, field_mappings, service_type_rules, default_values,
+			fallback_to_heuristics, is_active
+		)
+		SELECT k.organization_id, k.id, $3, $4, $5, $6, $7
+		FROM RAC_webhook_api_keys k
+		WHERE k.id = $1 AND k.organization_id = $2 AND k.is_active = true
+		ON CONFLICT (api_key_id) DO UPDATE SET
+			field_mappings = EXCLUDED.field_mappings,
+			service_type_rules = EXCLUDED.service_type_rules,
+			default_values = EXCLUDED.default_values,
+			fallback_to_heuristics = EXCLUDED.fallback_to_heuristics,
+			is_active = EXCLUDED.is_active,
+			updated_at = now()
+		RETURNING ` + fieldMappingColumns
+	saved, err := scanFieldMapping(r.pool.QueryRow(ctx, query,
+		cfg.APIKeyID, cfg.OrganizationID, fieldMappings, rules, defaults, cfg.FallbackToHeuristics, cfg.IsActive,
+	))
+	if errors.Is(err, ErrFieldMappingNotFound) {
+		return FieldMappingConfig{}, ErrAPIKeyNotFound
+	}
+	return saved, err
+}
+
+// DeleteFieldMapping removes the field mapping of an API key.
+func (r *Repository) DeleteFieldMapping(ctx context.Context, apiKeyID uuid.UUID, orgID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM RAC_webhook_field_mappings WHERE api_key_id = $1 AND organization_id = $2`, apiKeyID, orgID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrFieldMappingNotFound
+	}
+	return nil
+}
+
+const spamPolicyColumns = `organization_id, mode, junk_threshold, honeypot_field, blocked_email_domains, updated_at`
+
+func scanSpamPolicy(row pgx.Row) (SpamPolicy, error) {
+	var policy SpamPolicy
+	var updatedAt time.Time
+	if err := row.Scan(
+		&policy.OrganizationID,
+		&policy.Mode,
+		&policy.JunkThreshold,
+		&policy.HoneypotField,
+		&policy.BlockedEmailDomains,
+		&updatedAt,
+	); err != nil {
+		return SpamPolicy{}, err
+	}
+	policy.UpdatedAt = &updatedAt
+	if policy.BlockedEmailDomains == nil {
+		policy.BlockedEmailDomains = []string{}
+	}
+	return policy, nil
+}
+
+// GetSpamPolicy returns the spam policy of an organization, or the default
+// policy when it never configured one.
+func (r *Repository) GetSpamPolicy(ctx context.Context, orgID uuid.UUID) (SpamPolicy, error) {
+	policy, err := scanSpamPolicy(r.pool.QueryRow(ctx, `SELECT `+spamPolicyColumns+`
+		FROM RAC_webhook_spam_policies
+		WHERE organization_id = $1`, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DefaultSpamPolicy(orgID), nil
+	}
+	return policy, err
+}
+
+// UpsertSpamPolicy creates or replaces the spam policy of an organization.
+func (r *Repository) UpsertSpamPolicy(ctx context.Context, policy SpamPolicy) (SpamPolicy, error) {
+	return scanSpamPolicy(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_webhook_spam_policies (organization_id, mode, junk_threshold, honeypot_field, blocked_email_domains)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (organization_id) DO UPDATE SET
+			mode = EXCLUDED.mode,
+			junk_threshold = EXCLUDED.junk_threshold,
+			honeypot_field = EXCLUDED.honeypot_field,
+			blocked_email_domains = EXCLUDED.blocked_email_domains,
+			updated_at = now()
+		RETURNING `+spamPolicyColumns,
+		policy.OrganizationID, policy.Mode, policy.JunkThreshold, policy.HoneypotField, policy.BlockedEmailDomains,
+	))
+}
+
+const spamReviewColumns = `id, organization_id, api_key_id, source_domain, fields, raw_json, spam_score, spam_reasons,
+	status, lead_id, reviewed_by, reviewed_at, created_at`
+
+func scanSpamReview(row pgx.Row) (SpamReview, error) {
+	var review SpamReview
+	var fields, rawJSON []byte
+	if err := row.Scan(
+		&review.ID,
+		&review.OrganizationID,
+		&review.APIKeyID,
+		&review.SourceDomain,
+		&fields,
+		&rawJSON,
+		&review.SpamScore,
+		&review.SpamReasons,
+		&review.Status,
+		&review.LeadID,
+		&review.ReviewedBy,
+		&review.ReviewedAt,
+		&review.CreatedAt,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return SpamReview{}, ErrSpamReviewNotFound
+	} else if err != nil {
+		return SpamReview{}, err
+	}
+
+	review.Fields = map[string]string{}
+	if err := json.Unmarshal(fields, &review.Fields); err != nil {
+		return SpamReview{}, err
+	}
+	if len(rawJSON) > 0 {
+		if err := json.Unmarshal(rawJSON, &review.RawJSON); err != nil {
+			return SpamReview{}, err
+		}
+	}
+	if review.SpamReasons == nil {
+		review.SpamReasons = []string{}
+	}
+	return review, nil
+}
+
+// CreateSpamReview holds back a submission in the spam review queue.
+func (r *Repository) CreateSpamReview(ctx context.Context, review SpamReview) (SpamReview, error) {
+	fields, err := json.Marshal(review.Fields)
+	if err != nil {
+		return SpamReview{}, err
+	}
+	var rawJSON []byte
+	if review.RawJSON != nil {
+		if rawJSON, err = json.Marshal(review.RawJSON); err != nil {
+			return SpamReview{}, err
+		}
+	}
+
+	return scanSpamReview(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_webhook_spam_reviews (
+			organization_id, api_key_id, source_domain, fields, raw_json, spam_score, spam_reasons
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+spamReviewColumns,
+		review.OrganizationID, review.APIKeyID, review.SourceDomain, fields, rawJSON, review.SpamScore, review.SpamReasons,
+	))
+}
+
+// ListSpamReviews returns the reviews of an organization with the given
+// status, newest first, and the total number of them.
+func (r *Repository) ListSpamReviews(ctx context.Context, orgID uuid.UUID, status string, limit int) ([]SpamReview, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM RAC_webhook_spam_reviews
+		WHERE organization_id = $1 AND status = $2`, orgID, status,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT `+spamReviewColumns+`
+		FROM RAC_webhook_spam_reviews
+		WHERE organization_id = $1 AND status = $2
+		ORDER BY created_at DESC
+		LIMIT $3`, orgID, status, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]SpamReview, 0)
+	for rows.Next() {
+		review, err := scanSpamReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, review)
+	}
+	return items, total, rows.Err()
+}
+
+// ResolveSpamReview moves a pending review to the given status. It returns
+// ErrSpamReviewNotFound when the review does not exist or was already
+// resolved, so only one reviewer can release a submission.
+func (r *Repository) ResolveSpamReview(ctx context.Context, id, orgID uuid.UUID, status string, reviewedBy uuid.UUID) (SpamReview, error) {
+	return scanSpamReview(r.pool.QueryRow(ctx, `
+		UPDATE RAC_webhook_spam_reviews
+		SET status = $3, reviewed_by = $4, reviewed_at = now()
+		WHERE id = $1 AND organization_id = $2 AND status = 'pending'
+		RETURNING `+spamReviewColumns,
+		id, orgID, status, reviewedBy,
+	))
+}
+
+// SetSpamReviewLead records the lead a released review became.
+func (r *Repository) SetSpamReviewLead(ctx context.Context, id, orgID, leadID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE RAC_webhook_spam_reviews SET lead_id = $3
+		WHERE id = $1 AND organization_id = $2`, id, orgID, leadID)
+	return err
+}
+
+// ReopenSpamReview puts a review back in the queue, after releasing it failed.
+func (r *Repository) ReopenSpamReview(ctx context.Context, id, orgID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE RAC_webhook_spam_reviews
+		SET status = 'pending', reviewed_by = NULL, reviewed_at = NULL
+		WHERE id = $1 AND organization_id = $2`, id, orgID)
+	return err
+}
+
+const validationPolicyColumns = `id, organization_id, api_key_id, required_fields, pattern_rules, postcode_format,
+	phone_countries, action, is_active, created_at, updated_at`
+
+func scanValidationPolicy(row pgx.Row) (ValidationPolicy, error) {
+	var policy ValidationPolicy
+	var patternRules []byte
+	if err := row.Scan(
+		&policy.ID,
+		&policy.OrganizationID,
+		&policy.APIKeyID,
+		&policy.RequiredFields,
+		&patternRules,
+		&policy.PostcodeFormat,
+		&policy.PhoneCountries,
+		&policy.Action,
+		&policy.IsActive,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return ValidationPolicy{}, ErrValidationPolicyNotFound
+	} else if err != nil {
+		return ValidationPolicy{}, err
+	}
+
+	policy.PatternRules = []ValidationPatternRule{}
+	if err := json.Unmarshal(patternRules, &policy.PatternRules); err != nil {
+		return ValidationPolicy{}, err
+	}
+	if policy.RequiredFields == nil {
+		policy.RequiredFields = []string{}
+	}
+	if policy.PhoneCountries == nil {
+		policy.PhoneCountries = []string{}
+	}
+	return policy, nil
+}
+
+// GetValidationPolicy returns the validation policy attached to an API key.
+func (r *Repository) GetValidationPolicy(ctx context.Context, apiKeyID uuid.UUID, orgID uuid.UUID) (ValidationPolicy, error) {
+	return scanValidationPolicy(r.pool.QueryRow(ctx, `SELECT `+validationPolicyColumns+`
+		FROM RAC_webhook_validation_policies
+		WHERE api_key_id = $1 AND organization_id = $2`, apiKeyID, orgID))
+}
+
+// UpsertValidationPolicy creates or replaces the validation policy of an active API key.
+func (r *Repository) UpsertValidationPolicy(ctx context.Context, policy ValidationPolicy) (ValidationPolicy, error) {
+	patternRules, err := json.Marshal(policy.PatternRules)
+	if err != nil {
+		return ValidationPolicy{}, err
+	}
+
+	saved, err := scanValidationPolicy(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_webhook_validation_policies (
+			organization_id, api_key_id, required_fields, pattern_rules, postcode_format,
+			phone_countries, action, is_active
+		)
+		SELECT k.organization_id, k.id, $3, $4, $5, $6, $7, $8
+		FROM RAC_webhook_api_keys k
+		WHERE k.id = $1 AND k.organization_id = $2 AND k.is_active = true
+		ON CONFLICT (api_key_id) DO UPDATE SET
+			required_fields = EXCLUDED.required_fields,
+			pattern_rules = EXCLUDED.pattern_rules,
+			postcode_format = EXCLUDED.postcode_format,
+			phone_countries = EXCLUDED.phone_countries,
+			action = EXCLUDED.action,
+			is_active = EXCLUDED.is_active,
+			updated_at = now()
+		RETURNING `+validationPolicyColumns,
+		policy.APIKeyID, policy.OrganizationID, policy.RequiredFields, patternRules, policy.PostcodeFormat,
+		policy.PhoneCountries, policy.Action, policy.IsActive,
+	))
+	if errors.Is(err, ErrValidationPolicyNotFound) {
+		return ValidationPolicy{}, ErrAPIKeyNotFound
+	}
+	return saved, err
+}
+
+// DeleteValidationPolicy removes the validation policy of an API key.
+func (r *Repository) DeleteValidationPolicy(ctx context.Context, apiKeyID uuid.UUID, orgID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM RAC_webhook_validation_policies WHERE api_key_id = $1 AND organization_id = $2`, apiKeyID, orgID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrValidationPolicyNotFound
+	}
+	return nil
+}
+
+const quarantineColumns = `id, organization_id, api_key_id, source_domain, fields, raw_json, extracted, violations,
+	corrections, status, lead_id, reviewed_by, reviewed_at, created_at`
+
+func scanQuarantinedSubmission(row pgx.Row) (QuarantinedSubmission, error) {
+	var item QuarantinedSubmission
+	var fields, rawJSON, extracted, violations, corrections []byte
+	if err := row.Scan(
+		&item.ID,
+		&item.OrganizationID,
+		&item.APIKeyID,
+		&item.SourceDomain,
+		&fields,
+		&rawJSON,
+		&extracted,
+		&violations,
+		&corrections,
+		&item.Status,
+		&item.LeadID,
+		&item.ReviewedBy,
+		&item.ReviewedAt,
+		&item.CreatedAt,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return QuarantinedSubmission{}, ErrQuarantineNotFound
+	} else if err != nil {
+		return QuarantinedSubmission{}, err
+	}
+
+	item.Fields = map[string]string{}
+	item.Extracted = map[string]string{}
+	item.Violations = []ValidationViolation{}
+	item.Corrections = map[string]string{}
+	for _, target := range []struct {
+		data []byte
+		dest any
+	}{
+		{fields, &item.Fields},
+		{extracted, &item.Extracted},
+		{violations, &item.Violations},
+		{corrections, &item.Corrections},
+	} {
+		if err := json.Unmarshal(target.data, target.dest); err != nil {
+			return QuarantinedSubmission{}, err
+		}
+	}
+	if len(rawJSON) > 0 {
+		if err := json.Unmarshal(rawJSON, &item.RawJSON); err != nil {
+			return QuarantinedSubmission{}, err
+		}
+	}
+	return item, nil
+}
+
+// CreateQuarantinedSubmission parks a submission in the quarantine queue.
+func (r *Repository) CreateQuarantinedSubmission(ctx context.Context, item QuarantinedSubmission) (QuarantinedSubmission, error) {
+	fields, err := json.Marshal(item.Fields)
+	if err != nil {
+		return QuarantinedSubmission{}, err
+	}
+	extracted, err := json.Marshal(item.Extracted)
+	if err != nil {
+		return QuarantinedSubmission{}, err
+	}
+	violations, err := json.Marshal(item.Violations)
+	if err != nil {
+		return QuarantinedSubmission{}, err
+	}
+	var rawJSON []byte
+	if item.RawJSON != nil {
+		if rawJSON, err = json.Marshal(item.RawJSON); err != nil {
+			return QuarantinedSubmission{}, err
+		}
+	}
+
+	return scanQuarantinedSubmission(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_webhook_quarantine (
+			organization_id, api_key_id, source_domain, fields, raw_json, extracted, violations
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+quarantineColumns,
+		item.OrganizationID, item.APIKeyID, item.SourceDomain, fields, rawJSON, extracted, violations,
+	))
+}
+
+// GetQuarantinedSubmission returns a quarantined submission in any status.
+func (r *Repository) GetQuarantinedSubmission(ctx context.Context, id, orgID uuid.UUID) (QuarantinedSubmission, error) {
+	return scanQuarantinedSubmission(r.pool.QueryRow(ctx, `SELECT `+quarantineColumns+`
+		FROM RAC_webhook_quarantine
+		WHERE id = $1 AND organization_id = $2`, id, orgID))
+}
+
+// ListQuarantinedSubmissions returns the quarantined submissions of an
+// organization with the given status, newest first, and the total number of them.
+func (r *Repository) ListQuarantinedSubmissions(ctx context.Context, orgID uuid.UUID, status string, limit int) ([]QuarantinedSubmission, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM RAC_webhook_quarantine
+		WHERE organization_id = $1 AND status = $2`, orgID, status,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT `+quarantineColumns+`
+		FROM RAC_webhook_quarantine
+		WHERE organization_id = $1 AND status = $2
+		ORDER BY created_at DESC
+		LIMIT $3`, orgID, status, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]QuarantinedSubmission, 0)
+	for rows.Next() {
+		item, err := scanQuarantinedSubmission(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// UpdateQuarantineCorrections stores the corrections of a failed resubmit and
+// the violations that remain.
+func (r *Repository) UpdateQuarantineCorrections(ctx context.Context, id, orgID uuid.UUID, corrections map[string]string, violations []ValidationViolation) error {
+	correctionsJSON, err := json.Marshal(corrections)
+	if err != nil {
+		return err
+	}
+	violationsJSON, err := json.Marshal(violations)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+		UPDATE RAC_webhook_quarantine SET corrections = $3, violations = $4
+		WHERE id = $1 AND organization_id = $2 AND status = 'pending'`, id, orgID, correctionsJSON, violationsJSON)
+	return err
+}
+
+// ResolveQuarantinedSubmission moves a pending submission to the given status,
+// storing the corrections when given. It returns ErrQuarantineNotFound when
+// the submission does not exist or was already resolved, so only one agent
+// can resubmit it.
+func (r *Repository) ResolveQuarantinedSubmission(ctx context.Context, id, orgID uuid.UUID, status string, reviewedBy uuid.UUID, corrections map[string]string) (QuarantinedSubmission, error) {
+	var correctionsJSON []byte
+	if corrections != nil {
+		var err error
+		if correctionsJSON, err = json.Marshal(corrections); err != nil {
+			return QuarantinedSubmission{}, err
+		}
+	}
+	return scanQuarantinedSubmission(r.pool.QueryRow(ctx, `
+		UPDATE RAC_webhook_quarantine
+		SET status = $3, reviewed_by = $4, reviewed_at = now(), corrections = COALESCE($5, corrections)
+		WHERE id = $1 AND organization_id = $2 AND status = 'pending'
+		RETURNING `+quarantineColumns,
+		id, orgID, status, reviewedBy, correctionsJSON,
+	))
+}
+
+// SetQuarantineLead records the lead a resubmitted submission became.
+func (r *Repository) SetQuarantineLead(ctx context.Context, id, orgID, leadID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE RAC_webhook_quarantine SET lead_id = $3
+		WHERE id = $1 AND organization_id = $2`, id, orgID, leadID)
+	return err
+}
+
+// ReopenQuarantinedSubmission puts a submission back in the queue, after
+// creating its lead failed.
+func (r *Repository) ReopenQuarantinedSubmission(ctx context.Context, id, orgID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE RAC_webhook_quarantine
+		SET status = 'pending', reviewed_by = NULL, reviewed_at = NULL
+		WHERE id = $1 AND organization_id = $2`, id, orgID)
+	return err
+}
@@ -13,6 +13,9 @@
  *   data-success-url  (optional) Redirect URL after successful submission
  *   data-tracking-ttl (optional) Tracking TTL in days (default: 90)
  * 
+ * Captured forms get a hidden "_rac_hp" honeypot field. People never see or
+ * fill it; submissions where it has a value are treated as spam.
+ * 
  * Manual capture (for JS-rendered forms):
  *   window.RACFormCapture.submit({ name: "John", email: "john@example.com" })
  */
@@ -398,6 +401,23 @@
     });
   }
 
+  /**
+   * Add a hidden honeypot field that only bots fill in
+   */
+  function addHoneypot(form) {
+    if (form.querySelector('[name="_rac_hp"]')) {
+      return;
+    }
+    var input = document.createElement('input');
+    input.type = 'text';
+    input.name = '_rac_hp';
+    input.tabIndex = -1;
+    input.autocomplete = 'off';
+    input.setAttribute('aria-hidden', 'true');
+    input.style.cssText = 'position:absolute;left:-9999px;width:1px;height:1px;opacity:0;';
+    form.appendChild(input);
+  }
+
   /**
    * Auto-capture form submissions
    */
@@ -412,6 +432,7 @@
       }
 
       form.setAttribute('data-rac-attached', 'true');
+      addHoneypot(form);
 
       form.addEventListener('submit', function (e) {
         e.preventDefault();
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"portal_final_backend/internal/events"
 	"portal_final_backend/internal/leads/transport"
 	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/secrets"
 
 	"github.com/google/uuid"
 )
@@ -23,12 +25,18 @@ type LeadCreator interface {
 	Create(ctx context.Context, req transport.CreateLeadRequest, tenantID uuid.UUID) (transport.LeadResponse, error)
 }
 
+// CustomFieldWriter stores mapped custom field values on a lead. Satisfied by the custom fields service.
+type CustomFieldWriter interface {
+	ApplyLeadInput(ctx context.Context, organizationID, leadID uuid.UUID, input map[string]string) error
+}
+
 // FormSubmission represents an inbound form submission via the webhook.
 type FormSubmission struct {
 	Fields       map[string]string // all form fields as key-value
 	Files        []FormFile        // uploaded files
 	SourceDomain string            // origin domain of the form
 	APIKeyID     uuid.UUID         // the API key that authenticated this request
+	RawJSON      map[string]any    // decoded JSON body, used for nested field mapping paths
 }
 
 // FormFile represents an uploaded file within a form submission.
@@ -55,7 +63,11 @@ type Service struct {
 	storageSvc    storage.StorageService
 	storageBucket string
 	eventBus      events.Bus
+	customFields  CustomFieldWriter
 	log           *logger.Logger
+
+	connectorKeyring *secrets.Keyring
+	httpClient       *http.Client
 }
 
 // NewService creates a new webhook service.
@@ -67,16 +79,48 @@ func NewService(repo *Repository, leadCreator LeadCreator, storageSvc storage.St
 		storageBucket: storageBucket,
 		eventBus:      eventBus,
 		log:           log,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
 	}
 }
 
-// ProcessFormSubmission handles an inbound form submission: extract fields, create lead, upload files, store raw data.
+// SetCustomFieldWriter enables storing mapped custom field values on created leads.
+func (s *Service) SetCustomFieldWriter(writer CustomFieldWriter) {
+	s.customFields = writer
+}
+
+// ProcessFormSubmission handles an inbound form submission: screen it for spam, extract and validate fields, create lead, upload files, store raw data.
 func (s *Service) ProcessFormSubmission(ctx context.Context, sub FormSubmission, orgID uuid.UUID) (FormSubmissionResponse, error) {
-	extracted := ExtractFields(sub.Fields)
+	policy := s.loadSpamPolicy(ctx, orgID)
+	honeypotFilled := policy.HoneypotFilled(sub.Fields)
+	sub = policy.stripHoneypot(sub)
+
+	extracted := s.extractSubmissionFields(ctx, sub, orgID)
 	isIncomplete := extracted.IsIncomplete()
-	requestedServiceType := strings.TrimSpace(extracted.ServiceType)
 
-	// 1. Check for recent duplicate
+	// 1. Screen for spam before anything reaches the pipeline
+	var flagged *SpamAssessment
+	if policy.Mode != SpamModeOff {
+		assessment := policy.Assess(extracted, honeypotFilled)
+		if policy.IsJunk(assessment) {
+			if policy.Mode == SpamModeAutoJunk || assessment.Honeypot {
+				return s.holdForSpamReview(ctx, sub, extracted, orgID, assessment)
+			}
+			flagged = &assessment
+		}
+	}
+
+	// 2. Apply the validation policy of the source
+	if validation := s.loadValidationPolicy(ctx, sub.APIKeyID, orgID); validation != nil {
+		if violations := ValidateSubmission(*validation, extracted); len(violations) > 0 {
+			if validation.Action == ValidationActionQuarantine {
+				return s.quarantineSubmission(ctx, sub, extracted, orgID, violations)
+			}
+			s.log.Info("webhook: submission rejected by validation policy", "violations", len(violations), "domain", sub.SourceDomain)
+			return FormSubmissionResponse{}, rejectSubmission(violations)
+		}
+	}
+
+	// 3. Check for recent duplicate
 	dupID, err := s.repo.FindRecentDuplicateLead(ctx, orgID, extracted.Email, extracted.Phone, 60*time.Second)
 	if err != nil {
 		s.log.Error("webhook: failed to check for duplicate lead", "error", err, "domain", sub.SourceDomain)
@@ -93,23 +137,54 @@ func (s *Service) ProcessFormSubmission(ctx context.Context, sub FormSubmission,
 		}, nil
 	}
 
+	leadResp, err := s.createSubmissionLead(ctx, sub, extracted, orgID)
+	if err != nil {
+		return FormSubmissionResponse{}, err
+	}
+
+	if flagged != nil {
+		s.recordSpamFlagEvent(ctx, leadResp, orgID, *flagged, sub.SourceDomain)
+	}
+
+	extractedMap := buildExtractedMap(extracted)
+	msg := buildWebhookMessage(isIncomplete)
+
+	return FormSubmissionResponse{
+		LeadID:       leadResp.ID,
+		IsIncomplete: isIncomplete,
+		Extracted:    extractedMap,
+		Message:      msg,
+	}, nil
+}
+
+// createSubmissionLead creates the lead of a form submission, stores the raw form data on it and uploads its files.
+func (s *Service) createSubmissionLead(ctx context.Context, sub FormSubmission, extracted ExtractedFields, orgID uuid.UUID) (transport.LeadResponse, error) {
+	isIncomplete := extracted.IsIncomplete()
+	requestedServiceType := strings.TrimSpace(extracted.ServiceType)
+
 	createReq := buildCreateLeadRequest(extracted, sub.SourceDomain)
 	applyLeadPlaceholders(&createReq)
 
 	leadResp, err := s.leadCreator.Create(ctx, createReq, orgID)
 	if err != nil {
 		s.log.Error("webhook: failed to create lead from form submission", "error", err, "domain", sub.SourceDomain)
-		return FormSubmissionResponse{}, err
+		return transport.LeadResponse{}, err
 	}
 
-	// 4. Store the raw form data + webhook metadata on the lead
+	// Store the raw form data + webhook metadata on the lead
 	rawData, _ := json.Marshal(sub.Fields)
 	if err := s.repo.UpdateWebhookLeadData(ctx, leadResp.ID, orgID, rawData, sub.SourceDomain, isIncomplete); err != nil {
 		s.log.Error("webhook: failed to store raw form data", "error", err, "leadId", leadResp.ID)
 		// Non-fatal: don't fail the request
 	}
 
-	// 5. Upload files as lead service attachments
+	if s.customFields != nil && len(extracted.CustomFields) > 0 {
+		if err := s.customFields.ApplyLeadInput(ctx, orgID, leadResp.ID, extracted.CustomFields); err != nil {
+			s.log.Error("webhook: failed to store custom field values", "error", err, "leadId", leadResp.ID)
+		}
+	}
+
+	// Upload files as lead service attachments
 	if len(sub.Files) > 0 && len(leadResp.Services) > 0 {
 		serviceID := leadResp.Services[0].ID
 		s.uploadFiles(ctx, leadResp.ID, serviceID, orgID, sub.Files)
@@ -117,7 +192,6 @@ func (s *Service) ProcessFormSubmission(ctx context.Context, sub FormSubmission,
 
 	s.recordServiceTypeFallbackEvent(ctx, leadResp, orgID, requestedServiceType, string(createReq.ServiceType), sub.SourceDomain)
 
-	// 6. Publish event
 	s.eventBus.Publish(ctx, events.WebhookLeadCreated{
 		BaseEvent:    events.NewBaseEvent(),
 		LeadID:       leadResp.ID,
@@ -126,15 +200,64 @@ func (s *Service) ProcessFormSubmission(ctx context.Context, sub FormSubmission,
 		IsIncomplete: isIncomplete,
 	})
 
-	extractedMap := buildExtractedMap(extracted)
-	msg := buildWebhookMessage(isIncomplete)
+	return leadResp, nil
+}
 
-	return FormSubmissionResponse{
-		LeadID:       leadResp.ID,
-		IsIncomplete: isIncomplete,
-		Extracted:    extractedMap,
-		Message:      msg,
-	}, nil
+// extractSubmissionFields applies the API key's field mapping when one is configured, otherwise the label heuristics.
+func (s *Service) extractSubmissionFields(ctx context.Context, sub FormSubmission, orgID uuid.UUID) ExtractedFields {
+	if sub.APIKeyID == uuid.Nil {
+		return ExtractFields(sub.Fields)
+	}
+	cfg, err := s.repo.GetFieldMapping(ctx, sub.APIKeyID, orgID)
+	if err != nil {
+		if !errors.Is(err, ErrFieldMappingNotFound) {
+			s.log.Error("webhook: failed to load field mapping, using heuristics", "error", err, "apiKeyId", sub.APIKeyID)
+		}
+		return ExtractFields(sub.Fields)
+	}
+	if !cfg.IsActive {
+		return ExtractFields(sub.Fields)
+	}
+	return ApplyFieldMapping(cfg, sub.Fields, sub.RawJSON)
+}
+
+// GetFieldMapping returns the field mapping configured for an API key.
+func (s *Service) GetFieldMapping(ctx context.Context, apiKeyID uuid.UUID, orgID uuid.UUID) (FieldMappingConfig, error) {
+	return s.repo.GetFieldMapping(ctx, apiKeyID, orgID)
+}
+
+// SaveFieldMapping validates and stores the field mapping for an API key.
+func (s *Service) SaveFieldMapping(ctx context.Context, apiKeyID uuid.UUID, orgID uuid.UUID, req UpsertFieldMappingRequest) (FieldMappingConfig, error) {
+	cfg := NewFieldMappingConfig(orgID, apiKeyID, req)
+	if err := ValidateFieldMappingConfig(cfg); err != nil {
+		return FieldMappingConfig{}, err
+	}
+	return s.repo.UpsertFieldMapping(ctx, cfg)
+}
+
+// DeleteFieldMapping removes the field mapping for an API key, reverting it to heuristic extraction.
+func (s *Service) DeleteFieldMapping(ctx context.Context, apiKeyID uuid.UUID, orgID uuid.UUID) error {
+	return s.repo.DeleteFieldMapping(ctx, apiKeyID, orgID)
+}
+
+// PreviewFieldMapping runs a sample payload through either the supplied or the stored mapping.
+func (s *Service) PreviewFieldMapping(ctx context.Context, apiKeyID uuid.UUID, orgID uuid.UUID, req PreviewFieldMappingRequest) (FieldMappingPreview, error) {
+	var cfg FieldMappingConfig
+	if req.Mapping != nil {
+		cfg = NewFieldMappingConfig(orgID, apiKeyID, *req.Mapping)
+		if err := ValidateFieldMappingConfig(cfg); err != nil {
+			return FieldMappingPreview{}, err
+		}
+	} else {
+		stored, err := s.repo.GetFieldMapping(ctx, apiKeyID, orgID)
+		if errors.Is(err, ErrFieldMappingNotFound) {
+			stored = NewFieldMappingConfig(orgID, apiKeyID, UpsertFieldMappingRequest{})
+		} else if err != nil {
+			return FieldMappingPreview{}, err
+		}
+		cfg = stored
+	}
+	return PreviewFieldMapping(cfg, req.Payload), nil
 }
 
 func (s *Service) recordServiceTypeFallbackEvent(ctx context.Context, leadResp transport.LeadResponse, orgID uuid.UUID, requestedServiceType string, normalizedRequestedType string, sourceDomain string) {
@@ -246,6 +369,7 @@ func (s *Service) ProcessGoogleLeadWebhook(ctx context.Context, payload GoogleLe
 }
 
 func buildCreateLeadRequest(extracted ExtractedFields, sourceDomain string) transport.CreateLeadRequest {
+	fillAttributionFromURLs(&extracted)
 	return transport.CreateLeadRequest{
 		FirstName:     normalizeName(extracted.FirstName),
 		LastName:      normalizeName(extracted.LastName),
@@ -260,6 +384,7 @@ func buildCreateLeadRequest(extracted ExtractedFields, sourceDomain string) tran
 		ConsumerNote:  extracted.Message,
 		Source:        "webhook:" + sourceDomain,
 		GCLID:         extracted.GCLID,
+		FBCLID:        extracted.FBCLID,
 		UTMSource:     extracted.UTMSource,
 		UTMMedium:     extracted.UTMMedium,
 		UTMCampaign:   extracted.UTMCampaign,
@@ -331,6 +456,9 @@ func buildExtractedMap(extracted ExtractedFields) map[string]string {
 	if extracted.ServiceType != "" {
 		result["serviceType"] = extracted.ServiceType
 	}
+	for key, value := range extracted.CustomFields {
+		result[CustomFieldPrefix+key] = value
+	}
 	return result
 }
 
@@ -0,0 +1,396 @@
+package webhook
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"portal_final_backend/platform/phone"
+
+	"github.com/google/uuid"
+)
+
+// Spam policy modes. In flag mode likely junk still becomes a lead but gets a
+// timeline alert; in auto_junk mode it is held back in the review queue.
+const (
+	SpamModeOff      = "off"
+	SpamModeFlag     = "flag"
+	SpamModeAutoJunk = "auto_junk"
+)
+
+// DefaultHoneypotField is the hidden field the form capture SDK adds to every
+// captured form. People never see it, so a filled in value means a bot.
+const DefaultHoneypotField = "_rac_hp"
+
+const defaultJunkThreshold = 70
+
+// Reasons a submission scored as spam.
+const (
+	SpamReasonHoneypot         = "honeypot"
+	SpamReasonDisposableEmail  = "disposable_email"
+	SpamReasonBlockedEmail     = "blocked_email_domain"
+	SpamReasonInvalidEmail     = "invalid_email"
+	SpamReasonImplausiblePhone = "implausible_phone"
+	SpamReasonLinks            = "links"
+	SpamReasonGibberish        = "gibberish"
+	SpamReasonSpamKeywords     = "spam_keywords"
+)
+
+var spamReasonWeights = map[string]int{
+	SpamReasonHoneypot:         100,
+	SpamReasonDisposableEmail:  60,
+	SpamReasonBlockedEmail:     80,
+	SpamReasonInvalidEmail:     30,
+	SpamReasonImplausiblePhone: 35,
+	SpamReasonGibberish:        35,
+	SpamReasonSpamKeywords:     30,
+}
+
+// Links weigh more the more of them a submission contains.
+const (
+	spamLinkWeight     = 20
+	spamManyLinkWeight = 40
+)
+
+// honeypotFieldNames are honeypot names common form plugins use, checked on
+// top of the SDK field and the organization's own field.
+var honeypotFieldNames = []string{DefaultHoneypotField, "_gotcha", "_honeypot", "honeypot", "hp_field"}
+
+// disposableEmailDomains are throwaway mailbox providers. Subdomains match too.
+var disposableEmailDomains = map[string]struct{}{
+	"10minutemail.com": {}, "10minutemail.net": {}, "20minutemail.com": {}, "33mail.com": {},
+	"anonbox.net": {}, "burnermail.io": {}, "discard.email": {}, "dispostable.com": {},
+	"emailfake.com": {}, "emailondeck.com": {}, "fakeinbox.com": {}, "fakemail.net": {},
+	"getairmail.com": {}, "getnada.com": {}, "grr.la": {}, "guerrillamail.biz": {},
+	"guerrillamail.com": {}, "guerrillamail.de": {}, "guerrillamail.net": {}, "guerrillamail.org": {},
+	"guerrillamailblock.com": {}, "harakirimail.com": {}, "inboxkitten.com": {}, "incognitomail.org": {},
+	"jetable.org": {}, "mail.tm": {}, "mailcatch.com": {}, "maildrop.cc": {},
+	"mailinator.com": {}, "mailinator.net": {}, "mailnesia.com": {}, "mailpoof.com": {},
+	"mailsac.com": {}, "mintemail.com": {}, "moakt.com": {}, "mohmal.com": {},
+	"mytemp.email": {}, "nada.email": {}, "sharklasers.com": {}, "spam4.me": {},
+	"spambox.us": {}, "spamgourmet.com": {}, "temp-mail.io": {}, "temp-mail.org": {},
+	"tempinbox.com": {}, "tempmail.com": {}, "tempmail.net": {}, "tempmailo.com": {},
+	"tempr.email": {}, "throwawaymail.com": {}, "tmpmail.net": {}, "tmpmail.org": {},
+	"trashmail.com": {}, "trashmail.de": {}, "trashmail.net": {}, "yopmail.com": {},
+	"yopmail.fr": {}, "yopmail.net": {},
+}
+
+// spamKeywords are phrases that practically never occur in a genuine request
+// for a home service.
+var spamKeywords = []string{
+	"viagra", "cialis", "casino", "bitcoin", "crypto", "forex", "backlink",
+	"seo services", "seo-diensten", "guest post", "escort", "porn", "onlyfans",
+	"betting", "investment opportunity", "make money online",
+}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\[url=`)
+
+// SpamPolicy is an organization's spam guard configuration for form capture.
+type SpamPolicy struct {
+	OrganizationID      uuid.UUID  `json:"organizationId"`
+	Mode                string     `json:"mode"`
+	JunkThreshold       int        `json:"junkThreshold"`
+	HoneypotField       *string    `json:"honeypotField,omitempty"`
+	BlockedEmailDomains []string   `json:"blockedEmailDomains"`
+	UpdatedAt           *time.Time `json:"updatedAt,omitempty"`
+}
+
+// UpdateSpamPolicyRequest replaces an organization's spam policy.
+type UpdateSpamPolicyRequest struct {
+	Mode                string   `json:"mode" validate:"required,oneof=off flag auto_junk"`
+	JunkThreshold       int      `json:"junkThreshold" validate:"required,min=1,max=100"`
+	HoneypotField       *string  `json:"honeypotField,omitempty" validate:"omitempty,max=100"`
+	BlockedEmailDomains []string `json:"blockedEmailDomains" validate:"max=200,dive,required,max=253"`
+}
+
+// DefaultSpamPolicy is the policy of organizations that never configured one:
+// likely junk is flagged but still becomes a lead.
+func DefaultSpamPolicy(orgID uuid.UUID) SpamPolicy {
+	return SpamPolicy{
+		OrganizationID:      orgID,
+		Mode:                SpamModeFlag,
+		JunkThreshold:       defaultJunkThreshold,
+		BlockedEmailDomains: []string{},
+	}
+}
+
+// NewSpamPolicy builds the policy stored for an update request.
+func NewSpamPolicy(orgID uuid.UUID, req UpdateSpamPolicyRequest) SpamPolicy {
+	policy := SpamPolicy{
+		OrganizationID:      orgID,
+		Mode:                req.Mode,
+		JunkThreshold:       req.JunkThreshold,
+		BlockedEmailDomains: []string{},
+	}
+	if req.HoneypotField != nil {
+		if field := strings.TrimSpace(*req.HoneypotField); field != "" {
+			policy.HoneypotField = &field
+		}
+	}
+	seen := map[string]struct{}{}
+	for _, domain := range req.BlockedEmailDomains {
+		domain = normalizeEmailDomain(domain)
+		if _, ok := seen[domain]; ok || domain == "" {
+			continue
+		}
+		seen[domain] = struct{}{}
+		policy.BlockedEmailDomains = append(policy.BlockedEmailDomains, domain)
+	}
+	return policy
+}
+
+// SpamAssessment is the spam score of a submission, 0 to 100, with the
+// reasons that contributed to it.
+type SpamAssessment struct {
+	Score    int      `json:"score"`
+	Reasons  []string `json:"reasons"`
+	Honeypot bool     `json:"honeypot"`
+}
+
+// IsJunk reports whether the submission counts as junk under the policy. A
+// filled in honeypot always does.
+func (p SpamPolicy) IsJunk(a SpamAssessment) bool {
+	return a.Honeypot || a.Score >= p.JunkThreshold
+}
+
+func (p SpamPolicy) honeypotFields() []string {
+	if p.HoneypotField == nil {
+		return honeypotFieldNames
+	}
+	return append([]string{*p.HoneypotField}, honeypotFieldNames...)
+}
+
+// HoneypotFilled reports whether any honeypot field of the submission has a
+// value.
+func (p SpamPolicy) HoneypotFilled(fields map[string]string) bool {
+	for _, name := range p.honeypotFields() {
+		if strings.TrimSpace(fields[name]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// stripHoneypot removes the honeypot fields from a submission so they are
+// neither mapped onto the lead nor stored with it.
+func (p SpamPolicy) stripHoneypot(sub FormSubmission) FormSubmission {
+	names := p.honeypotFields()
+	fields := make(map[string]string, len(sub.Fields))
+	for key, value := range sub.Fields {
+		fields[key] = value
+	}
+	for _, name := range names {
+		delete(fields, name)
+	}
+	sub.Fields = fields
+
+	if sub.RawJSON != nil {
+		raw := make(map[string]any, len(sub.RawJSON))
+		for key, value := range sub.RawJSON {
+			raw[key] = value
+		}
+		for _, name := range names {
+			delete(raw, name)
+		}
+		sub.RawJSON = raw
+	}
+	return sub
+}
+
+// Assess scores the extracted fields of a submission.
+func (p SpamPolicy) Assess(extracted ExtractedFields, honeypotFilled bool) SpamAssessment {
+	assessment := SpamAssessment{Reasons: []string{}, Honeypot: honeypotFilled}
+	add := func(reason string, weight int) {
+		assessment.Reasons = append(assessment.Reasons, reason)
+		assessment.Score += weight
+	}
+
+	if honeypotFilled {
+		add(SpamReasonHoneypot, spamReasonWeights[SpamReasonHoneypot])
+	}
+	if reason := p.emailReason(extracted.Email); reason != "" {
+		add(reason, spamReasonWeights[reason])
+	}
+	if extracted.Phone != "" && !IsPlausiblePhone(extracted.Phone) {
+		add(SpamReasonImplausiblePhone, spamReasonWeights[SpamReasonImplausiblePhone])
+	}
+
+	text := strings.Join([]string{extracted.FirstName, extracted.LastName, extracted.Street, extracted.City, extracted.Message}, " ")
+	switch links := len(linkPattern.FindAllString(text, -1)); {
+	case links > 1:
+		add(SpamReasonLinks, spamManyLinkWeight)
+	case links == 1:
+		add(SpamReasonLinks, spamLinkWeight)
+	}
+	if isGibberishName(extracted.FirstName+" "+extracted.LastName) || isGibberishText(extracted.Message) {
+		add(SpamReasonGibberish, spamReasonWeights[SpamReasonGibberish])
+	}
+	if containsSpamKeyword(text) {
+		add(SpamReasonSpamKeywords, spamReasonWeights[SpamReasonSpamKeywords])
+	}
+
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+	return assessment
+}
+
+func (p SpamPolicy) emailReason(email string) string {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ""
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return SpamReasonInvalidEmail
+	}
+	at := strings.LastIndex(addr.Address, "@")
+	domain := normalizeEmailDomain(addr.Address[at+1:])
+	if !strings.Contains(domain, ".") {
+		return SpamReasonInvalidEmail
+	}
+	for _, blocked := range p.BlockedEmailDomains {
+		if domainMatches(domain, blocked) {
+			return SpamReasonBlockedEmail
+		}
+	}
+	if IsDisposableEmailDomain(domain) {
+		return SpamReasonDisposableEmail
+	}
+	return ""
+}
+
+// IsDisposableEmailDomain reports whether the domain, or a parent of it,
+// belongs to a throwaway mailbox provider.
+func IsDisposableEmailDomain(domain string) bool {
+	domain = normalizeEmailDomain(domain)
+	for domain != "" {
+		if _, ok := disposableEmailDomains[domain]; ok {
+			return true
+		}
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return false
+		}
+		domain = domain[dot+1:]
+	}
+	return false
+}
+
+func normalizeEmailDomain(domain string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@"), ".")
+}
+
+func domainMatches(domain, parent string) bool {
+	return domain == parent || strings.HasSuffix(domain, "."+parent)
+}
+
+// IsPlausiblePhone reports whether a phone number is a valid number that is
+// not an obvious filler like 0612345678 or 0000000000.
+func IsPlausiblePhone(value string) bool {
+	if phone.RegionCode(value) == "" {
+		return false
+	}
+	digits := make([]rune, 0, len(value))
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) < 7 {
+		return false
+	}
+	subscriber := digits[len(digits)-7:]
+	same, ascending, descending := true, true, true
+	for i := 1; i < len(subscriber); i++ {
+		same = same && subscriber[i] == subscriber[0]
+		ascending = ascending && subscriber[i] == subscriber[i-1]+1
+		descending = descending && subscriber[i] == subscriber[i-1]-1
+	}
+	return !same && !ascending && !descending
+}
+
+func containsSpamKeyword(text string) bool {
+	text = strings.ToLower(text)
+	for _, keyword := range spamKeywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGibberishName reports whether any part of a name looks like keyboard
+// mashing.
+func isGibberishName(name string) bool {
+	for _, word := range strings.Fields(name) {
+		if isGibberishWord(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGibberishText reports whether at least half of the longer words of a
+// message look like keyboard mashing.
+func isGibberishText(text string) bool {
+	words, gibberish := 0, 0
+	for _, word := range strings.Fields(text) {
+		if countLetters(word) < 6 {
+			continue
+		}
+		words++
+		if isGibberishWord(word) {
+			gibberish++
+		}
+	}
+	return words > 0 && gibberish*2 >= words
+}
+
+// isGibberishWord flags words of six or more letters without any vowel, with
+// erratic casing like "hGfTrK", or with one letter repeated four times.
+func isGibberishWord(word string) bool {
+	if countLetters(word) < 6 {
+		return false
+	}
+	var (
+		hasVowel    bool
+		caseChanges int
+		repeat      int
+		prev        rune
+	)
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			prev, repeat = 0, 0
+			continue
+		}
+		if strings.ContainsRune("aeiouyàáâäèéêëìíîïòóôöùúûü", unicode.ToLower(r)) {
+			hasVowel = true
+		}
+		if prev != 0 && unicode.IsUpper(r) != unicode.IsUpper(prev) {
+			caseChanges++
+		}
+		if unicode.ToLower(r) == unicode.ToLower(prev) {
+			repeat++
+			if repeat >= 3 {
+				return true
+			}
+		} else {
+			repeat = 0
+		}
+		prev = r
+	}
+	return !hasVowel || caseChanges >= 4
+}
+
+func countLetters(word string) int {
+	n := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/leads/transport"
+
+	"github.com/google/uuid"
+)
+
+// Spam review statuses.
+const (
+	SpamReviewPending   = "pending"
+	SpamReviewReleased  = "released"
+	SpamReviewDismissed = "dismissed"
+)
+
+const defaultSpamReviewLimit = 50
+
+// SpamReview is a form submission held back as junk until someone releases or
+// dismisses it.
+type SpamReview struct {
+	ID             uuid.UUID         `json:"id"`
+	OrganizationID uuid.UUID         `json:"organizationId"`
+	APIKeyID       *uuid.UUID        `json:"apiKeyId,omitempty"`
+	SourceDomain   string            `json:"sourceDomain"`
+	Fields         map[string]string `json:"fields"`
+	RawJSON        map[string]any    `json:"-"`
+	SpamScore      int               `json:"spamScore"`
+	SpamReasons    []string          `json:"spamReasons"`
+	Status         string            `json:"status"`
+	LeadID         *uuid.UUID        `json:"leadId,omitempty"`
+	ReviewedBy     *uuid.UUID        `json:"reviewedBy,omitempty"`
+	ReviewedAt     *time.Time        `json:"reviewedAt,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
+
+// ListSpamReviewsRequest filters the spam review queue. Pending reviews are
+// listed when no status is given.
+type ListSpamReviewsRequest struct {
+	Status string `form:"status" validate:"omitempty,oneof=pending released dismissed"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// SpamReviewListResponse lists spam reviews, newest first.
+type SpamReviewListResponse struct {
+	Items []SpamReview `json:"items"`
+	Total int          `json:"total"`
+}
+
+// loadSpamPolicy returns the spam policy of an organization. Form capture
+// must keep working when it cannot be loaded, so it falls back to the default.
+func (s *Service) loadSpamPolicy(ctx context.Context, orgID uuid.UUID) SpamPolicy {
+	policy, err := s.repo.GetSpamPolicy(ctx, orgID)
+	if err != nil {
+		s.log.Error("webhook: failed to load spam policy, using default", "error", err, "orgId", orgID)
+		return DefaultSpamPolicy(orgID)
+	}
+	return policy
+}
+
+// GetSpamPolicy returns the spam policy of an organization.
+func (s *Service) GetSpamPolicy(ctx context.Context, orgID uuid.UUID) (SpamPolicy, error) {
+	return s.repo.GetSpamPolicy(ctx, orgID)
+}
+
+// UpdateSpamPolicy replaces the spam policy of an organization.
+func (s *Service) UpdateSpamPolicy(ctx context.Context, orgID uuid.UUID, req UpdateSpamPolicyRequest) (SpamPolicy, error) {
+	return s.repo.UpsertSpamPolicy(ctx, NewSpamPolicy(orgID, req))
+}
+
+// holdForSpamReview parks a junk submission in the review queue instead of
+// creating a lead. The sender gets the same kind of answer as for a real
+// submission so bots learn nothing; uploaded files are discarded.
+func (s *Service) holdForSpamReview(ctx context.Context, sub FormSubmission, extracted ExtractedFields, orgID uuid.UUID, assessment SpamAssessment) (FormSubmissionResponse, error) {
+	var apiKeyID *uuid.UUID
+	if sub.APIKeyID != uuid.Nil {
+		apiKeyID = &sub.APIKeyID
+	}
+
+	review, err := s.repo.CreateSpamReview(ctx, SpamReview{
+		OrganizationID: orgID,
+		APIKeyID:       apiKeyID,
+		SourceDomain:   sub.SourceDomain,
+		Fields:         sub.Fields,
+		RawJSON:        sub.RawJSON,
+		SpamScore:      assessment.Score,
+		SpamReasons:    assessment.Reasons,
+	})
+	if err != nil {
+		s.log.Error("webhook: failed to hold submission for spam review", "error", err, "domain", sub.SourceDomain)
+		return FormSubmissionResponse{}, err
+	}
+
+	s.log.Info("webhook: submission held for spam review",
+		"reviewId", review.ID,
+		"score", assessment.Score,
+		"reasons", strings.Join(assessment.Reasons, ","),
+		"discardedFiles", len(sub.Files),
+		"domain", sub.SourceDomain,
+	)
+
+	return FormSubmissionResponse{
+		IsIncomplete: extracted.IsIncomplete(),
+		Extracted:    buildExtractedMap(extracted),
+		Message:      "Submission received",
+	}, nil
+}
+
+func (s *Service) recordSpamFlagEvent(ctx context.Context, leadResp transport.LeadResponse, orgID uuid.UUID, assessment SpamAssessment, sourceDomain string) {
+	_, serviceID := resolveAppliedServiceType(leadResp)
+	summary := "Aanvraag lijkt op spam: " + strings.Join(assessment.Reasons, ", ")
+	err := s.repo.CreateTimelineEvent(ctx, createTimelineEventParams{
+		LeadID:         leadResp.ID,
+		ServiceID:      serviceID,
+		OrganizationID: orgID,
+		ActorType:      "System",
+		ActorName:      "Spamfilter",
+		EventType:      "alert",
+		Title:          "Mogelijke spam",
+		Summary:        &summary,
+		Metadata: map[string]any{
+			"trigger":   "webhook_spam_guard",
+			"spamScore": assessment.Score,
+			"reasons":   assessment.Reasons,
+			"source":    sourceDomain,
+		},
+	})
+	if err != nil {
+		s.log.Error("webhook: failed to record spam flag timeline event", "error", err, "leadId", leadResp.ID)
+	}
+}
+
+// ListSpamReviews returns the spam review queue of an organization.
+func (s *Service) ListSpamReviews(ctx context.Context, orgID uuid.UUID, req ListSpamReviewsRequest) (SpamReviewListResponse, error) {
+	status := req.Status
+	if status == "" {
+		status = SpamReviewPending
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSpamReviewLimit
+	}
+
+	items, total, err := s.repo.ListSpamReviews(ctx, orgID, status, limit)
+	if err != nil {
+		return SpamReviewListResponse{}, err
+	}
+	return SpamReviewListResponse{Items: items, Total: total}, nil
+}
+
+// ReleaseSpamReview overrides the spam verdict of a held submission and
+// creates its lead as if it had come in just now.
+func (s *Service) ReleaseSpamReview(ctx context.Context, id, orgID, userID uuid.UUID) (SpamReview, error) {
+	review, err := s.repo.ResolveSpamReview(ctx, id, orgID, SpamReviewReleased, userID)
+	if err != nil {
+		return SpamReview{}, err
+	}
+
+	sub := FormSubmission{
+		Fields:       review.Fields,
+		SourceDomain: review.SourceDomain,
+		RawJSON:      review.RawJSON,
+	}
+	if review.APIKeyID != nil {
+		sub.APIKeyID = *review.APIKeyID
+	}
+
+	leadResp, err := s.createSubmissionLead(ctx, sub, s.extractSubmissionFields(ctx, sub, orgID), orgID)
+	if err != nil {
+		if reopenErr := s.repo.ReopenSpamReview(ctx, id, orgID); reopenErr != nil {
+			s.log.Error("webhook: failed to reopen spam review", "error", reopenErr, "reviewId", id)
+		}
+		return SpamReview{}, err
+	}
+
+	if err := s.repo.SetSpamReviewLead(ctx, id, orgID, leadResp.ID); err != nil {
+		s.log.Error("webhook: failed to link released spam review to lead", "error", err, "reviewId", id, "leadId", leadResp.ID)
+	}
+	review.LeadID = &leadResp.ID
+
+	_, serviceID := resolveAppliedServiceType(leadResp)
+	summary := "Door spamfilter tegengehouden aanvraag handmatig vrijgegeven"
+	if err := s.repo.CreateTimelineEvent(ctx, createTimelineEventParams{
+		LeadID:         leadResp.ID,
+		ServiceID:      serviceID,
+		OrganizationID: orgID,
+		ActorType:      "User",
+		ActorName:      "Spamcontrole",
+		EventType:      "note",
+		Title:          "Vrijgegeven uit spamcontrole",
+		Summary:        &summary,
+		Metadata: map[string]any{
+			"spamReviewId": review.ID,
+			"spamScore":    review.SpamScore,
+			"reasons":      review.SpamReasons,
+			"releasedBy":   userID,
+		},
+	}); err != nil {
+		s.log.Error("webhook: failed to record spam release timeline event", "error", err, "leadId", leadResp.ID)
+	}
+
+	return review, nil
+}
+
+// DismissSpamReview confirms a held submission as junk.
+func (s *Service) DismissSpamReview(ctx context.Context, id, orgID, userID uuid.UUID) (SpamReview, error) {
+	return s.repo.ResolveSpamReview(ctx, id, orgID, SpamReviewDismissed, userID)
+}
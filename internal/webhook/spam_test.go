@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSpamPolicyAssessGenuineSubmission(t *testing.T) {
+	policy := DefaultSpamPolicy(uuid.New())
+	assessment := policy.Assess(ExtractedFields{
+		FirstName: "Jan",
+		LastName:  "de Vries",
+		Email:     "jan.devries@gmail.com",
+		Phone:     "06 38 47 29 15",
+		Message:   "Graag een offerte voor het isoleren van onze spouwmuur.",
+	}, false)
+	if assessment.Score != 0 || len(assessment.Reasons) != 0 {
+		t.Fatalf("assessment = %+v, want clean", assessment)
+	}
+}
+
+func TestSpamPolicyAssessJunkSubmission(t *testing.T) {
+	policy := DefaultSpamPolicy(uuid.New())
+	assessment := policy.Assess(ExtractedFields{
+		FirstName: "hGfTrKsL",
+		Email:     "bot@sub.mailinator.com",
+		Phone:     "0612345678",
+		Message:   "Cheap SEO services https://spam.example https://more.example",
+	}, false)
+	for _, reason := range []string{SpamReasonDisposableEmail, SpamReasonImplausiblePhone, SpamReasonLinks, SpamReasonGibberish, SpamReasonSpamKeywords} {
+		if !slices.Contains(assessment.Reasons, reason) {
+			t.Errorf("reasons = %v, missing %s", assessment.Reasons, reason)
+		}
+	}
+	if assessment.Score != 100 || !policy.IsJunk(assessment) {
+		t.Fatalf("score = %d, want capped junk score", assessment.Score)
+	}
+}
+
+func TestSpamPolicyHoneypotIsAlwaysJunk(t *testing.T) {
+	field := "website"
+	policy := DefaultSpamPolicy(uuid.New())
+	policy.HoneypotField = &field
+	policy.JunkThreshold = 100
+
+	sub := FormSubmission{Fields: map[string]string{"name": "Jan", "website": "http://x.example", "_rac_hp": ""}}
+	if !policy.HoneypotFilled(sub.Fields) {
+		t.Fatal("expected organization honeypot field to trip")
+	}
+	stripped := policy.stripHoneypot(sub)
+	if _, ok := stripped.Fields["website"]; ok {
+		t.Fatal("honeypot field not stripped")
+	}
+	if _, ok := sub.Fields["website"]; !ok {
+		t.Fatal("stripping modified the original fields")
+	}
+	if !policy.IsJunk(policy.Assess(ExtractedFields{FirstName: "Jan"}, true)) {
+		t.Fatal("honeypot submission not junk")
+	}
+}
+
+func TestSpamPolicyBlockedEmailDomains(t *testing.T) {
+	policy := NewSpamPolicy(uuid.New(), UpdateSpamPolicyRequest{
+		Mode:                SpamModeAutoJunk,
+		JunkThreshold:       50,
+		BlockedEmailDomains: []string{" @Example.COM ", "example.com"},
+	})
+	if !slices.Equal(policy.BlockedEmailDomains, []string{"example.com"}) {
+		t.Fatalf("blocked domains = %v", policy.BlockedEmailDomains)
+	}
+	assessment := policy.Assess(ExtractedFields{Email: "sales@mail.example.com"}, false)
+	if !slices.Contains(assessment.Reasons, SpamReasonBlockedEmail) || !policy.IsJunk(assessment) {
+		t.Fatalf("assessment = %+v, want blocked domain junk", assessment)
+	}
+}
+
+func TestIsPlausiblePhone(t *testing.T) {
+	cases := map[string]bool{
+		"+31 6 38472915": true,
+		"020 624 1111":   true,
+		"0612345678":     false,
+		"0687654321":     false,
+		"0600000000":     false,
+		"12345":          false,
+	}
+	for input, want := range cases {
+		if got := IsPlausiblePhone(input); got != want {
+			t.Errorf("IsPlausiblePhone(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
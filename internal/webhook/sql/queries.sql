@@ -43,8 +43,8 @@ SELECT id
 FROM RAC_leads
 WHERE organization_id = $1
 	AND created_at >= now() - make_interval(secs => $2)
-	AND (CAST($3 AS text) = '' OR consumer_email = CAST($3 AS text))
-	AND (CAST($4 AS text) = '' OR consumer_phone = CAST($4 AS text))
+	AND (CAST($3 AS text) = '' OR consumer_email = CAST($3 AS text) OR consumer_email_bidx = CAST($5 AS text))
+	AND (CAST($4 AS text) = '' OR consumer_phone = CAST($4 AS text) OR consumer_phone_bidx = CAST($6 AS text))
 ORDER BY created_at DESC
 LIMIT 1;
 
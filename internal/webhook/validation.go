@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/phone"
+
+	"github.com/google/uuid"
+)
+
+// What happens to a submission that fails its source's validation policy.
+const (
+	ValidationActionReject     = "reject"
+	ValidationActionQuarantine = "quarantine"
+)
+
+// Rules a submission can violate.
+const (
+	ValidationRuleRequired     = "required"
+	ValidationRulePattern      = "pattern"
+	ValidationRulePostcode     = "postcode"
+	ValidationRulePhoneCountry = "phone_country"
+)
+
+// postcodeFormats are the postcode formats a policy can demand, by country.
+var postcodeFormats = map[string]*regexp.Regexp{
+	"NL": regexp.MustCompile(`^[1-9][0-9]{3} ?[A-Za-z]{2}$`),
+	"BE": regexp.MustCompile(`^[1-9][0-9]{3}$`),
+	"DE": regexp.MustCompile(`^[0-9]{5}$`),
+}
+
+// validatableLeadFields are the extracted lead fields a policy can check, on
+// top of custom fields.
+var validatableLeadFields = map[string]struct{}{
+	MappedFieldFirstName: {}, MappedFieldLastName: {}, MappedFieldEmail: {}, MappedFieldPhone: {},
+	MappedFieldStreet: {}, MappedFieldHouseNumber: {}, MappedFieldZipCode: {}, MappedFieldCity: {},
+	MappedFieldMessage: {}, MappedFieldServiceType: {},
+}
+
+// ValidationPolicy describes the checks the extracted fields of one webhook
+// source must pass before they become a lead.
+type ValidationPolicy struct {
+	ID             uuid.UUID               `json:"id"`
+	OrganizationID uuid.UUID               `json:"organizationId"`
+	APIKeyID       uuid.UUID               `json:"apiKeyId"`
+	RequiredFields []string                `json:"requiredFields"`
+	PatternRules   []ValidationPatternRule `json:"patternRules"`
+	// PostcodeFormat is the country whose postcode format the zip code must have.
+	PostcodeFormat *string `json:"postcodeFormat,omitempty"`
+	// PhoneCountries are the regions a phone number may belong to.
+	PhoneCountries []string  `json:"phoneCountries"`
+	Action         string    `json:"action"`
+	IsActive       bool      `json:"isActive"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// ValidationPatternRule requires a filled in field to match a regular expression.
+type ValidationPatternRule struct {
+	Field   string `json:"field" validate:"required,max=200"`
+	Pattern string `json:"pattern" validate:"required,max=500"`
+	Message string `json:"message,omitempty" validate:"max=200"`
+}
+
+// UpsertValidationPolicyRequest is the admin request body for saving a validation policy.
+type UpsertValidationPolicyRequest struct {
+	RequiredFields []string                `json:"requiredFields" validate:"max=30,dive,required,max=200"`
+	PatternRules   []ValidationPatternRule `json:"patternRules" validate:"max=30,dive"`
+	PostcodeFormat *string                 `json:"postcodeFormat,omitempty" validate:"omitempty,oneof=NL BE DE"`
+	PhoneCountries []string                `json:"phoneCountries" validate:"max=20,dive,len=2"`
+	Action         string                  `json:"action" validate:"required,oneof=reject quarantine"`
+	IsActive       *bool                   `json:"isActive,omitempty"`
+}
+
+// ValidationViolation is one failed check of a submission.
+type ValidationViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// NewValidationPolicy builds a validation policy from an admin request, applying defaults.
+func NewValidationPolicy(orgID, apiKeyID uuid.UUID, req UpsertValidationPolicyRequest) ValidationPolicy {
+	policy := ValidationPolicy{
+		OrganizationID: orgID,
+		APIKeyID:       apiKeyID,
+		RequiredFields: req.RequiredFields,
+		PatternRules:   req.PatternRules,
+		PostcodeFormat: req.PostcodeFormat,
+		Action:         req.Action,
+		IsActive:       true,
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+	if policy.RequiredFields == nil {
+		policy.RequiredFields = []string{}
+	}
+	if policy.PatternRules == nil {
+		policy.PatternRules = []ValidationPatternRule{}
+	}
+	policy.PhoneCountries = make([]string, 0, len(req.PhoneCountries))
+	for _, country := range req.PhoneCountries {
+		policy.PhoneCountries = append(policy.PhoneCountries, strings.ToUpper(country))
+	}
+	return policy
+}
+
+// ValidateValidationPolicy checks that a policy only refers to known fields and
+// that its patterns compile.
+func ValidateValidationPolicy(policy ValidationPolicy) error {
+	for _, field := range policy.RequiredFields {
+		if !isValidatableField(field) {
+			return apperr.Validation(fmt.Sprintf("unknown lead field %q in requiredFields", field))
+		}
+	}
+	for i, rule := range policy.PatternRules {
+		if !isValidatableField(rule.Field) {
+			return apperr.Validation(fmt.Sprintf("patternRules[%d]: unknown lead field %q", i, rule.Field))
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return apperr.Validation(fmt.Sprintf("patternRules[%d]: invalid pattern", i))
+		}
+	}
+	if policy.PostcodeFormat != nil {
+		if _, ok := postcodeFormats[*policy.PostcodeFormat]; !ok {
+			return apperr.Validation(fmt.Sprintf("unsupported postcode format %q", *policy.PostcodeFormat))
+		}
+	}
+	return nil
+}
+
+func isValidatableField(field string) bool {
+	if key, ok := strings.CutPrefix(field, CustomFieldPrefix); ok {
+		return key != ""
+	}
+	_, ok := validatableLeadFields[field]
+	return ok
+}
+
+// ValidateSubmission checks extracted fields against a policy and returns
+// every violation, in policy order. Pattern, postcode and phone checks only
+// apply to filled in fields; demanding a value is up to RequiredFields.
+func ValidateSubmission(policy ValidationPolicy, extracted ExtractedFields) []ValidationViolation {
+	violations := make([]ValidationViolation, 0)
+	add := func(field, rule, message string) {
+		violations = append(violations, ValidationViolation{Field: field, Rule: rule, Message: message})
+	}
+
+	for _, field := range policy.RequiredFields {
+		if extractedValue(extracted, field) == "" {
+			add(field, ValidationRuleRequired, "field is required")
+		}
+	}
+
+	for _, rule := range policy.PatternRules {
+		value := extractedValue(extracted, rule.Field)
+		if value == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			continue
+		}
+		if !pattern.MatchString(value) {
+			message := rule.Message
+			if message == "" {
+				message = "value does not have the expected format"
+			}
+			add(rule.Field, ValidationRulePattern, message)
+		}
+	}
+
+	if policy.PostcodeFormat != nil && extracted.ZipCode != "" {
+		if format, ok := postcodeFormats[*policy.PostcodeFormat]; ok && !format.MatchString(extracted.ZipCode) {
+			add(MappedFieldZipCode, ValidationRulePostcode, "postcode is not a valid "+*policy.PostcodeFormat+" postcode")
+		}
+	}
+
+	if len(policy.PhoneCountries) > 0 && extracted.Phone != "" {
+		region := phone.RegionCode(extracted.Phone)
+		allowed := false
+		for _, country := range policy.PhoneCountries {
+			if region != "" && region == country {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			add(MappedFieldPhone, ValidationRulePhoneCountry, "phone number is not from "+strings.Join(policy.PhoneCountries, ", "))
+		}
+	}
+
+	return violations
+}
+
+// extractedValue returns the trimmed value of a lead or custom field.
+func extractedValue(extracted ExtractedFields, field string) string {
+	if key, ok := strings.CutPrefix(field, CustomFieldPrefix); ok {
+		return strings.TrimSpace(extracted.CustomFields[key])
+	}
+	if target := extractedFieldPtr(&extracted, field); target != nil {
+		return strings.TrimSpace(*target)
+	}
+	return ""
+}
+
+// applyCorrections overwrites extracted fields with the values an agent
+// corrected. Unknown fields are ignored.
+func applyCorrections(extracted *ExtractedFields, corrections map[string]string) {
+	for field, value := range corrections {
+		value = strings.TrimSpace(value)
+		if key, ok := strings.CutPrefix(field, CustomFieldPrefix); ok && key != "" {
+			if extracted.CustomFields == nil {
+				extracted.CustomFields = map[string]string{}
+			}
+			extracted.CustomFields[key] = value
+			continue
+		}
+		if target := extractedFieldPtr(extracted, field); target != nil {
+			*target = value
+		}
+	}
+}
+
+func extractedFieldPtr(extracted *ExtractedFields, field string) *string {
+	switch field {
+	case MappedFieldFirstName:
+		return &extracted.FirstName
+	case MappedFieldLastName:
+		return &extracted.LastName
+	case MappedFieldEmail:
+		return &extracted.Email
+	case MappedFieldPhone:
+		return &extracted.Phone
+	case MappedFieldStreet:
+		return &extracted.Street
+	case MappedFieldHouseNumber:
+		return &extracted.HouseNumber
+	case MappedFieldZipCode:
+		return &extracted.ZipCode
+	case MappedFieldCity:
+		return &extracted.City
+	case MappedFieldMessage:
+		return &extracted.Message
+	case MappedFieldServiceType:
+		return &extracted.ServiceType
+	default:
+		return nil
+	}
+}
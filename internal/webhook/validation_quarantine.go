@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Quarantine statuses.
+const (
+	QuarantinePending     = "pending"
+	QuarantineResubmitted = "resubmitted"
+	QuarantineDismissed   = "dismissed"
+)
+
+const defaultQuarantineLimit = 50
+
+// QuarantinedSubmission is a form submission that failed the validation policy
+// of its source and waits for an agent to fix and resubmit or dismiss it.
+type QuarantinedSubmission struct {
+	ID             uuid.UUID             `json:"id"`
+	OrganizationID uuid.UUID             `json:"organizationId"`
+	APIKeyID       *uuid.UUID            `json:"apiKeyId,omitempty"`
+	SourceDomain   string                `json:"sourceDomain"`
+	Fields         map[string]string     `json:"fields"`
+	RawJSON        map[string]any        `json:"-"`
+	Extracted      map[string]string     `json:"extracted"`
+	Violations     []ValidationViolation `json:"violations"`
+	Corrections    map[string]string     `json:"corrections"`
+	Status         string                `json:"status"`
+	LeadID         *uuid.UUID            `json:"leadId,omitempty"`
+	ReviewedBy     *uuid.UUID            `json:"reviewedBy,omitempty"`
+	ReviewedAt     *time.Time            `json:"reviewedAt,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+}
+
+// ListQuarantineRequest filters the quarantine queue. Pending submissions are
+// listed when no status is given.
+type ListQuarantineRequest struct {
+	Status string `form:"status" validate:"omitempty,oneof=pending resubmitted dismissed"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// QuarantineListResponse lists quarantined submissions, newest first.
+type QuarantineListResponse struct {
+	Items []QuarantinedSubmission `json:"items"`
+	Total int                     `json:"total"`
+}
+
+// ResubmitQuarantineRequest carries the corrected lead fields, keyed like the
+// fields of a validation policy ("zipCode", "custom.roof_type").
+type ResubmitQuarantineRequest struct {
+	Fields map[string]string `json:"fields" validate:"max=30,dive,max=1000"`
+}
+
+// loadValidationPolicy returns the active validation policy of a submission's
+// API key, or nil when there is none. Form capture must keep working when it
+// cannot be loaded, so errors only get logged.
+func (s *Service) loadValidationPolicy(ctx context.Context, apiKeyID, orgID uuid.UUID) *ValidationPolicy {
+	if apiKeyID == uuid.Nil {
+		return nil
+	}
+	policy, err := s.repo.GetValidationPolicy(ctx, apiKeyID, orgID)
+	if err != nil {
+		if !errors.Is(err, ErrValidationPolicyNotFound) {
+			s.log.Error("webhook: failed to load validation policy, skipping validation", "error", err, "apiKeyId", apiKeyID)
+		}
+		return nil
+	}
+	if !policy.IsActive {
+		return nil
+	}
+	return &policy
+}
+
+// GetValidationPolicy returns the validation policy configured for an API key.
+func (s *Service) GetValidationPolicy(ctx context.Context, apiKeyID, orgID uuid.UUID) (ValidationPolicy, error) {
+	return s.repo.GetValidationPolicy(ctx, apiKeyID, orgID)
+}
+
+// SaveValidationPolicy validates and stores the validation policy for an API key.
+func (s *Service) SaveValidationPolicy(ctx context.Context, apiKeyID, orgID uuid.UUID, req UpsertValidationPolicyRequest) (ValidationPolicy, error) {
+	policy := NewValidationPolicy(orgID, apiKeyID, req)
+	if err := ValidateValidationPolicy(policy); err != nil {
+		return ValidationPolicy{}, err
+	}
+	return s.repo.UpsertValidationPolicy(ctx, policy)
+}
+
+// DeleteValidationPolicy removes the validation policy for an API key.
+func (s *Service) DeleteValidationPolicy(ctx context.Context, apiKeyID, orgID uuid.UUID) error {
+	return s.repo.DeleteValidationPolicy(ctx, apiKeyID, orgID)
+}
+
+// rejectSubmission answers a submission that failed validation with the
+// violations, so the form provider can show or log them.
+func rejectSubmission(violations []ValidationViolation) error {
+	return apperr.Validation("submission failed validation").WithDetails(violations)
+}
+
+// quarantineSubmission parks a submission that failed validation in the
+// quarantine queue instead of creating a lead. Uploaded files are discarded.
+func (s *Service) quarantineSubmission(ctx context.Context, sub FormSubmission, extracted ExtractedFields, orgID uuid.UUID, violations []ValidationViolation) (FormSubmissionResponse, error) {
+	var apiKeyID *uuid.UUID
+	if sub.APIKeyID != uuid.Nil {
+		apiKeyID = &sub.APIKeyID
+	}
+
+	item, err := s.repo.CreateQuarantinedSubmission(ctx, QuarantinedSubmission{
+		OrganizationID: orgID,
+		APIKeyID:       apiKeyID,
+		SourceDomain:   sub.SourceDomain,
+		Fields:         sub.Fields,
+		RawJSON:        sub.RawJSON,
+		Extracted:      quarantineSnapshot(extracted),
+		Violations:     violations,
+		Corrections:    map[string]string{},
+	})
+	if err != nil {
+		s.log.Error("webhook: failed to quarantine submission", "error", err, "domain", sub.SourceDomain)
+		return FormSubmissionResponse{}, err
+	}
+
+	s.log.Info("webhook: submission quarantined",
+		"quarantineId", item.ID,
+		"violations", len(violations),
+		"discardedFiles", len(sub.Files),
+		"domain", sub.SourceDomain,
+	)
+
+	return FormSubmissionResponse{
+		IsIncomplete: extracted.IsIncomplete(),
+		Extracted:    buildExtractedMap(extracted),
+		Message:      "Submission received for review",
+	}, nil
+}
+
+// quarantineSnapshot lists the extracted fields an agent gets to correct.
+func quarantineSnapshot(extracted ExtractedFields) map[string]string {
+	snapshot := buildExtractedMap(extracted)
+	if extracted.Message != "" {
+		snapshot[MappedFieldMessage] = extracted.Message
+	}
+	return snapshot
+}
+
+// ListQuarantine returns the quarantine queue of an organization.
+func (s *Service) ListQuarantine(ctx context.Context, orgID uuid.UUID, req ListQuarantineRequest) (QuarantineListResponse, error) {
+	status := req.Status
+	if status == "" {
+		status = QuarantinePending
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultQuarantineLimit
+	}
+
+	items, total, err := s.repo.ListQuarantinedSubmissions(ctx, orgID, status, limit)
+	if err != nil {
+		return QuarantineListResponse{}, err
+	}
+	return QuarantineListResponse{Items: items, Total: total}, nil
+}
+
+// ResubmitQuarantined applies an agent's corrections to a quarantined
+// submission, validates it against the current policy of its source and
+// creates the lead. Corrections are kept when it still fails, so the next
+// attempt builds on them.
+func (s *Service) ResubmitQuarantined(ctx context.Context, id, orgID, userID uuid.UUID, req ResubmitQuarantineRequest) (QuarantinedSubmission, error) {
+	item, err := s.repo.GetQuarantinedSubmission(ctx, id, orgID)
+	if err != nil {
+		return QuarantinedSubmission{}, err
+	}
+	if item.Status != QuarantinePending {
+		return QuarantinedSubmission{}, ErrQuarantineNotFound
+	}
+
+	sub := FormSubmission{
+		Fields:       item.Fields,
+		SourceDomain: item.SourceDomain,
+		RawJSON:      item.RawJSON,
+	}
+	if item.APIKeyID != nil {
+		sub.APIKeyID = *item.APIKeyID
+	}
+
+	corrections := maps.Clone(item.Corrections)
+	if corrections == nil {
+		corrections = map[string]string{}
+	}
+	maps.Copy(corrections, req.Fields)
+
+	extracted := s.extractSubmissionFields(ctx, sub, orgID)
+	applyCorrections(&extracted, corrections)
+
+	if policy := s.loadValidationPolicy(ctx, sub.APIKeyID, orgID); policy != nil {
+		if violations := ValidateSubmission(*policy, extracted); len(violations) > 0 {
+			if err := s.repo.UpdateQuarantineCorrections(ctx, id, orgID, corrections, violations); err != nil {
+				s.log.Error("webhook: failed to store quarantine corrections", "error", err, "quarantineId", id)
+			}
+			return QuarantinedSubmission{}, rejectSubmission(violations)
+		}
+	}
+
+	item, err = s.repo.ResolveQuarantinedSubmission(ctx, id, orgID, QuarantineResubmitted, userID, corrections)
+	if err != nil {
+		return QuarantinedSubmission{}, err
+	}
+
+	leadResp, err := s.createSubmissionLead(ctx, sub, extracted, orgID)
+	if err != nil {
+		if reopenErr := s.repo.ReopenQuarantinedSubmission(ctx, id, orgID); reopenErr != nil {
+			s.log.Error("webhook: failed to reopen quarantined submission", "error", reopenErr, "quarantineId", id)
+		}
+		return QuarantinedSubmission{}, err
+	}
+
+	if err := s.repo.SetQuarantineLead(ctx, id, orgID, leadResp.ID); err != nil {
+		s.log.Error("webhook: failed to link resubmitted submission to lead", "error", err, "quarantineId", id, "leadId", leadResp.ID)
+	}
+	item.LeadID = &leadResp.ID
+
+	_, serviceID := resolveAppliedServiceType(leadResp)
+	summary := "Afgekeurde aanvraag gecorrigeerd en opnieuw ingediend"
+	if err := s.repo.CreateTimelineEvent(ctx, createTimelineEventParams{
+		LeadID:         leadResp.ID,
+		ServiceID:      serviceID,
+		OrganizationID: orgID,
+		ActorType:      "User",
+		ActorName:      "Quarantaine",
+		EventType:      "note",
+		Title:          "Opnieuw ingediend uit quarantaine",
+		Summary:        &summary,
+		Metadata: map[string]any{
+			"quarantineId":  item.ID,
+			"violations":    item.Violations,
+			"corrections":   corrections,
+			"resubmittedBy": userID,
+		},
+	}); err != nil {
+		s.log.Error("webhook: failed to record quarantine resubmit timeline event", "error", err, "leadId", leadResp.ID)
+	}
+
+	return item, nil
+}
+
+// DismissQuarantined drops a quarantined submission without creating a lead.
+func (s *Service) DismissQuarantined(ctx context.Context, id, orgID, userID uuid.UUID) (QuarantinedSubmission, error) {
+	return s.repo.ResolveQuarantinedSubmission(ctx, id, orgID, QuarantineDismissed, userID, nil)
+}
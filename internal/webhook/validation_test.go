@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"testing"
+
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateSubmissionReportsEveryViolation(t *testing.T) {
+	nl := "NL"
+	policy := NewValidationPolicy(uuid.New(), uuid.New(), UpsertValidationPolicyRequest{
+		RequiredFields: []string{MappedFieldEmail, "custom.roof_type"},
+		PatternRules:   []ValidationPatternRule{{Field: MappedFieldHouseNumber, Pattern: `^[0-9]+`, Message: "house number must start with a digit"}},
+		PostcodeFormat: &nl,
+		PhoneCountries: []string{"nl"},
+		Action:         ValidationActionQuarantine,
+	})
+	if err := ValidateValidationPolicy(policy); err != nil {
+		t.Fatalf("policy rejected: %v", err)
+	}
+
+	violations := ValidateSubmission(policy, ExtractedFields{
+		FirstName:   "Jan",
+		Phone:       "+49 30 901820",
+		HouseNumber: "A12",
+		ZipCode:     "12345",
+	})
+	want := []ValidationViolation{
+		{Field: MappedFieldEmail, Rule: ValidationRuleRequired},
+		{Field: "custom.roof_type", Rule: ValidationRuleRequired},
+		{Field: MappedFieldHouseNumber, Rule: ValidationRulePattern},
+		{Field: MappedFieldZipCode, Rule: ValidationRulePostcode},
+		{Field: MappedFieldPhone, Rule: ValidationRulePhoneCountry},
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("violations = %+v, want %d", violations, len(want))
+	}
+	for i, v := range violations {
+		if v.Field != want[i].Field || v.Rule != want[i].Rule {
+			t.Errorf("violation %d = %s/%s, want %s/%s", i, v.Field, v.Rule, want[i].Field, want[i].Rule)
+		}
+	}
+	if violations[2].Message != "house number must start with a digit" {
+		t.Errorf("pattern message = %q", violations[2].Message)
+	}
+}
+
+func TestValidateSubmissionAcceptsCorrectedFields(t *testing.T) {
+	nl := "NL"
+	policy := NewValidationPolicy(uuid.New(), uuid.New(), UpsertValidationPolicyRequest{
+		RequiredFields: []string{MappedFieldEmail, "custom.roof_type"},
+		PostcodeFormat: &nl,
+		PhoneCountries: []string{"NL"},
+		Action:         ValidationActionReject,
+	})
+	extracted := ExtractedFields{FirstName: "Jan", Phone: "+49 30 901820", ZipCode: "12345"}
+
+	applyCorrections(&extracted, map[string]string{
+		MappedFieldEmail:   " jan@example.nl ",
+		MappedFieldPhone:   "06 38 47 29 15",
+		MappedFieldZipCode: "1234 AB",
+		"custom.roof_type": "plat",
+		"unknown":          "ignored",
+	})
+	if violations := ValidateSubmission(policy, extracted); len(violations) != 0 {
+		t.Fatalf("violations = %+v, want none", violations)
+	}
+	if extracted.Email != "jan@example.nl" {
+		t.Errorf("email = %q, want trimmed correction", extracted.Email)
+	}
+}
+
+func TestValidateValidationPolicyRejectsBadRules(t *testing.T) {
+	orgID, keyID := uuid.New(), uuid.New()
+	tests := map[string]UpsertValidationPolicyRequest{
+		"unknown required field": {RequiredFields: []string{"fax"}, Action: ValidationActionReject},
+		"unknown pattern field":  {PatternRules: []ValidationPatternRule{{Field: "fax", Pattern: ".*"}}, Action: ValidationActionReject},
+		"invalid pattern":        {PatternRules: []ValidationPatternRule{{Field: MappedFieldCity, Pattern: "("}}, Action: ValidationActionReject},
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateValidationPolicy(NewValidationPolicy(orgID, keyID, req))
+			if !apperr.Is(err, apperr.KindValidation) {
+				t.Fatalf("err = %v, want validation error", err)
+			}
+		})
+	}
+}
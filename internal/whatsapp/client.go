@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/circuitbreaker"
 	"portal_final_backend/platform/config"
 	"portal_final_backend/platform/logger"
 	"portal_final_backend/platform/phone"
@@ -291,8 +292,11 @@ func NewClient(cfg config.WhatsAppConfig, log *logger.Logger) *Client {
 		baseHost:          hostFromURL(cfg.GetWhatsAppURL()),
 		apiKey:            cfg.GetWhatsAppKey(),
 		apiKeyFingerprint: fingerprintKey(cfg.GetWhatsAppKey()),
-		http:              &http.Client{Timeout: 10 * time.Second},
-		log:               log,
+		http: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: circuitbreaker.Transport(circuitbreaker.For(circuitbreaker.DependencyWhatsApp), nil),
+		},
+		log: log,
 	}
 }
 
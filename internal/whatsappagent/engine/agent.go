@@ -22,6 +22,7 @@ import (
 
 	"portal_final_backend/internal/orchestration"
 	apptools "portal_final_backend/internal/tools"
+	"portal_final_backend/platform/ai/llmrouter"
 	"portal_final_backend/platform/ai/openaicompat"
 	"portal_final_backend/platform/logger"
 )
@@ -192,7 +193,7 @@ func NewAgent(modelCfg openaicompat.Config, toolHandler *ToolHandler, log *logge
 }
 
 func newAgentRuntime(modelCfg openaicompat.Config, workspace orchestration.Workspace, sessionService session.Service, toolHandler *ToolHandler, cfg agentRuntimeConfig) (agentRuntime, error) {
-	kimi := openaicompat.NewModel(modelCfg)
+	kimi := llmrouter.NewModel(modelCfg)
 	tools, err := cfg.toolBuilder(toolHandler)
 	if err != nil {
 		return agentRuntime{}, err
@@ -704,6 +705,7 @@ func (a *Agent) Run(ctx context.Context, orgID uuid.UUID, phoneKey string, messa
 
 func (a *Agent) enrichRunContext(ctx context.Context, orgID uuid.UUID, phoneKey string, inboundMessage *CurrentInboundMessage) context.Context {
 	ctx = context.WithValue(ctx, orgIDContextKey{}, orgID)
+	ctx = llmrouter.WithSource(llmrouter.WithOrganizationID(ctx, orgID), "whatsapp-agent")
 	ctx = context.WithValue(ctx, phoneKeyContextKey{}, strings.TrimSpace(phoneKey))
 	if inboundMessage != nil {
 		ctx = context.WithValue(ctx, currentInboundMessageContextKey{}, *inboundMessage)
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+
+	"portal_final_backend/internal/workorders/service"
+	"portal_final_backend/internal/workorders/transport"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	svc *service.Service
+	val *validator.Validator
+	// tokenLocator finds the organization of confirmation tokens across data
+	// schemas.
+	tokenLocator httpkit.PublicTokenResolver
+}
+
+func New(svc *service.Service, val *validator.Validator) *Handler {
+	return &Handler{svc: svc, val: val}
+}
+
+// RegisterRoutes registers planning and progressing the work orders of the
+// organization.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.List)
+	rg.GET("/:id", h.Get)
+	rg.PUT("/:id/planning", h.UpdatePlanning)
+	rg.PATCH("/:id/status", h.UpdateStatus)
+}
+
+// SetTokenLocator sets the lookup of confirmation tokens that live in the
+// dedicated data schema of their organization.
+func (h *Handler) SetTokenLocator(locator httpkit.PublicTokenResolver) {
+	h.tokenLocator = locator
+}
+
+func (h *Handler) tokenOrganization(ctx context.Context, token string) (uuid.UUID, bool, error) {
+	if h.tokenLocator == nil {
+		return uuid.Nil, false, nil
+	}
+	return h.tokenLocator(ctx, token)
+}
+
+// RegisterPublicRoutes registers the confirmation page of the customer and the
+// warranty claims submitted from it.
+func (h *Handler) RegisterPublicRoutes(rg *gin.RouterGroup) {
+	rg.Use(httpkit.PublicTokenTenant(h.tokenOrganization))
+	rg.GET("/:token", h.GetPublic)
+	rg.POST("/:token/confirm", h.ConfirmPublic)
+	rg.POST("/:token/warranty-claims", h.SubmitWarrantyClaim)
+}
+
+func (h *Handler) List(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListWorkOrdersRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.List(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.Get(c.Request.Context(), tenantID, id)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) UpdatePlanning(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.UpdatePlanningRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.UpdatePlanning(c.Request.Context(), tenantID, id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) UpdateStatus(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.UpdateStatusRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.UpdateStatus(c.Request.Context(), tenantID, id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) GetPublic(c *gin.Context) {
+	resp, err := h.svc.GetPublic(c.Request.Context(), c.Param("token"))
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) ConfirmPublic(c *gin.Context) {
+	req, ok := httpkit.BindJSON[transport.ConfirmWorkOrderRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ConfirmPublic(c.Request.Context(), c.Param("token"), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"net/http"
+
+	"portal_final_backend/internal/workorders/transport"
+	"portal_final_backend/platform/httpkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterWarrantyRoutes registers the warranties of completed work orders and
+// the triage of the claims against them.
+func (h *Handler) RegisterWarrantyRoutes(warranties, claims *gin.RouterGroup) {
+	warranties.GET("", h.ListWarranties)
+	claims.GET("", h.ListWarrantyClaims)
+	claims.PATCH("/:id", h.TriageWarrantyClaim)
+}
+
+// RegisterWarrantyTermsRoutes registers managing the warranty terms per
+// service type.
+func (h *Handler) RegisterWarrantyTermsRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.ListWarrantyTerms)
+	rg.PUT("/:serviceTypeId", h.UpsertWarrantyTerms)
+	rg.DELETE("/:serviceTypeId", h.DeleteWarrantyTerms)
+}
+
+func (h *Handler) ListWarrantyTerms(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListWarrantyTerms(c.Request.Context(), tenantID)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) UpsertWarrantyTerms(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	serviceTypeID, ok := httpkit.ParseUUIDParam(c, "serviceTypeId")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.UpsertWarrantyTermsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.UpsertWarrantyTerms(c.Request.Context(), tenantID, serviceTypeID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) DeleteWarrantyTerms(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	serviceTypeID, ok := httpkit.ParseUUIDParam(c, "serviceTypeId")
+	if !ok {
+		return
+	}
+
+	if httpkit.HandleError(c, h.svc.DeleteWarrantyTerms(c.Request.Context(), tenantID, serviceTypeID)) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ListWarranties(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListWarrantiesRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListWarranties(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) ListWarrantyClaims(c *gin.Context) {
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindQuery[transport.ListWarrantyClaimsRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.ListWarrantyClaims(c.Request.Context(), tenantID, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) TriageWarrantyClaim(c *gin.Context) {
+	identity := httpkit.MustGetIdentity(c)
+	if identity == nil {
+		return
+	}
+	tenantID, ok := httpkit.RequireTenant(c)
+	if !ok {
+		return
+	}
+	id, ok := httpkit.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, ok := httpkit.BindJSON[transport.TriageWarrantyClaimRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.TriageWarrantyClaim(c.Request.Context(), tenantID, identity.UserID(), id, req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	httpkit.OK(c, resp)
+}
+
+func (h *Handler) SubmitWarrantyClaim(c *gin.Context) {
+	req, ok := httpkit.BindJSON[transport.SubmitWarrantyClaimRequest](c, h.val)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.SubmitWarrantyClaim(c.Request.Context(), c.Param("token"), req)
+	if httpkit.HandleError(c, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
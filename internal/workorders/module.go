@@ -0,0 +1,79 @@
+// Package workorders tracks the execution of the work after a partner accepted
+// an offer: planned dates, progress, and the confirmation of the customer,
+// which closes the lead service and starts invoicing. Completed work orders
+// get the warranty of their service type, against which customers can claim.
+package workorders
+
+import (
+	"context"
+
+	"portal_final_backend/internal/events"
+	apphttp "portal_final_backend/internal/http"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/workorders/handler"
+	"portal_final_backend/internal/workorders/repository"
+	"portal_final_backend/internal/workorders/service"
+	"portal_final_backend/platform/httpkit"
+	"portal_final_backend/platform/logger"
+	"portal_final_backend/platform/validator"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Module struct {
+	service *service.Service
+	handler *handler.Handler
+	log     *logger.Logger
+}
+
+func NewModule(pool *pgxpool.Pool, eventBus events.Bus, timeline leadsrepo.TimelineEventStore, val *validator.Validator, log *logger.Logger) *Module {
+	svc := service.New(repository.New(pool), timeline, eventBus, log)
+	return &Module{
+		service: svc,
+		handler: handler.New(svc, val),
+		log:     log,
+	}
+}
+
+// Service returns the work orders service.
+func (m *Module) Service() *service.Service {
+	return m.service
+}
+
+func (m *Module) Name() string {
+	return "workorders"
+}
+
+// SetPublicTokenLocator injects the lookup of confirmation tokens whose work
+// orders live in a dedicated data schema.
+func (m *Module) SetPublicTokenLocator(locator httpkit.PublicTokenResolver) {
+	m.handler.SetTokenLocator(locator)
+}
+
+func (m *Module) RegisterRoutes(ctx *apphttp.RouterContext) {
+	m.handler.RegisterRoutes(ctx.Protected.Group("/work-orders"))
+	m.handler.RegisterWarrantyRoutes(ctx.Protected.Group("/warranties"), ctx.Protected.Group("/warranty-claims"))
+	m.handler.RegisterWarrantyTermsRoutes(ctx.Admin.Group("/warranty-terms"))
+	m.handler.RegisterPublicRoutes(ctx.Public.Group("/work-orders"))
+}
+
+// RegisterHandlers subscribes the module to system-wide events.
+func (m *Module) RegisterHandlers(bus *events.InMemoryBus) {
+	bus.Subscribe(events.PartnerOfferAccepted{}.EventName(), m)
+}
+
+// Handle processes subscribed domain events.
+func (m *Module) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.PartnerOfferAccepted:
+		if err := m.service.CreateForAcceptedOffer(ctx, e); err != nil {
+			m.log.Error("failed to create work order for accepted offer", "offerId", e.OfferID, "error", err)
+			return err
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+var _ apphttp.Module = (*Module)(nil)
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Work order statuses.
+const (
+	StatusScheduled  = "scheduled"
+	StatusInProgress = "in_progress"
+	StatusOnHold     = "on_hold"
+	StatusCompleted  = "completed"
+)
+
+var ErrNotFound = errors.New("work order not found")
+
+// Repository persists work orders.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// WorkOrder is the execution of the work on a lead service after a partner
+// accepted the offer.
+type WorkOrder struct {
+	ID                  uuid.UUID
+	OrganizationID      uuid.UUID
+	LeadID              uuid.UUID
+	LeadServiceID       uuid.UUID
+	PartnerOfferID      *uuid.UUID
+	PartnerID           *uuid.UUID
+	QuoteID             *uuid.UUID
+	Status              string
+	PlannedStartDate    *time.Time
+	PlannedEndDate      *time.Time
+	HoldReason          *string
+	StartedAt           *time.Time
+	CompletedAt         *time.Time
+	ConfirmationToken   *string
+	CustomerConfirmedAt *time.Time
+	CustomerFeedback    *string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// PublicWorkOrder is a work order with the details shown to the customer on
+// the confirmation page.
+type PublicWorkOrder struct {
+	WorkOrder
+	OrganizationName string
+	ServiceType      string
+	PartnerName      *string
+}
+
+// ListParams filters the work orders of an organization.
+type ListParams struct {
+	OrganizationID uuid.UUID
+	Status         string
+	LeadServiceID  *uuid.UUID
+	Limit          int
+	Offset         int
+}
+
+const workOrderColumns = `w.id, w.organization_id, w.lead_id, w.lead_service_id, w.partner_offer_id, w.partner_id, w.quote_id,
+	w.status, w.planned_start_date, w.planned_end_date, w.hold_reason, w.started_at, w.completed_at,
+	w.confirmation_token, w.customer_confirmed_at, w.customer_feedback, w.created_at, w.updated_at`
+
+func workOrderFields(w *WorkOrder) []any {
+	return []any{&w.ID, &w.OrganizationID, &w.LeadID, &w.LeadServiceID, &w.PartnerOfferID, &w.PartnerID, &w.QuoteID,
+		&w.Status, &w.PlannedStartDate, &w.PlannedEndDate, &w.HoldReason, &w.StartedAt, &w.CompletedAt,
+		&w.ConfirmationToken, &w.CustomerConfirmedAt, &w.CustomerFeedback, &w.CreatedAt, &w.UpdatedAt}
+}
+
+func scanWorkOrder(row pgx.Row) (WorkOrder, error) {
+	var w WorkOrder
+	if err := row.Scan(workOrderFields(&w)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return WorkOrder{}, ErrNotFound
+		}
+		return WorkOrder{}, err
+	}
+	return w, nil
+}
+
+// CreateForAcceptedOffer creates the work order of an accepted partner offer,
+// linked to the latest accepted quote of the service. It returns false when
+// the offer already has a work order.
+func (r *Repository) CreateForAcceptedOffer(ctx context.Context, organizationID, leadID, leadServiceID, offerID, partnerID uuid.UUID) (WorkOrder, bool, error) {
+	w, err := scanWorkOrder(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_work_orders AS w (organization_id, lead_id, lead_service_id, partner_offer_id, partner_id, quote_id)
+		VALUES ($1, $2, $3, $4, $5, (
+			SELECT q.id FROM RAC_quotes q
+			WHERE q.lead_service_id = $3 AND q.organization_id = $1 AND q.status = 'Accepted'
+			ORDER BY q.created_at DESC
+			LIMIT 1
+		))
+		ON CONFLICT (partner_offer_id) WHERE partner_offer_id IS NOT NULL DO NOTHING
+		RETURNING `+workOrderColumns,
+		organizationID, leadID, leadServiceID, offerID, partnerID,
+	))
+	if errors.Is(err, ErrNotFound) {
+		return WorkOrder{}, false, nil
+	}
+	if err != nil {
+		return WorkOrder{}, false, fmt.Errorf("create work order: %w", err)
+	}
+	return w, true, nil
+}
+
+func (r *Repository) Get(ctx context.Context, id, organizationID uuid.UUID) (WorkOrder, error) {
+	return scanWorkOrder(r.pool.QueryRow(ctx, `
+		SELECT `+workOrderColumns+`
+		FROM RAC_work_orders w
+		WHERE w.id = $1 AND w.organization_id = $2`, id, organizationID,
+	))
+}
+
+// List returns the work orders of an organization by planned start date, with
+// unplanned work orders last, and the total number of matches.
+func (r *Repository) List(ctx context.Context, p ListParams) ([]WorkOrder, int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+workOrderColumns+`, count(*) OVER ()
+		FROM RAC_work_orders w
+		WHERE w.organization_id = $1
+			AND ($2 = '' OR w.status = $2)
+			AND ($3::uuid IS NULL OR w.lead_service_id = $3)
+		ORDER BY w.planned_start_date ASC NULLS LAST, w.created_at DESC
+		LIMIT $4 OFFSET $5`, p.OrganizationID, p.Status, p.LeadServiceID, p.Limit, p.Offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list work orders: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WorkOrder, 0)
+	total := 0
+	for rows.Next() {
+		var w WorkOrder
+		if err := rows.Scan(append(workOrderFields(&w), &total)...); err != nil {
+			return nil, 0, fmt.Errorf("scan work order: %w", err)
+		}
+		items = append(items, w)
+	}
+	return items, total, rows.Err()
+}
+
+// UpdatePlanning sets the planned dates of a work order that is not completed.
+func (r *Repository) UpdatePlanning(ctx context.Context, id, organizationID uuid.UUID, start, end *time.Time) (WorkOrder, error) {
+	return scanWorkOrder(r.pool.QueryRow(ctx, `
+		UPDATE RAC_work_orders w
+		SET planned_start_date = $3, planned_end_date = $4, updated_at = now()
+		WHERE w.id = $1 AND w.organization_id = $2 AND w.status <> 'completed'
+		RETURNING `+workOrderColumns, id, organizationID, start, end,
+	))
+}
+
+// StatusUpdate is a status transition of a work order. The update only applies
+// while the work order still has FromStatus.
+type StatusUpdate struct {
+	ID                uuid.UUID
+	OrganizationID    uuid.UUID
+	FromStatus        string
+	ToStatus          string
+	HoldReason        *string
+	ConfirmationToken *string
+}
+
+// UpdateStatus applies a status transition. It returns ErrNotFound when the
+// work order changed status in the meantime.
+func (r *Repository) UpdateStatus(ctx context.Context, u StatusUpdate) (WorkOrder, error) {
+	return scanWorkOrder(r.pool.QueryRow(ctx, `
+		UPDATE RAC_work_orders w
+		SET status = $4,
+			hold_reason = $5,
+			started_at = CASE WHEN $4 = 'in_progress' THEN COALESCE(w.started_at, now()) ELSE w.started_at END,
+			completed_at = CASE WHEN $4 = 'completed' THEN now() ELSE w.completed_at END,
+			confirmation_token = COALESCE($6, w.confirmation_token),
+			updated_at = now()
+		WHERE w.id = $1 AND w.organization_id = $2 AND w.status = $3
+		RETURNING `+workOrderColumns, u.ID, u.OrganizationID, u.FromStatus, u.ToStatus, u.HoldReason, u.ConfirmationToken,
+	))
+}
+
+// GetByConfirmationToken returns a work order with the details shown on the
+// public confirmation page.
+func (r *Repository) GetByConfirmationToken(ctx context.Context, token string) (PublicWorkOrder, error) {
+	var w PublicWorkOrder
+	err := r.pool.QueryRow(ctx, `
+		SELECT `+workOrderColumns+`, org.name, st.name, p.business_name
+		FROM RAC_work_orders w
+		JOIN RAC_organizations org ON org.id = w.organization_id
+		JOIN RAC_lead_services ls ON ls.id = w.lead_service_id
+		JOIN RAC_service_types st ON st.id = ls.service_type_id AND st.organization_id = ls.organization_id
+		LEFT JOIN RAC_partners p ON p.id = w.partner_id
+		WHERE w.confirmation_token = $1`, token,
+	).Scan(append(workOrderFields(&w.WorkOrder), &w.OrganizationName, &w.ServiceType, &w.PartnerName)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PublicWorkOrder{}, ErrNotFound
+		}
+		return PublicWorkOrder{}, fmt.Errorf("get work order by token: %w", err)
+	}
+	return w, nil
+}
+
+// ConfirmByCustomer records the confirmation of a completed work order. It
+// returns ErrNotFound when the work order was already confirmed.
+func (r *Repository) ConfirmByCustomer(ctx context.Context, token string, feedback *string) (WorkOrder, error) {
+	return scanWorkOrder(r.pool.QueryRow(ctx, `
+		UPDATE RAC_work_orders w
+		SET customer_confirmed_at = now(), customer_feedback = $2, updated_at = now()
+		WHERE w.confirmation_token = $1 AND w.status = 'completed' AND w.customer_confirmed_at IS NULL
+		RETURNING `+workOrderColumns, token, feedback,
+	))
+}
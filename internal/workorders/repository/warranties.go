@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Warranty claim statuses.
+const (
+	ClaimStatusOpen     = "open"
+	ClaimStatusInReview = "in_review"
+	ClaimStatusAccepted = "accepted"
+	ClaimStatusRejected = "rejected"
+	ClaimStatusResolved = "resolved"
+)
+
+// WarrantyTerms are the warranty given on completed work of a service type.
+type WarrantyTerms struct {
+	ServiceTypeID   uuid.UUID
+	ServiceTypeName string
+	OrganizationID  uuid.UUID
+	DurationMonths  int
+	CoveredItems    []string
+	UpdatedAt       time.Time
+}
+
+// Warranty is the warranty on a completed work order.
+type Warranty struct {
+	ID                   uuid.UUID
+	OrganizationID       uuid.UUID
+	LeadID               uuid.UUID
+	LeadServiceID        uuid.UUID
+	WorkOrderID          uuid.UUID
+	StartsOn             time.Time
+	ExpiresOn            time.Time
+	CoveredItems         []string
+	ExpiryReminderSentAt *time.Time
+	CreatedAt            time.Time
+}
+
+// WarrantyClaim is a claim of the customer against a warranty, with the work
+// order and lead it concerns.
+type WarrantyClaim struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	WarrantyID     uuid.UUID
+	WorkOrderID    uuid.UUID
+	LeadID         uuid.UUID
+	LeadServiceID  uuid.UUID
+	Description    string
+	Status         string
+	TriageNote     *string
+	TriagedBy      *uuid.UUID
+	TriagedAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ClaimListParams filters the warranty claims of an organization.
+type ClaimListParams struct {
+	OrganizationID uuid.UUID
+	Status         string
+	Limit          int
+	Offset         int
+}
+
+// ClaimTriage is the assessment of a warranty claim by an agent. It only
+// applies while the claim still has FromStatus.
+type ClaimTriage struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	FromStatus     string
+	Status         string
+	Note           *string
+	TriagedBy      uuid.UUID
+}
+
+func (r *Repository) ListWarrantyTerms(ctx context.Context, organizationID uuid.UUID) ([]WarrantyTerms, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT t.service_type_id, st.name, t.organization_id, t.duration_months, t.covered_items, t.updated_at
+		FROM RAC_service_type_warranty_terms t
+		JOIN RAC_service_types st ON st.id = t.service_type_id
+		WHERE t.organization_id = $1
+		ORDER BY st.name`, organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list warranty terms: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WarrantyTerms, 0)
+	for rows.Next() {
+		var t WarrantyTerms
+		if err := rows.Scan(&t.ServiceTypeID, &t.ServiceTypeName, &t.OrganizationID, &t.DurationMonths, &t.CoveredItems, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan warranty terms: %w", err)
+		}
+		items = append(items, t)
+	}
+	return items, rows.Err()
+}
+
+// UpsertWarrantyTerms sets the warranty terms of a service type of the
+// organization. It returns ErrNotFound when the service type does not exist.
+func (r *Repository) UpsertWarrantyTerms(ctx context.Context, t WarrantyTerms) (WarrantyTerms, error) {
+	err := r.pool.QueryRow(ctx, `
+		WITH upserted AS (
+			INSERT INTO RAC_service_type_warranty_terms (service_type_id, organization_id, duration_months, covered_items)
+			SELECT st.id, st.organization_id, $3, $4
+			FROM RAC_service_types st
+			WHERE st.id = $1 AND st.organization_id = $2
+			ON CONFLICT (service_type_id) DO UPDATE
+			SET duration_months = EXCLUDED.duration_months, covered_items = EXCLUDED.covered_items, updated_at = now()
+			RETURNING service_type_id, organization_id, duration_months, covered_items, updated_at
+		)
+		SELECT u.service_type_id, st.name, u.organization_id, u.duration_months, u.covered_items, u.updated_at
+		FROM upserted u
+		JOIN RAC_service_types st ON st.id = u.service_type_id`,
+		t.ServiceTypeID, t.OrganizationID, t.DurationMonths, t.CoveredItems,
+	).Scan(&t.ServiceTypeID, &t.ServiceTypeName, &t.OrganizationID, &t.DurationMonths, &t.CoveredItems, &t.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return WarrantyTerms{}, ErrNotFound
+	}
+	if err != nil {
+		return WarrantyTerms{}, fmt.Errorf("upsert warranty terms: %w", err)
+	}
+	return t, nil
+}
+
+// DeleteWarrantyTerms removes the warranty terms of a service type. Warranties
+// already given are kept.
+func (r *Repository) DeleteWarrantyTerms(ctx context.Context, serviceTypeID, organizationID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM RAC_service_type_warranty_terms
+		WHERE service_type_id = $1 AND organization_id = $2`, serviceTypeID, organizationID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete warranty terms: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const warrantyColumns = `wa.id, wa.organization_id, wa.lead_id, wa.lead_service_id, wa.work_order_id,
+	wa.starts_on, wa.expires_on, wa.covered_items, wa.expiry_reminder_sent_at, wa.created_at`
+
+func scanWarranty(row pgx.Row) (Warranty, error) {
+	var w Warranty
+	err := row.Scan(&w.ID, &w.OrganizationID, &w.LeadID, &w.LeadServiceID, &w.WorkOrderID,
+		&w.StartsOn, &w.ExpiresOn, &w.CoveredItems, &w.ExpiryReminderSentAt, &w.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Warranty{}, ErrNotFound
+	}
+	return w, err
+}
+
+// CreateWarrantyForWorkOrder gives a completed work order the warranty of its
+// service type, starting on the completion date. It returns false when the
+// service type has no warranty terms or the work order already has a warranty.
+func (r *Repository) CreateWarrantyForWorkOrder(ctx context.Context, workOrderID, organizationID uuid.UUID) (Warranty, bool, error) {
+	w, err := scanWarranty(r.pool.QueryRow(ctx, `
+		INSERT INTO RAC_warranties AS wa (organization_id, lead_id, lead_service_id, work_order_id, starts_on, expires_on, covered_items)
+		SELECT w.organization_id, w.lead_id, w.lead_service_id, w.id,
+			w.completed_at::date,
+			(w.completed_at::date + make_interval(months => t.duration_months))::date,
+			t.covered_items
+		FROM RAC_work_orders w
+		JOIN RAC_lead_services ls ON ls.id = w.lead_service_id
+		JOIN RAC_service_type_warranty_terms t ON t.service_type_id = ls.service_type_id AND t.organization_id = w.organization_id
+		WHERE w.id = $1 AND w.organization_id = $2 AND w.status = 'completed' AND w.completed_at IS NOT NULL
+		ON CONFLICT (work_order_id) DO NOTHING
+		RETURNING `+warrantyColumns, workOrderID, organizationID,
+	))
+	if errors.Is(err, ErrNotFound) {
+		return Warranty{}, false, nil
+	}
+	if err != nil {
+		return Warranty{}, false, fmt.Errorf("create warranty: %w", err)
+	}
+	return w, true, nil
+}
+
+// GetWarrantyByWorkOrder returns the warranty of a work order, or nil when it
+// has none.
+func (r *Repository) GetWarrantyByWorkOrder(ctx context.Context, workOrderID uuid.UUID) (*Warranty, error) {
+	w, err := scanWarranty(r.pool.QueryRow(ctx, `
+		SELECT `+warrantyColumns+`
+		FROM RAC_warranties wa
+		WHERE wa.work_order_id = $1`, workOrderID,
+	))
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get warranty: %w", err)
+	}
+	return &w, nil
+}
+
+// ListWarranties returns the warranties of an organization, optionally of one
+// lead service, by expiry date.
+func (r *Repository) ListWarranties(ctx context.Context, organizationID uuid.UUID, leadServiceID *uuid.UUID) ([]Warranty, error) {
+	return r.queryWarranties(ctx, `
+		SELECT `+warrantyColumns+`
+		FROM RAC_warranties wa
+		WHERE wa.organization_id = $1 AND ($2::uuid IS NULL OR wa.lead_service_id = $2)
+		ORDER BY wa.expires_on ASC`, organizationID, leadServiceID,
+	)
+}
+
+// ListExpiringWarranties returns warranties across organizations that expire
+// on or before the given date and were not announced yet.
+func (r *Repository) ListExpiringWarranties(ctx context.Context, expiresBefore time.Time, limit int) ([]Warranty, error) {
+	return r.queryWarranties(ctx, `
+		SELECT `+warrantyColumns+`
+		FROM RAC_warranties wa
+		WHERE wa.expiry_reminder_sent_at IS NULL
+			AND wa.expires_on <= $1::date
+			AND wa.expires_on >= CURRENT_DATE
+		ORDER BY wa.expires_on ASC
+		LIMIT $2`, expiresBefore, limit,
+	)
+}
+
+func (r *Repository) queryWarranties(ctx context.Context, query string, args ...any) ([]Warranty, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list warranties: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]Warranty, 0)
+	for rows.Next() {
+		w, err := scanWarranty(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan warranty: %w", err)
+		}
+		items = append(items, w)
+	}
+	return items, rows.Err()
+}
+
+// MarkExpiryReminderSent claims the expiry reminder of a warranty. It returns
+// false when another run already claimed it.
+func (r *Repository) MarkExpiryReminderSent(ctx context.Context, warrantyID uuid.UUID, sentAt time.Time) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE RAC_warranties
+		SET expiry_reminder_sent_at = $2
+		WHERE id = $1 AND expiry_reminder_sent_at IS NULL`, warrantyID, sentAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("mark warranty expiry reminder: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+const warrantyClaimColumns = `c.id, c.organization_id, c.warranty_id, wa.work_order_id, wa.lead_id, wa.lead_service_id,
+	c.description, c.status, c.triage_note, c.triaged_by, c.triaged_at, c.created_at, c.updated_at`
+
+func warrantyClaimFields(c *WarrantyClaim) []any {
+	return []any{&c.ID, &c.OrganizationID, &c.WarrantyID, &c.WorkOrderID, &c.LeadID, &c.LeadServiceID,
+		&c.Description, &c.Status, &c.TriageNote, &c.TriagedBy, &c.TriagedAt, &c.CreatedAt, &c.UpdatedAt}
+}
+
+func scanWarrantyClaim(row pgx.Row) (WarrantyClaim, error) {
+	var c WarrantyClaim
+	if err := row.Scan(warrantyClaimFields(&c)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return WarrantyClaim{}, ErrNotFound
+		}
+		return WarrantyClaim{}, err
+	}
+	return c, nil
+}
+
+func (r *Repository) CreateWarrantyClaim(ctx context.Context, w Warranty, description string) (WarrantyClaim, error) {
+	c, err := scanWarrantyClaim(r.pool.QueryRow(ctx, `
+		WITH inserted AS (
+			INSERT INTO RAC_warranty_claims (organization_id, warranty_id, description)
+			VALUES ($1, $2, $3)
+			RETURNING *
+		)
+		SELECT `+warrantyClaimColumns+`
+		FROM inserted c
+		JOIN RAC_warranties wa ON wa.id = c.warranty_id`, w.OrganizationID, w.ID, description,
+	))
+	if err != nil {
+		return WarrantyClaim{}, fmt.Errorf("create warranty claim: %w", err)
+	}
+	return c, nil
+}
+
+func (r *Repository) GetWarrantyClaim(ctx context.Context, id, organizationID uuid.UUID) (WarrantyClaim, error) {
+	return scanWarrantyClaim(r.pool.QueryRow(ctx, `
+		SELECT `+warrantyClaimColumns+`
+		FROM RAC_warranty_claims c
+		JOIN RAC_warranties wa ON wa.id = c.warranty_id
+		WHERE c.id = $1 AND c.organization_id = $2`, id, organizationID,
+	))
+}
+
+// ListWarrantyClaims returns the claims of an organization, newest first, and
+// the total number of matches.
+func (r *Repository) ListWarrantyClaims(ctx context.Context, p ClaimListParams) ([]WarrantyClaim, int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+warrantyClaimColumns+`, count(*) OVER ()
+		FROM RAC_warranty_claims c
+		JOIN RAC_warranties wa ON wa.id = c.warranty_id
+		WHERE c.organization_id = $1 AND ($2 = '' OR c.status = $2)
+		ORDER BY c.created_at DESC
+		LIMIT $3 OFFSET $4`, p.OrganizationID, p.Status, p.Limit, p.Offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list warranty claims: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WarrantyClaim, 0)
+	total := 0
+	for rows.Next() {
+		var c WarrantyClaim
+		if err := rows.Scan(append(warrantyClaimFields(&c), &total)...); err != nil {
+			return nil, 0, fmt.Errorf("scan warranty claim: %w", err)
+		}
+		items = append(items, c)
+	}
+	return items, total, rows.Err()
+}
+
+// TriageWarrantyClaim records the assessment of a claim. It returns
+// ErrNotFound when the claim changed status in the meantime.
+func (r *Repository) TriageWarrantyClaim(ctx context.Context, t ClaimTriage) (WarrantyClaim, error) {
+	return scanWarrantyClaim(r.pool.QueryRow(ctx, `
+		WITH updated AS (
+			UPDATE RAC_warranty_claims
+			SET status = $3, triage_note = COALESCE($4, triage_note), triaged_by = $5, triaged_at = now(), updated_at = now()
+			WHERE id = $1 AND organization_id = $2 AND status = $6
+			RETURNING *
+		)
+		SELECT `+warrantyClaimColumns+`
+		FROM updated c
+		JOIN RAC_warranties wa ON wa.id = c.warranty_id`, t.ID, t.OrganizationID, t.Status, t.Note, t.TriagedBy, t.FromStatus,
+	))
+}
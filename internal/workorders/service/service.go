@@ -0,0 +1,399 @@
+// Package service runs the lifecycle of work orders: created when a partner
+// accepts an offer, planned and progressed by the organization, and closed by
+// the customer confirming the completed work through a public link.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/auth/token"
+	"portal_final_backend/internal/events"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/workorders/repository"
+	"portal_final_backend/internal/workorders/transport"
+	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	dateFormat             = "2006-01-02"
+	confirmationTokenBytes = 32
+	defaultPageSize        = 25
+	timelineActorName      = "Werkorders"
+	msgWorkOrderNotFound   = "work order not found"
+)
+
+// transitions lists the statuses each status may move to. Completed is final;
+// the customer confirmation is recorded separately.
+var transitions = map[string][]string{
+	repository.StatusScheduled:  {repository.StatusInProgress, repository.StatusOnHold, repository.StatusCompleted},
+	repository.StatusInProgress: {repository.StatusOnHold, repository.StatusCompleted},
+	repository.StatusOnHold:     {repository.StatusScheduled, repository.StatusInProgress},
+}
+
+// statusTitles are the timeline titles of the status transitions.
+var statusTitles = map[string]string{
+	repository.StatusScheduled:  "Werkorder opnieuw ingepland",
+	repository.StatusInProgress: "Werkzaamheden gestart",
+	repository.StatusOnHold:     "Werkorder gepauzeerd",
+	repository.StatusCompleted:  "Werkzaamheden afgerond",
+}
+
+// statusLabels are the Dutch status names used in timeline summaries.
+var statusLabels = map[string]string{
+	repository.StatusScheduled:  "ingepland",
+	repository.StatusInProgress: "in uitvoering",
+	repository.StatusOnHold:     "gepauzeerd",
+	repository.StatusCompleted:  "afgerond",
+}
+
+type Repository interface {
+	CreateForAcceptedOffer(ctx context.Context, organizationID, leadID, leadServiceID, offerID, partnerID uuid.UUID) (repository.WorkOrder, bool, error)
+	Get(ctx context.Context, id, organizationID uuid.UUID) (repository.WorkOrder, error)
+	List(ctx context.Context, p repository.ListParams) ([]repository.WorkOrder, int, error)
+	UpdatePlanning(ctx context.Context, id, organizationID uuid.UUID, start, end *time.Time) (repository.WorkOrder, error)
+	UpdateStatus(ctx context.Context, u repository.StatusUpdate) (repository.WorkOrder, error)
+	GetByConfirmationToken(ctx context.Context, token string) (repository.PublicWorkOrder, error)
+	ConfirmByCustomer(ctx context.Context, token string, feedback *string) (repository.WorkOrder, error)
+
+	ListWarrantyTerms(ctx context.Context, organizationID uuid.UUID) ([]repository.WarrantyTerms, error)
+	UpsertWarrantyTerms(ctx context.Context, t repository.WarrantyTerms) (repository.WarrantyTerms, error)
+	DeleteWarrantyTerms(ctx context.Context, serviceTypeID, organizationID uuid.UUID) error
+	CreateWarrantyForWorkOrder(ctx context.Context, workOrderID, organizationID uuid.UUID) (repository.Warranty, bool, error)
+	GetWarrantyByWorkOrder(ctx context.Context, workOrderID uuid.UUID) (*repository.Warranty, error)
+	ListWarranties(ctx context.Context, organizationID uuid.UUID, leadServiceID *uuid.UUID) ([]repository.Warranty, error)
+	ListExpiringWarranties(ctx context.Context, expiresBefore time.Time, limit int) ([]repository.Warranty, error)
+	MarkExpiryReminderSent(ctx context.Context, warrantyID uuid.UUID, sentAt time.Time) (bool, error)
+	CreateWarrantyClaim(ctx context.Context, w repository.Warranty, description string) (repository.WarrantyClaim, error)
+	GetWarrantyClaim(ctx context.Context, id, organizationID uuid.UUID) (repository.WarrantyClaim, error)
+	ListWarrantyClaims(ctx context.Context, p repository.ClaimListParams) ([]repository.WarrantyClaim, int, error)
+	TriageWarrantyClaim(ctx context.Context, t repository.ClaimTriage) (repository.WarrantyClaim, error)
+}
+
+type Service struct {
+	repo     Repository
+	timeline leadsrepo.TimelineEventStore
+	eventBus events.Bus
+	log      *logger.Logger
+}
+
+func New(repo Repository, timeline leadsrepo.TimelineEventStore, eventBus events.Bus, log *logger.Logger) *Service {
+	return &Service{repo: repo, timeline: timeline, eventBus: eventBus, log: log}
+}
+
+// CanTransition reports whether a work order may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateForAcceptedOffer opens the work order of an accepted partner offer.
+// Replayed acceptance events leave the existing work order untouched.
+func (s *Service) CreateForAcceptedOffer(ctx context.Context, e events.PartnerOfferAccepted) error {
+	w, created, err := s.repo.CreateForAcceptedOffer(ctx, e.OrganizationID, e.LeadID, e.LeadServiceID, e.OfferID, e.PartnerID)
+	if err != nil || !created {
+		return err
+	}
+	s.recordTimeline(ctx, w, leadsrepo.ActorTypeSystem, "Werkorder aangemaakt",
+		fmt.Sprintf("%s heeft het werkaanbod geaccepteerd; de werkorder staat klaar om in te plannen", e.PartnerName), nil)
+	return nil
+}
+
+func (s *Service) List(ctx context.Context, organizationID uuid.UUID, req transport.ListWorkOrdersRequest) (transport.WorkOrderListResponse, error) {
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	params := repository.ListParams{
+		OrganizationID: organizationID,
+		Status:         req.Status,
+		Limit:          pageSize,
+		Offset:         (page - 1) * pageSize,
+	}
+	if req.LeadServiceID != "" {
+		id, err := uuid.Parse(req.LeadServiceID)
+		if err != nil {
+			return transport.WorkOrderListResponse{}, apperr.Validation("invalid leadServiceId")
+		}
+		params.LeadServiceID = &id
+	}
+
+	items, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return transport.WorkOrderListResponse{}, err
+	}
+	resp := transport.WorkOrderListResponse{Items: make([]transport.WorkOrderResponse, len(items)), Total: total, Page: page, PageSize: pageSize}
+	for i, w := range items {
+		resp.Items[i] = toResponse(w)
+	}
+	return resp, nil
+}
+
+func (s *Service) Get(ctx context.Context, organizationID, id uuid.UUID) (transport.WorkOrderResponse, error) {
+	w, err := s.repo.Get(ctx, id, organizationID)
+	if err != nil {
+		return transport.WorkOrderResponse{}, mapNotFound(err)
+	}
+	return toResponse(w), nil
+}
+
+// UpdatePlanning sets the planned dates of a work order.
+func (s *Service) UpdatePlanning(ctx context.Context, organizationID, id uuid.UUID, req transport.UpdatePlanningRequest) (transport.WorkOrderResponse, error) {
+	start, err := parseDate(req.PlannedStartDate)
+	if err != nil {
+		return transport.WorkOrderResponse{}, err
+	}
+	end, err := parseDate(req.PlannedEndDate)
+	if err != nil {
+		return transport.WorkOrderResponse{}, err
+	}
+	if start != nil && end != nil && end.Before(*start) {
+		return transport.WorkOrderResponse{}, apperr.Validation("plannedEndDate must not be before plannedStartDate")
+	}
+
+	w, err := s.repo.UpdatePlanning(ctx, id, organizationID, start, end)
+	if errors.Is(err, repository.ErrNotFound) {
+		if _, getErr := s.repo.Get(ctx, id, organizationID); getErr == nil {
+			return transport.WorkOrderResponse{}, apperr.Conflict("completed work orders cannot be replanned")
+		}
+		return transport.WorkOrderResponse{}, apperr.NotFound(msgWorkOrderNotFound)
+	}
+	if err != nil {
+		return transport.WorkOrderResponse{}, err
+	}
+
+	s.recordTimeline(ctx, w, leadsrepo.ActorTypeUser, "Werkorder ingepland", planningSummary(start, end), nil)
+	return toResponse(w), nil
+}
+
+// UpdateStatus moves a work order to another status. Completing it issues the
+// confirmation link for the customer.
+func (s *Service) UpdateStatus(ctx context.Context, organizationID, id uuid.UUID, req transport.UpdateStatusRequest) (transport.WorkOrderResponse, error) {
+	current, err := s.repo.Get(ctx, id, organizationID)
+	if err != nil {
+		return transport.WorkOrderResponse{}, mapNotFound(err)
+	}
+	if current.Status == req.Status {
+		return toResponse(current), nil
+	}
+	if !CanTransition(current.Status, req.Status) {
+		return transport.WorkOrderResponse{}, apperr.Conflict(fmt.Sprintf("work order cannot move from %s to %s", current.Status, req.Status))
+	}
+
+	update := repository.StatusUpdate{ID: id, OrganizationID: organizationID, FromStatus: current.Status, ToStatus: req.Status}
+	if req.Status == repository.StatusOnHold {
+		reason := strings.TrimSpace(derefString(req.HoldReason))
+		if reason == "" {
+			return transport.WorkOrderResponse{}, apperr.Validation("holdReason is required to put a work order on hold")
+		}
+		update.HoldReason = &reason
+	}
+	if req.Status == repository.StatusCompleted {
+		confirmationToken, err := token.GenerateRandomToken(confirmationTokenBytes)
+		if err != nil {
+			return transport.WorkOrderResponse{}, fmt.Errorf("generate confirmation token: %w", err)
+		}
+		update.ConfirmationToken = &confirmationToken
+	}
+
+	w, err := s.repo.UpdateStatus(ctx, update)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.WorkOrderResponse{}, apperr.Conflict("work order was changed in the meantime")
+	}
+	if err != nil {
+		return transport.WorkOrderResponse{}, err
+	}
+
+	summary := fmt.Sprintf("Status gewijzigd van %s naar %s", statusLabels[current.Status], statusLabels[w.Status])
+	if w.HoldReason != nil {
+		summary += ": " + *w.HoldReason
+	}
+	s.recordTimeline(ctx, w, leadsrepo.ActorTypeUser, statusTitles[w.Status], summary, map[string]any{
+		"oldStatus": current.Status,
+		"newStatus": w.Status,
+	})
+
+	if w.Status == repository.StatusCompleted && s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.WorkOrderCompleted{
+			BaseEvent:         events.NewBaseEvent(),
+			WorkOrderID:       w.ID,
+			OrganizationID:    w.OrganizationID,
+			LeadID:            w.LeadID,
+			LeadServiceID:     w.LeadServiceID,
+			PartnerID:         w.PartnerID,
+			ConfirmationToken: derefString(w.ConfirmationToken),
+		})
+	}
+	if w.Status == repository.StatusCompleted {
+		s.issueWarranty(ctx, w)
+	}
+	return toResponse(w), nil
+}
+
+// GetPublic returns the work order behind a confirmation link.
+func (s *Service) GetPublic(ctx context.Context, confirmationToken string) (transport.PublicWorkOrderResponse, error) {
+	w, err := s.repo.GetByConfirmationToken(ctx, confirmationToken)
+	if err != nil {
+		return transport.PublicWorkOrderResponse{}, mapNotFound(err)
+	}
+	warranty, err := s.repo.GetWarrantyByWorkOrder(ctx, w.ID)
+	if err != nil {
+		return transport.PublicWorkOrderResponse{}, err
+	}
+	return transport.PublicWorkOrderResponse{
+		OrganizationName:    w.OrganizationName,
+		ServiceType:         w.ServiceType,
+		PartnerName:         w.PartnerName,
+		Status:              w.Status,
+		CompletedAt:         w.CompletedAt,
+		CustomerConfirmedAt: w.CustomerConfirmedAt,
+		Warranty:            toPublicWarrantyResponse(warranty, time.Now()),
+	}, nil
+}
+
+// ConfirmPublic records that the customer confirmed the completed work.
+func (s *Service) ConfirmPublic(ctx context.Context, confirmationToken string, req transport.ConfirmWorkOrderRequest) (transport.PublicWorkOrderResponse, error) {
+	var feedback *string
+	if text := strings.TrimSpace(derefString(req.Feedback)); text != "" {
+		feedback = &text
+	}
+
+	w, err := s.repo.ConfirmByCustomer(ctx, confirmationToken, feedback)
+	if errors.Is(err, repository.ErrNotFound) {
+		if _, getErr := s.repo.GetByConfirmationToken(ctx, confirmationToken); getErr == nil {
+			return transport.PublicWorkOrderResponse{}, apperr.Conflict("work order is already confirmed")
+		}
+		return transport.PublicWorkOrderResponse{}, apperr.NotFound(msgWorkOrderNotFound)
+	}
+	if err != nil {
+		return transport.PublicWorkOrderResponse{}, err
+	}
+
+	summary := "De klant heeft bevestigd dat de werkzaamheden naar wens zijn afgerond"
+	if feedback != nil {
+		summary += ": " + *feedback
+	}
+	s.recordTimeline(ctx, w, leadsrepo.ActorTypeLead, "Oplevering bevestigd door klant", summary, nil)
+
+	if s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.WorkOrderConfirmed{
+			BaseEvent:      events.NewBaseEvent(),
+			WorkOrderID:    w.ID,
+			OrganizationID: w.OrganizationID,
+			LeadID:         w.LeadID,
+			LeadServiceID:  w.LeadServiceID,
+			QuoteID:        w.QuoteID,
+			Feedback:       derefString(feedback),
+		})
+	}
+	return s.GetPublic(ctx, confirmationToken)
+}
+
+func (s *Service) recordTimeline(ctx context.Context, w repository.WorkOrder, actorType, title, summary string, metadata map[string]any) {
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["status"] = w.Status
+	s.recordLeadTimeline(ctx, w.OrganizationID, w.LeadID, w.LeadServiceID, w.ID, actorType, title, summary, metadata)
+}
+
+func (s *Service) recordLeadTimeline(ctx context.Context, organizationID, leadID, serviceID, workOrderID uuid.UUID, actorType, title, summary string, metadata map[string]any) {
+	if s.timeline == nil {
+		return
+	}
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["workOrderId"] = workOrderID.String()
+	if _, err := s.timeline.CreateTimelineEvent(ctx, leadsrepo.CreateTimelineEventParams{
+		LeadID:         leadID,
+		ServiceID:      &serviceID,
+		OrganizationID: organizationID,
+		ActorType:      actorType,
+		ActorName:      timelineActorName,
+		EventType:      leadsrepo.EventTypeWorkOrder,
+		Title:          title,
+		Summary:        leadsrepo.TruncateSummary(summary, leadsrepo.TimelineSummaryMaxLen),
+		Metadata:       metadata,
+	}); err != nil && s.log != nil {
+		s.log.Warn("failed to record work order timeline event", "workOrderId", workOrderID, "error", err)
+	}
+}
+
+func toResponse(w repository.WorkOrder) transport.WorkOrderResponse {
+	return transport.WorkOrderResponse{
+		ID:                  w.ID,
+		LeadID:              w.LeadID,
+		LeadServiceID:       w.LeadServiceID,
+		PartnerOfferID:      w.PartnerOfferID,
+		PartnerID:           w.PartnerID,
+		QuoteID:             w.QuoteID,
+		Status:              w.Status,
+		PlannedStartDate:    formatDate(w.PlannedStartDate),
+		PlannedEndDate:      formatDate(w.PlannedEndDate),
+		HoldReason:          w.HoldReason,
+		StartedAt:           w.StartedAt,
+		CompletedAt:         w.CompletedAt,
+		CustomerConfirmedAt: w.CustomerConfirmedAt,
+		CustomerFeedback:    w.CustomerFeedback,
+		CreatedAt:           w.CreatedAt,
+		UpdatedAt:           w.UpdatedAt,
+	}
+}
+
+func planningSummary(start, end *time.Time) string {
+	switch {
+	case start == nil && end == nil:
+		return "Planning verwijderd"
+	case end == nil || start != nil && start.Equal(*end):
+		return "Gepland op " + start.Format("02-01-2006")
+	case start == nil:
+		return "Gepland tot " + end.Format("02-01-2006")
+	default:
+		return fmt.Sprintf("Gepland van %s tot %s", start.Format("02-01-2006"), end.Format("02-01-2006"))
+	}
+}
+
+func parseDate(value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(dateFormat, *value)
+	if err != nil {
+		return nil, apperr.Validation("dates must be formatted as YYYY-MM-DD")
+	}
+	return &parsed, nil
+}
+
+func formatDate(value *time.Time) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := value.Format(dateFormat)
+	return &formatted
+}
+
+func mapNotFound(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.NotFound(msgWorkOrderNotFound)
+	}
+	return err
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
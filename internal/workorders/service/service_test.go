@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"portal_final_backend/internal/workorders/repository"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{repository.StatusScheduled, repository.StatusInProgress, true},
+		{repository.StatusScheduled, repository.StatusCompleted, true},
+		{repository.StatusInProgress, repository.StatusOnHold, true},
+		{repository.StatusOnHold, repository.StatusInProgress, true},
+		{repository.StatusOnHold, repository.StatusCompleted, false},
+		{repository.StatusInProgress, repository.StatusScheduled, false},
+		{repository.StatusCompleted, repository.StatusInProgress, false},
+		{repository.StatusScheduled, repository.StatusScheduled, false},
+	}
+
+	for _, tc := range cases {
+		if got := CanTransition(tc.from, tc.to); got != tc.want {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestCanTransitionClaim(t *testing.T) {
+	if !CanTransitionClaim(repository.ClaimStatusOpen, repository.ClaimStatusRejected) {
+		t.Error("open claims can be rejected")
+	}
+	if !CanTransitionClaim(repository.ClaimStatusAccepted, repository.ClaimStatusResolved) {
+		t.Error("accepted claims can be resolved")
+	}
+	if CanTransitionClaim(repository.ClaimStatusOpen, repository.ClaimStatusResolved) {
+		t.Error("open claims must be accepted before they are resolved")
+	}
+	if CanTransitionClaim(repository.ClaimStatusRejected, repository.ClaimStatusInReview) {
+		t.Error("rejected claims are closed")
+	}
+}
+
+func TestWarrantyActive(t *testing.T) {
+	w := repository.Warranty{
+		StartsOn:  time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresOn: time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	cases := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2025, 2, 28, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2027, 3, 1, 23, 0, 0, 0, time.UTC), true},
+		{time.Date(2027, 3, 2, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tc := range cases {
+		if got := warrantyActive(w, tc.now); got != tc.want {
+			t.Errorf("warrantyActive at %s = %v, want %v", tc.now, got, tc.want)
+		}
+	}
+}
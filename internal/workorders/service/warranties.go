@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"portal_final_backend/internal/events"
+	leadsrepo "portal_final_backend/internal/leads/repository"
+	"portal_final_backend/internal/workorders/repository"
+	"portal_final_backend/internal/workorders/transport"
+	"portal_final_backend/platform/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// warrantyReminderLeadTime is how long before the expiry date the customer
+	// is offered maintenance.
+	warrantyReminderLeadTime   = 60 * 24 * time.Hour
+	expiringWarrantiesBatch    = 200
+	msgWarrantyClaimNotFound   = "warranty claim not found"
+	msgServiceTypeNotFound     = "service type not found"
+	warrantyTimelineDateFormat = "02-01-2006"
+)
+
+// claimTransitions lists the statuses each claim status may move to. Rejected
+// and resolved claims are closed.
+var claimTransitions = map[string][]string{
+	repository.ClaimStatusOpen:     {repository.ClaimStatusInReview, repository.ClaimStatusAccepted, repository.ClaimStatusRejected},
+	repository.ClaimStatusInReview: {repository.ClaimStatusAccepted, repository.ClaimStatusRejected},
+	repository.ClaimStatusAccepted: {repository.ClaimStatusResolved},
+}
+
+// claimStatusTitles are the timeline titles of the claim triage outcomes.
+var claimStatusTitles = map[string]string{
+	repository.ClaimStatusInReview: "Garantieclaim in behandeling",
+	repository.ClaimStatusAccepted: "Garantieclaim toegekend",
+	repository.ClaimStatusRejected: "Garantieclaim afgewezen",
+	repository.ClaimStatusResolved: "Garantieclaim afgehandeld",
+}
+
+// CanTransitionClaim reports whether a warranty claim may move from one status
+// to another.
+func CanTransitionClaim(from, to string) bool {
+	for _, next := range claimTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) ListWarrantyTerms(ctx context.Context, organizationID uuid.UUID) ([]transport.WarrantyTermsResponse, error) {
+	terms, err := s.repo.ListWarrantyTerms(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]transport.WarrantyTermsResponse, len(terms))
+	for i, t := range terms {
+		resp[i] = toWarrantyTermsResponse(t)
+	}
+	return resp, nil
+}
+
+// UpsertWarrantyTerms sets the warranty of a service type. It applies to work
+// orders completed afterwards.
+func (s *Service) UpsertWarrantyTerms(ctx context.Context, organizationID, serviceTypeID uuid.UUID, req transport.UpsertWarrantyTermsRequest) (transport.WarrantyTermsResponse, error) {
+	items := make([]string, 0, len(req.CoveredItems))
+	for _, item := range req.CoveredItems {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	t, err := s.repo.UpsertWarrantyTerms(ctx, repository.WarrantyTerms{
+		ServiceTypeID:  serviceTypeID,
+		OrganizationID: organizationID,
+		DurationMonths: req.DurationMonths,
+		CoveredItems:   items,
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.WarrantyTermsResponse{}, apperr.NotFound(msgServiceTypeNotFound)
+	}
+	if err != nil {
+		return transport.WarrantyTermsResponse{}, err
+	}
+	return toWarrantyTermsResponse(t), nil
+}
+
+func (s *Service) DeleteWarrantyTerms(ctx context.Context, organizationID, serviceTypeID uuid.UUID) error {
+	if err := s.repo.DeleteWarrantyTerms(ctx, serviceTypeID, organizationID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.NotFound("warranty terms not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Service) ListWarranties(ctx context.Context, organizationID uuid.UUID, req transport.ListWarrantiesRequest) ([]transport.WarrantyResponse, error) {
+	var leadServiceID *uuid.UUID
+	if req.LeadServiceID != "" {
+		id, err := uuid.Parse(req.LeadServiceID)
+		if err != nil {
+			return nil, apperr.Validation("invalid leadServiceId")
+		}
+		leadServiceID = &id
+	}
+
+	warranties, err := s.repo.ListWarranties(ctx, organizationID, leadServiceID)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]transport.WarrantyResponse, len(warranties))
+	for i, w := range warranties {
+		resp[i] = toWarrantyResponse(w)
+	}
+	return resp, nil
+}
+
+// issueWarranty gives a completed work order the warranty of its service type.
+// A failure is logged; it does not undo the completion.
+func (s *Service) issueWarranty(ctx context.Context, w repository.WorkOrder) {
+	warranty, created, err := s.repo.CreateWarrantyForWorkOrder(ctx, w.ID, w.OrganizationID)
+	if err != nil {
+		if s.log != nil {
+			s.log.Error("failed to create warranty for completed work order", "workOrderId", w.ID, "error", err)
+		}
+		return
+	}
+	if !created {
+		return
+	}
+
+	summary := "Garantie geldig tot " + warranty.ExpiresOn.Format(warrantyTimelineDateFormat)
+	if len(warranty.CoveredItems) > 0 {
+		summary += " op " + strings.Join(warranty.CoveredItems, ", ")
+	}
+	s.recordLeadTimeline(ctx, w.OrganizationID, w.LeadID, w.LeadServiceID, w.ID, leadsrepo.ActorTypeSystem, "Garantie vastgelegd", summary, map[string]any{
+		"warrantyId": warranty.ID.String(),
+		"expiresOn":  warranty.ExpiresOn.Format(dateFormat),
+	})
+}
+
+// SubmitWarrantyClaim records a claim of the customer against the warranty of
+// the work order behind a confirmation link.
+func (s *Service) SubmitWarrantyClaim(ctx context.Context, confirmationToken string, req transport.SubmitWarrantyClaimRequest) (transport.PublicWarrantyClaimResponse, error) {
+	w, err := s.repo.GetByConfirmationToken(ctx, confirmationToken)
+	if err != nil {
+		return transport.PublicWarrantyClaimResponse{}, mapNotFound(err)
+	}
+	warranty, err := s.repo.GetWarrantyByWorkOrder(ctx, w.ID)
+	if err != nil {
+		return transport.PublicWarrantyClaimResponse{}, err
+	}
+	if warranty == nil {
+		return transport.PublicWarrantyClaimResponse{}, apperr.NotFound("this work order has no warranty")
+	}
+	if !warrantyActive(*warranty, time.Now()) {
+		return transport.PublicWarrantyClaimResponse{}, apperr.Gone("the warranty has expired")
+	}
+
+	description := strings.TrimSpace(req.Description)
+	claim, err := s.repo.CreateWarrantyClaim(ctx, *warranty, description)
+	if err != nil {
+		return transport.PublicWarrantyClaimResponse{}, err
+	}
+
+	s.recordLeadTimeline(ctx, claim.OrganizationID, claim.LeadID, claim.LeadServiceID, claim.WorkOrderID, leadsrepo.ActorTypeLead,
+		"Garantieclaim ingediend", description, map[string]any{"claimId": claim.ID.String()})
+
+	if s.eventBus != nil {
+		s.eventBus.Publish(ctx, events.WarrantyClaimSubmitted{
+			BaseEvent:      events.NewBaseEvent(),
+			ClaimID:        claim.ID,
+			WarrantyID:     claim.WarrantyID,
+			WorkOrderID:    claim.WorkOrderID,
+			OrganizationID: claim.OrganizationID,
+			LeadID:         claim.LeadID,
+			LeadServiceID:  claim.LeadServiceID,
+		})
+	}
+	return transport.PublicWarrantyClaimResponse{ID: claim.ID, Status: claim.Status, CreatedAt: claim.CreatedAt}, nil
+}
+
+func (s *Service) ListWarrantyClaims(ctx context.Context, organizationID uuid.UUID, req transport.ListWarrantyClaimsRequest) (transport.WarrantyClaimListResponse, error) {
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	claims, total, err := s.repo.ListWarrantyClaims(ctx, repository.ClaimListParams{
+		OrganizationID: organizationID,
+		Status:         req.Status,
+		Limit:          pageSize,
+		Offset:         (page - 1) * pageSize,
+	})
+	if err != nil {
+		return transport.WarrantyClaimListResponse{}, err
+	}
+	resp := transport.WarrantyClaimListResponse{Items: make([]transport.WarrantyClaimResponse, len(claims)), Total: total, Page: page, PageSize: pageSize}
+	for i, c := range claims {
+		resp.Items[i] = toWarrantyClaimResponse(c)
+	}
+	return resp, nil
+}
+
+// TriageWarrantyClaim records the assessment of a claim by an agent.
+func (s *Service) TriageWarrantyClaim(ctx context.Context, organizationID, userID, claimID uuid.UUID, req transport.TriageWarrantyClaimRequest) (transport.WarrantyClaimResponse, error) {
+	current, err := s.repo.GetWarrantyClaim(ctx, claimID, organizationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.WarrantyClaimResponse{}, apperr.NotFound(msgWarrantyClaimNotFound)
+	}
+	if err != nil {
+		return transport.WarrantyClaimResponse{}, err
+	}
+	if !CanTransitionClaim(current.Status, req.Status) {
+		return transport.WarrantyClaimResponse{}, apperr.Conflict(fmt.Sprintf("warranty claim cannot move from %s to %s", current.Status, req.Status))
+	}
+
+	var note *string
+	if text := strings.TrimSpace(derefString(req.Note)); text != "" {
+		note = &text
+	}
+	if req.Status == repository.ClaimStatusRejected && note == nil && current.TriageNote == nil {
+		return transport.WarrantyClaimResponse{}, apperr.Validation("note is required to reject a warranty claim")
+	}
+
+	claim, err := s.repo.TriageWarrantyClaim(ctx, repository.ClaimTriage{
+		ID:             claimID,
+		OrganizationID: organizationID,
+		FromStatus:     current.Status,
+		Status:         req.Status,
+		Note:           note,
+		TriagedBy:      userID,
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return transport.WarrantyClaimResponse{}, apperr.Conflict("warranty claim was changed in the meantime")
+	}
+	if err != nil {
+		return transport.WarrantyClaimResponse{}, err
+	}
+
+	s.recordLeadTimeline(ctx, claim.OrganizationID, claim.LeadID, claim.LeadServiceID, claim.WorkOrderID, leadsrepo.ActorTypeUser,
+		claimStatusTitles[claim.Status], derefString(note), map[string]any{
+			"claimId":   claim.ID.String(),
+			"oldStatus": current.Status,
+			"newStatus": claim.Status,
+		})
+	return toWarrantyClaimResponse(claim), nil
+}
+
+// PublishExpiringWarranties announces warranties that expire within the
+// reminder lead time, once per warranty, so the notification workflows can
+// offer maintenance to the customer.
+func (s *Service) PublishExpiringWarranties(ctx context.Context, now time.Time) (int, error) {
+	warranties, err := s.repo.ListExpiringWarranties(ctx, now.Add(warrantyReminderLeadTime), expiringWarrantiesBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, w := range warranties {
+		claimed, err := s.repo.MarkExpiryReminderSent(ctx, w.ID, now)
+		if err != nil {
+			return published, err
+		}
+		if !claimed {
+			continue
+		}
+
+		s.recordLeadTimeline(ctx, w.OrganizationID, w.LeadID, w.LeadServiceID, w.WorkOrderID, leadsrepo.ActorTypeSystem,
+			"Garantie verloopt binnenkort", "Garantie verloopt op "+w.ExpiresOn.Format(warrantyTimelineDateFormat),
+			map[string]any{"warrantyId": w.ID.String()})
+
+		if s.eventBus != nil {
+			s.eventBus.Publish(ctx, events.WarrantyExpiring{
+				BaseEvent:      events.NewBaseEvent(),
+				WarrantyID:     w.ID,
+				WorkOrderID:    w.WorkOrderID,
+				OrganizationID: w.OrganizationID,
+				LeadID:         w.LeadID,
+				LeadServiceID:  w.LeadServiceID,
+				ExpiresOn:      w.ExpiresOn,
+				CoveredItems:   w.CoveredItems,
+			})
+		}
+		published++
+	}
+	return published, nil
+}
+
+// warrantyActive reports whether the warranty still covers claims on the given
+// day; the expiry date itself is included.
+func warrantyActive(w repository.Warranty, now time.Time) bool {
+	today := now.Format(dateFormat)
+	return today >= w.StartsOn.Format(dateFormat) && today <= w.ExpiresOn.Format(dateFormat)
+}
+
+func toWarrantyTermsResponse(t repository.WarrantyTerms) transport.WarrantyTermsResponse {
+	return transport.WarrantyTermsResponse{
+		ServiceTypeID:   t.ServiceTypeID,
+		ServiceTypeName: t.ServiceTypeName,
+		DurationMonths:  t.DurationMonths,
+		CoveredItems:    t.CoveredItems,
+		UpdatedAt:       t.UpdatedAt,
+	}
+}
+
+func toWarrantyResponse(w repository.Warranty) transport.WarrantyResponse {
+	return transport.WarrantyResponse{
+		ID:                   w.ID,
+		LeadID:               w.LeadID,
+		LeadServiceID:        w.LeadServiceID,
+		WorkOrderID:          w.WorkOrderID,
+		StartsOn:             w.StartsOn.Format(dateFormat),
+		ExpiresOn:            w.ExpiresOn.Format(dateFormat),
+		CoveredItems:         w.CoveredItems,
+		ExpiryReminderSentAt: w.ExpiryReminderSentAt,
+		CreatedAt:            w.CreatedAt,
+	}
+}
+
+func toPublicWarrantyResponse(w *repository.Warranty, now time.Time) *transport.PublicWarrantyResponse {
+	if w == nil {
+		return nil
+	}
+	return &transport.PublicWarrantyResponse{
+		StartsOn:     w.StartsOn.Format(dateFormat),
+		ExpiresOn:    w.ExpiresOn.Format(dateFormat),
+		CoveredItems: w.CoveredItems,
+		Active:       warrantyActive(*w, now),
+	}
+}
+
+func toWarrantyClaimResponse(c repository.WarrantyClaim) transport.WarrantyClaimResponse {
+	return transport.WarrantyClaimResponse{
+		ID:            c.ID,
+		WarrantyID:    c.WarrantyID,
+		WorkOrderID:   c.WorkOrderID,
+		LeadID:        c.LeadID,
+		LeadServiceID: c.LeadServiceID,
+		Description:   c.Description,
+		Status:        c.Status,
+		TriageNote:    c.TriageNote,
+		TriagedBy:     c.TriagedBy,
+		TriagedAt:     c.TriagedAt,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
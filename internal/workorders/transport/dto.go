@@ -0,0 +1,150 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListWorkOrdersRequest struct {
+	Status        string `form:"status" validate:"omitempty,oneof=scheduled in_progress on_hold completed"`
+	LeadServiceID string `form:"leadServiceId" validate:"omitempty,uuid"`
+	Page          int    `form:"page" validate:"omitempty,min=1"`
+	PageSize      int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+// UpdatePlanningRequest sets the planned dates, formatted as YYYY-MM-DD. An
+// omitted date clears it.
+type UpdatePlanningRequest struct {
+	PlannedStartDate *string `json:"plannedStartDate" validate:"omitempty,datetime=2006-01-02"`
+	PlannedEndDate   *string `json:"plannedEndDate" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type UpdateStatusRequest struct {
+	Status     string  `json:"status" validate:"required,oneof=scheduled in_progress on_hold completed"`
+	HoldReason *string `json:"holdReason" validate:"omitempty,max=1000"`
+}
+
+type ConfirmWorkOrderRequest struct {
+	Feedback *string `json:"feedback" validate:"omitempty,max=2000"`
+}
+
+type WorkOrderResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	LeadID              uuid.UUID  `json:"leadId"`
+	LeadServiceID       uuid.UUID  `json:"leadServiceId"`
+	PartnerOfferID      *uuid.UUID `json:"partnerOfferId,omitempty"`
+	PartnerID           *uuid.UUID `json:"partnerId,omitempty"`
+	QuoteID             *uuid.UUID `json:"quoteId,omitempty"`
+	Status              string     `json:"status"`
+	PlannedStartDate    *string    `json:"plannedStartDate,omitempty"`
+	PlannedEndDate      *string    `json:"plannedEndDate,omitempty"`
+	HoldReason          *string    `json:"holdReason,omitempty"`
+	StartedAt           *time.Time `json:"startedAt,omitempty"`
+	CompletedAt         *time.Time `json:"completedAt,omitempty"`
+	CustomerConfirmedAt *time.Time `json:"customerConfirmedAt,omitempty"`
+	CustomerFeedback    *string    `json:"customerFeedback,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+}
+
+type WorkOrderListResponse struct {
+	Items    []WorkOrderResponse `json:"items"`
+	Total    int                 `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+}
+
+// PublicWorkOrderResponse is what the customer sees on the confirmation page.
+type PublicWorkOrderResponse struct {
+	OrganizationName    string                  `json:"organizationName"`
+	ServiceType         string                  `json:"serviceType"`
+	PartnerName         *string                 `json:"partnerName,omitempty"`
+	Status              string                  `json:"status"`
+	CompletedAt         *time.Time              `json:"completedAt,omitempty"`
+	CustomerConfirmedAt *time.Time              `json:"customerConfirmedAt,omitempty"`
+	Warranty            *PublicWarrantyResponse `json:"warranty,omitempty"`
+}
+
+// UpsertWarrantyTermsRequest sets the warranty given on completed work of a
+// service type.
+type UpsertWarrantyTermsRequest struct {
+	DurationMonths int      `json:"durationMonths" validate:"required,min=1,max=600"`
+	CoveredItems   []string `json:"coveredItems" validate:"omitempty,max=50,dive,required,max=200"`
+}
+
+type WarrantyTermsResponse struct {
+	ServiceTypeID   uuid.UUID `json:"serviceTypeId"`
+	ServiceTypeName string    `json:"serviceTypeName"`
+	DurationMonths  int       `json:"durationMonths"`
+	CoveredItems    []string  `json:"coveredItems"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+type ListWarrantiesRequest struct {
+	LeadServiceID string `form:"leadServiceId" validate:"omitempty,uuid"`
+}
+
+type WarrantyResponse struct {
+	ID                   uuid.UUID  `json:"id"`
+	LeadID               uuid.UUID  `json:"leadId"`
+	LeadServiceID        uuid.UUID  `json:"leadServiceId"`
+	WorkOrderID          uuid.UUID  `json:"workOrderId"`
+	StartsOn             string     `json:"startsOn"`
+	ExpiresOn            string     `json:"expiresOn"`
+	CoveredItems         []string   `json:"coveredItems"`
+	ExpiryReminderSentAt *time.Time `json:"expiryReminderSentAt,omitempty"`
+	CreatedAt            time.Time  `json:"createdAt"`
+}
+
+// PublicWarrantyResponse is the warranty shown to the customer.
+type PublicWarrantyResponse struct {
+	StartsOn     string   `json:"startsOn"`
+	ExpiresOn    string   `json:"expiresOn"`
+	CoveredItems []string `json:"coveredItems"`
+	Active       bool     `json:"active"`
+}
+
+type SubmitWarrantyClaimRequest struct {
+	Description string `json:"description" validate:"required,min=10,max=4000"`
+}
+
+type PublicWarrantyClaimResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ListWarrantyClaimsRequest struct {
+	Status   string `form:"status" validate:"omitempty,oneof=open in_review accepted rejected resolved"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+// TriageWarrantyClaimRequest records the assessment of a claim by an agent.
+type TriageWarrantyClaimRequest struct {
+	Status string  `json:"status" validate:"required,oneof=in_review accepted rejected resolved"`
+	Note   *string `json:"note" validate:"omitempty,max=2000"`
+}
+
+type WarrantyClaimResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	WarrantyID    uuid.UUID  `json:"warrantyId"`
+	WorkOrderID   uuid.UUID  `json:"workOrderId"`
+	LeadID        uuid.UUID  `json:"leadId"`
+	LeadServiceID uuid.UUID  `json:"leadServiceId"`
+	Description   string     `json:"description"`
+	Status        string     `json:"status"`
+	TriageNote    *string    `json:"triageNote,omitempty"`
+	TriagedBy     *uuid.UUID `json:"triagedBy,omitempty"`
+	TriagedAt     *time.Time `json:"triagedAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+type WarrantyClaimListResponse struct {
+	Items    []WarrantyClaimResponse `json:"items"`
+	Total    int                     `json:"total"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"pageSize"`
+}
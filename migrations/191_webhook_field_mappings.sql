@@ -0,0 +1,23 @@
+-- +goose Up
+-- Per-source field mapping configuration for webhook form submissions.
+-- A mapping is attached to a webhook API key so each external form provider
+-- can be onboarded without custom extraction code.
+CREATE TABLE IF NOT EXISTS RAC_webhook_field_mappings (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id    UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    api_key_id         UUID NOT NULL REFERENCES RAC_webhook_api_keys(id) ON DELETE CASCADE,
+    field_mappings     JSONB NOT NULL DEFAULT '{}'::jsonb,
+    service_type_rules JSONB NOT NULL DEFAULT '[]'::jsonb,
+    default_values     JSONB NOT NULL DEFAULT '{}'::jsonb,
+    fallback_to_heuristics BOOLEAN NOT NULL DEFAULT true,
+    is_active          BOOLEAN NOT NULL DEFAULT true,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT uq_webhook_field_mappings_api_key UNIQUE (api_key_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_field_mappings_org ON RAC_webhook_field_mappings(organization_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_webhook_field_mappings_org;
+DROP TABLE IF EXISTS RAC_webhook_field_mappings;
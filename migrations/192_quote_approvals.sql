@@ -0,0 +1,38 @@
+-- +goose Up
+-- Internal approval gate for high-value quotes or quotes sent by restricted roles.
+CREATE TABLE IF NOT EXISTS RAC_quote_approval_policies (
+    organization_id   UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    enabled           BOOLEAN NOT NULL DEFAULT false,
+    threshold_cents   BIGINT,
+    require_for_roles TEXT[] NOT NULL DEFAULT '{}',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS RAC_quote_approvals (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id   UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    quote_id          UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    status            TEXT NOT NULL DEFAULT 'pending'
+                      CHECK (status IN ('pending', 'approved', 'rejected')),
+    reasons           TEXT[] NOT NULL DEFAULT '{}',
+    quote_total_cents BIGINT NOT NULL,
+    requested_by_id   UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    request_note      TEXT,
+    decided_by_id     UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    decision_note     TEXT,
+    decided_at        TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_approvals_quote ON RAC_quote_approvals(quote_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_quote_approvals_org_pending ON RAC_quote_approvals(organization_id, created_at DESC) WHERE status = 'pending';
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quote_approvals_one_pending ON RAC_quote_approvals(quote_id) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS uq_quote_approvals_one_pending;
+DROP INDEX IF EXISTS idx_quote_approvals_org_pending;
+DROP INDEX IF EXISTS idx_quote_approvals_quote;
+DROP TABLE IF EXISTS RAC_quote_approvals;
+DROP TABLE IF EXISTS RAC_quote_approval_policies;
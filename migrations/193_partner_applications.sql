@@ -0,0 +1,51 @@
+-- +goose Up
+-- Public partner applications with a vetting queue ahead of conversion to an active partner.
+CREATE TABLE IF NOT EXISTS RAC_partner_applications (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id         UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    status                  TEXT NOT NULL DEFAULT 'pending'
+                            CHECK (status IN ('pending', 'approved', 'rejected')),
+    business_name           TEXT NOT NULL,
+    kvk_number              TEXT NOT NULL,
+    vat_number              TEXT,
+    address_line1           TEXT NOT NULL,
+    house_number            TEXT NOT NULL,
+    postal_code             TEXT NOT NULL,
+    city                    TEXT NOT NULL,
+    country                 TEXT NOT NULL,
+    contact_name            TEXT NOT NULL,
+    contact_email           TEXT NOT NULL,
+    contact_phone           TEXT NOT NULL,
+    service_type_ids        UUID[] NOT NULL DEFAULT '{}',
+    motivation              TEXT,
+    upload_token_hash       TEXT NOT NULL UNIQUE,
+    upload_token_expires_at TIMESTAMPTZ NOT NULL,
+    reviewed_by_id          UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    review_note             TEXT,
+    reviewed_at             TIMESTAMPTZ,
+    partner_id              UUID REFERENCES RAC_partners(id) ON DELETE SET NULL,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_applications_org_status ON RAC_partner_applications(organization_id, status, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_partner_applications_pending_kvk
+    ON RAC_partner_applications(organization_id, kvk_number) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS RAC_partner_application_documents (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id  UUID NOT NULL REFERENCES RAC_partner_applications(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    document_type   TEXT NOT NULL CHECK (document_type IN ('insurance', 'certification', 'other')),
+    file_key        TEXT NOT NULL,
+    file_name       TEXT NOT NULL,
+    content_type    TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_application_documents_app ON RAC_partner_application_documents(application_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_partner_application_documents;
+DROP TABLE IF EXISTS RAC_partner_applications;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- Cached KvK (Dutch Chamber of Commerce) company profiles, shared across organizations.
+CREATE TABLE IF NOT EXISTS RAC_kvk_profiles (
+    kvk_number    TEXT PRIMARY KEY,
+    legal_name    TEXT NOT NULL,
+    trade_names   TEXT[] NOT NULL DEFAULT '{}',
+    street        TEXT,
+    house_number  TEXT,
+    postal_code   TEXT,
+    city          TEXT,
+    country       TEXT,
+    sbi_codes     JSONB NOT NULL DEFAULT '[]'::jsonb,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    registered_at DATE,
+    dissolved_at  DATE,
+    fetched_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Links partners and business leads to a KvK registration and tracks revalidation.
+CREATE TABLE IF NOT EXISTS RAC_kvk_enrichments (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    entity_type     TEXT NOT NULL CHECK (entity_type IN ('partner', 'lead')),
+    entity_id       UUID NOT NULL,
+    kvk_number      TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'active'
+                    CHECK (status IN ('active', 'dissolved', 'not_found')),
+    last_checked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    flagged_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, entity_type, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kvk_enrichments_last_checked ON RAC_kvk_enrichments(last_checked_at);
+CREATE INDEX IF NOT EXISTS idx_kvk_enrichments_org_status ON RAC_kvk_enrichments(organization_id, status);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_kvk_enrichments;
+DROP TABLE IF EXISTS RAC_kvk_profiles;
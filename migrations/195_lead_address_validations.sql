@@ -0,0 +1,17 @@
+-- +goose Up
+-- BAG validation result of a lead's intake address, used by lead scoring.
+CREATE TABLE IF NOT EXISTS RAC_lead_address_validations (
+    lead_id                 UUID PRIMARY KEY REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    organization_id         UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    is_valid                BOOLEAN NOT NULL,
+    source                  TEXT NOT NULL DEFAULT 'bag',
+    nummeraanduiding_id     TEXT,
+    adresseerbaar_object_id TEXT,
+    validated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_address_validations_org_valid
+    ON RAC_lead_address_validations(organization_id, is_valid);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_lead_address_validations;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Per-organization AI provider configuration. NULL columns keep the platform defaults.
+CREATE TABLE IF NOT EXISTS RAC_organization_ai_settings (
+    organization_id      UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    provider             TEXT,
+    model                TEXT,
+    temperature          DOUBLE PRECISION CHECK (temperature IS NULL OR (temperature >= 0 AND temperature <= 2)),
+    monthly_token_budget BIGINT CHECK (monthly_token_budget IS NULL OR monthly_token_budget > 0),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Token usage per LLM call, used for budget enforcement and usage reporting.
+CREATE TABLE IF NOT EXISTS RAC_ai_usage_events (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id   UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    provider          TEXT NOT NULL,
+    model             TEXT NOT NULL,
+    prompt_tokens     BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_events_org_created
+    ON RAC_ai_usage_events(organization_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_ai_usage_events;
+DROP TABLE IF EXISTS RAC_organization_ai_settings;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Extend the AI usage ledger with the call kind, the calling feature and the
+-- estimated list-price cost, so AI features can be billed per tenant.
+ALTER TABLE RAC_ai_usage_events
+    ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'chat' CHECK (kind IN ('chat', 'embedding')),
+    ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS estimated_cost_usd NUMERIC(14, 6) NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_events_org_kind_created
+    ON RAC_ai_usage_events(organization_id, kind, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ai_usage_events_org_kind_created;
+ALTER TABLE RAC_ai_usage_events
+    DROP COLUMN IF EXISTS estimated_cost_usd,
+    DROP COLUMN IF EXISTS source,
+    DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Track which embedding model and version produced the vector of each catalog
+-- product, so stale vectors can be re-embedded after a model change.
+CREATE TABLE IF NOT EXISTS RAC_catalog_product_embeddings (
+    product_id UUID PRIMARY KEY REFERENCES RAC_catalog_products(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    collection TEXT NOT NULL,
+    embedding_model TEXT NOT NULL,
+    embedding_version TEXT NOT NULL,
+    indexed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_product_embeddings_org_version
+    ON RAC_catalog_product_embeddings(organization_id, embedding_model, embedding_version);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_catalog_product_embeddings;
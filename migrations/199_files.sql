@@ -0,0 +1,32 @@
+-- +goose Up
+-- Central metadata for uploaded files. Rows with the same checksum within an
+-- organization share one stored object, so an object is only removed from
+-- storage once the last row that references it is deleted.
+CREATE TABLE IF NOT EXISTS RAC_files (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    owner_type      TEXT NOT NULL CHECK (owner_type IN ('lead', 'lead_service', 'quote', 'catalog_product', 'partner', 'organization')),
+    owner_id        UUID NOT NULL,
+    bucket          TEXT NOT NULL,
+    file_key        TEXT NOT NULL,
+    file_name       TEXT NOT NULL,
+    content_type    TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL CHECK (size_bytes > 0),
+    checksum_sha256 TEXT NOT NULL CHECK (checksum_sha256 ~ '^[0-9a-f]{64}$'),
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready')),
+    created_by      UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    uploaded_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_files_owner
+    ON RAC_files(organization_id, owner_type, owner_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_files_checksum
+    ON RAC_files(organization_id, checksum_sha256) WHERE status = 'ready';
+CREATE INDEX IF NOT EXISTS idx_files_object
+    ON RAC_files(bucket, file_key);
+CREATE INDEX IF NOT EXISTS idx_files_pending
+    ON RAC_files(created_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_files;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Running storage usage per organization and bucket. Uploads and deletes adjust
+-- the counters; the nightly reconciliation overwrites them with the actual
+-- object listing so drift from abandoned presigned uploads is corrected.
+CREATE TABLE IF NOT EXISTS RAC_organization_storage_usage (
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    bucket          TEXT NOT NULL,
+    object_count    BIGINT NOT NULL DEFAULT 0,
+    size_bytes      BIGINT NOT NULL DEFAULT 0,
+    reconciled_at   TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, bucket)
+);
+
+-- Per-organization overrides of the configured default limits. NULL falls back
+-- to the default, zero disables the limit.
+CREATE TABLE IF NOT EXISTS RAC_organization_storage_quotas (
+    organization_id  UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    soft_limit_bytes BIGINT CHECK (soft_limit_bytes >= 0),
+    hard_limit_bytes BIGINT CHECK (hard_limit_bytes >= 0),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_organization_storage_quotas;
+DROP TABLE IF EXISTS RAC_organization_storage_usage;
@@ -0,0 +1,55 @@
+-- +goose Up
+-- Credit notes document downward corrections on accepted quotes. Amounts are stored
+-- negative, exactly as they appear on the credit note and in the accounting export.
+CREATE TABLE IF NOT EXISTS RAC_quote_credit_notes (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id    UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    quote_id           UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    sequence           INT NOT NULL CHECK (sequence > 0),
+    credit_note_number TEXT NOT NULL,
+    reason             TEXT NOT NULL,
+    subtotal_cents     BIGINT NOT NULL CHECK (subtotal_cents <= 0),
+    tax_total_cents    BIGINT NOT NULL CHECK (tax_total_cents <= 0),
+    total_cents        BIGINT NOT NULL CHECK (total_cents < 0),
+    pdf_file_key       TEXT,
+    created_by_id      UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (quote_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_credit_notes_quote ON RAC_quote_credit_notes(quote_id, sequence);
+
+CREATE TABLE IF NOT EXISTS RAC_quote_credit_note_items (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    credit_note_id   UUID NOT NULL REFERENCES RAC_quote_credit_notes(id) ON DELETE CASCADE,
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    quote_item_id    UUID REFERENCES RAC_quote_items(id) ON DELETE SET NULL,
+    description      TEXT NOT NULL,
+    quantity         TEXT NOT NULL,
+    unit_price_cents BIGINT NOT NULL CHECK (unit_price_cents < 0),
+    tax_rate_bps     INT NOT NULL DEFAULT 0,
+    line_total_cents BIGINT NOT NULL,
+    sort_order       INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_credit_note_items_note ON RAC_quote_credit_note_items(credit_note_id, sort_order);
+
+CREATE TABLE IF NOT EXISTS RAC_quote_credit_note_exports (
+    credit_note_id  UUID NOT NULL REFERENCES RAC_quote_credit_notes(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    provider        TEXT NOT NULL,
+    external_id     TEXT NOT NULL,
+    external_url    TEXT,
+    state           TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (credit_note_id, provider)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_quote_credit_note_exports;
+DROP INDEX IF EXISTS idx_quote_credit_note_items_note;
+DROP TABLE IF EXISTS RAC_quote_credit_note_items;
+DROP INDEX IF EXISTS idx_quote_credit_notes_quote;
+DROP TABLE IF EXISTS RAC_quote_credit_notes;
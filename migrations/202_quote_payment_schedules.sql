@@ -0,0 +1,66 @@
+-- +goose Up
+-- Payment schedules split a quote total into installments (e.g. 30% deposit,
+-- 60% at the start of work, 10% on completion). Amounts are derived from the
+-- quote total at read time so edits to the quote never leave the split stale.
+CREATE TABLE IF NOT EXISTS RAC_quote_payment_installments (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    quote_id        UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    sequence        INT NOT NULL CHECK (sequence > 0),
+    label           TEXT NOT NULL,
+    percentage_bps  INT NOT NULL CHECK (percentage_bps > 0 AND percentage_bps <= 10000),
+    due_trigger     TEXT NOT NULL CHECK (due_trigger IN ('on_acceptance', 'days_after_acceptance', 'on_date')),
+    due_after_days  INT CHECK (due_after_days >= 0),
+    due_date        DATE,
+    due_notified_at TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (quote_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_payment_installments_pending
+    ON RAC_quote_payment_installments(quote_id)
+    WHERE due_notified_at IS NULL;
+
+-- Reminder templates for installments that became due.
+INSERT INTO RAC_workflow_steps (
+  organization_id,
+  workflow_id,
+  trigger,
+  channel,
+  audience,
+  action,
+  step_order,
+  delay_minutes,
+  enabled,
+  recipient_config,
+  template_subject,
+  template_body,
+  stop_on_reply
+)
+SELECT
+  w.organization_id,
+  w.id,
+  s.trigger,
+  s.channel,
+  s.audience,
+  'send_message',
+  s.step_order,
+  0,
+  TRUE,
+  s.recipient_config,
+  s.template_subject,
+  s.template_body,
+  FALSE
+FROM RAC_workflows w
+CROSS JOIN (
+  VALUES
+    ('quote_installment_due', 'whatsapp', 'lead', 22, '{"includeLeadContact": true}'::jsonb, NULL::text, 'Hallo {{lead.name}}, de termijn "{{installment.label}}" van {{installment.amount}} voor offerte {{quote.number}} is verschuldigd per {{installment.dueDate}}.'::text),
+    ('quote_installment_due', 'email', 'lead', 23, '{"includeLeadContact": true}'::jsonb, 'Betalingstermijn offerte {{quote.number}}'::text, E'Hallo {{lead.name}},\n\nDe termijn "{{installment.label}}" van {{installment.amount}} voor offerte {{quote.number}} is verschuldigd per {{installment.dueDate}}.\n\nMet vriendelijke groet,\n{{org.name}}'::text)
+) AS s(trigger, channel, audience, step_order, recipient_config, template_subject, template_body)
+WHERE w.workflow_key = 'default'
+ON CONFLICT (workflow_id, trigger, channel, step_order) DO NOTHING;
+
+-- +goose Down
+DELETE FROM RAC_workflow_steps WHERE trigger = 'quote_installment_due';
+DROP INDEX IF EXISTS idx_quote_payment_installments_pending;
+DROP TABLE IF EXISTS RAC_quote_payment_installments;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Online payments (Mollie) for quote deposits. The Mollie API key of an organization is
+-- stored encrypted in RAC_provider_integrations (provider 'mollie', access_token column).
+CREATE TABLE IF NOT EXISTS RAC_quote_payments (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    quote_id        UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    installment_id  UUID REFERENCES RAC_quote_payment_installments(id) ON DELETE SET NULL,
+    provider        TEXT NOT NULL DEFAULT 'mollie',
+    external_id     TEXT NOT NULL,
+    checkout_url    TEXT,
+    amount_cents    BIGINT NOT NULL CHECK (amount_cents > 0),
+    currency        TEXT NOT NULL DEFAULT 'EUR',
+    status          TEXT NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'pending', 'authorized', 'paid', 'canceled', 'expired', 'failed')),
+    paid_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (provider, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_payments_quote ON RAC_quote_payments(quote_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_quote_payments;
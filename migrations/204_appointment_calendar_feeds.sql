@@ -0,0 +1,54 @@
+-- +goose Up
+-- Per-agent iCalendar subscription feeds. Calendar apps cannot send auth headers, so a
+-- feed is authorized by a secret URL token; only its SHA-256 hash is stored.
+CREATE TABLE IF NOT EXISTS RAC_appointment_calendar_feeds (
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    user_id          UUID NOT NULL REFERENCES RAC_users(id) ON DELETE CASCADE,
+    token_hash       TEXT NOT NULL UNIQUE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_accessed_at TIMESTAMPTZ,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+-- Templates for rescheduled appointments; the email carries an updated .ics invite.
+INSERT INTO RAC_workflow_steps (
+  organization_id,
+  workflow_id,
+  trigger,
+  channel,
+  audience,
+  action,
+  step_order,
+  delay_minutes,
+  enabled,
+  recipient_config,
+  template_subject,
+  template_body,
+  stop_on_reply
+)
+SELECT
+  w.organization_id,
+  w.id,
+  s.trigger,
+  s.channel,
+  s.audience,
+  'send_message',
+  s.step_order,
+  0,
+  TRUE,
+  s.recipient_config,
+  s.template_subject,
+  s.template_body,
+  FALSE
+FROM RAC_workflows w
+CROSS JOIN (
+  VALUES
+    ('appointment_updated', 'whatsapp', 'lead', 24, '{"includeLeadContact": true}'::jsonb, NULL::text, 'Hallo {{lead.name}}, je afspraak is gewijzigd. De nieuwe afspraak is op {{appointment.date}} om {{appointment.time}}.'::text),
+    ('appointment_updated', 'email', 'lead', 25, '{"includeLeadContact": true}'::jsonb, 'Afspraak gewijzigd naar {{appointment.date}}'::text, E'Hallo {{lead.name}},\n\nJe afspraak is gewijzigd. De nieuwe afspraak is op {{appointment.date}} om {{appointment.time}}. De bijgevoegde agenda-uitnodiging werkt je agenda bij.\n\nMet vriendelijke groet,\n{{org.name}}'::text)
+) AS s(trigger, channel, audience, step_order, recipient_config, template_subject, template_body)
+WHERE w.workflow_key = 'default'
+ON CONFLICT (workflow_id, trigger, channel, step_order) DO NOTHING;
+
+-- +goose Down
+DELETE FROM RAC_workflow_steps WHERE trigger = 'appointment_updated';
+DROP TABLE IF EXISTS RAC_appointment_calendar_feeds;
@@ -0,0 +1,50 @@
+-- +goose Up
+-- Daily funnel aggregates per organization, refreshed by the scheduler from the
+-- lead service event log and quotes. Days are Europe/Amsterdam calendar days.
+CREATE TABLE IF NOT EXISTS RAC_analytics_funnel_daily (
+    organization_id            UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    day                        DATE NOT NULL,
+    service_type               TEXT NOT NULL,
+    source                     TEXT NOT NULL,
+    assigned_agent_id          UUID,
+    services_created           INT NOT NULL DEFAULT 0,
+    services_won               INT NOT NULL DEFAULT 0,
+    services_lost              INT NOT NULL DEFAULT 0,
+    quotes_issued              INT NOT NULL DEFAULT 0,
+    quotes_accepted            INT NOT NULL DEFAULT 0,
+    quote_value_issued_cents   BIGINT NOT NULL DEFAULT 0,
+    quote_value_accepted_cents BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_funnel_daily_org_day
+    ON RAC_analytics_funnel_daily(organization_id, day);
+CREATE INDEX IF NOT EXISTS idx_analytics_funnel_daily_day
+    ON RAC_analytics_funnel_daily(day);
+
+-- Stage entries and exits per day. Time in stage is summed on the exit day so the
+-- average only covers services that actually left the stage.
+CREATE TABLE IF NOT EXISTS RAC_analytics_stage_daily (
+    organization_id         UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    day                     DATE NOT NULL,
+    pipeline_stage          TEXT NOT NULL,
+    entered_count           INT NOT NULL DEFAULT 0,
+    exited_count            INT NOT NULL DEFAULT 0,
+    exited_duration_seconds BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (organization_id, day, pipeline_stage)
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_stage_daily_day
+    ON RAC_analytics_stage_daily(day);
+
+-- Single row tracking how far the aggregates have been refreshed. A missing row
+-- makes the next refresh rebuild the full history.
+CREATE TABLE IF NOT EXISTS RAC_analytics_refresh_state (
+    id                BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    refreshed_through DATE NOT NULL,
+    refreshed_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_analytics_refresh_state;
+DROP TABLE IF EXISTS RAC_analytics_stage_daily;
+DROP TABLE IF EXISTS RAC_analytics_funnel_daily;
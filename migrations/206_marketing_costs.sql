@@ -0,0 +1,20 @@
+-- +goose Up
+-- Advertising spend per organization, day and source, imported from Google Ads
+-- cost reports. Campaign is empty for spend that is not split per campaign.
+CREATE TABLE IF NOT EXISTS RAC_marketing_costs (
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    day             DATE NOT NULL,
+    source          TEXT NOT NULL,
+    campaign        TEXT NOT NULL DEFAULT '',
+    cost_cents      BIGINT NOT NULL DEFAULT 0,
+    clicks          INT NOT NULL DEFAULT 0,
+    impressions     INT NOT NULL DEFAULT 0,
+    imported_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, day, source, campaign)
+);
+
+CREATE INDEX IF NOT EXISTS idx_marketing_costs_org_source_day
+    ON RAC_marketing_costs(organization_id, source, day);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_marketing_costs;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Meta click ID captured at lead intake, next to the Google Ads attribution fields
+
+ALTER TABLE RAC_leads
+  ADD COLUMN IF NOT EXISTS fbclid TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_leads_fbclid ON RAC_leads(fbclid) WHERE fbclid IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_leads_fbclid;
+
+ALTER TABLE RAC_leads DROP COLUMN IF EXISTS fbclid;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Per-organization data retention. The scheduler applies the active policies;
+-- NULL windows keep the data forever.
+CREATE TABLE IF NOT EXISTS RAC_organization_retention_policies (
+    organization_id      UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    rejected_lead_action TEXT NOT NULL DEFAULT 'off' CHECK (rejected_lead_action IN ('off', 'anonymize', 'purge')),
+    rejected_lead_days   INT NOT NULL DEFAULT 365 CHECK (rejected_lead_days >= 30),
+    timeline_event_days  INT CHECK (timeline_event_days >= 90),
+    draft_quote_days     INT CHECK (draft_quote_days >= 7),
+    last_applied_at      TIMESTAMPTZ,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Anonymized leads keep their services and quotes for reporting but are
+-- skipped by later retention runs.
+ALTER TABLE RAC_leads ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_timeline_org_created_at ON lead_timeline_events(organization_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_timeline_org_created_at;
+ALTER TABLE RAC_leads DROP COLUMN IF EXISTS anonymized_at;
+DROP TABLE IF EXISTS RAC_organization_retention_policies;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Organizations whose lead, quote, appointment and task data lives in a
+-- dedicated schema. The schema is chosen while the organization is still empty;
+-- synced_version is the goose version its tables were last brought up to.
+CREATE TABLE IF NOT EXISTS RAC_organization_data_schemas (
+    organization_id UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    schema_name     TEXT NOT NULL UNIQUE,
+    synced_version  BIGINT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    synced_at       TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_organization_data_schemas;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- Optimistic concurrency: every update of a lead, lead service or quote bumps
+-- row_version, so a client that read an older version gets a conflict instead
+-- of silently overwriting someone else's change. Statements that bump the
+-- version themselves are left alone.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION rac_bump_row_version() RETURNS trigger AS $$
+BEGIN
+    IF NEW.row_version = OLD.row_version THEN
+        NEW.row_version := OLD.row_version + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+ALTER TABLE RAC_leads ADD COLUMN IF NOT EXISTS row_version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE RAC_lead_services ADD COLUMN IF NOT EXISTS row_version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE RAC_quotes ADD COLUMN IF NOT EXISTS row_version BIGINT NOT NULL DEFAULT 1;
+
+DROP TRIGGER IF EXISTS trg_leads_row_version ON RAC_leads;
+CREATE TRIGGER trg_leads_row_version
+    BEFORE UPDATE ON RAC_leads
+    FOR EACH ROW EXECUTE FUNCTION rac_bump_row_version();
+
+DROP TRIGGER IF EXISTS trg_lead_services_row_version ON RAC_lead_services;
+CREATE TRIGGER trg_lead_services_row_version
+    BEFORE UPDATE ON RAC_lead_services
+    FOR EACH ROW EXECUTE FUNCTION rac_bump_row_version();
+
+DROP TRIGGER IF EXISTS trg_quotes_row_version ON RAC_quotes;
+CREATE TRIGGER trg_quotes_row_version
+    BEFORE UPDATE ON RAC_quotes
+    FOR EACH ROW EXECUTE FUNCTION rac_bump_row_version();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_quotes_row_version ON RAC_quotes;
+DROP TRIGGER IF EXISTS trg_lead_services_row_version ON RAC_lead_services;
+DROP TRIGGER IF EXISTS trg_leads_row_version ON RAC_leads;
+ALTER TABLE RAC_quotes DROP COLUMN IF EXISTS row_version;
+ALTER TABLE RAC_lead_services DROP COLUMN IF EXISTS row_version;
+ALTER TABLE RAC_leads DROP COLUMN IF EXISTS row_version;
+DROP FUNCTION IF EXISTS rac_bump_row_version();
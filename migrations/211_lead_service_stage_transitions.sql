@@ -0,0 +1,33 @@
+-- +goose Up
+-- Every pipeline stage change of a lead service, written in the same
+-- transaction as the change itself, with who made it and why.
+CREATE TABLE IF NOT EXISTS RAC_lead_service_stage_transitions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  lead_id UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+  lead_service_id UUID NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+  from_stage TEXT,
+  to_stage TEXT NOT NULL,
+  actor_type TEXT NOT NULL,
+  actor_id UUID,
+  actor_name TEXT NOT NULL,
+  reason TEXT,
+  occurred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stage_transitions_service ON RAC_lead_service_stage_transitions(lead_service_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_stage_transitions_org_time ON RAC_lead_service_stage_transitions(organization_id, occurred_at DESC);
+
+-- Reconstruct the history of existing services from their service events.
+INSERT INTO RAC_lead_service_stage_transitions (organization_id, lead_id, lead_service_id, from_stage, to_stage, actor_type, actor_name, reason, occurred_at)
+SELECT e.organization_id, e.lead_id, e.lead_service_id, e.prev_stage, e.pipeline_stage, 'System', 'Migration', 'Reconstructed from service events', e.occurred_at
+FROM (
+  SELECT ev.*, LAG(ev.pipeline_stage) OVER (PARTITION BY ev.lead_service_id ORDER BY ev.occurred_at, ev.id) AS prev_stage
+  FROM RAC_lead_service_events ev
+  WHERE ev.pipeline_stage IS NOT NULL
+) e
+WHERE e.event_type <> 'service_created'
+  AND e.prev_stage IS DISTINCT FROM e.pipeline_stage;
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_lead_service_stage_transitions;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Optional send condition per workflow step, evaluated against the step's
+-- template variables when the step is enqueued. NULL sends unconditionally.
+ALTER TABLE RAC_workflow_steps ADD COLUMN IF NOT EXISTS send_condition TEXT;
+
+-- +goose Down
+ALTER TABLE RAC_workflow_steps DROP COLUMN IF EXISTS send_condition;
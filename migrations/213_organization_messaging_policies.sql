@@ -0,0 +1,24 @@
+-- +goose Up
+-- Quiet hours and per-lead frequency caps for outbound messages. Messages
+-- that fall in quiet hours or over a cap are deferred, never dropped.
+CREATE TABLE IF NOT EXISTS RAC_organization_messaging_policies (
+  organization_id UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  quiet_hours_enabled BOOLEAN NOT NULL DEFAULT false,
+  -- Minutes since midnight in the policy time zone; the window may wrap
+  -- past midnight (start 1260, end 480 is 21:00-08:00).
+  quiet_hours_start SMALLINT NOT NULL DEFAULT 1260 CHECK (quiet_hours_start BETWEEN 0 AND 1439),
+  quiet_hours_end SMALLINT NOT NULL DEFAULT 480 CHECK (quiet_hours_end BETWEEN 0 AND 1439),
+  timezone TEXT NOT NULL DEFAULT 'Europe/Amsterdam',
+  max_messages_per_lead_per_hour INTEGER CHECK (max_messages_per_lead_per_hour > 0),
+  max_messages_per_lead_per_day INTEGER CHECK (max_messages_per_lead_per_day > 0),
+  include_email BOOLEAN NOT NULL DEFAULT false,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_lead_run_at
+  ON RAC_notification_outbox(tenant_id, lead_id, run_at)
+  WHERE lead_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notification_outbox_lead_run_at;
+DROP TABLE IF EXISTS RAC_organization_messaging_policies;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Short-lived magic links to the lead portal. The token itself is never
+-- stored: it is the link id signed with a server secret. expires_at slides
+-- forward on use, up to max_expires_at.
+CREATE TABLE IF NOT EXISTS RAC_lead_portal_links (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  lead_id UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+  channel TEXT NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  max_expires_at TIMESTAMPTZ NOT NULL,
+  last_used_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  revoked_reason TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_portal_links_lead ON RAC_lead_portal_links(organization_id, lead_id) WHERE revoked_at IS NULL;
+
+-- Legacy public tokens without an expiry keep working for a 90 day
+-- transition window, after which leads need a magic link.
+UPDATE RAC_leads
+SET public_token_expires_at = now() + interval '90 days'
+WHERE public_token IS NOT NULL
+  AND public_token_expires_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_lead_portal_links;
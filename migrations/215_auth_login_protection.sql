@@ -0,0 +1,47 @@
+-- +goose Up
+-- Every password sign-in attempt, used for progressive lockouts and audits.
+CREATE TABLE IF NOT EXISTS RAC_auth_login_attempts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID REFERENCES RAC_users(id) ON DELETE CASCADE,
+  email TEXT NOT NULL,
+  ip_address TEXT NOT NULL,
+  user_agent TEXT,
+  success BOOLEAN NOT NULL,
+  failure_reason TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_login_attempts_email ON RAC_auth_login_attempts(email, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_auth_login_attempts_ip ON RAC_auth_login_attempts(ip_address, created_at DESC);
+
+-- Lockouts per account (subject is the email) or per client IP. A lockout is
+-- active while locked_until is in the future and it was not cleared.
+CREATE TABLE IF NOT EXISTS RAC_auth_lockouts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  scope TEXT NOT NULL CHECK (scope IN ('account', 'ip')),
+  subject TEXT NOT NULL,
+  user_id UUID REFERENCES RAC_users(id) ON DELETE CASCADE,
+  failed_attempts INT NOT NULL,
+  locked_until TIMESTAMPTZ NOT NULL,
+  cleared_at TIMESTAMPTZ,
+  cleared_by UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (scope, subject)
+);
+
+-- Devices and networks a user signed in from, to detect new ones.
+CREATE TABLE IF NOT EXISTS RAC_auth_known_devices (
+  user_id UUID NOT NULL REFERENCES RAC_users(id) ON DELETE CASCADE,
+  device_hash TEXT NOT NULL,
+  network TEXT NOT NULL,
+  user_agent TEXT,
+  first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, device_hash, network)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_auth_known_devices;
+DROP TABLE IF EXISTS RAC_auth_lockouts;
+DROP TABLE IF EXISTS RAC_auth_login_attempts;
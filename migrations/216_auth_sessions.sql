@@ -0,0 +1,31 @@
+-- +goose Up
+-- A session groups the refresh tokens rotated from one sign-in, so users can
+-- see where they are signed in and revoke it. access_token_jti is the latest
+-- access token issued for the session, blocklisted when it is revoked.
+CREATE TABLE IF NOT EXISTS RAC_auth_sessions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES RAC_users(id) ON DELETE CASCADE,
+  user_agent TEXT,
+  ip_address TEXT,
+  access_token_jti TEXT,
+  access_token_expires_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_used_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ,
+  revoked_reason TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user_active
+  ON RAC_auth_sessions(user_id, last_used_at DESC)
+  WHERE revoked_at IS NULL;
+
+ALTER TABLE RAC_refresh_tokens
+  ADD COLUMN IF NOT EXISTS session_id UUID REFERENCES RAC_auth_sessions(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON RAC_refresh_tokens(session_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_refresh_tokens_session_id;
+ALTER TABLE RAC_refresh_tokens DROP COLUMN IF EXISTS session_id;
+DROP TABLE IF EXISTS RAC_auth_sessions;
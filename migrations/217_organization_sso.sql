@@ -0,0 +1,40 @@
+-- +goose Up
+-- Per-organization OpenID Connect single sign-on. role_mappings maps values of
+-- role_claim in the ID token to application roles; users without a mapped
+-- value get default_role. enforce_sso disables password sign-in for members.
+CREATE TABLE IF NOT EXISTS RAC_organization_sso_configs (
+  organization_id UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  enabled BOOLEAN NOT NULL DEFAULT false,
+  issuer_url TEXT NOT NULL,
+  client_id TEXT NOT NULL,
+  client_secret_encrypted TEXT NOT NULL,
+  allowed_domains TEXT[] NOT NULL DEFAULT '{}',
+  role_claim TEXT,
+  role_mappings JSONB NOT NULL DEFAULT '{}'::jsonb,
+  default_role TEXT NOT NULL DEFAULT 'user',
+  jit_provisioning BOOLEAN NOT NULL DEFAULT true,
+  enforce_sso BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_sso_configs_domains
+  ON RAC_organization_sso_configs USING GIN (allowed_domains)
+  WHERE enabled;
+
+-- Links an identity provider account (issuer + subject) to a user.
+CREATE TABLE IF NOT EXISTS RAC_user_sso_identities (
+  issuer TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  user_id UUID NOT NULL REFERENCES RAC_users(id) ON DELETE CASCADE,
+  organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_login_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sso_identities_user_id ON RAC_user_sso_identities(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_user_sso_identities;
+DROP TABLE IF EXISTS RAC_organization_sso_configs;
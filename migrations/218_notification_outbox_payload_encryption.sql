@@ -0,0 +1,12 @@
+-- +goose Up
+-- Encrypted payloads live in payload_encrypted; payload then holds '{}'.
+-- payload_key_id records the key that sealed the payload so rows can be
+-- re-encrypted after a key rotation.
+ALTER TABLE RAC_notification_outbox
+  ADD COLUMN IF NOT EXISTS payload_encrypted TEXT,
+  ADD COLUMN IF NOT EXISTS payload_key_id TEXT;
+
+-- +goose Down
+ALTER TABLE RAC_notification_outbox
+  DROP COLUMN IF EXISTS payload_key_id,
+  DROP COLUMN IF EXISTS payload_encrypted;
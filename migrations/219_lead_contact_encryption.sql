@@ -0,0 +1,28 @@
+-- +goose Up
+-- The consumer phone and email encrypted with the key named in contact_key_id,
+-- plus HMAC blind indexes so leads can still be found by exact phone or email
+-- without decrypting every row. The plaintext consumer_phone and
+-- consumer_email stay until every reader decrypts the sealed copy.
+ALTER TABLE RAC_leads
+  ADD COLUMN IF NOT EXISTS consumer_phone_encrypted TEXT,
+  ADD COLUMN IF NOT EXISTS consumer_email_encrypted TEXT,
+  ADD COLUMN IF NOT EXISTS contact_key_id TEXT,
+  ADD COLUMN IF NOT EXISTS consumer_phone_bidx TEXT,
+  ADD COLUMN IF NOT EXISTS consumer_email_bidx TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_leads_consumer_phone_bidx
+  ON RAC_leads(organization_id, consumer_phone_bidx)
+  WHERE consumer_phone_bidx IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_leads_consumer_email_bidx
+  ON RAC_leads(organization_id, consumer_email_bidx)
+  WHERE consumer_email_bidx IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_leads_consumer_email_bidx;
+DROP INDEX IF EXISTS idx_leads_consumer_phone_bidx;
+ALTER TABLE RAC_leads
+  DROP COLUMN IF EXISTS consumer_email_bidx,
+  DROP COLUMN IF EXISTS consumer_phone_bidx,
+  DROP COLUMN IF EXISTS contact_key_id,
+  DROP COLUMN IF EXISTS consumer_email_encrypted,
+  DROP COLUMN IF EXISTS consumer_phone_encrypted;
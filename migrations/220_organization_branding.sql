@@ -0,0 +1,18 @@
+-- +goose Up
+-- Branding applied to customer-facing emails, quote PDFs and the public
+-- quote and lead portals. NULL columns fall back to the platform defaults.
+CREATE TABLE IF NOT EXISTS RAC_organization_branding (
+  organization_id UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  -- Colors are #RRGGBB hex strings.
+  primary_color TEXT CHECK (primary_color ~ '^#[0-9A-Fa-f]{6}$'),
+  accent_color TEXT CHECK (accent_color ~ '^#[0-9A-Fa-f]{6}$'),
+  -- Sanitized HTML placed above and below the body of outgoing emails.
+  email_header_html TEXT,
+  email_footer_html TEXT,
+  sender_display_name TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_organization_branding;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Language of customer-facing notifications. Leads may override the
+-- organization default; workflow steps carry per-language template variants
+-- keyed by ISO 639-1 code, e.g. {"en": {"templateSubject": ..., "templateBody": ...}}.
+ALTER TABLE RAC_organizations
+  ADD COLUMN IF NOT EXISTS default_language TEXT NOT NULL DEFAULT 'nl';
+
+ALTER TABLE RAC_leads
+  ADD COLUMN IF NOT EXISTS preferred_language TEXT;
+
+ALTER TABLE RAC_workflow_steps
+  ADD COLUMN IF NOT EXISTS template_translations JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- +goose Down
+ALTER TABLE RAC_workflow_steps DROP COLUMN IF EXISTS template_translations;
+ALTER TABLE RAC_leads DROP COLUMN IF EXISTS preferred_language;
+ALTER TABLE RAC_organizations DROP COLUMN IF EXISTS default_language;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- Grouping, snoozing and resurfacing of in-app notifications. Repeated
+-- notifications with the same group_key on the same day are folded into one
+-- row; surfaced_at orders the feed and moves when a notification is grouped
+-- or returns from snooze.
+ALTER TABLE RAC_in_app_notifications
+  ADD COLUMN IF NOT EXISTS group_key TEXT,
+  ADD COLUMN IF NOT EXISTS group_count INTEGER NOT NULL DEFAULT 1,
+  ADD COLUMN IF NOT EXISTS snoozed_until TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS surfaced_at TIMESTAMPTZ;
+
+UPDATE RAC_in_app_notifications SET surfaced_at = created_at WHERE surfaced_at IS NULL;
+
+ALTER TABLE RAC_in_app_notifications
+  ALTER COLUMN surfaced_at SET DEFAULT now(),
+  ALTER COLUMN surfaced_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_surfaced
+ON RAC_in_app_notifications (user_id, surfaced_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_group
+ON RAC_in_app_notifications (user_id, group_key, surfaced_at DESC)
+WHERE group_key IS NOT NULL AND is_read = FALSE;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_user_group;
+DROP INDEX IF EXISTS idx_notifications_user_surfaced;
+ALTER TABLE RAC_in_app_notifications
+  DROP COLUMN IF EXISTS surfaced_at,
+  DROP COLUMN IF EXISTS snoozed_until,
+  DROP COLUMN IF EXISTS group_count,
+  DROP COLUMN IF EXISTS group_key;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Supports the per-agent workload report, which aggregates the open services
+-- and pending quotes of an organization in single passes.
+CREATE INDEX IF NOT EXISTS idx_lead_services_org_open
+ON RAC_lead_services (organization_id, lead_id)
+WHERE pipeline_stage NOT IN ('Completed', 'Lost');
+
+CREATE INDEX IF NOT EXISTS idx_quotes_org_pending
+ON RAC_quotes (organization_id, lead_id)
+WHERE status IN ('Draft', 'Sent');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_quotes_org_pending;
+DROP INDEX IF EXISTS idx_lead_services_org_open;
@@ -0,0 +1,60 @@
+-- +goose Up
+-- Per-organization thresholds of the stale lead sweep, in days, and what a
+-- stale lead triggers besides the in-app notification: a follow-up task for
+-- the assigned agent and the lead_stale workflow towards the consumer.
+-- Organizations without a row use the column defaults.
+CREATE TABLE IF NOT EXISTS RAC_organization_stale_lead_settings (
+  organization_id UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  no_activity_days INTEGER NOT NULL DEFAULT 7 CHECK (no_activity_days BETWEEN 1 AND 365),
+  nurturing_days INTEGER NOT NULL DEFAULT 7 CHECK (nurturing_days BETWEEN 1 AND 365),
+  no_quote_days INTEGER NOT NULL DEFAULT 14 CHECK (no_quote_days BETWEEN 1 AND 365),
+  draft_quote_days INTEGER NOT NULL DEFAULT 30 CHECK (draft_quote_days BETWEEN 1 AND 365),
+  reschedule_days INTEGER NOT NULL DEFAULT 2 CHECK (reschedule_days BETWEEN 1 AND 365),
+  create_owner_tasks BOOLEAN NOT NULL DEFAULT true,
+  consumer_nudge_enabled BOOLEAN NOT NULL DEFAULT false,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Steps of the lead_stale trigger in existing default workflows. They only
+-- run for organizations that enable consumer_nudge_enabled.
+INSERT INTO RAC_workflow_steps (
+  organization_id,
+  workflow_id,
+  trigger,
+  channel,
+  audience,
+  action,
+  step_order,
+  delay_minutes,
+  enabled,
+  recipient_config,
+  template_subject,
+  template_body,
+  stop_on_reply
+)
+SELECT
+  w.organization_id,
+  w.id,
+  s.trigger,
+  s.channel,
+  s.audience,
+  'send_message',
+  s.step_order,
+  0,
+  TRUE,
+  s.recipient_config,
+  s.template_subject,
+  s.template_body,
+  FALSE
+FROM RAC_workflows w
+CROSS JOIN (
+  VALUES
+    ('lead_stale', 'whatsapp', 'lead', 26, '{"includeLeadContact": true}'::jsonb, NULL::text, 'Hallo {{lead.name}}, we hebben al even niets van je gehoord over je aanvraag voor {{lead.serviceType}}. Heb je nog vragen of zullen we een afspraak inplannen? Reageer gerust op dit bericht.'::text),
+    ('lead_stale', 'email', 'lead', 27, '{"includeLeadContact": true}'::jsonb, 'Nog interesse in {{lead.serviceType}}?'::text, E'Hallo {{lead.name}},\n\nWe hebben al even niets van je gehoord over je aanvraag voor {{lead.serviceType}}. Heb je nog vragen, of zullen we een afspraak inplannen? Reageer gerust op deze e-mail.\n\nMet vriendelijke groet,\n{{org.name}}'::text)
+) AS s(trigger, channel, audience, step_order, recipient_config, template_subject, template_body)
+WHERE w.workflow_key = 'default'
+ON CONFLICT (workflow_id, trigger, channel, step_order) DO NOTHING;
+
+-- +goose Down
+DELETE FROM RAC_workflow_steps WHERE trigger = 'lead_stale';
+DROP TABLE IF EXISTS RAC_organization_stale_lead_settings;
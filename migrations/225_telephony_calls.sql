@@ -0,0 +1,51 @@
+-- +goose Up
+-- Telephony provider of an organization. The API token is stored encrypted
+-- with TELEPHONY_ENCRYPTION_KEY. The CDR webhook authenticates with a random
+-- token, looked up by its SHA-256 hash and kept encrypted to build the status
+-- callback URL of click-to-call calls.
+CREATE TABLE IF NOT EXISTS RAC_telephony_settings (
+  organization_id UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  provider TEXT NOT NULL CHECK (provider IN ('twilio', 'voipgrid')),
+  account_id TEXT NOT NULL,
+  api_token_encrypted TEXT NOT NULL,
+  caller_id TEXT,
+  webhook_token_hash TEXT NOT NULL UNIQUE,
+  webhook_token_encrypted TEXT NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  updated_by UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Calls placed through click-to-call or reported by the provider's CDR
+-- webhook, linked to the lead the remote number belongs to.
+CREATE TABLE IF NOT EXISTS RAC_telephony_calls (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  provider TEXT NOT NULL,
+  provider_call_id TEXT NOT NULL,
+  direction TEXT NOT NULL CHECK (direction IN ('inbound', 'outbound')),
+  status TEXT NOT NULL
+    CHECK (status IN ('initiated', 'ringing', 'answered', 'completed', 'missed', 'busy', 'failed')),
+  from_number TEXT NOT NULL DEFAULT '',
+  to_number TEXT NOT NULL DEFAULT '',
+  lead_id UUID REFERENCES RAC_leads(id) ON DELETE SET NULL,
+  lead_service_id UUID REFERENCES RAC_lead_services(id) ON DELETE SET NULL,
+  user_id UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+  started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  answered_at TIMESTAMPTZ,
+  ended_at TIMESTAMPTZ,
+  duration_seconds INTEGER,
+  recording_url TEXT,
+  timeline_logged BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (organization_id, provider, provider_call_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_telephony_calls_lead
+  ON RAC_telephony_calls(organization_id, lead_id, started_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_telephony_calls;
+DROP TABLE IF EXISTS RAC_telephony_settings;
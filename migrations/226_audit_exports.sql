@@ -0,0 +1,32 @@
+-- +goose Up
+-- Periodic compliance exports of audit logs, communications, auth events and
+-- API usage. Each run exports the period since last_exported_until as one
+-- gzip-compressed NDJSON object in the audit export bucket.
+CREATE TABLE IF NOT EXISTS RAC_organization_audit_export_settings (
+    organization_id     UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    enabled             BOOLEAN NOT NULL DEFAULT false,
+    cadence             TEXT NOT NULL DEFAULT 'weekly' CHECK (cadence IN ('daily', 'weekly', 'monthly')),
+    retention_days      INT NOT NULL DEFAULT 365 CHECK (retention_days >= 30),
+    last_exported_until TIMESTAMPTZ,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Export snapshots are removed, object and row, once they expire.
+CREATE TABLE IF NOT EXISTS RAC_audit_export_snapshots (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    period_start    TIMESTAMPTZ NOT NULL,
+    period_end      TIMESTAMPTZ NOT NULL,
+    object_key      TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    record_counts   JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_export_snapshots_org ON RAC_audit_export_snapshots(organization_id, period_end DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_export_snapshots_expires ON RAC_audit_export_snapshots(expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_audit_export_snapshots;
+DROP TABLE IF EXISTS RAC_organization_audit_export_settings;
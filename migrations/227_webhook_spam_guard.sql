@@ -0,0 +1,36 @@
+-- +goose Up
+-- Spam guard for webhook form capture. Submissions are scored before a lead is
+-- created; depending on the organization's policy likely junk is only flagged
+-- on the new lead or held back in a review queue instead.
+CREATE TABLE IF NOT EXISTS RAC_webhook_spam_policies (
+    organization_id       UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    mode                  TEXT NOT NULL DEFAULT 'flag' CHECK (mode IN ('off', 'flag', 'auto_junk')),
+    junk_threshold        INT NOT NULL DEFAULT 70 CHECK (junk_threshold BETWEEN 1 AND 100),
+    honeypot_field        TEXT,
+    blocked_email_domains TEXT[] NOT NULL DEFAULT '{}',
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Submissions held back as junk. Releasing one creates the lead from the
+-- stored form data; dismissing one keeps it out of the pipeline for good.
+CREATE TABLE IF NOT EXISTS RAC_webhook_spam_reviews (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    api_key_id      UUID REFERENCES RAC_webhook_api_keys(id) ON DELETE SET NULL,
+    source_domain   TEXT NOT NULL DEFAULT '',
+    fields          JSONB NOT NULL DEFAULT '{}'::jsonb,
+    raw_json        JSONB,
+    spam_score      INT NOT NULL,
+    spam_reasons    TEXT[] NOT NULL DEFAULT '{}',
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'released', 'dismissed')),
+    lead_id         UUID REFERENCES RAC_leads(id) ON DELETE SET NULL,
+    reviewed_by     UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    reviewed_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_spam_reviews_org_status ON RAC_webhook_spam_reviews(organization_id, status, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_webhook_spam_reviews;
+DROP TABLE IF EXISTS RAC_webhook_spam_policies;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Geocode answers by normalized address, shared by all organizations. Rows
+-- without coordinates cache addresses no provider could resolve.
+CREATE TABLE IF NOT EXISTS RAC_geocode_cache (
+    address_key TEXT PRIMARY KEY,
+    latitude    DOUBLE PRECISION,
+    longitude   DOUBLE PRECISION,
+    label       TEXT,
+    provider    TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_geocode_cache_expires ON RAC_geocode_cache(expires_at);
+
+-- Requests per geocoding provider per UTC day. A provider that reports it is
+-- out of quota is skipped until throttled_until.
+CREATE TABLE IF NOT EXISTS RAC_geocode_provider_usage (
+    provider        TEXT NOT NULL,
+    usage_date      DATE NOT NULL,
+    request_count   INT NOT NULL DEFAULT 0,
+    throttled_until TIMESTAMPTZ,
+    PRIMARY KEY (provider, usage_date)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_geocode_provider_usage;
+DROP TABLE IF EXISTS RAC_geocode_cache;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Spatial index for partner matching. The location columns are derived from
+-- latitude/longitude so existing writers keep working unchanged.
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+ALTER TABLE RAC_partners
+    ADD COLUMN IF NOT EXISTS location geography(Point, 4326)
+    GENERATED ALWAYS AS (
+        CASE WHEN latitude IS NOT NULL AND longitude IS NOT NULL
+            THEN ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography
+        END
+    ) STORED;
+
+ALTER TABLE RAC_leads
+    ADD COLUMN IF NOT EXISTS location geography(Point, 4326)
+    GENERATED ALWAYS AS (
+        CASE WHEN latitude IS NOT NULL AND longitude IS NOT NULL
+            THEN ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography
+        END
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_partners_location ON RAC_partners USING GIST (location);
+CREATE INDEX IF NOT EXISTS idx_leads_location ON RAC_leads USING GIST (location);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_leads_location;
+DROP INDEX IF EXISTS idx_partners_location;
+ALTER TABLE RAC_leads DROP COLUMN IF EXISTS location;
+ALTER TABLE RAC_partners DROP COLUMN IF EXISTS location;
+-- The postgis extension stays installed: other objects may depend on it.
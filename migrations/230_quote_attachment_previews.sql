@@ -0,0 +1,23 @@
+-- +goose Up
+-- Rendered previews of quote attachments, generated on first request and
+-- stored in object storage. Rows are keyed by the source file so a changed
+-- attachment (which always gets a new file key) never serves a stale preview.
+CREATE TABLE IF NOT EXISTS RAC_quote_attachment_previews (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL,
+    quote_id        UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    source          rac_quote_attachment_source NOT NULL,
+    file_key        TEXT NOT NULL,
+    variant         TEXT NOT NULL,
+    page            INT NOT NULL DEFAULT 0,
+    preview_key     TEXT NOT NULL,
+    content_type    TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_attachment_previews_variant
+    ON RAC_quote_attachment_previews (quote_id, source, file_key, variant, page);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_quote_attachment_previews;
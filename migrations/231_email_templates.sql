@@ -0,0 +1,29 @@
+-- +goose Up
+-- Organization overrides of the system emails (verification, password reset,
+-- invites). Every save adds a version; the override row selects the version
+-- that is sent. Without an override row the built-in template is used.
+CREATE TABLE IF NOT EXISTS RAC_email_template_versions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  template_key TEXT NOT NULL,
+  version INT NOT NULL CHECK (version > 0),
+  subject TEXT NOT NULL,
+  body_html TEXT NOT NULL,
+  created_by UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (organization_id, template_key, version)
+);
+
+CREATE TABLE IF NOT EXISTS RAC_email_template_overrides (
+  organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  template_key TEXT NOT NULL,
+  active_version INT NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (organization_id, template_key),
+  FOREIGN KEY (organization_id, template_key, active_version)
+    REFERENCES RAC_email_template_versions (organization_id, template_key, version) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_email_template_overrides;
+DROP TABLE IF EXISTS RAC_email_template_versions;
@@ -0,0 +1,58 @@
+-- +goose Up
+-- Organization-defined fields on leads and quotes, such as "frame material" or
+-- "building permit required". Definitions describe the type and validation of
+-- a field; the values live in a JSONB object on the lead or quote, keyed by
+-- field_key.
+CREATE TABLE IF NOT EXISTS RAC_custom_field_definitions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  entity TEXT NOT NULL CHECK (entity IN ('lead', 'quote')),
+  field_key TEXT NOT NULL CHECK (field_key ~ '^[a-z][a-z0-9_]{0,49}$'),
+  label TEXT NOT NULL,
+  field_type TEXT NOT NULL CHECK (field_type IN ('text', 'number', 'boolean', 'date', 'select')),
+  options TEXT[] NOT NULL DEFAULT '{}',
+  required BOOLEAN NOT NULL DEFAULT false,
+  max_length INT CHECK (max_length > 0),
+  min_value DOUBLE PRECISION,
+  max_value DOUBLE PRECISION,
+  position INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (organization_id, entity, field_key)
+);
+
+ALTER TABLE RAC_leads ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE RAC_quotes ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- List filters match values with @> containment.
+CREATE INDEX IF NOT EXISTS idx_leads_custom_fields ON RAC_leads USING GIN (custom_fields jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_quotes_custom_fields ON RAC_quotes USING GIN (custom_fields jsonb_path_ops);
+
+-- The values of a custom field object as one searchable text.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION rac_custom_fields_text(jsonb)
+RETURNS text
+LANGUAGE sql
+IMMUTABLE
+PARALLEL SAFE
+AS $$
+	SELECT coalesce(string_agg(value, ' '), '') FROM jsonb_each_text($1)
+$$;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS idx_leads_custom_fields_fts ON RAC_leads USING GIN (
+	to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(custom_fields)))
+);
+CREATE INDEX IF NOT EXISTS idx_quotes_custom_fields_fts ON RAC_quotes USING GIN (
+	to_tsvector('simple', rac_immutable_unaccent(rac_custom_fields_text(custom_fields)))
+);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_quotes_custom_fields_fts;
+DROP INDEX IF EXISTS idx_leads_custom_fields_fts;
+DROP FUNCTION IF EXISTS rac_custom_fields_text(jsonb);
+DROP INDEX IF EXISTS idx_quotes_custom_fields;
+DROP INDEX IF EXISTS idx_leads_custom_fields;
+ALTER TABLE RAC_quotes DROP COLUMN IF EXISTS custom_fields;
+ALTER TABLE RAC_leads DROP COLUMN IF EXISTS custom_fields;
+DROP TABLE IF EXISTS RAC_custom_field_definitions;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- Quote templates are named, reusable bundles of line items. A quantity may hold
+-- placeholders such as "{{breedte}} m" that are filled in when a draft quote is
+-- created from the template.
+CREATE TABLE IF NOT EXISTS RAC_quote_templates (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    name            TEXT NOT NULL,
+    description     TEXT,
+    pricing_mode    TEXT NOT NULL DEFAULT 'exclusive' CHECK (pricing_mode IN ('exclusive', 'inclusive')),
+    notes           TEXT,
+    created_by_id   UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS RAC_quote_template_items (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id        UUID NOT NULL REFERENCES RAC_quote_templates(id) ON DELETE CASCADE,
+    organization_id    UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    title              TEXT NOT NULL DEFAULT '',
+    description        TEXT NOT NULL,
+    quantity           TEXT NOT NULL,
+    unit_price_cents   BIGINT NOT NULL CHECK (unit_price_cents >= 0),
+    tax_rate_bps       INT NOT NULL DEFAULT 0,
+    is_optional        BOOLEAN NOT NULL DEFAULT false,
+    is_selected        BOOLEAN NOT NULL DEFAULT true,
+    catalog_product_id UUID REFERENCES RAC_catalog_products(id) ON DELETE SET NULL,
+    sort_order         INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_template_items_template ON RAC_quote_template_items(template_id, sort_order);
+
+-- Every draft quote created from a template is recorded for usage statistics.
+CREATE TABLE IF NOT EXISTS RAC_quote_template_usages (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id     UUID NOT NULL REFERENCES RAC_quote_templates(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    quote_id        UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    used_by_id      UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_template_usages_template ON RAC_quote_template_usages(template_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_quote_template_usages_template;
+DROP TABLE IF EXISTS RAC_quote_template_usages;
+DROP INDEX IF EXISTS idx_quote_template_items_template;
+DROP TABLE IF EXISTS RAC_quote_template_items;
+DROP TABLE IF EXISTS RAC_quote_templates;
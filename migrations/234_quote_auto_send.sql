@@ -0,0 +1,46 @@
+-- +goose Up
+-- Quote auto-send lets the estimator pipeline send AI-drafted quotes for
+-- low-risk services by itself after a review window, unless an agent intervenes.
+-- The policy is off by default; disabling it is the kill switch.
+CREATE TABLE IF NOT EXISTS RAC_quote_auto_send_policies (
+    organization_id       UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    enabled               BOOLEAN NOT NULL DEFAULT false,
+    review_window_minutes INT NOT NULL DEFAULT 60 CHECK (review_window_minutes >= 0),
+    min_lead_score        INT CHECK (min_lead_score BETWEEN 0 AND 100),
+    max_total_cents       BIGINT CHECK (max_total_cents >= 0),
+    service_type_ids      UUID[] NOT NULL DEFAULT '{}',
+    updated_by_id         UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Every auto-send decision is kept as the audit trail: quotes that were
+-- scheduled, skipped, cancelled by an agent or the policy, sent or failed.
+CREATE TABLE IF NOT EXISTS RAC_quote_auto_sends (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id   UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    quote_id          UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    review_id         UUID,
+    status            TEXT NOT NULL
+                      CHECK (status IN ('scheduled', 'processing', 'sent', 'cancelled', 'skipped', 'failed')),
+    reason            TEXT,
+    scheduled_for     TIMESTAMPTZ,
+    quote_total_cents BIGINT NOT NULL,
+    quote_row_version BIGINT NOT NULL,
+    lead_score        INT,
+    cancelled_by_id   UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    decided_at        TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_auto_sends_quote ON RAC_quote_auto_sends(quote_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_quote_auto_sends_due ON RAC_quote_auto_sends(scheduled_for) WHERE status = 'scheduled';
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quote_auto_sends_one_scheduled ON RAC_quote_auto_sends(quote_id) WHERE status = 'scheduled';
+
+-- +goose Down
+DROP INDEX IF EXISTS uq_quote_auto_sends_one_scheduled;
+DROP INDEX IF EXISTS idx_quote_auto_sends_due;
+DROP INDEX IF EXISTS idx_quote_auto_sends_quote;
+DROP TABLE IF EXISTS RAC_quote_auto_sends;
+DROP TABLE IF EXISTS RAC_quote_auto_send_policies;
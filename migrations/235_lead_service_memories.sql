@@ -0,0 +1,26 @@
+-- +goose Up
+-- Distilled facts that agents learned about a lead service in earlier runs,
+-- such as confirmed dimensions, customer preferences and rejected options.
+-- They are loaded into every new run so agents do not start context-free.
+-- Older entries are folded into a single summary entry when the list grows.
+CREATE TABLE IF NOT EXISTS RAC_lead_service_memories (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+  lead_id UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+  lead_service_id UUID NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('dimension', 'preference', 'rejected_option', 'fact', 'summary')),
+  content TEXT NOT NULL,
+  source TEXT NOT NULL,
+  run_id TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_service_memories_service ON RAC_lead_service_memories(lead_service_id, created_at);
+-- The same fact is stored once per lead service; repeating it refreshes it.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_lead_service_memories_content ON RAC_lead_service_memories(lead_service_id, kind, lower(content));
+
+-- +goose Down
+DROP INDEX IF EXISTS uq_lead_service_memories_content;
+DROP INDEX IF EXISTS idx_lead_service_memories_service;
+DROP TABLE IF EXISTS RAC_lead_service_memories;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- Review policy for AI actions. High-impact actions that match the policy wait
+-- in the agent_approvals queue until an agent approves or rejects them; when the
+-- SLA runs out the timeout action decides what happens to the pending action.
+-- Organizations without a policy keep the default confirmation rules.
+CREATE TABLE IF NOT EXISTS RAC_agent_review_policies (
+    organization_id          UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    enabled                  BOOLEAN NOT NULL DEFAULT true,
+    review_stage_moves       BOOLEAN NOT NULL DEFAULT true,
+    review_partner_offers    BOOLEAN NOT NULL DEFAULT true,
+    review_detail_overwrites BOOLEAN NOT NULL DEFAULT false,
+    sla_minutes              INT NOT NULL DEFAULT 5 CHECK (sla_minutes BETWEEN 1 AND 1440),
+    timeout_action           TEXT NOT NULL DEFAULT 'reject' CHECK (timeout_action IN ('reject', 'approve')),
+    updated_by_id            UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE agent_approvals
+    ADD COLUMN IF NOT EXISTS impact TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS timeout_action TEXT NOT NULL DEFAULT 'reject';
+
+CREATE INDEX IF NOT EXISTS idx_agent_approvals_expiry
+    ON agent_approvals (expires_at)
+    WHERE decision = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_agent_approvals_expiry;
+ALTER TABLE agent_approvals
+    DROP COLUMN IF EXISTS timeout_action,
+    DROP COLUMN IF EXISTS impact;
+DROP TABLE IF EXISTS RAC_agent_review_policies;
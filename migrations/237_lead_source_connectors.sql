@@ -0,0 +1,56 @@
+-- +goose Up
+-- Lead source connectors ingest leads from lead marketplaces, either pushed to
+-- a per-connector webhook URL or pulled from the marketplace API. The field
+-- mapping columns override the provider's default mapping per field.
+CREATE TABLE IF NOT EXISTS RAC_lead_source_connectors (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id        UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    provider               TEXT NOT NULL,
+    name                   TEXT NOT NULL,
+    mode                   TEXT NOT NULL CHECK (mode IN ('webhook', 'polling')),
+    is_active              BOOLEAN NOT NULL DEFAULT true,
+    secret_hash            TEXT NOT NULL UNIQUE,
+    secret_prefix          TEXT NOT NULL,
+    api_base_url           TEXT,
+    api_token_encrypted    TEXT,
+    poll_interval_minutes  INT NOT NULL DEFAULT 15 CHECK (poll_interval_minutes BETWEEN 5 AND 1440),
+    poll_cursor            TEXT,
+    last_polled_at         TIMESTAMPTZ,
+    last_error             TEXT,
+    field_mappings         JSONB NOT NULL DEFAULT '{}',
+    service_type_rules     JSONB NOT NULL DEFAULT '[]',
+    default_values         JSONB NOT NULL DEFAULT '{}',
+    duplicate_window_hours INT NOT NULL DEFAULT 720 CHECK (duplicate_window_hours BETWEEN 0 AND 8760),
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_source_connectors_org
+    ON RAC_lead_source_connectors (organization_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_lead_source_connectors_polling
+    ON RAC_lead_source_connectors (last_polled_at)
+    WHERE is_active AND mode = 'polling';
+
+-- One row per marketplace lead; the external ID is what suppresses redelivered
+-- and re-polled leads. Failed leads are retried when they come in again.
+CREATE TABLE IF NOT EXISTS RAC_lead_source_connector_leads (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connector_id    UUID NOT NULL REFERENCES RAC_lead_source_connectors(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    external_id     TEXT NOT NULL,
+    status          TEXT NOT NULL CHECK (status IN ('processing', 'created', 'duplicate', 'failed')),
+    lead_id         UUID REFERENCES RAC_leads(id) ON DELETE SET NULL,
+    payload         JSONB NOT NULL,
+    error           TEXT,
+    received_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (connector_id, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_source_connector_leads_connector
+    ON RAC_lead_source_connector_leads (connector_id, received_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_lead_source_connector_leads;
+DROP TABLE IF EXISTS RAC_lead_source_connectors;
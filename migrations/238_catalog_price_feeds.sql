@@ -0,0 +1,82 @@
+-- +goose Up
+-- Supplier price feeds keep catalog cost prices current. A feed is fetched from
+-- its source URL on an interval or uploaded by hand; rows are matched to products
+-- on the product reference.
+CREATE TABLE IF NOT EXISTS RAC_catalog_price_feeds (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id      UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    supplier_name        TEXT NOT NULL,
+    format               TEXT NOT NULL CHECK (format IN ('csv', 'edi', 'api')),
+    source_url           TEXT,
+    api_token_encrypted  TEXT,
+    sku_field            TEXT NOT NULL DEFAULT 'sku',
+    price_field          TEXT NOT NULL DEFAULT 'price',
+    csv_delimiter        TEXT NOT NULL DEFAULT ';',
+    fetch_interval_hours INT NOT NULL DEFAULT 168 CHECK (fetch_interval_hours BETWEEN 1 AND 720),
+    alert_threshold_bps  INT NOT NULL DEFAULT 1000 CHECK (alert_threshold_bps BETWEEN 0 AND 100000),
+    is_active            BOOLEAN NOT NULL DEFAULT true,
+    last_run_at          TIMESTAMPTZ,
+    last_error           TEXT,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_price_feeds_org
+    ON RAC_catalog_price_feeds (organization_id, supplier_name);
+
+-- Latest supplier cost per product; the feed that set it last wins.
+CREATE TABLE IF NOT EXISTS RAC_catalog_product_costs (
+    product_id      UUID PRIMARY KEY REFERENCES RAC_catalog_products(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    feed_id         UUID REFERENCES RAC_catalog_price_feeds(id) ON DELETE SET NULL,
+    supplier_sku    TEXT NOT NULL,
+    cost_cents      BIGINT NOT NULL CHECK (cost_cents >= 0),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Margin rules derive the sell price from the cost price. The most specific
+-- rule wins: feed and product type, then feed, then product type, then the
+-- organization default (both empty).
+CREATE TABLE IF NOT EXISTS RAC_catalog_price_margin_rules (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    feed_id         UUID REFERENCES RAC_catalog_price_feeds(id) ON DELETE CASCADE,
+    product_type    TEXT CHECK (product_type IN ('digital_service', 'service', 'product', 'material')),
+    margin_bps      INT NOT NULL CHECK (margin_bps BETWEEN 0 AND 100000),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_catalog_price_margin_rules_scope
+    ON RAC_catalog_price_margin_rules (
+        organization_id,
+        COALESCE(feed_id, '00000000-0000-0000-0000-000000000000'::uuid),
+        COALESCE(product_type, '')
+    );
+
+CREATE TABLE IF NOT EXISTS RAC_catalog_price_changes (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id   UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    product_id        UUID NOT NULL REFERENCES RAC_catalog_products(id) ON DELETE CASCADE,
+    feed_id           UUID REFERENCES RAC_catalog_price_feeds(id) ON DELETE SET NULL,
+    supplier_sku      TEXT NOT NULL,
+    old_cost_cents    BIGINT,
+    new_cost_cents    BIGINT NOT NULL,
+    old_price_cents   BIGINT NOT NULL,
+    new_price_cents   BIGINT NOT NULL,
+    change_bps        INT,
+    exceeds_threshold BOOLEAN NOT NULL DEFAULT false,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_price_changes_product
+    ON RAC_catalog_price_changes (product_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_price_changes_feed
+    ON RAC_catalog_price_changes (feed_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_catalog_price_changes;
+DROP TABLE IF EXISTS RAC_catalog_price_margin_rules;
+DROP TABLE IF EXISTS RAC_catalog_product_costs;
+DROP TABLE IF EXISTS RAC_catalog_price_feeds;
@@ -0,0 +1,49 @@
+-- +goose Up
+-- Optional stock tracking: a product is tracked once it has a stock row.
+CREATE TABLE IF NOT EXISTS RAC_catalog_product_stock (
+    product_id          UUID PRIMARY KEY REFERENCES RAC_catalog_products(id) ON DELETE CASCADE,
+    organization_id     UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    quantity            NUMERIC(12, 3) NOT NULL DEFAULT 0,
+    low_stock_threshold NUMERIC(12, 3) NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_product_stock_org
+    ON RAC_catalog_product_stock (organization_id);
+
+-- Every stock change. A quote consumes the stock of a product at most once,
+-- whichever event triggered the consumption.
+CREATE TABLE IF NOT EXISTS RAC_catalog_stock_movements (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    product_id      UUID NOT NULL REFERENCES RAC_catalog_products(id) ON DELETE CASCADE,
+    quantity_delta  NUMERIC(12, 3) NOT NULL,
+    quantity_after  NUMERIC(12, 3) NOT NULL,
+    reason          TEXT NOT NULL CHECK (reason IN ('adjustment', 'quote_accepted', 'partner_offer_accepted')),
+    quote_id        UUID REFERENCES RAC_quotes(id) ON DELETE SET NULL,
+    note            TEXT,
+    created_by      UUID,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_stock_movements_product
+    ON RAC_catalog_stock_movements (product_id, created_at DESC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_catalog_stock_movements_quote
+    ON RAC_catalog_stock_movements (product_id, quote_id)
+    WHERE quote_id IS NOT NULL;
+
+-- Which event consumes stock: the customer accepting the quote, or the partner
+-- accepting the job offer built from it. Organizations without a row use
+-- quote_accepted.
+CREATE TABLE IF NOT EXISTS RAC_catalog_stock_settings (
+    organization_id UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    consume_on      TEXT NOT NULL DEFAULT 'quote_accepted'
+                    CHECK (consume_on IN ('quote_accepted', 'partner_offer_accepted')),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_catalog_stock_settings;
+DROP TABLE IF EXISTS RAC_catalog_stock_movements;
+DROP TABLE IF EXISTS RAC_catalog_product_stock;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Organizations provisioned as a copy of another organization's configuration:
+-- demo organizations for sales and staging sandboxes for customers.
+CREATE TABLE IF NOT EXISTS RAC_organization_clones (
+    organization_id        UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    source_organization_id UUID REFERENCES RAC_organizations(id) ON DELETE SET NULL,
+    purpose                TEXT NOT NULL CHECK (purpose IN ('demo', 'sandbox')),
+    demo_leads             INT NOT NULL DEFAULT 0 CHECK (demo_leads >= 0),
+    created_by             UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_clones_source
+    ON RAC_organization_clones (source_organization_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_organization_clones;
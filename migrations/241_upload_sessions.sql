@@ -0,0 +1,41 @@
+-- +goose Up
+-- Resumable uploads of lead service and quote attachments. The API relays each
+-- part to an S3 multipart upload; abandoned sessions are aborted once expired.
+CREATE TABLE IF NOT EXISTS RAC_upload_sessions (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id   UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    target_type       TEXT NOT NULL CHECK (target_type IN ('lead_service', 'quote')),
+    target_id         UUID NOT NULL,
+    bucket            TEXT NOT NULL,
+    file_key          TEXT NOT NULL,
+    storage_upload_id TEXT NOT NULL,
+    file_name         TEXT NOT NULL,
+    content_type      TEXT NOT NULL,
+    size_bytes        BIGINT NOT NULL CHECK (size_bytes > 0),
+    part_size         BIGINT NOT NULL CHECK (part_size > 0),
+    checksum_sha256   TEXT CHECK (checksum_sha256 ~ '^[0-9a-f]{64}$'),
+    status            TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'aborted', 'expired')),
+    created_by        UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at        TIMESTAMPTZ NOT NULL,
+    completed_at      TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS RAC_upload_session_parts (
+    session_id  UUID NOT NULL REFERENCES RAC_upload_sessions(id) ON DELETE CASCADE,
+    part_number INT NOT NULL CHECK (part_number > 0),
+    etag        TEXT NOT NULL,
+    size_bytes  BIGINT NOT NULL CHECK (size_bytes > 0),
+    uploaded_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (session_id, part_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_target
+    ON RAC_upload_sessions(organization_id, target_type, target_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_expiry
+    ON RAC_upload_sessions(expires_at) WHERE status = 'active';
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_upload_session_parts;
+DROP TABLE IF EXISTS RAC_upload_sessions;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Web-optimized and thumbnail renditions of image attachments. A background
+-- sweep renders them into the attachments bucket next to the original.
+CREATE TABLE IF NOT EXISTS RAC_lead_attachment_renditions (
+    attachment_id   UUID NOT NULL REFERENCES RAC_lead_service_attachments(id) ON DELETE CASCADE,
+    variant         TEXT NOT NULL CHECK (variant IN ('web', 'thumbnail')),
+    organization_id UUID NOT NULL,
+    file_key        TEXT NOT NULL,
+    content_type    TEXT NOT NULL,
+    width           INT NOT NULL CHECK (width > 0),
+    height          INT NOT NULL CHECK (height > 0),
+    size_bytes      BIGINT NOT NULL CHECK (size_bytes > 0),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (attachment_id, variant)
+);
+
+-- Processing state per image attachment: processed, skipped (not renderable) or
+-- failed (retried until the attempt limit).
+CREATE TABLE IF NOT EXISTS RAC_lead_attachment_image_processing (
+    attachment_id UUID PRIMARY KEY REFERENCES RAC_lead_service_attachments(id) ON DELETE CASCADE,
+    status        TEXT NOT NULL CHECK (status IN ('processed', 'skipped', 'failed')),
+    attempts      INT NOT NULL DEFAULT 1,
+    last_error    TEXT,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_service_attachments_images
+    ON RAC_lead_service_attachments (created_at DESC)
+    WHERE lower(content_type) IN ('image/jpeg', 'image/jpg', 'image/png', 'image/gif');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_lead_service_attachments_images;
+DROP TABLE IF EXISTS RAC_lead_attachment_image_processing;
+DROP TABLE IF EXISTS RAC_lead_attachment_renditions;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Supports the periodic energy label refresh, which re-fetches the labels of
+-- leads whose label data is older than the refresh age.
+CREATE INDEX IF NOT EXISTS idx_leads_energy_label_fetched_at
+    ON RAC_leads (energy_label_fetched_at)
+    WHERE deleted_at IS NULL AND energy_label_fetched_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_leads_energy_label_fetched_at;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- PDOK/CBS enrichment results per postcode, shared across organizations. The
+-- statistics are postcode-level, so every lead in a postcode reuses one lookup.
+CREATE TABLE IF NOT EXISTS RAC_postcode_enrichment_cache (
+    postcode6  TEXT NOT NULL,
+    data_year  INT NOT NULL DEFAULT 0,
+    data       JSONB NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (postcode6, data_year)
+);
+
+CREATE INDEX IF NOT EXISTS idx_postcode_enrichment_cache_fetched
+    ON RAC_postcode_enrichment_cache (postcode6, fetched_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_postcode_enrichment_cache;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Progress of the backfills run through cmd/backfill, so an interrupted run
+-- resumes after the last completed batch.
+CREATE TABLE IF NOT EXISTS RAC_backfill_progress (
+    name              TEXT PRIMARY KEY,
+    cursor_created_at TIMESTAMPTZ NOT NULL,
+    cursor_id         UUID NOT NULL,
+    processed         INT NOT NULL DEFAULT 0,
+    updated           INT NOT NULL DEFAULT 0,
+    skipped           INT NOT NULL DEFAULT 0,
+    failed            INT NOT NULL DEFAULT 0,
+    started_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at      TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_backfill_progress;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Maintenance jobs started on demand by a superadmin and executed by the
+-- scheduler. Counters are updated while the job runs.
+CREATE TABLE IF NOT EXISTS RAC_maintenance_runs (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job                 TEXT NOT NULL,
+    params              JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status              TEXT NOT NULL DEFAULT 'queued'
+                        CHECK (status IN ('queued', 'running', 'completed', 'failed', 'cancelled')),
+    processed           INT NOT NULL DEFAULT 0,
+    updated             INT NOT NULL DEFAULT 0,
+    skipped             INT NOT NULL DEFAULT 0,
+    failed              INT NOT NULL DEFAULT 0,
+    error               TEXT,
+    requested_by        UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at          TIMESTAMPTZ,
+    finished_at         TIMESTAMPTZ,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One queued or running run per job.
+CREATE UNIQUE INDEX IF NOT EXISTS ux_maintenance_runs_active_job
+    ON RAC_maintenance_runs (job)
+    WHERE status IN ('queued', 'running');
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_runs_created_at
+    ON RAC_maintenance_runs (created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_maintenance_runs;
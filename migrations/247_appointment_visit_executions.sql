@@ -0,0 +1,25 @@
+-- +goose Up
+-- On-site execution of an appointment: the check-in on arrival and the
+-- check-out once the field report is captured, each with the reported GPS fix.
+CREATE TABLE IF NOT EXISTS RAC_appointment_visit_executions (
+    appointment_id        UUID PRIMARY KEY REFERENCES RAC_appointments(id) ON DELETE CASCADE,
+    organization_id       UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    checked_in_at         TIMESTAMPTZ NOT NULL,
+    checked_in_by         UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    check_in_latitude     DOUBLE PRECISION,
+    check_in_longitude    DOUBLE PRECISION,
+    check_in_accuracy_m   DOUBLE PRECISION,
+    checked_out_at        TIMESTAMPTZ,
+    checked_out_by        UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    check_out_latitude    DOUBLE PRECISION,
+    check_out_longitude   DOUBLE PRECISION,
+    check_out_accuracy_m  DOUBLE PRECISION,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointment_visit_executions_org
+    ON RAC_appointment_visit_executions (organization_id, checked_in_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_appointment_visit_executions;
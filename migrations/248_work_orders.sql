@@ -0,0 +1,80 @@
+-- +goose Up
+-- Execution of the work once a partner accepted the offer: planning, progress
+-- and the confirmation of the customer through a public token.
+CREATE TABLE IF NOT EXISTS RAC_work_orders (
+    id                    UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id       UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    lead_id               UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    lead_service_id       UUID NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+    partner_offer_id      UUID REFERENCES RAC_partner_offers(id) ON DELETE SET NULL,
+    partner_id            UUID REFERENCES RAC_partners(id) ON DELETE SET NULL,
+    quote_id              UUID REFERENCES RAC_quotes(id) ON DELETE SET NULL,
+    status                TEXT NOT NULL DEFAULT 'scheduled'
+                          CHECK (status IN ('scheduled', 'in_progress', 'on_hold', 'completed')),
+    planned_start_date    DATE,
+    planned_end_date      DATE,
+    hold_reason           TEXT,
+    started_at            TIMESTAMPTZ,
+    completed_at          TIMESTAMPTZ,
+    confirmation_token    TEXT UNIQUE,
+    customer_confirmed_at TIMESTAMPTZ,
+    customer_feedback     TEXT,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (planned_end_date IS NULL OR planned_start_date IS NULL OR planned_end_date >= planned_start_date)
+);
+
+-- One work order per accepted offer, so a replayed acceptance event is a no-op.
+CREATE UNIQUE INDEX IF NOT EXISTS ux_work_orders_partner_offer
+    ON RAC_work_orders (partner_offer_id)
+    WHERE partner_offer_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_work_orders_org_status
+    ON RAC_work_orders (organization_id, status, planned_start_date);
+
+CREATE INDEX IF NOT EXISTS idx_work_orders_lead_service
+    ON RAC_work_orders (lead_service_id);
+
+-- Steps of the work_order_completed trigger in existing default workflows,
+-- asking the customer to confirm the completed work.
+INSERT INTO RAC_workflow_steps (
+  organization_id,
+  workflow_id,
+  trigger,
+  channel,
+  audience,
+  action,
+  step_order,
+  delay_minutes,
+  enabled,
+  recipient_config,
+  template_subject,
+  template_body,
+  stop_on_reply
+)
+SELECT
+  w.organization_id,
+  w.id,
+  s.trigger,
+  s.channel,
+  s.audience,
+  'send_message',
+  s.step_order,
+  0,
+  TRUE,
+  s.recipient_config,
+  s.template_subject,
+  s.template_body,
+  FALSE
+FROM RAC_workflows w
+CROSS JOIN (
+  VALUES
+    ('work_order_completed', 'whatsapp', 'lead', 28, '{"includeLeadContact": true}'::jsonb, NULL::text, 'Hallo {{lead.name}}, de werkzaamheden voor {{lead.serviceType}} zijn afgerond. Wil je bevestigen dat alles naar wens is? {{links.confirm}}'::text),
+    ('work_order_completed', 'email', 'lead', 29, '{"includeLeadContact": true}'::jsonb, 'Bevestig de afgeronde werkzaamheden'::text, E'Hallo {{lead.name}},\n\nDe werkzaamheden voor {{lead.serviceType}} zijn afgerond. Wil je via onderstaande link bevestigen dat alles naar wens is uitgevoerd?\n\n{{links.confirm}}\n\nMet vriendelijke groet,\n{{org.name}}'::text)
+) AS s(trigger, channel, audience, step_order, recipient_config, template_subject, template_body)
+WHERE w.workflow_key = 'default'
+ON CONFLICT (workflow_id, trigger, channel, step_order) DO NOTHING;
+
+-- +goose Down
+DELETE FROM RAC_workflow_steps WHERE trigger = 'work_order_completed';
+DROP TABLE IF EXISTS RAC_work_orders;
@@ -0,0 +1,104 @@
+-- +goose Up
+-- Warranty terms per service type; a completed work order of a service type
+-- with terms gets a warranty.
+CREATE TABLE IF NOT EXISTS RAC_service_type_warranty_terms (
+    service_type_id  UUID PRIMARY KEY REFERENCES RAC_service_types(id) ON DELETE CASCADE,
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    duration_months  INT NOT NULL CHECK (duration_months BETWEEN 1 AND 600),
+    covered_items    TEXT[] NOT NULL DEFAULT '{}',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_type_warranty_terms_org
+    ON RAC_service_type_warranty_terms (organization_id);
+
+-- The warranty of a completed work order. The terms are copied so later
+-- changes to the service type do not alter warranties already given.
+CREATE TABLE IF NOT EXISTS RAC_warranties (
+    id                       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id          UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    lead_id                  UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    lead_service_id          UUID NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+    work_order_id            UUID NOT NULL UNIQUE REFERENCES RAC_work_orders(id) ON DELETE CASCADE,
+    starts_on                DATE NOT NULL,
+    expires_on               DATE NOT NULL,
+    covered_items            TEXT[] NOT NULL DEFAULT '{}',
+    expiry_reminder_sent_at  TIMESTAMPTZ,
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (expires_on > starts_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_warranties_expiry_reminder
+    ON RAC_warranties (expires_on)
+    WHERE expiry_reminder_sent_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_warranties_lead_service
+    ON RAC_warranties (lead_service_id);
+
+-- Claims submitted by the customer against a warranty, triaged by agents.
+CREATE TABLE IF NOT EXISTS RAC_warranty_claims (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    warranty_id      UUID NOT NULL REFERENCES RAC_warranties(id) ON DELETE CASCADE,
+    description      TEXT NOT NULL,
+    status           TEXT NOT NULL DEFAULT 'open'
+                     CHECK (status IN ('open', 'in_review', 'accepted', 'rejected', 'resolved')),
+    triage_note      TEXT,
+    triaged_by       UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    triaged_at       TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_warranty_claims_org_status
+    ON RAC_warranty_claims (organization_id, status, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_warranty_claims_warranty
+    ON RAC_warranty_claims (warranty_id);
+
+-- Steps of the warranty_expiring trigger in existing default workflows,
+-- offering maintenance to the customer before the warranty runs out.
+INSERT INTO RAC_workflow_steps (
+  organization_id,
+  workflow_id,
+  trigger,
+  channel,
+  audience,
+  action,
+  step_order,
+  delay_minutes,
+  enabled,
+  recipient_config,
+  template_subject,
+  template_body,
+  stop_on_reply
+)
+SELECT
+  w.organization_id,
+  w.id,
+  s.trigger,
+  s.channel,
+  s.audience,
+  'send_message',
+  s.step_order,
+  0,
+  TRUE,
+  s.recipient_config,
+  s.template_subject,
+  s.template_body,
+  FALSE
+FROM RAC_workflows w
+CROSS JOIN (
+  VALUES
+    ('warranty_expiring', 'whatsapp', 'lead', 30, '{"includeLeadContact": true}'::jsonb, NULL::text, 'Hallo {{lead.name}}, de garantie op {{lead.serviceType}} loopt af op {{warranty.expiresOn}}. Zullen we een onderhoudsbeurt inplannen? Reageer gerust op dit bericht.'::text),
+    ('warranty_expiring', 'email', 'lead', 31, '{"includeLeadContact": true}'::jsonb, 'Je garantie loopt binnenkort af'::text, E'Hallo {{lead.name}},\n\nDe garantie op {{lead.serviceType}} loopt af op {{warranty.expiresOn}}. Dit is een goed moment voor een onderhoudsbeurt of controle, zodat alles in goede staat blijft. Reageer op deze e-mail om een afspraak in te plannen.\n\nMet vriendelijke groet,\n{{org.name}}'::text)
+) AS s(trigger, channel, audience, step_order, recipient_config, template_subject, template_body)
+WHERE w.workflow_key = 'default'
+ON CONFLICT (workflow_id, trigger, channel, step_order) DO NOTHING;
+
+-- +goose Down
+DELETE FROM RAC_workflow_steps WHERE trigger = 'warranty_expiring';
+DROP TABLE IF EXISTS RAC_warranty_claims;
+DROP TABLE IF EXISTS RAC_warranties;
+DROP TABLE IF EXISTS RAC_service_type_warranty_terms;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Channel and purpose consent of a lead, managed by the consumer in the
+-- preference center. WhatsApp consent stays in RAC_leads.whatsapp_opted_in;
+-- a lead without a row here has given every other consent.
+CREATE TABLE IF NOT EXISTS RAC_lead_communication_consent (
+    lead_id                UUID PRIMARY KEY REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    organization_id        UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    email_allowed          BOOLEAN NOT NULL DEFAULT true,
+    phone_allowed          BOOLEAN NOT NULL DEFAULT true,
+    transactional_allowed  BOOLEAN NOT NULL DEFAULT true,
+    marketing_allowed      BOOLEAN NOT NULL DEFAULT true,
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Audit trail of every consent change, for accountability towards the consumer.
+CREATE TABLE IF NOT EXISTS RAC_lead_consent_changes (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    lead_id          UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    consent          TEXT NOT NULL CHECK (consent IN ('email', 'whatsapp', 'phone', 'transactional', 'marketing')),
+    granted          BOOLEAN NOT NULL,
+    source           TEXT NOT NULL CHECK (source IN ('preference_center', 'agent')),
+    actor_user_id    UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    ip_address       TEXT,
+    user_agent       TEXT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_consent_changes_lead
+    ON RAC_lead_consent_changes (lead_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_lead_consent_changes;
+DROP TABLE IF EXISTS RAC_lead_communication_consent;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Double opt-in for marketing messages: marketing consent only counts once
+-- the lead confirmed it through the link or a WhatsApp reply.
+ALTER TABLE RAC_leads
+    ADD COLUMN IF NOT EXISTS marketing_confirmation_token TEXT,
+    ADD COLUMN IF NOT EXISTS marketing_confirmation_requested_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS marketing_confirmed_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_leads_marketing_confirmation_token
+    ON RAC_leads (marketing_confirmation_token)
+    WHERE marketing_confirmation_token IS NOT NULL;
+
+ALTER TABLE RAC_lead_consent_changes DROP CONSTRAINT IF EXISTS rac_lead_consent_changes_source_check;
+ALTER TABLE RAC_lead_consent_changes ADD CONSTRAINT rac_lead_consent_changes_source_check
+    CHECK (source IN ('preference_center', 'agent', 'double_opt_in'));
+
+-- +goose Down
+DELETE FROM RAC_lead_consent_changes WHERE source = 'double_opt_in';
+ALTER TABLE RAC_lead_consent_changes DROP CONSTRAINT IF EXISTS rac_lead_consent_changes_source_check;
+ALTER TABLE RAC_lead_consent_changes ADD CONSTRAINT rac_lead_consent_changes_source_check
+    CHECK (source IN ('preference_center', 'agent'));
+
+DROP INDEX IF EXISTS idx_leads_marketing_confirmation_token;
+ALTER TABLE RAC_leads
+    DROP COLUMN IF EXISTS marketing_confirmed_at,
+    DROP COLUMN IF EXISTS marketing_confirmation_requested_at,
+    DROP COLUMN IF EXISTS marketing_confirmation_token;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Email addresses and phone numbers an organization must never contact, for
+-- example after a complaint. Checked before every outgoing notification.
+CREATE TABLE IF NOT EXISTS RAC_notification_suppressions (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    kind             TEXT NOT NULL CHECK (kind IN ('email', 'phone')),
+    value            TEXT NOT NULL,
+    reason           TEXT NOT NULL CHECK (reason IN ('manual', 'complaint', 'hard_bounce', 'opt_out')),
+    note             TEXT,
+    created_by       UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, kind, value)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_notification_suppressions;
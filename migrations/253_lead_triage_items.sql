@@ -0,0 +1,37 @@
+-- +goose Up
+-- Leads that need a human: blocked agent runs, failed enrichments, messages
+-- that could not be delivered and missed SLAs. A lead has at most one
+-- unresolved item of each kind; raising it again bumps the occurrences.
+CREATE TABLE IF NOT EXISTS RAC_lead_triage_items (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    lead_id          UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    lead_service_id  UUID REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+    kind             TEXT NOT NULL CHECK (kind IN ('manual_intervention', 'agent_run_blocked', 'enrichment_failed', 'message_undeliverable', 'sla_breach')),
+    priority         SMALLINT NOT NULL,
+    reason           TEXT NOT NULL,
+    reason_code      TEXT,
+    context          TEXT,
+    status           TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'claimed', 'resolved')),
+    assignee_id      UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    claimed_by       UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    claimed_at       TIMESTAMPTZ,
+    resolved_by      UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    resolved_at      TIMESTAMPTZ,
+    resolution_note  TEXT,
+    occurrences      INT NOT NULL DEFAULT 1,
+    last_raised_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lead_triage_items_unresolved
+    ON RAC_lead_triage_items (lead_id, kind)
+    WHERE status <> 'resolved';
+
+CREATE INDEX IF NOT EXISTS idx_lead_triage_items_queue
+    ON RAC_lead_triage_items (organization_id, priority, created_at)
+    WHERE status <> 'resolved';
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_lead_triage_items;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Lets interrupted AI quote jobs be recovered: the request is kept to queue
+-- the job again, the worker records a heartbeat while it runs, and attempts
+-- are counted so a job that keeps crashing the worker eventually fails.
+ALTER TABLE RAC_ai_quote_jobs
+    ADD COLUMN IF NOT EXISTS prompt TEXT,
+    ADD COLUMN IF NOT EXISTS existing_quote_id UUID,
+    ADD COLUMN IF NOT EXISTS force_regenerate BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS attempt_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS recovery_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_ai_quote_jobs_active_heartbeat
+ON RAC_ai_quote_jobs (status, GREATEST(heartbeat_at, updated_at))
+WHERE status IN ('pending', 'running');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ai_quote_jobs_active_heartbeat;
+ALTER TABLE RAC_ai_quote_jobs
+    DROP COLUMN IF EXISTS heartbeat_at,
+    DROP COLUMN IF EXISTS recovery_count,
+    DROP COLUMN IF EXISTS attempt_count,
+    DROP COLUMN IF EXISTS force_regenerate,
+    DROP COLUMN IF EXISTS existing_quote_id,
+    DROP COLUMN IF EXISTS prompt;
@@ -0,0 +1,69 @@
+-- +goose Up
+-- Named terms & conditions documents of an organization. The default document
+-- applies to every quote unless a service type or workflow override points to
+-- another document.
+CREATE TABLE IF NOT EXISTS RAC_quote_terms_documents (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    name             TEXT NOT NULL,
+    is_default       BOOLEAN NOT NULL DEFAULT false,
+    archived_at      TIMESTAMPTZ,
+    created_by       UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quote_terms_documents_name
+    ON RAC_quote_terms_documents (organization_id, lower(name))
+    WHERE archived_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quote_terms_documents_default
+    ON RAC_quote_terms_documents (organization_id)
+    WHERE is_default AND archived_at IS NULL;
+
+-- Versions are never changed; the version in effect at a moment is the latest
+-- one whose effective_from has passed.
+CREATE TABLE IF NOT EXISTS RAC_quote_terms_versions (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    document_id      UUID NOT NULL REFERENCES RAC_quote_terms_documents(id) ON DELETE CASCADE,
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    version_number   INT NOT NULL,
+    content          TEXT NOT NULL,
+    change_note      TEXT,
+    effective_from   TIMESTAMPTZ NOT NULL,
+    created_by       UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (document_id, version_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_terms_versions_effective
+    ON RAC_quote_terms_versions (document_id, effective_from DESC);
+
+-- Overrides pick the document for quotes of a service type or a workflow.
+CREATE TABLE IF NOT EXISTS RAC_quote_terms_overrides (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    document_id      UUID NOT NULL REFERENCES RAC_quote_terms_documents(id) ON DELETE CASCADE,
+    service_type     TEXT,
+    workflow_id      UUID REFERENCES RAC_workflows(id) ON DELETE CASCADE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((service_type IS NULL) <> (workflow_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quote_terms_overrides_service_type
+    ON RAC_quote_terms_overrides (organization_id, lower(service_type))
+    WHERE service_type IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quote_terms_overrides_workflow
+    ON RAC_quote_terms_overrides (organization_id, workflow_id)
+    WHERE workflow_id IS NOT NULL;
+
+-- The terms version a quote was sent with, embedded in its PDF.
+ALTER TABLE RAC_quotes
+    ADD COLUMN IF NOT EXISTS terms_version_id UUID REFERENCES RAC_quote_terms_versions(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE RAC_quotes DROP COLUMN IF EXISTS terms_version_id;
+DROP TABLE IF EXISTS RAC_quote_terms_overrides;
+DROP TABLE IF EXISTS RAC_quote_terms_versions;
+DROP TABLE IF EXISTS RAC_quote_terms_documents;
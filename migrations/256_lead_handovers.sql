@@ -0,0 +1,24 @@
+-- +goose Up
+-- Ownership transfers of leads between agents with the handover note the
+-- previous owner left. Leads moved together when an agent leaves the
+-- organization share a bulk_transfer_id.
+CREATE TABLE IF NOT EXISTS RAC_lead_handovers (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id   UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    lead_id           UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    from_agent_id     UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    to_agent_id       UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    note              TEXT NOT NULL,
+    transferred_by    UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    bulk_transfer_id  UUID,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_handovers_lead
+    ON RAC_lead_handovers (lead_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_lead_handovers_org_created
+    ON RAC_lead_handovers (organization_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_lead_handovers;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- Franchises: a head office with branches. The hierarchy is one level deep;
+-- head-office users get read access to their branches and branches inherit
+-- the head-office catalog.
+ALTER TABLE RAC_organizations
+    ADD COLUMN IF NOT EXISTS parent_organization_id UUID REFERENCES RAC_organizations(id) ON DELETE SET NULL;
+
+ALTER TABLE RAC_organizations
+    ADD CONSTRAINT organizations_parent_not_self CHECK (parent_organization_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_organizations_parent
+    ON RAC_organizations (parent_organization_id)
+    WHERE parent_organization_id IS NOT NULL;
+
+-- Branch copies of head-office catalog products. Copies follow the head-office
+-- product on every sync until the branch edits them (overridden_at). When the
+-- head-office product is deleted the link goes and the copy stays with the
+-- branch as its own product.
+CREATE TABLE IF NOT EXISTS RAC_catalog_inherited_products (
+    product_id         UUID PRIMARY KEY REFERENCES RAC_catalog_products(id) ON DELETE CASCADE,
+    organization_id    UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    source_product_id  UUID NOT NULL REFERENCES RAC_catalog_products(id) ON DELETE CASCADE,
+    overridden_at      TIMESTAMPTZ,
+    synced_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, source_product_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_catalog_inherited_products;
+DROP INDEX IF EXISTS idx_organizations_parent;
+ALTER TABLE RAC_organizations DROP CONSTRAINT IF EXISTS organizations_parent_not_self;
+ALTER TABLE RAC_organizations DROP COLUMN IF EXISTS parent_organization_id;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Custom domains for the public quote and lead portal. An organization proves
+-- it owns the domain with a DNS TXT record holding the verification token;
+-- portal links only use the domain once it is verified.
+CREATE TABLE IF NOT EXISTS RAC_organization_portal_domains (
+    organization_id     UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    domain              TEXT NOT NULL,
+    verification_token  TEXT NOT NULL,
+    verified_at         TIMESTAMPTZ,
+    last_checked_at     TIMESTAMPTZ,
+    last_error          TEXT,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_portal_domains_domain
+    ON RAC_organization_portal_domains (lower(domain));
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_organization_portal_domains;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Short links for the portal URLs in WhatsApp messages. A link expires with the
+-- token in its target URL; clicks are counted and written to the lead timeline.
+CREATE TABLE IF NOT EXISTS RAC_notification_short_links (
+    code             TEXT PRIMARY KEY,
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    lead_id          UUID,
+    service_id       UUID,
+    target_url       TEXT NOT NULL,
+    expires_at       TIMESTAMPTZ NOT NULL,
+    click_count      INTEGER NOT NULL DEFAULT 0,
+    last_clicked_at  TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_short_links_expires
+    ON RAC_notification_short_links (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_notification_short_links;
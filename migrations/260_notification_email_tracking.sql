@@ -0,0 +1,32 @@
+-- +goose Up
+-- Open and click tracking for outbox emails. Organizations opt in through their
+-- messaging policy; tracked emails get a tracking pixel and their links are
+-- wrapped in short links that point back to the outbox record.
+ALTER TABLE RAC_organization_messaging_policies
+    ADD COLUMN IF NOT EXISTS email_tracking_enabled BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE RAC_notification_short_links
+    ADD COLUMN IF NOT EXISTS outbox_id UUID REFERENCES RAC_notification_outbox(id) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS RAC_notification_email_tracking (
+    outbox_id         UUID PRIMARY KEY REFERENCES RAC_notification_outbox(id) ON DELETE CASCADE,
+    organization_id   UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    lead_id           UUID,
+    service_id        UUID,
+    category          TEXT NOT NULL DEFAULT '',
+    sent_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    open_count        INTEGER NOT NULL DEFAULT 0,
+    first_opened_at   TIMESTAMPTZ,
+    last_opened_at    TIMESTAMPTZ,
+    click_count       INTEGER NOT NULL DEFAULT 0,
+    first_clicked_at  TIMESTAMPTZ,
+    last_clicked_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_email_tracking_org_sent
+    ON RAC_notification_email_tracking (organization_id, sent_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_notification_email_tracking;
+ALTER TABLE RAC_notification_short_links DROP COLUMN IF EXISTS outbox_id;
+ALTER TABLE RAC_organization_messaging_policies DROP COLUMN IF EXISTS email_tracking_enabled;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- The workflow and step that produced each outbox message, for workflow
+-- analytics. Workflow and step IDs are kept without foreign keys so the
+-- history survives editing or deleting a workflow.
+CREATE TABLE IF NOT EXISTS RAC_workflow_messages (
+    outbox_id        UUID PRIMARY KEY REFERENCES RAC_notification_outbox(id) ON DELETE CASCADE,
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    workflow_id      UUID NOT NULL,
+    step_id          UUID NOT NULL,
+    lead_id          UUID,
+    channel          TEXT NOT NULL,
+    trigger          TEXT NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_messages_org_created
+    ON RAC_workflow_messages (organization_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_workflow_messages;
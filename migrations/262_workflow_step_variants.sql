@@ -0,0 +1,13 @@
+-- +goose Up
+-- A/B template variants of a workflow step: a JSON array of
+-- {key, weight, templateSubject, templateBody}. Each lead is assigned one
+-- variant deterministically; the assigned key is kept with the sent message.
+ALTER TABLE RAC_workflow_steps
+    ADD COLUMN IF NOT EXISTS template_variants JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+ALTER TABLE RAC_workflow_messages
+    ADD COLUMN IF NOT EXISTS variant_key TEXT;
+
+-- +goose Down
+ALTER TABLE RAC_workflow_messages DROP COLUMN IF EXISTS variant_key;
+ALTER TABLE RAC_workflow_steps DROP COLUMN IF EXISTS template_variants;
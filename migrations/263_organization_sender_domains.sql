@@ -0,0 +1,20 @@
+-- +goose Up
+-- DNS checks of the domain tenant SMTP sends from. Tenant SMTP is only enabled
+-- when SPF, DKIM and DMARC pass, unless an admin overrides the check.
+CREATE TABLE IF NOT EXISTS RAC_organization_sender_domains (
+    organization_id  UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    domain           TEXT NOT NULL,
+    dkim_selector    TEXT,
+    spf_status       TEXT NOT NULL,
+    dkim_status      TEXT NOT NULL,
+    dmarc_status     TEXT NOT NULL,
+    warnings         TEXT[] NOT NULL DEFAULT '{}',
+    verified_at      TIMESTAMPTZ,
+    override_at      TIMESTAMPTZ,
+    last_checked_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_organization_sender_domains;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Domain events written in the same transaction as the state change they
+-- describe, and published to the event bus after commit by the dispatcher.
+-- Published events keep only their envelope.
+CREATE TABLE IF NOT EXISTS RAC_domain_event_outbox (
+    id               UUID PRIMARY KEY,
+    event_name       TEXT NOT NULL,
+    payload          JSONB NOT NULL,
+    occurred_at      TIMESTAMPTZ NOT NULL,
+    attempts         INT NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until     TIMESTAMPTZ,
+    last_error       TEXT,
+    published_at     TIMESTAMPTZ,
+    dead_at          TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_event_outbox_pending
+    ON RAC_domain_event_outbox (next_attempt_at)
+    WHERE published_at IS NULL AND dead_at IS NULL;
+
+-- The handlers that completed a stored event, so redeliveries skip them.
+CREATE TABLE IF NOT EXISTS RAC_domain_event_receipts (
+    event_id      UUID NOT NULL,
+    handler       TEXT NOT NULL,
+    processed_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, handler)
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_event_receipts_processed
+    ON RAC_domain_event_receipts (processed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_domain_event_receipts;
+DROP TABLE IF EXISTS RAC_domain_event_outbox;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Optional idempotency key of a timeline write, unique per lead. Retried
+-- event handlers and agent tools pass the same key and get the existing row.
+ALTER TABLE lead_timeline_events ADD COLUMN IF NOT EXISTS dedup_key TEXT;
+
+-- Partner offer events are written once per offer. Earlier duplicates stay in
+-- the timeline; only the first of each gets the key, so the index can be built
+-- without deleting history.
+UPDATE lead_timeline_events e
+SET dedup_key = e.event_type || ':' || (e.metadata->>'offerId')
+WHERE e.event_type IN ('partner_offer_created', 'partner_offer_accepted', 'partner_offer_rejected', 'partner_offer_expired')
+  AND e.metadata->>'offerId' IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM lead_timeline_events first
+      WHERE first.lead_id = e.lead_id
+        AND first.event_type = e.event_type
+        AND first.metadata->>'offerId' = e.metadata->>'offerId'
+        AND (first.created_at, first.id) < (e.created_at, e.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_timeline_dedup_key
+    ON lead_timeline_events (lead_id, dedup_key)
+    WHERE dedup_key IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_timeline_dedup_key;
+ALTER TABLE lead_timeline_events DROP COLUMN IF EXISTS dedup_key;
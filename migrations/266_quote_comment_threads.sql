@@ -0,0 +1,27 @@
+-- +goose Up
+-- Comments on a quote as a whole. Comments on a line item stay in
+-- RAC_quote_annotations; together they form the quote's comment thread.
+CREATE TABLE IF NOT EXISTS RAC_quote_comments (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    quote_id         UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    author_type      TEXT NOT NULL CHECK (author_type IN ('customer', 'agent')),
+    author_id        UUID,
+    text             TEXT NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_comments_quote
+    ON RAC_quote_comments (quote_id, created_at);
+
+-- Until when each party read the thread, for unread indicators.
+CREATE TABLE IF NOT EXISTS RAC_quote_thread_reads (
+    quote_id      UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    party         TEXT NOT NULL CHECK (party IN ('customer', 'agent')),
+    last_read_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (quote_id, party)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_quote_thread_reads;
+DROP TABLE IF EXISTS RAC_quote_comments;
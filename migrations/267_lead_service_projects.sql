@@ -0,0 +1,56 @@
+-- +goose Up
+-- A project groups the services of one lead that form a single job, e.g. a
+-- dakkapel with the roofing and painting it needs.
+CREATE TABLE IF NOT EXISTS RAC_lead_service_projects (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    lead_id          UUID NOT NULL REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    name             TEXT NOT NULL,
+    created_by       UUID,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_service_projects_lead
+    ON RAC_lead_service_projects (organization_id, lead_id);
+
+-- A service belongs to at most one project; position is its place in the
+-- execution order.
+CREATE TABLE IF NOT EXISTS RAC_lead_service_project_services (
+    lead_service_id  UUID PRIMARY KEY REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+    project_id       UUID NOT NULL REFERENCES RAC_lead_service_projects(id) ON DELETE CASCADE,
+    position         INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_service_project_services_project
+    ON RAC_lead_service_project_services (project_id, position);
+
+-- lead_service_id can only start once depends_on_service_id is done.
+CREATE TABLE IF NOT EXISTS RAC_lead_service_dependencies (
+    lead_service_id        UUID NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+    depends_on_service_id  UUID NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+    project_id             UUID NOT NULL REFERENCES RAC_lead_service_projects(id) ON DELETE CASCADE,
+    PRIMARY KEY (lead_service_id, depends_on_service_id),
+    CHECK (lead_service_id <> depends_on_service_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_service_dependencies_project
+    ON RAC_lead_service_dependencies (project_id);
+
+-- Services a quote covers besides its own lead_service_id, so one quote can
+-- price a whole project.
+CREATE TABLE IF NOT EXISTS RAC_quote_covered_services (
+    quote_id         UUID NOT NULL REFERENCES RAC_quotes(id) ON DELETE CASCADE,
+    lead_service_id  UUID NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    PRIMARY KEY (quote_id, lead_service_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_covered_services_service
+    ON RAC_quote_covered_services (lead_service_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_quote_covered_services;
+DROP TABLE IF EXISTS RAC_lead_service_dependencies;
+DROP TABLE IF EXISTS RAC_lead_service_project_services;
+DROP TABLE IF EXISTS RAC_lead_service_projects;
@@ -0,0 +1,37 @@
+-- +goose Up
+-- Price agreements with sub-contractors. The vakman price of an offer created
+-- from a quote is derived from the agreement in effect for the partner and
+-- the service type. A renegotiation ends the previous agreement and links it
+-- to its successor, so the rows of a partner form its pricing history.
+CREATE TABLE IF NOT EXISTS RAC_partner_price_agreements (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    partner_id       UUID NOT NULL REFERENCES RAC_partners(id) ON DELETE CASCADE,
+    -- NULL applies to every service type without an agreement of its own.
+    service_type_id  UUID REFERENCES RAC_service_types(id) ON DELETE CASCADE,
+    pricing_model    TEXT NOT NULL CHECK (pricing_model IN ('percentage', 'rate_card', 'item_prices')),
+    -- Share of the customer price the partner receives; for rate cards and
+    -- item prices it applies to items without a rate.
+    percentage_bps   INT NOT NULL CHECK (percentage_bps BETWEEN 0 AND 10000),
+    rates            JSONB NOT NULL DEFAULT '[]'::jsonb,
+    effective_from   DATE NOT NULL,
+    effective_to     DATE,
+    notes            TEXT,
+    superseded_by    UUID REFERENCES RAC_partner_price_agreements(id) ON DELETE SET NULL,
+    created_by       UUID,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_price_agreements_partner
+    ON RAC_partner_price_agreements (organization_id, partner_id, effective_from DESC);
+
+-- The agreement an offer was priced with.
+CREATE TABLE IF NOT EXISTS RAC_partner_offer_price_agreements (
+    offer_id      UUID PRIMARY KEY REFERENCES RAC_partner_offers(id) ON DELETE CASCADE,
+    agreement_id  UUID NOT NULL REFERENCES RAC_partner_price_agreements(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_partner_offer_price_agreements;
+DROP TABLE IF EXISTS RAC_partner_price_agreements;
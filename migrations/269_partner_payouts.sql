@@ -0,0 +1,86 @@
+-- +goose Up
+-- Partner payouts. A commission is recorded per accepted offer once the
+-- customer confirmed the work: what the customer pays, what the partner
+-- earns, and the lead fee charged to the partner under its price agreement.
+-- Monthly statements collect the open commissions and adjustments of a
+-- partner; their PDF and accounting export are kept on the statement.
+ALTER TABLE RAC_partner_price_agreements
+    ADD COLUMN IF NOT EXISTS lead_fee_cents BIGINT NOT NULL DEFAULT 0 CHECK (lead_fee_cents >= 0);
+
+CREATE TABLE IF NOT EXISTS RAC_partner_payout_statements (
+    id                    UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id       UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    partner_id            UUID NOT NULL REFERENCES RAC_partners(id) ON DELETE CASCADE,
+    statement_number      TEXT NOT NULL,
+    period_start          DATE NOT NULL,
+    period_end            DATE NOT NULL,
+    earnings_cents        BIGINT NOT NULL DEFAULT 0,
+    lead_fees_cents       BIGINT NOT NULL DEFAULT 0,
+    adjustments_cents     BIGINT NOT NULL DEFAULT 0,
+    payout_cents          BIGINT NOT NULL DEFAULT 0,
+    pdf_file_key          TEXT,
+    created_by            UUID,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, partner_id, period_start),
+    UNIQUE (organization_id, statement_number),
+    CHECK (period_end >= period_start)
+);
+
+CREATE TABLE IF NOT EXISTS RAC_partner_commissions (
+    id                    UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id       UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    partner_id            UUID NOT NULL REFERENCES RAC_partners(id) ON DELETE CASCADE,
+    partner_offer_id      UUID NOT NULL UNIQUE REFERENCES RAC_partner_offers(id) ON DELETE CASCADE,
+    work_order_id         UUID REFERENCES RAC_work_orders(id) ON DELETE SET NULL,
+    lead_service_id       UUID NOT NULL REFERENCES RAC_lead_services(id) ON DELETE CASCADE,
+    agreement_id          UUID REFERENCES RAC_partner_price_agreements(id) ON DELETE SET NULL,
+    customer_price_cents  BIGINT NOT NULL,
+    vakman_price_cents    BIGINT NOT NULL,
+    -- What the organization keeps: the customer price minus the vakman price.
+    commission_cents      BIGINT NOT NULL,
+    lead_fee_cents        BIGINT NOT NULL DEFAULT 0,
+    -- What the partner is paid: the vakman price minus the lead fee.
+    payout_cents          BIGINT NOT NULL,
+    earned_at             TIMESTAMPTZ NOT NULL,
+    statement_id          UUID REFERENCES RAC_partner_payout_statements(id) ON DELETE SET NULL,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_commissions_open
+    ON RAC_partner_commissions (organization_id, partner_id, earned_at)
+    WHERE statement_id IS NULL;
+
+-- Manual corrections on the payout of a partner, e.g. material costs or a
+-- penalty. Positive amounts are paid to the partner, negative ones withheld.
+CREATE TABLE IF NOT EXISTS RAC_partner_payout_adjustments (
+    id                    UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id       UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    partner_id            UUID NOT NULL REFERENCES RAC_partners(id) ON DELETE CASCADE,
+    amount_cents          BIGINT NOT NULL CHECK (amount_cents <> 0),
+    description           TEXT NOT NULL,
+    effective_date        DATE NOT NULL,
+    statement_id          UUID REFERENCES RAC_partner_payout_statements(id) ON DELETE SET NULL,
+    created_by            UUID,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_payout_adjustments_open
+    ON RAC_partner_payout_adjustments (organization_id, partner_id, effective_date)
+    WHERE statement_id IS NULL;
+
+CREATE TABLE IF NOT EXISTS RAC_partner_payout_statement_exports (
+    statement_id          UUID NOT NULL REFERENCES RAC_partner_payout_statements(id) ON DELETE CASCADE,
+    organization_id       UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    provider              TEXT NOT NULL,
+    external_id           TEXT NOT NULL,
+    external_url          TEXT,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (statement_id, provider)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_partner_payout_statement_exports;
+DROP TABLE IF EXISTS RAC_partner_payout_adjustments;
+DROP TABLE IF EXISTS RAC_partner_commissions;
+DROP TABLE IF EXISTS RAC_partner_payout_statements;
+ALTER TABLE RAC_partner_price_agreements DROP COLUMN IF EXISTS lead_fee_cents;
@@ -0,0 +1,59 @@
+-- +goose Up
+-- Platform billing. Plans carry the limits of an organization (NULL is
+-- unlimited); an organization has at most one subscription, paid through
+-- Stripe or Mollie or assigned by a superadmin. Usage of the metered limits
+-- is counted per calendar month.
+CREATE TABLE IF NOT EXISTS RAC_billing_plans (
+    code                    TEXT PRIMARY KEY,
+    name                    TEXT NOT NULL,
+    price_cents             BIGINT NOT NULL DEFAULT 0 CHECK (price_cents >= 0),
+    currency                TEXT NOT NULL DEFAULT 'EUR',
+    max_users               INT CHECK (max_users >= 0),
+    max_leads_per_month     INT CHECK (max_leads_per_month >= 0),
+    max_ai_runs_per_month   INT CHECK (max_ai_runs_per_month >= 0),
+    stripe_price_id         TEXT,
+    is_active               BOOLEAN NOT NULL DEFAULT true,
+    sort_order              INT NOT NULL DEFAULT 0,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO RAC_billing_plans (code, name, price_cents, max_users, max_leads_per_month, max_ai_runs_per_month, sort_order)
+VALUES
+    ('starter', 'Starter', 4900, 3, 100, 500, 10),
+    ('growth', 'Growth', 14900, 10, 500, 2500, 20),
+    ('scale', 'Scale', 39900, NULL, NULL, 10000, 30)
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS RAC_organization_subscriptions (
+    organization_id           UUID PRIMARY KEY REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    plan_code                 TEXT NOT NULL REFERENCES RAC_billing_plans(code),
+    status                    TEXT NOT NULL CHECK (status IN ('pending', 'active', 'past_due', 'canceled')),
+    provider                  TEXT CHECK (provider IN ('stripe', 'mollie')),
+    provider_customer_id      TEXT,
+    provider_subscription_id  TEXT,
+    current_period_end        TIMESTAMPTZ,
+    grace_until               TIMESTAMPTZ,
+    cancel_at_period_end      BOOLEAN NOT NULL DEFAULT false,
+    created_at                TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at                TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_subscriptions_provider
+    ON RAC_organization_subscriptions(provider, provider_subscription_id)
+    WHERE provider_subscription_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS RAC_billing_usage (
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    metric           TEXT NOT NULL CHECK (metric IN ('leads', 'ai_runs')),
+    period_start     DATE NOT NULL,
+    quantity         BIGINT NOT NULL DEFAULT 0,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, metric, period_start)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_billing_usage;
+DROP INDEX IF EXISTS idx_organization_subscriptions_provider;
+DROP TABLE IF EXISTS RAC_organization_subscriptions;
+DROP TABLE IF EXISTS RAC_billing_plans;
@@ -0,0 +1,46 @@
+-- +goose Up
+-- Per-source validation of webhook form submissions. A policy is attached to a
+-- webhook API key, like its field mapping, and checks the extracted lead
+-- fields. Failing submissions are rejected or parked in a quarantine queue.
+CREATE TABLE IF NOT EXISTS RAC_webhook_validation_policies (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    api_key_id       UUID NOT NULL REFERENCES RAC_webhook_api_keys(id) ON DELETE CASCADE,
+    required_fields  TEXT[] NOT NULL DEFAULT '{}',
+    pattern_rules    JSONB NOT NULL DEFAULT '[]'::jsonb,
+    postcode_format  TEXT,
+    phone_countries  TEXT[] NOT NULL DEFAULT '{}',
+    action           TEXT NOT NULL DEFAULT 'quarantine' CHECK (action IN ('reject', 'quarantine')),
+    is_active        BOOLEAN NOT NULL DEFAULT true,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT uq_webhook_validation_policies_api_key UNIQUE (api_key_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_validation_policies_org ON RAC_webhook_validation_policies(organization_id);
+
+-- Submissions that failed validation. Resubmitting one applies the corrections
+-- of an agent and creates the lead; dismissing one drops it.
+CREATE TABLE IF NOT EXISTS RAC_webhook_quarantine (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    api_key_id       UUID REFERENCES RAC_webhook_api_keys(id) ON DELETE SET NULL,
+    source_domain    TEXT NOT NULL DEFAULT '',
+    fields           JSONB NOT NULL DEFAULT '{}'::jsonb,
+    raw_json         JSONB,
+    extracted        JSONB NOT NULL DEFAULT '{}'::jsonb,
+    violations       JSONB NOT NULL DEFAULT '[]'::jsonb,
+    corrections      JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status           TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'resubmitted', 'dismissed')),
+    lead_id          UUID REFERENCES RAC_leads(id) ON DELETE SET NULL,
+    reviewed_by      UUID REFERENCES RAC_users(id) ON DELETE SET NULL,
+    reviewed_at      TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_quarantine_org_status ON RAC_webhook_quarantine(organization_id, status, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS RAC_webhook_quarantine;
+DROP INDEX IF EXISTS idx_webhook_validation_policies_org;
+DROP TABLE IF EXISTS RAC_webhook_validation_policies;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- Geocoding state per lead. Leads are geocoded in the background after they
+-- are created or their address changes; address_key is the normalized address
+-- the state belongs to, so an unchanged address is not geocoded twice. Failed
+-- lookups are retried with backoff at next_attempt_at until the attempts run out.
+CREATE TABLE IF NOT EXISTS RAC_lead_geocoding (
+    lead_id          UUID PRIMARY KEY REFERENCES RAC_leads(id) ON DELETE CASCADE,
+    organization_id  UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    address_key      TEXT NOT NULL,
+    status           TEXT NOT NULL CHECK (status IN ('resolved', 'not_found', 'retrying', 'failed')),
+    attempts         INT NOT NULL DEFAULT 0,
+    last_error       TEXT,
+    next_attempt_at  TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_geocoding_retry ON RAC_lead_geocoding(next_attempt_at) WHERE status = 'retrying';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_lead_geocoding_retry;
+DROP TABLE IF EXISTS RAC_lead_geocoding;
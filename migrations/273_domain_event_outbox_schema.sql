@@ -0,0 +1,8 @@
+-- +goose Up
+-- The data schema of the organization that stored an event, so its handlers
+-- run against that schema when the event is published. NULL is the shared
+-- public schema.
+ALTER TABLE RAC_domain_event_outbox ADD COLUMN IF NOT EXISTS schema_name TEXT;
+
+-- +goose Down
+ALTER TABLE RAC_domain_event_outbox DROP COLUMN IF EXISTS schema_name;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Email domains of SSO sign-in. Like portal domains, an organization proves it
+-- owns a domain with a DNS TXT record holding the verification token; SSO only
+-- applies to verified domains, and a domain is verified by one organization.
+CREATE TABLE IF NOT EXISTS RAC_organization_sso_domains (
+    organization_id     UUID NOT NULL REFERENCES RAC_organization_sso_configs(organization_id) ON DELETE CASCADE,
+    domain              TEXT NOT NULL,
+    verification_token  TEXT NOT NULL,
+    verified_at         TIMESTAMPTZ,
+    last_checked_at     TIMESTAMPTZ,
+    last_error          TEXT,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, domain)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_sso_domains_verified
+    ON RAC_organization_sso_domains (lower(domain))
+    WHERE verified_at IS NOT NULL;
+
+-- Domains configured so far were never proven, so they start unverified.
+INSERT INTO RAC_organization_sso_domains (organization_id, domain, verification_token)
+SELECT c.organization_id, d.domain, encode(gen_random_bytes(24), 'hex')
+FROM RAC_organization_sso_configs c, unnest(c.allowed_domains) AS d(domain)
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS idx_organization_sso_configs_domains;
+
+-- +goose Down
+CREATE INDEX IF NOT EXISTS idx_organization_sso_configs_domains
+  ON RAC_organization_sso_configs USING GIN (allowed_domains)
+  WHERE enabled;
+
+DROP TABLE IF EXISTS RAC_organization_sso_domains;
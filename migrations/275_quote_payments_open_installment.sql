@@ -0,0 +1,22 @@
+-- +goose Up
+-- At most one payment per installment can still be completed, so concurrent
+-- requests for the deposit link cannot each create a payment. Older open
+-- duplicates are canceled; Mollie lets them expire.
+UPDATE RAC_quote_payments p
+SET status = 'canceled', updated_at = now()
+WHERE p.installment_id IS NOT NULL
+  AND p.status IN ('open', 'pending', 'authorized')
+  AND EXISTS (
+    SELECT 1
+    FROM RAC_quote_payments newer
+    WHERE newer.installment_id = p.installment_id
+      AND newer.status IN ('open', 'pending', 'authorized')
+      AND (newer.created_at, newer.id) > (p.created_at, p.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_quote_payments_open_installment
+    ON RAC_quote_payments (installment_id)
+    WHERE installment_id IS NOT NULL AND status IN ('open', 'pending', 'authorized');
+
+-- +goose Down
+DROP INDEX IF EXISTS ux_quote_payments_open_installment;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- An approval covers the line items it was requested for, not just the total:
+-- swapping items at the same price needs a new approval. Approvals recorded
+-- before this have no hash and no longer cover their quote.
+ALTER TABLE RAC_quote_approvals
+    ADD COLUMN IF NOT EXISTS quote_items_hash TEXT;
+
+-- +goose Down
+ALTER TABLE RAC_quote_approvals
+    DROP COLUMN IF EXISTS quote_items_hash;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Providers such as Stripe deliver webhook events in no particular order. The
+-- creation time of the last applied event lets older events be ignored.
+ALTER TABLE RAC_organization_subscriptions
+    ADD COLUMN IF NOT EXISTS provider_event_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE RAC_organization_subscriptions
+    DROP COLUMN IF EXISTS provider_event_at;
@@ -0,0 +1,39 @@
+-- +goose Up
+-- Statement numbers come from a counter per organization and month instead of
+-- counting the statements, so concurrent runs cannot hand out one number
+-- twice. The counters continue from the numbers already issued.
+CREATE TABLE IF NOT EXISTS RAC_partner_payout_statement_counters (
+    organization_id UUID NOT NULL REFERENCES RAC_organizations(id) ON DELETE CASCADE,
+    period_start    DATE NOT NULL,
+    last_number     INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (organization_id, period_start)
+);
+
+INSERT INTO RAC_partner_payout_statement_counters (organization_id, period_start, last_number)
+SELECT organization_id, period_start, MAX(split_part(statement_number, '-', 3)::int)
+FROM RAC_partner_payout_statements
+GROUP BY organization_id, period_start
+ON CONFLICT DO NOTHING;
+
+-- Name the one-statement-per-month constraint so the repository can tell it
+-- apart from a clashing statement number.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    con TEXT;
+BEGIN
+    SELECT conname INTO con
+    FROM pg_constraint
+    WHERE conrelid = 'rac_partner_payout_statements'::regclass
+      AND pg_get_constraintdef(oid) = 'UNIQUE (organization_id, partner_id, period_start)';
+    IF con IS NOT NULL AND con <> 'ux_partner_payout_statements_period' THEN
+        EXECUTE format('ALTER TABLE RAC_partner_payout_statements RENAME CONSTRAINT %I TO ux_partner_payout_statements_period', con);
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+ALTER TABLE RAC_partner_payout_statements
+    RENAME CONSTRAINT ux_partner_payout_statements_period TO rac_partner_payout_statements_organization_id_partner_id_pe_key;
+DROP TABLE IF EXISTS RAC_partner_payout_statement_counters;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Credit note lines keep the parsed quantity next to the free-form text so the
+-- quantity credited per quote item can be summed and capped at the quoted one.
+ALTER TABLE RAC_quote_credit_note_items
+    ADD COLUMN IF NOT EXISTS quantity_numeric NUMERIC(12, 3) NOT NULL DEFAULT 1;
+
+UPDATE RAC_quote_credit_note_items
+SET quantity_numeric = replace(substring(btrim(quantity) FROM '^[0-9]+(?:[.,][0-9]+)?'), ',', '.')::numeric
+WHERE btrim(quantity) ~ '^[0-9]+(?:[.,][0-9]+)?'
+  AND replace(substring(btrim(quantity) FROM '^[0-9]+(?:[.,][0-9]+)?'), ',', '.')::numeric > 0;
+
+-- +goose Down
+ALTER TABLE RAC_quote_credit_note_items DROP COLUMN IF EXISTS quantity_numeric;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- Only changes a user can make bump row_version. Background writes such as
+-- agent counters, viewed_at, PDF keys and tokens no longer invalidate the
+-- version a client is holding. Quote edits bump the version in the update
+-- statement itself, because replacing only line items leaves the quote row
+-- unchanged.
+DROP TRIGGER IF EXISTS trg_leads_row_version ON RAC_leads;
+CREATE TRIGGER trg_leads_row_version
+    BEFORE UPDATE ON RAC_leads
+    FOR EACH ROW
+    WHEN ((OLD.consumer_first_name, OLD.consumer_last_name, OLD.consumer_phone, OLD.consumer_email, OLD.consumer_role,
+           OLD.address_street, OLD.address_house_number, OLD.address_zip_code, OLD.address_city,
+           OLD.latitude, OLD.longitude, OLD.assigned_agent_id, OLD.whatsapp_opted_in)
+          IS DISTINCT FROM
+          (NEW.consumer_first_name, NEW.consumer_last_name, NEW.consumer_phone, NEW.consumer_email, NEW.consumer_role,
+           NEW.address_street, NEW.address_house_number, NEW.address_zip_code, NEW.address_city,
+           NEW.latitude, NEW.longitude, NEW.assigned_agent_id, NEW.whatsapp_opted_in))
+    EXECUTE FUNCTION rac_bump_row_version();
+
+DROP TRIGGER IF EXISTS trg_lead_services_row_version ON RAC_lead_services;
+CREATE TRIGGER trg_lead_services_row_version
+    BEFORE UPDATE ON RAC_lead_services
+    FOR EACH ROW
+    WHEN ((OLD.status, OLD.pipeline_stage, OLD.service_type_id, OLD.consumer_note)
+          IS DISTINCT FROM
+          (NEW.status, NEW.pipeline_stage, NEW.service_type_id, NEW.consumer_note))
+    EXECUTE FUNCTION rac_bump_row_version();
+
+DROP TRIGGER IF EXISTS trg_quotes_row_version ON RAC_quotes;
+CREATE TRIGGER trg_quotes_row_version
+    BEFORE UPDATE ON RAC_quotes
+    FOR EACH ROW
+    WHEN ((OLD.status, OLD.subtotal_cents, OLD.discount_amount_cents, OLD.tax_total_cents, OLD.total_cents)
+          IS DISTINCT FROM
+          (NEW.status, NEW.subtotal_cents, NEW.discount_amount_cents, NEW.tax_total_cents, NEW.total_cents))
+    EXECUTE FUNCTION rac_bump_row_version();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_quotes_row_version ON RAC_quotes;
+CREATE TRIGGER trg_quotes_row_version
+    BEFORE UPDATE ON RAC_quotes
+    FOR EACH ROW EXECUTE FUNCTION rac_bump_row_version();
+
+DROP TRIGGER IF EXISTS trg_lead_services_row_version ON RAC_lead_services;
+CREATE TRIGGER trg_lead_services_row_version
+    BEFORE UPDATE ON RAC_lead_services
+    FOR EACH ROW EXECUTE FUNCTION rac_bump_row_version();
+
+DROP TRIGGER IF EXISTS trg_leads_row_version ON RAC_leads;
+CREATE TRIGGER trg_leads_row_version
+    BEFORE UPDATE ON RAC_leads
+    FOR EACH ROW EXECUTE FUNCTION rac_bump_row_version();
@@ -0,0 +1,41 @@
+-- +goose Up
+-- A domain belongs to the organization that verified it. Unverified claims no
+-- longer block other organizations and expire after claimed_at; verified
+-- domains are re-checked periodically and last_verified_at records the last
+-- check that found the TXT record.
+DROP INDEX IF EXISTS idx_organization_portal_domains_domain;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_portal_domains_domain
+    ON RAC_organization_portal_domains (lower(domain))
+    WHERE verified_at IS NOT NULL;
+
+ALTER TABLE RAC_organization_portal_domains
+    ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS last_verified_at TIMESTAMPTZ;
+
+UPDATE RAC_organization_portal_domains
+SET claimed_at = created_at,
+    last_verified_at = verified_at;
+
+CREATE INDEX IF NOT EXISTS idx_organization_portal_domains_unverified
+    ON RAC_organization_portal_domains (claimed_at)
+    WHERE verified_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_organization_portal_domains_unverified;
+ALTER TABLE RAC_organization_portal_domains
+    DROP COLUMN IF EXISTS last_verified_at,
+    DROP COLUMN IF EXISTS claimed_at;
+
+-- Unverified claims of a domain another organization also claimed cannot be
+-- kept under the full unique index.
+DELETE FROM RAC_organization_portal_domains d
+WHERE d.verified_at IS NULL
+  AND EXISTS (
+      SELECT 1 FROM RAC_organization_portal_domains other
+      WHERE lower(other.domain) = lower(d.domain)
+        AND other.organization_id <> d.organization_id
+        AND (other.verified_at IS NOT NULL OR other.created_at < d.created_at)
+  );
+DROP INDEX IF EXISTS idx_organization_portal_domains_domain;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_portal_domains_domain
+    ON RAC_organization_portal_domains (lower(domain));
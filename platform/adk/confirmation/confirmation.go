@@ -37,6 +37,10 @@ type Request struct {
 	DecidedAt   *time.Time
 	DecidedBy   *string
 	TenantID    uuid.UUID
+	LeadID      *uuid.UUID
+	ServiceID   *uuid.UUID
+	// Impact is the review policy category that sent the request to review.
+	Impact string
 }
 
 // tenantContextKey is the context key for propagating tenant ID into HITL wrappers.
@@ -53,6 +57,26 @@ func GetTenantID(ctx context.Context) (uuid.UUID, bool) {
 	return id, ok
 }
 
+// leadServiceContextKey is the context key for the lead service an agent run acts on.
+type leadServiceContextKey struct{}
+
+type leadServiceRef struct {
+	leadID    uuid.UUID
+	serviceID uuid.UUID
+}
+
+// WithLeadService returns a child context carrying the lead service an agent run
+// acts on, so approval requests can be linked to it in the review queue.
+func WithLeadService(ctx context.Context, leadID, serviceID uuid.UUID) context.Context {
+	return context.WithValue(ctx, leadServiceContextKey{}, leadServiceRef{leadID: leadID, serviceID: serviceID})
+}
+
+// GetLeadService extracts the lead service previously injected by WithLeadService.
+func GetLeadService(ctx context.Context) (leadID, serviceID uuid.UUID, ok bool) {
+	ref, ok := ctx.Value(leadServiceContextKey{}).(leadServiceRef)
+	return ref.leadID, ref.serviceID, ok
+}
+
 // Provider decides whether a tool execution requires human confirmation.
 type Provider interface {
 	// RequiresConfirmation evaluates the tool call and returns true if human
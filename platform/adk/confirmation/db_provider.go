@@ -3,10 +3,12 @@ package confirmation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -36,49 +38,110 @@ func (p *DBProvider) RegisterEvaluator(toolName string, eval func(args map[strin
 	p.base.RegisterEvaluator(toolName, eval)
 }
 
-// RequiresConfirmation delegates to the underlying ThresholdProvider.
+// RequiresConfirmation applies the review policy of the tenant in ctx and falls
+// back to the underlying ThresholdProvider for tools the policy does not cover.
 func (p *DBProvider) RequiresConfirmation(ctx context.Context, toolName string, args map[string]any) (bool, string, error) {
+	policy, ok, err := p.reviewPolicy(ctx)
+	if err != nil {
+		return false, "", err
+	}
+	if ok {
+		if need, _, reason, handled := policy.Evaluate(toolName, args); handled {
+			return need, reason, nil
+		}
+	}
 	return p.base.RequiresConfirmation(ctx, toolName, args)
 }
 
+// reviewPolicy loads the review policy of the tenant in ctx. ok is false when
+// the tenant is unknown or has not configured a policy.
+func (p *DBProvider) reviewPolicy(ctx context.Context) (ReviewPolicy, bool, error) {
+	tenantID, ok := GetTenantID(ctx)
+	if !ok || tenantID == uuid.Nil {
+		return ReviewPolicy{}, false, nil
+	}
+	var policy ReviewPolicy
+	var slaMinutes int
+	var timeoutAction string
+	err := p.pool.QueryRow(ctx, `
+		SELECT enabled, review_stage_moves, review_partner_offers, review_detail_overwrites, sla_minutes, timeout_action
+		FROM RAC_agent_review_policies
+		WHERE organization_id = $1
+	`, tenantID).Scan(&policy.Enabled, &policy.ReviewStageMoves, &policy.ReviewPartnerOffers,
+		&policy.ReviewDetailOverwrites, &slaMinutes, &timeoutAction)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ReviewPolicy{}, false, nil
+	}
+	if err != nil {
+		return ReviewPolicy{}, false, fmt.Errorf("hitl: load review policy failed: %w", err)
+	}
+	policy.SLA = time.Duration(slaMinutes) * time.Minute
+	policy.TimeoutAction = TimeoutAction(timeoutAction)
+	return policy, true, nil
+}
+
 // SubmitRequest inserts a confirmation request into the agent_approvals table.
+// Requests sent to review by the tenant's policy get its SLA as deadline and
+// its timeout action; all others expire after DefaultTimeout and are rejected.
 func (p *DBProvider) SubmitRequest(ctx context.Context, req Request) error {
+	timeout := p.DefaultTimeout
+	timeoutAction := TimeoutActionReject
+	policy, ok, err := p.reviewPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if need, impact, _, handled := policy.Evaluate(req.ToolName, req.Arguments); handled && need {
+			req.Impact = impact
+			timeout = policy.SLA
+			timeoutAction = policy.TimeoutAction
+		}
+	}
+
 	var expiresAt *time.Time
-	if p.DefaultTimeout > 0 {
-		t := req.RequestedAt.Add(p.DefaultTimeout)
+	if timeout > 0 {
+		t := req.RequestedAt.Add(timeout)
 		expiresAt = &t
 	}
-	_, err := p.pool.Exec(ctx, `
-		INSERT INTO agent_approvals (id, agent_name, tool_name, arguments_json, reason, requested_at, expires_at, decision, lead_id, service_id, tenant_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-	`, req.ID, req.AgentName, req.ToolName, mustMarshal(req.Arguments), req.Reason, req.RequestedAt, expiresAt, string(DecisionPending), nil, nil, req.TenantID)
+	_, err = p.pool.Exec(ctx, `
+		INSERT INTO agent_approvals (id, agent_name, tool_name, arguments_json, reason, requested_at, expires_at, decision, lead_id, service_id, tenant_id, impact, timeout_action)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, req.ID, req.AgentName, req.ToolName, mustMarshal(req.Arguments), req.Reason, req.RequestedAt, expiresAt, string(DecisionPending),
+		req.LeadID, req.ServiceID, req.TenantID, req.Impact, string(timeoutAction))
 	return err
 }
 
 // PollDecision blocks until the confirmation request reaches a terminal state
-// (approved, rejected, or expired).
+// (approved, rejected, or expired). An expired request resolves to approved
+// when its timeout action says so, so the action goes ahead without review.
 func (p *DBProvider) PollDecision(ctx context.Context, id uuid.UUID) (Decision, error) {
 	deadline, hasDeadline := ctx.Deadline()
 	ticker := time.NewTicker(p.PollInterval)
 	defer ticker.Stop()
 
 	for {
-		var decision string
+		var decision, timeoutAction string
 		var expiresAt *time.Time
 		err := p.pool.QueryRow(ctx, `
-			SELECT decision, expires_at FROM agent_approvals WHERE id = $1
-		`, id).Scan(&decision, &expiresAt)
+			SELECT decision, expires_at, timeout_action FROM agent_approvals WHERE id = $1
+		`, id).Scan(&decision, &expiresAt, &timeoutAction)
 		if err != nil {
 			return DecisionPending, fmt.Errorf("hitl: poll decision failed: %w", err)
 		}
 
+		if decision == string(DecisionExpired) {
+			return expiredDecision(TimeoutAction(timeoutAction)), nil
+		}
 		if decision != string(DecisionPending) {
 			return Decision(decision), nil
 		}
 
 		if expiresAt != nil && time.Now().After(*expiresAt) {
-			_, _ = p.pool.Exec(ctx, `UPDATE agent_approvals SET decision = $1 WHERE id = $2`, string(DecisionExpired), id)
-			return DecisionExpired, nil
+			_, _ = p.pool.Exec(ctx, `
+				UPDATE agent_approvals SET decision = $1, decided_at = now(), decided_by = $2
+				WHERE id = $3 AND decision = $4
+			`, string(DecisionExpired), ExpiredDecidedBy, id, string(DecisionPending))
+			return expiredDecision(TimeoutAction(timeoutAction)), nil
 		}
 
 		select {
@@ -92,6 +155,16 @@ func (p *DBProvider) PollDecision(ctx context.Context, id uuid.UUID) (Decision,
 	}
 }
 
+// ExpiredDecidedBy marks approvals that were resolved by their SLA running out.
+const ExpiredDecidedBy = "system:timeout"
+
+func expiredDecision(action TimeoutAction) Decision {
+	if action == TimeoutActionApprove {
+		return DecisionApproved
+	}
+	return DecisionExpired
+}
+
 func mustMarshal(v map[string]any) []byte {
 	b, _ := json.Marshal(v)
 	return b
@@ -74,6 +74,10 @@ func executeWithConfirmation[Out any](
 	if tenantID, ok := GetTenantID(ctx); ok {
 		req.TenantID = tenantID
 	}
+	if leadID, serviceID, ok := GetLeadService(ctx); ok {
+		req.LeadID = &leadID
+		req.ServiceID = &serviceID
+	}
 	if err := provider.SubmitRequest(ctx, req); err != nil {
 		return zero, fmt.Errorf("confirmation submission failed for %s: %w", toolName, err)
 	}
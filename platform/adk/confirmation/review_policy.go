@@ -0,0 +1,91 @@
+package confirmation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimeoutAction is what happens to a pending request when its review SLA runs out.
+type TimeoutAction string
+
+const (
+	TimeoutActionReject  TimeoutAction = "reject"
+	TimeoutActionApprove TimeoutAction = "approve"
+)
+
+// Impact categories of AI actions that an organization can send to review.
+const (
+	ImpactStageMove       = "stage_move"
+	ImpactPartnerOffer    = "partner_offer"
+	ImpactDetailOverwrite = "detail_overwrite"
+)
+
+// stagesPastEstimation are the pipeline stages that commit the customer to a
+// proposal or job; moving a service there is a high-impact action.
+var stagesPastEstimation = map[string]struct{}{
+	"Proposal":    {},
+	"Fulfillment": {},
+	"Completed":   {},
+}
+
+// detailMetaArgs are UpdateLeadDetails arguments that describe the call rather
+// than overwrite a lead field.
+var detailMetaArgs = map[string]struct{}{
+	"leadId":     {},
+	"reason":     {},
+	"confidence": {},
+	"_reasoning": {},
+}
+
+// ReviewPolicy is an organization's rule set for which AI actions wait for an
+// agent's review, how long the review may take and what happens afterwards.
+type ReviewPolicy struct {
+	Enabled                bool
+	ReviewStageMoves       bool
+	ReviewPartnerOffers    bool
+	ReviewDetailOverwrites bool
+	SLA                    time.Duration
+	TimeoutAction          TimeoutAction
+}
+
+// Evaluate classifies a tool call under the policy. handled is false when the
+// policy has no rule for the tool, in which case the default rules apply.
+func (p ReviewPolicy) Evaluate(toolName string, args map[string]any) (need bool, impact, reason string, handled bool) {
+	if !p.Enabled {
+		return false, "", "", false
+	}
+	switch toolName {
+	case "UpdatePipelineStage":
+		stage, _ := args["stage"].(string)
+		if _, ok := stagesPastEstimation[stage]; !ok || !p.ReviewStageMoves {
+			return false, "", "", true
+		}
+		return true, ImpactStageMove, fmt.Sprintf("moving the service to %s is past Estimation and needs review", stage), true
+	case "CreatePartnerOffer":
+		if !p.ReviewPartnerOffers {
+			return false, "", "", true
+		}
+		return true, ImpactPartnerOffer, "creating a partner offer needs review", true
+	case "UpdateLeadDetails":
+		fields := overwrittenDetailFields(args)
+		if len(fields) == 0 || !p.ReviewDetailOverwrites {
+			return false, "", "", true
+		}
+		return true, ImpactDetailOverwrite, fmt.Sprintf("overwriting lead details (%s) needs review", strings.Join(fields, ", ")), true
+	}
+	return false, "", "", false
+}
+
+func overwrittenDetailFields(args map[string]any) []string {
+	fields := make([]string, 0, len(args))
+	for key, value := range args {
+		if _, meta := detailMetaArgs[key]; meta || value == nil {
+			continue
+		}
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+	return fields
+}
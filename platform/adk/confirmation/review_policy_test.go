@@ -0,0 +1,54 @@
+package confirmation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReviewPolicyEvaluate(t *testing.T) {
+	policy := ReviewPolicy{
+		Enabled:                true,
+		ReviewStageMoves:       true,
+		ReviewPartnerOffers:    true,
+		ReviewDetailOverwrites: true,
+		SLA:                    10 * time.Minute,
+		TimeoutAction:          TimeoutActionReject,
+	}
+
+	tests := []struct {
+		name        string
+		policy      ReviewPolicy
+		tool        string
+		args        map[string]any
+		wantNeed    bool
+		wantImpact  string
+		wantHandled bool
+	}{
+		{name: "stage move past estimation", policy: policy, tool: "UpdatePipelineStage", args: map[string]any{"stage": "Proposal"}, wantNeed: true, wantImpact: ImpactStageMove, wantHandled: true},
+		{name: "stage move to estimation", policy: policy, tool: "UpdatePipelineStage", args: map[string]any{"stage": "Estimation"}, wantHandled: true},
+		{name: "partner offer", policy: policy, tool: "CreatePartnerOffer", args: map[string]any{"partnerId": "p"}, wantNeed: true, wantImpact: ImpactPartnerOffer, wantHandled: true},
+		{name: "detail overwrite", policy: policy, tool: "UpdateLeadDetails", args: map[string]any{"leadId": "l", "phone": "+31612345678", "reason": "typo"}, wantNeed: true, wantImpact: ImpactDetailOverwrite, wantHandled: true},
+		{name: "detail call without fields", policy: policy, tool: "UpdateLeadDetails", args: map[string]any{"leadId": "l", "confidence": 0.9}, wantHandled: true},
+		{name: "stage moves not reviewed", policy: ReviewPolicy{Enabled: true}, tool: "UpdatePipelineStage", args: map[string]any{"stage": "Fulfillment"}, wantHandled: true},
+		{name: "uncovered tool uses default rules", policy: policy, tool: "DraftQuote", args: map[string]any{}},
+		{name: "disabled policy uses default rules", policy: ReviewPolicy{ReviewPartnerOffers: true}, tool: "CreatePartnerOffer", args: map[string]any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			need, impact, _, handled := tt.policy.Evaluate(tt.tool, tt.args)
+			if need != tt.wantNeed || impact != tt.wantImpact || handled != tt.wantHandled {
+				t.Fatalf("Evaluate() = (%v, %q, %v), want (%v, %q, %v)", need, impact, handled, tt.wantNeed, tt.wantImpact, tt.wantHandled)
+			}
+		})
+	}
+}
+
+func TestExpiredDecisionFollowsTimeoutAction(t *testing.T) {
+	if got := expiredDecision(TimeoutActionApprove); got != DecisionApproved {
+		t.Fatalf("expiredDecision(approve) = %q, want %q", got, DecisionApproved)
+	}
+	if got := expiredDecision(TimeoutActionReject); got != DecisionExpired {
+		t.Fatalf("expiredDecision(reject) = %q, want %q", got, DecisionExpired)
+	}
+}
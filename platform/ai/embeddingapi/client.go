@@ -10,6 +10,16 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"portal_final_backend/platform/ai/llmrouter"
+	"portal_final_backend/platform/circuitbreaker"
+)
+
+const (
+	// usageProvider identifies the product embedding API in the AI usage ledger.
+	usageProvider = "product-embedding-api"
+	// defaultModel is the model served by the product embedding API.
+	defaultModel = "bge-m3"
 )
 
 // Client is an HTTP client for the product embedding API.
@@ -17,6 +27,7 @@ type Client struct {
 	baseURL    string
 	apiKey     string
 	collection string
+	model      string
 	httpClient *http.Client
 }
 
@@ -25,6 +36,7 @@ type Config struct {
 	BaseURL    string
 	APIKey     string
 	Collection string
+	Model      string // Recorded in the AI usage ledger. Defaults to defaultModel.
 	Timeout    time.Duration
 }
 
@@ -35,14 +47,28 @@ func NewClient(cfg Config) *Client {
 		timeout = 30 * time.Second
 	}
 
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		model = defaultModel
+	}
+
 	return &Client{
 		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
 		apiKey:     cfg.APIKey,
 		collection: strings.TrimSpace(cfg.Collection),
-		httpClient: &http.Client{Timeout: timeout},
+		model:      model,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: circuitbreaker.Transport(circuitbreaker.For(circuitbreaker.DependencyCatalogEmbeddings), nil),
+		},
 	}
 }
 
+// Model returns the embedding model the API is configured to serve.
+func (c *Client) Model() string {
+	return c.model
+}
+
 // AddDocumentsRequest is the request body for adding documents.
 type AddDocumentsRequest struct {
 	Documents  []map[string]any `json:"documents"`
@@ -101,5 +127,19 @@ func (c *Client) AddDocuments(ctx context.Context, req AddDocumentsRequest) (Add
 		return AddDocumentsResponse{}, fmt.Errorf("failed to decode add documents response: %w (%s)", err, string(body))
 	}
 
+	llmrouter.RecordEmbedding(ctx, usageProvider, c.model, documentTexts(req)...)
 	return result, nil
 }
+
+// documentTexts returns the document fields the API embeds.
+func documentTexts(req AddDocumentsRequest) []string {
+	texts := make([]string, 0, len(req.Documents)*len(req.TextFields))
+	for _, doc := range req.Documents {
+		for _, field := range req.TextFields {
+			if value, ok := doc[field].(string); ok {
+				texts = append(texts, value)
+			}
+		}
+	}
+	return texts
+}
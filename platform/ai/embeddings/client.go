@@ -9,12 +9,23 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"portal_final_backend/platform/ai/llmrouter"
+	"portal_final_backend/platform/circuitbreaker"
+)
+
+const (
+	// defaultModel is the model served by the embedding API.
+	defaultModel = "bge-m3"
+	// usageProvider identifies the embedding API in the AI usage ledger.
+	usageProvider = "embedding-api"
 )
 
 // Client is an HTTP client for embedding API services.
 type Client struct {
 	baseURL    string
 	apiKey     string
+	model      string
 	httpClient *http.Client
 }
 
@@ -22,6 +33,7 @@ type Client struct {
 type Config struct {
 	BaseURL string
 	APIKey  string
+	Model   string // Recorded in the AI usage ledger. Defaults to defaultModel.
 	Timeout time.Duration
 }
 
@@ -32,11 +44,18 @@ func NewClient(cfg Config) *Client {
 		timeout = 30 * time.Second
 	}
 
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+
 	return &Client{
 		baseURL: cfg.BaseURL,
 		apiKey:  cfg.APIKey,
+		model:   model,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: circuitbreaker.Transport(circuitbreaker.For(circuitbreaker.DependencyEmbeddings), nil),
 		},
 	}
 }
@@ -86,11 +105,13 @@ func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
 		Vector []float32 `json:"vector"`
 	}
 	if err := json.Unmarshal(body, &wrapped); err == nil && len(wrapped.Vector) > 0 {
+		llmrouter.RecordEmbedding(ctx, usageProvider, c.model, text)
 		return wrapped.Vector, nil
 	}
 
 	var vector []float32
 	if err := json.Unmarshal(body, &vector); err == nil {
+		llmrouter.RecordEmbedding(ctx, usageProvider, c.model, text)
 		return vector, nil
 	}
 
@@ -0,0 +1,392 @@
+// Package llmrouter routes agent LLM calls to the provider an organization has configured
+// and enforces its monthly token budget. It also records the tokens and estimated cost of
+// every AI call, embeddings included, in a per-organization usage ledger.
+//
+// Agents build their models with NewModel instead of openaicompat.NewModel. Until a Router
+// is installed with SetDefault, those models call the configured provider directly.
+package llmrouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"portal_final_backend/platform/adk/confirmation"
+	"portal_final_backend/platform/ai/openaicompat"
+	"portal_final_backend/platform/config"
+	"portal_final_backend/platform/logger"
+
+	"github.com/google/uuid"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// orgStateTTL bounds how long cached settings and usage counters are trusted before they
+// are reloaded, so changes made by another process take effect within this window.
+const orgStateTTL = 30 * time.Second
+
+// admittedRunTTL bounds how long an admitted agent invocation is remembered. Runs
+// that take longer are metered again on their next model call.
+const admittedRunTTL = time.Hour
+
+// ErrTokenBudgetExceeded is returned instead of calling the provider once an organization
+// has used its monthly token budget. Agent runs fail with it until the next month starts
+// or the budget is raised.
+var ErrTokenBudgetExceeded = errors.New("monthly ai token budget exceeded")
+
+// Settings is the per-organization AI provider configuration. Zero values keep the
+// platform defaults.
+type Settings struct {
+	Provider           string
+	Model              string
+	Temperature        *float64
+	MonthlyTokenBudget *int64
+}
+
+// Usage is the token usage of a single AI call.
+type Usage struct {
+	OrganizationID   uuid.UUID
+	Kind             string
+	Source           string
+	Provider         string
+	Model            string
+	PromptTokens     int64
+	CompletionTokens int64
+	EstimatedCostUSD float64
+}
+
+// SettingsReader loads the AI settings of an organization.
+type SettingsReader func(ctx context.Context, organizationID uuid.UUID) (Settings, error)
+
+// UsageStore persists token usage and reports the chat token usage of the current period.
+type UsageStore interface {
+	TokenUsageSince(ctx context.Context, organizationID uuid.UUID, since time.Time) (int64, error)
+	RecordUsage(ctx context.Context, usage Usage) error
+}
+
+// RunMeter admits and counts the AI runs of an organization, e.g. against the limits
+// of its subscription. An agent invocation is one run however many model calls it
+// makes; a chat call made outside an invocation is a run of its own.
+type RunMeter interface {
+	AdmitAIRun(ctx context.Context, organizationID uuid.UUID) error
+}
+
+// ProviderResolver resolves a provider id or model name into a provider config
+// (see config.Config.ResolveProviderConfig).
+type ProviderResolver func(provider string) config.LLMProviderConfig
+
+// Router applies organization settings to LLM calls. It is safe for concurrent use.
+type Router struct {
+	resolveProvider ProviderResolver
+	settings        SettingsReader
+	usage           UsageStore
+	runs            RunMeter
+	log             *logger.Logger
+	now             func() time.Time
+
+	mu           sync.Mutex
+	orgs         map[uuid.UUID]*orgState
+	models       map[modelKey]*openaicompat.Model
+	admittedRuns map[string]time.Time
+	prunedAt     time.Time
+}
+
+// modelKey identifies a cached provider model. The temperature is keyed by value because
+// every settings reload yields a fresh pointer.
+type modelKey struct {
+	config         openaicompat.Config
+	temperature    float64
+	hasTemperature bool
+}
+
+type orgState struct {
+	settings    Settings
+	used        int64
+	periodStart time.Time
+	loadedAt    time.Time
+}
+
+func New(resolveProvider ProviderResolver, settings SettingsReader, usage UsageStore, log *logger.Logger) *Router {
+	return &Router{
+		resolveProvider: resolveProvider,
+		settings:        settings,
+		usage:           usage,
+		log:             log,
+		now:             time.Now,
+		orgs:            make(map[uuid.UUID]*orgState),
+		models:          make(map[modelKey]*openaicompat.Model),
+		admittedRuns:    make(map[string]time.Time),
+	}
+}
+
+// SetRunMeter installs the meter that admits AI runs. It must be called before the
+// router serves calls.
+func (r *Router) SetRunMeter(meter RunMeter) {
+	r.runs = meter
+}
+
+var defaultRouter atomic.Pointer[Router]
+
+// SetDefault installs the router used by models created with NewModel.
+func SetDefault(r *Router) {
+	defaultRouter.Store(r)
+}
+
+// Default returns the installed router, or nil.
+func Default() *Router {
+	return defaultRouter.Load()
+}
+
+type orgContextKey struct{}
+
+// WithOrganizationID returns a context whose LLM calls are attributed to the organization.
+func WithOrganizationID(ctx context.Context, organizationID uuid.UUID) context.Context {
+	return context.WithValue(ctx, orgContextKey{}, organizationID)
+}
+
+// OrganizationID returns the organization an LLM call is made for. It falls back to the
+// tenant set for HITL confirmations, which the lead agents already propagate.
+func OrganizationID(ctx context.Context) (uuid.UUID, bool) {
+	if id, ok := ctx.Value(orgContextKey{}).(uuid.UUID); ok && id != uuid.Nil {
+		return id, true
+	}
+	if id, ok := confirmation.GetTenantID(ctx); ok && id != uuid.Nil {
+		return id, true
+	}
+	return uuid.Nil, false
+}
+
+// Model is an ADK model.LLM that routes each call through the default Router.
+type Model struct {
+	config openaicompat.Config
+	base   *openaicompat.Model
+}
+
+// NewModel creates a routed model using cfg as the platform default.
+func NewModel(cfg openaicompat.Config) *Model {
+	return &Model{config: cfg, base: openaicompat.NewModel(cfg)}
+}
+
+func (m *Model) Name() string {
+	return m.base.Name()
+}
+
+// GenerateContent calls the provider configured for the organization in ctx, or the
+// platform default when no organization or router is known.
+func (m *Model) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	router := Default()
+	organizationID, ok := OrganizationID(ctx)
+	if router == nil || !ok {
+		return m.base.GenerateContent(ctx, req, stream)
+	}
+	return router.generate(ctx, organizationID, m, req, stream)
+}
+
+func (r *Router) generate(ctx context.Context, organizationID uuid.UUID, m *Model, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		state, err := r.loadState(ctx, organizationID)
+		if err != nil {
+			// Settings are an overlay: a lookup failure must not take the agents down.
+			r.log.Warn("llmrouter: failed to load organization ai settings", "organizationId", organizationID, "error", err)
+			state = orgState{}
+		}
+		if budgetExceeded(state) {
+			yield(nil, fmt.Errorf("%w: %d of %d tokens used this month", ErrTokenBudgetExceeded, state.used, *state.settings.MonthlyTokenBudget))
+			return
+		}
+		if err := r.admitRun(ctx, organizationID); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		cfg := applySettings(m.config, state.settings, r.resolveProvider)
+		llm := m.base
+		if cfg != m.config {
+			llm = r.modelFor(cfg)
+		}
+
+		for resp, err := range llm.GenerateContent(ctx, req, stream) {
+			if resp != nil && resp.UsageMetadata != nil {
+				r.recordUsage(ctx, organizationID, cfg, resp.UsageMetadata)
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// admitRun meters the run an LLM call belongs to. ADK passes the agent invocation
+// as the context of a model call; its tool rounds and sub-agents reuse the
+// invocation, so only the first call of an invocation is admitted.
+func (r *Router) admitRun(ctx context.Context, organizationID uuid.UUID) error {
+	if r.runs == nil {
+		return nil
+	}
+	invocation, ok := ctx.(interface{ InvocationID() string })
+	if !ok || invocation.InvocationID() == "" {
+		return r.runs.AdmitAIRun(ctx, organizationID)
+	}
+	invocationID := invocation.InvocationID()
+
+	now := r.now()
+	r.mu.Lock()
+	if admittedAt, seen := r.admittedRuns[invocationID]; seen && now.Sub(admittedAt) < admittedRunTTL {
+		r.mu.Unlock()
+		return nil
+	}
+	if now.Sub(r.prunedAt) >= admittedRunTTL {
+		for id, admittedAt := range r.admittedRuns {
+			if now.Sub(admittedAt) >= admittedRunTTL {
+				delete(r.admittedRuns, id)
+			}
+		}
+		r.prunedAt = now
+	}
+	// Claim the invocation first so concurrent calls of one run meter it once.
+	r.admittedRuns[invocationID] = now
+	r.mu.Unlock()
+
+	if err := r.runs.AdmitAIRun(ctx, organizationID); err != nil {
+		r.mu.Lock()
+		delete(r.admittedRuns, invocationID)
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (r *Router) loadState(ctx context.Context, organizationID uuid.UUID) (orgState, error) {
+	now := r.now()
+	periodStart := monthStart(now)
+
+	r.mu.Lock()
+	cached, ok := r.orgs[organizationID]
+	if ok && cached.periodStart.Equal(periodStart) && now.Sub(cached.loadedAt) < orgStateTTL {
+		state := *cached
+		r.mu.Unlock()
+		return state, nil
+	}
+	r.mu.Unlock()
+
+	state := orgState{periodStart: periodStart, loadedAt: now}
+	if r.settings != nil {
+		settings, err := r.settings(ctx, organizationID)
+		if err != nil {
+			return orgState{}, err
+		}
+		state.settings = settings
+	}
+	if r.usage != nil {
+		used, err := r.usage.TokenUsageSince(ctx, organizationID, periodStart)
+		if err != nil {
+			return orgState{}, err
+		}
+		state.used = used
+	}
+
+	r.mu.Lock()
+	r.orgs[organizationID] = &state
+	r.mu.Unlock()
+	return state, nil
+}
+
+func (r *Router) recordUsage(ctx context.Context, organizationID uuid.UUID, cfg openaicompat.Config, meta *genai.GenerateContentResponseUsageMetadata) {
+	usage := Usage{
+		OrganizationID:   organizationID,
+		Kind:             KindChat,
+		Source:           Source(ctx),
+		Provider:         cfg.Provider,
+		Model:            cfg.Model,
+		PromptTokens:     int64(meta.PromptTokenCount),
+		CompletionTokens: int64(meta.CandidatesTokenCount),
+	}
+	usage.EstimatedCostUSD = EstimateCostUSD(usage.Model, usage.PromptTokens, usage.CompletionTokens)
+
+	r.mu.Lock()
+	if cached, ok := r.orgs[organizationID]; ok {
+		cached.used += usage.PromptTokens + usage.CompletionTokens
+	}
+	r.mu.Unlock()
+
+	if r.usage == nil {
+		return
+	}
+	if err := r.usage.RecordUsage(context.WithoutCancel(ctx), usage); err != nil {
+		r.log.Warn("llmrouter: failed to record ai usage", "organizationId", organizationID, "error", err)
+	}
+}
+
+func (r *Router) modelFor(cfg openaicompat.Config) *openaicompat.Model {
+	key := modelKey{config: cfg}
+	if cfg.Temperature != nil {
+		key.temperature = *cfg.Temperature
+		key.hasTemperature = true
+		key.config.Temperature = nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if llm, ok := r.models[key]; ok {
+		return llm
+	}
+	llm := openaicompat.NewModel(cfg)
+	r.models[key] = llm
+	return llm
+}
+
+func budgetExceeded(state orgState) bool {
+	budget := state.settings.MonthlyTokenBudget
+	return budget != nil && *budget > 0 && state.used >= *budget
+}
+
+// applySettings overlays organization settings on the platform config of an agent. A
+// provider switch keeps the agent's reasoning mode and is skipped when the provider has no
+// API key or lacks vision support the agent relies on.
+func applySettings(base openaicompat.Config, settings Settings, resolveProvider ProviderResolver) openaicompat.Config {
+	cfg := base
+	if settings.Provider != "" && settings.Provider != base.Provider && resolveProvider != nil &&
+		(!base.SupportsVision || config.ProviderSupportsVision(settings.Provider)) {
+		target := resolveProvider(settings.Provider)
+		if target.APIKey != "" {
+			reasoning := isReasoningConfig(base, resolveProvider(base.Provider))
+			cfg.Provider = target.Provider
+			cfg.APIKey = target.APIKey
+			cfg.BaseURL = target.BaseURL
+			cfg.Model = target.Model
+			cfg.DisableThinking = true
+			if reasoning {
+				cfg.Model = target.ReasoningModel
+				cfg.DisableThinking = target.Provider != config.LLMProviderKimi
+			}
+			cfg.SupportsVision = config.ProviderSupportsVision(target.Provider)
+		}
+	}
+	if settings.Model != "" && cfg.Provider == settings.Provider {
+		cfg.Model = settings.Model
+	}
+	if settings.Temperature != nil {
+		cfg.Temperature = settings.Temperature
+	}
+	return cfg
+}
+
+// isReasoningConfig reports whether the agent was configured with its provider's
+// reasoning mode: the thinking toggle on Kimi or a dedicated reasoning model elsewhere.
+func isReasoningConfig(cfg openaicompat.Config, provider config.LLMProviderConfig) bool {
+	if !cfg.DisableThinking {
+		return true
+	}
+	return provider.ReasoningModel != provider.Model && cfg.Model == provider.ReasoningModel
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+var _ model.LLM = (*Model)(nil)
@@ -0,0 +1,184 @@
+package llmrouter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portal_final_backend/platform/adk/confirmation"
+	"portal_final_backend/platform/ai/openaicompat"
+	"portal_final_backend/platform/config"
+
+	"github.com/google/uuid"
+)
+
+func testResolver(provider string) config.LLMProviderConfig {
+	switch provider {
+	case config.LLMProviderDeepSeek:
+		return config.LLMProviderConfig{Provider: provider, APIKey: "ds-key", BaseURL: "https://ds", Model: "deepseek-chat", ReasoningModel: "deepseek-reasoner"}
+	default:
+		return config.LLMProviderConfig{Provider: config.LLMProviderKimi, APIKey: "kimi-key", BaseURL: "https://kimi", Model: "kimi-k2.6", ReasoningModel: "kimi-k2.6"}
+	}
+}
+
+func TestApplySettingsSwitchesProviderKeepingReasoningMode(t *testing.T) {
+	reasoning := openaicompat.Config{Provider: config.LLMProviderKimi, Model: "kimi-k2.6", APIKey: "kimi-key", DisableThinking: false}
+	got := applySettings(reasoning, Settings{Provider: config.LLMProviderDeepSeek}, testResolver)
+	if got.Provider != config.LLMProviderDeepSeek || got.Model != "deepseek-reasoner" || got.APIKey != "ds-key" {
+		t.Fatalf("unexpected reasoning config: %+v", got)
+	}
+
+	chat := openaicompat.Config{Provider: config.LLMProviderKimi, Model: "kimi-k2.6", APIKey: "kimi-key", DisableThinking: true}
+	got = applySettings(chat, Settings{Provider: config.LLMProviderDeepSeek}, testResolver)
+	if got.Model != "deepseek-chat" || !got.DisableThinking {
+		t.Fatalf("unexpected chat config: %+v", got)
+	}
+}
+
+func TestApplySettingsKeepsVisionProvider(t *testing.T) {
+	vision := openaicompat.Config{Provider: config.LLMProviderKimi, Model: "kimi-k2.6", SupportsVision: true}
+	got := applySettings(vision, Settings{Provider: config.LLMProviderDeepSeek}, testResolver)
+	if got.Provider != config.LLMProviderKimi {
+		t.Fatalf("expected vision agent to stay on kimi, got %q", got.Provider)
+	}
+}
+
+func TestApplySettingsModelAndTemperature(t *testing.T) {
+	temperature := 0.2
+	base := openaicompat.Config{Provider: config.LLMProviderKimi, Model: "kimi-k2.6", DisableThinking: true}
+	got := applySettings(base, Settings{Provider: config.LLMProviderKimi, Model: "kimi-k2.5", Temperature: &temperature}, testResolver)
+	if got.Model != "kimi-k2.5" || got.Temperature == nil || *got.Temperature != temperature {
+		t.Fatalf("unexpected config: %+v", got)
+	}
+	if unchanged := applySettings(base, Settings{}, testResolver); unchanged != base {
+		t.Fatalf("expected empty settings to keep the base config, got %+v", unchanged)
+	}
+}
+
+type fakeUsageStore struct {
+	used     int64
+	recorded []Usage
+}
+
+func (f *fakeUsageStore) TokenUsageSince(context.Context, uuid.UUID, time.Time) (int64, error) {
+	return f.used, nil
+}
+
+func (f *fakeUsageStore) RecordUsage(_ context.Context, usage Usage) error {
+	f.recorded = append(f.recorded, usage)
+	return nil
+}
+
+func TestLoadStateBudget(t *testing.T) {
+	budget := int64(1000)
+	store := &fakeUsageStore{used: 1000}
+	r := New(testResolver, func(context.Context, uuid.UUID) (Settings, error) {
+		return Settings{MonthlyTokenBudget: &budget}, nil
+	}, store, nil)
+
+	state, err := r.loadState(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("loadState: %v", err)
+	}
+	if !budgetExceeded(state) {
+		t.Fatalf("expected budget of %d to be exceeded at %d tokens", budget, state.used)
+	}
+
+	store.used = 999
+	state, _ = r.loadState(context.Background(), uuid.New())
+	if budgetExceeded(state) {
+		t.Fatal("expected budget not to be exceeded")
+	}
+}
+
+func TestOrganizationIDFallsBackToTenant(t *testing.T) {
+	tenantID := uuid.New()
+	got, ok := OrganizationID(confirmation.WithTenantID(context.Background(), tenantID))
+	if !ok || got != tenantID {
+		t.Fatalf("expected tenant fallback %s, got %s (%v)", tenantID, got, ok)
+	}
+
+	orgID := uuid.New()
+	got, _ = OrganizationID(WithOrganizationID(confirmation.WithTenantID(context.Background(), tenantID), orgID))
+	if got != orgID {
+		t.Fatalf("expected explicit organization %s, got %s", orgID, got)
+	}
+
+	if _, ok := OrganizationID(context.Background()); ok {
+		t.Fatal("expected no organization on a bare context")
+	}
+}
+
+func TestMonthStart(t *testing.T) {
+	got := monthStart(time.Date(2026, time.March, 17, 23, 30, 0, 0, time.UTC))
+	if want := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("monthStart = %s, want %s", got, want)
+	}
+}
+
+func TestEstimateCostUSD(t *testing.T) {
+	got := EstimateCostUSD("deepseek-chat", 1_000_000, 500_000)
+	if want := 0.27 + 0.55; got < want-1e-9 || got > want+1e-9 {
+		t.Fatalf("EstimateCostUSD = %f, want %f", got, want)
+	}
+	if got := EstimateCostUSD("bge-m3", 1000, 0); got != 0 {
+		t.Fatalf("expected unpriced model to cost 0, got %f", got)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("abcd", "efghi"); got != 3 {
+		t.Fatalf("EstimateTokens = %d, want 3", got)
+	}
+	if got := EstimateTokens(""); got != 0 {
+		t.Fatalf("EstimateTokens(\"\") = %d, want 0", got)
+	}
+}
+
+type countingRunMeter struct {
+	admitted int
+}
+
+func (m *countingRunMeter) AdmitAIRun(context.Context, uuid.UUID) error {
+	m.admitted++
+	return nil
+}
+
+type invocationContext struct {
+	context.Context
+	id string
+}
+
+func (c invocationContext) InvocationID() string { return c.id }
+
+func TestAdmitRunMetersAnInvocationOnce(t *testing.T) {
+	meter := &countingRunMeter{}
+	r := New(testResolver, nil, nil, nil)
+	r.SetRunMeter(meter)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	orgID := uuid.New()
+
+	run := invocationContext{Context: context.Background(), id: "e-1"}
+	for i := 0; i < 3; i++ {
+		if err := r.admitRun(run, orgID); err != nil {
+			t.Fatalf("admitRun: %v", err)
+		}
+	}
+	if meter.admitted != 1 {
+		t.Fatalf("three calls of one invocation metered %d runs, want 1", meter.admitted)
+	}
+
+	_ = r.admitRun(invocationContext{Context: context.Background(), id: "e-2"}, orgID)
+	_ = r.admitRun(context.Background(), orgID)
+	_ = r.admitRun(context.Background(), orgID)
+	if meter.admitted != 4 {
+		t.Fatalf("metered %d runs, want a new invocation and each call outside one counted", meter.admitted)
+	}
+
+	now = now.Add(admittedRunTTL)
+	_ = r.admitRun(run, orgID)
+	if meter.admitted != 5 {
+		t.Fatalf("metered %d runs, want the invocation metered again after %s", meter.admitted, admittedRunTTL)
+	}
+}
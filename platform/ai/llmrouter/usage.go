@@ -0,0 +1,99 @@
+package llmrouter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Usage kinds. Only chat tokens count towards the monthly token budget.
+const (
+	KindChat      = "chat"
+	KindEmbedding = "embedding"
+)
+
+// charsPerToken approximates the tokenizer for providers that do not report token counts.
+const charsPerToken = 4
+
+// ModelPrice is the list price of a model in USD per million tokens.
+type ModelPrice struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// modelPrices holds the providers' published list prices. Models missing here, such as
+// the self-hosted embedding model, are recorded without cost.
+var modelPrices = map[string]ModelPrice{
+	"kimi-k2.6":         {InputPerMillion: 0.60, OutputPerMillion: 2.50},
+	"kimi-k2.5":         {InputPerMillion: 0.60, OutputPerMillion: 2.50},
+	"moonshot-v1-8k":    {InputPerMillion: 0.20, OutputPerMillion: 2.00},
+	"moonshot-v1-32k":   {InputPerMillion: 1.00, OutputPerMillion: 3.00},
+	"moonshot-v1-128k":  {InputPerMillion: 2.00, OutputPerMillion: 5.00},
+	"deepseek-chat":     {InputPerMillion: 0.27, OutputPerMillion: 1.10},
+	"deepseek-reasoner": {InputPerMillion: 0.55, OutputPerMillion: 2.19},
+}
+
+// EstimateCostUSD returns the list-price cost of a call, or 0 for unpriced models.
+func EstimateCostUSD(model string, promptTokens, completionTokens int64) float64 {
+	price, ok := modelPrices[strings.ToLower(strings.TrimSpace(model))]
+	if !ok {
+		return 0
+	}
+	return (float64(promptTokens)*price.InputPerMillion + float64(completionTokens)*price.OutputPerMillion) / 1_000_000
+}
+
+// EstimateTokens approximates the token count of texts.
+func EstimateTokens(texts ...string) int64 {
+	var chars int
+	for _, text := range texts {
+		chars += len([]rune(text))
+	}
+	if chars == 0 {
+		return 0
+	}
+	return int64((chars + charsPerToken - 1) / charsPerToken)
+}
+
+type sourceContextKey struct{}
+
+// WithSource returns a context whose AI calls are attributed to the given feature, such as
+// an agent name. The innermost source wins.
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceContextKey{}, strings.TrimSpace(source))
+}
+
+// Source returns the feature the AI calls in ctx are attributed to.
+func Source(ctx context.Context) string {
+	source, _ := ctx.Value(sourceContextKey{}).(string)
+	return source
+}
+
+// RecordEmbedding records the estimated usage of an embedding call for the organization
+// in ctx. It is a no-op without an installed router or organization.
+func RecordEmbedding(ctx context.Context, provider, model string, texts ...string) {
+	router := Default()
+	organizationID, ok := OrganizationID(ctx)
+	if router == nil || !ok {
+		return
+	}
+	router.recordEmbedding(ctx, organizationID, provider, model, EstimateTokens(texts...))
+}
+
+func (r *Router) recordEmbedding(ctx context.Context, organizationID uuid.UUID, provider, model string, tokens int64) {
+	if r.usage == nil || tokens == 0 {
+		return
+	}
+	usage := Usage{
+		OrganizationID:   organizationID,
+		Kind:             KindEmbedding,
+		Source:           Source(ctx),
+		Provider:         provider,
+		Model:            model,
+		PromptTokens:     tokens,
+		EstimatedCostUSD: EstimateCostUSD(model, tokens, 0),
+	}
+	if err := r.usage.RecordUsage(context.WithoutCancel(ctx), usage); err != nil {
+		r.log.Warn("llmrouter: failed to record embedding usage", "organizationId", organizationID, "error", err)
+	}
+}
@@ -33,6 +33,7 @@ type Config struct {
 	DisableThinking bool          // For Kimi: toggles thinking payload. For DeepSeek: ignored (reasoning via model name).
 	SupportsVision  bool          // Whether this provider accepts image_url content parts.
 	Timeout         time.Duration // Per-request HTTP timeout. Zero uses defaultHTTPRequestTimeout.
+	Temperature     *float64      // Sampling temperature. Nil leaves the provider default (or the request's own temperature).
 }
 
 // Model adapts an OpenAI-compatible provider to the ADK model.LLM interface.
@@ -207,6 +208,9 @@ func (m *Model) buildPayload(req *model.LLMRequest) map[string]interface{} {
 			payload["temperature"] = float64(*req.Config.Temperature)
 		}
 	}
+	if _, set := payload["temperature"]; !set && m.config.Temperature != nil {
+		payload["temperature"] = *m.config.Temperature
+	}
 
 	if len(tools) > 0 {
 		payload["tools"] = tools
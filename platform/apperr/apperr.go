@@ -30,6 +30,14 @@ const (
 	KindInternal
 	// KindGone indicates a resource that existed but is no longer available.
 	KindGone
+	// KindTooManyRequests indicates the caller is temporarily blocked, e.g.
+	// after too many failed attempts.
+	KindTooManyRequests
+	// KindPayloadTooLarge indicates the request body exceeds the allowed size.
+	KindPayloadTooLarge
+	// KindPaymentRequired indicates the organization's subscription does not
+	// cover the action, e.g. a plan limit was hit or a payment is overdue.
+	KindPaymentRequired
 )
 
 // Error is a domain error with a typed Kind for HTTP mapping.
@@ -71,6 +79,12 @@ func (e *Error) HTTPStatus() int {
 		return http.StatusInternalServerError
 	case KindGone:
 		return http.StatusGone
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	case KindPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindPaymentRequired:
+		return http.StatusPaymentRequired
 	default:
 		return http.StatusBadRequest
 	}
@@ -135,6 +149,21 @@ func Gone(message string) *Error {
 	return New(KindGone, message)
 }
 
+// TooManyRequests creates an error for a caller that is temporarily blocked.
+func TooManyRequests(message string) *Error {
+	return New(KindTooManyRequests, message)
+}
+
+// PayloadTooLarge creates an error for a request body above the allowed size.
+func PayloadTooLarge(message string) *Error {
+	return New(KindPayloadTooLarge, message)
+}
+
+// PaymentRequired creates an error for an action the subscription does not cover.
+func PaymentRequired(message string) *Error {
+	return New(KindPaymentRequired, message)
+}
+
 // GetKind extracts the error kind from an error.
 // Returns KindUnknown if the error is not an *Error.
 func GetKind(err error) Kind {
@@ -0,0 +1,299 @@
+// Package circuitbreaker guards calls to external dependencies such as Gotenberg,
+// Qdrant, the embedding APIs and the WhatsApp service. After a run of failures a
+// breaker opens and rejects calls immediately with ErrOpen, so requests and jobs
+// fail fast instead of queueing on a dead dependency. Once the open timeout has
+// passed, a limited number of probe calls decide whether it closes again.
+//
+// Breakers are shared per dependency through For; Configure applies the
+// thresholds from the environment to all of them.
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Dependency names used for the shared breakers.
+const (
+	DependencyGotenberg         = "gotenberg"
+	DependencyQdrant            = "qdrant"
+	DependencyEmbeddings        = "embeddings"
+	DependencyCatalogEmbeddings = "catalog-embeddings"
+	DependencyWhatsApp          = "whatsapp"
+)
+
+// State is the state of a breaker.
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// ErrOpen matches every error returned for a call rejected by an open breaker.
+var ErrOpen = errors.New("circuit breaker open")
+
+// OpenError is returned instead of calling a dependency whose breaker is open.
+type OpenError struct {
+	Dependency string
+	// RetryAfter is the time until the breaker lets a probe call through.
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s unavailable: circuit breaker open, retry in %s", e.Dependency, e.RetryAfter.Round(time.Second))
+}
+
+// Is makes errors.Is(err, ErrOpen) match.
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// RetryAfter returns how long to wait before retrying a call rejected by an open
+// breaker, and false when err was not caused by an open breaker.
+func RetryAfter(err error) (time.Duration, bool) {
+	var openErr *OpenError
+	if !errors.As(err, &openErr) {
+		return 0, false
+	}
+	return openErr.RetryAfter, true
+}
+
+// Settings configures when a breaker opens and how it recovers.
+type Settings struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker rejects calls before probing.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of successful probe calls that closes the
+	// breaker again. At most this many probes run at the same time.
+	HalfOpenProbes int
+}
+
+// DefaultSettings returns the settings used when none are configured.
+func DefaultSettings() Settings {
+	return Settings{FailureThreshold: 5, OpenTimeout: 30 * time.Second, HalfOpenProbes: 1}
+}
+
+func (s Settings) normalized() Settings {
+	defaults := DefaultSettings()
+	if s.FailureThreshold <= 0 {
+		s.FailureThreshold = defaults.FailureThreshold
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = defaults.OpenTimeout
+	}
+	if s.HalfOpenProbes <= 0 {
+		s.HalfOpenProbes = defaults.HalfOpenProbes
+	}
+	return s
+}
+
+// Stats is a snapshot of the state and counters of a breaker.
+type Stats struct {
+	Dependency          string     `json:"dependency"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	Successes           int64      `json:"successes"`
+	Failures            int64      `json:"failures"`
+	Rejected            int64      `json:"rejected"`
+	Opened              int64      `json:"opened"`
+	LastOpenedAt        *time.Time `json:"lastOpenedAt,omitempty"`
+}
+
+// Breaker tracks the health of one dependency. It is safe for concurrent use.
+type Breaker struct {
+	name string
+	now  func() time.Time
+
+	mu             sync.Mutex
+	settings       Settings
+	state          State
+	failures       int
+	openedAt       time.Time
+	probesInFlight int
+	probeSuccesses int
+	stats          Stats
+}
+
+// New creates a closed breaker for a dependency.
+func New(name string, settings Settings) *Breaker {
+	return &Breaker{
+		name:     name,
+		now:      time.Now,
+		settings: settings.normalized(),
+		state:    StateClosed,
+		stats:    Stats{Dependency: name},
+	}
+}
+
+// Name returns the dependency the breaker guards.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Do calls fn unless the breaker is open. Every error returned by fn counts as a
+// failure of the dependency.
+func (b *Breaker) Do(fn func() error) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	b.record(probe, err == nil)
+	return err
+}
+
+// allow reserves a call. It reports whether the call is a half-open probe.
+func (b *Breaker) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		wait := b.openedAt.Add(b.settings.OpenTimeout).Sub(b.now())
+		if wait > 0 {
+			b.stats.Rejected++
+			return false, &OpenError{Dependency: b.name, RetryAfter: wait}
+		}
+		b.transition(StateHalfOpen)
+	}
+	if b.state == StateHalfOpen {
+		if b.probesInFlight >= b.settings.HalfOpenProbes-b.probeSuccesses {
+			b.stats.Rejected++
+			return false, &OpenError{Dependency: b.name, RetryAfter: time.Second}
+		}
+		b.probesInFlight++
+		return true, nil
+	}
+	return false, nil
+}
+
+// record stores the outcome of a call reserved with allow.
+func (b *Breaker) record(probe, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probesInFlight--
+	}
+	if success {
+		b.stats.Successes++
+		b.failures = 0
+		if probe && b.state == StateHalfOpen {
+			b.probeSuccesses++
+			if b.probeSuccesses >= b.settings.HalfOpenProbes {
+				b.transition(StateClosed)
+			}
+		}
+		return
+	}
+
+	b.stats.Failures++
+	b.failures++
+	if b.state == StateHalfOpen || (b.state == StateClosed && b.failures >= b.settings.FailureThreshold) {
+		b.transition(StateOpen)
+	}
+}
+
+// release frees a reserved call without counting it, e.g. when the caller gave up.
+func (b *Breaker) release(probe bool) {
+	if !probe {
+		return
+	}
+	b.mu.Lock()
+	b.probesInFlight--
+	b.mu.Unlock()
+}
+
+// transition must be called with mu held.
+func (b *Breaker) transition(to State) {
+	if b.state == to {
+		return
+	}
+	from := b.state
+	b.state = to
+	b.probeSuccesses = 0
+
+	switch to {
+	case StateOpen:
+		b.openedAt = b.now()
+		openedAt := b.openedAt
+		b.stats.Opened++
+		b.stats.LastOpenedAt = &openedAt
+		slog.Warn("circuit breaker opened", "dependency", b.name, "from", from, "consecutiveFailures", b.failures, "openTimeout", b.settings.OpenTimeout)
+	case StateHalfOpen:
+		slog.Info("circuit breaker probing", "dependency", b.name)
+	case StateClosed:
+		b.failures = 0
+		slog.Info("circuit breaker closed", "dependency", b.name)
+	}
+}
+
+// Stats returns a snapshot of the breaker.
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.State = b.state
+	stats.ConsecutiveFailures = b.failures
+	return stats
+}
+
+func (b *Breaker) configure(settings Settings) {
+	b.mu.Lock()
+	b.settings = settings.normalized()
+	b.mu.Unlock()
+}
+
+var registry = struct {
+	mu       sync.Mutex
+	settings Settings
+	breakers map[string]*Breaker
+}{settings: DefaultSettings(), breakers: make(map[string]*Breaker)}
+
+// Configure applies settings to all shared breakers, including those created later.
+func Configure(settings Settings) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.settings = settings.normalized()
+	for _, b := range registry.breakers {
+		b.configure(registry.settings)
+	}
+}
+
+// For returns the shared breaker of a dependency, creating it on first use.
+func For(dependency string) *Breaker {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if b, ok := registry.breakers[dependency]; ok {
+		return b
+	}
+	b := New(dependency, registry.settings)
+	registry.breakers[dependency] = b
+	return b
+}
+
+// Snapshot returns the stats of all shared breakers, ordered by dependency.
+func Snapshot() []Stats {
+	registry.mu.Lock()
+	breakers := make([]*Breaker, 0, len(registry.breakers))
+	for _, b := range registry.breakers {
+		breakers = append(breakers, b)
+	}
+	registry.mu.Unlock()
+
+	stats := make([]Stats, 0, len(breakers))
+	for _, b := range breakers {
+		stats = append(stats, b.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Dependency < stats[j].Dependency })
+	return stats
+}
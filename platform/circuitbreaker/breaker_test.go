@@ -0,0 +1,126 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var errDependency = errors.New("dependency down")
+
+func newTestBreaker(settings Settings) (*Breaker, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := New("test", settings)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func fail() error    { return errDependency }
+func succeed() error { return nil }
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(Settings{FailureThreshold: 3, OpenTimeout: time.Minute, HalfOpenProbes: 1})
+
+	_ = b.Do(fail)
+	_ = b.Do(fail)
+	_ = b.Do(succeed)
+	_ = b.Do(fail)
+	_ = b.Do(fail)
+	if state := b.Stats().State; state != StateClosed {
+		t.Fatalf("expected a success to reset the failure count, got %s", state)
+	}
+
+	_ = b.Do(fail)
+	called := false
+	err := b.Do(func() error { called = true; return nil })
+	if called || !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected the open breaker to reject without calling, got called=%t err=%v", called, err)
+	}
+	if retryAfter, ok := RetryAfter(err); !ok || retryAfter != time.Minute {
+		t.Fatalf("expected retry after the open timeout, got %s (%t)", retryAfter, ok)
+	}
+
+	stats := b.Stats()
+	if stats.State != StateOpen || stats.Opened != 1 || stats.Rejected != 1 || stats.Failures != 5 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestBreakerHalfOpenProbing(t *testing.T) {
+	b, now := newTestBreaker(Settings{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenProbes: 2})
+	_ = b.Do(fail)
+
+	*now = now.Add(time.Minute)
+	if err := b.Do(fail); !errors.Is(err, errDependency) {
+		t.Fatalf("expected a probe call after the open timeout, got %v", err)
+	}
+	if state := b.Stats().State; state != StateOpen {
+		t.Fatalf("expected a failed probe to reopen the breaker, got %s", state)
+	}
+
+	*now = now.Add(time.Minute)
+	_ = b.Do(succeed)
+	if state := b.Stats().State; state != StateHalfOpen {
+		t.Fatalf("expected the breaker to stay half-open until enough probes succeed, got %s", state)
+	}
+	_ = b.Do(succeed)
+	if state := b.Stats().State; state != StateClosed {
+		t.Fatalf("expected the breaker to close after successful probes, got %s", state)
+	}
+}
+
+func TestBreakerLimitsConcurrentProbes(t *testing.T) {
+	b, now := newTestBreaker(Settings{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenProbes: 1})
+	_ = b.Do(fail)
+	*now = now.Add(time.Minute)
+
+	probe, err := b.allow()
+	if err != nil || !probe {
+		t.Fatalf("expected the first call to be a probe, got probe=%t err=%v", probe, err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected a second concurrent probe to be rejected, got %v", err)
+	}
+	b.record(true, true)
+	if state := b.Stats().State; state != StateClosed {
+		t.Fatalf("expected the breaker to close, got %s", state)
+	}
+}
+
+func TestTransportCountsServerErrors(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	b := New("test", Settings{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenProbes: 1})
+	client := &http.Client{Transport: Transport(b, nil)}
+	get := func() error {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		return nil
+	}
+
+	status = http.StatusNotFound
+	for i := 0; i < 3; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("expected client errors to pass through, got %v", err)
+		}
+	}
+	if state := b.Stats().State; state != StateClosed {
+		t.Fatalf("expected 4xx answers not to open the breaker, got %s", state)
+	}
+
+	status = http.StatusServiceUnavailable
+	_ = get()
+	_ = get()
+	if err := get(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected 5xx answers to open the breaker, got %v", err)
+	}
+}
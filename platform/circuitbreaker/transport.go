@@ -0,0 +1,43 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// transport guards an HTTP client with a breaker. Transport errors, timeouts and
+// 5xx or 429 answers count as failures; other answers count as successes.
+type transport struct {
+	breaker *Breaker
+	base    http.RoundTripper
+}
+
+// Transport wraps base (http.DefaultTransport when nil) so that every request
+// passes through the breaker. Rejected requests fail with an *OpenError.
+func Transport(breaker *Breaker, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{breaker: breaker, base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	probe, err := t.breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil && errors.Is(req.Context().Err(), context.Canceled) {
+		// The caller gave up; that says nothing about the dependency.
+		t.breaker.release(probe)
+		return resp, err
+	}
+	t.breaker.record(probe, err == nil && !isServerFailure(resp.StatusCode))
+	return resp, err
+}
+
+func isServerFailure(status int) bool {
+	return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+}
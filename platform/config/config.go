@@ -5,7 +5,9 @@ package config
 import (
 	"fmt"
 	"net/http"
+	"net/netip"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -197,6 +199,7 @@ type SchedulerConfig interface {
 	GetRedisTLSInsecure() bool
 	GetAsynqQueueName() string
 	GetAsynqConcurrency() int
+	GetAsynqOrgConcurrency() int
 }
 
 // HTTPConfig provides settings for the HTTP server.
@@ -205,6 +208,28 @@ type HTTPConfig interface {
 	GetCORSAllowAll() bool
 	GetCORSOrigins() []string
 	GetCORSAllowCreds() bool
+	// GetAPIV1DeprecatedAt returns when /api/v1 was deprecated; zero when it is not.
+	GetAPIV1DeprecatedAt() time.Time
+	// GetAPIV1SunsetAt returns when /api/v1 will be removed; zero when not scheduled.
+	GetAPIV1SunsetAt() time.Time
+	// GetHTTPMaxBodyBytes returns the default request body limit.
+	GetHTTPMaxBodyBytes() int64
+	// GetHTTPMaxUploadBodyBytes returns the body limit of file upload routes.
+	GetHTTPMaxUploadBodyBytes() int64
+	// GetHTTPBodyLimits returns per-route body limits keyed by route pattern.
+	GetHTTPBodyLimits() map[string]int64
+	// GetTrustedProxies returns the networks of the reverse proxies whose
+	// forwarding headers are honoured.
+	GetTrustedProxies() []netip.Prefix
+}
+
+// GRPCConfig provides settings for the internal gRPC server. An empty address disables it.
+type GRPCConfig interface {
+	GetGRPCAddr() string
+	GetGRPCTLSCertFile() string
+	GetGRPCTLSKeyFile() string
+	GetGRPCTLSClientCAFile() string
+	GetGRPCAllowedClients() []string
 }
 
 // MinIOConfig provides settings for MinIO S3-compatible storage.
@@ -220,9 +245,35 @@ type MinIOConfig interface {
 	GetMinioBucketOrganizationLogos() string
 	GetMinioBucketQuotePDFs() string
 	GetMinioBucketQuoteAttachments() string
+	GetMinioBucketFiles() string
+	GetMinioBucketAuditExports() string
+	GetMinioBuckets() []string
 	IsMinIOEnabled() bool
 }
 
+// StorageQuotaConfig provides the default per-organization storage limits.
+// A limit of zero disables it.
+type StorageQuotaConfig interface {
+	GetStorageQuotaSoftLimitBytes() int64
+	GetStorageQuotaHardLimitBytes() int64
+}
+
+// BillingConfig provides the platform subscription settings. An empty default
+// plan leaves organizations without a subscription unlimited.
+type BillingConfig interface {
+	GetBillingDefaultPlan() string
+	GetBillingGracePeriod() time.Duration
+	GetStripeSecretKey() string
+	GetStripeWebhookSecret() string
+	GetBillingMollieAPIKey() string
+}
+
+// ResumableUploadConfig provides the part size and expiry of resumable uploads.
+type ResumableUploadConfig interface {
+	GetUploadPartSizeBytes() int64
+	GetUploadSessionTTL() time.Duration
+}
+
 // GotenbergConfig provides settings for the Gotenberg HTML-to-PDF service.
 type GotenbergConfig interface {
 	GetGotenbergURL() string
@@ -237,11 +288,27 @@ type EnergyLabelConfig interface {
 	IsEnergyLabelEnabled() bool
 }
 
+// KvKConfig provides settings for the KvK (Dutch Chamber of Commerce) API.
+type KvKConfig interface {
+	GetKvKAPIKey() string
+	GetKvKAPIBaseURL() string
+	IsKvKEnabled() bool
+}
+
+// GeocodingConfig provides settings for the geocoding providers. Providers
+// are tried in order; a daily limit of 0 means unlimited.
+type GeocodingConfig interface {
+	GetGeocodingProviders() []string
+	GetGoogleGeocodingAPIKey() string
+	GetGeocodingDailyLimit(provider string) int
+}
+
 // QdrantConfig provides settings for Qdrant vector database.
 type QdrantConfig interface {
 	GetQdrantURL() string
 	GetQdrantAPIKey() string
 	GetQdrantCollection() string
+	GetQdrantTenancyMode() string
 	IsQdrantEnabled() bool
 }
 
@@ -257,9 +324,19 @@ type CatalogEmbeddingConfig interface {
 	GetCatalogEmbeddingAPIURL() string
 	GetCatalogEmbeddingAPIKey() string
 	GetCatalogEmbeddingCollection() string
+	GetCatalogEmbeddingModel() string
+	GetCatalogEmbeddingVersion() string
 	IsCatalogEmbeddingEnabled() bool
 }
 
+// CircuitBreakerConfig provides the thresholds of the circuit breakers that
+// guard external dependencies.
+type CircuitBreakerConfig interface {
+	GetCircuitBreakerFailureThreshold() int
+	GetCircuitBreakerOpenTimeout() time.Duration
+	GetCircuitBreakerHalfOpenProbes() int
+}
+
 // =============================================================================
 // Main Config Struct
 // =============================================================================
@@ -278,6 +355,17 @@ type Config struct {
 	CORSAllowAll                      bool
 	CORSOrigins                       []string
 	CORSAllowCreds                    bool
+	APIV1DeprecatedAt                 time.Time
+	APIV1SunsetAt                     time.Time
+	HTTPMaxBodyBytes                  int64
+	HTTPMaxUploadBodyBytes            int64
+	HTTPBodyLimits                    map[string]int64
+	TrustedProxies                    []netip.Prefix
+	GRPCAddr                          string
+	GRPCTLSCertFile                   string
+	GRPCTLSKeyFile                    string
+	GRPCTLSClientCAFile               string
+	GRPCAllowedClients                []string
 	AppBaseURL                        string
 	PublicBaseURL                     string
 	PublicAPIBaseURL                  string
@@ -304,6 +392,12 @@ type Config struct {
 	LLMModelWhatsAppReply             string
 	LLMModelWhatsAppAgent             string
 	EPOnlineAPIKey                    string
+	KvKAPIKey                         string
+	KvKAPIBaseURL                     string
+	GeocodingProviders                []string
+	GoogleGeocodingAPIKey             string
+	GeocodingNominatimDailyLimit      int
+	GeocodingGoogleDailyLimit         int
 	MinIOEndpoint                     string
 	MinIOAccessKey                    string
 	MinIOSecretKey                    string
@@ -315,18 +409,35 @@ type Config struct {
 	MinioBucketOrganizationLogos      string
 	MinioBucketQuotePDFs              string
 	MinioBucketQuoteAttachments       string
+	MinioBucketFiles                  string
+	MinioBucketAuditExports           string
+	StorageQuotaSoftLimitBytes        int64
+	StorageQuotaHardLimitBytes        int64
+	BillingDefaultPlan                string
+	BillingGracePeriod                time.Duration
+	StripeSecretKey                   string
+	StripeWebhookSecret               string
+	BillingMollieAPIKey               string
+	UploadPartSizeBytes               int64
+	UploadSessionTTL                  time.Duration
 	GotenbergURL                      string
 	GotenbergUsername                 string
 	GotenbergPassword                 string
 	QdrantURL                         string
 	QdrantAPIKey                      string
 	QdrantCollection                  string
+	QdrantTenancyMode                 string
 	EmbeddingAPIURL                   string
 	EmbeddingAPIKey                   string
 	CatalogEmbeddingAPIURL            string
 	CatalogEmbeddingAPIKey            string
 	CatalogEmbeddingCollection        string
+	CatalogEmbeddingModel             string
+	CatalogEmbeddingVersion           string
 	BouwmaatEmbeddingCollection       string
+	CircuitBreakerFailureThreshold    int
+	CircuitBreakerOpenTimeout         time.Duration
+	CircuitBreakerHalfOpenProbes      int
 	WhatsAppURL                       string
 	WhatsAppKey                       string
 	WhatsAppDeviceID                  string
@@ -336,6 +447,7 @@ type Config struct {
 	RedisTLSInsecure                  bool
 	AsynqQueueName                    string
 	AsynqConcurrency                  int
+	AsynqOrgConcurrency               int
 	SMTPEncryptionKey                 string
 	IMAPEncryptionKey                 string
 	ExportsEncryptionKey              string
@@ -344,6 +456,13 @@ type Config struct {
 	MoneybirdRedirectURI              string
 	MoneybirdFrontendURL              string
 	MoneybirdEncryptionKey            string
+	SecretsBackend                    string
+	SecretsFileDir                    string
+	VaultAddr                         string
+	VaultToken                        string
+	VaultMount                        string
+	VaultSecretPath                   string
+	AWSRegion                         string
 	LeadsReconciliationEnabled        bool
 	BootstrapSuperAdminEmail          string
 	WebAuthnRPID                      string
@@ -407,36 +526,54 @@ func (c *Config) GetWhatsAppAgentStreamingEnabled() bool {
 	return c.WhatsAppAgentStreamingEnabled
 }
 
-// SMTPConfig getter
-func (c *Config) GetSMTPEncryptionKey() string { return c.SMTPEncryptionKey }
-
 // IMAPConfig getter
 func (c *Config) GetIMAPEncryptionKey() string { return c.IMAPEncryptionKey }
 
-// ExportsConfig getter
-func (c *Config) GetExportsEncryptionKey() string { return c.ExportsEncryptionKey }
-
 // Moneybird config getters
-func (c *Config) GetMoneybirdClientID() string      { return c.MoneybirdClientID }
-func (c *Config) GetMoneybirdClientSecret() string  { return c.MoneybirdClientSecret }
-func (c *Config) GetMoneybirdRedirectURI() string   { return c.MoneybirdRedirectURI }
-func (c *Config) GetMoneybirdFrontendURL() string   { return c.MoneybirdFrontendURL }
-func (c *Config) GetMoneybirdEncryptionKey() string { return c.MoneybirdEncryptionKey }
+func (c *Config) GetMoneybirdClientID() string     { return c.MoneybirdClientID }
+func (c *Config) GetMoneybirdClientSecret() string { return c.MoneybirdClientSecret }
+func (c *Config) GetMoneybirdRedirectURI() string  { return c.MoneybirdRedirectURI }
+func (c *Config) GetMoneybirdFrontendURL() string  { return c.MoneybirdFrontendURL }
+
+// SecretsConfig implementation
+func (c *Config) GetSecretsBackend() string  { return c.SecretsBackend }
+func (c *Config) GetSecretsFileDir() string  { return c.SecretsFileDir }
+func (c *Config) GetVaultAddr() string       { return c.VaultAddr }
+func (c *Config) GetVaultToken() string      { return c.VaultToken }
+func (c *Config) GetVaultMount() string      { return c.VaultMount }
+func (c *Config) GetVaultSecretPath() string { return c.VaultSecretPath }
+func (c *Config) GetAWSRegion() string       { return c.AWSRegion }
 
 // IsLeadsReconciliationEnabled controls the LeadService state reconciliation engine.
 func (c *Config) IsLeadsReconciliationEnabled() bool { return c.LeadsReconciliationEnabled }
 
 // SchedulerConfig implementation
-func (c *Config) GetRedisURL() string       { return c.RedisURL }
-func (c *Config) GetRedisTLSInsecure() bool { return c.RedisTLSInsecure }
-func (c *Config) GetAsynqQueueName() string { return c.AsynqQueueName }
-func (c *Config) GetAsynqConcurrency() int  { return c.AsynqConcurrency }
+func (c *Config) GetRedisURL() string         { return c.RedisURL }
+func (c *Config) GetRedisTLSInsecure() bool   { return c.RedisTLSInsecure }
+func (c *Config) GetAsynqQueueName() string   { return c.AsynqQueueName }
+func (c *Config) GetAsynqConcurrency() int    { return c.AsynqConcurrency }
+func (c *Config) GetAsynqOrgConcurrency() int { return c.AsynqOrgConcurrency }
 
 // HTTPConfig implementation
-func (c *Config) GetHTTPAddr() string      { return c.HTTPAddr }
-func (c *Config) GetCORSAllowAll() bool    { return c.CORSAllowAll }
-func (c *Config) GetCORSOrigins() []string { return c.CORSOrigins }
-func (c *Config) GetCORSAllowCreds() bool  { return c.CORSAllowCreds }
+func (c *Config) GetHTTPAddr() string             { return c.HTTPAddr }
+func (c *Config) GetCORSAllowAll() bool           { return c.CORSAllowAll }
+func (c *Config) GetCORSOrigins() []string        { return c.CORSOrigins }
+func (c *Config) GetCORSAllowCreds() bool         { return c.CORSAllowCreds }
+func (c *Config) GetAPIV1DeprecatedAt() time.Time { return c.APIV1DeprecatedAt }
+func (c *Config) GetAPIV1SunsetAt() time.Time     { return c.APIV1SunsetAt }
+func (c *Config) GetHTTPMaxBodyBytes() int64      { return c.HTTPMaxBodyBytes }
+func (c *Config) GetHTTPMaxUploadBodyBytes() int64 {
+	return c.HTTPMaxUploadBodyBytes
+}
+func (c *Config) GetHTTPBodyLimits() map[string]int64 { return c.HTTPBodyLimits }
+func (c *Config) GetTrustedProxies() []netip.Prefix   { return c.TrustedProxies }
+
+// GRPCConfig implementation
+func (c *Config) GetGRPCAddr() string             { return c.GRPCAddr }
+func (c *Config) GetGRPCTLSCertFile() string      { return c.GRPCTLSCertFile }
+func (c *Config) GetGRPCTLSKeyFile() string       { return c.GRPCTLSKeyFile }
+func (c *Config) GetGRPCTLSClientCAFile() string  { return c.GRPCTLSClientCAFile }
+func (c *Config) GetGRPCAllowedClients() []string { return c.GRPCAllowedClients }
 
 // MinIOConfig implementation
 func (c *Config) GetMinIOEndpoint() string   { return c.MinIOEndpoint }
@@ -462,8 +599,46 @@ func (c *Config) GetMinioBucketQuotePDFs() string {
 func (c *Config) GetMinioBucketQuoteAttachments() string {
 	return c.MinioBucketQuoteAttachments
 }
+func (c *Config) GetMinioBucketFiles() string {
+	return c.MinioBucketFiles
+}
+
+// GetMinioBucketAuditExports returns the bucket compliance exports are
+// written to. It is left out of GetMinioBuckets so exports do not count
+// towards the storage quota of an organization.
+func (c *Config) GetMinioBucketAuditExports() string {
+	return c.MinioBucketAuditExports
+}
+
+// GetMinioBuckets returns every configured bucket that holds organization data.
+func (c *Config) GetMinioBuckets() []string {
+	return []string{
+		c.MinioBucketLeadServiceAttachments,
+		c.MinioBucketCatalogAssets,
+		c.MinioBucketPartnerLogos,
+		c.MinioBucketOrganizationLogos,
+		c.MinioBucketQuotePDFs,
+		c.MinioBucketQuoteAttachments,
+		c.MinioBucketFiles,
+	}
+}
 func (c *Config) IsMinIOEnabled() bool { return c.MinIOEndpoint != "" }
 
+// StorageQuotaConfig implementation
+func (c *Config) GetStorageQuotaSoftLimitBytes() int64 { return c.StorageQuotaSoftLimitBytes }
+func (c *Config) GetStorageQuotaHardLimitBytes() int64 { return c.StorageQuotaHardLimitBytes }
+
+// BillingConfig implementation
+func (c *Config) GetBillingDefaultPlan() string        { return c.BillingDefaultPlan }
+func (c *Config) GetBillingGracePeriod() time.Duration { return c.BillingGracePeriod }
+func (c *Config) GetStripeSecretKey() string           { return c.StripeSecretKey }
+func (c *Config) GetStripeWebhookSecret() string       { return c.StripeWebhookSecret }
+func (c *Config) GetBillingMollieAPIKey() string       { return c.BillingMollieAPIKey }
+
+// ResumableUploadConfig implementation
+func (c *Config) GetUploadPartSizeBytes() int64      { return c.UploadPartSizeBytes }
+func (c *Config) GetUploadSessionTTL() time.Duration { return c.UploadSessionTTL }
+
 // GotenbergConfig implementation
 func (c *Config) GetGotenbergURL() string      { return c.GotenbergURL }
 func (c *Config) GetGotenbergUsername() string { return c.GotenbergUsername }
@@ -474,6 +649,28 @@ func (c *Config) IsGotenbergEnabled() bool     { return c.GotenbergURL != "" }
 func (c *Config) GetEPOnlineAPIKey() string  { return c.EPOnlineAPIKey }
 func (c *Config) IsEnergyLabelEnabled() bool { return c.EPOnlineAPIKey != "" }
 
+// KvKConfig implementation
+func (c *Config) GetKvKAPIKey() string     { return c.KvKAPIKey }
+func (c *Config) GetKvKAPIBaseURL() string { return c.KvKAPIBaseURL }
+func (c *Config) IsKvKEnabled() bool       { return c.KvKAPIKey != "" }
+
+// GeocodingConfig implementation
+func (c *Config) GetGeocodingProviders() []string  { return c.GeocodingProviders }
+func (c *Config) GetGoogleGeocodingAPIKey() string { return c.GoogleGeocodingAPIKey }
+
+// GetGeocodingDailyLimit returns the requests per UTC day a provider may
+// serve. PDOK has no quota.
+func (c *Config) GetGeocodingDailyLimit(provider string) int {
+	switch provider {
+	case "nominatim":
+		return c.GeocodingNominatimDailyLimit
+	case "google":
+		return c.GeocodingGoogleDailyLimit
+	default:
+		return 0
+	}
+}
+
 // ResolveLLMModel returns an explicit per-agent or global model override.
 // When no explicit override is configured it returns "" so the caller can
 // fall back to the provider preset's own default model.
@@ -581,6 +778,31 @@ func ProviderSupportsVision(provider string) bool {
 	return ok && preset.SupportsVision
 }
 
+// IsLLMProvider reports whether the given name is a known provider identifier.
+func IsLLMProvider(provider string) bool {
+	_, ok := llmProviderPresets[provider]
+	return ok
+}
+
+// LLMProviderIDs returns the known provider identifiers in sorted order.
+func LLMProviderIDs() []string {
+	ids := make([]string, 0, len(llmProviderPresets))
+	for id := range llmProviderPresets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LLMProviderModels returns the model names served by the given provider, or nil for unknown providers.
+func LLMProviderModels(provider string) []string {
+	preset, ok := llmProviderPresets[provider]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), preset.Models...)
+}
+
 // ResolveVisionProvider returns a provider config that supports vision.
 // If the primary provider already supports vision, it is returned as-is.
 // Otherwise, the first vision-capable provider with a configured API key is used.
@@ -630,6 +852,9 @@ func (c *Config) resolveAPIKey(provider string) string {
 func (c *Config) GetQdrantURL() string        { return c.QdrantURL }
 func (c *Config) GetQdrantAPIKey() string     { return c.QdrantAPIKey }
 func (c *Config) GetQdrantCollection() string { return c.QdrantCollection }
+
+// GetQdrantTenancyMode returns how tenant-owned catalog vectors are isolated ("shared" or "per_tenant").
+func (c *Config) GetQdrantTenancyMode() string { return c.QdrantTenancyMode }
 func (c *Config) IsQdrantEnabled() bool {
 	return c.QdrantURL != "" && c.QdrantCollection != ""
 }
@@ -645,6 +870,14 @@ func (c *Config) GetCatalogEmbeddingAPIKey() string { return c.CatalogEmbeddingA
 func (c *Config) GetCatalogEmbeddingCollection() string {
 	return c.CatalogEmbeddingCollection
 }
+func (c *Config) GetCatalogEmbeddingModel() string {
+	return c.CatalogEmbeddingModel
+}
+
+// GetCatalogEmbeddingVersion returns the catalog index version; bumping it re-embeds all products.
+func (c *Config) GetCatalogEmbeddingVersion() string {
+	return c.CatalogEmbeddingVersion
+}
 func (c *Config) GetBouwmaatEmbeddingCollection() string {
 	return c.BouwmaatEmbeddingCollection
 }
@@ -652,6 +885,13 @@ func (c *Config) IsCatalogEmbeddingEnabled() bool {
 	return c.CatalogEmbeddingAPIURL != ""
 }
 
+// CircuitBreakerConfig implementation
+func (c *Config) GetCircuitBreakerFailureThreshold() int { return c.CircuitBreakerFailureThreshold }
+func (c *Config) GetCircuitBreakerOpenTimeout() time.Duration {
+	return c.CircuitBreakerOpenTimeout
+}
+func (c *Config) GetCircuitBreakerHalfOpenProbes() int { return c.CircuitBreakerHalfOpenProbes }
+
 // Load reads configuration from environment variables.
 func Load() (*Config, error) {
 	_ = godotenv.Load()
@@ -688,6 +928,15 @@ func Load() (*Config, error) {
 		CORSAllowAll:                      corsAllowAll,
 		CORSOrigins:                       corsOrigins,
 		CORSAllowCreds:                    strings.EqualFold(getEnv("CORS_ALLOW_CREDENTIALS", "true"), "true"),
+		APIV1DeprecatedAt:                 mustDate(getEnv("API_V1_DEPRECATED_AT", "")),
+		APIV1SunsetAt:                     mustDate(getEnv("API_V1_SUNSET_AT", "")),
+		HTTPMaxBodyBytes:                  mustInt64(getEnv("HTTP_MAX_BODY_BYTES", "10485760")),
+		HTTPMaxUploadBodyBytes:            mustInt64(getEnv("HTTP_MAX_UPLOAD_BODY_BYTES", "0")),
+		GRPCAddr:                          strings.TrimSpace(getEnv("GRPC_ADDR", "")),
+		GRPCTLSCertFile:                   getEnv("GRPC_TLS_CERT_FILE", ""),
+		GRPCTLSKeyFile:                    getEnv("GRPC_TLS_KEY_FILE", ""),
+		GRPCTLSClientCAFile:               getEnv("GRPC_TLS_CLIENT_CA_FILE", ""),
+		GRPCAllowedClients:                splitCSV(getEnv("GRPC_ALLOWED_CLIENTS", "")),
 		AppBaseURL:                        appBaseURL,
 		PublicBaseURL:                     publicBaseURL,
 		PublicAPIBaseURL:                  publicAPIBaseURL,
@@ -714,6 +963,12 @@ func Load() (*Config, error) {
 		LLMModelWhatsAppReply:             getEnv("LLM_MODEL_WHATSAPP_REPLY", ""),
 		LLMModelWhatsAppAgent:             getEnv("LLM_MODEL_WHATSAPP_AGENT", ""),
 		EPOnlineAPIKey:                    getEnv("EP_ONLINE_API_KEY", ""),
+		KvKAPIKey:                         getEnv("KVK_API_KEY", ""),
+		KvKAPIBaseURL:                     getEnv("KVK_API_BASE_URL", "https://api.kvk.nl"),
+		GeocodingProviders:                splitCSV(getEnv("GEOCODING_PROVIDERS", "pdok,nominatim,google")),
+		GoogleGeocodingAPIKey:             getEnv("GOOGLE_GEOCODING_API_KEY", ""),
+		GeocodingNominatimDailyLimit:      mustInt(getEnv("GEOCODING_NOMINATIM_DAILY_LIMIT", "5000")),
+		GeocodingGoogleDailyLimit:         mustInt(getEnv("GEOCODING_GOOGLE_DAILY_LIMIT", "1000")),
 		MinIOEndpoint:                     getEnv("MINIO_ENDPOINT", ""),
 		MinIOAccessKey:                    getEnv("MINIO_ACCESS_KEY", ""),
 		MinIOSecretKey:                    getEnv("MINIO_SECRET_KEY", ""),
@@ -725,18 +980,35 @@ func Load() (*Config, error) {
 		MinioBucketOrganizationLogos:      getEnv("MINIO_BUCKET_ORGANIZATION_LOGOS", "organization-logos"),
 		MinioBucketQuotePDFs:              getEnv("MINIO_BUCKET_QUOTE_PDFS", "quote-pdfs"),
 		MinioBucketQuoteAttachments:       getEnv("MINIO_BUCKET_QUOTE_ATTACHMENTS", "quote-attachments"),
+		MinioBucketFiles:                  getEnv("MINIO_BUCKET_FILES", "files"),
+		MinioBucketAuditExports:           getEnv("MINIO_BUCKET_AUDIT_EXPORTS", "audit-exports"),
+		StorageQuotaSoftLimitBytes:        mustInt64(getEnv("STORAGE_QUOTA_SOFT_LIMIT_BYTES", "0")),
+		StorageQuotaHardLimitBytes:        mustInt64(getEnv("STORAGE_QUOTA_HARD_LIMIT_BYTES", "0")),
+		BillingDefaultPlan:                getEnv("BILLING_DEFAULT_PLAN", ""),
+		BillingGracePeriod:                mustDuration(getEnv("BILLING_GRACE_PERIOD", "168h")),
+		StripeSecretKey:                   getEnv("STRIPE_SECRET_KEY", ""),
+		StripeWebhookSecret:               getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		BillingMollieAPIKey:               getEnv("BILLING_MOLLIE_API_KEY", ""),
+		UploadPartSizeBytes:               mustInt64(getEnv("UPLOAD_PART_SIZE_BYTES", "8388608")),
+		UploadSessionTTL:                  mustDuration(getEnv("UPLOAD_SESSION_TTL", "24h")),
 		GotenbergURL:                      getEnv("GOTENBERG_URL", ""),
 		GotenbergUsername:                 getEnv("GOTENBERG_USERNAME", ""),
 		GotenbergPassword:                 getEnv("GOTENBERG_PASSWORD", ""),
 		QdrantURL:                         getEnv("QDRANT_URL", ""),
 		QdrantAPIKey:                      getEnv("QDRANT_API_KEY", ""),
 		QdrantCollection:                  getEnv("QDRANT_COLLECTION", ""),
+		QdrantTenancyMode:                 getEnv("QDRANT_TENANCY_MODE", "shared"),
 		EmbeddingAPIURL:                   getEnv("EMBEDDING_API_URL", ""),
 		EmbeddingAPIKey:                   getEnv("EMBEDDING_API_KEY", ""),
 		CatalogEmbeddingAPIURL:            getEnv("CATALOG_EMBEDDING_API_URL", ""),
 		CatalogEmbeddingAPIKey:            getEnv("CATALOG_EMBEDDING_API_KEY", ""),
 		CatalogEmbeddingCollection:        getEnv("CATALOG_EMBEDDING_COLLECTION", "catalog"),
+		CatalogEmbeddingModel:             getEnv("CATALOG_EMBEDDING_MODEL", "bge-m3"),
+		CatalogEmbeddingVersion:           getEnv("CATALOG_EMBEDDING_VERSION", "1"),
 		BouwmaatEmbeddingCollection:       getEnv("BOUWMAAT_EMBEDDING_COLLECTION", "bouwmaat_products"),
+		CircuitBreakerFailureThreshold:    mustInt(getEnv("CIRCUIT_BREAKER_FAILURE_THRESHOLD", "5")),
+		CircuitBreakerOpenTimeout:         mustDuration(getEnv("CIRCUIT_BREAKER_OPEN_TIMEOUT", "30s")),
+		CircuitBreakerHalfOpenProbes:      mustInt(getEnv("CIRCUIT_BREAKER_HALF_OPEN_PROBES", "1")),
 		WhatsAppURL:                       getEnv("WHATSAPP_SERVICE_URL", ""),
 		WhatsAppKey:                       getEnv("WHATSAPP_API_KEY", ""),
 		WhatsAppDeviceID:                  getEnv("WHATSAPP_DEVICE_ID", ""),
@@ -745,6 +1017,7 @@ func Load() (*Config, error) {
 		RedisTLSInsecure:                  strings.EqualFold(getEnv("REDIS_TLS_INSECURE", "false"), "true"),
 		AsynqQueueName:                    getEnv("ASYNQ_QUEUE_NAME", "default"),
 		AsynqConcurrency:                  mustInt(getEnv("ASYNQ_CONCURRENCY", "10")),
+		AsynqOrgConcurrency:               mustInt(getEnv("ASYNQ_ORG_CONCURRENCY", "3")),
 		SMTPEncryptionKey:                 getEnv("SMTP_ENCRYPTION_KEY", ""),
 		IMAPEncryptionKey:                 getEnv("IMAP_ENCRYPTION_KEY", ""),
 		ExportsEncryptionKey:              getEnv("EXPORTS_ENCRYPTION_KEY", ""),
@@ -753,6 +1026,13 @@ func Load() (*Config, error) {
 		MoneybirdRedirectURI:              getEnv("MONEYBIRD_REDIRECT_URI", ""),
 		MoneybirdFrontendURL:              getEnv("MONEYBIRD_FRONTEND_URL", appBaseURL),
 		MoneybirdEncryptionKey:            getEnv("MONEYBIRD_ENCRYPTION_KEY", ""),
+		SecretsBackend:                    strings.ToLower(getEnv("SECRETS_BACKEND", "env")),
+		SecretsFileDir:                    getEnv("SECRETS_FILE_DIR", "/run/secrets"),
+		VaultAddr:                         getEnv("VAULT_ADDR", ""),
+		VaultToken:                        getEnv("VAULT_TOKEN", ""),
+		VaultMount:                        getEnv("VAULT_MOUNT", "secret"),
+		VaultSecretPath:                   getEnv("VAULT_SECRET_PATH", ""),
+		AWSRegion:                         getEnv("AWS_REGION", ""),
 		LeadsReconciliationEnabled:        strings.EqualFold(getEnv("LEADS_RECONCILIATION_ENABLED", "true"), "true"),
 		BootstrapSuperAdminEmail:          strings.TrimSpace(getEnv("BOOTSTRAP_SUPERADMIN_EMAIL", "")),
 		WebAuthnRPID:                      getEnv("WEBAUTHN_RP_ID", "localhost"),
@@ -772,9 +1052,29 @@ func Load() (*Config, error) {
 	if cfg.EmailEnabled && cfg.EmailFromAddress == "" {
 		return nil, fmt.Errorf("EMAIL_FROM_ADDRESS is required when email is enabled")
 	}
+	if cfg.GRPCAddr != "" && (cfg.GRPCTLSCertFile == "" || cfg.GRPCTLSKeyFile == "" || cfg.GRPCTLSClientCAFile == "") {
+		return nil, fmt.Errorf("GRPC_TLS_CERT_FILE, GRPC_TLS_KEY_FILE and GRPC_TLS_CLIENT_CA_FILE are required when GRPC_ADDR is set")
+	}
 	if cfg.CORSAllowAll && cfg.CORSAllowCreds {
 		return nil, fmt.Errorf("CORS_ALLOW_CREDENTIALS cannot be true when CORS_ALLOW_ALL is true")
 	}
+	if cfg.HTTPMaxBodyBytes <= 0 {
+		return nil, fmt.Errorf("HTTP_MAX_BODY_BYTES must be a positive number of bytes")
+	}
+	if cfg.HTTPMaxUploadBodyBytes <= 0 {
+		// Leave room for the multipart framing around a maximum size file.
+		cfg.HTTPMaxUploadBodyBytes = cfg.MinIOMaxFileSize + 1<<20
+	}
+	bodyLimits, err := parseBodyLimits(getEnv("HTTP_BODY_LIMITS", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.HTTPBodyLimits = bodyLimits
+	trustedProxies, err := parseTrustedProxies(getEnv("TRUSTED_PROXIES", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.TrustedProxies = trustedProxies
 	for _, warning := range cfg.LLMSelectorWarnings() {
 		_, _ = fmt.Fprintf(os.Stderr, "config warning: %s\n", warning)
 	}
@@ -797,6 +1097,23 @@ func mustDuration(value string) time.Duration {
 	return d
 }
 
+// mustDate parses a YYYY-MM-DD or RFC 3339 timestamp, returning the zero time when the
+// value is empty or invalid.
+func mustDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 func mustInt64(value string) int64 {
 	result, err := strconv.ParseInt(value, 10, 64)
 	if err != nil {
@@ -825,6 +1142,39 @@ func splitCSV(value string) []string {
 	return results
 }
 
+// parseBodyLimits parses "pattern=bytes" pairs separated by commas, e.g.
+// "/api/v1/leads/*=1048576,*/upload=209715200".
+func parseBodyLimits(value string) (map[string]int64, error) {
+	limits := make(map[string]int64)
+	for _, entry := range splitCSV(value) {
+		pattern, size, ok := strings.Cut(entry, "=")
+		limit, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+		if !ok || strings.TrimSpace(pattern) == "" || err != nil || limit <= 0 {
+			return nil, fmt.Errorf("HTTP_BODY_LIMITS entry %q must be pattern=bytes", entry)
+		}
+		limits[strings.TrimSpace(pattern)] = limit
+	}
+	return limits, nil
+}
+
+// parseTrustedProxies parses a comma separated list of IP addresses and CIDR
+// ranges.
+func parseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range splitCSV(value) {
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES entry %q must be an IP address or CIDR range", entry)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
 func containsWildcard(values []string) bool {
 	for _, value := range values {
 		if value == "*" {
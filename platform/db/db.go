@@ -35,6 +35,11 @@ func NewPool(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, err
 	poolConfig.MaxConnIdleTime = 30 * time.Minute  // Maximum idle time before closing
 	poolConfig.HealthCheckPeriod = 1 * time.Minute // Health check interval
 
+	// Organizations with a dedicated schema are routed through the search_path.
+	router := newSchemaRouter()
+	poolConfig.PrepareConn = router.prepare
+	poolConfig.BeforeClose = router.forget
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type schemaContextKey struct{}
+
+var schemaNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// WithSchema routes the queries made with the returned context to a dedicated
+// schema. Unqualified table names resolve to the schema first and to public
+// for everything the schema does not hold.
+func WithSchema(ctx context.Context, schema string) context.Context {
+	return context.WithValue(ctx, schemaContextKey{}, schema)
+}
+
+// SchemaFromContext returns the dedicated schema of the context, or "" for the
+// shared public schema.
+func SchemaFromContext(ctx context.Context) string {
+	schema, _ := ctx.Value(schemaContextKey{}).(string)
+	return schema
+}
+
+// SchemaLister returns the dedicated schemas of all organizations.
+type SchemaLister func(ctx context.Context) ([]string, error)
+
+var (
+	schemaListerMu sync.RWMutex
+	schemaLister   SchemaLister
+)
+
+// SetSchemaLister sets how ForEachSchema finds the dedicated schemas. Without
+// a lister only the shared schema is visited.
+func SetSchemaLister(lister SchemaLister) {
+	schemaListerMu.Lock()
+	schemaLister = lister
+	schemaListerMu.Unlock()
+}
+
+// ForEachSchema runs fn against the shared schema and then against every
+// dedicated schema, for maintenance jobs that scan the data of all
+// organizations. A failing run does not stop the others; their errors are
+// joined.
+func ForEachSchema(ctx context.Context, fn func(ctx context.Context) error) error {
+	errs := []error{fn(WithSchema(ctx, ""))}
+
+	schemaListerMu.RLock()
+	lister := schemaLister
+	schemaListerMu.RUnlock()
+	if lister == nil {
+		return errs[0]
+	}
+	schemas, err := lister(WithSchema(ctx, ""))
+	if err != nil {
+		return errors.Join(errs[0], err)
+	}
+	for _, schema := range schemas {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		errs = append(errs, fn(WithSchema(ctx, schema)))
+	}
+	return errors.Join(errs...)
+}
+
+// ValidSchemaName reports whether name is a plain lower case identifier that can
+// be used as a schema name without quoting surprises.
+func ValidSchemaName(name string) bool {
+	return schemaNamePattern.MatchString(name)
+}
+
+// schemaRouter keeps the search_path of pooled connections in line with the
+// schema of the context that acquires them. Connections only pay a round trip
+// when they switch between schemas.
+type schemaRouter struct {
+	mu      sync.Mutex
+	current map[*pgx.Conn]string
+}
+
+func newSchemaRouter() *schemaRouter {
+	return &schemaRouter{current: make(map[*pgx.Conn]string)}
+}
+
+func (r *schemaRouter) prepare(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	want := SchemaFromContext(ctx)
+
+	r.mu.Lock()
+	have := r.current[conn]
+	r.mu.Unlock()
+	if want == have {
+		return true, nil
+	}
+
+	var err error
+	if want == "" {
+		_, err = conn.Exec(ctx, "RESET search_path")
+	} else {
+		_, err = conn.Exec(ctx, "SELECT set_config('search_path', $1, false)", pgx.Identifier{want}.Sanitize()+", public")
+	}
+	if err != nil {
+		// Destroy the connection: its search_path is unknown now.
+		return false, err
+	}
+
+	r.mu.Lock()
+	r.current[conn] = want
+	r.mu.Unlock()
+	return true, nil
+}
+
+func (r *schemaRouter) forget(conn *pgx.Conn) {
+	r.mu.Lock()
+	delete(r.current, conn)
+	r.mu.Unlock()
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestForEachSchemaVisitsSharedAndDedicatedSchemas(t *testing.T) {
+	t.Cleanup(func() { SetSchemaLister(nil) })
+	SetSchemaLister(func(context.Context) ([]string, error) {
+		return []string{"tenant_a", "tenant_b"}, nil
+	})
+
+	var visited []string
+	failure := errors.New("tenant_a failed")
+	err := ForEachSchema(WithSchema(context.Background(), "tenant_b"), func(ctx context.Context) error {
+		schema := SchemaFromContext(ctx)
+		visited = append(visited, schema)
+		if schema == "tenant_a" {
+			return failure
+		}
+		return nil
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("ForEachSchema() error = %v, want %v", err, failure)
+	}
+	if want := []string{"", "tenant_a", "tenant_b"}; !slices.Equal(visited, want) {
+		t.Fatalf("visited = %q, want %q", visited, want)
+	}
+}
+
+func TestForEachSchemaWithoutListerVisitsSharedSchema(t *testing.T) {
+	SetSchemaLister(nil)
+
+	var visited []string
+	err := ForEachSchema(context.Background(), func(ctx context.Context) error {
+		visited = append(visited, SchemaFromContext(ctx))
+		return nil
+	})
+	if err != nil || !slices.Equal(visited, []string{""}) {
+		t.Fatalf("ForEachSchema() = %v, visited %q", err, visited)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"sync"
 
+	"portal_final_backend/platform/db"
 	"portal_final_backend/platform/logger"
 )
 
@@ -17,6 +18,7 @@ type InMemoryBus struct {
 	handlers map[string][]Handler
 	log      *logger.Logger
 	wg       sync.WaitGroup
+	guard    HandlerGuard
 }
 
 // NewInMemoryBus creates a new in-memory event bus.
@@ -27,6 +29,29 @@ func NewInMemoryBus(log *logger.Logger) *InMemoryBus {
 	}
 }
 
+// SetHandlerGuard sets the guard that runs handlers for deliveries of stored
+// events, i.e. contexts carrying an event ID.
+func (b *InMemoryBus) SetHandlerGuard(guard HandlerGuard) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.guard = guard
+}
+
+// handle runs handler for event, through the guard when ctx delivers a
+// stored event.
+func (b *InMemoryBus) handle(ctx context.Context, guard HandlerGuard, key string, handler Handler, event Event) error {
+	eventID, stored := EventIDFromContext(ctx)
+	if !stored {
+		return handler.Handle(ctx, event)
+	}
+	if guard == nil {
+		return handler.Handle(withoutEventID(ctx), event)
+	}
+	return guard(ctx, eventID, key, func(ctx context.Context) error {
+		return handler.Handle(withoutEventID(ctx), event)
+	})
+}
+
 // Publish sends an event to all registered handlers asynchronously.
 // Errors are logged but do not propagate back to the publisher.
 // Note: Uses context.Background() so handlers aren't canceled when
@@ -34,6 +59,7 @@ func NewInMemoryBus(log *logger.Logger) *InMemoryBus {
 func (b *InMemoryBus) Publish(ctx context.Context, event Event) {
 	b.mu.RLock()
 	handlers := b.handlers[event.EventName()]
+	guard := b.guard
 	b.mu.RUnlock()
 
 	if len(handlers) == 0 {
@@ -41,18 +67,28 @@ func (b *InMemoryBus) Publish(ctx context.Context, event Event) {
 	}
 
 	// Execute all handlers asynchronously with a background context
-	// to prevent cancellation when the original request completes
-	for _, h := range handlers {
+	// to prevent cancellation when the original request completes. The data
+	// schema of the publisher carries over, so handlers of an organization
+	// with a dedicated schema read and write its data.
+	handlerCtx := context.Background()
+	if schema := db.SchemaFromContext(ctx); schema != "" {
+		handlerCtx = db.WithSchema(handlerCtx, schema)
+	}
+	if eventID, ok := EventIDFromContext(ctx); ok {
+		handlerCtx = WithEventID(handlerCtx, eventID)
+	}
+	keys := handlerKeys(event.EventName(), handlers)
+	for i, h := range handlers {
 		b.wg.Add(1)
-		go func(handler Handler) {
+		go func(key string, handler Handler) {
 			defer b.wg.Done()
-			if err := handler.Handle(context.Background(), event); err != nil {
+			if err := b.handle(handlerCtx, guard, key, handler, event); err != nil {
 				b.log.Error("event handler failed",
 					"event", event.EventName(),
 					"error", err,
 				)
 			}
-		}(h)
+		}(keys[i], h)
 	}
 }
 
@@ -61,6 +97,7 @@ func (b *InMemoryBus) Publish(ctx context.Context, event Event) {
 func (b *InMemoryBus) PublishSync(ctx context.Context, event Event) error {
 	b.mu.RLock()
 	handlers := b.handlers[event.EventName()]
+	guard := b.guard
 	b.mu.RUnlock()
 
 	if len(handlers) == 0 {
@@ -70,18 +107,19 @@ func (b *InMemoryBus) PublishSync(ctx context.Context, event Event) error {
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(handlers))
 
-	for _, h := range handlers {
+	keys := handlerKeys(event.EventName(), handlers)
+	for i, h := range handlers {
 		wg.Add(1)
-		go func(handler Handler) {
+		go func(key string, handler Handler) {
 			defer wg.Done()
-			if err := handler.Handle(ctx, event); err != nil {
+			if err := b.handle(ctx, guard, key, handler, event); err != nil {
 				errChan <- err
 				b.log.Error("event handler failed",
 					"event", event.EventName(),
 					"error", err,
 				)
 			}
-		}(h)
+		}(keys[i], h)
 	}
 
 	wg.Wait()
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portal_final_backend/platform/db"
+	"portal_final_backend/platform/logger"
+)
+
+type testEvent struct {
+	BaseEvent
+}
+
+func (testEvent) EventName() string { return "test.event" }
+
+func TestPublishHandlesEventInSchemaOfPublisher(t *testing.T) {
+	bus := NewInMemoryBus(logger.New("development"))
+	schemas := make(chan string, 1)
+	bus.Subscribe(testEvent{}.EventName(), HandlerFunc(func(ctx context.Context, _ Event) error {
+		schemas <- db.SchemaFromContext(ctx)
+		return nil
+	}))
+
+	reqCtx, cancel := context.WithCancel(db.WithSchema(context.Background(), "tenant_abc"))
+	bus.Publish(reqCtx, testEvent{BaseEvent: NewBaseEvent()})
+	// The request may end before the handler runs.
+	cancel()
+
+	select {
+	case schema := <-schemas:
+		if schema != "tenant_abc" {
+			t.Fatalf("handler schema = %q, want tenant_abc", schema)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler did not run")
+	}
+}
+
+func TestPublishHandlesSharedSchemaEventsInPublic(t *testing.T) {
+	bus := NewInMemoryBus(logger.New("development"))
+	bus.Subscribe(testEvent{}.EventName(), HandlerFunc(func(ctx context.Context, _ Event) error {
+		if schema := db.SchemaFromContext(ctx); schema != "" {
+			t.Errorf("handler schema = %q, want shared schema", schema)
+		}
+		return nil
+	}))
+
+	bus.Publish(context.Background(), testEvent{BaseEvent: NewBaseEvent()})
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
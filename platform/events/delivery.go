@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type eventIDKey struct{}
+
+// WithEventID marks ctx as the delivery of a stored event with the given ID.
+// Stored events can be delivered more than once; the ID lets a HandlerGuard
+// skip handlers that already processed the event.
+func WithEventID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, eventIDKey{}, id)
+}
+
+// EventIDFromContext returns the ID of the stored event being delivered.
+func EventIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(eventIDKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
+
+// HandlerGuard runs a handler for a delivery of a stored event. key
+// identifies the handler among the subscribers of the event, so the guard can
+// record which handlers completed and skip them on redelivery.
+type HandlerGuard func(ctx context.Context, eventID uuid.UUID, key string, run func(context.Context) error) error
+
+// withoutEventID returns ctx for running a handler of a stored event. Events
+// the handler publishes in turn are new events, so they must not be guarded
+// under the ID of the event that caused them.
+func withoutEventID(ctx context.Context) context.Context {
+	if _, ok := EventIDFromContext(ctx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, eventIDKey{}, uuid.Nil)
+}
+
+// handlerKeys names each handler of an event by the event and its type,
+// numbered among handlers of the same type, e.g.
+// "quotes.quote.accepted/*notification.Module#0". Keys are stable as long as
+// the subscription order is.
+func handlerKeys(eventName string, handlers []Handler) []string {
+	keys := make([]string, len(handlers))
+	seen := make(map[string]int, len(handlers))
+	for i, h := range handlers {
+		typeName := fmt.Sprintf("%T", h)
+		keys[i] = fmt.Sprintf("%s/%s#%d", eventName, typeName, seen[typeName])
+		seen[typeName]++
+	}
+	return keys
+}
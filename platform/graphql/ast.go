@@ -0,0 +1,163 @@
+package graphql
+
+// Document is a parsed GraphQL request document.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query, mutation or subscription definition.
+type Operation struct {
+	Type         string
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet []Selection
+}
+
+// VariableDefinition declares an operation variable.
+type VariableDefinition struct {
+	Name    string
+	Type    *TypeRef
+	Default Value
+}
+
+// TypeRef is a type reference as written in a variable definition, e.g. [ID!]!.
+type TypeRef struct {
+	Name    string
+	Elem    *TypeRef
+	NonNull bool
+}
+
+// Selection is a field, fragment spread or inline fragment.
+type Selection interface {
+	isSelection()
+}
+
+// FieldSelection selects a field of the enclosing type.
+type FieldSelection struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+	Line         int
+	Column       int
+}
+
+// ResponseKey is the key the field is written under in the response.
+func (f *FieldSelection) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread includes a named fragment.
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+// InlineFragment includes a selection set, optionally restricted to a type.
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+}
+
+func (*FieldSelection) isSelection() {}
+func (*FragmentSpread) isSelection() {}
+func (*InlineFragment) isSelection() {}
+
+// Fragment is a named fragment definition.
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  []Selection
+}
+
+// Argument is a name/value pair passed to a field or directive.
+type Argument struct {
+	Name  string
+	Value Value
+}
+
+// Directive is a directive such as @include(if: $flag).
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+}
+
+// Value is a literal or variable in a document.
+type Value interface {
+	isValue()
+}
+
+// Variable references an operation variable.
+type Variable struct{ Name string }
+
+// IntValue is an integer literal.
+type IntValue struct{ Value int64 }
+
+// FloatValue is a float literal.
+type FloatValue struct{ Value float64 }
+
+// StringValue is a string literal.
+type StringValue struct{ Value string }
+
+// BooleanValue is a boolean literal.
+type BooleanValue struct{ Value bool }
+
+// NullValue is the null literal.
+type NullValue struct{}
+
+// EnumValue is an enum literal.
+type EnumValue struct{ Value string }
+
+// ListValue is a list literal.
+type ListValue struct{ Values []Value }
+
+// ObjectValue is an input object literal.
+type ObjectValue struct{ Fields map[string]Value }
+
+func (Variable) isValue()     {}
+func (IntValue) isValue()     {}
+func (FloatValue) isValue()   {}
+func (StringValue) isValue()  {}
+func (BooleanValue) isValue() {}
+func (NullValue) isValue()    {}
+func (EnumValue) isValue()    {}
+func (ListValue) isValue()    {}
+func (ObjectValue) isValue()  {}
+
+// valueToGo converts a document value into plain Go values, substituting variables.
+func valueToGo(v Value, vars map[string]any) any {
+	switch v := v.(type) {
+	case Variable:
+		return vars[v.Name]
+	case IntValue:
+		return v.Value
+	case FloatValue:
+		return v.Value
+	case StringValue:
+		return v.Value
+	case BooleanValue:
+		return v.Value
+	case EnumValue:
+		return v.Value
+	case ListValue:
+		out := make([]any, 0, len(v.Values))
+		for _, item := range v.Values {
+			out = append(out, valueToGo(item, vars))
+		}
+		return out
+	case ObjectValue:
+		out := make(map[string]any, len(v.Fields))
+		for name, item := range v.Fields {
+			out[name] = valueToGo(item, vars)
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package graphql
+
+import "context"
+
+// BatchFunc fetches the values for a set of keys in one round trip. Keys without a
+// value may be left out of the result; they resolve to the zero value.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects keys requested by resolvers at the same depth and fetches them with a
+// single BatchFunc call when the first returned Thunk is evaluated. Results are cached
+// for the lifetime of the loader, so create one per request. A Loader is not safe for
+// concurrent use; the executor evaluates resolvers sequentially.
+type Loader[K comparable, V any] struct {
+	ctx    context.Context
+	fetch  BatchFunc[K, V]
+	queue  []K
+	queued map[K]struct{}
+	cache  map[K]V
+	errs   map[K]error
+}
+
+// NewLoader creates a loader bound to the request context.
+func NewLoader[K comparable, V any](ctx context.Context, fetch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		ctx:    ctx,
+		fetch:  fetch,
+		queued: make(map[K]struct{}),
+		cache:  make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// Load queues key and returns a thunk that yields its value.
+func (l *Loader[K, V]) Load(key K) Thunk {
+	if _, cached := l.cache[key]; !cached {
+		if _, failed := l.errs[key]; !failed {
+			if _, ok := l.queued[key]; !ok {
+				l.queued[key] = struct{}{}
+				l.queue = append(l.queue, key)
+			}
+		}
+	}
+	return func() (any, error) {
+		return l.get(key)
+	}
+}
+
+func (l *Loader[K, V]) get(key K) (V, error) {
+	if _, ok := l.queued[key]; ok {
+		l.dispatch()
+	}
+	if err, failed := l.errs[key]; failed {
+		var zero V
+		return zero, err
+	}
+	return l.cache[key], nil
+}
+
+func (l *Loader[K, V]) dispatch() {
+	keys := l.queue
+	l.queue = nil
+	clear(l.queued)
+
+	values, err := l.fetch(l.ctx, keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		l.cache[key] = values[key]
+	}
+}
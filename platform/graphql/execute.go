@@ -0,0 +1,518 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Result is a GraphQL response. Data is nil when the request failed before execution.
+type Result struct {
+	Data   *OrderedMap `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error is a GraphQL error with its document location and response path.
+type Error struct {
+	Message   string     `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+	Path      []any      `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Location is a line and column in the request document.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// OrderedMap is a response object that keeps fields in selection order.
+type OrderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// Set stores value under key, appending the key on first use.
+func (m *OrderedMap) Set(key string, value any) {
+	if m.values == nil {
+		m.values = make(map[string]any)
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value stored under key.
+func (m *OrderedMap) Get(key string) (any, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// MarshalJSON encodes the fields in insertion order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Execute parses, validates and executes a query. Fields are resolved breadth-first:
+// every resolver at one depth runs before any deferred Thunk is evaluated, so loaders
+// see all keys of a level at once. Resolver errors null the field and are reported in
+// Errors; they do not propagate to the parent.
+func (s *Schema) Execute(ctx context.Context, req Request) *Result {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return &Result{Errors: []*Error{{Message: syntaxErr.Message, Locations: []Location{{Line: syntaxErr.Line, Column: syntaxErr.Column}}}}}
+		}
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+	vars, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{ctx: ctx, schema: s, doc: doc, vars: vars}
+	if errs := e.validate(op); len(errs) > 0 {
+		return &Result{Errors: errs}
+	}
+	data := e.run(op)
+	return &Result{Data: data, Errors: e.errors}
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	var op *Operation
+	switch {
+	case name != "":
+		for _, candidate := range doc.Operations {
+			if candidate.Name == name {
+				op = candidate
+			}
+		}
+		if op == nil {
+			return nil, fmt.Errorf("unknown operation %q", name)
+		}
+	case len(doc.Operations) > 1:
+		return nil, errors.New("operationName is required when the document contains multiple operations")
+	default:
+		op = doc.Operations[0]
+	}
+	if op.Type != "query" {
+		return nil, fmt.Errorf("%s operations are not supported", op.Type)
+	}
+	return op, nil
+}
+
+func coerceVariables(op *Operation, provided map[string]any) (map[string]any, error) {
+	vars := make(map[string]any, len(op.Variables))
+	for _, def := range op.Variables {
+		value, ok := provided[def.Name]
+		if !ok && def.Default != nil {
+			value = valueToGo(def.Default, nil)
+		}
+		if value == nil && def.Type.NonNull {
+			return nil, fmt.Errorf("variable $%s is required", def.Name)
+		}
+		vars[def.Name] = value
+	}
+	return vars, nil
+}
+
+type executor struct {
+	ctx    context.Context
+	schema *Schema
+	doc    *Document
+	vars   map[string]any
+	errors []*Error
+}
+
+// validate checks the selected operation against the schema before anything runs.
+func (e *executor) validate(op *Operation) []*Error {
+	var errs []*Error
+	var walk func(obj *Object, selections []Selection, depth int, fragments []string)
+	walk = func(obj *Object, selections []Selection, depth int, fragments []string) {
+		if e.schema.MaxDepth > 0 && depth > e.schema.MaxDepth {
+			errs = append(errs, &Error{Message: fmt.Sprintf("query exceeds the maximum depth of %d", e.schema.MaxDepth)})
+			return
+		}
+		for _, selection := range selections {
+			switch sel := selection.(type) {
+			case *FieldSelection:
+				errs = append(errs, e.validateField(obj, sel)...)
+				if def := obj.Fields[sel.Name]; def != nil {
+					if child, ok := namedType(def.Type).(*Object); ok && len(sel.SelectionSet) > 0 {
+						walk(child, sel.SelectionSet, depth+1, fragments)
+					}
+				}
+			case *FragmentSpread:
+				fragment := e.doc.Fragments[sel.Name]
+				switch {
+				case fragment == nil:
+					errs = append(errs, &Error{Message: fmt.Sprintf("unknown fragment %q", sel.Name)})
+				case slices.Contains(fragments, sel.Name):
+					errs = append(errs, &Error{Message: fmt.Sprintf("fragment %q spreads itself", sel.Name)})
+				case fragment.TypeCondition != obj.Name:
+					errs = append(errs, &Error{Message: fmt.Sprintf("fragment %q cannot be spread on type %q", sel.Name, obj.Name)})
+				default:
+					walk(obj, fragment.SelectionSet, depth, append(slices.Clone(fragments), sel.Name))
+				}
+			case *InlineFragment:
+				if sel.TypeCondition != "" && sel.TypeCondition != obj.Name {
+					errs = append(errs, &Error{Message: fmt.Sprintf("inline fragment on %q cannot be used on type %q", sel.TypeCondition, obj.Name)})
+					continue
+				}
+				walk(obj, sel.SelectionSet, depth, fragments)
+			}
+		}
+	}
+	walk(e.schema.Query, op.SelectionSet, 1, nil)
+	return errs
+}
+
+func (e *executor) validateField(obj *Object, field *FieldSelection) []*Error {
+	location := []Location{{Line: field.Line, Column: field.Column}}
+	if field.Name == "__typename" {
+		if len(field.SelectionSet) > 0 {
+			return []*Error{{Message: "field \"__typename\" must not have a selection", Locations: location}}
+		}
+		return nil
+	}
+	def := obj.Fields[field.Name]
+	if def == nil {
+		return []*Error{{Message: fmt.Sprintf("cannot query field %q on type %q", field.Name, obj.Name), Locations: location}}
+	}
+
+	var errs []*Error
+	if _, err := coerceArgs(def.Args, field.Arguments, e.vars); err != nil {
+		errs = append(errs, &Error{Message: fmt.Sprintf("field %q: %v", field.Name, err), Locations: location})
+	}
+	_, composite := namedType(def.Type).(*Object)
+	switch {
+	case composite && len(field.SelectionSet) == 0:
+		errs = append(errs, &Error{Message: fmt.Sprintf("field %q of type %s must have a selection of subfields", field.Name, def.Type), Locations: location})
+	case !composite && len(field.SelectionSet) > 0:
+		errs = append(errs, &Error{Message: fmt.Sprintf("field %q of type %s must not have a selection", field.Name, def.Type), Locations: location})
+	}
+	return errs
+}
+
+type objectTask struct {
+	obj        *Object
+	source     any
+	selections []Selection
+	out        *OrderedMap
+	path       []any
+}
+
+type fieldGroup struct {
+	key    string
+	fields []*FieldSelection
+}
+
+type fieldTask struct {
+	parent *objectTask
+	group  fieldGroup
+	def    *Field
+	value  any
+	err    error
+}
+
+func (e *executor) run(op *Operation) *OrderedMap {
+	root := &OrderedMap{}
+	level := []*objectTask{{obj: e.schema.Query, selections: op.SelectionSet, out: root}}
+	for len(level) > 0 {
+		if err := e.ctx.Err(); err != nil {
+			e.errors = append(e.errors, &Error{Message: err.Error()})
+			break
+		}
+
+		var tasks []*fieldTask
+		for _, parent := range level {
+			for _, group := range e.collectFields(parent.obj, parent.selections, nil) {
+				field := group.fields[0]
+				if field.Name == "__typename" {
+					parent.out.Set(group.key, parent.obj.Name)
+					continue
+				}
+				parent.out.Set(group.key, nil)
+				task := &fieldTask{parent: parent, group: group, def: parent.obj.Fields[field.Name]}
+				task.value, task.err = e.resolve(task.def, parent.source, field)
+				tasks = append(tasks, task)
+			}
+		}
+
+		var next []*objectTask
+		for _, task := range tasks {
+			if thunk, ok := task.value.(Thunk); ok && task.err == nil {
+				task.value, task.err = callThunk(thunk)
+			}
+			path := append(slices.Clone(task.parent.path), task.group.key)
+			field := task.group.fields[0]
+			if task.err != nil {
+				e.addError(task.err, field, path)
+				continue
+			}
+			var selections []Selection
+			for _, f := range task.group.fields {
+				selections = append(selections, f.SelectionSet...)
+			}
+			value, err := e.complete(task.def.Type, task.value, field, selections, path, &next)
+			if err != nil {
+				e.addError(err, field, path)
+				continue
+			}
+			task.parent.out.Set(task.group.key, value)
+		}
+		level = next
+	}
+	return root
+}
+
+func (e *executor) resolve(def *Field, source any, field *FieldSelection) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("graphql: resolver panic: %v", r)
+		}
+	}()
+	args, err := coerceArgs(def.Args, field.Arguments, e.vars)
+	if err != nil {
+		return nil, err
+	}
+	if def.Resolve == nil {
+		if m, ok := source.(map[string]any); ok {
+			return m[field.Name], nil
+		}
+		return nil, fmt.Errorf("graphql: field %q has no resolver", field.Name)
+	}
+	return def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+}
+
+func callThunk(thunk Thunk) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("graphql: resolver panic: %v", r)
+		}
+	}()
+	return thunk()
+}
+
+func (e *executor) complete(t Type, value any, field *FieldSelection, selections []Selection, path []any, next *[]*objectTask) (any, error) {
+	if nonNull, ok := t.(*NonNull); ok {
+		if isNull(value) {
+			return nil, fmt.Errorf("cannot return null for non-nullable field %q", field.Name)
+		}
+		t = nonNull.Of
+	}
+	if isNull(value) {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		return t.Serialize(indirect(value))
+	case *List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("field %q expected a list, got %T", field.Name, value)
+		}
+		items := make([]any, rv.Len())
+		for i := range items {
+			itemPath := append(slices.Clone(path), i)
+			item, err := e.complete(t.Of, rv.Index(i).Interface(), field, selections, itemPath, next)
+			if err != nil {
+				e.addError(err, field, itemPath)
+				continue
+			}
+			items[i] = item
+		}
+		return items, nil
+	case *Object:
+		out := &OrderedMap{}
+		*next = append(*next, &objectTask{obj: t, source: value, selections: selections, out: out, path: path})
+		return out, nil
+	default:
+		return nil, fmt.Errorf("field %q has unsupported type %s", field.Name, t)
+	}
+}
+
+func (e *executor) addError(err error, field *FieldSelection, path []any) {
+	message := err.Error()
+	if e.schema.FormatError != nil {
+		message = e.schema.FormatError(err)
+	}
+	e.errors = append(e.errors, &Error{
+		Message:   message,
+		Locations: []Location{{Line: field.Line, Column: field.Column}},
+		Path:      path,
+	})
+}
+
+// collectFields flattens fragments and groups fields by response key, in order.
+func (e *executor) collectFields(obj *Object, selections []Selection, groups []fieldGroup) []fieldGroup {
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *FieldSelection:
+			if !e.included(sel.Directives) {
+				continue
+			}
+			key := sel.ResponseKey()
+			idx := slices.IndexFunc(groups, func(g fieldGroup) bool { return g.key == key })
+			if idx < 0 {
+				groups = append(groups, fieldGroup{key: key, fields: []*FieldSelection{sel}})
+			} else {
+				groups[idx].fields = append(groups[idx].fields, sel)
+			}
+		case *FragmentSpread:
+			if e.included(sel.Directives) {
+				groups = e.collectFields(obj, e.doc.Fragments[sel.Name].SelectionSet, groups)
+			}
+		case *InlineFragment:
+			if e.included(sel.Directives) {
+				groups = e.collectFields(obj, sel.SelectionSet, groups)
+			}
+		}
+	}
+	return groups
+}
+
+// included evaluates @skip and @include.
+func (e *executor) included(directives []*Directive) bool {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			continue
+		}
+		for _, arg := range directive.Arguments {
+			if arg.Name != "if" {
+				continue
+			}
+			flag, _ := valueToGo(arg.Value, e.vars).(bool)
+			if flag == (directive.Name == "skip") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func coerceArgs(defs []Arg, provided []*Argument, vars map[string]any) (map[string]any, error) {
+	values := make(map[string]Value, len(provided))
+	for _, arg := range provided {
+		if !slices.ContainsFunc(defs, func(def Arg) bool { return def.Name == arg.Name }) {
+			return nil, fmt.Errorf("unknown argument %q", arg.Name)
+		}
+		values[arg.Name] = arg.Value
+	}
+
+	args := make(map[string]any, len(defs))
+	for _, def := range defs {
+		raw, ok := values[def.Name]
+		var value any
+		if ok {
+			if variable, isVar := raw.(Variable); isVar {
+				if _, declared := vars[variable.Name]; !declared {
+					return nil, fmt.Errorf("variable $%s is not defined", variable.Name)
+				}
+			}
+			value = valueToGo(raw, vars)
+		}
+		if value == nil {
+			if _, required := def.Type.(*NonNull); required && def.Default == nil {
+				return nil, fmt.Errorf("argument %q of type %s is required", def.Name, def.Type)
+			}
+			args[def.Name] = def.Default
+			continue
+		}
+		coerced, err := coerceInput(def.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", def.Name, err)
+		}
+		args[def.Name] = coerced
+	}
+	return args, nil
+}
+
+func coerceInput(t Type, value any) (any, error) {
+	switch t := t.(type) {
+	case *NonNull:
+		if value == nil {
+			return nil, errors.New("value must not be null")
+		}
+		return coerceInput(t.Of, value)
+	case *List:
+		if value == nil {
+			return nil, nil
+		}
+		items, ok := value.([]any)
+		if !ok {
+			items = []any{value}
+		}
+		out := make([]any, 0, len(items))
+		for _, item := range items {
+			coerced, err := coerceInput(t.Of, item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, coerced)
+		}
+		return out, nil
+	case *Scalar:
+		if value == nil {
+			return nil, nil
+		}
+		return t.Parse(value)
+	default:
+		return nil, fmt.Errorf("%s is not an input type", t)
+	}
+}
+
+// isNull reports whether a resolved value is null. Nil slices are empty lists, not null.
+func isNull(value any) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	return (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Map || rv.Kind() == reflect.Interface) && rv.IsNil()
+}
+
+func indirect(value any) any {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return rv.Interface()
+}
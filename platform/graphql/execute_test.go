@@ -0,0 +1,158 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type testAuthor struct {
+	ID   string
+	Name string
+}
+
+type testBook struct {
+	ID       string
+	Title    string
+	AuthorID string
+}
+
+func newTestSchema() *Schema {
+	books := []testBook{{ID: "b1", Title: "Dak", AuthorID: "a1"}, {ID: "b2", Title: "Gevel", AuthorID: "a2"}, {ID: "b3", Title: "Vloer", AuthorID: "a1"}}
+
+	author := &Object{Name: "Author", Fields: Fields{
+		"id":   {Type: NonNullOf(ID), Resolve: func(p ResolveParams) (any, error) { return p.Source.(testAuthor).ID, nil }},
+		"name": {Type: String, Resolve: func(p ResolveParams) (any, error) { return p.Source.(testAuthor).Name, nil }},
+	}}
+	book := &Object{Name: "Book", Fields: Fields{
+		"id":    {Type: NonNullOf(ID), Resolve: func(p ResolveParams) (any, error) { return p.Source.(testBook).ID, nil }},
+		"title": {Type: String, Resolve: func(p ResolveParams) (any, error) { return p.Source.(testBook).Title, nil }},
+		"author": {Type: author, Resolve: func(p ResolveParams) (any, error) {
+			return loaderFrom(p.Context).Load(p.Source.(testBook).AuthorID), nil
+		}},
+	}}
+	return &Schema{
+		MaxDepth: 3,
+		Query: &Object{Name: "Query", Fields: Fields{
+			"books": {
+				Type: NonNullOf(ListOf(NonNullOf(book))),
+				Args: []Arg{{Name: "first", Type: Int, Default: 10}},
+				Resolve: func(p ResolveParams) (any, error) {
+					return books[:min(p.Args["first"].(int), len(books))], nil
+				},
+			},
+		}},
+		FormatError: func(err error) string { return "masked: " + err.Error() },
+	}
+}
+
+type loaderKey struct{}
+
+func loaderFrom(ctx context.Context) *Loader[string, testAuthor] {
+	return ctx.Value(loaderKey{}).(*Loader[string, testAuthor])
+}
+
+func executeTestQuery(t *testing.T, query string, vars map[string]any) (*Result, [][]string) {
+	t.Helper()
+	var batches [][]string
+	authors := map[string]testAuthor{"a1": {ID: "a1", Name: "Annie"}, "a2": {ID: "a2", Name: "Bram"}}
+	ctx := context.Background()
+	loader := NewLoader(ctx, func(_ context.Context, keys []string) (map[string]testAuthor, error) {
+		batches = append(batches, slices.Clone(keys))
+		out := make(map[string]testAuthor, len(keys))
+		for _, key := range keys {
+			out[key] = authors[key]
+		}
+		return out, nil
+	})
+	ctx = context.WithValue(ctx, loaderKey{}, loader)
+	return newTestSchema().Execute(ctx, Request{Query: query, Variables: vars}), batches
+}
+
+func TestExecuteBatchesNestedFieldsAndKeepsSelectionOrder(t *testing.T) {
+	t.Parallel()
+
+	result, batches := executeTestQuery(t, `
+		query Books($first: Int) {
+			books(first: $first) {
+				title
+				...BookID
+				writer: author { name }
+				author { id }
+			}
+		}
+		fragment BookID on Book { id __typename }
+	`, map[string]any{"first": float64(3)})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", result.Errors[0])
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"data":{"books":[` +
+		`{"title":"Dak","id":"b1","__typename":"Book","writer":{"name":"Annie"},"author":{"id":"a1"}},` +
+		`{"title":"Gevel","id":"b2","__typename":"Book","writer":{"name":"Bram"},"author":{"id":"a2"}},` +
+		`{"title":"Vloer","id":"b3","__typename":"Book","writer":{"name":"Annie"},"author":{"id":"a1"}}]}}`
+	if string(body) != want {
+		t.Fatalf("unexpected response\n got: %s\nwant: %s", body, want)
+	}
+	if len(batches) != 1 || !slices.Equal(batches[0], []string{"a1", "a2"}) {
+		t.Fatalf("expected one batched author lookup, got %v", batches)
+	}
+}
+
+func TestExecuteRejectsInvalidDocuments(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		`{ books { title `:             "unexpected end of document",
+		`{ books { isbn } }`:           `cannot query field "isbn" on type "Book"`,
+		`{ books }`:                    "must have a selection of subfields",
+		`{ books(limit: 1) { id } }`:   `unknown argument "limit"`,
+		`{ books(first: "x") { id } }`: "expected an integer",
+		`{ books { author { id } ... on Author { name } } }`:          `inline fragment on "Author" cannot be used on type "Book"`,
+		`{ books { author { ...A } } } fragment A on Author { ...A }`: `fragment "A" spreads itself`,
+		`mutation { books { id } }`:                                   "mutation operations are not supported",
+		`{ books { id } } query Q { books { id } }`:                   "operationName is required",
+	}
+	for query, want := range cases {
+		result, _ := executeTestQuery(t, query, nil)
+		if result.Data != nil || len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, want) {
+			t.Errorf("%s: expected error containing %q, got %+v", query, want, result.Errors)
+		}
+	}
+
+	result, _ := executeTestQuery(t, `{ books { author { id } } }`, nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("depth 3 should be allowed, got %+v", result.Errors[0])
+	}
+	result, _ = executeTestQuery(t, `{ books { author { ... on Author { id } } } }`, nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("fragments should not add depth, got %+v", result.Errors[0])
+	}
+}
+
+func TestExecuteReportsResolverErrorsWithPath(t *testing.T) {
+	t.Parallel()
+
+	result, _ := executeTestQuery(t, `{ books(first: 1) { title @skip(if: true) author @include(if: true) { id } } }`, nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", result.Errors[0])
+	}
+	books, _ := result.Data.Get("books")
+	first := books.([]any)[0].(*OrderedMap)
+	if _, ok := first.Get("title"); ok {
+		t.Fatal("expected @skip to drop the title field")
+	}
+
+	schema := newTestSchema()
+	schema.Query.Fields["broken"] = &Field{Type: NonNullOf(String), Resolve: func(ResolveParams) (any, error) { return nil, nil }}
+	result = schema.Execute(context.Background(), Request{Query: `{ broken }`})
+	if len(result.Errors) != 1 || result.Errors[0].Path[0] != "broken" || !strings.HasPrefix(result.Errors[0].Message, "masked: cannot return null") {
+		t.Fatalf("expected a masked non-null error for broken, got %+v", result.Errors)
+	}
+}
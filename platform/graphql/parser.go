@@ -0,0 +1,636 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind   tokenKind
+	value  string
+	line   int
+	column int
+}
+
+// SyntaxError reports a malformed document.
+type SyntaxError struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+type lexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+func (l *lexer) errorf(format string, args ...any) error {
+	return &SyntaxError{Message: fmt.Sprintf(format, args...), Line: l.line, Column: l.column}
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.pos < len(l.src); i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+		l.pos++
+	}
+}
+
+// skipIgnored skips whitespace, commas, comments and the byte order mark.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.advance(1)
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\ufeff"):
+			l.pos += len("\ufeff")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	tok := token{line: l.line, column: l.column}
+	if l.pos >= len(l.src) {
+		tok.kind = tokenEOF
+		return tok, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+		tok.kind, tok.value = tokenPunct, string(c)
+		l.advance(1)
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		tok.kind, tok.value = tokenPunct, "..."
+		l.advance(3)
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.advance(1)
+		}
+		tok.kind, tok.value = tokenName, l.src[start:l.pos]
+	case c == '-' || isDigit(c):
+		return l.number(tok)
+	case c == '"':
+		value, err := l.string()
+		if err != nil {
+			return tok, err
+		}
+		tok.kind, tok.value = tokenString, value
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return tok, l.errorf("unexpected character %q", r)
+	}
+	return tok, nil
+}
+
+func (l *lexer) number(tok token) (token, error) {
+	start := l.pos
+	if l.src[l.pos] == '-' {
+		l.advance(1)
+	}
+	digits := func() int {
+		n := 0
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.advance(1)
+			n++
+		}
+		return n
+	}
+	if digits() == 0 {
+		return tok, l.errorf("invalid number")
+	}
+	tok.kind = tokenInt
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.advance(1)
+		if digits() == 0 {
+			return tok, l.errorf("invalid number")
+		}
+		tok.kind = tokenFloat
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		l.advance(1)
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.advance(1)
+		}
+		if digits() == 0 {
+			return tok, l.errorf("invalid number")
+		}
+		tok.kind = tokenFloat
+	}
+	tok.value = l.src[start:l.pos]
+	return tok, nil
+}
+
+func (l *lexer) string() (string, error) {
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		l.advance(3)
+		end := strings.Index(l.src[l.pos:], `"""`)
+		if end < 0 {
+			return "", l.errorf("unterminated block string")
+		}
+		value := l.src[l.pos : l.pos+end]
+		l.advance(end + 3)
+		return strings.TrimSpace(value), nil
+	}
+
+	l.advance(1)
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' {
+			return "", l.errorf("unterminated string")
+		}
+		c := l.src[l.pos]
+		if c == '"' {
+			l.advance(1)
+			return b.String(), nil
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			l.advance(1)
+			continue
+		}
+		if l.pos+1 >= len(l.src) {
+			return "", l.errorf("unterminated string")
+		}
+		switch esc := l.src[l.pos+1]; esc {
+		case '"', '\\', '/':
+			b.WriteByte(esc)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if l.pos+6 > len(l.src) {
+				return "", l.errorf("invalid unicode escape")
+			}
+			code, err := strconv.ParseUint(l.src[l.pos+2:l.pos+6], 16, 32)
+			if err != nil {
+				return "", l.errorf("invalid unicode escape")
+			}
+			b.WriteRune(rune(code))
+			l.advance(4)
+		default:
+			return "", l.errorf("invalid escape sequence \\%c", esc)
+		}
+		l.advance(2)
+	}
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// Parse parses an executable GraphQL document. Type system definitions are not supported.
+func Parse(src string) (*Document, error) {
+	p := &parser{lex: &lexer{src: src, line: 1, column: 1}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", SelectionSet: selections})
+		case p.peek(tokenName, "fragment"):
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				return nil, p.errorf("duplicate fragment %q", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		case p.peek(tokenName, "query"), p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, &SyntaxError{Message: "document contains no operations", Line: 1, Column: 1}
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Message: fmt.Sprintf(format, args...), Line: p.tok.line, Column: p.tok.column}
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return p.errorf("unexpected end of document")
+	}
+	return p.errorf("unexpected %q", p.tok.value)
+}
+
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+func (p *parser) skip(kind tokenKind, value string) (bool, error) {
+	if !p.peek(kind, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.peek(kind, value) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) operation() (*Operation, error) {
+	op := &Operation{Type: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if ok, err := p.skip(tokenPunct, "("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(tokenPunct, ")") {
+			def, err := p.variableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.SelectionSet = selections
+	return op, nil
+}
+
+func (p *parser) variableDefinition() (*VariableDefinition, error) {
+	if err := p.expect(tokenPunct, "$"); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokenPunct, ":"); err != nil {
+		return nil, err
+	}
+	typ, err := p.typeRef()
+	if err != nil {
+		return nil, err
+	}
+	def := &VariableDefinition{Name: name, Type: typ}
+	if ok, err := p.skip(tokenPunct, "="); err != nil {
+		return nil, err
+	} else if ok {
+		if def.Default, err = p.value(true); err != nil {
+			return nil, err
+		}
+	}
+	return def, nil
+}
+
+func (p *parser) typeRef() (*TypeRef, error) {
+	var ref *TypeRef
+	if ok, err := p.skip(tokenPunct, "["); err != nil {
+		return nil, err
+	} else if ok {
+		elem, err := p.typeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenPunct, "]"); err != nil {
+			return nil, err
+		}
+		ref = &TypeRef{Elem: elem}
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		ref = &TypeRef{Name: name}
+	}
+	nonNull, err := p.skip(tokenPunct, "!")
+	if err != nil {
+		return nil, err
+	}
+	ref.NonNull = nonNull
+	return ref, nil
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, p.errorf("fragment cannot be named \"on\"")
+	}
+	if err := p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{Name: name, TypeCondition: typeCondition, SelectionSet: selections}, nil
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+	var selections []Selection
+	for !p.peek(tokenPunct, "}") {
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, p.errorf("selection set cannot be empty")
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) selection() (Selection, error) {
+	if ok, err := p.skip(tokenPunct, "..."); err != nil {
+		return nil, err
+	} else if ok {
+		return p.fragmentSelection()
+	}
+
+	field := &FieldSelection{Line: p.tok.line, Column: p.tok.column}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(tokenPunct, ":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+	if field.Arguments, err = p.arguments(); err != nil {
+		return nil, err
+	}
+	if field.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunct, "{") {
+		if field.SelectionSet, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) fragmentSelection() (Selection, error) {
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		directives, err := p.directives()
+		if err != nil {
+			return nil, err
+		}
+		return &FragmentSpread{Name: name, Directives: directives}, nil
+	}
+
+	inline := &InlineFragment{}
+	if ok, err := p.skip(tokenName, "on"); err != nil {
+		return nil, err
+	} else if ok {
+		if inline.TypeCondition, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if inline.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if inline.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return inline, nil
+}
+
+func (p *parser) arguments() ([]*Argument, error) {
+	ok, err := p.skip(tokenPunct, "(")
+	if err != nil || !ok {
+		return nil, err
+	}
+	var args []*Argument
+	for !p.peek(tokenPunct, ")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		value, err := p.value(false)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &Argument{Name: name, Value: value})
+	}
+	if len(args) == 0 {
+		return nil, p.errorf("argument list cannot be empty")
+	}
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek(tokenPunct, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &Directive{Name: name, Arguments: args})
+	}
+	return directives, nil
+}
+
+func (p *parser) value(constant bool) (Value, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokenPunct && tok.value == "$":
+		if constant {
+			return nil, p.errorf("variables are not allowed here")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		return Variable{Name: name}, nil
+	case tok.kind == tokenInt:
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid integer %s", tok.value)
+		}
+		return IntValue{Value: n}, p.advance()
+	case tok.kind == tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %s", tok.value)
+		}
+		return FloatValue{Value: f}, p.advance()
+	case tok.kind == tokenString:
+		return StringValue{Value: tok.value}, p.advance()
+	case tok.kind == tokenName:
+		var value Value
+		switch tok.value {
+		case "true":
+			value = BooleanValue{Value: true}
+		case "false":
+			value = BooleanValue{Value: false}
+		case "null":
+			value = NullValue{}
+		default:
+			value = EnumValue{Value: tok.value}
+		}
+		return value, p.advance()
+	case tok.kind == tokenPunct && tok.value == "[":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := ListValue{}
+		for !p.peek(tokenPunct, "]") {
+			item, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			list.Values = append(list.Values, item)
+		}
+		return list, p.advance()
+	case tok.kind == tokenPunct && tok.value == "{":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		object := ObjectValue{Fields: make(map[string]Value)}
+		for !p.peek(tokenPunct, "}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenPunct, ":"); err != nil {
+				return nil, err
+			}
+			item, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			object.Fields[name] = item
+		}
+		return object, p.advance()
+	default:
+		return nil, p.unexpected()
+	}
+}
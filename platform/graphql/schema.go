@@ -0,0 +1,222 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Type is an output or input type of the schema.
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Serialize converts resolver values into JSON values and
+// Parse coerces argument values.
+type Scalar struct {
+	Name      string
+	Serialize func(value any) (any, error)
+	Parse     func(value any) (any, error)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Object is a composite output type.
+type Object struct {
+	Name   string
+	Fields Fields
+}
+
+func (o *Object) String() string { return o.Name }
+
+// Fields maps field names to their definitions.
+type Fields map[string]*Field
+
+// List wraps a type in a list.
+type List struct{ Of Type }
+
+func (l *List) String() string { return "[" + l.Of.String() + "]" }
+
+// NonNull marks a type as non-nullable.
+type NonNull struct{ Of Type }
+
+func (n *NonNull) String() string { return n.Of.String() + "!" }
+
+// ListOf returns a list type of t.
+func ListOf(t Type) *List { return &List{Of: t} }
+
+// NonNullOf returns a non-null type of t.
+func NonNullOf(t Type) *NonNull { return &NonNull{Of: t} }
+
+// Field defines a field, its arguments and its resolver.
+type Field struct {
+	Type    Type
+	Args    []Arg
+	Resolve ResolveFunc
+}
+
+// Arg defines a field argument. Default is used when the argument is omitted.
+type Arg struct {
+	Name    string
+	Type    Type
+	Default any
+}
+
+// ResolveParams are passed to a field resolver.
+type ResolveParams struct {
+	Context context.Context
+	Source  any
+	Args    map[string]any
+}
+
+// ResolveFunc resolves a field. It may return a Thunk to defer work until every
+// sibling at the same depth has been resolved, which is how loaders batch lookups.
+type ResolveFunc func(p ResolveParams) (any, error)
+
+// Thunk is a deferred resolver result.
+type Thunk func() (any, error)
+
+// Schema is an executable read-only schema.
+type Schema struct {
+	Query *Object
+	// MaxDepth limits the nesting of selection sets; zero means unlimited.
+	MaxDepth int
+	// FormatError turns a resolver error into the message returned to clients.
+	// Defaults to err.Error().
+	FormatError func(err error) string
+}
+
+// Built-in scalars.
+var (
+	String = &Scalar{Name: "String", Serialize: serializeString, Parse: parseString}
+	ID     = &Scalar{Name: "ID", Serialize: serializeString, Parse: parseID}
+	Int    = &Scalar{Name: "Int", Serialize: serializeInt, Parse: parseInt}
+	Float  = &Scalar{Name: "Float", Serialize: serializeFloat, Parse: parseFloat}
+	Bool   = &Scalar{Name: "Boolean", Serialize: serializeBool, Parse: serializeBool}
+	// DateTime is an RFC 3339 timestamp, formatted like the REST API's JSON times.
+	DateTime = &Scalar{Name: "DateTime", Serialize: serializeDateTime, Parse: parseDateTime}
+)
+
+func serializeString(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	default:
+		return nil, fmt.Errorf("cannot serialize %T as String", value)
+	}
+}
+
+func parseString(value any) (any, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("expected a string, got %T", value)
+}
+
+func parseID(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return fmt.Sprint(v), nil
+	default:
+		return nil, fmt.Errorf("expected an ID, got %T", value)
+	}
+}
+
+func serializeInt(value any) (any, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("cannot serialize %T as Int", value)
+	}
+}
+
+func parseInt(value any) (any, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		// JSON variables decode numbers as float64.
+		if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+			return int(v), nil
+		}
+	}
+	return nil, fmt.Errorf("expected an integer, got %v", value)
+}
+
+func serializeFloat(value any) (any, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	default:
+		return nil, fmt.Errorf("cannot serialize %T as Float", value)
+	}
+}
+
+func parseFloat(value any) (any, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	default:
+		return nil, fmt.Errorf("expected a number, got %T", value)
+	}
+}
+
+func serializeBool(value any) (any, error) {
+	if b, ok := value.(bool); ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("expected a boolean, got %T", value)
+}
+
+func serializeDateTime(value any) (any, error) {
+	if t, ok := value.(time.Time); ok {
+		return t.Format(time.RFC3339Nano), nil
+	}
+	return nil, fmt.Errorf("cannot serialize %T as DateTime", value)
+}
+
+func parseDateTime(value any) (any, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected an RFC 3339 timestamp, got %T", value)
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("expected an RFC 3339 timestamp, got %q", s)
+	}
+	return t, nil
+}
+
+// namedType strips list and non-null wrappers.
+func namedType(t Type) Type {
+	for {
+		switch v := t.(type) {
+		case *NonNull:
+			t = v.Of
+		case *List:
+			t = v.Of
+		default:
+			return t
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package httpkit
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const msgPayloadTooLarge = "request body too large"
+
+// BodyLimit caps request bodies at maxBytes. routes overrides the limit per gin
+// route path (c.FullPath()); a pattern ending in "*" matches by prefix, one
+// starting with "*" by suffix, and the longest matching pattern wins. Requests
+// that declare a larger Content-Length are rejected with 413 before the body is
+// read; other bodies fail on the first read past the limit. A limit of zero or
+// less leaves the body unlimited.
+func BodyLimit(maxBytes int64, routes map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := bodyLimitFor(c.FullPath(), maxBytes, routes)
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: msgPayloadTooLarge})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+func bodyLimitFor(path string, maxBytes int64, routes map[string]int64) int64 {
+	limit, matched := maxBytes, -1
+	for pattern, routeLimit := range routes {
+		if len(pattern) > matched && routePatternMatches(pattern, path) {
+			limit, matched = routeLimit, len(pattern)
+		}
+	}
+	return limit
+}
+
+func routePatternMatches(pattern, path string) bool {
+	switch {
+	case strings.HasSuffix(pattern, "*"):
+		return strings.HasPrefix(path, strings.TrimSuffix(pattern, "*"))
+	case strings.HasPrefix(pattern, "*"):
+		return strings.HasSuffix(path, strings.TrimPrefix(pattern, "*"))
+	default:
+		return pattern == path
+	}
+}
@@ -0,0 +1,73 @@
+package httpkit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyLimitFor(t *testing.T) {
+	t.Parallel()
+
+	routes := map[string]int64{
+		"*/upload":              100,
+		"/api/v1/leads/*":       20,
+		"/api/v1/leads/:id/raw": 0,
+	}
+	cases := map[string]int64{
+		"/api/v1/quotes":                        10,
+		"/api/v1/leads/:id":                     20,
+		"/api/v1/quotes/:id/attachments/upload": 100,
+		"/api/v1/leads/:id/upload":              20,
+		"/api/v1/leads/:id/raw":                 0,
+	}
+	for path, want := range cases {
+		if got := bodyLimitFor(path, 10, routes); got != want {
+			t.Fatalf("bodyLimitFor(%q) = %d; want %d", path, got, want)
+		}
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(BodyLimit(8, map[string]int64{"*/upload": 32}))
+	read := func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); HandleError(c, err) {
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+	engine.POST("/notes", read)
+	engine.POST("/files/upload", read)
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		chunked  bool
+		wantCode int
+	}{
+		{name: "within default", path: "/notes", body: "12345678", wantCode: http.StatusNoContent},
+		{name: "declared too large", path: "/notes", body: "123456789", wantCode: http.StatusRequestEntityTooLarge},
+		{name: "streamed too large", path: "/notes", body: "123456789", chunked: true, wantCode: http.StatusRequestEntityTooLarge},
+		{name: "route override", path: "/files/upload", body: strings.Repeat("x", 32), wantCode: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+		if tt.chunked {
+			req.ContentLength = -1
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		if rec.Code != tt.wantCode {
+			t.Fatalf("%s: got status %d; want %d", tt.name, rec.Code, tt.wantCode)
+		}
+	}
+}
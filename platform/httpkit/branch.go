@@ -0,0 +1,102 @@
+package httpkit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderBranchID selects the branch a head-office user reads.
+	HeaderBranchID = "X-Branch-ID"
+	// ContextHeadOfficeIDKey is the gin context key for the organization of a
+	// head-office user reading one of its branches.
+	ContextHeadOfficeIDKey = "headOfficeID"
+
+	// branchReaderRole is the role a head-office user needs to read branches.
+	// It is the role that sees all data of its own organization; route and
+	// handler role checks still apply on top.
+	branchReaderRole = "admin"
+)
+
+// BranchAccessResolver reports whether an organization is a branch of a head office.
+type BranchAccessResolver func(ctx context.Context, headOfficeID, branchID uuid.UUID) (bool, error)
+
+var (
+	branchAccessResolverMu sync.RWMutex
+	branchAccessResolver   BranchAccessResolver
+)
+
+func SetBranchAccessResolver(resolver BranchAccessResolver) {
+	branchAccessResolverMu.Lock()
+	defer branchAccessResolverMu.Unlock()
+	branchAccessResolver = resolver
+}
+
+// scopeToBranch returns the tenant a request runs as. Head-office admins read a
+// branch by sending its ID in the X-Branch-ID header; the access is read-only,
+// so writes to a branch are done by the branch's own users. It runs after the
+// roles of the user are set on the context.
+func scopeToBranch(c *gin.Context, tenantID uuid.UUID) (uuid.UUID, bool) {
+	raw := strings.TrimSpace(c.GetHeader(HeaderBranchID))
+	if raw == "" {
+		return tenantID, true
+	}
+	branchID, err := uuid.Parse(raw)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "invalid branch id", nil)
+		c.Abort()
+		return uuid.UUID{}, false
+	}
+	if branchID == tenantID {
+		return tenantID, true
+	}
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		Error(c, http.StatusForbidden, "branch access is read-only", nil)
+		c.Abort()
+		return uuid.UUID{}, false
+	}
+	if !hasContextRole(c, branchReaderRole) {
+		Error(c, http.StatusForbidden, "branch access requires the admin role", nil)
+		c.Abort()
+		return uuid.UUID{}, false
+	}
+
+	branchAccessResolverMu.RLock()
+	resolver := branchAccessResolver
+	branchAccessResolverMu.RUnlock()
+	if resolver == nil {
+		Error(c, http.StatusForbidden, "forbidden", nil)
+		c.Abort()
+		return uuid.UUID{}, false
+	}
+
+	allowed, err := resolver(c.Request.Context(), tenantID, branchID)
+	if err != nil {
+		Error(c, http.StatusServiceUnavailable, "organization data unavailable", nil)
+		c.Abort()
+		return uuid.UUID{}, false
+	}
+	if !allowed {
+		Error(c, http.StatusForbidden, "forbidden", nil)
+		c.Abort()
+		return uuid.UUID{}, false
+	}
+	c.Set(ContextHeadOfficeIDKey, tenantID)
+	return branchID, true
+}
+
+func hasContextRole(c *gin.Context, role string) bool {
+	roles, _ := c.Get(ContextRolesKey)
+	roleList, _ := roles.([]string)
+	for _, item := range roleList {
+		if item == role {
+			return true
+		}
+	}
+	return false
+}
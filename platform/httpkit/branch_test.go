@@ -0,0 +1,62 @@
+package httpkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestScopeToBranch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	headOffice := uuid.New()
+	branch := uuid.New()
+	SetBranchAccessResolver(func(_ context.Context, headOfficeID, branchID uuid.UUID) (bool, error) {
+		return headOfficeID == headOffice && branchID == branch, nil
+	})
+	t.Cleanup(func() { SetBranchAccessResolver(nil) })
+
+	cases := []struct {
+		name       string
+		method     string
+		tenant     uuid.UUID
+		header     string
+		roles      []string
+		wantOK     bool
+		wantTenant uuid.UUID
+		wantStatus int
+	}{
+		{name: "no header", method: http.MethodPost, tenant: headOffice, wantOK: true, wantTenant: headOffice},
+		{name: "own organization", method: http.MethodPost, tenant: branch, header: branch.String(), wantOK: true, wantTenant: branch},
+		{name: "read branch", method: http.MethodGet, tenant: headOffice, header: branch.String(), roles: []string{"admin"}, wantOK: true, wantTenant: branch},
+		{name: "read branch without admin role", method: http.MethodGet, tenant: headOffice, header: branch.String(), roles: []string{"user"}, wantStatus: http.StatusForbidden},
+		{name: "write branch", method: http.MethodPatch, tenant: headOffice, header: branch.String(), roles: []string{"admin"}, wantStatus: http.StatusForbidden},
+		{name: "not a branch", method: http.MethodGet, tenant: branch, header: headOffice.String(), roles: []string{"admin"}, wantStatus: http.StatusForbidden},
+		{name: "invalid id", method: http.MethodGet, tenant: headOffice, header: "branch", wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(tc.method, "/test", nil)
+			c.Set(ContextRolesKey, tc.roles)
+			if tc.header != "" {
+				c.Request.Header.Set(HeaderBranchID, tc.header)
+			}
+
+			tenant, ok := scopeToBranch(c, tc.tenant)
+			if ok != tc.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tc.wantOK, ok)
+			}
+			if ok && tenant != tc.wantTenant {
+				t.Fatalf("expected tenant %s, got %s", tc.wantTenant, tenant)
+			}
+			if !ok && recorder.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, recorder.Code)
+			}
+		})
+	}
+}
@@ -94,6 +94,20 @@ func GetIdentity(c *gin.Context) Identity {
 	}
 }
 
+// GetSessionID returns the auth session the request's access token belongs
+// to. Tokens issued before sessions were tracked have none.
+func GetSessionID(c *gin.Context) *uuid.UUID {
+	value, ok := c.Get(ContextSessionIDKey)
+	if !ok {
+		return nil
+	}
+	sessionID, ok := value.(uuid.UUID)
+	if !ok {
+		return nil
+	}
+	return &sessionID
+}
+
 // MustGetIdentity extracts the Identity from a Gin context.
 // If the user is not authenticated, it aborts with 401 Unauthorized and returns nil.
 func MustGetIdentity(c *gin.Context) Identity {
@@ -128,5 +142,5 @@ func isOnboardingAllowedPath(c *gin.Context) bool {
 	if allowed[path] {
 		return true
 	}
-	return strings.HasPrefix(path, "/api/v1/users/me/imap-accounts")
+	return strings.HasPrefix(path, "/api/v1/users/me/imap-accounts") || strings.HasPrefix(path, "/api/v1/users/me/sessions")
 }
@@ -30,6 +30,8 @@ const (
 	ContextRolesKey = "roles"
 	// ContextTenantIDKey is the gin context key for the tenant (organization) ID.
 	ContextTenantIDKey = "tenantID"
+	// ContextSessionIDKey is the gin context key for the auth session ID.
+	ContextSessionIDKey = "sessionID"
 
 	errMissingToken = "missing token"
 	errInvalidToken = "invalid token"
@@ -118,6 +120,21 @@ func SetTokenRevocationLookup(lookup TokenRevocationLookup) {
 	tokenRevocationLookup = lookup
 }
 
+// TenantContextBinder derives the request context for an organization, e.g. to
+// route its queries to a dedicated database schema.
+type TenantContextBinder func(ctx context.Context, tenantID uuid.UUID) (context.Context, error)
+
+var (
+	tenantContextBinderMu sync.RWMutex
+	tenantContextBinder   TenantContextBinder
+)
+
+func SetTenantContextBinder(binder TenantContextBinder) {
+	tenantContextBinderMu.Lock()
+	defer tenantContextBinderMu.Unlock()
+	tenantContextBinder = binder
+}
+
 // NewIPRateLimiter creates a new IP-based rate limiter.
 func NewIPRateLimiter(r rate.Limit, burst int, log *logger.Logger) *IPRateLimiter {
 	return &IPRateLimiter{
@@ -211,12 +228,22 @@ func AuthRequired(cfg config.JWTConfig) gin.HandlerFunc {
 		}
 		c.Set(ContextUserIDKey, userID)
 		c.Set(ContextRolesKey, roles)
+		if sessionID := parseSessionID(claims); sessionID != nil {
+			c.Set(ContextSessionIDKey, *sessionID)
+		}
 
 		if tenantID, err := parseTenantID(claims); err != nil {
 			abortUnauthorized(c, errInvalidToken)
 			return
 		} else if tenantID != nil {
-			c.Set(ContextTenantIDKey, *tenantID)
+			scopedTenantID, ok := scopeToBranch(c, *tenantID)
+			if !ok {
+				return
+			}
+			c.Set(ContextTenantIDKey, scopedTenantID)
+			if !BindTenantContext(c, scopedTenantID) {
+				return
+			}
 		}
 		c.Next()
 	}
@@ -343,6 +370,17 @@ func parseTenantID(claims jwt.MapClaims) (*uuid.UUID, error) {
 	return &parsed, nil
 }
 
+// parseSessionID returns the session ID claim, or nil for tokens without a
+// valid one.
+func parseSessionID(claims jwt.MapClaims) *uuid.UUID {
+	value, _ := claims["sid"].(string)
+	parsed, err := uuid.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
 func abortUnauthorized(c *gin.Context, message string) {
 	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
 }
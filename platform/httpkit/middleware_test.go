@@ -8,6 +8,8 @@ import (
 	"portal_final_backend/platform/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 func TestRequestCorrelationGeneratesRequestAndTraceIDs(t *testing.T) {
@@ -67,3 +69,17 @@ func TestRequestCorrelationPreservesInboundHeaders(t *testing.T) {
 		t.Fatalf("expected trace id header to be preserved, got %q", recorder.Header().Get(headerTraceID))
 	}
 }
+
+func TestParseSessionID(t *testing.T) {
+	t.Parallel()
+
+	sessionID := uuid.New()
+	if got := parseSessionID(jwt.MapClaims{"sid": sessionID.String()}); got == nil || *got != sessionID {
+		t.Fatalf("expected session id %s, got %v", sessionID, got)
+	}
+	for _, claims := range []jwt.MapClaims{{}, {"sid": "not-a-uuid"}, {"sid": 42}} {
+		if got := parseSessionID(claims); got != nil {
+			t.Fatalf("expected no session id for %v, got %s", claims, got)
+		}
+	}
+}
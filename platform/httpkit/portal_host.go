@@ -0,0 +1,83 @@
+package httpkit
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ContextPortalOrganizationIDKey is the gin context key for the organization
+// whose custom portal domain served a public request.
+const ContextPortalOrganizationIDKey = "portalOrganizationID"
+
+// PortalHostResolver returns the organization whose verified portal domain is
+// the given host.
+type PortalHostResolver func(ctx context.Context, host string) (uuid.UUID, bool, error)
+
+var (
+	portalHostResolverMu sync.RWMutex
+	portalHostResolver   PortalHostResolver
+)
+
+func SetPortalHostResolver(resolver PortalHostResolver) {
+	portalHostResolverMu.Lock()
+	defer portalHostResolverMu.Unlock()
+	portalHostResolver = resolver
+}
+
+// PortalHost resolves the organization of a white-label portal from the host
+// the request was made to. Custom domains reach the API through a reverse
+// proxy, so the forwarded host takes precedence when the request comes from
+// one of the trusted proxies; anyone else could send the header. Requests on
+// the shared portal host carry no portal organization; lookup errors are
+// ignored because PublicTokenTenant still checks the token against it.
+func PortalHost(trustedProxies []netip.Prefix) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		portalHostResolverMu.RLock()
+		resolver := portalHostResolver
+		portalHostResolverMu.RUnlock()
+		if resolver == nil {
+			c.Next()
+			return
+		}
+
+		host := c.Request.Host
+		if fromTrustedProxy(c, trustedProxies) {
+			if forwarded := strings.TrimSpace(strings.Split(c.GetHeader("X-Forwarded-Host"), ",")[0]); forwarded != "" {
+				host = forwarded
+			}
+		}
+		if organizationID, ok, err := resolver(c.Request.Context(), host); err == nil && ok {
+			c.Set(ContextPortalOrganizationIDKey, organizationID)
+		}
+		c.Next()
+	}
+}
+
+// PortalOrganizationID returns the organization resolved by PortalHost.
+func PortalOrganizationID(c *gin.Context) (uuid.UUID, bool) {
+	value, ok := c.Get(ContextPortalOrganizationIDKey)
+	if !ok {
+		return uuid.UUID{}, false
+	}
+	organizationID, ok := value.(uuid.UUID)
+	return organizationID, ok
+}
+
+func fromTrustedProxy(c *gin.Context, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(c.RemoteIP())
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package httpkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestPortalHostOnlyTrustsForwardedHostFromProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orgID := uuid.New()
+	SetPortalHostResolver(func(_ context.Context, host string) (uuid.UUID, bool, error) {
+		return orgID, host == "portal.example.nl", nil
+	})
+	t.Cleanup(func() { SetPortalHostResolver(nil) })
+
+	engine := gin.New()
+	engine.Use(PortalHost([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}))
+	engine.GET("/", func(c *gin.Context) {
+		_, ok := PortalOrganizationID(c)
+		c.JSON(http.StatusOK, ok)
+	})
+
+	cases := map[string]struct {
+		remoteAddr string
+		want       string
+	}{
+		"trusted proxy":   {"10.1.2.3:4567", "true"},
+		"direct client":   {"203.0.113.7:4567", "false"},
+		"mapped IPv4 hop": {"[::ffff:10.1.2.3]:4567", "true"},
+	}
+	for name, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = "api.example.nl"
+		req.RemoteAddr = tc.remoteAddr
+		req.Header.Set("X-Forwarded-Host", "portal.example.nl")
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+		if recorder.Body.String() != tc.want {
+			t.Errorf("%s: portal organization resolved = %s, want %s", name, recorder.Body.String(), tc.want)
+		}
+	}
+}
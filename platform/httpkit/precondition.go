@@ -0,0 +1,45 @@
+package httpkit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RowVersionPrecondition resolves the row version an update was based on, from
+// the If-Match header or else from the version in the request body. The
+// precondition is optional: it returns nil when neither is present, and the
+// update then applies unconditionally. It sends a 400 and returns false when
+// the header is malformed.
+func RowVersionPrecondition(c *gin.Context, bodyVersion *int64) (*int64, bool) {
+	if header := strings.TrimSpace(c.GetHeader("If-Match")); header != "" {
+		version, ok := parseETagVersion(header)
+		if !ok {
+			Error(c, http.StatusBadRequest, "invalid If-Match header", nil)
+			return nil, false
+		}
+		return &version, true
+	}
+	return bodyVersion, true
+}
+
+// SetRowVersionETag exposes a row version as the ETag of the response so the
+// client can send it back in If-Match.
+func SetRowVersionETag(c *gin.Context, version int64) {
+	if version > 0 {
+		c.Header("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+	}
+}
+
+// parseETagVersion accepts "3", W/"3" and a bare 3.
+func parseETagVersion(value string) (int64, bool) {
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
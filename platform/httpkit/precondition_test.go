@@ -0,0 +1,75 @@
+package httpkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseETagVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		want int64
+		ok   bool
+	}{
+		`"3"`:    {3, true},
+		`W/"12"`: {12, true},
+		`7`:      {7, true},
+		`"0"`:    {0, false},
+		`"abc"`:  {0, false},
+		`*`:      {0, false},
+	}
+	for header, tc := range cases {
+		got, ok := parseETagVersion(header)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("parseETagVersion(%q) = %d, %v; want %d, %v", header, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestRowVersionPrecondition(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	bodyVersion := int64(4)
+	tests := []struct {
+		name     string
+		ifMatch  string
+		body     *int64
+		wantCode int
+		want     int64 // 0 for no precondition
+	}{
+		{name: "header wins over body", ifMatch: `"5"`, body: &bodyVersion, wantCode: http.StatusOK, want: 5},
+		{name: "body fallback", body: &bodyVersion, wantCode: http.StatusOK, want: 4},
+		{name: "missing is unconditional", wantCode: http.StatusOK},
+		{name: "malformed header", ifMatch: `"x"`, body: &bodyVersion, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			got, ok := RowVersionPrecondition(c, tt.body)
+			if ok {
+				c.Status(http.StatusOK)
+			}
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+			var version int64
+			if got != nil {
+				version = *got
+			}
+			if ok && version != tt.want {
+				t.Fatalf("version = %d, want %d", version, tt.want)
+			}
+		})
+	}
+}
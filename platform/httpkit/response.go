@@ -3,9 +3,13 @@
 package httpkit
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"portal_final_backend/platform/apperr"
+	"portal_final_backend/platform/circuitbreaker"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,7 +37,9 @@ func OK(c *gin.Context, payload interface{}) {
 
 // HandleError maps domain errors to HTTP responses.
 // If the error is a typed *apperr.Error, it uses the error's Kind to determine
-// the HTTP status code. Otherwise, it defaults to 400 Bad Request.
+// the HTTP status code. Calls rejected by an open circuit breaker answer 503 with
+// a Retry-After header and bodies cut off by BodyLimit answer 413. Otherwise,
+// it defaults to 400 Bad Request.
 // Returns true if an error was handled, false otherwise.
 func HandleError(c *gin.Context, err error) bool {
 	if err == nil {
@@ -48,6 +54,18 @@ func HandleError(c *gin.Context, err error) bool {
 		return true
 	}
 
+	if retryAfter, ok := circuitbreaker.RetryAfter(err); ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: err.Error()})
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: msgPayloadTooLarge})
+		return true
+	}
+
 	// Fallback for non-typed errors
 	c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 	return true
@@ -1,6 +1,7 @@
 package httpkit
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,3 +23,64 @@ func RequireTenant(c *gin.Context) (uuid.UUID, bool) {
 	}
 	return *tenantID, true
 }
+
+// BindTenant derives the context for queries of an organization with the
+// tenant context binder, e.g. to route them to its dedicated data schema.
+func BindTenant(ctx context.Context, tenantID uuid.UUID) (context.Context, error) {
+	tenantContextBinderMu.RLock()
+	binder := tenantContextBinder
+	tenantContextBinderMu.RUnlock()
+	if binder == nil {
+		return ctx, nil
+	}
+	return binder(ctx, tenantID)
+}
+
+// BindTenantContext binds the request context to an organization once it is
+// known, e.g. after a webhook resolved its API key. It fails closed: a request
+// must not fall through to the shared schema when the routing of its
+// organization is unknown.
+func BindTenantContext(c *gin.Context, tenantID uuid.UUID) bool {
+	ctx, err := BindTenant(c.Request.Context(), tenantID)
+	if err != nil {
+		Error(c, http.StatusServiceUnavailable, "organization data unavailable", nil)
+		c.Abort()
+		return false
+	}
+	c.Request = c.Request.WithContext(ctx)
+	return true
+}
+
+// PublicTokenResolver returns the organization a public portal token belongs
+// to, and false when the token is unknown.
+type PublicTokenResolver func(ctx context.Context, token string) (uuid.UUID, bool, error)
+
+// PublicTokenTenant binds the request context to the organization of the
+// :token path parameter, so public lead and quote endpoints read the data
+// schema the token lives in. On a custom portal domain, tokens of other
+// organizations are reported as not found. Unknown tokens pass through
+// unbound; the handler reports them.
+func PublicTokenTenant(resolve PublicTokenResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		if token == "" {
+			c.Next()
+			return
+		}
+		organizationID, ok, err := resolve(c.Request.Context(), token)
+		if err != nil {
+			Error(c, http.StatusServiceUnavailable, "organization data unavailable", nil)
+			c.Abort()
+			return
+		}
+		if portalOrganizationID, onPortal := PortalOrganizationID(c); ok && onPortal && organizationID != portalOrganizationID {
+			Error(c, http.StatusNotFound, "Link expired or invalid", nil)
+			c.Abort()
+			return
+		}
+		if ok && !BindTenantContext(c, organizationID) {
+			return
+		}
+		c.Next()
+	}
+}
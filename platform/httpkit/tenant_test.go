@@ -0,0 +1,89 @@
+package httpkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"portal_final_backend/platform/db"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestPublicTokenTenantBindsOrganizationOfToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orgID := uuid.New()
+	SetTenantContextBinder(func(ctx context.Context, tenantID uuid.UUID) (context.Context, error) {
+		if tenantID != orgID {
+			t.Errorf("bound tenant %s, want %s", tenantID, orgID)
+		}
+		return db.WithSchema(ctx, "tenant_abc"), nil
+	})
+	t.Cleanup(func() { SetTenantContextBinder(nil) })
+
+	engine := gin.New()
+	group := engine.Group("/leads")
+	group.Use(PublicTokenTenant(func(_ context.Context, token string) (uuid.UUID, bool, error) {
+		return orgID, token == "dedicated", nil
+	}))
+	group.GET("/:token", func(c *gin.Context) {
+		c.String(http.StatusOK, db.SchemaFromContext(c.Request.Context()))
+	})
+
+	cases := map[string]string{
+		"/leads/dedicated": "tenant_abc",
+		"/leads/shared":    "",
+	}
+	for path, want := range cases {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK || recorder.Body.String() != want {
+			t.Errorf("%s: got %d %q, want schema %q", path, recorder.Code, recorder.Body.String(), want)
+		}
+	}
+}
+
+func TestPublicTokenTenantRejectsTokensOfOtherPortalOrganizations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	portalOrgID, otherOrgID := uuid.New(), uuid.New()
+	SetPortalHostResolver(func(_ context.Context, host string) (uuid.UUID, bool, error) {
+		return portalOrgID, host == "portal.example.nl", nil
+	})
+	t.Cleanup(func() { SetPortalHostResolver(nil) })
+
+	engine := gin.New()
+	group := engine.Group("/leads")
+	group.Use(PortalHost(nil), PublicTokenTenant(func(_ context.Context, token string) (uuid.UUID, bool, error) {
+		switch token {
+		case "own":
+			return portalOrgID, true, nil
+		case "other":
+			return otherOrgID, true, nil
+		}
+		return uuid.Nil, false, nil
+	}))
+	group.GET("/:token", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	cases := []struct {
+		host, path string
+		want       int
+	}{
+		{"portal.example.nl", "/leads/own", http.StatusOK},
+		{"portal.example.nl", "/leads/other", http.StatusNotFound},
+		{"portal.example.nl", "/leads/unknown", http.StatusOK},
+		{"app.example.nl", "/leads/other", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.Host = tc.host
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+		if recorder.Code != tc.want {
+			t.Errorf("%s%s: got %d, want %d", tc.host, tc.path, recorder.Code, tc.want)
+		}
+	}
+}
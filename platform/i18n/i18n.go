@@ -0,0 +1,120 @@
+// Package i18n provides the languages customer-facing messages can be sent in
+// and locale-aware formatting of dates and amounts.
+// This is part of the platform layer and contains no business logic.
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLanguage is used when neither the lead nor the organization has a
+// language set.
+const DefaultLanguage = "nl"
+
+var supportedLanguages = []string{"nl", "en", "de", "fr"}
+
+// regionLanguages maps ISO 3166 region codes to the language messages to
+// those regions are sent in.
+var regionLanguages = map[string]string{
+	"NL": "nl", "BE": "nl", "SR": "nl",
+	"DE": "de", "AT": "de", "CH": "de", "LI": "de",
+	"FR": "fr", "LU": "fr", "MC": "fr",
+	"GB": "en", "IE": "en", "US": "en", "CA": "en", "AU": "en", "NZ": "en",
+}
+
+// SupportedLanguages returns the supported ISO 639-1 language codes.
+func SupportedLanguages() []string {
+	return append([]string(nil), supportedLanguages...)
+}
+
+// Normalize returns the supported language code for value, accepting tags
+// such as "EN" or "en-GB". It reports false for unsupported languages.
+func Normalize(value string) (string, bool) {
+	lang := strings.ToLower(strings.TrimSpace(value))
+	if idx := strings.IndexAny(lang, "-_"); idx >= 0 {
+		lang = lang[:idx]
+	}
+	for _, supported := range supportedLanguages {
+		if lang == supported {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Resolve returns the first supported language of candidates, or
+// DefaultLanguage when none is supported.
+func Resolve(candidates ...string) string {
+	for _, candidate := range candidates {
+		if lang, ok := Normalize(candidate); ok {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// LanguageForRegion returns the language for an ISO 3166 region code, or ""
+// when the region has no supported language.
+func LanguageForRegion(region string) string {
+	return regionLanguages[strings.ToUpper(strings.TrimSpace(region))]
+}
+
+// FormatDate formats t as a numeric date in the conventions of lang.
+func FormatDate(t time.Time, lang string) string {
+	switch Resolve(lang) {
+	case "en", "fr":
+		return t.Format("02/01/2006")
+	case "de":
+		return t.Format("02.01.2006")
+	default:
+		return t.Format("02-01-2006")
+	}
+}
+
+// FormatTime formats t as a 24-hour clock time.
+func FormatTime(t time.Time, _ string) string {
+	return t.Format("15:04")
+}
+
+// FormatCurrencyCents formats an amount in euro cents in the conventions of
+// lang, e.g. "€1.234,50" (nl), "€1,234.50" (en) or "1.234,50 €" (de).
+func FormatCurrencyCents(cents int64, lang string) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	euros, rest := cents/100, cents%100
+
+	switch Resolve(lang) {
+	case "en":
+		return sign + "€" + groupThousands(euros, ",") + "." + twoDigits(rest)
+	case "de":
+		return sign + groupThousands(euros, ".") + "," + twoDigits(rest) + " €"
+	case "fr":
+		return sign + groupThousands(euros, " ") + "," + twoDigits(rest) + " €"
+	default:
+		return sign + "€" + groupThousands(euros, ".") + "," + twoDigits(rest)
+	}
+}
+
+func groupThousands(value int64, separator string) string {
+	digits := []byte(strconv.FormatInt(value, 10))
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(separator)
+		}
+		b.WriteByte(digit)
+	}
+	return b.String()
+}
+
+func twoDigits(value int64) string {
+	if value < 10 {
+		return "0" + strconv.FormatInt(value, 10)
+	}
+	return strconv.FormatInt(value, 10)
+}
@@ -0,0 +1,59 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{"EN": "en", "en-GB": "en", " de_AT ": "de", "nl": "nl"}
+	for input, want := range tests {
+		if got, ok := Normalize(input); !ok || got != want {
+			t.Fatalf("Normalize(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+	if _, ok := Normalize("es"); ok {
+		t.Fatal("Normalize(es) should report an unsupported language")
+	}
+}
+
+func TestResolveFallsBackInOrder(t *testing.T) {
+	if got := Resolve("", "xx", "de", "en"); got != "de" {
+		t.Fatalf("Resolve() = %q, want de", got)
+	}
+	if got := Resolve("", "xx"); got != DefaultLanguage {
+		t.Fatalf("Resolve() = %q, want default language", got)
+	}
+}
+
+func TestFormatCurrencyCents(t *testing.T) {
+	tests := []struct {
+		lang  string
+		cents int64
+		want  string
+	}{
+		{lang: "nl", cents: 123450, want: "€1.234,50"},
+		{lang: "en", cents: 123450, want: "€1,234.50"},
+		{lang: "de", cents: 123405, want: "1.234,05 €"},
+		{lang: "fr", cents: 99, want: "0,99 €"},
+		{lang: "nl", cents: -150000000, want: "-€1.500.000,00"},
+	}
+	for _, tt := range tests {
+		if got := FormatCurrencyCents(tt.cents, tt.lang); got != tt.want {
+			t.Fatalf("FormatCurrencyCents(%d, %q) = %q, want %q", tt.cents, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2026, time.March, 7, 9, 5, 0, 0, time.UTC)
+	tests := map[string]string{"nl": "07-03-2026", "en": "07/03/2026", "de": "07.03.2026", "": "07-03-2026"}
+	for lang, want := range tests {
+		if got := FormatDate(date, lang); got != want {
+			t.Fatalf("FormatDate(%q) = %q, want %q", lang, got, want)
+		}
+	}
+	if got := FormatTime(date, "en"); got != "09:05" {
+		t.Fatalf("FormatTime() = %q, want 09:05", got)
+	}
+}
@@ -0,0 +1,182 @@
+// Package ical renders RFC 5545 iCalendar documents for calendar invites and feeds.
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MIMEType is the content type for iCalendar documents.
+const MIMEType = "text/calendar; charset=utf-8"
+
+// Calendar methods (RFC 5546). Invites sent by email use MethodRequest so calendar
+// clients offer to add or update the event; subscription feeds use MethodPublish.
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// Event statuses.
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	productID      = "-//Portal//Appointments//NL"
+	uidDomain      = "portal"
+	utcLayout      = "20060102T150405Z"
+	dateLayout     = "20060102"
+	maxLineOctets  = 75
+	lineTerminator = "\r\n"
+)
+
+// Event is a single VEVENT. UID must be stable across updates of the same event and
+// Sequence must increase with every update, otherwise calendar clients ignore changes.
+type Event struct {
+	UID            string
+	Sequence       int
+	Start          time.Time
+	End            time.Time
+	AllDay         bool
+	Summary        string
+	Description    string
+	Location       string
+	URL            string
+	Status         string
+	OrganizerName  string
+	OrganizerEmail string
+	LastModified   time.Time
+}
+
+// Calendar is a VCALENDAR with zero or more events.
+type Calendar struct {
+	Name   string
+	Method string
+	Events []Event
+}
+
+// UID builds a globally unique, stable event UID from a resource ID.
+func UID(id string) string {
+	return id + "@" + uidDomain
+}
+
+// SequenceSince derives a monotonically increasing SEQUENCE from the creation and last
+// modification time of a resource, so no separate revision counter has to be stored.
+func SequenceSince(createdAt, updatedAt time.Time) int {
+	if !updatedAt.After(createdAt) {
+		return 0
+	}
+	return int(updatedAt.Sub(createdAt) / time.Second)
+}
+
+// Encode renders the calendar using CRLF line endings and 75-octet line folding.
+func (c Calendar) Encode() []byte {
+	return c.encodeAt(time.Now())
+}
+
+func (c Calendar) encodeAt(now time.Time) []byte {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+productID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		writeLine(&b, "METHOD:"+c.Method)
+	}
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	for _, e := range c.Events {
+		writeEvent(&b, e, now)
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func writeEvent(b *strings.Builder, e Event, now time.Time) {
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+escapeText(e.UID))
+	writeLine(b, "DTSTAMP:"+now.UTC().Format(utcLayout))
+	writeLine(b, "SEQUENCE:"+strconv.Itoa(e.Sequence))
+	if e.AllDay {
+		end := e.End
+		if !end.After(e.Start) {
+			end = e.Start.AddDate(0, 0, 1)
+		}
+		writeLine(b, "DTSTART;VALUE=DATE:"+e.Start.Format(dateLayout))
+		writeLine(b, "DTEND;VALUE=DATE:"+end.Format(dateLayout))
+	} else {
+		writeLine(b, "DTSTART:"+e.Start.UTC().Format(utcLayout))
+		writeLine(b, "DTEND:"+e.End.UTC().Format(utcLayout))
+	}
+	writeLine(b, "SUMMARY:"+escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(b, "DESCRIPTION:"+escapeText(e.Description))
+	}
+	if e.Location != "" {
+		writeLine(b, "LOCATION:"+escapeText(e.Location))
+	}
+	if e.URL != "" {
+		writeLine(b, "URL:"+e.URL)
+	}
+	status := e.Status
+	if status == "" {
+		status = StatusConfirmed
+	}
+	writeLine(b, "STATUS:"+status)
+	if e.OrganizerEmail != "" {
+		organizer := "ORGANIZER"
+		if e.OrganizerName != "" {
+			organizer += ";CN=" + quoteParam(e.OrganizerName)
+		}
+		writeLine(b, organizer+":mailto:"+e.OrganizerEmail)
+	}
+	if !e.LastModified.IsZero() {
+		writeLine(b, "LAST-MODIFIED:"+e.LastModified.UTC().Format(utcLayout))
+	}
+	writeLine(b, "END:VEVENT")
+}
+
+// writeLine folds content lines longer than 75 octets (RFC 5545 §3.1) without splitting
+// multi-byte UTF-8 sequences.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString(lineTerminator + " ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString(lineTerminator)
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+func quoteParam(value string) string {
+	value = strings.NewReplacer(`"`, "", "\r", "", "\n", " ").Replace(value)
+	return `"` + value + `"`
+}
@@ -0,0 +1,72 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEncodeRendersEventWithEscapedText(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.March, 3, 9, 30, 0, 0, time.UTC)
+	out := string(Calendar{Method: MethodRequest, Events: []Event{{
+		UID:            "appt-1@portal",
+		Sequence:       2,
+		Start:          start,
+		End:            start.Add(time.Hour),
+		Summary:        "Inmeting; dak, zolder",
+		Location:       "Dorpsstraat 1\nAmsterdam",
+		OrganizerName:  "Bouwbedrijf",
+		OrganizerEmail: "info@example.com",
+	}}}.encodeAt(start))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"METHOD:REQUEST\r\n",
+		"UID:appt-1@portal\r\n",
+		"SEQUENCE:2\r\n",
+		"DTSTART:20260303T093000Z\r\n",
+		"DTEND:20260303T103000Z\r\n",
+		`SUMMARY:Inmeting\; dak\, zolder` + "\r\n",
+		`LOCATION:Dorpsstraat 1\nAmsterdam` + "\r\n",
+		`ORGANIZER;CN="Bouwbedrijf":mailto:info@example.com` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestEncodeRendersAllDayEventsAsDates(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	out := string(Calendar{Events: []Event{{UID: "x", Start: day, End: day, AllDay: true}}}.encodeAt(day))
+
+	if !strings.Contains(out, "DTSTART;VALUE=DATE:20260401\r\n") || !strings.Contains(out, "DTEND;VALUE=DATE:20260402\r\n") {
+		t.Fatalf("expected all-day dates, got:\n%s", out)
+	}
+}
+
+func TestWriteLineFoldsLongLinesOnRuneBoundaries(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	writeLine(&b, "DESCRIPTION:"+strings.Repeat("é", 80))
+
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Fatalf("line exceeds %d octets: %d", maxLineOctets, len(line))
+		}
+		if !utf8.ValidString(line) {
+			t.Fatal("line folding split a multi-byte character")
+		}
+	}
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	if unfolded != "DESCRIPTION:"+strings.Repeat("é", 80)+"\r\n" {
+		t.Fatal("unfolded output does not match the original line")
+	}
+}
@@ -0,0 +1,263 @@
+// Package imaging decodes photos and renders downscaled JPEG renditions of them
+// using only the standard library image codecs.
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Register the decoders of the supported formats.
+	_ "image/gif"
+	_ "image/png"
+)
+
+// MaxPixels caps the decoded size of a source image, so a small file that
+// declares huge dimensions cannot exhaust memory.
+const MaxPixels = 60_000_000
+
+var (
+	// ErrUnsupported is returned for data that is not a decodable image.
+	ErrUnsupported = errors.New("unsupported image format")
+	// ErrTooLarge is returned for images with more than MaxPixels pixels.
+	ErrTooLarge = errors.New("image dimensions too large")
+)
+
+// Source is a decoded image, flattened onto white, with its EXIF orientation.
+type Source struct {
+	img         *image.RGBA
+	orientation int
+}
+
+// Decode decodes a JPEG, PNG or GIF image. The EXIF orientation of JPEG files is
+// kept and applied by Fit.
+func Decode(data []byte) (*Source, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrUnsupported
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode %s image: %w", format, err)
+	}
+
+	bounds := decoded.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), decoded, bounds.Min, draw.Over)
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	return &Source{img: flat, orientation: orientation}, nil
+}
+
+// Width returns the displayed width, after orientation.
+func (s *Source) Width() int {
+	if s.swapsAxes() {
+		return s.img.Bounds().Dy()
+	}
+	return s.img.Bounds().Dx()
+}
+
+// Height returns the displayed height, after orientation.
+func (s *Source) Height() int {
+	if s.swapsAxes() {
+		return s.img.Bounds().Dx()
+	}
+	return s.img.Bounds().Dy()
+}
+
+func (s *Source) swapsAxes() bool {
+	return s.orientation >= 5
+}
+
+// Fit returns the upright image scaled down, keeping its aspect ratio, to fit in
+// maxWidth x maxHeight. Images that already fit are not enlarged.
+func (s *Source) Fit(maxWidth, maxHeight int) *image.RGBA {
+	width, height := s.Width(), s.Height()
+	if width > maxWidth || height > maxHeight {
+		if width*maxHeight > height*maxWidth {
+			height = max(1, height*maxWidth/width)
+			width = maxWidth
+		} else {
+			width = max(1, width*maxHeight/height)
+			height = maxHeight
+		}
+	}
+
+	// Scale in stored orientation, then rotate the (smaller) result.
+	if s.swapsAxes() {
+		width, height = height, width
+	}
+	return orient(downscale(s.img, width, height), s.orientation)
+}
+
+// EncodeJPEG encodes img as a JPEG of the given quality (1-100).
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("encode jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// downscale resizes src to width x height by averaging the source pixels that
+// cover each target pixel (a box filter), which avoids aliasing on large factors.
+func downscale(src *image.RGBA, width, height int) *image.RGBA {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	if width == srcW && height == srcH {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, max((y+1)*srcH/height, y*srcH/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, max((x+1)*srcW/width, x*srcW/width+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					b += uint64(row[i+2])
+					a += uint64(row[i+3])
+					n++
+				}
+			}
+			o := y*dst.Stride + x*4
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(b / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// orient applies an EXIF orientation (2-8) so the image is displayed upright.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				sx, sy = w-1-x, y
+			case 3: // rotated 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90° clockwise
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90° counter-clockwise
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation tag of a JPEG file, or 1 when it
+// has none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			i++ // fill byte
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			i += 2 // segment without length
+			continue
+		case marker == 0xDA || marker == 0xD9:
+			return 1 // image data starts; metadata segments precede it
+		}
+
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		if marker == 0xE1 {
+			if o := exifOrientation(data[i+4 : i+2+size]); o != 0 {
+				return o
+			}
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of an APP1 Exif
+// segment. It returns 0 when the segment holds no valid orientation.
+func exifOrientation(segment []byte) int {
+	if len(segment) < 14 || string(segment[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := segment[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int64(order.Uint32(tiff[4:8]))
+	if ifd+2 > int64(len(tiff)) {
+		return 0
+	}
+	entries := int64(order.Uint16(tiff[ifd:]))
+	for k := int64(0); k < entries; k++ {
+		entry := ifd + 2 + k*12
+		if entry+12 > int64(len(tiff)) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) != 0x0112 {
+			continue
+		}
+		if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+			return v
+		}
+		return 0
+	}
+	return 0
+}
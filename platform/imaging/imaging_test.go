@@ -0,0 +1,103 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// withOrientation inserts an APP1 Exif segment with the orientation tag after
+// the SOI marker of a JPEG file.
+func withOrientation(t *testing.T, data []byte, orientation uint16) []byte {
+	t.Helper()
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifd := make([]byte, 2+12+4)
+	binary.BigEndian.PutUint16(ifd[0:], 1)
+	binary.BigEndian.PutUint16(ifd[2:], 0x0112)
+	binary.BigEndian.PutUint16(ifd[4:], 3) // SHORT
+	binary.BigEndian.PutUint32(ifd[6:], 1)
+	binary.BigEndian.PutUint16(ifd[10:], orientation)
+	payload := append(append([]byte("Exif\x00\x00"), tiff...), ifd...)
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func encodeTestJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFitKeepsAspectRatioAndDoesNotEnlarge(t *testing.T) {
+	src, err := Decode(encodeTestJPEG(t, 400, 200))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	thumb := src.Fit(100, 100)
+	if got := thumb.Bounds().Size(); got != image.Pt(100, 50) {
+		t.Fatalf("expected 100x50 thumbnail, got %v", got)
+	}
+	full := src.Fit(1000, 1000)
+	if got := full.Bounds().Size(); got != image.Pt(400, 200) {
+		t.Fatalf("expected the image not to be enlarged, got %v", got)
+	}
+}
+
+func TestFitAppliesExifOrientation(t *testing.T) {
+	data := withOrientation(t, encodeTestJPEG(t, 400, 200), 6)
+	if got := jpegOrientation(data); got != 6 {
+		t.Fatalf("expected orientation 6, got %d", got)
+	}
+
+	src, err := Decode(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if src.Width() != 200 || src.Height() != 400 {
+		t.Fatalf("expected upright 200x400, got %dx%d", src.Width(), src.Height())
+	}
+	if got := src.Fit(100, 100).Bounds().Size(); got != image.Pt(50, 100) {
+		t.Fatalf("expected rotated 50x100 thumbnail, got %v", got)
+	}
+}
+
+func TestDecodeFlattensTransparencyOntoWhite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	src, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := src.Fit(4, 4).RGBAAt(1, 1); got != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Fatalf("expected white, got %v", got)
+	}
+}
+
+func TestDecodeRejectsNonImages(t *testing.T) {
+	if _, err := Decode([]byte("%PDF-1.7")); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+}
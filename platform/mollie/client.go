@@ -0,0 +1,79 @@
+// Package mollie provides a REST client for the Mollie payments API.
+package mollie
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"portal_final_backend/platform/apperr"
+)
+
+// DefaultBaseURL is the Mollie v2 API.
+const DefaultBaseURL = "https://api.mollie.com/v2"
+
+// Client sends JSON requests to the Mollie API. The API key is passed per
+// request: billing uses the platform key, quote deposits the key of the
+// organization that receives the payment.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the Mollie API at baseURL, or at
+// DefaultBaseURL when it is empty.
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 20 * time.Second},
+	}
+}
+
+// Do sends body as JSON to path and decodes the response into out when out is
+// not nil. Responses outside the 2xx range are returned as bad requests.
+func (c *Client) Do(ctx context.Context, apiKey, method, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal mollie request: %w", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("build mollie request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("mollie request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return apperr.BadRequest(fmt.Sprintf("mollie request failed with status %d", resp.StatusCode))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode mollie response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package mollie
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"portal_final_backend/platform/apperr"
+)
+
+func TestDoSendsJSONWithAPIKey(t *testing.T) {
+	var gotAuth, gotPath string
+	var gotBody map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"tr_test","status":"open"}`))
+	}))
+	defer srv.Close()
+
+	var payment struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	err := NewClient(srv.URL).Do(context.Background(), "test_key", http.MethodPost, "/payments", map[string]string{"description": "Aanbetaling"}, &payment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "Bearer test_key" {
+		t.Fatalf("expected bearer API key, got %q", gotAuth)
+	}
+	if gotPath != "/payments" || gotBody["description"] != "Aanbetaling" {
+		t.Fatalf("unexpected request %s %v", gotPath, gotBody)
+	}
+	if payment.ID != "tr_test" || payment.Status != "open" {
+		t.Fatalf("unexpected payment %+v", payment)
+	}
+}
+
+func TestDoReportsErrorStatusAsBadRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).Do(context.Background(), "test_key", http.MethodGet, "/methods", nil, nil)
+	if !apperr.Is(err, apperr.KindBadRequest) {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+}
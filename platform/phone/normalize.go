@@ -28,3 +28,20 @@ func NormalizeE164(input string) string {
 
 	return phonenumbers.Format(number, phonenumbers.E164)
 }
+
+// RegionCode returns the ISO 3166 region of a valid phone number, or "" when
+// the number cannot be parsed. Numbers without a country code are assumed to
+// be Dutch.
+func RegionCode(input string) string {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return ""
+	}
+
+	number, err := phonenumbers.Parse(trimmed, defaultRegion)
+	if err != nil || !phonenumbers.IsValidNumber(number) {
+		return ""
+	}
+
+	return phonenumbers.GetRegionCodeForNumber(number)
+}
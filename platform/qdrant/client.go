@@ -10,15 +10,18 @@ import (
 	"net/http"
 	"time"
 
+	"portal_final_backend/platform/circuitbreaker"
+
 	"golang.org/x/sync/errgroup"
 )
 
 // Client is an HTTP client for Qdrant vector database.
 type Client struct {
-	baseURL    string
-	apiKey     string
-	collection string
-	httpClient *http.Client
+	baseURL     string
+	apiKey      string
+	collection  string
+	tenancyMode string
+	httpClient  *http.Client
 }
 
 // Config configures the Qdrant client.
@@ -26,7 +29,10 @@ type Config struct {
 	BaseURL    string
 	APIKey     string
 	Collection string
-	Timeout    time.Duration
+	// TenancyMode selects how tenant-owned points are isolated: TenancyShared (default)
+	// or TenancyPerTenant.
+	TenancyMode string
+	Timeout     time.Duration
 }
 
 // NewClient creates a new Qdrant client.
@@ -37,11 +43,13 @@ func NewClient(cfg Config) *Client {
 	}
 
 	return &Client{
-		baseURL:    cfg.BaseURL,
-		apiKey:     cfg.APIKey,
-		collection: cfg.Collection,
+		baseURL:     cfg.BaseURL,
+		apiKey:      cfg.APIKey,
+		collection:  cfg.Collection,
+		tenancyMode: NormalizeTenancyMode(cfg.TenancyMode),
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: circuitbreaker.Transport(circuitbreaker.For(circuitbreaker.DependencyQdrant), nil),
 		},
 	}
 }
@@ -138,7 +146,7 @@ func (c *Client) BatchSearch(ctx context.Context, requests []SearchRequest) ([][
 		g.Go(func() error {
 			res, err := c.searchCollection(gCtx, col, sr.Vector, sr.Limit, sr.ScoreThreshold, sr.Filter)
 			if err != nil {
-				return fmt.Errorf("qdrant batch search returned %v", err)
+				return fmt.Errorf("qdrant batch search returned %w", err)
 			}
 			results[i] = res
 			return nil
@@ -208,7 +216,7 @@ func NewOrganizationFilter(organizationID string) *Filter {
 	return &Filter{
 		Must: []FieldCondition{
 			{
-				Key:   "organization_id",
+				Key:   OrganizationPayloadKey,
 				Match: MatchValue{Value: organizationID},
 			},
 		},
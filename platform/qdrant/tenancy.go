@@ -0,0 +1,186 @@
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OrganizationPayloadKey is the payload field that holds the owning organization of a point.
+const OrganizationPayloadKey = "organization_id"
+
+// Tenancy modes for tenant-owned collections.
+const (
+	// TenancyShared stores all tenants in one collection, isolated by the organization payload filter.
+	TenancyShared = "shared"
+	// TenancyPerTenant stores every tenant in its own collection named <collection>_<organization id>.
+	TenancyPerTenant = "per_tenant"
+)
+
+// ErrMissingTenant is returned when a tenant-scoped search is attempted without an organization.
+var ErrMissingTenant = errors.New("qdrant: tenant-scoped search requires an organization id")
+
+// NormalizeTenancyMode maps a configured mode onto a known tenancy mode, defaulting to shared.
+func NormalizeTenancyMode(mode string) string {
+	if strings.EqualFold(strings.TrimSpace(mode), TenancyPerTenant) {
+		return TenancyPerTenant
+	}
+	return TenancyShared
+}
+
+// TenantCollectionName returns the collection that holds the points of an organization.
+func TenantCollectionName(collection, organizationID, mode string) string {
+	if NormalizeTenancyMode(mode) != TenancyPerTenant || organizationID == "" {
+		return collection
+	}
+	return collection + "_" + strings.ReplaceAll(strings.ToLower(organizationID), "-", "")
+}
+
+// TenancyMode returns the tenancy mode of the configured collection.
+func (c *Client) TenancyMode() string {
+	return c.tenancyMode
+}
+
+// TenantCollection returns the collection that holds the points of an organization.
+func (c *Client) TenantCollection(organizationID string) string {
+	return TenantCollectionName(c.collection, organizationID, c.tenancyMode)
+}
+
+// TenantSearchRequest builds a search request scoped to an organization. The organization
+// payload filter is applied in both tenancy modes, so a misrouted point never leaks.
+func (c *Client) TenantSearchRequest(organizationID string, vector []float32, limit int, scoreThreshold float64) (SearchRequest, error) {
+	organizationID = strings.TrimSpace(organizationID)
+	if organizationID == "" {
+		return SearchRequest{}, ErrMissingTenant
+	}
+	return SearchRequest{
+		CollectionName: c.TenantCollection(organizationID),
+		Vector:         vector,
+		Limit:          limit,
+		WithPayload:    true,
+		ScoreThreshold: &scoreThreshold,
+		Filter:         NewOrganizationFilter(organizationID),
+	}, nil
+}
+
+// SearchForTenant performs a tenant-scoped vector search and fails closed without an organization.
+func (c *Client) SearchForTenant(ctx context.Context, organizationID string, vector []float32, limit int, scoreThreshold float64) ([]SearchResult, error) {
+	req, err := c.TenantSearchRequest(organizationID, vector, limit, scoreThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return c.searchCollection(ctx, req.CollectionName, req.Vector, req.Limit, req.ScoreThreshold, req.Filter)
+}
+
+// TenantPayloadAudit summarizes the points of a collection that lack an organization payload.
+type TenantPayloadAudit struct {
+	Collection string
+	Total      int64
+	Missing    int64
+	SampleIDs  []any
+}
+
+type countPointsRequest struct {
+	Filter map[string]any `json:"filter,omitempty"`
+	Exact  bool           `json:"exact"`
+}
+
+type countPointsResponse struct {
+	Result struct {
+		Count int64 `json:"count"`
+	} `json:"result"`
+}
+
+type scrollPointsRequest struct {
+	Filter      map[string]any `json:"filter,omitempty"`
+	Limit       int            `json:"limit"`
+	WithPayload bool           `json:"with_payload"`
+	WithVector  bool           `json:"with_vector"`
+}
+
+type scrollPointsResponse struct {
+	Result struct {
+		Points []struct {
+			ID any `json:"id"`
+		} `json:"points"`
+	} `json:"result"`
+}
+
+// missingOrganizationFilter matches points whose organization payload is absent, null or empty.
+func missingOrganizationFilter() map[string]any {
+	return map[string]any{
+		"must": []any{
+			map[string]any{"is_empty": map[string]any{"key": OrganizationPayloadKey}},
+		},
+	}
+}
+
+// AuditTenantPayloads counts the points of a collection that carry no organization payload
+// and returns up to sampleSize of their IDs. Such points are invisible to tenant-scoped
+// search but indicate an indexing path that skipped tenant attribution.
+func (c *Client) AuditTenantPayloads(ctx context.Context, collection string, sampleSize int) (TenantPayloadAudit, error) {
+	if collection == "" {
+		collection = c.collection
+	}
+	audit := TenantPayloadAudit{Collection: collection}
+
+	var total countPointsResponse
+	if err := c.postJSON(ctx, fmt.Sprintf("%s/collections/%s/points/count", c.baseURL, collection), countPointsRequest{Exact: true}, &total); err != nil {
+		return audit, err
+	}
+	audit.Total = total.Result.Count
+
+	var missing countPointsResponse
+	if err := c.postJSON(ctx, fmt.Sprintf("%s/collections/%s/points/count", c.baseURL, collection), countPointsRequest{Filter: missingOrganizationFilter(), Exact: true}, &missing); err != nil {
+		return audit, err
+	}
+	audit.Missing = missing.Result.Count
+
+	if audit.Missing == 0 || sampleSize <= 0 {
+		return audit, nil
+	}
+	var sample scrollPointsResponse
+	if err := c.postJSON(ctx, fmt.Sprintf("%s/collections/%s/points/scroll", c.baseURL, collection), scrollPointsRequest{Filter: missingOrganizationFilter(), Limit: sampleSize}, &sample); err != nil {
+		return audit, err
+	}
+	for _, point := range sample.Result.Points {
+		audit.SampleIDs = append(audit.SampleIDs, point.ID)
+	}
+	return audit, nil
+}
+
+func (c *Client) postJSON(ctx context.Context, url string, body any, out any) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+		return fmt.Errorf("qdrant returned %d for %s: %s", resp.StatusCode, url, string(respBody))
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBody)).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
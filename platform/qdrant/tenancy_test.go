@@ -0,0 +1,38 @@
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTenantCollectionName(t *testing.T) {
+	orgID := "7B1C4A3E-0F2D-4C5E-9A8B-1D2E3F4A5B6C"
+	if got := TenantCollectionName("catalog", orgID, TenancyShared); got != "catalog" {
+		t.Fatalf("shared mode should keep the collection, got %q", got)
+	}
+	if got, want := TenantCollectionName("catalog", orgID, "PER_TENANT"), "catalog_7b1c4a3e0f2d4c5e9a8b1d2e3f4a5b6c"; got != want {
+		t.Fatalf("TenantCollectionName = %q, want %q", got, want)
+	}
+	if got := TenantCollectionName("catalog", "", TenancyPerTenant); got != "catalog" {
+		t.Fatalf("empty organization should keep the collection, got %q", got)
+	}
+}
+
+func TestTenantSearchFailsClosed(t *testing.T) {
+	client := NewClient(Config{BaseURL: "http://qdrant.invalid", Collection: "catalog", TenancyMode: TenancyPerTenant})
+	if _, err := client.SearchForTenant(context.Background(), " ", []float32{1}, 5, 0.5); !errors.Is(err, ErrMissingTenant) {
+		t.Fatalf("expected ErrMissingTenant, got %v", err)
+	}
+
+	req, err := client.TenantSearchRequest("org-1", []float32{1}, 5, 0.5)
+	if err != nil {
+		t.Fatalf("TenantSearchRequest: %v", err)
+	}
+	if req.CollectionName != "catalog_org1" {
+		t.Fatalf("unexpected collection %q", req.CollectionName)
+	}
+	if req.Filter == nil || len(req.Filter.Must) != 1 || req.Filter.Must[0].Match.Value != "org-1" {
+		t.Fatalf("expected organization filter in per-tenant mode, got %+v", req.Filter)
+	}
+}
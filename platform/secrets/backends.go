@@ -0,0 +1,62 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvBackend reads secrets from process environment variables.
+type EnvBackend struct{}
+
+// NewEnvBackend creates an environment variable backend.
+func NewEnvBackend() *EnvBackend {
+	return &EnvBackend{}
+}
+
+// Name returns the backend identifier.
+func (b *EnvBackend) Name() string { return BackendEnv }
+
+// Lookup returns the environment variable with the given name.
+func (b *EnvBackend) Lookup(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// FileBackend reads each secret from a file named after it, e.g. Docker or Kubernetes mounted secrets.
+// Both the exact name and its lower-case variant are tried ("SMTP_ENCRYPTION_KEY", "smtp_encryption_key").
+type FileBackend struct {
+	dir string
+}
+
+// NewFileBackend creates a backend reading secrets from dir.
+func NewFileBackend(dir string) *FileBackend {
+	return &FileBackend{dir: dir}
+}
+
+// Name returns the backend identifier.
+func (b *FileBackend) Name() string { return BackendFile }
+
+// Lookup reads the secret file for name.
+func (b *FileBackend) Lookup(_ context.Context, name string) (string, error) {
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return "", ErrNotFound
+	}
+	for _, candidate := range []string{name, strings.ToLower(name)} {
+		data, err := os.ReadFile(filepath.Join(b.dir, candidate))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", ErrNotFound
+}
@@ -0,0 +1,201 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// KeySize is the required AES-256 key length in bytes.
+const KeySize = 32
+
+// keyIDSeparator splits "<keyID>:<hex ciphertext>". Legacy ciphertexts are plain hex and never contain it.
+const keyIDSeparator = ":"
+
+var keyIDRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Key is a single AES-256 key. An empty ID marks the legacy key whose ciphertexts carry no key ID.
+type Key struct {
+	ID       string
+	Material []byte
+}
+
+// Keyring encrypts with its primary key and decrypts with any key it holds.
+type Keyring struct {
+	primary Key
+	keys    map[string][]byte
+	order   [][]byte
+}
+
+// NewKeyring builds a keyring. The first key is the primary key used for encryption.
+func NewKeyring(keys ...Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyring requires at least one key")
+	}
+	kr := &Keyring{primary: keys[0], keys: make(map[string][]byte, len(keys))}
+	for _, key := range keys {
+		if key.ID != "" && !keyIDRe.MatchString(key.ID) {
+			return nil, fmt.Errorf("invalid key ID %q", key.ID)
+		}
+		if len(key.Material) != KeySize {
+			return nil, fmt.Errorf("key %q must be %d bytes (%d hex chars)", key.ID, KeySize, KeySize*2)
+		}
+		if _, exists := kr.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key ID %q", key.ID)
+		}
+		kr.keys[key.ID] = key.Material
+		kr.order = append(kr.order, key.Material)
+	}
+	return kr, nil
+}
+
+// SingleKeyring wraps one legacy key. It returns nil when the key is not a valid AES-256 key.
+func SingleKeyring(material []byte) *Keyring {
+	kr, err := NewKeyring(Key{Material: material})
+	if err != nil {
+		return nil
+	}
+	return kr
+}
+
+// ParseKeyring parses a keyring spec. Either a single hex key (legacy format) or a comma-separated
+// list of "id:hexkey" entries, where the first entry is the primary key:
+//
+//	SMTP_ENCRYPTION_KEY=2026-10:<64 hex>,<64 hex>
+func ParseKeyring(spec string) (*Keyring, error) {
+	var keys []Key
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, keyHex := "", entry
+		if before, after, found := strings.Cut(entry, keyIDSeparator); found {
+			id, keyHex = strings.TrimSpace(before), strings.TrimSpace(after)
+		}
+		material, err := hex.DecodeString(keyHex)
+		if err != nil {
+			return nil, fmt.Errorf("key %q must be hex-encoded: %w", id, err)
+		}
+		keys = append(keys, Key{ID: id, Material: material})
+	}
+	return NewKeyring(keys...)
+}
+
+// PrimaryID returns the ID of the key used for new ciphertexts.
+func (k *Keyring) PrimaryID() string {
+	return k.primary.ID
+}
+
+// PrimaryKey returns the raw primary key material, for uses like HMAC signing.
+func (k *Keyring) PrimaryKey() []byte {
+	return k.primary.Material
+}
+
+// Keys returns the raw material of every key, the primary key first, for
+// verifying HMAC signatures made before a rotation.
+func (k *Keyring) Keys() [][]byte {
+	return k.order
+}
+
+// Encrypt seals plaintext with the primary key. The output is "<keyID>:<hex nonce+ciphertext>",
+// or plain hex when the primary key is the legacy key.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	sealed, err := seal(plaintext, k.primary.Material)
+	if err != nil {
+		return "", err
+	}
+	if k.primary.ID == "" {
+		return sealed, nil
+	}
+	return k.primary.ID + keyIDSeparator + sealed, nil
+}
+
+// Decrypt opens a ciphertext produced by Encrypt. Ciphertexts without a key ID are tried against
+// the legacy key first, then against every other key in the ring.
+func (k *Keyring) Decrypt(ciphertext string) (string, error) {
+	if id, sealed, found := strings.Cut(ciphertext, keyIDSeparator); found {
+		key, ok := k.keys[id]
+		if !ok {
+			return "", fmt.Errorf("unknown encryption key ID %q", id)
+		}
+		return open(sealed, key)
+	}
+
+	if key, ok := k.keys[""]; ok {
+		if plain, err := open(ciphertext, key); err == nil {
+			return plain, nil
+		}
+	}
+	var lastErr error = errors.New("no key could decrypt the ciphertext")
+	for id, key := range k.keys {
+		if id == "" {
+			continue
+		}
+		plain, err := open(ciphertext, key)
+		if err == nil {
+			return plain, nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+// NeedsRotation reports whether a ciphertext was produced by a key other than the primary key.
+func (k *Keyring) NeedsRotation(ciphertext string) bool {
+	id, _, found := strings.Cut(ciphertext, keyIDSeparator)
+	if !found {
+		return k.primary.ID != ""
+	}
+	return id != k.primary.ID
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	aesGCM, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create GCM: %w", err)
+	}
+	return aesGCM, nil
+}
+
+func seal(plaintext string, key []byte) (string, error) {
+	aesGCM, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	return hex.EncodeToString(aesGCM.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+}
+
+func open(sealed string, key []byte) (string, error) {
+	data, err := hex.DecodeString(sealed)
+	if err != nil {
+		return "", fmt.Errorf("hex decode: %w", err)
+	}
+	aesGCM, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonceSize := aesGCM.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("ciphertext too short")
+	}
+	plaintext, err := aesGCM.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
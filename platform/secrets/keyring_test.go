@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testKeyA = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	testKeyB = "202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f"
+)
+
+func TestParseKeyringLegacySingleKeyStaysCompatible(t *testing.T) {
+	kr, err := ParseKeyring(testKeyA)
+	if err != nil {
+		t.Fatalf("parse keyring: %v", err)
+	}
+
+	ciphertext, err := kr.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if strings.Contains(ciphertext, keyIDSeparator) {
+		t.Fatalf("expected legacy ciphertext without key ID, got %q", ciphertext)
+	}
+
+	legacy, err := seal("existing", kr.PrimaryKey())
+	if err != nil {
+		t.Fatalf("legacy encrypt: %v", err)
+	}
+	plain, err := kr.Decrypt(legacy)
+	if err != nil || plain != "existing" {
+		t.Fatalf("expected legacy ciphertext to decrypt, got %q (%v)", plain, err)
+	}
+}
+
+func TestKeyringRotationDecryptsOldCiphertexts(t *testing.T) {
+	old, err := ParseKeyring(testKeyA)
+	if err != nil {
+		t.Fatalf("parse old keyring: %v", err)
+	}
+	legacyCiphertext, err := old.Encrypt("before-rotation")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	rotated, err := ParseKeyring("2026-10:" + testKeyB + "," + testKeyA)
+	if err != nil {
+		t.Fatalf("parse rotated keyring: %v", err)
+	}
+	ciphertext, err := rotated.Encrypt("after-rotation")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if !strings.HasPrefix(ciphertext, "2026-10:") {
+		t.Fatalf("expected key ID prefix, got %q", ciphertext)
+	}
+
+	for input, want := range map[string]string{legacyCiphertext: "before-rotation", ciphertext: "after-rotation"} {
+		got, err := rotated.Decrypt(input)
+		if err != nil || got != want {
+			t.Fatalf("decrypt %q: got %q (%v), want %q", input, got, err, want)
+		}
+	}
+	if !rotated.NeedsRotation(legacyCiphertext) || rotated.NeedsRotation(ciphertext) {
+		t.Fatal("unexpected NeedsRotation result")
+	}
+}
+
+func TestParseKeyringRejectsInvalidKeys(t *testing.T) {
+	for _, spec := range []string{"", "abcd", "bad id:" + testKeyA, "k1:" + testKeyA + ",k1:" + testKeyB} {
+		if _, err := ParseKeyring(spec); err == nil {
+			t.Fatalf("expected error for spec %q", spec)
+		}
+	}
+}
+
+func TestFileBackendLookup(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "smtp_encryption_key"), []byte(testKeyA+"\n"), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+
+	svc := New(NewFileBackend(dir))
+	kr, err := svc.Keyring(context.Background(), "SMTP_ENCRYPTION_KEY")
+	if err != nil || kr == nil {
+		t.Fatalf("expected keyring from file backend, got %v (%v)", kr, err)
+	}
+	missing, err := svc.Keyring(context.Background(), "EXPORTS_ENCRYPTION_KEY")
+	if err != nil || missing != nil {
+		t.Fatalf("expected nil keyring for missing secret, got %v (%v)", missing, err)
+	}
+}
@@ -0,0 +1,200 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KMSDecrypter decrypts an envelope-encrypted ciphertext blob.
+type KMSDecrypter interface {
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// KMSBackend resolves secrets whose stored value is a base64 KMS ciphertext. The ciphertext is read
+// from the source backend (usually the environment) and decrypted once per name.
+type KMSBackend struct {
+	source    Backend
+	decrypter KMSDecrypter
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewKMSBackend creates a KMS envelope backend.
+func NewKMSBackend(source Backend, decrypter KMSDecrypter) *KMSBackend {
+	return &KMSBackend{source: source, decrypter: decrypter, cache: map[string]string{}}
+}
+
+// Name returns the backend identifier.
+func (b *KMSBackend) Name() string { return BackendKMS }
+
+// Lookup reads the ciphertext for name from the source backend and decrypts it.
+func (b *KMSBackend) Lookup(ctx context.Context, name string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if value, ok := b.cache[name]; ok {
+		return value, nil
+	}
+
+	encoded, err := b.source.Lookup(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return "", ErrNotFound
+	}
+	blob, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("kms ciphertext must be base64: %w", err)
+	}
+	plaintext, err := b.decrypter.Decrypt(ctx, blob)
+	if err != nil {
+		return "", err
+	}
+	b.cache[name] = string(plaintext)
+	return b.cache[name], nil
+}
+
+// AWSKMSClient calls the AWS KMS Decrypt API using SigV4-signed requests.
+type AWSKMSClient struct {
+	region       string
+	accessKeyID  string
+	secretKey    string
+	sessionToken string
+	endpoint     string
+	httpClient   *http.Client
+	now          func() time.Time
+}
+
+// NewAWSKMSClientFromEnv creates a KMS client from the standard AWS_* credential environment variables.
+func NewAWSKMSClientFromEnv(region string) (*AWSKMSClient, error) {
+	if strings.TrimSpace(region) == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if region == "" || accessKeyID == "" || secretKey == "" {
+		return nil, errors.New("AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for the kms secrets backend")
+	}
+	return &AWSKMSClient{
+		region:       region,
+		accessKeyID:  accessKeyID,
+		secretKey:    secretKey,
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		endpoint:     fmt.Sprintf("https://kms.%s.amazonaws.com/", region),
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		now:          time.Now,
+	}, nil
+}
+
+// Decrypt calls TrentService.Decrypt for the given ciphertext blob.
+func (c *AWSKMSClient) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"CiphertextBlob": base64.StdEncoding.EncodeToString(ciphertext)})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Decrypt")
+	c.sign(req, body)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kms request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kms decrypt returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("decode kms response: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(result.Plaintext)
+}
+
+func (c *AWSKMSClient) sign(req *http.Request, body []byte) {
+	now := c.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if c.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.sessionToken)
+	}
+
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+		"x-amz-date":   amzDate,
+		"x-amz-target": req.Header.Get("X-Amz-Target"),
+	}
+	names := []string{"content-type", "host", "x-amz-date"}
+	if c.sessionToken != "" {
+		headers["x-amz-security-token"] = c.sessionToken
+		names = append(names, "x-amz-security-token")
+	}
+	names = append(names, "x-amz-target")
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	payloadHash := sha256.Sum256(body)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + c.region + "/kms/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+c.secretKey), date)
+	signingKey = hmacSHA256(signingKey, c.region)
+	signingKey = hmacSHA256(signingKey, "kms")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
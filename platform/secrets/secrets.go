@@ -0,0 +1,109 @@
+// Package secrets resolves sensitive configuration values (encryption keys, tokens)
+// from a pluggable backend and exposes AES-256-GCM keyrings with rotation support.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Backend names accepted by SECRETS_BACKEND.
+const (
+	BackendEnv   = "env"
+	BackendFile  = "file"
+	BackendVault = "vault"
+	BackendKMS   = "kms"
+)
+
+// ErrNotFound is returned when a backend has no value for the requested secret.
+var ErrNotFound = errors.New("secret not found")
+
+// Backend looks up raw secret values by name (e.g. "SMTP_ENCRYPTION_KEY").
+type Backend interface {
+	Name() string
+	Lookup(ctx context.Context, name string) (string, error)
+}
+
+// Service resolves secrets through a single backend.
+type Service struct {
+	backend Backend
+}
+
+// New creates a secrets service on top of the given backend.
+func New(backend Backend) *Service {
+	return &Service{backend: backend}
+}
+
+// BackendName returns the name of the configured backend.
+func (s *Service) BackendName() string {
+	return s.backend.Name()
+}
+
+// Get returns the trimmed value of a secret. Missing secrets return ErrNotFound.
+func (s *Service) Get(ctx context.Context, name string) (string, error) {
+	value, err := s.backend.Lookup(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Keyring loads and parses the keyring stored under name. It returns (nil, nil) when the secret is not set.
+func (s *Service) Keyring(ctx context.Context, name string) (*Keyring, error) {
+	spec, err := s.Get(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	keyring, err := ParseKeyring(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return keyring, nil
+}
+
+// Config is the narrow configuration interface needed to build a backend.
+type Config interface {
+	GetSecretsBackend() string
+	GetSecretsFileDir() string
+	GetVaultAddr() string
+	GetVaultToken() string
+	GetVaultMount() string
+	GetVaultSecretPath() string
+	GetAWSRegion() string
+}
+
+// NewFromConfig builds a secrets service for the backend selected in configuration.
+func NewFromConfig(cfg Config) (*Service, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.GetSecretsBackend())) {
+	case "", BackendEnv:
+		return New(NewEnvBackend()), nil
+	case BackendFile:
+		if strings.TrimSpace(cfg.GetSecretsFileDir()) == "" {
+			return nil, errors.New("SECRETS_FILE_DIR is required for the file secrets backend")
+		}
+		return New(NewFileBackend(cfg.GetSecretsFileDir())), nil
+	case BackendVault:
+		backend, err := NewVaultBackend(cfg.GetVaultAddr(), cfg.GetVaultToken(), cfg.GetVaultMount(), cfg.GetVaultSecretPath())
+		if err != nil {
+			return nil, err
+		}
+		return New(backend), nil
+	case BackendKMS:
+		client, err := NewAWSKMSClientFromEnv(cfg.GetAWSRegion())
+		if err != nil {
+			return nil, err
+		}
+		return New(NewKMSBackend(NewEnvBackend(), client)), nil
+	default:
+		return nil, fmt.Errorf("unsupported SECRETS_BACKEND %q", cfg.GetSecretsBackend())
+	}
+}
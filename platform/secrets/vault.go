@@ -0,0 +1,111 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultVaultMount = "secret"
+
+// VaultBackend reads secrets from a HashiCorp Vault KV v2 secret. Every secret name is a key
+// inside the single secret at <mount>/data/<path>.
+type VaultBackend struct {
+	addr       string
+	token      string
+	mount      string
+	path       string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	cached map[string]string
+}
+
+// NewVaultBackend creates a Vault KV v2 backend.
+func NewVaultBackend(addr, token, mount, path string) (*VaultBackend, error) {
+	addr = strings.TrimRight(strings.TrimSpace(addr), "/")
+	if addr == "" || strings.TrimSpace(token) == "" || strings.TrimSpace(path) == "" {
+		return nil, errors.New("VAULT_ADDR, VAULT_TOKEN and VAULT_SECRET_PATH are required for the vault secrets backend")
+	}
+	if strings.TrimSpace(mount) == "" {
+		mount = defaultVaultMount
+	}
+	return &VaultBackend{
+		addr:       addr,
+		token:      token,
+		mount:      strings.Trim(mount, "/"),
+		path:       strings.Trim(path, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns the backend identifier.
+func (b *VaultBackend) Name() string { return BackendVault }
+
+// Lookup returns a key from the configured Vault secret. The secret is fetched once and cached.
+func (b *VaultBackend) Lookup(ctx context.Context, name string) (string, error) {
+	data, err := b.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (b *VaultBackend) load(ctx context.Context) (map[string]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cached != nil {
+		return b.cached, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/%s/data/%s", b.addr, url.PathEscape(b.mount), b.path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", b.token)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		b.cached = map[string]string{}
+		return b.cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode vault response: %w", err)
+	}
+
+	values := make(map[string]string, len(payload.Data.Data))
+	for key, value := range payload.Data.Data {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		}
+	}
+	b.cached = values
+	return values, nil
+}